@@ -0,0 +1,189 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package main implements trace-assert, which checks a project against
+// architectural assertion rules for CI.
+//
+// Description:
+//
+//	Builds the graph for a project root exactly as the Trace service does,
+//	evaluates the rules file against it (see package assertions) and
+//	prints each violation with the symbols and edge involved. The exit
+//	status fails the CI job when any rule is broken.
+//
+// Usage:
+//
+//	trace-assert -root <dir> [flags]
+//	  -root string       Project root to build the graph from (required)
+//	  -rules string      Rules file (default: <root>/.trace-assertions.yaml)
+//	  -languages string  Comma-separated languages (default: all supported)
+//	  -exclude string    Comma-separated glob patterns to exclude
+//	  -timeout duration  Build timeout (default 10m)
+//	  -json              Print the report as JSON
+//
+// Exit status: 0 if every rule passes, 1 if any rule has violations, 2
+// for usage errors, bad rules or a failed build.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	trace "github.com/AleutianAI/AleutianFOSS/services/trace"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/assertions"
+)
+
+func main() {
+	root := flag.String("root", "", "Project root to build the graph from (required)")
+	rules := flag.String("rules", "", "Rules file (default: <root>/"+assertions.DefaultFile+")")
+	languages := flag.String("languages", "", "Comma-separated languages (default: all supported)")
+	exclude := flag.String("exclude", "", "Comma-separated glob patterns to exclude")
+	timeout := flag.Duration("timeout", 10*time.Minute, "Build timeout")
+	asJSON := flag.Bool("json", false, "Print the report as JSON")
+	flag.Parse()
+
+	if *root == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	rulesPath := *rules
+	if rulesPath == "" {
+		rulesPath = filepath.Join(*root, assertions.DefaultFile)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, *timeout)
+	defer cancelTimeout()
+
+	report, err := run(ctx, *root, rulesPath, splitList(*languages), splitList(*exclude))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace-assert: %v\n", err)
+		os.Exit(2)
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = printReport(os.Stdout, report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace-assert: %v\n", err)
+		os.Exit(2)
+	}
+	if !report.Passed {
+		os.Exit(1)
+	}
+}
+
+// run loads the rules, builds the graph for root and checks the rules.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and the build timeout.
+//	root - Project root.
+//	rulesPath - Rules file path.
+//	languages - Languages to parse; nil for the service default.
+//	excludes - Glob patterns to exclude.
+//
+// Outputs:
+//
+//	*assertions.Report - The outcome of every rule.
+//	error - Non-nil if the rules are invalid or the build failed.
+func run(ctx context.Context, root, rulesPath string, languages, excludes []string) (*assertions.Report, error) {
+	// Load the rules first so that a typo fails before a long build.
+	rs, err := assertions.Load(rulesPath)
+	if err != nil {
+		return nil, err
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("resolving root: %w", err)
+	}
+	svc := trace.NewService(trace.DefaultServiceConfig())
+	resp, err := svc.Init(ctx, absRoot, languages, excludes)
+	if err != nil {
+		return nil, fmt.Errorf("building graph: %w", err)
+	}
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		return nil, fmt.Errorf("loading graph: %w", err)
+	}
+	return assertions.Evaluate(ctx, cached.Graph, rs)
+}
+
+// printReport writes one line per rule and the violations of failing
+// rules, e.g.
+//
+//	FAIL api-no-db (1 violation)
+//	  pkg/api/handler.go:4: package api (pkg/api/handler.go:1) imports external example.com/shop/db
+//	PASS handler-names
+func printReport(w io.Writer, report *assertions.Report) error {
+	byRule := make(map[string][]assertions.Violation)
+	for _, v := range report.Violations {
+		byRule[v.Rule] = append(byRule[v.Rule], v)
+	}
+	var b strings.Builder
+	for _, r := range report.Rules {
+		if r.Passed {
+			fmt.Fprintf(&b, "PASS %s\n", r.Name)
+			continue
+		}
+		noun := "violations"
+		if r.Violations == 1 {
+			noun = "violation"
+		}
+		fmt.Fprintf(&b, "FAIL %s (%d %s)\n", r.Name, r.Violations, noun)
+		if r.Description != "" {
+			fmt.Fprintf(&b, "  %s\n", r.Description)
+		}
+		for _, v := range byRule[r.Name] {
+			fmt.Fprintf(&b, "  %s: %s\n", location(v), v.Message)
+		}
+		if r.Truncated {
+			fmt.Fprintf(&b, "  ... %d more\n", r.Violations-len(byRule[r.Name]))
+		}
+	}
+	fmt.Fprintf(&b, "%d rules, %d violations\n", len(report.Rules), report.ViolationCount)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// location returns the file:line of a violation's edge, or of its first
+// symbol.
+func location(v assertions.Violation) string {
+	if v.Edge != nil && v.Edge.FilePath != "" {
+		return fmt.Sprintf("%s:%d", v.Edge.FilePath, v.Edge.Line)
+	}
+	if s := v.Symbols[0]; s.FilePath != "" {
+		return fmt.Sprintf("%s:%d", s.FilePath, s.Line)
+	}
+	return v.Symbols[0].ID
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,267 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package main implements trace-bench, which runs the graph builder
+// benchmarks and checks them against a stored baseline.
+//
+// Description:
+//
+//	Compiles the test binary of a package, runs each matching benchmark in
+//	its own process with -benchmem, and writes a JSON report with ns/op,
+//	B/op, allocs/op and the peak RSS of the process. With -baseline, the
+//	report is compared with a previous one and the command exits 1 if any
+//	compared metric is more than -threshold worse. The default suite is
+//	the BenchmarkSynthetic_ benchmarks of services/trace/graph, which run
+//	over seeded synthetic corpora so results compare across machines.
+//
+// Usage:
+//
+//	trace-bench [flags]
+//	  -pkg string        Package to benchmark (default ./services/trace/graph)
+//	  -bench string      Benchmark regexp (default ^BenchmarkSynthetic_)
+//	  -benchtime string  Passed to -test.benchtime (default: go test default)
+//	  -count int         Runs per benchmark; the best of each metric is kept (default 1)
+//	  -out string        Report file (default: stdout)
+//	  -baseline string   Baseline report to compare against
+//	  -threshold float   Allowed regression, as a fraction (default 0.15)
+//	  -metrics string    Compared metrics (default ns_per_op,allocs_per_op,peak_rss_bytes)
+//	  -timeout duration  Timeout for the whole run (default 30m)
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errRegression is returned when the run regressed against the baseline.
+var errRegression = errors.New("benchmarks regressed against the baseline")
+
+// options are the command line flags.
+type options struct {
+	pkg       string
+	bench     string
+	benchtime string
+	count     int
+	out       string
+	baseline  string
+	threshold float64
+	metrics   []string
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.pkg, "pkg", "./services/trace/graph", "Package to benchmark")
+	flag.StringVar(&opts.bench, "bench", "^BenchmarkSynthetic_", "Benchmark regexp")
+	flag.StringVar(&opts.benchtime, "benchtime", "", "Passed to -test.benchtime (default: go test default)")
+	flag.IntVar(&opts.count, "count", 1, "Runs per benchmark; the best of each metric is kept")
+	flag.StringVar(&opts.out, "out", "", "Report file (default: stdout)")
+	flag.StringVar(&opts.baseline, "baseline", "", "Baseline report to compare against")
+	flag.Float64Var(&opts.threshold, "threshold", 0.15, "Allowed regression, as a fraction")
+	metrics := flag.String("metrics", strings.Join([]string{metricNsPerOp, metricAllocsPerOp, metricPeakRSS}, ","), "Compared metrics")
+	timeout := flag.Duration("timeout", 30*time.Minute, "Timeout for the whole run")
+	flag.Parse()
+
+	var err error
+	opts.metrics, err = parseMetrics(*metrics)
+	if err == nil && opts.threshold < 0 {
+		err = fmt.Errorf("threshold must not be negative")
+	}
+	if err == nil && opts.count < 1 {
+		err = fmt.Errorf("count must be at least 1")
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace-bench: %v\n", err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, *timeout)
+	defer cancelTimeout()
+
+	if err := run(ctx, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "trace-bench: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run benchmarks opts.pkg, writes the report and compares it with the
+// baseline.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and the run timeout.
+//	opts - The command line flags.
+//
+// Outputs:
+//
+//	error - errRegression if a metric regressed; non-nil if building,
+//	  running or writing failed.
+func run(ctx context.Context, opts options) error {
+	// Read the baseline first so a bad path fails before the long run.
+	var baseline *Report
+	if opts.baseline != "" {
+		data, err := os.ReadFile(opts.baseline)
+		if err != nil {
+			return fmt.Errorf("reading baseline: %w", err)
+		}
+		baseline = &Report{}
+		if err := json.Unmarshal(data, baseline); err != nil {
+			return fmt.Errorf("parsing baseline %s: %w", opts.baseline, err)
+		}
+	}
+
+	tmp, err := os.MkdirTemp("", "trace-bench-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	binary := filepath.Join(tmp, "bench.test")
+	if out, err := exec.CommandContext(ctx, "go", "test", "-c", "-o", binary, opts.pkg).CombinedOutput(); err != nil {
+		return fmt.Errorf("compiling %s: %w\n%s", opts.pkg, err, out)
+	}
+	dirOut, err := exec.CommandContext(ctx, "go", "list", "-f", "{{.Dir}}", opts.pkg).Output()
+	if err != nil {
+		return fmt.Errorf("locating %s: %w", opts.pkg, err)
+	}
+	pkgDir := strings.TrimSpace(string(dirOut))
+
+	names, err := listBenchmarks(ctx, binary, pkgDir, opts.bench)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("no benchmarks in %s match %q", opts.pkg, opts.bench)
+	}
+
+	report := Report{
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+		CPUs:      runtime.NumCPU(),
+		Package:   opts.pkg,
+	}
+	for _, name := range names {
+		var runs [][]Result
+		for i := 0; i < opts.count; i++ {
+			fmt.Fprintf(os.Stderr, "running %s (%d/%d)\n", name, i+1, opts.count)
+			results, err := runBenchmark(ctx, binary, pkgDir, name, opts.benchtime)
+			if err != nil {
+				return err
+			}
+			runs = append(runs, results)
+		}
+		report.Benchmarks = append(report.Benchmarks, bestOf(runs)...)
+	}
+	sort.Slice(report.Benchmarks, func(i, j int) bool { return report.Benchmarks[i].Name < report.Benchmarks[j].Name })
+	report.CreatedAtMilli = time.Now().UnixMilli()
+
+	if err := writeReport(report, opts.out); err != nil {
+		return err
+	}
+
+	if baseline == nil {
+		return nil
+	}
+	regressions := compareReports(*baseline, report, opts.metrics, opts.threshold)
+	for _, r := range regressions {
+		fmt.Fprintf(os.Stderr, "REGRESSION %s %s: %.0f -> %.0f (%+.1f%%)\n",
+			r.Name, r.Metric, r.Baseline, r.Current, (r.Ratio()-1)*100)
+	}
+	if len(regressions) > 0 {
+		return fmt.Errorf("%w: %d metrics more than %.0f%% worse", errRegression, len(regressions), opts.threshold*100)
+	}
+	fmt.Fprintf(os.Stderr, "no regressions over %.0f%% against %s\n", opts.threshold*100, opts.baseline)
+	return nil
+}
+
+// listBenchmarks returns the top-level benchmarks of the test binary that
+// match pattern.
+func listBenchmarks(ctx context.Context, binary, dir, pattern string) ([]string, error) {
+	cmd := exec.CommandContext(ctx, binary, "-test.list", pattern)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("listing benchmarks: %w", err)
+	}
+	var names []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "Benchmark") {
+			names = append(names, line)
+		}
+	}
+	return names, nil
+}
+
+// runBenchmark runs one top-level benchmark and its sub-benchmarks in a
+// process of its own, so its peak RSS is not inflated by the others.
+//
+// Outputs:
+//
+//	[]Result - The results, with the process's peak RSS.
+//	error - Non-nil if the benchmark failed or printed no results.
+func runBenchmark(ctx context.Context, binary, dir, name, benchtime string) ([]Result, error) {
+	args := []string{"-test.run", "^$", "-test.bench", "^" + name + "$", "-test.benchmem"}
+	if benchtime != "" {
+		args = append(args, "-test.benchtime", benchtime)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s: %w\n%s%s", name, err, stdout.String(), stderr.String())
+	}
+
+	results, err := parseBenchOutput(&stdout)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("%s: no results in output:\n%s", name, stdout.String())
+	}
+	rss := peakRSS(cmd.ProcessState)
+	for i := range results {
+		results[i].PeakRSSBytes = rss
+	}
+	return results, nil
+}
+
+// writeReport writes the report as indented JSON to path, or to stdout if
+// path is empty.
+func writeReport(report Report, path string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
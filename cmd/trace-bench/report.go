@@ -0,0 +1,235 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Metric names, as used in the JSON report and the -metrics flag.
+const (
+	metricNsPerOp     = "ns_per_op"
+	metricAllocsPerOp = "allocs_per_op"
+	metricPeakRSS     = "peak_rss_bytes"
+)
+
+// Report is the JSON output of a benchmark run.
+type Report struct {
+	// GoVersion, GOOS, GOARCH and CPUs describe the machine the run was on.
+	GoVersion string `json:"go_version"`
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+	CPUs      int    `json:"cpus"`
+
+	// Package is the benchmarked package.
+	Package string `json:"package"`
+
+	// CreatedAtMilli is when the run finished (Unix milliseconds UTC).
+	CreatedAtMilli int64 `json:"created_at"`
+
+	// Benchmarks are the results, sorted by name.
+	Benchmarks []Result `json:"benchmarks"`
+}
+
+// Result is the result of one benchmark.
+type Result struct {
+	// Name is the benchmark name without the GOMAXPROCS suffix, so
+	// results compare across machines.
+	Name string `json:"name"`
+
+	Iterations  int64   `json:"iterations"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	BytesPerOp  float64 `json:"bytes_per_op"`
+	AllocsPerOp float64 `json:"allocs_per_op"`
+
+	// PeakRSSBytes is the peak resident set size of the process that ran
+	// the benchmark. Sub-benchmarks of one benchmark share a process and
+	// report the same peak. Zero where the platform does not report it.
+	PeakRSSBytes int64 `json:"peak_rss_bytes"`
+}
+
+// metric returns the named metric of r.
+func (r Result) metric(name string) float64 {
+	switch name {
+	case metricNsPerOp:
+		return r.NsPerOp
+	case metricAllocsPerOp:
+		return r.AllocsPerOp
+	case metricPeakRSS:
+		return float64(r.PeakRSSBytes)
+	}
+	return 0
+}
+
+// benchLine matches a result line of go test -bench output.
+var benchLine = regexp.MustCompile(`^(Benchmark\S+)\s+(\d+)\s+(.*)$`)
+
+// procsSuffix is the -GOMAXPROCS suffix go test adds to benchmark names.
+var procsSuffix = regexp.MustCompile(`-\d+$`)
+
+// parseBenchOutput parses the result lines of go test -bench output.
+//
+// Inputs:
+//
+//	r - The output of the test binary.
+//
+// Outputs:
+//
+//	[]Result - The results, in output order, without peak RSS.
+//	error - Non-nil if reading fails or a value is malformed.
+func parseBenchOutput(r io.Reader) ([]Result, error) {
+	var results []Result
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		m := benchLine.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if m == nil {
+			continue
+		}
+		iterations, err := strconv.ParseInt(m[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: iterations: %w", m[1], err)
+		}
+		res := Result{Name: procsSuffix.ReplaceAllString(m[1], ""), Iterations: iterations}
+
+		// The rest is value-unit pairs: "123 ns/op 45 B/op 6 allocs/op".
+		fields := strings.Fields(m[3])
+		for i := 0; i+1 < len(fields); i += 2 {
+			value, err := strconv.ParseFloat(fields[i], 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", m[1], fields[i+1], err)
+			}
+			switch fields[i+1] {
+			case "ns/op":
+				res.NsPerOp = value
+			case "B/op":
+				res.BytesPerOp = value
+			case "allocs/op":
+				res.AllocsPerOp = value
+			}
+		}
+		results = append(results, res)
+	}
+	return results, scanner.Err()
+}
+
+// bestOf merges repeated runs of the same benchmarks, keeping the lowest
+// value of each metric to filter out noise from other load on the machine.
+//
+// Outputs:
+//
+//	[]Result - One result per name, in the order of the first run.
+func bestOf(runs [][]Result) []Result {
+	var merged []Result
+	at := make(map[string]int)
+	for _, results := range runs {
+		for _, r := range results {
+			i, ok := at[r.Name]
+			if !ok {
+				at[r.Name] = len(merged)
+				merged = append(merged, r)
+				continue
+			}
+			best := &merged[i]
+			best.Iterations += r.Iterations
+			best.NsPerOp = math.Min(best.NsPerOp, r.NsPerOp)
+			best.BytesPerOp = math.Min(best.BytesPerOp, r.BytesPerOp)
+			best.AllocsPerOp = math.Min(best.AllocsPerOp, r.AllocsPerOp)
+			if r.PeakRSSBytes > 0 && (best.PeakRSSBytes == 0 || r.PeakRSSBytes < best.PeakRSSBytes) {
+				best.PeakRSSBytes = r.PeakRSSBytes
+			}
+		}
+	}
+	return merged
+}
+
+// Regression is a metric that got worse than the threshold allows.
+type Regression struct {
+	Name     string
+	Metric   string
+	Baseline float64
+	Current  float64
+}
+
+// Ratio is the current value relative to the baseline.
+func (r Regression) Ratio() float64 {
+	return r.Current / r.Baseline
+}
+
+// compareReports finds the metrics of current more than threshold worse
+// than in baseline.
+//
+// Description:
+//
+//	Benchmarks missing from either report and metrics that are zero in
+//	the baseline are skipped.
+//
+// Inputs:
+//
+//	baseline, current - The reports.
+//	metrics - The metrics to compare.
+//	threshold - The allowed increase, as a fraction (0.15 for 15%).
+//
+// Outputs:
+//
+//	[]Regression - The regressions, sorted by name and metric.
+func compareReports(baseline, current Report, metrics []string, threshold float64) []Regression {
+	base := make(map[string]Result, len(baseline.Benchmarks))
+	for _, r := range baseline.Benchmarks {
+		base[r.Name] = r
+	}
+	var regressions []Regression
+	for _, cur := range current.Benchmarks {
+		old, ok := base[cur.Name]
+		if !ok {
+			continue
+		}
+		for _, metric := range metrics {
+			was, now := old.metric(metric), cur.metric(metric)
+			if was > 0 && now > was*(1+threshold) {
+				regressions = append(regressions, Regression{Name: cur.Name, Metric: metric, Baseline: was, Current: now})
+			}
+		}
+	}
+	sort.Slice(regressions, func(i, j int) bool {
+		if regressions[i].Name != regressions[j].Name {
+			return regressions[i].Name < regressions[j].Name
+		}
+		return regressions[i].Metric < regressions[j].Metric
+	})
+	return regressions
+}
+
+// parseMetrics parses the -metrics flag.
+func parseMetrics(s string) ([]string, error) {
+	var metrics []string
+	for _, m := range strings.Split(s, ",") {
+		m = strings.TrimSpace(m)
+		switch m {
+		case "":
+			continue
+		case metricNsPerOp, metricAllocsPerOp, metricPeakRSS:
+			metrics = append(metrics, m)
+		default:
+			return nil, fmt.Errorf("unknown metric %q (want %s, %s or %s)", m, metricNsPerOp, metricAllocsPerOp, metricPeakRSS)
+		}
+	}
+	if len(metrics) == 0 {
+		return nil, fmt.Errorf("no metrics to compare")
+	}
+	return metrics, nil
+}
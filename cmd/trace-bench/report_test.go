@@ -0,0 +1,95 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleOutput = `goos: linux
+goarch: amd64
+pkg: github.com/AleutianAI/AleutianFOSS/services/trace/graph
+BenchmarkSynthetic_Build/files=200-8            	      30	  40795120 ns/op	 9111720 B/op	  139515 allocs/op
+BenchmarkSynthetic_FindCallersWithInheritance/files=200-8         	   72000	     16314 ns/op	    3194 B/op	      23 allocs/op
+BenchmarkNoMem-8   	 1000	 12.5 ns/op
+PASS
+`
+
+func TestParseBenchOutput(t *testing.T) {
+	results, err := parseBenchOutput(strings.NewReader(sampleOutput))
+	if err != nil {
+		t.Fatalf("parseBenchOutput: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("got %d results, want 3: %+v", len(results), results)
+	}
+	build := results[0]
+	if build.Name != "BenchmarkSynthetic_Build/files=200" || build.Iterations != 30 ||
+		build.NsPerOp != 40795120 || build.BytesPerOp != 9111720 || build.AllocsPerOp != 139515 {
+		t.Errorf("build result = %+v", build)
+	}
+	if results[2].Name != "BenchmarkNoMem" || results[2].NsPerOp != 12.5 || results[2].AllocsPerOp != 0 {
+		t.Errorf("result without -benchmem = %+v", results[2])
+	}
+}
+
+func TestCompareReports(t *testing.T) {
+	baseline := Report{Benchmarks: []Result{
+		{Name: "BenchmarkA", NsPerOp: 100, AllocsPerOp: 10, PeakRSSBytes: 1000},
+		{Name: "BenchmarkB", NsPerOp: 100, AllocsPerOp: 10},
+		{Name: "BenchmarkGone", NsPerOp: 100},
+	}}
+	current := Report{Benchmarks: []Result{
+		{Name: "BenchmarkA", NsPerOp: 114, AllocsPerOp: 20, PeakRSSBytes: 1200},
+		{Name: "BenchmarkB", NsPerOp: 50, AllocsPerOp: 10, PeakRSSBytes: 5000},
+		{Name: "BenchmarkNew", NsPerOp: 1e9},
+	}}
+
+	metrics := []string{metricNsPerOp, metricAllocsPerOp, metricPeakRSS}
+	regressions := compareReports(baseline, current, metrics, 0.15)
+	if len(regressions) != 2 {
+		t.Fatalf("regressions = %+v, want allocs and RSS of BenchmarkA", regressions)
+	}
+	if regressions[0].Metric != metricAllocsPerOp || regressions[0].Ratio() != 2 {
+		t.Errorf("first regression = %+v", regressions[0])
+	}
+	if regressions[1].Metric != metricPeakRSS {
+		t.Errorf("second regression = %+v", regressions[1])
+	}
+
+	// ns/op at +14% is within the threshold; only gate on time here.
+	if got := compareReports(baseline, current, []string{metricNsPerOp}, 0.15); len(got) != 0 {
+		t.Errorf("ns/op regressions = %+v, want none", got)
+	}
+}
+
+func TestBestOf(t *testing.T) {
+	runs := [][]Result{
+		{{Name: "BenchmarkA", Iterations: 10, NsPerOp: 120, AllocsPerOp: 5, PeakRSSBytes: 2000}},
+		{{Name: "BenchmarkA", Iterations: 12, NsPerOp: 100, AllocsPerOp: 6, PeakRSSBytes: 1500}},
+	}
+	got := bestOf(runs)
+	if len(got) != 1 || got[0].NsPerOp != 100 || got[0].AllocsPerOp != 5 || got[0].PeakRSSBytes != 1500 || got[0].Iterations != 22 {
+		t.Errorf("bestOf = %+v", got)
+	}
+}
+
+func TestParseMetrics(t *testing.T) {
+	if got, err := parseMetrics("ns_per_op, allocs_per_op"); err != nil || len(got) != 2 {
+		t.Errorf("parseMetrics = %v, %v", got, err)
+	}
+	for _, bad := range []string{"", "ns/op"} {
+		if _, err := parseMetrics(bad); err == nil {
+			t.Errorf("parseMetrics(%q) succeeded", bad)
+		}
+	}
+}
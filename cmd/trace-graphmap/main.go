@@ -0,0 +1,118 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package main implements trace-graphmap, which writes a project's code
+// graph as a memory-mapped graph file.
+//
+// Description:
+//
+//	Builds the graph for a project root exactly as the Trace service does
+//	and writes it in the compact read-only format opened by
+//	graph.OpenMappedGraph. Several processes can then map the same file
+//	and share its pages instead of each building or loading its own heap
+//	copy of the graph.
+//
+// Usage:
+//
+//	trace-graphmap -root <dir> -out <file> [flags]
+//	  -root string       Project root to build the graph from (required)
+//	  -out string        Output file (required)
+//	  -languages string  Comma-separated languages (default: all supported)
+//	  -exclude string    Comma-separated glob patterns to exclude
+//	  -timeout duration  Build timeout (default 10m)
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	trace "github.com/AleutianAI/AleutianFOSS/services/trace"
+)
+
+func main() {
+	root := flag.String("root", "", "Project root to build the graph from (required)")
+	out := flag.String("out", "", "Output file (required)")
+	languages := flag.String("languages", "", "Comma-separated languages (default: all supported)")
+	exclude := flag.String("exclude", "", "Comma-separated glob patterns to exclude")
+	timeout := flag.Duration("timeout", 10*time.Minute, "Build timeout")
+	flag.Parse()
+
+	if *root == "" || *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, *timeout)
+	defer cancelTimeout()
+
+	if err := run(ctx, *root, *out, splitList(*languages), splitList(*exclude)); err != nil {
+		fmt.Fprintf(os.Stderr, "trace-graphmap: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run builds the graph for root and writes it to out.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and the build timeout.
+//	root - Project root.
+//	out - Output file path.
+//	languages - Languages to parse; nil for the service default.
+//	excludes - Glob patterns to exclude.
+//
+// Outputs:
+//
+//	error - Non-nil if the build or the write failed.
+func run(ctx context.Context, root, out string, languages, excludes []string) error {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("resolving root: %w", err)
+	}
+	svc := trace.NewService(trace.DefaultServiceConfig())
+	resp, err := svc.Init(ctx, absRoot, languages, excludes)
+	if err != nil {
+		return fmt.Errorf("building graph: %w", err)
+	}
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		return fmt.Errorf("loading graph: %w", err)
+	}
+	if err := cached.Graph.WriteMapped(ctx, out); err != nil {
+		return fmt.Errorf("writing %s: %w", out, err)
+	}
+
+	info, err := os.Stat(out)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s: %d nodes, %d edges, %d bytes\n",
+		out, cached.Graph.NodeCount(), cached.Graph.EdgeCount(), info.Size())
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
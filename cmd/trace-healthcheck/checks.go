@@ -0,0 +1,217 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	trace "github.com/AleutianAI/AleutianFOSS/services/trace"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// Smoke check names, as reported in CheckResult.Name.
+const (
+	checkSymbolLookup = "symbol_lookup"
+	checkCallers      = "callers"
+	checkSearch       = "search"
+)
+
+// maxSearchResults bounds the rows a search check requests.
+const maxSearchResults = 20
+
+// CheckResult is the outcome of one smoke query.
+type CheckResult struct {
+	Name string `json:"name"`
+
+	// Passed is false if the query failed or its results were empty or
+	// malformed. Skipped checks pass.
+	Passed  bool `json:"passed"`
+	Skipped bool `json:"skipped,omitempty"`
+
+	// Results is the number of results the query returned.
+	Results int `json:"results"`
+
+	LatencyMs int64 `json:"latency_ms"`
+
+	// Detail explains a failure or skip.
+	Detail string `json:"detail,omitempty"`
+}
+
+// smokeOptions configure runSmokeChecks.
+type smokeOptions struct {
+	graphID string
+
+	// symbol must exist and have callers; empty skips both checks.
+	symbol string
+
+	// search is a name regex that must match at least one symbol.
+	search *regexp.Regexp
+
+	// files is the graph's manifest. Symbols must be declared in it.
+	files map[string]string
+}
+
+// queryResponse is the AgenticResponse of POST /v1/trace/graph/query.
+type queryResponse struct {
+	Result graph.StructuredQueryResult `json:"result"`
+}
+
+// runSmokeChecks runs the symbol lookup, callers and search checks.
+//
+// Description:
+//
+//	Each check asserts that its query succeeds and returns non-empty,
+//	well-formed results: symbols have an ID and a name, and symbols with a
+//	file are declared in a file of the manifest.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	c - The API client.
+//	opts - The graph and the queries to run.
+//
+// Outputs:
+//
+//	[]CheckResult - One result per check, in a fixed order.
+func runSmokeChecks(ctx context.Context, c *client, opts smokeOptions) []CheckResult {
+	var checks []CheckResult
+	if opts.symbol == "" {
+		checks = append(checks,
+			CheckResult{Name: checkSymbolLookup, Passed: true, Skipped: true, Detail: "no -symbol given"},
+			CheckResult{Name: checkCallers, Passed: true, Skipped: true, Detail: "no -symbol given"},
+		)
+	} else {
+		checks = append(checks,
+			runCheck(checkSymbolLookup, func() (int, error) { return checkSymbol(ctx, c, opts) }),
+			runCheck(checkCallers, func() (int, error) { return checkSymbolCallers(ctx, c, opts) }),
+		)
+	}
+	return append(checks, runCheck(checkSearch, func() (int, error) { return checkNameSearch(ctx, c, opts) }))
+}
+
+// runCheck times fn and records its outcome.
+func runCheck(name string, fn func() (int, error)) CheckResult {
+	start := time.Now()
+	n, err := fn()
+	result := CheckResult{Name: name, Passed: err == nil, Results: n, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Detail = err.Error()
+	}
+	return result
+}
+
+// checkSymbol finds the symbol by exact name and fetches the first match
+// by ID, which must return the same symbol.
+func checkSymbol(ctx context.Context, c *client, opts smokeOptions) (int, error) {
+	rows, err := queryNames(ctx, c, opts.graphID, "^"+regexp.QuoteMeta(opts.symbol)+"$")
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no symbol named %q", opts.symbol)
+	}
+	id, _ := rows[0]["id"].(string)
+	if id == "" {
+		return len(rows), errors.New("query row has no id")
+	}
+
+	var resp trace.SymbolResponse
+	if err := c.get(ctx, "/symbol/"+url.PathEscape(id), url.Values{"graph_id": {opts.graphID}}, &resp); err != nil {
+		return len(rows), err
+	}
+	if resp.Symbol == nil || resp.Symbol.ID != id || resp.Symbol.Name != opts.symbol {
+		return len(rows), fmt.Errorf("lookup of %s returned %+v", id, resp.Symbol)
+	}
+	return len(rows), saneSymbol(resp.Symbol.ID, resp.Symbol.Name, resp.Symbol.FilePath, opts.files)
+}
+
+// checkSymbolCallers requires at least one well-formed caller.
+func checkSymbolCallers(ctx context.Context, c *client, opts smokeOptions) (int, error) {
+	var resp trace.CallersResponse
+	query := url.Values{"graph_id": {opts.graphID}, "function": {opts.symbol}}
+	if err := c.get(ctx, "/callers", query, &resp); err != nil {
+		return 0, err
+	}
+	if len(resp.Callers) == 0 {
+		return 0, fmt.Errorf("%q has no callers", opts.symbol)
+	}
+	for _, caller := range resp.Callers {
+		if caller == nil {
+			return len(resp.Callers), errors.New("nil caller")
+		}
+		if err := saneSymbol(caller.ID, caller.Name, caller.FilePath, opts.files); err != nil {
+			return len(resp.Callers), err
+		}
+	}
+	return len(resp.Callers), nil
+}
+
+// checkNameSearch requires symbols matching the search regex, all of
+// whose names match it.
+func checkNameSearch(ctx context.Context, c *client, opts smokeOptions) (int, error) {
+	rows, err := queryNames(ctx, c, opts.graphID, opts.search.String())
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, fmt.Errorf("no symbol matches %q", opts.search)
+	}
+	for _, row := range rows {
+		id, _ := row["id"].(string)
+		name, _ := row["name"].(string)
+		file, _ := row["file_path"].(string)
+		if !opts.search.MatchString(name) {
+			return len(rows), fmt.Errorf("result %q does not match %q", name, opts.search)
+		}
+		if err := saneSymbol(id, name, file, opts.files); err != nil {
+			return len(rows), err
+		}
+	}
+	return len(rows), nil
+}
+
+// queryNames runs a structured query for symbols whose name matches
+// nameRegex and returns the rows.
+func queryNames(ctx context.Context, c *client, graphID, nameRegex string) ([]map[string]any, error) {
+	req := trace.GraphQueryRequest{
+		GraphID: graphID,
+		Query: graph.StructuredQuery{
+			Match:  graph.NodeFilter{NameRegex: nameRegex},
+			Fields: []string{"id", "name", "file_path"},
+			Limits: graph.QueryLimits{MaxResults: maxSearchResults},
+		},
+	}
+	var resp queryResponse
+	if err := c.post(ctx, "/graph/query", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Result.Rows, nil
+}
+
+// saneSymbol checks that a result symbol has an ID and a name, and that
+// its file, if any, is in the manifest. Placeholder symbols for external
+// code have no file.
+func saneSymbol(id, name, file string, files map[string]string) error {
+	if id == "" || name == "" {
+		return fmt.Errorf("result without id or name (id %q, name %q)", id, name)
+	}
+	if file != "" && files != nil {
+		if _, ok := files[file]; !ok {
+			return fmt.Errorf("%s is declared in %s, which is not in the graph's manifest", id, file)
+		}
+	}
+	return nil
+}
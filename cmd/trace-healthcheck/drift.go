@@ -0,0 +1,231 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ignore"
+)
+
+// DriftReport compares the files a graph was built from with the project
+// on disk.
+type DriftReport struct {
+	// GraphFiles is the number of files in the graph's manifest.
+	GraphFiles int `json:"graph_files"`
+
+	// DiskFiles is the number of source files discovered on disk.
+	DiskFiles int `json:"disk_files"`
+
+	// Changed, Added and Removed count files whose content differs from
+	// the manifest, files on disk missing from it, and files in it missing
+	// from disk.
+	Changed int `json:"changed"`
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+
+	// Ratio is (Changed + Added + Removed) / GraphFiles; 1 when the
+	// manifest is empty and anything drifted.
+	Ratio float64 `json:"ratio"`
+
+	// MaxRatio is the largest tolerated Ratio (-max-drift).
+	MaxRatio float64 `json:"max_ratio"`
+
+	// ChangedSample, AddedSample and RemovedSample list the first paths of
+	// each category, sorted.
+	ChangedSample []string `json:"changed_sample,omitempty"`
+	AddedSample   []string `json:"added_sample,omitempty"`
+	RemovedSample []string `json:"removed_sample,omitempty"`
+}
+
+// Drifted returns the number of changed, added and removed files.
+func (d *DriftReport) Drifted() int {
+	return d.Changed + d.Added + d.Removed
+}
+
+// Exceeded reports whether the drift is above the tolerated ratio.
+func (d *DriftReport) Exceeded() bool {
+	return d.Drifted() > 0 && d.Ratio > d.MaxRatio
+}
+
+// driftOptions configure measureDrift.
+type driftOptions struct {
+	// extensions are the file extensions discovered, e.g. ".go".
+	extensions []string
+
+	// excludes are glob patterns matched against directory names and
+	// relative paths, as the server's discovery does.
+	excludes []string
+
+	// maxRatio is copied to DriftReport.MaxRatio.
+	maxRatio float64
+
+	// sample is the number of paths listed per category.
+	sample int
+}
+
+// measureDrift compares a manifest with the files under root.
+//
+// Description:
+//
+//	Every manifest entry is re-hashed from disk (SHA-256 of the content,
+//	as ast.ParseResult.Hash), so changed and removed files are exact even
+//	when discovery rules differ from the server's. Added files are found by
+//	walking root with the server's default discovery rules: language
+//	extensions, exclude patterns, .gitignore and .traceignore.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	root - Project root on this machine.
+//	manifest - Relative path to content hash, from the server.
+//	opts - Discovery rules and report limits.
+//
+// Outputs:
+//
+//	*DriftReport - The comparison.
+//	error - Non-nil if root cannot be walked or ignore files cannot be read.
+func measureDrift(ctx context.Context, root string, manifest map[string]string, opts driftOptions) (*DriftReport, error) {
+	report := &DriftReport{GraphFiles: len(manifest), MaxRatio: opts.maxRatio}
+
+	var changed, added, removed []string
+	for rel, want := range manifest {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		got, err := hashFile(filepath.Join(root, filepath.FromSlash(rel)))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			removed = append(removed, rel)
+		case err != nil:
+			return nil, err
+		case got != want:
+			changed = append(changed, rel)
+		}
+	}
+
+	disk, err := discoverFiles(ctx, root, opts)
+	if err != nil {
+		return nil, err
+	}
+	report.DiskFiles = len(disk)
+	for _, rel := range disk {
+		if _, ok := manifest[rel]; !ok {
+			added = append(added, rel)
+		}
+	}
+
+	report.Changed, report.Added, report.Removed = len(changed), len(added), len(removed)
+	report.ChangedSample = sample(changed, opts.sample)
+	report.AddedSample = sample(added, opts.sample)
+	report.RemovedSample = sample(removed, opts.sample)
+	if drifted := report.Drifted(); drifted > 0 {
+		report.Ratio = 1
+		if report.GraphFiles > 0 {
+			report.Ratio = float64(drifted) / float64(report.GraphFiles)
+		}
+	}
+	return report, nil
+}
+
+// hashFile returns the hex SHA-256 of a file's content.
+func hashFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// discoverFiles returns the slash-separated relative paths of the source
+// files under root, sorted.
+func discoverFiles(ctx context.Context, root string, opts driftOptions) ([]string, error) {
+	matcher, err := ignore.New(root, ignore.DefaultOptions())
+	if err != nil {
+		return nil, fmt.Errorf("loading ignore rules: %w", err)
+	}
+
+	var files []string
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if walkErr != nil {
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, path)
+		if relErr != nil || rel == "." {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" || matchesAny(opts.excludes, rel, d.Name()) {
+				return filepath.SkipDir
+			}
+			if ignored, _ := matcher.Match(rel, true); ignored {
+				return filepath.SkipDir
+			}
+			if loadErr := matcher.LoadDir(rel); loadErr != nil {
+				return fmt.Errorf("loading ignore rules: %w", loadErr)
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !slices.Contains(opts.extensions, filepath.Ext(rel)) {
+			return nil
+		}
+		if matchesAny(opts.excludes, rel, d.Name()) {
+			return nil
+		}
+		if ignored, _ := matcher.Match(rel, false); ignored {
+			return nil
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// matchesAny reports whether a glob pattern matches the relative path or
+// the base name.
+func matchesAny(patterns []string, rel, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// sample returns the first n of paths after sorting them.
+func sample(paths []string, n int) []string {
+	slices.Sort(paths)
+	if len(paths) > n {
+		paths = paths[:n]
+	}
+	return paths
+}
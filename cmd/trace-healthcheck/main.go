@@ -0,0 +1,313 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package main implements trace-healthcheck, which checks that a running
+// Trace server's graph matches a project on disk and answers queries.
+//
+// Description:
+//
+//	Fetches the graph's status and file manifest, hashes the project's
+//	files the way the parsers do (SHA-256 of the content) and reports the
+//	changed, added and removed files. With -refresh, drift above
+//	-max-drift rebuilds the graph through /v1/trace/init and is measured
+//	again. Smoke queries then look up a known symbol, list its callers and
+//	search symbol names; each must return non-empty, well-formed results.
+//	The report is printed as JSON.
+//
+// Usage:
+//
+//	trace-healthcheck -root <dir> [flags]
+//	  -root string         Project root on this machine (required)
+//	  -server string       Trace server URL (default: ALEUTIAN_TRACE_URL env or http://localhost:12217)
+//	  -server-root string  Project root as the server sees it (default: absolute -root)
+//	  -graph-id string     Graph to check (default: the graph of -server-root)
+//	  -exclude string      Comma-separated glob patterns excluded from discovery (default: the server's defaults)
+//	  -max-drift float     Largest tolerated fraction of drifted files (default 0.05)
+//	  -refresh             Rebuild the graph when drift exceeds -max-drift
+//	  -sample int          Paths listed per drift category (default 10)
+//	  -symbol string       Symbol that must exist and have callers (default: skip those checks)
+//	  -search string       Name regex that must match symbols (default ".")
+//	  -timeout duration    Overall timeout, including a refresh (default 10m)
+//
+// Exit status: 0 if the graph is healthy, 1 if the server is unreachable,
+// a check failed or the drift exceeds -max-drift, 2 for usage errors.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	trace "github.com/AleutianAI/AleutianFOSS/services/trace"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/bridge"
+)
+
+// Report is the JSON output of a health check.
+type Report struct {
+	// Passed is true if every check passed and the drift is tolerated.
+	Passed bool `json:"passed"`
+
+	Server      string `json:"server"`
+	ProjectRoot string `json:"project_root"`
+
+	// GraphID and ContentHash identify the graph checked; after a refresh,
+	// the rebuilt graph.
+	GraphID     string `json:"graph_id,omitempty"`
+	ContentHash string `json:"content_hash,omitempty"`
+
+	// Drift compares the graph with the files on disk; after a refresh,
+	// the rebuilt graph.
+	Drift *DriftReport `json:"drift,omitempty"`
+
+	// Refresh is set when the graph was rebuilt.
+	Refresh *RefreshReport `json:"refresh,omitempty"`
+
+	Checks []CheckResult `json:"checks"`
+
+	// Failures explains why Passed is false, one line per problem.
+	Failures []string `json:"failures,omitempty"`
+}
+
+// RefreshReport describes a rebuild triggered by drift.
+type RefreshReport struct {
+	// Drift is the drift that triggered the refresh.
+	Drift *DriftReport `json:"drift"`
+
+	PreviousID  string `json:"previous_id,omitempty"`
+	GraphID     string `json:"graph_id,omitempty"`
+	FilesParsed int    `json:"files_parsed"`
+	DurationMs  int64  `json:"duration_ms"`
+
+	// Error is set if the rebuild failed.
+	Error string `json:"error,omitempty"`
+}
+
+// options configure run.
+type options struct {
+	root       string
+	serverRoot string
+	graphID    string
+	excludes   []string
+	maxDrift   float64
+	refresh    bool
+	sample     int
+	symbol     string
+	search     *regexp.Regexp
+}
+
+func main() {
+	server := flag.String("server", "", "Trace server URL (default: ALEUTIAN_TRACE_URL env or "+bridge.DefaultTraceURL+")")
+	root := flag.String("root", "", "Project root on this machine (required)")
+	serverRoot := flag.String("server-root", "", "Project root as the server sees it (default: absolute -root)")
+	graphID := flag.String("graph-id", "", "Graph to check (default: the graph of -server-root)")
+	exclude := flag.String("exclude", "", "Comma-separated glob patterns excluded from discovery (default: the server's defaults)")
+	maxDrift := flag.Float64("max-drift", 0.05, "Largest tolerated fraction of drifted files")
+	refresh := flag.Bool("refresh", false, "Rebuild the graph when drift exceeds -max-drift")
+	sampleSize := flag.Int("sample", 10, "Paths listed per drift category")
+	symbol := flag.String("symbol", "", "Symbol that must exist and have callers (default: skip those checks)")
+	search := flag.String("search", ".", "Name regex that must match symbols")
+	timeout := flag.Duration("timeout", 10*time.Minute, "Overall timeout, including a refresh")
+	flag.Parse()
+
+	if *root == "" || *maxDrift < 0 || *sampleSize < 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	searchRe, err := regexp.Compile(*search)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace-healthcheck: invalid -search: %v\n", err)
+		os.Exit(2)
+	}
+	absRoot, err := filepath.Abs(*root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "trace-healthcheck: resolving root: %v\n", err)
+		os.Exit(2)
+	}
+	opts := options{
+		root:       absRoot,
+		serverRoot: *serverRoot,
+		graphID:    *graphID,
+		excludes:   splitList(*exclude),
+		maxDrift:   *maxDrift,
+		refresh:    *refresh,
+		sample:     *sampleSize,
+		symbol:     *symbol,
+		search:     searchRe,
+	}
+	if opts.serverRoot == "" {
+		opts.serverRoot = absRoot
+	}
+
+	baseURL := *server
+	if baseURL == "" {
+		baseURL = os.Getenv("ALEUTIAN_TRACE_URL")
+	}
+	if baseURL == "" {
+		baseURL = bridge.DefaultTraceURL
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+	ctx, cancelTimeout := context.WithTimeout(ctx, *timeout)
+	defer cancelTimeout()
+
+	c := &client{baseURL: strings.TrimSuffix(baseURL, "/"), http: &http.Client{}}
+	report := run(ctx, c, opts)
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		fmt.Fprintf(os.Stderr, "trace-healthcheck: %v\n", err)
+		os.Exit(1)
+	}
+	if !report.Passed {
+		os.Exit(1)
+	}
+}
+
+// run checks the graph and returns the report.
+//
+// Description:
+//
+//	Measures drift against the graph's manifest, rebuilds the graph if
+//	allowed and needed, then runs the smoke checks against the graph
+//	that is current. Every problem is recorded in Report.Failures rather
+//	than returned, so the caller always has a report to print.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and the overall timeout.
+//	c - The API client.
+//	opts - What to check.
+//
+// Outputs:
+//
+//	*Report - The outcome. Passed is false if anything failed.
+func run(ctx context.Context, c *client, opts options) *Report {
+	report := &Report{Server: c.baseURL, ProjectRoot: opts.root, Checks: []CheckResult{}}
+	fail := func(format string, args ...any) *Report {
+		report.Failures = append(report.Failures, fmt.Sprintf(format, args...))
+		return report
+	}
+
+	graphQuery := url.Values{"project_root": {opts.serverRoot}}
+	if opts.graphID != "" {
+		graphQuery = url.Values{"graph_id": {opts.graphID}}
+	}
+	var status trace.GraphStatusResponse
+	if err := c.get(ctx, "/graph/status", graphQuery, &status); err != nil {
+		return fail("graph status: %v", err)
+	}
+	report.GraphID, report.ContentHash = status.GraphID, status.ContentHash
+
+	manifest, drift, err := checkDrift(ctx, c, status.GraphID, opts)
+	if err != nil {
+		return fail("drift: %v", err)
+	}
+	report.Drift = drift
+
+	if drift.Exceeded() && opts.refresh {
+		report.Refresh = refreshGraph(ctx, c, status.GraphID, manifest.Languages, drift, opts)
+		if report.Refresh.Error != "" {
+			return fail("refresh: %s", report.Refresh.Error)
+		}
+		if err := c.get(ctx, "/graph/status", url.Values{"graph_id": {report.Refresh.GraphID}}, &status); err != nil {
+			return fail("graph status after refresh: %v", err)
+		}
+		report.GraphID, report.ContentHash = status.GraphID, status.ContentHash
+		if manifest, drift, err = checkDrift(ctx, c, status.GraphID, opts); err != nil {
+			return fail("drift after refresh: %v", err)
+		}
+		report.Drift = drift
+	}
+	if drift.Exceeded() {
+		fail("drift %.3f exceeds %.3f: %d changed, %d added, %d removed of %d files",
+			drift.Ratio, drift.MaxRatio, drift.Changed, drift.Added, drift.Removed, drift.GraphFiles)
+	}
+
+	report.Checks = runSmokeChecks(ctx, c, smokeOptions{
+		graphID: report.GraphID,
+		symbol:  opts.symbol,
+		search:  opts.search,
+		files:   manifest.Files,
+	})
+	for _, check := range report.Checks {
+		if !check.Passed {
+			fail("%s: %s", check.Name, check.Detail)
+		}
+	}
+
+	report.Passed = len(report.Failures) == 0
+	return report
+}
+
+// checkDrift fetches the graph's manifest and compares it with the files
+// under opts.root.
+func checkDrift(ctx context.Context, c *client, graphID string, opts options) (*trace.GraphManifestResponse, *DriftReport, error) {
+	var manifest trace.GraphManifestResponse
+	if err := c.get(ctx, "/graph/manifest", url.Values{"graph_id": {graphID}}, &manifest); err != nil {
+		return nil, nil, err
+	}
+	excludes := opts.excludes
+	if len(excludes) == 0 {
+		excludes = trace.DefaultExcludePatterns()
+	}
+	drift, err := measureDrift(ctx, opts.root, manifest.Files, driftOptions{
+		extensions: trace.LanguageExtensions(manifest.Languages),
+		excludes:   excludes,
+		maxRatio:   opts.maxDrift,
+		sample:     opts.sample,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return &manifest, drift, nil
+}
+
+// refreshGraph rebuilds the graph of opts.serverRoot with the languages
+// it was built with. The server refreshes incrementally when it can.
+func refreshGraph(ctx context.Context, c *client, graphID string, languages []string, drift *DriftReport, opts options) *RefreshReport {
+	refresh := &RefreshReport{Drift: drift, PreviousID: graphID}
+	req := trace.InitRequest{
+		ProjectRoot:     opts.serverRoot,
+		Languages:       languages,
+		ExcludePatterns: opts.excludes,
+	}
+	start := time.Now()
+	var resp trace.InitResponse
+	err := c.post(ctx, "/init", req, &resp)
+	refresh.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		refresh.Error = err.Error()
+		return refresh
+	}
+	refresh.GraphID, refresh.FilesParsed = resp.GraphID, resp.FilesParsed
+	return refresh
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"testing"
+
+	trace "github.com/AleutianAI/AleutianFOSS/services/trace"
+	"github.com/gin-gonic/gin"
+)
+
+// writeProject writes files under a new temporary project root.
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestMeasureDrift(t *testing.T) {
+	root := writeProject(t, map[string]string{
+		"same.go":             "package p\n",
+		"edited.go":           "package p // edited\n",
+		"new.go":              "package p\n",
+		"pkg/new_test.go":     "package p\n",
+		"vendor/dep/dep.go":   "package dep\n",
+		"ignored/skip.go":     "package skip\n",
+		"notes.txt":           "not source\n",
+		".gitignore":          "ignored/\n",
+		"build/generated.go":  "package build\n",
+		"pkg/handler/main.go": "package handler\n",
+	})
+	sameHash, err := hashFile(filepath.Join(root, "same.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	handlerHash, err := hashFile(filepath.Join(root, "pkg/handler/main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	manifest := map[string]string{
+		"same.go":             sameHash,
+		"pkg/handler/main.go": handlerHash,
+		"edited.go":           "0000",
+		"deleted.go":          "1111",
+	}
+
+	drift, err := measureDrift(context.Background(), root, manifest, driftOptions{
+		extensions: trace.LanguageExtensions([]string{"go"}),
+		excludes:   trace.DefaultExcludePatterns(),
+		maxRatio:   0.5,
+		sample:     10,
+	})
+	if err != nil {
+		t.Fatalf("measureDrift: %v", err)
+	}
+	want := &DriftReport{
+		GraphFiles: 4, DiskFiles: 4, Changed: 1, Added: 1, Removed: 1, Ratio: 0.75, MaxRatio: 0.5,
+		ChangedSample: []string{"edited.go"}, AddedSample: []string{"new.go"}, RemovedSample: []string{"deleted.go"},
+	}
+	if !reflect.DeepEqual(drift, want) {
+		t.Errorf("drift = %+v\nwant  %+v", drift, want)
+	}
+	if !drift.Exceeded() {
+		t.Error("drift of 0.75 must exceed 0.5")
+	}
+
+	empty, err := measureDrift(context.Background(), root, map[string]string{}, driftOptions{sample: 1})
+	if err != nil {
+		t.Fatalf("measureDrift: %v", err)
+	}
+	if empty.Drifted() != 0 || empty.Exceeded() {
+		t.Errorf("no extensions and an empty manifest must not drift: %+v", empty)
+	}
+}
+
+// newTestServer serves the Trace API for tests.
+func newTestServer(t *testing.T) *client {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	trace.RegisterRoutes(router.Group("/v1"), trace.NewHandlers(trace.NewService(trace.DefaultServiceConfig())))
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+	return &client{baseURL: srv.URL, http: srv.Client()}
+}
+
+func TestRun(t *testing.T) {
+	root := writeProject(t, map[string]string{
+		"main.go": "package main\n\nfunc main() {\n\thelper()\n}\n",
+		"util.go": "package main\n\nfunc helper() {}\n",
+	})
+	c := newTestServer(t)
+	ctx := context.Background()
+	if err := c.post(ctx, "/init", trace.InitRequest{ProjectRoot: root}, &trace.InitResponse{}); err != nil {
+		t.Fatalf("init: %v", err)
+	}
+	opts := options{root: root, serverRoot: root, maxDrift: 0, sample: 10, symbol: "helper", search: regexp.MustCompile("^(main|helper)$")}
+
+	report := run(ctx, c, opts)
+	if !report.Passed || report.Drift.Drifted() != 0 || report.Drift.GraphFiles != 2 || report.Refresh != nil {
+		t.Fatalf("healthy graph report: %+v", report)
+	}
+	for _, check := range report.Checks {
+		if !check.Passed || check.Skipped || check.Results == 0 {
+			t.Errorf("check %+v, want passed with results", check)
+		}
+	}
+
+	// Drift without -refresh fails; with it the graph is rebuilt.
+	if err := os.WriteFile(filepath.Join(root, "util.go"), []byte("package main\n\nfunc helper() { other() }\n\nfunc other() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report = run(ctx, c, opts)
+	if report.Passed || report.Drift.Changed != 1 || len(report.Failures) != 1 {
+		t.Fatalf("drifted report: %+v", report)
+	}
+
+	opts.refresh = true
+	report = run(ctx, c, opts)
+	if !report.Passed || report.Refresh == nil || report.Refresh.Drift.Changed != 1 || report.Drift.Drifted() != 0 {
+		t.Fatalf("refreshed report: %+v (refresh %+v)", report, report.Refresh)
+	}
+
+	// Failing smoke checks are reported.
+	opts.symbol = "missing"
+	report = run(ctx, c, opts)
+	if report.Passed || report.Checks[0].Passed || report.Checks[1].Passed || !report.Checks[2].Passed {
+		t.Errorf("missing symbol report: %+v", report.Checks)
+	}
+
+	// An unreachable server fails with a report.
+	down := &client{baseURL: "http://127.0.0.1:1", http: http.DefaultClient}
+	if report = run(ctx, down, opts); report.Passed || len(report.Failures) != 1 {
+		t.Errorf("unreachable server report: %+v", report)
+	}
+}
@@ -66,7 +66,7 @@ func main() {
 	telemetryShutdown, telemetryErr := telemetry.Init(context.Background(), telemetryCfg)
 	if telemetryErr != nil {
 		slog.Warn("Telemetry init failed, running without OTel",
-			slog.String("error", telemetryErr.Error()))
+			telemetry.SensitiveLogError("error", telemetryErr))
 	}
 	defer func() {
 		if telemetryShutdown != nil {
@@ -74,7 +74,7 @@ func finishToolSpan(ctx context.Context, span trace.Span, toolName string, resul
 	logger := telemetry.LoggerWithTrace(ctx, slog.Default())
 	if err != nil {
 		telemetry.RecordError(span, err)
-		logger.Error("MCP tool call failed", slog.String("tool", toolName), slog.String("error", err.Error()))
+		logger.Error("MCP tool call failed", slog.String("tool", toolName), telemetry.SensitiveLogError("error", err))
 		return
 	}
 	if result != nil && result.IsError {
@@ -182,7 +182,7 @@ func registerTraceTools(s *mcp.Server, b *bridge.ToolBridge) {
 			"Do NOT use if the project has already been initialized in this session.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input InitProjectInput) (*mcp.CallToolResult, any, error) {
 		ctx, span := startToolSpan(ctx, "trace_init_project",
-			attribute.String("mcp.param.project_root", input.ProjectRoot),
+			telemetry.SensitiveString("mcp.param.project_root", input.ProjectRoot),
 		)
 		defer span.End()
 
@@ -202,7 +202,7 @@ func registerTraceTools(s *mcp.Server, b *bridge.ToolBridge) {
 			"Do NOT use for finding what a function calls (use trace_find_callees instead).",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input FindCallersInput) (*mcp.CallToolResult, any, error) {
 		ctx, span := startToolSpan(ctx, "trace_find_callers",
-			attribute.String("mcp.param.function_name", input.FunctionName),
+			telemetry.SensitiveString("mcp.param.function_name", input.FunctionName),
 		)
 		defer span.End()
 
@@ -222,7 +222,7 @@ func registerTraceTools(s *mcp.Server, b *bridge.ToolBridge) {
 			"Do NOT use for finding who calls a function (use trace_find_callers instead).",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input FindCalleesInput) (*mcp.CallToolResult, any, error) {
 		ctx, span := startToolSpan(ctx, "trace_find_callees",
-			attribute.String("mcp.param.function_name", input.FunctionName),
+			telemetry.SensitiveString("mcp.param.function_name", input.FunctionName),
 		)
 		defer span.End()
 
@@ -242,7 +242,7 @@ func registerTraceTools(s *mcp.Server, b *bridge.ToolBridge) {
 			"Do NOT use for finding what interfaces a type implements.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input FindImplementationsInput) (*mcp.CallToolResult, any, error) {
 		ctx, span := startToolSpan(ctx, "trace_find_implementations",
-			attribute.String("mcp.param.interface_name", input.InterfaceName),
+			telemetry.SensitiveString("mcp.param.interface_name", input.InterfaceName),
 		)
 		defer span.End()
 
@@ -262,7 +262,7 @@ func registerTraceTools(s *mcp.Server, b *bridge.ToolBridge) {
 			"Do NOT use for finding relationships (use callers/callees/references instead).",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input FindSymbolInput) (*mcp.CallToolResult, any, error) {
 		ctx, span := startToolSpan(ctx, "trace_find_symbol",
-			attribute.String("mcp.param.name", input.Name),
+			telemetry.SensitiveString("mcp.param.name", input.Name),
 		)
 		defer span.End()
 
@@ -282,8 +282,8 @@ func registerTraceTools(s *mcp.Server, b *bridge.ToolBridge) {
 			"Do NOT use when you only need direct callers/callees.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input GetCallChainInput) (*mcp.CallToolResult, any, error) {
 		ctx, span := startToolSpan(ctx, "trace_get_call_chain",
-			attribute.String("mcp.param.from", input.From),
-			attribute.String("mcp.param.to", input.To),
+			telemetry.SensitiveString("mcp.param.from", input.From),
+			telemetry.SensitiveString("mcp.param.to", input.To),
 		)
 		defer span.End()
 
@@ -303,7 +303,7 @@ func registerTraceTools(s *mcp.Server, b *bridge.ToolBridge) {
 			"Do NOT use for call-graph analysis (use callers/callees instead).",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input FindReferencesInput) (*mcp.CallToolResult, any, error) {
 		ctx, span := startToolSpan(ctx, "trace_find_references",
-			attribute.String("mcp.param.symbol_name", input.SymbolName),
+			telemetry.SensitiveString("mcp.param.symbol_name", input.SymbolName),
 		)
 		defer span.End()
 
@@ -425,8 +425,8 @@ func registerTraceTools(s *mcp.Server, b *bridge.ToolBridge) {
 			"Do NOT use when you only need direct callers/callees of one function.",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input FindPathInput) (*mcp.CallToolResult, any, error) {
 		ctx, span := startToolSpan(ctx, "trace_find_path",
-			attribute.String("mcp.param.from", input.From),
-			attribute.String("mcp.param.to", input.To),
+			telemetry.SensitiveString("mcp.param.from", input.From),
+			telemetry.SensitiveString("mcp.param.to", input.To),
 		)
 		defer span.End()
 
@@ -78,7 +78,7 @@ func main() {
 	telemetryShutdown, telemetryErr := telemetry.Init(context.Background(), telemetryCfg)
 	if telemetryErr != nil {
 		slog.Warn("Telemetry init failed, running without OTel",
-			slog.String("error", telemetryErr.Error()))
+			telemetry.SensitiveLogError("error", telemetryErr))
 	}
 	defer func() {
 		if telemetryShutdown != nil {
@@ -98,7 +98,7 @@ func main() {
 		if cwd, err := os.Getwd(); err == nil {
 			resolvedProjectRoot = cwd
 			slog.Info("Defaulting project root to current directory",
-				slog.String("project_root", cwd))
+				telemetry.SensitiveLog("project_root", cwd))
 		}
 	}
 
@@ -158,7 +158,7 @@ func main() {
 		defer shutdownCancel()
 
 		if err := server.Shutdown(shutdownCtx); err != nil {
-			slog.Error("Server shutdown error", slog.String("error", err.Error()))
+			slog.Error("Server shutdown error", telemetry.SensitiveLogError("error", err))
 		}
 	}()
 
@@ -175,8 +175,8 @@ func main() {
 			time.Sleep(2 * time.Second)
 			if err := proxy.AutoInit(config.ProjectRoot); err != nil {
 				slog.Warn("CRS-26l: Auto-init failed, will init on first request",
-					slog.String("project_root", config.ProjectRoot),
-					slog.String("error", err.Error()))
+					telemetry.SensitiveLog("project_root", config.ProjectRoot),
+					telemetry.SensitiveLogError("error", err))
 			} else {
 				slog.Info("CRS-26l: Auto-init completed, symbol indexing triggered in background")
 				proxy.WatchIndexingProgress(ctx)
@@ -189,14 +189,14 @@ func main() {
 		slog.String("listen_addr", config.ListenAddr),
 		slog.String("trace_url", config.TraceURL),
 		slog.String("ollama_url", config.OllamaURL),
-		slog.String("project_root", config.ProjectRoot),
+		telemetry.SensitiveLog("project_root", config.ProjectRoot),
 		slog.Duration("timeout", config.Timeout),
 		slog.String("host_prefix", config.HostPrefix),
 		slog.String("container_prefix", config.ContainerPrefix),
 	)
 
 	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		slog.Error("Server failed", slog.String("error", err.Error()))
+		slog.Error("Server failed", telemetry.SensitiveLogError("error", err))
 		os.Exit(1)
 	}
 }
@@ -102,7 +102,7 @@ func NewProxyServer(config ProxyConfig) *ProxyServer {
 		metric.WithDescription("Total number of proxy requests"),
 	)
 	if err != nil {
-		slog.Warn("Failed to create request counter", slog.String("error", err.Error()))
+		slog.Warn("Failed to create request counter", telemetry.SensitiveLogError("error", err))
 	}
 
 	p.requestHistogram, err = proxyMeter.Float64Histogram(
@@ -110,7 +110,7 @@ func NewProxyServer(config ProxyConfig) *ProxyServer {
 		metric.WithDescription("Duration of proxy requests in seconds"),
 	)
 	if err != nil {
-		slog.Warn("Failed to create request histogram", slog.String("error", err.Error()))
+		slog.Warn("Failed to create request histogram", telemetry.SensitiveLogError("error", err))
 	}
 
 	return p
@@ -175,7 +175,7 @@ func (p *ProxyServer) handleChatCompletions(w http.ResponseWriter, r *http.Reque
 	var req ChatCompletionRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		span.SetStatus(codes.Error, "invalid request body")
-		logger.Warn("Invalid request body", slog.String("error", err.Error()))
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
 		writeError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
 		return
 	}
@@ -231,7 +231,7 @@ func (p *ProxyServer) handleChatCompletions(w http.ResponseWriter, r *http.Reque
 	agentResp, sessionReused, err := p.callAgentLoop(ctx, threadKey, projectRoot, query, req.Model)
 	if err != nil {
 		telemetry.RecordError(span, err)
-		logger.Error("Agent loop call failed", slog.String("error", err.Error()))
+		logger.Error("Agent loop call failed", telemetry.SensitiveLogError("error", err))
 		writeError(w, http.StatusBadGateway, "agent loop error: "+err.Error())
 		return
 	}
@@ -242,7 +242,7 @@ func (p *ProxyServer) handleChatCompletions(w http.ResponseWriter, r *http.Reque
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 	if err := json.NewEncoder(w).Encode(openAIResp); err != nil {
-		logger.Error("Failed to encode response", slog.String("error", err.Error()))
+		logger.Error("Failed to encode response", telemetry.SensitiveLogError("error", err))
 	}
 }
 
@@ -271,7 +271,7 @@ func (p *ProxyServer) handleModels(w http.ResponseWriter, r *http.Request) {
 	resp, err := p.client.Get(p.config.OllamaURL + "/api/tags")
 	if err != nil {
 		telemetry.RecordError(span, err)
-		logger.Warn("Failed to reach Ollama", slog.String("error", err.Error()))
+		logger.Warn("Failed to reach Ollama", telemetry.SensitiveLogError("error", err))
 		writeError(w, http.StatusBadGateway, "failed to reach Ollama: "+err.Error())
 		return
 	}
@@ -304,7 +304,7 @@ func (p *ProxyServer) handleModels(w http.ResponseWriter, r *http.Request) {
 		Object: "list",
 		Data:   models,
 	}); err != nil {
-		logger.Error("Failed to encode models response", slog.String("error", err.Error()))
+		logger.Error("Failed to encode models response", telemetry.SensitiveLogError("error", err))
 	}
 }
 
@@ -365,7 +365,7 @@ func (p *ProxyServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 		TraceServer: traceStatus,
 		Ollama:      ollamaStatus,
 	}); err != nil {
-		slog.Error("Failed to encode health response", slog.String("error", err.Error()))
+		slog.Error("Failed to encode health response", telemetry.SensitiveLogError("error", err))
 	}
 }
 
@@ -424,7 +424,7 @@ func (p *ProxyServer) handleInit(w http.ResponseWriter, r *http.Request) {
 	resp, err := p.client.Do(req)
 	if err != nil {
 		telemetry.RecordError(span, err)
-		logger.Error("Failed to reach trace server", slog.String("error", err.Error()))
+		logger.Error("Failed to reach trace server", telemetry.SensitiveLogError("error", err))
 		writeError(w, http.StatusBadGateway, "failed to reach trace server: "+err.Error())
 		return
 	}
@@ -434,7 +434,7 @@ func (p *ProxyServer) handleInit(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(resp.StatusCode)
 	if _, err := io.Copy(w, resp.Body); err != nil {
-		logger.Error("Failed to copy init response", slog.String("error", err.Error()))
+		logger.Error("Failed to copy init response", telemetry.SensitiveLogError("error", err))
 	}
 }
 
@@ -485,7 +485,7 @@ func (p *ProxyServer) callAgentLoop(ctx context.Context, threadKey, projectRoot,
 				// Session may have expired on server side — fall through to new run.
 				slog.Warn("Agent continue failed, creating new session",
 					slog.String("session_id", se.sessionID),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				p.sessions.Delete(threadKey)
 			} else {
@@ -803,7 +803,7 @@ func (p *ProxyServer) handleStreaming(
 
 	if err != nil {
 		telemetry.RecordError(span, err)
-		logger.Error("Agent loop call failed (streaming)", slog.String("error", err.Error()))
+		logger.Error("Agent loop call failed (streaming)", telemetry.SensitiveLogError("error", err))
 		// Headers already sent as 200 — emit error as SSE data so the client
 		// can display it. This matches OpenAI's behavior for mid-stream errors.
 		errChunk := ChatCompletionChunk{
@@ -866,7 +866,7 @@ func (p *ProxyServer) handleStreaming(
 func writeSSEChunk(w http.ResponseWriter, flusher http.Flusher, chunk ChatCompletionChunk) {
 	chunkJSON, err := json.Marshal(chunk)
 	if err != nil {
-		slog.Error("Failed to marshal SSE chunk", slog.String("error", err.Error()))
+		slog.Error("Failed to marshal SSE chunk", telemetry.SensitiveLogError("error", err))
 		return
 	}
 	fmt.Fprintf(w, "data: %s\n\n", chunkJSON)
@@ -956,11 +956,11 @@ func (p *ProxyServer) AutoInit(projectRoot string) error {
 
 	langs := detectProjectLanguages(projectRoot)
 	span.SetAttributes(
-		attribute.String("project_root", translated),
+		telemetry.SensitiveString("project_root", translated),
 		attribute.StringSlice("languages", langs),
 	)
 	slog.Info("CRS-26m: Detected project languages",
-		slog.String("project_root", projectRoot),
+		telemetry.SensitiveLog("project_root", projectRoot),
 		slog.Any("languages", langs),
 	)
 
@@ -999,7 +999,7 @@ func (p *ProxyServer) AutoInit(projectRoot string) error {
 	}
 	if decErr := json.NewDecoder(resp.Body).Decode(&initResp); decErr != nil {
 		slog.Warn("CRS-26l: Auto-init succeeded but couldn't decode response",
-			slog.String("error", decErr.Error()))
+			telemetry.SensitiveLogError("error", decErr))
 		return nil
 	}
 
@@ -1130,8 +1130,8 @@ func detectProjectLanguages(projectRoot string) []string {
 		return nil
 	}); walkErr != nil {
 		slog.Warn("detectProjectLanguages: walk failed, falling back to [go]",
-			slog.String("project_root", projectRoot),
-			slog.String("error", walkErr.Error()))
+			telemetry.SensitiveLog("project_root", projectRoot),
+			telemetry.SensitiveLogError("error", walkErr))
 	}
 
 	if len(found) == 0 {
@@ -1251,7 +1251,7 @@ func writeError(w http.ResponseWriter, status int, msg string) {
 		},
 	}
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		slog.Error("Failed to write error response", slog.String("error", err.Error()))
+		slog.Error("Failed to write error response", telemetry.SensitiveLogError("error", err))
 	}
 }
 
@@ -1393,7 +1393,7 @@ func (p *ProxyServer) forwardToOllama(
 
 	resp, err := p.client.Do(httpReq)
 	if err != nil {
-		logger.Warn("Ollama forward failed", slog.String("error", err.Error()))
+		logger.Warn("Ollama forward failed", telemetry.SensitiveLogError("error", err))
 		writeError(w, http.StatusBadGateway, "ollama unavailable: "+err.Error())
 		return
 	}
@@ -1469,7 +1469,7 @@ func (p *ProxyServer) forwardToOllama(
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(openAIResp); err != nil {
-		logger.Error("Failed to encode ollama forward response", slog.String("error", err.Error()))
+		logger.Error("Failed to encode ollama forward response", telemetry.SensitiveLogError("error", err))
 	}
 }
 
@@ -0,0 +1,230 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package main implements trace-stdlib-index, which generates the Go
+// standard library symbol index embedded by services/trace/stdlib.
+//
+// Description:
+//
+//	Reads the sources of the installed Go distribution with go/doc, the
+//	data go doc shows, and records the signature and doc one-liner of
+//	every exported function, type, method, variable and constant of the
+//	commonly used packages. Run it through go generate in
+//	services/trace/stdlib after a Go upgrade.
+//
+// Usage:
+//
+//	trace-stdlib-index -out <file> [flags]
+//	  -out string       Output file (required)
+//	  -packages string  Comma-separated import paths (default: the built-in list)
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/stdlib"
+)
+
+// defaultPackages are the standard library packages indexed by default:
+// those application code calls most. The whole library would triple the
+// binary's share of the index for little gain.
+var defaultPackages = []string{
+	"bufio", "bytes", "cmp", "context", "crypto/rand", "crypto/sha256",
+	"crypto/tls", "database/sql", "embed", "encoding/base64",
+	"encoding/binary", "encoding/csv", "encoding/hex", "encoding/json",
+	"encoding/xml", "errors", "flag", "fmt", "hash", "html/template", "io",
+	"io/fs", "log", "log/slog", "maps", "math", "math/rand", "net",
+	"net/http", "net/http/httptest", "net/url", "os", "os/exec",
+	"os/signal", "path", "path/filepath", "reflect", "regexp", "runtime",
+	"slices", "sort", "strconv", "strings", "sync", "sync/atomic",
+	"testing", "text/template", "time", "unicode", "unicode/utf8",
+}
+
+func main() {
+	out := flag.String("out", "", "Output file (required)")
+	packages := flag.String("packages", "", "Comma-separated import paths (default: the built-in list)")
+	flag.Parse()
+
+	if *out == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	paths := defaultPackages
+	if *packages != "" {
+		paths = strings.Split(*packages, ",")
+	}
+
+	if err := run(*out, paths); err != nil {
+		fmt.Fprintf(os.Stderr, "trace-stdlib-index: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run indexes the packages and writes the index to out.
+func run(out string, paths []string) error {
+	idx := &stdlib.Index{
+		GoVersion: runtime.Version(),
+		Packages:  make(map[string]*stdlib.Package, len(paths)),
+	}
+	for _, path := range paths {
+		pkg, err := indexPackage(strings.TrimSpace(path))
+		if err != nil {
+			return fmt.Errorf("index %s: %w", path, err)
+		}
+		idx.Packages[path] = pkg
+	}
+
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := stdlib.Write(f, idx); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// indexPackage reads the doc of one standard library package.
+func indexPackage(importPath string) (*stdlib.Package, error) {
+	bp, err := build.Default.Import(importPath, "", 0)
+	if err != nil {
+		return nil, err
+	}
+	if !bp.Goroot {
+		return nil, fmt.Errorf("%s is not in the standard library", importPath)
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(bp.GoFiles))
+	for _, name := range bp.GoFiles {
+		file, err := parser.ParseFile(fset, filepath.Join(bp.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	dp, err := doc.NewFromFiles(fset, files, importPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &stdlib.Package{
+		Doc:     dp.Synopsis(dp.Doc),
+		Symbols: make(map[string]stdlib.Symbol),
+	}
+	add := func(name, kind string, node any, docText string) {
+		pkg.Symbols[name] = stdlib.Symbol{Kind: kind, Signature: signature(fset, node), Doc: dp.Synopsis(docText)}
+	}
+	addValues := func(values []*doc.Value, kind string) {
+		for _, v := range values {
+			for _, spec := range v.Decl.Specs {
+				vs := spec.(*ast.ValueSpec)
+				for _, name := range vs.Names {
+					if name.IsExported() {
+						add(name.Name, kind, valueDecl(v.Decl.Tok, vs, name), firstNonEmpty(vs.Doc.Text(), v.Doc))
+					}
+				}
+			}
+		}
+	}
+	addFuncs := func(funcs []*doc.Func) {
+		for _, fn := range funcs {
+			add(fn.Name, stdlib.KindFunc, fn.Decl, fn.Doc)
+		}
+	}
+
+	addValues(dp.Consts, stdlib.KindConst)
+	addValues(dp.Vars, stdlib.KindVar)
+	addFuncs(dp.Funcs)
+	for _, t := range dp.Types {
+		spec := typeSpec(t)
+		add(t.Name, stdlib.KindType, spec, t.Doc)
+		addValues(t.Consts, stdlib.KindConst)
+		addValues(t.Vars, stdlib.KindVar)
+		addFuncs(t.Funcs)
+		for _, m := range t.Methods {
+			pkg.Symbols[t.Name+"."+m.Name] = stdlib.Symbol{Kind: stdlib.KindMethod, Signature: signature(fset, m.Decl), Doc: dp.Synopsis(m.Doc)}
+		}
+	}
+	return pkg, nil
+}
+
+// typeSpec returns the declaration of a type, with the fields of structs
+// and the methods of interfaces left out.
+func typeSpec(t *doc.Type) *ast.GenDecl {
+	for _, spec := range t.Decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != t.Name {
+			continue
+		}
+		short := *ts
+		short.Doc, short.Comment = nil, nil
+		switch ts.Type.(type) {
+		case *ast.StructType:
+			short.Type = &ast.StructType{Fields: &ast.FieldList{}}
+		case *ast.InterfaceType:
+			short.Type = &ast.InterfaceType{Methods: &ast.FieldList{}}
+		}
+		return &ast.GenDecl{Tok: token.TYPE, Specs: []ast.Spec{&short}}
+	}
+	return t.Decl
+}
+
+// valueDecl returns the declaration of one name of a var or const spec.
+func valueDecl(tok token.Token, vs *ast.ValueSpec, name *ast.Ident) *ast.GenDecl {
+	spec := &ast.ValueSpec{Names: []*ast.Ident{name}, Type: vs.Type}
+	if len(vs.Values) == len(vs.Names) {
+		for i, n := range vs.Names {
+			if n == name {
+				spec.Values = []ast.Expr{vs.Values[i]}
+			}
+		}
+	}
+	return &ast.GenDecl{Tok: tok, Specs: []ast.Spec{spec}}
+}
+
+// signature prints a declaration on one line, without its body.
+func signature(fset *token.FileSet, node any) string {
+	if fn, ok := node.(*ast.FuncDecl); ok {
+		decl := *fn
+		decl.Body, decl.Doc = nil, nil
+		node = &decl
+	}
+	var buf bytes.Buffer
+	if err := (&printer.Config{Mode: printer.RawFormat}).Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	sig := strings.Join(strings.Fields(buf.String()), " ")
+	sig = strings.ReplaceAll(sig, "struct { }", "struct")
+	return strings.ReplaceAll(sig, "interface { }", "interface")
+}
+
+// firstNonEmpty returns the first non-empty string.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -29,6 +29,10 @@
 //
 //	OLLAMA_BASE_URL=http://localhost:11434 OLLAMA_MODEL=glm-4.7-flash go run ./cmd/trace -with-context
 //
+// Context budget (with -with-context): TRACE_CONTEXT_MAX_TOKENS,
+// TRACE_CONTEXT_MAX_SYMBOL_TOKENS, TRACE_CONTEXT_MAX_FILES and
+// TRACE_CONTEXT_RANKING (relevance, graph-proximity, recency).
+//
 // With tools enabled (LLM can use exploration tools):
 //
 //	OLLAMA_BASE_URL=http://localhost:11434 OLLAMA_MODEL=glm-4.7-flash go run ./cmd/trace -with-tools
@@ -37,6 +41,12 @@
 //
 //	OLLAMA_BASE_URL=http://localhost:11434 OLLAMA_MODEL=glm-4.7-flash go run ./cmd/trace -with-context -with-tools
 //
+// As a language server (editor integration), sharing graphs with the HTTP API:
+//
+//	go run ./cmd/trace -lsp stdio            # editor spawns the process; logs go to stderr
+//	go run ./cmd/trace -lsp tcp -lsp-addr 127.0.0.1:12219
+//	go run ./cmd/trace -lsp stdio -port 0    # LSP only, no HTTP server
+//
 // Example requests:
 //
 //	# Health check
@@ -58,8 +68,10 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"os"
@@ -68,7 +80,9 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"text/tabwriter"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace"
@@ -78,17 +92,32 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/phases"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/egress"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/usage"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/runqueue"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/annotations"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/chunk"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	traceconfig "github.com/AleutianAI/AleutianFOSS/services/trace/config"
+	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/docenrich"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/lspconfig"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lspserver"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/policy"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/projectsummary"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/rag"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/semindex"
 	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
 	natsStorage "github.com/AleutianAI/AleutianFOSS/services/trace/storage/nats"
+	redisStorage "github.com/AleutianAI/AleutianFOSS/services/trace/storage/redis"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
 	traceweaviate "github.com/AleutianAI/AleutianFOSS/services/trace/weaviate"
+	"github.com/dgraph-io/badger/v4"
 	"github.com/gin-gonic/gin"
 	weaviateclient "github.com/weaviate/weaviate-go-client/v5/weaviate"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
@@ -96,7 +125,6 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/propagation"
-	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 // IsWarmupComplete checks if the main model warmup has finished.
@@ -115,78 +143,18 @@ func markWarmupComplete() {
 	trace.MarkWarmupComplete()
 }
 
-// WarmupGuardMiddleware returns 503 Service Unavailable for agent endpoints
-// if the model warmup has not yet completed.
-//
-// Description:
-//
-//	This middleware protects agent endpoints from receiving requests before
-//	the LLM model is fully loaded into VRAM. Without this guard, early requests
-//	would receive empty responses or errors due to model cold-start issues.
-//
-// Behavior:
-//
-//   - Returns 503 with Retry-After header if warmup not complete
-//   - Creates an OTel span for rejected requests with trace context from headers
-//   - Passes through to handler if warmup is complete
-//   - Health check and non-agent endpoints are not affected (use different routes)
-//
-// Tracing:
-//
-//	I-3: Inherits trace context from W3C TraceContext headers (traceparent).
-//	When rejecting requests, creates a span with the inherited trace ID so
-//	clients can correlate 503 responses with their distributed traces.
-//
-// Thread Safety: This middleware is safe for concurrent use.
-func WarmupGuardMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		if !IsWarmupComplete() {
-			// I-3: Create span with inherited trace context for observability.
-			// The otelgin middleware has already extracted trace context from headers.
-			ctx := c.Request.Context()
-			_, span := otel.Tracer("aleutian.trace").Start(ctx, "warmup_guard.reject",
-				oteltrace.WithAttributes(
-					attribute.String("path", c.Request.URL.Path),
-					attribute.String("method", c.Request.Method),
-					attribute.Int("http.status_code", http.StatusServiceUnavailable),
-				),
-			)
-			defer span.End()
-
-			// Extract trace_id for structured logging
-			spanCtx := span.SpanContext()
-			traceID := ""
-			if spanCtx.HasTraceID() {
-				traceID = spanCtx.TraceID().String()
-			}
-
-			slog.Warn("Agent request rejected: model warmup in progress",
-				slog.String("path", c.Request.URL.Path),
-				slog.String("method", c.Request.Method),
-				slog.String("trace_id", traceID))
-
-			span.SetStatus(codes.Error, "service unavailable during warmup")
-
-			c.Header("Retry-After", "30")
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"error":    "Model warmup in progress",
-				"code":     "SERVICE_WARMING_UP",
-				"message":  "The LLM model is still loading. Please retry in 30 seconds.",
-				"trace_id": traceID, // Include trace_id in response for client correlation
-			})
-			c.Abort()
-			return
-		}
-		c.Next()
-	}
-}
-
 func main() {
 	port := flag.Int("port", 12217, "Port to listen on")
 	debug := flag.Bool("debug", false, "Enable debug mode")
 	withContext := flag.Bool("with-context", false, "Enable ContextManager for code context assembly")
 	withTools := flag.Bool("with-tools", false, "Enable tool registry for agentic exploration")
 	lspEnabled := flag.Bool("lsp-enabled", false, "Enable LSP-based graph enrichment (requires pyright/tsserver)")
+	resetRoutingFeedback := flag.Bool("reset-routing-feedback", false, "Delete all persisted routing feedback at startup")
+	dumpRoutingCache := flag.Bool("dump-routing-cache", false, "Print routing embedding cache entries (model, dimension, active or orphaned) and exit")
+	lspMode := flag.String("lsp", "", `Also serve the graph over the Language Server Protocol: "stdio" or "tcp"`)
+	lspAddr := flag.String("lsp-addr", "127.0.0.1:12219", "Listen address for -lsp tcp")
+	logFormat := flag.String("log-format", telemetry.LogFormatText, `Log output format: "text" or "json"`)
+	logLevel := flag.String("log-level", "info", "Minimum log level: debug, info, warn or error (changeable at runtime via POST /v1/trace/admin/loglevel)")
 
 	// PORT env var override (matches orchestrator pattern for container deployments).
 	if envPort := os.Getenv("PORT"); envPort != "" {
@@ -197,6 +165,36 @@ func main() {
 
 	flag.Parse()
 
+	// Structured logging: one process-wide handler, with request and
+	// session correlation fields added from the context. -debug lowers the
+	// level to debug unless -log-level is given explicitly.
+	level, err := telemetry.ParseLogLevel(*logLevel)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *debug && !flagWasSet("log-level") {
+		level = slog.LevelDebug
+	}
+	if err := telemetry.SetupLogging(os.Stderr, *logFormat, level); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// In stdio LSP mode stdout carries the protocol, so everything else
+	// that would print there (banner, gin logs) goes to stderr instead.
+	lspStdout := os.Stdout
+	switch *lspMode {
+	case "":
+	case "stdio":
+		os.Stdout = os.Stderr
+		gin.DefaultWriter = os.Stderr
+	case "tcp":
+	default:
+		fmt.Fprintf(os.Stderr, "invalid -lsp %q: want \"stdio\" or \"tcp\"\n", *lspMode)
+		os.Exit(2)
+	}
+
 	// Set Gin mode
 	if *debug {
 		gin.SetMode(gin.DebugMode)
@@ -210,15 +208,24 @@ func main() {
 	telemetryCfg := telemetry.DefaultConfig()
 	telemetryCfg.ServiceName = "aleutian-trace"
 	telemetryCfg.AllowDegraded = true
+	// An invalid privacy mode is fatal: running in degraded mode would
+	// record everything in full.
+	privacy, err := telemetry.ParsePrivacyMode(telemetryCfg.Privacy)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	telemetry.SetPrivacyMode(privacy)
 	telemetryShutdown, telemetryErr := telemetry.Init(context.Background(), telemetryCfg)
 	if telemetryErr != nil {
 		slog.Warn("Telemetry init failed, running without OTel export",
-			slog.String("error", telemetryErr.Error()))
+			telemetry.SensitiveLogError("error", telemetryErr))
 	} else {
 		slog.Info("Telemetry initialized",
 			slog.String("service", telemetryCfg.ServiceName),
 			slog.String("exporter", telemetryCfg.TraceExporter),
-			slog.String("endpoint", telemetryCfg.OTLPEndpoint))
+			slog.String("endpoint", telemetryCfg.OTLPEndpoint),
+			slog.String("privacy", string(privacy)))
 	}
 
 	// I-3: Set up W3C TraceContext propagator for distributed tracing.
@@ -240,18 +247,113 @@ func main() {
 	}
 
 	// Wire allowed roots from environment for container path security.
-	// TRACE_ALLOWED_ROOTS is a comma-separated list of path prefixes that the
-	// trace server is permitted to access. Set by podman-compose.yml to restrict
-	// container filesystem access to mounted volumes only.
-	if allowedRoots := os.Getenv("TRACE_ALLOWED_ROOTS"); allowedRoots != "" {
-		for _, root := range strings.Split(allowedRoots, ",") {
-			trimmed := strings.TrimSpace(root)
-			if trimmed != "" {
-				cfg.AllowedRoots = append(cfg.AllowedRoots, trimmed)
+	// TRACE_ALLOWED_ROOTS is a colon-separated list of directories (globs
+	// allowed, e.g. /srv/projects/*) that /init, agent runs and file-reading
+	// tools may access. Set by podman-compose.yml to restrict container
+	// filesystem access to mounted volumes only. Unset, the server allows
+	// anything under $HOME and the working directory. The list used to be
+	// comma-separated; a value with commas is rejected so an old setting
+	// fails here instead of refusing every init.
+	var allowedRoots *validation.RootAllowlist
+	if spec := os.Getenv("TRACE_ALLOWED_ROOTS"); spec != "" {
+		var err error
+		allowedRoots, err = validation.ParseRootAllowlist(spec)
+		if err != nil {
+			slog.Error("Invalid TRACE_ALLOWED_ROOTS", telemetry.SensitiveLogError("error", err))
+			os.Exit(1)
+		}
+	} else {
+		allowedRoots = validation.DefaultRootAllowlist()
+	}
+	cfg.AllowedRoots = allowedRoots.Patterns()
+	slog.Info("Allowed roots configured",
+		slog.Any("roots", cfg.AllowedRoots))
+
+	// HTTP caching of read-only graph endpoints. TRACE_CACHE_SYMBOL_MAX_AGE
+	// and TRACE_CACHE_QUERY_MAX_AGE set the Cache-Control max-age per
+	// endpoint group; TRACE_CACHE_MEMO_ENTRIES caps memoized responses
+	// (0 disables memoization).
+	if v := os.Getenv("TRACE_CACHE_SYMBOL_MAX_AGE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			cfg.HTTPCache.SymbolMaxAge = d
+		}
+	}
+	if v := os.Getenv("TRACE_CACHE_QUERY_MAX_AGE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			cfg.HTTPCache.QueryMaxAge = d
+		}
+	}
+	if v := os.Getenv("TRACE_CACHE_MEMO_ENTRIES"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.HTTPCache.MemoEntries = n
+		}
+	}
+
+	// TRACE_PLAN_TTL sets how long a change plan stays valid before it
+	// expires, e.g. "30m". Approving a plan restarts the clock.
+	if v := os.Getenv("TRACE_PLAN_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.PlanTTL = d
+		}
+	}
+
+	// TRACE_PLAN_APPLY_COMMAND is run in the project root after a plan is
+	// applied, e.g. "gofmt -w {files}"; {files} expands to the changed files.
+	if v := strings.Fields(os.Getenv("TRACE_PLAN_APPLY_COMMAND")); len(v) > 0 {
+		cfg.PlanApplyCommand = v
+	}
+
+	// TRACE_PLAN_APPLY_TIMEOUT bounds the post-apply command, e.g. "5m".
+	if v := os.Getenv("TRACE_PLAN_APPLY_TIMEOUT"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			cfg.PlanApplyTimeout = d
+		}
+	}
+
+	// TRACE_GRAPH_COMPACT_AFTER sets how many incremental refreshes of a
+	// graph trigger a background compaction. "0" disables it.
+	if v := os.Getenv("TRACE_GRAPH_COMPACT_AFTER"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.CompactAfterUpdates = n
+		}
+	}
+
+	// TRACE_MAX_DEPENDENCY_SYMBOLS caps the dependency symbols merged by an
+	// init with resolve_dependencies. "0" removes the cap.
+	if v := os.Getenv("TRACE_MAX_DEPENDENCY_SYMBOLS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			cfg.MaxDependencySymbols = n
+		}
+	}
+
+	// TRACE_LINK_TEMPLATE renders the uri of code locations in responses,
+	// e.g. "vscode://file/{abs}:{line}". Requests override it with the
+	// link_template query parameter.
+	if v := os.Getenv("TRACE_LINK_TEMPLATE"); v != "" {
+		cfg.LinkTemplate = v
+	}
+
+	// TRACE_CHUNK_CHARS_PER_TOKEN sets the characters per token used to
+	// size /v1/trace/chunks chunks. Default 4.
+	if v := os.Getenv("TRACE_CHUNK_CHARS_PER_TOKEN"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			cfg.ChunkTokenEstimator = chunk.CharEstimator{CharsPerToken: n}
+		}
+	}
+
+	// TRACE_GENERATED_GLOBS replaces the default generated-file globs with
+	// a comma-separated list, e.g. "*.pb.go,*_mock.go". Files with a
+	// generated-code header are always flagged. Set it to "none" to rely
+	// on headers alone.
+	if v := os.Getenv("TRACE_GENERATED_GLOBS"); v != "" {
+		cfg.GeneratedGlobs = nil
+		if v != "none" {
+			for _, glob := range strings.Split(v, ",") {
+				if glob = strings.TrimSpace(glob); glob != "" {
+					cfg.GeneratedGlobs = append(cfg.GeneratedGlobs, glob)
+				}
 			}
 		}
-		slog.Info("Allowed roots configured",
-			slog.Any("roots", cfg.AllowedRoots))
 	}
 
 	// GR-75: Wire LSP configuration from env vars and --lsp-enabled flag.
@@ -297,13 +399,13 @@ func main() {
 		if snapErr != nil {
 			slog.Warn("Snapshot BadgerDB unavailable, graph snapshots disabled",
 				slog.String("path", snapshotDir),
-				slog.String("error", snapErr.Error()),
+				telemetry.SensitiveLogError("error", snapErr),
 			)
 		} else {
 			snapMgr, mgrErr := graph.NewSnapshotManager(snapDB.DB, slog.Default())
 			if mgrErr != nil {
 				slog.Warn("Failed to create snapshot manager",
-					slog.String("error", mgrErr.Error()))
+					telemetry.SensitiveLogError("error", mgrErr))
 				snapDB.Close()
 			} else {
 				snapshotDB = snapDB
@@ -314,6 +416,275 @@ func main() {
 		}
 	}
 
+	// Wire BadgerDB session transcript persistence from environment.
+	// TRACE_SESSION_MAX_COUNT and TRACE_SESSION_MAX_AGE bound retention.
+	var sessionDB *badgerstore.DB
+	var sessionStore *agent.TranscriptStore
+	if sessionDir := os.Getenv("TRACE_SESSION_DIR"); sessionDir != "" {
+		retention := agent.TranscriptRetention{MaxCount: 1000, MaxAge: 30 * 24 * time.Hour}
+		if v := os.Getenv("TRACE_SESSION_MAX_COUNT"); v != "" {
+			if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+				retention.MaxCount = n
+			}
+		}
+		if v := os.Getenv("TRACE_SESSION_MAX_AGE"); v != "" {
+			if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+				retention.MaxAge = d
+			}
+		}
+
+		sessCfg := badgerstore.DefaultConfig()
+		sessCfg.Path = sessionDir
+		sessDB, sessErr := badgerstore.OpenDB(sessCfg)
+		if sessErr != nil {
+			slog.Warn("Session BadgerDB unavailable, session transcripts disabled",
+				slog.String("path", sessionDir),
+				telemetry.SensitiveLogError("error", sessErr),
+			)
+		} else {
+			store, storeErr := agent.NewTranscriptStore(sessDB.DB, slog.Default(), retention)
+			if storeErr != nil {
+				slog.Warn("Failed to create session transcript store",
+					telemetry.SensitiveLogError("error", storeErr))
+				sessDB.Close()
+			} else {
+				sessionDB = sessDB
+				sessionStore = store
+				slog.Info("Session transcript persistence enabled",
+					slog.String("path", sessionDir),
+					slog.Int("max_count", retention.MaxCount),
+					slog.Duration("max_age", retention.MaxAge))
+			}
+		}
+	}
+
+	// Multiple instances: TRACE_SESSION_BACKEND=badger|redis writes live
+	// session records through to a shared store so any instance can
+	// continue a session. Graphs stay local to each instance.
+	instance := trace.InstanceConfig{
+		ID:          trace.ResolveInstanceID(),
+		StickyHints: os.Getenv("TRACE_STICKY_HINTS") == "true",
+	}
+	liveSessionTTL := 24 * time.Hour
+	if v := os.Getenv("TRACE_SESSION_TTL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			liveSessionTTL = d
+		}
+	}
+	var liveSessions agent.SessionStore
+	var sessionRedis *redisStorage.Client
+	switch backend := os.Getenv("TRACE_SESSION_BACKEND"); backend {
+	case "", "memory":
+		trace.RegisterHealthCheck(trace.HealthCheck{Name: trace.SubsystemSessionStore, Detail: "memory"})
+	case "badger":
+		if sessionDB == nil {
+			slog.Warn("TRACE_SESSION_BACKEND=badger needs TRACE_SESSION_DIR, sessions stay in memory")
+			trace.RegisterHealthCheck(trace.HealthCheck{
+				Name:  trace.SubsystemSessionStore,
+				Check: trace.StaticHealthCheck(fmt.Errorf("badger session backend needs TRACE_SESSION_DIR")),
+			})
+			break
+		}
+		records, err := agent.NewBadgerSessionRecordStore(sessionDB.DB, liveSessionTTL)
+		if err != nil {
+			slog.Warn("Shared session store unavailable", telemetry.SensitiveLogError("error", err))
+			break
+		}
+		liveSessions = agent.NewSharedSessionStore(records, instance.ID, slog.Default())
+		trace.RegisterHealthCheck(trace.HealthCheck{Name: trace.SubsystemSessionStore, Detail: "badger"})
+	case "redis":
+		redisURL := os.Getenv("TRACE_SESSION_REDIS_URL")
+		if redisURL == "" {
+			redisURL = "redis://localhost:6379"
+		}
+		client, err := redisStorage.NewClient(context.Background(), redisStorage.Config{URL: redisURL, Logger: slog.Default()})
+		if err != nil {
+			slog.Warn("Redis session store unavailable, sessions stay in memory",
+				telemetry.SensitiveLogError("error", err))
+			trace.RegisterHealthCheck(trace.HealthCheck{
+				Name:   trace.SubsystemSessionStore,
+				Detail: "redis",
+				Check:  trace.StaticHealthCheck(fmt.Errorf("connecting to redis: %w", err)),
+			})
+			break
+		}
+		records, err := agent.NewRedisSessionRecordStore(client, liveSessionTTL)
+		if err != nil {
+			slog.Warn("Shared session store unavailable", telemetry.SensitiveLogError("error", err))
+			_ = client.Close()
+			break
+		}
+		sessionRedis = client
+		liveSessions = agent.NewSharedSessionStore(records, instance.ID, slog.Default())
+		trace.RegisterHealthCheck(trace.HealthCheck{
+			Name:   trace.SubsystemSessionStore,
+			Detail: "redis",
+			Check:  client.Ping,
+		})
+	default:
+		slog.Warn("Unknown TRACE_SESSION_BACKEND, sessions stay in memory", slog.String("backend", backend))
+	}
+	instance.SharedSessions = liveSessions != nil
+	slog.Info("Instance configured",
+		slog.String("instance_id", instance.ID),
+		slog.Bool("shared_sessions", instance.SharedSessions),
+		slog.Bool("sticky_hints", instance.StickyHints))
+
+	// Spill cached tool results to BadgerDB so they survive restarts
+	// against an unchanged graph. Without it they are cached in memory only.
+	var toolCacheDB *badgerstore.DB
+	if toolCacheDir := os.Getenv("TRACE_TOOL_CACHE_DIR"); toolCacheDir != "" {
+		toolCfg := badgerstore.DefaultConfig()
+		toolCfg.Path = toolCacheDir
+		toolCfg.SyncWrites = false
+		db, err := badgerstore.OpenDB(toolCfg)
+		if err != nil {
+			slog.Warn("Tool cache BadgerDB unavailable, tool results cached in memory only",
+				slog.String("path", toolCacheDir),
+				telemetry.SensitiveLogError("error", err),
+			)
+		} else {
+			toolCacheDB = db
+			svc.SetToolResultCacheStore(tools.NewBadgerResultCacheStore(db, 0))
+			slog.Info("Tool result cache persistence enabled",
+				slog.String("path", toolCacheDir))
+		}
+	}
+
+	// Persist one egress audit record per outbound LLM request, queryable
+	// via /v1/trace/egress/audit. Retention is enforced by BadgerDB TTL.
+	var egressAuditDB *badgerstore.DB
+	var egressAudit egress.AuditStore
+	if auditCfg := egress.LoadEgressConfig(); auditCfg.AuditStoreDir != "" {
+		dbCfg := badgerstore.DefaultConfig()
+		dbCfg.Path = auditCfg.AuditStoreDir
+		db, err := badgerstore.OpenDB(dbCfg)
+		if err != nil {
+			slog.Warn("Egress audit BadgerDB unavailable, audit records logged only",
+				slog.String("path", auditCfg.AuditStoreDir),
+				telemetry.SensitiveLogError("error", err),
+			)
+		} else {
+			egressAuditDB = db
+			egressAudit = egress.NewBadgerAuditStore(db, auditCfg.AuditRetention)
+			slog.Info("Egress audit persistence enabled",
+				slog.String("path", auditCfg.AuditStoreDir),
+				slog.Duration("retention", auditCfg.AuditRetention))
+		}
+	}
+
+	// Record the token usage and cost of every LLM call. With
+	// TRACE_USAGE_DIR set, records and per-key/per-session rollups are
+	// persisted and queryable via /v1/trace/usage; without it only run
+	// totals and metrics are kept.
+	var usageDB *badgerstore.DB
+	usageOpts := []usage.AccountantOption{
+		usage.WithPrices(usage.ConfiguredPriceTable()),
+		usage.WithDailyTokenLimit(usage.DailyTokenLimitFromEnv()),
+	}
+	if usageDir := os.Getenv("TRACE_USAGE_DIR"); usageDir != "" {
+		dbCfg := badgerstore.DefaultConfig()
+		dbCfg.Path = usageDir
+		db, err := badgerstore.OpenDB(dbCfg)
+		if err != nil {
+			slog.Warn("Usage BadgerDB unavailable, usage not persisted",
+				slog.String("path", usageDir),
+				telemetry.SensitiveLogError("error", err),
+			)
+		} else {
+			usageDB = db
+			usageOpts = append(usageOpts, usage.WithStore(usage.NewBadgerStore(db, 0)))
+			slog.Info("Usage persistence enabled", slog.String("path", usageDir))
+		}
+	}
+	usageAccountant := usage.NewAccountant(usageOpts...)
+
+	// Persist synthetic docs (TRACE_DOC_ENRICHMENT) by symbol content hash
+	// so unchanged symbols keep their summary across rebuilds and restarts.
+	// Without a directory they are kept in memory for the process lifetime.
+	var docSummaryDB *badgerstore.DB
+	var docSummaryStore docenrich.Store
+	if docDir := os.Getenv("TRACE_DOC_ENRICHMENT_DIR"); docDir != "" {
+		dbCfg := badgerstore.DefaultConfig()
+		dbCfg.Path = docDir
+		dbCfg.SyncWrites = false
+		db, err := badgerstore.OpenDB(dbCfg)
+		if err != nil {
+			slog.Warn("Doc summary BadgerDB unavailable, summaries kept in memory only",
+				slog.String("path", docDir),
+				telemetry.SensitiveLogError("error", err),
+			)
+		} else {
+			docSummaryDB = db
+			docSummaryStore = docenrich.NewBadgerStore(db, 0)
+			slog.Info("Doc summary persistence enabled", slog.String("path", docDir))
+		}
+	}
+
+	// Persist semantic index vectors (TRACE_SEMANTIC_INDEX) by model and
+	// symbol content hash so a rebuild only embeds changed symbols.
+	// Without a directory they are kept in memory for the process lifetime.
+	var semanticDB *badgerstore.DB
+	var semanticStore semindex.Store
+	if semDir := os.Getenv("TRACE_SEMANTIC_INDEX_DIR"); semDir != "" {
+		dbCfg := badgerstore.DefaultConfig()
+		dbCfg.Path = semDir
+		dbCfg.SyncWrites = false
+		db, err := badgerstore.OpenDB(dbCfg)
+		if err != nil {
+			slog.Warn("Semantic index BadgerDB unavailable, vectors kept in memory only",
+				slog.String("path", semDir),
+				telemetry.SensitiveLogError("error", err),
+			)
+		} else {
+			semanticDB = db
+			semanticStore = semindex.NewBadgerStore(db, 0)
+			slog.Info("Semantic index persistence enabled", slog.String("path", semDir))
+		}
+	}
+	if os.Getenv("TRACE_SEMANTIC_INDEX") == "true" {
+		setupSemanticIndex(svc, semanticStore)
+	}
+
+	// Persist API symbol annotations per project. Without a directory they
+	// are kept in memory until restart; .trace/annotations.yaml is read on
+	// every init either way.
+	var annotationDB *badgerstore.DB
+	if annotationDir := os.Getenv("TRACE_ANNOTATIONS_DIR"); annotationDir != "" {
+		dbCfg := badgerstore.DefaultConfig()
+		dbCfg.Path = annotationDir
+		db, err := badgerstore.OpenDB(dbCfg)
+		if err != nil {
+			slog.Warn("Annotation BadgerDB unavailable, API annotations kept in memory only",
+				slog.String("path", annotationDir),
+				telemetry.SensitiveLogError("error", err),
+			)
+		} else {
+			annotationDB = db
+			svc.SetAnnotationStore(annotations.NewBadgerStore(db))
+			slog.Info("Annotation persistence enabled", slog.String("path", annotationDir))
+		}
+	}
+
+	// Persist the facts users confirm or correct in agent sessions, per
+	// project. Without a directory they are kept in memory until restart.
+	var factDB *badgerstore.DB
+	if factDir := os.Getenv("TRACE_FACTS_DIR"); factDir != "" {
+		dbCfg := badgerstore.DefaultConfig()
+		dbCfg.Path = factDir
+		db, err := badgerstore.OpenDB(dbCfg)
+		if err != nil {
+			slog.Warn("Fact BadgerDB unavailable, project facts kept in memory only",
+				slog.String("path", factDir),
+				telemetry.SensitiveLogError("error", err),
+			)
+		} else {
+			factDB = db
+			svc.SetFactStore(facts.NewBadgerStore(db))
+			slog.Info("Project fact persistence enabled", slog.String("path", factDir))
+		}
+	}
+
 	// GR-75: Store LSP availability on service for health endpoint.
 	// JavaScript uses the same typescript-language-server binary as TypeScript.
 	if lspCfg.Enabled {
@@ -325,8 +696,24 @@ func main() {
 		svc.SetLSPEnabled(true, lspLangs)
 	}
 
+	// Safety policy shared by the agent loop and the safety endpoints.
+	// A policy that fails to load is fatal: running without it would
+	// silently allow what the operator meant to deny.
+	safetyPolicy, safetyPolicyPath, err := safety.LoadPolicyFromEnv()
+	if err != nil {
+		slog.Error("Failed to load safety policy", telemetry.SensitiveLogError("error", err))
+		os.Exit(1)
+	}
+	if safetyPolicyPath != "" {
+		slog.Info("Safety policy loaded",
+			slog.String("path", safetyPolicyPath),
+			slog.Any("modes", safetyPolicy.Modes()),
+		)
+	}
+	safetyGate := safety.NewPolicyGate(safetyPolicy)
+
 	// Create handlers
-	handlers := trace.NewHandlers(svc)
+	handlers := trace.NewHandlers(svc).WithSafetyGate(safetyGate).WithEgressAudit(egressAudit).WithUsageStore(usageAccountant.Store())
 
 	// CRS-25/26: Connect to Weaviate if available.
 	// When running with `aleutian stack start`, Weaviate is on port 12212.
@@ -344,7 +731,7 @@ func main() {
 		if err != nil {
 			slog.Warn("Weaviate unavailable, running without",
 				slog.String("url", weaviateURL),
-				slog.String("error", err.Error()))
+				telemetry.SensitiveLogError("error", err))
 		} else {
 			weaviateDataSpace = os.Getenv("WEAVIATE_DATA_SPACE")
 			if weaviateDataSpace == "" {
@@ -356,7 +743,7 @@ func main() {
 			// CRS-25: Ensure CodeSymbol schema exists for semantic resolution.
 			if schemaErr := rag.EnsureCodeSymbolSchema(context.Background(), weaviateNativeClient); schemaErr != nil {
 				slog.Warn("CRS-25: Failed to ensure CodeSymbol schema",
-					slog.String("error", schemaErr.Error()))
+					telemetry.SensitiveLogError("error", schemaErr))
 			}
 
 			slog.Info("Weaviate connected",
@@ -379,7 +766,7 @@ func main() {
 	if natsErr != nil {
 		slog.Warn("CRS-27: NATS unavailable, CRS streaming disabled",
 			slog.String("url", natsURL),
-			slog.String("error", natsErr.Error()),
+			telemetry.SensitiveLogError("error", natsErr),
 		)
 	} else {
 		handlers = handlers.WithNATS(natsClient)
@@ -395,9 +782,10 @@ func main() {
 	// This extracts trace context from W3C TraceContext headers (traceparent, tracestate)
 	// and propagates it through the request context to all handlers.
 	router.Use(otelgin.Middleware("aleutian-trace"))
-	if *debug {
-		router.Use(gin.Logger())
-	}
+	// Access log via slog; also gives requests without trace headers a
+	// trace_id for log correlation.
+	router.Use(trace.AccessLogMiddleware())
+	router.Use(trace.InstanceMiddleware(instance.ID))
 
 	// Register routes under /v1/trace
 	v1 := router.Group("/v1")
@@ -415,32 +803,107 @@ func main() {
 		}
 	}
 	var routingDB *badgerstore.DB
-	if routingCacheDir != "" {
+	if routingCacheDir == "" {
+		trace.RegisterHealthCheck(trace.HealthCheck{Name: trace.SubsystemRoutingCache, Detail: "not configured"})
+	} else {
 		cfg := badgerstore.DefaultConfig()
 		cfg.Path = routingCacheDir
 		db, err := badgerstore.OpenDB(cfg)
 		if err != nil {
 			slog.Warn("Routing cache BadgerDB unavailable, embedding persistence disabled",
 				slog.String("path", routingCacheDir),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
+			trace.RegisterHealthCheck(trace.HealthCheck{
+				Name:   trace.SubsystemRoutingCache,
+				Detail: routingCacheDir,
+				Check:  trace.StaticHealthCheck(fmt.Errorf("opening routing cache: %w", err)),
+			})
 		} else {
 			routingDB = db
-			routingStore = routing.NewBadgerRouterCacheStore(db, 0, slog.Default())
+			trace.RegisterHealthCheck(trace.HealthCheck{
+				Name:   trace.SubsystemRoutingCache,
+				Detail: routingCacheDir,
+				Check: func(ctx context.Context) error {
+					return db.WithReadTxn(ctx, func(*badger.Txn) error { return nil })
+				},
+			})
+			badgerRouting := routing.NewBadgerRouterCacheStore(db, 0, routingEmbedModelIdentity(), slog.Default())
+			routingStore = badgerRouting
 			slog.Info("Routing cache BadgerDB opened",
 				slog.String("path", routingCacheDir),
+				slog.String("embed_model", badgerRouting.Model().String()),
 			)
+			if *dumpRoutingCache {
+				err := dumpRoutingCacheEntries(context.Background(), badgerRouting, os.Stdout)
+				_ = db.Close()
+				if err != nil {
+					slog.Error("Routing cache dump failed", telemetry.SensitiveLogError("error", err))
+					os.Exit(1)
+				}
+				os.Exit(0)
+			}
+			// Invalidate vectors written by a different ROUTING_EMBED_MODEL.
+			if _, _, err := badgerRouting.MigrateModel(context.Background()); err != nil {
+				slog.Warn("Routing cache model migration failed", telemetry.SensitiveLogError("error", err))
+			}
+			if *resetRoutingFeedback {
+				removed, err := badgerRouting.ResetFeedback(context.Background())
+				if err != nil {
+					slog.Warn("Routing feedback reset failed", telemetry.SensitiveLogError("error", err))
+				} else {
+					slog.Info("Routing feedback reset", slog.Int("removed", removed))
+				}
+			}
+		}
+	}
+	if *dumpRoutingCache {
+		slog.Error("Routing cache unavailable, nothing to dump", slog.String("path", routingCacheDir))
+		os.Exit(1)
+	}
+
+	// A shared read-only snapshot of another instance's routing cache lets
+	// new replicas start without re-embedding the tool corpus. Vectors read
+	// from it are copied into this instance's cache; it is never written.
+	var routingSnapshotDB *badgerstore.DB
+	if snapshotDir := os.Getenv("ROUTING_CACHE_SNAPSHOT_DIR"); snapshotDir != "" {
+		cfg := badgerstore.DefaultConfig()
+		cfg.Path = snapshotDir
+		cfg.ReadOnly = true
+		db, err := badgerstore.OpenDB(cfg)
+		if err != nil {
+			slog.Warn("Routing cache snapshot unavailable",
+				slog.String("path", snapshotDir),
+				telemetry.SensitiveLogError("error", err),
+			)
+		} else {
+			routingSnapshotDB = db
+			snapshot := routing.NewBadgerRouterCacheStore(db, 0, routingEmbedModelIdentity(), slog.Default())
+			routingStore = routing.NewSnapshotRouterCacheStore(routingStore, snapshot, slog.Default())
+			slog.Info("Routing cache snapshot opened read-only", slog.String("path", snapshotDir))
 		}
 	}
 
 	// Setup agent loop and register routes
-	agentEnabled, indexingCoord := setupAgentLoop(v1, svc, *withContext, *withTools, routingStore, weaviateNativeClient, weaviateDataSpace, natsClient)
+	agentEnabled, indexingCoord, routingReloader := setupAgentLoop(v1, svc, *withContext, *withTools, routingStore, weaviateNativeClient, weaviateDataSpace, natsClient, sessionStore, safetyGate, egressAudit, usageAccountant, liveSessions, instance, docSummaryStore)
 
 	// CRS-26l: Wire indexing coordinator to handlers for eager indexing at init time.
 	if indexingCoord != nil {
 		handlers = handlers.WithIndexingCoordinator(indexingCoord)
 	}
 
+	// POST /admin/reload-routing reloads the pre-filter rules and tool
+	// registry. TRACE_ROUTING_CONFIG_WATCH=true also reloads them when
+	// their files (PREFILTER_RULES_PATH, TOOL_REGISTRY_PATH) change.
+	if routingReloader != nil {
+		handlers = handlers.WithRoutingReloader(routingReloader)
+		if os.Getenv("TRACE_ROUTING_CONFIG_WATCH") == "true" {
+			if err := routingReloader.Watch(context.Background(), 0); err != nil {
+				slog.Warn("Routing config watch disabled", telemetry.SensitiveLogError("error", err))
+			}
+		}
+	}
+
 	// Print startup banner
 	printBanner(*port, agentEnabled)
 
@@ -448,55 +911,187 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 
-	go func() {
-		<-quit
-		slog.Info("Shutting down Aleutian Trace server")
-		if telemetryShutdown != nil {
-			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer shutdownCancel()
-			if err := telemetryShutdown(shutdownCtx); err != nil {
-				slog.Warn("Telemetry shutdown error", slog.String("error", err.Error()))
+	var shutdownOnce sync.Once
+	shutdown := func() {
+		shutdownOnce.Do(func() {
+			slog.Info("Shutting down Aleutian Trace server")
+			if telemetryShutdown != nil {
+				shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer shutdownCancel()
+				if err := telemetryShutdown(shutdownCtx); err != nil {
+					slog.Warn("Telemetry shutdown error", telemetry.SensitiveLogError("error", err))
+				}
 			}
-		}
-		if natsClient != nil {
-			if err := natsClient.Close(); err != nil {
-				slog.Warn("CRS-27: NATS shutdown error", slog.String("error", err.Error()))
+			if natsClient != nil {
+				if err := natsClient.Close(); err != nil {
+					slog.Warn("CRS-27: NATS shutdown error", telemetry.SensitiveLogError("error", err))
+				}
 			}
-		}
-		if routingDB != nil {
-			if err := routingDB.Close(); err != nil {
-				slog.Warn("Failed to close routing cache BadgerDB", slog.String("error", err.Error()))
+			if routingDB != nil {
+				if err := routingDB.Close(); err != nil {
+					slog.Warn("Failed to close routing cache BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
 			}
-		}
-		if snapshotDB != nil {
-			if err := snapshotDB.Close(); err != nil {
-				slog.Warn("Failed to close snapshot BadgerDB", slog.String("error", err.Error()))
+			if routingSnapshotDB != nil {
+				if err := routingSnapshotDB.Close(); err != nil {
+					slog.Warn("Failed to close routing cache snapshot", telemetry.SensitiveLogError("error", err))
+				}
 			}
-		}
+			if sessionRedis != nil {
+				if err := sessionRedis.Close(); err != nil {
+					slog.Warn("Failed to close session Redis client", telemetry.SensitiveLogError("error", err))
+				}
+			}
+			if snapshotDB != nil {
+				if err := snapshotDB.Close(); err != nil {
+					slog.Warn("Failed to close snapshot BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
+			}
+			if sessionDB != nil {
+				if err := sessionDB.Close(); err != nil {
+					slog.Warn("Failed to close session BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
+			}
+			if toolCacheDB != nil {
+				if err := toolCacheDB.Close(); err != nil {
+					slog.Warn("Failed to close tool cache BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
+			}
+			if egressAuditDB != nil {
+				if err := egressAuditDB.Close(); err != nil {
+					slog.Warn("Failed to close egress audit BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
+			}
+			if usageDB != nil {
+				if err := usageDB.Close(); err != nil {
+					slog.Warn("Failed to close usage BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
+			}
+			if annotationDB != nil {
+				if err := annotationDB.Close(); err != nil {
+					slog.Warn("Failed to close annotation BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
+			}
+			if factDB != nil {
+				if err := factDB.Close(); err != nil {
+					slog.Warn("Failed to close fact BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
+			}
+			if docSummaryDB != nil || semanticDB != nil {
+				// Stop doc enrichment and semantic indexing before their
+				// stores go away.
+				closeCtx, closeCancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := svc.Close(closeCtx); err != nil {
+					slog.Warn("Service shutdown error", telemetry.SensitiveLogError("error", err))
+				}
+				closeCancel()
+			}
+			if docSummaryDB != nil {
+				if err := docSummaryDB.Close(); err != nil {
+					slog.Warn("Failed to close doc summary BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
+			}
+			if semanticDB != nil {
+				if err := semanticDB.Close(); err != nil {
+					slog.Warn("Failed to close semantic index BadgerDB", telemetry.SensitiveLogError("error", err))
+				}
+			}
+		})
+	}
+
+	go func() {
+		<-quit
+		shutdown()
 		os.Exit(0)
 	}()
 
-	// Start server
 	addr := fmt.Sprintf(":%d", *port)
+
+	// LSP mode: the LSP server shares svc (and so its graphs) with the
+	// HTTP API, which keeps running unless -port 0. The process exits
+	// when the LSP client does (stdio) or on a signal (tcp).
+	if *lspMode != "" {
+		if *port > 0 {
+			go func() {
+				slog.Info("Starting Aleutian Trace server", slog.String("address", addr))
+				if err := router.Run(addr); err != nil {
+					slog.Warn("HTTP server unavailable, serving LSP only", telemetry.SensitiveLogError("error", err))
+				}
+			}()
+		}
+		code := runLSPServer(context.Background(), svc, *lspMode, *lspAddr, lspStdout)
+		shutdown()
+		os.Exit(code)
+	}
+
+	// Start server
 	slog.Info("Starting Aleutian Trace server", slog.String("address", addr))
 	if err := router.Run(addr); err != nil {
-		slog.Error("Failed to start server", slog.String("error", err.Error()))
+		slog.Error("Failed to start server", telemetry.SensitiveLogError("error", err))
 		os.Exit(1)
 	}
 }
 
+// runLSPServer serves the trace graph over LSP until the client exits
+// (stdio) or listening fails (tcp).
+//
+// Returns the process exit code: 1 if the server failed or a stdio client
+// sent exit without shutdown, as the protocol requires, otherwise 0.
+func runLSPServer(ctx context.Context, svc *trace.Service, mode, addr string, stdout *os.File) int {
+	srv := lspserver.NewServer(svc)
+
+	var err error
+	switch mode {
+	case "stdio":
+		slog.Info("Serving LSP over stdio")
+		err = srv.Serve(ctx, os.Stdin, stdout)
+	case "tcp":
+		err = srv.ListenAndServe(ctx, addr)
+	}
+	if errors.Is(err, lspserver.ErrExitWithoutShutdown) {
+		return 1
+	}
+	if err != nil {
+		slog.Error("LSP server failed", telemetry.SensitiveLogError("error", err))
+		return 1
+	}
+	return 0
+}
+
 // setupAgentLoop initializes the agent loop and registers routes.
 //
-// routingStore is the optional BadgerDB cache for tool embedding vectors.
+// routingStore is the optional BadgerDB cache for tool embedding vectors,
+// layered over a read-only snapshot when ROUTING_CACHE_SNAPSHOT_DIR is set.
 // Pass nil to disable persistence (e.g. when routing cache directory is unavailable).
 //
 // wvClient and wvDataSpace are the Weaviate connection from main() for CRS-25 semantic RAG.
 // CR-5: Shared from main() to avoid creating a duplicate ResilientClient.
 // Pass nil/empty to disable Weaviate integration.
 //
-// Returns true if the agent is fully enabled with LLM support, and the
-// SymbolIndexingCoordinator if Weaviate + embeddings are configured (CRS-26l).
-func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTools bool, routingStore routing.RouterCacheStore, wvClient *weaviateclient.Client, wvDataSpace string, natsClient *natsStorage.Client) (bool, *trace.SymbolIndexingCoordinator) {
+// sessionStore persists agent session transcripts (TRACE_SESSION_DIR).
+// Pass nil to disable the /v1/trace/agent/sessions endpoints.
+//
+// safetyGate applies the TRACE_SAFETY_POLICY to every agent tool call and
+// backs the safety_check tool. Shared with the HTTP handlers.
+//
+// egressAudit persists per-request egress audit records (TRACE_EGRESS_AUDIT_DIR).
+// Pass nil to keep audit records in the structured log only.
+//
+// usageAccountant records the token usage of every call made by the
+// factory's clients and backs the per-run usage in agent responses.
+//
+// liveSessions is the shared session store (TRACE_SESSION_BACKEND). Pass
+// nil to keep sessions in memory. instance describes this instance to the
+// agent handlers.
+//
+// docSummaryStore persists synthetic docs (TRACE_DOC_ENRICHMENT_DIR). Pass
+// nil to keep them in memory.
+//
+// Returns true if the agent is fully enabled with LLM support, the
+// SymbolIndexingCoordinator if Weaviate + embeddings are configured (CRS-26l),
+// and the reloader for the pre-filter rules and tool registry (nil without
+// an LLM, when there is no pre-filter to reload).
+func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTools bool, routingStore routing.RouterCacheStore, wvClient *weaviateclient.Client, wvDataSpace string, natsClient *natsStorage.Client, sessionStore *agent.TranscriptStore, safetyGate *safety.DefaultGate, egressAudit egress.AuditStore, usageAccountant *usage.Accountant, liveSessions agent.SessionStore, instance trace.InstanceConfig, docSummaryStore docenrich.Store) (bool, *trace.SymbolIndexingCoordinator, *trace.RoutingReloader) {
 	// CRS-26l: Coordinator returned to caller for handlers wiring.
 	var indexingCoord *trace.SymbolIndexingCoordinator
 
@@ -512,12 +1107,17 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 	// is Ollama. For cloud providers, the model must be set via env var.
 	roleConfig, err := providers.LoadRoleConfig(mainModelFallback, "granite4:micro-h", "ministral-3:3b")
 	if err != nil {
-		slog.Error("Failed to load role config", slog.String("error", err.Error()))
+		slog.Error("Failed to load role config", telemetry.SensitiveLogError("error", err))
+		trace.RegisterHealthCheck(trace.HealthCheck{
+			Name:     trace.SubsystemMainProvider,
+			Required: true,
+			Check:    trace.StaticHealthCheck(fmt.Errorf("loading role config: %w", err)),
+		})
 		markWarmupComplete()
-		agentLoop := agent.NewDefaultAgentLoop()
-		agentHandlers := trace.NewAgentHandlers(agentLoop, svc)
+		agentLoop := agent.NewDefaultAgentLoop(sessionStoreOpts(liveSessions)...)
+		agentHandlers := trace.NewAgentHandlers(agentLoop, svc, trace.WithInstance(instance))
 		trace.RegisterAgentRoutesWithMiddleware(v1, agentHandlers, nil)
-		return false, nil
+		return false, nil, nil
 	}
 
 	// CB-60b: Create provider factory. For Ollama roles, create the shared model manager.
@@ -525,10 +1125,15 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 	var ollamaModelManager *agentllm.MultiModelManager
 	if roleConfig.Main.Provider == providers.ProviderOllama ||
 		roleConfig.Router.Provider == providers.ProviderOllama ||
-		roleConfig.ParamExtractor.Provider == providers.ProviderOllama {
+		roleConfig.ParamExtractor.Provider == providers.ProviderOllama ||
+		ensembleUsesProvider(roleConfig.MainEnsemble, providers.ProviderOllama) {
 
 		ollamaURL := providers.ResolveOllamaURL()
 		ollamaModelManager = agentllm.NewMultiModelManager(ollamaURL)
+		trace.RegisterCircuitStates(ollamaModelManager.Resilience().BreakerStates)
+		if _, err := ollamaModelManager.Resilience().RegisterMetrics(otel.Meter("aleutian.llm.ollama")); err != nil {
+			slog.Warn("Failed to register Ollama circuit state metric", telemetry.SensitiveLogError("error", err))
+		}
 	}
 
 	// CB-60d: Load egress config and create guard builder for data egress control.
@@ -543,38 +1148,73 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 		if classifyData == "false" || classifyData == "0" {
 			slog.Info("Data classification disabled (TRACE_CLASSIFY_DATA=false), all data treated as public")
 			classifier = egress.NewNoOpClassifier()
+			trace.RegisterHealthCheck(trace.HealthCheck{
+				Name:   trace.SubsystemPolicyEngine,
+				Detail: "data classification disabled",
+			})
 		} else {
 			policyEngine, peErr := policy.NewPolicyEngine()
 			if peErr != nil {
 				slog.Warn("PolicyEngine unavailable, egress classifier will use NoOp (all data treated as public)",
-					slog.String("error", peErr.Error()))
+					telemetry.SensitiveLogError("error", peErr))
 				classifier = egress.NewNoOpClassifier()
 			} else {
 				classifier = egress.NewPolicyEngineClassifier(policyEngine)
 			}
+			trace.RegisterHealthCheck(trace.HealthCheck{
+				Name:   trace.SubsystemPolicyEngine,
+				Detail: "data classification",
+				Check:  trace.StaticHealthCheck(peErr),
+			})
 		}
 		egressBuilder = egress.NewEgressGuardBuilder(egressCfg, classifier)
+		if egressAudit != nil {
+			egressBuilder.SetAuditStore(egressAudit)
+		}
 		slog.Info("Egress guard initialized",
 			slog.Bool("enabled", egressCfg.Enabled),
 			slog.Bool("local_only", egressCfg.LocalOnly),
-			slog.Bool("audit", egressCfg.AuditEnabled))
+			slog.Bool("audit", egressCfg.AuditEnabled),
+			slog.Bool("audit_persisted", egressAudit != nil),
+			slog.String("sensitive_mode", egressCfg.SensitiveDataMode))
 	}
 
-	factory := providers.NewProviderFactory(ollamaModelManager, providers.WithEgressGuard(egressBuilder))
+	// Create event emitter
+	eventEmitter := events.NewEmitter()
+	svc.SetEventEmitter(eventEmitter)
+
+	factory := providers.NewProviderFactory(ollamaModelManager,
+		providers.WithEgressGuard(egressBuilder),
+		providers.WithUsageAccountant(usageAccountant),
+		providers.WithEventEmitter(eventEmitter))
+	registerProviderHealthChecks(roleConfig, ollamaModelManager)
+
+	if os.Getenv("TRACE_DOC_ENRICHMENT") == "true" {
+		setupDocEnrichment(svc, factory, roleConfig, egressCfg, docSummaryStore)
+	}
+	if os.Getenv("TRACE_PROJECT_SUMMARY_PROSE") == "true" {
+		setupSummaryProse(svc, factory, roleConfig, egressCfg)
+	}
 
 	// CB-60: Create main agent client using the factory.
 	llmClient, err := factory.CreateAgentClient(roleConfig.Main)
 	if err != nil {
 		slog.Warn("Main LLM provider not available",
 			slog.String("provider", roleConfig.Main.Provider),
-			slog.String("error", err.Error()))
+			telemetry.SensitiveLogError("error", err))
 		slog.Info("Agent endpoints will use mock mode (default state transitions only)")
 
+		trace.RegisterHealthCheck(trace.HealthCheck{
+			Name:     trace.SubsystemMainProvider,
+			Required: true,
+			Detail:   roleConfig.Main.Provider + "/" + roleConfig.Main.Model,
+			Check:    trace.StaticHealthCheck(fmt.Errorf("creating client: %w", err)),
+		})
 		markWarmupComplete()
-		agentLoop := agent.NewDefaultAgentLoop()
-		agentHandlers := trace.NewAgentHandlers(agentLoop, svc)
+		agentLoop := agent.NewDefaultAgentLoop(sessionStoreOpts(liveSessions)...)
+		agentHandlers := trace.NewAgentHandlers(agentLoop, svc, trace.WithInstance(instance))
 		trace.RegisterAgentRoutesWithMiddleware(v1, agentHandlers, nil)
-		return false, nil
+		return false, nil, nil
 	}
 
 	model := roleConfig.Main.Model
@@ -582,11 +1222,25 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 		slog.String("provider", roleConfig.Main.Provider),
 		slog.String("model", model))
 
+	// MAIN_ENSEMBLE replaces the main client with an ensemble. Ordinary steps
+	// use its first available member; runs with "ensemble": true compare
+	// every member's final answer.
+	if len(roleConfig.MainEnsemble) > 0 {
+		ensemble, ensembleErr := factory.CreateEnsembleClient(roleConfig.MainEnsemble)
+		if ensembleErr != nil {
+			slog.Warn("Main ensemble not available, using the main provider alone",
+				telemetry.SensitiveLogError("error", ensembleErr))
+		} else {
+			llmClient = ensemble
+			slog.Info("Main ensemble configured", slog.String("members", ensemble.Model()))
+		}
+	}
+
 	// CB-60: Create lifecycle manager for main model warmup.
 	mainLifecycle, err := factory.CreateLifecycleManager(roleConfig.Main)
 	if err != nil {
 		slog.Warn("Could not create lifecycle manager, skipping warmup",
-			slog.String("error", err.Error()))
+			telemetry.SensitiveLogError("error", err))
 		markWarmupComplete()
 	} else {
 		// S-1: Move warmup to background goroutine for non-blocking startup.
@@ -594,64 +1248,36 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 			slog.String("provider", roleConfig.Main.Provider),
 			slog.String("model", model))
 
-		go func() {
-			// CB-60a H-6: Panic recovery ensures markWarmupComplete is always called.
-			// Without this, a panic in warmup (from Ollama client, HTTP transport, etc.)
-			// would leave the server permanently in "warming up" state.
-			defer func() {
-				if r := recover(); r != nil {
-					buf := make([]byte, 4096)
-					n := runtime.Stack(buf, false)
-					slog.Error("Panic in warmup goroutine recovered",
-						slog.Any("panic", r),
-						slog.String("stack", string(buf[:n])),
-					)
-					markWarmupComplete()
-				}
-			}()
-
-			warmupCtx, warmupCancel := context.WithTimeout(context.Background(), 2*time.Minute)
-			defer warmupCancel()
-
-			startTime := time.Now()
-
-			if mainLifecycle.IsLocal() {
-				// Ollama: warm model into VRAM with full warmup procedure
-				ollamaClient, ollamaErr := agentllm.NewOllamaClient()
-				if ollamaErr != nil {
-					slog.Warn("Could not create Ollama client for warmup",
-						slog.String("error", ollamaErr.Error()))
-					markWarmupComplete()
-					return
-				}
-				if warmErr := warmMainModel(warmupCtx, ollamaClient, model); warmErr != nil {
-					slog.Warn("Main model warmup failed, LLM classifier may fall back to regex",
-						slog.String("model", model),
-						slog.String("error", warmErr.Error()),
-						slog.Duration("duration", time.Since(startTime)))
-				} else {
-					slog.Info("Model warmup completed successfully",
-						slog.String("model", model),
-						slog.Duration("duration", time.Since(startTime)))
-				}
-			} else {
-				// Cloud: just verify connectivity (auth check)
-				if warmErr := mainLifecycle.WarmModel(warmupCtx, model, providers.WarmupOptions{}); warmErr != nil {
-					slog.Warn("Cloud provider auth check failed",
-						slog.String("provider", roleConfig.Main.Provider),
-						slog.String("error", warmErr.Error()))
-				} else {
-					slog.Info("Cloud provider ready",
-						slog.String("provider", roleConfig.Main.Provider),
-						slog.Duration("duration", time.Since(startTime)))
-				}
+		// Every configured role is warmed concurrently and reports its
+		// own completion, so the warmup guard only holds back requests that
+		// need a role that is not ready yet.
+		warmups := []roleWarmup{{role: trace.WarmupRoleMain, cfg: roleConfig.Main, lifecycle: mainLifecycle}}
+		for _, r := range []struct {
+			role string
+			cfg  providers.ProviderConfig
+		}{
+			{trace.WarmupRoleRouter, roleConfig.Router},
+			{trace.WarmupRoleParamExtractor, roleConfig.ParamExtractor},
+		} {
+			if r.cfg.Model == "" || (r.role == trace.WarmupRoleParamExtractor && r.cfg.Model == roleConfig.Router.Model) {
+				continue
+			}
+			lifecycle, lifecycleErr := factory.CreateLifecycleManager(r.cfg)
+			if lifecycleErr != nil {
+				slog.Warn("Could not create lifecycle manager, skipping role warmup",
+					slog.String("role", r.role),
+					telemetry.SensitiveLogError("error", lifecycleErr))
+				continue
 			}
+			trace.ExpectWarmup(r.role)
+			warmups = append(warmups, roleWarmup{role: r.role, cfg: r.cfg, lifecycle: lifecycle})
+		}
 
-			markWarmupComplete()
-			slog.Info("Server ready to accept agent requests",
-				slog.String("provider", roleConfig.Main.Provider),
-				slog.String("model", model))
-		}()
+		var lister trace.ResidentModelLister
+		if ollamaModelManager != nil {
+			lister = ollamaModelManager
+		}
+		go warmRoles(warmups, lister)
 	}
 
 	// GR-Phase1: Query classification architecture
@@ -668,54 +1294,118 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 
 	// Create phase registry with actual phase implementations
 	registry := agent.NewPhaseRegistry()
-	registry.Register(agent.StateInit, trace.NewPhaseAdapter(phases.NewInitPhase()))
-	registry.Register(agent.StatePlan, trace.NewPhaseAdapter(phases.NewPlanPhase()))
+	initPhase, planPhase := phases.NewInitPhase(), phases.NewPlanPhase()
+	registry.Register(agent.StateInit, trace.NewPhaseAdapter(initPhase))
+	registry.Register(agent.StatePlan, trace.NewPhaseAdapter(planPhase))
 	// Pre-filter: load config and registry (singletons, ~0ms after first load).
 	// If either fails, proceed without pre-filter (graceful degradation).
 	pfCtx := context.Background()
 	pfCfg, pfErr := traceconfig.GetPreFilterConfig(pfCtx)
 	if pfErr != nil {
 		slog.Warn("Pre-filter config load failed, pre-filter disabled",
-			slog.String("error", pfErr.Error()))
+			telemetry.SensitiveLogError("error", pfErr))
 	}
 	toolRegistry, trErr := traceconfig.GetToolRoutingRegistry(pfCtx)
 	if trErr != nil {
 		slog.Warn("Tool routing registry load failed, pre-filter disabled",
-			slog.String("error", trErr.Error()))
+			telemetry.SensitiveLogError("error", trErr))
 	}
 
 	var executeOpts []phases.ExecutePhaseOption
+	var reflectOpts []phases.ReflectPhaseOption
+	var routingFeedback *routing.FeedbackIndex
+	var pf *routing.PreFilter
 	if pfErr == nil && trErr == nil && pfCfg.Enabled {
-		pf := routing.NewPreFilter(toolRegistry, pfCfg, slog.Default(), routingStore)
+		pf = routing.NewPreFilter(toolRegistry, pfCfg, slog.Default(), routingStore)
+		if routingFeedback = pf.FeedbackIndex(); routingFeedback != nil {
+			// Reflect reports whether the routed tool's result was accepted.
+			reflectOpts = append(reflectOpts, phases.WithRoutingFeedback(pf))
+		}
 		executeOpts = append(executeOpts, phases.WithPreFilter(pf))
 		slog.Info("Pre-filter enabled",
 			slog.Int("forced_mappings", len(pfCfg.ForcedMappings)),
 			slog.Int("negation_rules", len(pfCfg.NegationRules)),
 			slog.Int("confusion_pairs", len(pfCfg.ConfusionPairs)),
-			slog.String("scoring_mode", pfCfg.ScoringMode))
+			slog.String("scoring_mode", pfCfg.ScoringMode),
+			slog.Bool("routing_feedback", routingFeedback != nil))
 		// CB-62: Embedding warm-up happens synchronously on the first scored call
 		// in scoreHybrid (10s timeout). BadgerDB cache makes this ~100µs on restart;
 		// Ollama cold start ~300ms. No startup warm-up needed — specs aren't available
 		// until the first query arrives with tool definitions.
 	}
+	// TRACE_PARALLEL_TOOLS sets how many independent plan steps the Execute
+	// phase runs concurrently. Default 3; "1" runs every step sequentially.
+	if v := os.Getenv("TRACE_PARALLEL_TOOLS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			executeOpts = append(executeOpts, phases.WithParallelToolLimit(n))
+		}
+	}
+	// Rules and registry can be reloaded without a restart. Without a
+	// pre-filter, a reload only replaces the config singletons.
+	routingReloader := trace.NewRoutingReloader(pf, slog.Default())
 
-	registry.Register(agent.StateExecute, trace.NewPhaseAdapter(phases.NewExecutePhase(executeOpts...)))
+	executePhase := phases.NewExecutePhase(executeOpts...)
+	reflectPhase := phases.NewReflectPhase(reflectOpts...)
+	clarifyPhase := phases.NewClarifyPhase()
+	registry.Register(agent.StateExecute, trace.NewPhaseAdapter(executePhase))
 
-	registry.Register(agent.StateReflect, trace.NewPhaseAdapter(phases.NewReflectPhase()))
-	registry.Register(agent.StateClarify, trace.NewPhaseAdapter(phases.NewClarifyPhase()))
+	registry.Register(agent.StateReflect, trace.NewPhaseAdapter(reflectPhase))
+	registry.Register(agent.StateClarify, trace.NewPhaseAdapter(clarifyPhase))
 	slog.Info("Registered phases", slog.Int("count", registry.Count()))
 
+	// TRACE_PIPELINE_CONFIG replaces the built-in state machine with a
+	// configured one that can add custom phases (phases.RegisterPhase).
+	// An invalid config is fatal rather than silently running the default
+	// flow the operator meant to change.
+	loopOpts := append(sessionStoreOpts(liveSessions), agent.WithPhaseRegistry(registry))
+	pipelineCfg, pipelinePath, err := agent.LoadPipelineConfigFromEnv()
+	if err != nil {
+		slog.Error("Failed to load phase pipeline config", telemetry.SensitiveLogError("error", err))
+		os.Exit(1)
+	}
+	if pipelineCfg != nil {
+		pipeline, err := agent.NewPipeline(pipelineCfg,
+			trace.NamedPhaseExecutors(initPhase, planPhase, executePhase, reflectPhase, clarifyPhase))
+		if err != nil {
+			slog.Error("Invalid phase pipeline config",
+				slog.String("path", pipelinePath),
+				telemetry.SensitiveLogError("error", err))
+			os.Exit(1)
+		}
+		loopOpts = append(loopOpts, agent.WithPipeline(pipeline))
+		slog.Info("Phase pipeline loaded",
+			slog.String("path", pipelinePath),
+			slog.String("start", string(pipeline.Start())),
+			slog.Int("custom_phases", len(phases.RegisteredPhases())))
+	}
+
 	// Create graph provider wrapping the service
 	serviceAdapter := trace.NewServiceAdapter(svc)
 	graphProvider := agent.NewServiceGraphProvider(serviceAdapter)
 
-	// Create event emitter
-	eventEmitter := events.NewEmitter()
-
-	// Create safety gate
-	safetyGate := safety.NewDefaultGate(nil)
+	// Record/replay: TRACE_ALLOW_RECORD=true lets agent runs set "record"
+	// to save a replay fixture under TRACE_FIXTURE_DIR. With the replay
+	// main provider, TRACE_REPLAY_TOOLS=true also serves tool results from
+	// the fixture so the run is hermetic.
+	allowRecord := os.Getenv("TRACE_ALLOW_RECORD") == "true"
+	var toolInterceptor tools.ExecutionInterceptor
+	if roleConfig.Main.Provider == providers.ProviderReplay && os.Getenv("TRACE_REPLAY_TOOLS") == "true" {
+		// The main client already loaded this fixture, so this only fails
+		// if it was removed in between.
+		if fixture, fixtureErr := factory.Fixture(roleConfig.Main.FixturePath); fixtureErr != nil {
+			slog.Error("Tool replay disabled", telemetry.SensitiveLogError("error", fixtureErr))
+		} else {
+			toolInterceptor = replay.NewToolInterceptor(fixture)
+			slog.Info("Replaying tool executions from fixture", slog.String("path", roleConfig.Main.FixturePath))
+		}
+	} else if allowRecord {
+		toolInterceptor = replay.NewToolInterceptor(nil)
+	}
 
 	// Create dependencies factory
+	// Project facts given to each run; 0 uses the default, negative none.
+	maxKnownFacts, _ := strconv.Atoi(os.Getenv("TRACE_FACTS_MAX_INJECTED"))
+
 	// GR-39: Enable Coordinator and Session Restore for CRS persistence
 	baseFactoryOpts := []trace.DependenciesFactoryOption{
 		trace.WithLLMClient(llmClient),
@@ -727,6 +1417,8 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 		trace.WithToolsEnabled(withTools),
 		trace.WithCoordinatorEnabled(true),
 		trace.WithSessionRestoreEnabled(true),
+		trace.WithToolInterceptor(toolInterceptor),
+		trace.WithMaxKnownFacts(maxKnownFacts),
 	}
 
 	// CRS-27: Wire NATS JetStream into deps factory for CRS delta persistence.
@@ -752,6 +1444,8 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 			trace.WithToolsEnabled(withTools),
 			trace.WithCoordinatorEnabled(true),
 			trace.WithSessionRestoreEnabled(true),
+			trace.WithToolInterceptor(toolInterceptor),
+			trace.WithMaxKnownFacts(maxKnownFacts),
 			trace.WithWeaviateClient(wvClient, wvDataSpace),
 		}
 
@@ -770,7 +1464,7 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 			embedClient, embedErr := rag.NewEmbedClient(orchestratorURL, embeddingModel)
 			if embedErr != nil {
 				slog.Warn("CRS-26i: Failed to create embed client, semantic search will not have vectors",
-					slog.String("error", embedErr.Error()),
+					telemetry.SensitiveLogError("error", embedErr),
 				)
 			} else {
 				factoryOpts = append(factoryOpts, trace.WithEmbedClient(embedClient))
@@ -802,25 +1496,172 @@ func setupAgentLoop(v1 *gin.RouterGroup, svc *trace.Service, withContext, withTo
 		slog.Info("ToolRegistry ENABLED (agent can use exploration tools)")
 	}
 
+	// Summarize older turns of long multi-turn sessions with the Main
+	// client. The window is the Main role's NumCtx, TRACE_HISTORY_CONTEXT_WINDOW
+	// overrides it (cloud providers have no NumCtx), and
+	// TRACE_HISTORY_SUMMARY_MODEL selects a cheaper model for the summary.
+	historyWindow := roleConfig.Main.NumCtx
+	if historyWindow <= 0 {
+		historyWindow = 65536
+	}
+	if v := os.Getenv("TRACE_HISTORY_CONTEXT_WINDOW"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			historyWindow = n
+		}
+	}
+	historySummarizer, err := agentllm.NewHistorySummarizer(llmClient, historyWindow, os.Getenv("TRACE_HISTORY_SUMMARY_MODEL"))
+	if err != nil {
+		slog.Warn("Conversation summarization disabled", telemetry.SensitiveLogError("error", err))
+	} else {
+		loopOpts = append(loopOpts, agent.WithHistorySummarizer(historySummarizer))
+	}
+
 	// Create agent loop with phases and dependency factory
 	agentLoop := agent.NewDefaultAgentLoop(
-		agent.WithPhaseRegistry(registry),
-		agent.WithDependenciesFactory(depsFactory),
+		append(loopOpts, agent.WithDependenciesFactory(depsFactory))...,
 	)
 	agentOpts := []trace.AgentHandlersOption{
 		trace.WithProviderFactory(factory),
+		trace.WithUsageAccountant(usageAccountant),
 		trace.WithModelManager(ollamaModelManager),
 		trace.WithRoleConfig(roleConfig),
+		trace.WithInstance(instance),
 	}
 	if natsClient != nil {
 		agentOpts = append(agentOpts, trace.WithNATSSSE(natsClient))
 	}
+	if sessionStore != nil {
+		agentOpts = append(agentOpts, trace.WithSessionStore(sessionStore))
+	}
+	if routingFeedback != nil {
+		agentOpts = append(agentOpts, trace.WithRoutingFeedback(routingFeedback))
+	}
+	if pf != nil {
+		agentOpts = append(agentOpts, trace.WithPreFilter(pf))
+	}
+	if withContext {
+		// TRACE_CONTEXT_* env vars set the server-wide context budget;
+		// per-request config fields override it.
+		budget := cbcontext.ContextBudgetFromEnv()
+		agentOpts = append(agentOpts, trace.WithContextBudget(budget))
+		slog.Info("Context budget configured",
+			slog.Int("max_total_tokens", budget.MaxTotalTokens),
+			slog.Int("max_symbol_tokens", budget.MaxSymbolTokens),
+			slog.Int("max_files", budget.MaxFiles),
+			slog.String("ranking", budget.Ranking),
+		)
+	}
+	if allowRecord {
+		fixtureDir := os.Getenv("TRACE_FIXTURE_DIR")
+		if fixtureDir == "" {
+			fixtureDir = filepath.Join(os.TempDir(), "trace-fixtures")
+		}
+		agentOpts = append(agentOpts, trace.WithFixtureRecording(fixtureDir))
+		slog.Warn("Agent run recording enabled; fixtures contain prompts and code verbatim",
+			slog.String("dir", fixtureDir))
+	}
+	agentOpts = append(agentOpts, trace.WithRunQueue(runQueueFromEnv()))
+	if os.Getenv("TRACE_QUICK_ANSWER") != "false" {
+		// TRACE_QUICK_ANSWER_TIMEOUT bounds quick answers, e.g. "8s".
+		quickCfg := trace.QuickAnswerConfig{Client: llmClient}
+		if d, err := time.ParseDuration(os.Getenv("TRACE_QUICK_ANSWER_TIMEOUT")); err == nil && d > 0 {
+			quickCfg.Timeout = d
+		}
+		agentOpts = append(agentOpts, trace.WithQuickAnswer(quickCfg))
+	}
 	agentHandlers := trace.NewAgentHandlers(agentLoop, svc, agentOpts...)
 
 	// S-1: Apply warmup guard middleware to agent routes.
-	// This returns 503 Service Unavailable for agent requests during model warmup.
-	trace.RegisterAgentRoutesWithMiddleware(v1, agentHandlers, WarmupGuardMiddleware())
-	return true, indexingCoord
+	// This returns 503 Service Unavailable for agent requests that need a
+	// model still warming up, or holds those that ask to queue.
+	trace.RegisterAgentRoutesWithMiddleware(v1, agentHandlers, trace.NewWarmupGuard(warmupGuardFromEnv(roleConfig)))
+	return true, indexingCoord, routingReloader
+}
+
+// sessionStoreOpts returns the loop option for a shared session store, or
+// none to keep the default in-memory store.
+func sessionStoreOpts(store agent.SessionStore) []agent.DefaultLoopOption {
+	if store == nil {
+		return nil
+	}
+	return []agent.DefaultLoopOption{agent.WithSessionStore(store)}
+}
+
+// flagWasSet reports whether the named flag was given on the command line.
+func flagWasSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// routingEmbedModelIdentity returns the routing embedding model identity.
+//
+// The name comes from routing.ResolveRoutingEmbedModel. ROUTING_EMBED_DIM
+// optionally pins the expected dimension; when unset, cached entries are only
+// checked for a consistent dimension.
+func routingEmbedModelIdentity() routing.EmbeddingModelIdentity {
+	identity := routing.EmbeddingModelIdentity{Name: routing.ResolveRoutingEmbedModel()}
+	if dimStr := os.Getenv("ROUTING_EMBED_DIM"); dimStr != "" {
+		dim, err := strconv.Atoi(dimStr)
+		if err != nil || dim <= 0 {
+			slog.Warn("Ignoring invalid ROUTING_EMBED_DIM", slog.String("value", dimStr))
+		} else {
+			identity.Dim = dim
+		}
+	}
+	return identity
+}
+
+// dumpRoutingCacheEntries prints one line per routing embedding cache entry.
+// Entries the active tool corpus uses are "active"; usable entries it does
+// not use are "orphaned".
+func dumpRoutingCacheEntries(ctx context.Context, store *routing.BadgerRouterCacheStore, w io.Writer) error {
+	entries, err := store.ListEmbeddings(ctx)
+	if err != nil {
+		return err
+	}
+	active, err := store.ActiveCorpus(ctx)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "MODEL\tDIM\tTOOLS\tCORPUS_HASH\tEXPIRES\tSTATUS\n")
+	orphaned := 0
+	for _, e := range entries {
+		model := e.Model
+		if e.Legacy {
+			model = "(legacy v1)"
+		}
+		status := "ok"
+		switch {
+		case e.Stale:
+			status = "stale"
+		case e.Active:
+			status = "active"
+		case e.Orphaned:
+			status = "orphaned"
+			orphaned++
+		}
+		expires := "-"
+		if !e.ExpiresAt.IsZero() {
+			expires = e.ExpiresAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\n", model, e.Dim, e.ToolCount, e.CorpusHash, expires, status)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	activeHash := active.CorpusHash
+	if activeHash == "" {
+		activeHash = "none"
+	}
+	fmt.Fprintf(w, "%d entries, %d orphaned (current model: %s, active corpus: %s)\n", len(entries), orphaned, store.Model(), activeHash)
+	return nil
 }
 
 func printBanner(port int, agentEnabled bool) {
@@ -870,19 +1711,404 @@ func printBanner(port int, agentEnabled bool) {
 	fmt.Printf(banner, agentStatus, port, port, port, port)
 }
 
+// ensembleUsesProvider reports whether any ensemble member uses provider.
+func ensembleUsesProvider(members []providers.ProviderConfig, provider string) bool {
+	for _, m := range members {
+		if m.Provider == provider {
+			return true
+		}
+	}
+	return false
+}
+
+// setupSemanticIndex enables the local semantic index, which embeds the
+// functions and methods of every built graph for semantic_search and
+// GET /v1/trace/search/semantic.
+//
+// Description:
+//
+//	Embeds with TRACE_SEMANTIC_INDEX_MODEL, or the routing embedding
+//	model (ROUTING_EMBED_MODEL, EMBEDDING_MODEL), through Ollama's
+//	/api/embed at TRACE_SEMANTIC_INDEX_URL or OLLAMA_BASE_URL. An endpoint
+//	off localhost is used only with TRACE_SEMANTIC_INDEX_ALLOW_REMOTE=true
+//	and an egress config that is not local-only; otherwise the index
+//	stays disabled and searches fall back to keyword matching.
+//	TRACE_SEMANTIC_INDEX_RATE_PER_MIN and TRACE_SEMANTIC_INDEX_MAX_SYMBOLS
+//	override the request rate and the per-graph symbol cap.
+//
+// Inputs:
+//
+//	svc - The service whose graphs are indexed.
+//	store - Vector store. Nil keeps vectors in memory.
+func setupSemanticIndex(svc *trace.Service, store semindex.Store) {
+	model := os.Getenv("TRACE_SEMANTIC_INDEX_MODEL")
+	if model == "" {
+		model = routing.ResolveRoutingEmbedModel()
+	}
+	endpoint := os.Getenv("TRACE_SEMANTIC_INDEX_URL")
+	if endpoint == "" {
+		endpoint = strings.TrimRight(providers.ResolveOllamaURL(), "/") + "/api/embed"
+	}
+	cfg := semindex.Config{
+		Model:       model,
+		Endpoint:    endpoint,
+		AllowRemote: os.Getenv("TRACE_SEMANTIC_INDEX_ALLOW_REMOTE") == "true",
+		LocalOnly:   egress.LoadEgressConfig().LocalOnly,
+	}
+	if n, err := strconv.Atoi(os.Getenv("TRACE_SEMANTIC_INDEX_RATE_PER_MIN")); err == nil && n > 0 {
+		cfg.RatePerMinute = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("TRACE_SEMANTIC_INDEX_MAX_SYMBOLS")); err == nil && n > 0 {
+		cfg.MaxSymbols = n
+	}
+	ix, err := semindex.New(semindex.NewOllamaEmbedder(endpoint, model), store, cfg)
+	if err != nil {
+		slog.Warn("Semantic index disabled", telemetry.SensitiveLogError("error", err))
+		return
+	}
+	svc.SetSemanticIndex(ix)
+	slog.Info("Semantic index enabled",
+		slog.String("model", model),
+		slog.String("endpoint", endpoint),
+		slog.Int("rate_per_min", ix.Status().RatePerMinute))
+}
+
+// setupDocEnrichment enables synthetic docs for undocumented exported
+// symbols, generated with the router model after every graph build.
+//
+// Description:
+//
+//	Uses the router role, or the param-extractor role when no router model
+//	is set. A cloud provider is used only when the egress config allows it
+//	explicitly (allowlist and consent, not local-only); otherwise
+//	enrichment stays disabled. TRACE_DOC_ENRICHMENT_RATE_PER_MIN and
+//	TRACE_DOC_ENRICHMENT_MAX_SYMBOLS override the request rate and the
+//	per-graph symbol cap.
+//
+// Inputs:
+//
+//	svc - The service whose graphs are enriched.
+//	factory - Creates the chat client.
+//	roleConfig - The loaded per-role provider configuration.
+//	egressCfg - Decides whether a cloud provider may be used.
+//	store - Summary store. Nil keeps summaries in memory.
+func setupDocEnrichment(svc *trace.Service, factory *providers.ProviderFactory, roleConfig *providers.RoleConfig, egressCfg *egress.EgressConfig, store docenrich.Store) {
+	cfg := roleConfig.Router
+	if cfg.Model == "" {
+		cfg = roleConfig.ParamExtractor
+	}
+	if !docenrich.ProviderAllowed(cfg.Provider, egressCfg) {
+		slog.Warn("Doc enrichment disabled: provider not explicitly allowed by the egress config",
+			slog.String("provider", cfg.Provider))
+		return
+	}
+	client, err := factory.CreateChatClient(cfg)
+	if err != nil {
+		slog.Warn("Doc enrichment disabled: chat client unavailable",
+			slog.String("provider", cfg.Provider),
+			telemetry.SensitiveLogError("error", err))
+		return
+	}
+	enrichCfg := docenrich.Config{Provider: cfg.Provider, Model: cfg.Model, Egress: egressCfg}
+	if n, err := strconv.Atoi(os.Getenv("TRACE_DOC_ENRICHMENT_RATE_PER_MIN")); err == nil && n > 0 {
+		enrichCfg.RatePerMinute = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("TRACE_DOC_ENRICHMENT_MAX_SYMBOLS")); err == nil && n > 0 {
+		enrichCfg.MaxSymbols = n
+	}
+	pipeline, err := docenrich.New(client, store, enrichCfg)
+	if err != nil {
+		slog.Warn("Doc enrichment disabled", telemetry.SensitiveLogError("error", err))
+		return
+	}
+	svc.SetDocEnricher(pipeline)
+	slog.Info("Doc enrichment enabled",
+		slog.String("provider", cfg.Provider),
+		slog.String("model", cfg.Model),
+		slog.Int("rate_per_min", pipeline.Status().RatePerMinute))
+}
+
+// setupSummaryProse enables the prose description of project summaries,
+// written by the router model the first time a summary is asked for.
+//
+// Description:
+//
+//	Uses the same role and egress rules as setupDocEnrichment: the router
+//	role, or the param-extractor role when no router model is set, and a
+//	cloud provider only when the egress config allows it explicitly.
+//
+// Inputs:
+//
+//	svc - The service whose project summaries get prose.
+//	factory - Creates the chat client.
+//	roleConfig - The loaded per-role provider configuration.
+//	egressCfg - Decides whether a cloud provider may be used.
+func setupSummaryProse(svc *trace.Service, factory *providers.ProviderFactory, roleConfig *providers.RoleConfig, egressCfg *egress.EgressConfig) {
+	cfg := roleConfig.Router
+	if cfg.Model == "" {
+		cfg = roleConfig.ParamExtractor
+	}
+	if !docenrich.ProviderAllowed(cfg.Provider, egressCfg) {
+		slog.Warn("Project summary prose disabled: provider not explicitly allowed by the egress config",
+			slog.String("provider", cfg.Provider))
+		return
+	}
+	client, err := factory.CreateChatClient(cfg)
+	if err != nil {
+		slog.Warn("Project summary prose disabled: chat client unavailable",
+			slog.String("provider", cfg.Provider),
+			telemetry.SensitiveLogError("error", err))
+		return
+	}
+	svc.SetSummaryPolisher(projectsummary.NewPolisher(client, cfg.Model))
+	slog.Info("Project summary prose enabled",
+		slog.String("provider", cfg.Provider),
+		slog.String("model", cfg.Model))
+}
+
+// runQueueFromEnv creates the queue agent runs wait in for an execution slot.
+//
+// Description:
+//
+//	TRACE_AGENT_MAX_CONCURRENT_RUNS (default 1) is the number of runs
+//	executed at once and TRACE_AGENT_QUEUE_MAX_DEPTH (default 16) the
+//	number allowed to wait. TRACE_AGENT_RUN_PRIORITIES limits the
+//	priorities API keys may use, e.g. "key_1a2b3c4d5e6f=ci"; keys are the
+//	api_key_id of the access log. Exits on a malformed grant list.
+func runQueueFromEnv() *runqueue.Queue {
+	cfg := runqueue.Config{}
+	if n, err := strconv.Atoi(os.Getenv("TRACE_AGENT_MAX_CONCURRENT_RUNS")); err == nil && n > 0 {
+		cfg.MaxConcurrent = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("TRACE_AGENT_QUEUE_MAX_DEPTH")); err == nil && n > 0 {
+		cfg.MaxDepth = n
+	}
+	allowed, err := runqueue.ParseAllowedPriorities(os.Getenv("TRACE_AGENT_RUN_PRIORITIES"))
+	if err != nil {
+		slog.Error("Invalid TRACE_AGENT_RUN_PRIORITIES", telemetry.SensitiveLogError("error", err))
+		os.Exit(1)
+	}
+	cfg.AllowedPriorities = allowed
+	q := runqueue.New(cfg)
+	st := q.Status()
+	slog.Info("Agent run queue configured",
+		slog.Int("max_concurrent", st.MaxConcurrent),
+		slog.Int("max_depth", st.MaxDepth),
+		slog.Int("keys_with_priority_limits", len(allowed)))
+	return q
+}
+
+// warmupGuardFromEnv returns the warmup guard configuration for the agent
+// routes, with queue bounds from TRACE_WARMUP_QUEUE_SIZE and
+// TRACE_WARMUP_QUEUE_TIMEOUT.
+func warmupGuardFromEnv(roleConfig *providers.RoleConfig) trace.WarmupGuardConfig {
+	cfg := trace.WarmupGuardConfig{
+		Roles:      []string{trace.WarmupRoleMain, trace.WarmupRoleRouter, trace.WarmupRoleParamExtractor},
+		RoleConfig: roleConfig,
+	}
+	if n, err := strconv.Atoi(os.Getenv("TRACE_WARMUP_QUEUE_SIZE")); err == nil && n > 0 {
+		cfg.QueueSize = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("TRACE_WARMUP_QUEUE_TIMEOUT")); err == nil && d > 0 {
+		cfg.QueueTimeout = d
+	}
+	return cfg
+}
+
+// registerProviderHealthChecks registers readiness checks for the main and
+// router providers.
+//
+// Description:
+//
+//	Each check is a cheap connectivity request (Ollama's version endpoint,
+//	or a cloud provider's model list, which also validates the API key).
+//	Both roles gate readiness. The registry caches results, so probes don't
+//	reach the providers on every call. An Ollama role also fails while its
+//	model's circuit breaker is open.
+//
+// Inputs:
+//
+//	roleConfig - The loaded per-role provider configuration.
+//	ollama - The shared Ollama model manager. Nil if no role uses Ollama.
+func registerProviderHealthChecks(roleConfig *providers.RoleConfig, ollama *agentllm.MultiModelManager) {
+	client := &http.Client{Timeout: 5 * time.Second}
+	for _, role := range []struct {
+		name string
+		cfg  providers.ProviderConfig
+	}{
+		{trace.SubsystemMainProvider, roleConfig.Main},
+		{trace.SubsystemRouterProvider, roleConfig.Router},
+	} {
+		cfg := role.cfg
+		trace.RegisterHealthCheck(trace.HealthCheck{
+			Name:     role.name,
+			Required: true,
+			Detail:   cfg.Provider + "/" + cfg.Model,
+			Check: func(ctx context.Context) error {
+				if ollama != nil && cfg.Provider == providers.ProviderOllama {
+					if state := ollama.Resilience().State(cfg.Model); state != agentllm.BreakerClosed {
+						return fmt.Errorf("circuit breaker %s for model %s", state, cfg.Model)
+					}
+				}
+				return providers.CheckConnectivity(ctx, client, cfg)
+			},
+		})
+	}
+}
+
+// roleWarmup is one model role to warm at startup.
+type roleWarmup struct {
+	role      string
+	cfg       providers.ProviderConfig
+	lifecycle providers.ModelLifecycleManager
+}
+
+// warmRoles warms every role concurrently and then checks that the local
+// models are still resident.
+//
+// Description:
+//
+//	Each role warms in its own goroutine with its own panic recovery and
+//	marks its own role complete, so a failure or panic in one role never
+//	leaves another stuck in "warming up". Once all roles finish, the
+//	models of the local roles are checked against Ollama's /api/ps; a
+//	model evicted by a later load is logged and counted.
+//
+// Inputs:
+//
+//	warmups - The roles to warm. Every role but main must have been
+//	          registered with trace.ExpectWarmup.
+//	lister - Lists resident local models. Nil skips the residency check.
+//
+// Thread Safety: Intended to run in its own goroutine.
+func warmRoles(warmups []roleWarmup, lister trace.ResidentModelLister) {
+	warmupCtx, warmupCancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer warmupCancel()
+
+	var wg sync.WaitGroup
+	for _, w := range warmups {
+		wg.Add(1)
+		go func(w roleWarmup) {
+			defer wg.Done()
+			var err error
+			// CB-60a H-6: Panic recovery ensures the role is always marked
+			// complete. Without this, a panic in warmup (from Ollama client,
+			// HTTP transport, etc.) would leave the server permanently in
+			// "warming up" state.
+			defer func() {
+				if r := recover(); r != nil {
+					buf := make([]byte, 4096)
+					n := runtime.Stack(buf, false)
+					slog.Error("Panic in warmup goroutine recovered",
+						slog.String("role", w.role),
+						slog.Any("panic", r),
+						slog.String("stack", string(buf[:n])),
+					)
+					err = fmt.Errorf("warmup panicked: %v", r)
+				}
+				trace.MarkRoleWarmupComplete(w.role, err)
+			}()
+			err = warmRole(warmupCtx, w)
+		}(w)
+	}
+	wg.Wait()
+
+	slog.Info("Server ready to accept agent requests",
+		slog.Int("roles", len(warmups)))
+
+	if lister == nil {
+		return
+	}
+	expected := make(map[string]string)
+	for _, w := range warmups {
+		if w.lifecycle.IsLocal() {
+			expected[w.role] = w.cfg.Model
+		}
+	}
+	if len(expected) == 0 {
+		return
+	}
+	evicted, err := trace.VerifyWarmupResidency(warmupCtx, lister, expected)
+	if err != nil {
+		slog.Warn("Could not verify warmed models are resident",
+			telemetry.SensitiveLogError("error", err))
+		return
+	}
+	if len(evicted) == 0 {
+		slog.Info("All warmed models are resident", slog.Int("models", len(expected)))
+	}
+}
+
+// warmRole warms a single role's model.
+//
+// Description:
+//
+//	A local main model gets the full warmup procedure (warmMainModel); a
+//	cloud main provider only has its connectivity checked. Other roles are
+//	warmed through their lifecycle manager with the role's keep-alive and
+//	context window.
+//
+// Inputs:
+//
+//	ctx - Context with the warmup deadline.
+//	w - The role to warm.
+//
+// Outputs:
+//
+//	error - Non-nil if warmup failed. Failure is non-fatal; the model
+//	        loads on first use instead.
+func warmRole(ctx context.Context, w roleWarmup) error {
+	startTime := time.Now()
+
+	var err error
+	switch {
+	case w.role == trace.WarmupRoleMain && w.lifecycle.IsLocal():
+		ollamaClient, ollamaErr := agentllm.NewOllamaClient()
+		if ollamaErr != nil {
+			err = fmt.Errorf("creating Ollama client: %w", ollamaErr)
+		} else {
+			err = warmMainModel(ctx, ollamaClient, w.cfg)
+		}
+	case w.role == trace.WarmupRoleMain:
+		// Cloud: just verify connectivity (auth check)
+		err = w.lifecycle.WarmModel(ctx, w.cfg.Model, providers.WarmupOptions{})
+	default:
+		err = w.lifecycle.WarmModel(ctx, w.cfg.Model, w.cfg.WarmupOptions())
+	}
+
+	if err != nil {
+		slog.Warn("Model warmup failed, role will load on first use",
+			slog.String("role", w.role),
+			slog.String("provider", w.cfg.Provider),
+			slog.String("model", w.cfg.Model),
+			telemetry.SensitiveLogError("error", err),
+			slog.Duration("duration", time.Since(startTime)))
+		return err
+	}
+	slog.Info("Model warmup completed",
+		slog.String("role", w.role),
+		slog.String("provider", w.cfg.Provider),
+		slog.String("model", w.cfg.Model),
+		slog.Duration("duration", time.Since(startTime)))
+	return nil
+}
+
 // warmMainModel pre-loads the main LLM model into VRAM to prevent cold-start issues.
 //
 // Description:
 //
 //	Sends a minimal "ping" request to the Ollama server to trigger model loading.
 //	This prevents empty response errors when the LLMClassifier makes its first call.
-//	The model is kept alive with keep_alive=-1 to prevent unloading.
+//	The model is loaded with the main role's keep_alive and num_ctx
+//	(TRACE_MAIN_KEEP_ALIVE, TRACE_MAIN_NUM_CTX), the same values the agent
+//	sends on every request, so Ollama does not reload it on first use.
 //
 // Inputs:
 //
 //	ctx - Context for cancellation/timeout. Should have 60-120s timeout.
 //	client - The OllamaClient to use for warmup.
-//	model - The model name to warm (e.g., "glm-4.7-flash").
+//	cfg - The main role's provider configuration. cfg.Model is the model
+//	      to warm (e.g., "glm-4.7-flash").
 //
 // Outputs:
 //
@@ -892,15 +2118,14 @@ func printBanner(port int, agentEnabled bool) {
 //
 //	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 //	defer cancel()
-//	if err := warmMainModel(ctx, ollamaClient, model); err != nil {
-//	    slog.Warn("Model warmup failed", slog.String("error", err.Error()))
+//	if err := warmMainModel(ctx, ollamaClient, roleConfig.Main); err != nil {
+//	    slog.Warn("Model warmup failed", telemetry.SensitiveLogError("error", err))
 //	}
 //
 // Limitations:
 //
 //   - Warmup failure is non-fatal; system falls back to lazy-loading on first request.
 //   - Very large models (>50GB) may timeout even with 2-minute context.
-//   - Context window (65536 tokens) is hardcoded to match main agent configuration.
 //   - No retry logic; single attempt only. Caller may implement retry if needed.
 //
 // Assumptions:
@@ -910,7 +2135,8 @@ func printBanner(port int, agentEnabled bool) {
 //   - No other processes are competing for VRAM during warmup.
 //
 // Thread Safety: This function is safe for concurrent use.
-func warmMainModel(ctx context.Context, client *agentllm.OllamaClient, model string) error {
+func warmMainModel(ctx context.Context, client *agentllm.OllamaClient, cfg providers.ProviderConfig) error {
+	model := cfg.Model
 	// R-5: Validate model parameter
 	if model == "" {
 		return fmt.Errorf("model must not be empty")
@@ -921,14 +2147,21 @@ func warmMainModel(ctx context.Context, client *agentllm.OllamaClient, model str
 	// O-1: Add OTel span for distributed tracing
 	ctx, span := otel.Tracer("aleutian.trace").Start(ctx, "warmMainModel")
 	defer span.End()
-	// Use 24h keep_alive to match router configuration.
 	// Note: "-1" is invalid Go duration format and causes Ollama 400 error.
-	// 24h is long enough to keep model warm during testing sessions.
-	const keepAlive = "24h"
+	keepAlive := cfg.KeepAlive
+	if keepAlive == "" {
+		keepAlive = providers.DefaultKeepAlive
+	}
+	// The context window MUST match what the main agent uses to ensure the
+	// model is loaded with the correct configuration.
+	numCtx := cfg.NumCtx
+	if numCtx <= 0 {
+		numCtx = providers.DefaultMainNumCtx
+	}
 
 	span.SetAttributes(
 		attribute.String("model", model),
-		attribute.Int("num_ctx", 65536),
+		attribute.Int("num_ctx", numCtx),
 		attribute.String("keep_alive", keepAlive),
 	)
 
@@ -937,10 +2170,6 @@ func warmMainModel(ctx context.Context, client *agentllm.OllamaClient, model str
 		slog.String("keep_alive", keepAlive),
 	)
 
-	// Build minimal warmup request with large context window for main model.
-	// The context window MUST match what the main agent uses (64K tokens)
-	// to ensure the model is loaded with the correct configuration.
-	numCtx := 65536
 	params := agentllm.GenerationParams{
 		KeepAlive: keepAlive,
 		NumCtx:    &numCtx,
@@ -973,7 +2202,7 @@ func warmMainModel(ctx context.Context, client *agentllm.OllamaClient, model str
 		span.SetStatus(codes.Error, "warmup failed")
 		slog.Error("Main model warmup failed",
 			slog.String("model", model),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.String("error_type", fmt.Sprintf("%T", err)),
 			slog.Duration("duration", duration),
 		)
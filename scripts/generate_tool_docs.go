@@ -147,7 +147,7 @@ func categorizeTools(tools []ToolEntryYAML) []ToolCategory {
 			categoryMap["graph_reasoning"].Tools = append(categoryMap["graph_reasoning"].Tools, tool)
 
 		case tool.Name == "plan_multi_file_change" || tool.Name == "validate_plan" ||
-			tool.Name == "preview_changes":
+			tool.Name == "preview_changes" || tool.Name == "preview_rename":
 			categoryMap["graph_coordination"].Tools = append(categoryMap["graph_coordination"].Tools, tool)
 
 		case tool.Name == "detect_patterns" || tool.Name == "find_code_smells" ||
@@ -0,0 +1,304 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+)
+
+// DefaultAbortTimeout is how long Abort waits for a running session to
+// stop before marking it aborted anyway.
+const DefaultAbortTimeout = 5 * time.Second
+
+// WithAbortTimeout sets how long Abort waits for a running session to
+// acknowledge the cancellation.
+//
+// Inputs:
+//
+//	d - The wait. Zero or negative uses DefaultAbortTimeout.
+//
+// Outputs:
+//
+//	DefaultLoopOption - The configuration function.
+func WithAbortTimeout(d time.Duration) DefaultLoopOption {
+	return func(l *DefaultAgentLoop) {
+		l.abortTimeout = d
+	}
+}
+
+// AbortRecord describes how a session was aborted.
+type AbortRecord struct {
+	// AbortedAt is when the session was aborted (Unix milliseconds UTC).
+	AbortedAt int64 `json:"aborted_at"`
+
+	// InterruptedPhase is the phase that was running, or the state the
+	// session was in when no run was active.
+	InterruptedPhase AgentState `json:"interrupted_phase,omitempty"`
+
+	// LastCompletedPhase is the last phase that finished before the abort.
+	// Empty when none did.
+	LastCompletedPhase AgentState `json:"last_completed_phase,omitempty"`
+
+	// Resumable is true when Continue can rerun InterruptedPhase from the
+	// context it started with.
+	Resumable bool `json:"resumable"`
+
+	// Acknowledged is false when the run did not stop within the abort
+	// timeout; it may still be finishing its current call.
+	Acknowledged bool `json:"acknowledged"`
+}
+
+// activeRun is a Run or Continue in progress.
+type activeRun struct {
+	// cancel cancels the run's context, with ErrAborted as the cause.
+	cancel context.CancelCauseFunc
+
+	// done is closed once the run has returned and released the session.
+	done chan struct{}
+}
+
+// phaseCheckpoint is what an aborted session resumes from.
+type phaseCheckpoint struct {
+	// state is the phase that was started last.
+	state AgentState
+
+	// lastCompleted is the last phase that finished.
+	lastCompleted AgentState
+
+	// context is a copy of the session context taken before state ran.
+	context *AssembledContext
+}
+
+// beginRun registers a run of the session so Abort can cancel it.
+//
+// Description:
+//
+//	Derives the run's context from ctx. The returned function must be
+//	deferred right after TryAcquire succeeds: it releases the session,
+//	unregisters the run and only then signals Abort, so an Abort that
+//	returns can be followed by Continue.
+//
+// Inputs:
+//
+//	ctx - The caller's context.
+//	session - The acquired session.
+//
+// Outputs:
+//
+//	context.Context - The run's context.
+//	func() - Ends the run.
+func (l *DefaultAgentLoop) beginRun(ctx context.Context, session *Session) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	run := &activeRun{cancel: cancel, done: make(chan struct{})}
+
+	l.runsMu.Lock()
+	if l.runs == nil {
+		l.runs = make(map[string]*activeRun)
+	}
+	l.runs[session.ID] = run
+	l.runsMu.Unlock()
+
+	return ctx, func() {
+		session.Release()
+		l.runsMu.Lock()
+		if l.runs[session.ID] == run {
+			delete(l.runs, session.ID)
+		}
+		l.runsMu.Unlock()
+		cancel(nil)
+		close(run.done)
+	}
+}
+
+// activeRunFor returns the session's run in progress, or nil.
+func (l *DefaultAgentLoop) activeRunFor(sessionID string) *activeRun {
+	l.runsMu.Lock()
+	defer l.runsMu.Unlock()
+	return l.runs[sessionID]
+}
+
+// waitForAbort waits until run has stopped, the abort timeout elapses or
+// ctx is done. Reports whether the run stopped.
+func (l *DefaultAgentLoop) waitForAbort(ctx context.Context, run *activeRun) bool {
+	timeout := l.abortTimeout
+	if timeout <= 0 {
+		timeout = DefaultAbortTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-run.done:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// finishAborted ends a run whose context was cancelled by Abort.
+func (l *DefaultAgentLoop) finishAborted(ctx context.Context, session *Session) *RunResult {
+	record := session.markAborted(true)
+	slog.InfoContext(ctx, "Agent run aborted",
+		slog.String("session_id", session.ID),
+		slog.String("interrupted_phase", string(record.InterruptedPhase)),
+		slog.String("last_completed_phase", string(record.LastCompletedPhase)),
+		slog.Bool("resumable", record.Resumable),
+	)
+	return &RunResult{
+		State:      StateAborted,
+		TokensUsed: session.Metrics.TotalTokens,
+		StepsTaken: session.Metrics.TotalSteps,
+		ToolsUsed:  l.collectToolInvocations(session),
+		Abort:      record,
+		Error: &AgentError{
+			Code:        "ABORTED",
+			Message:     ErrAborted.Error(),
+			Recoverable: record.Resumable,
+		},
+	}
+}
+
+// checkpointPhase records that state is about to run, with a copy of the
+// context it starts from.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) checkpointPhase(state AgentState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := &phaseCheckpoint{state: state, context: cloneAssembledContext(s.CurrentContext)}
+	if s.checkpoint != nil {
+		cp.lastCompleted = s.checkpoint.lastCompleted
+	}
+	s.checkpoint = cp
+}
+
+// completePhase records that state finished.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) completePhase(state AgentState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.checkpoint != nil {
+		s.checkpoint.lastCompleted = state
+	}
+}
+
+// markAborted moves the session to ABORTED and records why.
+//
+// Description:
+//
+//	The checkpoint is resumable when the interrupted phase is neither
+//	terminal nor CLARIFY. Calling it again on an ABORTED session returns
+//	the existing record, so the run and a timed-out Abort do not both
+//	record the abort.
+//
+// Inputs:
+//
+//	acknowledged - Whether no run is still executing.
+//
+// Outputs:
+//
+//	*AbortRecord - A copy of the record.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) markAborted(acknowledged bool) *AbortRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.State == StateAborted && s.abort != nil {
+		record := *s.abort
+		return &record
+	}
+
+	now := time.Now()
+	record := &AbortRecord{
+		AbortedAt:        now.UnixMilli(),
+		InterruptedPhase: s.State,
+		Acknowledged:     acknowledged,
+	}
+	if cp := s.checkpoint; cp != nil {
+		record.InterruptedPhase = cp.state
+		record.LastCompletedPhase = cp.lastCompleted
+		record.Resumable = !cp.state.IsTerminal() && cp.state != StateClarify
+	}
+	s.abort = record
+	s.State = StateAborted
+	s.LastActiveAt = now.UnixMilli()
+	s.History = append(s.History, HistoryEntry{
+		Step:      len(s.History),
+		State:     StateAborted,
+		Timestamp: now.UnixMilli(),
+		Type:      "abort",
+		Input:     fmt.Sprintf("aborted during %s", record.InterruptedPhase),
+		Error:     ErrAborted.Error(),
+	})
+
+	out := *record
+	return &out
+}
+
+// abortRecord returns a copy of the abort record, or nil when the session
+// is not ABORTED.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) abortRecord() *AbortRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.State != StateAborted || s.abort == nil {
+		return nil
+	}
+	record := *s.abort
+	return &record
+}
+
+// resumeFromAbort restores the checkpoint of an aborted session.
+//
+// Outputs:
+//
+//	AgentState - The phase to rerun, now the session state.
+//	error - ErrSessionNotResumable when the abort record is not resumable.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) resumeFromAbort() (AgentState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.State != StateAborted || s.abort == nil || !s.abort.Resumable || s.checkpoint == nil {
+		return "", ErrSessionNotResumable
+	}
+	s.CurrentContext = cloneAssembledContext(s.checkpoint.context)
+	s.State = s.checkpoint.state
+	s.abort = nil
+	s.LastActiveAt = time.Now().UnixMilli()
+	return s.State, nil
+}
+
+// cloneAssembledContext copies the parts of c that phases append to or
+// modify, so a checkpoint is unaffected by the phase it precedes.
+func cloneAssembledContext(c *AssembledContext) *AssembledContext {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	out.CodeContext = slices.Clone(c.CodeContext)
+	out.LibraryDocs = slices.Clone(c.LibraryDocs)
+	out.ToolResults = slices.Clone(c.ToolResults)
+	out.ConversationHistory = slices.Clone(c.ConversationHistory)
+	out.Relevance = maps.Clone(c.Relevance)
+	return &out
+}
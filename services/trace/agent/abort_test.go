@@ -0,0 +1,195 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// slowToolPhase is an EXECUTE phase whose first run adds a message to the
+// context and calls a slow tool. Later runs complete.
+type slowToolPhase struct {
+	session *Session
+
+	// ignoreCancel makes the tool wait for release instead of ctx.
+	ignoreCancel bool
+	release      chan struct{}
+
+	started chan struct{}
+	toolErr chan error
+	runs    int
+	seen    []int // conversation length at the start of each run
+}
+
+func newSlowToolPhase(session *Session) *slowToolPhase {
+	return &slowToolPhase{
+		session: session,
+		release: make(chan struct{}),
+		started: make(chan struct{}),
+		toolErr: make(chan error, 1),
+	}
+}
+
+func (p *slowToolPhase) Name() string { return "execute" }
+
+func (p *slowToolPhase) Execute(ctx context.Context, deps any) (AgentState, error) {
+	p.runs++
+	sessionCtx := p.session.GetCurrentContext()
+	p.seen = append(p.seen, len(sessionCtx.ConversationHistory))
+	if p.runs > 1 {
+		return StateComplete, nil
+	}
+	sessionCtx.ConversationHistory = append(sessionCtx.ConversationHistory, Message{Role: "assistant", Content: "calling tool"})
+	close(p.started)
+	err := p.slowTool(ctx)
+	p.toolErr <- err
+	return StateExecute, err
+}
+
+// slowTool takes a minute unless its context is cancelled.
+func (p *slowToolPhase) slowTool(ctx context.Context) error {
+	if p.ignoreCancel {
+		<-p.release
+		return nil
+	}
+	select {
+	case <-time.After(time.Minute):
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// newSlowToolLoop returns a loop running INIT -> PLAN -> EXECUTE, where
+// EXECUTE calls the slow tool, and a session with a one-message context.
+func newSlowToolLoop(t *testing.T, opts ...DefaultLoopOption) (*DefaultAgentLoop, *Session, *slowToolPhase) {
+	t.Helper()
+	session, err := NewSession("/test/project", nil)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	session.SetCurrentContext(&AssembledContext{
+		ConversationHistory: []Message{{Role: "user", Content: "Who calls Save?"}},
+	})
+	phase := newSlowToolPhase(session)
+	registry := NewMockPhaseRegistry()
+	registry.RegisterPhase(StateInit, &MockPhase{name: "init", nextState: StatePlan})
+	registry.RegisterPhase(StatePlan, &MockPhase{name: "plan", nextState: StateExecute})
+	registry.RegisterPhase(StateExecute, phase)
+	loop := NewDefaultAgentLoop(append([]DefaultLoopOption{WithPhaseRegistry(registry)}, opts...)...)
+	return loop, session, phase
+}
+
+// startRun runs the session in the background once the tool has started.
+func startRun(t *testing.T, loop *DefaultAgentLoop, session *Session, phase *slowToolPhase) <-chan *RunResult {
+	t.Helper()
+	results := make(chan *RunResult, 1)
+	go func() {
+		result, err := loop.Run(context.Background(), session, "Who calls Save?")
+		if err != nil {
+			t.Errorf("Run: %v", err)
+		}
+		results <- result
+	}()
+	select {
+	case <-phase.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("slow tool never started")
+	}
+	return results
+}
+
+func TestDefaultAgentLoop_Abort_CancelsRunningTool(t *testing.T) {
+	loop, session, phase := newSlowToolLoop(t)
+	results := startRun(t, loop, session, phase)
+
+	start := time.Now()
+	if err := loop.Abort(context.Background(), session.ID); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Abort took %v", elapsed)
+	}
+	if err := <-phase.toolErr; !errors.Is(err, ErrAborted) {
+		t.Errorf("tool context error = %v, want ErrAborted", err)
+	}
+
+	result := <-results
+	if result.State != StateAborted || result.Error == nil || result.Error.Code != "ABORTED" {
+		t.Fatalf("result = %s %+v, want ABORTED", result.State, result.Error)
+	}
+	state, err := loop.GetState(session.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := AbortRecord{InterruptedPhase: StateExecute, LastCompletedPhase: StatePlan, Resumable: true, Acknowledged: true}
+	if got := state.Abort; got == nil || got.AbortedAt == 0 {
+		t.Fatalf("Abort = %+v", got)
+	} else if got.AbortedAt = 0; *got != want {
+		t.Errorf("Abort = %+v, want %+v", *got, want)
+	}
+
+	// Continue reruns EXECUTE from the context it started with.
+	resumed, err := loop.Continue(context.Background(), session.ID, "")
+	if err != nil {
+		t.Fatalf("Continue: %v", err)
+	}
+	if resumed.State != StateComplete {
+		t.Errorf("resumed state = %s, want COMPLETE", resumed.State)
+	}
+	if len(phase.seen) != 2 || phase.seen[1] != 1 {
+		t.Errorf("conversation lengths seen = %v, want the aborted message rolled back", phase.seen)
+	}
+}
+
+func TestDefaultAgentLoop_Abort_RunIgnoringCancellation(t *testing.T) {
+	loop, session, phase := newSlowToolLoop(t, WithAbortTimeout(50*time.Millisecond))
+	phase.ignoreCancel = true
+	results := startRun(t, loop, session, phase)
+
+	start := time.Now()
+	if err := loop.Abort(context.Background(), session.ID); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Abort took %v, want the abort timeout", elapsed)
+	}
+	state, _ := loop.GetState(session.ID)
+	if state.State != StateAborted || state.Abort == nil || state.Abort.Acknowledged {
+		t.Fatalf("state = %s %+v, want an unacknowledged abort", state.State, state.Abort)
+	}
+
+	// The run still ends aborted once the tool returns.
+	close(phase.release)
+	if result := <-results; result.State != StateAborted {
+		t.Errorf("result state = %s, want ABORTED", result.State)
+	}
+}
+
+func TestDefaultAgentLoop_Continue_NotResumable(t *testing.T) {
+	loop := NewDefaultAgentLoop()
+	session, _ := NewSession("/test/project", nil)
+	session.SetState(StateClarify)
+	loop.sessions.Put(session)
+
+	if err := loop.Abort(context.Background(), session.ID); err != nil {
+		t.Fatalf("Abort: %v", err)
+	}
+	if _, err := loop.Continue(context.Background(), session.ID, "go on"); !errors.Is(err, ErrSessionNotResumable) {
+		t.Errorf("Continue error = %v, want ErrSessionNotResumable", err)
+	}
+	if session.GetState() != StateAborted {
+		t.Errorf("State = %s, want ABORTED", session.GetState())
+	}
+}
@@ -0,0 +1,414 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxCitations caps the citation set. Symbols observed after the cap are
+// not citable, which keeps indices stable and the prompt block bounded.
+const MaxCitations = 100
+
+// citationMarkerRe matches citation markers such as [3] or [1, 4]. The
+// preceding character must not be part of an identifier or an index
+// expression, so args[0] and m[1][2] are not markers.
+var citationMarkerRe = regexp.MustCompile(`(^|[^\w\]\[])\[(\d{1,4}(?:\s*,\s*\d{1,4})*)\]`)
+
+// Citation is a symbol observed through a tool call that an answer may cite.
+type Citation struct {
+	// Index is the citation number used in answer markers, starting at 1.
+	Index int `json:"index"`
+
+	// SymbolID is the observed symbol's ID.
+	SymbolID string `json:"symbol_id"`
+
+	// File is the symbol's file path, relative to the project root.
+	File string `json:"file,omitempty"`
+
+	// Line is the symbol's 1-indexed line, or 0 if unknown.
+	Line int `json:"line,omitempty"`
+
+	// Tool is the tool call that first returned the symbol.
+	Tool string `json:"tool"`
+
+	// Step is the agent step of that tool call.
+	Step int `json:"step"`
+
+	// URI links to the symbol's location. Set by the server when a link
+	// template is configured; see LinkCitations.
+	URI string `json:"uri,omitempty"`
+}
+
+// Location returns "file:line", or just the file when the line is unknown.
+func (c Citation) Location() string {
+	if c.Line > 0 {
+		return fmt.Sprintf("%s:%d", c.File, c.Line)
+	}
+	return c.File
+}
+
+// UnverifiedCitation is a citation marker that does not match an observed symbol.
+type UnverifiedCitation struct {
+	// Index is the cited number.
+	Index int `json:"index"`
+
+	// Marker is the marker as written in the answer, e.g. "[7]".
+	Marker string `json:"marker"`
+}
+
+// ObservedSymbol is a symbol found in a tool result.
+type ObservedSymbol struct {
+	// ID is the symbol ID. Required.
+	ID string
+
+	// File and Line locate the symbol. If empty, they are parsed from an
+	// ID of the form "file:line:name".
+	File string
+	Line int
+}
+
+// CitationSet is the set of symbols observed via tools during a session.
+//
+// Description:
+//
+//	Symbols are numbered in the order they were first observed, so an
+//	index given to the model in one step still means the same symbol
+//	when the final answer is validated.
+//
+// Thread Safety: Safe for concurrent use.
+type CitationSet struct {
+	mu      sync.RWMutex
+	entries []Citation
+	byID    map[string]int
+}
+
+// NewCitationSet creates an empty citation set.
+func NewCitationSet() *CitationSet {
+	return &CitationSet{byID: make(map[string]int)}
+}
+
+// Observe adds the symbols a tool call returned.
+//
+// Inputs:
+//
+//	tool - The tool name.
+//	step - The agent step of the call.
+//	symbols - The symbols found in the result. Already-observed symbols
+//	          keep their original index and tool.
+//
+// Outputs:
+//
+//	int - The number of symbols added.
+func (s *CitationSet) Observe(tool string, step int, symbols []ObservedSymbol) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	added := 0
+	for _, sym := range symbols {
+		if sym.ID == "" || len(s.entries) >= MaxCitations {
+			continue
+		}
+		if _, ok := s.byID[sym.ID]; ok {
+			continue
+		}
+		file, line := sym.File, sym.Line
+		if file == "" {
+			file, line = parseSymbolLocation(sym.ID)
+		}
+		c := Citation{
+			Index:    len(s.entries) + 1,
+			SymbolID: sym.ID,
+			File:     file,
+			Line:     line,
+			Tool:     tool,
+			Step:     step,
+		}
+		s.entries = append(s.entries, c)
+		s.byID[sym.ID] = c.Index
+		added++
+	}
+	return added
+}
+
+// Len returns the number of citable symbols.
+func (s *CitationSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.entries)
+}
+
+// Lookup returns the citation with the given index.
+func (s *CitationSet) Lookup(index int) (Citation, bool) {
+	if s == nil {
+		return Citation{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if index < 1 || index > len(s.entries) {
+		return Citation{}, false
+	}
+	return s.entries[index-1], true
+}
+
+// Entries returns a copy of the citations in index order.
+func (s *CitationSet) Entries() []Citation {
+	if s == nil {
+		return nil
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Citation(nil), s.entries...)
+}
+
+// PromptBlock renders the citable symbols and the citation rules for the
+// answer-generation prompt. Empty if no symbols were observed.
+func (s *CitationSet) PromptBlock() string {
+	entries := s.Entries()
+	if len(entries) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\n## Citations\n")
+	b.WriteString("When you state a fact about a specific function, type or file, cite the symbol it comes from ")
+	b.WriteString("by writing its index in square brackets right after the claim, e.g. \"Tokens are checked in ValidateToken [2].\" ")
+	b.WriteString("Only cite indices from this list of symbols returned by your tool calls. ")
+	b.WriteString("Cite several symbols as [1, 3]. Do not cite symbols you have not seen in tool results.\n")
+	for _, c := range entries {
+		fmt.Fprintf(&b, "[%d] %s (%s)\n", c.Index, c.SymbolID, c.Location())
+	}
+	return b.String()
+}
+
+// CitationReport is the outcome of validating an answer's citations.
+type CitationReport struct {
+	// Answer is the answer, with unverified markers removed if stripping
+	// was requested.
+	Answer string
+
+	// Citations are the verified citations the answer uses, in index order.
+	Citations []Citation
+
+	// Unverified are markers whose index is not in the citation set, in
+	// order of appearance.
+	Unverified []UnverifiedCitation
+}
+
+// ValidateCitations checks an answer's citation markers against set.
+//
+// Description:
+//
+//	Finds [n] and [n, m] markers outside code spans and code blocks and
+//	checks each index against the citation set. Unverified indices are
+//	reported and, if strip is set, removed from the answer; a marker left
+//	with no indices is removed entirely.
+//
+// Inputs:
+//
+//	answer - The final answer text.
+//	set - The session's citation set. May be nil (every marker is unverified).
+//	strip - Remove unverified markers from the answer.
+//
+// Outputs:
+//
+//	CitationReport - The verified citations and unverified markers.
+//
+// Thread Safety: Safe for concurrent use.
+func ValidateCitations(answer string, set *CitationSet, strip bool) CitationReport {
+	report := CitationReport{Answer: answer}
+	cited := make(map[int]Citation)
+
+	var out strings.Builder
+	last := 0
+	for _, span := range proseSpans(answer) {
+		text := answer[span[0]:span[1]]
+		for _, m := range citationMarkerRe.FindAllStringSubmatchIndex(text, -1) {
+			markerStart, markerEnd := span[0]+m[4]-1, span[0]+m[5]+1
+			marker := answer[markerStart:markerEnd]
+			var kept []string
+			for _, part := range strings.Split(answer[span[0]+m[4]:span[0]+m[5]], ",") {
+				index, _ := strconv.Atoi(strings.TrimSpace(part))
+				if c, ok := set.Lookup(index); ok {
+					cited[index] = c
+					kept = append(kept, strconv.Itoa(index))
+					continue
+				}
+				report.Unverified = append(report.Unverified, UnverifiedCitation{Index: index, Marker: marker})
+			}
+			if !strip || len(kept) == len(strings.Split(marker, ",")) {
+				continue
+			}
+			start := markerStart
+			replacement := ""
+			if len(kept) > 0 {
+				replacement = "[" + strings.Join(kept, ", ") + "]"
+			} else if start > 0 && answer[start-1] == ' ' {
+				start--
+			}
+			out.WriteString(answer[last:start])
+			out.WriteString(replacement)
+			last = markerEnd
+		}
+	}
+	if strip && last > 0 {
+		out.WriteString(answer[last:])
+		report.Answer = out.String()
+	}
+
+	for _, c := range cited {
+		report.Citations = append(report.Citations, c)
+	}
+	sort.Slice(report.Citations, func(i, j int) bool {
+		return report.Citations[i].Index < report.Citations[j].Index
+	})
+	return report
+}
+
+// LinkCitations rewrites the citation markers of an answer as markdown
+// links to the cited locations.
+//
+// Description:
+//
+//	Each index of a marker outside code spans and code blocks whose
+//	citation has a URI becomes "[[n]](uri)"; a marker citing several
+//	becomes those links separated by ", ". Indices without a URI, and
+//	markers with none, are left as they are. Rewritten markers are not
+//	matched again, so linking an answer twice changes nothing.
+//
+// Inputs:
+//
+//	answer - The answer, after ValidateCitations.
+//	citations - The verified citations, with URIs set.
+//
+// Outputs:
+//
+//	string - The answer with linked markers.
+//
+// Thread Safety: Safe for concurrent use.
+func LinkCitations(answer string, citations []Citation) string {
+	uris := make(map[int]string, len(citations))
+	for _, c := range citations {
+		if c.URI != "" {
+			uris[c.Index] = markdownLinkEscaper.Replace(c.URI)
+		}
+	}
+	if len(uris) == 0 {
+		return answer
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, span := range proseSpans(answer) {
+		text := answer[span[0]:span[1]]
+		for _, m := range citationMarkerRe.FindAllStringSubmatchIndex(text, -1) {
+			markerStart, markerEnd := span[0]+m[4]-1, span[0]+m[5]+1
+			parts := strings.Split(answer[span[0]+m[4]:span[0]+m[5]], ",")
+			linked := make([]string, 0, len(parts))
+			anyLinked := false
+			for _, part := range parts {
+				index, _ := strconv.Atoi(strings.TrimSpace(part))
+				if uri, ok := uris[index]; ok {
+					linked = append(linked, fmt.Sprintf("[[%d]](%s)", index, uri))
+					anyLinked = true
+					continue
+				}
+				linked = append(linked, fmt.Sprintf("[%d]", index))
+			}
+			if !anyLinked {
+				continue
+			}
+			out.WriteString(answer[last:markerStart])
+			out.WriteString(strings.Join(linked, ", "))
+			last = markerEnd
+		}
+	}
+	if last == 0 {
+		return answer
+	}
+	out.WriteString(answer[last:])
+	return out.String()
+}
+
+// markdownLinkEscaper escapes the characters that would end a markdown
+// link destination.
+var markdownLinkEscaper = strings.NewReplacer("(", "%28", ")", "%29", " ", "%20")
+
+// proseSpans returns the [start, end) byte ranges of s outside fenced
+// code blocks and inline code spans.
+func proseSpans(s string) [][2]int {
+	var spans [][2]int
+	start := 0
+	inFence, inCode := false, false
+	for i := 0; i < len(s); i++ {
+		atLineStart := i == 0 || s[i-1] == '\n'
+		switch {
+		case atLineStart && strings.HasPrefix(s[i:], "```"):
+			if !inFence && !inCode && i > start {
+				spans = append(spans, [2]int{start, i})
+			}
+			inFence = !inFence
+			end := strings.IndexByte(s[i:], '\n')
+			if end < 0 {
+				i = len(s)
+			} else {
+				i += end
+			}
+			start = i + 1
+		case inFence:
+		case s[i] == '`':
+			if !inCode && i > start {
+				spans = append(spans, [2]int{start, i})
+			}
+			inCode = !inCode
+			start = i + 1
+		}
+	}
+	if !inFence && !inCode && start < len(s) {
+		spans = append(spans, [2]int{start, len(s)})
+	}
+	return spans
+}
+
+// parseSymbolLocation extracts file and line from a "file:line:name"
+// symbol ID. IDs in other formats yield the ID's prefix before the first
+// colon as the file and line 0.
+func parseSymbolLocation(id string) (string, int) {
+	parts := strings.Split(id, ":")
+	for i := len(parts) - 2; i > 0; i-- {
+		if line, err := strconv.Atoi(parts[i]); err == nil {
+			return strings.Join(parts[:i], ":"), line
+		}
+	}
+	if i := strings.IndexByte(id, ':'); i > 0 {
+		return id[:i], 0
+	}
+	return "", 0
+}
+
+// applyCitations validates the citation markers in a completed run's
+// response and records the outcome on the result.
+func applyCitations(session *Session, result *RunResult) {
+	if result.Response == "" {
+		return
+	}
+	strip := session.Config != nil && session.Config.StripUnverifiedCitations
+	report := ValidateCitations(result.Response, session.Citations(), strip)
+	result.Response = report.Answer
+	result.Citations = report.Citations
+	result.UnverifiedCitations = report.Unverified
+}
@@ -0,0 +1,148 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"strings"
+	"testing"
+)
+
+func newTestCitationSet() *CitationSet {
+	set := NewCitationSet()
+	set.Observe("find_symbol", 1, []ObservedSymbol{
+		{ID: "pkg/auth/jwt.go:10:ValidateToken"},
+		{ID: "pkg/auth/middleware.go:22:Middleware"},
+	})
+	set.Observe("find_callers", 2, []ObservedSymbol{
+		{ID: "pkg/auth/jwt.go:10:ValidateToken"},
+		{ID: "cmd/server.go:5:main", File: "cmd/server.go", Line: 5},
+	})
+	return set
+}
+
+func TestCitationSet_Observe(t *testing.T) {
+	set := newTestCitationSet()
+
+	if set.Len() != 3 {
+		t.Fatalf("Len = %d, want 3", set.Len())
+	}
+	c, ok := set.Lookup(1)
+	if !ok || c.File != "pkg/auth/jwt.go" || c.Line != 10 || c.Tool != "find_symbol" || c.Step != 1 {
+		t.Errorf("unexpected first citation: %+v", c)
+	}
+	if c, _ := set.Lookup(3); c.Tool != "find_callers" || c.Location() != "cmd/server.go:5" {
+		t.Errorf("unexpected third citation: %+v", c)
+	}
+	if _, ok := set.Lookup(4); ok {
+		t.Error("Lookup(4) should fail")
+	}
+
+	block := set.PromptBlock()
+	if !strings.Contains(block, "[2] pkg/auth/middleware.go:22:Middleware (pkg/auth/middleware.go:22)") {
+		t.Errorf("prompt block missing entry:\n%s", block)
+	}
+	if NewCitationSet().PromptBlock() != "" {
+		t.Error("empty set should render no prompt block")
+	}
+}
+
+func TestValidateCitations(t *testing.T) {
+	set := newTestCitationSet()
+
+	t.Run("verified and unverified", func(t *testing.T) {
+		answer := "Tokens are checked in ValidateToken [1]. The server wires it up [2, 3]. Sessions live in Redis [7]."
+		report := ValidateCitations(answer, set, false)
+
+		if report.Answer != answer {
+			t.Errorf("answer changed without strip: %q", report.Answer)
+		}
+		if len(report.Citations) != 3 || report.Citations[0].Index != 1 || report.Citations[2].SymbolID != "cmd/server.go:5:main" {
+			t.Errorf("unexpected citations: %+v", report.Citations)
+		}
+		if len(report.Unverified) != 1 || report.Unverified[0].Index != 7 || report.Unverified[0].Marker != "[7]" {
+			t.Errorf("unexpected unverified: %+v", report.Unverified)
+		}
+	})
+
+	t.Run("strip", func(t *testing.T) {
+		answer := "Checked in ValidateToken [1, 9]. Cached in Redis [7]."
+		report := ValidateCitations(answer, set, true)
+
+		want := "Checked in ValidateToken [1]. Cached in Redis."
+		if report.Answer != want {
+			t.Errorf("Answer = %q, want %q", report.Answer, want)
+		}
+		if len(report.Unverified) != 2 {
+			t.Errorf("expected 2 unverified, got %+v", report.Unverified)
+		}
+	})
+
+	t.Run("ignores code and index expressions", func(t *testing.T) {
+		answer := "Use `items[3]` or args[0].\n```go\nx := m[5]\ny := []int{1}[0]\n```\nSee [1]."
+		report := ValidateCitations(answer, set, false)
+
+		if len(report.Unverified) != 0 || len(report.Citations) != 1 {
+			t.Errorf("expected only [1], got citations %+v unverified %+v", report.Citations, report.Unverified)
+		}
+	})
+
+	t.Run("nil set", func(t *testing.T) {
+		report := ValidateCitations("Handled in Foo [1].", nil, false)
+		if len(report.Unverified) != 1 || len(report.Citations) != 0 {
+			t.Errorf("expected one unverified citation, got %+v", report)
+		}
+	})
+}
+
+func TestApplyCitations(t *testing.T) {
+	cfg := DefaultSessionConfig()
+	cfg.StripUnverifiedCitations = true
+	session, err := NewSession("/proj", cfg)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	session.Citations().Observe("find_symbol", 1, []ObservedSymbol{{ID: "a.go:3:A"}})
+
+	result := &RunResult{State: StateComplete, Response: "A is defined here [1] and used there [2]."}
+	applyCitations(session, result)
+
+	if result.Response != "A is defined here [1] and used there." {
+		t.Errorf("Response = %q", result.Response)
+	}
+	if len(result.Citations) != 1 || len(result.UnverifiedCitations) != 1 {
+		t.Errorf("unexpected citations %+v / %+v", result.Citations, result.UnverifiedCitations)
+	}
+}
+
+func TestLinkCitations(t *testing.T) {
+	citations := []Citation{
+		{Index: 1, SymbolID: "a.go:3:A", URI: "vscode://file/p/a.go:3"},
+		{Index: 2, SymbolID: "b.go:9:B"},
+		{Index: 3, SymbolID: "c (1).go:5:C", URI: "https://x/blob/abc/c%20(1).go#L5"},
+	}
+	answer := "A [1] calls B [2] and C [1, 3]. See `m[1]`:\n```\nx := args[1]\n[1]\n```\n"
+	want := "A [[1]](vscode://file/p/a.go:3) calls B [2] and C [[1]](vscode://file/p/a.go:3), " +
+		"[[3]](https://x/blob/abc/c%20%281%29.go#L5). See `m[1]`:\n```\nx := args[1]\n[1]\n```\n"
+
+	got := LinkCitations(answer, citations)
+	if got != want {
+		t.Errorf("LinkCitations =\n%q\nwant\n%q", got, want)
+	}
+	if again := LinkCitations(got, citations); again != got {
+		t.Errorf("linking twice changed the answer:\n%q", again)
+	}
+	if report := ValidateCitations(got, nil, false); len(report.Unverified) != 1 {
+		t.Errorf("linked answer has markers %+v, want only [2]", report.Unverified)
+	}
+	if got := LinkCitations(answer, citations[1:2]); got != answer {
+		t.Errorf("answer without URIs changed: %q", got)
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MaxClarificationOptions bounds the options of a ClarifyRequest.
+const MaxClarificationOptions = 8
+
+// Clarification paths: how the user answered a clarification request.
+const (
+	// ClarificationPathOption means the user selected one of the options.
+	ClarificationPathOption = "option"
+
+	// ClarificationPathFreeText means the user typed an answer.
+	ClarificationPathFreeText = "free_text"
+)
+
+// ClarificationOption is one answer the user can pick instead of typing.
+//
+// Description:
+//
+//	Options are derived from the tool results the session already has,
+//	e.g. the symbols of one name that a find_symbol call returned, never
+//	invented by the model. Selecting one continues the session as if the
+//	user had typed Text().
+type ClarificationOption struct {
+	// ID identifies the option within its request ("opt_1", "opt_2", ...).
+	ID string `json:"id"`
+
+	// Label is a short human-readable description.
+	Label string `json:"label"`
+
+	// SymbolID is the symbol the option refers to, if any.
+	SymbolID string `json:"symbol_id,omitempty"`
+
+	// FilePath is the file the option refers to, relative to the project.
+	FilePath string `json:"file_path,omitempty"`
+
+	// Line is the 1-indexed line in FilePath, 0 if unknown.
+	Line int `json:"line,omitempty"`
+
+	// Snippet is a short excerpt, such as the symbol's signature.
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// Text returns the clarification that selecting the option sends.
+func (o ClarificationOption) Text() string {
+	var sb strings.Builder
+	sb.WriteString("I mean ")
+	sb.WriteString(o.Label)
+	if o.SymbolID != "" {
+		sb.WriteString(fmt.Sprintf(" (symbol ID %s)", o.SymbolID))
+	} else if o.FilePath != "" && o.Line > 0 {
+		sb.WriteString(fmt.Sprintf(" (%s:%d)", o.FilePath, o.Line))
+	}
+	sb.WriteString(".")
+	return sb.String()
+}
+
+// ClarificationResponse is the user's answer to a clarification request,
+// handed from Continue to the clarify phase.
+type ClarificationResponse struct {
+	// Text is the clarification added to the conversation.
+	Text string
+
+	// OptionID is the selected option, empty for a free-text answer.
+	OptionID string
+}
+
+// Path returns ClarificationPathOption or ClarificationPathFreeText.
+func (r ClarificationResponse) Path() string {
+	if r.OptionID != "" {
+		return ClarificationPathOption
+	}
+	return ClarificationPathFreeText
+}
+
+// SetPendingClarification stores the clarification request the session is
+// waiting on. Nil clears it.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) SetPendingClarification(req *ClarifyRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pendingClarification = req.clone()
+}
+
+// PendingClarification returns a copy of the clarification request the
+// session is waiting on, or nil.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) PendingClarification() *ClarifyRequest {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pendingClarification.clone()
+}
+
+// ClarificationOption looks up an option of the pending clarification
+// request by ID.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) ClarificationOption(id string) (ClarificationOption, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.pendingClarification == nil {
+		return ClarificationOption{}, false
+	}
+	for _, opt := range s.pendingClarification.Options {
+		if opt.ID == id {
+			return opt, true
+		}
+	}
+	return ClarificationOption{}, false
+}
+
+// SetClarificationResponse stores the user's answer for the clarify
+// phase to pick up with TakeClarificationResponse.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) SetClarificationResponse(resp ClarificationResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clarificationResponse = &resp
+}
+
+// TakeClarificationResponse returns and clears the user's answer.
+//
+// Outputs:
+//
+//	ClarificationResponse - The answer.
+//	bool - False if there is no answer waiting.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) TakeClarificationResponse() (ClarificationResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.clarificationResponse == nil {
+		return ClarificationResponse{}, false
+	}
+	resp := *s.clarificationResponse
+	s.clarificationResponse = nil
+	return resp, true
+}
+
+// clone returns a copy of the request that shares nothing with it.
+func (r *ClarifyRequest) clone() *ClarifyRequest {
+	if r == nil {
+		return nil
+	}
+	out := *r
+	if r.Options != nil {
+		out.Options = make([]ClarificationOption, len(r.Options))
+		copy(out.Options, r.Options)
+	}
+	return &out
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// clarifyingPlanPhase asks for clarification on its first run only.
+type clarifyingPlanPhase struct {
+	runs int
+}
+
+func (p *clarifyingPlanPhase) Name() string { return "plan" }
+
+func (p *clarifyingPlanPhase) Execute(ctx context.Context, deps any) (AgentState, error) {
+	p.runs++
+	if p.runs == 1 {
+		return StateClarify, nil
+	}
+	return StateExecute, nil
+}
+
+// optionsClarifyPhase offers two options, like the clarify phase, and
+// moves to PLAN once Continue left an answer on the session.
+type optionsClarifyPhase struct {
+	session *Session
+	answers []ClarificationResponse
+}
+
+func (p *optionsClarifyPhase) Name() string { return "clarify" }
+
+func (p *optionsClarifyPhase) Execute(ctx context.Context, deps any) (AgentState, error) {
+	if resp, ok := p.session.TakeClarificationResponse(); ok {
+		p.answers = append(p.answers, resp)
+		return StatePlan, nil
+	}
+	p.session.SetPendingClarification(&ClarifyRequest{
+		Question: "Which Handler do you mean?",
+		Options: []ClarificationOption{
+			{ID: "opt_1", Label: "Handler in pkg/auth/handler.go", SymbolID: "pkg/auth/handler.go:12:Handler"},
+			{ID: "opt_2", Label: "Handler in pkg/admin/handler.go", SymbolID: "pkg/admin/handler.go:40:Handler"},
+		},
+	})
+	return StateClarify, ErrAwaitingClarification
+}
+
+// newClarifyingLoop returns a loop whose plan phase asks for clarification
+// once, with a session already paused in CLARIFY.
+func newClarifyingLoop(t *testing.T) (*DefaultAgentLoop, *Session, *optionsClarifyPhase) {
+	t.Helper()
+	session, err := NewSession("/test/project", nil)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	clarify := &optionsClarifyPhase{session: session}
+	registry := NewMockPhaseRegistry()
+	registry.RegisterPhase(StateInit, &MockPhase{name: "init", nextState: StatePlan})
+	registry.RegisterPhase(StatePlan, &clarifyingPlanPhase{})
+	registry.RegisterPhase(StateExecute, &MockPhase{name: "execute", nextState: StateComplete})
+	registry.RegisterPhase(StateClarify, clarify)
+	loop := NewDefaultAgentLoop(WithPhaseRegistry(registry))
+
+	result, err := loop.Run(context.Background(), session, "What does Handler do?")
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.State != StateClarify || result.NeedsClarify == nil || len(result.NeedsClarify.Options) != 2 {
+		t.Fatalf("Run result = %s %+v, want CLARIFY with 2 options", result.State, result.NeedsClarify)
+	}
+	return loop, session, clarify
+}
+
+func TestDefaultAgentLoop_ContinueWithOption(t *testing.T) {
+	loop, session, clarify := newClarifyingLoop(t)
+
+	result, err := loop.ContinueWithOption(context.Background(), session.ID, "opt_2")
+	if err != nil {
+		t.Fatalf("ContinueWithOption: %v", err)
+	}
+	if result.State != StateComplete {
+		t.Errorf("State = %s, want COMPLETE", result.State)
+	}
+	if len(clarify.answers) != 1 || clarify.answers[0].OptionID != "opt_2" {
+		t.Fatalf("clarify phase answers = %+v", clarify.answers)
+	}
+	if !strings.Contains(clarify.answers[0].Text, "pkg/admin/handler.go:40:Handler") {
+		t.Errorf("answer text = %q, want the option's symbol", clarify.answers[0].Text)
+	}
+	if !strings.HasPrefix(session.LastQuery, "What does Handler do?") {
+		t.Errorf("LastQuery = %q, want the original query refined", session.LastQuery)
+	}
+	if session.PendingClarification() != nil {
+		t.Error("pending clarification not cleared")
+	}
+
+	transcript := BuildTranscript(session, result)
+	if len(transcript.Clarifications) != 1 {
+		t.Fatalf("transcript clarifications = %+v", transcript.Clarifications)
+	}
+	got := transcript.Clarifications[0]
+	if got.Path != ClarificationPathOption || got.OptionID != "opt_2" || got.Question != "Which Handler do you mean?" {
+		t.Errorf("transcript clarification = %+v", got)
+	}
+}
+
+func TestDefaultAgentLoop_ContinueFreeTextWithOptions(t *testing.T) {
+	loop, session, clarify := newClarifyingLoop(t)
+
+	result, err := loop.Continue(context.Background(), session.ID, "the admin one")
+	if err != nil {
+		t.Fatalf("Continue: %v", err)
+	}
+	if result.State != StateComplete {
+		t.Errorf("State = %s, want COMPLETE", result.State)
+	}
+	if len(clarify.answers) != 1 || clarify.answers[0].OptionID != "" || clarify.answers[0].Text != "the admin one" {
+		t.Errorf("clarify phase answers = %+v", clarify.answers)
+	}
+
+	transcript := BuildTranscript(session, result)
+	if len(transcript.Clarifications) != 1 || transcript.Clarifications[0].Path != ClarificationPathFreeText {
+		t.Errorf("transcript clarifications = %+v", transcript.Clarifications)
+	}
+}
+
+func TestDefaultAgentLoop_ContinueWithOption_Unknown(t *testing.T) {
+	loop, session, _ := newClarifyingLoop(t)
+
+	_, err := loop.ContinueWithOption(context.Background(), session.ID, "opt_9")
+	if !errors.Is(err, ErrUnknownClarificationOption) {
+		t.Errorf("error = %v, want ErrUnknownClarificationOption", err)
+	}
+	if session.GetState() != StateClarify {
+		t.Errorf("State = %s, want CLARIFY", session.GetState())
+	}
+}
+
+func TestDefaultAgentLoop_ContinueWithOption_NotInClarify(t *testing.T) {
+	loop := NewDefaultAgentLoop()
+	session, _ := NewSession("/test/project", nil)
+	session.SetState(StateComplete)
+	loop.sessions.Put(session)
+
+	if _, err := loop.ContinueWithOption(context.Background(), session.ID, "opt_1"); !errors.Is(err, ErrNotInClarifyState) {
+		t.Errorf("error = %v, want ErrNotInClarifyState", err)
+	}
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -293,7 +294,7 @@ func (c *LLMClassifier) classifyWithRetry(ctx context.Context, query string, sta
 		slog.Debug("classification attempt failed, retrying",
 			slog.Int("attempt", attempt+1),
 			slog.Int("max_retries", c.config.MaxRetries),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		recordRetry()
 	}
@@ -0,0 +1,122 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+
+package classifier
+
+import (
+	"regexp"
+	"strings"
+)
+
+// LookupKind is the kind of a simple lookup query.
+type LookupKind string
+
+const (
+	// LookupCallers asks who calls a symbol.
+	LookupCallers LookupKind = "callers"
+
+	// LookupCallees asks what a symbol calls.
+	LookupCallees LookupKind = "callees"
+
+	// LookupDefinition asks where a symbol is defined.
+	LookupDefinition LookupKind = "definition"
+
+	// LookupExplain asks what a symbol is or does.
+	LookupExplain LookupKind = "explain"
+)
+
+// Lookup is a query about a single named symbol that one or two graph
+// tools can answer.
+type Lookup struct {
+	// Kind is what the query asks about the symbol.
+	Kind LookupKind
+
+	// Symbol is the symbol name as written in the query, e.g. "ParseConfig"
+	// or "Server.Start".
+	Symbol string
+}
+
+// lookupSymbol matches one identifier, optionally qualified with dots
+// ("pkg.Type.Method"), in optional backticks or quotes.
+const lookupSymbol = "[`'\"]?([A-Za-z_][A-Za-z0-9_]*(?:\\.[A-Za-z_][A-Za-z0-9_]*)*)(?:\\(\\))?[`'\"]?"
+
+// lookupPatterns match a whole query. Each pattern is anchored so that a
+// query with more to it than the lookup ("who calls X and why does it
+// fail?") is not treated as simple. The first capture group is the symbol.
+var lookupPatterns = []struct {
+	kind    LookupKind
+	pattern *regexp.Regexp
+}{
+	{LookupCallers, lookupRegexp(`(?:who|what) calls (?:the )?(?:function |method )?` + lookupSymbol)},
+	{LookupCallers, lookupRegexp(`(?:show |list |find )?(?:me )?(?:all )?(?:the )?callers of (?:the )?(?:function |method )?` + lookupSymbol)},
+	{LookupCallers, lookupRegexp(`where is ` + lookupSymbol + ` (?:called|used)(?: from)?`)},
+	{LookupCallees, lookupRegexp(`what (?:functions |methods )?does ` + lookupSymbol + ` call`)},
+	{LookupCallees, lookupRegexp(`(?:show |list |find )?(?:me )?(?:all )?(?:the )?callees of (?:the )?(?:function |method )?` + lookupSymbol)},
+	{LookupDefinition, lookupRegexp(`where (?:is|are) ` + lookupSymbol + ` (?:defined|declared|implemented)`)},
+	{LookupDefinition, lookupRegexp(`(?:find|show me|go to) (?:the )?(?:definition|declaration) of ` + lookupSymbol)},
+	{LookupExplain, lookupRegexp(`what does (?:the )?(?:function |method |type )?` + lookupSymbol + ` do`)},
+	{LookupExplain, lookupRegexp(`(?:explain|describe) (?:the )?(?:function |method |type )?` + lookupSymbol)},
+	{LookupExplain, lookupRegexp(`what is (?:the )?(?:function |method |type )?` + lookupSymbol)},
+}
+
+// lookupRegexp anchors body to the whole query, case-insensitively, with
+// optional trailing punctuation.
+func lookupRegexp(body string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)^\s*` + body + `\s*[?.!]*\s*$`)
+}
+
+// lookupStopWords are captures that are not symbol names ("what is the
+// purpose" matches "what is <symbol>").
+var lookupStopWords = map[string]bool{
+	"it": true, "this": true, "that": true, "the": true, "a": true, "an": true,
+	"purpose": true, "difference": true, "architecture": true, "code": true,
+	"project": true, "codebase": true, "repo": true, "repository": true,
+}
+
+// ClassifyLookup recognizes simple lookup queries about one symbol.
+//
+// Description:
+//
+//	Matches the whole query against a small set of anchored patterns
+//	("who calls X", "what does X call", "where is X defined", "what does
+//	X do", "explain X"). Queries with additional clauses do not match, so
+//	a match is a strong signal that one or two graph tools answer the
+//	question without planning.
+//
+// Inputs:
+//
+//	query - The user's question.
+//
+// Outputs:
+//
+//	Lookup - The lookup kind and symbol name. Zero when ok is false.
+//	bool - True if the query is a simple lookup.
+//
+// Example:
+//
+//	lookup, ok := NewRegexClassifier().ClassifyLookup("Who calls ParseConfig?")
+//	// lookup == Lookup{Kind: LookupCallers, Symbol: "ParseConfig"}, ok == true
+//
+// Thread Safety: This method is safe for concurrent use.
+func (c *RegexClassifier) ClassifyLookup(query string) (Lookup, bool) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return Lookup{}, false
+	}
+	for _, lp := range lookupPatterns {
+		m := lp.pattern.FindStringSubmatch(query)
+		if m == nil {
+			continue
+		}
+		symbol := m[1]
+		if lookupStopWords[strings.ToLower(symbol)] {
+			return Lookup{}, false
+		}
+		return Lookup{Kind: lp.kind, Symbol: symbol}, true
+	}
+	return Lookup{}, false
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+
+package classifier
+
+import "testing"
+
+func TestRegexClassifier_ClassifyLookup(t *testing.T) {
+	classifier := NewRegexClassifier()
+
+	tests := []struct {
+		query  string
+		want   Lookup
+		wantOK bool
+	}{
+		{"Who calls ParseConfig?", Lookup{LookupCallers, "ParseConfig"}, true},
+		{"what calls the function `handleRequest`", Lookup{LookupCallers, "handleRequest"}, true},
+		{"Show me all callers of Server.Start", Lookup{LookupCallers, "Server.Start"}, true},
+		{"where is parse_args used?", Lookup{LookupCallers, "parse_args"}, true},
+		{"What does BuildGraph call?", Lookup{LookupCallees, "BuildGraph"}, true},
+		{"callees of main", Lookup{LookupCallees, "main"}, true},
+		{"Where is NewRouter defined?", Lookup{LookupDefinition, "NewRouter"}, true},
+		{"find the definition of Config", Lookup{LookupDefinition, "Config"}, true},
+		{"What does ResolveSymbol() do?", Lookup{LookupExplain, "ResolveSymbol"}, true},
+		{"explain Graph.Freeze", Lookup{LookupExplain, "Graph.Freeze"}, true},
+		{"What is CachedGraph", Lookup{LookupExplain, "CachedGraph"}, true},
+
+		// Not simple lookups.
+		{"", Lookup{}, false},
+		{"What is the purpose of this project?", Lookup{}, false},
+		{"what is it", Lookup{}, false},
+		{"Who calls ParseConfig and why does it fail on empty input?", Lookup{}, false},
+		{"How does authentication work?", Lookup{}, false},
+		{"What tests exist?", Lookup{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got, ok := classifier.ClassifyLookup(tt.query)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ClassifyLookup(%q) = %+v, %v; want %+v, %v", tt.query, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
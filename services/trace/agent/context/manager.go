@@ -28,8 +28,10 @@ import (
 	"sync"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 	"github.com/google/uuid"
@@ -247,6 +249,10 @@ type Manager struct {
 	index     *index.SymbolIndex
 	config    ManagerConfig
 
+	// parsers parse changed files the graph does not contain (see
+	// AssembleWithDiff). Nil skips those files.
+	parsers *ast.ParserRegistry
+
 	// relevance tracks the relevance score for each entry ID.
 	relevance map[string]float64
 
@@ -310,6 +316,7 @@ func NewManager(g *graph.Graph, idx *index.SymbolIndex, config *ManagerConfig) (
 //
 //	Uses the underlying Assembler to build initial context, then
 //	wraps it in an AssembledContext suitable for the agent loop.
+//	Equivalent to AssembleWithBudget with only MaxTotalTokens set.
 //
 // Inputs:
 //
@@ -324,14 +331,132 @@ func NewManager(g *graph.Graph, idx *index.SymbolIndex, config *ManagerConfig) (
 //
 // Thread Safety: This method is safe for concurrent use.
 func (m *Manager) Assemble(ctx context.Context, query string, budget int) (*agent.AssembledContext, error) {
+	return m.AssembleWithBudget(ctx, query, cbcontext.ContextBudget{MaxTotalTokens: budget})
+}
+
+// AssembleWithBudget builds initial context for a query under a ContextBudget.
+//
+// Description:
+//
+//	Applies the per-symbol and per-file limits and the ranking strategy of
+//	budget, and records the included and dropped symbols in the returned
+//	context's Assembly so callers can report what the model saw.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	query - The user's query.
+//	budget - Limits and ranking. A non-positive MaxTotalTokens uses
+//	  ManagerConfig.InitialBudget; an empty Ranking uses "relevance".
+//
+// Outputs:
+//
+//	*agent.AssembledContext - The assembled context.
+//	error - Non-nil if assembly fails or the ranking strategy is unknown.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (m *Manager) AssembleWithBudget(ctx context.Context, query string, budget cbcontext.ContextBudget) (*agent.AssembledContext, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.assembleSeeded(ctx, query, budget, nil)
+}
+
+// WithParsers sets the parsers used for changed files the graph does not
+// contain, such as files a reviewed diff adds.
+//
+// Thread Safety: Call before the manager is shared.
+func (m *Manager) WithParsers(parsers *ast.ParserRegistry) *Manager {
+	m.parsers = parsers
+	return m
+}
+
+// AssembleWithDiff builds initial context for reviewing a diff.
+//
+// Description:
+//
+//	Maps the diff's changed lines onto symbols (see
+//	cbcontext.Assembler.DiffSeeds) and assembles context that leads with
+//	the changed symbols, then their direct callers and callees, then
+//	whatever the query matches. The system prompt lists the changed
+//	symbols so the plan starts from them.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	query - The user's query.
+//	budget - Limits and ranking, as for AssembleWithBudget.
+//	files - The diff's changed lines (see diff.ChangedLines).
+//
+// Outputs:
+//
+//	*agent.AssembledContext - The assembled context.
+//	[]agent.DiffSymbol - The symbols derived from the diff, changed ones
+//	  first, marked with whether they fit in the budget.
+//	error - Non-nil if assembly fails.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (m *Manager) AssembleWithDiff(ctx context.Context, query string, budget cbcontext.ContextBudget, files []diff.FileLines) (*agent.AssembledContext, []agent.DiffSymbol, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if budget <= 0 {
-		budget = m.config.InitialBudget
+	seeds := m.assembler.DiffSeeds(ctx, files, m.parsers)
+	assembled, err := m.assembleSeeded(ctx, query, budget, seeds)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	result, err := m.assembler.Assemble(ctx, query, budget)
+	inContext := make(map[string]bool, len(assembled.CodeContext))
+	for _, entry := range assembled.CodeContext {
+		inContext[entry.ID] = true
+	}
+	symbols := make([]agent.DiffSymbol, 0, len(seeds))
+	for _, seed := range seeds {
+		symbols = append(symbols, agent.DiffSymbol{
+			SymbolID:  seed.Symbol.ID,
+			Name:      seed.Symbol.Name,
+			Kind:      seed.Symbol.Kind.String(),
+			FilePath:  seed.Symbol.FilePath,
+			StartLine: seed.Symbol.StartLine,
+			EndLine:   seed.Symbol.EndLine,
+			Relation:  string(seed.Relation),
+			Temporary: seed.Temporary,
+			InContext: inContext[seed.Symbol.ID],
+		})
+	}
+	if note := formatDiffNote(symbols); note != "" {
+		assembled.SystemPrompt += "\n\n" + note
+	}
+	return assembled, symbols, nil
+}
+
+// formatDiffNote lists the symbols derived from a diff for the system
+// prompt. Returns "" if there are none.
+func formatDiffNote(symbols []agent.DiffSymbol) string {
+	if len(symbols) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("## Change Under Review\n\n")
+	b.WriteString("The user's diff changes the symbols marked \"changed\". Review those first; callers and callees show how the change propagates.\n\n")
+	for _, sym := range symbols {
+		fmt.Fprintf(&b, "- %s %s (%s:%d-%d) [%s", sym.Relation, sym.Name, sym.FilePath, sym.StartLine, sym.EndLine, sym.Kind)
+		if sym.Temporary {
+			b.WriteString(", new file")
+		}
+		b.WriteString("]\n")
+	}
+	return b.String()
+}
+
+// assembleSeeded is AssembleWithBudget with seeds leading the context.
+// The caller must hold m.mu.
+func (m *Manager) assembleSeeded(ctx context.Context, query string, budget cbcontext.ContextBudget, seeds []cbcontext.Seed) (*agent.AssembledContext, error) {
+	if budget.MaxTotalTokens <= 0 {
+		budget.MaxTotalTokens = m.config.InitialBudget
+	}
+
+	result, err := m.assembler.AssembleWithSeeds(ctx, query, budget, seeds)
 	if err != nil {
 		return nil, fmt.Errorf("assemble context: %w", err)
 	}
@@ -345,12 +470,24 @@ func (m *Manager) Assemble(ctx context.Context, query string, budget int) (*agen
 		ConversationHistory: make([]agent.Message, 0),
 		TotalTokens:         result.TokensUsed,
 		Relevance:           make(map[string]float64),
+		Assembly:            toContextAssembly(result),
 	}
 
 	// Parse the assembled context into structured entries
 	// The Assembler returns markdown-formatted context
-	entries := m.parseContextEntries(result.Context, result.SymbolsIncluded)
+	temporary := make(map[string]*ast.Symbol)
+	for _, seed := range seeds {
+		if seed.Temporary {
+			temporary[seed.Symbol.ID] = seed.Symbol
+		}
+	}
+	entries := m.parseContextEntries(result.Context, result.SymbolsIncluded, temporary)
 	for _, entry := range entries {
+		// Entries carry full symbol source; hold them to the same per-symbol cap.
+		if truncated, ok := cbcontext.TruncateToTokens(entry.Content, budget.MaxSymbolTokens); ok {
+			entry.Content = truncated
+			entry.Tokens = estimateTokens(entry.Content)
+		}
 		assembled.CodeContext = append(assembled.CodeContext, entry)
 		assembled.Relevance[entry.ID] = entry.Relevance
 		m.relevance[entry.ID] = entry.Relevance
@@ -372,6 +509,39 @@ func (m *Manager) Assemble(ctx context.Context, query string, budget int) (*agen
 	return assembled, nil
 }
 
+// toContextAssembly converts assembler budget metadata for the agent.
+//
+// The result is immutable once built and may be shared between copies of
+// an AssembledContext.
+func toContextAssembly(result *cbcontext.ContextResult) *agent.ContextAssembly {
+	convert := func(entries []cbcontext.BudgetEntry) []agent.ContextSymbolBudget {
+		out := make([]agent.ContextSymbolBudget, 0, len(entries))
+		for _, e := range entries {
+			out = append(out, agent.ContextSymbolBudget{
+				SymbolID:  e.SymbolID,
+				Name:      e.Name,
+				FilePath:  e.FilePath,
+				Tokens:    e.Tokens,
+				Score:     e.Score,
+				Depth:     e.Depth,
+				Truncated: e.Truncated,
+				Reason:    e.Reason,
+			})
+		}
+		return out
+	}
+
+	return &agent.ContextAssembly{
+		Ranking:         result.Budget.Ranking,
+		MaxTotalTokens:  result.Budget.MaxTotalTokens,
+		MaxSymbolTokens: result.Budget.MaxSymbolTokens,
+		MaxFiles:        result.Budget.MaxFiles,
+		TokensUsed:      result.TokensUsed,
+		Included:        convert(result.Included),
+		Dropped:         convert(result.Dropped),
+	}
+}
+
 // parseContextEntries parses the assembled context into structured entries.
 //
 // Description:
@@ -385,6 +555,7 @@ func (m *Manager) Assemble(ctx context.Context, query string, budget int) (*agen
 //
 //	contextStr - The raw context string from Assembler (used for token estimation).
 //	symbolIDs - List of symbol IDs included in the context, ordered by relevance.
+//	temporary - Included symbols that are not in the index, by ID.
 //
 // Outputs:
 //
@@ -394,7 +565,7 @@ func (m *Manager) Assemble(ctx context.Context, query string, budget int) (*agen
 //
 //	Currently uses simple position-based relevance scoring (1.0 - 0.05*index).
 //	Does not parse contextStr for detailed content extraction.
-func (m *Manager) parseContextEntries(contextStr string, symbolIDs []string) []agent.CodeEntry {
+func (m *Manager) parseContextEntries(contextStr string, symbolIDs []string, temporary map[string]*ast.Symbol) []agent.CodeEntry {
 	entries := make([]agent.CodeEntry, 0, len(symbolIDs))
 
 	// Simple parsing - in practice, the Assembler could return structured data
@@ -407,11 +578,16 @@ func (m *Manager) parseContextEntries(contextStr string, symbolIDs []string) []a
 		}
 
 		// Try to get symbol details from index
-		if sym, ok := m.index.GetByID(symbolID); ok {
+		sym, ok := m.index.GetByID(symbolID)
+		if !ok {
+			sym, ok = temporary[symbolID]
+		}
+		if ok {
 			entry.FilePath = sym.FilePath
 			entry.SymbolName = sym.Name
-			// Use full source code from assembler instead of just signature
-			entry.Content = m.assembler.GetSymbolSourceCode(sym)
+			// Use full source code from assembler instead of just signature,
+			// led by the symbol's annotations (deprecated, tier, ...).
+			entry.Content = m.assembler.AnnotationNote(sym) + m.assembler.GetSymbolSourceCode(sym)
 			entry.Tokens = estimateTokens(entry.Content)
 		}
 
@@ -666,6 +842,10 @@ func (m *Manager) Update(ctx context.Context, current *agent.AssembledContext, r
 		Truncated:    truncated,
 		Tool:         toolName,          // IT_CRS_03 AC-3
 		ProofDelta:   result.ProofDelta, // IT_CRS_03 AC-8
+		NextCursor:   result.NextCursor,
+	}
+	if param, ok := result.Metadata[tools.MetaInvalidParameter].(string); ok {
+		agentResult.InvalidParameter = param
 	}
 	updated.ToolResults = append(updated.ToolResults, agentResult)
 	updated.TotalTokens += agentResult.TokensUsed
@@ -1048,6 +1228,7 @@ func (m *Manager) copyContext(ctx *agent.AssembledContext) *agent.AssembledConte
 		ConversationHistory: make([]agent.Message, len(ctx.ConversationHistory)),
 		TotalTokens:         ctx.TotalTokens,
 		Relevance:           make(map[string]float64),
+		Assembly:            ctx.Assembly,
 	}
 
 	copy(copied.CodeContext, ctx.CodeContext)
@@ -8,11 +8,19 @@
 package context
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 )
 
 func TestDefaultManagerConfig_SystemPrompt(t *testing.T) {
@@ -305,3 +313,84 @@ func TestSystemPromptOrder(t *testing.T) {
 		}
 	})
 }
+
+func TestToContextAssembly(t *testing.T) {
+	result := &cbcontext.ContextResult{
+		TokensUsed: 120,
+		Budget:     cbcontext.ContextBudget{MaxTotalTokens: 500, MaxSymbolTokens: 100, MaxFiles: 2, Ranking: cbcontext.RankingRecency},
+		Included: []cbcontext.BudgetEntry{
+			{SymbolID: "a.go:1:A", Name: "A", FilePath: "a.go", Tokens: 100, Truncated: true},
+		},
+		Dropped: []cbcontext.BudgetEntry{
+			{SymbolID: "c.go:1:C", Name: "C", FilePath: "c.go", Reason: cbcontext.DropReasonMaxFiles},
+		},
+	}
+
+	got := toContextAssembly(result)
+
+	if got.Ranking != cbcontext.RankingRecency || got.MaxTotalTokens != 500 || got.MaxSymbolTokens != 100 || got.MaxFiles != 2 {
+		t.Errorf("budget not copied: %+v", got)
+	}
+	if got.TokensUsed != 120 {
+		t.Errorf("TokensUsed = %d, want 120", got.TokensUsed)
+	}
+	if len(got.Included) != 1 || !got.Included[0].Truncated || got.Included[0].Tokens != 100 {
+		t.Errorf("Included = %+v", got.Included)
+	}
+	if len(got.Dropped) != 1 || got.Dropped[0].Reason != cbcontext.DropReasonMaxFiles {
+		t.Errorf("Dropped = %+v", got.Dropped)
+	}
+}
+
+func TestManager_AssembleWithDiff(t *testing.T) {
+	root := t.TempDir()
+	source := "package main\n\nfunc Start() {\n\tstop()\n}\n\nfunc stop() {}\n"
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := graph.NewGraph(root)
+	idx := index.NewSymbolIndex()
+	start := &ast.Symbol{ID: "main.go:3:Start", Name: "Start", Kind: ast.SymbolKindFunction, FilePath: "main.go", StartLine: 3, EndLine: 5, Language: "go"}
+	stop := &ast.Symbol{ID: "main.go:7:stop", Name: "stop", Kind: ast.SymbolKindFunction, FilePath: "main.go", StartLine: 7, EndLine: 7, Language: "go"}
+	for _, sym := range []*ast.Symbol{start, stop} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+		if err := idx.Add(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.AddEdge(start.ID, stop.ID, graph.EdgeTypeCalls, ast.Location{FilePath: "main.go", StartLine: 4}); err != nil {
+		t.Fatal(err)
+	}
+	g.Freeze()
+
+	m, err := NewManager(g, idx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := diff.ChangedLines("--- a/main.go\n+++ b/main.go\n@@ -3,3 +3,3 @@\n func Start() {\n-\tgo stop()\n+\tstop()\n }\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assembled, symbols, err := m.AssembleWithDiff(context.Background(), "review this change", cbcontext.ContextBudget{}, files)
+	if err != nil {
+		t.Fatalf("AssembleWithDiff: %v", err)
+	}
+	if len(symbols) != 2 || symbols[0].Name != "Start" || symbols[0].Relation != "changed" ||
+		symbols[1].Name != "stop" || symbols[1].Relation != "callee" {
+		t.Fatalf("symbols = %+v", symbols)
+	}
+	if !symbols[0].InContext || !symbols[1].InContext {
+		t.Errorf("both symbols fit the default budget: %+v", symbols)
+	}
+	if len(assembled.CodeContext) == 0 || assembled.CodeContext[0].ID != start.ID ||
+		!strings.Contains(assembled.CodeContext[0].Content, "\tstop()") {
+		t.Errorf("context must lead with the changed symbol's source: %+v", assembled.CodeContext)
+	}
+	if !strings.Contains(assembled.SystemPrompt, "## Change Under Review") ||
+		!strings.Contains(assembled.SystemPrompt, "- changed Start (main.go:3-5) [function]") {
+		t.Errorf("system prompt lacks the changed symbols:\n%s", assembled.SystemPrompt)
+	}
+}
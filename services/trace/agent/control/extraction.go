@@ -16,6 +16,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // ExtractionConfig configures buffer extraction behavior.
@@ -147,7 +149,7 @@ func (e *BufferExtractor) Extract(state AgentStateInput) ExtractionResult {
 	successfulResults := e.collectSuccessfulResults(state.ToolResults)
 	if len(successfulResults) == 0 {
 		e.logger.Warn("no tool results to extract",
-			slog.String("query", truncateQuery(state.Query, 100)),
+			telemetry.SensitiveLog("query", truncateQuery(state.Query, 100)),
 		)
 		return ExtractionResult{
 			Response:   e.buildNoInfoResponse(state.Query),
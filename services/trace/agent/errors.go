@@ -38,6 +38,13 @@ var (
 	// ErrCanceled indicates the operation was canceled via context.
 	ErrCanceled = errors.New("operation canceled")
 
+	// ErrAborted is the cancellation cause of a run stopped by Abort.
+	ErrAborted = errors.New("run aborted")
+
+	// ErrSessionNotResumable indicates an aborted session has no phase
+	// checkpoint to resume from.
+	ErrSessionNotResumable = errors.New("aborted session cannot be resumed")
+
 	// ErrInitFailed indicates Trace initialization failed.
 	ErrInitFailed = errors.New("code buddy initialization failed")
 
@@ -50,6 +57,10 @@ var (
 	// ErrLLMUnavailable indicates the LLM service is unavailable.
 	ErrLLMUnavailable = errors.New("LLM service unavailable")
 
+	// ErrContextOverflow indicates a prompt could not be reduced to fit the
+	// model's context window.
+	ErrContextOverflow = errors.New("prompt exceeds the model's context window")
+
 	// ErrSafetyBlocked indicates a safety check blocked the operation.
 	ErrSafetyBlocked = errors.New("operation blocked by safety check")
 
@@ -66,4 +77,11 @@ var (
 	// NOTE: This is used for flow control in the agent loop, not as a true error.
 	// The loop returns this when it needs to pause for user input via Continue().
 	ErrAwaitingClarification = errors.New("awaiting user clarification")
+
+	// ErrUnknownClarificationOption indicates ContinueWithOption was called
+	// with an option ID the pending clarification request does not offer.
+	ErrUnknownClarificationOption = errors.New("unknown clarification option")
+
+	// ErrInvalidPipeline indicates a phase pipeline config failed validation.
+	ErrInvalidPipeline = errors.New("invalid phase pipeline")
 )
@@ -14,6 +14,8 @@ import (
 	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // LoggingHandler creates a handler that logs events.
@@ -61,7 +63,7 @@ func LoggingHandler(logger *slog.Logger, level slog.Level) Handler {
 				slog.Duration("duration", data.Duration),
 			)
 			if data.Error != "" {
-				attrs = append(attrs, slog.String("error", data.Error))
+				attrs = append(attrs, telemetry.SensitiveLog("error", data.Error))
 			}
 
 		case *LLMRequestData:
@@ -112,7 +114,7 @@ func LoggingHandler(logger *slog.Logger, level slog.Level) Handler {
 
 		case *ErrorData:
 			attrs = append(attrs,
-				slog.String("error", data.Error),
+				telemetry.SensitiveLog("error", data.Error),
 				slog.Bool("recoverable", data.Recoverable),
 			)
 			if data.Code != "" {
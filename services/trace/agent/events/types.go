@@ -66,6 +66,38 @@ const (
 
 	// TypeToolForcing is emitted when tool usage is being forced for an analytical query.
 	TypeToolForcing Type = "tool_forcing"
+
+	// TypePlanStatus is emitted when a change plan is created or its
+	// approval status changes.
+	TypePlanStatus Type = "plan_status"
+
+	// TypeEnsemble is emitted when the reflect phase reconciles the
+	// answers of an ensemble of main models.
+	TypeEnsemble Type = "ensemble"
+
+	// TypeCitationValidation is emitted when a final answer cites symbols
+	// that were not observed via tools during the run.
+	TypeCitationValidation Type = "citation_validation"
+
+	// TypeRoutingDecision is emitted when the execute phase has chosen a
+	// tool, with the pre-filter and router details behind the choice.
+	TypeRoutingDecision Type = "routing_decision"
+
+	// TypeClarifyOptions is emitted when the clarify phase pauses with
+	// options the user can select instead of typing an answer.
+	TypeClarifyOptions Type = "clarify_options"
+
+	// TypeRunAborted is emitted when Abort stops a session.
+	TypeRunAborted Type = "run_aborted"
+
+	// TypeContextReduction is emitted when a prompt was reduced to fit the
+	// model's context window, or could not be.
+	TypeContextReduction Type = "context_reduction"
+
+	// TypeFactsApplied is emitted when known facts about the project are
+	// added to a run's prompt, so answers can be audited for the facts
+	// that shaped them.
+	TypeFactsApplied Type = "facts_applied"
 )
 
 // Event represents an agent event.
@@ -99,7 +131,10 @@ type Event struct {
 	// Data contains event-specific data. Should be one of the typed
 	// data structs: StateTransitionData, ToolInvocationData, ToolResultData,
 	// ContextUpdateData, LLMRequestData, LLMResponseData, SafetyCheckData,
-	// ReflectionData, ErrorData, SessionStartData, SessionEndData, or StepCompleteData.
+	// ReflectionData, ErrorData, SessionStartData, SessionEndData,
+	// StepCompleteData, PlanStatusData, EnsembleData, CitationValidationData,
+	// RoutingDecisionData, ClarifyOptionsData, ContextReductionData, or
+	// FactsAppliedData.
 	Data any `json:"data,omitempty"`
 
 	// Metadata contains typed additional context for the event.
@@ -143,6 +178,10 @@ type ToolInvocationData struct {
 
 	// Parameters are the typed tool parameters.
 	Parameters *ToolInvocationParameters `json:"parameters,omitempty"`
+
+	// StepIndex is the invocation's position in its batch. Steps of a
+	// batch may run concurrently; StepIndex pairs their events.
+	StepIndex int `json:"step_index"`
 }
 
 // ToolResultData is the data for tool result events.
@@ -153,6 +192,9 @@ type ToolResultData struct {
 	// InvocationID links to the invocation.
 	InvocationID string `json:"invocation_id"`
 
+	// StepIndex is the invocation's position in its batch.
+	StepIndex int `json:"step_index"`
+
 	// Success indicates if the tool succeeded.
 	Success bool `json:"success"`
 
@@ -165,8 +207,19 @@ type ToolResultData struct {
 	// Cached indicates if the result was cached.
 	Cached bool `json:"cached,omitempty"`
 
+	// CacheGeneration is the graph generation a cached result was computed
+	// from, set when the result came from the shared tool result cache.
+	CacheGeneration string `json:"cache_generation,omitempty"`
+
 	// Error is set if the tool failed.
 	Error string `json:"error,omitempty"`
+
+	// Truncated indicates the output was cut to fit the result size cap.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// LimitHit names the execution limit the tool hit ("timeout",
+	// "output_size"), empty if none.
+	LimitHit string `json:"limit_hit,omitempty"`
 }
 
 // ContextUpdateData is the data for context update events.
@@ -182,6 +235,10 @@ type ContextUpdateData struct {
 
 	// TokensAfter is the token count after the update.
 	TokensAfter int `json:"tokens_after"`
+
+	// Assembly describes the included and dropped symbols for the
+	// "initial" action. Nil for other actions.
+	Assembly *agent.ContextAssembly `json:"assembly,omitempty"`
 }
 
 // LLMRequestData is the data for LLM request events.
@@ -223,6 +280,12 @@ type LLMResponseData struct {
 	// Model is the model that responded.
 	Model string `json:"model"`
 
+	// Provider is the provider that responded, when known.
+	Provider string `json:"provider,omitempty"`
+
+	// TokensIn is the input token count, when the provider reports it.
+	TokensIn int `json:"tokens_in,omitempty"`
+
 	// TokensOut is the output token count.
 	TokensOut int `json:"tokens_out"`
 
@@ -267,6 +330,85 @@ type SafetyCheckData struct {
 
 	// Blocked indicates if execution was blocked.
 	Blocked bool `json:"blocked"`
+
+	// Tool is the tool the safety policy evaluated, when the check came
+	// from a policy decision.
+	Tool string `json:"tool,omitempty"`
+
+	// Mode is the run mode whose policy rules applied.
+	Mode string `json:"mode,omitempty"`
+
+	// RuleID identifies the policy rule that denied the call.
+	RuleID string `json:"rule_id,omitempty"`
+
+	// Reason explains the policy decision.
+	Reason string `json:"reason,omitempty"`
+}
+
+// PlanStatusData is the data for plan status events.
+type PlanStatusData struct {
+	// PlanID identifies the change plan.
+	PlanID string `json:"plan_id"`
+
+	// SessionID is the agent session the plan belongs to, if any. The
+	// event's own SessionID is that of the emitter.
+	SessionID string `json:"session_id,omitempty"`
+
+	// FromStatus is the previous status; empty when the plan was created.
+	FromStatus string `json:"from_status,omitempty"`
+
+	// ToStatus is the new status: pending, approved, rejected, expired or
+	// applied.
+	ToStatus string `json:"to_status"`
+
+	// Comment is the reviewer's comment on approve or reject.
+	Comment string `json:"comment,omitempty"`
+
+	// Reason explains why the plan needs approval, or why it changed
+	// status without a reviewer.
+	Reason string `json:"reason,omitempty"`
+}
+
+// EnsembleData is the data for ensemble reconciliation events.
+type EnsembleData struct {
+	// Consensus is true when the chosen answer agrees with a majority of
+	// the other answers.
+	Consensus bool `json:"consensus"`
+
+	// Chosen is the "provider/model" whose answer was used. Empty when
+	// the models disagreed and all answers were returned.
+	Chosen string `json:"chosen,omitempty"`
+
+	// Disagreements names what the answers disagreed on:
+	// "cited_symbols", "recommendation", or both.
+	Disagreements []string `json:"disagreements,omitempty"`
+
+	// Candidates has one entry per ensemble member.
+	Candidates []EnsembleCandidateData `json:"candidates"`
+}
+
+// EnsembleCandidateData describes one ensemble member's answer.
+type EnsembleCandidateData struct {
+	// Provider is the member's provider (e.g. "anthropic").
+	Provider string `json:"provider"`
+
+	// Model is the member's model.
+	Model string `json:"model"`
+
+	// TokensIn is the input token count.
+	TokensIn int `json:"tokens_in"`
+
+	// TokensOut is the output token count.
+	TokensOut int `json:"tokens_out"`
+
+	// Duration is how long the member took to answer.
+	Duration time.Duration `json:"duration"`
+
+	// CitedSymbols is the number of symbols the answer cited.
+	CitedSymbols int `json:"cited_symbols"`
+
+	// Error is set when the member failed; its answer was not considered.
+	Error string `json:"error,omitempty"`
 }
 
 // ReflectionData is the data for reflection events.
@@ -391,3 +533,89 @@ type ToolForcingData struct {
 	// Reason explains why tool forcing was triggered.
 	Reason string `json:"reason,omitempty"`
 }
+
+// CitationValidationData is the data for citation validation events.
+type CitationValidationData struct {
+	// SessionID is the agent session whose answer was validated. The
+	// event's own SessionID is that of the emitter.
+	SessionID string `json:"session_id"`
+
+	// Verified is the number of distinct verified citations in the answer.
+	Verified int `json:"verified"`
+
+	// Unverified lists the markers that cite no observed symbol.
+	Unverified []agent.UnverifiedCitation `json:"unverified"`
+
+	// Citable is the size of the session's citation set.
+	Citable int `json:"citable"`
+
+	// Stripped is true if the unverified markers were removed from the answer.
+	Stripped bool `json:"stripped"`
+}
+
+// RoutingDecisionData is the data for routing decision events.
+type RoutingDecisionData struct {
+	// Decision explains the tool choice.
+	Decision agent.RoutingDecision `json:"decision"`
+}
+
+// RunAbortedData is the data for run aborted events.
+type RunAbortedData struct {
+	// SessionID is the aborted session.
+	SessionID string `json:"session_id"`
+
+	// Abort describes the abort: the interrupted and last completed
+	// phases, and whether Continue can resume the session.
+	Abort agent.AbortRecord `json:"abort"`
+}
+
+// ClarifyOptionsData is the data for clarify options events.
+type ClarifyOptionsData struct {
+	// Question is what the agent needs to know.
+	Question string `json:"question"`
+
+	// Options are the answers the user can select.
+	Options []agent.ClarificationOption `json:"options"`
+}
+
+// ContextReductionData is the data for context reduction events.
+type ContextReductionData struct {
+	// SessionID is the session whose prompt was reduced, when known.
+	SessionID string `json:"session_id,omitempty"`
+
+	// Provider is the provider of the model, when the reduction happened
+	// in the provider layer.
+	Provider string `json:"provider,omitempty"`
+
+	// Model is the model whose context window was exceeded.
+	Model string `json:"model"`
+
+	// LimitTokens is the model's context window.
+	LimitTokens int `json:"limit_tokens"`
+
+	// EstimatedTokens is the estimated prompt size after the reductions.
+	EstimatedTokens int `json:"estimated_tokens"`
+
+	// ReservedTokens is what the prompt had to leave free for the
+	// response.
+	ReservedTokens int `json:"reserved_tokens,omitempty"`
+
+	// Reductions are the steps taken, in order.
+	Reductions []agent.ContextReduction `json:"reductions,omitempty"`
+
+	// Overflow is true when the prompt still did not fit and the call
+	// failed with CONTEXT_OVERFLOW.
+	Overflow bool `json:"overflow"`
+}
+
+// FactsAppliedData is the data for facts applied events.
+type FactsAppliedData struct {
+	// FactIDs are the facts added to the prompt, best ranked first.
+	FactIDs []string `json:"fact_ids"`
+
+	// Statements are the facts' statements, in FactIDs order.
+	Statements []string `json:"statements"`
+
+	// Query is the query the facts were ranked for.
+	Query string `json:"query,omitempty"`
+}
@@ -17,6 +17,7 @@ import (
 	"strings"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/algorithms/constraints"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // TMSVerifierConfig configures the TMS verifier.
@@ -466,7 +467,7 @@ func (v *TMSVerifier) Check(ctx context.Context, input *CheckInput) []Violation
 	if err != nil {
 		// Log error for debugging but don't fail the check
 		slog.Warn("TMS verification failed",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.Int("claims_count", len(claims)),
 		)
 		return nil
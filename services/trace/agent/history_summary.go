@@ -0,0 +1,281 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// DefaultHistorySummaryFraction is the share of the context window the
+// conversation may fill before older turns are summarized.
+const DefaultHistorySummaryFraction = 0.75
+
+// historySummaryEntryType is the HistoryEntry type recorded when older
+// turns are replaced by a session summary.
+const historySummaryEntryType = "history_summarized"
+
+// Session summary message layout. The appendix headers are also used to
+// carry decisions forward when a summary is itself summarized.
+const (
+	historySummaryHeader    = "[Session summary]"
+	historySymbolsHeader    = "Resolved symbols (verbatim):"
+	historyDecisionsHeader  = "Decisions (verbatim):"
+	historyDecisionMaxBytes = 300
+	historyMaxSymbolIDs     = 200
+)
+
+// HistorySummarizer condenses older conversation turns of a multi-turn session.
+//
+// Description:
+//
+//	Implementations wrap the model that answers the session (or a cheaper
+//	one) and know its context window and tokenizer characteristics, so the
+//	loop can decide when to summarize without importing provider code.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type HistorySummarizer interface {
+	// EstimateTokens estimates the prompt tokens messages will occupy.
+	EstimateTokens(messages []Message) int
+
+	// ContextWindow returns the model's context window in tokens, or 0
+	// if unknown (which disables summarization).
+	ContextWindow() int
+
+	// Summarize returns a compact prose summary of messages and the
+	// tokens the summarization call consumed.
+	Summarize(ctx context.Context, messages []Message) (string, int, error)
+}
+
+// WithHistorySummarizer enables summarization of older conversation turns.
+//
+// Description:
+//
+//	Before each follow-up turn (Continue on a completed session), if the
+//	conversation exceeds HistorySummaryFraction of the summarizer's context
+//	window, all turns but the most recent are replaced by a single session
+//	summary message. Resolved symbol IDs and decisions are appended to it
+//	verbatim. Sessions opt out with DisableHistorySummarization.
+//
+// Inputs:
+//
+//	s - The summarizer. Nil disables summarization.
+//
+// Outputs:
+//
+//	DefaultLoopOption - The configuration function.
+func WithHistorySummarizer(s HistorySummarizer) DefaultLoopOption {
+	return func(l *DefaultAgentLoop) {
+		l.historySummarizer = s
+	}
+}
+
+// summarizeHistory replaces older turns of sessionCtx with a session summary
+// when the conversation is near the context window.
+//
+// Description:
+//
+//	A failed summarization is logged and leaves the history untouched; the
+//	follow-up still runs. On success a "history_summarized" history entry
+//	records the event for the session transcript.
+//
+// Inputs:
+//
+//	ctx - Context for the summarization call.
+//	session - The session being continued.
+//	sessionCtx - The session's current context. Modified in place.
+//
+// Thread Safety: Caller must hold the session (TryAcquire).
+func (l *DefaultAgentLoop) summarizeHistory(ctx context.Context, session *Session, sessionCtx *AssembledContext) {
+	s := l.historySummarizer
+	if s == nil || sessionCtx == nil || len(sessionCtx.ConversationHistory) < 2 {
+		return
+	}
+	fraction := DefaultHistorySummaryFraction
+	if session.Config != nil {
+		if session.Config.DisableHistorySummarization {
+			return
+		}
+		if session.Config.HistorySummaryFraction > 0 {
+			fraction = session.Config.HistorySummaryFraction
+		}
+	}
+	window := s.ContextWindow()
+	if window <= 0 {
+		return
+	}
+	history := sessionCtx.ConversationHistory
+	before := s.EstimateTokens(history)
+	if float64(before) <= fraction*float64(window) {
+		return
+	}
+
+	older, recent := splitRecentTurn(history)
+	start := time.Now()
+	summary, tokensUsed, err := s.Summarize(ctx, older)
+	if err != nil {
+		slog.WarnContext(ctx, "Conversation summarization failed, keeping full history",
+			slog.String("session_id", session.ID),
+			slog.Int("estimated_tokens", before),
+			telemetry.SensitiveLogError("error", err),
+		)
+		return
+	}
+
+	session.IncrementMetric(MetricLLMCalls, 1)
+	session.IncrementMetric(MetricTokens, tokensUsed)
+
+	msg := Message{
+		Role:    "user",
+		Content: formatHistorySummary(summary, sessionSymbolIDs(session), historyDecisions(older)),
+	}
+	sessionCtx.ConversationHistory = append([]Message{msg}, recent...)
+	after := s.EstimateTokens(sessionCtx.ConversationHistory)
+
+	session.AddHistoryEntry(HistoryEntry{
+		Type:       historySummaryEntryType,
+		Input:      fmt.Sprintf("summarized %d of %d messages (~%d -> ~%d tokens, window %d)", len(older), len(history), before, after, window),
+		Output:     msg.Content,
+		TokensUsed: tokensUsed,
+		DurationMs: time.Since(start).Milliseconds(),
+	})
+	slog.InfoContext(ctx, "Summarized older conversation turns",
+		slog.String("session_id", session.ID),
+		slog.Int("messages_summarized", len(older)),
+		slog.Int("tokens_before", before),
+		slog.Int("tokens_after", after),
+	)
+}
+
+// splitRecentTurn splits history before its last user message so the most
+// recent turn stays verbatim. A single-turn history is summarized whole.
+func splitRecentTurn(history []Message) (older, recent []Message) {
+	for i := len(history) - 1; i > 0; i-- {
+		if history[i].Role == "user" {
+			return history[:i], history[i:]
+		}
+	}
+	return history, nil
+}
+
+// sessionSymbolIDs returns the distinct symbol IDs resolved by the
+// session's tool calls, in discovery order.
+func sessionSymbolIDs(session *Session) []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, step := range session.GetTraceSteps() {
+		for _, id := range step.SymbolsFound {
+			if id == "" || seen[id] {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > historyMaxSymbolIDs {
+		ids = ids[len(ids)-historyMaxSymbolIDs:]
+	}
+	return ids
+}
+
+// historyDecisions extracts, per summarized turn, the question and the
+// first line of the final answer, and carries forward the decisions of
+// any earlier session summary.
+func historyDecisions(messages []Message) []string {
+	var decisions []string
+	question, answer := "", ""
+	flush := func() {
+		if question != "" && answer != "" {
+			decisions = append(decisions, "Q: "+question+" -> A: "+answer)
+		}
+		question, answer = "", ""
+	}
+	for _, msg := range messages {
+		switch msg.Role {
+		case "user":
+			if strings.HasPrefix(msg.Content, historySummaryHeader) {
+				decisions = append(decisions, parseHistoryDecisions(msg.Content)...)
+				continue
+			}
+			flush()
+			question = firstLine(msg.Content)
+		case "assistant":
+			if line := firstLine(msg.Content); line != "" && len(msg.ToolCalls) == 0 {
+				answer = line
+			}
+		}
+	}
+	flush()
+	return decisions
+}
+
+// parseHistoryDecisions reads the decisions appendix of a session summary.
+func parseHistoryDecisions(content string) []string {
+	_, section, ok := strings.Cut(content, historyDecisionsHeader)
+	if !ok {
+		return nil
+	}
+	var decisions []string
+	for _, line := range strings.Split(section, "\n") {
+		if d, ok := strings.CutPrefix(line, "- "); ok {
+			decisions = append(decisions, d)
+		}
+	}
+	return decisions
+}
+
+// firstLine returns the first non-empty line of s, capped at
+// historyDecisionMaxBytes.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if len(line) > historyDecisionMaxBytes {
+			cut := historyDecisionMaxBytes
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			line = line[:cut] + "..."
+		}
+		return line
+	}
+	return ""
+}
+
+// formatHistorySummary renders the session summary message.
+func formatHistorySummary(summary string, symbolIDs, decisions []string) string {
+	var b strings.Builder
+	b.WriteString(historySummaryHeader)
+	b.WriteString(" Earlier turns of this conversation, condensed:\n\n")
+	b.WriteString(strings.TrimSpace(summary))
+	b.WriteString("\n")
+	if len(symbolIDs) > 0 {
+		b.WriteString("\n" + historySymbolsHeader + "\n")
+		for _, id := range symbolIDs {
+			b.WriteString("- " + id + "\n")
+		}
+	}
+	if len(decisions) > 0 {
+		b.WriteString("\n" + historyDecisionsHeader + "\n")
+		for _, d := range decisions {
+			b.WriteString("- " + d + "\n")
+		}
+	}
+	return b.String()
+}
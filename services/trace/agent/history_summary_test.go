@@ -0,0 +1,175 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+// fakeHistorySummarizer counts one token per message content byte.
+type fakeHistorySummarizer struct {
+	window   int
+	err      error
+	calls    int
+	received []Message
+}
+
+func (f *fakeHistorySummarizer) EstimateTokens(messages []Message) int {
+	n := 0
+	for _, m := range messages {
+		n += len(m.Content)
+	}
+	return n
+}
+
+func (f *fakeHistorySummarizer) ContextWindow() int { return f.window }
+
+func (f *fakeHistorySummarizer) Summarize(_ context.Context, messages []Message) (string, int, error) {
+	f.calls++
+	f.received = messages
+	if f.err != nil {
+		return "", 0, f.err
+	}
+	return "User explored auth; JWT validation lives in pkg/auth.", 17, nil
+}
+
+func longHistory() []Message {
+	filler := strings.Repeat("x", 400)
+	return []Message{
+		{Role: "user", Content: "how does auth work?"},
+		{Role: "assistant", Content: "", ToolCalls: []ToolInvocation{{Tool: "find_symbol"}}},
+		{Role: "tool", Content: filler},
+		{Role: "assistant", Content: "Auth uses JWT via ValidateToken.\nDetails follow."},
+		{Role: "user", Content: "who calls ValidateToken?"},
+		{Role: "assistant", Content: "Middleware calls it."},
+	}
+}
+
+func TestSummarizeHistory(t *testing.T) {
+	newSession := func(t *testing.T, cfg *SessionConfig) (*Session, *AssembledContext) {
+		t.Helper()
+		session, err := NewSession("/proj", cfg)
+		if err != nil {
+			t.Fatalf("NewSession: %v", err)
+		}
+		session.RecordTraceStep(crs.TraceStep{Tool: "find_symbol", SymbolsFound: []string{"pkg/auth/jwt.go:10:ValidateToken"}})
+		ctx := &AssembledContext{ConversationHistory: longHistory()}
+		session.SetCurrentContext(ctx)
+		return session, ctx
+	}
+
+	t.Run("replaces older turns over the threshold", func(t *testing.T) {
+		s := &fakeHistorySummarizer{window: 500}
+		loop := NewDefaultAgentLoop(WithHistorySummarizer(s))
+		session, ctx := newSession(t, nil)
+
+		loop.summarizeHistory(context.Background(), session, ctx)
+
+		if s.calls != 1 || len(s.received) != 4 {
+			t.Fatalf("expected one call with the 4 older messages, got %d calls, %d messages", s.calls, len(s.received))
+		}
+		history := ctx.ConversationHistory
+		if len(history) != 3 {
+			t.Fatalf("expected summary + 2 recent messages, got %d", len(history))
+		}
+		summary := history[0].Content
+		for _, want := range []string{
+			historySummaryHeader,
+			"JWT validation lives in pkg/auth",
+			"- pkg/auth/jwt.go:10:ValidateToken",
+			"- Q: how does auth work? -> A: Auth uses JWT via ValidateToken.",
+		} {
+			if !strings.Contains(summary, want) {
+				t.Errorf("summary missing %q:\n%s", want, summary)
+			}
+		}
+		if history[1].Content != "who calls ValidateToken?" {
+			t.Errorf("recent turn not kept verbatim: %+v", history[1])
+		}
+
+		tr := BuildTranscript(session, nil)
+		if len(tr.Summarizations) != 1 || tr.Summarizations[0].TokensUsed != 17 {
+			t.Fatalf("expected summarization in transcript, got %+v", tr.Summarizations)
+		}
+		if session.GetMetrics().TotalTokens != 17 {
+			t.Errorf("TotalTokens = %d, want 17", session.GetMetrics().TotalTokens)
+		}
+	})
+
+	t.Run("carries decisions of an earlier summary forward", func(t *testing.T) {
+		s := &fakeHistorySummarizer{window: 500}
+		loop := NewDefaultAgentLoop(WithHistorySummarizer(s))
+		session, ctx := newSession(t, nil)
+		loop.summarizeHistory(context.Background(), session, ctx)
+
+		ctx.ConversationHistory = append(ctx.ConversationHistory,
+			Message{Role: "user", Content: strings.Repeat("y", 400)},
+			Message{Role: "assistant", Content: "Done."},
+		)
+		loop.summarizeHistory(context.Background(), session, ctx)
+
+		summary := ctx.ConversationHistory[0].Content
+		for _, want := range []string{
+			"- Q: how does auth work? -> A: Auth uses JWT via ValidateToken.",
+			"- Q: who calls ValidateToken? -> A: Middleware calls it.",
+		} {
+			if !strings.Contains(summary, want) {
+				t.Errorf("summary missing %q:\n%s", want, summary)
+			}
+		}
+	})
+
+	t.Run("under the threshold", func(t *testing.T) {
+		s := &fakeHistorySummarizer{window: 100000}
+		loop := NewDefaultAgentLoop(WithHistorySummarizer(s))
+		session, ctx := newSession(t, nil)
+
+		loop.summarizeHistory(context.Background(), session, ctx)
+
+		if s.calls != 0 || len(ctx.ConversationHistory) != 6 {
+			t.Errorf("expected no summarization, got %d calls", s.calls)
+		}
+	})
+
+	t.Run("disabled per session", func(t *testing.T) {
+		s := &fakeHistorySummarizer{window: 500}
+		loop := NewDefaultAgentLoop(WithHistorySummarizer(s))
+		cfg := DefaultSessionConfig()
+		cfg.DisableHistorySummarization = true
+		session, ctx := newSession(t, cfg)
+
+		loop.summarizeHistory(context.Background(), session, ctx)
+
+		if s.calls != 0 || len(ctx.ConversationHistory) != 6 {
+			t.Errorf("expected no summarization, got %d calls", s.calls)
+		}
+	})
+
+	t.Run("failure keeps history", func(t *testing.T) {
+		s := &fakeHistorySummarizer{window: 500, err: errors.New("model down")}
+		loop := NewDefaultAgentLoop(WithHistorySummarizer(s))
+		session, ctx := newSession(t, nil)
+
+		loop.summarizeHistory(context.Background(), session, ctx)
+
+		if len(ctx.ConversationHistory) != 6 {
+			t.Errorf("history modified after failure: %d messages", len(ctx.ConversationHistory))
+		}
+		if tr := BuildTranscript(session, nil); len(tr.Summarizations) != 0 {
+			t.Errorf("unexpected summarization record: %+v", tr.Summarizations)
+		}
+	})
+}
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	agenttypes "github.com/AleutianAI/AleutianFOSS/services/trace/agent/types"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 )
 
 // recordedRequest captures an HTTP request sent to a mock server.
@@ -554,6 +555,74 @@ func TestAllAdapters_Complete_Success(t *testing.T) {
 	}
 }
 
+func TestAllAdapters_Complete_TokenUsage(t *testing.T) {
+	providers := []struct {
+		name          string
+		createAdapter func(url string) Client
+		mockResponse  string
+	}{
+		{
+			"anthropic",
+			func(url string) Client {
+				return NewAnthropicAgentAdapter(NewAnthropicClientWithConfig("test-key", "claude-sonnet-4-20250514", url), "claude-sonnet-4-20250514")
+			},
+			anthropicMockResponse,
+		},
+		{
+			"openai",
+			func(url string) Client {
+				return NewOpenAIAgentAdapter(NewOpenAIClientWithConfig("test-key", "gpt-4o", url), "gpt-4o")
+			},
+			openaiMockResponse,
+		},
+		{
+			"gemini",
+			func(url string) Client {
+				return NewGeminiAgentAdapter(NewGeminiClientWithConfig("test-key", "gemini-1.5-flash", url), "gemini-1.5-flash")
+			},
+			geminiMockResponse,
+		},
+	}
+
+	for _, p := range providers {
+		t.Run(p.name, func(t *testing.T) {
+			server, _ := mockAnthropicServer(t, http.StatusOK, p.mockResponse)
+			defer server.Close()
+			adapter := p.createAdapter(server.URL)
+
+			// The tool path reads the usage the provider reports.
+			resp, err := adapter.Complete(context.Background(), &Request{
+				Messages: []Message{{Role: "user", Content: "Hello"}},
+				Tools: []tools.ToolDefinition{{
+					Name:        "explore_file",
+					Description: "Explore a file",
+					Parameters:  map[string]tools.ParamDef{"file_path": {Type: "string", Required: true}},
+				}},
+			})
+			if err != nil {
+				t.Fatalf("Complete() with tools error: %v", err)
+			}
+			if resp.InputTokens != 50 || resp.OutputTokens != 30 || resp.TokensUsed != 80 {
+				t.Errorf("tokens = %d in, %d out, %d total; want 50, 30, 80", resp.InputTokens, resp.OutputTokens, resp.TokensUsed)
+			}
+			if resp.TokensEstimated {
+				t.Error("TokensEstimated = true for reported usage")
+			}
+
+			// The plain chat path only has text, so counts are estimated.
+			resp, err = adapter.Complete(context.Background(), &Request{
+				Messages: []Message{{Role: "user", Content: "Hello"}},
+			})
+			if err != nil {
+				t.Fatalf("Complete() error: %v", err)
+			}
+			if !resp.TokensEstimated {
+				t.Error("TokensEstimated = false for estimated usage")
+			}
+		})
+	}
+}
+
 func TestAnthropicAdapter_Complete_ErrorResponses(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -104,6 +104,7 @@ func (a *AnthropicAgentAdapter) Complete(ctx context.Context, request *Request)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		recordLLMMetrics("anthropic", duration, 0, 0, err)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, err
 	}
 
@@ -116,6 +117,7 @@ func (a *AnthropicAgentAdapter) Complete(ctx context.Context, request *Request)
 		span.RecordError(emptyErr)
 		span.SetStatus(codes.Error, emptyErr.Error())
 		recordLLMMetrics("anthropic", duration, 0, 0, emptyErr)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, emptyErr
 	}
 
@@ -129,6 +131,7 @@ func (a *AnthropicAgentAdapter) Complete(ctx context.Context, request *Request)
 	))
 
 	recordLLMMetrics("anthropic", duration, inputTokens, outputTokens, nil)
+	setLLMSpanUsage(span, duration, inputTokens, outputTokens)
 
 	// Build CRS TraceStep
 	traceStep := crs.NewTraceStepBuilder().
@@ -143,14 +146,15 @@ func (a *AnthropicAgentAdapter) Complete(ctx context.Context, request *Request)
 		Build()
 
 	return &Response{
-		Content:      content,
-		StopReason:   "end",
-		TokensUsed:   inputTokens + outputTokens,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		Duration:     duration,
-		Model:        a.model,
-		TraceStep:    &traceStep,
+		Content:         content,
+		StopReason:      "end",
+		TokensUsed:      inputTokens + outputTokens,
+		InputTokens:     inputTokens,
+		OutputTokens:    outputTokens,
+		TokensEstimated: true,
+		Duration:        duration,
+		Model:           a.model,
+		TraceStep:       &traceStep,
 	}, nil
 }
 
@@ -259,6 +263,7 @@ func (a *AnthropicAgentAdapter) completeWithTools(ctx context.Context, request *
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		recordLLMMetrics("anthropic", duration, 0, 0, err)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, err
 	}
 
@@ -271,6 +276,7 @@ func (a *AnthropicAgentAdapter) completeWithTools(ctx context.Context, request *
 		span.RecordError(emptyErr)
 		span.SetStatus(codes.Error, emptyErr.Error())
 		recordLLMMetrics("anthropic", duration, 0, 0, emptyErr)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, emptyErr
 	}
 
@@ -284,8 +290,7 @@ func (a *AnthropicAgentAdapter) completeWithTools(ctx context.Context, request *
 		})
 	}
 
-	inputTokens := estimateInputTokensChat(chatMessages)
-	outputTokens := estimateTokens(result.Content)
+	inputTokens, outputTokens, estimated := resultTokens(result, chatMessages)
 
 	span.AddEvent("response_received", trace.WithAttributes(
 		attribute.Int("input_tokens", inputTokens),
@@ -295,6 +300,7 @@ func (a *AnthropicAgentAdapter) completeWithTools(ctx context.Context, request *
 	))
 
 	recordLLMMetrics("anthropic", duration, inputTokens, outputTokens, nil)
+	setLLMSpanUsage(span, duration, inputTokens, outputTokens)
 
 	// Build CRS TraceStep
 	traceStep := crs.NewTraceStepBuilder().
@@ -309,15 +315,16 @@ func (a *AnthropicAgentAdapter) completeWithTools(ctx context.Context, request *
 		Build()
 
 	return &Response{
-		Content:      result.Content,
-		ToolCalls:    agentToolCalls,
-		StopReason:   result.StopReason,
-		TokensUsed:   inputTokens + outputTokens,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		Duration:     duration,
-		Model:        a.model,
-		TraceStep:    &traceStep,
+		Content:         result.Content,
+		ToolCalls:       agentToolCalls,
+		StopReason:      result.StopReason,
+		TokensUsed:      inputTokens + outputTokens,
+		InputTokens:     inputTokens,
+		OutputTokens:    outputTokens,
+		TokensEstimated: estimated,
+		Duration:        duration,
+		Model:           a.model,
+		TraceStep:       &traceStep,
 	}, nil
 }
 
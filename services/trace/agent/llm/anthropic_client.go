@@ -24,6 +24,7 @@ import (
 	"time"
 
 	agenttypes "github.com/AleutianAI/AleutianFOSS/services/trace/agent/types"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 const (
@@ -151,6 +152,13 @@ type anthropicToolResponse struct {
 	Content    []json.RawMessage `json:"content"`
 	Error      *anthropicError   `json:"error,omitempty"`
 	StopReason string            `json:"stop_reason,omitempty"`
+	Usage      *anthropicUsage   `json:"usage,omitempty"`
+}
+
+// anthropicUsage is the token usage reported with a response.
+type anthropicUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
 }
 
 // anthropicContentBlock is used for parsing individual content blocks from response.
@@ -586,7 +594,7 @@ func (a *AnthropicClient) ChatWithTools(ctx context.Context, messages []ChatMess
 	for _, raw := range apiResp.Content {
 		var block anthropicContentBlock
 		if err := json.Unmarshal(raw, &block); err != nil {
-			slog.Warn("Failed to parse content block", "error", err)
+			slog.Warn("Failed to parse content block", telemetry.SensitiveLogError("error", err))
 			continue
 		}
 
@@ -607,6 +615,9 @@ func (a *AnthropicClient) ChatWithTools(ctx context.Context, messages []ChatMess
 	}
 
 	result.Content = strings.Join(textParts, "")
+	if apiResp.Usage != nil {
+		result.Usage = &TokenUsage{InputTokens: apiResp.Usage.InputTokens, OutputTokens: apiResp.Usage.OutputTokens}
+	}
 
 	if len(result.ToolCalls) > 0 {
 		result.StopReason = "tool_use"
@@ -948,7 +959,7 @@ func (a *AnthropicClient) handleSSEEvent(
 	case "content_block_delta":
 		var delta anthropicContentBlockDelta
 		if err := json.Unmarshal([]byte(data), &delta); err != nil {
-			slog.Warn("Failed to parse content_block_delta", "error", err, "data", data)
+			slog.Warn("Failed to parse content_block_delta", telemetry.SensitiveLogError("error", err), "data", data)
 			return nil // Don't fail on parse errors, continue stream
 		}
 
@@ -977,7 +988,7 @@ func (a *AnthropicClient) handleSSEEvent(
 	case "error":
 		var streamErr anthropicStreamError
 		if err := json.Unmarshal([]byte(data), &streamErr); err != nil {
-			slog.Warn("Failed to parse error event", "error", err, "data", data)
+			slog.Warn("Failed to parse error event", telemetry.SensitiveLogError("error", err), "data", data)
 			_ = callback(StreamEvent{Type: StreamEventError, Error: "stream error"})
 			return fmt.Errorf("stream error: %s", data)
 		}
@@ -124,6 +124,11 @@ type Request struct {
 	// Values: "-1" = infinite, "5m" = 5 minutes (default), "0" = unload immediately.
 	// Used to prevent model thrashing when alternating between models.
 	KeepAlive string `json:"keep_alive,omitempty"`
+
+	// CodeContext holds the entries BuildRequest formatted into the first
+	// message, so FitContextWindow can drop the least relevant ones. Not
+	// sent to the provider.
+	CodeContext []agent.CodeEntry `json:"-"`
 }
 
 // Message represents a conversation message.
@@ -217,12 +222,20 @@ type Response struct {
 	// OutputTokens is the output token count.
 	OutputTokens int `json:"output_tokens"`
 
+	// TokensEstimated is true when the token counts are estimated from
+	// the text rather than reported by the provider.
+	TokensEstimated bool `json:"tokens_estimated,omitempty"`
+
 	// Duration is how long the request took.
 	Duration time.Duration `json:"duration"`
 
 	// Model is the model that generated this response.
 	Model string `json:"model,omitempty"`
 
+	// Provider is the provider that generated this response. Set by
+	// clients that route between providers; empty means Client.Name().
+	Provider string `json:"provider,omitempty"`
+
 	// TraceStep is the CRS trace step recorded for this LLM call.
 	// Callers can use this to record the step in the CRS trace recorder.
 	// May be nil if CRS recording was not applicable.
@@ -316,6 +329,7 @@ func BuildRequest(
 		Tools:        availableTools,
 		MaxTokens:    maxTokens,
 		Temperature:  0.7,
+		CodeContext:  ctx.CodeContext,
 	}
 }
 
@@ -348,7 +362,9 @@ func formatCodeContext(entries []agent.CodeEntry) string {
 //
 // Description:
 //
-//	Parses native tool_calls from the LLM response structure.
+//	Parses native tool_calls from the LLM response structure. Plan
+//	markings in the arguments (_output, _depends_on, _group) are moved
+//	onto the invocations.
 //	For models that don't support native function calling, use
 //	ParseToolCallsWithReAct instead.
 //
@@ -368,15 +384,17 @@ func ParseToolCalls(response *Response) []agent.ToolInvocation {
 
 	invocations := make([]agent.ToolInvocation, 0, len(response.ToolCalls))
 	for _, call := range response.ToolCalls {
-		// Parse arguments JSON into ToolParameters
-		params := parseArguments(call.Arguments)
-
-		invocations = append(invocations, agent.ToolInvocation{
+		inv := agent.ToolInvocation{
 			ID:               call.ID,
 			Tool:             call.Name,
-			Parameters:       params,
 			ThoughtSignature: call.ThoughtSignature,
-		})
+		}
+
+		// Move plan markings onto the invocation, then parse the remaining
+		// arguments JSON into ToolParameters
+		inv.Parameters = parseArguments(extractPlanMarkings(call.Arguments, &inv))
+
+		invocations = append(invocations, inv)
 	}
 
 	return invocations
@@ -446,6 +464,78 @@ func ParseToolCallsWithReAct(response *Response) ([]agent.ToolInvocation, bool)
 	return []agent.ToolInvocation{invocation}, true
 }
 
+// Reserved tool call arguments the model uses to mark plan structure.
+// They are moved onto the invocation and never reach the tool.
+const (
+	planArgOutput    = "_output"
+	planArgDependsOn = "_depends_on"
+	planArgGroup     = "_group"
+)
+
+// extractPlanMarkings moves the reserved plan arguments onto inv.
+//
+// Description:
+//
+//	Sets inv.OutputVar, inv.DependsOn and inv.Group from the "_output",
+//	"_depends_on" (a string or a list of strings) and "_group" arguments
+//	and returns the arguments without them. Arguments without plan
+//	markings, or that are not a JSON object, are returned unchanged.
+//
+// Inputs:
+//
+//	argsJSON - Raw JSON string of arguments.
+//	inv - The invocation to update. Must not be nil.
+//
+// Outputs:
+//
+//	string - The arguments without the plan markings.
+func extractPlanMarkings(argsJSON string, inv *agent.ToolInvocation) string {
+	if !strings.Contains(argsJSON, `"_`) {
+		return argsJSON
+	}
+	var rawMap map[string]any
+	if err := json.Unmarshal([]byte(argsJSON), &rawMap); err != nil {
+		return argsJSON
+	}
+
+	found := false
+	if v, ok := rawMap[planArgOutput]; ok {
+		inv.OutputVar, _ = v.(string)
+		delete(rawMap, planArgOutput)
+		found = true
+	}
+	if v, ok := rawMap[planArgDependsOn]; ok {
+		switch deps := v.(type) {
+		case string:
+			if deps != "" {
+				inv.DependsOn = []string{deps}
+			}
+		case []any:
+			for _, d := range deps {
+				if s, ok := d.(string); ok && s != "" {
+					inv.DependsOn = append(inv.DependsOn, s)
+				}
+			}
+		}
+		delete(rawMap, planArgDependsOn)
+		found = true
+	}
+	if v, ok := rawMap[planArgGroup]; ok {
+		inv.Group, _ = v.(string)
+		delete(rawMap, planArgGroup)
+		found = true
+	}
+	if !found {
+		return argsJSON
+	}
+
+	stripped, err := json.Marshal(rawMap)
+	if err != nil {
+		return argsJSON
+	}
+	return string(stripped)
+}
+
 // parseArguments parses JSON arguments string into ToolParameters.
 //
 // Description:
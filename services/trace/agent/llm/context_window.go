@@ -0,0 +1,397 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+)
+
+const (
+	// historySummaryHeader starts the message that replaces condensed
+	// history.
+	historySummaryHeader = "[Earlier conversation condensed to fit the context window]"
+
+	// summaryLineChars is how much of each condensed message is kept.
+	summaryLineChars = 200
+
+	// summaryMaxLines caps the lines of a condensed history; older
+	// messages beyond it are only counted.
+	summaryMaxLines = 30
+)
+
+// ContextOverflowError is returned instead of sending a prompt that does
+// not fit the model's context window even after reduction.
+//
+// It wraps agent.ErrContextOverflow, so the agent loop reports the run as
+// CONTEXT_OVERFLOW.
+type ContextOverflowError struct {
+	// Model is the model whose window was exceeded.
+	Model string
+
+	// EstimatedTokens is the estimated prompt size after every reduction.
+	EstimatedTokens int
+
+	// ReservedTokens is what the prompt must leave free for the response.
+	ReservedTokens int
+
+	// LimitTokens is the model's context window.
+	LimitTokens int
+
+	// Reductions are the steps that were tried, in order.
+	Reductions []agent.ContextReduction
+}
+
+// Error implements error.
+func (e *ContextOverflowError) Error() string {
+	return fmt.Sprintf("prompt needs ~%d tokens plus %d for the response but %s has a %d-token context window",
+		e.EstimatedTokens, e.ReservedTokens, e.Model, e.LimitTokens)
+}
+
+// Unwrap returns agent.ErrContextOverflow.
+func (e *ContextOverflowError) Unwrap() error {
+	return agent.ErrContextOverflow
+}
+
+// Overflow returns the estimate and limit as an agent.ContextOverflow.
+func (e *ContextOverflowError) Overflow() agent.ContextOverflow {
+	return agent.ContextOverflow{
+		Model:           e.Model,
+		EstimatedTokens: e.EstimatedTokens,
+		ReservedTokens:  e.ReservedTokens,
+		LimitTokens:     e.LimitTokens,
+	}
+}
+
+// ContextWindow is the prompt budget of one model.
+type ContextWindow struct {
+	// Model names the model in errors.
+	Model string
+
+	// Tokens is the context window size. Zero or less disables Fit.
+	Tokens int
+
+	// CharsPerToken is the estimation ratio (see CharsPerToken). Zero
+	// uses the default of 4.
+	CharsPerToken float64
+}
+
+// ContextFit is the outcome of ContextWindow.Fit.
+type ContextFit struct {
+	// Request is the request to send: the original when it fit, otherwise
+	// a reduced copy. Nil when the prompt could not be made to fit.
+	Request *Request
+
+	// Reductions are the steps applied, in order. Empty when the request
+	// fit as it was.
+	Reductions []agent.ContextReduction
+
+	// EstimatedTokens is the estimated prompt size of Request.
+	EstimatedTokens int
+
+	// ReservedTokens is what the prompt must leave free for the response.
+	ReservedTokens int
+}
+
+// Fit reduces request until it fits the window.
+//
+// Description:
+//
+//	The prompt is estimated from the system prompt, messages and tool
+//	definitions, and must leave MaxTokens free for the response (an
+//	eighth of the window when unset, at most half of it).
+//	When it does not fit, the reductions below run in order, each only
+//	as far as needed, and stop as soon as the prompt fits:
+//
+//	  1. Drop code context blocks (Request.CodeContext), lowest relevance
+//	     first, then the oldest, then the latest in the message.
+//	  2. Condense the oldest messages after the query into one summary
+//	     message, keeping each line short; the most recent messages stay
+//	     verbatim and a tool result is never separated from its call.
+//
+//	The reductions are deterministic: the same request and window always
+//	give the same result.
+//
+// Inputs:
+//
+//	request - The request. Never modified.
+//
+// Outputs:
+//
+//	ContextFit - The request to send and the reductions applied.
+//	error - *ContextOverflowError if the prompt still does not fit; the
+//	  ContextFit then has the reductions that were tried.
+//
+// Thread Safety: Safe for concurrent use.
+func (w ContextWindow) Fit(request *Request) (ContextFit, error) {
+	if request == nil || w.Tokens <= 0 {
+		return ContextFit{Request: request}, nil
+	}
+	f := newContextFitter(w, request)
+	fit := ContextFit{Request: request, EstimatedTokens: f.estimate(request), ReservedTokens: f.reserve}
+	if f.fits(request) {
+		return fit, nil
+	}
+
+	fitted := *request
+	fitted.Messages = append([]Message(nil), request.Messages...)
+	if r, ok := f.dropContextBlocks(&fitted); ok {
+		fit.Reductions = append(fit.Reductions, r)
+	}
+	if !f.fits(&fitted) {
+		if r, ok := f.summarizeHistory(&fitted); ok {
+			fit.Reductions = append(fit.Reductions, r)
+		}
+	}
+	fit.EstimatedTokens = f.estimate(&fitted)
+	if !f.fits(&fitted) {
+		fit.Request = nil
+		return fit, &ContextOverflowError{
+			Model:           w.Model,
+			EstimatedTokens: fit.EstimatedTokens,
+			ReservedTokens:  fit.ReservedTokens,
+			LimitTokens:     w.Tokens,
+			Reductions:      fit.Reductions,
+		}
+	}
+	fit.Request = &fitted
+	return fit, nil
+}
+
+// EstimateRequestTokens estimates the prompt tokens of request.
+//
+// Inputs:
+//
+//	request - The request.
+//	charsPerToken - The estimation ratio (see CharsPerToken).
+//
+// Outputs:
+//
+//	int - The estimated prompt tokens, excluding the response.
+func EstimateRequestTokens(request *Request, charsPerToken float64) int {
+	if request == nil {
+		return 0
+	}
+	return newContextFitter(ContextWindow{CharsPerToken: charsPerToken}, request).estimate(request)
+}
+
+// contextFitter holds what stays fixed while a request is reduced.
+type contextFitter struct {
+	window        int
+	charsPerToken float64
+	toolChars     int
+	reserve       int
+}
+
+// newContextFitter prepares to fit request into w.
+func newContextFitter(w ContextWindow, request *Request) *contextFitter {
+	f := &contextFitter{window: w.Tokens, charsPerToken: w.CharsPerToken}
+	if f.charsPerToken <= 0 {
+		f.charsPerToken = defaultCharsPerToken
+	}
+	if len(request.Tools) > 0 {
+		if data, err := json.Marshal(request.Tools); err == nil {
+			f.toolChars = len(data)
+		}
+	}
+	f.reserve = request.MaxTokens
+	if f.reserve <= 0 {
+		f.reserve = w.Tokens / 8
+	}
+	if f.reserve > w.Tokens/2 {
+		f.reserve = w.Tokens / 2
+	}
+	return f
+}
+
+// estimate returns the estimated prompt tokens of request.
+func (f *contextFitter) estimate(request *Request) int {
+	chars := len(request.SystemPrompt) + f.toolChars
+	for _, msg := range request.Messages {
+		chars += len(msg.Content)
+		for _, call := range msg.ToolCalls {
+			chars += len(call.Name) + len(call.Arguments)
+		}
+		for _, result := range msg.ToolResults {
+			chars += len(result.Content)
+		}
+	}
+	return int(float64(chars)/f.charsPerToken) + (len(request.Messages)+1)*messageTokenOverhead
+}
+
+// fits reports whether request leaves the response reserve free.
+func (f *contextFitter) fits(request *Request) bool {
+	return f.estimate(request)+f.reserve <= f.window
+}
+
+// dropContextBlocks drops code context blocks until request fits.
+//
+// Only runs when the first message is still the one BuildRequest
+// formatted from request.CodeContext. Modifies request.
+func (f *contextFitter) dropContextBlocks(request *Request) (agent.ContextReduction, bool) {
+	if !hasCodeMessage(request) {
+		return agent.ContextReduction{}, false
+	}
+	entries := request.CodeContext
+
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ea, eb := entries[order[a]], entries[order[b]]
+		if ea.Relevance != eb.Relevance {
+			return ea.Relevance < eb.Relevance
+		}
+		if ea.AddedAt != eb.AddedAt {
+			return ea.AddedAt < eb.AddedAt
+		}
+		return order[a] > order[b]
+	})
+
+	reduction := agent.ContextReduction{
+		Action:       agent.ReductionDropContextBlocks,
+		TokensBefore: f.estimate(request),
+	}
+	dropped := make(map[int]bool, len(entries))
+	for _, i := range order {
+		dropped[i] = true
+		reduction.Items = append(reduction.Items, codeBlockName(entries[i]))
+
+		kept := make([]agent.CodeEntry, 0, len(entries)-len(dropped))
+		for j, entry := range entries {
+			if !dropped[j] {
+				kept = append(kept, entry)
+			}
+		}
+		request.CodeContext = kept
+		if len(kept) == 0 {
+			request.Messages = request.Messages[1:]
+		} else {
+			request.Messages[0] = Message{Role: "user", Content: formatCodeContext(kept)}
+		}
+		if f.fits(request) {
+			break
+		}
+	}
+	reduction.TokensAfter = f.estimate(request)
+	return reduction, true
+}
+
+// hasCodeMessage reports whether the first message of request is the one
+// BuildRequest formatted from request.CodeContext.
+func hasCodeMessage(request *Request) bool {
+	return len(request.CodeContext) > 0 && len(request.Messages) > 0 &&
+		request.Messages[0].Role == "user" && request.Messages[0].Content == formatCodeContext(request.CodeContext)
+}
+
+// codeBlockName names a code context block in a reduction record.
+func codeBlockName(entry agent.CodeEntry) string {
+	if entry.SymbolName == "" {
+		return entry.FilePath
+	}
+	return entry.FilePath + " (" + entry.SymbolName + ")"
+}
+
+// summarizeHistory condenses the oldest messages until request fits, or
+// as far as possible.
+//
+// The code context message and the first user message after it (the
+// query) are kept. The condensed run grows one message at a time from
+// the oldest; the kept tail never starts with a tool result, whose call
+// would otherwise be condensed away. Modifies request.
+func (f *contextFitter) summarizeHistory(request *Request) (agent.ContextReduction, bool) {
+	msgs := request.Messages
+	head := 0
+	if hasCodeMessage(request) {
+		head = 1
+	}
+	if head < len(msgs) && msgs[head].Role == "user" {
+		head++
+	}
+	if len(msgs)-head < 1 {
+		return agent.ContextReduction{}, false
+	}
+
+	before := f.estimate(request)
+	var best []Message
+	bestCut, bestTokens := 0, 0
+	for cut := head + 1; cut <= len(msgs); cut++ {
+		if cut < len(msgs) && msgs[cut].Role == "tool" {
+			continue
+		}
+		candidate := make([]Message, 0, head+1+len(msgs)-cut)
+		candidate = append(candidate, msgs[:head]...)
+		candidate = append(candidate, Message{Role: "user", Content: condenseMessages(msgs[head:cut])})
+		candidate = append(candidate, msgs[cut:]...)
+
+		trial := *request
+		trial.Messages = candidate
+		tokens := f.estimate(&trial)
+		if best == nil || tokens < bestTokens {
+			best, bestCut, bestTokens = candidate, cut, tokens
+		}
+		if f.fits(&trial) {
+			best, bestCut = candidate, cut
+			break
+		}
+	}
+	if best == nil {
+		return agent.ContextReduction{}, false
+	}
+
+	request.Messages = best
+	reduction := agent.ContextReduction{
+		Action:       agent.ReductionSummarizeHistory,
+		TokensBefore: before,
+		TokensAfter:  f.estimate(request),
+	}
+	for i := head; i < bestCut; i++ {
+		reduction.Items = append(reduction.Items, fmt.Sprintf("%s#%d", msgs[i].Role, i))
+	}
+	return reduction, true
+}
+
+// condenseMessages renders messages as one short line each. Only the
+// last summaryMaxLines messages get a line.
+func condenseMessages(messages []Message) string {
+	var sb strings.Builder
+	sb.WriteString(historySummaryHeader)
+	sb.WriteString("\n")
+	start := 0
+	if len(messages) > summaryMaxLines {
+		start = len(messages) - summaryMaxLines
+		fmt.Fprintf(&sb, "- (%d older messages omitted)\n", start)
+	}
+	for _, msg := range messages[start:] {
+		text := msg.Content
+		for _, result := range msg.ToolResults {
+			text += " " + result.Content
+		}
+		text = strings.Join(strings.Fields(text), " ")
+		text = truncate(text, summaryLineChars)
+		sb.WriteString("- ")
+		sb.WriteString(strings.ToUpper(msg.Role))
+		sb.WriteString(": ")
+		sb.WriteString(text)
+		for _, call := range msg.ToolCalls {
+			sb.WriteString(" [called ")
+			sb.WriteString(call.Name)
+			sb.WriteString("]")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
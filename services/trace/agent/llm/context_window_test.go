@@ -0,0 +1,126 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+)
+
+// overflowingRequest builds a request with three code blocks of 1000
+// chars and a history of long messages and tool results.
+func overflowingRequest() *Request {
+	return BuildRequest(&agent.AssembledContext{
+		SystemPrompt: "You analyze code.",
+		CodeContext: []agent.CodeEntry{
+			{ID: "a", FilePath: "a.go", SymbolName: "A", Content: strings.Repeat("a", 1000), Relevance: 0.9, AddedAt: 1},
+			{ID: "b", FilePath: "b.go", SymbolName: "B", Content: strings.Repeat("b", 1000), Relevance: 0.1, AddedAt: 2},
+			{ID: "c", FilePath: "c.go", Content: strings.Repeat("c", 1000), Relevance: 0.5, AddedAt: 1},
+		},
+		ConversationHistory: []agent.Message{
+			{Role: "user", Content: "Who calls Save?"},
+			{Role: "assistant", Content: strings.Repeat("thinking ", 250)},
+			{Role: "user", Content: strings.Repeat("more detail ", 150)},
+		},
+		ToolResults: []agent.ToolResult{
+			{InvocationID: "t1", Tool: "find_callers", Output: strings.Repeat("x", 1500), Success: true},
+			{InvocationID: "t2", Tool: "find_references", Output: strings.Repeat("y", 300), Success: true},
+		},
+	}, nil, 100)
+}
+
+func TestContextWindow_Fit(t *testing.T) {
+	request := overflowingRequest()
+	original := append([]Message(nil), request.Messages...)
+	full := EstimateRequestTokens(request, 1)
+
+	t.Run("fits unchanged", func(t *testing.T) {
+		fit, err := ContextWindow{Model: "m", Tokens: full + 100, CharsPerToken: 1}.Fit(request)
+		if err != nil || fit.Request != request || len(fit.Reductions) != 0 {
+			t.Errorf("Fit = %+v, %v; want the request unchanged", fit, err)
+		}
+	})
+
+	t.Run("drops least relevant block first", func(t *testing.T) {
+		fit, err := ContextWindow{Model: "m", Tokens: full + 100 - 900, CharsPerToken: 1}.Fit(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fit.Reductions) != 1 || fit.Reductions[0].Action != agent.ReductionDropContextBlocks ||
+			!reflect.DeepEqual(fit.Reductions[0].Items, []string{"b.go (B)"}) {
+			t.Fatalf("reductions = %+v, want b.go dropped", fit.Reductions)
+		}
+		code := fit.Request.Messages[0].Content
+		if strings.Contains(code, "bbb") || !strings.Contains(code, "aaa") || !strings.Contains(code, "ccc") {
+			t.Errorf("code context = %.80q..., want a and c only", code)
+		}
+		if fit.EstimatedTokens+fit.ReservedTokens > full+100-900 {
+			t.Errorf("estimate %d + %d exceeds the window", fit.EstimatedTokens, fit.ReservedTokens)
+		}
+		if !reflect.DeepEqual(request.Messages, original) || len(request.CodeContext) != 3 {
+			t.Error("Fit modified the request")
+		}
+	})
+
+	t.Run("then summarizes history", func(t *testing.T) {
+		window := full + 100 - 3000 - 1500
+		fit, err := ContextWindow{Model: "m", Tokens: window, CharsPerToken: 1}.Fit(request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var actions []string
+		for _, r := range fit.Reductions {
+			actions = append(actions, r.Action)
+		}
+		if !reflect.DeepEqual(actions, []string{agent.ReductionDropContextBlocks, agent.ReductionSummarizeHistory}) {
+			t.Fatalf("actions = %v", actions)
+		}
+		if got := fit.Reductions[0].Items; !reflect.DeepEqual(got, []string{"b.go (B)", "c.go", "a.go (A)"}) {
+			t.Errorf("dropped blocks = %v, want b, c, a", got)
+		}
+		msgs := fit.Request.Messages
+		if msgs[0].Content != "Who calls Save?" || !strings.HasPrefix(msgs[1].Content, historySummaryHeader) {
+			t.Fatalf("messages start %q, %.60q; want the query, then the summary", msgs[0].Content, msgs[1].Content)
+		}
+		if msgs[2].Role == "tool" {
+			t.Error("kept history starts with a tool result")
+		}
+		if last := msgs[len(msgs)-1]; last.Role != "tool" || last.ToolResults[0].ToolCallID != "t2" {
+			t.Errorf("last message = %+v, want the newest tool result verbatim", last)
+		}
+		if fit.EstimatedTokens+fit.ReservedTokens > window {
+			t.Errorf("estimate %d + %d exceeds window %d", fit.EstimatedTokens, fit.ReservedTokens, window)
+		}
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		huge := *request
+		huge.SystemPrompt = strings.Repeat("s", 5000)
+		fit, err := ContextWindow{Model: "small", Tokens: 1000, CharsPerToken: 1}.Fit(&huge)
+		var overflow *ContextOverflowError
+		if !errors.As(err, &overflow) || !errors.Is(err, agent.ErrContextOverflow) {
+			t.Fatalf("error = %v, want a ContextOverflowError", err)
+		}
+		if overflow.Model != "small" || overflow.LimitTokens != 1000 || overflow.EstimatedTokens < 5000 || overflow.ReservedTokens != 100 {
+			t.Errorf("overflow = %+v", overflow)
+		}
+		if len(overflow.Reductions) != 2 || fit.Request != nil {
+			t.Errorf("reductions = %+v, request = %v; want both steps tried and no request", overflow.Reductions, fit.Request)
+		}
+		if !strings.Contains(err.Error(), "1000-token context window") {
+			t.Errorf("error = %q", err)
+		}
+	})
+}
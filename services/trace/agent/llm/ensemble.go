@@ -0,0 +1,52 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import "context"
+
+// EnsembleClient is a Client backed by several providers.
+//
+// Description:
+//
+//	Complete answers from one member, so the agent loop behaves as with a
+//	single provider. CompleteAll sends the same request to every member
+//	concurrently, for callers that compare the answers.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type EnsembleClient interface {
+	Client
+
+	// CompleteAll sends request to every member concurrently.
+	//
+	// Inputs:
+	//   ctx - Context for cancellation and timeout
+	//   request - The completion request; each member answers with its own model
+	//
+	// Outputs:
+	//   []Candidate - One entry per member, in member order
+	//   error - Non-nil only if every member failed
+	CompleteAll(ctx context.Context, request *Request) ([]Candidate, error)
+}
+
+// Candidate is one ensemble member's answer.
+type Candidate struct {
+	// Provider is the member's provider name.
+	Provider string
+
+	// Model is the member's model.
+	Model string
+
+	// Response is the member's response. Nil when Err is set.
+	Response *Response
+
+	// Err is the member's error, if it failed.
+	Err error
+}
@@ -101,6 +101,7 @@ func (a *GeminiAgentAdapter) Complete(ctx context.Context, request *Request) (*R
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		recordLLMMetrics("gemini", duration, 0, 0, err)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, err
 	}
 
@@ -113,6 +114,7 @@ func (a *GeminiAgentAdapter) Complete(ctx context.Context, request *Request) (*R
 		span.RecordError(emptyErr)
 		span.SetStatus(codes.Error, emptyErr.Error())
 		recordLLMMetrics("gemini", duration, 0, 0, emptyErr)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, emptyErr
 	}
 
@@ -126,6 +128,7 @@ func (a *GeminiAgentAdapter) Complete(ctx context.Context, request *Request) (*R
 	))
 
 	recordLLMMetrics("gemini", duration, inputTokens, outputTokens, nil)
+	setLLMSpanUsage(span, duration, inputTokens, outputTokens)
 
 	// Build CRS TraceStep
 	traceStep := crs.NewTraceStepBuilder().
@@ -140,14 +143,15 @@ func (a *GeminiAgentAdapter) Complete(ctx context.Context, request *Request) (*R
 		Build()
 
 	return &Response{
-		Content:      content,
-		StopReason:   "end",
-		TokensUsed:   inputTokens + outputTokens,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		Duration:     duration,
-		Model:        a.model,
-		TraceStep:    &traceStep,
+		Content:         content,
+		StopReason:      "end",
+		TokensUsed:      inputTokens + outputTokens,
+		InputTokens:     inputTokens,
+		OutputTokens:    outputTokens,
+		TokensEstimated: true,
+		Duration:        duration,
+		Model:           a.model,
+		TraceStep:       &traceStep,
 	}, nil
 }
 
@@ -251,6 +255,7 @@ func (a *GeminiAgentAdapter) completeWithTools(ctx context.Context, request *Req
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		recordLLMMetrics("gemini", duration, 0, 0, err)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, err
 	}
 
@@ -263,6 +268,7 @@ func (a *GeminiAgentAdapter) completeWithTools(ctx context.Context, request *Req
 		span.RecordError(emptyErr)
 		span.SetStatus(codes.Error, emptyErr.Error())
 		recordLLMMetrics("gemini", duration, 0, 0, emptyErr)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, emptyErr
 	}
 
@@ -276,8 +282,7 @@ func (a *GeminiAgentAdapter) completeWithTools(ctx context.Context, request *Req
 		})
 	}
 
-	inputTokens := estimateInputTokensChat(chatMessages)
-	outputTokens := estimateTokens(result.Content)
+	inputTokens, outputTokens, estimated := resultTokens(result, chatMessages)
 
 	span.AddEvent("response_received", trace.WithAttributes(
 		attribute.Int("input_tokens", inputTokens),
@@ -287,6 +292,7 @@ func (a *GeminiAgentAdapter) completeWithTools(ctx context.Context, request *Req
 	))
 
 	recordLLMMetrics("gemini", duration, inputTokens, outputTokens, nil)
+	setLLMSpanUsage(span, duration, inputTokens, outputTokens)
 
 	// Build CRS TraceStep
 	traceStep := crs.NewTraceStepBuilder().
@@ -301,15 +307,16 @@ func (a *GeminiAgentAdapter) completeWithTools(ctx context.Context, request *Req
 		Build()
 
 	return &Response{
-		Content:      result.Content,
-		ToolCalls:    agentToolCalls,
-		StopReason:   result.StopReason,
-		TokensUsed:   inputTokens + outputTokens,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		Duration:     duration,
-		Model:        a.model,
-		TraceStep:    &traceStep,
+		Content:         result.Content,
+		ToolCalls:       agentToolCalls,
+		StopReason:      result.StopReason,
+		TokensUsed:      inputTokens + outputTokens,
+		InputTokens:     inputTokens,
+		OutputTokens:    outputTokens,
+		TokensEstimated: estimated,
+		Duration:        duration,
+		Model:           a.model,
+		TraceStep:       &traceStep,
 	}, nil
 }
 
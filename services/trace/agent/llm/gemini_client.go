@@ -486,6 +486,12 @@ func (g *GeminiClient) ChatWithTools(ctx context.Context, messages []ChatMessage
 
 	// Parse response parts
 	result := &ChatWithToolsResult{}
+	if apiResp.UsageMetadata != nil {
+		result.Usage = &TokenUsage{
+			InputTokens:  apiResp.UsageMetadata.PromptTokenCount,
+			OutputTokens: apiResp.UsageMetadata.CandidatesTokenCount,
+		}
+	}
 	var textParts []string
 	callIndex := 0
 
@@ -0,0 +1,185 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+)
+
+// historySummarySystemPrompt instructs the model to condense a conversation.
+const historySummarySystemPrompt = `You condense the earlier part of a code-analysis conversation so it can continue within a limited context window.
+Write a compact summary (at most a few short paragraphs) of what the user asked, what was found and what was concluded.
+Keep file paths, function and type names exactly as written. Do not speculate and do not answer any open question.`
+
+// Summary response size bounds, in tokens.
+const (
+	historySummaryMaxTokens    = 1024
+	historySummaryMinMaxTokens = 256
+)
+
+// messageTokenOverhead approximates the per-message framing tokens (role
+// markers, separators) that chat templates add.
+const messageTokenOverhead = 4
+
+// providerCharsPerToken approximates each provider's tokenizer density on
+// mixed code and prose. Unknown providers use defaultCharsPerToken.
+var providerCharsPerToken = map[string]float64{
+	"anthropic": 3.5,
+	"openai":    4.0,
+	"gemini":    4.0,
+	"ollama":    3.5,
+}
+
+// defaultCharsPerToken is the fallback density, matching the 4-chars-per-token
+// estimate used elsewhere.
+const defaultCharsPerToken = 4.0
+
+// HistorySummarizer summarizes agent conversation history with an LLM.
+//
+// Description:
+//
+//	Implements agent.HistorySummarizer. Token estimates use the density of
+//	the client's provider, so the same conversation counts as more tokens
+//	against an Ollama or Anthropic window than an OpenAI one.
+//
+// Thread Safety: Safe for concurrent use if the client is.
+type HistorySummarizer struct {
+	client        Client
+	contextWindow int
+	model         string
+	charsPerToken float64
+}
+
+// NewHistorySummarizer creates a summarizer.
+//
+// Inputs:
+//
+//	client - The client to summarize with, usually the Main role's.
+//	contextWindow - The Main model's context window in tokens (NumCtx).
+//	model - Optional cheaper model for the summary call (ModelOverride).
+//	        Empty uses the client's model.
+//
+// Outputs:
+//
+//	*HistorySummarizer - The summarizer.
+//	error - Non-nil if client is nil or contextWindow is not positive.
+func NewHistorySummarizer(client Client, contextWindow int, model string) (*HistorySummarizer, error) {
+	if client == nil {
+		return nil, errors.New("history summarizer: client must not be nil")
+	}
+	if contextWindow <= 0 {
+		return nil, fmt.Errorf("history summarizer: context window must be positive, got %d", contextWindow)
+	}
+	return &HistorySummarizer{
+		client:        client,
+		contextWindow: contextWindow,
+		model:         model,
+		charsPerToken: CharsPerToken(client.Name()),
+	}, nil
+}
+
+// CharsPerToken returns the approximate characters per token for a provider.
+//
+// Inputs:
+//
+//	provider - The provider name, as returned by Client.Name().
+//
+// Outputs:
+//
+//	float64 - Characters per token.
+func CharsPerToken(provider string) float64 {
+	if cpt, ok := providerCharsPerToken[strings.ToLower(provider)]; ok {
+		return cpt
+	}
+	return defaultCharsPerToken
+}
+
+// ContextWindow implements agent.HistorySummarizer.
+func (h *HistorySummarizer) ContextWindow() int {
+	return h.contextWindow
+}
+
+// EstimateTokens implements agent.HistorySummarizer.
+//
+// Description:
+//
+//	Counts message content and tool call arguments at the provider's
+//	density, plus a fixed framing overhead per message.
+func (h *HistorySummarizer) EstimateTokens(messages []agent.Message) int {
+	chars := 0
+	for _, msg := range messages {
+		chars += len(msg.Content)
+		for _, call := range msg.ToolCalls {
+			chars += len(call.Tool)
+			if call.Parameters != nil {
+				chars += len(call.Parameters.RawJSON)
+			}
+		}
+	}
+	return int(float64(chars)/h.charsPerToken) + len(messages)*messageTokenOverhead
+}
+
+// Summarize implements agent.HistorySummarizer.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	messages - The older turns to condense.
+//
+// Outputs:
+//
+//	string - The summary.
+//	int - Tokens consumed by the call.
+//	error - Non-nil if the call fails or returns no text.
+func (h *HistorySummarizer) Summarize(ctx context.Context, messages []agent.Message) (string, int, error) {
+	var b strings.Builder
+	b.WriteString("Conversation to summarize:\n\n")
+	for _, msg := range messages {
+		b.WriteString(strings.ToUpper(msg.Role))
+		b.WriteString(": ")
+		b.WriteString(msg.Content)
+		for _, call := range msg.ToolCalls {
+			b.WriteString("\n[called tool ")
+			b.WriteString(call.Tool)
+			b.WriteString("]")
+		}
+		b.WriteString("\n\n")
+	}
+
+	maxTokens := h.contextWindow / 8
+	if maxTokens > historySummaryMaxTokens {
+		maxTokens = historySummaryMaxTokens
+	}
+	if maxTokens < historySummaryMinMaxTokens {
+		maxTokens = historySummaryMinMaxTokens
+	}
+
+	resp, err := h.client.Complete(ctx, &Request{
+		SystemPrompt:  historySummarySystemPrompt,
+		Messages:      []Message{{Role: "user", Content: b.String()}},
+		MaxTokens:     maxTokens,
+		Temperature:   0.2,
+		ModelOverride: h.model,
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("summarizing history: %w", err)
+	}
+	summary := strings.TrimSpace(resp.Content)
+	if summary == "" {
+		return "", resp.TokensUsed, errors.New("summarizing history: empty summary")
+	}
+	return summary, resp.TokensUsed, nil
+}
@@ -0,0 +1,84 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+)
+
+func TestHistorySummarizer(t *testing.T) {
+	messages := []agent.Message{
+		{Role: "user", Content: strings.Repeat("a", 700)},
+		{Role: "assistant", Content: strings.Repeat("b", 700)},
+	}
+
+	t.Run("estimates per provider", func(t *testing.T) {
+		anthropic, err := NewHistorySummarizer(NewMockClient().WithName("anthropic"), 8192, "")
+		if err != nil {
+			t.Fatalf("NewHistorySummarizer: %v", err)
+		}
+		openai, _ := NewHistorySummarizer(NewMockClient().WithName("openai"), 8192, "")
+
+		if got := anthropic.EstimateTokens(messages); got != 408 {
+			t.Errorf("anthropic estimate = %d, want 408", got)
+		}
+		if got := openai.EstimateTokens(messages); got != 358 {
+			t.Errorf("openai estimate = %d, want 358", got)
+		}
+		if CharsPerToken("unknown") != defaultCharsPerToken {
+			t.Errorf("unknown provider should use the default density")
+		}
+	})
+
+	t.Run("summarizes with the override model", func(t *testing.T) {
+		client := NewMockClient().WithName("ollama")
+		client.QueueResponse(&Response{Content: "  condensed  ", TokensUsed: 90})
+		s, _ := NewHistorySummarizer(client, 4096, "small-model")
+
+		summary, tokens, err := s.Summarize(context.Background(), messages)
+		if err != nil {
+			t.Fatalf("Summarize: %v", err)
+		}
+		if summary != "condensed" || tokens != 90 {
+			t.Errorf("got %q, %d tokens", summary, tokens)
+		}
+		req := client.LastRequest()
+		if req.ModelOverride != "small-model" || req.MaxTokens != 512 {
+			t.Errorf("unexpected request: model %q, max tokens %d", req.ModelOverride, req.MaxTokens)
+		}
+		if !strings.Contains(req.Messages[0].Content, "USER: aaa") {
+			t.Errorf("conversation not included in prompt")
+		}
+	})
+
+	t.Run("empty summary is an error", func(t *testing.T) {
+		client := NewMockClient()
+		client.QueueResponse(&Response{Content: " "})
+		s, _ := NewHistorySummarizer(client, 4096, "")
+
+		if _, _, err := s.Summarize(context.Background(), messages); err == nil {
+			t.Error("expected error for empty summary")
+		}
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		if _, err := NewHistorySummarizer(nil, 4096, ""); err == nil {
+			t.Error("expected error for nil client")
+		}
+		if _, err := NewHistorySummarizer(NewMockClient(), 0, ""); err == nil {
+			t.Error("expected error for zero window")
+		}
+	})
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	agenttypes "github.com/AleutianAI/AleutianFOSS/services/trace/agent/types"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel/attribute"
 )
 
@@ -59,6 +60,7 @@ type MultiModelManager struct {
 	models     map[string]*ManagedModel
 	mu         sync.RWMutex
 	logger     *slog.Logger
+	resilience *Resilience
 }
 
 // ManagedModel tracks a model's lifecycle state.
@@ -142,8 +144,31 @@ func NewMultiModelManager(baseURL string) *MultiModelManager {
 		httpClient: &http.Client{
 			Timeout: 5 * time.Minute, // Long timeout for model loading
 		},
-		models: make(map[string]*ManagedModel),
-		logger: slog.Default(),
+		models:     make(map[string]*ManagedModel),
+		logger:     slog.Default(),
+		resilience: NewResilience(ResilienceConfigFromEnv()),
+	}
+}
+
+// Resilience returns the manager's timeout, retry and circuit breaker layer.
+//
+// # Description
+//
+// Chat and ChatWithTools run through it, with a circuit breaker per model.
+// Its breaker states back the readiness endpoint and the
+// ollama_circuit_state metric.
+//
+// # Thread Safety
+//
+// This method is safe for concurrent use.
+func (m *MultiModelManager) Resilience() *Resilience {
+	return m.resilience
+}
+
+// ping is the half-open probe for model.
+func (m *MultiModelManager) ping(model, keepAlive string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		return pingOllama(ctx, m.httpClient, m.baseURL, model, keepAlive)
 	}
 }
 
@@ -200,7 +225,7 @@ func (m *MultiModelManager) WarmModels(ctx context.Context, configs []ModelWarmu
 		if err := m.WarmModel(ctx, cfg.Model, cfg.KeepAlive, cfg.NumCtx); err != nil {
 			m.logger.Error("Failed to warm model",
 				slog.String("model", cfg.Model),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			// Store error but continue with other models
 			m.mu.Lock()
@@ -378,26 +403,9 @@ func (m *MultiModelManager) Chat(ctx context.Context, model string,
 		return "", fmt.Errorf("marshaling chat request: %w", err)
 	}
 
-	chatURL := m.baseURL + "/api/chat"
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", chatURL, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return "", fmt.Errorf("creating chat request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	resp, err := m.httpClient.Do(httpReq)
-	if err != nil {
-		return "", fmt.Errorf("sending chat request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := m.send(ctx, model, params.KeepAlive, reqBody)
 	if err != nil {
-		return "", fmt.Errorf("reading response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("chat failed with status %d: %s", resp.StatusCode, string(respBody))
+		return "", err
 	}
 
 	var chatResp ollamaChatResponse
@@ -478,26 +486,9 @@ func (m *MultiModelManager) ChatWithTools(ctx context.Context, model string,
 		return nil, fmt.Errorf("marshaling chat request: %w", err)
 	}
 
-	chatURL := m.baseURL + "/api/chat"
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", chatURL, bytes.NewBuffer(reqBody))
-	if err != nil {
-		return nil, fmt.Errorf("creating chat request: %w", err)
-	}
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	resp, err := m.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("sending chat request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	respBody, err := m.send(ctx, model, params.KeepAlive, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("chat failed with status %d: %s", resp.StatusCode, string(respBody))
+		return nil, err
 	}
 
 	var chatResp ollamaChatResponse
@@ -536,6 +527,37 @@ func (m *MultiModelManager) ChatWithTools(ctx context.Context, model string,
 	return result, nil
 }
 
+// send posts a chat request body for model through the resilience layer
+// and returns the response body.
+func (m *MultiModelManager) send(ctx context.Context, model, keepAlive string, reqBody []byte) ([]byte, error) {
+	chatURL := m.baseURL + "/api/chat"
+	var respBody []byte
+	err := m.resilience.Do(ctx, model, m.ping(model, keepAlive), func(callCtx context.Context) error {
+		httpReq, err := http.NewRequestWithContext(callCtx, "POST", chatURL, bytes.NewBuffer(reqBody))
+		if err != nil {
+			return fmt.Errorf("creating chat request: %w", err)
+		}
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := m.httpClient.Do(httpReq)
+		if err != nil {
+			return fmt.Errorf("sending chat request: %w", err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("reading response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return &StatusError{Op: "chat", StatusCode: resp.StatusCode, Body: string(body)}
+		}
+		respBody = body
+		return nil
+	})
+	return respBody, err
+}
+
 // GetLoadedModels returns currently tracked models.
 //
 // # Description
@@ -557,6 +579,63 @@ func (m *MultiModelManager) GetLoadedModels() []ManagedModel {
 	return models
 }
 
+// RunningModels returns the models Ollama currently holds in memory.
+//
+// # Description
+//
+// Queries GET /api/ps (the API behind `ollama ps`). Unlike GetLoadedModels,
+// this reflects Ollama's actual state, so it shows models that were
+// evicted after warming.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//
+// # Outputs
+//
+//   - []string: Names of the resident models (e.g. "granite4:micro-h").
+//   - error: Non-nil if the request fails or the response is malformed.
+func (m *MultiModelManager) RunningModels(ctx context.Context) ([]string, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", m.baseURL+"/api/ps", nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating ps request: %w", err)
+	}
+
+	resp, err := m.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("sending ps request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading ps response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama ps returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var ps struct {
+		Models []struct {
+			Name  string `json:"name"`
+			Model string `json:"model"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &ps); err != nil {
+		return nil, fmt.Errorf("parsing ps response: %w", err)
+	}
+
+	names := make([]string, 0, len(ps.Models))
+	for _, model := range ps.Models {
+		name := model.Name
+		if name == "" {
+			name = model.Model
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
 // UnloadModel explicitly unloads a model from VRAM.
 //
 // # Description
@@ -0,0 +1,47 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMultiModelManager_RunningModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/ps" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"models":[{"name":"gpt-oss:20b","model":"gpt-oss:20b"},{"model":"granite4:micro-h"}]}`))
+	}))
+	defer server.Close()
+
+	models, err := NewMultiModelManager(server.URL).RunningModels(context.Background())
+	if err != nil {
+		t.Fatalf("RunningModels() error = %v", err)
+	}
+	if len(models) != 2 || models[0] != "gpt-oss:20b" || models[1] != "granite4:micro-h" {
+		t.Errorf("RunningModels() = %v, want [gpt-oss:20b granite4:micro-h]", models)
+	}
+}
+
+func TestMultiModelManager_RunningModels_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if _, err := NewMultiModelManager(server.URL).RunningModels(context.Background()); err == nil {
+		t.Error("expected an error for a failed ps request")
+	}
+}
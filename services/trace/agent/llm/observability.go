@@ -16,6 +16,8 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // llmTracerName is the shared OTel tracer name for all agent LLM adapters.
@@ -189,6 +191,29 @@ func recordLLMMetrics(provider string, duration time.Duration, inputTokens, outp
 	}
 }
 
+// setLLMSpanUsage records token counts and latency on an LLM call span.
+//
+// Description:
+//
+//	Sets the attributes on the span itself, not an event, so traces can be
+//	filtered and aggregated by them. Token counts are zero on error.
+//
+// Inputs:
+//
+//	span - The LLM call span.
+//	duration - How long the call took.
+//	inputTokens - Estimated prompt token count.
+//	outputTokens - Estimated completion token count.
+//
+// Thread Safety: Safe for concurrent use.
+func setLLMSpanUsage(span trace.Span, duration time.Duration, inputTokens, outputTokens int) {
+	span.SetAttributes(
+		attribute.Int("llm.prompt_tokens", inputTokens),
+		attribute.Int("llm.completion_tokens", outputTokens),
+		attribute.Int64("llm.latency_ms", duration.Milliseconds()),
+	)
+}
+
 // incActiveRequests increments the active requests gauge for a provider.
 //
 // Inputs:
@@ -249,6 +249,14 @@ func TestComplete_SpanCreated_Anthropic(t *testing.T) {
 			if attrs["model"] != "claude-sonnet-4-20250514" {
 				t.Errorf("span model = %q, want %q", attrs["model"], "claude-sonnet-4-20250514")
 			}
+			for _, key := range []string{"llm.prompt_tokens", "llm.completion_tokens", "llm.latency_ms"} {
+				if _, ok := attrs[key]; !ok {
+					t.Errorf("span missing attribute %q", key)
+				}
+			}
+			if attrs["llm.completion_tokens"] == "0" {
+				t.Error("llm.completion_tokens should be set on success")
+			}
 		}
 	}
 	if !foundSpan {
@@ -39,8 +39,10 @@ import (
 //
 //	OllamaAdapter is safe for concurrent use.
 type OllamaAdapter struct {
-	client *OllamaClient
-	model  string
+	client    *OllamaClient
+	model     string
+	numCtx    int
+	keepAlive string
 }
 
 // NewOllamaAdapter creates a new OllamaAdapter.
@@ -69,6 +71,31 @@ func NewOllamaAdapter(client *OllamaClient, model string) *OllamaAdapter {
 	}
 }
 
+// WithModelOptions sets the context window and keep-alive sent with every
+// request.
+//
+// Description:
+//
+//	Ollama reloads a model whose num_ctx changes, so requests must use the
+//	same values the model was warmed with. Zero values keep the defaults
+//	(64K context, 24h keep-alive).
+//
+// Inputs:
+//
+//	numCtx - Context window size in tokens. Zero keeps the default.
+//	keepAlive - Model keep-alive duration (e.g. "24h"). Empty keeps the default.
+//
+// Outputs:
+//
+//	*OllamaAdapter - The adapter, for chaining.
+//
+// Thread Safety: Call before the adapter is shared.
+func (a *OllamaAdapter) WithModelOptions(numCtx int, keepAlive string) *OllamaAdapter {
+	a.numCtx = numCtx
+	a.keepAlive = keepAlive
+	return a
+}
+
 // Complete implements Client.
 //
 // Description:
@@ -160,11 +187,12 @@ func (a *OllamaAdapter) Complete(ctx context.Context, request *Request) (*Respon
 
 	if err != nil {
 		logger.Error("OllamaAdapter.Chat failed",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		recordLLMMetrics("ollama", duration, 0, 0, err)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, err
 	}
 
@@ -188,6 +216,7 @@ func (a *OllamaAdapter) Complete(ctx context.Context, request *Request) (*Respon
 		span.RecordError(emptyErr)
 		span.SetStatus(codes.Error, emptyErr.Error())
 		recordLLMMetrics("ollama", duration, 0, 0, emptyErr)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, emptyErr
 	}
 
@@ -201,6 +230,7 @@ func (a *OllamaAdapter) Complete(ctx context.Context, request *Request) (*Respon
 	))
 
 	recordLLMMetrics("ollama", duration, inputTokens, outputTokens, nil)
+	setLLMSpanUsage(span, duration, inputTokens, outputTokens)
 
 	// Build CRS TraceStep
 	traceStep := crs.NewTraceStepBuilder().
@@ -216,14 +246,15 @@ func (a *OllamaAdapter) Complete(ctx context.Context, request *Request) (*Respon
 
 	// Build response
 	return &Response{
-		Content:      content,
-		StopReason:   "end",
-		TokensUsed:   inputTokens + outputTokens,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		Duration:     duration,
-		Model:        a.model,
-		TraceStep:    &traceStep,
+		Content:         content,
+		StopReason:      "end",
+		TokensUsed:      inputTokens + outputTokens,
+		InputTokens:     inputTokens,
+		OutputTokens:    outputTokens,
+		TokensEstimated: true,
+		Duration:        duration,
+		Model:           a.model,
+		TraceStep:       &traceStep,
 	}, nil
 }
 
@@ -281,11 +312,12 @@ func (a *OllamaAdapter) completeWithTools(
 
 	if err != nil {
 		logger.Error("OllamaAdapter.ChatWithTools failed",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		recordLLMMetrics("ollama", duration, 0, 0, err)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, err
 	}
 
@@ -310,6 +342,7 @@ func (a *OllamaAdapter) completeWithTools(
 		span.RecordError(emptyErr)
 		span.SetStatus(codes.Error, emptyErr.Error())
 		recordLLMMetrics("ollama", duration, 0, 0, emptyErr)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, emptyErr
 	}
 
@@ -335,6 +368,7 @@ func (a *OllamaAdapter) completeWithTools(
 	))
 
 	recordLLMMetrics("ollama", duration, inputTokens, outputTokens, nil)
+	setLLMSpanUsage(span, duration, inputTokens, outputTokens)
 
 	// Build CRS TraceStep
 	traceStep := crs.NewTraceStepBuilder().
@@ -349,15 +383,16 @@ func (a *OllamaAdapter) completeWithTools(
 		Build()
 
 	return &Response{
-		Content:      result.Content,
-		ToolCalls:    agentToolCalls,
-		StopReason:   result.StopReason,
-		TokensUsed:   inputTokens + outputTokens,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		Duration:     duration,
-		Model:        a.model,
-		TraceStep:    &traceStep,
+		Content:         result.Content,
+		ToolCalls:       agentToolCalls,
+		StopReason:      result.StopReason,
+		TokensUsed:      inputTokens + outputTokens,
+		InputTokens:     inputTokens,
+		OutputTokens:    outputTokens,
+		TokensEstimated: true,
+		Duration:        duration,
+		Model:           a.model,
+		TraceStep:       &traceStep,
 	}, nil
 }
 
@@ -526,10 +561,16 @@ func (a *OllamaAdapter) buildParams(request *Request) GenerationParams {
 	// This MUST be passed on every request to prevent Ollama from
 	// resetting to default 4096 context window.
 	numCtx := 65536 // 64K tokens for main agent
+	if a.numCtx > 0 {
+		numCtx = a.numCtx
+	}
 	params.NumCtx = &numCtx
 
 	// Set keep_alive to prevent Ollama from unloading the model between requests.
 	// This is critical for multi-model operation to prevent thrashing.
+	if params.KeepAlive == "" {
+		params.KeepAlive = a.keepAlive
+	}
 	if params.KeepAlive == "" {
 		params.KeepAlive = "24h"
 	}
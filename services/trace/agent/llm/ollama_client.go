@@ -25,6 +25,7 @@ import (
 	"time"
 
 	agenttypes "github.com/AleutianAI/AleutianFOSS/services/trace/agent/types"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -39,6 +40,7 @@ type OllamaClient struct {
 	httpClient *http.Client
 	baseURL    string
 	model      string
+	resilience *Resilience
 }
 
 // Ollama API request structure
@@ -356,7 +358,7 @@ func initStreamMetrics() {
 			metric.WithDescription("Total tokens streamed from Ollama"),
 		)
 		if err != nil {
-			slog.Warn("Failed to create stream token counter", "error", err)
+			slog.Warn("Failed to create stream token counter", telemetry.SensitiveLogError("error", err))
 		}
 
 		streamDuration, err = meter.Float64Histogram(
@@ -364,7 +366,7 @@ func initStreamMetrics() {
 			metric.WithDescription("Duration of streaming requests"),
 		)
 		if err != nil {
-			slog.Warn("Failed to create stream duration histogram", "error", err)
+			slog.Warn("Failed to create stream duration histogram", telemetry.SensitiveLogError("error", err))
 		}
 
 		streamErrorCount, err = meter.Int64Counter(
@@ -372,7 +374,7 @@ func initStreamMetrics() {
 			metric.WithDescription("Total streaming errors"),
 		)
 		if err != nil {
-			slog.Warn("Failed to create stream error counter", "error", err)
+			slog.Warn("Failed to create stream error counter", telemetry.SensitiveLogError("error", err))
 		}
 	})
 }
@@ -393,9 +395,24 @@ func NewOllamaClient() (*OllamaClient, error) {
 		httpClient: &http.Client{Timeout: 5 * time.Minute},
 		baseURL:    baseURL,
 		model:      model,
+		resilience: NewResilience(ResilienceConfigFromEnv()),
 	}, nil
 }
 
+// Resilience returns the client's timeout, retry and circuit breaker layer.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (o *OllamaClient) Resilience() *Resilience {
+	return o.resilience
+}
+
+// ping is the half-open probe for model.
+func (o *OllamaClient) ping(model, keepAlive string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		return pingOllama(ctx, o.httpClient, o.baseURL, model, keepAlive)
+	}
+}
+
 // Generate implements the LLMClient interface
 func (o *OllamaClient) Generate(ctx context.Context, prompt string,
 	params GenerationParams) (string, error) {
@@ -447,51 +464,55 @@ func (o *OllamaClient) Generate(ctx context.Context, prompt string,
 		return "", fmt.Errorf("failed to marshal request to Ollama: %w", err)
 	}
 
-	// Use NewRequestWithContext to respect context cancellation/timeout
-	req, err := http.NewRequestWithContext(ctx, "POST", generateURL, bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return "", fmt.Errorf("failed to create request to Ollama: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
+	var respBodyBytes []byte
+	err = o.resilience.Do(ctx, o.model, o.ping(o.model, ""), func(callCtx context.Context) error {
+		// Use NewRequestWithContext to respect context cancellation/timeout
+		req, err := http.NewRequestWithContext(callCtx, "POST", generateURL, bytes.NewBuffer(reqBodyBytes))
+		if err != nil {
+			return fmt.Errorf("failed to create request to Ollama: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
 
-	resp, err := o.httpClient.Do(req)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		slog.Error("Ollama API call failed", "error", err)
-		return "", fmt.Errorf("Ollama API call failed: %w", err)
-	}
-	defer resp.Body.Close()
+		resp, err := o.httpClient.Do(req)
+		if err != nil {
+			slog.Error("Ollama API call failed", telemetry.SensitiveLogError("error", err))
+			return fmt.Errorf("Ollama API call failed: %w", err)
+		}
+		defer resp.Body.Close()
 
-	respBodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return "", fmt.Errorf("failed to read response body from Ollama: %w", err)
-	}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body from Ollama: %w", err)
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		if resp.StatusCode == http.StatusNotFound {
-			var errResp struct {
-				Error string `json:"error"`
-			}
-			if err := json.Unmarshal(respBodyBytes, &errResp); err == nil && strings.Contains(errResp.Error, "model") && strings.Contains(errResp.Error, "not found") {
-				slog.Warn("Ollama model not found", "model", o.model)
-				// Return a specific, user-friendly error
-				return "", fmt.Errorf("model '%s' not found. Please run: 'ollama pull %s'", o.model, o.model)
+		if resp.StatusCode != http.StatusOK {
+			if resp.StatusCode == http.StatusNotFound {
+				var errResp struct {
+					Error string `json:"error"`
+				}
+				if err := json.Unmarshal(body, &errResp); err == nil && strings.Contains(errResp.Error, "model") && strings.Contains(errResp.Error, "not found") {
+					slog.Warn("Ollama model not found", "model", o.model)
+					// Return a specific, user-friendly error
+					return fmt.Errorf("model '%s' not found. Please run: 'ollama pull %s'", o.model, o.model)
+				}
 			}
+			slog.Error("Ollama returned an error", "status_code", resp.StatusCode, "response", string(body))
+			return &StatusError{Op: "Ollama", StatusCode: resp.StatusCode, Body: string(body)}
 		}
-		slog.Error("Ollama returned an error", "status_code", resp.StatusCode, "response", string(respBodyBytes))
-		return "", fmt.Errorf("Ollama failed with status %d: %s", resp.StatusCode, string(respBodyBytes))
+		respBodyBytes = body
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return "", err
 	}
 
 	var ollamaResp ollamaGenerateResponse
 	if err := json.Unmarshal(respBodyBytes, &ollamaResp); err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		slog.Error("Failed to parse JSON response from Ollama", "error", err, "response", string(respBodyBytes))
+		slog.Error("Failed to parse JSON response from Ollama", telemetry.SensitiveLogError("error", err), "response", string(respBodyBytes))
 		return "", fmt.Errorf("failed to parse Ollama response: %w", err)
 	}
 
@@ -563,39 +584,40 @@ func (o *OllamaClient) Chat(ctx context.Context, messages []agenttypes.Message,
 		return "", fmt.Errorf("failed to marshal chat request to Ollama: %w", err)
 	}
 
-	// Use NewRequestWithContext to respect context cancellation/timeout
-	req, err := http.NewRequestWithContext(ctx, "POST", chatURL, bytes.NewBuffer(reqBody))
-	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
-		return "", fmt.Errorf("failed to create chat request to Ollama: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
+	var respBody []byte
+	err = o.resilience.Do(ctx, model, o.ping(model, params.KeepAlive), func(callCtx context.Context) error {
+		// Use NewRequestWithContext to respect context cancellation/timeout
+		req, err := http.NewRequestWithContext(callCtx, "POST", chatURL, bytes.NewBuffer(reqBody))
+		if err != nil {
+			return fmt.Errorf("failed to create chat request to Ollama: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
 
-	resp, err := o.httpClient.Do(req)
+		resp, err := o.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send the request to %s: %w", chatURL, err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			slog.Error("Ollama chat returned an error", "status_code", resp.StatusCode,
+				"response", string(body))
+			return &StatusError{Op: "ollama chat", StatusCode: resp.StatusCode, Body: string(body)}
+		}
+		respBody = body
+		return nil
+	})
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
-		return "", fmt.Errorf("failed to send the request to %s: %v", chatURL, err)
-	}
-	defer resp.Body.Close()
-	respBody, readErr := io.ReadAll(resp.Body)
-	if readErr != nil {
-		span.RecordError(readErr)
-		span.SetStatus(codes.Error, readErr.Error())
-		return "", fmt.Errorf("failed to read response body: %v", readErr)
-	}
-	if resp.StatusCode != http.StatusOK {
-		httpErr := fmt.Errorf("ollama chat failed with status %d: %s", resp.StatusCode, string(respBody))
-		slog.Error("Ollama chat returned an error", "status_code", resp.StatusCode,
-			"response", string(respBody))
-		span.RecordError(httpErr)
-		span.SetStatus(codes.Error, httpErr.Error())
-		return "", httpErr
+		return "", err
 	}
 	var ollamaResp ollamaChatResponse
 	if err = json.Unmarshal(respBody, &ollamaResp); err != nil {
-		slog.Error("Failed to parse JSON chat response from Ollama", "error", err,
+		slog.Error("Failed to parse JSON chat response from Ollama", telemetry.SensitiveLogError("error", err),
 			"response", string(respBody))
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
@@ -720,67 +742,66 @@ func (o *OllamaClient) ChatWithTools(ctx context.Context, messages []agenttypes.
 	var respBody []byte
 	var ollamaResp ollamaChatResponse
 
-	for attempt := 0; attempt <= maxToolParseRetries; attempt++ {
-		req, err := http.NewRequestWithContext(ctx, "POST", chatURL, bytes.NewBuffer(reqBody))
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-			return nil, fmt.Errorf("failed to create chat request to Ollama: %w", err)
-		}
-		req.Header.Set("Content-Type", "application/json")
-
-		resp, err := o.httpClient.Do(req)
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-			return nil, fmt.Errorf("failed to send request to %s: %v", chatURL, err)
-		}
-
-		respBody, err = io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
-			return nil, fmt.Errorf("failed to read response body: %v", err)
-		}
+	err = o.resilience.Do(ctx, model, o.ping(model, params.KeepAlive), func(callCtx context.Context) error {
+		for attempt := 0; attempt <= maxToolParseRetries; attempt++ {
+			req, err := http.NewRequestWithContext(callCtx, "POST", chatURL, bytes.NewBuffer(reqBody))
+			if err != nil {
+				return fmt.Errorf("failed to create chat request to Ollama: %w", err)
+			}
+			req.Header.Set("Content-Type", "application/json")
 
-		if resp.StatusCode != http.StatusOK {
-			respStr := string(respBody)
+			resp, err := o.httpClient.Do(req)
+			if err != nil {
+				return fmt.Errorf("failed to send request to %s: %w", chatURL, err)
+			}
 
-			// Retry on LLM tool call JSON parse errors (intermittent formatting issue)
-			if resp.StatusCode == http.StatusInternalServerError &&
-				strings.Contains(respStr, "error parsing tool call") &&
-				attempt < maxToolParseRetries {
+			respBody, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				return fmt.Errorf("failed to read response body: %w", err)
+			}
 
-				slog.Info("LLM produced malformed tool call JSON, retrying",
-					slog.Int("attempt", attempt+1),
-					slog.Int("max_retries", maxToolParseRetries),
-					slog.String("error", respStr),
+			if resp.StatusCode != http.StatusOK {
+				respStr := string(respBody)
+
+				// Retry on LLM tool call JSON parse errors (intermittent formatting issue)
+				if resp.StatusCode == http.StatusInternalServerError &&
+					strings.Contains(respStr, toolCallParseError) &&
+					attempt < maxToolParseRetries {
+
+					slog.Info("LLM produced malformed tool call JSON, retrying",
+						slog.Int("attempt", attempt+1),
+						slog.Int("max_retries", maxToolParseRetries),
+						telemetry.SensitiveLog("error", respStr),
+					)
+					span.AddEvent("tool_call_parse_retry", trace.WithAttributes(
+						attribute.Int("attempt", attempt+1),
+					))
+					time.Sleep(toolParseRetryDelay)
+					continue
+				}
+
+				slog.Error("Ollama chat returned an error",
+					"status_code", resp.StatusCode,
+					"response", respStr,
 				)
-				span.AddEvent("tool_call_parse_retry", trace.WithAttributes(
-					attribute.Int("attempt", attempt+1),
-				))
-				time.Sleep(toolParseRetryDelay)
-				continue
+				return &StatusError{Op: "ollama chat", StatusCode: resp.StatusCode, Body: respStr}
 			}
 
-			httpErr := fmt.Errorf("ollama chat failed with status %d: %s", resp.StatusCode, respStr)
-			slog.Error("Ollama chat returned an error",
-				"status_code", resp.StatusCode,
-				"response", respStr,
-			)
-			span.RecordError(httpErr)
-			span.SetStatus(codes.Error, httpErr.Error())
-			return nil, httpErr
+			// Success — break out of retry loop
+			break
 		}
-
-		// Success — break out of retry loop
-		break
+		return nil
+	})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
 	}
 
 	if err := json.Unmarshal(respBody, &ollamaResp); err != nil {
 		slog.Error("Failed to parse JSON chat response from Ollama",
-			"error", err,
+			telemetry.SensitiveLogError("error", err),
 			"response", string(respBody),
 		)
 		span.RecordError(err)
@@ -1656,7 +1677,7 @@ func (o *OllamaClient) readStreamResponse(ctx context.Context, body io.Reader,
 		// Parse chunk
 		chunk, err := o.parseStreamChunk(line)
 		if err != nil {
-			slog.Warn("Failed to parse stream chunk", "error", err)
+			slog.Warn("Failed to parse stream chunk", telemetry.SensitiveLogError("error", err))
 			continue
 		}
 
@@ -571,3 +571,21 @@ func TestOllamaAdapter_convertMessages_ToolResults(t *testing.T) {
 		}
 	})
 }
+
+func TestOllamaAdapter_buildParams_ModelOptions(t *testing.T) {
+	defaults := (&OllamaAdapter{model: "test-model"}).buildParams(&Request{})
+	if defaults.NumCtx == nil || *defaults.NumCtx != 65536 || defaults.KeepAlive != "24h" {
+		t.Errorf("defaults = num_ctx %v keep_alive %q, want 65536 and 24h", defaults.NumCtx, defaults.KeepAlive)
+	}
+
+	adapter := NewOllamaAdapter(nil, "test-model").WithModelOptions(8192, "1h")
+	params := adapter.buildParams(&Request{})
+	if params.NumCtx == nil || *params.NumCtx != 8192 || params.KeepAlive != "1h" {
+		t.Errorf("configured = num_ctx %v keep_alive %q, want 8192 and 1h", params.NumCtx, params.KeepAlive)
+	}
+
+	override := adapter.buildParams(&Request{KeepAlive: "5m"})
+	if override.KeepAlive != "5m" {
+		t.Errorf("request keep_alive = %q, want 5m", override.KeepAlive)
+	}
+}
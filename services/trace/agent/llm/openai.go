@@ -101,6 +101,7 @@ func (a *OpenAIAgentAdapter) Complete(ctx context.Context, request *Request) (*R
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		recordLLMMetrics("openai", duration, 0, 0, err)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, err
 	}
 
@@ -113,6 +114,7 @@ func (a *OpenAIAgentAdapter) Complete(ctx context.Context, request *Request) (*R
 		span.RecordError(emptyErr)
 		span.SetStatus(codes.Error, emptyErr.Error())
 		recordLLMMetrics("openai", duration, 0, 0, emptyErr)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, emptyErr
 	}
 
@@ -126,6 +128,7 @@ func (a *OpenAIAgentAdapter) Complete(ctx context.Context, request *Request) (*R
 	))
 
 	recordLLMMetrics("openai", duration, inputTokens, outputTokens, nil)
+	setLLMSpanUsage(span, duration, inputTokens, outputTokens)
 
 	// Build CRS TraceStep
 	traceStep := crs.NewTraceStepBuilder().
@@ -140,14 +143,15 @@ func (a *OpenAIAgentAdapter) Complete(ctx context.Context, request *Request) (*R
 		Build()
 
 	return &Response{
-		Content:      content,
-		StopReason:   "end",
-		TokensUsed:   inputTokens + outputTokens,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		Duration:     duration,
-		Model:        a.model,
-		TraceStep:    &traceStep,
+		Content:         content,
+		StopReason:      "end",
+		TokensUsed:      inputTokens + outputTokens,
+		InputTokens:     inputTokens,
+		OutputTokens:    outputTokens,
+		TokensEstimated: true,
+		Duration:        duration,
+		Model:           a.model,
+		TraceStep:       &traceStep,
 	}, nil
 }
 
@@ -256,6 +260,7 @@ func (a *OpenAIAgentAdapter) completeWithTools(ctx context.Context, request *Req
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
 		recordLLMMetrics("openai", duration, 0, 0, err)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, err
 	}
 
@@ -268,6 +273,7 @@ func (a *OpenAIAgentAdapter) completeWithTools(ctx context.Context, request *Req
 		span.RecordError(emptyErr)
 		span.SetStatus(codes.Error, emptyErr.Error())
 		recordLLMMetrics("openai", duration, 0, 0, emptyErr)
+		setLLMSpanUsage(span, duration, 0, 0)
 		return nil, emptyErr
 	}
 
@@ -281,8 +287,7 @@ func (a *OpenAIAgentAdapter) completeWithTools(ctx context.Context, request *Req
 		})
 	}
 
-	inputTokens := estimateInputTokensChat(chatMessages)
-	outputTokens := estimateTokens(result.Content)
+	inputTokens, outputTokens, estimated := resultTokens(result, chatMessages)
 
 	span.AddEvent("response_received", trace.WithAttributes(
 		attribute.Int("input_tokens", inputTokens),
@@ -292,6 +297,7 @@ func (a *OpenAIAgentAdapter) completeWithTools(ctx context.Context, request *Req
 	))
 
 	recordLLMMetrics("openai", duration, inputTokens, outputTokens, nil)
+	setLLMSpanUsage(span, duration, inputTokens, outputTokens)
 
 	// Build CRS TraceStep
 	traceStep := crs.NewTraceStepBuilder().
@@ -306,15 +312,16 @@ func (a *OpenAIAgentAdapter) completeWithTools(ctx context.Context, request *Req
 		Build()
 
 	return &Response{
-		Content:      result.Content,
-		ToolCalls:    agentToolCalls,
-		StopReason:   result.StopReason,
-		TokensUsed:   inputTokens + outputTokens,
-		InputTokens:  inputTokens,
-		OutputTokens: outputTokens,
-		Duration:     duration,
-		Model:        a.model,
-		TraceStep:    &traceStep,
+		Content:         result.Content,
+		ToolCalls:       agentToolCalls,
+		StopReason:      result.StopReason,
+		TokensUsed:      inputTokens + outputTokens,
+		InputTokens:     inputTokens,
+		OutputTokens:    outputTokens,
+		TokensEstimated: estimated,
+		Duration:        duration,
+		Model:           a.model,
+		TraceStep:       &traceStep,
 	}, nil
 }
 
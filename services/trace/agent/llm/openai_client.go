@@ -51,9 +51,16 @@ type openaiResponse struct {
 	ID      string         `json:"id"`
 	Object  string         `json:"object"`
 	Choices []openaiChoice `json:"choices"`
+	Usage   *openaiUsage   `json:"usage,omitempty"`
 	Error   *openaiError   `json:"error,omitempty"`
 }
 
+// openaiUsage is the token usage reported with a response.
+type openaiUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
 type openaiChoice struct {
 	Index        int           `json:"index"`
 	Message      openaiMessage `json:"message"`
@@ -450,6 +457,9 @@ func (o *OpenAIClient) ChatWithTools(ctx context.Context, messages []ChatMessage
 	result := &ChatWithToolsResult{
 		Content: choice.Message.Content,
 	}
+	if apiResp.Usage != nil {
+		result.Usage = &TokenUsage{InputTokens: apiResp.Usage.PromptTokens, OutputTokens: apiResp.Usage.CompletionTokens}
+	}
 
 	// Convert tool calls
 	for _, tc := range choice.Message.ToolCalls {
@@ -0,0 +1,580 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	agenttypes "github.com/AleutianAI/AleutianFOSS/services/trace/agent/types"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// =============================================================================
+// Ollama Call Resilience
+// =============================================================================
+
+// ErrProviderUnavailable is wrapped by errors from Ollama calls that failed
+// because the server is down, stalled or its circuit breaker is open. It
+// wraps agent.ErrLLMUnavailable, so the agent loop reports these runs as
+// PROVIDER_UNAVAILABLE.
+var ErrProviderUnavailable = fmt.Errorf("ollama provider unavailable: %w", agent.ErrLLMUnavailable)
+
+// errCallTimeout marks a call that hit the per-call timeout while the
+// caller's own context was still live.
+var errCallTimeout = errors.New("ollama call timed out")
+
+// toolCallParseError is the body fragment of Ollama's 500 for malformed tool
+// call JSON. It is a model formatting problem, not a server fault.
+const toolCallParseError = "error parsing tool call"
+
+// StatusError is a non-200 response from Ollama.
+type StatusError struct {
+	// Op is the failed operation, e.g. "ollama chat".
+	Op string
+
+	// StatusCode is the HTTP status code.
+	StatusCode int
+
+	// Body is the response body.
+	Body string
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s failed with status %d: %s", e.Op, e.StatusCode, e.Body)
+}
+
+// serverFault reports whether the status means the server itself is failing.
+func (e *StatusError) serverFault() bool {
+	return e.StatusCode >= 500 && !strings.Contains(e.Body, toolCallParseError)
+}
+
+// ProviderError is returned when an Ollama model is unavailable.
+//
+// Description:
+//
+//	Either the model's circuit breaker is open (Err is nil, or the failed
+//	half-open probe), or the call failed with a server fault after its
+//	retries. Matches ErrProviderUnavailable and agent.ErrLLMUnavailable
+//	with errors.Is, as well as the underlying error.
+type ProviderError struct {
+	// Model is the Ollama model the call was for.
+	Model string
+
+	// BreakerOpen is true if the call was rejected without being sent.
+	BreakerOpen bool
+
+	// Err is the underlying failure, if any.
+	Err error
+}
+
+// Error implements error.
+func (e *ProviderError) Error() string {
+	switch {
+	case e.BreakerOpen && e.Err != nil:
+		return fmt.Sprintf("ollama model %q unavailable: circuit breaker open: %v", e.Model, e.Err)
+	case e.BreakerOpen:
+		return fmt.Sprintf("ollama model %q unavailable: circuit breaker open", e.Model)
+	default:
+		return fmt.Sprintf("ollama model %q unavailable: %v", e.Model, e.Err)
+	}
+}
+
+// Unwrap returns ErrProviderUnavailable and the underlying error.
+func (e *ProviderError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{ErrProviderUnavailable}
+	}
+	return []error{ErrProviderUnavailable, e.Err}
+}
+
+// BreakerState is the state of a model's circuit breaker.
+type BreakerState int
+
+const (
+	// BreakerClosed allows calls through.
+	BreakerClosed BreakerState = iota
+
+	// BreakerOpen rejects calls without sending them.
+	BreakerOpen
+
+	// BreakerHalfOpen means a probe is checking whether the model recovered.
+	// Other calls are rejected until it finishes.
+	BreakerHalfOpen
+)
+
+// String returns the state name.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ResilienceConfig configures timeouts, retries and circuit breaking for
+// Ollama calls.
+type ResilienceConfig struct {
+	// CallTimeout bounds a single attempt, independent of the caller's
+	// (run) deadline. Zero disables it.
+	CallTimeout time.Duration
+
+	// ProbeTimeout bounds a half-open probe. Probes may have to reload an
+	// evicted model, so this is longer than a plain ping needs.
+	ProbeTimeout time.Duration
+
+	// MaxRetries is how many times a call is retried after a refused
+	// connection or 5xx response.
+	MaxRetries int
+
+	// RetryBaseDelay is the backoff before the first retry. It doubles on
+	// each further retry, with jitter.
+	RetryBaseDelay time.Duration
+
+	// FailureThreshold is how many consecutive failed calls open a model's
+	// circuit breaker.
+	FailureThreshold int
+
+	// OpenDuration is how long a breaker stays open before a probe.
+	OpenDuration time.Duration
+}
+
+// DefaultResilienceConfig returns the default Ollama resilience settings.
+//
+// Outputs:
+//
+//	ResilienceConfig - 3m call timeout, 60s probe timeout, 2 retries from
+//	500ms, and a breaker that opens after 5 failures for 30s.
+func DefaultResilienceConfig() ResilienceConfig {
+	return ResilienceConfig{
+		CallTimeout:      3 * time.Minute,
+		ProbeTimeout:     60 * time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   500 * time.Millisecond,
+		FailureThreshold: 5,
+		OpenDuration:     30 * time.Second,
+	}
+}
+
+// ResilienceConfigFromEnv returns DefaultResilienceConfig with environment
+// overrides applied.
+//
+// Description:
+//
+//	Reads OLLAMA_CALL_TIMEOUT, OLLAMA_PROBE_TIMEOUT and
+//	OLLAMA_BREAKER_COOLDOWN as durations ("90s"), and OLLAMA_MAX_RETRIES
+//	and OLLAMA_BREAKER_THRESHOLD as integers. Invalid values are logged
+//	and ignored.
+//
+// Outputs:
+//
+//	ResilienceConfig - The resulting configuration.
+func ResilienceConfigFromEnv() ResilienceConfig {
+	cfg := DefaultResilienceConfig()
+	envDuration("OLLAMA_CALL_TIMEOUT", &cfg.CallTimeout)
+	envDuration("OLLAMA_PROBE_TIMEOUT", &cfg.ProbeTimeout)
+	envDuration("OLLAMA_BREAKER_COOLDOWN", &cfg.OpenDuration)
+	envInt("OLLAMA_MAX_RETRIES", &cfg.MaxRetries)
+	envInt("OLLAMA_BREAKER_THRESHOLD", &cfg.FailureThreshold)
+	return cfg
+}
+
+// envDuration sets *dst from the named variable if it holds a valid,
+// non-negative duration.
+func envDuration(name string, dst *time.Duration) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d < 0 {
+		slog.Warn("Ignoring invalid duration", slog.String("env", name), slog.String("value", raw))
+		return
+	}
+	*dst = d
+}
+
+// envInt sets *dst from the named variable if it holds a valid,
+// non-negative integer.
+func envInt(name string, dst *int) {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 0 {
+		slog.Warn("Ignoring invalid integer", slog.String("env", name), slog.String("value", raw))
+		return
+	}
+	*dst = n
+}
+
+// modelBreaker is the circuit breaker state for one model.
+type modelBreaker struct {
+	state    BreakerState
+	failures int
+	openedAt time.Time
+}
+
+// Resilience applies per-call timeouts, retries and per-model circuit
+// breakers to Ollama calls.
+//
+// Description:
+//
+//	Each call attempt gets its own timeout. Refused connections and 5xx
+//	responses are retried with jittered exponential backoff; timeouts are
+//	not, since a stalled server would only stall again. Calls ended by the
+//	caller's context are returned as-is and never count as failures.
+//
+//	A call that still fails with a server fault counts once toward its
+//	model's breaker. After FailureThreshold consecutive failures the
+//	breaker opens and calls fail immediately with a ProviderError. Once
+//	OpenDuration has passed, the next call sends a cheap ping probe
+//	(half-open); success closes the breaker, failure reopens it.
+//
+// Thread Safety: Safe for concurrent use.
+type Resilience struct {
+	cfg      ResilienceConfig
+	mu       sync.Mutex
+	breakers map[string]*modelBreaker
+	now      func() time.Time
+}
+
+// NewResilience creates a resilience layer.
+//
+// Inputs:
+//
+//	cfg - The configuration. A FailureThreshold below 1 is treated as 1.
+//
+// Outputs:
+//
+//	*Resilience - The layer, with every breaker closed.
+func NewResilience(cfg ResilienceConfig) *Resilience {
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = 1
+	}
+	return &Resilience{
+		cfg:      cfg,
+		breakers: make(map[string]*modelBreaker),
+		now:      time.Now,
+	}
+}
+
+// Config returns the layer's configuration.
+func (r *Resilience) Config() ResilienceConfig {
+	return r.cfg
+}
+
+// Do runs call for model under the layer's timeout, retry and breaker
+// policy.
+//
+// Inputs:
+//
+//	ctx - The caller's context. Its cancellation stops retries at once.
+//	model - The Ollama model, which selects the breaker.
+//	probe - Cheap request used as the half-open probe.
+//	call - Sends the request once, honouring the context it is given.
+//
+// Outputs:
+//
+//	error - nil on success; a ProviderError if the breaker is open or the
+//	call failed with a server fault; otherwise call's error.
+//
+// Thread Safety: Safe for concurrent use.
+func (r *Resilience) Do(ctx context.Context, model string, probe, call func(context.Context) error) error {
+	if err := r.admit(ctx, model, probe); err != nil {
+		return err
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = r.attempt(ctx, call)
+		if err == nil {
+			r.recordSuccess(model)
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		if !isServerFault(err) {
+			// The server answered; the request itself was bad.
+			r.recordSuccess(model)
+			return err
+		}
+		if attempt >= r.cfg.MaxRetries || !isRetryable(err) {
+			break
+		}
+
+		backoff := r.cfg.RetryBaseDelay << attempt
+		wait := backoff/2 + rand.N(backoff/2+1)
+		slog.WarnContext(ctx, "Retrying Ollama call",
+			slog.String("model", model),
+			slog.Int("attempt", attempt+1),
+			slog.Duration("backoff", wait),
+			telemetry.SensitiveLogError("error", err))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+
+	r.recordFailure(model)
+	return &ProviderError{Model: model, Err: err}
+}
+
+// attempt runs call once under the per-call timeout.
+func (r *Resilience) attempt(ctx context.Context, call func(context.Context) error) error {
+	if r.cfg.CallTimeout <= 0 {
+		return call(ctx)
+	}
+	callCtx, cancel := context.WithTimeout(ctx, r.cfg.CallTimeout)
+	defer cancel()
+	err := call(callCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w after %s: %w", errCallTimeout, r.cfg.CallTimeout, err)
+	}
+	return err
+}
+
+// admit checks model's breaker, running the half-open probe if the breaker
+// is due for one.
+func (r *Resilience) admit(ctx context.Context, model string, probe func(context.Context) error) error {
+	r.mu.Lock()
+	b := r.breaker(model)
+	switch b.state {
+	case BreakerClosed:
+		r.mu.Unlock()
+		return nil
+	case BreakerOpen:
+		if r.now().Sub(b.openedAt) < r.cfg.OpenDuration {
+			r.mu.Unlock()
+			return &ProviderError{Model: model, BreakerOpen: true}
+		}
+		b.state = BreakerHalfOpen
+		r.mu.Unlock()
+	default:
+		r.mu.Unlock()
+		return &ProviderError{Model: model, BreakerOpen: true}
+	}
+
+	probeCtx := ctx
+	if r.cfg.ProbeTimeout > 0 {
+		var cancel context.CancelFunc
+		probeCtx, cancel = context.WithTimeout(ctx, r.cfg.ProbeTimeout)
+		defer cancel()
+	}
+	err := probe(probeCtx)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		b.state = BreakerOpen
+		if ctx.Err() != nil {
+			// The caller gave up; leave the probe to the next call.
+			return ctx.Err()
+		}
+		b.openedAt = r.now()
+		slog.WarnContext(ctx, "Ollama circuit breaker probe failed",
+			slog.String("model", model), telemetry.SensitiveLogError("error", err))
+		return &ProviderError{Model: model, BreakerOpen: true, Err: fmt.Errorf("probe failed: %w", err)}
+	}
+	b.state = BreakerClosed
+	b.failures = 0
+	slog.InfoContext(ctx, "Ollama circuit breaker closed", slog.String("model", model))
+	return nil
+}
+
+// recordSuccess resets model's consecutive failure count.
+func (r *Resilience) recordSuccess(model string) {
+	r.mu.Lock()
+	r.breaker(model).failures = 0
+	r.mu.Unlock()
+}
+
+// recordFailure counts a failed call, opening the breaker at the threshold.
+func (r *Resilience) recordFailure(model string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b := r.breaker(model)
+	b.failures++
+	if b.state == BreakerClosed && b.failures >= r.cfg.FailureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = r.now()
+		slog.Warn("Ollama circuit breaker opened",
+			slog.String("model", model),
+			slog.Int("consecutive_failures", b.failures),
+			slog.Duration("open_duration", r.cfg.OpenDuration))
+	}
+}
+
+// breaker returns model's breaker, creating it closed. Callers hold r.mu.
+func (r *Resilience) breaker(model string) *modelBreaker {
+	b, ok := r.breakers[model]
+	if !ok {
+		b = &modelBreaker{}
+		r.breakers[model] = b
+	}
+	return b
+}
+
+// State returns model's breaker state. Models never called are closed.
+//
+// Thread Safety: Safe for concurrent use.
+func (r *Resilience) State(model string) BreakerState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if b, ok := r.breakers[model]; ok {
+		return b.state
+	}
+	return BreakerClosed
+}
+
+// BreakerStates returns the breaker state of every model called so far,
+// keyed by model name.
+//
+// Thread Safety: Safe for concurrent use.
+func (r *Resilience) BreakerStates() map[string]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	states := make(map[string]string, len(r.breakers))
+	for model, b := range r.breakers {
+		states[model] = b.state.String()
+	}
+	return states
+}
+
+// RegisterMetrics registers an ollama_circuit_state gauge reporting each
+// model's breaker state (0=closed, 1=open, 2=half-open).
+//
+// Inputs:
+//
+//	meter - The OTel meter to register with.
+//
+// Outputs:
+//
+//	metric.Registration - Registration handle for cleanup.
+//	error - Non-nil if registration fails.
+func (r *Resilience) RegisterMetrics(meter metric.Meter) (metric.Registration, error) {
+	gauge, err := meter.Int64ObservableGauge(
+		"ollama_circuit_state",
+		metric.WithDescription("Ollama per-model circuit breaker state (0=closed, 1=open, 2=half-open)"),
+		metric.WithUnit("{state}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("create ollama_circuit_state: %w", err)
+	}
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		r.mu.Lock()
+		models := make([]string, 0, len(r.breakers))
+		for model := range r.breakers {
+			models = append(models, model)
+		}
+		sort.Strings(models)
+		states := make([]BreakerState, len(models))
+		for i, model := range models {
+			states[i] = r.breakers[model].state
+		}
+		r.mu.Unlock()
+
+		for i, model := range models {
+			o.ObserveInt64(gauge, int64(states[i]), metric.WithAttributes(attribute.String("model", model)))
+		}
+		return nil
+	}, gauge)
+}
+
+// isServerFault reports whether err means the Ollama server is down,
+// stalled or failing, as opposed to rejecting the request.
+func isServerFault(err error) bool {
+	if errors.Is(err, errCallTimeout) {
+		return true
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.serverFault()
+	}
+	var urlErr *url.Error
+	var netErr net.Error
+	return errors.As(err, &urlErr) || errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isRetryable reports whether a server fault is worth retrying: a refused
+// connection or a 5xx response.
+func isRetryable(err error) bool {
+	if errors.Is(err, errCallTimeout) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.serverFault()
+	}
+	return errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// pingOllama sends the minimal warmup-style chat request for model. Used as
+// the half-open probe; keepAlive is passed through so the probe doesn't
+// change how long the model stays loaded.
+func pingOllama(ctx context.Context, client *http.Client, baseURL, model, keepAlive string) error {
+	reqBody, err := json.Marshal(ollamaChatRequest{
+		Model:     model,
+		Messages:  []agenttypes.Message{{Role: "user", Content: "ping"}},
+		Stream:    false,
+		KeepAlive: keepAlive,
+		Options:   map[string]interface{}{"num_predict": 1},
+	})
+	if err != nil {
+		return fmt.Errorf("marshaling ping request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/chat", bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("creating ping request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending ping request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return &StatusError{Op: "ping", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	return nil
+}
@@ -0,0 +1,315 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	agenttypes "github.com/AleutianAI/AleutianFOSS/services/trace/agent/types"
+)
+
+// resilienceTestConfig is a fast configuration for tests.
+func resilienceTestConfig() ResilienceConfig {
+	return ResilienceConfig{
+		CallTimeout:      time.Second,
+		ProbeTimeout:     time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   time.Millisecond,
+		FailureThreshold: 2,
+		OpenDuration:     time.Minute,
+	}
+}
+
+// resilienceTestManager returns a manager for url using cfg.
+func resilienceTestManager(url string, cfg ResilienceConfig) *MultiModelManager {
+	mgr := NewMultiModelManager(url)
+	mgr.resilience = NewResilience(cfg)
+	return mgr
+}
+
+// resilienceTestChat sends a one-message chat to model.
+func resilienceTestChat(ctx context.Context, mgr *MultiModelManager, model string) (string, error) {
+	return mgr.Chat(ctx, model, []agenttypes.Message{{Role: "user", Content: "hello"}}, GenerationParams{})
+}
+
+// writeChatReply writes a successful Ollama chat response.
+func writeChatReply(w http.ResponseWriter, content string) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"message": map[string]string{"role": "assistant", "content": content},
+		"done":    true,
+	})
+}
+
+func TestResilience_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= 2 {
+			http.Error(w, "model runner crashed", http.StatusServiceUnavailable)
+			return
+		}
+		writeChatReply(w, "ok")
+	}))
+	defer server.Close()
+
+	mgr := resilienceTestManager(server.URL, resilienceTestConfig())
+	got, err := resilienceTestChat(context.Background(), mgr, "m")
+	if err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	if got != "ok" || calls.Load() != 3 {
+		t.Errorf("Chat() = %q after %d requests, want ok after 3", got, calls.Load())
+	}
+}
+
+func TestResilience_ClientErrorsNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "model not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	mgr := resilienceTestManager(server.URL, resilienceTestConfig())
+	for i := 0; i < 3; i++ {
+		_, err := resilienceTestChat(context.Background(), mgr, "m")
+		var statusErr *StatusError
+		if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+			t.Fatalf("Chat() error = %v, want 404 StatusError", err)
+		}
+		if errors.Is(err, ErrProviderUnavailable) {
+			t.Errorf("404 should not be reported as provider unavailable: %v", err)
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("requests = %d, want 3 (no retries)", calls.Load())
+	}
+	if state := mgr.Resilience().State("m"); state != BreakerClosed {
+		t.Errorf("breaker = %v, want closed", state)
+	}
+}
+
+func TestResilience_ConnectionRefused(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	mgr := resilienceTestManager(url, resilienceTestConfig())
+	_, err := resilienceTestChat(context.Background(), mgr, "m")
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || providerErr.BreakerOpen {
+		t.Fatalf("Chat() error = %v, want ProviderError from the call", err)
+	}
+	if !errors.Is(err, agent.ErrLLMUnavailable) {
+		t.Errorf("error should match agent.ErrLLMUnavailable: %v", err)
+	}
+}
+
+func TestResilience_CancelledContextNotRetriedOrCounted(t *testing.T) {
+	var calls atomic.Int32
+	started := make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		started <- struct{}{}
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	mgr := resilienceTestManager(server.URL, resilienceTestConfig())
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-started
+			cancel()
+		}()
+		_, err := resilienceTestChat(ctx, mgr, "m")
+		if err == nil || errors.Is(err, ErrProviderUnavailable) {
+			t.Fatalf("Chat() error = %v, want plain cancellation error", err)
+		}
+	}
+	if calls.Load() != 3 {
+		t.Errorf("requests = %d, want 3 (no retries)", calls.Load())
+	}
+	if state := mgr.Resilience().State("m"); state != BreakerClosed {
+		t.Errorf("breaker = %v, want closed", state)
+	}
+}
+
+func TestResilience_CallTimeout(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	cfg := resilienceTestConfig()
+	cfg.CallTimeout = 50 * time.Millisecond
+	mgr := resilienceTestManager(server.URL, cfg)
+
+	start := time.Now()
+	_, err := resilienceTestChat(context.Background(), mgr, "m")
+	if !errors.Is(err, ErrProviderUnavailable) || !errors.Is(err, errCallTimeout) {
+		t.Fatalf("Chat() error = %v, want provider unavailable after call timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Chat() took %v, want about the call timeout", elapsed)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("requests = %d, want 1 (timeouts are not retried)", calls.Load())
+	}
+}
+
+func TestResilience_BreakerOpensAndFailsFast(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		http.Error(w, "overloaded", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	mgr := resilienceTestManager(server.URL, resilienceTestConfig())
+	for i := 0; i < 2; i++ {
+		if _, err := resilienceTestChat(context.Background(), mgr, "m"); !errors.Is(err, ErrProviderUnavailable) {
+			t.Fatalf("call %d error = %v, want provider unavailable", i, err)
+		}
+	}
+	if state := mgr.Resilience().State("m"); state != BreakerOpen {
+		t.Fatalf("breaker = %v, want open", state)
+	}
+	// Each call counts once, however many attempts it made.
+	if calls.Load() != 6 {
+		t.Errorf("requests = %d, want 6", calls.Load())
+	}
+
+	_, err := resilienceTestChat(context.Background(), mgr, "m")
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || !providerErr.BreakerOpen {
+		t.Fatalf("Chat() error = %v, want open-breaker ProviderError", err)
+	}
+	if calls.Load() != 6 {
+		t.Errorf("open breaker sent a request: %d requests", calls.Load())
+	}
+	if states := mgr.Resilience().BreakerStates(); states["m"] != "open" {
+		t.Errorf("BreakerStates() = %v, want m open", states)
+	}
+	// Other models are unaffected.
+	if state := mgr.Resilience().State("other"); state != BreakerClosed {
+		t.Errorf("other model breaker = %v, want closed", state)
+	}
+}
+
+func TestResilience_HalfOpenProbe(t *testing.T) {
+	var (
+		healthy atomic.Bool
+		pings   atomic.Int32
+		chats   atomic.Int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), `"content":"ping"`) {
+			pings.Add(1)
+			if !strings.Contains(string(body), `"num_predict":1`) {
+				t.Errorf("probe should be a one-token ping: %s", body)
+			}
+		} else {
+			chats.Add(1)
+		}
+		if !healthy.Load() {
+			http.Error(w, "loading", http.StatusServiceUnavailable)
+			return
+		}
+		writeChatReply(w, "ok")
+	}))
+	defer server.Close()
+
+	cfg := resilienceTestConfig()
+	cfg.MaxRetries = 0
+	cfg.FailureThreshold = 1
+	mgr := resilienceTestManager(server.URL, cfg)
+	now := time.Now()
+	mgr.resilience.now = func() time.Time { return now }
+
+	if _, err := resilienceTestChat(context.Background(), mgr, "m"); err == nil {
+		t.Fatal("expected first call to fail")
+	}
+	if state := mgr.Resilience().State("m"); state != BreakerOpen {
+		t.Fatalf("breaker = %v, want open", state)
+	}
+
+	// After the cooldown a failing probe reopens the breaker.
+	now = now.Add(cfg.OpenDuration)
+	_, err := resilienceTestChat(context.Background(), mgr, "m")
+	var providerErr *ProviderError
+	if !errors.As(err, &providerErr) || !providerErr.BreakerOpen || providerErr.Err == nil {
+		t.Fatalf("Chat() error = %v, want failed-probe ProviderError", err)
+	}
+	if pings.Load() != 1 || chats.Load() != 1 {
+		t.Errorf("pings = %d, chats = %d, want 1 and 1", pings.Load(), chats.Load())
+	}
+
+	// Still within the new cooldown: fail fast without probing.
+	if _, err := resilienceTestChat(context.Background(), mgr, "m"); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("Chat() error = %v, want provider unavailable", err)
+	}
+	if pings.Load() != 1 {
+		t.Errorf("pings = %d, want 1", pings.Load())
+	}
+
+	// A successful probe closes the breaker and the call goes through.
+	healthy.Store(true)
+	now = now.Add(cfg.OpenDuration)
+	got, err := resilienceTestChat(context.Background(), mgr, "m")
+	if err != nil || got != "ok" {
+		t.Fatalf("Chat() = %q, %v, want ok", got, err)
+	}
+	if pings.Load() != 2 || chats.Load() != 2 {
+		t.Errorf("pings = %d, chats = %d, want 2 and 2", pings.Load(), chats.Load())
+	}
+	if state := mgr.Resilience().State("m"); state != BreakerClosed {
+		t.Errorf("breaker = %v, want closed", state)
+	}
+}
+
+func TestResilience_ToolCallParseErrorIsNotServerFault(t *testing.T) {
+	err := &StatusError{Op: "ollama chat", StatusCode: 500, Body: `{"error":"error parsing tool call: bad json"}`}
+	if isServerFault(err) || isRetryable(err) {
+		t.Error("tool call parse errors should not count as server faults")
+	}
+}
+
+func TestResilienceConfigFromEnv(t *testing.T) {
+	t.Setenv("OLLAMA_CALL_TIMEOUT", "90s")
+	t.Setenv("OLLAMA_MAX_RETRIES", "4")
+	t.Setenv("OLLAMA_BREAKER_THRESHOLD", "bogus")
+	t.Setenv("OLLAMA_BREAKER_COOLDOWN", "1m")
+
+	cfg := ResilienceConfigFromEnv()
+	def := DefaultResilienceConfig()
+	if cfg.CallTimeout != 90*time.Second || cfg.MaxRetries != 4 || cfg.OpenDuration != time.Minute {
+		t.Errorf("ResilienceConfigFromEnv() = %+v", cfg)
+	}
+	if cfg.FailureThreshold != def.FailureThreshold {
+		t.Errorf("invalid threshold should keep default %d, got %d", def.FailureThreshold, cfg.FailureThreshold)
+	}
+}
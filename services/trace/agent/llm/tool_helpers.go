@@ -21,8 +21,9 @@ import (
 // Description:
 //
 //	Maps tools.ToolDefinition to ToolDef for use with provider
-//	ChatWithTools methods. Preserves parameter types, descriptions,
-//	and required fields.
+//	ChatWithTools methods. Parameters carry the definition's full input
+//	schema (types, descriptions, enums, defaults, bounds, nested items
+//	and properties), so providers can send it without further mapping.
 //
 // Inputs:
 //   - defs: Tool definitions in agent format.
@@ -37,39 +38,13 @@ func convertToolDefs(defs []tools.ToolDefinition) []ToolDef {
 	}
 
 	result := make([]ToolDef, 0, len(defs))
-	for _, def := range defs {
-		properties := make(map[string]ToolParamDef)
-		var required []string
-
-		for paramName, paramDef := range def.Parameters {
-			pd := ToolParamDef{
-				Type:        string(paramDef.Type),
-				Description: paramDef.Description,
-				Enum:        paramDef.Enum,
-				Default:     paramDef.Default,
-			}
-			if paramDef.Items != nil {
-				pd.Items = &ToolParamDef{
-					Type:        string(paramDef.Items.Type),
-					Description: paramDef.Items.Description,
-				}
-			}
-			properties[paramName] = pd
-			if paramDef.Required {
-				required = append(required, paramName)
-			}
-		}
-
+	for i := range defs {
 		result = append(result, ToolDef{
 			Type: "function",
 			Function: ToolFunction{
-				Name:        def.Name,
-				Description: def.Description,
-				Parameters: ToolParameters{
-					Type:       "object",
-					Properties: properties,
-					Required:   required,
-				},
+				Name:        defs[i].Name,
+				Description: defs[i].Description,
+				Parameters:  defs[i].InputSchema(),
 			},
 		})
 	}
@@ -178,3 +153,26 @@ func estimateInputTokensChat(messages []ChatMessage) int {
 	}
 	return total / 4
 }
+
+// resultTokens returns the token counts for a ChatWithTools result.
+//
+// Description:
+//
+//	Uses the counts the provider reported when present; otherwise falls
+//	back to the ~4 characters per token estimate and reports estimated.
+//
+// Inputs:
+//   - result: The ChatWithTools result. Must not be nil.
+//   - messages: The input messages, used for the estimate.
+//
+// Outputs:
+//   - input, output: The token counts.
+//   - estimated: True if the counts are estimates.
+//
+// Thread Safety: This function is safe for concurrent use.
+func resultTokens(result *ChatWithToolsResult, messages []ChatMessage) (input, output int, estimated bool) {
+	if result.Usage != nil {
+		return result.Usage.InputTokens, result.Usage.OutputTokens, false
+	}
+	return estimateInputTokensChat(messages), estimateTokens(result.Content), true
+}
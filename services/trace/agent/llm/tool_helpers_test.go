@@ -424,6 +424,46 @@ func TestParseToolCalls_ThoughtSignaturePreserved(t *testing.T) {
 	}
 }
 
+func TestParseToolCalls_PlanMarkings(t *testing.T) {
+	response := &Response{
+		ToolCalls: []ToolCall{
+			{ID: "call-1", Name: "find_symbol", Arguments: `{"name": "Handler", "_output": "sym", "_group": "lookup"}`},
+			{ID: "call-2", Name: "find_callers", Arguments: `{"symbol": "$sym", "_depends_on": ["sym", "call-0"]}`},
+			{ID: "call-3", Name: "read_file", Arguments: `{"path": "a.go", "_depends_on": "call-1"}`},
+			{ID: "call-4", Name: "grep", Arguments: `{"pattern": "_output"}`},
+		},
+	}
+
+	invocations := ParseToolCalls(response)
+	if len(invocations) != 4 {
+		t.Fatalf("len(invocations) = %d, want 4", len(invocations))
+	}
+
+	first := invocations[0]
+	if first.OutputVar != "sym" || first.Group != "lookup" || len(first.DependsOn) != 0 {
+		t.Errorf("invocations[0] markings = %q %q %v", first.OutputVar, first.Group, first.DependsOn)
+	}
+	if _, ok := first.Parameters.StringParams["_output"]; ok {
+		t.Error("plan marking _output reached the tool parameters")
+	}
+	if string(first.Parameters.RawJSON) != `{"name":"Handler"}` {
+		t.Errorf("invocations[0] RawJSON = %s", first.Parameters.RawJSON)
+	}
+	if deps := invocations[1].DependsOn; len(deps) != 2 || deps[0] != "sym" || deps[1] != "call-0" {
+		t.Errorf("invocations[1].DependsOn = %v", deps)
+	}
+	if invocations[1].Parameters.StringParams["symbol"] != "$sym" {
+		t.Errorf("invocations[1] params = %v", invocations[1].Parameters.StringParams)
+	}
+	if deps := invocations[2].DependsOn; len(deps) != 1 || deps[0] != "call-1" {
+		t.Errorf("invocations[2].DependsOn = %v", deps)
+	}
+	// Arguments without markings are passed through untouched.
+	if string(invocations[3].Parameters.RawJSON) != `{"pattern": "_output"}` {
+		t.Errorf("invocations[3] RawJSON = %s", invocations[3].Parameters.RawJSON)
+	}
+}
+
 // =============================================================================
 // estimateInputTokensChat Tests
 // =============================================================================
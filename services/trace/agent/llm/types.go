@@ -10,7 +10,11 @@
 
 package llm
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
 
 // GenerationParams holds parameters for LLM generation.
 //
@@ -45,25 +49,12 @@ type ToolDef struct {
 
 // ToolFunction contains the function name, description, and parameter schema.
 type ToolFunction struct {
-	Name        string         `json:"name"`
-	Description string         `json:"description"`
-	Parameters  ToolParameters `json:"parameters"`
-}
-
-// ToolParameters defines the JSON Schema for tool parameters.
-type ToolParameters struct {
-	Type       string                  `json:"type"`
-	Properties map[string]ToolParamDef `json:"properties,omitempty"`
-	Required   []string                `json:"required,omitempty"`
-}
+	Name        string `json:"name"`
+	Description string `json:"description"`
 
-// ToolParamDef defines a single parameter in JSON Schema format.
-type ToolParamDef struct {
-	Type        string        `json:"type"`
-	Description string        `json:"description,omitempty"`
-	Enum        []any         `json:"enum,omitempty"`
-	Default     any           `json:"default,omitempty"`
-	Items       *ToolParamDef `json:"items,omitempty"`
+	// Parameters is the JSON Schema of the arguments object, passed to the
+	// provider as is.
+	Parameters *tools.JSONSchema `json:"parameters"`
 }
 
 // ChatMessage is a richer message type that carries tool call metadata.
@@ -120,6 +111,16 @@ type ChatWithToolsResult struct {
 	Content    string
 	ToolCalls  []ToolCallResponse
 	StopReason string
+
+	// Usage is the token usage reported by the provider, or nil if the
+	// response did not include it.
+	Usage *TokenUsage
+}
+
+// TokenUsage is the token usage a provider reports for one call.
+type TokenUsage struct {
+	InputTokens  int
+	OutputTokens int
 }
 
 // StreamEventType represents the type of streaming event.
@@ -12,6 +12,7 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"sort"
@@ -19,7 +20,11 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -115,7 +120,8 @@ type AgentLoop interface {
 	//
 	// Description:
 	//   Processes the user's clarification response and continues execution.
-	//   Only valid when session is in CLARIFY state.
+	//   Valid in CLARIFY state, in COMPLETE state for follow-ups, and in
+	//   ABORTED state when the session's AbortRecord is resumable.
 	//
 	// Inputs:
 	//   ctx - Context for cancellation and timeout.
@@ -124,16 +130,40 @@ type AgentLoop interface {
 	//
 	// Outputs:
 	//   *RunResult - The execution result.
-	//   error - Non-nil if session not found or not in CLARIFY state.
+	//   error - Non-nil if session not found or not in a continuable
+	//     state; ErrSessionNotResumable for an aborted session without a
+	//     checkpoint.
 	//
 	// Thread Safety: This method is safe for concurrent use.
 	Continue(ctx context.Context, sessionID string, clarification string) (*RunResult, error)
 
+	// ContinueWithOption resumes a session from CLARIFY state with a
+	// selected clarification option.
+	//
+	// Description:
+	//   Like Continue, with the option's text as the clarification. The
+	//   option must belong to the session's pending clarification request.
+	//
+	// Inputs:
+	//   ctx - Context for cancellation and timeout.
+	//   sessionID - The session ID to continue.
+	//   optionID - The selected option's ID.
+	//
+	// Outputs:
+	//   *RunResult - The execution result.
+	//   error - Non-nil if session not found, not in CLARIFY state, or
+	//     ErrUnknownClarificationOption if the option is not offered.
+	//
+	// Thread Safety: This method is safe for concurrent use.
+	ContinueWithOption(ctx context.Context, sessionID string, optionID string) (*RunResult, error)
+
 	// Abort terminates a running session.
 	//
 	// Description:
-	//   Stops a session that is currently executing. Does not affect
-	//   sessions that are already in terminal states.
+	//   Stops a session that is currently executing by cancelling its
+	//   run context, waiting a bounded time for the run to return, and
+	//   moves it to ABORTED. Does not affect sessions that are already in
+	//   terminal states.
 	//
 	// Inputs:
 	//   ctx - Context for the abort operation.
@@ -297,11 +327,29 @@ type DefaultAgentLoop struct {
 	// Takes precedence over phaseDeps when set.
 	depsFactory DependenciesFactory
 
+	// pipeline, when set, supplies the state machine, the phases and the
+	// start and follow-up states, and rewrites phase results.
+	pipeline *Pipeline
+
+	// historySummarizer condenses older turns before follow-ups. Nil
+	// disables summarization.
+	historySummarizer HistorySummarizer
+
 	// maxConcurrent limits concurrent sessions (0 = unlimited).
 	maxConcurrent int
 
 	// activeSessions tracks currently running sessions.
 	activeSessions int
+
+	// runsMu guards runs.
+	runsMu sync.Mutex
+
+	// runs holds the Run and Continue calls in progress by session ID,
+	// for Abort.
+	runs map[string]*activeRun
+
+	// abortTimeout bounds how long Abort waits for a run to stop.
+	abortTimeout time.Duration
 }
 
 // DefaultLoopOption configures a DefaultAgentLoop.
@@ -393,6 +441,28 @@ func WithPhaseDependencies(deps any) DefaultLoopOption {
 	}
 }
 
+// WithPipeline drives the loop from a configured phase pipeline.
+//
+// Description:
+//
+//	Replaces the built-in state machine and phase registry with those of
+//	the pipeline, so its states, transitions and redirects decide the
+//	flow. Overrides WithPhaseRegistry. Without it the loop runs the
+//	built-in INIT/PLAN/EXECUTE/REFLECT/CLARIFY flow.
+//
+// Inputs:
+//
+//	p - The validated pipeline, from NewPipeline.
+//
+// Outputs:
+//
+//	DefaultLoopOption - The configuration function.
+func WithPipeline(p *Pipeline) DefaultLoopOption {
+	return func(l *DefaultAgentLoop) {
+		l.pipeline = p
+	}
+}
+
 // NewDefaultAgentLoop creates a new agent loop.
 //
 // Description:
@@ -412,11 +482,16 @@ func NewDefaultAgentLoop(opts ...DefaultLoopOption) *DefaultAgentLoop {
 	l := &DefaultAgentLoop{
 		sessions:     NewInMemorySessionStore(),
 		stateMachine: DefaultStateMachine,
+		abortTimeout: DefaultAbortTimeout,
 	}
 
 	for _, opt := range opts {
 		opt(l)
 	}
+	if l.pipeline != nil {
+		l.stateMachine = l.pipeline.StateMachine()
+		l.phaseRegistry = l.pipeline
+	}
 
 	return l
 }
@@ -426,8 +501,9 @@ func NewDefaultAgentLoop(opts ...DefaultLoopOption) *DefaultAgentLoop {
 // Description:
 //
 //	Executes the agent loop for a new query. The session must be in IDLE state.
-//	The loop runs until reaching a terminal state (COMPLETE, ERROR) or
-//	CLARIFY state (which pauses for user input).
+//	The loop runs until reaching a terminal state (COMPLETE, ERROR, ABORTED)
+//	or CLARIFY state (which pauses for user input). The run's context is
+//	cancelled by Abort.
 //
 // Inputs:
 //
@@ -443,26 +519,27 @@ func NewDefaultAgentLoop(opts ...DefaultLoopOption) *DefaultAgentLoop {
 // Thread Safety: This method is safe for concurrent use with different sessions.
 func (l *DefaultAgentLoop) Run(ctx context.Context, session *Session, query string) (*RunResult, error) {
 	if err := l.validateRunInput(session, query); err != nil {
-		slog.Error("Agent loop validation failed", slog.String("error", err.Error()))
+		slog.ErrorContext(ctx, "Agent loop validation failed", telemetry.SensitiveLogError("error", err))
 		return nil, err
 	}
 
-	slog.Info("Agent loop starting",
+	slog.InfoContext(ctx, "Agent loop starting",
 		slog.String("session_id", session.ID),
-		slog.String("project_root", session.ProjectRoot),
+		telemetry.SensitiveLog("project_root", session.ProjectRoot),
 		slog.Int("query_len", len(query)),
 	)
 
 	// Try to acquire the session
 	if !session.TryAcquire() {
-		slog.Warn("Session already in progress", slog.String("session_id", session.ID))
+		slog.WarnContext(ctx, "Session already in progress", slog.String("session_id", session.ID))
 		return nil, ErrSessionInProgress
 	}
-	defer session.Release()
+	ctx, endRun := l.beginRun(ctx, session)
+	defer endRun()
 
 	// Check concurrent session limit
 	if err := l.acquireSlot(); err != nil {
-		slog.Warn("Concurrent session limit reached", slog.String("error", err.Error()))
+		slog.WarnContext(ctx, "Concurrent session limit reached", telemetry.SensitiveLogError("error", err))
 		return nil, err
 	}
 	defer l.releaseSlot()
@@ -473,13 +550,19 @@ func (l *DefaultAgentLoop) Run(ctx context.Context, session *Session, query stri
 	// Store the query
 	session.LastQuery = query
 
-	// Transition to INIT
-	if err := l.transition(session, StateInit, "query received"); err != nil {
+	// Transition to the start state (INIT unless a pipeline says otherwise)
+	if err := l.transition(session, l.startState(), "query received"); err != nil {
 		return nil, err
 	}
 
 	// Run the main loop
-	return l.runLoop(ctx, session)
+	ctx, span := startRunSpan(ctx, "agent.DefaultAgentLoop.Run", session, query)
+	result, err := l.runLoop(ctx, session)
+	endRunSpan(span, result, err)
+
+	// Store the final state too, for stores that persist sessions.
+	l.sessions.Put(session)
+	return result, err
 }
 
 // Continue implements AgentLoop.
@@ -493,6 +576,9 @@ func (l *DefaultAgentLoop) Run(ctx context.Context, session *Session, query stri
 //	For CLARIFY state: Provides the requested clarification and continues.
 //	For COMPLETE state: Treats input as a follow-up question with conversation
 //	history preserved, enabling multi-turn conversations.
+//	For ABORTED state: Reruns the interrupted phase from the context it
+//	started with, adding any non-empty input as a user message. Returns
+//	ErrSessionNotResumable when the abort left nothing to resume.
 //
 // Inputs:
 //
@@ -507,22 +593,67 @@ func (l *DefaultAgentLoop) Run(ctx context.Context, session *Session, query stri
 //
 // Thread Safety: This method is safe for concurrent use.
 func (l *DefaultAgentLoop) Continue(ctx context.Context, sessionID string, clarification string) (*RunResult, error) {
+	return l.continueSession(ctx, sessionID, ClarificationResponse{Text: clarification})
+}
+
+// ContinueWithOption implements AgentLoop.
+//
+// Description:
+//
+//	Resumes a CLARIFY session with one of the options of its pending
+//	clarification request. The original query is kept and the option's
+//	text is appended to it, so planning sees both.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout.
+//	sessionID - The session ID to continue.
+//	optionID - The selected option's ID.
+//
+// Outputs:
+//
+//	*RunResult - The execution result.
+//	error - Non-nil if session not found, invalid state, or the option is
+//	  not offered (ErrUnknownClarificationOption).
+//
+// Thread Safety: This method is safe for concurrent use.
+func (l *DefaultAgentLoop) ContinueWithOption(ctx context.Context, sessionID string, optionID string) (*RunResult, error) {
+	session, ok := l.sessions.Get(sessionID)
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if session.GetState() != StateClarify {
+		return nil, ErrNotInClarifyState
+	}
+	opt, ok := session.ClarificationOption(optionID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownClarificationOption, optionID)
+	}
+	return l.continueSession(ctx, sessionID, ClarificationResponse{Text: opt.Text(), OptionID: opt.ID})
+}
+
+// continueSession resumes a CLARIFY, COMPLETE or ABORTED session with the
+// user's answer. See Continue.
+func (l *DefaultAgentLoop) continueSession(ctx context.Context, sessionID string, resp ClarificationResponse) (*RunResult, error) {
 	session, ok := l.sessions.Get(sessionID)
 	if !ok {
 		return nil, ErrSessionNotFound
 	}
 
 	currentState := session.GetState()
+	clarification := resp.Text
 
-	// Accept both CLARIFY (awaiting clarification) and COMPLETE (follow-up question)
-	if currentState != StateClarify && currentState != StateComplete {
+	// Accept CLARIFY (awaiting clarification), COMPLETE (follow-up question)
+	// and ABORTED (resume from the checkpoint)
+	if currentState != StateClarify && currentState != StateComplete && currentState != StateAborted {
 		return nil, ErrNotInClarifyState
 	}
 
 	if !session.TryAcquire() {
 		return nil, ErrSessionInProgress
 	}
-	defer session.Release()
+	ctx, endRun := l.beginRun(ctx, session)
+	defer endRun()
 
 	// Check concurrent session limit
 	if err := l.acquireSlot(); err != nil {
@@ -531,9 +662,38 @@ func (l *DefaultAgentLoop) Continue(ctx context.Context, sessionID string, clari
 	defer l.releaseSlot()
 
 	// Handle based on current state
-	if currentState == StateComplete {
+	switch currentState {
+	case StateAborted:
+		record := session.abortRecord()
+		resumeState, err := session.resumeFromAbort()
+		if err != nil {
+			return nil, err
+		}
+		slog.InfoContext(ctx, "Resuming aborted session",
+			slog.String("session_id", session.ID),
+			slog.String("phase", string(resumeState)),
+			slog.String("last_completed_phase", string(record.LastCompletedPhase)),
+			slog.Int("message_len", len(clarification)),
+		)
+		session.AddHistoryEntry(HistoryEntry{
+			Type:  "resume",
+			Input: fmt.Sprintf("resuming %s after abort", resumeState),
+			Query: clarification,
+		})
+
+		// A message sent with the resume joins the conversation
+		if clarification != "" {
+			if sessionCtx := session.GetCurrentContext(); sessionCtx != nil {
+				sessionCtx.ConversationHistory = append(sessionCtx.ConversationHistory, Message{
+					Role:    "user",
+					Content: clarification,
+				})
+				session.SetCurrentContext(sessionCtx)
+			}
+		}
+	case StateComplete:
 		// Follow-up question on completed session
-		slog.Info("Continuing completed session with follow-up",
+		slog.InfoContext(ctx, "Continuing completed session with follow-up",
 			slog.String("session_id", session.ID),
 			slog.Int("follow_up_len", len(clarification)),
 		)
@@ -551,6 +711,7 @@ func (l *DefaultAgentLoop) Continue(ctx context.Context, sessionID string, clari
 		// Add to conversation context if available
 		sessionCtx := session.GetCurrentContext()
 		if sessionCtx != nil {
+			l.summarizeHistory(ctx, session, sessionCtx)
 			sessionCtx.ConversationHistory = append(sessionCtx.ConversationHistory, Message{
 				Role:    "user",
 				Content: clarification,
@@ -558,41 +719,69 @@ func (l *DefaultAgentLoop) Continue(ctx context.Context, sessionID string, clari
 			session.SetCurrentContext(sessionCtx)
 		}
 
-		// Transition back to PLAN to process the follow-up (via state machine)
-		if err := l.transition(session, StatePlan, "multi-turn follow-up"); err != nil {
+		// Transition to the follow-up state (PLAN by default) via the state machine
+		if err := l.transition(session, l.followUpState(), "multi-turn follow-up"); err != nil {
 			return nil, err
 		}
-	} else {
+	default:
 		// Clarification for ambiguous query
-		slog.Info("Continuing with clarification",
+		slog.InfoContext(ctx, "Continuing with clarification",
 			slog.String("session_id", session.ID),
 			slog.Int("clarification_len", len(clarification)),
+			slog.String("path", resp.Path()),
+			slog.String("option_id", resp.OptionID),
 		)
 
+		question := ""
+		if pending := session.PendingClarification(); pending != nil {
+			question = pending.Question
+		}
 		session.AddHistoryEntry(HistoryEntry{
-			Type:  "clarification",
-			Input: clarification,
-			Query: clarification,
+			Type:                  "clarification",
+			Input:                 clarification,
+			Query:                 clarification,
+			ClarificationPrompt:   question,
+			ClarificationOptionID: resp.OptionID,
 		})
-
-		// Update the query with the clarified version
-		session.LastQuery = clarification
+		session.SetPendingClarification(nil)
+		session.SetClarificationResponse(resp)
+
+		// Update the query with the clarified version. A selected option
+		// only names the symbol, so it refines the query instead.
+		if resp.OptionID != "" && session.LastQuery != "" {
+			session.LastQuery = session.LastQuery + "\n\n" + clarification
+		} else {
+			session.LastQuery = clarification
+		}
 	}
 
 	// Run the main loop
-	return l.runLoop(ctx, session)
+	ctx, span := startRunSpan(ctx, "agent.DefaultAgentLoop.Continue", session, clarification)
+	result, err := l.runLoop(ctx, session)
+	endRunSpan(span, result, err)
+
+	// Store the new state, for stores that persist sessions.
+	l.sessions.Put(session)
+	return result, err
 }
 
 // Abort implements AgentLoop.
 //
 // Description:
 //
-//	Terminates a running session by transitioning it to ERROR state.
-//	If the session is already in a terminal state, this is a no-op.
+//	Moves a session to ABORTED. A run in progress has its context
+//	cancelled with ErrAborted as the cause, which stops the LLM call or
+//	tool batch it is in, and Abort waits up to the abort timeout for it
+//	to return. A run that does not stop in time is marked aborted anyway,
+//	with AbortRecord.Acknowledged false. A session that is not running
+//	is aborted directly. Sessions in a terminal state are left as is.
+//
+//	The session's AbortRecord, in GetState, tells whether Continue can
+//	resume it.
 //
 // Inputs:
 //
-//	ctx - Context for the abort operation.
+//	ctx - Context for the abort operation. Cancelling it stops the wait.
 //	sessionID - The session ID to abort.
 //
 // Outputs:
@@ -606,18 +795,28 @@ func (l *DefaultAgentLoop) Abort(ctx context.Context, sessionID string) error {
 		return ErrSessionNotFound
 	}
 
-	// If already terminated, nothing to do
-	if session.IsTerminated() {
+	run := l.activeRunFor(sessionID)
+	if run == nil {
+		// If already terminated, nothing to do
+		if session.IsTerminated() {
+			return nil
+		}
+		session.markAborted(true)
+		l.sessions.Put(session)
 		return nil
 	}
 
-	// Force transition to ERROR
-	session.SetState(StateError)
-	session.AddHistoryEntry(HistoryEntry{
-		Type:  "abort",
-		Error: "session aborted by user",
-	})
+	run.cancel(ErrAborted)
+	if l.waitForAbort(ctx, run) {
+		return nil
+	}
 
+	slog.WarnContext(ctx, "Aborted run did not stop in time",
+		slog.String("session_id", sessionID),
+		slog.Duration("timeout", l.abortTimeout),
+	)
+	session.markAborted(false)
+	l.sessions.Put(session)
 	return nil
 }
 
@@ -764,6 +963,22 @@ func (l *DefaultAgentLoop) validateRunInput(session *Session, query string) erro
 	return nil
 }
 
+// startState returns the state a new query enters.
+func (l *DefaultAgentLoop) startState() AgentState {
+	if l.pipeline != nil {
+		return l.pipeline.Start()
+	}
+	return StateInit
+}
+
+// followUpState returns the state a follow-up question enters.
+func (l *DefaultAgentLoop) followUpState() AgentState {
+	if l.pipeline != nil {
+		return l.pipeline.FollowUp()
+	}
+	return StatePlan
+}
+
 // acquireSlot attempts to acquire a concurrent session slot.
 func (l *DefaultAgentLoop) acquireSlot() error {
 	l.mu.Lock()
@@ -798,7 +1013,7 @@ func (l *DefaultAgentLoop) transition(session *Session, newState AgentState, rea
 	if err := l.stateMachine.Transition(session, newState); err != nil {
 		slog.Error("State transition failed",
 			slog.String("session_id", session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return err
 	}
@@ -815,10 +1030,19 @@ func (l *DefaultAgentLoop) transition(session *Session, newState AgentState, rea
 // runLoop executes the main agent loop.
 func (l *DefaultAgentLoop) runLoop(ctx context.Context, session *Session) (*RunResult, error) {
 	startTime := time.Now()
+	ctx = ContextWithSessionID(ctx, session.ID)
+	phaseRuns := make(map[AgentState]int)
+	linkedOrigins := 0
 
 	for {
+		// Restore origins appear once phase dependencies restore a session.
+		linkedOrigins = linkRestoreOrigins(ctx, session, linkedOrigins)
+
 		// Check context cancellation
 		if err := ctx.Err(); err != nil {
+			if errors.Is(context.Cause(ctx), ErrAborted) {
+				return l.finishAborted(ctx, session), nil
+			}
 			// LP-001: Record audit trail before setting error state
 			session.AddHistoryEntry(HistoryEntry{
 				Type:  "context_cancelled",
@@ -826,7 +1050,7 @@ func (l *DefaultAgentLoop) runLoop(ctx context.Context, session *Session) (*RunR
 				Error: ErrCanceled.Error(),
 			})
 			if transErr := l.transition(session, StateError, "context cancelled"); transErr != nil {
-				slog.Warn("Failed to transition to error state", slog.String("error", transErr.Error()))
+				slog.WarnContext(ctx, "Failed to transition to error state", telemetry.SensitiveLogError("error", transErr))
 			}
 			return l.buildErrorResult(session, ErrCanceled, startTime), nil
 		}
@@ -837,7 +1061,7 @@ func (l *DefaultAgentLoop) runLoop(ctx context.Context, session *Session) (*RunR
 
 			// cb_30b: Enhanced timeout diagnostics
 			// Log detailed information to help debug timeout issues
-			slog.Error("Session timeout exceeded",
+			slog.ErrorContext(ctx, "Session timeout exceeded",
 				slog.String("session_id", session.ID),
 				slog.Duration("elapsed", elapsed),
 				slog.Duration("timeout", session.Config.TotalTimeout),
@@ -853,7 +1077,7 @@ func (l *DefaultAgentLoop) runLoop(ctx context.Context, session *Session) (*RunR
 				Error: ErrTimeout.Error(),
 			})
 			if transErr := l.transition(session, StateError, "timeout exceeded"); transErr != nil {
-				slog.Warn("Failed to transition to error state", slog.String("error", transErr.Error()))
+				slog.WarnContext(ctx, "Failed to transition to error state", telemetry.SensitiveLogError("error", transErr))
 			}
 
 			// cb_30b: Return result with partial trace for debugging
@@ -870,13 +1094,17 @@ func (l *DefaultAgentLoop) runLoop(ctx context.Context, session *Session) (*RunR
 			return l.buildResult(session, startTime), nil
 		}
 
-		// Check for CLARIFY state (pause for user input)
-		if currentState == StateClarify {
-			return l.buildClarifyResult(session, startTime), nil
-		}
-
+		// CLARIFY pauses for user input: its phase either takes the answer
+		// Continue left on the session or returns ErrAwaitingClarification.
 		// Execute the current phase
-		nextState, err := l.executePhase(ctx, session)
+		phaseRuns[currentState]++
+		session.checkpointPhase(currentState)
+		nextState, err := l.executePhase(ctx, session, phaseRuns[currentState])
+
+		// A phase cut short by Abort did not complete, whatever it returned
+		if errors.Is(context.Cause(ctx), ErrAborted) {
+			return l.finishAborted(ctx, session), nil
+		}
 		if err != nil {
 			// Check if it's awaiting clarification (not a real error)
 			if err == ErrAwaitingClarification {
@@ -890,30 +1118,47 @@ func (l *DefaultAgentLoop) runLoop(ctx context.Context, session *Session) (*RunR
 				Error: err.Error(),
 			})
 			if transErr := l.transition(session, StateError, fmt.Sprintf("phase error: %v", err)); transErr != nil {
-				slog.Warn("Failed to transition to error state", slog.String("error", transErr.Error()))
+				slog.WarnContext(ctx, "Failed to transition to error state", telemetry.SensitiveLogError("error", transErr))
 				// Fallback: force state if transition fails (edge case)
 				session.SetState(StateError)
 			}
 			return l.buildErrorResult(session, err, startTime), nil
 		}
 
+		// Apply the pipeline's redirect, e.g. EXECUTE's COMPLETE -> VERIFY
+		if l.pipeline != nil {
+			if redirected := l.pipeline.Next(currentState, nextState); redirected != nextState {
+				slog.InfoContext(ctx, "Phase result redirected by pipeline",
+					slog.String("session_id", session.ID),
+					slog.String("state", string(currentState)),
+					slog.String("result", string(nextState)),
+					slog.String("next_state", string(redirected)),
+				)
+				nextState = redirected
+			}
+		}
+
 		// Transition to the next state
 		if nextState != currentState {
 			if err := l.transition(session, nextState, "phase completed"); err != nil {
 				// LP-002: Record history for transition failure
+				if errors.Is(context.Cause(ctx), ErrAborted) {
+					return l.finishAborted(ctx, session), nil
+				}
 				session.AddHistoryEntry(HistoryEntry{
 					Type:  "transition_error",
 					Input: fmt.Sprintf("%s -> %s", currentState, nextState),
 					Error: err.Error(),
 				})
 				if transErr := l.transition(session, StateError, fmt.Sprintf("transition error: %v", err)); transErr != nil {
-					slog.Warn("Failed to transition to error state", slog.String("error", transErr.Error()))
+					slog.WarnContext(ctx, "Failed to transition to error state", telemetry.SensitiveLogError("error", transErr))
 					session.SetState(StateError)
 				}
 				return l.buildErrorResult(session, err, startTime), nil
 			}
 		}
 
+		session.completePhase(currentState)
 		session.IncrementMetric(MetricSteps, 1)
 	}
 }
@@ -927,52 +1172,69 @@ func (l *DefaultAgentLoop) runLoop(ctx context.Context, session *Session) (*RunR
 //	Uses depsFactory for per-session dependency creation if available,
 //	otherwise falls back to static phaseDeps.
 //
+//	Each execution gets its own span; tool and LLM spans started by the
+//	phase nest under it.
+//
 // Inputs:
 //
 //	ctx - Context for cancellation and timeout.
 //	session - The session being executed.
+//	iteration - How many times the current state's phase has run in this
+//	  Run or Continue call, including this one.
 //
 // Outputs:
 //
 //	AgentState - The next state to transition to.
 //	error - Non-nil if phase execution failed.
-func (l *DefaultAgentLoop) executePhase(ctx context.Context, session *Session) (AgentState, error) {
+func (l *DefaultAgentLoop) executePhase(ctx context.Context, session *Session, iteration int) (nextState AgentState, err error) {
 	currentState := session.GetState()
 
-	slog.Info("Executing phase",
+	ctx, span := startPhaseSpan(ctx, session, currentState, iteration)
+	defer func() {
+		span.SetAttributes(attribute.String("phase.next_state", string(nextState)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
+
+	slog.InfoContext(ctx, "Executing phase",
 		slog.String("session_id", session.ID),
 		slog.String("state", string(currentState)),
 	)
 
 	if l.phaseRegistry == nil {
-		slog.Debug("No phase registry, using default execution")
+		slog.DebugContext(ctx, "No phase registry, using default execution")
+		span.SetAttributes(attribute.String("phase.name", "default"))
 		return l.defaultPhaseExecution(session)
 	}
 
 	phase, ok := l.phaseRegistry.GetPhase(currentState)
 	if !ok || phase == nil {
-		slog.Debug("No phase registered for state, using default execution",
+		slog.DebugContext(ctx, "No phase registered for state, using default execution",
 			slog.String("state", string(currentState)),
 		)
+		span.SetAttributes(attribute.String("phase.name", "default"))
 		return l.defaultPhaseExecution(session)
 	}
+	span.SetAttributes(attribute.String("phase.name", phase.Name()))
 
-	slog.Info("Running phase implementation",
+	slog.InfoContext(ctx, "Running phase implementation",
 		slog.String("phase", phase.Name()),
 		slog.String("session_id", session.ID),
 	)
 
 	// Get dependencies for phase execution
 	var deps any
-	var err error
 
 	if l.depsFactory != nil {
 		// Use factory for per-session dependencies
 		deps, err = l.depsFactory.Create(session, session.LastQuery)
 		if err != nil {
-			slog.Error("Failed to create dependencies",
+			slog.ErrorContext(ctx, "Failed to create dependencies",
 				slog.String("session_id", session.ID),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return StateError, fmt.Errorf("failed to create dependencies: %w", err)
 		}
@@ -982,15 +1244,20 @@ func (l *DefaultAgentLoop) executePhase(ctx context.Context, session *Session) (
 	}
 
 	// Execute the phase with dependencies
-	nextState, err := phase.Execute(ctx, deps)
-	if err != nil {
-		slog.Error("Phase execution failed",
+	nextState, err = phase.Execute(ctx, deps)
+	if errors.Is(err, ErrAwaitingClarification) {
+		slog.InfoContext(ctx, "Phase awaiting clarification",
+			slog.String("phase", phase.Name()),
+			slog.String("session_id", session.ID),
+		)
+	} else if err != nil {
+		slog.ErrorContext(ctx, "Phase execution failed",
 			slog.String("phase", phase.Name()),
 			slog.String("session_id", session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 	} else {
-		slog.Info("Phase execution completed",
+		slog.InfoContext(ctx, "Phase execution completed",
 			slog.String("phase", phase.Name()),
 			slog.String("session_id", session.ID),
 			slog.String("next_state", string(nextState)),
@@ -1084,22 +1351,30 @@ func (l *DefaultAgentLoop) buildResult(session *Session, startTime time.Time) *R
 	// Add response if complete
 	if session.GetState() == StateComplete {
 		result.Response = l.getLastAssistantMessage(session)
+		applyCitations(session, result)
 	}
 
 	return result
 }
 
 // buildClarifyResult creates a RunResult for a session needing clarification.
+//
+// The request is the one the clarify phase left on the session, with
+// options when it could derive them, or a plain question otherwise.
 func (l *DefaultAgentLoop) buildClarifyResult(session *Session, startTime time.Time) *RunResult {
+	req := session.PendingClarification()
+	if req == nil {
+		req = &ClarifyRequest{Question: session.GetClarificationPrompt()}
+	}
+	if req.Context == "" {
+		req.Context = "Additional information needed to proceed"
+	}
 	return &RunResult{
-		State:      StateClarify,
-		TokensUsed: session.Metrics.TotalTokens,
-		StepsTaken: session.Metrics.TotalSteps,
-		ToolsUsed:  l.collectToolInvocations(session),
-		NeedsClarify: &ClarifyRequest{
-			Question: session.GetClarificationPrompt(),
-			Context:  "Additional information needed to proceed",
-		},
+		State:        StateClarify,
+		TokensUsed:   session.Metrics.TotalTokens,
+		StepsTaken:   session.Metrics.TotalSteps,
+		ToolsUsed:    l.collectToolInvocations(session),
+		NeedsClarify: req,
 	}
 }
 
@@ -1182,16 +1457,28 @@ func (l *DefaultAgentLoop) buildTimeoutResult(session *Session, startTime time.T
 }
 
 // buildErrorResult creates a RunResult for an error.
+//
+// Errors wrapping ErrLLMUnavailable, such as an LLM client's open circuit
+// breaker, are reported as PROVIDER_UNAVAILABLE and marked recoverable:
+// the same run may succeed once the provider is back. Errors wrapping
+// ErrContextOverflow are reported as CONTEXT_OVERFLOW; the message carries
+// the estimated prompt size and the model's limit.
 func (l *DefaultAgentLoop) buildErrorResult(session *Session, err error, startTime time.Time) *RunResult {
+	code, recoverable := "EXECUTION_ERROR", false
+	if errors.Is(err, ErrLLMUnavailable) {
+		code, recoverable = "PROVIDER_UNAVAILABLE", true
+	} else if errors.Is(err, ErrContextOverflow) {
+		code = "CONTEXT_OVERFLOW"
+	}
 	return &RunResult{
 		State:      StateError,
 		TokensUsed: session.Metrics.TotalTokens,
 		StepsTaken: session.Metrics.TotalSteps,
 		ToolsUsed:  l.collectToolInvocations(session),
 		Error: &AgentError{
-			Code:        "EXECUTION_ERROR",
+			Code:        code,
 			Message:     err.Error(),
-			Recoverable: false,
+			Recoverable: recoverable,
 		},
 	}
 }
@@ -13,6 +13,7 @@ package agent
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -184,8 +185,13 @@ func TestDefaultAgentLoop_Abort_Success(t *testing.T) {
 		t.Fatalf("Abort failed: %v", err)
 	}
 
-	if session.GetState() != StateError {
-		t.Errorf("State = %s, want ERROR", session.GetState())
+	if session.GetState() != StateAborted {
+		t.Errorf("State = %s, want ABORTED", session.GetState())
+	}
+	// Not running, so there is no checkpoint to resume from.
+	state, _ := loop.GetState(session.ID)
+	if state.Abort == nil || state.Abort.Resumable || state.Abort.InterruptedPhase != StateExecute {
+		t.Errorf("Abort = %+v, want a non-resumable record of EXECUTE", state.Abort)
 	}
 }
 
@@ -414,6 +420,27 @@ func TestDefaultAgentLoop_PhaseError(t *testing.T) {
 	}
 }
 
+func TestDefaultAgentLoop_PhaseError_ProviderUnavailable(t *testing.T) {
+	registry := NewMockPhaseRegistry()
+
+	// An LLM client's open circuit breaker surfaces as a wrapped ErrLLMUnavailable.
+	phaseErr := fmt.Errorf("LLM request failed: %w", ErrLLMUnavailable)
+	registry.RegisterPhase(StateInit, &MockPhase{name: "init", nextState: StateError, err: phaseErr})
+
+	loop := NewDefaultAgentLoop(WithPhaseRegistry(registry))
+
+	session, _ := NewSession("/test/project", nil)
+
+	result, err := loop.Run(context.Background(), session, "query")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if result.Error == nil || result.Error.Code != "PROVIDER_UNAVAILABLE" || !result.Error.Recoverable {
+		t.Errorf("Error = %+v, want recoverable PROVIDER_UNAVAILABLE", result.Error)
+	}
+}
+
 func TestDefaultAgentLoop_ClarifyWorkflow(t *testing.T) {
 	// This test verifies that the CLARIFY state correctly pauses for user input
 	// and that Continue() can resume the session.
@@ -0,0 +1,131 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// loopTracer creates the run and phase spans of the agent loop. Tool and
+// LLM spans started by phases nest under the phase span.
+var loopTracer = otel.Tracer("aleutian.agent.loop")
+
+// startRunSpan starts the run-level span for a Run or Continue call and
+// records it on the session.
+//
+// Inputs:
+//
+//	ctx - Parent context, usually carrying the HTTP request span.
+//	name - Span name.
+//	session - The session being run.
+//	query - The query or clarification for this turn.
+//
+// Outputs:
+//
+//	context.Context - ctx carrying the run span.
+//	trace.Span - The run span. End it with endRunSpan.
+func startRunSpan(ctx context.Context, name string, session *Session, query string) (context.Context, trace.Span) {
+	ctx, span := loopTracer.Start(ctx, name,
+		trace.WithAttributes(
+			attribute.String("session_id", session.ID),
+			attribute.String("agent.start_state", string(session.GetState())),
+			attribute.Int("query_len", len(query)),
+		),
+	)
+	session.SetRunSpanContext(span.SpanContext())
+	return ctx, span
+}
+
+// endRunSpan records the outcome of a run on its span and ends it.
+func endRunSpan(span trace.Span, result *RunResult, err error) {
+	defer span.End()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	if result == nil {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("agent.state", string(result.State)),
+		attribute.Int("agent.steps", result.StepsTaken),
+		attribute.Int("agent.tokens", result.TokensUsed),
+		attribute.Int("agent.tool_calls", len(result.ToolsUsed)),
+	)
+	if result.Error != nil {
+		span.SetStatus(codes.Error, result.Error.Message)
+	}
+}
+
+// linkRestoreOrigins links the run span in ctx to the session's restore
+// origins that are not linked yet.
+//
+// Description:
+//
+//	Session restore happens while building phase dependencies, after the
+//	run span has started, so links are added as origins appear rather
+//	than at span start.
+//
+// Inputs:
+//
+//	ctx - Context carrying the run span.
+//	session - The session being run.
+//	linked - How many origins are already linked.
+//
+// Outputs:
+//
+//	int - How many origins are linked now.
+func linkRestoreOrigins(ctx context.Context, session *Session, linked int) int {
+	origins := session.RestoreOrigins()
+	if len(origins) <= linked {
+		return linked
+	}
+	span := trace.SpanFromContext(ctx)
+	for _, origin := range origins[linked:] {
+		span.AddLink(trace.Link{
+			SpanContext: origin,
+			Attributes:  []attribute.KeyValue{attribute.String("link.kind", "session_restore")},
+		})
+	}
+	return len(origins)
+}
+
+// startPhaseSpan starts the span for one phase execution.
+//
+// Inputs:
+//
+//	ctx - Context carrying the run span.
+//	session - The session being run.
+//	state - The state whose phase runs.
+//	iteration - How many times this state's phase has run in this call,
+//	  including this one.
+//
+// Outputs:
+//
+//	context.Context - ctx carrying the phase span.
+//	trace.Span - The phase span. The caller sets phase.name once the
+//	  phase is resolved and ends the span.
+func startPhaseSpan(ctx context.Context, session *Session, state AgentState, iteration int) (context.Context, trace.Span) {
+	return loopTracer.Start(ctx, "agent.DefaultAgentLoop.executePhase",
+		trace.WithAttributes(
+			attribute.String("session_id", session.ID),
+			attribute.String("agent.state", string(state)),
+			attribute.Int("phase.iteration", iteration),
+			attribute.Int("agent.step", session.Metrics.TotalSteps),
+		),
+	)
+}
@@ -0,0 +1,179 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedPhase starts child spans the way the execute phase does for its
+// LLM and tool calls, then moves to next once it has run runs times.
+type tracedPhase struct {
+	name     string
+	next     AgentState
+	runs     int
+	executed int
+	children []string
+}
+
+func (p *tracedPhase) Name() string { return p.name }
+
+func (p *tracedPhase) Execute(ctx context.Context, deps any) (AgentState, error) {
+	tracer := otel.Tracer("test.phase")
+	for _, child := range p.children {
+		_, span := tracer.Start(ctx, child)
+		span.End()
+	}
+	p.executed++
+	if p.executed < p.runs {
+		return StateExecute, nil
+	}
+	return p.next, nil
+}
+
+// restoringFactory records a restore origin on the first Create, as the
+// dependencies factory does when it restores a checkpoint.
+type restoringFactory struct {
+	origin trace.SpanContext
+	done   bool
+}
+
+func (f *restoringFactory) Create(session *Session, query string) (any, error) {
+	if !f.done {
+		session.AddRestoreOrigin(f.origin)
+		f.done = true
+	}
+	return nil, nil
+}
+
+func setupLoopTracer(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func spanAttr(s tracetest.SpanStub, key string) string {
+	for _, a := range s.Attributes {
+		if string(a.Key) == key {
+			return a.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestDefaultAgentLoop_Run_SpanTree(t *testing.T) {
+	exporter := setupLoopTracer(t)
+
+	registry := NewMockPhaseRegistry()
+	registry.RegisterPhase(StateInit, &tracedPhase{name: "init", next: StatePlan, runs: 1})
+	registry.RegisterPhase(StatePlan, &tracedPhase{name: "plan", next: StateExecute, runs: 1, children: []string{"llm.call"}})
+	registry.RegisterPhase(StateExecute, &tracedPhase{
+		name: "execute", next: StateComplete, runs: 2,
+		children: []string{"llm.call", "tool.call"},
+	})
+
+	origin := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{9, 9, 9},
+		SpanID:     trace.SpanID{7, 7, 7},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	loop := NewDefaultAgentLoop(
+		WithPhaseRegistry(registry),
+		WithDependenciesFactory(&restoringFactory{origin: origin}),
+	)
+	session, _ := NewSession("/test/project", nil)
+
+	result, err := loop.Run(context.Background(), session, "who calls main")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.State != StateComplete {
+		t.Fatalf("State = %s, want COMPLETE", result.State)
+	}
+
+	spans := exporter.GetSpans()
+	byID := make(map[trace.SpanID]tracetest.SpanStub, len(spans))
+	var run tracetest.SpanStub
+	for _, s := range spans {
+		byID[s.SpanContext.SpanID()] = s
+		if s.Name == "agent.DefaultAgentLoop.Run" {
+			run = s
+		}
+	}
+	if !run.SpanContext.IsValid() {
+		t.Fatal("run span not recorded")
+	}
+	if run.Parent.IsValid() {
+		t.Error("run span should be the root")
+	}
+	if !session.RunSpanContext().Equal(run.SpanContext) {
+		t.Error("session should record the run span context")
+	}
+	if spanAttr(run, "agent.state") != string(StateComplete) {
+		t.Errorf("run agent.state = %q", spanAttr(run, "agent.state"))
+	}
+
+	// The run span links to the restore origin.
+	if len(run.Links) != 1 || run.Links[0].SpanContext.SpanID() != origin.SpanID() {
+		t.Fatalf("run links = %+v, want one link to the restore origin", run.Links)
+	}
+
+	// Phases are children of the run span; LLM and tool spans are children
+	// of their phase.
+	var phases []string
+	children := make(map[string][]string)
+	for _, s := range spans {
+		if s.Name != "agent.DefaultAgentLoop.executePhase" {
+			continue
+		}
+		if s.Parent.SpanID() != run.SpanContext.SpanID() {
+			t.Errorf("phase %s is not a child of the run span", spanAttr(s, "phase.name"))
+		}
+		phases = append(phases, spanAttr(s, "phase.name")+"#"+spanAttr(s, "phase.iteration"))
+	}
+	for _, s := range spans {
+		parent, ok := byID[s.Parent.SpanID()]
+		if !ok || parent.Name != "agent.DefaultAgentLoop.executePhase" {
+			continue
+		}
+		key := spanAttr(parent, "phase.name") + "#" + spanAttr(parent, "phase.iteration")
+		children[key] = append(children[key], s.Name)
+	}
+
+	wantPhases := []string{"init#1", "plan#1", "execute#1", "execute#2"}
+	if len(phases) != len(wantPhases) {
+		t.Fatalf("phases = %v, want %v", phases, wantPhases)
+	}
+	for i := range wantPhases {
+		if phases[i] != wantPhases[i] {
+			t.Errorf("phase[%d] = %s, want %s", i, phases[i], wantPhases[i])
+		}
+	}
+	for phase, want := range map[string]int{"init#1": 0, "plan#1": 1, "execute#1": 2, "execute#2": 2} {
+		if got := len(children[phase]); got != want {
+			t.Errorf("%s has %d child spans (%v), want %d", phase, got, children[phase], want)
+		}
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -152,7 +153,7 @@ func (r *Runner) runAlgorithm(ctx context.Context, algo Algorithm, snapshot crs.
 		r.logger.Warn("algorithm failed",
 			slog.String("algorithm", name),
 			slog.Duration("duration", result.Duration),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 	} else {
 		r.logger.Debug("algorithm completed",
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -438,7 +439,7 @@ func (c *crsImpl) SetGraphProvider(provider GraphQuery) {
 	if c.graphProvider != nil && c.graphProvider != provider {
 		if err := c.graphProvider.Close(); err != nil {
 			c.logger.Warn("failed to close old graph provider",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 		}
 	}
@@ -450,7 +451,7 @@ func (c *crsImpl) SetGraphProvider(provider GraphQuery) {
 		depIndex, err := NewGraphBackedDependencyIndex(provider)
 		if err != nil {
 			c.logger.Error("failed to create graph-backed dependency index",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			c.graphBackedDepIndex = nil
 		} else {
@@ -722,7 +723,7 @@ func (c *crsImpl) SaveCheckpointToDisk(ctx context.Context, pm *PersistenceManag
 	if len(simDelta.Updates) > 0 {
 		if jErr := journal.Append(ctx, simDelta); jErr != nil {
 			c.logger.Warn("CRS-15: Failed to flush similarity to journal",
-				slog.String("error", jErr.Error()),
+				telemetry.SensitiveLogError("error", jErr),
 				slog.Int("pairs", len(simDelta.Updates)),
 			)
 		} else {
@@ -1518,7 +1519,7 @@ func (c *crsImpl) UpdateProofNumber(ctx context.Context, update ProofUpdate) err
 	// Start tracing
 	ctx, span := otel.Tracer("crs").Start(ctx, "crs.UpdateProofNumber",
 		trace.WithAttributes(
-			attribute.String("node_id", update.NodeID),
+			telemetry.SensitiveString("node_id", update.NodeID),
 			attribute.String("type", update.Type.String()),
 			attribute.Int64("delta", int64(update.Delta)),
 			attribute.String("source", update.Source.String()),
@@ -1702,7 +1703,7 @@ func (c *crsImpl) PropagateDisproof(ctx context.Context, nodeID string) int {
 	// Start tracing
 	ctx, span := otel.Tracer("crs").Start(ctx, "crs.PropagateDisproof",
 		trace.WithAttributes(
-			attribute.String("node_id", nodeID),
+			telemetry.SensitiveString("node_id", nodeID),
 		),
 	)
 	defer span.End()
@@ -1760,7 +1761,7 @@ func (c *crsImpl) PropagateDisproof(ctx context.Context, nodeID string) int {
 				c.logger.Warn("failed to update parent proof number",
 					slog.String("parent_id", parentID),
 					slog.String("child_id", current.id),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				continue
 			}
@@ -25,6 +25,8 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -195,7 +197,7 @@ func (d *GraphBackedDependencyIndex) DependsOn(nodeID string) []string {
 
 	ctx, span := graphDepIndexTracer.Start(ctx, "crs.GraphBackedDependencyIndex.DependsOn",
 		trace.WithAttributes(
-			attribute.String("node_id", nodeID),
+			telemetry.SensitiveString("node_id", nodeID),
 		),
 	)
 	defer span.End()
@@ -207,8 +209,8 @@ func (d *GraphBackedDependencyIndex) DependsOn(nodeID string) []string {
 		// GR-32 Code Review Fix: Log at Warn level for operational visibility
 		depIndexQueryErrors.WithLabelValues("depends_on").Inc()
 		d.logger.Warn("DependsOn failed",
-			slog.String("node_id", nodeID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("node_id", nodeID),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil
 	}
@@ -256,7 +258,7 @@ func (d *GraphBackedDependencyIndex) DependedBy(nodeID string) []string {
 
 	ctx, span := graphDepIndexTracer.Start(ctx, "crs.GraphBackedDependencyIndex.DependedBy",
 		trace.WithAttributes(
-			attribute.String("node_id", nodeID),
+			telemetry.SensitiveString("node_id", nodeID),
 		),
 	)
 	defer span.End()
@@ -268,8 +270,8 @@ func (d *GraphBackedDependencyIndex) DependedBy(nodeID string) []string {
 		// GR-32 Code Review Fix: Log at Warn level for operational visibility
 		depIndexQueryErrors.WithLabelValues("depended_by").Inc()
 		d.logger.Warn("DependedBy failed",
-			slog.String("node_id", nodeID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("node_id", nodeID),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil
 	}
@@ -318,7 +320,7 @@ func (d *GraphBackedDependencyIndex) HasCycle(nodeID string) bool {
 
 	ctx, span := graphDepIndexTracer.Start(ctx, "crs.GraphBackedDependencyIndex.HasCycle",
 		trace.WithAttributes(
-			attribute.String("node_id", nodeID),
+			telemetry.SensitiveString("node_id", nodeID),
 		),
 	)
 	defer span.End()
@@ -333,7 +335,7 @@ func (d *GraphBackedDependencyIndex) HasCycle(nodeID string) bool {
 	if !ok {
 		span.SetAttributes(attribute.Bool("cycle_check_supported", false))
 		d.logger.Debug("HasCycle not supported by adapter",
-			slog.String("node_id", nodeID),
+			telemetry.SensitiveLog("node_id", nodeID),
 		)
 		return false
 	}
@@ -345,8 +347,8 @@ func (d *GraphBackedDependencyIndex) HasCycle(nodeID string) bool {
 		// GR-32 Code Review Fix: Log at Warn level for operational visibility
 		depIndexQueryErrors.WithLabelValues("has_cycle").Inc()
 		d.logger.Warn("HasCycle failed",
-			slog.String("node_id", nodeID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("node_id", nodeID),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return false
 	}
@@ -414,7 +416,7 @@ func (d *GraphBackedDependencyIndex) Size() int {
 			// GR-32 Code Review Fix: Log at Warn level for operational visibility
 			depIndexQueryErrors.WithLabelValues("size").Inc()
 			d.logger.Warn("CallEdgeCount failed",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return 0, err
 		}
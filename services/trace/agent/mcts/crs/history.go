@@ -26,6 +26,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // ErrDeltaHistoryClosed is returned when querying a closed delta history worker.
@@ -266,7 +268,7 @@ func (w *DeltaHistoryWorker) run() {
 			deltaBytes, err := serializeDelta(req.delta)
 			if err != nil {
 				w.logger.Warn("failed to serialize delta for history",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 					slog.Int64("generation", req.gen),
 				)
 				continue
@@ -532,7 +534,7 @@ func (w *DeltaHistoryWorker) GetByNode(ctx context.Context, nodeID string) ([]De
 
 	ctx, span := otel.Tracer("crs").Start(ctx, "crs.DeltaHistory.GetByNode",
 		trace.WithAttributes(
-			attribute.String("node_id", nodeID),
+			telemetry.SensitiveString("node_id", nodeID),
 		),
 	)
 	defer span.End()
@@ -676,7 +678,7 @@ func (w *DeltaHistoryWorker) Explain(ctx context.Context, nodeID string) ([]Delt
 
 	ctx, span := otel.Tracer("crs").Start(ctx, "crs.DeltaHistory.Explain",
 		trace.WithAttributes(
-			attribute.String("node_id", nodeID),
+			telemetry.SensitiveString("node_id", nodeID),
 		),
 	)
 	defer span.End()
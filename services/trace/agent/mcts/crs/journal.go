@@ -25,6 +25,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	dgbadger "github.com/dgraph-io/badger/v4"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -365,7 +366,7 @@ func NewBadgerJournal(config JournalConfig) (*BadgerJournal, error) {
 		if config.AllowDegraded {
 			j.logger.Warn("BadgerDB unavailable, operating in degraded mode",
 				slog.String("path", config.Path),
-				slog.String("error", err.Error()))
+				telemetry.SensitiveLogError("error", err))
 			j.degraded.Store(true)
 			return j, nil
 		}
@@ -786,7 +787,7 @@ func (j *BadgerJournal) Replay(ctx context.Context) ([]Delta, error) {
 						if j.config.SkipCorruptedDeltas {
 							j.logger.Warn("skipping corrupted entry",
 								slog.Uint64("seq_num", seqNum),
-								slog.String("error", err.Error()))
+								telemetry.SensitiveLogError("error", err))
 							return nil
 						}
 					}
@@ -1016,7 +1017,7 @@ func (j *BadgerJournal) Checkpoint(ctx context.Context) error {
 
 	if err != nil {
 		span.RecordError(err)
-		j.logger.Warn("checkpoint truncation failed", slog.String("error", err.Error()))
+		j.logger.Warn("checkpoint truncation failed", telemetry.SensitiveLogError("error", err))
 		// Don't fail checkpoint if truncation fails - marker is saved
 	}
 
@@ -1091,7 +1092,7 @@ func (j *BadgerJournal) Close() error {
 
 	if j.db != nil {
 		if err := j.db.Sync(); err != nil {
-			j.logger.Warn("sync before close failed", slog.String("error", err.Error()))
+			j.logger.Warn("sync before close failed", telemetry.SensitiveLogError("error", err))
 		}
 		return j.db.Close()
 	}
@@ -31,6 +31,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // CRS-27a: Prometheus metrics for NATS journal operations.
@@ -205,7 +207,7 @@ func NewNATSJournal(config NATSJournalConfig) (*NATSJournal, error) {
 	if err := j.initSeqNum(); err != nil {
 		if config.AllowDegraded {
 			j.logger.Warn("CRS-27: NATS stream unavailable, operating in degraded mode",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			j.degraded.Store(true)
 			natsJournalDegraded.Set(1)
@@ -592,7 +594,7 @@ func (j *NATSJournal) Replay(ctx context.Context) ([]Delta, error) {
 			if j.config.SkipCorruptedDeltas {
 				j.logger.Warn("CRS-27: Skipping corrupted delta during replay",
 					slog.Uint64("seq", meta.Sequence.Stream),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				continue
 			}
@@ -775,7 +777,7 @@ func (j *NATSJournal) Checkpoint(ctx context.Context) error {
 	if err != nil {
 		j.logger.Warn("CRS-27: Failed to purge deltas after checkpoint",
 			slog.Uint64("checkpoint_seq", currentSeq),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		// Non-fatal — checkpoint is still recorded
 	}
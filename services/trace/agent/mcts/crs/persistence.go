@@ -33,6 +33,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -232,6 +234,12 @@ type BackupMetadata struct {
 	// ExportPath is the path to the companion JSON export (if created).
 	ExportPath string `json:"export_path,omitempty"`
 
+	// TraceID and SpanID identify the span that saved this backup, so a
+	// session restored from it can link back to the run that produced it.
+	// Empty if the save was not traced.
+	TraceID string `json:"trace_id,omitempty"`
+	SpanID  string `json:"span_id,omitempty"`
+
 	// MetadataHash is the SHA256 hash of this metadata (excluding this field).
 	// Used to detect metadata file corruption (P2 fix: I2).
 	MetadataHash string `json:"metadata_hash,omitempty"`
@@ -257,6 +265,26 @@ func (m *BackupMetadata) Age() time.Duration {
 	return time.Since(time.UnixMilli(m.CreatedAt))
 }
 
+// OriginSpanContext returns the span context of the span that saved the
+// backup, marked remote. Returns an invalid span context if none was
+// recorded.
+func (m *BackupMetadata) OriginSpanContext() trace.SpanContext {
+	traceID, err := trace.TraceIDFromHex(m.TraceID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	spanID, err := trace.SpanIDFromHex(m.SpanID)
+	if err != nil {
+		return trace.SpanContext{}
+	}
+	return trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+}
+
 // CompressionRatio returns the compression ratio.
 func (m *BackupMetadata) CompressionRatio() float64 {
 	if m.UncompressedSize == 0 {
@@ -507,7 +535,7 @@ func (pm *PersistenceManager) SaveBackup(ctx context.Context, projectHash string
 			pm.logger.Warn("backup attempt failed, retrying",
 				slog.Int("attempt", attempt+1),
 				slog.Int("max_retries", maxRetries),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			// Exponential backoff: 100ms, 200ms, 400ms...
 			time.Sleep(time.Duration(100<<attempt) * time.Millisecond)
@@ -633,7 +661,7 @@ func (pm *PersistenceManager) saveBackupOnce(ctx context.Context, projectHash st
 		if renameErr := os.Rename(backupPath, prevPath); renameErr != nil {
 			// Log but don't fail — losing the prev backup is acceptable.
 			logger.Warn("CRS-21: failed to rotate current checkpoint to prev",
-				slog.String("error", renameErr.Error()),
+				telemetry.SensitiveLogError("error", renameErr),
 			)
 		}
 	}
@@ -652,7 +680,7 @@ func (pm *PersistenceManager) saveBackupOnce(ctx context.Context, projectHash st
 	if err := syncDir(filepath.Dir(backupPath)); err != nil {
 		// Log but don't fail - the backup was written successfully
 		logger.Warn("directory sync failed (backup still valid)",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 	}
 
@@ -674,11 +702,15 @@ func (pm *PersistenceManager) saveBackupOnce(ctx context.Context, projectHash st
 	if opts != nil && opts.SessionID != "" {
 		metadata.SessionID = opts.SessionID
 	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		metadata.TraceID = sc.TraceID().String()
+		metadata.SpanID = sc.SpanID().String()
+	}
 
 	// Write metadata
 	if err := pm.writeMetadata(projectHash, metadata); err != nil {
 		logger.Warn("failed to write metadata",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		// Don't fail backup if metadata write fails
 	}
@@ -796,7 +828,7 @@ func (pm *PersistenceManager) LoadBackup(ctx context.Context, projectHash string
 		if err := coordinator.Pause(ctx); err != nil {
 			span.RecordError(err)
 			logger.Warn("failed to pause coordinator, continuing anyway",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 		} else {
 			coordinatorPaused = true
@@ -808,7 +840,7 @@ func (pm *PersistenceManager) LoadBackup(ctx context.Context, projectHash string
 			logger.Debug("resuming graph refresh coordinator")
 			if err := coordinator.Resume(ctx); err != nil {
 				logger.Warn("failed to resume coordinator",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 			}
 		}
@@ -857,7 +889,7 @@ func (pm *PersistenceManager) LoadBackup(ctx context.Context, projectHash string
 	}
 
 	logger.Warn("CRS-PERSIST-02: Current checkpoint failed, falling back to previous",
-		slog.String("error", loadErr.Error()),
+		telemetry.SensitiveLogError("error", loadErr),
 	)
 
 	metadata, fallbackErr := pm.loadBackupFromPath(ctx, projectHash, prevBackupPath, prevMetadataPath, journal, logger, span)
@@ -1094,7 +1126,7 @@ func (pm *PersistenceManager) writeMetadata(projectHash string, metadata *Backup
 		if renameErr := os.Rename(metaPath, prevMetaPath); renameErr != nil {
 			// Log but don't fail — losing the prev metadata is acceptable.
 			pm.logger.Warn("CRS-21: failed to rotate metadata to prev",
-				slog.String("error", renameErr.Error()),
+				telemetry.SensitiveLogError("error", renameErr),
 			)
 		}
 	}
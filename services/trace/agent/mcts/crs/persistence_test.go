@@ -19,6 +19,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 func TestPersistenceConfig_Validate(t *testing.T) {
@@ -1751,3 +1753,54 @@ func TestCRS21_NoPrevNoCrash(t *testing.T) {
 	}
 	// Should NOT panic — just return an error.
 }
+
+func TestPersistenceIntegration_RecordsOriginSpan(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	pm, err := NewPersistenceManager(&PersistenceConfig{
+		BaseDir:          t.TempDir(),
+		CompressionLevel: 6,
+		LockTimeoutSec:   30,
+	})
+	if err != nil {
+		t.Fatalf("NewPersistenceManager() error = %v", err)
+	}
+	defer pm.Close()
+
+	journal, err := NewBadgerJournal(JournalConfig{SessionID: "test-session-origin", InMemory: true})
+	if err != nil {
+		t.Fatalf("NewBadgerJournal error: %v", err)
+	}
+	defer journal.Close()
+
+	// With the default no-op tracer the save span inherits the run's span
+	// context, so the recorded origin is the run span itself.
+	run := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), run)
+	projectHash := "0123456789abcdef"
+	if _, err := pm.SaveBackup(ctx, projectHash, journal, nil); err != nil {
+		t.Fatalf("SaveBackup error: %v", err)
+	}
+
+	meta, err := pm.GetBackupMetadata(projectHash)
+	if err != nil {
+		t.Fatalf("GetBackupMetadata error: %v", err)
+	}
+	origin := meta.OriginSpanContext()
+	if !origin.IsValid() || origin.TraceID() != run.TraceID() || origin.SpanID() != run.SpanID() {
+		t.Errorf("origin = %v/%v, want %v/%v", origin.TraceID(), origin.SpanID(), run.TraceID(), run.SpanID())
+	}
+	if !origin.IsRemote() {
+		t.Error("origin should be marked remote")
+	}
+
+	if (&BackupMetadata{}).OriginSpanContext().IsValid() {
+		t.Error("metadata without trace IDs should have an invalid origin")
+	}
+}
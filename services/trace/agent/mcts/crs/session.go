@@ -30,6 +30,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -187,7 +189,7 @@ func NewSessionIdentifier(ctx context.Context, projectPath string) (*SessionIden
 	// Start tracing (GR-36 Code Review Fix: L1 - Add span)
 	ctx, span := sessionTracer.Start(ctx, "crs.NewSessionIdentifier",
 		trace.WithAttributes(
-			attribute.String("project_path", projectPath),
+			telemetry.SensitiveString("project_path", projectPath),
 		),
 	)
 	defer span.End()
@@ -350,8 +352,8 @@ func getGitCommitHash(projectPath string) string {
 	output, err := cmd.Output()
 	if err != nil {
 		slog.Debug("git commit hash unavailable",
-			slog.String("project_path", projectPath),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("project_path", projectPath),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return ""
 	}
@@ -392,6 +394,13 @@ type SessionRestorerConfig struct {
 	// Logger for session restore operations.
 	// If nil, uses slog.Default().
 	Logger *slog.Logger
+
+	// NodeIDMapper translates node IDs recorded in replayed deltas to the
+	// IDs of the current graph. Symbol IDs embed line numbers, so without
+	// it state recorded against a symbol is lost once the symbol moves.
+	// Typically resolves line-based and stable IDs to the current node
+	// ID, returning unknown IDs unchanged. If nil, IDs are replayed as-is.
+	NodeIDMapper func(id string) string
 }
 
 // DefaultSessionRestorerConfig returns production defaults.
@@ -461,6 +470,10 @@ type RestoreResult struct {
 
 	// DurationMs is how long the restore took in milliseconds.
 	DurationMs int64 `json:"duration_ms"`
+
+	// Origin is the span that saved the restored checkpoint. Invalid if
+	// not restored or the checkpoint predates trace recording.
+	Origin trace.SpanContext `json:"-"`
 }
 
 // -----------------------------------------------------------------------------
@@ -580,7 +593,7 @@ func (r *SessionRestorer) TryRestore(
 	// Start tracing (GR-36 Code Review Fix: L1 - Add OTel span)
 	ctx, span := sessionTracer.Start(ctx, "crs.SessionRestorer.TryRestore",
 		trace.WithAttributes(
-			attribute.String("project_path", sessionID.ProjectPath),
+			telemetry.SensitiveString("project_path", sessionID.ProjectPath),
 			attribute.String("project_hash", sessionID.ProjectHash),
 			attribute.String("checkpoint_key", sessionID.CheckpointKey()),
 		),
@@ -636,7 +649,7 @@ retryLoop:
 			logger.Warn("restore attempt failed, retrying",
 				slog.Int("attempt", attempt+1),
 				slog.Int("max_retries", r.config.MaxRetries),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.Duration("backoff", backoff),
 			)
 			// NEW-13 Fix: Respect context cancellation during backoff
@@ -700,7 +713,7 @@ func (r *SessionRestorer) tryRestoreOnce(
 	if err := r.validateCheckpoint(ctx, metadata, sessionID); err != nil {
 		sessionRestoreTotal.WithLabelValues("incompatible").Inc()
 		logger.Warn("checkpoint incompatible",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.Int64("checkpoint_generation", metadata.Generation),
 		)
 		return &RestoreResult{
@@ -745,6 +758,10 @@ func (r *SessionRestorer) tryRestoreOnce(
 			}
 		}
 
+		if r.config.NodeIDMapper != nil {
+			remapDeltaNodeIDs(delta, r.config.NodeIDMapper)
+		}
+
 		if _, err := crsi.Apply(ctx, delta); err != nil {
 			sessionRestoreTotal.WithLabelValues("apply_error").Inc()
 			span.RecordError(err)
@@ -763,7 +780,7 @@ func (r *SessionRestorer) tryRestoreOnce(
 	if err := journal.Checkpoint(ctx); err != nil {
 		// Log but don't fail - restore succeeded, checkpoint is optimization
 		logger.Warn("failed to checkpoint journal after restore",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 	}
 
@@ -794,9 +811,40 @@ func (r *SessionRestorer) tryRestoreOnce(
 		CheckpointTime: loadedMeta.CreatedAt,
 		CheckpointAge:  loadedMeta.Age(),
 		Reason:         "success",
+		Origin:         loadedMeta.OriginSpanContext(),
 	}, nil
 }
 
+// remapDeltaNodeIDs rewrites the node IDs in a replayed delta in place.
+// Deltas without node IDs are left unchanged.
+func remapDeltaNodeIDs(delta Delta, mapID func(string) string) {
+	switch d := delta.(type) {
+	case *ProofDelta:
+		updates := make(map[string]ProofNumber, len(d.Updates))
+		for id, pn := range d.Updates {
+			updates[mapID(id)] = pn
+		}
+		d.Updates = updates
+	case *SimilarityDelta:
+		updates := make(map[[2]string]float64, len(d.Updates))
+		for pair, dist := range d.Updates {
+			updates[[2]string{mapID(pair[0]), mapID(pair[1])}] = dist
+		}
+		d.Updates = updates
+	case *DependencyDelta:
+		for i, edge := range d.AddEdges {
+			d.AddEdges[i] = [2]string{mapID(edge[0]), mapID(edge[1])}
+		}
+		for i, edge := range d.RemoveEdges {
+			d.RemoveEdges[i] = [2]string{mapID(edge[0]), mapID(edge[1])}
+		}
+	case *CompositeDelta:
+		for _, inner := range d.Deltas {
+			remapDeltaNodeIDs(inner, mapID)
+		}
+	}
+}
+
 // validateCheckpoint validates checkpoint compatibility.
 //
 // GR-36 Code Review Fix: R2 - Safe type assertion.
@@ -876,7 +924,7 @@ func FindFilesModifiedSince(
 
 	ctx, span := sessionTracer.Start(ctx, "crs.FindFilesModifiedSince",
 		trace.WithAttributes(
-			attribute.String("project_path", projectPath),
+			telemetry.SensitiveString("project_path", projectPath),
 			attribute.String("since", since.Format(time.RFC3339)),
 		),
 	)
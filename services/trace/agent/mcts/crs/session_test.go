@@ -1067,3 +1067,30 @@ func TestTryRestore_ContextCancellationDuringBackoff(t *testing.T) {
 		t.Error("should not restore with cancelled context")
 	}
 }
+
+func TestRemapDeltaNodeIDs(t *testing.T) {
+	mapID := func(id string) string {
+		if id == "a.go:10:Foo" {
+			return "a.go:12:Foo"
+		}
+		return id
+	}
+
+	proof := NewProofDelta(SignalSourceHard, map[string]ProofNumber{"a.go:10:Foo": {Proof: 1}})
+	sim := NewSimilarityDelta(SignalSourceSoft)
+	sim.Updates[[2]string{"a.go:10:Foo", "b.go:3:Bar"}] = 0.25
+	dep := NewDependencyDelta(SignalSourceHard)
+	dep.AddEdges = append(dep.AddEdges, [2]string{"b.go:3:Bar", "a.go:10:Foo"})
+
+	remapDeltaNodeIDs(NewCompositeDelta(proof, sim, dep), mapID)
+
+	if _, ok := proof.Updates["a.go:12:Foo"]; !ok || len(proof.Updates) != 1 {
+		t.Errorf("proof updates = %v", proof.Updates)
+	}
+	if sim.Updates[[2]string{"a.go:12:Foo", "b.go:3:Bar"}] != 0.25 {
+		t.Errorf("similarity updates = %v", sim.Updates)
+	}
+	if dep.AddEdges[0] != [2]string{"b.go:3:Bar", "a.go:12:Foo"} {
+		t.Errorf("dependency edges = %v", dep.AddEdges)
+	}
+}
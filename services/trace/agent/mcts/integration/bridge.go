@@ -20,6 +20,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/activities"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -313,7 +314,7 @@ func (b *Bridge) RunActivity(
 		b.logger.Debug("activity apply conflict, retrying",
 			slog.String("activity", activity.Name()),
 			slog.Int("attempt", attempt+1),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 	}
 
@@ -361,7 +362,7 @@ func (b *Bridge) runActivityOnce(
 				if jErr := journal.Append(ctx, delta); jErr != nil {
 					b.logger.Warn("journal append failed",
 						slog.String("activity", activity.Name()),
-						slog.String("error", jErr.Error()),
+						telemetry.SensitiveLogError("error", jErr),
 					)
 				}
 			}
@@ -21,6 +21,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/activities"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -346,7 +347,7 @@ func (c *Coordinator) RunOnce(ctx context.Context) ([]activities.ActivityResult,
 	for err := range errorsCh {
 		// Extract trace context for correlation
 		spanCtx := trace.SpanContextFromContext(ctx)
-		attrs := []any{slog.String("error", err.Error())}
+		attrs := []any{telemetry.SensitiveLogError("error", err)}
 		if spanCtx.IsValid() {
 			attrs = append(attrs,
 				slog.String("trace_id", spanCtx.TraceID().String()),
@@ -389,7 +390,7 @@ func (c *Coordinator) Run(ctx context.Context) error {
 			_, err := c.RunOnce(ctx)
 			if err != nil && !errors.Is(err, context.Canceled) {
 				c.logger.Warn("run cycle failed",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 			}
 		}
@@ -667,13 +668,13 @@ func (c *Coordinator) HandleEvent(
 			if isOptional {
 				c.logger.Warn("optional activity failed",
 					slog.String("activity", sa.activity.Name()),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				// Continue with other activities
 			} else {
 				c.logger.Error("required activity failed",
 					slog.String("activity", sa.activity.Name()),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				return results, &CoordinatorError{
 					Operation: "HandleEvent." + sa.activity.Name(),
@@ -15,6 +15,8 @@ import (
 	"log/slog"
 	"sync"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -583,7 +585,7 @@ func (b *GraphContextBuilder) Build() (*GraphContext, error) {
 	if err := b.ctx.Validate(); err != nil {
 		// R-1 fix: Release context back to pool on validation failure
 		slog.Debug("GraphContext validation failed, releasing to pool",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		ReleaseGraphContext(b.ctx)
 		b.ctx = nil
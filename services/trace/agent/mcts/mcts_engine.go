@@ -18,6 +18,8 @@ import (
 	"time"
 
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // MCTSEngine implements the full MCTS algorithm for plan tree exploration.
@@ -226,7 +228,7 @@ func (e *MCTSEngine) Run(ctx context.Context, task string, budget *TreeBudget) (
 		if err := e.runIteration(ctx, tree, budget, iteration); err != nil {
 			e.logger.Warn("iteration failed",
 				slog.Int("iteration", iteration),
-				slog.String("error", err.Error()))
+				telemetry.SensitiveLogError("error", err))
 			// Continue with next iteration
 		}
 
@@ -284,7 +286,7 @@ func (e *MCTSEngine) runIteration(ctx context.Context, tree *PlanTree, budget *T
 			// Expansion failed, continue with simulation of current node
 			e.logger.Debug("expansion failed",
 				slog.String("node", leaf.ID),
-				slog.String("error", err.Error()))
+				telemetry.SensitiveLogError("error", err))
 		} else if leaf.ChildCount() > 0 {
 			// Select a child for simulation
 			child := e.selectionPolicy.Select(leaf)
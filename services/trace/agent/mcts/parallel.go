@@ -17,6 +17,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // ParallelMCTSEngine runs multiple MCTS iterations concurrently using virtual loss.
@@ -204,7 +206,7 @@ func (p *ParallelMCTSEngine) worker(
 		if err != nil {
 			p.logger.Debug("worker iteration failed",
 				slog.Int("worker", workerID),
-				slog.String("error", err.Error()))
+				telemetry.SensitiveLogError("error", err))
 		}
 
 		completedIterations.Add(1)
@@ -417,7 +419,7 @@ func (l *LeafParallelMCTSEngine) Run(ctx context.Context, task string, budget *T
 		if err := l.runIterationLeafParallel(ctx, tree, budget); err != nil {
 			l.logger.Debug("iteration failed",
 				slog.Int("iteration", iteration),
-				slog.String("error", err.Error()))
+				telemetry.SensitiveLogError("error", err))
 		}
 
 		iteration++
@@ -0,0 +1,157 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+// observedSymbolsMaxDepth bounds how deep a tool output is searched.
+const observedSymbolsMaxDepth = 8
+
+// symbolIDKeys are output fields holding a symbol ID.
+var symbolIDKeys = []string{"id", "symbol_id", "target_id", "caller_id", "callee_id"}
+
+// symbolFileKeys and symbolLineKeys locate symbols reported without an ID.
+var (
+	symbolFileKeys = []string{"file", "file_path"}
+	symbolLineKeys = []string{"line", "start_line"}
+)
+
+// observeCitations adds the symbols in a successful tool result to the
+// session's citation set.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	inv - The tool invocation.
+//	result - The tool result. Ignored if nil or unsuccessful.
+func observeCitations(deps *Dependencies, inv *agent.ToolInvocation, result *tools.Result) {
+	if deps.Session == nil || result == nil || !result.Success || result.Output == nil {
+		return
+	}
+	symbols := observedSymbols(result.Output)
+	if len(symbols) == 0 {
+		return
+	}
+	deps.Session.Citations().Observe(inv.Tool, inv.StepNumber, symbols)
+}
+
+// observedSymbols finds the symbols a tool output reports.
+//
+// Description:
+//
+//	Tool outputs are typed per tool, so the output is searched through
+//	its JSON form: objects with a symbol ID field ("id", "target_id", ...)
+//	whose value looks like "file:line:name", and objects with a name,
+//	file and line, which are given the ID "file:line:name".
+//
+// Inputs:
+//
+//	output - The tool result's Output.
+//
+// Outputs:
+//
+//	[]agent.ObservedSymbol - The symbols, in output order (object fields
+//	                         in key order).
+func observedSymbols(output any) []agent.ObservedSymbol {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil
+	}
+	var symbols []agent.ObservedSymbol
+	collectObservedSymbols(generic, 0, &symbols)
+	return symbols
+}
+
+// collectObservedSymbols walks a decoded JSON value for symbols.
+func collectObservedSymbols(v any, depth int, out *[]agent.ObservedSymbol) {
+	if depth > observedSymbolsMaxDepth || len(*out) >= agent.MaxCitations {
+		return
+	}
+	switch val := v.(type) {
+	case []any:
+		for _, item := range val {
+			collectObservedSymbols(item, depth+1, out)
+		}
+	case map[string]any:
+		if sym, ok := observedSymbolOf(val); ok {
+			*out = append(*out, sym)
+		}
+		keys := make([]string, 0, len(val))
+		for key := range val {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			switch val[key].(type) {
+			case []any, map[string]any:
+				collectObservedSymbols(val[key], depth+1, out)
+			}
+		}
+	}
+}
+
+// observedSymbolOf reads a symbol from a single output object.
+func observedSymbolOf(obj map[string]any) (agent.ObservedSymbol, bool) {
+	for _, key := range symbolIDKeys {
+		if id, ok := obj[key].(string); ok && strings.Count(id, ":") >= 2 {
+			return agent.ObservedSymbol{ID: id}, true
+		}
+	}
+	name, _ := obj["name"].(string)
+	if name == "" {
+		return agent.ObservedSymbol{}, false
+	}
+	for _, fileKey := range symbolFileKeys {
+		file, _ := obj[fileKey].(string)
+		if file == "" {
+			continue
+		}
+		for _, lineKey := range symbolLineKeys {
+			if line, ok := obj[lineKey].(float64); ok && line > 0 {
+				return agent.ObservedSymbol{
+					ID:   fmt.Sprintf("%s:%d:%s", file, int(line), name),
+					File: file,
+					Line: int(line),
+				}, true
+			}
+		}
+	}
+	return agent.ObservedSymbol{}, false
+}
+
+// appendCitationPrompt adds the citable symbols and citation rules to an
+// answer-generation request. No-op before any symbol was observed.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	request - The LLM request. Modified in place.
+func appendCitationPrompt(deps *Dependencies, request *llm.Request) {
+	if deps.Session == nil || request == nil {
+		return
+	}
+	if block := deps.Session.Citations().PromptBlock(); block != "" {
+		request.SystemPrompt += block
+	}
+}
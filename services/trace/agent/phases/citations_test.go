@@ -0,0 +1,70 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+func TestObserveCitations(t *testing.T) {
+	type caller struct {
+		Name string `json:"name"`
+		File string `json:"file"`
+		Line int    `json:"line"`
+	}
+	type callerResult struct {
+		TargetID string   `json:"target_id"`
+		Callers  []caller `json:"callers"`
+	}
+	output := struct {
+		Results []callerResult `json:"results"`
+	}{
+		Results: []callerResult{{
+			TargetID: "pkg/auth/jwt.go:10:ValidateToken",
+			Callers: []caller{
+				{Name: "Middleware", File: "pkg/auth/middleware.go", Line: 22},
+				{Name: "noLine", File: "pkg/auth/x.go"},
+			},
+		}},
+	}
+
+	session, err := agent.NewSession("/proj", nil)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	deps := &Dependencies{Session: session}
+	inv := &agent.ToolInvocation{Tool: "find_callers", StepNumber: 4}
+
+	observeCitations(deps, inv, &tools.Result{Success: true, Output: output})
+	observeCitations(deps, inv, &tools.Result{Success: false, Output: map[string]any{"id": "f.go:1:Failed"}})
+
+	entries := session.Citations().Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 citations, got %+v", entries)
+	}
+	if entries[0].SymbolID != "pkg/auth/jwt.go:10:ValidateToken" || entries[0].Tool != "find_callers" || entries[0].Step != 4 {
+		t.Errorf("unexpected target citation: %+v", entries[0])
+	}
+	if entries[1].SymbolID != "pkg/auth/middleware.go:22:Middleware" || entries[1].Line != 22 {
+		t.Errorf("unexpected caller citation: %+v", entries[1])
+	}
+
+	request := &llm.Request{SystemPrompt: "base"}
+	appendCitationPrompt(deps, request)
+	if !strings.HasPrefix(request.SystemPrompt, "base") || !strings.Contains(request.SystemPrompt, "[1] pkg/auth/jwt.go:10:ValidateToken") {
+		t.Errorf("citation block not appended:\n%s", request.SystemPrompt)
+	}
+}
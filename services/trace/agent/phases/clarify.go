@@ -17,6 +17,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
 )
 
 // ClarifyPhase handles user clarification requests.
@@ -166,15 +167,23 @@ func (p *ClarifyPhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 		return agent.StateError, err
 	}
 
+	// Continue leaves the user's answer on the session
+	if resp, ok := deps.Session.TakeClarificationResponse(); ok && resp.Text != "" {
+		return p.processClarification(ctx, deps, resp)
+	}
+
 	// Check if we have clarification input (thread-safe access)
 	if p.getClarificationInput() == "" {
-		// No input yet - return to signal we're awaiting clarification
-		// The agent loop should pause and wait for user input
+		// No input yet - publish the request and signal we're awaiting
+		// clarification. The agent loop should pause and wait for user input
+		req := p.buildClarifyRequest(deps)
+		deps.Session.SetPendingClarification(req)
+		p.emitClarifyOptions(deps, req)
 		return agent.StateClarify, agent.ErrAwaitingClarification
 	}
 
 	// Process the clarification
-	return p.processClarification(ctx, deps)
+	return p.processClarification(ctx, deps, agent.ClarificationResponse{Text: p.getAndClearClarification()})
 }
 
 // validateDependencies checks that required dependencies are present.
@@ -202,23 +211,33 @@ func (p *ClarifyPhase) validateDependencies(deps *Dependencies) error {
 //
 //	ctx - Context for cancellation.
 //	deps - Phase dependencies.
+//	resp - The user's answer, typed or a selected option.
 //
 // Outputs:
 //
 //	agent.AgentState - PLAN to re-analyze with clarification.
 //	error - Non-nil if processing fails.
-func (p *ClarifyPhase) processClarification(ctx context.Context, deps *Dependencies) (agent.AgentState, error) {
-	// Get the clarification (thread-safe) and clear it atomically
-	clarification := p.getAndClearClarification()
+func (p *ClarifyPhase) processClarification(ctx context.Context, deps *Dependencies, resp agent.ClarificationResponse) (agent.AgentState, error) {
+	clarification := resp.Text
+	deps.Session.SetPendingClarification(nil)
 
 	// Add clarification to conversation history
 	p.addClarificationToContext(deps, clarification)
 
+	// A typed answer may confirm or correct something worth keeping
+	if resp.OptionID == "" {
+		proposeFact(ctx, deps, clarification, facts.ConfirmedByClarification)
+	}
+
 	// Emit context update event
 	p.emitContextUpdate(deps, clarification)
 
 	// Transition back to PLAN to re-analyze with new information
-	p.emitStateTransition(deps, agent.StateClarify, agent.StatePlan, "clarification received")
+	reason := "clarification received"
+	if resp.OptionID != "" {
+		reason = "clarification option " + resp.OptionID + " selected"
+	}
+	p.emitStateTransition(deps, agent.StateClarify, agent.StatePlan, reason)
 
 	return agent.StatePlan, nil
 }
@@ -0,0 +1,225 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+)
+
+// maxClarifySnippetLen bounds the snippet of a clarification option.
+const maxClarifySnippetLen = 160
+
+// clarifySymbolIDPattern matches symbol IDs ("path:line:name", see
+// ast.GenerateID) in tool output.
+var clarifySymbolIDPattern = regexp.MustCompile(`[\w./-]+\.\w+:\d+:[\w.$]+`)
+
+// clarifyCandidate is a symbol referenced by a tool result.
+type clarifyCandidate struct {
+	id       string
+	name     string
+	kind     string
+	filePath string
+	line     int
+	snippet  string
+}
+
+// buildClarifyRequest builds the clarification request for the session.
+//
+// Description:
+//
+//	Derives options from the session's tool results (see
+//	deriveClarificationOptions). With options and no explicit prompt, the
+//	question names the ambiguous symbol; without options the request is
+//	the plain-text prompt.
+//
+// Inputs:
+//
+//	deps - Phase dependencies. Must not be nil.
+//
+// Outputs:
+//
+//	*agent.ClarifyRequest - The request. Never nil.
+func (p *ClarifyPhase) buildClarifyRequest(deps *Dependencies) *agent.ClarifyRequest {
+	name, options := deriveClarificationOptions(deps)
+
+	question := deps.Session.GetClarificationPrompt()
+	if question == "" && len(options) > 0 {
+		question = fmt.Sprintf("Which %s do you mean?", name)
+	}
+	if question == "" {
+		question = p.defaultPrompt
+	}
+	return &agent.ClarifyRequest{Question: question, Options: options}
+}
+
+// deriveClarificationOptions turns ambiguous tool results into options.
+//
+// Description:
+//
+//	Walks the context's tool results newest first. A result is ambiguous
+//	when it references two or more symbols with the same name, e.g. a
+//	find_symbol call that matched Handler in pkg/auth and in pkg/admin.
+//	The symbols of the first such name in the newest ambiguous result
+//	become the options, at most agent.MaxClarificationOptions. Symbols are
+//	looked up in the symbol index when available for their kind and
+//	signature; otherwise the ID and the output line are used.
+//
+// Inputs:
+//
+//	deps - Phase dependencies. Must not be nil.
+//
+// Outputs:
+//
+//	string - The ambiguous symbol name, empty if there are no options.
+//	[]agent.ClarificationOption - The options, nil if none were derived.
+func deriveClarificationOptions(deps *Dependencies) (string, []agent.ClarificationOption) {
+	if deps.Context == nil {
+		return "", nil
+	}
+
+	for i := len(deps.Context.ToolResults) - 1; i >= 0; i-- {
+		result := deps.Context.ToolResults[i]
+		if !result.Success {
+			continue
+		}
+		name, candidates := ambiguousCandidates(deps, result.Output)
+		if len(candidates) < 2 {
+			continue
+		}
+		if len(candidates) > agent.MaxClarificationOptions {
+			candidates = candidates[:agent.MaxClarificationOptions]
+		}
+		options := make([]agent.ClarificationOption, len(candidates))
+		for j, c := range candidates {
+			label := c.name
+			if c.kind != "" {
+				label = fmt.Sprintf("%s (%s)", c.name, c.kind)
+			}
+			options[j] = agent.ClarificationOption{
+				ID:       fmt.Sprintf("opt_%d", j+1),
+				Label:    fmt.Sprintf("%s in %s", label, c.filePath),
+				SymbolID: c.id,
+				FilePath: c.filePath,
+				Line:     c.line,
+				Snippet:  c.snippet,
+			}
+		}
+		return name, options
+	}
+	return "", nil
+}
+
+// ambiguousCandidates returns the symbols of the first name that output
+// references with two or more distinct IDs, in output order.
+func ambiguousCandidates(deps *Dependencies, output string) (string, []clarifyCandidate) {
+	var order []string
+	byName := make(map[string][]clarifyCandidate)
+	seen := make(map[string]bool)
+
+	for _, line := range strings.Split(output, "\n") {
+		for _, id := range clarifySymbolIDPattern.FindAllString(line, -1) {
+			if seen[id] {
+				continue
+			}
+			c, ok := newClarifyCandidate(deps, id, line)
+			if !ok {
+				continue
+			}
+			seen[id] = true
+			if _, exists := byName[c.name]; !exists {
+				order = append(order, c.name)
+			}
+			byName[c.name] = append(byName[c.name], c)
+		}
+	}
+
+	for _, name := range order {
+		if len(byName[name]) >= 2 {
+			return name, byName[name]
+		}
+	}
+	return "", nil
+}
+
+// newClarifyCandidate describes the symbol with the given ID, found on
+// line of a tool output.
+func newClarifyCandidate(deps *Dependencies, id, line string) (clarifyCandidate, bool) {
+	if deps.SymbolIndex != nil {
+		if sym, ok := deps.SymbolIndex.GetByID(id); ok {
+			snippet := sym.Signature
+			if snippet == "" {
+				snippet = line
+			}
+			return clarifyCandidate{
+				id:       sym.ID,
+				name:     sym.Name,
+				kind:     sym.Kind.String(),
+				filePath: sym.FilePath,
+				line:     sym.StartLine,
+				snippet:  clarifySnippet(snippet),
+			}, true
+		}
+	}
+
+	// Fall back to the ID's own parts: path:line:name
+	nameSep := strings.LastIndex(id, ":")
+	if nameSep < 0 {
+		return clarifyCandidate{}, false
+	}
+	lineSep := strings.LastIndex(id[:nameSep], ":")
+	if lineSep < 0 {
+		return clarifyCandidate{}, false
+	}
+	lineNo, err := strconv.Atoi(id[lineSep+1 : nameSep])
+	if err != nil {
+		return clarifyCandidate{}, false
+	}
+	return clarifyCandidate{
+		id:       id,
+		name:     id[nameSep+1:],
+		filePath: id[:lineSep],
+		line:     lineNo,
+		snippet:  clarifySnippet(line),
+	}, true
+}
+
+// clarifySnippet trims s to one short line.
+func clarifySnippet(s string) string {
+	s = strings.TrimSpace(s)
+	if runes := []rune(s); len(runes) > maxClarifySnippetLen {
+		s = string(runes[:maxClarifySnippetLen]) + "..."
+	}
+	return s
+}
+
+// emitClarifyOptions emits a clarify_options event when the request has
+// options.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	req - The clarification request.
+func (p *ClarifyPhase) emitClarifyOptions(deps *Dependencies, req *agent.ClarifyRequest) {
+	if deps.EventEmitter == nil || len(req.Options) == 0 {
+		return
+	}
+
+	deps.EventEmitter.Emit(events.TypeClarifyOptions, &events.ClarifyOptionsData{
+		Question: req.Question,
+		Options:  req.Options,
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	agentcontext "github.com/AleutianAI/AleutianFOSS/services/trace/agent/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// ambiguousFindSymbolOutput is find_symbol output matching two Handlers.
+const ambiguousFindSymbolOutput = `Found 2 symbols matching 'Handler':
+
+• Handler (function)
+  Location: pkg/auth/handler.go:12
+  Package: auth
+  ID: pkg/auth/handler.go:12:Handler
+
+• Handler (function)
+  Location: pkg/admin/handler.go:40
+  Package: admin
+  ID: pkg/admin/handler.go:40:Handler
+`
+
+// clarifyDeps returns dependencies whose context holds the given tool
+// outputs, oldest first, and the clarify_options events emitted.
+func clarifyDeps(outputs ...string) (*Dependencies, *[]*events.Event) {
+	deps := createTestDependencies()
+	deps.Context = &agent.AssembledContext{}
+	for _, out := range outputs {
+		deps.Context.ToolResults = append(deps.Context.ToolResults, agent.ToolResult{Success: true, Output: out})
+	}
+	var got []*events.Event
+	deps.EventEmitter.Subscribe(func(e *events.Event) {
+		got = append(got, e)
+	}, events.TypeClarifyOptions)
+	return deps, &got
+}
+
+func TestClarifyPhase_Execute_DerivesOptions(t *testing.T) {
+	deps, got := clarifyDeps(ambiguousFindSymbolOutput, "Found 1 symbols matching 'Login':\n  ID: pkg/auth/login.go:3:Login\n")
+
+	_, err := NewClarifyPhase().Execute(context.Background(), deps)
+	if !errors.Is(err, agent.ErrAwaitingClarification) {
+		t.Fatalf("Execute error = %v, want ErrAwaitingClarification", err)
+	}
+
+	req := deps.Session.PendingClarification()
+	if req == nil {
+		t.Fatal("no pending clarification on the session")
+	}
+	if req.Question != "Which Handler do you mean?" {
+		t.Errorf("Question = %q", req.Question)
+	}
+	want := []agent.ClarificationOption{
+		{ID: "opt_1", Label: "Handler in pkg/auth/handler.go", SymbolID: "pkg/auth/handler.go:12:Handler", FilePath: "pkg/auth/handler.go", Line: 12, Snippet: "ID: pkg/auth/handler.go:12:Handler"},
+		{ID: "opt_2", Label: "Handler in pkg/admin/handler.go", SymbolID: "pkg/admin/handler.go:40:Handler", FilePath: "pkg/admin/handler.go", Line: 40, Snippet: "ID: pkg/admin/handler.go:40:Handler"},
+	}
+	if len(req.Options) != len(want) {
+		t.Fatalf("Options = %+v, want %d", req.Options, len(want))
+	}
+	for i := range want {
+		if req.Options[i] != want[i] {
+			t.Errorf("Options[%d] = %+v, want %+v", i, req.Options[i], want[i])
+		}
+	}
+
+	if len(*got) != 1 {
+		t.Fatalf("clarify_options events = %d, want 1", len(*got))
+	}
+	data, ok := (*got)[0].Data.(*events.ClarifyOptionsData)
+	if !ok || len(data.Options) != 2 || data.Question != req.Question {
+		t.Errorf("event data = %+v", (*got)[0].Data)
+	}
+}
+
+func TestClarifyPhase_Execute_OptionsFromIndex(t *testing.T) {
+	deps, _ := clarifyDeps(ambiguousFindSymbolOutput)
+	deps.SymbolIndex = index.NewSymbolIndex()
+	sym := &ast.Symbol{
+		ID: "pkg/auth/handler.go:12:Handler", Name: "Handler", Kind: ast.SymbolKindFunction,
+		FilePath: "pkg/auth/handler.go", StartLine: 12, EndLine: 20, Language: "go",
+		Signature: "func Handler(w http.ResponseWriter, r *http.Request)",
+	}
+	if err := deps.SymbolIndex.Add(sym); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	_, options := deriveClarificationOptions(deps)
+	if len(options) != 2 {
+		t.Fatalf("options = %+v, want 2", options)
+	}
+	if options[0].Label != "Handler (function) in pkg/auth/handler.go" || options[0].Snippet != sym.Signature {
+		t.Errorf("indexed option = %+v", options[0])
+	}
+	if options[1].Label != "Handler in pkg/admin/handler.go" {
+		t.Errorf("unindexed option = %+v", options[1])
+	}
+}
+
+func TestClarifyPhase_Execute_PlainTextFallback(t *testing.T) {
+	deps, got := clarifyDeps("Found 1 symbols matching 'Login':\n  ID: pkg/auth/login.go:3:Login\n")
+
+	_, err := NewClarifyPhase(WithDefaultPrompt("Which module?")).Execute(context.Background(), deps)
+	if !errors.Is(err, agent.ErrAwaitingClarification) {
+		t.Fatalf("Execute error = %v, want ErrAwaitingClarification", err)
+	}
+	req := deps.Session.PendingClarification()
+	if req == nil || req.Question != "Which module?" || len(req.Options) != 0 {
+		t.Errorf("pending clarification = %+v, want plain question", req)
+	}
+	if len(*got) != 0 {
+		t.Errorf("clarify_options emitted without options")
+	}
+}
+
+func TestClarifyPhase_Execute_SessionResponse(t *testing.T) {
+	deps, _ := clarifyDeps(ambiguousFindSymbolOutput)
+	deps.ContextManager = &agentcontext.Manager{}
+	phase := NewClarifyPhase()
+	if _, err := phase.Execute(context.Background(), deps); !errors.Is(err, agent.ErrAwaitingClarification) {
+		t.Fatalf("Execute error = %v", err)
+	}
+
+	opt, ok := deps.Session.ClarificationOption("opt_2")
+	if !ok {
+		t.Fatal("opt_2 not offered")
+	}
+	deps.Session.SetClarificationResponse(agent.ClarificationResponse{Text: opt.Text(), OptionID: opt.ID})
+
+	nextState, err := phase.Execute(context.Background(), deps)
+	if err != nil || nextState != agent.StatePlan {
+		t.Fatalf("Execute = %s, %v, want PLAN", nextState, err)
+	}
+	if deps.Session.PendingClarification() != nil {
+		t.Error("pending clarification not cleared")
+	}
+	if _, ok := deps.Session.TakeClarificationResponse(); ok {
+		t.Error("response not taken")
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+)
+
+const (
+	// overflowMinResultChars is the least a narrowed tool result keeps.
+	overflowMinResultChars = 500
+
+	// overflowHint asks the model for narrower tool calls after the
+	// context was narrowed.
+	overflowHint = "[The last request did not fit the model's context window (~%d of %d tokens). " +
+		"Older tool results were dropped and the rest shortened. Use narrower tool calls: " +
+		"a specific symbol, file or package, and smaller limits.]"
+)
+
+// handleContextOverflow hands an overflowing LLM call to the reflect phase.
+//
+// Description:
+//
+//	The provider layer already dropped code context and condensed
+//	history; what is left is mostly tool output. When there is some, the
+//	overflow is recorded on the session and the phase moves to REFLECT,
+//	which narrows the tool results and retries.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	overflowErr - The provider's overflow error.
+//
+// Outputs:
+//
+//	agent.AgentState - REFLECT when the context can be narrowed.
+//	bool - False when there are no tool results to narrow.
+func (p *ExecutePhase) handleContextOverflow(deps *Dependencies, overflowErr *llm.ContextOverflowError) (agent.AgentState, bool) {
+	if deps.Context == nil || len(deps.Context.ToolResults) == 0 {
+		return "", false
+	}
+	slog.Warn("Prompt exceeds the context window, narrowing tool results",
+		slog.String("session_id", deps.Session.ID),
+		slog.String("model", overflowErr.Model),
+		slog.Int("estimated_tokens", overflowErr.EstimatedTokens),
+		slog.Int("limit_tokens", overflowErr.LimitTokens),
+	)
+	deps.Session.SetContextOverflow(overflowErr.Overflow())
+	deps.Session.AddHistoryEntry(agent.HistoryEntry{
+		Type:  "context_overflow",
+		Input: overflowErr.Error(),
+	})
+	return agent.StateReflect, true
+}
+
+// narrowAfterOverflow narrows the context after an LLM call overflowed the
+// model's context window, so Execute can retry.
+//
+// Description:
+//
+//	Drops the older half of the tool results, shortens the rest and asks
+//	the model for narrower tool calls. Each overflow halves the results
+//	again, so repeated overflows end once one short result is left: the
+//	run then fails with ErrContextOverflow.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	overflow - The overflow taken from the session.
+//
+// Outputs:
+//
+//	agent.AgentState - EXECUTE to retry, or ERROR.
+//	error - Wraps agent.ErrContextOverflow when nothing is left to narrow.
+func (p *ReflectPhase) narrowAfterOverflow(deps *Dependencies, overflow agent.ContextOverflow) (agent.AgentState, error) {
+	reduction, ok := narrowToolResults(deps.Context, overflow)
+	if !ok {
+		err := fmt.Errorf("%w: prompt needs ~%d tokens plus %d for the response but %s has a %d-token context window, and no tool results are left to narrow",
+			agent.ErrContextOverflow, overflow.EstimatedTokens, overflow.ReservedTokens, overflow.Model, overflow.LimitTokens)
+		p.emitStateTransition(deps, agent.StateReflect, agent.StateError, "context overflow")
+		return agent.StateError, err
+	}
+
+	deps.Context.ConversationHistory = append(deps.Context.ConversationHistory, agent.Message{
+		Role:    "user",
+		Content: fmt.Sprintf(overflowHint, overflow.EstimatedTokens+overflow.ReservedTokens, overflow.LimitTokens),
+	})
+	deps.Session.SetCurrentContext(deps.Context)
+	deps.Session.AddHistoryEntry(agent.HistoryEntry{
+		Type:  "context_narrowed",
+		Input: fmt.Sprintf("narrowed tool results (~%d -> ~%d tokens, window %d)", reduction.TokensBefore, reduction.TokensAfter, overflow.LimitTokens),
+	})
+	slog.Info("Narrowed tool results after context overflow",
+		slog.String("session_id", deps.Session.ID),
+		slog.Int("tokens_before", reduction.TokensBefore),
+		slog.Int("tokens_after", reduction.TokensAfter),
+		slog.Int("tool_results", len(deps.Context.ToolResults)),
+	)
+
+	if deps.EventEmitter != nil {
+		deps.EventEmitter.Emit(events.TypeContextReduction, &events.ContextReductionData{
+			SessionID:       deps.Session.ID,
+			Model:           overflow.Model,
+			LimitTokens:     overflow.LimitTokens,
+			EstimatedTokens: reduction.TokensAfter,
+			ReservedTokens:  overflow.ReservedTokens,
+			Reductions:      []agent.ContextReduction{reduction},
+		})
+	}
+	p.emitStateTransition(deps, agent.StateReflect, agent.StateExecute, "context overflow: retrying with narrower tool results")
+	return agent.StateExecute, nil
+}
+
+// narrowToolResults keeps the newer half of c's tool results, each cut to
+// its share of the window. Modifies c.
+//
+// Outputs:
+//
+//	agent.ContextReduction - What was dropped or shortened.
+//	bool - False when nothing could be narrowed.
+func narrowToolResults(c *agent.AssembledContext, overflow agent.ContextOverflow) (agent.ContextReduction, bool) {
+	results := c.ToolResults
+	if len(results) == 0 {
+		return agent.ContextReduction{}, false
+	}
+	keep := len(results) / 2
+	if keep < 1 {
+		keep = 1
+	}
+	// Tool output may take about a quarter of the window (~4 chars/token).
+	maxChars := overflow.LimitTokens / keep
+	if maxChars < overflowMinResultChars {
+		maxChars = overflowMinResultChars
+	}
+
+	reduction := agent.ContextReduction{
+		Action:       agent.ReductionNarrowToolResults,
+		TokensBefore: estimateContextTokens(c),
+	}
+	for _, r := range results[:len(results)-keep] {
+		reduction.Items = append(reduction.Items, toolResultName(r))
+	}
+	narrowed := make([]agent.ToolResult, 0, keep)
+	for _, r := range results[len(results)-keep:] {
+		if len(r.Output) > maxChars {
+			reduction.Items = append(reduction.Items, toolResultName(r)+" (shortened)")
+			r.Output = r.Output[:maxChars] + "\n... [output truncated]"
+		}
+		narrowed = append(narrowed, r)
+	}
+	if len(reduction.Items) == 0 {
+		return agent.ContextReduction{}, false
+	}
+
+	c.ToolResults = narrowed
+	reduction.TokensAfter = estimateContextTokens(c)
+	return reduction, true
+}
+
+// toolResultName names a tool result in a reduction record.
+func toolResultName(r agent.ToolResult) string {
+	if r.Tool == "" {
+		return r.InvocationID
+	}
+	return r.Tool
+}
+
+// estimateContextTokens estimates the prompt size of c at 4 chars/token.
+func estimateContextTokens(c *agent.AssembledContext) int {
+	chars := len(c.SystemPrompt)
+	for _, entry := range c.CodeContext {
+		chars += len(entry.Content)
+	}
+	for _, msg := range c.ConversationHistory {
+		chars += len(msg.Content)
+	}
+	for _, r := range c.ToolResults {
+		chars += len(r.Output)
+	}
+	return chars / 4
+}
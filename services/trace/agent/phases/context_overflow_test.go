@@ -0,0 +1,92 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+)
+
+func TestContextOverflow_ReflectNarrowsAndRetries(t *testing.T) {
+	deps := createTestDependencies()
+	deps.Context = &agent.AssembledContext{
+		ConversationHistory: []agent.Message{{Role: "user", Content: "Who calls Save?"}},
+	}
+	for i := 1; i <= 4; i++ {
+		deps.Context.ToolResults = append(deps.Context.ToolResults, agent.ToolResult{
+			InvocationID: fmt.Sprintf("t%d", i),
+			Tool:         fmt.Sprintf("tool_%d", i),
+			Output:       strings.Repeat("x", 3000),
+			Success:      true,
+		})
+	}
+	var reductions []*events.ContextReductionData
+	deps.EventEmitter.Subscribe(func(e *events.Event) {
+		reductions = append(reductions, e.Data.(*events.ContextReductionData))
+	}, events.TypeContextReduction)
+
+	overflowErr := fmt.Errorf("LLM request failed: %w", &llm.ContextOverflowError{
+		Model: "m", EstimatedTokens: 9000, ReservedTokens: 500, LimitTokens: 1000,
+	})
+	state, err := NewExecutePhase().handleLLMError(deps, overflowErr)
+	if err != nil || state != agent.StateReflect {
+		t.Fatalf("handleLLMError = %s, %v; want REFLECT", state, err)
+	}
+
+	reflect := NewReflectPhase()
+	state, err = reflect.Execute(context.Background(), deps)
+	if err != nil || state != agent.StateExecute {
+		t.Fatalf("reflect = %s, %v; want EXECUTE", state, err)
+	}
+	results := deps.Context.ToolResults
+	if len(results) != 2 || results[0].Tool != "tool_3" || len(results[1].Output) > 600 {
+		t.Errorf("tool results = %d, first %q; want the newest two, shortened", len(results), results[0].Tool)
+	}
+	hint := deps.Context.ConversationHistory[len(deps.Context.ConversationHistory)-1].Content
+	if !strings.Contains(hint, "narrower tool calls") {
+		t.Errorf("last message = %q, want the narrowing hint", hint)
+	}
+	if len(reductions) != 1 || reductions[0].Reductions[0].Action != agent.ReductionNarrowToolResults ||
+		reductions[0].SessionID != deps.Session.ID || len(reductions[0].Reductions[0].Items) != 4 {
+		t.Fatalf("events = %+v", reductions)
+	}
+
+	// Overflowing until one short result is left fails the run.
+	for i := 0; i < 3 && state != agent.StateError; i++ {
+		if _, err := NewExecutePhase().handleLLMError(deps, overflowErr); err != nil {
+			t.Fatal(err)
+		}
+		state, err = reflect.Execute(context.Background(), deps)
+	}
+	if state != agent.StateError || !errors.Is(err, agent.ErrContextOverflow) || !strings.Contains(err.Error(), "1000-token") {
+		t.Errorf("final state = %s, %v; want ERROR with CONTEXT_OVERFLOW", state, err)
+	}
+}
+
+func TestContextOverflow_NoToolResults(t *testing.T) {
+	deps := createTestDependencies()
+	deps.Context = &agent.AssembledContext{}
+	overflowErr := &llm.ContextOverflowError{Model: "m", EstimatedTokens: 9000, LimitTokens: 1000}
+	state, err := NewExecutePhase().handleLLMError(deps, overflowErr)
+	if state != agent.StateError || !errors.Is(err, agent.ErrContextOverflow) {
+		t.Errorf("handleLLMError = %s, %v; want ERROR with the overflow", state, err)
+	}
+	if deps.Session.TakeContextOverflow() != nil {
+		t.Error("overflow recorded with nothing to narrow")
+	}
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/rag"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -91,6 +92,10 @@ type ExecutePhase struct {
 	// prefilter narrows tool candidates before the LLM router classifies (CB-38).
 	// nil = disabled (backward compatible, no behavior change).
 	prefilter *routing.PreFilter
+
+	// parallelToolLimit is the maximum number of independent plan steps
+	// executed concurrently. 1 executes every step sequentially.
+	parallelToolLimit int
 }
 
 // ExecutePhaseOption configures an ExecutePhase.
@@ -281,6 +286,7 @@ func NewExecutePhase(opts ...ExecutePhaseOption) *ExecutePhase {
 		forcingPolicy:         NewDefaultForcingPolicy(),
 		maxToolForcingRetries: 2, // Circuit breaker for tool forcing
 		maxStepForForcing:     2, // Only force on early steps
+		parallelToolLimit:     defaultParallelToolLimit,
 		// Hybrid stack components
 		toolChoiceSelector: classifier.NewToolChoiceSelector(queryClassifier, nil),
 		responseValidator:  classifier.NewRetryableValidator(2), // Max 2 retries
@@ -328,7 +334,7 @@ func (p *ExecutePhase) Name() string {
 // Thread Safety: This method is safe for concurrent use.
 func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.AgentState, error) {
 	if err := p.validateDependencies(deps); err != nil {
-		slog.Error("ExecutePhase validation failed", slog.String("error", err.Error()))
+		slog.ErrorContext(ctx, "ExecutePhase validation failed", telemetry.SensitiveLogError("error", err))
 		return agent.StateError, err
 	}
 
@@ -342,9 +348,9 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 	routerEnabled := deps.Session.IsToolRouterEnabled()
 	routerModel := deps.Session.Config.ToolRouterModel
 
-	slog.Info("ExecutePhase starting",
+	slog.InfoContext(ctx, "ExecutePhase starting",
 		slog.String("session_id", deps.Session.ID),
-		slog.String("query", deps.Query),
+		telemetry.SensitiveLog("query", deps.Query),
 		slog.Bool("router_enabled", routerEnabled),
 		slog.String("router_model", routerModel),
 	)
@@ -352,7 +358,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 	// Record router status in trace for debugging
 	if !routerEnabled && deps.Session.Config.ToolRouterEnabled {
 		// Router was configured but failed to initialize - surface this issue
-		slog.Warn("GR-41b: Router configured but not initialized - falling back to main LLM for tool selection",
+		slog.WarnContext(ctx, "GR-41b: Router configured but not initialized - falling back to main LLM for tool selection",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("configured_model", routerModel),
 		)
@@ -374,7 +380,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 		stepNumber = deps.EventEmitter.IncrementStep()
 	}
 
-	slog.Info("Building LLM request",
+	slog.InfoContext(ctx, "Building LLM request",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("step", stepNumber),
 	)
@@ -456,12 +462,12 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 
 	// Build the LLM request (includes routing — ~570ms on granite4:micro-h).
 	// While this blocks, ministral-3:3b is extracting params in parallel.
-	request, hardForcing, buildErr := p.buildLLMRequest(deps)
+	request, hardForcing, buildErr := p.buildLLMRequest(ctx, deps)
 	if buildErr != nil {
 		// GR-44 Rev 2: Router errors are fatal - propagate up
-		slog.Error("GR-44: buildLLMRequest failed due to router error",
+		slog.ErrorContext(ctx, "GR-44: buildLLMRequest failed due to router error",
 			slog.String("session_id", deps.Session.ID),
-			slog.String("error", buildErr.Error()),
+			telemetry.SensitiveLogError("error", buildErr),
 		)
 		return agent.StateError, buildErr
 	}
@@ -473,7 +479,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 	// Previous placement (Rev 4c) was AFTER the hardForcing block, so it was bypassed
 	// when the router selected a non-graph tool (e.g., Grep) on subsequent steps.
 	if stepNumber >= 1 && deps.Session != nil && deps.Session.GraphToolHadSubstantiveResults() {
-		slog.Info("GR-59 Rev 4d: Pre-tool synthesis — forced graph tool already completed, skipping router-forced tool",
+		slog.InfoContext(ctx, "GR-59 Rev 4d: Pre-tool synthesis — forced graph tool already completed, skipping router-forced tool",
 			slog.String("session_id", deps.Session.ID),
 			slog.Int("step_number", stepNumber),
 			slog.String("router_suggested", func() string {
@@ -500,7 +506,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 		_, semanticSpan := executePhaseTracer.Start(ctx, "semantic_duplicate_check",
 			trace.WithAttributes(
 				attribute.String("tool", hardForcing.Tool),
-				attribute.String("query_preview", truncateQuery(deps.Query, 100)),
+				telemetry.SensitiveString("query_preview", truncateQuery(deps.Query, 100)),
 			),
 		)
 
@@ -532,22 +538,22 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 			currentKey := fmt.Sprintf("%s:%s", hardForcing.Tool, deps.Query)
 			previousKey := fmt.Sprintf("%s:%s", hardForcing.Tool, similarCall.RawQuery)
 			if recErr := deps.Session.GetCRS().RecordSimilarity(ctx, currentKey, previousKey, 1.0-similarity); recErr != nil {
-				slog.Debug("CRS-15: Failed to record similarity",
-					slog.String("error", recErr.Error()),
+				slog.DebugContext(ctx, "CRS-15: Failed to record similarity",
+					telemetry.SensitiveLogError("error", recErr),
 				)
 			}
 		}
 
 		if status == "blocked" {
-			slog.Info("GR-38: Skipping hard force, semantically similar call already made",
+			slog.InfoContext(ctx, "GR-38: Skipping hard force, semantically similar call already made",
 				slog.String("session_id", deps.Session.ID),
 				slog.String("tool", hardForcing.Tool),
 				slog.Float64("similarity", similarity),
 				slog.String("status", status),
 			)
 			if similarCall != nil {
-				slog.Debug("GR-38: Similar previous call details",
-					slog.String("previous_query", similarCall.RawQuery),
+				slog.DebugContext(ctx, "GR-38: Similar previous call details",
+					telemetry.SensitiveLog("previous_query", similarCall.RawQuery),
 					slog.Int("previous_step", similarCall.StepNumber),
 				)
 			}
@@ -558,7 +564,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 			hardForcing = nil
 		} else if status == "penalized" {
 			// Log but allow - the UCB1 scorer will apply a penalty if this continues
-			slog.Debug("GR-38: Similar but distinct call detected, allowing with penalty awareness",
+			slog.DebugContext(ctx, "GR-38: Similar but distinct call detected, allowing with penalty awareness",
 				slog.String("tool", hardForcing.Tool),
 				slog.Float64("similarity", similarity),
 			)
@@ -568,7 +574,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 	// Execute hard forcing if still enabled after duplicate check
 	if hardForcing != nil {
 		// TR-2 Fix: Execute tool directly with full observability
-		slog.Info("Router hard-forcing tool, attempting direct execution (CB-31d)",
+		slog.InfoContext(ctx, "Router hard-forcing tool, attempting direct execution (CB-31d)",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("forced_tool", hardForcing.Tool),
 		)
@@ -592,7 +598,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 			// Speculative hit: router confirmed our pre-filter prediction
 			converted, convErr := convertMapToTypedParams(hardForcing.Tool, llmResult.params)
 			if convErr == nil {
-				slog.Info("IT-08e: LLM param extraction succeeded (parallel, speculative hit)",
+				slog.InfoContext(ctx, "IT-08e: LLM param extraction succeeded (parallel, speculative hit)",
 					slog.String("tool", hardForcing.Tool),
 					slog.String("speculative_outcome", "hit"),
 					slog.Duration("speculative_duration", speculativeDuration),
@@ -607,14 +613,14 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 					if regexParams, ok := params.(tools.FindPathParams); ok {
 						if llmParams, ok := converted.(tools.FindPathParams); ok {
 							if strings.Contains(regexParams.From, ".") && !strings.Contains(llmParams.From, ".") {
-								slog.Info("IT-Summary FIX-D: preserving regex dot-notation for 'from'",
+								slog.InfoContext(ctx, "IT-Summary FIX-D: preserving regex dot-notation for 'from'",
 									slog.String("regex", regexParams.From),
 									slog.String("llm", llmParams.From),
 								)
 								llmParams.From = regexParams.From
 							}
 							if strings.Contains(regexParams.To, ".") && !strings.Contains(llmParams.To, ".") {
-								slog.Info("IT-Summary FIX-D: preserving regex dot-notation for 'to'",
+								slog.InfoContext(ctx, "IT-Summary FIX-D: preserving regex dot-notation for 'to'",
 									slog.String("regex", regexParams.To),
 									slog.String("llm", llmParams.To),
 								)
@@ -627,9 +633,9 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 				params = converted
 				paramErr = nil
 			} else {
-				slog.Warn("IT-08e: LLM param conversion failed, using regex",
+				slog.WarnContext(ctx, "IT-08e: LLM param conversion failed, using regex",
 					slog.String("tool", hardForcing.Tool),
-					slog.String("error", convErr.Error()),
+					telemetry.SensitiveLogError("error", convErr),
 					slog.String("speculative_outcome", "hit_conversion_failed"),
 				)
 				routing.RecordSpeculativeExtraction("hit_conversion_failed", speculativeDuration.Seconds())
@@ -639,7 +645,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 			// The speculative LLM extracted params for the wrong tool. Instead of
 			// discarding and falling back to crude regex params, re-extract using
 			// the param extractor for the correct tool.
-			slog.Info("IT-08e: Speculative mispredict, attempting re-extraction",
+			slog.InfoContext(ctx, "IT-08e: Speculative mispredict, attempting re-extraction",
 				slog.String("speculated", llmResult.tool),
 				slog.String("actual", hardForcing.Tool),
 			)
@@ -672,7 +678,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 								params = converted
 								paramErr = nil
 								reExtracted = true
-								slog.Info("IT-08e: Re-extraction succeeded",
+								slog.InfoContext(ctx, "IT-08e: Re-extraction succeeded",
 									slog.String("tool", hardForcing.Tool),
 									slog.String("speculative_outcome", "mispredict_reextracted"),
 									slog.Duration("speculative_duration", speculativeDuration),
@@ -684,7 +690,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 				}
 			}
 			if !reExtracted {
-				slog.Info("IT-08e: Re-extraction failed or unavailable, using regex params",
+				slog.InfoContext(ctx, "IT-08e: Re-extraction failed or unavailable, using regex params",
 					slog.String("tool", hardForcing.Tool),
 					slog.String("speculative_outcome", "mispredict_regex_fallback"),
 					slog.Duration("speculative_duration", speculativeDuration),
@@ -692,7 +698,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 				routing.RecordSpeculativeExtraction("mispredict_regex_fallback", speculativeDuration.Seconds())
 			}
 		} else if llmResult.err != nil {
-			slog.Debug("IT-08e: LLM param extraction unavailable, using regex",
+			slog.DebugContext(ctx, "IT-08e: LLM param extraction unavailable, using regex",
 				slog.String("reason", llmResult.err.Error()),
 				slog.String("speculative_outcome", "error"),
 				slog.Duration("speculative_duration", speculativeDuration),
@@ -702,7 +708,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 			// CRS-14 CR2 Fix H1: Catch-all for remaining cases (e.g., nil params
 			// with matching tool, empty tool with no error). Without this, the
 			// speculative extraction outcome is silently unrecorded.
-			slog.Debug("IT-08e: LLM param extraction returned empty result, using regex",
+			slog.DebugContext(ctx, "IT-08e: LLM param extraction returned empty result, using regex",
 				slog.String("speculative_outcome", "empty_result"),
 				slog.Duration("speculative_duration", speculativeDuration),
 			)
@@ -960,9 +966,9 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 
 		if paramErr != nil {
 			// TR-7 Fix: Fallback to Main LLM on parameter extraction failure
-			slog.Warn("Parameter extraction failed, falling back to Main LLM (CB-31d)",
+			slog.WarnContext(ctx, "Parameter extraction failed, falling back to Main LLM (CB-31d)",
 				slog.String("tool", hardForcing.Tool),
-				slog.String("error", paramErr.Error()),
+				telemetry.SensitiveLogError("error", paramErr),
 			)
 			grounding.RecordRouterFallback(hardForcing.Tool, "param_extraction_failed")
 			// GR-Phase1 Fix: When hard forcing fails, check if we already have tool results.
@@ -977,7 +983,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 			if deps.Context != nil && len(deps.Context.ToolResults) > 0 {
 				// Already have tool results - force synthesis (no tools needed)
 				request.ToolChoice = llm.ToolChoiceNone()
-				slog.Info("GR-Phase1: Skipping tool forcing, already have tool results",
+				slog.InfoContext(ctx, "GR-Phase1: Skipping tool forcing, already have tool results",
 					slog.String("session_id", deps.Session.ID),
 					slog.Int("tool_results", len(deps.Context.ToolResults)),
 				)
@@ -990,9 +996,9 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 			execResult, execErr := p.executeToolDirectlyWithFallback(ctx, deps, hardForcing.Tool, params, toolDefs)
 			if execErr != nil {
 				// TR-3 Fix: Fallback to Main LLM if direct execution fails
-				slog.Warn("Hard-forced tool execution failed, falling back to Main LLM (CB-31d)",
+				slog.WarnContext(ctx, "Hard-forced tool execution failed, falling back to Main LLM (CB-31d)",
 					slog.String("tool", hardForcing.Tool),
-					slog.String("error", execErr.Error()),
+					telemetry.SensitiveLogError("error", execErr),
 				)
 				grounding.RecordRouterFallback(hardForcing.Tool, "execution_failed")
 				// GR-Phase1 Fix: Same as above - check for existing tool results.
@@ -1000,7 +1006,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 				request.Tools = nil
 				if deps.Context != nil && len(deps.Context.ToolResults) > 0 {
 					request.ToolChoice = llm.ToolChoiceNone()
-					slog.Info("GR-Phase1: Skipping tool forcing, already have tool results",
+					slog.InfoContext(ctx, "GR-Phase1: Skipping tool forcing, already have tool results",
 						slog.String("session_id", deps.Session.ID),
 						slog.Int("tool_results", len(deps.Context.ToolResults)),
 					)
@@ -1022,7 +1028,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 				// are positive or CRS has exhausted scope relaxation.
 				if graphToolsWithSubstantiveResults[hardForcing.Tool] &&
 					deps.Session.GraphToolHadSubstantiveResults() {
-					slog.Info("GR-59 Rev 5: Graph tool completed — forcing immediate synthesis",
+					slog.InfoContext(ctx, "GR-59 Rev 5: Graph tool completed — forcing immediate synthesis",
 						slog.String("session_id", deps.Session.ID),
 						slog.String("tool", hardForcing.Tool),
 					)
@@ -1036,7 +1042,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 	}
 
 	// Send request to LLM
-	slog.Info("Sending LLM request",
+	slog.InfoContext(ctx, "Sending LLM request",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("max_tokens", request.MaxTokens),
 		slog.Int("tool_count", len(request.Tools)),
@@ -1044,14 +1050,14 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 
 	response, err := p.callLLM(ctx, deps, request)
 	if err != nil {
-		slog.Error("LLM request failed",
+		slog.ErrorContext(ctx, "LLM request failed",
 			slog.String("session_id", deps.Session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return p.handleLLMError(deps, err)
 	}
 
-	slog.Info("LLM response received",
+	slog.InfoContext(ctx, "LLM response received",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("output_tokens", response.OutputTokens),
 		slog.Bool("has_tool_calls", response.HasToolCalls()),
@@ -1066,7 +1072,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 		circuitBreakerActive := request.ToolChoice != nil && request.ToolChoice.Type == "none" && request.Tools == nil
 		if circuitBreakerActive && containsToolCallPattern(response.Content) {
 			// LLM escaped the constraint! It output tool calls in text despite ToolChoiceNone()
-			slog.Warn("Circuit breaker desperation trap: LLM escaped ToolChoiceNone() constraint (CB-31d)",
+			slog.WarnContext(ctx, "Circuit breaker desperation trap: LLM escaped ToolChoiceNone() constraint (CB-31d)",
 				slog.String("session_id", deps.Session.ID),
 				slog.Int("response_len", len(response.Content)),
 				slog.String("response_preview", truncateForLog(response.Content, 200)),
@@ -1077,7 +1083,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 		}
 
 		// Normal completion - no circuit breaker or no escaped patterns
-		slog.Info("No tool calls, completing",
+		slog.InfoContext(ctx, "No tool calls, completing",
 			slog.String("session_id", deps.Session.ID),
 		)
 
@@ -1090,7 +1096,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 	// "Read src/flask/app.py 1-200" instead of JSON tool calls.
 	invocations, usedReAct := llm.ParseToolCallsWithReAct(response)
 	if usedReAct {
-		slog.Info("IT-05 R5: ReAct fallback parsed tool call from text response",
+		slog.InfoContext(ctx, "IT-05 R5: ReAct fallback parsed tool call from text response",
 			slog.Int("invocations", len(invocations)),
 		)
 	}
@@ -1114,7 +1120,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 		// progress before reflection threshold at step 10). Force synthesis from
 		// any accumulated results instead of wasting time on blocked tool calls.
 		if deps.Session.IsCircuitBreakerActive() {
-			slog.Info("IT-06c I-13: All tool calls blocked, forcing synthesis",
+			slog.InfoContext(ctx, "IT-06c I-13: All tool calls blocked, forcing synthesis",
 				slog.String("session_id", deps.Session.ID),
 				slog.Int("step_number", stepNumber),
 			)
@@ -1130,7 +1136,7 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 	// already returned the answer. If graph tools produced results and we've
 	// done at least 2 steps, synthesize instead of continuing to search.
 	if p.shouldForceSynthesisAfterGraphTools(deps, toolResults, stepNumber) {
-		slog.Info("GR-41b: Forcing synthesis after graph tool results",
+		slog.InfoContext(ctx, "GR-41b: Forcing synthesis after graph tool results",
 			slog.String("session_id", deps.Session.ID),
 			slog.Int("step_number", stepNumber),
 			slog.Int("tool_results", len(toolResults)),
@@ -1146,13 +1152,19 @@ func (p *ExecutePhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 		// GR-59 Rev 2: If current batch has no graph results (e.g., only CB errors),
 		// force the LLM to produce an answer from conversation history which already
 		// contains the graph tool results from prior steps.
-		slog.Info("GR-59 Rev 2: Current batch has no graph results, forcing LLM synthesis from history",
+		slog.InfoContext(ctx, "GR-59 Rev 2: Current batch has no graph results, forcing LLM synthesis from history",
 			slog.String("session_id", deps.Session.ID),
 		)
 		deps.Session.SetCircuitBreakerActive(true)
 		return p.forceLLMSynthesis(ctx, deps, request, stepStart, stepNumber)
 	}
 
+	// Rejected arguments are re-extracted by Reflect, not retried blindly
+	if hasRejectedArguments(toolResults) {
+		p.emitStateTransition(deps, agent.StateExecute, agent.StateReflect, "tool arguments rejected")
+		return agent.StateReflect, nil
+	}
+
 	// Check if reflection is needed
 	if p.shouldReflect(deps, stepNumber) {
 		p.emitStateTransition(deps, agent.StateExecute, agent.StateReflect, "reflection threshold reached")
@@ -1194,6 +1206,7 @@ func (p *ExecutePhase) validateDependencies(deps *Dependencies) error {
 //
 // Inputs:
 //
+//	ctx - Context for the router and classifier calls.
 //	deps - Phase dependencies.
 //
 // Outputs:
@@ -1201,7 +1214,7 @@ func (p *ExecutePhase) validateDependencies(deps *Dependencies) error {
 //	*llm.Request - The LLM request.
 //	*agent.ToolRouterSelection - Non-nil if hard forcing is enabled.
 //	error - Non-nil if router is configured but fails (GR-44: fatal, no fallback).
-func (p *ExecutePhase) buildLLMRequest(deps *Dependencies) (*llm.Request, *agent.ToolRouterSelection, error) {
+func (p *ExecutePhase) buildLLMRequest(ctx context.Context, deps *Dependencies) (*llm.Request, *agent.ToolRouterSelection, error) {
 	// Get available tools
 	var toolDefs []tools.ToolDefinition
 	var toolNames []string
@@ -1214,6 +1227,9 @@ func (p *ExecutePhase) buildLLMRequest(deps *Dependencies) (*llm.Request, *agent
 	}
 
 	request := llm.BuildRequest(deps.Context, toolDefs, p.maxTokens)
+	appendCitationPrompt(deps, request)
+	appendGraphQualityPrompt(deps, request)
+	p.appendToolPlanPrompt(request)
 
 	// CB-62: Apply per-session main model override from user's OpenWebUI selection.
 	// ModelOverride is respected by Ollama, OpenAI, and Gemini adapters.
@@ -1250,7 +1266,7 @@ func (p *ExecutePhase) buildLLMRequest(deps *Dependencies) (*llm.Request, *agent
 			slog.Bool("router_is_nil", router == nil),
 		)
 		if router != nil {
-			routerSelection, routerErr := p.tryToolRouterSelection(context.Background(), deps, router, toolDefs)
+			routerSelection, routerErr := p.tryToolRouterSelection(ctx, deps, router, toolDefs)
 			// GR-44 Rev 2: Router errors are fatal - propagate up
 			if routerErr != nil {
 				return nil, nil, routerErr
@@ -1379,7 +1395,7 @@ Provide your answer now:`
 	// Classifier fallback ONLY allowed when router is NOT configured.
 	// This prevents the main LLM from selecting tools - that's the router's job.
 	if !routerUsed && !deps.Session.Config.ToolRouterEnabled && p.toolChoiceSelector != nil && deps.Query != "" && len(toolDefs) > 0 {
-		selection := p.toolChoiceSelector.SelectToolChoice(context.Background(), deps.Query, toolNames)
+		selection := p.toolChoiceSelector.SelectToolChoice(ctx, deps.Query, toolNames)
 
 		// Only set tool_choice for analytical queries
 		if selection.IsAnalytical {
@@ -1417,10 +1433,13 @@ Provide your answer now:`
 //
 //	*agent.ToolRouterSelection - The selection if confident, nil for low confidence.
 //	error - Non-nil if router fails (GR-44: fatal error, no fallback).
-func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Dependencies, router agent.ToolRouter, toolDefs []tools.ToolDefinition) (*agent.ToolRouterSelection, error) {
+//
+// The decision behind the selection is recorded on the session and emitted
+// as a routing_decision event (see recordRoutingDecision).
+func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Dependencies, router agent.ToolRouter, toolDefs []tools.ToolDefinition) (final *agent.ToolRouterSelection, err error) {
 	// GR-Phase1: Track router invocation count to debug duplicate calls
 	routerCallID := time.Now().UnixNano()
-	slog.Info("CB-31d tryToolRouterSelection CALLED",
+	slog.InfoContext(ctx, "CB-31d tryToolRouterSelection CALLED",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("num_tool_defs", len(toolDefs)),
 		slog.String("router_model", router.Model()),
@@ -1433,6 +1452,14 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 	// Convert tool definitions to ToolSpecs for the router
 	toolSpecs := toolDefsToSpecs(toolDefs)
 
+	decision := agent.NewRoutingDecision(deps.Query, len(toolSpecs))
+	defer func() {
+		if err != nil {
+			decision.Source = agent.RoutingSourceError
+		}
+		p.recordRoutingDecision(deps, decision)
+	}()
+
 	// GR-61: Build session counts once for both the prefilter UCB1 penalty
 	// and the legacy circuit-breaker check below. Avoids iterating trace steps
 	// twice in the same function call.
@@ -1444,6 +1471,9 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 	// CB-38: Pre-filter narrows candidate set before LLM router
 	if p.prefilter != nil {
 		pfResult := p.prefilter.FilterAgentSpecs(ctx, deps.Query, toolSpecs, sessionCounts)
+		if pfResult.Decision != nil {
+			decision = pfResult.Decision
+		}
 		if pfResult.ForcedTool != "" {
 			// CB-38: Check circuit breaker before accepting forced selection.
 			// A forced tool that has been called too many times should not bypass
@@ -1453,7 +1483,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 				cbResult := deps.Session.GetCRS().CheckCircuitBreaker(deps.Session.ID, pfResult.ForcedTool)
 				if cbResult.ShouldFire {
 					cbBlocked = true
-					slog.Warn("CB-38 prefilter forced tool blocked by circuit breaker",
+					slog.WarnContext(ctx, "CB-38 prefilter forced tool blocked by circuit breaker",
 						slog.String("session_id", deps.Session.ID),
 						slog.String("tool", pfResult.ForcedTool),
 						slog.String("cb_reason", cbResult.Reason),
@@ -1463,7 +1493,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 				// GR-61: reuse sessionCounts built above — no second iteration.
 				if sessionCounts[pfResult.ForcedTool] >= maxRepeatedToolCalls {
 					cbBlocked = true
-					slog.Warn("CB-38 prefilter forced tool blocked by circuit breaker (legacy)",
+					slog.WarnContext(ctx, "CB-38 prefilter forced tool blocked by circuit breaker (legacy)",
 						slog.String("session_id", deps.Session.ID),
 						slog.String("tool", pfResult.ForcedTool),
 						slog.Int("call_count", sessionCounts[pfResult.ForcedTool]),
@@ -1472,7 +1502,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 			}
 
 			if !cbBlocked {
-				slog.Info("CB-38 prefilter forced tool selection",
+				slog.InfoContext(ctx, "CB-38 prefilter forced tool selection",
 					slog.String("session_id", deps.Session.ID),
 					slog.String("tool", pfResult.ForcedTool),
 					slog.String("reason", pfResult.ForcedReason),
@@ -1503,6 +1533,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 				}, nil
 			}
 			// Circuit breaker fired — fall through to normal routing with narrowed specs
+			decision.AddRuleHit("circuit_breaker_blocked:" + pfResult.ForcedTool)
 		}
 		toolSpecs = pfResult.NarrowedSpecs // 5-10 instead of 55
 		span.SetAttributes(
@@ -1516,14 +1547,14 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 
 	span.SetAttributes(
 		attribute.Int("num_tools", len(toolSpecs)),
-		attribute.String("query_preview", truncateQuery(deps.Query, 100)),
+		telemetry.SensitiveString("query_preview", truncateQuery(deps.Query, 100)),
 	)
 
 	// Call the router
-	slog.Info("CB-31d tryToolRouterSelection calling router.SelectTool",
+	slog.InfoContext(ctx, "CB-31d tryToolRouterSelection calling router.SelectTool",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("num_specs", len(toolSpecs)),
-		slog.String("query_preview", truncateQuery(deps.Query, 100)),
+		telemetry.SensitiveLog("query_preview", truncateQuery(deps.Query, 100)),
 	)
 
 	selection, err := router.SelectTool(ctx, deps.Query, toolSpecs, codeContext)
@@ -1531,11 +1562,11 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 		// GR-44 Rev 2: Router failure is FATAL - no silent fallback to classifier.
 		// The router is the router PERIOD. If it fails, the whole process fails.
 		errMsg := fmt.Sprintf("GR-44: Router selection failed: %v", err)
-		slog.Error(errMsg,
+		slog.ErrorContext(ctx, errMsg,
 			slog.String("session_id", deps.Session.ID),
 			slog.String("router_model", router.Model()),
 		)
-		span.SetAttributes(attribute.String("router_fatal_error", err.Error()))
+		span.SetAttributes(telemetry.SensitiveString("router_fatal_error", err.Error()))
 		span.SetStatus(codes.Error, errMsg)
 
 		// Record in trace for debugging
@@ -1548,7 +1579,9 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 		return nil, errors.New(errMsg)
 	}
 
-	slog.Info("CB-31d tryToolRouterSelection router.SelectTool RETURNED",
+	decision.ApplyRouterSelection(router.Model(), selection)
+
+	slog.InfoContext(ctx, "CB-31d tryToolRouterSelection router.SelectTool RETURNED",
 		slog.String("session_id", deps.Session.ID),
 		slog.String("selected_tool", selection.Tool),
 		slog.Float64("confidence", selection.Confidence),
@@ -1572,7 +1605,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 				// Log at INFO — the semantic correction is working as designed,
 				// not indicating a problem. The router model simply can't distinguish
 				// callers vs callees grammar; the correction layer fixes it.
-				slog.Info("GR-Phase1: Router selection semantically corrected",
+				slog.InfoContext(ctx, "GR-Phase1: Router selection semantically corrected",
 					slog.String("session_id", deps.Session.ID),
 					slog.String("original_tool", originalTool),
 					slog.String("corrected_tool", correctedTool),
@@ -1599,7 +1632,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 					})
 				}
 			} else {
-				slog.Debug("GR-Phase1: Semantic correction already applied, skipping duplicate",
+				slog.DebugContext(ctx, "GR-Phase1: Semantic correction already applied, skipping duplicate",
 					slog.String("session_id", deps.Session.ID),
 					slog.String("corrected_tool", correctedTool),
 				)
@@ -1613,6 +1646,8 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 
 			selection.Tool = correctedTool
 			selection.Reasoning = fmt.Sprintf("Semantic correction: %s. Original: %s", reason, selection.Reasoning)
+			decision.AddRuleHit("semantic_correction:" + correctedTool)
+			decision.SetFinal(correctedTool, agent.RoutingSourceSemanticCorrection)
 		}
 	}
 
@@ -1627,7 +1662,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 		// Instead of returning nil (which triggers classifier fallback),
 		// we log the low confidence and still use the router's suggestion
 		// if we have gathered enough tool results to synthesize an answer.
-		slog.Info("CB-31e: Router low confidence, checking if synthesis is possible",
+		slog.InfoContext(ctx, "CB-31e: Router low confidence, checking if synthesis is possible",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("tool", selection.Tool),
 			slog.Float64("confidence", selection.Confidence),
@@ -1651,12 +1686,13 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 
 		if hasToolResults {
 			// CB-31e: Force synthesis instead of fallback
-			slog.Info("CB-31e: Forcing synthesis from existing tool results",
+			slog.InfoContext(ctx, "CB-31e: Forcing synthesis from existing tool results",
 				slog.String("session_id", deps.Session.ID),
 				slog.Int("tool_result_count", toolResultCount),
 			)
 			// Record metric
 			config.RecordFallbackBlocked()
+			decision.SetFinal("answer", agent.RoutingSourceLowConfidence)
 			// Return "answer" tool to force synthesis
 			return &agent.ToolRouterSelection{
 				Tool:       "answer",
@@ -1667,7 +1703,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 
 		// If no tool results, still try the router's suggestion (better than random classifier)
 		// but log that this is a degraded path
-		slog.Warn("CB-31e: No tool results available, using low-confidence router suggestion",
+		slog.WarnContext(ctx, "CB-31e: No tool results available, using low-confidence router suggestion",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("tool", selection.Tool),
 			slog.Float64("confidence", selection.Confidence),
@@ -1719,7 +1755,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 		cbResult := deps.Session.GetCRS().CheckCircuitBreaker(deps.Session.ID, selection.Tool)
 		cbShouldFire = cbResult.ShouldFire
 		cbReason = cbResult.Reason
-		slog.Debug("CRS-02 circuit breaker check",
+		slog.DebugContext(ctx, "CRS-02 circuit breaker check",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("suggested_tool", selection.Tool),
 			slog.Bool("should_fire", cbShouldFire),
@@ -1730,7 +1766,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 	} else if codeContext != nil && codeContext.ToolHistory != nil {
 		// CB-31d: Fall back to count-based check when CRS not available
 		callCount := countToolCalls(codeContext.ToolHistory, selection.Tool)
-		slog.Debug("CB-31d circuit breaker check (legacy)",
+		slog.DebugContext(ctx, "CB-31d circuit breaker check (legacy)",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("suggested_tool", selection.Tool),
 			slog.Int("call_count", callCount),
@@ -1744,7 +1780,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 	}
 
 	if cbShouldFire {
-		slog.Info("Circuit breaker: forcing answer due to proof status",
+		slog.InfoContext(ctx, "Circuit breaker: forcing answer due to proof status",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("suggested_tool", selection.Tool),
 			slog.String("reason", cbReason),
@@ -1770,6 +1806,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 		deps.Session.SetCircuitBreakerActive(true)
 
 		// Force "answer" to synthesize a response from gathered information
+		decision.SetFinal("answer", agent.RoutingSourceCircuitBreaker)
 		return &agent.ToolRouterSelection{
 			Tool:       "answer",
 			Confidence: 0.8,
@@ -1790,8 +1827,8 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 			currentKey := fmt.Sprintf("%s:%s", selection.Tool, deps.Query)
 			previousKey := fmt.Sprintf("%s:%s", selection.Tool, similarQuery)
 			if recErr := deps.Session.GetCRS().RecordSimilarity(ctx, currentKey, previousKey, 1.0-similarity); recErr != nil {
-				slog.Debug("CRS-15: Failed to record similarity",
-					slog.String("error", recErr.Error()),
+				slog.DebugContext(ctx, "CRS-15: Failed to record similarity",
+					telemetry.SensitiveLogError("error", recErr),
 				)
 			}
 		}
@@ -1800,17 +1837,17 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 			srReason := fmt.Sprintf("semantic repetition: query %.0f%% similar to previous '%s'",
 				similarity*100, truncateQuery(similarQuery, 30))
 
-			slog.Info("CB-30c Semantic repetition: forcing answer",
+			slog.InfoContext(ctx, "CB-30c Semantic repetition: forcing answer",
 				slog.String("session_id", deps.Session.ID),
 				slog.String("tool", selection.Tool),
 				slog.Float64("similarity", similarity),
-				slog.String("similar_to", similarQuery),
+				telemetry.SensitiveLog("similar_to", similarQuery),
 			)
 
 			span.SetAttributes(
 				attribute.String("semantic_repetition", "detected"),
 				attribute.Float64("similarity", similarity),
-				attribute.String("similar_to", truncateQuery(similarQuery, 50)),
+				telemetry.SensitiveString("similar_to", truncateQuery(similarQuery, 50)),
 			)
 
 			// Record metric
@@ -1831,6 +1868,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 			deps.Session.SetCircuitBreakerActive(true)
 
 			// Force "answer" to synthesize
+			decision.SetFinal("answer", agent.RoutingSourceSemanticRepetition)
 			return &agent.ToolRouterSelection{
 				Tool:       "answer",
 				Confidence: 0.8,
@@ -1853,6 +1891,7 @@ func (p *ExecutePhase) tryToolRouterSelection(ctx context.Context, deps *Depende
 		ucb1Selection, modified := p.selectToolWithUCB1(ctx, deps, ucb1Ctx, selection, availableTools)
 
 		if modified {
+			decision.SetFinal(ucb1Selection.Tool, agent.RoutingSourceUCB1)
 			span.SetAttributes(
 				attribute.String("ucb1_original_tool", selection.Tool),
 				attribute.String("ucb1_selected_tool", ucb1Selection.Tool),
@@ -2056,6 +2095,8 @@ func (p *ExecutePhase) callLLM(ctx context.Context, deps *Dependencies, request
 //	Handles different LLM error types with appropriate recovery strategies.
 //	For EmptyResponseError (context overflow), synthesizes a graceful response
 //	from gathered tool results instead of failing. Fixed in cb_30a.
+//	For ContextOverflowError, moves to REFLECT to narrow the tool results
+//	and retry, when there are any.
 //
 // Inputs:
 //
@@ -2064,9 +2105,17 @@ func (p *ExecutePhase) callLLM(ctx context.Context, deps *Dependencies, request
 //
 // Outputs:
 //
-//	agent.AgentState - ERROR for unrecoverable, COMPLETE if graceful recovery.
+//	agent.AgentState - ERROR for unrecoverable, COMPLETE if graceful recovery,
+//	  REFLECT to retry after a context overflow.
 //	error - The original error or nil if recovered.
 func (p *ExecutePhase) handleLLMError(deps *Dependencies, err error) (agent.AgentState, error) {
+	var overflowErr *llm.ContextOverflowError
+	if errors.As(err, &overflowErr) {
+		if state, ok := p.handleContextOverflow(deps, overflowErr); ok {
+			return state, nil
+		}
+	}
+
 	// Check for EmptyResponseError - often caused by context overflow.
 	// Instead of failing, synthesize a response from gathered tool results.
 	// This provides a graceful degradation when the model is overwhelmed.
@@ -2097,7 +2146,7 @@ func (p *ExecutePhase) handleLLMError(deps *Dependencies, err error) (agent.Agen
 	if isOllamaToolParsingError(err) {
 		slog.Warn("IT-05 R6: Ollama tool-parsing error detected, attempting graceful recovery",
 			slog.String("session_id", deps.Session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 
 		if state, recovered := p.tryToolResultRecovery(deps, "ollama_tool_parse_error"); recovered {
@@ -2182,7 +2231,7 @@ func (p *ExecutePhase) tryToolResultRecovery(deps *Dependencies, reason string)
 //	agent.AgentState - COMPLETE if grounded, EXECUTE if retry needed.
 //	error - Non-nil only for unrecoverable errors.
 func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies, response *llm.Response, request *llm.Request, stepStart time.Time, stepNumber int) (agent.AgentState, error) {
-	slog.Info("Handling completion",
+	slog.InfoContext(ctx, "Handling completion",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("output_tokens", response.OutputTokens),
 		slog.Int("response_len", len(response.Content)),
@@ -2190,21 +2239,21 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 
 	// Update session metrics with token usage
 	if response.OutputTokens > 0 {
-		deps.Session.IncrementMetric(agent.MetricTokens, response.OutputTokens)
+		deps.Session.IncrementProviderTokens(providerLabel(deps.LLMClient, response), response.OutputTokens)
 	}
 	deps.Session.IncrementMetric(agent.MetricLLMCalls, 1)
 
 	// GR-39a Issue 1: Handle empty response by synthesizing from tool results
 	// This fixes cases where the LLM returns no content after tool calls
 	if strings.TrimSpace(response.Content) == "" {
-		slog.Warn("GR-39a: Empty response content detected, attempting synthesis",
+		slog.WarnContext(ctx, "GR-39a: Empty response content detected, attempting synthesis",
 			slog.String("session_id", deps.Session.ID),
 			slog.Int("output_tokens", response.OutputTokens),
 		)
 
 		synthesized := p.synthesizeFromToolResults(deps)
 		if synthesized != "" {
-			slog.Info("GR-39a: Synthesized response from tool results",
+			slog.InfoContext(ctx, "GR-39a: Synthesized response from tool results",
 				slog.String("session_id", deps.Session.ID),
 				slog.Int("synthesized_len", len(synthesized)),
 			)
@@ -2215,7 +2264,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 			// - LLM returns empty response AND
 			// - ToolResults is empty AND
 			// - TraceSteps fallback also fails
-			slog.Warn("CB-31: No tool results available for synthesis, providing fallback response",
+			slog.WarnContext(ctx, "CB-31: No tool results available for synthesis, providing fallback response",
 				slog.String("session_id", deps.Session.ID),
 			)
 
@@ -2242,7 +2291,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 	if isSurrenderResponse(response.Content) && deps.Context != nil && len(deps.Context.ToolResults) > 0 {
 		surrenderRetries := deps.Session.GetMetric(agent.MetricSurrenderRetries)
 		if surrenderRetries < 1 {
-			slog.Info("GR-59: Surrender detected with tool results available, retrying synthesis",
+			slog.InfoContext(ctx, "GR-59: Surrender detected with tool results available, retrying synthesis",
 				slog.String("session_id", deps.Session.ID),
 				slog.String("response_preview", truncateForLog(response.Content, 50)),
 				slog.Int("tool_results", len(deps.Context.ToolResults)),
@@ -2267,7 +2316,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 		if !validation.Valid {
 			forcingRetries := deps.Session.GetMetric(agent.MetricToolForcingRetries)
 
-			slog.Warn("Response validation failed",
+			slog.WarnContext(ctx, "Response validation failed",
 				slog.String("session_id", deps.Session.ID),
 				slog.String("reason", validation.Reason),
 				slog.String("pattern", validation.MatchedPattern),
@@ -2278,7 +2327,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 			// GR-44: Skip tool requirement when circuit breaker has forced synthesis mode.
 			// This prevents the death spiral where CB fires but execute phase still demands tools.
 			if deps.Session.IsCircuitBreakerActive() {
-				slog.Debug("GR-44: Skipping tool requirement (circuit breaker active)",
+				slog.DebugContext(ctx, "GR-44: Skipping tool requirement (circuit breaker active)",
 					slog.String("session_id", deps.Session.ID),
 					slog.String("validation_reason", validation.Reason),
 				)
@@ -2304,7 +2353,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 		if !qualityResult.Valid {
 			forcingRetries := deps.Session.GetMetric(agent.MetricToolForcingRetries)
 
-			slog.Warn("Response quality validation failed",
+			slog.WarnContext(ctx, "Response quality validation failed",
 				slog.String("session_id", deps.Session.ID),
 				slog.String("reason", qualityResult.Reason),
 				slog.String("pattern", qualityResult.MatchedPattern),
@@ -2339,9 +2388,9 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 		var err error
 		groundingResult, err = deps.ResponseGrounder.Validate(ctx, response.Content, deps.Context)
 		if err != nil {
-			slog.Warn("Grounding validation error",
+			slog.WarnContext(ctx, "Grounding validation error",
 				slog.String("session_id", deps.Session.ID),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			// Continue with unvalidated response on error
 		}
@@ -2349,7 +2398,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 
 	// Handle grounding result
 	if groundingResult != nil {
-		slog.Info("Grounding validation complete",
+		slog.InfoContext(ctx, "Grounding validation complete",
 			slog.String("session_id", deps.Session.ID),
 			slog.Bool("grounded", groundingResult.Grounded),
 			slog.Float64("confidence", groundingResult.Confidence),
@@ -2359,7 +2408,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 
 		// Log violations
 		for _, v := range groundingResult.Violations {
-			slog.Warn("Grounding violation",
+			slog.WarnContext(ctx, "Grounding violation",
 				slog.String("session_id", deps.Session.ID),
 				slog.String("type", string(v.Type)),
 				slog.String("severity", string(v.Severity)),
@@ -2376,7 +2425,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 				// Build correction prompt and retry
 				correctionPrompt := p.buildCorrectionPrompt(groundingResult)
 
-				slog.Info("Grounding rejection - requesting retry",
+				slog.InfoContext(ctx, "Grounding rejection - requesting retry",
 					slog.String("session_id", deps.Session.ID),
 					slog.Int("retry_count", retryCount+1),
 					slog.Int("max_retries", p.maxGroundingRetries),
@@ -2399,7 +2448,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 			}
 
 			// Circuit breaker triggered - log and continue with best effort
-			slog.Error("Grounding circuit breaker triggered - accepting ungrounded response",
+			slog.ErrorContext(ctx, "Grounding circuit breaker triggered - accepting ungrounded response",
 				slog.String("session_id", deps.Session.ID),
 				slog.Int("retry_count", retryCount),
 				slog.Int("critical_violations", groundingResult.CriticalCount),
@@ -2440,7 +2489,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 
 		// Log quality warnings for low scores.
 		if qr.Score < 0.5 {
-			slog.Warn("SYNTH-QUALITY: Response may not reflect tool results",
+			slog.WarnContext(ctx, "SYNTH-QUALITY: Response may not reflect tool results",
 				slog.String("session_id", deps.Session.ID),
 				slog.Float64("score", qr.Score),
 				slog.String("reason", qr.Reason),
@@ -2448,7 +2497,7 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 				slog.Int("symbols_found", qr.SymbolsFound),
 			)
 		} else {
-			slog.Debug("CRS-20: Synthesis quality scored",
+			slog.DebugContext(ctx, "CRS-20: Synthesis quality scored",
 				slog.String("session_id", deps.Session.ID),
 				slog.Float64("score", qr.Score),
 				slog.String("reason", qr.Reason),
@@ -2483,10 +2532,16 @@ func (p *ExecutePhase) handleCompletion(ctx context.Context, deps *Dependencies,
 		deps.Session.RecordTraceStep(completionStep)
 	}
 
+	// Ensemble runs reconcile the members' answers in the reflect phase
+	if _, ok := ensembleFor(deps); ok {
+		p.emitStateTransition(deps, agent.StateExecute, agent.StateReflect, "ensemble reconciliation")
+		return agent.StateReflect, nil
+	}
+
 	// Transition to complete
 	p.emitStateTransition(deps, agent.StateExecute, agent.StateComplete, "task completed")
 
-	slog.Info("ExecutePhase completed successfully",
+	slog.InfoContext(ctx, "ExecutePhase completed successfully",
 		slog.String("session_id", deps.Session.ID),
 	)
 
@@ -2597,9 +2652,9 @@ func (p *ExecutePhase) shouldForceToolUsage(ctx context.Context, deps *Dependenc
 func (p *ExecutePhase) forceToolUsage(ctx context.Context, deps *Dependencies, response *llm.Response, stepNumber int) (agent.AgentState, error) {
 	forcingRetries := deps.Session.GetMetric(agent.MetricToolForcingRetries)
 
-	slog.Info("Forcing tool usage",
+	slog.InfoContext(ctx, "Forcing tool usage",
 		slog.String("session_id", deps.Session.ID),
-		slog.String("query", deps.Query),
+		telemetry.SensitiveLog("query", deps.Query),
 		slog.Int("step", stepNumber),
 		slog.Int("retry", forcingRetries+1),
 	)
@@ -2726,13 +2781,14 @@ func (p *ExecutePhase) buildEmptyResponseFallback(deps *Dependencies) string {
 // the other 16. Now all 23 are listed.
 var graphToolsWithSubstantiveResults = map[string]bool{
 	// Core graph traversal
-	"find_callers":         true,
-	"find_callees":         true,
-	"find_implementations": true,
-	"find_references":      true,
-	"find_symbol":          true,
-	"find_path":            true,
-	"get_call_chain":       true,
+	"find_callers":           true,
+	"find_callees":           true,
+	"find_implementations":   true,
+	"explain_implementation": true,
+	"find_references":        true,
+	"find_symbol":            true,
+	"find_path":              true,
+	"get_call_chain":         true,
 	// Graph analytics
 	"find_hotspots":             true,
 	"find_important":            true,
@@ -3052,6 +3108,21 @@ func (p *ExecutePhase) synthesizeFromGraphToolResults(deps *Dependencies, toolRe
 			sb.WriteString(content)
 			sb.WriteString("\n\n")
 
+		case "find_owners":
+			sb.WriteString("**Owners:**\n")
+			sb.WriteString(content)
+			sb.WriteString("\n\n")
+
+		case "find_literal_usages":
+			sb.WriteString("**Literal usages:**\n")
+			sb.WriteString(content)
+			sb.WriteString("\n\n")
+
+		case "explain_implementation":
+			sb.WriteString("**Implementation check:**\n")
+			sb.WriteString(content)
+			sb.WriteString("\n\n")
+
 		case "find_entry_points":
 			sb.WriteString("**Entry points found:**\n")
 			sb.WriteString(content)
@@ -3130,7 +3201,7 @@ func (p *ExecutePhase) forceLLMSynthesis(
 	stepStart time.Time,
 	stepNumber int,
 ) (agent.AgentState, error) {
-	slog.Info("GR-59 Rev 2: Forcing LLM synthesis from conversation history",
+	slog.InfoContext(ctx, "GR-59 Rev 2: Forcing LLM synthesis from conversation history",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("step_number", stepNumber),
 	)
@@ -3140,7 +3211,7 @@ func (p *ExecutePhase) forceLLMSynthesis(
 	// the tool's definitive answer (e.g., "Symbol not found" → LLM fabricates details).
 	if deps.Context != nil && len(deps.Context.ToolResults) > 0 {
 		if singleResult, ok := getSingleFormattedResult(deps.Context.ToolResults); ok {
-			slog.Info("GR-59 Rev 2: Pass-through — authoritative graph result found, skipping LLM synthesis",
+			slog.InfoContext(ctx, "GR-59 Rev 2: Pass-through — authoritative graph result found, skipping LLM synthesis",
 				slog.String("session_id", deps.Session.ID),
 			)
 			// Record trace step for observability
@@ -3163,6 +3234,8 @@ func (p *ExecutePhase) forceLLMSynthesis(
 
 	// Build request without tools — force text-only response.
 	synthRequest := llm.BuildRequest(deps.Context, nil, p.maxTokens)
+	appendCitationPrompt(deps, synthRequest)
+	appendGraphQualityPrompt(deps, synthRequest)
 
 	// CB-62: Apply per-session main model override.
 	if deps.Session != nil && deps.Session.Config.MainModel != "" {
@@ -3193,7 +3266,7 @@ func (p *ExecutePhase) forceLLMSynthesis(
 			totalInputLen += len(tr.Content)
 		}
 	}
-	slog.Info("IT-11: Synthesis request after stripping code context",
+	slog.InfoContext(ctx, "IT-11: Synthesis request after stripping code context",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("message_count", len(synthRequest.Messages)),
 		slog.Int("total_input_bytes", totalInputLen),
@@ -3255,9 +3328,9 @@ CRITICAL RULES FOR SYNTHESIS — VIOLATING THESE PRODUCES HARMFUL OUTPUT:
 
 	response, err := p.callLLM(ctx, deps, synthRequest)
 	if err != nil {
-		slog.Error("GR-59 Rev 2: Synthesis LLM call failed",
+		slog.ErrorContext(ctx, "GR-59 Rev 2: Synthesis LLM call failed",
 			slog.String("session_id", deps.Session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return p.handleLLMError(deps, err)
 	}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.opentelemetry.io/otel/attribute"
@@ -237,7 +238,7 @@ func (p *ExecutePhase) filterBatchWithRouter(
 	// R-2 fix: Graceful degradation on timeout/error
 	if err != nil {
 		span.SetAttributes(attribute.Bool("router_error", true))
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 
 		if errors.Is(err, context.DeadlineExceeded) {
 			batchFilterTotal.WithLabelValues("timeout").Inc()
@@ -251,7 +252,7 @@ func (p *ExecutePhase) filterBatchWithRouter(
 
 		batchFilterTotal.WithLabelValues("error").Inc()
 		logger.Warn("GR-39a: Router call failed, using original batch",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.Int("batch_size", len(invocations)),
 		)
 		// Return original batch on error - graceful degradation
@@ -27,6 +27,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // conceptualResolutionResult holds the output of resolveConceptualName.
@@ -124,8 +125,8 @@ func resolveConceptualName(
 			if hasCallable {
 				return unchanged // Name exists in index with callable symbols
 			}
-			slog.Debug("IT-12 Rev 4: name exists but only as non-callable kinds, continuing resolution",
-				slog.String("name", name),
+			slog.DebugContext(ctx, "IT-12 Rev 4: name exists but only as non-callable kinds, continuing resolution",
+				telemetry.SensitiveLog("name", name),
 				slog.Int("matches", len(syms)),
 			)
 		}
@@ -263,7 +264,7 @@ func resolveConceptualName(
 			tier1Count++
 		}
 	}
-	slog.Info("IT-12: domain noun extraction",
+	slog.InfoContext(ctx, "IT-12: domain noun extraction",
 		slog.String("hallucinated", name),
 		slog.Any("name_tokens", nameTokens),
 		slog.Any("domain_nouns", domainNouns),
@@ -323,7 +324,7 @@ func resolveConceptualName(
 	// D3c Option 7: Auto-pick when exactly one candidate is an exact concept synonym.
 	// Fires AFTER reachability filter so unreachable exact matches are removed first.
 	if autoPick, ok := autoPickExactConceptMatch(symCandidates, conceptValues, domainNouns); ok {
-		slog.Info("D3c: auto-picked exact concept synonym match",
+		slog.InfoContext(ctx, "D3c: auto-picked exact concept synonym match",
 			slog.String("picked", autoPick.Name),
 			slog.String("hallucinated", name))
 		if session != nil {
@@ -351,7 +352,7 @@ func resolveConceptualName(
 		}
 	}
 
-	slog.Info("D3: post-prune tier counts",
+	slog.InfoContext(ctx, "D3: post-prune tier counts",
 		slog.String("hallucinated", name),
 		slog.Int("pruned_total", len(symCandidates)),
 		slog.Int("pruned_tier0", prunedTier0Count),
@@ -365,9 +366,9 @@ func resolveConceptualName(
 	resolved, err := extractor.ResolveConceptualSymbol(ctx, resolveQuery, symCandidates,
 		prunedTier0Count, prunedTier1Count, opts.SourceContext)
 	if err != nil {
-		slog.Warn("IT-12: conceptual symbol resolution failed",
+		slog.WarnContext(ctx, "IT-12: conceptual symbol resolution failed",
 			slog.String("hallucinated", name),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return unchanged
 	}
@@ -402,9 +403,9 @@ func resolveConceptualName(
 		})
 	}
 
-	slog.Info("IT-12: conceptual symbol resolution replaced hallucinated name",
+	slog.InfoContext(ctx, "IT-12: conceptual symbol resolution replaced hallucinated name",
 		slog.String("hallucinated", name),
-		slog.String("resolved", validated),
+		telemetry.SensitiveLog("resolved", validated),
 		slog.String("llm_pick", resolved),
 		slog.Bool("overridden", overridden),
 		slog.Int("candidates", len(symCandidates)),
@@ -863,16 +864,16 @@ func filterByReachability(
 		if pathResult != nil && len(pathResult.Path) > 0 {
 			reachable = append(reachable, c)
 		} else {
-			slog.Debug("D3c: filtering unreachable candidate",
-				slog.String("candidate", c.Name),
-				slog.String("from", fromID),
+			slog.DebugContext(ctx, "D3c: filtering unreachable candidate",
+				telemetry.SensitiveLog("candidate", c.Name),
+				telemetry.SensitiveLog("from", fromID),
 			)
 		}
 	}
 
 	// Graceful degradation: if all checked candidates are unreachable, return original
 	if len(reachable) == 0 {
-		slog.Info("D3c: all checked candidates unreachable, keeping original list",
+		slog.InfoContext(ctx, "D3c: all checked candidates unreachable, keeping original list",
 			slog.Int("checked", checkedCount),
 			slog.Int("total", len(candidates)),
 		)
@@ -1091,8 +1092,8 @@ func searchSymbolCandidates(
 		if err != nil {
 			continue
 		}
-		slog.Debug("IT-12: searchSymbolCandidates keyword result",
-			slog.String("keyword", kw),
+		slog.DebugContext(ctx, "IT-12: searchSymbolCandidates keyword result",
+			telemetry.SensitiveLog("keyword", kw),
 			slog.Int("raw_hits", len(results)),
 		)
 		for _, sym := range results {
@@ -23,6 +23,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -277,7 +278,7 @@ func (d *RepetitionDetector) IsSemanticallyRepetitive(
 	ctx, span := executePhaseTracer.Start(ctx, "RepetitionDetector.IsSemanticallyRepetitive",
 		trace.WithAttributes(
 			attribute.String("tool", tool),
-			attribute.String("query_preview", truncateQuery(query, 50)),
+			telemetry.SensitiveString("query_preview", truncateQuery(query, 50)),
 			attribute.Float64("threshold", d.threshold),
 		),
 	)
@@ -338,8 +339,8 @@ func (d *RepetitionDetector) IsSemanticallyRepetitive(
 	)
 
 	if maxSim >= d.threshold {
-		span.AddEvent("repetition_detected",
-			trace.WithAttributes(attribute.String("similar_to", simQuery)),
+		telemetry.AddSpanEvent(span, "repetition_detected",
+			telemetry.SensitiveString("similar_to", simQuery),
 		)
 		return true, maxSim, simQuery
 	}
@@ -455,7 +456,7 @@ func (p *ExecutePhase) checkSemanticRepetition(
 	ctx, span := executePhaseTracer.Start(ctx, "ExecutePhase.checkSemanticRepetition",
 		trace.WithAttributes(
 			attribute.String("tool", tool),
-			attribute.String("query_preview", truncateQuery(query, 50)),
+			telemetry.SensitiveString("query_preview", truncateQuery(query, 50)),
 		),
 	)
 	defer span.End()
@@ -508,11 +509,9 @@ func (p *ExecutePhase) checkSemanticRepetition(
 		// GR-38 Issue 14: Check for EXACT duplicate first (fast path)
 		// This catches cases like find_callees("main") called twice
 		if strings.EqualFold(query, prevQuery) {
-			span.AddEvent("exact_duplicate_detected",
-				trace.WithAttributes(
-					attribute.String("tool", tool),
-					attribute.String("query", query),
-				),
+			telemetry.AddSpanEvent(span, "exact_duplicate_detected",
+				attribute.String("tool", tool),
+				telemetry.SensitiveString("query", query),
 			)
 			return true, 1.0, prevQuery
 		}
@@ -538,10 +537,8 @@ func (p *ExecutePhase) checkSemanticRepetition(
 	)
 
 	if maxSimilarity >= semanticRepetitionThreshold {
-		span.AddEvent("semantic_repetition_detected",
-			trace.WithAttributes(
-				attribute.String("similar_to_query", similarQuery),
-			),
+		telemetry.AddSpanEvent(span, "semantic_repetition_detected",
+			telemetry.SensitiveString("similar_to_query", similarQuery),
 		)
 		return true, maxSimilarity, similarQuery
 	}
@@ -28,6 +28,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -185,6 +186,8 @@ func (p *ExecutePhase) emitLLMResponse(deps *Dependencies, response *llm.Respons
 
 	deps.EventEmitter.Emit(events.TypeLLMResponse, &events.LLMResponseData{
 		Model:            response.Model,
+		Provider:         response.Provider,
+		TokensIn:         response.InputTokens,
 		TokensOut:        response.OutputTokens,
 		Duration:         response.Duration,
 		StopReason:       response.StopReason,
@@ -224,7 +227,7 @@ func truncateArgs(args string, maxLen int) string {
 }
 
 // emitToolInvocation emits a tool invocation event.
-func (p *ExecutePhase) emitToolInvocation(deps *Dependencies, inv *agent.ToolInvocation) {
+func (p *ExecutePhase) emitToolInvocation(deps *Dependencies, inv *agent.ToolInvocation, stepIndex int) {
 	if deps.EventEmitter == nil {
 		return
 	}
@@ -234,6 +237,7 @@ func (p *ExecutePhase) emitToolInvocation(deps *Dependencies, inv *agent.ToolInv
 		ToolName:     inv.Tool,
 		InvocationID: inv.ID,
 		Parameters:   toolParamsToEventParams(inv.Parameters),
+		StepIndex:    stepIndex,
 	})
 }
 
@@ -251,22 +255,55 @@ func toolParamsToEventParams(params *agent.ToolParameters) *events.ToolInvocatio
 }
 
 // emitToolResult emits a tool result event.
-func (p *ExecutePhase) emitToolResult(deps *Dependencies, inv *agent.ToolInvocation, result *tools.Result) {
+func (p *ExecutePhase) emitToolResult(deps *Dependencies, inv *agent.ToolInvocation, stepIndex int, result *tools.Result) {
 	if deps.EventEmitter == nil {
 		return
 	}
 
 	deps.EventEmitter.Emit(events.TypeToolResult, &events.ToolResultData{
-		ToolName:     inv.Tool,
-		InvocationID: inv.ID,
-		Success:      result.Success,
-		Duration:     result.Duration,
-		TokensUsed:   result.TokensUsed,
-		Cached:       result.Cached,
-		Error:        result.Error,
+		ToolName:        inv.Tool,
+		InvocationID:    inv.ID,
+		StepIndex:       stepIndex,
+		Success:         result.Success,
+		Duration:        result.Duration,
+		TokensUsed:      result.TokensUsed,
+		Cached:          result.Cached,
+		CacheGeneration: resultCacheGeneration(result),
+		Error:           result.Error,
+		Truncated:       result.Truncated,
+		LimitHit:        resultLimitHit(result),
 	})
 }
 
+// resultCacheGeneration returns the graph generation of a result served
+// from the shared tool result cache, if any.
+func resultCacheGeneration(result *tools.Result) string {
+	if result.Metadata == nil {
+		return ""
+	}
+	generation, _ := result.Metadata[tools.MetaCacheGeneration].(string)
+	return generation
+}
+
+// resultLimitHit returns the sandbox limit recorded on a tool result, if any.
+func resultLimitHit(result *tools.Result) string {
+	if result.Metadata == nil {
+		return ""
+	}
+	limit, _ := result.Metadata[tools.MetaLimitHit].(string)
+	return limit
+}
+
+// resultInvalidParameter returns the argument a tool result was rejected
+// for, if any.
+func resultInvalidParameter(result *tools.Result) string {
+	if result.Metadata == nil {
+		return ""
+	}
+	param, _ := result.Metadata[tools.MetaInvalidParameter].(string)
+	return param
+}
+
 // emitSafetyCheck emits a safety check event.
 func (p *ExecutePhase) emitSafetyCheck(deps *Dependencies, result *safety.Result) {
 	if deps.EventEmitter == nil {
@@ -282,6 +319,44 @@ func (p *ExecutePhase) emitSafetyCheck(deps *Dependencies, result *safety.Result
 	})
 }
 
+// emitPolicyDenial emits a safety check event for a call the safety
+// policy denied.
+func (p *ExecutePhase) emitPolicyDenial(deps *Dependencies, decision *safety.Decision) {
+	if deps.EventEmitter == nil {
+		return
+	}
+
+	deps.EventEmitter.Emit(events.TypeSafetyCheck, &events.SafetyCheckData{
+		ChangesChecked: 1,
+		Passed:         false,
+		Blocked:        true,
+		Tool:           decision.Tool,
+		Mode:           decision.Mode,
+		RuleID:         decision.RuleID,
+		Reason:         decision.Reason,
+	})
+}
+
+// recordRoutingDecision stores a routing decision on the session and emits
+// it as a routing_decision event.
+func (p *ExecutePhase) recordRoutingDecision(deps *Dependencies, decision *agent.RoutingDecision) {
+	if decision == nil {
+		return
+	}
+	if deps.Session != nil {
+		deps.Session.RecordRoutingDecision(*decision)
+	}
+	if deps.EventEmitter == nil {
+		return
+	}
+
+	record := *decision
+	record.Scores = nil
+	deps.EventEmitter.Emit(events.TypeRoutingDecision, &events.RoutingDecisionData{
+		Decision: record,
+	})
+}
+
 // emitStepComplete emits a step complete event.
 func (p *ExecutePhase) emitStepComplete(deps *Dependencies, stepStart time.Time, stepNumber, toolsInvoked int) {
 	if deps.EventEmitter == nil {
@@ -402,7 +477,7 @@ func (p *ExecutePhase) emitCoordinatorEvent(
 		data.Graph = p.buildGraphContextForTool(inv, result)
 		if data.Graph != nil {
 			// Use Info level so it appears in server logs (test 8 greps for "graph_context")
-			slog.Info("GR-30: Event with graph_context",
+			slog.InfoContext(ctx, "GR-30: Event with graph_context",
 				slog.String("event", string(event)),
 				slog.String("tool", inv.Tool),
 				slog.Int("node_count", data.Graph.NodeCount),
@@ -414,14 +489,14 @@ func (p *ExecutePhase) emitCoordinatorEvent(
 	// Handle the event - activities run synchronously in priority order
 	results, err := deps.Coordinator.HandleEvent(ctx, event, data)
 	if err != nil {
-		slog.Warn("CRS-06: Coordinator event handling failed",
+		slog.WarnContext(ctx, "CRS-06: Coordinator event handling failed",
 			slog.String("event", string(event)),
 			slog.String("session_id", deps.Session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 	} else {
 		// CR-11 fix: Log result count for observability
-		slog.Debug("CRS-06: Coordinator event handled",
+		slog.DebugContext(ctx, "CRS-06: Coordinator event handled",
 			slog.String("event", string(event)),
 			slog.String("session_id", deps.Session.ID),
 			slog.Int("activities_run", len(results)),
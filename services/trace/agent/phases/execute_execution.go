@@ -34,6 +34,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -49,7 +50,9 @@ import (
 //
 //	Iterates through tool invocations, executing each one with safety checks,
 //	circuit breaker checks, and CRS integration. Records trace steps and
-//	updates proof numbers based on execution outcomes.
+//	updates proof numbers based on execution outcomes. Steps without a data
+//	dependency run concurrently, up to parallelToolLimit at a time; results
+//	keep plan order.
 //
 // Inputs:
 //
@@ -71,7 +74,7 @@ func (p *ExecutePhase) executeToolCalls(ctx context.Context, deps *Dependencies,
 		router := deps.Session.GetToolRouter()
 		if router != nil {
 			if bf, ok := router.(BatchFilterer); ok {
-				slog.Debug("GR-39a: Batch filter check triggered",
+				slog.DebugContext(ctx, "GR-39a: Batch filter check triggered",
 					slog.String("session_id", deps.Session.ID),
 					slog.Int("batch_size", batchSize),
 					slog.Int("min_size", batchFilterMinSize),
@@ -80,13 +83,13 @@ func (p *ExecutePhase) executeToolCalls(ctx context.Context, deps *Dependencies,
 
 				filtered, err := p.filterBatchWithRouter(ctx, deps, invocations)
 				if err != nil {
-					slog.Warn("GR-39a: Batch filter error, using original batch",
+					slog.WarnContext(ctx, "GR-39a: Batch filter error, using original batch",
 						slog.String("session_id", deps.Session.ID),
-						slog.String("error", err.Error()),
+						telemetry.SensitiveLogError("error", err),
 					)
 					// Continue with original batch on error
 				} else if len(filtered) < batchSize {
-					slog.Info("GR-39a: Batch filtered before execution",
+					slog.InfoContext(ctx, "GR-39a: Batch filtered before execution",
 						slog.String("session_id", deps.Session.ID),
 						slog.Int("original", batchSize),
 						slog.Int("filtered", len(filtered)),
@@ -94,24 +97,24 @@ func (p *ExecutePhase) executeToolCalls(ctx context.Context, deps *Dependencies,
 					)
 					invocations = filtered
 				} else {
-					slog.Debug("GR-39a: Batch filter kept all tools",
+					slog.DebugContext(ctx, "GR-39a: Batch filter kept all tools",
 						slog.String("session_id", deps.Session.ID),
 						slog.Int("batch_size", batchSize),
 					)
 				}
 			} else {
-				slog.Debug("GR-39a: Router does not implement BatchFilterer",
+				slog.DebugContext(ctx, "GR-39a: Router does not implement BatchFilterer",
 					slog.String("session_id", deps.Session.ID),
 					slog.String("router_type", fmt.Sprintf("%T", router)),
 				)
 			}
 		} else {
-			slog.Debug("GR-39a: No router available for batch filtering",
+			slog.DebugContext(ctx, "GR-39a: No router available for batch filtering",
 				slog.String("session_id", deps.Session.ID),
 			)
 		}
 	} else if batchSize > 0 && batchSize < batchFilterMinSize {
-		slog.Debug("GR-39a: Batch too small for filtering",
+		slog.DebugContext(ctx, "GR-39a: Batch too small for filtering",
 			slog.Int("batch_size", batchSize),
 			slog.Int("min_size", batchFilterMinSize),
 		)
@@ -129,392 +132,460 @@ func (p *ExecutePhase) executeToolCalls(ctx context.Context, deps *Dependencies,
 	// This counts ALL tool calls (router + LLM paths) from session trace steps.
 	toolCounts := buildToolCountMapFromSession(deps.Session)
 
-	for i, inv := range invocations {
-		// GR-39 Issue 3: Emit routing decision for batch-executed tools.
-		// This ensures all tool calls have routing trace steps, not just router-selected ones.
-		p.emitToolRouting(deps, &agent.ToolRouterSelection{
-			Tool:       inv.Tool,
-			Confidence: 1.0, // Batch calls have implicit full confidence from LLM
-			Reasoning:  "batch_execution",
-			Duration:   0,
-		})
-
-		// Refresh graph if dirty files exist (before tool queries stale data)
-		p.maybeRefreshGraph(ctx, deps)
-
-		// CRS-19: Check graph staleness before tool dispatch.
-		// Runs once per session (cached for 60s). Logs warning/error if files changed.
-		var stalenessResult *graph.StalenessResult
-		if deps.StalenessChecker != nil {
-			sr := deps.StalenessChecker.Check()
-			stalenessResult = &sr
+	// Steps without a data dependency run concurrently in waves of up to
+	// parallelToolLimit tools. Everything around the execution itself (events,
+	// safety, circuit breakers, trace steps) stays sequential in plan order.
+	var failedGroups map[string]int
+	var parallelCalls int
+	var timeSaved time.Duration
+	stop := false
+	defer func() { p.recordParallelSavings(ctx, deps, parallelCalls, timeSaved) }()
+
+	for _, wave := range p.planToolWaves(deps, invocations) {
+		// An aborted run does not start the tools still queued in the batch.
+		if err := ctx.Err(); err != nil {
+			slog.InfoContext(ctx, "Run cancelled, skipping queued tool calls",
+				slog.Int("skipped", len(invocations)-wave[0]),
+				slog.String("reason", context.Cause(ctx).Error()),
+			)
+			return results, invocations[:len(results)], blocked
 		}
 
-		// Emit tool invocation event
-		p.emitToolInvocation(deps, &inv)
+		// slots holds the wave's results in plan order; pending are the
+		// steps that passed preflight and still have to run.
+		slots := make([]*tools.Result, 0, len(wave))
+		pending := make([]*pendingToolCall, 0, len(wave))
 
-		// Run safety check if required
-		if p.requireSafetyCheck {
-			// Generate node ID for CDCL constraint extraction
-			nodeID := fmt.Sprintf("tool_%s_%d", inv.Tool, i)
-			safetyResult := p.isBlockedBySafety(ctx, deps, &inv, nodeID)
+		for _, i := range wave {
+			inv := invocations[i]
 
-			if safetyResult.Blocked {
-				blocked = true
-				results = append(results, &tools.Result{
-					Success: false,
-					Error:   safetyResult.ErrorMessage,
-				})
-				// Record blocked trace step
-				p.recordTraceStep(deps, &inv, nil, 0, safetyResult.ErrorMessage)
-
-				// Record safety violation for CDCL learning (Issue #6)
-				// Safety violations are hard signals - CDCL should learn to avoid them
-				if deps.Session != nil && len(safetyResult.Constraints) > 0 {
-					deps.Session.RecordSafetyViolation(
-						nodeID,
-						safetyResult.ErrorMessage,
-						safetyResult.Constraints,
-					)
-				}
+			// GR-39 Issue 3: Emit routing decision for batch-executed tools.
+			// This ensures all tool calls have routing trace steps, not just router-selected ones.
+			p.emitToolRouting(deps, &agent.ToolRouterSelection{
+				Tool:       inv.Tool,
+				Confidence: 1.0, // Batch calls have implicit full confidence from LLM
+				Reasoning:  "batch_execution",
+				Duration:   0,
+			})
 
-				// CRS-04: Learn from safety violation
-				p.learnFromFailure(ctx, deps, crs.FailureEvent{
-					SessionID:    deps.Session.ID,
-					FailureType:  crs.FailureTypeSafety,
-					Tool:         inv.Tool,
-					ErrorMessage: safetyResult.ErrorMessage,
-					Source:       crs.SignalSourceSafety,
-				})
+			// Refresh graph if dirty files exist (before tool queries stale data)
+			p.maybeRefreshGraph(ctx, deps)
 
-				// CRS-02: Mark tool path as disproven due to safety violation.
-				// Safety violations are hard signals - the path cannot lead to a solution.
-				p.markToolDisproven(ctx, deps, &inv, "safety_violation: "+safetyResult.ErrorMessage)
-				continue
+			// CRS-19: Check graph staleness before tool dispatch.
+			// Runs once per session (cached for 60s). Logs warning/error if files changed.
+			var stalenessResult *graph.StalenessResult
+			if deps.StalenessChecker != nil {
+				sr := deps.StalenessChecker.Check()
+				stalenessResult = &sr
 			}
-		}
-
-		// GR-39b: Count-based circuit breaker check BEFORE semantic check.
-		// This blocks tool calls after N=2 calls regardless of query similarity.
-		// The semantic check (CB-30c) catches variations with similarity >= 0.7,
-		// but LLMs can produce queries with < 0.7 similarity (e.g., "main" vs "func main").
-		// Count-based check provides a hard stop after threshold is reached.
-		if deps.Session != nil {
-			callCount := toolCounts[inv.Tool]
-			if callCount >= crs.DefaultCircuitBreakerThreshold {
-				slog.Info("GR-39b: Count-based circuit breaker fired in LLM path",
-					slog.String("session_id", deps.Session.ID),
-					slog.String("tool", inv.Tool),
-					slog.Int("call_count", callCount),
-					slog.Int("threshold", crs.DefaultCircuitBreakerThreshold),
-				)
-
-				// Record metric
-				grounding.RecordCountCircuitBreaker(inv.Tool, "llm")
-
-				// Record trace step for observability
-				// CB-31d Item 3: Don't use Error field for expected circuit breaker activations.
-				// Error field causes these to be displayed at ERROR level in test output.
-				deps.Session.RecordTraceStep(crs.TraceStep{
-					Action: "circuit_breaker",
-					Tool:   inv.Tool,
-					Metadata: map[string]string{
-						"path":      "llm",
-						"count":     fmt.Sprintf("%d", callCount),
-						"threshold": fmt.Sprintf("%d", crs.DefaultCircuitBreakerThreshold),
-						"reason":    fmt.Sprintf("GR-39b: count threshold exceeded (%d >= %d)", callCount, crs.DefaultCircuitBreakerThreshold),
-						"expected":  "true", // This is expected behavior, not an error
-					},
-				})
-
-				// Add span event for tracing
-				span := trace.SpanFromContext(ctx)
-				if span.IsRecording() {
-					span.AddEvent("count_circuit_breaker_fired",
-						trace.WithAttributes(
-							attribute.String("tool", inv.Tool),
-							attribute.Int("count", callCount),
-							attribute.String("path", "llm"),
-						),
-					)
-				}
 
-				// Learn from repeated calls (CDCL clause generation)
-				p.learnFromFailure(ctx, deps, crs.FailureEvent{
-					SessionID:    deps.Session.ID,
-					FailureType:  crs.FailureTypeCircuitBreaker,
-					Tool:         inv.Tool,
-					ErrorMessage: "GR-39b: LLM path count threshold exceeded",
-					Source:       crs.SignalSourceHard,
-				})
+			// Emit tool invocation event
+			p.emitToolInvocation(deps, &inv, i)
 
-				// Emit coordinator event for activity orchestration
-				p.emitCoordinatorEvent(ctx, deps, integration.EventCircuitBreaker, &inv, nil,
-					fmt.Sprintf("GR-39b: %s count threshold exceeded (%d >= %d)", inv.Tool, callCount, crs.DefaultCircuitBreakerThreshold),
-					crs.ErrorCategoryInternal)
-
-				// GR-44 Rev 2: Set circuit breaker active in LLM path.
-				// This ensures handleCompletion knows CB has fired and won't
-				// send "Your response didn't use tools as required" messages.
-				deps.Session.SetCircuitBreakerActive(true)
-				slog.Debug("GR-44 Rev 2: CB flag set in LLM path (count-based)",
-					slog.String("session_id", deps.Session.ID),
-					slog.String("tool", inv.Tool),
-				)
-
-				// GR-59 Group B Part 1: Stronger CB message that signals finality.
-				results = append(results, &tools.Result{
+			// A failed step cancels the steps of its plan group that have not run yet.
+			if failedStep, failed := failedGroups[inv.Group]; failed && inv.Group != "" {
+				skipped := &tools.Result{
 					Success: false,
-					Error: fmt.Sprintf("GR-39b: Tool %s PERMANENTLY BLOCKED (called %d times, threshold: %d). "+
-						"You MUST provide your answer NOW using the results you already have. "+
-						"Do NOT call any search tools.", inv.Tool, callCount, crs.DefaultCircuitBreakerThreshold),
-				})
-				blocked = true
-
-				// GR-59 Group B Part 2: Track consecutive CB fires.
-				if consecutiveCBFires == nil {
-					consecutiveCBFires = make(map[string]int)
-				}
-				if lastCBTool == inv.Tool {
-					consecutiveCBFires[inv.Tool]++
-				} else {
-					consecutiveCBFires[inv.Tool] = 1
-					lastCBTool = inv.Tool
+					Error:   fmt.Sprintf("skipped: step %d of group %q failed", failedStep, inv.Group),
 				}
-
-				// If 2+ consecutive CB fires for the same tool, force immediate return.
-				// This eliminates 5+ wasted LLM round-trips after CB activation.
-				if consecutiveCBFires[inv.Tool] >= 2 {
-					slog.Info("GR-59: Consecutive CB fires forcing immediate synthesis",
-						slog.String("session_id", deps.Session.ID),
-						slog.String("tool", inv.Tool),
-						slog.Int("consecutive_fires", consecutiveCBFires[inv.Tool]),
-					)
-					return results, invocations, true
-				}
-
+				slots = append(slots, skipped)
+				p.recordTraceStep(deps, &inv, skipped, 0, skipped.Error)
+				p.emitToolResult(deps, &inv, i, skipped)
 				continue
 			}
-		}
 
-		// GR-39b: Increment count for this tool (for within-batch duplicate detection).
-		// Must happen AFTER circuit breaker check passes but BEFORE execution.
-		toolCounts[inv.Tool]++
+			// Run safety check if required
+			if p.requireSafetyCheck {
+				// Generate node ID for CDCL constraint extraction
+				nodeID := fmt.Sprintf("tool_%s_%d", inv.Tool, i)
+				safetyResult := p.isBlockedBySafety(ctx, deps, &inv, nodeID)
 
-		// CB-30c: Check for semantic repetition BEFORE executing the tool.
-		// This catches cases where the main LLM (not router) calls similar tools repeatedly.
-		if deps.Session != nil {
-			toolQuery := extractToolQuery(&inv)
-			if toolQuery != "" {
-				isRepetitive, similarity, similarQuery := p.checkSemanticRepetition(ctx, deps, inv.Tool, toolQuery)
-
-				// CRS-15: Record similarity to CRS for cross-session persistence.
-				if similarity > 0 && similarQuery != "" && deps.Session.HasCRS() {
-					currentKey := fmt.Sprintf("%s:%s", inv.Tool, toolQuery)
-					previousKey := fmt.Sprintf("%s:%s", inv.Tool, similarQuery)
-					if recErr := deps.Session.GetCRS().RecordSimilarity(ctx, currentKey, previousKey, 1.0-similarity); recErr != nil {
-						slog.Debug("CRS-15: Failed to record similarity",
-							slog.String("error", recErr.Error()),
+				if safetyResult.Blocked {
+					blocked = true
+					slots = append(slots, &tools.Result{
+						Success: false,
+						Error:   safetyResult.ErrorMessage,
+					})
+					// Record blocked trace step
+					p.recordTraceStep(deps, &inv, nil, 0, safetyResult.ErrorMessage)
+
+					// Record safety violation for CDCL learning (Issue #6)
+					// Safety violations are hard signals - CDCL should learn to avoid them
+					if deps.Session != nil && len(safetyResult.Constraints) > 0 {
+						deps.Session.RecordSafetyViolation(
+							nodeID,
+							safetyResult.ErrorMessage,
+							safetyResult.Constraints,
 						)
 					}
+
+					// CRS-04: Learn from safety violation
+					p.learnFromFailure(ctx, deps, crs.FailureEvent{
+						SessionID:    deps.Session.ID,
+						FailureType:  crs.FailureTypeSafety,
+						Tool:         inv.Tool,
+						ErrorMessage: safetyResult.ErrorMessage,
+						Source:       crs.SignalSourceSafety,
+					})
+
+					// CRS-02: Mark tool path as disproven due to safety violation.
+					// Safety violations are hard signals - the path cannot lead to a solution.
+					p.markToolDisproven(ctx, deps, &inv, "safety_violation: "+safetyResult.ErrorMessage)
+					continue
 				}
+			}
 
-				if isRepetitive {
-					slog.Info("CB-30c: Blocking semantically repetitive tool call",
+			// GR-39b: Count-based circuit breaker check BEFORE semantic check.
+			// This blocks tool calls after N=2 calls regardless of query similarity.
+			// The semantic check (CB-30c) catches variations with similarity >= 0.7,
+			// but LLMs can produce queries with < 0.7 similarity (e.g., "main" vs "func main").
+			// Count-based check provides a hard stop after threshold is reached.
+			if deps.Session != nil {
+				callCount := toolCounts[inv.Tool]
+				if callCount >= crs.DefaultCircuitBreakerThreshold {
+					slog.InfoContext(ctx, "GR-39b: Count-based circuit breaker fired in LLM path",
 						slog.String("session_id", deps.Session.ID),
 						slog.String("tool", inv.Tool),
-						slog.String("query", toolQuery),
-						slog.Float64("similarity", similarity),
-						slog.String("similar_to", similarQuery),
+						slog.Int("call_count", callCount),
+						slog.Int("threshold", crs.DefaultCircuitBreakerThreshold),
 					)
 
 					// Record metric
-					grounding.RecordSemanticRepetition(inv.Tool, similarity, inv.Tool)
+					grounding.RecordCountCircuitBreaker(inv.Tool, "llm")
+
+					// Record trace step for observability
+					// CB-31d Item 3: Don't use Error field for expected circuit breaker activations.
+					// Error field causes these to be displayed at ERROR level in test output.
+					deps.Session.RecordTraceStep(crs.TraceStep{
+						Action: "circuit_breaker",
+						Tool:   inv.Tool,
+						Metadata: map[string]string{
+							"path":      "llm",
+							"count":     fmt.Sprintf("%d", callCount),
+							"threshold": fmt.Sprintf("%d", crs.DefaultCircuitBreakerThreshold),
+							"reason":    fmt.Sprintf("GR-39b: count threshold exceeded (%d >= %d)", callCount, crs.DefaultCircuitBreakerThreshold),
+							"expected":  "true", // This is expected behavior, not an error
+						},
+					})
+
+					// Add span event for tracing
+					span := trace.SpanFromContext(ctx)
+					if span.IsRecording() {
+						span.AddEvent("count_circuit_breaker_fired",
+							trace.WithAttributes(
+								attribute.String("tool", inv.Tool),
+								attribute.Int("count", callCount),
+								attribute.String("path", "llm"),
+							),
+						)
+					}
 
-					// Learn from repetition
+					// Learn from repeated calls (CDCL clause generation)
 					p.learnFromFailure(ctx, deps, crs.FailureEvent{
-						SessionID:   deps.Session.ID,
-						FailureType: crs.FailureTypeSemanticRepetition,
-						Tool:        inv.Tool,
-						Source:      crs.SignalSourceHard,
+						SessionID:    deps.Session.ID,
+						FailureType:  crs.FailureTypeCircuitBreaker,
+						Tool:         inv.Tool,
+						ErrorMessage: "GR-39b: LLM path count threshold exceeded",
+						Source:       crs.SignalSourceHard,
 					})
 
-					// Emit event
-					p.emitCoordinatorEvent(ctx, deps, integration.EventSemanticRepetition, &inv, nil,
-						fmt.Sprintf("query %.0f%% similar to '%s'", similarity*100, truncateQuery(similarQuery, 30)),
+					// Emit coordinator event for activity orchestration
+					p.emitCoordinatorEvent(ctx, deps, integration.EventCircuitBreaker, &inv, nil,
+						fmt.Sprintf("GR-39b: %s count threshold exceeded (%d >= %d)", inv.Tool, callCount, crs.DefaultCircuitBreakerThreshold),
 						crs.ErrorCategoryInternal)
 
 					// GR-44 Rev 2: Set circuit breaker active in LLM path.
 					// This ensures handleCompletion knows CB has fired and won't
 					// send "Your response didn't use tools as required" messages.
 					deps.Session.SetCircuitBreakerActive(true)
-					slog.Debug("GR-44 Rev 2: CB flag set in LLM path (semantic repetition)",
+					slog.DebugContext(ctx, "GR-44 Rev 2: CB flag set in LLM path (count-based)",
 						slog.String("session_id", deps.Session.ID),
 						slog.String("tool", inv.Tool),
 					)
 
-					// Return a result that indicates semantic repetition
-					// This will cause the completion handler to synthesize instead
-					results = append(results, &tools.Result{
+					// GR-59 Group B Part 1: Stronger CB message that signals finality.
+					slots = append(slots, &tools.Result{
 						Success: false,
-						Error:   fmt.Sprintf("Semantic repetition detected: query %.0f%% similar to previous. Synthesize from existing results.", similarity*100),
+						Error: fmt.Sprintf("GR-39b: Tool %s PERMANENTLY BLOCKED (called %d times, threshold: %d). "+
+							"You MUST provide your answer NOW using the results you already have. "+
+							"Do NOT call any search tools.", inv.Tool, callCount, crs.DefaultCircuitBreakerThreshold),
 					})
 					blocked = true
+
+					// GR-59 Group B Part 2: Track consecutive CB fires.
+					if consecutiveCBFires == nil {
+						consecutiveCBFires = make(map[string]int)
+					}
+					if lastCBTool == inv.Tool {
+						consecutiveCBFires[inv.Tool]++
+					} else {
+						consecutiveCBFires[inv.Tool] = 1
+						lastCBTool = inv.Tool
+					}
+
+					// If 2+ consecutive CB fires for the same tool, force immediate return.
+					// This eliminates 5+ wasted LLM round-trips after CB activation.
+					if consecutiveCBFires[inv.Tool] >= 2 {
+						slog.InfoContext(ctx, "GR-59: Consecutive CB fires forcing immediate synthesis",
+							slog.String("session_id", deps.Session.ID),
+							slog.String("tool", inv.Tool),
+							slog.Int("consecutive_fires", consecutiveCBFires[inv.Tool]),
+						)
+						stop = true
+						break
+					}
+
 					continue
 				}
 			}
-		}
 
-		// GR-59 Group B: Reset consecutive CB counter when a different tool is called
-		// (the LLM changed strategy, so give it a chance).
-		if lastCBTool != "" && inv.Tool != lastCBTool {
-			lastCBTool = ""
-		}
+			// GR-39b: Increment count for this tool (for within-batch duplicate detection).
+			// Must happen AFTER circuit breaker check passes but BEFORE execution.
+			toolCounts[inv.Tool]++
+
+			// CB-30c: Check for semantic repetition BEFORE executing the tool.
+			// This catches cases where the main LLM (not router) calls similar tools repeatedly.
+			if deps.Session != nil {
+				toolQuery := extractToolQuery(&inv)
+				if toolQuery != "" {
+					isRepetitive, similarity, similarQuery := p.checkSemanticRepetition(ctx, deps, inv.Tool, toolQuery)
+
+					// CRS-15: Record similarity to CRS for cross-session persistence.
+					if similarity > 0 && similarQuery != "" && deps.Session.HasCRS() {
+						currentKey := fmt.Sprintf("%s:%s", inv.Tool, toolQuery)
+						previousKey := fmt.Sprintf("%s:%s", inv.Tool, similarQuery)
+						if recErr := deps.Session.GetCRS().RecordSimilarity(ctx, currentKey, previousKey, 1.0-similarity); recErr != nil {
+							slog.DebugContext(ctx, "CRS-15: Failed to record similarity",
+								telemetry.SensitiveLogError("error", recErr),
+							)
+						}
+					}
 
-		// Execute the tool with timing
-		toolStart := time.Now()
-		result := p.executeSingleTool(ctx, deps, &inv)
-		toolDuration := time.Since(toolStart)
-		results = append(results, result)
+					if isRepetitive {
+						slog.InfoContext(ctx, "CB-30c: Blocking semantically repetitive tool call",
+							slog.String("session_id", deps.Session.ID),
+							slog.String("tool", inv.Tool),
+							telemetry.SensitiveLog("query", toolQuery),
+							slog.Float64("similarity", similarity),
+							telemetry.SensitiveLog("similar_to", similarQuery),
+						)
 
-		// Record trace step for this tool call
-		errMsg := ""
-		if !result.Success {
-			errMsg = result.Error
+						// Record metric
+						grounding.RecordSemanticRepetition(inv.Tool, similarity, inv.Tool)
 
-			// Phase 11B: Convert "not found" errors to successful informational results (Feb 14, 2026)
-			// When a tool definitively determines a symbol doesn't exist, that's a VALID RESULT,
-			// not an error. Convert to Success=true to prevent LLM retry loops.
-			if isNotFoundError(errMsg) {
-				slog.Info("Phase 11B: Converting 'not found' error to informational result",
-					slog.String("session_id", deps.Session.ID),
-					slog.String("tool", inv.Tool),
-					slog.String("original_error", errMsg),
-				)
+						// Learn from repetition
+						p.learnFromFailure(ctx, deps, crs.FailureEvent{
+							SessionID:   deps.Session.ID,
+							FailureType: crs.FailureTypeSemanticRepetition,
+							Tool:        inv.Tool,
+							Source:      crs.SignalSourceHard,
+						})
+
+						// Emit event
+						p.emitCoordinatorEvent(ctx, deps, integration.EventSemanticRepetition, &inv, nil,
+							fmt.Sprintf("query %.0f%% similar to '%s'", similarity*100, truncateQuery(similarQuery, 30)),
+							crs.ErrorCategoryInternal)
+
+						// GR-44 Rev 2: Set circuit breaker active in LLM path.
+						// This ensures handleCompletion knows CB has fired and won't
+						// send "Your response didn't use tools as required" messages.
+						deps.Session.SetCircuitBreakerActive(true)
+						slog.DebugContext(ctx, "GR-44 Rev 2: CB flag set in LLM path (semantic repetition)",
+							slog.String("session_id", deps.Session.ID),
+							slog.String("tool", inv.Tool),
+						)
 
-				// Build informational output
-				infoText := fmt.Sprintf("## Search Result: Not Found\n\n"+
-					"The requested symbol was not found in the codebase.\n\n"+
-					"Original message: %s\n\n"+
-					"The graph has been fully indexed - this is the definitive answer.\n"+
-					"**Do NOT use Grep to search further** - the graph already analyzed all source files.\n",
-					errMsg)
-
-				// Replace the failed result with a successful informational result
-				result.Success = true
-				result.Error = ""
-				result.Output = infoText
-				result.OutputText = infoText
-				if result.TokensUsed == 0 {
-					result.TokensUsed = len(infoText) / 4 // Estimate tokens (simple heuristic)
+						// Return a result that indicates semantic repetition
+						// This will cause the completion handler to synthesize instead
+						slots = append(slots, &tools.Result{
+							Success: false,
+							Error:   fmt.Sprintf("Semantic repetition detected: query %.0f%% similar to previous. Synthesize from existing results.", similarity*100),
+						})
+						blocked = true
+						continue
+					}
 				}
-				results[len(results)-1] = result // Update the result we just added
-				errMsg = ""                      // Clear error since we converted to success
 			}
 
-			// P0-3: Detect validation errors and force synthesis (Feb 14, 2026)
-			// If tool failed due to parameter validation, mark circuit breaker as active
-			// to force synthesis from existing tool results instead of retrying.
-			if strings.Contains(errMsg, "parameter validation") ||
-				strings.Contains(errMsg, "required parameter missing") ||
-				strings.Contains(errMsg, "validation failed") {
-				slog.Warn("P0-3: Validation error detected, will force synthesis",
-					slog.String("session_id", deps.Session.ID),
-					slog.String("tool", inv.Tool),
-					slog.String("error", errMsg),
-				)
+			// GR-59 Group B: Reset consecutive CB counter when a different tool is called
+			// (the LLM changed strategy, so give it a chance).
+			if lastCBTool != "" && inv.Tool != lastCBTool {
+				lastCBTool = ""
+			}
 
-				// Set circuit breaker flag to prevent LLM from retrying with same tool
-				if deps.Session != nil {
-					deps.Session.SetCircuitBreakerActive(true)
-					slog.Debug("P0-3: Circuit breaker activated due to validation failure",
+			slots = append(slots, nil)
+			pending = append(pending, &pendingToolCall{
+				index:     i,
+				slot:      len(slots) - 1,
+				inv:       inv,
+				staleness: stalenessResult,
+			})
+		}
+
+		// Execute the wave's tools with timing
+		if saved := p.runToolWave(ctx, deps, pending); len(pending) > 1 {
+			parallelCalls += len(pending)
+			timeSaved += saved
+		}
+
+		for _, call := range pending {
+			inv := call.inv
+			result := call.result
+			toolDuration := call.duration
+			stalenessResult := call.staleness
+			slots[call.slot] = result
+			if inv.Group != "" && !result.Success && !isNotFoundError(result.Error) {
+				if failedGroups == nil {
+					failedGroups = make(map[string]int)
+				}
+				if _, seen := failedGroups[inv.Group]; !seen {
+					failedGroups[inv.Group] = call.index
+				}
+			}
+
+			// Record trace step for this tool call
+			errMsg := ""
+			if !result.Success {
+				errMsg = result.Error
+
+				// Phase 11B: Convert "not found" errors to successful informational results (Feb 14, 2026)
+				// When a tool definitively determines a symbol doesn't exist, that's a VALID RESULT,
+				// not an error. Convert to Success=true to prevent LLM retry loops.
+				if isNotFoundError(errMsg) {
+					slog.InfoContext(ctx, "Phase 11B: Converting 'not found' error to informational result",
 						slog.String("session_id", deps.Session.ID),
 						slog.String("tool", inv.Tool),
+						slog.String("original_error", errMsg),
 					)
-				}
-			}
 
-			// Record error for router feedback
-			if deps.Session != nil {
-				deps.Session.RecordToolError(inv.Tool, errMsg)
-			}
+					// Build informational output
+					infoText := fmt.Sprintf("## Search Result: Not Found\n\n"+
+						"The requested symbol was not found in the codebase.\n\n"+
+						"Original message: %s\n\n"+
+						"The graph has been fully indexed - this is the definitive answer.\n"+
+						"**Do NOT use Grep to search further** - the graph already analyzed all source files.\n",
+						errMsg)
+
+					// Replace the failed result with a successful informational result
+					result.Success = true
+					result.Error = ""
+					result.Output = infoText
+					result.OutputText = infoText
+					if result.TokensUsed == 0 {
+						result.TokensUsed = len(infoText) / 4 // Estimate tokens (simple heuristic)
+					}
+					errMsg = "" // Clear error since we converted to success
+				}
 
-			// CRS-04: Learn from tool execution error
-			// Determine error category from error message
-			errorCategory := categorizeToolError(errMsg)
-			p.learnFromFailure(ctx, deps, crs.FailureEvent{
-				SessionID:     deps.Session.ID,
-				FailureType:   crs.FailureTypeToolError,
-				Tool:          inv.Tool,
-				ErrorMessage:  errMsg,
-				ErrorCategory: errorCategory,
-				Source:        crs.SignalSourceHard,
-			})
+				// P0-3: Detect validation errors and force synthesis (Feb 14, 2026)
+				// If tool failed due to parameter validation, mark circuit breaker as active
+				// to force synthesis from existing tool results instead of retrying.
+				if strings.Contains(errMsg, "parameter validation") ||
+					strings.Contains(errMsg, "required parameter missing") ||
+					strings.Contains(errMsg, "validation failed") {
+					slog.WarnContext(ctx, "P0-3: Validation error detected, will force synthesis",
+						slog.String("session_id", deps.Session.ID),
+						slog.String("tool", inv.Tool),
+						telemetry.SensitiveLog("error", errMsg),
+					)
 
-			// CRS-06: Emit EventToolFailed to Coordinator
-			p.emitCoordinatorEvent(ctx, deps, integration.EventToolFailed, &inv, result, errMsg, errorCategory)
-		} else {
-			// CRS-06: Emit EventToolExecuted to Coordinator for successful execution
-			p.emitCoordinatorEvent(ctx, deps, integration.EventToolExecuted, &inv, result, "", crs.ErrorCategoryNone)
-		}
-		// CRS-19: Attach staleness metadata to result and trace step.
-		if stalenessResult != nil && stalenessResult.IsStale {
-			if result != nil {
-				if result.TraceStep == nil {
-					result.TraceStep = &crs.TraceStep{
-						Metadata: make(map[string]string),
+					// Set circuit breaker flag to prevent LLM from retrying with same tool
+					if deps.Session != nil {
+						deps.Session.SetCircuitBreakerActive(true)
+						slog.DebugContext(ctx, "P0-3: Circuit breaker activated due to validation failure",
+							slog.String("session_id", deps.Session.ID),
+							slog.String("tool", inv.Tool),
+						)
 					}
 				}
-				if result.TraceStep.Metadata == nil {
-					result.TraceStep.Metadata = make(map[string]string)
+
+				// Record error for router feedback
+				if deps.Session != nil {
+					deps.Session.RecordToolError(inv.Tool, errMsg)
+				}
+
+				// CRS-04: Learn from tool execution error
+				// Determine error category from error message
+				errorCategory := categorizeToolError(errMsg)
+				p.learnFromFailure(ctx, deps, crs.FailureEvent{
+					SessionID:     deps.Session.ID,
+					FailureType:   crs.FailureTypeToolError,
+					Tool:          inv.Tool,
+					ErrorMessage:  errMsg,
+					ErrorCategory: errorCategory,
+					Source:        crs.SignalSourceHard,
+				})
+
+				// CRS-06: Emit EventToolFailed to Coordinator
+				p.emitCoordinatorEvent(ctx, deps, integration.EventToolFailed, &inv, result, errMsg, errorCategory)
+			} else {
+				// CRS-06: Emit EventToolExecuted to Coordinator for successful execution
+				p.emitCoordinatorEvent(ctx, deps, integration.EventToolExecuted, &inv, result, "", crs.ErrorCategoryNone)
+			}
+			// CRS-19: Attach staleness metadata to result and trace step.
+			if stalenessResult != nil && stalenessResult.IsStale {
+				if result != nil {
+					if result.TraceStep == nil {
+						result.TraceStep = &crs.TraceStep{
+							Metadata: make(map[string]string),
+						}
+					}
+					if result.TraceStep.Metadata == nil {
+						result.TraceStep.Metadata = make(map[string]string)
+					}
+					result.TraceStep.Metadata["graph_stale"] = "true"
+					result.TraceStep.Metadata["graph_stale_changed_files"] = fmt.Sprintf("%d", stalenessResult.ChangedFileCount)
+					result.TraceStep.Metadata["graph_stale_percent"] = fmt.Sprintf("%.1f", stalenessResult.PercentChanged*100)
 				}
-				result.TraceStep.Metadata["graph_stale"] = "true"
-				result.TraceStep.Metadata["graph_stale_changed_files"] = fmt.Sprintf("%d", stalenessResult.ChangedFileCount)
-				result.TraceStep.Metadata["graph_stale_percent"] = fmt.Sprintf("%.1f", stalenessResult.PercentChanged*100)
 			}
-		}
 
-		p.recordTraceStep(deps, &inv, result, toolDuration, errMsg)
+			p.recordTraceStep(deps, &inv, result, toolDuration, errMsg)
 
-		// GR-38 Issue 16: Track tokens for tool results.
-		// IT-06c I-12: Use result.TokensUsed (computed by the tool from OutputText)
-		// instead of fmt.Sprintf("%v", result.Output) which produces verbose Go struct
-		// notation and inflates token counts ~13.5x for large outputs.
-		if result != nil && result.Success && result.TokensUsed > 0 {
-			deps.Session.IncrementMetric(agent.MetricTokens, result.TokensUsed)
-		}
+			// GR-38 Issue 16: Track tokens for tool results.
+			// IT-06c I-12: Use result.TokensUsed (computed by the tool from OutputText)
+			// instead of fmt.Sprintf("%v", result.Output) which produces verbose Go struct
+			// notation and inflates token counts ~13.5x for large outputs.
+			if result != nil && result.Success && result.TokensUsed > 0 {
+				deps.Session.IncrementMetric(agent.MetricTokens, result.TokensUsed)
+			}
 
-		// CRS-02: Update proof numbers based on tool execution outcome.
-		// Proof number represents COST TO PROVE (lower = better).
-		// Success decreases cost (path is viable), failure increases cost.
-		p.updateProofNumber(ctx, deps, &inv, result)
+			// CRS-02: Update proof numbers based on tool execution outcome.
+			// Proof number represents COST TO PROVE (lower = better).
+			// Success decreases cost (path is viable), failure increases cost.
+			p.updateProofNumber(ctx, deps, &inv, result)
 
-		// CRS-03: Check for reasoning cycles after each step.
-		// Brent's algorithm detects cycles in O(1) amortized time per step.
-		stepNumber := 0
-		if deps.Session != nil {
-			stepNumber = deps.Session.GetMetric(agent.MetricSteps)
-		}
-		if cycleDetected, cycleReason := p.checkCycleAfterStep(ctx, deps, &inv, stepNumber, result.Success); cycleDetected {
-			// Cycle detected - mark this as a blocked result
-			slog.Warn("CRS-03: Cycle triggered circuit breaker",
-				slog.String("session_id", deps.Session.ID),
-				slog.String("tool", inv.Tool),
-				slog.String("reason", cycleReason),
-			)
+			// CRS-03: Check for reasoning cycles after each step.
+			// Brent's algorithm detects cycles in O(1) amortized time per step.
+			stepNumber := 0
+			if deps.Session != nil {
+				stepNumber = deps.Session.GetMetric(agent.MetricSteps)
+			}
+			if cycleDetected, cycleReason := p.checkCycleAfterStep(ctx, deps, &inv, stepNumber, result.Success); cycleDetected {
+				// Cycle detected - mark this as a blocked result
+				slog.WarnContext(ctx, "CRS-03: Cycle triggered circuit breaker",
+					slog.String("session_id", deps.Session.ID),
+					slog.String("tool", inv.Tool),
+					slog.String("reason", cycleReason),
+				)
 
-			// CRS-06: Emit EventCycleDetected to Coordinator
-			p.emitCoordinatorEvent(ctx, deps, integration.EventCycleDetected, &inv, nil, cycleReason, crs.ErrorCategoryInternal)
+				// CRS-06: Emit EventCycleDetected to Coordinator
+				p.emitCoordinatorEvent(ctx, deps, integration.EventCycleDetected, &inv, nil, cycleReason, crs.ErrorCategoryInternal)
 
-			// Continue processing - the cycle states are already marked disproven
-			// The circuit breaker will fire on the next tool selection
-		}
+				// Continue processing - the cycle states are already marked disproven
+				// The circuit breaker will fire on the next tool selection
+			}
+
+			// Track file modifications for graph refresh
+			p.trackModifiedFiles(deps, result)
 
-		// Track file modifications for graph refresh
-		p.trackModifiedFiles(deps, result)
+			// Emit tool result event
+			p.emitToolResult(deps, &inv, call.index, result)
+		}
+		results = append(results, slots...)
 
-		// Emit tool result event
-		p.emitToolResult(deps, &inv, result)
+		if stop {
+			return results, invocations, true
+		}
 	}
 
 	// GR-39 Issue 2: Check for "not found" pattern across results.
@@ -522,7 +593,7 @@ func (p *ExecutePhase) executeToolCalls(ctx context.Context, deps *Dependencies,
 	// searching for something that doesn't exist. Force early synthesis.
 	notFoundCount := p.countNotFoundResults(results)
 	if notFoundCount >= maxNotFoundBeforeSynthesize {
-		slog.Info("GR-39: Not-found pattern detected, signaling synthesis",
+		slog.InfoContext(ctx, "GR-39: Not-found pattern detected, signaling synthesis",
 			slog.String("session_id", deps.Session.ID),
 			slog.Int("not_found_count", notFoundCount),
 			slog.Int("threshold", maxNotFoundBeforeSynthesize),
@@ -653,10 +724,16 @@ func (p *ExecutePhase) recordTraceStep(deps *Dependencies, inv *agent.ToolInvoca
 		}
 	}
 
+	// Link the step to the invocation's ToolResult for transcripts.
+	if inv.ID != "" {
+		step.Metadata[agent.TraceInvocationIDKey] = inv.ID
+	}
+
 	// Extract symbols found from result if available
 	if result != nil && result.Success {
 		step.SymbolsFound = extractSymbolsFromResult(result)
 	}
+	observeCitations(deps, inv, result)
 
 	// GR-59 Rev 3: Merge tool's own TraceStep metadata into the session step.
 	// Tools like find_implementations, find_callers, find_callees record domain-specific
@@ -684,7 +761,7 @@ func (p *ExecutePhase) recordTraceStep(deps *Dependencies, inv *agent.ToolInvoca
 		if err := deps.Session.GetCRS().RecordStep(context.Background(), stepRecord); err != nil {
 			slog.Debug("CRS step recording failed",
 				slog.String("tool", inv.Tool),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 		}
 	}
@@ -721,7 +798,7 @@ func (p *ExecutePhase) generateToolClauses(ctx context.Context, deps *Dependenci
 	crsInstance := deps.Session.GetCRS()
 	sessionID := deps.Session.ID
 
-	slog.Info("CRS-WIRE-02: generateToolClauses called",
+	slog.InfoContext(ctx, "CRS-WIRE-02: generateToolClauses called",
 		slog.String("session_id", sessionID),
 		slog.String("tool", inv.Tool),
 		slog.Bool("has_error", errMsg != ""),
@@ -742,13 +819,13 @@ func (p *ExecutePhase) generateToolClauses(ctx context.Context, deps *Dependenci
 			SessionID:   sessionID,
 		}
 		if addErr := crsInstance.AddClause(ctx, clause); addErr != nil {
-			slog.Warn("CRS-WIRE-02: CDCL clause generation failed",
+			slog.WarnContext(ctx, "CRS-WIRE-02: CDCL clause generation failed",
 				slog.String("tool", inv.Tool),
 				slog.String("clause_type", "tool_error"),
-				slog.String("error", addErr.Error()),
+				telemetry.SensitiveLogError("error", addErr),
 			)
 		} else {
-			slog.Info("CRS-WIRE-02: CDCL hard clause generated",
+			slog.InfoContext(ctx, "CRS-WIRE-02: CDCL hard clause generated",
 				slog.String("session_id", sessionID),
 				slog.String("tool", inv.Tool),
 				slog.String("clause_id", clause.ID),
@@ -763,7 +840,7 @@ func (p *ExecutePhase) generateToolClauses(ctx context.Context, deps *Dependenci
 	// Empty results from graph tools ARE hard signals — the tool ran authoritatively
 	// and found nothing. This is a definitive learned constraint.
 	resultCount := extractResultCount(result)
-	slog.Info("CRS-WIRE-02: extractResultCount",
+	slog.InfoContext(ctx, "CRS-WIRE-02: extractResultCount",
 		slog.String("session_id", sessionID),
 		slog.String("tool", inv.Tool),
 		slog.Int("result_count", resultCount),
@@ -783,13 +860,13 @@ func (p *ExecutePhase) generateToolClauses(ctx context.Context, deps *Dependenci
 			SessionID:   sessionID,
 		}
 		if addErr := crsInstance.AddClause(ctx, clause); addErr != nil {
-			slog.Warn("CRS-WIRE-02: CDCL clause generation failed",
+			slog.WarnContext(ctx, "CRS-WIRE-02: CDCL clause generation failed",
 				slog.String("tool", inv.Tool),
 				slog.String("clause_type", "empty_result"),
-				slog.String("error", addErr.Error()),
+				telemetry.SensitiveLogError("error", addErr),
 			)
 		} else {
-			slog.Info("CRS-WIRE-02: CDCL empty-result clause generated",
+			slog.InfoContext(ctx, "CRS-WIRE-02: CDCL empty-result clause generated",
 				slog.String("session_id", sessionID),
 				slog.String("tool", inv.Tool),
 				slog.String("clause_id", clause.ID),
@@ -940,6 +1017,13 @@ func (p *ExecutePhase) isBlockedBySafety(ctx context.Context, deps *Dependencies
 		return &SafetyCheckResult{Blocked: false}
 	}
 
+	// Policy rules (run mode, paths, network) apply to every tool.
+	if evaluator, ok := deps.SafetyGate.(safety.Evaluator); ok {
+		if denied := p.evaluateSafetyPolicy(ctx, deps, evaluator, inv); denied != nil {
+			return denied
+		}
+	}
+
 	// Build proposed change from invocation
 	change := p.buildProposedChange(inv)
 	if change == nil {
@@ -974,6 +1058,40 @@ func (p *ExecutePhase) isBlockedBySafety(ctx context.Context, deps *Dependencies
 	}
 }
 
+// evaluateSafetyPolicy applies the gate's declarative policy to a call.
+//
+// Description:
+//
+//	Denials by the gate's checkers ("gate.*" rules) are left to the
+//	checker path in isBlockedBySafety, which also extracts CDCL
+//	constraints. Any other denial blocks the call and emits a safety
+//	check event carrying the rule ID.
+//
+// Outputs:
+//
+//	*SafetyCheckResult - A blocked result, or nil if the policy allows the call.
+func (p *ExecutePhase) evaluateSafetyPolicy(ctx context.Context, deps *Dependencies, evaluator safety.Evaluator, inv *agent.ToolInvocation) *SafetyCheckResult {
+	call := safety.ToolCall{Tool: inv.Tool, Params: safetyParams(inv.Parameters)}
+	if deps.Session != nil && deps.Session.Config != nil {
+		call.Mode = deps.Session.Config.RunMode
+	}
+
+	decision, err := evaluator.Evaluate(ctx, call)
+	if err != nil {
+		p.emitError(deps, fmt.Errorf("safety policy evaluation failed: %w", err), true)
+		return nil
+	}
+	if decision.Allowed || strings.HasPrefix(decision.RuleID, "gate.") {
+		return nil
+	}
+
+	p.emitPolicyDenial(deps, decision)
+	return &SafetyCheckResult{
+		Blocked:      true,
+		ErrorMessage: fmt.Sprintf("safety policy denied %s (rule %s): %s", decision.Tool, decision.RuleID, decision.Reason),
+	}
+}
+
 // buildProposedChange creates a safety change from a tool invocation.
 //
 // Inputs:
@@ -1035,6 +1153,30 @@ func (p *ExecutePhase) executeSingleTool(ctx context.Context, deps *Dependencies
 
 	result, err := deps.ToolExecutor.Execute(ctx, toolInvocation)
 	if err != nil {
+		// Sandbox limits become a structured tool error rather than failing
+		// the run, so Reflect can steer the LLM toward a narrower query.
+		var limitErr *tools.LimitError
+		if errors.As(err, &limitErr) {
+			return &tools.Result{
+				Success: false,
+				Error:   limitErr.Error() + ". " + limitErr.Hint(),
+				Metadata: map[string]any{
+					tools.MetaLimitHit: limitErr.Limit,
+				},
+			}
+		}
+		// Invalid arguments name the offending field so the LLM can fix
+		// the call instead of guessing.
+		var validationErr *tools.ValidationError
+		if errors.As(err, &validationErr) {
+			return &tools.Result{
+				Success: false,
+				Error:   fmt.Sprintf("invalid arguments for %s: %s", inv.Tool, validationErr.Error()),
+				Metadata: map[string]any{
+					tools.MetaInvalidParameter: validationErr.Parameter,
+				},
+			}
+		}
 		return &tools.Result{
 			Success: false,
 			Error:   err.Error(),
@@ -1134,7 +1276,7 @@ func (p *ExecutePhase) updateContextWithResults(ctx context.Context, deps *Depen
 		if deps.Session != nil {
 			sessionID = deps.Session.ID
 		}
-		slog.Warn("updateContextWithResults: deps.Context is nil, cannot store results",
+		slog.WarnContext(ctx, "updateContextWithResults: deps.Context is nil, cannot store results",
 			slog.String("session_id", sessionID),
 			slog.Int("result_count", len(results)),
 		)
@@ -1142,7 +1284,7 @@ func (p *ExecutePhase) updateContextWithResults(ctx context.Context, deps *Depen
 	}
 
 	if deps.Session == nil {
-		slog.Warn("updateContextWithResults: deps.Session is nil, cannot persist results",
+		slog.WarnContext(ctx, "updateContextWithResults: deps.Session is nil, cannot persist results",
 			slog.Int("result_count", len(results)),
 		)
 		return
@@ -1172,6 +1314,9 @@ func (p *ExecutePhase) updateContextWithResults(ctx context.Context, deps *Depen
 				if i < len(invocations) {
 					if n := len(updated.ToolResults); n > 0 {
 						lastResult := &updated.ToolResults[n-1]
+						if invocations[i].ID != "" {
+							lastResult.InvocationID = invocations[i].ID
+						}
 						if invocations[i].ThoughtSignature != "" {
 							lastResult.ThoughtSignature = invocations[i].ThoughtSignature
 						}
@@ -1211,7 +1356,11 @@ func (p *ExecutePhase) updateContextWithResults(ctx context.Context, deps *Depen
 
 		// Carry ThoughtSignature and Tool name from invocation for Gemini 3 round-trip.
 		var thoughtSig string
+		invocationID := uuid.NewString()
 		if i < len(invocations) {
+			if invocations[i].ID != "" {
+				invocationID = invocations[i].ID
+			}
 			thoughtSig = invocations[i].ThoughtSignature
 			// Fall back to invocation tool name when TraceStep doesn't provide one.
 			if toolName == "" {
@@ -1220,7 +1369,7 @@ func (p *ExecutePhase) updateContextWithResults(ctx context.Context, deps *Depen
 		}
 
 		agentResult := agent.ToolResult{
-			InvocationID:     uuid.NewString(),
+			InvocationID:     invocationID,
 			Success:          result.Success,
 			Output:           outputText,
 			Error:            result.Error,
@@ -1230,6 +1379,8 @@ func (p *ExecutePhase) updateContextWithResults(ctx context.Context, deps *Depen
 			Truncated:        truncated,
 			Tool:             toolName,          // IT_CRS_03 AC-3
 			ProofDelta:       result.ProofDelta, // IT_CRS_03 AC-8
+			NextCursor:       result.NextCursor,
+			InvalidParameter: resultInvalidParameter(result),
 			ThoughtSignature: thoughtSig,
 		}
 
@@ -1255,7 +1406,7 @@ func (p *ExecutePhase) updateContextWithResults(ctx context.Context, deps *Depen
 			})
 		}
 
-		slog.Debug("updateContextWithResults: direct append (no ContextManager)",
+		slog.DebugContext(ctx, "updateContextWithResults: direct append (no ContextManager)",
 			slog.String("session_id", deps.Session.ID),
 			slog.Bool("success", result.Success),
 			slog.Int("output_len", len(outputText)),
@@ -1315,7 +1466,7 @@ func (p *ExecutePhase) maybeRefreshGraph(ctx context.Context, deps *Dependencies
 		return
 	}
 
-	slog.Info("refreshing graph for modified files",
+	slog.InfoContext(ctx, "refreshing graph for modified files",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("file_count", len(dirtyFiles)),
 	)
@@ -1323,9 +1474,9 @@ func (p *ExecutePhase) maybeRefreshGraph(ctx context.Context, deps *Dependencies
 	// Perform refresh
 	result, err := deps.GraphRefresher.RefreshFiles(ctx, dirtyFiles)
 	if err != nil {
-		slog.Warn("incremental graph refresh failed, continuing with stale graph",
+		slog.WarnContext(ctx, "incremental graph refresh failed, continuing with stale graph",
 			slog.String("session_id", deps.Session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		// Non-fatal: continue with stale data rather than failing
 		return
@@ -1334,7 +1485,7 @@ func (p *ExecutePhase) maybeRefreshGraph(ctx context.Context, deps *Dependencies
 	// Clear the successfully refreshed files
 	deps.DirtyTracker.Clear(dirtyFiles)
 
-	slog.Info("graph refreshed",
+	slog.InfoContext(ctx, "graph refreshed",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("nodes_removed", result.NodesRemoved),
 		slog.Int("nodes_added", result.NodesAdded),
@@ -1345,15 +1496,15 @@ func (p *ExecutePhase) maybeRefreshGraph(ctx context.Context, deps *Dependencies
 	if deps.SymbolStore != nil && deps.SymbolIndex != nil {
 		for _, file := range dirtyFiles {
 			if err := deps.SymbolStore.DeleteByFile(ctx, file); err != nil {
-				slog.Warn("CRS-25b: Failed to delete symbols for file",
-					slog.String("file", file), slog.String("error", err.Error()))
+				slog.WarnContext(ctx, "CRS-25b: Failed to delete symbols for file",
+					telemetry.SensitiveLog("file", file), telemetry.SensitiveLogError("error", err))
 			}
 		}
 		count, indexErr := deps.SymbolStore.IndexFileSymbols(ctx, deps.SymbolIndex, dirtyFiles, "")
 		if indexErr != nil {
-			slog.Warn("CRS-25b: Failed to re-index symbols", slog.String("error", indexErr.Error()))
+			slog.WarnContext(ctx, "CRS-25b: Failed to re-index symbols", telemetry.SensitiveLogError("error", indexErr))
 		} else if count > 0 {
-			slog.Info("CRS-25b: Symbols refreshed in Weaviate",
+			slog.InfoContext(ctx, "CRS-25b: Symbols refreshed in Weaviate",
 				slog.Int("count", count), slog.Int("files", len(dirtyFiles)))
 		}
 	}
@@ -1417,7 +1568,7 @@ func (p *ExecutePhase) invalidateGraphCaches(ctx context.Context, deps *Dependen
 	if err != nil {
 		logger.Warn("graph refresh event handling failed",
 			slog.String("session_id", deps.Session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		span.SetAttributes(attribute.Bool("event_handled", false))
 	} else {
@@ -1590,10 +1741,10 @@ func (p *ExecutePhase) extractToolParameters(
 		// symbols share the same name (e.g., 11 "Build" functions in Hugo).
 		pkgHint := extractPackageContextFromQuery(query)
 		if pkgHint != "" {
-			slog.Info("IT-06c: extracted package context for disambiguation",
+			slog.InfoContext(goCtx, "IT-06c: extracted package context for disambiguation",
 				slog.String("tool", toolName),
-				slog.String("function_name", funcName),
-				slog.String("package_hint", pkgHint),
+				telemetry.SensitiveLog("function_name", funcName),
+				telemetry.SensitiveLog("package_hint", pkgHint),
 			)
 		}
 
@@ -1652,7 +1803,7 @@ func (p *ExecutePhase) extractToolParameters(
 		kind := extractKindFromQuery(query)
 		sortBy := extractSortByFromQuery(query)
 		excludeTests := extractExcludeTestsFromQuery(query)
-		slog.Debug("GR-Phase1: extracted find_hotspots params",
+		slog.DebugContext(goCtx, "GR-Phase1: extracted find_hotspots params",
 			slog.String("tool", toolName),
 			slog.Int("top", top),
 			slog.String("kind", kind),
@@ -1691,7 +1842,7 @@ func (p *ExecutePhase) extractToolParameters(
 		}
 		// IT-08: Only include test files if the query explicitly mentions tests
 		excludeTests := !strings.Contains(lowerQuery, "test")
-		slog.Debug("GR-Phase1: extracted find_dead_code params",
+		slog.DebugContext(goCtx, "GR-Phase1: extracted find_dead_code params",
 			slog.String("tool", toolName),
 			slog.Bool("include_exported", includeExported),
 			slog.Bool("exclude_tests", excludeTests),
@@ -1706,7 +1857,7 @@ func (p *ExecutePhase) extractToolParameters(
 
 	case "find_cycles":
 		// Defaults: min_size=2, limit=20
-		slog.Debug("GR-Phase1: extracted find_cycles params (defaults)",
+		slog.DebugContext(goCtx, "GR-Phase1: extracted find_cycles params (defaults)",
 			slog.String("tool", toolName),
 			slog.Int("min_size", 2),
 			slog.Int("limit", 20),
@@ -1735,18 +1886,18 @@ func (p *ExecutePhase) extractToolParameters(
 			}
 		}
 		if from == "" || to == "" {
-			slog.Debug("GR-Phase1: find_path extraction failed",
+			slog.DebugContext(goCtx, "GR-Phase1: find_path extraction failed",
 				slog.String("tool", toolName),
-				slog.String("query_preview", truncateForLog(query, 100)),
-				slog.String("from", from),
-				slog.String("to", to),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
+				telemetry.SensitiveLog("from", from),
+				telemetry.SensitiveLog("to", to),
 			)
 			return nil, fmt.Errorf("could not extract 'from' and 'to' symbols from query for find_path (need both source and target)")
 		}
-		slog.Debug("GR-Phase1: extracted find_path params",
+		slog.DebugContext(goCtx, "GR-Phase1: extracted find_path params",
 			slog.String("tool", toolName),
-			slog.String("from", from),
-			slog.String("to", to),
+			telemetry.SensitiveLog("from", from),
+			telemetry.SensitiveLog("to", to),
 		)
 		return tools.FindPathParams{
 			From: from,
@@ -1764,12 +1915,12 @@ func (p *ExecutePhase) extractToolParameters(
 		excludeTests := extractExcludeTestsFromQuery(query)
 		pkg := extractPackageContextFromQuery(query)
 		reverse := extractReverseFromQuery(query)
-		slog.Debug("GR-Phase1: extracted find_important params",
+		slog.DebugContext(goCtx, "GR-Phase1: extracted find_important params",
 			slog.String("tool", toolName),
 			slog.Int("top", top),
 			slog.String("kind", kind),
 			slog.Bool("exclude_tests", excludeTests),
-			slog.String("package", pkg),
+			telemetry.SensitiveLog("package", pkg),
 			slog.Bool("reverse", reverse),
 		)
 		return tools.FindImportantParams{
@@ -1787,16 +1938,16 @@ func (p *ExecutePhase) extractToolParameters(
 			symbolName = extractFunctionNameFromContext(ctx)
 		}
 		if symbolName == "" {
-			slog.Debug("GR-Phase1: find_symbol extraction failed",
+			slog.DebugContext(goCtx, "GR-Phase1: find_symbol extraction failed",
 				slog.String("tool", toolName),
-				slog.String("query_preview", truncateForLog(query, 100)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
 			)
 			return nil, fmt.Errorf("could not extract symbol name from query for find_symbol")
 		}
 		kind := extractKindFromQuery(query)
-		slog.Debug("GR-Phase1: extracted find_symbol params",
+		slog.DebugContext(goCtx, "GR-Phase1: extracted find_symbol params",
 			slog.String("tool", toolName),
-			slog.String("name", symbolName),
+			telemetry.SensitiveLog("name", symbolName),
 			slog.String("kind", kind),
 		)
 		return tools.FindSymbolParams{
@@ -1818,7 +1969,7 @@ func (p *ExecutePhase) extractToolParameters(
 			resolution = 0.5
 		}
 		top := extractTopNFromQuery(query, 20)
-		slog.Debug("GR-47: extracted find_communities params",
+		slog.DebugContext(goCtx, "GR-47: extracted find_communities params",
 			slog.String("tool", toolName),
 			slog.Float64("resolution", resolution),
 			slog.Int("top", top),
@@ -1834,9 +1985,9 @@ func (p *ExecutePhase) extractToolParameters(
 		// Risk of empty results from wrong package name is LOW (communities still run,
 		// just returns empty for that scope — same as find_important behavior per CR-11).
 		pkg := extractPackageContextFromQuery(query)
-		slog.Debug("IT-R2c: extracted find_communities package",
+		slog.DebugContext(goCtx, "IT-R2c: extracted find_communities package",
 			slog.String("tool", toolName),
-			slog.String("package", pkg),
+			telemetry.SensitiveLog("package", pkg),
 		)
 		return tools.FindCommunitiesParams{
 			MinSize:        3,
@@ -1857,7 +2008,7 @@ func (p *ExecutePhase) extractToolParameters(
 			strings.Contains(lowerQuery, "only points") || strings.Contains(lowerQuery, "just points") {
 			includeBridges = false
 		}
-		slog.Debug("GR-47: extracted find_articulation_points params",
+		slog.DebugContext(goCtx, "GR-47: extracted find_articulation_points params",
 			slog.String("tool", toolName),
 			slog.Int("top", top),
 			slog.Bool("include_bridges", includeBridges),
@@ -1880,9 +2031,9 @@ func (p *ExecutePhase) extractToolParameters(
 			target = candidates[0]
 		}
 		if target == "" && len(candidates) == 0 {
-			slog.Debug("CB-31d: find_dominators extraction failed",
+			slog.DebugContext(goCtx, "CB-31d: find_dominators extraction failed",
 				slog.String("tool", toolName),
-				slog.String("query_preview", truncateForLog(query, 100)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
 			)
 			return nil, fmt.Errorf("could not extract target function from query for find_dominators")
 		}
@@ -1897,9 +2048,9 @@ func (p *ExecutePhase) extractToolParameters(
 			if len(candidates) > 1 {
 				resolvedTarget, rawName, confidence, err := resolveFirstCandidate(goCtx, &p.symbolCache, sessionID, candidates, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved target symbol for find_dominators",
+					slog.DebugContext(goCtx, "CB-31d: resolved target symbol for find_dominators",
 						slog.String("raw", rawName),
-						slog.String("resolved", resolvedTarget),
+						telemetry.SensitiveLog("resolved", resolvedTarget),
 						slog.Float64("confidence", confidence),
 						slog.Int("candidates", len(candidates)),
 					)
@@ -1908,9 +2059,9 @@ func (p *ExecutePhase) extractToolParameters(
 			} else {
 				resolvedTarget, confidence, err := resolveSymbolCached(&p.symbolCache, sessionID, target, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved target symbol for find_dominators",
+					slog.DebugContext(goCtx, "CB-31d: resolved target symbol for find_dominators",
 						slog.String("raw", target),
-						slog.String("resolved", resolvedTarget),
+						telemetry.SensitiveLog("resolved", resolvedTarget),
 						slog.Float64("confidence", confidence),
 					)
 					target = resolvedTarget
@@ -1930,9 +2081,9 @@ func (p *ExecutePhase) extractToolParameters(
 				}
 				resolvedFrom, confidence, err := resolveSymbolCached(&p.symbolCache, sessionID, from, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved entry_point symbol for find_dominators",
+					slog.DebugContext(goCtx, "CB-31d: resolved entry_point symbol for find_dominators",
 						slog.String("raw", from),
-						slog.String("resolved", resolvedFrom),
+						telemetry.SensitiveLog("resolved", resolvedFrom),
 						slog.Float64("confidence", confidence),
 					)
 					from = resolvedFrom
@@ -1940,9 +2091,9 @@ func (p *ExecutePhase) extractToolParameters(
 			}
 			entry = from
 		}
-		slog.Debug("CB-31d: extracted find_dominators params",
+		slog.DebugContext(goCtx, "CB-31d: extracted find_dominators params",
 			slog.String("tool", toolName),
-			slog.String("target", target),
+			telemetry.SensitiveLog("target", target),
 		)
 		return tools.FindDominatorsParams{
 			Target: target,
@@ -1954,11 +2105,11 @@ func (p *ExecutePhase) extractToolParameters(
 		// Patterns: "common dependency between X and Y", "shared by X and Y", "LCD of X and Y"
 		from, to, ok := extractPathSymbolsFromQuery(query)
 		if !ok || from == "" || to == "" {
-			slog.Debug("CB-31d: find_common_dependency extraction failed",
+			slog.DebugContext(goCtx, "CB-31d: find_common_dependency extraction failed",
 				slog.String("tool", toolName),
-				slog.String("query_preview", truncateForLog(query, 100)),
-				slog.String("from", from),
-				slog.String("to", to),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
+				telemetry.SensitiveLog("from", from),
+				telemetry.SensitiveLog("to", to),
 			)
 			return nil, fmt.Errorf("could not extract two function names from query for find_common_dependency")
 		}
@@ -1977,10 +2128,10 @@ func (p *ExecutePhase) extractToolParameters(
 			for i, target := range targets {
 				resolvedTarget, confidence, err := resolveSymbolCached(&p.symbolCache, sessionID, target, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved target symbol for find_common_dependency",
+					slog.DebugContext(goCtx, "CB-31d: resolved target symbol for find_common_dependency",
 						slog.Int("index", i),
 						slog.String("raw", target),
-						slog.String("resolved", resolvedTarget),
+						telemetry.SensitiveLog("resolved", resolvedTarget),
 						slog.Float64("confidence", confidence),
 					)
 					resolvedTargets = append(resolvedTargets, resolvedTarget)
@@ -2009,10 +2160,10 @@ func (p *ExecutePhase) extractToolParameters(
 			}
 		}
 
-		slog.Debug("CB-31d: extracted find_common_dependency params",
+		slog.DebugContext(goCtx, "CB-31d: extracted find_common_dependency params",
 			slog.String("tool", toolName),
-			slog.Any("targets", targets),
-			slog.String("entry", entry),
+			telemetry.SensitiveLogStrings("targets", targets),
+			telemetry.SensitiveLog("entry", entry),
 		)
 		return tools.FindCommonDependencyParams{
 			Targets: targets,
@@ -2038,9 +2189,9 @@ func (p *ExecutePhase) extractToolParameters(
 			}
 		}
 		if target == "" {
-			slog.Debug("CB-31d: find_critical_path extraction failed",
+			slog.DebugContext(goCtx, "CB-31d: find_critical_path extraction failed",
 				slog.String("tool", toolName),
-				slog.String("query_preview", truncateForLog(query, 100)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
 			)
 			return nil, fmt.Errorf("could not extract target function from query for find_critical_path")
 		}
@@ -2055,9 +2206,9 @@ func (p *ExecutePhase) extractToolParameters(
 			if len(candidates) > 1 {
 				resolvedTarget, rawName, confidence, err := resolveFirstCandidate(goCtx, &p.symbolCache, sessionID, candidates, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved target symbol for find_critical_path",
+					slog.DebugContext(goCtx, "CB-31d: resolved target symbol for find_critical_path",
 						slog.String("raw", rawName),
-						slog.String("resolved", resolvedTarget),
+						telemetry.SensitiveLog("resolved", resolvedTarget),
 						slog.Float64("confidence", confidence),
 						slog.Int("candidates", len(candidates)),
 					)
@@ -2066,9 +2217,9 @@ func (p *ExecutePhase) extractToolParameters(
 			} else {
 				resolvedTarget, confidence, err := resolveSymbolCached(&p.symbolCache, sessionID, target, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved target symbol for find_critical_path",
+					slog.DebugContext(goCtx, "CB-31d: resolved target symbol for find_critical_path",
 						slog.String("raw", target),
-						slog.String("resolved", resolvedTarget),
+						telemetry.SensitiveLog("resolved", resolvedTarget),
 						slog.Float64("confidence", confidence),
 					)
 					target = resolvedTarget
@@ -2088,9 +2239,9 @@ func (p *ExecutePhase) extractToolParameters(
 				}
 				resolvedFrom, confidence, err := resolveSymbolCached(&p.symbolCache, sessionID, from, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved entry_point symbol for find_critical_path",
+					slog.DebugContext(goCtx, "CB-31d: resolved entry_point symbol for find_critical_path",
 						slog.String("raw", from),
-						slog.String("resolved", resolvedFrom),
+						telemetry.SensitiveLog("resolved", resolvedFrom),
 						slog.Float64("confidence", confidence),
 					)
 					from = resolvedFrom
@@ -2098,9 +2249,9 @@ func (p *ExecutePhase) extractToolParameters(
 			}
 			entry = from
 		}
-		slog.Debug("CB-31d: extracted find_critical_path params",
+		slog.DebugContext(goCtx, "CB-31d: extracted find_critical_path params",
 			slog.String("tool", toolName),
-			slog.String("target", target),
+			telemetry.SensitiveLog("target", target),
 		)
 		return tools.FindCriticalPathParams{
 			Target: target,
@@ -2126,9 +2277,9 @@ func (p *ExecutePhase) extractToolParameters(
 			sources = append(sources, to)
 		}
 		if len(sources) == 0 {
-			slog.Debug("CB-31d: find_merge_points extraction failed",
+			slog.DebugContext(goCtx, "CB-31d: find_merge_points extraction failed",
 				slog.String("tool", toolName),
-				slog.String("query_preview", truncateForLog(query, 100)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
 			)
 		}
 
@@ -2142,9 +2293,9 @@ func (p *ExecutePhase) extractToolParameters(
 			for _, source := range sources {
 				resolvedSource, confidence, err := resolveSymbolCached(&p.symbolCache, sessionID, source, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved source symbol for find_merge_points",
+					slog.DebugContext(goCtx, "CB-31d: resolved source symbol for find_merge_points",
 						slog.String("raw", source),
-						slog.String("resolved", resolvedSource),
+						telemetry.SensitiveLog("resolved", resolvedSource),
 						slog.Float64("confidence", confidence),
 					)
 					resolvedSources = append(resolvedSources, resolvedSource)
@@ -2155,7 +2306,7 @@ func (p *ExecutePhase) extractToolParameters(
 			sources = resolvedSources
 		}
 
-		slog.Debug("CB-31d: extracted find_merge_points params",
+		slog.DebugContext(goCtx, "CB-31d: extracted find_merge_points params",
 			slog.String("tool", toolName),
 			slog.Int("source_count", len(sources)),
 		)
@@ -2176,22 +2327,22 @@ func (p *ExecutePhase) extractToolParameters(
 		if len(candidates) > 0 {
 			target = candidates[0]
 		}
-		slog.Info("P0 DEBUG: find_control_dependencies parameter extraction",
+		slog.InfoContext(goCtx, "P0 DEBUG: find_control_dependencies parameter extraction",
 			slog.String("tool", toolName),
-			slog.String("query_preview", truncateForLog(query, 100)),
-			slog.String("extracted_from_query", target),
+			telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
+			telemetry.SensitiveLog("extracted_from_query", target),
 			slog.Int("candidates", len(candidates)),
 		)
 		if target == "" && ctx != nil {
 			target = extractFunctionNameFromContext(ctx)
-			slog.Info("P0 DEBUG: fallback to context extraction",
+			slog.InfoContext(goCtx, "P0 DEBUG: fallback to context extraction",
 				slog.String("extracted_from_context", target),
 			)
 		}
 		if target == "" {
-			slog.Debug("CB-31d: find_control_dependencies extraction failed",
+			slog.DebugContext(goCtx, "CB-31d: find_control_dependencies extraction failed",
 				slog.String("tool", toolName),
-				slog.String("query_preview", truncateForLog(query, 100)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
 			)
 			return nil, fmt.Errorf("could not extract target function from query for find_control_dependencies")
 		}
@@ -2206,9 +2357,9 @@ func (p *ExecutePhase) extractToolParameters(
 			if len(candidates) > 1 {
 				resolvedTarget, rawName, confidence, err := resolveFirstCandidate(goCtx, &p.symbolCache, sessionID, candidates, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved target symbol for find_control_dependencies",
+					slog.DebugContext(goCtx, "CB-31d: resolved target symbol for find_control_dependencies",
 						slog.String("raw", rawName),
-						slog.String("resolved", resolvedTarget),
+						telemetry.SensitiveLog("resolved", resolvedTarget),
 						slog.Float64("confidence", confidence),
 						slog.Int("candidates", len(candidates)),
 					)
@@ -2217,9 +2368,9 @@ func (p *ExecutePhase) extractToolParameters(
 			} else {
 				resolvedTarget, confidence, err := resolveSymbolCached(&p.symbolCache, sessionID, target, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved target symbol for find_control_dependencies",
+					slog.DebugContext(goCtx, "CB-31d: resolved target symbol for find_control_dependencies",
 						slog.String("raw", target),
-						slog.String("resolved", resolvedTarget),
+						telemetry.SensitiveLog("resolved", resolvedTarget),
 						slog.Float64("confidence", confidence),
 					)
 					target = resolvedTarget
@@ -2227,9 +2378,9 @@ func (p *ExecutePhase) extractToolParameters(
 			}
 		}
 
-		slog.Debug("CB-31d: extracted find_control_dependencies params",
+		slog.DebugContext(goCtx, "CB-31d: extracted find_control_dependencies params",
 			slog.String("tool", toolName),
-			slog.String("target", target),
+			telemetry.SensitiveLog("target", target),
 		)
 		return tools.FindControlDependenciesParams{
 			Target: target,
@@ -2238,7 +2389,7 @@ func (p *ExecutePhase) extractToolParameters(
 	case "find_extractable_regions":
 		// CB-31d: Use defaults - no parameters required
 		// Analyzes entire codebase for SESE regions
-		slog.Debug("CB-31d: extracted find_extractable_regions params (defaults)",
+		slog.DebugContext(goCtx, "CB-31d: extracted find_extractable_regions params (defaults)",
 			slog.String("tool", toolName),
 		)
 		return tools.EmptyParams{Tool: "find_extractable_regions"}, nil
@@ -2249,7 +2400,7 @@ func (p *ExecutePhase) extractToolParameters(
 		// IT-00a-1 Phase 3: Multi-candidate extraction + candidate-loop resolution
 		// find_loops accepts top, min_size, show_nesting — no entry_point parameter.
 		// The old code extracted entry_point but it was silently ignored by parseParams().
-		slog.Debug("CB-31d: extracted find_loops params",
+		slog.DebugContext(goCtx, "CB-31d: extracted find_loops params",
 			slog.String("tool", toolName),
 		)
 		return tools.FindLoopsParams{
@@ -2262,7 +2413,7 @@ func (p *ExecutePhase) extractToolParameters(
 		// CB-31d: Extract top N parameter
 		// Patterns: "top 10 critical functions", "most critical functions"
 		top := extractTopNFromQuery(query, 20)
-		slog.Debug("CB-31d: extracted find_weighted_criticality params",
+		slog.DebugContext(goCtx, "CB-31d: extracted find_weighted_criticality params",
 			slog.String("tool", toolName),
 			slog.Int("top", top),
 		)
@@ -2277,7 +2428,7 @@ func (p *ExecutePhase) extractToolParameters(
 		// Note: Tool analyzes communities detected by community detection, not specific modules
 		top := extractTopNFromQuery(query, 10)
 
-		slog.Debug("CB-31d: extracted find_module_api params",
+		slog.DebugContext(goCtx, "CB-31d: extracted find_module_api params",
 			slog.String("tool", toolName),
 			slog.Int("top", top),
 		)
@@ -2304,9 +2455,9 @@ func (p *ExecutePhase) extractToolParameters(
 		}
 
 		if pattern == "" {
-			slog.Debug("P0-2: Grep parameter extraction failed - no pattern found",
+			slog.DebugContext(goCtx, "P0-2: Grep parameter extraction failed - no pattern found",
 				slog.String("tool", toolName),
-				slog.String("query_preview", truncateForLog(query, 100)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
 			)
 			return nil, fmt.Errorf("could not extract search pattern from query for Grep")
 		}
@@ -2320,7 +2471,7 @@ func (p *ExecutePhase) extractToolParameters(
 			outputMode = "count"
 		}
 
-		slog.Debug("P0-2: extracted Grep params",
+		slog.DebugContext(goCtx, "P0-2: extracted Grep params",
 			slog.String("tool", toolName),
 			slog.String("pattern", pattern),
 			slog.String("output_mode", outputMode),
@@ -2341,7 +2492,7 @@ func (p *ExecutePhase) extractToolParameters(
 			showIrreducible = false
 		}
 
-		slog.Debug("CB-31d: extracted check_reducibility params",
+		slog.DebugContext(goCtx, "CB-31d: extracted check_reducibility params",
 			slog.String("tool", toolName),
 			slog.Bool("show_irreducible", showIrreducible),
 		)
@@ -2366,9 +2517,9 @@ func (p *ExecutePhase) extractToolParameters(
 			funcName = extractFunctionNameFromContext(ctx)
 		}
 		if funcName == "" {
-			slog.Debug("CB-31d: get_call_chain extraction failed - no function name",
+			slog.DebugContext(goCtx, "CB-31d: get_call_chain extraction failed - no function name",
 				slog.String("tool", toolName),
-				slog.String("query_preview", truncateForLog(query, 100)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
 			)
 			return nil, fmt.Errorf("could not extract function name from query for get_call_chain")
 		}
@@ -2383,9 +2534,9 @@ func (p *ExecutePhase) extractToolParameters(
 		isDotNotation := strings.Contains(funcName, ".") && !strings.Contains(funcName, "/")
 		if isDotNotation {
 			// CR-R2-4: Log the passthrough decision for observability.
-			slog.Debug("IT-05: skipping agent-side resolution for dot-notation name",
+			slog.DebugContext(goCtx, "IT-05: skipping agent-side resolution for dot-notation name",
 				slog.String("tool", toolName),
-				slog.String("func_name", funcName),
+				telemetry.SensitiveLog("func_name", funcName),
 			)
 		} else if deps != nil && deps.SymbolIndex != nil {
 			sessionID := ""
@@ -2395,9 +2546,9 @@ func (p *ExecutePhase) extractToolParameters(
 			if len(candidates) > 1 {
 				resolvedFunc, rawName, confidence, err := resolveFirstCandidate(goCtx, &p.symbolCache, sessionID, candidates, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved function symbol for get_call_chain",
+					slog.DebugContext(goCtx, "CB-31d: resolved function symbol for get_call_chain",
 						slog.String("raw", rawName),
-						slog.String("resolved", resolvedFunc),
+						telemetry.SensitiveLog("resolved", resolvedFunc),
 						slog.Float64("confidence", confidence),
 						slog.Int("candidates", len(candidates)),
 					)
@@ -2406,9 +2557,9 @@ func (p *ExecutePhase) extractToolParameters(
 			} else {
 				resolvedFunc, confidence, err := resolveSymbolCached(&p.symbolCache, sessionID, funcName, deps)
 				if err == nil {
-					slog.Debug("CB-31d: resolved function symbol for get_call_chain",
+					slog.DebugContext(goCtx, "CB-31d: resolved function symbol for get_call_chain",
 						slog.String("raw", funcName),
-						slog.String("resolved", resolvedFunc),
+						telemetry.SensitiveLog("resolved", resolvedFunc),
 						slog.Float64("confidence", confidence),
 					)
 					funcName = resolvedFunc
@@ -2491,17 +2642,17 @@ func (p *ExecutePhase) extractToolParameters(
 				context.Background(), &p.symbolCache, sessionID, destCandidates, deps)
 			if destErr == nil && destConf > 0 {
 				callChainParams.DestinationName = destID
-				slog.Debug("IT-05 R5: resolved destination for get_call_chain",
+				slog.DebugContext(goCtx, "IT-05 R5: resolved destination for get_call_chain",
 					slog.String("raw", destName),
-					slog.String("resolved", destID),
+					telemetry.SensitiveLog("resolved", destID),
 					slog.Float64("confidence", destConf),
 				)
 			}
 		}
 
-		slog.Debug("CB-31d: extracted get_call_chain params",
+		slog.DebugContext(goCtx, "CB-31d: extracted get_call_chain params",
 			slog.String("tool", toolName),
-			slog.String("function_name", funcName),
+			telemetry.SensitiveLog("function_name", funcName),
 			slog.String("direction", direction),
 			slog.Int("max_depth", maxDepth),
 		)
@@ -2519,11 +2670,11 @@ func (p *ExecutePhase) extractToolParameters(
 		}
 		kind := extractKindFromQuery(query)
 		pkgHint := extractPackageContextFromQuery(query)
-		slog.Debug("CB-63: extracted read_symbol params",
+		slog.DebugContext(goCtx, "CB-63: extracted read_symbol params",
 			slog.String("tool", toolName),
-			slog.String("name", funcName),
+			telemetry.SensitiveLog("name", funcName),
 			slog.String("kind", kind),
-			slog.String("package_hint", pkgHint),
+			telemetry.SensitiveLog("package_hint", pkgHint),
 		)
 		return tools.ReadSymbolParams{
 			Name:        funcName,
@@ -2538,9 +2689,9 @@ func (p *ExecutePhase) extractToolParameters(
 			return nil, fmt.Errorf("could not extract file path from query for read_file")
 		}
 		startLine, endLine := extractLineRangeFromQuery(query, 1, 200)
-		slog.Debug("CB-63: extracted read_file params",
+		slog.DebugContext(goCtx, "CB-63: extracted read_file params",
 			slog.String("tool", toolName),
-			slog.String("path", filePath),
+			telemetry.SensitiveLog("path", filePath),
 			slog.Int("start_line", startLine),
 			slog.Int("end_line", endLine),
 		)
@@ -2559,9 +2710,9 @@ func (p *ExecutePhase) extractToolParameters(
 		if funcName == "" {
 			return nil, fmt.Errorf("could not extract symbol name from query for get_signature")
 		}
-		slog.Debug("CB-63: extracted get_signature params",
+		slog.DebugContext(goCtx, "CB-63: extracted get_signature params",
 			slog.String("tool", toolName),
-			slog.String("name", funcName),
+			telemetry.SensitiveLog("name", funcName),
 		)
 		return tools.GetSignatureParams{
 			Name: funcName,
@@ -2573,14 +2724,59 @@ func (p *ExecutePhase) extractToolParameters(
 		if filePath == "" {
 			return nil, fmt.Errorf("could not extract file path from query for list_symbols_in_file")
 		}
-		slog.Debug("CB-63: extracted list_symbols_in_file params",
+		slog.DebugContext(goCtx, "CB-63: extracted list_symbols_in_file params",
 			slog.String("tool", toolName),
-			slog.String("path", filePath),
+			telemetry.SensitiveLog("path", filePath),
 		)
 		return tools.ListSymbolsInFileParams{
 			Path: filePath,
 		}, nil
 
+	case "find_owners":
+		// Prefer a file path; otherwise resolve the symbol named in the query
+		if filePath := extractFilePathFromQuery(query); filePath != "" {
+			slog.DebugContext(goCtx, "extracted find_owners params",
+				slog.String("tool", toolName),
+				telemetry.SensitiveLog("file", filePath),
+			)
+			return tools.FindOwnersParams{Files: []string{filePath}}, nil
+		}
+		symbolName := extractFunctionNameFromQuery(query)
+		if symbolName == "" && ctx != nil {
+			symbolName = extractFunctionNameFromContext(ctx)
+		}
+		if symbolName == "" {
+			return nil, fmt.Errorf("could not extract symbol or file from query for find_owners")
+		}
+		slog.DebugContext(goCtx, "extracted find_owners params",
+			slog.String("tool", toolName),
+			telemetry.SensitiveLog("symbol", symbolName),
+		)
+		return tools.FindOwnersParams{Symbol: symbolName}, nil
+
+	case "find_literal_usages":
+		value := extractLiteralValueFromQuery(query)
+		if value == "" {
+			return nil, fmt.Errorf("could not extract a literal value from query for find_literal_usages")
+		}
+		slog.DebugContext(goCtx, "extracted find_literal_usages params",
+			slog.String("tool", toolName),
+			telemetry.SensitiveLog("value", value),
+		)
+		return tools.FindLiteralUsagesParams{Value: value}, nil
+
+	case "explain_implementation":
+		typeName, ifaceName := extractImplementsPairFromQuery(query)
+		if typeName == "" || ifaceName == "" {
+			return nil, fmt.Errorf("could not extract type and interface from query for explain_implementation")
+		}
+		slog.DebugContext(goCtx, "extracted explain_implementation params",
+			slog.String("tool", toolName),
+			slog.String("type", typeName),
+			telemetry.SensitiveLog("interface", ifaceName),
+		)
+		return tools.ExplainImplementationParams{Type: typeName, Interface: ifaceName}, nil
+
 	default:
 		// For other tools, fallback to Main LLM
 		return nil, fmt.Errorf("parameter extraction not implemented for tool: %s", toolName)
@@ -2644,9 +2840,9 @@ func (p *ExecutePhase) enhanceParamsWithLLM(
 		ctx, deps.Query, toolName, schemas, regexHint,
 	)
 	if llmErr != nil {
-		slog.Warn("IT-08b: LLM param extraction failed, using regex fallback",
+		slog.WarnContext(ctx, "IT-08b: LLM param extraction failed, using regex fallback",
 			slog.String("tool", toolName),
-			slog.String("error", llmErr.Error()),
+			telemetry.SensitiveLogError("error", llmErr),
 		)
 		return regexResult
 	}
@@ -2654,9 +2850,9 @@ func (p *ExecutePhase) enhanceParamsWithLLM(
 	// Convert enhanced map back to TypedParams
 	converted, convErr := convertMapToTypedParams(toolName, enhanced)
 	if convErr != nil {
-		slog.Warn("IT-08b: Failed to convert LLM params, using regex fallback",
+		slog.WarnContext(ctx, "IT-08b: Failed to convert LLM params, using regex fallback",
 			slog.String("tool", toolName),
-			slog.String("error", convErr.Error()),
+			telemetry.SensitiveLogError("error", convErr),
 		)
 		return regexResult
 	}
@@ -2907,6 +3103,12 @@ func convertMapToTypedParams(toolName string, params map[string]any) (tools.Type
 			OutputMode: getStringParam(params, "output_mode", "content"),
 		}, nil
 
+	case "explain_implementation":
+		return tools.ExplainImplementationParams{
+			Type:      getStringParam(params, "type", ""),
+			Interface: getStringParam(params, "interface", ""),
+		}, nil
+
 	case "list_packages", "find_entry_points", "find_extractable_regions":
 		return tools.EmptyParams{Tool: toolName}, nil
 
@@ -3086,9 +3288,9 @@ func (p *ExecutePhase) executeToolDirectlyWithFallback(
 					Reason: "tool_success_with_results",
 					Source: crs.SignalSourceHard,
 				}); proofErr != nil {
-					slog.Warn("CRS-PROOF-01: failed to decrement proof number",
+					slog.WarnContext(ctx, "CRS-PROOF-01: failed to decrement proof number",
 						slog.String("tool", toolName),
-						slog.String("error", proofErr.Error()),
+						telemetry.SensitiveLogError("error", proofErr),
 					)
 				}
 				deps.Session.RecordTraceStep(crs.NewTraceStepBuilder().
@@ -3107,10 +3309,10 @@ func (p *ExecutePhase) executeToolDirectlyWithFallback(
 					Reason: fmt.Sprintf("scoped_empty:scope=%s,pre_scope=%d", result.ScopeApplied, result.PreScopeCount),
 					Source: crs.SignalSourceHard,
 				}); proofErr != nil {
-					slog.Warn("CRS-PROOF-01: failed to increment proof number for scoped empty",
+					slog.WarnContext(ctx, "CRS-PROOF-01: failed to increment proof number for scoped empty",
 						slog.String("tool", toolName),
 						slog.String("scope", result.ScopeApplied),
-						slog.String("error", proofErr.Error()),
+						telemetry.SensitiveLogError("error", proofErr),
 					)
 				}
 				deps.Session.RecordTraceStep(crs.NewTraceStepBuilder().
@@ -3159,10 +3361,10 @@ func (p *ExecutePhase) executeToolDirectlyWithFallback(
 						LearnedAt:   time.Now().UnixMilli(),
 					}
 					if addErr := crsInstance.AddClause(ctx, scopeClause); addErr != nil {
-						slog.Warn("CRS-SCOPE-01: failed to add scope clause",
+						slog.WarnContext(ctx, "CRS-SCOPE-01: failed to add scope clause",
 							slog.String("tool", toolName),
 							slog.String("scope", result.ScopeApplied),
-							slog.String("error", addErr.Error()),
+							telemetry.SensitiveLogError("error", addErr),
 						)
 					} else {
 						deps.Session.RecordTraceStep(crs.NewTraceStepBuilder().
@@ -3185,7 +3387,7 @@ func (p *ExecutePhase) executeToolDirectlyWithFallback(
 			}
 
 			// Re-execute with empty scope
-			slog.Info("CRS-SCOPE-01: Scope relaxation — retrying with empty scope",
+			slog.InfoContext(ctx, "CRS-SCOPE-01: Scope relaxation — retrying with empty scope",
 				slog.String("session_id", deps.Session.ID),
 				slog.String("tool", toolName),
 				slog.String("original_scope", result.ScopeApplied),
@@ -3232,7 +3434,7 @@ func (p *ExecutePhase) executeToolDirectlyWithFallback(
 
 		if hasPositiveResults || crsExhausted {
 			deps.Session.SetGraphToolHadSubstantiveResults(true)
-			slog.Info("GR-59 Rev 4: Forced graph tool completed — will force synthesis",
+			slog.InfoContext(ctx, "GR-59 Rev 4: Forced graph tool completed — will force synthesis",
 				slog.String("session_id", deps.Session.ID),
 				slog.String("tool", toolName),
 				slog.Int("output_len", len(result.OutputText)),
@@ -3245,7 +3447,7 @@ func (p *ExecutePhase) executeToolDirectlyWithFallback(
 				WithMetadata("has_positive_results", fmt.Sprintf("%t", hasPositiveResults)).
 				Build())
 		} else {
-			slog.Info("CRS-GR59-01: Empty scoped result, deferring synthesis for relaxation",
+			slog.InfoContext(ctx, "CRS-GR59-01: Empty scoped result, deferring synthesis for relaxation",
 				slog.String("tool", toolName),
 				slog.String("scope", result.ScopeApplied),
 			)
@@ -3263,7 +3465,7 @@ func (p *ExecutePhase) executeToolDirectlyWithFallback(
 	// instead of fmt.Sprintf("%v", result.Output) which inflates counts ~13.5x.
 	if result != nil && result.TokensUsed > 0 {
 		deps.Session.IncrementMetric(agent.MetricTokens, result.TokensUsed)
-		slog.Debug("CB-30c: Token count for hard-forced tool",
+		slog.DebugContext(ctx, "CB-30c: Token count for hard-forced tool",
 			slog.String("tool", toolName),
 			slog.Int("tokens_used", result.TokensUsed),
 		)
@@ -3276,14 +3478,14 @@ func (p *ExecutePhase) executeToolDirectlyWithFallback(
 	if deps.ContextManager != nil && deps.Context != nil && result != nil {
 		updated, err := deps.ContextManager.Update(ctx, deps.Context, result)
 		if err != nil {
-			slog.Warn("Failed to update context with hard-forced tool result",
+			slog.WarnContext(ctx, "Failed to update context with hard-forced tool result",
 				slog.String("tool", toolName),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 		} else {
 			deps.Context = updated
 			deps.Session.SetCurrentContext(updated)
-			slog.Debug("CRS-07: Context updated with hard-forced tool result",
+			slog.DebugContext(ctx, "CRS-07: Context updated with hard-forced tool result",
 				slog.String("tool", toolName),
 				slog.Int("tool_results_count", len(updated.ToolResults)),
 			)
@@ -3355,10 +3557,10 @@ func (p *ExecutePhase) updateProofNumber(ctx context.Context, deps *Dependencies
 		Source: crs.SignalSourceHard, // Tool execution is a hard signal
 	})
 	if err != nil {
-		slog.Warn("CRS-02: failed to update proof number",
+		slog.WarnContext(ctx, "CRS-02: failed to update proof number",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("tool", inv.Tool),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 	}
 }
@@ -3397,10 +3599,10 @@ func (p *ExecutePhase) markToolDisproven(ctx context.Context, deps *Dependencies
 		Source: crs.SignalSourceSafety, // Safety violation is a hard signal
 	})
 	if err != nil {
-		slog.Warn("CRS-02: failed to mark tool disproven",
+		slog.WarnContext(ctx, "CRS-02: failed to mark tool disproven",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("tool", inv.Tool),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return
 	}
@@ -3408,7 +3610,7 @@ func (p *ExecutePhase) markToolDisproven(ctx context.Context, deps *Dependencies
 	// Propagate disproof to parent decisions
 	affected := crsInstance.PropagateDisproof(ctx, nodeID)
 	if affected > 0 {
-		slog.Debug("CRS-02: disproof propagated to parents",
+		slog.DebugContext(ctx, "CRS-02: disproof propagated to parents",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("tool", inv.Tool),
 			slog.Int("affected_nodes", affected),
@@ -15,6 +15,7 @@ package phases
 // original file as part of D3a decomposition.
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -171,6 +172,27 @@ func toolParamsToMap(params *agent.ToolParameters) map[string]any {
 	return result
 }
 
+// safetyParams returns the parameters of a call for safety policy
+// evaluation, including those only present in RawJSON.
+//
+// Inputs:
+//
+//	params - The tool parameters
+//
+// Outputs:
+//
+//	map[string]any - Parameters as a map; typed parameters win over RawJSON
+func safetyParams(params *agent.ToolParameters) map[string]any {
+	result := make(map[string]any)
+	if params != nil && len(params.RawJSON) > 0 {
+		_ = json.Unmarshal(params.RawJSON, &result)
+	}
+	for k, v := range toolParamsToMap(params) {
+		result[k] = v
+	}
+	return result
+}
+
 // -----------------------------------------------------------------------------
 // Tool Name Utilities
 // -----------------------------------------------------------------------------
@@ -20,6 +20,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/integration"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/google/uuid"
 )
 
@@ -61,15 +62,15 @@ func (p *ExecutePhase) learnFromFailure(ctx context.Context, deps *Dependencies,
 
 	// Validate failure event
 	if err := failure.Validate(); err != nil {
-		slog.Warn("CRS-04: Invalid failure event",
-			slog.String("error", err.Error()),
+		slog.WarnContext(ctx, "CRS-04: Invalid failure event",
+			telemetry.SensitiveLogError("error", err),
 		)
 		return
 	}
 
 	// Only learn from hard signals (Rule #2)
 	if !failure.Source.IsHard() {
-		slog.Debug("CRS-04: Skipping learning from soft signal",
+		slog.DebugContext(ctx, "CRS-04: Skipping learning from soft signal",
 			slog.String("failure_type", string(failure.FailureType)),
 		)
 		return
@@ -87,14 +88,14 @@ func (p *ExecutePhase) learnFromFailure(ctx context.Context, deps *Dependencies,
 
 	// Add clause to CRS
 	if err := crsInstance.AddClause(ctx, clause); err != nil {
-		slog.Warn("CRS-04: Failed to add learned clause",
+		slog.WarnContext(ctx, "CRS-04: Failed to add learned clause",
 			slog.String("session_id", deps.Session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return
 	}
 
-	slog.Info("CRS-04: Learned clause from failure",
+	slog.InfoContext(ctx, "CRS-04: Learned clause from failure",
 		slog.String("session_id", deps.Session.ID),
 		slog.String("failure_type", string(failure.FailureType)),
 		slog.String("clause_id", clause.ID),
@@ -0,0 +1,363 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+// execute_parallel.go schedules the independent steps of a tool call batch
+// into waves that execute concurrently.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// defaultParallelToolLimit is the default number of tools run concurrently.
+const defaultParallelToolLimit = 3
+
+var (
+	// parallelToolCalls counts tool calls that ran in a wave of two or more.
+	parallelToolCalls = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "trace_parallel_tool_calls_total",
+		Help: "Total tool calls executed concurrently with other plan steps",
+	})
+
+	// parallelTimeSaved tracks the wall-clock time saved per tool call batch
+	// by running independent steps concurrently (sum of tool durations minus
+	// wave time).
+	parallelTimeSaved = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "trace_parallel_tool_time_saved_seconds",
+		Help:    "Wall-clock time saved per tool call batch by parallel tool execution",
+		Buckets: []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	})
+)
+
+// WithParallelToolLimit sets how many independent tools run concurrently.
+//
+// Inputs:
+//
+//	limit - Maximum concurrent tools. Values below 1 are treated as 1,
+//	        which executes every step sequentially.
+//
+// Outputs:
+//
+//	ExecutePhaseOption - The configuration function.
+func WithParallelToolLimit(limit int) ExecutePhaseOption {
+	return func(p *ExecutePhase) {
+		if limit < 1 {
+			limit = 1
+		}
+		p.parallelToolLimit = limit
+	}
+}
+
+// pendingToolCall is a step that passed preflight and waits for execution.
+type pendingToolCall struct {
+	// index is the step's position in the plan.
+	index int
+
+	// slot is the step's position in the wave's results.
+	slot int
+
+	inv       agent.ToolInvocation
+	staleness *graph.StalenessResult
+
+	// result and duration are set by runToolWave.
+	result   *tools.Result
+	duration time.Duration
+
+	// cancelled reports whether the step's context was cancelled by the
+	// time it returned. Set by runToolWave.
+	cancelled bool
+}
+
+// planToolWaves splits a batch into waves of steps that may run concurrently.
+//
+// Description:
+//
+//	Waves are contiguous runs of the plan, so executing them one after the
+//	other keeps plan order. A step starts a new wave when the current wave
+//	is full, when it depends on a step of the wave (or a step of the wave
+//	depends on it), or when either side has side effects: tools with side
+//	effects always run alone.
+//
+// Inputs:
+//
+//	deps - Phase dependencies. The tool registry supplies side effects.
+//	invocations - The batch in plan order.
+//
+// Outputs:
+//
+//	[][]int - Plan indexes of each wave. With a limit of 1 every step is
+//	          its own wave.
+func (p *ExecutePhase) planToolWaves(deps *Dependencies, invocations []agent.ToolInvocation) [][]int {
+	limit := p.parallelToolLimit
+	if limit < 1 {
+		limit = 1
+	}
+
+	waves := make([][]int, 0, len(invocations))
+	var current []int
+	for i := range invocations {
+		if len(current) > 0 && (len(current) >= limit ||
+			hasSideEffects(deps, invocations[i].Tool) ||
+			hasSideEffects(deps, invocations[current[0]].Tool) ||
+			conflictsWithWave(invocations, current, i)) {
+			waves = append(waves, current)
+			current = nil
+		}
+		current = append(current, i)
+	}
+	if len(current) > 0 {
+		waves = append(waves, current)
+	}
+	return waves
+}
+
+// conflictsWithWave reports whether step i depends on a step of the wave,
+// or a step of the wave depends on it.
+func conflictsWithWave(invocations []agent.ToolInvocation, wave []int, i int) bool {
+	for _, j := range wave {
+		if stepDependsOn(&invocations[i], &invocations[j]) || stepDependsOn(&invocations[j], &invocations[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// stepDependsOn reports whether step a needs the output of step b.
+//
+// Description:
+//
+//	An explicit DependsOn list on a is authoritative: a depends on b when
+//	it names b's ID or output variable. Without one, a depends on b when
+//	one of its parameters consumes b's output variable as "$name".
+func stepDependsOn(a, b *agent.ToolInvocation) bool {
+	if len(a.DependsOn) > 0 {
+		for _, dep := range a.DependsOn {
+			if (b.ID != "" && dep == b.ID) || (b.OutputVar != "" && dep == b.OutputVar) {
+				return true
+			}
+		}
+		return false
+	}
+	return b.OutputVar != "" && consumesVar(a.Parameters, b.OutputVar)
+}
+
+// consumesVar reports whether any parameter references "$name".
+func consumesVar(params *agent.ToolParameters, name string) bool {
+	if params == nil {
+		return false
+	}
+	if len(params.RawJSON) > 0 {
+		return containsVarRef(string(params.RawJSON), name)
+	}
+	for _, v := range params.StringParams {
+		if containsVarRef(v, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsVarRef reports whether s contains "$name" not followed by
+// another identifier character, so "$callers" does not match "$callers2".
+func containsVarRef(s, name string) bool {
+	ref := "$" + name
+	for {
+		idx := strings.Index(s, ref)
+		if idx < 0 {
+			return false
+		}
+		s = s[idx+len(ref):]
+		if s == "" || !isIdentByte(s[0]) {
+			return true
+		}
+	}
+}
+
+// isIdentByte reports whether c can continue a variable name.
+func isIdentByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// hasSideEffects reports whether the named tool modifies state. Unknown
+// tools are treated as read-only; they fail on execution anyway.
+func hasSideEffects(deps *Dependencies, name string) bool {
+	if deps == nil || deps.ToolRegistry == nil {
+		return false
+	}
+	tool, ok := deps.ToolRegistry.Get(name)
+	if !ok {
+		return false
+	}
+	return tool.Definition().SideEffects
+}
+
+// runToolWave executes the pending steps of one wave.
+//
+// Description:
+//
+//	A single step runs inline. Several steps run concurrently, each with
+//	its own context. When a step of a plan group fails, the context shared
+//	by the group is cancelled so its running siblings stop; other steps
+//	are unaffected. Results are stored on the pending calls, so the caller
+//	merges them in plan order.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies.
+//	calls - The wave's steps in plan order. Modified in place.
+//
+// Outputs:
+//
+//	time.Duration - Wall-clock time saved: the sum of the step durations
+//	                minus the time the wave took. Zero for a single step.
+//
+// Thread Safety: Safe for concurrent use; each goroutine writes only its own call.
+func (p *ExecutePhase) runToolWave(ctx context.Context, deps *Dependencies, calls []*pendingToolCall) time.Duration {
+	switch len(calls) {
+	case 0:
+		return 0
+	case 1:
+		start := time.Now()
+		calls[0].result = p.executeSingleTool(ctx, deps, &calls[0].inv)
+		calls[0].duration = time.Since(start)
+		return 0
+	}
+
+	type groupState struct {
+		ctx    context.Context
+		cancel context.CancelCauseFunc
+		once   sync.Once
+		failed int
+	}
+	groups := make(map[string]*groupState)
+	for _, call := range calls {
+		if call.inv.Group != "" && groups[call.inv.Group] == nil {
+			gctx, cancel := context.WithCancelCause(ctx)
+			groups[call.inv.Group] = &groupState{ctx: gctx, cancel: cancel, failed: -1}
+		}
+	}
+
+	waveStart := time.Now()
+	var wg sync.WaitGroup
+	for _, call := range calls {
+		group := groups[call.inv.Group]
+		parent := ctx
+		if group != nil {
+			parent = group.ctx
+		}
+
+		wg.Add(1)
+		go func(call *pendingToolCall, parent context.Context, group *groupState) {
+			defer wg.Done()
+			toolCtx, cancel := context.WithCancel(parent)
+			defer cancel()
+
+			start := time.Now()
+			call.result = p.executeSingleTool(toolCtx, deps, &call.inv)
+			call.duration = time.Since(start)
+			call.cancelled = errors.Is(toolCtx.Err(), context.Canceled)
+
+			if group != nil && !call.result.Success && !isNotFoundError(call.result.Error) && parent.Err() == nil {
+				group.once.Do(func() {
+					group.failed = call.index
+					group.cancel(fmt.Errorf("step %d of group %q failed", call.index, call.inv.Group))
+				})
+			}
+		}(call, parent, group)
+	}
+	wg.Wait()
+	wall := time.Since(waveStart)
+
+	var total time.Duration
+	for _, call := range calls {
+		total += call.duration
+
+		// Siblings stopped by a group failure report why, not "context canceled".
+		// A sibling that failed on its own keeps its error.
+		if group := groups[call.inv.Group]; group != nil && group.failed >= 0 &&
+			group.failed != call.index && !call.result.Success && call.cancelled {
+			call.result.Error = "cancelled: " + context.Cause(group.ctx).Error()
+		}
+	}
+	for _, group := range groups {
+		group.cancel(nil)
+	}
+
+	slog.DebugContext(ctx, "Executed tool wave concurrently",
+		slog.Int("tools", len(calls)),
+		slog.Duration("wall", wall),
+		slog.Duration("sequential", total),
+	)
+	if total <= wall {
+		return 0
+	}
+	return total - wall
+}
+
+// recordParallelSavings records the wall-clock time a run saved by
+// executing independent steps concurrently. No-op if nothing ran in parallel.
+//
+// Inputs:
+//
+//	ctx - Context for logging.
+//	deps - Phase dependencies.
+//	calls - Tool calls that ran in a wave of two or more.
+//	saved - Wall-clock time saved.
+func (p *ExecutePhase) recordParallelSavings(ctx context.Context, deps *Dependencies, calls int, saved time.Duration) {
+	if calls == 0 {
+		return
+	}
+	parallelToolCalls.Add(float64(calls))
+	parallelTimeSaved.Observe(saved.Seconds())
+	if deps != nil && deps.Session != nil {
+		deps.Session.IncrementMetric(agent.MetricParallelToolCalls, calls)
+		deps.Session.IncrementMetric(agent.MetricParallelTimeSavedMs, int(saved.Milliseconds()))
+		slog.InfoContext(ctx, "Parallel tool execution saved wall-clock time",
+			slog.String("session_id", deps.Session.ID),
+			slog.Int("parallel_calls", calls),
+			slog.Duration("saved", saved),
+		)
+	}
+}
+
+// toolPlanPrompt explains the plan markings to the model.
+const toolPlanPrompt = "\n\n## Tool call plans\n" +
+	"Independent tool calls in one response run in parallel. When a call needs the result of another call " +
+	"in the same response, give the earlier call an \"_output\" argument naming its result and list that " +
+	"name in the later call's \"_depends_on\" argument. Calls that share a \"_group\" argument are cancelled " +
+	"together when one of them fails.\n"
+
+// appendToolPlanPrompt tells the model how to mark dependencies between
+// tool calls. No-op when tools run sequentially or the request has no tools.
+//
+// Inputs:
+//
+//	request - The LLM request. Modified in place.
+func (p *ExecutePhase) appendToolPlanPrompt(request *llm.Request) {
+	if request == nil || len(request.Tools) == 0 || p.parallelToolLimit <= 1 {
+		return
+	}
+	request.SystemPrompt += toolPlanPrompt
+}
@@ -0,0 +1,312 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+func planStep(id, tool, raw string) agent.ToolInvocation {
+	inv := agent.ToolInvocation{ID: id, Tool: tool}
+	if raw != "" {
+		inv.Parameters = &agent.ToolParameters{RawJSON: []byte(raw)}
+	}
+	return inv
+}
+
+func TestPlanToolWaves(t *testing.T) {
+	deps := createTestDependencies()
+	deps.ToolRegistry = tools.NewRegistry()
+	writer := tools.NewMockTool("write_file", tools.CategoryExploration)
+	writer.WithDefinition(tools.ToolDefinition{Name: "write_file", SideEffects: true})
+	deps.ToolRegistry.Register(writer)
+
+	withOutput := func(inv agent.ToolInvocation, name string) agent.ToolInvocation {
+		inv.OutputVar = name
+		return inv
+	}
+	withDeps := func(inv agent.ToolInvocation, on ...string) agent.ToolInvocation {
+		inv.DependsOn = on
+		return inv
+	}
+
+	tests := []struct {
+		name  string
+		limit int
+		plan  []agent.ToolInvocation
+		want  [][]int
+	}{
+		{
+			name:  "independent steps share a wave",
+			limit: 3,
+			plan:  []agent.ToolInvocation{planStep("a", "find_callers", ""), planStep("b", "find_implementations", ""), planStep("c", "read_file", "")},
+			want:  [][]int{{0, 1, 2}},
+		},
+		{
+			name:  "limit caps the wave",
+			limit: 2,
+			plan:  []agent.ToolInvocation{planStep("a", "t1", ""), planStep("b", "t2", ""), planStep("c", "t3", "")},
+			want:  [][]int{{0, 1}, {2}},
+		},
+		{
+			name:  "limit 1 is sequential",
+			limit: 1,
+			plan:  []agent.ToolInvocation{planStep("a", "t1", ""), planStep("b", "t2", "")},
+			want:  [][]int{{0}, {1}},
+		},
+		{
+			name:  "consumed output variable",
+			limit: 3,
+			plan: []agent.ToolInvocation{
+				withOutput(planStep("a", "find_symbol", ""), "sym"),
+				planStep("b", "find_callers", `{"symbol": "$sym"}`),
+				planStep("c", "find_callees", `{"symbol": "$symbol"}`),
+			},
+			want: [][]int{{0}, {1, 2}},
+		},
+		{
+			name:  "explicit dependency replaces detection",
+			limit: 3,
+			plan: []agent.ToolInvocation{
+				withOutput(planStep("a", "t1", ""), "x"),
+				withDeps(planStep("b", "t2", `{"q": "$x"}`), "c"),
+				planStep("c", "t3", ""),
+			},
+			want: [][]int{{0, 1}, {2}},
+		},
+		{
+			name:  "dependency by ID",
+			limit: 3,
+			plan:  []agent.ToolInvocation{planStep("a", "t1", ""), withDeps(planStep("b", "t2", ""), "a")},
+			want:  [][]int{{0}, {1}},
+		},
+		{
+			name:  "side effects run alone",
+			limit: 3,
+			plan:  []agent.ToolInvocation{planStep("a", "t1", ""), planStep("b", "write_file", ""), planStep("c", "t2", ""), planStep("d", "t3", "")},
+			want:  [][]int{{0}, {1}, {2, 3}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phase := NewExecutePhase(WithParallelToolLimit(tt.limit))
+			if got := phase.planToolWaves(deps, tt.plan); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("planToolWaves() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// registerWaveTool registers a tool that waits until all of started have
+// begun, so the test only passes when the tools run concurrently.
+func registerWaveTool(registry *tools.Registry, name string, started *sync.WaitGroup, run func(ctx context.Context) (*tools.Result, error)) {
+	tool := tools.NewMockTool(name, tools.CategoryExploration)
+	tool.ExecuteFunc = func(ctx context.Context, _ tools.TypedParams) (*tools.Result, error) {
+		if started != nil {
+			started.Done()
+			done := make(chan struct{})
+			go func() { started.Wait(); close(done) }()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				return nil, errors.New("siblings did not start concurrently")
+			}
+		}
+		return run(ctx)
+	}
+	registry.Register(tool)
+}
+
+func TestExecuteToolCalls_ParallelKeepsPlanOrder(t *testing.T) {
+	phase := NewExecutePhase(WithSafetyCheck(false))
+	deps := createTestDependencies()
+	deps.ToolRegistry = tools.NewRegistry()
+	deps.ToolExecutor = tools.NewExecutor(deps.ToolRegistry, nil)
+
+	var started sync.WaitGroup
+	started.Add(3)
+	delays := map[string]time.Duration{"slow": 60 * time.Millisecond, "medium": 30 * time.Millisecond, "fast": 0}
+	for name, delay := range delays {
+		name, delay := name, delay
+		registerWaveTool(deps.ToolRegistry, name, &started, func(context.Context) (*tools.Result, error) {
+			time.Sleep(delay)
+			return &tools.Result{Success: true, OutputText: name}, nil
+		})
+	}
+
+	var resultSteps []int
+	var invocationSteps []int
+	deps.EventEmitter.Subscribe(func(e *events.Event) {
+		switch data := e.Data.(type) {
+		case *events.ToolInvocationData:
+			invocationSteps = append(invocationSteps, data.StepIndex)
+		case *events.ToolResultData:
+			resultSteps = append(resultSteps, data.StepIndex)
+		}
+	}, events.TypeToolInvocation, events.TypeToolResult)
+
+	plan := []agent.ToolInvocation{planStep("1", "slow", ""), planStep("2", "medium", ""), planStep("3", "fast", "")}
+	results, invs, blocked := phase.executeToolCalls(context.Background(), deps, plan)
+	if blocked {
+		t.Fatal("unexpected blocked batch")
+	}
+	if len(results) != 3 || len(invs) != 3 {
+		t.Fatalf("got %d results for %d invocations, want 3", len(results), len(invs))
+	}
+	for i, want := range []string{"slow", "medium", "fast"} {
+		if !results[i].Success || results[i].OutputText != want {
+			t.Errorf("results[%d] = %+v, want %s", i, results[i], want)
+		}
+	}
+	if want := []int{0, 1, 2}; !reflect.DeepEqual(invocationSteps, want) || !reflect.DeepEqual(resultSteps, want) {
+		t.Errorf("event step indexes = %v / %v, want %v", invocationSteps, resultSteps, want)
+	}
+
+	metrics := deps.Session.GetMetrics()
+	if metrics.ParallelToolCalls != 3 {
+		t.Errorf("ParallelToolCalls = %d, want 3", metrics.ParallelToolCalls)
+	}
+	if metrics.ParallelTimeSavedMs <= 0 {
+		t.Errorf("ParallelTimeSavedMs = %d, want > 0", metrics.ParallelTimeSavedMs)
+	}
+}
+
+func TestExecuteToolCalls_GroupFailureCancelsOnlyGroup(t *testing.T) {
+	phase := NewExecutePhase(WithSafetyCheck(false))
+	deps := createTestDependencies()
+	deps.ToolRegistry = tools.NewRegistry()
+	deps.ToolExecutor = tools.NewExecutor(deps.ToolRegistry, nil)
+
+	var started sync.WaitGroup
+	started.Add(3)
+	registerWaveTool(deps.ToolRegistry, "failing", &started, func(context.Context) (*tools.Result, error) {
+		return nil, errors.New("backend unavailable")
+	})
+	registerWaveTool(deps.ToolRegistry, "grouped", &started, func(ctx context.Context) (*tools.Result, error) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+			return &tools.Result{Success: true, OutputText: "not cancelled"}, nil
+		}
+	})
+	registerWaveTool(deps.ToolRegistry, "independent", &started, func(context.Context) (*tools.Result, error) {
+		time.Sleep(20 * time.Millisecond)
+		return &tools.Result{Success: true, OutputText: "independent"}, nil
+	})
+	registerWaveTool(deps.ToolRegistry, "later", nil, func(context.Context) (*tools.Result, error) {
+		return &tools.Result{Success: true, OutputText: "later"}, nil
+	})
+
+	plan := []agent.ToolInvocation{
+		planStep("1", "failing", ""),
+		planStep("2", "grouped", ""),
+		planStep("3", "independent", ""),
+		planStep("4", "later", ""),
+	}
+	plan[0].Group, plan[1].Group, plan[3].Group = "g", "g", "g"
+	plan[3].DependsOn = []string{"3"}
+
+	start := time.Now()
+	results, _, _ := phase.executeToolCalls(context.Background(), deps, plan)
+	if elapsed := time.Since(start); elapsed > 4*time.Second {
+		t.Fatalf("group sibling was not cancelled (took %v)", elapsed)
+	}
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want 4", len(results))
+	}
+	if results[0].Success || !strings.Contains(results[0].Error, "backend unavailable") {
+		t.Errorf("failing step = %+v", results[0])
+	}
+	if results[1].Success || !strings.Contains(results[1].Error, `step 0 of group "g" failed`) {
+		t.Errorf("grouped sibling = %+v, want cancelled by step 0", results[1])
+	}
+	if !results[2].Success || results[2].OutputText != "independent" {
+		t.Errorf("independent step = %+v, want success", results[2])
+	}
+	if results[3].Success || !strings.Contains(results[3].Error, "skipped") {
+		t.Errorf("later group step = %+v, want skipped", results[3])
+	}
+}
+
+func TestExecuteToolCalls_GroupFailureKeepsSiblingOwnError(t *testing.T) {
+	phase := NewExecutePhase(WithSafetyCheck(false))
+	deps := createTestDependencies()
+	deps.ToolRegistry = tools.NewRegistry()
+	deps.ToolExecutor = tools.NewExecutor(deps.ToolRegistry, nil)
+
+	var started sync.WaitGroup
+	started.Add(2)
+	// A "not found" failure does not cancel the group, so this sibling
+	// fails on its own before the other step cancels the group.
+	registerWaveTool(deps.ToolRegistry, "missing", &started, func(context.Context) (*tools.Result, error) {
+		return &tools.Result{Success: false, Error: "symbol Foo not found"}, nil
+	})
+	registerWaveTool(deps.ToolRegistry, "failing", &started, func(context.Context) (*tools.Result, error) {
+		time.Sleep(50 * time.Millisecond)
+		return nil, errors.New("backend unavailable")
+	})
+
+	plan := []agent.ToolInvocation{
+		planStep("1", "missing", ""),
+		planStep("2", "failing", ""),
+	}
+	plan[0].Group, plan[1].Group = "g", "g"
+
+	results, _, _ := phase.executeToolCalls(context.Background(), deps, plan)
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	// Kept as a "not found" result, which the executor reports as success.
+	if strings.Contains(results[0].Error, "cancelled") || !strings.Contains(results[0].OutputText, "Not Found") {
+		t.Errorf("sibling = %+v, want its own not-found result", results[0])
+	}
+	if results[1].Success || !strings.Contains(results[1].Error, "backend unavailable") {
+		t.Errorf("failing step = %+v", results[1])
+	}
+}
+
+func TestExecuteToolCalls_UngroupedFailureKeepsSiblings(t *testing.T) {
+	phase := NewExecutePhase(WithSafetyCheck(false))
+	deps := createTestDependencies()
+	deps.ToolRegistry = tools.NewRegistry()
+	deps.ToolExecutor = tools.NewExecutor(deps.ToolRegistry, nil)
+
+	var started sync.WaitGroup
+	started.Add(2)
+	registerWaveTool(deps.ToolRegistry, "failing", &started, func(context.Context) (*tools.Result, error) {
+		return nil, errors.New("backend unavailable")
+	})
+	registerWaveTool(deps.ToolRegistry, "sibling", &started, func(ctx context.Context) (*tools.Result, error) {
+		time.Sleep(20 * time.Millisecond)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return &tools.Result{Success: true, OutputText: "sibling"}, nil
+	})
+
+	results, _, _ := phase.executeToolCalls(context.Background(), deps,
+		[]agent.ToolInvocation{planStep("1", "failing", ""), planStep("2", "sibling", "")})
+	if len(results) != 2 || results[0].Success || !results[1].Success {
+		t.Fatalf("results = %+v, want failure then success", results)
+	}
+}
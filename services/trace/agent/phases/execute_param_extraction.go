@@ -22,6 +22,7 @@ import (
 	"unicode"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // extractPackageNameFromQuery extracts a package name from a query string.
@@ -902,6 +903,61 @@ func extractInterfaceNameFromQuery(query string) string {
 	return ""
 }
 
+// extractImplementsPairFromQuery extracts the type and interface from a
+// question about one type implementing one interface.
+//
+// Description:
+//
+//	Handles "why doesn't Mem implement Store", "does Mem satisfy Store?"
+//	and "is Mem an implementation of Store": the type is the nearest valid
+//	name before the verb, the interface the first valid name after it.
+//	Negations and auxiliaries between the type and the verb are skipped.
+//
+// Outputs:
+//
+//	typeName, ifaceName - Both empty unless both are found.
+func extractImplementsPairFromQuery(query string) (typeName, ifaceName string) {
+	words := strings.Fields(query)
+	for i, w := range words {
+		lower := strings.ToLower(strings.Trim(w, "?,.()'\""))
+		verb := strings.HasPrefix(lower, "implement") || strings.HasPrefix(lower, "satisf") ||
+			lower == "conform" || lower == "conforms"
+		if !verb {
+			continue
+		}
+		for j := i - 1; j >= 0 && typeName == ""; j-- {
+			candidate := strings.Trim(words[j], "?,.()'\"")
+			candidateLower := strings.ToLower(candidate)
+			if candidateLower == "not" || candidateLower == "doesn't" || candidateLower == "dont" ||
+				candidateLower == "doesnt" || candidateLower == "don't" || candidateLower == "an" {
+				continue
+			}
+			if isValidFunctionName(candidate) && !isQueryKeyword(candidateLower) {
+				typeName = candidate
+			}
+			break
+		}
+		for j := i + 1; j < len(words) && ifaceName == ""; j++ {
+			candidate := strings.Trim(words[j], "?,.()'\"")
+			candidateLower := strings.ToLower(candidate)
+			if candidateLower == "the" || candidateLower == "a" || candidateLower == "an" ||
+				candidateLower == "of" || candidateLower == "to" || candidateLower == "interface" ||
+				candidateLower == "protocol" {
+				continue
+			}
+			if isValidFunctionName(candidate) && !isQueryKeyword(candidateLower) {
+				ifaceName = candidate
+			}
+			break
+		}
+		if typeName != "" && ifaceName != "" {
+			return typeName, ifaceName
+		}
+		typeName, ifaceName = "", ""
+	}
+	return "", ""
+}
+
 // isQueryKeyword returns true if the word is a common query keyword that should
 // not be extracted as a symbol name.
 func isQueryKeyword(lower string) bool {
@@ -1102,9 +1158,9 @@ func extractTypeDotMethodFromQuery(query string) string {
 		if isValidTypeName(typeName) {
 			result := typeName + "." + methodName
 			slog.Debug("extractTypeDotMethodFromQuery: matched",
-				slog.String("query", query),
+				telemetry.SensitiveLog("query", query),
 				slog.String("type", typeName),
-				slog.String("method", methodName),
+				telemetry.SensitiveLog("method", methodName),
 				slog.String("result", result),
 			)
 			return result
@@ -1112,7 +1168,7 @@ func extractTypeDotMethodFromQuery(query string) string {
 	}
 
 	slog.Debug("extractTypeDotMethodFromQuery: no match",
-		slog.String("query", query),
+		telemetry.SensitiveLog("query", query),
 	)
 	return ""
 }
@@ -1307,6 +1363,48 @@ func extractSearchPatternFromQuery(query string) string {
 	return ""
 }
 
+// literalQuotedRegex matches a value in double, single or back quotes.
+var literalQuotedRegex = regexp.MustCompile("\"([^\"]+)\"|'([^']+)'|`([^`]+)`")
+
+// literalNumberRegex matches a number following "to", "as", "of", "=" or
+// "is", e.g. "timeout to 30 seconds". Captures the number in group 1.
+var literalNumberRegex = regexp.MustCompile(`(?i)(?:\bto|\bas|\bof|\bis|=)\s*(-?\d+(?:\.\d+)?)\b`)
+
+// extractLiteralValueFromQuery extracts the literal a find_literal_usages
+// query searches for.
+//
+// Description:
+//
+//	Prefers a quoted value ("production", 'prod', `v1`), then a number
+//	introduced by "to", "as", "of", "is" or "=" ("set the timeout to 30"),
+//	then a standalone true or false.
+//
+// Inputs:
+//
+//	query - The user's query string.
+//
+// Outputs:
+//
+//	string - The literal value, or empty if none was found.
+func extractLiteralValueFromQuery(query string) string {
+	if m := literalQuotedRegex.FindStringSubmatch(query); m != nil {
+		for _, g := range m[1:] {
+			if strings.TrimSpace(g) != "" {
+				return g
+			}
+		}
+	}
+	if m := literalNumberRegex.FindStringSubmatch(query); m != nil {
+		return m[1]
+	}
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		if w := strings.Trim(word, "?,.()"); w == "true" || w == "false" {
+			return w
+		}
+	}
+	return ""
+}
+
 // -----------------------------------------------------------------------------
 // Parameter Extraction Helpers (GR-Phase1)
 // -----------------------------------------------------------------------------
@@ -1760,15 +1858,15 @@ func validateExtractedPathParams(from, to, query string) (string, string, bool)
 				slog.String("rule", reason),
 				slog.String("original_to", to),
 				slog.String("re_derived_to", reDerived),
-				slog.String("from", from),
+				telemetry.SensitiveLog("from", from),
 			)
 			to = reDerived
 			changed = true
 		} else {
 			slog.Debug("D3b: param validation rule fired but re-derivation unchanged",
 				slog.String("rule", reason),
-				slog.String("to", to),
-				slog.String("from", from),
+				telemetry.SensitiveLog("to", to),
+				telemetry.SensitiveLog("from", from),
 			)
 		}
 	}
@@ -938,6 +938,28 @@ func TestExtractInterfaceNameFromQuery(t *testing.T) {
 	}
 }
 
+func TestExtractImplementsPairFromQuery(t *testing.T) {
+	tests := []struct {
+		query     string
+		wantType  string
+		wantIface string
+	}{
+		{"Why doesn't MemStore implement Store?", "MemStore", "Store"},
+		{"Does Engine satisfy the IRouter interface?", "Engine", "IRouter"},
+		{"Is Server an implementation of Handler?", "Server", "Handler"},
+		{"What implements the Reader interface?", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			gotType, gotIface := extractImplementsPairFromQuery(tt.query)
+			if gotType != tt.wantType || gotIface != tt.wantIface {
+				t.Errorf("extractImplementsPairFromQuery(%q) = (%q, %q), want (%q, %q)",
+					tt.query, gotType, gotIface, tt.wantType, tt.wantIface)
+			}
+		})
+	}
+}
+
 func TestIsFileExtension(t *testing.T) {
 	// File extensions should be rejected
 	for _, ext := range []string{"js", "ts", "py", "go", "rs", "java", "css", "html", "json"} {
@@ -2023,6 +2045,29 @@ func TestExtractSortByFromQuery(t *testing.T) {
 	}
 }
 
+func TestExtractLiteralValueFromQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "double quoted", query: `Which call sites pass "production" as the environment?`, want: "production"},
+		{name: "single quoted", query: "Where do we pass 'us-east-1'?", want: "us-east-1"},
+		{name: "number after to", query: "Where do we set the timeout to 30 seconds?", want: "30"},
+		{name: "number after equals", query: "Find calls with retries=3", want: "3"},
+		{name: "bool", query: "Which calls pass true for insecure?", want: "true"},
+		{name: "none", query: "Where is the timeout configured?", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractLiteralValueFromQuery(tt.query); got != tt.want {
+				t.Errorf("extractLiteralValueFromQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
 // IT-07 Phase 3: Test extractExcludeTestsFromQuery.
 func TestExtractExcludeTestsFromQuery(t *testing.T) {
 	tests := []struct {
@@ -28,6 +28,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/integration"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -98,7 +99,7 @@ func (p *ExecutePhase) retryWithStrongerToolChoice(
 ) (agent.AgentState, error) {
 	forcingRetries := deps.Session.GetMetric(agent.MetricToolForcingRetries)
 
-	slog.Info("Retrying with stronger tool_choice",
+	slog.InfoContext(ctx, "Retrying with stronger tool_choice",
 		slog.String("session_id", deps.Session.ID),
 		slog.String("validation_reason", validation.Reason),
 		slog.Int("retry", forcingRetries+1),
@@ -115,7 +116,7 @@ func (p *ExecutePhase) retryWithStrongerToolChoice(
 	// Get stronger tool_choice for retry
 	retryToolChoice := p.responseValidator.GetRetryToolChoice(forcingRetries+1, nil, suggestedTool)
 
-	slog.Info("Escalating tool_choice for retry",
+	slog.InfoContext(ctx, "Escalating tool_choice for retry",
 		slog.String("session_id", deps.Session.ID),
 		slog.String("new_tool_choice_type", retryToolChoice.Type),
 		slog.String("new_tool_choice_name", retryToolChoice.Name),
@@ -184,7 +185,7 @@ func (p *ExecutePhase) retryWithQualityCorrection(
 ) (agent.AgentState, error) {
 	forcingRetries := deps.Session.GetMetric(agent.MetricToolForcingRetries)
 
-	slog.Info("Retrying with quality correction",
+	slog.InfoContext(ctx, "Retrying with quality correction",
 		slog.String("session_id", deps.Session.ID),
 		slog.String("validation_reason", validation.Reason),
 		slog.Int("retry", forcingRetries+1),
@@ -261,7 +262,7 @@ func (p *ExecutePhase) retryDesperationWithStrongerPrompt(
 	stepStart time.Time,
 	stepNumber int,
 ) (agent.AgentState, error) {
-	slog.Info("Retrying with stronger anti-tool-call prompt (CB-31d TR-5)",
+	slog.InfoContext(ctx, "Retrying with stronger anti-tool-call prompt (CB-31d TR-5)",
 		slog.String("session_id", deps.Session.ID),
 	)
 
@@ -269,6 +270,8 @@ func (p *ExecutePhase) retryDesperationWithStrongerPrompt(
 	// Previously we called buildLLMRequest which triggered the router, then ignored the result.
 	// This avoids duplicate router calls and the associated semantic correction warnings.
 	request := llm.BuildRequest(deps.Context, nil, p.maxTokens) // nil tools - desperation mode
+	appendCitationPrompt(deps, request)
+	appendGraphQualityPrompt(deps, request)
 
 	// CB-62: Apply per-session main model override.
 	if deps.Session != nil && deps.Session.Config.MainModel != "" {
@@ -309,14 +312,14 @@ If you need more information, say "I don't have enough information" and explain
 	// Retry the LLM call
 	response, err := p.callLLM(ctx, deps, request)
 	if err != nil {
-		slog.Error("Desperation retry LLM call failed",
+		slog.ErrorContext(ctx, "Desperation retry LLM call failed",
 			slog.String("session_id", deps.Session.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return p.handleLLMError(deps, err)
 	}
 
-	slog.Info("Desperation retry LLM response received",
+	slog.InfoContext(ctx, "Desperation retry LLM response received",
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("output_tokens", response.OutputTokens),
 		slog.Bool("has_tool_calls", response.HasToolCalls()),
@@ -325,7 +328,7 @@ If you need more information, say "I don't have enough information" and explain
 
 	// Check again - if it STILL has escaped tool calls, give up and complete anyway
 	if !response.HasToolCalls() && containsToolCallPattern(response.Content) {
-		slog.Error("Circuit breaker desperation: LLM STILL escaping after retry (CB-31d)",
+		slog.ErrorContext(ctx, "Circuit breaker desperation: LLM STILL escaping after retry (CB-31d)",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("response_preview", truncateForLog(response.Content, 300)),
 		)
@@ -857,7 +860,7 @@ func (p *ExecutePhase) checkCycleAfterStep(
 	result := crs.CheckCycleOnStep(ctx, crsInstance, step, detector)
 
 	if result.Detected {
-		slog.Warn("CRS-03: Reasoning cycle detected",
+		slog.WarnContext(ctx, "CRS-03: Reasoning cycle detected",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("tool", inv.Tool),
 			slog.Int("cycle_length", result.CycleLength),
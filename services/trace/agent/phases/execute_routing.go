@@ -168,7 +168,7 @@ func (p *ExecutePhase) selectToolWithUCB1(
 	cacheKey := ucb1Ctx.stateKeyBuilder.BuildKey(steps, generation)
 	if tool, score, ok := ucb1Ctx.cache.Get(cacheKey, generation); ok {
 		routing.RecordUCB1CacheHit()
-		slog.Debug("CRS-05: UCB1 cache hit",
+		slog.DebugContext(ctx, "CRS-05: UCB1 cache hit",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("tool", tool),
 			slog.Float64("score", score),
@@ -192,7 +192,7 @@ func (p *ExecutePhase) selectToolWithUCB1(
 
 	if forcedResult.IsForced {
 		routing.RecordUCB1ForcedMove(forcedResult.ForcedTool)
-		slog.Info("CRS-05: Forced move detected",
+		slog.InfoContext(ctx, "CRS-05: Forced move detected",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("forced_tool", forcedResult.ForcedTool),
 			slog.Int("blocked_count", len(forcedResult.BlockedTools)),
@@ -214,7 +214,7 @@ func (p *ExecutePhase) selectToolWithUCB1(
 	// CR-4 fix: Use ViableCount from CheckForcedMove instead of calling CheckAllBlocked
 	if forcedResult.ViableCount == 0 && len(availableTools) > 0 {
 		routing.RecordUCB1AllBlocked()
-		slog.Warn("CRS-05: All tools blocked by clauses",
+		slog.WarnContext(ctx, "CRS-05: All tools blocked by clauses",
 			slog.String("session_id", deps.Session.ID),
 			slog.Int("blocked_count", len(forcedResult.BlockedTools)),
 		)
@@ -296,7 +296,7 @@ func (p *ExecutePhase) selectToolWithUCB1(
 	modified := bestTool != routerSelection.Tool
 
 	if modified {
-		slog.Info("CRS-05: UCB1 modified tool selection",
+		slog.InfoContext(ctx, "CRS-05: UCB1 modified tool selection",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("router_suggested", routerSelection.Tool),
 			slog.String("ucb1_selected", bestTool),
@@ -306,7 +306,7 @@ func (p *ExecutePhase) selectToolWithUCB1(
 			slog.Float64("exploration_bonus", bestScore.ExplorationBonus),
 		)
 	} else {
-		slog.Debug("CRS-05: UCB1 confirmed router selection",
+		slog.DebugContext(ctx, "CRS-05: UCB1 confirmed router selection",
 			slog.String("session_id", deps.Session.ID),
 			slog.String("tool", bestTool),
 			slog.Float64("ucb1_score", bestScore.FinalScore),
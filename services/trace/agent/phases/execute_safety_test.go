@@ -0,0 +1,86 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+
+package phases
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+)
+
+// TestIsBlockedBySafety_PolicyDenial verifies that the safety policy is
+// applied to every tool, using the session's run mode, and that denials
+// emit a safety check event carrying the rule ID.
+func TestIsBlockedBySafety_PolicyDenial(t *testing.T) {
+	policy, err := safety.ParsePolicy([]byte("tools:\n  readonly:\n    allow: [\"find_*\"]\npaths:\n  deny: [\"**/.env\"]\n"))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+
+	config := agent.DefaultSessionConfig()
+	config.RunMode = "readonly"
+	session, err := agent.NewSession("/test/project", config)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+
+	emitter := events.NewEmitter()
+	var got []*events.SafetyCheckData
+	emitter.Subscribe(func(e *events.Event) {
+		if data, ok := e.Data.(*events.SafetyCheckData); ok {
+			got = append(got, data)
+		}
+	}, events.TypeSafetyCheck)
+
+	deps := &Dependencies{
+		Session:      session,
+		SafetyGate:   safety.NewPolicyGate(policy),
+		EventEmitter: emitter,
+	}
+	phase := NewExecutePhase()
+
+	tests := []struct {
+		name     string
+		inv      agent.ToolInvocation
+		blocked  bool
+		wantRule string
+	}{
+		{"allowed read-only tool", agent.ToolInvocation{Tool: "find_callers"}, false, ""},
+		{"tool outside mode allow list", agent.ToolInvocation{Tool: "summarize_file"}, true, "tools.readonly.allow"},
+		{"denied path in RawJSON", agent.ToolInvocation{
+			Tool:       "find_callers",
+			Parameters: &agent.ToolParameters{RawJSON: []byte(`{"file_path": "cfg/.env"}`)},
+		}, true, "paths.deny[0]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got = nil
+			result := phase.isBlockedBySafety(context.Background(), deps, &tt.inv, "node")
+			if result.Blocked != tt.blocked {
+				t.Fatalf("Blocked = %v, want %v (%s)", result.Blocked, tt.blocked, result.ErrorMessage)
+			}
+			if !tt.blocked {
+				if len(got) != 0 {
+					t.Errorf("unexpected safety events: %+v", got)
+				}
+				return
+			}
+			if !strings.Contains(result.ErrorMessage, tt.wantRule) {
+				t.Errorf("ErrorMessage %q missing rule %s", result.ErrorMessage, tt.wantRule)
+			}
+			if len(got) != 1 || got[0].RuleID != tt.wantRule || !got[0].Blocked || got[0].Mode != "readonly" {
+				t.Errorf("events = %+v, want one blocked event for %s", got, tt.wantRule)
+			}
+		})
+	}
+}
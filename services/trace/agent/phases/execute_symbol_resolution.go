@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -101,7 +102,7 @@ func resolveSymbol(
 	ctx := context.Background()
 	ctx, span := symbolResolutionTracer.Start(ctx, "resolveSymbol",
 		trace.WithAttributes(
-			attribute.String("name", name),
+			telemetry.SensitiveString("name", name),
 		),
 	)
 	defer span.End()
@@ -125,17 +126,17 @@ func resolveSymbol(
 		)
 		if err == nil {
 			slog.Debug("CB-31d: symbol resolution complete",
-				slog.String("name", name),
-				slog.String("resolved", symbolID),
+				telemetry.SensitiveLog("name", name),
+				telemetry.SensitiveLog("resolved", symbolID),
 				slog.String("strategy", strategy),
 				slog.Float64("confidence", confidence),
 				slog.Duration("duration", duration),
 			)
 		} else {
 			slog.Debug("CB-31d: symbol resolution failed",
-				slog.String("name", name),
+				telemetry.SensitiveLog("name", name),
 				slog.Duration("duration", duration),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 		}
 	}()
@@ -188,7 +189,7 @@ func resolveSymbol(
 		}
 		span.SetAttributes(
 			attribute.Bool("function_preferred", isFuncOrMethod),
-			attribute.String("disambiguated_file", best.FilePath),
+			telemetry.SensitiveString("disambiguated_file", best.FilePath),
 		)
 		return best.ID, conf, strat, nil
 	}
@@ -630,8 +631,8 @@ func resolveSymbolCached(
 			// CB-31d: Record cache hit metric
 			symbolCacheHits.Inc()
 			slog.Debug("CB-31d: symbol resolution: cache hit",
-				slog.String("name", name),
-				slog.String("resolved", result.SymbolID),
+				telemetry.SensitiveLog("name", name),
+				telemetry.SensitiveLog("resolved", result.SymbolID),
 				slog.Float64("confidence", result.Confidence),
 				slog.String("strategy", result.Strategy),
 			)
@@ -656,8 +657,8 @@ func resolveSymbolCached(
 	})
 
 	slog.Debug("CB-31d: symbol resolution: cache miss, resolved and cached",
-		slog.String("name", name),
-		slog.String("resolved", symbolID),
+		telemetry.SensitiveLog("name", name),
+		telemetry.SensitiveLog("resolved", symbolID),
 		slog.Float64("confidence", confidence),
 		slog.String("strategy", strategy),
 	)
@@ -736,18 +737,18 @@ func resolveFirstCandidate(
 			}
 
 			if !hasCallEdges && candidate != candidates[len(candidates)-1] {
-				slog.Debug("IT-05: skipping candidate with 0 outgoing call edges",
-					slog.String("candidate", candidate),
-					slog.String("symbol_id", resolved),
+				slog.DebugContext(ctx, "IT-05: skipping candidate with 0 outgoing call edges",
+					telemetry.SensitiveLog("candidate", candidate),
+					telemetry.SensitiveLog("symbol_id", resolved),
 					slog.Int("remaining_candidates", len(candidates)-1),
 				)
 				continue
 			}
 
 			if len(candidates) > 1 {
-				slog.Debug("IT-00a-1: resolved via candidate loop",
+				slog.DebugContext(ctx, "IT-00a-1: resolved via candidate loop",
 					slog.String("resolved_candidate", candidate),
-					slog.String("symbol_id", resolved),
+					telemetry.SensitiveLog("symbol_id", resolved),
 					slog.Int("total_candidates", len(candidates)),
 					slog.Bool("passed_fanout_gate", hasCallEdges),
 				)
@@ -760,7 +761,7 @@ func resolveFirstCandidate(
 	// IT-05 R4 Fix: If we had a resolution that failed the fan-out gate but
 	// no better candidate was found, return the fallback rather than failing.
 	if hasFallback {
-		slog.Debug("IT-05: returning fallback resolution (all candidates had 0 call edges)",
+		slog.DebugContext(ctx, "IT-05: returning fallback resolution (all candidates had 0 call edges)",
 			slog.String("fallback_candidate", fallbackName),
 			slog.String("fallback_id", fallbackID),
 		)
@@ -773,9 +774,9 @@ func resolveFirstCandidate(
 	// This handles "concept queries" where users describe flows ("memtable flush")
 	// rather than naming functions directly.
 	if stemID, stemName, stemConf := stemExpansionFallback(ctx, candidates, deps); stemID != "" {
-		slog.Debug("IT-05 R5: stem expansion resolved concept query",
+		slog.DebugContext(ctx, "IT-05 R5: stem expansion resolved concept query",
 			slog.String("stem_candidate", stemName),
-			slog.String("resolved_id", stemID),
+			telemetry.SensitiveLog("resolved_id", stemID),
 		)
 		return stemID, stemName, stemConf, nil
 	}
@@ -908,9 +909,9 @@ func stemExpansionFallback(
 		conf = 0.5
 	}
 
-	slog.Debug("IT-05 R7: stem expansion picked best match",
-		slog.String("candidate", best.candidate),
-		slog.String("symbol_id", best.id),
+	slog.DebugContext(ctx, "IT-05 R7: stem expansion picked best match",
+		telemetry.SensitiveLog("candidate", best.candidate),
+		telemetry.SensitiveLog("symbol_id", best.id),
 		slog.Int("score", best.score),
 		slog.Int("total_matches", len(allMatches)),
 	)
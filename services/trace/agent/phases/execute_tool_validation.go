@@ -18,6 +18,7 @@ import (
 	"strings"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -162,7 +163,7 @@ func hasSemanticCorrectionForQuery(session *agent.Session, query, correctedTool
 			slog.Int("steps", len(steps)),
 			slog.Int("semantic_corrections", semanticCount),
 			slog.String("looking_for", correctedTool),
-			slog.String("query_prefix", queryPreview[:min(30, len(queryPreview))]),
+			telemetry.SensitiveLog("query_prefix", queryPreview[:min(30, len(queryPreview))]),
 		)
 	}
 
@@ -185,7 +186,7 @@ func hasSemanticCorrectionForQuery(session *agent.Session, query, correctedTool
 			// If no query recorded, consider it a match for safety
 			// (older correction, same tool)
 			slog.Debug("GR-Phase1: found match (no query metadata)",
-				slog.String("target", step.Target),
+				telemetry.SensitiveLog("target", step.Target),
 			)
 			return true
 		}
@@ -200,7 +201,7 @@ func hasSemanticCorrectionForQuery(session *agent.Session, query, correctedTool
 		}
 		if minLen > 0 && queryPreview[:minLen] == stepQuery[:minLen] {
 			slog.Debug("GR-Phase1: found match (prefix)",
-				slog.String("target", step.Target),
+				telemetry.SensitiveLog("target", step.Target),
 			)
 			return true
 		}
@@ -208,14 +209,14 @@ func hasSemanticCorrectionForQuery(session *agent.Session, query, correctedTool
 		// Also match if one is a prefix of the other
 		if strings.HasPrefix(stepQuery, queryPreview) || strings.HasPrefix(queryPreview, stepQuery) {
 			slog.Debug("GR-Phase1: found match (hasPrefix)",
-				slog.String("target", step.Target),
+				telemetry.SensitiveLog("target", step.Target),
 			)
 			return true
 		}
 
 		slog.Debug("GR-Phase1: near miss in semantic correction check",
 			slog.String("action", step.Action),
-			slog.String("target", step.Target),
+			telemetry.SensitiveLog("target", step.Target),
 		)
 	}
 
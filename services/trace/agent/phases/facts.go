@@ -0,0 +1,133 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
+)
+
+// knownFactsHeading introduces the known facts in the system prompt.
+const knownFactsHeading = "## Known facts about this project\n" +
+	"Users confirmed or corrected these in earlier sessions. Prefer them over assumptions; " +
+	"verify with tools when an answer depends on one.\n"
+
+// proposeFact keeps the fact a user message states, if it explicitly
+// confirms or corrects something (see facts.Detect). Facts that name no
+// symbol of the graph are dropped. No-op without a fact recorder.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies.
+//	text - The user's message.
+//	confirmedBy - facts.ConfirmedByClarification or facts.ConfirmedByFollowUp.
+func proposeFact(ctx context.Context, deps *Dependencies, text, confirmedBy string) {
+	if deps.Facts == nil {
+		return
+	}
+	candidate, ok := facts.Detect(text)
+	if !ok {
+		return
+	}
+	candidate.SessionID = deps.Session.ID
+	candidate.ConfirmedBy = confirmedBy
+
+	f, err := deps.Facts.ProposeFact(ctx, candidate)
+	if err != nil {
+		slog.Info("Candidate fact not kept",
+			slog.String("session_id", deps.Session.ID),
+			slog.String("kind", candidate.Kind),
+			slog.String("reason", err.Error()),
+		)
+		return
+	}
+	deps.Session.AddHistoryEntry(agent.HistoryEntry{
+		Type:   "fact_recorded",
+		Input:  text,
+		Output: fmt.Sprintf("%s %s: %s", f.Kind, f.ID, f.Statement),
+	})
+	slog.Info("Project fact recorded",
+		slog.String("session_id", deps.Session.ID),
+		slog.String("fact_id", f.ID),
+		slog.String("kind", f.Kind),
+		slog.Int("anchors", len(f.Anchors)),
+	)
+}
+
+// proposeFollowUpFacts looks for facts in the follow-up messages of the
+// session not yet looked at. A follow-up answers the agent's previous
+// answer, so it is where users confirm or correct it.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies.
+func (p *ReflectPhase) proposeFollowUpFacts(ctx context.Context, deps *Dependencies) {
+	if deps.Facts == nil {
+		return
+	}
+	for i, entry := range deps.Session.GetHistory() {
+		if entry.Type != "follow_up" {
+			continue
+		}
+		flag := fmt.Sprintf("facts_checked_%d", i)
+		if deps.Session.HasFlag(flag) {
+			continue
+		}
+		deps.Session.SetFlag(flag)
+		proposeFact(ctx, deps, entry.Input, facts.ConfirmedByFollowUp)
+	}
+}
+
+// appendKnownFacts adds the known facts about the project to the system
+// prompt of the initial context, and records that they were applied with
+// a TypeFactsApplied event and a history entry. No-op without facts.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies.
+//	assembled - The initial context. Modified in place.
+func appendKnownFacts(ctx context.Context, deps *Dependencies, assembled *agent.AssembledContext) {
+	if deps == nil || assembled == nil || len(deps.KnownFacts) == 0 {
+		return
+	}
+	assembled.SystemPrompt += "\n\n" + knownFactsHeading + facts.Render(deps.KnownFacts)
+
+	ids := make([]string, len(deps.KnownFacts))
+	statements := make([]string, len(deps.KnownFacts))
+	for i, f := range deps.KnownFacts {
+		ids[i] = f.ID
+		statements[i] = f.Statement
+	}
+	if deps.Facts != nil {
+		deps.Facts.FactsApplied(ctx, deps.Session.ID, ids)
+	}
+	deps.Session.AddHistoryEntry(agent.HistoryEntry{
+		Type:  "facts_applied",
+		Input: strings.Join(ids, ","),
+		Query: deps.Query,
+	})
+	if deps.EventEmitter != nil {
+		deps.EventEmitter.Emit(events.TypeFactsApplied, &events.FactsAppliedData{
+			FactIDs:    ids,
+			Statements: statements,
+			Query:      deps.Query,
+		})
+	}
+}
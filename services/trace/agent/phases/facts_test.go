@@ -0,0 +1,130 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
+)
+
+// recordingFacts is a FactRecorder that keeps what it is given.
+type recordingFacts struct {
+	proposed []facts.Candidate
+	applied  [][]string
+}
+
+func (r *recordingFacts) ProposeFact(_ context.Context, c facts.Candidate) (*facts.Fact, error) {
+	r.proposed = append(r.proposed, c)
+	return &facts.Fact{ID: facts.IDFor(c.Statement), Statement: c.Statement, Kind: c.Kind}, nil
+}
+
+func (r *recordingFacts) FactsApplied(_ context.Context, _ string, ids []string) {
+	r.applied = append(r.applied, ids)
+}
+
+func TestPlanPhase_InjectsKnownFacts(t *testing.T) {
+	deps := createTestDependencies()
+	recorder := &recordingFacts{}
+	deps.Facts = recorder
+	deps.KnownFacts = []facts.Fact{
+		{ID: "f1", Statement: "Retry logic lives in pkg/transport", Anchors: []facts.Anchor{{Name: "transport", FilePath: "pkg/transport/retry.go"}}},
+		{ID: "f2", Statement: "Config is loaded once"},
+	}
+	var got []*events.Event
+	deps.EventEmitter.Subscribe(func(e *events.Event) {
+		got = append(got, e)
+	}, events.TypeFactsApplied)
+
+	if _, err := NewPlanPhase().Execute(context.Background(), deps); err != nil {
+		t.Fatal(err)
+	}
+	prompt := deps.Context.SystemPrompt
+	if !strings.Contains(prompt, "## Known facts about this project\n") ||
+		!strings.Contains(prompt, "- Retry logic lives in pkg/transport (about transport in pkg/transport/retry.go)\n- Config is loaded once\n") {
+		t.Errorf("system prompt lacks the known facts: %q", prompt)
+	}
+	if want := [][]string{{"f1", "f2"}}; !reflect.DeepEqual(recorder.applied, want) {
+		t.Errorf("FactsApplied = %v, want %v", recorder.applied, want)
+	}
+	if len(got) != 1 {
+		t.Fatalf("facts_applied events = %d, want 1", len(got))
+	}
+	data := got[0].Data.(*events.FactsAppliedData)
+	if !reflect.DeepEqual(data.FactIDs, []string{"f1", "f2"}) || data.Query != deps.Query {
+		t.Errorf("event data = %+v", data)
+	}
+
+	deps = createTestDependencies()
+	if _, err := NewPlanPhase().Execute(context.Background(), deps); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(deps.Context.SystemPrompt, "Known facts") {
+		t.Errorf("known facts added without facts: %q", deps.Context.SystemPrompt)
+	}
+}
+
+func TestClarifyPhase_ProposesTypedFacts(t *testing.T) {
+	deps, _ := clarifyDeps()
+	recorder := &recordingFacts{}
+	deps.Facts = recorder
+	phase := NewClarifyPhase()
+
+	phase.SetClarificationInput("No, the retry logic lives in pkg/transport not pkg/client")
+	if _, err := phase.Execute(context.Background(), deps); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.proposed) != 1 {
+		t.Fatalf("proposed %d facts, want 1", len(recorder.proposed))
+	}
+	c := recorder.proposed[0]
+	if c.Kind != facts.KindCorrection || c.ConfirmedBy != facts.ConfirmedByClarification || c.SessionID != deps.Session.ID {
+		t.Errorf("candidate = %+v", c)
+	}
+
+	// A selected option was worded by the agent, not stated by the user.
+	deps.Session.SetClarificationResponse(agent.ClarificationResponse{Text: "yes, Handler in pkg/auth/handler.go", OptionID: "opt_1"})
+	if _, err := phase.Execute(context.Background(), deps); err != nil {
+		t.Fatal(err)
+	}
+	if len(recorder.proposed) != 1 {
+		t.Errorf("proposed %d facts after an option, want 1", len(recorder.proposed))
+	}
+}
+
+func TestReflectPhase_ProposesFollowUpFactsOnce(t *testing.T) {
+	deps := createTestDependencies()
+	recorder := &recordingFacts{}
+	deps.Facts = recorder
+	deps.Session.AddHistoryEntry(agent.HistoryEntry{Type: "follow_up", Input: "what else calls Charge?"})
+	deps.Session.AddHistoryEntry(agent.HistoryEntry{Type: "follow_up", Input: "actually `Charge` is only called by PlaceOrder"})
+
+	phase := NewReflectPhase()
+	phase.proposeFollowUpFacts(context.Background(), deps)
+	phase.proposeFollowUpFacts(context.Background(), deps)
+	if len(recorder.proposed) != 1 || recorder.proposed[0].ConfirmedBy != facts.ConfirmedByFollowUp {
+		t.Errorf("proposed = %+v, want the correction once", recorder.proposed)
+	}
+	var recorded int
+	for _, e := range deps.Session.GetHistory() {
+		if e.Type == "fact_recorded" {
+			recorded++
+		}
+	}
+	if recorded != 1 {
+		t.Errorf("fact_recorded history entries = %d, want 1", recorded)
+	}
+}
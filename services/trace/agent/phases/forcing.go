@@ -14,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/classifier"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -222,7 +223,7 @@ func (p *DefaultForcingPolicy) ShouldForce(ctx context.Context, req *ForcingRequ
 			attribute.Bool("should_force", false),
 			attribute.String("reason", "step_threshold_exceeded"),
 		)
-		slog.Debug("Tool forcing skipped - step threshold exceeded",
+		slog.DebugContext(ctx, "Tool forcing skipped - step threshold exceeded",
 			slog.Int("step", req.StepNumber),
 			slog.Int("max_step", req.MaxStepForForcing),
 		)
@@ -235,7 +236,7 @@ func (p *DefaultForcingPolicy) ShouldForce(ctx context.Context, req *ForcingRequ
 			attribute.Bool("should_force", false),
 			attribute.String("reason", "circuit_breaker"),
 		)
-		slog.Debug("Tool forcing skipped - circuit breaker triggered",
+		slog.DebugContext(ctx, "Tool forcing skipped - circuit breaker triggered",
 			slog.Int("retries", req.ForcingRetries),
 			slog.Int("max_retries", req.MaxRetries),
 		)
@@ -256,8 +257,8 @@ func (p *DefaultForcingPolicy) ShouldForce(ctx context.Context, req *ForcingRequ
 	)
 
 	if isAnalytical {
-		slog.Info("Tool forcing triggered for analytical query",
-			slog.String("query", req.Query),
+		slog.InfoContext(ctx, "Tool forcing triggered for analytical query",
+			telemetry.SensitiveLog("query", req.Query),
 			slog.Int("step", req.StepNumber),
 			slog.Int("retry", req.ForcingRetries),
 		)
@@ -0,0 +1,57 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// appendGraphQualityPrompt tells the model how far the code graph can be
+// trusted when its quality level is not high. No-op otherwise.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	request - The LLM request. Modified in place.
+func appendGraphQualityPrompt(deps *Dependencies, request *llm.Request) {
+	if deps == nil || request == nil {
+		return
+	}
+	if block := graphQualityPromptBlock(deps.GraphQuality); block != "" {
+		request.SystemPrompt += block
+	}
+}
+
+// graphQualityPromptBlock returns the caveat for a report, or "" when the
+// report is nil or its level is high.
+func graphQualityPromptBlock(report *graph.QualityReport) string {
+	if report == nil || report.Level == graph.QualityHigh || report.Level == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\n## Graph reliability\n")
+	fmt.Fprintf(&b, "The code graph behind your tools has %s confidence (%.2f). ", report.Level, report.Confidence)
+	fmt.Fprintf(&b, "%.0f%% of calls between project symbols were resolved", report.Calls.ResolutionRate*100)
+	if report.ParseErrors.Count > 0 {
+		fmt.Fprintf(&b, " and %d of %d files had parse errors", report.ParseErrors.Count, report.ParseErrors.TotalFiles)
+	}
+	b.WriteString(". Callers, callees, implementations and references found through the graph may be incomplete. ")
+	if report.Level == graph.QualityLow {
+		b.WriteString("Tell the user that the answer is based on an incomplete graph, and read the source before stating that something has no callers or is unused.\n")
+	} else {
+		b.WriteString("Read the source before stating that something has no callers or is unused.\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+func TestAppendGraphQualityPrompt(t *testing.T) {
+	report := func(level graph.QualityLevel, confidence float64) *graph.QualityReport {
+		return &graph.QualityReport{
+			Level:       level,
+			Confidence:  confidence,
+			Calls:       graph.CallResolution{ResolutionRate: 0.42},
+			ParseErrors: graph.ParseErrorSummary{Count: 3, TotalFiles: 120},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		report  *graph.QualityReport
+		want    []string
+		notWant []string
+	}{
+		{name: "no report"},
+		{name: "high", report: report(graph.QualityHigh, 0.93)},
+		{
+			name:    "medium",
+			report:  report(graph.QualityMedium, 0.7),
+			want:    []string{"## Graph reliability", "medium confidence (0.70)", "42% of calls", "3 of 120 files"},
+			notWant: []string{"Tell the user"},
+		},
+		{
+			name:   "low",
+			report: report(graph.QualityLow, 0.41),
+			want:   []string{"low confidence (0.41)", "Tell the user that the answer is based on an incomplete graph"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := &llm.Request{SystemPrompt: "base"}
+			appendGraphQualityPrompt(&Dependencies{GraphQuality: tt.report}, request)
+
+			if len(tt.want) == 0 && request.SystemPrompt != "base" {
+				t.Fatalf("SystemPrompt = %q, want it unchanged", request.SystemPrompt)
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(request.SystemPrompt, s) {
+					t.Errorf("SystemPrompt missing %q:\n%s", s, request.SystemPrompt)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(request.SystemPrompt, s) {
+					t.Errorf("SystemPrompt contains %q:\n%s", s, request.SystemPrompt)
+				}
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // InitPhase handles session initialization.
@@ -168,7 +169,7 @@ func (p *InitPhase) handleGraphInitError(deps *Dependencies, err error) (agent.A
 	// The agent can still function with limited capabilities
 	slog.Warn("Graph initialization failed, entering degraded mode",
 		slog.String("session_id", deps.Session.ID),
-		slog.String("error", err.Error()),
+		telemetry.SensitiveLogError("error", err),
 	)
 	p.emitError(deps, err, true)
 	p.emitStateTransition(deps, agent.StateInit, agent.StateDegraded, "graph initialization failed")
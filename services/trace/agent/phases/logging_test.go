@@ -0,0 +1,93 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	agentcontext "github.com/AleutianAI/AleutianFOSS/services/trace/agent/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// TestExecutePhase_LogsCarryContextFields verifies that log records emitted
+// inside the execute phase carry the request's trace_id and the session_id
+// from the context, without the call sites passing them.
+func TestExecutePhase_LogsCarryContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(telemetry.NewContextHandler(
+		slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	traceID, _ := oteltrace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := oteltrace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := oteltrace.ContextWithSpanContext(context.Background(), oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: oteltrace.FlagsSampled,
+	}))
+
+	phase := NewExecutePhase()
+	deps := createTestDependencies()
+	deps.Context = &agent.AssembledContext{ConversationHistory: []agent.Message{}}
+	deps.ToolRegistry = tools.NewRegistry()
+	deps.ToolExecutor = tools.NewExecutor(deps.ToolRegistry, nil)
+	deps.ContextManager = &agentcontext.Manager{}
+	mockLLM := llm.NewMockClient()
+	mockLLM.QueueFinalResponse("Here is the answer to your question.")
+	deps.LLMClient = mockLLM
+
+	// The agent loop adds the session ID to the context of every phase.
+	ctx = agent.ContextWithSessionID(ctx, deps.Session.ID)
+	if _, err := phase.Execute(ctx, deps); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+
+	records := make(map[string]map[string]any)
+	scanner := bufio.NewScanner(&buf)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", scanner.Text(), err)
+		}
+		msg, _ := rec["msg"].(string)
+		records[msg] = rec
+	}
+
+	// The phase entry point, and records from callLLM and handleCompletion.
+	for _, msg := range []string{"ExecutePhase starting", "Sending LLM request", "Handling completion"} {
+		rec, ok := records[msg]
+		if !ok {
+			t.Fatalf("no %q record; got %d records", msg, len(records))
+		}
+		if rec["trace_id"] != traceID.String() {
+			t.Errorf("%q trace_id = %v, want %s", msg, rec["trace_id"], traceID)
+		}
+		if rec["span_id"] != spanID.String() {
+			t.Errorf("%q span_id = %v, want %s", msg, rec["span_id"], spanID)
+		}
+		if rec["session_id"] != deps.Session.ID {
+			t.Errorf("%q session_id = %v, want %s", msg, rec["session_id"], deps.Session.ID)
+		}
+	}
+}
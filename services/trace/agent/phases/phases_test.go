@@ -453,6 +453,36 @@ func TestReflectPhase_LooksStuck(t *testing.T) {
 	}
 }
 
+func TestReflectPhase_WantsNextPage(t *testing.T) {
+	page := func(cursor string) agent.ToolResult {
+		return agent.ToolResult{Success: true, Tool: "find_callers", NextCursor: cursor}
+	}
+
+	tests := []struct {
+		name     string
+		opts     []ReflectPhaseOption
+		results  []agent.ToolResult
+		expected bool
+	}{
+		{"no cursor", nil, []agent.ToolResult{page("")}, false},
+		{"first page", nil, []agent.ToolResult{page("c1")}, true},
+		{"after other tool", nil, []agent.ToolResult{{Success: true, Tool: "find_symbol"}, page("c1"), page("c2")}, true},
+		{"page limit reached", nil, []agent.ToolResult{page("c1"), page("c2"), page("c3")}, false},
+		{"disabled", []ReflectPhaseOption{WithMaxResultPages(0)}, []agent.ToolResult{page("c1")}, false},
+		{"failed call", nil, []agent.ToolResult{{Tool: "find_callers", NextCursor: "c1"}}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phase := NewReflectPhase(tt.opts...)
+			input := &ReflectionInput{RecentResults: tt.results}
+			if got := phase.wantsNextPage(input); got != tt.expected {
+				t.Errorf("wantsNextPage() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestReflectPhase_LooksComplete_NotFoundAnswer(t *testing.T) {
 	phase := NewReflectPhase()
 
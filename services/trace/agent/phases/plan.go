@@ -18,6 +18,9 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // PlanPhase handles context assembly and execution preparation.
@@ -102,13 +105,13 @@ func (p *PlanPhase) Name() string {
 // Thread Safety: This method is safe for concurrent use.
 func (p *PlanPhase) Execute(ctx context.Context, deps *Dependencies) (agent.AgentState, error) {
 	if err := p.validateDependencies(deps); err != nil {
-		slog.Error("PlanPhase validation failed", slog.String("error", err.Error()))
+		slog.ErrorContext(ctx, "PlanPhase validation failed", telemetry.SensitiveLogError("error", err))
 		return agent.StateError, err
 	}
 
-	slog.Info("PlanPhase starting",
+	slog.InfoContext(ctx, "PlanPhase starting",
 		slog.String("session_id", deps.Session.ID),
-		slog.String("query", deps.Query),
+		telemetry.SensitiveLog("query", deps.Query),
 	)
 
 	// Clear tool errors from previous queries so the router starts fresh.
@@ -117,7 +120,7 @@ func (p *PlanPhase) Execute(ctx context.Context, deps *Dependencies) (agent.Agen
 
 	// Check if query needs clarification
 	if p.isQueryAmbiguous(deps.Query) {
-		slog.Info("Query is ambiguous, requesting clarification",
+		slog.InfoContext(ctx, "Query is ambiguous, requesting clarification",
 			slog.String("session_id", deps.Session.ID),
 		)
 		return p.handleAmbiguousQuery(deps)
@@ -125,25 +128,28 @@ func (p *PlanPhase) Execute(ctx context.Context, deps *Dependencies) (agent.Agen
 
 	// If ContextManager is available, assemble initial context
 	if deps.ContextManager != nil {
-		slog.Info("Assembling context with ContextManager",
+		slog.InfoContext(ctx, "Assembling context with ContextManager",
 			slog.String("session_id", deps.Session.ID),
 		)
 		assembledContext, err := p.assembleContext(ctx, deps)
 		if err != nil {
-			slog.Error("Context assembly failed",
+			slog.ErrorContext(ctx, "Context assembly failed",
 				slog.String("session_id", deps.Session.ID),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return p.handleAssemblyError(deps, err)
 		}
 
+		appendProjectSummary(deps, assembledContext)
+		appendKnownFacts(ctx, deps, assembledContext)
+
 		// Store context in dependencies for execute phase
 		deps.Context = assembledContext
 
 		// Persist context to session for cross-phase access
 		deps.Session.SetCurrentContext(assembledContext)
 
-		slog.Info("Context assembled successfully",
+		slog.InfoContext(ctx, "Context assembled successfully",
 			slog.String("session_id", deps.Session.ID),
 			slog.Int("total_tokens", assembledContext.TotalTokens),
 			slog.Int("code_entries", len(assembledContext.CodeContext)),
@@ -153,7 +159,7 @@ func (p *PlanPhase) Execute(ctx context.Context, deps *Dependencies) (agent.Agen
 		p.emitContextUpdate(deps, assembledContext)
 	} else {
 		// Create minimal context without ContextManager (degraded mode)
-		slog.Info("Creating minimal context (degraded mode)",
+		slog.InfoContext(ctx, "Creating minimal context (degraded mode)",
 			slog.String("session_id", deps.Session.ID),
 		)
 		assembledContext := &agent.AssembledContext{
@@ -172,12 +178,14 @@ func (p *PlanPhase) Execute(ctx context.Context, deps *Dependencies) (agent.Agen
 				},
 			},
 		}
+		appendProjectSummary(deps, assembledContext)
+		appendKnownFacts(ctx, deps, assembledContext)
 		deps.Context = assembledContext
 
 		// Persist context to session for cross-phase access
 		deps.Session.SetCurrentContext(assembledContext)
 
-		slog.Info("Minimal context created and stored in session",
+		slog.InfoContext(ctx, "Minimal context created and stored in session",
 			slog.String("session_id", deps.Session.ID),
 			slog.Int("history_len", len(assembledContext.ConversationHistory)),
 		)
@@ -186,7 +194,7 @@ func (p *PlanPhase) Execute(ctx context.Context, deps *Dependencies) (agent.Agen
 	// Emit state transition
 	p.emitStateTransition(deps, agent.StatePlan, agent.StateExecute, "context ready")
 
-	slog.Info("PlanPhase completed, transitioning to Execute",
+	slog.InfoContext(ctx, "PlanPhase completed, transitioning to Execute",
 		slog.String("session_id", deps.Session.ID),
 	)
 
@@ -275,6 +283,12 @@ func (p *PlanPhase) handleAmbiguousQuery(deps *Dependencies) (agent.AgentState,
 
 // assembleContext creates the initial context for the query.
 //
+// Description:
+//
+//	When the session reviews a diff, the context leads with the symbols
+//	the diff changes and their direct callers and callees, which are
+//	recorded on the session for the run response.
+//
 // Inputs:
 //
 //	ctx - Context for cancellation.
@@ -285,7 +299,13 @@ func (p *PlanPhase) handleAmbiguousQuery(deps *Dependencies) (agent.AgentState,
 //	*agent.AssembledContext - The assembled context.
 //	error - Non-nil if assembly fails.
 func (p *PlanPhase) assembleContext(ctx context.Context, deps *Dependencies) (*agent.AssembledContext, error) {
-	assembled, err := deps.ContextManager.Assemble(ctx, deps.Query, p.initialBudget)
+	var assembled *agent.AssembledContext
+	var err error
+	if diffText := deps.Session.GetDiff(); diffText != "" {
+		assembled, err = p.assembleDiffContext(ctx, deps, diffText)
+	} else {
+		assembled, err = deps.ContextManager.AssembleWithBudget(ctx, deps.Query, p.contextBudget(deps))
+	}
 	if err != nil {
 		return nil, fmt.Errorf("assemble context: %w", err)
 	}
@@ -300,6 +320,57 @@ func (p *PlanPhase) assembleContext(ctx context.Context, deps *Dependencies) (*a
 	return assembled, nil
 }
 
+// assembleDiffContext assembles context seeded with the symbols a diff
+// changes and records them on the session.
+func (p *PlanPhase) assembleDiffContext(ctx context.Context, deps *Dependencies, diffText string) (*agent.AssembledContext, error) {
+	files, err := diff.ChangedLines(diffText)
+	if err != nil {
+		return nil, fmt.Errorf("parse diff: %w", err)
+	}
+	assembled, symbols, err := deps.ContextManager.AssembleWithDiff(ctx, deps.Query, p.contextBudget(deps), files)
+	if err != nil {
+		return nil, err
+	}
+	deps.Session.SetDiffSymbols(symbols)
+
+	slog.InfoContext(ctx, "Context seeded from diff",
+		slog.String("session_id", deps.Session.ID),
+		slog.Int("files", len(files)),
+		slog.Int("diff_symbols", len(symbols)),
+	)
+	return assembled, nil
+}
+
+// contextBudget builds the assembly budget for this session.
+//
+// Description:
+//
+//	Starts from the phase's initial budget and applies the session's
+//	context settings (total tokens, per-symbol cap, file cap, ranking),
+//	which already combine server defaults with per-request overrides.
+//
+// Inputs:
+//
+//	deps - Phase dependencies. Session may be nil.
+//
+// Outputs:
+//
+//	cbcontext.ContextBudget - The budget to assemble under.
+func (p *PlanPhase) contextBudget(deps *Dependencies) cbcontext.ContextBudget {
+	budget := cbcontext.ContextBudget{MaxTotalTokens: p.initialBudget}
+	if deps.Session == nil || deps.Session.Config == nil {
+		return budget
+	}
+
+	cfg := deps.Session.Config
+	return budget.Merge(cbcontext.ContextBudget{
+		MaxTotalTokens:  cfg.InitialContextBudget,
+		MaxSymbolTokens: cfg.ContextMaxSymbolTokens,
+		MaxFiles:        cfg.ContextMaxFiles,
+		Ranking:         cfg.ContextRanking,
+	})
+}
+
 // handleAssemblyError handles context assembly errors.
 //
 // Inputs:
@@ -334,6 +405,7 @@ func (p *PlanPhase) emitContextUpdate(deps *Dependencies, assembled *agent.Assem
 		EntriesAffected: len(assembled.CodeContext),
 		TokensBefore:    0,
 		TokensAfter:     assembled.TotalTokens,
+		Assembly:        assembled.Assembly,
 	})
 }
 
@@ -0,0 +1,30 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+)
+
+// appendProjectSummary adds the project overview to the system prompt of
+// the initial context, so the model knows the project's layout before its
+// first tool call. No-op without a summary.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	assembled - The initial context. Modified in place.
+func appendProjectSummary(deps *Dependencies, assembled *agent.AssembledContext) {
+	if deps == nil || assembled == nil || deps.ProjectSummary == "" {
+		return
+	}
+	assembled.SystemPrompt += "\n\n## Project overview\n" + deps.ProjectSummary + "\n"
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+)
+
+func TestPlanPhase_InjectsProjectSummary(t *testing.T) {
+	deps := createTestDependencies()
+	deps.ProjectSummary = "12 files, 80 symbols.\nFrameworks: gin"
+
+	state, err := NewPlanPhase().Execute(context.Background(), deps)
+	if err != nil || state != agent.StateExecute {
+		t.Fatalf("plan = %s, %v; want EXECUTE", state, err)
+	}
+	prompt := deps.Context.SystemPrompt
+	if !strings.Contains(prompt, "## Project overview\n12 files, 80 symbols.\nFrameworks: gin") {
+		t.Errorf("system prompt lacks the project overview: %q", prompt)
+	}
+
+	deps = createTestDependencies()
+	if _, err := NewPlanPhase().Execute(context.Background(), deps); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(deps.Context.SystemPrompt, "Project overview") {
+		t.Errorf("overview added without a summary: %q", deps.Context.SystemPrompt)
+	}
+}
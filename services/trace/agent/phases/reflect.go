@@ -20,6 +20,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/grounding"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // ReflectPhase handles progress evaluation and decision making.
@@ -36,6 +37,13 @@ type ReflectPhase struct {
 
 	// maxTokens is the maximum tokens before forcing completion.
 	maxTokens int
+
+	// routingFeedback receives routed-tool outcomes. Nil disables feedback.
+	routingFeedback RoutingFeedbackRecorder
+
+	// maxResultPages is how many pages of one paged tool result to fetch
+	// before reflecting normally. Zero disables page following.
+	maxResultPages int
 }
 
 // ReflectPhaseOption configures a ReflectPhase.
@@ -71,6 +79,37 @@ func WithMaxTotalTokens(tokens int) ReflectPhaseOption {
 	}
 }
 
+// WithRoutingFeedback sets the recorder for routed-tool outcomes.
+//
+// Inputs:
+//
+//	rec - The recorder, typically the execute phase's *routing.PreFilter.
+//
+// Outputs:
+//
+//	ReflectPhaseOption - The configuration function.
+func WithRoutingFeedback(rec RoutingFeedbackRecorder) ReflectPhaseOption {
+	return func(p *ReflectPhase) {
+		p.routingFeedback = rec
+	}
+}
+
+// WithMaxResultPages sets how many pages of a paged tool result (one with
+// a next_cursor) Reflect continues for before judging completion.
+//
+// Inputs:
+//
+//	pages - Maximum pages per paged call. Zero disables page following.
+//
+// Outputs:
+//
+//	ReflectPhaseOption - The configuration function.
+func WithMaxResultPages(pages int) ReflectPhaseOption {
+	return func(p *ReflectPhase) {
+		p.maxResultPages = pages
+	}
+}
+
 // NewReflectPhase creates a new reflection phase.
 //
 // Inputs:
@@ -82,8 +121,9 @@ func WithMaxTotalTokens(tokens int) ReflectPhaseOption {
 //	*ReflectPhase - The configured phase.
 func NewReflectPhase(opts ...ReflectPhaseOption) *ReflectPhase {
 	p := &ReflectPhase{
-		maxSteps:  50,
-		maxTokens: 100000,
+		maxSteps:       50,
+		maxTokens:      100000,
+		maxResultPages: 3,
 	}
 
 	for _, opt := range opts {
@@ -127,22 +167,44 @@ func (p *ReflectPhase) Execute(ctx context.Context, deps *Dependencies) (agent.A
 		return agent.StateError, err
 	}
 
+	// An LLM call overflowed the context window: narrow it and retry
+	if overflow := deps.Session.TakeContextOverflow(); overflow != nil {
+		return p.narrowAfterOverflow(deps, *overflow)
+	}
+
+	// Keep what the user confirmed or corrected in a follow-up
+	p.proposeFollowUpFacts(ctx, deps)
+
 	// Gather reflection input
 	input := p.gatherReflectionInput(deps)
 
+	// An ensemble run hands its final answer over for reconciliation
+	if idx, ok := p.pendingEnsembleAnswer(deps); ok {
+		return p.reconcileFinalAnswer(ctx, deps, input, idx)
+	}
+
 	// Check hard limits first
 	if p.exceedsLimits(input) {
-		return p.handleLimitExceeded(deps, input)
+		p.recordRoutingFeedback(ctx, deps, DecisionComplete)
+		return p.handleLimitExceeded(ctx, deps, input)
+	}
+
+	// A tool call was rejected for its arguments: have them re-extracted
+	if rejected, ok := rejectedArguments(deps.Context); ok {
+		return p.reextractArguments(deps, input, rejected)
 	}
 
 	// Perform reflection analysis
 	output := p.analyzeProgress(deps, input)
 
+	// Feed the routed tool's outcome back to the router
+	p.recordRoutingFeedback(ctx, deps, output.Decision)
+
 	// Emit reflection event
 	p.emitReflection(deps, input, output)
 
 	// Handle the decision
-	return p.handleDecision(deps, output)
+	return p.handleDecision(ctx, deps, output)
 }
 
 // validateDependencies checks that required dependencies are present.
@@ -237,6 +299,7 @@ func (p *ReflectPhase) exceedsLimits(input *ReflectionInput) bool {
 //
 // Inputs:
 //
+//	ctx - Context for the synthesis call; cancelled when the run is aborted.
 //	deps - Phase dependencies.
 //	input - The reflection input.
 //
@@ -244,7 +307,7 @@ func (p *ReflectPhase) exceedsLimits(input *ReflectionInput) bool {
 //
 //	agent.AgentState - COMPLETE.
 //	error - Always nil.
-func (p *ReflectPhase) handleLimitExceeded(deps *Dependencies, input *ReflectionInput) (agent.AgentState, error) {
+func (p *ReflectPhase) handleLimitExceeded(ctx context.Context, deps *Dependencies, input *ReflectionInput) (agent.AgentState, error) {
 	var reason string
 	if input.StepsCompleted >= p.maxSteps {
 		reason = "maximum steps reached"
@@ -253,7 +316,7 @@ func (p *ReflectPhase) handleLimitExceeded(deps *Dependencies, input *Reflection
 	}
 
 	// Synthesize a final response before completing
-	p.synthesizeResponse(context.Background(), deps, reason)
+	p.synthesizeResponse(ctx, deps, reason)
 
 	p.emitReflection(deps, input, &ReflectionOutput{
 		Decision: DecisionComplete,
@@ -295,6 +358,15 @@ func (p *ReflectPhase) handleLimitExceeded(deps *Dependencies, input *Reflection
 //	  - The agent cannot make progress despite trying
 //	  - There's a genuine blocker (missing permissions, invalid paths, etc.)
 func (p *ReflectPhase) analyzeProgress(deps *Dependencies, input *ReflectionInput) *ReflectionOutput {
+	// A paged tool result with more pages: continue so Execute can repeat
+	// the call with its cursor, up to maxResultPages pages.
+	if p.wantsNextPage(input) {
+		return &ReflectionOutput{
+			Decision: DecisionContinue,
+			Reason:   "paged result has more pages",
+		}
+	}
+
 	// Check if recent results indicate completion
 	if p.looksComplete(input) {
 		return &ReflectionOutput{
@@ -322,6 +394,32 @@ func (p *ReflectPhase) analyzeProgress(deps *Dependencies, input *ReflectionInpu
 	}
 }
 
+// wantsNextPage reports whether the last tool result has a next page that
+// should be fetched.
+//
+// Inputs:
+//
+//	input - The reflection input.
+//
+// Outputs:
+//
+//	bool - True if the last result has a NextCursor and fewer than
+//	  maxResultPages pages of its tool were fetched in a row.
+func (p *ReflectPhase) wantsNextPage(input *ReflectionInput) bool {
+	if p.maxResultPages <= 0 || len(input.RecentResults) == 0 {
+		return false
+	}
+	last := input.RecentResults[len(input.RecentResults)-1]
+	if !last.Success || last.NextCursor == "" {
+		return false
+	}
+	pages := 0
+	for i := len(input.RecentResults) - 1; i >= 0 && input.RecentResults[i].Tool == last.Tool; i-- {
+		pages++
+	}
+	return pages < p.maxResultPages
+}
+
 // looksComplete determines if the task appears complete.
 //
 // Inputs:
@@ -502,6 +600,7 @@ func (p *ReflectPhase) looksStuck(input *ReflectionInput) bool {
 //
 // Inputs:
 //
+//	ctx - Context for the synthesis call; cancelled when the run is aborted.
 //	deps - Phase dependencies.
 //	output - The reflection decision.
 //
@@ -509,7 +608,7 @@ func (p *ReflectPhase) looksStuck(input *ReflectionInput) bool {
 //
 //	agent.AgentState - The next state.
 //	error - Always nil.
-func (p *ReflectPhase) handleDecision(deps *Dependencies, output *ReflectionOutput) (agent.AgentState, error) {
+func (p *ReflectPhase) handleDecision(ctx context.Context, deps *Dependencies, output *ReflectionOutput) (agent.AgentState, error) {
 	var nextState agent.AgentState
 
 	switch output.Decision {
@@ -517,7 +616,7 @@ func (p *ReflectPhase) handleDecision(deps *Dependencies, output *ReflectionOutp
 		nextState = agent.StateExecute
 	case DecisionComplete:
 		// Synthesize a final response before completing
-		p.synthesizeResponse(context.Background(), deps, output.Reason)
+		p.synthesizeResponse(ctx, deps, output.Reason)
 		nextState = agent.StateComplete
 	case DecisionClarify:
 		nextState = agent.StateClarify
@@ -602,7 +701,7 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 					continue
 				}
 				// Already have a real response, no need to synthesize
-				slog.Debug("Skipping synthesis - response already exists",
+				slog.DebugContext(ctx, "Skipping synthesis - response already exists",
 					slog.String("session_id", deps.Session.ID),
 				)
 				return
@@ -616,14 +715,14 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 
 	// No LLM client available, use fallback
 	if deps.LLMClient == nil {
-		slog.Warn("Cannot synthesize response - no LLM client, using fallback",
+		slog.WarnContext(ctx, "Cannot synthesize response - no LLM client, using fallback",
 			slog.String("session_id", deps.Session.ID),
 		)
 		p.storeFallbackResponse(deps)
 		return
 	}
 
-	slog.Info("Synthesizing final response",
+	slog.InfoContext(ctx, "Synthesizing final response",
 		slog.String("session_id", deps.Session.ID),
 		slog.String("reason", reason),
 	)
@@ -632,17 +731,7 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 	synthesisCtx := p.prepareSynthesisContext(deps)
 
 	// Build anchored synthesis prompt if builder available
-	anchoredBuilder := p.getAnchoredSynthesisBuilder(deps)
-	userQuestion := grounding.ExtractUserQuestion(synthesisCtx)
-	projectLang := p.detectProjectLanguage(synthesisCtx)
-
-	var baseSynthesisPrompt string
-	if anchoredBuilder != nil {
-		baseSynthesisPrompt = anchoredBuilder.BuildAnchoredSynthesisPrompt(ctx, synthesisCtx, userQuestion, projectLang)
-	} else {
-		// Fallback to basic prompt
-		baseSynthesisPrompt = "Based on the tools you used and information you gathered, please provide a concise summary answering the user's original question. Focus on the key findings and insights."
-	}
+	baseSynthesisPrompt := p.synthesisPrompt(ctx, deps, synthesisCtx)
 
 	// Get post-synthesis verifier if available
 	postSynthesisVerifier := p.getPostSynthesisVerifier(deps)
@@ -670,17 +759,19 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 
 		// Build and send LLM request (no tools - just get a text response)
 		request := llm.BuildRequest(attemptCtx, nil, 4096)
+		appendCitationPrompt(deps, request)
+		appendGraphQualityPrompt(deps, request)
 
 		// CB-62: Apply per-session main model override.
 		if deps.Session != nil && deps.Session.Config.MainModel != "" {
 			request.ModelOverride = deps.Session.Config.MainModel
 		}
 
-		response, err := deps.LLMClient.Complete(ctx, request)
+		response, err := p.completeSynthesis(ctx, deps, request)
 		if err != nil {
-			slog.Error("Failed to synthesize response",
+			slog.ErrorContext(ctx, "Failed to synthesize response",
 				slog.String("session_id", deps.Session.ID),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.Int("retry_count", retryCount),
 			)
 			if retryCount == maxRetries-1 {
@@ -692,7 +783,7 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 
 		// Check for empty response (context overflow or model issue)
 		if response.Content == "" {
-			slog.Warn("Synthesis returned empty content",
+			slog.WarnContext(ctx, "Synthesis returned empty content",
 				slog.String("session_id", deps.Session.ID),
 				slog.Int("output_tokens", response.OutputTokens),
 				slog.String("stop_reason", response.StopReason),
@@ -711,13 +802,13 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 		if postSynthesisVerifier != nil {
 			verifyResult, verifyErr := postSynthesisVerifier.VerifyPostSynthesis(ctx, response.Content, deps.Context, retryCount)
 			if verifyErr != nil {
-				slog.Warn("Post-synthesis verification error",
+				slog.WarnContext(ctx, "Post-synthesis verification error",
 					slog.String("session_id", deps.Session.ID),
-					slog.String("error", verifyErr.Error()),
+					telemetry.SensitiveLogError("error", verifyErr),
 				)
 				// Treat verification errors as pass (non-fatal)
 			} else if !verifyResult.Passed {
-				slog.Warn("Post-synthesis verification failed",
+				slog.WarnContext(ctx, "Post-synthesis verification failed",
 					slog.String("session_id", deps.Session.ID),
 					slog.Int("violations", len(verifyResult.Violations)),
 					slog.Int("retry_count", retryCount),
@@ -727,7 +818,7 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 
 				// Check if we've exhausted retries
 				if verifyResult.NeedsFeedbackLoop {
-					slog.Info("Post-synthesis feedback loop triggered",
+					slog.InfoContext(ctx, "Post-synthesis feedback loop triggered",
 						slog.String("session_id", deps.Session.ID),
 						slog.Int("questions", len(verifyResult.FeedbackQuestions)),
 					)
@@ -740,7 +831,7 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 				// Retry with stricter prompt
 				continue
 			} else {
-				slog.Debug("Post-synthesis verification passed",
+				slog.DebugContext(ctx, "Post-synthesis verification passed",
 					slog.String("session_id", deps.Session.ID),
 					slog.Int("retry_count", retryCount),
 				)
@@ -760,7 +851,7 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 		// Persist to session
 		deps.Session.SetCurrentContext(deps.Context)
 
-		slog.Info("Synthesized response stored",
+		slog.InfoContext(ctx, "Synthesized response stored",
 			slog.String("session_id", deps.Session.ID),
 			slog.Int("response_len", len(lastResponse)),
 		)
@@ -769,6 +860,29 @@ func (p *ReflectPhase) synthesizeResponse(ctx context.Context, deps *Dependencie
 	}
 }
 
+// synthesisPrompt builds the prompt asking for a final answer.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies.
+//	synthesisCtx - The context the answer is synthesized from.
+//
+// Outputs:
+//
+//	string - The anchored synthesis prompt, or a basic one when no
+//	         builder is available.
+func (p *ReflectPhase) synthesisPrompt(ctx context.Context, deps *Dependencies, synthesisCtx *agent.AssembledContext) string {
+	anchoredBuilder := p.getAnchoredSynthesisBuilder(deps)
+	if anchoredBuilder == nil {
+		// Fallback to basic prompt
+		return "Based on the tools you used and information you gathered, please provide a concise summary answering the user's original question. Focus on the key findings and insights."
+	}
+	userQuestion := grounding.ExtractUserQuestion(synthesisCtx)
+	projectLang := p.detectProjectLanguage(synthesisCtx)
+	return anchoredBuilder.BuildAnchoredSynthesisPrompt(ctx, synthesisCtx, userQuestion, projectLang)
+}
+
 // getPostSynthesisVerifier extracts the post-synthesis verifier from deps if available.
 //
 // Inputs:
@@ -928,16 +1042,31 @@ func (p *ReflectPhase) detectProjectLanguage(ctx *agent.AssembledContext) string
 //
 //	*agent.AssembledContext - A reduced context suitable for synthesis.
 func (p *ReflectPhase) prepareSynthesisContext(deps *Dependencies) *agent.AssembledContext {
-	if deps.Context == nil {
+	return p.reduceSynthesisContext(deps, deps.Context)
+}
+
+// reduceSynthesisContext is prepareSynthesisContext for an arbitrary
+// source context.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	source - The context to reduce. Returned as-is when small enough.
+//
+// Outputs:
+//
+//	*agent.AssembledContext - A reduced context suitable for synthesis.
+func (p *ReflectPhase) reduceSynthesisContext(deps *Dependencies, source *agent.AssembledContext) *agent.AssembledContext {
+	if source == nil {
 		return &agent.AssembledContext{}
 	}
 
 	// Estimate current context size (rough: 4 chars per token)
-	totalSize := len(deps.Context.SystemPrompt)
-	for _, msg := range deps.Context.ConversationHistory {
+	totalSize := len(source.SystemPrompt)
+	for _, msg := range source.ConversationHistory {
 		totalSize += len(msg.Content)
 	}
-	for _, result := range deps.Context.ToolResults {
+	for _, result := range source.ToolResults {
 		totalSize += len(result.Output)
 	}
 
@@ -945,13 +1074,13 @@ func (p *ReflectPhase) prepareSynthesisContext(deps *Dependencies) *agent.Assemb
 		slog.String("session_id", deps.Session.ID),
 		slog.Int("total_chars", totalSize),
 		slog.Int("estimated_tokens", totalSize/4),
-		slog.Int("tool_results", len(deps.Context.ToolResults)),
+		slog.Int("tool_results", len(source.ToolResults)),
 	)
 
 	// If context is small enough (under ~20K tokens), use as-is
 	maxContextChars := 80000 // ~20K tokens at 4 chars/token
 	if totalSize <= maxContextChars {
-		return deps.Context
+		return source
 	}
 
 	slog.Info("Truncating context for synthesis",
@@ -962,41 +1091,41 @@ func (p *ReflectPhase) prepareSynthesisContext(deps *Dependencies) *agent.Assemb
 
 	// Create a reduced context
 	reduced := &agent.AssembledContext{
-		SystemPrompt: deps.Context.SystemPrompt,
-		TotalTokens:  deps.Context.TotalTokens,
+		SystemPrompt: source.SystemPrompt,
+		TotalTokens:  source.TotalTokens,
 	}
 
 	// Keep the user's original query (first user message) and last few messages
-	if len(deps.Context.ConversationHistory) > 0 {
-		reduced.ConversationHistory = append(reduced.ConversationHistory, deps.Context.ConversationHistory[0])
+	if len(source.ConversationHistory) > 0 {
+		reduced.ConversationHistory = append(reduced.ConversationHistory, source.ConversationHistory[0])
 
 		// Add a summary message if we're truncating
-		if len(deps.Context.ConversationHistory) > 5 {
+		if len(source.ConversationHistory) > 5 {
 			reduced.ConversationHistory = append(reduced.ConversationHistory, agent.Message{
 				Role:    "assistant",
-				Content: fmt.Sprintf("[%d intermediate messages and tool calls truncated for synthesis]", len(deps.Context.ConversationHistory)-5),
+				Content: fmt.Sprintf("[%d intermediate messages and tool calls truncated for synthesis]", len(source.ConversationHistory)-5),
 			})
 		}
 
 		// Keep the last 4 messages
-		start := len(deps.Context.ConversationHistory) - 4
+		start := len(source.ConversationHistory) - 4
 		if start < 1 {
 			start = 1
 		}
-		for i := start; i < len(deps.Context.ConversationHistory); i++ {
-			reduced.ConversationHistory = append(reduced.ConversationHistory, deps.Context.ConversationHistory[i])
+		for i := start; i < len(source.ConversationHistory); i++ {
+			reduced.ConversationHistory = append(reduced.ConversationHistory, source.ConversationHistory[i])
 		}
 	}
 
 	// Keep only the most recent tool results with truncated output
 	maxResultLen := 2000
 	maxResults := 5
-	start := len(deps.Context.ToolResults) - maxResults
+	start := len(source.ToolResults) - maxResults
 	if start < 0 {
 		start = 0
 	}
-	for i := start; i < len(deps.Context.ToolResults); i++ {
-		result := deps.Context.ToolResults[i]
+	for i := start; i < len(source.ToolResults); i++ {
+		result := source.ToolResults[i]
 		truncatedOutput := result.Output
 		if len(truncatedOutput) > maxResultLen {
 			truncatedOutput = truncatedOutput[:maxResultLen] + "\n... [output truncated]"
@@ -0,0 +1,122 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+const (
+	// maxArgumentRetries is how many tool calls in a row may be rejected
+	// for invalid arguments before Reflect stops asking for re-extraction
+	// and judges progress as usual.
+	maxArgumentRetries = 2
+
+	// argumentRetryHint asks the model to re-extract a rejected call's
+	// arguments.
+	argumentRetryHint = "[The %s call was rejected before it ran: %s. " +
+		"Re-extract its arguments from the question and the earlier tool results, " +
+		"correct %q, and call it again.]"
+)
+
+// hasRejectedArguments reports whether any result was rejected for
+// invalid arguments before its tool ran.
+func hasRejectedArguments(results []*tools.Result) bool {
+	for _, result := range results {
+		if result != nil && resultInvalidParameter(result) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// rejectedArguments returns the last tool result when its call was
+// rejected for invalid arguments and fewer than maxArgumentRetries calls
+// in a row were.
+//
+// Inputs:
+//
+//	c - The assembled context.
+//
+// Outputs:
+//
+//	agent.ToolResult - The rejected result.
+//	bool - False when the last call ran, or re-extraction was tried enough.
+func rejectedArguments(c *agent.AssembledContext) (agent.ToolResult, bool) {
+	results := c.ToolResults
+	if len(results) == 0 || results[len(results)-1].InvalidParameter == "" {
+		return agent.ToolResult{}, false
+	}
+	rejected := 0
+	for i := len(results) - 1; i >= 0 && results[i].InvalidParameter != ""; i-- {
+		rejected++
+	}
+	if rejected > maxArgumentRetries {
+		return agent.ToolResult{}, false
+	}
+	return results[len(results)-1], true
+}
+
+// reextractArguments asks the model to correct the arguments of a tool
+// call that was rejected before it ran, so Execute can retry it.
+//
+// Description:
+//
+//	The executor rejects arguments it cannot repair (a file path for a
+//	symbol ID, a path outside the project, a negative depth) with the
+//	offending parameter named. That is an extraction mistake, not a sign
+//	the task is stuck, so rather than count it toward CLARIFY the phase
+//	adds a hint naming the parameter and continues. Routing feedback is
+//	not recorded: the tool choice may well have been right.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	input - The reflection input.
+//	rejected - The rejected tool result.
+//
+// Outputs:
+//
+//	agent.AgentState - EXECUTE.
+//	error - Always nil.
+func (p *ReflectPhase) reextractArguments(deps *Dependencies, input *ReflectionInput, rejected agent.ToolResult) (agent.AgentState, error) {
+	tool := rejected.Tool
+	if tool == "" {
+		tool = "last tool"
+	}
+	deps.Context.ConversationHistory = append(deps.Context.ConversationHistory, agent.Message{
+		Role:    "user",
+		Content: fmt.Sprintf(argumentRetryHint, tool, rejected.Error, rejected.InvalidParameter),
+	})
+	deps.Session.SetCurrentContext(deps.Context)
+	deps.Session.AddHistoryEntry(agent.HistoryEntry{
+		Type:     "arguments_rejected",
+		ToolName: rejected.Tool,
+		Input:    rejected.Error,
+	})
+	slog.Info("Tool arguments rejected, asking for re-extraction",
+		slog.String("session_id", deps.Session.ID),
+		slog.String("tool", rejected.Tool),
+		slog.String("param", rejected.InvalidParameter),
+	)
+
+	output := &ReflectionOutput{
+		Decision: DecisionContinue,
+		Reason:   "tool arguments were invalid, re-extracting parameters",
+	}
+	p.emitReflection(deps, input, output)
+	p.emitStateTransition(deps, agent.StateReflect, agent.StateExecute, output.Reason)
+	return agent.StateExecute, nil
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+func TestReflect_RejectedArgumentsAreReextracted(t *testing.T) {
+	deps := createTestDependencies()
+	rejected := agent.ToolResult{
+		Tool:             "read_symbol_source",
+		Error:            "invalid arguments for read_symbol_source: symbol_id: got a file path where a symbol ID was expected",
+		InvalidParameter: "symbol_id",
+	}
+	deps.Context = &agent.AssembledContext{
+		ConversationHistory: []agent.Message{{Role: "user", Content: "Show ParseConfig"}},
+		ToolResults:         []agent.ToolResult{rejected},
+	}
+
+	reflect := NewReflectPhase()
+	for i := 1; i <= maxArgumentRetries; i++ {
+		state, err := reflect.Execute(context.Background(), deps)
+		if err != nil || state != agent.StateExecute {
+			t.Fatalf("attempt %d: reflect = %s, %v; want EXECUTE", i, state, err)
+		}
+		hint := deps.Context.ConversationHistory[len(deps.Context.ConversationHistory)-1].Content
+		if !strings.Contains(hint, "Re-extract") || !strings.Contains(hint, `"symbol_id"`) {
+			t.Fatalf("last message = %q, want the re-extraction hint", hint)
+		}
+		deps.Context.ToolResults = append(deps.Context.ToolResults, rejected)
+	}
+
+	// Past the retry limit the rejection counts as an ordinary failure.
+	if _, ok := rejectedArguments(deps.Context); ok {
+		t.Errorf("%d rejections in a row still asked for re-extraction", len(deps.Context.ToolResults))
+	}
+}
+
+func TestHasRejectedArguments(t *testing.T) {
+	ok := &tools.Result{Success: true}
+	bad := &tools.Result{Metadata: map[string]any{tools.MetaInvalidParameter: "depth"}}
+	if hasRejectedArguments([]*tools.Result{ok, nil}) || !hasRejectedArguments([]*tools.Result{ok, bad}) {
+		t.Error("hasRejectedArguments does not match results with an invalid parameter")
+	}
+}
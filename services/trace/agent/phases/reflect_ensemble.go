@@ -0,0 +1,554 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// Ensemble reconciliation thresholds. Two answers agree on an aspect when
+// the Jaccard similarity of what they say about it reaches the threshold.
+const (
+	// ensembleSymbolAgreement is the similarity needed between the sets
+	// of cited symbols.
+	ensembleSymbolAgreement = 0.5
+
+	// ensembleRecommendationAgreement is the similarity needed between
+	// the words of the final recommendations.
+	ensembleRecommendationAgreement = 0.35
+)
+
+// Aspects two ensemble answers can disagree on.
+const (
+	disagreementSymbols        = "cited_symbols"
+	disagreementRecommendation = "recommendation"
+)
+
+var (
+	// ensembleSymbolIDPattern matches graph symbol IDs: "path/file.go:42:Name".
+	ensembleSymbolIDPattern = regexp.MustCompile(`[\w./-]+\.\w+:\d+:[A-Za-z_][\w.]*`)
+
+	// ensembleCodeSpanPattern matches identifiers in backticks: `Save` or `pkg.Save()`.
+	ensembleCodeSpanPattern = regexp.MustCompile("`([A-Za-z_][\\w.]*)(?:\\(\\))?`")
+
+	// ensembleWordPattern matches the words compared between recommendations.
+	ensembleWordPattern = regexp.MustCompile(`[a-z0-9_]{3,}`)
+
+	// ensembleRecommendationCues mark the sentence carrying an answer's
+	// recommendation or conclusion.
+	ensembleRecommendationCues = []string{
+		"recommend", "should", "suggest", "conclusion", "in summary",
+		"to summarize", "the answer is", "best option", "in short",
+	}
+
+	// ensembleStopWords are ignored when comparing recommendations.
+	ensembleStopWords = map[string]bool{
+		"the": true, "and": true, "for": true, "that": true, "this": true,
+		"with": true, "you": true, "are": true, "can": true, "should": true,
+		"would": true, "recommend": true, "suggest": true, "from": true,
+		"into": true, "its": true, "use": true, "which": true, "will": true,
+	}
+)
+
+// ensembleFor returns the main client as an ensemble when the session
+// asked for ensemble mode and the client is one.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//
+// Outputs:
+//
+//	llm.EnsembleClient - The ensemble client.
+//	bool - False when ensemble mode is off or unavailable.
+func ensembleFor(deps *Dependencies) (llm.EnsembleClient, bool) {
+	if deps == nil || deps.Session == nil || deps.Session.Config == nil || !deps.Session.Config.Ensemble {
+		return nil, false
+	}
+	ens, ok := deps.LLMClient.(llm.EnsembleClient)
+	return ens, ok
+}
+
+// providerLabel names the provider and model that served a response, as
+// used for per-provider token accounting.
+//
+// Inputs:
+//
+//	client - The client that was called. May be nil.
+//	response - The response.
+//
+// Outputs:
+//
+//	string - "provider/model", or "" when neither is known.
+func providerLabel(client llm.Client, response *llm.Response) string {
+	provider, model := response.Provider, response.Model
+	if provider == "" && client != nil {
+		provider = client.Name()
+	}
+	if model == "" && client != nil {
+		model = client.Model()
+	}
+	switch {
+	case provider == "":
+		return model
+	case model == "":
+		return provider
+	}
+	return provider + "/" + model
+}
+
+// pendingEnsembleAnswer reports whether an ensemble run's final answer is
+// waiting to be reconciled.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//
+// Outputs:
+//
+//	int - Index of the answer in the conversation history.
+//	bool - True when ensemble mode is on and the last message is a final
+//	       assistant answer.
+func (p *ReflectPhase) pendingEnsembleAnswer(deps *Dependencies) (int, bool) {
+	if _, ok := ensembleFor(deps); !ok || deps.Context == nil {
+		return 0, false
+	}
+	history := deps.Context.ConversationHistory
+	if len(history) == 0 {
+		return 0, false
+	}
+	last := history[len(history)-1]
+	if last.Role != "assistant" || strings.TrimSpace(last.Content) == "" || strings.HasPrefix(last.Content, "[Tool calls:") {
+		return 0, false
+	}
+	return len(history) - 1, true
+}
+
+// reconcileFinalAnswer asks every ensemble member for the final answer
+// and replaces the answer at idx with the reconciled one.
+//
+// Description:
+//
+//	The members answer the same synthesis prompt over the conversation up
+//	to, but excluding, the answer the execute phase produced. If every
+//	member fails, that answer is kept.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies.
+//	input - The reflection input, for the reflection event.
+//	idx - Index of the final answer in the conversation history.
+//
+// Outputs:
+//
+//	agent.AgentState - COMPLETE.
+//	error - Always nil.
+func (p *ReflectPhase) reconcileFinalAnswer(ctx context.Context, deps *Dependencies, input *ReflectionInput, idx int) (agent.AgentState, error) {
+	const reason = "ensemble reconciliation"
+
+	source := p.copySynthesisContext(deps.Context)
+	source.ConversationHistory = source.ConversationHistory[:idx]
+	synthesisCtx := p.reduceSynthesisContext(deps, source)
+
+	attemptCtx := p.copySynthesisContext(synthesisCtx)
+	attemptCtx.ConversationHistory = append(attemptCtx.ConversationHistory, agent.Message{
+		Role:    "user",
+		Content: p.synthesisPrompt(ctx, deps, synthesisCtx),
+	})
+	request := llm.BuildRequest(attemptCtx, nil, 4096)
+	appendCitationPrompt(deps, request)
+	appendGraphQualityPrompt(deps, request)
+
+	response, err := p.completeSynthesis(ctx, deps, request)
+	if err != nil {
+		slog.WarnContext(ctx, "Ensemble reconciliation failed, keeping the primary answer",
+			slog.String("session_id", deps.Session.ID),
+			telemetry.SensitiveLogError("error", err),
+		)
+	} else {
+		deps.Context.ConversationHistory[idx].Content = response.Content
+		deps.Session.SetCurrentContext(deps.Context)
+	}
+
+	p.emitReflection(deps, input, &ReflectionOutput{
+		Decision: DecisionComplete,
+		Reason:   reason,
+	})
+	p.emitStateTransition(deps, agent.StateReflect, agent.StateComplete, reason)
+	return agent.StateComplete, nil
+}
+
+// completeSynthesis sends a synthesis request to the main model.
+//
+// Description:
+//
+//	Without ensemble mode this is deps.LLMClient.Complete. With it, the
+//	request goes to every member concurrently, each member's usage is
+//	recorded against its provider, and the answers are reconciled into
+//	one response. A failing member is left out; the call fails only when
+//	every member fails.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies.
+//	request - The synthesis request.
+//
+// Outputs:
+//
+//	*llm.Response - The (reconciled) response.
+//	error - Non-nil if the call failed.
+func (p *ReflectPhase) completeSynthesis(ctx context.Context, deps *Dependencies, request *llm.Request) (*llm.Response, error) {
+	ens, ok := ensembleFor(deps)
+	if !ok {
+		return deps.LLMClient.Complete(ctx, request)
+	}
+
+	candidates, err := ens.CompleteAll(ctx, request)
+	answers := p.recordEnsembleCandidates(deps, candidates)
+	if err != nil {
+		p.emitEnsemble(deps, candidates, answers, ensembleOutcome{chosen: -1})
+		return nil, err
+	}
+	if len(answers) == 0 {
+		p.emitEnsemble(deps, candidates, answers, ensembleOutcome{chosen: -1})
+		return nil, fmt.Errorf("all ensemble members returned empty answers")
+	}
+
+	outcome := reconcileAnswers(answers)
+	p.emitEnsemble(deps, candidates, answers, outcome)
+
+	slog.InfoContext(ctx, "Ensemble answers reconciled",
+		slog.String("session_id", deps.Session.ID),
+		slog.Int("answers", len(answers)),
+		slog.Int("members", len(candidates)),
+		slog.Bool("consensus", outcome.consensus),
+		slog.String("disagreements", strings.Join(outcome.disagreements, ",")),
+	)
+
+	response := &llm.Response{Content: outcome.content, Model: ens.Model(), Provider: ens.Name()}
+	if outcome.chosen >= 0 {
+		chosen := answers[outcome.chosen].response
+		response.Model, response.Provider = chosen.Model, chosen.Provider
+	}
+	return response, nil
+}
+
+// ensembleAnswer is one member's non-empty answer, prepared for comparison.
+type ensembleAnswer struct {
+	// label is the member's "provider/model".
+	label string
+
+	// content is the answer text.
+	content string
+
+	// symbols is the set of symbols the answer cites.
+	symbols map[string]bool
+
+	// recommendation is the set of words of the answer's recommendation.
+	recommendation map[string]bool
+
+	// response is the member's response.
+	response *llm.Response
+}
+
+// newEnsembleAnswer prepares a response for comparison.
+func newEnsembleAnswer(label string, response *llm.Response) ensembleAnswer {
+	return ensembleAnswer{
+		label:          label,
+		content:        strings.TrimSpace(response.Content),
+		symbols:        citedSymbols(response.Content),
+		recommendation: recommendationWords(response.Content),
+		response:       response,
+	}
+}
+
+// ensembleOutcome is the result of reconciling ensemble answers.
+type ensembleOutcome struct {
+	// consensus is true when the chosen answer agrees with a majority.
+	consensus bool
+
+	// chosen indexes the answer used, or -1 when all were returned.
+	chosen int
+
+	// disagreements lists the aspects the answers disagreed on.
+	disagreements []string
+
+	// content is the final answer text.
+	content string
+}
+
+// recordEnsembleCandidates accounts for each member's usage and emits an
+// LLM response event per answering member.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	candidates - The members' answers.
+//
+// Outputs:
+//
+//	[]ensembleAnswer - The non-empty answers, in member order.
+func (p *ReflectPhase) recordEnsembleCandidates(deps *Dependencies, candidates []llm.Candidate) []ensembleAnswer {
+	var answers []ensembleAnswer
+	for _, c := range candidates {
+		if c.Err != nil || c.Response == nil {
+			continue
+		}
+		label := providerLabel(nil, c.Response)
+		deps.Session.IncrementMetric(agent.MetricLLMCalls, 1)
+		if c.Response.OutputTokens > 0 {
+			deps.Session.IncrementProviderTokens(label, c.Response.OutputTokens)
+		}
+		if deps.EventEmitter != nil {
+			deps.EventEmitter.Emit(events.TypeLLMResponse, &events.LLMResponseData{
+				Model:      c.Response.Model,
+				Provider:   c.Response.Provider,
+				TokensIn:   c.Response.InputTokens,
+				TokensOut:  c.Response.OutputTokens,
+				Duration:   c.Response.Duration,
+				StopReason: c.Response.StopReason,
+				ContentLen: len(c.Response.Content),
+			})
+		}
+		if strings.TrimSpace(c.Response.Content) != "" {
+			answers = append(answers, newEnsembleAnswer(label, c.Response))
+		}
+	}
+	return answers
+}
+
+// emitEnsemble emits the ensemble reconciliation event.
+//
+// Inputs:
+//
+//	deps - Phase dependencies.
+//	candidates - The members' answers.
+//	answers - The non-empty answers.
+//	outcome - The reconciliation outcome.
+func (p *ReflectPhase) emitEnsemble(deps *Dependencies, candidates []llm.Candidate, answers []ensembleAnswer, outcome ensembleOutcome) {
+	if deps.EventEmitter == nil {
+		return
+	}
+
+	cited := make(map[*llm.Response]int, len(answers))
+	for _, a := range answers {
+		cited[a.response] = len(a.symbols)
+	}
+
+	data := &events.EnsembleData{
+		Consensus:     outcome.consensus,
+		Disagreements: outcome.disagreements,
+		Candidates:    make([]events.EnsembleCandidateData, 0, len(candidates)),
+	}
+	if outcome.chosen >= 0 && outcome.chosen < len(answers) {
+		data.Chosen = answers[outcome.chosen].label
+	}
+	for _, c := range candidates {
+		entry := events.EnsembleCandidateData{Provider: c.Provider, Model: c.Model}
+		switch {
+		case c.Err != nil:
+			entry.Error = c.Err.Error()
+		case c.Response == nil || strings.TrimSpace(c.Response.Content) == "":
+			entry.Error = "empty response"
+		}
+		if c.Response != nil {
+			entry.TokensIn = c.Response.InputTokens
+			entry.TokensOut = c.Response.OutputTokens
+			entry.Duration = c.Response.Duration
+			entry.CitedSymbols = cited[c.Response]
+		}
+		data.Candidates = append(data.Candidates, entry)
+	}
+	deps.EventEmitter.Emit(events.TypeEnsemble, data)
+}
+
+// reconcileAnswers picks the consensus answer or flags disagreement.
+//
+// Description:
+//
+//	Two answers agree when they cite largely the same symbols and their
+//	final recommendations share most of their words. The answer agreeing
+//	with the most others is chosen (the earlier member on ties). It is
+//	the consensus when it agrees with a majority of the others; the
+//	answer is returned with a note naming the agreeing models. Otherwise
+//	the result flags the disagreement and attaches every answer.
+//
+// Inputs:
+//
+//	answers - The non-empty answers, in member order. Must not be empty.
+//
+// Outputs:
+//
+//	ensembleOutcome - The reconciled answer.
+func reconcileAnswers(answers []ensembleAnswer) ensembleOutcome {
+	if len(answers) == 1 {
+		return ensembleOutcome{chosen: 0, content: answers[0].content}
+	}
+
+	best, bestAgreements := 0, -1
+	var agreeing [][]int
+	seen := make(map[string]bool)
+	var disagreements []string
+	for i := range answers {
+		var with []int
+		for j := range answers {
+			if i == j {
+				continue
+			}
+			symbolsAgree := setAgreement(answers[i].symbols, answers[j].symbols) >= ensembleSymbolAgreement
+			recommendationAgrees := setAgreement(answers[i].recommendation, answers[j].recommendation) >= ensembleRecommendationAgreement
+			if symbolsAgree && recommendationAgrees {
+				with = append(with, j)
+				continue
+			}
+			if !symbolsAgree && !seen[disagreementSymbols] {
+				seen[disagreementSymbols] = true
+				disagreements = append(disagreements, disagreementSymbols)
+			}
+			if !recommendationAgrees && !seen[disagreementRecommendation] {
+				seen[disagreementRecommendation] = true
+				disagreements = append(disagreements, disagreementRecommendation)
+			}
+		}
+		agreeing = append(agreeing, with)
+		if len(with) > bestAgreements {
+			best, bestAgreements = i, len(with)
+		}
+	}
+
+	others := len(answers) - 1
+	if bestAgreements > 0 && 2*bestAgreements >= others {
+		labels := []string{answers[best].label}
+		for _, j := range agreeing[best] {
+			labels = append(labels, answers[j].label)
+		}
+		content := fmt.Sprintf("%s\n\n---\n*%d of %d models agreed on this answer (%s).*",
+			answers[best].content, len(labels), len(answers), strings.Join(labels, ", "))
+		return ensembleOutcome{consensus: true, chosen: best, disagreements: disagreements, content: content}
+	}
+
+	return ensembleOutcome{chosen: -1, disagreements: disagreements, content: formatDisagreement(answers, disagreements)}
+}
+
+// formatDisagreement builds a final answer that flags the disagreement
+// and attaches every answer.
+func formatDisagreement(answers []ensembleAnswer, disagreements []string) string {
+	var aspects []string
+	for _, d := range disagreements {
+		switch d {
+		case disagreementSymbols:
+			aspects = append(aspects, "the code they cite")
+		case disagreementRecommendation:
+			aspects = append(aspects, "their conclusion")
+		}
+	}
+	if len(aspects) == 0 {
+		aspects = append(aspects, "their answer")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "⚠️ **The %d models disagree on %s.** Each answer follows; check them against the code before acting on either.\n",
+		len(answers), strings.Join(aspects, " and "))
+	for _, a := range answers {
+		fmt.Fprintf(&sb, "\n### Answer from %s\n\n%s\n", a.label, a.content)
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// citedSymbols returns the symbols an answer cites: graph symbol IDs and
+// identifiers in backticks.
+func citedSymbols(content string) map[string]bool {
+	symbols := make(map[string]bool)
+	for _, id := range ensembleSymbolIDPattern.FindAllString(content, -1) {
+		symbols[id] = true
+	}
+	for _, m := range ensembleCodeSpanPattern.FindAllStringSubmatch(content, -1) {
+		symbols[m[1]] = true
+	}
+	return symbols
+}
+
+// recommendationWords returns the words of an answer's recommendation:
+// the last sentence with a recommendation cue, or else the answer's
+// final sentence.
+func recommendationWords(content string) map[string]bool {
+	sentences := splitSentences(content)
+	if len(sentences) == 0 {
+		return map[string]bool{}
+	}
+
+	recommendation := sentences[len(sentences)-1]
+	for i := len(sentences) - 1; i >= 0; i-- {
+		lower := strings.ToLower(sentences[i])
+		found := false
+		for _, cue := range ensembleRecommendationCues {
+			if strings.Contains(lower, cue) {
+				found = true
+				break
+			}
+		}
+		if found {
+			recommendation = sentences[i]
+			break
+		}
+	}
+
+	words := make(map[string]bool)
+	for _, w := range ensembleWordPattern.FindAllString(strings.ToLower(recommendation), -1) {
+		if !ensembleStopWords[w] {
+			words[w] = true
+		}
+	}
+	return words
+}
+
+// splitSentences splits text into trimmed, non-empty sentences and lines,
+// skipping code fences and headings.
+func splitSentences(content string) []string {
+	var sentences []string
+	inFence := false
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			continue
+		}
+		for _, part := range strings.SplitAfter(trimmed, ". ") {
+			if part = strings.TrimSpace(part); part != "" {
+				sentences = append(sentences, part)
+			}
+		}
+	}
+	return sentences
+}
+
+// setAgreement is jaccardSimilarity, except that two empty sets agree:
+// answers that both cite nothing do not disagree on what they cite.
+func setAgreement(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	return jaccardSimilarity(a, b)
+}
@@ -0,0 +1,226 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+)
+
+// fakeEnsemble is an llm.EnsembleClient with canned member answers.
+type fakeEnsemble struct {
+	candidates []llm.Candidate
+	err        error
+
+	mu       sync.Mutex
+	requests []*llm.Request
+}
+
+func (f *fakeEnsemble) Complete(ctx context.Context, request *llm.Request) (*llm.Response, error) {
+	return f.candidates[0].Response, nil
+}
+
+func (f *fakeEnsemble) CompleteAll(ctx context.Context, request *llm.Request) ([]llm.Candidate, error) {
+	f.mu.Lock()
+	f.requests = append(f.requests, request)
+	f.mu.Unlock()
+	return f.candidates, f.err
+}
+
+func (f *fakeEnsemble) Name() string  { return "ensemble" }
+func (f *fakeEnsemble) Model() string { return "ollama:glm,anthropic:claude" }
+
+func ensembleCandidate(provider, model, content string, tokens int) llm.Candidate {
+	return llm.Candidate{Provider: provider, Model: model, Response: &llm.Response{
+		Content: content, Provider: provider, Model: model, InputTokens: 100, OutputTokens: tokens,
+	}}
+}
+
+func ensembleDeps(t *testing.T, client llm.Client) (*Dependencies, *[]*events.Event) {
+	t.Helper()
+	deps := createTestDependencies()
+	deps.Session.Config.Ensemble = true
+	deps.LLMClient = client
+	deps.Context = &agent.AssembledContext{
+		ConversationHistory: []agent.Message{
+			{Role: "user", Content: "Where are sessions saved?"},
+			{Role: "assistant", Content: "[Tool calls: find_symbol]"},
+			{Role: "assistant", Content: "Sessions are saved by `Store.Save`."},
+		},
+	}
+	var mu sync.Mutex
+	var got []*events.Event
+	deps.EventEmitter.Subscribe(func(e *events.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, e)
+	}, events.TypeEnsemble, events.TypeLLMResponse)
+	return deps, &got
+}
+
+func TestReconcileAnswers(t *testing.T) {
+	agreeA := "Sessions are persisted by `Store.Save` in store.go. I recommend adding a retry around `Store.Save`."
+	agreeB := "The `Store.Save` method writes sessions. I recommend wrapping `Store.Save` with a retry."
+	other := "Sessions live in memory in `Cache.Put`. You should switch to a database-backed cache."
+
+	tests := []struct {
+		name          string
+		contents      []string
+		wantConsensus bool
+		wantChosen    int
+		wantDisagree  []string
+	}{
+		{"two agree", []string{agreeA, agreeB}, true, 0, nil},
+		{"two disagree", []string{agreeA, other}, false, -1, []string{disagreementSymbols, disagreementRecommendation}},
+		{"majority of three", []string{other, agreeA, agreeB}, true, 1, []string{disagreementSymbols, disagreementRecommendation}},
+		{"single answer", []string{agreeA}, false, 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels := []string{"ollama/glm", "anthropic/claude", "gemini/pro"}
+			var answers []ensembleAnswer
+			for i, content := range tt.contents {
+				answers = append(answers, newEnsembleAnswer(labels[i], &llm.Response{Content: content}))
+			}
+			got := reconcileAnswers(answers)
+			if got.consensus != tt.wantConsensus || got.chosen != tt.wantChosen {
+				t.Errorf("consensus=%v chosen=%d, want %v %d", got.consensus, got.chosen, tt.wantConsensus, tt.wantChosen)
+			}
+			if strings.Join(got.disagreements, ",") != strings.Join(tt.wantDisagree, ",") {
+				t.Errorf("disagreements = %v, want %v", got.disagreements, tt.wantDisagree)
+			}
+			if !tt.wantConsensus && tt.wantChosen < 0 {
+				for _, label := range labels[:len(tt.contents)] {
+					if !strings.Contains(got.content, "### Answer from "+label) {
+						t.Errorf("content lacks the answer from %s:\n%s", label, got.content)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestReflectPhase_EnsembleConsensus(t *testing.T) {
+	client := &fakeEnsemble{candidates: []llm.Candidate{
+		ensembleCandidate("ollama", "glm", "Sessions are persisted by `Store.Save`. I recommend adding a retry around `Store.Save`.", 40),
+		ensembleCandidate("anthropic", "claude", "`Store.Save` writes the sessions. I recommend a retry around `Store.Save`.", 60),
+	}}
+	deps, got := ensembleDeps(t, client)
+
+	state, err := NewReflectPhase().Execute(context.Background(), deps)
+	if err != nil || state != agent.StateComplete {
+		t.Fatalf("Execute = %s, %v", state, err)
+	}
+	if len(client.requests) != 1 {
+		t.Fatalf("CompleteAll called %d times", len(client.requests))
+	}
+	for _, msg := range client.requests[0].Messages {
+		if strings.Contains(msg.Content, "saved by `Store.Save`") {
+			t.Error("the members should not see the answer being reconciled")
+		}
+	}
+
+	history := deps.Context.ConversationHistory
+	if len(history) != 3 || !strings.Contains(history[2].Content, "2 of 2 models agreed") {
+		t.Errorf("final answer = %q", history[2].Content)
+	}
+
+	metrics := deps.Session.GetMetrics()
+	if metrics.TokensByProvider["ollama/glm"] != 40 || metrics.TokensByProvider["anthropic/claude"] != 60 || metrics.TotalTokens != 100 {
+		t.Errorf("metrics = %+v", metrics)
+	}
+
+	var llmEvents int
+	var ensemble *events.EnsembleData
+	for _, e := range *got {
+		switch data := e.Data.(type) {
+		case *events.LLMResponseData:
+			llmEvents++
+			if data.Provider == "" || data.TokensIn != 100 {
+				t.Errorf("llm_response = %+v", data)
+			}
+		case *events.EnsembleData:
+			ensemble = data
+		}
+	}
+	if llmEvents != 2 || ensemble == nil || !ensemble.Consensus || ensemble.Chosen != "ollama/glm" || len(ensemble.Candidates) != 2 {
+		t.Errorf("events: %d llm_response, ensemble %+v", llmEvents, ensemble)
+	}
+}
+
+func TestReflectPhase_EnsembleDisagreementAndFailure(t *testing.T) {
+	client := &fakeEnsemble{candidates: []llm.Candidate{
+		ensembleCandidate("ollama", "glm", "Sessions are saved by `Store.Save`. You should add retries.", 40),
+		{Provider: "gemini", Model: "pro", Err: errors.New("quota exceeded")},
+		ensembleCandidate("anthropic", "claude", "Sessions are kept in `Cache.Put` only. I recommend persisting them to disk.", 60),
+	}}
+	deps, got := ensembleDeps(t, client)
+
+	if state, _ := NewReflectPhase().Execute(context.Background(), deps); state != agent.StateComplete {
+		t.Fatalf("state = %s", state)
+	}
+	answer := deps.Context.ConversationHistory[2].Content
+	for _, want := range []string{"models disagree", "### Answer from ollama/glm", "### Answer from anthropic/claude"} {
+		if !strings.Contains(answer, want) {
+			t.Errorf("answer lacks %q:\n%s", want, answer)
+		}
+	}
+	if strings.Contains(answer, "gemini") {
+		t.Error("the failed member's answer should not be attached")
+	}
+
+	var ensemble *events.EnsembleData
+	for _, e := range *got {
+		if data, ok := e.Data.(*events.EnsembleData); ok {
+			ensemble = data
+		}
+	}
+	if ensemble == nil || ensemble.Consensus || ensemble.Chosen != "" || ensemble.Candidates[1].Error != "quota exceeded" {
+		t.Errorf("ensemble event = %+v", ensemble)
+	}
+}
+
+func TestReflectPhase_EnsembleAllFailKeepsAnswer(t *testing.T) {
+	client := &fakeEnsemble{
+		candidates: []llm.Candidate{{Provider: "ollama", Model: "glm", Err: errors.New("down")}},
+		err:        errors.New("all ensemble members failed"),
+	}
+	deps, _ := ensembleDeps(t, client)
+
+	if state, _ := NewReflectPhase().Execute(context.Background(), deps); state != agent.StateComplete {
+		t.Fatalf("state = %s", state)
+	}
+	if got := deps.Context.ConversationHistory[2].Content; got != "Sessions are saved by `Store.Save`." {
+		t.Errorf("answer = %q, want the primary answer kept", got)
+	}
+}
+
+func TestReflectPhase_EnsembleToggleOff(t *testing.T) {
+	client := &fakeEnsemble{candidates: []llm.Candidate{ensembleCandidate("ollama", "glm", "unused", 1)}}
+	deps, _ := ensembleDeps(t, client)
+	deps.Session.Config.Ensemble = false
+
+	if _, ok := NewReflectPhase().pendingEnsembleAnswer(deps); ok {
+		t.Error("ensemble reconciliation should be off without the per-run toggle")
+	}
+	deps.Session.Config.Ensemble = true
+	deps.LLMClient = llm.NewMockClient()
+	if _, ok := NewReflectPhase().pendingEnsembleAnswer(deps); ok {
+		t.Error("ensemble reconciliation should be off when the client is not an ensemble")
+	}
+}
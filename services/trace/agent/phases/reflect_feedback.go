@@ -0,0 +1,145 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// routingFeedbackAction marks the trace step written once feedback for a
+// session's routed tool has been recorded.
+const routingFeedbackAction = "routing_feedback"
+
+// routingFeedbackTimeout bounds the feedback call (it may embed the query).
+const routingFeedbackTimeout = 2 * time.Second
+
+// RoutingFeedbackRecorder receives routing outcomes from the Reflect phase.
+//
+// Implemented by *routing.PreFilter.
+type RoutingFeedbackRecorder interface {
+	// RecordFeedback records whether routing query to tool worked.
+	RecordFeedback(ctx context.Context, query, tool string, success bool) error
+}
+
+// deriveRoutingOutcome decides whether the first routed tool call worked.
+//
+// Description:
+//
+//	The first tool call of a session is the router's selection for the
+//	query. Its outcome is:
+//	  - success if it returned without error and Reflect completed, or the
+//	    agent moved on to another call (the result was used);
+//	  - failure if it errored and the agent immediately called a different
+//	    tool, or Reflect stopped (complete/clarify) after the error.
+//	Otherwise the outcome is not yet known.
+//
+// Inputs:
+//
+//	steps - Session trace steps in order.
+//	decision - The current reflection decision.
+//
+// Outputs:
+//
+//	string - The routed tool.
+//	bool - True for success.
+//	bool - True if an outcome could be decided.
+func deriveRoutingOutcome(steps []crs.TraceStep, decision ReflectionDecision) (string, bool, bool) {
+	var calls []crs.TraceStep
+	for _, step := range steps {
+		if step.Action == "tool_call" || step.Action == "tool_call_forced" {
+			calls = append(calls, step)
+			if len(calls) == 2 {
+				break
+			}
+		}
+	}
+	if len(calls) == 0 {
+		return "", false, false
+	}
+
+	first := calls[0]
+	if first.Error == "" {
+		if decision == DecisionComplete || len(calls) > 1 {
+			return first.Tool, true, true
+		}
+		return "", false, false
+	}
+
+	if len(calls) > 1 && calls[1].Tool != first.Tool {
+		return first.Tool, false, true
+	}
+	if decision == DecisionComplete || decision == DecisionClarify {
+		return first.Tool, false, true
+	}
+	return "", false, false
+}
+
+// recordRoutingFeedback reports the routed tool's outcome once per session.
+//
+// Description:
+//
+//	No-op without a recorder, once feedback was recorded for the session,
+//	or while the outcome is undecided. Failures are logged; feedback never
+//	affects the reflection decision.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	deps - Phase dependencies.
+//	decision - The reflection decision.
+func (p *ReflectPhase) recordRoutingFeedback(ctx context.Context, deps *Dependencies, decision ReflectionDecision) {
+	if p.routingFeedback == nil || deps.Query == "" {
+		return
+	}
+
+	steps := deps.Session.GetTraceSteps()
+	for _, step := range steps {
+		if step.Action == routingFeedbackAction {
+			return
+		}
+	}
+
+	tool, success, decided := deriveRoutingOutcome(steps, decision)
+	if !decided {
+		return
+	}
+
+	fbCtx, cancel := context.WithTimeout(ctx, routingFeedbackTimeout)
+	defer cancel()
+	if err := p.routingFeedback.RecordFeedback(fbCtx, deps.Query, tool, success); err != nil {
+		slog.WarnContext(ctx, "routing feedback: record failed",
+			slog.String("session_id", deps.Session.ID),
+			slog.String("tool", tool),
+			telemetry.SensitiveLogError("error", err),
+		)
+		return
+	}
+
+	deps.Session.RecordTraceStep(crs.TraceStep{
+		Timestamp: time.Now().UnixMilli(),
+		Action:    routingFeedbackAction,
+		Target:    tool,
+		Tool:      "router",
+		Metadata: map[string]string{
+			"success": strconv.FormatBool(success),
+		},
+	})
+}
+
+// Compile-time check: the pre-filter is the production recorder.
+var _ RoutingFeedbackRecorder = (*routing.PreFilter)(nil)
@@ -0,0 +1,82 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+)
+
+type fakeFeedbackRecorder struct {
+	calls   int
+	tool    string
+	success bool
+}
+
+func (f *fakeFeedbackRecorder) RecordFeedback(_ context.Context, _ string, tool string, success bool) error {
+	f.calls++
+	f.tool = tool
+	f.success = success
+	return nil
+}
+
+func TestDeriveRoutingOutcome(t *testing.T) {
+	call := func(tool, errMsg string) crs.TraceStep {
+		return crs.TraceStep{Action: "tool_call", Tool: tool, Error: errMsg}
+	}
+
+	tests := []struct {
+		name        string
+		steps       []crs.TraceStep
+		decision    ReflectionDecision
+		wantTool    string
+		wantSuccess bool
+		wantDecided bool
+	}{
+		{"no calls", nil, DecisionComplete, "", false, false},
+		{"success then complete", []crs.TraceStep{call("find_callers", "")}, DecisionComplete, "find_callers", true, true},
+		{"success still running", []crs.TraceStep{call("find_callers", "")}, DecisionContinue, "", false, false},
+		{"success then follow-up", []crs.TraceStep{call("find_callers", ""), call("read_symbol", "")}, DecisionContinue, "find_callers", true, true},
+		{"error then other tool", []crs.TraceStep{call("find_callers", "boom"), call("find_references", "")}, DecisionContinue, "find_callers", false, true},
+		{"error then same tool", []crs.TraceStep{call("find_callers", "boom"), call("find_callers", "")}, DecisionContinue, "", false, false},
+		{"error then clarify", []crs.TraceStep{call("find_callers", "boom")}, DecisionClarify, "find_callers", false, true},
+		{"forced call counts", []crs.TraceStep{{Action: "tool_call_forced", Tool: "find_symbol"}}, DecisionComplete, "find_symbol", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool, success, decided := deriveRoutingOutcome(tt.steps, tt.decision)
+			if tool != tt.wantTool || success != tt.wantSuccess || decided != tt.wantDecided {
+				t.Errorf("deriveRoutingOutcome() = (%q, %v, %v), want (%q, %v, %v)",
+					tool, success, decided, tt.wantTool, tt.wantSuccess, tt.wantDecided)
+			}
+		})
+	}
+}
+
+func TestReflectPhase_RecordRoutingFeedback_OncePerSession(t *testing.T) {
+	rec := &fakeFeedbackRecorder{}
+	phase := NewReflectPhase(WithRoutingFeedback(rec))
+	deps := createTestDependencies()
+	deps.Session.RecordTraceStep(crs.TraceStep{Action: "tool_call", Tool: "find_callers"})
+
+	ctx := context.Background()
+	phase.recordRoutingFeedback(ctx, deps, DecisionComplete)
+	phase.recordRoutingFeedback(ctx, deps, DecisionComplete)
+
+	if rec.calls != 1 {
+		t.Fatalf("RecordFeedback calls = %d, want 1", rec.calls)
+	}
+	if rec.tool != "find_callers" || !rec.success {
+		t.Errorf("recorded (%q, %v), want (find_callers, true)", rec.tool, rec.success)
+	}
+}
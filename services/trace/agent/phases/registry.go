@@ -0,0 +1,112 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// BuiltinPhaseNames are the names of the phases that ship with the agent.
+// Custom phases cannot reuse them.
+var BuiltinPhaseNames = []string{"init", "plan", "execute", "reflect", "clarify"}
+
+// customPhases holds phases registered with RegisterPhase.
+var customPhases = struct {
+	mu     sync.RWMutex
+	byName map[string]Phase
+}{
+	byName: make(map[string]Phase),
+}
+
+// RegisterPhase makes a user-defined phase available to pipeline configs.
+//
+// Description:
+//
+//	Custom phases are compiled into the binary and register themselves by
+//	name, typically from an init function, so a pipeline config
+//	(TRACE_PIPELINE_CONFIG) can assign them to a state:
+//
+//	    func init() {
+//	        phases.MustRegisterPhase(NewVerifyPhase())
+//	    }
+//
+//	A custom phase implements Phase like the built-in ones. Execute gets
+//	the session's Dependencies and returns the next state, which must be
+//	one of the state's configured transitions; the loop wraps it in a
+//	PhaseAdapter (package trace) to run it. Returning an error moves the
+//	session to ERROR.
+//
+// Inputs:
+//
+//	phase - The phase. Registered under phase.Name().
+//
+// Outputs:
+//
+//	error - Non-nil if the phase is nil, its name is empty or built in,
+//	  or another phase has the same name.
+//
+// Thread Safety: Safe for concurrent use.
+func RegisterPhase(phase Phase) error {
+	if phase == nil {
+		return fmt.Errorf("register phase: phase is nil")
+	}
+	name := phase.Name()
+	if name == "" {
+		return fmt.Errorf("register phase: name is empty")
+	}
+	for _, builtin := range BuiltinPhaseNames {
+		if name == builtin {
+			return fmt.Errorf("register phase %q: name is reserved for a built-in phase", name)
+		}
+	}
+
+	customPhases.mu.Lock()
+	defer customPhases.mu.Unlock()
+	if _, exists := customPhases.byName[name]; exists {
+		return fmt.Errorf("register phase %q: already registered", name)
+	}
+	customPhases.byName[name] = phase
+	return nil
+}
+
+// MustRegisterPhase is RegisterPhase for init functions; it panics on
+// error.
+func MustRegisterPhase(phase Phase) {
+	if err := RegisterPhase(phase); err != nil {
+		panic(err)
+	}
+}
+
+// UnregisterPhase removes a custom phase. Used by tests.
+//
+// Thread Safety: Safe for concurrent use.
+func UnregisterPhase(name string) {
+	customPhases.mu.Lock()
+	defer customPhases.mu.Unlock()
+	delete(customPhases.byName, name)
+}
+
+// RegisteredPhases returns the custom phases, sorted by name.
+//
+// Thread Safety: Safe for concurrent use.
+func RegisteredPhases() []Phase {
+	customPhases.mu.RLock()
+	defer customPhases.mu.RUnlock()
+
+	out := make([]Phase, 0, len(customPhases.byName))
+	for _, phase := range customPhases.byName {
+		out = append(out, phase)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name() < out[j].Name() })
+	return out
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+)
+
+// verifyPhase is a minimal custom phase.
+type verifyPhase struct{ name string }
+
+func (p verifyPhase) Name() string { return p.name }
+
+func (p verifyPhase) Execute(_ context.Context, _ *Dependencies) (agent.AgentState, error) {
+	return agent.StateComplete, nil
+}
+
+func TestRegisterPhase(t *testing.T) {
+	t.Cleanup(func() { UnregisterPhase("verify") })
+
+	if err := RegisterPhase(verifyPhase{name: "verify"}); err != nil {
+		t.Fatalf("RegisterPhase(verify) error = %v", err)
+	}
+	if got := RegisteredPhases(); len(got) != 1 || got[0].Name() != "verify" {
+		t.Errorf("RegisteredPhases() = %v, want [verify]", got)
+	}
+
+	for name, phase := range map[string]Phase{
+		"duplicate": verifyPhase{name: "verify"},
+		"built-in":  verifyPhase{name: "execute"},
+		"empty":     verifyPhase{},
+		"nil":       nil,
+	} {
+		if err := RegisterPhase(phase); err == nil {
+			t.Errorf("%s: RegisterPhase succeeded, want an error", name)
+		}
+	}
+	for _, builtin := range []Phase{NewInitPhase(), NewPlanPhase(), NewExecutePhase(), NewReflectPhase(), NewClarifyPhase()} {
+		found := false
+		for _, name := range BuiltinPhaseNames {
+			found = found || name == builtin.Name()
+		}
+		if !found {
+			t.Errorf("BuiltinPhaseNames is missing %q", builtin.Name())
+		}
+	}
+}
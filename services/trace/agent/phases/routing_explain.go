@@ -0,0 +1,78 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+// ExplainRouting runs the routing pipeline for a query without executing
+// anything.
+//
+// Description:
+//
+//	Runs the pre-filter over defs, then the router over the narrowed
+//	tools (unless a tool was forced), then the query-semantics
+//	correction, and returns the resulting decision. There is no session,
+//	so the circuit breaker, UCB1 and the session penalty do not apply.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	pf - The pre-filter. May be nil: all tools go to the router.
+//	router - The tool router. May be nil: the pre-filter's best tool is
+//	  the final choice.
+//	query - The query to route.
+//	defs - The available tools.
+//
+// Outputs:
+//
+//	*agent.RoutingDecision - The decision.
+//	error - Non-nil if the router fails.
+//
+// Thread Safety: Safe for concurrent use if pf and router are.
+func ExplainRouting(ctx context.Context, pf *routing.PreFilter, router agent.ToolRouter, query string, defs []tools.ToolDefinition) (*agent.RoutingDecision, error) {
+	specs := toolDefsToSpecs(defs)
+	decision := agent.NewRoutingDecision(query, len(specs))
+
+	if pf != nil {
+		result := pf.FilterAgentSpecs(ctx, query, specs, nil)
+		if result.Decision != nil {
+			decision = result.Decision
+		}
+		if result.ForcedTool != "" {
+			return decision, nil
+		}
+		specs = result.NarrowedSpecs
+	}
+
+	if router != nil {
+		selection, err := router.SelectTool(ctx, query, specs, nil)
+		if err != nil {
+			return decision, fmt.Errorf("tool router: %w", err)
+		}
+		decision.ApplyRouterSelection(router.Model(), selection)
+	}
+
+	if decision.FinalTool != "" {
+		if corrected, changed, _ := ValidateToolQuerySemantics(query, decision.FinalTool); changed {
+			decision.AddRuleHit("semantic_correction:" + corrected)
+			decision.SetFinal(corrected, agent.RoutingSourceSemanticCorrection)
+		}
+	}
+	decision.Scores = nil
+	return decision, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+// explainRouter returns a fixed selection.
+type explainRouter struct {
+	tool string
+	err  error
+	seen int
+}
+
+func (r *explainRouter) SelectTool(ctx context.Context, query string, availableTools []agent.ToolRouterSpec, codeContext *agent.ToolRouterCodeContext) (*agent.ToolRouterSelection, error) {
+	r.seen = len(availableTools)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &agent.ToolRouterSelection{Tool: r.tool, Confidence: 0.9, Reasoning: "matched"}, nil
+}
+
+func (r *explainRouter) Model() string { return "explain-model" }
+
+func (r *explainRouter) Close() error { return nil }
+
+func explainDefs() []tools.ToolDefinition {
+	return []tools.ToolDefinition{
+		{Name: "find_callers", Description: "Find functions that call a symbol"},
+		{Name: "find_callees", Description: "Find functions a symbol calls"},
+		{Name: "get_call_chain", Description: "Trace the call chain from a function"},
+	}
+}
+
+func TestExplainRouting_Router(t *testing.T) {
+	router := &explainRouter{tool: "find_callers"}
+	d, err := ExplainRouting(context.Background(), nil, router, "Who calls parseConfig", explainDefs())
+	if err != nil {
+		t.Fatalf("ExplainRouting: %v", err)
+	}
+	if router.seen != 3 {
+		t.Errorf("router saw %d tools, want 3", router.seen)
+	}
+	if d.FinalTool != "find_callers" || d.Source != agent.RoutingSourceRouter {
+		t.Errorf("final = %s (%s), want find_callers (router)", d.FinalTool, d.Source)
+	}
+	if d.RouterModel != "explain-model" || d.RouterConfidence != 0.9 {
+		t.Errorf("router fields not recorded: %+v", d)
+	}
+}
+
+func TestExplainRouting_SemanticCorrection(t *testing.T) {
+	router := &explainRouter{tool: "find_callers"}
+	d, err := ExplainRouting(context.Background(), nil, router, "Show the call chain from main", explainDefs())
+	if err != nil {
+		t.Fatalf("ExplainRouting: %v", err)
+	}
+	if d.FinalTool != "get_call_chain" || d.Source != agent.RoutingSourceSemanticCorrection {
+		t.Errorf("final = %s (%s), want corrected get_call_chain", d.FinalTool, d.Source)
+	}
+	if d.RouterTool != "find_callers" {
+		t.Errorf("RouterTool = %s, want the router's original pick", d.RouterTool)
+	}
+	if len(d.RuleHits) != 1 || d.RuleHits[0] != "semantic_correction:get_call_chain" {
+		t.Errorf("RuleHits = %v", d.RuleHits)
+	}
+}
+
+func TestExplainRouting_RouterError(t *testing.T) {
+	router := &explainRouter{err: errors.New("model offline")}
+	d, err := ExplainRouting(context.Background(), nil, router, "Who calls parseConfig", explainDefs())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if d == nil || d.FinalTool != "" {
+		t.Errorf("unexpected decision on error: %+v", d)
+	}
+}
+
+func TestRecordRoutingDecision_Session(t *testing.T) {
+	session, err := agent.NewSession("/tmp/project", nil)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	d := agent.NewRoutingDecision("Who calls parseConfig", 3)
+	d.Scores = map[string]float64{"find_callers": 0.8, "find_callees": 0.5}
+	d.SetFinal("find_callers", agent.RoutingSourceRouter)
+
+	p := &ExecutePhase{}
+	p.recordRoutingDecision(&Dependencies{Session: session}, d)
+	p.recordRoutingDecision(&Dependencies{Session: session}, nil)
+
+	got := session.RoutingDecisions()
+	if len(got) != 1 {
+		t.Fatalf("got %d decisions, want 1", len(got))
+	}
+	if got[0].FinalTool != "find_callers" || got[0].Scores != nil {
+		t.Errorf("stored decision = %+v", got[0])
+	}
+	if got[0].Margin < 0.29 || got[0].Margin > 0.31 {
+		t.Errorf("Margin = %f, want 0.3", got[0].Margin)
+	}
+}
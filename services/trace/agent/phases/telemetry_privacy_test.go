@@ -0,0 +1,260 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package phases
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	agentcontext "github.com/AleutianAI/AleutianFOSS/services/trace/agent/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cache"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+var (
+	phaseSpansOnce sync.Once
+	phaseSpans     *tracetest.InMemoryExporter
+)
+
+// recordPhaseSpans installs an in-memory span exporter as the global tracer
+// provider. Package-level tracers only follow the first provider set, so
+// it is installed once and reset for each caller.
+func recordPhaseSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	phaseSpansOnce.Do(func() {
+		phaseSpans = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(phaseSpans)))
+	})
+	phaseSpans.Reset()
+	return phaseSpans
+}
+
+// captureTelemetry switches to privacy mode for the rest of the test and
+// records its spans and JSON logs. The returned function stops nothing; it
+// returns every span attribute and log field recorded so far, with where
+// it was recorded. Set up before constructing components that keep
+// slog.Default().
+func captureTelemetry(t *testing.T, mode telemetry.PrivacyMode) func() []string {
+	t.Helper()
+	exporter := recordPhaseSpans(t)
+	previousMode := telemetry.SetPrivacyMode(mode)
+	t.Cleanup(func() { telemetry.SetPrivacyMode(previousMode) })
+
+	var buf bytes.Buffer
+	previousLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	t.Cleanup(func() { slog.SetDefault(previousLogger) })
+
+	return func() []string {
+		var values []string
+		for _, s := range exporter.GetSpans() {
+			for _, a := range s.Attributes {
+				values = append(values, fmt.Sprintf("span %s %s=%s", s.Name, a.Key, a.Value.Emit()))
+			}
+			for _, ev := range s.Events {
+				for _, a := range ev.Attributes {
+					values = append(values, fmt.Sprintf("span %s event %s %s=%s", s.Name, ev.Name, a.Key, a.Value.Emit()))
+				}
+			}
+			values = append(values, fmt.Sprintf("span %s status=%s", s.Name, s.Status.Description))
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(buf.Bytes()))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			var rec map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+				t.Fatalf("invalid JSON log line %q: %v", scanner.Text(), err)
+			}
+			for key, v := range rec {
+				if key == "msg" || key == "time" || key == "level" {
+					continue
+				}
+				values = append(values, fmt.Sprintf("log %q %s=%v", rec["msg"], key, v))
+			}
+		}
+		return values
+	}
+}
+
+// runFailingToolPhase runs the execute phase in mode against a mocked LLM
+// that calls a tool failing with an error naming a file and a symbol, and
+// returns every exported span attribute and log field, with where it was
+// recorded.
+func runFailingToolPhase(t *testing.T, mode telemetry.PrivacyMode) []string {
+	t.Helper()
+	recorded := captureTelemetry(t, mode)
+
+	phase := NewExecutePhase(WithReflectionThreshold(100))
+	deps := createTestDependencies()
+	deps.Query = "Who calls PlaceOrder?"
+	deps.Context = &agent.AssembledContext{ConversationHistory: []agent.Message{}}
+	deps.ToolRegistry = tools.NewRegistry()
+	deps.Coordinator = nil
+
+	failing := tools.NewMockTool("find_callers", tools.CategoryExploration)
+	failing.ExecuteFunc = func(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
+		return nil, fmt.Errorf("open /home/dev/shop/pkg/orders.go: symbol PlaceOrder not indexed")
+	}
+	deps.ToolRegistry.Register(failing)
+	deps.ToolExecutor = tools.NewExecutor(deps.ToolRegistry, nil)
+	deps.ContextManager = &agentcontext.Manager{}
+
+	mockLLM := llm.NewMockClient()
+	mockLLM.QueueToolCall("find_callers", map[string]any{"function_name": "PlaceOrder"})
+	deps.LLMClient = mockLLM
+
+	if _, err := phase.Execute(context.Background(), deps); err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	return recorded()
+}
+
+// scriptedChat is a providers.ChatClient answering with a fixed reply.
+type scriptedChat struct {
+	reply string
+}
+
+func (c *scriptedChat) Chat(context.Context, []providers.Message, providers.ChatOptions) (string, error) {
+	return c.reply, nil
+}
+
+// runRoutingAndCache routes a query naming a symbol through the escalating
+// router, extracts its parameters, resolves a conceptual symbol, and loads
+// a project under root through the graph cache twice (build, then a
+// staleness-checked hit), in mode. Returns every exported span attribute
+// and log field, with where it was recorded.
+func runRoutingAndCache(t *testing.T, mode telemetry.PrivacyMode, root string) []string {
+	t.Helper()
+	recorded := captureTelemetry(t, mode)
+	ctx := context.Background()
+
+	chat := &scriptedChat{}
+	router, err := routing.NewGranite4Router(chat, routing.DefaultRouterConfig())
+	if err != nil {
+		t.Fatalf("NewGranite4Router: %v", err)
+	}
+	specs := []routing.ToolSpec{{Name: "find_callers", Description: "Find the callers of a function"}}
+	escalating := routing.NewEscalatingRouter(router, router, 0.5, specs, time.Second, slog.Default())
+	chat.reply = `{"tool": "find_callers", "confidence": 0.9, "reasoning": "callers of PlaceOrder"}`
+	if _, err := escalating.SelectTool(ctx, "Who calls PlaceOrder in orders.go?", specs, nil); err != nil {
+		t.Fatalf("SelectTool: %v", err)
+	}
+
+	extractor, err := routing.NewParamExtractor(chat, routing.DefaultParamExtractorConfig())
+	if err != nil {
+		t.Fatalf("NewParamExtractor: %v", err)
+	}
+	chat.reply = `{"function_name": "PlaceOrder"}`
+	schemas := []routing.ParamSchema{{Name: "function_name", Type: "string", Required: true}}
+	if _, err := extractor.ExtractParams(ctx, "Who calls PlaceOrder?", "find_callers", schemas, nil); err != nil {
+		t.Fatalf("ExtractParams: %v", err)
+	}
+	chat.reply = "PlaceOrder (method) in orders.go:12"
+	candidates := []agent.SymbolCandidate{{Name: "PlaceOrder", Kind: "method", FilePath: "pkg/orders.go", Line: 12}}
+	if _, err := extractor.ResolveConceptualSymbol(ctx, "where are orders placed?", candidates, 1, 0, ""); err != nil {
+		t.Fatalf("ResolveConceptualSymbol: %v", err)
+	}
+
+	graphs := cache.NewGraphCache()
+	build := func(ctx context.Context, projectRoot string) (*graph.Graph, *manifest.Manifest, error) {
+		g := graph.NewGraph(projectRoot)
+		g.Freeze()
+		return g, nil, nil
+	}
+	for i := 0; i < 2; i++ {
+		_, release, err := graphs.GetOrBuild(ctx, root, build)
+		if err != nil {
+			t.Fatalf("GetOrBuild: %v", err)
+		}
+		release()
+	}
+	return recorded()
+}
+
+// TestExecutePhase_MinimalPrivacy verifies that in minimal mode neither the
+// phase's spans nor its log fields carry the failing tool's error text, the
+// query's symbol or the file it names.
+func TestExecutePhase_MinimalPrivacy(t *testing.T) {
+	sensitive := regexp.MustCompile(`PlaceOrder|orders\.go|/home/dev`)
+	reveals := func(values []string) []string {
+		var hits []string
+		for _, v := range values {
+			if sensitive.MatchString(v) {
+				hits = append(hits, v)
+			}
+		}
+		return hits
+	}
+
+	// The error text is recorded in full mode, so the run exercises the
+	// redacted paths.
+	if hits := reveals(runFailingToolPhase(t, telemetry.PrivacyFull)); len(hits) == 0 {
+		t.Fatal("full mode recorded no sensitive values; the test no longer covers redaction")
+	}
+
+	if hits := reveals(runFailingToolPhase(t, telemetry.PrivacyMinimal)); len(hits) > 0 {
+		t.Errorf("minimal mode recorded sensitive values: %q", hits)
+	}
+}
+
+// TestRoutingAndCache_MinimalPrivacy verifies that in minimal mode routing,
+// parameter extraction and the graph cache record neither the query's
+// symbol nor the project root in span attributes or log fields.
+func TestRoutingAndCache_MinimalPrivacy(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "shop")
+	if err := os.MkdirAll(filepath.Join(root, "pkg"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "pkg", "orders.go"), []byte("package pkg\n\nfunc PlaceOrder() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sensitive := regexp.MustCompile(`PlaceOrder|orders\.go|` + regexp.QuoteMeta(root))
+	reveals := func(values []string) []string {
+		var hits []string
+		for _, v := range values {
+			if sensitive.MatchString(v) {
+				hits = append(hits, v)
+			}
+		}
+		return hits
+	}
+
+	if hits := reveals(runRoutingAndCache(t, telemetry.PrivacyFull, root)); len(hits) == 0 {
+		t.Fatal("full mode recorded no sensitive values; the test no longer covers redaction")
+	}
+
+	if hits := reveals(runRoutingAndCache(t, telemetry.PrivacyMinimal, root)); len(hits) > 0 {
+		t.Errorf("minimal mode recorded sensitive values:\n%s", strings.Join(hits, "\n"))
+	}
+}
@@ -34,6 +34,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/integration"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/rag"
@@ -202,6 +203,43 @@ type Dependencies struct {
 	// CRS-19: Optional - if nil, staleness detection is disabled.
 	// When available, spot-checks file mtimes before graph tool dispatch.
 	StalenessChecker *graph.StalenessChecker
+
+	// GraphQuality is the quality report of the session's graph.
+	// Optional - if nil, answers are not caveated on graph quality.
+	GraphQuality *graph.QualityReport
+
+	// ProjectSummary is the rendered summary of the session's project:
+	// languages, frameworks, entry points and main packages.
+	// Optional - if empty, the Plan phase prompt has no project overview.
+	ProjectSummary string
+
+	// KnownFacts are the facts users confirmed about the project in earlier
+	// sessions, ranked for this query and capped.
+	// Optional - if empty, the Plan phase prompt has no known facts.
+	KnownFacts []facts.Fact
+
+	// Facts records facts the user confirms or corrects in this session.
+	// Optional - if nil, user statements are not kept across sessions.
+	Facts FactRecorder
+}
+
+// FactRecorder keeps the facts users state about a project across
+// sessions.
+type FactRecorder interface {
+	// ProposeFact stores a fact detected in a user message.
+	//
+	// Inputs:
+	//   ctx - Context for cancellation.
+	//   c - The candidate, with SessionID and ConfirmedBy set.
+	//
+	// Outputs:
+	//   *facts.Fact - The stored fact.
+	//   error - Non-nil if the fact names no symbol of the graph, or
+	//     could not be stored.
+	ProposeFact(ctx context.Context, c facts.Candidate) (*facts.Fact, error)
+
+	// FactsApplied records that facts were given to a run of a session.
+	FactsApplied(ctx context.Context, sessionID string, ids []string)
 }
 
 // GraphProvider initializes and provides access to the code graph.
@@ -0,0 +1,365 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PipelineEnvVar names the environment variable holding the path of the
+// phase pipeline config.
+const PipelineEnvVar = "TRACE_PIPELINE_CONFIG"
+
+// PipelineConfig describes the agent loop's state machine: which states
+// exist, which registered phase handles each, and where each may go next.
+//
+// Description:
+//
+//	A state with no phase uses the loop's built-in transition for that
+//	state, so only INIT, PLAN, EXECUTE, REFLECT, CLARIFY and DEGRADED may
+//	omit it. COMPLETE and ERROR are always present and terminal, every
+//	non-terminal state may go to ERROR, IDLE goes to Start, and COMPLETE
+//	goes to FollowUp for multi-turn questions.
+//
+//	Redirect rewrites a phase's result before the transition. It is how a
+//	custom phase is inserted without changing the phases around it: with
+//	EXECUTE redirecting COMPLETE to VERIFY, the execute phase still returns
+//	COMPLETE and the loop moves to VERIFY instead.
+//
+// Example:
+//
+//	start: INIT
+//	states:
+//	  - {name: INIT, phase: init, transitions: [PLAN, DEGRADED]}
+//	  - {name: PLAN, phase: plan, transitions: [EXECUTE, CLARIFY]}
+//	  - name: EXECUTE
+//	    phase: execute
+//	    transitions: [EXECUTE, REFLECT, VERIFY, CLARIFY]
+//	    redirect: {COMPLETE: VERIFY}
+//	  - {name: VERIFY, phase: verify, transitions: [COMPLETE, EXECUTE]}
+//	  - {name: REFLECT, phase: reflect, transitions: [EXECUTE, COMPLETE, CLARIFY]}
+//	  - {name: CLARIFY, phase: clarify, transitions: [PLAN]}
+//	  - {name: DEGRADED, transitions: [PLAN]}
+type PipelineConfig struct {
+	// Start is the state a new query enters. Defaults to INIT.
+	Start AgentState `yaml:"start" json:"start"`
+
+	// FollowUp is the state a follow-up question on a completed session
+	// enters. Defaults to PLAN.
+	FollowUp AgentState `yaml:"follow_up" json:"follow_up"`
+
+	// States lists every non-terminal state.
+	States []PipelineStateConfig `yaml:"states" json:"states"`
+}
+
+// PipelineStateConfig describes one state of a PipelineConfig.
+type PipelineStateConfig struct {
+	// Name is the state, e.g. "EXECUTE" or a custom "VERIFY".
+	Name AgentState `yaml:"name" json:"name"`
+
+	// Phase is the registered name of the phase that handles the state.
+	Phase string `yaml:"phase" json:"phase"`
+
+	// Transitions lists the states the state may move to. ERROR is
+	// always allowed.
+	Transitions []AgentState `yaml:"transitions" json:"transitions"`
+
+	// Redirect maps a phase result to the state the loop moves to instead.
+	// Targets must be listed in Transitions.
+	Redirect map[AgentState]AgentState `yaml:"redirect" json:"redirect"`
+}
+
+// builtinPhaseStates are the states the loop can run without a phase.
+var builtinPhaseStates = map[AgentState]bool{
+	StateInit:     true,
+	StatePlan:     true,
+	StateExecute:  true,
+	StateReflect:  true,
+	StateClarify:  true,
+	StateDegraded: true,
+}
+
+// DefaultPipelineConfig returns the pipeline the loop runs when no config
+// is given, naming the built-in phases.
+//
+// Outputs:
+//
+//	*PipelineConfig - The transitions of NewStateMachine.
+func DefaultPipelineConfig() *PipelineConfig {
+	return &PipelineConfig{
+		Start:    StateInit,
+		FollowUp: StatePlan,
+		States: []PipelineStateConfig{
+			{Name: StateInit, Phase: "init", Transitions: []AgentState{StatePlan, StateDegraded}},
+			{Name: StatePlan, Phase: "plan", Transitions: []AgentState{StateExecute, StateClarify}},
+			{Name: StateExecute, Phase: "execute", Transitions: []AgentState{StateExecute, StateReflect, StateComplete, StateClarify}},
+			{Name: StateReflect, Phase: "reflect", Transitions: []AgentState{StateExecute, StateComplete, StateClarify}},
+			{Name: StateClarify, Phase: "clarify", Transitions: []AgentState{StatePlan}},
+			{Name: StateDegraded, Transitions: []AgentState{StatePlan}},
+		},
+	}
+}
+
+// ParsePipelineConfig parses a YAML or JSON pipeline config.
+//
+// Outputs:
+//
+//	*PipelineConfig - The parsed config. Not yet validated; see NewPipeline.
+//	error - ErrInvalidPipeline if the document is malformed.
+func ParsePipelineConfig(data []byte) (*PipelineConfig, error) {
+	var cfg PipelineConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPipeline, err)
+	}
+	return &cfg, nil
+}
+
+// LoadPipelineConfigFromEnv loads the config named by TRACE_PIPELINE_CONFIG.
+//
+// Outputs:
+//
+//	*PipelineConfig - The parsed config, or nil when the variable is unset.
+//	string - The file path, empty when unset.
+//	error - Non-nil if the file cannot be read or parsed.
+func LoadPipelineConfigFromEnv() (*PipelineConfig, string, error) {
+	path := os.Getenv(PipelineEnvVar)
+	if path == "" {
+		return nil, "", nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, path, fmt.Errorf("read pipeline config: %w", err)
+	}
+	cfg, err := ParsePipelineConfig(data)
+	if err != nil {
+		return nil, path, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, path, nil
+}
+
+// Pipeline is a validated PipelineConfig bound to phase executors.
+//
+// Description:
+//
+//	Pipeline is the PhaseRegistry and state machine of a DefaultAgentLoop
+//	configured with WithPipeline, and rewrites phase results according to
+//	each state's Redirect.
+//
+// Thread Safety: Immutable after NewPipeline; safe for concurrent use.
+type Pipeline struct {
+	start     AgentState
+	followUp  AgentState
+	machine   *StateMachine
+	phases    map[AgentState]PhaseExecutor
+	redirects map[AgentState]map[AgentState]AgentState
+}
+
+// NewPipeline validates cfg and binds its states to phases.
+//
+// Description:
+//
+//	Rejects configs the loop could not run: duplicate or undeclared
+//	states, phase names that are not in phases, custom states without a
+//	phase, redirects to states that are not allowed transitions, and
+//	states (including COMPLETE) that cannot be reached from Start. The
+//	error names the offending state.
+//
+// Inputs:
+//
+//	cfg - The pipeline config. Must not be nil.
+//	phases - Phase executors by registered name.
+//
+// Outputs:
+//
+//	*Pipeline - The pipeline.
+//	error - Wraps ErrInvalidPipeline if cfg is invalid.
+func NewPipeline(cfg *PipelineConfig, phases map[string]PhaseExecutor) (*Pipeline, error) {
+	if cfg == nil || len(cfg.States) == 0 {
+		return nil, fmt.Errorf("%w: no states declared", ErrInvalidPipeline)
+	}
+	invalid := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s", ErrInvalidPipeline, fmt.Sprintf(format, args...))
+	}
+
+	p := &Pipeline{
+		start:     normalizeState(cfg.Start, StateInit),
+		followUp:  normalizeState(cfg.FollowUp, StatePlan),
+		phases:    make(map[AgentState]PhaseExecutor),
+		redirects: make(map[AgentState]map[AgentState]AgentState),
+	}
+	declared := map[AgentState]bool{StateComplete: true, StateError: true}
+	for i, sc := range cfg.States {
+		name := normalizeState(sc.Name, "")
+		switch {
+		case name == "":
+			return nil, invalid("states[%d]: name is required", i)
+		case name == StateIdle:
+			return nil, invalid("states[%d]: IDLE is implicit and cannot be declared", i)
+		case name.IsTerminal():
+			return nil, invalid("states[%d]: %s is terminal and cannot be declared", i, name)
+		case declared[name]:
+			return nil, invalid("state %s is declared more than once", name)
+		}
+		declared[name] = true
+	}
+
+	sm := &StateMachine{transitions: make(map[AgentState]map[AgentState]bool)}
+	allow := func(from, to AgentState) {
+		if sm.transitions[from] == nil {
+			sm.transitions[from] = make(map[AgentState]bool)
+		}
+		sm.transitions[from][to] = true
+	}
+
+	for _, sc := range cfg.States {
+		name := normalizeState(sc.Name, "")
+		if sc.Phase != "" {
+			executor, ok := phases[sc.Phase]
+			if !ok || executor == nil {
+				return nil, invalid("state %s: phase %q is not registered (registered: %s)",
+					name, sc.Phase, strings.Join(sortedPhaseNames(phases), ", "))
+			}
+			p.phases[name] = executor
+		} else if !builtinPhaseStates[name] {
+			return nil, invalid("state %s: phase is required for a custom state", name)
+		}
+
+		for _, to := range sc.Transitions {
+			to = normalizeState(to, "")
+			if !declared[to] {
+				return nil, invalid("state %s: transition to undeclared state %q", name, to)
+			}
+			allow(name, to)
+		}
+		allow(name, StateError)
+
+		for result, to := range sc.Redirect {
+			result, to = normalizeState(result, ""), normalizeState(to, "")
+			if !sm.CanTransition(name, to) {
+				return nil, invalid("state %s: redirect %s -> %s targets a state not in transitions", name, result, to)
+			}
+			if p.redirects[name] == nil {
+				p.redirects[name] = make(map[AgentState]AgentState)
+			}
+			p.redirects[name][result] = to
+		}
+	}
+
+	for _, s := range []struct {
+		field string
+		state AgentState
+	}{{"start", p.start}, {"follow_up", p.followUp}} {
+		if !declared[s.state] || s.state.IsTerminal() {
+			return nil, invalid("%s state %s is not a declared non-terminal state", s.field, s.state)
+		}
+	}
+	allow(StateIdle, p.start)
+	allow(StateComplete, p.followUp)
+	p.machine = sm
+
+	if unreachable := p.unreachable(declared); len(unreachable) > 0 {
+		return nil, invalid("unreachable from %s: %s", p.start, strings.Join(unreachable, ", "))
+	}
+	return p, nil
+}
+
+// unreachable returns the declared states not reachable from the start
+// state, sorted.
+func (p *Pipeline) unreachable(declared map[AgentState]bool) []string {
+	seen := map[AgentState]bool{p.start: true}
+	queue := []AgentState{p.start}
+	for len(queue) > 0 {
+		from := queue[0]
+		queue = queue[1:]
+		for to := range p.machine.transitions[from] {
+			if !seen[to] {
+				seen[to] = true
+				queue = append(queue, to)
+			}
+		}
+	}
+	var out []string
+	for state := range declared {
+		// ERROR is reachable from every state.
+		if !seen[state] && state != StateError {
+			out = append(out, string(state))
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// GetPhase implements PhaseRegistry.
+//
+// Outputs:
+//
+//	PhaseExecutor - The phase configured for the state.
+//	bool - False if the state has no phase and uses the built-in transition.
+func (p *Pipeline) GetPhase(state AgentState) (PhaseExecutor, bool) {
+	executor, ok := p.phases[state]
+	return executor, ok
+}
+
+// Next applies the state's redirect to a phase result.
+//
+// Inputs:
+//
+//	from - The state whose phase ran.
+//	result - The state the phase returned.
+//
+// Outputs:
+//
+//	AgentState - The state to transition to.
+func (p *Pipeline) Next(from, result AgentState) AgentState {
+	if to, ok := p.redirects[from][result]; ok {
+		return to
+	}
+	return result
+}
+
+// Start returns the state a new query enters.
+func (p *Pipeline) Start() AgentState { return p.start }
+
+// FollowUp returns the state a follow-up question enters.
+func (p *Pipeline) FollowUp() AgentState { return p.followUp }
+
+// StateMachine returns the pipeline's transitions.
+func (p *Pipeline) StateMachine() *StateMachine { return p.machine }
+
+// normalizeState trims and upper-cases a configured state name, returning
+// def when it is empty.
+func normalizeState(s AgentState, def AgentState) AgentState {
+	s = AgentState(strings.ToUpper(strings.TrimSpace(string(s))))
+	if s == "" {
+		return def
+	}
+	return s
+}
+
+// sortedPhaseNames returns the keys of phases, sorted.
+func sortedPhaseNames(phases map[string]PhaseExecutor) []string {
+	names := make([]string, 0, len(phases))
+	for name := range phases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
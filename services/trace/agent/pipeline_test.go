@@ -0,0 +1,192 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// builtinMockPhases returns mock executors under the built-in phase names,
+// running the happy path INIT -> PLAN -> EXECUTE -> COMPLETE.
+func builtinMockPhases() map[string]PhaseExecutor {
+	return map[string]PhaseExecutor{
+		"init":    &MockPhase{name: "init", nextState: StatePlan},
+		"plan":    &MockPhase{name: "plan", nextState: StateExecute},
+		"execute": &MockPhase{name: "execute", nextState: StateComplete},
+		"reflect": &MockPhase{name: "reflect", nextState: StateComplete},
+		"clarify": &MockPhase{name: "clarify", nextState: StatePlan},
+	}
+}
+
+const verifyPipelineYAML = `
+start: init
+states:
+  - {name: INIT, phase: init, transitions: [PLAN, DEGRADED]}
+  - {name: PLAN, phase: plan, transitions: [EXECUTE, CLARIFY]}
+  - name: EXECUTE
+    phase: execute
+    transitions: [EXECUTE, REFLECT, VERIFY, CLARIFY]
+    redirect: {COMPLETE: VERIFY}
+  - {name: VERIFY, phase: verify, transitions: [COMPLETE, EXECUTE]}
+  - {name: REFLECT, phase: reflect, transitions: [EXECUTE, COMPLETE, CLARIFY]}
+  - {name: CLARIFY, phase: clarify, transitions: [PLAN]}
+  - {name: DEGRADED, transitions: [PLAN]}
+`
+
+func TestDefaultPipelineConfig_MatchesBuiltinStateMachine(t *testing.T) {
+	p, err := NewPipeline(DefaultPipelineConfig(), builtinMockPhases())
+	if err != nil {
+		t.Fatalf("NewPipeline(default) error = %v", err)
+	}
+	for _, from := range AllStates() {
+		for _, to := range AllStates() {
+			if got, want := p.StateMachine().CanTransition(from, to), DefaultStateMachine.CanTransition(from, to); got != want {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+	if _, ok := p.GetPhase(StateDegraded); ok {
+		t.Error("DEGRADED has a phase, want the built-in transition")
+	}
+}
+
+func TestNewPipeline_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"no states", `start: INIT`, "no states declared"},
+		{"unknown field", "states: []\nbogus: 1", "bogus"},
+		{"duplicate state", `
+states:
+  - {name: INIT, phase: init, transitions: [COMPLETE]}
+  - {name: INIT, phase: init, transitions: [COMPLETE]}`, "declared more than once"},
+		{"unregistered phase", `
+states:
+  - {name: INIT, phase: lint, transitions: [COMPLETE]}`, `phase "lint" is not registered`},
+		{"custom state without phase", `
+states:
+  - {name: INIT, phase: init, transitions: [VERIFY]}
+  - {name: VERIFY, transitions: [COMPLETE]}`, "phase is required"},
+		{"undeclared transition", `
+states:
+  - {name: INIT, phase: init, transitions: [PLAN]}`, `undeclared state "PLAN"`},
+		{"redirect outside transitions", `
+states:
+  - name: INIT
+    phase: init
+    transitions: [COMPLETE]
+    redirect: {COMPLETE: PLAN}
+  - {name: PLAN, phase: plan, transitions: [COMPLETE]}`, "not in transitions"},
+		{"unreachable state", `
+follow_up: INIT
+states:
+  - {name: INIT, phase: init, transitions: [COMPLETE]}
+  - {name: VERIFY, phase: init, transitions: [COMPLETE]}`, "unreachable from INIT: VERIFY"},
+		{"complete unreachable", `
+follow_up: INIT
+states:
+  - {name: INIT, phase: init, transitions: [INIT]}`, "unreachable from INIT: COMPLETE"},
+		{"terminal declared", `
+states:
+  - {name: COMPLETE, transitions: [PLAN]}`, "terminal"},
+		{"undeclared start", `
+start: PLAN
+states:
+  - {name: INIT, phase: init, transitions: [COMPLETE]}`, "start state PLAN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParsePipelineConfig([]byte(tt.yaml))
+			if err == nil {
+				_, err = NewPipeline(cfg, builtinMockPhases())
+			}
+			if !errors.Is(err, ErrInvalidPipeline) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want ErrInvalidPipeline containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultAgentLoop_WithPipeline_CustomPhase(t *testing.T) {
+	cfg, err := ParsePipelineConfig([]byte(verifyPipelineYAML))
+	if err != nil {
+		t.Fatalf("ParsePipelineConfig error = %v", err)
+	}
+	named := builtinMockPhases()
+	verify := &MockPhase{name: "verify", nextState: StateComplete}
+	named["verify"] = verify
+	p, err := NewPipeline(cfg, named)
+	if err != nil {
+		t.Fatalf("NewPipeline error = %v", err)
+	}
+	loop := NewDefaultAgentLoop(WithPipeline(p))
+
+	session, _ := NewSession("/test/project", nil)
+	result, err := loop.Run(context.Background(), session, "query")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.State != StateComplete {
+		t.Fatalf("State = %s, want COMPLETE", result.State)
+	}
+
+	var path []string
+	for _, entry := range session.GetHistory() {
+		if entry.Type == "state_transition" {
+			path = append(path, strings.SplitN(entry.Input, ":", 2)[0])
+		}
+	}
+	want := []string{"IDLE -> INIT", "INIT -> PLAN", "PLAN -> EXECUTE", "EXECUTE -> VERIFY", "VERIFY -> COMPLETE"}
+	if strings.Join(path, ",") != strings.Join(want, ",") {
+		t.Errorf("transitions = %v, want %v", path, want)
+	}
+
+	// A verify phase that sends the session somewhere it may not go fails
+	// the run rather than skipping the check.
+	verify.nextState = StateReflect
+	session, _ = NewSession("/test/project", nil)
+	result, err = loop.Run(context.Background(), session, "query")
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if result.State != StateError {
+		t.Errorf("State = %s, want ERROR for VERIFY -> REFLECT", result.State)
+	}
+}
+
+func TestLoadPipelineConfigFromEnv(t *testing.T) {
+	t.Setenv(PipelineEnvVar, "")
+	if cfg, path, err := LoadPipelineConfigFromEnv(); cfg != nil || path != "" || err != nil {
+		t.Errorf("unset: got %v, %q, %v", cfg, path, err)
+	}
+
+	file := filepath.Join(t.TempDir(), "pipeline.yaml")
+	if err := os.WriteFile(file, []byte(verifyPipelineYAML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(PipelineEnvVar, file)
+	cfg, path, err := LoadPipelineConfigFromEnv()
+	if err != nil || path != file || len(cfg.States) != 7 {
+		t.Errorf("set: got %d states, %q, %v", len(cfg.States), path, err)
+	}
+
+	t.Setenv(PipelineEnvVar, filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, _, err := LoadPipelineConfigFromEnv(); err == nil {
+		t.Error("missing file: expected an error")
+	}
+}
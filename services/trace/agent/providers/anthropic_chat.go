@@ -80,10 +80,12 @@ func (a *AnthropicChatAdapter) Chat(ctx context.Context, messages []Message, opt
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
+		setChatSpanUsage(span, opts.Model, duration, messages, "")
 		recordChatMetrics("anthropic", duration, err)
 		return "", err
 	}
 
+	setChatSpanUsage(span, opts.Model, duration, messages, result)
 	recordChatMetrics("anthropic", duration, nil)
 	return result, nil
 }
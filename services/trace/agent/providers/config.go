@@ -14,7 +14,9 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Provider constants for supported LLM providers.
@@ -23,6 +25,10 @@ const (
 	ProviderAnthropic = "anthropic"
 	ProviderOpenAI    = "openai"
 	ProviderGemini    = "gemini"
+
+	// ProviderReplay serves responses from a recorded fixture
+	// (TRACE_FIXTURE_PATH) instead of a live model.
+	ProviderReplay = "replay"
 )
 
 // Role constants for LLM roles in the Trace agent.
@@ -32,6 +38,24 @@ const (
 	RoleParamExtractor = "PARAM"
 )
 
+// Model residency defaults, used when TRACE_<ROLE>_KEEP_ALIVE or
+// TRACE_<ROLE>_NUM_CTX is not set. The context windows match what each
+// role requests at runtime: Ollama reloads a model whose num_ctx changes,
+// so warming with a different value would not save the cold start.
+const (
+	DefaultKeepAlive    = "24h"
+	DefaultMainNumCtx   = 65536
+	DefaultRouterNumCtx = 16384
+	DefaultParamNumCtx  = 4096
+)
+
+// defaultNumCtx maps each role to its default context window.
+var defaultNumCtx = map[string]int{
+	RoleMain:           DefaultMainNumCtx,
+	RoleRouter:         DefaultRouterNumCtx,
+	RoleParamExtractor: DefaultParamNumCtx,
+}
+
 // ProviderConfig holds the configuration for a single LLM provider instance.
 //
 // Description:
@@ -39,7 +63,8 @@ const (
 //	Specifies which provider to use, which model, and any provider-specific
 //	settings. Used by ProviderFactory to create the right adapter.
 type ProviderConfig struct {
-	// Provider is the backend to use: "ollama", "anthropic", "openai", "gemini".
+	// Provider is the backend to use: "ollama", "anthropic", "openai", "gemini",
+	// or "replay".
 	Provider string
 
 	// Model is the provider-specific model identifier.
@@ -56,10 +81,68 @@ type ProviderConfig struct {
 	APIKey string
 
 	// KeepAlive controls model VRAM lifetime (Ollama-specific).
+	// Loaded from TRACE_<ROLE>_KEEP_ALIVE, default DefaultKeepAlive.
 	KeepAlive string
 
 	// NumCtx sets the context window size (Ollama-specific).
+	// Loaded from TRACE_<ROLE>_NUM_CTX, default per role (DefaultMainNumCtx,
+	// DefaultRouterNumCtx, DefaultParamNumCtx).
 	NumCtx int
+
+	// ContextWindow is the model's context window in tokens, for the
+	// context window guard. Loaded from TRACE_<ROLE>_CONTEXT_WINDOW; zero
+	// derives it (see ContextWindowTokens).
+	ContextWindow int
+
+	// FixturePath is the recorded fixture to serve (replay-specific).
+	// Loaded from TRACE_FIXTURE_PATH.
+	FixturePath string
+}
+
+// WarmupOptions returns the options to warm this provider's model with.
+func (c ProviderConfig) WarmupOptions() WarmupOptions {
+	return WarmupOptions{KeepAlive: c.KeepAlive, NumCtx: c.NumCtx}
+}
+
+// knownContextWindows are the context windows of known cloud model
+// families, matched by model name prefix in order.
+var knownContextWindows = []struct {
+	prefix string
+	tokens int
+}{
+	{"claude-", 200000},
+	{"gpt-4.1", 1047576},
+	{"gpt-4o", 128000},
+	{"gpt-4-turbo", 128000},
+	{"gpt-5", 400000},
+	{"o1", 200000},
+	{"o3", 200000},
+	{"o4", 200000},
+	{"gemini-", 1048576},
+}
+
+// ContextWindowTokens returns the context window the guard budgets
+// prompts against.
+//
+// Description:
+//
+//	ContextWindow when set; for Ollama, NumCtx, since that is the window
+//	every request asks for; otherwise the window of a known model family.
+//	Zero means the window is unknown and prompts are not checked.
+func (c ProviderConfig) ContextWindowTokens() int {
+	if c.ContextWindow > 0 {
+		return c.ContextWindow
+	}
+	if c.Provider == ProviderOllama && c.NumCtx > 0 {
+		return c.NumCtx
+	}
+	model := strings.ToLower(c.Model)
+	for _, known := range knownContextWindows {
+		if strings.HasPrefix(model, known.prefix) {
+			return known.tokens
+		}
+	}
+	return 0
 }
 
 // RoleConfig holds per-role provider configurations.
@@ -72,10 +155,18 @@ type RoleConfig struct {
 	Main           ProviderConfig
 	Router         ProviderConfig
 	ParamExtractor ProviderConfig
+
+	// MainEnsemble lists the providers of the Main role's ensemble, from
+	// MAIN_ENSEMBLE. Empty when no ensemble is configured.
+	MainEnsemble []ProviderConfig
 }
 
+// MainEnsembleEnv names the environment variable listing the Main role's
+// ensemble members as comma-separated provider:model pairs.
+const MainEnsembleEnv = "MAIN_ENSEMBLE"
+
 // ValidProviders contains the set of valid provider names.
-var ValidProviders = []string{ProviderOllama, ProviderAnthropic, ProviderOpenAI, ProviderGemini}
+var ValidProviders = []string{ProviderOllama, ProviderAnthropic, ProviderOpenAI, ProviderGemini, ProviderReplay}
 
 // isValidProvider checks if a provider name is valid.
 func isValidProvider(provider string) bool {
@@ -167,30 +258,39 @@ func MergeSessionOverrides(base *RoleConfig, mainModel, routerModel, paramModel
 	// Copy all fields explicitly (lesson J-7: no implicit field forwarding)
 	merged := &RoleConfig{
 		Main: ProviderConfig{
-			Provider:  base.Main.Provider,
-			Model:     base.Main.Model,
-			BaseURL:   base.Main.BaseURL,
-			APIKey:    base.Main.APIKey,
-			KeepAlive: base.Main.KeepAlive,
-			NumCtx:    base.Main.NumCtx,
+			Provider:      base.Main.Provider,
+			Model:         base.Main.Model,
+			BaseURL:       base.Main.BaseURL,
+			APIKey:        base.Main.APIKey,
+			KeepAlive:     base.Main.KeepAlive,
+			NumCtx:        base.Main.NumCtx,
+			ContextWindow: base.Main.ContextWindow,
+			FixturePath:   base.Main.FixturePath,
 		},
 		Router: ProviderConfig{
-			Provider:  base.Router.Provider,
-			Model:     base.Router.Model,
-			BaseURL:   base.Router.BaseURL,
-			APIKey:    base.Router.APIKey,
-			KeepAlive: base.Router.KeepAlive,
-			NumCtx:    base.Router.NumCtx,
+			Provider:      base.Router.Provider,
+			Model:         base.Router.Model,
+			BaseURL:       base.Router.BaseURL,
+			APIKey:        base.Router.APIKey,
+			KeepAlive:     base.Router.KeepAlive,
+			NumCtx:        base.Router.NumCtx,
+			ContextWindow: base.Router.ContextWindow,
+			FixturePath:   base.Router.FixturePath,
 		},
 		ParamExtractor: ProviderConfig{
-			Provider:  base.ParamExtractor.Provider,
-			Model:     base.ParamExtractor.Model,
-			BaseURL:   base.ParamExtractor.BaseURL,
-			APIKey:    base.ParamExtractor.APIKey,
-			KeepAlive: base.ParamExtractor.KeepAlive,
-			NumCtx:    base.ParamExtractor.NumCtx,
+			Provider:      base.ParamExtractor.Provider,
+			Model:         base.ParamExtractor.Model,
+			BaseURL:       base.ParamExtractor.BaseURL,
+			APIKey:        base.ParamExtractor.APIKey,
+			KeepAlive:     base.ParamExtractor.KeepAlive,
+			NumCtx:        base.ParamExtractor.NumCtx,
+			ContextWindow: base.ParamExtractor.ContextWindow,
+			FixturePath:   base.ParamExtractor.FixturePath,
 		},
 	}
+	if len(base.MainEnsemble) > 0 {
+		merged.MainEnsemble = append([]ProviderConfig(nil), base.MainEnsemble...)
+	}
 
 	if mainModel != "" {
 		merged.Main.Model = mainModel
@@ -219,6 +319,9 @@ func MergeSessionOverrides(base *RoleConfig, mainModel, routerModel, paramModel
 //  3. TRACE_<ROLE>_MODEL -> explicit model
 //  4. Fallback: existing env vars (OLLAMA_MODEL for main, session config for router/param)
 //
+// TRACE_<ROLE>_KEEP_ALIVE and TRACE_<ROLE>_NUM_CTX set how long the role's
+// model stays loaded and its context window, for warmup and for requests.
+//
 // Inputs:
 //   - mainModelFallback: Fallback model for the main role (e.g., from OLLAMA_MODEL).
 //   - routerModelFallback: Fallback model for the router role (e.g., from SessionConfig).
@@ -226,7 +329,8 @@ func MergeSessionOverrides(base *RoleConfig, mainModel, routerModel, paramModel
 //
 // Outputs:
 //   - *RoleConfig: Per-role configurations.
-//   - error: Non-nil if an invalid provider is specified.
+//   - error: Non-nil if an invalid provider, keep-alive or context window
+//     is specified.
 //
 // Example:
 //
@@ -247,13 +351,130 @@ func LoadRoleConfig(mainModelFallback, routerModelFallback, paramModelFallback s
 		return nil, fmt.Errorf("loading param extractor role config: %w", err)
 	}
 
+	ensemble, err := ParseEnsembleSpec(os.Getenv(MainEnsembleEnv))
+	if err != nil {
+		return nil, fmt.Errorf("loading %s: %w", MainEnsembleEnv, err)
+	}
+
 	return &RoleConfig{
 		Main:           mainCfg,
 		Router:         routerCfg,
 		ParamExtractor: paramCfg,
+		MainEnsemble:   ensemble,
 	}, nil
 }
 
+// ParseEnsembleSpec parses an ensemble specification.
+//
+// Description:
+//
+//	The spec is a comma-separated list of provider:model pairs, for
+//	example "ollama:glm-4.7-flash,anthropic:claude-sonnet-4-20250514".
+//	The model is everything after the first colon, so Ollama tags such
+//	as "granite4:micro-h" work. Provider settings (API keys, Ollama URL,
+//	fixture path) are read from the environment as for a single role.
+//
+// Inputs:
+//   - spec: The specification. Empty means no ensemble.
+//
+// Outputs:
+//   - []ProviderConfig: The members in spec order, or nil for an empty spec.
+//   - error: Non-nil for a malformed entry, an unknown provider, a
+//     duplicate member, or fewer than two members.
+func ParseEnsembleSpec(spec string) ([]ProviderConfig, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	var members []ProviderConfig
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		provider, model, ok := strings.Cut(entry, ":")
+		provider, model = strings.TrimSpace(provider), strings.TrimSpace(model)
+		if !ok || provider == "" || model == "" {
+			return nil, fmt.Errorf("entry %q must be provider:model", entry)
+		}
+		if !isValidProvider(provider) {
+			return nil, fmt.Errorf("invalid provider %q in entry %q (valid: %v)", provider, entry, ValidProviders)
+		}
+		if seen[provider+":"+model] {
+			return nil, fmt.Errorf("duplicate entry %q", entry)
+		}
+		seen[provider+":"+model] = true
+
+		cfg := ProviderConfig{Provider: provider, Model: model}
+		if err := applyProviderSettings(&cfg); err != nil {
+			return nil, fmt.Errorf("entry %q: %w", entry, err)
+		}
+		members = append(members, cfg)
+	}
+
+	if len(members) < 2 {
+		return nil, fmt.Errorf("an ensemble needs at least two members, got %d", len(members))
+	}
+	return members, nil
+}
+
+// applyProviderSettings fills in the provider-specific settings of cfg
+// from the environment.
+func applyProviderSettings(cfg *ProviderConfig) error {
+	switch cfg.Provider {
+	case ProviderOllama:
+		cfg.BaseURL = ResolveOllamaURL()
+	case ProviderAnthropic:
+		cfg.APIKey = os.Getenv("ANTHROPIC_API_KEY")
+	case ProviderOpenAI:
+		cfg.APIKey = os.Getenv("OPENAI_API_KEY")
+	case ProviderGemini:
+		cfg.APIKey = os.Getenv("GEMINI_API_KEY")
+	case ProviderReplay:
+		cfg.FixturePath = os.Getenv("TRACE_FIXTURE_PATH")
+		if cfg.FixturePath == "" {
+			return fmt.Errorf("provider is %q but TRACE_FIXTURE_PATH is not set", cfg.Provider)
+		}
+	}
+	return nil
+}
+
+// applyResidencySettings sets the keep-alive and context window of a role
+// from TRACE_<ROLE>_KEEP_ALIVE and TRACE_<ROLE>_NUM_CTX, or the defaults,
+// and the guard's window from TRACE_<ROLE>_CONTEXT_WINDOW.
+func applyResidencySettings(cfg *ProviderConfig, role string) error {
+	keepAliveEnv := fmt.Sprintf("TRACE_%s_KEEP_ALIVE", role)
+	cfg.KeepAlive = DefaultKeepAlive
+	if v := os.Getenv(keepAliveEnv); v != "" {
+		if _, err := time.ParseDuration(v); err != nil {
+			return fmt.Errorf("%s: invalid duration %q: %w", keepAliveEnv, v, err)
+		}
+		cfg.KeepAlive = v
+	}
+
+	numCtxEnv := fmt.Sprintf("TRACE_%s_NUM_CTX", role)
+	cfg.NumCtx = defaultNumCtx[role]
+	if v := os.Getenv(numCtxEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("%s: must be a positive integer, got %q", numCtxEnv, v)
+		}
+		cfg.NumCtx = n
+	}
+
+	windowEnv := fmt.Sprintf("TRACE_%s_CONTEXT_WINDOW", role)
+	if v := os.Getenv(windowEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("%s: must be a positive integer, got %q", windowEnv, v)
+		}
+		cfg.ContextWindow = n
+	}
+	return nil
+}
+
 // loadSingleRoleConfig loads configuration for a single role.
 func loadSingleRoleConfig(role, modelFallback string) (ProviderConfig, error) {
 	providerEnv := fmt.Sprintf("TRACE_%s_PROVIDER", role)
@@ -279,15 +500,15 @@ func loadSingleRoleConfig(role, modelFallback string) (ProviderConfig, error) {
 	}
 
 	// Load provider-specific settings
-	switch provider {
-	case ProviderOllama:
-		cfg.BaseURL = ResolveOllamaURL()
-	case ProviderAnthropic:
-		cfg.APIKey = os.Getenv("ANTHROPIC_API_KEY")
-	case ProviderOpenAI:
-		cfg.APIKey = os.Getenv("OPENAI_API_KEY")
-	case ProviderGemini:
-		cfg.APIKey = os.Getenv("GEMINI_API_KEY")
+	if err := applyProviderSettings(&cfg); err != nil {
+		return ProviderConfig{}, fmt.Errorf("%s: %w", providerEnv, err)
+	}
+	if err := applyResidencySettings(&cfg, role); err != nil {
+		return ProviderConfig{}, err
+	}
+	// Replayed requests are matched by prompt, not model.
+	if provider == ProviderReplay && cfg.Model == "" {
+		cfg.Model = ProviderReplay
 	}
 
 	// Validate: if provider is explicitly set but model is empty and no fallback was provided,
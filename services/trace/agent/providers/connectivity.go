@@ -0,0 +1,124 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Default API roots used by CheckConnectivity for cloud providers.
+const (
+	anthropicAPIRoot = "https://api.anthropic.com/v1"
+	openAIAPIRoot    = "https://api.openai.com/v1"
+	geminiAPIRoot    = "https://generativelanguage.googleapis.com/v1beta"
+)
+
+// CheckConnectivity verifies that a provider is reachable and accepts the
+// configured credentials.
+//
+// Description:
+//
+//	Issues one cheap read-only request that generates no tokens: Ollama's
+//	GET /api/version, and the model-list endpoint of the cloud providers
+//	(which also validates the API key). Intended for readiness probes;
+//	callers should cache the result rather than calling it per request.
+//	For cloud providers BaseURL, when set, replaces the API root (for
+//	example "https://api.openai.com/v1"); OpenAI also honors
+//	OPENAI_BASE_URL like its client does.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and deadline.
+//	client - HTTP client to use. Nil uses http.DefaultClient.
+//	cfg - The role's provider configuration.
+//
+// Outputs:
+//
+//	error - Nil if the provider answered with 2xx. Otherwise describes
+//	        the failure (unreachable, rejected credentials, bad status).
+//
+// Thread Safety: Safe for concurrent use.
+func CheckConnectivity(ctx context.Context, client *http.Client, cfg ProviderConfig) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	var (
+		url     string
+		headers = map[string]string{}
+	)
+	switch cfg.Provider {
+	case ProviderReplay:
+		// Fixtures are loaded when the client is created.
+		return nil
+	case ProviderOllama:
+		base := cfg.BaseURL
+		if base == "" {
+			base = ResolveOllamaURL()
+		}
+		url = strings.TrimRight(base, "/") + "/api/version"
+	case ProviderAnthropic:
+		url = apiRoot(cfg.BaseURL, anthropicAPIRoot) + "/models?limit=1"
+		headers["x-api-key"] = cfg.APIKey
+		headers["anthropic-version"] = "2023-06-01"
+	case ProviderOpenAI:
+		root := cfg.BaseURL
+		if root == "" {
+			root = strings.TrimSuffix(os.Getenv("OPENAI_BASE_URL"), "/chat/completions")
+		}
+		url = apiRoot(root, openAIAPIRoot) + "/models"
+		headers["Authorization"] = "Bearer " + cfg.APIKey
+	case ProviderGemini:
+		url = apiRoot(cfg.BaseURL, geminiAPIRoot) + "/models?pageSize=1"
+		headers["x-goog-api-key"] = cfg.APIKey
+	default:
+		return fmt.Errorf("unknown provider %q", cfg.Provider)
+	}
+	if cfg.Provider != ProviderOllama && cfg.APIKey == "" {
+		return fmt.Errorf("%s: API key not configured", cfg.Provider)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: creating request: %w", cfg.Provider, err)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s unreachable: %w", cfg.Provider, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%s rejected the API key (status %d)", cfg.Provider, resp.StatusCode)
+	default:
+		return fmt.Errorf("%s returned status %d", cfg.Provider, resp.StatusCode)
+	}
+}
+
+// apiRoot returns override without a trailing slash, or def if empty.
+func apiRoot(override, def string) string {
+	if override == "" {
+		return def
+	}
+	return strings.TrimRight(override, "/")
+}
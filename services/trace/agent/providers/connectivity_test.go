@@ -0,0 +1,82 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCheckConnectivity_Ollama(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		_, _ = w.Write([]byte(`{"version":"0.5.0"}`))
+	}))
+	defer srv.Close()
+
+	err := CheckConnectivity(context.Background(), srv.Client(), ProviderConfig{Provider: ProviderOllama, BaseURL: srv.URL + "/"})
+	if err != nil {
+		t.Fatalf("CheckConnectivity: %v", err)
+	}
+	if gotPath != "/api/version" {
+		t.Errorf("path = %q, want /api/version", gotPath)
+	}
+
+	srv.Close()
+	err = CheckConnectivity(context.Background(), nil, ProviderConfig{Provider: ProviderOllama, BaseURL: srv.URL})
+	if err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Errorf("err = %v, want unreachable", err)
+	}
+}
+
+func TestCheckConnectivity_Cloud(t *testing.T) {
+	var gotKey string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKey = r.Header.Get("x-api-key")
+		if gotKey != "good" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v1/models" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	cfg := ProviderConfig{Provider: ProviderAnthropic, BaseURL: srv.URL + "/v1", APIKey: "good"}
+	if err := CheckConnectivity(context.Background(), srv.Client(), cfg); err != nil {
+		t.Fatalf("CheckConnectivity: %v", err)
+	}
+
+	cfg.APIKey = "bad"
+	err := CheckConnectivity(context.Background(), srv.Client(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "rejected the API key") {
+		t.Errorf("err = %v, want rejected API key", err)
+	}
+
+	cfg.APIKey = ""
+	err = CheckConnectivity(context.Background(), srv.Client(), cfg)
+	if err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Errorf("err = %v, want missing key", err)
+	}
+}
+
+func TestCheckConnectivity_UnknownProvider(t *testing.T) {
+	if err := CheckConnectivity(context.Background(), nil, ProviderConfig{Provider: "acme"}); err == nil {
+		t.Error("expected error for unknown provider")
+	}
+}
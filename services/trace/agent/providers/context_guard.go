@@ -0,0 +1,119 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+)
+
+// contextGuardClient keeps prompts within the model's context window.
+//
+// Description:
+//
+//	Every request is fitted with agentllm.ContextWindow.Fit before it is
+//	sent: code context blocks are dropped, then older history condensed,
+//	until the estimated prompt fits. A prompt that still does not fit is
+//	never sent; the call fails with *agentllm.ContextOverflowError.
+//	Every reduction, and every overflow, is emitted as a
+//	context_reduction event for the session in the context.
+//
+// Thread Safety: Safe for concurrent use if the inner client is.
+type contextGuardClient struct {
+	inner    agentllm.Client
+	provider string
+	window   int
+	emitter  *events.Emitter
+	logger   *slog.Logger
+}
+
+// newContextGuardClient wraps inner with a guard for cfg's context window.
+// Returns inner unchanged when the window is unknown.
+func newContextGuardClient(inner agentllm.Client, cfg ProviderConfig, emitter *events.Emitter, logger *slog.Logger) agentllm.Client {
+	window := cfg.ContextWindowTokens()
+	if window <= 0 {
+		logger.Info("Context window unknown, prompts are not checked",
+			slog.String("provider", cfg.Provider),
+			slog.String("model", cfg.Model))
+		return inner
+	}
+	return &contextGuardClient{
+		inner:    inner,
+		provider: cfg.Provider,
+		window:   window,
+		emitter:  emitter,
+		logger:   logger,
+	}
+}
+
+// Complete implements agentllm.Client.
+func (c *contextGuardClient) Complete(ctx context.Context, request *agentllm.Request) (*agentllm.Response, error) {
+	model := c.inner.Model()
+	if request != nil && request.ModelOverride != "" {
+		model = request.ModelOverride
+	}
+	fit, err := agentllm.ContextWindow{
+		Model:         model,
+		Tokens:        c.window,
+		CharsPerToken: agentllm.CharsPerToken(c.provider),
+	}.Fit(request)
+	if len(fit.Reductions) > 0 || err != nil {
+		c.record(ctx, model, fit, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return c.inner.Complete(ctx, fit.Request)
+}
+
+// record logs the reductions of one call and emits them as an event.
+func (c *contextGuardClient) record(ctx context.Context, model string, fit agentllm.ContextFit, err error) {
+	var overflow *agentllm.ContextOverflowError
+	isOverflow := errors.As(err, &overflow)
+	actions := make([]string, 0, len(fit.Reductions))
+	for _, r := range fit.Reductions {
+		actions = append(actions, r.Action)
+	}
+	sessionID := agent.SessionIDFromContext(ctx)
+	c.logger.WarnContext(ctx, "Prompt reduced to fit the context window",
+		slog.String("session_id", sessionID),
+		slog.String("model", model),
+		slog.Int("estimated_tokens", fit.EstimatedTokens),
+		slog.Int("reserved_tokens", fit.ReservedTokens),
+		slog.Int("limit_tokens", c.window),
+		slog.Any("reductions", actions),
+		slog.Bool("overflow", isOverflow))
+
+	if c.emitter == nil {
+		return
+	}
+	c.emitter.Emit(events.TypeContextReduction, &events.ContextReductionData{
+		SessionID:       sessionID,
+		Provider:        c.provider,
+		Model:           model,
+		LimitTokens:     c.window,
+		EstimatedTokens: fit.EstimatedTokens,
+		ReservedTokens:  fit.ReservedTokens,
+		Reductions:      fit.Reductions,
+		Overflow:        isOverflow,
+	})
+}
+
+// Name implements agentllm.Client.
+func (c *contextGuardClient) Name() string { return c.inner.Name() }
+
+// Model implements agentllm.Client.
+func (c *contextGuardClient) Model() string { return c.inner.Model() }
@@ -0,0 +1,127 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+)
+
+// guardedRequest has two code blocks and a long history.
+func guardedRequest() *agentllm.Request {
+	return agentllm.BuildRequest(&agent.AssembledContext{
+		CodeContext: []agent.CodeEntry{
+			{FilePath: "keep.go", Content: strings.Repeat("k", 4000), Relevance: 0.8},
+			{FilePath: "drop.go", Content: strings.Repeat("d", 4000), Relevance: 0.2},
+		},
+		ConversationHistory: []agent.Message{
+			{Role: "user", Content: "Explain the cache"},
+			{Role: "assistant", Content: strings.Repeat("old ", 4000)},
+			{Role: "user", Content: "And eviction?"},
+		},
+	}, nil, 200)
+}
+
+func TestContextGuardClient(t *testing.T) {
+	emitter := events.NewEmitter()
+	var got []*events.ContextReductionData
+	emitter.Subscribe(func(e *events.Event) {
+		got = append(got, e.Data.(*events.ContextReductionData))
+	}, events.TypeContextReduction)
+
+	fake := agentllm.NewMockClient().WithName(ProviderOpenAI).WithModel("gpt-4o")
+	fake.SetDefaultResponse(&agentllm.Response{Content: "ok"})
+	ctx := agent.ContextWithSessionID(context.Background(), "sess-1")
+
+	t.Run("reduces in order", func(t *testing.T) {
+		got = nil
+		// ~8000 chars of code and ~16000 of history at 4 chars/token.
+		client := newContextGuardClient(fake, ProviderConfig{Provider: ProviderOpenAI, Model: "gpt-4o", ContextWindow: 3000}, emitter, slog.Default())
+		if _, err := client.Complete(ctx, guardedRequest()); err != nil {
+			t.Fatal(err)
+		}
+		sent := fake.LastRequest()
+		if strings.Contains(sent.Messages[0].Content, "drop.go") || sent.Messages[0].Role != "user" {
+			t.Errorf("first message = %.60q, want drop.go gone", sent.Messages[0].Content)
+		}
+		if last := sent.Messages[len(sent.Messages)-1]; last.Content != "And eviction?" {
+			t.Errorf("last message = %q, want the newest turn verbatim", last.Content)
+		}
+		if len(got) != 1 || got[0].SessionID != "sess-1" || got[0].Overflow || got[0].LimitTokens != 3000 {
+			t.Fatalf("events = %+v", got)
+		}
+		r := got[0].Reductions
+		if len(r) != 2 || r[0].Action != agent.ReductionDropContextBlocks || r[1].Action != agent.ReductionSummarizeHistory {
+			t.Fatalf("reductions = %+v, want blocks dropped, then history summarized", r)
+		}
+		if r[0].Items[0] != "drop.go" {
+			t.Errorf("first dropped block = %q, want drop.go", r[0].Items[0])
+		}
+		if r[0].TokensAfter >= r[0].TokensBefore || r[1].TokensAfter >= r[1].TokensBefore {
+			t.Errorf("reductions did not shrink the prompt: %+v", r)
+		}
+	})
+
+	t.Run("overflow is not sent", func(t *testing.T) {
+		got = nil
+		fake.Reset()
+		fake.SetDefaultResponse(&agentllm.Response{Content: "ok"})
+		client := newContextGuardClient(fake, ProviderConfig{Provider: ProviderOpenAI, Model: "gpt-4o", ContextWindow: 500}, emitter, slog.Default())
+		request := guardedRequest()
+		request.SystemPrompt = strings.Repeat("rules ", 1000)
+		_, err := client.Complete(ctx, request)
+		var overflow *agentllm.ContextOverflowError
+		if !errors.As(err, &overflow) || !errors.Is(err, agent.ErrContextOverflow) {
+			t.Fatalf("error = %v, want CONTEXT_OVERFLOW", err)
+		}
+		if overflow.LimitTokens != 500 || overflow.EstimatedTokens <= 500 || overflow.Model != "gpt-4o" {
+			t.Errorf("overflow = %+v", overflow)
+		}
+		if fake.CallCount() != 0 {
+			t.Error("overflowing prompt was sent to the provider")
+		}
+		if len(got) != 1 || !got[0].Overflow || got[0].EstimatedTokens != overflow.EstimatedTokens || len(got[0].Reductions) != 2 {
+			t.Errorf("events = %+v, want one overflow event with both reductions", got)
+		}
+	})
+
+	t.Run("unknown window", func(t *testing.T) {
+		if client := newContextGuardClient(fake, ProviderConfig{Provider: ProviderOpenAI, Model: "custom"}, emitter, slog.Default()); client != agentllm.Client(fake) {
+			t.Error("client wrapped without a known context window")
+		}
+	})
+}
+
+func TestProviderConfig_ContextWindowTokens(t *testing.T) {
+	tests := []struct {
+		cfg  ProviderConfig
+		want int
+	}{
+		{ProviderConfig{Provider: ProviderAnthropic, Model: "claude-sonnet-4-20250514", NumCtx: DefaultMainNumCtx}, 200000},
+		{ProviderConfig{Provider: ProviderOpenAI, Model: "gpt-4o-mini"}, 128000},
+		{ProviderConfig{Provider: ProviderGemini, Model: "gemini-2.5-pro"}, 1048576},
+		{ProviderConfig{Provider: ProviderOllama, Model: "llama3", NumCtx: 8192}, 8192},
+		{ProviderConfig{Provider: ProviderOllama, Model: "llama3", NumCtx: 8192, ContextWindow: 4096}, 4096},
+		{ProviderConfig{Provider: ProviderOpenAI, Model: "my-finetune"}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.cfg.ContextWindowTokens(); got != tt.want {
+			t.Errorf("%s/%s window = %d, want %d", tt.cfg.Provider, tt.cfg.Model, got, tt.want)
+		}
+	}
+}
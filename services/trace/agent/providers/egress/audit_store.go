@@ -0,0 +1,250 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package egress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	dgbadger "github.com/dgraph-io/badger/v4"
+)
+
+// auditKeyPrefix namespaces audit records in a shared BadgerDB.
+const auditKeyPrefix = "egress/audit/v1/"
+
+// Audit query limits.
+const (
+	DefaultAuditQueryLimit = 100
+	MaxAuditQueryLimit     = 1000
+)
+
+// Audit decisions recorded in AuditRecord.Decision.
+const (
+	AuditDecisionAllowed  = "allowed"
+	AuditDecisionRedacted = "redacted"
+	AuditDecisionBlocked  = "blocked"
+)
+
+// Content block kinds recorded in BlockAudit.Kind.
+const (
+	BlockKindSystem     = "system"
+	BlockKindMessage    = "message"
+	BlockKindToolCall   = "tool_call"
+	BlockKindToolResult = "tool_result"
+)
+
+// BlockAudit is the classification of one content block of a request.
+type BlockAudit struct {
+	// Index is the position of the block in the serialized request.
+	Index int `json:"index"`
+
+	// Kind is one of system, message, tool_call, or tool_result.
+	Kind string `json:"kind"`
+
+	// Role is the message role, if the block belongs to a message.
+	Role string `json:"role,omitempty"`
+
+	// Sensitivity is the classification of the original block.
+	Sensitivity string `json:"sensitivity"`
+
+	// Bytes is the size of the original block.
+	Bytes int `json:"bytes"`
+
+	// SentBytes is the size of the block as sent (after redaction).
+	SentBytes int `json:"sent_bytes"`
+
+	// Redactions lists the spans replaced in this block.
+	Redactions []RedactionAction `json:"redactions,omitempty"`
+}
+
+// AuditRecord is the persisted audit entry for one outbound LLM request.
+// It never contains message content.
+type AuditRecord struct {
+	RequestID     string       `json:"request_id"`
+	SessionID     string       `json:"session_id"`
+	Timestamp     int64        `json:"timestamp"`
+	Provider      string       `json:"provider"`
+	Model         string       `json:"model"`
+	Mode          string       `json:"mode"`
+	Decision      string       `json:"decision"`
+	BlockedBy     string       `json:"blocked_by,omitempty"`
+	BlockReason   string       `json:"block_reason,omitempty"`
+	Sensitivity   string       `json:"sensitivity"`
+	ContentHash   string       `json:"content_hash,omitempty"`
+	Blocks        []BlockAudit `json:"blocks"`
+	BytesIn       int          `json:"bytes_in"`
+	BytesSent     int          `json:"bytes_sent"`
+	BytesReceived int          `json:"bytes_received"`
+	InputTokens   int          `json:"input_tokens"`
+	OutputTokens  int          `json:"output_tokens"`
+	CostCents     float64      `json:"cost_cents"`
+	DurationMs    int64        `json:"duration_ms"`
+	Status        string       `json:"status"`
+	Error         string       `json:"error,omitempty"`
+}
+
+// RedactionCount returns the total number of redactions across all blocks.
+func (r *AuditRecord) RedactionCount() int {
+	n := 0
+	for _, b := range r.Blocks {
+		n += len(b.Redactions)
+	}
+	return n
+}
+
+// AuditQuery filters audit records.
+type AuditQuery struct {
+	// Since excludes records before this time. Zero means no lower bound.
+	Since time.Time
+
+	// Until excludes records after this time. Zero means no upper bound.
+	Until time.Time
+
+	// SessionID restricts results to one session. Empty matches all.
+	SessionID string
+
+	// Limit caps the number of records. <= 0 uses DefaultAuditQueryLimit;
+	// values above MaxAuditQueryLimit are clamped.
+	Limit int
+}
+
+// AuditStore persists egress audit records.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type AuditStore interface {
+	// Append persists a record.
+	Append(ctx context.Context, record *AuditRecord) error
+
+	// Query returns matching records, newest first.
+	Query(ctx context.Context, q AuditQuery) ([]AuditRecord, error)
+}
+
+// BadgerAuditStore implements AuditStore backed by BadgerDB.
+//
+// Description:
+//
+//	Records are keyed by timestamp so time-range queries are a single
+//	range scan. Retention is enforced through BadgerDB's native TTL. The
+//	DB is owned by the caller and must stay open while the store is in use.
+//
+// Thread Safety: Safe for concurrent use.
+type BadgerAuditStore struct {
+	db        *badgerstore.DB
+	retention time.Duration
+}
+
+// NewBadgerAuditStore creates a store backed by db.
+//
+// Inputs:
+//   - db: Opened BadgerDB wrapper. Must not be nil.
+//   - retention: Lifetime of each record. <= 0 uses DefaultAuditRetention.
+//
+// Outputs:
+//   - *BadgerAuditStore: The store. Never nil.
+func NewBadgerAuditStore(db *badgerstore.DB, retention time.Duration) *BadgerAuditStore {
+	if db == nil {
+		panic("NewBadgerAuditStore: db must not be nil")
+	}
+	if retention <= 0 {
+		retention = DefaultAuditRetention
+	}
+	return &BadgerAuditStore{db: db, retention: retention}
+}
+
+// Append implements AuditStore.
+func (s *BadgerAuditStore) Append(ctx context.Context, record *AuditRecord) error {
+	if record == nil {
+		return fmt.Errorf("egress audit append: record must not be nil")
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("egress audit encode: %w", err)
+	}
+	key := auditKey(time.UnixMilli(record.Timestamp), record.RequestID)
+	err = s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.SetEntry(dgbadger.NewEntry(key, value).WithTTL(s.retention))
+	})
+	if err != nil {
+		return fmt.Errorf("egress audit append: %w", err)
+	}
+	return nil
+}
+
+// Query implements AuditStore.
+func (s *BadgerAuditStore) Query(ctx context.Context, q AuditQuery) ([]AuditRecord, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultAuditQueryLimit
+	}
+	if limit > MaxAuditQueryLimit {
+		limit = MaxAuditQueryLimit
+	}
+
+	seek := []byte(auditKeyPrefix + "\xff")
+	if !q.Until.IsZero() {
+		seek = []byte(auditKeyPrefix + auditTimeKey(q.Until) + "/\xff")
+	}
+	var since string
+	if !q.Since.IsZero() {
+		since = auditTimeKey(q.Since)
+	}
+
+	records := make([]AuditRecord, 0)
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		opts := dgbadger.DefaultIteratorOptions
+		opts.Reverse = true
+		opts.Prefix = []byte(auditKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Seek(seek); it.ValidForPrefix(opts.Prefix) && len(records) < limit; it.Next() {
+			key := strings.TrimPrefix(string(it.Item().Key()), auditKeyPrefix)
+			if since != "" && key < since {
+				break
+			}
+			var record AuditRecord
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &record)
+			}); err != nil {
+				return err
+			}
+			if q.SessionID != "" && record.SessionID != q.SessionID {
+				continue
+			}
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("egress audit query: %w", err)
+	}
+	return records, nil
+}
+
+// auditKey builds the key for a record. The zero-padded hex timestamp keeps
+// lexicographic and chronological order aligned.
+func auditKey(ts time.Time, requestID string) []byte {
+	return []byte(auditKeyPrefix + auditTimeKey(ts) + "/" + requestID)
+}
+
+// auditTimeKey encodes a timestamp as fixed-width hex nanoseconds.
+func auditTimeKey(ts time.Time) string {
+	n := ts.UnixNano()
+	if n < 0 {
+		n = 0
+	}
+	s := strconv.FormatInt(n, 16)
+	return strings.Repeat("0", 16-len(s)) + s
+}
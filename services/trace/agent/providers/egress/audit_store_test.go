@@ -0,0 +1,79 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package egress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+)
+
+func newTestAuditStore(t *testing.T) *BadgerAuditStore {
+	t.Helper()
+	db, err := badgerstore.OpenDB(badgerstore.InMemoryConfig())
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewBadgerAuditStore(db, time.Hour)
+}
+
+func TestBadgerAuditStore_Query(t *testing.T) {
+	store := newTestAuditStore(t)
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, sess := range []string{"a", "b", "a", "a"} {
+		err := store.Append(ctx, &AuditRecord{
+			RequestID: string(rune('w' + i)),
+			SessionID: sess,
+			Timestamp: base.Add(time.Duration(i) * time.Minute).UnixMilli(),
+			Provider:  "anthropic",
+			Decision:  AuditDecisionAllowed,
+		})
+		if err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		q    AuditQuery
+		want []string
+	}{
+		{"all newest first", AuditQuery{}, []string{"z", "y", "x", "w"}},
+		{"session filter", AuditQuery{SessionID: "a"}, []string{"z", "y", "w"}},
+		{"time range", AuditQuery{Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)}, []string{"y", "x"}},
+		{"limit", AuditQuery{SessionID: "a", Limit: 2}, []string{"z", "y"}},
+		{"empty range", AuditQuery{Since: base.Add(time.Hour)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.Query(ctx, tt.q)
+			if err != nil {
+				t.Fatalf("Query: %v", err)
+			}
+			if got == nil {
+				t.Fatal("Query should return a non-nil slice")
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d records, want %v", len(got), tt.want)
+			}
+			for i, id := range tt.want {
+				if got[i].RequestID != id {
+					t.Errorf("record %d = %q, want %q", i, got[i].RequestID, id)
+				}
+			}
+		})
+	}
+}
@@ -15,9 +15,12 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // EgressAuditor produces structured audit log entries for egress events (P-5/P-6).
@@ -29,11 +32,17 @@ import (
 //	and a SHA256 content hash (if enabled). This provides a compliance-ready
 //	audit trail without storing actual message content.
 //
+//	When an AuditStore is attached, Record also persists one queryable
+//	AuditRecord per request, independent of the slog enabled flag.
+//
 // Thread Safety: Safe for concurrent use (slog.Logger is concurrent-safe).
 type EgressAuditor struct {
 	logger      *slog.Logger
 	enabled     bool
 	hashContent bool
+
+	storeMu sync.RWMutex
+	store   AuditStore
 }
 
 // NewEgressAuditor creates a new auditor.
@@ -129,7 +138,7 @@ func (a *EgressAuditor) LogAfter(
 	}
 
 	if callErr != nil {
-		attrs = append(attrs, slog.String("error", callErr.Error()))
+		attrs = append(attrs, telemetry.SensitiveLogError("error", callErr))
 	}
 
 	logger.Info("egress response", attrs...)
@@ -199,6 +208,42 @@ func (a *EgressAuditor) LogMinimization(ctx context.Context, requestID string, s
 	)
 }
 
+// SetStore attaches a persistent audit store. Nil detaches it.
+//
+// Thread Safety: Safe for concurrent use.
+func (a *EgressAuditor) SetStore(store AuditStore) {
+	a.storeMu.Lock()
+	defer a.storeMu.Unlock()
+	a.store = store
+}
+
+// Record persists an audit record if a store is attached.
+//
+// Description:
+//
+//	Persistence failures are logged and never fail the LLM call.
+//
+// Inputs:
+//   - ctx: Context containing trace information.
+//   - record: The record to persist. Nil is ignored.
+func (a *EgressAuditor) Record(ctx context.Context, record *AuditRecord) {
+	if a == nil || record == nil {
+		return
+	}
+	a.storeMu.RLock()
+	store := a.store
+	a.storeMu.RUnlock()
+	if store == nil {
+		return
+	}
+	if err := store.Append(context.WithoutCancel(ctx), record); err != nil {
+		a.loggerWithTrace(ctx).Warn("egress audit record not persisted",
+			slog.String("request_id", record.RequestID),
+			telemetry.SensitiveLogError("error", err),
+		)
+	}
+}
+
 // loggerWithTrace returns a logger enriched with trace context.
 func (a *EgressAuditor) loggerWithTrace(ctx context.Context) *slog.Logger {
 	spanCtx := trace.SpanContextFromContext(ctx)
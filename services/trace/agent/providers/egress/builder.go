@@ -35,6 +35,8 @@ type EgressGuardBuilder struct {
 	rateLimiter   *RateLimiter
 	secretManager *SecretManager
 	minimizer     *DataMinimizer
+	redactor      *Redactor
+	mode          string
 	logger        *slog.Logger
 }
 
@@ -69,6 +71,8 @@ func NewEgressGuardBuilder(cfg *EgressConfig, classifier DataClassifier) *Egress
 		rateLimiter:   NewRateLimiter(cfg.RateLimitsPerMin),
 		secretManager: NewSecretManager(0),
 		minimizer:     NewDataMinimizer(cfg.MinimizationEnabled, cfg.MinContextTokens, logger),
+		redactor:      NewRedactor(0),
+		mode:          cfg.SensitiveDataMode,
 		logger:        logger,
 	}
 }
@@ -111,6 +115,8 @@ func (b *EgressGuardBuilder) WrapAgentClient(
 		auditor:       b.auditor,
 		metrics:       NewProviderMetrics(provider),
 		minimizer:     b.minimizer,
+		redactor:      b.redactor,
+		mode:          b.mode,
 		provider:      provider,
 		model:         model,
 		sessionID:     sessionID,
@@ -154,12 +160,33 @@ func (b *EgressGuardBuilder) WrapChatClient(
 		costEstimator: b.costEstimator,
 		auditor:       b.auditor,
 		metrics:       NewProviderMetrics(provider),
+		redactor:      b.redactor,
+		mode:          b.mode,
 		provider:      provider,
 		model:         model,
 		sessionID:     sessionID,
 	}
 }
 
+// SetAuditStore attaches a persistent store for per-request audit records.
+//
+// Inputs:
+//   - store: The store. Nil disables persistence.
+//
+// Thread Safety: Safe for concurrent use.
+func (b *EgressGuardBuilder) SetAuditStore(store AuditStore) {
+	b.auditor.SetStore(store)
+}
+
+// Redactor returns the shared redactor holding the placeholder mappings
+// of redact mode.
+//
+// Outputs:
+//   - *Redactor: The shared redactor.
+func (b *EgressGuardBuilder) Redactor() *Redactor {
+	return b.redactor
+}
+
 // ControlPlane returns the shared control plane for runtime kill switch access.
 //
 // Outputs:
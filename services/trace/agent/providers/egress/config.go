@@ -14,8 +14,26 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// Sensitive data enforcement modes for TRACE_EGRESS_SENSITIVE_MODE.
+const (
+	// SensitiveModeDeny blocks requests containing PII, PHI, or Secret data.
+	SensitiveModeDeny = "deny"
+
+	// SensitiveModeAllow sends sensitive requests unchanged. The audit
+	// record still captures the classification of every content block.
+	SensitiveModeAllow = "allow"
+
+	// SensitiveModeRedact replaces sensitive spans with placeholders before
+	// egress and restores them in the response.
+	SensitiveModeRedact = "redact"
+)
+
+// DefaultAuditRetention is how long persisted egress audit records are kept.
+const DefaultAuditRetention = 30 * 24 * time.Hour
+
 // EgressConfig holds all configuration for the egress guard system.
 //
 // Description:
@@ -83,6 +101,21 @@ type EgressConfig struct {
 	// Requests estimated at fewer tokens than this are already small enough.
 	// Env: TRACE_MIN_CONTEXT_TOKENS (default: 500)
 	MinContextTokens int
+
+	// SensitiveDataMode controls what happens when a request contains PII,
+	// PHI, or Secret data: "deny", "allow", or "redact". Unknown values
+	// fall back to "deny".
+	// Env: TRACE_EGRESS_SENSITIVE_MODE (default: "deny")
+	SensitiveDataMode string
+
+	// AuditStoreDir is the BadgerDB directory for persisted audit records.
+	// Empty disables persistence; slog auditing is unaffected.
+	// Env: TRACE_EGRESS_AUDIT_DIR (default: "")
+	AuditStoreDir string
+
+	// AuditRetention is how long persisted audit records are kept.
+	// Env: TRACE_EGRESS_AUDIT_RETENTION (Go duration, default: "720h")
+	AuditRetention time.Duration
 }
 
 // LoadEgressConfig reads egress configuration from environment variables.
@@ -109,6 +142,9 @@ func LoadEgressConfig() *EgressConfig {
 		CostLimitCents:      envFloat("TRACE_COST_LIMIT_CENTS", 0),
 		MinimizationEnabled: envBool("TRACE_MINIMIZATION_ENABLED", true),
 		MinContextTokens:    envInt("TRACE_MIN_CONTEXT_TOKENS", 500),
+		SensitiveDataMode:   envSensitiveMode("TRACE_EGRESS_SENSITIVE_MODE"),
+		AuditStoreDir:       os.Getenv("TRACE_EGRESS_AUDIT_DIR"),
+		AuditRetention:      envDuration("TRACE_EGRESS_AUDIT_RETENTION", DefaultAuditRetention),
 		RateLimitsPerMin:    make(map[string]int),
 		ProviderConsent:     make(map[string]bool),
 	}
@@ -168,6 +204,31 @@ func envFloat(key string, defaultVal float64) float64 {
 	return f
 }
 
+// envDuration reads a time.Duration environment variable with a default value.
+// Non-positive durations fall back to the default.
+func envDuration(key string, defaultVal time.Duration) time.Duration {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultVal
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		return defaultVal
+	}
+	return d
+}
+
+// envSensitiveMode reads the sensitive data mode. Unknown values fall back
+// to SensitiveModeDeny (fail-safe).
+func envSensitiveMode(key string) string {
+	switch mode := strings.ToLower(strings.TrimSpace(os.Getenv(key))); mode {
+	case SensitiveModeAllow, SensitiveModeRedact:
+		return mode
+	default:
+		return SensitiveModeDeny
+	}
+}
+
 // envSet reads a comma-separated environment variable into a set.
 // Returns an empty map (not nil) if the variable is unset.
 func envSet(key string) map[string]bool {
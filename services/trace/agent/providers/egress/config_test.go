@@ -12,6 +12,7 @@ package egress
 
 import (
 	"testing"
+	"time"
 )
 
 func TestLoadEgressConfig_Defaults(t *testing.T) {
@@ -172,3 +173,32 @@ func TestEnvSet_Whitespace(t *testing.T) {
 		t.Errorf("Allowlist should have 2 entries, got %d", len(cfg.Allowlist))
 	}
 }
+
+func TestLoadEgressConfig_SensitiveModeAndAudit(t *testing.T) {
+	tests := []struct {
+		mode, retention string
+		wantMode        string
+		wantRetention   time.Duration
+	}{
+		{"", "", SensitiveModeDeny, DefaultAuditRetention},
+		{"REDACT", "48h", SensitiveModeRedact, 48 * time.Hour},
+		{"allow", "-1h", SensitiveModeAllow, DefaultAuditRetention},
+		{"bogus", "soon", SensitiveModeDeny, DefaultAuditRetention},
+	}
+	for _, tt := range tests {
+		t.Setenv("TRACE_EGRESS_SENSITIVE_MODE", tt.mode)
+		t.Setenv("TRACE_EGRESS_AUDIT_RETENTION", tt.retention)
+		t.Setenv("TRACE_EGRESS_AUDIT_DIR", "/tmp/audit")
+
+		cfg := LoadEgressConfig()
+		if cfg.SensitiveDataMode != tt.wantMode {
+			t.Errorf("mode %q: SensitiveDataMode = %q, want %q", tt.mode, cfg.SensitiveDataMode, tt.wantMode)
+		}
+		if cfg.AuditRetention != tt.wantRetention {
+			t.Errorf("retention %q: AuditRetention = %v, want %v", tt.retention, cfg.AuditRetention, tt.wantRetention)
+		}
+		if cfg.AuditStoreDir != "/tmp/audit" {
+			t.Errorf("AuditStoreDir = %q", cfg.AuditStoreDir)
+		}
+	}
+}
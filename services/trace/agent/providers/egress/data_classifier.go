@@ -12,6 +12,7 @@ package egress
 
 import (
 	"context"
+	"sort"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/policy"
 )
@@ -31,6 +32,40 @@ type DataClassifier interface {
 	Classify(ctx context.Context, data []byte) DataSensitivity
 }
 
+// SensitiveSpan is a byte range of classified content that must not leave
+// the environment.
+type SensitiveSpan struct {
+	// Start is the byte offset of the first sensitive byte.
+	Start int
+
+	// End is the byte offset one past the last sensitive byte.
+	End int
+
+	// Sensitivity is the classification of the span.
+	Sensitivity DataSensitivity
+
+	// PatternID identifies the pattern that matched, if known.
+	PatternID string
+}
+
+// SpanClassifier is a DataClassifier that can also locate the sensitive
+// spans it detects. Required by the "redact" sensitive data mode.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type SpanClassifier interface {
+	DataClassifier
+
+	// FindSpans returns non-overlapping sensitive spans sorted by Start.
+	//
+	// Inputs:
+	//   - ctx: Context for cancellation.
+	//   - data: The raw data to scan.
+	//
+	// Outputs:
+	//   - []SensitiveSpan: The spans. Nil if the data is safe to send.
+	FindSpans(ctx context.Context, data []byte) []SensitiveSpan
+}
+
 // PolicyEngineClassifier adapts the policy_engine.PolicyEngine to the
 // DataClassifier interface.
 //
@@ -76,6 +111,81 @@ func (c *PolicyEngineClassifier) Classify(_ context.Context, data []byte) DataSe
 	return ParseSensitivity(classification)
 }
 
+// FindSpans locates every span that must not leave the environment.
+//
+// Description:
+//
+//	Runs each compiled pattern of every PII, PHI, and Secret classification
+//	over data. Public and Confidential patterns are skipped because their
+//	content may be sent externally. Overlapping spans are merged; the
+//	merged span takes the highest sensitivity of its parts.
+//
+// Inputs:
+//   - ctx: Context for cancellation (not used by regex matcher).
+//   - data: The raw data to scan.
+//
+// Outputs:
+//   - []SensitiveSpan: Non-overlapping spans sorted by Start. Nil if none.
+func (c *PolicyEngineClassifier) FindSpans(_ context.Context, data []byte) []SensitiveSpan {
+	if len(data) == 0 {
+		return nil
+	}
+	var spans []SensitiveSpan
+	for _, classifier := range c.engine.Classifiers {
+		sensitivity := ParseSensitivity(classifier.Name)
+		if sensitivity.AllowsExternalSend() {
+			continue
+		}
+		for i, re := range classifier.CompiledPatterns {
+			patternID := ""
+			if i < len(classifier.Patterns) {
+				patternID = classifier.Patterns[i].Id
+			}
+			for _, loc := range re.FindAllIndex(data, -1) {
+				if loc[1] > loc[0] {
+					spans = append(spans, SensitiveSpan{
+						Start:       loc[0],
+						End:         loc[1],
+						Sensitivity: sensitivity,
+						PatternID:   patternID,
+					})
+				}
+			}
+		}
+	}
+	return mergeSpans(spans)
+}
+
+// mergeSpans sorts spans and merges overlapping ones, keeping the highest
+// sensitivity and its pattern ID.
+func mergeSpans(spans []SensitiveSpan) []SensitiveSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i].Start != spans[j].Start {
+			return spans[i].Start < spans[j].Start
+		}
+		return spans[i].End > spans[j].End
+	})
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s.Start >= last.End {
+			merged = append(merged, s)
+			continue
+		}
+		if s.End > last.End {
+			last.End = s.End
+		}
+		if s.Sensitivity > last.Sensitivity {
+			last.Sensitivity = s.Sensitivity
+			last.PatternID = s.PatternID
+		}
+	}
+	return merged
+}
+
 // NoOpClassifier always returns SensitivityPublic.
 //
 // Description:
@@ -13,7 +13,6 @@ package egress
 import (
 	"context"
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -22,6 +21,7 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	oteltrace "go.opentelemetry.io/otel/trace"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
 )
 
@@ -48,6 +48,8 @@ type EgressGuardClient struct {
 	auditor       *EgressAuditor
 	metrics       *ProviderMetrics
 	minimizer     *DataMinimizer
+	redactor      *Redactor
+	mode          string
 	provider      string
 	model         string
 	sessionID     string
@@ -61,7 +63,9 @@ type EgressGuardClient struct {
 //	  1. ControlPlane — is the provider enabled?
 //	  2. Policy — is the provider in the allowlist (not in denylist)?
 //	  3. Consent — has the user given consent for this provider?
-//	  4. Classify — does the content contain sensitive data?
+//	  4. Classify — does the content contain sensitive data? Each content
+//	     block is classified and the sensitive data mode applied (deny,
+//	     allow, or redact).
 //	  5. RateLimit — is the provider within rate limits?
 //	  6. Budget — does the session have enough token budget?
 //	  7. Cost — is the estimated cost within the cost ceiling?
@@ -69,8 +73,14 @@ type EgressGuardClient struct {
 //	If any check fails, returns the appropriate sentinel error without
 //	calling the inner client.
 //
+//	In redact mode the inner client receives a redacted copy of the
+//	request, and placeholders in the response content and tool call
+//	arguments are restored. Every attempt, blocked or not, is recorded
+//	to the auditor's persistent store.
+//
 // Inputs:
-//   - ctx: Context for cancellation and tracing.
+//   - ctx: Context for cancellation and tracing. The session ID set by
+//     agent.ContextWithSessionID takes precedence over the guard's own.
 //   - request: The LLM completion request.
 //
 // Outputs:
@@ -84,26 +94,28 @@ func (g *EgressGuardClient) Complete(ctx context.Context, request *agentllm.Requ
 		return nil, fmt.Errorf("egress guard: request must not be nil")
 	}
 
+	sessionID := sessionIDFor(ctx, g.sessionID)
 	ctx, span := otel.Tracer("aleutian.trace").Start(ctx, "egress.EgressGuardClient.Complete",
 		oteltrace.WithAttributes(
 			attribute.String("provider", g.provider),
 			attribute.String("model", g.model),
-			attribute.String("session_id", g.sessionID),
+			attribute.String("session_id", sessionID),
 		),
 	)
 	defer span.End()
 
 	requestID := uuid.New().String()
-	decision := NewEgressDecision(requestID, g.sessionID, g.provider, g.model)
+	decision := NewEgressDecision(requestID, sessionID, g.provider, g.model)
 	start := time.Now()
 
-	// Serialize messages once for classification and hashing
-	serialized := serializeAgentRequest(request)
+	blocks := agentBlocks(request)
+	screen := newContentScreen(g.mode, g.classifier, g.redactor, sessionID, blocks)
 
 	// Pre-flight checks
-	if blocked, blockedBy, reason := g.preFlightChecks(ctx, serialized, decision); blocked {
+	if blocked, blockedBy, reason := g.preFlightChecks(ctx, screen, decision); blocked {
 		decision.DurationMs = time.Since(start).Milliseconds()
 		g.auditor.LogBlocked(ctx, decision)
+		g.auditor.Record(ctx, newAuditRecord(decision, screen))
 		RecordEgressBlocked(g.provider, blockedBy)
 		span.SetAttributes(attribute.String("blocked_by", blockedBy))
 		span.SetStatus(codes.Error, reason)
@@ -114,11 +126,16 @@ func (g *EgressGuardClient) Complete(ctx context.Context, request *agentllm.Requ
 	decision.Allowed = true
 	g.auditor.LogBefore(ctx, decision)
 
+	outbound := request
+	if screen.redacted {
+		outbound = applyAgentBlocks(request, blocks, screen.sent)
+	}
+
 	// Minimize request before sending to external provider
-	minimizedReq := request
+	minimizedReq := outbound
 	if g.minimizer != nil {
 		var stats MinimizationStats
-		minimizedReq, stats = g.minimizer.Minimize(request, g.provider, g.model)
+		minimizedReq, stats = g.minimizer.Minimize(outbound, g.provider, g.model)
 		g.auditor.LogMinimization(ctx, requestID, stats)
 	}
 
@@ -127,10 +144,14 @@ func (g *EgressGuardClient) Complete(ctx context.Context, request *agentllm.Requ
 
 	// Post-call recording
 	callDuration := time.Since(start)
-	var inputTokens, outputTokens int
+	var inputTokens, outputTokens, bytesReceived int
 	if resp != nil {
 		inputTokens = resp.InputTokens
 		outputTokens = resp.OutputTokens
+		bytesReceived = len(resp.Content)
+		for _, tc := range resp.ToolCalls {
+			bytesReceived += len(tc.Arguments)
+		}
 	}
 
 	costCents := g.costEstimator.Record(g.model, inputTokens, outputTokens)
@@ -143,6 +164,7 @@ func (g *EgressGuardClient) Complete(ctx context.Context, request *agentllm.Requ
 
 	g.auditor.LogAfter(ctx, requestID, g.provider, g.model,
 		inputTokens, outputTokens, callDuration.Milliseconds(), costCents, err)
+	g.auditor.Record(ctx, completedAuditRecord(decision, screen, inputTokens, outputTokens, bytesReceived, callDuration, costCents, err))
 
 	if err != nil {
 		g.metrics.RecordError()
@@ -152,7 +174,7 @@ func (g *EgressGuardClient) Complete(ctx context.Context, request *agentllm.Requ
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return resp, nil
+	return restoreAgentResponse(resp, screen), nil
 }
 
 // Name returns the provider name from the inner client.
@@ -184,6 +206,8 @@ type EgressGuardChatClient struct {
 	costEstimator *CostEstimator
 	auditor       *EgressAuditor
 	metrics       *ProviderMetrics
+	redactor      *Redactor
+	mode          string
 	provider      string
 	model         string
 	sessionID     string
@@ -204,26 +228,28 @@ func (g *EgressGuardChatClient) Chat(ctx context.Context, messages []Message, op
 		ctx = context.Background()
 	}
 
+	sessionID := sessionIDFor(ctx, g.sessionID)
 	ctx, span := otel.Tracer("aleutian.trace").Start(ctx, "egress.EgressGuardChatClient.Chat",
 		oteltrace.WithAttributes(
 			attribute.String("provider", g.provider),
 			attribute.String("model", g.model),
-			attribute.String("session_id", g.sessionID),
+			attribute.String("session_id", sessionID),
 		),
 	)
 	defer span.End()
 
 	requestID := uuid.New().String()
-	decision := NewEgressDecision(requestID, g.sessionID, g.provider, g.model)
+	decision := NewEgressDecision(requestID, sessionID, g.provider, g.model)
 	start := time.Now()
 
-	// Serialize messages once for classification and hashing
-	serialized := serializeChatMessages(messages)
+	blocks := chatBlocks(messages)
+	screen := newContentScreen(g.mode, g.classifier, g.redactor, sessionID, blocks)
 
 	// Pre-flight checks
-	if blocked, blockedBy, reason := g.preFlightChecks(ctx, serialized, decision); blocked {
+	if blocked, blockedBy, reason := g.preFlightChecks(ctx, screen, decision); blocked {
 		decision.DurationMs = time.Since(start).Milliseconds()
 		g.auditor.LogBlocked(ctx, decision)
+		g.auditor.Record(ctx, newAuditRecord(decision, screen))
 		RecordEgressBlocked(g.provider, blockedBy)
 		span.SetAttributes(attribute.String("blocked_by", blockedBy))
 		span.SetStatus(codes.Error, reason)
@@ -233,8 +259,13 @@ func (g *EgressGuardChatClient) Chat(ctx context.Context, messages []Message, op
 	decision.Allowed = true
 	g.auditor.LogBefore(ctx, decision)
 
+	outbound := messages
+	if screen.redacted {
+		outbound = applyChatBlocks(messages, blocks, screen.sent)
+	}
+
 	// Delegate to inner client
-	resp, err := g.inner.Chat(ctx, messages, opts)
+	resp, err := g.inner.Chat(ctx, outbound, opts)
 
 	callDuration := time.Since(start)
 
@@ -247,6 +278,7 @@ func (g *EgressGuardChatClient) Chat(ctx context.Context, messages []Message, op
 
 	g.auditor.LogAfter(ctx, requestID, g.provider, g.model,
 		0, estimatedOutputTokens, callDuration.Milliseconds(), costCents, err)
+	g.auditor.Record(ctx, completedAuditRecord(decision, screen, 0, estimatedOutputTokens, len(resp), callDuration, costCents, err))
 
 	if err != nil {
 		g.metrics.RecordError()
@@ -256,24 +288,24 @@ func (g *EgressGuardChatClient) Chat(ctx context.Context, messages []Message, op
 	}
 
 	span.SetStatus(codes.Ok, "")
-	return resp, nil
+	return screen.restore(resp), nil
 }
 
 // preFlightChecks runs all egress pre-flight checks for either guard type.
 // Returns (blocked, blockerName, reason) — blocked=false means all checks passed.
-func (g *EgressGuardClient) preFlightChecks(ctx context.Context, serialized []byte, decision *EgressDecision) (bool, string, string) {
+func (g *EgressGuardClient) preFlightChecks(ctx context.Context, screen *contentScreen, decision *EgressDecision) (bool, string, string) {
 	return runPreFlightChecks(
-		ctx, serialized, decision,
-		g.controlPlane, g.policy, g.consent, g.classifier,
+		ctx, screen, decision,
+		g.controlPlane, g.policy, g.consent,
 		g.rateLimiter, g.tokenBudget, g.costEstimator,
 		g.provider, g.model,
 	)
 }
 
-func (g *EgressGuardChatClient) preFlightChecks(ctx context.Context, serialized []byte, decision *EgressDecision) (bool, string, string) {
+func (g *EgressGuardChatClient) preFlightChecks(ctx context.Context, screen *contentScreen, decision *EgressDecision) (bool, string, string) {
 	return runPreFlightChecks(
-		ctx, serialized, decision,
-		g.controlPlane, g.policy, g.consent, g.classifier,
+		ctx, screen, decision,
+		g.controlPlane, g.policy, g.consent,
 		g.rateLimiter, g.tokenBudget, g.costEstimator,
 		g.provider, g.model,
 	)
@@ -282,12 +314,11 @@ func (g *EgressGuardChatClient) preFlightChecks(ctx context.Context, serialized
 // runPreFlightChecks is the shared implementation for all guard types.
 func runPreFlightChecks(
 	ctx context.Context,
-	serialized []byte,
+	screen *contentScreen,
 	decision *EgressDecision,
 	controlPlane *ProviderControlPlane,
 	policy *ProviderPolicy,
 	consent *ConsentPolicy,
-	classifier DataClassifier,
 	rateLimiter *RateLimiter,
 	tokenBudget *TokenBudget,
 	costEstimator *CostEstimator,
@@ -314,19 +345,19 @@ func runPreFlightChecks(
 		return true, "consent", reason
 	}
 
-	// P-3: Data classification
-	sensitivity := classifier.Classify(ctx, serialized)
-	decision.Sensitivity = sensitivity
-	RecordEgressSensitivity(provider, sensitivity)
+	// P-3: Data classification (per block, with the sensitive data mode)
+	reason := screen.run(ctx, provider)
+	decision.Sensitivity = screen.sensitivity
+	RecordEgressSensitivity(provider, screen.sensitivity)
 
-	if !sensitivity.AllowsExternalSend() {
-		reason := fmt.Sprintf("data classified as %s — cannot send to external provider %q", sensitivity, provider)
+	if reason != "" {
 		decision.BlockedBy = "sensitive_data"
 		decision.BlockReason = reason
 		return true, "sensitive_data", reason
 	}
 
-	// Content hash for audit
+	// Content hash for audit, over the content as sent
+	serialized := screen.serialized()
 	decision.ContentHash = HashContent(serialized)
 
 	// Rate limit
@@ -391,38 +422,35 @@ func sentinelForBlocker(blockedBy string) error {
 	}
 }
 
-// serializeAgentRequest serializes an agent request's messages for classification.
-// Returns an empty (non-nil) byte slice for requests with no content, ensuring
-// downstream classification and hashing always receive valid input.
-func serializeAgentRequest(req *agentllm.Request) []byte {
-	if req == nil {
-		return []byte{}
+// sessionIDFor returns the session ID carried by ctx, falling back to the
+// guard's own. Shared clients are wrapped once with a placeholder session,
+// so the context value is what attributes a call to its session.
+func sessionIDFor(ctx context.Context, fallback string) string {
+	if id := agent.SessionIDFromContext(ctx); id != "" {
+		return id
 	}
-
-	var sb strings.Builder
-	if req.SystemPrompt != "" {
-		sb.WriteString(req.SystemPrompt)
-		sb.WriteByte('\n')
-	}
-	for _, msg := range req.Messages {
-		sb.WriteString(msg.Content)
-		sb.WriteByte('\n')
-	}
-	return []byte(sb.String())
+	return fallback
 }
 
-// serializeChatMessages serializes chat messages for classification.
-// Returns an empty (non-nil) byte slice for empty message lists, ensuring
-// downstream classification and hashing always receive valid input.
-func serializeChatMessages(messages []Message) []byte {
-	if len(messages) == 0 {
-		return []byte{}
-	}
-
-	var sb strings.Builder
-	for _, msg := range messages {
-		sb.WriteString(msg.Content)
-		sb.WriteByte('\n')
+// completedAuditRecord builds the persisted record for a request that
+// reached the provider.
+func completedAuditRecord(
+	decision *EgressDecision,
+	screen *contentScreen,
+	inputTokens, outputTokens, bytesReceived int,
+	duration time.Duration,
+	costCents float64,
+	callErr error,
+) *AuditRecord {
+	record := newAuditRecord(decision, screen)
+	record.InputTokens = inputTokens
+	record.OutputTokens = outputTokens
+	record.BytesReceived = bytesReceived
+	record.DurationMs = duration.Milliseconds()
+	record.CostCents = costCents
+	if callErr != nil {
+		record.Status = "error"
+		record.Error = callErr.Error()
 	}
-	return []byte(sb.String())
+	return record
 }
@@ -14,9 +14,11 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"regexp"
 	"testing"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
 )
 
@@ -305,9 +307,9 @@ func TestInterfaceCompliance(t *testing.T) {
 // Serialization helpers
 // =============================================================================
 
-func TestSerializeAgentRequest(t *testing.T) {
+func TestAgentBlocks(t *testing.T) {
 	t.Run("nil request", func(t *testing.T) {
-		data := serializeAgentRequest(nil)
+		data := newContentScreen("", NewNoOpClassifier(), nil, "", agentBlocks(nil)).serialized()
 		if data == nil {
 			t.Error("nil request should return empty non-nil slice")
 		}
@@ -317,7 +319,7 @@ func TestSerializeAgentRequest(t *testing.T) {
 	})
 
 	t.Run("empty request", func(t *testing.T) {
-		data := serializeAgentRequest(&agentllm.Request{})
+		data := newContentScreen("", NewNoOpClassifier(), nil, "", agentBlocks(&agentllm.Request{})).serialized()
 		if data == nil {
 			t.Error("empty request should return non-nil slice")
 		}
@@ -328,19 +330,26 @@ func TestSerializeAgentRequest(t *testing.T) {
 			SystemPrompt: "You are helpful.",
 			Messages: []agentllm.Message{
 				{Role: "user", Content: "Hello"},
-				{Role: "assistant", Content: "Hi there"},
+				{Role: "assistant", Content: "Hi there", ToolCalls: []agentllm.ToolCall{{ID: "1", Name: "find", Arguments: `{"q":"x"}`}}},
+				{Role: "user", ToolResults: []agentllm.ToolCallResult{{ToolCallID: "1", Content: "found"}}},
 			},
 		}
-		data := serializeAgentRequest(req)
-		if len(data) == 0 {
-			t.Error("should produce non-empty output")
+		blocks := agentBlocks(req)
+		wantKinds := []string{BlockKindSystem, BlockKindMessage, BlockKindMessage, BlockKindToolCall, BlockKindToolResult}
+		if len(blocks) != len(wantKinds) {
+			t.Fatalf("got %d blocks, want %d", len(blocks), len(wantKinds))
+		}
+		for i, kind := range wantKinds {
+			if blocks[i].kind != kind {
+				t.Errorf("block %d kind = %q, want %q", i, blocks[i].kind, kind)
+			}
 		}
 	})
 }
 
-func TestSerializeChatMessages(t *testing.T) {
+func TestChatBlocks(t *testing.T) {
 	t.Run("nil messages", func(t *testing.T) {
-		data := serializeChatMessages(nil)
+		data := newContentScreen("", NewNoOpClassifier(), nil, "", chatBlocks(nil)).serialized()
 		if data == nil {
 			t.Error("nil messages should return empty non-nil slice")
 		}
@@ -353,7 +362,7 @@ func TestSerializeChatMessages(t *testing.T) {
 		msgs := []Message{
 			{Role: "user", Content: "Hello"},
 		}
-		data := serializeChatMessages(msgs)
+		data := newContentScreen("", NewNoOpClassifier(), nil, "", chatBlocks(msgs)).serialized()
 		if len(data) == 0 {
 			t.Error("should produce non-empty output")
 		}
@@ -372,3 +381,170 @@ func TestEgressGuardClient_Complete_NilRequest(t *testing.T) {
 		t.Errorf("unexpected error message: %v", err)
 	}
 }
+
+// =============================================================================
+// Sensitive data modes and audit records
+// =============================================================================
+
+// capturingAgentClient records the request it receives and answers with a
+// tool call echoing a placeholder.
+type capturingAgentClient struct {
+	got      *agentllm.Request
+	response *agentllm.Response
+}
+
+func (m *capturingAgentClient) Complete(_ context.Context, req *agentllm.Request) (*agentllm.Response, error) {
+	m.got = req
+	return m.response, nil
+}
+
+func (m *capturingAgentClient) Name() string  { return "anthropic" }
+func (m *capturingAgentClient) Model() string { return "claude-sonnet-4-20250514" }
+
+// ssnClassifier classifies text containing an SSN as PII and locates it.
+type ssnClassifier struct{}
+
+var testSSNPattern = regexp.MustCompile(`\d{3}-\d{2}-\d{4}`)
+
+func (c *ssnClassifier) Classify(_ context.Context, data []byte) DataSensitivity {
+	if testSSNPattern.Match(data) {
+		return SensitivityPII
+	}
+	return SensitivityPublic
+}
+
+func (c *ssnClassifier) FindSpans(_ context.Context, data []byte) []SensitiveSpan {
+	var spans []SensitiveSpan
+	for _, loc := range testSSNPattern.FindAllIndex(data, -1) {
+		spans = append(spans, SensitiveSpan{Start: loc[0], End: loc[1], Sensitivity: SensitivityPII, PatternID: "SSN"})
+	}
+	return spans
+}
+
+// memAuditStore keeps audit records in memory.
+type memAuditStore struct {
+	records []AuditRecord
+}
+
+func (s *memAuditStore) Append(_ context.Context, r *AuditRecord) error {
+	s.records = append(s.records, *r)
+	return nil
+}
+
+func (s *memAuditStore) Query(_ context.Context, _ AuditQuery) ([]AuditRecord, error) {
+	return s.records, nil
+}
+
+func TestEgressGuardClient_Complete_RedactMode(t *testing.T) {
+	inner := &capturingAgentClient{response: &agentllm.Response{
+		Content:   "Looking up [REDACTED:pii:1]",
+		ToolCalls: []agentllm.ToolCall{{ID: "t1", Name: "find_user", Arguments: `{"ssn": "[REDACTED:pii:1]"}`}},
+	}}
+	store := &memAuditStore{}
+	guard := buildTestGuardClient(inner, func(g *EgressGuardClient) {
+		g.classifier = &ssnClassifier{}
+		g.mode = SensitiveModeRedact
+		g.redactor = NewRedactor(0)
+		g.auditor.SetStore(store)
+	})
+
+	req := &agentllm.Request{
+		SystemPrompt: "You are helpful.",
+		Messages: []agentllm.Message{
+			{Role: "user", Content: "Who has SSN 123-45-6789?"},
+			{Role: "user", ToolResults: []agentllm.ToolCallResult{{ToolCallID: "t0", Content: "row: 123-45-6789"}}},
+		},
+	}
+	ctx := agent.ContextWithSessionID(context.Background(), "sess-42")
+	resp, err := guard.Complete(ctx, req)
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+
+	if got := inner.got.Messages[0].Content; got != "Who has SSN [REDACTED:pii:1]?" {
+		t.Errorf("sent content = %q", got)
+	}
+	if got := inner.got.Messages[1].ToolResults[0].Content; got != "row: [REDACTED:pii:1]" {
+		t.Errorf("sent tool result = %q", got)
+	}
+	if req.Messages[0].Content != "Who has SSN 123-45-6789?" || req.Messages[1].ToolResults[0].Content != "row: 123-45-6789" {
+		t.Error("caller's request must not be mutated")
+	}
+	if resp.Content != "Looking up 123-45-6789" || resp.ToolCalls[0].Arguments != `{"ssn": "123-45-6789"}` {
+		t.Errorf("response not restored: %+v", resp)
+	}
+
+	if len(store.records) != 1 {
+		t.Fatalf("got %d audit records, want 1", len(store.records))
+	}
+	rec := store.records[0]
+	if rec.SessionID != "sess-42" || rec.Decision != AuditDecisionRedacted || rec.Mode != SensitiveModeRedact {
+		t.Errorf("record = %+v", rec)
+	}
+	if rec.RedactionCount() != 2 || len(rec.Blocks) != 3 || rec.Blocks[2].Kind != BlockKindToolResult {
+		t.Errorf("blocks = %+v", rec.Blocks)
+	}
+	if rec.BytesSent == rec.BytesIn || rec.BytesReceived == 0 {
+		t.Errorf("byte counts = in %d sent %d received %d", rec.BytesIn, rec.BytesSent, rec.BytesReceived)
+	}
+}
+
+func TestEgressGuardClient_Complete_RedactUnavailable(t *testing.T) {
+	inner := &mockAgentClient{}
+	store := &memAuditStore{}
+	guard := buildTestGuardClient(inner, func(g *EgressGuardClient) {
+		g.classifier = &sensitiveClassifier{}
+		g.mode = SensitiveModeRedact
+		g.redactor = NewRedactor(0)
+		g.auditor.SetStore(store)
+	})
+
+	_, err := guard.Complete(context.Background(), &agentllm.Request{
+		Messages: []agentllm.Message{{Role: "user", Content: "some pii data"}},
+	})
+	if !errors.Is(err, ErrSensitiveData) {
+		t.Fatalf("error should wrap ErrSensitiveData, got: %v", err)
+	}
+	if len(store.records) != 1 || store.records[0].Decision != AuditDecisionBlocked || store.records[0].BlockedBy != "sensitive_data" {
+		t.Errorf("records = %+v", store.records)
+	}
+	if store.records[0].SessionID != "test-session" {
+		t.Errorf("SessionID = %q, want guard fallback", store.records[0].SessionID)
+	}
+}
+
+func TestEgressGuardClient_Complete_AllowMode(t *testing.T) {
+	inner := &mockAgentClient{response: &agentllm.Response{Content: "ok"}}
+	store := &memAuditStore{}
+	guard := buildTestGuardClient(inner, func(g *EgressGuardClient) {
+		g.classifier = &sensitiveClassifier{}
+		g.mode = SensitiveModeAllow
+		g.auditor.SetStore(store)
+	})
+
+	if _, err := guard.Complete(context.Background(), &agentllm.Request{
+		Messages: []agentllm.Message{{Role: "user", Content: "some pii data"}},
+	}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if len(store.records) != 1 || store.records[0].Decision != AuditDecisionAllowed || store.records[0].Blocks[0].Sensitivity != "pii" {
+		t.Errorf("records = %+v", store.records)
+	}
+}
+
+func TestEgressGuardChatClient_Chat_RedactMode(t *testing.T) {
+	inner := &mockChatClient{response: "route for [REDACTED:pii:1]"}
+	guard := buildTestGuardChatClient(inner, func(g *EgressGuardChatClient) {
+		g.classifier = &ssnClassifier{}
+		g.mode = SensitiveModeRedact
+		g.redactor = NewRedactor(0)
+	})
+
+	resp, err := guard.Chat(context.Background(), []Message{{Role: "user", Content: "SSN 123-45-6789"}}, ChatOptions{})
+	if err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+	if resp != "route for 123-45-6789" {
+		t.Errorf("response not restored: %q", resp)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package egress
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// defaultRedactorMaxSessions bounds how many session vaults are kept.
+const defaultRedactorMaxSessions = 256
+
+// placeholderPattern matches placeholders produced by the Redactor.
+var placeholderPattern = regexp.MustCompile(`\[REDACTED:[a-z]+:[0-9]+\]`)
+
+// RedactionAction records one span replaced before egress. It never
+// contains the original value.
+type RedactionAction struct {
+	// Placeholder is the text sent in place of the sensitive span.
+	Placeholder string `json:"placeholder"`
+
+	// Sensitivity is the classification of the replaced span.
+	Sensitivity string `json:"sensitivity"`
+
+	// PatternID identifies the classification pattern that matched.
+	PatternID string `json:"pattern_id,omitempty"`
+
+	// Bytes is the length of the replaced span.
+	Bytes int `json:"bytes"`
+}
+
+// Redactor replaces sensitive spans with placeholders and keeps the mapping
+// locally so responses and follow-up tool calls can be restored.
+//
+// Description:
+//
+//	Each session has its own vault. A value always maps to the same
+//	placeholder within a session (e.g. "[REDACTED:pii:1]"), so the model
+//	can refer to it consistently and a tool result containing the same
+//	value is redacted to the same placeholder. When more than maxSessions
+//	vaults exist, the oldest is evicted.
+//
+// Thread Safety: Safe for concurrent use.
+type Redactor struct {
+	mu          sync.Mutex
+	maxSessions int
+	vaults      map[string]*redactionVault
+	order       []string
+}
+
+// redactionVault holds the placeholder mapping for one session.
+type redactionVault struct {
+	byValue       map[string]string
+	byPlaceholder map[string]string
+	counts        map[DataSensitivity]int
+}
+
+// NewRedactor creates a redactor.
+//
+// Inputs:
+//   - maxSessions: Maximum number of session vaults kept. <= 0 uses 256.
+//
+// Outputs:
+//   - *Redactor: The redactor. Never nil.
+func NewRedactor(maxSessions int) *Redactor {
+	if maxSessions <= 0 {
+		maxSessions = defaultRedactorMaxSessions
+	}
+	return &Redactor{
+		maxSessions: maxSessions,
+		vaults:      make(map[string]*redactionVault),
+	}
+}
+
+// Redact replaces spans in text with placeholders.
+//
+// Inputs:
+//   - sessionID: The session whose vault stores the mapping.
+//   - text: The content to redact.
+//   - spans: Non-overlapping byte spans sorted by Start, as returned by
+//     SpanClassifier.FindSpans.
+//
+// Outputs:
+//   - string: The redacted text. Equal to text if spans is empty.
+//   - []RedactionAction: One action per replaced span.
+func (r *Redactor) Redact(sessionID, text string, spans []SensitiveSpan) (string, []RedactionAction) {
+	if len(spans) == 0 {
+		return text, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	vault := r.vaultLocked(sessionID)
+
+	var sb strings.Builder
+	actions := make([]RedactionAction, 0, len(spans))
+	last := 0
+	for _, span := range spans {
+		if span.Start < last || span.End > len(text) || span.Start >= span.End {
+			continue
+		}
+		value := text[span.Start:span.End]
+		placeholder, ok := vault.byValue[value]
+		if !ok {
+			vault.counts[span.Sensitivity]++
+			placeholder = fmt.Sprintf("[REDACTED:%s:%d]", span.Sensitivity, vault.counts[span.Sensitivity])
+			vault.byValue[value] = placeholder
+			vault.byPlaceholder[placeholder] = value
+		}
+		sb.WriteString(text[last:span.Start])
+		sb.WriteString(placeholder)
+		last = span.End
+		actions = append(actions, RedactionAction{
+			Placeholder: placeholder,
+			Sensitivity: span.Sensitivity.String(),
+			PatternID:   span.PatternID,
+			Bytes:       span.End - span.Start,
+		})
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), actions
+}
+
+// Restore replaces known placeholders in text with their original values.
+// Unknown placeholders are left unchanged.
+//
+// Inputs:
+//   - sessionID: The session whose vault holds the mapping.
+//   - text: Text that may contain placeholders.
+//
+// Outputs:
+//   - string: The restored text.
+func (r *Redactor) Restore(sessionID, text string) string {
+	if !strings.Contains(text, "[REDACTED:") {
+		return text
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	vault, ok := r.vaults[sessionID]
+	if !ok {
+		return text
+	}
+	return placeholderPattern.ReplaceAllStringFunc(text, func(p string) string {
+		if value, ok := vault.byPlaceholder[p]; ok {
+			return value
+		}
+		return p
+	})
+}
+
+// Lookup returns the original value behind a placeholder.
+//
+// Outputs:
+//   - string: The original value.
+//   - bool: False if the placeholder is unknown for the session.
+func (r *Redactor) Lookup(sessionID, placeholder string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	vault, ok := r.vaults[sessionID]
+	if !ok {
+		return "", false
+	}
+	value, ok := vault.byPlaceholder[placeholder]
+	return value, ok
+}
+
+// Forget discards the vault of a session.
+func (r *Redactor) Forget(sessionID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.vaults[sessionID]; !ok {
+		return
+	}
+	delete(r.vaults, sessionID)
+	for i, id := range r.order {
+		if id == sessionID {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// vaultLocked returns the vault for sessionID, creating it and evicting the
+// oldest vault if needed. Caller must hold r.mu.
+func (r *Redactor) vaultLocked(sessionID string) *redactionVault {
+	if vault, ok := r.vaults[sessionID]; ok {
+		return vault
+	}
+	for len(r.order) >= r.maxSessions {
+		delete(r.vaults, r.order[0])
+		r.order = r.order[1:]
+	}
+	vault := &redactionVault{
+		byValue:       make(map[string]string),
+		byPlaceholder: make(map[string]string),
+		counts:        make(map[DataSensitivity]int),
+	}
+	r.vaults[sessionID] = vault
+	r.order = append(r.order, sessionID)
+	return vault
+}
@@ -0,0 +1,311 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package egress
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+)
+
+// contentBlock is one independently classified piece of an outbound request.
+type contentBlock struct {
+	kind string
+	role string
+
+	// msg is the message index, or -1 for the system prompt.
+	msg int
+
+	// sub is the tool call or tool result index within the message.
+	sub int
+
+	text string
+}
+
+// contentScreen classifies the blocks of one request and applies the
+// sensitive data mode (P-3).
+//
+// Description:
+//
+//	Each block is classified on its own so the audit record shows where
+//	sensitive data was found. In "deny" mode any sensitive block blocks
+//	the request. In "allow" mode the request is sent unchanged. In
+//	"redact" mode sensitive spans are replaced with placeholders; the
+//	request is blocked if the classifier cannot locate spans or if a
+//	redacted block is still classified as sensitive (fail-safe).
+//
+// Thread Safety: Not safe for concurrent use. One screen per request.
+type contentScreen struct {
+	mode       string
+	classifier DataClassifier
+	redactor   *Redactor
+	sessionID  string
+	blocks     []contentBlock
+
+	// Results, populated by run.
+	sent        []string
+	audits      []BlockAudit
+	sensitivity DataSensitivity
+	redacted    bool
+}
+
+// newContentScreen creates a screen. An empty mode means SensitiveModeDeny.
+func newContentScreen(mode string, classifier DataClassifier, redactor *Redactor, sessionID string, blocks []contentBlock) *contentScreen {
+	if mode == "" {
+		mode = SensitiveModeDeny
+	}
+	sent := make([]string, len(blocks))
+	for i, b := range blocks {
+		sent[i] = b.text
+	}
+	return &contentScreen{
+		mode:       mode,
+		classifier: classifier,
+		redactor:   redactor,
+		sessionID:  sessionID,
+		blocks:     blocks,
+		sent:       sent,
+	}
+}
+
+// run classifies every block and applies the mode.
+//
+// Outputs:
+//   - string: Empty if the request may be sent, otherwise the block reason.
+func (s *contentScreen) run(ctx context.Context, provider string) string {
+	s.audits = make([]BlockAudit, len(s.blocks))
+	levels := make([]DataSensitivity, len(s.blocks))
+	for i, b := range s.blocks {
+		sensitivity := s.classifier.Classify(ctx, []byte(b.text))
+		levels[i] = sensitivity
+		if sensitivity > s.sensitivity {
+			s.sensitivity = sensitivity
+		}
+		s.audits[i] = BlockAudit{
+			Index:       i,
+			Kind:        b.kind,
+			Role:        b.role,
+			Sensitivity: sensitivity.String(),
+			Bytes:       len(b.text),
+			SentBytes:   len(b.text),
+		}
+	}
+
+	if s.sensitivity.AllowsExternalSend() || s.mode == SensitiveModeAllow {
+		return ""
+	}
+	if s.mode != SensitiveModeRedact {
+		return fmt.Sprintf("data classified as %s — cannot send to external provider %q", s.sensitivity, provider)
+	}
+
+	spanClassifier, ok := s.classifier.(SpanClassifier)
+	if !ok || s.redactor == nil {
+		return fmt.Sprintf("data classified as %s and redaction is unavailable — cannot send to external provider %q", s.sensitivity, provider)
+	}
+	for i, b := range s.blocks {
+		if levels[i].AllowsExternalSend() {
+			continue
+		}
+		text, actions := s.redactor.Redact(s.sessionID, b.text, spanClassifier.FindSpans(ctx, []byte(b.text)))
+		if residual := s.classifier.Classify(ctx, []byte(text)); !residual.AllowsExternalSend() {
+			return fmt.Sprintf("%s block %d still classified as %s after redaction — cannot send to external provider %q", b.kind, i, residual, provider)
+		}
+		s.sent[i] = text
+		s.audits[i].SentBytes = len(text)
+		s.audits[i].Redactions = actions
+		s.redacted = s.redacted || len(actions) > 0
+	}
+	return ""
+}
+
+// serialized returns the content that will be sent, for hashing and token
+// estimation. Never nil.
+func (s *contentScreen) serialized() []byte {
+	return joinBlocks(s.sent)
+}
+
+// bytesIn returns the size of the original content.
+func (s *contentScreen) bytesIn() int {
+	n := 0
+	for _, b := range s.blocks {
+		n += len(b.text)
+	}
+	return n
+}
+
+// bytesSent returns the size of the content as sent.
+func (s *contentScreen) bytesSent() int {
+	n := 0
+	for _, text := range s.sent {
+		n += len(text)
+	}
+	return n
+}
+
+// restoring reports whether model output may contain placeholders. True in
+// redact mode even if this request had nothing to redact, since the session
+// vault may hold placeholders from earlier requests.
+func (s *contentScreen) restoring() bool {
+	return s.mode == SensitiveModeRedact && s.redactor != nil
+}
+
+// restore replaces placeholders in model output with the original values.
+func (s *contentScreen) restore(text string) string {
+	if !s.restoring() {
+		return text
+	}
+	return s.redactor.Restore(s.sessionID, text)
+}
+
+// agentBlocks splits an agent request into classifiable blocks: the system
+// prompt, message content, tool call arguments, and tool result content.
+// Empty texts are skipped.
+func agentBlocks(req *agentllm.Request) []contentBlock {
+	if req == nil {
+		return nil
+	}
+	var blocks []contentBlock
+	if req.SystemPrompt != "" {
+		blocks = append(blocks, contentBlock{kind: BlockKindSystem, role: "system", msg: -1, text: req.SystemPrompt})
+	}
+	for i, msg := range req.Messages {
+		if msg.Content != "" {
+			blocks = append(blocks, contentBlock{kind: BlockKindMessage, role: msg.Role, msg: i, text: msg.Content})
+		}
+		for j, tc := range msg.ToolCalls {
+			if tc.Arguments != "" {
+				blocks = append(blocks, contentBlock{kind: BlockKindToolCall, role: msg.Role, msg: i, sub: j, text: tc.Arguments})
+			}
+		}
+		for j, tr := range msg.ToolResults {
+			if tr.Content != "" {
+				blocks = append(blocks, contentBlock{kind: BlockKindToolResult, role: msg.Role, msg: i, sub: j, text: tr.Content})
+			}
+		}
+	}
+	return blocks
+}
+
+// applyAgentBlocks returns a copy of req with the sent block texts. Slices
+// that change are copied so the caller's request is never mutated.
+func applyAgentBlocks(req *agentllm.Request, blocks []contentBlock, sent []string) *agentllm.Request {
+	out := *req
+	out.Messages = append([]agentllm.Message(nil), req.Messages...)
+	copiedCalls := make(map[int]bool)
+	copiedResults := make(map[int]bool)
+	for i, b := range blocks {
+		if sent[i] == b.text {
+			continue
+		}
+		switch b.kind {
+		case BlockKindSystem:
+			out.SystemPrompt = sent[i]
+		case BlockKindMessage:
+			out.Messages[b.msg].Content = sent[i]
+		case BlockKindToolCall:
+			if !copiedCalls[b.msg] {
+				out.Messages[b.msg].ToolCalls = append([]agentllm.ToolCall(nil), out.Messages[b.msg].ToolCalls...)
+				copiedCalls[b.msg] = true
+			}
+			out.Messages[b.msg].ToolCalls[b.sub].Arguments = sent[i]
+		case BlockKindToolResult:
+			if !copiedResults[b.msg] {
+				out.Messages[b.msg].ToolResults = append([]agentllm.ToolCallResult(nil), out.Messages[b.msg].ToolResults...)
+				copiedResults[b.msg] = true
+			}
+			out.Messages[b.msg].ToolResults[b.sub].Content = sent[i]
+		}
+	}
+	return &out
+}
+
+// restoreAgentResponse returns a copy of resp with placeholders in the
+// content and tool call arguments restored, so locally executed tools
+// receive the original values.
+func restoreAgentResponse(resp *agentllm.Response, screen *contentScreen) *agentllm.Response {
+	if resp == nil || !screen.restoring() {
+		return resp
+	}
+	out := *resp
+	out.Content = screen.restore(resp.Content)
+	if len(resp.ToolCalls) > 0 {
+		out.ToolCalls = append([]agentllm.ToolCall(nil), resp.ToolCalls...)
+		for i := range out.ToolCalls {
+			out.ToolCalls[i].Arguments = screen.restore(out.ToolCalls[i].Arguments)
+		}
+	}
+	return &out
+}
+
+// chatBlocks splits chat messages into classifiable blocks.
+func chatBlocks(messages []Message) []contentBlock {
+	var blocks []contentBlock
+	for i, msg := range messages {
+		if msg.Content != "" {
+			blocks = append(blocks, contentBlock{kind: BlockKindMessage, role: msg.Role, msg: i, text: msg.Content})
+		}
+	}
+	return blocks
+}
+
+// applyChatBlocks returns a copy of messages with the sent block texts.
+func applyChatBlocks(messages []Message, blocks []contentBlock, sent []string) []Message {
+	out := append([]Message(nil), messages...)
+	for i, b := range blocks {
+		out[b.msg].Content = sent[i]
+	}
+	return out
+}
+
+// joinBlocks concatenates block texts, one per line. Never nil.
+func joinBlocks(texts []string) []byte {
+	var sb strings.Builder
+	for _, text := range texts {
+		sb.WriteString(text)
+		sb.WriteByte('\n')
+	}
+	return []byte(sb.String())
+}
+
+// newAuditRecord builds the persisted record for a request.
+func newAuditRecord(decision *EgressDecision, screen *contentScreen) *AuditRecord {
+	record := &AuditRecord{
+		RequestID:   decision.RequestID,
+		SessionID:   decision.SessionID,
+		Timestamp:   decision.Timestamp,
+		Provider:    decision.Provider,
+		Model:       decision.Model,
+		Mode:        screen.mode,
+		Decision:    AuditDecisionAllowed,
+		BlockedBy:   decision.BlockedBy,
+		BlockReason: decision.BlockReason,
+		Sensitivity: decision.Sensitivity.String(),
+		ContentHash: decision.ContentHash,
+		Blocks:      screen.audits,
+		BytesIn:     screen.bytesIn(),
+		BytesSent:   screen.bytesSent(),
+		Status:      "success",
+	}
+	if record.Blocks == nil {
+		record.Blocks = []BlockAudit{}
+	}
+	switch {
+	case !decision.Allowed:
+		record.Decision = AuditDecisionBlocked
+		record.Status = "blocked"
+		record.BytesSent = 0
+	case screen.redacted:
+		record.Decision = AuditDecisionRedacted
+	}
+	return record
+}
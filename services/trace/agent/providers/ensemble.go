@@ -0,0 +1,254 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// EnsembleMember is one provider of an EnsembleClient.
+type EnsembleMember struct {
+	// Provider is the provider name (e.g. "ollama").
+	Provider string
+
+	// Client is the member's agent client.
+	Client agentllm.Client
+}
+
+// EnsembleClient fans Main-role requests out to several providers.
+//
+// Description:
+//
+//	Complete sends each request to every member concurrently and answers
+//	with the highest-priority member that succeeds, so a slow or failing
+//	provider costs no extra round trip. CompleteAll also fans out to every
+//	member but returns all candidates; the reflect phase uses it to
+//	compare final answers when a run asks for ensemble mode.
+//
+//	Every response carries the Provider and Model of the member that
+//	produced it, so token usage can be attributed per provider.
+//
+// Thread Safety: EnsembleClient is safe for concurrent use if its members are.
+type EnsembleClient struct {
+	members []EnsembleMember
+	logger  *slog.Logger
+}
+
+// NewEnsembleClient creates an EnsembleClient.
+//
+// Inputs:
+//   - members: The members in priority order. Must not be empty.
+//
+// Outputs:
+//   - *EnsembleClient: The client.
+//   - error: Non-nil if members is empty or a member has no client.
+func NewEnsembleClient(members []EnsembleMember) (*EnsembleClient, error) {
+	if len(members) == 0 {
+		return nil, fmt.Errorf("ensemble has no members")
+	}
+	for i, m := range members {
+		if m.Client == nil {
+			return nil, fmt.Errorf("ensemble member %d (%s) has no client", i, m.Provider)
+		}
+	}
+	return &EnsembleClient{
+		members: append([]EnsembleMember(nil), members...),
+		logger:  slog.Default(),
+	}, nil
+}
+
+// Complete implements agentllm.Client.
+//
+// Description:
+//
+//	Sends request to every member concurrently and returns the response
+//	of the first member, in priority order, that succeeds. This is the
+//	fan-out ensemble mode asks for: one provider failing does not fail
+//	the call while another member succeeds. Once that
+//	member and every member ahead of it have answered, the lower-priority
+//	calls still in flight are cancelled and their usage is not reported.
+//	The request's ModelOverride applies to the first member only; later
+//	members answer with their own model.
+//
+// Outputs:
+//   - *agentllm.Response: The highest-priority successful response.
+//   - error: The joined member errors if every member failed.
+func (c *EnsembleClient) Complete(ctx context.Context, request *agentllm.Request) (*agentllm.Response, error) {
+	fanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		resp *agentllm.Response
+		err  error
+	}
+	results := make([]chan result, len(c.members))
+	for i, m := range c.members {
+		req := request
+		if i > 0 {
+			req = withoutModelOverride(request)
+		}
+		results[i] = make(chan result, 1)
+		go func(out chan<- result, m EnsembleMember, req *agentllm.Request) {
+			resp, err := m.Client.Complete(fanCtx, req)
+			out <- result{resp: resp, err: err}
+		}(results[i], m, req)
+	}
+
+	var errs []error
+	for i, m := range c.members {
+		r := <-results[i]
+		if r.err == nil {
+			return c.attribute(m, r.resp), nil
+		}
+		if ctx.Err() != nil {
+			return nil, r.err
+		}
+		errs = append(errs, fmt.Errorf("%s/%s: %w", m.Provider, m.Client.Model(), r.err))
+		if i < len(c.members)-1 {
+			c.logger.WarnContext(ctx, "Ensemble member failed, using next",
+				slog.String("provider", m.Provider),
+				slog.String("model", m.Client.Model()),
+				telemetry.SensitiveLogError("error", r.err),
+			)
+		}
+	}
+	return nil, fmt.Errorf("all ensemble members failed: %w", errors.Join(errs...))
+}
+
+// CompleteAll implements agentllm.EnsembleClient.
+//
+// Description:
+//
+//	Sends request to every member concurrently and waits for all of them.
+//	ModelOverride is cleared, since each member answers with its own
+//	model. A failing member is reported in its Candidate and does not
+//	fail the call.
+//
+// Outputs:
+//   - []agentllm.Candidate: One entry per member, in member order.
+//   - error: Non-nil only if every member failed.
+func (c *EnsembleClient) CompleteAll(ctx context.Context, request *agentllm.Request) ([]agentllm.Candidate, error) {
+	req := withoutModelOverride(request)
+	candidates := make([]agentllm.Candidate, len(c.members))
+
+	var wg sync.WaitGroup
+	for i, m := range c.members {
+		wg.Add(1)
+		go func(i int, m EnsembleMember) {
+			defer wg.Done()
+			candidate := agentllm.Candidate{Provider: m.Provider, Model: m.Client.Model()}
+			resp, err := m.Client.Complete(ctx, req)
+			if err != nil {
+				candidate.Err = err
+			} else {
+				candidate.Response = c.attribute(m, resp)
+			}
+			candidates[i] = candidate
+		}(i, m)
+	}
+	wg.Wait()
+
+	var errs []error
+	for _, candidate := range candidates {
+		if candidate.Err == nil {
+			return candidates, nil
+		}
+		errs = append(errs, fmt.Errorf("%s/%s: %w", candidate.Provider, candidate.Model, candidate.Err))
+	}
+	return candidates, fmt.Errorf("all ensemble members failed: %w", errors.Join(errs...))
+}
+
+// Name implements agentllm.Client.
+func (c *EnsembleClient) Name() string { return "ensemble" }
+
+// Model implements agentllm.Client.
+//
+// Outputs:
+//   - string: The members as "provider:model" pairs, comma-separated.
+func (c *EnsembleClient) Model() string {
+	labels := make([]string, len(c.members))
+	for i, m := range c.members {
+		labels[i] = m.Provider + ":" + m.Client.Model()
+	}
+	return strings.Join(labels, ",")
+}
+
+// Members returns the ensemble's members in priority order.
+func (c *EnsembleClient) Members() []EnsembleMember {
+	return append([]EnsembleMember(nil), c.members...)
+}
+
+// attribute stamps resp with the member that produced it.
+func (c *EnsembleClient) attribute(m EnsembleMember, resp *agentllm.Response) *agentllm.Response {
+	if resp == nil {
+		return nil
+	}
+	resp.Provider = m.Provider
+	if resp.Model == "" {
+		resp.Model = m.Client.Model()
+	}
+	return resp
+}
+
+// withoutModelOverride returns request, or a shallow copy of it with
+// ModelOverride cleared.
+func withoutModelOverride(request *agentllm.Request) *agentllm.Request {
+	if request == nil || request.ModelOverride == "" {
+		return request
+	}
+	req := *request
+	req.ModelOverride = ""
+	return &req
+}
+
+// CreateEnsembleClient creates an EnsembleClient for the Main role.
+//
+// Description:
+//
+//	Creates an agent client for each config with CreateAgentClient, so
+//	every member gets the egress guard and fixture recording. A member
+//	whose client can't be created is logged and left out.
+//
+// Inputs:
+//   - cfgs: The members in priority order (RoleConfig.MainEnsemble).
+//
+// Outputs:
+//   - *EnsembleClient: The ensemble of the members that could be created.
+//   - error: Non-nil if no member could be created.
+func (f *ProviderFactory) CreateEnsembleClient(cfgs []ProviderConfig) (*EnsembleClient, error) {
+	var members []EnsembleMember
+	var errs []error
+	for _, cfg := range cfgs {
+		client, err := f.CreateAgentClient(cfg)
+		if err != nil {
+			f.logger.Warn("Ensemble member not available",
+				slog.String("provider", cfg.Provider),
+				slog.String("model", cfg.Model),
+				telemetry.SensitiveLogError("error", err),
+			)
+			errs = append(errs, fmt.Errorf("%s:%s: %w", cfg.Provider, cfg.Model, err))
+			continue
+		}
+		members = append(members, EnsembleMember{Provider: cfg.Provider, Client: client})
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("no ensemble member available: %w", errors.Join(errs...))
+	}
+	return NewEnsembleClient(members)
+}
@@ -0,0 +1,224 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+)
+
+func ensembleMock(name, model, content string) *agentllm.MockClient {
+	return agentllm.NewMockClient().WithName(name).WithModel(model).SetDefaultResponse(&agentllm.Response{
+		Content:      content,
+		OutputTokens: 10,
+	})
+}
+
+func TestParseEnsembleSpec(t *testing.T) {
+	t.Setenv("ANTHROPIC_API_KEY", "sk-test")
+	t.Setenv("OLLAMA_BASE_URL", "http://ollama:11434")
+
+	members, err := ParseEnsembleSpec(" ollama:granite4:micro-h , anthropic:claude-sonnet-4-20250514 ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(members) != 2 {
+		t.Fatalf("members = %+v", members)
+	}
+	if members[0].Provider != ProviderOllama || members[0].Model != "granite4:micro-h" || members[0].BaseURL != "http://ollama:11434" {
+		t.Errorf("members[0] = %+v", members[0])
+	}
+	if members[1].Provider != ProviderAnthropic || members[1].APIKey != "sk-test" {
+		t.Errorf("members[1] = %+v", members[1])
+	}
+
+	if members, err := ParseEnsembleSpec(""); err != nil || members != nil {
+		t.Errorf("empty spec = %v, %v", members, err)
+	}
+
+	for _, spec := range []string{
+		"ollama:glm-4.7-flash",
+		"ollama,anthropic:claude",
+		"bogus:model,ollama:glm",
+		"ollama:glm,ollama:glm",
+		"ollama:,anthropic:claude",
+	} {
+		if _, err := ParseEnsembleSpec(spec); err == nil {
+			t.Errorf("ParseEnsembleSpec(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestLoadRoleConfig_MainEnsemble(t *testing.T) {
+	t.Setenv("TRACE_MAIN_PROVIDER", "")
+	t.Setenv("TRACE_MAIN_MODEL", "")
+	t.Setenv(MainEnsembleEnv, "ollama:glm-4.7-flash,gemini:gemini-2.5-pro")
+
+	cfg, err := LoadRoleConfig("glm-4.7-flash", "granite4:micro-h", "ministral-3:3b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(cfg.MainEnsemble) != 2 || cfg.MainEnsemble[1].Provider != ProviderGemini {
+		t.Errorf("MainEnsemble = %+v", cfg.MainEnsemble)
+	}
+
+	merged := MergeSessionOverrides(cfg, "other-model", "", "")
+	merged.MainEnsemble[0].Model = "changed"
+	if cfg.MainEnsemble[0].Model != "glm-4.7-flash" {
+		t.Error("MergeSessionOverrides shares the ensemble with the base config")
+	}
+
+	t.Setenv(MainEnsembleEnv, "ollama:glm-4.7-flash")
+	if _, err := LoadRoleConfig("glm-4.7-flash", "granite4:micro-h", "ministral-3:3b"); err == nil {
+		t.Error("expected an error for a one-member ensemble")
+	}
+}
+
+func TestEnsembleClient_CompleteFailsOver(t *testing.T) {
+	primary := agentllm.NewMockClient().WithName("ollama").WithModel("glm").WithError(errors.New("connection refused"))
+	secondary := ensembleMock("anthropic", "claude", "from claude")
+	client, err := NewEnsembleClient([]EnsembleMember{
+		{Provider: ProviderOllama, Client: primary},
+		{Provider: ProviderAnthropic, Client: secondary},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Complete(context.Background(), &agentllm.Request{ModelOverride: "glm-big"})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if resp.Content != "from claude" || resp.Provider != ProviderAnthropic || resp.Model != "claude" {
+		t.Errorf("resp = %+v", resp)
+	}
+	if primary.LastRequest().ModelOverride != "glm-big" || secondary.LastRequest().ModelOverride != "" {
+		t.Error("the model override should only reach the first member")
+	}
+	if client.Model() != "ollama:glm,anthropic:claude" || client.Name() != "ensemble" {
+		t.Errorf("Name/Model = %q/%q", client.Name(), client.Model())
+	}
+}
+
+func TestEnsembleClient_CompleteFansOut(t *testing.T) {
+	primary := agentllm.NewMockClient().WithName("ollama").WithModel("glm").
+		WithError(errors.New("timeout")).WithDelay(50 * time.Millisecond)
+	secondary := ensembleMock("anthropic", "claude", "from claude").WithDelay(50 * time.Millisecond)
+	client, _ := NewEnsembleClient([]EnsembleMember{
+		{Provider: ProviderOllama, Client: primary},
+		{Provider: ProviderAnthropic, Client: secondary},
+	})
+
+	start := time.Now()
+	resp, err := client.Complete(context.Background(), &agentllm.Request{})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 90*time.Millisecond {
+		t.Errorf("members ran sequentially (%v)", elapsed)
+	}
+	if resp.Content != "from claude" || resp.Provider != ProviderAnthropic {
+		t.Errorf("resp = %+v", resp)
+	}
+
+	// Both succeed: the higher-priority member wins even when slower.
+	slow := ensembleMock("ollama", "glm", "from glm").WithDelay(30 * time.Millisecond)
+	fast := ensembleMock("anthropic", "claude", "from claude")
+	client, _ = NewEnsembleClient([]EnsembleMember{
+		{Provider: ProviderOllama, Client: slow},
+		{Provider: ProviderAnthropic, Client: fast},
+	})
+	resp, err = client.Complete(context.Background(), &agentllm.Request{})
+	if err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	if resp.Content != "from glm" || resp.Provider != ProviderOllama {
+		t.Errorf("resp = %+v, want the first member's answer", resp)
+	}
+	if fast.CallCount() != 1 {
+		t.Errorf("second member called %d times, want 1", fast.CallCount())
+	}
+}
+
+func TestEnsembleClient_CompleteAll(t *testing.T) {
+	slow := ensembleMock("ollama", "glm", "from glm").WithDelay(50 * time.Millisecond)
+	fast := ensembleMock("anthropic", "claude", "from claude").WithDelay(50 * time.Millisecond)
+	failing := agentllm.NewMockClient().WithName("gemini").WithModel("gemini-pro").WithError(errors.New("quota exceeded"))
+	client, _ := NewEnsembleClient([]EnsembleMember{
+		{Provider: ProviderOllama, Client: slow},
+		{Provider: ProviderAnthropic, Client: fast},
+		{Provider: ProviderGemini, Client: failing},
+	})
+
+	start := time.Now()
+	candidates, err := client.CompleteAll(context.Background(), &agentllm.Request{ModelOverride: "glm-big"})
+	if err != nil {
+		t.Fatalf("CompleteAll: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 90*time.Millisecond {
+		t.Errorf("members ran sequentially (%v)", elapsed)
+	}
+	if len(candidates) != 3 {
+		t.Fatalf("candidates = %+v", candidates)
+	}
+	if candidates[0].Response.Content != "from glm" || candidates[0].Response.Provider != ProviderOllama {
+		t.Errorf("candidates[0] = %+v", candidates[0].Response)
+	}
+	if candidates[1].Response.Content != "from claude" {
+		t.Errorf("candidates[1] = %+v", candidates[1].Response)
+	}
+	if candidates[2].Err == nil || candidates[2].Provider != ProviderGemini || candidates[2].Model != "gemini-pro" {
+		t.Errorf("candidates[2] = %+v", candidates[2])
+	}
+	if slow.LastRequest().ModelOverride != "" {
+		t.Error("CompleteAll should clear the model override")
+	}
+}
+
+func TestEnsembleClient_AllMembersFail(t *testing.T) {
+	client, _ := NewEnsembleClient([]EnsembleMember{
+		{Provider: ProviderOllama, Client: agentllm.NewMockClient().WithError(errors.New("down"))},
+		{Provider: ProviderOpenAI, Client: agentllm.NewMockClient().WithError(errors.New("rate limited"))},
+	})
+
+	if _, err := client.CompleteAll(context.Background(), &agentllm.Request{}); err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("CompleteAll err = %v", err)
+	}
+	if _, err := client.Complete(context.Background(), &agentllm.Request{}); err == nil || !strings.Contains(err.Error(), "down") {
+		t.Errorf("Complete err = %v", err)
+	}
+}
+
+func TestProviderFactory_CreateEnsembleClient(t *testing.T) {
+	factory := NewProviderFactory(nil)
+
+	// Ollama needs the model manager, so only the Anthropic member is created.
+	t.Setenv("ANTHROPIC_API_KEY", "sk-test")
+	client, err := factory.CreateEnsembleClient([]ProviderConfig{
+		{Provider: ProviderOllama, Model: "glm-4.7-flash"},
+		{Provider: ProviderAnthropic, Model: "claude-sonnet-4-20250514", APIKey: "sk-test"},
+	})
+	if err != nil {
+		t.Fatalf("CreateEnsembleClient: %v", err)
+	}
+	if members := client.Members(); len(members) != 1 || members[0].Provider != ProviderAnthropic {
+		t.Errorf("members = %+v", members)
+	}
+
+	if _, err := factory.CreateEnsembleClient([]ProviderConfig{{Provider: ProviderOllama, Model: "glm-4.7-flash"}}); err == nil {
+		t.Error("expected an error when no member can be created")
+	}
+}
@@ -14,9 +14,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"sync"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
 	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/egress"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/usage"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -40,6 +44,19 @@ type ProviderFactory struct {
 	// When nil, clients are returned unwrapped (no egress control).
 	egressBuilder *egress.EgressGuardBuilder
 
+	// accountant records the usage of every call made by created clients.
+	// When nil, usage is not recorded.
+	accountant *usage.Accountant
+
+	// emitter receives the context_reduction events of created agent
+	// clients. When nil, reductions are only logged.
+	emitter *events.Emitter
+
+	// fixtures caches replay fixtures by path, so every role replaying
+	// the same file shares one fixture.
+	fixturesMu sync.Mutex
+	fixtures   map[string]*replay.Fixture
+
 	logger *slog.Logger
 }
 
@@ -60,6 +77,35 @@ func WithEgressGuard(builder *egress.EgressGuardBuilder) FactoryOption {
 	}
 }
 
+// WithUsageAccountant configures the factory to report the token usage of
+// every call made by created clients (except replay clients) to accountant.
+//
+// Inputs:
+//   - accountant: The accountant. Nil disables usage recording.
+//
+// Outputs:
+//   - FactoryOption: Option to pass to NewProviderFactory.
+func WithUsageAccountant(accountant *usage.Accountant) FactoryOption {
+	return func(f *ProviderFactory) {
+		f.accountant = accountant
+	}
+}
+
+// WithEventEmitter configures the factory to emit a context_reduction
+// event whenever an agent client reduces a prompt to fit its model's
+// context window, or fails the call because it cannot.
+//
+// Inputs:
+//   - emitter: The emitter. Nil leaves reductions logged only.
+//
+// Outputs:
+//   - FactoryOption: Option to pass to NewProviderFactory.
+func WithEventEmitter(emitter *events.Emitter) FactoryOption {
+	return func(f *ProviderFactory) {
+		f.emitter = emitter
+	}
+}
+
 // NewProviderFactory creates a new ProviderFactory.
 //
 // Description:
@@ -119,7 +165,7 @@ func (f *ProviderFactory) CreateChatClient(cfg ProviderConfig) (ChatClient, erro
 		if f.ollamaModelManager == nil {
 			return nil, fmt.Errorf("Ollama model manager not available")
 		}
-		return NewOllamaChatAdapter(f.ollamaModelManager, cfg.Model), nil
+		return NewRecordingChatClient(f.withUsage(NewOllamaChatAdapter(f.ollamaModelManager, cfg.Model), cfg), cfg.Model), nil
 
 	case ProviderAnthropic:
 		if cfg.APIKey == "" {
@@ -129,7 +175,7 @@ func (f *ProviderFactory) CreateChatClient(cfg ProviderConfig) (ChatClient, erro
 		if err != nil {
 			return nil, fmt.Errorf("creating Anthropic client: %w", err)
 		}
-		return NewAnthropicChatAdapter(client), nil
+		return NewRecordingChatClient(f.withUsage(NewAnthropicChatAdapter(client), cfg), cfg.Model), nil
 
 	case ProviderOpenAI:
 		if cfg.APIKey == "" {
@@ -139,7 +185,7 @@ func (f *ProviderFactory) CreateChatClient(cfg ProviderConfig) (ChatClient, erro
 		if err != nil {
 			return nil, fmt.Errorf("creating OpenAI client: %w", err)
 		}
-		return NewOpenAIChatAdapter(client), nil
+		return NewRecordingChatClient(f.withUsage(NewOpenAIChatAdapter(client), cfg), cfg.Model), nil
 
 	case ProviderGemini:
 		if cfg.APIKey == "" {
@@ -149,13 +195,29 @@ func (f *ProviderFactory) CreateChatClient(cfg ProviderConfig) (ChatClient, erro
 		if err != nil {
 			return nil, fmt.Errorf("creating Gemini client: %w", err)
 		}
-		return NewGeminiChatAdapter(client), nil
+		return NewRecordingChatClient(f.withUsage(NewGeminiChatAdapter(client), cfg), cfg.Model), nil
+
+	case ProviderReplay:
+		fixture, err := f.Fixture(cfg.FixturePath)
+		if err != nil {
+			return nil, err
+		}
+		return NewFixtureChatClient(fixture), nil
 
 	default:
 		return nil, fmt.Errorf("unsupported provider: %q (valid: %v)", cfg.Provider, ValidProviders)
 	}
 }
 
+// withUsage wraps a chat adapter so its calls are accounted, if the
+// factory has an accountant.
+func (f *ProviderFactory) withUsage(inner ChatClient, cfg ProviderConfig) ChatClient {
+	if f.accountant == nil {
+		return inner
+	}
+	return &usageChatClient{inner: inner, provider: cfg.Provider, model: cfg.Model, accountant: f.accountant}
+}
+
 // CreateAgentClient creates an agent/llm.Client adapter for the given provider config.
 //
 // Description:
@@ -197,7 +259,8 @@ func (f *ProviderFactory) CreateAgentClient(cfg ProviderConfig) (agentllm.Client
 		if err != nil {
 			return nil, fmt.Errorf("creating Ollama client: %w", err)
 		}
-		rawClient = agentllm.NewOllamaAdapter(ollamaClient, cfg.Model)
+		rawClient = agentllm.NewOllamaAdapter(ollamaClient, cfg.Model).
+			WithModelOptions(cfg.NumCtx, cfg.KeepAlive)
 
 	case ProviderAnthropic:
 		if cfg.APIKey == "" {
@@ -229,6 +292,15 @@ func (f *ProviderFactory) CreateAgentClient(cfg ProviderConfig) (agentllm.Client
 		}
 		rawClient = agentllm.NewGeminiAgentAdapter(client, cfg.Model)
 
+	case ProviderReplay:
+		// Replayed responses never leave the process: no egress guard and
+		// nothing to record.
+		fixture, err := f.Fixture(cfg.FixturePath)
+		if err != nil {
+			return nil, err
+		}
+		return replay.NewFixtureProvider(fixture, ""), nil
+
 	default:
 		return nil, fmt.Errorf("unsupported provider: %q (valid: %v)", cfg.Provider, ValidProviders)
 	}
@@ -242,7 +314,53 @@ func (f *ProviderFactory) CreateAgentClient(cfg ProviderConfig) (agentllm.Client
 		rawClient = f.egressBuilder.WrapAgentClient(rawClient, cfg.Provider, cfg.Model, "shared-main", 0)
 	}
 
-	return rawClient, nil
+	// Account outside the egress guard, so blocked calls are not counted.
+	if f.accountant != nil {
+		rawClient = &usageAgentClient{inner: rawClient, accountant: f.accountant}
+	}
+
+	// Fit prompts to the context window before they are accounted, checked
+	// for egress or sent; an overflowing prompt never leaves the process.
+	rawClient = newContextGuardClient(rawClient, cfg, f.emitter, f.logger)
+
+	// Record outside the egress guard, so fixtures hold requests as the
+	// agent built them and replay without a guard still matches.
+	return replay.NewRecordingClient(rawClient), nil
+}
+
+// Fixture returns the replay fixture at path, loading it on first use.
+//
+// Description:
+//
+//	Fixtures are cached by path, so the main, router and param roles
+//	replaying the same file share one fixture.
+//
+// Inputs:
+//   - path: The fixture file (TRACE_FIXTURE_PATH).
+//
+// Outputs:
+//   - *replay.Fixture: The loaded fixture.
+//   - error: Non-nil if path is empty or the file can't be loaded.
+//
+// Thread Safety: Safe for concurrent use.
+func (f *ProviderFactory) Fixture(path string) (*replay.Fixture, error) {
+	if path == "" {
+		return nil, fmt.Errorf("TRACE_FIXTURE_PATH required for replay provider")
+	}
+	f.fixturesMu.Lock()
+	defer f.fixturesMu.Unlock()
+	if fixture, ok := f.fixtures[path]; ok {
+		return fixture, nil
+	}
+	fixture, err := replay.LoadFixture(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading replay fixture: %w", err)
+	}
+	if f.fixtures == nil {
+		f.fixtures = make(map[string]*replay.Fixture)
+	}
+	f.fixtures[path] = fixture
+	return fixture, nil
 }
 
 // EgressBuilder returns the egress guard builder, if configured.
@@ -279,7 +397,7 @@ func (f *ProviderFactory) WrapChatClientForEgress(
 	provider, model, sessionID, role string,
 	tokenLimit int,
 ) ChatClient {
-	if f.egressBuilder == nil {
+	if f.egressBuilder == nil || provider == ProviderReplay {
 		return client
 	}
 	// Adapt providers.ChatClient → egress.ChatClient
@@ -342,7 +460,7 @@ func (f *ProviderFactory) CreateLifecycleManager(cfg ProviderConfig) (ModelLifec
 		}
 		return NewOllamaLifecycleAdapter(f.ollamaModelManager), nil
 
-	case ProviderAnthropic, ProviderOpenAI, ProviderGemini:
+	case ProviderAnthropic, ProviderOpenAI, ProviderGemini, ProviderReplay:
 		return NewCloudLifecycleAdapter(cfg.Provider), nil
 
 	default:
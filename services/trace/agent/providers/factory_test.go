@@ -110,6 +110,50 @@ func TestLoadRoleConfig_ModelEnvOverridesFallback(t *testing.T) {
 	}
 }
 
+func TestLoadRoleConfig_Residency(t *testing.T) {
+	for _, role := range []string{RoleMain, RoleRouter, RoleParamExtractor} {
+		t.Setenv("TRACE_"+role+"_PROVIDER", "")
+		t.Setenv("TRACE_"+role+"_KEEP_ALIVE", "")
+		t.Setenv("TRACE_"+role+"_NUM_CTX", "")
+		t.Setenv("TRACE_"+role+"_CONTEXT_WINDOW", "")
+	}
+	t.Setenv("TRACE_ROUTER_KEEP_ALIVE", "2h")
+	t.Setenv("TRACE_PARAM_NUM_CTX", "8192")
+	t.Setenv("TRACE_MAIN_CONTEXT_WINDOW", "32768")
+
+	cfg, err := LoadRoleConfig("main", "router", "param")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Main.KeepAlive != DefaultKeepAlive || cfg.Main.NumCtx != DefaultMainNumCtx {
+		t.Errorf("Main = keep_alive %q num_ctx %d, want defaults", cfg.Main.KeepAlive, cfg.Main.NumCtx)
+	}
+	if cfg.Main.ContextWindowTokens() != 32768 || cfg.Router.ContextWindowTokens() != DefaultRouterNumCtx {
+		t.Errorf("context windows = main %d router %d, want 32768 and num_ctx", cfg.Main.ContextWindowTokens(), cfg.Router.ContextWindowTokens())
+	}
+	if cfg.Router.KeepAlive != "2h" || cfg.Router.NumCtx != DefaultRouterNumCtx {
+		t.Errorf("Router = keep_alive %q num_ctx %d, want 2h and %d", cfg.Router.KeepAlive, cfg.Router.NumCtx, DefaultRouterNumCtx)
+	}
+	if opts := cfg.ParamExtractor.WarmupOptions(); opts.KeepAlive != DefaultKeepAlive || opts.NumCtx != 8192 {
+		t.Errorf("ParamExtractor warmup options = %+v, want 24h and 8192", opts)
+	}
+
+	t.Setenv("TRACE_MAIN_NUM_CTX", "lots")
+	if _, err := LoadRoleConfig("main", "router", "param"); err == nil || !strings.Contains(err.Error(), "TRACE_MAIN_NUM_CTX") {
+		t.Errorf("invalid num_ctx error = %v, want TRACE_MAIN_NUM_CTX error", err)
+	}
+	t.Setenv("TRACE_MAIN_NUM_CTX", "")
+	t.Setenv("TRACE_MAIN_CONTEXT_WINDOW", "0")
+	if _, err := LoadRoleConfig("main", "router", "param"); err == nil || !strings.Contains(err.Error(), "TRACE_MAIN_CONTEXT_WINDOW") {
+		t.Errorf("invalid context window error = %v, want TRACE_MAIN_CONTEXT_WINDOW error", err)
+	}
+	t.Setenv("TRACE_MAIN_CONTEXT_WINDOW", "")
+	t.Setenv("TRACE_ROUTER_KEEP_ALIVE", "forever")
+	if _, err := LoadRoleConfig("main", "router", "param"); err == nil || !strings.Contains(err.Error(), "TRACE_ROUTER_KEEP_ALIVE") {
+		t.Errorf("invalid keep_alive error = %v, want TRACE_ROUTER_KEEP_ALIVE error", err)
+	}
+}
+
 func TestLoadRoleConfig_OllamaBaseURL(t *testing.T) {
 	t.Setenv("TRACE_MAIN_PROVIDER", "")
 	t.Setenv("TRACE_MAIN_MODEL", "")
@@ -80,10 +80,12 @@ func (a *GeminiChatAdapter) Chat(ctx context.Context, messages []Message, opts C
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
+		setChatSpanUsage(span, opts.Model, duration, messages, "")
 		recordChatMetrics("gemini", duration, err)
 		return "", err
 	}
 
+	setChatSpanUsage(span, opts.Model, duration, messages, result)
 	recordChatMetrics("gemini", duration, nil)
 	return result, nil
 }
@@ -16,6 +16,8 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // chatTracerName is the shared OTel tracer name for all ChatClient adapters.
@@ -148,3 +150,36 @@ func recordChatMetrics(provider string, duration time.Duration, err error) {
 	chatCallDuration.WithLabelValues(provider, status).Observe(duration.Seconds())
 	chatCallsTotal.WithLabelValues(provider, status).Inc()
 }
+
+// setChatSpanUsage records the model, estimated token counts and latency
+// on a Chat span.
+//
+// Description:
+//
+//	Token counts are estimated at four bytes per token because the
+//	ChatClient interface does not return provider usage. The completion
+//	count is zero on error.
+//
+// Inputs:
+//
+//	span - The Chat span.
+//	model - The requested model. Empty means the adapter default.
+//	duration - How long the call took.
+//	messages - The request messages.
+//	result - The response text.
+//
+// Thread Safety: Safe for concurrent use.
+func setChatSpanUsage(span trace.Span, model string, duration time.Duration, messages []Message, result string) {
+	promptBytes := 0
+	for _, msg := range messages {
+		promptBytes += len(msg.Content)
+	}
+	if model != "" {
+		span.SetAttributes(attribute.String("model", model))
+	}
+	span.SetAttributes(
+		attribute.Int("llm.prompt_tokens", promptBytes/4),
+		attribute.Int("llm.completion_tokens", len(result)/4),
+		attribute.Int64("llm.latency_ms", duration.Milliseconds()),
+	)
+}
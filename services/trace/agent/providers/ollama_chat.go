@@ -99,10 +99,12 @@ func (a *OllamaChatAdapter) Chat(ctx context.Context, messages []Message, opts C
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
+		setChatSpanUsage(span, model, duration, messages, "")
 		recordChatMetrics("ollama", duration, err)
 		return "", err
 	}
 
+	setChatSpanUsage(span, model, duration, messages, result)
 	recordChatMetrics("ollama", duration, nil)
 	return result, nil
 }
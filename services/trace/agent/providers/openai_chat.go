@@ -80,10 +80,12 @@ func (a *OpenAIChatAdapter) Chat(ctx context.Context, messages []Message, opts C
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
+		setChatSpanUsage(span, opts.Model, duration, messages, "")
 		recordChatMetrics("openai", duration, err)
 		return "", err
 	}
 
+	setChatSpanUsage(span, opts.Model, duration, messages, result)
 	recordChatMetrics("openai", duration, nil)
 	return result, nil
 }
@@ -0,0 +1,104 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
+)
+
+// RecordingChatClient wraps a ChatClient and records its calls when the
+// call's context carries a replay.Recorder.
+//
+// Thread Safety: Safe for concurrent use if the inner client is.
+type RecordingChatClient struct {
+	inner ChatClient
+	model string
+}
+
+// NewRecordingChatClient wraps inner. Calls without a recorder pass
+// straight through.
+//
+// Inputs:
+//   - inner: The client to record.
+//   - model: The configured model, stored in the fixture for reference.
+//
+// Outputs:
+//   - *RecordingChatClient: The wrapped client.
+func NewRecordingChatClient(inner ChatClient, model string) *RecordingChatClient {
+	return &RecordingChatClient{inner: inner, model: model}
+}
+
+// Chat implements ChatClient.
+func (c *RecordingChatClient) Chat(ctx context.Context, messages []Message, opts ChatOptions) (string, error) {
+	rec := replay.RecorderFrom(ctx)
+	if rec == nil {
+		return c.inner.Chat(ctx, messages, opts)
+	}
+	hash := replay.ChatHash(messages)
+	resp, err := c.inner.Chat(ctx, messages, opts)
+	model := c.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+	ex := replay.ChatExchange{Hash: hash, Model: model, Response: resp}
+	if err != nil {
+		ex.Error = err.Error()
+	}
+	rec.RecordChat(ex)
+	return resp, err
+}
+
+// FixtureChatClient is a ChatClient that serves replies from a fixture.
+//
+// Description:
+//
+//	Calls are matched by replay.ChatHash. A call that wasn't recorded
+//	returns an error wrapping replay.ErrFixtureMiss.
+//
+// Thread Safety: Safe for concurrent use.
+type FixtureChatClient struct {
+	fixture *replay.Fixture
+}
+
+// NewFixtureChatClient creates a chat client that replays fixture.
+//
+// Inputs:
+//   - fixture: The recorded run. Must not be nil.
+//
+// Outputs:
+//   - *FixtureChatClient: The replaying client.
+func NewFixtureChatClient(fixture *replay.Fixture) *FixtureChatClient {
+	return &FixtureChatClient{fixture: fixture}
+}
+
+// Chat implements ChatClient.
+func (c *FixtureChatClient) Chat(ctx context.Context, messages []Message, opts ChatOptions) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	hash := replay.ChatHash(messages)
+	ex, err := c.fixture.NextChat(hash)
+	if err != nil {
+		slog.ErrorContext(ctx, "Replay fixture miss for chat request",
+			slog.String("hash", hash),
+			slog.Int("messages", len(messages)),
+		)
+		return "", err
+	}
+	if ex.Error != "" {
+		return "", errors.New(ex.Error)
+	}
+	return ex.Response, nil
+}
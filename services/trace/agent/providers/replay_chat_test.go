@@ -0,0 +1,105 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
+)
+
+// stubChatClient returns a fixed reply.
+type stubChatClient struct{ reply string }
+
+func (s stubChatClient) Chat(context.Context, []Message, ChatOptions) (string, error) {
+	return s.reply, nil
+}
+
+func TestLoadRoleConfig_Replay(t *testing.T) {
+	t.Setenv("TRACE_MAIN_PROVIDER", "replay")
+	t.Setenv("TRACE_MAIN_MODEL", "")
+	t.Setenv("TRACE_ROUTER_PROVIDER", "")
+	t.Setenv("TRACE_PARAM_PROVIDER", "")
+	t.Setenv("TRACE_FIXTURE_PATH", "/fixtures/run.json")
+
+	cfg, err := LoadRoleConfig("", "granite4:micro-h", "ministral-3:3b")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.Main.Provider != ProviderReplay || cfg.Main.FixturePath != "/fixtures/run.json" || cfg.Main.Model == "" {
+		t.Errorf("Main = %+v, want replay with the fixture path and a model", cfg.Main)
+	}
+
+	t.Setenv("TRACE_FIXTURE_PATH", "")
+	if _, err := LoadRoleConfig("", "granite4:micro-h", "ministral-3:3b"); err == nil {
+		t.Error("expected an error when TRACE_FIXTURE_PATH is not set")
+	}
+}
+
+func TestProviderFactory_Replay(t *testing.T) {
+	// Record one completion and one chat call.
+	rec := replay.NewRecorder("q")
+	ctx := replay.WithRecorder(context.Background(), rec)
+
+	mock := agentllm.NewMockClient()
+	mock.QueueFinalResponse("answer")
+	req := &agentllm.Request{Messages: []agentllm.Message{{Role: "user", Content: "q"}}}
+	if _, err := replay.NewRecordingClient(mock).Complete(ctx, req); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	msgs := []Message{{Role: "user", Content: "route q"}}
+	if _, err := NewRecordingChatClient(stubChatClient{"find_symbol"}, "router").Chat(ctx, msgs, ChatOptions{}); err != nil {
+		t.Fatalf("Chat() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "run.json")
+	if err := rec.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	factory := NewProviderFactory(nil)
+	cfg := ProviderConfig{Provider: ProviderReplay, Model: ProviderReplay, FixturePath: path}
+
+	client, err := factory.CreateAgentClient(cfg)
+	if err != nil {
+		t.Fatalf("CreateAgentClient() error = %v", err)
+	}
+	resp, err := client.Complete(context.Background(), req)
+	if err != nil || resp.Content != "answer" {
+		t.Errorf("Complete() = %+v, %v, want the recorded answer", resp, err)
+	}
+
+	chat, err := factory.CreateChatClient(cfg)
+	if err != nil {
+		t.Fatalf("CreateChatClient() error = %v", err)
+	}
+	if reply, err := chat.Chat(context.Background(), msgs, ChatOptions{}); err != nil || reply != "find_symbol" {
+		t.Errorf("Chat() = %q, %v, want the recorded reply", reply, err)
+	}
+	other := []Message{{Role: "user", Content: "something else"}}
+	if _, err := chat.Chat(context.Background(), other, ChatOptions{}); !errors.Is(err, replay.ErrFixtureMiss) {
+		t.Errorf("Chat() error = %v, want ErrFixtureMiss", err)
+	}
+
+	if lm, err := factory.CreateLifecycleManager(cfg); err != nil || lm.IsLocal() {
+		t.Errorf("CreateLifecycleManager() = %v, %v, want a no-op manager", lm, err)
+	}
+	if err := CheckConnectivity(context.Background(), nil, cfg); err != nil {
+		t.Errorf("CheckConnectivity() error = %v, want nil", err)
+	}
+
+	if _, err := factory.CreateAgentClient(ProviderConfig{Provider: ProviderReplay, FixturePath: filepath.Join(t.TempDir(), "missing.json")}); err == nil {
+		t.Error("expected an error for a missing fixture")
+	}
+}
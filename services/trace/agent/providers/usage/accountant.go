@@ -0,0 +1,217 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package usage
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/google/uuid"
+)
+
+// DailyTokenLimitEnv names the per-key daily token ceiling. Unset or 0
+// disables it.
+const DailyTokenLimitEnv = "TRACE_USAGE_DAILY_TOKEN_LIMIT"
+
+// Accountant records the usage of LLM calls.
+//
+// Description:
+//
+//	Each call is attributed to the session and API key found in its
+//	context, priced, added to the context's Tally and, when a store is
+//	configured, persisted. When an API key's tokens for the UTC day cross
+//	the daily ceiling, a warning is logged and
+//	trace_usage_daily_limit_exceeded_total is incremented. The ceiling is
+//	an alert, not a quota: calls are never refused.
+//
+// Thread Safety: Safe for concurrent use.
+type Accountant struct {
+	store      Store
+	prices     *PriceTable
+	dailyLimit int
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+// AccountantOption configures an Accountant.
+type AccountantOption func(*Accountant)
+
+// WithStore persists calls to store. Without a store only run tallies
+// and metrics are kept.
+func WithStore(store Store) AccountantOption {
+	return func(a *Accountant) {
+		a.store = store
+	}
+}
+
+// WithPrices prices calls with table instead of DefaultPriceTable.
+func WithPrices(table *PriceTable) AccountantOption {
+	return func(a *Accountant) {
+		a.prices = table
+	}
+}
+
+// WithDailyTokenLimit sets the per-key daily token ceiling. <= 0 disables
+// it. Requires a store.
+func WithDailyTokenLimit(tokens int) AccountantOption {
+	return func(a *Accountant) {
+		a.dailyLimit = tokens
+	}
+}
+
+// withClock replaces time.Now for tests.
+func withClock(now func() time.Time) AccountantOption {
+	return func(a *Accountant) {
+		a.now = now
+	}
+}
+
+// NewAccountant creates an accountant.
+//
+// Inputs:
+//   - opts: Optional configuration (WithStore, WithPrices, WithDailyTokenLimit).
+//
+// Outputs:
+//   - *Accountant: The accountant. Never nil.
+func NewAccountant(opts ...AccountantOption) *Accountant {
+	a := &Accountant{
+		now:    time.Now,
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.prices == nil {
+		a.prices = DefaultPriceTable()
+	}
+	return a
+}
+
+// DailyTokenLimitFromEnv reads TRACE_USAGE_DAILY_TOKEN_LIMIT, returning 0
+// (disabled) if it is unset or not a positive integer.
+func DailyTokenLimitFromEnv() int {
+	val := os.Getenv(DailyTokenLimitEnv)
+	if val == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n < 0 {
+		slog.Warn("Invalid daily token limit, ceiling disabled",
+			slog.String("env", DailyTokenLimitEnv),
+			slog.String("value", val))
+		return 0
+	}
+	return n
+}
+
+// Record accounts for one call.
+//
+// Description:
+//
+//	Persistence errors are logged, not returned: accounting must never
+//	fail the call it describes.
+//
+// Inputs:
+//   - ctx: The call's context, carrying the session ID, API key ID and
+//     run tally.
+//   - call: The call's provider, model and token counts.
+//
+// Outputs:
+//   - *Record: The recorded entry.
+func (a *Accountant) Record(ctx context.Context, call Call) *Record {
+	record := &Record{
+		ID:               uuid.New().String(),
+		Timestamp:        a.now().UnixMilli(),
+		SessionID:        agent.SessionIDFromContext(ctx),
+		APIKeyID:         APIKeyIDFromContext(ctx),
+		Provider:         call.Provider,
+		Model:            call.Model,
+		PromptTokens:     call.PromptTokens,
+		CompletionTokens: call.CompletionTokens,
+		Estimated:        call.Estimated,
+		CostUSD:          a.prices.Cost(call),
+	}
+
+	estimated := strconv.FormatBool(call.Estimated)
+	usageTokensTotal.WithLabelValues(call.Provider, call.Model, "input", estimated).Add(float64(call.PromptTokens))
+	usageTokensTotal.WithLabelValues(call.Provider, call.Model, "output", estimated).Add(float64(call.CompletionTokens))
+	if record.CostUSD != nil {
+		usageCostUSDTotal.WithLabelValues(call.Provider, call.Model).Add(*record.CostUSD)
+	}
+
+	if tally := TallyFromContext(ctx); tally != nil {
+		tally.add(record)
+	}
+
+	if a.store == nil {
+		return record
+	}
+	// Persist even if the caller's request was canceled after the call.
+	daily, err := a.store.Append(context.WithoutCancel(ctx), record)
+	if err != nil {
+		a.logger.Warn("Failed to persist usage record",
+			slog.String("session_id", record.SessionID),
+			telemetry.SensitiveLogError("error", err))
+		return record
+	}
+	a.checkDailyLimit(record, daily)
+	return record
+}
+
+// checkDailyLimit alerts when record pushed its key's daily total over
+// the ceiling. Only the crossing call alerts, so each key alerts at most
+// once per day.
+func (a *Accountant) checkDailyLimit(record *Record, daily Totals) {
+	if a.dailyLimit <= 0 {
+		return
+	}
+	before := daily.TotalTokens - record.PromptTokens - record.CompletionTokens
+	if daily.TotalTokens < a.dailyLimit || before >= a.dailyLimit {
+		return
+	}
+	usageDailyLimitExceededTotal.Inc()
+	a.logger.Warn("API key exceeded daily token ceiling",
+		slog.String("api_key_id", record.APIKeyID),
+		slog.Int("tokens_today", daily.TotalTokens),
+		slog.Int("daily_limit", a.dailyLimit))
+}
+
+// SessionTotals returns a session's persisted totals.
+//
+// Outputs:
+//   - Totals: The session's totals.
+//   - bool: False if no store is configured or the lookup failed.
+func (a *Accountant) SessionTotals(ctx context.Context, sessionID string) (Totals, bool) {
+	if a == nil || a.store == nil {
+		return Totals{}, false
+	}
+	totals, err := a.store.SessionTotals(ctx, sessionID)
+	if err != nil {
+		a.logger.Warn("Failed to read session usage",
+			slog.String("session_id", sessionID),
+			telemetry.SensitiveLogError("error", err))
+		return Totals{}, false
+	}
+	return totals, true
+}
+
+// Store returns the accountant's store, or nil.
+func (a *Accountant) Store() Store {
+	if a == nil {
+		return nil
+	}
+	return a.store
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package usage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestAccountant_Record(t *testing.T) {
+	store := newTestStore(t)
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := NewAccountant(WithStore(store), withClock(func() time.Time { return now }))
+
+	ctx := agent.ContextWithSessionID(context.Background(), "sess-1")
+	ctx = ContextWithAPIKeyID(ctx, "key_abc")
+	ctx, tally := WithTally(ctx)
+
+	r := a.Record(ctx, Call{Provider: "anthropic", Model: "claude-sonnet-4-6", PromptTokens: 1000, CompletionTokens: 100})
+	if r.SessionID != "sess-1" || r.APIKeyID != "key_abc" {
+		t.Errorf("attribution = %s/%s, want sess-1/key_abc", r.SessionID, r.APIKeyID)
+	}
+	if r.CostUSD == nil || *r.CostUSD <= 0 {
+		t.Errorf("CostUSD = %v, want a price", r.CostUSD)
+	}
+	a.Record(ctx, Call{Provider: "ollama", Model: "glm", PromptTokens: 10, CompletionTokens: 5, Estimated: true})
+
+	run := tally.Totals()
+	if run.Calls != 2 || run.TotalTokens != 1115 || !run.Estimated {
+		t.Errorf("tally = %+v", run)
+	}
+	session, ok := a.SessionTotals(context.Background(), "sess-1")
+	if !ok || session != run {
+		t.Errorf("SessionTotals = %+v, %v; want %+v", session, ok, run)
+	}
+
+	anon := a.Record(context.Background(), Call{Provider: "ollama", Model: "glm"})
+	if anon.APIKeyID != AnonymousKeyID || anon.SessionID != "" {
+		t.Errorf("unattributed call = %s/%s, want %s and no session", anon.APIKeyID, anon.SessionID, AnonymousKeyID)
+	}
+}
+
+func TestAccountant_DailyLimit(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	a := NewAccountant(WithStore(newTestStore(t)), WithDailyTokenLimit(100), withClock(func() time.Time { return now }))
+	ctx := ContextWithAPIKeyID(context.Background(), "key_limit")
+
+	before := testutil.ToFloat64(usageDailyLimitExceededTotal)
+	call := Call{Provider: "ollama", Model: "glm", PromptTokens: 40}
+	for i := 0; i < 4; i++ { // 40, 80, 120 (crosses), 160
+		a.Record(ctx, call)
+	}
+	if got := testutil.ToFloat64(usageDailyLimitExceededTotal) - before; got != 1 {
+		t.Errorf("alerts = %v, want 1 (only the crossing call)", got)
+	}
+
+	// A new day starts from zero and can alert again.
+	now = now.Add(24 * time.Hour)
+	for i := 0; i < 3; i++ {
+		a.Record(ctx, call)
+	}
+	if got := testutil.ToFloat64(usageDailyLimitExceededTotal) - before; got != 2 {
+		t.Errorf("alerts after a new day = %v, want 2", got)
+	}
+}
+
+func TestAccountant_NoStore(t *testing.T) {
+	a := NewAccountant()
+	ctx, tally := WithTally(context.Background())
+	a.Record(ctx, Call{Provider: "ollama", Model: "glm", PromptTokens: 3, CompletionTokens: 4})
+	if tally.Totals().TotalTokens != 7 {
+		t.Errorf("tally = %+v, want 7 tokens", tally.Totals())
+	}
+	if _, ok := a.SessionTotals(ctx, "x"); ok {
+		t.Error("SessionTotals ok without a store")
+	}
+	if a.Store() != nil {
+		t.Error("Store() non-nil without a store")
+	}
+}
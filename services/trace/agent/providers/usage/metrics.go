@@ -0,0 +1,45 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package usage
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// usageTokensTotal counts tokens by provider, model and direction.
+	// Labels: provider, model, direction (input, output), estimated (true, false)
+	usageTokensTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trace",
+		Subsystem: "usage",
+		Name:      "tokens_total",
+		Help:      "Total LLM tokens by provider, model, direction and whether they were estimated",
+	}, []string{"provider", "model", "direction", "estimated"})
+
+	// usageCostUSDTotal tracks cumulative cost in US dollars of priced calls.
+	// Labels: provider, model
+	usageCostUSDTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trace",
+		Subsystem: "usage",
+		Name:      "cost_usd_total",
+		Help:      "Cumulative LLM cost in US dollars by provider and model",
+	}, []string{"provider", "model"})
+
+	// usageDailyLimitExceededTotal counts API keys crossing the daily token
+	// ceiling. Alert on any increase.
+	usageDailyLimitExceededTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "trace",
+		Subsystem: "usage",
+		Name:      "daily_limit_exceeded_total",
+		Help:      "Times an API key crossed the daily token ceiling",
+	})
+)
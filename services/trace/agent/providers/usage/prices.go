@@ -0,0 +1,190 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package usage
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// PricesEnv names the YAML file that overrides the built-in price table.
+const PricesEnv = "TRACE_USAGE_PRICES"
+
+//go:embed prices.yaml
+var defaultPricesYAML []byte
+
+// Price is the cost of a model's tokens in US dollars per million tokens.
+type Price struct {
+	InputPerMillion  float64 `yaml:"input_per_million"`
+	OutputPerMillion float64 `yaml:"output_per_million"`
+}
+
+// PriceTable is the YAML layout of a price file.
+//
+// Thread Safety: Read-only after loading; safe for concurrent use.
+type PriceTable struct {
+	// ReplaceDefaults drops the built-in prices instead of merging them.
+	ReplaceDefaults bool `yaml:"replace_defaults"`
+
+	// Providers price every model of a provider without a model price.
+	Providers map[string]Price `yaml:"providers"`
+
+	// Models price one model, or every model it is the longest prefix of.
+	Models map[string]Price `yaml:"models"`
+}
+
+// ParsePriceTable parses and validates a price file.
+//
+// Description:
+//
+//	Unknown keys are rejected so typos do not silently drop a price.
+//	Negative prices are invalid.
+//
+// Inputs:
+//   - data: YAML bytes.
+//
+// Outputs:
+//   - *PriceTable: The parsed table.
+//   - error: Non-nil if the YAML is malformed or a price is negative.
+func ParsePriceTable(data []byte) (*PriceTable, error) {
+	var table PriceTable
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&table); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse usage prices: %w", err)
+	}
+	for _, group := range []map[string]Price{table.Providers, table.Models} {
+		for name, p := range group {
+			if p.InputPerMillion < 0 || p.OutputPerMillion < 0 {
+				return nil, fmt.Errorf("price for %q must not be negative", name)
+			}
+		}
+	}
+	return &table, nil
+}
+
+// DefaultPriceTable returns the built-in prices.
+//
+// Thread Safety: Safe for concurrent use. Returns a fresh table.
+func DefaultPriceTable() *PriceTable {
+	table, err := ParsePriceTable(defaultPricesYAML)
+	if err != nil {
+		// The embedded file is covered by tests; fail soft rather than panic.
+		slog.Error("Embedded usage prices are invalid", telemetry.SensitiveLogError("error", err))
+		return &PriceTable{}
+	}
+	return table
+}
+
+// LoadPriceTable reads a price file and merges it over the defaults.
+//
+// Inputs:
+//   - filePath: Path to the YAML file.
+//
+// Outputs:
+//   - *PriceTable: The merged table, or the file alone if it sets
+//     replace_defaults.
+//   - error: Non-nil if the file cannot be read or is invalid.
+func LoadPriceTable(filePath string) (*PriceTable, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("read usage prices: %w", err)
+	}
+	file, err := ParsePriceTable(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	if file.ReplaceDefaults {
+		return file, nil
+	}
+	merged := DefaultPriceTable()
+	for name, p := range file.Providers {
+		if merged.Providers == nil {
+			merged.Providers = make(map[string]Price)
+		}
+		merged.Providers[name] = p
+	}
+	for name, p := range file.Models {
+		if merged.Models == nil {
+			merged.Models = make(map[string]Price)
+		}
+		merged.Models[name] = p
+	}
+	return merged, nil
+}
+
+// ConfiguredPriceTable returns the table named by TRACE_USAGE_PRICES,
+// falling back to the defaults with a warning if it cannot be loaded.
+func ConfiguredPriceTable() *PriceTable {
+	filePath := os.Getenv(PricesEnv)
+	if filePath == "" {
+		return DefaultPriceTable()
+	}
+	table, err := LoadPriceTable(filePath)
+	if err != nil {
+		slog.Warn("Usage price file not loaded, using built-in prices",
+			slog.String("path", filePath),
+			telemetry.SensitiveLogError("error", err))
+		return DefaultPriceTable()
+	}
+	return table
+}
+
+// Lookup returns the price of a provider's model.
+//
+// Description:
+//
+//	An exact model match wins, then the longest model name that prefixes
+//	model, then the provider's price.
+//
+// Outputs:
+//   - Price: The price.
+//   - bool: False if the table has no price for the call.
+func (t *PriceTable) Lookup(provider, model string) (Price, bool) {
+	if t == nil {
+		return Price{}, false
+	}
+	if p, ok := t.Models[model]; ok {
+		return p, true
+	}
+	best := ""
+	for name := range t.Models {
+		if len(name) > len(best) && strings.HasPrefix(model, name) {
+			best = name
+		}
+	}
+	if best != "" {
+		return t.Models[best], true
+	}
+	p, ok := t.Providers[provider]
+	return p, ok
+}
+
+// Cost returns the cost of a call in US dollars, or nil if the table has
+// no price for it.
+func (t *PriceTable) Cost(c Call) *float64 {
+	p, ok := t.Lookup(c.Provider, c.Model)
+	if !ok {
+		return nil
+	}
+	cost := (float64(c.PromptTokens)*p.InputPerMillion + float64(c.CompletionTokens)*p.OutputPerMillion) / 1e6
+	return &cost
+}
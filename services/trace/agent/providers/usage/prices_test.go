@@ -0,0 +1,95 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package usage
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDefaultPriceTable(t *testing.T) {
+	if _, err := ParsePriceTable(defaultPricesYAML); err != nil {
+		t.Fatalf("embedded prices are invalid: %v", err)
+	}
+	table := DefaultPriceTable()
+
+	tests := []struct {
+		name     string
+		provider string
+		model    string
+		want     Price
+		ok       bool
+	}{
+		{"exact model", "anthropic", "claude-sonnet-4-6", Price{3, 15}, true},
+		{"longest prefix", "openai", "gpt-4o-mini-2024-07-18", Price{0.15, 0.60}, true},
+		{"prefix", "openai", "gpt-4o-2024-08-06", Price{2.5, 10}, true},
+		{"provider fallback", "ollama", "glm-4.7-flash", Price{0, 0}, true},
+		{"unknown", "openai", "o9-preview", Price{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := table.Lookup(tt.provider, tt.model)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("Lookup(%s, %s) = %v, %v; want %v, %v", tt.provider, tt.model, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestPriceTable_Cost(t *testing.T) {
+	table := DefaultPriceTable()
+	cost := table.Cost(Call{Provider: "anthropic", Model: "claude-sonnet-4-6", PromptTokens: 1_000_000, CompletionTokens: 100_000})
+	if cost == nil || math.Abs(*cost-4.5) > 1e-9 {
+		t.Errorf("Cost = %v, want 4.5", cost)
+	}
+	if cost := table.Cost(Call{Provider: "openai", Model: "o9-preview", PromptTokens: 10}); cost != nil {
+		t.Errorf("unpriced Cost = %v, want nil", *cost)
+	}
+}
+
+func TestLoadPriceTable(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	merged, err := LoadPriceTable(write("merge.yaml", "models:\n  gpt-4o: {input_per_million: 1, output_per_million: 2}\n  in-house: {input_per_million: 0.5, output_per_million: 0.5}\n"))
+	if err != nil {
+		t.Fatalf("LoadPriceTable: %v", err)
+	}
+	if p, _ := merged.Lookup("openai", "gpt-4o"); p != (Price{1, 2}) {
+		t.Errorf("override gpt-4o = %v, want {1 2}", p)
+	}
+	if _, ok := merged.Lookup("anthropic", "claude-sonnet-4-6"); !ok {
+		t.Error("defaults dropped without replace_defaults")
+	}
+
+	replaced, err := LoadPriceTable(write("replace.yaml", "replace_defaults: true\nmodels:\n  in-house: {input_per_million: 0.5, output_per_million: 0.5}\n"))
+	if err != nil {
+		t.Fatalf("LoadPriceTable: %v", err)
+	}
+	if _, ok := replaced.Lookup("anthropic", "claude-sonnet-4-6"); ok {
+		t.Error("defaults kept with replace_defaults")
+	}
+
+	if _, err := LoadPriceTable(write("typo.yaml", "modles: {}\n")); err == nil {
+		t.Error("unknown key accepted")
+	}
+	if _, err := LoadPriceTable(write("negative.yaml", "models:\n  x: {input_per_million: -1}\n")); err == nil {
+		t.Error("negative price accepted")
+	}
+}
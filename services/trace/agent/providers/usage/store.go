@@ -0,0 +1,303 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	dgbadger "github.com/dgraph-io/badger/v4"
+)
+
+// Key prefixes namespace usage data in a shared BadgerDB.
+const (
+	recordKeyPrefix  = "usage/v1/record/"
+	dailyKeyPrefix   = "usage/v1/daily/"
+	sessionKeyPrefix = "usage/v1/session/"
+)
+
+// DefaultRetention is how long records and rollups are kept.
+const DefaultRetention = 90 * 24 * time.Hour
+
+// dayLayout formats the UTC day of daily rollups.
+const dayLayout = "2006-01-02"
+
+// Query groupings.
+const (
+	GroupByKey     = "key"
+	GroupBySession = "session"
+	GroupByModel   = "model"
+)
+
+// ErrInvalidGroupBy is returned for a Query.GroupBy that is not one of the
+// GroupBy constants.
+var ErrInvalidGroupBy = errors.New("group_by must be key, session or model")
+
+// Query selects records to roll up.
+type Query struct {
+	// From excludes records before this time. Zero means no lower bound.
+	From time.Time
+
+	// To excludes records at or after this time. Zero means no upper bound.
+	To time.Time
+
+	// GroupBy is GroupByKey, GroupBySession or GroupByModel.
+	GroupBy string
+}
+
+// Group is the usage of one key, session or model.
+type Group struct {
+	// Key is the API key ID, session ID or "provider/model".
+	Key string `json:"key"`
+
+	Totals
+}
+
+// Store persists usage records and their rollups.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type Store interface {
+	// Append persists a record and adds it to the daily and session
+	// rollups. It returns the record's API key's totals for the record's
+	// UTC day, including the record.
+	Append(ctx context.Context, record *Record) (Totals, error)
+
+	// Query rolls up the records in a time range, largest total first.
+	Query(ctx context.Context, q Query) ([]Group, error)
+
+	// SessionTotals returns a session's totals. An unknown session has
+	// zero totals.
+	SessionTotals(ctx context.Context, sessionID string) (Totals, error)
+
+	// DailyTotals returns an API key's totals for the UTC day of day.
+	DailyTotals(ctx context.Context, apiKeyID string, day time.Time) (Totals, error)
+}
+
+// BadgerStore implements Store backed by BadgerDB.
+//
+// Description:
+//
+//	Records are keyed by timestamp so a time-range query is a single
+//	range scan. Per-key daily and per-session rollups are updated in the
+//	same transaction as the record. Retention is enforced through
+//	BadgerDB's native TTL. The DB is owned by the caller and must stay
+//	open while the store is in use.
+//
+// Thread Safety: Safe for concurrent use. Appends are serialized so
+// rollup updates never conflict.
+type BadgerStore struct {
+	db        *badgerstore.DB
+	retention time.Duration
+	mu        sync.Mutex
+}
+
+// NewBadgerStore creates a store backed by db.
+//
+// Inputs:
+//   - db: Opened BadgerDB wrapper. Must not be nil.
+//   - retention: Lifetime of each record and rollup. <= 0 uses
+//     DefaultRetention.
+//
+// Outputs:
+//   - *BadgerStore: The store. Never nil.
+func NewBadgerStore(db *badgerstore.DB, retention time.Duration) *BadgerStore {
+	if db == nil {
+		panic("NewBadgerStore: db must not be nil")
+	}
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &BadgerStore{db: db, retention: retention}
+}
+
+// Append implements Store.
+func (s *BadgerStore) Append(ctx context.Context, record *Record) (Totals, error) {
+	if record == nil {
+		return Totals{}, fmt.Errorf("usage append: record must not be nil")
+	}
+	value, err := json.Marshal(record)
+	if err != nil {
+		return Totals{}, fmt.Errorf("usage encode: %w", err)
+	}
+	ts := time.UnixMilli(record.Timestamp)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var daily Totals
+	err = s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		if err := txn.SetEntry(dgbadger.NewEntry(recordKey(ts, record.ID), value).WithTTL(s.retention)); err != nil {
+			return err
+		}
+		daily, err = s.addToRollup(txn, dailyKey(record.APIKeyID, ts), record)
+		if err != nil {
+			return err
+		}
+		if record.SessionID != "" {
+			if _, err := s.addToRollup(txn, []byte(sessionKeyPrefix+record.SessionID), record); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Totals{}, fmt.Errorf("usage append: %w", err)
+	}
+	return daily, nil
+}
+
+// addToRollup adds record to the totals stored at key and returns them.
+func (s *BadgerStore) addToRollup(txn *dgbadger.Txn, key []byte, record *Record) (Totals, error) {
+	totals, err := readTotals(txn, key)
+	if err != nil {
+		return Totals{}, err
+	}
+	totals.Add(record)
+	value, err := json.Marshal(totals)
+	if err != nil {
+		return Totals{}, err
+	}
+	return totals, txn.SetEntry(dgbadger.NewEntry(key, value).WithTTL(s.retention))
+}
+
+// Query implements Store.
+func (s *BadgerStore) Query(ctx context.Context, q Query) ([]Group, error) {
+	var groupKey func(r *Record) string
+	switch q.GroupBy {
+	case GroupByKey:
+		groupKey = func(r *Record) string { return r.APIKeyID }
+	case GroupBySession:
+		groupKey = func(r *Record) string { return r.SessionID }
+	case GroupByModel:
+		groupKey = func(r *Record) string { return r.Provider + "/" + r.Model }
+	default:
+		return nil, ErrInvalidGroupBy
+	}
+
+	seek := []byte(recordKeyPrefix)
+	if !q.From.IsZero() {
+		seek = []byte(recordKeyPrefix + timeKey(q.From))
+	}
+	var until string
+	if !q.To.IsZero() {
+		until = timeKey(q.To)
+	}
+
+	groups := make(map[string]*Group)
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		opts := dgbadger.DefaultIteratorOptions
+		opts.Prefix = []byte(recordKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Seek(seek); it.ValidForPrefix(opts.Prefix); it.Next() {
+			key := strings.TrimPrefix(string(it.Item().Key()), recordKeyPrefix)
+			if until != "" && key >= until {
+				break
+			}
+			var record Record
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &record)
+			}); err != nil {
+				return err
+			}
+			k := groupKey(&record)
+			g, ok := groups[k]
+			if !ok {
+				g = &Group{Key: k}
+				groups[k] = g
+			}
+			g.Add(&record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("usage query: %w", err)
+	}
+
+	result := make([]Group, 0, len(groups))
+	for _, g := range groups {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].TotalTokens != result[j].TotalTokens {
+			return result[i].TotalTokens > result[j].TotalTokens
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// SessionTotals implements Store.
+func (s *BadgerStore) SessionTotals(ctx context.Context, sessionID string) (Totals, error) {
+	return s.readRollup(ctx, []byte(sessionKeyPrefix+sessionID))
+}
+
+// DailyTotals implements Store.
+func (s *BadgerStore) DailyTotals(ctx context.Context, apiKeyID string, day time.Time) (Totals, error) {
+	return s.readRollup(ctx, dailyKey(apiKeyID, day))
+}
+
+func (s *BadgerStore) readRollup(ctx context.Context, key []byte) (Totals, error) {
+	var totals Totals
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		var err error
+		totals, err = readTotals(txn, key)
+		return err
+	})
+	if err != nil {
+		return Totals{}, fmt.Errorf("usage rollup: %w", err)
+	}
+	return totals, nil
+}
+
+// readTotals reads the rollup at key; a missing key is zero totals.
+func readTotals(txn *dgbadger.Txn, key []byte) (Totals, error) {
+	var totals Totals
+	item, err := txn.Get(key)
+	if errors.Is(err, dgbadger.ErrKeyNotFound) {
+		return totals, nil
+	}
+	if err != nil {
+		return totals, err
+	}
+	err = item.Value(func(val []byte) error {
+		return json.Unmarshal(val, &totals)
+	})
+	return totals, err
+}
+
+// recordKey builds the key for a record. The zero-padded hex timestamp
+// keeps lexicographic and chronological order aligned.
+func recordKey(ts time.Time, id string) []byte {
+	return []byte(recordKeyPrefix + timeKey(ts) + "/" + id)
+}
+
+// dailyKey builds the key of an API key's rollup for the UTC day of ts.
+func dailyKey(apiKeyID string, ts time.Time) []byte {
+	return []byte(dailyKeyPrefix + ts.UTC().Format(dayLayout) + "/" + apiKeyID)
+}
+
+// timeKey encodes a timestamp as fixed-width hex nanoseconds.
+func timeKey(ts time.Time) string {
+	n := ts.UnixNano()
+	if n < 0 {
+		n = 0
+	}
+	s := strconv.FormatInt(n, 16)
+	return strings.Repeat("0", 16-len(s)) + s
+}
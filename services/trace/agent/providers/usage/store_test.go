@@ -0,0 +1,119 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package usage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+)
+
+func newTestStore(t *testing.T) *BadgerStore {
+	t.Helper()
+	db, err := badgerstore.OpenDB(badgerstore.InMemoryConfig())
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return NewBadgerStore(db, time.Hour)
+}
+
+func TestBadgerStore(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 23, 58, 0, 0, time.UTC)
+	cost := 0.01
+
+	records := []Record{
+		{SessionID: "s1", APIKeyID: "key_a", Provider: "anthropic", Model: "claude-sonnet-4-6", PromptTokens: 100, CompletionTokens: 10, CostUSD: &cost},
+		{SessionID: "s1", APIKeyID: "key_a", Provider: "ollama", Model: "glm", PromptTokens: 50, CompletionTokens: 5, Estimated: true},
+		{SessionID: "s2", APIKeyID: "key_b", Provider: "anthropic", Model: "claude-sonnet-4-6", PromptTokens: 20, CompletionTokens: 2, CostUSD: &cost},
+		// The next UTC day.
+		{SessionID: "s1", APIKeyID: "key_a", Provider: "anthropic", Model: "claude-sonnet-4-6", PromptTokens: 1000, CompletionTokens: 100, CostUSD: &cost},
+	}
+	var daily []Totals
+	for i := range records {
+		r := &records[i]
+		r.ID = fmt.Sprintf("r%d", i)
+		r.Timestamp = base.Add(time.Duration(i) * time.Minute).UnixMilli()
+		totals, err := store.Append(ctx, r)
+		if err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+		daily = append(daily, totals)
+	}
+
+	if daily[1].TotalTokens != 165 || daily[3].TotalTokens != 1100 {
+		t.Errorf("daily totals after append = %d, %d; want 165 (same day), 1100 (next day)", daily[1].TotalTokens, daily[3].TotalTokens)
+	}
+
+	t.Run("session rollup", func(t *testing.T) {
+		s1, err := store.SessionTotals(ctx, "s1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s1.Calls != 3 || s1.TotalTokens != 1265 || !s1.Estimated || s1.UnpricedCalls != 1 {
+			t.Errorf("s1 = %+v", s1)
+		}
+		if unknown, _ := store.SessionTotals(ctx, "nope"); unknown.Calls != 0 {
+			t.Errorf("unknown session = %+v, want zero", unknown)
+		}
+	})
+
+	t.Run("daily rollup", func(t *testing.T) {
+		day, err := store.DailyTotals(ctx, "key_a", base)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if day.Calls != 2 || day.PromptTokens != 150 || day.CompletionTokens != 15 {
+			t.Errorf("key_a on %s = %+v", base.Format(dayLayout), day)
+		}
+	})
+
+	tests := []struct {
+		name string
+		q    Query
+		want map[string]int // group key -> total tokens
+	}{
+		{"by key", Query{GroupBy: GroupByKey}, map[string]int{"key_a": 1265, "key_b": 22}},
+		{"by session", Query{GroupBy: GroupBySession}, map[string]int{"s1": 1265, "s2": 22}},
+		{"by model", Query{GroupBy: GroupByModel}, map[string]int{"anthropic/claude-sonnet-4-6": 1232, "ollama/glm": 55}},
+		{"time range", Query{GroupBy: GroupByKey, From: base.Add(time.Minute), To: base.Add(3 * time.Minute)}, map[string]int{"key_a": 55, "key_b": 22}},
+		{"empty range", Query{GroupBy: GroupByKey, From: base.Add(time.Hour)}, map[string]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups, err := store.Query(ctx, tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(groups) != len(tt.want) {
+				t.Fatalf("groups = %+v, want %v", groups, tt.want)
+			}
+			for i, g := range groups {
+				if tt.want[g.Key] != g.TotalTokens {
+					t.Errorf("%s = %d tokens, want %d", g.Key, g.TotalTokens, tt.want[g.Key])
+				}
+				if i > 0 && groups[i-1].TotalTokens < g.TotalTokens {
+					t.Errorf("groups not sorted by total: %+v", groups)
+				}
+			}
+		})
+	}
+
+	if _, err := store.Query(ctx, Query{GroupBy: "day"}); !errors.Is(err, ErrInvalidGroupBy) {
+		t.Errorf("group_by=day error = %v, want ErrInvalidGroupBy", err)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package usage records token usage and cost for every LLM call and rolls
+// it up per API key, per session and per day.
+//
+// Provider clients created by the ProviderFactory report each call to an
+// Accountant, which prices it with a PriceTable, adds it to the run's
+// Tally (if the context carries one) and persists it to a Store.
+package usage
+
+import (
+	"context"
+	"sync"
+)
+
+// AnonymousKeyID is the API key ID recorded for calls made without an
+// API key (unauthenticated servers and background work).
+const AnonymousKeyID = "anonymous"
+
+// Call is one LLM call as reported by a provider client.
+type Call struct {
+	// Provider is the provider name (anthropic, openai, gemini, ollama).
+	Provider string
+
+	// Model is the model that served the call.
+	Model string
+
+	// PromptTokens is the input token count.
+	PromptTokens int
+
+	// CompletionTokens is the output token count.
+	CompletionTokens int
+
+	// Estimated is true when the counts are estimated from the text
+	// rather than reported by the provider.
+	Estimated bool
+}
+
+// Record is the persisted usage entry for one LLM call.
+type Record struct {
+	ID               string   `json:"id"`
+	Timestamp        int64    `json:"timestamp"`
+	SessionID        string   `json:"session_id,omitempty"`
+	APIKeyID         string   `json:"api_key_id"`
+	Provider         string   `json:"provider"`
+	Model            string   `json:"model"`
+	PromptTokens     int      `json:"prompt_tokens"`
+	CompletionTokens int      `json:"completion_tokens"`
+	Estimated        bool     `json:"estimated,omitempty"`
+	CostUSD          *float64 `json:"cost_usd,omitempty"`
+}
+
+// Totals is the usage summed over a set of calls.
+type Totals struct {
+	// Calls is the number of LLM calls.
+	Calls int `json:"calls"`
+
+	// PromptTokens is the summed input token count.
+	PromptTokens int `json:"prompt_tokens"`
+
+	// CompletionTokens is the summed output token count.
+	CompletionTokens int `json:"completion_tokens"`
+
+	// TotalTokens is PromptTokens + CompletionTokens.
+	TotalTokens int `json:"total_tokens"`
+
+	// CostUSD is the summed cost of the calls that have a price.
+	CostUSD float64 `json:"cost_usd"`
+
+	// UnpricedCalls counts calls whose model has no price, so CostUSD
+	// does not include them.
+	UnpricedCalls int `json:"unpriced_calls,omitempty"`
+
+	// Estimated is true if any call's token counts were estimated.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// Add adds one record to the totals.
+func (t *Totals) Add(r *Record) {
+	t.Calls++
+	t.PromptTokens += r.PromptTokens
+	t.CompletionTokens += r.CompletionTokens
+	t.TotalTokens += r.PromptTokens + r.CompletionTokens
+	if r.CostUSD != nil {
+		t.CostUSD += *r.CostUSD
+	} else {
+		t.UnpricedCalls++
+	}
+	if r.Estimated {
+		t.Estimated = true
+	}
+}
+
+// Merge adds other's totals to t.
+func (t *Totals) Merge(other Totals) {
+	t.Calls += other.Calls
+	t.PromptTokens += other.PromptTokens
+	t.CompletionTokens += other.CompletionTokens
+	t.TotalTokens += other.TotalTokens
+	t.CostUSD += other.CostUSD
+	t.UnpricedCalls += other.UnpricedCalls
+	t.Estimated = t.Estimated || other.Estimated
+}
+
+// Tally sums the usage of one agent run.
+//
+// Description:
+//
+//	A handler attaches a Tally to the run's context with WithTally; the
+//	Accountant adds every call made with that context, including calls
+//	from the router and parameter extractor.
+//
+// Thread Safety: Safe for concurrent use.
+type Tally struct {
+	mu     sync.Mutex
+	totals Totals
+}
+
+// Totals returns a copy of the run's totals so far.
+func (t *Tally) Totals() Totals {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.totals
+}
+
+func (t *Tally) add(r *Record) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.totals.Add(r)
+}
+
+type tallyKey struct{}
+
+type apiKeyIDKey struct{}
+
+// WithTally returns a context carrying a new Tally.
+//
+// Outputs:
+//   - context.Context: The derived context.
+//   - *Tally: The tally calls made with the context are added to.
+func WithTally(ctx context.Context) (context.Context, *Tally) {
+	t := &Tally{}
+	return context.WithValue(ctx, tallyKey{}, t), t
+}
+
+// TallyFromContext returns the context's Tally, or nil.
+func TallyFromContext(ctx context.Context) *Tally {
+	if ctx == nil {
+		return nil
+	}
+	t, _ := ctx.Value(tallyKey{}).(*Tally)
+	return t
+}
+
+// ContextWithAPIKeyID returns a context whose calls are attributed to the
+// API key with the given ID (a hash, never the key itself).
+func ContextWithAPIKeyID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, apiKeyIDKey{}, id)
+}
+
+// APIKeyIDFromContext returns the API key ID calls made with ctx are
+// attributed to, or AnonymousKeyID.
+func APIKeyIDFromContext(ctx context.Context) string {
+	if ctx != nil {
+		if id, _ := ctx.Value(apiKeyIDKey{}).(string); id != "" {
+			return id
+		}
+	}
+	return AnonymousKeyID
+}
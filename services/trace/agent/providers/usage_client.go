@@ -0,0 +1,101 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/usage"
+)
+
+// usageAgentClient reports every successful completion to an Accountant.
+//
+// Description:
+//
+//	Token counts come from the response: the provider's reported usage
+//	when it has one, otherwise the adapter's estimate, in which case the
+//	record is marked estimated.
+//
+// Thread Safety: Safe for concurrent use if the inner client is.
+type usageAgentClient struct {
+	inner      agentllm.Client
+	accountant *usage.Accountant
+}
+
+// Complete implements agentllm.Client.
+func (c *usageAgentClient) Complete(ctx context.Context, request *agentllm.Request) (*agentllm.Response, error) {
+	resp, err := c.inner.Complete(ctx, request)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+	provider := resp.Provider
+	if provider == "" {
+		provider = c.inner.Name()
+	}
+	model := resp.Model
+	if model == "" {
+		model = c.inner.Model()
+	}
+	c.accountant.Record(ctx, usage.Call{
+		Provider:         provider,
+		Model:            model,
+		PromptTokens:     resp.InputTokens,
+		CompletionTokens: resp.OutputTokens,
+		Estimated:        resp.TokensEstimated,
+	})
+	return resp, nil
+}
+
+// Name implements agentllm.Client.
+func (c *usageAgentClient) Name() string { return c.inner.Name() }
+
+// Model implements agentllm.Client.
+func (c *usageAgentClient) Model() string { return c.inner.Model() }
+
+// usageChatClient reports every successful chat to an Accountant.
+//
+// Description:
+//
+//	ChatClient returns only text, so token counts are always estimated
+//	(~4 characters per token) and the record is marked estimated.
+//
+// Thread Safety: Safe for concurrent use if the inner client is.
+type usageChatClient struct {
+	inner      ChatClient
+	provider   string
+	model      string
+	accountant *usage.Accountant
+}
+
+// Chat implements ChatClient.
+func (c *usageChatClient) Chat(ctx context.Context, messages []Message, opts ChatOptions) (string, error) {
+	resp, err := c.inner.Chat(ctx, messages, opts)
+	if err != nil {
+		return resp, err
+	}
+	model := c.model
+	if opts.Model != "" {
+		model = opts.Model
+	}
+	prompt := 0
+	for _, m := range messages {
+		prompt += len(m.Content)
+	}
+	c.accountant.Record(ctx, usage.Call{
+		Provider:         c.provider,
+		Model:            model,
+		PromptTokens:     prompt / 4,
+		CompletionTokens: len(resp) / 4,
+		Estimated:        true,
+	})
+	return resp, nil
+}
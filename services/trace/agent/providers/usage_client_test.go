@@ -0,0 +1,73 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package providers
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/usage"
+)
+
+func TestUsageAgentClient(t *testing.T) {
+	mock := agentllm.NewMockClient().WithName("anthropic").WithModel("claude-sonnet-4-6")
+	mock.QueueResponse(&agentllm.Response{Content: "a", InputTokens: 1000, OutputTokens: 200})
+	mock.QueueResponse(&agentllm.Response{Content: "b", InputTokens: 10, OutputTokens: 2, TokensEstimated: true})
+	client := &usageAgentClient{inner: mock, accountant: usage.NewAccountant()}
+
+	ctx, tally := usage.WithTally(context.Background())
+	for i := 0; i < 2; i++ {
+		if _, err := client.Complete(ctx, &agentllm.Request{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := tally.Totals()
+	if got.Calls != 2 || got.PromptTokens != 1010 || got.CompletionTokens != 202 {
+		t.Errorf("tally = %+v, want 2 calls, 1010 prompt, 202 completion", got)
+	}
+	if !got.Estimated {
+		t.Error("Estimated = false after an estimated response")
+	}
+	// claude-sonnet-4-6 is priced at $3/$15 per million tokens.
+	if want := (1010*3.0 + 202*15.0) / 1e6; math.Abs(got.CostUSD-want) > 1e-12 || got.UnpricedCalls != 0 {
+		t.Errorf("cost = %v (%d unpriced), want %v", got.CostUSD, got.UnpricedCalls, want)
+	}
+
+	failing := &usageAgentClient{inner: agentllm.NewMockClient().WithError(errors.New("down")), accountant: usage.NewAccountant()}
+	ctx, tally = usage.WithTally(context.Background())
+	if _, err := failing.Complete(ctx, &agentllm.Request{}); err == nil {
+		t.Fatal("error not returned")
+	}
+	if tally.Totals().Calls != 0 {
+		t.Error("failed call was accounted")
+	}
+}
+
+func TestUsageChatClient(t *testing.T) {
+	f := NewProviderFactory(nil, WithUsageAccountant(usage.NewAccountant()))
+	client := f.withUsage(stubChatClient{reply: "12345678"}, ProviderConfig{Provider: ProviderOpenAI, Model: "gpt-4o"})
+
+	ctx, tally := usage.WithTally(context.Background())
+	if _, err := client.Chat(ctx, []Message{{Role: "user", Content: "0123456789abcdef"}}, ChatOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	got := tally.Totals()
+	if got.PromptTokens != 4 || got.CompletionTokens != 2 || !got.Estimated {
+		t.Errorf("tally = %+v, want estimated 4 prompt and 2 completion tokens", got)
+	}
+
+	if plain := NewProviderFactory(nil).withUsage(stubChatClient{}, ProviderConfig{}); plain != (stubChatClient{}) {
+		t.Error("client wrapped without an accountant")
+	}
+}
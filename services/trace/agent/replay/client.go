@@ -0,0 +1,191 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+// ProviderName is the provider name reported by FixtureProvider.
+const ProviderName = "replay"
+
+// RecordingClient wraps an llm.Client and records its completions when the
+// call's context carries a Recorder (see WithRecorder).
+//
+// Thread Safety: Safe for concurrent use if the inner client is.
+type RecordingClient struct {
+	inner llm.Client
+}
+
+// NewRecordingClient wraps inner. Calls without a Recorder pass straight
+// through.
+func NewRecordingClient(inner llm.Client) *RecordingClient {
+	return &RecordingClient{inner: inner}
+}
+
+// Complete implements llm.Client.
+func (c *RecordingClient) Complete(ctx context.Context, request *llm.Request) (*llm.Response, error) {
+	rec := RecorderFrom(ctx)
+	if rec == nil {
+		return c.inner.Complete(ctx, request)
+	}
+	hash := RequestHash(request)
+	resp, err := c.inner.Complete(ctx, request)
+	ex := LLMExchange{Hash: hash, Model: c.inner.Model(), Response: resp}
+	if err != nil {
+		ex.Response = nil
+		ex.Error = err.Error()
+	}
+	rec.RecordLLM(ex)
+	return resp, err
+}
+
+// Name implements llm.Client.
+func (c *RecordingClient) Name() string { return c.inner.Name() }
+
+// Model implements llm.Client.
+func (c *RecordingClient) Model() string { return c.inner.Model() }
+
+// FixtureProvider is an llm.Client that serves completions from a fixture.
+//
+// Description:
+//
+//	Each request is looked up by RequestHash. A request that wasn't
+//	recorded returns an error wrapping ErrFixtureMiss and is logged at
+//	error level, so a diverging run fails instead of silently drifting.
+//
+// Thread Safety: Safe for concurrent use.
+type FixtureProvider struct {
+	fixture *Fixture
+	model   string
+}
+
+// NewFixtureProvider creates a provider that replays fixture.
+//
+// Inputs:
+//
+//	fixture - The recorded run. Must not be nil.
+//	model - Reported by Model(). Empty uses the first recorded model.
+func NewFixtureProvider(fixture *Fixture, model string) *FixtureProvider {
+	if model == "" && len(fixture.LLM) > 0 {
+		model = fixture.LLM[0].Model
+	}
+	return &FixtureProvider{fixture: fixture, model: model}
+}
+
+// Complete implements llm.Client.
+func (p *FixtureProvider) Complete(ctx context.Context, request *llm.Request) (*llm.Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	hash := RequestHash(request)
+	ex, err := p.fixture.NextLLM(hash)
+	if err != nil {
+		slog.ErrorContext(ctx, "Replay fixture miss for LLM request",
+			slog.String("hash", hash),
+			slog.Int("messages", len(request.Messages)),
+		)
+		return nil, err
+	}
+	if ex.Error != "" {
+		return nil, errors.New(ex.Error)
+	}
+	resp := *ex.Response
+	return &resp, nil
+}
+
+// Name implements llm.Client.
+func (p *FixtureProvider) Name() string { return ProviderName }
+
+// Model implements llm.Client.
+func (p *FixtureProvider) Model() string { return p.model }
+
+// ToolInterceptor records tool executions and, given a fixture, replays
+// them.
+//
+// Description:
+//
+//	Implements tools.ExecutionInterceptor. When the context carries a
+//	Recorder, every execution is recorded. When constructed with a
+//	fixture, tools don't run at all: results come from the fixture by
+//	ToolHash, and a miss returns an error wrapping ErrFixtureMiss.
+//
+//	Replayed results are decoded from JSON, so Result.Output holds
+//	generic maps and slices rather than the tool's own types.
+//
+// Thread Safety: Safe for concurrent use.
+type ToolInterceptor struct {
+	fixture *Fixture
+}
+
+// NewToolInterceptor creates an interceptor. A nil fixture only records.
+func NewToolInterceptor(fixture *Fixture) *ToolInterceptor {
+	return &ToolInterceptor{fixture: fixture}
+}
+
+// Intercept implements tools.ExecutionInterceptor.
+func (t *ToolInterceptor) Intercept(ctx context.Context, invocation *tools.Invocation,
+	execute func(context.Context, *tools.Invocation) (*tools.Result, error)) (*tools.Result, error) {
+	hash := ToolHash(invocation.ToolName, invocation.Parameters)
+	if t.fixture != nil {
+		return t.replay(ctx, hash, invocation)
+	}
+
+	rec := RecorderFrom(ctx)
+	if rec == nil {
+		return execute(ctx, invocation)
+	}
+	params := copyParams(invocation.Parameters)
+	result, err := execute(ctx, invocation)
+	ex := ToolExchange{Hash: hash, Tool: invocation.ToolName, Params: params, Result: result}
+	if err != nil {
+		ex.Result = nil
+		ex.Error = err.Error()
+	}
+	rec.RecordTool(ex)
+	return result, err
+}
+
+// replay serves one invocation from the fixture.
+func (t *ToolInterceptor) replay(ctx context.Context, hash string, invocation *tools.Invocation) (*tools.Result, error) {
+	ex, err := t.fixture.NextTool(hash)
+	if err != nil {
+		slog.ErrorContext(ctx, "Replay fixture miss for tool execution",
+			slog.String("tool", invocation.ToolName),
+			slog.String("hash", hash),
+		)
+		return nil, err
+	}
+	if ex.Error != "" {
+		return nil, errors.New(ex.Error)
+	}
+	result := *ex.Result
+	invocation.Result = &result
+	return &result, nil
+}
+
+// copyParams returns a shallow copy of params, taken before the executor
+// coerces them in place.
+func copyParams(params map[string]any) map[string]any {
+	if params == nil {
+		return nil
+	}
+	out := make(map[string]any, len(params))
+	for k, v := range params {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,384 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package replay records agent runs to fixture files and replays them
+// deterministically.
+//
+// In record mode, a Recorder attached to the run's context collects every
+// LLM completion, chat call and tool execution, keyed by a hash of its
+// input. In replay mode, FixtureProvider serves LLM completions from a
+// fixture and ToolInterceptor serves tool executions, so a run needs no
+// live provider (and, with tool replay, no graph tools either). A request
+// whose hash isn't in the fixture fails with ErrFixtureMiss.
+package replay
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	agenttypes "github.com/AleutianAI/AleutianFOSS/services/trace/agent/types"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+// FixtureVersion is the current fixture file format version.
+const FixtureVersion = 1
+
+// ErrFixtureMiss is returned in replay mode when a request has no recorded
+// response, i.e. the run diverged from the recording.
+var ErrFixtureMiss = errors.New("replay fixture miss")
+
+// Fixture is a recorded agent run.
+//
+// Exchanges are stored in call order. Requests with equal hashes are
+// replayed in the order they were recorded, starting over after the last.
+type Fixture struct {
+	// Version is the file format version (FixtureVersion).
+	Version int `json:"version"`
+
+	// Query is the recorded run's query, for reference.
+	Query string `json:"query,omitempty"`
+
+	// RecordedAt is when the recording finished.
+	RecordedAt time.Time `json:"recorded_at"`
+
+	// LLM holds main agent completions.
+	LLM []LLMExchange `json:"llm"`
+
+	// Chat holds router and parameter extractor chat calls.
+	Chat []ChatExchange `json:"chat,omitempty"`
+
+	// Tools holds tool executions.
+	Tools []ToolExchange `json:"tools,omitempty"`
+
+	mu      sync.Mutex
+	cursors map[string]int
+}
+
+// LLMExchange is one recorded agent completion.
+type LLMExchange struct {
+	// Hash identifies the request; see RequestHash.
+	Hash string `json:"hash"`
+
+	// Model is the model that served the request.
+	Model string `json:"model,omitempty"`
+
+	// Response is the completion. Nil if the call failed.
+	Response *llm.Response `json:"response,omitempty"`
+
+	// Error is the call's error message, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// ChatExchange is one recorded chat call.
+type ChatExchange struct {
+	// Hash identifies the request; see ChatHash.
+	Hash string `json:"hash"`
+
+	// Model is the requested model.
+	Model string `json:"model,omitempty"`
+
+	// Response is the assistant's reply.
+	Response string `json:"response"`
+
+	// Error is the call's error message, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// ToolExchange is one recorded tool execution.
+type ToolExchange struct {
+	// Hash identifies the invocation; see ToolHash.
+	Hash string `json:"hash"`
+
+	// Tool is the tool name.
+	Tool string `json:"tool"`
+
+	// Params are the invocation parameters, for reference.
+	Params map[string]any `json:"params,omitempty"`
+
+	// Result is the tool result. Nil if execution failed.
+	Result *tools.Result `json:"result,omitempty"`
+
+	// Error is the execution error message, if it failed.
+	Error string `json:"error,omitempty"`
+}
+
+// LoadFixture reads a fixture file.
+//
+// Inputs:
+//
+//	path - The fixture file.
+//
+// Outputs:
+//
+//	*Fixture - The fixture, ready for replay.
+//	error - Non-nil if the file can't be read or has another version.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+	var f Fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", path, err)
+	}
+	if f.Version != FixtureVersion {
+		return nil, fmt.Errorf("fixture %s has version %d, want %d", path, f.Version, FixtureVersion)
+	}
+	return &f, nil
+}
+
+// Save writes the fixture to path, creating parent directories.
+//
+// Thread Safety: Must not run concurrently with recording into f.
+func (f *Fixture) Save(path string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding fixture: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating fixture directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing fixture: %w", err)
+	}
+	return nil
+}
+
+// next returns the index of the next exchange with hash among n
+// exchanges, using hashAt to read them, or -1 if none has that hash. Once
+// every exchange with hash has been served, serving starts over, so the
+// same run can be replayed repeatedly against one fixture.
+func (f *Fixture) next(kind, hash string, n int, hashAt func(int) string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cursors == nil {
+		f.cursors = make(map[string]int)
+	}
+	key := kind + ":" + hash
+	start := f.cursors[key]
+	for j := 0; j < n; j++ {
+		i := (start + j) % n
+		if hashAt(i) == hash {
+			f.cursors[key] = i + 1
+			return i
+		}
+	}
+	return -1
+}
+
+// NextLLM returns the next recorded completion for hash.
+//
+// Outputs:
+//
+//	*LLMExchange - The exchange.
+//	error - Wraps ErrFixtureMiss if nothing was recorded for hash.
+//
+// Thread Safety: Safe for concurrent use.
+func (f *Fixture) NextLLM(hash string) (*LLMExchange, error) {
+	i := f.next("llm", hash, len(f.LLM), func(i int) string { return f.LLM[i].Hash })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: no LLM response recorded for request %s", ErrFixtureMiss, hash)
+	}
+	return &f.LLM[i], nil
+}
+
+// NextChat returns the next recorded chat reply for hash.
+//
+// Outputs:
+//
+//	*ChatExchange - The exchange.
+//	error - Wraps ErrFixtureMiss if nothing was recorded for hash.
+//
+// Thread Safety: Safe for concurrent use.
+func (f *Fixture) NextChat(hash string) (*ChatExchange, error) {
+	i := f.next("chat", hash, len(f.Chat), func(i int) string { return f.Chat[i].Hash })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: no chat response recorded for request %s", ErrFixtureMiss, hash)
+	}
+	return &f.Chat[i], nil
+}
+
+// NextTool returns the next recorded execution for hash.
+//
+// Outputs:
+//
+//	*ToolExchange - The exchange.
+//	error - Wraps ErrFixtureMiss if nothing was recorded for hash.
+//
+// Thread Safety: Safe for concurrent use.
+func (f *Fixture) NextTool(hash string) (*ToolExchange, error) {
+	i := f.next("tool", hash, len(f.Tools), func(i int) string { return f.Tools[i].Hash })
+	if i < 0 {
+		return nil, fmt.Errorf("%w: no tool result recorded for invocation %s", ErrFixtureMiss, hash)
+	}
+	return &f.Tools[i], nil
+}
+
+// Recorder collects the exchanges of one run.
+//
+// Thread Safety: Safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	fixture Fixture
+}
+
+// NewRecorder creates an empty recorder.
+//
+// Inputs:
+//
+//	query - The run's query, stored in the fixture for reference.
+func NewRecorder(query string) *Recorder {
+	return &Recorder{fixture: Fixture{Version: FixtureVersion, Query: query}}
+}
+
+// RecordLLM appends a completion.
+func (r *Recorder) RecordLLM(ex LLMExchange) {
+	r.mu.Lock()
+	r.fixture.LLM = append(r.fixture.LLM, ex)
+	r.mu.Unlock()
+}
+
+// RecordChat appends a chat call.
+func (r *Recorder) RecordChat(ex ChatExchange) {
+	r.mu.Lock()
+	r.fixture.Chat = append(r.fixture.Chat, ex)
+	r.mu.Unlock()
+}
+
+// RecordTool appends a tool execution.
+func (r *Recorder) RecordTool(ex ToolExchange) {
+	r.mu.Lock()
+	r.fixture.Tools = append(r.fixture.Tools, ex)
+	r.mu.Unlock()
+}
+
+// Fixture returns a copy of everything recorded so far.
+func (r *Recorder) Fixture() *Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &Fixture{
+		Version:    r.fixture.Version,
+		Query:      r.fixture.Query,
+		RecordedAt: time.Now().UTC(),
+		LLM:        append([]LLMExchange(nil), r.fixture.LLM...),
+		Chat:       append([]ChatExchange(nil), r.fixture.Chat...),
+		Tools:      append([]ToolExchange(nil), r.fixture.Tools...),
+	}
+}
+
+// Save writes everything recorded so far to path.
+func (r *Recorder) Save(path string) error {
+	return r.Fixture().Save(path)
+}
+
+// recorderKey is the context key for the run's Recorder.
+type recorderKey struct{}
+
+// WithRecorder returns a context that records the run's LLM, chat and tool
+// calls into rec.
+func WithRecorder(ctx context.Context, rec *Recorder) context.Context {
+	return context.WithValue(ctx, recorderKey{}, rec)
+}
+
+// RecorderFrom returns the context's Recorder, or nil when not recording.
+func RecorderFrom(ctx context.Context) *Recorder {
+	rec, _ := ctx.Value(recorderKey{}).(*Recorder)
+	return rec
+}
+
+// requestKey is the hashed view of an llm.Request. IDs, timing and
+// sampling settings are left out so that equal prompts hash equally.
+type requestKey struct {
+	SystemPrompt string          `json:"system_prompt"`
+	Messages     []messageKey    `json:"messages"`
+	Tools        []string        `json:"tools,omitempty"`
+	ToolChoice   *llm.ToolChoice `json:"tool_choice,omitempty"`
+	Model        string          `json:"model,omitempty"`
+}
+
+// messageKey is the hashed view of an llm.Message.
+type messageKey struct {
+	Role        string      `json:"role"`
+	Content     string      `json:"content"`
+	ToolCalls   [][2]string `json:"tool_calls,omitempty"`
+	ToolResults []string    `json:"tool_results,omitempty"`
+}
+
+// RequestHash returns the prompt hash of an agent completion request.
+//
+// Description:
+//
+//	Covers the system prompt, messages (roles, content, tool call names
+//	and arguments, tool result content), offered tool names, tool choice
+//	and model override. Tool call IDs are excluded since providers
+//	generate them.
+func RequestHash(req *llm.Request) string {
+	key := requestKey{
+		SystemPrompt: req.SystemPrompt,
+		ToolChoice:   req.ToolChoice,
+		Model:        req.ModelOverride,
+	}
+	for _, t := range req.Tools {
+		key.Tools = append(key.Tools, t.Name)
+	}
+	for _, m := range req.Messages {
+		mk := messageKey{Role: m.Role, Content: m.Content}
+		for _, tc := range m.ToolCalls {
+			mk.ToolCalls = append(mk.ToolCalls, [2]string{tc.Name, tc.Arguments})
+		}
+		for _, tr := range m.ToolResults {
+			content := tr.Content
+			if tr.IsError {
+				content = "error: " + content
+			}
+			mk.ToolResults = append(mk.ToolResults, content)
+		}
+		key.Messages = append(key.Messages, mk)
+	}
+	return hashJSON(key)
+}
+
+// ChatHash returns the prompt hash of a chat request's messages. The model
+// is left out so a replay needn't be configured with the recorded models.
+func ChatHash(messages []agenttypes.Message) string {
+	key := make([][2]string, 0, len(messages))
+	for _, m := range messages {
+		key = append(key, [2]string{m.Role, m.Content})
+	}
+	return hashJSON(key)
+}
+
+// ToolHash returns the hash of a tool invocation's name and parameters.
+func ToolHash(tool string, params map[string]any) string {
+	return hashJSON(struct {
+		Tool   string         `json:"tool"`
+		Params map[string]any `json:"params"`
+	}{tool, params})
+}
+
+// hashJSON returns the hex SHA-256 of v's JSON encoding. Map keys are
+// sorted by encoding/json, so the encoding is canonical.
+func hashJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%#v", v))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
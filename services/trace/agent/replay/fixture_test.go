@@ -0,0 +1,129 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+func TestRequestHash_IgnoresToolCallIDs(t *testing.T) {
+	build := func(id string) *llm.Request {
+		return &llm.Request{
+			SystemPrompt: "system",
+			Messages: []llm.Message{
+				{Role: "user", Content: "q"},
+				{Role: "assistant", ToolCalls: []llm.ToolCall{{ID: id, Name: "find_symbol", Arguments: `{"name":"x"}`}}},
+				{Role: "user", ToolResults: []llm.ToolCallResult{{ToolCallID: id, Content: "found"}}},
+			},
+			Temperature: 0.7,
+		}
+	}
+	if RequestHash(build("call_1")) != RequestHash(build("call_2")) {
+		t.Error("hash should not depend on tool call IDs")
+	}
+
+	changed := build("call_1")
+	changed.Messages[2].ToolResults[0].IsError = true
+	if RequestHash(changed) == RequestHash(build("call_1")) {
+		t.Error("hash should depend on whether a tool result is an error")
+	}
+}
+
+func TestFixture_ServesInOrderAndStartsOver(t *testing.T) {
+	f := &Fixture{LLM: []LLMExchange{
+		{Hash: "a", Response: &llm.Response{Content: "a1"}},
+		{Hash: "b", Response: &llm.Response{Content: "b1"}},
+		{Hash: "a", Response: &llm.Response{Content: "a2"}},
+	}}
+
+	var got []string
+	for i := 0; i < 3; i++ {
+		ex, err := f.NextLLM("a")
+		if err != nil {
+			t.Fatalf("NextLLM() error = %v", err)
+		}
+		got = append(got, ex.Response.Content)
+	}
+	if got[0] != "a1" || got[1] != "a2" || got[2] != "a1" {
+		t.Errorf("served %v, want [a1 a2 a1]", got)
+	}
+
+	if _, err := f.NextLLM("missing"); !errors.Is(err, ErrFixtureMiss) {
+		t.Errorf("NextLLM(missing) error = %v, want ErrFixtureMiss", err)
+	}
+}
+
+func TestLoadFixture_RejectsOtherVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "f.json")
+	if err := os.WriteFile(path, []byte(`{"version": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFixture(path); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+}
+
+func TestToolInterceptor_RecordsBeforeCoercion(t *testing.T) {
+	rec := NewRecorder("q")
+	ctx := WithRecorder(context.Background(), rec)
+	inv := &tools.Invocation{ToolName: "find_symbol", Parameters: map[string]any{"limit": "5"}}
+
+	_, err := NewToolInterceptor(nil).Intercept(ctx, inv, func(ctx context.Context, inv *tools.Invocation) (*tools.Result, error) {
+		inv.Parameters["limit"] = 5
+		return nil, errors.New("boom")
+	})
+	if err == nil {
+		t.Fatal("expected the execution error to pass through")
+	}
+
+	f := rec.Fixture()
+	if len(f.Tools) != 1 {
+		t.Fatalf("recorded %d tool exchanges, want 1", len(f.Tools))
+	}
+	ex := f.Tools[0]
+	if ex.Hash != ToolHash("find_symbol", map[string]any{"limit": "5"}) || ex.Params["limit"] != "5" {
+		t.Errorf("recorded %+v, want the uncoerced parameters", ex)
+	}
+	if ex.Error != "boom" || ex.Result != nil {
+		t.Errorf("recorded error %q result %v, want boom and nil", ex.Error, ex.Result)
+	}
+
+	// Replaying returns the recorded error without running the tool.
+	inv = &tools.Invocation{ToolName: "find_symbol", Parameters: map[string]any{"limit": "5"}}
+	_, err = NewToolInterceptor(f).Intercept(context.Background(), inv, func(context.Context, *tools.Invocation) (*tools.Result, error) {
+		t.Fatal("tool ran during replay")
+		return nil, nil
+	})
+	if err == nil || err.Error() != "boom" {
+		t.Errorf("replay error = %v, want boom", err)
+	}
+}
+
+func TestRecordingClient_PassesThroughWithoutRecorder(t *testing.T) {
+	mock := llm.NewMockClient()
+	mock.QueueFinalResponse("hi")
+	client := NewRecordingClient(mock)
+
+	resp, err := client.Complete(context.Background(), &llm.Request{Messages: []llm.Message{{Role: "user", Content: "q"}}})
+	if err != nil || resp.Content != "hi" {
+		t.Fatalf("Complete() = %v, %v", resp, err)
+	}
+	if client.Name() != mock.Name() || client.Model() != mock.Model() {
+		t.Error("Name and Model should come from the inner client")
+	}
+}
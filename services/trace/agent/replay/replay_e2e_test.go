@@ -0,0 +1,180 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package replay_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/phases"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+const e2eQuery = "What does the parseConfig function do?"
+
+// phaseAdapter adapts a phases.Phase to agent.PhaseExecutor.
+type phaseAdapter struct {
+	phase phases.Phase
+}
+
+func (a phaseAdapter) Execute(ctx context.Context, deps any) (agent.AgentState, error) {
+	return a.phase.Execute(ctx, deps.(*phases.Dependencies))
+}
+
+func (a phaseAdapter) Name() string { return a.phase.Name() }
+
+// stubGraph is a GraphProvider that is always initialized.
+type stubGraph struct{}
+
+func (stubGraph) Initialize(context.Context, string) (string, error) { return "graph-1", nil }
+func (stubGraph) IsAvailable() bool                                  { return true }
+func (stubGraph) EnrichmentTraceStep(string) *crs.TraceStep          { return nil }
+
+// depsFactory builds phase dependencies around one LLM client and tool.
+type depsFactory struct {
+	client      llm.Client
+	tool        tools.Tool
+	interceptor tools.ExecutionInterceptor
+}
+
+func (f *depsFactory) Create(session *agent.Session, query string) (any, error) {
+	registry := tools.NewRegistry()
+	registry.Register(f.tool)
+	return &phases.Dependencies{
+		Session:       session,
+		Query:         query,
+		Context:       session.GetCurrentContext(),
+		EventEmitter:  events.NewEmitter(),
+		GraphProvider: stubGraph{},
+		LLMClient:     f.client,
+		ToolRegistry:  registry,
+		ToolExecutor:  tools.NewExecutorWithOptions(registry, nil, tools.WithExecutionInterceptor(f.interceptor)),
+		SafetyGate:    safety.NewMockGate(),
+	}, nil
+}
+
+// runAgent runs e2eQuery through the Init/Plan/Execute/Reflect phases.
+func runAgent(t *testing.T, ctx context.Context, factory *depsFactory) *agent.RunResult {
+	t.Helper()
+	registry := agent.NewPhaseRegistry()
+	registry.Register(agent.StateInit, phaseAdapter{phases.NewInitPhase()})
+	registry.Register(agent.StatePlan, phaseAdapter{phases.NewPlanPhase()})
+	// Reflect after every step so the run passes through REFLECT.
+	registry.Register(agent.StateExecute, phaseAdapter{phases.NewExecutePhase(phases.WithReflectionThreshold(1))})
+	registry.Register(agent.StateReflect, phaseAdapter{phases.NewReflectPhase()})
+	loop := agent.NewDefaultAgentLoop(
+		agent.WithPhaseRegistry(registry),
+		agent.WithDependenciesFactory(factory),
+	)
+
+	session, err := agent.NewSession("/test/project", nil)
+	if err != nil {
+		t.Fatalf("NewSession() error = %v", err)
+	}
+	session.Config.ToolRouterEnabled = false
+
+	result, err := loop.Run(ctx, session, e2eQuery)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	return result
+}
+
+// newLookupTool returns a tool whose output is counted in *calls.
+func newLookupTool(calls *int) *tools.MockTool {
+	tool := tools.NewMockTool("find_symbol", tools.CategoryExploration)
+	tool.ExecuteFunc = func(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
+		*calls++
+		return &tools.Result{Success: true, OutputText: "parseConfig reads config.yaml into Config"}, nil
+	}
+	return tool
+}
+
+func TestReplay_EndToEnd(t *testing.T) {
+	// Record a run against a scripted model and a live tool.
+	mock := llm.NewMockClient()
+	mock.QueueToolCall("find_symbol", map[string]any{"name": "parseConfig"})
+	mock.QueueFinalResponse("parseConfig reads config.yaml and returns a Config.")
+
+	toolCalls := 0
+	recorder := replay.NewRecorder(e2eQuery)
+	recorded := runAgent(t, replay.WithRecorder(context.Background(), recorder), &depsFactory{
+		client:      replay.NewRecordingClient(mock),
+		tool:        newLookupTool(&toolCalls),
+		interceptor: replay.NewToolInterceptor(nil),
+	})
+	if recorded.State != agent.StateComplete {
+		t.Fatalf("recorded run ended in %s (error %+v), want COMPLETE", recorded.State, recorded.Error)
+	}
+	if toolCalls != 1 {
+		t.Fatalf("tool ran %d times while recording, want 1", toolCalls)
+	}
+
+	path := filepath.Join(t.TempDir(), "run.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	fixture, err := replay.LoadFixture(path)
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+	if len(fixture.LLM) < 2 || len(fixture.Tools) != 1 {
+		t.Fatalf("fixture has %d LLM and %d tool exchanges, want >= 2 and 1", len(fixture.LLM), len(fixture.Tools))
+	}
+
+	// Replay hermetically: no model, and the tool must not run.
+	replayed := runAgent(t, context.Background(), &depsFactory{
+		client:      replay.NewFixtureProvider(fixture, ""),
+		tool:        newLookupTool(&toolCalls),
+		interceptor: replay.NewToolInterceptor(fixture),
+	})
+	if toolCalls != 1 {
+		t.Errorf("tool ran during replay")
+	}
+	if replayed.State != recorded.State || replayed.Response != recorded.Response {
+		t.Errorf("replay = %s %q, want %s %q", replayed.State, replayed.Response, recorded.State, recorded.Response)
+	}
+	if replayed.StepsTaken != recorded.StepsTaken {
+		t.Errorf("replay took %d steps, recording took %d", replayed.StepsTaken, recorded.StepsTaken)
+	}
+}
+
+func TestReplay_EndToEnd_DivergingRunFails(t *testing.T) {
+	mock := llm.NewMockClient()
+	mock.QueueFinalResponse("parseConfig reads config.yaml.")
+
+	toolCalls := 0
+	recorder := replay.NewRecorder(e2eQuery)
+	runAgent(t, replay.WithRecorder(context.Background(), recorder), &depsFactory{
+		client:      replay.NewRecordingClient(mock),
+		tool:        newLookupTool(&toolCalls),
+		interceptor: replay.NewToolInterceptor(nil),
+	})
+	fixture := recorder.Fixture()
+
+	// Change the prompt: the first completion no longer matches.
+	provider := replay.NewFixtureProvider(fixture, "")
+	_, err := provider.Complete(context.Background(), &llm.Request{
+		SystemPrompt: "a different prompt",
+		Messages:     []llm.Message{{Role: "user", Content: e2eQuery}},
+	})
+	if !errors.Is(err, replay.ErrFixtureMiss) {
+		t.Fatalf("Complete() error = %v, want ErrFixtureMiss", err)
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // RouterAdapter adapts a routing.ToolRouter to the agent.ToolRouter interface.
@@ -53,7 +54,7 @@ func NewRouterAdapter(router ToolRouter) agent.ToolRouter {
 func (a *RouterAdapter) SelectTool(ctx context.Context, query string, availableTools []agent.ToolRouterSpec, codeContext *agent.ToolRouterCodeContext) (*agent.ToolRouterSelection, error) {
 	slog.Info("CB-31d RouterAdapter.SelectTool CALLED",
 		slog.Int("num_tools", len(availableTools)),
-		slog.String("query_preview", truncateForLog(query, 100)),
+		telemetry.SensitiveLog("query_preview", truncateForLog(query, 100)),
 		slog.Bool("has_code_context", codeContext != nil),
 		slog.String("router_model", a.router.Model()),
 	)
@@ -119,7 +120,7 @@ func (a *RouterAdapter) SelectTool(ctx context.Context, query string, availableT
 	selection, err := a.router.SelectTool(ctx, query, routingSpecs, routingContext)
 	if err != nil {
 		slog.Error("CB-31d RouterAdapter.SelectTool FAILED",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, err
 	}
@@ -14,27 +14,47 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"math"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"golang.org/x/sync/errgroup"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
 // Tool Embedding Cache (IT-06c Option I)
 // =============================================================================
 
-// toolEmbeddingWarmConcurrency is the number of parallel Ollama calls during warm-up.
+// toolEmbeddingWarmConcurrency is the default number of parallel Ollama calls
+// during warm-up (ROUTING_EMBED_CONCURRENCY overrides it).
 // 10 concurrent requests saturates Ollama without overwhelming it.
 const toolEmbeddingWarmConcurrency = 10
 
+// toolEmbeddingWarmBatchSize is the default number of tool documents sent in
+// one /api/embed call during warm-up (ROUTING_EMBED_BATCH_SIZE overrides it).
+// One batched call replaces 16 sequential model invocations on a busy Ollama.
+const toolEmbeddingWarmBatchSize = 16
+
+// toolEmbeddingMaxAttempts is the number of tries per warm-up embed call.
+// Only 429, 5xx and timeouts are retried.
+const toolEmbeddingMaxAttempts = 4
+
+// toolEmbeddingRetryBase is the backoff before the first warm-up retry. It
+// doubles per attempt, and each wait is jittered to 50–100% of its value so
+// concurrent callers do not retry in lockstep.
+const toolEmbeddingRetryBase = 250 * time.Millisecond
+
 // toolEmbeddingQueryTimeout is the per-query embedding call timeout.
 // Score() is on the hot path; 3 seconds is ample for a local Ollama call.
 const toolEmbeddingQueryTimeout = 3 * time.Second
@@ -45,6 +65,27 @@ type ollamaEmbedReq struct {
 	Input string `json:"input"`
 }
 
+// ollamaEmbedBatchReq is the Ollama /api/embed request body with several
+// inputs; the response holds one embedding per input, in order.
+type ollamaEmbedBatchReq struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// errBatchUnsupported reports that the embedding endpoint rejected a
+// multi-input request or answered it with the wrong number of embeddings.
+var errBatchUnsupported = errors.New("embedding endpoint does not support batched input")
+
+// embedStatusError is a non-200 response from the embedding endpoint.
+type embedStatusError struct {
+	status int
+	body   string
+}
+
+func (e *embedStatusError) Error() string {
+	return fmt.Sprintf("embed service returned %d: %s", e.status, e.body)
+}
+
 // ollamaEmbedResp is the Ollama /api/embed response body.
 type ollamaEmbedResp struct {
 	Embeddings [][]float32 `json:"embeddings"`
@@ -61,15 +102,30 @@ type ollamaEmbedResp struct {
 // to the find_references tool vector — regardless of exact word form.
 //
 // The cache calls Ollama's /api/embed endpoint (nomic-embed-text-v2-moe by
-// default) in parallel during Warm(). If Ollama is unavailable, the cache
-// degrades gracefully: Score() returns (nil, nil) and the hybrid scorer
-// falls back to BM25-only mode.
+// default) during Warm(), sending several tool documents per request and
+// running a bounded number of requests in parallel. Endpoints that reject
+// batched input are detected on the first batch and served one document
+// per request instead. If Ollama is unavailable, the cache degrades
+// gracefully: Score() returns (nil, nil) and the hybrid scorer falls back
+// to BM25-only mode.
 //
 // GR-61: Vectors are persisted in BadgerDB (via RouterCacheStore) between
-// service restarts. The corpus hash (SHA256 of tool specs + model name)
-// serves as the cache key, providing automatic invalidation when the tool
-// registry or model changes. If the store is nil, the cache operates in
-// in-memory-only mode (no persistence).
+// service restarts. If the store also implements ToolEmbeddingStore, each
+// tool vector is persisted on its own, keyed by the hash of the tool's
+// embedding document: a warm-up that embeds only some tools keeps them, and
+// the next Warm() embeds only the missing or changed tools. Otherwise the
+// corpus hash (SHA256 of tool specs + model name) keys one entry for all
+// tools. If the store is nil, the cache operates in in-memory-only mode (no
+// persistence).
+//
+// The vector dimension is recorded at warm-up. A query embedding with a
+// different dimension (the model behind the endpoint changed) is treated
+// like an unavailable embedding instead of producing garbage scores.
+//
+// With EnableHashFallback, a warm-up that embeds no tool via Ollama switches
+// to locally computed hash embeddings (see hashEmbed); queries are then
+// embedded locally too and Model() reports HashEmbeddingModel. Fallback
+// vectors are never persisted.
 //
 // # Thread Safety
 //
@@ -78,19 +134,46 @@ type ToolEmbeddingCache struct {
 	mu      sync.RWMutex
 	vectors map[string][]float32 // tool name → unit-normalized embedding vector
 	warmed  bool
+	dim     int  // dimension of the cached vectors; 0 until warmed
+	hashed  bool // true when vectors come from the hash fallback
+
+	hashFallback   bool        // fall back to hash embeddings if Ollama warm-up fails
+	dimMismatchLog atomic.Bool // dimension mismatch already logged
+
+	batchSize        int           // tool documents per warm-up request; 1 disables batching
+	concurrency      int           // maximum in-flight warm-up requests
+	retryBase        time.Duration // backoff before the first warm-up retry
+	batchUnsupported atomic.Bool   // endpoint rejected batched input
 
 	url    string // Ollama /api/embed endpoint URL
 	model  string // embedding model name
 	client *http.Client
 	logger *slog.Logger
 	store  RouterCacheStore // BadgerDB persistence; nil = in-memory-only
+
+	// Corpus tracking, guarded by mu. corpusHash and docHashes describe the
+	// corpus the vectors were embedded from; target is the corpus last
+	// requested. While the two hashes differ, a background re-embed is
+	// pending and scoring degrades to BM25 (see Refresh).
+	corpusHash   string
+	docHashes    map[string]string // tool name → embedding document hash
+	target       []ToolSpec
+	targetHash   string
+	pending      []ToolSpec // corpus queued for the re-embed loop; nil when none
+	pendingForce bool       // the queued re-embed ignores cached vectors
+	refreshing   bool       // the re-embed loop is running
+
+	warmMu sync.Mutex // serializes Warm and re-embeds
 }
 
 // NewToolEmbeddingCache creates an unwarmed embedding cache.
 //
 // # Description
 //
-// Reads EMBEDDING_SERVICE_URL and EMBEDDING_MODEL from the environment.
+// Reads EMBEDDING_SERVICE_URL from the environment and the model name from
+// ResolveRoutingEmbedModel (ROUTING_EMBED_MODEL, then EMBEDDING_MODEL).
+// ROUTING_EMBED_BATCH_SIZE and ROUTING_EMBED_CONCURRENCY set the warm-up
+// batch size and parallelism; invalid values use the defaults.
 // Call Warm() to pre-compute tool embeddings before the cache can score queries.
 //
 // GR-61: If store is non-nil, Warm() will check the BadgerDB cache before
@@ -122,10 +205,7 @@ func NewToolEmbeddingCache(logger *slog.Logger, store RouterCacheStore) *ToolEmb
 		url = "http://localhost:11434/api/embed"
 	}
 
-	model := os.Getenv("EMBEDDING_MODEL")
-	if model == "" {
-		model = "nomic-embed-text-v2-moe"
-	}
+	model := ResolveRoutingEmbedModel()
 
 	return &ToolEmbeddingCache{
 		vectors: make(map[string][]float32),
@@ -134,22 +214,41 @@ func NewToolEmbeddingCache(logger *slog.Logger, store RouterCacheStore) *ToolEmb
 		client: &http.Client{
 			Timeout: 30 * time.Second, // warm-up can be slow; query timeout set per-call
 		},
-		logger: logger,
-		store:  store,
+		logger:      logger,
+		store:       store,
+		batchSize:   envPositiveInt("ROUTING_EMBED_BATCH_SIZE", toolEmbeddingWarmBatchSize),
+		concurrency: envPositiveInt("ROUTING_EMBED_CONCURRENCY", toolEmbeddingWarmConcurrency),
+		retryBase:   toolEmbeddingRetryBase,
 	}
 }
 
+// EnableHashFallback makes Warm fall back to local hash embeddings when no
+// tool could be embedded via Ollama.
+//
+// # Thread Safety
+//
+// Not safe to call concurrently with Warm. Call before warm-up.
+func (c *ToolEmbeddingCache) EnableHashFallback() {
+	c.hashFallback = true
+}
+
 // Warm pre-computes and caches an embedding vector for every tool spec.
 //
 // # Description
 //
-// Builds an embedding document for each tool (name + keywords + use_when),
-// then calls Ollama in parallel (up to toolEmbeddingWarmConcurrency concurrent
-// requests). Vectors are stored unit-normalized for efficient cosine similarity.
+// Builds an embedding document for each tool (name + keywords + use_when).
+// Vectors already persisted in the store are loaded first; only the
+// remaining tools are sent to Ollama, in batches of up to batchSize
+// documents with at most concurrency requests in flight. Calls failing with
+// 429, 5xx or a timeout are retried with jittered exponential backoff. Vectors are stored unit-normalized for efficient cosine
+// similarity.
 //
 // If any single tool fails to embed, a warning is logged and that tool is
-// skipped — it will receive score 0 from Score(). If all tools fail, warmed
-// remains false and Score() degrades gracefully.
+// skipped — it will receive score 0 from Score(). The vectors that were
+// computed are still persisted, so the next Warm() fills only the gaps.
+// If all tools fail, warmed remains false and Score() degrades gracefully,
+// unless the hash fallback is enabled, in which case every tool is
+// embedded locally.
 //
 // # Inputs
 //
@@ -158,107 +257,139 @@ func NewToolEmbeddingCache(logger *slog.Logger, store RouterCacheStore) *ToolEmb
 //
 // # Outputs
 //
-//   - error: Non-nil if the Ollama endpoint is completely unreachable. Partial
-//     failures (individual tools) are logged as warnings, not returned as errors.
+//   - error: Always nil. Failures (individual tools or the whole endpoint)
+//     are logged as warnings and leave the affected tools unembedded.
 //
 // # Thread Safety
 //
-// Not safe to call concurrently. Call once at service startup.
+// Call once at service startup. Serialized with background re-embeds; later
+// corpus changes go through Refresh.
 func (c *ToolEmbeddingCache) Warm(ctx context.Context, specs []ToolSpec) error {
 	if len(specs) == 0 {
 		return nil
 	}
+	c.warmMu.Lock()
+	defer c.warmMu.Unlock()
 
 	// GR-61 Step 8: Check BadgerDB cache before calling Ollama.
 	// The corpus hash captures all signals that determine vector shape:
 	// tool names, BestFor keywords, UseWhen text, and embedding model name.
 	// Any change produces a different hash → automatic cache miss → fresh warm-up.
 	corpusHash := computeCorpusHash(specs, c.model)
-	if c.store != nil {
-		cached, err := c.store.LoadEmbeddings(ctx, corpusHash)
-		if err != nil {
-			c.logger.Warn("embedding cache: store load failed, continuing with Ollama warm-up",
-				slog.String("error", err.Error()),
-			)
-		} else if len(cached) > 0 {
-			c.mu.Lock()
-			for name, vec := range cached {
-				c.vectors[name] = vec // already unit-normalized on save
-			}
-			c.warmed = true
-			c.mu.Unlock()
-			c.logger.Info("embedding cache: loaded from BadgerDB (skipping Ollama warm-up)",
-				slog.Int("tool_count", len(cached)),
-				slog.String("corpus_hash", shortHash(corpusHash)),
-			)
-			return nil
+	toolStore, _ := c.store.(ToolEmbeddingStore)
+	docHashes := toolDocHashes(specs)
+	c.checkActiveCorpus(ctx, corpusHash)
+	cached, migrate := c.loadPersisted(ctx, corpusHash, toolStore, docHashes)
+
+	c.mu.Lock()
+	for name, vec := range cached {
+		c.vectors[name] = vec // already unit-normalized on save
+		c.dim = len(vec)      // the store guarantees one dimension per load
+	}
+	c.corpusHash, c.docHashes = corpusHash, docHashes
+	if c.targetHash == "" {
+		c.target, c.targetHash = specs, corpusHash
+	}
+	c.mu.Unlock()
+
+	pending := make([]ToolSpec, 0, len(specs))
+	for _, spec := range specs {
+		if _, ok := cached[spec.Name]; !ok {
+			pending = append(pending, spec)
+		}
+	}
+
+	if len(pending) == 0 {
+		c.mu.Lock()
+		c.warmed = len(c.vectors) > 0
+		c.mu.Unlock()
+		c.logger.Info("embedding cache: loaded from BadgerDB (skipping Ollama warm-up)",
+			slog.Int("tool_count", len(cached)),
+			slog.String("corpus_hash", shortHash(corpusHash)),
+		)
+		if migrate {
+			c.persist(ctx, corpusHash, toolStore, docHashes, cached)
 		}
+		c.activate(ctx, corpusHash, docHashes)
+		return nil
 	}
 
 	c.logger.Info("embedding cache: starting Ollama warm-up",
-		slog.Int("tool_count", len(specs)),
+		slog.Int("tool_count", len(pending)),
+		slog.Int("cached_tools", len(cached)),
+		slog.Int("batch_size", c.batchSize),
+		slog.Int("concurrency", c.concurrency),
 		slog.String("url", c.url),
 		slog.String("model", c.model),
 	)
 
-	type result struct {
-		name   string
-		vector []float32
+	names := make([]string, len(pending))
+	docs := make([]string, len(pending))
+	for i, spec := range pending {
+		names[i] = spec.Name
+		docs[i] = buildEmbeddingDoc(spec)
 	}
-
-	resultCh := make(chan result, len(specs))
-	g, gctx := errgroup.WithContext(ctx)
-
-	// Semaphore to limit concurrency.
-	sem := make(chan struct{}, toolEmbeddingWarmConcurrency)
-
-	for _, spec := range specs {
-		s := spec // capture
-		g.Go(func() error {
-			sem <- struct{}{}
-			defer func() { <-sem }()
-
-			doc := buildEmbeddingDoc(s)
-			vec, err := c.embed(gctx, doc)
-			if err != nil {
-				c.logger.Warn("embedding cache: failed to embed tool",
-					slog.String("tool", s.Name),
-					slog.String("error", err.Error()),
-				)
-				// Individual failure is not fatal.
-				return nil
-			}
-
-			resultCh <- result{name: s.Name, vector: vec}
-			return nil
-		})
-	}
-
-	if err := g.Wait(); err != nil {
-		return fmt.Errorf("embedding cache warm-up: %w", err)
-	}
-	close(resultCh)
+	embedded := c.embedDocs(ctx, names, docs)
 
 	c.mu.Lock()
-	for r := range resultCh {
-		norm := l2Norm(r.vector)
-		if norm > 0 {
+	computed := make(map[string][]float32, len(pending))
+	for i, vec := range embedded {
+		name := pending[i].Name
+		if vec == nil {
+			continue
+		}
+		if c.dim == 0 {
+			c.dim = len(vec)
+		}
+		if len(vec) != c.dim {
+			c.logger.Warn("embedding cache: tool vector dimension mismatch, skipping",
+				slog.String("tool", name),
+				slog.Int("dim", len(vec)),
+				slog.Int("want_dim", c.dim),
+			)
+			continue
+		}
+		if normalized := unitNormalize(vec); normalized != nil {
 			// Store unit-normalized vector so cosine = dot product at query time.
-			normalized := make([]float32, len(r.vector))
-			for i, v := range r.vector {
-				normalized[i] = v / float32(norm)
-			}
-			c.vectors[r.name] = normalized
+			c.vectors[name] = normalized
+			computed[name] = normalized
 		}
 	}
 	c.warmed = len(c.vectors) > 0
 
+	if !c.warmed && c.hashFallback {
+		for _, spec := range specs {
+			if normalized := unitNormalize(hashEmbed(buildEmbeddingDoc(spec))); normalized != nil {
+				c.vectors[spec.Name] = normalized
+			}
+		}
+		c.dim = hashEmbeddingDim
+		c.hashed = true
+		c.warmed = len(c.vectors) > 0
+		c.mu.Unlock()
+		c.logger.Warn("embedding cache: Ollama unavailable, using hash fallback embeddings",
+			slog.Int("embedded_tools", len(c.vectors)),
+			slog.String("model", HashEmbeddingModel),
+		)
+		return nil
+	}
+
 	// Capture embeddedCount and snapshot vectors under lock, then release before
 	// the BadgerDB write. Avoids holding the lock during a potentially slow I/O
-	// call, and ensures the log below reads a consistent value.
+	// call, and ensures the log below reads a consistent value. Per-tool stores
+	// only need the newly computed vectors (plus migrated ones); the corpus
+	// entry needs all of them.
 	embeddedCount := len(c.vectors)
 	var toSave map[string][]float32
-	if c.warmed && c.store != nil {
+	switch {
+	case toolStore != nil && migrate:
+		toSave = make(map[string][]float32, len(c.vectors))
+		for k, v := range c.vectors {
+			toSave[k] = v
+		}
+	case toolStore != nil:
+		toSave = computed
+	case c.warmed && c.store != nil:
 		toSave = make(map[string][]float32, len(c.vectors))
 		for k, v := range c.vectors {
 			toSave[k] = v
@@ -266,37 +397,168 @@ func (c *ToolEmbeddingCache) Warm(ctx context.Context, specs []ToolSpec) error {
 	}
 	c.mu.Unlock()
 
-	c.logger.Info("embedding cache: warm-up complete",
+	logAttrs := []any{
 		slog.Int("embedded_tools", embeddedCount),
 		slog.Int("requested_tools", len(specs)),
-	)
+	}
+	if embeddedCount < len(specs) {
+		c.logger.Warn("embedding cache: warm-up incomplete, missing tools will be embedded on next start", logAttrs...)
+	} else {
+		c.logger.Info("embedding cache: warm-up complete", logAttrs...)
+	}
 
 	// GR-61: Persist to BadgerDB after releasing the lock.
 	// Persistence failure is non-fatal: vectors are already in RAM.
-	if toSave != nil {
-		if err := c.store.SaveEmbeddings(ctx, corpusHash, toSave); err != nil {
-			c.logger.Warn("embedding cache: failed to persist vectors to BadgerDB",
-				slog.String("error", err.Error()),
-				slog.String("corpus_hash", shortHash(corpusHash)),
+	if len(toSave) > 0 {
+		c.persist(ctx, corpusHash, toolStore, docHashes, toSave)
+	}
+	if embeddedCount == len(specs) {
+		c.activate(ctx, corpusHash, docHashes)
+	}
+
+	return nil
+}
+
+// loadPersisted returns the persisted vectors for the current specs.
+//
+// With a ToolEmbeddingStore, per-tool entries are loaded first. If none
+// exist, a corpus entry written by an older version is used instead and
+// migrate is true, so the caller copies it into per-tool entries. Load
+// failures are logged and treated as a miss.
+func (c *ToolEmbeddingCache) loadPersisted(ctx context.Context, corpusHash string, toolStore ToolEmbeddingStore, docHashes map[string]string) (vectors map[string][]float32, migrate bool) {
+	if c.store == nil {
+		return nil, false
+	}
+	if toolStore != nil {
+		cached, err := toolStore.LoadToolEmbeddings(ctx, docHashes)
+		if err != nil {
+			c.logger.Warn("embedding cache: tool store load failed, continuing with Ollama warm-up",
+				telemetry.SensitiveLogError("error", err),
 			)
-		} else {
-			c.logger.Debug("embedding cache: persisted vectors to BadgerDB",
-				slog.Int("tool_count", len(toSave)),
-				slog.String("corpus_hash", shortHash(corpusHash)),
+			return nil, false
+		}
+		if len(cached) > 0 {
+			return cached, false
+		}
+	}
+	cached, err := c.store.LoadEmbeddings(ctx, corpusHash)
+	if err != nil {
+		c.logger.Warn("embedding cache: store load failed, continuing with Ollama warm-up",
+			telemetry.SensitiveLogError("error", err),
+		)
+		return nil, false
+	}
+	return cached, toolStore != nil && len(cached) > 0
+}
+
+// persist saves vectors to the per-tool store if there is one, otherwise
+// as the corpus entry. Failures are logged; vectors stay in RAM.
+func (c *ToolEmbeddingCache) persist(ctx context.Context, corpusHash string, toolStore ToolEmbeddingStore, docHashes map[string]string, vectors map[string][]float32) {
+	var err error
+	if toolStore != nil {
+		err = toolStore.SaveToolEmbeddings(ctx, docHashes, vectors)
+	} else {
+		err = c.store.SaveEmbeddings(ctx, corpusHash, vectors)
+	}
+	if err != nil {
+		c.logger.Warn("embedding cache: failed to persist vectors to BadgerDB",
+			telemetry.SensitiveLogError("error", err),
+			slog.String("corpus_hash", shortHash(corpusHash)),
+		)
+		return
+	}
+	c.logger.Debug("embedding cache: persisted vectors to BadgerDB",
+		slog.Int("tool_count", len(vectors)),
+		slog.String("corpus_hash", shortHash(corpusHash)),
+	)
+}
+
+// embedDocs embeds the documents of the named tools for warm-up and returns
+// raw vectors aligned with docs; entries for documents that failed are nil.
+//
+// Documents are grouped into batches of batchSize. At most concurrency
+// requests are in flight at a time. When a batch is rejected with
+// errBatchUnsupported, batching is disabled for the rest of the cache's
+// lifetime and the batch's documents are embedded one per request.
+func (c *ToolEmbeddingCache) embedDocs(ctx context.Context, names, docs []string) [][]float32 {
+	out := make([][]float32, len(docs))
+	batchSize := max(c.batchSize, 1)
+	sem := make(chan struct{}, max(c.concurrency, 1))
+
+	acquire := func() bool {
+		select {
+		case sem <- struct{}{}:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	var wg sync.WaitGroup
+	embedOne := func(i int) {
+		defer wg.Done()
+		if !acquire() {
+			return
+		}
+		defer func() { <-sem }()
+		vecs, err := c.embedWithRetry(ctx, docs[i:i+1])
+		if err != nil {
+			c.logger.Warn("embedding cache: failed to embed tool",
+				slog.String("tool", names[i]),
+				telemetry.SensitiveLogError("error", err),
 			)
+			return
 		}
+		out[i] = vecs[0]
 	}
 
-	return nil
+	for start := 0; start < len(docs); start += batchSize {
+		end := min(start+batchSize, len(docs))
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			if end-start > 1 && !c.batchUnsupported.Load() {
+				if !acquire() {
+					return
+				}
+				vecs, err := c.embedWithRetry(ctx, docs[start:end])
+				<-sem
+				if err == nil {
+					copy(out[start:end], vecs)
+					return
+				}
+				if !errors.Is(err, errBatchUnsupported) {
+					c.logger.Warn("embedding cache: failed to embed tool batch",
+						slog.String("first_tool", names[start]),
+						slog.Int("batch_size", end-start),
+						telemetry.SensitiveLogError("error", err),
+					)
+					return
+				}
+				if c.batchUnsupported.CompareAndSwap(false, true) {
+					c.logger.Info("embedding cache: endpoint rejected batched input, embedding one tool per request",
+						telemetry.SensitiveLogError("error", err),
+					)
+				}
+			}
+			for i := start; i < end; i++ {
+				wg.Add(1)
+				go embedOne(i)
+			}
+		}(start, end)
+	}
+	wg.Wait()
+	return out
 }
 
 // Score embeds the query and returns cosine similarity vs each cached tool vector.
 //
 // # Description
 //
-// Returns (nil, nil) in two cases where the caller should fall back to BM25:
+// Returns (nil, nil) in three cases where the caller should fall back to BM25:
 //  1. The cache was never warmed (Ollama was unavailable at startup).
 //  2. The Ollama call for the query embedding fails or times out.
+//  3. The tool corpus changed and is being re-embedded (see Refresh).
 //
 // Returns a non-nil map on success. Scores are in [0.0, 1.0]; tools absent
 // from the cache have implicit score 0 and are omitted from the map.
@@ -316,34 +578,44 @@ func (c *ToolEmbeddingCache) Warm(ctx context.Context, specs []ToolSpec) error {
 //
 // Safe for concurrent use after Warm() completes.
 func (c *ToolEmbeddingCache) Score(ctx context.Context, query string) (map[string]float64, error) {
+	scores, _ := c.scoreWithVector(ctx, query)
+	return scores, nil
+}
+
+// scoreWithVector is Score plus the unit-normalized query vector.
+//
+// The vector is returned so routing feedback can be recorded against the
+// same embedding that produced the scores. Both results are nil on
+// graceful degradation.
+func (c *ToolEmbeddingCache) scoreWithVector(ctx context.Context, query string) (map[string]float64, []float32) {
 	c.mu.RLock()
-	warmed := c.warmed
+	warmed, dim := c.warmed, c.dim
+	outdated := c.corpusHash != c.targetHash
 	c.mu.RUnlock()
 
-	if !warmed {
+	if !warmed || outdated {
 		return nil, nil
 	}
 
-	// Apply a tight timeout for the query embedding call.
-	embedCtx, cancel := context.WithTimeout(ctx, toolEmbeddingQueryTimeout)
-	defer cancel()
-
-	queryVec, err := c.embed(embedCtx, query)
+	queryUnit, err := c.EmbedQuery(ctx, query)
 	if err != nil {
 		c.logger.Warn("embedding cache: query embedding failed, falling back to BM25",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, nil
 	}
-
-	// Unit-normalize query vector.
-	queryNorm := l2Norm(queryVec)
-	if queryNorm == 0 {
+	if queryUnit == nil {
 		return nil, nil
 	}
-	queryUnit := make([]float32, len(queryVec))
-	for i, v := range queryVec {
-		queryUnit[i] = v / float32(queryNorm)
+	if len(queryUnit) != dim {
+		if c.dimMismatchLog.CompareAndSwap(false, true) {
+			c.logger.Warn("embedding cache: query dimension does not match tool vectors, falling back to BM25",
+				slog.Int("query_dim", len(queryUnit)),
+				slog.Int("tool_dim", dim),
+				slog.String("model", c.model),
+			)
+		}
+		return nil, nil
 	}
 
 	c.mu.RLock()
@@ -357,7 +629,69 @@ func (c *ToolEmbeddingCache) Score(ctx context.Context, query string) (map[strin
 		}
 	}
 
-	return scores, nil
+	return scores, queryUnit
+}
+
+// EmbedQuery embeds a query and returns its unit-normalized vector.
+//
+// # Description
+//
+// Applies the per-query timeout. Returns (nil, nil) when the embedding has
+// zero norm, which callers treat the same as an unavailable embedding.
+// While the hash fallback is active the query is embedded locally.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - query: The query text.
+//
+// # Outputs
+//
+//   - []float32: Unit-normalized query vector, or nil.
+//   - error: Non-nil if the embedding call fails.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (c *ToolEmbeddingCache) EmbedQuery(ctx context.Context, query string) ([]float32, error) {
+	if c.UsingHashFallback() {
+		return unitNormalize(hashEmbed(query)), nil
+	}
+
+	// Apply a tight timeout for the query embedding call.
+	embedCtx, cancel := context.WithTimeout(ctx, toolEmbeddingQueryTimeout)
+	defer cancel()
+
+	queryVec, err := c.embed(embedCtx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return unitNormalize(queryVec), nil
+}
+
+// Model returns the embedding model name, or HashEmbeddingModel while the
+// hash fallback is active.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (c *ToolEmbeddingCache) Model() string {
+	if c.UsingHashFallback() {
+		return HashEmbeddingModel
+	}
+	return c.model
+}
+
+// UsingHashFallback reports whether the cache is scoring with hash embeddings.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (c *ToolEmbeddingCache) UsingHashFallback() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.hashed
 }
 
 // IsWarmed reports whether the cache has been successfully warmed.
@@ -390,12 +724,72 @@ func buildEmbeddingDoc(spec ToolSpec) string {
 	return strings.Join(parts, ". ")
 }
 
-// embed calls the Ollama /api/embed endpoint and returns the embedding vector.
+// embed calls the Ollama /api/embed endpoint once and returns the embedding
+// vector. Used on the query path, where retries would exceed the budget.
 func (c *ToolEmbeddingCache) embed(ctx context.Context, text string) ([]float32, error) {
-	reqBody, err := json.Marshal(ollamaEmbedReq{
-		Model: c.model,
-		Input: text,
-	})
+	vecs, err := c.embedInputs(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+// embedWithRetry is embedInputs retried on 429, 5xx and timeouts,
+// up to toolEmbeddingMaxAttempts tries with jittered exponential backoff.
+func (c *ToolEmbeddingCache) embedWithRetry(ctx context.Context, texts []string) ([][]float32, error) {
+	var lastErr error
+	for attempt := 0; attempt < toolEmbeddingMaxAttempts; attempt++ {
+		if attempt > 0 {
+			backoff := c.retryBase << (attempt - 1)
+			wait := backoff/2 + time.Duration(rand.Int64N(int64(backoff/2)+1))
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		vecs, err := c.embedInputs(ctx, texts)
+		if err == nil {
+			return vecs, nil
+		}
+		if !isRetryableEmbedError(ctx, err) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("after %d attempts: %w", toolEmbeddingMaxAttempts, lastErr)
+}
+
+// isRetryableEmbedError reports whether an embed failure is worth retrying:
+// rate limiting, server errors, and timeouts while ctx is live. A refused
+// connection means Ollama is not running and fails fast.
+func isRetryableEmbedError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, errBatchUnsupported) {
+		return false
+	}
+	var statusErr *embedStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.status == http.StatusTooManyRequests || statusErr.status >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && urlErr.Timeout()
+}
+
+// embedInputs calls the Ollama /api/embed endpoint once and returns one
+// embedding vector per text, in order.
+//
+// A single text is sent as a string input, several as an array. A 400
+// response or a wrong embedding count for an array request is reported as
+// errBatchUnsupported.
+func (c *ToolEmbeddingCache) embedInputs(ctx context.Context, texts []string) ([][]float32, error) {
+	batched := len(texts) > 1
+	var reqBody []byte
+	var err error
+	if batched {
+		reqBody, err = json.Marshal(ollamaEmbedBatchReq{Model: c.model, Input: texts})
+	} else {
+		reqBody, err = json.Marshal(ollamaEmbedReq{Model: c.model, Input: texts[0]})
+	}
 	if err != nil {
 		return nil, fmt.Errorf("marshal embed request: %w", err)
 	}
@@ -418,18 +812,39 @@ func (c *ToolEmbeddingCache) embed(ctx context.Context, text string) ([]float32,
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("embed service returned %d: %s", resp.StatusCode, string(body))
+		statusErr := &embedStatusError{status: resp.StatusCode, body: string(body)}
+		if batched && resp.StatusCode == http.StatusBadRequest {
+			return nil, fmt.Errorf("%w: %v", errBatchUnsupported, statusErr)
+		}
+		return nil, statusErr
 	}
 
 	var ollamaResp ollamaEmbedResp
 	if err := json.Unmarshal(body, &ollamaResp); err != nil {
 		return nil, fmt.Errorf("parse embed response: %w", err)
 	}
-	if len(ollamaResp.Embeddings) == 0 || len(ollamaResp.Embeddings[0]) == 0 {
+	if batched && len(ollamaResp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("%w: got %d embeddings for %d inputs", errBatchUnsupported, len(ollamaResp.Embeddings), len(texts))
+	}
+	if len(ollamaResp.Embeddings) == 0 {
 		return nil, fmt.Errorf("embed service returned empty vector")
 	}
+	for _, vec := range ollamaResp.Embeddings[:len(texts)] {
+		if len(vec) == 0 {
+			return nil, fmt.Errorf("embed service returned empty vector")
+		}
+	}
 
-	return ollamaResp.Embeddings[0], nil
+	return ollamaResp.Embeddings[:len(texts)], nil
+}
+
+// envPositiveInt returns the positive integer value of the environment
+// variable name, or def if it is unset or invalid.
+func envPositiveInt(name string, def int) int {
+	if n, err := strconv.Atoi(os.Getenv(name)); err == nil && n > 0 {
+		return n
+	}
+	return def
 }
 
 // l2Norm computes the L2 (Euclidean) norm of a float32 vector.
@@ -441,6 +856,19 @@ func l2Norm(v []float32) float64 {
 	return math.Sqrt(sum)
 }
 
+// unitNormalize returns v scaled to unit length, or nil if v has zero norm.
+func unitNormalize(v []float32) []float32 {
+	norm := l2Norm(v)
+	if norm == 0 {
+		return nil
+	}
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = x / float32(norm)
+	}
+	return out
+}
+
 // dotProduct computes the dot product of two float32 vectors.
 // Both vectors must have the same length; mismatched lengths use the shorter.
 func dotProduct(a, b []float32) float32 {
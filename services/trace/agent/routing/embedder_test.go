@@ -463,3 +463,338 @@ func containsSubstr(s, sub string) bool {
 		return false
 	}()
 }
+
+// =============================================================================
+// Hash Fallback and Dimension Validation Tests
+// =============================================================================
+
+func TestToolEmbeddingCache_Warm_HashFallback(t *testing.T) {
+	server := mockOllamaServer(t, 8, 0)
+	server.Close() // unreachable endpoint
+
+	cache := newTestEmbeddingCache(t, server.URL)
+	cache.client = &http.Client{Timeout: 100 * time.Millisecond}
+	cache.EnableHashFallback()
+
+	if err := cache.Warm(context.Background(), makeReferencesVsSymbolSpecs()); err != nil {
+		t.Fatalf("Warm: %v", err)
+	}
+	if !cache.IsWarmed() || !cache.UsingHashFallback() {
+		t.Fatal("expected cache warmed with hash fallback")
+	}
+	if cache.Model() != HashEmbeddingModel {
+		t.Errorf("Model() = %q, want %q", cache.Model(), HashEmbeddingModel)
+	}
+
+	scores, err := cache.Score(context.Background(), "who calls this function, show the call sites")
+	if err != nil || scores == nil {
+		t.Fatalf("Score = %v, %v; want non-nil scores", scores, err)
+	}
+	if scores["find_callers"] <= scores["find_references"] {
+		t.Errorf("expected find_callers to outscore find_references: %v", scores)
+	}
+}
+
+func TestToolEmbeddingCache_Warm_NoHashFallbackWhenOllamaUp(t *testing.T) {
+	server := mockOllamaServer(t, 8, 0)
+	defer server.Close()
+
+	cache := newTestEmbeddingCache(t, server.URL)
+	cache.EnableHashFallback()
+	if err := cache.Warm(context.Background(), makeReferencesVsSymbolSpecs()); err != nil {
+		t.Fatalf("Warm: %v", err)
+	}
+	if cache.UsingHashFallback() {
+		t.Error("hash fallback must not be used when Ollama is reachable")
+	}
+	if cache.Model() != "test-model" {
+		t.Errorf("Model() = %q, want test-model", cache.Model())
+	}
+}
+
+func TestToolEmbeddingCache_Score_DimensionMismatch(t *testing.T) {
+	server := mockOllamaServer(t, 8, 0)
+	defer server.Close()
+
+	cache := newTestEmbeddingCache(t, server.URL)
+	// Simulate vectors cached by a model with a different dimension.
+	cache.vectors = map[string][]float32{"find_callers": {1, 0, 0, 0}}
+	cache.dim = 4
+	cache.warmed = true
+
+	scores, _ := cache.Score(context.Background(), "who calls main")
+	if scores != nil {
+		t.Errorf("expected nil scores on dimension mismatch, got %v", scores)
+	}
+}
+
+func TestHashEmbed_Deterministic(t *testing.T) {
+	a := hashEmbed("Find all callers of ParseConfig")
+	b := hashEmbed("find ALL callers of parseconfig")
+	if len(a) != hashEmbeddingDim {
+		t.Fatalf("len = %d, want %d", len(a), hashEmbeddingDim)
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("hashEmbed is not case-insensitive/deterministic at %d", i)
+		}
+	}
+	if unitNormalize(hashEmbed("  ?! ")) != nil {
+		t.Error("expected nil normalized vector for text without words")
+	}
+}
+
+// =============================================================================
+// Batching, Retry and Resume Tests
+// =============================================================================
+
+// batchOllamaServer is a mock /api/embed that accepts string and array inputs.
+type batchOllamaServer struct {
+	*httptest.Server
+	requests    atomic.Int64 // requests received
+	inputs      atomic.Int64 // documents received
+	rejectBatch bool         // answer array inputs with 400
+	throttle    atomic.Int64 // answer this many requests with 429 first
+	failTool    string       // answer requests containing this text with 500
+}
+
+// newBatchOllamaServer starts a batchOllamaServer returning dim-sized vectors.
+func newBatchOllamaServer(t *testing.T, dim int, configure func(*batchOllamaServer)) *batchOllamaServer {
+	t.Helper()
+	m := &batchOllamaServer{}
+	if configure != nil {
+		configure(m)
+	}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.requests.Add(1)
+		if m.throttle.Add(-1) >= 0 {
+			http.Error(w, "busy", http.StatusTooManyRequests)
+			return
+		}
+
+		var req struct {
+			Input json.RawMessage `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var texts []string
+		if len(req.Input) > 0 && req.Input[0] == '[' {
+			if m.rejectBatch {
+				http.Error(w, "input must be a string", http.StatusBadRequest)
+				return
+			}
+			if err := json.Unmarshal(req.Input, &texts); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		} else {
+			var text string
+			if err := json.Unmarshal(req.Input, &text); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			texts = []string{text}
+		}
+
+		resp := ollamaEmbedResp{}
+		for _, text := range texts {
+			if m.failTool != "" && containsSubstr(text, m.failTool) {
+				http.Error(w, "model crashed", http.StatusInternalServerError)
+				return
+			}
+			vec := make([]float32, dim)
+			for i := range vec {
+				vec[i] = float32((len(text)+i)%7 + 1)
+			}
+			resp.Embeddings = append(resp.Embeddings, vec)
+		}
+		m.inputs.Add(int64(len(texts)))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(m.Close)
+	return m
+}
+
+// makeManySpecs returns n distinct tool specs.
+func makeManySpecs(n int) []ToolSpec {
+	specs := make([]ToolSpec, n)
+	for i := range specs {
+		specs[i] = ToolSpec{
+			Name:    fmt.Sprintf("tool_%02d", i),
+			BestFor: []string{fmt.Sprintf("keyword %d", i)},
+			UseWhen: fmt.Sprintf("Use for task %d", i),
+		}
+	}
+	return specs
+}
+
+// newBatchTestCache creates a cache for server with fast retries.
+func newBatchTestCache(t *testing.T, server *batchOllamaServer, store RouterCacheStore) *ToolEmbeddingCache {
+	t.Helper()
+	cache := NewToolEmbeddingCache(slog.Default(), store)
+	cache.url = server.URL + "/api/embed"
+	cache.model = "test-model"
+	cache.batchSize = 8
+	cache.concurrency = 3
+	cache.retryBase = time.Millisecond
+	return cache
+}
+
+func TestToolEmbeddingCache_Warm_Batched(t *testing.T) {
+	server := newBatchOllamaServer(t, 8, nil)
+	cache := newBatchTestCache(t, server, nil)
+
+	if err := cache.Warm(context.Background(), makeManySpecs(20)); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if n := len(cache.vectors); n != 20 {
+		t.Errorf("embedded %d tools, want 20", n)
+	}
+	// 20 tools in batches of 8 → 3 requests.
+	if got := server.requests.Load(); got != 3 {
+		t.Errorf("server received %d requests, want 3", got)
+	}
+}
+
+func TestToolEmbeddingCache_Warm_BatchUnsupportedFallsBack(t *testing.T) {
+	server := newBatchOllamaServer(t, 8, func(m *batchOllamaServer) { m.rejectBatch = true })
+	cache := newBatchTestCache(t, server, nil)
+
+	if err := cache.Warm(context.Background(), makeManySpecs(20)); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if n := len(cache.vectors); n != 20 {
+		t.Errorf("embedded %d tools, want 20", n)
+	}
+	if !cache.batchUnsupported.Load() {
+		t.Error("expected batching to be disabled after a rejected batch")
+	}
+	if got := server.inputs.Load(); got != 20 {
+		t.Errorf("server embedded %d documents, want 20 single requests", got)
+	}
+}
+
+func TestToolEmbeddingCache_Warm_RetriesThrottled(t *testing.T) {
+	server := newBatchOllamaServer(t, 8, func(m *batchOllamaServer) { m.throttle.Store(2) })
+	cache := newBatchTestCache(t, server, nil)
+	cache.batchSize = 16
+
+	if err := cache.Warm(context.Background(), makeManySpecs(5)); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if n := len(cache.vectors); n != 5 {
+		t.Errorf("embedded %d tools, want 5 after retries", n)
+	}
+	if got := server.requests.Load(); got != 3 {
+		t.Errorf("server received %d requests, want 3 (2 throttled + 1 success)", got)
+	}
+}
+
+func TestToolEmbeddingCache_Warm_ResumesPartialWarmUp(t *testing.T) {
+	ctx := context.Background()
+	store := NewBadgerRouterCacheStore(openTestDB(t), 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
+	specs := makeManySpecs(10)
+
+	// First start: one tool keeps failing, the others are persisted.
+	failing := newBatchOllamaServer(t, 8, func(m *batchOllamaServer) { m.failTool = "task 7" })
+	first := newBatchTestCache(t, failing, store)
+	first.batchSize = 1
+	if err := first.Warm(ctx, specs); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if n := len(first.vectors); n != 9 {
+		t.Fatalf("first warm-up embedded %d tools, want 9", n)
+	}
+
+	// Second start: only the missing tool is embedded.
+	healthy := newBatchOllamaServer(t, 8, nil)
+	second := newBatchTestCache(t, healthy, store)
+	if err := second.Warm(ctx, specs); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if n := len(second.vectors); n != 10 {
+		t.Errorf("second warm-up has %d tools, want 10", n)
+	}
+	if got := healthy.inputs.Load(); got != 1 {
+		t.Errorf("second warm-up embedded %d documents, want 1", got)
+	}
+
+	// Third start: everything is cached.
+	third := newBatchTestCache(t, healthy, store)
+	if err := third.Warm(ctx, specs); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if got := healthy.inputs.Load(); got != 1 {
+		t.Errorf("third warm-up called Ollama (%d documents total), want cache hit", got)
+	}
+	if !third.IsWarmed() {
+		t.Error("expected third cache to be warmed from BadgerDB")
+	}
+}
+
+func TestToolEmbeddingCache_Warm_ReadsLegacyCorpusEntry(t *testing.T) {
+	ctx := context.Background()
+	store := NewBadgerRouterCacheStore(openTestDB(t), 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
+	specs := makeManySpecs(3)
+
+	legacy := map[string][]float32{
+		"tool_00": {1, 0, 0, 0},
+		"tool_01": {0, 1, 0, 0},
+		"tool_02": {0, 0, 1, 0},
+	}
+	if err := store.SaveEmbeddings(ctx, computeCorpusHash(specs, "test-model"), legacy); err != nil {
+		t.Fatalf("SaveEmbeddings: %v", err)
+	}
+
+	server := newBatchOllamaServer(t, 4, nil)
+	cache := newBatchTestCache(t, server, store)
+	if err := cache.Warm(ctx, specs); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if got := server.requests.Load(); got != 0 {
+		t.Errorf("server received %d requests, want 0 (legacy entry hit)", got)
+	}
+	if !cache.IsWarmed() || len(cache.vectors) != 3 {
+		t.Errorf("expected 3 tools loaded from the legacy entry, got %d", len(cache.vectors))
+	}
+
+	// The legacy entry was copied into per-tool entries.
+	docHashes := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		docHashes[spec.Name] = computeToolDocHash(spec)
+	}
+	if got, err := store.LoadToolEmbeddings(ctx, docHashes); err != nil || len(got) != 3 {
+		t.Errorf("LoadToolEmbeddings after migration = %d tools, %v; want 3", len(got), err)
+	}
+}
+
+func TestIsRetryableEmbedError(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"throttled", &embedStatusError{status: http.StatusTooManyRequests}, true},
+		{"server error", fmt.Errorf("wrapped: %w", &embedStatusError{status: http.StatusBadGateway}), true},
+		{"bad request", &embedStatusError{status: http.StatusBadRequest}, false},
+		{"batch unsupported", errBatchUnsupported, false},
+		{"other", fmt.Errorf("parse embed response: bad json"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryableEmbedError(ctx, tt.err); got != tt.want {
+				t.Errorf("isRetryableEmbedError(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if isRetryableEmbedError(cancelled, &embedStatusError{status: http.StatusServiceUnavailable}) {
+		t.Error("expected no retry after context cancellation")
+	}
+}
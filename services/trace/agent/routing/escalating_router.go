@@ -22,6 +22,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -168,7 +170,7 @@ func NewEscalatingRouter(primary ToolRouter, escalation ToolRouter, threshold fl
 func (r *EscalatingRouter) SelectTool(ctx context.Context, query string, availableTools []ToolSpec, codeCtx *CodeContext) (*ToolSelection, error) {
 	ctx, span := escalatingRouterTracer.Start(ctx, "routing.EscalatingRouter.SelectTool",
 		trace.WithAttributes(
-			attribute.String("query_preview", truncateForLog(query, 80)),
+			telemetry.SensitiveString("query_preview", truncateForLog(query, 80)),
 			attribute.Int("available_tools", len(availableTools)),
 			attribute.Bool("escalation_configured", r.escalation != nil),
 		),
@@ -339,7 +341,7 @@ func (r *EscalatingRouter) escalate(ctx context.Context, query string,
 	if escErr != nil {
 		// Escalation failed — return primary result (best effort).
 		r.logger.Warn("router escalation failed, using primary result",
-			slog.String("error", escErr.Error()),
+			telemetry.SensitiveLogError("error", escErr),
 			slog.Duration("duration", escDuration),
 		)
 		routerEscalationTotal.WithLabelValues("error").Inc()
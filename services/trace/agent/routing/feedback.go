@@ -0,0 +1,540 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package routing
+
+// =============================================================================
+// Routing Feedback — k-NN Re-ranking From Past Selections
+// =============================================================================
+//
+// The hybrid scorer has no memory: a phrasing that routes to the wrong tool
+// today will route to the wrong tool tomorrow. Routing feedback closes the
+// loop. Each routed query records (query embedding, selected tool, outcome),
+// where the outcome comes from the Reflect phase: the result was accepted,
+// or the agent immediately retried with a different tool.
+//
+// At routing time the nearest past queries (cosine similarity of their
+// embeddings) vote for the tools that worked for them and against the tools
+// that did not. The vote is added to the hybrid score with a configurable
+// weight. With no feedback, or no neighbor above the similarity floor, the
+// vote is empty and scores pass through unchanged, so cold-start routing is
+// identical to routing without feedback.
+//
+// Storage layout (same BadgerDB as the embedding cache):
+//
+//	routing/fb/v1/{unixNano}-{tool}  →  gob-encoded RoutingFeedback
+//	                                    TTL: 30 days
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	dgbadger "github.com/dgraph-io/badger/v4"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+const (
+	// feedbackMinSimilarity is the cosine floor for a past query to vote.
+	// Below this, past queries are about something else and add noise.
+	feedbackMinSimilarity = 0.80
+
+	// feedbackMaxRecords bounds the in-memory index. Oldest records are
+	// dropped first; they remain in BadgerDB until their TTL expires.
+	feedbackMaxRecords = 5000
+
+	// feedbackTTL is the lifetime of a persisted feedback record.
+	feedbackTTL = 30 * 24 * time.Hour
+
+	// feedbackKeyPrefix is the BadgerDB key prefix for feedback records.
+	feedbackKeyPrefix = "routing/fb/v1/"
+)
+
+// RoutingFeedback is one observed routing outcome.
+type RoutingFeedback struct {
+	// Query is the (lowercased) query that was routed.
+	Query string `json:"query"`
+
+	// Tool is the tool that was selected.
+	Tool string `json:"tool"`
+
+	// Success is true if the Reflect phase accepted the tool result.
+	Success bool `json:"success"`
+
+	// Model is the embedding model that produced Vector.
+	Model string `json:"model"`
+
+	// Vector is the unit-normalized query embedding.
+	Vector []float32 `json:"-"`
+
+	// Timestamp is when the outcome was recorded (Unix milliseconds UTC).
+	Timestamp int64 `json:"timestamp"`
+}
+
+// RoutingFeedbackStore persists routing feedback records.
+//
+// # Description
+//
+// Implemented by BadgerRouterCacheStore. Kept separate from
+// RouterCacheStore so existing store implementations need not change; the
+// PreFilter enables feedback only when its store also implements this.
+//
+// # Thread Safety
+//
+// Implementations must be safe for concurrent use.
+type RoutingFeedbackStore interface {
+	// AppendFeedback persists one record.
+	AppendFeedback(ctx context.Context, rec RoutingFeedback) error
+
+	// LoadFeedback returns all unexpired records, oldest first.
+	LoadFeedback(ctx context.Context) ([]RoutingFeedback, error)
+
+	// ResetFeedback deletes all records and returns how many were removed.
+	ResetFeedback(ctx context.Context) (int, error)
+}
+
+// AppendFeedback persists a routing feedback record with a 30-day TTL.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - rec: The record. Vector must be non-empty.
+//
+// # Outputs
+//
+//   - error: Non-nil on encode or storage failure.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (s *BadgerRouterCacheStore) AppendFeedback(ctx context.Context, rec RoutingFeedback) error {
+	if len(rec.Vector) == 0 {
+		return fmt.Errorf("routing feedback: empty vector")
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(rec); err != nil {
+		return fmt.Errorf("routing feedback encode: %w", err)
+	}
+
+	key := []byte(fmt.Sprintf("%s%020d-%s", feedbackKeyPrefix, time.Now().UnixNano(), rec.Tool))
+	err := s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.SetEntry(dgbadger.NewEntry(key, buf.Bytes()).WithTTL(feedbackTTL))
+	})
+	if err != nil {
+		return fmt.Errorf("routing feedback save: %w", err)
+	}
+	return nil
+}
+
+// LoadFeedback returns all unexpired feedback records, oldest first.
+//
+// # Description
+//
+// Records that fail to decode are skipped with a warning rather than
+// failing the load; a single corrupt record must not disable feedback.
+//
+// # Outputs
+//
+//   - []RoutingFeedback: The records. Empty when none exist.
+//   - error: Non-nil on storage failure.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (s *BadgerRouterCacheStore) LoadFeedback(ctx context.Context) ([]RoutingFeedback, error) {
+	var records []RoutingFeedback
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		opts := dgbadger.DefaultIteratorOptions
+		opts.Prefix = []byte(feedbackKeyPrefix)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			raw, err := it.Item().ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("copy value: %w", err)
+			}
+			var rec RoutingFeedback
+			if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&rec); err != nil {
+				s.logger.Warn("routing feedback: skipping undecodable record",
+					slog.String("key", string(it.Item().Key())),
+					telemetry.SensitiveLogError("error", err),
+				)
+				continue
+			}
+			records = append(records, rec)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("routing feedback load: %w", err)
+	}
+	return records, nil
+}
+
+// ResetFeedback deletes every feedback record. Embedding cache entries are kept.
+//
+// # Outputs
+//
+//   - int: Number of records deleted.
+//   - error: Non-nil on storage failure.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (s *BadgerRouterCacheStore) ResetFeedback(ctx context.Context) (int, error) {
+	var keys [][]byte
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		opts := dgbadger.DefaultIteratorOptions
+		opts.Prefix = []byte(feedbackKeyPrefix)
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			keys = append(keys, it.Item().KeyCopy(nil))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("routing feedback reset: %w", err)
+	}
+
+	removed, err := s.deleteKeys(ctx, keys)
+	if err != nil {
+		return removed, fmt.Errorf("routing feedback reset: %w", err)
+	}
+	return removed, nil
+}
+
+// =============================================================================
+// FeedbackIndex
+// =============================================================================
+
+// FeedbackToolStats summarizes feedback for one tool.
+type FeedbackToolStats struct {
+	// Successes is the number of accepted selections.
+	Successes int `json:"successes"`
+
+	// Failures is the number of selections followed by a retry.
+	Failures int `json:"failures"`
+}
+
+// FeedbackSummary is the inspection view of the feedback index.
+type FeedbackSummary struct {
+	// Enabled is false when no feedback store is configured.
+	Enabled bool `json:"enabled"`
+
+	// Weight is the configured vote weight.
+	Weight float64 `json:"weight"`
+
+	// K is the configured neighbor count.
+	K int `json:"k"`
+
+	// Total is the number of records in the index.
+	Total int `json:"total"`
+
+	// ByTool breaks records down per tool.
+	ByTool map[string]FeedbackToolStats `json:"by_tool"`
+
+	// Recent lists the most recent records, newest first (vectors omitted).
+	Recent []RoutingFeedback `json:"recent"`
+}
+
+// FeedbackIndex holds routing feedback in memory for k-NN voting.
+//
+// # Description
+//
+// Loads persisted records from the store on first use, then serves votes
+// from memory. New records are appended to both memory and the store.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+type FeedbackIndex struct {
+	store  RoutingFeedbackStore
+	k      int
+	weight float64
+	logger *slog.Logger
+
+	mu      sync.RWMutex
+	loaded  bool
+	records []RoutingFeedback
+}
+
+// NewFeedbackIndex creates a feedback index.
+//
+// # Inputs
+//
+//   - store: Persistence store. May be nil (in-memory only).
+//   - k: Neighbors that vote. <= 0 uses config.DefaultFeedbackK.
+//   - weight: Vote weight added to hybrid scores. Must be > 0.
+//   - logger: Logger. May be nil.
+//
+// # Outputs
+//
+//   - *FeedbackIndex: The index. Never nil.
+//
+// # Thread Safety
+//
+// The returned index is safe for concurrent use.
+func NewFeedbackIndex(store RoutingFeedbackStore, k int, weight float64, logger *slog.Logger) *FeedbackIndex {
+	if k <= 0 {
+		k = config.DefaultFeedbackK
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &FeedbackIndex{store: store, k: k, weight: weight, logger: logger}
+}
+
+// ensureLoaded loads persisted records once. Load failures are logged and
+// leave the index empty, which routes exactly as without feedback.
+func (fi *FeedbackIndex) ensureLoaded(ctx context.Context) {
+	fi.mu.RLock()
+	loaded := fi.loaded
+	fi.mu.RUnlock()
+	if loaded {
+		return
+	}
+
+	var records []RoutingFeedback
+	if fi.store != nil {
+		var err error
+		records, err = fi.store.LoadFeedback(ctx)
+		if err != nil {
+			fi.logger.Warn("routing feedback: load failed, starting empty",
+				telemetry.SensitiveLogError("error", err),
+			)
+			records = nil
+		}
+	}
+
+	fi.mu.Lock()
+	defer fi.mu.Unlock()
+	if fi.loaded {
+		return
+	}
+	if len(records) > feedbackMaxRecords {
+		records = records[len(records)-feedbackMaxRecords:]
+	}
+	fi.records = records
+	fi.loaded = true
+}
+
+// Record adds a feedback record to the index and the store.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - rec: The record. Records without a vector are ignored.
+//
+// # Outputs
+//
+//   - error: Non-nil if persistence fails. The in-memory index is updated regardless.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (fi *FeedbackIndex) Record(ctx context.Context, rec RoutingFeedback) error {
+	if len(rec.Vector) == 0 || rec.Tool == "" {
+		return nil
+	}
+	if rec.Timestamp == 0 {
+		rec.Timestamp = time.Now().UnixMilli()
+	}
+	fi.ensureLoaded(ctx)
+
+	fi.mu.Lock()
+	fi.records = append(fi.records, rec)
+	if len(fi.records) > feedbackMaxRecords {
+		fi.records = fi.records[len(fi.records)-feedbackMaxRecords:]
+	}
+	fi.mu.Unlock()
+
+	if fi.store != nil {
+		return fi.store.AppendFeedback(ctx, rec)
+	}
+	return nil
+}
+
+// Vote computes the k-NN vote for a query vector.
+//
+// # Description
+//
+// Finds up to k past records from the same embedding model whose
+// similarity to queryUnit is at least feedbackMinSimilarity. Each neighbor
+// contributes +similarity (success) or -similarity (failure) to its tool.
+// Totals are divided by the summed similarity, giving values in [-1, 1].
+//
+// # Inputs
+//
+//   - ctx: Context for the lazy load.
+//   - queryUnit: Unit-normalized query vector.
+//   - model: Embedding model name that produced queryUnit.
+//
+// # Outputs
+//
+//   - map[string]float64: Tool → vote in [-1, 1]. Nil when no neighbor qualifies.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (fi *FeedbackIndex) Vote(ctx context.Context, queryUnit []float32, model string) map[string]float64 {
+	if len(queryUnit) == 0 {
+		return nil
+	}
+	fi.ensureLoaded(ctx)
+
+	type neighbor struct {
+		sim     float64
+		tool    string
+		success bool
+	}
+
+	fi.mu.RLock()
+	var neighbors []neighbor
+	for _, rec := range fi.records {
+		if rec.Model != model || len(rec.Vector) != len(queryUnit) {
+			continue
+		}
+		sim := float64(dotProduct(queryUnit, rec.Vector))
+		if sim >= feedbackMinSimilarity {
+			neighbors = append(neighbors, neighbor{sim: sim, tool: rec.Tool, success: rec.Success})
+		}
+	}
+	fi.mu.RUnlock()
+
+	if len(neighbors) == 0 {
+		return nil
+	}
+	sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].sim > neighbors[j].sim })
+	if len(neighbors) > fi.k {
+		neighbors = neighbors[:fi.k]
+	}
+
+	var total float64
+	votes := make(map[string]float64)
+	for _, n := range neighbors {
+		total += n.sim
+		if n.success {
+			votes[n.tool] += n.sim
+		} else {
+			votes[n.tool] -= n.sim
+		}
+	}
+	for t := range votes {
+		votes[t] /= total
+	}
+	return votes
+}
+
+// Blend adds weight × vote to scores in place, flooring at 0.
+//
+// # Description
+//
+// Tools with a positive vote that are missing from scores are added, so a
+// tool the hybrid scorer missed entirely can still become a candidate.
+// A nil or empty vote leaves scores untouched.
+//
+// # Thread Safety
+//
+// Safe for concurrent use; scores must not be shared.
+func (fi *FeedbackIndex) Blend(scores map[string]float64, vote map[string]float64) {
+	for tool, v := range vote {
+		blended := math.Max(0, scores[tool]+fi.weight*v)
+		if blended > 0 {
+			scores[tool] = blended
+		} else if _, ok := scores[tool]; ok {
+			scores[tool] = 0
+		}
+	}
+}
+
+// Summary returns counts and the most recent records.
+//
+// # Inputs
+//
+//   - ctx: Context for the lazy load.
+//   - recent: Maximum recent records to include.
+//
+// # Outputs
+//
+//   - FeedbackSummary: The summary.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (fi *FeedbackIndex) Summary(ctx context.Context, recent int) FeedbackSummary {
+	fi.ensureLoaded(ctx)
+
+	fi.mu.RLock()
+	defer fi.mu.RUnlock()
+
+	summary := FeedbackSummary{
+		Enabled: true,
+		Weight:  fi.weight,
+		K:       fi.k,
+		Total:   len(fi.records),
+		ByTool:  make(map[string]FeedbackToolStats),
+	}
+	for _, rec := range fi.records {
+		stats := summary.ByTool[rec.Tool]
+		if rec.Success {
+			stats.Successes++
+		} else {
+			stats.Failures++
+		}
+		summary.ByTool[rec.Tool] = stats
+	}
+	for i := len(fi.records) - 1; i >= 0 && len(summary.Recent) < recent; i-- {
+		rec := fi.records[i]
+		rec.Vector = nil
+		summary.Recent = append(summary.Recent, rec)
+	}
+	return summary
+}
+
+// Reset deletes all feedback from memory and the store.
+//
+// # Outputs
+//
+//   - int: Number of records removed (persisted count when a store is configured).
+//   - error: Non-nil if the store reset fails.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (fi *FeedbackIndex) Reset(ctx context.Context) (int, error) {
+	fi.mu.Lock()
+	removed := len(fi.records)
+	fi.records = nil
+	fi.loaded = true
+	fi.mu.Unlock()
+
+	if fi.store != nil {
+		n, err := fi.store.ResetFeedback(ctx)
+		if err != nil {
+			return n, err
+		}
+		removed = n
+	}
+	fi.logger.Info("routing feedback: reset", slog.Int("removed", removed))
+	return removed, nil
+}
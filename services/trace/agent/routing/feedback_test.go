@@ -0,0 +1,144 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package routing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
+)
+
+func TestBadgerRouterCacheStore_Feedback_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := NewBadgerRouterCacheStore(openTestDB(t), 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
+
+	if err := store.AppendFeedback(ctx, RoutingFeedback{Tool: "find_callers", Vector: nil}); err == nil {
+		t.Error("expected error for empty vector")
+	}
+	for _, tool := range []string{"find_callers", "find_references"} {
+		rec := RoutingFeedback{Query: "who calls x", Tool: tool, Success: true, Model: "m", Vector: []float32{1, 0}}
+		if err := store.AppendFeedback(ctx, rec); err != nil {
+			t.Fatalf("AppendFeedback: %v", err)
+		}
+	}
+	// Embedding cache entries must survive a feedback reset.
+	if err := store.SaveEmbeddings(ctx, "hash", makeTestVectors()); err != nil {
+		t.Fatalf("SaveEmbeddings: %v", err)
+	}
+
+	records, err := store.LoadFeedback(ctx)
+	if err != nil {
+		t.Fatalf("LoadFeedback: %v", err)
+	}
+	if len(records) != 2 || records[0].Tool != "find_callers" || len(records[0].Vector) != 2 {
+		t.Fatalf("unexpected records: %+v", records)
+	}
+
+	removed, err := store.ResetFeedback(ctx)
+	if err != nil || removed != 2 {
+		t.Fatalf("ResetFeedback = %d, %v; want 2, nil", removed, err)
+	}
+	if records, _ := store.LoadFeedback(ctx); len(records) != 0 {
+		t.Errorf("expected no records after reset, got %d", len(records))
+	}
+	if vectors, _ := store.LoadEmbeddings(ctx, "hash"); vectors == nil {
+		t.Error("reset must not delete embedding cache entries")
+	}
+}
+
+func TestFeedbackIndex_Vote(t *testing.T) {
+	ctx := context.Background()
+	fi := NewFeedbackIndex(nil, 0, 0.5, nil)
+	query := []float32{1, 0}
+
+	// Cold start: no feedback means no vote and untouched scores.
+	if vote := fi.Vote(ctx, query, "m"); vote != nil {
+		t.Fatalf("expected nil vote with no feedback, got %v", vote)
+	}
+
+	records := []RoutingFeedback{
+		{Tool: "find_references", Success: true, Model: "m", Vector: []float32{1, 0}},
+		{Tool: "find_references", Success: true, Model: "m", Vector: []float32{0.95, 0.312}},
+		{Tool: "find_callers", Success: false, Model: "m", Vector: []float32{0.9, 0.436}},
+		{Tool: "find_symbol", Success: true, Model: "m", Vector: []float32{0, 1}},     // dissimilar
+		{Tool: "find_symbol", Success: true, Model: "other", Vector: []float32{1, 0}}, // other model
+	}
+	for _, rec := range records {
+		if err := fi.Record(ctx, rec); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	vote := fi.Vote(ctx, query, "m")
+	if vote["find_references"] <= 0 {
+		t.Errorf("find_references vote = %v, want positive", vote["find_references"])
+	}
+	if vote["find_callers"] >= 0 {
+		t.Errorf("find_callers vote = %v, want negative", vote["find_callers"])
+	}
+	if _, ok := vote["find_symbol"]; ok {
+		t.Error("dissimilar and other-model records must not vote")
+	}
+
+	scores := map[string]float64{"find_callers": 0.6, "find_references": 0.55, "answer": 0.4}
+	fi.Blend(scores, vote)
+	if scores["find_references"] <= scores["find_callers"] {
+		t.Errorf("expected feedback to re-rank find_references above find_callers: %v", scores)
+	}
+	if scores["answer"] != 0.4 {
+		t.Errorf("tools without votes must be unchanged, got %v", scores["answer"])
+	}
+
+	summary := fi.Summary(ctx, 2)
+	if summary.Total != 5 || summary.ByTool["find_references"].Successes != 2 || len(summary.Recent) != 2 {
+		t.Errorf("unexpected summary: %+v", summary)
+	}
+	if summary.Recent[0].Vector != nil {
+		t.Error("summary must omit vectors")
+	}
+
+	if removed, err := fi.Reset(ctx); err != nil || removed != 5 {
+		t.Errorf("Reset = %d, %v; want 5, nil", removed, err)
+	}
+	if vote := fi.Vote(ctx, query, "m"); vote != nil {
+		t.Errorf("expected nil vote after reset, got %v", vote)
+	}
+}
+
+func TestFeedbackIndex_LoadsFromStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewBadgerRouterCacheStore(openTestDB(t), 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
+	rec := RoutingFeedback{Query: "q", Tool: "find_callers", Success: true, Model: "m", Vector: []float32{1, 0}}
+	if err := store.AppendFeedback(ctx, rec); err != nil {
+		t.Fatalf("AppendFeedback: %v", err)
+	}
+
+	fi := NewFeedbackIndex(store, 3, 0.2, nil)
+	if vote := fi.Vote(ctx, []float32{1, 0}, "m"); vote["find_callers"] != 1 {
+		t.Errorf("expected persisted record to vote, got %v", vote)
+	}
+}
+
+func TestNewPreFilter_FeedbackDisabledByDefault(t *testing.T) {
+	pf := NewPreFilter(nil, &config.PreFilterConfig{Enabled: true}, nil, nil)
+	if pf.FeedbackIndex() != nil {
+		t.Error("feedback must be disabled when FeedbackWeight is 0")
+	}
+	if err := pf.RecordFeedback(context.Background(), "q", "find_callers", true); err != nil {
+		t.Errorf("RecordFeedback with feedback disabled: %v", err)
+	}
+
+	pf = NewPreFilter(nil, &config.PreFilterConfig{Enabled: true, FeedbackWeight: 0.2}, nil, nil)
+	if pf.FeedbackIndex() == nil {
+		t.Error("expected feedback index when FeedbackWeight > 0")
+	}
+}
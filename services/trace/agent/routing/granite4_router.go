@@ -20,6 +20,7 @@ import (
 
 	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -92,7 +93,7 @@ func NewGranite4Router(chatClient providers.ChatClient, config RouterConfig) (*G
 	promptBuilder, err := NewPromptBuilder()
 	if err != nil {
 		slog.Error("NewGranite4Router: Failed to create prompt builder",
-			slog.String("error", err.Error()))
+			telemetry.SensitiveLogError("error", err))
 		return nil, fmt.Errorf("creating prompt builder: %w", err)
 	}
 
@@ -166,7 +167,7 @@ func (r *Granite4Router) SelectTool(ctx context.Context, query string, available
 	span.SetAttributes(
 		attribute.String("router.model", r.config.Model),
 		attribute.Int("router.num_tools", len(availableTools)),
-		attribute.String("query_preview", truncate(query, 100)),
+		telemetry.SensitiveString("query_preview", truncate(query, 100)),
 	)
 
 	startTime := time.Now()
@@ -194,7 +195,7 @@ func (r *Granite4Router) SelectTool(ctx context.Context, query string, available
 	r.logger.Debug("Routing query",
 		slog.String("model", r.config.Model),
 		slog.Int("num_tools", len(availableTools)),
-		slog.String("query_preview", truncate(query, 50)),
+		telemetry.SensitiveLog("query_preview", truncate(query, 50)),
 	)
 
 	// Call the model
@@ -223,7 +224,7 @@ func (r *Granite4Router) SelectTool(ctx context.Context, query string, available
 	if err != nil {
 		slog.Error("CB-31d Granite4Router.Chat FAILED",
 			slog.String("model", r.config.Model),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.Duration("elapsed", time.Since(startTime)),
 		)
 		duration := time.Since(startTime)
@@ -243,7 +244,7 @@ func (r *Granite4Router) SelectTool(ctx context.Context, query string, available
 	slog.Info("CB-31d Granite4Router.Chat SUCCEEDED",
 		slog.String("model", r.config.Model),
 		slog.Int("response_len", len(response)),
-		slog.String("response_preview", truncate(response, 200)),
+		telemetry.SensitiveLog("response_preview", truncate(response, 200)),
 		slog.Duration("elapsed", time.Since(startTime)),
 	)
 
@@ -289,7 +290,7 @@ func (r *Granite4Router) SelectTool(ctx context.Context, query string, available
 		slog.String("tool", selection.Tool),
 		slog.Float64("confidence", selection.Confidence),
 		slog.Duration("duration", selection.Duration),
-		slog.String("reasoning", selection.Reasoning),
+		telemetry.SensitiveLog("reasoning", selection.Reasoning),
 	)
 
 	return selection, nil
@@ -580,7 +581,7 @@ func (r *Granite4Router) WarmRouter(ctx context.Context, lifecycle providers.Mod
 		r.logger.Error("WarmRouter: Model warmup failed",
 			slog.String("model", r.config.Model),
 			slog.Duration("duration", duration),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.String("error_type", fmt.Sprintf("%T", err)))
 	} else {
 		r.logger.Info("WarmRouter: Model warmup succeeded",
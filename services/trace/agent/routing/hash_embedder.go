@@ -0,0 +1,91 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package routing
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+// =============================================================================
+// Hash Fallback Embedding
+// =============================================================================
+//
+// When Ollama's embedding endpoint is unreachable at warm-up, the embedding
+// cache can fall back to a locally computed feature-hashing embedding instead
+// of disabling the semantic half of the hybrid scorer. The vectors capture
+// lexical overlap only (words and character trigrams), so they are weaker
+// than a real model, but they are deterministic, need no network and make
+// routing degrade predictably.
+
+const (
+	// HashEmbeddingModel is the model name reported while the fallback is active.
+	// Feedback recorded during fallback is keyed by it and never mixed with
+	// vectors from a real model.
+	HashEmbeddingModel = "hash-fallback-v1"
+
+	// hashEmbeddingDim is the dimension of fallback vectors.
+	hashEmbeddingDim = 512
+
+	// hashEmbeddingTrigramWeight down-weights trigram features relative to
+	// whole words.
+	hashEmbeddingTrigramWeight = 0.5
+)
+
+// hashEmbed computes a feature-hashing embedding of text.
+//
+// # Description
+//
+// Lowercases the text and splits it into alphanumeric words (so snake_case
+// tool names contribute their parts). Each word and each padded character
+// trigram of the word is hashed with FNV-1a into one of hashEmbeddingDim
+// buckets; the top hash bit picks the sign to reduce collision bias.
+// The result is not normalized.
+//
+// # Inputs
+//
+//   - text: Text to embed.
+//
+// # Outputs
+//
+//   - []float32: Vector of length hashEmbeddingDim. All zeros for text
+//     without alphanumeric characters.
+//
+// # Thread Safety
+//
+// Stateless. Safe for concurrent use.
+func hashEmbed(text string) []float32 {
+	vec := make([]float32, hashEmbeddingDim)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		addHashedFeature(vec, "w:"+word, 1)
+		padded := []rune("#" + word + "#")
+		for i := 0; i+3 <= len(padded); i++ {
+			addHashedFeature(vec, "t:"+string(padded[i:i+3]), hashEmbeddingTrigramWeight)
+		}
+	}
+	return vec
+}
+
+// addHashedFeature adds a signed, weighted feature to its hash bucket.
+func addHashedFeature(vec []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(feature))
+	sum := h.Sum64()
+	idx := sum % uint64(len(vec))
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	vec[idx] += weight
+}
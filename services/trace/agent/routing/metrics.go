@@ -94,6 +94,27 @@ var (
 		Name:      "init_total",
 		Help:      "Router initialization attempts by status",
 	}, []string{"model", "status", "reason"})
+
+	// embeddingCacheInvalidations counts tool corpora whose cached
+	// embeddings were replaced.
+	// Labels: reason (corpus_changed, forced)
+	embeddingCacheInvalidations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trace",
+		Subsystem: "routing",
+		Name:      "embedding_cache_invalidations_total",
+		Help:      "Tool embedding cache invalidations by reason",
+	}, []string{"reason"})
+
+	// embeddingReembedDuration measures background re-embeddings of the
+	// tool corpus.
+	// Labels: result (complete, partial)
+	embeddingReembedDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "trace",
+		Subsystem: "routing",
+		Name:      "embedding_reembed_duration_seconds",
+		Help:      "Duration of background tool corpus re-embeddings",
+		Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"result"})
 )
 
 // =============================================================================
@@ -21,6 +21,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/rag"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 )
@@ -204,7 +205,7 @@ func (e *ParamExtractor) ExtractParams(
 	span.SetAttributes(
 		attribute.String("extractor.model", e.config.Model),
 		attribute.String("extractor.tool", toolName),
-		attribute.String("query_preview", truncate(query, 100)),
+		telemetry.SensitiveString("query_preview", truncate(query, 100)),
 	)
 
 	startTime := time.Now()
@@ -555,7 +556,7 @@ func (e *ParamExtractor) ResolveConceptualSymbol(
 	span.SetAttributes(
 		attribute.String("extractor.model", e.config.Model),
 		attribute.Int("candidates.count", len(candidates)),
-		attribute.String("query_preview", truncate(query, 100)),
+		telemetry.SensitiveString("query_preview", truncate(query, 100)),
 	)
 
 	startTime := time.Now()
@@ -670,7 +671,7 @@ Rules:
 		slog.Int("shown_to_llm", symbolCap),
 		slog.Int("tier0_count", tier0Count),
 		slog.Int("tier1_count", tier1Count),
-		slog.String("top_15", candidatePreview.String()),
+		telemetry.SensitiveLog("top_15", candidatePreview.String()),
 	)
 
 	messages := []providers.Message{
@@ -700,7 +701,7 @@ Rules:
 
 	// IT-12 Rev 5c: Log raw LLM response for debugging.
 	e.logger.Info("IT-12: LLM raw response for conceptual resolution",
-		slog.String("raw_response", response),
+		telemetry.SensitiveLog("raw_response", response),
 		slog.Duration("duration", duration),
 	)
 
@@ -714,11 +715,11 @@ Rules:
 	// Validate it's actually in our candidate list (exact match on full response)
 	for _, c := range candidates {
 		if c.Name == symbolName {
-			span.SetAttributes(attribute.String("resolved_symbol", symbolName))
+			span.SetAttributes(telemetry.SensitiveString("resolved_symbol", symbolName))
 			RecordParamExtractionLatency(e.config.Model, "conceptual_success", duration.Seconds())
 			RecordParamExtractionTotal(e.config.Model, "conceptual_success")
 			e.logger.Info("IT-12: conceptual symbol resolution succeeded",
-				slog.String("resolved", symbolName),
+				telemetry.SensitiveLog("resolved", symbolName),
 				slog.Duration("duration", duration),
 			)
 			return symbolName, nil
@@ -738,12 +739,12 @@ Rules:
 	if firstToken != symbolName {
 		for _, c := range candidates {
 			if c.Name == firstToken {
-				span.SetAttributes(attribute.String("resolved_symbol", c.Name))
+				span.SetAttributes(telemetry.SensitiveString("resolved_symbol", c.Name))
 				RecordParamExtractionLatency(e.config.Model, "conceptual_success", duration.Seconds())
 				RecordParamExtractionTotal(e.config.Model, "conceptual_success")
 				e.logger.Info("IT-12: conceptual symbol resolution succeeded (first-token match)",
-					slog.String("llm_response", symbolName),
-					slog.String("resolved", c.Name),
+					telemetry.SensitiveLog("llm_response", symbolName),
+					telemetry.SensitiveLog("resolved", c.Name),
 					slog.Duration("duration", duration),
 				)
 				return c.Name, nil
@@ -754,12 +755,12 @@ Rules:
 	// Try partial match (LLM might return "Type.Method" when candidate is just "Method")
 	for _, c := range candidates {
 		if strings.HasSuffix(symbolName, "."+c.Name) || strings.HasSuffix(symbolName, c.Name) {
-			span.SetAttributes(attribute.String("resolved_symbol", c.Name))
+			span.SetAttributes(telemetry.SensitiveString("resolved_symbol", c.Name))
 			RecordParamExtractionLatency(e.config.Model, "conceptual_success", duration.Seconds())
 			RecordParamExtractionTotal(e.config.Model, "conceptual_success")
 			e.logger.Info("IT-12: conceptual symbol resolution succeeded (partial match)",
-				slog.String("llm_response", symbolName),
-				slog.String("resolved", c.Name),
+				telemetry.SensitiveLog("llm_response", symbolName),
+				telemetry.SensitiveLog("resolved", c.Name),
 				slog.Duration("duration", duration),
 			)
 			return c.Name, nil
@@ -770,19 +771,19 @@ Rules:
 	// Handles: "The best symbol is _setMaterial because..." → "_setMaterial"
 	for _, c := range candidates {
 		if strings.Contains(symbolName, c.Name) && len(c.Name) > 2 {
-			span.SetAttributes(attribute.String("resolved_symbol", c.Name))
+			span.SetAttributes(telemetry.SensitiveString("resolved_symbol", c.Name))
 			RecordParamExtractionLatency(e.config.Model, "conceptual_success", duration.Seconds())
 			RecordParamExtractionTotal(e.config.Model, "conceptual_success")
 			e.logger.Info("IT-12: conceptual symbol resolution succeeded (contains match)",
-				slog.String("llm_response", symbolName),
-				slog.String("resolved", c.Name),
+				telemetry.SensitiveLog("llm_response", symbolName),
+				telemetry.SensitiveLog("resolved", c.Name),
 				slog.Duration("duration", duration),
 			)
 			return c.Name, nil
 		}
 	}
 
-	span.SetAttributes(attribute.String("llm_response", symbolName))
+	span.SetAttributes(telemetry.SensitiveString("llm_response", symbolName))
 	RecordParamExtractionLatency(e.config.Model, "conceptual_mismatch", duration.Seconds())
 	RecordParamExtractionTotal(e.config.Model, "conceptual_mismatch")
 	return "", fmt.Errorf("LLM returned '%s' which is not in candidate list", symbolName)
@@ -796,7 +797,7 @@ func (e *ParamExtractor) logParamDiff(toolName string, regexHint, llmResult map[
 			e.logger.Info("IT-08b: LLM added param not in regex result",
 				slog.String("tool", toolName),
 				slog.String("param", key),
-				slog.Any("llm_value", llmVal),
+				telemetry.SensitiveLog("llm_value", fmt.Sprint(llmVal)),
 			)
 			continue
 		}
@@ -804,8 +805,8 @@ func (e *ParamExtractor) logParamDiff(toolName string, regexHint, llmResult map[
 			e.logger.Info("IT-08b: LLM corrected regex extraction",
 				slog.String("tool", toolName),
 				slog.String("param", key),
-				slog.Any("regex_value", regexVal),
-				slog.Any("llm_value", llmVal),
+				telemetry.SensitiveLog("regex_value", fmt.Sprint(regexVal)),
+				telemetry.SensitiveLog("llm_value", fmt.Sprint(llmVal)),
 			)
 		}
 	}
@@ -12,6 +12,7 @@ package routing
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"math"
 	"regexp"
@@ -27,6 +28,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -108,8 +110,16 @@ var (
 		Namespace: "trace",
 		Subsystem: "prefilter",
 		Name:      "warmup_source_total",
-		Help:      "Source of warm-up vectors: badger_cache, ollama, timeout",
+		Help:      "Source of warm-up vectors: badger_cache, ollama, hash_fallback, timeout",
 	}, []string{"source"})
+
+	// Routing feedback metrics.
+	prefilterFeedbackTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trace",
+		Subsystem: "prefilter",
+		Name:      "feedback_total",
+		Help:      "Routing feedback events: applied (k-NN vote blended), success, failure (recorded outcomes)",
+	}, []string{"event"})
 )
 
 // =============================================================================
@@ -145,21 +155,50 @@ type compiledPattern struct {
 //	cfg - Pre-filter configuration with rules. Must not be nil.
 //	logger - Logger for structured output. Must not be nil.
 //
-// Thread Safety: Safe for concurrent use (all state is read-only after construction).
+// Thread Safety: Safe for concurrent use. Rules can be replaced with SetRules
+// while queries are filtered; BM25, embeddings and routing feedback are
+// guarded by their own locks.
 type PreFilter struct {
-	registry *config.ToolRoutingRegistry
-	cfg      *config.PreFilterConfig
-	logger   *slog.Logger
+	logger *slog.Logger
+
+	// rulesMu guards rules. Each Filter call reads rules once, so a
+	// concurrent SetRules never mixes old and new rules within a query.
+	rulesMu sync.RWMutex
+	rules   *prefilterRules
 
 	// IT-06c: Hybrid Phase 3 scoring components.
 	// bm25mu is a read-write mutex protecting the bm25 pointer.
 	// Multiple goroutines may read pf.bm25 concurrently (RLock); only the
 	// one-time lazy rebuild writes to it (Lock). Using RWMutex prevents the
 	// per-call read from serializing all concurrent prefilter invocations.
-	bm25mu   sync.RWMutex        // guards bm25 pointer during lazy init
-	bm25     *BM25Index          // BM25 lexical scorer; lazily built on first scored request.
-	embedder *ToolEmbeddingCache // Semantic scorer; lazily warmed on first scored request.
-	warmOnce sync.Once           // ensures embedding warm-up fires exactly once.
+	bm25mu     sync.RWMutex        // guards bm25 pointer and corpusHash
+	bm25       *BM25Index          // BM25 lexical scorer; lazily built on first scored request.
+	corpusHash string              // corpus hash of the specs bm25 was built from
+	embedder   *ToolEmbeddingCache // Semantic scorer; lazily warmed on first scored request.
+	warmOnce   sync.Once           // ensures embedding warm-up fires exactly once.
+
+	// feedback re-ranks Phase 3 scores from past routing outcomes.
+	// Nil when feedback is disabled (FeedbackWeight <= 0).
+	feedback *FeedbackIndex
+
+	// queryVecs remembers recent query embeddings so RecordFeedback can
+	// reuse the vector that produced the routing decision. Bounded FIFO.
+	queryVecMu    sync.Mutex
+	queryVecs     map[string][]float32
+	queryVecOrder []string
+}
+
+// feedbackQueryCacheSize bounds the remembered query embeddings.
+const feedbackQueryCacheSize = 256
+
+// prefilterRules is the rule set a PreFilter applies: the configuration,
+// the registry, and the patterns compiled from the configuration.
+//
+// Thread Safety: Immutable after construction; SetRules replaces the
+// whole value.
+type prefilterRules struct {
+	registry *config.ToolRoutingRegistry
+	cfg      *config.PreFilterConfig
 
 	// compiledForcedPatterns holds pre-compiled patterns per forced mapping index.
 	compiledForcedPatterns [][]compiledPattern
@@ -206,6 +245,11 @@ type PreFilterResult struct {
 
 	// Duration is how long the pre-filter took.
 	Duration time.Duration
+
+	// Decision explains the result: candidate scores, rule hits and
+	// adjustments. FinalTool is the forced tool or the top candidate until
+	// the router runs.
+	Decision *agent.RoutingDecision
 }
 
 // NewPreFilter creates a new PreFilter.
@@ -226,12 +270,19 @@ type PreFilterResult struct {
 //	computed vectors for future service restarts. Pass nil for tests and for
 //	deployments without a routing cache directory.
 //
+//	With cfg.HashEmbeddingFallback, an unreachable Ollama endpoint at warm-up
+//	switches Phase 3 to local hash embeddings instead of BM25-only scoring.
+//
 // Inputs:
 //
 //	registry - Tool routing registry for keyword lookup. May be nil.
 //	cfg      - Pre-filter configuration. Must not be nil.
 //	logger   - Logger instance. Must not be nil.
 //	store    - Optional BadgerDB embedding cache store. Nil disables persistence.
+//	           If it also implements RoutingFeedbackStore, routing feedback
+//	           is persisted there; otherwise feedback is kept in memory.
+//	           If it also implements ToolEmbeddingStore, tool vectors are
+//	           persisted per tool and partial warm-ups resume on restart.
 //
 // Outputs:
 //
@@ -252,38 +303,102 @@ func NewPreFilter(registry *config.ToolRoutingRegistry, cfg *config.PreFilterCon
 	}
 
 	pf := &PreFilter{
-		registry: registry,
-		cfg:      cfg,
 		logger:   logger,
+		rules:    compileRules(registry, cfg, logger),
 		embedder: NewToolEmbeddingCache(logger, store),
 		bm25:     BuildBM25Index(nil), // empty; replaced on first scored call
 	}
 
+	if cfg.HashEmbeddingFallback {
+		pf.embedder.EnableHashFallback()
+	}
+
+	if cfg.FeedbackWeight > 0 {
+		fbStore, _ := store.(RoutingFeedbackStore)
+		pf.feedback = NewFeedbackIndex(fbStore, cfg.FeedbackK, cfg.FeedbackWeight, logger)
+		pf.queryVecs = make(map[string][]float32)
+	}
+
+	return pf
+}
+
+// compileRules builds a rule set, pre-compiling the config's patterns.
+func compileRules(registry *config.ToolRoutingRegistry, cfg *config.PreFilterConfig, logger *slog.Logger) *prefilterRules {
+	r := &prefilterRules{registry: registry, cfg: cfg}
+
 	// Pre-compile regex patterns for forced mappings.
-	pf.compiledForcedPatterns = make([][]compiledPattern, len(cfg.ForcedMappings))
+	r.compiledForcedPatterns = make([][]compiledPattern, len(cfg.ForcedMappings))
 	for i, fm := range cfg.ForcedMappings {
-		pf.compiledForcedPatterns[i] = compilePatterns(fm.Patterns, logger)
+		r.compiledForcedPatterns[i] = compilePatterns(fm.Patterns, logger)
 	}
 
 	// Pre-compile regex patterns for confusion pairs.
-	pf.compiledConfusionAPatterns = make([][]compiledPattern, len(cfg.ConfusionPairs))
-	pf.compiledConfusionBPatterns = make([][]compiledPattern, len(cfg.ConfusionPairs))
+	r.compiledConfusionAPatterns = make([][]compiledPattern, len(cfg.ConfusionPairs))
+	r.compiledConfusionBPatterns = make([][]compiledPattern, len(cfg.ConfusionPairs))
 	for i, cp := range cfg.ConfusionPairs {
-		pf.compiledConfusionAPatterns[i] = compilePatterns(cp.ToolAPatterns, logger)
-		pf.compiledConfusionBPatterns[i] = compilePatterns(cp.ToolBPatterns, logger)
+		r.compiledConfusionAPatterns[i] = compilePatterns(cp.ToolAPatterns, logger)
+		r.compiledConfusionBPatterns[i] = compilePatterns(cp.ToolBPatterns, logger)
 	}
 
 	// CB-62 Rev 2: Pre-compile regex patterns for routing encyclopedia entries.
-	pf.compiledEncyclopediaPatterns = make([][]compiledPattern, len(cfg.RoutingEncyclopedia))
+	r.compiledEncyclopediaPatterns = make([][]compiledPattern, len(cfg.RoutingEncyclopedia))
 	for i, entry := range cfg.RoutingEncyclopedia {
 		patterns := make([]string, len(entry.Intents))
 		for j, intent := range entry.Intents {
 			patterns[j] = intent.Pattern
 		}
-		pf.compiledEncyclopediaPatterns[i] = compilePatterns(patterns, logger)
+		r.compiledEncyclopediaPatterns[i] = compilePatterns(patterns, logger)
 	}
 
-	return pf
+	return r
+}
+
+// SetRules replaces the pre-filter's configuration and registry.
+//
+// Description:
+//
+//	Compiles the new rules and swaps them in atomically. Filter calls
+//	already running finish with the rules they started with; later calls
+//	use the new ones. The BM25 index, embedding cache and routing feedback
+//	are kept: they are built from tool specs, not rules. FeedbackWeight,
+//	FeedbackK and HashEmbeddingFallback are read only by NewPreFilter, so
+//	changing them needs a new PreFilter.
+//
+// Inputs:
+//
+//	registry - Tool routing registry for keyword lookup. May be nil.
+//	cfg      - Validated pre-filter configuration. Must not be nil.
+//
+// Outputs:
+//
+//	error - Non-nil if cfg is nil; the rules are unchanged.
+//
+// Thread Safety: Safe for concurrent use.
+func (pf *PreFilter) SetRules(registry *config.ToolRoutingRegistry, cfg *config.PreFilterConfig) error {
+	if cfg == nil {
+		return fmt.Errorf("SetRules: cfg must not be nil")
+	}
+	rules := compileRules(registry, cfg, pf.logger)
+
+	pf.rulesMu.Lock()
+	pf.rules = rules
+	pf.rulesMu.Unlock()
+	return nil
+}
+
+// Config returns the pre-filter configuration currently applied.
+//
+// Thread Safety: Safe for concurrent use.
+func (pf *PreFilter) Config() *config.PreFilterConfig {
+	return pf.currentRules().cfg
+}
+
+// currentRules returns the current rule set. Callers use the returned
+// value for a whole query.
+func (pf *PreFilter) currentRules() *prefilterRules {
+	pf.rulesMu.RLock()
+	defer pf.rulesMu.RUnlock()
+	return pf.rules
 }
 
 // compilePatterns pre-compiles a list of patterns, treating ".*" patterns as regex.
@@ -297,7 +412,7 @@ func compilePatterns(patterns []string, logger *slog.Logger) []compiledPattern {
 			if err != nil {
 				logger.Warn("prefilter: invalid regex pattern, will skip",
 					slog.String("pattern", pattern),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 			} else {
 				cp.regex = re
@@ -340,20 +455,22 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 	ctx, span := prefilterTracer.Start(ctx, "routing.PreFilter.Filter")
 	defer span.End()
 
+	rules := pf.currentRules()
 	result := &PreFilterResult{
 		NarrowedSpecs: allSpecs,
 		Scores:        make(map[string]float64),
 		OriginalCount: len(allSpecs),
+		Decision:      agent.NewRoutingDecision(query, len(allSpecs)),
 	}
 
 	// Passthrough conditions
-	if !pf.cfg.Enabled || len(query) == 0 || len(allSpecs) == 0 {
+	if !rules.cfg.Enabled || len(query) == 0 || len(allSpecs) == 0 {
 		result.NarrowedCount = len(allSpecs)
 		result.Duration = time.Since(start)
 		prefilterPassthroughTotal.Inc()
 		span.SetAttributes(
 			attribute.Bool("passthrough", true),
-			attribute.String("reason", pf.passthroughReason(query, allSpecs)),
+			attribute.String("reason", rules.passthroughReason(query, allSpecs)),
 		)
 		return result
 	}
@@ -367,7 +484,7 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 	}
 
 	// Phase 0: Routing Encyclopedia (CB-62 Rev 2)
-	encycForcedTool, encycBoosts, encycHints := pf.applyEncyclopedia(queryLower)
+	encycForcedTool, encycBoosts, encycHints := rules.applyEncyclopedia(queryLower)
 	if encycForcedTool != "" {
 		// Validate forced tool exists in the available spec set
 		if _, exists := specIndex[encycForcedTool]; exists {
@@ -376,6 +493,7 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 			result.AppliedRules = append(result.AppliedRules, "encyclopedia_force:"+encycForcedTool)
 			result.NarrowedCount = 1
 			result.Duration = time.Since(start)
+			result.describeForced()
 
 			prefilterForcedTotal.WithLabelValues("encyclopedia_force", encycForcedTool).Inc()
 			prefilterRulesFired.WithLabelValues("encyclopedia_force").Inc()
@@ -390,7 +508,7 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 
 			pf.logger.Info("prefilter encyclopedia force selection",
 				slog.String("tool", encycForcedTool),
-				slog.String("query_preview", truncateForLog(query, 80)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 80)),
 			)
 			return result
 		}
@@ -400,7 +518,7 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 	}
 
 	// Phase 1: Forced mapping check
-	if tool, reason, matched := pf.checkForcedMappings(queryLower); matched {
+	if tool, reason, matched := rules.checkForcedMappings(queryLower); matched {
 		// Validate forced tool exists in the available spec set
 		if _, exists := specIndex[tool]; !exists {
 			pf.logger.Warn("prefilter forced mapping tool not in spec set, skipping",
@@ -413,6 +531,7 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 			result.AppliedRules = append(result.AppliedRules, "forced_mapping:"+tool)
 			result.NarrowedCount = 1
 			result.Duration = time.Since(start)
+			result.describeForced()
 
 			prefilterForcedTotal.WithLabelValues("forced_mapping", tool).Inc()
 			prefilterRulesFired.WithLabelValues("forced_mapping").Inc()
@@ -428,14 +547,14 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 			pf.logger.Info("prefilter forced selection",
 				slog.String("tool", tool),
 				slog.String("reason", reason),
-				slog.String("query_preview", truncateForLog(query, 80)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 80)),
 			)
 			return result
 		}
 	}
 
 	// Phase 2: Negation detection
-	if tool, reason, matched := pf.checkNegationRules(queryLower); matched {
+	if tool, reason, matched := rules.checkNegationRules(queryLower); matched {
 		// Validate forced tool exists in the available spec set
 		if _, exists := specIndex[tool]; !exists {
 			pf.logger.Warn("prefilter negation tool not in spec set, skipping",
@@ -448,6 +567,7 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 			result.AppliedRules = append(result.AppliedRules, "negation:"+tool)
 			result.NarrowedCount = 1
 			result.Duration = time.Since(start)
+			result.describeForced()
 
 			prefilterForcedTotal.WithLabelValues("negation", tool).Inc()
 			prefilterRulesFired.WithLabelValues("negation").Inc()
@@ -463,17 +583,21 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 			pf.logger.Info("prefilter negation forced",
 				slog.String("tool", tool),
 				slog.String("reason", reason),
-				slog.String("query_preview", truncateForLog(query, 80)),
+				telemetry.SensitiveLog("query_preview", truncateForLog(query, 80)),
 			)
 			return result
 		}
 	}
 
 	// Phase 3: Hybrid scoring (BM25 + embedding + UCB1 session penalty).
-	scores := pf.scoreHybrid(ctx, queryLower, allSpecs, sessionCounts)
+	hybrid := pf.scoreHybridDetailed(ctx, rules, queryLower, allSpecs, sessionCounts)
+	scores := hybrid.scores
 	for k, v := range scores {
 		result.Scores[k] = v
 	}
+	for _, adj := range hybrid.adjustments {
+		result.Decision.AddAdjustment(adj.Tool, adj.Rule, adj.Delta)
+	}
 	if len(scores) > 0 {
 		result.AppliedRules = append(result.AppliedRules, "hybrid_scoring")
 		prefilterRulesFired.WithLabelValues("hybrid_scoring").Inc()
@@ -486,15 +610,16 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 		for tool, boost := range encycBoosts {
 			result.Scores[tool] += boost
 			result.AppliedRules = append(result.AppliedRules, "encyclopedia_boost:"+tool)
+			result.Decision.AddAdjustment(tool, "encyclopedia_boost", boost)
 		}
 		prefilterRulesFired.WithLabelValues("encyclopedia_boost").Inc()
 	}
 
 	// Phase 4: Confusion pair resolution
-	pf.resolveConfusionPairs(queryLower, result.Scores, result)
+	rules.resolveConfusionPairs(queryLower, result.Scores, result)
 
 	// Phase 5: Candidate selection
-	narrowed := pf.selectCandidates(result.Scores, allSpecs)
+	narrowed := rules.selectCandidates(result.Scores, allSpecs)
 
 	// Phase 5.5: Ensure encyclopedia hints are in the candidate set (CB-62 Rev 2).
 	if len(encycHints) > 0 {
@@ -517,6 +642,7 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 	result.NarrowedSpecs = narrowed
 	result.NarrowedCount = len(narrowed)
 	result.Duration = time.Since(start)
+	result.describeScored(rules, hybrid)
 
 	prefilterLatency.Observe(result.Duration.Seconds())
 	prefilterNarrowedCount.Observe(float64(result.NarrowedCount))
@@ -531,7 +657,7 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 		pf.logger.Info("prefilter narrowed candidates",
 			slog.Int("original", result.OriginalCount),
 			slog.Int("narrowed", result.NarrowedCount),
-			slog.String("query_preview", truncateForLog(query, 80)),
+			telemetry.SensitiveLog("query_preview", truncateForLog(query, 80)),
 		)
 	} else {
 		prefilterPassthroughTotal.Inc()
@@ -560,12 +686,12 @@ func (pf *PreFilter) Filter(ctx context.Context, query string, allSpecs []ToolSp
 //	tool - The forced tool name, or "" if no match.
 //	reason - The reason for forcing, or "" if no match.
 //	matched - True if a forced mapping matched.
-func (pf *PreFilter) checkForcedMappings(queryLower string) (tool string, reason string, matched bool) {
-	for i, fm := range pf.cfg.ForcedMappings {
-		if i >= len(pf.compiledForcedPatterns) {
+func (r *prefilterRules) checkForcedMappings(queryLower string) (tool string, reason string, matched bool) {
+	for i, fm := range r.cfg.ForcedMappings {
+		if i >= len(r.compiledForcedPatterns) {
 			break
 		}
-		for _, cp := range pf.compiledForcedPatterns[i] {
+		for _, cp := range r.compiledForcedPatterns[i] {
 			if matchCompiledPattern(queryLower, cp) {
 				return fm.Tool, fm.Reason, true
 			}
@@ -614,13 +740,13 @@ func matchCompiledPattern(queryLower string, cp compiledPattern) bool {
 //	tool - The correct tool to use, or "" if no match.
 //	reason - The reason for the correction, or "" if no match.
 //	matched - True if a negation pattern was detected.
-func (pf *PreFilter) checkNegationRules(queryLower string) (tool string, reason string, matched bool) {
+func (r *prefilterRules) checkNegationRules(queryLower string) (tool string, reason string, matched bool) {
 	words := strings.Fields(queryLower)
 	if len(words) == 0 {
 		return "", "", false
 	}
 
-	for _, rule := range pf.cfg.NegationRules {
+	for _, rule := range r.cfg.NegationRules {
 		// Find positions of negation words
 		var negPositions []int
 		for i, word := range words {
@@ -673,7 +799,7 @@ func (pf *PreFilter) checkNegationRules(queryLower string) (tool string, reason
 			for _, kwPos := range kwPositions {
 				if kwPos > negPos {
 					dist := kwPos - negPos
-					if dist <= pf.cfg.NegationProximity {
+					if dist <= r.cfg.NegationProximity {
 						return rule.CorrectTool, rule.Reason, true
 					}
 				}
@@ -714,6 +840,7 @@ func (pf *PreFilter) checkNegationRules(queryLower string) (tool string, reason
 // # Inputs
 //
 //   - ctx: Context for the embedding HTTP call.
+//   - rules: The rule set of the current query.
 //   - queryLower: Lowercase query string.
 //   - allSpecs: All available tool specs (used only for BestFor fallback).
 //   - sessionCounts: Per-tool selection counts for this session. May be nil.
@@ -721,36 +848,73 @@ func (pf *PreFilter) checkNegationRules(queryLower string) (tool string, reason
 // # Outputs
 //
 //   - map[string]float64: Tool name → score. Nil in passthrough mode (embeddings unavailable).
-func (pf *PreFilter) scoreHybrid(ctx context.Context, queryLower string, allSpecs []ToolSpec, sessionCounts map[string]int) map[string]float64 {
-	// --- Lazy corpus init (one-time, double-checked) ---
+func (pf *PreFilter) scoreHybrid(ctx context.Context, rules *prefilterRules, queryLower string, allSpecs []ToolSpec, sessionCounts map[string]int) map[string]float64 {
+	return pf.scoreHybridDetailed(ctx, rules, queryLower, allSpecs, sessionCounts).scores
+}
+
+// hybridScores is the output of scoreHybridDetailed: the ranking scores
+// and the components behind them, for the routing decision record.
+type hybridScores struct {
+	// scores are the ranking scores. Nil in passthrough mode.
+	scores map[string]float64
+
+	// bm25 and embedding are the per-tool component scores. embedding is
+	// nil when embeddings are unavailable.
+	bm25      map[string]float64
+	embedding map[string]float64
+
+	// adjustments are the routing feedback and session penalty changes.
+	adjustments []agent.RoutingAdjustment
+}
+
+// scoreHybridDetailed is scoreHybrid returning the component scores too.
+// BM25 is always computed for the record, but in embedding_primary mode
+// it does not affect the ranking.
+func (pf *PreFilter) scoreHybridDetailed(ctx context.Context, rules *prefilterRules, queryLower string, allSpecs []ToolSpec, sessionCounts map[string]int) *hybridScores {
+	// --- Lazy corpus init and change detection (double-checked) ---
 	// On the first call that provides non-empty specs, build the BM25 index
-	// and kick off the background embedding warm-up exactly once.
+	// and run the embedding warm-up exactly once. When a later call brings a
+	// different corpus (project tools, edited descriptions), rebuild BM25 at
+	// once and re-embed the changed tools in the background; embedding
+	// scores are unavailable until that completes.
 	//
 	// warmOnce.Do is called AFTER releasing bm25mu to avoid nesting a sync.Once
 	// inside an external lock. specsForWarm is captured while the write lock is
 	// held (before bm25mu.Unlock) so it's safe to use in the goroutine.
-	var specsForWarm []ToolSpec
+	var specsForWarm, specsForRefresh []ToolSpec
 	if len(allSpecs) > 0 {
-		// Fast path: read lock to check emptiness without blocking other readers.
+		hash := computeCorpusHash(allSpecs, pf.embedder.model)
+
+		// Fast path: read lock to compare hashes without blocking other readers.
 		pf.bm25mu.RLock()
-		isEmpty := pf.bm25.IsEmpty()
+		changed := hash != pf.corpusHash
 		pf.bm25mu.RUnlock()
 
-		if isEmpty {
+		if changed {
 			pf.bm25mu.Lock()
-			// Double-check: another goroutine may have built it while we waited.
-			if pf.bm25.IsEmpty() {
+			// Double-check: another goroutine may have rebuilt it while we waited.
+			if hash != pf.corpusHash {
+				first := pf.corpusHash == ""
 				pf.bm25 = BuildBM25Index(allSpecs)
+				pf.corpusHash = hash
 				pf.logger.Info("prefilter: BM25 corpus built",
 					slog.Int("tool_count", len(allSpecs)),
+					slog.String("corpus_hash", shortHash(hash)),
 				)
 				// Capture allSpecs snapshot here, under the write lock, for use
-				// in the warmOnce goroutine below (after the lock is released).
-				specsForWarm = allSpecs
+				// after the lock is released.
+				if first {
+					specsForWarm = allSpecs
+				} else {
+					specsForRefresh = allSpecs
+				}
 			}
 			pf.bm25mu.Unlock()
 		}
 	}
+	if specsForRefresh != nil {
+		pf.embedder.Refresh(specsForRefresh)
+	}
 
 	// CB-62: Synchronous embedding warm-up with bounded timeout.
 	// Belt-and-suspenders: startup already warms, but this protects against
@@ -762,9 +926,11 @@ func (pf *PreFilter) scoreHybrid(ctx context.Context, queryLower string, allSpec
 			warmStart := time.Now()
 			if err := pf.embedder.Warm(warmCtx, specsForWarm); err != nil {
 				pf.logger.Warn("prefilter: embedding warm-up failed, will passthrough all tools",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				prefilterWarmupSource.WithLabelValues("timeout").Inc()
+			} else if pf.embedder.UsingHashFallback() {
+				prefilterWarmupSource.WithLabelValues("hash_fallback").Inc()
 			} else {
 				prefilterWarmupSource.WithLabelValues("ollama").Inc()
 			}
@@ -774,18 +940,36 @@ func (pf *PreFilter) scoreHybrid(ctx context.Context, queryLower string, allSpec
 
 	// --- Embedding ---
 	embStart := time.Now()
-	embScores, _ := pf.embedder.Score(ctx, queryLower) // nil on graceful degradation
+	embScores, queryVec := pf.embedder.scoreWithVector(ctx, queryLower) // nil on graceful degradation
 	prefilterEmbeddingLatency.Observe(time.Since(embStart).Seconds())
 
+	// --- BM25 ---
+	// Computed in both modes for the decision record (microseconds); only
+	// hybrid mode ranks with it.
+	pf.bm25mu.RLock()
+	bm25idx := pf.bm25
+	pf.bm25mu.RUnlock()
+
+	bm25Start := time.Now()
+	bm25Scores := bm25idx.Score(queryLower)
+	prefilterBM25Latency.Observe(time.Since(bm25Start).Seconds())
+
+	out := &hybridScores{bm25: bm25Scores, embedding: embScores}
+
 	// --- Scoring mode switch (CB-62) ---
 	var scores map[string]float64
-	if pf.cfg.ScoringMode == "embedding_primary" {
+	if rules.cfg.ScoringMode == "embedding_primary" {
 		// CB-62: In embedding_primary mode, BM25 is never used for ranking.
-		// Skip BM25 computation entirely to avoid wasted CPU.
 		if embScores != nil {
 			// Normal path: pure embedding scoring.
 			scores = embScores
 			prefilterHybridMethodTotal.WithLabelValues("embedding_primary").Inc()
+		} else if pf.embedder.Reembedding() {
+			// The corpus changed and its tools are being re-embedded in the
+			// background. BM25 already covers the new corpus; use it until
+			// the new vectors are swapped in.
+			scores = bm25Scores
+			prefilterHybridMethodTotal.WithLabelValues("bm25_only").Inc()
 		} else {
 			// Degraded path: Ollama unavailable.
 			// DO NOT use BM25 — it cannot handle typos, misspellings, or synonyms.
@@ -797,19 +981,12 @@ func (pf *PreFilter) scoreHybrid(ctx context.Context, queryLower string, allSpec
 		}
 	} else {
 		// Legacy hybrid mode (backward compat): 0.4 BM25 + 0.6 embedding.
-		// BM25 is only computed in hybrid mode.
-		pf.bm25mu.RLock()
-		bm25idx := pf.bm25
-		pf.bm25mu.RUnlock()
-
-		bm25Start := time.Now()
-		bm25Scores := bm25idx.Score(queryLower)
-		prefilterBM25Latency.Observe(time.Since(bm25Start).Seconds())
 
 		// Fall back to legacy keyword counting only when the BM25 corpus is empty
 		// (service startup race: allSpecs arrived but BM25 hasn't been built yet).
 		if len(bm25Scores) == 0 && bm25idx.IsEmpty() {
-			bm25Scores = pf.scoreByKeywordsLegacy(queryLower, allSpecs)
+			bm25Scores = rules.scoreByKeywordsLegacy(queryLower, allSpecs)
+			out.bm25 = bm25Scores
 		}
 
 		if embScores == nil {
@@ -839,6 +1016,24 @@ func (pf *PreFilter) scoreHybrid(ctx context.Context, queryLower string, allSpec
 		}
 	}
 
+	// --- Routing feedback (k-NN re-rank) ---
+	// Past outcomes for similar queries nudge scores. No neighbors → no-op,
+	// so cold start matches routing without feedback.
+	if scores != nil && queryVec != nil && pf.feedback != nil {
+		pf.rememberQueryVector(queryLower, queryVec)
+		if vote := pf.feedback.Vote(ctx, queryVec, pf.embedder.Model()); vote != nil {
+			before := make(map[string]float64, len(vote))
+			for tool := range vote {
+				before[tool] = scores[tool]
+			}
+			pf.feedback.Blend(scores, vote)
+			for tool, prev := range before {
+				out.adjustments = append(out.adjustments, agent.RoutingAdjustment{Tool: tool, Rule: "feedback", Delta: scores[tool] - prev})
+			}
+			prefilterFeedbackTotal.WithLabelValues("applied").Inc()
+		}
+	}
+
 	// --- UCB1 session penalty (Option K) ---
 	// Tools used more often in this session get progressively penalized,
 	// encouraging the router to explore alternatives.
@@ -849,21 +1044,137 @@ func (pf *PreFilter) scoreHybrid(ctx context.Context, queryLower string, allSpec
 			n := sessionCounts[tool]
 			if n > 0 {
 				scores[tool] = math.Max(0, s-penaltyPerUse*float64(n))
+				out.adjustments = append(out.adjustments, agent.RoutingAdjustment{Tool: tool, Rule: "session_penalty", Delta: scores[tool] - s})
 			}
 		}
 	}
 
-	return scores
+	out.scores = scores
+	return out
+}
+
+// RecordFeedback records the outcome of routing query to tool.
+//
+// # Description
+//
+// Reuses the query embedding computed at routing time when available and
+// embeds the query otherwise. A no-op when feedback is disabled or
+// embeddings are unavailable.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - query: The routed query (case-insensitive).
+//   - tool: The selected tool.
+//   - success: True if the tool result was accepted.
+//
+// # Outputs
+//
+//   - error: Non-nil if embedding or persistence fails.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (pf *PreFilter) RecordFeedback(ctx context.Context, query, tool string, success bool) error {
+	if pf.feedback == nil || tool == "" || query == "" {
+		return nil
+	}
+	queryLower := strings.ToLower(query)
+
+	pf.queryVecMu.Lock()
+	vec := pf.queryVecs[queryLower]
+	pf.queryVecMu.Unlock()
+
+	if vec == nil {
+		if !pf.embedder.IsWarmed() {
+			return nil
+		}
+		var err error
+		vec, err = pf.embedder.EmbedQuery(ctx, queryLower)
+		if err != nil {
+			return err
+		}
+		if vec == nil {
+			return nil
+		}
+	}
+
+	outcome := "failure"
+	if success {
+		outcome = "success"
+	}
+	prefilterFeedbackTotal.WithLabelValues(outcome).Inc()
+
+	return pf.feedback.Record(ctx, RoutingFeedback{
+		Query:   queryLower,
+		Tool:    tool,
+		Success: success,
+		Model:   pf.embedder.Model(),
+		Vector:  vec,
+	})
+}
+
+// FeedbackIndex returns the routing feedback index, or nil if disabled.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (pf *PreFilter) FeedbackIndex() *FeedbackIndex {
+	return pf.feedback
+}
+
+// ReembedStatus describes a queued re-embedding of the tool corpus.
+type ReembedStatus struct {
+	// CorpusHash is the hash of the corpus being re-embedded.
+	CorpusHash string `json:"corpus_hash"`
+
+	// ToolCount is the number of tools in the corpus.
+	ToolCount int `json:"tool_count"`
+}
+
+// Reembed queues a forced background re-embedding of every tool.
+//
+// Description:
+//
+//	Cached vectors are ignored and every tool of the current corpus is
+//	sent to the embedding endpoint again. Queries keep using the current
+//	vectors until the new ones are swapped in. See
+//	ToolEmbeddingCache.Reembed.
+//
+// Outputs:
+//
+//	ReembedStatus - The corpus being re-embedded.
+//	bool - False if no tools have been routed yet, so there is no corpus.
+//
+// Thread Safety: Safe for concurrent use.
+func (pf *PreFilter) Reembed() (ReembedStatus, bool) {
+	hash, tools, ok := pf.embedder.Reembed()
+	return ReembedStatus{CorpusHash: hash, ToolCount: tools}, ok
+}
+
+// rememberQueryVector caches a query embedding for RecordFeedback.
+func (pf *PreFilter) rememberQueryVector(queryLower string, vec []float32) {
+	pf.queryVecMu.Lock()
+	defer pf.queryVecMu.Unlock()
+
+	if _, ok := pf.queryVecs[queryLower]; !ok {
+		pf.queryVecOrder = append(pf.queryVecOrder, queryLower)
+		if len(pf.queryVecOrder) > feedbackQueryCacheSize {
+			delete(pf.queryVecs, pf.queryVecOrder[0])
+			pf.queryVecOrder = pf.queryVecOrder[1:]
+		}
+	}
+	pf.queryVecs[queryLower] = vec
 }
 
 // scoreByKeywordsLegacy is the original keyword substring scoring kept as a
 // fallback when BM25 produces no results (e.g., empty specs at startup).
 // It preserves pre-IT-06c behavior exactly.
-func (pf *PreFilter) scoreByKeywordsLegacy(queryLower string, allSpecs []ToolSpec) map[string]float64 {
+func (r *prefilterRules) scoreByKeywordsLegacy(queryLower string, allSpecs []ToolSpec) map[string]float64 {
 	scores := make(map[string]float64)
 
-	if pf.registry != nil {
-		matches := pf.registry.FindToolsByKeyword(queryLower)
+	if r.registry != nil {
+		matches := r.registry.FindToolsByKeyword(queryLower)
 		for _, m := range matches {
 			scores[m.ToolName] = float64(m.MatchCount)
 		}
@@ -905,34 +1216,40 @@ func (pf *PreFilter) scoreByKeywordsLegacy(queryLower string, allSpecs []ToolSpe
 //	queryLower - Lowercase query string.
 //	scores - Current scores to modify in-place.
 //	result - PreFilterResult to append applied rules.
-func (pf *PreFilter) resolveConfusionPairs(queryLower string, scores map[string]float64, result *PreFilterResult) {
+func (r *prefilterRules) resolveConfusionPairs(queryLower string, scores map[string]float64, result *PreFilterResult) {
 	// CB-62: Scale boost for embedding_primary mode where scores are cosine [0,1].
 	// YAML boost_amount values (e.g., 3.0) were calibrated for the old hybrid blend
 	// where BM25 keyword counts could be large. In embedding_primary mode, a raw
 	// 3.0 boost on [0,1] scores completely overwhelms the ranking.
 	// Scale factor: 0.1 → a YAML boost of 3.0 becomes 0.30 on the [0,1] scale.
 	boostScale := 1.0
-	if pf.cfg.ScoringMode == "embedding_primary" {
+	if r.cfg.ScoringMode == "embedding_primary" {
 		boostScale = 0.1
 	}
 
-	for i, pair := range pf.cfg.ConfusionPairs {
-		if i >= len(pf.compiledConfusionAPatterns) || i >= len(pf.compiledConfusionBPatterns) {
+	for i, pair := range r.cfg.ConfusionPairs {
+		if i >= len(r.compiledConfusionAPatterns) || i >= len(r.compiledConfusionBPatterns) {
 			break
 		}
 
-		aMatched := matchCompiledPatterns(queryLower, pf.compiledConfusionAPatterns[i])
-		bMatched := matchCompiledPatterns(queryLower, pf.compiledConfusionBPatterns[i])
+		aMatched := matchCompiledPatterns(queryLower, r.compiledConfusionAPatterns[i])
+		bMatched := matchCompiledPatterns(queryLower, r.compiledConfusionBPatterns[i])
 
 		boost := pair.BoostAmount * boostScale
 
 		if aMatched && !bMatched {
 			scores[pair.ToolA] += boost
 			result.AppliedRules = append(result.AppliedRules, "confusion_pair_boost:"+pair.ToolA)
+			if result.Decision != nil {
+				result.Decision.AddAdjustment(pair.ToolA, "confusion_pair", boost)
+			}
 			prefilterRulesFired.WithLabelValues("confusion_pair").Inc()
 		} else if bMatched && !aMatched {
 			scores[pair.ToolB] += boost
 			result.AppliedRules = append(result.AppliedRules, "confusion_pair_boost:"+pair.ToolB)
+			if result.Decision != nil {
+				result.Decision.AddAdjustment(pair.ToolB, "confusion_pair", boost)
+			}
 			prefilterRulesFired.WithLabelValues("confusion_pair").Inc()
 		}
 		// If both or neither match, no boost applied — let the router decide
@@ -949,6 +1266,81 @@ func matchCompiledPatterns(queryLower string, patterns []compiledPattern) bool {
 	return false
 }
 
+// =============================================================================
+// Decision Record
+// =============================================================================
+
+// describeForced completes r.Decision for a forced selection.
+func (r *PreFilterResult) describeForced() {
+	d := r.Decision
+	for _, rule := range r.AppliedRules {
+		d.AddRuleHit(rule)
+	}
+	d.ForcedReason = r.ForcedReason
+	d.NarrowedCount = r.NarrowedCount
+	d.SetFinal(r.ForcedTool, agent.RoutingSourcePrefilterForced)
+}
+
+// describeScored completes r.Decision after scoring: the top
+// agent.MaxRoutingCandidates tools with their component scores, the rules
+// that fired, and the top candidate as provisional final choice.
+func (r *PreFilterResult) describeScored(rules *prefilterRules, hybrid *hybridScores) {
+	d := r.Decision
+	d.ScoringMode = rules.cfg.ScoringMode
+	if d.ScoringMode == "" {
+		d.ScoringMode = "hybrid"
+	}
+	d.NarrowedCount = r.NarrowedCount
+	for _, rule := range r.AppliedRules {
+		d.AddRuleHit(rule)
+	}
+
+	if len(r.Scores) > 0 {
+		d.Scores = make(map[string]float64, len(r.Scores))
+		for tool, score := range r.Scores {
+			d.Scores[tool] = score
+		}
+	}
+	narrowed := make(map[string]bool, len(r.NarrowedSpecs))
+	for _, spec := range r.NarrowedSpecs {
+		narrowed[spec.Name] = true
+	}
+	for _, tool := range topScored(r.Scores, agent.MaxRoutingCandidates) {
+		c := agent.RoutingCandidate{
+			Tool:     tool,
+			Score:    r.Scores[tool],
+			Narrowed: narrowed[tool],
+		}
+		if hybrid != nil {
+			c.BM25 = hybrid.bm25[tool]
+			c.Embedding = hybrid.embedding[tool]
+		}
+		d.Candidates = append(d.Candidates, c)
+	}
+	if len(d.Candidates) > 0 {
+		d.SetFinal(d.Candidates[0].Tool, agent.RoutingSourcePrefilterTop)
+	}
+	d.Prune()
+}
+
+// topScored returns up to n tool names by descending score, ties by name.
+func topScored(scores map[string]float64, n int) []string {
+	names := make([]string, 0, len(scores))
+	for name := range scores {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if scores[names[i]] != scores[names[j]] {
+			return scores[names[i]] > scores[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > n {
+		names = names[:n]
+	}
+	return names
+}
+
 // =============================================================================
 // Phase 5: Candidate Selection
 // =============================================================================
@@ -974,7 +1366,7 @@ func matchCompiledPatterns(queryLower string, patterns []compiledPattern) bool {
 // Outputs:
 //
 //	[]ToolSpec - The narrowed candidate set.
-func (pf *PreFilter) selectCandidates(scores map[string]float64, allSpecs []ToolSpec) []ToolSpec {
+func (r *prefilterRules) selectCandidates(scores map[string]float64, allSpecs []ToolSpec) []ToolSpec {
 	// Passthrough: no scores means embeddings unavailable, let router see everything.
 	if len(scores) == 0 {
 		return allSpecs
@@ -999,13 +1391,13 @@ func (pf *PreFilter) selectCandidates(scores map[string]float64, allSpecs []Tool
 	// Apply score floor: drop tools below ScoreFloor.
 	var aboveFloor []scoredTool
 	for _, st := range sorted {
-		if st.score >= pf.cfg.ScoreFloor {
+		if st.score >= r.cfg.ScoreFloor {
 			aboveFloor = append(aboveFloor, st)
 		}
 	}
 	// Ensure at least MinCandidates (even if below floor).
-	if len(aboveFloor) < pf.cfg.MinCandidates && len(sorted) > len(aboveFloor) {
-		limit := pf.cfg.MinCandidates
+	if len(aboveFloor) < r.cfg.MinCandidates && len(sorted) > len(aboveFloor) {
+		limit := r.cfg.MinCandidates
 		if limit > len(sorted) {
 			limit = len(sorted)
 		}
@@ -1014,16 +1406,16 @@ func (pf *PreFilter) selectCandidates(scores map[string]float64, allSpecs []Tool
 
 	// Find gap cutoff: starting at MinCandidates, scan for first gap > threshold.
 	cutoff := len(aboveFloor)
-	for i := pf.cfg.MinCandidates; i < len(aboveFloor); i++ {
+	for i := r.cfg.MinCandidates; i < len(aboveFloor); i++ {
 		gap := aboveFloor[i-1].score - aboveFloor[i].score
-		if gap > pf.cfg.ScoreGapThreshold {
+		if gap > r.cfg.ScoreGapThreshold {
 			cutoff = i
 			break
 		}
 	}
 	// Cap at MaxCandidates.
-	if cutoff > pf.cfg.MaxCandidates {
-		cutoff = pf.cfg.MaxCandidates
+	if cutoff > r.cfg.MaxCandidates {
+		cutoff = r.cfg.MaxCandidates
 	}
 
 	selected := make(map[string]bool, cutoff)
@@ -1032,14 +1424,14 @@ func (pf *PreFilter) selectCandidates(scores map[string]float64, allSpecs []Tool
 	}
 
 	// Always include required tools.
-	for _, name := range pf.cfg.AlwaysInclude {
+	for _, name := range r.cfg.AlwaysInclude {
 		selected[name] = true
 	}
 
 	// If below MinCandidates, fill from allSpecs by original order.
-	if len(selected) < pf.cfg.MinCandidates {
+	if len(selected) < r.cfg.MinCandidates {
 		for _, spec := range allSpecs {
-			if len(selected) >= pf.cfg.MinCandidates {
+			if len(selected) >= r.cfg.MinCandidates {
 				break
 			}
 			selected[spec.Name] = true
@@ -1088,6 +1480,9 @@ type AgentPreFilterResult struct {
 
 	// NarrowedCount is the number of tools after filtering.
 	NarrowedCount int
+
+	// Decision explains the result. See PreFilterResult.Decision.
+	Decision *agent.RoutingDecision
 }
 
 // FilterAgentSpecs narrows agent.ToolRouterSpec candidates.
@@ -1153,6 +1548,7 @@ func (pf *PreFilter) FilterAgentSpecs(ctx context.Context, query string, allSpec
 		AppliedRules:  pfResult.AppliedRules,
 		OriginalCount: pfResult.OriginalCount,
 		NarrowedCount: pfResult.NarrowedCount,
+		Decision:      pfResult.Decision,
 	}
 }
 
@@ -1179,26 +1575,26 @@ func (pf *PreFilter) FilterAgentSpecs(ctx context.Context, query string, allSpec
 //	forcedTool - Non-empty if a tier=force entry matched.
 //	boosts     - Map of tool name → boost amount for tier=boost entries.
 //	hints      - List of tool names for tier=hint entries.
-func (pf *PreFilter) applyEncyclopedia(queryLower string) (string, map[string]float64, []string) {
-	if len(pf.cfg.RoutingEncyclopedia) == 0 {
+func (r *prefilterRules) applyEncyclopedia(queryLower string) (string, map[string]float64, []string) {
+	if len(r.cfg.RoutingEncyclopedia) == 0 {
 		return "", nil, nil
 	}
 
 	boosts := make(map[string]float64)
 	var hints []string
 
-	for i, entry := range pf.cfg.RoutingEncyclopedia {
-		if i >= len(pf.compiledEncyclopediaPatterns) {
+	for i, entry := range r.cfg.RoutingEncyclopedia {
+		if i >= len(r.compiledEncyclopediaPatterns) {
 			break
 		}
 
 		// Check anti-signals first (cheap string contains).
-		if pf.matchesAntiSignals(queryLower, entry.AntiSignals) {
+		if r.matchesAntiSignals(queryLower, entry.AntiSignals) {
 			continue
 		}
 
 		// Check intent patterns.
-		if !matchCompiledPatterns(queryLower, pf.compiledEncyclopediaPatterns[i]) {
+		if !matchCompiledPatterns(queryLower, r.compiledEncyclopediaPatterns[i]) {
 			continue
 		}
 
@@ -1225,7 +1621,7 @@ func (pf *PreFilter) applyEncyclopedia(queryLower string) (string, map[string]fl
 // Outputs:
 //
 //	bool - True if any anti-signal matches.
-func (pf *PreFilter) matchesAntiSignals(queryLower string, antiSignals []string) bool {
+func (r *prefilterRules) matchesAntiSignals(queryLower string, antiSignals []string) bool {
 	for _, signal := range antiSignals {
 		if strings.Contains(queryLower, strings.ToLower(signal)) {
 			return true
@@ -1239,8 +1635,8 @@ func (pf *PreFilter) matchesAntiSignals(queryLower string, antiSignals []string)
 // =============================================================================
 
 // passthroughReason returns a human-readable reason for passthrough.
-func (pf *PreFilter) passthroughReason(query string, allSpecs []ToolSpec) string {
-	if !pf.cfg.Enabled {
+func (r *prefilterRules) passthroughReason(query string, allSpecs []ToolSpec) string {
+	if !r.cfg.Enabled {
 		return "disabled"
 	}
 	if len(query) == 0 {
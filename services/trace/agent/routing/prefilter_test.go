@@ -13,8 +13,10 @@ package routing
 import (
 	"context"
 	"log/slog"
+	"sync"
 	"testing"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
 )
 
@@ -190,6 +192,71 @@ func TestPreFilter_EmptySpecs(t *testing.T) {
 	}
 }
 
+func TestPreFilter_SetRules(t *testing.T) {
+	pf := newTestPreFilter(makeTestConfig())
+	specs := makeTestSpecs(16)
+	query := "show the call chain from main"
+
+	if got := pf.Filter(context.Background(), query, specs, nil).ForcedTool; got != "get_call_chain" {
+		t.Fatalf("before SetRules: forced tool = %q, want get_call_chain", got)
+	}
+
+	cfg := makeTestConfig()
+	cfg.ForcedMappings = []config.ForcedMapping{
+		{Patterns: []string{"call chain from"}, Tool: "find_path", Reason: "reloaded"},
+	}
+	if err := pf.SetRules(nil, cfg); err != nil {
+		t.Fatalf("SetRules: %v", err)
+	}
+	if pf.Config() != cfg {
+		t.Error("Config() does not return the new config")
+	}
+	if got := pf.Filter(context.Background(), query, specs, nil).ForcedTool; got != "find_path" {
+		t.Errorf("after SetRules: forced tool = %q, want find_path", got)
+	}
+
+	if err := pf.SetRules(nil, nil); err == nil {
+		t.Error("expected an error for a nil config")
+	}
+	if pf.Config() != cfg {
+		t.Error("a rejected SetRules must keep the current rules")
+	}
+}
+
+func TestPreFilter_SetRules_Concurrent(t *testing.T) {
+	pf := newTestPreFilter(makeTestConfig())
+	specs := makeTestSpecs(16)
+	other := makeTestConfig()
+	other.ForcedMappings = []config.ForcedMapping{
+		{Patterns: []string{"call chain from"}, Tool: "find_path", Reason: "reloaded"},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				tool := pf.Filter(context.Background(), "show the call chain from main", specs, nil).ForcedTool
+				if tool != "get_call_chain" && tool != "find_path" {
+					t.Errorf("forced tool = %q, want one of the two rule sets", tool)
+					return
+				}
+			}
+		}()
+	}
+	for j := 0; j < 50; j++ {
+		cfg := makeTestConfig()
+		if j%2 == 0 {
+			cfg = other
+		}
+		if err := pf.SetRules(nil, cfg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wg.Wait()
+}
+
 // =============================================================================
 // Forced Mapping Tests
 // =============================================================================
@@ -376,6 +443,63 @@ func TestPreFilter_ConfusionPair_CallersBoosted(t *testing.T) {
 	}
 }
 
+// =============================================================================
+// Decision Record Tests
+// =============================================================================
+
+func TestPreFilter_Decision_Scored(t *testing.T) {
+	pf := newTestPreFilter(makeTestConfig())
+	specs := makeTestSpecs(16)
+
+	result := pf.Filter(context.Background(), "Who calls parseConfig in the server", specs, nil)
+	d := result.Decision
+	if d == nil {
+		t.Fatal("Decision is nil")
+	}
+	if d.NormalizedQuery != "who calls parseconfig in the server" {
+		t.Errorf("NormalizedQuery = %q", d.NormalizedQuery)
+	}
+	if len(d.Candidates) == 0 || len(d.Candidates) > agent.MaxRoutingCandidates {
+		t.Fatalf("got %d candidates, want 1..%d", len(d.Candidates), agent.MaxRoutingCandidates)
+	}
+	for i := 1; i < len(d.Candidates); i++ {
+		if d.Candidates[i].Score > d.Candidates[i-1].Score {
+			t.Errorf("candidates not sorted: %+v", d.Candidates)
+		}
+	}
+	if d.FinalTool != d.Candidates[0].Tool || d.Source != agent.RoutingSourcePrefilterTop {
+		t.Errorf("final = %s (%s), want top candidate %s", d.FinalTool, d.Source, d.Candidates[0].Tool)
+	}
+	if d.Margin < 0 {
+		t.Errorf("Margin = %f, want >= 0 for the top candidate", d.Margin)
+	}
+
+	rules := make(map[string]bool)
+	for _, a := range d.Adjustments {
+		rules[a.Rule+":"+a.Tool] = true
+	}
+	if !rules["confusion_pair:find_callers"] {
+		t.Errorf("missing confusion pair adjustment: %+v", d.Adjustments)
+	}
+}
+
+func TestPreFilter_Decision_Forced(t *testing.T) {
+	pf := newTestPreFilter(makeTestConfig())
+	specs := makeTestSpecs(16)
+
+	result := pf.Filter(context.Background(), "functions with no callers", specs, nil)
+	d := result.Decision
+	if d.FinalTool != "find_dead_code" || d.Source != agent.RoutingSourcePrefilterForced {
+		t.Errorf("final = %s (%s), want forced find_dead_code", d.FinalTool, d.Source)
+	}
+	if len(d.RuleHits) != 1 || d.RuleHits[0] != "negation:find_dead_code" {
+		t.Errorf("RuleHits = %v", d.RuleHits)
+	}
+	if d.ForcedReason == "" || len(d.Candidates) != 0 {
+		t.Errorf("unexpected decision: %+v", d)
+	}
+}
+
 func TestPreFilter_ConfusionPair_CallersVsRefs(t *testing.T) {
 	pf := newTestPreFilter(makeTestConfig())
 	specs := makeTestSpecs(16)
@@ -880,7 +1004,7 @@ func TestApplyEncyclopedia_ForceMatch(t *testing.T) {
 	pf := newTestPreFilter(cfg)
 
 	// IT-12 Rev 4: "call chain from X to Y" → find_path (two-endpoint).
-	forcedTool, boosts, hints := pf.applyEncyclopedia("show the call chain from main to handler")
+	forcedTool, boosts, hints := pf.currentRules().applyEncyclopedia("show the call chain from main to handler")
 
 	if forcedTool != "find_path" {
 		t.Errorf("expected forced tool 'find_path', got %q", forcedTool)
@@ -898,7 +1022,7 @@ func TestApplyEncyclopedia_ForceMatch_SingleEndpoint(t *testing.T) {
 	pf := newTestPreFilter(cfg)
 
 	// Single-endpoint "call chain from X" → get_call_chain.
-	forcedTool, _, _ := pf.applyEncyclopedia("show the call chain from main")
+	forcedTool, _, _ := pf.currentRules().applyEncyclopedia("show the call chain from main")
 
 	if forcedTool != "get_call_chain" {
 		t.Errorf("expected forced tool 'get_call_chain', got %q", forcedTool)
@@ -909,7 +1033,7 @@ func TestApplyEncyclopedia_BoostMatch(t *testing.T) {
 	cfg := makeEncyclopediaConfig()
 	pf := newTestPreFilter(cfg)
 
-	forcedTool, boosts, _ := pf.applyEncyclopedia("what classes extend the light base class")
+	forcedTool, boosts, _ := pf.currentRules().applyEncyclopedia("what classes extend the light base class")
 
 	if forcedTool != "" {
 		t.Errorf("expected no forced tool for boost, got %q", forcedTool)
@@ -923,7 +1047,7 @@ func TestApplyEncyclopedia_HintMatch(t *testing.T) {
 	cfg := makeEncyclopediaConfig()
 	pf := newTestPreFilter(cfg)
 
-	forcedTool, _, hints := pf.applyEncyclopedia("find the shortest path between function a and function b")
+	forcedTool, _, hints := pf.currentRules().applyEncyclopedia("find the shortest path between function a and function b")
 
 	if forcedTool != "" {
 		t.Errorf("expected no forced tool for hint, got %q", forcedTool)
@@ -944,7 +1068,7 @@ func TestApplyEncyclopedia_AntiSignalSuppresses(t *testing.T) {
 	pf := newTestPreFilter(cfg)
 
 	// "mock implementation" anti-signal should suppress find_implementations boost
-	_, boosts, _ := pf.applyEncyclopedia("what classes extend the mock implementation of handler")
+	_, boosts, _ := pf.currentRules().applyEncyclopedia("what classes extend the mock implementation of handler")
 
 	if boosts["find_implementations"] != 0 {
 		t.Errorf("expected find_implementations boost suppressed by anti-signal, got %f", boosts["find_implementations"])
@@ -955,7 +1079,7 @@ func TestApplyEncyclopedia_NoMatch(t *testing.T) {
 	cfg := makeEncyclopediaConfig()
 	pf := newTestPreFilter(cfg)
 
-	forcedTool, boosts, hints := pf.applyEncyclopedia("what is the performance of the system")
+	forcedTool, boosts, hints := pf.currentRules().applyEncyclopedia("what is the performance of the system")
 
 	if forcedTool != "" {
 		t.Errorf("expected no forced tool, got %q", forcedTool)
@@ -975,7 +1099,7 @@ func TestApplyEncyclopedia_ForceWithAntiSignal(t *testing.T) {
 	// "unreferenced" matches find_dead_code force, but also matches
 	// find_references anti-signal. find_dead_code has no anti-signal for
 	// "unreferenced", so it should force find_dead_code.
-	forcedTool, _, _ := pf.applyEncyclopedia("find unreferenced functions in the codebase")
+	forcedTool, _, _ := pf.currentRules().applyEncyclopedia("find unreferenced functions in the codebase")
 
 	if forcedTool != "find_dead_code" {
 		t.Errorf("expected forced tool 'find_dead_code', got %q", forcedTool)
@@ -987,7 +1111,7 @@ func TestApplyEncyclopedia_ReferenceAntiSignalBlocksBoost(t *testing.T) {
 	pf := newTestPreFilter(cfg)
 
 	// "no references" should suppress find_references boost
-	_, boosts, _ := pf.applyEncyclopedia("functions with no references in the codebase")
+	_, boosts, _ := pf.currentRules().applyEncyclopedia("functions with no references in the codebase")
 
 	if boosts["find_references"] != 0 {
 		t.Errorf("expected find_references boost suppressed by 'no references' anti-signal, got %f", boosts["find_references"])
@@ -1034,7 +1158,7 @@ func TestApplyEncyclopedia_EmptyEncyclopedia(t *testing.T) {
 	// No encyclopedia entries
 	pf := newTestPreFilter(cfg)
 
-	forcedTool, boosts, hints := pf.applyEncyclopedia("what classes extend base")
+	forcedTool, boosts, hints := pf.currentRules().applyEncyclopedia("what classes extend base")
 
 	if forcedTool != "" || len(boosts) != 0 || len(hints) != 0 {
 		t.Error("expected empty results from empty encyclopedia")
@@ -1173,7 +1297,7 @@ func TestScoreHybrid_EmbeddingPrimaryMode(t *testing.T) {
 	pf := newTestPreFilter(cfg)
 	specs := makeTestSpecs(16)
 
-	scores := pf.scoreHybrid(context.Background(), "find callers of main", specs, nil)
+	scores := pf.scoreHybrid(context.Background(), pf.currentRules(), "find callers of main", specs, nil)
 
 	// In embedding_primary mode, if embeddings are unavailable, scores should be nil
 	// (passthrough to router).
@@ -1194,7 +1318,7 @@ func TestScoreHybrid_HybridBackwardCompat(t *testing.T) {
 		{Name: "answer", Description: "Answer", BestFor: []string{"answer"}},
 	}
 
-	scores := pf.scoreHybrid(context.Background(), "who calls parseconfig", specs, nil)
+	scores := pf.scoreHybrid(context.Background(), pf.currentRules(), "who calls parseconfig", specs, nil)
 
 	// In hybrid mode, BM25 should still produce scores even without embeddings
 	if len(scores) == 0 {
@@ -1211,13 +1335,13 @@ func TestScoreHybrid_SynchronousWarmup(t *testing.T) {
 	specs := makeTestSpecs(16)
 
 	// Call scoreHybrid — this triggers warmOnce.Do synchronously
-	pf.scoreHybrid(context.Background(), "test query", specs, nil)
+	pf.scoreHybrid(context.Background(), pf.currentRules(), "test query", specs, nil)
 
 	// warmOnce should have executed (we can't verify the internal state
 	// directly, but the fact that scoreHybrid returned means the sync
 	// warm-up completed or timed out — it didn't launch an async goroutine).
 	// Calling again should be a no-op.
-	pf.scoreHybrid(context.Background(), "test query 2", specs, nil)
+	pf.scoreHybrid(context.Background(), pf.currentRules(), "test query 2", specs, nil)
 }
 
 // =============================================================================
@@ -1229,7 +1353,7 @@ func TestSelectCandidates_NilScores_Passthrough(t *testing.T) {
 	pf := newTestPreFilter(cfg)
 	specs := makeTestSpecs(55)
 
-	result := pf.selectCandidates(nil, specs)
+	result := pf.currentRules().selectCandidates(nil, specs)
 
 	if len(result) != len(specs) {
 		t.Errorf("expected passthrough (all %d specs), got %d", len(specs), len(result))
@@ -1241,7 +1365,7 @@ func TestSelectCandidates_EmptyScores_Passthrough(t *testing.T) {
 	pf := newTestPreFilter(cfg)
 	specs := makeTestSpecs(10)
 
-	result := pf.selectCandidates(map[string]float64{}, specs)
+	result := pf.currentRules().selectCandidates(map[string]float64{}, specs)
 
 	if len(result) != len(specs) {
 		t.Errorf("expected passthrough (all %d specs), got %d", len(specs), len(result))
@@ -1267,7 +1391,7 @@ func TestSelectCandidates_ScoreFloor(t *testing.T) {
 		"tool_e": 0.10, // below floor
 	}
 
-	result := pf.selectCandidates(scores, specs)
+	result := pf.currentRules().selectCandidates(scores, specs)
 
 	resultNames := make(map[string]bool)
 	for _, s := range result {
@@ -1303,7 +1427,7 @@ func TestSelectCandidates_GapCutoff(t *testing.T) {
 		"tool_e": 0.40,
 	}
 
-	result := pf.selectCandidates(scores, specs)
+	result := pf.currentRules().selectCandidates(scores, specs)
 
 	resultNames := make(map[string]bool)
 	for _, s := range result {
@@ -1339,7 +1463,7 @@ func TestSelectCandidates_GapCutoff_NoGap(t *testing.T) {
 		"tool_e": 0.70,
 	}
 
-	result := pf.selectCandidates(scores, specs)
+	result := pf.currentRules().selectCandidates(scores, specs)
 
 	// No gap exceeds threshold → all above-floor tools included (up to MaxCandidates)
 	if len(result) != 5 {
@@ -1367,7 +1491,7 @@ func TestSelectCandidates_MinFloor(t *testing.T) {
 		"tool_e": 0.40,
 	}
 
-	result := pf.selectCandidates(scores, specs)
+	result := pf.currentRules().selectCandidates(scores, specs)
 
 	if len(result) < cfg.MinCandidates {
 		t.Errorf("expected at least %d candidates (MinCandidates), got %d", cfg.MinCandidates, len(result))
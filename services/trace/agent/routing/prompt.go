@@ -16,6 +16,8 @@ import (
 	"log/slog"
 	"strings"
 	"text/template"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -396,7 +398,7 @@ func NewPromptBuilder() (*PromptBuilder, error) {
 	tmpl, err := template.New("system").Funcs(funcMap).Parse(systemPromptTemplate)
 	if err != nil {
 		slog.Error("NewPromptBuilder: Template parsing failed",
-			"error", err,
+			telemetry.SensitiveLogError("error", err),
 			"error_type", fmt.Sprintf("%T", err))
 		return nil, err
 	}
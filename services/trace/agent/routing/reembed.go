@@ -0,0 +1,366 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package routing
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// =============================================================================
+// Background Re-embedding
+// =============================================================================
+//
+// The tool corpus can change while the service runs: a project brings its
+// own tools (.trace/tools.yaml), or a tool description is edited. The
+// pre-filter compares the corpus hash of the specs it is given with the one
+// the cache was embedded from and calls Refresh when they differ. Until the
+// re-embed completes, scoring returns nil and routing uses BM25 alone;
+// vectors are never scored against a corpus they were not built for.
+//
+// A re-embed reuses the vectors of unchanged tools, loads changed tools
+// from the store when another corpus already embedded them, and sends only
+// the rest to Ollama. When every tool is embedded the new corpus is
+// activated in the store, which deletes the entries only the previous
+// corpus used.
+
+// toolEmbeddingReembedTimeout bounds one background re-embed of the corpus.
+const toolEmbeddingReembedTimeout = 2 * time.Minute
+
+// Refresh re-embeds the cache in the background if specs differ from the
+// corpus it was last asked to embed.
+//
+// # Description
+//
+// Compares the corpus hash of specs with the requested corpus. If it
+// differs, the new corpus is queued for the background re-embed loop
+// (replacing any corpus queued before it), the invalidation is logged and
+// counted, and Score returns nil until the re-embed completes.
+//
+// # Inputs
+//
+//   - specs: The current tool specs. Empty is a no-op.
+//
+// # Outputs
+//
+//   - bool: True if a re-embed was queued.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (c *ToolEmbeddingCache) Refresh(specs []ToolSpec) bool {
+	if len(specs) == 0 {
+		return false
+	}
+	hash := computeCorpusHash(specs, c.model)
+
+	c.mu.Lock()
+	if hash == c.targetHash {
+		c.mu.Unlock()
+		return false
+	}
+	previous := c.corpusHash
+	c.target, c.targetHash = specs, hash
+	c.queueLocked(specs, false)
+	c.mu.Unlock()
+
+	if hash != previous {
+		embeddingCacheInvalidations.WithLabelValues("corpus_changed").Inc()
+		c.logger.Info("embedding cache: tool corpus changed, routing with BM25 until re-embedding completes",
+			slog.String("previous_hash", shortHash(previous)),
+			slog.String("corpus_hash", shortHash(hash)),
+			slog.Int("tool_count", len(specs)),
+		)
+	}
+	return true
+}
+
+// Reembed re-embeds every tool of the current corpus in the background,
+// ignoring cached vectors.
+//
+// # Description
+//
+// Used after the model behind the embedding endpoint was replaced under
+// the same name. The current vectors keep scoring queries until the new
+// ones are swapped in; a tool that fails to embed keeps its old vector.
+//
+// # Outputs
+//
+//   - string: The corpus hash being re-embedded.
+//   - int: Number of tools in the corpus.
+//   - bool: False if no corpus has been warmed or refreshed yet.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (c *ToolEmbeddingCache) Reembed() (string, int, bool) {
+	c.mu.Lock()
+	if c.targetHash == "" {
+		c.mu.Unlock()
+		return "", 0, false
+	}
+	hash, specs := c.targetHash, c.target
+	c.queueLocked(specs, true)
+	c.mu.Unlock()
+
+	embeddingCacheInvalidations.WithLabelValues("forced").Inc()
+	c.logger.Info("embedding cache: forced re-embedding of tool corpus",
+		slog.String("corpus_hash", shortHash(hash)),
+		slog.Int("tool_count", len(specs)),
+	)
+	return hash, len(specs), true
+}
+
+// Reembedding reports whether the corpus changed and its re-embed has not
+// completed yet. Score returns nil meanwhile.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (c *ToolEmbeddingCache) Reembedding() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.corpusHash != c.targetHash
+}
+
+// queueLocked queues specs for the re-embed loop and starts the loop if it
+// is not running. The caller holds c.mu.
+func (c *ToolEmbeddingCache) queueLocked(specs []ToolSpec, force bool) {
+	c.pending = specs
+	c.pendingForce = c.pendingForce || force
+	if !c.refreshing {
+		c.refreshing = true
+		go c.refreshLoop()
+	}
+}
+
+// refreshLoop re-embeds queued corpora until the queue is empty.
+func (c *ToolEmbeddingCache) refreshLoop() {
+	for {
+		c.mu.Lock()
+		specs, force := c.pending, c.pendingForce
+		c.pending, c.pendingForce = nil, false
+		if specs == nil {
+			c.refreshing = false
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+
+		ctx, cancel := context.WithTimeout(context.Background(), toolEmbeddingReembedTimeout)
+		c.reembed(ctx, specs, force)
+		cancel()
+	}
+}
+
+// reembed embeds specs, reusing what it can, and swaps the result in.
+//
+// Without force, unchanged tools keep their vectors and changed tools are
+// loaded from the store before calling Ollama. With force, every tool is
+// embedded again; one that fails keeps its previous vector if its document
+// and dimension are unchanged. While the hash fallback is active, tools are
+// embedded locally and nothing is persisted.
+func (c *ToolEmbeddingCache) reembed(ctx context.Context, specs []ToolSpec, force bool) {
+	c.warmMu.Lock()
+	defer c.warmMu.Unlock()
+	start := time.Now()
+
+	corpusHash := computeCorpusHash(specs, c.model)
+	docHashes := toolDocHashes(specs)
+
+	vectors := make(map[string][]float32, len(specs))
+	previous := make(map[string][]float32)
+	var pending []ToolSpec
+	c.mu.RLock()
+	for _, spec := range specs {
+		vec, ok := c.vectors[spec.Name]
+		if ok && c.docHashes[spec.Name] == docHashes[spec.Name] {
+			if !force {
+				vectors[spec.Name] = vec
+				continue
+			}
+			previous[spec.Name] = vec
+		}
+		pending = append(pending, spec)
+	}
+	dim, hashed := c.dim, c.hashed
+	c.mu.RUnlock()
+	reused := len(vectors)
+	if force {
+		dim = 0
+	}
+
+	toolStore, _ := c.store.(ToolEmbeddingStore)
+	loaded := 0
+	if !force && !hashed && toolStore != nil && len(pending) > 0 {
+		want := make(map[string]string, len(pending))
+		for _, spec := range pending {
+			want[spec.Name] = docHashes[spec.Name]
+		}
+		cached, err := toolStore.LoadToolEmbeddings(ctx, want)
+		if err != nil {
+			c.logger.Warn("embedding cache: tool store load failed during re-embedding",
+				telemetry.SensitiveLogError("error", err),
+			)
+		}
+		remaining := pending[:0]
+		for _, spec := range pending {
+			vec, ok := cached[spec.Name]
+			if ok && (dim == 0 || len(vec) == dim) {
+				vectors[spec.Name] = vec
+				dim = len(vec)
+				loaded++
+				continue
+			}
+			remaining = append(remaining, spec)
+		}
+		pending = remaining
+	}
+
+	computed := make(map[string][]float32, len(pending))
+	if len(pending) > 0 {
+		names := make([]string, len(pending))
+		docs := make([]string, len(pending))
+		for i, spec := range pending {
+			names[i] = spec.Name
+			docs[i] = buildEmbeddingDoc(spec)
+		}
+		var embedded [][]float32
+		if hashed {
+			embedded = make([][]float32, len(docs))
+			for i, doc := range docs {
+				embedded[i] = hashEmbed(doc)
+			}
+		} else {
+			embedded = c.embedDocs(ctx, names, docs)
+		}
+		for i, vec := range embedded {
+			if vec == nil {
+				continue
+			}
+			if dim == 0 {
+				dim = len(vec)
+			}
+			if len(vec) != dim {
+				c.logger.Warn("embedding cache: tool vector dimension mismatch, skipping",
+					slog.String("tool", names[i]),
+					slog.Int("dim", len(vec)),
+					slog.Int("want_dim", dim),
+				)
+				continue
+			}
+			if normalized := unitNormalize(vec); normalized != nil {
+				vectors[names[i]] = normalized
+				computed[names[i]] = normalized
+			}
+		}
+	}
+	for name, vec := range previous {
+		if _, ok := vectors[name]; !ok && len(vec) == dim {
+			vectors[name] = vec
+		}
+	}
+	complete := len(vectors) == len(specs)
+
+	c.mu.Lock()
+	c.vectors = vectors
+	if len(vectors) > 0 {
+		c.dim = dim
+	}
+	c.corpusHash, c.docHashes = corpusHash, docHashes
+	c.warmed = len(vectors) > 0
+	c.mu.Unlock()
+
+	if !hashed && c.store != nil {
+		toSave := computed
+		if toolStore == nil && complete {
+			toSave = vectors
+		}
+		if len(toSave) > 0 {
+			c.persist(ctx, corpusHash, toolStore, docHashes, toSave)
+		}
+		if complete {
+			c.activate(ctx, corpusHash, docHashes)
+		}
+	}
+
+	result := "complete"
+	if !complete {
+		result = "partial"
+	}
+	embeddingReembedDuration.WithLabelValues(result).Observe(time.Since(start).Seconds())
+
+	logAttrs := []any{
+		slog.String("corpus_hash", shortHash(corpusHash)),
+		slog.Int("tool_count", len(specs)),
+		slog.Int("reused", reused),
+		slog.Int("loaded", loaded),
+		slog.Int("embedded", len(computed)),
+		slog.Bool("forced", force),
+		slog.Duration("duration", time.Since(start)),
+	}
+	if complete {
+		c.logger.Info("embedding cache: re-embedding complete", logAttrs...)
+	} else {
+		c.logger.Warn("embedding cache: re-embedding incomplete, missing tools score 0", logAttrs...)
+	}
+}
+
+// checkActiveCorpus logs and counts a warm-up whose corpus differs from the
+// one the store last activated.
+func (c *ToolEmbeddingCache) checkActiveCorpus(ctx context.Context, corpusHash string) {
+	store, ok := c.store.(ActiveCorpusStore)
+	if !ok {
+		return
+	}
+	active, err := store.ActiveCorpus(ctx)
+	if err != nil {
+		c.logger.Warn("embedding cache: reading active corpus failed",
+			telemetry.SensitiveLogError("error", err),
+		)
+		return
+	}
+	if active.CorpusHash == "" || active.CorpusHash == corpusHash {
+		return
+	}
+	embeddingCacheInvalidations.WithLabelValues("corpus_changed").Inc()
+	c.logger.Info("embedding cache: tool corpus changed since the cache was written, embedding changed tools",
+		slog.String("previous_hash", shortHash(active.CorpusHash)),
+		slog.String("corpus_hash", shortHash(corpusHash)),
+	)
+}
+
+// activate records corpusHash as the store's active corpus, deleting
+// entries only the previous corpus used. Failures are logged.
+func (c *ToolEmbeddingCache) activate(ctx context.Context, corpusHash string, docHashes map[string]string) {
+	store, ok := c.store.(ActiveCorpusStore)
+	if !ok {
+		return
+	}
+	if _, err := store.ActivateCorpus(ctx, ActiveCorpus{CorpusHash: corpusHash, DocHashes: docHashes}); err != nil {
+		c.logger.Warn("embedding cache: failed to activate corpus",
+			telemetry.SensitiveLogError("error", err),
+			slog.String("corpus_hash", shortHash(corpusHash)),
+		)
+	}
+}
+
+// toolDocHashes returns tool name → embedding document hash for specs.
+func toolDocHashes(specs []ToolSpec) map[string]string {
+	docHashes := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		docHashes[spec.Name] = computeToolDocHash(spec)
+	}
+	return docHashes
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package routing
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// waitRefreshed waits until the cache's background re-embed loop is idle.
+func waitRefreshed(t *testing.T, c *ToolEmbeddingCache) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		c.mu.RLock()
+		idle := !c.refreshing
+		c.mu.RUnlock()
+		if idle {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("background re-embed did not finish")
+}
+
+func TestToolEmbeddingCache_Refresh_ReembedsChangedTools(t *testing.T) {
+	ctx := context.Background()
+	store := NewBadgerRouterCacheStore(openTestDB(t), 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
+	server := newBatchOllamaServer(t, 8, nil)
+	cache := newBatchTestCache(t, server, store)
+
+	specs := makeManySpecs(5)
+	if err := cache.Warm(ctx, specs); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+	if got := server.inputs.Load(); got != 5 {
+		t.Fatalf("warm-up embedded %d documents, want 5", got)
+	}
+	if cache.Refresh(specs) {
+		t.Error("Refresh with the warmed corpus queued a re-embed")
+	}
+
+	// One description changes and a project tool is added.
+	changed := append(makeManySpecs(5), ToolSpec{Name: "project_routes", UseWhen: "Lists HTTP handlers."})
+	changed[2].UseWhen = "Use for something else"
+
+	cache.warmMu.Lock() // hold the re-embed until the degraded state is checked
+	if !cache.Refresh(changed) {
+		cache.warmMu.Unlock()
+		t.Fatal("Refresh with a changed corpus queued nothing")
+	}
+	if !cache.Reembedding() {
+		t.Error("Reembedding() = false while the re-embed is pending")
+	}
+	if scores, _ := cache.Score(ctx, "task 1"); scores != nil {
+		t.Error("Score returned embedding scores for an outdated corpus")
+	}
+	cache.warmMu.Unlock()
+	waitRefreshed(t, cache)
+
+	if cache.Reembedding() {
+		t.Error("Reembedding() = true after the re-embed finished")
+	}
+	if got := server.inputs.Load(); got != 7 {
+		t.Errorf("re-embed sent %d documents, want 2 (changed and added)", got-5)
+	}
+	if n := len(cache.vectors); n != 6 {
+		t.Errorf("cache has %d tools, want 6", n)
+	}
+	if scores, _ := cache.Score(ctx, "lists http handlers"); scores["project_routes"] == 0 {
+		t.Errorf("no score for the added tool: %v", scores)
+	}
+
+	// The new corpus is active and the changed tool's old entry is gone.
+	active, err := store.ActiveCorpus(ctx)
+	if err != nil || active.CorpusHash != computeCorpusHash(changed, "test-model") {
+		t.Errorf("ActiveCorpus = %+v, %v; want the changed corpus", active, err)
+	}
+	entries, err := store.ListEmbeddings(ctx)
+	if err != nil || len(entries) != 6 {
+		t.Fatalf("ListEmbeddings = %d entries, %v; want 6", len(entries), err)
+	}
+	for _, e := range entries {
+		if !e.Active {
+			t.Errorf("entry %s (%s) is not active", e.Key, e.Tool)
+		}
+	}
+
+	// Switching back embeds the original description again: its entry was
+	// deleted when the changed corpus was activated.
+	before := server.inputs.Load()
+	if !cache.Refresh(specs) {
+		t.Fatal("Refresh back to the original corpus queued nothing")
+	}
+	waitRefreshed(t, cache)
+	if got := server.inputs.Load() - before; got != 1 {
+		t.Errorf("re-embed back sent %d documents, want 1", got)
+	}
+}
+
+func TestToolEmbeddingCache_Reembed_Forced(t *testing.T) {
+	ctx := context.Background()
+	server := newBatchOllamaServer(t, 8, nil)
+	cache := newBatchTestCache(t, server, nil)
+
+	if _, _, ok := cache.Reembed(); ok {
+		t.Error("Reembed before any warm-up reported a corpus")
+	}
+
+	specs := makeManySpecs(4)
+	if err := cache.Warm(ctx, specs); err != nil {
+		t.Fatalf("Warm() error = %v", err)
+	}
+
+	cache.warmMu.Lock()
+	hash, n, ok := cache.Reembed()
+	if !ok || n != 4 || hash != computeCorpusHash(specs, "test-model") {
+		cache.warmMu.Unlock()
+		t.Fatalf("Reembed() = %q, %d, %v", hash, n, ok)
+	}
+	// The corpus is unchanged, so current vectors keep scoring.
+	if cache.Reembedding() {
+		t.Error("forced re-embed of the same corpus disabled embedding scores")
+	}
+	if scores, _ := cache.Score(ctx, "task 1"); scores == nil {
+		t.Error("Score returned nil during a forced re-embed")
+	}
+	cache.warmMu.Unlock()
+	waitRefreshed(t, cache)
+
+	// Warm-up (4) + query (1) + forced re-embed (4).
+	if got := server.inputs.Load(); got != 9 {
+		t.Errorf("server embedded %d documents, want 9", got)
+	}
+	if n := len(cache.vectors); n != 4 {
+		t.Errorf("cache has %d tools after forced re-embed, want 4", n)
+	}
+}
+
+func TestPreFilter_CorpusChange_ServesBM25UntilReembedded(t *testing.T) {
+	ctx := context.Background()
+	server := newBatchOllamaServer(t, 8, nil)
+	pf := newTestPreFilter(makeEmbeddingPrimaryConfig())
+	pf.embedder.url = server.URL + "/api/embed"
+	pf.embedder.model = "test-model"
+	pf.embedder.retryBase = time.Millisecond
+
+	specs := makeManySpecs(5)
+	if out := pf.scoreHybridDetailed(ctx, pf.currentRules(), "task 1", specs, nil); out.embedding == nil {
+		t.Fatal("expected embedding scores after warm-up")
+	}
+
+	changed := append(makeManySpecs(5), ToolSpec{Name: "deploy_service", BestFor: []string{"deploy"}, UseWhen: "Deploys a service."})
+	pf.embedder.warmMu.Lock()
+	out := pf.scoreHybridDetailed(ctx, pf.currentRules(), "deploy the service", changed, nil)
+	pf.embedder.warmMu.Unlock()
+	if out.embedding != nil {
+		t.Error("embedding scores used while the changed corpus is re-embedded")
+	}
+	if out.scores["deploy_service"] == 0 {
+		t.Errorf("BM25 was not rebuilt for the new tool: %v", out.scores)
+	}
+
+	waitRefreshed(t, pf.embedder)
+	out = pf.scoreHybridDetailed(ctx, pf.currentRules(), "deploy the service", changed, nil)
+	if out.embedding["deploy_service"] == 0 {
+		t.Errorf("no embedding score for the new tool after re-embedding: %v", out.embedding)
+	}
+
+	status, ok := pf.Reembed()
+	if !ok || status.ToolCount != 6 || status.CorpusHash != computeCorpusHash(changed, "test-model") {
+		t.Errorf("Reembed() = %+v, %v", status, ok)
+	}
+	waitRefreshed(t, pf.embedder)
+}
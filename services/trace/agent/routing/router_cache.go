@@ -26,10 +26,14 @@ package routing
 //	   HNSW indexing. BadgerDB is embedded — no network call, no availability
 //	   dependency, ~100µs access latency.
 //
-//	2. Corpus hash as cache key: SHA256(sorted tool specs + model name). Any
-//	   change to tool names, keywords, or UseWhen text produces a different hash,
-//	   automatically invalidating the cached vectors. No explicit invalidation
-//	   API is needed — just delete the ROUTING_CACHE_DIR directory.
+//	2. Model name + corpus hash as cache key: SHA256(sorted tool specs + model
+//	   name). Any change to tool names, keywords, or UseWhen text produces a
+//	   different hash, automatically invalidating the cached vectors. The
+//	   model name is also part of the key and the value, together with the
+//	   vector dimension, so entries written by another model are detected on
+//	   load and discarded instead of producing garbage similarity scores.
+//	   MigrateModel deletes entries of other models (and legacy v1 entries)
+//	   when ROUTING_EMBED_MODEL changes.
 //
 //	3. BadgerDB native TTL: 7-day expiry is enforced by BadgerDB's GC, not by
 //	   application code. No metadata record is needed; expired keys return
@@ -38,11 +42,38 @@ package routing
 //	4. BM25 index is NOT persisted: it rebuilds from specs in <1ms and has no
 //	   network dependency. Persisting it would add complexity with no benefit.
 //
+//	5. Per-tool entries (v3): each tool vector is its own key, addressed by
+//	   the hash of the tool's embedding document. A warm-up that embeds only
+//	   some tools persists those, and the next start embeds only the rest; a
+//	   change to one tool's spec re-embeds only that tool.
+//
 // Storage layout:
 //
-//	routing/emb/v1/{corpusHash}  →  gob-encoded map[string][]float32
-//	                                 (tool name → unit-normalized vector)
-//	                                 TTL: 7 days
+//	routing/emb/v3/{model}/{docHash}     →  gob-encoded toolVectorEntry
+//	                                         (model, tool, dimension,
+//	                                         unit-normalized vector)
+//	                                         TTL: 7 days
+//	routing/emb/v2/{model}/{corpusHash}  →  gob-encoded embeddingCacheEntry
+//	                                         (model, dimension, tool name →
+//	                                         unit-normalized vector)
+//	                                         TTL: 7 days
+//	routing/emb/model                    →  name of the model that last
+//	                                         opened the store (no TTL)
+//	routing/emb/active/{model}           →  gob-encoded ActiveCorpus: the
+//	                                         corpus hash and per-tool
+//	                                         document hashes the running
+//	                                         corpus uses (no TTL)
+//
+// When the corpus changes, the embedding cache re-embeds only the changed
+// tools and then activates the new corpus; entries used only by the
+// previously active corpus are deleted (see ActivateCorpus). Entries of the
+// store's model that the active corpus does not use are reported as
+// orphaned by ListEmbeddings.
+//
+// v2 entries hold the whole corpus in one value. They are still read (and
+// copied into v3 entries) when no v3 entry exists, but no longer written by
+// warm-up. Entries under the legacy routing/emb/v1/ prefix carry no model
+// identity; they are never read and are removed by MigrateModel.
 
 import (
 	"bytes"
@@ -53,6 +84,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"os"
 	"sort"
 	"strings"
 	"time"
@@ -60,6 +92,7 @@ import (
 	dgbadger "github.com/dgraph-io/badger/v4"
 
 	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // routerCacheDefaultTTL is the default lifetime of a cached embedding entry.
@@ -67,9 +100,155 @@ import (
 // accumulating stale data indefinitely.
 const routerCacheDefaultTTL = 7 * 24 * time.Hour
 
-// routerCacheKeyPrefix is prepended to the corpus hash to form the BadgerDB key.
-// Versioned (v1) to allow future format changes without collision.
-const routerCacheKeyPrefix = "routing/emb/v1/"
+// routerCacheKeyPrefix is prepended to the model name and corpus hash to form
+// the BadgerDB key. Versioned (v2) to allow future format changes without
+// collision.
+const routerCacheKeyPrefix = "routing/emb/v2/"
+
+// routerCacheToolPrefix is prepended to the model name and tool document
+// hash to form the key of a per-tool (v3) entry.
+const routerCacheToolPrefix = "routing/emb/v3/"
+
+// routerCacheLegacyPrefix is the v1 prefix (corpus hash only, no model
+// identity). Entries under it are ignored and removed by MigrateModel.
+const routerCacheLegacyPrefix = "routing/emb/v1/"
+
+// routerCacheDeleteBatch bounds keys deleted per transaction.
+const routerCacheDeleteBatch = 1000
+
+// routerCacheModelKey records the embedding model that last opened the store.
+const routerCacheModelKey = "routing/emb/model"
+
+// routerCacheActivePrefix is prepended to the model name to form the key
+// recording the model's active corpus.
+const routerCacheActivePrefix = "routing/emb/active/"
+
+// DefaultRoutingEmbedModel is the embedding model used when neither
+// ROUTING_EMBED_MODEL nor EMBEDDING_MODEL is set.
+const DefaultRoutingEmbedModel = "nomic-embed-text-v2-moe"
+
+// ResolveRoutingEmbedModel returns the embedding model used for tool routing.
+//
+// # Description
+//
+// ROUTING_EMBED_MODEL takes precedence so routing can use a different model
+// than the rest of the service; EMBEDDING_MODEL is the fallback, then
+// DefaultRoutingEmbedModel.
+//
+// # Outputs
+//
+//   - string: The model name. Never empty.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func ResolveRoutingEmbedModel() string {
+	if model := os.Getenv("ROUTING_EMBED_MODEL"); model != "" {
+		return model
+	}
+	if model := os.Getenv("EMBEDDING_MODEL"); model != "" {
+		return model
+	}
+	return DefaultRoutingEmbedModel
+}
+
+// EmbeddingModelIdentity identifies the embedding space of cached vectors.
+type EmbeddingModelIdentity struct {
+	// Name is the embedding model name (e.g. "nomic-embed-text-v2-moe").
+	Name string
+
+	// Dim is the expected vector dimension. 0 means unknown: entries are
+	// then only checked for internal consistency.
+	Dim int
+}
+
+// String returns "name/dim", or just the name when the dimension is unknown.
+func (m EmbeddingModelIdentity) String() string {
+	if m.Dim > 0 {
+		return fmt.Sprintf("%s/%d", m.Name, m.Dim)
+	}
+	return m.Name
+}
+
+// embeddingCacheEntry is the persisted value of one embedding cache key.
+type embeddingCacheEntry struct {
+	Model   string
+	Dim     int
+	Vectors map[string][]float32
+}
+
+// activeCorpusEntry is the persisted value of the active corpus key.
+type activeCorpusEntry struct {
+	Model      string
+	CorpusHash string
+	DocHashes  map[string]string
+}
+
+// toolVectorEntry is the persisted value of one per-tool (v3) key.
+type toolVectorEntry struct {
+	Model  string
+	Tool   string
+	Dim    int
+	Vector []float32
+}
+
+// EmbeddingCacheEntry describes one persisted entry for inspection.
+type EmbeddingCacheEntry struct {
+	// Key is the raw BadgerDB key.
+	Key string `json:"key"`
+
+	// Model is the embedding model that wrote the entry. Empty for legacy entries.
+	Model string `json:"model"`
+
+	// Dim is the vector dimension.
+	Dim int `json:"dim"`
+
+	// CorpusHash is the corpus hash part of the key. For per-tool entries
+	// it is the hash of the tool's embedding document.
+	CorpusHash string `json:"corpus_hash"`
+
+	// Tool is the tool name of a per-tool entry. Empty for corpus entries.
+	Tool string `json:"tool,omitempty"`
+
+	// ToolCount is the number of tool vectors in the entry.
+	ToolCount int `json:"tool_count"`
+
+	// ExpiresAt is the TTL expiry, zero if the entry does not expire.
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Legacy is true for v1 entries without model identity.
+	Legacy bool `json:"legacy"`
+
+	// Stale is true when the entry does not match the store's model.
+	Stale bool `json:"stale"`
+
+	// Active is true when the active corpus uses the entry.
+	Active bool `json:"active"`
+
+	// Orphaned is true for a usable entry that the active corpus does not
+	// use. Always false while no corpus has been activated.
+	Orphaned bool `json:"orphaned"`
+}
+
+// ActiveCorpus identifies the persisted entries a tool corpus uses.
+type ActiveCorpus struct {
+	// CorpusHash is the hash of the whole corpus (see computeCorpusHash).
+	// Empty when no corpus has been activated.
+	CorpusHash string
+
+	// DocHashes maps each tool name to the hash of its embedding document.
+	DocHashes map[string]string
+}
+
+// uses reports whether the corpus uses the per-tool entry with docHash.
+func (a ActiveCorpus) uses(docHash string) bool {
+	for _, h := range a.DocHashes {
+		if h == docHash {
+			return true
+		}
+	}
+	return false
+}
 
 // errCacheMiss is a sentinel used internally to distinguish "key not found"
 // (a normal cache miss) from a genuine storage error in LoadEmbeddings.
@@ -83,7 +262,7 @@ var errCacheMiss = errors.New("cache miss")
 //
 // # Description
 //
-// The store is keyed by corpus hash — a SHA256 digest of all tool names,
+// The store is keyed by model name and corpus hash — a SHA256 digest of all tool names,
 // keywords, and use_when text plus the embedding model name. Any change to
 // the tool registry or model automatically produces a different hash, so the
 // previous entry becomes unreachable (expires via TTL) without explicit
@@ -115,6 +294,55 @@ type RouterCacheStore interface {
 	SaveEmbeddings(ctx context.Context, corpusHash string, vectors map[string][]float32) error
 }
 
+// ToolEmbeddingStore persists tool embedding vectors one tool at a time.
+//
+// # Description
+//
+// Implemented by BadgerRouterCacheStore. Kept separate from
+// RouterCacheStore so existing store implementations need not change; the
+// ToolEmbeddingCache resumes partial warm-ups only when its store also
+// implements this.
+//
+// Tools are addressed by the hash of their embedding document (see
+// computeToolDocHash), so an entry is reused for as long as the tool's
+// spec and the model are unchanged.
+//
+// # Thread Safety
+//
+// Implementations must be safe for concurrent use.
+type ToolEmbeddingStore interface {
+	// LoadToolEmbeddings returns the cached vectors of the given tools
+	// (tool name → document hash). Tools without a usable entry are absent
+	// from the result; a miss for every tool returns an empty map.
+	LoadToolEmbeddings(ctx context.Context, docHashes map[string]string) (map[string][]float32, error)
+
+	// SaveToolEmbeddings persists one entry per vector. Every vector must
+	// have a document hash in docHashes.
+	SaveToolEmbeddings(ctx context.Context, docHashes map[string]string, vectors map[string][]float32) error
+}
+
+// ActiveCorpusStore records which tool corpus the running service uses.
+//
+// # Description
+//
+// Implemented by BadgerRouterCacheStore. The ToolEmbeddingCache activates a
+// corpus once every tool of it is embedded; activation deletes the entries
+// of the previously active corpus that the new one does not use, so a
+// changed tool description leaves no orphaned vectors behind.
+//
+// # Thread Safety
+//
+// Implementations must be safe for concurrent use.
+type ActiveCorpusStore interface {
+	// ActiveCorpus returns the active corpus. CorpusHash is empty if none
+	// has been activated.
+	ActiveCorpus(ctx context.Context) (ActiveCorpus, error)
+
+	// ActivateCorpus records corpus as active and deletes the entries only
+	// the previously active corpus used. Returns the number deleted.
+	ActivateCorpus(ctx context.Context, corpus ActiveCorpus) (int, error)
+}
+
 // =============================================================================
 // BadgerRouterCacheStore
 // =============================================================================
@@ -125,10 +353,13 @@ type RouterCacheStore interface {
 //
 // # Description
 //
-// Vectors are gob-encoded as map[string][]float32. Encoding is compact
-// (~4 bytes/float32; 30 tools × 768 dims ≈ 90KB) and fast (~5µs
-// encode/decode). The key is the corpus hash prefixed with the storage
-// layout version string.
+// Vectors are gob-encoded together with the model name and dimension.
+// Encoding is compact (~4 bytes/float32; 30 tools × 768 dims ≈ 90KB) and
+// fast (~5µs encode/decode). The key is the model name and corpus hash
+// prefixed with the storage layout version string.
+//
+// Entries whose model or dimension does not match the store's
+// EmbeddingModelIdentity are discarded on load with a warning.
 //
 // TTL is enforced by BadgerDB's native GC — no application-level expiry
 // check is needed. Expired keys return ErrKeyNotFound, which this store
@@ -140,6 +371,7 @@ type RouterCacheStore interface {
 type BadgerRouterCacheStore struct {
 	db     *badgerstore.DB
 	ttl    time.Duration
+	model  EmbeddingModelIdentity
 	logger *slog.Logger
 }
 
@@ -156,6 +388,8 @@ type BadgerRouterCacheStore struct {
 //
 //   - db: Opened BadgerDB wrapper. Must not be nil.
 //   - ttl: Lifetime for each cached entry. Pass 0 to use the default (7 days).
+//   - model: Identity of the embedding model. An empty Name uses
+//     ResolveRoutingEmbedModel().
 //   - logger: Logger for cache hit/miss diagnostics. May be nil.
 //
 // # Outputs
@@ -165,26 +399,40 @@ type BadgerRouterCacheStore struct {
 // # Thread Safety
 //
 // The returned store is safe for concurrent use.
-func NewBadgerRouterCacheStore(db *badgerstore.DB, ttl time.Duration, logger *slog.Logger) *BadgerRouterCacheStore {
+func NewBadgerRouterCacheStore(db *badgerstore.DB, ttl time.Duration, model EmbeddingModelIdentity, logger *slog.Logger) *BadgerRouterCacheStore {
 	if db == nil {
 		panic("NewBadgerRouterCacheStore: db must not be nil")
 	}
 	if ttl <= 0 {
 		ttl = routerCacheDefaultTTL
 	}
+	if model.Name == "" {
+		model.Name = ResolveRoutingEmbedModel()
+	}
 	if logger == nil {
 		logger = slog.Default()
 	}
-	return &BadgerRouterCacheStore{db: db, ttl: ttl, logger: logger}
+	return &BadgerRouterCacheStore{db: db, ttl: ttl, model: model, logger: logger}
+}
+
+// Model returns the store's embedding model identity.
+//
+// # Thread Safety
+//
+// Safe for concurrent use (immutable after construction).
+func (s *BadgerRouterCacheStore) Model() EmbeddingModelIdentity {
+	return s.model
 }
 
 // LoadEmbeddings retrieves cached unit-normalized tool embedding vectors.
 //
 // # Description
 //
-// Looks up the key routing/emb/v1/{corpusHash}. Returns (nil, nil) on miss
-// (key not found or TTL expired). Returns (nil, error) on storage or decode
-// failure. Returns (vectors, nil) on success.
+// Looks up the key routing/emb/v2/{model}/{corpusHash}. Returns (nil, nil)
+// on miss (key not found or TTL expired). An entry written by a different
+// model, or whose vectors do not all have the expected dimension, is deleted
+// with a warning and also reported as a miss. Returns (nil, error) on
+// storage or decode failure. Returns (vectors, nil) on success.
 //
 // # Inputs
 //
@@ -200,7 +448,7 @@ func NewBadgerRouterCacheStore(db *badgerstore.DB, ttl time.Duration, logger *sl
 //
 // Safe for concurrent use.
 func (s *BadgerRouterCacheStore) LoadEmbeddings(ctx context.Context, corpusHash string) (map[string][]float32, error) {
-	key := routerCacheKey(corpusHash)
+	key := s.routerCacheKey(corpusHash)
 
 	var raw []byte
 	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
@@ -226,25 +474,43 @@ func (s *BadgerRouterCacheStore) LoadEmbeddings(ctx context.Context, corpusHash
 		return nil, fmt.Errorf("router cache load: %w", err)
 	}
 
-	vectors, err := gobDecode(raw)
+	entry, err := decodeCacheEntry(raw)
 	if err != nil {
 		return nil, fmt.Errorf("router cache decode: %w", err)
 	}
 
+	if reason := s.checkEntry(entry); reason != "" {
+		s.logger.Warn("router cache: discarding mismatched entry",
+			slog.String("hash", shortHash(corpusHash)),
+			slog.String("reason", reason),
+			slog.String("entry_model", entry.Model),
+			slog.Int("entry_dim", entry.Dim),
+			slog.String("store_model", s.model.String()),
+		)
+		if _, err := s.deleteKeys(ctx, [][]byte{key}); err != nil {
+			s.logger.Warn("router cache: failed to delete mismatched entry",
+				telemetry.SensitiveLogError("error", err),
+			)
+		}
+		return nil, nil
+	}
+
 	s.logger.Debug("router cache: hit",
 		slog.String("hash", shortHash(corpusHash)),
-		slog.Int("tool_count", len(vectors)),
+		slog.Int("tool_count", len(entry.Vectors)),
+		slog.Int("dim", entry.Dim),
 	)
-	return vectors, nil
+	return entry.Vectors, nil
 }
 
 // SaveEmbeddings persists unit-normalized tool embedding vectors with a 7-day TTL.
 //
 // # Description
 //
-// Encodes vectors as gob-encoded map[string][]float32 and writes to BadgerDB
-// under the key routing/emb/v1/{corpusHash} with the configured TTL. After
-// TTL expires, the key is invisible to LoadEmbeddings (returns cache miss).
+// Encodes vectors with the store's model name and their dimension and writes
+// to BadgerDB under the key routing/emb/v2/{model}/{corpusHash} with the
+// configured TTL. After TTL expires, the key is invisible to LoadEmbeddings
+// (returns cache miss).
 //
 // # Inputs
 //
@@ -254,7 +520,8 @@ func (s *BadgerRouterCacheStore) LoadEmbeddings(ctx context.Context, corpusHash
 //
 // # Outputs
 //
-//   - error: Non-nil on encode or storage failure.
+//   - error: Non-nil on inconsistent or unexpected vector dimensions, or on
+//     encode or storage failure.
 //
 // # Thread Safety
 //
@@ -264,12 +531,25 @@ func (s *BadgerRouterCacheStore) SaveEmbeddings(ctx context.Context, corpusHash
 		return nil
 	}
 
-	raw, err := gobEncode(vectors)
+	dim := -1
+	for name, vec := range vectors {
+		if dim < 0 {
+			dim = len(vec)
+		}
+		if len(vec) != dim || dim == 0 {
+			return fmt.Errorf("router cache save: tool %q has dimension %d, want %d", name, len(vec), dim)
+		}
+	}
+	if s.model.Dim > 0 && dim != s.model.Dim {
+		return fmt.Errorf("router cache save: dimension %d does not match model %s", dim, s.model)
+	}
+
+	raw, err := encodeCacheEntry(embeddingCacheEntry{Model: s.model.Name, Dim: dim, Vectors: vectors})
 	if err != nil {
 		return fmt.Errorf("router cache encode: %w", err)
 	}
 
-	key := routerCacheKey(corpusHash)
+	key := s.routerCacheKey(corpusHash)
 	err = s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
 		entry := dgbadger.NewEntry(key, raw).WithTTL(s.ttl)
 		return txn.SetEntry(entry)
@@ -281,11 +561,550 @@ func (s *BadgerRouterCacheStore) SaveEmbeddings(ctx context.Context, corpusHash
 	s.logger.Debug("router cache: saved",
 		slog.String("hash", shortHash(corpusHash)),
 		slog.Int("tool_count", len(vectors)),
+		slog.Int("dim", dim),
+		slog.Duration("ttl", s.ttl),
+	)
+	return nil
+}
+
+// LoadToolEmbeddings retrieves cached per-tool vectors.
+//
+// # Description
+//
+// Looks up routing/emb/v3/{model}/{docHash} for every tool in one read
+// transaction. Entries written by a different model, for a different
+// tool, or with an unexpected dimension are deleted with a warning and
+// treated as misses, as are vectors whose dimension differs from the
+// first usable one.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - docHashes: Tool name → hash of its embedding document.
+//
+// # Outputs
+//
+//   - map[string][]float32: Tool name → unit-normalized vector, for the tools
+//     found. Never nil on success.
+//   - error: Non-nil on storage or decode failure.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (s *BadgerRouterCacheStore) LoadToolEmbeddings(ctx context.Context, docHashes map[string]string) (map[string][]float32, error) {
+	entries := make(map[string]toolVectorEntry, len(docHashes))
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		for tool, docHash := range docHashes {
+			item, err := txn.Get(s.toolCacheKey(docHash))
+			if errors.Is(err, dgbadger.ErrKeyNotFound) {
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("get cache key: %w", err)
+			}
+			raw, err := item.ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("copy value: %w", err)
+			}
+			entry, err := decodeToolVectorEntry(raw)
+			if err != nil {
+				return fmt.Errorf("decode tool %q: %w", tool, err)
+			}
+			entries[tool] = entry
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("router cache load tools: %w", err)
+	}
+
+	// Sort for a deterministic reference dimension.
+	tools := make([]string, 0, len(entries))
+	for tool := range entries {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	vectors := make(map[string][]float32, len(entries))
+	dim := s.model.Dim
+	var stale [][]byte
+	for _, tool := range tools {
+		entry := entries[tool]
+		reason := ""
+		switch {
+		case entry.Model != s.model.Name:
+			reason = "model mismatch"
+		case entry.Tool != tool:
+			reason = "tool mismatch"
+		case len(entry.Vector) == 0 || len(entry.Vector) != entry.Dim:
+			reason = "inconsistent vector dimension"
+		case dim > 0 && entry.Dim != dim:
+			reason = "dimension mismatch"
+		}
+		if reason != "" {
+			s.logger.Warn("router cache: discarding mismatched tool entry",
+				slog.String("tool", tool),
+				slog.String("reason", reason),
+				slog.String("entry_model", entry.Model),
+				slog.Int("entry_dim", entry.Dim),
+				slog.String("store_model", s.model.String()),
+			)
+			stale = append(stale, s.toolCacheKey(docHashes[tool]))
+			continue
+		}
+		dim = entry.Dim
+		vectors[tool] = entry.Vector
+	}
+	if len(stale) > 0 {
+		if _, err := s.deleteKeys(ctx, stale); err != nil {
+			s.logger.Warn("router cache: failed to delete mismatched tool entries",
+				telemetry.SensitiveLogError("error", err),
+			)
+		}
+	}
+
+	s.logger.Debug("router cache: loaded tool entries",
+		slog.Int("requested", len(docHashes)),
+		slog.Int("found", len(vectors)),
+	)
+	return vectors, nil
+}
+
+// SaveToolEmbeddings persists one per-tool entry per vector with the
+// configured TTL.
+//
+// # Description
+//
+// All entries are written in one transaction under
+// routing/emb/v3/{model}/{docHash}. Existing entries of other tools are
+// untouched, so successive partial warm-ups accumulate.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - docHashes: Tool name → hash of its embedding document.
+//   - vectors: Unit-normalized vectors, keyed by tool name. Empty is a no-op.
+//
+// # Outputs
+//
+//   - error: Non-nil on a missing document hash, inconsistent or unexpected
+//     vector dimensions, or encode or storage failure.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (s *BadgerRouterCacheStore) SaveToolEmbeddings(ctx context.Context, docHashes map[string]string, vectors map[string][]float32) error {
+	if len(vectors) == 0 {
+		return nil
+	}
+
+	dim := -1
+	for tool, vec := range vectors {
+		if docHashes[tool] == "" {
+			return fmt.Errorf("router cache save tools: no document hash for tool %q", tool)
+		}
+		if dim < 0 {
+			dim = len(vec)
+		}
+		if len(vec) != dim || dim == 0 {
+			return fmt.Errorf("router cache save tools: tool %q has dimension %d, want %d", tool, len(vec), dim)
+		}
+	}
+	if s.model.Dim > 0 && dim != s.model.Dim {
+		return fmt.Errorf("router cache save tools: dimension %d does not match model %s", dim, s.model)
+	}
+
+	err := s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		for tool, vec := range vectors {
+			raw, err := encodeToolVectorEntry(toolVectorEntry{Model: s.model.Name, Tool: tool, Dim: dim, Vector: vec})
+			if err != nil {
+				return fmt.Errorf("encode tool %q: %w", tool, err)
+			}
+			if err := txn.SetEntry(dgbadger.NewEntry(s.toolCacheKey(docHashes[tool]), raw).WithTTL(s.ttl)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("router cache save tools: %w", err)
+	}
+
+	s.logger.Debug("router cache: saved tool entries",
+		slog.Int("tool_count", len(vectors)),
+		slog.Int("dim", dim),
 		slog.Duration("ttl", s.ttl),
 	)
 	return nil
 }
 
+// ActiveCorpus returns the corpus last activated for the store's model.
+//
+// # Description
+//
+// Reads routing/emb/active/{model}. A missing or undecodable record is
+// reported as no active corpus.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//
+// # Outputs
+//
+//   - ActiveCorpus: The active corpus; CorpusHash is empty if none.
+//   - error: Non-nil on storage failure.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (s *BadgerRouterCacheStore) ActiveCorpus(ctx context.Context) (ActiveCorpus, error) {
+	var active ActiveCorpus
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		var err error
+		active, err = s.readActiveCorpus(txn)
+		return err
+	})
+	if err != nil {
+		return ActiveCorpus{}, fmt.Errorf("router cache active corpus: %w", err)
+	}
+	return active, nil
+}
+
+// ActivateCorpus records corpus as the active corpus of the store's model.
+//
+// # Description
+//
+// Writes routing/emb/active/{model}, then deletes the per-tool entries of
+// the previously active corpus whose document hash the new corpus does not
+// use, and its corpus (v2) entry. Entries never activated are left alone;
+// they expire through their TTL.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - corpus: The corpus to activate. CorpusHash must not be empty.
+//
+// # Outputs
+//
+//   - int: Number of entries deleted.
+//   - error: Non-nil on an empty corpus hash, or encode or storage failure.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (s *BadgerRouterCacheStore) ActivateCorpus(ctx context.Context, corpus ActiveCorpus) (int, error) {
+	if corpus.CorpusHash == "" {
+		return 0, errors.New("router cache activate: empty corpus hash")
+	}
+	raw, err := encodeActiveCorpus(activeCorpusEntry{Model: s.model.Name, CorpusHash: corpus.CorpusHash, DocHashes: corpus.DocHashes})
+	if err != nil {
+		return 0, fmt.Errorf("router cache activate: %w", err)
+	}
+
+	var previous ActiveCorpus
+	var stale [][]byte
+	err = s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		var err error
+		if previous, err = s.readActiveCorpus(txn); err != nil {
+			return err
+		}
+		if previous.CorpusHash != "" && previous.CorpusHash != corpus.CorpusHash {
+			candidates := [][]byte{s.routerCacheKey(previous.CorpusHash)}
+			for _, docHash := range previous.DocHashes {
+				if !corpus.uses(docHash) {
+					candidates = append(candidates, s.toolCacheKey(docHash))
+				}
+			}
+			for _, key := range candidates {
+				if _, err := txn.Get(key); err == nil {
+					stale = append(stale, key)
+				} else if !errors.Is(err, dgbadger.ErrKeyNotFound) {
+					return err
+				}
+			}
+		}
+		return txn.Set(s.activeCorpusKey(), raw)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("router cache activate: %w", err)
+	}
+	if len(stale) == 0 {
+		return 0, nil
+	}
+
+	removed, err := s.deleteKeys(ctx, stale)
+	if err != nil {
+		return removed, fmt.Errorf("router cache activate: %w", err)
+	}
+
+	s.logger.Info("router cache: activated new tool corpus, deleted stale entries",
+		slog.String("previous_hash", shortHash(previous.CorpusHash)),
+		slog.String("hash", shortHash(corpus.CorpusHash)),
+		slog.Int("removed", removed),
+	)
+	return removed, nil
+}
+
+// readActiveCorpus reads the active corpus record within txn.
+func (s *BadgerRouterCacheStore) readActiveCorpus(txn *dgbadger.Txn) (ActiveCorpus, error) {
+	item, err := txn.Get(s.activeCorpusKey())
+	if errors.Is(err, dgbadger.ErrKeyNotFound) {
+		return ActiveCorpus{}, nil
+	}
+	if err != nil {
+		return ActiveCorpus{}, err
+	}
+	raw, err := item.ValueCopy(nil)
+	if err != nil {
+		return ActiveCorpus{}, err
+	}
+	entry, err := decodeActiveCorpus(raw)
+	if err != nil || entry.Model != s.model.Name {
+		s.logger.Warn("router cache: ignoring unreadable active corpus record")
+		return ActiveCorpus{}, nil
+	}
+	return ActiveCorpus{CorpusHash: entry.CorpusHash, DocHashes: entry.DocHashes}, nil
+}
+
+// MigrateModel invalidates cached vectors written by other embedding models.
+//
+// # Description
+//
+// Compares the store's model name with the one recorded by the previous
+// run. If they differ (or nothing was recorded), deletes legacy v1 entries
+// and the v2 and v3 entries and active corpus records of every other model,
+// then records the current model.
+// Safe to call on every startup: when the model is unchanged it only reads
+// one key.
+//
+// Routing feedback records are not touched; they carry their model name
+// and are ignored by FeedbackIndex.Vote for other models.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//
+// # Outputs
+//
+//   - string: The previously recorded model name. Empty if none.
+//   - int: Number of entries deleted.
+//   - error: Non-nil on storage failure.
+//
+// # Thread Safety
+//
+// Safe for concurrent use, but intended to run once at startup.
+func (s *BadgerRouterCacheStore) MigrateModel(ctx context.Context) (string, int, error) {
+	var previous string
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		item, err := txn.Get([]byte(routerCacheModelKey))
+		if errors.Is(err, dgbadger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		previous = string(val)
+		return nil
+	})
+	if err != nil {
+		return "", 0, fmt.Errorf("router cache migrate: %w", err)
+	}
+	if previous == s.model.Name {
+		return previous, 0, nil
+	}
+
+	current := []byte(routerCacheKeyPrefix + s.model.Name + "/")
+	currentTools := []byte(routerCacheToolPrefix + s.model.Name + "/")
+	currentActive := s.activeCorpusKey()
+	var stale [][]byte
+	err = s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		opts := dgbadger.DefaultIteratorOptions
+		opts.Prefix = []byte("routing/emb/")
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			key := it.Item().Key()
+			switch {
+			case string(key) == routerCacheModelKey, bytes.Equal(key, currentActive):
+			case bytes.HasPrefix(key, current), bytes.HasPrefix(key, currentTools):
+			default:
+				stale = append(stale, it.Item().KeyCopy(nil))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return previous, 0, fmt.Errorf("router cache migrate: %w", err)
+	}
+
+	removed, err := s.deleteKeys(ctx, stale)
+	if err != nil {
+		return previous, removed, fmt.Errorf("router cache migrate: %w", err)
+	}
+
+	err = s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.Set([]byte(routerCacheModelKey), []byte(s.model.Name))
+	})
+	if err != nil {
+		return previous, removed, fmt.Errorf("router cache migrate: %w", err)
+	}
+
+	s.logger.Info("router cache: embedding model changed, invalidated cached vectors",
+		slog.String("previous_model", previous),
+		slog.String("model", s.model.Name),
+		slog.Int("removed", removed),
+	)
+	return previous, removed, nil
+}
+
+// ListEmbeddings returns a description of every persisted embedding entry.
+//
+// # Description
+//
+// Lists per-tool v3 entries, v2 entries and legacy v1 entries, decoding each
+// value to report its model and dimension. Entries that do not match the store's model are
+// marked Stale; undecodable values are reported with Dim 0. Once a corpus has
+// been activated, each usable entry is marked Active if that corpus uses it
+// and Orphaned otherwise.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//
+// # Outputs
+//
+//   - []EmbeddingCacheEntry: Entries in key order.
+//   - error: Non-nil on storage failure.
+//
+// # Thread Safety
+//
+// Safe for concurrent use.
+func (s *BadgerRouterCacheStore) ListEmbeddings(ctx context.Context) ([]EmbeddingCacheEntry, error) {
+	var entries []EmbeddingCacheEntry
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		active, err := s.readActiveCorpus(txn)
+		if err != nil {
+			return fmt.Errorf("read active corpus: %w", err)
+		}
+
+		opts := dgbadger.DefaultIteratorOptions
+		opts.Prefix = []byte("routing/emb/v")
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			key := string(item.Key())
+			info := EmbeddingCacheEntry{Key: key}
+			if exp := item.ExpiresAt(); exp > 0 {
+				info.ExpiresAt = time.Unix(int64(exp), 0)
+			}
+
+			raw, err := item.ValueCopy(nil)
+			if err != nil {
+				return fmt.Errorf("copy value: %w", err)
+			}
+
+			if strings.HasPrefix(key, routerCacheToolPrefix) {
+				rest := strings.TrimPrefix(key, routerCacheToolPrefix)
+				if i := strings.LastIndexByte(rest, '/'); i >= 0 {
+					info.CorpusHash = rest[i+1:]
+				}
+				if entry, err := decodeToolVectorEntry(raw); err == nil {
+					info.Model = entry.Model
+					info.Tool = entry.Tool
+					info.Dim = entry.Dim
+					info.ToolCount = 1
+					info.Stale = entry.Model != s.model.Name ||
+						(s.model.Dim > 0 && entry.Dim != s.model.Dim) ||
+						len(entry.Vector) != entry.Dim
+				} else {
+					info.Stale = true
+				}
+			} else if strings.HasPrefix(key, routerCacheLegacyPrefix) {
+				info.Legacy = true
+				info.Stale = true
+				info.CorpusHash = strings.TrimPrefix(key, routerCacheLegacyPrefix)
+				if vectors, err := gobDecode(raw); err == nil {
+					info.ToolCount = len(vectors)
+					for _, vec := range vectors {
+						info.Dim = len(vec)
+						break
+					}
+				}
+			} else {
+				rest := strings.TrimPrefix(key, routerCacheKeyPrefix)
+				if i := strings.LastIndexByte(rest, '/'); i >= 0 {
+					info.CorpusHash = rest[i+1:]
+				}
+				if entry, err := decodeCacheEntry(raw); err == nil {
+					info.Model = entry.Model
+					info.Dim = entry.Dim
+					info.ToolCount = len(entry.Vectors)
+					info.Stale = s.checkEntry(entry) != ""
+				} else {
+					info.Stale = true
+				}
+			}
+			if !info.Stale && active.CorpusHash != "" {
+				if info.Tool != "" {
+					info.Active = active.uses(info.CorpusHash)
+				} else {
+					info.Active = info.CorpusHash == active.CorpusHash
+				}
+				info.Orphaned = !info.Active
+			}
+			entries = append(entries, info)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("router cache list: %w", err)
+	}
+	return entries, nil
+}
+
+// checkEntry returns why an entry cannot be used by this store, or "".
+func (s *BadgerRouterCacheStore) checkEntry(entry embeddingCacheEntry) string {
+	if entry.Model != s.model.Name {
+		return "model mismatch"
+	}
+	if s.model.Dim > 0 && entry.Dim != s.model.Dim {
+		return "dimension mismatch"
+	}
+	for _, vec := range entry.Vectors {
+		if len(vec) != entry.Dim {
+			return "inconsistent vector dimension"
+		}
+	}
+	return ""
+}
+
+// deleteKeys deletes keys in batches and returns how many were deleted.
+func (s *BadgerRouterCacheStore) deleteKeys(ctx context.Context, keys [][]byte) (int, error) {
+	for start := 0; start < len(keys); start += routerCacheDeleteBatch {
+		end := min(start+routerCacheDeleteBatch, len(keys))
+		err := s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+			for _, k := range keys[start:end] {
+				if err := txn.Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return start, err
+		}
+	}
+	return len(keys), nil
+}
+
 // =============================================================================
 // Corpus Hash
 // =============================================================================
@@ -300,7 +1119,7 @@ func (s *BadgerRouterCacheStore) SaveEmbeddings(ctx context.Context, corpusHash
 //   - Tool name (changes if tool is renamed)
 //   - Tool BestFor keywords (changes if keywords are added/removed/changed)
 //   - Tool UseWhen text (changes if description is updated)
-//   - Embedding model name (changes if EMBEDDING_MODEL env var changes)
+//   - Embedding model name (changes if ROUTING_EMBED_MODEL/EMBEDDING_MODEL changes)
 //
 // AvoidWhen is excluded: it is not used in the embedding document (negative
 // framing degrades embedding quality) so a change to AvoidWhen does not
@@ -345,13 +1164,39 @@ func computeCorpusHash(specs []ToolSpec, model string) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// computeToolDocHash returns the hex SHA256 of a tool's embedding document.
+//
+// # Description
+//
+// Covers exactly the text that is embedded (see buildEmbeddingDoc), so a
+// per-tool entry stays valid until that text changes. The model name is
+// part of the per-tool key rather than the hash.
+//
+// # Thread Safety
+//
+// Stateless. Safe for concurrent use.
+func computeToolDocHash(spec ToolSpec) string {
+	sum := sha256.Sum256([]byte(buildEmbeddingDoc(spec)))
+	return hex.EncodeToString(sum[:])
+}
+
 // =============================================================================
 // Helpers
 // =============================================================================
 
+// toolCacheKey builds the BadgerDB key of a per-tool entry.
+func (s *BadgerRouterCacheStore) toolCacheKey(docHash string) []byte {
+	return []byte(routerCacheToolPrefix + s.model.Name + "/" + docHash)
+}
+
+// activeCorpusKey builds the BadgerDB key of the active corpus record.
+func (s *BadgerRouterCacheStore) activeCorpusKey() []byte {
+	return []byte(routerCacheActivePrefix + s.model.Name)
+}
+
 // routerCacheKey builds the BadgerDB key for the given corpus hash.
-func routerCacheKey(corpusHash string) []byte {
-	return []byte(routerCacheKeyPrefix + corpusHash)
+func (s *BadgerRouterCacheStore) routerCacheKey(corpusHash string) []byte {
+	return []byte(routerCacheKeyPrefix + s.model.Name + "/" + corpusHash)
 }
 
 // shortHash returns the first 8 characters of a hash for log display.
@@ -379,3 +1224,57 @@ func gobDecode(data []byte) (map[string][]float32, error) {
 	}
 	return vectors, nil
 }
+
+// encodeCacheEntry serializes an embeddingCacheEntry using encoding/gob.
+func encodeCacheEntry(entry embeddingCacheEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return nil, fmt.Errorf("gob encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeCacheEntry deserializes an embeddingCacheEntry from gob-encoded bytes.
+func decodeCacheEntry(data []byte) (embeddingCacheEntry, error) {
+	var entry embeddingCacheEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return embeddingCacheEntry{}, fmt.Errorf("gob decode: %w", err)
+	}
+	return entry, nil
+}
+
+// encodeToolVectorEntry serializes a toolVectorEntry using encoding/gob.
+func encodeToolVectorEntry(entry toolVectorEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return nil, fmt.Errorf("gob encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeToolVectorEntry deserializes a toolVectorEntry from gob-encoded bytes.
+func decodeToolVectorEntry(data []byte) (toolVectorEntry, error) {
+	var entry toolVectorEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return toolVectorEntry{}, fmt.Errorf("gob decode: %w", err)
+	}
+	return entry, nil
+}
+
+// encodeActiveCorpus serializes an activeCorpusEntry using encoding/gob.
+func encodeActiveCorpus(entry activeCorpusEntry) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return nil, fmt.Errorf("gob encode: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeActiveCorpus deserializes an activeCorpusEntry from gob-encoded bytes.
+func decodeActiveCorpus(data []byte) (activeCorpusEntry, error) {
+	var entry activeCorpusEntry
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&entry); err != nil {
+		return activeCorpusEntry{}, fmt.Errorf("gob decode: %w", err)
+	}
+	return entry, nil
+}
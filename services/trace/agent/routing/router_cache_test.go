@@ -12,8 +12,11 @@ package routing
 
 import (
 	"context"
+	"maps"
 	"testing"
 
+	dgbadger "github.com/dgraph-io/badger/v4"
+
 	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
 )
 
@@ -48,7 +51,7 @@ func makeTestVectors() map[string][]float32 {
 
 func TestRouterCache_Load_EmptyDB(t *testing.T) {
 	db := openTestDB(t)
-	store := NewBadgerRouterCacheStore(db, 0, nil)
+	store := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
 
 	vectors, err := store.LoadEmbeddings(context.Background(), "nonexistenthash")
 	if err != nil {
@@ -61,7 +64,7 @@ func TestRouterCache_Load_EmptyDB(t *testing.T) {
 
 func TestRouterCache_Load_ContextCancelled(t *testing.T) {
 	db := openTestDB(t)
-	store := NewBadgerRouterCacheStore(db, 0, nil)
+	store := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // cancelled immediately
@@ -81,7 +84,7 @@ func TestRouterCache_Load_ContextCancelled(t *testing.T) {
 
 func TestRouterCache_Save_EmptyVectors(t *testing.T) {
 	db := openTestDB(t)
-	store := NewBadgerRouterCacheStore(db, 0, nil)
+	store := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
 
 	// Empty map should be a no-op — not an error.
 	if err := store.SaveEmbeddings(context.Background(), "anyhash", nil); err != nil {
@@ -94,7 +97,7 @@ func TestRouterCache_Save_EmptyVectors(t *testing.T) {
 
 func TestRouterCache_Save_ContextCancelled(t *testing.T) {
 	db := openTestDB(t)
-	store := NewBadgerRouterCacheStore(db, 0, nil)
+	store := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
 
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
@@ -112,7 +115,7 @@ func TestRouterCache_Save_ContextCancelled(t *testing.T) {
 
 func TestRouterCache_RoundTrip(t *testing.T) {
 	db := openTestDB(t)
-	store := NewBadgerRouterCacheStore(db, 0, nil)
+	store := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
 	ctx := context.Background()
 
 	want := makeTestVectors()
@@ -150,7 +153,7 @@ func TestRouterCache_RoundTrip(t *testing.T) {
 
 func TestRouterCache_RoundTrip_MultipleKeys(t *testing.T) {
 	db := openTestDB(t)
-	store := NewBadgerRouterCacheStore(db, 0, nil)
+	store := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
 	ctx := context.Background()
 
 	hashes := []string{"hashA", "hashB", "hashC"}
@@ -184,7 +187,7 @@ func TestRouterCache_RoundTrip_MultipleKeys(t *testing.T) {
 func TestRouterCache_Miss_AfterOverwrite(t *testing.T) {
 	// Saving a new hash does not affect an old hash — different keys.
 	db := openTestDB(t)
-	store := NewBadgerRouterCacheStore(db, 0, nil)
+	store := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
 	ctx := context.Background()
 
 	if err := store.SaveEmbeddings(ctx, "hash1", makeTestVectors()); err != nil {
@@ -379,3 +382,301 @@ func TestShortHash_ExactlyEight(t *testing.T) {
 		t.Errorf("expected %q unchanged, got %q", h, got)
 	}
 }
+
+// =============================================================================
+// Model Identity Tests
+// =============================================================================
+
+func TestRouterCache_Load_ModelMismatchDiscarded(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	old := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "model-a"}, nil)
+	if err := old.SaveEmbeddings(ctx, "hash", makeTestVectors()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	// Same key space, different model: the key differs, so this is a plain miss.
+	other := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "model-b"}, nil)
+	if got, err := other.LoadEmbeddings(ctx, "hash"); err != nil || got != nil {
+		t.Errorf("expected miss for other model, got %v, %v", got, err)
+	}
+
+	// Same model name but a pinned dimension that does not match.
+	pinned := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "model-a", Dim: 768}, nil)
+	if got, err := pinned.LoadEmbeddings(ctx, "hash"); err != nil || got != nil {
+		t.Errorf("expected miss for dimension mismatch, got %v, %v", got, err)
+	}
+	// The mismatched entry is deleted on load.
+	if got, _ := old.LoadEmbeddings(ctx, "hash"); got != nil {
+		t.Error("expected mismatched entry to be deleted")
+	}
+}
+
+func TestRouterCache_Save_DimensionValidation(t *testing.T) {
+	store := NewBadgerRouterCacheStore(openTestDB(t), 0, EmbeddingModelIdentity{Name: "m", Dim: 4}, nil)
+	ctx := context.Background()
+
+	mixed := map[string][]float32{"a": {1, 2, 3, 4}, "b": {1, 2}}
+	if err := store.SaveEmbeddings(ctx, "hash", mixed); err == nil {
+		t.Error("expected error for inconsistent dimensions")
+	}
+	wrong := map[string][]float32{"a": {1, 2}}
+	if err := store.SaveEmbeddings(ctx, "hash", wrong); err == nil {
+		t.Error("expected error for dimension not matching the model")
+	}
+	if err := store.SaveEmbeddings(ctx, "hash", makeTestVectors()); err != nil {
+		t.Errorf("expected matching dimension to save, got %v", err)
+	}
+}
+
+func TestRouterCache_MigrateModel(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	// Legacy v1 entry without model identity.
+	raw, err := gobEncode(makeTestVectors())
+	if err != nil {
+		t.Fatalf("gobEncode: %v", err)
+	}
+	if err := db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.Set([]byte(routerCacheLegacyPrefix+"legacyhash"), raw)
+	}); err != nil {
+		t.Fatalf("write legacy entry: %v", err)
+	}
+
+	a := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "model-a"}, nil)
+	if prev, removed, err := a.MigrateModel(ctx); err != nil || prev != "" || removed != 1 {
+		t.Fatalf("first MigrateModel = %q, %d, %v; want \"\", 1, nil", prev, removed, err)
+	}
+	if err := a.SaveEmbeddings(ctx, "hash", makeTestVectors()); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	if _, removed, _ := a.MigrateModel(ctx); removed != 0 {
+		t.Errorf("unchanged model removed %d entries, want 0", removed)
+	}
+
+	entries, err := a.ListEmbeddings(ctx)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("ListEmbeddings = %+v, %v", entries, err)
+	}
+	if e := entries[0]; e.Model != "model-a" || e.Dim != 4 || e.ToolCount != 3 || e.CorpusHash != "hash" || e.Stale {
+		t.Errorf("unexpected entry: %+v", e)
+	}
+
+	b := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "model-b"}, nil)
+	if prev, removed, err := b.MigrateModel(ctx); err != nil || prev != "model-a" || removed != 1 {
+		t.Errorf("MigrateModel after model change = %q, %d, %v; want model-a, 1, nil", prev, removed, err)
+	}
+	if entries, _ := b.ListEmbeddings(ctx); len(entries) != 0 {
+		t.Errorf("expected no entries after migration, got %+v", entries)
+	}
+}
+
+// =============================================================================
+// Per-Tool Entry Tests
+// =============================================================================
+
+func TestRouterCache_ToolEmbeddings_RoundTrip(t *testing.T) {
+	store := NewBadgerRouterCacheStore(openTestDB(t), 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
+	ctx := context.Background()
+	docHashes := map[string]string{"find_references": "h1", "find_symbol": "h2", "find_callers": "h3"}
+
+	got, err := store.LoadToolEmbeddings(ctx, docHashes)
+	if err != nil || len(got) != 0 {
+		t.Fatalf("LoadToolEmbeddings on empty DB = %v, %v; want empty, nil", got, err)
+	}
+
+	// Two saves accumulate instead of replacing each other.
+	vectors := makeTestVectors()
+	if err := store.SaveToolEmbeddings(ctx, docHashes, map[string][]float32{"find_references": vectors["find_references"]}); err != nil {
+		t.Fatalf("SaveToolEmbeddings: %v", err)
+	}
+	if err := store.SaveToolEmbeddings(ctx, docHashes, map[string][]float32{"find_symbol": vectors["find_symbol"]}); err != nil {
+		t.Fatalf("SaveToolEmbeddings: %v", err)
+	}
+
+	got, err = store.LoadToolEmbeddings(ctx, docHashes)
+	if err != nil {
+		t.Fatalf("LoadToolEmbeddings: %v", err)
+	}
+	if len(got) != 2 || got["find_callers"] != nil {
+		t.Fatalf("expected find_references and find_symbol, got %v", got)
+	}
+	for i, v := range vectors["find_symbol"] {
+		if got["find_symbol"][i] != v {
+			t.Errorf("find_symbol[%d] = %v, want %v", i, got["find_symbol"][i], v)
+		}
+	}
+
+	// A changed document hash is a miss for that tool only.
+	docHashes["find_symbol"] = "h2-changed"
+	if got, _ := store.LoadToolEmbeddings(ctx, docHashes); len(got) != 1 || got["find_references"] == nil {
+		t.Errorf("expected only find_references after doc change, got %v", got)
+	}
+}
+
+func TestRouterCache_ToolEmbeddings_Validation(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	store := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "m", Dim: 4}, nil)
+	docHashes := map[string]string{"a": "ha", "b": "hb"}
+
+	if err := store.SaveToolEmbeddings(ctx, docHashes, map[string][]float32{"c": {1, 2, 3, 4}}); err == nil {
+		t.Error("expected error for tool without document hash")
+	}
+	if err := store.SaveToolEmbeddings(ctx, docHashes, map[string][]float32{"a": {1, 2, 3, 4}, "b": {1, 2}}); err == nil {
+		t.Error("expected error for inconsistent dimensions")
+	}
+	if err := store.SaveToolEmbeddings(ctx, docHashes, map[string][]float32{"a": {1, 2}}); err == nil {
+		t.Error("expected error for dimension not matching the model")
+	}
+	if err := store.SaveToolEmbeddings(ctx, docHashes, map[string][]float32{"a": {1, 2, 3, 4}}); err != nil {
+		t.Fatalf("SaveToolEmbeddings: %v", err)
+	}
+
+	// The entry for "a" is read back under another tool name: discarded and deleted.
+	if got, err := store.LoadToolEmbeddings(ctx, map[string]string{"b": "ha"}); err != nil || len(got) != 0 {
+		t.Errorf("expected tool mismatch to be discarded, got %v, %v", got, err)
+	}
+	if got, _ := store.LoadToolEmbeddings(ctx, docHashes); len(got) != 0 {
+		t.Errorf("expected mismatched entry to be deleted, got %v", got)
+	}
+}
+
+func TestRouterCache_MigrateModel_KeepsCurrentToolEntries(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	docHashes := map[string]string{"find_references": "h1", "find_symbol": "h2", "find_callers": "h3"}
+
+	a := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "model-a"}, nil)
+	if _, _, err := a.MigrateModel(ctx); err != nil {
+		t.Fatalf("MigrateModel: %v", err)
+	}
+	if err := a.SaveToolEmbeddings(ctx, docHashes, makeTestVectors()); err != nil {
+		t.Fatalf("SaveToolEmbeddings: %v", err)
+	}
+	if _, removed, _ := a.MigrateModel(ctx); removed != 0 {
+		t.Errorf("unchanged model removed %d entries, want 0", removed)
+	}
+
+	entries, err := a.ListEmbeddings(ctx)
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("ListEmbeddings = %+v, %v", entries, err)
+	}
+	for _, e := range entries {
+		if e.Model != "model-a" || e.Tool == "" || e.ToolCount != 1 || e.Dim != 4 || e.Stale || e.CorpusHash != docHashes[e.Tool] {
+			t.Errorf("unexpected entry: %+v", e)
+		}
+	}
+
+	b := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "model-b"}, nil)
+	if _, removed, err := b.MigrateModel(ctx); err != nil || removed != 3 {
+		t.Errorf("MigrateModel after model change removed %d, %v; want 3, nil", removed, err)
+	}
+}
+
+func TestRouterCache_ActivateCorpus(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	store := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "test-model"}, nil)
+	vectors := makeTestVectors()
+
+	if active, err := store.ActiveCorpus(ctx); err != nil || active.CorpusHash != "" {
+		t.Fatalf("ActiveCorpus on empty DB = %+v, %v", active, err)
+	}
+
+	first := ActiveCorpus{CorpusHash: "corpus-a", DocHashes: map[string]string{"find_references": "h1", "find_symbol": "h2"}}
+	if err := store.SaveToolEmbeddings(ctx, first.DocHashes, map[string][]float32{
+		"find_references": vectors["find_references"], "find_symbol": vectors["find_symbol"],
+	}); err != nil {
+		t.Fatalf("SaveToolEmbeddings: %v", err)
+	}
+	if removed, err := store.ActivateCorpus(ctx, first); err != nil || removed != 0 {
+		t.Fatalf("first ActivateCorpus = %d, %v", removed, err)
+	}
+
+	// find_symbol changed and find_callers was added; not activated yet.
+	second := ActiveCorpus{CorpusHash: "corpus-b", DocHashes: map[string]string{"find_references": "h1", "find_symbol": "h2-changed", "find_callers": "h3"}}
+	if err := store.SaveToolEmbeddings(ctx, second.DocHashes, map[string][]float32{
+		"find_symbol": vectors["find_symbol"], "find_callers": vectors["find_callers"],
+	}); err != nil {
+		t.Fatalf("SaveToolEmbeddings: %v", err)
+	}
+	status := func() map[string]string {
+		entries, err := store.ListEmbeddings(ctx)
+		if err != nil {
+			t.Fatalf("ListEmbeddings: %v", err)
+		}
+		out := make(map[string]string, len(entries))
+		for _, e := range entries {
+			switch {
+			case e.Active:
+				out[e.CorpusHash] = "active"
+			case e.Orphaned:
+				out[e.CorpusHash] = "orphaned"
+			default:
+				out[e.CorpusHash] = "unknown"
+			}
+		}
+		return out
+	}
+	want := map[string]string{"h1": "active", "h2": "active", "h2-changed": "orphaned", "h3": "orphaned"}
+	if got := status(); !maps.Equal(got, want) {
+		t.Errorf("before activation: %v, want %v", got, want)
+	}
+
+	// Activating the second corpus deletes the entry only the first used.
+	if removed, err := store.ActivateCorpus(ctx, second); err != nil || removed != 1 {
+		t.Fatalf("second ActivateCorpus = %d, %v; want 1", removed, err)
+	}
+	want = map[string]string{"h1": "active", "h2-changed": "active", "h3": "active"}
+	if got := status(); !maps.Equal(got, want) {
+		t.Errorf("after activation: %v, want %v", got, want)
+	}
+	if active, _ := store.ActiveCorpus(ctx); active.CorpusHash != "corpus-b" || len(active.DocHashes) != 3 {
+		t.Errorf("ActiveCorpus = %+v, want corpus-b", active)
+	}
+
+	// Another model drops the record on migration.
+	other := NewBadgerRouterCacheStore(db, 0, EmbeddingModelIdentity{Name: "other-model"}, nil)
+	if _, _, err := other.MigrateModel(ctx); err != nil {
+		t.Fatalf("MigrateModel: %v", err)
+	}
+	if active, _ := store.ActiveCorpus(ctx); active.CorpusHash != "" {
+		t.Errorf("ActiveCorpus after model change = %+v, want none", active)
+	}
+}
+
+func TestComputeToolDocHash(t *testing.T) {
+	spec := ToolSpec{Name: "find_callers", BestFor: []string{"callers"}, UseWhen: "who calls X"}
+	if computeToolDocHash(spec) != computeToolDocHash(spec) {
+		t.Error("expected deterministic hash")
+	}
+	changed := spec
+	changed.UseWhen = "who invokes X"
+	if computeToolDocHash(spec) == computeToolDocHash(changed) {
+		t.Error("expected UseWhen change to change the hash")
+	}
+	avoid := spec
+	avoid.AvoidWhen = "not for callees"
+	if computeToolDocHash(spec) != computeToolDocHash(avoid) {
+		t.Error("expected AvoidWhen, which is not embedded, to leave the hash unchanged")
+	}
+}
+
+func TestResolveRoutingEmbedModel(t *testing.T) {
+	t.Setenv("ROUTING_EMBED_MODEL", "")
+	t.Setenv("EMBEDDING_MODEL", "")
+	if got := ResolveRoutingEmbedModel(); got != DefaultRoutingEmbedModel {
+		t.Errorf("default = %q, want %q", got, DefaultRoutingEmbedModel)
+	}
+	t.Setenv("EMBEDDING_MODEL", "shared-model")
+	if got := ResolveRoutingEmbedModel(); got != "shared-model" {
+		t.Errorf("EMBEDDING_MODEL = %q, want shared-model", got)
+	}
+	t.Setenv("ROUTING_EMBED_MODEL", "routing-model")
+	if got := ResolveRoutingEmbedModel(); got != "routing-model" {
+		t.Errorf("ROUTING_EMBED_MODEL = %q, want routing-model", got)
+	}
+}
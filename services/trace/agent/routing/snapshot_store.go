@@ -0,0 +1,199 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package routing
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// SnapshotRouterCacheStore reads tool embeddings from a shared read-only
+// snapshot when the instance's own cache misses.
+//
+// # Description
+//
+// Lets replicas started together share one warm embedding cache instead of
+// each re-embedding the tool corpus. Loads go to the local store first and
+// fall back to the snapshot; vectors found only in the snapshot are copied
+// into the local store so later restarts do not need it. Saves and routing
+// feedback go to the local store only; the snapshot is never written.
+//
+// Implements RouterCacheStore, ToolEmbeddingStore, ActiveCorpusStore and
+// RoutingFeedbackStore. The per-tool, active corpus and feedback methods use
+// the underlying stores only if they implement those interfaces; otherwise
+// they behave as an empty store.
+//
+// # Thread Safety
+//
+// Safe for concurrent use if both stores are.
+type SnapshotRouterCacheStore struct {
+	local    RouterCacheStore
+	snapshot RouterCacheStore
+	logger   *slog.Logger
+}
+
+// NewSnapshotRouterCacheStore layers a read-only snapshot under a local store.
+//
+// # Inputs
+//
+//   - local: The instance's own store. May be nil for snapshot-only reads.
+//   - snapshot: The shared snapshot (typically a BadgerRouterCacheStore on
+//     a DB opened read-only). Must not be nil.
+//   - logger: Logger for fallback diagnostics. May be nil.
+//
+// # Outputs
+//
+//   - *SnapshotRouterCacheStore: The layered store. Never nil.
+func NewSnapshotRouterCacheStore(local, snapshot RouterCacheStore, logger *slog.Logger) *SnapshotRouterCacheStore {
+	if snapshot == nil {
+		panic("NewSnapshotRouterCacheStore: snapshot must not be nil")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SnapshotRouterCacheStore{local: local, snapshot: snapshot, logger: logger}
+}
+
+// LoadEmbeddings implements RouterCacheStore.
+func (s *SnapshotRouterCacheStore) LoadEmbeddings(ctx context.Context, corpusHash string) (map[string][]float32, error) {
+	if s.local != nil {
+		vectors, err := s.local.LoadEmbeddings(ctx, corpusHash)
+		if err != nil {
+			s.logger.Warn("router cache: local load failed, trying snapshot", telemetry.SensitiveLogError("error", err))
+		} else if len(vectors) > 0 {
+			return vectors, nil
+		}
+	}
+	vectors, err := s.snapshot.LoadEmbeddings(ctx, corpusHash)
+	if err != nil || len(vectors) == 0 {
+		return nil, err
+	}
+	s.logger.Info("router cache: corpus loaded from shared snapshot",
+		slog.String("hash", shortHash(corpusHash)),
+		slog.Int("tool_count", len(vectors)),
+	)
+	if s.local != nil {
+		if err := s.local.SaveEmbeddings(ctx, corpusHash, vectors); err != nil {
+			s.logger.Warn("router cache: failed to copy snapshot entry", telemetry.SensitiveLogError("error", err))
+		}
+	}
+	return vectors, nil
+}
+
+// SaveEmbeddings implements RouterCacheStore. Writes the local store only.
+func (s *SnapshotRouterCacheStore) SaveEmbeddings(ctx context.Context, corpusHash string, vectors map[string][]float32) error {
+	if s.local == nil {
+		return nil
+	}
+	return s.local.SaveEmbeddings(ctx, corpusHash, vectors)
+}
+
+// LoadToolEmbeddings implements ToolEmbeddingStore.
+//
+// # Description
+//
+// Returns the local vectors and fills the tools the local store lacks from
+// the snapshot, copying those into the local store.
+func (s *SnapshotRouterCacheStore) LoadToolEmbeddings(ctx context.Context, docHashes map[string]string) (map[string][]float32, error) {
+	vectors := make(map[string][]float32, len(docHashes))
+	localTools, _ := s.local.(ToolEmbeddingStore)
+	if localTools != nil {
+		found, err := localTools.LoadToolEmbeddings(ctx, docHashes)
+		if err != nil {
+			s.logger.Warn("router cache: local tool load failed, trying snapshot", telemetry.SensitiveLogError("error", err))
+		}
+		for tool, vec := range found {
+			vectors[tool] = vec
+		}
+	}
+
+	snapshotTools, ok := s.snapshot.(ToolEmbeddingStore)
+	if !ok || len(vectors) == len(docHashes) {
+		return vectors, nil
+	}
+	missing := make(map[string]string, len(docHashes)-len(vectors))
+	for tool, docHash := range docHashes {
+		if _, ok := vectors[tool]; !ok {
+			missing[tool] = docHash
+		}
+	}
+	found, err := snapshotTools.LoadToolEmbeddings(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return vectors, nil
+	}
+	for tool, vec := range found {
+		vectors[tool] = vec
+	}
+	s.logger.Info("router cache: tools loaded from shared snapshot", slog.Int("tool_count", len(found)))
+	if localTools != nil {
+		if err := localTools.SaveToolEmbeddings(ctx, missing, found); err != nil {
+			s.logger.Warn("router cache: failed to copy snapshot tool entries", telemetry.SensitiveLogError("error", err))
+		}
+	}
+	return vectors, nil
+}
+
+// SaveToolEmbeddings implements ToolEmbeddingStore. Writes the local store
+// only.
+func (s *SnapshotRouterCacheStore) SaveToolEmbeddings(ctx context.Context, docHashes map[string]string, vectors map[string][]float32) error {
+	if localTools, ok := s.local.(ToolEmbeddingStore); ok {
+		return localTools.SaveToolEmbeddings(ctx, docHashes, vectors)
+	}
+	return nil
+}
+
+// ActiveCorpus implements ActiveCorpusStore. The active corpus is per
+// instance and kept in the local store only.
+func (s *SnapshotRouterCacheStore) ActiveCorpus(ctx context.Context) (ActiveCorpus, error) {
+	if active, ok := s.local.(ActiveCorpusStore); ok {
+		return active.ActiveCorpus(ctx)
+	}
+	return ActiveCorpus{}, nil
+}
+
+// ActivateCorpus implements ActiveCorpusStore. Only local entries are
+// deleted.
+func (s *SnapshotRouterCacheStore) ActivateCorpus(ctx context.Context, corpus ActiveCorpus) (int, error) {
+	if active, ok := s.local.(ActiveCorpusStore); ok {
+		return active.ActivateCorpus(ctx, corpus)
+	}
+	return 0, nil
+}
+
+// AppendFeedback implements RoutingFeedbackStore. Feedback is per instance
+// and kept in the local store only.
+func (s *SnapshotRouterCacheStore) AppendFeedback(ctx context.Context, rec RoutingFeedback) error {
+	if fb, ok := s.local.(RoutingFeedbackStore); ok {
+		return fb.AppendFeedback(ctx, rec)
+	}
+	return nil
+}
+
+// LoadFeedback implements RoutingFeedbackStore.
+func (s *SnapshotRouterCacheStore) LoadFeedback(ctx context.Context) ([]RoutingFeedback, error) {
+	if fb, ok := s.local.(RoutingFeedbackStore); ok {
+		return fb.LoadFeedback(ctx)
+	}
+	return nil, nil
+}
+
+// ResetFeedback implements RoutingFeedbackStore.
+func (s *SnapshotRouterCacheStore) ResetFeedback(ctx context.Context) (int, error) {
+	if fb, ok := s.local.(RoutingFeedbackStore); ok {
+		return fb.ResetFeedback(ctx)
+	}
+	return 0, nil
+}
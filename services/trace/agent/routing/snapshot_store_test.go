@@ -0,0 +1,83 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package routing
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSnapshotRouterCacheStore_FallsBackAndCopies(t *testing.T) {
+	ctx := context.Background()
+	model := EmbeddingModelIdentity{Name: "test-model"}
+	snapshot := NewBadgerRouterCacheStore(openTestDB(t), 0, model, nil)
+	local := NewBadgerRouterCacheStore(openTestDB(t), 0, model, nil)
+
+	vectors := makeTestVectors()
+	docHashes := map[string]string{"find_references": "h1", "find_symbol": "h2", "find_callers": "h3"}
+	if err := snapshot.SaveEmbeddings(ctx, "corpus", vectors); err != nil {
+		t.Fatalf("SaveEmbeddings: %v", err)
+	}
+	if err := snapshot.SaveToolEmbeddings(ctx, docHashes, vectors); err != nil {
+		t.Fatalf("SaveToolEmbeddings: %v", err)
+	}
+	// The local store already has one tool; the rest come from the snapshot.
+	if err := local.SaveToolEmbeddings(ctx, docHashes, map[string][]float32{"find_symbol": vectors["find_symbol"]}); err != nil {
+		t.Fatalf("SaveToolEmbeddings: %v", err)
+	}
+
+	store := NewSnapshotRouterCacheStore(local, snapshot, nil)
+	got, err := store.LoadEmbeddings(ctx, "corpus")
+	if err != nil || len(got) != len(vectors) {
+		t.Fatalf("LoadEmbeddings = %d vectors, %v; want %d", len(got), err, len(vectors))
+	}
+	if got, _ := local.LoadEmbeddings(ctx, "corpus"); len(got) != len(vectors) {
+		t.Errorf("corpus entry not copied to local store, got %d vectors", len(got))
+	}
+
+	tools, err := store.LoadToolEmbeddings(ctx, docHashes)
+	if err != nil || len(tools) != 3 {
+		t.Fatalf("LoadToolEmbeddings = %v, %v; want 3 tools", tools, err)
+	}
+	if got, _ := local.LoadToolEmbeddings(ctx, docHashes); len(got) != 3 {
+		t.Errorf("snapshot tools not copied to local store, got %v", got)
+	}
+}
+
+func TestSnapshotRouterCacheStore_WritesLocalOnly(t *testing.T) {
+	ctx := context.Background()
+	model := EmbeddingModelIdentity{Name: "test-model"}
+	snapshot := NewBadgerRouterCacheStore(openTestDB(t), 0, model, nil)
+	local := NewBadgerRouterCacheStore(openTestDB(t), 0, model, nil)
+	store := NewSnapshotRouterCacheStore(local, snapshot, nil)
+
+	if err := store.SaveEmbeddings(ctx, "corpus", makeTestVectors()); err != nil {
+		t.Fatalf("SaveEmbeddings: %v", err)
+	}
+	if got, _ := snapshot.LoadEmbeddings(ctx, "corpus"); got != nil {
+		t.Errorf("snapshot was written: %v", got)
+	}
+	if err := store.AppendFeedback(ctx, RoutingFeedback{Query: "q", Tool: "find_symbol", Model: "test-model", Vector: []float32{0.1, 0.2}}); err != nil {
+		t.Fatalf("AppendFeedback: %v", err)
+	}
+	if got, _ := snapshot.LoadFeedback(ctx); len(got) != 0 {
+		t.Errorf("snapshot received feedback: %v", got)
+	}
+
+	// Without a local store, writes are dropped and reads use the snapshot.
+	snapshotOnly := NewSnapshotRouterCacheStore(nil, local, nil)
+	if err := snapshotOnly.SaveEmbeddings(ctx, "other", makeTestVectors()); err != nil {
+		t.Errorf("SaveEmbeddings without local store: %v", err)
+	}
+	if got, _ := snapshotOnly.LoadEmbeddings(ctx, "corpus"); len(got) == 0 {
+		t.Error("expected snapshot-only store to read the snapshot")
+	}
+}
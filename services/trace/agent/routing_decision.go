@@ -0,0 +1,251 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"strings"
+	"time"
+)
+
+// MaxRoutingCandidates is the number of top-scored tools a RoutingDecision
+// keeps.
+const MaxRoutingCandidates = 5
+
+// maxRoutingListLen bounds RuleHits and Adjustments of a RoutingDecision.
+const maxRoutingListLen = 20
+
+// maxSessionRoutingDecisions bounds the decisions a session keeps.
+const maxSessionRoutingDecisions = 50
+
+// Routing decision sources: what made the final choice.
+const (
+	RoutingSourcePassthrough        = "passthrough"
+	RoutingSourcePrefilterForced    = "prefilter_forced"
+	RoutingSourcePrefilterTop       = "prefilter_top"
+	RoutingSourceRouter             = "router"
+	RoutingSourceSemanticCorrection = "semantic_correction"
+	RoutingSourceLowConfidence      = "low_confidence_synthesis"
+	RoutingSourceCircuitBreaker     = "circuit_breaker"
+	RoutingSourceSemanticRepetition = "semantic_repetition"
+	RoutingSourceUCB1               = "ucb1"
+	RoutingSourceError              = "error"
+)
+
+// RoutingDecision records why a tool was picked for a query.
+//
+// Description:
+//
+//	Built by the pre-filter (scores, rule hits, score adjustments) and
+//	completed by the execute phase (router pick, corrections, final
+//	choice). Sessions keep their decisions for the transcript, and each is
+//	emitted as a routing_decision event. The record is bounded: at most
+//	MaxRoutingCandidates candidates and 20 rule hits and adjustments.
+//
+// Thread Safety: Not safe for concurrent mutation; sessions store copies.
+type RoutingDecision struct {
+	// Query is the query as received.
+	Query string `json:"query"`
+
+	// NormalizedQuery is the query the pre-filter matched rules against.
+	NormalizedQuery string `json:"normalized_query"`
+
+	// Timestamp is when the decision was made (Unix milliseconds UTC).
+	Timestamp int64 `json:"timestamp"`
+
+	// ScoringMode is the pre-filter scoring mode ("embedding_primary" or
+	// "hybrid"). Empty when scoring did not run.
+	ScoringMode string `json:"scoring_mode,omitempty"`
+
+	// Candidates are the top-scored tools, best first.
+	Candidates []RoutingCandidate `json:"candidates,omitempty"`
+
+	// RuleHits lists the rules that fired, e.g. "forced_mapping:find_callers",
+	// "negation:find_callees" or "semantic_correction:find_callers".
+	RuleHits []string `json:"rule_hits,omitempty"`
+
+	// ForcedReason explains a forced selection.
+	ForcedReason string `json:"forced_reason,omitempty"`
+
+	// Adjustments are score changes after the base similarity: confusion
+	// pairs, encyclopedia boosts, routing feedback and the session
+	// penalty. Only adjustments of candidates and the final tool are kept.
+	Adjustments []RoutingAdjustment `json:"adjustments,omitempty"`
+
+	// OriginalCount and NarrowedCount are the tool counts before and after
+	// the pre-filter.
+	OriginalCount int `json:"original_count"`
+	NarrowedCount int `json:"narrowed_count"`
+
+	// RouterModel, RouterTool, RouterConfidence and RouterReasoning are
+	// the router's pick. Empty when the router did not run.
+	RouterModel      string  `json:"router_model,omitempty"`
+	RouterTool       string  `json:"router_tool,omitempty"`
+	RouterConfidence float64 `json:"router_confidence,omitempty"`
+	RouterReasoning  string  `json:"router_reasoning,omitempty"`
+
+	// FinalTool is the tool chosen.
+	FinalTool string `json:"final_tool"`
+
+	// Source is what made the final choice (RoutingSource* constants).
+	Source string `json:"source"`
+
+	// Margin is FinalTool's pre-filter score minus the best score of any
+	// other tool. Negative when a lower-scored tool was chosen; 0 without
+	// scores.
+	Margin float64 `json:"margin"`
+
+	// Scores are all pre-filter scores, used to compute Margin. Not
+	// serialized, to keep the record bounded.
+	Scores map[string]float64 `json:"-"`
+}
+
+// RoutingCandidate is one scored tool in a RoutingDecision.
+type RoutingCandidate struct {
+	// Tool is the tool name.
+	Tool string `json:"tool"`
+
+	// Score is the final pre-filter score.
+	Score float64 `json:"score"`
+
+	// BM25 is the lexical score of the tool's description.
+	BM25 float64 `json:"bm25"`
+
+	// Embedding is the cosine similarity of query and tool embeddings.
+	// 0 when embeddings are unavailable.
+	Embedding float64 `json:"embedding"`
+
+	// Narrowed is true if the tool was passed to the router.
+	Narrowed bool `json:"narrowed"`
+}
+
+// RoutingAdjustment is one score change applied to a tool.
+type RoutingAdjustment struct {
+	// Tool is the adjusted tool.
+	Tool string `json:"tool"`
+
+	// Rule is the adjustment's source: "confusion_pair",
+	// "encyclopedia_boost", "feedback" or "session_penalty".
+	Rule string `json:"rule"`
+
+	// Delta is the change in score.
+	Delta float64 `json:"delta"`
+}
+
+// NewRoutingDecision starts a decision for query over toolCount tools.
+func NewRoutingDecision(query string, toolCount int) *RoutingDecision {
+	return &RoutingDecision{
+		Query:           query,
+		NormalizedQuery: strings.ToLower(query),
+		Timestamp:       time.Now().UnixMilli(),
+		OriginalCount:   toolCount,
+		NarrowedCount:   toolCount,
+		Source:          RoutingSourcePassthrough,
+	}
+}
+
+// AddRuleHit records a fired rule, keeping at most 20.
+func (d *RoutingDecision) AddRuleHit(rule string) {
+	if len(d.RuleHits) < maxRoutingListLen {
+		d.RuleHits = append(d.RuleHits, rule)
+	}
+}
+
+// AddAdjustment records a score change. Zero changes are ignored.
+// Prune bounds the list once candidates are known.
+func (d *RoutingDecision) AddAdjustment(tool, rule string, delta float64) {
+	if delta == 0 {
+		return
+	}
+	d.Adjustments = append(d.Adjustments, RoutingAdjustment{Tool: tool, Rule: rule, Delta: delta})
+}
+
+// Prune drops adjustments of tools that are neither candidates nor the
+// final tool, and bounds RuleHits and Adjustments to 20 entries.
+func (d *RoutingDecision) Prune() {
+	keep := make(map[string]bool, len(d.Candidates)+1)
+	for _, c := range d.Candidates {
+		keep[c.Tool] = true
+	}
+	if d.FinalTool != "" {
+		keep[d.FinalTool] = true
+	}
+	kept := d.Adjustments[:0]
+	for _, a := range d.Adjustments {
+		if keep[a.Tool] && len(kept) < maxRoutingListLen {
+			kept = append(kept, a)
+		}
+	}
+	d.Adjustments = kept
+	if len(d.RuleHits) > maxRoutingListLen {
+		d.RuleHits = d.RuleHits[:maxRoutingListLen]
+	}
+}
+
+// SetFinal records the chosen tool and what chose it, and computes Margin.
+func (d *RoutingDecision) SetFinal(tool, source string) {
+	d.FinalTool = tool
+	d.Source = source
+	d.Margin = 0
+	if len(d.Scores) == 0 {
+		return
+	}
+	best, found := 0.0, false
+	for name, score := range d.Scores {
+		if name == tool {
+			continue
+		}
+		if !found || score > best {
+			best, found = score, true
+		}
+	}
+	d.Margin = d.Scores[tool] - best
+}
+
+// ApplyRouterSelection records the router's pick and makes it the final
+// choice.
+func (d *RoutingDecision) ApplyRouterSelection(model string, sel *ToolRouterSelection) {
+	if sel == nil {
+		return
+	}
+	d.RouterModel = model
+	d.RouterTool = sel.Tool
+	d.RouterConfidence = sel.Confidence
+	d.RouterReasoning = sel.Reasoning
+	d.SetFinal(sel.Tool, RoutingSourceRouter)
+}
+
+// RecordRoutingDecision stores a copy of a routing decision on the
+// session, keeping the 50 most recent.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) RecordRoutingDecision(d RoutingDecision) {
+	d.Scores = nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.routingDecisions = append(s.routingDecisions, d)
+	if len(s.routingDecisions) > maxSessionRoutingDecisions {
+		s.routingDecisions = s.routingDecisions[len(s.routingDecisions)-maxSessionRoutingDecisions:]
+	}
+}
+
+// RoutingDecisions returns the session's routing decisions, oldest first.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) RoutingDecisions() []RoutingDecision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.routingDecisions) == 0 {
+		return nil
+	}
+	out := make([]RoutingDecision, len(s.routingDecisions))
+	copy(out, s.routingDecisions)
+	return out
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRoutingDecision_SetFinalMargin(t *testing.T) {
+	d := NewRoutingDecision("Who calls parseConfig", 3)
+	d.Scores = map[string]float64{"find_callers": 0.8, "find_callees": 0.6, "find_references": 0.5}
+
+	d.SetFinal("find_callers", RoutingSourcePrefilterTop)
+	if d.Margin < 0.19 || d.Margin > 0.21 {
+		t.Errorf("Margin = %f, want 0.2", d.Margin)
+	}
+
+	d.ApplyRouterSelection("router-model", &ToolRouterSelection{Tool: "find_callees", Confidence: 0.7})
+	if d.FinalTool != "find_callees" || d.Source != RoutingSourceRouter {
+		t.Errorf("final = %s (%s), want find_callees (router)", d.FinalTool, d.Source)
+	}
+	if d.Margin > -0.19 || d.Margin < -0.21 {
+		t.Errorf("Margin = %f, want -0.2 for a lower-scored pick", d.Margin)
+	}
+}
+
+func TestRoutingDecision_Prune(t *testing.T) {
+	d := NewRoutingDecision("q", 10)
+	d.Candidates = []RoutingCandidate{{Tool: "a"}, {Tool: "b"}}
+	d.AddAdjustment("a", "feedback", 0.1)
+	d.AddAdjustment("c", "feedback", 0.1)
+	d.AddAdjustment("b", "feedback", 0)
+	for i := 0; i < 30; i++ {
+		d.AddAdjustment("b", "confusion_pair", 0.01)
+		d.AddRuleHit(fmt.Sprintf("rule:%d", i))
+	}
+	d.Prune()
+
+	if len(d.RuleHits) != maxRoutingListLen || len(d.Adjustments) != maxRoutingListLen {
+		t.Errorf("got %d rule hits and %d adjustments, want %d each", len(d.RuleHits), len(d.Adjustments), maxRoutingListLen)
+	}
+	for _, a := range d.Adjustments {
+		if a.Tool == "c" {
+			t.Errorf("kept adjustment of a non-candidate: %+v", a)
+		}
+	}
+}
+
+func TestSession_RoutingDecisionsInTranscript(t *testing.T) {
+	session, err := NewSession("/tmp/project", nil)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	for i := 0; i < maxSessionRoutingDecisions+5; i++ {
+		d := NewRoutingDecision(fmt.Sprintf("query %d", i), 3)
+		d.Scores = map[string]float64{"a": 1}
+		session.RecordRoutingDecision(*d)
+	}
+
+	tr := BuildTranscript(session, nil)
+	if len(tr.RoutingDecisions) != maxSessionRoutingDecisions {
+		t.Fatalf("transcript has %d decisions, want %d", len(tr.RoutingDecisions), maxSessionRoutingDecisions)
+	}
+	if tr.RoutingDecisions[0].Query != "query 5" {
+		t.Errorf("oldest kept = %q, want query 5", tr.RoutingDecisions[0].Query)
+	}
+	if tr.RoutingDecisions[0].Scores != nil {
+		t.Error("stored decision kept its full score map")
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package runqueue
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// runBuckets spans queue waits and agent runs, from instant to an hour.
+var runBuckets = []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1800, 3600}
+
+var (
+	// queueDepth is the number of runs waiting.
+	// Labels: priority
+	queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "trace",
+		Subsystem: "agent_queue",
+		Name:      "depth",
+		Help:      "Agent runs waiting for an execution slot by priority",
+	}, []string{"priority"})
+
+	// queueRunning is the number of runs holding a slot.
+	queueRunning = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "trace",
+		Subsystem: "agent_queue",
+		Name:      "running",
+		Help:      "Agent runs executing",
+	})
+
+	// queueWaitSeconds is the time runs waited for a slot.
+	// Labels: priority
+	queueWaitSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "trace",
+		Subsystem: "agent_queue",
+		Name:      "wait_seconds",
+		Help:      "Time agent runs waited for an execution slot by priority",
+		Buckets:   runBuckets,
+	}, []string{"priority"})
+
+	// runDurationSeconds is the time runs held a slot.
+	// Labels: priority
+	runDurationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "trace",
+		Subsystem: "agent_queue",
+		Name:      "run_duration_seconds",
+		Help:      "Duration of agent runs by priority",
+		Buckets:   runBuckets,
+	}, []string{"priority"})
+
+	// queueRejectedTotal counts runs refused because the queue was full.
+	// Labels: priority
+	queueRejectedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "trace",
+		Subsystem: "agent_queue",
+		Name:      "rejected_total",
+		Help:      "Agent runs refused because the queue was full by priority",
+	}, []string{"priority"})
+)
@@ -0,0 +1,557 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package runqueue admits agent runs to a fixed number of execution slots
+// by priority.
+//
+// Description:
+//
+//	A local model serves one run at a time well; several runs at once
+//	slow each other down. The queue caps concurrent runs, orders waiting
+//	runs by priority (interactive before ci) and FIFO within a priority,
+//	refuses new runs once too many are waiting, and reports each waiting
+//	run's position as it changes. Depth, wait time and run duration are
+//	exported per priority as Prometheus metrics.
+package runqueue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Priority is the scheduling class of a run.
+type Priority string
+
+const (
+	// PriorityInteractive is for runs a developer is waiting on.
+	PriorityInteractive Priority = "interactive"
+
+	// PriorityCI is for automated runs, which yield to interactive ones.
+	PriorityCI Priority = "ci"
+)
+
+// priorities lists every priority, highest first.
+var priorities = []Priority{PriorityInteractive, PriorityCI}
+
+// rank returns p's index in priorities, or -1 if p is unknown.
+func (p Priority) rank() int {
+	return slices.Index(priorities, p)
+}
+
+// ParsePriority validates a priority name. The empty name is returned
+// as is, meaning the caller's highest allowed priority.
+//
+// Outputs:
+//
+//	Priority - The priority.
+//	error - Wraps ErrUnknownPriority if name is not a priority.
+func ParsePriority(name string) (Priority, error) {
+	p := Priority(name)
+	if name != "" && p.rank() < 0 {
+		return "", fmt.Errorf("%w: %q (want %s or %s)", ErrUnknownPriority, name, PriorityInteractive, PriorityCI)
+	}
+	return p, nil
+}
+
+// ParseAllowedPriorities parses per-key priority grants of the form
+// "key_id=ci;other_key=interactive,ci" for Config.AllowedPriorities.
+//
+// Outputs:
+//
+//	map[string][]Priority - Allowed priorities by API key ID; nil for an
+//	  empty spec.
+//	error - Wraps ErrUnknownPriority for an unknown priority; describes
+//	  a malformed entry otherwise.
+func ParseAllowedPriorities(spec string) (map[string][]Priority, error) {
+	var allowed map[string][]Priority
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, list, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("priority grant %q: want key_id=priority[,priority]", entry)
+		}
+		var grants []Priority
+		for _, name := range strings.Split(list, ",") {
+			p, err := ParsePriority(strings.TrimSpace(name))
+			if err != nil {
+				return nil, fmt.Errorf("priority grant %q: %w", entry, err)
+			}
+			if p != "" {
+				grants = append(grants, p)
+			}
+		}
+		if allowed == nil {
+			allowed = make(map[string][]Priority)
+		}
+		allowed[key] = grants
+	}
+	return allowed, nil
+}
+
+const (
+	// DefaultMaxConcurrent is the number of runs executed at once: one,
+	// for a single-GPU model server.
+	DefaultMaxConcurrent = 1
+
+	// DefaultMaxDepth is the number of runs allowed to wait.
+	DefaultMaxDepth = 16
+
+	// DefaultRunEstimate is the run duration assumed for wait estimates
+	// until a run has completed.
+	DefaultRunEstimate = time.Minute
+
+	// runEstimateWeight is the weight of the latest run duration in the
+	// moving average used for wait estimates.
+	runEstimateWeight = 0.2
+)
+
+var (
+	// ErrUnknownPriority indicates a priority name that is not a Priority.
+	ErrUnknownPriority = errors.New("unknown run priority")
+
+	// ErrPriorityNotAllowed indicates a priority the API key may not use.
+	ErrPriorityNotAllowed = errors.New("run priority not allowed")
+
+	// ErrQueueFull indicates the queue already holds MaxDepth waiting runs.
+	ErrQueueFull = errors.New("run queue is full")
+
+	// ErrAlreadyQueued indicates a run ID that is already waiting or running.
+	ErrAlreadyQueued = errors.New("run already queued")
+
+	// ErrRemoved indicates a waiting run was removed before it started.
+	ErrRemoved = errors.New("queued run removed")
+)
+
+// Config configures a Queue.
+type Config struct {
+	// MaxConcurrent is the number of runs executed at once. <= 0 uses
+	// DefaultMaxConcurrent.
+	MaxConcurrent int
+
+	// MaxDepth is the number of runs allowed to wait; further runs are
+	// refused with ErrQueueFull. <= 0 uses DefaultMaxDepth.
+	MaxDepth int
+
+	// AllowedPriorities lists the priorities each API key ID may use.
+	// Keys not listed, and requests without a key, may use any priority.
+	AllowedPriorities map[string][]Priority
+
+	// RunEstimate is the run duration assumed for wait estimates until
+	// a run has completed. <= 0 uses DefaultRunEstimate.
+	RunEstimate time.Duration
+}
+
+// Position is a waiting run's place in the queue.
+type Position struct {
+	// Ahead is the number of waiting runs that will start first.
+	Ahead int `json:"runs_ahead"`
+
+	// Running is the number of runs executing.
+	Running int `json:"running"`
+
+	// EstimatedWaitMs estimates the time until the run starts, from the
+	// average duration of recent runs.
+	EstimatedWaitMs int64 `json:"estimated_wait_ms"`
+}
+
+// Status is a snapshot of the queue.
+type Status struct {
+	// MaxConcurrent is the number of runs executed at once.
+	MaxConcurrent int `json:"max_concurrent"`
+
+	// MaxDepth is the number of runs allowed to wait.
+	MaxDepth int `json:"max_depth"`
+
+	// Running is the number of runs executing.
+	Running int `json:"running"`
+
+	// Depth is the number of runs waiting.
+	Depth int `json:"depth"`
+
+	// Waiting is Depth by priority.
+	Waiting map[Priority]int `json:"waiting"`
+
+	// AverageRunMs is the moving average run duration used for estimates.
+	AverageRunMs int64 `json:"average_run_ms"`
+}
+
+// Queue admits runs to MaxConcurrent execution slots.
+//
+// Thread Safety: Safe for concurrent use.
+type Queue struct {
+	maxConcurrent int
+	maxDepth      int
+	allowed       map[string][]Priority
+
+	mu      sync.Mutex
+	running int
+	waiting [][]*Ticket // by priority rank, oldest first
+	byID    map[string]*Ticket
+	avgRun  time.Duration
+	now     func() time.Time
+}
+
+// New creates a queue.
+//
+// Inputs:
+//
+//	cfg - Limits and per-key priorities. Zero values use the defaults.
+//
+// Outputs:
+//
+//	*Queue - The queue. Never nil.
+func New(cfg Config) *Queue {
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = DefaultMaxConcurrent
+	}
+	if cfg.MaxDepth <= 0 {
+		cfg.MaxDepth = DefaultMaxDepth
+	}
+	if cfg.RunEstimate <= 0 {
+		cfg.RunEstimate = DefaultRunEstimate
+	}
+	return &Queue{
+		maxConcurrent: cfg.MaxConcurrent,
+		maxDepth:      cfg.MaxDepth,
+		allowed:       cfg.AllowedPriorities,
+		waiting:       make([][]*Ticket, len(priorities)),
+		byID:          make(map[string]*Ticket),
+		avgRun:        cfg.RunEstimate,
+		now:           time.Now,
+	}
+}
+
+// Resolve checks a requested priority against the API key's allowed
+// priorities.
+//
+// Inputs:
+//
+//	apiKeyID - The key ID of the request; "" for none.
+//	requested - The requested priority; "" for the key's highest allowed.
+//
+// Outputs:
+//
+//	Priority - The priority to enqueue with.
+//	error - Wraps ErrUnknownPriority or ErrPriorityNotAllowed.
+func (q *Queue) Resolve(apiKeyID string, requested Priority) (Priority, error) {
+	if _, err := ParsePriority(string(requested)); err != nil {
+		return "", err
+	}
+	allowed, limited := q.allowed[apiKeyID]
+	if !limited {
+		allowed = priorities
+	}
+	if requested == "" {
+		for _, p := range priorities {
+			if slices.Contains(allowed, p) {
+				return p, nil
+			}
+		}
+		return "", fmt.Errorf("%w: key has no allowed priorities", ErrPriorityNotAllowed)
+	}
+	if !slices.Contains(allowed, requested) {
+		return "", fmt.Errorf("%w: %s", ErrPriorityNotAllowed, requested)
+	}
+	return requested, nil
+}
+
+// Enqueue adds a run to the queue.
+//
+// Description:
+//
+//	The run starts at once if a slot is free and nothing is waiting;
+//	otherwise it waits behind the runs of its own and higher priorities.
+//	Call Ticket.Wait to wait for it to start and Ticket.Release when it
+//	finishes or is abandoned.
+//
+// Inputs:
+//
+//	id - Identifies the run for Remove, e.g. its session ID.
+//	p - The run's priority (see Resolve). Must be known.
+//
+// Outputs:
+//
+//	*Ticket - The run's place in the queue.
+//	error - Wraps ErrQueueFull, ErrAlreadyQueued or ErrUnknownPriority.
+func (q *Queue) Enqueue(id string, p Priority) (*Ticket, error) {
+	rank := p.rank()
+	if rank < 0 {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownPriority, p)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.byID[id]; exists {
+		return nil, fmt.Errorf("%w: %s", ErrAlreadyQueued, id)
+	}
+	t := &Ticket{
+		q:        q,
+		id:       id,
+		priority: p,
+		enqueued: q.now(),
+		started:  make(chan struct{}),
+		removed:  make(chan struct{}),
+		updates:  make(chan Position, 1),
+	}
+	if q.running < q.maxConcurrent && q.depthLocked() == 0 {
+		q.byID[id] = t
+		q.startLocked(t)
+		return t, nil
+	}
+	if q.depthLocked() >= q.maxDepth {
+		queueRejectedTotal.WithLabelValues(string(p)).Inc()
+		return nil, fmt.Errorf("%w: %d runs waiting", ErrQueueFull, q.depthLocked())
+	}
+	q.byID[id] = t
+	q.waiting[rank] = append(q.waiting[rank], t)
+	queueDepth.WithLabelValues(string(p)).Inc()
+	q.publishLocked()
+	return t, nil
+}
+
+// Remove removes a waiting run so it never starts. Its Wait returns
+// ErrRemoved.
+//
+// Outputs:
+//
+//	bool - False if no run with id is waiting (unknown or already started).
+func (q *Queue) Remove(id string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	t, ok := q.byID[id]
+	if !ok || t.state != ticketWaiting {
+		return false
+	}
+	q.dropLocked(t)
+	close(t.removed)
+	q.publishLocked()
+	return true
+}
+
+// Status returns a snapshot of the queue.
+func (q *Queue) Status() Status {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	st := Status{
+		MaxConcurrent: q.maxConcurrent,
+		MaxDepth:      q.maxDepth,
+		Running:       q.running,
+		Depth:         q.depthLocked(),
+		Waiting:       make(map[Priority]int, len(priorities)),
+		AverageRunMs:  q.avgRun.Milliseconds(),
+	}
+	for rank, p := range priorities {
+		st.Waiting[p] = len(q.waiting[rank])
+	}
+	return st
+}
+
+// EstimateWait estimates how long a run enqueued now at priority p would
+// wait to start.
+func (q *Queue) EstimateWait(p Priority) time.Duration {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	ahead := 0
+	for rank := 0; rank < len(priorities) && rank <= p.rank(); rank++ {
+		ahead += len(q.waiting[rank])
+	}
+	return q.estimateLocked(ahead)
+}
+
+// depthLocked returns the number of waiting runs. Caller holds q.mu.
+func (q *Queue) depthLocked() int {
+	n := 0
+	for _, w := range q.waiting {
+		n += len(w)
+	}
+	return n
+}
+
+// estimateLocked estimates the wait of a run with ahead runs before it:
+// the slots that must free up, at one per average run per slot.
+func (q *Queue) estimateLocked(ahead int) time.Duration {
+	needed := ahead + 1 - (q.maxConcurrent - q.running)
+	if needed <= 0 {
+		return 0
+	}
+	return q.avgRun * time.Duration(needed) / time.Duration(q.maxConcurrent)
+}
+
+// startLocked gives t a slot. Caller holds q.mu.
+func (q *Queue) startLocked(t *Ticket) {
+	t.state = ticketRunning
+	t.startedAt = q.now()
+	q.running++
+	queueRunning.Inc()
+	queueWaitSeconds.WithLabelValues(string(t.priority)).Observe(t.startedAt.Sub(t.enqueued).Seconds())
+	close(t.started)
+}
+
+// dropLocked removes a waiting t from the queue. Caller holds q.mu.
+func (q *Queue) dropLocked(t *Ticket) {
+	rank := t.priority.rank()
+	q.waiting[rank] = slices.DeleteFunc(q.waiting[rank], func(w *Ticket) bool { return w == t })
+	delete(q.byID, t.id)
+	t.state = ticketDone
+	queueDepth.WithLabelValues(string(t.priority)).Dec()
+}
+
+// admitLocked starts waiting runs, highest priority and oldest first,
+// while slots are free, then publishes the new positions. Caller holds q.mu.
+func (q *Queue) admitLocked() {
+	for q.running < q.maxConcurrent {
+		var next *Ticket
+		for rank := range q.waiting {
+			if len(q.waiting[rank]) > 0 {
+				next = q.waiting[rank][0]
+				q.waiting[rank] = q.waiting[rank][1:]
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		queueDepth.WithLabelValues(string(next.priority)).Dec()
+		q.startLocked(next)
+	}
+	q.publishLocked()
+}
+
+// publishLocked sends every waiting run its current position, replacing
+// any position it has not read yet. Caller holds q.mu.
+func (q *Queue) publishLocked() {
+	ahead := 0
+	for _, w := range q.waiting {
+		for _, t := range w {
+			pos := Position{Ahead: ahead, Running: q.running, EstimatedWaitMs: q.estimateLocked(ahead).Milliseconds()}
+			select {
+			case <-t.updates:
+			default:
+			}
+			t.updates <- pos
+			ahead++
+		}
+	}
+}
+
+// finishLocked frees a running t's slot and records its duration.
+// Caller holds q.mu.
+func (q *Queue) finishLocked(t *Ticket) {
+	d := q.now().Sub(t.startedAt)
+	t.state = ticketDone
+	delete(q.byID, t.id)
+	q.running--
+	queueRunning.Dec()
+	runDurationSeconds.WithLabelValues(string(t.priority)).Observe(d.Seconds())
+	q.avgRun = time.Duration((1-runEstimateWeight)*float64(q.avgRun) + runEstimateWeight*float64(d))
+	q.admitLocked()
+}
+
+// ticketState is the lifecycle state of a Ticket.
+type ticketState int
+
+const (
+	ticketWaiting ticketState = iota
+	ticketRunning
+	ticketDone
+)
+
+// Ticket is one run's place in a Queue.
+//
+// Thread Safety: Safe for concurrent use.
+type Ticket struct {
+	q        *Queue
+	id       string
+	priority Priority
+	enqueued time.Time
+
+	// Guarded by q.mu.
+	state     ticketState
+	startedAt time.Time
+
+	started chan struct{} // closed when the run gets a slot
+	removed chan struct{} // closed by Queue.Remove
+	updates chan Position // latest unread position
+}
+
+// Priority returns the run's priority.
+func (t *Ticket) Priority() Priority { return t.priority }
+
+// Wait blocks until the run gets a slot.
+//
+// Inputs:
+//
+//	ctx - Cancelling it while waiting takes the run out of the queue.
+//	onPosition - Called with each new position while waiting; nil to
+//	  ignore. The first call, if any, reports the starting position.
+//
+// Outputs:
+//
+//	error - nil once the run may start; ErrRemoved if it was removed,
+//	  or ctx.Err().
+func (t *Ticket) Wait(ctx context.Context, onPosition func(Position)) error {
+	for {
+		select {
+		case <-t.started:
+			return nil
+		case <-t.removed:
+			return ErrRemoved
+		case <-ctx.Done():
+			t.Release()
+			return ctx.Err()
+		case pos := <-t.updates:
+			// A start or removal published alongside the position wins.
+			select {
+			case <-t.started:
+				return nil
+			case <-t.removed:
+				return ErrRemoved
+			default:
+			}
+			if onPosition != nil {
+				onPosition(pos)
+			}
+		}
+	}
+}
+
+// Waited returns how long the run waited for its slot, or how long it has
+// been waiting so far.
+func (t *Ticket) Waited() time.Duration {
+	t.q.mu.Lock()
+	defer t.q.mu.Unlock()
+	if t.startedAt.IsZero() {
+		return t.q.now().Sub(t.enqueued)
+	}
+	return t.startedAt.Sub(t.enqueued)
+}
+
+// Release ends the run: a running run frees its slot for the next one,
+// a waiting run leaves the queue. Safe to call more than once.
+func (t *Ticket) Release() {
+	q := t.q
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	switch t.state {
+	case ticketRunning:
+		q.finishLocked(t)
+	case ticketWaiting:
+		q.dropLocked(t)
+		q.publishLocked()
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package runqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustEnqueue(t *testing.T, q *Queue, id string, p Priority) *Ticket {
+	t.Helper()
+	ticket, err := q.Enqueue(id, p)
+	if err != nil {
+		t.Fatalf("Enqueue(%s): %v", id, err)
+	}
+	return ticket
+}
+
+func isStarted(t *Ticket) bool {
+	select {
+	case <-t.started:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestQueue_PriorityThenFIFO(t *testing.T) {
+	q := New(Config{})
+	first := mustEnqueue(t, q, "first", PriorityCI)
+	if !isStarted(first) {
+		t.Fatal("run did not start on an idle queue")
+	}
+	ci1 := mustEnqueue(t, q, "ci1", PriorityCI)
+	ci2 := mustEnqueue(t, q, "ci2", PriorityCI)
+	dev1 := mustEnqueue(t, q, "dev1", PriorityInteractive)
+	dev2 := mustEnqueue(t, q, "dev2", PriorityInteractive)
+
+	st := q.Status()
+	if st.Running != 1 || st.Depth != 4 || st.Waiting[PriorityInteractive] != 2 || st.Waiting[PriorityCI] != 2 {
+		t.Fatalf("status = %+v", st)
+	}
+
+	running := first
+	for _, next := range []*Ticket{dev1, dev2, ci1, ci2} {
+		running.Release()
+		if !isStarted(next) {
+			t.Fatalf("after %s finished, %s did not start", running.id, next.id)
+		}
+		running = next
+	}
+	if st := q.Status(); st.Running != 1 || st.Depth != 0 {
+		t.Errorf("status = %+v", st)
+	}
+}
+
+func TestQueue_Positions(t *testing.T) {
+	q := New(Config{RunEstimate: 10 * time.Second})
+	mustEnqueue(t, q, "running", PriorityInteractive)
+	ci := mustEnqueue(t, q, "ci", PriorityCI)
+	if pos := <-ci.updates; pos.Ahead != 0 || pos.Running != 1 || pos.EstimatedWaitMs != 10_000 {
+		t.Errorf("first position = %+v", pos)
+	}
+	mustEnqueue(t, q, "dev", PriorityInteractive)
+	if pos := <-ci.updates; pos.Ahead != 1 || pos.EstimatedWaitMs != 20_000 {
+		t.Errorf("position after interactive arrival = %+v", pos)
+	}
+	if got := q.EstimateWait(PriorityCI); got != 30*time.Second {
+		t.Errorf("EstimateWait(ci) = %v", got)
+	}
+	if got := q.EstimateWait(PriorityInteractive); got != 20*time.Second {
+		t.Errorf("EstimateWait(interactive) = %v", got)
+	}
+}
+
+func TestQueue_DepthLimit(t *testing.T) {
+	q := New(Config{MaxDepth: 1})
+	mustEnqueue(t, q, "a", PriorityInteractive)
+	mustEnqueue(t, q, "b", PriorityInteractive)
+	if _, err := q.Enqueue("c", PriorityInteractive); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("err = %v, want ErrQueueFull", err)
+	}
+	if _, err := q.Enqueue("a", PriorityCI); !errors.Is(err, ErrAlreadyQueued) {
+		t.Errorf("err = %v, want ErrAlreadyQueued", err)
+	}
+}
+
+func TestQueue_RemoveAndCancel(t *testing.T) {
+	q := New(Config{})
+	running := mustEnqueue(t, q, "running", PriorityInteractive)
+	removed := mustEnqueue(t, q, "removed", PriorityInteractive)
+	cancelled := mustEnqueue(t, q, "cancelled", PriorityInteractive)
+	last := mustEnqueue(t, q, "last", PriorityCI)
+
+	if q.Remove("running") {
+		t.Error("removed a running run")
+	}
+	if !q.Remove("removed") {
+		t.Fatal("Remove of a waiting run failed")
+	}
+	if err := removed.Wait(context.Background(), nil); !errors.Is(err, ErrRemoved) {
+		t.Errorf("Wait after Remove = %v, want ErrRemoved", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := cancelled.Wait(ctx, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait with cancelled ctx = %v", err)
+	}
+
+	running.Release()
+	var positions []Position
+	if err := last.Wait(context.Background(), func(p Position) { positions = append(positions, p) }); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if isStarted(removed) || isStarted(cancelled) {
+		t.Error("a removed run started")
+	}
+	if st := q.Status(); st.Running != 1 || st.Depth != 0 {
+		t.Errorf("status = %+v", st)
+	}
+	last.Release()
+	last.Release()
+	if st := q.Status(); st.Running != 0 {
+		t.Errorf("running after double release = %d", st.Running)
+	}
+}
+
+func TestQueue_Resolve(t *testing.T) {
+	q := New(Config{AllowedPriorities: map[string][]Priority{"ci-bot": {PriorityCI}}})
+	tests := []struct {
+		key       string
+		requested Priority
+		want      Priority
+		wantErr   error
+	}{
+		{"", "", PriorityInteractive, nil},
+		{"dev", PriorityCI, PriorityCI, nil},
+		{"ci-bot", "", PriorityCI, nil},
+		{"ci-bot", PriorityInteractive, "", ErrPriorityNotAllowed},
+		{"dev", "urgent", "", ErrUnknownPriority},
+	}
+	for _, tt := range tests {
+		got, err := q.Resolve(tt.key, tt.requested)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("Resolve(%q, %q) = %q, %v; want %q, %v", tt.key, tt.requested, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseAllowedPriorities(t *testing.T) {
+	got, err := ParseAllowedPriorities(" ci-bot = ci ; admin=interactive,ci;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || len(got["ci-bot"]) != 1 || got["ci-bot"][0] != PriorityCI || len(got["admin"]) != 2 {
+		t.Errorf("got %v", got)
+	}
+	if got, err := ParseAllowedPriorities(""); err != nil || got != nil {
+		t.Errorf("empty spec = %v, %v", got, err)
+	}
+	if _, err := ParseAllowedPriorities("bot=urgent"); !errors.Is(err, ErrUnknownPriority) {
+		t.Errorf("unknown priority: err = %v", err)
+	}
+	if _, err := ParseAllowedPriorities("bot"); err == nil {
+		t.Error("entry without = accepted")
+	}
+}
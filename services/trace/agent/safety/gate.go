@@ -236,6 +236,7 @@ type DefaultGate struct {
 	mu       sync.RWMutex
 	config   GateConfig
 	checkers []Checker
+	policy   *Policy
 }
 
 // NewDefaultGate creates a new safety gate with the provided config.
@@ -0,0 +1,769 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package safety
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PolicyEnvVar names the environment variable holding the policy file path.
+const PolicyEnvVar = "TRACE_SAFETY_POLICY"
+
+// DefaultRunMode is the run mode whose tool rules apply when a run names
+// no mode, or a mode the policy does not define.
+const DefaultRunMode = "default"
+
+// PlanToolName is the tool name under which coordinate-phase change plans
+// are evaluated against the blast radius limits.
+const PlanToolName = "plan_changes"
+
+// ApplyPlanToolName is the tool name under which applying an approved
+// change plan is evaluated. The plan's files are checked against the path
+// rules.
+const ApplyPlanToolName = "apply_plan"
+
+// Decisions returned by Evaluate.
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+)
+
+// Rule IDs that do not depend on the position of a pattern in the policy.
+const (
+	// RuleDefaultAllow is reported when no rule matched and the call is allowed.
+	RuleDefaultAllow = "default.allow"
+
+	// RuleNetworkDeny is reported for network-reaching tools when the
+	// policy does not permit network access.
+	RuleNetworkDeny = "network.deny"
+
+	// RuleBlastRadiusFiles is reported when a plan touches too many files.
+	RuleBlastRadiusFiles = "blast_radius.max_files"
+
+	// RuleBlastRadiusSymbols is reported when a plan modifies too many symbols.
+	RuleBlastRadiusSymbols = "blast_radius.max_symbols"
+
+	// RuleApprovalRequired is reported when the policy requires approval
+	// for every plan.
+	RuleApprovalRequired = "approval.required"
+
+	// RuleApprovalMinFiles is reported when a plan touches enough files to
+	// need approval.
+	RuleApprovalMinFiles = "approval.min_files"
+
+	// RuleApprovalMinSymbols is reported when a plan modifies enough
+	// symbols to need approval.
+	RuleApprovalMinSymbols = "approval.min_symbols"
+)
+
+// ErrInvalidPolicy indicates a policy file that cannot be used.
+var ErrInvalidPolicy = errors.New("invalid safety policy")
+
+// Policy is the declarative safety policy evaluated by DefaultGate.
+//
+// Description:
+//
+//	Loaded from YAML or JSON (JSON is valid YAML). Example:
+//
+//	  tools:
+//	    default:
+//	      deny: [write_file, edit_file, delete_file, run_command]
+//	    edit:
+//	      allow: ["*"]
+//	  paths:
+//	    deny: ["**/.env", "**/secrets/**"]
+//	  blast_radius:
+//	    max_files: 20
+//	    max_symbols: 50
+//	  approval:
+//	    min_files: 3
+//	  network:
+//	    allow: false
+//
+//	Rule IDs name the rule that decided a call, e.g. "tools.default.deny[2]",
+//	"paths.deny[0]", "blast_radius.max_files" or "gate.BLOCKED_PATH" for
+//	issues raised by the gate's checkers.
+type Policy struct {
+	// Tools holds tool allow/deny rules keyed by run mode. The
+	// DefaultRunMode entry applies to runs with an unknown or empty mode.
+	Tools map[string]ToolRules `yaml:"tools,omitempty" json:"tools,omitempty"`
+
+	// Paths restricts the paths any tool may read or write.
+	Paths PathRules `yaml:"paths,omitempty" json:"paths,omitempty"`
+
+	// BlastRadius bounds coordinate-phase change plans.
+	BlastRadius BlastRadiusLimits `yaml:"blast_radius,omitempty" json:"blast_radius,omitempty"`
+
+	// Approval selects the change plans that need human approval before
+	// anything acts on them.
+	Approval ApprovalRules `yaml:"approval,omitempty" json:"approval,omitempty"`
+
+	// Network controls tools that reach external services.
+	Network NetworkRules `yaml:"network,omitempty" json:"network,omitempty"`
+
+	// Gate overrides DefaultGateConfig for the gate's checkers. Nil keeps
+	// the defaults.
+	Gate *GatePolicy `yaml:"gate,omitempty" json:"gate,omitempty"`
+}
+
+// ToolRules are the tool name globs allowed and denied in one run mode.
+//
+// Deny rules are checked first. A non-empty Allow list denies every tool
+// it does not match.
+type ToolRules struct {
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty" json:"deny,omitempty"`
+}
+
+// PathRules are path globs allowed and denied for tool path parameters.
+//
+// Globs use "/" separators; "*" matches within one path segment and "**"
+// matches any number of segments. Deny rules are checked first. A
+// non-empty Allow list denies every path it does not match.
+type PathRules struct {
+	Allow []string `yaml:"allow,omitempty" json:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty" json:"deny,omitempty"`
+}
+
+// BlastRadiusLimits bound a change plan. Zero means unlimited.
+type BlastRadiusLimits struct {
+	// MaxFiles is the maximum number of files a plan may touch.
+	MaxFiles int `yaml:"max_files,omitempty" json:"max_files,omitempty"`
+
+	// MaxSymbols is the maximum number of symbols a plan may modify.
+	MaxSymbols int `yaml:"max_symbols,omitempty" json:"max_symbols,omitempty"`
+}
+
+// ApprovalRules select the change plans that need human approval. A plan
+// needs approval if any rule matches. Zero values match nothing.
+type ApprovalRules struct {
+	// Required requires approval for every plan.
+	Required bool `yaml:"required,omitempty" json:"required,omitempty"`
+
+	// MinFiles requires approval for plans touching at least this many files.
+	MinFiles int `yaml:"min_files,omitempty" json:"min_files,omitempty"`
+
+	// MinSymbols requires approval for plans modifying at least this many
+	// symbols.
+	MinSymbols int `yaml:"min_symbols,omitempty" json:"min_symbols,omitempty"`
+}
+
+// NetworkRules control network-reaching tools.
+type NetworkRules struct {
+	// Allow permits network-reaching tools. Nil means true.
+	Allow *bool `yaml:"allow,omitempty" json:"allow,omitempty"`
+
+	// Tools lists the network-reaching tools. Empty uses DefaultNetworkTools.
+	Tools []string `yaml:"tools,omitempty" json:"tools,omitempty"`
+}
+
+// GatePolicy overrides fields of GateConfig. Nil fields keep the default.
+type GatePolicy struct {
+	BlockOnWarning      *bool    `yaml:"block_on_warning,omitempty" json:"block_on_warning,omitempty"`
+	BlockedPaths        []string `yaml:"blocked_paths,omitempty" json:"blocked_paths,omitempty"`
+	BlockedCommands     []string `yaml:"blocked_commands,omitempty" json:"blocked_commands,omitempty"`
+	MaxFileSize         *int64   `yaml:"max_file_size,omitempty" json:"max_file_size,omitempty"`
+	AllowPackageInstall *bool    `yaml:"allow_package_install,omitempty" json:"allow_package_install,omitempty"`
+}
+
+// DefaultNetworkTools are the tools that call external services: the
+// semantic search tools query Weaviate and the embedding service.
+var DefaultNetworkTools = []string{"semantic_search", "find_similar_symbols"}
+
+// pathParams are the tool parameters treated as file system paths.
+var pathParams = []string{"path", "file_path", "filepath", "file", "directory", "dir", "paths", "files"}
+
+// DefaultPolicy returns the policy used when none is configured: every
+// tool, path and plan size is allowed, and network tools are permitted.
+// The gate's checkers still apply.
+func DefaultPolicy() *Policy {
+	return &Policy{}
+}
+
+// ParsePolicy parses a YAML or JSON policy.
+//
+// Outputs:
+//
+//	*Policy - The parsed policy.
+//	error - ErrInvalidPolicy if the document is malformed or a glob is invalid.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p Policy
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&p); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPolicy, err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// LoadPolicy reads and parses the policy file at path.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read safety policy: %w", err)
+	}
+	p, err := ParsePolicy(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return p, nil
+}
+
+// LoadPolicyFromEnv loads the policy named by TRACE_SAFETY_POLICY.
+//
+// Outputs:
+//
+//	*Policy - The loaded policy, or DefaultPolicy() when the variable is unset.
+//	string - The file path, empty when unset.
+//	error - Non-nil if the file cannot be read or parsed.
+func LoadPolicyFromEnv() (*Policy, string, error) {
+	path := os.Getenv(PolicyEnvVar)
+	if path == "" {
+		return DefaultPolicy(), "", nil
+	}
+	p, err := LoadPolicy(path)
+	return p, path, err
+}
+
+// Validate checks that every glob in the policy is well formed and limits
+// are not negative.
+func (p *Policy) Validate() error {
+	check := func(field string, globs []string) error {
+		for i, g := range globs {
+			if _, err := path.Match(strings.ReplaceAll(g, "**", "*"), ""); err != nil {
+				return fmt.Errorf("%w: %s[%d] %q: %v", ErrInvalidPolicy, field, i, g, err)
+			}
+		}
+		return nil
+	}
+	for mode, rules := range p.Tools {
+		if err := check("tools."+mode+".allow", rules.Allow); err != nil {
+			return err
+		}
+		if err := check("tools."+mode+".deny", rules.Deny); err != nil {
+			return err
+		}
+	}
+	if err := check("paths.allow", p.Paths.Allow); err != nil {
+		return err
+	}
+	if err := check("paths.deny", p.Paths.Deny); err != nil {
+		return err
+	}
+	if p.BlastRadius.MaxFiles < 0 || p.BlastRadius.MaxSymbols < 0 {
+		return fmt.Errorf("%w: blast_radius limits must not be negative", ErrInvalidPolicy)
+	}
+	if p.Approval.MinFiles < 0 || p.Approval.MinSymbols < 0 {
+		return fmt.Errorf("%w: approval thresholds must not be negative", ErrInvalidPolicy)
+	}
+	return nil
+}
+
+// GateConfig returns DefaultGateConfig with the policy's gate overrides.
+func (p *Policy) GateConfig() GateConfig {
+	cfg := DefaultGateConfig()
+	if p == nil || p.Gate == nil {
+		return cfg
+	}
+	g := p.Gate
+	if g.BlockOnWarning != nil {
+		cfg.BlockOnWarning = *g.BlockOnWarning
+	}
+	if g.BlockedPaths != nil {
+		cfg.BlockedPaths = g.BlockedPaths
+	}
+	if g.BlockedCommands != nil {
+		cfg.BlockedCommands = g.BlockedCommands
+	}
+	if g.MaxFileSize != nil {
+		cfg.MaxFileSize = *g.MaxFileSize
+	}
+	if g.AllowPackageInstall != nil {
+		cfg.AllowPackageInstall = *g.AllowPackageInstall
+	}
+	return cfg
+}
+
+// Modes returns the run modes the policy defines, sorted.
+func (p *Policy) Modes() []string {
+	modes := make([]string, 0, len(p.Tools))
+	for mode := range p.Tools {
+		modes = append(modes, mode)
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+// ToolCall is a proposed tool call submitted for evaluation.
+type ToolCall struct {
+	// Tool is the tool name. Required.
+	Tool string `json:"tool"`
+
+	// Mode is the run mode; empty uses DefaultRunMode.
+	Mode string `json:"mode,omitempty"`
+
+	// Params are the tool parameters. Path parameters are checked against
+	// the path rules; write, delete and command tools are also run through
+	// the gate's checkers.
+	Params map[string]any `json:"params,omitempty"`
+
+	// Plan is the size of a proposed change plan, checked against the
+	// blast radius limits.
+	Plan *PlanImpact `json:"plan,omitempty"`
+}
+
+// PlanImpact is the size of a coordinate-phase change plan.
+type PlanImpact struct {
+	// FilesTouched is the number of files the plan changes.
+	FilesTouched int `json:"files_touched"`
+
+	// SymbolsModified is the number of symbols the plan changes.
+	SymbolsModified int `json:"symbols_modified"`
+}
+
+// RuleMatch is one rule that matched during evaluation.
+type RuleMatch struct {
+	// RuleID identifies the rule.
+	RuleID string `json:"rule_id"`
+
+	// Decision is DecisionAllow or DecisionDeny.
+	Decision string `json:"decision"`
+
+	// Reason explains the match.
+	Reason string `json:"reason"`
+}
+
+// Decision is the outcome of evaluating a ToolCall.
+type Decision struct {
+	// Decision is DecisionAllow or DecisionDeny.
+	Decision string `json:"decision"`
+
+	// Allowed is true when Decision is DecisionAllow.
+	Allowed bool `json:"allowed"`
+
+	// RuleID is the rule that decided the call: the first deny, otherwise
+	// the first allow, otherwise RuleDefaultAllow.
+	RuleID string `json:"rule_id"`
+
+	// Reason explains the decision.
+	Reason string `json:"reason"`
+
+	// Tool and Mode echo the evaluated call; Mode is the mode whose tool
+	// rules applied.
+	Tool string `json:"tool"`
+	Mode string `json:"mode"`
+
+	// Matches lists every rule that matched, in evaluation order.
+	Matches []RuleMatch `json:"matches,omitempty"`
+
+	// Issues are the gate checker issues for the call, if any.
+	Issues []Issue `json:"issues,omitempty"`
+
+	// ApprovalRequired is true when the call carries a plan that the
+	// approval rules select. An allowed plan that requires approval must
+	// not be acted on until a human approves it.
+	ApprovalRequired bool `json:"approval_required,omitempty"`
+
+	// ApprovalRuleID and ApprovalReason name the approval rule that
+	// matched, when ApprovalRequired is true.
+	ApprovalRuleID string `json:"approval_rule_id,omitempty"`
+	ApprovalReason string `json:"approval_reason,omitempty"`
+}
+
+// Evaluator explains how a gate would treat a tool call, without
+// executing it. *DefaultGate implements it.
+type Evaluator interface {
+	Evaluate(ctx context.Context, call ToolCall) (*Decision, error)
+}
+
+// SetPolicy replaces the gate's policy. Nil restores DefaultPolicy().
+//
+// Thread Safety: Safe for concurrent use.
+func (g *DefaultGate) SetPolicy(policy *Policy) {
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.policy = policy
+}
+
+// Policy returns the gate's policy.
+//
+// Thread Safety: Safe for concurrent use.
+func (g *DefaultGate) Policy() *Policy {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if g.policy == nil {
+		return DefaultPolicy()
+	}
+	return g.policy
+}
+
+// NewPolicyGate creates a DefaultGate configured by policy.
+//
+// Inputs:
+//
+//	policy - The policy. Nil uses DefaultPolicy().
+//
+// Outputs:
+//
+//	*DefaultGate - A gate whose checkers use policy.GateConfig() and whose
+//	Evaluate applies policy.
+func NewPolicyGate(policy *Policy) *DefaultGate {
+	if policy == nil {
+		policy = DefaultPolicy()
+	}
+	cfg := policy.GateConfig()
+	gate := NewDefaultGate(&cfg)
+	gate.SetPolicy(policy)
+	return gate
+}
+
+// Evaluate decides whether call is permitted, without executing it.
+//
+// Description:
+//
+//	Applies, in order: the tool rules of the call's run mode, the network
+//	rules, the path rules for every path parameter, the blast radius
+//	limits for a plan, and the gate's checkers for write, delete and
+//	command tools. The call is denied if any rule denies it; the first
+//	deny is reported as the deciding rule. A disabled gate allows every
+//	call under RuleDefaultAllow.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	call - The proposed call. Tool is required.
+//
+// Outputs:
+//
+//	*Decision - The decision with the matched rules.
+//	error - Non-nil if Tool is empty or ctx is done.
+//
+// Thread Safety: Safe for concurrent use.
+func (g *DefaultGate) Evaluate(ctx context.Context, call ToolCall) (*Decision, error) {
+	if call.Tool == "" {
+		return nil, errors.New("evaluate: tool is required")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	policy := g.Policy()
+	g.mu.RLock()
+	enabled, blockOnWarning := g.config.Enabled, g.config.BlockOnWarning
+	g.mu.RUnlock()
+
+	mode, rules := policy.toolRules(call.Mode)
+	d := &Decision{Tool: call.Tool, Mode: mode}
+	if !enabled {
+		d.finish()
+		return d, nil
+	}
+
+	d.Matches = append(d.Matches, matchToolRules(mode, rules, call.Tool)...)
+	d.Matches = append(d.Matches, policy.matchNetwork(call.Tool)...)
+	for _, p := range pathsFromParams(call.Params) {
+		d.Matches = append(d.Matches, policy.Paths.match(p)...)
+	}
+	if call.Plan != nil {
+		d.Matches = append(d.Matches, policy.BlastRadius.match(*call.Plan)...)
+		if m := policy.Approval.match(*call.Plan); m != nil {
+			d.ApprovalRequired = true
+			d.ApprovalRuleID, d.ApprovalReason = m.RuleID, m.Reason
+		}
+	}
+
+	if change := ChangeForTool(call.Tool, call.Params); change != nil {
+		result, err := g.Check(ctx, []ProposedChange{*change})
+		if err != nil {
+			return nil, err
+		}
+		d.Issues = result.Issues
+		blocking := g.ShouldBlock(result)
+		for _, issue := range result.Issues {
+			decision := DecisionAllow
+			if blocking && (issue.Severity == SeverityCritical || (issue.Severity == SeverityWarning && blockOnWarning)) {
+				decision = DecisionDeny
+			}
+			d.Matches = append(d.Matches, RuleMatch{
+				RuleID:   "gate." + issue.Code,
+				Decision: decision,
+				Reason:   issue.Message,
+			})
+		}
+	}
+
+	d.finish()
+	return d, nil
+}
+
+// finish sets the decision from the matches.
+func (d *Decision) finish() {
+	d.Decision, d.Allowed = DecisionAllow, true
+	d.RuleID, d.Reason = RuleDefaultAllow, "no rule denies this call"
+	var firstAllow *RuleMatch
+	for i := range d.Matches {
+		m := &d.Matches[i]
+		if m.Decision == DecisionDeny {
+			d.Decision, d.Allowed = DecisionDeny, false
+			d.RuleID, d.Reason = m.RuleID, m.Reason
+			return
+		}
+		if firstAllow == nil {
+			firstAllow = m
+		}
+	}
+	if firstAllow != nil {
+		d.RuleID, d.Reason = firstAllow.RuleID, firstAllow.Reason
+	}
+}
+
+// toolRules returns the mode whose rules apply and those rules.
+func (p *Policy) toolRules(mode string) (string, ToolRules) {
+	if mode == "" {
+		mode = DefaultRunMode
+	}
+	if rules, ok := p.Tools[mode]; ok {
+		return mode, rules
+	}
+	return DefaultRunMode, p.Tools[DefaultRunMode]
+}
+
+// matchToolRules applies one mode's tool rules to tool.
+func matchToolRules(mode string, rules ToolRules, tool string) []RuleMatch {
+	for i, pattern := range rules.Deny {
+		if matchGlob(pattern, tool) {
+			return []RuleMatch{{
+				RuleID:   fmt.Sprintf("tools.%s.deny[%d]", mode, i),
+				Decision: DecisionDeny,
+				Reason:   fmt.Sprintf("tool %s is denied in %s mode (matches %q)", tool, mode, pattern),
+			}}
+		}
+	}
+	if len(rules.Allow) == 0 {
+		return nil
+	}
+	for i, pattern := range rules.Allow {
+		if matchGlob(pattern, tool) {
+			return []RuleMatch{{
+				RuleID:   fmt.Sprintf("tools.%s.allow[%d]", mode, i),
+				Decision: DecisionAllow,
+				Reason:   fmt.Sprintf("tool %s is allowed in %s mode (matches %q)", tool, mode, pattern),
+			}}
+		}
+	}
+	return []RuleMatch{{
+		RuleID:   fmt.Sprintf("tools.%s.allow", mode),
+		Decision: DecisionDeny,
+		Reason:   fmt.Sprintf("tool %s is not in the allow list of %s mode", tool, mode),
+	}}
+}
+
+// matchNetwork denies network-reaching tools when the policy forbids them.
+func (p *Policy) matchNetwork(tool string) []RuleMatch {
+	if p.Network.Allow == nil || *p.Network.Allow {
+		return nil
+	}
+	networkTools := p.Network.Tools
+	if len(networkTools) == 0 {
+		networkTools = DefaultNetworkTools
+	}
+	for _, pattern := range networkTools {
+		if matchGlob(pattern, tool) {
+			return []RuleMatch{{
+				RuleID:   RuleNetworkDeny,
+				Decision: DecisionDeny,
+				Reason:   fmt.Sprintf("tool %s reaches the network, which the policy does not permit", tool),
+			}}
+		}
+	}
+	return nil
+}
+
+// match applies the path rules to one path.
+func (r PathRules) match(p string) []RuleMatch {
+	p = cleanPolicyPath(p)
+	for i, pattern := range r.Deny {
+		if matchGlob(pattern, p) {
+			return []RuleMatch{{
+				RuleID:   fmt.Sprintf("paths.deny[%d]", i),
+				Decision: DecisionDeny,
+				Reason:   fmt.Sprintf("path %s is denied (matches %q)", p, pattern),
+			}}
+		}
+	}
+	if len(r.Allow) == 0 {
+		return nil
+	}
+	for i, pattern := range r.Allow {
+		if matchGlob(pattern, p) {
+			return []RuleMatch{{
+				RuleID:   fmt.Sprintf("paths.allow[%d]", i),
+				Decision: DecisionAllow,
+				Reason:   fmt.Sprintf("path %s is allowed (matches %q)", p, pattern),
+			}}
+		}
+	}
+	return []RuleMatch{{
+		RuleID:   "paths.allow",
+		Decision: DecisionDeny,
+		Reason:   fmt.Sprintf("path %s is not in the path allow list", p),
+	}}
+}
+
+// match applies the blast radius limits to a plan.
+func (l BlastRadiusLimits) match(plan PlanImpact) []RuleMatch {
+	var matches []RuleMatch
+	if l.MaxFiles > 0 && plan.FilesTouched > l.MaxFiles {
+		matches = append(matches, RuleMatch{
+			RuleID:   RuleBlastRadiusFiles,
+			Decision: DecisionDeny,
+			Reason:   fmt.Sprintf("plan touches %d files, limit is %d", plan.FilesTouched, l.MaxFiles),
+		})
+	}
+	if l.MaxSymbols > 0 && plan.SymbolsModified > l.MaxSymbols {
+		matches = append(matches, RuleMatch{
+			RuleID:   RuleBlastRadiusSymbols,
+			Decision: DecisionDeny,
+			Reason:   fmt.Sprintf("plan modifies %d symbols, limit is %d", plan.SymbolsModified, l.MaxSymbols),
+		})
+	}
+	return matches
+}
+
+// match returns the first approval rule that selects plan, or nil if the
+// plan needs no approval. The returned match carries DecisionAllow: an
+// approval rule never denies a plan on its own.
+func (r ApprovalRules) match(plan PlanImpact) *RuleMatch {
+	switch {
+	case r.Required:
+		return &RuleMatch{RuleID: RuleApprovalRequired, Decision: DecisionAllow, Reason: "the policy requires approval for every plan"}
+	case r.MinFiles > 0 && plan.FilesTouched >= r.MinFiles:
+		return &RuleMatch{
+			RuleID:   RuleApprovalMinFiles,
+			Decision: DecisionAllow,
+			Reason:   fmt.Sprintf("plan touches %d files, approval is required from %d", plan.FilesTouched, r.MinFiles),
+		}
+	case r.MinSymbols > 0 && plan.SymbolsModified >= r.MinSymbols:
+		return &RuleMatch{
+			RuleID:   RuleApprovalMinSymbols,
+			Decision: DecisionAllow,
+			Reason:   fmt.Sprintf("plan modifies %d symbols, approval is required from %d", plan.SymbolsModified, r.MinSymbols),
+		}
+	}
+	return nil
+}
+
+// ChangeForTool maps a write, delete or command tool call to the change
+// the gate's checkers inspect. Returns nil for other tools.
+func ChangeForTool(tool string, params map[string]any) *ProposedChange {
+	str := func(key string) string {
+		s, _ := params[key].(string)
+		return s
+	}
+	pathParam := func() string {
+		if p := str("path"); p != "" {
+			return p
+		}
+		return str("file_path")
+	}
+	switch tool {
+	case "write_file", "edit_file", "Write", "Edit":
+		return &ProposedChange{Type: "file_write", Target: pathParam(), Content: str("content")}
+	case "delete_file":
+		return &ProposedChange{Type: "file_delete", Target: pathParam()}
+	case "run_command", "shell", "Bash":
+		return &ProposedChange{Type: "shell_command", Target: str("command")}
+	default:
+		return nil
+	}
+}
+
+// pathsFromParams returns the path-valued parameters of a tool call.
+func pathsFromParams(params map[string]any) []string {
+	var paths []string
+	for _, name := range pathParams {
+		switch v := params[name].(type) {
+		case string:
+			if v != "" {
+				paths = append(paths, v)
+			}
+		case []string:
+			paths = append(paths, v...)
+		case []any:
+			for _, item := range v {
+				if s, ok := item.(string); ok && s != "" {
+					paths = append(paths, s)
+				}
+			}
+		}
+	}
+	return paths
+}
+
+// cleanPolicyPath normalizes a path for glob matching.
+func cleanPolicyPath(p string) string {
+	return path.Clean(filepath.ToSlash(p))
+}
+
+// matchGlob reports whether name matches pattern. "*" matches within one
+// "/"-separated segment and "**" matches zero or more whole segments.
+// Patterns without "/" are also tried against the last segment of name,
+// so "*.pem" matches "certs/server.pem".
+func matchGlob(pattern, name string) bool {
+	if matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/")) {
+		return true
+	}
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	return false
+}
+
+// matchSegments matches pattern segments against name segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
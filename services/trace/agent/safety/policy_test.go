@@ -0,0 +1,247 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package safety
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPolicyYAML = `
+tools:
+  default:
+    deny: [write_file, edit_file, delete_file, run_command]
+  readonly:
+    allow: ["find_*", "get_*", read_file]
+  edit:
+    allow: ["*"]
+paths:
+  deny: ["**/.env", "**/secrets/**"]
+  allow: ["src/**", "docs/*.md"]
+blast_radius:
+  max_files: 5
+  max_symbols: 10
+network:
+  allow: false
+`
+
+func newTestPolicyGate(t *testing.T) *DefaultGate {
+	t.Helper()
+	policy, err := ParsePolicy([]byte(testPolicyYAML))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	return NewPolicyGate(policy)
+}
+
+func TestParsePolicy_JSON(t *testing.T) {
+	policy, err := ParsePolicy([]byte(`{"tools": {"ci": {"deny": ["*"]}}, "blast_radius": {"max_files": 3}}`))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	if got := policy.Tools["ci"].Deny; len(got) != 1 || got[0] != "*" {
+		t.Errorf("tools.ci.deny = %v", got)
+	}
+	if policy.BlastRadius.MaxFiles != 3 {
+		t.Errorf("max_files = %d, want 3", policy.BlastRadius.MaxFiles)
+	}
+}
+
+func TestParsePolicy_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":     "tools: {}\nbogus: true\n",
+		"bad glob":          "paths:\n  deny: [\"[\"]\n",
+		"negative limit":    "blast_radius:\n  max_files: -1\n",
+		"negative approval": "approval:\n  min_symbols: -2\n",
+	}
+	for name, doc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParsePolicy([]byte(doc)); !errors.Is(err, ErrInvalidPolicy) {
+				t.Errorf("err = %v, want ErrInvalidPolicy", err)
+			}
+		})
+	}
+}
+
+func TestParsePolicy_Empty(t *testing.T) {
+	policy, err := ParsePolicy(nil)
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	d, err := NewPolicyGate(policy).Evaluate(context.Background(), ToolCall{Tool: "semantic_search"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if !d.Allowed || d.RuleID != RuleDefaultAllow {
+		t.Errorf("decision = %+v, want default allow", d)
+	}
+}
+
+func TestLoadPolicyFromEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(path, []byte(testPolicyYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(PolicyEnvVar, path)
+
+	policy, got, err := LoadPolicyFromEnv()
+	if err != nil {
+		t.Fatalf("LoadPolicyFromEnv: %v", err)
+	}
+	if got != path {
+		t.Errorf("path = %q, want %q", got, path)
+	}
+	if modes := policy.Modes(); len(modes) != 3 || modes[0] != "default" {
+		t.Errorf("Modes = %v", modes)
+	}
+
+	t.Setenv(PolicyEnvVar, filepath.Join(t.TempDir(), "missing.yaml"))
+	if _, _, err := LoadPolicyFromEnv(); err == nil {
+		t.Error("expected error for missing policy file")
+	}
+}
+
+func TestDefaultGate_Evaluate(t *testing.T) {
+	gate := newTestPolicyGate(t)
+
+	tests := []struct {
+		name     string
+		call     ToolCall
+		allowed  bool
+		wantRule string
+	}{
+		{"default mode denies writes", ToolCall{Tool: "write_file", Params: map[string]any{"path": "src/a.go"}}, false, "tools.default.deny[0]"},
+		{"unknown mode falls back to default", ToolCall{Tool: "run_command", Mode: "nope"}, false, "tools.default.deny[3]"},
+		{"readonly allow glob", ToolCall{Tool: "find_callers", Mode: "readonly"}, true, "tools.readonly.allow[0]"},
+		{"readonly implicit deny", ToolCall{Tool: "write_file", Mode: "readonly"}, false, "tools.readonly.allow"},
+		{"edit mode allows writes", ToolCall{Tool: "write_file", Mode: "edit", Params: map[string]any{"path": "src/a.go"}}, true, "tools.edit.allow[0]"},
+		{"path deny beats tool allow", ToolCall{Tool: "read_file", Mode: "readonly", Params: map[string]any{"file_path": "src/.env"}}, false, "paths.deny[0]"},
+		{"path outside allow list", ToolCall{Tool: "read_file", Mode: "readonly", Params: map[string]any{"path": "vendor/x.go"}}, false, "paths.allow"},
+		{"path list parameter", ToolCall{Tool: "read_file", Mode: "readonly", Params: map[string]any{"paths": []any{"docs/a.md", "src/secrets/key.pem"}}}, false, "paths.deny[1]"},
+		{"network tool denied", ToolCall{Tool: "semantic_search", Mode: "edit"}, false, RuleNetworkDeny},
+		{"plan within limits", ToolCall{Tool: PlanToolName, Mode: "edit", Plan: &PlanImpact{FilesTouched: 5, SymbolsModified: 10}}, true, "tools.edit.allow[0]"},
+		{"plan touches too many files", ToolCall{Tool: PlanToolName, Mode: "edit", Plan: &PlanImpact{FilesTouched: 6}}, false, RuleBlastRadiusFiles},
+		{"plan modifies too many symbols", ToolCall{Tool: PlanToolName, Mode: "edit", Plan: &PlanImpact{FilesTouched: 1, SymbolsModified: 11}}, false, RuleBlastRadiusSymbols},
+		{"checker issue", ToolCall{Tool: "run_command", Mode: "edit", Params: map[string]any{"command": "rm -rf /"}}, false, "gate.BLOCKED_COMMAND"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := gate.Evaluate(context.Background(), tt.call)
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if d.Allowed != tt.allowed {
+				t.Errorf("Allowed = %v, want %v (%+v)", d.Allowed, tt.allowed, d)
+			}
+			if d.RuleID != tt.wantRule {
+				t.Errorf("RuleID = %q, want %q (matches %+v)", d.RuleID, tt.wantRule, d.Matches)
+			}
+			wantDecision := DecisionAllow
+			if !tt.allowed {
+				wantDecision = DecisionDeny
+			}
+			if d.Decision != wantDecision {
+				t.Errorf("Decision = %q, want %q", d.Decision, wantDecision)
+			}
+		})
+	}
+}
+
+func TestDefaultGate_EvaluateRequiresTool(t *testing.T) {
+	if _, err := NewPolicyGate(nil).Evaluate(context.Background(), ToolCall{}); err == nil {
+		t.Error("expected error for empty tool")
+	}
+}
+
+func TestDefaultGate_EvaluateDisabled(t *testing.T) {
+	gate := newTestPolicyGate(t)
+	gate.config.Enabled = false
+	d, err := gate.Evaluate(context.Background(), ToolCall{Tool: "write_file"})
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	if !d.Allowed {
+		t.Errorf("disabled gate denied call: %+v", d)
+	}
+}
+
+func TestDefaultGate_EvaluateApproval(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		plan     PlanImpact
+		want     bool
+		wantRule string
+	}{
+		{"no rules", "{}", PlanImpact{FilesTouched: 50}, false, ""},
+		{"always required", "approval:\n  required: true\n", PlanImpact{FilesTouched: 1}, true, RuleApprovalRequired},
+		{"below file threshold", "approval:\n  min_files: 3\n", PlanImpact{FilesTouched: 2}, false, ""},
+		{"at file threshold", "approval:\n  min_files: 3\n", PlanImpact{FilesTouched: 3}, true, RuleApprovalMinFiles},
+		{"symbol threshold", "approval:\n  min_symbols: 4\n", PlanImpact{FilesTouched: 1, SymbolsModified: 9}, true, RuleApprovalMinSymbols},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := ParsePolicy([]byte(tt.policy))
+			if err != nil {
+				t.Fatalf("ParsePolicy: %v", err)
+			}
+			d, err := NewPolicyGate(policy).Evaluate(context.Background(), ToolCall{Tool: PlanToolName, Plan: &tt.plan})
+			if err != nil {
+				t.Fatalf("Evaluate: %v", err)
+			}
+			if !d.Allowed {
+				t.Errorf("approval rule denied plan: %+v", d)
+			}
+			if d.ApprovalRequired != tt.want || d.ApprovalRuleID != tt.wantRule {
+				t.Errorf("approval = %v %q, want %v %q", d.ApprovalRequired, d.ApprovalRuleID, tt.want, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestPolicy_GateConfig(t *testing.T) {
+	policy, err := ParsePolicy([]byte("gate:\n  block_on_warning: true\n  max_file_size: 42\n"))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	cfg := policy.GateConfig()
+	if !cfg.BlockOnWarning || cfg.MaxFileSize != 42 {
+		t.Errorf("GateConfig = %+v", cfg)
+	}
+	if len(cfg.BlockedCommands) == 0 {
+		t.Error("unset gate fields should keep defaults")
+	}
+}
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"**/.env", ".env", true},
+		{"**/.env", "a/b/.env", true},
+		{"src/**", "src/a/b.go", true},
+		{"src/**", "lib/a.go", false},
+		{"src/*.go", "src/a/b.go", false},
+		{"*.pem", "certs/server.pem", true},
+		{"find_*", "find_callers", true},
+		{"a/**/z", "a/z", true},
+	}
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
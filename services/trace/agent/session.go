@@ -13,15 +13,18 @@ package agent
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // MetricField represents a session metric field for type-safe increments.
@@ -54,11 +57,21 @@ const (
 
 	// MetricSurrenderRetries is the count of surrender detection retries (GR-59 Group F).
 	MetricSurrenderRetries MetricField = "surrender_retries"
+
+	// MetricParallelToolCalls is the number of tool calls run concurrently.
+	MetricParallelToolCalls MetricField = "parallel_tool_calls"
+
+	// MetricParallelTimeSavedMs is the wall-clock time saved by parallel tool calls.
+	MetricParallelTimeSavedMs MetricField = "parallel_time_saved_ms"
 )
 
 // ValidContextEvictionPolicies contains valid eviction policy values.
 var ValidContextEvictionPolicies = []string{"lru", "relevance", "hybrid"}
 
+// ValidContextRankingStrategies contains valid context ranking strategy values.
+// Mirrors the strategies registered in the context assembler.
+var ValidContextRankingStrategies = []string{"relevance", "graph-proximity", "recency"}
+
 // ValidSafetyCheckScopes contains valid safety check scope values.
 var ValidSafetyCheckScopes = []string{"changed_files", "blast_radius", "full"}
 
@@ -100,6 +113,21 @@ type SessionConfig struct {
 	// Default: 8000
 	InitialContextBudget int `json:"initial_context_budget"`
 
+	// ContextMaxSymbolTokens caps the tokens any single symbol may use in the
+	// initial context. Larger symbols are truncated.
+	// Default: 0 (server configuration applies)
+	ContextMaxSymbolTokens int `json:"context_max_symbol_tokens,omitempty"`
+
+	// ContextMaxFiles caps the number of distinct files in the initial context.
+	// Default: 0 (server configuration applies)
+	ContextMaxFiles int `json:"context_max_files,omitempty"`
+
+	// ContextRanking selects how candidate symbols are ordered for the
+	// initial context.
+	// Options: "relevance", "graph-proximity", "recency"
+	// Default: "" (server configuration applies)
+	ContextRanking string `json:"context_ranking,omitempty"`
+
 	// ContextEvictionPolicy determines how context is evicted when over budget.
 	// Options: "lru", "relevance", "hybrid"
 	// Default: "hybrid"
@@ -109,6 +137,22 @@ type SessionConfig struct {
 	// Default: 50000
 	SummarizationThreshold int `json:"summarization_threshold"`
 
+	// DisableHistorySummarization keeps the full conversation on follow-up
+	// turns instead of summarizing older turns near the context window.
+	// Default: false
+	DisableHistorySummarization bool `json:"disable_history_summarization,omitempty"`
+
+	// HistorySummaryFraction is the share of the Main model's context
+	// window the conversation may fill before older turns are summarized.
+	// Default: 0.75
+	HistorySummaryFraction float64 `json:"history_summary_fraction,omitempty"`
+
+	// StripUnverifiedCitations removes citation markers that do not match a
+	// symbol observed via tools from the final answer. They are reported
+	// in RunResult.UnverifiedCitations either way.
+	// Default: false
+	StripUnverifiedCitations bool `json:"strip_unverified_citations,omitempty"`
+
 	// RequireSafetyCheck enables safety checks before code changes.
 	// Default: true
 	RequireSafetyCheck bool `json:"require_safety_check"`
@@ -173,6 +217,36 @@ type SessionConfig struct {
 	// Default: 0.7
 	ToolRouterConfidence float64 `json:"tool_router_confidence"`
 
+	// ToolTimeoutsMs overrides the per-tool execution timeout for this run,
+	// in integer milliseconds keyed by tool name (e.g. {"find_callers": 30000}
+	// for 30s). Layered over the registry's ToolExecutionPolicy.
+	// Default: empty (registry policy applies)
+	ToolTimeoutsMs map[string]int64 `json:"tool_timeouts_ms,omitempty"`
+
+	// MaxToolResultBytes caps a single tool result's output for this run.
+	// Default: 0 (registry policy applies)
+	MaxToolResultBytes int `json:"max_tool_result_bytes,omitempty"`
+
+	// MaxConcurrentTools caps in-flight tool executions for this run.
+	// Default: 0 (registry policy applies)
+	MaxConcurrentTools int `json:"max_concurrent_tools,omitempty"`
+
+	// BypassToolCache runs every tool for this run instead of serving
+	// results from the tool result caches. For debugging.
+	// Default: false
+	BypassToolCache bool `json:"bypass_tool_cache,omitempty"`
+
+	// RunMode selects the safety policy's tool rules for this run
+	// (e.g. "readonly", "edit"). Modes are defined by TRACE_SAFETY_POLICY.
+	// Default: "" (the policy's "default" mode)
+	RunMode string `json:"run_mode,omitempty"`
+
+	// Ensemble asks every Main-role ensemble member (MAIN_ENSEMBLE) for the
+	// final answer and reconciles them. Ignored when no ensemble is
+	// configured.
+	// Default: false
+	Ensemble bool `json:"ensemble,omitempty"`
+
 	// ParamExtractorModel is the Ollama model for LLM parameter extraction.
 	// IT-08e: Should be a small, fast model optimized for JSON structured output.
 	// Runs in parallel with the tool router on a separate model to avoid
@@ -202,6 +276,7 @@ func DefaultSessionConfig() *SessionConfig {
 		InitialContextBudget:   8000,
 		ContextEvictionPolicy:  "hybrid",
 		SummarizationThreshold: 50000,
+		HistorySummaryFraction: DefaultHistorySummaryFraction,
 		RequireSafetyCheck:     true,
 		SafetyCheckScope:       "blast_radius",
 		BlockOnCritical:        true,
@@ -263,12 +338,30 @@ func (c *SessionConfig) MergeOverrides(overrides *SessionConfig) *SessionConfig
 	if overrides.InitialContextBudget > 0 {
 		c.InitialContextBudget = overrides.InitialContextBudget
 	}
+	if overrides.ContextMaxSymbolTokens > 0 {
+		c.ContextMaxSymbolTokens = overrides.ContextMaxSymbolTokens
+	}
+	if overrides.ContextMaxFiles > 0 {
+		c.ContextMaxFiles = overrides.ContextMaxFiles
+	}
+	if overrides.ContextRanking != "" {
+		c.ContextRanking = overrides.ContextRanking
+	}
 	if overrides.ContextEvictionPolicy != "" {
 		c.ContextEvictionPolicy = overrides.ContextEvictionPolicy
 	}
 	if overrides.SummarizationThreshold > 0 {
 		c.SummarizationThreshold = overrides.SummarizationThreshold
 	}
+	if overrides.DisableHistorySummarization {
+		c.DisableHistorySummarization = true
+	}
+	if overrides.HistorySummaryFraction > 0 {
+		c.HistorySummaryFraction = overrides.HistorySummaryFraction
+	}
+	if overrides.StripUnverifiedCitations {
+		c.StripUnverifiedCitations = true
+	}
 	if overrides.SafetyCheckScope != "" {
 		c.SafetyCheckScope = overrides.SafetyCheckScope
 	}
@@ -305,6 +398,24 @@ func (c *SessionConfig) MergeOverrides(overrides *SessionConfig) *SessionConfig
 	if len(overrides.ToolPriorities) > 0 {
 		c.ToolPriorities = overrides.ToolPriorities
 	}
+	if len(overrides.ToolTimeoutsMs) > 0 {
+		c.ToolTimeoutsMs = overrides.ToolTimeoutsMs
+	}
+	if overrides.MaxToolResultBytes > 0 {
+		c.MaxToolResultBytes = overrides.MaxToolResultBytes
+	}
+	if overrides.MaxConcurrentTools > 0 {
+		c.MaxConcurrentTools = overrides.MaxConcurrentTools
+	}
+	if overrides.BypassToolCache {
+		c.BypassToolCache = true
+	}
+	if overrides.RunMode != "" {
+		c.RunMode = overrides.RunMode
+	}
+	if overrides.Ensemble {
+		c.Ensemble = true
+	}
 	return c
 }
 
@@ -340,11 +451,34 @@ func (c *SessionConfig) Validate() error {
 	if c.ConfidenceThreshold < 0 || c.ConfidenceThreshold > 1 {
 		return fmt.Errorf("%w: ConfidenceThreshold must be between 0 and 1", ErrInvalidSession)
 	}
+	if c.HistorySummaryFraction < 0 || c.HistorySummaryFraction > 1 {
+		return fmt.Errorf("%w: HistorySummaryFraction must be between 0 and 1", ErrInvalidSession)
+	}
+	if c.ContextMaxSymbolTokens < 0 {
+		return fmt.Errorf("%w: ContextMaxSymbolTokens must not be negative", ErrInvalidSession)
+	}
+	if c.ContextMaxFiles < 0 {
+		return fmt.Errorf("%w: ContextMaxFiles must not be negative", ErrInvalidSession)
+	}
+	if c.MaxToolResultBytes < 0 {
+		return fmt.Errorf("%w: MaxToolResultBytes must not be negative", ErrInvalidSession)
+	}
+	if c.MaxConcurrentTools < 0 {
+		return fmt.Errorf("%w: MaxConcurrentTools must not be negative", ErrInvalidSession)
+	}
+	for name, timeoutMs := range c.ToolTimeoutsMs {
+		if timeoutMs <= 0 {
+			return fmt.Errorf("%w: ToolTimeoutsMs[%s] must be positive", ErrInvalidSession, name)
+		}
+	}
 
 	// Validate string enums
 	if c.ContextEvictionPolicy != "" && !isValidEnum(c.ContextEvictionPolicy, ValidContextEvictionPolicies) {
 		return fmt.Errorf("%w: ContextEvictionPolicy must be one of %v", ErrInvalidSession, ValidContextEvictionPolicies)
 	}
+	if c.ContextRanking != "" && !isValidEnum(c.ContextRanking, ValidContextRankingStrategies) {
+		return fmt.Errorf("%w: ContextRanking must be one of %v", ErrInvalidSession, ValidContextRankingStrategies)
+	}
 	if c.SafetyCheckScope != "" && !isValidEnum(c.SafetyCheckScope, ValidSafetyCheckScopes) {
 		return fmt.Errorf("%w: SafetyCheckScope must be one of %v", ErrInvalidSession, ValidSafetyCheckScopes)
 	}
@@ -396,6 +530,17 @@ type Session struct {
 	// GraphID is the Trace graph ID (set after init).
 	GraphID string `json:"graph_id,omitempty"`
 
+	// Scope names the graph view the session's tools are restricted to.
+	// Empty for the whole graph.
+	Scope string `json:"scope,omitempty"`
+
+	// Diff is the unified diff the run reviews. Its changed symbols seed
+	// the initial context. Empty for ordinary queries.
+	Diff string `json:"diff,omitempty"`
+
+	// DiffSymbols are the symbols the Plan phase derived from Diff.
+	DiffSymbols []DiffSymbol `json:"diff_symbols,omitempty"`
+
 	// State is the current agent state.
 	State AgentState `json:"state"`
 
@@ -485,6 +630,43 @@ type Session struct {
 	// CRS-SCOPE-01: Used to prevent infinite scope relaxation retries
 	// (e.g., "scope_relaxed_find_hotspots" ensures at most one relaxation per tool).
 	flags map[string]bool
+
+	// contextOverflow is the last LLM call that did not fit the model's
+	// context window, until the reflect phase takes it to narrow the
+	// context. See SetContextOverflow.
+	contextOverflow *ContextOverflow
+
+	// citations are the symbols observed via tools that answers may cite.
+	// Created on first use.
+	citations *CitationSet
+
+	// routingDecisions are the most recent routing decisions, oldest
+	// first. See RecordRoutingDecision.
+	routingDecisions []RoutingDecision
+
+	// pendingClarification is the clarification request the session is
+	// waiting on. Nil outside CLARIFY.
+	pendingClarification *ClarifyRequest
+
+	// clarificationResponse is the user's answer, set by Continue and
+	// taken by the clarify phase.
+	clarificationResponse *ClarificationResponse
+
+	// runSpan is the span context of the current or most recent Run or
+	// Continue call. Session restore parents its spans under it and
+	// checkpoints record it as their origin.
+	runSpan trace.SpanContext
+
+	// checkpoint is the phase an aborted session resumes from. Updated
+	// by the loop before and after each phase.
+	checkpoint *phaseCheckpoint
+
+	// abort describes the abort while the session is ABORTED.
+	abort *AbortRecord
+
+	// restoreOrigins are the spans that saved checkpoints restored into
+	// this session. Run spans link to them.
+	restoreOrigins []trace.SpanContext
 }
 
 // SafetyViolation represents a safety-blocked operation for CDCL learning.
@@ -541,6 +723,93 @@ type AssembledContext struct {
 
 	// Relevance maps entry IDs to relevance scores.
 	Relevance map[string]float64 `json:"relevance"`
+
+	// Assembly describes how the initial code context was selected.
+	// Nil when no ContextManager assembled the context.
+	Assembly *ContextAssembly `json:"assembly,omitempty"`
+}
+
+// ContextAssembly records the budget and ranking decisions made when the
+// initial code context was assembled, for debugging what the model saw.
+type ContextAssembly struct {
+	// Ranking is the ranking strategy used.
+	Ranking string `json:"ranking"`
+
+	// MaxTotalTokens is the total token budget applied.
+	MaxTotalTokens int `json:"max_total_tokens"`
+
+	// MaxSymbolTokens is the per-symbol cap applied (0 = none).
+	MaxSymbolTokens int `json:"max_symbol_tokens"`
+
+	// MaxFiles is the file cap applied (0 = none).
+	MaxFiles int `json:"max_files"`
+
+	// TokensUsed is the estimated tokens of the assembled context.
+	TokensUsed int `json:"tokens_used"`
+
+	// Included lists the symbols placed in context, in rank order.
+	Included []ContextSymbolBudget `json:"included"`
+
+	// Dropped lists ranked symbols left out, with the reason.
+	Dropped []ContextSymbolBudget `json:"dropped"`
+}
+
+// ContextSymbolBudget describes one candidate symbol in a ContextAssembly.
+type ContextSymbolBudget struct {
+	// SymbolID is the symbol's ID.
+	SymbolID string `json:"symbol_id"`
+
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// FilePath is the file the symbol is defined in.
+	FilePath string `json:"file_path"`
+
+	// Tokens is the estimated token cost.
+	Tokens int `json:"tokens"`
+
+	// Score is the relevance score.
+	Score float64 `json:"score"`
+
+	// Depth is the graph distance from the query's entry points.
+	Depth int `json:"depth"`
+
+	// Truncated is true if the symbol was cut to the per-symbol cap.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Reason explains why the symbol was dropped ("total_budget", "max_files").
+	Reason string `json:"reason,omitempty"`
+}
+
+// DiffSymbol is a symbol derived from the diff a run was given, so
+// reviewers can check which code the change was mapped onto.
+type DiffSymbol struct {
+	// SymbolID is the symbol's ID.
+	SymbolID string `json:"symbol_id"`
+
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// Kind is the symbol kind, e.g. "function".
+	Kind string `json:"kind"`
+
+	// FilePath is the file the symbol is defined in.
+	FilePath string `json:"file_path"`
+
+	// StartLine and EndLine are the symbol's lines.
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+
+	// Relation is "changed" for symbols whose lines the diff changes,
+	// "caller" or "callee" for their direct callers and callees.
+	Relation string `json:"relation"`
+
+	// Temporary is true for symbols parsed from a file the graph does not
+	// contain, such as a file the diff adds.
+	Temporary bool `json:"temporary,omitempty"`
+
+	// InContext is true if the symbol fit in the initial context budget.
+	InContext bool `json:"in_context"`
 }
 
 // GetRelevance returns the relevance score for an entry ID.
@@ -733,6 +1002,61 @@ func (s *Session) SetGraphID(graphID string) {
 	s.LastActiveAt = time.Now().UnixMilli()
 }
 
+// GetScope returns the graph view the session is restricted to, if any.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) GetScope() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Scope
+}
+
+// SetScope restricts the session's graph tools to a named graph view.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) SetScope(scope string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Scope = scope
+}
+
+// GetDiff returns the unified diff the session reviews, if any.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) GetDiff() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.Diff
+}
+
+// SetDiff sets the unified diff whose changed symbols seed the session's
+// initial context.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) SetDiff(diff string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Diff = diff
+}
+
+// GetDiffSymbols returns the symbols derived from the session's diff.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) GetDiffSymbols() []DiffSymbol {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.DiffSymbols
+}
+
+// SetDiffSymbols records the symbols derived from the session's diff.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) SetDiffSymbols(symbols []DiffSymbol) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.DiffSymbols = symbols
+}
+
 // AddHistoryEntry appends a history entry.
 //
 // Thread Safety: This method is safe for concurrent use.
@@ -800,6 +1124,38 @@ func (s *Session) IncrementMetric(field MetricField, value int) {
 		s.Metrics.GroundingRetries += value
 	case MetricToolForcingRetries:
 		s.Metrics.ToolForcingRetries += value
+	case MetricParallelToolCalls:
+		s.Metrics.ParallelToolCalls += value
+	case MetricParallelTimeSavedMs:
+		s.Metrics.ParallelTimeSavedMs += value
+	}
+	s.LastActiveAt = time.Now().UnixMilli()
+}
+
+// IncrementProviderTokens adds tokens used by a main-model call.
+//
+// Description:
+//
+//	Adds value to the total token metric and to the bucket of the model
+//	that served the call, so runs using several providers can be
+//	accounted per provider.
+//
+// Inputs:
+//
+//	provider - The "provider/model" that served the call. Empty only
+//	           updates the total.
+//	value - The tokens to add.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) IncrementProviderTokens(provider string, value int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Metrics.TotalTokens += value
+	if provider != "" {
+		if s.Metrics.TokensByProvider == nil {
+			s.Metrics.TokensByProvider = make(map[string]int)
+		}
+		s.Metrics.TokensByProvider[provider] += value
 	}
 	s.LastActiveAt = time.Now().UnixMilli()
 }
@@ -874,10 +1230,11 @@ func (s *Session) SetCurrentContext(ctx *AssembledContext) {
 func (s *Session) ToSessionState() *SessionState {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
-	return &SessionState{
+	state := &SessionState{
 		ID:           s.ID,
 		ProjectRoot:  s.ProjectRoot,
 		GraphID:      s.GraphID,
+		Scope:        s.Scope,
 		State:        s.State,
 		StepCount:    s.Metrics.TotalSteps,
 		TokensUsed:   s.Metrics.TotalTokens,
@@ -885,6 +1242,11 @@ func (s *Session) ToSessionState() *SessionState {
 		LastActiveAt: s.LastActiveAt,
 		DegradedMode: s.Metrics.DegradedMode,
 	}
+	if s.State == StateAborted && s.abort != nil {
+		record := *s.abort
+		state.Abort = &record
+	}
+	return state
 }
 
 // GetClarificationPrompt returns the clarification prompt if set.
@@ -910,7 +1272,14 @@ func (s *Session) GetMetrics() SessionMetrics {
 	if s.Metrics == nil {
 		return SessionMetrics{}
 	}
-	return *s.Metrics
+	metrics := *s.Metrics
+	if s.Metrics.TokensByProvider != nil {
+		metrics.TokensByProvider = make(map[string]int, len(s.Metrics.TokensByProvider))
+		for provider, tokens := range s.Metrics.TokensByProvider {
+			metrics.TokensByProvider[provider] = tokens
+		}
+	}
+	return metrics
 }
 
 // GetMetric returns the value of a specific metric field.
@@ -939,6 +1308,10 @@ func (s *Session) GetMetric(field MetricField) int {
 		return s.Metrics.GroundingRetries
 	case MetricToolForcingRetries:
 		return s.Metrics.ToolForcingRetries
+	case MetricParallelToolCalls:
+		return s.Metrics.ParallelToolCalls
+	case MetricParallelTimeSavedMs:
+		return s.Metrics.ParallelTimeSavedMs
 	default:
 		return 0
 	}
@@ -1593,6 +1966,28 @@ func (s *Session) GetTraceSteps() []crs.TraceStep {
 // Session Flags (CRS-SCOPE-01)
 // -----------------------------------------------------------------------------
 
+// Citations returns the session's citation set.
+//
+// Description:
+//
+//	The set accumulates across turns of a multi-turn session. The execute
+//	phase adds the symbols each tool call returns; the loop validates the
+//	final answer's citation markers against it.
+//
+// Outputs:
+//
+//	*CitationSet - The citation set. Never nil.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Session) Citations() *CitationSet {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.citations == nil {
+		s.citations = NewCitationSet()
+	}
+	return s.citations
+}
+
 // HasFlag checks whether a per-session boolean flag has been set.
 //
 // Description:
@@ -1639,6 +2034,32 @@ func (s *Session) SetFlag(key string) {
 	s.flags[key] = true
 }
 
+// SetContextOverflow records an LLM call that failed with
+// ErrContextOverflow, for the reflect phase to narrow the context.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Session) SetContextOverflow(overflow ContextOverflow) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contextOverflow = &overflow
+}
+
+// TakeContextOverflow returns and clears the overflow recorded by
+// SetContextOverflow.
+//
+// Outputs:
+//
+//	*ContextOverflow - The overflow, or nil if none is pending.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Session) TakeContextOverflow() *ContextOverflow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	overflow := s.contextOverflow
+	s.contextOverflow = nil
+	return overflow
+}
+
 // -----------------------------------------------------------------------------
 // Safety Violation Metrics (Issue #6)
 // -----------------------------------------------------------------------------
@@ -1688,3 +2109,69 @@ func recordSafetyViolation(nodeID string) {
 		attribute.String("node_id", nodeID),
 	))
 }
+
+// sessionIDKey is the context key for the ID of the running session.
+type sessionIDKey struct{}
+
+// ContextWithSessionID returns ctx carrying the ID of the session it runs
+// for, so shared components (e.g. the egress guard around a shared LLM
+// client) can attribute work to the session. Records logged with the
+// context carry session_id.
+func ContextWithSessionID(ctx context.Context, sessionID string) context.Context {
+	ctx = telemetry.ContextWithLogAttrs(ctx, slog.String("session_id", sessionID))
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// SessionIDFromContext returns the session ID set by ContextWithSessionID,
+// or "" if none.
+func SessionIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(sessionIDKey{}).(string)
+	return id
+}
+
+// SetRunSpanContext records the span context of the run in progress.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Session) SetRunSpanContext(sc trace.SpanContext) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.runSpan = sc
+}
+
+// RunSpanContext returns the span context of the current or most recent
+// run, or an invalid span context if the session has not run traced.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Session) RunSpanContext() trace.SpanContext {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.runSpan
+}
+
+// AddRestoreOrigin records the span that saved a checkpoint restored into
+// this session. Invalid span contexts are ignored.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Session) AddRestoreOrigin(sc trace.SpanContext) {
+	if !sc.IsValid() {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.restoreOrigins = append(s.restoreOrigins, sc)
+}
+
+// RestoreOrigins returns a copy of the restore origins recorded by
+// AddRestoreOrigin, oldest first.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Session) RestoreOrigins() []trace.SpanContext {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]trace.SpanContext, len(s.restoreOrigins))
+	copy(out, s.restoreOrigins)
+	return out
+}
@@ -0,0 +1,455 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/storage/redis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/dgraph-io/badger/v4"
+)
+
+// ErrSessionRecordNotFound is returned by a SessionRecordStore when no
+// record exists for a session.
+var ErrSessionRecordNotFound = errors.New("session record not found")
+
+// Key prefixes for shared session records.
+const (
+	keyPrefixLiveSession   = "agent:live:"
+	redisKeyPrefixSession  = "trace:session:"
+	sharedSessionIOTimeout = 2 * time.Second
+)
+
+// SessionRecord is the persisted form of a Session.
+//
+// Description:
+//
+//	Holds the fields another instance needs to continue the session: its
+//	state, configuration, history, assembled context and pending
+//	clarification. Runtime attachments (tool router, CRS, trace recorder,
+//	model manager) are not persisted; an instance restoring the session
+//	starts them afresh.
+type SessionRecord struct {
+	ID                   string            `json:"id"`
+	ProjectRoot          string            `json:"project_root"`
+	GraphID              string            `json:"graph_id,omitempty"`
+	Scope                string            `json:"scope,omitempty"`
+	State                AgentState        `json:"state"`
+	Config               *SessionConfig    `json:"config"`
+	History              []HistoryEntry    `json:"history"`
+	Metrics              *SessionMetrics   `json:"metrics"`
+	Context              *AssembledContext `json:"context,omitempty"`
+	LastQuery            string            `json:"last_query,omitempty"`
+	LastIntent           *QueryIntent      `json:"last_intent,omitempty"`
+	PendingClarification *ClarifyRequest   `json:"pending_clarification,omitempty"`
+	CreatedAt            int64             `json:"created_at"`
+	LastActiveAt         int64             `json:"last_active_at"`
+
+	// Revision increases with every save of the session, on any
+	// instance. It orders records without relying on clocks.
+	Revision int64 `json:"revision"`
+
+	// Instance is the ID of the instance that saved the record.
+	Instance string `json:"instance,omitempty"`
+}
+
+// encodeRecord marshals the session as a SessionRecord.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Session) encodeRecord(revision int64, instance string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return json.Marshal(&SessionRecord{
+		ID:                   s.ID,
+		ProjectRoot:          s.ProjectRoot,
+		GraphID:              s.GraphID,
+		Scope:                s.Scope,
+		State:                s.State,
+		Config:               s.Config,
+		History:              s.History,
+		Metrics:              s.Metrics,
+		Context:              s.CurrentContext,
+		LastQuery:            s.LastQuery,
+		LastIntent:           s.LastIntent,
+		PendingClarification: s.pendingClarification,
+		CreatedAt:            s.CreatedAt,
+		LastActiveAt:         s.LastActiveAt,
+		Revision:             revision,
+		Instance:             instance,
+	})
+}
+
+// isInProgress reports whether an operation holds the session.
+func (s *Session) isInProgress() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.inProgress
+}
+
+// decodeSessionRecord restores a session from an encoded SessionRecord.
+//
+// Outputs:
+//
+//	*Session - The restored session, idle and not in progress.
+//	*SessionRecord - The decoded record, for its revision and writer.
+//	error - Non-nil if the record is malformed or has no ID.
+func decodeSessionRecord(data []byte) (*Session, *SessionRecord, error) {
+	var rec SessionRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, nil, fmt.Errorf("decoding session record: %w", err)
+	}
+	if rec.ID == "" {
+		return nil, nil, fmt.Errorf("%w: session record has no ID", ErrInvalidSession)
+	}
+	if rec.Config == nil {
+		rec.Config = DefaultSessionConfig()
+	}
+	if rec.Metrics == nil {
+		rec.Metrics = &SessionMetrics{}
+	}
+	if rec.History == nil {
+		rec.History = make([]HistoryEntry, 0)
+	}
+	return &Session{
+		ID:                   rec.ID,
+		ProjectRoot:          rec.ProjectRoot,
+		GraphID:              rec.GraphID,
+		Scope:                rec.Scope,
+		State:                rec.State,
+		Config:               rec.Config,
+		History:              rec.History,
+		Metrics:              rec.Metrics,
+		CurrentContext:       rec.Context,
+		LastQuery:            rec.LastQuery,
+		LastIntent:           rec.LastIntent,
+		CreatedAt:            rec.CreatedAt,
+		LastActiveAt:         rec.LastActiveAt,
+		pendingClarification: rec.PendingClarification,
+		traceRecorder:        crs.NewTraceRecorder(crs.DefaultTraceConfig()),
+	}, &rec, nil
+}
+
+// SessionRecordStore persists encoded session records shared between
+// instances.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type SessionRecordStore interface {
+	// LoadSessionRecord returns the record of a session, or
+	// ErrSessionRecordNotFound.
+	LoadSessionRecord(ctx context.Context, sessionID string) ([]byte, error)
+
+	// SaveSessionRecord replaces the record of a session.
+	SaveSessionRecord(ctx context.Context, sessionID string, data []byte) error
+
+	// DeleteSessionRecord removes the record of a session. Deleting a
+	// missing record is not an error.
+	DeleteSessionRecord(ctx context.Context, sessionID string) error
+}
+
+// SharedSessionStore is a SessionStore whose sessions survive the
+// instance: every Put is written through to a SessionRecordStore, and a
+// session missing or outdated locally is restored from it.
+//
+// Description:
+//
+//	Sessions are kept in memory as with InMemorySessionStore. Get also
+//	reads the shared record, unless the local session is in progress, and
+//	replaces the local session when the record has a newer revision, so
+//	a session continued on another instance is picked up here. Backend
+//	failures are logged and the local copy is used, so a shared store
+//	outage degrades to instance-local sessions.
+//
+// Limitations:
+//
+//   - Last writer wins: two instances continuing the same session at
+//     the same time both save, and the later save is kept.
+//   - List returns only the sessions known to this instance.
+//
+// Thread Safety: Safe for concurrent use.
+type SharedSessionStore struct {
+	local    *InMemorySessionStore
+	backend  SessionRecordStore
+	instance string
+	logger   *slog.Logger
+
+	mu        sync.Mutex
+	revisions map[string]int64
+}
+
+// NewSharedSessionStore creates a SessionStore backed by a shared record
+// store.
+//
+// Inputs:
+//
+//	backend - The shared record store. Must not be nil.
+//	instance - This instance's ID, recorded with each save.
+//	logger - Logger for restore and backend diagnostics. May be nil.
+//
+// Outputs:
+//
+//	*SharedSessionStore - The store. Never nil.
+func NewSharedSessionStore(backend SessionRecordStore, instance string, logger *slog.Logger) *SharedSessionStore {
+	if backend == nil {
+		panic("NewSharedSessionStore: backend must not be nil")
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &SharedSessionStore{
+		local:     NewInMemorySessionStore(),
+		backend:   backend,
+		instance:  instance,
+		logger:    logger,
+		revisions: make(map[string]int64),
+	}
+}
+
+// Get implements SessionStore.
+func (s *SharedSessionStore) Get(id string) (*Session, bool) {
+	local, ok := s.local.Get(id)
+	if ok && local.isInProgress() {
+		return local, true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharedSessionIOTimeout)
+	defer cancel()
+	data, err := s.backend.LoadSessionRecord(ctx, id)
+	if err != nil {
+		if !errors.Is(err, ErrSessionRecordNotFound) {
+			s.logger.Warn("shared session store: load failed, using local session",
+				slog.String("session_id", id), telemetry.SensitiveLogError("error", err))
+		}
+		return local, ok
+	}
+	restored, rec, err := decodeSessionRecord(data)
+	if err != nil {
+		s.logger.Warn("shared session store: discarding malformed record",
+			slog.String("session_id", id), telemetry.SensitiveLogError("error", err))
+		return local, ok
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ok && rec.Revision <= s.revisions[id] {
+		return local, true
+	}
+	s.revisions[id] = rec.Revision
+	s.local.Put(restored)
+	s.logger.Info("shared session store: session restored",
+		slog.String("session_id", id),
+		slog.String("saved_by", rec.Instance),
+		slog.Int64("revision", rec.Revision),
+		slog.String("state", string(rec.State)),
+		slog.Bool("replaced_local", ok),
+	)
+	return restored, true
+}
+
+// Put implements SessionStore. The session is written through to the
+// shared store; a failed write is logged and the session stays local.
+func (s *SharedSessionStore) Put(session *Session) {
+	s.local.Put(session)
+
+	s.mu.Lock()
+	revision := s.revisions[session.ID] + 1
+	s.revisions[session.ID] = revision
+	s.mu.Unlock()
+
+	data, err := session.encodeRecord(revision, s.instance)
+	if err != nil {
+		s.logger.Warn("shared session store: encoding failed",
+			slog.String("session_id", session.ID), telemetry.SensitiveLogError("error", err))
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sharedSessionIOTimeout)
+	defer cancel()
+	if err := s.backend.SaveSessionRecord(ctx, session.ID, data); err != nil {
+		s.logger.Warn("shared session store: save failed, session is local to this instance",
+			slog.String("session_id", session.ID), telemetry.SensitiveLogError("error", err))
+	}
+}
+
+// Delete implements SessionStore.
+func (s *SharedSessionStore) Delete(id string) {
+	s.local.Delete(id)
+	s.mu.Lock()
+	delete(s.revisions, id)
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), sharedSessionIOTimeout)
+	defer cancel()
+	if err := s.backend.DeleteSessionRecord(ctx, id); err != nil {
+		s.logger.Warn("shared session store: delete failed",
+			slog.String("session_id", id), telemetry.SensitiveLogError("error", err))
+	}
+}
+
+// List implements SessionStore. Only sessions known to this instance are
+// listed.
+func (s *SharedSessionStore) List() []string {
+	return s.local.List()
+}
+
+// BadgerSessionRecordStore keeps session records in BadgerDB.
+//
+// Description:
+//
+//	Records are stored under agent:live:{sessionID} with an optional TTL.
+//	BadgerDB allows one process per directory, so a Badger path shared
+//	between replicas serves failover (a standby or restarted instance
+//	opening the same volume) rather than replicas running side by side;
+//	use the Redis backend for those.
+//
+// Thread Safety: Safe for concurrent use.
+type BadgerSessionRecordStore struct {
+	db  *badger.DB
+	ttl time.Duration
+}
+
+// NewBadgerSessionRecordStore creates a Badger-backed record store.
+//
+// Inputs:
+//
+//	db - An opened BadgerDB. Must not be nil.
+//	ttl - Record lifetime after the last save. Zero keeps records forever.
+//
+// Outputs:
+//
+//	*BadgerSessionRecordStore - The store.
+//	error - Non-nil if db is nil.
+func NewBadgerSessionRecordStore(db *badger.DB, ttl time.Duration) (*BadgerSessionRecordStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("badger db must not be nil")
+	}
+	return &BadgerSessionRecordStore{db: db, ttl: ttl}, nil
+}
+
+// LoadSessionRecord implements SessionRecordStore.
+func (s *BadgerSessionRecordStore) LoadSessionRecord(ctx context.Context, sessionID string) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var data []byte
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(keyPrefixLiveSession + sessionID))
+		if err != nil {
+			return err
+		}
+		data, err = item.ValueCopy(nil)
+		return err
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, ErrSessionRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading session record %s: %w", sessionID, err)
+	}
+	return data, nil
+}
+
+// SaveSessionRecord implements SessionRecordStore.
+func (s *BadgerSessionRecordStore) SaveSessionRecord(ctx context.Context, sessionID string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	entry := badger.NewEntry([]byte(keyPrefixLiveSession+sessionID), data)
+	if s.ttl > 0 {
+		entry = entry.WithTTL(s.ttl)
+	}
+	if err := s.db.Update(func(txn *badger.Txn) error { return txn.SetEntry(entry) }); err != nil {
+		return fmt.Errorf("saving session record %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// DeleteSessionRecord implements SessionRecordStore.
+func (s *BadgerSessionRecordStore) DeleteSessionRecord(ctx context.Context, sessionID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := s.db.Update(func(txn *badger.Txn) error {
+		return txn.Delete([]byte(keyPrefixLiveSession + sessionID))
+	}); err != nil {
+		return fmt.Errorf("deleting session record %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// RedisClient is the subset of the Redis client the Redis record store
+// uses. Get reports a missing key with redis.ErrNil.
+type RedisClient interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Del(ctx context.Context, key string) error
+}
+
+// RedisSessionRecordStore keeps session records in Redis, under
+// trace:session:{sessionID}, so replicas running side by side share them.
+//
+// Thread Safety: Safe for concurrent use if the client is.
+type RedisSessionRecordStore struct {
+	client RedisClient
+	ttl    time.Duration
+}
+
+// NewRedisSessionRecordStore creates a Redis-backed record store.
+//
+// Inputs:
+//
+//	client - The Redis client. Must not be nil.
+//	ttl - Record lifetime after the last save. Zero keeps records forever.
+//
+// Outputs:
+//
+//	*RedisSessionRecordStore - The store.
+//	error - Non-nil if client is nil.
+func NewRedisSessionRecordStore(client RedisClient, ttl time.Duration) (*RedisSessionRecordStore, error) {
+	if client == nil {
+		return nil, fmt.Errorf("redis client must not be nil")
+	}
+	return &RedisSessionRecordStore{client: client, ttl: ttl}, nil
+}
+
+// LoadSessionRecord implements SessionRecordStore.
+func (s *RedisSessionRecordStore) LoadSessionRecord(ctx context.Context, sessionID string) ([]byte, error) {
+	data, err := s.client.Get(ctx, redisKeyPrefixSession+sessionID)
+	if errors.Is(err, redis.ErrNil) {
+		return nil, ErrSessionRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading session record %s: %w", sessionID, err)
+	}
+	return data, nil
+}
+
+// SaveSessionRecord implements SessionRecordStore.
+func (s *RedisSessionRecordStore) SaveSessionRecord(ctx context.Context, sessionID string, data []byte) error {
+	if err := s.client.Set(ctx, redisKeyPrefixSession+sessionID, data, s.ttl); err != nil {
+		return fmt.Errorf("saving session record %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// DeleteSessionRecord implements SessionRecordStore.
+func (s *RedisSessionRecordStore) DeleteSessionRecord(ctx context.Context, sessionID string) error {
+	if err := s.client.Del(ctx, redisKeyPrefixSession+sessionID); err != nil {
+		return fmt.Errorf("deleting session record %s: %w", sessionID, err)
+	}
+	return nil
+}
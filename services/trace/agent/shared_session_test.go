@@ -0,0 +1,245 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/storage/redis"
+	"github.com/dgraph-io/badger/v4"
+)
+
+// newTestBadgerRecordStore returns a record store on an in-memory BadgerDB.
+func newTestBadgerRecordStore(t *testing.T) *BadgerSessionRecordStore {
+	t.Helper()
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("failed to open in-memory badger: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := NewBadgerSessionRecordStore(db, time.Hour)
+	if err != nil {
+		t.Fatalf("NewBadgerSessionRecordStore: %v", err)
+	}
+	return store
+}
+
+func TestSessionRecord_RoundTrip(t *testing.T) {
+	session, _ := NewSession("/test/project", nil)
+	session.SetGraphID("g1")
+	session.SetScope("backend")
+	session.SetState(StateClarify)
+	session.LastQuery = "where is Handler?"
+	session.AddHistoryEntry(HistoryEntry{Type: "query", Input: "where is Handler?"})
+	session.SetCurrentContext(&AssembledContext{ConversationHistory: []Message{{Role: "user", Content: "hi"}}})
+	session.SetPendingClarification(&ClarifyRequest{
+		Question: "Which Handler?",
+		Options:  []ClarificationOption{{ID: "opt_1", Label: "Handler in pkg/auth"}},
+	})
+
+	data, err := session.encodeRecord(3, "replica-a")
+	if err != nil {
+		t.Fatalf("encodeRecord: %v", err)
+	}
+	restored, rec, err := decodeSessionRecord(data)
+	if err != nil {
+		t.Fatalf("decodeSessionRecord: %v", err)
+	}
+	if rec.Revision != 3 || rec.Instance != "replica-a" {
+		t.Errorf("revision/instance = %d/%q, want 3/replica-a", rec.Revision, rec.Instance)
+	}
+	if restored.ID != session.ID || restored.GetGraphID() != "g1" || restored.GetScope() != "backend" {
+		t.Errorf("identity not restored: %+v", restored)
+	}
+	if restored.GetState() != StateClarify || restored.LastQuery != session.LastQuery {
+		t.Errorf("state/query = %s/%q", restored.GetState(), restored.LastQuery)
+	}
+	if len(restored.History) != 1 || restored.GetCurrentContext() == nil {
+		t.Errorf("history/context not restored")
+	}
+	if _, ok := restored.ClarificationOption("opt_1"); !ok {
+		t.Error("pending clarification option not restored")
+	}
+	if restored.GetTraceRecorder() == nil {
+		t.Error("restored session has no trace recorder")
+	}
+
+	if _, _, err := decodeSessionRecord([]byte(`{"state":"IDLE"}`)); !errors.Is(err, ErrInvalidSession) {
+		t.Errorf("record without ID: err = %v, want ErrInvalidSession", err)
+	}
+}
+
+func TestSharedSessionStore_ContinueOnOtherInstance(t *testing.T) {
+	backend := newTestBadgerRecordStore(t)
+
+	// Instance A stops in CLARIFY; instance B answers the clarification.
+	registryA := NewMockPhaseRegistry()
+	registryA.RegisterPhase(StateInit, &MockPhase{name: "init", nextState: StatePlan})
+	registryA.RegisterPhase(StatePlan, &MockPhase{name: "plan", nextState: StateClarify})
+	registryB := NewMockPhaseRegistry()
+	registryB.RegisterPhase(StateClarify, &MockPhase{name: "clarify", nextState: StatePlan})
+	registryB.RegisterPhase(StatePlan, &MockPhase{name: "plan", nextState: StateExecute})
+	registryB.RegisterPhase(StateExecute, &MockPhase{name: "execute", nextState: StateComplete})
+
+	storeA := NewSharedSessionStore(backend, "replica-a", nil)
+	storeB := NewSharedSessionStore(backend, "replica-b", nil)
+	loopA := NewDefaultAgentLoop(WithSessionStore(storeA), WithPhaseRegistry(registryA))
+	loopB := NewDefaultAgentLoop(WithSessionStore(storeB), WithPhaseRegistry(registryB))
+
+	session, _ := NewSession("/test/project", nil)
+	result, err := loopA.Run(context.Background(), session, "where is Handler?")
+	if err != nil || result.State != StateClarify {
+		t.Fatalf("Run on A = %v, %v; want CLARIFY", result, err)
+	}
+
+	result, err = loopB.Continue(context.Background(), session.ID, "the auth one")
+	if err != nil {
+		t.Fatalf("Continue on B: %v", err)
+	}
+	if result.State != StateComplete {
+		t.Fatalf("Continue on B state = %s, want COMPLETE", result.State)
+	}
+
+	// A picks up B's newer revision instead of its stale local copy.
+	state, err := loopA.GetState(session.ID)
+	if err != nil || state.State != StateComplete {
+		t.Errorf("A sees state %v, %v; want COMPLETE", state, err)
+	}
+	restored, _ := storeA.Get(session.ID)
+	if restored == session {
+		t.Error("A kept its stale local session")
+	}
+
+	// Deleting on one instance removes the shared record.
+	storeB.Delete(session.ID)
+	if _, err := backend.LoadSessionRecord(context.Background(), session.ID); !errors.Is(err, ErrSessionRecordNotFound) {
+		t.Errorf("record after Delete: err = %v, want ErrSessionRecordNotFound", err)
+	}
+}
+
+func TestSharedSessionStore_KeepsInProgressLocalSession(t *testing.T) {
+	backend := newTestBadgerRecordStore(t)
+	storeA := NewSharedSessionStore(backend, "replica-a", nil)
+	storeB := NewSharedSessionStore(backend, "replica-b", nil)
+
+	session, _ := NewSession("/test/project", nil)
+	storeA.Put(session)
+	other, ok := storeB.Get(session.ID)
+	if !ok {
+		t.Fatal("B could not restore the session")
+	}
+	other.SetState(StateComplete)
+	storeB.Put(other)
+	storeB.Put(other)
+
+	// A session held by a run on A is not swapped out under it.
+	session.TryAcquire()
+	if got, _ := storeA.Get(session.ID); got != session {
+		t.Error("in-progress local session was replaced")
+	}
+	session.Release()
+	if got, _ := storeA.Get(session.ID); got == session || got.GetState() != StateComplete {
+		t.Error("idle local session was not refreshed from the newer record")
+	}
+}
+
+// failingRecordStore fails every operation.
+type failingRecordStore struct{}
+
+func (failingRecordStore) LoadSessionRecord(context.Context, string) ([]byte, error) {
+	return nil, errors.New("backend down")
+}
+func (failingRecordStore) SaveSessionRecord(context.Context, string, []byte) error {
+	return errors.New("backend down")
+}
+func (failingRecordStore) DeleteSessionRecord(context.Context, string) error {
+	return errors.New("backend down")
+}
+
+func TestSharedSessionStore_BackendFailureFallsBackToLocal(t *testing.T) {
+	store := NewSharedSessionStore(failingRecordStore{}, "replica-a", nil)
+	session, _ := NewSession("/test/project", nil)
+	store.Put(session)
+	if got, ok := store.Get(session.ID); !ok || got != session {
+		t.Errorf("Get = %v, %v; want the local session", got, ok)
+	}
+	if _, ok := store.Get("missing"); ok {
+		t.Error("Get of unknown session succeeded")
+	}
+	store.Delete(session.ID)
+	if ids := store.List(); len(ids) != 0 {
+		t.Errorf("List after Delete = %v", ids)
+	}
+}
+
+// fakeRedisClient is an in-memory RedisClient.
+type fakeRedisClient struct {
+	mu   sync.Mutex
+	data map[string][]byte
+	ttls map[string]time.Duration
+}
+
+func (c *fakeRedisClient) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	if !ok {
+		return nil, redis.ErrNil
+	}
+	return v, nil
+}
+
+func (c *fakeRedisClient) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	c.ttls[key] = ttl
+	return nil
+}
+
+func (c *fakeRedisClient) Del(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.data, key)
+	return nil
+}
+
+func TestRedisSessionRecordStore(t *testing.T) {
+	client := &fakeRedisClient{data: map[string][]byte{}, ttls: map[string]time.Duration{}}
+	store, err := NewRedisSessionRecordStore(client, 30*time.Minute)
+	if err != nil {
+		t.Fatalf("NewRedisSessionRecordStore: %v", err)
+	}
+	ctx := context.Background()
+
+	if _, err := store.LoadSessionRecord(ctx, "s1"); !errors.Is(err, ErrSessionRecordNotFound) {
+		t.Errorf("missing record: err = %v, want ErrSessionRecordNotFound", err)
+	}
+	if err := store.SaveSessionRecord(ctx, "s1", []byte(`{"id":"s1"}`)); err != nil {
+		t.Fatalf("SaveSessionRecord: %v", err)
+	}
+	if client.ttls["trace:session:s1"] != 30*time.Minute {
+		t.Errorf("ttl = %v, want 30m", client.ttls["trace:session:s1"])
+	}
+	if data, err := store.LoadSessionRecord(ctx, "s1"); err != nil || string(data) != `{"id":"s1"}` {
+		t.Errorf("LoadSessionRecord = %q, %v", data, err)
+	}
+	if err := store.DeleteSessionRecord(ctx, "s1"); err != nil {
+		t.Fatalf("DeleteSessionRecord: %v", err)
+	}
+	if _, err := store.LoadSessionRecord(ctx, "s1"); !errors.Is(err, ErrSessionRecordNotFound) {
+		t.Errorf("deleted record: err = %v, want ErrSessionRecordNotFound", err)
+	}
+}
@@ -43,6 +43,9 @@ import (
 //	DEGRADED → ERROR             : Degraded mode failed
 //	* → ERROR                    : Any state can transition to ERROR
 //
+// ABORTED is entered and left outside the table: Abort forces it and
+// Continue restores the checkpointed phase.
+//
 // Thread Safety:
 //
 //	StateMachine is safe for concurrent use.
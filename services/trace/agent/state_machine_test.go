@@ -297,6 +297,7 @@ func TestAgentState_IsTerminal(t *testing.T) {
 		{StateDegraded, false},
 		{StateComplete, true},
 		{StateError, true},
+		{StateAborted, true},
 	}
 
 	for _, tt := range tests {
@@ -357,7 +358,7 @@ func TestDefaultStateMachine(t *testing.T) {
 func TestAllStates(t *testing.T) {
 	states := AllStates()
 
-	expected := 9 // IDLE, INIT, PLAN, EXECUTE, REFLECT, CLARIFY, DEGRADED, COMPLETE, ERROR
+	expected := 10 // IDLE, INIT, PLAN, EXECUTE, REFLECT, CLARIFY, DEGRADED, COMPLETE, ERROR, ABORTED
 	if len(states) != expected {
 		t.Errorf("expected %d states, got %d", expected, len(states))
 	}
@@ -371,7 +372,7 @@ func TestAllStates(t *testing.T) {
 	expectedStates := []AgentState{
 		StateIdle, StateInit, StatePlan, StateExecute,
 		StateReflect, StateClarify, StateDegraded,
-		StateComplete, StateError,
+		StateComplete, StateError, StateAborted,
 	}
 
 	for _, s := range expectedStates {
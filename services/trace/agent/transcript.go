@@ -0,0 +1,381 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"maps"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// transcriptContentLimit caps message and tool output text in transcripts.
+const transcriptContentLimit = 4000
+
+// transcriptToolResultLimit caps the output recorded with each tool call.
+// Lower than transcriptContentLimit because a run makes many calls.
+const transcriptToolResultLimit = 1000
+
+// TraceInvocationIDKey is the trace step metadata key holding the ID of
+// the tool invocation the step records. BuildTranscript uses it to find
+// the call's output in AssembledContext.ToolResults.
+const TraceInvocationIDKey = "invocation_id"
+
+// SessionTranscript is the persisted record of a completed (or paused) agent run.
+//
+// Description:
+//
+//	Captures everything needed to review a run after the fact: the query,
+//	phase transitions, tool calls with arguments and truncated results,
+//	conversation messages, the final answer and token accounting. Built
+//	from live session state by BuildTranscript and stored by TranscriptStore.
+type SessionTranscript struct {
+	// Summary holds the listing fields (id, project, status, timestamps).
+	SessionTranscriptSummary
+
+	// Transitions lists phase transitions in order.
+	Transitions []TranscriptTransition `json:"transitions"`
+
+	// ToolCalls lists tool invocations in order.
+	ToolCalls []TranscriptToolCall `json:"tool_calls"`
+
+	// Messages is the LLM conversation (content truncated).
+	Messages []TranscriptMessage `json:"messages"`
+
+	// Summarizations lists the times older turns were replaced by a
+	// session summary.
+	Summarizations []TranscriptSummarization `json:"summarizations,omitempty"`
+
+	// Clarifications lists the user's answers to clarification requests
+	// and whether each was typed or a selected option.
+	Clarifications []TranscriptClarification `json:"clarifications,omitempty"`
+
+	// RoutingDecisions explain the tool router's choices, oldest first.
+	RoutingDecisions []RoutingDecision `json:"routing_decisions,omitempty"`
+
+	// Response is the final answer, if any.
+	Response string `json:"response,omitempty"`
+
+	// Error is the terminal error message, if any.
+	Error string `json:"error,omitempty"`
+
+	// Usage is token and call accounting for the run.
+	Usage TranscriptUsage `json:"usage"`
+}
+
+// SessionTranscriptSummary is the listing view of a persisted session.
+type SessionTranscriptSummary struct {
+	// SessionID is the agent session ID.
+	SessionID string `json:"session_id"`
+
+	// ProjectRoot is the project the session ran against.
+	ProjectRoot string `json:"project_root"`
+
+	// Query is the initial user query.
+	Query string `json:"query"`
+
+	// Status is the agent state when the transcript was saved.
+	Status AgentState `json:"status"`
+
+	// CreatedAt is when the session was created (Unix milliseconds UTC).
+	CreatedAt int64 `json:"created_at"`
+
+	// UpdatedAt is when the transcript was last saved (Unix milliseconds UTC).
+	UpdatedAt int64 `json:"updated_at"`
+
+	// StepsTaken is the number of agent steps executed.
+	StepsTaken int `json:"steps_taken"`
+
+	// TokensUsed is the total tokens consumed.
+	TokensUsed int `json:"tokens_used"`
+}
+
+// TranscriptTransition records a single phase transition.
+type TranscriptTransition struct {
+	// Description is "FROM -> TO: reason".
+	Description string `json:"description"`
+
+	// Timestamp is when the transition occurred (Unix milliseconds UTC).
+	Timestamp int64 `json:"timestamp"`
+}
+
+// TranscriptToolCall records a single tool invocation.
+type TranscriptToolCall struct {
+	// Step is the trace step number.
+	Step int `json:"step"`
+
+	// Tool is the tool name.
+	Tool string `json:"tool"`
+
+	// Arguments are the tool parameters (values truncated upstream).
+	Arguments map[string]string `json:"arguments,omitempty"`
+
+	// SymbolsFound lists symbols discovered by the call.
+	SymbolsFound []string `json:"symbols_found,omitempty"`
+
+	// DurationMs is how long the call took.
+	DurationMs int64 `json:"duration_ms"`
+
+	// Result is the call's output, truncated to transcriptToolResultLimit.
+	// Empty if the output is no longer in the session context.
+	Result string `json:"result,omitempty"`
+
+	// Error is set if the call failed.
+	Error string `json:"error,omitempty"`
+
+	// Timestamp is when the call occurred (Unix milliseconds UTC).
+	Timestamp int64 `json:"timestamp"`
+}
+
+// TranscriptMessage is one conversation message.
+type TranscriptMessage struct {
+	// Role is "user", "assistant", "tool" or "system".
+	Role string `json:"role"`
+
+	// Content is the message content, truncated to transcriptContentLimit.
+	Content string `json:"content"`
+
+	// Truncated is true if Content was cut.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// ToolCalls lists tool names requested by an assistant message.
+	ToolCalls []string `json:"tool_calls,omitempty"`
+}
+
+// TranscriptSummarization records older conversation turns being replaced
+// by a session summary.
+type TranscriptSummarization struct {
+	// Description states how many messages and tokens were condensed.
+	Description string `json:"description"`
+
+	// Summary is the session summary message (truncated).
+	Summary string `json:"summary"`
+
+	// TokensUsed is the tokens the summarization call consumed.
+	TokensUsed int `json:"tokens_used"`
+
+	// Timestamp is when the summarization occurred (Unix milliseconds UTC).
+	Timestamp int64 `json:"timestamp"`
+}
+
+// TranscriptClarification is one answer to a clarification request.
+type TranscriptClarification struct {
+	// Question is the clarification question, if known.
+	Question string `json:"question,omitempty"`
+
+	// Path is ClarificationPathOption or ClarificationPathFreeText.
+	Path string `json:"path"`
+
+	// OptionID is the selected option, for the option path.
+	OptionID string `json:"option_id,omitempty"`
+
+	// Response is the clarification sent to the agent (truncated).
+	Response string `json:"response"`
+
+	// Timestamp is when the answer was received (Unix milliseconds UTC).
+	Timestamp int64 `json:"timestamp"`
+}
+
+// TranscriptUsage is token and call accounting for a run.
+type TranscriptUsage struct {
+	// TotalTokens is the total tokens consumed.
+	TotalTokens int `json:"total_tokens"`
+
+	// LLMCalls is the number of LLM API calls.
+	LLMCalls int `json:"llm_calls"`
+
+	// ToolCalls is the number of tool invocations.
+	ToolCalls int `json:"tool_calls"`
+
+	// ToolErrors is the number of failed tool calls.
+	ToolErrors int `json:"tool_errors"`
+
+	// DurationMs is the total execution time in milliseconds.
+	DurationMs int64 `json:"duration_ms"`
+
+	// PromptTokens and CompletionTokens split the session's tokens as
+	// accounted per LLM call. Zero when usage accounting is disabled.
+	PromptTokens     int `json:"prompt_tokens,omitempty"`
+	CompletionTokens int `json:"completion_tokens,omitempty"`
+
+	// CostUSD is the session's cost in US dollars, for calls with a price.
+	CostUSD float64 `json:"cost_usd,omitempty"`
+
+	// Estimated is true if any call's token counts were estimated.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// BuildTranscript snapshots a session into a SessionTranscript.
+//
+// Description:
+//
+//	Reads history, trace steps, conversation and metrics from the session.
+//	Long message content is truncated to keep persisted transcripts bounded.
+//
+// Inputs:
+//
+//	session - The session to snapshot. Must not be nil.
+//	result - The run result, if available. May be nil.
+//
+// Outputs:
+//
+//	*SessionTranscript - The transcript.
+//
+// Thread Safety: Safe for concurrent use (reads session via its accessors).
+func BuildTranscript(session *Session, result *RunResult) *SessionTranscript {
+	metrics := session.GetMetrics()
+	history := session.GetHistory()
+
+	t := &SessionTranscript{
+		SessionTranscriptSummary: SessionTranscriptSummary{
+			SessionID:   session.ID,
+			ProjectRoot: session.GetProjectRoot(),
+			Status:      session.GetState(),
+			CreatedAt:   session.CreatedAt,
+			UpdatedAt:   time.Now().UnixMilli(),
+			StepsTaken:  metrics.TotalSteps,
+			TokensUsed:  metrics.TotalTokens,
+		},
+		Usage: TranscriptUsage{
+			TotalTokens: metrics.TotalTokens,
+			LLMCalls:    metrics.LLMCalls,
+			ToolCalls:   metrics.ToolCalls,
+			ToolErrors:  metrics.ToolErrors,
+			DurationMs:  metrics.TotalDurationMs,
+		},
+	}
+
+	for _, entry := range history {
+		switch entry.Type {
+		case "state_transition":
+			t.Transitions = append(t.Transitions, TranscriptTransition{
+				Description: entry.Input,
+				Timestamp:   entry.Timestamp,
+			})
+		case "clarification":
+			response, _ := truncateTranscriptContent(entry.Input)
+			answer := ClarificationResponse{OptionID: entry.ClarificationOptionID}
+			t.Clarifications = append(t.Clarifications, TranscriptClarification{
+				Question:  entry.ClarificationPrompt,
+				Path:      answer.Path(),
+				OptionID:  entry.ClarificationOptionID,
+				Response:  response,
+				Timestamp: entry.Timestamp,
+			})
+		case historySummaryEntryType:
+			summary, _ := truncateTranscriptContent(entry.Output)
+			t.Summarizations = append(t.Summarizations, TranscriptSummarization{
+				Description: entry.Input,
+				Summary:     summary,
+				TokensUsed:  entry.TokensUsed,
+				Timestamp:   entry.Timestamp,
+			})
+		}
+		if t.Query == "" && entry.Query != "" {
+			t.Query = entry.Query
+		}
+		if entry.Error != "" {
+			t.Error = entry.Error
+		}
+	}
+	if t.Query == "" {
+		t.Query = session.LastQuery
+	}
+
+	// Outputs by invocation ID, in order: providers may reuse IDs across
+	// turns, so each step takes the oldest output left for its ID.
+	ctx := session.GetCurrentContext()
+	outputs := make(map[string][]string)
+	if ctx != nil {
+		for _, r := range ctx.ToolResults {
+			if r.InvocationID != "" {
+				outputs[r.InvocationID] = append(outputs[r.InvocationID], r.Output)
+			}
+		}
+	}
+
+	for _, step := range session.GetTraceSteps() {
+		if step.Tool == "" {
+			continue
+		}
+		call := TranscriptToolCall{
+			Step:         step.Step,
+			Tool:         step.Tool,
+			Arguments:    step.Metadata,
+			SymbolsFound: step.SymbolsFound,
+			DurationMs:   step.Duration.Milliseconds(),
+			Error:        step.Error,
+			Timestamp:    step.Timestamp,
+		}
+		if id, ok := step.Metadata[TraceInvocationIDKey]; ok {
+			call.Arguments = maps.Clone(step.Metadata)
+			delete(call.Arguments, TraceInvocationIDKey)
+			if queue := outputs[id]; len(queue) > 0 {
+				call.Result, _ = truncateTranscriptText(queue[0], transcriptToolResultLimit)
+				outputs[id] = queue[1:]
+			}
+		}
+		t.ToolCalls = append(t.ToolCalls, call)
+	}
+
+	t.RoutingDecisions = session.RoutingDecisions()
+
+	if ctx != nil {
+		for _, msg := range ctx.ConversationHistory {
+			content, truncated := truncateTranscriptContent(msg.Content)
+			tm := TranscriptMessage{
+				Role:      msg.Role,
+				Content:   content,
+				Truncated: truncated,
+			}
+			for _, call := range msg.ToolCalls {
+				tm.ToolCalls = append(tm.ToolCalls, call.Tool)
+			}
+			t.Messages = append(t.Messages, tm)
+		}
+	}
+
+	if result != nil {
+		if result.State != "" {
+			t.Status = result.State
+		}
+		t.Response = result.Response
+		if result.Error != nil {
+			t.Error = result.Error.Message
+		}
+		if result.StepsTaken > t.StepsTaken {
+			t.StepsTaken = result.StepsTaken
+		}
+		if result.TokensUsed > t.TokensUsed {
+			t.TokensUsed = result.TokensUsed
+			t.Usage.TotalTokens = result.TokensUsed
+		}
+	}
+
+	return t
+}
+
+// truncateTranscriptContent cuts s to transcriptContentLimit bytes.
+func truncateTranscriptContent(s string) (string, bool) {
+	return truncateTranscriptText(s, transcriptContentLimit)
+}
+
+// truncateTranscriptText cuts s to limit bytes.
+func truncateTranscriptText(s string, limit int) (string, bool) {
+	if len(s) <= limit {
+		return s, false
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return strings.TrimRight(s[:cut], " \n") + "\n... [truncated]", true
+}
@@ -0,0 +1,391 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/badger/v4"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// BadgerDB key prefixes for session transcripts.
+const (
+	keyPrefixSession    = "agent:sess:"
+	keySuffixSessMeta   = ":meta"
+	keySuffixSessData   = ":data"
+	defaultSessionLimit = 50
+)
+
+// ErrTranscriptNotFound is returned when a session transcript does not exist.
+var ErrTranscriptNotFound = errors.New("session transcript not found")
+
+// TranscriptRetention bounds how many transcripts are kept.
+type TranscriptRetention struct {
+	// MaxCount is the maximum number of transcripts kept. Zero disables.
+	MaxCount int
+
+	// MaxAge is the maximum transcript age (by UpdatedAt). Zero disables.
+	MaxAge time.Duration
+}
+
+// TranscriptFilter selects transcripts in List.
+type TranscriptFilter struct {
+	// ProjectRoot filters by exact project root. Empty matches all.
+	ProjectRoot string
+
+	// Status filters by agent state. Empty matches all.
+	Status AgentState
+
+	// Limit is the page size. If <= 0, defaults to 50.
+	Limit int
+
+	// Offset is the number of matching transcripts to skip.
+	Offset int
+}
+
+// TranscriptStore persists agent session transcripts in BadgerDB.
+//
+// Description:
+//
+//	Stores each transcript as a small summary record (for listing) and a
+//	full JSON record (for retrieval). Retention is enforced after every
+//	Save by count and age.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use. BadgerDB handles its own concurrency control.
+type TranscriptStore struct {
+	db        *badger.DB
+	logger    *slog.Logger
+	retention TranscriptRetention
+}
+
+// NewTranscriptStore creates a new TranscriptStore.
+//
+// Inputs:
+//
+//	db - An opened BadgerDB instance. Must not be nil.
+//	logger - Logger for diagnostic output. Must not be nil.
+//	retention - Retention limits applied after each save.
+//
+// Outputs:
+//
+//	*TranscriptStore - The configured store.
+//	error - Non-nil if db or logger is nil.
+func NewTranscriptStore(db *badger.DB, logger *slog.Logger, retention TranscriptRetention) (*TranscriptStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("badger db must not be nil")
+	}
+	if logger == nil {
+		return nil, fmt.Errorf("logger must not be nil")
+	}
+	return &TranscriptStore{db: db, logger: logger, retention: retention}, nil
+}
+
+// Save persists a transcript, replacing any previous version for the session.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. Must not be nil.
+//	t - The transcript. Must not be nil and must have a SessionID.
+//
+// Outputs:
+//
+//	error - Non-nil if marshaling or storage fails.
+//
+// Key Schema:
+//
+//	agent:sess:{sessionID}:meta → JSON(SessionTranscriptSummary)
+//	agent:sess:{sessionID}:data → JSON(SessionTranscript)
+func (s *TranscriptStore) Save(ctx context.Context, t *SessionTranscript) error {
+	if ctx == nil {
+		return fmt.Errorf("ctx must not be nil")
+	}
+	if t == nil || t.SessionID == "" {
+		return fmt.Errorf("transcript must have a session ID")
+	}
+
+	metaJSON, err := json.Marshal(t.SessionTranscriptSummary)
+	if err != nil {
+		return fmt.Errorf("marshaling transcript summary: %w", err)
+	}
+	dataJSON, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("marshaling transcript: %w", err)
+	}
+
+	err = s.db.Update(func(txn *badger.Txn) error {
+		if err := txn.Set(sessionMetaKey(t.SessionID), metaJSON); err != nil {
+			return fmt.Errorf("writing summary: %w", err)
+		}
+		if err := txn.Set(sessionDataKey(t.SessionID), dataJSON); err != nil {
+			return fmt.Errorf("writing transcript: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("saving transcript %s: %w", t.SessionID, err)
+	}
+
+	if _, err := s.Prune(ctx); err != nil {
+		s.logger.Warn("transcript retention prune failed", telemetry.SensitiveLogError("error", err))
+	}
+	return nil
+}
+
+// Get loads the full transcript for a session.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. Must not be nil.
+//	sessionID - The session ID.
+//
+// Outputs:
+//
+//	*SessionTranscript - The transcript.
+//	error - ErrTranscriptNotFound if missing, or a storage error.
+func (s *TranscriptStore) Get(ctx context.Context, sessionID string) (*SessionTranscript, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("ctx must not be nil")
+	}
+
+	var t SessionTranscript
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(sessionDataKey(sessionID))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(val []byte) error {
+			return json.Unmarshal(val, &t)
+		})
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return nil, ErrTranscriptNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading transcript %s: %w", sessionID, err)
+	}
+	return &t, nil
+}
+
+// List returns transcript summaries matching the filter, newest first.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. Must not be nil.
+//	filter - Project/status filter and pagination.
+//
+// Outputs:
+//
+//	[]*SessionTranscriptSummary - The requested page.
+//	int - Total number of matching transcripts (before pagination).
+//	error - Non-nil if the read fails.
+func (s *TranscriptStore) List(ctx context.Context, filter TranscriptFilter) ([]*SessionTranscriptSummary, int, error) {
+	if ctx == nil {
+		return nil, 0, fmt.Errorf("ctx must not be nil")
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultSessionLimit
+	}
+	offset := filter.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	all, err := s.listAll()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	matched := all[:0]
+	for _, summary := range all {
+		if filter.ProjectRoot != "" && summary.ProjectRoot != filter.ProjectRoot {
+			continue
+		}
+		if filter.Status != "" && summary.Status != filter.Status {
+			continue
+		}
+		matched = append(matched, summary)
+	}
+
+	total := len(matched)
+	if offset >= total {
+		return []*SessionTranscriptSummary{}, total, nil
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return matched[offset:end], total, nil
+}
+
+// Delete removes a session transcript.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. Must not be nil.
+//	sessionID - The session ID.
+//
+// Outputs:
+//
+//	error - ErrTranscriptNotFound if missing, or a storage error.
+func (s *TranscriptStore) Delete(ctx context.Context, sessionID string) error {
+	if ctx == nil {
+		return fmt.Errorf("ctx must not be nil")
+	}
+
+	err := s.db.Update(func(txn *badger.Txn) error {
+		if _, err := txn.Get(sessionMetaKey(sessionID)); err != nil {
+			return err
+		}
+		return deleteSessionKeys(txn, sessionID)
+	})
+	if errors.Is(err, badger.ErrKeyNotFound) {
+		return ErrTranscriptNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("deleting transcript %s: %w", sessionID, err)
+	}
+	return nil
+}
+
+// Prune enforces the retention policy.
+//
+// Description:
+//
+//	Deletes transcripts older than MaxAge, then the oldest transcripts
+//	beyond MaxCount.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. Must not be nil.
+//
+// Outputs:
+//
+//	int - Number of transcripts removed.
+//	error - Non-nil if the read or delete fails.
+func (s *TranscriptStore) Prune(ctx context.Context) (int, error) {
+	if ctx == nil {
+		return 0, fmt.Errorf("ctx must not be nil")
+	}
+	if s.retention.MaxCount <= 0 && s.retention.MaxAge <= 0 {
+		return 0, nil
+	}
+
+	all, err := s.listAll()
+	if err != nil {
+		return 0, err
+	}
+
+	var expired []string
+	kept := 0
+	cutoff := int64(0)
+	if s.retention.MaxAge > 0 {
+		cutoff = time.Now().Add(-s.retention.MaxAge).UnixMilli()
+	}
+	for _, summary := range all {
+		if cutoff > 0 && summary.UpdatedAt < cutoff {
+			expired = append(expired, summary.SessionID)
+			continue
+		}
+		kept++
+		if s.retention.MaxCount > 0 && kept > s.retention.MaxCount {
+			expired = append(expired, summary.SessionID)
+		}
+	}
+	if len(expired) == 0 {
+		return 0, nil
+	}
+
+	err = s.db.Update(func(txn *badger.Txn) error {
+		for _, id := range expired {
+			if err := deleteSessionKeys(txn, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("pruning transcripts: %w", err)
+	}
+
+	s.logger.Debug("pruned session transcripts", slog.Int("count", len(expired)))
+	return len(expired), nil
+}
+
+// listAll reads every transcript summary, newest first.
+func (s *TranscriptStore) listAll() ([]*SessionTranscriptSummary, error) {
+	var results []*SessionTranscriptSummary
+
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.Prefix = []byte(keyPrefixSession)
+
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		for it.Seek(opts.Prefix); it.Valid(); it.Next() {
+			item := it.Item()
+			key := string(item.Key())
+			if !strings.HasSuffix(key, keySuffixSessMeta) {
+				continue
+			}
+
+			var summary SessionTranscriptSummary
+			err := item.Value(func(val []byte) error {
+				return json.Unmarshal(val, &summary)
+			})
+			if err != nil {
+				s.logger.Warn("skipping corrupt transcript summary", slog.String("key", key), telemetry.SensitiveLogError("error", err))
+				continue
+			}
+			results = append(results, &summary)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("listing transcripts: %w", err)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].UpdatedAt > results[j].UpdatedAt
+	})
+	return results, nil
+}
+
+// deleteSessionKeys removes both keys for a session within txn.
+func deleteSessionKeys(txn *badger.Txn, sessionID string) error {
+	if err := txn.Delete(sessionMetaKey(sessionID)); err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+		return fmt.Errorf("deleting summary: %w", err)
+	}
+	if err := txn.Delete(sessionDataKey(sessionID)); err != nil && !errors.Is(err, badger.ErrKeyNotFound) {
+		return fmt.Errorf("deleting transcript: %w", err)
+	}
+	return nil
+}
+
+func sessionMetaKey(sessionID string) []byte {
+	return []byte(keyPrefixSession + sessionID + keySuffixSessMeta)
+}
+
+func sessionDataKey(sessionID string) []byte {
+	return []byte(keyPrefixSession + sessionID + keySuffixSessData)
+}
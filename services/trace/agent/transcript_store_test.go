@@ -0,0 +1,233 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/dgraph-io/badger/v4"
+)
+
+// newTestTranscriptStore creates a TranscriptStore backed by in-memory BadgerDB.
+func newTestTranscriptStore(t *testing.T, retention TranscriptRetention) *TranscriptStore {
+	t.Helper()
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("failed to open in-memory badger: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	store, err := NewTranscriptStore(db, logger, retention)
+	if err != nil {
+		t.Fatalf("NewTranscriptStore: %v", err)
+	}
+	return store
+}
+
+func testTranscript(id, project string, status AgentState, updatedAt int64) *SessionTranscript {
+	return &SessionTranscript{
+		SessionTranscriptSummary: SessionTranscriptSummary{
+			SessionID:   id,
+			ProjectRoot: project,
+			Query:       "query " + id,
+			Status:      status,
+			UpdatedAt:   updatedAt,
+		},
+		Response: "answer " + id,
+	}
+}
+
+func TestTranscriptStore_SaveGetDelete(t *testing.T) {
+	ctx := context.Background()
+	store := newTestTranscriptStore(t, TranscriptRetention{})
+
+	if err := store.Save(ctx, testTranscript("s1", "/p", StateComplete, time.Now().UnixMilli())); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	got, err := store.Get(ctx, "s1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Response != "answer s1" || got.ProjectRoot != "/p" {
+		t.Errorf("unexpected transcript: %+v", got)
+	}
+
+	if err := store.Delete(ctx, "s1"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get(ctx, "s1"); !errors.Is(err, ErrTranscriptNotFound) {
+		t.Errorf("expected ErrTranscriptNotFound after delete, got %v", err)
+	}
+	if err := store.Delete(ctx, "s1"); !errors.Is(err, ErrTranscriptNotFound) {
+		t.Errorf("expected ErrTranscriptNotFound on second delete, got %v", err)
+	}
+}
+
+func TestTranscriptStore_ListFilterAndPaginate(t *testing.T) {
+	ctx := context.Background()
+	store := newTestTranscriptStore(t, TranscriptRetention{})
+	now := time.Now().UnixMilli()
+
+	for i := 0; i < 5; i++ {
+		status := StateComplete
+		if i%2 == 1 {
+			status = StateError
+		}
+		project := "/a"
+		if i == 4 {
+			project = "/b"
+		}
+		if err := store.Save(ctx, testTranscript(fmt.Sprintf("s%d", i), project, status, now+int64(i))); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+
+	all, total, err := store.List(ctx, TranscriptFilter{})
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if total != 5 || len(all) != 5 || all[0].SessionID != "s4" {
+		t.Errorf("expected 5 newest-first, got total=%d first=%s", total, all[0].SessionID)
+	}
+
+	page, total, _ := store.List(ctx, TranscriptFilter{ProjectRoot: "/a", Limit: 2, Offset: 1})
+	if total != 4 || len(page) != 2 || page[0].SessionID != "s2" {
+		t.Errorf("unexpected page: total=%d len=%d", total, len(page))
+	}
+
+	errored, total, _ := store.List(ctx, TranscriptFilter{Status: StateError})
+	if total != 2 || len(errored) != 2 {
+		t.Errorf("expected 2 errored sessions, got %d", total)
+	}
+
+	empty, total, _ := store.List(ctx, TranscriptFilter{Offset: 10})
+	if total != 5 || len(empty) != 0 {
+		t.Errorf("expected empty page past end, got %d", len(empty))
+	}
+}
+
+func TestTranscriptStore_Retention(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("max count keeps newest", func(t *testing.T) {
+		store := newTestTranscriptStore(t, TranscriptRetention{MaxCount: 2})
+		now := time.Now().UnixMilli()
+		for i := 0; i < 4; i++ {
+			if err := store.Save(ctx, testTranscript(fmt.Sprintf("s%d", i), "/p", StateComplete, now+int64(i))); err != nil {
+				t.Fatalf("Save: %v", err)
+			}
+		}
+		list, total, _ := store.List(ctx, TranscriptFilter{})
+		if total != 2 || list[0].SessionID != "s3" || list[1].SessionID != "s2" {
+			t.Errorf("expected s3,s2 retained, got %d sessions", total)
+		}
+	})
+
+	t.Run("max age drops expired", func(t *testing.T) {
+		store := newTestTranscriptStore(t, TranscriptRetention{MaxAge: time.Hour})
+		old := time.Now().Add(-2 * time.Hour).UnixMilli()
+		if err := store.Save(ctx, testTranscript("old", "/p", StateComplete, old)); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		if err := store.Save(ctx, testTranscript("new", "/p", StateComplete, time.Now().UnixMilli())); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+		if _, err := store.Get(ctx, "old"); !errors.Is(err, ErrTranscriptNotFound) {
+			t.Errorf("expected old transcript pruned, got %v", err)
+		}
+		if _, err := store.Get(ctx, "new"); err != nil {
+			t.Errorf("expected new transcript kept, got %v", err)
+		}
+	})
+}
+
+func TestBuildTranscript(t *testing.T) {
+	session, err := NewSession("/proj", nil)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	session.AddHistoryEntry(HistoryEntry{Type: "query", Query: "how does auth work?"})
+	session.AddHistoryEntry(HistoryEntry{Type: "state_transition", Input: "IDLE -> INIT: start"})
+	session.SetCurrentContext(&AssembledContext{
+		ConversationHistory: []Message{
+			{Role: "user", Content: "how does auth work?"},
+			{Role: "tool", Content: strings.Repeat("x", transcriptContentLimit*2)},
+		},
+	})
+
+	tr := BuildTranscript(session, &RunResult{State: StateComplete, Response: "It uses JWT.", TokensUsed: 42})
+
+	if tr.Query != "how does auth work?" {
+		t.Errorf("Query = %q", tr.Query)
+	}
+	if len(tr.Transitions) != 1 || tr.Transitions[0].Description != "IDLE -> INIT: start" {
+		t.Errorf("unexpected transitions: %+v", tr.Transitions)
+	}
+	if len(tr.Messages) != 2 || !tr.Messages[1].Truncated || len(tr.Messages[1].Content) > transcriptContentLimit+32 {
+		t.Errorf("expected truncated tool message, got %+v", tr.Messages)
+	}
+	if tr.Response != "It uses JWT." || tr.Usage.TotalTokens != 42 {
+		t.Errorf("unexpected response/usage: %q %+v", tr.Response, tr.Usage)
+	}
+}
+
+func TestBuildTranscript_ToolCallResults(t *testing.T) {
+	session, err := NewSession("/proj", nil)
+	if err != nil {
+		t.Fatalf("NewSession: %v", err)
+	}
+	output := "callers of Authenticate: " + strings.Repeat("y", transcriptToolResultLimit*2)
+	session.RecordTraceStep(crs.TraceStep{
+		Action:   "tool_call",
+		Tool:     "find_callers",
+		Metadata: map[string]string{"function_name": "Authenticate", TraceInvocationIDKey: "call_1"},
+	})
+	session.RecordTraceStep(crs.TraceStep{
+		Action:   "tool_call",
+		Tool:     "find_callees",
+		Metadata: map[string]string{"function_name": "Login", TraceInvocationIDKey: "call_2"},
+	})
+	session.SetCurrentContext(&AssembledContext{
+		ToolResults: []ToolResult{
+			{InvocationID: "call_1", Success: true, Output: output},
+			{InvocationID: "call_2", Success: true, Output: "Login calls Authenticate"},
+		},
+	})
+
+	tr := BuildTranscript(session, nil)
+
+	if len(tr.ToolCalls) != 2 {
+		t.Fatalf("expected 2 tool calls, got %+v", tr.ToolCalls)
+	}
+	long := tr.ToolCalls[0]
+	if !strings.HasPrefix(long.Result, "callers of Authenticate: ") {
+		t.Errorf("Result = %.60q, want the find_callers output", long.Result)
+	}
+	if !strings.HasSuffix(long.Result, "[truncated]") || len(long.Result) > transcriptToolResultLimit+32 {
+		t.Errorf("expected Result truncated to %d bytes, got %d", transcriptToolResultLimit, len(long.Result))
+	}
+	if _, ok := long.Arguments[TraceInvocationIDKey]; ok || long.Arguments["function_name"] != "Authenticate" {
+		t.Errorf("Arguments = %v, want the tool parameters only", long.Arguments)
+	}
+	if got := tr.ToolCalls[1].Result; got != "Login calls Authenticate" {
+		t.Errorf("Result = %q, want the find_callees output", got)
+	}
+}
@@ -59,6 +59,11 @@ const (
 
 	// StateError indicates an unrecoverable error occurred.
 	StateError AgentState = "ERROR"
+
+	// StateAborted indicates the user aborted the run. Continue resumes
+	// it from its last phase checkpoint when the session's AbortRecord is
+	// resumable.
+	StateAborted AgentState = "ABORTED"
 )
 
 // ModelType identifies the LLM model for format-aware processing.
@@ -122,13 +127,14 @@ func (s AgentState) String() string {
 	return string(s)
 }
 
-// IsTerminal returns true if the state is a terminal state (COMPLETE or ERROR).
+// IsTerminal returns true if the state is a terminal state (COMPLETE, ERROR
+// or ABORTED).
 //
 // Outputs:
 //
-//	bool - True if state is COMPLETE or ERROR
+//	bool - True if state is COMPLETE, ERROR or ABORTED
 func (s AgentState) IsTerminal() bool {
-	return s == StateComplete || s == StateError
+	return s == StateComplete || s == StateError || s == StateAborted
 }
 
 // IsActive returns true if the state allows continued execution.
@@ -149,7 +155,7 @@ func (s AgentState) IsActive() bool {
 //
 // Outputs:
 //
-//	[]AgentState - Slice containing all 9 valid states
+//	[]AgentState - Slice containing all 10 valid states
 func AllStates() []AgentState {
 	return []AgentState{
 		StateIdle,
@@ -161,6 +167,7 @@ func AllStates() []AgentState {
 		StateDegraded,
 		StateComplete,
 		StateError,
+		StateAborted,
 	}
 }
 
@@ -201,6 +208,10 @@ type HistoryEntry struct {
 
 	// ClarificationPrompt is the prompt shown when entering CLARIFY state.
 	ClarificationPrompt string `json:"clarification_prompt,omitempty"`
+
+	// ClarificationOptionID is the option selected to answer a
+	// clarification (for clarification entries answered by option).
+	ClarificationOptionID string `json:"clarification_option_id,omitempty"`
 }
 
 // SessionMetrics tracks metrics for a session.
@@ -211,6 +222,10 @@ type SessionMetrics struct {
 	// TotalTokens is the total tokens consumed.
 	TotalTokens int `json:"total_tokens"`
 
+	// TokensByProvider splits the tokens of main-model calls by the
+	// "provider/model" that served them.
+	TokensByProvider map[string]int `json:"tokens_by_provider,omitempty"`
+
 	// TotalDurationMs is the total execution time in milliseconds.
 	TotalDurationMs int64 `json:"total_duration_ms"`
 
@@ -235,6 +250,14 @@ type SessionMetrics struct {
 	// ToolForcingRetries is the number of tool forcing retries.
 	ToolForcingRetries int `json:"tool_forcing_retries"`
 
+	// ParallelToolCalls is the number of tool calls that ran concurrently
+	// with other independent plan steps.
+	ParallelToolCalls int `json:"parallel_tool_calls"`
+
+	// ParallelTimeSavedMs is the wall-clock time saved by running those
+	// calls concurrently, in milliseconds.
+	ParallelTimeSavedMs int `json:"parallel_time_saved_ms"`
+
 	// GraphStats contains Trace graph statistics.
 	GraphStats *GraphStats `json:"graph_stats,omitempty"`
 }
@@ -262,6 +285,14 @@ type RunResult struct {
 	// Response is the final response text (for COMPLETE state).
 	Response string `json:"response,omitempty"`
 
+	// Citations are the verified citations in Response, each tied to a
+	// symbol a tool call returned (for COMPLETE state).
+	Citations []Citation `json:"citations,omitempty"`
+
+	// UnverifiedCitations are citation markers in Response that do not
+	// match any symbol observed during the run.
+	UnverifiedCitations []UnverifiedCitation `json:"unverified_citations,omitempty"`
+
 	// NeedsClarify contains clarification details (for CLARIFY state).
 	NeedsClarify *ClarifyRequest `json:"needs_clarify,omitempty"`
 
@@ -280,6 +311,9 @@ type RunResult struct {
 	// Error contains error details (for ERROR state).
 	Error *AgentError `json:"error,omitempty"`
 
+	// Abort describes the abort (for ABORTED state).
+	Abort *AbortRecord `json:"abort,omitempty"`
+
 	// ReasoningSummary provides high-level metrics about the reasoning process.
 	// Populated when CRS is enabled for the session.
 	ReasoningSummary *ReasoningSummary `json:"reasoning_summary,omitempty"`
@@ -322,8 +356,9 @@ type ClarifyRequest struct {
 	// Question is what the agent needs to know.
 	Question string `json:"question"`
 
-	// Options are suggested clarification options.
-	Options []string `json:"options,omitempty"`
+	// Options are answers the user can select instead of typing, derived
+	// from the session's tool results. Empty when none could be derived.
+	Options []ClarificationOption `json:"options,omitempty"`
 
 	// Context explains why clarification is needed.
 	Context string `json:"context,omitempty"`
@@ -427,6 +462,18 @@ type ToolInvocation struct {
 	// echoed back in subsequent requests to preserve reasoning context.
 	// Empty for non-Gemini providers and older Gemini models.
 	ThoughtSignature string `json:"thought_signature,omitempty"`
+
+	// OutputVar names this step's result so later steps of the same batch
+	// can consume it as "$name". Empty if no step refers to it.
+	OutputVar string `json:"output_var,omitempty"`
+
+	// DependsOn lists the IDs or output variables of the steps this step
+	// waits for. When set, it replaces the implicit "$name" detection.
+	DependsOn []string `json:"depends_on,omitempty"`
+
+	// Group ties steps together: when one fails, the steps of the group
+	// that have not finished are cancelled. Empty steps fail independently.
+	Group string `json:"group,omitempty"`
 }
 
 // ToolResult contains the outcome of a tool execution.
@@ -467,6 +514,15 @@ type ToolResult struct {
 	// IT_CRS_03 AC-8: Higher values = stronger signal (exact match = 2, fuzzy = 1).
 	ProofDelta int `json:"proof_delta,omitempty"`
 
+	// NextCursor is set when a paged tool has more results. Repeating the
+	// call with cursor=NextCursor fetches the next page.
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// InvalidParameter names the argument that failed validation when the
+	// tool was rejected before running, so Reflect can have the arguments
+	// re-extracted. Empty otherwise.
+	InvalidParameter string `json:"invalid_parameter,omitempty"`
+
 	// ThoughtSignature is an opaque token from Gemini 3 models that must be
 	// echoed back in subsequent requests to preserve reasoning context.
 	// Empty for non-Gemini providers and older Gemini models.
@@ -566,6 +622,55 @@ func (e *AgentError) Unwrap() error {
 	return nil
 }
 
+// Context reduction actions, in the order they are applied.
+const (
+	// ReductionDropContextBlocks drops code context blocks, least relevant
+	// first.
+	ReductionDropContextBlocks = "drop_context_blocks"
+
+	// ReductionSummarizeHistory condenses the oldest messages into one
+	// summary message.
+	ReductionSummarizeHistory = "summarize_history"
+
+	// ReductionNarrowToolResults drops older tool results and truncates the
+	// rest after a prompt overflowed the context window.
+	ReductionNarrowToolResults = "narrow_tool_results"
+)
+
+// ContextReduction records one step taken to fit a prompt into a model's
+// context window.
+type ContextReduction struct {
+	// Action is one of the Reduction* constants.
+	Action string `json:"action"`
+
+	// Items names what was dropped or condensed: code context blocks as
+	// "file (symbol)", messages as "role#index", tool results by tool name.
+	Items []string `json:"items,omitempty"`
+
+	// TokensBefore is the estimated prompt size before the step.
+	TokensBefore int `json:"tokens_before"`
+
+	// TokensAfter is the estimated prompt size after the step.
+	TokensAfter int `json:"tokens_after"`
+}
+
+// ContextOverflow describes a prompt that still exceeded the model's
+// context window after reduction.
+type ContextOverflow struct {
+	// Model is the model whose window was exceeded.
+	Model string `json:"model"`
+
+	// EstimatedTokens is the estimated prompt size after reduction.
+	EstimatedTokens int `json:"estimated_tokens"`
+
+	// ReservedTokens is what the prompt had to leave free for the
+	// response.
+	ReservedTokens int `json:"reserved_tokens"`
+
+	// LimitTokens is the model's context window.
+	LimitTokens int `json:"limit_tokens"`
+}
+
 // QueryIntent represents the classified intent of a user query.
 type QueryIntent struct {
 	// Type is the query type (explore, modify, explain, debug, refactor).
@@ -657,6 +762,9 @@ type SessionState struct {
 	// GraphID is the Trace graph ID.
 	GraphID string `json:"graph_id,omitempty"`
 
+	// Scope is the graph view the session's tools are restricted to.
+	Scope string `json:"scope,omitempty"`
+
 	// State is the current agent state.
 	State AgentState `json:"state"`
 
@@ -674,6 +782,9 @@ type SessionState struct {
 
 	// DegradedMode indicates if running with limited tools.
 	DegradedMode bool `json:"degraded_mode"`
+
+	// Abort describes the abort of an ABORTED session.
+	Abort *AbortRecord `json:"abort,omitempty"`
 }
 
 // SessionSummary is a brief summary of a session for listing/debug endpoints.
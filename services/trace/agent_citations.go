@@ -0,0 +1,73 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// agentCitationsTotal counts citation markers in final answers by outcome,
+// so the hallucinated-citation rate is unverified / (verified + unverified).
+var agentCitationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "trace_agent_citations_total",
+	Help: "Citation markers in agent final answers by validation result",
+}, []string{"result"})
+
+// reportCitations records the citation validation outcome of a run.
+//
+// Description:
+//
+//	Counts verified and unverified citations, and emits a
+//	TypeCitationValidation event when the answer cites symbols that were
+//	not observed via tools.
+//
+// Inputs:
+//
+//	session - The agent session.
+//	result - The run result, with citations validated by the loop.
+//	logger - Request logger.
+func (h *AgentHandlers) reportCitations(session *agent.Session, result *agent.RunResult, logger *slog.Logger) {
+	if result == nil {
+		return
+	}
+	agentCitationsTotal.WithLabelValues("verified").Add(float64(len(result.Citations)))
+	if len(result.UnverifiedCitations) == 0 {
+		return
+	}
+	agentCitationsTotal.WithLabelValues("unverified").Add(float64(len(result.UnverifiedCitations)))
+
+	stripped := session.Config != nil && session.Config.StripUnverifiedCitations
+	logger.Warn("Agent answer cites unobserved symbols",
+		"session_id", session.ID,
+		"unverified", len(result.UnverifiedCitations),
+		"verified", len(result.Citations),
+		"stripped", stripped)
+
+	if h.svc == nil {
+		return
+	}
+	emitter := h.svc.EventEmitter()
+	if emitter == nil {
+		return
+	}
+	emitter.Emit(events.TypeCitationValidation, &events.CitationValidationData{
+		SessionID:  session.ID,
+		Verified:   len(result.Citations),
+		Unverified: result.UnverifiedCitations,
+		Citable:    session.Citations().Len(),
+		Stripped:   stripped,
+	})
+}
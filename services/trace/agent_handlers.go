@@ -22,14 +22,26 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
 	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/phases"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/usage"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/runqueue"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/gin-gonic/gin"
 	"github.com/nats-io/nats.go"
 	"github.com/prometheus/client_golang/prometheus"
@@ -48,6 +60,33 @@ type AgentHandlers struct {
 	// natsClient provides NATS access for CRS SSE streaming.
 	// CRS-27: Optional. If nil, SSE streaming endpoint returns 503.
 	natsClient NATSSSEProvider
+	// sessionStore persists session transcripts.
+	// Optional. If nil, the /sessions endpoints return 503.
+	sessionStore *agent.TranscriptStore
+	// routingFeedback is the router's usage-feedback index.
+	// Optional. If nil, the /debug/routing/feedback endpoints return 503.
+	routingFeedback *routing.FeedbackIndex
+	// prefilter is the tool pre-filter the execute phase routes with.
+	// Optional. Used by POST /routing/explain.
+	prefilter *routing.PreFilter
+	// contextBudget is the server-wide context assembly budget.
+	// Optional. If nil, SessionConfig defaults apply.
+	contextBudget *cbcontext.ContextBudget
+	// fixtureDir is where recorded runs' replay fixtures are saved.
+	// Optional. If empty, runs with "record" set are rejected.
+	fixtureDir string
+	// usage accounts the token usage of runs.
+	// Optional. If nil, responses and transcripts carry no usage.
+	usage *usage.Accountant
+	// instance describes this instance when several serve the same
+	// clients. Zero value: single instance, no hints.
+	instance InstanceConfig
+	// runQueue admits runs and continues to execution slots.
+	// Optional. If nil, every request runs at once.
+	runQueue *runqueue.Queue
+	// quick answers simple lookups without the agent loop.
+	// Optional. If nil, every run uses the full loop.
+	quick *quickAnswerer
 }
 
 // NATSSSEProvider provides NATS subscription capability for SSE streaming.
@@ -126,6 +165,91 @@ func WithRoleConfig(rc *providers.RoleConfig) AgentHandlersOption {
 	}
 }
 
+// WithSessionStore injects a transcript store into AgentHandlers.
+//
+// Description:
+//
+//	When provided, a transcript is saved after every run, continue and
+//	abort, and the GET/DELETE /sessions endpoints are enabled.
+func WithSessionStore(store *agent.TranscriptStore) AgentHandlersOption {
+	return func(h *AgentHandlers) {
+		h.sessionStore = store
+	}
+}
+
+// WithRoutingFeedback injects the router's feedback index into AgentHandlers.
+//
+// Description:
+//
+//	Enables GET/DELETE /debug/routing/feedback for inspecting and resetting
+//	the routing feedback store.
+func WithRoutingFeedback(fi *routing.FeedbackIndex) AgentHandlersOption {
+	return func(h *AgentHandlers) {
+		h.routingFeedback = fi
+	}
+}
+
+// WithPreFilter injects the tool pre-filter into AgentHandlers.
+//
+// Description:
+//
+//	POST /routing/explain runs the same pre-filter as the execute phase.
+//	Without one, the explanation covers the router only.
+func WithPreFilter(pf *routing.PreFilter) AgentHandlersOption {
+	return func(h *AgentHandlers) {
+		h.prefilter = pf
+	}
+}
+
+// WithContextBudget sets the server-wide context assembly budget.
+//
+// Description:
+//
+//	The budget seeds every new session's context settings (total tokens,
+//	per-symbol cap, file cap, ranking). Fields in a request's config
+//	override it per run.
+func WithContextBudget(budget cbcontext.ContextBudget) AgentHandlersOption {
+	return func(h *AgentHandlers) {
+		h.contextBudget = &budget
+	}
+}
+
+// WithFixtureRecording allows runs to be recorded to replay fixtures.
+//
+// Description:
+//
+//	Runs with "record" set save their LLM calls and tool executions to
+//	<dir>/<session_id>.json. Only enable in trusted environments:
+//	fixtures contain prompts, code context and tool output verbatim.
+func WithFixtureRecording(dir string) AgentHandlersOption {
+	return func(h *AgentHandlers) {
+		h.fixtureDir = dir
+	}
+}
+
+// WithUsageAccountant sets the accountant the provider factory reports
+// calls to, so run responses and transcripts include token usage and cost.
+func WithUsageAccountant(a *usage.Accountant) AgentHandlersOption {
+	return func(h *AgentHandlers) {
+		h.usage = a
+	}
+}
+
+// WithInstance configures the handlers for running several instances.
+//
+// Description:
+//
+//	With StickyHints, run and continue responses carry an InstanceHint.
+//	With SharedSessions, a session restored from another instance gets
+//	its tool router attached here, and continuing it returns
+//	409 GRAPH_NOT_INITIALIZED_HERE while its graph is not loaded on this
+//	instance.
+func WithInstance(cfg InstanceConfig) AgentHandlersOption {
+	return func(h *AgentHandlers) {
+		h.instance = cfg
+	}
+}
+
 // NewAgentHandlers creates handlers for the Trace agent.
 //
 // Description:
@@ -189,6 +313,8 @@ func NewAgentHandlers(loop agent.AgentLoop, svc *Service, opts ...AgentHandlersO
 //	Starts a new agent session with the given query. The session
 //	initializes the code graph (if not already initialized), assembles
 //	context, and executes the agent loop until completion or clarification.
+//	With quick answers enabled (WithQuickAnswer), a simple lookup is first
+//	answered without the loop, falling back to it on failure.
 //
 // Request Body:
 //
@@ -197,56 +323,100 @@ func NewAgentHandlers(loop agent.AgentLoop, svc *Service, opts ...AgentHandlersO
 // Response:
 //
 //	200 OK: AgentRunResponse (session completed or needs clarification)
-//	400 Bad Request: Validation error
-//	409 Conflict: Session already in progress
+//	400 Bad Request: Validation error, or an unknown mode
+//	403 Forbidden: Priority not allowed for the API key
+//	409 Conflict: Session already in progress, or aborted while queued
+//	429 Too Many Requests: Run queue full (QueueFullProblem)
 //	500 Internal Server Error: Processing error
 //
+//	With a run queue and "Accept: text/event-stream", the response is a
+//	stream of "queue_position" events while the run waits, a "started"
+//	event, and then a "result" (AgentRunResponse) or "error" (problem)
+//	event. Errors found before the run is queued are plain responses.
+//
 // Thread Safety: This method is safe for concurrent use.
 func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleAgentRun")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleAgentRun")
 
 	var req AgentRunRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	if req.Query == "" {
 		logger.Warn("Empty query")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Query is required",
-			Code:  "EMPTY_QUERY",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeEmptyQuery, "Query is required")
+		return
+	}
+
+	if !validAgentMode(req.Mode) {
+		logger.Warn("Invalid mode", "mode", req.Mode)
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter,
+			fmt.Sprintf("mode must be %q or %q", AgentModeQuick, AgentModeFull))
 		return
 	}
 
+	if req.Record && h.fixtureDir == "" {
+		logger.Warn("Recording requested but not enabled")
+		apierror.Write(c, http.StatusForbidden, apierror.CodeRecordingNotAllowed, "Fixture recording is not enabled")
+		return
+	}
+
+	if h.svc != nil {
+		if _, err := h.svc.CheckRoot(req.ProjectRoot); err != nil {
+			logger.Warn("Project root refused", telemetry.SensitiveLog("project_root", req.ProjectRoot), telemetry.SensitiveLogError("error", err))
+			if errors.Is(err, ErrRootNotAllowed) {
+				apierror.Write(c, http.StatusForbidden, apierror.CodeRootNotAllowed, err.Error())
+			} else {
+				apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidPath, err.Error())
+			}
+			return
+		}
+	}
+
+	if req.Scope != "" {
+		if err := h.checkAgentScope(req.Scope, req.ProjectRoot); err != nil {
+			logger.Warn("Scope refused", "scope", req.Scope, telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+			return
+		}
+	}
+
+	if req.Diff != "" {
+		if err := checkAgentDiff(req.Diff); err != nil {
+			logger.Warn("Diff refused", "diff_len", len(req.Diff), telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+			return
+		}
+	}
+
 	logger.Info("Starting agent session",
-		"project_root", req.ProjectRoot,
+		telemetry.SensitiveLog("project_root", req.ProjectRoot),
+		telemetry.SensitiveLog("scope", req.Scope),
 		"query_len", len(req.Query))
 
 	// Create session with optional config.
 	// CB-62: The proxy sends a partial config with only MainModel set.
 	// We must merge it into defaults so zero-valued fields don't fail validation.
-	var sessionConfig *agent.SessionConfig
-	if req.Config != nil {
-		sessionConfig = agent.DefaultSessionConfig().MergeOverrides(req.Config)
-	}
+	sessionConfig := h.baseSessionConfig().MergeOverrides(req.Config)
 
 	session, err := agent.NewSession(req.ProjectRoot, sessionConfig)
 	if err != nil {
-		logger.Error("Failed to create session", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: err.Error(),
-			Code:  "INVALID_CONFIG",
-		})
+		logger.Error("Failed to create session", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
 		return
 	}
 
+	if req.Scope != "" {
+		session.SetScope(req.Scope)
+	}
+	if req.Diff != "" {
+		session.SetDiff(req.Diff)
+	}
+
 	// CB-62: Log main model override when user selected a model in OpenWebUI.
 	if session.Config.MainModel != "" {
 		logger.Info("CB-62: Main model overridden by user selection",
@@ -260,6 +430,46 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 		"tool_router_enabled", session.Config.ToolRouterEnabled,
 		"tool_router_model", session.Config.ToolRouterModel)
 
+	responder := newRunResponder(c)
+	release, admitted := h.waitForRunSlot(c, responder, session.ID, req.Priority, logger)
+	if !admitted {
+		return
+	}
+	defer release()
+
+	admittedAt := time.Now()
+	servedMode := AgentModeFull
+	defer func() {
+		agentRunDuration.WithLabelValues(servedMode).Observe(time.Since(admittedAt).Seconds())
+	}()
+
+	runCtx, tally := h.usageContext(c)
+	answer, quickFallback, attempted := h.tryQuickAnswer(runCtx, session, req, logger)
+	if answer != nil {
+		servedMode = AgentModeQuick
+		logger.Info("Agent run answered in quick mode",
+			"session_id", session.ID,
+			"tools_run", answer.toolsRun,
+			"duration", time.Since(admittedAt))
+		responder.ok(AgentRunResponse{
+			SessionID:    session.ID,
+			State:        string(agent.StateComplete),
+			StepsTaken:   answer.toolsRun,
+			TokensUsed:   answer.tokensUsed,
+			Response:     answer.response,
+			Usage:        runUsage(tally),
+			InstanceHint: h.instanceHint(session),
+			Mode:         AgentModeQuick,
+		})
+		return
+	}
+	if attempted {
+		agentQuickFallbacks.WithLabelValues(quickFallback).Inc()
+		logger.Info("Quick answer fell back to the full loop",
+			"session_id", session.ID,
+			"reason", quickFallback)
+	}
+
 	// Initialize tool router if enabled
 	if session.Config.ToolRouterEnabled {
 		// PRE-FLIGHT CHECK: Verify router model is available
@@ -273,7 +483,7 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 			logger.Error("⚠️  PRE-FLIGHT CHECK FAILED",
 				"session_id", session.ID,
 				"router_model", session.Config.ToolRouterModel,
-				"error", preflightErr,
+				telemetry.SensitiveLogError("error", preflightErr),
 				"error_type", fmt.Sprintf("%T", preflightErr))
 		} else {
 			logger.Info("✓ PRE-FLIGHT CHECK PASSED",
@@ -293,7 +503,7 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 			// Log warning but don't fail - tool routing is optional
 			logger.Warn("Failed to initialize tool router, continuing without it",
 				"session_id", session.ID,
-				"error", err,
+				telemetry.SensitiveLogError("error", err),
 				"model", session.Config.ToolRouterModel,
 				"error_type", fmt.Sprintf("%T", err))
 
@@ -319,47 +529,219 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 	}
 
 	// Run the agent loop
-	result, err := h.loop.Run(c.Request.Context(), session, req.Query)
+	var recorder *replay.Recorder
+	if req.Record {
+		recorder = replay.NewRecorder(req.Query)
+		runCtx = replay.WithRecorder(runCtx, recorder)
+	}
+	result, err := h.loop.Run(runCtx, session, req.Query)
+	fixturePath := h.saveFixture(recorder, session.ID, logger)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		errCode := "AGENT_ERROR"
+		errCode := apierror.CodeInternal
 
 		if errors.Is(err, agent.ErrInvalidSession) {
 			statusCode = http.StatusBadRequest
-			errCode = "INVALID_SESSION"
+			errCode = apierror.CodeInvalidSession
 		} else if errors.Is(err, agent.ErrEmptyQuery) {
 			statusCode = http.StatusBadRequest
-			errCode = "EMPTY_QUERY"
+			errCode = apierror.CodeEmptyQuery
 		} else if errors.Is(err, agent.ErrSessionInProgress) {
 			statusCode = http.StatusConflict
-			errCode = "SESSION_IN_PROGRESS"
+			errCode = apierror.CodeSessionInProgress
+		} else if errors.Is(err, agent.ErrMaxStepsExceeded) || errors.Is(err, agent.ErrMaxTokensExceeded) {
+			statusCode = http.StatusUnprocessableEntity
+			errCode = apierror.CodeBudgetExceeded
+		} else if errors.Is(err, agent.ErrLLMUnavailable) {
+			statusCode = http.StatusServiceUnavailable
+			errCode = apierror.CodeProviderUnavailable
 		}
 
-		logger.Error("Agent run failed", "error", err)
-		c.JSON(statusCode, ErrorResponse{
-			Error: err.Error(),
-			Code:  errCode,
-		})
+		logger.Error("Agent run failed", telemetry.SensitiveLogError("error", err))
+		responder.fail(statusCode, errCode, err.Error())
 		return
 	}
 
+	h.reportCitations(session, result, logger)
+	h.linkCitations(c, session, result)
+	pending := h.pendingPlans(session.ID, result.State)
 	logger.Info("Agent session completed",
 		"session_id", session.ID,
 		"state", result.State,
-		"steps_taken", result.StepsTaken)
-
-	c.JSON(http.StatusOK, AgentRunResponse{
-		SessionID:    session.ID,
-		State:        string(result.State),
-		StepsTaken:   result.StepsTaken,
-		TokensUsed:   result.TokensUsed,
-		Response:     result.Response,
-		NeedsClarify: result.NeedsClarify,
-		Error:        agentErrorToString(result.Error),
-		DegradedMode: session.GetMetrics().DegradedMode,
+		"steps_taken", result.StepsTaken,
+		"pending_plans", len(pending))
+
+	if result.State == agent.StateAborted {
+		responder.event("aborted", result.Abort)
+	}
+	responder.ok(AgentRunResponse{
+		SessionID:           session.ID,
+		State:               string(result.State),
+		StepsTaken:          result.StepsTaken,
+		TokensUsed:          result.TokensUsed,
+		Response:            result.Response,
+		Citations:           result.Citations,
+		UnverifiedCitations: result.UnverifiedCitations,
+		NeedsClarify:        result.NeedsClarify,
+		Error:               agentErrorToString(result.Error),
+		Abort:               result.Abort,
+		DegradedMode:        session.GetMetrics().DegradedMode,
+		TranscriptURL:       h.saveTranscript(c.Request.Context(), session, result, logger),
+		ContextAssembly:     contextAssemblyOf(session),
+		Status:              approvalStatus(pending),
+		PendingPlans:        pending,
+		FixturePath:         fixturePath,
+		Usage:               runUsage(tally),
+		InstanceHint:        h.instanceHint(session),
+		Mode:                AgentModeFull,
+		QuickFallback:       quickFallback,
+		DiffSymbols:         session.GetDiffSymbols(),
 	})
 }
 
+// usageContext returns the request context with the run's LLM calls
+// attributed to the request's API key and a tally of their usage. The
+// tally is nil when usage accounting is disabled.
+func (h *AgentHandlers) usageContext(c *gin.Context) (context.Context, *usage.Tally) {
+	ctx := c.Request.Context()
+	if h.usage == nil {
+		return ctx, nil
+	}
+	return usage.WithTally(usage.ContextWithAPIKeyID(ctx, apiKeyID(c)))
+}
+
+// runUsage returns a run's usage totals, or nil without a tally.
+func runUsage(tally *usage.Tally) *usage.Totals {
+	if tally == nil {
+		return nil
+	}
+	totals := tally.Totals()
+	return &totals
+}
+
+// saveFixture writes a recorded run's fixture and returns its path. Returns
+// "" when the run wasn't recorded or saving failed.
+func (h *AgentHandlers) saveFixture(recorder *replay.Recorder, sessionID string, logger *slog.Logger) string {
+	if recorder == nil {
+		return ""
+	}
+	path := filepath.Join(h.fixtureDir, sessionID+".json")
+	if err := recorder.Save(path); err != nil {
+		logger.Error("Failed to save replay fixture", "session_id", sessionID, telemetry.SensitiveLogError("error", err))
+		return ""
+	}
+	logger.Info("Replay fixture saved", "session_id", sessionID, telemetry.SensitiveLog("path", path))
+	return path
+}
+
+// baseSessionConfig returns the defaults new sessions start from, with the
+// server-wide context budget applied.
+func (h *AgentHandlers) baseSessionConfig() *agent.SessionConfig {
+	cfg := agent.DefaultSessionConfig()
+	if h.contextBudget == nil {
+		return cfg
+	}
+	if h.contextBudget.MaxTotalTokens > 0 {
+		cfg.InitialContextBudget = h.contextBudget.MaxTotalTokens
+	}
+	cfg.ContextMaxSymbolTokens = h.contextBudget.MaxSymbolTokens
+	cfg.ContextMaxFiles = h.contextBudget.MaxFiles
+	cfg.ContextRanking = h.contextBudget.Ranking
+	return cfg
+}
+
+// contextAssemblyOf returns the initial context assembly metadata for a
+// session, or nil if no context was assembled.
+func contextAssemblyOf(session *agent.Session) *agent.ContextAssembly {
+	if session == nil {
+		return nil
+	}
+	if assembled := session.GetCurrentContext(); assembled != nil {
+		return assembled.Assembly
+	}
+	return nil
+}
+
+// pendingPlans returns the IDs of the session's change plans awaiting
+// approval, or nil if the session errored or plans are not tracked.
+func (h *AgentHandlers) pendingPlans(sessionID string, state agent.AgentState) []string {
+	if h.svc == nil || state == agent.StateError {
+		return nil
+	}
+	return h.svc.PendingPlanIDs(sessionID)
+}
+
+// approvalStatus returns the AgentRunResponse status for a set of pending
+// plans.
+func approvalStatus(pending []string) string {
+	if len(pending) == 0 {
+		return ""
+	}
+	return AgentStatusAwaitingApproval
+}
+
+// planContinuation builds the message that resumes a session with an
+// approved change plan.
+//
+// Description:
+//
+//	Checks that the plan exists, belongs to the session and is approved,
+//	then describes the plan, the reviewer's comment and its diff preview
+//	so the agent can proceed with the edit. Writes the error response and
+//	returns false if the plan cannot be used.
+func (h *AgentHandlers) planContinuation(c *gin.Context, req AgentContinueRequest, logger *slog.Logger) (string, bool) {
+	if h.svc == nil {
+		apierror.Write(c, http.StatusNotFound, apierror.CodePlanNotFound, "Plan not found")
+		return "", false
+	}
+	summary, planData, err := h.svc.PlanRecord(req.PlanID)
+	if err != nil {
+		apierror.Write(c, http.StatusNotFound, apierror.CodePlanNotFound, "Plan not found")
+		return "", false
+	}
+	if summary.SessionID != req.SessionID {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "plan_id does not belong to this session")
+		return "", false
+	}
+	if summary.Status != PlanStatusApproved {
+		apierror.Write(c, http.StatusConflict, apierror.CodePlanNotApproved,
+			fmt.Sprintf("plan %s is %s, not approved", req.PlanID, summary.Status))
+		return "", false
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "The change plan %s was approved. Proceed with it.\n", summary.PlanID)
+	if summary.Description != "" {
+		fmt.Fprintf(&sb, "Plan: %s\n", summary.Description)
+	}
+	fmt.Fprintf(&sb, "Files: %d, changes: %d, risk: %s\n", summary.TotalFiles, summary.TotalChanges, summary.RiskLevel)
+	if summary.Comment != "" {
+		fmt.Fprintf(&sb, "Reviewer comment: %s\n", summary.Comment)
+	}
+	if plan, ok := planData.(*coordinate.ChangePlan); ok {
+		diffs, err := h.svc.PreviewPlan(c.Request.Context(), plan)
+		if err != nil {
+			logger.Warn("Plan preview failed", "plan_id", req.PlanID, telemetry.SensitiveLogError("error", err))
+		}
+		for _, diff := range diffs {
+			fmt.Fprintf(&sb, "\n--- %s (+%d -%d)\n", diff.FilePath, diff.LinesAdded, diff.LinesRemoved)
+			for _, hunk := range diff.Hunks {
+				fmt.Fprintf(&sb, "@@ line %d @@\n", hunk.StartLine)
+				for _, line := range hunk.OldLines {
+					sb.WriteString("-" + line + "\n")
+				}
+				for _, line := range hunk.NewLines {
+					sb.WriteString("+" + line + "\n")
+				}
+			}
+		}
+	}
+	if req.Clarification != "" {
+		fmt.Fprintf(&sb, "\n%s\n", req.Clarification)
+	}
+	return sb.String(), true
+}
+
 // HandleAgentContinue handles POST /v1/trace/agent/continue.
 //
 // Description:
@@ -367,6 +749,12 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 //	Continues an existing agent session that is waiting for clarification.
 //	The session must be in the CLARIFY state to accept continuation.
 //
+//	A session with change plans awaiting approval is blocked until each
+//	plan is decided; it resumes with plan_id set to an approved plan.
+//
+//	A clarification is answered either with free text (clarification) or
+//	by selecting one of the request's options (option_id).
+//
 // Request Body:
 //
 //	AgentContinueRequest
@@ -374,59 +762,115 @@ func (h *AgentHandlers) HandleAgentRun(c *gin.Context) {
 // Response:
 //
 //	200 OK: AgentRunResponse
-//	400 Bad Request: Session not in CLARIFY state
-//	404 Not Found: Session not found
+//	400 Bad Request: Session not in CLARIFY state, or unknown option_id
+//	403 Forbidden: Priority not allowed for the API key
+//	404 Not Found: Session or plan not found
+//	409 Conflict: Plans awaiting approval, plan_id not approved,
+//	  aborted while queued, or an aborted session that cannot be resumed
+//	429 Too Many Requests: Run queue full (QueueFullProblem)
 //	500 Internal Server Error: Processing error
 //
+//	Queued and streamed like POST /agent/run.
+//
 // Thread Safety: This method is safe for concurrent use.
 func (h *AgentHandlers) HandleAgentContinue(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleAgentContinue")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleAgentContinue")
 
 	var req AgentContinueRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	if req.SessionID == "" {
 		logger.Warn("Missing session_id")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "session_id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "session_id is required")
+		return
+	}
+
+	if req.Clarification == "" && req.PlanID == "" && req.OptionID == "" {
+		logger.Warn("Missing clarification")
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "clarification, option_id or plan_id is required")
+		return
+	}
+
+	if req.OptionID != "" && (req.Clarification != "" || req.PlanID != "") {
+		logger.Warn("option_id combined with clarification or plan_id")
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "option_id cannot be combined with clarification or plan_id")
+		return
+	}
+
+	message := req.Clarification
+	if req.PlanID != "" {
+		var ok bool
+		if message, ok = h.planContinuation(c, req, logger); !ok {
+			return
+		}
+	} else if pending := h.pendingPlans(req.SessionID, ""); len(pending) > 0 {
+		logger.Info("Session awaiting plan approval", "session_id", req.SessionID, "pending_plans", pending)
+		apierror.New(http.StatusConflict, apierror.CodePlanAwaitingApproval,
+			"session has change plans awaiting approval").
+			WithDetails("pending_plans: " + strings.Join(pending, ", ")).
+			Write(c)
+		return
+	}
+
+	if h.instance.SharedSessions && !h.prepareSharedSession(c, req.SessionID, logger) {
 		return
 	}
 
 	logger.Info("Continuing agent session",
 		"session_id", req.SessionID,
+		"plan_id", req.PlanID,
+		"option_id", req.OptionID,
 		"clarification_len", len(req.Clarification))
 
-	result, err := h.loop.Continue(c.Request.Context(), req.SessionID, req.Clarification)
+	responder := newRunResponder(c)
+	release, admitted := h.waitForRunSlot(c, responder, req.SessionID, req.Priority, logger)
+	if !admitted {
+		return
+	}
+	defer release()
+
+	runCtx, tally := h.usageContext(c)
+	var result *agent.RunResult
+	var err error
+	if req.OptionID != "" {
+		result, err = h.loop.ContinueWithOption(runCtx, req.SessionID, req.OptionID)
+	} else {
+		result, err = h.loop.Continue(runCtx, req.SessionID, message)
+	}
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		errCode := "AGENT_ERROR"
+		errCode := apierror.CodeInternal
 
-		if errors.Is(err, agent.ErrSessionNotFound) {
+		if errors.Is(err, agent.ErrUnknownClarificationOption) {
+			statusCode = http.StatusBadRequest
+			errCode = apierror.CodeInvalidParameter
+		} else if errors.Is(err, agent.ErrSessionNotFound) {
 			statusCode = http.StatusNotFound
-			errCode = "SESSION_NOT_FOUND"
+			errCode = apierror.CodeSessionNotFound
 		} else if errors.Is(err, agent.ErrNotInClarifyState) {
 			statusCode = http.StatusBadRequest
-			errCode = "NOT_IN_CLARIFY_STATE"
+			errCode = apierror.CodeNotInClarifyState
+		} else if errors.Is(err, agent.ErrSessionNotResumable) {
+			statusCode = http.StatusConflict
+			errCode = apierror.CodeSessionNotResumable
 		} else if errors.Is(err, agent.ErrSessionInProgress) {
 			statusCode = http.StatusConflict
-			errCode = "SESSION_IN_PROGRESS"
+			errCode = apierror.CodeSessionInProgress
+		} else if errors.Is(err, agent.ErrMaxStepsExceeded) || errors.Is(err, agent.ErrMaxTokensExceeded) {
+			statusCode = http.StatusUnprocessableEntity
+			errCode = apierror.CodeBudgetExceeded
+		} else if errors.Is(err, agent.ErrLLMUnavailable) {
+			statusCode = http.StatusServiceUnavailable
+			errCode = apierror.CodeProviderUnavailable
 		}
 
-		logger.Error("Agent continue failed", "error", err)
-		c.JSON(statusCode, ErrorResponse{
-			Error: err.Error(),
-			Code:  errCode,
-		})
+		logger.Error("Agent continue failed", telemetry.SensitiveLogError("error", err))
+		responder.fail(statusCode, errCode, err.Error())
 		return
 	}
 
@@ -437,29 +881,107 @@ func (h *AgentHandlers) HandleAgentContinue(c *gin.Context) {
 		degradedMode = state.DegradedMode
 	}
 
+	pending := h.pendingPlans(req.SessionID, result.State)
 	logger.Info("Agent session continued",
 		"session_id", req.SessionID,
 		"state", result.State,
-		"steps_taken", result.StepsTaken)
-
-	c.JSON(http.StatusOK, AgentRunResponse{
-		SessionID:    req.SessionID,
-		State:        string(result.State),
-		StepsTaken:   result.StepsTaken,
-		TokensUsed:   result.TokensUsed,
-		Response:     result.Response,
-		NeedsClarify: result.NeedsClarify,
-		Error:        agentErrorToString(result.Error),
-		DegradedMode: degradedMode,
+		"steps_taken", result.StepsTaken,
+		"pending_plans", len(pending))
+
+	transcriptURL := ""
+	var contextAssembly *agent.ContextAssembly
+	var instanceHint *InstanceHint
+	if session, sessErr := h.loop.GetSession(req.SessionID); sessErr == nil {
+		h.reportCitations(session, result, logger)
+		h.linkCitations(c, session, result)
+		transcriptURL = h.saveTranscript(c.Request.Context(), session, result, logger)
+		contextAssembly = contextAssemblyOf(session)
+		instanceHint = h.instanceHint(session)
+	}
+
+	if result.State == agent.StateAborted {
+		responder.event("aborted", result.Abort)
+	}
+	responder.ok(AgentRunResponse{
+		SessionID:           req.SessionID,
+		State:               string(result.State),
+		StepsTaken:          result.StepsTaken,
+		TokensUsed:          result.TokensUsed,
+		Response:            result.Response,
+		Citations:           result.Citations,
+		UnverifiedCitations: result.UnverifiedCitations,
+		NeedsClarify:        result.NeedsClarify,
+		Error:               agentErrorToString(result.Error),
+		Abort:               result.Abort,
+		DegradedMode:        degradedMode,
+		TranscriptURL:       transcriptURL,
+		ContextAssembly:     contextAssembly,
+		Status:              approvalStatus(pending),
+		PendingPlans:        pending,
+		Usage:               runUsage(tally),
+		InstanceHint:        instanceHint,
+		Mode:                AgentModeFull,
 	})
 }
 
+// prepareSharedSession readies a session that may have been restored from
+// another instance for continuing here.
+//
+// Description:
+//
+//	Graphs are not shared between instances. If the session's graph is
+//	not loaded on this instance, writes 409 GRAPH_NOT_INITIALIZED_HERE
+//	naming the project root to re-initialize and returns false. Otherwise
+//	attaches a tool router when the session's config enables one and it
+//	has none (runtime attachments are not persisted). Unknown sessions
+//	are left for the loop to report.
+func (h *AgentHandlers) prepareSharedSession(c *gin.Context, sessionID string, logger *slog.Logger) bool {
+	session, err := h.loop.GetSession(sessionID)
+	if err != nil {
+		return true
+	}
+	if graphID := session.GetGraphID(); graphID != "" && h.svc != nil {
+		if _, err := h.svc.GetGraph(graphID); errors.Is(err, ErrGraphNotInitialized) {
+			logger.Info("Session graph not loaded on this instance",
+				"session_id", sessionID, "graph_id", graphID, "instance", h.instance.ID)
+			apierror.New(http.StatusConflict, apierror.CodeGraphNotInitializedHere,
+				fmt.Sprintf("graph %s of session %s is not initialized on instance %s", graphID, sessionID, h.instance.ID)).
+				WithDetails(fmt.Sprintf("POST /v1/trace/init with project_root %q on this instance, then retry", session.ProjectRoot)).
+				Write(c)
+			return false
+		}
+	}
+	if session.Config != nil && session.Config.ToolRouterEnabled && session.GetToolRouter() == nil {
+		if err := h.initializeToolRouter(c.Request.Context(), session, logger); err != nil {
+			logger.Warn("Failed to initialize tool router for restored session, continuing without it",
+				"session_id", sessionID, telemetry.SensitiveLogError("error", err))
+		}
+	}
+	return true
+}
+
+// instanceHint returns the sticky routing hint for a session's project,
+// or nil when hints are disabled.
+func (h *AgentHandlers) instanceHint(session *agent.Session) *InstanceHint {
+	if !h.instance.StickyHints || session == nil {
+		return nil
+	}
+	key := session.GetGraphID()
+	if key == "" && h.svc != nil {
+		key = h.svc.projectGraphID(session.ProjectRoot, "")
+	}
+	return &InstanceHint{Instance: h.instance.ID, StickyKey: key, Header: HeaderStickyKey}
+}
+
 // HandleAgentAbort handles POST /v1/trace/agent/abort.
 //
 // Description:
 //
-//	Aborts an active agent session. The session will transition
-//	to the ERROR state and any in-progress operations will be cancelled.
+//	Aborts an agent session. A run in progress has its context
+//	cancelled, which stops its LLM call or tool batch, and the handler
+//	waits a bounded time for it to stop. The session moves to ABORTED
+//	with the last completed phase recorded and a "run_aborted" event is
+//	emitted. A queued run is removed from the queue instead.
 //
 // Request Body:
 //
@@ -467,62 +989,269 @@ func (h *AgentHandlers) HandleAgentContinue(c *gin.Context) {
 //
 // Response:
 //
-//	200 OK: Success message
+//	200 OK: AgentAbortResponse
 //	404 Not Found: Session not found
 //	500 Internal Server Error: Processing error
 //
 // Thread Safety: This method is safe for concurrent use.
 func (h *AgentHandlers) HandleAgentAbort(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleAgentAbort")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleAgentAbort")
 
 	var req AgentAbortRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	if req.SessionID == "" {
 		logger.Warn("Missing session_id")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "session_id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "session_id is required")
 		return
 	}
 
 	logger.Info("Aborting agent session", "session_id", req.SessionID)
 
+	// A run still waiting for a slot leaves the queue and never starts.
+	if h.runQueue != nil && h.runQueue.Remove(req.SessionID) {
+		logger.Info("Queued agent run removed", "session_id", req.SessionID)
+		c.JSON(http.StatusOK, AgentAbortResponse{
+			Message:   "Queued run removed",
+			SessionID: req.SessionID,
+		})
+		return
+	}
+
 	err := h.loop.Abort(c.Request.Context(), req.SessionID)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		errCode := "AGENT_ERROR"
+		errCode := apierror.CodeInternal
 
 		if errors.Is(err, agent.ErrSessionNotFound) {
 			statusCode = http.StatusNotFound
-			errCode = "SESSION_NOT_FOUND"
+			errCode = apierror.CodeSessionNotFound
 		}
 
-		logger.Error("Agent abort failed", "error", err)
-		c.JSON(statusCode, ErrorResponse{
-			Error: err.Error(),
-			Code:  errCode,
-		})
+		logger.Error("Agent abort failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, statusCode, errCode, err.Error())
+		return
+	}
+
+	resp := AgentAbortResponse{
+		Message:   "Session aborted successfully",
+		SessionID: req.SessionID,
+	}
+	if state, stateErr := h.loop.GetState(req.SessionID); stateErr == nil {
+		resp.State = string(state.State)
+		resp.Abort = state.Abort
+	}
+	if resp.Abort == nil {
+		resp.Message = "Session already finished"
+	}
+	logger.Info("Agent session aborted",
+		"session_id", req.SessionID,
+		"state", resp.State,
+		"acknowledged", resp.Abort != nil && resp.Abort.Acknowledged)
+
+	if session, sessErr := h.loop.GetSession(req.SessionID); sessErr == nil {
+		h.saveTranscript(c.Request.Context(), session, nil, logger)
+	}
+	h.emitRunAborted(req.SessionID, resp.Abort)
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// emitRunAborted emits a TypeRunAborted event for an aborted session.
+// Does nothing when record is nil or no emitter is set.
+func (h *AgentHandlers) emitRunAborted(sessionID string, record *agent.AbortRecord) {
+	if record == nil || h.svc == nil {
+		return
+	}
+	emitter := h.svc.EventEmitter()
+	if emitter == nil {
 		return
 	}
+	emitter.Emit(events.TypeRunAborted, &events.RunAbortedData{
+		SessionID: sessionID,
+		Abort:     *record,
+	})
+}
 
-	logger.Info("Agent session aborted", "session_id", req.SessionID)
+// HandleListSessions handles GET /v1/trace/agent/sessions.
+//
+// Description:
+//
+//	Lists persisted session transcripts, newest first.
+//
+// Query Parameters:
+//
+//	project_root: Optional exact project root filter
+//	status: Optional agent state filter (e.g. COMPLETE, ERROR, CLARIFY)
+//	limit: Page size, default 50, max 500
+//	offset: Number of sessions to skip, default 0
+//
+// Response:
+//
+//	200 OK: ListSessionsResponse
+//	400 Bad Request: Invalid limit or offset
+//	503 Service Unavailable: Session persistence not configured
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleListSessions(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleListSessions")
+
+	if h.sessionStore == nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeSessionsNotAvailable, "session persistence not configured")
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":    "Session aborted successfully",
-		"session_id": req.SessionID,
+	filter := agent.TranscriptFilter{
+		ProjectRoot: c.Query("project_root"),
+		Status:      agent.AgentState(c.Query("status")),
+		Limit:       50,
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err := parseIntParam(limitStr, 1, 500)
+		if err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "invalid limit parameter")
+			return
+		}
+		filter.Limit = limit
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		offset, err := parseIntParam(offsetStr, 0, 1<<30)
+		if err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "invalid offset parameter")
+			return
+		}
+		filter.Offset = offset
+	}
+
+	sessions, total, err := h.sessionStore.List(c.Request.Context(), filter)
+	if err != nil {
+		logger.Error("failed to list sessions", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "failed to list sessions: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, ListSessionsResponse{
+		Sessions: sessions,
+		Total:    total,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
 	})
 }
 
+// HandleGetSession handles GET /v1/trace/agent/sessions/:id.
+//
+// Description:
+//
+//	Returns the full persisted transcript for a session: query, phase
+//	transitions, tool calls, LLM messages, final answer and token usage.
+//
+// Path Parameters:
+//
+//	id: Session ID (required)
+//
+// Response:
+//
+//	200 OK: agent.SessionTranscript
+//	404 Not Found: Transcript not found
+//	503 Service Unavailable: Session persistence not configured
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleGetSession(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGetSession")
+
+	if h.sessionStore == nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeSessionsNotAvailable, "session persistence not configured")
+		return
+	}
+
+	sessionID := c.Param("id")
+	transcript, err := h.sessionStore.Get(c.Request.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, agent.ErrTranscriptNotFound) {
+			apierror.Write(c, http.StatusNotFound, apierror.CodeSessionNotFound, err.Error())
+			return
+		}
+		logger.Error("failed to load session transcript", slog.String("session_id", sessionID), telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "failed to load session: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, transcript)
+}
+
+// HandleDeleteSession handles DELETE /v1/trace/agent/sessions/:id.
+//
+// Description:
+//
+//	Deletes a persisted session transcript. Does not affect live sessions.
+//
+// Path Parameters:
+//
+//	id: Session ID (required)
+//
+// Response:
+//
+//	200 OK: {"deleted": true}
+//	404 Not Found: Transcript not found
+//	503 Service Unavailable: Session persistence not configured
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleDeleteSession(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDeleteSession")
+
+	if h.sessionStore == nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeSessionsNotAvailable, "session persistence not configured")
+		return
+	}
+
+	sessionID := c.Param("id")
+	if err := h.sessionStore.Delete(c.Request.Context(), sessionID); err != nil {
+		if errors.Is(err, agent.ErrTranscriptNotFound) {
+			apierror.Write(c, http.StatusNotFound, apierror.CodeSessionNotFound, err.Error())
+			return
+		}
+		logger.Error("failed to delete session transcript", slog.String("session_id", sessionID), telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "failed to delete session: "+err.Error())
+		return
+	}
+
+	logger.Info("session transcript deleted", slog.String("session_id", sessionID))
+	c.JSON(http.StatusOK, gin.H{"deleted": true})
+}
+
+// saveTranscript persists the session transcript if a store is configured.
+//
+// Outputs:
+//
+//	string - The transcript URL, or empty if not persisted.
+func (h *AgentHandlers) saveTranscript(ctx context.Context, session *agent.Session, result *agent.RunResult, logger *slog.Logger) string {
+	if h.sessionStore == nil || session == nil {
+		return ""
+	}
+	transcript := agent.BuildTranscript(session, result)
+	if totals, ok := h.usage.SessionTotals(ctx, session.ID); ok {
+		transcript.Usage.PromptTokens = totals.PromptTokens
+		transcript.Usage.CompletionTokens = totals.CompletionTokens
+		transcript.Usage.CostUSD = totals.CostUSD
+		transcript.Usage.Estimated = totals.Estimated
+	}
+	if err := h.sessionStore.Save(ctx, transcript); err != nil {
+		logger.Warn("failed to persist session transcript",
+			"session_id", session.ID,
+			telemetry.SensitiveLogError("error", err))
+		return ""
+	}
+	return "/v1/trace/agent/sessions/" + session.ID
+}
+
 // HandleAgentState handles GET /v1/trace/agent/:id.
 //
 // Description:
@@ -542,15 +1271,12 @@ func (h *AgentHandlers) HandleAgentAbort(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use.
 func (h *AgentHandlers) HandleAgentState(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleAgentState")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleAgentState")
 
 	sessionID := c.Param("id")
 	if sessionID == "" {
 		logger.Warn("Missing session id")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "session id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "session id is required")
 		return
 	}
 
@@ -559,18 +1285,12 @@ func (h *AgentHandlers) HandleAgentState(c *gin.Context) {
 	state, err := h.loop.GetState(sessionID)
 	if err != nil {
 		if errors.Is(err, agent.ErrSessionNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: err.Error(),
-				Code:  "SESSION_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeSessionNotFound, err.Error())
 			return
 		}
 
-		logger.Error("Get session state failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "GET_STATE_FAILED",
-		})
+		logger.Error("Get session state failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -612,15 +1332,12 @@ func (h *AgentHandlers) HandleAgentState(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use.
 func (h *AgentHandlers) HandleGetReasoningTrace(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleGetReasoningTrace")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGetReasoningTrace")
 
 	sessionID := c.Param("id")
 	if sessionID == "" {
 		logger.Warn("Missing session id")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "session id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "session id is required")
 		return
 	}
 
@@ -629,18 +1346,12 @@ func (h *AgentHandlers) HandleGetReasoningTrace(c *gin.Context) {
 	session, err := h.loop.GetSession(sessionID)
 	if err != nil {
 		if errors.Is(err, agent.ErrSessionNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: err.Error(),
-				Code:  "SESSION_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeSessionNotFound, err.Error())
 			return
 		}
 
-		logger.Error("Get session failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "GET_SESSION_FAILED",
-		})
+		logger.Error("Get session failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -682,15 +1393,12 @@ func (h *AgentHandlers) HandleGetReasoningTrace(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use.
 func (h *AgentHandlers) HandleGetCRSExport(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleGetCRSExport")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGetCRSExport")
 
 	sessionID := c.Param("id")
 	if sessionID == "" {
 		logger.Warn("Missing session id")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "session id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "session id is required")
 		return
 	}
 
@@ -699,18 +1407,12 @@ func (h *AgentHandlers) HandleGetCRSExport(c *gin.Context) {
 	session, err := h.loop.GetSession(sessionID)
 	if err != nil {
 		if errors.Is(err, agent.ErrSessionNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: err.Error(),
-				Code:  "SESSION_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeSessionNotFound, err.Error())
 			return
 		}
 
-		logger.Error("Get session failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "GET_SESSION_FAILED",
-		})
+		logger.Error("Get session failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -751,7 +1453,7 @@ func (h *AgentHandlers) HandleGetCRSExport(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use.
 func (h *AgentHandlers) HandleDebugCRS(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleDebugCRS")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDebugCRS")
 
 	sessionID := c.Query("session_id")
 
@@ -762,17 +1464,11 @@ func (h *AgentHandlers) HandleDebugCRS(c *gin.Context) {
 		session, err := h.loop.GetSession(sessionID)
 		if err != nil {
 			if errors.Is(err, agent.ErrSessionNotFound) {
-				c.JSON(http.StatusNotFound, ErrorResponse{
-					Error: err.Error(),
-					Code:  "SESSION_NOT_FOUND",
-				})
+				apierror.Write(c, http.StatusNotFound, apierror.CodeSessionNotFound, err.Error())
 				return
 			}
-			logger.Error("Get session failed", "error", err)
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error: err.Error(),
-				Code:  "GET_SESSION_FAILED",
-			})
+			logger.Error("Get session failed", telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 			return
 		}
 
@@ -902,7 +1598,7 @@ func boolToInt(b bool) int {
 // Thread Safety: This method is safe for concurrent use.
 func (h *AgentHandlers) HandleDebugHistory(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleDebugHistory")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDebugHistory")
 
 	sessionID := c.Query("session_id")
 	limitStr := c.Query("limit")
@@ -924,17 +1620,11 @@ func (h *AgentHandlers) HandleDebugHistory(c *gin.Context) {
 		session, err := h.loop.GetSession(sessionID)
 		if err != nil {
 			if errors.Is(err, agent.ErrSessionNotFound) {
-				c.JSON(http.StatusNotFound, ErrorResponse{
-					Error: err.Error(),
-					Code:  "SESSION_NOT_FOUND",
-				})
+				apierror.Write(c, http.StatusNotFound, apierror.CodeSessionNotFound, err.Error())
 				return
 			}
-			logger.Error("Get session failed", slog.String("error", err.Error()))
-			c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error: err.Error(),
-				Code:  "GET_SESSION_FAILED",
-			})
+			logger.Error("Get session failed", telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 			return
 		}
 
@@ -963,7 +1653,7 @@ func (h *AgentHandlers) HandleDebugHistory(c *gin.Context) {
 		if err != nil {
 			logger.Warn("Failed to get session for history",
 				slog.String("session_id", summary.ID),
-				slog.String("error", err.Error()))
+				telemetry.SensitiveLogError("error", err))
 			continue
 		}
 
@@ -1053,6 +1743,140 @@ func (h *AgentHandlers) buildHistoryEntriesForSession(session *agent.Session, ma
 	return entries
 }
 
+// HandleGetRoutingFeedback handles GET /v1/trace/agent/debug/routing/feedback.
+//
+// Description:
+//
+//	Returns routing feedback counts per tool and the most recent records.
+//
+// Query Parameters:
+//
+//	recent: Optional number of recent records to include (default 20, max 500)
+//
+// Response:
+//
+//	200 OK: routing.FeedbackSummary
+//	503 Service Unavailable: Routing feedback not enabled
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleGetRoutingFeedback(c *gin.Context) {
+	if h.routingFeedback == nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeRoutingFeedbackNotAvailable, "routing feedback not enabled")
+		return
+	}
+
+	recent := 20
+	if recentStr := c.Query("recent"); recentStr != "" {
+		parsed, err := parseIntParam(recentStr, 0, 500)
+		if err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "invalid recent parameter")
+			return
+		}
+		recent = parsed
+	}
+
+	c.JSON(http.StatusOK, h.routingFeedback.Summary(c.Request.Context(), recent))
+}
+
+// HandleResetRoutingFeedback handles DELETE /v1/trace/agent/debug/routing/feedback.
+//
+// Description:
+//
+//	Deletes all routing feedback. Routing falls back to the plain hybrid
+//	score until new feedback is recorded.
+//
+// Response:
+//
+//	200 OK: ResetRoutingFeedbackResponse
+//	500 Internal Server Error: Store reset failed
+//	503 Service Unavailable: Routing feedback not enabled
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleResetRoutingFeedback(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleResetRoutingFeedback")
+
+	if h.routingFeedback == nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeRoutingFeedbackNotAvailable, "routing feedback not enabled")
+		return
+	}
+
+	removed, err := h.routingFeedback.Reset(c.Request.Context())
+	if err != nil {
+		logger.Error("Reset routing feedback failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		return
+	}
+
+	logger.Info("Routing feedback reset", slog.Int("removed", removed))
+	c.JSON(http.StatusOK, ResetRoutingFeedbackResponse{Removed: removed})
+}
+
+// HandleRoutingExplain handles POST /v1/trace/routing/explain.
+//
+// Description:
+//
+//	Runs the tool routing pipeline on a query without executing anything
+//	and returns why each tool scored as it did: BM25 and embedding scores
+//	of the top candidates, forced-mapping and negation hits, confusion-pair
+//	and other score adjustments, the router's pick and the final choice
+//	with its margin. The router runs in a throwaway session; the circuit
+//	breaker, UCB1 and the session penalty, which depend on session
+//	history, do not apply.
+//
+// Request Body:
+//
+//	RoutingExplainRequest
+//
+// Response:
+//
+//	200 OK: agent.RoutingDecision
+//	400 Bad Request: Invalid request or empty query
+//	503 Service Unavailable: No pre-filter and no router, or the router
+//	  failed
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleRoutingExplain(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleRoutingExplain")
+
+	var req RoutingExplainRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Query) == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeEmptyQuery, "Query is required")
+		return
+	}
+
+	var router agent.ToolRouter
+	if !req.SkipRouter {
+		// The session only hosts the router; no project is read.
+		session, err := agent.NewSession(".", agent.DefaultSessionConfig())
+		if err == nil {
+			err = h.initializeToolRouter(c.Request.Context(), session, logger)
+		}
+		if err != nil {
+			logger.Warn("Tool router unavailable for routing explain", telemetry.SensitiveLogError("error", err))
+		} else if router = session.GetToolRouter(); router != nil {
+			defer router.Close()
+		}
+	}
+	if h.prefilter == nil && router == nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeRoutingNotAvailable, "no tool pre-filter or tool router available")
+		return
+	}
+
+	decision, err := phases.ExplainRouting(c.Request.Context(), h.prefilter, router, req.Query, tools.StaticToolDefinitions())
+	if err != nil {
+		logger.Warn("Routing explain failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeProviderUnavailable, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, decision)
+}
+
 // parseIntParam parses an integer parameter with bounds checking.
 func parseIntParam(s string, min, max int) (int, error) {
 	var val int
@@ -1149,7 +1973,7 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 		if roleErr != nil {
 			logger.Error("initializeToolRouter: LoadRoleConfig failed",
 				"session_id", session.ID,
-				"error", roleErr)
+				telemetry.SensitiveLogError("error", roleErr))
 			routing.RecordRouterInit(session.Config.ToolRouterModel, false, "config_load_failed")
 			return fmt.Errorf("loading role config: %w", roleErr)
 		}
@@ -1160,13 +1984,22 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 	// are used instead of the session default ("granite4:micro-h").
 	routerConfig.Model = roleConfig.Router.Model
 
+	// Keep-alive and context window come from TRACE_ROUTER_KEEP_ALIVE and
+	// TRACE_ROUTER_NUM_CTX so requests match what startup warmup loaded.
+	if roleConfig.Router.KeepAlive != "" {
+		routerConfig.KeepAlive = roleConfig.Router.KeepAlive
+	}
+	if roleConfig.Router.NumCtx > 0 {
+		routerConfig.NumCtx = roleConfig.Router.NumCtx
+	}
+
 	// CB-60: Create ChatClient for the router via ProviderFactory.
 	routerChatClient, err := h.providerFactory.CreateChatClient(roleConfig.Router)
 	if err != nil {
 		logger.Error("initializeToolRouter: CreateChatClient failed for router",
 			"session_id", session.ID,
 			"provider", roleConfig.Router.Provider,
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 		routing.RecordRouterInit(session.Config.ToolRouterModel, false, "chat_client_failed")
 		return fmt.Errorf("creating router chat client: %w", err)
 	}
@@ -1183,7 +2016,7 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 	if err != nil {
 		logger.Error("initializeToolRouter: CreateLifecycleManager failed",
 			"session_id", session.ID,
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 		routing.RecordRouterInit(session.Config.ToolRouterModel, false, "lifecycle_failed")
 		return fmt.Errorf("creating router lifecycle manager: %w", err)
 	}
@@ -1197,7 +2030,7 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 	if err != nil {
 		logger.Error("initializeToolRouter: NewGranite4Router failed",
 			"session_id", session.ID,
-			"error", err,
+			telemetry.SensitiveLogError("error", err),
 			"error_type", fmt.Sprintf("%T", err))
 		routing.RecordRouterInit(session.Config.ToolRouterModel, false, "router_creation_failed")
 		return fmt.Errorf("failed to create router: %w", err)
@@ -1231,12 +2064,12 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 		escClient, escErr := h.providerFactory.CreateChatClient(escProviderConfig)
 		if escErr != nil {
 			logger.Warn("initializeToolRouter: Escalation router creation failed, continuing without escalation",
-				"error", escErr)
+				telemetry.SensitiveLogError("error", escErr))
 		} else {
 			escRouter, escErr := routing.NewGranite4Router(escClient, escConfig)
 			if escErr != nil {
 				logger.Warn("initializeToolRouter: Escalation Granite4Router creation failed, continuing without escalation",
-					"error", escErr)
+					telemetry.SensitiveLogError("error", escErr))
 			} else {
 				// CB-62: Convert StaticToolDefinitions to routing.ToolSpec for escalation.
 				// The escalation router needs the full 55-tool set to bypass the prefilter.
@@ -1297,7 +2130,7 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 		warmupDuration := time.Since(warmupStart)
 		logger.Error("initializeToolRouter: Model warmup failed",
 			"session_id", session.ID,
-			"error", warmErr,
+			telemetry.SensitiveLogError("error", warmErr),
 			"model", routerConfig.Model,
 			"error_type", fmt.Sprintf("%T", warmErr))
 		routing.RecordRouterInit(session.Config.ToolRouterModel, false, "warmup_failed")
@@ -1327,15 +2160,22 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 	// doesn't pay a ~14s cold-load penalty.
 	// CB-60: Only needed when router uses local Ollama (cloud providers don't evict).
 	// CB-62: Use session override if set, otherwise env var.
+	mainKeepAlive, mainNumCtx := roleConfig.Main.KeepAlive, roleConfig.Main.NumCtx
+	if mainKeepAlive == "" {
+		mainKeepAlive = providers.DefaultKeepAlive
+	}
+	if mainNumCtx <= 0 {
+		mainNumCtx = providers.DefaultMainNumCtx
+	}
 	if routerLifecycle.IsLocal() {
 		mainModel := roleConfig.Main.Model
 		if mainModel != "" && mainModel != routerConfig.Model && h.modelManager != nil {
 			mainWarmStart := time.Now()
-			if warmErr := h.modelManager.WarmModel(warmupCtx, mainModel, "24h", 65536); warmErr != nil {
+			if warmErr := h.modelManager.WarmModel(warmupCtx, mainModel, mainKeepAlive, mainNumCtx); warmErr != nil {
 				logger.Warn("initializeToolRouter: Main model re-warm failed (non-fatal)",
 					"session_id", session.ID,
 					"model", mainModel,
-					"error", warmErr)
+					telemetry.SensitiveLogError("error", warmErr))
 			} else {
 				logger.Info("initializeToolRouter: Main model re-warmed",
 					"session_id", session.ID,
@@ -1356,6 +2196,12 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 		paramConfig.Model = roleConfig.ParamExtractor.Model
 	}
 	paramConfig.KeepAlive = routerConfig.KeepAlive
+	if roleConfig.ParamExtractor.KeepAlive != "" {
+		paramConfig.KeepAlive = roleConfig.ParamExtractor.KeepAlive
+	}
+	if roleConfig.ParamExtractor.NumCtx > 0 {
+		paramConfig.NumCtx = roleConfig.ParamExtractor.NumCtx
+	}
 
 	// CB-60: Create ChatClient for param extractor via ProviderFactory.
 	paramChatClient, paramClientErr := h.providerFactory.CreateChatClient(roleConfig.ParamExtractor)
@@ -1363,7 +2209,7 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 		logger.Warn("initializeToolRouter: ParamExtractor chat client creation failed (non-fatal)",
 			"session_id", session.ID,
 			"provider", roleConfig.ParamExtractor.Provider,
-			"error", paramClientErr)
+			telemetry.SensitiveLogError("error", paramClientErr))
 	} else {
 		// CB-60d: Wrap param extractor ChatClient with egress guard.
 		paramChatClient = h.providerFactory.WrapChatClientForEgress(
@@ -1376,7 +2222,7 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 		if paramLifecycleErr != nil {
 			logger.Warn("initializeToolRouter: ParamExtractor lifecycle creation failed (non-fatal)",
 				"session_id", session.ID,
-				"error", paramLifecycleErr)
+				telemetry.SensitiveLogError("error", paramLifecycleErr))
 		}
 
 		// Warm the param extraction model if it's different from the router model
@@ -1384,14 +2230,14 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 		if paramLifecycle != nil && paramLifecycle.IsLocal() && paramConfig.Model != routerConfig.Model {
 			paramWarmStart := time.Now()
 			warmOpts := providers.WarmupOptions{
-				KeepAlive: "24h",
+				KeepAlive: paramConfig.KeepAlive,
 				NumCtx:    paramConfig.NumCtx,
 			}
 			if warmErr := paramLifecycle.WarmModel(warmupCtx, paramConfig.Model, warmOpts); warmErr != nil {
 				logger.Warn("initializeToolRouter: Param model warmup failed (non-fatal)",
 					"session_id", session.ID,
 					"model", paramConfig.Model,
-					"error", warmErr)
+					telemetry.SensitiveLogError("error", warmErr))
 			} else {
 				logger.Info("initializeToolRouter: Param model warmed",
 					"session_id", session.ID,
@@ -1403,11 +2249,11 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 			// CB-62: Use roleConfig.Main.Model which includes session override.
 			mainModel := roleConfig.Main.Model
 			if mainModel != "" && mainModel != paramConfig.Model && h.modelManager != nil {
-				if warmErr := h.modelManager.WarmModel(warmupCtx, mainModel, "24h", 65536); warmErr != nil {
+				if warmErr := h.modelManager.WarmModel(warmupCtx, mainModel, mainKeepAlive, mainNumCtx); warmErr != nil {
 					logger.Warn("initializeToolRouter: Main model re-warm after param model failed (non-fatal)",
 						"session_id", session.ID,
 						"model", mainModel,
-						"error", warmErr)
+						telemetry.SensitiveLogError("error", warmErr))
 				}
 			}
 		}
@@ -1416,7 +2262,7 @@ func (h *AgentHandlers) initializeToolRouter(ctx context.Context, session *agent
 		if paramErr != nil {
 			logger.Warn("initializeToolRouter: ParamExtractor creation failed (non-fatal)",
 				"session_id", session.ID,
-				"error", paramErr)
+				telemetry.SensitiveLogError("error", paramErr))
 		} else {
 			session.SetParamExtractor(paramExtractor)
 			logger.Info("initializeToolRouter: ParamExtractor created",
@@ -1459,6 +2305,60 @@ func (h *AgentHandlers) getOllamaEndpoint() string {
 	return providers.ResolveOllamaURL()
 }
 
+// checkAgentScope validates the scope of an agent run.
+//
+// Description:
+//
+//	The view must exist and be over a graph of the run's project root.
+//	The session's graph is only known after its init phase, so a view
+//	over another graph of the same project is reported when the session
+//	creates its tools.
+//
+// Outputs:
+//
+//	error - Non-nil if the scope cannot be used for the run.
+func (h *AgentHandlers) checkAgentScope(scope, projectRoot string) error {
+	if h.svc == nil {
+		return fmt.Errorf("scope %q: graph views are not available", scope)
+	}
+	view, err := h.svc.GetView(scope)
+	if err != nil {
+		return err
+	}
+	cached, err := h.svc.GetGraph(view.GraphID)
+	if err != nil {
+		return fmt.Errorf("scope %q: %w", scope, err)
+	}
+	if cached.ProjectRoot != projectRoot {
+		return fmt.Errorf("%w: view %q is over project %s, not %s", ErrInvalidView, scope, cached.ProjectRoot, projectRoot)
+	}
+	return nil
+}
+
+// maxAgentDiffBytes bounds the diff of an agent run.
+const maxAgentDiffBytes = 1 << 20
+
+// checkAgentDiff validates the diff of an agent run: it must be a
+// unified diff of at most maxAgentDiffBytes that changes at least one
+// file.
+//
+// Outputs:
+//
+//	error - Non-nil if the diff cannot seed the run's context.
+func checkAgentDiff(diffText string) error {
+	if len(diffText) > maxAgentDiffBytes {
+		return fmt.Errorf("diff is %d bytes, the limit is %d", len(diffText), maxAgentDiffBytes)
+	}
+	files, err := diff.ChangedLines(diffText)
+	if err != nil {
+		return fmt.Errorf("diff: %w", err)
+	}
+	if len(files) == 0 {
+		return errors.New("diff changes no files; expected unified diff text such as git diff output")
+	}
+	return nil
+}
+
 // verifyRouterModelAvailable performs a pre-flight check to ensure the router model
 // is available and responding on Ollama before attempting to initialize the router.
 //
@@ -1514,7 +2414,7 @@ func (h *AgentHandlers) verifyRouterModelAvailable(ctx context.Context, routerMo
 	if err != nil {
 		logger.Error("verifyRouterModelAvailable: Test request failed",
 			"model", routerModel,
-			"error", err,
+			telemetry.SensitiveLogError("error", err),
 			"error_type", fmt.Sprintf("%T", err))
 		return fmt.Errorf("router model test failed: %w", err)
 	}
@@ -1716,12 +2616,12 @@ func convertCRSExport(export *crs.CRSExport) *CRSExportResponse {
 func (h *AgentHandlers) HandleCRSStream(c *gin.Context) {
 	sessionID := c.Param("id")
 	if sessionID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "session ID is required"})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "session ID is required")
 		return
 	}
 
 	if h.natsClient == nil || !h.natsClient.IsConnected() {
-		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "NATS not available"})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeProviderUnavailable, "NATS not available")
 		return
 	}
 
@@ -1729,7 +2629,7 @@ func (h *AgentHandlers) HandleCRSStream(c *gin.Context) {
 	if h.loop != nil {
 		_, err := h.loop.GetSession(sessionID)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeSessionNotFound, "session not found")
 			return
 		}
 	}
@@ -1748,9 +2648,9 @@ func (h *AgentHandlers) HandleCRSStream(c *gin.Context) {
 	if err != nil {
 		slog.Error("CRS-27: Failed to subscribe for SSE stream",
 			slog.String("session_id", sessionID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to subscribe to delta stream"})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "failed to subscribe to delta stream")
 		return
 	}
 	defer sub.Unsubscribe()
@@ -1788,7 +2688,7 @@ func (h *AgentHandlers) HandleCRSStream(c *gin.Context) {
 				}
 				slog.Warn("CRS-27: Error receiving SSE message",
 					slog.String("session_id", sessionID),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				return false
 			}
@@ -1798,7 +2698,7 @@ func (h *AgentHandlers) HandleCRSStream(c *gin.Context) {
 			if decErr != nil {
 				slog.Warn("CRS-27: Failed to decode delta for SSE",
 					slog.String("session_id", sessionID),
-					slog.String("error", decErr.Error()),
+					telemetry.SensitiveLogError("error", decErr),
 				)
 				return true // Skip corrupted, continue
 			}
@@ -14,14 +14,24 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/replay"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
+	"github.com/dgraph-io/badger/v4"
 	"github.com/gin-gonic/gin"
 )
 
@@ -33,6 +43,7 @@ func init() {
 type MockAgentLoop struct {
 	runFunc          func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error)
 	continueFunc     func(ctx context.Context, sessionID string, clarification string) (*agent.RunResult, error)
+	continueOptFunc  func(ctx context.Context, sessionID string, optionID string) (*agent.RunResult, error)
 	abortFunc        func(ctx context.Context, sessionID string) error
 	getStateFunc     func(sessionID string) (*agent.SessionState, error)
 	getSessionFunc   func(sessionID string) (*agent.Session, error)
@@ -61,6 +72,16 @@ func (m *MockAgentLoop) Continue(ctx context.Context, sessionID string, clarific
 	}, nil
 }
 
+func (m *MockAgentLoop) ContinueWithOption(ctx context.Context, sessionID string, optionID string) (*agent.RunResult, error) {
+	if m.continueOptFunc != nil {
+		return m.continueOptFunc(ctx, sessionID, optionID)
+	}
+	return &agent.RunResult{
+		State:      agent.StateComplete,
+		StepsTaken: 2,
+	}, nil
+}
+
 func (m *MockAgentLoop) Abort(ctx context.Context, sessionID string) error {
 	if m.abortFunc != nil {
 		return m.abortFunc(ctx, sessionID)
@@ -179,6 +200,167 @@ func TestAgentHandlers_HandleAgentRun_EmptyQuery(t *testing.T) {
 	}
 }
 
+func TestAgentHandlers_HandleAgentRun_RootNotAllowed(t *testing.T) {
+	config := DefaultServiceConfig()
+	config.AllowedRoots = []string{t.TempDir()}
+	handlers := NewAgentHandlers(&MockAgentLoop{}, NewService(config))
+	r := setupAgentTestRouter(handlers)
+
+	jsonBody, _ := json.Marshal(AgentRunRequest{ProjectRoot: "/etc", Query: "list the files"})
+	req := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if !strings.Contains(w.Body.String(), "ROOT_NOT_ALLOWED") {
+		t.Errorf("body = %s, want ROOT_NOT_ALLOWED", w.Body.String())
+	}
+}
+func TestAgentHandlers_HandleAgentRun_Scope(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	root := writeMonorepo(t)
+	initResp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if _, err := svc.CreateView("payment", initResp.GraphID, []string{"services/payment/**"}, nil, ""); err != nil {
+		t.Fatalf("CreateView: %v", err)
+	}
+
+	var gotScope string
+	mockLoop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			gotScope = session.GetScope()
+			return &agent.RunResult{State: agent.StateComplete}, nil
+		},
+	}
+	r := setupAgentTestRouter(NewAgentHandlers(mockLoop, svc))
+
+	run := func(projectRoot, scope string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(AgentRunRequest{ProjectRoot: projectRoot, Query: "what calls Charge?", Scope: scope})
+		req := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := run(root, "payment"); w.Code != http.StatusOK || gotScope != "payment" {
+		t.Errorf("scoped run: status %d, session scope %q", w.Code, gotScope)
+	}
+	for _, tc := range []struct{ name, root, scope string }{
+		{"unknown view", root, "nope"},
+		{"other project", t.TempDir(), "payment"},
+	} {
+		w := run(tc.root, tc.scope)
+		var problem apierror.Problem
+		_ = json.Unmarshal(w.Body.Bytes(), &problem)
+		if w.Code != http.StatusBadRequest || problem.Code != apierror.CodeInvalidParameter {
+			t.Errorf("%s: status %d code %s, want 400 INVALID_PARAMETER", tc.name, w.Code, problem.Code)
+		}
+	}
+}
+
+func TestAgentHandlers_HandleAgentRun_Diff(t *testing.T) {
+	const reviewDiff = "--- a/main.go\n+++ b/main.go\n@@ -1,1 +1,1 @@\n-package old\n+package main\n"
+	changed := []agent.DiffSymbol{{SymbolID: "main.go:1:main", Name: "main", Relation: "changed", InContext: true}}
+	var gotDiff string
+	mockLoop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			gotDiff = session.GetDiff()
+			session.SetDiffSymbols(changed)
+			return &agent.RunResult{State: agent.StateComplete}, nil
+		},
+	}
+	r := setupAgentTestRouter(NewAgentHandlers(mockLoop, nil))
+
+	run := func(diffText string) *httptest.ResponseRecorder {
+		jsonBody, _ := json.Marshal(AgentRunRequest{ProjectRoot: "/test/project", Query: "review this change", Diff: diffText})
+		req := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := run(reviewDiff)
+	var resp AgentRunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if w.Code != http.StatusOK || gotDiff != reviewDiff {
+		t.Fatalf("diff run: status %d, session diff %q", w.Code, gotDiff)
+	}
+	if len(resp.DiffSymbols) != 1 || resp.DiffSymbols[0] != changed[0] {
+		t.Errorf("DiffSymbols = %+v, want %+v", resp.DiffSymbols, changed)
+	}
+
+	for _, tc := range []struct{ name, diff string }{
+		{"not a diff", "please review my change"},
+		{"too large", strings.Repeat("x", maxAgentDiffBytes+1)},
+	} {
+		w := run(tc.diff)
+		var problem apierror.Problem
+		_ = json.Unmarshal(w.Body.Bytes(), &problem)
+		if w.Code != http.StatusBadRequest || problem.Code != apierror.CodeInvalidParameter {
+			t.Errorf("%s: status %d code %s, want 400 INVALID_PARAMETER", tc.name, w.Code, problem.Code)
+		}
+	}
+}
+
+func TestAgentHandlers_HandleAgentRun_Record(t *testing.T) {
+	mockLoop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			if replay.RecorderFrom(ctx) == nil {
+				t.Error("recorded run should carry a recorder")
+			}
+			return &agent.RunResult{State: agent.StateComplete, Response: "done"}, nil
+		},
+	}
+	body, _ := json.Marshal(AgentRunRequest{
+		ProjectRoot: "/test/project",
+		Query:       "What does the add function do?",
+		Record:      true,
+	})
+	run := func(handlers *AgentHandlers) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		setupAgentTestRouter(handlers).ServeHTTP(w, req)
+		return w
+	}
+
+	// Recording is off unless the server allows it.
+	if w := run(NewAgentHandlers(mockLoop, nil)); w.Code != http.StatusForbidden ||
+		!strings.Contains(w.Body.String(), string(apierror.CodeRecordingNotAllowed)) {
+		t.Fatalf("Status = %d body %s, want 403 RECORDING_NOT_ALLOWED", w.Code, w.Body.String())
+	}
+
+	dir := t.TempDir()
+	w := run(NewAgentHandlers(mockLoop, nil, WithFixtureRecording(dir)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200", w.Code)
+	}
+	var resp AgentRunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if resp.FixturePath != filepath.Join(dir, resp.SessionID+".json") {
+		t.Errorf("FixturePath = %q, want it under %s", resp.FixturePath, dir)
+	}
+	fixture, err := replay.LoadFixture(resp.FixturePath)
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+	if fixture.Query != "What does the add function do?" {
+		t.Errorf("fixture query = %q", fixture.Query)
+	}
+}
+
 func TestAgentHandlers_HandleAgentRun_NeedsClarify(t *testing.T) {
 	mockLoop := &MockAgentLoop{
 		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
@@ -187,7 +369,10 @@ func TestAgentHandlers_HandleAgentRun_NeedsClarify(t *testing.T) {
 				StepsTaken: 2,
 				NeedsClarify: &agent.ClarifyRequest{
 					Question: "Which add function are you referring to?",
-					Options:  []string{"math.Add", "util.Add"},
+					Options: []agent.ClarificationOption{
+						{ID: "opt_1", Label: "Add (function) in math/add.go", SymbolID: "math/add.go:3:Add"},
+						{ID: "opt_2", Label: "Add (function) in util/add.go", SymbolID: "util/add.go:9:Add"},
+					},
 				},
 			}, nil
 		},
@@ -292,6 +477,58 @@ func TestAgentHandlers_HandleAgentContinue_NotFound(t *testing.T) {
 	}
 }
 
+func TestAgentHandlers_HandleAgentContinue_OptionID(t *testing.T) {
+	var gotOption string
+	mockLoop := &MockAgentLoop{
+		continueFunc: func(ctx context.Context, sessionID string, clarification string) (*agent.RunResult, error) {
+			t.Error("Continue called for an option_id request")
+			return nil, errors.New("unexpected")
+		},
+		continueOptFunc: func(ctx context.Context, sessionID string, optionID string) (*agent.RunResult, error) {
+			gotOption = optionID
+			if optionID != "opt_1" {
+				return nil, fmt.Errorf("%w: %q", agent.ErrUnknownClarificationOption, optionID)
+			}
+			return &agent.RunResult{State: agent.StateComplete, StepsTaken: 3}, nil
+		},
+	}
+	r := setupAgentTestRouter(NewAgentHandlers(mockLoop, nil))
+
+	tests := []struct {
+		name     string
+		body     AgentContinueRequest
+		wantCode int
+		wantErr  apierror.Code
+	}{
+		{"selected option", AgentContinueRequest{SessionID: "s1", OptionID: "opt_1"}, http.StatusOK, ""},
+		{"unknown option", AgentContinueRequest{SessionID: "s1", OptionID: "opt_7"}, http.StatusBadRequest, apierror.CodeInvalidParameter},
+		{"option and text", AgentContinueRequest{SessionID: "s1", OptionID: "opt_1", Clarification: "math.Add"}, http.StatusBadRequest, apierror.CodeInvalidParameter},
+		{"neither", AgentContinueRequest{SessionID: "s1"}, http.StatusBadRequest, apierror.CodeMissingParameter},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonBody, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest("POST", "/v1/trace/agent/continue", bytes.NewBuffer(jsonBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("Status = %d, want %d: %s", w.Code, tt.wantCode, w.Body.String())
+			}
+			if tt.wantErr != "" {
+				var problem apierror.Problem
+				if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil || problem.Code != tt.wantErr {
+					t.Errorf("problem = %+v (%v), want code %s", problem, err, tt.wantErr)
+				}
+			}
+		})
+	}
+	if gotOption != "opt_7" {
+		t.Errorf("last option passed to the loop = %q, want opt_7", gotOption)
+	}
+}
+
 func TestAgentHandlers_HandleAgentAbort_Success(t *testing.T) {
 	abortCalled := false
 	mockLoop := &MockAgentLoop{
@@ -350,6 +587,84 @@ func TestAgentHandlers_HandleAgentAbort_NotFound(t *testing.T) {
 	}
 }
 
+func TestAgentHandlers_HandleAgentAbort_ReturnsAbortRecord(t *testing.T) {
+	record := &agent.AbortRecord{
+		AbortedAt:          time.Now().UnixMilli(),
+		InterruptedPhase:   agent.StateExecute,
+		LastCompletedPhase: agent.StatePlan,
+		Resumable:          true,
+		Acknowledged:       true,
+	}
+	mockLoop := &MockAgentLoop{
+		getStateFunc: func(sessionID string) (*agent.SessionState, error) {
+			return &agent.SessionState{ID: sessionID, State: agent.StateAborted, Abort: record}, nil
+		},
+	}
+	r := setupAgentTestRouter(NewAgentHandlers(mockLoop, nil))
+
+	jsonBody, _ := json.Marshal(AgentAbortRequest{SessionID: "test-session-id"})
+	req := httptest.NewRequest("POST", "/v1/trace/agent/abort", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want %d", w.Code, http.StatusOK)
+	}
+	var resp AgentAbortResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.State != "ABORTED" || resp.Abort == nil || *resp.Abort != *record {
+		t.Errorf("response = %s", w.Body.String())
+	}
+}
+
+func TestAgentHandlers_HandleAgentContinue_NotResumable(t *testing.T) {
+	mockLoop := &MockAgentLoop{
+		continueFunc: func(ctx context.Context, sessionID string, clarification string) (*agent.RunResult, error) {
+			return nil, agent.ErrSessionNotResumable
+		},
+	}
+	r := setupAgentTestRouter(NewAgentHandlers(mockLoop, nil))
+
+	jsonBody, _ := json.Marshal(AgentContinueRequest{SessionID: "test-session-id", Clarification: "go on"})
+	req := httptest.NewRequest("POST", "/v1/trace/agent/continue", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "SESSION_NOT_RESUMABLE") {
+		t.Errorf("Status = %d, body %s; want 409 SESSION_NOT_RESUMABLE", w.Code, w.Body.String())
+	}
+}
+
+func TestAgentHandlers_HandleAgentRun_StreamsAbortedEvent(t *testing.T) {
+	mockLoop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			return &agent.RunResult{
+				State: agent.StateAborted,
+				Abort: &agent.AbortRecord{InterruptedPhase: agent.StateExecute, Resumable: true, Acknowledged: true},
+				Error: &agent.AgentError{Code: "ABORTED", Message: agent.ErrAborted.Error(), Recoverable: true},
+			}, nil
+		},
+	}
+	r := setupAgentTestRouter(NewAgentHandlers(mockLoop, nil))
+
+	jsonBody, _ := json.Marshal(AgentRunRequest{ProjectRoot: "/test/project", Query: "Who calls Save?"})
+	req := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewBuffer(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	body := w.Body.String()
+	aborted, result := strings.Index(body, "event:aborted"), strings.Index(body, "event:result")
+	if aborted < 0 || result < aborted {
+		t.Errorf("stream = %q, want an aborted event before the result", body)
+	}
+}
+
 func TestAgentHandlers_HandleAgentState_Success(t *testing.T) {
 	mockLoop := &MockAgentLoop{
 		getStateFunc: func(sessionID string) (*agent.SessionState, error) {
@@ -707,3 +1022,242 @@ func TestAgentHandlers_HandleGetCRSExport_MissingSessionID(t *testing.T) {
 		t.Errorf("Status = %d, want %d", w.Code, http.StatusBadRequest)
 	}
 }
+
+// newTestSessionStore creates a TranscriptStore backed by in-memory BadgerDB.
+func newTestSessionStore(t *testing.T) *agent.TranscriptStore {
+	t.Helper()
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("failed to open in-memory badger: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := agent.NewTranscriptStore(db, slog.Default(), agent.TranscriptRetention{})
+	if err != nil {
+		t.Fatalf("NewTranscriptStore: %v", err)
+	}
+	return store
+}
+
+func TestAgentHandlers_Sessions_NotConfigured(t *testing.T) {
+	handlers := NewAgentHandlers(&MockAgentLoop{}, nil)
+	r := setupAgentTestRouter(handlers)
+
+	for _, tc := range []struct{ method, path string }{
+		{"GET", "/v1/trace/agent/sessions"},
+		{"GET", "/v1/trace/agent/sessions/abc"},
+		{"DELETE", "/v1/trace/agent/sessions/abc"},
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s %s: status = %d, want 503", tc.method, tc.path, w.Code)
+		}
+	}
+}
+
+func TestAgentHandlers_Sessions_RunPersistsTranscript(t *testing.T) {
+	mockLoop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			return &agent.RunResult{
+				State:      agent.StateComplete,
+				StepsTaken: 2,
+				TokensUsed: 500,
+				Response:   "It parses the config.",
+			}, nil
+		},
+	}
+	handlers := NewAgentHandlers(mockLoop, nil, WithSessionStore(newTestSessionStore(t)))
+	r := setupAgentTestRouter(handlers)
+
+	body, _ := json.Marshal(AgentRunRequest{ProjectRoot: "/test/project", Query: "What does load do?"})
+	req := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var runResp AgentRunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &runResp); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if runResp.SessionID == "" || !strings.HasSuffix(runResp.TranscriptURL, runResp.SessionID) {
+		t.Fatalf("expected transcript URL for session, got %q", runResp.TranscriptURL)
+	}
+
+	// List with matching and non-matching filters
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/trace/agent/sessions?project_root=/test/project&status=COMPLETE", nil))
+	var listResp ListSessionsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &listResp); err != nil {
+		t.Fatalf("Failed to unmarshal list: %v", err)
+	}
+	if w.Code != http.StatusOK || listResp.Total != 1 || listResp.Sessions[0].SessionID != runResp.SessionID {
+		t.Errorf("unexpected list response: %d %+v", w.Code, listResp)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/trace/agent/sessions?project_root=/other", nil))
+	listResp = ListSessionsResponse{}
+	_ = json.Unmarshal(w.Body.Bytes(), &listResp)
+	if listResp.Total != 0 {
+		t.Errorf("expected no sessions for other project, got %d", listResp.Total)
+	}
+
+	// Get full transcript
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", runResp.TranscriptURL, nil))
+	var transcript agent.SessionTranscript
+	if err := json.Unmarshal(w.Body.Bytes(), &transcript); err != nil {
+		t.Fatalf("Failed to unmarshal transcript: %v", err)
+	}
+	if w.Code != http.StatusOK || transcript.Response != "It parses the config." || transcript.TokensUsed != 500 {
+		t.Errorf("unexpected transcript: %d %+v", w.Code, transcript)
+	}
+
+	// Delete, then 404
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("DELETE", runResp.TranscriptURL, nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("delete status = %d, want 200", w.Code)
+	}
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", runResp.TranscriptURL, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("get after delete status = %d, want 404", w.Code)
+	}
+}
+
+func TestAgentHandlers_RoutingFeedback_NotConfigured(t *testing.T) {
+	r := setupAgentTestRouter(NewAgentHandlers(&MockAgentLoop{}, nil))
+
+	for _, method := range []string{"GET", "DELETE"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, "/v1/trace/agent/debug/routing/feedback", nil))
+		if w.Code != http.StatusServiceUnavailable {
+			t.Errorf("%s status = %d, want %d", method, w.Code, http.StatusServiceUnavailable)
+		}
+	}
+}
+
+func TestAgentHandlers_RoutingFeedback_InspectAndReset(t *testing.T) {
+	ctx := context.Background()
+	fi := routing.NewFeedbackIndex(nil, 0, 0.2, nil)
+	rec := routing.RoutingFeedback{Query: "who calls main", Tool: "find_callers", Success: true, Model: "m", Vector: []float32{1, 0}}
+	if err := fi.Record(ctx, rec); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	r := setupAgentTestRouter(NewAgentHandlers(&MockAgentLoop{}, nil, WithRoutingFeedback(fi)))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/trace/agent/debug/routing/feedback?recent=5", nil))
+	var summary routing.FeedbackSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to unmarshal summary: %v", err)
+	}
+	if w.Code != http.StatusOK || summary.Total != 1 || len(summary.Recent) != 1 {
+		t.Errorf("unexpected summary: %d %+v", w.Code, summary)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/v1/trace/agent/debug/routing/feedback?recent=abc", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("invalid recent status = %d, want 400", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("DELETE", "/v1/trace/agent/debug/routing/feedback", nil))
+	var resetResp ResetRoutingFeedbackResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resetResp)
+	if w.Code != http.StatusOK || resetResp.Removed != 1 {
+		t.Errorf("unexpected reset response: %d %+v", w.Code, resetResp)
+	}
+}
+
+func TestRegisterAgentRoutesWithMiddleware_GuardsModelBackedRoutes(t *testing.T) {
+	handlers := NewAgentHandlers(&MockAgentLoop{}, nil)
+	r := gin.New()
+	guard := func(c *gin.Context) { c.AbortWithStatus(http.StatusServiceUnavailable) }
+	RegisterAgentRoutesWithMiddleware(r.Group("/v1"), handlers, guard)
+
+	for _, tc := range []struct {
+		method, path string
+		guarded      bool
+	}{
+		{"POST", "/v1/trace/agent/run", true},
+		{"POST", "/v1/trace/agent/continue", true},
+		{"GET", "/v1/trace/agent/unknown-session", false},
+		{"GET", "/v1/trace/agent/debug/history", false},
+	} {
+		req, _ := http.NewRequest(tc.method, tc.path, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if guarded := w.Code == http.StatusServiceUnavailable; guarded != tc.guarded {
+			t.Errorf("%s %s: status %d, guarded = %v, want %v", tc.method, tc.path, w.Code, guarded, tc.guarded)
+		}
+	}
+}
+
+func TestAgentHandlers_RoutingExplain_PreFilterOnly(t *testing.T) {
+	pf := routing.NewPreFilter(nil, &config.PreFilterConfig{
+		Enabled:       true,
+		MinCandidates: 3,
+		MaxCandidates: 10,
+		ForcedMappings: []config.ForcedMapping{{
+			Patterns: []string{"call chain from"},
+			Tool:     "get_call_chain",
+			Reason:   "Explicit call chain request",
+		}},
+	}, slog.Default(), nil)
+	handlers := NewAgentHandlers(&MockAgentLoop{}, nil, WithPreFilter(pf))
+	r := setupAgentTestRouter(handlers)
+
+	body, _ := json.Marshal(RoutingExplainRequest{Query: "Show the call chain from main", SkipRouter: true})
+	req := httptest.NewRequest("POST", "/v1/trace/routing/explain", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	var decision agent.RoutingDecision
+	if err := json.Unmarshal(w.Body.Bytes(), &decision); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if decision.FinalTool != "get_call_chain" || decision.Source != agent.RoutingSourcePrefilterForced {
+		t.Errorf("final = %s (%s), want forced get_call_chain", decision.FinalTool, decision.Source)
+	}
+	if decision.RouterModel != "" {
+		t.Errorf("RouterModel = %q, want empty with skip_router", decision.RouterModel)
+	}
+}
+
+func TestAgentHandlers_RoutingExplain_Errors(t *testing.T) {
+	handlers := NewAgentHandlers(&MockAgentLoop{}, nil)
+	r := setupAgentTestRouter(handlers)
+
+	for _, tc := range []struct {
+		name string
+		body string
+		want int
+		code apierror.Code
+	}{
+		{"empty query", `{"query": "  ", "skip_router": true}`, http.StatusBadRequest, apierror.CodeEmptyQuery},
+		{"missing query", `{}`, http.StatusBadRequest, apierror.CodeInvalidRequest},
+		{"nothing to route with", `{"query": "who calls main", "skip_router": true}`, http.StatusServiceUnavailable, apierror.CodeRoutingNotAvailable},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/v1/trace/routing/explain", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tc.want {
+				t.Errorf("Status = %d, want %d", w.Code, tc.want)
+			}
+			if !strings.Contains(w.Body.String(), string(tc.code)) {
+				t.Errorf("body %s does not name %s", w.Body.String(), tc.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/runqueue"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// WithRunQueue admits agent runs and continues through q.
+//
+// Description:
+//
+//	Without a queue every request runs at once. With one, a request
+//	waits for a free slot, by priority and then arrival; a client that
+//	accepts text/event-stream sees its queue position while it waits.
+func WithRunQueue(q *runqueue.Queue) AgentHandlersOption {
+	return func(h *AgentHandlers) {
+		h.runQueue = q
+	}
+}
+
+// QueueFullProblem is the 429 response when the run queue is full.
+type QueueFullProblem struct {
+	*apierror.Problem
+
+	// QueueDepth is the number of runs waiting.
+	QueueDepth int `json:"queue_depth"`
+
+	// EstimatedWaitMs estimates the wait of a run enqueued now.
+	EstimatedWaitMs int64 `json:"estimated_wait_ms"`
+}
+
+// QueuePositionEvent is the data of a "queue_position" server-sent event.
+type QueuePositionEvent struct {
+	// SessionID is the session of the waiting run.
+	SessionID string `json:"session_id"`
+
+	// Priority is the run's priority.
+	Priority runqueue.Priority `json:"priority"`
+
+	runqueue.Position
+}
+
+// RunStartedEvent is the data of a "started" server-sent event.
+type RunStartedEvent struct {
+	// SessionID is the session of the run.
+	SessionID string `json:"session_id"`
+
+	// WaitedMs is how long the run waited for a slot.
+	WaitedMs int64 `json:"waited_ms"`
+}
+
+// runResponder writes a run's outcome as a JSON response or, when the
+// client asked for server-sent events, as the last event of the stream.
+//
+// Thread Safety: Not safe for concurrent use.
+type runResponder struct {
+	c      *gin.Context
+	stream bool
+	open   bool
+}
+
+// newRunResponder returns a responder that streams if the request
+// accepts text/event-stream.
+func newRunResponder(c *gin.Context) *runResponder {
+	return &runResponder{c: c, stream: strings.Contains(c.GetHeader("Accept"), "text/event-stream")}
+}
+
+// event sends a server-sent event, starting the stream on first use.
+// No-op for a non-streaming client.
+func (r *runResponder) event(name string, data any) {
+	if !r.stream {
+		return
+	}
+	if !r.open {
+		r.c.Header("Content-Type", "text/event-stream")
+		r.c.Header("Cache-Control", "no-cache")
+		r.c.Header("Connection", "keep-alive")
+		r.c.Header("X-Accel-Buffering", "no")
+		r.c.Status(http.StatusOK)
+		r.open = true
+	}
+	r.c.SSEvent(name, data)
+	r.c.Writer.Flush()
+}
+
+// fail reports an error: as a problem response, or as an "error" event
+// once the stream has started.
+func (r *runResponder) fail(status int, code apierror.Code, detail string) {
+	r.failProblem(apierror.New(status, code, detail))
+}
+
+// failProblem is fail for a prepared problem.
+func (r *runResponder) failProblem(p *apierror.Problem) {
+	if !r.open {
+		p.Write(r.c)
+		return
+	}
+	if p.Instance == "" {
+		p.Instance = r.c.Request.URL.Path
+	}
+	if p.TraceID == "" {
+		p.TraceID = apierror.TraceID(r.c.Request.Context())
+	}
+	r.event("error", p)
+}
+
+// ok reports the run's result: as a 200 response, or as a "result" event
+// once the stream has started.
+func (r *runResponder) ok(resp any) {
+	if !r.open {
+		r.c.JSON(http.StatusOK, resp)
+		return
+	}
+	r.event("result", resp)
+}
+
+// waitForRunSlot queues the session's run and blocks until it may start.
+//
+// Description:
+//
+//	Resolves the requested priority against the request's API key,
+//	enqueues the run and waits for a slot, sending a "queue_position"
+//	event on every position change and a "started" event once admitted
+//	to a streaming client. Writes the error response itself on failure:
+//	400 for an unknown priority, 403 for a priority the key may not use,
+//	429 when the queue is full, 409 when the run was aborted while
+//	queued.
+//
+// Inputs:
+//
+//	c - The request.
+//	r - Where the run's responses go.
+//	sessionID - The run's session; POST /agent/abort removes it by this ID.
+//	requested - The requested priority; "" for the key's highest.
+//	logger - Request logger.
+//
+// Outputs:
+//
+//	func() - Releases the slot; call when the run finishes. Nil if
+//	  ok is false.
+//	bool - False if the run must not execute; the response is written.
+func (h *AgentHandlers) waitForRunSlot(c *gin.Context, r *runResponder, sessionID, requested string, logger *slog.Logger) (func(), bool) {
+	if h.runQueue == nil {
+		return func() {}, true
+	}
+	priority, err := h.runQueue.Resolve(apiKeyID(c), runqueue.Priority(requested))
+	switch {
+	case errors.Is(err, runqueue.ErrUnknownPriority):
+		r.fail(http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+		return nil, false
+	case err != nil:
+		logger.Warn("Run priority refused", "priority", requested, telemetry.SensitiveLogError("error", err))
+		r.fail(http.StatusForbidden, apierror.CodePriorityNotAllowed, err.Error())
+		return nil, false
+	}
+
+	ticket, err := h.runQueue.Enqueue(sessionID, priority)
+	switch {
+	case errors.Is(err, runqueue.ErrQueueFull):
+		h.writeQueueFull(c, priority, err)
+		return nil, false
+	case errors.Is(err, runqueue.ErrAlreadyQueued):
+		r.fail(http.StatusConflict, apierror.CodeSessionInProgress, err.Error())
+		return nil, false
+	case err != nil:
+		r.fail(http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		return nil, false
+	}
+
+	err = ticket.Wait(c.Request.Context(), func(pos runqueue.Position) {
+		r.event("queue_position", QueuePositionEvent{SessionID: sessionID, Priority: priority, Position: pos})
+	})
+	switch {
+	case errors.Is(err, runqueue.ErrRemoved):
+		logger.Info("Queued run aborted", "session_id", sessionID, "priority", priority)
+		r.fail(http.StatusConflict, apierror.CodeRunAborted, "run aborted while queued")
+		return nil, false
+	case err != nil:
+		logger.Info("Client left while run was queued", "session_id", sessionID, telemetry.SensitiveLogError("error", err))
+		return nil, false
+	}
+
+	waited := ticket.Waited()
+	if waited > 0 {
+		logger.Info("Run admitted from queue", "session_id", sessionID, "priority", priority, "waited_ms", waited.Milliseconds())
+	}
+	r.event("started", RunStartedEvent{SessionID: sessionID, WaitedMs: waited.Milliseconds()})
+	return ticket.Release, true
+}
+
+// writeQueueFull writes the 429 response for a full queue with its depth,
+// the estimated wait and a Retry-After header.
+func (h *AgentHandlers) writeQueueFull(c *gin.Context, priority runqueue.Priority, err error) {
+	wait := h.runQueue.EstimateWait(priority)
+	problem := &QueueFullProblem{
+		Problem:         apierror.New(http.StatusTooManyRequests, apierror.CodeQueueFull, err.Error()),
+		QueueDepth:      h.runQueue.Status().Depth,
+		EstimatedWaitMs: wait.Milliseconds(),
+	}
+	problem.Instance = c.Request.URL.Path
+	problem.TraceID = apierror.TraceID(c.Request.Context())
+	body, marshalErr := json.Marshal(problem)
+	if marshalErr != nil {
+		apierror.Write(c, http.StatusTooManyRequests, apierror.CodeQueueFull, err.Error())
+		return
+	}
+	c.Header("Retry-After", strconv.Itoa(int((wait+time.Second-1)/time.Second)))
+	c.Data(http.StatusTooManyRequests, apierror.ContentType, body)
+}
+
+// HandleAgentQueue handles GET /v1/trace/agent/queue.
+//
+// Description:
+//
+//	Reports the run queue: slots, running and waiting runs by priority,
+//	and the average run duration used for wait estimates. All zero when
+//	no queue is configured and runs are not limited.
+//
+// Response:
+//
+//	200 OK: runqueue.Status
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *AgentHandlers) HandleAgentQueue(c *gin.Context) {
+	if h.runQueue == nil {
+		c.JSON(http.StatusOK, runqueue.Status{})
+		return
+	}
+	c.JSON(http.StatusOK, h.runQueue.Status())
+}
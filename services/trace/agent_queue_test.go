@@ -0,0 +1,232 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/runqueue"
+	"github.com/gin-gonic/gin"
+)
+
+// syncRecorder is a ResponseRecorder whose body can be read while the
+// handler is still streaming.
+type syncRecorder struct {
+	*httptest.ResponseRecorder
+	mu sync.Mutex
+}
+
+func newSyncRecorder() *syncRecorder {
+	return &syncRecorder{ResponseRecorder: httptest.NewRecorder()}
+}
+
+func (r *syncRecorder) Write(b []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ResponseRecorder.Write(b)
+}
+
+func (r *syncRecorder) WriteString(s string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ResponseRecorder.WriteString(s)
+}
+
+func (r *syncRecorder) body() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.Body.String()
+}
+
+func postAgentRun(r *gin.Engine, w http.ResponseWriter, req AgentRunRequest, header map[string]string) {
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewBuffer(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		httpReq.Header.Set(k, v)
+	}
+	r.ServeHTTP(w, httpReq)
+}
+
+func waitQueue(t *testing.T, q *runqueue.Queue, cond func(runqueue.Status) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond(q.Status()) {
+			return
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	t.Fatalf("queue never reached the expected state: %+v", q.Status())
+}
+
+func TestAgentHandlers_RunQueue(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var order []string
+	loop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			mu.Lock()
+			order = append(order, query)
+			mu.Unlock()
+			if query == "first" {
+				<-release
+			}
+			return &agent.RunResult{State: agent.StateComplete, Response: query}, nil
+		},
+	}
+	q := runqueue.New(runqueue.Config{})
+	r := setupAgentTestRouter(NewAgentHandlers(loop, nil, WithRunQueue(q)))
+
+	var wg sync.WaitGroup
+	run := func(w http.ResponseWriter, query, priority string, header map[string]string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			postAgentRun(r, w, AgentRunRequest{ProjectRoot: "/test/project", Query: query, Priority: priority}, header)
+		}()
+	}
+
+	first := httptest.NewRecorder()
+	run(first, "first", "ci", nil)
+	waitQueue(t, q, func(s runqueue.Status) bool { return s.Running == 1 })
+
+	aborted := newSyncRecorder()
+	run(aborted, "aborted", "ci", map[string]string{"Accept": "text/event-stream"})
+	waitQueue(t, q, func(s runqueue.Status) bool { return s.Depth == 1 })
+	ci := httptest.NewRecorder()
+	run(ci, "ci", "ci", nil)
+	waitQueue(t, q, func(s runqueue.Status) bool { return s.Depth == 2 })
+	interactive := httptest.NewRecorder()
+	run(interactive, "interactive", "", nil)
+	waitQueue(t, q, func(s runqueue.Status) bool { return s.Depth == 3 })
+
+	// The streaming client was told where it stands, including the
+	// interactive run that overtook it.
+	sessionRe := regexp.MustCompile(`"session_id":"([^"]+)"`)
+	var sessionID string
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		body := aborted.body()
+		if strings.Contains(body, `"runs_ahead":1`) {
+			sessionID = sessionRe.FindStringSubmatch(body)[1]
+			break
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	if sessionID == "" {
+		t.Fatalf("no queue_position event moved the run back: %q", aborted.body())
+	}
+
+	abortBody, _ := json.Marshal(AgentAbortRequest{SessionID: sessionID})
+	abortReq := httptest.NewRequest("POST", "/v1/trace/agent/abort", bytes.NewBuffer(abortBody))
+	abortReq.Header.Set("Content-Type", "application/json")
+	abortW := httptest.NewRecorder()
+	r.ServeHTTP(abortW, abortReq)
+	if abortW.Code != http.StatusOK || !strings.Contains(abortW.Body.String(), "Queued run removed") {
+		t.Fatalf("abort: %d %s", abortW.Code, abortW.Body.String())
+	}
+
+	close(release)
+	wg.Wait()
+
+	if got := strings.Join(order, ","); got != "first,interactive,ci" {
+		t.Errorf("run order = %s, want first,interactive,ci", got)
+	}
+	for name, w := range map[string]*httptest.ResponseRecorder{"first": first, "ci": ci, "interactive": interactive} {
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: status %d: %s", name, w.Code, w.Body.String())
+		}
+	}
+	body := aborted.body()
+	if !strings.Contains(body, "event:queue_position") || !strings.Contains(body, "event:error") || !strings.Contains(body, "RUN_ABORTED") {
+		t.Errorf("aborted stream = %q", body)
+	}
+	if strings.Contains(body, "event:started") {
+		t.Error("aborted run started")
+	}
+}
+
+func TestAgentHandlers_RunQueue_Backpressure(t *testing.T) {
+	release := make(chan struct{})
+	loop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			<-release
+			return &agent.RunResult{State: agent.StateComplete}, nil
+		},
+	}
+	ciKey := func() string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest("GET", "/", nil)
+		c.Request.Header.Set("X-API-Key", "ci-secret")
+		return apiKeyID(c)
+	}()
+	q := runqueue.New(runqueue.Config{
+		MaxDepth:          1,
+		RunEstimate:       90 * time.Second,
+		AllowedPriorities: map[string][]runqueue.Priority{ciKey: {runqueue.PriorityCI}},
+	})
+	r := setupAgentTestRouter(NewAgentHandlers(loop, nil, WithRunQueue(q)))
+
+	w := httptest.NewRecorder()
+	postAgentRun(r, w, AgentRunRequest{ProjectRoot: "/test/project", Query: "q", Priority: "interactive"}, map[string]string{"X-API-Key": "ci-secret"})
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "PRIORITY_NOT_ALLOWED") {
+		t.Errorf("interactive with ci key: %d %s", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	postAgentRun(r, w, AgentRunRequest{ProjectRoot: "/test/project", Query: "q", Priority: "urgent"}, nil)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("unknown priority: %d %s", w.Code, w.Body.String())
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			postAgentRun(r, httptest.NewRecorder(), AgentRunRequest{ProjectRoot: "/test/project", Query: "q"}, nil)
+		}()
+		waitQueue(t, q, func(s runqueue.Status) bool { return s.Running+s.Depth == i+1 })
+	}
+
+	w = httptest.NewRecorder()
+	postAgentRun(r, w, AgentRunRequest{ProjectRoot: "/test/project", Query: "q"}, nil)
+	close(release)
+	wg.Wait()
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429: %s", w.Code, w.Body.String())
+	}
+	var problem struct {
+		Code            string `json:"code"`
+		QueueDepth      int    `json:"queue_depth"`
+		EstimatedWaitMs int64  `json:"estimated_wait_ms"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Code != "QUEUE_FULL" || problem.QueueDepth != 1 || problem.EstimatedWaitMs != 180_000 {
+		t.Errorf("problem = %+v", problem)
+	}
+	if got := w.Header().Get("Retry-After"); got != "180" {
+		t.Errorf("Retry-After = %q, want 180", got)
+	}
+}
@@ -0,0 +1,376 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/classifier"
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Agent run modes: the "mode" of AgentRunRequest and AgentRunResponse.
+const (
+	// AgentModeQuick answers a simple lookup with one or two graph tools
+	// and a single LLM call, skipping planning, reflection and the tool
+	// router.
+	AgentModeQuick = "quick"
+
+	// AgentModeFull runs the full agent loop.
+	AgentModeFull = "full"
+)
+
+// DefaultQuickAnswerTimeout bounds a quick answer, tools and LLM call
+// included. A quick answer not done by then falls back to the full loop.
+const DefaultQuickAnswerTimeout = 5 * time.Second
+
+// Quick answer fallback reasons: the quick_fallback of AgentRunResponse and
+// the reason label of trace_agent_quick_fallbacks_total.
+const (
+	quickFallbackNotConfigured = "not_configured"
+	quickFallbackUnsupported   = "unsupported_request"
+	quickFallbackNotLookup     = "not_a_lookup"
+	quickFallbackNoGraph       = "no_graph"
+	quickFallbackNotFound      = "symbol_not_found"
+	quickFallbackAmbiguous     = "ambiguous_symbol"
+	quickFallbackToolMiss      = "tool_miss"
+	quickFallbackLLMError      = "llm_error"
+	quickFallbackTimeout       = "timeout"
+)
+
+const (
+	// quickMaxToolOutput caps the characters of each tool output in the
+	// quick answer prompt.
+	quickMaxToolOutput = 6000
+
+	// quickMaxTokens caps the quick answer's length.
+	quickMaxTokens = 512
+
+	// quickResultLimit is the limit passed to the caller and callee tools.
+	quickResultLimit = 25
+)
+
+// quickSystemPrompt is the system prompt of the quick answer LLM call.
+const quickSystemPrompt = `You answer a question about a codebase using only the code graph tool output given with it.
+Answer directly in a few sentences or a short list. Cite locations as [file:line].
+Do not speculate beyond the tool output.`
+
+var (
+	// agentRunDuration is the latency of agent runs by the mode that served them.
+	agentRunDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "trace_agent_run_duration_seconds",
+		Help:    "Agent run latency from admission to response, by serving mode",
+		Buckets: []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"mode"})
+
+	// agentQuickFallbacks counts quick answer attempts that fell back to
+	// the full loop, by reason.
+	agentQuickFallbacks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "trace_agent_quick_fallbacks_total",
+		Help: "Quick answer attempts that fell back to the full agent loop, by reason",
+	}, []string{"reason"})
+)
+
+// QuickAnswerConfig configures quick answers. See WithQuickAnswer.
+type QuickAnswerConfig struct {
+	// Client is the main-role LLM client that writes the answer. Required.
+	Client agentllm.Client
+
+	// Timeout bounds a quick answer. Default: DefaultQuickAnswerTimeout.
+	Timeout time.Duration
+}
+
+// quickAnswerer answers simple lookups without the agent loop.
+//
+// Thread Safety: Safe for concurrent use.
+type quickAnswerer struct {
+	client  agentllm.Client
+	timeout time.Duration
+	lookups *classifier.RegexClassifier
+}
+
+// quickAnswer is a served quick answer.
+type quickAnswer struct {
+	response   string
+	toolsRun   int
+	tokensUsed int
+}
+
+// WithQuickAnswer enables quick answers to simple lookups.
+//
+// Description:
+//
+//	A run with "mode": "quick", or without a mode whose query is a simple
+//	lookup ("who calls X", "what does X call", "where is X defined",
+//	"what does X do"), resolves the symbol in the project's graph, runs
+//	one or two graph tools and makes a single LLM call with their output,
+//	all within cfg.Timeout. If the graph is not loaded, the symbol is
+//	unknown or ambiguous, a tool finds nothing, or the LLM call fails or
+//	runs out of time, the run falls back to the full loop and its
+//	response says why. Quick answers are not kept as sessions.
+func WithQuickAnswer(cfg QuickAnswerConfig) AgentHandlersOption {
+	return func(h *AgentHandlers) {
+		if cfg.Client == nil {
+			return
+		}
+		if cfg.Timeout <= 0 {
+			cfg.Timeout = DefaultQuickAnswerTimeout
+		}
+		h.quick = &quickAnswerer{
+			client:  cfg.Client,
+			timeout: cfg.Timeout,
+			lookups: classifier.NewRegexClassifier(),
+		}
+	}
+}
+
+// validAgentMode reports whether mode is a valid AgentRunRequest mode.
+func validAgentMode(mode string) bool {
+	return mode == "" || mode == AgentModeQuick || mode == AgentModeFull
+}
+
+// tryQuickAnswer answers req in quick mode when it asks for it or is a
+// simple lookup.
+//
+// Description:
+//
+//	Returns attempted false when the run should go straight to the full
+//	loop: mode "full", or no mode and a query that is not a simple lookup
+//	or not eligible. Otherwise returns the answer, or the fallback reason
+//	when it could not answer.
+//
+// Inputs:
+//
+//	ctx - The run context, carrying usage accounting.
+//	session - The run's session, for its main model override.
+//	req - The run request.
+//	logger - Request logger.
+//
+// Outputs:
+//
+//	*quickAnswer - The answer. Nil on fallback.
+//	string - The fallback reason. Empty when answered or not attempted.
+//	bool - Whether quick mode was attempted.
+func (h *AgentHandlers) tryQuickAnswer(ctx context.Context, session *agent.Session, req AgentRunRequest, logger *slog.Logger) (*quickAnswer, string, bool) {
+	explicit := req.Mode == AgentModeQuick
+	if req.Mode == AgentModeFull {
+		return nil, "", false
+	}
+	if h.quick == nil {
+		if explicit {
+			return nil, quickFallbackNotConfigured, true
+		}
+		return nil, "", false
+	}
+
+	lookup, isLookup := h.quick.lookups.ClassifyLookup(req.Query)
+	switch {
+	case req.Scope != "" || req.Record || req.Diff != "":
+		// Scoped views, replay fixtures and diff review are features of
+		// the loop.
+		if explicit {
+			return nil, quickFallbackUnsupported, true
+		}
+		return nil, "", false
+	case !isLookup:
+		if explicit {
+			return nil, quickFallbackNotLookup, true
+		}
+		return nil, "", false
+	}
+
+	answer, reason := h.quick.answer(ctx, h.svc, session, req, lookup, logger)
+	return answer, reason, true
+}
+
+// answer resolves lookup's symbol and answers the question, or returns the
+// reason it could not.
+func (q *quickAnswerer) answer(ctx context.Context, svc *Service, session *agent.Session, req AgentRunRequest, lookup classifier.Lookup, logger *slog.Logger) (*quickAnswer, string) {
+	ctx, cancel := context.WithTimeout(ctx, q.timeout)
+	defer cancel()
+
+	if svc == nil {
+		return nil, quickFallbackNoGraph
+	}
+	cached, err := svc.GetGraph(svc.projectGraphID(req.ProjectRoot, ""))
+	if err != nil || cached.Graph == nil || cached.Index == nil {
+		return nil, quickFallbackNoGraph
+	}
+
+	symbol, reason := resolveQuickSymbol(ctx, cached.Index, lookup, logger)
+	if symbol == nil {
+		return nil, reason
+	}
+
+	outputs, reason := runQuickTools(ctx, cached, lookup.Kind, symbol)
+	if outputs == nil {
+		if ctx.Err() != nil {
+			return nil, quickFallbackTimeout
+		}
+		return nil, reason
+	}
+
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Question: %s\n\nResolved symbol: %s %s at %s:%d\n",
+		req.Query, symbol.Kind, symbol.Name, symbol.FilePath, symbol.StartLine)
+	for _, out := range outputs {
+		if len(out) > quickMaxToolOutput {
+			out = out[:quickMaxToolOutput] + "\n[truncated]"
+		}
+		prompt.WriteString("\n")
+		prompt.WriteString(out)
+		prompt.WriteString("\n")
+	}
+
+	resp, err := q.client.Complete(ctx, &agentllm.Request{
+		SystemPrompt:  quickSystemPrompt,
+		Messages:      []agentllm.Message{{Role: "user", Content: prompt.String()}},
+		MaxTokens:     quickMaxTokens,
+		Temperature:   0.1,
+		ModelOverride: session.Config.MainModel,
+	})
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, quickFallbackTimeout
+		}
+		logger.Warn("Quick answer LLM call failed", telemetry.SensitiveLogError("error", err))
+		return nil, quickFallbackLLMError
+	}
+	if strings.TrimSpace(resp.Content) == "" {
+		return nil, quickFallbackLLMError
+	}
+	return &quickAnswer{
+		response:   resp.Content,
+		toolsRun:   len(outputs),
+		tokensUsed: resp.TokensUsed,
+	}, ""
+}
+
+// resolveQuickSymbol resolves the symbol of a lookup.
+//
+// Description:
+//
+//	Collects ranked candidates with the shared symbol resolution (exact,
+//	Type.Method, then fuzzy search). A dotted name takes the top candidate
+//	when its name matches the method part. A bare name needs exactly one
+//	candidate with that name; several make the lookup ambiguous, and none
+//	takes a single case-insensitive fuzzy match. Callers and callees
+//	lookups consider callable symbols only.
+//
+// Outputs:
+//
+//	*ast.Symbol - The symbol. Nil when not resolved.
+//	string - The fallback reason when not resolved.
+func resolveQuickSymbol(ctx context.Context, idx *index.SymbolIndex, lookup classifier.Lookup, logger *slog.Logger) (*ast.Symbol, string) {
+	kinds := tools.KindFilterAny
+	if lookup.Kind == classifier.LookupCallers || lookup.Kind == classifier.LookupCallees {
+		kinds = tools.KindFilterCallable
+	}
+	candidates, err := tools.ResolveFunctionCandidates(ctx, idx, lookup.Symbol, logger, 10, tools.WithKindFilter(kinds))
+	if err != nil || len(candidates) == 0 {
+		return nil, quickFallbackNotFound
+	}
+
+	name := lookup.Symbol
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		if candidates[0].Name != name[i+1:] {
+			return nil, quickFallbackNotFound
+		}
+		return candidates[0], ""
+	}
+
+	var exact, folded []*ast.Symbol
+	for _, c := range candidates {
+		switch {
+		case c.Name == name:
+			exact = append(exact, c)
+		case strings.EqualFold(c.Name, name):
+			folded = append(folded, c)
+		}
+	}
+	if len(exact) == 0 {
+		exact = folded
+	}
+	switch len(exact) {
+	case 0:
+		return nil, quickFallbackNotFound
+	case 1:
+		return exact[0], ""
+	default:
+		return nil, quickFallbackAmbiguous
+	}
+}
+
+// runQuickTools runs the graph tools answering a lookup of symbol and
+// returns their output text, or nil and the fallback reason when a tool
+// fails or finds nothing. Tools are passed the symbol's ID so they do not
+// resolve the name again.
+func runQuickTools(ctx context.Context, cached *CachedGraph, kind classifier.LookupKind, symbol *ast.Symbol) ([]string, string) {
+	g, idx := cached.Graph, cached.Index
+	type step struct {
+		tool   tools.Tool
+		params tools.TypedParams
+		// optional steps add context; finding nothing is no miss.
+		optional bool
+	}
+	var steps []step
+	switch kind {
+	case classifier.LookupCallers:
+		steps = []step{{tool: tools.NewFindCallersTool(g, idx), params: tools.FindCallersParams{FunctionName: symbol.ID, Limit: quickResultLimit}}}
+	case classifier.LookupCallees:
+		steps = []step{{tool: tools.NewFindCalleesTool(g, idx, nil), params: tools.FindCalleesParams{FunctionName: symbol.ID, Limit: quickResultLimit}}}
+	case classifier.LookupDefinition:
+		steps = []step{{tool: tools.NewReadSymbolTool(g, idx), params: tools.ReadSymbolParams{Name: symbol.ID}}}
+	default:
+		steps = []step{{tool: tools.NewReadSymbolTool(g, idx), params: tools.ReadSymbolParams{Name: symbol.ID}}}
+		if symbol.Kind == ast.SymbolKindFunction || symbol.Kind == ast.SymbolKindMethod {
+			steps = append(steps, step{tool: tools.NewFindCalleesTool(g, idx, nil), params: tools.FindCalleesParams{FunctionName: symbol.ID, Limit: quickResultLimit}, optional: true})
+		}
+	}
+
+	outputs := make([]string, 0, len(steps))
+	for _, s := range steps {
+		result, err := s.tool.Execute(ctx, s.params)
+		if err != nil || result == nil || !result.Success || quickToolFoundNothing(result.Output) {
+			if s.optional && ctx.Err() == nil {
+				continue
+			}
+			return nil, quickFallbackToolMiss
+		}
+		outputs = append(outputs, result.OutputText)
+	}
+	return outputs, ""
+}
+
+// quickToolFoundNothing reports whether a quick tool's output is empty.
+func quickToolFoundNothing(output any) bool {
+	switch out := output.(type) {
+	case tools.FindCallersOutput:
+		return out.TotalCallers == 0
+	case tools.FindCalleesOutput:
+		return out.TotalCount == 0
+	case tools.ReadSymbolOutput:
+		return out.MatchCount == 0
+	}
+	return false
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+)
+
+func TestAgentHandlers_HandleAgentRun_QuickMode(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	root := writeMonorepo(t)
+	if _, err := svc.Init(context.Background(), root, nil, nil); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	loopRuns := 0
+	mockLoop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			loopRuns++
+			return &agent.RunResult{State: agent.StateComplete, Response: "full answer"}, nil
+		},
+	}
+
+	run := func(t *testing.T, client *agentllm.MockClient, timeout time.Duration, body AgentRunRequest) (*httptest.ResponseRecorder, AgentRunResponse) {
+		t.Helper()
+		loopRuns = 0
+		r := setupAgentTestRouter(NewAgentHandlers(mockLoop, svc,
+			WithQuickAnswer(QuickAnswerConfig{Client: client, Timeout: timeout})))
+		body.ProjectRoot = root
+		jsonBody, _ := json.Marshal(body)
+		req := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewBuffer(jsonBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var resp AgentRunResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp
+	}
+
+	t.Run("simple lookup is answered without the loop", func(t *testing.T) {
+		client := agentllm.NewMockClient().QueueFinalResponse("Charge is called by PlaceOrder.")
+		w, resp := run(t, client, time.Minute, AgentRunRequest{Query: "Who calls Charge?"})
+		if w.Code != http.StatusOK || resp.Mode != AgentModeQuick || loopRuns != 0 {
+			t.Fatalf("status %d mode %q loop runs %d, want 200 quick 0", w.Code, resp.Mode, loopRuns)
+		}
+		if resp.Response != "Charge is called by PlaceOrder." || resp.StepsTaken != 1 {
+			t.Errorf("response = %+v", resp)
+		}
+		prompt := client.LastRequest().Messages[0].Content
+		if !strings.Contains(prompt, "PlaceOrder") {
+			t.Errorf("prompt lacks the find_callers output: %q", prompt)
+		}
+	})
+
+	t.Run("ambiguous symbol falls back to the full loop", func(t *testing.T) {
+		client := agentllm.NewMockClient()
+		_, resp := run(t, client, time.Minute, AgentRunRequest{Query: "who calls Save?"})
+		if resp.Mode != AgentModeFull || resp.QuickFallback != quickFallbackAmbiguous || loopRuns != 1 {
+			t.Errorf("mode %q fallback %q loop runs %d, want full ambiguous_symbol 1", resp.Mode, resp.QuickFallback, loopRuns)
+		}
+		if client.CallCount() != 0 {
+			t.Errorf("LLM calls = %d, want 0", client.CallCount())
+		}
+	})
+
+	t.Run("slow answer falls back on timeout", func(t *testing.T) {
+		client := agentllm.NewMockClient().WithDelay(100 * time.Millisecond)
+		_, resp := run(t, client, 20*time.Millisecond, AgentRunRequest{Query: "What does Charge call?"})
+		if resp.Mode != AgentModeFull || resp.QuickFallback != quickFallbackTimeout || resp.Response != "full answer" {
+			t.Errorf("mode %q fallback %q response %q, want a full answer after timeout", resp.Mode, resp.QuickFallback, resp.Response)
+		}
+	})
+
+	t.Run("mode selects the path", func(t *testing.T) {
+		client := agentllm.NewMockClient()
+		_, resp := run(t, client, time.Minute, AgentRunRequest{Query: "Who calls Charge?", Mode: AgentModeFull})
+		if resp.Mode != AgentModeFull || resp.QuickFallback != "" || client.CallCount() != 0 {
+			t.Errorf("mode full: mode %q fallback %q LLM calls %d", resp.Mode, resp.QuickFallback, client.CallCount())
+		}
+
+		_, resp = run(t, client, time.Minute, AgentRunRequest{Query: "How is payment wired into orders?", Mode: AgentModeQuick})
+		if resp.Mode != AgentModeFull || resp.QuickFallback != quickFallbackNotLookup {
+			t.Errorf("quick non-lookup: mode %q fallback %q", resp.Mode, resp.QuickFallback)
+		}
+
+		_, resp = run(t, client, time.Minute, AgentRunRequest{Query: "How is payment wired into orders?"})
+		if resp.Mode != AgentModeFull || resp.QuickFallback != "" {
+			t.Errorf("auto non-lookup: mode %q fallback %q", resp.Mode, resp.QuickFallback)
+		}
+
+		w, _ := run(t, client, time.Minute, AgentRunRequest{Query: "Who calls Charge?", Mode: "fast"})
+		var problem apierror.Problem
+		_ = json.Unmarshal(w.Body.Bytes(), &problem)
+		if w.Code != http.StatusBadRequest || problem.Code != apierror.CodeInvalidParameter {
+			t.Errorf("unknown mode: status %d code %s, want 400 INVALID_PARAMETER", w.Code, problem.Code)
+		}
+	})
+}
@@ -11,16 +11,21 @@
 package trace
 
 import (
-	"log/slog"
+	"errors"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/explore"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/patterns"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/reason"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/gin-gonic/gin"
 )
 
@@ -29,13 +34,118 @@ import (
 // =============================================================================
 
 // HandleGetTools returns all available tool definitions for agent discovery.
+//
+// The format query parameter selects the output: omitted for the trace
+// format with input and output schemas, "openai" or "anthropic" for the
+// providers' tool formats. The optional graph_id query parameter adds the
+// project tools of that graph, category "project", which run through
+// POST /v1/trace/tools/:name/execute.
 func (h *Handlers) HandleGetTools(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleGetTools")
-	logger.Info("Fetching tool definitions")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGetTools")
+	format := c.Query("format")
+	graphID := c.Query("graph_id")
+	logger.Info("Fetching tool definitions", "format", format, "graph_id", graphID)
 
 	registry := NewToolRegistry()
-	c.JSON(http.StatusOK, ToolsResponse{Tools: registry.GetTools()})
+	if graphID != "" {
+		cached, err := h.svc.GetGraph(graphID)
+		if err != nil {
+			logger.Warn("Graph not found", "graph_id", graphID, telemetry.SensitiveLogError("error", err))
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
+			return
+		}
+		registry.tools = append(registry.tools, projectToolDefinitions(cached)...)
+	}
+	switch format {
+	case "":
+		c.JSON(http.StatusOK, ToolsResponse{Tools: registry.GetTools()})
+	case "openai":
+		c.JSON(http.StatusOK, OpenAIToolsResponse{Tools: registry.OpenAITools()})
+	case "anthropic":
+		c.JSON(http.StatusOK, AnthropicToolsResponse{Tools: registry.AnthropicTools()})
+	default:
+		apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter, "unknown format "+strconv.Quote(format)).
+			WithDetails("format must be openai or anthropic, or omitted").Write(c)
+	}
+}
+
+// HandleExecuteProjectTool runs one of a graph's project tools.
+//
+// Description:
+//
+//	Project tools are declared in the project's .trace/tools.yaml and
+//	loaded at /init. They run through the tool executor with the same
+//	timeout and output cap as agent tool calls. Built-in tools have their
+//	own endpoints and are not run here.
+//
+// Errors:
+//
+//	400 GRAPH_NOT_FOUND - Unknown graph_id.
+//	400 INVALID_PARAMETER - Bad parameters, a built-in tool name, or a
+//	  tool that failed on its input (a missing file, a bad query).
+//	404 TOOL_NOT_FOUND - The graph has no project tool of that name.
+func (h *Handlers) HandleExecuteProjectTool(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	name := c.Param("name")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleExecuteProjectTool", "tool", name)
+
+	var req ToolExecuteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	cached, err := h.svc.GetGraph(req.GraphID)
+	if err != nil {
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
+		return
+	}
+
+	registry := projectToolRegistry(cached)
+	if _, ok := registry.Get(name); !ok {
+		if slices.Contains(builtinToolNames(), name) {
+			apierror.New(http.StatusBadRequest, apierror.CodeInvalidParameter, name+" is a built-in tool").
+				WithDetails("Only project tools run here; see GET /v1/trace/tools for the built-in endpoints").Write(c)
+			return
+		}
+		apierror.New(http.StatusNotFound, apierror.CodeToolNotFound, "no project tool named "+strconv.Quote(name)).
+			WithDetails("Project tools are declared in .trace/tools.yaml and listed by GET /v1/trace/tools?graph_id=").Write(c)
+		return
+	}
+
+	params := req.Params
+	if params == nil {
+		params = map[string]any{}
+	}
+	executor := tools.NewExecutorWithOptions(registry, nil)
+	result, err := executor.Execute(c.Request.Context(), &tools.Invocation{ToolName: name, Parameters: params})
+	switch {
+	case errors.Is(err, tools.ErrValidationFailed):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+		return
+	case err != nil:
+		logger.Error("Project tool failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Project tool failed")
+		return
+	case !result.Success:
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, result.Error)
+		return
+	}
+
+	logger.Info("Ran project tool", "duration_ms", time.Since(start).Milliseconds())
+	c.JSON(http.StatusOK, ToolExecuteResponse{
+		Tool:       name,
+		Result:     result.Output,
+		OutputText: result.OutputText,
+		Truncated:  result.Truncated,
+		LatencyMs:  time.Since(start).Milliseconds(),
+	})
 }
 
 // =============================================================================
@@ -46,26 +156,20 @@ func (h *Handlers) HandleGetTools(c *gin.Context) {
 func (h *Handlers) HandleFindEntryPoints(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindEntryPoints")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindEntryPoints")
 
 	var req FindEntryPointsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
@@ -86,18 +190,17 @@ func (h *Handlers) HandleFindEntryPoints(c *gin.Context) {
 		IncludeTests: req.IncludeTests,
 	})
 	if err != nil {
-		logger.Error("Failed to find entry points", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to find entry points",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to find entry points", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to find entry points")
 		return
 	}
 
 	logger.Info("Found entry points", "count", len(result.EntryPoints))
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -105,26 +208,20 @@ func (h *Handlers) HandleFindEntryPoints(c *gin.Context) {
 func (h *Handlers) HandleTraceDataFlow(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleTraceDataFlow")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleTraceDataFlow")
 
 	var req TraceDataFlowRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
@@ -141,19 +238,18 @@ func (h *Handlers) HandleTraceDataFlow(c *gin.Context) {
 	}
 	result, err := tracer.TraceDataFlow(c.Request.Context(), req.SourceID, opts...)
 	if err != nil {
-		logger.Error("Failed to trace data flow", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to trace data flow",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to trace data flow", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to trace data flow")
 		return
 	}
 
 	logger.Info("Traced data flow", "sources", len(result.Sources), "sinks", len(result.Sinks))
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:      result,
-		LatencyMs:   time.Since(start).Milliseconds(),
-		Limitations: result.Limitations,
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
+		Limitations:         result.Limitations,
 	})
 }
 
@@ -161,26 +257,20 @@ func (h *Handlers) HandleTraceDataFlow(c *gin.Context) {
 func (h *Handlers) HandleTraceErrorFlow(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleTraceErrorFlow")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleTraceErrorFlow")
 
 	var req TraceErrorFlowRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
@@ -192,18 +282,17 @@ func (h *Handlers) HandleTraceErrorFlow(c *gin.Context) {
 	tracer := explore.NewErrorFlowTracer(cached.Graph, cached.Index)
 	result, err := tracer.TraceErrorFlow(c.Request.Context(), req.Scope, explore.WithMaxHops(maxHops))
 	if err != nil {
-		logger.Error("Failed to trace error flow", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to trace error flow",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to trace error flow", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to trace error flow")
 		return
 	}
 
 	logger.Info("Traced error flow", "origins", len(result.Origins), "escapes", len(result.Escapes))
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -211,44 +300,37 @@ func (h *Handlers) HandleTraceErrorFlow(c *gin.Context) {
 func (h *Handlers) HandleFindConfigUsage(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindConfigUsage")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindConfigUsage")
 
 	var req FindConfigUsageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
 	finder := explore.NewConfigFinder(cached.Graph, cached.Index)
 	result, err := finder.FindConfigUsage(c.Request.Context(), req.ConfigKey)
 	if err != nil {
-		logger.Error("Failed to find config usage", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to find config usage",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to find config usage", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to find config usage")
 		return
 	}
 
 	logger.Info("Found config usage", "key", req.ConfigKey, "uses", len(result.UsedIn))
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -256,28 +338,23 @@ func (h *Handlers) HandleFindConfigUsage(c *gin.Context) {
 func (h *Handlers) HandleFindSimilarCode(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindSimilarCode")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindSimilarCode")
 
 	var req FindSimilarCodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
+	req.SymbolID = cached.ResolveSymbolID(req.SymbolID)
 
 	// Apply defaults
 	limit := req.Limit
@@ -289,28 +366,24 @@ func (h *Handlers) HandleFindSimilarCode(c *gin.Context) {
 
 	// Build the similarity index before searching
 	if err := engine.Build(c.Request.Context()); err != nil {
-		logger.Error("Failed to build similarity index", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to build similarity index",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to build similarity index", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to build similarity index")
 		return
 	}
 
 	result, err := engine.FindSimilarCode(c.Request.Context(), req.SymbolID, explore.WithMaxNodes(limit))
 	if err != nil {
-		logger.Error("Failed to find similar code", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to find similar code",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to find similar code", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to find similar code")
 		return
 	}
 
 	logger.Info("Found similar code", "matches", len(result.Results))
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -318,28 +391,23 @@ func (h *Handlers) HandleFindSimilarCode(c *gin.Context) {
 func (h *Handlers) HandleBuildMinimalContext(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleBuildMinimalContext")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleBuildMinimalContext")
 
 	var req BuildMinimalContextRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
+	req.SymbolID = cached.ResolveSymbolID(req.SymbolID)
 
 	tokenBudget := req.TokenBudget
 	if tokenBudget <= 0 {
@@ -349,18 +417,17 @@ func (h *Handlers) HandleBuildMinimalContext(c *gin.Context) {
 	builder := explore.NewMinimalContextBuilder(cached.Graph, cached.Index)
 	result, err := builder.BuildMinimalContext(c.Request.Context(), req.SymbolID, explore.WithTokenBudget(tokenBudget))
 	if err != nil {
-		logger.Error("Failed to build minimal context", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to build minimal context",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to build minimal context", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to build minimal context")
 		return
 	}
 
 	logger.Info("Built minimal context", "tokens", result.TotalTokens)
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -368,44 +435,37 @@ func (h *Handlers) HandleBuildMinimalContext(c *gin.Context) {
 func (h *Handlers) HandleSummarizeFile(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleSummarizeFile")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSummarizeFile")
 
 	var req SummarizeFileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
 	summarizer := explore.NewFileSummarizer(cached.Graph, cached.Index)
 	result, err := summarizer.SummarizeFile(c.Request.Context(), req.FilePath)
 	if err != nil {
-		logger.Error("Failed to summarize file", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to summarize file",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to summarize file", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to summarize file")
 		return
 	}
 
-	logger.Info("Summarized file", "file", req.FilePath)
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	logger.Info("Summarized file", telemetry.SensitiveLog("file", req.FilePath))
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -413,44 +473,37 @@ func (h *Handlers) HandleSummarizeFile(c *gin.Context) {
 func (h *Handlers) HandleSummarizePackage(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleSummarizePackage")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSummarizePackage")
 
 	var req SummarizePackageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
 	summarizer := explore.NewPackageAPISummarizer(cached.Graph, cached.Index)
 	result, err := summarizer.FindPackageAPI(c.Request.Context(), req.Package)
 	if err != nil {
-		logger.Error("Failed to summarize package", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to summarize package",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to summarize package", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to summarize package")
 		return
 	}
 
 	logger.Info("Summarized package", "package", req.Package)
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -458,44 +511,38 @@ func (h *Handlers) HandleSummarizePackage(c *gin.Context) {
 func (h *Handlers) HandleAnalyzeChangeImpact(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleAnalyzeChangeImpact")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleAnalyzeChangeImpact")
 
 	var req AnalyzeChangeImpactRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
+	req.SymbolID = cached.ResolveSymbolID(req.SymbolID)
 
 	analyzer := analysis.NewBlastRadiusAnalyzer(cached.Graph, cached.Index, nil)
 	result, err := analyzer.Analyze(c.Request.Context(), req.SymbolID, nil)
 	if err != nil {
-		logger.Error("Failed to analyze change impact", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to analyze change impact",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to analyze change impact", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to analyze change impact")
 		return
 	}
 
-	logger.Info("Analyzed change impact", "symbol", req.SymbolID)
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	logger.Info("Analyzed change impact", telemetry.SensitiveLog("symbol", req.SymbolID))
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -507,45 +554,39 @@ func (h *Handlers) HandleAnalyzeChangeImpact(c *gin.Context) {
 func (h *Handlers) HandleCheckBreakingChanges(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleCheckBreakingChanges")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleCheckBreakingChanges")
 
 	var req CheckBreakingChangesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
+	req.SymbolID = cached.ResolveSymbolID(req.SymbolID)
 
 	analyzer := reason.NewBreakingChangeAnalyzer(cached.Graph, cached.Index)
 	result, err := analyzer.AnalyzeBreaking(c.Request.Context(), req.SymbolID, req.ProposedSignature)
 	if err != nil {
-		logger.Error("Failed to check breaking changes", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to check breaking changes",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to check breaking changes", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to check breaking changes")
 		return
 	}
 
 	logger.Info("Checked breaking changes", "is_breaking", result.IsBreaking, "callers_affected", result.CallersAffected)
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:      result,
-		LatencyMs:   time.Since(start).Milliseconds(),
-		Limitations: result.Limitations,
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
+		Limitations:         result.Limitations,
 	})
 }
 
@@ -553,28 +594,23 @@ func (h *Handlers) HandleCheckBreakingChanges(c *gin.Context) {
 func (h *Handlers) HandleSimulateChange(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleSimulateChange")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSimulateChange")
 
 	var req SimulateChangeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
+	req.SymbolID = cached.ResolveSymbolID(req.SymbolID)
 
 	// Extract new signature from change details
 	newSignature := ""
@@ -596,30 +632,26 @@ func (h *Handlers) HandleSimulateChange(c *gin.Context) {
 
 	// Validate we have a new signature to simulate
 	if newSignature == "" {
-		logger.Warn("No new signature or valid change details provided", "symbol_id", req.SymbolID, "change_type", req.ChangeType)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   "new_signature or valid change_details required",
-			Code:    "INVALID_REQUEST",
-			Details: "For rename operations, provide change_details.new_name. For other changes, provide change_details.new_signature",
-		})
+		logger.Warn("No new signature or valid change details provided", telemetry.SensitiveLog("symbol_id", req.SymbolID), "change_type", req.ChangeType)
+		apierror.New(http.StatusBadRequest, apierror.CodeInvalidRequest, "new_signature or valid change_details required").
+			WithDetails("For rename operations, provide change_details.new_name. For other changes, provide change_details.new_signature").Write(c)
 		return
 	}
 
 	simulator := reason.NewChangeSimulator(cached.Graph, cached.Index)
 	result, err := simulator.SimulateChange(c.Request.Context(), req.SymbolID, newSignature)
 	if err != nil {
-		logger.Error("Failed to simulate change", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to simulate change",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to simulate change", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to simulate change")
 		return
 	}
 
 	logger.Info("Simulated change", "updates_needed", len(result.CallersToUpdate))
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -627,26 +659,20 @@ func (h *Handlers) HandleSimulateChange(c *gin.Context) {
 func (h *Handlers) HandleValidateChange(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleValidateChange")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleValidateChange")
 
 	var req ValidateChangeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	validator := reason.NewChangeValidator(nil) // nil index for validation-only
 	result, err := validator.ValidateChange(c.Request.Context(), req.Code, req.Language)
 	if err != nil {
-		logger.Error("Failed to validate change", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to validate change",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to validate change", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to validate change")
 		return
 	}
 
@@ -664,44 +690,38 @@ func (h *Handlers) HandleValidateChange(c *gin.Context) {
 func (h *Handlers) HandleFindTestCoverage(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindTestCoverage")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindTestCoverage")
 
 	var req FindTestCoverageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
+	req.SymbolID = cached.ResolveSymbolID(req.SymbolID)
 
 	finder := reason.NewTestCoverageFinder(cached.Graph, cached.Index)
 	result, err := finder.FindTestCoverage(c.Request.Context(), req.SymbolID)
 	if err != nil {
-		logger.Error("Failed to find test coverage", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to find test coverage",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to find test coverage", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to find test coverage")
 		return
 	}
 
 	logger.Info("Found test coverage", "direct", len(result.DirectTests), "indirect", len(result.IndirectTests))
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -709,44 +729,38 @@ func (h *Handlers) HandleFindTestCoverage(c *gin.Context) {
 func (h *Handlers) HandleDetectSideEffects(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleDetectSideEffects")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDetectSideEffects")
 
 	var req DetectSideEffectsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
+	req.SymbolID = cached.ResolveSymbolID(req.SymbolID)
 
 	analyzer := reason.NewSideEffectAnalyzer(cached.Graph, cached.Index)
 	result, err := analyzer.FindSideEffects(c.Request.Context(), req.SymbolID)
 	if err != nil {
-		logger.Error("Failed to detect side effects", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to detect side effects",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to detect side effects", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to detect side effects")
 		return
 	}
 
 	logger.Info("Detected side effects", "count", len(result.SideEffects))
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
 	})
 }
 
@@ -754,44 +768,83 @@ func (h *Handlers) HandleDetectSideEffects(c *gin.Context) {
 func (h *Handlers) HandleSuggestRefactor(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleSuggestRefactor")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSuggestRefactor")
 
 	var req SuggestRefactorRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
+	req.SymbolID = cached.ResolveSymbolID(req.SymbolID)
 
 	suggester := reason.NewRefactorSuggester(cached.Graph, cached.Index)
 	result, err := suggester.SuggestRefactor(c.Request.Context(), req.SymbolID)
 	if err != nil {
-		logger.Error("Failed to suggest refactoring", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to suggest refactoring",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to suggest refactoring", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to suggest refactoring")
 		return
 	}
 
 	logger.Info("Suggested refactoring", "suggestions", len(result.Suggestions))
-	c.JSON(http.StatusOK, AgenticResponse{
-		Result:    result,
-		LatencyMs: time.Since(start).Milliseconds(),
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
+	})
+}
+
+// HandleAnalyzeImpact lists the symbols affected by changes to a set of files.
+func (h *Handlers) HandleAnalyzeImpact(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleAnalyzeImpact")
+
+	var req AnalyzeImpactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	cached, err := h.svc.GetGraph(req.GraphID)
+	if err != nil {
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
+		return
+	}
+
+	analyzer := reason.NewFileImpactAnalyzer(cached.Graph, cached.Index)
+	result, err := analyzer.AnalyzeFileImpact(c.Request.Context(), req.Files, reason.FileImpactOptions{
+		MaxDepth: req.MaxDepth,
+	})
+	if err != nil {
+		logger.Error("Failed to analyze impact", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to analyze impact")
+		return
+	}
+
+	logger.Info("Analyzed impact",
+		"files", len(req.Files),
+		"direct", result.TotalDirectDependents,
+		"transitive", result.TotalTransitiveDependents,
+		"tests", len(result.AffectedTests))
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
+		Limitations:         result.Limitations,
 	})
 }
 
@@ -803,28 +856,23 @@ func (h *Handlers) HandleSuggestRefactor(c *gin.Context) {
 func (h *Handlers) HandlePlanMultiFileChange(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandlePlanMultiFileChange")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandlePlanMultiFileChange")
 
 	var req PlanMultiFileChangeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
+	req.TargetID = cached.ResolveSymbolID(req.TargetID)
 
 	breakingAnalyzer := reason.NewBreakingChangeAnalyzer(cached.Graph, cached.Index)
 	blastAnalyzer := analysis.NewBlastRadiusAnalyzer(cached.Graph, cached.Index, nil)
@@ -850,26 +898,141 @@ func (h *Handlers) HandlePlanMultiFileChange(c *gin.Context) {
 
 	result, err := coordinator.PlanChanges(c.Request.Context(), changeSet, &opts)
 	if err != nil {
-		logger.Error("Failed to plan multi-file change", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to plan multi-file change",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to plan multi-file change", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to plan multi-file change")
+		return
+	}
+
+	decision, err := h.checkPlanBlastRadius(c.Request.Context(), result)
+	if err != nil {
+		logger.Error("Failed to evaluate safety policy", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to evaluate safety policy")
+		return
+	}
+	if !decision.Allowed {
+		logger.Warn("Safety policy denied change plan",
+			"rule_id", decision.RuleID,
+			"reason", decision.Reason,
+			"files", result.TotalFiles,
+		)
+		apierror.New(http.StatusForbidden, apierror.CodeSafetyDenied, decision.Reason).
+			WithDetails("Denied by safety rule " + decision.RuleID + "; narrow the change or raise the limit in TRACE_SAFETY_POLICY").Write(c)
 		return
 	}
 
 	// Set the graph ID so the plan can be validated/previewed later
 	result.GraphID = req.GraphID
 
-	// Store plan for later validation/preview
-	h.svc.StorePlan(result)
+	// Store plan for later validation/preview, pending if the policy
+	// requires approval.
+	summary := h.svc.StorePlan(result, req.SessionID, PlanApproval{
+		Required: decision.ApprovalRequired,
+		RuleID:   decision.ApprovalRuleID,
+		Reason:   decision.ApprovalReason,
+	})
 
-	logger.Info("Planned multi-file change", "files", result.TotalFiles, "changes", result.TotalChanges)
-	c.JSON(http.StatusOK, AgenticResponse{
+	logger.Info("Planned multi-file change",
+		"files", result.TotalFiles,
+		"changes", result.TotalChanges,
+		"plan_id", summary.PlanID,
+		"status", summary.Status,
+	)
+	h.writeAgentic(c, cached, AgenticResponse{
+		Result:      result,
+		LatencyMs:   time.Since(start).Milliseconds(),
+		Warnings:    result.Warnings,
+		Limitations: result.Limitations,
+		PlanStatus:  &summary,
+	})
+}
+
+// HandlePreviewRename plans a graph-aware rename without applying it.
+//
+// The result is a ChangePlan with one file change per occurrence, stored
+// like a plan_changes plan so validate_plan, preview_changes and the
+// approval flow accept its plan_id.
+func (h *Handlers) HandlePreviewRename(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandlePreviewRename")
+
+	var req PreviewRenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	cached, err := h.svc.GetGraph(req.GraphID)
+	if err != nil {
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
+		return
+	}
+	req.TargetID = cached.ResolveSymbolID(req.TargetID)
+
+	coordinator := coordinate.NewMultiFileChangeCoordinator(
+		cached.Graph, cached.Index,
+		reason.NewBreakingChangeAnalyzer(cached.Graph, cached.Index),
+		analysis.NewBlastRadiusAnalyzer(cached.Graph, cached.Index, nil),
+		reason.NewChangeValidator(cached.Index),
+	)
+
+	opts := coordinate.DefaultPlanOptions()
+	opts.IncludeTests = req.IncludeTests
+
+	result, err := coordinator.PreviewRename(c.Request.Context(), req.TargetID, req.NewName, &opts)
+	switch {
+	case errors.Is(err, coordinate.ErrSymbolNotFound):
+		apierror.Write(c, http.StatusNotFound, apierror.CodeSymbolNotFound, "Symbol not found: "+req.TargetID)
+		return
+	case errors.Is(err, coordinate.ErrInvalidInput):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+		return
+	case err != nil:
+		logger.Error("Failed to preview rename", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to preview rename")
+		return
+	}
+
+	decision, err := h.checkPlanBlastRadius(c.Request.Context(), result)
+	if err != nil {
+		logger.Error("Failed to evaluate safety policy", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to evaluate safety policy")
+		return
+	}
+	if !decision.Allowed {
+		logger.Warn("Safety policy denied rename plan",
+			"rule_id", decision.RuleID,
+			"reason", decision.Reason,
+			"files", result.TotalFiles,
+		)
+		apierror.New(http.StatusForbidden, apierror.CodeSafetyDenied, decision.Reason).
+			WithDetails("Denied by safety rule " + decision.RuleID + "; narrow the change or raise the limit in TRACE_SAFETY_POLICY").Write(c)
+		return
+	}
+
+	result.GraphID = req.GraphID
+	summary := h.svc.StorePlan(result, req.SessionID, PlanApproval{
+		Required: decision.ApprovalRequired,
+		RuleID:   decision.ApprovalRuleID,
+		Reason:   decision.ApprovalReason,
+	})
+
+	logger.Info("Previewed rename",
+		"files", result.TotalFiles,
+		"edits", result.TotalChanges,
+		"risks", len(result.Risks),
+		"plan_id", summary.PlanID,
+		"status", summary.Status,
+	)
+	h.writeAgentic(c, cached, AgenticResponse{
 		Result:      result,
 		LatencyMs:   time.Since(start).Milliseconds(),
 		Warnings:    result.Warnings,
 		Limitations: result.Limitations,
+		PlanStatus:  &summary,
 	})
 }
 
@@ -877,46 +1040,34 @@ func (h *Handlers) HandlePlanMultiFileChange(c *gin.Context) {
 func (h *Handlers) HandleValidatePlan(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleValidatePlan")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleValidatePlan")
 
 	var req ValidatePlanRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	planData, err := h.svc.GetPlan(req.PlanID)
 	if err != nil {
 		logger.Warn("Plan not found", "plan_id", req.PlanID)
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error: "Plan not found",
-			Code:  "PLAN_NOT_FOUND",
-		})
+		apierror.Write(c, http.StatusNotFound, apierror.CodePlanNotFound, "Plan not found")
 		return
 	}
 
 	plan, ok := planData.(*coordinate.ChangePlan)
 	if !ok {
 		logger.Error("Invalid plan type")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Invalid plan data",
-			Code:  "INTERNAL_ERROR",
-		})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid plan data")
 		return
 	}
 
 	cached, err := h.svc.GetGraphForPlan(planData)
 	if err != nil {
 		logger.Warn("Graph for plan not found", "plan_id", req.PlanID)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "The graph for this plan is no longer available",
-		})
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("The graph for this plan is no longer available").Write(c)
 		return
 	}
 
@@ -931,16 +1082,13 @@ func (h *Handlers) HandleValidatePlan(c *gin.Context) {
 
 	result, err := coordinator.ValidatePlan(c.Request.Context(), plan)
 	if err != nil {
-		logger.Error("Failed to validate plan", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to validate plan",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to validate plan", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to validate plan")
 		return
 	}
 
 	logger.Info("Validated plan", "valid", result.Valid)
-	c.JSON(http.StatusOK, AgenticResponse{
+	h.writeAgentic(c, cached, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
 		Warnings:  result.Warnings,
@@ -951,46 +1099,34 @@ func (h *Handlers) HandleValidatePlan(c *gin.Context) {
 func (h *Handlers) HandlePreviewChanges(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandlePreviewChanges")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandlePreviewChanges")
 
 	var req PreviewChangesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	planData, err := h.svc.GetPlan(req.PlanID)
 	if err != nil {
 		logger.Warn("Plan not found", "plan_id", req.PlanID)
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error: "Plan not found",
-			Code:  "PLAN_NOT_FOUND",
-		})
+		apierror.Write(c, http.StatusNotFound, apierror.CodePlanNotFound, "Plan not found")
 		return
 	}
 
 	plan, ok := planData.(*coordinate.ChangePlan)
 	if !ok {
 		logger.Error("Invalid plan type")
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Invalid plan data",
-			Code:  "INTERNAL_ERROR",
-		})
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid plan data")
 		return
 	}
 
 	cached, err := h.svc.GetGraphForPlan(planData)
 	if err != nil {
 		logger.Warn("Graph for plan not found", "plan_id", req.PlanID)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "The graph for this plan is no longer available",
-		})
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("The graph for this plan is no longer available").Write(c)
 		return
 	}
 
@@ -1005,16 +1141,13 @@ func (h *Handlers) HandlePreviewChanges(c *gin.Context) {
 
 	result, err := coordinator.PreviewChanges(c.Request.Context(), plan)
 	if err != nil {
-		logger.Error("Failed to preview changes", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to preview changes",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to preview changes", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to preview changes")
 		return
 	}
 
 	logger.Info("Previewed changes", "files", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	h.writeAgentic(c, cached, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
 	})
@@ -1028,26 +1161,20 @@ func (h *Handlers) HandlePreviewChanges(c *gin.Context) {
 func (h *Handlers) HandleDetectPatterns(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleDetectPatterns")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDetectPatterns")
 
 	var req DetectPatternsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
@@ -1072,16 +1199,13 @@ func (h *Handlers) HandleDetectPatterns(c *gin.Context) {
 
 	result, err := detector.DetectPatterns(c.Request.Context(), req.Scope, opts)
 	if err != nil {
-		logger.Error("Failed to detect patterns", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to detect patterns",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to detect patterns", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to detect patterns")
 		return
 	}
 
 	logger.Info("Detected patterns", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	h.writeAgentic(c, cached, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
 	})
@@ -1091,26 +1215,20 @@ func (h *Handlers) HandleDetectPatterns(c *gin.Context) {
 func (h *Handlers) HandleFindCodeSmells(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindCodeSmells")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindCodeSmells")
 
 	var req FindCodeSmellsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
@@ -1129,16 +1247,13 @@ func (h *Handlers) HandleFindCodeSmells(c *gin.Context) {
 
 	result, err := finder.FindCodeSmells(c.Request.Context(), req.Scope, opts)
 	if err != nil {
-		logger.Error("Failed to find code smells", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to find code smells",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to find code smells", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to find code smells")
 		return
 	}
 
 	logger.Info("Found code smells", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	h.writeAgentic(c, cached, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
 	})
@@ -1148,59 +1263,83 @@ func (h *Handlers) HandleFindCodeSmells(c *gin.Context) {
 func (h *Handlers) HandleFindDuplication(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindDuplication")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindDuplication")
 
 	var req FindDuplicationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+	if req.Mode != "" && req.Mode != "pairs" && req.Mode != "clusters" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "mode must be pairs or clusters")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
 	finder := patterns.NewDuplicationFinder(cached.Index, cached.ProjectRoot)
 
+	if req.Mode == "clusters" {
+		cloneOpts := patterns.DefaultCloneOptions()
+		if req.MinSimilarity > 0 {
+			cloneOpts.MinSimilarity = req.MinSimilarity
+		}
+		if req.MinBodyTokens > 0 {
+			cloneOpts.MinBodyTokens = req.MinBodyTokens
+		}
+		cloneOpts.IncludeTests = req.IncludeTests
+		cloneOpts.IncludeGenerated = req.IncludeGenerated
+		cloneOpts.CrossLanguageOnly = req.CrossLanguageOnly
+
+		clusters, err := finder.FindClones(c.Request.Context(), req.Scope, &cloneOpts)
+		if errors.Is(err, patterns.ErrInvalidInput) {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+			return
+		}
+		if err != nil {
+			logger.Error("Failed to find duplicate clusters", telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to find duplicate clusters")
+			return
+		}
+
+		logger.Info("Found duplicate clusters", "count", len(clusters))
+		h.writeAgentic(c, cached, AgenticResponse{
+			Result:    clusters,
+			LatencyMs: time.Since(start).Milliseconds(),
+		})
+		return
+	}
+
 	opts := patterns.DefaultDuplicationOptions()
 	if req.MinSimilarity > 0 {
 		opts.SimilarityThreshold = req.MinSimilarity
 	}
 	opts.Type = req.Type
 	opts.IncludeTests = req.IncludeTests
+	opts.IncludeGenerated = req.IncludeGenerated
 
 	if _, err := finder.BuildIndex(c.Request.Context(), &opts); err != nil {
-		logger.Error("Failed to build duplication index", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to build duplication index",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to build duplication index", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to build duplication index")
 		return
 	}
 
 	result, err := finder.FindDuplication(c.Request.Context(), req.Scope, &opts)
 	if err != nil {
-		logger.Error("Failed to find duplication", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to find duplication",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to find duplication", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to find duplication")
 		return
 	}
 
 	logger.Info("Found duplication", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	h.writeAgentic(c, cached, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
 	})
@@ -1210,26 +1349,20 @@ func (h *Handlers) HandleFindDuplication(c *gin.Context) {
 func (h *Handlers) HandleFindCircularDeps(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindCircularDeps")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindCircularDeps")
 
 	var req FindCircularDepsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
@@ -1242,16 +1375,13 @@ func (h *Handlers) HandleFindCircularDeps(c *gin.Context) {
 	finder := patterns.NewCircularDepFinder(cached.Graph, "")
 	result, err := finder.FindCircularDeps(c.Request.Context(), req.Scope, depType)
 	if err != nil {
-		logger.Error("Failed to find circular dependencies", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to find circular dependencies",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to find circular dependencies", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to find circular dependencies")
 		return
 	}
 
 	logger.Info("Found circular dependencies", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	h.writeAgentic(c, cached, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
 	})
@@ -1261,26 +1391,20 @@ func (h *Handlers) HandleFindCircularDeps(c *gin.Context) {
 func (h *Handlers) HandleExtractConventions(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleExtractConventions")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleExtractConventions")
 
 	var req ExtractConventionsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
@@ -1292,16 +1416,13 @@ func (h *Handlers) HandleExtractConventions(c *gin.Context) {
 
 	result, err := extractor.ExtractConventions(c.Request.Context(), req.Scope, &opts)
 	if err != nil {
-		logger.Error("Failed to extract conventions", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to extract conventions",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to extract conventions", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to extract conventions")
 		return
 	}
 
 	logger.Info("Extracted conventions", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	h.writeAgentic(c, cached, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
 	})
@@ -1311,46 +1432,38 @@ func (h *Handlers) HandleExtractConventions(c *gin.Context) {
 func (h *Handlers) HandleFindDeadCode(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindDeadCode")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindDeadCode")
 
 	var req FindDeadCodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
 	cached, err := h.svc.GetGraph(req.GraphID)
 	if err != nil {
-		logger.Warn("Graph not found", "graph_id", req.GraphID, "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error:   err.Error(),
-			Code:    "GRAPH_NOT_FOUND",
-			Details: "Ensure /init was called first",
-		})
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
 		return
 	}
 
 	finder := patterns.NewDeadCodeFinder(cached.Graph, cached.Index, cached.ProjectRoot)
 	opts := &patterns.DeadCodeOptions{
-		IncludeExported: req.IncludeExported,
+		IncludeExported:  req.IncludeExported,
+		IncludeGenerated: req.IncludeGenerated,
 	}
 
 	result, err := finder.FindDeadCode(c.Request.Context(), req.Scope, opts)
 	if err != nil {
-		logger.Error("Failed to find dead code", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "Failed to find dead code",
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Failed to find dead code", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to find dead code")
 		return
 	}
 
 	logger.Info("Found dead code", "count", len(result))
-	c.JSON(http.StatusOK, AgenticResponse{
+	h.writeAgentic(c, cached, AgenticResponse{
 		Result:    result,
 		LatencyMs: time.Since(start).Milliseconds(),
 	})
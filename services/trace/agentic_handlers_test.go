@@ -12,12 +12,19 @@ package trace
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/patterns"
 )
 
 // =============================================================================
@@ -41,9 +48,9 @@ func TestHandlers_HandleGetTools(t *testing.T) {
 		t.Fatalf("failed to unmarshal response: %v", err)
 	}
 
-	// Should have 24 tools
-	if len(resp.Tools) != 24 {
-		t.Errorf("expected 24 tools, got %d", len(resp.Tools))
+	// Should have 27 tools
+	if len(resp.Tools) != 27 {
+		t.Errorf("expected 27 tools, got %d", len(resp.Tools))
 	}
 
 	// Verify tool categories are present
@@ -54,8 +61,8 @@ func TestHandlers_HandleGetTools(t *testing.T) {
 
 	expectedCategories := map[string]int{
 		"explore":    9,
-		"reason":     6,
-		"coordinate": 3,
+		"reason":     8,
+		"coordinate": 4,
 		"patterns":   6,
 	}
 
@@ -66,6 +73,97 @@ func TestHandlers_HandleGetTools(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandleGetTools_Schemas(t *testing.T) {
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+
+	req, _ := http.NewRequest("GET", "/v1/trace/tools", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp ToolsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	for _, tool := range resp.Tools {
+		if tool.InputSchema == nil || tool.InputSchema.Type != "object" {
+			t.Errorf("%s: input_schema = %+v", tool.Name, tool.InputSchema)
+			continue
+		}
+		if len(tool.InputSchema.Properties) != len(tool.Parameters) {
+			t.Errorf("%s: %d schema properties for %d parameters", tool.Name, len(tool.InputSchema.Properties), len(tool.Parameters))
+		}
+		if tool.OutputSchema == nil || tool.OutputSchema.Properties["result"] == nil {
+			t.Errorf("%s: output_schema lacks result", tool.Name)
+		}
+	}
+
+	var entryPoints *ToolDefinition
+	for i := range resp.Tools {
+		if resp.Tools[i].Name == "find_entry_points" {
+			entryPoints = &resp.Tools[i]
+		}
+	}
+	if entryPoints == nil {
+		t.Fatal("find_entry_points missing")
+	}
+	in := entryPoints.InputSchema
+	if len(in.Required) != 1 || in.Required[0] != "graph_id" {
+		t.Errorf("required = %v, want [graph_id]", in.Required)
+	}
+	if limit := in.Properties["limit"]; limit.Type != "integer" || limit.Default != float64(100) {
+		t.Errorf("limit = %+v, want integer defaulting to 100", limit)
+	}
+	if typ := in.Properties["type"]; len(typ.Enum) != 8 {
+		t.Errorf("type enum = %v", typ.Enum)
+	}
+}
+
+func TestHandlers_HandleGetTools_ProviderFormats(t *testing.T) {
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+	get := func(format string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", "/v1/trace/tools?format="+format, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("openai")
+	var openai struct {
+		Tools []struct {
+			Type     string `json:"type"`
+			Function struct {
+				Name       string          `json:"name"`
+				Parameters json.RawMessage `json:"parameters"`
+			} `json:"function"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &openai); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("openai: %d %v", w.Code, err)
+	}
+	if len(openai.Tools) != 27 || openai.Tools[0].Type != "function" ||
+		!strings.Contains(string(openai.Tools[0].Function.Parameters), `"type":"object"`) {
+		t.Errorf("openai tools = %s", w.Body.String()[:200])
+	}
+
+	w = get("anthropic")
+	var anthropic struct {
+		Tools []struct {
+			Name        string          `json:"name"`
+			InputSchema json.RawMessage `json:"input_schema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &anthropic); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("anthropic: %d %v", w.Code, err)
+	}
+	if len(anthropic.Tools) != 27 || !strings.Contains(string(anthropic.Tools[0].InputSchema), `"graph_id"`) {
+		t.Errorf("anthropic tools = %s", w.Body.String()[:200])
+	}
+
+	if w := get("gemini"); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID_PARAMETER") {
+		t.Errorf("unknown format: %d %s", w.Code, w.Body.String())
+	}
+}
+
 // =============================================================================
 // EXPLORATION HANDLER TESTS
 // =============================================================================
@@ -168,6 +266,44 @@ func TestHandlers_HandleCheckBreakingChanges_GraphNotFound(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandleAnalyzeImpact_GraphNotFound(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	body := `{"graph_id": "nonexistent", "files": ["pkg/foo.go"]}`
+	req, _ := http.NewRequest("POST", "/v1/trace/reason/impact", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestHandlers_HandleAnalyzeImpact_NoFiles(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	body := `{"graph_id": "any", "files": []}`
+	req, _ := http.NewRequest("POST", "/v1/trace/reason/impact", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Code != "INVALID_REQUEST" {
+		t.Errorf("expected code INVALID_REQUEST, got %s", resp.Code)
+	}
+}
+
 func TestHandlers_HandleValidateChange_Success(t *testing.T) {
 	svc := NewService(DefaultServiceConfig())
 	router := setupTestRouter(svc)
@@ -227,6 +363,68 @@ func TestHandlers_HandlePlanMultiFileChange_GraphNotFound(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandlePreviewRename(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	dir := t.TempDir()
+	writeTestGoFiles(t, dir)
+	resp, err := svc.Init(context.Background(), dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph: %v", err)
+	}
+	var targetID string
+	for _, node := range cached.Graph.Nodes() {
+		if node.Symbol != nil && node.Symbol.Name == "helper" {
+			targetID = node.Symbol.ID
+		}
+	}
+
+	body, _ := json.Marshal(PreviewRenameRequest{GraphID: resp.GraphID, TargetID: targetID, NewName: "assist"})
+	w := postJSON(router, "/v1/trace/coordinate/preview_rename", string(body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var out struct {
+		Result     coordinate.ChangePlan `json:"result"`
+		PlanStatus *PlanSummary          `json:"plan_status"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	edits := make(map[string]coordinate.FileChange)
+	for _, fc := range out.Result.FileChanges {
+		edits[fc.FilePath] = fc
+	}
+	if fc := edits["util.go"]; fc.StartLine != 3 || fc.Column != 6 || fc.ProposedCode != `func assist() string { return "ok" }` {
+		t.Errorf("declaration edit = %+v", fc)
+	}
+	if fc := edits["main.go"]; fc.StartLine != 4 || fc.Column != 2 || fc.ChangeType != coordinate.FileChangeCallerUpdate {
+		t.Errorf("caller edit = %+v", fc)
+	}
+	if out.PlanStatus == nil {
+		t.Fatal("plan_status missing")
+	}
+
+	// The plan flows into preview_changes like a plan_changes plan.
+	w = postJSON(router, "/v1/trace/coordinate/preview_changes", `{"plan_id": "`+out.PlanStatus.PlanID+`"}`)
+	if w.Code != http.StatusOK {
+		t.Errorf("preview_changes status = %d: %s", w.Code, w.Body.String())
+	}
+
+	body, _ = json.Marshal(PreviewRenameRequest{GraphID: resp.GraphID, TargetID: targetID, NewName: "not a name"})
+	if w := postJSON(router, "/v1/trace/coordinate/preview_rename", string(body)); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid name status = %d, want 400", w.Code)
+	}
+	body, _ = json.Marshal(PreviewRenameRequest{GraphID: resp.GraphID, TargetID: "nope.go:1:nope", NewName: "other"})
+	if w := postJSON(router, "/v1/trace/coordinate/preview_rename", string(body)); w.Code != http.StatusNotFound {
+		t.Errorf("unknown symbol status = %d, want 404", w.Code)
+	}
+}
+
 func TestHandlers_HandleValidatePlan_PlanNotFound(t *testing.T) {
 	svc := NewService(DefaultServiceConfig())
 	router := setupTestRouter(svc)
@@ -315,6 +513,187 @@ func TestHandlers_HandleFindDuplication_GraphNotFound(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandleFindDuplication_Clusters(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"limits.go": `package main
+
+func clampLimit(limit int) int {
+	if limit <= 0 {
+		return 10
+	}
+	if limit > 500 {
+		return 500
+	}
+	return limit
+}
+
+func main() {}
+`,
+		"web/limits.ts": `export function clampPageSize(size: number): number {
+  if (size <= 0) {
+    return 25;
+  }
+  if (size > 100) {
+    return 100;
+  }
+  return size;
+}
+`,
+	}
+	for path, content := range files {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), dir, []string{"go", "typescript"}, nil)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	router := setupTestRouter(svc)
+
+	// The bodies are 13 normalized tokens, under the default minimum.
+	w := postJSON(router, "/v1/trace/patterns/duplication",
+		`{"graph_id": "`+resp.GraphID+`", "mode": "clusters"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var empty struct {
+		Result []patterns.DuplicationCluster `json:"result"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &empty); err != nil {
+		t.Fatal(err)
+	}
+	if len(empty.Result) != 0 {
+		t.Errorf("clusters = %+v, want none below min_body_tokens", empty.Result)
+	}
+
+	w = postJSON(router, "/v1/trace/patterns/duplication",
+		`{"graph_id": "`+resp.GraphID+`", "mode": "clusters", "min_body_tokens": 10, "cross_language_only": true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var body struct {
+		Result []patterns.DuplicationCluster `json:"result"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Result) != 1 || len(body.Result[0].Members) != 2 || !body.Result[0].CrossLanguage {
+		t.Fatalf("clusters = %+v, want one Go/TypeScript cluster", body.Result)
+	}
+	if m := body.Result[0].Members[0]; m.FilePath != "limits.go" || m.LineStart != 3 || m.LineEnd != 11 {
+		t.Errorf("first member = %+v, want limits.go:3-11", m)
+	}
+
+	for _, bad := range []string{
+		`{"graph_id": "` + resp.GraphID + `", "mode": "groups"}`,
+		`{"graph_id": "` + resp.GraphID + `", "mode": "clusters", "min_similarity": 2}`,
+	} {
+		if w := postJSON(router, "/v1/trace/patterns/duplication", bad); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", bad, w.Code)
+		}
+	}
+}
+
+func TestHandlers_GeneratedCodeSuppressed(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"api/client.go": `package api
+
+// Fetch loads a record.
+func Fetch(id int) string { return "" }
+`,
+		"api/client.pb.go": `package api
+
+// GetName returns the name field.
+func GetName() string { return "" }
+`,
+		"api/zz_deepcopy.go": `// Code generated by controller-gen. DO NOT EDIT.
+
+package api
+
+func DeepCopy() {}
+
+func unusedGenerated() {}
+`,
+	}
+	for path, content := range files {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	router := setupTestRouter(svc)
+
+	type packageResponse struct {
+		Result struct {
+			Functions []struct {
+				Name string `json:"name"`
+			} `json:"functions"`
+		} `json:"result"`
+		GeneratedSuppressed int `json:"generated_suppressed"`
+	}
+	summarize := func(body string) packageResponse {
+		t.Helper()
+		w := postJSON(router, "/v1/trace/explore/summarize_package", body)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+		}
+		var out packageResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		return out
+	}
+
+	hidden := summarize(`{"graph_id": "` + resp.GraphID + `", "package": "api"}`)
+	if len(hidden.Result.Functions) != 1 || hidden.Result.Functions[0].Name != "Fetch" {
+		t.Errorf("functions = %+v, want only Fetch", hidden.Result.Functions)
+	}
+	if hidden.GeneratedSuppressed != 2 {
+		t.Errorf("generated_suppressed = %d, want 2", hidden.GeneratedSuppressed)
+	}
+
+	all := summarize(`{"graph_id": "` + resp.GraphID + `", "package": "api", "include_generated": true}`)
+	if len(all.Result.Functions) != 3 || all.GeneratedSuppressed != 0 {
+		t.Errorf("with include_generated: functions = %+v, suppressed = %d", all.Result.Functions, all.GeneratedSuppressed)
+	}
+
+	w := postJSON(router, "/v1/trace/patterns/dead_code", `{"graph_id": "`+resp.GraphID+`"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("dead_code status = %d: %s", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "unusedGenerated") {
+		t.Errorf("dead code should skip generated files by default: %s", w.Body.String())
+	}
+
+	for _, def := range NewToolRegistry().GetTools() {
+		_, has := def.InputSchema.Properties["include_generated"]
+		if want := def.Name == "summarize_package" || def.Name == "find_dead_code"; want && !has {
+			t.Errorf("%s should accept include_generated", def.Name)
+		}
+		if def.Name == "validate_change" && has {
+			t.Error("validate_change has no graph and should not accept include_generated")
+		}
+	}
+}
+
 func TestHandlers_HandleFindCircularDeps_GraphNotFound(t *testing.T) {
 	svc := NewService(DefaultServiceConfig())
 	router := setupTestRouter(svc)
@@ -391,10 +770,12 @@ func TestAgenticRoutes_AllEndpointsExist(t *testing.T) {
 		{"POST", "/v1/trace/reason/test_coverage"},
 		{"POST", "/v1/trace/reason/side_effects"},
 		{"POST", "/v1/trace/reason/suggest_refactor"},
+		{"POST", "/v1/trace/reason/impact"},
 		// Coordination
 		{"POST", "/v1/trace/coordinate/plan_changes"},
 		{"POST", "/v1/trace/coordinate/validate_plan"},
 		{"POST", "/v1/trace/coordinate/preview_changes"},
+		{"POST", "/v1/trace/coordinate/preview_rename"},
 		// Patterns
 		{"POST", "/v1/trace/patterns/detect"},
 		{"POST", "/v1/trace/patterns/code_smells"},
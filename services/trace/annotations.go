@@ -0,0 +1,462 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/annotations"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// maxUnresolvedAnnotationsReported bounds the selectors listed in the
+// error for file annotations that match no symbol.
+const maxUnresolvedAnnotationsReported = 5
+
+// SetAnnotationStore sets the store API annotations are persisted in.
+//
+// Description:
+//
+//	Without a store, annotations are kept in memory for the process
+//	lifetime. Must be called before the first graph is built: each
+//	project's annotations are loaded from the store once, when its first
+//	graph is built.
+//
+// Inputs:
+//
+//	store - The store. Nil keeps annotations in memory only.
+func (s *Service) SetAnnotationStore(store annotations.Store) {
+	if store == nil {
+		store = annotations.NewMemoryStore()
+	}
+	s.annotationStore = store
+}
+
+// projectAnnotations returns the annotation set of a project, loading its
+// API annotations from the store on first use.
+//
+// Outputs:
+//
+//	*annotations.Set - The set. Never nil.
+//	error - Non-nil if the store could not be read. The returned set is
+//	  then empty and not kept, so the next build tries the store again.
+func (s *Service) projectAnnotations(ctx context.Context, projectRoot string) (*annotations.Set, error) {
+	s.annotationsMu.Lock()
+	defer s.annotationsMu.Unlock()
+	if set, ok := s.annotationSets[projectRoot]; ok {
+		return set, nil
+	}
+	stored, err := s.annotationStore.List(ctx, projectRoot)
+	if err != nil {
+		return annotations.NewSet(nil), fmt.Errorf("loading annotations: %w", err)
+	}
+	set := annotations.NewSet(stored)
+	s.annotationSets[projectRoot] = set
+	return set, nil
+}
+
+// recordAnnotations attaches the project's annotations to g.
+//
+// Description:
+//
+//	API annotations are loaded from the store once per project. With
+//	readFile, the project's .trace/annotations.yaml is read and its
+//	entries, resolved against g, replace the file annotations of the
+//	project. A file that fails to parse is logged and reported, and the
+//	project keeps no file annotations; entries that match no symbol are
+//	skipped and reported.
+//
+// Inputs:
+//
+//	g - The graph, before it is shared for reads.
+//	projectRoot - The project, keying its annotations.
+//	readFile - False for graphs that do not describe the working tree.
+//
+// Outputs:
+//
+//	*annotations.Set - The set recorded on g. Never nil.
+//	error - What could not be loaded, for InitResponse.Errors.
+func (s *Service) recordAnnotations(ctx context.Context, g *graph.Graph, projectRoot string, readFile bool) (*annotations.Set, error) {
+	set, err := s.projectAnnotations(ctx, projectRoot)
+	var errs []error
+	if err != nil {
+		slog.Warn("Annotation store unavailable, API annotations not loaded",
+			telemetry.SensitiveLog("root", projectRoot),
+			telemetry.SensitiveLogError("error", err),
+		)
+		errs = append(errs, err)
+	}
+
+	if readFile {
+		entries, err := annotations.Load(projectRoot)
+		if err != nil {
+			slog.Warn("Ignoring project annotations file",
+				telemetry.SensitiveLog("root", projectRoot),
+				telemetry.SensitiveLogError("error", err),
+			)
+			errs = append(errs, err)
+		}
+		var resolved []annotations.Annotation
+		var unresolved []string
+		for _, e := range entries {
+			stableID, err := resolveAnnotationSymbol(g, e)
+			if err != nil {
+				unresolved = append(unresolved, e.Selector())
+				continue
+			}
+			resolved = append(resolved, annotations.Annotation{SymbolID: stableID, Key: e.Key, Value: e.Value})
+		}
+		set.ReplaceFile(resolved)
+		if len(entries) > 0 {
+			slog.Info("Loaded project annotations",
+				slog.Int("entries", len(entries)),
+				slog.Int("unresolved", len(unresolved)),
+			)
+		}
+		if len(unresolved) > 0 {
+			listed := unresolved
+			if len(listed) > maxUnresolvedAnnotationsReported {
+				listed = listed[:maxUnresolvedAnnotationsReported]
+			}
+			errs = append(errs, fmt.Errorf("%s: %d entries match no symbol: %s",
+				annotations.DefaultFile, len(unresolved), strings.Join(listed, ", ")))
+		}
+	}
+
+	graph.RecordAnnotations(g, set)
+	return set, errors.Join(errs...)
+}
+
+// resolveAnnotationSymbol returns the stable ID of the symbol an entry
+// selects.
+//
+// Description:
+//
+//	SymbolID may be any ID form the graph resolves. File and Name select
+//	the symbols of the file whose qualified name (the part of the stable
+//	ID between "::" and "@", e.g. "Server.Handle") equals Name or, if
+//	none does, whose plain name does.
+//
+// Outputs:
+//
+//	string - The stable ID.
+//	error - ErrAnnotationSymbolNotFound, or annotations.ErrInvalidAnnotation
+//	  if Name matches several symbols.
+func resolveAnnotationSymbol(g *graph.Graph, e annotations.Entry) (string, error) {
+	if e.SymbolID != "" {
+		stableID, ok := g.StableIDFor(e.SymbolID)
+		if !ok {
+			return "", fmt.Errorf("%w: %s", ErrAnnotationSymbolNotFound, e.SymbolID)
+		}
+		return stableID, nil
+	}
+
+	var qualified, named []string
+	seen := make(map[string]bool)
+	for _, node := range g.SymbolsInFile(path.Clean(e.File)) {
+		sym := node.Symbol
+		if sym == nil || sym.StableID == "" || seen[sym.StableID] {
+			continue
+		}
+		switch {
+		case qualifiedSymbolName(sym.StableID) == e.Name:
+			qualified = append(qualified, sym.StableID)
+		case sym.Name == e.Name:
+			named = append(named, sym.StableID)
+		default:
+			continue
+		}
+		seen[sym.StableID] = true
+	}
+	matches := qualified
+	if len(matches) == 0 {
+		matches = named
+	}
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("%w: no %s in %s", ErrAnnotationSymbolNotFound, e.Name, e.File)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf("%w: %s matches %d symbols in %s, use symbol_id: %s",
+			annotations.ErrInvalidAnnotation, e.Name, len(matches), e.File, strings.Join(matches, ", "))
+	}
+}
+
+// qualifiedSymbolName returns the scope-qualified name of a stable ID:
+// "pkg/a.go::Server.Handle@1a2b3c4d" yields "Server.Handle".
+func qualifiedSymbolName(stableID string) string {
+	_, rest, ok := strings.Cut(stableID, "::")
+	if !ok {
+		return ""
+	}
+	if at := strings.LastIndexByte(rest, '@'); at >= 0 {
+		rest = rest[:at]
+	}
+	return rest
+}
+
+// graphAnnotations returns a graph and its annotation set.
+//
+// Outputs:
+//
+//	*CachedGraph - The graph.
+//	error - The GetGraph error, or ErrAnnotationsUnavailable.
+func (s *Service) graphAnnotations(graphID string) (*CachedGraph, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	if cached.Annotations == nil {
+		return nil, fmt.Errorf("%w: graph %s", ErrAnnotationsUnavailable, graphID)
+	}
+	return cached, nil
+}
+
+// PutAnnotations adds or replaces API annotations on the symbols of a
+// graph's project.
+//
+// Description:
+//
+//	Every entry is validated and resolved before any is written, so a bad
+//	entry writes nothing. Entries are persisted by stable ID and apply to
+//	every graph of the project, now and after rebuilds. An API annotation
+//	overrides a file annotation with the same symbol and key; the file
+//	value is returned as its Shadows.
+//
+// Inputs:
+//
+//	graphID - The graph used to resolve selectors.
+//	entries - The annotations to write.
+//
+// Outputs:
+//
+//	[]SymbolAnnotation - The effective annotations written, in entry order.
+//	error - annotations.ErrInvalidAnnotation or ErrAnnotationSymbolNotFound
+//	  (wrapped, naming the entry index), a GetGraph error,
+//	  ErrAnnotationsUnavailable, or a store error.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) PutAnnotations(ctx context.Context, graphID string, entries []annotations.Entry) ([]SymbolAnnotation, error) {
+	cached, err := s.graphAnnotations(graphID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	pending := make([]annotations.Annotation, 0, len(entries))
+	for i, e := range entries {
+		if err := e.Validate(); err != nil {
+			return nil, fmt.Errorf("annotations[%d]: %w", i, err)
+		}
+		stableID, err := resolveAnnotationSymbol(cached.Graph, e)
+		if err != nil {
+			return nil, fmt.Errorf("annotations[%d]: %w", i, err)
+		}
+		pending = append(pending, annotations.Annotation{
+			SymbolID:       stableID,
+			Key:            e.Key,
+			Value:          e.Value,
+			Source:         annotations.SourceAPI,
+			UpdatedAtMilli: now,
+		})
+	}
+
+	written := make([]SymbolAnnotation, 0, len(pending))
+	err = s.updateAnnotations(ctx, cached.Annotations, func() error {
+		for _, a := range pending {
+			if err := s.annotationStore.Put(ctx, cached.ProjectRoot, a); err != nil {
+				return err
+			}
+			written = append(written, cached.symbolAnnotation(cached.Annotations.Put(a)))
+		}
+		return nil
+	})
+	return written, err
+}
+
+// ListAnnotations returns the effective annotations of a graph's project.
+//
+// Inputs:
+//
+//	graphID - The graph.
+//	symbolID - Restricts the list to one symbol, in any ID form. Empty
+//	  lists every annotated symbol, including those the graph no longer
+//	  has (Attached false).
+//	key - Restricts the list to one key. Empty lists every key.
+//
+// Outputs:
+//
+//	[]SymbolAnnotation - Sorted by symbol ID and key.
+//	error - A GetGraph error or ErrAnnotationsUnavailable.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ListAnnotations(graphID, symbolID, key string) ([]SymbolAnnotation, error) {
+	cached, err := s.graphAnnotations(graphID)
+	if err != nil {
+		return nil, err
+	}
+	if symbolID != "" {
+		if stableID, ok := cached.Graph.StableIDFor(symbolID); ok {
+			symbolID = stableID
+		}
+	}
+	list := cached.Annotations.List(symbolID, key)
+	out := make([]SymbolAnnotation, 0, len(list))
+	for _, a := range list {
+		out = append(out, cached.symbolAnnotation(a))
+	}
+	return out, nil
+}
+
+// DeleteAnnotations deletes API annotations of a symbol.
+//
+// Description:
+//
+//	Deleting an API annotation that overrides a file annotation makes the
+//	file value effective again. File annotations cannot be deleted here;
+//	they change when .trace/annotations.yaml is edited and the project is
+//	initialized again.
+//
+// Inputs:
+//
+//	graphID - The graph.
+//	symbolID - The symbol, in any ID form; a stable ID the graph no longer
+//	  has is accepted as is.
+//	key - The key to delete. Empty deletes every API key of the symbol.
+//
+// Outputs:
+//
+//	int - The number of API annotations deleted.
+//	error - ErrAnnotationReadOnly if the matching annotations all come
+//	  from the file, ErrAnnotationNotFound if none match, a GetGraph
+//	  error, ErrAnnotationsUnavailable, or a store error.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) DeleteAnnotations(ctx context.Context, graphID, symbolID, key string) (int, error) {
+	cached, err := s.graphAnnotations(graphID)
+	if err != nil {
+		return 0, err
+	}
+	if stableID, ok := cached.Graph.StableIDFor(symbolID); ok {
+		symbolID = stableID
+	}
+
+	var targets []annotations.Annotation
+	fileDefined := false
+	for _, a := range cached.Annotations.List(symbolID, key) {
+		if a.Source == annotations.SourceAPI {
+			targets = append(targets, a)
+		} else {
+			fileDefined = true
+		}
+	}
+	switch {
+	case len(targets) == 0 && fileDefined:
+		return 0, fmt.Errorf("%w: %s is set in %s", ErrAnnotationReadOnly, annotationLabel(symbolID, key), annotations.DefaultFile)
+	case len(targets) == 0:
+		return 0, fmt.Errorf("%w: %s", ErrAnnotationNotFound, annotationLabel(symbolID, key))
+	}
+
+	deleted := 0
+	err = s.updateAnnotations(ctx, cached.Annotations, func() error {
+		for _, a := range targets {
+			if err := s.annotationStore.Delete(ctx, cached.ProjectRoot, a.SymbolID, a.Key); err != nil {
+				return err
+			}
+			if cached.Annotations.Remove(a.SymbolID, a.Key) {
+				deleted++
+			}
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// annotationLabel names a symbol and optional key in error messages.
+func annotationLabel(symbolID, key string) string {
+	if key == "" {
+		return symbolID
+	}
+	return fmt.Sprintf("%s of %s", key, symbolID)
+}
+
+// updateAnnotations runs update, which changes set, and drops the tool
+// results and memoized responses cached for the graphs using set before
+// the change. Their generation includes the annotation hash, so later
+// requests are served under the new generation.
+func (s *Service) updateAnnotations(ctx context.Context, set *annotations.Set, update func() error) error {
+	s.mu.RLock()
+	var before []string
+	for _, cached := range s.graphs {
+		if cached.Annotations == set {
+			before = append(before, graphGeneration(cached))
+		}
+	}
+	s.mu.RUnlock()
+
+	err := update()
+
+	for _, generation := range before {
+		s.toolCache.Invalidate(context.WithoutCancel(ctx), generation)
+		if s.responseMemo != nil {
+			s.responseMemo.invalidate(generation)
+		}
+	}
+	return err
+}
+
+// symbolAnnotation describes a in terms of the graph.
+func (c *CachedGraph) symbolAnnotation(a annotations.Annotation) SymbolAnnotation {
+	out := SymbolAnnotation{Annotation: a}
+	if node, ok := c.Graph.GetNodeByStableID(a.SymbolID); ok && node.Symbol != nil {
+		out.Attached = true
+		out.NodeID = node.ID
+		out.Name = node.Symbol.Name
+		out.FilePath = node.Symbol.FilePath
+	}
+	return out
+}
+
+// AnnotationsOf returns the annotations of a symbol in a graph.
+//
+// Outputs:
+//
+//	map[string]string - key → value, or nil if the symbol has none.
+//	error - Non-nil if the graph is not found.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) AnnotationsOf(graphID, symbolID string) (map[string]string, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	node, ok := cached.Graph.GetNode(cached.ResolveSymbolID(symbolID))
+	if !ok {
+		return nil, nil
+	}
+	return cached.Graph.AnnotationsOf(node.Symbol), nil
+}
+
+// annotationsOrEmpty returns annotations, or an empty map so that JSON
+// encodes "annotations": {} rather than null.
+func annotationsOrEmpty(annotations map[string]string) map[string]string {
+	if annotations == nil {
+		return map[string]string{}
+	}
+	return annotations
+}
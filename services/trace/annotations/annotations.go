@@ -0,0 +1,381 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package annotations provides organizational metadata attached to graph
+// symbols: key/value pairs such as deprecated=true, tier=critical or
+// migration_wave=3.
+//
+// Annotations are keyed by symbol stable ID (ast.Symbol.StableID), which
+// does not change when code moves to another line, so they re-attach to
+// the same symbols when the graph is rebuilt. They come from two sources:
+//
+//	file  the project's .trace/annotations.yaml, read on every init
+//	api   POST /v1/trace/annotations, persisted in a Store per project
+//
+// When both define the same key on the same symbol, the API value wins
+// and the file value is kept as the annotation's Shadows. Deleting the API
+// annotation makes the file value effective again. File annotations can
+// only be changed by editing the file.
+package annotations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// MaxValueLength bounds the length of an annotation value, in bytes.
+const MaxValueLength = 512
+
+var (
+	// ErrInvalidAnnotation is returned for an entry that cannot be stored.
+	ErrInvalidAnnotation = errors.New("invalid annotation")
+
+	// keyPattern is the form of an annotation key.
+	keyPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]{0,63}$`)
+)
+
+// Source says where an annotation was defined.
+type Source string
+
+// Annotation sources.
+const (
+	// SourceFile annotations come from the project's annotations file.
+	SourceFile Source = "file"
+
+	// SourceAPI annotations were added through the annotations API.
+	SourceAPI Source = "api"
+)
+
+// Annotation is one key/value pair on a symbol.
+type Annotation struct {
+	// SymbolID is the stable ID of the annotated symbol.
+	SymbolID string `json:"symbol_id"`
+
+	// Key names the annotation, e.g. "deprecated".
+	Key string `json:"key"`
+
+	// Value is the annotation value. Empty for a plain tag.
+	Value string `json:"value"`
+
+	// Source is where the annotation was defined.
+	Source Source `json:"source"`
+
+	// UpdatedAtMilli is when an API annotation was last written (Unix
+	// milliseconds UTC). Zero for file annotations.
+	UpdatedAtMilli int64 `json:"updated_at,omitempty"`
+
+	// Shadows is the file annotation with the same symbol and key that
+	// this API annotation overrides. Nil when there is none.
+	Shadows *Annotation `json:"shadows,omitempty"`
+}
+
+// Entry is an annotation as written in the annotations file or an API
+// request: a symbol selector, a key and a value.
+//
+// The symbol is selected either by SymbolID, in any form the graph
+// resolves (node ID or stable ID), or by File and Name. Name is the
+// symbol name, optionally qualified by its enclosing symbols as in its
+// stable ID (e.g. "Server.Handle").
+type Entry struct {
+	// SymbolID selects the symbol by ID.
+	SymbolID string `yaml:"symbol_id,omitempty" json:"symbol_id,omitempty"`
+
+	// File is the project-relative file of the symbol.
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+
+	// Name is the symbol name in File.
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+
+	// Key names the annotation.
+	Key string `yaml:"key" json:"key"`
+
+	// Value is the annotation value. May be empty.
+	Value string `yaml:"value" json:"value"`
+}
+
+// Validate checks the selector, key and value of an entry.
+//
+// Outputs:
+//
+//	error - ErrInvalidAnnotation (wrapped) describing the problem.
+func (e Entry) Validate() error {
+	switch {
+	case e.SymbolID != "" && (e.File != "" || e.Name != ""):
+		return fmt.Errorf("%w: give symbol_id or file and name, not both", ErrInvalidAnnotation)
+	case e.SymbolID == "" && (e.File == "" || e.Name == ""):
+		return fmt.Errorf("%w: symbol_id, or file and name, is required", ErrInvalidAnnotation)
+	case !keyPattern.MatchString(e.Key):
+		return fmt.Errorf("%w: key %q must be 1-64 letters, digits, '_', '.' or '-', starting with a letter", ErrInvalidAnnotation, e.Key)
+	case len(e.Value) > MaxValueLength:
+		return fmt.Errorf("%w: value of %s is %d bytes, at most %d", ErrInvalidAnnotation, e.Key, len(e.Value), MaxValueLength)
+	}
+	return nil
+}
+
+// Selector describes the entry's symbol selector for error messages.
+func (e Entry) Selector() string {
+	if e.SymbolID != "" {
+		return e.SymbolID
+	}
+	return e.File + ":" + e.Name
+}
+
+// Set holds the effective annotations of one project.
+//
+// Description:
+//
+//	File and API annotations are kept apart so that reloading the file
+//	never loses an API annotation and deleting an API annotation reveals
+//	the file value it shadowed. A Set is shared by every graph of the
+//	project and updated in place; readers always see a consistent state.
+//
+// Thread Safety: Safe for concurrent use.
+type Set struct {
+	mu   sync.RWMutex
+	api  map[string]map[string]Annotation
+	file map[string]map[string]Annotation
+	hash string
+}
+
+// NewSet returns a Set holding the given API annotations.
+func NewSet(api []Annotation) *Set {
+	s := &Set{
+		api:  make(map[string]map[string]Annotation),
+		file: make(map[string]map[string]Annotation),
+	}
+	for _, a := range api {
+		a.Source, a.Shadows = SourceAPI, nil
+		putAnnotation(s.api, a)
+	}
+	s.rehashLocked()
+	return s
+}
+
+// ReplaceFile replaces the file annotations.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Set) ReplaceFile(file []Annotation) {
+	byID := make(map[string]map[string]Annotation)
+	for _, a := range file {
+		a.Source, a.UpdatedAtMilli, a.Shadows = SourceFile, 0, nil
+		putAnnotation(byID, a)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.file = byID
+	s.rehashLocked()
+}
+
+// Put adds or replaces an API annotation.
+//
+// Outputs:
+//
+//	Annotation - The effective annotation, with the file annotation it
+//	  shadows if any.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Set) Put(a Annotation) Annotation {
+	a.Source, a.Shadows = SourceAPI, nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	putAnnotation(s.api, a)
+	s.rehashLocked()
+	return s.effectiveLocked(a.SymbolID, a.Key)
+}
+
+// Remove deletes an API annotation. File annotations are not affected.
+//
+// Outputs:
+//
+//	bool - True if the API annotation existed.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Set) Remove(symbolID, key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys, ok := s.api[symbolID]
+	if !ok {
+		return false
+	}
+	if _, ok := keys[key]; !ok {
+		return false
+	}
+	delete(keys, key)
+	if len(keys) == 0 {
+		delete(s.api, symbolID)
+	}
+	s.rehashLocked()
+	return true
+}
+
+// Of returns the effective annotations of a symbol as key → value.
+//
+// Outputs:
+//
+//	map[string]string - A new map, or nil if the symbol has none.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Set) Of(symbolID string) map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fileKeys, apiKeys := s.file[symbolID], s.api[symbolID]
+	if len(fileKeys) == 0 && len(apiKeys) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(fileKeys)+len(apiKeys))
+	for key, a := range fileKeys {
+		values[key] = a.Value
+	}
+	for key, a := range apiKeys {
+		values[key] = a.Value
+	}
+	return values
+}
+
+// SymbolIDs returns the stable IDs of the annotated symbols, sorted.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Set) SymbolIDs() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	seen := make(map[string]bool, len(s.api)+len(s.file))
+	for id := range s.file {
+		seen[id] = true
+	}
+	for id := range s.api {
+		seen[id] = true
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// List returns the effective annotations, sorted by symbol ID and key.
+//
+// Inputs:
+//
+//	symbolID - Restricts the list to one symbol. Empty lists every symbol.
+//	key - Restricts the list to one key. Empty lists every key.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Set) List(symbolID, key string) []Annotation {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	pairs := make(map[[2]string]bool)
+	collect := func(byID map[string]map[string]Annotation) {
+		for id, keys := range byID {
+			if symbolID != "" && id != symbolID {
+				continue
+			}
+			for k := range keys {
+				if key == "" || k == key {
+					pairs[[2]string{id, k}] = true
+				}
+			}
+		}
+	}
+	collect(s.file)
+	collect(s.api)
+
+	list := make([]Annotation, 0, len(pairs))
+	for pair := range pairs {
+		list = append(list, s.effectiveLocked(pair[0], pair[1]))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SymbolID != list[j].SymbolID {
+			return list[i].SymbolID < list[j].SymbolID
+		}
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+// Hash identifies the effective annotations: equal sets have equal hashes.
+//
+// Outputs:
+//
+//	string - Hex SHA-256, or "" when the set is empty.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Set) Hash() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.hash
+}
+
+// effectiveLocked returns the annotation in effect for a symbol and key.
+// The caller holds s.mu and the pair exists in s.api or s.file.
+func (s *Set) effectiveLocked(symbolID, key string) Annotation {
+	fileAnn, inFile := s.file[symbolID][key]
+	apiAnn, inAPI := s.api[symbolID][key]
+	if !inAPI {
+		return fileAnn
+	}
+	if inFile {
+		shadowed := fileAnn
+		apiAnn.Shadows = &shadowed
+	}
+	return apiAnn
+}
+
+// rehashLocked recomputes s.hash. The caller holds s.mu for writing.
+func (s *Set) rehashLocked() {
+	type triple struct{ id, key, value string }
+	var entries []triple
+	for id, keys := range s.file {
+		for key, a := range keys {
+			if _, overridden := s.api[id][key]; !overridden {
+				entries = append(entries, triple{id, key, a.Value})
+			}
+		}
+	}
+	for id, keys := range s.api {
+		for key, a := range keys {
+			entries = append(entries, triple{id, key, a.Value})
+		}
+	}
+	if len(entries) == 0 {
+		s.hash = ""
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].id != entries[j].id {
+			return entries[i].id < entries[j].id
+		}
+		return entries[i].key < entries[j].key
+	})
+	h := sha256.New()
+	for _, e := range entries {
+		h.Write([]byte(e.id + "\x00" + e.key + "\x00" + e.value + "\x00"))
+	}
+	s.hash = hex.EncodeToString(h.Sum(nil))
+}
+
+// putAnnotation stores a in byID, replacing any annotation with the same
+// symbol and key.
+func putAnnotation(byID map[string]map[string]Annotation, a Annotation) {
+	keys, ok := byID[a.SymbolID]
+	if !ok {
+		keys = make(map[string]Annotation)
+		byID[a.SymbolID] = keys
+	}
+	keys[a.Key] = a
+}
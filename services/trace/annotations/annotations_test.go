@@ -0,0 +1,174 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package annotations
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+)
+
+func TestSet_APIOverridesFile(t *testing.T) {
+	s := NewSet([]Annotation{{SymbolID: "a.go::F@1", Key: "tier", Value: "critical"}})
+	s.ReplaceFile([]Annotation{
+		{SymbolID: "a.go::F@1", Key: "deprecated", Value: "use G"},
+		{SymbolID: "a.go::F@1", Key: "tier", Value: "low"},
+	})
+
+	if got := s.Of("a.go::F@1"); got["tier"] != "critical" || got["deprecated"] != "use G" || len(got) != 2 {
+		t.Errorf("Of = %v, want tier=critical from the API and deprecated from the file", got)
+	}
+
+	list := s.List("a.go::F@1", "tier")
+	if len(list) != 1 {
+		t.Fatalf("List(tier) = %v, want one annotation", list)
+	}
+	if list[0].Source != SourceAPI || list[0].Shadows == nil || list[0].Shadows.Value != "low" || list[0].Shadows.Source != SourceFile {
+		t.Errorf("tier = %+v, want the API value shadowing file value low", list[0])
+	}
+
+	// Removing the API annotation reveals the file value.
+	hashBefore := s.Hash()
+	if !s.Remove("a.go::F@1", "tier") {
+		t.Fatal("Remove(tier) = false, want true")
+	}
+	if got := s.Of("a.go::F@1")["tier"]; got != "low" {
+		t.Errorf("tier after Remove = %q, want file value low", got)
+	}
+	if s.Hash() == hashBefore {
+		t.Error("Hash unchanged after Remove")
+	}
+
+	// File annotations cannot be removed.
+	if s.Remove("a.go::F@1", "deprecated") {
+		t.Error("Remove(deprecated) = true for a file annotation")
+	}
+}
+
+func TestSet_ReplaceFileKeepsAPI(t *testing.T) {
+	s := NewSet(nil)
+	if s.Hash() != "" {
+		t.Errorf("empty set Hash = %q, want empty", s.Hash())
+	}
+	s.Put(Annotation{SymbolID: "b.go::H@2", Key: "owner", Value: "payments"})
+	s.ReplaceFile([]Annotation{{SymbolID: "a.go::F@1", Key: "deprecated"}})
+	s.ReplaceFile(nil)
+
+	if got := s.SymbolIDs(); len(got) != 1 || got[0] != "b.go::H@2" {
+		t.Errorf("SymbolIDs = %v, want only the API-annotated symbol", got)
+	}
+	if s.Of("a.go::F@1") != nil {
+		t.Error("file annotation survived ReplaceFile(nil)")
+	}
+
+	other := NewSet([]Annotation{{SymbolID: "b.go::H@2", Key: "owner", Value: "payments"}})
+	if s.Hash() != other.Hash() {
+		t.Error("sets with equal annotations have different hashes")
+	}
+}
+
+func TestParse(t *testing.T) {
+	entries, err := Parse([]byte(`
+annotations:
+  - file: pkg/a.go
+    name: Server.Handle
+    key: deprecated
+    value: "use Serve"
+  - symbol_id: "pkg/a.go::F@1"
+    key: tier
+    value: critical
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name != "Server.Handle" || entries[1].Value != "critical" {
+		t.Errorf("entries = %+v", entries)
+	}
+
+	if entries, err := Parse(nil); err != nil || entries != nil {
+		t.Errorf("Parse(empty) = %v, %v; want no entries", entries, err)
+	}
+
+	for name, doc := range map[string]string{
+		"unknown field": "annotations:\n  - file: a.go\n    name: F\n    key: k\n    colour: red\n",
+		"no selector":   "annotations:\n  - key: k\n",
+		"both":          "annotations:\n  - symbol_id: x\n    file: a.go\n    name: F\n    key: k\n",
+		"bad key":       "annotations:\n  - symbol_id: x\n    key: 9lives\n",
+		"long value":    "annotations:\n  - symbol_id: x\n    key: k\n    value: " + strings.Repeat("v", MaxValueLength+1) + "\n",
+	} {
+		if _, err := Parse([]byte(doc)); !errors.Is(err, ErrInvalidAnnotation) {
+			t.Errorf("%s: err = %v, want ErrInvalidAnnotation", name, err)
+		}
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	root := t.TempDir()
+	if entries, err := Load(root); err != nil || entries != nil {
+		t.Errorf("Load without file = %v, %v; want nil, nil", entries, err)
+	}
+
+	if err := os.MkdirAll(filepath.Join(root, ".trace"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, DefaultFile), []byte("annotations: [\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(root); !errors.Is(err, ErrInvalidAnnotation) {
+		t.Errorf("Load(malformed) err = %v, want ErrInvalidAnnotation", err)
+	}
+}
+
+func TestBadgerStore(t *testing.T) {
+	db, err := badgerstore.OpenDB(badgerstore.InMemoryConfig())
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store := NewBadgerStore(db)
+	ctx := context.Background()
+
+	for _, a := range []Annotation{
+		{SymbolID: "a.go::F@1", Key: "tier", Value: "low", Source: SourceAPI},
+		{SymbolID: "a.go::F@1", Key: "tier", Value: "critical", Source: SourceAPI, UpdatedAtMilli: 7},
+		{SymbolID: "a.go::G@2", Key: "deprecated", Source: SourceAPI},
+	} {
+		if err := store.Put(ctx, "/repo", a); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	if err := store.Put(ctx, "/repo-other", Annotation{SymbolID: "x", Key: "k"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	list, err := store.List(ctx, "/repo")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || list[0].Value != "critical" || list[0].UpdatedAtMilli != 7 || list[1].Key != "deprecated" {
+		t.Errorf("List = %+v, want the replaced tier and deprecated", list)
+	}
+
+	if err := store.Delete(ctx, "/repo", "a.go::F@1", "tier"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "/repo", "a.go::F@1", "tier"); err != nil {
+		t.Errorf("Delete of a missing annotation: %v", err)
+	}
+	if list, _ := store.List(ctx, "/repo"); len(list) != 1 {
+		t.Errorf("List after Delete = %+v, want one annotation", list)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package annotations
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the annotations file, relative to the project root.
+const DefaultFile = ".trace/annotations.yaml"
+
+// MaxFileEntries bounds the number of entries in one annotations file.
+const MaxFileEntries = 10000
+
+// fileSpec is the document layout of the annotations file.
+type fileSpec struct {
+	Annotations []Entry `yaml:"annotations"`
+}
+
+// Parse reads and validates an annotations file.
+//
+// Description:
+//
+//	The document has a single key, annotations, listing Entries:
+//
+//	  annotations:
+//	    - file: pkg/billing/invoice.go
+//	      name: LegacyTotal
+//	      key: deprecated
+//	      value: "use Invoice.Total"
+//	    - symbol_id: "pkg/billing/invoice.go::Invoice.Total@3f2a9c1e"
+//	      key: tier
+//	      value: critical
+//
+//	Unknown keys are rejected. When an entry repeats the selector and key
+//	of an earlier one, the later value wins.
+//
+// Outputs:
+//
+//	[]Entry - The entries, in file order.
+//	error - ErrInvalidAnnotation (wrapped) naming the first bad entry.
+func Parse(data []byte) ([]Entry, error) {
+	var f fileSpec
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAnnotation, err)
+	}
+	if len(f.Annotations) > MaxFileEntries {
+		return nil, fmt.Errorf("%w: %d entries, at most %d", ErrInvalidAnnotation, len(f.Annotations), MaxFileEntries)
+	}
+	for i, e := range f.Annotations {
+		if err := e.Validate(); err != nil {
+			return nil, fmt.Errorf("entry #%d: %w", i+1, err)
+		}
+	}
+	return f.Annotations, nil
+}
+
+// Load reads the annotations file of a project. See Parse.
+//
+// Outputs:
+//
+//	[]Entry - The entries. Nil, with a nil error, when the project has no
+//	  annotations file.
+//	error - ErrInvalidAnnotation (wrapped), or a read error.
+func Load(projectRoot string) ([]Entry, error) {
+	data, err := os.ReadFile(filepath.Join(projectRoot, filepath.FromSlash(DefaultFile)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", DefaultFile, err)
+	}
+	entries, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", DefaultFile, err)
+	}
+	return entries, nil
+}
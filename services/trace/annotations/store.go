@@ -0,0 +1,191 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package annotations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	dgbadger "github.com/dgraph-io/badger/v4"
+)
+
+// annotationKeyPrefix namespaces annotation keys in a shared BadgerDB.
+const annotationKeyPrefix = "annot:"
+
+// Store persists API annotations per project.
+//
+// The project is identified by its root path. File annotations are not
+// stored: they are read from the project on every init.
+type Store interface {
+	// List returns the annotations of a project.
+	List(ctx context.Context, project string) ([]Annotation, error)
+
+	// Put stores an annotation, replacing one with the same symbol and key.
+	Put(ctx context.Context, project string, a Annotation) error
+
+	// Delete removes an annotation. Deleting a missing one is not an error.
+	Delete(ctx context.Context, project, symbolID, key string) error
+}
+
+// MemoryStore is a Store that keeps annotations in memory only.
+//
+// Thread Safety: Safe for concurrent use.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	projects map[string]map[string]map[string]Annotation
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{projects: make(map[string]map[string]map[string]Annotation)}
+}
+
+// List implements Store.
+func (s *MemoryStore) List(_ context.Context, project string) ([]Annotation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var list []Annotation
+	for _, keys := range s.projects[project] {
+		for _, a := range keys {
+			list = append(list, a)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SymbolID != list[j].SymbolID {
+			return list[i].SymbolID < list[j].SymbolID
+		}
+		return list[i].Key < list[j].Key
+	})
+	return list, nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(_ context.Context, project string, a Annotation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byID, ok := s.projects[project]
+	if !ok {
+		byID = make(map[string]map[string]Annotation)
+		s.projects[project] = byID
+	}
+	a.Shadows = nil
+	putAnnotation(byID, a)
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, project, symbolID, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if keys, ok := s.projects[project][symbolID]; ok {
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(s.projects[project], symbolID)
+		}
+	}
+	return nil
+}
+
+// BadgerStore is a Store backed by BadgerDB.
+//
+// Description:
+//
+//	Each annotation is one key, annot:{project}\x00{symbol ID}\x00{key},
+//	holding the JSON Annotation. Annotations do not expire. The DB is
+//	owned by the caller and must stay open while the store is in use.
+//
+// Thread Safety: Safe for concurrent use.
+type BadgerStore struct {
+	db *badgerstore.DB
+}
+
+// NewBadgerStore creates a store backed by db.
+//
+// Inputs:
+//
+//	db - Opened BadgerDB wrapper. Must not be nil.
+//
+// Outputs:
+//
+//	*BadgerStore - The store. Never nil.
+func NewBadgerStore(db *badgerstore.DB) *BadgerStore {
+	if db == nil {
+		panic("NewBadgerStore: db must not be nil")
+	}
+	return &BadgerStore{db: db}
+}
+
+// List implements Store.
+func (s *BadgerStore) List(ctx context.Context, project string) ([]Annotation, error) {
+	var list []Annotation
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		opts := dgbadger.DefaultIteratorOptions
+		opts.Prefix = projectPrefix(project)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			var a Annotation
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &a)
+			}); err != nil {
+				return err
+			}
+			list = append(list, a)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("annotation list: %w", err)
+	}
+	return list, nil
+}
+
+// Put implements Store.
+func (s *BadgerStore) Put(ctx context.Context, project string, a Annotation) error {
+	a.Shadows = nil
+	value, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("annotation encode: %w", err)
+	}
+	err = s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.Set(annotationKey(project, a.SymbolID, a.Key), value)
+	})
+	if err != nil {
+		return fmt.Errorf("annotation put: %w", err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *BadgerStore) Delete(ctx context.Context, project, symbolID, key string) error {
+	err := s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.Delete(annotationKey(project, symbolID, key))
+	})
+	if err != nil && !errors.Is(err, dgbadger.ErrKeyNotFound) {
+		return fmt.Errorf("annotation delete: %w", err)
+	}
+	return nil
+}
+
+// projectPrefix is the key prefix of a project's annotations.
+func projectPrefix(project string) []byte {
+	return []byte(annotationKeyPrefix + project + "\x00")
+}
+
+// annotationKey is the key of one annotation.
+func annotationKey(project, symbolID, key string) []byte {
+	return []byte(annotationKeyPrefix + project + "\x00" + symbolID + "\x00" + key)
+}
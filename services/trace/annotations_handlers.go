@@ -0,0 +1,159 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/annotations"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// HandlePutAnnotations adds or replaces symbol annotations.
+//
+// Description:
+//
+//	POST /v1/trace/annotations with a PutAnnotationsRequest. Annotations
+//	are stored per project by symbol stable ID, so they apply to every
+//	graph of the project and survive rebuilds. Nothing is written if any
+//	entry is invalid.
+//
+// Response:
+//
+//	200 OK: AnnotationsResponse with the effective annotations written
+//	400 Bad Request: Invalid body, too many entries, INVALID_ANNOTATION,
+//	  or graph not initialized
+//	404 Not Found: SYMBOL_NOT_FOUND if an entry's symbol is not in the graph
+//	500 Internal Server Error: The annotation store failed
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandlePutAnnotations(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandlePutAnnotations")
+
+	var req PutAnnotationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body: graph_id and annotations are required")
+		return
+	}
+	if len(req.Annotations) == 0 || len(req.Annotations) > MaxAnnotationsPerRequest {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest,
+			fmt.Sprintf("annotations must have 1 to %d entries, got %d", MaxAnnotationsPerRequest, len(req.Annotations)))
+		return
+	}
+
+	written, err := h.svc.PutAnnotations(c.Request.Context(), req.GraphID, req.Annotations)
+	if err != nil {
+		writeAnnotationError(c, logger, err)
+		return
+	}
+
+	logger.Info("Annotations written", "graph_id", req.GraphID, "count", len(written))
+	c.JSON(http.StatusOK, AnnotationsResponse{GraphID: req.GraphID, Annotations: written, Count: len(written)})
+}
+
+// HandleListAnnotations lists the effective annotations of a graph's
+// project.
+//
+// Description:
+//
+//	GET /v1/trace/annotations?graph_id=...[&symbol_id=...][&key=...].
+//	Annotations whose symbol is not in the graph are listed with
+//	attached false.
+//
+// Response:
+//
+//	200 OK: AnnotationsResponse
+//	400 Bad Request: graph_id missing, or graph not initialized
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleListAnnotations(c *gin.Context) {
+	logger := requestLogger(c).With("handler", "HandleListAnnotations")
+
+	graphID := c.Query("graph_id")
+	if graphID == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "graph_id is required")
+		return
+	}
+
+	list, err := h.svc.ListAnnotations(graphID, c.Query("symbol_id"), c.Query("key"))
+	if err != nil {
+		writeAnnotationError(c, logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, AnnotationsResponse{GraphID: graphID, Annotations: list, Count: len(list)})
+}
+
+// HandleDeleteAnnotations deletes API annotations of a symbol.
+//
+// Description:
+//
+//	DELETE /v1/trace/annotations?graph_id=...&symbol_id=...[&key=...].
+//	Without key, every API annotation of the symbol is deleted. A file
+//	annotation the deleted one overrode becomes effective again.
+//
+// Response:
+//
+//	200 OK: DeleteAnnotationsResponse
+//	400 Bad Request: graph_id or symbol_id missing, or graph not initialized
+//	404 Not Found: ANNOTATION_NOT_FOUND
+//	409 Conflict: ANNOTATION_READ_ONLY if only the annotations file
+//	  defines the annotation
+//	500 Internal Server Error: The annotation store failed
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleDeleteAnnotations(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDeleteAnnotations")
+
+	graphID, symbolID := c.Query("graph_id"), c.Query("symbol_id")
+	if graphID == "" || symbolID == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "graph_id and symbol_id are required")
+		return
+	}
+
+	deleted, err := h.svc.DeleteAnnotations(c.Request.Context(), graphID, symbolID, c.Query("key"))
+	if err != nil {
+		writeAnnotationError(c, logger, err)
+		return
+	}
+
+	logger.Info("Annotations deleted", "graph_id", graphID, telemetry.SensitiveLog("symbol_id", symbolID), "count", deleted)
+	c.JSON(http.StatusOK, DeleteAnnotationsResponse{Deleted: deleted})
+}
+
+// writeAnnotationError writes the problem response for an annotations
+// service error.
+func writeAnnotationError(c *gin.Context, logger *slog.Logger, err error) {
+	switch {
+	case isGraphStateError(err):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
+	case errors.Is(err, annotations.ErrInvalidAnnotation):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidAnnotation, err.Error())
+	case errors.Is(err, ErrAnnotationSymbolNotFound):
+		apierror.Write(c, http.StatusNotFound, apierror.CodeSymbolNotFound, err.Error())
+	case errors.Is(err, ErrAnnotationNotFound):
+		apierror.Write(c, http.StatusNotFound, apierror.CodeAnnotationNotFound, err.Error())
+	case errors.Is(err, ErrAnnotationReadOnly):
+		apierror.Write(c, http.StatusConflict, apierror.CodeAnnotationReadOnly, err.Error())
+	case errors.Is(err, ErrAnnotationsUnavailable):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+	default:
+		logger.Error("Annotation operation failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "annotation store: "+err.Error())
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/annotations"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/gin-gonic/gin"
+)
+
+const monorepoAnnotations = `annotations:
+  - file: services/payment/charge.go
+    name: Charge
+    key: deprecated
+    value: use Pay
+  - file: services/payment/charge.go
+    name: PaymentStore.Save
+    key: tier
+    value: low
+  - file: services/payment/charge.go
+    name: Missing
+    key: tier
+    value: low
+`
+
+// annotationRequest issues a request to the annotations endpoints and
+// decodes the response into out, if given.
+func annotationRequest(t *testing.T, router *gin.Engine, method string, query url.Values, body string, out any) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, "/v1/trace/annotations?"+query.Encode(), strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if out != nil && w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode %s: %v", w.Body.String(), err)
+		}
+	}
+	return w
+}
+
+// assertProblem checks the status and code of a problem response.
+func assertProblem(t *testing.T, w *httptest.ResponseRecorder, status int, code apierror.Code) {
+	t.Helper()
+	var problem apierror.Problem
+	_ = json.Unmarshal(w.Body.Bytes(), &problem)
+	if w.Code != status || problem.Code != code {
+		t.Errorf("status %d code %q, want %d %s: %s", w.Code, problem.Code, status, code, w.Body.String())
+	}
+}
+
+func TestAnnotations_FileAPIConflictAndDelete(t *testing.T) {
+	root := writeMonorepo(t)
+	if err := os.MkdirAll(filepath.Join(root, ".trace"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, annotations.DefaultFile), []byte(monorepoAnnotations), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := annotations.NewMemoryStore()
+	svc := NewService(DefaultServiceConfig())
+	svc.SetAnnotationStore(store)
+	resp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if !strings.Contains(strings.Join(resp.Errors, "\n"), "services/payment/charge.go:Missing") {
+		t.Errorf("init errors %v do not report the unresolved entry", resp.Errors)
+	}
+	router := setupTestRouter(svc)
+	graphQuery := url.Values{"graph_id": {resp.GraphID}}
+
+	// The file annotations are imported at init.
+	var listed AnnotationsResponse
+	if w := annotationRequest(t, router, http.MethodGet, graphQuery, "", &listed); w.Code != http.StatusOK {
+		t.Fatalf("GET: status %d: %s", w.Code, w.Body.String())
+	}
+	if listed.Count != 2 {
+		t.Fatalf("listed %d annotations, want 2: %+v", listed.Count, listed.Annotations)
+	}
+	var chargeID, saveID string
+	for _, a := range listed.Annotations {
+		if a.Source != annotations.SourceFile || !a.Attached {
+			t.Errorf("file annotation %+v: want source file, attached", a)
+		}
+		switch a.Name {
+		case "Charge":
+			chargeID = a.SymbolID
+		case "Save":
+			saveID = a.SymbolID
+		}
+	}
+
+	// An API value overrides the file value and reports it as shadowed.
+	var written AnnotationsResponse
+	w := annotationRequest(t, router, http.MethodPost, nil, `{"graph_id": "`+resp.GraphID+`", "annotations": [
+		{"file": "services/payment/charge.go", "name": "PaymentStore.Save", "key": "tier", "value": "critical"},
+		{"symbol_id": "`+chargeID+`", "key": "replacement", "value": "Pay"}]}`, &written)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST: status %d: %s", w.Code, w.Body.String())
+	}
+	tier := written.Annotations[0]
+	if tier.Value != "critical" || tier.Source != annotations.SourceAPI || tier.Shadows == nil || tier.Shadows.Value != "low" {
+		t.Errorf("written tier = %+v, want critical shadowing low", tier)
+	}
+
+	// Bad entries write nothing.
+	w = annotationRequest(t, router, http.MethodPost, nil, `{"graph_id": "`+resp.GraphID+`", "annotations": [
+		{"symbol_id": "`+chargeID+`", "key": "owner", "value": "payments"},
+		{"symbol_id": "`+chargeID+`", "key": "9lives"}]}`, nil)
+	assertProblem(t, w, http.StatusBadRequest, apierror.CodeInvalidAnnotation)
+	w = annotationRequest(t, router, http.MethodPost, nil, `{"graph_id": "`+resp.GraphID+`", "annotations": [
+		{"file": "services/payment/charge.go", "name": "Refund", "key": "owner"}]}`, nil)
+	assertProblem(t, w, http.StatusNotFound, apierror.CodeSymbolNotFound)
+	if got, _ := svc.ListAnnotations(resp.GraphID, chargeID, "owner"); len(got) != 0 {
+		t.Errorf("rejected request wrote %+v", got)
+	}
+
+	// Symbol lookups include the effective annotations.
+	if got, err := svc.AnnotationsOf(resp.GraphID, saveID); err != nil || got["tier"] != "critical" {
+		t.Errorf("AnnotationsOf(Save) = %v, %v; want tier critical", got, err)
+	}
+
+	// File annotations cannot be deleted through the API.
+	w = annotationRequest(t, router, http.MethodDelete, url.Values{"graph_id": {resp.GraphID}, "symbol_id": {chargeID}, "key": {"deprecated"}}, "", nil)
+	assertProblem(t, w, http.StatusConflict, apierror.CodeAnnotationReadOnly)
+
+	// Deleting the API value reveals the file value.
+	var deleted DeleteAnnotationsResponse
+	w = annotationRequest(t, router, http.MethodDelete, url.Values{"graph_id": {resp.GraphID}, "symbol_id": {saveID}, "key": {"tier"}}, "", &deleted)
+	if w.Code != http.StatusOK || deleted.Deleted != 1 {
+		t.Fatalf("DELETE tier: status %d, deleted %d: %s", w.Code, deleted.Deleted, w.Body.String())
+	}
+	var afterDelete AnnotationsResponse
+	annotationRequest(t, router, http.MethodGet, url.Values{"graph_id": {resp.GraphID}, "symbol_id": {saveID}, "key": {"tier"}}, "", &afterDelete)
+	if afterDelete.Count != 1 || afterDelete.Annotations[0].Value != "low" || afterDelete.Annotations[0].Source != annotations.SourceFile {
+		t.Errorf("tier after delete = %+v, want file value low", afterDelete.Annotations)
+	}
+	w = annotationRequest(t, router, http.MethodDelete, url.Values{"graph_id": {resp.GraphID}, "symbol_id": {saveID}, "key": {"owner"}}, "", nil)
+	assertProblem(t, w, http.StatusNotFound, apierror.CodeAnnotationNotFound)
+	w = annotationRequest(t, router, http.MethodDelete, url.Values{"graph_id": {resp.GraphID}}, "", nil)
+	assertProblem(t, w, http.StatusBadRequest, apierror.CodeMissingParameter)
+
+	// API annotations are persisted and re-attach in a new service.
+	restarted := NewService(DefaultServiceConfig())
+	restarted.SetAnnotationStore(store)
+	resp2, err := restarted.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init after restart: %v", err)
+	}
+	got, err := restarted.ListAnnotations(resp2.GraphID, chargeID, "")
+	if err != nil {
+		t.Fatalf("ListAnnotations: %v", err)
+	}
+	if len(got) != 2 || got[1].Key != "replacement" || got[1].Source != annotations.SourceAPI || !got[1].Attached {
+		t.Errorf("annotations of Charge after restart = %+v, want file deprecated and API replacement", got)
+	}
+}
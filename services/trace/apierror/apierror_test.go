@@ -0,0 +1,161 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package apierror
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func serve(t *testing.T, path string, ctxSpan trace.SpanContext, h gin.HandlerFunc) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+	router := gin.New()
+	router.GET(path, h)
+
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ctxSpan.IsValid() {
+		req = req.WithContext(trace.ContextWithSpanContext(req.Context(), ctxSpan))
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("response is not JSON: %v: %s", err, w.Body.String())
+	}
+	return w, body
+}
+
+func TestWrite(t *testing.T) {
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID})
+
+	w, body := serve(t, "/v1/trace/symbol", sc, func(c *gin.Context) {
+		Write(c, http.StatusNotFound, CodeSymbolNotFound, "symbol not found: x")
+	})
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != ContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, ContentType)
+	}
+	want := map[string]any{
+		"type":     CatalogPath + "#SYMBOL_NOT_FOUND",
+		"title":    "Symbol not found",
+		"status":   float64(http.StatusNotFound),
+		"detail":   "symbol not found: x",
+		"instance": "/v1/trace/symbol",
+		"code":     "SYMBOL_NOT_FOUND",
+		"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"error":    "symbol not found: x",
+	}
+	for k, v := range want {
+		if body[k] != v {
+			t.Errorf("%s = %v, want %v", k, body[k], v)
+		}
+	}
+	if _, ok := body["details"]; ok {
+		t.Error("details should be omitted when empty")
+	}
+}
+
+func TestProblem_WithDetailsAndTraceID(t *testing.T) {
+	_, body := serve(t, "/x", trace.SpanContext{}, func(c *gin.Context) {
+		New(http.StatusBadRequest, CodeGraphNotInitialized, "graph not initialized").
+			WithDetails("Ensure /init was called first").
+			WithTraceID("explicit").
+			Write(c)
+	})
+	if body["details"] != "Ensure /init was called first" {
+		t.Errorf("details = %v", body["details"])
+	}
+	if body["trace_id"] != "explicit" {
+		t.Errorf("trace_id = %v, want explicit", body["trace_id"])
+	}
+}
+
+func TestProblem_NoSpan(t *testing.T) {
+	_, body := serve(t, "/x", trace.SpanContext{}, func(c *gin.Context) {
+		Write(c, http.StatusInternalServerError, CodeInternal, "boom")
+	})
+	if _, ok := body["trace_id"]; ok {
+		t.Errorf("trace_id should be omitted without a span, got %v", body["trace_id"])
+	}
+}
+
+func TestProblem_Abort(t *testing.T) {
+	router := gin.New()
+	reached := false
+	router.GET("/x", func(c *gin.Context) {
+		New(http.StatusServiceUnavailable, CodeWarmingUp, "loading").Abort(c)
+	}, func(c *gin.Context) {
+		reached = true
+	})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/x", nil))
+	if reached {
+		t.Error("handler after Abort should not run")
+	}
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+}
+
+func TestNew_UnknownCode(t *testing.T) {
+	p := New(http.StatusTeapot, Code("NOT_A_CODE"), "x")
+	if p.Title != "Error" {
+		t.Errorf("Title = %q, want Error", p.Title)
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	infos := Catalog()
+	if len(infos) != len(catalog) {
+		t.Fatalf("Catalog returned %d entries, want %d", len(infos), len(catalog))
+	}
+	for i, info := range infos {
+		if i > 0 && infos[i-1].Code >= info.Code {
+			t.Errorf("catalog not sorted at %d: %s >= %s", i, infos[i-1].Code, info.Code)
+		}
+		if info.Status < 400 || info.Status > 599 {
+			t.Errorf("%s: status %d is not an error status", info.Code, info.Status)
+		}
+		if info.Title == "" || info.Description == "" {
+			t.Errorf("%s: missing title or description", info.Code)
+		}
+		if got, ok := Lookup(info.Code); !ok || got != info {
+			t.Errorf("Lookup(%s) = %+v, %v", info.Code, got, ok)
+		}
+	}
+
+	for _, code := range []Code{
+		CodeGraphNotInitialized, CodeSymbolNotFound, CodeParseFailed,
+		CodeProviderUnavailable, CodeBudgetExceeded, CodeWarmingUp,
+	} {
+		if _, ok := Lookup(code); !ok {
+			t.Errorf("%s missing from catalog", code)
+		}
+	}
+	if _, ok := Lookup("NOT_A_CODE"); ok {
+		t.Error("Lookup should fail for unknown code")
+	}
+}
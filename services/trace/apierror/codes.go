@@ -0,0 +1,388 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package apierror
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Code is a stable, machine-readable error identifier.
+type Code string
+
+// Request validation errors.
+const (
+	// CodeInvalidRequest means the request body or parameters are malformed
+	// or fail validation.
+	CodeInvalidRequest Code = "INVALID_REQUEST"
+
+	// CodeMissingParameter means a required parameter is absent.
+	CodeMissingParameter Code = "MISSING_PARAMETER"
+
+	// CodeInvalidParameter means a parameter has an unusable value.
+	CodeInvalidParameter Code = "INVALID_PARAMETER"
+
+	// CodeEmptyQuery means the query text is empty.
+	CodeEmptyQuery Code = "EMPTY_QUERY"
+
+	// CodeQueryTooLong means the query text exceeds the maximum length.
+	CodeQueryTooLong Code = "QUERY_TOO_LONG"
+
+	// CodeInvalidPath means the project root is not an absolute path.
+	CodeInvalidPath Code = "INVALID_PATH"
+
+	// CodePathTraversal means a path contains ".." traversal sequences.
+	CodePathTraversal Code = "PATH_TRAVERSAL"
+
+	// CodeRootNotAllowed means a path is outside the server's allowed roots
+	// (TRACE_ALLOWED_ROOTS).
+	CodeRootNotAllowed Code = "ROOT_NOT_ALLOWED"
+
+	// CodeInvalidFilePath means a file path is empty or escapes the project.
+	CodeInvalidFilePath Code = "INVALID_FILE_PATH"
+
+	// CodeInvalidWorkspace means the workspace roots or manifest are unusable.
+	CodeInvalidWorkspace Code = "INVALID_WORKSPACE"
+
+	// CodeInvalidGitRef means git_ref does not resolve to a commit of the
+	// project's repository.
+	CodeInvalidGitRef Code = "INVALID_GIT_REF"
+
+	// CodeInvalidParseResult means an uploaded parse result is malformed,
+	// has an unsafe file path, or uses an unsupported language.
+	CodeInvalidParseResult Code = "INVALID_PARSE_RESULT"
+
+	// CodePayloadTooLarge means the request body or one of its records
+	// exceeds the size limits.
+	CodePayloadTooLarge Code = "PAYLOAD_TOO_LARGE"
+
+	// CodeInvalidAnnotation means an annotation has a bad selector, key or
+	// value.
+	CodeInvalidAnnotation Code = "INVALID_ANNOTATION"
+
+	// CodeInvalidFact means a fact statement is empty or too long.
+	CodeInvalidFact Code = "INVALID_FACT"
+)
+
+// Graph lifecycle errors.
+const (
+	// CodeGraphNotInitialized means no graph has been built for the project.
+	CodeGraphNotInitialized Code = "GRAPH_NOT_INITIALIZED"
+
+	// CodeGraphNotFound means the requested graph ID is not cached.
+	CodeGraphNotFound Code = "GRAPH_NOT_FOUND"
+
+	// CodeGraphExpired means the cached graph was evicted.
+	CodeGraphExpired Code = "GRAPH_EXPIRED"
+
+	// CodeNoGraphs means no graph is cached at all.
+	CodeNoGraphs Code = "NO_GRAPHS"
+
+	// CodeGraphNotInitializedHere means the session's graph was built on
+	// another instance; graphs are not shared between instances.
+	CodeGraphNotInitializedHere Code = "GRAPH_NOT_INITIALIZED_HERE"
+
+	// CodeProjectTooLarge means the project exceeds size limits.
+	CodeProjectTooLarge Code = "PROJECT_TOO_LARGE"
+
+	// CodeInitInProgress means another init is running for the project.
+	CodeInitInProgress Code = "INIT_IN_PROGRESS"
+
+	// CodeInitTimeout means graph initialization timed out.
+	CodeInitTimeout Code = "INIT_TIMEOUT"
+
+	// CodeInitCancelled means the build was cancelled before it finished.
+	CodeInitCancelled Code = "INIT_CANCELLED"
+
+	// CodeBuildNotFound means the build ID is unknown or was pruned.
+	CodeBuildNotFound Code = "BUILD_NOT_FOUND"
+
+	// CodeParseFailed means source files could not be parsed.
+	CodeParseFailed Code = "PARSE_FAILED"
+
+	// CodeCompactionInProgress means the graph is already being compacted.
+	CodeCompactionInProgress Code = "COMPACTION_IN_PROGRESS"
+
+	// CodeManifestUnavailable means the graph has no recorded file hashes.
+	CodeManifestUnavailable Code = "MANIFEST_UNAVAILABLE"
+
+	// CodeCursorExpired means a paging cursor was issued against a graph
+	// generation that has since been replaced.
+	CodeCursorExpired Code = "CURSOR_EXPIRED"
+)
+
+// Not-found errors for individual resources.
+const (
+	// CodeSymbolNotFound means the symbol does not exist in the graph.
+	CodeSymbolNotFound Code = "SYMBOL_NOT_FOUND"
+
+	// CodeFileNotFound means a referenced source file no longer exists.
+	CodeFileNotFound Code = "FILE_NOT_FOUND"
+
+	// CodeSessionNotFound means the agent session does not exist.
+	CodeSessionNotFound Code = "SESSION_NOT_FOUND"
+
+	// CodeMemoryNotFound means the memory does not exist.
+	CodeMemoryNotFound Code = "MEMORY_NOT_FOUND"
+
+	// CodePlanNotFound means the change plan does not exist or expired.
+	CodePlanNotFound Code = "PLAN_NOT_FOUND"
+
+	// CodeSnapshotNotFound means the graph snapshot does not exist.
+	CodeSnapshotNotFound Code = "SNAPSHOT_NOT_FOUND"
+
+	// CodeViewNotFound means the scoped graph view does not exist.
+	CodeViewNotFound Code = "VIEW_NOT_FOUND"
+
+	// CodeToolNotFound means no tool has the requested name.
+	CodeToolNotFound Code = "TOOL_NOT_FOUND"
+
+	// CodeAnnotationNotFound means no annotation matches the symbol and key.
+	CodeAnnotationNotFound Code = "ANNOTATION_NOT_FOUND"
+
+	// CodeFactNotFound means the project has no fact with the ID.
+	CodeFactNotFound Code = "FACT_NOT_FOUND"
+)
+
+// Agent session errors.
+const (
+	// CodeInvalidSession means the session configuration is invalid.
+	CodeInvalidSession Code = "INVALID_SESSION"
+
+	// CodeSessionInProgress means another operation holds the session.
+	CodeSessionInProgress Code = "SESSION_IN_PROGRESS"
+
+	// CodeNotInClarifyState means the session is not awaiting clarification.
+	CodeNotInClarifyState Code = "NOT_IN_CLARIFY_STATE"
+
+	// CodeBudgetExceeded means a step or token budget was exhausted.
+	CodeBudgetExceeded Code = "BUDGET_EXCEEDED"
+
+	// CodeRunAborted means a queued run was aborted before it started.
+	CodeRunAborted Code = "RUN_ABORTED"
+
+	// CodeSessionNotResumable means an aborted session has no checkpoint
+	// to resume from.
+	CodeSessionNotResumable Code = "SESSION_NOT_RESUMABLE"
+)
+
+// Availability errors.
+const (
+	// CodeWarmingUp means the service is still loading its model.
+	CodeWarmingUp Code = "WARMING_UP"
+
+	// CodeProviderUnavailable means an upstream dependency (LLM provider,
+	// message bus) is unreachable.
+	CodeProviderUnavailable Code = "PROVIDER_UNAVAILABLE"
+
+	// CodeMemoryNotConfigured means the memory store is not configured.
+	CodeMemoryNotConfigured Code = "MEMORY_NOT_CONFIGURED"
+
+	// CodeSessionsNotAvailable means session persistence is not configured.
+	CodeSessionsNotAvailable Code = "SESSIONS_NOT_AVAILABLE"
+
+	// CodeSnapshotsNotAvailable means snapshot storage is not configured.
+	CodeSnapshotsNotAvailable Code = "SNAPSHOTS_NOT_AVAILABLE"
+
+	// CodeCacheNotAvailable means the graph has no query cache.
+	CodeCacheNotAvailable Code = "CACHE_NOT_AVAILABLE"
+
+	// CodeRoutingFeedbackNotAvailable means routing feedback is disabled.
+	CodeRoutingFeedbackNotAvailable Code = "ROUTING_FEEDBACK_NOT_AVAILABLE"
+
+	// CodeEgressAuditNotAvailable means the egress audit store is not configured.
+	CodeEgressAuditNotAvailable Code = "EGRESS_AUDIT_NOT_AVAILABLE"
+
+	// CodeUsageNotAvailable means the usage store is not configured.
+	CodeUsageNotAvailable Code = "USAGE_NOT_AVAILABLE"
+
+	// CodePlanEventsNotAvailable means no event emitter is configured for
+	// plan status events.
+	CodePlanEventsNotAvailable Code = "PLAN_EVENTS_NOT_AVAILABLE"
+
+	// CodeRoutingReloadNotAvailable means routing config reload is not
+	// configured on this server.
+	CodeRoutingReloadNotAvailable Code = "ROUTING_RELOAD_NOT_AVAILABLE"
+
+	// CodeRoutingNotAvailable means neither a tool pre-filter nor a tool
+	// router is available to explain routing.
+	CodeRoutingNotAvailable Code = "ROUTING_NOT_AVAILABLE"
+
+	// CodeRoutingReembedNotAvailable means there is no tool pre-filter, or
+	// no tool corpus has been routed yet, so there is nothing to re-embed.
+	CodeRoutingReembedNotAvailable Code = "ROUTING_REEMBED_NOT_AVAILABLE"
+
+	// CodeQueueFull means too many agent runs are waiting for a slot.
+	CodeQueueFull Code = "QUEUE_FULL"
+)
+
+// Configuration errors.
+const (
+	// CodeInvalidRoutingConfig means reloaded pre-filter rules or tool
+	// registry failed validation. The detail lists every problem; the
+	// running config is unchanged.
+	CodeInvalidRoutingConfig Code = "INVALID_ROUTING_CONFIG"
+)
+
+// Policy errors.
+const (
+	// CodeSafetyDenied means the safety policy denied the operation. The
+	// detail names the rule.
+	CodeSafetyDenied Code = "SAFETY_DENIED"
+
+	// CodeRecordingNotAllowed means a run asked to be recorded but fixture
+	// recording is not enabled on this server.
+	CodeRecordingNotAllowed Code = "RECORDING_NOT_ALLOWED"
+
+	// CodePriorityNotAllowed means a run asked for a priority its API key
+	// may not use.
+	CodePriorityNotAllowed Code = "PRIORITY_NOT_ALLOWED"
+)
+
+// Plan approval errors.
+const (
+	// CodePlanNotPending means an approve or reject targeted a plan that
+	// is not pending approval.
+	CodePlanNotPending Code = "PLAN_NOT_PENDING"
+
+	// CodePlanNotApproved means a plan was used before it was approved.
+	CodePlanNotApproved Code = "PLAN_NOT_APPROVED"
+
+	// CodePlanAwaitingApproval means the session has plans pending
+	// approval and cannot continue until they are decided.
+	CodePlanAwaitingApproval Code = "PLAN_AWAITING_APPROVAL"
+
+	// CodePlanConflict means a plan's target regions no longer match the
+	// source captured at plan time. The problem lists the stale hunks.
+	CodePlanConflict Code = "PLAN_CONFLICT"
+)
+
+// CodeAnnotationReadOnly means the annotation comes from the project's
+// annotations file and cannot be changed through the API.
+const CodeAnnotationReadOnly Code = "ANNOTATION_READ_ONLY"
+
+// CodeInternal means an unexpected server-side failure. The detail names
+// the operation that failed.
+const CodeInternal Code = "INTERNAL_ERROR"
+
+// CodeInfo describes one error code.
+type CodeInfo struct {
+	// Code is the machine-readable identifier.
+	Code Code `json:"code"`
+
+	// Status is the HTTP status normally returned with this code.
+	Status int `json:"status"`
+
+	// Title is the short, human-readable summary used as the problem title.
+	Title string `json:"title"`
+
+	// Description explains when the code is returned.
+	Description string `json:"description"`
+}
+
+// catalog is indexed by code. Kept in one place so Catalog, Lookup and the
+// problem title can never disagree.
+var catalog = map[Code]CodeInfo{
+	CodeInvalidRequest:              {CodeInvalidRequest, http.StatusBadRequest, "Invalid request", "The request body or parameters are malformed or fail validation."},
+	CodeMissingParameter:            {CodeMissingParameter, http.StatusBadRequest, "Missing parameter", "A required parameter is absent."},
+	CodeInvalidParameter:            {CodeInvalidParameter, http.StatusBadRequest, "Invalid parameter", "A parameter has an unusable value."},
+	CodeEmptyQuery:                  {CodeEmptyQuery, http.StatusBadRequest, "Empty query", "The query text is empty."},
+	CodeQueryTooLong:                {CodeQueryTooLong, http.StatusBadRequest, "Query too long", "The query text exceeds the maximum length."},
+	CodeInvalidPath:                 {CodeInvalidPath, http.StatusBadRequest, "Invalid path", "The project root is not an absolute path."},
+	CodePathTraversal:               {CodePathTraversal, http.StatusBadRequest, "Path traversal", "A path contains '..' traversal sequences."},
+	CodeRootNotAllowed:              {CodeRootNotAllowed, http.StatusForbidden, "Root not allowed", "The path, with symlinks resolved, is outside the server's allowed roots (TRACE_ALLOWED_ROOTS)."},
+	CodeInvalidFilePath:             {CodeInvalidFilePath, http.StatusBadRequest, "Invalid file path", "A file path is empty or escapes the project root."},
+	CodeInvalidWorkspace:            {CodeInvalidWorkspace, http.StatusBadRequest, "Invalid workspace", "The workspace roots overlap, share no parent directory, or the manifest cannot be read."},
+	CodeInvalidGitRef:               {CodeInvalidGitRef, http.StatusBadRequest, "Invalid git ref", "The project root is not in a git repository, or git_ref does not resolve to a commit in it."},
+	CodeInvalidParseResult:          {CodeInvalidParseResult, http.StatusBadRequest, "Invalid parse result", "An uploaded parse result is malformed, has an unsafe or duplicate file path, or names an unsupported language."},
+	CodePayloadTooLarge:             {CodePayloadTooLarge, http.StatusRequestEntityTooLarge, "Payload too large", "The request body, or one record in it, exceeds the configured size limit."},
+	CodeInvalidAnnotation:           {CodeInvalidAnnotation, http.StatusBadRequest, "Invalid annotation", "An annotation has no usable symbol selector, a malformed key, or a value that is too long."},
+	CodeInvalidFact:                 {CodeInvalidFact, http.StatusBadRequest, "Invalid fact", "A fact statement is empty or longer than the maximum length."},
+	CodeGraphNotInitialized:         {CodeGraphNotInitialized, http.StatusBadRequest, "Graph not initialized", "No graph has been built for the project; call /v1/trace/init first."},
+	CodeGraphNotFound:               {CodeGraphNotFound, http.StatusNotFound, "Graph not found", "The requested graph is not cached; call /v1/trace/init first."},
+	CodeGraphExpired:                {CodeGraphExpired, http.StatusBadRequest, "Graph expired", "The cached graph was evicted; call /v1/trace/init again."},
+	CodeNoGraphs:                    {CodeNoGraphs, http.StatusNotFound, "No graphs cached", "No graph has been built yet."},
+	CodeGraphNotInitializedHere:     {CodeGraphNotInitializedHere, http.StatusConflict, "Graph not initialized on this instance", "The session's graph lives on another instance; call /v1/trace/init for the project root on this instance, then retry."},
+	CodeProjectTooLarge:             {CodeProjectTooLarge, http.StatusBadRequest, "Project too large", "The project exceeds the configured size limits."},
+	CodeInitInProgress:              {CodeInitInProgress, http.StatusConflict, "Initialization in progress", "Another initialization is running for the project."},
+	CodeInitTimeout:                 {CodeInitTimeout, http.StatusGatewayTimeout, "Initialization timed out", "Building the graph exceeded the time limit."},
+	CodeInitCancelled:               {CodeInitCancelled, http.StatusConflict, "Initialization cancelled", "The build was cancelled; the previous graph, if any, is unchanged."},
+	CodeBuildNotFound:               {CodeBuildNotFound, http.StatusNotFound, "Build not found", "The build ID is unknown or its status has been pruned."},
+	CodeParseFailed:                 {CodeParseFailed, http.StatusUnprocessableEntity, "Parse failed", "Source files could not be parsed."},
+	CodeCompactionInProgress:        {CodeCompactionInProgress, http.StatusConflict, "Compaction in progress", "The graph is already being compacted; retry when it finishes."},
+	CodeManifestUnavailable:         {CodeManifestUnavailable, http.StatusConflict, "Manifest unavailable", "The graph was built without file content hashes (e.g. from a snapshot written before they were recorded); rebuild it with /v1/trace/init."},
+	CodeCursorExpired:               {CodeCursorExpired, http.StatusConflict, "Cursor expired", "The graph was rebuilt since the first page; repeat the query without a cursor."},
+	CodeSymbolNotFound:              {CodeSymbolNotFound, http.StatusNotFound, "Symbol not found", "The symbol does not exist in the graph."},
+	CodeFileNotFound:                {CodeFileNotFound, http.StatusNotFound, "File not found", "A referenced source file no longer exists."},
+	CodeSessionNotFound:             {CodeSessionNotFound, http.StatusNotFound, "Session not found", "The agent session does not exist."},
+	CodeMemoryNotFound:              {CodeMemoryNotFound, http.StatusNotFound, "Memory not found", "The memory does not exist."},
+	CodePlanNotFound:                {CodePlanNotFound, http.StatusNotFound, "Plan not found", "The change plan does not exist or has expired."},
+	CodeSnapshotNotFound:            {CodeSnapshotNotFound, http.StatusNotFound, "Snapshot not found", "The graph snapshot does not exist."},
+	CodeViewNotFound:                {CodeViewNotFound, http.StatusNotFound, "View not found", "The scoped graph view does not exist."},
+	CodeToolNotFound:                {CodeToolNotFound, http.StatusNotFound, "Tool not found", "No tool has the requested name."},
+	CodeAnnotationNotFound:          {CodeAnnotationNotFound, http.StatusNotFound, "Annotation not found", "No annotation matches the symbol and key."},
+	CodeFactNotFound:                {CodeFactNotFound, http.StatusNotFound, "Fact not found", "The project has no fact with the ID."},
+	CodeInvalidSession:              {CodeInvalidSession, http.StatusBadRequest, "Invalid session", "The agent session configuration is invalid."},
+	CodeSessionInProgress:           {CodeSessionInProgress, http.StatusConflict, "Session in progress", "Another operation is running on the session."},
+	CodeNotInClarifyState:           {CodeNotInClarifyState, http.StatusBadRequest, "Not awaiting clarification", "The session is not in the CLARIFY state."},
+	CodeBudgetExceeded:              {CodeBudgetExceeded, http.StatusUnprocessableEntity, "Budget exceeded", "The agent exhausted its step or token budget."},
+	CodeRunAborted:                  {CodeRunAborted, http.StatusConflict, "Run aborted", "The run was aborted while queued and never started."},
+	CodeSessionNotResumable:         {CodeSessionNotResumable, http.StatusConflict, "Session not resumable", "The session was aborted with no phase to resume from."},
+	CodeWarmingUp:                   {CodeWarmingUp, http.StatusServiceUnavailable, "Warming up", "The model is still loading; retry after the Retry-After interval."},
+	CodeProviderUnavailable:         {CodeProviderUnavailable, http.StatusServiceUnavailable, "Provider unavailable", "An upstream dependency (LLM provider, message bus) is unreachable."},
+	CodeMemoryNotConfigured:         {CodeMemoryNotConfigured, http.StatusServiceUnavailable, "Memory not configured", "The memory store is not configured."},
+	CodeSessionsNotAvailable:        {CodeSessionsNotAvailable, http.StatusServiceUnavailable, "Sessions not available", "Session persistence is not configured."},
+	CodeSnapshotsNotAvailable:       {CodeSnapshotsNotAvailable, http.StatusServiceUnavailable, "Snapshots not available", "Snapshot storage is not configured."},
+	CodeCacheNotAvailable:           {CodeCacheNotAvailable, http.StatusServiceUnavailable, "Cache not available", "The graph has no query cache."},
+	CodeRoutingFeedbackNotAvailable: {CodeRoutingFeedbackNotAvailable, http.StatusServiceUnavailable, "Routing feedback not available", "Routing feedback collection is disabled."},
+	CodeEgressAuditNotAvailable:     {CodeEgressAuditNotAvailable, http.StatusServiceUnavailable, "Egress audit not available", "The egress audit store is not configured."},
+	CodeUsageNotAvailable:           {CodeUsageNotAvailable, http.StatusServiceUnavailable, "Usage not available", "The usage store is not configured."},
+	CodeQueueFull:                   {CodeQueueFull, http.StatusTooManyRequests, "Queue full", "Too many agent runs are waiting; the problem reports queue_depth and estimated_wait_ms, and Retry-After is set."},
+	CodeSafetyDenied:                {CodeSafetyDenied, http.StatusForbidden, "Safety policy denied", "The safety policy denied the operation; the detail names the rule."},
+	CodeRecordingNotAllowed:         {CodeRecordingNotAllowed, http.StatusForbidden, "Recording not allowed", "Fixture recording is not enabled on this server (TRACE_ALLOW_RECORD)."},
+	CodePriorityNotAllowed:          {CodePriorityNotAllowed, http.StatusForbidden, "Priority not allowed", "The run's priority is not one the API key may use (TRACE_AGENT_RUN_PRIORITIES)."},
+	CodePlanEventsNotAvailable:      {CodePlanEventsNotAvailable, http.StatusServiceUnavailable, "Plan events not available", "No event emitter is configured for plan status events."},
+	CodeRoutingReloadNotAvailable:   {CodeRoutingReloadNotAvailable, http.StatusServiceUnavailable, "Routing reload not available", "Routing config reload is not configured on this server."},
+	CodeRoutingNotAvailable:         {CodeRoutingNotAvailable, http.StatusServiceUnavailable, "Routing not available", "Neither a tool pre-filter nor a tool router is available."},
+	CodeRoutingReembedNotAvailable:  {CodeRoutingReembedNotAvailable, http.StatusServiceUnavailable, "Routing re-embedding not available", "The tool pre-filter is disabled or has not routed any tools yet."},
+	CodeInvalidRoutingConfig:        {CodeInvalidRoutingConfig, http.StatusUnprocessableEntity, "Invalid routing config", "The reloaded pre-filter rules or tool registry failed validation; the detail lists every problem and the running config is kept."},
+	CodePlanNotPending:              {CodePlanNotPending, http.StatusConflict, "Plan not pending", "The change plan is not awaiting approval; the detail names its status."},
+	CodePlanNotApproved:             {CodePlanNotApproved, http.StatusConflict, "Plan not approved", "The change plan must be approved before the agent can act on it."},
+	CodePlanAwaitingApproval:        {CodePlanAwaitingApproval, http.StatusConflict, "Awaiting plan approval", "The session has change plans pending approval; approve or reject them first."},
+	CodePlanConflict:                {CodePlanConflict, http.StatusConflict, "Plan conflict", "The source changed since the plan was made; stale_hunks lists the changes that no longer apply. Nothing was written; create a new plan."},
+	CodeAnnotationReadOnly:          {CodeAnnotationReadOnly, http.StatusConflict, "Annotation read-only", "The annotation is defined in the project's .trace/annotations.yaml; edit the file and re-init to change it."},
+	CodeInternal:                    {CodeInternal, http.StatusInternalServerError, "Internal error", "An unexpected server-side failure; the detail names the failed operation."},
+}
+
+// Catalog returns every error code, sorted by code.
+//
+// Thread Safety: Safe for concurrent use. Returns a fresh slice.
+func Catalog() []CodeInfo {
+	infos := make([]CodeInfo, 0, len(catalog))
+	for _, info := range catalog {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Code < infos[j].Code
+	})
+	return infos
+}
+
+// Lookup returns the catalog entry for code.
+//
+// Outputs:
+//
+//	CodeInfo - The entry; zero-valued if unknown.
+//	bool - True if code is in the catalog.
+func Lookup(code Code) (CodeInfo, bool) {
+	info, ok := catalog[code]
+	return info, ok
+}
@@ -0,0 +1,36 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package apierror defines the error taxonomy of the trace HTTP API.
+//
+// Every error response is an RFC 7807 problem document served as
+// application/problem+json:
+//
+//	{
+//	  "type": "/v1/trace/errors#GRAPH_NOT_INITIALIZED",
+//	  "title": "Graph not initialized",
+//	  "status": 400,
+//	  "detail": "graph not initialized",
+//	  "instance": "/v1/trace/context",
+//	  "code": "GRAPH_NOT_INITIALIZED",
+//	  "trace_id": "4bf92f3577b34da6a3ce929d0e0e4736",
+//	  "error": "graph not initialized"
+//	}
+//
+// Code is the stable, machine-readable identifier; clients should switch on
+// it rather than on status or text. Catalog lists every code and is served
+// at GET /v1/trace/errors so client generators can build enums.
+//
+// # Compatibility
+//
+// Before this package, handlers returned {"error", "code", "details"}. The
+// "error" and "details" members are still populated for one release and
+// will then be removed; new clients should read "detail".
+package apierror
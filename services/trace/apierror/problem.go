@@ -0,0 +1,147 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package apierror
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContentType is the media type of problem responses (RFC 7807).
+const ContentType = "application/problem+json"
+
+// CatalogPath is the path of the error catalog endpoint. Problem types are
+// fragments of it, so a type URI resolves to the code's catalog entry.
+const CatalogPath = "/v1/trace/errors"
+
+// Problem is an RFC 7807 problem document with trace extensions.
+type Problem struct {
+	// Type identifies the problem type: CatalogPath + "#" + Code.
+	Type string `json:"type"`
+
+	// Title is the catalog title for Code.
+	Title string `json:"title"`
+
+	// Status is the HTTP status code.
+	Status int `json:"status"`
+
+	// Detail explains this occurrence.
+	Detail string `json:"detail,omitempty"`
+
+	// Instance is the request path that produced the problem.
+	Instance string `json:"instance,omitempty"`
+
+	// Code is the machine-readable error code.
+	Code Code `json:"code"`
+
+	// TraceID is the OpenTelemetry trace ID of the request, if sampled.
+	TraceID string `json:"trace_id,omitempty"`
+
+	// Error mirrors Detail for clients of the pre-problem format.
+	//
+	// Deprecated: Read Detail. Will be removed in the next release.
+	Error string `json:"error"`
+
+	// Details carries the legacy remediation hint.
+	//
+	// Deprecated: Will be removed in the next release.
+	Details string `json:"details,omitempty"`
+}
+
+// New creates a problem for code with the given status and detail.
+//
+// Description:
+//
+//	Status is explicit because a few endpoints return a code with a status
+//	other than the catalog default; pass info.Status from Lookup to use the
+//	default. Unknown codes get the generic title "Error".
+func New(status int, code Code, detail string) *Problem {
+	title := "Error"
+	if info, ok := catalog[code]; ok {
+		title = info.Title
+	}
+	return &Problem{
+		Type:   CatalogPath + "#" + string(code),
+		Title:  title,
+		Status: status,
+		Detail: detail,
+		Code:   code,
+		Error:  detail,
+	}
+}
+
+// WithDetails sets the legacy remediation hint and returns p.
+func (p *Problem) WithDetails(details string) *Problem {
+	p.Details = details
+	return p
+}
+
+// WithTraceID sets the trace ID explicitly and returns p. Write only fills
+// TraceID from the request context when it is empty.
+func (p *Problem) WithTraceID(traceID string) *Problem {
+	p.TraceID = traceID
+	return p
+}
+
+// Write renders p as application/problem+json.
+//
+// Description:
+//
+//	Fills Instance from the request path and TraceID from the active span
+//	in the request context when they are unset.
+//
+// Thread Safety: Not safe for concurrent use with the same gin.Context.
+func (p *Problem) Write(c *gin.Context) {
+	if c.Request != nil {
+		if p.Instance == "" && c.Request.URL != nil {
+			p.Instance = c.Request.URL.Path
+		}
+		if p.TraceID == "" {
+			p.TraceID = TraceID(c.Request.Context())
+		}
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		// Problem holds only strings and ints; this cannot happen.
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+	c.Data(p.Status, ContentType, body)
+}
+
+// Abort writes p and aborts the gin handler chain. For middleware.
+func (p *Problem) Abort(c *gin.Context) {
+	p.Write(c)
+	c.Abort()
+}
+
+// Write renders a problem for code with the given status and detail.
+//
+// Example:
+//
+//	apierror.Write(c, http.StatusNotFound, apierror.CodeSymbolNotFound, err.Error())
+func Write(c *gin.Context, status int, code Code, detail string) {
+	New(status, code, detail).Write(c)
+}
+
+// TraceID returns the hex trace ID of the span in ctx, or "" if none.
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
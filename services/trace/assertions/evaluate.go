@@ -0,0 +1,338 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package assertions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// MaxViolationsPerRule bounds the violations listed for one rule. Further
+// violations are counted but not listed.
+const MaxViolationsPerRule = 200
+
+// ErrGraphNotFrozen is returned by Evaluate for a graph still being built.
+var ErrGraphNotFrozen = errors.New("graph is not frozen")
+
+// SymbolRef identifies a symbol involved in a violation.
+type SymbolRef struct {
+	// ID is the graph node ID.
+	ID string `json:"id"`
+
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// Kind is the symbol kind.
+	Kind string `json:"kind"`
+
+	// FilePath and Line locate the symbol. Empty for external symbols.
+	FilePath string `json:"file_path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// EdgeRef identifies an edge involved in a violation.
+type EdgeRef struct {
+	// FromID and ToID are the edge's node IDs.
+	FromID string `json:"from_id"`
+	ToID   string `json:"to_id"`
+
+	// Type is the edge type, e.g. "imports".
+	Type string `json:"type"`
+
+	// FilePath and Line locate the edge in source, when known.
+	FilePath string `json:"file_path,omitempty"`
+	Line     int    `json:"line,omitempty"`
+}
+
+// Violation is one breach of a rule.
+type Violation struct {
+	// Rule is the name of the broken rule.
+	Rule string `json:"rule"`
+
+	// Type is the rule type.
+	Type RuleType `json:"type"`
+
+	// Message describes the breach.
+	Message string `json:"message"`
+
+	// Symbols are the symbols involved: the edge's endpoints for
+	// forbidden_edge, the implementer and the interface for
+	// required_implementation, the node for constraint.
+	Symbols []SymbolRef `json:"symbols"`
+
+	// Edge is the offending edge. Nil for constraint violations.
+	Edge *EdgeRef `json:"edge,omitempty"`
+}
+
+// RuleResult is the outcome of one rule.
+type RuleResult struct {
+	// Name and Type identify the rule.
+	Name string   `json:"name"`
+	Type RuleType `json:"type"`
+
+	// Description is the rule's description.
+	Description string `json:"description,omitempty"`
+
+	// Passed is true if the rule has no violations.
+	Passed bool `json:"passed"`
+
+	// Violations is the number of violations, including unlisted ones.
+	Violations int `json:"violations"`
+
+	// Truncated is true if more than MaxViolationsPerRule violations were
+	// found and only the first were listed.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// Report is the outcome of checking a rule set.
+type Report struct {
+	// Passed is true if no rule has violations.
+	Passed bool `json:"passed"`
+
+	// Rules are the per-rule outcomes, in rule order.
+	Rules []RuleResult `json:"rules"`
+
+	// Violations lists violations by rule, then file and line.
+	Violations []Violation `json:"violations"`
+
+	// ViolationCount is the total number of violations, including
+	// unlisted ones.
+	ViolationCount int `json:"violation_count"`
+}
+
+// Evaluate checks a rule set against a frozen graph.
+//
+// Description:
+//
+//	forbidden_edge rules scan the edges of their types; an edge whose
+//	source matches "from" and target matches "to" is a violation.
+//	required_implementation rules follow the implements edges into each
+//	node matching "interface"; an implementer not matching "require" is
+//	a violation. constraint rules check every node matching "select"
+//	against "require". Violations are sorted by file and line and at most
+//	MaxViolationsPerRule are listed per rule.
+//
+// Inputs:
+//
+//	ctx - Cancels long evaluations.
+//	g - The graph. Must be frozen.
+//	rs - The compiled rules.
+//
+// Outputs:
+//
+//	*Report - The outcome of every rule.
+//	error - ErrGraphNotFrozen, or the context's error if cancelled.
+//
+// Thread Safety: Safe for concurrent use on frozen graphs.
+func Evaluate(ctx context.Context, g *graph.Graph, rs *RuleSet) (*Report, error) {
+	if g == nil || !g.IsFrozen() {
+		return nil, ErrGraphNotFrozen
+	}
+	report := &Report{
+		Rules:      make([]RuleResult, 0, len(rs.rules)),
+		Violations: []Violation{},
+	}
+	for i := range rs.rules {
+		r := &rs.rules[i]
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var found []Violation
+		switch r.Type {
+		case RuleForbiddenEdge:
+			found = forbiddenEdges(g, r)
+		case RuleRequiredImplementation:
+			found = implementationViolations(g, r)
+		case RuleConstraint:
+			found = constraintViolations(g, r)
+		}
+		sortViolations(found)
+
+		result := RuleResult{
+			Name:        r.Name,
+			Type:        r.Type,
+			Description: r.Description,
+			Passed:      len(found) == 0,
+			Violations:  len(found),
+		}
+		if len(found) > MaxViolationsPerRule {
+			found = found[:MaxViolationsPerRule]
+			result.Truncated = true
+		}
+		report.Rules = append(report.Rules, result)
+		report.Violations = append(report.Violations, found...)
+		report.ViolationCount += result.Violations
+	}
+	report.Passed = report.ViolationCount == 0
+	return report, nil
+}
+
+// forbiddenEdges returns the edges breaking a forbidden_edge rule.
+func forbiddenEdges(g *graph.Graph, r *compiledRule) []Violation {
+	var edges []*graph.Edge
+	if len(r.edgeTypes) == 0 {
+		edges = g.Edges()
+	} else {
+		for _, t := range r.edgeTypes {
+			edges = append(edges, g.GetEdgesByType(t)...)
+		}
+	}
+
+	var found []Violation
+	for _, e := range edges {
+		from, ok := g.GetNode(e.FromID)
+		if !ok || !r.from.matches(from.Symbol) {
+			continue
+		}
+		to, ok := g.GetNode(e.ToID)
+		if !ok || !r.to.matches(to.Symbol) {
+			continue
+		}
+		found = append(found, Violation{
+			Rule:    r.Name,
+			Type:    r.Type,
+			Message: fmt.Sprintf("%s %s %s", describe(from.Symbol), verb(e.Type), describe(to.Symbol)),
+			Symbols: []SymbolRef{symbolRef(from.Symbol), symbolRef(to.Symbol)},
+			Edge:    edgeRef(e),
+		})
+	}
+	return found
+}
+
+// implementationViolations returns the implementers breaking a
+// required_implementation rule.
+func implementationViolations(g *graph.Graph, r *compiledRule) []Violation {
+	var found []Violation
+	for _, node := range g.Nodes() {
+		if !r.iface.matches(node.Symbol) {
+			continue
+		}
+		for _, e := range node.Incoming {
+			if e.Type != graph.EdgeTypeImplements {
+				continue
+			}
+			impl, ok := g.GetNode(e.FromID)
+			if !ok || impl.Symbol == nil || r.require.matches(impl.Symbol) {
+				continue
+			}
+			found = append(found, Violation{
+				Rule: r.Name,
+				Type: r.Type,
+				Message: fmt.Sprintf("%s implements %s but does not match %s",
+					describe(impl.Symbol), describe(node.Symbol), r.Require),
+				Symbols: []SymbolRef{symbolRef(impl.Symbol), symbolRef(node.Symbol)},
+				Edge:    edgeRef(e),
+			})
+		}
+	}
+	return found
+}
+
+// constraintViolations returns the nodes breaking a constraint rule.
+func constraintViolations(g *graph.Graph, r *compiledRule) []Violation {
+	var found []Violation
+	for _, node := range g.Nodes() {
+		if !r.sel.matches(node.Symbol) || r.require.matches(node.Symbol) {
+			continue
+		}
+		found = append(found, Violation{
+			Rule:    r.Name,
+			Type:    r.Type,
+			Message: fmt.Sprintf("%s does not match %s", describe(node.Symbol), r.Require),
+			Symbols: []SymbolRef{symbolRef(node.Symbol)},
+		})
+	}
+	return found
+}
+
+// sortViolations orders violations by the file and line of their first
+// symbol (or edge), then by symbol ID.
+func sortViolations(vs []Violation) {
+	key := func(v Violation) (string, int, string) {
+		if v.Edge != nil && v.Edge.FilePath != "" {
+			return v.Edge.FilePath, v.Edge.Line, v.Symbols[0].ID + "\x00" + v.Symbols[len(v.Symbols)-1].ID
+		}
+		s := v.Symbols[0]
+		return s.FilePath, s.Line, s.ID + "\x00" + v.Symbols[len(v.Symbols)-1].ID
+	}
+	sort.SliceStable(vs, func(i, j int) bool {
+		fi, li, ii := key(vs[i])
+		fj, lj, ij := key(vs[j])
+		if fi != fj {
+			return fi < fj
+		}
+		if li != lj {
+			return li < lj
+		}
+		return ii < ij
+	})
+}
+
+// symbolRef returns the reference of sym.
+func symbolRef(sym *ast.Symbol) SymbolRef {
+	return SymbolRef{
+		ID:       sym.ID,
+		Name:     sym.Name,
+		Kind:     sym.Kind.String(),
+		FilePath: sym.FilePath,
+		Line:     sym.StartLine,
+	}
+}
+
+// edgeRef returns the reference of e.
+func edgeRef(e *graph.Edge) *EdgeRef {
+	return &EdgeRef{
+		FromID:   e.FromID,
+		ToID:     e.ToID,
+		Type:     e.Type.String(),
+		FilePath: e.Location.FilePath,
+		Line:     e.Location.StartLine,
+	}
+}
+
+// describe names a symbol for a message, e.g. "function Charge
+// (services/payment/charge.go:46)" or "external example.com/db".
+func describe(sym *ast.Symbol) string {
+	if sym.FilePath == "" {
+		return fmt.Sprintf("%s %s", sym.Kind, sym.Name)
+	}
+	return fmt.Sprintf("%s %s (%s:%d)", sym.Kind, sym.Name, sym.FilePath, sym.StartLine)
+}
+
+// verb describes an edge type in a message.
+func verb(t graph.EdgeType) string {
+	switch t {
+	case graph.EdgeTypeCalls:
+		return "calls"
+	case graph.EdgeTypeImports:
+		return "imports"
+	case graph.EdgeTypeImplements:
+		return "implements"
+	case graph.EdgeTypeEmbeds:
+		return "embeds"
+	case graph.EdgeTypeReturns:
+		return "returns"
+	case graph.EdgeTypeReceives:
+		return "has receiver"
+	case graph.EdgeTypeParameters:
+		return "takes parameter"
+	case graph.EdgeTypeDefines:
+		return "defines"
+	case graph.EdgeTypeVariantOf:
+		return "is a variant of"
+	}
+	return "references"
+}
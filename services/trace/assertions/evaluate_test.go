@@ -0,0 +1,181 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package assertions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// newLayeredGraph returns a frozen graph of a small layered service:
+//
+//	pkg/api/handler.go     Serve calls db.Query and services.Place, and
+//	                       the api package imports the db package
+//	pkg/services/order.go  Place calls db.Query
+//	pkg/payments/stripe.go Stripe implements PaymentProvider
+//	pkg/legacy/paypal.go   Paypal implements PaymentProvider
+//	pkg/handlers/users.go  structs UsersHandler and userCache
+func newLayeredGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	g := graph.NewGraph("/project")
+	sym := func(id, name string, kind ast.SymbolKind, file string, line int, pkg string) *ast.Symbol {
+		return &ast.Symbol{ID: id, Name: name, Kind: kind, FilePath: file, StartLine: line, EndLine: line + 5, Package: pkg, Language: "go"}
+	}
+	symbols := []*ast.Symbol{
+		sym("pkg/api/handler.go:1:api", "api", ast.SymbolKindPackage, "pkg/api/handler.go", 1, "api"),
+		sym("pkg/api/handler.go:10:Serve", "Serve", ast.SymbolKindFunction, "pkg/api/handler.go", 10, "api"),
+		sym("pkg/services/order.go:5:Place", "Place", ast.SymbolKindFunction, "pkg/services/order.go", 5, "services"),
+		sym("pkg/internal/db/db.go:3:Query", "Query", ast.SymbolKindFunction, "pkg/internal/db/db.go", 3, "db"),
+		{ID: "external:example.com/shop/pkg/internal/db:example.com/shop/pkg/internal/db", Name: "example.com/shop/pkg/internal/db",
+			Kind: ast.SymbolKindExternal, Package: "example.com/shop/pkg/internal/db", Language: "external"},
+		sym("pkg/payments/provider.go:3:PaymentProvider", "PaymentProvider", ast.SymbolKindInterface, "pkg/payments/provider.go", 3, "payments"),
+		sym("pkg/payments/stripe.go:3:Stripe", "Stripe", ast.SymbolKindStruct, "pkg/payments/stripe.go", 3, "payments"),
+		sym("pkg/legacy/paypal.go:3:Paypal", "Paypal", ast.SymbolKindStruct, "pkg/legacy/paypal.go", 3, "legacy"),
+		sym("pkg/handlers/users.go:3:UsersHandler", "UsersHandler", ast.SymbolKindStruct, "pkg/handlers/users.go", 3, "handlers"),
+		sym("pkg/handlers/users.go:20:userCache", "userCache", ast.SymbolKindStruct, "pkg/handlers/users.go", 20, "handlers"),
+	}
+	for _, s := range symbols {
+		if _, err := g.AddNode(s); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	edge := func(from, to string, typ graph.EdgeType, file string, line int) {
+		if err := g.AddEdge(from, to, typ, ast.Location{FilePath: file, StartLine: line}); err != nil {
+			t.Fatalf("AddEdge: %v", err)
+		}
+	}
+	edge("pkg/api/handler.go:1:api", symbols[4].ID, graph.EdgeTypeImports, "pkg/api/handler.go", 4)
+	edge("pkg/api/handler.go:10:Serve", "pkg/internal/db/db.go:3:Query", graph.EdgeTypeCalls, "pkg/api/handler.go", 12)
+	edge("pkg/api/handler.go:10:Serve", "pkg/services/order.go:5:Place", graph.EdgeTypeCalls, "pkg/api/handler.go", 13)
+	edge("pkg/services/order.go:5:Place", "pkg/internal/db/db.go:3:Query", graph.EdgeTypeCalls, "pkg/services/order.go", 7)
+	edge("pkg/payments/stripe.go:3:Stripe", "pkg/payments/provider.go:3:PaymentProvider", graph.EdgeTypeImplements, "pkg/payments/stripe.go", 3)
+	edge("pkg/legacy/paypal.go:3:Paypal", "pkg/payments/provider.go:3:PaymentProvider", graph.EdgeTypeImplements, "pkg/legacy/paypal.go", 3)
+	g.Freeze()
+	return g
+}
+
+// evaluate compiles rules and evaluates them against g.
+func evaluate(t *testing.T, g *graph.Graph, rules ...Rule) *Report {
+	t.Helper()
+	rs, err := Compile(rules)
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	report, err := Evaluate(context.Background(), g, rs)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	return report
+}
+
+func TestEvaluate_ForbiddenEdge(t *testing.T) {
+	g := newLayeredGraph(t)
+
+	t.Run("imports of an external package", func(t *testing.T) {
+		report := evaluate(t, g, Rule{
+			Name: "api-no-db", Type: RuleForbiddenEdge,
+			From: &Selector{Path: "pkg/api/**"}, To: &Selector{Package: "**/pkg/internal/db"},
+			EdgeTypes: []string{"imports"},
+		})
+		if report.Passed || report.ViolationCount != 1 {
+			t.Fatalf("report = %+v, want one violation", report)
+		}
+		v := report.Violations[0]
+		if v.Edge == nil || v.Edge.Type != "imports" || v.Edge.Line != 4 || v.Symbols[0].Name != "api" {
+			t.Errorf("violation = %+v", v)
+		}
+	})
+
+	t.Run("calls into a directory", func(t *testing.T) {
+		report := evaluate(t, g, Rule{
+			Name: "db-only-from-services", Type: RuleForbiddenEdge,
+			From: &Selector{ExcludePath: "pkg/services/**"}, To: &Selector{Path: "pkg/internal/db/**"},
+			EdgeTypes: []string{"calls"},
+		})
+		if report.ViolationCount != 1 || report.Violations[0].Symbols[0].Name != "Serve" {
+			t.Fatalf("violations = %+v, want Serve -> Query only", report.Violations)
+		}
+		if msg := report.Violations[0].Message; !strings.Contains(msg, "function Serve (pkg/api/handler.go:10) calls function Query") {
+			t.Errorf("message = %q", msg)
+		}
+	})
+
+	t.Run("every edge type by default", func(t *testing.T) {
+		report := evaluate(t, g, Rule{
+			Name: "api-isolated", Type: RuleForbiddenEdge,
+			From: &Selector{Path: "pkg/api/**"}, To: &Selector{},
+		})
+		if report.ViolationCount != 3 {
+			t.Errorf("violations = %d, want 3 (import and two calls)", report.ViolationCount)
+		}
+		// Sorted by file and line of the edge.
+		if lines := []int{report.Violations[0].Edge.Line, report.Violations[1].Edge.Line, report.Violations[2].Edge.Line}; lines[0] != 4 || lines[1] != 12 || lines[2] != 13 {
+			t.Errorf("edge lines = %v, want [4 12 13]", lines)
+		}
+	})
+}
+
+func TestEvaluate_RequiredImplementation(t *testing.T) {
+	report := evaluate(t, newLayeredGraph(t), Rule{
+		Name: "payment-providers", Type: RuleRequiredImplementation,
+		Interface: &Selector{Name: "^PaymentProvider$"}, Require: &Selector{Path: "pkg/payments/**"},
+	})
+	if report.ViolationCount != 1 {
+		t.Fatalf("violations = %+v, want Paypal only", report.Violations)
+	}
+	v := report.Violations[0]
+	if v.Symbols[0].Name != "Paypal" || v.Symbols[1].Name != "PaymentProvider" || v.Edge.Type != "implements" {
+		t.Errorf("violation = %+v", v)
+	}
+}
+
+func TestEvaluate_Constraint(t *testing.T) {
+	g := newLayeredGraph(t)
+	report := evaluate(t, g,
+		Rule{
+			Name: "handler-names", Type: RuleConstraint,
+			Select: &Selector{Kind: []string{"struct"}, Path: "pkg/handlers/**"}, Require: &Selector{Name: "Handler$"},
+		},
+		Rule{
+			Name: "structs-in-pkg", Type: RuleConstraint,
+			Select: &Selector{Kind: []string{"struct"}}, Require: &Selector{Path: "pkg/**"},
+		},
+	)
+	if report.Passed || report.ViolationCount != 1 || report.Violations[0].Symbols[0].Name != "userCache" {
+		t.Fatalf("report = %+v, want userCache only", report)
+	}
+	if r := report.Rules; len(r) != 2 || r[0].Passed || r[0].Violations != 1 || !r[1].Passed {
+		t.Errorf("rule results = %+v", r)
+	}
+	if v := report.Violations[0]; v.Edge != nil || !strings.Contains(v.Message, "name=/Handler$/") {
+		t.Errorf("violation = %+v", v)
+	}
+}
+
+func TestEvaluate_Errors(t *testing.T) {
+	rs, err := Compile([]Rule{{Name: "a", Type: RuleConstraint, Select: &Selector{}, Require: &Selector{}}})
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if _, err := Evaluate(context.Background(), graph.NewGraph("/project"), rs); !errors.Is(err, ErrGraphNotFrozen) {
+		t.Errorf("unfrozen graph: err = %v, want ErrGraphNotFrozen", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Evaluate(ctx, newLayeredGraph(t), rs); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled: err = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,407 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package assertions checks architectural rules against a code graph.
+//
+// Rules are written in YAML (see Parse) and select graph nodes by kind,
+// file path glob, package glob and name regex. Three rule types exist:
+//
+//   - forbidden_edge: no edge of the given types may lead from a node
+//     matching "from" to a node matching "to" ("nothing in pkg/api may
+//     import pkg/internal/db").
+//   - required_implementation: every type implementing an interface
+//     matching "interface" must match "require" ("implementations of
+//     PaymentProvider live under pkg/payments").
+//   - constraint: every node matching "select" must match "require"
+//     ("structs in pkg/handlers are named *Handler").
+package assertions
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is the rules file looked up at the project root when no
+// other is given.
+const DefaultFile = ".trace-assertions.yaml"
+
+// MaxRules bounds the number of rules in a rule set.
+const MaxRules = 500
+
+// ErrInvalidRule is returned for a rule set that cannot be compiled.
+var ErrInvalidRule = errors.New("invalid assertion rule")
+
+// RuleType is the kind of check a rule makes.
+type RuleType string
+
+const (
+	// RuleForbiddenEdge forbids edges from "from" nodes to "to" nodes.
+	RuleForbiddenEdge RuleType = "forbidden_edge"
+
+	// RuleRequiredImplementation requires implementers of "interface"
+	// nodes to match "require".
+	RuleRequiredImplementation RuleType = "required_implementation"
+
+	// RuleConstraint requires "select" nodes to match "require".
+	RuleConstraint RuleType = "constraint"
+)
+
+// Selector selects graph nodes. A node matches when it satisfies every
+// field set; an empty selector matches every node.
+type Selector struct {
+	// Kind lists symbol kinds ("function", "method", "struct",
+	// "interface", ...). The node's kind must be one of them.
+	Kind []string `yaml:"kind,omitempty" json:"kind,omitempty"`
+
+	// Path is a glob on the node's file path, relative to the project
+	// root; ** crosses directories. Nodes without a file (external
+	// packages and symbols) never match a path.
+	Path string `yaml:"path,omitempty" json:"path,omitempty"`
+
+	// ExcludePath is a glob of file paths that do not match.
+	ExcludePath string `yaml:"exclude_path,omitempty" json:"exclude_path,omitempty"`
+
+	// Package is a glob on the node's package. For external packages
+	// reached by imports it is the import path, e.g.
+	// "**/pkg/internal/db".
+	Package string `yaml:"package,omitempty" json:"package,omitempty"`
+
+	// Name is a regular expression the node's name must match
+	// (unanchored; use ^ and $ for whole names).
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+}
+
+// String describes the selector, e.g. "kind=[struct] path=pkg/** name=/Handler$/".
+func (s Selector) String() string {
+	var parts []string
+	if len(s.Kind) > 0 {
+		parts = append(parts, "kind=["+strings.Join(s.Kind, ",")+"]")
+	}
+	if s.Path != "" {
+		parts = append(parts, "path="+s.Path)
+	}
+	if s.ExcludePath != "" {
+		parts = append(parts, "exclude_path="+s.ExcludePath)
+	}
+	if s.Package != "" {
+		parts = append(parts, "package="+s.Package)
+	}
+	if s.Name != "" {
+		parts = append(parts, "name=/"+s.Name+"/")
+	}
+	if len(parts) == 0 {
+		return "any"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Rule is one architectural rule.
+type Rule struct {
+	// Name identifies the rule in violations. Required and unique.
+	Name string `yaml:"name" json:"name"`
+
+	// Description explains the rule to whoever breaks it.
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+
+	// Type is the check the rule makes. Required.
+	Type RuleType `yaml:"type" json:"type"`
+
+	// From and To select the endpoints of forbidden edges.
+	From *Selector `yaml:"from,omitempty" json:"from,omitempty"`
+	To   *Selector `yaml:"to,omitempty" json:"to,omitempty"`
+
+	// EdgeTypes are the forbidden edge types ("calls", "imports",
+	// "references", ...). Empty forbids every type.
+	EdgeTypes []string `yaml:"edge_types,omitempty" json:"edge_types,omitempty"`
+
+	// Interface selects the interfaces of a required_implementation rule.
+	Interface *Selector `yaml:"interface,omitempty" json:"interface,omitempty"`
+
+	// Select selects the nodes of a constraint rule.
+	Select *Selector `yaml:"select,omitempty" json:"select,omitempty"`
+
+	// Require is what implementers (required_implementation) or selected
+	// nodes (constraint) must match.
+	Require *Selector `yaml:"require,omitempty" json:"require,omitempty"`
+}
+
+// File is the YAML document of a rules file.
+type File struct {
+	// Rules are the rules, checked in order.
+	Rules []Rule `yaml:"rules" json:"rules"`
+}
+
+// RuleSet is a compiled set of rules.
+//
+// Thread Safety: Safe for concurrent use; immutable after Compile.
+type RuleSet struct {
+	rules []compiledRule
+}
+
+// Len returns the number of rules.
+func (rs *RuleSet) Len() int {
+	return len(rs.rules)
+}
+
+// compiledRule is a rule with its selectors compiled.
+type compiledRule struct {
+	Rule
+	from, to, iface, sel, require *selector
+	// edgeTypes are the forbidden edge types; nil forbids all.
+	edgeTypes []graph.EdgeType
+}
+
+// selector is a compiled Selector.
+type selector struct {
+	kinds map[ast.SymbolKind]bool
+	paths *manifest.GlobMatcher
+	pkg   *manifest.GlobMatcher
+	name  *regexp.Regexp
+	// needsPath is true when Path is set: nodes without a file fail.
+	needsPath bool
+}
+
+// matches reports whether sym satisfies the selector.
+func (s *selector) matches(sym *ast.Symbol) bool {
+	if sym == nil {
+		return false
+	}
+	if s.kinds != nil && !s.kinds[sym.Kind] {
+		return false
+	}
+	if s.paths != nil {
+		if sym.FilePath == "" {
+			if s.needsPath {
+				return false
+			}
+		} else if !s.paths.Match(sym.FilePath) {
+			return false
+		}
+	}
+	if s.pkg != nil && (sym.Package == "" || !s.pkg.Match(sym.Package)) {
+		return false
+	}
+	if s.name != nil && !s.name.MatchString(sym.Name) {
+		return false
+	}
+	return true
+}
+
+// Parse parses and compiles a YAML rules file.
+//
+// Description:
+//
+//	The document has a top-level "rules" list. Unknown fields are errors,
+//	so a misspelled key fails loudly instead of weakening a rule:
+//
+//	  rules:
+//	    - name: api-no-db
+//	      type: forbidden_edge
+//	      from: {path: "pkg/api/**"}
+//	      to: {package: "**/pkg/internal/db"}
+//	      edge_types: [imports]
+//	    - name: payment-providers
+//	      type: required_implementation
+//	      interface: {name: "^PaymentProvider$"}
+//	      require: {path: "pkg/payments/**"}
+//	    - name: handler-names
+//	      type: constraint
+//	      select: {kind: [struct], path: "pkg/handlers/**"}
+//	      require: {name: "Handler$"}
+//
+// Inputs:
+//
+//	data - The YAML document.
+//
+// Outputs:
+//
+//	*RuleSet - The compiled rules.
+//	error - ErrInvalidRule (wrapped) naming the first bad rule, or a YAML error.
+func Parse(data []byte) (*RuleSet, error) {
+	var f File
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+	}
+	return Compile(f.Rules)
+}
+
+// Load reads and compiles a rules file. See Parse.
+func Load(path string) (*RuleSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading rules: %w", err)
+	}
+	rs, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return rs, nil
+}
+
+// Compile validates rules and compiles their selectors.
+//
+// Description:
+//
+//	Each rule needs a unique name and the selectors of its type:
+//	from and to for forbidden_edge, interface and require for
+//	required_implementation, select and require for constraint. Kinds
+//	and edge types must be known, globs and regular expressions valid.
+//
+// Outputs:
+//
+//	*RuleSet - The compiled rules.
+//	error - ErrInvalidRule (wrapped) naming the first bad rule.
+func Compile(rules []Rule) (*RuleSet, error) {
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("%w: no rules", ErrInvalidRule)
+	}
+	if len(rules) > MaxRules {
+		return nil, fmt.Errorf("%w: %d rules, at most %d", ErrInvalidRule, len(rules), MaxRules)
+	}
+	rs := &RuleSet{rules: make([]compiledRule, 0, len(rules))}
+	names := make(map[string]bool, len(rules))
+	for i, r := range rules {
+		label := r.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		cr, err := compileRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: rule %s: %v", ErrInvalidRule, label, err)
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("%w: rule %s: duplicate name", ErrInvalidRule, label)
+		}
+		names[r.Name] = true
+		rs.rules = append(rs.rules, cr)
+	}
+	return rs, nil
+}
+
+// compileRule checks one rule and compiles its selectors.
+func compileRule(r Rule) (compiledRule, error) {
+	cr := compiledRule{Rule: r}
+	if r.Name == "" {
+		return cr, errors.New("name is required")
+	}
+
+	// required lists the selectors the type needs; every other one must
+	// be absent so a rule never silently ignores a field.
+	type field struct {
+		name string
+		sel  *Selector
+		dst  **selector
+	}
+	fields := []field{
+		{"from", r.From, &cr.from},
+		{"to", r.To, &cr.to},
+		{"interface", r.Interface, &cr.iface},
+		{"select", r.Select, &cr.sel},
+		{"require", r.Require, &cr.require},
+	}
+	var required []string
+	switch r.Type {
+	case RuleForbiddenEdge:
+		required = []string{"from", "to"}
+	case RuleRequiredImplementation:
+		required = []string{"interface", "require"}
+	case RuleConstraint:
+		required = []string{"select", "require"}
+	case "":
+		return cr, errors.New("type is required")
+	default:
+		return cr, fmt.Errorf("unknown type %q (want %s, %s or %s)", r.Type, RuleForbiddenEdge, RuleRequiredImplementation, RuleConstraint)
+	}
+	for _, f := range fields {
+		want := false
+		for _, name := range required {
+			want = want || name == f.name
+		}
+		switch {
+		case want && f.sel == nil:
+			return cr, fmt.Errorf("%s rules need %q", r.Type, f.name)
+		case !want && f.sel != nil:
+			return cr, fmt.Errorf("%s rules do not use %q", r.Type, f.name)
+		case want:
+			compiled, err := compileSelector(*f.sel)
+			if err != nil {
+				return cr, fmt.Errorf("%s: %v", f.name, err)
+			}
+			*f.dst = compiled
+		}
+	}
+
+	if len(r.EdgeTypes) > 0 && r.Type != RuleForbiddenEdge {
+		return cr, fmt.Errorf("%s rules do not use %q", r.Type, "edge_types")
+	}
+	for _, name := range r.EdgeTypes {
+		t, ok := graph.ParseEdgeType(name)
+		if !ok {
+			return cr, fmt.Errorf("unknown edge type %q", name)
+		}
+		cr.edgeTypes = append(cr.edgeTypes, t)
+	}
+	return cr, nil
+}
+
+// compileSelector validates and compiles a selector.
+func compileSelector(s Selector) (*selector, error) {
+	out := &selector{needsPath: s.Path != ""}
+	if len(s.Kind) > 0 {
+		out.kinds = make(map[ast.SymbolKind]bool, len(s.Kind))
+		for _, name := range s.Kind {
+			kind := ast.ParseSymbolKind(name)
+			if kind == ast.SymbolKindUnknown && name != "unknown" {
+				return nil, fmt.Errorf("unknown kind %q", name)
+			}
+			out.kinds[kind] = true
+		}
+	}
+	for _, pattern := range []string{s.Path, s.ExcludePath, s.Package} {
+		if pattern == "" {
+			continue
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("glob %q: %v", pattern, err)
+		}
+	}
+	if s.Path != "" || s.ExcludePath != "" {
+		var include, exclude []string
+		if s.Path != "" {
+			include = []string{s.Path}
+		}
+		if s.ExcludePath != "" {
+			exclude = []string{s.ExcludePath}
+		}
+		out.paths = manifest.NewGlobMatcher(include, exclude)
+	}
+	if s.Package != "" {
+		out.pkg = manifest.NewGlobMatcher([]string{s.Package}, nil)
+	}
+	if s.Name != "" {
+		re, err := regexp.Compile(s.Name)
+		if err != nil {
+			return nil, fmt.Errorf("name %q: %v", s.Name, err)
+		}
+		out.name = re
+	}
+	return out, nil
+}
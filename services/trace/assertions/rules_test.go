@@ -0,0 +1,87 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package assertions
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	rs, err := Parse([]byte(`
+rules:
+  - name: api-no-db
+    description: The API layer goes through services.
+    type: forbidden_edge
+    from: {path: "pkg/api/**", exclude_path: "**/*_test.go"}
+    to: {package: "**/pkg/internal/db"}
+    edge_types: [imports, calls]
+  - name: payment-providers
+    type: required_implementation
+    interface: {name: "^PaymentProvider$", kind: [interface]}
+    require: {path: "pkg/payments/**"}
+  - name: handler-names
+    type: constraint
+    select: {kind: [struct], path: "pkg/handlers/**"}
+    require: {name: "Handler$"}
+`))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if rs.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", rs.Len())
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"no rules", "rules: []", "no rules"},
+		{"unknown field", "rules:\n  - {name: a, type: constraint, select: {}, require: {}, form: {}}", "form"},
+		{"missing name", "rules:\n  - {type: constraint, select: {}, require: {}}", "name is required"},
+		{"unknown type", "rules:\n  - {name: a, type: forbid}", `unknown type "forbid"`},
+		{"missing selector", "rules:\n  - {name: a, type: forbidden_edge, from: {}}", `need "to"`},
+		{"extra selector", "rules:\n  - {name: a, type: constraint, select: {}, require: {}, to: {}}", `do not use "to"`},
+		{"edge types on constraint", "rules:\n  - {name: a, type: constraint, select: {}, require: {}, edge_types: [calls]}", `do not use "edge_types"`},
+		{"unknown edge type", "rules:\n  - {name: a, type: forbidden_edge, from: {}, to: {}, edge_types: [uses]}", `unknown edge type "uses"`},
+		{"unknown kind", "rules:\n  - {name: a, type: constraint, select: {kind: [klass]}, require: {}}", `unknown kind "klass"`},
+		{"bad glob", "rules:\n  - {name: a, type: constraint, select: {path: \"[a\"}, require: {}}", "glob"},
+		{"bad regex", "rules:\n  - {name: a, type: constraint, select: {}, require: {name: \"(\"}}", "require: name"},
+		{"duplicate name", "rules:\n  - {name: a, type: constraint, select: {}, require: {}}\n  - {name: a, type: constraint, select: {}, require: {}}", "duplicate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml))
+			if !errors.Is(err, ErrInvalidRule) || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want ErrInvalidRule containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), DefaultFile)
+	if err := os.WriteFile(path, []byte("rules:\n  - {name: a, type: constraint, select: {}, require: {}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rs, err := Load(path); err != nil || rs.Len() != 1 {
+		t.Errorf("Load() = %v, %v", rs, err)
+	}
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load(missing) succeeded")
+	}
+}
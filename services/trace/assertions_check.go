@@ -0,0 +1,90 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/assertions"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
+)
+
+// CheckAssertions evaluates architectural assertion rules against a graph.
+//
+// Description:
+//
+//	Rules come inline in req.Rules or from req.RulesPath, a YAML file
+//	relative to the graph's project root (assertions.DefaultFile when
+//	neither is given). The path may not leave the project root. See
+//	assertions.Evaluate for how each rule type is checked.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	req - The graph and the rules.
+//
+// Outputs:
+//
+//	*AssertionsCheckResponse - The report. LatencyMs is set.
+//	error - assertions.ErrInvalidRule for bad rules; ErrInvalidAssertions
+//	  for an unreadable rules file, or both rules and rules_path; a
+//	  validation error if rules_path escapes the project root;
+//	  ErrGraphNotInitialized or ErrGraphExpired for an unknown graph; or
+//	  the context's error.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) CheckAssertions(ctx context.Context, req AssertionsCheckRequest) (*AssertionsCheckResponse, error) {
+	start := time.Now()
+	if len(req.Rules) > 0 && req.RulesPath != "" {
+		return nil, fmt.Errorf("%w: pass rules or rules_path, not both", ErrInvalidAssertions)
+	}
+
+	cached, err := s.GetGraph(req.GraphID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &AssertionsCheckResponse{GraphID: req.GraphID}
+	var rs *assertions.RuleSet
+	if len(req.Rules) > 0 {
+		rs, err = assertions.Compile(req.Rules)
+	} else {
+		resp.RulesPath = req.RulesPath
+		if resp.RulesPath == "" {
+			resp.RulesPath = assertions.DefaultFile
+		}
+		var absPath string
+		absPath, err = validation.ResolveWithin(cached.ProjectRoot, resp.RulesPath)
+		if validation.IsPathTraversal(err) {
+			return nil, err
+		}
+		if err == nil {
+			rs, err = assertions.Load(absPath)
+		}
+	}
+	switch {
+	case errors.Is(err, assertions.ErrInvalidRule):
+		return nil, err
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAssertions, err)
+	}
+
+	report, err := assertions.Evaluate(ctx, cached.Graph, rs)
+	if err != nil {
+		return nil, err
+	}
+	resp.Report = *report
+	resp.LatencyMs = time.Since(start).Milliseconds()
+	return resp, nil
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/assertions"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleCheckAssertions handles POST /v1/trace/assertions/check.
+//
+// Description:
+//
+//	Evaluates architectural assertion rules (forbidden edges, required
+//	implementation locations, naming and location constraints) against a
+//	graph. Rules are passed inline or read from a YAML file inside the
+//	project. A failing check is still 200 OK, with passed=false and the
+//	violating symbols and edges listed.
+//
+// Response:
+//
+//	200 OK: AssertionsCheckResponse
+//	400 Bad Request: Invalid body or rules, unreadable rules file,
+//	  rules_path outside the project, or unknown graph
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleCheckAssertions(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleCheckAssertions")
+
+	var req AssertionsCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body: graph_id is required")
+		return
+	}
+
+	resp, err := h.svc.CheckAssertions(c.Request.Context(), req)
+	switch {
+	case errors.Is(err, assertions.ErrInvalidRule), errors.Is(err, ErrInvalidAssertions):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+		return
+	case validation.IsPathTraversal(err):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidFilePath, "rules_path must be inside the project root")
+		return
+	case isGraphStateError(err):
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
+		return
+	case err != nil:
+		logger.Error("Assertion check failed", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to check assertions")
+		return
+	}
+
+	logger.Info("Checked assertions",
+		"graph_id", req.GraphID,
+		"rules", len(resp.Rules),
+		"passed", resp.Passed,
+		"violations", resp.ViolationCount,
+		"latency_ms", resp.LatencyMs)
+	c.JSON(http.StatusOK, resp)
+}
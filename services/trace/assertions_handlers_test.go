@@ -0,0 +1,97 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/assertions"
+)
+
+func TestHandlers_HandleCheckAssertions(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	root := writeMonorepo(t)
+	initResp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	graphID := initResp.GraphID
+
+	check := func(t *testing.T, body string) (*AssertionsCheckResponse, int, string) {
+		t.Helper()
+		w := postJSON(router, "/v1/trace/assertions/check", body)
+		var resp AssertionsCheckResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return &resp, w.Code, w.Body.String()
+	}
+
+	t.Run("inline rules report the offending edge", func(t *testing.T) {
+		resp, code, raw := check(t, fmt.Sprintf(`{"graph_id": %q, "rules": [
+			{"name": "orders-no-payment-calls", "type": "forbidden_edge",
+			 "from": {"path": "services/orders/**"}, "to": {"path": "services/payment/**"}, "edge_types": ["calls"]},
+			{"name": "stores-named-store", "type": "constraint",
+			 "select": {"kind": ["struct"]}, "require": {"name": "Store$"}}
+		]}`, graphID))
+		if code != http.StatusOK {
+			t.Fatalf("status = %d: %s", code, raw)
+		}
+		if resp.Passed || resp.ViolationCount != 1 || len(resp.Rules) != 2 || !resp.Rules[1].Passed {
+			t.Fatalf("response = %s", raw)
+		}
+		v := resp.Violations[0]
+		if v.Rule != "orders-no-payment-calls" || v.Edge == nil || v.Symbols[0].Name != "PlaceOrder" || v.Symbols[1].Name != "Charge" {
+			t.Errorf("violation = %+v", v)
+		}
+	})
+
+	t.Run("default rules file", func(t *testing.T) {
+		rules := "rules:\n  - {name: store-interfaces, type: required_implementation, interface: {name: ^Store$}, require: {path: \"services/**\"}}\n"
+		if err := os.WriteFile(filepath.Join(root, assertions.DefaultFile), []byte(rules), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		resp, code, raw := check(t, fmt.Sprintf(`{"graph_id": %q}`, graphID))
+		if code != http.StatusOK || !resp.Passed || resp.RulesPath != assertions.DefaultFile {
+			t.Errorf("status %d: %s", code, raw)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body string
+			code apierror.Code
+		}{
+			{"missing graph_id", `{}`, apierror.CodeInvalidRequest},
+			{"unknown graph", `{"graph_id": "nope", "rules_path": "x.yaml"}`, apierror.CodeGraphNotFound},
+			{"invalid rule", fmt.Sprintf(`{"graph_id": %q, "rules": [{"name": "a", "type": "forbid"}]}`, graphID), apierror.CodeInvalidParameter},
+			{"missing file", fmt.Sprintf(`{"graph_id": %q, "rules_path": "missing.yaml"}`, graphID), apierror.CodeInvalidParameter},
+			{"both sources", fmt.Sprintf(`{"graph_id": %q, "rules_path": "x.yaml", "rules": [{"name": "a"}]}`, graphID), apierror.CodeInvalidParameter},
+			{"outside the project", fmt.Sprintf(`{"graph_id": %q, "rules_path": "../rules.yaml"}`, graphID), apierror.CodeInvalidFilePath},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, code, raw := check(t, tt.body)
+				if code != http.StatusBadRequest || !strings.Contains(raw, string(tt.code)) {
+					t.Errorf("status %d body %s, want 400 %s", code, raw, tt.code)
+				}
+			})
+		}
+	})
+}
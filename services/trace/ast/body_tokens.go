@@ -0,0 +1,374 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// BodyToken is one token of a normalized function body.
+//
+// The vocabulary is shared by every language, so the same logic written in
+// Go, TypeScript, JavaScript or Python yields the same token stream. One
+// byte per token keeps the stream cheap to hold for every function in a
+// project.
+type BodyToken uint8
+
+// Normalized body tokens.
+const (
+	BodyTokenIdent BodyToken = iota + 1
+	BodyTokenString
+	BodyTokenNumber
+	BodyTokenNil
+	BodyTokenTrue
+	BodyTokenFalse
+	BodyTokenFunc
+	BodyTokenIf
+	BodyTokenElse
+	BodyTokenLoop
+	BodyTokenIn
+	BodyTokenReturn
+	BodyTokenThrow
+	BodyTokenTry
+	BodyTokenCatch
+	BodyTokenFinally
+	BodyTokenSwitch
+	BodyTokenCase
+	BodyTokenDefault
+	BodyTokenBreak
+	BodyTokenContinue
+	BodyTokenAwait
+	BodyTokenYield
+	BodyTokenDefer
+	BodyTokenEq
+	BodyTokenNe
+	BodyTokenLt
+	BodyTokenLe
+	BodyTokenGt
+	BodyTokenGe
+	BodyTokenAnd
+	BodyTokenOr
+	BodyTokenNot
+	BodyTokenAssign
+	BodyTokenOpAssign
+	BodyTokenAdd
+	BodyTokenSub
+	BodyTokenMul
+	BodyTokenDiv
+	BodyTokenMod
+	BodyTokenBitAnd
+	BodyTokenBitOr
+	BodyTokenBitXor
+	BodyTokenShl
+	BodyTokenShr
+	BodyTokenInc
+	BodyTokenDec
+	BodyTokenTernary
+	BodyTokenIndex
+)
+
+// MaxBodyTokens caps the normalized token stream of a single body.
+// Longer bodies are truncated; they are far past any useful clone size.
+const MaxBodyTokens = 4096
+
+var bodyTokenNames = [...]string{
+	BodyTokenIdent: "ID", BodyTokenString: "STR", BodyTokenNumber: "NUM",
+	BodyTokenNil: "NIL", BodyTokenTrue: "TRUE", BodyTokenFalse: "FALSE",
+	BodyTokenFunc: "FUNC", BodyTokenIf: "IF", BodyTokenElse: "ELSE",
+	BodyTokenLoop: "LOOP", BodyTokenIn: "IN", BodyTokenReturn: "RETURN",
+	BodyTokenThrow: "THROW", BodyTokenTry: "TRY", BodyTokenCatch: "CATCH",
+	BodyTokenFinally: "FINALLY", BodyTokenSwitch: "SWITCH", BodyTokenCase: "CASE",
+	BodyTokenDefault: "DEFAULT", BodyTokenBreak: "BREAK", BodyTokenContinue: "CONTINUE",
+	BodyTokenAwait: "AWAIT", BodyTokenYield: "YIELD", BodyTokenDefer: "DEFER",
+	BodyTokenEq: "==", BodyTokenNe: "!=", BodyTokenLt: "<", BodyTokenLe: "<=",
+	BodyTokenGt: ">", BodyTokenGe: ">=", BodyTokenAnd: "&&", BodyTokenOr: "||",
+	BodyTokenNot: "!", BodyTokenAssign: "=", BodyTokenOpAssign: "op=",
+	BodyTokenAdd: "+", BodyTokenSub: "-", BodyTokenMul: "*", BodyTokenDiv: "/",
+	BodyTokenMod: "%", BodyTokenBitAnd: "&", BodyTokenBitOr: "|", BodyTokenBitXor: "^",
+	BodyTokenShl: "<<", BodyTokenShr: ">>", BodyTokenInc: "++", BodyTokenDec: "--",
+	BodyTokenTernary: "?", BodyTokenIndex: "[",
+}
+
+// String returns the token's display form.
+func (t BodyToken) String() string {
+	if int(t) < len(bodyTokenNames) && bodyTokenNames[t] != "" {
+		return bodyTokenNames[t]
+	}
+	return "?"
+}
+
+// bodyIdentNodes are node types normalized to BodyTokenIdent.
+var bodyIdentNodes = map[string]bool{
+	"identifier":                            true,
+	"field_identifier":                      true,
+	"property_identifier":                   true,
+	"package_identifier":                    true,
+	"shorthand_property_identifier":         true,
+	"shorthand_property_identifier_pattern": true,
+	"private_property_identifier":           true,
+	"this":                                  true,
+	"super":                                 true,
+}
+
+// bodyLiteralNodes are node types normalized to a single literal token.
+// Their children (escape sequences, template substitutions) are not visited.
+var bodyLiteralNodes = map[string]BodyToken{
+	"interpreted_string_literal": BodyTokenString,
+	"raw_string_literal":         BodyTokenString,
+	"rune_literal":               BodyTokenString,
+	"string":                     BodyTokenString,
+	"concatenated_string":        BodyTokenString,
+	"template_string":            BodyTokenString,
+	"regex":                      BodyTokenString,
+	"int_literal":                BodyTokenNumber,
+	"float_literal":              BodyTokenNumber,
+	"imaginary_literal":          BodyTokenNumber,
+	"number":                     BodyTokenNumber,
+	"integer":                    BodyTokenNumber,
+	"float":                      BodyTokenNumber,
+	"nil":                        BodyTokenNil,
+	"null":                       BodyTokenNil,
+	"undefined":                  BodyTokenNil,
+	"none":                       BodyTokenNil,
+	"true":                       BodyTokenTrue,
+	"false":                      BodyTokenFalse,
+}
+
+// bodySkippedNodes are subtrees that carry no behavior: comments and type
+// syntax, which only some of the languages have.
+var bodySkippedNodes = map[string]bool{
+	"comment":              true,
+	"type_annotation":      true,
+	"type_arguments":       true,
+	"type_parameters":      true,
+	"type_identifier":      true,
+	"predefined_type":      true,
+	"qualified_type":       true,
+	"generic_type":         true,
+	"pointer_type":         true,
+	"slice_type":           true,
+	"array_type":           true,
+	"map_type":             true,
+	"channel_type":         true,
+	"function_type":        true,
+	"struct_type":          true,
+	"interface_type":       true,
+	"type":                 true,
+	"statement_identifier": true,
+}
+
+// bodyKeywordTokens maps anonymous keyword and operator leaves to tokens.
+// Leaves not listed (punctuation, declaration keywords such as let, const
+// and var, and new) are dropped.
+var bodyKeywordTokens = map[string]BodyToken{
+	"if": BodyTokenIf, "else": BodyTokenElse,
+	"for": BodyTokenLoop, "while": BodyTokenLoop,
+	"range": BodyTokenIn, "in": BodyTokenIn, "of": BodyTokenIn,
+	"return": BodyTokenReturn, "throw": BodyTokenThrow, "raise": BodyTokenThrow,
+	"try": BodyTokenTry, "catch": BodyTokenCatch, "except": BodyTokenCatch,
+	"finally": BodyTokenFinally,
+	"switch":  BodyTokenSwitch, "match": BodyTokenSwitch, "select": BodyTokenSwitch,
+	"case": BodyTokenCase, "default": BodyTokenDefault,
+	"break": BodyTokenBreak, "continue": BodyTokenContinue,
+	"func": BodyTokenFunc, "function": BodyTokenFunc, "def": BodyTokenFunc,
+	"lambda": BodyTokenFunc, "=>": BodyTokenFunc,
+	"await": BodyTokenAwait, "yield": BodyTokenYield, "defer": BodyTokenDefer,
+	"==": BodyTokenEq, "===": BodyTokenEq, "is": BodyTokenEq,
+	"!=": BodyTokenNe, "!==": BodyTokenNe,
+	"<": BodyTokenLt, "<=": BodyTokenLe, ">": BodyTokenGt, ">=": BodyTokenGe,
+	"&&": BodyTokenAnd, "and": BodyTokenAnd,
+	"||": BodyTokenOr, "or": BodyTokenOr, "??": BodyTokenOr,
+	"!": BodyTokenNot, "not": BodyTokenNot,
+	"=": BodyTokenAssign, ":=": BodyTokenAssign,
+	"+=": BodyTokenOpAssign, "-=": BodyTokenOpAssign, "*=": BodyTokenOpAssign,
+	"/=": BodyTokenOpAssign, "%=": BodyTokenOpAssign, "&=": BodyTokenOpAssign,
+	"|=": BodyTokenOpAssign, "^=": BodyTokenOpAssign, "<<=": BodyTokenOpAssign,
+	">>=": BodyTokenOpAssign, "**=": BodyTokenOpAssign, "&&=": BodyTokenOpAssign,
+	"||=": BodyTokenOpAssign, "??=": BodyTokenOpAssign,
+	"+": BodyTokenAdd, "-": BodyTokenSub, "*": BodyTokenMul, "/": BodyTokenDiv,
+	"%": BodyTokenMod, "&": BodyTokenBitAnd, "|": BodyTokenBitOr, "^": BodyTokenBitXor,
+	"<<": BodyTokenShl, ">>": BodyTokenShr, ">>>": BodyTokenShr,
+	"++": BodyTokenInc, "--": BodyTokenDec,
+	"?": BodyTokenTernary, "[": BodyTokenIndex,
+}
+
+// bodyFunctionNodes are the node types whose "body" field is tokenized.
+var bodyFunctionNodes = map[string]bool{
+	"function_declaration":           true, // Go, TypeScript, JavaScript
+	"method_declaration":             true, // Go
+	"func_literal":                   true, // Go
+	"function_definition":            true, // Python
+	"method_definition":              true, // TypeScript, JavaScript
+	"function":                       true, // JavaScript function expression
+	"function_expression":            true, // TypeScript function expression
+	"arrow_function":                 true, // TypeScript, JavaScript
+	"generator_function_declaration": true, // TypeScript, JavaScript
+}
+
+// NormalizeBodyTokens returns the normalized token stream of a body node.
+//
+// Description:
+//
+//	Walks the leaves of node in source order. Identifiers become ID, and a
+//	run of identifiers (a selector chain, a call and its plain arguments)
+//	collapses to one ID, because member access and call syntax differ
+//	between languages. String and numeric literals become STR and NUM,
+//	the nil-like and boolean literals get their own tokens, and keywords
+//	and operators map to a shared vocabulary (elif is ELSE IF, raise is
+//	THROW, === is ==). Comments, type syntax and punctuation are dropped.
+//
+// Inputs:
+//
+//	node - The body node. May be nil.
+//	content - The source the tree was parsed from.
+//
+// Outputs:
+//
+//	[]BodyToken - At most MaxBodyTokens tokens. Nil for a nil node.
+//
+// Thread Safety: Safe for concurrent use.
+func NormalizeBodyTokens(node *sitter.Node, content []byte) []BodyToken {
+	if node == nil {
+		return nil
+	}
+	tokens := make([]BodyToken, 0, 64)
+	appendBodyTokens(node, content, &tokens)
+	return tokens
+}
+
+// appendBodyTokens appends the tokens of node's subtree to tokens.
+func appendBodyTokens(node *sitter.Node, content []byte, tokens *[]BodyToken) {
+	if len(*tokens) >= MaxBodyTokens {
+		return
+	}
+	nodeType := node.Type()
+	if bodySkippedNodes[nodeType] {
+		return
+	}
+	if bodyIdentNodes[nodeType] {
+		if n := len(*tokens); n == 0 || (*tokens)[n-1] != BodyTokenIdent {
+			*tokens = append(*tokens, BodyTokenIdent)
+		}
+		return
+	}
+	if tok, ok := bodyLiteralNodes[nodeType]; ok {
+		*tokens = append(*tokens, tok)
+		return
+	}
+
+	count := int(node.ChildCount())
+	if count == 0 {
+		if node.IsNamed() {
+			return
+		}
+		switch nodeType {
+		case "elif":
+			*tokens = append(*tokens, BodyTokenElse, BodyTokenIf)
+		case "!":
+			// TypeScript's non-null assertion x! is not a negation.
+			if parent := node.Parent(); parent != nil && parent.Type() == "non_null_expression" {
+				return
+			}
+			*tokens = append(*tokens, BodyTokenNot)
+		default:
+			if tok, ok := bodyKeywordTokens[nodeType]; ok {
+				*tokens = append(*tokens, tok)
+			}
+		}
+		return
+	}
+	for i := 0; i < count; i++ {
+		if child := node.Child(i); child != nil {
+			appendBodyTokens(child, content, tokens)
+		}
+	}
+}
+
+// attachBodyTokens sets BodyTokens on the function and method symbols of a
+// parsed file.
+//
+// Description:
+//
+//	Finds every function-like node with a body and matches it to the
+//	symbols that start on the same line (or on its parent's line, which
+//	covers decorators and variable-assigned arrow functions), narrowed by
+//	name when the node has one. Called by parsers when
+//	ParseOptions.ExtractBodyTokens is set.
+//
+// Inputs:
+//
+//	root - The root node of the file.
+//	content - The source the tree was parsed from.
+//	symbols - The file's top-level symbols; children are searched too.
+//
+// Thread Safety: Not safe for concurrent use on the same symbols.
+func attachBodyTokens(root *sitter.Node, content []byte, symbols []*Symbol) {
+	byLine := make(map[int][]*Symbol)
+	var index func(syms []*Symbol)
+	index = func(syms []*Symbol) {
+		for _, sym := range syms {
+			if sym == nil {
+				continue
+			}
+			if sym.Kind == SymbolKindFunction || sym.Kind == SymbolKindMethod {
+				byLine[sym.StartLine] = append(byLine[sym.StartLine], sym)
+			}
+			index(sym.Children)
+		}
+	}
+	index(symbols)
+	if len(byLine) == 0 || root == nil {
+		return
+	}
+
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		if bodyFunctionNodes[node.Type()] {
+			if body := node.ChildByFieldName("body"); body != nil {
+				assignBodyTokens(node, body, content, byLine)
+			}
+		}
+		for i := 0; i < int(node.ChildCount()); i++ {
+			if child := node.Child(i); child != nil {
+				walk(child)
+			}
+		}
+	}
+	walk(root)
+}
+
+// assignBodyTokens tokenizes body and stores it on the symbols matching node.
+func assignBodyTokens(node, body *sitter.Node, content []byte, byLine map[int][]*Symbol) {
+	candidates := byLine[int(node.StartPoint().Row)+1]
+	if len(candidates) == 0 {
+		if parent := node.Parent(); parent != nil {
+			candidates = byLine[int(parent.StartPoint().Row)+1]
+		}
+	}
+	if len(candidates) == 0 {
+		return
+	}
+
+	name := ""
+	if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+		name = nameNode.Content(content)
+	}
+	var tokens []BodyToken
+	for _, sym := range candidates {
+		if sym.BodyTokens != nil || (name != "" && sym.Name != name) {
+			continue
+		}
+		if tokens == nil {
+			tokens = NormalizeBodyTokens(body, content)
+		}
+		sym.BodyTokens = tokens
+	}
+}
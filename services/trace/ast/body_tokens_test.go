@@ -0,0 +1,140 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const bodyTokensGoSource = `package validate
+
+import (
+	"errors"
+	"strings"
+)
+
+// ValidateEmail checks an address.
+func ValidateEmail(email string) error {
+	if len(email) == 0 {
+		return errors.New("email is required")
+	}
+	if !strings.Contains(email, "@") {
+		return errors.New("email must contain @")
+	}
+	return nil
+}
+`
+
+const bodyTokensTSSource = `export function validateAddress(addr: string): Error | null {
+  // Reject empty input first.
+  if (addr.length === 0) {
+    return new Error("address is required");
+  }
+  if (!addr.includes("@")) {
+    return new Error("address must contain @");
+  }
+  return null;
+}
+`
+
+const bodyTokensJSSource = `function checkMail(value) {
+  if (value.length === 0) {
+    return new Error("required");
+  }
+  if (!value.includes("@")) {
+    return new Error("missing @");
+  }
+  return null;
+}
+`
+
+const bodyTokensPySource = `def validate_email(email: str) -> Exception | None:
+    if len(email) == 0:
+        return ValueError("email is required")
+    elif not "@" in email:
+        return ValueError("email must contain @")
+    return None
+`
+
+func symbolNamed(t *testing.T, result *ParseResult, name string) *Symbol {
+	t.Helper()
+	for _, sym := range result.Symbols {
+		if sym.Name == name {
+			return sym
+		}
+	}
+	t.Fatalf("symbol %s not found", name)
+	return nil
+}
+
+func tokenString(tokens []BodyToken) string {
+	parts := make([]string, len(tokens))
+	for i, tok := range tokens {
+		parts[i] = tok.String()
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestBodyTokens_SameLogicAcrossLanguages(t *testing.T) {
+	ctx := context.Background()
+	withTokens := ParseOptions{IncludePrivate: true, ExtractBodyTokens: true}
+
+	goResult, err := NewGoParser(WithParseOptions(withTokens)).Parse(ctx, []byte(bodyTokensGoSource), "validate/email.go")
+	if err != nil {
+		t.Fatalf("go parse: %v", err)
+	}
+	tsResult, err := NewTypeScriptParser(WithTypeScriptParseOptions(withTokens)).Parse(ctx, []byte(bodyTokensTSSource), "web/validate.ts")
+	if err != nil {
+		t.Fatalf("ts parse: %v", err)
+	}
+	jsResult, err := NewJavaScriptParser(WithJSExtractBodyTokens(true)).Parse(ctx, []byte(bodyTokensJSSource), "web/check.js")
+	if err != nil {
+		t.Fatalf("js parse: %v", err)
+	}
+
+	goTokens := symbolNamed(t, goResult, "ValidateEmail").BodyTokens
+	want := "IF ID == NUM RETURN ID STR IF ! ID STR RETURN ID STR RETURN NIL"
+	if got := tokenString(goTokens); got != want {
+		t.Errorf("go tokens = %q, want %q", got, want)
+	}
+	if ts := symbolNamed(t, tsResult, "validateAddress").BodyTokens; !slices.Equal(ts, goTokens) {
+		t.Errorf("ts tokens = %q, want the Go stream %q", tokenString(ts), tokenString(goTokens))
+	}
+	if js := symbolNamed(t, jsResult, "checkMail").BodyTokens; !slices.Equal(js, goTokens) {
+		t.Errorf("js tokens = %q, want the Go stream %q", tokenString(js), tokenString(goTokens))
+	}
+}
+
+func TestBodyTokens_Python(t *testing.T) {
+	parser := NewPythonParser(WithPythonParseOptions(ParseOptions{IncludePrivate: true, ExtractBodyTokens: true}))
+	result, err := parser.Parse(context.Background(), []byte(bodyTokensPySource), "validate.py")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	got := tokenString(symbolNamed(t, result, "validate_email").BodyTokens)
+	want := "IF ID == NUM RETURN ID STR ELSE IF ! STR IN ID RETURN ID STR RETURN NIL"
+	if got != want {
+		t.Errorf("tokens = %q, want %q", got, want)
+	}
+}
+
+func TestBodyTokens_DisabledByDefault(t *testing.T) {
+	result, err := NewGoParser().Parse(context.Background(), []byte(bodyTokensGoSource), "validate/email.go")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if tokens := symbolNamed(t, result, "ValidateEmail").BodyTokens; tokens != nil {
+		t.Errorf("BodyTokens = %v, want nil without ExtractBodyTokens", tokens)
+	}
+}
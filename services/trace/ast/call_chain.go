@@ -0,0 +1,111 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// MaxCallChainHops is the maximum number of method hops kept in
+// CallSite.Chain. Longer chains are not decomposed.
+const MaxCallChainHops = 8
+
+// callChainSyntax names the tree-sitter node types and fields of one
+// grammar's method calls.
+type callChainSyntax struct {
+	call     string // call node type
+	callee   string // field of the call holding the callee
+	member   string // member access node type
+	object   string // field of the member access holding the receiver
+	property string // field of the member access holding the name
+}
+
+var (
+	goCallChainSyntax = callChainSyntax{
+		call: "call_expression", callee: "function",
+		member: "selector_expression", object: "operand", property: "field",
+	}
+	jsCallChainSyntax = callChainSyntax{
+		call: "call_expression", callee: "function",
+		member: "member_expression", object: "object", property: "property",
+	}
+	pythonCallChainSyntax = callChainSyntax{
+		call: "call", callee: "function",
+		member: "attribute", object: "object", property: "attribute",
+	}
+)
+
+// extractCallChain decomposes the callee of a method call whose receiver
+// is itself a method call into the hops of the chain.
+//
+// Description:
+//
+//	For client.Users().Get(id).Profile() the callee of the outer call is
+//	the member access "….Profile", and the chain is
+//	["client", "Users", "Get", "Profile"]: the root receiver, then every
+//	method called on it in order, ending with the call's own target. A
+//	root that is a call of a plain name, as in NewClient().Users().Get(),
+//	is kept with its parentheses ("NewClient()") so its return type can
+//	be looked up. Any other root (field access, index, literal) is kept
+//	as written.
+//
+// Inputs:
+//
+//	syn - The grammar's call syntax.
+//	member - The callee node of the call. Must be of type syn.member.
+//	content - Source file bytes.
+//
+// Outputs:
+//
+//	[]string - The chain, or nil when the receiver is not a method call
+//	  or the chain has more than MaxCallChainHops hops.
+//
+// Thread Safety: Safe for concurrent use.
+func extractCallChain(syn callChainSyntax, member *sitter.Node, content []byte) []string {
+	property := member.ChildByFieldName(syn.property)
+	receiver := member.ChildByFieldName(syn.object)
+	if property == nil || receiver == nil {
+		return nil
+	}
+	hops := []string{property.Content(content)}
+	for receiver.Type() == syn.call {
+		callee := receiver.ChildByFieldName(syn.callee)
+		if callee == nil || callee.Type() != syn.member {
+			break
+		}
+		property = callee.ChildByFieldName(syn.property)
+		next := callee.ChildByFieldName(syn.object)
+		if property == nil || next == nil {
+			return nil
+		}
+		if len(hops) == MaxCallChainHops {
+			return nil
+		}
+		hops = append(hops, property.Content(content))
+		receiver = next
+	}
+	if len(hops) < 2 {
+		return nil
+	}
+
+	root := receiver.Content(content)
+	if receiver.Type() == syn.call {
+		if callee := receiver.ChildByFieldName(syn.callee); callee != nil && callee.Type() == "identifier" {
+			root = callee.Content(content) + "()"
+		}
+	}
+	chain := make([]string, 0, len(hops)+1)
+	chain = append(chain, root)
+	for i := len(hops) - 1; i >= 0; i-- {
+		chain = append(chain, hops[i])
+	}
+	return chain
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// chainsByTarget returns the Chain of every call named in a symbol's
+// Calls, keyed by Target, searching children too.
+func chainsByTarget(syms []*Symbol, caller string) map[string][]string {
+	out := make(map[string][]string)
+	var walk func([]*Symbol)
+	walk = func(syms []*Symbol) {
+		for _, sym := range syms {
+			if sym.Name == caller {
+				for _, call := range sym.Calls {
+					out[call.Target] = call.Chain
+				}
+			}
+			walk(sym.Children)
+		}
+	}
+	walk(syms)
+	return out
+}
+
+func TestCallChain_Extraction(t *testing.T) {
+	tests := []struct {
+		name   string
+		parser Parser
+		path   string
+		src    string
+		caller string
+		want   map[string][]string
+	}{
+		{
+			name:   "go three hops",
+			parser: NewGoParser(),
+			path:   "api.go",
+			src:    "package api\n\nfunc Show(client *Client, id string) {\n\tclient.Users().Get(id).Profile()\n\tNewClient().Users().List()\n}\n",
+			caller: "Show",
+			want: map[string][]string{
+				"Users":   nil,
+				"Get":     {"client", "Users", "Get"},
+				"Profile": {"client", "Users", "Get", "Profile"},
+				"List":    {"NewClient()", "Users", "List"},
+			},
+		},
+		{
+			name:   "javascript express route",
+			parser: NewJavaScriptParser(),
+			path:   "app.js",
+			src:    "function setup(app) {\n  app.route('/x').get(handler).post(handler);\n}\n",
+			caller: "setup",
+			want: map[string][]string{
+				"route": nil,
+				"get":   {"app", "route", "get"},
+				"post":  {"app", "route", "get", "post"},
+			},
+		},
+		{
+			name:   "typescript this",
+			parser: NewTypeScriptParser(),
+			path:   "q.ts",
+			src:    "class Q {\n  run(): void {\n    this.select('a').where('b');\n  }\n}\n",
+			caller: "run",
+			want: map[string][]string{
+				"select": nil,
+				"where":  {"this", "select", "where"},
+			},
+		},
+		{
+			name:   "python session query",
+			parser: NewPythonParser(),
+			path:   "q.py",
+			src:    "def load(session):\n    return session.query(User).filter(x).first()\n",
+			caller: "load",
+			want: map[string][]string{
+				"query":  nil,
+				"filter": {"session", "query", "filter"},
+				"first":  {"session", "query", "filter", "first"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.parser.Parse(context.Background(), []byte(tt.src), tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := chainsByTarget(result.Symbols, tt.caller)
+			for target, want := range tt.want {
+				chain, ok := got[target]
+				if !ok {
+					t.Errorf("no call to %s; calls: %v", target, got)
+					continue
+				}
+				if !reflect.DeepEqual(chain, want) {
+					t.Errorf("%s chain = %q, want %q", target, chain, want)
+				}
+			}
+		})
+	}
+}
+
+func TestCallChain_MaxHops(t *testing.T) {
+	src := "package a\n\nfunc F(b *B) {\n\tb" + strings.Repeat(".M()", MaxCallChainHops+1) + "\n}\n"
+	result, err := NewGoParser().Parse(context.Background(), []byte(src), "a.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	longest := 0
+	for _, call := range callsOf(result.Symbols, "F") {
+		longest = max(longest, len(call.Chain))
+	}
+	if longest != MaxCallChainHops+1 {
+		t.Errorf("longest chain = %d entries, want root plus %d hops", longest, MaxCallChainHops)
+	}
+}
+
+// callsOf returns the calls of the top-level symbol named caller.
+func callsOf(syms []*Symbol, caller string) []CallSite {
+	for _, sym := range syms {
+		if sym.Name == caller {
+			return sym.Calls
+		}
+	}
+	return nil
+}
+
+func TestJavaScriptParser_InfersFluentReturnType(t *testing.T) {
+	src := `class Route {
+  get(h) { this.stack.push(h); return this; }
+  all(h) { if (h) { return this; } return this; }
+  mixed(h) { if (h) { return this; } return null; }
+  make() { const f = () => { return 1; }; return new Layer(f); }
+  none() { this.x = 1; }
+}
+`
+	result, err := NewJavaScriptParser().Parse(context.Background(), []byte(src), "route.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"get": "this", "all": "this", "mixed": "", "make": "Layer", "none": ""}
+	found := 0
+	var walk func([]*Symbol)
+	walk = func(syms []*Symbol) {
+		for _, sym := range syms {
+			if w, ok := want[sym.Name]; ok && sym.Kind == SymbolKindMethod {
+				found++
+				got := ""
+				if sym.Metadata != nil {
+					got = sym.Metadata.ReturnType
+				}
+				if got != w {
+					t.Errorf("%s ReturnType = %q, want %q", sym.Name, got, w)
+				}
+			}
+			walk(sym.Children)
+		}
+	}
+	walk(result.Symbols)
+	if found != len(want) {
+		t.Errorf("found %d methods, want %d", found, len(want))
+	}
+}
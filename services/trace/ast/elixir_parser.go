@@ -0,0 +1,955 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/elixir"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// ElixirParserOption configures an ElixirParser instance.
+type ElixirParserOption func(*ElixirParser)
+
+// WithElixirMaxFileSize sets the maximum file size the parser will accept.
+//
+// Parameters:
+//   - bytes: Maximum file size in bytes. Must be positive.
+//
+// Example:
+//
+//	parser := NewElixirParser(WithElixirMaxFileSize(5 * 1024 * 1024)) // 5MB limit
+func WithElixirMaxFileSize(bytes int64) ElixirParserOption {
+	return func(p *ElixirParser) {
+		if bytes > 0 {
+			p.maxFileSize = bytes
+		}
+	}
+}
+
+// WithElixirParseOptions applies the given ParseOptions to the parser.
+//
+// Parameters:
+//   - opts: ParseOptions to apply.
+//
+// Example:
+//
+//	parser := NewElixirParser(WithElixirParseOptions(ParseOptions{IncludePrivate: false}))
+func WithElixirParseOptions(opts ParseOptions) ElixirParserOption {
+	return func(p *ElixirParser) {
+		p.parseOptions = opts
+	}
+}
+
+// ElixirParser implements the Parser interface for Elixir source code.
+//
+// Description:
+//
+//	ElixirParser uses tree-sitter to parse .ex and .exs files and extract
+//	symbols, mapped onto the existing symbol kinds:
+//
+//	  alias/import/use/require Mod
+//	                        -> Import, Path "Mod"
+//	  defmodule             -> SymbolKindClass
+//	  defprotocol           -> SymbolKindInterface with its functions in
+//	                           Metadata.Methods
+//	  defimpl P, for: T     -> SymbolKindClass named T with
+//	                           Metadata.Implements = [P]
+//	  def, defmacro, defguard, defdelegate (in a module)
+//	                        -> SymbolKindMethod, Receiver = module
+//	  defp, defmacrop, defguardp
+//	                        -> the same, with Exported false
+//	  def (top level)       -> SymbolKindFunction
+//
+//	Modules are named by their last segment, with Package set to the rest
+//	of the path ("Chat.Room" -> Name "Room", Package "Chat"). A nested
+//	"defmodule State" in Chat.Room is Chat.Room.State. Consecutive clauses
+//	of a function with the same name and arity become one symbol spanning
+//	all of them. Macros are treated as functions.
+//
+//	An implementation without "for:" implements the protocol for the
+//	enclosing module. With a list of types ("for: [List, Map]") the
+//	symbol is named after the first one. Conformance is only recorded
+//	through defimpl; a module that happens to define a protocol's
+//	functions does not implement it.
+//
+//	Remote calls use the module's last segment as Receiver, after
+//	expanding aliases declared in the module: with "alias Chat.Store,
+//	as: S", "S.put(x)" has Receiver "Store". Behaviours, GenServer
+//	callbacks, structs and module attributes are not modelled.
+//
+// Thread Safety:
+//
+//	ElixirParser instances are safe for concurrent use. Each Parse call
+//	creates its own tree-sitter parser instance.
+//
+// Example:
+//
+//	parser := NewElixirParser()
+//	result, err := parser.Parse(ctx, content, "lib/chat/room.ex")
+//	if err != nil {
+//	    return fmt.Errorf("parse: %w", err)
+//	}
+//	for _, sym := range result.Symbols {
+//	    fmt.Printf("%s: %s\n", sym.Kind, sym.Name)
+//	}
+type ElixirParser struct {
+	maxFileSize  int64
+	parseOptions ParseOptions
+}
+
+// NewElixirParser creates a new ElixirParser with the given options.
+//
+// Inputs:
+//   - opts: Optional configuration functions (WithElixirMaxFileSize, WithElixirParseOptions)
+//
+// Outputs:
+//   - *ElixirParser: Configured parser instance, never nil
+//
+// Thread Safety:
+//
+//	The returned ElixirParser is safe for concurrent use.
+func NewElixirParser(opts ...ElixirParserOption) *ElixirParser {
+	p := &ElixirParser{
+		maxFileSize:  DefaultMaxFileSize,
+		parseOptions: DefaultParseOptions(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Language returns the canonical language name for this parser.
+func (p *ElixirParser) Language() string {
+	return "elixir"
+}
+
+// Extensions returns the file extensions this parser handles.
+func (p *ElixirParser) Extensions() []string {
+	return []string{".ex", ".exs"}
+}
+
+// elixirFile holds the per-file state of one Parse call.
+type elixirFile struct {
+	content  []byte
+	filePath string
+}
+
+// elixirBody describes the module body being extracted.
+type elixirBody struct {
+	// owner is the module, protocol or implementation symbol, nil at the
+	// top level.
+	owner *Symbol
+	// module is the full dotted name of the enclosing module, used to
+	// name nested modules.
+	module string
+	// aliases maps the short names declared with alias in this body and
+	// its enclosing bodies to full module names.
+	aliases map[string]string
+}
+
+// elixirDefinitions are the macros that define functions.
+var elixirDefinitions = map[string]bool{
+	"def": true, "defp": true, "defmacro": true, "defmacrop": true,
+	"defguard": true, "defguardp": true, "defdelegate": true,
+}
+
+// elixirSpecialForms are calls that are control flow or declarations
+// rather than calls to project functions. Their arguments are still
+// searched for calls.
+var elixirSpecialForms = map[string]bool{
+	"alias": true, "case": true, "cond": true, "for": true, "if": true,
+	"import": true, "quote": true, "receive": true, "require": true,
+	"super": true, "try": true, "unless": true, "unquote": true,
+	"unquote_splicing": true, "use": true, "with": true,
+}
+
+// Parse extracts symbols from Elixir source code.
+//
+// Description:
+//
+//	Parses the provided Elixir source with tree-sitter and extracts
+//	alias, import, use and require directives, modules, protocols,
+//	protocol implementations and functions. The parser is error-tolerant:
+//	syntax errors are reported in ParseResult.Errors and the remaining
+//	declarations are still extracted.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked before and after parsing.
+//   - content: Raw Elixir source bytes. Must be valid UTF-8.
+//   - filePath: Path to the file, relative to the project root.
+//
+// Outputs:
+//   - *ParseResult: Extracted symbols and metadata. Never nil on success.
+//   - error: Non-nil for complete failures:
+//   - ErrFileTooLarge: Content exceeds maxFileSize
+//   - ErrInvalidContent: Content is not valid UTF-8
+//   - Context errors: Context was canceled or timed out
+//
+// Thread Safety:
+//
+//	This method is safe for concurrent use.
+func (p *ElixirParser) Parse(ctx context.Context, content []byte, filePath string) (*ParseResult, error) {
+	ctx, span := startParseSpan(ctx, "elixir", filePath, len(content))
+	defer span.End()
+
+	start := time.Now()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "elixir", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled before start: %w", err)
+	}
+
+	if int64(len(content)) > p.maxFileSize {
+		recordParseMetrics(ctx, "elixir", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: size %d exceeds limit %d", ErrFileTooLarge, len(content), p.maxFileSize)
+	}
+
+	if !utf8.Valid(content) {
+		recordParseMetrics(ctx, "elixir", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: content is not valid UTF-8", ErrInvalidContent)
+	}
+
+	hash := sha256.Sum256(content)
+	hashStr := hex.EncodeToString(hash[:])
+
+	// New tree-sitter parser per call for thread safety
+	parser := sitter.NewParser()
+	parser.SetLanguage(elixir.GetLanguage())
+
+	tree, err := parser.ParseCtx(ctx, nil, content)
+	if err != nil {
+		recordParseMetrics(ctx, "elixir", time.Since(start), 0, false)
+		return nil, fmt.Errorf("tree-sitter parse failed: %w", err)
+	}
+	defer tree.Close()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "elixir", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled after tree-sitter: %w", err)
+	}
+
+	result := &ParseResult{
+		FilePath:      filePath,
+		Language:      "elixir",
+		Hash:          hashStr,
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Symbols:       make([]*Symbol, 0),
+		Imports:       make([]Import, 0),
+		Errors:        make([]string, 0),
+	}
+
+	rootNode := tree.RootNode()
+	if rootNode == nil {
+		result.Errors = append(result.Errors, "tree-sitter returned nil root node")
+		return result, nil
+	}
+
+	f := &elixirFile{content: content, filePath: filePath}
+	p.extractBody(ctx, rootNode, f, elixirBody{aliases: make(map[string]string)}, result)
+
+	if err := result.Validate(); err != nil {
+		recordParseMetrics(ctx, "elixir", time.Since(start), 0, false)
+		return nil, fmt.Errorf("result validation failed: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "elixir", time.Since(start), len(result.Symbols), false)
+		return nil, fmt.Errorf("parse canceled after extraction: %w", err)
+	}
+
+	setParseSpanResult(span, len(result.Symbols), len(result.Errors))
+	recordParseMetrics(ctx, "elixir", time.Since(start), len(result.Symbols), true)
+
+	return result, nil
+}
+
+// extractBody extracts the expressions directly under parent: the source
+// file or a module's do block. Modules are appended to result.Symbols;
+// functions become children of body.owner, or top-level symbols if there
+// is none.
+func (p *ElixirParser) extractBody(ctx context.Context, parent *sitter.Node, f *elixirFile, body elixirBody, result *ParseResult) {
+	// doc is the most recent @doc, attached to the next function.
+	doc := ""
+	var members []*Symbol
+	// lastKey is the name/arity of the last function, to merge clauses.
+	lastKey := ""
+
+	for i := 0; i < int(parent.NamedChildCount()); i++ {
+		if ctx.Err() != nil {
+			return
+		}
+
+		child := parent.NamedChild(i)
+		switch child.Type() {
+		case elixirNodeUnaryOperator:
+			name, text, ok := elixirAttribute(child, f.content)
+			if !ok {
+				continue
+			}
+			switch name {
+			case "doc":
+				doc = text
+			case "moduledoc":
+				if body.owner != nil && body.owner.DocComment == "" {
+					body.owner.DocComment = text
+				}
+			}
+			continue
+		case elixirNodeERROR:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s:%d: syntax error: %s",
+				f.filePath, int(child.StartPoint().Row)+1, sqlSnippet(f.content[child.StartByte():child.EndByte()])))
+			continue
+		case elixirNodeCall:
+		default:
+			continue
+		}
+
+		target := child.ChildByFieldName("target")
+		if target == nil || target.Type() != elixirNodeIdentifier {
+			continue
+		}
+		switch keyword := elixirNodeText(target, f.content); {
+		case keyword == "defmodule" || keyword == "defprotocol":
+			p.extractModule(ctx, child, f, body, keyword, result)
+		case keyword == "defimpl":
+			p.extractImpl(ctx, child, f, body, result)
+		case keyword == "alias" || keyword == "import" || keyword == "use" || keyword == "require":
+			p.extractDirective(child, f, body, keyword, result)
+		case elixirDefinitions[keyword]:
+			fn, key := p.extractFunction(ctx, child, f, body, keyword)
+			if fn == nil {
+				continue
+			}
+			if key == lastKey && len(members) > 0 {
+				// Another clause of the previous function.
+				prev := members[len(members)-1]
+				prev.EndLine, prev.EndCol = fn.EndLine, fn.EndCol
+				prev.Calls = append(prev.Calls, fn.Calls...)
+				continue
+			}
+			if doc != "" {
+				fn.DocComment = doc
+				doc = ""
+			}
+			members = append(members, fn)
+			lastKey = key
+		}
+	}
+
+	for _, member := range members {
+		if !p.include(member) {
+			continue
+		}
+		if body.owner != nil {
+			body.owner.Children = append(body.owner.Children, member)
+		} else {
+			result.Symbols = append(result.Symbols, member)
+		}
+	}
+}
+
+// extractModule builds a module or protocol symbol, appends it to
+// result.Symbols and extracts its body.
+func (p *ElixirParser) extractModule(ctx context.Context, node *sitter.Node, f *elixirFile, body elixirBody, keyword string, result *ParseResult) {
+	args := elixirArguments(node)
+	if args == nil || args.NamedChildCount() == 0 || args.NamedChild(0).Type() != elixirNodeAlias {
+		return
+	}
+	nameNode := args.NamedChild(0)
+	path := elixirNodeText(nameNode, f.content)
+	full := path
+	if body.module != "" {
+		full = body.module + "." + path
+		// A nested module is aliased by its first segment in the
+		// enclosing module.
+		first, _, _ := strings.Cut(path, ".")
+		body.aliases[first] = body.module + "." + first
+	}
+
+	kind := SymbolKindClass
+	if keyword == "defprotocol" {
+		kind = SymbolKindInterface
+	}
+	name, pkg := elixirSplitModule(full)
+	signature := elixirCollapseSpace(string(f.content[node.StartByte():nameNode.EndByte()]))
+	sym := p.newSymbol(node, f, name, kind, signature, pkg)
+	if result.Package == "" && pkg != "" {
+		result.Package = pkg
+	}
+	result.Symbols = append(result.Symbols, sym)
+
+	if block := elixirDoBlock(node); block != nil {
+		p.extractBody(ctx, block, f, elixirBody{owner: sym, module: full, aliases: elixirCopyAliases(body.aliases)}, result)
+	}
+	if kind == SymbolKindInterface {
+		elixirCollectMethods(sym)
+	}
+}
+
+// extractImpl builds the symbol of a protocol implementation: a class
+// named after the implementing type with Metadata.Implements set to the
+// protocol.
+func (p *ElixirParser) extractImpl(ctx context.Context, node *sitter.Node, f *elixirFile, body elixirBody, result *ParseResult) {
+	args := elixirArguments(node)
+	if args == nil || args.NamedChildCount() == 0 || args.NamedChild(0).Type() != elixirNodeAlias {
+		return
+	}
+	protocolNode := args.NamedChild(0)
+	protocol := elixirExpandAlias(elixirNodeText(protocolNode, f.content), body.aliases)
+
+	forType := body.module
+	sigEnd := protocolNode.EndByte()
+	if value := elixirKeyword(args, "for", f.content); value != nil {
+		sigEnd = args.EndByte()
+		if value.Type() == elixirNodeList && value.NamedChildCount() > 0 {
+			value = value.NamedChild(0)
+		}
+		if value.Type() != elixirNodeAlias {
+			return
+		}
+		forType = elixirExpandAlias(elixirNodeText(value, f.content), body.aliases)
+	}
+	if forType == "" {
+		return
+	}
+
+	name, _ := elixirSplitModule(forType)
+	protocolName, _ := elixirSplitModule(protocol)
+	signature := elixirCollapseSpace(string(f.content[node.StartByte():sigEnd]))
+	sym := p.newSymbol(node, f, name, SymbolKindClass, signature, protocol)
+	elixirMetadata(sym).Implements = []string{protocolName}
+	result.Symbols = append(result.Symbols, sym)
+
+	if block := elixirDoBlock(node); block != nil {
+		p.extractBody(ctx, block, f, elixirBody{owner: sym, module: protocol + "." + forType, aliases: elixirCopyAliases(body.aliases)}, result)
+	}
+}
+
+// extractDirective records an alias, import, use or require directive as
+// one import per module. Aliases are also added to body.aliases.
+func (p *ElixirParser) extractDirective(node *sitter.Node, f *elixirFile, body elixirBody, keyword string, result *ParseResult) {
+	args := elixirArguments(node)
+	if args == nil || args.NamedChildCount() == 0 {
+		return
+	}
+	first := args.NamedChild(0)
+
+	var paths []string
+	switch first.Type() {
+	case elixirNodeAlias:
+		paths = []string{elixirExpandAlias(elixirNodeText(first, f.content), body.aliases)}
+	case elixirNodeDot:
+		// alias Chat.{Message, User}
+		left, right := first.ChildByFieldName("left"), first.ChildByFieldName("right")
+		if left == nil || right == nil || left.Type() != elixirNodeAlias || right.Type() != elixirNodeTuple {
+			return
+		}
+		prefix := elixirExpandAlias(elixirNodeText(left, f.content), body.aliases)
+		for i := 0; i < int(right.NamedChildCount()); i++ {
+			if item := right.NamedChild(i); item.Type() == elixirNodeAlias {
+				paths = append(paths, prefix+"."+elixirNodeText(item, f.content))
+			}
+		}
+	default:
+		return
+	}
+
+	for _, path := range paths {
+		imp := Import{Path: path, Location: elixirLocation(node, f.filePath)}
+		switch keyword {
+		case "alias":
+			short, _ := elixirSplitModule(path)
+			if as := elixirKeyword(args, "as", f.content); as != nil && len(paths) == 1 {
+				short = elixirNodeText(as, f.content)
+				imp.Alias = short
+			}
+			body.aliases[short] = path
+		case "import":
+			only := elixirKeyword(args, "only", f.content)
+			if only != nil && only.Type() == elixirNodeList {
+				imp.Names = elixirKeywordKeys(only, f.content)
+			}
+			imp.IsWildcard = len(imp.Names) == 0
+		}
+		result.Imports = append(result.Imports, imp)
+	}
+}
+
+// extractFunction builds the symbol of a def-family call. It returns the
+// symbol and its "name/arity" key, or nil if the head cannot be named.
+func (p *ElixirParser) extractFunction(ctx context.Context, node *sitter.Node, f *elixirFile, body elixirBody, keyword string) (*Symbol, string) {
+	args := elixirArguments(node)
+	if args == nil || args.NamedChildCount() == 0 {
+		return nil, ""
+	}
+	head := args.NamedChild(0)
+	if head.Type() == elixirNodeBinaryOperator {
+		if op := head.ChildByFieldName("operator"); op != nil && elixirNodeText(op, f.content) == "when" {
+			head = head.ChildByFieldName("left")
+		}
+	}
+	if head == nil {
+		return nil, ""
+	}
+
+	var name string
+	arity := 0
+	switch head.Type() {
+	case elixirNodeIdentifier:
+		name = elixirNodeText(head, f.content)
+	case elixirNodeCall:
+		target := head.ChildByFieldName("target")
+		if target == nil || target.Type() != elixirNodeIdentifier {
+			return nil, ""
+		}
+		name = elixirNodeText(target, f.content)
+		if params := elixirArguments(head); params != nil {
+			arity = int(params.NamedChildCount())
+		}
+	default:
+		return nil, ""
+	}
+
+	signature := elixirCollapseSpace(string(f.content[node.StartByte():head.EndByte()]))
+
+	kind := SymbolKindFunction
+	owner, pkg := "", ""
+	if body.owner != nil {
+		kind = SymbolKindMethod
+		owner, pkg = body.owner.Name, body.owner.Package
+	}
+	sym := p.newSymbol(node, f, name, kind, signature, pkg)
+	sym.Receiver = owner
+	if owner != "" {
+		elixirMetadata(sym).ParentName = owner
+	}
+	if strings.HasSuffix(keyword, "p") {
+		elixirMetadata(sym).AccessModifier = "private"
+		sym.Exported = false
+	}
+
+	if keyword == "defdelegate" {
+		if to := elixirKeyword(args, "to", f.content); to != nil && to.Type() == elixirNodeAlias {
+			target := name
+			if as := elixirKeyword(args, "as", f.content); as != nil && as.Type() == elixirNodeAtom {
+				target = strings.TrimPrefix(elixirNodeText(as, f.content), ":")
+			}
+			module, _ := elixirSplitModule(elixirExpandAlias(elixirNodeText(to, f.content), body.aliases))
+			sym.Calls = []CallSite{{Target: target, IsMethod: true, Receiver: module, Location: elixirLocation(node, f.filePath)}}
+		}
+	} else {
+		scope := elixirScope{module: owner, aliases: body.aliases}
+		if block := elixirDoBlock(node); block != nil {
+			sym.Calls = p.extractCallSites(ctx, block, f, scope)
+		} else if value := elixirKeyword(args, "do", f.content); value != nil {
+			sym.Calls = p.extractCallSites(ctx, value, f, scope)
+		}
+	}
+	return sym, fmt.Sprintf("%s/%d", name, arity)
+}
+
+// elixirScope is the context needed to name the receivers of calls.
+type elixirScope struct {
+	// module is the last segment of the enclosing module, for __MODULE__.
+	module  string
+	aliases map[string]string
+}
+
+// extractCallSites extracts all call sites within a function body.
+//
+// Description:
+//
+//	Walks the subtree under bodyNode and records each call. "helper(x)"
+//	and "x |> helper()" are plain calls. "Chat.Room.join(r)",
+//	"Room.join(r)" and "__MODULE__.join(r)" are method calls with the
+//	module's last segment as Receiver, after alias expansion; ":ets.insert"
+//	uses "ets". Captures count as calls: "&Formatter.format/1" like a
+//	remote call and "&helper/1" like a local one. Calls on variables need
+//	parentheses ("state.repo.get(id)" has Receiver "state.repo"), since
+//	"user.name" is a map field access. Anonymous function calls
+//	("fun.(x)"), special forms (if, case, with, ...) and nested
+//	definitions are not recorded.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked every 100 nodes.
+//   - bodyNode: The function body. May be nil.
+//   - f: The file being parsed.
+//   - scope: The enclosing module and its aliases.
+//
+// Outputs:
+//   - []CallSite: Extracted call sites. Limited to MaxCallSitesPerSymbol (1000).
+//
+// Thread Safety: Safe for concurrent use.
+func (p *ElixirParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node, f *elixirFile, scope elixirScope) []CallSite {
+	if bodyNode == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "ElixirParser.extractCallSites")
+	defer span.End()
+
+	calls := make([]CallSite, 0, 8)
+
+	type stackEntry struct {
+		node  *sitter.Node
+		depth int
+	}
+	stack := []stackEntry{{node: bodyNode}}
+
+	nodeCount := 0
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := entry.node
+		if entry.depth > MaxCallExpressionDepth {
+			continue
+		}
+
+		nodeCount++
+		if nodeCount%100 == 0 && ctx.Err() != nil {
+			return calls
+		}
+
+		if len(calls) >= MaxCallSitesPerSymbol {
+			slog.Warn("max call sites per symbol reached in Elixir",
+				telemetry.SensitiveLog("file", f.filePath),
+				slog.Int("limit", MaxCallSitesPerSymbol),
+			)
+			return calls
+		}
+
+		switch node.Type() {
+		case elixirNodeCall:
+			if target := node.ChildByFieldName("target"); target != nil && target.Type() == elixirNodeIdentifier {
+				keyword := elixirNodeText(target, f.content)
+				if keyword == "defmodule" || keyword == "defprotocol" || keyword == "defimpl" || elixirDefinitions[keyword] {
+					continue
+				}
+			}
+			if call := elixirCallSite(node, f, scope); call != nil {
+				calls = append(calls, *call)
+			}
+		case elixirNodeUnaryOperator:
+			if call := elixirLocalCapture(node, f); call != nil {
+				calls = append(calls, *call)
+			}
+		}
+
+		// Push in reverse order for left-to-right processing.
+		for i := int(node.NamedChildCount()) - 1; i >= 0; i-- {
+			if child := node.NamedChild(i); child != nil {
+				stack = append(stack, stackEntry{node: child, depth: entry.depth + 1})
+			}
+		}
+	}
+
+	span.SetAttributes(
+		telemetry.SensitiveString("file", f.filePath),
+		attribute.Int("calls_found", len(calls)),
+		attribute.Int("nodes_traversed", nodeCount),
+	)
+
+	return calls
+}
+
+// elixirCallSite converts a call node to a CallSite, or nil if the callee
+// cannot be named or is a special form.
+func elixirCallSite(node *sitter.Node, f *elixirFile, scope elixirScope) *CallSite {
+	target := node.ChildByFieldName("target")
+	if target == nil {
+		return nil
+	}
+	call := &CallSite{Location: elixirLocation(node, f.filePath)}
+
+	switch target.Type() {
+	case elixirNodeIdentifier:
+		name := elixirNodeText(target, f.content)
+		if elixirSpecialForms[name] {
+			return nil
+		}
+		call.Target = name
+		return call
+	case elixirNodeDot:
+	default:
+		return nil
+	}
+
+	left, right := target.ChildByFieldName("left"), target.ChildByFieldName("right")
+	if left == nil || right == nil || right.Type() != elixirNodeIdentifier {
+		return nil
+	}
+	call.Target = elixirNodeText(right, f.content)
+	call.IsMethod = true
+
+	switch left.Type() {
+	case elixirNodeAlias:
+		call.Receiver, _ = elixirSplitModule(elixirExpandAlias(elixirNodeText(left, f.content), scope.aliases))
+	case elixirNodeAtom:
+		call.Receiver = strings.TrimPrefix(elixirNodeText(left, f.content), ":")
+	default:
+		text := elixirNodeText(left, f.content)
+		switch {
+		case text == "__MODULE__":
+			if scope.module == "" {
+				return nil
+			}
+			call.Receiver = scope.module
+		case elixirArguments(node) == nil:
+			// user.name is a map field access.
+			return nil
+		default:
+			call.Receiver = elixirCollapseSpace(text)
+		}
+	}
+	return call
+}
+
+// elixirLocalCapture converts "&helper/1" to a call of helper, or returns
+// nil for other unary operators. Remote captures are calls and are
+// handled by elixirCallSite.
+func elixirLocalCapture(node *sitter.Node, f *elixirFile) *CallSite {
+	op := node.ChildByFieldName("operator")
+	operand := node.ChildByFieldName("operand")
+	if op == nil || operand == nil || elixirNodeText(op, f.content) != "&" || operand.Type() != elixirNodeBinaryOperator {
+		return nil
+	}
+	slash := operand.ChildByFieldName("operator")
+	left := operand.ChildByFieldName("left")
+	if slash == nil || left == nil || elixirNodeText(slash, f.content) != "/" || left.Type() != elixirNodeIdentifier {
+		return nil
+	}
+	return &CallSite{Target: elixirNodeText(left, f.content), Location: elixirLocation(node, f.filePath)}
+}
+
+// newSymbol creates an Elixir symbol with its location and package set.
+func (p *ElixirParser) newSymbol(node *sitter.Node, f *elixirFile, name string, kind SymbolKind, signature, pkg string) *Symbol {
+	return &Symbol{
+		ID:            GenerateID(f.filePath, int(node.StartPoint().Row)+1, name),
+		Name:          name,
+		Kind:          kind,
+		FilePath:      f.filePath,
+		StartLine:     int(node.StartPoint().Row) + 1,
+		EndLine:       int(node.EndPoint().Row) + 1,
+		StartCol:      int(node.StartPoint().Column),
+		EndCol:        int(node.EndPoint().Column),
+		Signature:     signature,
+		Package:       pkg,
+		Language:      "elixir",
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Exported:      true,
+	}
+}
+
+// include reports whether sym passes the IncludePrivate option.
+func (p *ElixirParser) include(sym *Symbol) bool {
+	return p.parseOptions.IncludePrivate || sym.Exported
+}
+
+// elixirCollectMethods fills Metadata.Methods of a protocol from its
+// function declarations.
+func elixirCollectMethods(sym *Symbol) {
+	for _, child := range sym.Children {
+		if child.Kind != SymbolKindMethod {
+			continue
+		}
+		sig := MethodSignature{Name: child.Name, ReceiverType: sym.Name}
+		if open := strings.Index(child.Signature, "("); open >= 0 && strings.HasSuffix(child.Signature, ")") {
+			sig.Params = strings.TrimSpace(child.Signature[open+1 : len(child.Signature)-1])
+		}
+		if sig.Params != "" {
+			sig.ParamCount = strings.Count(sig.Params, ",") + 1
+		}
+		meta := elixirMetadata(sym)
+		meta.Methods = append(meta.Methods, sig)
+	}
+}
+
+// elixirAttribute returns the name and string value of a module attribute
+// such as @doc "text". The value is "" for non-string values (@doc false).
+func elixirAttribute(node *sitter.Node, content []byte) (name, value string, ok bool) {
+	op := node.ChildByFieldName("operator")
+	operand := node.ChildByFieldName("operand")
+	if op == nil || operand == nil || elixirNodeText(op, content) != "@" || operand.Type() != elixirNodeCall {
+		return "", "", false
+	}
+	target := operand.ChildByFieldName("target")
+	if target == nil || target.Type() != elixirNodeIdentifier {
+		return "", "", false
+	}
+	if args := elixirArguments(operand); args != nil && args.NamedChildCount() > 0 {
+		value = elixirStringContent(args.NamedChild(0), content)
+	}
+	return elixirNodeText(target, content), value, true
+}
+
+// elixirStringContent returns the text of a string literal with each line
+// trimmed, or "" for other nodes and interpolated strings.
+func elixirStringContent(node *sitter.Node, content []byte) string {
+	if node.Type() != elixirNodeString {
+		return ""
+	}
+	var parts []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() != elixirNodeQuotedContent {
+			return ""
+		}
+		parts = append(parts, elixirNodeText(child, content))
+	}
+	lines := strings.Split(strings.Join(parts, ""), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// elixirArguments returns the arguments node of a call, or nil.
+func elixirArguments(call *sitter.Node) *sitter.Node {
+	for i := 0; i < int(call.NamedChildCount()); i++ {
+		if child := call.NamedChild(i); child.Type() == elixirNodeArguments {
+			return child
+		}
+	}
+	return nil
+}
+
+// elixirDoBlock returns the do block of a call, or nil.
+func elixirDoBlock(call *sitter.Node) *sitter.Node {
+	for i := 0; i < int(call.NamedChildCount()); i++ {
+		if child := call.NamedChild(i); child.Type() == elixirNodeDoBlock {
+			return child
+		}
+	}
+	return nil
+}
+
+// elixirKeyword returns the value of keyword key ("for" in "for: T") in
+// the trailing keyword list of args, or nil.
+func elixirKeyword(args *sitter.Node, key string, content []byte) *sitter.Node {
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		keywords := args.NamedChild(i)
+		if keywords.Type() != elixirNodeKeywords {
+			continue
+		}
+		for j := 0; j < int(keywords.NamedChildCount()); j++ {
+			pair := keywords.NamedChild(j)
+			if pair.Type() != elixirNodePair {
+				continue
+			}
+			if k := pair.ChildByFieldName("key"); k != nil && elixirKeyName(k, content) == key {
+				return pair.ChildByFieldName("value")
+			}
+		}
+	}
+	return nil
+}
+
+// elixirKeywordKeys returns the keys of a keyword list literal: ["map",
+// "filter"] for [map: 2, filter: 2].
+func elixirKeywordKeys(list *sitter.Node, content []byte) []string {
+	var keys []string
+	for i := 0; i < int(list.NamedChildCount()); i++ {
+		keywords := list.NamedChild(i)
+		if keywords.Type() != elixirNodeKeywords {
+			continue
+		}
+		for j := 0; j < int(keywords.NamedChildCount()); j++ {
+			if k := keywords.NamedChild(j).ChildByFieldName("key"); k != nil {
+				keys = append(keys, elixirKeyName(k, content))
+			}
+		}
+	}
+	return keys
+}
+
+// elixirKeyName returns the name of a keyword key: "for" for "for: ".
+func elixirKeyName(key *sitter.Node, content []byte) string {
+	return strings.TrimSuffix(strings.TrimSpace(elixirNodeText(key, content)), ":")
+}
+
+// elixirExpandAlias replaces the first segment of a module name with its
+// alias target: "S.Cache" -> "Chat.Store.Cache" after alias Chat.Store,
+// as: S.
+func elixirExpandAlias(name string, aliases map[string]string) string {
+	first, rest, nested := strings.Cut(name, ".")
+	full, ok := aliases[first]
+	if !ok {
+		return name
+	}
+	if nested {
+		return full + "." + rest
+	}
+	return full
+}
+
+// elixirSplitModule splits a module name into its last segment and the
+// rest: "Chat.Room" -> "Room", "Chat".
+func elixirSplitModule(module string) (name, pkg string) {
+	if i := strings.LastIndex(module, "."); i >= 0 {
+		return module[i+1:], module[:i]
+	}
+	return module, ""
+}
+
+// elixirCopyAliases returns a copy of aliases, so aliases declared in a
+// nested module do not leak into the enclosing one.
+func elixirCopyAliases(aliases map[string]string) map[string]string {
+	out := make(map[string]string, len(aliases))
+	for k, v := range aliases {
+		out[k] = v
+	}
+	return out
+}
+
+// elixirMetadata returns sym.Metadata, allocating it if needed.
+func elixirMetadata(sym *Symbol) *SymbolMetadata {
+	if sym.Metadata == nil {
+		sym.Metadata = &SymbolMetadata{}
+	}
+	return sym.Metadata
+}
+
+// elixirLocation returns the location of node.
+func elixirLocation(node *sitter.Node, filePath string) Location {
+	return Location{
+		FilePath:  filePath,
+		StartLine: int(node.StartPoint().Row) + 1,
+		EndLine:   int(node.EndPoint().Row) + 1,
+		StartCol:  int(node.StartPoint().Column),
+		EndCol:    int(node.EndPoint().Column),
+	}
+}
+
+// elixirCollapseSpace replaces runs of whitespace with single spaces.
+func elixirCollapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// elixirNodeText returns the source text of node.
+func elixirNodeText(node *sitter.Node, content []byte) string {
+	return string(content[node.StartByte():node.EndByte()])
+}
@@ -0,0 +1,237 @@
+package ast
+
+import (
+	"context"
+	"testing"
+)
+
+const testElixirSource = `defmodule Chat.Room do
+  @moduledoc "A chat room."
+  alias Chat.{Message, User}
+  alias Chat.Store, as: S
+  import Enum, only: [map: 2]
+  use GenServer
+  require Logger
+
+  @doc """
+  Joins a room.
+  """
+  def join(room, %User{} = user) when is_binary(room) do
+    Message.broadcast(room, user)
+    S.put(room)
+    Chat.Presence.track(user)
+    helper(user)
+    user |> format()
+    :ets.insert(:rooms, room)
+    Enum.map([user], &Formatter.format/1)
+    Enum.each([user], &helper/1)
+    __MODULE__.leave(room)
+    if user.admin, do: log(user)
+  end
+
+  def leave(room), do: room
+
+  defp helper(%User{} = user), do: user
+  defp helper(other), do: other
+
+  defmacro trace(expr) do
+    quote do: unquote(expr)
+  end
+
+  defdelegate fetch(id), to: S, as: :get
+
+  defmodule State do
+    def new, do: %{}
+  end
+end
+
+defprotocol Chat.Sendable do
+  @doc "Delivers a message."
+  def deliver(msg, to)
+  def id(msg)
+end
+
+defimpl Chat.Sendable, for: Chat.Message do
+  def deliver(msg, to), do: Chat.Room.join(to, msg)
+  def id(msg), do: msg.id
+end
+`
+
+func parseTestElixir(t *testing.T, opts ...ElixirParserOption) *ParseResult {
+	t.Helper()
+	result, err := NewElixirParser(opts...).Parse(context.Background(), []byte(testElixirSource), "lib/chat/room.ex")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected parse errors: %v", result.Errors)
+	}
+	return result
+}
+
+func TestElixirParser_LanguageAndExtensions(t *testing.T) {
+	parser := NewElixirParser()
+	if got := parser.Language(); got != "elixir" {
+		t.Errorf("Language() = %q, want %q", got, "elixir")
+	}
+	if exts := parser.Extensions(); len(exts) != 2 || exts[0] != ".ex" || exts[1] != ".exs" {
+		t.Errorf("Extensions() = %v, want [.ex .exs]", exts)
+	}
+}
+
+func TestElixirParser_Directives(t *testing.T) {
+	result := parseTestElixir(t)
+
+	want := []struct{ path, alias string }{
+		{"Chat.Message", ""}, {"Chat.User", ""}, {"Chat.Store", "S"}, {"Enum", ""}, {"GenServer", ""}, {"Logger", ""},
+	}
+	if len(result.Imports) != len(want) {
+		t.Fatalf("imports = %+v, want %d", result.Imports, len(want))
+	}
+	for i, w := range want {
+		if imp := result.Imports[i]; imp.Path != w.path || imp.Alias != w.alias {
+			t.Errorf("import %d = %+v, want %s as %q", i, imp, w.path, w.alias)
+		}
+	}
+	if enum := result.Imports[3]; len(enum.Names) != 1 || enum.Names[0] != "map" || enum.IsWildcard {
+		t.Errorf("import Enum = %+v, want only map", enum)
+	}
+	if result.Package != "Chat" {
+		t.Errorf("Package = %q, want Chat", result.Package)
+	}
+}
+
+func TestElixirParser_ModulesAndFunctions(t *testing.T) {
+	result := parseTestElixir(t)
+
+	room := kotlinTopLevel(t, result, "Room")
+	if room.Kind != SymbolKindClass || room.Package != "Chat" || room.Signature != "defmodule Chat.Room" || room.DocComment != "A chat room." {
+		t.Errorf("Room = %v package %q %q doc %q", room.Kind, room.Package, room.Signature, room.DocComment)
+	}
+	if state := kotlinTopLevel(t, result, "State"); state.Package != "Chat.Room" {
+		t.Errorf("State package = %q, want Chat.Room", state.Package)
+	}
+
+	join := kotlinChild(t, room, "join")
+	if join.Kind != SymbolKindMethod || join.Receiver != "Room" || join.Signature != "def join(room, %User{} = user)" || join.DocComment != "Joins a room." {
+		t.Errorf("join = %v receiver %q %q doc %q", join.Kind, join.Receiver, join.Signature, join.DocComment)
+	}
+
+	// The two clauses of helper/1 are one symbol.
+	helpers := 0
+	for _, child := range room.Children {
+		if child.Name == "helper" {
+			helpers++
+			if child.Exported || child.Metadata.AccessModifier != "private" || child.EndLine != child.StartLine+1 {
+				t.Errorf("helper = exported %v %+v lines %d-%d", child.Exported, child.Metadata, child.StartLine, child.EndLine)
+			}
+		}
+	}
+	if helpers != 1 {
+		t.Errorf("found %d helper symbols, want 1", helpers)
+	}
+	if trace := kotlinChild(t, room, "trace"); trace.Signature != "defmacro trace(expr)" || !trace.Exported {
+		t.Errorf("trace = %q exported %v", trace.Signature, trace.Exported)
+	}
+	if fetch := kotlinChild(t, room, "fetch"); !hasCallSite(fetch.Calls, "Store", "get") {
+		t.Errorf("fetch calls = %+v, want Store.get", fetch.Calls)
+	}
+
+	// Private functions are dropped without IncludePrivate.
+	publicOnly := parseTestElixir(t, WithElixirParseOptions(ParseOptions{IncludePrivate: false}))
+	for _, child := range kotlinTopLevel(t, publicOnly, "Room").Children {
+		if child.Name == "helper" {
+			t.Error("defp helper included without IncludePrivate")
+		}
+	}
+}
+
+func TestElixirParser_ProtocolsAndImplementations(t *testing.T) {
+	result := parseTestElixir(t)
+
+	sendable := kotlinTopLevel(t, result, "Sendable")
+	if sendable.Kind != SymbolKindInterface || sendable.Package != "Chat" {
+		t.Errorf("Sendable = %v package %q", sendable.Kind, sendable.Package)
+	}
+	methods := sendable.Metadata.Methods
+	if len(methods) != 2 || methods[0].Name != "deliver" || methods[0].ParamCount != 2 || methods[1].Name != "id" {
+		t.Errorf("Sendable methods = %+v, want deliver/2 and id/1", methods)
+	}
+	if deliver := kotlinChild(t, sendable, "deliver"); deliver.DocComment != "Delivers a message." {
+		t.Errorf("deliver doc = %q", deliver.DocComment)
+	}
+
+	var impl *Symbol
+	for _, sym := range result.Symbols {
+		if sym.Name == "Message" && sym.Kind == SymbolKindClass {
+			impl = sym
+		}
+	}
+	if impl == nil {
+		t.Fatal("implementation of Sendable for Chat.Message not found")
+	}
+	if impl.Package != "Chat.Sendable" || impl.Signature != "defimpl Chat.Sendable, for: Chat.Message" {
+		t.Errorf("impl package %q signature %q", impl.Package, impl.Signature)
+	}
+	if len(impl.Metadata.Implements) != 1 || impl.Metadata.Implements[0] != "Sendable" {
+		t.Errorf("impl Implements = %v, want [Sendable]", impl.Metadata.Implements)
+	}
+	if deliver := kotlinChild(t, impl, "deliver"); deliver.Receiver != "Message" || !hasCallSite(deliver.Calls, "Room", "join") {
+		t.Errorf("impl deliver receiver %q calls %+v", deliver.Receiver, deliver.Calls)
+	}
+	if id := kotlinChild(t, impl, "id"); len(id.Calls) != 0 {
+		t.Errorf("msg.id is a field access, got calls %+v", id.Calls)
+	}
+}
+
+func TestElixirParser_CallSites(t *testing.T) {
+	result := parseTestElixir(t)
+	join := kotlinChild(t, kotlinTopLevel(t, result, "Room"), "join")
+
+	for _, want := range []struct{ receiver, target string }{
+		{"Message", "broadcast"}, {"Store", "put"}, {"Presence", "track"}, {"", "helper"},
+		{"", "format"}, {"ets", "insert"}, {"Enum", "map"}, {"Formatter", "format"},
+		{"Room", "leave"}, {"", "log"},
+	} {
+		if !hasCallSite(join.Calls, want.receiver, want.target) {
+			t.Errorf("join calls %+v missing %s.%s", join.Calls, want.receiver, want.target)
+		}
+	}
+	for _, call := range join.Calls {
+		if call.Target == "if" || call.Target == "admin" {
+			t.Errorf("unexpected call %+v", call)
+		}
+	}
+	helperCalls := 0
+	for _, call := range join.Calls {
+		if call.Target == "helper" {
+			helperCalls++
+		}
+	}
+	if helperCalls != 2 {
+		t.Errorf("join has %d calls to helper, want the call and the &helper/1 capture", helperCalls)
+	}
+}
+
+func TestElixirParser_SyntaxErrorReported(t *testing.T) {
+	content := []byte(`defmodule Good do
+end
+defmodule Bad do
+  def (
+`)
+	result, err := NewElixirParser().Parse(context.Background(), content, "bad.ex")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) == 0 {
+		t.Error("expected a syntax error to be reported")
+	}
+}
+
+func TestElixirParser_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewElixirParser().Parse(ctx, []byte(testElixirSource), "room.ex"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+// Elixir Tree-sitter Node Types
+//
+// This file documents the tree-sitter node types used by ElixirParser for symbol extraction.
+//
+// Reference: https://github.com/elixir-lang/tree-sitter-elixir
+
+// Node type constants for Elixir AST traversal.
+const (
+	// Top-level nodes
+	elixirNodeSource  = "source"
+	elixirNodeDoBlock = "do_block"
+
+	// Calls. Every definition (defmodule, def, alias, ...) is a call whose
+	// target is an identifier naming the macro.
+	elixirNodeCall      = "call"
+	elixirNodeArguments = "arguments"
+	elixirNodeDot       = "dot"
+
+	// Names and literals
+	elixirNodeAlias         = "alias"
+	elixirNodeIdentifier    = "identifier"
+	elixirNodeAtom          = "atom"
+	elixirNodeString        = "string"
+	elixirNodeQuotedContent = "quoted_content"
+	elixirNodeKeywords      = "keywords"
+	elixirNodePair          = "pair"
+	elixirNodeList          = "list"
+	elixirNodeTuple         = "tuple"
+
+	// Operators
+	elixirNodeUnaryOperator  = "unary_operator"
+	elixirNodeBinaryOperator = "binary_operator"
+
+	// Errors
+	elixirNodeERROR = "ERROR"
+)
+
+// ElixirNodeTypes maps symbol kinds to the tree-sitter node types that produce them.
+var ElixirNodeTypes = map[SymbolKind][]string{
+	SymbolKindClass:     {elixirNodeCall},
+	SymbolKindInterface: {elixirNodeCall},
+	SymbolKindFunction:  {elixirNodeCall},
+	SymbolKindMethod:    {elixirNodeCall},
+}
+
+// Elixir AST Structure Reference
+//
+// source
+// └── call                                  defmodule Chat.Room do ... end
+//     ├── target: identifier (defmodule | defprotocol | defimpl)
+//     ├── arguments
+//     │   ├── alias (Chat.Room)
+//     │   └── keywords                      defimpl P, for: Chat.Message
+//     │       └── pair
+//     │           ├── key: keyword ("for: ")
+//     │           └── value: alias | list
+//     └── do_block
+//         ├── unary_operator                @doc "..." / @moduledoc "..."
+//         │   ├── operator: "@"
+//         │   └── operand: call
+//         │       ├── target: identifier (doc | moduledoc)
+//         │       └── arguments
+//         │           └── string
+//         │               └── quoted_content
+//         ├── call                          alias Chat.Store, as: S
+//         │   ├── target: identifier (alias | import | use | require)
+//         │   └── arguments
+//         │       ├── alias | dot           Chat.{Message, User} is a dot
+//         │       │                         whose right is a tuple of aliases
+//         │       └── keywords              as: / only: / except:
+//         └── call                          def join(room, user) when ... do
+//             ├── target: identifier (def | defp | defmacro | defmacrop
+//             │                       | defguard | defguardp | defdelegate)
+//             ├── arguments
+//             │   ├── call                  join(room, user)
+//             │   │   ├── target: identifier
+//             │   │   └── arguments
+//             │   ├── identifier            def run do (no parameters)
+//             │   ├── binary_operator       head "when" guard
+//             │   │   ├── left: call | identifier
+//             │   │   └── right: <guard>
+//             │   └── keywords              , do: expr / to: Module
+//             └── do_block                  [absent for "do:" one-liners
+//                                           and protocol declarations]
+//
+// Calls:
+//
+// call                                      helper(x), Chat.Room.join(r), :ets.insert(t)
+// ├── target: identifier                    local call
+// │         | dot
+// │           ├── left: alias | atom | identifier | call
+// │           └── right: identifier         absent for fun.(x)
+// └── arguments                             [absent for Mod.fun and &Mod.fun/1]
+// unary_operator                            &helper/1 (local capture)
+// ├── operator: "&"
+// └── operand: binary_operator
+//     ├── left: identifier | call
+//     ├── operator: "/"
+//     └── right: integer
@@ -0,0 +1,316 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/build/constraint"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// knownGOOS and knownGOARCH mirror the lists go/build uses to recognize
+// platform-specific filename suffixes (foo_linux.go, foo_amd64.go).
+var (
+	knownGOOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true,
+		"freebsd": true, "hurd": true, "illumos": true, "ios": true,
+		"js": true, "linux": true, "nacl": true, "netbsd": true,
+		"openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	knownGOARCH = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true,
+		"armbe": true, "arm64": true, "arm64be": true, "loong64": true,
+		"mips": true, "mipsle": true, "mips64": true, "mips64le": true,
+		"mips64p32": true, "mips64p32le": true, "ppc": true, "ppc64": true,
+		"ppc64le": true, "riscv": true, "riscv64": true, "s390": true,
+		"s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+	}
+	// unixGOOS are the operating systems satisfying the "unix" build tag.
+	unixGOOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true,
+		"freebsd": true, "hurd": true, "illumos": true, "ios": true,
+		"linux": true, "netbsd": true, "openbsd": true, "solaris": true,
+	}
+)
+
+// KnownGOOS reports whether name is an operating system Go recognizes in
+// build constraints and filename suffixes.
+func KnownGOOS(name string) bool {
+	return knownGOOS[name]
+}
+
+// KnownGOARCH reports whether name is an architecture Go recognizes in
+// build constraints and filename suffixes.
+func KnownGOARCH(name string) bool {
+	return knownGOARCH[name]
+}
+
+// GoBuildConstraint returns the build constraint of a Go source file.
+//
+// Description:
+//
+//	Combines the //go:build line (or, for older files, the // +build
+//	lines) in the file header with the GOOS/GOARCH implied by the file
+//	name, the way go/build does: foo_linux.go, foo_arm64.go and
+//	foo_linux_arm64.go are constrained, and a trailing _test is ignored.
+//	The result is the constraint expression in //go:build syntax, e.g.
+//	"linux && (amd64 || arm64)".
+//
+// Inputs:
+//
+//	content - The file content.
+//	filePath - The file path. Only the base name is used.
+//
+// Outputs:
+//
+//	string - The constraint expression, or "" when the file builds on
+//	  every platform.
+//	error - Non-nil when a header constraint line is malformed. The
+//	  filename constraint is still returned.
+func GoBuildConstraint(content []byte, filePath string) (string, error) {
+	header, err := goHeaderConstraint(content)
+	name := goFilenameConstraint(filepath.Base(filePath))
+
+	switch {
+	case header != nil && name != nil:
+		return (&constraint.AndExpr{X: header, Y: name}).String(), err
+	case header != nil:
+		return header.String(), err
+	case name != nil:
+		return name.String(), err
+	default:
+		return "", err
+	}
+}
+
+// goHeaderConstraint parses the build constraint lines in the comment
+// block before the package clause. A //go:build line wins over
+// // +build lines; several // +build lines are ANDed.
+func goHeaderConstraint(content []byte) (constraint.Expr, error) {
+	var (
+		goBuild   constraint.Expr
+		plusBuild constraint.Expr
+		inBlock   bool
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if inBlock {
+			if strings.Contains(line, "*/") {
+				inBlock = false
+			}
+			continue
+		}
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "/*"):
+			inBlock = !strings.Contains(line[2:], "*/")
+			continue
+		case !strings.HasPrefix(line, "//"):
+			// First code line (normally the package clause) ends the header.
+			return headerResult(goBuild, plusBuild), nil
+		}
+
+		if !constraint.IsGoBuild(line) && !constraint.IsPlusBuild(line) {
+			continue
+		}
+		expr, err := constraint.Parse(line)
+		if err != nil {
+			return headerResult(goBuild, plusBuild), fmt.Errorf("build constraint %q: %w", line, err)
+		}
+		if constraint.IsGoBuild(line) {
+			if goBuild == nil {
+				goBuild = expr
+			}
+			continue
+		}
+		if plusBuild == nil {
+			plusBuild = expr
+		} else {
+			plusBuild = &constraint.AndExpr{X: plusBuild, Y: expr}
+		}
+	}
+	return headerResult(goBuild, plusBuild), nil
+}
+
+// headerResult prefers the //go:build expression over // +build lines.
+func headerResult(goBuild, plusBuild constraint.Expr) constraint.Expr {
+	if goBuild != nil {
+		return goBuild
+	}
+	return plusBuild
+}
+
+// goFilenameConstraint returns the GOOS/GOARCH constraint implied by a
+// file name, or nil when the name carries none.
+func goFilenameConstraint(base string) constraint.Expr {
+	name := strings.TrimSuffix(base, ".go")
+	// Like go/build, everything before the first underscore is the
+	// file's own name and never a platform suffix.
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return nil
+	}
+	parts := strings.Split(name[i:], "_")
+	if n := len(parts); n > 0 && parts[n-1] == "test" {
+		parts = parts[:n-1]
+	}
+	n := len(parts)
+	if n >= 2 && knownGOOS[parts[n-2]] && knownGOARCH[parts[n-1]] {
+		return &constraint.AndExpr{
+			X: &constraint.TagExpr{Tag: parts[n-2]},
+			Y: &constraint.TagExpr{Tag: parts[n-1]},
+		}
+	}
+	if n >= 1 && (knownGOOS[parts[n-1]] || knownGOARCH[parts[n-1]]) {
+		return &constraint.TagExpr{Tag: parts[n-1]}
+	}
+	return nil
+}
+
+// attachBuildConstraint records a file's build constraint on the parse
+// result and on every symbol declared in it.
+func attachBuildConstraint(result *ParseResult, expr string) {
+	if expr == "" {
+		return
+	}
+	result.BuildConstraint = expr
+	var mark func(symbols []*Symbol)
+	mark = func(symbols []*Symbol) {
+		for _, sym := range symbols {
+			if sym == nil {
+				continue
+			}
+			if sym.Metadata == nil {
+				sym.Metadata = &SymbolMetadata{}
+			}
+			sym.Metadata.BuildConstraint = expr
+			mark(sym.Children)
+		}
+	}
+	mark(result.Symbols)
+}
+
+// GoBuildProfile is one GOOS/GOARCH/tags combination a Go build targets.
+//
+// Description:
+//
+//	Used by the graph builder to decide which platform-specific files
+//	belong to a build. Matches evaluates constraints the way the go
+//	command does for that platform: GOOS and GOARCH are set, "unix" is
+//	set on Unix systems, android implies linux, ios implies darwin,
+//	illumos implies solaris, and the "gc" and "go1.N" release tags are
+//	always set. Other tags (cgo, integration, ...) are set only when
+//	listed in Tags.
+//
+// Thread Safety: Immutable after construction; safe for concurrent use.
+type GoBuildProfile struct {
+	// GOOS is the target operating system, e.g. "linux".
+	GOOS string `json:"goos"`
+
+	// GOARCH is the target architecture, e.g. "amd64".
+	GOARCH string `json:"goarch"`
+
+	// Tags are extra build tags, as passed to go build -tags.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// DefaultGoBuildProfile returns the profile of the running process.
+func DefaultGoBuildProfile() GoBuildProfile {
+	return GoBuildProfile{GOOS: runtime.GOOS, GOARCH: runtime.GOARCH}
+}
+
+// String returns the profile as "goos/goarch", followed by the tags in
+// brackets when there are any.
+func (p GoBuildProfile) String() string {
+	s := p.GOOS + "/" + p.GOARCH
+	if len(p.Tags) > 0 {
+		s += " [" + strings.Join(p.Tags, ",") + "]"
+	}
+	return s
+}
+
+// Matches reports whether a file with build constraint expr is part of a
+// build for this profile.
+//
+// Inputs:
+//
+//	expr - A constraint from GoBuildConstraint. "" matches every profile.
+//
+// Outputs:
+//
+//	bool - True if the file is included. Unparsable expressions are
+//	  treated as matching, so a bad constraint never hides a file.
+func (p GoBuildProfile) Matches(expr string) bool {
+	if expr == "" {
+		return true
+	}
+	parsed, err := constraint.Parse("//go:build " + expr)
+	if err != nil {
+		return true
+	}
+	return parsed.Eval(p.hasTag)
+}
+
+// hasTag reports whether tag is satisfied by the profile.
+func (p GoBuildProfile) hasTag(tag string) bool {
+	switch {
+	case tag == p.GOOS || tag == p.GOARCH:
+		return true
+	case tag == "unix":
+		return unixGOOS[p.GOOS]
+	case tag == "linux" && p.GOOS == "android",
+		tag == "darwin" && p.GOOS == "ios",
+		tag == "solaris" && p.GOOS == "illumos":
+		return true
+	case tag == "gc" || strings.HasPrefix(tag, "go1."):
+		return true
+	}
+	for _, t := range p.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// IsBuildVariant reports whether a and b are platform variants of one
+// declaration: Go symbols with the same name, kind and receiver in the
+// same package directory, declared in files with different build
+// constraints. Such pairs are never part of the same build, so their
+// similarity is expected rather than duplication.
+func IsBuildVariant(a, b *Symbol) bool {
+	if a == nil || b == nil || a == b {
+		return false
+	}
+	ca, cb := symbolBuildConstraint(a), symbolBuildConstraint(b)
+	if ca == cb {
+		return false
+	}
+	return a.Language == "go" && b.Language == "go" &&
+		a.Name == b.Name && a.Kind == b.Kind && a.Receiver == b.Receiver &&
+		filepath.Dir(a.FilePath) == filepath.Dir(b.FilePath)
+}
+
+// symbolBuildConstraint returns the build constraint recorded on sym.
+func symbolBuildConstraint(sym *Symbol) string {
+	if sym.Metadata == nil {
+		return ""
+	}
+	return sym.Metadata.BuildConstraint
+}
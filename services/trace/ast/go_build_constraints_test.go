@@ -0,0 +1,130 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGoBuildConstraint(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{"none", "pkg/file.go", "package pkg\n", "", false},
+		{"go build line", "pkg/file.go", "//go:build linux || darwin\n\npackage pkg\n", "linux || darwin", false},
+		{"plus build lines", "pkg/file.go", "// +build linux darwin\n// +build amd64\n\npackage pkg\n", "(linux || darwin) && amd64", false},
+		{"go build wins", "pkg/file.go", "//go:build windows\n// +build linux\n\npackage pkg\n", "windows", false},
+		{"after license", "pkg/file.go", "// Copyright x\n\n/* block\n//go:build ignored\n*/\n\n//go:build cgo\n\npackage pkg\n", "cgo", false},
+		{"body comment ignored", "pkg/file.go", "package pkg\n\n//go:build linux\n", "", false},
+		{"goos suffix", "pkg/file_windows.go", "package pkg\n", "windows", false},
+		{"goarch suffix", "pkg/file_arm64.go", "package pkg\n", "arm64", false},
+		{"goos goarch suffix", "pkg/file_linux_amd64_test.go", "package pkg\n", "linux && amd64", false},
+		{"bare platform name", "pkg/linux.go", "package pkg\n", "", false},
+		{"unknown suffix", "pkg/file_helper.go", "package pkg\n", "", false},
+		{"line and suffix", "pkg/file_linux.go", "//go:build !android\n\npackage pkg\n", "!android && linux", false},
+		{"malformed", "pkg/file_linux.go", "//go:build linux &&\n\npackage pkg\n", "linux", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := GoBuildConstraint([]byte(tc.content), tc.path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("constraint = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGoBuildProfile_Matches(t *testing.T) {
+	linux := GoBuildProfile{GOOS: "linux", GOARCH: "amd64"}
+	android := GoBuildProfile{GOOS: "android", GOARCH: "arm64", Tags: []string{"integration"}}
+	tests := []struct {
+		profile GoBuildProfile
+		expr    string
+		want    bool
+	}{
+		{linux, "", true},
+		{linux, "linux && amd64", true},
+		{linux, "windows", false},
+		{linux, "unix && !cgo", true},
+		{linux, "go1.21 && gc", true},
+		{linux, "integration", false},
+		{android, "integration", true},
+		{android, "linux && arm64", true},
+		{GoBuildProfile{GOOS: "windows", GOARCH: "amd64"}, "unix", false},
+		{linux, "((", true},
+	}
+	for _, tc := range tests {
+		if got := tc.profile.Matches(tc.expr); got != tc.want {
+			t.Errorf("%s.Matches(%q) = %v, want %v", tc.profile, tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestGoParser_BuildConstraintMetadata(t *testing.T) {
+	src := "//go:build linux\n\npackage fsys\n\ntype File struct{}\n\nfunc (f *File) Close() error { return nil }\n\nfunc Open() *File { return nil }\n"
+	result, err := NewGoParser().Parse(context.Background(), []byte(src), "fsys/open_amd64.go")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	const want = "linux && amd64"
+	if result.BuildConstraint != want {
+		t.Errorf("result constraint = %q, want %q", result.BuildConstraint, want)
+	}
+	for _, sym := range result.Symbols {
+		if sym.Metadata == nil || sym.Metadata.BuildConstraint != want {
+			t.Errorf("%s %s: metadata constraint missing", sym.Kind, sym.Name)
+		}
+	}
+
+	plain, err := NewGoParser().Parse(context.Background(), []byte("package fsys\n\nfunc Open() {}\n"), "fsys/open.go")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if plain.BuildConstraint != "" {
+		t.Errorf("unconstrained file has constraint %q", plain.BuildConstraint)
+	}
+}
+
+func TestIsBuildVariant(t *testing.T) {
+	sym := func(name, path, constraint string) *Symbol {
+		s := &Symbol{Name: name, Kind: SymbolKindFunction, FilePath: path, Language: "go"}
+		if constraint != "" {
+			s.Metadata = &SymbolMetadata{BuildConstraint: constraint}
+		}
+		return s
+	}
+	unix := sym("Open", "fsys/open_unix.go", "unix")
+	windows := sym("Open", "fsys/open_windows.go", "windows")
+
+	if !IsBuildVariant(unix, windows) {
+		t.Error("unix and windows Open should be variants")
+	}
+	if IsBuildVariant(unix, sym("Open", "fsys/open_bsd.go", "unix")) {
+		t.Error("same constraint is not a variant")
+	}
+	if IsBuildVariant(unix, sym("Open", "other/open_windows.go", "windows")) {
+		t.Error("different package directory is not a variant")
+	}
+	if IsBuildVariant(unix, sym("Create", "fsys/create_windows.go", "windows")) {
+		t.Error("different name is not a variant")
+	}
+	if IsBuildVariant(unix, unix) {
+		t.Error("a symbol is not its own variant")
+	}
+}
@@ -0,0 +1,440 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// goMajorVersionSegment matches the /vN suffix of a Go module path.
+var goMajorVersionSegment = regexp.MustCompile(`^v\d+$`)
+
+// goImportNames returns the names the imports of a Go file are referred
+// to by: the alias if set, otherwise the last path element without a
+// major version (gopkg.in/yaml.v3 -> yaml, example.com/lib/v2 -> lib).
+func goImportNames(imports []Import) map[string]bool {
+	names := make(map[string]bool, len(imports))
+	for _, imp := range imports {
+		if imp.Alias != "" {
+			if imp.Alias != "_" && imp.Alias != "." {
+				names[imp.Alias] = true
+			}
+			continue
+		}
+		name := path.Base(imp.Path)
+		if goMajorVersionSegment.MatchString(name) {
+			name = path.Base(path.Dir(imp.Path))
+		}
+		if dot := strings.Index(name, ".v"); dot > 0 {
+			name = name[:dot]
+		}
+		names[name] = true
+	}
+	return names
+}
+
+// goTypeName returns the name of a named type node without pointers or
+// type arguments, or "" for unnamed types.
+//
+// Example: *models.User -> models.User, Cache[K, V] -> Cache.
+func goTypeName(node *sitter.Node, content []byte) string {
+	for node != nil {
+		switch node.Type() {
+		case "type_identifier", "qualified_type":
+			return string(content[node.StartByte():node.EndByte()])
+		case "pointer_type", "parenthesized_type":
+			node = node.NamedChild(0)
+		case "generic_type":
+			node = node.ChildByFieldName("type")
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// goLocalTypes maps the variables of a function to their named types.
+//
+// Description:
+//
+//	Covers the receiver and parameters (paramLists), var declarations
+//	with a type, and short variable declarations and var declarations
+//	initialized from a composite literal (u := &User{}) or a
+//	constructor-like call (s := NewServer(), c, err := cache.NewLRU()).
+//	A name declared with different types in different scopes maps to "".
+//
+// Inputs:
+//
+//	bodyNode - The function body. May be nil.
+//	content - Source file bytes.
+//	paramLists - The receiver and parameter parameter_list nodes. Nil
+//	  entries are skipped.
+//
+// Outputs:
+//
+//	map[string]string - Variable name to type name.
+func goLocalTypes(bodyNode *sitter.Node, content []byte, paramLists ...*sitter.Node) map[string]string {
+	types := make(map[string]string)
+	declare := func(nameNode *sitter.Node, typeName string) {
+		if nameNode == nil || nameNode.Type() != "identifier" || typeName == "" {
+			return
+		}
+		name := string(content[nameNode.StartByte():nameNode.EndByte()])
+		if name == "_" {
+			return
+		}
+		if prev, ok := types[name]; ok && prev != typeName {
+			types[name] = ""
+			return
+		}
+		types[name] = typeName
+	}
+
+	for _, list := range paramLists {
+		if list == nil {
+			continue
+		}
+		for i := 0; i < int(list.NamedChildCount()); i++ {
+			param := list.NamedChild(i)
+			if param == nil || param.Type() != "parameter_declaration" {
+				continue
+			}
+			typeName := goTypeName(param.ChildByFieldName("type"), content)
+			for j := 0; j < int(param.NamedChildCount()); j++ {
+				declare(param.NamedChild(j), typeName)
+			}
+		}
+	}
+
+	if bodyNode == nil {
+		return types
+	}
+	stack := []*sitter.Node{bodyNode}
+	for depth := 0; len(stack) > 0 && depth < maxGoFieldAccessNodes; depth++ {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		switch node.Type() {
+		case "var_spec":
+			typeName := goTypeName(node.ChildByFieldName("type"), content)
+			values := goExpressionList(node.ChildByFieldName("value"))
+			var names []*sitter.Node
+			for i := 0; i < int(node.NamedChildCount()); i++ {
+				if child := node.NamedChild(i); child.Type() == "identifier" {
+					names = append(names, child)
+				}
+			}
+			for i, name := range names {
+				switch {
+				case typeName != "":
+					declare(name, typeName)
+				case len(values) == len(names):
+					declare(name, goValueTypeName(values[i], content))
+				case i == 0 && len(values) == 1:
+					declare(name, goConstructorTypeName(values[0], content))
+				}
+			}
+		case "short_var_declaration":
+			left := node.ChildByFieldName("left")
+			values := goExpressionList(node.ChildByFieldName("right"))
+			if left == nil {
+				break
+			}
+			if int(left.NamedChildCount()) == len(values) {
+				for i := 0; i < int(left.NamedChildCount()); i++ {
+					declare(left.NamedChild(i), goValueTypeName(values[i], content))
+				}
+			} else if len(values) == 1 {
+				// x, err := NewX(): the first result is the constructed value.
+				declare(left.NamedChild(0), goConstructorTypeName(values[0], content))
+			}
+		case "func_literal":
+			// Closures may shadow names; their bodies are still scanned
+			// for accesses but not for declarations.
+			continue
+		}
+		for i := int(node.NamedChildCount()) - 1; i >= 0; i-- {
+			if child := node.NamedChild(i); child != nil {
+				stack = append(stack, child)
+			}
+		}
+	}
+	return types
+}
+
+// goLiteralTypeName returns the type name of a composite literal
+// expression (User{} or &User{}), or "" for any other expression.
+// An expression_list value uses its first element.
+func goLiteralTypeName(node *sitter.Node, content []byte) string {
+	if node != nil && node.Type() == "expression_list" {
+		node = node.NamedChild(0)
+	}
+	if node != nil && node.Type() == "unary_expression" {
+		node = node.ChildByFieldName("operand")
+	}
+	if node == nil || node.Type() != "composite_literal" {
+		return ""
+	}
+	return goTypeName(node.ChildByFieldName("type"), content)
+}
+
+// goConstructorTypeName returns the type a constructor-like call is
+// assumed to return, or "" for any other expression. A call of a
+// function named New<Type> returns Type, qualified by the package when
+// the call is (NewServer() -> Server, http.NewRequest() -> http.Request).
+func goConstructorTypeName(node *sitter.Node, content []byte) string {
+	if node == nil || node.Type() != "call_expression" {
+		return ""
+	}
+	fn := node.ChildByFieldName("function")
+	if fn == nil {
+		return ""
+	}
+	qualifier := ""
+	if fn.Type() == "selector_expression" {
+		operand := fn.ChildByFieldName("operand")
+		if operand == nil || operand.Type() != "identifier" {
+			return ""
+		}
+		qualifier = string(content[operand.StartByte():operand.EndByte()]) + "."
+		fn = fn.ChildByFieldName("field")
+	}
+	if fn == nil || (fn.Type() != "identifier" && fn.Type() != "field_identifier") {
+		return ""
+	}
+	name := string(content[fn.StartByte():fn.EndByte()])
+	if len(name) <= 3 || !strings.HasPrefix(name, "New") || name[3] < 'A' || name[3] > 'Z' {
+		return ""
+	}
+	return qualifier + name[3:]
+}
+
+// goValueTypeName returns the type of a composite literal or
+// constructor-like call expression, or "".
+func goValueTypeName(node *sitter.Node, content []byte) string {
+	if typeName := goLiteralTypeName(node, content); typeName != "" {
+		return typeName
+	}
+	return goConstructorTypeName(node, content)
+}
+
+// goExpressionList returns the elements of an expression_list node, or
+// the node itself for a single expression. Nil yields nil.
+func goExpressionList(node *sitter.Node) []*sitter.Node {
+	if node == nil {
+		return nil
+	}
+	if node.Type() != "expression_list" {
+		return []*sitter.Node{node}
+	}
+	exprs := make([]*sitter.Node, 0, node.NamedChildCount())
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if child := node.NamedChild(i); child != nil {
+			exprs = append(exprs, child)
+		}
+	}
+	return exprs
+}
+
+// Bounds on the syntax tree walk of one function body when collecting
+// field accesses and local types.
+const (
+	maxGoFieldAccessNodes = 100_000
+	maxGoFieldAccessDepth = 200
+)
+
+// extractGoFieldAccesses collects the struct field reads and writes in a
+// Go function body.
+//
+// Description:
+//
+//	Records selector expressions that are not package-qualified names or
+//	method calls, and the keys of keyed composite literals of named types.
+//	A selector is a write when it is the target of an assignment or an
+//	increment, when its address is taken, or when an element of it is
+//	assigned (u.Tags[i] = t). Accesses are deduplicated by field,
+//	receiver and direction, keeping the first location.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	bodyNode - The function body. May be nil.
+//	content - Source file bytes.
+//	filePath - Relative path for Location.
+//	importNames - Names of the file's imports; selectors on them are
+//	  package-qualified identifiers, not fields.
+//	localTypes - Variable types from goLocalTypes.
+//
+// Outputs:
+//
+//	[]FieldAccess - The accesses, at most MaxFieldAccessesPerSymbol, in
+//	  source order. Nil if none.
+//
+// Limitations:
+//   - No type checking: the receiver type is known only for variables
+//     declared with a named type or from a composite literal.
+//   - Fields promoted from embedded structs are recorded under the
+//     selector's name and resolved like any other.
+//
+// Thread Safety: Safe for concurrent use.
+func extractGoFieldAccesses(
+	ctx context.Context,
+	bodyNode *sitter.Node,
+	content []byte,
+	filePath string,
+	importNames map[string]bool,
+	localTypes map[string]string,
+) []FieldAccess {
+	if bodyNode == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	type accessKey struct {
+		field, receiver string
+		write           bool
+	}
+	var accesses []FieldAccess
+	seen := make(map[accessKey]bool)
+	add := func(access FieldAccess, node *sitter.Node) {
+		key := accessKey{access.Field, access.Receiver, access.Write}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		access.Location = Location{
+			FilePath:  filePath,
+			StartLine: int(node.StartPoint().Row) + 1,
+			EndLine:   int(node.EndPoint().Row) + 1,
+			StartCol:  int(node.StartPoint().Column),
+			EndCol:    int(node.EndPoint().Column),
+		}
+		accesses = append(accesses, access)
+	}
+
+	type stackEntry struct {
+		node  *sitter.Node
+		depth int
+		write bool // node is an assignment target or has its address taken
+	}
+	stack := []stackEntry{{node: bodyNode}}
+	push := func(node *sitter.Node, depth int, write bool) {
+		if node != nil {
+			stack = append(stack, stackEntry{node: node, depth: depth, write: write})
+		}
+	}
+
+	nodeCount := 0
+	for len(stack) > 0 && len(accesses) < MaxFieldAccessesPerSymbol && nodeCount < maxGoFieldAccessNodes {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := entry.node
+		if entry.depth > maxGoFieldAccessDepth {
+			continue
+		}
+		nodeCount++
+		if nodeCount%100 == 0 && ctx.Err() != nil {
+			return accesses
+		}
+		next := entry.depth + 1
+
+		switch node.Type() {
+		case "selector_expression":
+			operand, field := node.ChildByFieldName("operand"), node.ChildByFieldName("field")
+			if operand == nil || field == nil {
+				continue
+			}
+			receiver := string(content[operand.StartByte():operand.EndByte()])
+			isCallee := false
+			if parent := node.Parent(); parent != nil && parent.Type() == "call_expression" {
+				if fn := parent.ChildByFieldName("function"); fn != nil && fn.Equal(node) {
+					isCallee = true
+				}
+			}
+			qualified := operand.Type() == "identifier" && importNames[receiver]
+			if !isCallee && !qualified {
+				access := FieldAccess{
+					Field:    string(content[field.StartByte():field.EndByte()]),
+					Receiver: receiver,
+					Write:    entry.write,
+				}
+				if operand.Type() == "identifier" {
+					access.ReceiverType = localTypes[receiver]
+				}
+				add(access, node)
+			}
+			push(operand, next, false)
+			continue
+
+		case "assignment_statement":
+			push(node.ChildByFieldName("right"), next, false)
+			push(node.ChildByFieldName("left"), next, true)
+			continue
+
+		case "inc_statement", "dec_statement":
+			push(node.NamedChild(0), next, true)
+			continue
+
+		case "unary_expression":
+			if op := node.ChildByFieldName("operator"); op != nil && op.Type() == "&" {
+				push(node.ChildByFieldName("operand"), next, true)
+				continue
+			}
+
+		case "index_expression":
+			push(node.ChildByFieldName("index"), next, false)
+			push(node.ChildByFieldName("operand"), next, entry.write)
+			continue
+
+		case "composite_literal":
+			if typeName := goTypeName(node.ChildByFieldName("type"), content); typeName != "" {
+				addGoLiteralFields(node.ChildByFieldName("body"), content, typeName, func(a FieldAccess, n *sitter.Node) { add(a, n) })
+			}
+		}
+
+		// Write targets carry through lists and parentheses only.
+		write := entry.write && (node.Type() == "expression_list" || node.Type() == "parenthesized_expression")
+		for i := int(node.NamedChildCount()) - 1; i >= 0; i-- {
+			push(node.NamedChild(i), next, write)
+		}
+	}
+
+	if len(accesses) == 0 {
+		return nil
+	}
+	return accesses
+}
+
+// addGoLiteralFields records the keys of a keyed composite literal body as
+// writes of typeName's fields.
+func addGoLiteralFields(body *sitter.Node, content []byte, typeName string, add func(FieldAccess, *sitter.Node)) {
+	if body == nil {
+		return
+	}
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		elem := body.NamedChild(i)
+		if elem == nil || elem.Type() != "keyed_element" || elem.NamedChildCount() < 2 {
+			continue
+		}
+		key := goLiteralElement(elem.NamedChild(0))
+		if key == nil || (key.Type() != "identifier" && key.Type() != "field_identifier") {
+			continue
+		}
+		add(FieldAccess{
+			Field:        string(content[key.StartByte():key.EndByte()]),
+			ReceiverType: typeName,
+			Write:        true,
+		}, key)
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGoParser_FieldAccesses(t *testing.T) {
+	src := `package users
+
+import (
+	"encoding/json"
+	yaml "gopkg.in/yaml.v3"
+)
+
+type User struct {
+	ID    string ` + "`json:\"id\" db:\"user_id\"`" + `
+	Email string ` + "`json:\"email,omitempty\"`" + `
+	Tags  []string
+	Count int
+}
+
+func (u *User) Rename(email string) {
+	u.Email = email
+	u.Count++
+	u.Tags[0] = "renamed"
+	u.Save()
+}
+
+func Export(in *User) ([]byte, error) {
+	out := &User{ID: in.ID}
+	parse(&out.Email)
+	_ = yaml.Node{}
+	return json.Marshal(out.Email)
+}
+`
+	result, err := NewGoParser().Parse(context.Background(), []byte(src), "users/user.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syms := make(map[string]*Symbol)
+	for _, sym := range result.Symbols {
+		syms[sym.Name] = sym
+	}
+
+	type key struct {
+		field, receiver, receiverType string
+		write                         bool
+	}
+	collect := func(name string) map[key]bool {
+		t.Helper()
+		sym := syms[name]
+		if sym == nil {
+			t.Fatalf("symbol %s not found", name)
+		}
+		got := make(map[key]bool)
+		for _, a := range sym.FieldAccesses {
+			got[key{a.Field, a.Receiver, a.ReceiverType, a.Write}] = true
+		}
+		return got
+	}
+
+	rename := collect("Rename")
+	for _, want := range []key{
+		{"Email", "u", "User", true},
+		{"Count", "u", "User", true},
+		{"Tags", "u", "User", true},
+	} {
+		if !rename[want] {
+			t.Errorf("Rename missing %+v, got %v", want, rename)
+		}
+	}
+	if len(rename) != 3 {
+		t.Errorf("Rename accesses = %v, want 3 (method call u.Save excluded)", rename)
+	}
+
+	export := collect("Export")
+	for _, want := range []key{
+		{"ID", "", "User", true},        // keyed literal element
+		{"ID", "in", "User", false},     // read of the parameter
+		{"Email", "out", "User", true},  // address taken
+		{"Email", "out", "User", false}, // argument
+	} {
+		if !export[want] {
+			t.Errorf("Export missing %+v, got %v", want, export)
+		}
+	}
+	for k := range export {
+		if k.receiver == "json" || k.receiver == "yaml" {
+			t.Errorf("package-qualified name recorded as field access: %+v", k)
+		}
+	}
+
+	var user *Symbol
+	for _, sym := range result.Symbols {
+		if sym.Name == "User" && sym.Kind == SymbolKindStruct {
+			user = sym
+		}
+	}
+	if user == nil {
+		t.Fatal("User struct not found")
+	}
+	tags := make(map[string]string)
+	for _, field := range user.Children {
+		if field.Metadata != nil {
+			tags[field.Name] = field.Metadata.StructTag
+		}
+	}
+	if tags["ID"] != `json:"id" db:"user_id"` || tags["Email"] != `json:"email,omitempty"` || tags["Tags"] != "" {
+		t.Errorf("struct tags = %v", tags)
+	}
+}
+
+func TestGoImportNames(t *testing.T) {
+	names := goImportNames([]Import{
+		{Path: "encoding/json"},
+		{Path: "gopkg.in/yaml.v3"},
+		{Path: "github.com/jackc/pgx/v5"},
+		{Path: "github.com/x/long", Alias: "short"},
+		{Path: "embed", Alias: "_"},
+	})
+	for _, want := range []string{"json", "yaml", "pgx", "short"} {
+		if !names[want] {
+			t.Errorf("missing %s in %v", want, names)
+		}
+	}
+	if names["_"] || names["long"] || names["v5"] {
+		t.Errorf("unexpected names in %v", names)
+	}
+}
+
+func TestGoParser_LocalTypes(t *testing.T) {
+	src := `package server
+
+import "net/http"
+
+func (s *Server) Handle(w http.ResponseWriter, r *http.Request) {
+	var cfg Config
+	var cache = NewCache()
+	store, err := NewStore(cfg)
+	req, _ := http.NewRequest("GET", "/", nil)
+	u := &User{}
+	n, other := 1, NewThing()
+	v := compute()
+	var a, b = Alpha{}, NewBeta()
+	Newsletter := Newsletter()
+	_, _, _, _, _, _, _, _, _, _ = store, err, req, u, n, other, v, a, b, Newsletter
+	_ = cache
+}
+
+func Empty() {}
+`
+	result, err := NewGoParser().Parse(context.Background(), []byte(src), "server/server.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var handle, empty *Symbol
+	for _, sym := range result.Symbols {
+		switch sym.Name {
+		case "Handle":
+			handle = sym
+		case "Empty":
+			empty = sym
+		}
+	}
+	if handle == nil || handle.Metadata == nil {
+		t.Fatalf("Handle has no metadata: %+v", handle)
+	}
+	want := map[string]string{
+		"s":     "Server",
+		"w":     "http.ResponseWriter",
+		"r":     "http.Request",
+		"cfg":   "Config",
+		"cache": "Cache",
+		"store": "Store",
+		"req":   "http.Request",
+		"u":     "User",
+		"other": "Thing",
+		"a":     "Alpha",
+		"b":     "Beta",
+	}
+	got := handle.Metadata.LocalTypes
+	for name, typeName := range want {
+		if got[name] != typeName {
+			t.Errorf("LocalTypes[%s] = %q, want %q", name, got[name], typeName)
+		}
+	}
+	for _, name := range []string{"err", "n", "v", "Newsletter", "_"} {
+		if typeName, ok := got[name]; ok {
+			t.Errorf("LocalTypes[%s] = %q, want no entry", name, typeName)
+		}
+	}
+	if empty != nil && empty.Metadata != nil && empty.Metadata.LocalTypes != nil {
+		t.Errorf("Empty has local types %v", empty.Metadata.LocalTypes)
+	}
+}
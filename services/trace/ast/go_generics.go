@@ -0,0 +1,390 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// goTypeParams holds the type parameter list of a generic Go declaration.
+//
+// Names and Constraints are parallel: Constraints[i] is the constraint source
+// text for Names[i] (e.g., "comparable", "~[]E", "Number"). ConstraintRefs
+// lists the non-builtin named types used by the constraints so the builder
+// can create REFERENCES edges to constraint interfaces.
+type goTypeParams struct {
+	Names          []string
+	Constraints    []string
+	ConstraintRefs []TypeReference
+}
+
+// set returns the type parameter names as a lookup set.
+func (tp goTypeParams) set() map[string]bool {
+	if len(tp.Names) == 0 {
+		return nil
+	}
+	names := make(map[string]bool, len(tp.Names))
+	for _, name := range tp.Names {
+		names[name] = true
+	}
+	return names
+}
+
+// extractGoTypeParams parses a tree-sitter type_parameter_list node.
+//
+// Description:
+//
+//	Each type_parameter_declaration contains one or more identifiers followed
+//	by a type_constraint (`[K comparable, V any]`, `[K, V any]`). Every
+//	identifier receives the constraint text of its declaration. Named types
+//	inside constraints that are neither builtins nor type parameters
+//	themselves are returned as TypeReferences.
+//
+// Inputs:
+//   - listNode: A tree-sitter "type_parameter_list" node. May be nil.
+//   - content: Source file bytes.
+//   - filePath: Relative path for Location.
+//
+// Outputs:
+//   - goTypeParams: Parameter names, constraint texts and constraint refs.
+//     Zero value if listNode is nil.
+func extractGoTypeParams(listNode *sitter.Node, content []byte, filePath string) goTypeParams {
+	var tp goTypeParams
+	if listNode == nil {
+		return tp
+	}
+
+	var constraintNodes []*sitter.Node
+	for i := 0; i < int(listNode.ChildCount()); i++ {
+		decl := listNode.Child(i)
+		if decl == nil || decl.Type() != "type_parameter_declaration" {
+			continue
+		}
+
+		var names []string
+		var constraint string
+		for j := 0; j < int(decl.ChildCount()); j++ {
+			child := decl.Child(j)
+			if child == nil {
+				continue
+			}
+			switch child.Type() {
+			case "identifier":
+				names = append(names, string(content[child.StartByte():child.EndByte()]))
+			case "type_constraint":
+				constraint = string(content[child.StartByte():child.EndByte()])
+				constraintNodes = append(constraintNodes, child)
+			}
+		}
+
+		for _, name := range names {
+			tp.Names = append(tp.Names, name)
+			tp.Constraints = append(tp.Constraints, constraint)
+		}
+	}
+
+	// Constraints may mention sibling parameters (`S ~[]E, E Number`), so refs
+	// are filtered after all names are known.
+	params := tp.set()
+	for _, node := range constraintNodes {
+		tp.ConstraintRefs = append(tp.ConstraintRefs,
+			filterGoTypeParamRefs(extractGoReturnTypeRefs(node, content, filePath), params)...)
+	}
+
+	return tp
+}
+
+// extractGoReceiverTypeParams returns the type parameter names declared by a
+// generic method receiver.
+//
+// Description:
+//
+//	For `func (c *Cache[K, V]) Get(k K) V`, the receiver parameter_list holds
+//	a generic_type whose type_arguments name the receiver's type parameters.
+//	Returns ["K", "V"]. Returns nil for non-generic receivers.
+//
+// Inputs:
+//   - receiverNode: The receiver "parameter_list" node. May be nil.
+//   - content: Source file bytes.
+//
+// Outputs:
+//   - []string: Receiver type parameter names in declaration order.
+func extractGoReceiverTypeParams(receiverNode *sitter.Node, content []byte) []string {
+	if receiverNode == nil {
+		return nil
+	}
+
+	var generic *sitter.Node
+	var find func(node *sitter.Node)
+	find = func(node *sitter.Node) {
+		if node == nil || generic != nil {
+			return
+		}
+		if node.Type() == "generic_type" {
+			generic = node
+			return
+		}
+		for i := 0; i < int(node.ChildCount()); i++ {
+			find(node.Child(i))
+		}
+	}
+	find(receiverNode)
+
+	if generic == nil {
+		return nil
+	}
+	args := generic.ChildByFieldName("type_arguments")
+	if args == nil {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < int(args.ChildCount()); i++ {
+		elem := args.Child(i)
+		if elem == nil || elem.Type() != "type_elem" {
+			continue
+		}
+		for j := 0; j < int(elem.ChildCount()); j++ {
+			ident := elem.Child(j)
+			if ident != nil && ident.Type() == "type_identifier" {
+				names = append(names, string(content[ident.StartByte():ident.EndByte()]))
+			}
+		}
+	}
+	return names
+}
+
+// filterGoTypeParamRefs drops TypeReferences that name a type parameter.
+//
+// Type parameters like T are placeholders, not types declared anywhere in
+// the project; leaving them in would make the builder resolve `T` against
+// any unrelated symbol that happens to be called T.
+func filterGoTypeParamRefs(refs []TypeReference, params map[string]bool) []TypeReference {
+	if len(params) == 0 || len(refs) == 0 {
+		return refs
+	}
+	filtered := refs[:0]
+	for _, ref := range refs {
+		if !params[ref.Name] {
+			filtered = append(filtered, ref)
+		}
+	}
+	return filtered
+}
+
+// extractGoTypeArguments collects the named types used as type arguments of
+// generic instantiations under the given nodes.
+//
+// Description:
+//
+//	Walks each node looking for type_arguments (e.g., `Map[string, User](...)`,
+//	`Identity[Order](o)`, `Repository[User]`) and returns the non-builtin type
+//	names they contain, excluding the enclosing declaration's own type
+//	parameters. The result feeds SymbolMetadata.TypeArguments, which the
+//	builder's extractTypeArgEdges turns into REFERENCES edges.
+//
+// Inputs:
+//   - ctx: Context for cancellation.
+//   - content: Source file bytes.
+//   - params: Type parameter names of the enclosing declaration. May be nil.
+//   - nodes: Subtrees to walk (body, parameter lists, ...). Nil entries are skipped.
+//
+// Outputs:
+//   - []string: Deduplicated type argument names in source order.
+//
+// Thread Safety: Safe for concurrent use.
+func extractGoTypeArguments(ctx context.Context, content []byte, params map[string]bool, nodes ...*sitter.Node) []string {
+	var typeArgs []string
+	seen := make(map[string]bool)
+
+	type stackEntry struct {
+		node   *sitter.Node
+		depth  int
+		inArgs bool
+	}
+
+	stack := make([]stackEntry, 0, 64)
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if nodes[i] != nil {
+			stack = append(stack, stackEntry{node: nodes[i]})
+		}
+	}
+
+	nodeCount := 0
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := entry.node
+		if node == nil || entry.depth > MaxCallExpressionDepth {
+			continue
+		}
+
+		nodeCount++
+		if nodeCount%100 == 0 && ctx.Err() != nil {
+			return typeArgs
+		}
+		if len(typeArgs) >= MaxTypeReferencesPerSymbol {
+			return typeArgs
+		}
+
+		inArgs := entry.inArgs || node.Type() == "type_arguments"
+		if inArgs && node.Type() == "type_identifier" {
+			name := string(content[node.StartByte():node.EndByte()])
+			if name != "" && !goTypeSkipList[name] && !params[name] && !seen[name] {
+				seen[name] = true
+				typeArgs = append(typeArgs, name)
+			}
+			continue
+		}
+
+		for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+			child := node.Child(i)
+			if child == nil {
+				continue
+			}
+			// The package qualifier of pkg.Type is not a type argument.
+			if child.Type() == "package_identifier" {
+				continue
+			}
+			stack = append(stack, stackEntry{node: child, depth: entry.depth + 1, inArgs: inArgs})
+		}
+	}
+
+	return typeArgs
+}
+
+// stripGoTypeArgs removes an instantiation or type parameter suffix from a
+// type expression: "Cache[K, V]" → "Cache", "*Repo[T]" → "*Repo".
+func stripGoTypeArgs(typeExpr string) string {
+	if idx := strings.Index(typeExpr, "["); idx > 0 {
+		return strings.TrimSpace(typeExpr[:idx])
+	}
+	return typeExpr
+}
+
+// extractGenericTypeName returns the base type name of a generic_type node.
+//
+// Description:
+//
+//	`Reader[T]` → "Reader", `pkg.Reader[T]` → "Reader". Used for embedded
+//	generic types in structs and interfaces so the builder sees the declared
+//	type name rather than the instantiation text.
+func (p *GoParser) extractGenericTypeName(node *sitter.Node, content []byte) string {
+	if node == nil {
+		return ""
+	}
+	base := node.ChildByFieldName("type")
+	if base == nil {
+		return ""
+	}
+	switch base.Type() {
+	case "type_identifier":
+		if int(base.EndByte()) > len(content) {
+			return ""
+		}
+		return string(content[base.StartByte():base.EndByte()])
+	case "qualified_type":
+		return p.extractQualifiedTypeName(base, content)
+	}
+	return ""
+}
+
+// extractGenericInstantiationCallSite extracts a call site from an explicit
+// single-argument generic instantiation.
+//
+// Description:
+//
+//	tree-sitter cannot distinguish `Identity[User](u)` from a conversion to a
+//	generic type, so it parses it as a type_conversion_expression whose type
+//	is a generic_type. Multi-argument instantiations (`Map[K, V](...)`) are
+//	ordinary call_expressions and handled by extractSingleCallSite. Qualified
+//	forms (`pkg.Do[Item](x)`) are reported like selector calls: Receiver is
+//	the package and IsMethod is true, leaving resolution to the builder.
+//
+// Inputs:
+//   - node: A type_conversion_expression node. Must not be nil.
+//   - content: Source file bytes.
+//   - filePath: Path to the source file for location data.
+//
+// Outputs:
+//   - *CallSite: The call site, or nil if the conversion is not a generic instantiation.
+//
+// Thread Safety: This function is safe for concurrent use.
+func (p *GoParser) extractGenericInstantiationCallSite(node *sitter.Node, content []byte, filePath string) *CallSite {
+	if node == nil || node.Type() != "type_conversion_expression" {
+		return nil
+	}
+	typeNode := node.ChildByFieldName("type")
+	if typeNode == nil || typeNode.Type() != "generic_type" {
+		return nil
+	}
+	base := typeNode.ChildByFieldName("type")
+	if base == nil {
+		return nil
+	}
+
+	call := &CallSite{
+		Location: Location{
+			FilePath:  filePath,
+			StartLine: int(node.StartPoint().Row) + 1,
+			EndLine:   int(node.EndPoint().Row) + 1,
+			StartCol:  int(node.StartPoint().Column),
+			EndCol:    int(node.EndPoint().Column),
+		},
+	}
+
+	switch base.Type() {
+	case "type_identifier":
+		call.Target = string(content[base.StartByte():base.EndByte()])
+	case "qualified_type":
+		if name := base.ChildByFieldName("name"); name != nil {
+			call.Target = string(content[name.StartByte():name.EndByte()])
+		}
+		if pkg := base.ChildByFieldName("package"); pkg != nil {
+			call.Receiver = string(content[pkg.StartByte():pkg.EndByte()])
+			call.IsMethod = true
+		}
+	}
+
+	if call.Target == "" {
+		return nil
+	}
+	return call
+}
+
+// isGoTypeSetElem reports whether an interface type_elem is a constraint type
+// set (`~int | ~float64`, `int | string`, `~string`) rather than an embedded
+// interface.
+func isGoTypeSetElem(elem *sitter.Node) bool {
+	if elem == nil {
+		return false
+	}
+	if elem.NamedChildCount() > 1 {
+		return true
+	}
+	for i := 0; i < int(elem.NamedChildCount()); i++ {
+		if elem.NamedChild(i).Type() == "negated_type" {
+			return true
+		}
+	}
+	return false
+}
+
+// isGoConstraintBuiltin reports whether name is a predeclared identifier that
+// may appear as a type_elem in a constraint interface without contributing
+// methods. error is excluded: embedding it does add Error().
+func isGoConstraintBuiltin(name string) bool {
+	return name != "error" && goTypeSkipList[name]
+}
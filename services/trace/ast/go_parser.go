@@ -17,6 +17,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -24,6 +25,8 @@ import (
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/golang"
 	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // File size constants for security validation.
@@ -203,7 +206,7 @@ func (p *GoParser) Parse(ctx context.Context, content []byte, filePath string) (
 	// Log warning for large files
 	if len(content) > WarnFileSize {
 		slog.Warn("parsing large file",
-			slog.String("file", filePath),
+			telemetry.SensitiveLog("file", filePath),
 			slog.Int("size_bytes", len(content)))
 	}
 
@@ -274,12 +277,24 @@ func (p *GoParser) Parse(ctx context.Context, content []byte, filePath string) (
 	p.extractTypes(rootNode, content, filePath, result)
 
 	// Extract top-level variables and constants
-	p.extractVariables(rootNode, content, filePath, result)
-	p.extractConstants(rootNode, content, filePath, result)
+	p.extractVariables(ctx, rootNode, content, filePath, result)
+	p.extractConstants(ctx, rootNode, content, filePath, result)
 
 	// Associate methods with their receiver types for interface implementation detection (GR-40)
 	p.associateMethodsWithTypes(result)
 
+	// Build constraints (//go:build lines and _GOOS/_GOARCH file suffixes)
+	buildConstraint, constraintErr := GoBuildConstraint(content, filePath)
+	if constraintErr != nil {
+		result.Errors = append(result.Errors, constraintErr.Error())
+	}
+	attachBuildConstraint(result, buildConstraint)
+
+	// Normalized body tokens for clone detection
+	if p.parseOptions.ExtractBodyTokens {
+		attachBodyTokens(rootNode, content, result.Symbols)
+	}
+
 	// Validate result before returning
 	if err := result.Validate(); err != nil {
 		recordParseMetrics(ctx, "go", time.Since(start), 0, false)
@@ -459,12 +474,16 @@ func (p *GoParser) processFunctionDecl(ctx context.Context, node *sitter.Node, c
 	var bodyNode *sitter.Node
 	var paramListNode *sitter.Node  // IT-06 Bug 9: track for type ref extraction
 	var returnTypeNode *sitter.Node // IT-06 Bug 9: track standalone return type node
+	var typeParams goTypeParams
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		switch child.Type() {
 		case "identifier":
 			name = string(content[child.StartByte():child.EndByte()])
+		case "type_parameter_list":
+			// Generic function: func Map[K comparable, V any](...)
+			typeParams = extractGoTypeParams(child, content, filePath)
 		case "parameter_list":
 			// First parameter_list is params, subsequent ones are return types
 			plist := string(content[child.StartByte():child.EndByte()])
@@ -475,7 +494,7 @@ func (p *GoParser) processFunctionDecl(ctx context.Context, node *sitter.Node, c
 				returns = plist
 				returnTypeNode = child // IT-06 Bug 9: return parameter_list
 			}
-		case "type_identifier", "pointer_type", "slice_type", "map_type", "channel_type", "qualified_type", "interface_type", "struct_type", "function_type":
+		case "type_identifier", "pointer_type", "slice_type", "map_type", "channel_type", "qualified_type", "interface_type", "struct_type", "function_type", "generic_type":
 			returns = string(content[child.StartByte():child.EndByte()])
 			returnTypeNode = child // IT-06 Bug 9
 		case "block":
@@ -522,6 +541,11 @@ func (p *GoParser) processFunctionDecl(ctx context.Context, node *sitter.Node, c
 	// GR-41: Extract call sites from function body
 	if bodyNode != nil {
 		sym.Calls = p.extractCallSites(ctx, bodyNode, content, filePath)
+		sym.StructLiterals = p.extractGoStructLiterals(ctx, bodyNode, content, filePath)
+		localTypes := goLocalTypes(bodyNode, content, paramListNode)
+		sym.FieldAccesses = extractGoFieldAccesses(ctx, bodyNode, content, filePath,
+			goImportNames(result.Imports), localTypes)
+		setGoLocalTypes(sym, localTypes)
 	}
 
 	// IT-06 Bug 9: Extract type references from parameter and return type annotations
@@ -540,6 +564,10 @@ func (p *GoParser) processFunctionDecl(ctx context.Context, node *sitter.Node, c
 	if bodyNode != nil {
 		typeRefs = append(typeRefs, p.extractCompositeLiteralTypeRefs(ctx, bodyNode, content, filePath)...)
 	}
+	// Type parameters are placeholders, not project types; constraints are.
+	typeParamSet := typeParams.set()
+	typeRefs = filterGoTypeParamRefs(typeRefs, typeParamSet)
+	typeRefs = append(typeRefs, typeParams.ConstraintRefs...)
 	if len(typeRefs) > 0 {
 		if len(typeRefs) > MaxTypeReferencesPerSymbol {
 			typeRefs = typeRefs[:MaxTypeReferencesPerSymbol]
@@ -547,6 +575,9 @@ func (p *GoParser) processFunctionDecl(ctx context.Context, node *sitter.Node, c
 		sym.TypeReferences = typeRefs
 	}
 
+	p.setGoGenericMetadata(ctx, sym, typeParams, typeParamSet, content, paramListNode, returnTypeNode, bodyNode)
+	setGoSQLTableRefs(ctx, sym, bodyNode, content)
+
 	result.Symbols = append(result.Symbols, sym)
 }
 
@@ -571,6 +602,7 @@ func (p *GoParser) processMethodDecl(ctx context.Context, node *sitter.Node, con
 	var bodyNode *sitter.Node
 	var paramListNode *sitter.Node  // IT-06 Bug 9: track params (not receiver) for type ref extraction
 	var returnTypeNode *sitter.Node // IT-06 Bug 9: track standalone return type node
+	var receiverNode *sitter.Node
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -580,6 +612,7 @@ func (p *GoParser) processMethodDecl(ctx context.Context, node *sitter.Node, con
 			plist := string(content[child.StartByte():child.EndByte()])
 			if receiverStr == "" {
 				receiverStr = plist
+				receiverNode = child
 			} else if params == "" {
 				params = plist
 				paramListNode = child // IT-06 Bug 9: second parameter_list is params
@@ -590,7 +623,7 @@ func (p *GoParser) processMethodDecl(ctx context.Context, node *sitter.Node, con
 			}
 		case "field_identifier":
 			name = string(content[child.StartByte():child.EndByte()])
-		case "type_identifier", "pointer_type", "slice_type", "map_type", "channel_type", "qualified_type":
+		case "type_identifier", "pointer_type", "slice_type", "map_type", "channel_type", "qualified_type", "generic_type":
 			returns = string(content[child.StartByte():child.EndByte()])
 			returnTypeNode = child // IT-06 Bug 9
 		case "block":
@@ -641,6 +674,11 @@ func (p *GoParser) processMethodDecl(ctx context.Context, node *sitter.Node, con
 	// GR-41: Extract call sites from method body
 	if bodyNode != nil {
 		sym.Calls = p.extractCallSites(ctx, bodyNode, content, filePath)
+		sym.StructLiterals = p.extractGoStructLiterals(ctx, bodyNode, content, filePath)
+		localTypes := goLocalTypes(bodyNode, content, receiverNode, paramListNode)
+		sym.FieldAccesses = extractGoFieldAccesses(ctx, bodyNode, content, filePath,
+			goImportNames(result.Imports), localTypes)
+		setGoLocalTypes(sym, localTypes)
 	}
 
 	// IT-06 Bug 9: Extract type references from parameter and return type annotations
@@ -659,6 +697,11 @@ func (p *GoParser) processMethodDecl(ctx context.Context, node *sitter.Node, con
 	if bodyNode != nil {
 		typeRefs = append(typeRefs, p.extractCompositeLiteralTypeRefs(ctx, bodyNode, content, filePath)...)
 	}
+	// Methods cannot declare type parameters, but a generic receiver
+	// (c *Cache[K, V]) brings the type's parameters into scope.
+	typeParams := goTypeParams{Names: extractGoReceiverTypeParams(receiverNode, content)}
+	typeParamSet := typeParams.set()
+	typeRefs = filterGoTypeParamRefs(typeRefs, typeParamSet)
 	if len(typeRefs) > 0 {
 		if len(typeRefs) > MaxTypeReferencesPerSymbol {
 			typeRefs = typeRefs[:MaxTypeReferencesPerSymbol]
@@ -666,9 +709,66 @@ func (p *GoParser) processMethodDecl(ctx context.Context, node *sitter.Node, con
 		sym.TypeReferences = typeRefs
 	}
 
+	p.setGoGenericMetadata(ctx, sym, typeParams, typeParamSet, content, paramListNode, returnTypeNode, bodyNode)
+	setGoSQLTableRefs(ctx, sym, bodyNode, content)
+
 	result.Symbols = append(result.Symbols, sym)
 }
 
+// setGoLocalTypes records the variables of localTypes with a known type
+// in sym.Metadata.LocalTypes. Metadata is only allocated when there is
+// something to record.
+func setGoLocalTypes(sym *Symbol, localTypes map[string]string) {
+	known := make(map[string]string, len(localTypes))
+	for name, typeName := range localTypes {
+		if typeName != "" {
+			known[name] = typeName
+		}
+	}
+	if len(known) == 0 {
+		return
+	}
+	if sym.Metadata == nil {
+		sym.Metadata = &SymbolMetadata{}
+	}
+	sym.Metadata.LocalTypes = known
+}
+
+// setGoGenericMetadata records type parameters and instantiated type arguments
+// on a Go function or method symbol.
+//
+// Description:
+//
+//	TypeParameters/Constraints come from the declaration's type parameter list
+//	(or the generic receiver for methods, without constraints). TypeArguments
+//	lists the named types used to instantiate generics in the signature and
+//	body (Map[string, User](...) → User), which the builder turns into
+//	REFERENCES edges. Metadata is only allocated when there is something to record.
+//
+// Inputs:
+//   - ctx: Context for cancellation.
+//   - sym: The symbol to annotate. Must not be nil.
+//   - typeParams: Parsed type parameters of the declaration.
+//   - params: Type parameter name set, excluded from TypeArguments.
+//   - content: Source file bytes.
+//   - nodes: Signature and body nodes to scan for type arguments.
+func (p *GoParser) setGoGenericMetadata(ctx context.Context, sym *Symbol, typeParams goTypeParams, params map[string]bool, content []byte, nodes ...*sitter.Node) {
+	typeArgs := extractGoTypeArguments(ctx, content, params, nodes...)
+	if len(typeParams.Names) == 0 && len(typeArgs) == 0 {
+		return
+	}
+	if sym.Metadata == nil {
+		sym.Metadata = &SymbolMetadata{}
+	}
+	if len(typeParams.Names) > 0 {
+		sym.Metadata.TypeParameters = typeParams.Names
+		sym.Metadata.Constraints = typeParams.Constraints
+	}
+	if len(typeArgs) > 0 {
+		sym.Metadata.TypeArguments = typeArgs
+	}
+}
+
 // goTypeSkipList contains Go built-in types that should not produce TypeReference entries.
 var goTypeSkipList = map[string]bool{
 	// Numeric types
@@ -831,6 +931,9 @@ func extractReceiverTypeFromString(receiver string) string {
 	receiver = strings.TrimSuffix(receiver, ")")
 	receiver = strings.TrimSpace(receiver)
 
+	// Generic receivers: "(c *Cache[K, V])" → "c *Cache"
+	receiver = stripGoTypeArgs(receiver)
+
 	if receiver == "" {
 		return ""
 	}
@@ -875,10 +978,14 @@ func (p *GoParser) processTypeSpec(node *sitter.Node, content []byte, filePath s
 	var name string
 	var kind SymbolKind
 	var typeNode *sitter.Node
+	var typeParams goTypeParams
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		switch child.Type() {
+		case "type_parameter_list":
+			// Generic type: type Repository[T any] interface { ... }
+			typeParams = extractGoTypeParams(child, content, filePath)
 		case "type_identifier":
 			// Phase 20-C: Only set name from the FIRST type_identifier child.
 			// For type aliases like `type nopPage int`, tree-sitter produces two
@@ -931,6 +1038,21 @@ func (p *GoParser) processTypeSpec(node *sitter.Node, content []byte, filePath s
 	// Get doc comment (from type_declaration, not type_spec)
 	sym.DocComment = p.getPrecedingComment(root, parentDecl, content)
 
+	// Generic types: record the parameter list and reference constraint types.
+	// Instantiations in the type body (next *Node[T], r Repository[User])
+	// become TypeArguments, excluding the type's own parameters.
+	typeArgs := extractGoTypeArguments(context.Background(), content, typeParams.set(), node.ChildByFieldName("type"))
+	if len(typeParams.Names) > 0 || len(typeArgs) > 0 {
+		sym.Metadata = &SymbolMetadata{
+			TypeParameters: typeParams.Names,
+			Constraints:    typeParams.Constraints,
+			TypeArguments:  typeArgs,
+		}
+	}
+	if len(typeParams.ConstraintRefs) > 0 {
+		sym.TypeReferences = typeParams.ConstraintRefs
+	}
+
 	// Extract children (fields for struct, methods for interface)
 	if typeNode != nil {
 		sym.Children = p.extractTypeChildren(typeNode, content, filePath, kind)
@@ -1017,6 +1139,8 @@ func (p *GoParser) extractTypeChildren(node *sitter.Node, content []byte, filePa
 func (p *GoParser) extractField(node *sitter.Node, content []byte, filePath string) []*Symbol {
 	fields := make([]*Symbol, 0)
 	var fieldType string
+	var dbColumn string
+	var structTag string
 	var names []string
 
 	for i := 0; i < int(node.ChildCount()); i++ {
@@ -1024,6 +1148,13 @@ func (p *GoParser) extractField(node *sitter.Node, content []byte, filePath stri
 		switch child.Type() {
 		case "field_identifier":
 			names = append(names, string(content[child.StartByte():child.EndByte()]))
+		case "raw_string_literal", "interpreted_string_literal":
+			// Struct tag
+			rawTag := string(content[child.StartByte():child.EndByte()])
+			dbColumn = StructTagColumn(rawTag)
+			if unquoted, err := strconv.Unquote(rawTag); err == nil {
+				structTag = unquoted
+			}
 		default:
 			// Assume other nodes are the type
 			if len(names) > 0 && fieldType == "" {
@@ -1052,6 +1183,9 @@ func (p *GoParser) extractField(node *sitter.Node, content []byte, filePath stri
 			StartCol:  int(node.StartPoint().Column + 1),
 			EndCol:    int(node.EndPoint().Column + 1),
 		}
+		if dbColumn != "" || structTag != "" {
+			sym.Metadata = &SymbolMetadata{DBColumn: dbColumn, StructTag: structTag}
+		}
 		fields = append(fields, sym)
 	}
 
@@ -1168,6 +1302,11 @@ func (p *GoParser) extractInterfaceEmbeds(ifaceNode *sitter.Node, content []byte
 		switch child.Type() {
 		case "type_elem":
 			// tree-sitter wraps embedded interfaces in a type_elem node.
+			// Constraint type sets (~int | ~float64, int | string) are not
+			// embeds: they restrict the type set and contribute no methods.
+			if isGoTypeSetElem(child) {
+				continue
+			}
 			// Walk its children for type_identifier, qualified_type, or pointer_type.
 			for j := 0; j < int(child.ChildCount()); j++ {
 				inner := child.Child(j)
@@ -1181,7 +1320,8 @@ func (p *GoParser) extractInterfaceEmbeds(ifaceNode *sitter.Node, content []byte
 						continue
 					}
 					name := string(content[inner.StartByte():inner.EndByte()])
-					if name != "" {
+					// comparable/any in a constraint add no methods.
+					if name != "" && !isGoConstraintBuiltin(name) {
 						embedded = append(embedded, name)
 					}
 				case "qualified_type":
@@ -1189,6 +1329,12 @@ func (p *GoParser) extractInterfaceEmbeds(ifaceNode *sitter.Node, content []byte
 					if name != "" {
 						embedded = append(embedded, name)
 					}
+				case "generic_type":
+					// Reader[T] — embed the generic interface by its declared name
+					name := p.extractGenericTypeName(inner, content)
+					if name != "" {
+						embedded = append(embedded, name)
+					}
 				case "pointer_type":
 					name := p.unwrapPointerType(inner, content)
 					if name != "" {
@@ -1248,6 +1394,9 @@ func (p *GoParser) extractEmbeddedTypeName(fieldNode *sitter.Node, content []byt
 		case "qualified_type":
 			// pkg.Type — extract just the type name (last identifier)
 			return p.extractQualifiedTypeName(child, content)
+		case "generic_type":
+			// Base[T] — embed the generic type itself, not the instantiation
+			return p.extractGenericTypeName(child, content)
 		}
 	}
 	return ""
@@ -1270,6 +1419,8 @@ func (p *GoParser) unwrapPointerType(node *sitter.Node, content []byte) string {
 			return string(content[child.StartByte():child.EndByte()])
 		case "qualified_type":
 			return p.extractQualifiedTypeName(child, content)
+		case "generic_type":
+			return p.extractGenericTypeName(child, content)
 		}
 	}
 	return ""
@@ -1613,7 +1764,9 @@ func extractReceiverTypeName(signature string) (typeName string, isPointer bool)
 	}
 
 	receiver := rest[1:end]
-	// receiver is now like "r *Handler" or "h Handler" or "*Handler"
+	// receiver is now like "r *Handler" or "h Handler" or "*Handler".
+	// Generic receivers ("r *Repo[T]") are reduced to the base type name.
+	receiver = stripGoTypeArgs(receiver)
 
 	// Remove the variable name by finding the type
 	parts := strings.Fields(receiver)
@@ -1791,37 +1944,37 @@ func countReturnString(returns string) int {
 }
 
 // extractVariables extracts top-level variable declarations.
-func (p *GoParser) extractVariables(root *sitter.Node, content []byte, filePath string, result *ParseResult) {
+func (p *GoParser) extractVariables(ctx context.Context, root *sitter.Node, content []byte, filePath string, result *ParseResult) {
 	for i := 0; i < int(root.ChildCount()); i++ {
 		child := root.Child(i)
 		if child.Type() == "var_declaration" {
-			p.processVarDecl(child, content, filePath, result, root, SymbolKindVariable)
+			p.processVarDecl(ctx, child, content, filePath, result, root, SymbolKindVariable)
 		}
 	}
 }
 
 // extractConstants extracts top-level constant declarations.
-func (p *GoParser) extractConstants(root *sitter.Node, content []byte, filePath string, result *ParseResult) {
+func (p *GoParser) extractConstants(ctx context.Context, root *sitter.Node, content []byte, filePath string, result *ParseResult) {
 	for i := 0; i < int(root.ChildCount()); i++ {
 		child := root.Child(i)
 		if child.Type() == "const_declaration" {
-			p.processVarDecl(child, content, filePath, result, root, SymbolKindConstant)
+			p.processVarDecl(ctx, child, content, filePath, result, root, SymbolKindConstant)
 		}
 	}
 }
 
 // processVarDecl processes variable or constant declarations.
-func (p *GoParser) processVarDecl(node *sitter.Node, content []byte, filePath string, result *ParseResult, root *sitter.Node, kind SymbolKind) {
+func (p *GoParser) processVarDecl(ctx context.Context, node *sitter.Node, content []byte, filePath string, result *ParseResult, root *sitter.Node, kind SymbolKind) {
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		switch child.Type() {
 		case "var_spec", "const_spec":
-			p.processVarSpec(child, content, filePath, result, root, kind, node)
+			p.processVarSpec(ctx, child, content, filePath, result, root, kind, node)
 		case "var_spec_list", "const_spec_list":
 			for j := 0; j < int(child.ChildCount()); j++ {
 				spec := child.Child(j)
 				if spec.Type() == "var_spec" || spec.Type() == "const_spec" {
-					p.processVarSpec(spec, content, filePath, result, root, kind, node)
+					p.processVarSpec(ctx, spec, content, filePath, result, root, kind, node)
 				}
 			}
 		}
@@ -1829,7 +1982,7 @@ func (p *GoParser) processVarDecl(node *sitter.Node, content []byte, filePath st
 }
 
 // processVarSpec processes a single variable or constant specification.
-func (p *GoParser) processVarSpec(node *sitter.Node, content []byte, filePath string, result *ParseResult, root *sitter.Node, kind SymbolKind, parentDecl *sitter.Node) {
+func (p *GoParser) processVarSpec(ctx context.Context, node *sitter.Node, content []byte, filePath string, result *ParseResult, root *sitter.Node, kind SymbolKind, parentDecl *sitter.Node) {
 	var names []string
 	var typeStr string
 
@@ -1843,6 +1996,13 @@ func (p *GoParser) processVarSpec(node *sitter.Node, content []byte, filePath st
 		}
 	}
 
+	// Package-level commands and routes are often declared as variables,
+	// e.g. var serveCmd = &cobra.Command{...}.
+	var structLiterals []StructLiteral
+	if kind == SymbolKindVariable {
+		structLiterals = p.extractGoStructLiterals(ctx, node.ChildByFieldName("value"), content, filePath)
+	}
+
 	for _, name := range names {
 		exported := len(name) > 0 && name[0] >= 'A' && name[0] <= 'Z'
 
@@ -1866,6 +2026,7 @@ func (p *GoParser) processVarSpec(node *sitter.Node, content []byte, filePath st
 
 		// Get doc comment
 		sym.DocComment = p.getPrecedingComment(root, parentDecl, content)
+		sym.StructLiterals = structLiterals
 
 		result.Symbols = append(result.Symbols, sym)
 	}
@@ -1968,7 +2129,7 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 		// Check depth limit
 		if entry.depth > MaxCallExpressionDepth {
 			slog.Debug("GR-41: Max call expression depth reached",
-				slog.String("file", filePath),
+				telemetry.SensitiveLog("file", filePath),
 				slog.Int("depth", entry.depth),
 			)
 			continue
@@ -1979,7 +2140,7 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 		if nodeCount%100 == 0 {
 			if ctx.Err() != nil {
 				slog.Debug("GR-41: Context cancelled during call extraction",
-					slog.String("file", filePath),
+					telemetry.SensitiveLog("file", filePath),
 					slog.Int("calls_found", len(calls)),
 				)
 				return calls
@@ -1989,7 +2150,7 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 		// Check call limit
 		if len(calls) >= MaxCallSitesPerSymbol {
 			slog.Warn("GR-41: Max call sites per symbol reached",
-				slog.String("file", filePath),
+				telemetry.SensitiveLog("file", filePath),
 				slog.Int("limit", MaxCallSitesPerSymbol),
 			)
 			return calls
@@ -2001,6 +2162,12 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 			if call != nil && call.Target != "" {
 				calls = append(calls, *call)
 			}
+		} else if node.Type() == "type_conversion_expression" {
+			// Explicit single-argument instantiation: Identity[User](u)
+			call := p.extractGenericInstantiationCallSite(node, content, filePath)
+			if call != nil {
+				calls = append(calls, *call)
+			}
 		}
 
 		// Add children to stack (in reverse order to process left-to-right)
@@ -2018,7 +2185,7 @@ func (p *GoParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node,
 
 	// GR-41: Record span attributes for observability
 	span.SetAttributes(
-		attribute.String("file", filePath),
+		telemetry.SensitiveString("file", filePath),
 		attribute.Int("calls_found", len(calls)),
 		attribute.Int("nodes_traversed", nodeCount),
 	)
@@ -2238,6 +2405,7 @@ func (p *GoParser) extractSingleCallSite(node *sitter.Node, content []byte, file
 			// or is a complex expression. Package references typically use CamelCase.
 			// We'll mark it as a method call and let the graph builder resolve it.
 			call.IsMethod = true
+			call.Chain = extractCallChain(goCallChainSyntax, funcNode, content)
 		}
 
 	case "parenthesized_expression":
@@ -2258,8 +2426,201 @@ func (p *GoParser) extractSingleCallSite(node *sitter.Node, content []byte, file
 		return nil
 	}
 
+	if argsNode := node.ChildByFieldName("arguments"); argsNode != nil {
+		call.FunctionArgs = extractGoCallArgs(argsNode, content)
+		// Registration calls keep their route or schedule string, template
+		// calls their file names; every call keeps its literals when
+		// capturing them for config tracing.
+		if len(call.FunctionArgs) > 0 || p.parseOptions.CaptureLiteralArgs || keepsLinkageLiterals(call.Target) {
+			capture := newLiteralArgCapture(filePath, content, p.parseOptions.CaptureLiteralArgs, p.parseOptions.KeepSensitiveLiterals)
+			captureGoLiteralArgs(capture, argsNode)
+			call.LiteralArgs = capture.finish(call.Target)
+		}
+	}
+
 	return call
 }
 
+// extractGoCallArgs extracts callback arguments from a call's
+// argument_list node.
+//
+// Description:
+//
+//	Identifiers and selector expressions are returned as possible function
+//	references (e.g. getUser, h.HandleInit). Literal arguments are
+//	captured separately (see captureGoLiteralArgs).
+//
+// Inputs:
+//   - argsNode: The argument_list node. Must not be nil.
+//   - content: Source file bytes.
+//
+// Outputs:
+//   - []string: Identifier and selector arguments, or nil.
+//
+// Thread Safety: Safe for concurrent use.
+func extractGoCallArgs(argsNode *sitter.Node, content []byte) []string {
+	var funcArgs []string
+	for i := 0; i < int(argsNode.NamedChildCount()); i++ {
+		child := argsNode.NamedChild(i)
+		if child == nil {
+			continue
+		}
+		switch child.Type() {
+		case "identifier":
+			name := string(content[child.StartByte():child.EndByte()])
+			switch name {
+			case "nil", "true", "false", "iota", "_":
+			default:
+				funcArgs = append(funcArgs, name)
+			}
+		case "selector_expression":
+			text := string(content[child.StartByte():child.EndByte()])
+			if len(text) <= 80 && !strings.ContainsAny(text, "(\n") {
+				funcArgs = append(funcArgs, text)
+			}
+		}
+	}
+	return funcArgs
+}
+
+// unquoteGoString returns the value of an interpreted or raw string literal node.
+func unquoteGoString(node *sitter.Node, content []byte) (string, bool) {
+	text := string(content[node.StartByte():node.EndByte()])
+	if node.Type() == "raw_string_literal" {
+		if len(text) < 2 {
+			return "", false
+		}
+		return text[1 : len(text)-1], true
+	}
+	lit, err := strconv.Unquote(text)
+	if err != nil {
+		return "", false
+	}
+	return lit, true
+}
+
+// extractGoStructLiterals extracts keyed composite literals of qualified types.
+//
+// Description:
+//
+//	Walks the node looking for composite literals such as
+//	&cobra.Command{Use: "serve", RunE: runServe} and records their keyed
+//	fields. A literal is kept only if at least one field value is an
+//	identifier, selector or function literal, i.e. if it may wire up a
+//	handler; plain configuration literals are skipped.
+//
+// Inputs:
+//   - ctx: Context for cancellation.
+//   - node: A function body or variable initializer. May be nil.
+//   - content: Source file bytes.
+//   - filePath: Relative path for Location.
+//
+// Outputs:
+//   - []StructLiteral: At most MaxStructLiteralsPerSymbol literals.
+//
+// Thread Safety: Safe for concurrent use.
+func (p *GoParser) extractGoStructLiterals(ctx context.Context, node *sitter.Node, content []byte, filePath string) []StructLiteral {
+	if node == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	var literals []StructLiteral
+
+	type stackEntry struct {
+		node  *sitter.Node
+		depth int
+	}
+
+	stack := []stackEntry{{node: node, depth: 0}}
+	nodeCount := 0
+	for len(stack) > 0 && len(literals) < MaxStructLiteralsPerSymbol {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if entry.node == nil || entry.depth > MaxCallExpressionDepth {
+			continue
+		}
+
+		nodeCount++
+		if nodeCount%100 == 0 && ctx.Err() != nil {
+			return literals
+		}
+
+		if entry.node.Type() == "composite_literal" {
+			if lit, ok := goStructLiteral(entry.node, content, filePath); ok {
+				literals = append(literals, lit)
+			}
+		}
+
+		for i := int(entry.node.ChildCount()) - 1; i >= 0; i-- {
+			if child := entry.node.Child(i); child != nil {
+				stack = append(stack, stackEntry{node: child, depth: entry.depth + 1})
+			}
+		}
+	}
+
+	return literals
+}
+
+// goStructLiteral converts a composite_literal node into a StructLiteral.
+// Returns false if the type is not package-qualified or no field wires up
+// a function.
+func goStructLiteral(node *sitter.Node, content []byte, filePath string) (StructLiteral, bool) {
+	typeNode := node.ChildByFieldName("type")
+	bodyNode := node.ChildByFieldName("body")
+	if typeNode == nil || bodyNode == nil || typeNode.Type() != "qualified_type" {
+		return StructLiteral{}, false
+	}
+
+	fields := make(map[string]string)
+	wired := false
+	for i := 0; i < int(bodyNode.NamedChildCount()); i++ {
+		elem := bodyNode.NamedChild(i)
+		if elem == nil || elem.Type() != "keyed_element" || elem.NamedChildCount() < 2 {
+			continue
+		}
+		key, value := goLiteralElement(elem.NamedChild(0)), goLiteralElement(elem.NamedChild(1))
+		if key == nil || value == nil || (key.Type() != "identifier" && key.Type() != "field_identifier") {
+			continue
+		}
+		name := string(content[key.StartByte():key.EndByte()])
+		switch value.Type() {
+		case "identifier", "selector_expression":
+			fields[name] = string(content[value.StartByte():value.EndByte()])
+			wired = true
+		case "func_literal":
+			fields[name] = FuncLiteralValue
+			wired = true
+		case "interpreted_string_literal", "raw_string_literal":
+			if lit, ok := unquoteGoString(value, content); ok {
+				fields[name] = lit
+			}
+		}
+	}
+	if !wired {
+		return StructLiteral{}, false
+	}
+
+	return StructLiteral{
+		Type:   string(content[typeNode.StartByte():typeNode.EndByte()]),
+		Fields: fields,
+		Location: Location{
+			FilePath:  filePath,
+			StartLine: int(node.StartPoint().Row) + 1,
+			EndLine:   int(node.EndPoint().Row) + 1,
+			StartCol:  int(node.StartPoint().Column),
+			EndCol:    int(node.EndPoint().Column),
+		},
+	}, true
+}
+
+// goLiteralElement unwraps a literal_element node to its expression.
+// Older grammars put the expression directly under keyed_element.
+func goLiteralElement(node *sitter.Node) *sitter.Node {
+	if node != nil && node.Type() == "literal_element" && node.NamedChildCount() > 0 {
+		return node.NamedChild(0)
+	}
+	return node
+}
+
 // Compile-time interface compliance check.
 var _ Parser = (*GoParser)(nil)
@@ -2080,3 +2080,258 @@ func makeSlice() []Item {
 		}
 	})
 }
+
+// TestGoParser_Generics verifies type parameter, constraint and type argument
+// extraction for Go generics.
+func TestGoParser_Generics(t *testing.T) {
+	src := `package repo
+
+type Entity interface {
+	ID() string
+}
+
+type Number interface {
+	~int | ~float64
+}
+
+type Keyed interface {
+	comparable
+	Key() string
+}
+
+type Reader[T any] interface {
+	Get(id string) (T, error)
+}
+
+type Repository[T Entity] interface {
+	Reader[T]
+	Save(item T) error
+}
+
+type Cache[K comparable, V any] struct {
+	items map[K]V
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	v, ok := c.items[key]
+	return v, ok
+}
+
+type User struct{}
+
+type Order struct{}
+
+func Map[K comparable, V any](in []K, fn func(K) V) []V {
+	return nil
+}
+
+func Identity[T any](v T) T {
+	return v
+}
+
+func Sum[S ~[]E, E Number](s S) E {
+	var total E
+	return total
+}
+
+func Load(o Order) {
+	users := Map[string, User](nil, nil)
+	_ = Identity[Order](o)
+	_ = users
+}
+`
+	parser := NewGoParser(WithParseOptions(ParseOptions{IncludePrivate: true}))
+	result, err := parser.Parse(context.Background(), []byte(src), "repo.go")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+
+	t.Run("function type params and constraints", func(t *testing.T) {
+		fn := findSymbolByName(result.Symbols, "Map")
+		if fn == nil || fn.Metadata == nil {
+			t.Fatal("expected Map with metadata")
+		}
+		if got := strings.Join(fn.Metadata.TypeParameters, ","); got != "K,V" {
+			t.Errorf("TypeParameters = %q, want K,V", got)
+		}
+		if got := strings.Join(fn.Metadata.Constraints, ","); got != "comparable,any" {
+			t.Errorf("Constraints = %q, want comparable,any", got)
+		}
+		for _, ref := range fn.TypeReferences {
+			if ref.Name == "K" || ref.Name == "V" {
+				t.Errorf("type parameter %q must not be a TypeReference", ref.Name)
+			}
+		}
+	})
+
+	t.Run("constraint interface is referenced", func(t *testing.T) {
+		fn := findSymbolByName(result.Symbols, "Sum")
+		if fn == nil || fn.Metadata == nil {
+			t.Fatal("expected Sum with metadata")
+		}
+		if got := strings.Join(fn.Metadata.Constraints, ","); got != "~[]E,Number" {
+			t.Errorf("Constraints = %q, want ~[]E,Number", got)
+		}
+		var names []string
+		for _, ref := range fn.TypeReferences {
+			names = append(names, ref.Name)
+		}
+		if got := strings.Join(names, ","); got != "Number" {
+			t.Errorf("TypeReferences = %q, want only Number", got)
+		}
+	})
+
+	t.Run("generic type params", func(t *testing.T) {
+		typ := findSymbolByName(result.Symbols, "Cache")
+		if typ == nil || typ.Metadata == nil {
+			t.Fatal("expected Cache with metadata")
+		}
+		if got := strings.Join(typ.Metadata.TypeParameters, ","); got != "K,V" {
+			t.Errorf("TypeParameters = %q, want K,V", got)
+		}
+		if len(typ.Metadata.TypeArguments) != 0 {
+			t.Errorf("own type params must not be TypeArguments, got %v", typ.Metadata.TypeArguments)
+		}
+	})
+
+	t.Run("generic receiver", func(t *testing.T) {
+		var method *Symbol
+		for _, sym := range result.Symbols {
+			if sym.Name == "Get" && sym.Kind == SymbolKindMethod {
+				method = sym
+			}
+		}
+		if method == nil {
+			t.Fatal("expected Cache.Get method")
+		}
+		if method.Receiver != "Cache" {
+			t.Errorf("Receiver = %q, want Cache", method.Receiver)
+		}
+		if got := strings.Join(method.Metadata.TypeParameters, ","); got != "K,V" {
+			t.Errorf("TypeParameters = %q, want K,V", got)
+		}
+		if len(method.TypeReferences) != 0 {
+			t.Errorf("receiver type params must not be TypeReferences, got %v", method.TypeReferences)
+		}
+
+		typ := findSymbolByName(result.Symbols, "Cache")
+		if len(typ.Metadata.Methods) != 1 || typ.Metadata.Methods[0].Name != "Get" {
+			t.Errorf("expected Get associated with Cache, got %v", typ.Metadata.Methods)
+		}
+	})
+
+	t.Run("instantiated calls", func(t *testing.T) {
+		fn := findSymbolByName(result.Symbols, "Load")
+		if fn == nil || fn.Metadata == nil {
+			t.Fatal("expected Load with metadata")
+		}
+		if got := strings.Join(fn.Metadata.TypeArguments, ","); got != "User,Order" {
+			t.Errorf("TypeArguments = %q, want User,Order", got)
+		}
+		targets := make(map[string]bool)
+		for _, call := range fn.Calls {
+			targets[call.Target] = true
+		}
+		if !targets["Map"] || !targets["Identity"] {
+			t.Errorf("expected calls to Map and Identity, got %v", fn.Calls)
+		}
+	})
+
+	t.Run("generic interface embeds", func(t *testing.T) {
+		iface := findSymbolByName(result.Symbols, "Repository")
+		if iface == nil || iface.Metadata == nil {
+			t.Fatal("expected Repository with metadata")
+		}
+		if iface.Metadata.Extends != "Reader" {
+			t.Errorf("Extends = %q, want Reader", iface.Metadata.Extends)
+		}
+	})
+
+	t.Run("constraint type sets are not embeds", func(t *testing.T) {
+		number := findSymbolByName(result.Symbols, "Number")
+		if number != nil && number.Metadata != nil && number.Metadata.Extends != "" {
+			t.Errorf("Number must not embed anything, got Extends=%q", number.Metadata.Extends)
+		}
+		keyed := findSymbolByName(result.Symbols, "Keyed")
+		if keyed == nil || keyed.Metadata == nil {
+			t.Fatal("expected Keyed with metadata")
+		}
+		if keyed.Metadata.Extends != "" {
+			t.Errorf("comparable must not be an embed, got Extends=%q", keyed.Metadata.Extends)
+		}
+		if len(keyed.Metadata.Methods) != 1 {
+			t.Errorf("expected Keyed to keep its Key method, got %v", keyed.Metadata.Methods)
+		}
+	})
+}
+
+func TestStripGoTypeArgs(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Cache[K, V]", "Cache"},
+		{"r *Repo[T]", "r *Repo"},
+		{"Handler", "Handler"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := stripGoTypeArgs(tt.in); got != tt.want {
+			t.Errorf("stripGoTypeArgs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGoParser_RegistrationArgs(t *testing.T) {
+	src := `package api
+
+var serveCmd = &cobra.Command{
+	Use:  "serve",
+	RunE: runServe,
+}
+
+var cfg = &http.Server{Addr: ":8080"}
+
+func Register(r *gin.Engine, h *Handlers) {
+	r.GET("/users/:id", auth, h.GetUser)
+	r.Handle(` + "`/raw`" + `, nil)
+	fmt.Println("not recorded")
+	root.AddCommand(&cobra.Command{Use: "migrate", Run: func(cmd *cobra.Command, args []string) {}})
+}
+`
+	result, err := NewGoParser().Parse(context.Background(), []byte(src), "api/routes.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	syms := make(map[string]*Symbol)
+	for _, sym := range result.Symbols {
+		syms[sym.Name] = sym
+	}
+
+	calls := make(map[string]CallSite)
+	for _, call := range syms["Register"].Calls {
+		calls[call.Target] = call
+	}
+	get := calls["GET"]
+	if strings.Join(get.FunctionArgs, ",") != "auth,h.GetUser" || strings.Join(get.StringLiteralArgs(), ",") != "/users/:id" {
+		t.Errorf("GET args = %v / %v", get.FunctionArgs, get.LiteralArgs)
+	}
+	if handle := calls["Handle"]; handle.FunctionArgs != nil || handle.LiteralArgs != nil {
+		t.Errorf("Handle(\"/raw\", nil) args = %v / %v, want none", handle.FunctionArgs, handle.LiteralArgs)
+	}
+	if println := calls["Println"]; println.LiteralArgs != nil {
+		t.Errorf("literals without a callback should not be kept: %v", println.LiteralArgs)
+	}
+
+	serve := syms["serveCmd"].StructLiterals
+	if len(serve) != 1 || serve[0].Type != "cobra.Command" || serve[0].Fields["Use"] != "serve" || serve[0].Fields["RunE"] != "runServe" {
+		t.Errorf("serveCmd literals = %+v", serve)
+	}
+	if len(syms["cfg"].StructLiterals) != 0 {
+		t.Errorf("literal without a function field should be skipped: %+v", syms["cfg"].StructLiterals)
+	}
+	inline := syms["Register"].StructLiterals
+	if len(inline) != 1 || inline[0].Fields["Run"] != FuncLiteralValue || inline[0].Location.StartLine != 14 {
+		t.Errorf("Register literals = %+v", inline)
+	}
+}
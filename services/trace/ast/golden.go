@@ -0,0 +1,210 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// CanonicalParseResult returns a normalized deep copy of r for snapshot
+// comparison.
+//
+// Description:
+//
+//	Two parses of the same content produce identical canonical results,
+//	regardless of when they ran. Normalization rules:
+//
+//	  - Timestamps, durations and hashes are cleared: ParsedAtMilli,
+//	    ParseDurationMs, Hash and Symbol.StableID.
+//	  - File paths equal to r.FilePath are cleared from symbols and
+//	    locations; they repeat the result's own path.
+//	  - Symbols (at every level), imports, calls, type references and
+//	    struct literals are sorted by position, then by name.
+//	  - Nil symbol and import slices become empty, so "no symbols" always
+//	    serializes the same way.
+//
+//	Everything else, including symbol IDs and metadata, is kept, so new
+//	ParseResult fields show up in snapshots without changes here.
+//
+// Inputs:
+//
+//	r - The parse result. Not modified. May be nil.
+//
+// Outputs:
+//
+//	*ParseResult - The canonical copy, or nil if r is nil.
+//
+// Thread Safety: Safe for concurrent use.
+func CanonicalParseResult(r *ParseResult) *ParseResult {
+	if r == nil {
+		return nil
+	}
+	out := *r
+	out.ParsedAtMilli = 0
+	out.ParseDurationMs = 0
+	out.Hash = ""
+
+	out.Imports = make([]Import, len(r.Imports))
+	for i, imp := range r.Imports {
+		imp.Location = canonicalLocation(imp.Location, r.FilePath)
+		out.Imports[i] = imp
+	}
+	sort.SliceStable(out.Imports, func(i, j int) bool {
+		a, b := out.Imports[i], out.Imports[j]
+		if c := compareLocations(a.Location, b.Location); c != 0 {
+			return c < 0
+		}
+		return a.Path < b.Path
+	})
+
+	out.Symbols = canonicalSymbols(r.Symbols, r.FilePath, 0)
+	if out.Symbols == nil {
+		out.Symbols = []*Symbol{}
+	}
+	return &out
+}
+
+// MarshalGolden serializes the canonical form of r as indented JSON.
+//
+// Description:
+//
+//	The output ends with a newline and is stable across runs, so it can be
+//	checked in as a golden file and compared byte for byte.
+//
+// Inputs:
+//
+//	r - The parse result.
+//
+// Outputs:
+//
+//	[]byte - The serialized snapshot.
+//	error - Non-nil if serialization fails.
+//
+// Thread Safety: Safe for concurrent use.
+func MarshalGolden(r *ParseResult) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(CanonicalParseResult(r)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// canonicalSymbols copies and normalizes symbols and their children.
+func canonicalSymbols(symbols []*Symbol, filePath string, depth int) []*Symbol {
+	if len(symbols) == 0 || depth > MaxSymbolDepth {
+		return nil
+	}
+	out := make([]*Symbol, 0, len(symbols))
+	for _, sym := range symbols {
+		if sym == nil {
+			continue
+		}
+		c := *sym
+		c.ParsedAtMilli = 0
+		c.StableID = ""
+		if c.FilePath == filePath {
+			c.FilePath = ""
+		}
+		c.Children = canonicalSymbols(sym.Children, filePath, depth+1)
+
+		c.Calls = make([]CallSite, len(sym.Calls))
+		for i, call := range sym.Calls {
+			call.Location = canonicalLocation(call.Location, filePath)
+			c.Calls[i] = call
+		}
+		sort.SliceStable(c.Calls, func(i, j int) bool {
+			if cmp := compareLocations(c.Calls[i].Location, c.Calls[j].Location); cmp != 0 {
+				return cmp < 0
+			}
+			return c.Calls[i].Target < c.Calls[j].Target
+		})
+
+		c.TypeReferences = make([]TypeReference, len(sym.TypeReferences))
+		for i, ref := range sym.TypeReferences {
+			ref.Location = canonicalLocation(ref.Location, filePath)
+			c.TypeReferences[i] = ref
+		}
+		sort.SliceStable(c.TypeReferences, func(i, j int) bool {
+			if cmp := compareLocations(c.TypeReferences[i].Location, c.TypeReferences[j].Location); cmp != 0 {
+				return cmp < 0
+			}
+			return c.TypeReferences[i].Name < c.TypeReferences[j].Name
+		})
+
+		c.StructLiterals = make([]StructLiteral, len(sym.StructLiterals))
+		for i, lit := range sym.StructLiterals {
+			lit.Location = canonicalLocation(lit.Location, filePath)
+			c.StructLiterals[i] = lit
+		}
+		sort.SliceStable(c.StructLiterals, func(i, j int) bool {
+			if cmp := compareLocations(c.StructLiterals[i].Location, c.StructLiterals[j].Location); cmp != 0 {
+				return cmp < 0
+			}
+			return c.StructLiterals[i].Type < c.StructLiterals[j].Type
+		})
+
+		c.FieldAccesses = make([]FieldAccess, len(sym.FieldAccesses))
+		for i, access := range sym.FieldAccesses {
+			access.Location = canonicalLocation(access.Location, filePath)
+			c.FieldAccesses[i] = access
+		}
+		sort.SliceStable(c.FieldAccesses, func(i, j int) bool {
+			if cmp := compareLocations(c.FieldAccesses[i].Location, c.FieldAccesses[j].Location); cmp != 0 {
+				return cmp < 0
+			}
+			return c.FieldAccesses[i].Field < c.FieldAccesses[j].Field
+		})
+
+		out = append(out, &c)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		if a.StartCol != b.StartCol {
+			return a.StartCol < b.StartCol
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Kind < b.Kind
+	})
+	return out
+}
+
+// canonicalLocation clears a file path that repeats the result's path.
+func canonicalLocation(loc Location, filePath string) Location {
+	if loc.FilePath == filePath {
+		loc.FilePath = ""
+	}
+	return loc
+}
+
+// compareLocations orders locations by file, start line and start column.
+func compareLocations(a, b Location) int {
+	switch {
+	case a.FilePath != b.FilePath:
+		if a.FilePath < b.FilePath {
+			return -1
+		}
+		return 1
+	case a.StartLine != b.StartLine:
+		return a.StartLine - b.StartLine
+	default:
+		return a.StartCol - b.StartCol
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// updateGolden rewrites the golden files instead of comparing against them:
+//
+//	go test ./services/trace/ast -run TestGoldenParseResults -update
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden/**/*.golden.json from current parser output")
+
+// goldenDir holds one directory per language of source files, each with a
+// sibling <file>.golden.json snapshot.
+const goldenDir = "testdata/golden"
+
+// goldenSuffix marks snapshot files in goldenDir.
+const goldenSuffix = ".golden.json"
+
+// goldenDiffContext is the number of unchanged lines shown around changes.
+const goldenDiffContext = 3
+
+// goldenParsers returns a registry with every parser, selected per file by
+// extension.
+func goldenParsers() *ParserRegistry {
+	r := NewParserRegistry()
+	r.Register(NewGoParser())
+	r.Register(NewPythonParser())
+	r.Register(NewTypeScriptParser())
+	r.Register(NewJavaScriptParser())
+	r.Register(NewPHPParser())
+	r.Register(NewKotlinParser())
+	r.Register(NewSwiftParser())
+	r.Register(NewProtoParser())
+	r.Register(NewSQLParser())
+	r.Register(NewBashParser())
+	r.Register(NewYAMLParser())
+	r.Register(NewMarkdownParser())
+	r.Register(NewHTMLParser())
+	r.Register(NewCSSParser())
+	return r
+}
+
+// TestGoldenParseResults parses every file under testdata/golden and
+// compares the canonical result (MarshalGolden) with its snapshot.
+func TestGoldenParseResults(t *testing.T) {
+	parsers := goldenParsers()
+	var sources []string
+	err := filepath.WalkDir(goldenDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && !strings.HasSuffix(path, goldenSuffix) {
+			sources = append(sources, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("walking %s: %v", goldenDir, err)
+	}
+	if len(sources) == 0 {
+		t.Fatalf("no golden sources under %s", goldenDir)
+	}
+
+	for _, src := range sources {
+		rel := filepath.ToSlash(strings.TrimPrefix(src, goldenDir+string(filepath.Separator)))
+		t.Run(rel, func(t *testing.T) {
+			parser, ok := parsers.GetByExtension(filepath.Ext(src))
+			if !ok {
+				t.Fatalf("no parser for %s", rel)
+			}
+			content, err := os.ReadFile(src)
+			if err != nil {
+				t.Fatalf("reading source: %v", err)
+			}
+			result, err := parser.Parse(context.Background(), content, rel)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			got, err := MarshalGolden(result)
+			if err != nil {
+				t.Fatalf("MarshalGolden: %v", err)
+			}
+
+			goldenPath := src + goldenSuffix
+			if *updateGolden {
+				if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+					t.Fatalf("writing golden: %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("reading golden (run with -update to create it): %v", err)
+			}
+			if !bytes.Equal(want, got) {
+				t.Errorf("parse result differs from %s (run with -update to accept):\n%s",
+					goldenPath, lineDiff(string(want), string(got)))
+			}
+		})
+	}
+}
+
+// TestMarshalGolden_Normalizes checks that run-dependent fields are
+// dropped and ordering does not depend on parser output order.
+func TestMarshalGolden_Normalizes(t *testing.T) {
+	build := func(parsedAt int64, reversed bool) *ParseResult {
+		a := &Symbol{ID: "a.go:1:A", Name: "A", Kind: SymbolKindFunction, FilePath: "a.go", StartLine: 1, ParsedAtMilli: parsedAt, StableID: fmt.Sprint(parsedAt)}
+		b := &Symbol{ID: "a.go:5:B", Name: "B", Kind: SymbolKindFunction, FilePath: "a.go", StartLine: 5, ParsedAtMilli: parsedAt}
+		r := &ParseResult{FilePath: "a.go", Language: "go", Symbols: []*Symbol{a, b}, ParsedAtMilli: parsedAt, ParseDurationMs: parsedAt, Hash: fmt.Sprint(parsedAt)}
+		if reversed {
+			r.Symbols = []*Symbol{b, a}
+		}
+		return r
+	}
+
+	first, err := MarshalGolden(build(1, false))
+	if err != nil {
+		t.Fatalf("MarshalGolden: %v", err)
+	}
+	second, err := MarshalGolden(build(2, true))
+	if err != nil {
+		t.Fatalf("MarshalGolden: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Errorf("snapshots differ:\n%s", lineDiff(string(first), string(second)))
+	}
+	if canonical := CanonicalParseResult(build(1, false)); canonical.Symbols[0].FilePath != "" || canonical.FilePath != "a.go" {
+		t.Errorf("expected only the result's file path, got %q / %q", canonical.FilePath, canonical.Symbols[0].FilePath)
+	}
+
+	original := build(1, true)
+	CanonicalParseResult(original)
+	if original.Symbols[0].Name != "B" || original.Symbols[0].ParsedAtMilli != 1 {
+		t.Error("CanonicalParseResult modified its input")
+	}
+}
+
+// lineDiff renders a unified-style diff of want and got with
+// goldenDiffContext lines of context around each change.
+func lineDiff(want, got string) string {
+	a := strings.Split(want, "\n")
+	b := strings.Split(got, "\n")
+
+	// lcs[i][j] is the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+		num  int
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i], i + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i], i + 1})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j], i + 1})
+			j++
+		}
+	}
+
+	var out strings.Builder
+	last := -1
+	for k, l := range lines {
+		if l.op == ' ' && !nearChange(len(lines), k, func(n int) bool { return lines[n].op != ' ' }) {
+			continue
+		}
+		if last < 0 || k != last+1 {
+			fmt.Fprintf(&out, "@@ golden line %d @@\n", l.num)
+		}
+		fmt.Fprintf(&out, "%c %s\n", l.op, l.text)
+		last = k
+	}
+	return out.String()
+}
+
+// nearChange reports whether any line within goldenDiffContext of k changed.
+func nearChange(n, k int, changed func(int) bool) bool {
+	for i := max(0, k-goldenDiffContext); i <= min(n-1, k+goldenDiffContext); i++ {
+		if changed(i) {
+			return true
+		}
+	}
+	return false
+}
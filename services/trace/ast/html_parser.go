@@ -1,10 +1,14 @@
 package ast
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -139,6 +143,8 @@ func (p *HTMLParser) Extensions() []string {
 //	Parses the provided HTML content using tree-sitter and extracts all
 //	symbols including elements with IDs, forms, custom elements, and imports.
 //	Inline <script> and <style> content is delegated to the respective parsers.
+//	A Go template file, or a file with classes on elements without an id,
+//	also gets a file symbol carrying them (see fileSymbol).
 //
 // Inputs:
 //
@@ -203,6 +209,9 @@ func (p *HTMLParser) Parse(ctx context.Context, content []byte, filePath string)
 	// Extract symbols from AST
 	rootNode := tree.RootNode()
 	p.extractSymbols(ctx, rootNode, content, filePath, result)
+	if file := p.fileSymbol(rootNode, content, filePath); file != nil {
+		result.Symbols = append(result.Symbols, file)
+	}
 
 	// Validate result
 	if err := result.Validate(); err != nil {
@@ -256,12 +265,13 @@ func (p *HTMLParser) extractElement(ctx context.Context, node *sitter.Node, cont
 	name := ""
 	href := ""
 	rel := ""
+	class := ""
 
 	// Get start tag
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child.Type() == htmlNodeStartTag || child.Type() == htmlNodeSelfClosing {
-			tagName, id, name, href, rel = p.extractTagInfo(child, content)
+			tagName, id, name, href, rel, class = p.extractTagInfo(child, content)
 			break
 		}
 	}
@@ -282,6 +292,9 @@ func (p *HTMLParser) extractElement(ctx context.Context, node *sitter.Node, cont
 			ParsedAtMilli: time.Now().UnixMilli(),
 			Exported:      true,
 		}
+		if classes := htmlClassNames(class); len(classes) > 0 {
+			sym.Metadata = &SymbolMetadata{HTMLClasses: classes}
+		}
 		result.Symbols = append(result.Symbols, sym)
 	}
 
@@ -360,7 +373,7 @@ func (p *HTMLParser) extractElement(ctx context.Context, node *sitter.Node, cont
 }
 
 // extractTagInfo extracts tag information from a start_tag or self_closing_tag.
-func (p *HTMLParser) extractTagInfo(node *sitter.Node, content []byte) (tagName, id, name, href, rel string) {
+func (p *HTMLParser) extractTagInfo(node *sitter.Node, content []byte) (tagName, id, name, href, rel, class string) {
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		switch child.Type() {
@@ -377,6 +390,8 @@ func (p *HTMLParser) extractTagInfo(node *sitter.Node, content []byte) (tagName,
 				href = attrValue
 			case "rel":
 				rel = attrValue
+			case "class":
+				class = attrValue
 			}
 		}
 	}
@@ -534,3 +549,92 @@ func (p *HTMLParser) extractStyle(ctx context.Context, node *sitter.Node, conten
 		}
 	}
 }
+
+// htmlTemplateAction matches the Go template actions that define or include
+// a named template: {{define "name"}}, {{block "name" .}} and
+// {{template "name" .}}, with optional trim markers and either quote style.
+var htmlTemplateAction = regexp.MustCompile("\\{\\{-?\\s*(define|block|template)\\s+(?:\"([^\"]+)\"|`([^`]+)`)")
+
+// fileSymbol returns the file symbol of an HTML file, or nil if the file
+// neither contains Go template actions ({{ ... }}) nor has classes on
+// elements without an id.
+//
+// Description:
+//
+//	The symbol is named after the file's base name, which is how Go code
+//	usually executes a template file. Its metadata lists the templates the
+//	file defines and includes, and the classes of its elements without an
+//	id. Elements with an id carry their own classes.
+func (p *HTMLParser) fileSymbol(root *sitter.Node, content []byte, filePath string) *Symbol {
+	meta := &SymbolMetadata{}
+	for _, m := range htmlTemplateAction.FindAllSubmatch(content, -1) {
+		name := string(m[2])
+		if name == "" {
+			name = string(m[3])
+		}
+		if string(m[1]) == "template" {
+			meta.TemplateRefs = appendUnique(meta.TemplateRefs, name)
+		} else {
+			meta.TemplateDefines = appendUnique(meta.TemplateDefines, name)
+		}
+	}
+	p.collectAnonymousClasses(root, content, meta)
+	if !bytes.Contains(content, []byte("{{")) && len(meta.HTMLClasses) == 0 {
+		return nil
+	}
+
+	base := filepath.Base(filePath)
+	return &Symbol{
+		ID:            GenerateID(filePath, 1, "file:"+base),
+		Name:          base,
+		Kind:          SymbolKindFile,
+		FilePath:      filePath,
+		StartLine:     1,
+		EndLine:       int(root.EndPoint().Row) + 1,
+		Signature:     base,
+		Language:      "html",
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Exported:      true,
+		Metadata:      meta,
+	}
+}
+
+// collectAnonymousClasses adds the classes of elements without an id under
+// node to meta.HTMLClasses.
+func (p *HTMLParser) collectAnonymousClasses(node *sitter.Node, content []byte, meta *SymbolMetadata) {
+	if node == nil {
+		return
+	}
+	if node.Type() == htmlNodeStartTag || node.Type() == htmlNodeSelfClosing {
+		if _, id, _, _, _, class := p.extractTagInfo(node, content); id == "" {
+			for _, c := range htmlClassNames(class) {
+				meta.HTMLClasses = appendUnique(meta.HTMLClasses, c)
+			}
+		}
+		return
+	}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		p.collectAnonymousClasses(node.Child(i), content, meta)
+	}
+}
+
+// htmlClassNames splits a class attribute into class names, dropping names
+// built by template actions ("{{.Active}}"), which can't be matched.
+func htmlClassNames(class string) []string {
+	var names []string
+	for _, name := range strings.Fields(class) {
+		if strings.Contains(name, "{{") || strings.Contains(name, "}}") {
+			continue
+		}
+		names = appendUnique(names, name)
+	}
+	return names
+}
+
+// appendUnique appends value to values unless it is already present.
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
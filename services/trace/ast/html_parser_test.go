@@ -79,6 +79,53 @@ func TestHTMLParser_Parse_ElementWithID(t *testing.T) {
 	}
 }
 
+func TestHTMLParser_Parse_TemplatesAndClasses(t *testing.T) {
+	content := []byte(`{{define "layout"}}
+<div id="main" class="panel wide {{.Extra}}">
+  <span class="badge">{{template "header" .}}</span>
+  {{- block "footer" .}}<p class="note">x</p>{{end}}
+  {{template ` + "`header`" + `}}
+</div>
+{{end}}`)
+	result, err := NewHTMLParser().Parse(context.Background(), content, "web/templates/layout.html")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	elements := filterSymbolsByKind(result.Symbols, SymbolKindElement)
+	if len(elements) != 1 || elements[0].Metadata == nil ||
+		strings.Join(elements[0].Metadata.HTMLClasses, " ") != "panel wide" {
+		t.Fatalf("elements = %+v, want main with classes panel wide", elements)
+	}
+
+	files := filterSymbolsByKind(result.Symbols, SymbolKindFile)
+	if len(files) != 1 {
+		t.Fatalf("got %d file symbols, want 1", len(files))
+	}
+	file := files[0]
+	if file.Name != "layout.html" || file.StartLine != 1 || file.EndLine != 7 || file.Language != "html" {
+		t.Errorf("file symbol = %s lines %d-%d %s", file.Name, file.StartLine, file.EndLine, file.Language)
+	}
+	if got := strings.Join(file.Metadata.TemplateDefines, ","); got != "layout,footer" {
+		t.Errorf("TemplateDefines = %q, want layout,footer", got)
+	}
+	if got := strings.Join(file.Metadata.TemplateRefs, ","); got != "header" {
+		t.Errorf("TemplateRefs = %q, want header", got)
+	}
+	if got := strings.Join(file.Metadata.HTMLClasses, ","); got != "badge,note" {
+		t.Errorf("HTMLClasses = %q, want the classes of elements without an id", got)
+	}
+
+	// A plain page gets no file symbol.
+	plain, err := NewHTMLParser().Parse(context.Background(), []byte(`<div id="x" class="a"></div>`), "plain.html")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if files := filterSymbolsByKind(plain.Symbols, SymbolKindFile); len(files) != 0 {
+		t.Errorf("plain page file symbols = %+v, want none", files)
+	}
+}
+
 func TestHTMLParser_Parse_FormWithName(t *testing.T) {
 	parser := NewHTMLParser()
 	ctx := context.Background()
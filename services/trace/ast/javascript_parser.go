@@ -17,6 +17,8 @@ import (
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/javascript"
 	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // JavaScriptParser extracts symbols from JavaScript source code.
@@ -60,6 +62,20 @@ type JavaScriptParserOptions struct {
 	// ExtractBodies determines whether to include function body text.
 	// Default: false (bodies are expensive and often not needed)
 	ExtractBodies bool
+
+	// ExtractBodyTokens sets Symbol.BodyTokens on functions and methods.
+	// Default: false
+	ExtractBodyTokens bool
+
+	// CaptureLiteralArgs keeps the literal arguments of every call, as
+	// ParseOptions.CaptureLiteralArgs.
+	// Default: false
+	CaptureLiteralArgs bool
+
+	// KeepSensitiveLiterals turns off redaction of sensitive-looking
+	// literal arguments, as ParseOptions.KeepSensitiveLiterals.
+	// Default: false
+	KeepSensitiveLiterals bool
 }
 
 // DefaultJavaScriptParserOptions returns the default options.
@@ -95,6 +111,29 @@ func WithJSExtractBodies(extract bool) JavaScriptParserOption {
 	}
 }
 
+// WithJSExtractBodyTokens sets whether to attach normalized body tokens.
+func WithJSExtractBodyTokens(extract bool) JavaScriptParserOption {
+	return func(o *JavaScriptParserOptions) {
+		o.ExtractBodyTokens = extract
+	}
+}
+
+// WithJSCaptureLiteralArgs sets whether to keep the literal arguments of
+// every call.
+func WithJSCaptureLiteralArgs(capture bool) JavaScriptParserOption {
+	return func(o *JavaScriptParserOptions) {
+		o.CaptureLiteralArgs = capture
+	}
+}
+
+// WithJSKeepSensitiveLiterals sets whether to keep sensitive-looking
+// literal arguments unredacted.
+func WithJSKeepSensitiveLiterals(keep bool) JavaScriptParserOption {
+	return func(o *JavaScriptParserOptions) {
+		o.KeepSensitiveLiterals = keep
+	}
+}
+
 // NewJavaScriptParser creates a new JavaScriptParser with the given options.
 //
 // Description:
@@ -232,6 +271,11 @@ func (p *JavaScriptParser) Parse(ctx context.Context, content []byte, filePath s
 		p.emitSyntheticClassSymbols(exportAliases, filePath, result)
 	}
 
+	// Normalized body tokens for clone detection
+	if p.options.ExtractBodyTokens {
+		attachBodyTokens(rootNode, content, result.Symbols)
+	}
+
 	// Validate result
 	if err := result.Validate(); err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("validation error: %v", err))
@@ -824,6 +868,15 @@ func (p *JavaScriptParser) extractMethod(ctx context.Context, node *sitter.Node,
 		}
 	}
 
+	// Fluent methods: record what the body returns so the builder can
+	// follow call chains through this method.
+	if returnType := inferJSReturnType(bodyNode, content, 0); returnType != "" {
+		if sym.Metadata == nil {
+			sym.Metadata = &SymbolMetadata{}
+		}
+		sym.Metadata.ReturnType = returnType
+	}
+
 	// GR-41: Extract call sites from method body
 	var dynImps []Import
 	if bodyNode != nil {
@@ -833,6 +886,93 @@ func (p *JavaScriptParser) extractMethod(ctx context.Context, node *sitter.Node,
 	return sym, dynImps
 }
 
+// inferJSReturnType infers the return type of an unannotated JavaScript
+// method from its return statements.
+//
+// Description:
+//
+//	Returns "this" when every return statement returns this (a fluent
+//	method), or the class name when every one returns new Name(...).
+//	Nested functions and classes are skipped; their returns are not the
+//	method's.
+//
+// Inputs:
+//   - node: The method body. May be nil.
+//   - content: Source file bytes.
+//   - depth: Current recursion depth. Stops at MaxCallExpressionDepth.
+//
+// Outputs:
+//   - string: "this", a class name, or "" when the body has no return
+//     statement or its returns differ or are anything else.
+//
+// Thread Safety: Safe for concurrent use.
+func inferJSReturnType(node *sitter.Node, content []byte, depth int) string {
+	if node == nil || depth > MaxCallExpressionDepth {
+		return ""
+	}
+	inferred := ""
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		var got string
+		switch child.Type() {
+		case jsNodeFunctionDeclaration, jsNodeFunctionExpression, jsNodeArrowFunction,
+			jsNodeGeneratorFunction, jsNodeGeneratorFunctionDecl, jsNodeClassDeclaration, jsNodeClass:
+			continue
+		case jsNodeReturnStatement:
+			value := child.NamedChild(0)
+			switch {
+			case value == nil:
+				return ""
+			case value.Type() == "this":
+				got = "this"
+			case value.Type() == jsNodeNewExpression:
+				ctor := value.ChildByFieldName("constructor")
+				if ctor == nil || ctor.Type() != jsNodeIdentifier {
+					return ""
+				}
+				got = ctor.Content(content)
+			default:
+				return ""
+			}
+		default:
+			got = inferJSReturnType(child, content, depth+1)
+			if got == "" {
+				if hasJSReturn(child, depth+1) {
+					return ""
+				}
+				continue
+			}
+		}
+		if inferred != "" && got != inferred {
+			return ""
+		}
+		inferred = got
+	}
+	return inferred
+}
+
+// hasJSReturn reports whether node contains a return statement outside
+// nested functions and classes.
+func hasJSReturn(node *sitter.Node, depth int) bool {
+	if depth > MaxCallExpressionDepth {
+		return false
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case jsNodeFunctionDeclaration, jsNodeFunctionExpression, jsNodeArrowFunction,
+			jsNodeGeneratorFunction, jsNodeGeneratorFunctionDecl, jsNodeClassDeclaration, jsNodeClass:
+			continue
+		case jsNodeReturnStatement:
+			return true
+		}
+		if hasJSReturn(child, depth+1) {
+			return true
+		}
+	}
+	return false
+}
+
 // extractField extracts a field definition from a class.
 //
 // IT-R2d F.1: Now accepts ctx and returns []Import. Detects arrow function or
@@ -1313,9 +1453,9 @@ func (p *JavaScriptParser) extractExportsRequireImport(node *sitter.Node, conten
 		})
 
 		slog.Debug("IT-06e Bug 2: exports.X = require() import extracted",
-			slog.String("file", filePath),
-			slog.String("alias", alias),
-			slog.String("path", requirePath),
+			telemetry.SensitiveLog("file", filePath),
+			telemetry.SensitiveLog("alias", alias),
+			telemetry.SensitiveLog("path", requirePath),
 		)
 	}
 }
@@ -1391,8 +1531,8 @@ func (p *JavaScriptParser) scanForDynamicImports(rootNode *sitter.Node, content
 								},
 							})
 							slog.Debug("IT-06e Bug 4: dynamic import() in initializer expression",
-								slog.String("file", filePath),
-								slog.String("path", importPath),
+								telemetry.SensitiveLog("file", filePath),
+								telemetry.SensitiveLog("path", importPath),
 							)
 						}
 						break
@@ -1595,7 +1735,7 @@ func (p *JavaScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 
 		if entry.depth > MaxCallExpressionDepth {
 			slog.Debug("GR-41: Max call expression depth reached in JavaScript",
-				slog.String("file", filePath),
+				telemetry.SensitiveLog("file", filePath),
 				slog.Int("depth", entry.depth),
 			)
 			continue
@@ -1605,7 +1745,7 @@ func (p *JavaScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 		if nodeCount%100 == 0 {
 			if ctx.Err() != nil {
 				slog.Debug("GR-41: Context cancelled during JavaScript call extraction",
-					slog.String("file", filePath),
+					telemetry.SensitiveLog("file", filePath),
 					slog.Int("calls_found", len(calls)),
 				)
 				return calls, dynImports
@@ -1614,7 +1754,7 @@ func (p *JavaScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 
 		if len(calls) >= MaxCallSitesPerSymbol {
 			slog.Warn("GR-41: Max call sites per symbol reached in JavaScript",
-				slog.String("file", filePath),
+				telemetry.SensitiveLog("file", filePath),
 				slog.Int("limit", MaxCallSitesPerSymbol),
 			)
 			return calls, dynImports
@@ -1645,8 +1785,8 @@ func (p *JavaScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 								},
 							})
 							slog.Debug("IT-06e Bug 4: dynamic import() in function body",
-								slog.String("file", filePath),
-								slog.String("path", importPath),
+								telemetry.SensitiveLog("file", filePath),
+								telemetry.SensitiveLog("path", importPath),
 							)
 						}
 						break
@@ -1684,7 +1824,7 @@ func (p *JavaScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 	}
 
 	span.SetAttributes(
-		attribute.String("file", filePath),
+		telemetry.SensitiveString("file", filePath),
 		attribute.Int("calls_found", len(calls)),
 		attribute.Int("nodes_traversed", nodeCount),
 	)
@@ -1756,6 +1896,7 @@ func (p *JavaScriptParser) extractSingleCallSite(node *sitter.Node, content []by
 			receiver := string(content[objectNode.StartByte():objectNode.EndByte()])
 			call.Receiver = receiver
 			call.IsMethod = true
+			call.Chain = extractCallChain(jsCallChainSyntax, funcNode, content)
 		}
 
 	default:
@@ -1774,6 +1915,11 @@ func (p *JavaScriptParser) extractSingleCallSite(node *sitter.Node, content []by
 	argsNode := node.ChildByFieldName("arguments")
 	if argsNode != nil {
 		call.FunctionArgs = p.extractCallbackArgIdentifiers(argsNode, content)
+		if len(call.FunctionArgs) > 0 || p.options.CaptureLiteralArgs || keepsLinkageLiterals(call.Target) {
+			capture := newLiteralArgCapture(filePath, content, p.options.CaptureLiteralArgs, p.options.KeepSensitiveLiterals)
+			captureJSLiteralArgs(capture, argsNode)
+			call.LiteralArgs = capture.finish(call.Target)
+		}
 	}
 
 	return call
@@ -1852,6 +1998,12 @@ func (p *JavaScriptParser) extractNewExpressionCallSite(node *sitter.Node, conte
 		return nil
 	}
 
+	if argsNode := node.ChildByFieldName("arguments"); argsNode != nil && p.options.CaptureLiteralArgs {
+		capture := newLiteralArgCapture(filePath, content, true, p.options.KeepSensitiveLiterals)
+		captureJSLiteralArgs(capture, argsNode)
+		call.LiteralArgs = capture.finish(call.Target)
+	}
+
 	return call
 }
 
@@ -1972,7 +2124,7 @@ func (p *JavaScriptParser) buildModuleExportAliases(rootNode *sitter.Node, conte
 
 	if len(aliases) > 0 {
 		slog.Debug("IT-01 Phase C: module export aliases detected",
-			slog.String("file", filePath),
+			telemetry.SensitiveLog("file", filePath),
 			slog.String("semantic_type", semanticName),
 			slog.Int("alias_count", len(aliases)),
 		)
@@ -2081,8 +2233,8 @@ func (p *JavaScriptParser) emitSyntheticClassSymbols(exportAliases map[string]st
 		emitted[semanticName] = true
 
 		slog.Debug("IT-06b: emitted synthetic class symbol",
-			slog.String("name", semanticName),
-			slog.String("file", filePath),
+			telemetry.SensitiveLog("name", semanticName),
+			telemetry.SensitiveLog("file", filePath),
 			slog.String("alias_of", varName),
 			slog.Int("children", len(children)),
 		)
@@ -3392,3 +3392,33 @@ class Service {
 		t.Errorf("expected processor to have >= 2 call sites, got %d", len(processor.Calls))
 	}
 }
+
+func TestJavaScriptParser_CallLiteralArgs(t *testing.T) {
+	src := "function register(app) {\n" +
+		"  app.get('/users', auth, listUsers);\n" +
+		"  app.post(`/users/${id}`, createUser);\n" +
+		"  console.log('no callback');\n" +
+		"}\n"
+	result, err := NewJavaScriptParser().Parse(context.Background(), []byte(src), "routes.js")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := make(map[string]CallSite)
+	for _, sym := range result.Symbols {
+		if sym.Name == "register" {
+			for _, call := range sym.Calls {
+				calls[call.Target] = call
+			}
+		}
+	}
+	if got := calls["get"].LiteralArgs; len(got) != 1 || got[0].Value != "/users" {
+		t.Errorf("get literals = %v", got)
+	}
+	if got := calls["post"].LiteralArgs; len(got) != 0 {
+		t.Errorf("template with substitution should be skipped: %v", got)
+	}
+	if got := calls["log"].LiteralArgs; len(got) != 0 {
+		t.Errorf("literals without a callback should not be kept: %v", got)
+	}
+}
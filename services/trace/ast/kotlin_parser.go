@@ -0,0 +1,992 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/kotlin"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// KotlinParserOption configures a KotlinParser instance.
+type KotlinParserOption func(*KotlinParser)
+
+// WithKotlinMaxFileSize sets the maximum file size the parser will accept.
+//
+// Parameters:
+//   - bytes: Maximum file size in bytes. Must be positive.
+//
+// Example:
+//
+//	parser := NewKotlinParser(WithKotlinMaxFileSize(5 * 1024 * 1024)) // 5MB limit
+func WithKotlinMaxFileSize(bytes int64) KotlinParserOption {
+	return func(p *KotlinParser) {
+		if bytes > 0 {
+			p.maxFileSize = bytes
+		}
+	}
+}
+
+// WithKotlinParseOptions applies the given ParseOptions to the parser.
+//
+// Parameters:
+//   - opts: ParseOptions to apply.
+//
+// Example:
+//
+//	parser := NewKotlinParser(WithKotlinParseOptions(ParseOptions{IncludePrivate: false}))
+func WithKotlinParseOptions(opts ParseOptions) KotlinParserOption {
+	return func(p *KotlinParser) {
+		p.parseOptions = opts
+	}
+}
+
+// KotlinParser implements the Parser interface for Kotlin source code.
+//
+// Description:
+//
+//	KotlinParser uses tree-sitter to parse .kt and .kts files and extract
+//	symbols, mapped onto the existing symbol kinds:
+//
+//	  class, data class, sealed class -> SymbolKindClass
+//	  object                          -> SymbolKindClass
+//	  interface                       -> SymbolKindInterface, with
+//	                                     Metadata.Methods for implicit
+//	                                     implementation detection
+//	  enum class                      -> SymbolKindEnum, entries as
+//	                                     SymbolKindEnumMember children
+//	  member function                 -> SymbolKindMethod, Receiver = class
+//	  extension function              -> SymbolKindMethod, Receiver = the
+//	                                     extended type ("String" for
+//	                                     fun String.shout())
+//	  top-level function              -> SymbolKindFunction
+//	  property                        -> SymbolKindField in a class,
+//	                                     SymbolKindVariable or
+//	                                     SymbolKindConstant at top level
+//	  typealias                       -> SymbolKindType
+//
+//	Suspend functions set Metadata.IsAsync. A supertype written as a
+//	constructor call (Base()) is the superclass and goes to
+//	Metadata.Extends; the others are interfaces and go to
+//	Metadata.Implements. Companion object members are attached to the
+//	enclosing class with Metadata.IsStatic. Annotations are recorded in
+//	Metadata.Decorators. Call sites are extracted from function bodies,
+//	property accessors, init blocks and secondary constructors.
+//
+// Thread Safety:
+//
+//	KotlinParser instances are safe for concurrent use. Each Parse call
+//	creates its own tree-sitter parser instance.
+//
+// Example:
+//
+//	parser := NewKotlinParser()
+//	result, err := parser.Parse(ctx, content, "app/src/main/kotlin/UserRepository.kt")
+//	if err != nil {
+//	    return fmt.Errorf("parse: %w", err)
+//	}
+//	for _, sym := range result.Symbols {
+//	    fmt.Printf("%s: %s\n", sym.Kind, sym.Name)
+//	}
+type KotlinParser struct {
+	maxFileSize  int64
+	parseOptions ParseOptions
+}
+
+// NewKotlinParser creates a new KotlinParser with the given options.
+//
+// Inputs:
+//   - opts: Optional configuration functions (WithKotlinMaxFileSize, WithKotlinParseOptions)
+//
+// Outputs:
+//   - *KotlinParser: Configured parser instance, never nil
+//
+// Thread Safety:
+//
+//	The returned KotlinParser is safe for concurrent use.
+func NewKotlinParser(opts ...KotlinParserOption) *KotlinParser {
+	p := &KotlinParser{
+		maxFileSize:  DefaultMaxFileSize,
+		parseOptions: DefaultParseOptions(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Language returns the canonical language name for this parser.
+func (p *KotlinParser) Language() string {
+	return "kotlin"
+}
+
+// Extensions returns the file extensions this parser handles.
+func (p *KotlinParser) Extensions() []string {
+	return []string{".kt", ".kts"}
+}
+
+// kotlinFile holds the per-file state of one Parse call.
+type kotlinFile struct {
+	content  []byte
+	filePath string
+	pkg      string
+	lines    []string
+}
+
+// Parse extracts symbols from Kotlin source code.
+//
+// Description:
+//
+//	Parses the provided Kotlin source with tree-sitter and extracts
+//	classes, objects, interfaces, functions, properties and imports. The
+//	parser is error-tolerant: syntax errors are reported in
+//	ParseResult.Errors and the remaining declarations are still extracted.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked before and after parsing.
+//   - content: Raw Kotlin source bytes. Must be valid UTF-8.
+//   - filePath: Path to the file, relative to the project root.
+//
+// Outputs:
+//   - *ParseResult: Extracted symbols and metadata. Never nil on success.
+//   - error: Non-nil for complete failures:
+//   - ErrFileTooLarge: Content exceeds maxFileSize
+//   - ErrInvalidContent: Content is not valid UTF-8
+//   - Context errors: Context was canceled or timed out
+//
+// Thread Safety:
+//
+//	This method is safe for concurrent use.
+func (p *KotlinParser) Parse(ctx context.Context, content []byte, filePath string) (*ParseResult, error) {
+	ctx, span := startParseSpan(ctx, "kotlin", filePath, len(content))
+	defer span.End()
+
+	start := time.Now()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "kotlin", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled before start: %w", err)
+	}
+
+	if int64(len(content)) > p.maxFileSize {
+		recordParseMetrics(ctx, "kotlin", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: size %d exceeds limit %d", ErrFileTooLarge, len(content), p.maxFileSize)
+	}
+
+	if !utf8.Valid(content) {
+		recordParseMetrics(ctx, "kotlin", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: content is not valid UTF-8", ErrInvalidContent)
+	}
+
+	hash := sha256.Sum256(content)
+	hashStr := hex.EncodeToString(hash[:])
+
+	// New tree-sitter parser per call for thread safety
+	parser := sitter.NewParser()
+	parser.SetLanguage(kotlin.GetLanguage())
+
+	tree, err := parser.ParseCtx(ctx, nil, content)
+	if err != nil {
+		recordParseMetrics(ctx, "kotlin", time.Since(start), 0, false)
+		return nil, fmt.Errorf("tree-sitter parse failed: %w", err)
+	}
+	defer tree.Close()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "kotlin", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled after tree-sitter: %w", err)
+	}
+
+	result := &ParseResult{
+		FilePath:      filePath,
+		Language:      "kotlin",
+		Hash:          hashStr,
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Symbols:       make([]*Symbol, 0),
+		Imports:       make([]Import, 0),
+		Errors:        make([]string, 0),
+	}
+
+	rootNode := tree.RootNode()
+	if rootNode == nil {
+		result.Errors = append(result.Errors, "tree-sitter returned nil root node")
+		return result, nil
+	}
+
+	f := &kotlinFile{
+		content:  content,
+		filePath: filePath,
+		lines:    strings.Split(string(content), "\n"),
+	}
+	p.extractSymbols(ctx, rootNode, f, result)
+
+	if err := result.Validate(); err != nil {
+		recordParseMetrics(ctx, "kotlin", time.Since(start), 0, false)
+		return nil, fmt.Errorf("result validation failed: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "kotlin", time.Since(start), len(result.Symbols), false)
+		return nil, fmt.Errorf("parse canceled after extraction: %w", err)
+	}
+
+	setParseSpanResult(span, len(result.Symbols), len(result.Errors))
+	recordParseMetrics(ctx, "kotlin", time.Since(start), len(result.Symbols), true)
+
+	return result, nil
+}
+
+// extractSymbols extracts the package, imports and top-level declarations.
+func (p *KotlinParser) extractSymbols(ctx context.Context, root *sitter.Node, f *kotlinFile, result *ParseResult) {
+	for i := 0; i < int(root.ChildCount()); i++ {
+		child := root.Child(i)
+		if child.Type() == kotlinNodePackageHeader {
+			if ident := kotlinChildByType(child, kotlinNodeIdentifier); ident != nil {
+				f.pkg = kotlinNodeText(ident, f.content)
+				result.Package = f.pkg
+			}
+		}
+	}
+
+	for i := 0; i < int(root.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			return
+		}
+
+		child := root.Child(i)
+		var sym *Symbol
+		switch child.Type() {
+		case kotlinNodeImportList:
+			for j := 0; j < int(child.ChildCount()); j++ {
+				if header := child.Child(j); header.Type() == kotlinNodeImportHeader {
+					p.extractImport(header, f, result)
+				}
+			}
+		case kotlinNodeClassDecl, kotlinNodeObjectDecl:
+			sym = p.extractClass(ctx, child, f, "")
+		case kotlinNodeFunctionDecl:
+			sym = p.extractFunction(ctx, child, f, "")
+		case kotlinNodePropertyDecl:
+			sym = p.extractProperty(ctx, child, f, "")
+		case kotlinNodeTypeAlias:
+			sym = p.extractTypeAlias(child, f)
+		case kotlinNodeERROR:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s:%d: syntax error: %s",
+				f.filePath, int(child.StartPoint().Row)+1, sqlSnippet(f.content[child.StartByte():child.EndByte()])))
+		}
+		if sym != nil && p.include(sym) {
+			result.Symbols = append(result.Symbols, sym)
+		}
+	}
+}
+
+// extractImport records an import directive. "import a.b.C as D" becomes
+// Path "a.b.C", Names ["C"], Alias "D"; "import a.b.*" becomes Path "a.b"
+// with IsWildcard.
+func (p *KotlinParser) extractImport(node *sitter.Node, f *kotlinFile, result *ParseResult) {
+	ident := kotlinChildByType(node, kotlinNodeIdentifier)
+	if ident == nil {
+		return
+	}
+	imp := Import{
+		Path:     kotlinNodeText(ident, f.content),
+		Location: kotlinLocation(node, f.filePath),
+	}
+	if kotlinChildByType(node, kotlinNodeWildcard) != nil {
+		imp.IsWildcard = true
+	} else {
+		imp.Names = []string{imp.Path[strings.LastIndexByte(imp.Path, '.')+1:]}
+	}
+	if alias := kotlinChildByType(node, kotlinNodeImportAlias); alias != nil {
+		if name := kotlinChildByType(alias, kotlinNodeTypeIdentifier); name != nil {
+			imp.Alias = kotlinNodeText(name, f.content)
+		}
+	}
+	result.Imports = append(result.Imports, imp)
+}
+
+// extractClass builds a class, interface, enum or object symbol with its
+// members as children. owner is the enclosing class name, or "".
+func (p *KotlinParser) extractClass(ctx context.Context, node *sitter.Node, f *kotlinFile, owner string) *Symbol {
+	nameNode := kotlinChildByType(node, kotlinNodeTypeIdentifier)
+	if nameNode == nil {
+		return nil
+	}
+	name := kotlinNodeText(nameNode, f.content)
+	mods := kotlinParseModifiers(node, f.content)
+
+	kind := SymbolKindClass
+	var keyword *sitter.Node
+	var body *sitter.Node
+	var superclass string
+	var interfaces []string
+	var ctorParams *sitter.Node
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case kotlinKeywordInterface:
+			kind = SymbolKindInterface
+		case kotlinKeywordEnum:
+			kind = SymbolKindEnum
+		case kotlinNodeClassBody, kotlinNodeEnumClassBody:
+			body = child
+		case kotlinNodePrimaryCtor:
+			ctorParams = child
+		case kotlinNodeDelegationSpec:
+			if invocation := kotlinChildByType(child, kotlinNodeCtorInvocation); invocation != nil {
+				superclass = kotlinTypeName(invocation.NamedChild(0), f.content)
+			} else if child.NamedChildCount() > 0 {
+				interfaces = append(interfaces, kotlinTypeName(child.NamedChild(0), f.content))
+			}
+		}
+		if keyword == nil && !child.IsNamed() && child.Type() != "@" {
+			keyword = child
+		}
+	}
+
+	// Signature: class modifiers, then the declaration up to the body.
+	sigEnd := node.EndByte()
+	if body != nil {
+		sigEnd = body.StartByte()
+	}
+	sigStart := nameNode.StartByte()
+	if keyword != nil {
+		sigStart = keyword.StartByte()
+	}
+	signature := kotlinCollapseSpace(string(f.content[sigStart:sigEnd]))
+	if len(mods.keywords) > 0 {
+		signature = strings.Join(mods.keywords, " ") + " " + signature
+	}
+
+	sym := p.newSymbol(node, f, name, kind, signature)
+	p.applyModifiers(sym, mods)
+	if mods.has("abstract") || mods.has("sealed") {
+		kotlinMetadata(sym).IsAbstract = true
+	}
+	if owner != "" {
+		kotlinMetadata(sym).ParentName = owner
+	}
+
+	// Interfaces list their super-interfaces like Go interface embeds:
+	// the first in Extends, the rest in Implements.
+	if kind == SymbolKindInterface && len(interfaces) > 0 {
+		superclass, interfaces = interfaces[0], interfaces[1:]
+	}
+	if superclass != "" {
+		kotlinMetadata(sym).Extends = superclass
+	}
+	if len(interfaces) > 0 {
+		kotlinMetadata(sym).Implements = interfaces
+	}
+
+	if ctorParams != nil {
+		for i := 0; i < int(ctorParams.ChildCount()); i++ {
+			param := ctorParams.Child(i)
+			if param.Type() != kotlinNodeClassParameter || kotlinChildByType(param, kotlinNodeBindingPattern) == nil {
+				continue
+			}
+			if field := p.extractClassParameter(param, f, name); field != nil && p.include(field) {
+				sym.Children = append(sym.Children, field)
+			}
+		}
+	}
+	if body != nil {
+		p.extractMembers(ctx, body, f, sym, false)
+	}
+	kotlinCollectMethods(sym)
+	return sym
+}
+
+// extractMembers adds the declarations in a class body to classSym.
+// static is true for companion object bodies.
+func (p *KotlinParser) extractMembers(ctx context.Context, body *sitter.Node, f *kotlinFile, classSym *Symbol, static bool) {
+	for i := 0; i < int(body.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			return
+		}
+		child := body.Child(i)
+		var member *Symbol
+		switch child.Type() {
+		case kotlinNodeFunctionDecl:
+			member = p.extractFunction(ctx, child, f, classSym.Name)
+		case kotlinNodePropertyDecl:
+			member = p.extractProperty(ctx, child, f, classSym.Name)
+		case kotlinNodeClassDecl, kotlinNodeObjectDecl:
+			member = p.extractClass(ctx, child, f, classSym.Name)
+		case kotlinNodeSecondaryCtor, kotlinNodeInitializer:
+			member = p.extractConstructor(ctx, child, f, classSym.Name)
+		case kotlinNodeEnumEntry:
+			if ident := kotlinChildByType(child, kotlinNodeSimpleIdentifier); ident != nil {
+				entry := kotlinNodeText(ident, f.content)
+				member = p.newSymbol(child, f, entry, SymbolKindEnumMember, entry)
+				kotlinMetadata(member).ParentName = classSym.Name
+			}
+		case kotlinNodeCompanionObject:
+			if companionBody := kotlinChildByType(child, kotlinNodeClassBody); companionBody != nil {
+				p.extractMembers(ctx, companionBody, f, classSym, true)
+			}
+		}
+		if member == nil || !p.include(member) {
+			continue
+		}
+		if static && member.Kind != SymbolKindClass {
+			kotlinMetadata(member).IsStatic = true
+		}
+		classSym.Children = append(classSym.Children, member)
+	}
+}
+
+// extractFunction builds a function symbol. Inside a class (owner != "")
+// it is a method with Receiver = owner; an extension function is a method
+// with Receiver = the extended type.
+func (p *KotlinParser) extractFunction(ctx context.Context, node *sitter.Node, f *kotlinFile, owner string) *Symbol {
+	var nameNode, funKeyword, params, returnType, body, typeParams *sitter.Node
+	var receiverType *sitter.Node
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case kotlinKeywordFun:
+			funKeyword = child
+		case kotlinNodeTypeParameters:
+			typeParams = child
+		case kotlinNodeSimpleIdentifier:
+			if nameNode == nil {
+				nameNode = child
+			}
+		case ".":
+			if nameNode == nil && i > 0 {
+				receiverType = node.Child(i - 1)
+			}
+		case kotlinNodeValueParams:
+			params = child
+		case ":":
+			if params != nil && i+1 < int(node.ChildCount()) {
+				returnType = node.Child(i + 1)
+			}
+		case kotlinNodeFunctionBody:
+			body = child
+		}
+	}
+	if nameNode == nil {
+		return nil
+	}
+	name := kotlinNodeText(nameNode, f.content)
+	mods := kotlinParseModifiers(node, f.content)
+
+	sigStart := nameNode.StartByte()
+	if funKeyword != nil {
+		sigStart = funKeyword.StartByte()
+	}
+	sigEnd := nameNode.EndByte()
+	switch {
+	case returnType != nil:
+		sigEnd = returnType.EndByte()
+	case params != nil:
+		sigEnd = params.EndByte()
+	}
+	signature := kotlinCollapseSpace(string(f.content[sigStart:sigEnd]))
+	if mods.has("suspend") {
+		signature = "suspend " + signature
+	}
+
+	kind := SymbolKindFunction
+	receiver := owner
+	if receiverType != nil && owner == "" {
+		receiver = kotlinTypeName(receiverType, f.content)
+	}
+	if receiver != "" {
+		kind = SymbolKindMethod
+	}
+
+	sym := p.newSymbol(node, f, name, kind, signature)
+	sym.Receiver = receiver
+	p.applyModifiers(sym, mods)
+	if mods.has("suspend") {
+		kotlinMetadata(sym).IsAsync = true
+	}
+	if mods.has("abstract") {
+		kotlinMetadata(sym).IsAbstract = true
+	}
+	if returnType != nil {
+		kotlinMetadata(sym).ReturnType = strings.TrimSuffix(kotlinNodeText(returnType, f.content), "?")
+	}
+	if typeParams != nil {
+		for i := 0; i < int(typeParams.ChildCount()); i++ {
+			if tp := typeParams.Child(i); tp.Type() == kotlinNodeTypeParameter {
+				if ident := kotlinChildByType(tp, kotlinNodeTypeIdentifier); ident != nil {
+					meta := kotlinMetadata(sym)
+					meta.TypeParameters = append(meta.TypeParameters, kotlinNodeText(ident, f.content))
+				}
+			}
+		}
+	}
+	if owner != "" {
+		kotlinMetadata(sym).ParentName = owner
+	}
+	if body != nil {
+		sym.Calls = p.extractCallSites(ctx, body, f)
+	}
+	return sym
+}
+
+// extractConstructor builds a method symbol for a secondary constructor
+// ("constructor") or init block ("init") so their calls get edges.
+func (p *KotlinParser) extractConstructor(ctx context.Context, node *sitter.Node, f *kotlinFile, owner string) *Symbol {
+	name := "init"
+	signature := "init"
+	if node.Type() == kotlinNodeSecondaryCtor {
+		name = "constructor"
+		signature = name
+		if params := kotlinChildByType(node, kotlinNodeValueParams); params != nil {
+			signature += kotlinCollapseSpace(kotlinNodeText(params, f.content))
+		}
+	}
+	sym := p.newSymbol(node, f, name, SymbolKindMethod, signature)
+	sym.Receiver = owner
+	p.applyModifiers(sym, kotlinParseModifiers(node, f.content))
+	meta := kotlinMetadata(sym)
+	meta.IsConstructor = true
+	meta.ParentName = owner
+	sym.Calls = p.extractCallSites(ctx, node, f)
+	return sym
+}
+
+// extractProperty builds a property symbol: a field inside a class, a
+// variable or constant at top level. Calls in its accessors are kept.
+func (p *KotlinParser) extractProperty(ctx context.Context, node *sitter.Node, f *kotlinFile, owner string) *Symbol {
+	decl := kotlinChildByType(node, kotlinNodeVariableDecl)
+	if decl == nil {
+		return nil
+	}
+	ident := kotlinChildByType(decl, kotlinNodeSimpleIdentifier)
+	if ident == nil {
+		return nil
+	}
+	name := kotlinNodeText(ident, f.content)
+	mods := kotlinParseModifiers(node, f.content)
+
+	sigStart := decl.StartByte()
+	if binding := kotlinChildByType(node, kotlinNodeBindingPattern); binding != nil {
+		sigStart = binding.StartByte()
+	}
+	signature := kotlinCollapseSpace(string(f.content[sigStart:decl.EndByte()]))
+	if mods.has("const") {
+		signature = "const " + signature
+	}
+
+	kind := SymbolKindVariable
+	switch {
+	case owner != "":
+		kind = SymbolKindField
+	case mods.has("const"):
+		kind = SymbolKindConstant
+	}
+
+	sym := p.newSymbol(node, f, name, kind, signature)
+	p.applyModifiers(sym, mods)
+	if owner != "" {
+		sym.Receiver = owner
+		kotlinMetadata(sym).ParentName = owner
+	}
+	if typeNode := decl.NamedChild(1); typeNode != nil {
+		kotlinMetadata(sym).ReturnType = strings.TrimSuffix(kotlinNodeText(typeNode, f.content), "?")
+	}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if accessor := node.Child(i); accessor.Type() == kotlinNodeGetter || accessor.Type() == kotlinNodeSetter {
+			sym.Calls = append(sym.Calls, p.extractCallSites(ctx, accessor, f)...)
+		}
+	}
+	return sym
+}
+
+// extractClassParameter builds a field symbol for a primary constructor
+// parameter declared with val or var.
+func (p *KotlinParser) extractClassParameter(node *sitter.Node, f *kotlinFile, owner string) *Symbol {
+	ident := kotlinChildByType(node, kotlinNodeSimpleIdentifier)
+	if ident == nil {
+		return nil
+	}
+	binding := kotlinChildByType(node, kotlinNodeBindingPattern)
+	signature := kotlinCollapseSpace(string(f.content[binding.StartByte():node.EndByte()]))
+	sym := p.newSymbol(node, f, kotlinNodeText(ident, f.content), SymbolKindField, signature)
+	p.applyModifiers(sym, kotlinParseModifiers(node, f.content))
+	sym.Receiver = owner
+	kotlinMetadata(sym).ParentName = owner
+	return sym
+}
+
+// extractTypeAlias builds a type symbol for a typealias.
+func (p *KotlinParser) extractTypeAlias(node *sitter.Node, f *kotlinFile) *Symbol {
+	nameNode := kotlinChildByType(node, kotlinNodeTypeIdentifier)
+	if nameNode == nil {
+		return nil
+	}
+	sym := p.newSymbol(node, f, kotlinNodeText(nameNode, f.content), SymbolKindType,
+		kotlinCollapseSpace(kotlinNodeText(node, f.content)))
+	p.applyModifiers(sym, kotlinParseModifiers(node, f.content))
+	return sym
+}
+
+// extractCallSites extracts all call sites within a function body.
+//
+// Description:
+//
+//	Walks the subtree under bodyNode and records each call_expression.
+//	"fetch()" is a plain call; "client.get()" and "client?.get()" are
+//	method calls with Receiver "client"; "this.log()" and "super.save()"
+//	use the receivers "this" and "super" that the graph builder resolves
+//	against the enclosing class. Calls inside trailing lambdas
+//	(list.forEach { it.go() }) are included. Nested class and object
+//	declarations are skipped: their members are separate symbols.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked every 100 nodes.
+//   - bodyNode: The function body, accessor or constructor node. May be nil.
+//   - f: The file being parsed.
+//
+// Outputs:
+//   - []CallSite: Extracted call sites. Limited to MaxCallSitesPerSymbol (1000).
+//
+// Thread Safety: Safe for concurrent use.
+func (p *KotlinParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node, f *kotlinFile) []CallSite {
+	if bodyNode == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "KotlinParser.extractCallSites")
+	defer span.End()
+
+	calls := make([]CallSite, 0, 8)
+
+	type stackEntry struct {
+		node  *sitter.Node
+		depth int
+	}
+	stack := []stackEntry{{node: bodyNode}}
+
+	nodeCount := 0
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := entry.node
+		if entry.depth > MaxCallExpressionDepth {
+			continue
+		}
+
+		nodeCount++
+		if nodeCount%100 == 0 && ctx.Err() != nil {
+			return calls
+		}
+
+		if len(calls) >= MaxCallSitesPerSymbol {
+			slog.Warn("max call sites per symbol reached in Kotlin",
+				telemetry.SensitiveLog("file", f.filePath),
+				slog.Int("limit", MaxCallSitesPerSymbol),
+			)
+			return calls
+		}
+
+		switch node.Type() {
+		case kotlinNodeCallExpression:
+			if call := kotlinCallSite(node, f); call != nil {
+				calls = append(calls, *call)
+			}
+		case kotlinNodeClassDecl, kotlinNodeObjectDecl:
+			if node != bodyNode {
+				continue
+			}
+		}
+
+		// Push in reverse order for left-to-right processing
+		for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+			if child := node.Child(i); child != nil {
+				stack = append(stack, stackEntry{node: child, depth: entry.depth + 1})
+			}
+		}
+	}
+
+	span.SetAttributes(
+		telemetry.SensitiveString("file", f.filePath),
+		attribute.Int("calls_found", len(calls)),
+		attribute.Int("nodes_traversed", nodeCount),
+	)
+
+	return calls
+}
+
+// kotlinCallSite converts a call_expression node to a CallSite, or nil if
+// the callee is not a name (an invoked lambda, for example).
+func kotlinCallSite(node *sitter.Node, f *kotlinFile) *CallSite {
+	callee := node.NamedChild(0)
+	if callee == nil {
+		return nil
+	}
+	call := &CallSite{Location: kotlinLocation(node, f.filePath)}
+
+	switch callee.Type() {
+	case kotlinNodeSimpleIdentifier:
+		call.Target = kotlinNodeText(callee, f.content)
+	case kotlinNodeNavigationExpression:
+		suffix := callee.NamedChild(int(callee.NamedChildCount()) - 1)
+		if suffix == nil || suffix.Type() != kotlinNodeNavigationSuffix {
+			return nil
+		}
+		ident := kotlinChildByType(suffix, kotlinNodeSimpleIdentifier)
+		if ident == nil {
+			return nil
+		}
+		call.Target = kotlinNodeText(ident, f.content)
+		call.IsMethod = true
+		switch receiver := callee.NamedChild(0); receiver.Type() {
+		case kotlinNodeThisExpression:
+			call.Receiver = "this"
+		case kotlinNodeSuperExpression:
+			call.Receiver = "super"
+		default:
+			call.Receiver = kotlinCollapseSpace(kotlinNodeText(receiver, f.content))
+		}
+	default:
+		return nil
+	}
+
+	if call.Target == "" {
+		return nil
+	}
+	return call
+}
+
+// newSymbol creates a Kotlin symbol with its location, package and doc
+// comment set.
+func (p *KotlinParser) newSymbol(node *sitter.Node, f *kotlinFile, name string, kind SymbolKind, signature string) *Symbol {
+	return &Symbol{
+		ID:            GenerateID(f.filePath, int(node.StartPoint().Row)+1, name),
+		Name:          name,
+		Kind:          kind,
+		FilePath:      f.filePath,
+		StartLine:     int(node.StartPoint().Row) + 1,
+		EndLine:       int(node.EndPoint().Row) + 1,
+		StartCol:      int(node.StartPoint().Column),
+		EndCol:        int(node.EndPoint().Column),
+		Signature:     signature,
+		DocComment:    cStyleDocComment(f.lines, int(node.StartPoint().Row)),
+		Package:       f.pkg,
+		Language:      "kotlin",
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Exported:      true,
+	}
+}
+
+// applyModifiers sets visibility and annotations from mods on sym.
+// Kotlin declarations are public unless marked otherwise; private ones
+// are not exported.
+func (p *KotlinParser) applyModifiers(sym *Symbol, mods kotlinModifiers) {
+	if mods.visibility != "" {
+		kotlinMetadata(sym).AccessModifier = mods.visibility
+		sym.Exported = mods.visibility != "private"
+	}
+	if len(mods.annotations) > 0 {
+		kotlinMetadata(sym).Decorators = mods.annotations
+	}
+}
+
+// include reports whether sym passes the IncludePrivate option.
+func (p *KotlinParser) include(sym *Symbol) bool {
+	return p.parseOptions.IncludePrivate || sym.Exported
+}
+
+// kotlinCollectMethods fills Metadata.Methods of a class or interface from
+// its instance methods, so the graph builder can match classes to the
+// interfaces they implement.
+func kotlinCollectMethods(sym *Symbol) {
+	for _, child := range sym.Children {
+		if child.Kind != SymbolKindMethod || child.Metadata == nil ||
+			child.Metadata.IsStatic || child.Metadata.IsConstructor {
+			continue
+		}
+		meta := kotlinMetadata(sym)
+		meta.Methods = append(meta.Methods, kotlinMethodSignature(child, sym.Name))
+	}
+}
+
+// kotlinMethodSignature builds the MethodSignature of a method symbol
+// from its "fun name(params): Return" signature.
+func kotlinMethodSignature(method *Symbol, receiverType string) MethodSignature {
+	sig := MethodSignature{
+		Name:         method.Name,
+		ReceiverType: receiverType,
+	}
+	if open := strings.IndexByte(method.Signature, '('); open >= 0 {
+		if end := strings.LastIndexByte(method.Signature, ')'); end > open {
+			sig.Params = method.Signature[open+1 : end]
+		}
+	}
+	if sig.Params != "" {
+		sig.ParamCount = strings.Count(sig.Params, ",") + 1
+	}
+	if method.Metadata != nil && method.Metadata.ReturnType != "" {
+		sig.Returns = method.Metadata.ReturnType
+		sig.ReturnCount = 1
+	}
+	return sig
+}
+
+// kotlinModifiers holds the parsed modifiers of a declaration.
+type kotlinModifiers struct {
+	visibility  string
+	annotations []string
+	// keywords are the remaining modifiers in source order
+	// (data, sealed, abstract, suspend, override, const, ...).
+	keywords []string
+}
+
+// has reports whether keyword is among the modifiers.
+func (m kotlinModifiers) has(keyword string) bool {
+	for _, k := range m.keywords {
+		if k == keyword {
+			return true
+		}
+	}
+	return false
+}
+
+// kotlinParseModifiers reads the modifiers child of a declaration.
+func kotlinParseModifiers(node *sitter.Node, content []byte) kotlinModifiers {
+	var mods kotlinModifiers
+	modifiers := kotlinChildByType(node, kotlinNodeModifiers)
+	if modifiers == nil {
+		return mods
+	}
+	for i := 0; i < int(modifiers.NamedChildCount()); i++ {
+		child := modifiers.NamedChild(i)
+		text := kotlinNodeText(child, content)
+		switch child.Type() {
+		case kotlinNodeAnnotation:
+			mods.annotations = append(mods.annotations, strings.TrimPrefix(text, "@"))
+		case kotlinNodeVisibilityModifier:
+			mods.visibility = text
+		default:
+			mods.keywords = append(mods.keywords, text)
+		}
+	}
+	return mods
+}
+
+// kotlinTypeName returns the simple name of a type node: "Comparable" for
+// Comparable<T>, "Client" for com.example.Client, "User" for User?.
+func kotlinTypeName(node *sitter.Node, content []byte) string {
+	if node == nil {
+		return ""
+	}
+	switch node.Type() {
+	case kotlinNodeUserType:
+		for i := int(node.NamedChildCount()) - 1; i >= 0; i-- {
+			if child := node.NamedChild(i); child.Type() == kotlinNodeTypeIdentifier {
+				return kotlinNodeText(child, content)
+			}
+		}
+	case kotlinNodeNullableType, kotlinNodeParenType:
+		return kotlinTypeName(node.NamedChild(0), content)
+	}
+	return kotlinNodeText(node, content)
+}
+
+// kotlinMetadata returns sym.Metadata, allocating it if needed.
+func kotlinMetadata(sym *Symbol) *SymbolMetadata {
+	if sym.Metadata == nil {
+		sym.Metadata = &SymbolMetadata{}
+	}
+	return sym.Metadata
+}
+
+// cStyleDocComment returns the comment directly above line row (0-based):
+// a /** ... */ or /* ... */ block ending on the line before, or a run of
+// // or /// lines. Comment markers and leading asterisks are stripped.
+// Used by the Kotlin and Swift parsers.
+func cStyleDocComment(lines []string, row int) string {
+	i := row - 1
+	if i < 0 || i >= len(lines) {
+		return ""
+	}
+	var out []string
+	if strings.HasSuffix(strings.TrimSpace(lines[i]), "*/") {
+		end := i
+		for i >= 0 && !strings.Contains(lines[i], "/*") {
+			i--
+		}
+		if i < 0 {
+			return ""
+		}
+		for _, line := range lines[i : end+1] {
+			line = strings.TrimSpace(line)
+			line = strings.TrimPrefix(strings.TrimPrefix(line, "/**"), "/*")
+			line = strings.TrimSpace(strings.TrimSuffix(line, "*/"))
+			line = strings.TrimSpace(strings.TrimPrefix(line, "*"))
+			if line != "" {
+				out = append(out, line)
+			}
+		}
+		return strings.Join(out, "\n")
+	}
+	for ; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		out = append([]string{strings.TrimSpace(strings.TrimLeft(line, "/"))}, out...)
+	}
+	return strings.Join(out, "\n")
+}
+
+// kotlinLocation returns the location of node.
+func kotlinLocation(node *sitter.Node, filePath string) Location {
+	return Location{
+		FilePath:  filePath,
+		StartLine: int(node.StartPoint().Row) + 1,
+		EndLine:   int(node.EndPoint().Row) + 1,
+		StartCol:  int(node.StartPoint().Column),
+		EndCol:    int(node.EndPoint().Column),
+	}
+}
+
+// kotlinCollapseSpace replaces runs of whitespace with single spaces.
+func kotlinCollapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// kotlinChildByType returns the first direct child of node with the given
+// type, or nil.
+func kotlinChildByType(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// kotlinNodeText returns the source text of node.
+func kotlinNodeText(node *sitter.Node, content []byte) string {
+	return string(content[node.StartByte():node.EndByte()])
+}
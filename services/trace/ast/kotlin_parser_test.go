@@ -0,0 +1,264 @@
+package ast
+
+import (
+	"context"
+	"testing"
+)
+
+const testKotlinSource = `package com.example.app
+
+import kotlinx.coroutines.delay
+import com.example.util.*
+import com.example.net.Client as NetClient
+
+/** Loads and stores users. */
+interface Repository {
+    fun load(id: String): User?
+    suspend fun save(user: User)
+}
+
+data class User(val id: String, var name: String)
+
+open class Base
+
+class UserRepository(private val client: NetClient) : Base(), Repository {
+    override fun load(id: String): User? {
+        return client.get(id)?.let { parse(it) }
+    }
+
+    override suspend fun save(user: User) {
+        delay(10)
+        client.put(user.id, user)
+        this.log("saved")
+    }
+
+    private fun log(msg: String) = println(msg)
+
+    companion object {
+        fun create(): UserRepository = UserRepository(NetClient())
+    }
+}
+
+object Registry {
+    fun register(name: String) { }
+}
+
+enum class Color { RED, GREEN }
+
+fun String.shout(): String = uppercase() + "!"
+
+suspend fun fetchAll(repo: Repository) {
+    repo.save(User("1", "a"))
+}
+
+const val MAX_USERS = 100
+
+typealias Name = String
+`
+
+func parseTestKotlin(t *testing.T) *ParseResult {
+	t.Helper()
+	result, err := NewKotlinParser().Parse(context.Background(), []byte(testKotlinSource), "app/src/main/kotlin/UserRepository.kt")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected parse errors: %v", result.Errors)
+	}
+	return result
+}
+
+// kotlinTopLevel returns the top-level symbol with the given name.
+func kotlinTopLevel(t *testing.T, result *ParseResult, name string) *Symbol {
+	t.Helper()
+	for _, sym := range result.Symbols {
+		if sym.Name == name {
+			return sym
+		}
+	}
+	t.Fatalf("top-level symbol %q not found", name)
+	return nil
+}
+
+// kotlinChild returns the child of sym with the given name.
+func kotlinChild(t *testing.T, sym *Symbol, name string) *Symbol {
+	t.Helper()
+	for _, child := range sym.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	t.Fatalf("%s has no child %q", sym.Name, name)
+	return nil
+}
+
+// hasCallSite reports whether calls contains a call to target on receiver.
+func hasCallSite(calls []CallSite, receiver, target string) bool {
+	for _, call := range calls {
+		if call.Receiver == receiver && call.Target == target {
+			return true
+		}
+	}
+	return false
+}
+
+func TestKotlinParser_LanguageAndExtensions(t *testing.T) {
+	parser := NewKotlinParser()
+	if got := parser.Language(); got != "kotlin" {
+		t.Errorf("Language() = %q, want %q", got, "kotlin")
+	}
+	if exts := parser.Extensions(); len(exts) != 2 || exts[0] != ".kt" || exts[1] != ".kts" {
+		t.Errorf("Extensions() = %v, want [.kt .kts]", exts)
+	}
+}
+
+func TestKotlinParser_PackageAndImports(t *testing.T) {
+	result := parseTestKotlin(t)
+
+	if result.Package != "com.example.app" {
+		t.Errorf("Package = %q, want com.example.app", result.Package)
+	}
+	if len(result.Imports) != 3 {
+		t.Fatalf("expected 3 imports, got %d", len(result.Imports))
+	}
+	if imp := result.Imports[0]; imp.Path != "kotlinx.coroutines.delay" || len(imp.Names) != 1 || imp.Names[0] != "delay" {
+		t.Errorf("import 0 = %+v", imp)
+	}
+	if imp := result.Imports[1]; imp.Path != "com.example.util" || !imp.IsWildcard {
+		t.Errorf("import 1 = %+v, want wildcard com.example.util", imp)
+	}
+	if imp := result.Imports[2]; imp.Path != "com.example.net.Client" || imp.Alias != "NetClient" {
+		t.Errorf("import 2 = %+v, want alias NetClient", imp)
+	}
+}
+
+func TestKotlinParser_Interface(t *testing.T) {
+	result := parseTestKotlin(t)
+
+	repo := kotlinTopLevel(t, result, "Repository")
+	if repo.Kind != SymbolKindInterface {
+		t.Fatalf("Repository kind = %v, want interface", repo.Kind)
+	}
+	if repo.DocComment != "Loads and stores users." {
+		t.Errorf("DocComment = %q", repo.DocComment)
+	}
+	if repo.Metadata == nil || len(repo.Metadata.Methods) != 2 {
+		t.Fatalf("Repository methods = %+v, want load and save", repo.Metadata)
+	}
+	if repo.Metadata.Methods[0].Name != "load" || repo.Metadata.Methods[1].Name != "save" {
+		t.Errorf("Repository methods = %+v", repo.Metadata.Methods)
+	}
+	if load := kotlinChild(t, repo, "load"); load.Metadata.ReturnType != "User" {
+		t.Errorf("load return type = %q, want User", load.Metadata.ReturnType)
+	}
+}
+
+func TestKotlinParser_Classes(t *testing.T) {
+	result := parseTestKotlin(t)
+
+	user := kotlinTopLevel(t, result, "User")
+	if user.Kind != SymbolKindClass || user.Signature != "data class User(val id: String, var name: String)" {
+		t.Errorf("User = %v %q", user.Kind, user.Signature)
+	}
+	if id := kotlinChild(t, user, "id"); id.Kind != SymbolKindField || id.Receiver != "User" {
+		t.Errorf("User.id = %v receiver %q, want field of User", id.Kind, id.Receiver)
+	}
+
+	repo := kotlinTopLevel(t, result, "UserRepository")
+	if repo.Metadata == nil || repo.Metadata.Extends != "Base" {
+		t.Fatalf("UserRepository should extend Base, got %+v", repo.Metadata)
+	}
+	if len(repo.Metadata.Implements) != 1 || repo.Metadata.Implements[0] != "Repository" {
+		t.Errorf("Implements = %v, want [Repository]", repo.Metadata.Implements)
+	}
+
+	save := kotlinChild(t, repo, "save")
+	if save.Kind != SymbolKindMethod || save.Receiver != "UserRepository" {
+		t.Errorf("save = %v receiver %q", save.Kind, save.Receiver)
+	}
+	if !save.Metadata.IsAsync {
+		t.Error("suspend fun save should be async")
+	}
+	if log := kotlinChild(t, repo, "log"); log.Exported {
+		t.Error("private fun log should not be exported")
+	}
+	if create := kotlinChild(t, repo, "create"); !create.Metadata.IsStatic {
+		t.Error("companion object member create should be static")
+	}
+
+	registry := kotlinTopLevel(t, result, "Registry")
+	if registry.Kind != SymbolKindClass {
+		t.Errorf("object Registry kind = %v, want class", registry.Kind)
+	}
+
+	color := kotlinTopLevel(t, result, "Color")
+	if color.Kind != SymbolKindEnum || len(color.Children) != 2 || color.Children[0].Kind != SymbolKindEnumMember {
+		t.Errorf("Color = %v with children %v", color.Kind, color.Children)
+	}
+}
+
+func TestKotlinParser_TopLevelDeclarations(t *testing.T) {
+	result := parseTestKotlin(t)
+
+	shout := kotlinTopLevel(t, result, "shout")
+	if shout.Kind != SymbolKindMethod || shout.Receiver != "String" {
+		t.Errorf("extension shout = %v receiver %q, want method on String", shout.Kind, shout.Receiver)
+	}
+
+	fetchAll := kotlinTopLevel(t, result, "fetchAll")
+	if fetchAll.Kind != SymbolKindFunction || !fetchAll.Metadata.IsAsync {
+		t.Errorf("fetchAll = %v %+v, want async function", fetchAll.Kind, fetchAll.Metadata)
+	}
+
+	if maxUsers := kotlinTopLevel(t, result, "MAX_USERS"); maxUsers.Kind != SymbolKindConstant {
+		t.Errorf("MAX_USERS kind = %v, want constant", maxUsers.Kind)
+	}
+	if name := kotlinTopLevel(t, result, "Name"); name.Kind != SymbolKindType {
+		t.Errorf("Name kind = %v, want type", name.Kind)
+	}
+}
+
+func TestKotlinParser_CallSites(t *testing.T) {
+	result := parseTestKotlin(t)
+
+	repo := kotlinTopLevel(t, result, "UserRepository")
+	save := kotlinChild(t, repo, "save")
+	for _, want := range []struct{ receiver, target string }{
+		{"", "delay"}, {"client", "put"}, {"this", "log"},
+	} {
+		if !hasCallSite(save.Calls, want.receiver, want.target) {
+			t.Errorf("save calls %+v missing %s.%s", save.Calls, want.receiver, want.target)
+		}
+	}
+
+	load := kotlinChild(t, repo, "load")
+	if !hasCallSite(load.Calls, "", "parse") {
+		t.Errorf("load calls %+v should include parse inside the lambda", load.Calls)
+	}
+
+	fetchAll := kotlinTopLevel(t, result, "fetchAll")
+	if !hasCallSite(fetchAll.Calls, "repo", "save") || !hasCallSite(fetchAll.Calls, "", "User") {
+		t.Errorf("fetchAll calls = %+v", fetchAll.Calls)
+	}
+}
+
+func TestKotlinParser_SyntaxErrorReported(t *testing.T) {
+	content := []byte(`class Good
+class {
+`)
+	result, err := NewKotlinParser().Parse(context.Background(), content, "Bad.kt")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) == 0 {
+		t.Error("expected a syntax error to be reported")
+	}
+}
+
+func TestKotlinParser_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewKotlinParser().Parse(ctx, []byte(testKotlinSource), "UserRepository.kt"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
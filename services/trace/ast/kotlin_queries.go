@@ -0,0 +1,165 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+// Kotlin Tree-sitter Node Types
+//
+// This file documents the tree-sitter node types used by KotlinParser for symbol extraction.
+// The Kotlin grammar defines no field names, so the parser walks children by type.
+//
+// Reference: https://github.com/fwcd/tree-sitter-kotlin
+
+// Node type constants for Kotlin AST traversal.
+const (
+	// Top-level nodes
+	kotlinNodeSourceFile    = "source_file"
+	kotlinNodePackageHeader = "package_header"
+	kotlinNodeImportList    = "import_list"
+	kotlinNodeImportHeader  = "import_header"
+	kotlinNodeImportAlias   = "import_alias"
+	kotlinNodeWildcard      = "wildcard_import"
+
+	// Declaration nodes
+	kotlinNodeClassDecl       = "class_declaration"
+	kotlinNodeObjectDecl      = "object_declaration"
+	kotlinNodeCompanionObject = "companion_object"
+	kotlinNodeFunctionDecl    = "function_declaration"
+	kotlinNodePropertyDecl    = "property_declaration"
+	kotlinNodeTypeAlias       = "type_alias"
+	kotlinNodeClassBody       = "class_body"
+	kotlinNodeEnumClassBody   = "enum_class_body"
+	kotlinNodeEnumEntry       = "enum_entry"
+	kotlinNodePrimaryCtor     = "primary_constructor"
+	kotlinNodeClassParameter  = "class_parameter"
+	kotlinNodeSecondaryCtor   = "secondary_constructor"
+	kotlinNodeInitializer     = "anonymous_initializer"
+	kotlinNodeDelegationSpec  = "delegation_specifier"
+	kotlinNodeCtorInvocation  = "constructor_invocation"
+	kotlinNodeVariableDecl    = "variable_declaration"
+	kotlinNodeBindingPattern  = "binding_pattern_kind"
+	kotlinNodeGetter          = "getter"
+	kotlinNodeSetter          = "setter"
+
+	// Function parts
+	kotlinNodeFunctionBody   = "function_body"
+	kotlinNodeValueParams    = "function_value_parameters"
+	kotlinNodeParameter      = "parameter"
+	kotlinNodeTypeParameters = "type_parameters"
+	kotlinNodeTypeParameter  = "type_parameter"
+
+	// Modifiers
+	kotlinNodeModifiers          = "modifiers"
+	kotlinNodeAnnotation         = "annotation"
+	kotlinNodeVisibilityModifier = "visibility_modifier"
+	kotlinNodeFunctionModifier   = "function_modifier"
+	kotlinNodeClassModifier      = "class_modifier"
+	kotlinNodeInheritanceMod     = "inheritance_modifier"
+	kotlinNodePropertyModifier   = "property_modifier"
+
+	// Types
+	kotlinNodeUserType       = "user_type"
+	kotlinNodeNullableType   = "nullable_type"
+	kotlinNodeFunctionType   = "function_type"
+	kotlinNodeParenType      = "parenthesized_type"
+	kotlinNodeTypeIdentifier = "type_identifier"
+
+	// Expressions
+	kotlinNodeCallExpression       = "call_expression"
+	kotlinNodeNavigationExpression = "navigation_expression"
+	kotlinNodeNavigationSuffix     = "navigation_suffix"
+	kotlinNodeSimpleIdentifier     = "simple_identifier"
+	kotlinNodeIdentifier           = "identifier"
+	kotlinNodeThisExpression       = "this_expression"
+	kotlinNodeSuperExpression      = "super_expression"
+
+	// Keywords that appear as anonymous children
+	kotlinKeywordInterface = "interface"
+	kotlinKeywordEnum      = "enum"
+	kotlinKeywordFun       = "fun"
+
+	// Comments and errors
+	kotlinNodeLineComment      = "line_comment"
+	kotlinNodeMultilineComment = "multiline_comment"
+	kotlinNodeERROR            = "ERROR"
+)
+
+// KotlinNodeTypes maps symbol kinds to the tree-sitter node types that produce them.
+var KotlinNodeTypes = map[SymbolKind][]string{
+	SymbolKindClass:      {kotlinNodeClassDecl, kotlinNodeObjectDecl},
+	SymbolKindInterface:  {kotlinNodeClassDecl},
+	SymbolKindEnum:       {kotlinNodeClassDecl},
+	SymbolKindEnumMember: {kotlinNodeEnumEntry},
+	SymbolKindFunction:   {kotlinNodeFunctionDecl},
+	SymbolKindMethod:     {kotlinNodeFunctionDecl, kotlinNodeSecondaryCtor},
+	SymbolKindField:      {kotlinNodePropertyDecl, kotlinNodeClassParameter},
+	SymbolKindVariable:   {kotlinNodePropertyDecl},
+	SymbolKindConstant:   {kotlinNodePropertyDecl},
+	SymbolKindType:       {kotlinNodeTypeAlias},
+}
+
+// Kotlin AST Structure Reference
+//
+// source_file
+// ├── package_header
+// │   └── identifier (com.example.app)
+// ├── import_list
+// │   └── import_header
+// │       ├── identifier (com.example.net.Client)
+// │       ├── wildcard_import (*)          [optional]
+// │       └── import_alias (as NetClient)  [optional]
+// ├── class_declaration
+// │   ├── modifiers (data, sealed, abstract, @Annotation, private, ...)
+// │   ├── "class" | "interface" | "enum" "class"
+// │   ├── type_identifier (name)
+// │   ├── type_parameters                  [optional]
+// │   ├── primary_constructor
+// │   │   └── class_parameter (val id: String)
+// │   ├── delegation_specifier
+// │   │   ├── constructor_invocation (Base())  superclass
+// │   │   └── user_type (Repository)           interface
+// │   └── class_body | enum_class_body
+// │       ├── function_declaration
+// │       ├── property_declaration
+// │       ├── companion_object
+// │       │   └── class_body
+// │       ├── secondary_constructor
+// │       ├── anonymous_initializer (init { ... })
+// │       └── enum_entry
+// ├── object_declaration
+// │   ├── type_identifier (name)
+// │   └── class_body
+// ├── function_declaration
+// │   ├── modifiers (suspend, override, private, ...)
+// │   ├── "fun"
+// │   ├── type_parameters                  [optional]
+// │   ├── user_type "."                     [extension receiver]
+// │   ├── simple_identifier (name)
+// │   ├── function_value_parameters
+// │   ├── ":" <type>                        [return type]
+// │   └── function_body
+// ├── property_declaration
+// │   ├── binding_pattern_kind (val | var)
+// │   └── variable_declaration
+// │       └── simple_identifier (name)
+// └── type_alias
+//     └── type_identifier (name)
+//
+// Calls:
+//
+// call_expression
+// ├── simple_identifier (fetch)            fetch()
+// │   | navigation_expression              client.get()
+// │   │   ├── <receiver expression>
+// │   │   └── navigation_suffix
+// │   │       └── simple_identifier (get)
+// └── call_suffix
+//     ├── value_arguments
+//     └── annotated_lambda                 [trailing lambda]
@@ -0,0 +1,337 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// sensitiveLiteralValue matches literal values that look like credentials:
+// provider API keys and tokens, JWTs, private keys, bearer headers and
+// URLs with a password.
+var sensitiveLiteralValue = regexp.MustCompile(`` +
+	`sk-[A-Za-z0-9_-]{16,}` +
+	`|AKIA[0-9A-Z]{16}` +
+	`|AIza[0-9A-Za-z_-]{30,}` +
+	`|gh[pousr]_[A-Za-z0-9]{20,}|github_pat_[A-Za-z0-9_]{20,}` +
+	`|xox[abprs]-[A-Za-z0-9-]{10,}` +
+	`|eyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.` +
+	`|-----BEGIN [A-Z ]*PRIVATE KEY-----` +
+	`|(?i:bearer)\s+[A-Za-z0-9._~+/-]{10,}` +
+	`|://[^/\s:@]+:[^/\s@]+@`)
+
+// sensitiveLiteralName matches names that mark the literals passed with
+// them as secrets: the called function (SetPassword), a keyword or field
+// (api_key=...), or a preceding string argument (Setenv("API_TOKEN", ...)).
+var sensitiveLiteralName = regexp.MustCompile(
+	`(?i)secret|token|passw(or)?d|passphrase|pwd|api[_-]?key|credential|private[_-]?key|auth[_-]?key|authorization`)
+
+// linkageCalls are calls whose string arguments name templates or DOM
+// selectors: Go html/template and text/template loading and execution, and
+// the DOM lookup functions. Their literals are kept without
+// CaptureLiteralArgs so the graph builder can link them to template files
+// and HTML elements.
+var linkageCalls = map[string]bool{
+	"ParseFiles":             true,
+	"ParseGlob":              true,
+	"ParseFS":                true,
+	"ExecuteTemplate":        true,
+	"querySelector":          true,
+	"querySelectorAll":       true,
+	"getElementById":         true,
+	"getElementsByClassName": true,
+}
+
+// keepsLinkageLiterals reports whether a call to target keeps its string
+// literals for template and selector linkage (see linkageCalls).
+func keepsLinkageLiterals(target string) bool {
+	return linkageCalls[target]
+}
+
+// literalArgCapture collects the literal arguments of one call.
+type literalArgCapture struct {
+	filePath string
+	content  []byte
+
+	// all captures every literal kind and nested object fields; otherwise
+	// only top-level strings are kept.
+	all bool
+
+	// keepSensitive turns off redaction.
+	keepSensitive bool
+
+	args []LiteralArg
+}
+
+// newLiteralArgCapture returns a capture for a call in filePath. all is
+// ParseOptions.CaptureLiteralArgs.
+func newLiteralArgCapture(filePath string, content []byte, all, keepSensitive bool) *literalArgCapture {
+	return &literalArgCapture{filePath: filePath, content: content, all: all, keepSensitive: keepSensitive}
+}
+
+// full reports whether MaxLiteralArgsPerCall literals have been kept.
+func (c *literalArgCapture) full() bool {
+	return len(c.args) >= MaxLiteralArgsPerCall
+}
+
+// add keeps a literal found at node. Literals other than strings, and
+// named (nested) ones, are only kept when capturing everything.
+func (c *literalArgCapture) add(kind LiteralKind, value string, index int, name string, node *sitter.Node) {
+	if c.full() || (!c.all && (kind != LiteralKindString || name != "")) {
+		return
+	}
+	arg := LiteralArg{
+		Value: value,
+		Kind:  kind,
+		Index: index,
+		Name:  name,
+		Location: Location{
+			FilePath:  c.filePath,
+			StartLine: int(node.StartPoint().Row) + 1,
+			EndLine:   int(node.EndPoint().Row) + 1,
+			StartCol:  int(node.StartPoint().Column),
+			EndCol:    int(node.EndPoint().Column),
+		},
+	}
+	if len(arg.Value) > MaxLiteralArgLength {
+		cut := MaxLiteralArgLength
+		for cut > 0 && !utf8.RuneStart(arg.Value[cut]) {
+			cut--
+		}
+		arg.Value = arg.Value[:cut]
+		arg.Truncated = true
+	}
+	c.args = append(c.args, arg)
+}
+
+// finish returns the kept literals of a call to target, with
+// sensitive-looking ones redacted unless keepSensitive is set. Nil if
+// none were kept.
+//
+// Description:
+//
+//	A string literal is redacted when its value looks like a credential.
+//	A string or number literal is also redacted when the called function,
+//	its keyword or field name, or a string argument before it names a
+//	secret (password, token, api_key, ...). Bools are never redacted.
+func (c *literalArgCapture) finish(target string) []LiteralArg {
+	if len(c.args) == 0 {
+		return nil
+	}
+	if c.keepSensitive {
+		return c.args
+	}
+	sensitiveCall := sensitiveLiteralName.MatchString(target)
+	sensitiveKey := false
+	for i := range c.args {
+		arg := &c.args[i]
+		if arg.Kind == LiteralKindBool {
+			continue
+		}
+		redact := sensitiveCall || sensitiveKey ||
+			(arg.Name != "" && sensitiveLiteralName.MatchString(arg.Name)) ||
+			(arg.Kind == LiteralKindString && sensitiveLiteralValue.MatchString(arg.Value))
+		if arg.Kind == LiteralKindString && arg.Name == "" && sensitiveLiteralName.MatchString(arg.Value) {
+			sensitiveKey = true
+		}
+		if redact {
+			arg.Value = RedactedLiteral
+			arg.Truncated = false
+			arg.Redacted = true
+		}
+	}
+	return c.args
+}
+
+// nodeText returns the source text of node.
+func (c *literalArgCapture) nodeText(node *sitter.Node) string {
+	return string(c.content[node.StartByte():node.EndByte()])
+}
+
+// negated returns the operand of a unary minus node whose operand is a
+// number literal of one of numberTypes, or nil.
+func negated(node *sitter.Node, numberTypes ...string) *sitter.Node {
+	if node.ChildCount() != 2 || node.Child(0).Type() != "-" {
+		return nil
+	}
+	operand := node.Child(1)
+	for _, t := range numberTypes {
+		if operand.Type() == t {
+			return operand
+		}
+	}
+	return nil
+}
+
+// captureGoLiteralArgs collects the literals of a Go call's argument_list.
+// Keyed fields of composite literal arguments (Config{Timeout: 30}) are
+// captured with the field as Name.
+func captureGoLiteralArgs(c *literalArgCapture, argsNode *sitter.Node) {
+	for i := 0; i < int(argsNode.NamedChildCount()) && !c.full(); i++ {
+		child := argsNode.NamedChild(i)
+		if child == nil {
+			continue
+		}
+		if child.Type() == "composite_literal" && c.all {
+			if body := child.ChildByFieldName("body"); body != nil {
+				captureGoKeyedElements(c, body, i)
+			}
+			continue
+		}
+		c.addGo(child, i, "")
+	}
+}
+
+// captureGoKeyedElements captures the literal values of a literal_value's
+// keyed elements.
+func captureGoKeyedElements(c *literalArgCapture, body *sitter.Node, index int) {
+	for j := 0; j < int(body.NamedChildCount()) && !c.full(); j++ {
+		elem := body.NamedChild(j)
+		if elem == nil || elem.Type() != "keyed_element" || elem.NamedChildCount() != 2 {
+			continue
+		}
+		key, value := elem.NamedChild(0), elem.NamedChild(1)
+		if value.Type() == "literal_element" && value.NamedChildCount() == 1 {
+			value = value.NamedChild(0)
+		}
+		c.addGo(value, index, c.nodeText(key))
+	}
+}
+
+// addGo keeps node if it is a Go literal.
+func (c *literalArgCapture) addGo(node *sitter.Node, index int, name string) {
+	switch node.Type() {
+	case "interpreted_string_literal", "raw_string_literal":
+		if lit, ok := unquoteGoString(node, c.content); ok {
+			c.add(LiteralKindString, lit, index, name, node)
+		}
+	case "int_literal", "float_literal", "imaginary_literal":
+		c.add(LiteralKindNumber, c.nodeText(node), index, name, node)
+	case "true", "false":
+		c.add(LiteralKindBool, node.Type(), index, name, node)
+	case "unary_expression":
+		if operand := negated(node, "int_literal", "float_literal"); operand != nil {
+			c.add(LiteralKindNumber, "-"+c.nodeText(operand), index, name, node)
+		}
+	}
+}
+
+// capturePythonLiteralArgs collects the literals of a Python call's
+// argument_list. Keyword arguments are captured with the keyword as Name.
+func capturePythonLiteralArgs(c *literalArgCapture, argsNode *sitter.Node) {
+	index := 0
+	for i := 0; i < int(argsNode.NamedChildCount()) && !c.full(); i++ {
+		child := argsNode.NamedChild(i)
+		if child == nil || child.Type() == "comment" {
+			continue
+		}
+		if child.Type() == "keyword_argument" {
+			name, value := child.ChildByFieldName("name"), child.ChildByFieldName("value")
+			if name != nil && value != nil {
+				c.addPython(value, index, c.nodeText(name))
+			}
+		} else {
+			c.addPython(child, index, "")
+		}
+		index++
+	}
+}
+
+// addPython keeps node if it is a Python literal. f-strings with
+// interpolations are not literals.
+func (c *literalArgCapture) addPython(node *sitter.Node, index int, name string) {
+	switch node.Type() {
+	case "string":
+		var start, end *sitter.Node
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			switch child := node.NamedChild(i); child.Type() {
+			case "string_start":
+				start = child
+			case "string_end":
+				end = child
+			case "interpolation":
+				return
+			}
+		}
+		if start != nil && end != nil && start.EndByte() <= end.StartByte() {
+			c.add(LiteralKindString, string(c.content[start.EndByte():end.StartByte()]), index, name, node)
+		}
+	case "integer", "float":
+		c.add(LiteralKindNumber, c.nodeText(node), index, name, node)
+	case "true", "false":
+		c.add(LiteralKindBool, strings.ToLower(c.nodeText(node)), index, name, node)
+	case "unary_operator":
+		if operand := negated(node, "integer", "float"); operand != nil {
+			c.add(LiteralKindNumber, "-"+c.nodeText(operand), index, name, node)
+		}
+	}
+}
+
+// captureJSLiteralArgs collects the literals of a JavaScript or TypeScript
+// call's arguments. Properties of object literal arguments ({timeout: 30})
+// are captured with the key as Name.
+func captureJSLiteralArgs(c *literalArgCapture, argsNode *sitter.Node) {
+	for i := 0; i < int(argsNode.NamedChildCount()) && !c.full(); i++ {
+		child := argsNode.NamedChild(i)
+		if child == nil {
+			continue
+		}
+		if child.Type() == "object" && c.all {
+			for j := 0; j < int(child.NamedChildCount()) && !c.full(); j++ {
+				pair := child.NamedChild(j)
+				if pair == nil || pair.Type() != "pair" {
+					continue
+				}
+				key, value := pair.ChildByFieldName("key"), pair.ChildByFieldName("value")
+				if key == nil || value == nil {
+					continue
+				}
+				name := c.nodeText(key)
+				if key.Type() == "string" && len(name) >= 2 {
+					name = name[1 : len(name)-1]
+				}
+				c.addJS(value, i, name)
+			}
+			continue
+		}
+		c.addJS(child, i, "")
+	}
+}
+
+// addJS keeps node if it is a JavaScript or TypeScript literal. Template
+// strings count only without substitutions. Escapes are kept as written.
+func (c *literalArgCapture) addJS(node *sitter.Node, index int, name string) {
+	switch node.Type() {
+	case "template_string":
+		for j := 0; j < int(node.NamedChildCount()); j++ {
+			if node.NamedChild(j).Type() == "template_substitution" {
+				return
+			}
+		}
+		fallthrough
+	case "string":
+		if text := c.nodeText(node); len(text) >= 2 {
+			c.add(LiteralKindString, text[1:len(text)-1], index, name, node)
+		}
+	case "number":
+		c.add(LiteralKindNumber, c.nodeText(node), index, name, node)
+	case "true", "false":
+		c.add(LiteralKindBool, node.Type(), index, name, node)
+	case "unary_expression":
+		if operand := negated(node, "number"); operand != nil {
+			c.add(LiteralKindNumber, "-"+c.nodeText(operand), index, name, node)
+		}
+	}
+}
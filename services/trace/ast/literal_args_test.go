@@ -0,0 +1,227 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// literalCaptureOptions enables literal capture.
+func literalCaptureOptions() ParseOptions {
+	opts := DefaultParseOptions()
+	opts.CaptureLiteralArgs = true
+	return opts
+}
+
+// callsTo returns the call sites of every symbol in result whose target
+// is target.
+func callsTo(result *ParseResult, target string) []CallSite {
+	var calls []CallSite
+	var walk func(symbols []*Symbol)
+	walk = func(symbols []*Symbol) {
+		for _, sym := range symbols {
+			for _, call := range sym.Calls {
+				if call.Target == target {
+					calls = append(calls, call)
+				}
+			}
+			walk(sym.Children)
+		}
+	}
+	walk(result.Symbols)
+	return calls
+}
+
+// literalSummary renders args as "name=value:kind" for comparison, with
+// "@index" for unnamed ones.
+func literalSummary(args []LiteralArg) string {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		key := a.Name
+		if key == "" {
+			key = "@" + string(rune('0'+a.Index))
+		}
+		parts[i] = key + "=" + a.Value + ":" + string(a.Kind)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestLiteralArgs_Capture(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		parse   func() (*ParseResult, error)
+		target  string
+		want    string
+		wantPos [2]int // line and column of the first literal
+	}{
+		{
+			name: "go",
+			parse: func() (*ParseResult, error) {
+				src := "package main\n\nfunc main() {\n\tserver.Start(\"prod\", 30, -1.5, true, Config{Timeout: 30, Name: \"api\"}, other)\n}\n"
+				return NewGoParser(WithParseOptions(literalCaptureOptions())).Parse(ctx, []byte(src), "main.go")
+			},
+			target:  "Start",
+			want:    "@0=prod:string @1=30:number @2=-1.5:number @3=true:bool Timeout=30:number Name=api:string",
+			wantPos: [2]int{4, 14},
+		},
+		{
+			name: "python",
+			parse: func() (*ParseResult, error) {
+				src := "def main():\n    connect(\"db\", 5432, timeout=30, ssl=True, label=f\"x{y}\")\n"
+				return NewPythonParser(WithPythonParseOptions(literalCaptureOptions())).Parse(ctx, []byte(src), "main.py")
+			},
+			target:  "connect",
+			want:    "@0=db:string @1=5432:number timeout=30:number ssl=true:bool",
+			wantPos: [2]int{2, 12},
+		},
+		{
+			name: "typescript",
+			parse: func() (*ParseResult, error) {
+				src := "function main() {\n  configure('production', {timeout: 30, 'retry': false, url: `http://x`});\n}\n"
+				return NewTypeScriptParser(WithTypeScriptParseOptions(literalCaptureOptions())).Parse(ctx, []byte(src), "main.ts")
+			},
+			target:  "configure",
+			want:    "@0=production:string timeout=30:number retry=false:bool url=http://x:string",
+			wantPos: [2]int{2, 12},
+		},
+		{
+			name: "javascript",
+			parse: func() (*ParseResult, error) {
+				src := "function main() {\n  const c = new Client('eu', -3);\n}\n"
+				return NewJavaScriptParser(WithJSCaptureLiteralArgs(true)).Parse(ctx, []byte(src), "main.js")
+			},
+			target:  "Client",
+			want:    "@0=eu:string @1=-3:number",
+			wantPos: [2]int{2, 23},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.parse()
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			calls := callsTo(result, tt.target)
+			if len(calls) != 1 {
+				t.Fatalf("calls to %s = %d, want 1", tt.target, len(calls))
+			}
+			args := calls[0].LiteralArgs
+			if got := literalSummary(args); got != tt.want {
+				t.Errorf("literals = %q, want %q", got, tt.want)
+			}
+			if len(args) > 0 {
+				if loc := args[0].Location; loc.StartLine != tt.wantPos[0] || loc.StartCol != tt.wantPos[1] {
+					t.Errorf("first literal at %d:%d, want %d:%d", loc.StartLine, loc.StartCol, tt.wantPos[0], tt.wantPos[1])
+				}
+			}
+		})
+	}
+}
+
+func TestLiteralArgs_OffByDefault(t *testing.T) {
+	src := "package main\n\nfunc main() {\n\tserver.Start(\"prod\", 30)\n}\n"
+	result, err := NewGoParser().Parse(context.Background(), []byte(src), "main.go")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	calls := callsTo(result, "Start")
+	if len(calls) != 1 || calls[0].LiteralArgs != nil {
+		t.Errorf("calls = %+v, want one call without literals", calls)
+	}
+}
+
+func TestLiteralArgs_LinkageCallsAlwaysKept(t *testing.T) {
+	ctx := context.Background()
+	goSrc := "package main\n\nfunc main() {\n\ttemplate.ParseGlob(\"templates/*.html\")\n}\n"
+	goResult, err := NewGoParser().Parse(ctx, []byte(goSrc), "main.go")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if calls := callsTo(goResult, "ParseGlob"); len(calls) != 1 || literalSummary(calls[0].LiteralArgs) != "@0=templates/*.html:string" {
+		t.Errorf("ParseGlob calls = %+v, want its pattern kept", calls)
+	}
+
+	jsSrc := "function show() {\n  document.querySelector('#main .card');\n}\n"
+	jsResult, err := NewJavaScriptParser().Parse(ctx, []byte(jsSrc), "app.js")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if calls := callsTo(jsResult, "querySelector"); len(calls) != 1 || literalSummary(calls[0].LiteralArgs) != "@0=#main .card:string" {
+		t.Errorf("querySelector calls = %+v, want its selector kept", calls)
+	}
+}
+
+func TestLiteralArgs_Limits(t *testing.T) {
+	long := strings.Repeat("é", MaxLiteralArgLength)
+	src := "package main\n\nfunc main() {\n\tf(\"" + long + "\", 1, 2, 3, 4, 5, 6, 7, 8, 9)\n}\n"
+	result, err := NewGoParser(WithParseOptions(literalCaptureOptions())).Parse(context.Background(), []byte(src), "main.go")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	args := callsTo(result, "f")[0].LiteralArgs
+	if len(args) != MaxLiteralArgsPerCall {
+		t.Errorf("kept %d literals, want %d", len(args), MaxLiteralArgsPerCall)
+	}
+	first := args[0]
+	if !first.Truncated || len(first.Value) > MaxLiteralArgLength || !strings.HasPrefix(long, first.Value) {
+		t.Errorf("long literal: truncated=%v len=%d, want a truncated prefix of at most %d bytes",
+			first.Truncated, len(first.Value), MaxLiteralArgLength)
+	}
+}
+
+func TestLiteralArgs_Redaction(t *testing.T) {
+	src := `package main
+
+func main() {
+	client.SetPassword("hunter2")
+	os.Setenv("API_TOKEN", "abc123", "plain")
+	connect("postgres://admin:s3cret@db:5432/app", "sk-abcdefghijklmnopqrstuvwx", true)
+	configure(Config{APIKey: "k-123", Region: "eu", Debug: true})
+}
+`
+	ctx := context.Background()
+	result, err := NewGoParser(WithParseOptions(literalCaptureOptions())).Parse(ctx, []byte(src), "main.go")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"SetPassword", "@0=[REDACTED]:string"},
+		{"Setenv", "@0=API_TOKEN:string @1=[REDACTED]:string @2=[REDACTED]:string"},
+		{"connect", "@0=[REDACTED]:string @1=[REDACTED]:string @2=true:bool"},
+		{"configure", "APIKey=[REDACTED]:string Region=eu:string Debug=true:bool"},
+	}
+	for _, tt := range tests {
+		calls := callsTo(result, tt.target)
+		if len(calls) != 1 {
+			t.Fatalf("calls to %s = %d, want 1", tt.target, len(calls))
+		}
+		if got := literalSummary(calls[0].LiteralArgs); got != tt.want {
+			t.Errorf("%s literals = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+
+	opts := literalCaptureOptions()
+	opts.KeepSensitiveLiterals = true
+	result, err = NewGoParser(WithParseOptions(opts)).Parse(ctx, []byte(src), "main.go")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if args := callsTo(result, "SetPassword")[0].LiteralArgs; args[0].Value != "hunter2" || args[0].Redacted {
+		t.Errorf("KeepSensitiveLiterals: got %+v, want the value kept", args[0])
+	}
+}
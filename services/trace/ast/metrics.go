@@ -19,6 +19,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Package-level tracer and meter for AST parsing.
@@ -153,7 +155,7 @@ func startParseSpan(ctx context.Context, language, filePath string, contentSize
 	return tracer.Start(ctx, "Parser.Parse",
 		trace.WithAttributes(
 			attribute.String("ast.language", language),
-			attribute.String("ast.file", filePath),
+			telemetry.SensitiveString("ast.file", filePath),
 			attribute.Int("ast.content_size", contentSize),
 		),
 	)
@@ -212,7 +214,7 @@ func recordProtocolDetected(ctx context.Context, className string, methodCount i
 func startProtocolDetectionSpan(ctx context.Context, className string) (context.Context, trace.Span) {
 	return tracer.Start(ctx, "PythonParser.ProtocolDetection",
 		trace.WithAttributes(
-			attribute.String("ast.class_name", className),
+			telemetry.SensitiveString("ast.class_name", className),
 		),
 	)
 }
@@ -12,6 +12,8 @@ package ast
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -142,6 +144,10 @@ type ParserRegistry struct {
 
 	// byExtension maps file extensions to parser instances.
 	byExtension map[string]Parser
+
+	// factories maps language names to the factories registered with
+	// RegisterFactory.
+	factories map[string]ParserFactory
 }
 
 // NewParserRegistry creates a new empty ParserRegistry.
@@ -149,7 +155,96 @@ func NewParserRegistry() *ParserRegistry {
 	return &ParserRegistry{
 		byLanguage:  make(map[string]Parser),
 		byExtension: make(map[string]Parser),
+		factories:   make(map[string]ParserFactory),
+	}
+}
+
+// RegisterFactory registers factory and a parser it constructs with
+// default settings.
+//
+// Description:
+//
+//	The factory lets Configure construct parsers of factory.Language
+//	with other settings. Registering a parser of the same language with
+//	Register afterwards replaces the default parser but keeps the factory.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (r *ParserRegistry) RegisterFactory(factory ParserFactory) {
+	if factory.New == nil {
+		return
 	}
+	r.Register(factory.New(nil))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[factory.Language] = factory
+}
+
+// Factory returns the factory registered for language.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (r *ParserRegistry) Factory(language string) (ParserFactory, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	factory, ok := r.factories[language]
+	return factory, ok
+}
+
+// Configure returns a registry whose parsers for the languages in
+// settings are constructed with those settings.
+//
+// Description:
+//
+//	The returned registry shares the parsers and factories of r for all
+//	other languages; r is not modified. Used to parse one build with
+//	options of its own while other builds keep the shared defaults.
+//
+// Inputs:
+//
+//	settings - Parser settings by language name. Empty returns r.
+//
+// Outputs:
+//
+//	*ParserRegistry - The configured registry.
+//	error - Wraps ErrUnsupportedLanguage for a language without a factory,
+//	  or the error of ParserFactory.Validate.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (r *ParserRegistry) Configure(settings map[string]ParserSettings) (*ParserRegistry, error) {
+	if len(settings) == 0 {
+		return r, nil
+	}
+	languages := make([]string, 0, len(settings))
+	for lang := range settings {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+
+	configured := NewParserRegistry()
+	r.mu.RLock()
+	for lang, parser := range r.byLanguage {
+		configured.byLanguage[lang] = parser
+	}
+	for ext, parser := range r.byExtension {
+		configured.byExtension[ext] = parser
+	}
+	for lang, factory := range r.factories {
+		configured.factories[lang] = factory
+	}
+	r.mu.RUnlock()
+
+	for _, lang := range languages {
+		factory, ok := configured.factories[lang]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q has no configurable parser", ErrUnsupportedLanguage, lang)
+		}
+		if err := factory.Validate(settings[lang]); err != nil {
+			return nil, err
+		}
+		configured.Register(factory.New(settings[lang]))
+	}
+	return configured, nil
 }
 
 // Register adds a parser to the registry.
@@ -266,14 +361,36 @@ type ParseOptions struct {
 	// ExtractBodies determines whether to include function/method body text.
 	// Default: false (bodies are expensive and often not needed)
 	ExtractBodies bool
+
+	// ExtractBodyTokens sets Symbol.BodyTokens on functions and methods to
+	// the normalized token stream of their body (see NormalizeBodyTokens).
+	// Used for clone detection; the stream is a byte per token rather than
+	// the body text. Supported by the Go, Python, TypeScript and JavaScript
+	// parsers. Default: false
+	ExtractBodyTokens bool
+
+	// CaptureLiteralArgs keeps the string, number and bool arguments of
+	// every call in CallSite.LiteralArgs, for configuration tracing.
+	// Supported by the Go, Python, TypeScript and JavaScript parsers.
+	// Default: false (only registration calls keep their string arguments)
+	CaptureLiteralArgs bool
+
+	// KeepSensitiveLiterals turns off the redaction of literal arguments
+	// that look like secrets (see CallSite.LiteralArgs).
+	// Default: false (sensitive-looking literals are redacted)
+	KeepSensitiveLiterals bool
 }
 
 // DefaultParseOptions returns the default parse options.
 func DefaultParseOptions() ParseOptions {
 	return ParseOptions{
-		IncludeComments: false,
-		IncludePrivate:  true,
-		MaxDepth:        0,
-		ExtractBodies:   false,
+		IncludeComments:   false,
+		IncludePrivate:    true,
+		MaxDepth:          0,
+		ExtractBodies:     false,
+		ExtractBodyTokens: false,
+
+		CaptureLiteralArgs:    false,
+		KeepSensitiveLiterals: false,
 	}
 }
@@ -0,0 +1,285 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Parser option names accepted in ParserSettings.
+const (
+	// OptionMaxFileSize is the largest file in bytes the parser accepts.
+	OptionMaxFileSize = "max_file_size"
+
+	// OptionIncludePrivate keeps non-exported symbols (ParseOptions.IncludePrivate).
+	OptionIncludePrivate = "include_private"
+
+	// OptionIncludeComments is ParseOptions.IncludeComments.
+	OptionIncludeComments = "include_comments"
+
+	// OptionMaxDepth is ParseOptions.MaxDepth.
+	OptionMaxDepth = "max_depth"
+
+	// OptionExtractBodies is ParseOptions.ExtractBodies.
+	OptionExtractBodies = "extract_bodies"
+
+	// OptionExtractBodyTokens is ParseOptions.ExtractBodyTokens.
+	OptionExtractBodyTokens = "extract_body_tokens"
+
+	// OptionExtractColumns extracts SQL table columns as child symbols.
+	OptionExtractColumns = "extract_columns"
+
+	// OptionCaptureLiteralArgs is ParseOptions.CaptureLiteralArgs.
+	OptionCaptureLiteralArgs = "capture_literal_args"
+
+	// OptionKeepSensitiveLiterals is ParseOptions.KeepSensitiveLiterals.
+	OptionKeepSensitiveLiterals = "keep_sensitive_literals"
+)
+
+var (
+	// ErrUnknownParserOption indicates a ParserSettings key the language's
+	// parser does not accept.
+	ErrUnknownParserOption = errors.New("unknown parser option")
+
+	// ErrInvalidParserOption indicates a ParserSettings value of the wrong
+	// type or out of range.
+	ErrInvalidParserOption = errors.New("invalid parser option")
+)
+
+// parserOptionIsBool records the value type of each option: true for
+// booleans, false for non-negative integers.
+var parserOptionIsBool = map[string]bool{
+	OptionMaxFileSize:       false,
+	OptionIncludePrivate:    true,
+	OptionIncludeComments:   true,
+	OptionMaxDepth:          false,
+	OptionExtractBodies:     true,
+	OptionExtractBodyTokens: true,
+	OptionExtractColumns:    true,
+
+	OptionCaptureLiteralArgs:    true,
+	OptionKeepSensitiveLiterals: true,
+}
+
+// ParserSettings are parser option values for one language, keyed by
+// option name (OptionMaxFileSize, ...). Values are bools or non-negative
+// integers, as decoded from JSON (float64) or set in Go (int, int64).
+// Options that are not set keep the parser's defaults.
+type ParserSettings map[string]any
+
+// Bool returns the boolean option name, or def when it is not set.
+func (s ParserSettings) Bool(name string, def bool) bool {
+	if v, ok := s[name].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// Int returns the integer option name, or def when it is not set.
+func (s ParserSettings) Int(name string, def int64) int64 {
+	if v, ok := parserSettingInt(s[name]); ok {
+		return v
+	}
+	return def
+}
+
+// parseOptions returns DefaultParseOptions with the settings applied.
+func (s ParserSettings) parseOptions() ParseOptions {
+	opts := DefaultParseOptions()
+	opts.IncludePrivate = s.Bool(OptionIncludePrivate, opts.IncludePrivate)
+	opts.IncludeComments = s.Bool(OptionIncludeComments, opts.IncludeComments)
+	opts.MaxDepth = int(s.Int(OptionMaxDepth, int64(opts.MaxDepth)))
+	opts.ExtractBodies = s.Bool(OptionExtractBodies, opts.ExtractBodies)
+	opts.ExtractBodyTokens = s.Bool(OptionExtractBodyTokens, opts.ExtractBodyTokens)
+	opts.CaptureLiteralArgs = s.Bool(OptionCaptureLiteralArgs, opts.CaptureLiteralArgs)
+	opts.KeepSensitiveLiterals = s.Bool(OptionKeepSensitiveLiterals, opts.KeepSensitiveLiterals)
+	return opts
+}
+
+// parserSettingInt converts a whole, non-negative number to int64.
+func parserSettingInt(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), n >= 0
+	case int64:
+		return n, n >= 0
+	case float64:
+		if n < 0 || n != math.Trunc(n) || n > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// ParserFactory constructs parsers of one language from ParserSettings.
+type ParserFactory struct {
+	// Language is the language of the parsers, as returned by Language().
+	Language string
+
+	// Options are the option names New reads. Settings with any other
+	// name are rejected with ErrUnknownParserOption.
+	Options []string
+
+	// New returns a parser configured with settings, which have been
+	// validated against Options. Nil settings yield the defaults.
+	New func(settings ParserSettings) Parser
+}
+
+// Validate checks settings against the factory's options.
+//
+// Outputs:
+//
+//	error - Wraps ErrUnknownParserOption, naming the closest known option
+//	  when one is near, or ErrInvalidParserOption for a value of the wrong
+//	  type. Nil when every setting is valid.
+func (f ParserFactory) Validate(settings ParserSettings) error {
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		known := false
+		for _, opt := range f.Options {
+			if opt == name {
+				known = true
+				break
+			}
+		}
+		if !known {
+			msg := fmt.Sprintf("%q for %s", name, f.Language)
+			if near := closestName(name, f.Options); near != "" {
+				msg += fmt.Sprintf(" (did you mean %q?)", near)
+			}
+			return fmt.Errorf("%w: %s; accepted: %s", ErrUnknownParserOption, msg, strings.Join(f.Options, ", "))
+		}
+		value := settings[name]
+		if parserOptionIsBool[name] {
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("%w: %s.%s must be a boolean", ErrInvalidParserOption, f.Language, name)
+			}
+		} else if _, ok := parserSettingInt(value); !ok {
+			return fmt.Errorf("%w: %s.%s must be a non-negative integer", ErrInvalidParserOption, f.Language, name)
+		}
+	}
+	return nil
+}
+
+// parseOptionNames are the options of parsers configured by MaxFileSize
+// and ParseOptions.
+var parseOptionNames = []string{
+	OptionMaxFileSize, OptionIncludePrivate, OptionIncludeComments,
+	OptionMaxDepth, OptionExtractBodies, OptionExtractBodyTokens,
+	OptionCaptureLiteralArgs, OptionKeepSensitiveLiterals,
+}
+
+// DefaultParserFactories returns the factories of the parsers the trace
+// service builds graphs with: go, python, typescript, javascript, sql,
+// html, css, proto, kotlin, swift, php, ruby and elixir.
+//
+// Thread Safety: Safe for concurrent use. Each call returns new values.
+func DefaultParserFactories() []ParserFactory {
+	return []ParserFactory{
+		{Language: "go", Options: parseOptionNames, New: func(s ParserSettings) Parser {
+			return NewGoParser(WithMaxFileSize(s.Int(OptionMaxFileSize, 0)), WithParseOptions(s.parseOptions()))
+		}},
+		{Language: "python", Options: parseOptionNames, New: func(s ParserSettings) Parser {
+			return NewPythonParser(WithPythonMaxFileSize(s.Int(OptionMaxFileSize, 0)), WithPythonParseOptions(s.parseOptions()))
+		}},
+		{Language: "typescript", Options: parseOptionNames, New: func(s ParserSettings) Parser {
+			return NewTypeScriptParser(WithTypeScriptMaxFileSize(s.Int(OptionMaxFileSize, 0)), WithTypeScriptParseOptions(s.parseOptions()))
+		}},
+		{Language: "javascript", Options: []string{
+			OptionMaxFileSize, OptionIncludePrivate, OptionExtractBodies, OptionExtractBodyTokens,
+			OptionCaptureLiteralArgs, OptionKeepSensitiveLiterals,
+		}, New: func(s ParserSettings) Parser {
+			def := DefaultJavaScriptParserOptions()
+			return NewJavaScriptParser(
+				WithJSMaxFileSize(int(s.Int(OptionMaxFileSize, int64(def.MaxFileSize)))),
+				WithJSIncludePrivate(s.Bool(OptionIncludePrivate, def.IncludePrivate)),
+				WithJSExtractBodies(s.Bool(OptionExtractBodies, def.ExtractBodies)),
+				WithJSExtractBodyTokens(s.Bool(OptionExtractBodyTokens, def.ExtractBodyTokens)),
+				WithJSCaptureLiteralArgs(s.Bool(OptionCaptureLiteralArgs, def.CaptureLiteralArgs)),
+				WithJSKeepSensitiveLiterals(s.Bool(OptionKeepSensitiveLiterals, def.KeepSensitiveLiterals)),
+			)
+		}},
+		{Language: "sql", Options: []string{OptionMaxFileSize, OptionExtractColumns}, New: func(s ParserSettings) Parser {
+			def := DefaultSQLParserOptions()
+			return NewSQLParser(
+				WithSQLMaxFileSize(int(s.Int(OptionMaxFileSize, int64(def.MaxFileSize)))),
+				WithSQLExtractColumns(s.Bool(OptionExtractColumns, def.ExtractColumns)),
+			)
+		}},
+		{Language: "proto", Options: []string{OptionMaxFileSize}, New: func(s ParserSettings) Parser {
+			return NewProtoParser(WithProtoMaxFileSize(int(s.Int(OptionMaxFileSize, int64(DefaultProtoParserOptions().MaxFileSize)))))
+		}},
+		{Language: "html", Options: []string{OptionMaxFileSize}, New: func(s ParserSettings) Parser {
+			return NewHTMLParser(WithHTMLMaxFileSize(int(s.Int(OptionMaxFileSize, int64(DefaultHTMLParserOptions().MaxFileSize)))))
+		}},
+		{Language: "css", Options: []string{OptionMaxFileSize}, New: func(s ParserSettings) Parser {
+			return NewCSSParser(WithCSSMaxFileSize(int(s.Int(OptionMaxFileSize, int64(DefaultCSSParserOptions().MaxFileSize)))))
+		}},
+		{Language: "kotlin", Options: parseOptionNames, New: func(s ParserSettings) Parser {
+			return NewKotlinParser(WithKotlinMaxFileSize(s.Int(OptionMaxFileSize, 0)), WithKotlinParseOptions(s.parseOptions()))
+		}},
+		{Language: "swift", Options: parseOptionNames, New: func(s ParserSettings) Parser {
+			return NewSwiftParser(WithSwiftMaxFileSize(s.Int(OptionMaxFileSize, 0)), WithSwiftParseOptions(s.parseOptions()))
+		}},
+		{Language: "php", Options: parseOptionNames, New: func(s ParserSettings) Parser {
+			return NewPHPParser(WithPHPMaxFileSize(s.Int(OptionMaxFileSize, 0)), WithPHPParseOptions(s.parseOptions()))
+		}},
+		{Language: "ruby", Options: parseOptionNames, New: func(s ParserSettings) Parser {
+			return NewRubyParser(WithRubyMaxFileSize(s.Int(OptionMaxFileSize, 0)), WithRubyParseOptions(s.parseOptions()))
+		}},
+		{Language: "elixir", Options: parseOptionNames, New: func(s ParserSettings) Parser {
+			return NewElixirParser(WithElixirMaxFileSize(s.Int(OptionMaxFileSize, 0)), WithElixirParseOptions(s.parseOptions()))
+		}},
+	}
+}
+
+// closestName returns the candidate nearest to name by edit distance,
+// or "" if none is within max(2, len(name)/4) edits. Ties go to the
+// earlier candidate.
+func closestName(name string, candidates []string) string {
+	best, bestDist := "", max(2, len(name)/4)+1
+	for _, c := range candidates {
+		if d := editDistance(name, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
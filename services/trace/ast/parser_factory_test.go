@@ -0,0 +1,105 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newFactoryRegistry() *ParserRegistry {
+	r := NewParserRegistry()
+	for _, f := range DefaultParserFactories() {
+		r.RegisterFactory(f)
+	}
+	return r
+}
+
+func TestParserFactory_Validate(t *testing.T) {
+	r := newFactoryRegistry()
+	goFactory, ok := r.Factory("go")
+	if !ok {
+		t.Fatal("no go factory")
+	}
+
+	if err := goFactory.Validate(ParserSettings{OptionExtractBodies: true, OptionMaxFileSize: float64(1 << 20)}); err != nil {
+		t.Errorf("valid settings rejected: %v", err)
+	}
+
+	err := goFactory.Validate(ParserSettings{"extract_body": true})
+	if !errors.Is(err, ErrUnknownParserOption) || !strings.Contains(err.Error(), `did you mean "extract_bodies"`) {
+		t.Errorf("misspelled option: err = %v", err)
+	}
+	err = goFactory.Validate(ParserSettings{"colour": true})
+	if !errors.Is(err, ErrUnknownParserOption) || strings.Contains(err.Error(), "did you mean") {
+		t.Errorf("unrelated option: err = %v", err)
+	}
+
+	sqlFactory, _ := r.Factory("sql")
+	if err := sqlFactory.Validate(ParserSettings{OptionIncludePrivate: true}); !errors.Is(err, ErrUnknownParserOption) {
+		t.Errorf("go-only option accepted for sql: %v", err)
+	}
+
+	for _, bad := range []ParserSettings{
+		{OptionExtractBodies: "yes"},
+		{OptionMaxFileSize: true},
+		{OptionMaxFileSize: float64(-1)},
+		{OptionMaxFileSize: 1.5},
+	} {
+		if err := goFactory.Validate(bad); !errors.Is(err, ErrInvalidParserOption) {
+			t.Errorf("Validate(%v) = %v, want ErrInvalidParserOption", bad, err)
+		}
+	}
+}
+
+func TestParserRegistry_Configure(t *testing.T) {
+	r := newFactoryRegistry()
+	if got, err := r.Configure(nil); err != nil || got != r {
+		t.Errorf("Configure(nil) = %p, %v; want the registry itself", got, err)
+	}
+
+	configured, err := r.Configure(map[string]ParserSettings{"go": {OptionIncludePrivate: false}})
+	if err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	src := []byte("package p\n\nfunc Exported() {}\n\nfunc private() {}\n")
+	count := func(reg *ParserRegistry) int {
+		t.Helper()
+		p, ok := reg.GetByExtension(".go")
+		if !ok {
+			t.Fatal("no .go parser")
+		}
+		result, err := p.Parse(context.Background(), src, "p.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(result.Symbols)
+	}
+	if shared, own := count(r), count(configured); own != shared-1 {
+		t.Errorf("symbols: shared %d, configured %d; want one private symbol dropped", shared, own)
+	}
+
+	// Other languages keep the shared parsers.
+	sharedPy, _ := r.GetByLanguage("python")
+	ownPy, _ := configured.GetByLanguage("python")
+	if sharedPy != ownPy {
+		t.Error("python parser was replaced")
+	}
+
+	if _, err := r.Configure(map[string]ParserSettings{"cobol": {}}); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("unknown language: err = %v, want ErrUnsupportedLanguage", err)
+	}
+	if _, err := r.Configure(map[string]ParserSettings{"go": {"typo": true}}); !errors.Is(err, ErrUnknownParserOption) {
+		t.Errorf("unknown option: err = %v, want ErrUnknownParserOption", err)
+	}
+}
@@ -0,0 +1,941 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/php"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// PHPParserOption configures a PHPParser instance.
+type PHPParserOption func(*PHPParser)
+
+// WithPHPMaxFileSize sets the maximum file size the parser will accept.
+//
+// Parameters:
+//   - bytes: Maximum file size in bytes. Must be positive.
+//
+// Example:
+//
+//	parser := NewPHPParser(WithPHPMaxFileSize(5 * 1024 * 1024)) // 5MB limit
+func WithPHPMaxFileSize(bytes int64) PHPParserOption {
+	return func(p *PHPParser) {
+		if bytes > 0 {
+			p.maxFileSize = bytes
+		}
+	}
+}
+
+// WithPHPParseOptions applies the given ParseOptions to the parser.
+//
+// Parameters:
+//   - opts: ParseOptions to apply.
+//
+// Example:
+//
+//	parser := NewPHPParser(WithPHPParseOptions(ParseOptions{IncludePrivate: false}))
+func WithPHPParseOptions(opts ParseOptions) PHPParserOption {
+	return func(p *PHPParser) {
+		p.parseOptions = opts
+	}
+}
+
+// PHPParser implements the Parser interface for PHP source code.
+//
+// Description:
+//
+//	PHPParser uses tree-sitter to parse .php files and extract symbols,
+//	mapped onto the existing symbol kinds:
+//
+//	  namespace          -> ParseResult.Package and Symbol.Package
+//	                        ("App\Services")
+//	  use App\Models\User -> Import, Path "App\Models\User", Names ["User"]
+//	  class              -> SymbolKindClass
+//	  trait              -> SymbolKindClass with Metadata.IsAbstract and
+//	                        signature "trait Name"
+//	  interface          -> SymbolKindInterface, with Metadata.Methods for
+//	                        implicit implementation detection
+//	  enum               -> SymbolKindEnum, cases as SymbolKindEnumMember
+//	                        children
+//	  method             -> SymbolKindMethod, Receiver = class
+//	  function           -> SymbolKindFunction
+//	  property           -> SymbolKindField, named without the "$"
+//	  const              -> SymbolKindConstant
+//
+//	"extends" goes to Metadata.Extends and "implements" to
+//	Metadata.Implements. Traits used inside a class body ("use
+//	LogsActivity;") go to Metadata.Mixins; the graph builder links them
+//	with EMBEDS edges so trait methods are promoted onto the class the
+//	way Go promotes the methods of embedded fields. Constructor property
+//	promotion (public function __construct(private Repo $repo)) yields a
+//	field. Attributes are recorded in Metadata.Decorators and docblocks
+//	in DocComment. Framework conventions (Laravel facades, magic
+//	__call) are not modelled.
+//
+// Thread Safety:
+//
+//	PHPParser instances are safe for concurrent use. Each Parse call
+//	creates its own tree-sitter parser instance.
+//
+// Example:
+//
+//	parser := NewPHPParser()
+//	result, err := parser.Parse(ctx, content, "src/Services/UserService.php")
+//	if err != nil {
+//	    return fmt.Errorf("parse: %w", err)
+//	}
+//	for _, sym := range result.Symbols {
+//	    fmt.Printf("%s: %s\n", sym.Kind, sym.Name)
+//	}
+type PHPParser struct {
+	maxFileSize  int64
+	parseOptions ParseOptions
+}
+
+// NewPHPParser creates a new PHPParser with the given options.
+//
+// Inputs:
+//   - opts: Optional configuration functions (WithPHPMaxFileSize, WithPHPParseOptions)
+//
+// Outputs:
+//   - *PHPParser: Configured parser instance, never nil
+//
+// Thread Safety:
+//
+//	The returned PHPParser is safe for concurrent use.
+func NewPHPParser(opts ...PHPParserOption) *PHPParser {
+	p := &PHPParser{
+		maxFileSize:  DefaultMaxFileSize,
+		parseOptions: DefaultParseOptions(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Language returns the canonical language name for this parser.
+func (p *PHPParser) Language() string {
+	return "php"
+}
+
+// Extensions returns the file extensions this parser handles.
+func (p *PHPParser) Extensions() []string {
+	return []string{".php"}
+}
+
+// phpFile holds the per-file state of one Parse call.
+type phpFile struct {
+	content  []byte
+	filePath string
+	// namespace is the namespace in effect; a file may declare several.
+	namespace string
+	lines     []string
+}
+
+// Parse extracts symbols from PHP source code.
+//
+// Description:
+//
+//	Parses the provided PHP source with tree-sitter and extracts
+//	namespaces, use statements, classes, interfaces, traits, enums,
+//	functions, properties and constants. The parser is error-tolerant:
+//	syntax errors are reported in ParseResult.Errors and the remaining
+//	declarations are still extracted.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked before and after parsing.
+//   - content: Raw PHP source bytes. Must be valid UTF-8.
+//   - filePath: Path to the file, relative to the project root.
+//
+// Outputs:
+//   - *ParseResult: Extracted symbols and metadata. Never nil on success.
+//   - error: Non-nil for complete failures:
+//   - ErrFileTooLarge: Content exceeds maxFileSize
+//   - ErrInvalidContent: Content is not valid UTF-8
+//   - Context errors: Context was canceled or timed out
+//
+// Thread Safety:
+//
+//	This method is safe for concurrent use.
+func (p *PHPParser) Parse(ctx context.Context, content []byte, filePath string) (*ParseResult, error) {
+	ctx, span := startParseSpan(ctx, "php", filePath, len(content))
+	defer span.End()
+
+	start := time.Now()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "php", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled before start: %w", err)
+	}
+
+	if int64(len(content)) > p.maxFileSize {
+		recordParseMetrics(ctx, "php", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: size %d exceeds limit %d", ErrFileTooLarge, len(content), p.maxFileSize)
+	}
+
+	if !utf8.Valid(content) {
+		recordParseMetrics(ctx, "php", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: content is not valid UTF-8", ErrInvalidContent)
+	}
+
+	hash := sha256.Sum256(content)
+	hashStr := hex.EncodeToString(hash[:])
+
+	// New tree-sitter parser per call for thread safety
+	parser := sitter.NewParser()
+	parser.SetLanguage(php.GetLanguage())
+
+	tree, err := parser.ParseCtx(ctx, nil, content)
+	if err != nil {
+		recordParseMetrics(ctx, "php", time.Since(start), 0, false)
+		return nil, fmt.Errorf("tree-sitter parse failed: %w", err)
+	}
+	defer tree.Close()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "php", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled after tree-sitter: %w", err)
+	}
+
+	result := &ParseResult{
+		FilePath:      filePath,
+		Language:      "php",
+		Hash:          hashStr,
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Symbols:       make([]*Symbol, 0),
+		Imports:       make([]Import, 0),
+		Errors:        make([]string, 0),
+	}
+
+	rootNode := tree.RootNode()
+	if rootNode == nil {
+		result.Errors = append(result.Errors, "tree-sitter returned nil root node")
+		return result, nil
+	}
+
+	f := &phpFile{
+		content:  content,
+		filePath: filePath,
+		lines:    strings.Split(string(content), "\n"),
+	}
+	p.extractSymbols(ctx, rootNode, f, result)
+
+	if err := result.Validate(); err != nil {
+		recordParseMetrics(ctx, "php", time.Since(start), 0, false)
+		return nil, fmt.Errorf("result validation failed: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "php", time.Since(start), len(result.Symbols), false)
+		return nil, fmt.Errorf("parse canceled after extraction: %w", err)
+	}
+
+	setParseSpanResult(span, len(result.Symbols), len(result.Errors))
+	recordParseMetrics(ctx, "php", time.Since(start), len(result.Symbols), true)
+
+	return result, nil
+}
+
+// extractSymbols extracts the namespace, use statements and declarations
+// directly under parent: the program, or the body of a braced namespace.
+func (p *PHPParser) extractSymbols(ctx context.Context, parent *sitter.Node, f *phpFile, result *ParseResult) {
+	for i := 0; i < int(parent.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			return
+		}
+
+		child := parent.Child(i)
+		var syms []*Symbol
+		switch child.Type() {
+		case phpNodeNamespaceDef:
+			// "namespace A;" applies to the declarations that follow it;
+			// "namespace A { ... }" only to its body.
+			f.namespace = ""
+			if name := child.ChildByFieldName("name"); name != nil {
+				f.namespace = phpNodeText(name, f.content)
+			}
+			if result.Package == "" {
+				result.Package = f.namespace
+			}
+			if body := child.ChildByFieldName("body"); body != nil {
+				p.extractSymbols(ctx, body, f, result)
+				f.namespace = ""
+			}
+		case phpNodeNamespaceUseDecl:
+			p.extractUse(child, f, result)
+		case phpNodeClassDecl, phpNodeInterfaceDecl, phpNodeTraitDecl, phpNodeEnumDecl:
+			syms = append(syms, p.extractClass(ctx, child, f))
+		case phpNodeFunctionDef:
+			syms = append(syms, p.extractFunction(ctx, child, f, ""))
+		case phpNodeConstDecl:
+			syms = p.extractConstants(child, f, "")
+		case phpNodeERROR:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s:%d: syntax error: %s",
+				f.filePath, int(child.StartPoint().Row)+1, sqlSnippet(f.content[child.StartByte():child.EndByte()])))
+		}
+		for _, sym := range syms {
+			if sym != nil && p.include(sym) {
+				result.Symbols = append(result.Symbols, sym)
+			}
+		}
+	}
+}
+
+// extractUse records the imports of a namespace use declaration.
+// "use App\Models\User as U;" becomes Path "App\Models\User", Names
+// ["User"], Alias "U"; the group form "use App\Models\{User, Post};"
+// yields one import per clause.
+func (p *PHPParser) extractUse(node *sitter.Node, f *phpFile, result *ParseResult) {
+	prefix := ""
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case phpNodeNamespaceName:
+			prefix = phpNodeText(child, f.content)
+		case phpNodeNamespaceUse:
+			p.appendImport(child, "", f, result)
+		case phpNodeNamespaceUseGroup:
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				if clause := child.NamedChild(j); clause.Type() == phpNodeUseGroupClause || clause.Type() == phpNodeNamespaceUse {
+					p.appendImport(clause, prefix, f, result)
+				}
+			}
+		}
+	}
+}
+
+// appendImport adds the import named by a use clause, prefixed with the
+// group prefix if any.
+func (p *PHPParser) appendImport(clause *sitter.Node, prefix string, f *phpFile, result *ParseResult) {
+	var path, alias string
+	for i := 0; i < int(clause.NamedChildCount()); i++ {
+		child := clause.NamedChild(i)
+		if child.Type() == phpNodeAliasingClause {
+			if name := phpChildByType(child, phpNodeName); name != nil {
+				alias = phpNodeText(name, f.content)
+			}
+		} else if path == "" {
+			path = strings.TrimPrefix(phpNodeText(child, f.content), `\`)
+		}
+	}
+	if path == "" {
+		return
+	}
+	if prefix != "" {
+		path = strings.TrimSuffix(prefix, `\`) + `\` + path
+	}
+	result.Imports = append(result.Imports, Import{
+		Path:     path,
+		Names:    []string{phpLastSegment(path)},
+		Alias:    alias,
+		Location: phpLocation(clause, f.filePath),
+	})
+}
+
+// extractClass builds a class, interface, trait or enum symbol with its
+// members as children.
+func (p *PHPParser) extractClass(ctx context.Context, node *sitter.Node, f *phpFile) *Symbol {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := phpNodeText(nameNode, f.content)
+	body := node.ChildByFieldName("body")
+
+	kind := SymbolKindClass
+	switch node.Type() {
+	case phpNodeInterfaceDecl:
+		kind = SymbolKindInterface
+	case phpNodeEnumDecl:
+		kind = SymbolKindEnum
+	}
+
+	var parents, interfaces []string
+	abstract := node.Type() == phpNodeTraitDecl
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case phpNodeAbstractModifier:
+			abstract = true
+		case phpNodeBaseClause:
+			parents = phpTypeNames(child, f.content)
+		case phpNodeInterfaceClause:
+			interfaces = phpTypeNames(child, f.content)
+		}
+	}
+
+	sym := p.newSymbol(node, f, name, kind, phpDeclSignature(node, body, f))
+	if decorators := phpAttributes(node, f.content); len(decorators) > 0 {
+		phpMetadata(sym).Decorators = decorators
+	}
+	if abstract {
+		phpMetadata(sym).IsAbstract = true
+	}
+
+	// Interfaces list their parents like Go interface embeds: the first
+	// in Extends, the rest in Implements.
+	if kind == SymbolKindInterface && len(parents) > 0 {
+		parents, interfaces = parents[:1], parents[1:]
+	}
+	if len(parents) > 0 {
+		phpMetadata(sym).Extends = parents[0]
+	}
+	if len(interfaces) > 0 {
+		phpMetadata(sym).Implements = interfaces
+	}
+
+	if body != nil {
+		p.extractMembers(ctx, body, f, sym)
+	}
+	phpCollectMethods(sym)
+	return sym
+}
+
+// extractMembers adds the declarations in a class-like body to classSym.
+func (p *PHPParser) extractMembers(ctx context.Context, body *sitter.Node, f *phpFile, classSym *Symbol) {
+	for i := 0; i < int(body.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			return
+		}
+		child := body.Child(i)
+		var members []*Symbol
+		switch child.Type() {
+		case phpNodeUseDecl:
+			if traits := phpTypeNames(child, f.content); len(traits) > 0 {
+				meta := phpMetadata(classSym)
+				meta.Mixins = append(meta.Mixins, traits...)
+			}
+		case phpNodeMethodDecl:
+			method := p.extractFunction(ctx, child, f, classSym.Name)
+			members = append(members, method)
+			if method != nil && method.Metadata.IsConstructor {
+				members = append(members, p.extractPromotedParams(child, f, classSym.Name)...)
+			}
+		case phpNodePropertyDecl:
+			members = p.extractProperties(child, f, classSym.Name)
+		case phpNodeConstDecl:
+			members = p.extractConstants(child, f, classSym.Name)
+		case phpNodeEnumCase:
+			if nameNode := child.ChildByFieldName("name"); nameNode != nil {
+				entry := phpNodeText(nameNode, f.content)
+				member := p.newSymbol(child, f, entry, SymbolKindEnumMember, phpCollapseSpace(strings.TrimSuffix(phpNodeText(child, f.content), ";")))
+				phpMetadata(member).ParentName = classSym.Name
+				members = append(members, member)
+			}
+		}
+		for _, member := range members {
+			if member != nil && p.include(member) {
+				classSym.Children = append(classSym.Children, member)
+			}
+		}
+	}
+}
+
+// extractFunction builds a function symbol, or a method with Receiver =
+// owner when owner is not "".
+func (p *PHPParser) extractFunction(ctx context.Context, node *sitter.Node, f *phpFile, owner string) *Symbol {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := phpNodeText(nameNode, f.content)
+	params := node.ChildByFieldName("parameters")
+	returnType := node.ChildByFieldName("return_type")
+
+	sigEnd := nameNode.EndByte()
+	switch {
+	case returnType != nil:
+		sigEnd = returnType.EndByte()
+	case params != nil:
+		sigEnd = params.EndByte()
+	}
+	signature := phpCollapseSpace(string(f.content[phpDeclStart(node).StartByte():sigEnd]))
+
+	kind := SymbolKindFunction
+	if owner != "" {
+		kind = SymbolKindMethod
+	}
+	sym := p.newSymbol(node, f, name, kind, signature)
+	sym.Receiver = owner
+	p.applyModifiers(sym, node, f)
+	meta := phpMetadata(sym)
+	if owner != "" {
+		meta.ParentName = owner
+		meta.IsConstructor = name == "__construct"
+	}
+	if returnType != nil {
+		meta.ReturnType = strings.TrimPrefix(phpNodeText(returnType, f.content), "?")
+	}
+	if body := node.ChildByFieldName("body"); body != nil {
+		sym.Calls = p.extractCallSites(ctx, body, f)
+	}
+	return sym
+}
+
+// extractPromotedParams builds field symbols for constructor parameters
+// declared with a visibility (constructor property promotion).
+func (p *PHPParser) extractPromotedParams(ctor *sitter.Node, f *phpFile, owner string) []*Symbol {
+	params := ctor.ChildByFieldName("parameters")
+	if params == nil {
+		return nil
+	}
+	var fields []*Symbol
+	for i := 0; i < int(params.NamedChildCount()); i++ {
+		param := params.NamedChild(i)
+		if param.Type() != phpNodePromotedParam {
+			continue
+		}
+		varName := param.ChildByFieldName("name")
+		if varName == nil {
+			continue
+		}
+		field := p.newSymbol(param, f, phpVariableName(varName, f.content), SymbolKindField,
+			phpCollapseSpace(phpNodeText(param, f.content)))
+		field.Receiver = owner
+		p.applyModifiers(field, param, f)
+		meta := phpMetadata(field)
+		meta.ParentName = owner
+		if typeNode := param.ChildByFieldName("type"); typeNode != nil {
+			meta.ReturnType = strings.TrimPrefix(phpNodeText(typeNode, f.content), "?")
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// extractProperties builds one field symbol per property in a property
+// declaration ("private int $a = 1, $b;" declares two).
+func (p *PHPParser) extractProperties(node *sitter.Node, f *phpFile, owner string) []*Symbol {
+	// The signature of each property is the shared modifiers and type
+	// followed by its own name: "private int $b".
+	var modifiers string
+	if first := phpChildByType(node, phpNodePropertyElement); first != nil {
+		modifiers = string(f.content[phpDeclStart(node).StartByte():first.StartByte()])
+	}
+	typeNode := node.ChildByFieldName("type")
+	var fields []*Symbol
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		element := node.NamedChild(i)
+		if element.Type() != phpNodePropertyElement {
+			continue
+		}
+		varName := phpChildByType(element, phpNodeVariableName)
+		if varName == nil {
+			continue
+		}
+		field := p.newSymbol(element, f, phpVariableName(varName, f.content), SymbolKindField,
+			phpCollapseSpace(modifiers+phpNodeText(varName, f.content)))
+		field.Receiver = owner
+		p.applyModifiers(field, node, f)
+		meta := phpMetadata(field)
+		meta.ParentName = owner
+		if typeNode != nil {
+			meta.ReturnType = strings.TrimPrefix(phpNodeText(typeNode, f.content), "?")
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// extractConstants builds one constant symbol per element of a const
+// declaration. Class constants get Receiver = owner.
+func (p *PHPParser) extractConstants(node *sitter.Node, f *phpFile, owner string) []*Symbol {
+	var consts []*Symbol
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		element := node.NamedChild(i)
+		if element.Type() != phpNodeConstElement {
+			continue
+		}
+		nameNode := phpChildByType(element, phpNodeName)
+		if nameNode == nil {
+			continue
+		}
+		sym := p.newSymbol(element, f, phpNodeText(nameNode, f.content), SymbolKindConstant,
+			"const "+phpCollapseSpace(phpNodeText(element, f.content)))
+		p.applyModifiers(sym, node, f)
+		if owner != "" {
+			sym.Receiver = owner
+			phpMetadata(sym).ParentName = owner
+		}
+		consts = append(consts, sym)
+	}
+	return consts
+}
+
+// extractCallSites extracts all call sites within a function body.
+//
+// Description:
+//
+//	Walks the subtree under bodyNode and records each call. "format()"
+//	and "\strlen()" are plain calls with the last name segment as
+//	Target; "$this->log()" is a method call with Receiver "this";
+//	"$this->repo->find()" and "$user?->save()" use the receivers
+//	"this.repo" and "user"; "parent::boot()" uses Receiver "super" and
+//	"self::make()" / "static::make()" use "self", which the graph
+//	builder resolves against the enclosing class; "Str::upper()" uses
+//	Receiver "Str". "new Repo()" is a plain call to Repo. Calls inside
+//	closures and arrow functions are included. Nested class-like
+//	declarations are skipped: their members are separate symbols.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked every 100 nodes.
+//   - bodyNode: The function body. May be nil.
+//   - f: The file being parsed.
+//
+// Outputs:
+//   - []CallSite: Extracted call sites. Limited to MaxCallSitesPerSymbol (1000).
+//
+// Thread Safety: Safe for concurrent use.
+func (p *PHPParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node, f *phpFile) []CallSite {
+	if bodyNode == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "PHPParser.extractCallSites")
+	defer span.End()
+
+	calls := make([]CallSite, 0, 8)
+
+	type stackEntry struct {
+		node  *sitter.Node
+		depth int
+	}
+	stack := []stackEntry{{node: bodyNode}}
+
+	nodeCount := 0
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := entry.node
+		if entry.depth > MaxCallExpressionDepth {
+			continue
+		}
+
+		nodeCount++
+		if nodeCount%100 == 0 && ctx.Err() != nil {
+			return calls
+		}
+
+		if len(calls) >= MaxCallSitesPerSymbol {
+			slog.Warn("max call sites per symbol reached in PHP",
+				telemetry.SensitiveLog("file", f.filePath),
+				slog.Int("limit", MaxCallSitesPerSymbol),
+			)
+			return calls
+		}
+
+		switch node.Type() {
+		case phpNodeFunctionCall, phpNodeMemberCall, phpNodeNullsafeMemberCall, phpNodeScopedCall, phpNodeObjectCreation:
+			if call := phpCallSite(node, f); call != nil {
+				calls = append(calls, *call)
+			}
+		case phpNodeClassDecl, phpNodeInterfaceDecl, phpNodeTraitDecl, phpNodeEnumDecl:
+			if node != bodyNode {
+				continue
+			}
+		}
+
+		// Push in reverse order for left-to-right processing
+		for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+			if child := node.Child(i); child != nil {
+				stack = append(stack, stackEntry{node: child, depth: entry.depth + 1})
+			}
+		}
+	}
+
+	span.SetAttributes(
+		telemetry.SensitiveString("file", f.filePath),
+		attribute.Int("calls_found", len(calls)),
+		attribute.Int("nodes_traversed", nodeCount),
+	)
+
+	return calls
+}
+
+// phpCallSite converts a call node to a CallSite, or nil if the callee is
+// dynamic ($fn(), $obj->$method(), new $class()).
+func phpCallSite(node *sitter.Node, f *phpFile) *CallSite {
+	call := &CallSite{Location: phpLocation(node, f.filePath)}
+
+	switch node.Type() {
+	case phpNodeFunctionCall:
+		fn := node.ChildByFieldName("function")
+		if fn == nil || (fn.Type() != phpNodeName && fn.Type() != phpNodeQualifiedName) {
+			return nil
+		}
+		call.Target = phpLastSegment(phpNodeText(fn, f.content))
+	case phpNodeObjectCreation:
+		class := phpChildByType(node, phpNodeName)
+		if class == nil {
+			class = phpChildByType(node, phpNodeQualifiedName)
+		}
+		if class == nil {
+			return nil
+		}
+		call.Target = phpLastSegment(phpNodeText(class, f.content))
+	case phpNodeMemberCall, phpNodeNullsafeMemberCall:
+		name := node.ChildByFieldName("name")
+		object := node.ChildByFieldName("object")
+		if name == nil || name.Type() != phpNodeName || object == nil {
+			return nil
+		}
+		call.Target = phpNodeText(name, f.content)
+		call.IsMethod = true
+		call.Receiver = phpReceiver(phpNodeText(object, f.content))
+	case phpNodeScopedCall:
+		name := node.ChildByFieldName("name")
+		scope := node.ChildByFieldName("scope")
+		if name == nil || name.Type() != phpNodeName || scope == nil {
+			return nil
+		}
+		call.Target = phpNodeText(name, f.content)
+		call.IsMethod = true
+		switch text := phpNodeText(scope, f.content); {
+		case scope.Type() == phpNodeRelativeScope && strings.EqualFold(text, "parent"):
+			call.Receiver = "super"
+		case scope.Type() == phpNodeRelativeScope:
+			call.Receiver = "self"
+		case scope.Type() == phpNodeName || scope.Type() == phpNodeQualifiedName:
+			call.Receiver = phpLastSegment(text)
+		default:
+			call.Receiver = phpReceiver(text)
+		}
+	}
+
+	if call.Target == "" {
+		return nil
+	}
+	return call
+}
+
+// phpReceiver normalizes a receiver expression to the dotted form the
+// graph builder expects: "$this" -> "this", "$this->repo" -> "this.repo",
+// "$user?->profile" -> "user.profile".
+func phpReceiver(text string) string {
+	text = phpCollapseSpace(text)
+	text = strings.ReplaceAll(text, "?->", ".")
+	text = strings.ReplaceAll(text, "->", ".")
+	text = strings.ReplaceAll(text, "$", "")
+	return text
+}
+
+// newSymbol creates a PHP symbol with its location, namespace and doc
+// comment set.
+func (p *PHPParser) newSymbol(node *sitter.Node, f *phpFile, name string, kind SymbolKind, signature string) *Symbol {
+	return &Symbol{
+		ID:            GenerateID(f.filePath, int(node.StartPoint().Row)+1, name),
+		Name:          name,
+		Kind:          kind,
+		FilePath:      f.filePath,
+		StartLine:     int(node.StartPoint().Row) + 1,
+		EndLine:       int(node.EndPoint().Row) + 1,
+		StartCol:      int(node.StartPoint().Column),
+		EndCol:        int(node.EndPoint().Column),
+		Signature:     signature,
+		DocComment:    cStyleDocComment(f.lines, int(node.StartPoint().Row)),
+		Package:       f.namespace,
+		Language:      "php",
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Exported:      true,
+	}
+}
+
+// applyModifiers sets visibility, static, abstract and attributes from
+// the modifier children of node. PHP members are public unless marked
+// otherwise; private ones are not exported.
+func (p *PHPParser) applyModifiers(sym *Symbol, node *sitter.Node, f *phpFile) {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case phpNodeVisibilityModifier:
+			visibility := strings.ToLower(phpNodeText(child, f.content))
+			phpMetadata(sym).AccessModifier = visibility
+			sym.Exported = visibility != "private"
+		case phpNodeStaticModifier:
+			phpMetadata(sym).IsStatic = true
+		case phpNodeAbstractModifier:
+			phpMetadata(sym).IsAbstract = true
+		}
+	}
+	if decorators := phpAttributes(node, f.content); len(decorators) > 0 {
+		phpMetadata(sym).Decorators = decorators
+	}
+}
+
+// include reports whether sym passes the IncludePrivate option.
+func (p *PHPParser) include(sym *Symbol) bool {
+	return p.parseOptions.IncludePrivate || sym.Exported
+}
+
+// phpCollectMethods fills Metadata.Methods of a class-like symbol from its
+// instance methods, so the graph builder can match classes to the
+// interfaces they implement.
+func phpCollectMethods(sym *Symbol) {
+	for _, child := range sym.Children {
+		if child.Kind != SymbolKindMethod || child.Metadata == nil ||
+			child.Metadata.IsStatic || child.Metadata.IsConstructor {
+			continue
+		}
+		meta := phpMetadata(sym)
+		meta.Methods = append(meta.Methods, phpMethodSignature(child, sym.Name))
+	}
+}
+
+// phpMethodSignature builds the MethodSignature of a method symbol from
+// its "function name(params): Return" signature.
+func phpMethodSignature(method *Symbol, receiverType string) MethodSignature {
+	sig := MethodSignature{
+		Name:         method.Name,
+		ReceiverType: receiverType,
+	}
+	if open := strings.IndexByte(method.Signature, '('); open >= 0 {
+		if end := strings.LastIndexByte(method.Signature, ')'); end > open {
+			sig.Params = method.Signature[open+1 : end]
+		}
+	}
+	if sig.Params != "" {
+		sig.ParamCount = strings.Count(sig.Params, ",") + 1
+	}
+	if method.Metadata != nil && method.Metadata.ReturnType != "" {
+		sig.Returns = method.Metadata.ReturnType
+		sig.ReturnCount = 1
+	}
+	return sig
+}
+
+// phpDeclStart returns the first child of a declaration after its
+// attributes, where the signature begins.
+func phpDeclStart(node *sitter.Node) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() != phpNodeAttributeList && child.Type() != "comment" {
+			return child
+		}
+	}
+	return node
+}
+
+// phpDeclSignature returns the text of a class-like declaration from its
+// first modifier or keyword up to the body.
+func phpDeclSignature(node, body *sitter.Node, f *phpFile) string {
+	end := node.EndByte()
+	if body != nil {
+		end = body.StartByte()
+	}
+	return phpCollapseSpace(string(f.content[phpDeclStart(node).StartByte():end]))
+}
+
+// phpTypeNames returns the last segments of the names listed in an
+// extends, implements or trait use clause.
+func phpTypeNames(node *sitter.Node, content []byte) []string {
+	var names []string
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		if child.Type() == phpNodeName || child.Type() == phpNodeQualifiedName {
+			names = append(names, phpLastSegment(phpNodeText(child, content)))
+		}
+	}
+	return names
+}
+
+// phpAttributes returns the names of the attributes on a declaration:
+// "Route" for #[Route('/users')].
+func phpAttributes(node *sitter.Node, content []byte) []string {
+	list := node.ChildByFieldName("attributes")
+	if list == nil {
+		list = phpChildByType(node, phpNodeAttributeList)
+	}
+	if list == nil {
+		return nil
+	}
+	var names []string
+	stack := []*sitter.Node{list}
+	for len(stack) > 0 {
+		n := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if n.Type() == phpNodeAttribute {
+			if name := n.NamedChild(0); name != nil {
+				names = append(names, phpLastSegment(phpNodeText(name, content)))
+			}
+			continue
+		}
+		for i := int(n.NamedChildCount()) - 1; i >= 0; i-- {
+			stack = append(stack, n.NamedChild(i))
+		}
+	}
+	return names
+}
+
+// phpVariableName returns the name of a variable_name node without "$".
+func phpVariableName(node *sitter.Node, content []byte) string {
+	return strings.TrimPrefix(phpNodeText(node, content), "$")
+}
+
+// phpLastSegment returns the last segment of a namespaced name:
+// "User" for \App\Models\User.
+func phpLastSegment(name string) string {
+	return name[strings.LastIndexByte(name, '\\')+1:]
+}
+
+// phpMetadata returns sym.Metadata, allocating it if needed.
+func phpMetadata(sym *Symbol) *SymbolMetadata {
+	if sym.Metadata == nil {
+		sym.Metadata = &SymbolMetadata{}
+	}
+	return sym.Metadata
+}
+
+// phpLocation returns the location of node.
+func phpLocation(node *sitter.Node, filePath string) Location {
+	return Location{
+		FilePath:  filePath,
+		StartLine: int(node.StartPoint().Row) + 1,
+		EndLine:   int(node.EndPoint().Row) + 1,
+		StartCol:  int(node.StartPoint().Column),
+		EndCol:    int(node.EndPoint().Column),
+	}
+}
+
+// phpCollapseSpace replaces runs of whitespace with single spaces.
+func phpCollapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// phpChildByType returns the first direct child of node with the given
+// type, or nil.
+func phpChildByType(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// phpNodeText returns the source text of node.
+func phpNodeText(node *sitter.Node, content []byte) string {
+	return string(content[node.StartByte():node.EndByte()])
+}
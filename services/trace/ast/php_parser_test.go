@@ -0,0 +1,317 @@
+package ast
+
+import (
+	"context"
+	"testing"
+)
+
+const testPHPSource = `<?php
+
+declare(strict_types=1);
+
+namespace App\Services;
+
+use App\Models\User;
+use App\Contracts\{Repository, Cache as AppCache};
+use function App\Support\format_name;
+
+/**
+ * Writes audit entries.
+ */
+trait LogsActivity
+{
+    public function logActivity(string $msg): void
+    {
+        $this->writer()->write($msg);
+    }
+
+    protected function writer(): Writer
+    {
+        return new Writer();
+    }
+}
+
+interface Auditable extends Loggable, \JsonSerializable
+{
+    public function audit(): array;
+}
+
+abstract class BaseService
+{
+    public function boot(): void {}
+}
+
+#[Service]
+final class UserService extends BaseService implements Auditable
+{
+    use LogsActivity;
+
+    public const VERSION = '1.0';
+
+    private static ?int $count = 0;
+    protected string $name = '', $label;
+
+    public function __construct(private Repository $repo, ?AppCache $cache = null)
+    {
+        parent::boot();
+    }
+
+    public function audit(): array
+    {
+        $this->logActivity('audit');
+        $user = $this->repo->find(1);
+        $user?->touch();
+        return array_map(fn ($u) => format_name($u), [self::make(), Helper::wrap($user)]);
+    }
+
+    public static function make(): static
+    {
+        return new static();
+    }
+
+    private function secret(): ?User
+    {
+        return null;
+    }
+}
+
+enum Suit: string implements HasLabel
+{
+    case Hearts = 'H';
+    case Spades = 'S';
+
+    public function label(): string
+    {
+        return ucfirst($this->name);
+    }
+}
+
+function helper(int $a, int $b = 2): int
+{
+    return \strlen((string) $a);
+}
+
+const MAX_USERS = 100;
+`
+
+func parseTestPHP(t *testing.T) *ParseResult {
+	t.Helper()
+	result, err := NewPHPParser().Parse(context.Background(), []byte(testPHPSource), "src/Services/UserService.php")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected parse errors: %v", result.Errors)
+	}
+	return result
+}
+
+func TestPHPParser_LanguageAndExtensions(t *testing.T) {
+	parser := NewPHPParser()
+	if got := parser.Language(); got != "php" {
+		t.Errorf("Language() = %q, want %q", got, "php")
+	}
+	if exts := parser.Extensions(); len(exts) != 1 || exts[0] != ".php" {
+		t.Errorf("Extensions() = %v, want [.php]", exts)
+	}
+}
+
+func TestPHPParser_NamespaceAndUses(t *testing.T) {
+	result := parseTestPHP(t)
+
+	if result.Package != `App\Services` {
+		t.Errorf(`Package = %q, want App\Services`, result.Package)
+	}
+	want := []struct{ path, name, alias string }{
+		{`App\Models\User`, "User", ""},
+		{`App\Contracts\Repository`, "Repository", ""},
+		{`App\Contracts\Cache`, "Cache", "AppCache"},
+		{`App\Support\format_name`, "format_name", ""},
+	}
+	if len(result.Imports) != len(want) {
+		t.Fatalf("imports = %+v, want %d", result.Imports, len(want))
+	}
+	for i, w := range want {
+		imp := result.Imports[i]
+		if imp.Path != w.path || len(imp.Names) != 1 || imp.Names[0] != w.name || imp.Alias != w.alias {
+			t.Errorf("import %d = %+v, want %+v", i, imp, w)
+		}
+	}
+	if helper := kotlinTopLevel(t, result, "helper"); helper.Package != `App\Services` {
+		t.Errorf("helper package = %q", helper.Package)
+	}
+}
+
+func TestPHPParser_TraitAndInterface(t *testing.T) {
+	result := parseTestPHP(t)
+
+	trait := kotlinTopLevel(t, result, "LogsActivity")
+	if trait.Kind != SymbolKindClass || trait.Signature != "trait LogsActivity" || !trait.Metadata.IsAbstract {
+		t.Errorf("LogsActivity = %v %q %+v", trait.Kind, trait.Signature, trait.Metadata)
+	}
+	if trait.DocComment != "Writes audit entries." {
+		t.Errorf("DocComment = %q", trait.DocComment)
+	}
+	if len(trait.Metadata.Methods) != 2 || trait.Metadata.Methods[0].Name != "logActivity" {
+		t.Errorf("trait methods = %+v", trait.Metadata.Methods)
+	}
+
+	auditable := kotlinTopLevel(t, result, "Auditable")
+	if auditable.Kind != SymbolKindInterface || auditable.Metadata.Extends != "Loggable" {
+		t.Fatalf("Auditable = %v %+v", auditable.Kind, auditable.Metadata)
+	}
+	if impl := auditable.Metadata.Implements; len(impl) != 1 || impl[0] != "JsonSerializable" {
+		t.Errorf("Auditable parents = %v, want [JsonSerializable]", impl)
+	}
+	if len(auditable.Metadata.Methods) != 1 || auditable.Metadata.Methods[0].Returns != "array" {
+		t.Errorf("Auditable methods = %+v", auditable.Metadata.Methods)
+	}
+}
+
+func TestPHPParser_Class(t *testing.T) {
+	result := parseTestPHP(t)
+
+	if base := kotlinTopLevel(t, result, "BaseService"); !base.Metadata.IsAbstract {
+		t.Error("abstract class BaseService should be abstract")
+	}
+
+	svc := kotlinTopLevel(t, result, "UserService")
+	meta := svc.Metadata
+	if svc.Signature != "final class UserService extends BaseService implements Auditable" {
+		t.Errorf("Signature = %q", svc.Signature)
+	}
+	if meta.Extends != "BaseService" || len(meta.Implements) != 1 || meta.Implements[0] != "Auditable" {
+		t.Errorf("UserService extends %q implements %v", meta.Extends, meta.Implements)
+	}
+	if len(meta.Mixins) != 1 || meta.Mixins[0] != "LogsActivity" {
+		t.Errorf("Mixins = %v, want [LogsActivity]", meta.Mixins)
+	}
+	if len(meta.Decorators) != 1 || meta.Decorators[0] != "Service" {
+		t.Errorf("Decorators = %v, want [Service]", meta.Decorators)
+	}
+
+	if c := kotlinChild(t, svc, "VERSION"); c.Kind != SymbolKindConstant || c.Receiver != "UserService" {
+		t.Errorf("VERSION = %v receiver %q", c.Kind, c.Receiver)
+	}
+	if count := kotlinChild(t, svc, "count"); count.Exported || !count.Metadata.IsStatic || count.Metadata.ReturnType != "int" {
+		t.Errorf("count = exported %v %+v", count.Exported, count.Metadata)
+	}
+	if label := kotlinChild(t, svc, "label"); label.Kind != SymbolKindField || label.Signature != "protected string $label" {
+		t.Errorf("label = %v %q", label.Kind, label.Signature)
+	}
+	if repo := kotlinChild(t, svc, "repo"); repo.Kind != SymbolKindField || repo.Metadata.AccessModifier != "private" {
+		t.Errorf("promoted repo = %v %+v", repo.Kind, repo.Metadata)
+	}
+	for _, child := range svc.Children {
+		if child.Name == "cache" {
+			t.Error("plain constructor parameter cache should not be a field")
+		}
+	}
+
+	ctor := kotlinChild(t, svc, "__construct")
+	if ctor.Kind != SymbolKindMethod || ctor.Receiver != "UserService" || !ctor.Metadata.IsConstructor {
+		t.Errorf("__construct = %v receiver %q %+v", ctor.Kind, ctor.Receiver, ctor.Metadata)
+	}
+	if factory := kotlinChild(t, svc, "make"); !factory.Metadata.IsStatic {
+		t.Error("static function make should be static")
+	}
+	secret := kotlinChild(t, svc, "secret")
+	if secret.Exported || secret.Metadata.ReturnType != "User" {
+		t.Errorf("secret = exported %v return %q", secret.Exported, secret.Metadata.ReturnType)
+	}
+	if len(meta.Methods) != 2 {
+		t.Errorf("UserService methods = %+v, want audit and secret", meta.Methods)
+	}
+
+	suit := kotlinTopLevel(t, result, "Suit")
+	if suit.Kind != SymbolKindEnum || len(suit.Metadata.Implements) != 1 || suit.Metadata.Implements[0] != "HasLabel" {
+		t.Errorf("Suit = %v %+v", suit.Kind, suit.Metadata)
+	}
+	if hearts := kotlinChild(t, suit, "Hearts"); hearts.Kind != SymbolKindEnumMember {
+		t.Errorf("Hearts kind = %v", hearts.Kind)
+	}
+}
+
+func TestPHPParser_TopLevelDeclarations(t *testing.T) {
+	result := parseTestPHP(t)
+
+	helper := kotlinTopLevel(t, result, "helper")
+	if helper.Kind != SymbolKindFunction || helper.Signature != "function helper(int $a, int $b = 2): int" {
+		t.Errorf("helper = %v %q", helper.Kind, helper.Signature)
+	}
+	if maxUsers := kotlinTopLevel(t, result, "MAX_USERS"); maxUsers.Kind != SymbolKindConstant {
+		t.Errorf("MAX_USERS kind = %v, want constant", maxUsers.Kind)
+	}
+}
+
+func TestPHPParser_CallSites(t *testing.T) {
+	result := parseTestPHP(t)
+
+	svc := kotlinTopLevel(t, result, "UserService")
+	audit := kotlinChild(t, svc, "audit")
+	for _, want := range []struct{ receiver, target string }{
+		{"this", "logActivity"}, {"this.repo", "find"}, {"user", "touch"},
+		{"", "array_map"}, {"", "format_name"}, {"self", "make"}, {"Helper", "wrap"},
+	} {
+		if !hasCallSite(audit.Calls, want.receiver, want.target) {
+			t.Errorf("audit calls %+v missing %s.%s", audit.Calls, want.receiver, want.target)
+		}
+	}
+	if ctor := kotlinChild(t, svc, "__construct"); !hasCallSite(ctor.Calls, "super", "boot") {
+		t.Errorf("__construct calls = %+v, want super.boot", ctor.Calls)
+	}
+
+	trait := kotlinTopLevel(t, result, "LogsActivity")
+	if logActivity := kotlinChild(t, trait, "logActivity"); !hasCallSite(logActivity.Calls, "this.writer()", "write") {
+		t.Errorf("logActivity calls = %+v", logActivity.Calls)
+	}
+	if writer := kotlinChild(t, trait, "writer"); !hasCallSite(writer.Calls, "", "Writer") {
+		t.Errorf("writer calls = %+v, want new Writer", writer.Calls)
+	}
+	if helper := kotlinTopLevel(t, result, "helper"); !hasCallSite(helper.Calls, "", "strlen") {
+		t.Errorf("helper calls = %+v, want strlen", helper.Calls)
+	}
+}
+
+func TestPHPParser_BracedNamespaces(t *testing.T) {
+	content := []byte(`<?php
+namespace App\One {
+    class A {}
+}
+namespace App\Two {
+    function b() {}
+}
+`)
+	result, err := NewPHPParser().Parse(context.Background(), content, "multi.php")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if a := kotlinTopLevel(t, result, "A"); a.Package != `App\One` {
+		t.Errorf("A package = %q", a.Package)
+	}
+	if b := kotlinTopLevel(t, result, "b"); b.Package != `App\Two` {
+		t.Errorf("b package = %q", b.Package)
+	}
+}
+
+func TestPHPParser_SyntaxErrorReported(t *testing.T) {
+	content := []byte(`<?php
+class Good {}
+class {
+`)
+	result, err := NewPHPParser().Parse(context.Background(), content, "Bad.php")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) == 0 {
+		t.Error("expected a syntax error to be reported")
+	}
+}
+
+func TestPHPParser_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewPHPParser().Parse(ctx, []byte(testPHPSource), "UserService.php"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+// PHP Tree-sitter Node Types
+//
+// This file documents the tree-sitter node types used by PHPParser for symbol extraction.
+//
+// Reference: https://github.com/tree-sitter/tree-sitter-php
+
+// Node type constants for PHP AST traversal.
+const (
+	// Top-level nodes
+	phpNodeProgram           = "program"
+	phpNodeNamespaceDef      = "namespace_definition"
+	phpNodeNamespaceName     = "namespace_name"
+	phpNodeNamespaceUseDecl  = "namespace_use_declaration"
+	phpNodeNamespaceUse      = "namespace_use_clause"
+	phpNodeNamespaceUseGroup = "namespace_use_group"
+	phpNodeUseGroupClause    = "namespace_use_group_clause"
+	phpNodeAliasingClause    = "namespace_aliasing_clause"
+	phpNodeCompoundStatement = "compound_statement"
+
+	// Declaration nodes
+	phpNodeClassDecl       = "class_declaration"
+	phpNodeInterfaceDecl   = "interface_declaration"
+	phpNodeTraitDecl       = "trait_declaration"
+	phpNodeEnumDecl        = "enum_declaration"
+	phpNodeEnumCase        = "enum_case"
+	phpNodeFunctionDef     = "function_definition"
+	phpNodeMethodDecl      = "method_declaration"
+	phpNodePropertyDecl    = "property_declaration"
+	phpNodePropertyElement = "property_element"
+	phpNodeConstDecl       = "const_declaration"
+	phpNodeConstElement    = "const_element"
+	phpNodeUseDecl         = "use_declaration"
+	phpNodeBaseClause      = "base_clause"
+	phpNodeInterfaceClause = "class_interface_clause"
+	phpNodePromotedParam   = "property_promotion_parameter"
+
+	// Modifiers
+	phpNodeVisibilityModifier = "visibility_modifier"
+	phpNodeStaticModifier     = "static_modifier"
+	phpNodeAbstractModifier   = "abstract_modifier"
+	phpNodeFinalModifier      = "final_modifier"
+	phpNodeReadonlyModifier   = "readonly_modifier"
+	phpNodeAttributeList      = "attribute_list"
+	phpNodeAttribute          = "attribute"
+
+	// Names and types
+	phpNodeName          = "name"
+	phpNodeQualifiedName = "qualified_name"
+	phpNodeVariableName  = "variable_name"
+	phpNodeRelativeScope = "relative_scope"
+	phpNodeOptionalType  = "optional_type"
+
+	// Expressions
+	phpNodeFunctionCall       = "function_call_expression"
+	phpNodeMemberCall         = "member_call_expression"
+	phpNodeNullsafeMemberCall = "nullsafe_member_call_expression"
+	phpNodeScopedCall         = "scoped_call_expression"
+	phpNodeObjectCreation     = "object_creation_expression"
+
+	// Errors
+	phpNodeERROR = "ERROR"
+)
+
+// PHPNodeTypes maps symbol kinds to the tree-sitter node types that produce them.
+var PHPNodeTypes = map[SymbolKind][]string{
+	SymbolKindClass:      {phpNodeClassDecl, phpNodeTraitDecl},
+	SymbolKindInterface:  {phpNodeInterfaceDecl},
+	SymbolKindEnum:       {phpNodeEnumDecl},
+	SymbolKindEnumMember: {phpNodeEnumCase},
+	SymbolKindFunction:   {phpNodeFunctionDef},
+	SymbolKindMethod:     {phpNodeMethodDecl},
+	SymbolKindField:      {phpNodePropertyDecl, phpNodePromotedParam},
+	SymbolKindConstant:   {phpNodeConstDecl},
+}
+
+// PHP AST Structure Reference
+//
+// program
+// ├── php_tag (<?php)
+// ├── namespace_definition
+// │   ├── name: namespace_name (App\Services)
+// │   └── body: compound_statement          [braced form only]
+// ├── namespace_use_declaration
+// │   ├── "function" | "const"              [optional]
+// │   ├── namespace_use_clause
+// │   │   ├── qualified_name (App\Models\User)
+// │   │   └── namespace_aliasing_clause (as U)   [optional]
+// │   └── namespace_name "\" namespace_use_group   [group form]
+// │       └── namespace_use_group_clause (Repo, Cache as C)
+// ├── class_declaration
+// │   ├── attributes: attribute_list (#[Attr])
+// │   ├── abstract_modifier | final_modifier | readonly_modifier
+// │   ├── name: name
+// │   ├── base_clause ("extends" name)
+// │   ├── class_interface_clause ("implements" name, qualified_name, ...)
+// │   └── body: declaration_list
+// │       ├── use_declaration ("use" name, ...; trait use)
+// │       ├── const_declaration
+// │       │   └── const_element (name = value)
+// │       ├── property_declaration
+// │       │   ├── visibility_modifier, static_modifier, type
+// │       │   └── property_element
+// │       │       └── variable_name ($ name)
+// │       └── method_declaration
+// │           ├── visibility_modifier, static_modifier, abstract_modifier
+// │           ├── name: name
+// │           ├── parameters: formal_parameters
+// │           │   └── property_promotion_parameter   [constructor only]
+// │           ├── return_type: <type>                 [optional]
+// │           └── body: compound_statement            [absent if abstract]
+// ├── interface_declaration
+// │   ├── name: name
+// │   ├── base_clause ("extends" name, name, ...)
+// │   └── body: declaration_list
+// ├── trait_declaration
+// │   ├── name: name
+// │   └── body: declaration_list
+// ├── enum_declaration
+// │   ├── name: name
+// │   ├── class_interface_clause
+// │   └── body: enum_declaration_list
+// │       ├── enum_case (case Hearts = 'H';)
+// │       └── method_declaration
+// ├── function_definition
+// │   ├── name: name
+// │   ├── parameters: formal_parameters
+// │   ├── return_type: <type>
+// │   └── body: compound_statement
+// └── const_declaration
+//
+// Calls:
+//
+// function_call_expression                 format($u), \strlen($s)
+// ├── function: name | qualified_name
+// └── arguments
+// member_call_expression                   $this->log(), $this->repo->find()
+// │   (nullsafe_member_call_expression     $u?->save())
+// ├── object: variable_name | member_access_expression | ...
+// ├── name: name
+// └── arguments
+// scoped_call_expression                   Str::upper(), parent::__construct()
+// ├── scope: name | qualified_name | relative_scope (self, static, parent)
+// ├── name: name
+// └── arguments
+// object_creation_expression               new Repo($x)
+// ├── name | qualified_name
+// └── arguments
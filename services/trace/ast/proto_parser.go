@@ -0,0 +1,575 @@
+package ast
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/protobuf"
+)
+
+// ProtoParser extracts symbols from Protocol Buffers definition files.
+//
+// Description:
+//
+//	ProtoParser uses tree-sitter to parse .proto files and extract messages,
+//	enums, services and rpcs, mapped onto the existing symbol kinds so the
+//	graph queries work on them unchanged:
+//
+//	  message -> SymbolKindStruct, with fields (SymbolKindField) and nested
+//	             messages and enums as children
+//	  enum    -> SymbolKindEnum, with values (SymbolKindEnumMember) as children
+//	  service -> SymbolKindInterface, with rpcs as children
+//	  rpc     -> SymbolKindMethod, with Metadata.ProtoRequestType and
+//	             Metadata.ProtoResponseType
+//
+//	Options on each definition are recorded in Metadata.ProtoOptions.
+//	Nested definitions record their enclosing message in
+//	Metadata.ParentName. Imports become ParseResult.Imports and the package
+//	declaration ParseResult.Package.
+//
+// Thread Safety:
+//
+//	ProtoParser is safe for concurrent use. Multiple goroutines can call Parse
+//	simultaneously. Each Parse call creates its own tree-sitter parser instance.
+//
+// Example:
+//
+//	parser := NewProtoParser()
+//	result, err := parser.Parse(ctx, content, "api/users/v1/users.proto")
+//	if err != nil {
+//	    return fmt.Errorf("parse: %w", err)
+//	}
+//	for _, sym := range result.Symbols {
+//	    fmt.Printf("%s: %s\n", sym.Kind, sym.Name)
+//	}
+type ProtoParser struct {
+	options ProtoParserOptions
+}
+
+// ProtoParserOptions configures ProtoParser behavior.
+type ProtoParserOptions struct {
+	// MaxFileSize is the maximum file size in bytes to parse.
+	// Files larger than this return ErrFileTooLarge.
+	// Default: 10MB
+	MaxFileSize int
+}
+
+// DefaultProtoParserOptions returns the default options.
+func DefaultProtoParserOptions() ProtoParserOptions {
+	return ProtoParserOptions{
+		MaxFileSize: 10 * 1024 * 1024, // 10MB
+	}
+}
+
+// ProtoParserOption is a functional option for configuring ProtoParser.
+type ProtoParserOption func(*ProtoParserOptions)
+
+// WithProtoMaxFileSize sets the maximum file size for parsing.
+func WithProtoMaxFileSize(size int) ProtoParserOption {
+	return func(o *ProtoParserOptions) {
+		o.MaxFileSize = size
+	}
+}
+
+// NewProtoParser creates a new ProtoParser with the given options.
+//
+// Description:
+//
+//	Creates a parser configured for .proto files. The parser can be
+//	reused for multiple files and is safe for concurrent use.
+//
+// Example:
+//
+//	// Default options
+//	parser := NewProtoParser()
+//
+//	// With custom options
+//	parser := NewProtoParser(
+//	    WithProtoMaxFileSize(5 * 1024 * 1024),
+//	)
+func NewProtoParser(opts ...ProtoParserOption) *ProtoParser {
+	options := DefaultProtoParserOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return &ProtoParser{
+		options: options,
+	}
+}
+
+// Language returns the language name for this parser.
+func (p *ProtoParser) Language() string {
+	return "proto"
+}
+
+// Extensions returns the file extensions this parser handles.
+func (p *ProtoParser) Extensions() []string {
+	return []string{".proto"}
+}
+
+// Parse extracts symbols from protobuf source code.
+//
+// Description:
+//
+//	Parses the provided .proto content using tree-sitter and extracts all
+//	messages, enums, services and rpcs with their options.
+//
+// Inputs:
+//
+//	ctx      - Context for cancellation. Checked before/after parsing.
+//	content  - Raw .proto source bytes. Must be valid UTF-8.
+//	filePath - Path to the file (relative to project root, for ID generation).
+//
+// Outputs:
+//
+//	*ParseResult - Extracted symbols and metadata. Never nil on success.
+//	error        - Non-nil only for complete failures (invalid UTF-8, too large).
+//
+// Thread Safety:
+//
+//	This method is safe for concurrent use.
+func (p *ProtoParser) Parse(ctx context.Context, content []byte, filePath string) (*ParseResult, error) {
+	// Check context before starting
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("proto parse canceled before start: %w", err)
+	}
+
+	// Validate file size
+	if len(content) > p.options.MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	// Validate UTF-8
+	if !utf8.Valid(content) {
+		return nil, ErrInvalidContent
+	}
+
+	// Compute hash before parsing
+	hash := sha256.Sum256(content)
+	hashStr := hex.EncodeToString(hash[:])
+
+	// Create result
+	result := &ParseResult{
+		FilePath:      filePath,
+		Language:      "proto",
+		Hash:          hashStr,
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Symbols:       make([]*Symbol, 0),
+		Imports:       make([]Import, 0),
+		Errors:        make([]string, 0),
+	}
+
+	// Parse with tree-sitter
+	parser := sitter.NewParser()
+	parser.SetLanguage(protobuf.GetLanguage())
+
+	tree, err := parser.ParseCtx(ctx, nil, content)
+	if err != nil {
+		return nil, fmt.Errorf("tree-sitter parse failed: %w", err)
+	}
+	defer tree.Close()
+
+	// Check context after parsing
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("proto parse canceled after tree-sitter: %w", err)
+	}
+
+	// Extract symbols from AST
+	rootNode := tree.RootNode()
+	p.extractSymbols(ctx, rootNode, content, filePath, result)
+
+	// Validate result
+	if err := result.Validate(); err != nil {
+		result.Errors = append(result.Errors, fmt.Sprintf("validation error: %v", err))
+	}
+
+	return result, nil
+}
+
+// extractSymbols extracts top-level definitions from the source_file node.
+func (p *ProtoParser) extractSymbols(ctx context.Context, root *sitter.Node, content []byte, filePath string, result *ParseResult) {
+	if root == nil || root.Type() != protoNodeSourceFile {
+		return
+	}
+
+	// The package applies to every definition, wherever it is declared.
+	for i := 0; i < int(root.ChildCount()); i++ {
+		child := root.Child(i)
+		if child.Type() == protoNodePackage {
+			if ident := protoChildByType(child, protoNodeFullIdent); ident != nil {
+				result.Package = protoNodeText(ident, content)
+			}
+		}
+	}
+
+	for i := 0; i < int(root.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			return
+		}
+
+		child := root.Child(i)
+		switch child.Type() {
+		case protoNodeImport:
+			p.extractImport(child, content, filePath, result)
+		case protoNodeMessage:
+			if sym := p.extractMessage(ctx, child, content, filePath, result.Package, ""); sym != nil {
+				result.Symbols = append(result.Symbols, sym)
+			}
+		case protoNodeEnum:
+			if sym := p.extractEnum(child, content, filePath, result.Package, ""); sym != nil {
+				result.Symbols = append(result.Symbols, sym)
+			}
+		case protoNodeService:
+			if sym := p.extractService(child, content, filePath, result.Package); sym != nil {
+				result.Symbols = append(result.Symbols, sym)
+			}
+		case protoNodeERROR:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s:%d: syntax error: %s",
+				filePath, int(child.StartPoint().Row)+1, sqlSnippet(content[child.StartByte():child.EndByte()])))
+		}
+	}
+}
+
+// extractImport records an import statement.
+func (p *ProtoParser) extractImport(node *sitter.Node, content []byte, filePath string, result *ParseResult) {
+	str := protoChildByType(node, protoNodeString)
+	if str == nil {
+		return
+	}
+	path := strings.Trim(protoNodeText(str, content), `"'`)
+	if path == "" {
+		return
+	}
+	result.Imports = append(result.Imports, Import{
+		Path:     path,
+		Location: protoLocation(node, filePath),
+	})
+}
+
+// extractMessage builds a message symbol with its fields and nested
+// definitions. parent is the enclosing message's qualified name, or "".
+func (p *ProtoParser) extractMessage(ctx context.Context, node *sitter.Node, content []byte, filePath, pkg, parent string) *Symbol {
+	name := protoDefinitionName(node, protoNodeMessageName, content)
+	if name == "" {
+		return nil
+	}
+	qualified := protoQualify(parent, name)
+
+	sym := p.newSymbol(node, filePath, qualified, name, SymbolKindStruct, "message "+name, pkg)
+	sym.DocComment = protoDocComment(node, content)
+	if parent != "" {
+		protoMetadata(sym).ParentName = parent
+	}
+
+	body := protoChildByType(node, protoNodeMessageBody)
+	if body == nil {
+		return sym
+	}
+	for i := 0; i < int(body.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			break
+		}
+		child := body.Child(i)
+		switch child.Type() {
+		case protoNodeField, protoNodeMapField:
+			if field := p.extractField(child, content, filePath, pkg, qualified); field != nil {
+				sym.Children = append(sym.Children, field)
+			}
+		case protoNodeOneof:
+			for j := 0; j < int(child.ChildCount()); j++ {
+				if oneofField := child.Child(j); oneofField.Type() == protoNodeOneofField {
+					if field := p.extractField(oneofField, content, filePath, pkg, qualified); field != nil {
+						sym.Children = append(sym.Children, field)
+					}
+				}
+			}
+		case protoNodeMessage:
+			if nested := p.extractMessage(ctx, child, content, filePath, pkg, qualified); nested != nil {
+				sym.Children = append(sym.Children, nested)
+			}
+		case protoNodeEnum:
+			if nested := p.extractEnum(child, content, filePath, pkg, qualified); nested != nil {
+				sym.Children = append(sym.Children, nested)
+			}
+		case protoNodeOption:
+			protoMetadata(sym).ProtoOptions = append(protoMetadata(sym).ProtoOptions, protoOptionText(child, content))
+		}
+	}
+	return sym
+}
+
+// extractField builds a message field symbol. The signature is the field
+// declaration without options or the trailing semicolon.
+func (p *ProtoParser) extractField(node *sitter.Node, content []byte, filePath, pkg, message string) *Symbol {
+	name := ""
+	var options []string
+	signature := strings.Builder{}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case protoNodeIdentifier:
+			if name == "" {
+				name = protoNodeText(child, content)
+			}
+		case protoNodeFieldOptions:
+			for j := 0; j < int(child.ChildCount()); j++ {
+				if opt := child.Child(j); opt.Type() == protoNodeFieldOption {
+					options = append(options, protoCollapseSpace(protoNodeText(opt, content)))
+				}
+			}
+			continue
+		case "[", "]", ";":
+			continue
+		}
+		if signature.Len() > 0 && child.Type() != "," && child.Type() != ">" && child.Type() != "<" {
+			last := signature.String()[signature.Len()-1]
+			if last != '<' {
+				signature.WriteByte(' ')
+			}
+		}
+		signature.WriteString(protoNodeText(child, content))
+	}
+	if name == "" {
+		return nil
+	}
+
+	sym := p.newSymbol(node, filePath, protoQualify(message, name), name, SymbolKindField, signature.String(), pkg)
+	sym.DocComment = protoDocComment(node, content)
+	meta := protoMetadata(sym)
+	meta.ParentName = message
+	meta.ProtoOptions = options
+	return sym
+}
+
+// extractEnum builds an enum symbol with its values as children.
+func (p *ProtoParser) extractEnum(node *sitter.Node, content []byte, filePath, pkg, parent string) *Symbol {
+	name := protoDefinitionName(node, protoNodeEnumName, content)
+	if name == "" {
+		return nil
+	}
+	qualified := protoQualify(parent, name)
+
+	sym := p.newSymbol(node, filePath, qualified, name, SymbolKindEnum, "enum "+name, pkg)
+	sym.DocComment = protoDocComment(node, content)
+	if parent != "" {
+		protoMetadata(sym).ParentName = parent
+	}
+
+	body := protoChildByType(node, protoNodeEnumBody)
+	if body == nil {
+		return sym
+	}
+	for i := 0; i < int(body.ChildCount()); i++ {
+		child := body.Child(i)
+		switch child.Type() {
+		case protoNodeEnumField:
+			ident := protoChildByType(child, protoNodeIdentifier)
+			if ident == nil {
+				continue
+			}
+			valueName := protoNodeText(ident, content)
+			value := p.newSymbol(child, filePath, protoQualify(qualified, valueName), valueName, SymbolKindEnumMember,
+				strings.TrimSuffix(protoCollapseSpace(protoNodeText(child, content)), ";"), pkg)
+			protoMetadata(value).ParentName = qualified
+			sym.Children = append(sym.Children, value)
+		case protoNodeOption:
+			protoMetadata(sym).ProtoOptions = append(protoMetadata(sym).ProtoOptions, protoOptionText(child, content))
+		}
+	}
+	return sym
+}
+
+// extractService builds a service symbol with its rpcs as children.
+// Metadata.Methods lists the rpc names, as for Go interfaces.
+func (p *ProtoParser) extractService(node *sitter.Node, content []byte, filePath, pkg string) *Symbol {
+	name := protoDefinitionName(node, protoNodeServiceName, content)
+	if name == "" {
+		return nil
+	}
+
+	sym := p.newSymbol(node, filePath, name, name, SymbolKindInterface, "service "+name, pkg)
+	sym.DocComment = protoDocComment(node, content)
+	meta := protoMetadata(sym)
+
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case protoNodeRPC:
+			rpc := p.extractRPC(child, content, filePath, pkg, name)
+			if rpc == nil {
+				continue
+			}
+			sym.Children = append(sym.Children, rpc)
+			meta.Methods = append(meta.Methods, MethodSignature{
+				Name:         rpc.Name,
+				Params:       rpc.Metadata.ProtoRequestType,
+				Returns:      rpc.Metadata.ProtoResponseType,
+				ParamCount:   1,
+				ReturnCount:  1,
+				ReceiverType: name,
+			})
+		case protoNodeOption:
+			meta.ProtoOptions = append(meta.ProtoOptions, protoOptionText(child, content))
+		}
+	}
+	return sym
+}
+
+// extractRPC builds an rpc symbol. The signature keeps stream keywords:
+// "rpc Watch(stream WatchRequest) returns (stream Event)".
+func (p *ProtoParser) extractRPC(node *sitter.Node, content []byte, filePath, pkg, service string) *Symbol {
+	name := protoDefinitionName(node, protoNodeRPCName, content)
+	if name == "" {
+		return nil
+	}
+
+	var types [2]string
+	var streams [2]bool
+	typeIdx := 0
+	var options []string
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case protoNodeStream:
+			if typeIdx < 2 {
+				streams[typeIdx] = true
+			}
+		case protoNodeMessageOrEnumType:
+			if typeIdx < 2 {
+				types[typeIdx] = protoNodeText(child, content)
+				typeIdx++
+			}
+		case protoNodeOption:
+			options = append(options, protoOptionText(child, content))
+		}
+	}
+
+	streamPrefix := func(stream bool) string {
+		if stream {
+			return "stream "
+		}
+		return ""
+	}
+	signature := fmt.Sprintf("rpc %s(%s%s) returns (%s%s)",
+		name, streamPrefix(streams[0]), types[0], streamPrefix(streams[1]), types[1])
+
+	sym := p.newSymbol(node, filePath, protoQualify(service, name), name, SymbolKindMethod, signature, pkg)
+	sym.DocComment = protoDocComment(node, content)
+	sym.Receiver = service
+	meta := protoMetadata(sym)
+	meta.ParentName = service
+	meta.ProtoRequestType = types[0]
+	meta.ProtoResponseType = types[1]
+	meta.ProtoOptions = options
+	return sym
+}
+
+// newSymbol creates a proto symbol. idName is the dotted name used for the
+// ID so nested definitions with the same name do not collide.
+func (p *ProtoParser) newSymbol(node *sitter.Node, filePath, idName, name string, kind SymbolKind, signature, pkg string) *Symbol {
+	return &Symbol{
+		ID:            GenerateID(filePath, int(node.StartPoint().Row)+1, idName),
+		Name:          name,
+		Kind:          kind,
+		FilePath:      filePath,
+		StartLine:     int(node.StartPoint().Row) + 1,
+		EndLine:       int(node.EndPoint().Row) + 1,
+		StartCol:      int(node.StartPoint().Column),
+		EndCol:        int(node.EndPoint().Column),
+		Signature:     signature,
+		Package:       pkg,
+		Language:      "proto",
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Exported:      true,
+	}
+}
+
+// protoMetadata returns sym.Metadata, allocating it if needed.
+func protoMetadata(sym *Symbol) *SymbolMetadata {
+	if sym.Metadata == nil {
+		sym.Metadata = &SymbolMetadata{}
+	}
+	return sym.Metadata
+}
+
+// protoDefinitionName returns the identifier inside the nameType child of
+// a message, enum, service or rpc node.
+func protoDefinitionName(node *sitter.Node, nameType string, content []byte) string {
+	nameNode := protoChildByType(node, nameType)
+	if nameNode == nil {
+		return ""
+	}
+	return protoNodeText(nameNode, content)
+}
+
+// protoQualify joins a parent and child name with a dot.
+func protoQualify(parent, name string) string {
+	if parent == "" {
+		return name
+	}
+	return parent + "." + name
+}
+
+// protoOptionText returns an option statement without the "option"
+// keyword and semicolon: "(google.api.http) = { get: \"/v1/users\" }".
+func protoOptionText(node *sitter.Node, content []byte) string {
+	text := protoCollapseSpace(protoNodeText(node, content))
+	text = strings.TrimPrefix(text, "option ")
+	return strings.TrimSuffix(text, ";")
+}
+
+// protoDocComment returns the comment lines directly above node, joined
+// with newlines and stripped of comment markers.
+func protoDocComment(node *sitter.Node, content []byte) string {
+	var lines []string
+	expectedRow := int(node.StartPoint().Row)
+	for prev := node.PrevSibling(); prev != nil && prev.Type() == protoNodeComment; prev = prev.PrevSibling() {
+		if int(prev.EndPoint().Row) != expectedRow-1 {
+			break
+		}
+		text := protoNodeText(prev, content)
+		text = strings.TrimPrefix(text, "//")
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+		lines = append([]string{strings.TrimSpace(text)}, lines...)
+		expectedRow = int(prev.StartPoint().Row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// protoLocation returns the location of node.
+func protoLocation(node *sitter.Node, filePath string) Location {
+	return Location{
+		FilePath:  filePath,
+		StartLine: int(node.StartPoint().Row) + 1,
+		EndLine:   int(node.EndPoint().Row) + 1,
+		StartCol:  int(node.StartPoint().Column),
+		EndCol:    int(node.EndPoint().Column),
+	}
+}
+
+// protoCollapseSpace replaces runs of whitespace with single spaces.
+func protoCollapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// protoChildByType returns the first direct child of node with the given
+// type, or nil.
+func protoChildByType(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// protoNodeText returns the source text of node.
+func protoNodeText(node *sitter.Node, content []byte) string {
+	return string(content[node.StartByte():node.EndByte()])
+}
@@ -0,0 +1,249 @@
+package ast
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const testProtoSource = `syntax = "proto3";
+
+package acme.users.v1;
+
+import "google/api/annotations.proto";
+import "acme/common/v1/page.proto";
+
+option go_package = "github.com/acme/users/gen/usersv1";
+
+// User is an account holder.
+message User {
+  string id = 1;
+  repeated string tags = 2 [deprecated = true];
+  map<string, int64> counters = 3;
+  oneof contact {
+    string email = 4;
+    string phone = 5;
+  }
+  Role role = 6;
+
+  message Address {
+    string city = 1;
+  }
+
+  option deprecated = true;
+}
+
+enum Role {
+  ROLE_UNSPECIFIED = 0;
+  ROLE_ADMIN = 1;
+}
+
+message UpdateUserRequest {
+  User user = 1;
+}
+
+// UserService manages users.
+service UserService {
+  option (acme.auth.required) = true;
+
+  // UpdateUser replaces a user.
+  rpc UpdateUser(UpdateUserRequest) returns (User) {
+    option (google.api.http) = {
+      put: "/v1/users/{user.id}"
+      body: "user"
+    };
+  }
+
+  rpc WatchUsers(stream .acme.users.v1.UpdateUserRequest) returns (stream User);
+}
+`
+
+func parseTestProto(t *testing.T) *ParseResult {
+	t.Helper()
+	result, err := NewProtoParser().Parse(context.Background(), []byte(testProtoSource), "api/users/v1/users.proto")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected parse errors: %v", result.Errors)
+	}
+	return result
+}
+
+// protoTopLevel returns the top-level symbol with the given name.
+func protoTopLevel(t *testing.T, result *ParseResult, name string) *Symbol {
+	t.Helper()
+	for _, sym := range result.Symbols {
+		if sym.Name == name {
+			return sym
+		}
+	}
+	t.Fatalf("top-level symbol %q not found", name)
+	return nil
+}
+
+// protoChild returns the child of sym with the given name.
+func protoChild(t *testing.T, sym *Symbol, name string) *Symbol {
+	t.Helper()
+	for _, child := range sym.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	t.Fatalf("%s has no child %q", sym.Name, name)
+	return nil
+}
+
+func TestProtoParser_LanguageAndExtensions(t *testing.T) {
+	parser := NewProtoParser()
+	if got := parser.Language(); got != "proto" {
+		t.Errorf("Language() = %q, want %q", got, "proto")
+	}
+	if exts := parser.Extensions(); len(exts) != 1 || exts[0] != ".proto" {
+		t.Errorf("Extensions() = %v, want [.proto]", exts)
+	}
+}
+
+func TestProtoParser_PackageAndImports(t *testing.T) {
+	result := parseTestProto(t)
+
+	if result.Package != "acme.users.v1" {
+		t.Errorf("Package = %q, want acme.users.v1", result.Package)
+	}
+	if len(result.Imports) != 2 {
+		t.Fatalf("expected 2 imports, got %d", len(result.Imports))
+	}
+	if result.Imports[0].Path != "google/api/annotations.proto" || result.Imports[1].Path != "acme/common/v1/page.proto" {
+		t.Errorf("imports = %+v", result.Imports)
+	}
+}
+
+func TestProtoParser_Messages(t *testing.T) {
+	result := parseTestProto(t)
+
+	user := protoTopLevel(t, result, "User")
+	if user.Kind != SymbolKindStruct {
+		t.Errorf("User kind = %s, want struct", user.Kind)
+	}
+	if user.Signature != "message User" {
+		t.Errorf("User signature = %q", user.Signature)
+	}
+	if user.DocComment != "User is an account holder." {
+		t.Errorf("User doc comment = %q", user.DocComment)
+	}
+	if user.Package != "acme.users.v1" {
+		t.Errorf("User package = %q", user.Package)
+	}
+	if user.Metadata == nil || len(user.Metadata.ProtoOptions) != 1 || user.Metadata.ProtoOptions[0] != "deprecated = true" {
+		t.Errorf("User options = %+v", user.Metadata)
+	}
+
+	for _, name := range []string{"id", "tags", "counters", "email", "phone", "role"} {
+		field := protoChild(t, user, name)
+		if field.Kind != SymbolKindField {
+			t.Errorf("%s kind = %s, want field", name, field.Kind)
+		}
+		if field.Metadata == nil || field.Metadata.ParentName != "User" {
+			t.Errorf("%s ParentName = %+v, want User", name, field.Metadata)
+		}
+	}
+
+	tags := protoChild(t, user, "tags")
+	if tags.Signature != "repeated string tags = 2" {
+		t.Errorf("tags signature = %q", tags.Signature)
+	}
+	if len(tags.Metadata.ProtoOptions) != 1 || tags.Metadata.ProtoOptions[0] != "deprecated = true" {
+		t.Errorf("tags options = %v", tags.Metadata.ProtoOptions)
+	}
+	if got := protoChild(t, user, "counters").Signature; got != "map<string, int64> counters = 3" {
+		t.Errorf("counters signature = %q", got)
+	}
+
+	address := protoChild(t, user, "Address")
+	if address.Kind != SymbolKindStruct || address.Metadata.ParentName != "User" {
+		t.Errorf("nested Address = kind %s, metadata %+v", address.Kind, address.Metadata)
+	}
+	city := protoChild(t, address, "city")
+	if city.Metadata.ParentName != "User.Address" {
+		t.Errorf("city ParentName = %q, want User.Address", city.Metadata.ParentName)
+	}
+}
+
+func TestProtoParser_Enums(t *testing.T) {
+	result := parseTestProto(t)
+
+	role := protoTopLevel(t, result, "Role")
+	if role.Kind != SymbolKindEnum {
+		t.Errorf("Role kind = %s, want enum", role.Kind)
+	}
+	admin := protoChild(t, role, "ROLE_ADMIN")
+	if admin.Kind != SymbolKindEnumMember || admin.Signature != "ROLE_ADMIN = 1" {
+		t.Errorf("ROLE_ADMIN = kind %s, signature %q", admin.Kind, admin.Signature)
+	}
+}
+
+func TestProtoParser_ServicesAndRPCs(t *testing.T) {
+	result := parseTestProto(t)
+
+	svc := protoTopLevel(t, result, "UserService")
+	if svc.Kind != SymbolKindInterface {
+		t.Errorf("UserService kind = %s, want interface", svc.Kind)
+	}
+	if svc.DocComment != "UserService manages users." {
+		t.Errorf("UserService doc comment = %q", svc.DocComment)
+	}
+	if len(svc.Metadata.ProtoOptions) != 1 || svc.Metadata.ProtoOptions[0] != "(acme.auth.required) = true" {
+		t.Errorf("UserService options = %v", svc.Metadata.ProtoOptions)
+	}
+	if len(svc.Metadata.Methods) != 2 || svc.Metadata.Methods[0].Name != "UpdateUser" {
+		t.Errorf("UserService methods = %+v", svc.Metadata.Methods)
+	}
+
+	update := protoChild(t, svc, "UpdateUser")
+	if update.Kind != SymbolKindMethod || update.Receiver != "UserService" {
+		t.Errorf("UpdateUser = kind %s, receiver %q", update.Kind, update.Receiver)
+	}
+	if update.Signature != "rpc UpdateUser(UpdateUserRequest) returns (User)" {
+		t.Errorf("UpdateUser signature = %q", update.Signature)
+	}
+	if update.DocComment != "UpdateUser replaces a user." {
+		t.Errorf("UpdateUser doc comment = %q", update.DocComment)
+	}
+	meta := update.Metadata
+	if meta.ProtoRequestType != "UpdateUserRequest" || meta.ProtoResponseType != "User" {
+		t.Errorf("UpdateUser types = %q -> %q", meta.ProtoRequestType, meta.ProtoResponseType)
+	}
+	if len(meta.ProtoOptions) != 1 || !strings.HasPrefix(meta.ProtoOptions[0], "(google.api.http) = { put: \"/v1/users/{user.id}\"") {
+		t.Errorf("UpdateUser options = %v", meta.ProtoOptions)
+	}
+
+	watch := protoChild(t, svc, "WatchUsers")
+	if watch.Signature != "rpc WatchUsers(stream .acme.users.v1.UpdateUserRequest) returns (stream User)" {
+		t.Errorf("WatchUsers signature = %q", watch.Signature)
+	}
+	if watch.Metadata.ProtoRequestType != ".acme.users.v1.UpdateUserRequest" {
+		t.Errorf("WatchUsers request type = %q", watch.Metadata.ProtoRequestType)
+	}
+}
+
+func TestProtoParser_SyntaxErrorReported(t *testing.T) {
+	content := []byte(`syntax = "proto3";
+message Good { string id = 1; }
+message { oops
+`)
+	result, err := NewProtoParser().Parse(context.Background(), content, "bad.proto")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) == 0 {
+		t.Error("expected a syntax error to be reported")
+	}
+}
+
+func TestProtoParser_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewProtoParser().Parse(ctx, []byte(testProtoSource), "users.proto"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
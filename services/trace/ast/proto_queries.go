@@ -0,0 +1,103 @@
+package ast
+
+// Protocol Buffers Tree-sitter Node Types
+//
+// This file documents the tree-sitter node types used by ProtoParser for symbol extraction.
+// The parser uses direct node traversal rather than tree-sitter's query language for
+// more precise control over symbol extraction.
+//
+// Reference: https://github.com/mitchellh/tree-sitter-proto
+
+// Node type constants for protobuf AST traversal.
+const (
+	// Top-level nodes
+	protoNodeSourceFile = "source_file"
+	protoNodeComment    = "comment"
+	protoNodePackage    = "package"
+	protoNodeImport     = "import"
+	protoNodeOption     = "option"
+
+	// Definition nodes
+	protoNodeMessage     = "message"
+	protoNodeMessageName = "message_name"
+	protoNodeMessageBody = "message_body"
+	protoNodeEnum        = "enum"
+	protoNodeEnumName    = "enum_name"
+	protoNodeEnumBody    = "enum_body"
+	protoNodeEnumField   = "enum_field"
+	protoNodeService     = "service"
+	protoNodeServiceName = "service_name"
+	protoNodeRPC         = "rpc"
+	protoNodeRPCName     = "rpc_name"
+
+	// Field nodes
+	protoNodeField        = "field"
+	protoNodeMapField     = "map_field"
+	protoNodeOneof        = "oneof"
+	protoNodeOneofField   = "oneof_field"
+	protoNodeFieldOptions = "field_options"
+	protoNodeFieldOption  = "field_option"
+
+	// Common nodes
+	protoNodeIdentifier        = "identifier"
+	protoNodeFullIdent         = "full_ident"
+	protoNodeString            = "string"
+	protoNodeMessageOrEnumType = "message_or_enum_type"
+	protoNodeStream            = "stream"
+
+	// Error nodes
+	protoNodeERROR = "ERROR"
+)
+
+// ProtoNodeTypes maps symbol kinds to the tree-sitter node types that produce them.
+var ProtoNodeTypes = map[SymbolKind][]string{
+	SymbolKindStruct:     {protoNodeMessage},
+	SymbolKindField:      {protoNodeField, protoNodeMapField, protoNodeOneofField},
+	SymbolKindEnum:       {protoNodeEnum},
+	SymbolKindEnumMember: {protoNodeEnumField},
+	SymbolKindInterface:  {protoNodeService},
+	SymbolKindMethod:     {protoNodeRPC},
+}
+
+// Protobuf AST Structure Reference
+//
+// source_file
+// ├── syntax (syntax = "proto3";)
+// ├── package
+// │   └── full_ident (acme.users.v1)
+// ├── import
+// │   └── string ("google/api/annotations.proto")
+// ├── option
+// │   ├── identifier | ( full_ident )
+// │   └── constant
+// ├── comment
+// ├── message
+// │   ├── message_name
+// │   │   └── identifier
+// │   └── message_body
+// │       ├── field
+// │       │   ├── type
+// │       │   ├── identifier
+// │       │   ├── field_number
+// │       │   └── field_options (optional)
+// │       │       └── field_option
+// │       ├── map_field
+// │       ├── oneof
+// │       │   └── oneof_field
+// │       ├── message (nested)
+// │       ├── enum (nested)
+// │       └── option
+// ├── enum
+// │   ├── enum_name
+// │   └── enum_body
+// │       └── enum_field
+// └── service
+//     ├── service_name
+//     ├── option
+//     └── rpc
+//         ├── rpc_name
+//         ├── stream (optional)
+//         ├── message_or_enum_type (request)
+//         ├── stream (optional)
+//         ├── message_or_enum_type (response)
+//         └── option (in optional { } body)
@@ -23,6 +23,8 @@ import (
 	sitter "github.com/smacker/go-tree-sitter"
 	"github.com/smacker/go-tree-sitter/python"
 	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // PythonParserOption configures a PythonParser instance.
@@ -190,7 +192,7 @@ func (p *PythonParser) Parse(ctx context.Context, content []byte, filePath strin
 	// Log warning for large files
 	if len(content) > WarnFileSize {
 		slog.Warn("parsing large file",
-			slog.String("file", filePath),
+			telemetry.SensitiveLog("file", filePath),
 			slog.Int("size_bytes", len(content)))
 	}
 
@@ -260,6 +262,11 @@ func (p *PythonParser) Parse(ctx context.Context, content []byte, filePath strin
 	// Extract module-level variables
 	p.extractModuleVariables(rootNode, content, filePath, result)
 
+	// Normalized body tokens for clone detection
+	if p.parseOptions.ExtractBodyTokens {
+		attachBodyTokens(rootNode, content, result.Symbols)
+	}
+
 	// Validate result before returning
 	if err := result.Validate(); err != nil {
 		recordParseMetrics(ctx, "python", time.Since(start), 0, false)
@@ -742,6 +749,7 @@ func (p *PythonParser) processMethod(ctx context.Context, node *sitter.Node, con
 // processDecoratedMethod extracts a decorated method.
 func (p *PythonParser) processDecoratedMethod(ctx context.Context, node *sitter.Node, content []byte, filePath string, className string) *Symbol {
 	decorators, decoratorArgs := p.extractDecoratorsWithArgs(node, content)
+	decoratorLiterals := p.extractDecoratorLiterals(node, content)
 
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
@@ -754,6 +762,12 @@ func (p *PythonParser) processDecoratedMethod(ctx context.Context, node *sitter.
 				}
 				sym.Metadata.DecoratorArgs = decoratorArgs
 			}
+			if sym != nil && len(decoratorLiterals) > 0 {
+				if sym.Metadata == nil {
+					sym.Metadata = &SymbolMetadata{}
+				}
+				sym.Metadata.DecoratorLiterals = decoratorLiterals
+			}
 			return sym
 		}
 	}
@@ -786,6 +800,12 @@ func (p *PythonParser) extractFunctions(ctx context.Context, root *sitter.Node,
 							}
 							fn.Metadata.DecoratorArgs = decoratorArgs
 						}
+						if decoratorLiterals := p.extractDecoratorLiterals(child, content); len(decoratorLiterals) > 0 {
+							if fn.Metadata == nil {
+								fn.Metadata = &SymbolMetadata{}
+							}
+							fn.Metadata.DecoratorLiterals = decoratorLiterals
+						}
 						// Extract nested functions
 						p.extractNestedFunctions(ctx, grandchild, content, filePath, fn)
 						result.Symbols = append(result.Symbols, fn)
@@ -1222,6 +1242,100 @@ func (p *PythonParser) extractDecoratorsWithArgs(node *sitter.Node, content []by
 	return decorators, decoratorArgs
 }
 
+// extractDecoratorLiterals extracts the string literal arguments of decorator calls.
+//
+// Description:
+//
+//	For @app.route("/users", methods=["GET", "POST"]) returns
+//	{"app.route": ["/users", "methods=GET,POST"]}. Positional strings are
+//	kept in order; keyword arguments are kept only if their value is a
+//	string or a list of strings. Decorators without such arguments are
+//	omitted.
+//
+// Inputs:
+//   - node: The decorated_definition node.
+//   - content: Source file bytes.
+//
+// Outputs:
+//   - map[string][]string: Decorator name to literals, or nil if none.
+//
+// Thread Safety: Safe for concurrent use.
+func (p *PythonParser) extractDecoratorLiterals(node *sitter.Node, content []byte) map[string][]string {
+	var literals map[string][]string
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() != "decorator" {
+			continue
+		}
+		for j := 0; j < int(child.NamedChildCount()); j++ {
+			call := child.NamedChild(j)
+			if call.Type() != "call" {
+				continue
+			}
+			fn, args := call.ChildByFieldName("function"), call.ChildByFieldName("arguments")
+			if fn == nil || args == nil {
+				continue
+			}
+			var values []string
+			for k := 0; k < int(args.NamedChildCount()); k++ {
+				arg := args.NamedChild(k)
+				switch arg.Type() {
+				case "string":
+					if v, ok := pythonStringValue(arg, content); ok {
+						values = append(values, v)
+					}
+				case "keyword_argument":
+					name, value := arg.ChildByFieldName("name"), arg.ChildByFieldName("value")
+					if name == nil || value == nil {
+						continue
+					}
+					var items []string
+					switch value.Type() {
+					case "string":
+						if v, ok := pythonStringValue(value, content); ok {
+							items = append(items, v)
+						}
+					case "list", "tuple":
+						for m := 0; m < int(value.NamedChildCount()); m++ {
+							if v, ok := pythonStringValue(value.NamedChild(m), content); ok {
+								items = append(items, v)
+							}
+						}
+					}
+					if len(items) > 0 {
+						values = append(values, string(content[name.StartByte():name.EndByte()])+"="+strings.Join(items, ","))
+					}
+				}
+			}
+			if len(values) > 0 {
+				if literals == nil {
+					literals = make(map[string][]string)
+				}
+				literals[string(content[fn.StartByte():fn.EndByte()])] = values
+			}
+		}
+	}
+	return literals
+}
+
+// pythonStringValue returns the content of a plain (non-interpolated) string node.
+func pythonStringValue(node *sitter.Node, content []byte) (string, bool) {
+	if node == nil || node.Type() != "string" {
+		return "", false
+	}
+	var b strings.Builder
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		part := node.NamedChild(i)
+		switch part.Type() {
+		case "string_content":
+			b.WriteString(string(content[part.StartByte():part.EndByte()]))
+		case "interpolation":
+			return "", false
+		}
+	}
+	return b.String(), true
+}
+
 // extractDecoratorArgIdentifiers extracts identifier arguments from a Python decorator's argument list.
 //
 // Description:
@@ -1630,7 +1744,7 @@ func (p *PythonParser) hasAbstractMethod(ctx context.Context, classSym *Symbol)
 			if dec == "abstractmethod" || dec == "abc.abstractmethod" {
 				slog.Debug("GR-40a: Found @abstractmethod",
 					slog.String("class", classSym.Name),
-					slog.String("method", child.Name),
+					telemetry.SensitiveLog("method", child.Name),
 				)
 				return true
 			}
@@ -1943,7 +2057,7 @@ func (p *PythonParser) extractCallSites(ctx context.Context, bodyNode *sitter.No
 
 		if entry.depth > MaxCallExpressionDepth {
 			slog.Debug("GR-41: Max call expression depth reached in Python",
-				slog.String("file", filePath),
+				telemetry.SensitiveLog("file", filePath),
 				slog.Int("depth", entry.depth),
 			)
 			continue
@@ -1953,7 +2067,7 @@ func (p *PythonParser) extractCallSites(ctx context.Context, bodyNode *sitter.No
 		if nodeCount%100 == 0 {
 			if ctx.Err() != nil {
 				slog.Debug("GR-41: Context cancelled during Python call extraction",
-					slog.String("file", filePath),
+					telemetry.SensitiveLog("file", filePath),
 					slog.Int("calls_found", len(calls)),
 				)
 				return calls
@@ -1962,7 +2076,7 @@ func (p *PythonParser) extractCallSites(ctx context.Context, bodyNode *sitter.No
 
 		if len(calls) >= MaxCallSitesPerSymbol {
 			slog.Warn("GR-41: Max call sites per symbol reached in Python",
-				slog.String("file", filePath),
+				telemetry.SensitiveLog("file", filePath),
 				slog.Int("limit", MaxCallSitesPerSymbol),
 			)
 			return calls
@@ -1990,7 +2104,7 @@ func (p *PythonParser) extractCallSites(ctx context.Context, bodyNode *sitter.No
 	}
 
 	span.SetAttributes(
-		attribute.String("file", filePath),
+		telemetry.SensitiveString("file", filePath),
 		attribute.Int("calls_found", len(calls)),
 		attribute.Int("nodes_traversed", nodeCount),
 	)
@@ -2075,6 +2189,7 @@ func (p *PythonParser) extractSingleCallSite(node *sitter.Node, content []byte,
 
 			call.Receiver = receiver
 			call.IsMethod = true
+			call.Chain = extractCallChain(pythonCallChainSyntax, funcNode, content)
 		}
 
 	default:
@@ -2092,6 +2207,12 @@ func (p *PythonParser) extractSingleCallSite(node *sitter.Node, content []byte,
 		return nil
 	}
 
+	if argsNode := node.ChildByFieldName("arguments"); argsNode != nil && p.parseOptions.CaptureLiteralArgs {
+		capture := newLiteralArgCapture(filePath, content, true, p.parseOptions.KeepSensitiveLiterals)
+		capturePythonLiteralArgs(capture, argsNode)
+		call.LiteralArgs = capture.finish(call.Target)
+	}
+
 	return call
 }
 
@@ -3326,3 +3326,45 @@ func TestPythonParser_PropertyAccess_ExistingTestSource(t *testing.T) {
 		t.Error("expected display_name to be extracted as SymbolKindProperty")
 	}
 }
+
+func TestPythonParser_DecoratorLiterals(t *testing.T) {
+	src := `@app.route("/items", methods=["GET", 'POST'], strict_slashes=False)
+def items():
+    pass
+
+@router.get(f"/users/{uid}")
+def user():
+    pass
+
+class Views:
+    @bp.post('/views')
+    def create(self):
+        pass
+`
+	result, err := NewPythonParser().Parse(context.Background(), []byte(src), "app/views.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	literals := make(map[string]map[string][]string)
+	var walk func(syms []*Symbol)
+	walk = func(syms []*Symbol) {
+		for _, sym := range syms {
+			if sym.Metadata != nil {
+				literals[sym.Name] = sym.Metadata.DecoratorLiterals
+			}
+			walk(sym.Children)
+		}
+	}
+	walk(result.Symbols)
+
+	if got := strings.Join(literals["items"]["app.route"], "|"); got != "/items|methods=GET,POST" {
+		t.Errorf("items literals = %q", got)
+	}
+	if got := literals["user"]; got != nil {
+		t.Errorf("f-string route should not be recorded: %v", got)
+	}
+	if got := strings.Join(literals["create"]["bp.post"], "|"); got != "/views" {
+		t.Errorf("method literals = %q", got)
+	}
+}
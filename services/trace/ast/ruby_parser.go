@@ -0,0 +1,855 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/ruby"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// RubyParserOption configures a RubyParser instance.
+type RubyParserOption func(*RubyParser)
+
+// WithRubyMaxFileSize sets the maximum file size the parser will accept.
+//
+// Parameters:
+//   - bytes: Maximum file size in bytes. Must be positive.
+//
+// Example:
+//
+//	parser := NewRubyParser(WithRubyMaxFileSize(5 * 1024 * 1024)) // 5MB limit
+func WithRubyMaxFileSize(bytes int64) RubyParserOption {
+	return func(p *RubyParser) {
+		if bytes > 0 {
+			p.maxFileSize = bytes
+		}
+	}
+}
+
+// WithRubyParseOptions applies the given ParseOptions to the parser.
+//
+// Parameters:
+//   - opts: ParseOptions to apply.
+//
+// Example:
+//
+//	parser := NewRubyParser(WithRubyParseOptions(ParseOptions{IncludePrivate: false}))
+func WithRubyParseOptions(opts ParseOptions) RubyParserOption {
+	return func(p *RubyParser) {
+		p.parseOptions = opts
+	}
+}
+
+// RubyParser implements the Parser interface for Ruby source code.
+//
+// Description:
+//
+//	RubyParser uses tree-sitter to parse .rb files and extract symbols,
+//	mapped onto the existing symbol kinds:
+//
+//	  require "json"        -> Import, Path "json"
+//	  require_relative "x"  -> Import, Path "x", IsRelative
+//	  class                 -> SymbolKindClass
+//	  module                -> SymbolKindClass with Metadata.IsAbstract and
+//	                           signature "module Name"
+//	  def (in a class)      -> SymbolKindMethod, Receiver = class
+//	  def self.x, class << self
+//	                        -> SymbolKindMethod with Metadata.IsStatic
+//	  def (top level)       -> SymbolKindFunction
+//	  attr_accessor :name   -> SymbolKindProperty, one per name
+//	  NAME = value          -> SymbolKindConstant
+//
+//	Classes and modules nested in a module or class are emitted as
+//	top-level symbols with Package set to the enclosing path
+//	("Billing::Invoice" -> Name "Invoice", Package "Billing"), so a class
+//	reopened in several files yields one symbol per file, all with the
+//	same name. The graph builder associates each reopening's instance
+//	methods with every declaration of the class, the way it associates
+//	Go methods declared in another file with their type.
+//
+//	"class A < B" goes to Metadata.Extends. Modules added with include,
+//	prepend or extend go to Metadata.Mixins; the graph builder links them
+//	with EMBEDS edges so their methods are promoted onto the class like
+//	PHP trait methods. Whether a module extends the class's instance or
+//	singleton methods is not distinguished. Visibility set with private,
+//	protected and public (bare, with a def, or with symbol names) is
+//	recorded in Metadata.AccessModifier. Metaprogramming (define_method,
+//	method_missing, Rails DSLs) and RBS/Sorbet signatures are not modelled.
+//
+// Thread Safety:
+//
+//	RubyParser instances are safe for concurrent use. Each Parse call
+//	creates its own tree-sitter parser instance.
+//
+// Example:
+//
+//	parser := NewRubyParser()
+//	result, err := parser.Parse(ctx, content, "app/models/invoice.rb")
+//	if err != nil {
+//	    return fmt.Errorf("parse: %w", err)
+//	}
+//	for _, sym := range result.Symbols {
+//	    fmt.Printf("%s: %s\n", sym.Kind, sym.Name)
+//	}
+type RubyParser struct {
+	maxFileSize  int64
+	parseOptions ParseOptions
+}
+
+// NewRubyParser creates a new RubyParser with the given options.
+//
+// Inputs:
+//   - opts: Optional configuration functions (WithRubyMaxFileSize, WithRubyParseOptions)
+//
+// Outputs:
+//   - *RubyParser: Configured parser instance, never nil
+//
+// Thread Safety:
+//
+//	The returned RubyParser is safe for concurrent use.
+func NewRubyParser(opts ...RubyParserOption) *RubyParser {
+	p := &RubyParser{
+		maxFileSize:  DefaultMaxFileSize,
+		parseOptions: DefaultParseOptions(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Language returns the canonical language name for this parser.
+func (p *RubyParser) Language() string {
+	return "ruby"
+}
+
+// Extensions returns the file extensions this parser handles.
+func (p *RubyParser) Extensions() []string {
+	return []string{".rb"}
+}
+
+// rubyFile holds the per-file state of one Parse call.
+type rubyFile struct {
+	content  []byte
+	filePath string
+	lines    []string
+}
+
+// rubyBody describes the class or module body being extracted.
+type rubyBody struct {
+	// owner is the class or module symbol, nil at the top level.
+	owner *Symbol
+	// namespace is the "::"-joined path of the enclosing classes and
+	// modules, used as Package of declarations in the body.
+	namespace string
+	// static is true inside "class << self".
+	static bool
+}
+
+// rubyScope identifies the method whose calls are being extracted.
+type rubyScope struct {
+	owner  string
+	method string
+}
+
+// Parse extracts symbols from Ruby source code.
+//
+// Description:
+//
+//	Parses the provided Ruby source with tree-sitter and extracts
+//	require statements, classes, modules, methods, attribute accessors
+//	and constants. The parser is error-tolerant: syntax errors are
+//	reported in ParseResult.Errors and the remaining declarations are
+//	still extracted.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked before and after parsing.
+//   - content: Raw Ruby source bytes. Must be valid UTF-8.
+//   - filePath: Path to the file, relative to the project root.
+//
+// Outputs:
+//   - *ParseResult: Extracted symbols and metadata. Never nil on success.
+//   - error: Non-nil for complete failures:
+//   - ErrFileTooLarge: Content exceeds maxFileSize
+//   - ErrInvalidContent: Content is not valid UTF-8
+//   - Context errors: Context was canceled or timed out
+//
+// Thread Safety:
+//
+//	This method is safe for concurrent use.
+func (p *RubyParser) Parse(ctx context.Context, content []byte, filePath string) (*ParseResult, error) {
+	ctx, span := startParseSpan(ctx, "ruby", filePath, len(content))
+	defer span.End()
+
+	start := time.Now()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "ruby", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled before start: %w", err)
+	}
+
+	if int64(len(content)) > p.maxFileSize {
+		recordParseMetrics(ctx, "ruby", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: size %d exceeds limit %d", ErrFileTooLarge, len(content), p.maxFileSize)
+	}
+
+	if !utf8.Valid(content) {
+		recordParseMetrics(ctx, "ruby", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: content is not valid UTF-8", ErrInvalidContent)
+	}
+
+	hash := sha256.Sum256(content)
+	hashStr := hex.EncodeToString(hash[:])
+
+	// New tree-sitter parser per call for thread safety
+	parser := sitter.NewParser()
+	parser.SetLanguage(ruby.GetLanguage())
+
+	tree, err := parser.ParseCtx(ctx, nil, content)
+	if err != nil {
+		recordParseMetrics(ctx, "ruby", time.Since(start), 0, false)
+		return nil, fmt.Errorf("tree-sitter parse failed: %w", err)
+	}
+	defer tree.Close()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "ruby", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled after tree-sitter: %w", err)
+	}
+
+	result := &ParseResult{
+		FilePath:      filePath,
+		Language:      "ruby",
+		Hash:          hashStr,
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Symbols:       make([]*Symbol, 0),
+		Imports:       make([]Import, 0),
+		Errors:        make([]string, 0),
+	}
+
+	rootNode := tree.RootNode()
+	if rootNode == nil {
+		result.Errors = append(result.Errors, "tree-sitter returned nil root node")
+		return result, nil
+	}
+
+	f := &rubyFile{
+		content:  content,
+		filePath: filePath,
+		lines:    strings.Split(string(content), "\n"),
+	}
+	p.extractBody(ctx, rootNode, f, rubyBody{}, result)
+
+	if err := result.Validate(); err != nil {
+		recordParseMetrics(ctx, "ruby", time.Since(start), 0, false)
+		return nil, fmt.Errorf("result validation failed: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "ruby", time.Since(start), len(result.Symbols), false)
+		return nil, fmt.Errorf("parse canceled after extraction: %w", err)
+	}
+
+	setParseSpanResult(span, len(result.Symbols), len(result.Errors))
+	recordParseMetrics(ctx, "ruby", time.Since(start), len(result.Symbols), true)
+
+	return result, nil
+}
+
+// extractBody extracts the statements directly under parent: the program,
+// or the body of a class, module or "class << self". Classes and modules
+// are appended to result.Symbols; methods, accessors and constants become
+// children of body.owner, or top-level symbols if there is none.
+func (p *RubyParser) extractBody(ctx context.Context, parent *sitter.Node, f *rubyFile, body rubyBody, result *ParseResult) {
+	visibility := "public"
+	// Visibility given by name ("private :a, :b") applies after the body
+	// is extracted, since the methods may be defined after the call.
+	named := make(map[string]string)
+
+	var members []*Symbol
+	for i := 0; i < int(parent.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			return
+		}
+
+		child := parent.Child(i)
+		switch child.Type() {
+		case rubyNodeClass, rubyNodeModule:
+			p.extractClass(ctx, child, f, body.namespace, result)
+		case rubyNodeSingletonClass:
+			if value := child.ChildByFieldName("value"); value != nil && value.Type() == rubyNodeSelf {
+				if inner := child.ChildByFieldName("body"); inner != nil {
+					p.extractBody(ctx, inner, f, rubyBody{owner: body.owner, namespace: body.namespace, static: true}, result)
+				}
+			}
+		case rubyNodeMethod, rubyNodeSingletonMethod:
+			members = append(members, p.extractMethod(ctx, child, f, body, visibility))
+		case rubyNodeIdentifier:
+			if v := rubyNodeText(child, f.content); rubyIsVisibility(v) {
+				visibility = v
+			}
+		case rubyNodeAssignment:
+			members = append(members, p.extractConstant(child, f, body))
+		case rubyNodeCall:
+			members = append(members, p.extractBodyCall(ctx, child, f, body, &visibility, named, result)...)
+		case rubyNodeERROR:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s:%d: syntax error: %s",
+				f.filePath, int(child.StartPoint().Row)+1, sqlSnippet(f.content[child.StartByte():child.EndByte()])))
+		}
+	}
+
+	for _, member := range members {
+		if member == nil {
+			continue
+		}
+		if v, ok := named[member.Name]; ok && member.Kind != SymbolKindConstant {
+			rubySetVisibility(member, v)
+		}
+		if !p.include(member) {
+			continue
+		}
+		if body.owner != nil {
+			body.owner.Children = append(body.owner.Children, member)
+		} else {
+			result.Symbols = append(result.Symbols, member)
+		}
+	}
+}
+
+// extractBodyCall handles a method call in a body: require, include and
+// friends, attribute accessors and visibility changes. Other calls are
+// ignored. Returns the members the call declares.
+func (p *RubyParser) extractBodyCall(ctx context.Context, node *sitter.Node, f *rubyFile, body rubyBody, visibility *string, named map[string]string, result *ParseResult) []*Symbol {
+	if node.ChildByFieldName("receiver") != nil {
+		return nil
+	}
+	methodNode := node.ChildByFieldName("method")
+	if methodNode == nil || methodNode.Type() != rubyNodeIdentifier {
+		return nil
+	}
+	name := rubyNodeText(methodNode, f.content)
+	args := node.ChildByFieldName("arguments")
+
+	switch name {
+	case "require", "require_relative":
+		if args == nil {
+			return nil
+		}
+		if path := rubyStringArg(args, f.content); path != "" {
+			result.Imports = append(result.Imports, Import{
+				Path:       path,
+				IsRelative: name == "require_relative",
+				Location:   rubyLocation(node, f.filePath),
+			})
+		}
+	case "include", "prepend", "extend":
+		if body.owner == nil || args == nil {
+			return nil
+		}
+		for i := 0; i < int(args.NamedChildCount()); i++ {
+			if mixin := rubyConstantName(args.NamedChild(i), f.content); mixin != "" {
+				meta := rubyMetadata(body.owner)
+				meta.Mixins = append(meta.Mixins, mixin)
+			}
+		}
+	case "attr_accessor", "attr_reader", "attr_writer":
+		if body.owner == nil || args == nil {
+			return nil
+		}
+		var props []*Symbol
+		for i := 0; i < int(args.NamedChildCount()); i++ {
+			arg := args.NamedChild(i)
+			if arg.Type() != rubyNodeSimpleSymbol {
+				continue
+			}
+			propName := strings.TrimPrefix(rubyNodeText(arg, f.content), ":")
+			prop := p.newSymbol(arg, f, propName, SymbolKindProperty, name+" :"+propName, body.owner.Package)
+			prop.Receiver = body.owner.Name
+			meta := rubyMetadata(prop)
+			meta.ParentName = body.owner.Name
+			rubySetVisibility(prop, *visibility)
+			props = append(props, prop)
+		}
+		return props
+	case "private", "protected", "public":
+		if args == nil {
+			*visibility = name
+			return nil
+		}
+		var methods []*Symbol
+		for i := 0; i < int(args.NamedChildCount()); i++ {
+			arg := args.NamedChild(i)
+			switch arg.Type() {
+			case rubyNodeMethod, rubyNodeSingletonMethod:
+				methods = append(methods, p.extractMethod(ctx, arg, f, body, name))
+			case rubyNodeSimpleSymbol:
+				named[strings.TrimPrefix(rubyNodeText(arg, f.content), ":")] = name
+			}
+		}
+		return methods
+	}
+	return nil
+}
+
+// extractClass builds a class or module symbol, appends it to
+// result.Symbols and extracts its body.
+func (p *RubyParser) extractClass(ctx context.Context, node *sitter.Node, f *rubyFile, namespace string, result *ParseResult) {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return
+	}
+	path := rubyNodeText(nameNode, f.content)
+	name := rubyLastSegment(path)
+	pkg := rubyJoinNamespace(namespace, strings.TrimSuffix(strings.TrimSuffix(path, name), "::"))
+
+	sigEnd := nameNode.EndByte()
+	superclass := node.ChildByFieldName("superclass")
+	if superclass != nil {
+		sigEnd = superclass.EndByte()
+	}
+	signature := rubyCollapseSpace(string(f.content[node.StartByte():sigEnd]))
+
+	sym := p.newSymbol(node, f, name, SymbolKindClass, signature, pkg)
+	if node.Type() == rubyNodeModule {
+		rubyMetadata(sym).IsAbstract = true
+	}
+	if superclass != nil {
+		if parent := superclass.NamedChild(0); parent != nil {
+			if parentName := rubyConstantName(parent, f.content); parentName != "" {
+				rubyMetadata(sym).Extends = parentName
+			}
+		}
+	}
+	if result.Package == "" && pkg != "" {
+		result.Package = pkg
+	}
+	result.Symbols = append(result.Symbols, sym)
+
+	if body := node.ChildByFieldName("body"); body != nil {
+		p.extractBody(ctx, body, f, rubyBody{owner: sym, namespace: rubyJoinNamespace(pkg, name)}, result)
+	}
+	rubyCollectMethods(sym)
+}
+
+// extractMethod builds a method symbol with Receiver = the owning class,
+// or a function symbol at the top level.
+func (p *RubyParser) extractMethod(ctx context.Context, node *sitter.Node, f *rubyFile, body rubyBody, visibility string) *Symbol {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil
+	}
+	name := rubyNodeText(nameNode, f.content)
+
+	sigEnd := nameNode.EndByte()
+	if params := node.ChildByFieldName("parameters"); params != nil {
+		sigEnd = params.EndByte()
+	}
+	signature := rubyCollapseSpace(string(f.content[node.StartByte():sigEnd]))
+
+	owner, pkg := "", body.namespace
+	kind := SymbolKindFunction
+	if body.owner != nil {
+		owner, pkg = body.owner.Name, body.owner.Package
+		kind = SymbolKindMethod
+	}
+	sym := p.newSymbol(node, f, name, kind, signature, pkg)
+	sym.Receiver = owner
+	rubySetVisibility(sym, visibility)
+	if owner != "" {
+		meta := rubyMetadata(sym)
+		meta.ParentName = owner
+		meta.IsStatic = body.static || node.Type() == rubyNodeSingletonMethod
+		meta.IsConstructor = name == "initialize" && !meta.IsStatic
+	}
+	if methodBody := node.ChildByFieldName("body"); methodBody != nil {
+		sym.Calls = p.extractCallSites(ctx, methodBody, f, rubyScope{owner: owner, method: name})
+	}
+	return sym
+}
+
+// extractConstant builds a constant symbol for "NAME = value". Other
+// assignments in a body are ignored.
+func (p *RubyParser) extractConstant(node *sitter.Node, f *rubyFile, body rubyBody) *Symbol {
+	left := node.ChildByFieldName("left")
+	if left == nil || left.Type() != rubyNodeConstant {
+		return nil
+	}
+	name := rubyNodeText(left, f.content)
+	signature := name
+	if node.StartPoint().Row == node.EndPoint().Row {
+		signature = rubyCollapseSpace(rubyNodeText(node, f.content))
+	}
+
+	pkg := body.namespace
+	if body.owner != nil {
+		pkg = body.owner.Package
+	}
+	sym := p.newSymbol(node, f, name, SymbolKindConstant, signature, pkg)
+	if body.owner != nil {
+		sym.Receiver = body.owner.Name
+		rubyMetadata(sym).ParentName = body.owner.Name
+	}
+	return sym
+}
+
+// extractCallSites extracts all call sites within a method body.
+//
+// Description:
+//
+//	Walks the subtree under bodyNode and records each call. "helper(x)"
+//	is a plain call; "self.helper(x)" is a method call with Receiver
+//	"self", which the graph builder resolves against the enclosing
+//	class, its superclasses and its mixins; "Formatter.format(a)" and
+//	"Billing::Tax.rate" use the constant ("Formatter", "Tax") as
+//	Receiver; "@repo.find" uses "self.repo"; "user&.save!" uses "user".
+//	"Invoice.new" and a receiverless "new" in a class method are plain
+//	calls to the class. "super" and "super(x)" are calls to the same
+//	method with Receiver "super". Calls inside blocks are included.
+//	Nested method and class definitions are skipped: they are separate
+//	symbols. Bare identifiers ("helper" without arguments) are not
+//	recorded, since they cannot be told apart from local variables.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked every 100 nodes.
+//   - bodyNode: The method body. May be nil.
+//   - f: The file being parsed.
+//   - scope: The method being extracted and its owning class.
+//
+// Outputs:
+//   - []CallSite: Extracted call sites. Limited to MaxCallSitesPerSymbol (1000).
+//
+// Thread Safety: Safe for concurrent use.
+func (p *RubyParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node, f *rubyFile, scope rubyScope) []CallSite {
+	if bodyNode == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "RubyParser.extractCallSites")
+	defer span.End()
+
+	calls := make([]CallSite, 0, 8)
+
+	type stackEntry struct {
+		node  *sitter.Node
+		depth int
+	}
+	stack := []stackEntry{{node: bodyNode}}
+
+	nodeCount := 0
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := entry.node
+		if entry.depth > MaxCallExpressionDepth {
+			continue
+		}
+
+		nodeCount++
+		if nodeCount%100 == 0 && ctx.Err() != nil {
+			return calls
+		}
+
+		if len(calls) >= MaxCallSitesPerSymbol {
+			slog.Warn("max call sites per symbol reached in Ruby",
+				telemetry.SensitiveLog("file", f.filePath),
+				slog.Int("limit", MaxCallSitesPerSymbol),
+			)
+			return calls
+		}
+
+		switch node.Type() {
+		case rubyNodeCall, rubyNodeSuper:
+			if call := rubyCallSite(node, f, scope); call != nil {
+				calls = append(calls, *call)
+			}
+		case rubyNodeMethod, rubyNodeSingletonMethod, rubyNodeClass, rubyNodeModule, rubyNodeSingletonClass:
+			if node != bodyNode {
+				continue
+			}
+		}
+
+		// Push in reverse order for left-to-right processing. The method
+		// name of a call is not itself a call ("super(x)" has a super child).
+		for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+			if node.Type() == rubyNodeCall && node.FieldNameForChild(i) == "method" {
+				continue
+			}
+			if child := node.Child(i); child != nil {
+				stack = append(stack, stackEntry{node: child, depth: entry.depth + 1})
+			}
+		}
+	}
+
+	span.SetAttributes(
+		telemetry.SensitiveString("file", f.filePath),
+		attribute.Int("calls_found", len(calls)),
+		attribute.Int("nodes_traversed", nodeCount),
+	)
+
+	return calls
+}
+
+// rubyCallSite converts a call or bare super node to a CallSite, or nil if
+// the callee cannot be named.
+func rubyCallSite(node *sitter.Node, f *rubyFile, scope rubyScope) *CallSite {
+	call := &CallSite{Location: rubyLocation(node, f.filePath)}
+
+	methodNode := node
+	if node.Type() == rubyNodeCall {
+		methodNode = node.ChildByFieldName("method")
+		if methodNode == nil {
+			return nil
+		}
+	}
+
+	switch methodNode.Type() {
+	case rubyNodeSuper:
+		if scope.method == "" {
+			return nil
+		}
+		call.Target = scope.method
+		call.IsMethod = true
+		call.Receiver = "super"
+		return call
+	case rubyNodeIdentifier, rubyNodeConstant:
+	default:
+		return nil
+	}
+
+	name := rubyNodeText(methodNode, f.content)
+	receiver := node.ChildByFieldName("receiver")
+	switch {
+	case receiver == nil && name == "new" && scope.owner != "":
+		call.Target = scope.owner
+	case receiver == nil:
+		call.Target = name
+	case name == "new" && (receiver.Type() == rubyNodeConstant || receiver.Type() == rubyNodeScopeResolution):
+		call.Target = rubyConstantName(receiver, f.content)
+	default:
+		call.Target = name
+		call.IsMethod = true
+		call.Receiver = rubyReceiver(receiver, f.content)
+	}
+
+	if call.Target == "" {
+		return nil
+	}
+	return call
+}
+
+// rubyReceiver normalizes a receiver expression to the dotted form the
+// graph builder expects: "self" -> "self", "Billing::Tax" -> "Tax",
+// "@repo" -> "self.repo", "user.profile" -> "user.profile".
+func rubyReceiver(node *sitter.Node, content []byte) string {
+	switch node.Type() {
+	case rubyNodeSelf:
+		return "self"
+	case rubyNodeConstant, rubyNodeScopeResolution:
+		return rubyConstantName(node, content)
+	case rubyNodeInstanceVar:
+		return "self." + strings.TrimPrefix(rubyNodeText(node, content), "@")
+	case rubyNodeCall:
+		method := node.ChildByFieldName("method")
+		if method == nil {
+			break
+		}
+		name := rubyNodeText(method, content)
+		if receiver := node.ChildByFieldName("receiver"); receiver != nil {
+			return rubyReceiver(receiver, content) + "." + name
+		}
+		return name
+	}
+	return rubyCollapseSpace(rubyNodeText(node, content))
+}
+
+// newSymbol creates a Ruby symbol with its location, package and doc
+// comment set.
+func (p *RubyParser) newSymbol(node *sitter.Node, f *rubyFile, name string, kind SymbolKind, signature, pkg string) *Symbol {
+	return &Symbol{
+		ID:            GenerateID(f.filePath, int(node.StartPoint().Row)+1, name),
+		Name:          name,
+		Kind:          kind,
+		FilePath:      f.filePath,
+		StartLine:     int(node.StartPoint().Row) + 1,
+		EndLine:       int(node.EndPoint().Row) + 1,
+		StartCol:      int(node.StartPoint().Column),
+		EndCol:        int(node.EndPoint().Column),
+		Signature:     signature,
+		DocComment:    rubyDocComment(f.lines, int(node.StartPoint().Row)),
+		Package:       pkg,
+		Language:      "ruby",
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Exported:      true,
+	}
+}
+
+// include reports whether sym passes the IncludePrivate option.
+func (p *RubyParser) include(sym *Symbol) bool {
+	return p.parseOptions.IncludePrivate || sym.Exported
+}
+
+// rubySetVisibility records the visibility of a member. Ruby members are
+// public unless marked otherwise; private ones are not exported.
+func rubySetVisibility(sym *Symbol, visibility string) {
+	switch visibility {
+	case "private", "protected":
+		rubyMetadata(sym).AccessModifier = visibility
+		sym.Exported = visibility != "private"
+	default:
+		if sym.Metadata != nil {
+			sym.Metadata.AccessModifier = ""
+		}
+		sym.Exported = true
+	}
+}
+
+// rubyIsVisibility reports whether name is a visibility keyword.
+func rubyIsVisibility(name string) bool {
+	return name == "private" || name == "protected" || name == "public"
+}
+
+// rubyCollectMethods fills Metadata.Methods of a class or module from its
+// instance methods. The graph builder adds the instance methods of the
+// same class declared in other files.
+func rubyCollectMethods(sym *Symbol) {
+	for _, child := range sym.Children {
+		if child.Kind != SymbolKindMethod || child.Metadata == nil ||
+			child.Metadata.IsStatic || child.Metadata.IsConstructor {
+			continue
+		}
+		meta := rubyMetadata(sym)
+		meta.Methods = append(meta.Methods, rubyMethodSignature(child, sym.Name))
+	}
+}
+
+// rubyMethodSignature builds the MethodSignature of a method symbol from
+// its "def name(params)" or "def name params" signature.
+func rubyMethodSignature(method *Symbol, receiverType string) MethodSignature {
+	sig := MethodSignature{
+		Name:         method.Name,
+		ReceiverType: receiverType,
+	}
+	params := strings.TrimSpace(strings.TrimPrefix(method.Signature, "def "))
+	params = strings.TrimSpace(strings.TrimPrefix(params, method.Name))
+	params = strings.TrimSuffix(strings.TrimPrefix(params, "("), ")")
+	sig.Params = strings.TrimSpace(params)
+	if sig.Params != "" {
+		sig.ParamCount = strings.Count(sig.Params, ",") + 1
+	}
+	return sig
+}
+
+// rubyStringArg returns the content of the first argument of a call if
+// it is a plain string literal: "json" for require "json".
+func rubyStringArg(args *sitter.Node, content []byte) string {
+	arg := args.NamedChild(0)
+	if arg == nil || arg.Type() != rubyNodeString || arg.NamedChildCount() != 1 {
+		return ""
+	}
+	if text := arg.NamedChild(0); text.Type() == rubyNodeStringContent {
+		return rubyNodeText(text, content)
+	}
+	return ""
+}
+
+// rubyConstantName returns the last segment of a constant or scoped
+// constant ("Payable" for Billing::Payable), or "" for other nodes.
+func rubyConstantName(node *sitter.Node, content []byte) string {
+	switch node.Type() {
+	case rubyNodeConstant:
+		return rubyNodeText(node, content)
+	case rubyNodeScopeResolution:
+		if name := node.ChildByFieldName("name"); name != nil {
+			return rubyNodeText(name, content)
+		}
+	}
+	return ""
+}
+
+// rubyDocComment returns the run of # comment lines directly above line
+// row (0-based), with the markers stripped.
+func rubyDocComment(lines []string, row int) string {
+	var out []string
+	for i := row - 1; i >= 0 && i < len(lines); i-- {
+		line := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		out = append([]string{strings.TrimSpace(strings.TrimLeft(line, "#"))}, out...)
+	}
+	return strings.Join(out, "\n")
+}
+
+// rubyLastSegment returns the last segment of a scoped name: "Invoice"
+// for Billing::Invoice.
+func rubyLastSegment(name string) string {
+	if i := strings.LastIndex(name, "::"); i >= 0 {
+		return name[i+2:]
+	}
+	return name
+}
+
+// rubyJoinNamespace joins two "::"-separated paths, either of which may
+// be empty.
+func rubyJoinNamespace(outer, inner string) string {
+	inner = strings.TrimPrefix(inner, "::")
+	switch {
+	case outer == "":
+		return inner
+	case inner == "":
+		return outer
+	}
+	return outer + "::" + inner
+}
+
+// rubyMetadata returns sym.Metadata, allocating it if needed.
+func rubyMetadata(sym *Symbol) *SymbolMetadata {
+	if sym.Metadata == nil {
+		sym.Metadata = &SymbolMetadata{}
+	}
+	return sym.Metadata
+}
+
+// rubyLocation returns the location of node.
+func rubyLocation(node *sitter.Node, filePath string) Location {
+	return Location{
+		FilePath:  filePath,
+		StartLine: int(node.StartPoint().Row) + 1,
+		EndLine:   int(node.EndPoint().Row) + 1,
+		StartCol:  int(node.StartPoint().Column),
+		EndCol:    int(node.EndPoint().Column),
+	}
+}
+
+// rubyCollapseSpace replaces runs of whitespace with single spaces.
+func rubyCollapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// rubyNodeText returns the source text of node.
+func rubyNodeText(node *sitter.Node, content []byte) string {
+	return string(content[node.StartByte():node.EndByte()])
+}
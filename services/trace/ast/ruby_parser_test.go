@@ -0,0 +1,239 @@
+package ast
+
+import (
+	"context"
+	"testing"
+)
+
+const testRubySource = `require "json"
+require_relative "../support/formatter"
+
+module Billing
+  # Adds payment helpers.
+  module Payable
+    def pay!
+      self.charge(amount)
+    end
+  end
+
+  # An invoice sent to a customer.
+  class Invoice < ApplicationRecord
+    include Payable
+    extend Billing::Searchable
+    attr_accessor :amount, :due
+    attr_reader :id
+
+    CURRENCY = "USD"
+
+    def initialize(amount)
+      @amount = amount
+    end
+
+    def total(tax = 0)
+      self.subtotal(tax)
+      Formatter.format(amount)
+      @ledger.record(amount)
+      Billing::Tax.rate
+      user&.save!
+      items.each do |item|
+        process(item)
+      end
+      super
+    end
+
+    def self.build(amount)
+      new(amount)
+    end
+
+    class << self
+      def create
+        Invoice.new(0)
+      end
+    end
+
+    private
+
+    def secret; end
+
+    public def visible; end
+
+    protected :id
+  end
+end
+
+class Billing::Receipt; end
+
+def top_level(a)
+  puts a
+end
+
+MAX_ITEMS = 10
+`
+
+func parseTestRuby(t *testing.T) *ParseResult {
+	t.Helper()
+	result, err := NewRubyParser().Parse(context.Background(), []byte(testRubySource), "app/models/invoice.rb")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected parse errors: %v", result.Errors)
+	}
+	return result
+}
+
+func TestRubyParser_LanguageAndExtensions(t *testing.T) {
+	parser := NewRubyParser()
+	if got := parser.Language(); got != "ruby" {
+		t.Errorf("Language() = %q, want %q", got, "ruby")
+	}
+	if exts := parser.Extensions(); len(exts) != 1 || exts[0] != ".rb" {
+		t.Errorf("Extensions() = %v, want [.rb]", exts)
+	}
+}
+
+func TestRubyParser_Requires(t *testing.T) {
+	result := parseTestRuby(t)
+
+	if len(result.Imports) != 2 {
+		t.Fatalf("imports = %+v, want 2", result.Imports)
+	}
+	if imp := result.Imports[0]; imp.Path != "json" || imp.IsRelative {
+		t.Errorf("import 0 = %+v, want json", imp)
+	}
+	if imp := result.Imports[1]; imp.Path != "../support/formatter" || !imp.IsRelative {
+		t.Errorf("import 1 = %+v, want relative ../support/formatter", imp)
+	}
+	if result.Package != "Billing" {
+		t.Errorf("Package = %q, want Billing", result.Package)
+	}
+}
+
+func TestRubyParser_ModulesAndClasses(t *testing.T) {
+	result := parseTestRuby(t)
+
+	billing := kotlinTopLevel(t, result, "Billing")
+	if billing.Kind != SymbolKindClass || billing.Signature != "module Billing" || !billing.Metadata.IsAbstract {
+		t.Errorf("Billing = %v %q %+v", billing.Kind, billing.Signature, billing.Metadata)
+	}
+
+	payable := kotlinTopLevel(t, result, "Payable")
+	if payable.Package != "Billing" || payable.DocComment != "Adds payment helpers." {
+		t.Errorf("Payable package %q doc %q", payable.Package, payable.DocComment)
+	}
+	if len(payable.Metadata.Methods) != 1 || payable.Metadata.Methods[0].Name != "pay!" {
+		t.Errorf("Payable methods = %+v", payable.Metadata.Methods)
+	}
+
+	invoice := kotlinTopLevel(t, result, "Invoice")
+	meta := invoice.Metadata
+	if invoice.Signature != "class Invoice < ApplicationRecord" || invoice.Package != "Billing" || meta.IsAbstract {
+		t.Errorf("Invoice = %q package %q %+v", invoice.Signature, invoice.Package, meta)
+	}
+	if meta.Extends != "ApplicationRecord" {
+		t.Errorf("Extends = %q, want ApplicationRecord", meta.Extends)
+	}
+	if len(meta.Mixins) != 2 || meta.Mixins[0] != "Payable" || meta.Mixins[1] != "Searchable" {
+		t.Errorf("Mixins = %v, want [Payable Searchable]", meta.Mixins)
+	}
+	if len(meta.Methods) != 3 {
+		t.Errorf("Invoice methods = %+v, want total, secret and visible", meta.Methods)
+	}
+
+	if receipt := kotlinTopLevel(t, result, "Receipt"); receipt.Package != "Billing" {
+		t.Errorf("Receipt package = %q, want Billing", receipt.Package)
+	}
+}
+
+func TestRubyParser_Members(t *testing.T) {
+	result := parseTestRuby(t)
+	invoice := kotlinTopLevel(t, result, "Invoice")
+
+	for _, name := range []string{"amount", "due"} {
+		prop := kotlinChild(t, invoice, name)
+		if prop.Kind != SymbolKindProperty || prop.Receiver != "Invoice" || prop.Signature != "attr_accessor :"+name {
+			t.Errorf("%s = %v receiver %q %q", name, prop.Kind, prop.Receiver, prop.Signature)
+		}
+	}
+	if id := kotlinChild(t, invoice, "id"); id.Metadata.AccessModifier != "protected" || !id.Exported {
+		t.Errorf("id = exported %v %+v, want protected", id.Exported, id.Metadata)
+	}
+	if c := kotlinChild(t, invoice, "CURRENCY"); c.Kind != SymbolKindConstant || c.Signature != `CURRENCY = "USD"` {
+		t.Errorf("CURRENCY = %v %q", c.Kind, c.Signature)
+	}
+
+	ctor := kotlinChild(t, invoice, "initialize")
+	if ctor.Kind != SymbolKindMethod || ctor.Receiver != "Invoice" || !ctor.Metadata.IsConstructor {
+		t.Errorf("initialize = %v receiver %q %+v", ctor.Kind, ctor.Receiver, ctor.Metadata)
+	}
+	if total := kotlinChild(t, invoice, "total"); total.Signature != "def total(tax = 0)" || total.Metadata.IsStatic {
+		t.Errorf("total = %q %+v", total.Signature, total.Metadata)
+	}
+	for _, name := range []string{"build", "create"} {
+		if m := kotlinChild(t, invoice, name); !m.Metadata.IsStatic {
+			t.Errorf("%s should be a class method", name)
+		}
+	}
+	if secret := kotlinChild(t, invoice, "secret"); secret.Exported || secret.Metadata.AccessModifier != "private" {
+		t.Errorf("secret = exported %v %+v", secret.Exported, secret.Metadata)
+	}
+	if visible := kotlinChild(t, invoice, "visible"); !visible.Exported {
+		t.Error("public def visible should be exported")
+	}
+
+	topLevel := kotlinTopLevel(t, result, "top_level")
+	if topLevel.Kind != SymbolKindFunction || topLevel.Receiver != "" {
+		t.Errorf("top_level = %v receiver %q", topLevel.Kind, topLevel.Receiver)
+	}
+	if maxItems := kotlinTopLevel(t, result, "MAX_ITEMS"); maxItems.Kind != SymbolKindConstant {
+		t.Errorf("MAX_ITEMS kind = %v, want constant", maxItems.Kind)
+	}
+}
+
+func TestRubyParser_CallSites(t *testing.T) {
+	result := parseTestRuby(t)
+	invoice := kotlinTopLevel(t, result, "Invoice")
+
+	total := kotlinChild(t, invoice, "total")
+	for _, want := range []struct{ receiver, target string }{
+		{"self", "subtotal"}, {"Formatter", "format"}, {"self.ledger", "record"},
+		{"Tax", "rate"}, {"user", "save!"}, {"items", "each"}, {"", "process"}, {"super", "total"},
+	} {
+		if !hasCallSite(total.Calls, want.receiver, want.target) {
+			t.Errorf("total calls %+v missing %s.%s", total.Calls, want.receiver, want.target)
+		}
+	}
+	if build := kotlinChild(t, invoice, "build"); !hasCallSite(build.Calls, "", "Invoice") {
+		t.Errorf("build calls = %+v, want new Invoice", build.Calls)
+	}
+	if create := kotlinChild(t, invoice, "create"); !hasCallSite(create.Calls, "", "Invoice") {
+		t.Errorf("create calls = %+v, want Invoice.new", create.Calls)
+	}
+
+	payable := kotlinTopLevel(t, result, "Payable")
+	if pay := kotlinChild(t, payable, "pay!"); !hasCallSite(pay.Calls, "self", "charge") {
+		t.Errorf("pay! calls = %+v, want self.charge", pay.Calls)
+	}
+}
+
+func TestRubyParser_SyntaxErrorReported(t *testing.T) {
+	content := []byte(`class Good; end
+class
+  def (
+`)
+	result, err := NewRubyParser().Parse(context.Background(), content, "bad.rb")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) == 0 {
+		t.Error("expected a syntax error to be reported")
+	}
+}
+
+func TestRubyParser_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewRubyParser().Parse(ctx, []byte(testRubySource), "invoice.rb"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
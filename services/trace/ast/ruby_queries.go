@@ -0,0 +1,109 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+// Ruby Tree-sitter Node Types
+//
+// This file documents the tree-sitter node types used by RubyParser for symbol extraction.
+//
+// Reference: https://github.com/tree-sitter/tree-sitter-ruby
+
+// Node type constants for Ruby AST traversal.
+const (
+	// Top-level nodes
+	rubyNodeProgram       = "program"
+	rubyNodeBodyStatement = "body_statement"
+	rubyNodeComment       = "comment"
+
+	// Declaration nodes
+	rubyNodeClass           = "class"
+	rubyNodeModule          = "module"
+	rubyNodeSingletonClass  = "singleton_class"
+	rubyNodeMethod          = "method"
+	rubyNodeSingletonMethod = "singleton_method"
+	rubyNodeSuperclass      = "superclass"
+	rubyNodeAssignment      = "assignment"
+
+	// Names and literals
+	rubyNodeConstant        = "constant"
+	rubyNodeScopeResolution = "scope_resolution"
+	rubyNodeIdentifier      = "identifier"
+	rubyNodeSelf            = "self"
+	rubyNodeSuper           = "super"
+	rubyNodeInstanceVar     = "instance_variable"
+	rubyNodeSimpleSymbol    = "simple_symbol"
+	rubyNodeString          = "string"
+	rubyNodeStringContent   = "string_content"
+
+	// Expressions
+	rubyNodeCall         = "call"
+	rubyNodeArgumentList = "argument_list"
+
+	// Errors
+	rubyNodeERROR = "ERROR"
+)
+
+// RubyNodeTypes maps symbol kinds to the tree-sitter node types that produce them.
+var RubyNodeTypes = map[SymbolKind][]string{
+	SymbolKindClass:    {rubyNodeClass, rubyNodeModule},
+	SymbolKindFunction: {rubyNodeMethod},
+	SymbolKindMethod:   {rubyNodeMethod, rubyNodeSingletonMethod},
+	SymbolKindProperty: {rubyNodeCall},
+	SymbolKindConstant: {rubyNodeAssignment},
+}
+
+// Ruby AST Structure Reference
+//
+// program
+// ├── call                                  require "json"
+// │   ├── method: identifier (require | require_relative)
+// │   └── arguments: argument_list
+// │       └── string
+// │           └── string_content
+// ├── module
+// │   ├── name: constant | scope_resolution (Billing, Billing::Tax)
+// │   └── body: body_statement
+// ├── class
+// │   ├── name: constant | scope_resolution
+// │   ├── superclass: superclass ("<" constant | scope_resolution)
+// │   └── body: body_statement
+// │       ├── call                          include Payable, attr_reader :id
+// │       │   ├── method: identifier
+// │       │   └── arguments: argument_list
+// │       │       └── constant | scope_resolution | simple_symbol | method
+// │       ├── identifier                    private (bare visibility keyword)
+// │       ├── assignment                    CURRENCY = "USD"
+// │       │   ├── left: constant
+// │       │   └── right: <expression>
+// │       ├── method
+// │       │   ├── name: identifier
+// │       │   ├── parameters: method_parameters    [optional]
+// │       │   └── body: body_statement             [optional]
+// │       ├── singleton_method               def self.build(x)
+// │       │   ├── object: self | constant
+// │       │   ├── name: identifier
+// │       │   ├── parameters: method_parameters
+// │       │   └── body: body_statement
+// │       └── singleton_class                class << self
+// │           ├── value: self
+// │           └── body: body_statement
+// └── method                                 top-level def
+//
+// Calls:
+//
+// call                                      self.log(x), Formatter.format(a), user&.save!
+// ├── receiver: self | constant | scope_resolution | identifier
+// │             | instance_variable | call      [absent for helper(x)]
+// ├── operator: "." | "&."
+// ├── method: identifier | super            super(x) has method: super
+// ├── arguments: argument_list                 [optional]
+// └── block: block | do_block                  [optional]
+// super                                     bare super (forwards all arguments)
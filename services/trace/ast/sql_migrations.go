@@ -0,0 +1,213 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+// Goose migration annotations. See https://github.com/pressly/goose.
+const (
+	gooseUp             = "-- +goose Up"
+	gooseDown           = "-- +goose Down"
+	gooseStatementBegin = "-- +goose StatementBegin"
+	gooseStatementEnd   = "-- +goose StatementEnd"
+)
+
+// isDownMigration reports whether filePath is a golang-migrate down
+// migration ("000001_create_users.down.sql").
+//
+// Description:
+//
+//	Down migrations undo schema changes, so symbols declared in them (for
+//	example a CREATE TABLE restoring a dropped table) describe past schema
+//	and are not extracted.
+func isDownMigration(filePath string) bool {
+	return strings.HasSuffix(strings.ToLower(filepath.Base(filePath)), ".down.sql")
+}
+
+// blankGooseDown returns content with every goose Down section blanked.
+//
+// Description:
+//
+//	A Down section runs from a "-- +goose Down" line to the next
+//	"-- +goose Up" line or end of file. Its bytes are replaced with spaces,
+//	keeping newlines, so line numbers of the remaining statements are
+//	unchanged. Content without goose annotations is returned as is.
+func blankGooseDown(content []byte) []byte {
+	if !bytes.Contains(content, []byte(gooseDown)) {
+		return content
+	}
+
+	out := make([]byte, len(content))
+	copy(out, content)
+	inDown := false
+	for start := 0; start < len(out); {
+		end := bytes.IndexByte(out[start:], '\n')
+		if end < 0 {
+			end = len(out)
+		} else {
+			end += start
+		}
+		line := strings.TrimSpace(string(content[start:end]))
+		switch {
+		case strings.HasPrefix(line, gooseDown):
+			inDown = true
+		case strings.HasPrefix(line, gooseUp):
+			inDown = false
+		}
+		if inDown {
+			for i := start; i < end; i++ {
+				if out[i] != '\r' {
+					out[i] = ' '
+				}
+			}
+		}
+		start = end + 1
+	}
+	return out
+}
+
+// sqlSpan is a half-open byte range [start, end) of one SQL statement.
+type sqlSpan struct {
+	start, end int
+}
+
+// splitSQLStatements splits content[start:end] into statements.
+//
+// Description:
+//
+//	Splits on semicolons outside string literals, quoted identifiers,
+//	comments, and PostgreSQL dollar-quoted bodies ($$ ... $$ or
+//	$tag$ ... $tag$). Semicolons between goose StatementBegin and
+//	StatementEnd annotations do not split. Spans that contain only
+//	whitespace and comments are dropped. The trailing semicolon is
+//	excluded from each span.
+func splitSQLStatements(content []byte, start, end int) []sqlSpan {
+	var spans []sqlSpan
+	stmtStart := start
+	hasCode := false
+	inGooseBlock := false
+
+	flush := func(at int) {
+		if hasCode {
+			spans = append(spans, sqlSpan{start: stmtStart, end: at})
+		}
+		stmtStart = at + 1
+		hasCode = false
+	}
+
+	for i := start; i < end; i++ {
+		c := content[i]
+		switch {
+		case c == '-' && i+1 < end && content[i+1] == '-':
+			lineEnd := bytes.IndexByte(content[i:end], '\n')
+			if lineEnd < 0 {
+				lineEnd = end
+			} else {
+				lineEnd += i
+			}
+			comment := strings.TrimSpace(string(content[i:lineEnd]))
+			switch {
+			case strings.HasPrefix(comment, gooseStatementBegin):
+				inGooseBlock = true
+			case strings.HasPrefix(comment, gooseStatementEnd):
+				inGooseBlock = false
+				flush(lineEnd)
+				i = lineEnd
+				continue
+			}
+			if !hasCode {
+				stmtStart = lineEnd + 1
+			}
+			i = lineEnd
+		case c == '/' && i+1 < end && content[i+1] == '*':
+			closeAt := bytes.Index(content[i+2:end], []byte("*/"))
+			if closeAt < 0 {
+				i = end
+			} else {
+				i += closeAt + 3
+			}
+		case c == '\'' || c == '"' || c == '`':
+			hasCode = true
+			i = skipQuoted(content, i, end, c)
+		case c == '$':
+			hasCode = true
+			if tag := dollarQuoteTag(content, i, end); tag != "" {
+				closeAt := bytes.Index(content[i+len(tag):end], []byte(tag))
+				if closeAt < 0 {
+					i = end
+				} else {
+					i += len(tag) + closeAt + len(tag) - 1
+				}
+			}
+		case c == ';' && !inGooseBlock:
+			flush(i)
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if !hasCode {
+				stmtStart = i + 1
+			}
+		default:
+			hasCode = true
+		}
+	}
+	if hasCode {
+		spans = append(spans, sqlSpan{start: stmtStart, end: end})
+	}
+	return spans
+}
+
+// skipQuoted returns the index of the quote closing the literal that opens
+// at content[i]. A doubled quote is an escaped quote. Returns end-1 if the
+// literal is unterminated.
+func skipQuoted(content []byte, i, end int, quote byte) int {
+	for j := i + 1; j < end; j++ {
+		if content[j] != quote {
+			continue
+		}
+		if j+1 < end && content[j+1] == quote {
+			j++
+			continue
+		}
+		return j
+	}
+	return end - 1
+}
+
+// dollarQuoteTag returns the PostgreSQL dollar-quote tag ("$$" or "$tag$")
+// opening at content[i], or "" if there is none.
+func dollarQuoteTag(content []byte, i, end int) string {
+	for j := i + 1; j < end; j++ {
+		c := content[j]
+		switch {
+		case c == '$':
+			return string(content[i : j+1])
+		case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (j > i+1 && c >= '0' && c <= '9'):
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// unquoteSQLIdent strips one level of SQL identifier quoting
+// ("name", `name`, or [name]).
+func unquoteSQLIdent(s string) string {
+	if len(s) >= 2 {
+		first, last := s[0], s[len(s)-1]
+		if (first == '"' && last == '"') || (first == '`' && last == '`') || (first == '[' && last == ']') {
+			return s[1 : len(s)-1]
+		}
+	}
+	return s
+}
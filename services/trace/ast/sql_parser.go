@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -19,7 +20,14 @@ import (
 //
 //	SQLParser uses tree-sitter to parse SQL source files and extract
 //	structured symbol information including tables, columns, views,
-//	indexes, and constraints.
+//	indexes, and constraints. Columns added by ALTER TABLE ... ADD COLUMN
+//	are extracted as columns of the altered table.
+//
+//	Migration files are handled best-effort: golang-migrate down files
+//	(*.down.sql) and goose "-- +goose Down" sections yield no symbols.
+//	Statements tree-sitter cannot parse are re-parsed one at a time so
+//	a bad statement does not hide the ones after it, and each statement
+//	that still fails is reported in ParseResult.Errors.
 //
 // Thread Safety:
 //
@@ -166,6 +174,12 @@ func (p *SQLParser) Parse(ctx context.Context, content []byte, filePath string)
 		Errors:        make([]string, 0),
 	}
 
+	// Down migrations describe schema being removed, not declared.
+	if isDownMigration(filePath) {
+		return result, nil
+	}
+	content = blankGooseDown(content)
+
 	// Parse with tree-sitter
 	parser := sitter.NewParser()
 	parser.SetLanguage(sql.GetLanguage())
@@ -228,6 +242,12 @@ func (p *SQLParser) extractSymbols(ctx context.Context, node *sitter.Node, conte
 	case sqlNodeCreateView:
 		p.extractCreateView(node, content, filePath, result)
 
+	case sqlNodeAlterTable:
+		p.extractAlterTable(ctx, node, content, filePath, result)
+
+	case sqlNodeERROR:
+		p.recoverStatements(ctx, node, content, filePath, result)
+
 	case sqlNodeComment:
 		// SQL comments are not extracted as symbols - skip them
 
@@ -309,7 +329,12 @@ func (p *SQLParser) extractColumn(node *sitter.Node, content []byte, filePath, t
 		switch child.Type() {
 		case sqlNodeIdentifier:
 			if columnName == "" {
-				columnName = string(content[child.StartByte():child.EndByte()])
+				columnName = unquoteSQLIdent(string(content[child.StartByte():child.EndByte()]))
+			}
+		case sqlNodeLiteral:
+			// Double-quoted column names parse as literals.
+			if columnName == "" && i == 0 && content[child.StartByte()] == '"' {
+				columnName = unquoteSQLIdent(string(content[child.StartByte():child.EndByte()]))
 			}
 		case sqlNodeInt, sqlNodeVarchar, sqlNodeDecimal, sqlNodeTimestamp, sqlNodeDate, sqlNodeBool, sqlNodeText, sqlNodeBlob:
 			dataType = p.extractDataType(child, content)
@@ -512,12 +537,112 @@ func (p *SQLParser) extractCreateView(node *sitter.Node, content []byte, filePat
 }
 
 // extractIdentifier extracts an identifier from an object_reference node.
+//
+// For schema-qualified names ("public.users") the last identifier is the
+// object name. Identifier quoting is removed.
 func (p *SQLParser) extractIdentifier(node *sitter.Node, content []byte) string {
+	name := ""
 	for i := 0; i < int(node.ChildCount()); i++ {
 		child := node.Child(i)
 		if child.Type() == sqlNodeIdentifier {
-			return string(content[child.StartByte():child.EndByte()])
+			name = string(content[child.StartByte():child.EndByte()])
+		}
+	}
+	return unquoteSQLIdent(name)
+}
+
+// extractAlterTable extracts columns added by ALTER TABLE ... ADD COLUMN.
+func (p *SQLParser) extractAlterTable(ctx context.Context, node *sitter.Node, content []byte, filePath string, result *ParseResult) {
+	if !p.options.ExtractColumns {
+		return
+	}
+
+	tableName := ""
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			return
+		}
+		child := node.Child(i)
+		switch child.Type() {
+		case sqlNodeObjectReference:
+			tableName = p.extractIdentifier(child, content)
+		case sqlNodeAddColumn:
+			if tableName == "" {
+				continue
+			}
+			for j := 0; j < int(child.ChildCount()); j++ {
+				if def := child.Child(j); def.Type() == sqlNodeColumnDefinition {
+					p.extractColumn(def, content, filePath, tableName, result)
+				}
+			}
+		}
+	}
+}
+
+// recoverStatements re-parses the statements covered by an ERROR node.
+//
+// Description:
+//
+//	tree-sitter's error recovery can fold several valid statements that
+//	follow a bad one into a single ERROR node. Each statement in the
+//	node's range is parsed on its own, restricted to its byte range of
+//	the original content so positions stay absolute. Statements that
+//	parse cleanly are extracted normally; the rest are reported in
+//	result.Errors with their line number.
+func (p *SQLParser) recoverStatements(ctx context.Context, node *sitter.Node, content []byte, filePath string, result *ParseResult) {
+	lineStarts := sqlLineStarts(content)
+	parser := sitter.NewParser()
+	parser.SetLanguage(sql.GetLanguage())
+
+	for _, span := range splitSQLStatements(content, int(node.StartByte()), int(node.EndByte())) {
+		if ctx.Err() != nil {
+			return
 		}
+
+		parser.SetIncludedRanges([]sitter.Range{{
+			StartPoint: sqlPointAt(lineStarts, span.start),
+			EndPoint:   sqlPointAt(lineStarts, span.end),
+			StartByte:  uint32(span.start),
+			EndByte:    uint32(span.end),
+		}})
+		tree, err := parser.ParseCtx(ctx, nil, content)
+		if err != nil {
+			return
+		}
+		root := tree.RootNode()
+		if root.HasError() {
+			line := int(sqlPointAt(lineStarts, span.start).Row) + 1
+			result.Errors = append(result.Errors,
+				fmt.Sprintf("%s:%d: unparseable SQL statement: %s", filePath, line, sqlSnippet(content[span.start:span.end])))
+		} else {
+			p.extractSymbols(ctx, root, content, filePath, result)
+		}
+		tree.Close()
+	}
+}
+
+// sqlLineStarts returns the byte offset at which each line begins.
+func sqlLineStarts(content []byte) []int {
+	starts := []int{0}
+	for i, c := range content {
+		if c == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// sqlPointAt converts a byte offset to a tree-sitter point.
+func sqlPointAt(lineStarts []int, offset int) sitter.Point {
+	row := sort.SearchInts(lineStarts, offset+1) - 1
+	return sitter.Point{Row: uint32(row), Column: uint32(offset - lineStarts[row])}
+}
+
+// sqlSnippet returns the first line of a statement, shortened for errors.
+func sqlSnippet(stmt []byte) string {
+	s := strings.Join(strings.Fields(string(stmt)), " ")
+	if len(s) > 60 {
+		s = s[:60] + "..."
 	}
-	return ""
+	return s
 }
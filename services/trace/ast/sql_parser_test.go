@@ -755,3 +755,160 @@ func TestSQLParser_Parse_ViewQueryTruncation(t *testing.T) {
 		t.Errorf("view signature too long (%d chars), should be truncated", len(view.Signature))
 	}
 }
+
+// sqlSymbolNames returns "kind:name" for every symbol in result.
+func sqlSymbolNames(result *ParseResult) map[string]bool {
+	names := make(map[string]bool)
+	for _, sym := range result.Symbols {
+		names[sym.Kind.String()+":"+sym.Name] = true
+	}
+	return names
+}
+
+func TestSQLParser_Parse_GooseMigration(t *testing.T) {
+	parser := NewSQLParser()
+	ctx := context.Background()
+
+	content := []byte(`-- +goose Up
+CREATE TABLE users (
+    id BIGINT PRIMARY KEY,
+    email TEXT NOT NULL
+);
+
+-- +goose StatementBegin
+CREATE FUNCTION touch() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE INDEX idx_users_email ON users (email);
+
+-- +goose Down
+DROP TABLE users;
+CREATE TABLE legacy_users (id INT);
+`)
+
+	result, err := parser.Parse(ctx, content, "migrations/00001_users.sql")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	names := sqlSymbolNames(result)
+	for _, want := range []string{"table:users", "column:email", "index:idx_users_email"} {
+		if !names[want] {
+			t.Errorf("missing symbol %s; got %v", want, names)
+		}
+	}
+	if names["table:legacy_users"] {
+		t.Error("table from goose Down section should not be extracted")
+	}
+
+	for _, sym := range result.Symbols {
+		if sym.Kind == SymbolKindIndex && sym.StartLine != 16 {
+			t.Errorf("index StartLine = %d, want 16", sym.StartLine)
+		}
+	}
+}
+
+func TestSQLParser_Parse_DownMigrationSkipped(t *testing.T) {
+	parser := NewSQLParser()
+
+	content := []byte(`CREATE TABLE users (id INT);`)
+	result, err := parser.Parse(context.Background(), content, "db/migrations/000001_create_users.down.sql")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Symbols) != 0 {
+		t.Errorf("expected no symbols from down migration, got %d", len(result.Symbols))
+	}
+
+	result, err = parser.Parse(context.Background(), content, "db/migrations/000001_create_users.up.sql")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if !sqlSymbolNames(result)["table:users"] {
+		t.Error("expected users table from up migration")
+	}
+}
+
+func TestSQLParser_Parse_QualifiedAndQuotedNames(t *testing.T) {
+	parser := NewSQLParser()
+
+	content := []byte(`
+CREATE TABLE public.accounts (
+    id INT PRIMARY KEY,
+    "Display Name" TEXT
+);
+CREATE TABLE "order_items" (id INT);
+`)
+
+	result, err := parser.Parse(context.Background(), content, "schema.sql")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	names := sqlSymbolNames(result)
+	for _, want := range []string{"table:accounts", "table:order_items", "column:Display Name"} {
+		if !names[want] {
+			t.Errorf("missing symbol %s; got %v", want, names)
+		}
+	}
+}
+
+func TestSQLParser_Parse_AlterTableAddColumn(t *testing.T) {
+	parser := NewSQLParser()
+
+	content := []byte(`
+CREATE TABLE users (id INT PRIMARY KEY);
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP;
+`)
+
+	result, err := parser.Parse(context.Background(), content, "migrations/0002_soft_delete.up.sql")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var column *Symbol
+	for _, sym := range result.Symbols {
+		if sym.Kind == SymbolKindColumn && sym.Name == "deleted_at" {
+			column = sym
+		}
+	}
+	if column == nil {
+		t.Fatal("expected deleted_at column from ALTER TABLE")
+	}
+	if column.Metadata == nil || column.Metadata.ParentName != "users" {
+		t.Errorf("deleted_at ParentName = %+v, want users", column.Metadata)
+	}
+}
+
+func TestSQLParser_Parse_UnparseableStatementRecovered(t *testing.T) {
+	parser := NewSQLParser()
+
+	content := []byte(`CREATE TABLE before_bad (id INT);
+CREATE TABLEX oops;
+CREATE TABLE after_bad (id INT);
+`)
+
+	result, err := parser.Parse(context.Background(), content, "schema.sql")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	names := sqlSymbolNames(result)
+	for _, want := range []string{"table:before_bad", "table:after_bad"} {
+		if !names[want] {
+			t.Errorf("missing symbol %s; got %v", want, names)
+		}
+	}
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("expected 1 error, got %d: %v", len(result.Errors), result.Errors)
+	}
+	if !strings.Contains(result.Errors[0], "schema.sql:2:") || !strings.Contains(result.Errors[0], "TABLEX") {
+		t.Errorf("error should name file, line and statement: %q", result.Errors[0])
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// MaxSQLTableRefsPerSymbol caps SymbolMetadata.SQLTableRefs per symbol.
+const MaxSQLTableRefsPerSymbol = 32
+
+// sqlIdentPattern matches a plain or quoted SQL identifier.
+const sqlIdentPattern = "(?:\"[^\"]+\"|`[^`]+`|[A-Za-z_][A-Za-z0-9_$]*)"
+
+var (
+	// sqlStatementPatterns recognise string literals that are SQL queries.
+	// Each requires the statement's structural keywords so ordinary strings
+	// such as "update the cache" or "read from disk" do not match.
+	sqlStatementPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?is)^\s*select\b.*\bfrom\b`),
+		regexp.MustCompile(`(?is)^\s*insert\s+(?:ignore\s+)?into\b`),
+		regexp.MustCompile(`(?is)^\s*update\s+\S+\s+(?:as\s+\S+\s+)?set\b`),
+		regexp.MustCompile(`(?is)^\s*delete\s+from\b`),
+		regexp.MustCompile(`(?is)^\s*with\s+(?:recursive\s+)?\w+\s*(?:\([^)]*\)\s*)?as\s*\(`),
+		regexp.MustCompile(`(?is)^\s*(?:replace|merge)\s+into\b`),
+		regexp.MustCompile(`(?is)^\s*truncate\s+(?:table\s+)?\w`),
+	}
+
+	// sqlTableRefPattern captures the table after FROM, JOIN, INTO, UPDATE
+	// and TRUNCATE [TABLE], skipping PostgreSQL's ONLY. For a
+	// schema-qualified name, group 1 is the schema and group 2 the table.
+	sqlTableRefPattern = regexp.MustCompile(`(?i)\b(?:from|join|into|update|truncate(?:\s+table)?)\s+(?:only\s+)?(` +
+		sqlIdentPattern + `)(?:\s*\.\s*(` + sqlIdentPattern + `))?`)
+)
+
+// ExtractSQLTableRefs returns the tables a SQL query string reads or writes.
+//
+// Description:
+//
+//	Best-effort: if query looks like a SELECT, INSERT, UPDATE, DELETE,
+//	WITH, MERGE/REPLACE or TRUNCATE statement, returns the identifiers
+//	following FROM, JOIN, INTO, UPDATE and TRUNCATE, unquoted and without
+//	schema qualifiers, in first-seen order without duplicates (compared
+//	case-insensitively). CTE names and table functions may appear in the
+//	result; callers resolve names against known tables.
+//
+// Inputs:
+//
+//	query - The string literal's value.
+//
+// Outputs:
+//
+//	[]string - Referenced table names. Nil if query is not SQL.
+func ExtractSQLTableRefs(query string) []string {
+	if !looksLikeSQL(query) {
+		return nil
+	}
+
+	var refs []string
+	seen := make(map[string]bool)
+	for _, m := range sqlTableRefPattern.FindAllStringSubmatch(query, -1) {
+		name := m[1]
+		if m[2] != "" {
+			name = m[2]
+		}
+		name = unquoteSQLIdent(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] || sqlRefStopWords[key] {
+			continue
+		}
+		seen[key] = true
+		refs = append(refs, name)
+	}
+	return refs
+}
+
+// sqlRefStopWords are words that can follow FROM/INTO/UPDATE without
+// naming a table: SQL keywords ("SELECT ... FROM LATERAL") and the English
+// determiners of prose that slips past sqlStatementPatterns ("select a
+// value from the list").
+var sqlRefStopWords = map[string]bool{
+	"only": true, "lateral": true, "select": true, "unnest": true, "table": true,
+	"the": true, "a": true, "an": true, "this": true, "that": true, "these": true,
+	"those": true, "its": true, "their": true, "your": true, "our": true,
+}
+
+// looksLikeSQL reports whether s matches one of sqlStatementPatterns.
+func looksLikeSQL(s string) bool {
+	for _, re := range sqlStatementPatterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// StructTagColumn returns the database column named by a Go struct tag.
+//
+// Description:
+//
+//	Reads `db:"name"` (sqlx and friends) first, then gorm's
+//	`gorm:"column:name"`. Options after a comma in db tags are ignored and
+//	db:"-" means no column.
+//
+// Inputs:
+//
+//	tag - The tag as written in source, including its quotes or backticks.
+//
+// Outputs:
+//
+//	string - The column name, or "" if the tag names none.
+func StructTagColumn(tag string) string {
+	unquoted, err := strconv.Unquote(tag)
+	if err != nil {
+		return ""
+	}
+	st := reflect.StructTag(unquoted)
+
+	if db, ok := st.Lookup("db"); ok {
+		name, _, _ := strings.Cut(db, ",")
+		if name == "-" {
+			return ""
+		}
+		return strings.TrimSpace(name)
+	}
+	if gorm, ok := st.Lookup("gorm"); ok {
+		for _, opt := range strings.Split(gorm, ";") {
+			key, value, found := strings.Cut(strings.TrimSpace(opt), ":")
+			if found && strings.EqualFold(key, "column") {
+				return strings.TrimSpace(value)
+			}
+		}
+	}
+	return ""
+}
+
+// extractGoSQLTableRefs collects SQL table references from the string
+// literals in a Go function body.
+//
+// Description:
+//
+//	Walks bodyNode iteratively (bounded by MaxCallExpressionDepth) and runs
+//	ExtractSQLTableRefs on every interpreted and raw string literal.
+//	Queries assembled by concatenation are matched per literal.
+//
+// Outputs:
+//
+//	[]string - Deduplicated table names, at most MaxSQLTableRefsPerSymbol.
+func extractGoSQLTableRefs(ctx context.Context, bodyNode *sitter.Node, content []byte) []string {
+	if bodyNode == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	type stackEntry struct {
+		node  *sitter.Node
+		depth int
+	}
+
+	var refs []string
+	seen := make(map[string]bool)
+	stack := []stackEntry{{node: bodyNode}}
+	for len(stack) > 0 && len(refs) < MaxSQLTableRefsPerSymbol {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := entry.node
+		switch node.Type() {
+		case "interpreted_string_literal", "raw_string_literal":
+			value, err := strconv.Unquote(string(content[node.StartByte():node.EndByte()]))
+			if err != nil {
+				continue
+			}
+			for _, ref := range ExtractSQLTableRefs(value) {
+				key := strings.ToLower(ref)
+				if !seen[key] && len(refs) < MaxSQLTableRefsPerSymbol {
+					seen[key] = true
+					refs = append(refs, ref)
+				}
+			}
+			continue
+		}
+
+		if entry.depth >= MaxCallExpressionDepth {
+			continue
+		}
+		for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+			stack = append(stack, stackEntry{node: node.Child(i), depth: entry.depth + 1})
+		}
+	}
+	return refs
+}
+
+// setGoSQLTableRefs records the SQL tables a Go function body queries in
+// sym.Metadata.SQLTableRefs. Metadata is only allocated when there is
+// something to record.
+func setGoSQLTableRefs(ctx context.Context, sym *Symbol, bodyNode *sitter.Node, content []byte) {
+	refs := extractGoSQLTableRefs(ctx, bodyNode, content)
+	if len(refs) == 0 {
+		return
+	}
+	if sym.Metadata == nil {
+		sym.Metadata = &SymbolMetadata{}
+	}
+	sym.Metadata.SQLTableRefs = refs
+}
@@ -0,0 +1,114 @@
+package ast
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestExtractSQLTableRefs(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"select", "SELECT id, email FROM users WHERE id = $1", []string{"users"}},
+		{"join", "SELECT u.id FROM users u JOIN orders o ON o.user_id = u.id LEFT JOIN Users x ON true", []string{"users", "orders"}},
+		{"insert", "INSERT INTO audit_log (msg) VALUES (?)", []string{"audit_log"}},
+		{"update", "UPDATE accounts SET balance = balance - $1", []string{"accounts"}},
+		{"delete only", "DELETE FROM ONLY sessions WHERE expired", []string{"sessions"}},
+		{"schema qualified", `SELECT * FROM public."order_items"`, []string{"order_items"}},
+		{"truncate", "TRUNCATE TABLE events", []string{"events"}},
+		{"plain prose", "update the cache from disk", nil},
+		{"not sql", "reading from the network", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractSQLTableRefs(tt.query)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractSQLTableRefs(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructTagColumn(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"`db:\"email\"`", "email"},
+		{"`json:\"id\" db:\"user_id,omitempty\"`", "user_id"},
+		{"`db:\"-\"`", ""},
+		{"`gorm:\"primaryKey;column:created_at\"`", "created_at"},
+		{"`json:\"name\"`", ""},
+		{"\"db:\\\"quoted\\\"\"", "quoted"},
+		{"not a tag", ""},
+	}
+	for _, tt := range tests {
+		if got := StructTagColumn(tt.tag); got != tt.want {
+			t.Errorf("StructTagColumn(%s) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestGoParser_SQLMetadata(t *testing.T) {
+	content := []byte("package store\n" + `
+type User struct {
+	ID    int64  ` + "`db:\"id\"`" + `
+	Email string ` + "`db:\"email\"`" + `
+	Name  string
+}
+
+const listUsers = "SELECT id FROM users"
+
+func (s *Store) ActiveUsers() error {
+	_, err := s.db.Query(` + "`SELECT u.id FROM users u JOIN sessions s ON s.user_id = u.id`" + `)
+	return err
+}
+
+func Log(msg string) {
+	println("select a value from the list")
+}
+`)
+
+	result, err := NewGoParser().Parse(context.Background(), content, "store/user.go")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	var user, active, logFn *Symbol
+	for _, sym := range result.Symbols {
+		switch sym.Name {
+		case "User":
+			user = sym
+		case "ActiveUsers":
+			active = sym
+		case "Log":
+			logFn = sym
+		}
+	}
+	if user == nil || active == nil || logFn == nil {
+		t.Fatal("expected User, ActiveUsers and Log symbols")
+	}
+
+	columns := make(map[string]string)
+	for _, field := range user.Children {
+		if field.Metadata != nil {
+			columns[field.Name] = field.Metadata.DBColumn
+		}
+	}
+	if columns["ID"] != "id" || columns["Email"] != "email" {
+		t.Errorf("field DB columns = %v, want ID->id, Email->email", columns)
+	}
+	if _, ok := columns["Name"]; ok {
+		t.Errorf("untagged field Name should have no metadata, got %q", columns["Name"])
+	}
+
+	if active.Metadata == nil || !reflect.DeepEqual(active.Metadata.SQLTableRefs, []string{"users", "sessions"}) {
+		t.Errorf("ActiveUsers SQLTableRefs = %+v, want [users sessions]", active.Metadata)
+	}
+	if logFn.Metadata != nil && len(logFn.Metadata.SQLTableRefs) != 0 {
+		t.Errorf("Log should have no SQL refs, got %v", logFn.Metadata.SQLTableRefs)
+	}
+}
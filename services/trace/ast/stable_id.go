@@ -0,0 +1,188 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// stableIDHashLen is the number of hex characters of the signature hash
+// kept in a stable ID.
+const stableIDHashLen = 8
+
+// GenerateStableID creates a line-independent identifier for a symbol.
+//
+// Format: "file_path::Scope.Chain.name@sighash"
+//
+// Example: "handlers/agent.go::AgentHandler.HandleAgent@3fa2b1c0"
+//
+// Unlike GenerateID, the result does not change when code above the
+// symbol moves it to another line. sighash is the first 8 hex characters
+// of the SHA-256 of the kind and whitespace-normalized signature, so
+// overloads with different signatures get different IDs. Symbols that
+// still collide are disambiguated by AssignStableIDs.
+//
+// Parameters:
+//   - filePath: Path relative to project root.
+//   - scope: Names of the enclosing symbols, outermost first. May be nil.
+//   - name: The symbol's identifier name.
+//   - kind: The symbol's kind.
+//   - signature: The symbol's signature. May be empty.
+func GenerateStableID(filePath string, scope []string, name string, kind SymbolKind, signature string) string {
+	h := sha256.New()
+	h.Write([]byte(kind.String()))
+	h.Write([]byte{0})
+	h.Write([]byte(strings.Join(strings.Fields(signature), " ")))
+	sum := hex.EncodeToString(h.Sum(nil))[:stableIDHashLen]
+
+	var b strings.Builder
+	b.WriteString(filePath)
+	b.WriteString("::")
+	for _, s := range scope {
+		b.WriteString(s)
+		b.WriteByte('.')
+	}
+	b.WriteString(name)
+	b.WriteByte('@')
+	b.WriteString(sum)
+	return b.String()
+}
+
+// AssignStableIDs sets StableID on every symbol in symbols and their
+// children.
+//
+// Description:
+//
+//	Symbols are grouped by file. A symbol's scope chain is the names of
+//	the symbols whose source range strictly encloses it, outermost
+//	first; a Go method with no enclosing symbol is scoped by its
+//	receiver. Scope is derived from ranges rather than from the Children
+//	tree, so a flat symbol list (as stored in graph snapshots) gets the
+//	same IDs as the tree it came from. When two symbols in a file
+//	produce the same ID, the first in source order keeps it and the rest
+//	get ".2", ".3", ... appended.
+//
+//	Files whose symbols all have a StableID already are left untouched,
+//	so calling this again on shared symbols does not write to them.
+//
+// Inputs:
+//
+//	symbols - Top-level symbols. Nil entries are skipped. Symbols that
+//	  appear more than once (by ID) are assigned once.
+//
+// Thread Safety: Not safe for concurrent use on the same symbols.
+func AssignStableIDs(symbols []*Symbol) {
+	byFile := make(map[string][]*Symbol)
+	seen := make(map[*Symbol]bool)
+	var collect func([]*Symbol)
+	collect = func(syms []*Symbol) {
+		for _, sym := range syms {
+			if sym == nil || seen[sym] {
+				continue
+			}
+			seen[sym] = true
+			byFile[sym.FilePath] = append(byFile[sym.FilePath], sym)
+			collect(sym.Children)
+		}
+	}
+	collect(symbols)
+
+	for _, fileSyms := range byFile {
+		assignFileStableIDs(fileSyms)
+	}
+}
+
+// assignFileStableIDs assigns stable IDs to the symbols of one file.
+func assignFileStableIDs(syms []*Symbol) {
+	missing := false
+	for _, sym := range syms {
+		if sym.StableID == "" {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return
+	}
+
+	// Source order, enclosing symbols before the symbols they contain.
+	sort.SliceStable(syms, func(i, j int) bool {
+		a, b := syms[i], syms[j]
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		if a.StartCol != b.StartCol {
+			return a.StartCol < b.StartCol
+		}
+		if a.EndLine != b.EndLine {
+			return a.EndLine > b.EndLine
+		}
+		if a.EndCol != b.EndCol {
+			return a.EndCol > b.EndCol
+		}
+		return a.ID < b.ID
+	})
+
+	// The same symbol may appear twice as distinct objects (for example
+	// top-level and as a child); both get the same ID.
+	assigned := make(map[string]string, len(syms))
+	counts := make(map[string]int, len(syms))
+	var open []*Symbol
+	for _, sym := range syms {
+		for len(open) > 0 && !encloses(open[len(open)-1], sym) {
+			open = open[:len(open)-1]
+		}
+		if id, ok := assigned[sym.ID]; ok && sym.ID != "" {
+			sym.StableID = id
+			open = append(open, sym)
+			continue
+		}
+
+		var scope []string
+		for _, parent := range open {
+			if parent.Name != "" {
+				scope = append(scope, parent.Name)
+			}
+		}
+		if len(scope) == 0 && sym.Receiver != "" {
+			scope = []string{strings.TrimLeft(sym.Receiver, "*")}
+		}
+
+		id := GenerateStableID(sym.FilePath, scope, sym.Name, sym.Kind, sym.Signature)
+		counts[id]++
+		if n := counts[id]; n > 1 {
+			id += "." + strconv.Itoa(n)
+		}
+		sym.StableID = id
+		if sym.ID != "" {
+			assigned[sym.ID] = id
+		}
+		open = append(open, sym)
+	}
+}
+
+// encloses reports whether outer's source range contains inner's and is
+// strictly larger.
+func encloses(outer, inner *Symbol) bool {
+	startsBefore := outer.StartLine < inner.StartLine ||
+		(outer.StartLine == inner.StartLine && outer.StartCol <= inner.StartCol)
+	endsAfter := outer.EndLine > inner.EndLine ||
+		(outer.EndLine == inner.EndLine && outer.EndCol >= inner.EndCol)
+	if !startsBefore || !endsAfter {
+		return false
+	}
+	return outer.StartLine != inner.StartLine || outer.StartCol != inner.StartCol ||
+		outer.EndLine != inner.EndLine || outer.EndCol != inner.EndCol
+}
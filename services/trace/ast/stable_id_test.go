@@ -0,0 +1,136 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const stableIDGoSource = `package server
+
+type Server struct{}
+
+func (s *Server) Start() error { return nil }
+
+func New() *Server { return &Server{} }
+`
+
+// stableIDsByName parses content and returns StableID by symbol name.
+func stableIDsByName(t *testing.T, parser Parser, content, path string) map[string]string {
+	t.Helper()
+	result, err := parser.Parse(context.Background(), []byte(content), path)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	AssignStableIDs(result.Symbols)
+	ids := make(map[string]string)
+	var walk func([]*Symbol)
+	walk = func(syms []*Symbol) {
+		for _, sym := range syms {
+			if sym.StableID == "" {
+				t.Errorf("%s has no stable ID", sym.ID)
+			}
+			ids[sym.Name] = sym.StableID
+			walk(sym.Children)
+		}
+	}
+	walk(result.Symbols)
+	return ids
+}
+
+func TestAssignStableIDs_SurvivesLineShift(t *testing.T) {
+	before := stableIDsByName(t, NewGoParser(), stableIDGoSource, "server/server.go")
+	shifted := strings.Replace(stableIDGoSource, "package server\n", "package server\n\n// Package server starts things.\n// It has a long comment.\n", 1)
+	after := stableIDsByName(t, NewGoParser(), shifted, "server/server.go")
+
+	for name, id := range before {
+		if after[name] != id {
+			t.Errorf("%s: stable ID changed from %q to %q", name, id, after[name])
+		}
+	}
+	if !strings.HasPrefix(before["Start"], "server/server.go::Server.Start@") {
+		t.Errorf("method stable ID = %q, want receiver scope", before["Start"])
+	}
+}
+
+func TestAssignStableIDs_ScopeAndCollisions(t *testing.T) {
+	source := `class Greeter:
+    def greet(self):
+        return "hi"
+
+def greet():
+    return "hello"
+
+def greet():
+    return "hello again"
+`
+	result, err := NewPythonParser().Parse(context.Background(), []byte(source), "greet.py")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	AssignStableIDs(result.Symbols)
+
+	var method string
+	var functions []string
+	var walk func([]*Symbol)
+	walk = func(syms []*Symbol) {
+		for _, sym := range syms {
+			if sym.Name == "greet" {
+				if sym.Kind == SymbolKindMethod {
+					method = sym.StableID
+				} else {
+					functions = append(functions, sym.StableID)
+				}
+			}
+			walk(sym.Children)
+		}
+	}
+	walk(result.Symbols)
+
+	if !strings.HasPrefix(method, "greet.py::Greeter.greet@") {
+		t.Errorf("method stable ID = %q, want class scope", method)
+	}
+	if len(functions) != 2 {
+		t.Fatalf("got %d greet functions, want 2", len(functions))
+	}
+	if functions[1] != functions[0]+".2" {
+		t.Errorf("redefinition stable ID = %q, want %q", functions[1], functions[0]+".2")
+	}
+}
+
+func TestAssignStableIDs_FlatListMatchesTree(t *testing.T) {
+	tree := stableIDsByName(t, NewPythonParser(), "class A:\n    def run(self):\n        pass\n", "a.py")
+
+	// A flat copy without Children, as graph snapshots store symbols.
+	result, err := NewPythonParser().Parse(context.Background(), []byte("class A:\n    def run(self):\n        pass\n"), "a.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var flat []*Symbol
+	var walk func([]*Symbol)
+	walk = func(syms []*Symbol) {
+		for _, sym := range syms {
+			c := *sym
+			c.Children = nil
+			flat = append(flat, &c)
+			walk(sym.Children)
+		}
+	}
+	walk(result.Symbols)
+	AssignStableIDs(flat)
+	for _, sym := range flat {
+		if sym.StableID != tree[sym.Name] {
+			t.Errorf("%s: flat %q, tree %q", sym.Name, sym.StableID, tree[sym.Name])
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/python"
+	"github.com/smacker/go-tree-sitter/typescript/tsx"
+	"github.com/smacker/go-tree-sitter/typescript/typescript"
+)
+
+// statementContainerNodes are the node types whose named children are
+// statements, members or elements: the places a long symbol can be split
+// without cutting a construct in half. The set covers the Go, Python,
+// JavaScript and TypeScript grammars.
+var statementContainerNodes = map[string]bool{
+	// File level.
+	"source_file": true,
+	"module":      true,
+	"program":     true,
+
+	// Statement lists.
+	"block":           true,
+	"statement_block": true,
+	"if_statement":    true,
+	"try_statement":   true,
+
+	// Switch arms.
+	"expression_switch_statement": true,
+	"type_switch_statement":       true,
+	"select_statement":            true,
+	"switch_body":                 true,
+	"switch_case":                 true,
+	"switch_default":              true,
+	"expression_case":             true,
+	"type_case":                   true,
+	"default_case":                true,
+	"communication_case":          true,
+
+	// Type and class members.
+	"class_body":             true,
+	"interface_body":         true,
+	"enum_body":              true,
+	"field_declaration_list": true,
+	"interface_type":         true,
+
+	// Grouped declarations.
+	"import_spec_list":  true,
+	"const_declaration": true,
+	"var_declaration":   true,
+
+	// Literals, e.g. large lookup tables.
+	"literal_value": true,
+	"object":        true,
+	"array":         true,
+	"list":          true,
+	"dictionary":    true,
+}
+
+// statementLanguage returns the tree-sitter grammar used to find the
+// statements of a file, or nil if the language is not supported.
+func statementLanguage(language, filePath string) *sitter.Language {
+	switch language {
+	case "go":
+		return golang.GetLanguage()
+	case "python":
+		return python.GetLanguage()
+	case "javascript":
+		return javascript.GetLanguage()
+	case "typescript":
+		if strings.HasSuffix(filePath, ".tsx") {
+			return tsx.GetLanguage()
+		}
+		return typescript.GetLanguage()
+	}
+	return nil
+}
+
+// StatementStarts returns the lines on which a statement begins.
+//
+// Description:
+//
+//	Parses content and collects the start line of every statement,
+//	member or literal element that is the first thing on its line,
+//	at any nesting depth. Splitting a range of lines just before one of
+//	these lines never cuts a statement that starts on that line, so the
+//	result serves as the split points for long symbols. Supports Go,
+//	Python, JavaScript and TypeScript.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	language - The file's language, as in Symbol.Language.
+//	filePath - The file path; selects TSX for .tsx files.
+//	content - The file content.
+//
+// Outputs:
+//
+//	[]int - Sorted, distinct 1-indexed line numbers.
+//	error - ErrUnsupportedLanguage for other languages, or a parse error.
+//
+// Thread Safety: Safe for concurrent use.
+func StatementStarts(ctx context.Context, language, filePath string, content []byte) ([]int, error) {
+	lang := statementLanguage(language, filePath)
+	if lang == nil {
+		return nil, fmt.Errorf("statement starts for %q: %w", language, ErrUnsupportedLanguage)
+	}
+
+	parser := sitter.NewParser()
+	parser.SetLanguage(lang)
+	tree, err := parser.ParseCtx(ctx, nil, content)
+	if err != nil {
+		return nil, fmt.Errorf("tree-sitter parse failed: %w", err)
+	}
+	defer tree.Close()
+
+	seen := make(map[int]bool)
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		container := statementContainerNodes[node.Type()]
+		for i := 0; i < int(node.ChildCount()); i++ {
+			child := node.Child(i)
+			if child == nil {
+				continue
+			}
+			if container && child.IsNamed() && startsLine(child, content) {
+				seen[int(child.StartPoint().Row)+1] = true
+			}
+			walk(child)
+		}
+	}
+	walk(tree.RootNode())
+
+	lines := make([]int, 0, len(seen))
+	for line := range seen {
+		lines = append(lines, line)
+	}
+	sort.Ints(lines)
+	return lines, nil
+}
+
+// startsLine reports whether only whitespace precedes node on its line.
+func startsLine(node *sitter.Node, content []byte) bool {
+	start := int(node.StartByte())
+	for i := start - 1; i >= 0 && i < len(content); i-- {
+		switch content[i] {
+		case '\n':
+			return true
+		case ' ', '\t', '\r':
+			continue
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestStatementStarts(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		path     string
+		src      string
+		want     []int
+	}{
+		{
+			name:     "go",
+			language: "go",
+			path:     "a.go",
+			src:      "package a\n\nfunc F(x int) int {\n\tif x > 0 {\n\t\tx++\n\t}\n\tswitch x {\n\tcase 1:\n\t\treturn 2\n\t}\n\treturn x\n}\n",
+			want:     []int{1, 3, 4, 5, 7, 8, 9, 11},
+		},
+		{
+			name:     "python",
+			language: "python",
+			path:     "a.py",
+			src:      "class A:\n    x = 1\n    def f(self):\n        if self.x:\n            return 1\n        elif y:\n            pass\n        return 2\n",
+			want:     []int{1, 2, 3, 4, 5, 6, 7, 8},
+		},
+		{
+			name:     "tsx",
+			language: "typescript",
+			path:     "a.tsx",
+			src:      "function f(x: number) {\n  const y = x + 1;\n  return <div>{y}</div>;\n}\n",
+			want:     []int{1, 2, 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StatementStarts(context.Background(), tt.language, tt.path, []byte(tt.src))
+			if err != nil {
+				t.Fatalf("StatementStarts: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("StatementStarts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := StatementStarts(context.Background(), "css", "a.css", []byte("a {}")); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("css err = %v, want ErrUnsupportedLanguage", err)
+	}
+}
@@ -0,0 +1,1062 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/swift"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// SwiftParserOption configures a SwiftParser instance.
+type SwiftParserOption func(*SwiftParser)
+
+// WithSwiftMaxFileSize sets the maximum file size the parser will accept.
+//
+// Parameters:
+//   - bytes: Maximum file size in bytes. Must be positive.
+//
+// Example:
+//
+//	parser := NewSwiftParser(WithSwiftMaxFileSize(5 * 1024 * 1024)) // 5MB limit
+func WithSwiftMaxFileSize(bytes int64) SwiftParserOption {
+	return func(p *SwiftParser) {
+		if bytes > 0 {
+			p.maxFileSize = bytes
+		}
+	}
+}
+
+// WithSwiftParseOptions applies the given ParseOptions to the parser.
+//
+// Parameters:
+//   - opts: ParseOptions to apply.
+//
+// Example:
+//
+//	parser := NewSwiftParser(WithSwiftParseOptions(ParseOptions{IncludePrivate: false}))
+func WithSwiftParseOptions(opts ParseOptions) SwiftParserOption {
+	return func(p *SwiftParser) {
+		p.parseOptions = opts
+	}
+}
+
+// SwiftParser implements the Parser interface for Swift source code.
+//
+// Description:
+//
+//	SwiftParser uses tree-sitter to parse .swift files and extract symbols,
+//	mapped onto the existing symbol kinds:
+//
+//	  class, actor     -> SymbolKindClass
+//	  struct           -> SymbolKindStruct
+//	  enum             -> SymbolKindEnum, cases as SymbolKindEnumMember
+//	                      children
+//	  protocol         -> SymbolKindInterface, with Metadata.Methods for
+//	                      implicit conformance detection
+//	  method, init     -> SymbolKindMethod, Receiver = the enclosing type
+//	  top-level func   -> SymbolKindFunction
+//	  stored property  -> SymbolKindField
+//	  computed or
+//	  protocol property -> SymbolKindProperty
+//	  top-level let/var -> SymbolKindConstant / SymbolKindVariable
+//	  typealias        -> SymbolKindType
+//
+//	An extension produces no symbol of its own. Its members become
+//	top-level methods with Receiver = the extended type, which the graph
+//	builder associates with that type across files, the way Go methods are
+//	associated with structs declared in another file. When the extended
+//	type is declared in the same file, the extension's methods are also
+//	added to its Metadata.Methods and its conformances to
+//	Metadata.Implements.
+//
+//	For classes the first inherited type is the superclass (Metadata.Extends)
+//	unless it is a protocol declared in the same file; the rest are
+//	conformances (Metadata.Implements). Structs and enums cannot inherit, so
+//	all their inherited types are conformances. async functions set
+//	Metadata.IsAsync; attributes are recorded in Metadata.Decorators.
+//
+// Thread Safety:
+//
+//	SwiftParser instances are safe for concurrent use. Each Parse call
+//	creates its own tree-sitter parser instance.
+//
+// Example:
+//
+//	parser := NewSwiftParser()
+//	result, err := parser.Parse(ctx, content, "Sources/App/UserStore.swift")
+//	if err != nil {
+//	    return fmt.Errorf("parse: %w", err)
+//	}
+//	for _, sym := range result.Symbols {
+//	    fmt.Printf("%s: %s\n", sym.Kind, sym.Name)
+//	}
+type SwiftParser struct {
+	maxFileSize  int64
+	parseOptions ParseOptions
+}
+
+// NewSwiftParser creates a new SwiftParser with the given options.
+//
+// Inputs:
+//   - opts: Optional configuration functions (WithSwiftMaxFileSize, WithSwiftParseOptions)
+//
+// Outputs:
+//   - *SwiftParser: Configured parser instance, never nil
+//
+// Thread Safety:
+//
+//	The returned SwiftParser is safe for concurrent use.
+func NewSwiftParser(opts ...SwiftParserOption) *SwiftParser {
+	p := &SwiftParser{
+		maxFileSize:  DefaultMaxFileSize,
+		parseOptions: DefaultParseOptions(),
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Language returns the canonical language name for this parser.
+func (p *SwiftParser) Language() string {
+	return "swift"
+}
+
+// Extensions returns the file extensions this parser handles.
+func (p *SwiftParser) Extensions() []string {
+	return []string{".swift"}
+}
+
+// swiftFile holds the per-file state of one Parse call.
+type swiftFile struct {
+	content  []byte
+	filePath string
+	lines    []string
+	// protocols are the names of the protocols declared in the file.
+	protocols map[string]bool
+}
+
+// swiftExtension records what an extension adds to the type it extends.
+type swiftExtension struct {
+	typeName     string
+	conformances []string
+	methods      []*Symbol
+}
+
+// Parse extracts symbols from Swift source code.
+//
+// Description:
+//
+//	Parses the provided Swift source with tree-sitter and extracts types,
+//	protocols, extensions, functions, properties and imports. The parser is
+//	error-tolerant: syntax errors are reported in ParseResult.Errors and the
+//	remaining declarations are still extracted.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked before and after parsing.
+//   - content: Raw Swift source bytes. Must be valid UTF-8.
+//   - filePath: Path to the file, relative to the project root.
+//
+// Outputs:
+//   - *ParseResult: Extracted symbols and metadata. Never nil on success.
+//   - error: Non-nil for complete failures:
+//   - ErrFileTooLarge: Content exceeds maxFileSize
+//   - ErrInvalidContent: Content is not valid UTF-8
+//   - Context errors: Context was canceled or timed out
+//
+// Thread Safety:
+//
+//	This method is safe for concurrent use.
+func (p *SwiftParser) Parse(ctx context.Context, content []byte, filePath string) (*ParseResult, error) {
+	ctx, span := startParseSpan(ctx, "swift", filePath, len(content))
+	defer span.End()
+
+	start := time.Now()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "swift", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled before start: %w", err)
+	}
+
+	if int64(len(content)) > p.maxFileSize {
+		recordParseMetrics(ctx, "swift", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: size %d exceeds limit %d", ErrFileTooLarge, len(content), p.maxFileSize)
+	}
+
+	if !utf8.Valid(content) {
+		recordParseMetrics(ctx, "swift", time.Since(start), 0, false)
+		return nil, fmt.Errorf("%w: content is not valid UTF-8", ErrInvalidContent)
+	}
+
+	hash := sha256.Sum256(content)
+	hashStr := hex.EncodeToString(hash[:])
+
+	// New tree-sitter parser per call for thread safety
+	parser := sitter.NewParser()
+	parser.SetLanguage(swift.GetLanguage())
+
+	tree, err := parser.ParseCtx(ctx, nil, content)
+	if err != nil {
+		recordParseMetrics(ctx, "swift", time.Since(start), 0, false)
+		return nil, fmt.Errorf("tree-sitter parse failed: %w", err)
+	}
+	defer tree.Close()
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "swift", time.Since(start), 0, false)
+		return nil, fmt.Errorf("parse canceled after tree-sitter: %w", err)
+	}
+
+	result := &ParseResult{
+		FilePath:      filePath,
+		Language:      "swift",
+		Hash:          hashStr,
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Symbols:       make([]*Symbol, 0),
+		Imports:       make([]Import, 0),
+		Errors:        make([]string, 0),
+	}
+
+	rootNode := tree.RootNode()
+	if rootNode == nil {
+		result.Errors = append(result.Errors, "tree-sitter returned nil root node")
+		return result, nil
+	}
+
+	f := &swiftFile{
+		content:   content,
+		filePath:  filePath,
+		lines:     strings.Split(string(content), "\n"),
+		protocols: make(map[string]bool),
+	}
+	p.extractSymbols(ctx, rootNode, f, result)
+
+	if err := result.Validate(); err != nil {
+		recordParseMetrics(ctx, "swift", time.Since(start), 0, false)
+		return nil, fmt.Errorf("result validation failed: %w", err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		recordParseMetrics(ctx, "swift", time.Since(start), len(result.Symbols), false)
+		return nil, fmt.Errorf("parse canceled after extraction: %w", err)
+	}
+
+	setParseSpanResult(span, len(result.Symbols), len(result.Errors))
+	recordParseMetrics(ctx, "swift", time.Since(start), len(result.Symbols), true)
+
+	return result, nil
+}
+
+// extractSymbols extracts the imports and top-level declarations, then
+// merges extensions into the types of the same file.
+func (p *SwiftParser) extractSymbols(ctx context.Context, root *sitter.Node, f *swiftFile, result *ParseResult) {
+	// Protocol names are needed before classes to tell a superclass from a
+	// conformance.
+	for i := 0; i < int(root.ChildCount()); i++ {
+		if child := root.Child(i); child.Type() == swiftNodeProtocolDecl {
+			if name := child.ChildByFieldName(swiftFieldName); name != nil {
+				f.protocols[swiftNodeText(name, f.content)] = true
+			}
+		}
+	}
+
+	var extensions []swiftExtension
+	for i := 0; i < int(root.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			return
+		}
+
+		child := root.Child(i)
+		var sym *Symbol
+		switch child.Type() {
+		case swiftNodeImportDecl:
+			p.extractImport(child, f, result)
+		case swiftNodeClassDecl:
+			if swiftDeclarationKind(child, f.content) == swiftKindExtension {
+				ext := p.extractExtension(ctx, child, f)
+				result.Symbols = append(result.Symbols, ext.methods...)
+				extensions = append(extensions, ext)
+				continue
+			}
+			sym = p.extractType(ctx, child, f, "")
+		case swiftNodeProtocolDecl:
+			sym = p.extractProtocol(ctx, child, f, "")
+		case swiftNodeFunctionDecl:
+			sym = p.extractFunction(ctx, child, f, "")
+		case swiftNodePropertyDecl:
+			sym = p.extractProperty(ctx, child, f, "")
+		case swiftNodeTypealiasDecl:
+			sym = p.extractTypeAlias(child, f, "")
+		case swiftNodeERROR:
+			result.Errors = append(result.Errors, fmt.Sprintf("%s:%d: syntax error: %s",
+				f.filePath, int(child.StartPoint().Row)+1, sqlSnippet(f.content[child.StartByte():child.EndByte()])))
+		}
+		if sym != nil && p.include(sym) {
+			result.Symbols = append(result.Symbols, sym)
+		}
+	}
+
+	swiftMergeExtensions(result.Symbols, extensions)
+}
+
+// extractImport records an import declaration. "import struct MyKit.Point"
+// becomes Path "MyKit.Point" with Names ["Point"]; a module import has no
+// Names.
+func (p *SwiftParser) extractImport(node *sitter.Node, f *swiftFile, result *ParseResult) {
+	ident := swiftChildByType(node, swiftNodeIdentifier)
+	if ident == nil {
+		return
+	}
+	imp := Import{
+		Path:     swiftNodeText(ident, f.content),
+		Location: swiftLocation(node, f.filePath),
+	}
+	if dot := strings.LastIndexByte(imp.Path, '.'); dot >= 0 {
+		imp.Names = []string{imp.Path[dot+1:]}
+	}
+	result.Imports = append(result.Imports, imp)
+}
+
+// extractType builds a class, struct, enum or actor symbol with its members
+// as children. owner is the enclosing type name, or "".
+func (p *SwiftParser) extractType(ctx context.Context, node *sitter.Node, f *swiftFile, owner string) *Symbol {
+	nameNode := node.ChildByFieldName(swiftFieldName)
+	if nameNode == nil {
+		return nil
+	}
+	name := swiftNodeText(nameNode, f.content)
+	declKind := swiftDeclarationKind(node, f.content)
+
+	kind := SymbolKindClass
+	switch declKind {
+	case swiftKindStruct:
+		kind = SymbolKindStruct
+	case swiftKindEnum:
+		kind = SymbolKindEnum
+	}
+
+	body := node.ChildByFieldName(swiftFieldBody)
+	sym := p.newSymbol(node, f, name, kind, swiftDeclSignature(node, node.ChildByFieldName(swiftFieldDeclarationKind), body, f))
+	mods := swiftParseModifiers(node, f.content)
+	p.applyModifiers(sym, mods)
+	if owner != "" {
+		swiftMetadata(sym).ParentName = owner
+	}
+	if typeParams := swiftChildByType(node, swiftNodeTypeParameters); typeParams != nil {
+		swiftMetadata(sym).TypeParameters = swiftTypeParameters(typeParams, f.content)
+	}
+
+	inherited := swiftInheritedTypes(node, f.content)
+	if declKind == swiftKindClass || declKind == swiftKindActor {
+		if len(inherited) > 0 && !f.protocols[inherited[0]] {
+			swiftMetadata(sym).Extends = inherited[0]
+			inherited = inherited[1:]
+		}
+	}
+	if len(inherited) > 0 {
+		swiftMetadata(sym).Implements = inherited
+	}
+
+	if body != nil {
+		sym.Children = append(sym.Children, p.extractMembers(ctx, body, f, name)...)
+	}
+	swiftCollectMethods(sym, sym.Children)
+	return sym
+}
+
+// extractProtocol builds an interface symbol for a protocol. Its function
+// requirements become method children and Metadata.Methods.
+func (p *SwiftParser) extractProtocol(ctx context.Context, node *sitter.Node, f *swiftFile, owner string) *Symbol {
+	nameNode := node.ChildByFieldName(swiftFieldName)
+	if nameNode == nil {
+		return nil
+	}
+	name := swiftNodeText(nameNode, f.content)
+	body := node.ChildByFieldName(swiftFieldBody)
+
+	sym := p.newSymbol(node, f, name, SymbolKindInterface, swiftDeclSignature(node, node.ChildByFieldName(swiftFieldDeclarationKind), body, f))
+	p.applyModifiers(sym, swiftParseModifiers(node, f.content))
+	if owner != "" {
+		swiftMetadata(sym).ParentName = owner
+	}
+
+	// Protocols list their parents like Go interface embeds: the first in
+	// Extends, the rest in Implements.
+	if inherited := swiftInheritedTypes(node, f.content); len(inherited) > 0 {
+		swiftMetadata(sym).Extends = inherited[0]
+		if len(inherited) > 1 {
+			swiftMetadata(sym).Implements = inherited[1:]
+		}
+	}
+
+	if body != nil {
+		sym.Children = append(sym.Children, p.extractMembers(ctx, body, f, name)...)
+	}
+	swiftCollectMethods(sym, sym.Children)
+	return sym
+}
+
+// extractExtension extracts the members of an extension as top-level
+// symbols owned by the extended type.
+func (p *SwiftParser) extractExtension(ctx context.Context, node *sitter.Node, f *swiftFile) swiftExtension {
+	ext := swiftExtension{
+		typeName:     swiftTypeName(node.ChildByFieldName(swiftFieldName), f.content),
+		conformances: swiftInheritedTypes(node, f.content),
+	}
+	if ext.typeName == "" {
+		return ext
+	}
+	if body := node.ChildByFieldName(swiftFieldBody); body != nil {
+		ext.methods = p.extractMembers(ctx, body, f, ext.typeName)
+	}
+	return ext
+}
+
+// extractMembers returns the declarations in a type, protocol or extension
+// body. owner is the name of the type the members belong to.
+func (p *SwiftParser) extractMembers(ctx context.Context, body *sitter.Node, f *swiftFile, owner string) []*Symbol {
+	var members []*Symbol
+	for i := 0; i < int(body.ChildCount()); i++ {
+		if ctx.Err() != nil {
+			return members
+		}
+		child := body.Child(i)
+		var member *Symbol
+		switch child.Type() {
+		case swiftNodeFunctionDecl, swiftNodeProtocolFuncDecl:
+			member = p.extractFunction(ctx, child, f, owner)
+		case swiftNodeInitDecl:
+			member = p.extractInit(ctx, child, f, owner)
+		case swiftNodePropertyDecl, swiftNodeProtocolPropDecl:
+			member = p.extractProperty(ctx, child, f, owner)
+		case swiftNodeClassDecl:
+			member = p.extractType(ctx, child, f, owner)
+		case swiftNodeProtocolDecl:
+			member = p.extractProtocol(ctx, child, f, owner)
+		case swiftNodeTypealiasDecl:
+			member = p.extractTypeAlias(child, f, owner)
+		case swiftNodeEnumEntry:
+			for j := 0; j < int(child.ChildCount()); j++ {
+				if child.FieldNameForChild(j) != swiftFieldName {
+					continue
+				}
+				entry := swiftNodeText(child.Child(j), f.content)
+				enumMember := p.newSymbol(child, f, entry, SymbolKindEnumMember, "case "+entry)
+				swiftMetadata(enumMember).ParentName = owner
+				members = append(members, enumMember)
+			}
+		}
+		if member != nil && p.include(member) {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// extractFunction builds a function symbol. Inside a type or extension
+// (owner != "") it is a method with Receiver = owner.
+func (p *SwiftParser) extractFunction(ctx context.Context, node *sitter.Node, f *swiftFile, owner string) *Symbol {
+	nameNode := node.ChildByFieldName(swiftFieldName)
+	if nameNode == nil {
+		return nil
+	}
+	name := swiftNodeText(nameNode, f.content)
+	mods := swiftParseModifiers(node, f.content)
+	body := node.ChildByFieldName(swiftFieldBody)
+
+	var funcKeyword, returnType *sitter.Node
+	isAsync := false
+	for i := 0; i < int(node.ChildCount()); i++ {
+		switch child := node.Child(i); child.Type() {
+		case swiftKeywordFunc:
+			funcKeyword = child
+		case swiftKeywordAsync:
+			isAsync = true
+		case swiftKeywordArrow:
+			// The return type shares the "name" field with the function
+			// name, so it is found by position instead.
+			if i+1 < int(node.ChildCount()) {
+				returnType = node.Child(i + 1)
+			}
+		}
+	}
+
+	kind := SymbolKindFunction
+	if owner != "" {
+		kind = SymbolKindMethod
+	}
+	sym := p.newSymbol(node, f, name, kind, swiftDeclSignature(node, funcKeyword, body, f))
+	p.applyModifiers(sym, mods)
+	if owner != "" {
+		sym.Receiver = owner
+		swiftMetadata(sym).ParentName = owner
+	}
+	if isAsync {
+		swiftMetadata(sym).IsAsync = true
+	}
+	if mods.isStatic() {
+		swiftMetadata(sym).IsStatic = true
+	}
+	if returnType != nil {
+		swiftMetadata(sym).ReturnType = swiftTypeText(returnType, f.content)
+	}
+	if typeParams := swiftChildByType(node, swiftNodeTypeParameters); typeParams != nil {
+		swiftMetadata(sym).TypeParameters = swiftTypeParameters(typeParams, f.content)
+	}
+	if body != nil {
+		sym.Calls = p.extractCallSites(ctx, body, f)
+	}
+	return sym
+}
+
+// extractInit builds a constructor method symbol named "init".
+func (p *SwiftParser) extractInit(ctx context.Context, node *sitter.Node, f *swiftFile, owner string) *Symbol {
+	body := node.ChildByFieldName(swiftFieldBody)
+	sym := p.newSymbol(node, f, "init", SymbolKindMethod, swiftDeclSignature(node, node.ChildByFieldName(swiftFieldName), body, f))
+	p.applyModifiers(sym, swiftParseModifiers(node, f.content))
+	sym.Receiver = owner
+	meta := swiftMetadata(sym)
+	meta.IsConstructor = true
+	meta.ParentName = owner
+	if body != nil {
+		sym.Calls = p.extractCallSites(ctx, body, f)
+	}
+	return sym
+}
+
+// extractProperty builds a property symbol: a field or computed property
+// inside a type, a variable or constant at top level. Calls in a computed
+// property's body are kept.
+func (p *SwiftParser) extractProperty(ctx context.Context, node *sitter.Node, f *swiftFile, owner string) *Symbol {
+	pattern := node.ChildByFieldName(swiftFieldName)
+	if pattern == nil {
+		return nil
+	}
+	ident := pattern.ChildByFieldName(swiftFieldBoundIdentifier)
+	if ident == nil {
+		return nil
+	}
+	name := swiftNodeText(ident, f.content)
+	mods := swiftParseModifiers(node, f.content)
+
+	binding := swiftChildByType(node, swiftNodeValueBinding)
+	if binding == nil {
+		binding = swiftChildByType(pattern, swiftNodeValueBinding)
+	}
+	computed := node.ChildByFieldName(swiftFieldComputedValue)
+
+	sigStart := pattern.StartByte()
+	if binding != nil && binding.StartByte() < sigStart {
+		sigStart = binding.StartByte()
+	}
+	sigEnd := pattern.EndByte()
+	typeAnnotation := swiftChildByType(node, swiftNodeTypeAnnotation)
+	if typeAnnotation != nil {
+		sigEnd = typeAnnotation.EndByte()
+	}
+	signature := swiftCollapseSpace(string(f.content[sigStart:sigEnd]))
+	if !strings.HasPrefix(signature, "let") && !strings.HasPrefix(signature, "var") && binding != nil {
+		signature = swiftNodeText(binding, f.content) + " " + signature
+	}
+
+	isLet := binding != nil && swiftNodeText(binding, f.content) == "let"
+	kind := SymbolKindVariable
+	switch {
+	case owner != "" && (computed != nil || node.Type() == swiftNodeProtocolPropDecl):
+		kind = SymbolKindProperty
+	case owner != "":
+		kind = SymbolKindField
+	case isLet:
+		kind = SymbolKindConstant
+	}
+
+	sym := p.newSymbol(node, f, name, kind, signature)
+	p.applyModifiers(sym, mods)
+	if owner != "" {
+		sym.Receiver = owner
+		swiftMetadata(sym).ParentName = owner
+	}
+	if mods.isStatic() {
+		swiftMetadata(sym).IsStatic = true
+	}
+	if typeAnnotation != nil {
+		if typeNode := typeAnnotation.ChildByFieldName(swiftFieldName); typeNode != nil {
+			swiftMetadata(sym).ReturnType = swiftTypeText(typeNode, f.content)
+		}
+	}
+	if computed != nil {
+		sym.Calls = p.extractCallSites(ctx, computed, f)
+	}
+	return sym
+}
+
+// extractTypeAlias builds a type symbol for a typealias.
+func (p *SwiftParser) extractTypeAlias(node *sitter.Node, f *swiftFile, owner string) *Symbol {
+	nameNode := node.ChildByFieldName(swiftFieldName)
+	if nameNode == nil {
+		return nil
+	}
+	sym := p.newSymbol(node, f, swiftNodeText(nameNode, f.content), SymbolKindType,
+		swiftCollapseSpace(swiftNodeText(node, f.content)))
+	p.applyModifiers(sym, swiftParseModifiers(node, f.content))
+	if owner != "" {
+		swiftMetadata(sym).ParentName = owner
+	}
+	return sym
+}
+
+// extractCallSites extracts all call sites within a function body.
+//
+// Description:
+//
+//	Walks the subtree under bodyNode and records each call_expression.
+//	"format(x)" is a plain call; "client.load(url:)" and "client?.load()"
+//	are method calls with Receiver "client"; "self.log()" and
+//	"super.init()" use the receivers "self" and "super" that the graph
+//	builder resolves against the enclosing type. Subscripts (items[i]),
+//	which the grammar also parses as calls, are skipped. Calls inside
+//	closures are included; nested type declarations are skipped because
+//	their members are separate symbols.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Checked every 100 nodes.
+//   - bodyNode: The function body or computed property node. May be nil.
+//   - f: The file being parsed.
+//
+// Outputs:
+//   - []CallSite: Extracted call sites. Limited to MaxCallSitesPerSymbol (1000).
+//
+// Thread Safety: Safe for concurrent use.
+func (p *SwiftParser) extractCallSites(ctx context.Context, bodyNode *sitter.Node, f *swiftFile) []CallSite {
+	if bodyNode == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	ctx, span := tracer.Start(ctx, "SwiftParser.extractCallSites")
+	defer span.End()
+
+	calls := make([]CallSite, 0, 8)
+
+	type stackEntry struct {
+		node  *sitter.Node
+		depth int
+	}
+	stack := []stackEntry{{node: bodyNode}}
+
+	nodeCount := 0
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		node := entry.node
+		if entry.depth > MaxCallExpressionDepth {
+			continue
+		}
+
+		nodeCount++
+		if nodeCount%100 == 0 && ctx.Err() != nil {
+			return calls
+		}
+
+		if len(calls) >= MaxCallSitesPerSymbol {
+			slog.Warn("max call sites per symbol reached in Swift",
+				telemetry.SensitiveLog("file", f.filePath),
+				slog.Int("limit", MaxCallSitesPerSymbol),
+			)
+			return calls
+		}
+
+		switch node.Type() {
+		case swiftNodeCallExpression:
+			if call := swiftCallSite(node, f); call != nil {
+				calls = append(calls, *call)
+			}
+		case swiftNodeClassDecl, swiftNodeProtocolDecl:
+			continue
+		}
+
+		// Push in reverse order for left-to-right processing
+		for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+			if child := node.Child(i); child != nil {
+				stack = append(stack, stackEntry{node: child, depth: entry.depth + 1})
+			}
+		}
+	}
+
+	span.SetAttributes(
+		telemetry.SensitiveString("file", f.filePath),
+		attribute.Int("calls_found", len(calls)),
+		attribute.Int("nodes_traversed", nodeCount),
+	)
+
+	return calls
+}
+
+// swiftCallSite converts a call_expression node to a CallSite, or nil if
+// it is a subscript or the callee is not a name.
+func swiftCallSite(node *sitter.Node, f *swiftFile) *CallSite {
+	callee := node.NamedChild(0)
+	if callee == nil {
+		return nil
+	}
+	if suffix := swiftChildByType(node, swiftNodeCallSuffix); suffix != nil {
+		if args := swiftChildByType(suffix, swiftNodeValueArguments); args != nil &&
+			args.ChildCount() > 0 && args.Child(0).Type() == "[" {
+			return nil
+		}
+	}
+	call := &CallSite{Location: swiftLocation(node, f.filePath)}
+
+	switch callee.Type() {
+	case swiftNodeSimpleIdentifier:
+		call.Target = swiftNodeText(callee, f.content)
+	case swiftNodePrefixExpression:
+		// Implicit member call: .make()
+		target := callee.ChildByFieldName(swiftFieldTarget)
+		if target == nil || target.Type() != swiftNodeSimpleIdentifier {
+			return nil
+		}
+		call.Target = swiftNodeText(target, f.content)
+	case swiftNodeNavigationExpr:
+		suffix := callee.ChildByFieldName(swiftFieldSuffix)
+		if suffix == nil || suffix.Type() != swiftNodeNavigationSuffix {
+			return nil
+		}
+		ident := suffix.ChildByFieldName(swiftFieldSuffix)
+		if ident == nil || ident.Type() != swiftNodeSimpleIdentifier {
+			return nil
+		}
+		call.Target = swiftNodeText(ident, f.content)
+		call.IsMethod = true
+		receiver := callee.ChildByFieldName(swiftFieldTarget)
+		switch {
+		case receiver == nil:
+		case receiver.Type() == swiftNodeSelfExpression:
+			call.Receiver = "self"
+		case receiver.Type() == swiftNodeSuperExpression:
+			call.Receiver = "super"
+		default:
+			call.Receiver = swiftCollapseSpace(swiftNodeText(receiver, f.content))
+		}
+	default:
+		return nil
+	}
+
+	if call.Target == "" {
+		return nil
+	}
+	return call
+}
+
+// newSymbol creates a Swift symbol with its location and doc comment set.
+func (p *SwiftParser) newSymbol(node *sitter.Node, f *swiftFile, name string, kind SymbolKind, signature string) *Symbol {
+	return &Symbol{
+		ID:            GenerateID(f.filePath, int(node.StartPoint().Row)+1, name),
+		Name:          name,
+		Kind:          kind,
+		FilePath:      f.filePath,
+		StartLine:     int(node.StartPoint().Row) + 1,
+		EndLine:       int(node.EndPoint().Row) + 1,
+		StartCol:      int(node.StartPoint().Column),
+		EndCol:        int(node.EndPoint().Column),
+		Signature:     signature,
+		DocComment:    cStyleDocComment(f.lines, int(node.StartPoint().Row)),
+		Language:      "swift",
+		ParsedAtMilli: time.Now().UnixMilli(),
+		Exported:      true,
+	}
+}
+
+// applyModifiers sets visibility and attributes from mods on sym. Swift
+// declarations default to internal access, which is visible module-wide;
+// private and fileprivate ones are not exported.
+func (p *SwiftParser) applyModifiers(sym *Symbol, mods swiftModifiers) {
+	if mods.visibility != "" {
+		swiftMetadata(sym).AccessModifier = mods.visibility
+		sym.Exported = mods.visibility != "private" && mods.visibility != "fileprivate"
+	}
+	if len(mods.attributes) > 0 {
+		swiftMetadata(sym).Decorators = mods.attributes
+	}
+}
+
+// include reports whether sym passes the IncludePrivate option.
+func (p *SwiftParser) include(sym *Symbol) bool {
+	return p.parseOptions.IncludePrivate || sym.Exported
+}
+
+// swiftMergeExtensions adds the conformances and instance methods of each
+// extension to the type it extends, when that type is declared among
+// symbols (including nested types).
+func swiftMergeExtensions(symbols []*Symbol, extensions []swiftExtension) {
+	if len(extensions) == 0 {
+		return
+	}
+	types := make(map[string]*Symbol)
+	var register func(syms []*Symbol)
+	register = func(syms []*Symbol) {
+		for _, sym := range syms {
+			switch sym.Kind {
+			case SymbolKindClass, SymbolKindStruct, SymbolKindEnum:
+				if _, dup := types[sym.Name]; !dup {
+					types[sym.Name] = sym
+				}
+				register(sym.Children)
+			}
+		}
+	}
+	register(symbols)
+
+	for _, ext := range extensions {
+		typeSym := types[ext.typeName]
+		if typeSym == nil {
+			continue
+		}
+		for _, conformance := range ext.conformances {
+			meta := swiftMetadata(typeSym)
+			if !slices.Contains(meta.Implements, conformance) && meta.Extends != conformance {
+				meta.Implements = append(meta.Implements, conformance)
+			}
+		}
+		swiftCollectMethods(typeSym, ext.methods)
+	}
+}
+
+// swiftCollectMethods appends the instance methods among members to the
+// Metadata.Methods of sym, so the graph builder can match types to the
+// protocols they conform to.
+func swiftCollectMethods(sym *Symbol, members []*Symbol) {
+	for _, member := range members {
+		if member.Kind != SymbolKindMethod || member.Metadata == nil ||
+			member.Metadata.IsStatic || member.Metadata.IsConstructor {
+			continue
+		}
+		meta := swiftMetadata(sym)
+		meta.Methods = append(meta.Methods, swiftMethodSignature(member, sym.Name))
+	}
+}
+
+// swiftMethodSignature builds the MethodSignature of a method symbol from
+// its "func name(params) -> Return" signature.
+func swiftMethodSignature(method *Symbol, receiverType string) MethodSignature {
+	sig := MethodSignature{
+		Name:         method.Name,
+		ReceiverType: receiverType,
+	}
+	if open := strings.IndexByte(method.Signature, '('); open >= 0 {
+		depth := 0
+		for i := open; i < len(method.Signature); i++ {
+			switch method.Signature[i] {
+			case '(', '[':
+				depth++
+			case ')', ']':
+				depth--
+			case ',':
+				if depth == 1 {
+					sig.ParamCount++
+				}
+			}
+			if depth == 0 {
+				sig.Params = method.Signature[open+1 : i]
+				break
+			}
+		}
+	}
+	if sig.Params != "" {
+		sig.ParamCount++
+	}
+	if method.Metadata != nil && method.Metadata.ReturnType != "" {
+		sig.Returns = method.Metadata.ReturnType
+		sig.ReturnCount = 1
+	}
+	return sig
+}
+
+// swiftModifiers holds the parsed modifiers of a declaration.
+type swiftModifiers struct {
+	visibility string
+	attributes []string
+	// keywords are the remaining modifiers in source order
+	// (static, class, override, final, mutating, lazy, ...).
+	keywords []string
+}
+
+// isStatic reports whether the declaration is static or class-level.
+func (m swiftModifiers) isStatic() bool {
+	for _, k := range m.keywords {
+		if k == "static" || k == "class" {
+			return true
+		}
+	}
+	return false
+}
+
+// swiftParseModifiers reads the modifiers child of a declaration.
+// "private(set)" restricts only the setter and leaves the visibility as is.
+func swiftParseModifiers(node *sitter.Node, content []byte) swiftModifiers {
+	var mods swiftModifiers
+	modifiers := swiftChildByType(node, swiftNodeModifiers)
+	if modifiers == nil {
+		return mods
+	}
+	for i := 0; i < int(modifiers.NamedChildCount()); i++ {
+		child := modifiers.NamedChild(i)
+		text := swiftNodeText(child, content)
+		switch child.Type() {
+		case swiftNodeAttribute:
+			if userType := swiftChildByType(child, swiftNodeUserType); userType != nil {
+				text = swiftNodeText(userType, content)
+			}
+			mods.attributes = append(mods.attributes, strings.TrimPrefix(text, "@"))
+		case swiftNodeVisibilityMod:
+			if !strings.Contains(text, "(") {
+				mods.visibility = text
+			}
+		default:
+			mods.keywords = append(mods.keywords, text)
+		}
+	}
+	return mods
+}
+
+// swiftDeclarationKind returns the declaration_kind of a class_declaration:
+// "class", "struct", "enum", "actor" or "extension".
+func swiftDeclarationKind(node *sitter.Node, content []byte) string {
+	if kind := node.ChildByFieldName(swiftFieldDeclarationKind); kind != nil {
+		return swiftNodeText(kind, content)
+	}
+	return ""
+}
+
+// swiftDeclSignature returns the declaration text from start up to the
+// body, with modifiers left out and whitespace collapsed. start falls back
+// to node when nil.
+func swiftDeclSignature(node, start, body *sitter.Node, f *swiftFile) string {
+	sigStart := node.StartByte()
+	if start != nil {
+		sigStart = start.StartByte()
+	}
+	sigEnd := node.EndByte()
+	if body != nil {
+		sigEnd = body.StartByte()
+	}
+	return swiftCollapseSpace(string(f.content[sigStart:sigEnd]))
+}
+
+// swiftInheritedTypes returns the simple names of the types listed after
+// the colon of a type, protocol or extension declaration.
+func swiftInheritedTypes(node *sitter.Node, content []byte) []string {
+	var names []string
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() != swiftNodeInheritanceSpec {
+			continue
+		}
+		if name := swiftTypeName(child.ChildByFieldName(swiftFieldInheritsFrom), content); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// swiftTypeParameters returns the names of the generic parameters in a
+// type_parameters node.
+func swiftTypeParameters(node *sitter.Node, content []byte) []string {
+	var names []string
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if tp := node.Child(i); tp.Type() == swiftNodeTypeParameter {
+			if ident := swiftChildByType(tp, swiftNodeTypeIdentifier); ident != nil {
+				names = append(names, swiftNodeText(ident, content))
+			}
+		}
+	}
+	return names
+}
+
+// swiftTypeName returns the simple name of a type node: "Inner" for
+// Outer.Inner, "Array" for Array<Int>.
+func swiftTypeName(node *sitter.Node, content []byte) string {
+	if node == nil {
+		return ""
+	}
+	if node.Type() == swiftNodeUserType {
+		for i := int(node.NamedChildCount()) - 1; i >= 0; i-- {
+			if child := node.NamedChild(i); child.Type() == swiftNodeTypeIdentifier {
+				return swiftNodeText(child, content)
+			}
+		}
+	}
+	return swiftNodeText(node, content)
+}
+
+// swiftTypeText returns the text of a type node with an optional's "?"
+// removed: "Data" for Data?.
+func swiftTypeText(node *sitter.Node, content []byte) string {
+	if node.Type() == swiftNodeOptionalType && node.NamedChildCount() > 0 {
+		node = node.NamedChild(0)
+	}
+	return swiftCollapseSpace(swiftNodeText(node, content))
+}
+
+// swiftMetadata returns sym.Metadata, allocating it if needed.
+func swiftMetadata(sym *Symbol) *SymbolMetadata {
+	if sym.Metadata == nil {
+		sym.Metadata = &SymbolMetadata{}
+	}
+	return sym.Metadata
+}
+
+// swiftLocation returns the location of node.
+func swiftLocation(node *sitter.Node, filePath string) Location {
+	return Location{
+		FilePath:  filePath,
+		StartLine: int(node.StartPoint().Row) + 1,
+		EndLine:   int(node.EndPoint().Row) + 1,
+		StartCol:  int(node.StartPoint().Column),
+		EndCol:    int(node.EndPoint().Column),
+	}
+}
+
+// swiftCollapseSpace replaces runs of whitespace with single spaces.
+func swiftCollapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// swiftChildByType returns the first direct child of node with the given
+// type, or nil.
+func swiftChildByType(node *sitter.Node, nodeType string) *sitter.Node {
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() == nodeType {
+			return child
+		}
+	}
+	return nil
+}
+
+// swiftNodeText returns the source text of node.
+func swiftNodeText(node *sitter.Node, content []byte) string {
+	return string(content[node.StartByte():node.EndByte()])
+}
@@ -0,0 +1,276 @@
+package ast
+
+import (
+	"context"
+	"testing"
+)
+
+const testSwiftSource = `import Foundation
+import struct MyKit.Point
+
+/// Greets people by name.
+protocol Greeter {
+    func greet(name: String) -> String
+    var title: String { get }
+}
+
+public struct Person: Greeter {
+    let name: String
+    var title: String { return format(name) }
+
+    func greet(name: String) -> String {
+        return format(name).uppercased()
+    }
+}
+
+class Service: NSObject, Greeter {
+    init(client: Client) {
+        self.client = client
+        super.init()
+    }
+
+    func greet(name: String) -> String { "hi" }
+
+    func fetch() async throws -> Data? {
+        let data = try await client.load(url: url)
+        self.log(data)
+        return items[0]
+    }
+
+    static func shared() -> Service { Service(client: Client()) }
+
+    private func log(_ data: Data?) {}
+}
+
+extension Person: Codable {
+    func describe() -> String {
+        return greet(name: name)
+    }
+}
+
+enum Direction {
+    case north, south
+}
+
+func format(_ s: String) -> String { s }
+
+let maxUsers = 100
+
+typealias Handler = (String) -> Void
+`
+
+func parseTestSwift(t *testing.T) *ParseResult {
+	t.Helper()
+	result, err := NewSwiftParser().Parse(context.Background(), []byte(testSwiftSource), "Sources/App/Person.swift")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected parse errors: %v", result.Errors)
+	}
+	return result
+}
+
+// swiftTopLevel returns the top-level symbol with the given name.
+func swiftTopLevel(t *testing.T, result *ParseResult, name string) *Symbol {
+	t.Helper()
+	for _, sym := range result.Symbols {
+		if sym.Name == name {
+			return sym
+		}
+	}
+	t.Fatalf("top-level symbol %q not found", name)
+	return nil
+}
+
+// swiftChild returns the child of sym with the given name.
+func swiftChild(t *testing.T, sym *Symbol, name string) *Symbol {
+	t.Helper()
+	for _, child := range sym.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	t.Fatalf("%s has no child %q", sym.Name, name)
+	return nil
+}
+
+// swiftMethodNames returns the names in sym.Metadata.Methods.
+func swiftMethodNames(sym *Symbol) []string {
+	if sym.Metadata == nil {
+		return nil
+	}
+	names := make([]string, 0, len(sym.Metadata.Methods))
+	for _, m := range sym.Metadata.Methods {
+		names = append(names, m.Name)
+	}
+	return names
+}
+
+func TestSwiftParser_LanguageAndExtensions(t *testing.T) {
+	parser := NewSwiftParser()
+	if got := parser.Language(); got != "swift" {
+		t.Errorf("Language() = %q, want %q", got, "swift")
+	}
+	if exts := parser.Extensions(); len(exts) != 1 || exts[0] != ".swift" {
+		t.Errorf("Extensions() = %v, want [.swift]", exts)
+	}
+}
+
+func TestSwiftParser_Imports(t *testing.T) {
+	result := parseTestSwift(t)
+
+	if len(result.Imports) != 2 {
+		t.Fatalf("expected 2 imports, got %d", len(result.Imports))
+	}
+	if result.Imports[0].Path != "Foundation" || len(result.Imports[0].Names) != 0 {
+		t.Errorf("import 0 = %+v", result.Imports[0])
+	}
+	if imp := result.Imports[1]; imp.Path != "MyKit.Point" || len(imp.Names) != 1 || imp.Names[0] != "Point" {
+		t.Errorf("import 1 = %+v", imp)
+	}
+}
+
+func TestSwiftParser_Protocol(t *testing.T) {
+	result := parseTestSwift(t)
+
+	greeter := swiftTopLevel(t, result, "Greeter")
+	if greeter.Kind != SymbolKindInterface {
+		t.Fatalf("Greeter kind = %v, want interface", greeter.Kind)
+	}
+	if greeter.DocComment != "Greets people by name." {
+		t.Errorf("DocComment = %q", greeter.DocComment)
+	}
+	if names := swiftMethodNames(greeter); len(names) != 1 || names[0] != "greet" {
+		t.Errorf("Greeter methods = %v, want [greet]", names)
+	}
+	if title := swiftChild(t, greeter, "title"); title.Kind != SymbolKindProperty {
+		t.Errorf("title kind = %v, want property", title.Kind)
+	}
+}
+
+func TestSwiftParser_TypesAndMembers(t *testing.T) {
+	result := parseTestSwift(t)
+
+	person := swiftTopLevel(t, result, "Person")
+	if person.Kind != SymbolKindStruct {
+		t.Fatalf("Person kind = %v, want struct", person.Kind)
+	}
+	if name := swiftChild(t, person, "name"); name.Kind != SymbolKindField || name.Metadata.ReturnType != "String" {
+		t.Errorf("Person.name = %v %+v, want String field", name.Kind, name.Metadata)
+	}
+	if title := swiftChild(t, person, "title"); title.Kind != SymbolKindProperty || !hasCallSite(title.Calls, "", "format") {
+		t.Errorf("Person.title = %v calls %+v, want computed property calling format", title.Kind, title.Calls)
+	}
+
+	service := swiftTopLevel(t, result, "Service")
+	if service.Kind != SymbolKindClass || service.Metadata.Extends != "NSObject" {
+		t.Errorf("Service = %v %+v, want class extending NSObject", service.Kind, service.Metadata)
+	}
+	if impl := service.Metadata.Implements; len(impl) != 1 || impl[0] != "Greeter" {
+		t.Errorf("Service implements = %v, want [Greeter]", impl)
+	}
+
+	initializer := swiftChild(t, service, "init")
+	if initializer.Kind != SymbolKindMethod || !initializer.Metadata.IsConstructor || initializer.Receiver != "Service" {
+		t.Errorf("init = %v %+v receiver %q", initializer.Kind, initializer.Metadata, initializer.Receiver)
+	}
+	fetch := swiftChild(t, service, "fetch")
+	if !fetch.Metadata.IsAsync || fetch.Metadata.ReturnType != "Data" {
+		t.Errorf("fetch metadata = %+v, want async returning Data", fetch.Metadata)
+	}
+	if shared := swiftChild(t, service, "shared"); !shared.Metadata.IsStatic {
+		t.Error("static func shared should be static")
+	}
+	if log := swiftChild(t, service, "log"); log.Exported {
+		t.Error("private func log should not be exported")
+	}
+	// Static methods and initializers are not part of the conformance method set.
+	if names := swiftMethodNames(service); len(names) != 3 || names[0] != "greet" || names[1] != "fetch" || names[2] != "log" {
+		t.Errorf("Service methods = %v, want [greet fetch log]", names)
+	}
+
+	direction := swiftTopLevel(t, result, "Direction")
+	if direction.Kind != SymbolKindEnum || len(direction.Children) != 2 ||
+		direction.Children[0].Name != "north" || direction.Children[1].Kind != SymbolKindEnumMember {
+		t.Errorf("Direction = %v with children %v", direction.Kind, direction.Children)
+	}
+}
+
+func TestSwiftParser_Extension(t *testing.T) {
+	result := parseTestSwift(t)
+
+	describe := swiftTopLevel(t, result, "describe")
+	if describe.Kind != SymbolKindMethod || describe.Receiver != "Person" {
+		t.Fatalf("describe = %v receiver %q, want method on Person", describe.Kind, describe.Receiver)
+	}
+
+	// The extended type is declared in the same file, so it picks up the
+	// extension's methods and conformances directly.
+	person := swiftTopLevel(t, result, "Person")
+	if names := swiftMethodNames(person); len(names) != 2 || names[0] != "greet" || names[1] != "describe" {
+		t.Errorf("Person methods = %v, want [greet describe]", names)
+	}
+	if impl := person.Metadata.Implements; len(impl) != 2 || impl[0] != "Greeter" || impl[1] != "Codable" {
+		t.Errorf("Person implements = %v, want [Greeter Codable]", impl)
+	}
+}
+
+func TestSwiftParser_TopLevelDeclarations(t *testing.T) {
+	result := parseTestSwift(t)
+
+	if format := swiftTopLevel(t, result, "format"); format.Kind != SymbolKindFunction {
+		t.Errorf("format kind = %v, want function", format.Kind)
+	}
+	if maxUsers := swiftTopLevel(t, result, "maxUsers"); maxUsers.Kind != SymbolKindConstant {
+		t.Errorf("maxUsers kind = %v, want constant", maxUsers.Kind)
+	}
+	if handler := swiftTopLevel(t, result, "Handler"); handler.Kind != SymbolKindType {
+		t.Errorf("Handler kind = %v, want type", handler.Kind)
+	}
+}
+
+func TestSwiftParser_CallSites(t *testing.T) {
+	result := parseTestSwift(t)
+
+	service := swiftTopLevel(t, result, "Service")
+	if initializer := swiftChild(t, service, "init"); !hasCallSite(initializer.Calls, "super", "init") {
+		t.Errorf("init calls = %+v, want super.init", initializer.Calls)
+	}
+
+	fetch := swiftChild(t, service, "fetch")
+	if !hasCallSite(fetch.Calls, "client", "load") || !hasCallSite(fetch.Calls, "self", "log") {
+		t.Errorf("fetch calls = %+v, want client.load and self.log", fetch.Calls)
+	}
+	for _, call := range fetch.Calls {
+		if call.Target == "items" {
+			t.Errorf("subscript items[0] recorded as a call: %+v", call)
+		}
+	}
+
+	shared := swiftChild(t, service, "shared")
+	if !hasCallSite(shared.Calls, "", "Service") || !hasCallSite(shared.Calls, "", "Client") {
+		t.Errorf("shared calls = %+v, want Service and Client", shared.Calls)
+	}
+}
+
+func TestSwiftParser_SyntaxErrorReported(t *testing.T) {
+	content := []byte(`struct Good {}
+func (
+`)
+	result, err := NewSwiftParser().Parse(context.Background(), content, "Bad.swift")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(result.Errors) == 0 {
+		t.Error("expected a syntax error to be reported")
+	}
+}
+
+func TestSwiftParser_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := NewSwiftParser().Parse(ctx, []byte(testSwiftSource), "Person.swift"); err == nil {
+		t.Error("expected error for cancelled context")
+	}
+}
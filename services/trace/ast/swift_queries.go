@@ -0,0 +1,159 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ast
+
+// Swift Tree-sitter Node Types
+//
+// This file documents the tree-sitter node types used by SwiftParser for symbol extraction.
+// Classes, structs, enums, actors and extensions share class_declaration and are told
+// apart by its declaration_kind field.
+//
+// Reference: https://github.com/alex-pinkus/tree-sitter-swift
+
+// Node type constants for Swift AST traversal.
+const (
+	// Top-level nodes
+	swiftNodeSourceFile = "source_file"
+	swiftNodeImportDecl = "import_declaration"
+
+	// Declaration nodes
+	swiftNodeClassDecl         = "class_declaration"
+	swiftNodeProtocolDecl      = "protocol_declaration"
+	swiftNodeFunctionDecl      = "function_declaration"
+	swiftNodeProtocolFuncDecl  = "protocol_function_declaration"
+	swiftNodeProtocolPropDecl  = "protocol_property_declaration"
+	swiftNodeInitDecl          = "init_declaration"
+	swiftNodePropertyDecl      = "property_declaration"
+	swiftNodeTypealiasDecl     = "typealias_declaration"
+	swiftNodeEnumEntry         = "enum_entry"
+	swiftNodeInheritanceSpec   = "inheritance_specifier"
+	swiftNodeTypeAnnotation    = "type_annotation"
+	swiftNodeValueBinding      = "value_binding_pattern"
+	swiftNodeComputedProperty  = "computed_property"
+	swiftNodeTypeParameters    = "type_parameters"
+	swiftNodeTypeParameter     = "type_parameter"
+	swiftNodeFunctionBody      = "function_body"
+	swiftNodeClassBody         = "class_body"
+	swiftNodeEnumClassBody     = "enum_class_body"
+	swiftNodeProtocolBody      = "protocol_body"
+	swiftNodeDeinitDecl        = "deinit_declaration"
+	swiftNodeSubscriptDecl     = "subscript_declaration"
+	swiftNodeAssociatedType    = "associatedtype_declaration"
+	swiftNodeOptionalType      = "optional_type"
+	swiftNodeUserType          = "user_type"
+	swiftNodeTypeIdentifier    = "type_identifier"
+	swiftNodeIdentifier        = "identifier"
+	swiftNodeSimpleIdentifier  = "simple_identifier"
+	swiftNodePattern           = "pattern"
+	swiftNodeModifiers         = "modifiers"
+	swiftNodeAttribute         = "attribute"
+	swiftNodeVisibilityMod     = "visibility_modifier"
+	swiftNodePropertyModifier  = "property_modifier"
+	swiftNodeCallExpression    = "call_expression"
+	swiftNodeCallSuffix        = "call_suffix"
+	swiftNodeValueArguments    = "value_arguments"
+	swiftNodeNavigationExpr    = "navigation_expression"
+	swiftNodeNavigationSuffix  = "navigation_suffix"
+	swiftNodePrefixExpression  = "prefix_expression"
+	swiftNodeSelfExpression    = "self_expression"
+	swiftNodeSuperExpression   = "super_expression"
+	swiftNodeComment           = "comment"
+	swiftNodeMultilineComment  = "multiline_comment"
+	swiftNodeERROR             = "ERROR"
+	swiftKeywordFunc           = "func"
+	swiftKeywordAsync          = "async"
+	swiftKeywordArrow          = "->"
+	swiftKindClass             = "class"
+	swiftKindStruct            = "struct"
+	swiftKindEnum              = "enum"
+	swiftKindActor             = "actor"
+	swiftKindExtension         = "extension"
+	swiftFieldName             = "name"
+	swiftFieldBody             = "body"
+	swiftFieldDeclarationKind  = "declaration_kind"
+	swiftFieldInheritsFrom     = "inherits_from"
+	swiftFieldBoundIdentifier  = "bound_identifier"
+	swiftFieldComputedValue    = "computed_value"
+	swiftFieldTarget           = "target"
+	swiftFieldSuffix           = "suffix"
+	swiftFieldMutability       = "mutability"
+	swiftFieldNavigationSuffix = "suffix"
+)
+
+// SwiftNodeTypes maps symbol kinds to the tree-sitter node types that produce them.
+var SwiftNodeTypes = map[SymbolKind][]string{
+	SymbolKindClass:      {swiftNodeClassDecl},
+	SymbolKindStruct:     {swiftNodeClassDecl},
+	SymbolKindEnum:       {swiftNodeClassDecl},
+	SymbolKindEnumMember: {swiftNodeEnumEntry},
+	SymbolKindInterface:  {swiftNodeProtocolDecl},
+	SymbolKindFunction:   {swiftNodeFunctionDecl},
+	SymbolKindMethod:     {swiftNodeFunctionDecl, swiftNodeProtocolFuncDecl, swiftNodeInitDecl},
+	SymbolKindField:      {swiftNodePropertyDecl},
+	SymbolKindProperty:   {swiftNodePropertyDecl, swiftNodeProtocolPropDecl},
+	SymbolKindVariable:   {swiftNodePropertyDecl},
+	SymbolKindConstant:   {swiftNodePropertyDecl},
+	SymbolKindType:       {swiftNodeTypealiasDecl},
+}
+
+// Swift AST Structure Reference
+//
+// source_file
+// ├── import_declaration
+// │   └── identifier (Foundation | MyKit.Point)
+// ├── class_declaration
+// │   ├── modifiers (public, final, @MainActor, ...)
+// │   ├── declaration_kind: class | struct | enum | actor | extension
+// │   ├── name: type_identifier (Person) | user_type (extension Outer.Inner)
+// │   ├── type_parameters                        [optional]
+// │   ├── inheritance_specifier
+// │   │   └── inherits_from: user_type (Greeter)
+// │   └── body: class_body | enum_class_body
+// │       ├── function_declaration
+// │       ├── init_declaration
+// │       ├── property_declaration
+// │       │   ├── value_binding_pattern (let | var)
+// │       │   ├── name: pattern
+// │       │   │   └── bound_identifier: simple_identifier
+// │       │   ├── type_annotation                [optional]
+// │       │   └── computed_value: computed_property [optional]
+// │       ├── enum_entry
+// │       │   └── name: simple_identifier (repeated for case a, b)
+// │       └── class_declaration | protocol_declaration (nested)
+// ├── protocol_declaration
+// │   ├── name: type_identifier
+// │   ├── inheritance_specifier
+// │   └── body: protocol_body
+// │       ├── protocol_function_declaration
+// │       ├── protocol_property_declaration
+// │       └── init_declaration
+// ├── function_declaration
+// │   ├── modifiers (static, override, private, @objc, ...)
+// │   ├── "func"
+// │   ├── name: simple_identifier
+// │   ├── parameter*
+// │   ├── "async"                                [optional]
+// │   ├── "->" <type>                            [return type]
+// │   └── body: function_body
+// └── typealias_declaration
+//     └── name: type_identifier
+//
+// Calls:
+//
+// call_expression
+// ├── simple_identifier (format)                 format(x)
+// │   | navigation_expression                    client.load(url:)
+// │   │   ├── target: <receiver expression>
+// │   │   └── suffix: navigation_suffix
+// │   │       └── suffix: simple_identifier (load)
+// │   | prefix_expression                        .make()
+// └── call_suffix
+//     └── value_arguments ( ... ) | [ ... ]       [brackets are subscripts]
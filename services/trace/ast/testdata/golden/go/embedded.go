@@ -0,0 +1,78 @@
+// Package store persists sessions behind small embedded interfaces.
+package store
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// Reader loads a value by key.
+type Reader interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// Writer stores a value by key.
+type Writer interface {
+	Put(ctx context.Context, key string, value []byte) error
+}
+
+// ReadWriteCloser embeds both halves plus io.Closer.
+type ReadWriteCloser interface {
+	Reader
+	Writer
+	io.Closer
+}
+
+// Cache is a generic read-through cache.
+type Cache[K comparable, V any] struct {
+	sync.RWMutex
+	items map[K]V
+	load  func(K) (V, error)
+}
+
+// NewCache creates a cache backed by load.
+func NewCache[K comparable, V any](load func(K) (V, error)) *Cache[K, V] {
+	return &Cache[K, V]{items: make(map[K]V), load: load}
+}
+
+// Get returns the cached value or loads it.
+func (c *Cache[K, V]) Get(key K) (V, error) {
+	c.RLock()
+	v, ok := c.items[key]
+	c.RUnlock()
+	if ok {
+		return v, nil
+	}
+	v, err := c.load(key)
+	if err != nil {
+		return v, err
+	}
+	c.Lock()
+	c.items[key] = v
+	c.Unlock()
+	return v, nil
+}
+
+// memoryStore is an in-memory ReadWriteCloser.
+type memoryStore struct {
+	*Cache[string, []byte]
+	closed bool
+}
+
+func (m *memoryStore) Put(_ context.Context, key string, value []byte) error {
+	m.Lock()
+	defer m.Unlock()
+	m.items[key] = value
+	return nil
+}
+
+func (m *memoryStore) Close() error {
+	m.closed = true
+	return nil
+}
+
+// Open returns an empty in-memory store.
+func Open() ReadWriteCloser {
+	return &memoryStore{Cache: NewCache(func(string) ([]byte, error) { return nil, io.EOF })}
+}
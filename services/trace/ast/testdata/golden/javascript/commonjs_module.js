@@ -0,0 +1,69 @@
+'use strict';
+
+var EventEmitter = require('events').EventEmitter;
+var util = require('util');
+var path = require('path');
+
+/**
+ * Router dispatches requests to registered handlers.
+ */
+function Router(options) {
+  if (!(this instanceof Router)) {
+    return new Router(options);
+  }
+  EventEmitter.call(this);
+  this.stack = [];
+  this.prefix = (options && options.prefix) || '/';
+}
+
+util.inherits(Router, EventEmitter);
+
+Router.prototype.use = function use(fn) {
+  this.stack.push(fn);
+  return this;
+};
+
+Router.prototype.handle = function handle(req, res, done) {
+  var idx = 0;
+  var stack = this.stack;
+  function next(err) {
+    var layer = stack[idx++];
+    if (!layer) {
+      return done(err);
+    }
+    layer(req, res, next);
+  }
+  next();
+};
+
+var proto = module.exports = function createApplication() {
+  var app = new Router({ prefix: '/' });
+  app.resolve = function (p) {
+    return path.join(app.prefix, p);
+  };
+  return app;
+};
+
+proto.Router = Router;
+
+exports.loadPlugins = async function loadPlugins(names) {
+  const loaded = [];
+  for (const name of names) {
+    const plugin = await import('./plugins/' + name);
+    loaded.push(plugin);
+  }
+  return loaded;
+};
+
+class Layer {
+  constructor(route, fn) {
+    this.route = route;
+    this.fn = fn;
+  }
+
+  match(url) {
+    return url.startsWith(this.route);
+  }
+}
+
+module.exports.Layer = Layer;
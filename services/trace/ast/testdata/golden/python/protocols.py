@@ -0,0 +1,78 @@
+"""Pluggable storage backends described by structural protocols."""
+
+from __future__ import annotations
+
+import abc
+from dataclasses import dataclass, field
+from typing import Iterator, Protocol, runtime_checkable
+
+from .errors import BackendError
+
+
+@runtime_checkable
+class SupportsRead(Protocol):
+    """Anything that can read bytes by key."""
+
+    def read(self, key: str) -> bytes:
+        ...
+
+
+class SupportsWrite(Protocol):
+    def write(self, key: str, data: bytes) -> None: ...
+
+
+class Backend(SupportsRead, SupportsWrite, Protocol):
+    """A full read/write backend."""
+
+    name: str
+
+    def keys(self) -> Iterator[str]:
+        ...
+
+
+@dataclass(frozen=True)
+class Entry:
+    key: str
+    size: int = 0
+    tags: list[str] = field(default_factory=list)
+
+
+class BaseBackend(abc.ABC):
+    @abc.abstractmethod
+    def read(self, key: str) -> bytes:
+        raise NotImplementedError
+
+    @property
+    def name(self) -> str:
+        return type(self).__name__.lower()
+
+    @staticmethod
+    def normalize(key: str) -> str:
+        return key.strip("/")
+
+
+class MemoryBackend(BaseBackend):
+    def __init__(self) -> None:
+        self._data: dict[str, bytes] = {}
+
+    def read(self, key: str) -> bytes:
+        try:
+            return self._data[self.normalize(key)]
+        except KeyError as exc:
+            raise BackendError(key) from exc
+
+    def write(self, key: str, data: bytes) -> None:
+        self._data[self.normalize(key)] = data
+
+    async def stream(self, prefix: str):
+        for key in sorted(self._data):
+            if key.startswith(prefix):
+                yield Entry(key=key, size=len(self._data[key]))
+
+
+def copy_all(src: SupportsRead, dst: SupportsWrite, keys: Iterator[str]) -> int:
+    count = 0
+    for key in keys:
+        dst.write(key, src.read(key))
+        count += 1
+    return count
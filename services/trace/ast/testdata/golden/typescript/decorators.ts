@@ -0,0 +1,56 @@
+import { Injectable, Inject } from "@nestjs/common";
+import type { Request } from "express";
+import * as metrics from "./metrics";
+import Logger, { LogLevel } from "./logger";
+
+export interface UserRecord {
+  id: string;
+  email: string;
+  roles?: string[];
+}
+
+export type Role = "admin" | "member";
+
+function Audited(action: string): MethodDecorator {
+  return (_target, _key, descriptor) => descriptor;
+}
+
+@Injectable()
+export class UserService {
+  private readonly cache = new Map<string, UserRecord>();
+
+  constructor(@Inject("DB") private readonly db: Database, private logger: Logger) {}
+
+  @Audited("user.read")
+  async findById(id: string): Promise<UserRecord | undefined> {
+    if (this.cache.has(id)) {
+      metrics.increment("user.cache_hit");
+      return this.cache.get(id);
+    }
+    const record = await this.db.query<UserRecord>("users", id);
+    this.logger.log(LogLevel.Debug, `loaded ${id}`);
+    return record;
+  }
+
+  static fromRequest(req: Request): string {
+    return req.params.id;
+  }
+
+  async loadReport(id: string) {
+    const { renderReport } = await import("./reports/render");
+    return renderReport(await this.findById(id));
+  }
+}
+
+export abstract class Repository<T extends { id: string }> {
+  protected abstract table: string;
+
+  abstract save(entity: T): Promise<void>;
+}
+
+export const lazyAdmin = () => import("./admin/panel");
+
+export enum Status {
+  Active = "active",
+  Disabled = "disabled",
+}
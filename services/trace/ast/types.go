@@ -382,6 +382,12 @@ type Symbol struct {
 	// Example: "handlers/agent.go:27:HandleAgent"
 	ID string `json:"id"`
 
+	// StableID identifies the symbol independently of its line number.
+	// Format: "file_path::Scope.name@sighash" (see GenerateStableID).
+	// Set by AssignStableIDs during graph building; survives edits that
+	// only move the symbol up or down the file.
+	StableID string `json:"stable_id,omitempty"`
+
 	// Name is the symbol's identifier as it appears in source code.
 	// Example: "HandleAgent", "UserService", "MAX_RETRIES"
 	Name string `json:"name"`
@@ -434,6 +440,17 @@ type Symbol struct {
 	// Used for cache invalidation and staleness detection.
 	ParsedAtMilli int64 `json:"parsed_at_milli"`
 
+	// Generated marks symbols from generated code (protobuf stubs, mocks,
+	// files with a "Code generated ... DO NOT EDIT." header). Set by
+	// ParseResult.MarkGenerated; tools hide these symbols by default.
+	Generated bool `json:"generated,omitempty"`
+
+	// DependencyDepth is how many imports away from the project this
+	// symbol's package is: 1 for a package the project imports directly,
+	// 2 for one those import, and so on. 0 for project code. Set by
+	// ParseResult.MarkDependency when init resolves dependency source.
+	DependencyDepth int `json:"dependency_depth,omitempty"`
+
 	// Children contains nested symbols (e.g., methods within a class).
 	// May be nil if the symbol has no children.
 	Children []*Symbol `json:"children,omitempty"`
@@ -455,6 +472,25 @@ type Symbol struct {
 	// IT-06 Bug 9: Enables graph-based discovery of type usage across the codebase.
 	// Primitives and language-specific constructs (e.g., str, int, Optional, List) are excluded.
 	TypeReferences []TypeReference `json:"type_references,omitempty"`
+
+	// StructLiterals contains keyed composite literals of qualified types
+	// built in this symbol's body or initializer, such as
+	// &cobra.Command{Use: "serve", RunE: runServe}.
+	// Only populated for Go, and only for literals that wire up a function.
+	// Used by entry point heuristics to find framework registrations.
+	StructLiterals []StructLiteral `json:"struct_literals,omitempty"`
+
+	// FieldAccesses contains the struct fields read and written in this
+	// symbol's body: selector expressions (u.Email) and keyed composite
+	// literal elements (User{Email: e}).
+	// Only populated for Go functions and methods.
+	// Used by the graph builder to create field-level EdgeTypeReferences edges.
+	FieldAccesses []FieldAccess `json:"field_accesses,omitempty"`
+
+	// BodyTokens is the normalized token stream of a function or method body.
+	// Only populated when the parser runs with ParseOptions.ExtractBodyTokens.
+	// Used by the duplication detector to match clones across languages.
+	BodyTokens []BodyToken `json:"-"`
 }
 
 // MethodSignature represents a method's signature for interface implementation detection.
@@ -540,6 +576,17 @@ type CallSite struct {
 	//   - "ctx" for ctx.Done()
 	Receiver string `json:"receiver,omitempty"`
 
+	// Chain holds the hops of a method call whose receiver is itself a
+	// method call: the root receiver, then each method called in order,
+	// ending with Target. Empty for calls that are not chained. Set by the
+	// Go, TypeScript, JavaScript and Python parsers; see extractCallChain.
+	//
+	// Examples:
+	//   - ["client", "Users", "Get", "Profile"] for client.Users().Get(id).Profile()
+	//   - ["app", "route", "get"] for app.route("/x").get(handler)
+	//   - ["NewClient()", "Users", "List"] for NewClient().Users().List()
+	Chain []string `json:"chain,omitempty"`
+
 	// FunctionArgs lists identifiers passed as callback/HOF arguments.
 	// Only populated when a call passes identifiers that may reference other functions.
 	// IT-03a C-1: Enables EdgeTypeReferences from caller to callback arguments.
@@ -548,8 +595,165 @@ type CallSite struct {
 	//   - ["middleware"] for app.use(middleware)
 	//   - ["LoggingInterceptor"] for UseInterceptors(LoggingInterceptor)
 	FunctionArgs []string `json:"function_args,omitempty"`
+
+	// LiteralArgs lists the literal arguments of the call in source order.
+	//
+	// By default only the top-level string literals of calls that also pass
+	// FunctionArgs are kept, so registration calls keep their route, command
+	// or schedule string. With ParseOptions.CaptureLiteralArgs, the Go,
+	// Python, TypeScript and JavaScript parsers keep the string, number and
+	// bool arguments of every call, including keyword arguments and the
+	// fields of object or struct literal arguments, up to
+	// MaxLiteralArgsPerCall.
+	//
+	// Examples:
+	//   - [{Value: "/users/:id"}] for r.GET("/users/:id", getUser)
+	//   - [{Value: "@every 1h"}] for c.AddFunc("@every 1h", cleanup)
+	//   - [{Value: "30", Kind: "number", Name: "timeout", Index: 1}] for
+	//     connect(host, timeout=30) when capturing every call
+	LiteralArgs []LiteralArg `json:"literal_args,omitempty"`
+}
+
+// StringLiteralArgs returns the values of the call's string literal
+// arguments, in source order.
+func (c CallSite) StringLiteralArgs() []string {
+	var values []string
+	for _, arg := range c.LiteralArgs {
+		if arg.Kind == LiteralKindString {
+			values = append(values, arg.Value)
+		}
+	}
+	return values
+}
+
+// LiteralKind is the type of a literal argument.
+type LiteralKind string
+
+// Literal argument kinds.
+const (
+	// LiteralKindString is a string literal without interpolation.
+	LiteralKindString LiteralKind = "string"
+
+	// LiteralKindNumber is an integer or floating point literal, with its
+	// sign if negated.
+	LiteralKindNumber LiteralKind = "number"
+
+	// LiteralKindBool is true or false.
+	LiteralKindBool LiteralKind = "bool"
+)
+
+// Literal argument capture limits.
+const (
+	// MaxLiteralArgsPerCall is the maximum number of literal arguments kept
+	// per call site.
+	MaxLiteralArgsPerCall = 8
+
+	// MaxLiteralArgLength is the maximum length in bytes of a kept literal
+	// value. Longer values are cut at a rune boundary and marked Truncated.
+	MaxLiteralArgLength = 256
+
+	// RedactedLiteral replaces the value of a literal that looks sensitive.
+	RedactedLiteral = "[REDACTED]"
+)
+
+// LiteralArg is a literal argument of a call.
+//
+// Thread Safety: LiteralArg is immutable after creation and safe for concurrent read.
+type LiteralArg struct {
+	// Value is the literal's value: a string unquoted (escapes kept as
+	// written except in Go), a number or bool as written. RedactedLiteral
+	// if Redacted.
+	Value string `json:"value"`
+
+	// Kind is the literal's type.
+	Kind LiteralKind `json:"kind"`
+
+	// Index is the zero-based position of the argument the literal is or
+	// is inside of.
+	Index int `json:"index"`
+
+	// Name is the keyword of a keyword argument, or the field or key of an
+	// object or struct literal argument. Empty for plain arguments.
+	Name string `json:"name,omitempty"`
+
+	// Location is where the literal appears in the source file.
+	Location Location `json:"location"`
+
+	// Truncated is true if Value was cut to MaxLiteralArgLength.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Redacted is true if the literal looked like a secret and its value
+	// was replaced at capture time.
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// StructLiteral represents a keyed composite literal found in a symbol.
+//
+// Description:
+//
+//	StructLiteral captures framework wiring expressed as data rather than as
+//	a call, e.g. a cobra.Command whose Run field names the handler. Field
+//	values are kept as written for identifiers and selectors, unquoted for
+//	string literals, and FuncLiteralValue for inline function literals.
+//	Other values are omitted.
+//
+// Thread Safety: StructLiteral is immutable after creation and safe for concurrent read.
+type StructLiteral struct {
+	// Type is the literal's type as written, without the & or package path.
+	// Example: "cobra.Command".
+	Type string `json:"type"`
+
+	// Fields maps field names to their recorded values.
+	Fields map[string]string `json:"fields"`
+
+	// Location is where the composite literal appears in the source file.
+	Location Location `json:"location"`
 }
 
+// FuncLiteralValue is the StructLiteral field value recorded for an inline
+// function literal.
+const FuncLiteralValue = "func"
+
+// MaxStructLiteralsPerSymbol is the maximum number of struct literals extracted per symbol.
+const MaxStructLiteralsPerSymbol = 100
+
+// FieldAccess represents a read or write of a struct field within a symbol.
+//
+// Description:
+//
+//	FieldAccess records the field name and what is known of the value it
+//	is accessed on, without type checking. The graph builder resolves it
+//	to a field symbol by name, narrowed by ReceiverType when known.
+//
+// Thread Safety: FieldAccess is immutable after creation and safe for concurrent read.
+type FieldAccess struct {
+	// Field is the accessed field name.
+	// Example: "Email" for u.Email.
+	Field string `json:"field"`
+
+	// Receiver is the expression the field is selected from, as written.
+	// Empty for composite literal elements.
+	// Example: "u" for u.Email, "req.User" for req.User.Email.
+	Receiver string `json:"receiver,omitempty"`
+
+	// ReceiverType is the type of Receiver when the parser could tell it
+	// from the declaration of a parameter, receiver or local variable, or
+	// the type of a composite literal, without pointers.
+	// Example: "User", "models.User".
+	ReceiverType string `json:"receiver_type,omitempty"`
+
+	// Write is true when the field is assigned, incremented, set in a
+	// composite literal or has its address taken (&u.Email, as passed to
+	// Scan or Unmarshal). Otherwise the field is read.
+	Write bool `json:"write,omitempty"`
+
+	// Location is where the access occurs in the source file.
+	Location Location `json:"location"`
+}
+
+// MaxFieldAccessesPerSymbol is the maximum number of field accesses extracted per symbol.
+const MaxFieldAccessesPerSymbol = 200
+
 // Validate checks if the CallSite has valid field values.
 //
 // Returns nil if valid, or a ValidationError describing the issue.
@@ -617,10 +821,21 @@ type SymbolMetadata struct {
 	// IT-03a A-3: Enables EdgeTypeReferences from decorated symbol to decorator arguments.
 	DecoratorArgs map[string][]string `json:"decorator_args,omitempty"`
 
+	// DecoratorLiterals maps decorator names to their string literal arguments.
+	// Positional strings are kept unquoted in order; keyword arguments whose
+	// value is a string or a list of strings are kept as "key=a,b".
+	// Example: {"app.route": ["/users", "methods=GET,POST"]}
+	DecoratorLiterals map[string][]string `json:"decorator_literals,omitempty"`
+
 	// TypeParameters lists generic type parameter names.
-	// Example: ["T", "U"] for TypeScript generic function.
+	// Example: ["T", "U"] for TypeScript generic function, ["K", "V"] for Go `Cache[K, V]`.
 	TypeParameters []string `json:"type_parameters,omitempty"`
 
+	// Constraints lists the constraint of each type parameter, parallel to TypeParameters.
+	// Example: ["comparable", "any"] for Go `func Index[K comparable, V any]`.
+	// Only populated for Go declarations that declare their own type parameter list.
+	Constraints []string `json:"constraints,omitempty"`
+
 	// TypeArguments lists type identifiers used as generic arguments in the symbol's type annotations.
 	// IT-03a C-2: Tracks type arguments like User in Promise<User>, Handler in Map<string, Handler>.
 	// Only populated for non-primitive type names (skips string, number, boolean, etc.).
@@ -655,6 +870,11 @@ type SymbolMetadata struct {
 	// Extends is the parent class name for inheritance.
 	Extends string `json:"extends,omitempty"`
 
+	// Mixins lists types whose members are copied into this one, such as
+	// the traits a PHP class uses. The graph builder links them with
+	// EMBEDS edges so their methods are promoted like Go embedded fields.
+	Mixins []string `json:"mixins,omitempty"`
+
 	// Methods contains method signatures for interfaces and types.
 	// For interfaces: lists required method signatures.
 	// For structs/types: lists methods with receivers matching this type.
@@ -683,12 +903,64 @@ type SymbolMetadata struct {
 	// CSSSelector is the full CSS selector for CSS symbols.
 	CSSSelector string `json:"css_selector,omitempty"`
 
+	// HTMLClasses lists the names in an HTML element's class attribute.
+	// On an HTML file symbol it lists the classes of elements without an
+	// id, which have no symbol of their own. Linked to CSS class symbols
+	// by the graph builder.
+	HTMLClasses []string `json:"html_classes,omitempty"`
+
+	// TemplateDefines lists the Go templates an HTML file defines with
+	// {{define "name"}} or {{block "name" .}}.
+	TemplateDefines []string `json:"template_defines,omitempty"`
+
+	// TemplateRefs lists the Go templates an HTML file includes with
+	// {{template "name"}}. Linked to the files defining them by the graph
+	// builder.
+	TemplateRefs []string `json:"template_refs,omitempty"`
+
 	// ParentName is the parent symbol name (e.g., table name for columns).
 	ParentName string `json:"parent_name,omitempty"`
 
 	// SQLConstraints lists SQL constraints for columns (PRIMARY KEY, UNIQUE, etc.).
 	SQLConstraints []string `json:"sql_constraints,omitempty"`
 
+	// DBColumn is the database column a Go struct field maps to, from its
+	// db:"col" or gorm:"column:col" tag. Linked to SQL column symbols by the
+	// graph builder.
+	DBColumn string `json:"db_column,omitempty"`
+
+	// StructTag is the tag of a Go struct field, unquoted.
+	// Example: `json:"email,omitempty" db:"email"`
+	StructTag string `json:"struct_tag,omitempty"`
+
+	// LocalTypes maps the receiver, parameters and local variables of a Go
+	// function or method to their named types, where declared or inferred
+	// from a composite literal or New<Type>() call. The graph builder uses
+	// it to resolve method calls on those variables.
+	// Example: {"s": "Server", "req": "http.Request"}
+	LocalTypes map[string]string `json:"local_types,omitempty"`
+
+	// SQLTableRefs lists tables named in SQL query string literals in a
+	// function body (see ExtractSQLTableRefs). Linked to SQL table and view
+	// symbols by the graph builder.
+	SQLTableRefs []string `json:"sql_table_refs,omitempty"`
+
+	// BuildConstraint is the build constraint of the Go file declaring the
+	// symbol (see GoBuildConstraint), in //go:build syntax.
+	// Example: "linux && amd64"
+	BuildConstraint string `json:"build_constraint,omitempty"`
+
+	// ProtoOptions lists the options set on a protobuf message, field, enum,
+	// service or rpc, as written with whitespace collapsed.
+	// Example: ["deprecated = true", "(google.api.http) = { get: \"/v1/users\" }"]
+	ProtoOptions []string `json:"proto_options,omitempty"`
+
+	// ProtoRequestType and ProtoResponseType are the message types of a
+	// protobuf rpc as written, without the "stream" keyword.
+	// Example: "GetUserRequest", "acme.users.v1.User"
+	ProtoRequestType  string `json:"proto_request_type,omitempty"`
+	ProtoResponseType string `json:"proto_response_type,omitempty"`
+
 	// HeadingLevel is the heading level (1-6) for Markdown headings.
 	HeadingLevel int `json:"heading_level,omitempty"`
 
@@ -707,6 +979,11 @@ type SymbolMetadata struct {
 
 	// LinkTitle is the optional title for link reference definitions.
 	LinkTitle string `json:"link_title,omitempty"`
+
+	// ExternalMembers are the names a project uses from an external
+	// package, sorted. Set on the package's placeholder when the graph
+	// collapses placeholders per package.
+	ExternalMembers []string `json:"external_members,omitempty"`
 }
 
 // GenerateID creates a unique identifier for a symbol based on its location and name.
@@ -741,6 +1018,57 @@ func (s *Symbol) Location() Location {
 	}
 }
 
+// MarkGenerated flags the result and every symbol in it, including nested
+// children up to MaxSymbolDepth levels, as generated code.
+func (r *ParseResult) MarkGenerated() {
+	r.Generated = true
+
+	type stackEntry struct {
+		symbols []*Symbol
+		depth   int
+	}
+	stack := []stackEntry{{symbols: r.Symbols, depth: 0}}
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, s := range entry.symbols {
+			if s == nil {
+				continue
+			}
+			s.Generated = true
+			if len(s.Children) > 0 && entry.depth < MaxSymbolDepth {
+				stack = append(stack, stackEntry{symbols: s.Children, depth: entry.depth + 1})
+			}
+		}
+	}
+}
+
+// MarkDependency flags the result and every symbol in it, including
+// nested children up to MaxSymbolDepth levels, as dependency code depth
+// imports away from the project.
+func (r *ParseResult) MarkDependency(depth int) {
+	r.DependencyDepth = depth
+
+	type stackEntry struct {
+		symbols []*Symbol
+		depth   int
+	}
+	stack := []stackEntry{{symbols: r.Symbols, depth: 0}}
+	for len(stack) > 0 {
+		entry := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, s := range entry.symbols {
+			if s == nil {
+				continue
+			}
+			s.DependencyDepth = depth
+			if len(s.Children) > 0 && entry.depth < MaxSymbolDepth {
+				stack = append(stack, stackEntry{symbols: s.Children, depth: entry.depth + 1})
+			}
+		}
+	}
+}
+
 // SetParsedAt sets the ParsedAtMilli field to the current time.
 func (s *Symbol) SetParsedAt() {
 	s.ParsedAtMilli = time.Now().UnixMilli()
@@ -867,6 +1195,18 @@ type ParseResult struct {
 	// Hash is the SHA256 hash of the file content at parse time.
 	// Used for cache invalidation and staleness detection.
 	Hash string `json:"hash"`
+
+	// BuildConstraint is the build constraint of a Go file, combining its
+	// //go:build line and GOOS/GOARCH filename suffix (see
+	// GoBuildConstraint). Empty when the file builds on every platform.
+	BuildConstraint string `json:"build_constraint,omitempty"`
+
+	// Generated indicates the file is generated code. See MarkGenerated.
+	Generated bool `json:"generated,omitempty"`
+
+	// DependencyDepth is non-zero for a file of a dependency package. See
+	// MarkDependency.
+	DependencyDepth int `json:"dependency_depth,omitempty"`
 }
 
 // Import represents an import statement in source code.
@@ -11,9 +11,11 @@
 package ast
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
+	"reflect"
 	"sync"
 	"testing"
 	"time"
@@ -318,6 +320,48 @@ func TestParseResult_SymbolCount(t *testing.T) {
 	}
 }
 
+func TestParseResult_MarkGenerated(t *testing.T) {
+	method := &Symbol{Name: "Get"}
+	result := &ParseResult{
+		Symbols: []*Symbol{
+			{Name: "Client", Children: []*Symbol{method}},
+			{Name: "NewClient"},
+		},
+	}
+
+	result.MarkGenerated()
+
+	if !result.Generated {
+		t.Error("result should be marked generated")
+	}
+	for _, sym := range append(result.Symbols, method) {
+		if !sym.Generated {
+			t.Errorf("%s should be marked generated", sym.Name)
+		}
+	}
+}
+
+func TestParseResult_MarkDependency(t *testing.T) {
+	method := &Symbol{Name: "Get"}
+	result := &ParseResult{
+		Symbols: []*Symbol{
+			{Name: "Client", Children: []*Symbol{method}},
+			{Name: "NewClient"},
+		},
+	}
+
+	result.MarkDependency(2)
+
+	if result.DependencyDepth != 2 {
+		t.Errorf("result DependencyDepth = %d, want 2", result.DependencyDepth)
+	}
+	for _, sym := range append(result.Symbols, method) {
+		if sym.DependencyDepth != 2 {
+			t.Errorf("%s DependencyDepth = %d, want 2", sym.Name, sym.DependencyDepth)
+		}
+	}
+}
+
 func TestParseResult_HasErrors(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -1093,3 +1137,86 @@ func (m *mockParser) Language() string {
 func (m *mockParser) Extensions() []string {
 	return m.extensions
 }
+
+// TestParseResult_JSONTags checks that every exported field reachable from
+// ParseResult has an explicit json tag, so parse results uploaded to the
+// service decode into the same fields a parser fills in.
+func TestParseResult_JSONTags(t *testing.T) {
+	seen := make(map[reflect.Type]bool)
+	var walk func(typ reflect.Type)
+	walk = func(typ reflect.Type) {
+		for typ.Kind() == reflect.Pointer || typ.Kind() == reflect.Slice || typ.Kind() == reflect.Map {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct || seen[typ] {
+			return
+		}
+		seen[typ] = true
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			tag := f.Tag.Get("json")
+			if tag == "" {
+				t.Errorf("%s.%s has no json tag", typ.Name(), f.Name)
+			}
+			if tag != "-" {
+				walk(f.Type)
+			}
+		}
+	}
+	walk(reflect.TypeOf(ParseResult{}))
+}
+
+func TestParseResult_JSONRoundtrip(t *testing.T) {
+	parser := NewGoParser()
+	content := []byte(`package demo
+
+import "fmt"
+
+type Greeter interface{ Greet() string }
+
+type English struct{ Name string ` + "`json:\"name\"`" + ` }
+
+func (e *English) Greet() string { return fmt.Sprintf("hello %s", e.Name) }
+
+func Run[T any](g Greeter, v T) { _ = g.Greet() }
+`)
+	want, err := parser.Parse(context.Background(), content, "demo/demo.go")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	clearBodyTokens(want.Symbols)
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got ParseResult
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	// Empty slices under omitempty come back nil.
+	if len(want.Errors) == 0 {
+		want.Errors = nil
+	}
+	for i := range want.Imports {
+		if len(want.Imports[i].Names) == 0 {
+			want.Imports[i].Names = nil
+		}
+	}
+	if !reflect.DeepEqual(&got, want) {
+		t.Errorf("round-tripped result differs:\n got %+v\nwant %+v", got, *want)
+	}
+}
+
+// clearBodyTokens drops the body tokens, which are not serialized.
+func clearBodyTokens(symbols []*Symbol) {
+	for _, sym := range symbols {
+		sym.BodyTokens = nil
+		clearBodyTokens(sym.Children)
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/smacker/go-tree-sitter/typescript/tsx"
 	"github.com/smacker/go-tree-sitter/typescript/typescript"
 	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 const (
@@ -196,7 +198,7 @@ func (p *TypeScriptParser) Parse(ctx context.Context, content []byte, filePath s
 	// Log warning for large files
 	if len(content) > WarnFileSize {
 		slog.Warn("parsing large file",
-			slog.String("file", filePath),
+			telemetry.SensitiveLog("file", filePath),
 			slog.Int("size_bytes", len(content)))
 	}
 
@@ -263,6 +265,11 @@ func (p *TypeScriptParser) Parse(ctx context.Context, content []byte, filePath s
 	// Extract declarations (functions, classes, interfaces, types, enums, variables)
 	p.extractDeclarations(ctx, rootNode, content, filePath, result)
 
+	// Normalized body tokens for clone detection
+	if p.parseOptions.ExtractBodyTokens {
+		attachBodyTokens(rootNode, content, result.Symbols)
+	}
+
 	// Validate result before returning
 	if err := result.Validate(); err != nil {
 		recordParseMetrics(ctx, "typescript", time.Since(start), 0, false)
@@ -2018,7 +2025,7 @@ func (p *TypeScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 
 		if entry.depth > MaxCallExpressionDepth {
 			slog.Debug("GR-41: Max call expression depth reached in TypeScript",
-				slog.String("file", filePath),
+				telemetry.SensitiveLog("file", filePath),
 				slog.Int("depth", entry.depth),
 			)
 			continue
@@ -2028,7 +2035,7 @@ func (p *TypeScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 		if nodeCount%100 == 0 {
 			if ctx.Err() != nil {
 				slog.Debug("GR-41: Context cancelled during TypeScript call extraction",
-					slog.String("file", filePath),
+					telemetry.SensitiveLog("file", filePath),
 					slog.Int("calls_found", len(calls)),
 				)
 				return calls, dynImports
@@ -2037,7 +2044,7 @@ func (p *TypeScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 
 		if len(calls) >= MaxCallSitesPerSymbol {
 			slog.Warn("GR-41: Max call sites per symbol reached in TypeScript",
-				slog.String("file", filePath),
+				telemetry.SensitiveLog("file", filePath),
 				slog.Int("limit", MaxCallSitesPerSymbol),
 			)
 			return calls, dynImports
@@ -2067,8 +2074,8 @@ func (p *TypeScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 								},
 							})
 							slog.Debug("IT-06e Bug 4: TS dynamic import() in function body",
-								slog.String("file", filePath),
-								slog.String("path", importPath),
+								telemetry.SensitiveLog("file", filePath),
+								telemetry.SensitiveLog("path", importPath),
 							)
 						}
 						break
@@ -2107,7 +2114,7 @@ func (p *TypeScriptParser) extractCallSites(ctx context.Context, bodyNode *sitte
 	}
 
 	span.SetAttributes(
-		attribute.String("file", filePath),
+		telemetry.SensitiveString("file", filePath),
 		attribute.Int("calls_found", len(calls)),
 		attribute.Int("nodes_traversed", nodeCount),
 	)
@@ -2182,8 +2189,8 @@ func (p *TypeScriptParser) scanForDynamicImports(node *sitter.Node, content []by
 								},
 							})
 							slog.Debug("IT-06e Bug 4: TS dynamic import() at module scope",
-								slog.String("file", filePath),
-								slog.String("path", importPath),
+								telemetry.SensitiveLog("file", filePath),
+								telemetry.SensitiveLog("path", importPath),
 							)
 						}
 						break
@@ -2264,6 +2271,7 @@ func (p *TypeScriptParser) extractSingleCallSite(node *sitter.Node, content []by
 			receiver := string(content[objectNode.StartByte():objectNode.EndByte()])
 			call.Receiver = receiver
 			call.IsMethod = true
+			call.Chain = extractCallChain(jsCallChainSyntax, funcNode, content)
 		}
 
 	default:
@@ -2282,6 +2290,11 @@ func (p *TypeScriptParser) extractSingleCallSite(node *sitter.Node, content []by
 	argsNode := node.ChildByFieldName("arguments")
 	if argsNode != nil {
 		call.FunctionArgs = p.extractCallbackArgIdentifiers(argsNode, content)
+		if len(call.FunctionArgs) > 0 || p.parseOptions.CaptureLiteralArgs || keepsLinkageLiterals(call.Target) {
+			capture := newLiteralArgCapture(filePath, content, p.parseOptions.CaptureLiteralArgs, p.parseOptions.KeepSensitiveLiterals)
+			captureJSLiteralArgs(capture, argsNode)
+			call.LiteralArgs = capture.finish(call.Target)
+		}
 	}
 
 	return call
@@ -2360,6 +2373,12 @@ func (p *TypeScriptParser) extractNewExpressionCallSite(node *sitter.Node, conte
 		return nil
 	}
 
+	if argsNode := node.ChildByFieldName("arguments"); argsNode != nil && p.parseOptions.CaptureLiteralArgs {
+		capture := newLiteralArgCapture(filePath, content, true, p.parseOptions.KeepSensitiveLiterals)
+		captureJSLiteralArgs(capture, argsNode)
+		call.LiteralArgs = capture.finish(call.Target)
+	}
+
 	return call
 }
 
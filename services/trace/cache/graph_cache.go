@@ -20,6 +20,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -185,7 +186,7 @@ func (c *GraphCache) Get(projectRoot string) (*CacheEntry, func(), bool) {
 func (c *GraphCache) GetOrBuild(ctx context.Context, projectRoot string, build BuildFunc) (*CacheEntry, func(), error) {
 	ctx, span := graphCacheTracer.Start(ctx, "GraphCache.GetOrBuild",
 		trace.WithAttributes(
-			attribute.String("project_root", projectRoot),
+			telemetry.SensitiveString("project_root", projectRoot),
 		),
 	)
 	defer span.End()
@@ -209,7 +210,7 @@ func (c *GraphCache) GetOrBuild(ctx context.Context, projectRoot string, build B
 			// Log but continue - staleness check failure shouldn't block
 			slog.Warn("GR-42: staleness check failed, assuming stale",
 				slog.String("graph_id", graphID),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			reason = StalenessHashError
 		}
@@ -307,7 +308,7 @@ func (c *GraphCache) buildAndCache(ctx context.Context, projectRoot, graphID str
 	ctx, span := graphCacheTracer.Start(ctx, "GraphCache.buildAndCache",
 		trace.WithAttributes(
 			attribute.String("graph_id", graphID),
-			attribute.String("project_root", projectRoot),
+			telemetry.SensitiveString("project_root", projectRoot),
 		),
 	)
 	defer span.End()
@@ -329,7 +330,7 @@ func (c *GraphCache) buildAndCache(ctx context.Context, projectRoot, graphID str
 		// Log but continue - we'll just have an empty hash
 		slog.Warn("GR-42: failed to compute source hash for new entry",
 			slog.String("graph_id", graphID),
-			slog.String("error", hashErr.Error()),
+			telemetry.SensitiveLogError("error", hashErr),
 		)
 		sourceHash = ""
 	}
@@ -781,7 +782,7 @@ func (c *GraphCache) Refresh(ctx context.Context, projectRoot string, refresh Re
 	if hashErr != nil {
 		slog.Warn("GR-42: failed to compute source hash during refresh",
 			slog.String("graph_id", entry.GraphID),
-			slog.String("error", hashErr.Error()),
+			telemetry.SensitiveLogError("error", hashErr),
 		)
 		sourceHash = ""
 	}
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -132,7 +133,7 @@ func startCacheSpan(ctx context.Context, operation, projectRoot string) (context
 	return tracer.Start(ctx, "GraphCache."+operation,
 		trace.WithAttributes(
 			attribute.String("cache.operation", operation),
-			attribute.String("cache.project_root", projectRoot),
+			telemetry.SensitiveString("cache.project_root", projectRoot),
 		),
 	)
 }
@@ -28,6 +28,8 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Tracer for staleness operations - uses consistent naming with graph cache.
@@ -223,7 +225,7 @@ func ComputeSourceHash(ctx context.Context, root string) (string, int, error) {
 func ComputeSourceHashWithExtensions(ctx context.Context, root string, extensions map[string]bool) (string, int, error) {
 	ctx, span := stalenessTracer.Start(ctx, "ComputeSourceHash",
 		trace.WithAttributes(
-			attribute.String("root", root),
+			telemetry.SensitiveString("root", root),
 		),
 	)
 	defer span.End()
@@ -265,8 +267,8 @@ func ComputeSourceHashWithExtensions(ctx context.Context, root string, extension
 			permissionErrors = append(permissionErrors, path)
 			if len(permissionErrors) <= 3 {
 				slog.Debug("GR-42: skipping inaccessible path",
-					slog.String("path", path),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLog("path", path),
+					telemetry.SensitiveLogError("error", err),
 				)
 			}
 			return nil
@@ -327,7 +329,7 @@ func ComputeSourceHashWithExtensions(ctx context.Context, root string, extension
 	// H1 Fix: Log summary of permission errors
 	if len(permissionErrors) > 3 {
 		slog.Warn("GR-42: multiple inaccessible paths during hash",
-			slog.String("root", root),
+			telemetry.SensitiveLog("root", root),
 			slog.Int("count", len(permissionErrors)),
 			slog.String("first", permissionErrors[0]),
 		)
@@ -362,7 +364,7 @@ func ComputeSourceHashWithExtensions(ctx context.Context, root string, extension
 	// M4 Fix: Only log at Debug level, reduce noise
 	if duration > 100*time.Millisecond {
 		slog.Debug("GR-42: source hash computation slow",
-			slog.String("root", root),
+			telemetry.SensitiveLog("root", root),
 			slog.Int("file_count", fileCount),
 			slog.Duration("duration", duration),
 		)
@@ -398,7 +400,7 @@ func ComputeSourceHashWithExtensions(ctx context.Context, root string, extension
 //	reason, err := CheckStaleness(ctx, entry)
 //	if err != nil {
 //	    // Hash computation failed - treat as stale but log error
-//	    slog.Warn("staleness check failed", slog.String("error", err.Error()))
+//	    slog.Warn("staleness check failed", telemetry.SensitiveLogError("error", err))
 //	}
 //	if reason != StalenessNone {
 //	    // Entry is stale, needs rebuild
@@ -419,7 +421,7 @@ func CheckStaleness(ctx context.Context, entry *CacheEntry) (StalenessReason, er
 	ctx, span := stalenessTracer.Start(ctx, "CheckStaleness",
 		trace.WithAttributes(
 			attribute.String("graph_id", entry.GraphID),
-			attribute.String("project_root", entry.ProjectRoot),
+			telemetry.SensitiveString("project_root", entry.ProjectRoot),
 			attribute.String("cached_version", entry.BuilderVersion),
 			attribute.String("current_version", GraphBuilderVersion),
 		),
@@ -444,8 +446,8 @@ func CheckStaleness(ctx context.Context, entry *CacheEntry) (StalenessReason, er
 		// M6 Fix: Preserve and return the actual error
 		slog.Warn("GR-42: failed to compute source hash",
 			slog.String("graph_id", entry.GraphID),
-			slog.String("project_root", entry.ProjectRoot),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("project_root", entry.ProjectRoot),
+			telemetry.SensitiveLogError("error", err),
 		)
 		span.RecordError(err)
 		span.SetAttributes(attribute.String("staleness_reason", string(StalenessHashError)))
@@ -0,0 +1,615 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package chunk splits source files into symbol-aligned chunks for
+// embedding pipelines.
+//
+// # Description
+//
+// Each function, method, class or other type becomes one chunk when it
+// fits the token budget. A type that does not fit is chunked member by
+// member; a function that does not fit is split at statement boundaries
+// found with the AST. Code outside any symbol, such as imports, is
+// chunked on its own. Every chunk carries its symbol IDs, line range and
+// scope path.
+//
+// # Thread Safety
+//
+// Split is safe for concurrent use if the estimator is.
+package chunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// Defaults for zero Options fields.
+const (
+	DefaultMaxTokens     = 512
+	DefaultCharsPerToken = 4
+)
+
+// ErrInvalidOptions indicates chunking options out of range.
+var ErrInvalidOptions = errors.New("invalid chunk options")
+
+// KindFile is the Chunk.Kind of code outside any symbol.
+const KindFile = "file"
+
+// TokenEstimator estimates how many tokens a text is for the embedding
+// model's tokenizer.
+type TokenEstimator interface {
+	// EstimateTokens returns the estimated token count of text. It must
+	// not decrease when text grows.
+	EstimateTokens(text string) int
+}
+
+// CharEstimator estimates one token per CharsPerToken bytes, rounded up.
+// A zero CharsPerToken means DefaultCharsPerToken.
+type CharEstimator struct {
+	CharsPerToken int
+}
+
+// EstimateTokens implements TokenEstimator.
+func (e CharEstimator) EstimateTokens(text string) int {
+	per := e.CharsPerToken
+	if per <= 0 {
+		per = DefaultCharsPerToken
+	}
+	return (len(text) + per - 1) / per
+}
+
+// Options configures Split.
+type Options struct {
+	// MaxTokens is the token budget of a chunk. Zero means
+	// DefaultMaxTokens. A single line over the budget still becomes one
+	// chunk.
+	MaxTokens int
+
+	// OverlapTokens is how much of the end of a split part is repeated at
+	// the start of the next part, in whole lines. Zero disables overlap.
+	// Chunks of different symbols never overlap.
+	OverlapTokens int
+
+	// Estimator estimates chunk sizes. Nil means CharEstimator{}.
+	Estimator TokenEstimator
+}
+
+// Validate checks the options.
+//
+// Outputs:
+//
+//	error - ErrInvalidOptions if MaxTokens or OverlapTokens is negative,
+//	  or OverlapTokens is more than half the budget.
+func (o Options) Validate() error {
+	if o.MaxTokens < 0 {
+		return fmt.Errorf("%w: max_tokens must not be negative", ErrInvalidOptions)
+	}
+	if o.OverlapTokens < 0 {
+		return fmt.Errorf("%w: overlap_tokens must not be negative", ErrInvalidOptions)
+	}
+	if o.OverlapTokens > o.withDefaults().MaxTokens/2 {
+		return fmt.Errorf("%w: overlap_tokens must be at most half of max_tokens", ErrInvalidOptions)
+	}
+	return nil
+}
+
+// withDefaults returns the options with zero fields defaulted.
+func (o Options) withDefaults() Options {
+	if o.MaxTokens == 0 {
+		o.MaxTokens = DefaultMaxTokens
+	}
+	if o.Estimator == nil {
+		o.Estimator = CharEstimator{}
+	}
+	return o
+}
+
+// File is one source file to chunk.
+type File struct {
+	// Path is the file path relative to the project root.
+	Path string
+
+	// Language is the file's language, as in ast.Symbol.Language. It
+	// selects the grammar used to find statement boundaries.
+	Language string
+
+	// Content is the file content.
+	Content []byte
+
+	// Symbols are the symbols defined in the file, in any order. Nested
+	// symbols may be listed directly or as Children.
+	Symbols []*ast.Symbol
+}
+
+// Chunk is one symbol-aligned piece of a file.
+type Chunk struct {
+	// ID identifies the chunk: "<file>:<start>-<end>".
+	ID string `json:"chunk_id"`
+
+	// FilePath is the file path relative to the project root.
+	FilePath string `json:"file_path"`
+
+	// Language is the file's language.
+	Language string `json:"language,omitempty"`
+
+	// StartLine and EndLine are the 1-indexed inclusive line range,
+	// including any overlap.
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+
+	// Kind is the kind of the chunk's symbol, e.g. "function", or "file"
+	// for code outside any symbol.
+	Kind string `json:"kind"`
+
+	// ScopePath is the package and the enclosing symbols, outermost
+	// first, ending with the chunk's own symbol.
+	ScopePath []string `json:"scope_path,omitempty"`
+
+	// SymbolIDs are the symbols the chunk covers: its own symbol first,
+	// then the symbols declared in it that have no chunk of their own.
+	SymbolIDs []string `json:"symbol_ids,omitempty"`
+
+	// Part and Parts number the pieces of a split symbol, from 1. Both
+	// are zero when the chunk holds the whole symbol.
+	Part  int `json:"part,omitempty"`
+	Parts int `json:"parts,omitempty"`
+
+	// OverlapLines is how many leading lines repeat the end of the
+	// previous part.
+	OverlapLines int `json:"overlap_lines,omitempty"`
+
+	// TokenEstimate is the estimated size of Content.
+	TokenEstimate int `json:"token_estimate"`
+
+	// Content is the chunk's source.
+	Content string `json:"content"`
+}
+
+// unitKinds are the symbol kinds chunked on their own.
+var unitKinds = map[ast.SymbolKind]bool{
+	ast.SymbolKindFunction:  true,
+	ast.SymbolKindMethod:    true,
+	ast.SymbolKindClass:     true,
+	ast.SymbolKindStruct:    true,
+	ast.SymbolKindInterface: true,
+	ast.SymbolKindType:      true,
+	ast.SymbolKindEnum:      true,
+	ast.SymbolKindComponent: true,
+}
+
+// unit is a symbol chunked on its own, or the whole file at the root.
+type unit struct {
+	sym        *ast.Symbol
+	start, end int
+	scope      []string
+	children   []*unit
+	members    []*ast.Symbol
+}
+
+// splitter holds the state of one Split call.
+type splitter struct {
+	ctx    context.Context
+	file   File
+	opts   Options
+	lines  []string
+	stmts  []int
+	parsed bool
+	out    []Chunk
+}
+
+// Split chunks a file.
+//
+// Description:
+//
+//	Builds the tree of function, method and type symbols by line range
+//	and emits each symbol as one chunk when it fits the budget. A symbol
+//	with nested symbols that does not fit is emitted as its nested
+//	symbols plus chunks for the lines between them; one without is split
+//	at statement boundaries (see ast.StatementStarts), falling back to
+//	blank lines for languages without statement support. Doc comments
+//	directly above a symbol belong to its chunk. Blank stretches and
+//	lines holding only closing brackets are not emitted on their own.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	f - The file. Symbols outside the file's lines are ignored.
+//	opts - The budget, overlap and estimator.
+//
+// Outputs:
+//
+//	[]Chunk - The chunks in source order.
+//	error - ErrInvalidOptions, or the context's error.
+//
+// Thread Safety: Safe for concurrent use if opts.Estimator is.
+func Split(ctx context.Context, f File, opts Options) ([]Chunk, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	lines := strings.Split(string(f.Content), "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	s := &splitter{ctx: ctx, file: f, opts: opts.withDefaults(), lines: lines}
+	root := s.buildTree()
+	s.emitUnit(root)
+	return s.out, ctx.Err()
+}
+
+// buildTree nests the file's unit symbols by line range and assigns every
+// other symbol to the innermost unit containing it.
+func (s *splitter) buildTree() *unit {
+	n := len(s.lines)
+	root := &unit{start: 1, end: n}
+	for _, sym := range flattenSymbols(s.file.Symbols) {
+		if sym.Package != "" {
+			root.scope = []string{sym.Package}
+			break
+		}
+	}
+
+	var syms []*ast.Symbol
+	for _, sym := range flattenSymbols(s.file.Symbols) {
+		if sym.StartLine >= 1 && sym.StartLine <= n {
+			syms = append(syms, sym)
+		}
+	}
+	sort.SliceStable(syms, func(i, j int) bool {
+		a, b := syms[i], syms[j]
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		return s.symEnd(a) > s.symEnd(b)
+	})
+
+	stack := []*unit{root}
+	for _, sym := range syms {
+		end := s.symEnd(sym)
+		for len(stack) > 1 && stack[len(stack)-1].end < end {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1]
+		if !unitKinds[sym.Kind] {
+			parent.members = append(parent.members, sym)
+			continue
+		}
+		u := &unit{sym: sym, start: sym.StartLine, end: end}
+		u.start = s.extendOverComments(u.start, parent)
+		u.scope = append(append([]string(nil), parent.scope...), scopeName(sym, parent)...)
+		parent.children = append(parent.children, u)
+		stack = append(stack, u)
+	}
+	return root
+}
+
+// symEnd returns sym's last line, clamped to the file.
+func (s *splitter) symEnd(sym *ast.Symbol) int {
+	end := sym.EndLine
+	if end < sym.StartLine {
+		end = sym.StartLine
+	}
+	if end > len(s.lines) {
+		end = len(s.lines)
+	}
+	return end
+}
+
+// extendOverComments moves start up over the comment and decorator lines
+// directly above it, staying after parent's first line and its previous
+// child.
+func (s *splitter) extendOverComments(start int, parent *unit) int {
+	limit := parent.start
+	if parent.sym != nil {
+		limit++
+	}
+	if n := len(parent.children); n > 0 && parent.children[n-1].end+1 > limit {
+		limit = parent.children[n-1].end + 1
+	}
+	for start-1 >= limit && isCommentLine(s.lines[start-2]) {
+		start--
+	}
+	return start
+}
+
+// isCommentLine reports whether line is a comment or decorator line.
+func isCommentLine(line string) bool {
+	t := strings.TrimSpace(line)
+	for _, prefix := range []string{"//", "#", "/*", "*", "@"} {
+		if strings.HasPrefix(t, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeName returns the scope path elements sym adds under parent: its
+// name, preceded by its receiver type for a method declared outside its
+// type (Go).
+func scopeName(sym *ast.Symbol, parent *unit) []string {
+	if sym.Kind == ast.SymbolKindMethod && parent.sym == nil && sym.Receiver != "" {
+		recv := strings.TrimLeft(sym.Receiver, "*")
+		if i := strings.IndexByte(recv, '['); i >= 0 {
+			recv = recv[:i]
+		}
+		return []string{recv, sym.Name}
+	}
+	return []string{sym.Name}
+}
+
+// flattenSymbols returns syms and their children, once per symbol ID.
+func flattenSymbols(syms []*ast.Symbol) []*ast.Symbol {
+	seen := make(map[*ast.Symbol]bool)
+	seenIDs := make(map[string]bool)
+	var out []*ast.Symbol
+	var walk func(list []*ast.Symbol)
+	walk = func(list []*ast.Symbol) {
+		for _, sym := range list {
+			if sym == nil || seen[sym] || (sym.ID != "" && seenIDs[sym.ID]) {
+				continue
+			}
+			seen[sym] = true
+			seenIDs[sym.ID] = true
+			out = append(out, sym)
+			walk(sym.Children)
+		}
+	}
+	walk(syms)
+	return out
+}
+
+// emitUnit emits u whole if it fits, else its children and the lines
+// between them, else u split at statements.
+func (s *splitter) emitUnit(u *unit) {
+	if s.ctx.Err() != nil {
+		return
+	}
+	if u.sym != nil && s.fits(u.start, u.end) {
+		s.emit(u, u.start, u.end, 0, unitSymbolIDs(u), 0, 0)
+		return
+	}
+	if u.sym != nil && len(u.children) == 0 {
+		s.splitRange(u, u.start, u.end)
+		return
+	}
+	cur := u.start
+	for _, child := range u.children {
+		if child.start > cur {
+			s.emitGap(u, cur, child.start-1)
+		}
+		s.emitUnit(child)
+		if child.end+1 > cur {
+			cur = child.end + 1
+		}
+	}
+	if cur <= u.end {
+		s.emitGap(u, cur, u.end)
+	}
+}
+
+// emitGap emits the lines start..end of u that belong to no child.
+func (s *splitter) emitGap(u *unit, start, end int) {
+	for start <= end && isTrivialLine(s.lines[start-1]) {
+		start++
+	}
+	for end >= start && isTrivialLine(s.lines[end-1]) {
+		end--
+	}
+	if start > end {
+		return
+	}
+	if s.fits(start, end) {
+		s.emit(u, start, end, 0, s.rangeSymbolIDs(u, start, end), 0, 0)
+		return
+	}
+	s.splitRange(u, start, end)
+}
+
+// isTrivialLine reports whether line is blank or holds only closing
+// brackets and separators.
+func isTrivialLine(line string) bool {
+	return strings.Trim(line, " \t\r})];,") == ""
+}
+
+// part is one piece of a split range.
+type part struct {
+	start, end int
+	overlap    int
+}
+
+// splitRange emits start..end of u in parts that fit the budget, cutting
+// before statement boundaries where possible.
+func (s *splitter) splitRange(u *unit, start, end int) {
+	var parts []part
+	next, overlapStart := start, start
+	for next <= end {
+		pieceStart := overlapStart
+		last := s.lastFitting(pieceStart, next, end)
+		if last < next && pieceStart < next {
+			pieceStart = next
+			last = s.lastFitting(pieceStart, next, end)
+		}
+		if last < next {
+			last = next
+		}
+		if last < end {
+			if b := s.cutBefore(next, last+1); b > 0 {
+				last = b - 1
+			}
+			if s.allTrivial(last+1, end) {
+				last = end
+			}
+		}
+		parts = append(parts, part{start: pieceStart, end: last, overlap: next - pieceStart})
+		overlapStart = s.overlapFrom(pieceStart, last)
+		next = last + 1
+	}
+	for i, p := range parts {
+		ids := s.rangeSymbolIDs(u, p.start+p.overlap, p.end)
+		if len(parts) == 1 {
+			s.emit(u, p.start, p.end, p.overlap, ids, 0, 0)
+			continue
+		}
+		s.emit(u, p.start, p.end, p.overlap, ids, i+1, len(parts))
+	}
+}
+
+// lastFitting returns the last line e in from..max such that
+// pieceStart..e fits the budget, or from-1 if none does.
+func (s *splitter) lastFitting(pieceStart, from, max int) int {
+	lo, hi := from, max
+	best := from - 1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		if s.fits(pieceStart, mid) {
+			best = mid
+			lo = mid + 1
+		} else {
+			hi = mid - 1
+		}
+	}
+	return best
+}
+
+// cutBefore returns the latest line in (start, limit] to start the next
+// part at: a statement start, else a line after a blank line. Returns 0
+// if there is none.
+func (s *splitter) cutBefore(start, limit int) int {
+	stmts := s.statements()
+	i := sort.SearchInts(stmts, limit+1)
+	if i > 0 && stmts[i-1] > start {
+		return stmts[i-1]
+	}
+	for line := limit; line > start; line-- {
+		if strings.TrimSpace(s.lines[line-2]) == "" {
+			return line
+		}
+	}
+	return 0
+}
+
+// statements returns the file's statement start lines, parsing the file
+// on first use. Empty for unsupported languages.
+func (s *splitter) statements() []int {
+	if !s.parsed {
+		s.parsed = true
+		stmts, err := ast.StatementStarts(s.ctx, s.file.Language, s.file.Path, s.file.Content)
+		if err == nil {
+			s.stmts = stmts
+		}
+	}
+	return s.stmts
+}
+
+// overlapFrom returns the first line of the next part: the start of the
+// most trailing lines of the previous part, prevStart..prevEnd, that fit
+// OverlapTokens, or prevEnd+1 for none. The previous part is never
+// repeated whole.
+func (s *splitter) overlapFrom(prevStart, prevEnd int) int {
+	from := prevEnd + 1
+	if s.opts.OverlapTokens == 0 {
+		return from
+	}
+	for line := prevEnd; line > prevStart; line-- {
+		if s.estimate(line, prevEnd) > s.opts.OverlapTokens {
+			break
+		}
+		from = line
+	}
+	return from
+}
+
+// allTrivial reports whether lines start..end are all trivial.
+func (s *splitter) allTrivial(start, end int) bool {
+	for line := start; line <= end; line++ {
+		if !isTrivialLine(s.lines[line-1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// fits reports whether lines start..end fit the budget.
+func (s *splitter) fits(start, end int) bool {
+	return s.estimate(start, end) <= s.opts.MaxTokens
+}
+
+// estimate returns the estimated tokens of lines start..end.
+func (s *splitter) estimate(start, end int) int {
+	return s.opts.Estimator.EstimateTokens(s.text(start, end))
+}
+
+// text returns lines start..end joined by newlines.
+func (s *splitter) text(start, end int) string {
+	return strings.Join(s.lines[start-1:end], "\n")
+}
+
+// emit appends a chunk of lines start..end for u.
+func (s *splitter) emit(u *unit, start, end, overlap int, ids []string, partNum, parts int) {
+	kind := KindFile
+	if u.sym != nil {
+		kind = u.sym.Kind.String()
+	}
+	content := s.text(start, end)
+	s.out = append(s.out, Chunk{
+		ID:            fmt.Sprintf("%s:%d-%d", s.file.Path, start, end),
+		FilePath:      s.file.Path,
+		Language:      s.file.Language,
+		StartLine:     start,
+		EndLine:       end,
+		Kind:          kind,
+		ScopePath:     u.scope,
+		SymbolIDs:     ids,
+		Part:          partNum,
+		Parts:         parts,
+		OverlapLines:  overlap,
+		TokenEstimate: s.opts.Estimator.EstimateTokens(content),
+		Content:       content,
+	})
+}
+
+// unitSymbolIDs returns u's ID and those of every symbol nested in it.
+func unitSymbolIDs(u *unit) []string {
+	ids := []string{u.sym.ID}
+	var walk func(n *unit)
+	walk = func(n *unit) {
+		for _, m := range n.members {
+			ids = append(ids, m.ID)
+		}
+		for _, c := range n.children {
+			ids = append(ids, c.sym.ID)
+			walk(c)
+		}
+	}
+	walk(u)
+	return ids
+}
+
+// rangeSymbolIDs returns u's ID and the IDs of u's members declared in
+// lines start..end.
+func (s *splitter) rangeSymbolIDs(u *unit, start, end int) []string {
+	var ids []string
+	if u.sym != nil {
+		ids = append(ids, u.sym.ID)
+	}
+	for _, m := range u.members {
+		if m.StartLine >= start && m.StartLine <= end {
+			ids = append(ids, m.ID)
+		}
+	}
+	return ids
+}
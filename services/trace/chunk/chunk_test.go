@@ -0,0 +1,210 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package chunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+const storeSource = `package store
+
+import "fmt"
+
+// Store keeps values.
+type Store struct {
+	data map[string]int
+}
+
+// Get returns a value.
+func (s *Store) Get(key string) int {
+	return s.data[key]
+}
+
+func Long(n int) int {
+	total := 0
+	for i := 0; i < n; i++ {
+		total += i
+	}
+	if total > 100 {
+		fmt.Println("big")
+		total = 100
+	}
+	switch n {
+	case 1:
+		total++
+	case 2:
+		total--
+	}
+	return total
+}
+`
+
+// parseGo parses src as store.go.
+func parseGo(t *testing.T, src string) File {
+	t.Helper()
+	result, err := ast.NewGoParser().Parse(context.Background(), []byte(src), "store.go")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	return File{Path: "store.go", Language: "go", Content: []byte(src), Symbols: result.Symbols}
+}
+
+// chunkNames returns the last scope element of each chunk, or its kind.
+func chunkNames(chunks []Chunk) []string {
+	var out []string
+	for _, c := range chunks {
+		name := c.Kind
+		if n := len(c.ScopePath); n > 0 && c.Kind != KindFile {
+			name = c.ScopePath[n-1]
+		}
+		if c.Parts > 0 {
+			name = fmt.Sprintf("%s#%d/%d", name, c.Part, c.Parts)
+		}
+		out = append(out, name)
+	}
+	return out
+}
+
+func TestSplit_OneChunkPerSymbol(t *testing.T) {
+	f := parseGo(t, storeSource)
+	chunks, err := Split(context.Background(), f, Options{})
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if got, want := chunkNames(chunks), []string{"file", "Store", "Get", "Long"}; !slices.Equal(got, want) {
+		t.Fatalf("chunks = %v, want %v", got, want)
+	}
+
+	imports, store, get := chunks[0], chunks[1], chunks[2]
+	if imports.StartLine != 1 || imports.EndLine != 3 || imports.Content != "package store\n\nimport \"fmt\"" {
+		t.Errorf("file chunk = %+v", imports)
+	}
+	if store.StartLine != 5 || !strings.HasPrefix(store.Content, "// Store keeps values.") {
+		t.Errorf("Store chunk does not start with its doc comment: %+v", store)
+	}
+	if get.ID != "store.go:10-13" || !slices.Equal(get.ScopePath, []string{"store", "Store", "Get"}) || get.Kind != "method" {
+		t.Errorf("Get chunk = %+v", get)
+	}
+	if len(get.SymbolIDs) == 0 || !strings.Contains(get.SymbolIDs[0], "Get") {
+		t.Errorf("Get symbol IDs = %v", get.SymbolIDs)
+	}
+	if get.TokenEstimate != (CharEstimator{}).EstimateTokens(get.Content) {
+		t.Errorf("token estimate = %d", get.TokenEstimate)
+	}
+}
+
+func TestSplit_LongSymbolAtStatements(t *testing.T) {
+	f := parseGo(t, storeSource)
+	chunks, err := Split(context.Background(), f, Options{MaxTokens: 20})
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	lines := strings.Split(storeSource, "\n")
+	var parts []Chunk
+	for _, c := range chunks {
+		if c.Parts > 0 && c.ScopePath[len(c.ScopePath)-1] == "Long" {
+			parts = append(parts, c)
+		}
+	}
+	if len(parts) < 3 {
+		t.Fatalf("Long split into %v, want several parts", chunkNames(chunks))
+	}
+	statementStarts := map[int]bool{}
+	stmts, err := ast.StatementStarts(context.Background(), "go", "store.go", f.Content)
+	if err != nil {
+		t.Fatalf("StatementStarts: %v", err)
+	}
+	for _, line := range stmts {
+		statementStarts[line] = true
+	}
+	for i, p := range parts {
+		if p.Part != i+1 || p.Parts != len(parts) || p.Kind != "function" {
+			t.Errorf("part %d = %+v", i, p)
+		}
+		if i > 0 {
+			if !statementStarts[p.StartLine] {
+				t.Errorf("part %d starts at line %d (%q), not a statement", i+1, p.StartLine, lines[p.StartLine-1])
+			}
+			if p.StartLine != parts[i-1].EndLine+1 {
+				t.Errorf("part %d starts at %d after part ending at %d", i+1, p.StartLine, parts[i-1].EndLine)
+			}
+		}
+		if p.TokenEstimate > 20 && p.StartLine != p.EndLine {
+			t.Errorf("part %d has %d tokens", i+1, p.TokenEstimate)
+		}
+	}
+	if last := parts[len(parts)-1]; last.EndLine != 31 {
+		t.Errorf("last part ends at %d, want the closing brace on 31", last.EndLine)
+	}
+}
+
+func TestSplit_Overlap(t *testing.T) {
+	f := parseGo(t, storeSource)
+	chunks, err := Split(context.Background(), f, Options{MaxTokens: 40, OverlapTokens: 10})
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	var prev *Chunk
+	overlapped := false
+	for i := range chunks {
+		c := &chunks[i]
+		if c.Parts == 0 {
+			prev = nil
+			continue
+		}
+		if prev != nil && c.OverlapLines > 0 {
+			overlapped = true
+			if c.StartLine != prev.EndLine-c.OverlapLines+1 {
+				t.Errorf("part %d starts at %d with %d overlap lines after part ending at %d", c.Part, c.StartLine, c.OverlapLines, prev.EndLine)
+			}
+			overlapText := strings.Join(strings.Split(c.Content, "\n")[:c.OverlapLines], "\n")
+			if !strings.HasSuffix(prev.Content, overlapText) {
+				t.Errorf("overlap %q is not the end of the previous part", overlapText)
+			}
+		}
+		if c.Part == 1 && c.OverlapLines != 0 {
+			t.Errorf("first part has overlap: %+v", c)
+		}
+		prev = c
+	}
+	if !overlapped {
+		t.Errorf("no part carried overlap: %v", chunkNames(chunks))
+	}
+}
+
+func TestSplit_UnsupportedLanguageUsesBlankLines(t *testing.T) {
+	src := "a = 1\nb = 2\n\nc = 3\nd = 4\n"
+	chunks, err := Split(context.Background(), File{Path: "x.conf", Language: "conf", Content: []byte(src)}, Options{MaxTokens: 4})
+	if err != nil {
+		t.Fatalf("Split: %v", err)
+	}
+	if len(chunks) != 2 || chunks[0].EndLine != 3 || chunks[1].StartLine != 4 {
+		t.Errorf("chunks = %+v", chunks)
+	}
+}
+
+func TestOptions_Validate(t *testing.T) {
+	for _, opts := range []Options{{MaxTokens: -1}, {OverlapTokens: -1}, {MaxTokens: 100, OverlapTokens: 60}} {
+		if err := opts.Validate(); !errors.Is(err, ErrInvalidOptions) {
+			t.Errorf("Validate(%+v) = %v, want ErrInvalidOptions", opts, err)
+		}
+	}
+	if err := (Options{MaxTokens: 100, OverlapTokens: 50}).Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleChunks splits a project's files into symbol-aligned chunks.
+//
+// Description:
+//
+//	POST /v1/trace/chunks with a ChunksRequest. The project must have
+//	been initialized; chunks follow the symbols of its graph. Pass
+//	next_cursor back as cursor to page through large projects.
+//
+// Response:
+//
+//	200 OK: ChunksResponse
+//	400 Bad Request: Invalid body, options, patterns or cursor, or graph
+//	  not initialized
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleChunks(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleChunks")
+
+	var req ChunksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body: project_root is required")
+		return
+	}
+
+	resp, err := h.svc.Chunks(c.Request.Context(), req)
+	switch {
+	case errors.Is(err, ErrInvalidChunkRequest):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+		return
+	case isGraphStateError(err):
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+			WithDetails("Call /v1/trace/init for project_root first").Write(c)
+		return
+	case err != nil:
+		logger.Error("Chunking failed", "project_root", req.ProjectRoot, telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to chunk project")
+		return
+	}
+
+	logger.Info("Chunked project",
+		"project_root", req.ProjectRoot,
+		"chunks", resp.Count,
+		"more", resp.NextCursor != "",
+		"errors", len(resp.Errors),
+		"latency_ms", resp.LatencyMs)
+	c.JSON(http.StatusOK, resp)
+}
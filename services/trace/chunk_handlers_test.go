@@ -0,0 +1,108 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+)
+
+func TestHandlers_HandleChunks(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	root := t.TempDir()
+	files := map[string]string{
+		"a.go":     "package demo\n\nfunc A() int {\n\treturn 1\n}\n\nfunc B() int {\n\treturn 2\n}\n",
+		"b.go":     "package demo\n\n// C calls A.\nfunc C() int {\n\treturn A()\n}\n",
+		"sub/c.go": "package sub\n\ntype T struct {\n\tX int\n}\n",
+	}
+	for name, src := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	body := fmt.Sprintf(`{"project_root": %q}`, root)
+	if w := postJSON(router, "/v1/trace/chunks", body); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), string(apierror.CodeGraphNotInitialized)) {
+		t.Fatalf("before init = %d: %s", w.Code, w.Body.String())
+	}
+	if _, err := svc.Init(context.Background(), root, []string{"go"}, nil); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	// Page two chunks at a time and collect every chunk.
+	var all []string
+	cursor := ""
+	for page := 0; page < 10; page++ {
+		w := postJSON(router, "/v1/trace/chunks", fmt.Sprintf(`{"project_root": %q, "limit": 2, "cursor": %q}`, root, cursor))
+		if w.Code != http.StatusOK {
+			t.Fatalf("page %d = %d: %s", page, w.Code, w.Body.String())
+		}
+		var resp ChunksResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if resp.Count != len(resp.Chunks) || resp.Count > 2 {
+			t.Errorf("page %d count = %d, chunks = %d", page, resp.Count, len(resp.Chunks))
+		}
+		for _, c := range resp.Chunks {
+			all = append(all, fmt.Sprintf("%s:%s", c.ID, c.Kind))
+			if c.Kind == "function" && (len(c.SymbolIDs) == 0 || c.ScopePath[0] != "demo") {
+				t.Errorf("chunk %s metadata = %+v", c.ID, c)
+			}
+		}
+		if cursor = resp.NextCursor; cursor == "" {
+			break
+		}
+	}
+	want := []string{
+		"a.go:1-1:file", "a.go:3-5:function", "a.go:7-9:function",
+		"b.go:1-1:file", "b.go:3-6:function",
+		"sub/c.go:1-1:file", "sub/c.go:3-5:struct",
+	}
+	if strings.Join(all, " ") != strings.Join(want, " ") {
+		t.Errorf("chunks = %v\nwant %v", all, want)
+	}
+
+	w := postJSON(router, "/v1/trace/chunks", fmt.Sprintf(`{"project_root": %q, "include": ["sub/**"]}`, root))
+	var filtered ChunksResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &filtered); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if filtered.Count != 2 || filtered.Chunks[1].FilePath != "sub/c.go" || filtered.NextCursor != "" {
+		t.Errorf("filtered = %+v", filtered)
+	}
+
+	for _, bad := range []string{
+		`"overlap_tokens": 400`,
+		`"max_tokens": 100000`,
+		`"limit": 5000`,
+		`"cursor": "!!"`,
+		`"include": ["["]`,
+	} {
+		w := postJSON(router, "/v1/trace/chunks", fmt.Sprintf(`{"project_root": %q, %s}`, root, bad))
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), string(apierror.CodeInvalidParameter)) {
+			t.Errorf("%s = %d: %s", bad, w.Code, w.Body.String())
+		}
+	}
+}
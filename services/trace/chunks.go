@@ -0,0 +1,209 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/chunk"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
+)
+
+// Chunk request limits.
+const (
+	maxChunkTokens     = 32768
+	defaultChunksLimit = 200
+	maxChunksLimit     = 2000
+)
+
+// Chunks splits the files of an initialized project into symbol-aligned
+// chunks for embedding.
+//
+// Description:
+//
+//	Uses the symbols of the project's working-tree graph to chunk each
+//	file read from disk (see chunk.Split): one chunk per function,
+//	method or type when it fits max_tokens, larger ones split at
+//	statement boundaries. Files are visited in path order and the result
+//	is paged by chunk; the cursor names the file and chunk to resume at,
+//	so a page never re-chunks the files before it. Files that cannot be
+//	read are listed in Errors and skipped.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	req - The project, budget, file filter and page.
+//
+// Outputs:
+//
+//	*ChunksResponse - The page of chunks. LatencyMs is set.
+//	error - ErrInvalidChunkRequest for bad options, patterns or cursor;
+//	  ErrGraphNotInitialized or ErrGraphExpired if the project has no
+//	  graph; or the context's error.
+//
+// Thread Safety: Safe for concurrent use if ChunkTokenEstimator is.
+func (s *Service) Chunks(ctx context.Context, req ChunksRequest) (*ChunksResponse, error) {
+	start := time.Now()
+	opts := chunk.Options{
+		MaxTokens:     req.MaxTokens,
+		OverlapTokens: req.OverlapTokens,
+		Estimator:     s.config.ChunkTokenEstimator,
+	}
+	if req.MaxTokens > maxChunkTokens {
+		return nil, fmt.Errorf("%w: max_tokens must be at most %d", ErrInvalidChunkRequest, maxChunkTokens)
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidChunkRequest, err)
+	}
+	limit := req.Limit
+	switch {
+	case limit < 0 || limit > maxChunksLimit:
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidChunkRequest, maxChunksLimit)
+	case limit == 0:
+		limit = defaultChunksLimit
+	}
+	for _, pattern := range append(append([]string(nil), req.Include...), req.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, fmt.Errorf("%w: bad file pattern %q", ErrInvalidChunkRequest, pattern)
+		}
+	}
+	fromFile, skip, err := decodeChunkCursor(req.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	graphID := s.projectGraphID(req.ProjectRoot, "")
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+
+	files := chunkableFiles(cached, manifest.NewGlobMatcher(req.Include, req.Exclude))
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		if path >= fromFile {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	resp := &ChunksResponse{GraphID: graphID, Chunks: []chunk.Chunk{}}
+	for _, path := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if path != fromFile {
+			skip = 0
+		}
+		symbols := files[path]
+		absPath, err := validation.ResolveWithin(cached.ProjectRoot, path)
+		if err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		content, err := os.ReadFile(absPath)
+		if err != nil {
+			resp.Errors = append(resp.Errors, fmt.Sprintf("%s: %v", path, err))
+			continue
+		}
+		chunks, err := chunk.Split(ctx, chunk.File{
+			Path:     path,
+			Language: symbols[0].Language,
+			Content:  content,
+			Symbols:  symbols,
+		}, opts)
+		if err != nil {
+			return nil, err
+		}
+		if skip > len(chunks) {
+			skip = len(chunks)
+		}
+		chunks = chunks[skip:]
+		if room := limit - len(resp.Chunks); len(chunks) > room {
+			resp.Chunks = append(resp.Chunks, chunks[:room]...)
+			resp.NextCursor = encodeChunkCursor(path, skip+room)
+			break
+		}
+		resp.Chunks = append(resp.Chunks, chunks...)
+		if len(resp.Chunks) == limit {
+			if next := nextPath(paths, path); next != "" {
+				resp.NextCursor = encodeChunkCursor(next, 0)
+			}
+			break
+		}
+	}
+	resp.Count = len(resp.Chunks)
+	resp.LatencyMs = time.Since(start).Milliseconds()
+	return resp, nil
+}
+
+// chunkableFiles returns the project's own symbols by file, for the files
+// matcher accepts. Dependency symbols and placeholders are left out.
+func chunkableFiles(cached *CachedGraph, matcher *manifest.GlobMatcher) map[string][]*ast.Symbol {
+	files := make(map[string][]*ast.Symbol)
+	for _, node := range cached.Graph.Nodes() {
+		sym := node.Symbol
+		if sym == nil || sym.FilePath == "" || sym.DependencyDepth > 0 || sym.Kind == ast.SymbolKindExternal {
+			continue
+		}
+		if !matcher.Match(sym.FilePath) {
+			continue
+		}
+		files[sym.FilePath] = append(files[sym.FilePath], sym)
+	}
+	return files
+}
+
+// nextPath returns the path after path in sorted paths, or "".
+func nextPath(paths []string, path string) string {
+	i := sort.SearchStrings(paths, path)
+	if i+1 < len(paths) {
+		return paths[i+1]
+	}
+	return ""
+}
+
+// encodeChunkCursor returns the cursor resuming at chunk skip of path.
+func encodeChunkCursor(path string, skip int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(skip) + ":" + path))
+}
+
+// decodeChunkCursor parses a cursor from encodeChunkCursor. An empty
+// cursor starts at the first file.
+func decodeChunkCursor(cursor string) (string, int, error) {
+	if cursor == "" {
+		return "", 0, nil
+	}
+	errBad := fmt.Errorf("%w: malformed cursor", ErrInvalidChunkRequest)
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, errBad
+	}
+	n, path, ok := strings.Cut(string(raw), ":")
+	if !ok || path == "" {
+		return "", 0, errBad
+	}
+	skip, err := strconv.Atoi(n)
+	if err != nil || skip < 0 {
+		return "", 0, errBad
+	}
+	return path, skip, nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/rag"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
@@ -81,9 +82,17 @@ func RegisterExploreTools(registry *Registry, g *graph.Graph, idx *index.SymbolI
 	// These expose source code reading and navigation for answering
 	// questions like "explain X", "show me this file", "what's in this file?"
 	registry.Register(NewReadSymbolTool(g, idx))
+	registry.Register(NewReadSymbolSourceTool(g, idx))
 	registry.Register(NewReadFileTool(g))
 	registry.Register(NewGetSignatureTool(g, idx))
 	registry.Register(NewListSymbolsInFileTool(g, idx))
+	registry.Register(NewFindOwnersTool(g, idx))
+	registry.Register(NewFindLiteralUsagesTool(g))
+	registry.Register(NewExplainImplementationTool(g))
+
+	// Level 4c: Structured graph query for questions no dedicated tool
+	// answers. Ranked below the dedicated tools.
+	registry.Register(NewGraphQueryTool(g))
 
 	// Level 5: Graph analytics tools (GR-02 to GR-05, GR-12/GR-13, GR-15)
 	// These wrap GraphAnalytics for code quality insights.
@@ -107,7 +116,7 @@ func RegisterExploreTools(registry *Registry, g *graph.Graph, idx *index.SymbolI
 				)
 			} else if fcErr != nil {
 				slog.Warn("GR-60: file classification failed, tools will use heuristic fallback",
-					slog.String("error", fcErr.Error()),
+					telemetry.SensitiveLogError("error", fcErr),
 				)
 			}
 
@@ -197,10 +206,10 @@ func (t *findEntryPointsTool) Definition() ToolDefinition {
 		Parameters: map[string]ParamDef{
 			"type": {
 				Type:        ParamTypeString,
-				Description: "Type of entry point to find: 'main', 'handler', 'command', 'test', 'lambda', 'grpc', or 'all'",
+				Description: "Type of entry point to find: 'main', 'handler', 'command', 'test', 'lambda', 'grpc', 'scheduled', or 'all'",
 				Required:    false,
 				Default:     "all",
-				Enum:        []any{"main", "handler", "command", "test", "lambda", "grpc", "all"},
+				Enum:        []any{"main", "handler", "command", "test", "lambda", "grpc", "scheduled", "all"},
 			},
 			"package": {
 				Type:        ParamTypeString,
@@ -228,6 +237,10 @@ func (t *findEntryPointsTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findEntryPointsTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), explore.EntryPointResult{})
+}
+
 func (t *findEntryPointsTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	ctx, span := adaptersTracer.Start(ctx, "findEntryPointsTool.Execute",
 		trace.WithAttributes(attribute.String("tool", "find_entry_points")))
@@ -294,11 +307,11 @@ func (t *traceDataFlowTool) Category() ToolCategory {
 func (t *traceDataFlowTool) Definition() ToolDefinition {
 	return ToolDefinition{
 		Name:        "trace_data_flow",
-		Description: "Traces data flow through function calls, identifying sources, transforms, and sinks",
+		Description: "Traces data flow through function calls, identifying sources, transforms, and sinks. For a Go struct field, lists its writers, readers and serialization points",
 		Parameters: map[string]ParamDef{
 			"symbol_id": {
 				Type:        ParamTypeString,
-				Description: "The symbol ID to start tracing from",
+				Description: "The symbol ID to start tracing from; a Go struct field ID traces that field",
 				Required:    true,
 			},
 			"max_hops": {
@@ -316,6 +329,10 @@ func (t *traceDataFlowTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *traceDataFlowTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), explore.DataFlow{})
+}
+
 func (t *traceDataFlowTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	ctx, span := adaptersTracer.Start(ctx, "traceDataFlowTool.Execute",
 		trace.WithAttributes(attribute.String("tool", "trace_data_flow")))
@@ -396,6 +413,10 @@ func (t *traceErrorFlowTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *traceErrorFlowTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), explore.ErrorFlow{})
+}
+
 func (t *traceErrorFlowTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	ctx, span := adaptersTracer.Start(ctx, "traceErrorFlowTool.Execute",
 		trace.WithAttributes(attribute.String("tool", "trace_error_flow")))
@@ -478,6 +499,10 @@ func (t *buildMinimalContextTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *buildMinimalContextTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), explore.MinimalContext{})
+}
+
 func (t *buildMinimalContextTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	ctx, span := adaptersTracer.Start(ctx, "buildMinimalContextTool.Execute",
 		trace.WithAttributes(attribute.String("tool", "build_minimal_context")))
@@ -505,7 +530,7 @@ func (t *buildMinimalContextTool) Execute(ctx context.Context, params TypedParam
 			resolvedID = matches[0].ID
 			slog.Debug("GR-47: resolved partial symbol name to full ID",
 				slog.String("partial", symbolID),
-				slog.String("resolved", resolvedID),
+				telemetry.SensitiveLog("resolved", resolvedID),
 			)
 		} else if len(matches) > 1 {
 			// Multiple matches - try to find the best one (prefer functions over types)
@@ -514,7 +539,7 @@ func (t *buildMinimalContextTool) Execute(ctx context.Context, params TypedParam
 					resolvedID = m.ID
 					slog.Debug("GR-47: resolved partial symbol name (multiple matches, chose function)",
 						slog.String("partial", symbolID),
-						slog.String("resolved", resolvedID),
+						telemetry.SensitiveLog("resolved", resolvedID),
 						slog.Int("total_matches", len(matches)),
 					)
 					break
@@ -525,7 +550,7 @@ func (t *buildMinimalContextTool) Execute(ctx context.Context, params TypedParam
 				resolvedID = matches[0].ID
 				slog.Debug("GR-47: resolved partial symbol name (multiple matches, chose first)",
 					slog.String("partial", symbolID),
-					slog.String("resolved", resolvedID),
+					telemetry.SensitiveLog("resolved", resolvedID),
 					slog.Int("total_matches", len(matches)),
 				)
 			}
@@ -608,6 +633,10 @@ func (t *findSimilarCodeTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findSimilarCodeTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), explore.SimilarCode{})
+}
+
 func (t *findSimilarCodeTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	ctx, span := adaptersTracer.Start(ctx, "findSimilarCodeTool.Execute",
 		trace.WithAttributes(attribute.String("tool", "find_similar_code")))
@@ -702,6 +731,10 @@ func (t *summarizeFileTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *summarizeFileTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), explore.FileSummary{})
+}
+
 func (t *summarizeFileTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	ctx, span := adaptersTracer.Start(ctx, "summarizeFileTool.Execute",
 		trace.WithAttributes(attribute.String("tool", "summarize_file")))
@@ -771,6 +804,10 @@ func (t *findConfigUsageTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findConfigUsageTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), explore.ConfigUsage{})
+}
+
 func (t *findConfigUsageTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	ctx, span := adaptersTracer.Start(ctx, "findConfigUsageTool.Execute",
 		trace.WithAttributes(attribute.String("tool", "find_config_usage")))
@@ -897,6 +934,10 @@ func (t *listPackagesTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *listPackagesTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), ListPackagesResult{})
+}
+
 // PackageInfo contains information about a discovered package.
 type PackageInfo struct {
 	Name        string   `json:"name"`
@@ -1107,6 +1148,7 @@ func NewMockTool(name string, category ToolCategory) *MockTool {
 func (t *MockTool) Name() string               { return t.name }
 func (t *MockTool) Category() ToolCategory     { return t.category }
 func (t *MockTool) Definition() ToolDefinition { return t.definition }
+func (t *MockTool) Schema() ToolSchema         { return NewToolSchema(t.definition, nil) }
 func (t *MockTool) WithDefinition(d ToolDefinition) *MockTool {
 	t.definition = d
 	return t
@@ -1146,10 +1188,10 @@ func StaticToolDefinitions() []ToolDefinition {
 			Parameters: map[string]ParamDef{
 				"type": {
 					Type:        ParamTypeString,
-					Description: "Type of entry point to find: 'main', 'handler', 'command', 'test', 'lambda', 'grpc', or 'all'",
+					Description: "Type of entry point to find: 'main', 'handler', 'command', 'test', 'lambda', 'grpc', 'scheduled', or 'all'",
 					Required:    false,
 					Default:     "all",
-					Enum:        []any{"main", "handler", "command", "test", "lambda", "grpc", "all"},
+					Enum:        []any{"main", "handler", "command", "test", "lambda", "grpc", "scheduled", "all"},
 				},
 				"package": {
 					Type:        ParamTypeString,
@@ -1847,6 +1889,7 @@ func StaticToolDefinitions() []ToolDefinition {
 					"User asks about just the signature — use get_signature.",
 			},
 		},
+		readSymbolSourceDefinition(),
 		{
 			Name: "read_file",
 			Description: "Read a file or line range from the project. " +
@@ -1941,5 +1984,9 @@ func StaticToolDefinitions() []ToolDefinition {
 					"User wants to find a symbol by name — use find_symbol.",
 			},
 		},
+		findOwnersDefinition(),
+		findLiteralUsagesDefinition(),
+		explainImplementationDefinition(),
+		graphQueryDefinition(),
 	}
 }
@@ -0,0 +1,119 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// annotationParamDef is the shared definition of the optional "annotation"
+// parameter.
+//
+// Description:
+//
+//	Annotations are organizational key/value pairs attached to symbols
+//	through the annotations API or .trace/annotations.yaml. The parameter
+//	restricts results to symbols with an annotation key, or with a key
+//	and value.
+var annotationParamDef = ParamDef{
+	Type:        ParamTypeString,
+	Description: "Only symbols with this annotation, as 'key' or 'key=value' (e.g. 'deprecated', 'tier=critical') (optional)",
+	Required:    false,
+}
+
+// parseAnnotationParam extracts and normalizes the "annotation" parameter.
+//
+// Outputs:
+//   - string: The filter in canonical form, or "" when absent.
+//   - error: Non-nil if the filter has no key.
+func parseAnnotationParam(params map[string]any) (string, error) {
+	raw, ok := params["annotation"]
+	if !ok {
+		return "", nil
+	}
+	s, _ := parseStringParam(raw)
+	filter, err := graph.ParseAnnotationFilter(s)
+	if err != nil {
+		return "", err
+	}
+	return filter.String(), nil
+}
+
+// annotationFilterOf parses a filter already normalized by
+// parseAnnotationParam.
+func annotationFilterOf(s string) graph.AnnotationFilter {
+	filter, _ := graph.ParseAnnotationFilter(s)
+	return filter
+}
+
+// annotatedSymbols returns the symbols of g matching filter, sorted by ID.
+//
+// Thread Safety: This function is safe for concurrent use.
+func annotatedSymbols(g *graph.Graph, filter graph.AnnotationFilter) []*ast.Symbol {
+	nodes := g.AnnotatedNodes(filter)
+	symbols := make([]*ast.Symbol, 0, len(nodes))
+	for _, node := range nodes {
+		if node.Symbol != nil {
+			symbols = append(symbols, node.Symbol)
+		}
+	}
+	return symbols
+}
+
+// filterSymbolsByAnnotation keeps the symbols whose annotations match filter.
+//
+// Inputs:
+//   - g: The graph holding the annotations.
+//   - symbols: Symbols to filter. Nil entries are dropped.
+//   - filter: The filter. The zero filter keeps every non-nil symbol.
+//
+// Outputs:
+//   - []*ast.Symbol: The matching symbols, in input order.
+//
+// Thread Safety: This function is safe for concurrent use.
+func filterSymbolsByAnnotation(g *graph.Graph, symbols []*ast.Symbol, filter graph.AnnotationFilter) []*ast.Symbol {
+	filtered := make([]*ast.Symbol, 0, len(symbols))
+	for _, sym := range symbols {
+		if sym != nil && filter.Matches(g.AnnotationsOf(sym)) {
+			filtered = append(filtered, sym)
+		}
+	}
+	return filtered
+}
+
+// annotationsText renders the annotations of sym for text output, or ""
+// when it has none.
+func annotationsText(g *graph.Graph, sym *ast.Symbol) string {
+	return graph.FormatAnnotations(g.AnnotationsOf(sym))
+}
+
+// annotationsOfID returns the annotations of the symbol with node ID id,
+// or nil if it has none or is not in g.
+func annotationsOfID(g *graph.Graph, id string) map[string]string {
+	if !g.HasAnnotations() {
+		return nil
+	}
+	node, ok := g.GetNode(id)
+	if !ok {
+		return nil
+	}
+	return g.AnnotationsOf(node.Symbol)
+}
+
+// targetAnnotationsSuffix renders the annotations of the symbol with node
+// ID id as a text suffix, or "" when it has none.
+func targetAnnotationsSuffix(g *graph.Graph, id string) string {
+	if annotations := graph.FormatAnnotations(annotationsOfID(g, id)); annotations != "" {
+		return " [" + annotations + "]"
+	}
+	return ""
+}
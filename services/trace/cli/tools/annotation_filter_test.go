@@ -0,0 +1,145 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// testAnnotations is an annotation source over a fixed map.
+type testAnnotations map[string]map[string]string
+
+func (a testAnnotations) Of(stableID string) map[string]string { return a[stableID] }
+
+func (a testAnnotations) SymbolIDs() []string {
+	ids := make([]string, 0, len(a))
+	for id := range a {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// createAnnotatedGraph creates a graph where main and initServer call the
+// deprecated parseConfig and initServer is annotated tier=critical.
+func createAnnotatedGraph(t *testing.T) (*graph.Graph, *index.SymbolIndex) {
+	t.Helper()
+	g := graph.NewGraph("/test")
+	idx := index.NewSymbolIndex()
+
+	symbols := make(map[string]*ast.Symbol)
+	for i, name := range []string{"parseConfig", "main", "initServer"} {
+		file := name + ".go"
+		sym := &ast.Symbol{
+			ID:        file + ":1:" + name,
+			StableID:  fmt.Sprintf("%s::%s@%08x", file, name, i),
+			Name:      name,
+			Kind:      ast.SymbolKindFunction,
+			FilePath:  file,
+			StartLine: 1,
+			EndLine:   5,
+			Package:   "app",
+			Language:  "go",
+		}
+		g.AddNode(sym)
+		if err := idx.Add(sym); err != nil {
+			t.Fatalf("index %s: %v", name, err)
+		}
+		symbols[name] = sym
+	}
+	for _, caller := range []string{"main", "initServer"} {
+		g.AddEdge(symbols[caller].ID, symbols["parseConfig"].ID, graph.EdgeTypeCalls, ast.Location{FilePath: symbols[caller].FilePath, StartLine: 2})
+	}
+	graph.RecordAnnotations(g, testAnnotations{
+		symbols["parseConfig"].StableID: {"deprecated": "use loadConfig"},
+		symbols["initServer"].StableID:  {"tier": "critical"},
+	})
+	g.Freeze()
+	return g, idx
+}
+
+func TestFindSymbolTool_AnnotationFilter(t *testing.T) {
+	ctx := context.Background()
+	g, idx := createAnnotatedGraph(t)
+	tool := NewFindSymbolTool(g, idx)
+
+	// Annotation alone lists every annotated symbol.
+	result, err := tool.Execute(ctx, MapParams{Params: map[string]any{"annotation": "deprecated"}})
+	if err != nil || !result.Success {
+		t.Fatalf("Execute: %v %+v", err, result)
+	}
+	output := result.Output.(FindSymbolOutput)
+	if output.MatchCount != 1 || output.Symbols[0].Name != "parseConfig" || output.Symbols[0].Annotations["deprecated"] != "use loadConfig" {
+		t.Errorf("output = %+v, want parseConfig with its annotation", output)
+	}
+	if !strings.Contains(result.OutputText, "Annotations: deprecated: use loadConfig") {
+		t.Errorf("text does not show annotations:\n%s", result.OutputText)
+	}
+
+	// With a name, the annotation filters the matches.
+	result, _ = tool.Execute(ctx, MapParams{Params: map[string]any{"name": "main", "annotation": "tier=critical"}})
+	if result.Output.(FindSymbolOutput).MatchCount != 0 {
+		t.Errorf("main matched tier=critical: %s", result.OutputText)
+	}
+
+	result, _ = tool.Execute(ctx, MapParams{Params: map[string]any{"annotation": "=critical"}})
+	if result.Success {
+		t.Error("filter without a key succeeded")
+	}
+}
+
+func TestFindCallersTool_AnnotationFilter(t *testing.T) {
+	ctx := context.Background()
+	g, idx := createAnnotatedGraph(t)
+	tool := NewFindCallersTool(g, idx)
+
+	// Callers of every deprecated function.
+	result, err := tool.Execute(ctx, MapParams{Params: map[string]any{"annotation": "deprecated"}})
+	if err != nil || !result.Success {
+		t.Fatalf("Execute: %v %+v", err, result)
+	}
+	output := result.Output.(FindCallersOutput)
+	if output.MatchCount != 1 || output.TotalCallers != 2 {
+		t.Fatalf("output = %+v, want 2 callers of parseConfig", output)
+	}
+	if output.Results[0].TargetAnnotations["deprecated"] != "use loadConfig" {
+		t.Errorf("target annotations = %v", output.Results[0].TargetAnnotations)
+	}
+	critical := 0
+	for _, caller := range output.Results[0].Callers {
+		if caller.Annotations["tier"] == "critical" {
+			critical++
+		}
+	}
+	if critical != 1 {
+		t.Errorf("callers = %+v, want initServer annotated tier=critical", output.Results[0].Callers)
+	}
+	if !strings.Contains(result.OutputText, "[deprecated: use loadConfig]") {
+		t.Errorf("text does not show target annotations:\n%s", result.OutputText)
+	}
+
+	// A name whose symbol lacks the annotation matches nothing.
+	result, _ = tool.Execute(ctx, MapParams{Params: map[string]any{"function_name": "parseConfig", "annotation": "tier"}})
+	if result.Output.(FindCallersOutput).MatchCount != 0 {
+		t.Errorf("parseConfig matched annotation tier: %s", result.OutputText)
+	}
+
+	result, _ = tool.Execute(ctx, MapParams{Params: map[string]any{"annotation": "owner"}})
+	if !strings.Contains(result.OutputText, "No symbols have annotation 'owner'") {
+		t.Errorf("text for an unused annotation:\n%s", result.OutputText)
+	}
+}
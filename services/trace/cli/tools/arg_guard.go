@@ -0,0 +1,396 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// Argument repair kinds recorded by ArgumentGuard.
+const (
+	// RepairTrimmed indicates whitespace or wrapping quotes were removed.
+	RepairTrimmed = "trimmed"
+
+	// RepairResolvedSymbol indicates a name or stale ID was replaced by
+	// the ID of the symbol it names.
+	RepairResolvedSymbol = "resolved_symbol"
+
+	// RepairNormalizedPath indicates a path was made project-relative.
+	RepairNormalizedPath = "normalized_path"
+)
+
+// Argument checks whose failures ArgumentGuard and the executor count.
+const (
+	// ArgCheckSchema is the input schema check (types, bounds, enums).
+	ArgCheckSchema = "schema"
+
+	// ArgCheckSymbolID is the ParamFormatSymbolID check.
+	ArgCheckSymbolID = "symbol_id"
+
+	// ArgCheckProjectPath is the ParamFormatProjectPath check.
+	ArgCheckProjectPath = "project_path"
+)
+
+// ArgumentRepair records one automatic fix to a tool argument.
+type ArgumentRepair struct {
+	// Parameter is the repaired argument.
+	Parameter string
+
+	// Kind is the repair (RepairTrimmed, ...).
+	Kind string
+
+	// From and To are the value before and after.
+	From, To string
+}
+
+// ArgumentGuard checks LLM-supplied tool arguments against the project
+// before a tool runs, and repairs the cheap mistakes.
+//
+// Description:
+//
+//	The router's parameter extraction and the LLM both get arguments
+//	wrong in ways a tool only notices deep inside, with an error that
+//	does not say which argument was bad. The guard catches them first:
+//
+//	  - Strings are trimmed of whitespace and a wrapping pair of quotes,
+//	    for graph tools and for typed, enum and formatted arguments. Free
+//	    text of other tools (file content, grep patterns) is left alone.
+//	  - ParamFormatSymbolID arguments must name a graph node. A stable ID,
+//	    an ID whose line moved, or a bare symbol name that resolves exactly
+//	    is replaced by the node's ID; a file path is rejected.
+//	  - ParamFormatProjectPath arguments must stay inside the project
+//	    root; absolute paths inside it are made relative.
+//
+//	Numeric bounds and types are left to the input schema, which the
+//	executor validates after the guard.
+//
+// Thread Safety: Safe for concurrent use; Check modifies only the map it
+// is given.
+type ArgumentGuard struct {
+	graph       *graph.Graph
+	index       *index.SymbolIndex
+	projectRoot string
+	realRoot    string
+	logger      *slog.Logger
+}
+
+// NewArgumentGuard creates a guard for one project.
+//
+// Inputs:
+//
+//	g - The code graph, for symbol ID checks. Nil skips them.
+//	idx - The symbol index, for resolving names. Nil skips resolution.
+//	projectRoot - Absolute project root, for path checks. Empty skips them.
+//
+// Outputs:
+//
+//	*ArgumentGuard - The guard.
+func NewArgumentGuard(g *graph.Graph, idx *index.SymbolIndex, projectRoot string) *ArgumentGuard {
+	realRoot := ""
+	if projectRoot != "" {
+		projectRoot = filepath.Clean(projectRoot)
+		// The file tools resolve symlinks, so accept paths under either.
+		realRoot = projectRoot
+		if resolved, err := filepath.EvalSymlinks(projectRoot); err == nil {
+			realRoot = resolved
+		}
+	}
+	return &ArgumentGuard{
+		graph:       g,
+		index:       idx,
+		projectRoot: projectRoot,
+		realRoot:    realRoot,
+		logger:      slog.Default(),
+	}
+}
+
+// Check validates and repairs a tool's arguments in place.
+//
+// Inputs:
+//
+//	ctx - Context for symbol resolution and metrics.
+//	def - The tool definition.
+//	params - The arguments; repaired values replace the originals.
+//
+// Outputs:
+//
+//	[]ArgumentRepair - The repairs made, in parameter name order.
+//	*ValidationError - Non-nil if an argument is wrong and could not be
+//	  repaired; params may then be partly repaired.
+func (g *ArgumentGuard) Check(ctx context.Context, def ToolDefinition, params map[string]any) ([]ArgumentRepair, *ValidationError) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	graphTool := slices.Contains(def.Requires, "graph_initialized")
+	var repairs []ArgumentRepair
+	for _, name := range names {
+		paramDef, ok := def.Parameters[name]
+		if !ok {
+			continue
+		}
+		value, ok := params[name].(string)
+		if !ok {
+			continue
+		}
+
+		if graphTool || paramDef.Type != ParamTypeString || len(paramDef.Enum) > 0 || paramDef.Format != "" {
+			if trimmed := trimArgument(value); trimmed != value {
+				repairs = append(repairs, ArgumentRepair{Parameter: name, Kind: RepairTrimmed, From: value, To: trimmed})
+				value = trimmed
+				params[name] = value
+			}
+		}
+		if value == "" {
+			continue
+		}
+
+		var repaired string
+		var kind string
+		var verr *ValidationError
+		switch paramDef.Format {
+		case ParamFormatSymbolID:
+			repaired, verr = g.checkSymbolID(ctx, name, value)
+			kind = RepairResolvedSymbol
+			if verr != nil {
+				recordArgumentRejection(ctx, def.Name, ArgCheckSymbolID)
+			}
+		case ParamFormatProjectPath:
+			repaired, verr = g.checkProjectPath(name, value)
+			kind = RepairNormalizedPath
+			if verr != nil {
+				recordArgumentRejection(ctx, def.Name, ArgCheckProjectPath)
+			}
+		default:
+			continue
+		}
+		if verr != nil {
+			return repairs, verr
+		}
+		if repaired != value {
+			repairs = append(repairs, ArgumentRepair{Parameter: name, Kind: kind, From: value, To: repaired})
+			params[name] = repaired
+		}
+	}
+
+	for _, r := range repairs {
+		recordArgumentRepair(ctx, def.Name, r.Kind)
+		g.logger.Info("Repaired tool argument",
+			slog.String("tool", def.Name),
+			slog.String("param", r.Parameter),
+			slog.String("repair", r.Kind),
+			telemetry.SensitiveLog("from", r.From),
+			telemetry.SensitiveLog("to", r.To),
+		)
+	}
+	return repairs, nil
+}
+
+// checkSymbolID returns the graph node ID value names.
+func (g *ArgumentGuard) checkSymbolID(ctx context.Context, name, value string) (string, *ValidationError) {
+	if g.graph == nil {
+		return value, nil
+	}
+	if _, ok := g.graph.GetNode(value); ok {
+		return value, nil
+	}
+	if node, ok := g.graph.GetNodeByStableID(value); ok {
+		return node.ID, nil
+	}
+
+	// "file:line:Name" whose line moved: the symbol of that name in the file
+	if file, symbolName, ok := splitSymbolID(value); ok {
+		if id, ok := g.nodeInFile(file, symbolName); ok {
+			return id, nil
+		}
+	}
+
+	if g.isFilePath(value) {
+		return "", &ValidationError{
+			Parameter: name,
+			Message:   "got a file path where a symbol ID was expected; list the file's symbols first and pass one of their IDs",
+			Expected:  "symbol ID (file:line:name)",
+			Actual:    value,
+		}
+	}
+
+	if g.index != nil && !strings.Contains(value, ":") {
+		sym, fuzzy, err := ResolveFunctionWithFuzzy(ctx, g.index, value, g.logger, WithKindFilter(KindFilterAny))
+		if err == nil && !fuzzy {
+			if _, ok := g.graph.GetNode(sym.ID); ok {
+				return sym.ID, nil
+			}
+		}
+	}
+
+	return "", &ValidationError{
+		Parameter: name,
+		Message:   "no symbol with this ID in the code graph; use an ID returned by another tool",
+		Expected:  "symbol ID (file:line:name)",
+		Actual:    value,
+	}
+}
+
+// nodeInFile returns the ID of the only node called symbolName in file.
+func (g *ArgumentGuard) nodeInFile(file, symbolName string) (string, bool) {
+	id := ""
+	for _, node := range g.graph.GetNodesByFile(file) {
+		if node.Symbol == nil || node.Symbol.Name != symbolName {
+			continue
+		}
+		if id != "" {
+			return "", false
+		}
+		id = node.ID
+	}
+	return id, id != ""
+}
+
+// isFilePath reports whether value is a path to a file rather than a
+// symbol ID or name: a file of the graph, or a slashed path with an
+// extension.
+func (g *ArgumentGuard) isFilePath(value string) bool {
+	if strings.Contains(value, ":") {
+		return false
+	}
+	return len(g.graph.GetNodesByFile(value)) > 0 || strings.Contains(value, "/") && path.Ext(value) != ""
+}
+
+// checkProjectPath returns value as a path inside the project root.
+func (g *ArgumentGuard) checkProjectPath(name, value string) (string, *ValidationError) {
+	if g.projectRoot == "" {
+		return value, nil
+	}
+	outside := &ValidationError{
+		Parameter: name,
+		Message:   "path is outside the project",
+		Expected:  "path relative to the project root",
+		Actual:    value,
+	}
+
+	cleaned := filepath.Clean(value)
+	if filepath.IsAbs(cleaned) {
+		for _, root := range []string{g.projectRoot, g.realRoot} {
+			if rel, err := filepath.Rel(root, cleaned); err == nil && !escapesRoot(rel) {
+				return filepath.ToSlash(rel), nil
+			}
+		}
+		return "", outside
+	}
+	if escapesRoot(cleaned) {
+		return "", outside
+	}
+	return filepath.ToSlash(cleaned), nil
+}
+
+// escapesRoot reports whether a cleaned relative path leaves its root.
+func escapesRoot(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// trimArgument removes surrounding whitespace and one pair of wrapping
+// quotes or backticks, unless the quote also appears inside (then the
+// quotes are part of the value, e.g. `"a" OR "b"`).
+func trimArgument(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) >= 2 {
+		q := trimmed[0]
+		if (q == '"' || q == '\'' || q == '`') && trimmed[len(trimmed)-1] == q {
+			if inner := trimmed[1 : len(trimmed)-1]; !strings.ContainsRune(inner, rune(q)) {
+				trimmed = strings.TrimSpace(inner)
+			}
+		}
+	}
+	return trimmed
+}
+
+// splitSymbolID splits an ID of the form "file:line:name".
+func splitSymbolID(id string) (file, name string, ok bool) {
+	parts := strings.SplitN(id, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return "", "", false
+	}
+	if _, err := strconv.Atoi(parts[1]); err != nil {
+		return "", "", false
+	}
+	return parts[0], parts[2], true
+}
+
+// Argument guard metrics.
+var (
+	argGuardMeter = otel.Meter("aleutian.tools.arg_guard")
+
+	toolArgRepairs    metric.Int64Counter
+	toolArgRejections metric.Int64Counter
+
+	argGuardMetricsOnce sync.Once
+	argGuardMetricsErr  error
+)
+
+// initArgGuardMetrics initializes argument guard metrics.
+func initArgGuardMetrics() error {
+	argGuardMetricsOnce.Do(func() {
+		toolArgRepairs, argGuardMetricsErr = argGuardMeter.Int64Counter(
+			"trace_tool_argument_repairs_total",
+			metric.WithDescription("Total tool arguments repaired before execution"),
+		)
+		if argGuardMetricsErr != nil {
+			return
+		}
+		toolArgRejections, argGuardMetricsErr = argGuardMeter.Int64Counter(
+			"trace_tool_argument_rejections_total",
+			metric.WithDescription("Total tool calls rejected for invalid arguments"),
+		)
+	})
+	return argGuardMetricsErr
+}
+
+// recordArgumentRepair counts a repair of a tool's argument.
+func recordArgumentRepair(ctx context.Context, toolName, kind string) {
+	if err := initArgGuardMetrics(); err != nil {
+		return
+	}
+	toolArgRepairs.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("tool", toolName),
+		attribute.String("repair", kind),
+	))
+}
+
+// recordArgumentRejection counts a tool call rejected by an argument check.
+func recordArgumentRejection(ctx context.Context, toolName, check string) {
+	if err := initArgGuardMetrics(); err != nil {
+		return
+	}
+	toolArgRejections.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("tool", toolName),
+		attribute.String("check", check),
+	))
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArgumentGuard_Check(t *testing.T) {
+	ctx := context.Background()
+	g, idx, root := createTestGraphWithSourceFiles(t)
+	guard := NewArgumentGuard(g, idx, root)
+	symbolDef := NewReadSymbolSourceTool(g, idx).Definition()
+	pathDef := ToolDefinition{Name: "read", Parameters: map[string]ParamDef{
+		"file_path": {Type: ParamTypeString, Format: ParamFormatProjectPath},
+		"content":   {Type: ParamTypeString},
+	}}
+
+	tests := []struct {
+		name      string
+		def       ToolDefinition
+		param     string
+		value     string
+		want      string
+		wantKinds []string
+		wantErr   bool
+	}{
+		{"valid ID", symbolDef, "symbol_id", "pkg/config/config.go:7:ParseConfig", "pkg/config/config.go:7:ParseConfig", nil, false},
+		{"quoted ID", symbolDef, "symbol_id", ` "pkg/config/config.go:7:ParseConfig"`, "pkg/config/config.go:7:ParseConfig", []string{RepairTrimmed}, false},
+		{"bare name", symbolDef, "symbol_id", "ParseConfig", "pkg/config/config.go:7:ParseConfig", []string{RepairResolvedSymbol}, false},
+		{"moved line", symbolDef, "symbol_id", "pkg/config/config.go:9:ParseConfig", "pkg/config/config.go:7:ParseConfig", []string{RepairResolvedSymbol}, false},
+		{"file path", symbolDef, "symbol_id", "pkg/config/config.go", "", nil, true},
+		{"unknown name", symbolDef, "symbol_id", "NoSuchSymbol", "", nil, true},
+		{"relative path", pathDef, "file_path", "./pkg/config/config.go", "pkg/config/config.go", []string{RepairNormalizedPath}, false},
+		{"absolute path inside", pathDef, "file_path", filepath.Join(root, "cmd", "main.go"), "cmd/main.go", []string{RepairNormalizedPath}, false},
+		{"absolute path outside", pathDef, "file_path", "/etc/passwd", "", nil, true},
+		{"relative path escaping", pathDef, "file_path", "pkg/../../secret", "", nil, true},
+		{"free text untouched", pathDef, "content", `  "x"  `, `  "x"  `, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := map[string]any{tt.param: tt.value}
+			repairs, verr := guard.Check(ctx, tt.def, params)
+			if tt.wantErr {
+				if verr == nil || verr.Parameter != tt.param {
+					t.Fatalf("error = %v, want a validation error for %s", verr, tt.param)
+				}
+				return
+			}
+			if verr != nil {
+				t.Fatalf("unexpected error: %v", verr)
+			}
+			if params[tt.param] != tt.want {
+				t.Errorf("%s = %q, want %q", tt.param, params[tt.param], tt.want)
+			}
+			var kinds []string
+			for _, r := range repairs {
+				kinds = append(kinds, r.Kind)
+			}
+			if len(kinds) != len(tt.wantKinds) || (len(kinds) > 0 && kinds[len(kinds)-1] != tt.wantKinds[len(tt.wantKinds)-1]) {
+				t.Errorf("repairs = %v, want %v", kinds, tt.wantKinds)
+			}
+		})
+	}
+}
+
+func TestTrimArgument(t *testing.T) {
+	tests := map[string]string{
+		"  Handler ":          "Handler",
+		`"Handler"`:           "Handler",
+		"'Handler'":           "Handler",
+		"`Handler`":           "Handler",
+		`"a" OR "b"`:          `"a" OR "b"`,
+		`"unterminated`:       `"unterminated`,
+		`" spaced inside "  `: "spaced inside",
+	}
+	for in, want := range tests {
+		if got := trimArgument(in); got != want {
+			t.Errorf("trimArgument(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestExecutor_ArgumentGuard(t *testing.T) {
+	g, idx, root := createTestGraphWithSourceFiles(t)
+	registry := NewRegistry()
+	registry.Register(NewReadSymbolSourceTool(g, idx))
+	registry.Register(&mockTool{name: "walk", definition: ToolDefinition{
+		Name: "walk", Timeout: 5 * time.Second,
+		Parameters: map[string]ParamDef{"depth": {Type: ParamTypeInt, Minimum: Bound(1)}},
+	}})
+	executor := NewExecutorWithOptions(registry, nil, WithArgumentGuard(NewArgumentGuard(g, idx, root)))
+	executor.SatisfyRequirement("graph_initialized")
+
+	result, err := executor.Execute(context.Background(), &Invocation{
+		ToolName:   "read_symbol_source",
+		Parameters: map[string]any{"symbol_id": "ParseConfig"},
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("bare name: err = %v, result = %+v; want it resolved and run", err, result)
+	}
+
+	for _, inv := range []*Invocation{
+		{ToolName: "read_symbol_source", Parameters: map[string]any{"symbol_id": "pkg/config/config.go"}},
+		{ToolName: "walk", Parameters: map[string]any{"depth": -2}},
+	} {
+		_, err := executor.Execute(context.Background(), inv)
+		var verr *ValidationError
+		if !errors.Is(err, ErrValidationFailed) || !errors.As(err, &verr) {
+			t.Errorf("%s: err = %v, want ErrValidationFailed with a *ValidationError", inv.ToolName, err)
+		}
+	}
+}
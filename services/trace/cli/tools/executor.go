@@ -12,15 +12,18 @@ package tools
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/transaction"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel"
@@ -71,6 +74,49 @@ type Executor struct {
 
 	// sessionID correlates transactions with agent sessions for tracing.
 	sessionID string
+
+	// policyOverride is the per-run execution policy layered over the
+	// registry policy. Nil means the registry policy applies unchanged.
+	policyOverride *ToolExecutionPolicy
+
+	// slots bounds concurrent tool executions (nil = unbounded).
+	// Created lazily from the effective policy on first Execute.
+	slots     chan struct{}
+	slotsOnce sync.Once
+
+	// sharedCache holds results of Cacheable tools across executors,
+	// keyed by cacheGeneration. Nil disables it.
+	sharedCache     *ResultCache
+	cacheGeneration string
+
+	// stableID maps symbol IDs in parameters to stable IDs for shared
+	// cache keys. Nil leaves parameters unchanged.
+	stableID func(id string) (string, bool)
+
+	// bypassCache skips every result cache for this executor (debugging).
+	bypassCache bool
+
+	// interceptor wraps every execution (record/replay). Nil runs tools
+	// directly.
+	interceptor ExecutionInterceptor
+
+	// banner returns a warning to put before the output of graph tools,
+	// or "" for none. Nil adds none.
+	banner func() string
+
+	// guard checks and repairs arguments before validation. Nil skips it.
+	guard *ArgumentGuard
+}
+
+// ExecutionInterceptor wraps tool executions, e.g. to record them to a
+// fixture or serve them from one.
+type ExecutionInterceptor interface {
+	// Intercept handles one invocation. It may return a stored result
+	// without calling execute, or call execute and observe its outcome.
+	// The invocation's parameters are as the caller passed them, before
+	// any coercion.
+	Intercept(ctx context.Context, invocation *Invocation,
+		execute func(context.Context, *Invocation) (*Result, error)) (*Result, error)
 }
 
 // ExecutorOption configures an Executor.
@@ -93,6 +139,77 @@ func WithSessionID(sessionID string) ExecutorOption {
 	}
 }
 
+// WithExecutionPolicy layers a per-run policy over the registry policy.
+//
+// Non-zero fields in policy override the registry's ToolExecutionPolicy
+// for this executor only, e.g. a longer timeout for one expensive tool.
+func WithExecutionPolicy(policy ToolExecutionPolicy) ExecutorOption {
+	return func(e *Executor) {
+		e.policyOverride = &policy
+	}
+}
+
+// WithResultCache serves Cacheable tools from a cache shared across executors.
+//
+// generation identifies the graph the tools run against (its content
+// hash); results are only shared between executors with equal generations.
+// An empty generation disables the shared cache.
+func WithResultCache(cache *ResultCache, generation string) ExecutorOption {
+	return func(e *Executor) {
+		e.sharedCache = cache
+		e.cacheGeneration = generation
+	}
+}
+
+// WithStableIDResolver makes shared cache keys use stable symbol IDs.
+//
+// String parameters (and string list elements) that resolve to a symbol
+// are replaced by its stable ID before the key is computed, so a call
+// made with a line-based ID and one made with the stable ID share an
+// entry. The tool itself still receives the parameters unchanged.
+// Typically graph.Graph.StableIDFor.
+func WithStableIDResolver(resolve func(id string) (string, bool)) ExecutorOption {
+	return func(e *Executor) {
+		e.stableID = resolve
+	}
+}
+
+// WithCacheBypass disables result caching for this executor when bypass is
+// true: every tool runs, and nothing is read from or written to any cache.
+func WithCacheBypass(bypass bool) ExecutorOption {
+	return func(e *Executor) {
+		e.bypassCache = bypass
+	}
+}
+
+// WithExecutionInterceptor routes every execution through interceptor.
+func WithExecutionInterceptor(interceptor ExecutionInterceptor) ExecutorOption {
+	return func(e *Executor) {
+		e.interceptor = interceptor
+	}
+}
+
+// WithWarningBanner puts banner() before the output of every tool that
+// requires graph_initialized, when it returns a non-empty string, e.g.
+// while the graph failed validation. banner is called per execution, so
+// the warning follows the graph's current state; cached results are not
+// affected.
+func WithWarningBanner(banner func() string) ExecutorOption {
+	return func(e *Executor) {
+		e.banner = banner
+	}
+}
+
+// WithArgumentGuard checks and repairs every invocation's arguments with
+// guard before schema validation; see ArgumentGuard. An argument it cannot
+// repair fails the invocation with ErrValidationFailed wrapping a
+// *ValidationError, as a schema violation does.
+func WithArgumentGuard(guard *ArgumentGuard) ExecutorOption {
+	return func(e *Executor) {
+		e.guard = guard
+	}
+}
+
 // NewExecutor creates a new tool executor.
 //
 // Inputs:
@@ -152,12 +269,60 @@ func (e *Executor) SetSessionID(sessionID string) {
 	e.sessionID = sessionID
 }
 
+// Policy returns the effective tool execution policy for this executor.
+//
+// Outputs:
+//
+//	ToolExecutionPolicy - Registry policy with any per-run override applied.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (e *Executor) Policy() ToolExecutionPolicy {
+	policy := e.registry.Policy()
+	if e.policyOverride != nil {
+		policy = policy.Merge(*e.policyOverride)
+	}
+	return policy
+}
+
+// acquireSlot blocks until a concurrency slot is free or ctx is done.
+//
+// Outputs:
+//
+//	func() - Releases the slot. Always non-nil when err is nil.
+//	error - Non-nil if ctx was cancelled while waiting.
+func (e *Executor) acquireSlot(ctx context.Context, toolName string, maxConcurrent int) (func(), error) {
+	e.slotsOnce.Do(func() {
+		if maxConcurrent > 0 {
+			e.slots = make(chan struct{}, maxConcurrent)
+		}
+	})
+	if e.slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case e.slots <- struct{}{}:
+		return func() { <-e.slots }, nil
+	default:
+	}
+
+	// All slots busy: record the contention, then wait.
+	recordLimitHit(ctx, toolName, LimitConcurrency)
+	select {
+	case e.slots <- struct{}{}:
+		return func() { <-e.slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for tool execution slot: %w", ctx.Err())
+	}
+}
+
 // Execute runs a tool with the given invocation.
 //
 // Description:
 //
 //	Validates the invocation, checks requirements, executes the tool,
-//	and optionally caches the result.
+//	and optionally caches the result. With an ExecutionInterceptor set,
+//	the interceptor decides whether the tool actually runs.
 //
 // Inputs:
 //
@@ -179,6 +344,38 @@ func (e *Executor) SetSessionID(sessionID string) {
 //
 // Thread Safety: This method is safe for concurrent use.
 func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result, error) {
+	var result *Result
+	var err error
+	if e.interceptor != nil && invocation != nil {
+		result, err = e.interceptor.Intercept(ctx, invocation, e.execute)
+	} else {
+		result, err = e.execute(ctx, invocation)
+	}
+	if err != nil || result == nil || e.banner == nil {
+		return result, err
+	}
+	return e.withBanner(invocation.ToolName, result), nil
+}
+
+// withBanner returns result with the warning banner before its output if
+// toolName is a graph tool and there is a banner. result itself, which may
+// be cached, is not modified.
+func (e *Executor) withBanner(toolName string, result *Result) *Result {
+	tool, ok := e.registry.Get(toolName)
+	if !ok || !slices.Contains(tool.Definition().Requires, "graph_initialized") {
+		return result
+	}
+	banner := e.banner()
+	if banner == "" {
+		return result
+	}
+	bannered := *result
+	bannered.OutputText = banner + "\n\n" + result.OutputText
+	return &bannered
+}
+
+// execute runs one invocation; see Execute.
+func (e *Executor) execute(ctx context.Context, invocation *Invocation) (*Result, error) {
 	// TO-001: Check context cancellation early to avoid wasted work
 	if err := ctx.Err(); err != nil {
 		return nil, fmt.Errorf("context cancelled before execution: %w", err)
@@ -218,40 +415,70 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 		return nil, fmt.Errorf("%w: %s", ErrToolNotFound, invocation.ToolName)
 	}
 
+	// Repair malformed arguments and reject ones that do not fit the project
+	if e.guard != nil {
+		repairs, verr := e.guard.Check(ctx, tool.Definition(), invocation.Parameters)
+		span.SetAttributes(attribute.Int("tool.args_repaired", len(repairs)))
+		if verr != nil {
+			logger.Warn("Argument check failed", telemetry.SensitiveLogError("error", verr))
+			return nil, fmt.Errorf("%w: %w", ErrValidationFailed, verr)
+		}
+	}
+
 	// Coerce parameters to expected types (handles LLM string-to-number conversion)
 	e.coerceParams(tool, invocation.Parameters)
+	span.SetAttributes(attribute.Int("tool.args_bytes", paramsSize(invocation.Parameters)))
 
 	// Validate parameters
 	if err := e.validateParams(tool, invocation.Parameters); err != nil {
-		logger.Warn("Parameter validation failed", "error", err)
-		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
+		logger.Warn("Parameter validation failed", telemetry.SensitiveLogError("error", err))
+		recordArgumentRejection(ctx, invocation.ToolName, ArgCheckSchema)
+		return nil, fmt.Errorf("%w: %w", ErrValidationFailed, err)
 	}
 
 	// Check requirements
 	if err := e.checkRequirements(tool); err != nil {
-		logger.Warn("Requirement not met", "error", err)
+		logger.Warn("Requirement not met", telemetry.SensitiveLogError("error", err))
 		return nil, err
 	}
 
 	// Check cache
-	if e.cache != nil && !tool.Definition().SideEffects {
+	if e.cache != nil && !e.bypassCache && !tool.Definition().SideEffects {
 		if cached, ok := e.cache.get(invocation.ToolName, invocation.Parameters); ok {
 			logger.Debug("Cache hit")
 			cached.Cached = true
 			span.SetAttributes(
 				attribute.Bool("tool.cached", true),
 				attribute.Bool("tool.success", cached.Success),
+				attribute.Int("tool.result_bytes", len(cached.OutputText)),
 			)
 			span.SetStatus(codes.Ok, "cache hit")
 			return cached, nil
 		}
 	}
 
-	// Set up timeout
-	timeout := e.options.DefaultTimeout
-	if tool.Definition().Timeout > 0 {
-		timeout = tool.Definition().Timeout
+	// Bound concurrency and time per the effective execution policy
+	policy := e.Policy()
+
+	// Check the shared cache for graph-deterministic tools
+	useShared := e.useSharedCache(tool.Definition())
+	var keyParams map[string]any
+	if useShared {
+		keyParams = e.cacheKeyParams(invocation.Parameters)
+		lookupStart := time.Now()
+		if cached, ok := e.sharedCache.Get(ctx, e.cacheGeneration, invocation.ToolName, keyParams); ok {
+			return e.finishSharedHit(ctx, span, logger, invocation, cached, policy, time.Since(lookupStart)), nil
+		}
 	}
+	release, err := e.acquireSlot(ctx, invocation.ToolName, policy.MaxConcurrent)
+	if err != nil {
+		return nil, err
+	}
+	// runWithDeadline takes over the slot, since its call may outlive
+	// Execute.
+	defer func() { release() }()
+
+	timeout := policy.TimeoutFor(tool.Definition(), e.options.DefaultTimeout)
 
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
@@ -271,7 +498,7 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 			// Start transaction for side-effect tools
 			_, txErr := e.transactionManager.Begin(ctx, sessionID)
 			if txErr != nil {
-				logger.Error("Failed to start transaction", "error", txErr)
+				logger.Error("Failed to start transaction", telemetry.SensitiveLogError("error", txErr))
 				return nil, fmt.Errorf("starting transaction: %w", txErr)
 			}
 			txActive = true
@@ -283,7 +510,16 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 	invocation.StartedAt = time.Now().UnixMilli()
 	logger.Debug("Executing tool")
 
-	result, err := tool.Execute(ctx, MapParams{Tool: invocation.ToolName, Params: invocation.Parameters})
+	params := MapParams{Tool: invocation.ToolName, Params: invocation.Parameters}
+	var result *Result
+	if hasSideEffects {
+		// Side-effect tools run inline so a write can never land after
+		// the transaction has been rolled back on timeout.
+		result, err = tool.Execute(ctx, params)
+	} else {
+		result, err = e.runWithDeadline(ctx, tool, params, release)
+		release = func() {}
+	}
 	invocation.CompletedAt = time.Now().UnixMilli()
 
 	// Handle transaction commit/rollback
@@ -296,7 +532,7 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 			}
 			_, rollbackErr := e.transactionManager.Rollback(ctx, reason)
 			if rollbackErr != nil {
-				logger.Error("Transaction rollback failed", "error", rollbackErr)
+				logger.Error("Transaction rollback failed", telemetry.SensitiveLogError("error", rollbackErr))
 			} else {
 				logger.Debug("Transaction rolled back", "reason", reason)
 			}
@@ -313,7 +549,7 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 			// Commit on success
 			_, commitErr := e.transactionManager.Commit(ctx, "agent tool execution")
 			if commitErr != nil {
-				logger.Error("Transaction commit failed", "error", commitErr)
+				logger.Error("Transaction commit failed", telemetry.SensitiveLogError("error", commitErr))
 				return nil, fmt.Errorf("committing transaction: %w", commitErr)
 			}
 			logger.Debug("Transaction committed")
@@ -321,27 +557,38 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 	}
 
 	if err != nil {
-		span.SetStatus(codes.Error, err.Error())
-		span.RecordError(err)
-		if errors.Is(err, context.DeadlineExceeded) {
+		telemetry.SetErrorStatus(span, err)
+		telemetry.RecordException(span, err)
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			span.SetAttributes(attribute.Bool("tool.timeout", true))
 			logger.Error("Tool execution timed out", "timeout", timeout)
-			return nil, fmt.Errorf("%w: %s after %v", ErrTimeout, invocation.ToolName, timeout)
+			recordLimitHit(ctx, invocation.ToolName, LimitTimeout)
+			return nil, &LimitError{Tool: invocation.ToolName, Limit: LimitTimeout, Timeout: timeout}
 		}
-		logger.Error("Tool execution failed", "error", err)
+		logger.Error("Tool execution failed", telemetry.SensitiveLogError("error", err))
 		return nil, fmt.Errorf("%w: %v", ErrExecutionFailed, err)
 	}
 
 	// Set duration
 	result.Duration = time.Duration(invocation.CompletedAt-invocation.StartedAt) * time.Millisecond
 
+	// Share the untruncated result; limits are applied per executor below
+	if useShared {
+		e.sharedCache.Put(ctx, e.cacheGeneration, invocation.ToolName, keyParams, result)
+	}
+
 	// Truncate if needed
 	if result.TokensUsed > e.options.MaxOutputTokens {
 		result = e.truncateResult(result)
 	}
+	if e.capResultBytes(result, policy.MaxResultBytes) {
+		span.SetAttributes(attribute.Bool("tool.output_capped", true))
+		logger.Warn("Tool output exceeded size cap", "max_bytes", policy.MaxResultBytes)
+		recordLimitHit(ctx, invocation.ToolName, LimitOutputSize)
+	}
 
 	// Cache successful results
-	if e.cache != nil && result.Success && !tool.Definition().SideEffects {
+	if e.cache != nil && !e.bypassCache && result.Success && !tool.Definition().SideEffects {
 		e.cache.set(invocation.ToolName, invocation.Parameters, result)
 	}
 
@@ -354,6 +601,7 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 		attribute.Int64("tool.duration_ms", int64(result.Duration/time.Millisecond)),
 		attribute.Int("tool.tokens_used", result.TokensUsed),
 		attribute.Bool("tool.cached", result.Cached),
+		attribute.Int("tool.result_bytes", len(result.OutputText)),
 	)
 	span.SetStatus(codes.Ok, "")
 
@@ -366,6 +614,180 @@ func (e *Executor) Execute(ctx context.Context, invocation *Invocation) (*Result
 	return result, nil
 }
 
+// paramsSize returns the JSON-encoded size of tool parameters in bytes, or
+// 0 if they cannot be encoded.
+func paramsSize(params map[string]any) int {
+	if len(params) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
+
+// cacheKeyParams returns params with symbol IDs replaced by stable IDs
+// (see WithStableIDResolver). Returns params itself when nothing changes.
+func (e *Executor) cacheKeyParams(params map[string]any) map[string]any {
+	if e.stableID == nil {
+		return params
+	}
+	var out map[string]any
+	set := func(k string, v any) {
+		if out == nil {
+			out = make(map[string]any, len(params))
+			for pk, pv := range params {
+				out[pk] = pv
+			}
+		}
+		out[k] = v
+	}
+	for k, v := range params {
+		switch val := v.(type) {
+		case string:
+			if id, ok := e.stableID(val); ok && id != val {
+				set(k, id)
+			}
+		case []string:
+			if ids, changed := e.stableIDs(val); changed {
+				set(k, ids)
+			}
+		case []any:
+			mapped := make([]any, len(val))
+			changed := false
+			for i, item := range val {
+				mapped[i] = item
+				if s, ok := item.(string); ok {
+					if id, ok := e.stableID(s); ok && id != s {
+						mapped[i] = id
+						changed = true
+					}
+				}
+			}
+			if changed {
+				set(k, mapped)
+			}
+		}
+	}
+	if out == nil {
+		return params
+	}
+	return out
+}
+
+// stableIDs maps each element of ids through e.stableID.
+func (e *Executor) stableIDs(ids []string) ([]string, bool) {
+	mapped := make([]string, len(ids))
+	changed := false
+	for i, s := range ids {
+		mapped[i] = s
+		if id, ok := e.stableID(s); ok && id != s {
+			mapped[i] = id
+			changed = true
+		}
+	}
+	return mapped, changed
+}
+
+// useSharedCache reports whether def participates in the shared result cache.
+func (e *Executor) useSharedCache(def ToolDefinition) bool {
+	return e.sharedCache != nil && e.cacheGeneration != "" && !e.bypassCache &&
+		def.Cacheable && !def.SideEffects
+}
+
+// finishSharedHit completes an execution answered by the shared cache.
+//
+// Description:
+//
+//	Marks the result as cached with the generation it came from, so the
+//	hit is visible in agent events and transcripts, and applies this
+//	executor's output limits. Duration is the lookup time, not the time
+//	the original execution took.
+func (e *Executor) finishSharedHit(
+	ctx context.Context,
+	span trace.Span,
+	logger *slog.Logger,
+	invocation *Invocation,
+	result *Result,
+	policy ToolExecutionPolicy,
+	lookup time.Duration,
+) *Result {
+	result.Cached = true
+	result.Duration = lookup
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]any)
+	}
+	result.Metadata[MetaCacheGeneration] = e.cacheGeneration
+
+	if result.TokensUsed > e.options.MaxOutputTokens {
+		result = e.truncateResult(result)
+	}
+	if e.capResultBytes(result, policy.MaxResultBytes) {
+		span.SetAttributes(attribute.Bool("tool.output_capped", true))
+		recordLimitHit(ctx, invocation.ToolName, LimitOutputSize)
+	}
+
+	now := time.Now().UnixMilli()
+	invocation.StartedAt = now
+	invocation.CompletedAt = now
+	invocation.Result = result
+
+	span.SetAttributes(
+		attribute.Bool("tool.cached", true),
+		attribute.Bool("tool.success", result.Success),
+		attribute.String("tool.cache_generation", e.cacheGeneration),
+		attribute.Int("tool.result_bytes", len(result.OutputText)),
+	)
+	span.SetStatus(codes.Ok, "cache hit")
+	logger.Debug("Shared cache hit", "generation", e.cacheGeneration)
+	return result
+}
+
+// runWithDeadline runs tool.Execute and returns when it finishes or ctx is done.
+//
+// Description:
+//
+//	Tools are expected to honour ctx, but a graph traversal stuck in a tight
+//	loop may not. Running the call in a goroutine guarantees the agent run
+//	regains control at the deadline; the abandoned call finishes in the
+//	background and its result is discarded. The call keeps its concurrency
+//	slot until it actually returns, so stuck calls cannot pile up past
+//	MaxConcurrent. Only used for read-only tools.
+//
+// Inputs:
+//
+//	ctx - Context carrying the tool deadline.
+//	tool - The tool to execute.
+//	params - The tool parameters.
+//	release - Releases the call's concurrency slot. Called once
+//	  tool.Execute returns, which may be after runWithDeadline does.
+//
+// Outputs:
+//
+//	*Result - The tool result, or nil if ctx expired first.
+//	error - The tool error, or ctx.Err() if ctx expired first.
+func (e *Executor) runWithDeadline(ctx context.Context, tool Tool, params TypedParams, release func()) (*Result, error) {
+	type outcome struct {
+		result *Result
+		err    error
+	}
+
+	done := make(chan outcome, 1)
+	go func() {
+		defer release()
+		result, err := tool.Execute(ctx, params)
+		done <- outcome{result: result, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.result, out.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
 // coerceParams attempts to convert parameter values to their expected types.
 //
 // Description:
@@ -436,7 +858,7 @@ func (e *Executor) coerceParams(tool Tool, params map[string]any) {
 				slog.Debug("coerceParams: unrecognized semantic value for numeric param",
 					slog.String("tool", tool.Name()),
 					slog.String("param", name),
-					slog.String("value", strVal),
+					telemetry.SensitiveLog("value", strVal),
 				)
 			}
 			// If no match, leave as string and let validation fail with clear error
@@ -453,170 +875,14 @@ func (e *Executor) coerceParams(tool Tool, params map[string]any) {
 	}
 }
 
-// validateParams validates tool parameters against the definition.
+// validateParams validates tool parameters against the tool's input schema,
+// as cached by the registry at registration.
 func (e *Executor) validateParams(tool Tool, params map[string]any) error {
-	def := tool.Definition()
-
-	// Check required parameters
-	for name, paramDef := range def.Parameters {
-		if paramDef.Required {
-			if _, ok := params[name]; !ok {
-				return &ValidationError{
-					Parameter: name,
-					Message:   "required parameter missing",
-				}
-			}
-		}
-	}
-
-	// Validate provided parameters
-	for name, value := range params {
-		paramDef, ok := def.Parameters[name]
-		if !ok {
-			// Unknown parameter - ignore or error based on strictness
-			continue
-		}
-
-		if err := e.validateParam(name, value, paramDef); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// validateParam validates a single parameter value.
-func (e *Executor) validateParam(name string, value any, def ParamDef) error {
-	if value == nil {
-		if def.Required {
-			return &ValidationError{
-				Parameter: name,
-				Message:   "required parameter is nil",
-			}
-		}
-		return nil
-	}
-
-	switch def.Type {
-	case ParamTypeString:
-		str, ok := value.(string)
-		if !ok {
-			return &ValidationError{
-				Parameter: name,
-				Message:   "expected string",
-				Actual:    fmt.Sprintf("%T", value),
-			}
-		}
-		if def.MinLength > 0 && len(str) < def.MinLength {
-			return &ValidationError{
-				Parameter: name,
-				Message:   fmt.Sprintf("string length must be at least %d", def.MinLength),
-			}
-		}
-		if def.MaxLength > 0 && len(str) > def.MaxLength {
-			return &ValidationError{
-				Parameter: name,
-				Message:   fmt.Sprintf("string length must be at most %d", def.MaxLength),
-			}
-		}
-
-	case ParamTypeInt:
-		// Accept both int and float64 (JSON unmarshals numbers as float64)
-		var num float64
-		switch v := value.(type) {
-		case int:
-			num = float64(v)
-		case int64:
-			num = float64(v)
-		case float64:
-			num = v
-		default:
-			return &ValidationError{
-				Parameter: name,
-				Message:   "expected integer",
-				Actual:    fmt.Sprintf("%T", value),
-			}
-		}
-		if def.Minimum != nil && num < *def.Minimum {
-			return &ValidationError{
-				Parameter: name,
-				Message:   fmt.Sprintf("value must be at least %v", *def.Minimum),
-			}
-		}
-		if def.Maximum != nil && num > *def.Maximum {
-			return &ValidationError{
-				Parameter: name,
-				Message:   fmt.Sprintf("value must be at most %v", *def.Maximum),
-			}
-		}
-
-	case ParamTypeFloat:
-		num, ok := value.(float64)
-		if !ok {
-			return &ValidationError{
-				Parameter: name,
-				Message:   "expected number",
-				Actual:    fmt.Sprintf("%T", value),
-			}
-		}
-		if def.Minimum != nil && num < *def.Minimum {
-			return &ValidationError{
-				Parameter: name,
-				Message:   fmt.Sprintf("value must be at least %v", *def.Minimum),
-			}
-		}
-		if def.Maximum != nil && num > *def.Maximum {
-			return &ValidationError{
-				Parameter: name,
-				Message:   fmt.Sprintf("value must be at most %v", *def.Maximum),
-			}
-		}
-
-	case ParamTypeBool:
-		if _, ok := value.(bool); !ok {
-			return &ValidationError{
-				Parameter: name,
-				Message:   "expected boolean",
-				Actual:    fmt.Sprintf("%T", value),
-			}
-		}
-
-	case ParamTypeArray:
-		if _, ok := value.([]any); !ok {
-			// Also accept typed slices
-			return nil // Relaxed validation for arrays
-		}
-
-	case ParamTypeObject:
-		if _, ok := value.(map[string]any); !ok {
-			return &ValidationError{
-				Parameter: name,
-				Message:   "expected object",
-				Actual:    fmt.Sprintf("%T", value),
-			}
-		}
-	}
-
-	// Check enum constraint
-	if len(def.Enum) > 0 {
-		found := false
-		for _, allowed := range def.Enum {
-			if value == allowed {
-				found = true
-				break
-			}
-		}
-		if !found {
-			return &ValidationError{
-				Parameter: name,
-				Message:   "value not in allowed enum",
-				Expected:  fmt.Sprintf("%v", def.Enum),
-				Actual:    fmt.Sprintf("%v", value),
-			}
-		}
+	schema, ok := e.registry.Schema(tool.Name())
+	if !ok {
+		schema = tool.Schema()
 	}
-
-	return nil
+	return schema.Input.ValidateArgs(params)
 }
 
 // checkRequirements verifies all tool requirements are satisfied.
@@ -712,6 +978,43 @@ func (e *Executor) truncateResult(result *Result) *Result {
 	return result
 }
 
+// capResultBytes applies the policy's MaxResultBytes to result.OutputText.
+//
+// Description:
+//
+//	Keeps head and tail of oversized output with an elision marker (see
+//	TruncateHeadTail) and flags the result via Truncated and Metadata so the
+//	LLM and the Reflect phase can see the output was cut.
+//
+// Inputs:
+//
+//	result - The result to cap. Modified in place.
+//	maxBytes - Byte cap. <= 0 disables the cap.
+//
+// Outputs:
+//
+//	bool - True if the output was truncated.
+func (e *Executor) capResultBytes(result *Result, maxBytes int) bool {
+	originalLen := len(result.OutputText)
+	text, truncated := TruncateHeadTail(result.OutputText, maxBytes)
+	if !truncated {
+		return false
+	}
+
+	result.OutputText = text
+	result.Truncated = true
+	if result.TokensUsed > len(text)/4 {
+		result.TokensUsed = len(text) / 4
+	}
+	if result.Metadata == nil {
+		result.Metadata = make(map[string]any)
+	}
+	result.Metadata[MetaTruncated] = true
+	result.Metadata[MetaLimitHit] = LimitOutputSize
+	result.Metadata[MetaOriginalBytes] = originalLen
+	return true
+}
+
 // GetAvailableTools returns tools available with current requirements.
 //
 // Inputs:
@@ -748,7 +1051,8 @@ func (e *Executor) GetAvailableTools(enabledCategories []string, disabledTools [
 	return available
 }
 
-// ClearCache clears the result cache.
+// ClearCache clears the executor's own result cache. The shared
+// ResultCache is invalidated by graph generation instead.
 //
 // Thread Safety: This method is safe for concurrent use.
 func (e *Executor) ClearCache() {
@@ -12,8 +12,13 @@ package tools
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // mockTool is a minimal tool implementation for testing.
@@ -25,6 +30,7 @@ type mockTool struct {
 func (t *mockTool) Name() string               { return t.name }
 func (t *mockTool) Category() ToolCategory     { return CategoryExploration }
 func (t *mockTool) Definition() ToolDefinition { return t.definition }
+func (t *mockTool) Schema() ToolSchema         { return NewToolSchema(t.definition, nil) }
 func (t *mockTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	return &Result{Success: true, OutputText: "ok"}, nil
 }
@@ -433,3 +439,87 @@ func TestExecutor_Execute_WithCoercion(t *testing.T) {
 		}
 	})
 }
+
+func TestExecutor_Execute_SpanAttributes(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+
+	registry := NewRegistry()
+	registry.Register(&mockTool{
+		name: "test_tool",
+		definition: ToolDefinition{
+			Name:       "test_tool",
+			Parameters: map[string]ParamDef{"target": {Type: ParamTypeString}},
+			Timeout:    5 * time.Second,
+		},
+	})
+	executor := NewExecutor(registry, nil)
+
+	for i := 0; i < 2; i++ {
+		invocation := &Invocation{ToolName: "test_tool", Parameters: map[string]any{"target": "main"}}
+		if _, err := executor.Execute(context.Background(), invocation); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	for i, want := range []bool{false, true} {
+		attrs := make(map[string]string)
+		for _, a := range spans[i].Attributes {
+			attrs[string(a.Key)] = a.Value.Emit()
+		}
+		if attrs["tool.name"] != "test_tool" {
+			t.Errorf("span %d: tool.name = %q", i, attrs["tool.name"])
+		}
+		if attrs["tool.args_bytes"] != "17" {
+			t.Errorf("span %d: tool.args_bytes = %q, want 17", i, attrs["tool.args_bytes"])
+		}
+		if attrs["tool.result_bytes"] != "2" {
+			t.Errorf("span %d: tool.result_bytes = %q, want 2", i, attrs["tool.result_bytes"])
+		}
+		if attrs["tool.cached"] != fmt.Sprint(want) {
+			t.Errorf("span %d: tool.cached = %q, want %v", i, attrs["tool.cached"], want)
+		}
+	}
+}
+
+func TestExecutor_WarningBanner(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&mockTool{name: "graph_tool", definition: ToolDefinition{
+		Name: "graph_tool", Requires: []string{"graph_initialized"}, Timeout: 5 * time.Second,
+	}})
+	registry.Register(&mockTool{name: "file_tool", definition: ToolDefinition{
+		Name: "file_tool", Timeout: 5 * time.Second,
+	}})
+
+	banner := "WARNING: graph degraded"
+	executor := NewExecutorWithOptions(registry, nil, WithWarningBanner(func() string { return banner }))
+	executor.SatisfyRequirement("graph_initialized")
+	run := func(name string) string {
+		result, err := executor.Execute(context.Background(), &Invocation{ToolName: name, Parameters: map[string]any{}})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return result.OutputText
+	}
+
+	if got := run("graph_tool"); got != banner+"\n\nok" {
+		t.Errorf("graph tool output = %q, want the banner first", got)
+	}
+	if got := run("file_tool"); got != "ok" {
+		t.Errorf("file tool output = %q, want no banner", got)
+	}
+	banner = ""
+	if got := run("graph_tool"); got != "ok" {
+		t.Errorf("output after the graph recovered = %q, want no banner", got)
+	}
+}
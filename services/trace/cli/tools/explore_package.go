@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -106,6 +107,10 @@ func (t *explorePackageTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *explorePackageTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), ExplorePackageResult{})
+}
+
 // ExplorePackageResult is the output of the explore_package tool.
 type ExplorePackageResult struct {
 	Name         string         `json:"name"`
@@ -210,7 +215,7 @@ func (t *explorePackageTool) Execute(ctx context.Context, params TypedParams) (*
 	}
 
 	slog.Debug("explore_package starting",
-		slog.String("package", pkgName),
+		telemetry.SensitiveLog("package", pkgName),
 		slog.Bool("include_private", includePrivate),
 		slog.Bool("include_deps", includeDeps),
 		slog.Int("max_symbols", maxSymbols),
@@ -251,7 +256,7 @@ func (t *explorePackageTool) Execute(ctx context.Context, params TypedParams) (*
 		traceStep := stepBuilder.Build()
 
 		slog.Debug("explore_package package not found",
-			slog.String("package", pkgName),
+			telemetry.SensitiveLog("package", pkgName),
 			slog.Int("packages_searched", len(packageSymbols)),
 		)
 
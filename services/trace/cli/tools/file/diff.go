@@ -82,6 +82,11 @@ func (t *DiffTool) Definition() tools.ToolDefinition {
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *DiffTool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), DiffResult{})
+}
+
 // Execute compares two files and returns the diff.
 func (t *DiffTool) Execute(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
 	start := time.Now()
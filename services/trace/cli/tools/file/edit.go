@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // EditTool implements the Edit file operation.
@@ -56,6 +57,7 @@ func (t *EditTool) Definition() tools.ToolDefinition {
 				Type:        tools.ParamTypeString,
 				Description: "Path to the file to edit. Can be absolute or relative to the project root.",
 				Required:    true,
+				Format:      tools.ParamFormatProjectPath,
 			},
 			"old_string": {
 				Type:        tools.ParamTypeString,
@@ -100,6 +102,11 @@ func (t *EditTool) Definition() tools.ToolDefinition {
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *EditTool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), EditResult{})
+}
+
 // Execute performs a surgical edit on a file.
 func (t *EditTool) Execute(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
 	start := time.Now()
@@ -230,8 +237,8 @@ func (t *EditTool) Execute(ctx context.Context, params tools.TypedParams) (*tool
 		if err := t.config.GraphRefresher.RefreshFiles(ctx, []string{p.FilePath}); err != nil {
 			// Log but don't fail the edit - graph will eventually catch up via fsnotify
 			slog.Warn("failed to refresh graph after edit",
-				slog.String("file", p.FilePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("file", p.FilePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 		}
 	}
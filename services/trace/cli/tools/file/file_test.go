@@ -1515,13 +1515,13 @@ func TestQueryJSON(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.query, func(t *testing.T) {
-			got, err := queryJSON(data, tt.query)
+			got, err := QueryJSON(data, tt.query)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("queryJSON() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("QueryJSON() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 			if !tt.wantErr && fmt.Sprintf("%v", got) != fmt.Sprintf("%v", tt.expected) {
-				t.Errorf("queryJSON() = %v, want %v", got, tt.expected)
+				t.Errorf("QueryJSON() = %v, want %v", got, tt.expected)
 			}
 		})
 	}
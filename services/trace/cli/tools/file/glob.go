@@ -60,6 +60,7 @@ func (t *GlobTool) Definition() tools.ToolDefinition {
 				Type:        tools.ParamTypeString,
 				Description: "Directory to search in. Defaults to working directory.",
 				Required:    false,
+				Format:      tools.ParamFormatProjectPath,
 			},
 			"limit": {
 				Type:        tools.ParamTypeInt,
@@ -90,6 +91,11 @@ func (t *GlobTool) Definition() tools.ToolDefinition {
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *GlobTool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), GlobResult{})
+}
+
 // Execute finds files matching the glob pattern.
 func (t *GlobTool) Execute(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
 	start := time.Now()
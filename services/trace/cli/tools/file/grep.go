@@ -62,6 +62,7 @@ func (t *GrepTool) Definition() tools.ToolDefinition {
 				Type:        tools.ParamTypeString,
 				Description: "File or directory to search. Defaults to working directory.",
 				Required:    false,
+				Format:      tools.ParamFormatProjectPath,
 			},
 			"glob": {
 				Type:        tools.ParamTypeString,
@@ -137,6 +138,11 @@ func (t *GrepTool) Definition() tools.ToolDefinition {
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *GrepTool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), GrepResult{})
+}
+
 // Execute searches for content matching the pattern.
 func (t *GrepTool) Execute(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
 	start := time.Now()
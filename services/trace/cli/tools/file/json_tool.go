@@ -55,6 +55,7 @@ func (t *JSONTool) Definition() tools.ToolDefinition {
 				Type:        tools.ParamTypeString,
 				Description: "Path to the JSON file. Can be absolute or relative to the project root.",
 				Required:    true,
+				Format:      tools.ParamFormatProjectPath,
 			},
 			"query": {
 				Type:        tools.ParamTypeString,
@@ -98,6 +99,11 @@ func (t *JSONTool) Definition() tools.ToolDefinition {
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *JSONTool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), JSONResult{})
+}
+
 // Execute queries or validates a JSON file.
 func (t *JSONTool) Execute(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
 	start := time.Now()
@@ -197,7 +203,7 @@ func (t *JSONTool) Execute(ctx context.Context, params tools.TypedParams) (*tool
 	}
 
 	// Execute query
-	value, err := queryJSON(data, p.Query)
+	value, err := QueryJSON(data, p.Query)
 	if err != nil {
 		return &tools.Result{
 			Success:  false,
@@ -232,9 +238,10 @@ func (t *JSONTool) Execute(ctx context.Context, params tools.TypedParams) (*tool
 	}, nil
 }
 
-// queryJSON executes a jq-style query on JSON data.
+// QueryJSON applies the JSON tool's jq-style query to decoded JSON or
+// YAML data.
 // Supports: .field, .field.nested, .array[0], .array[*]
-func queryJSON(data any, query string) (any, error) {
+func QueryJSON(data any, query string) (any, error) {
 	if query == "" || query == "." {
 		return data, nil
 	}
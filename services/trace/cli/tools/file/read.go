@@ -58,6 +58,7 @@ func (t *ReadTool) Definition() tools.ToolDefinition {
 				Type:        tools.ParamTypeString,
 				Description: "Path to the file to read. Can be absolute or relative to the project root.",
 				Required:    true,
+				Format:      tools.ParamFormatProjectPath,
 			},
 			"offset": {
 				Type:        tools.ParamTypeInt,
@@ -95,6 +96,11 @@ func (t *ReadTool) Definition() tools.ToolDefinition {
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *ReadTool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), ReadResult{})
+}
+
 // Execute reads a file and returns its contents with line numbers.
 func (t *ReadTool) Execute(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
 	start := time.Now()
@@ -54,6 +54,7 @@ func (t *TreeTool) Definition() tools.ToolDefinition {
 				Type:        tools.ParamTypeString,
 				Description: "Directory to visualize. Defaults to working directory.",
 				Required:    false,
+				Format:      tools.ParamFormatProjectPath,
 			},
 			"depth": {
 				Type:        tools.ParamTypeInt,
@@ -96,6 +97,11 @@ func (t *TreeTool) Definition() tools.ToolDefinition {
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *TreeTool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), TreeResult{})
+}
+
 // Execute generates a tree visualization of a directory.
 func (t *TreeTool) Execute(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
 	start := time.Now()
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // WriteTool implements the Write file operation.
@@ -54,6 +55,7 @@ func (t *WriteTool) Definition() tools.ToolDefinition {
 				Type:        tools.ParamTypeString,
 				Description: "Path for the file to write. Can be absolute or relative to the project root.",
 				Required:    true,
+				Format:      tools.ParamFormatProjectPath,
 			},
 			"content": {
 				Type:        tools.ParamTypeString,
@@ -77,6 +79,11 @@ func (t *WriteTool) Definition() tools.ToolDefinition {
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *WriteTool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), WriteResult{})
+}
+
 // Execute writes content to a file using atomic write.
 func (t *WriteTool) Execute(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
 	start := time.Now()
@@ -144,8 +151,8 @@ func (t *WriteTool) Execute(ctx context.Context, params tools.TypedParams) (*too
 		if err := t.config.LSPReleaser.ReleaseFile(ctx, p.FilePath); err != nil {
 			// Log but continue - best effort
 			slog.Warn("failed to release file for LSP",
-				slog.String("file", p.FilePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("file", p.FilePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 		}
 	}
@@ -159,8 +166,8 @@ func (t *WriteTool) Execute(ctx context.Context, params tools.TypedParams) (*too
 		if reopenErr := t.config.LSPReleaser.ReopenFile(ctx, p.FilePath, p.Content, langID); reopenErr != nil {
 			// Log but continue - best effort
 			slog.Warn("failed to reopen file for LSP",
-				slog.String("file", p.FilePath),
-				slog.String("error", reopenErr.Error()),
+				telemetry.SensitiveLog("file", p.FilePath),
+				telemetry.SensitiveLogError("error", reopenErr),
 			)
 		}
 	}
@@ -180,8 +187,8 @@ func (t *WriteTool) Execute(ctx context.Context, params tools.TypedParams) (*too
 		if err := t.config.GraphRefresher.RefreshFiles(ctx, []string{p.FilePath}); err != nil {
 			// Log but don't fail the write - graph will eventually catch up via fsnotify
 			slog.Warn("failed to refresh graph after write",
-				slog.String("file", p.FilePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("file", p.FilePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 		}
 	}
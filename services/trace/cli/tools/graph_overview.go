@@ -100,6 +100,10 @@ func (t *graphOverviewTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *graphOverviewTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), GraphOverviewResult{})
+}
+
 // GraphOverviewResult is the output of the graph_overview tool.
 type GraphOverviewResult struct {
 	Project         string              `json:"project"`
@@ -0,0 +1,122 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// =============================================================================
+// Paged Results
+// =============================================================================
+//
+// find_callers, find_callees, find_implementations, find_references and
+// find_symbol return one page of at most limit results. When more exist,
+// the output carries a next_cursor and the text output starts with a note
+// telling the model to repeat the call with it. The cursor records the
+// offset and the generation of the frozen graph (graph.Graph.Generation),
+// so a cursor outliving a graph refresh fails with graph.ErrStaleCursor
+// instead of returning a shifted page.
+
+// cursorParamDef is the shared cursor parameter of the paged tools.
+var cursorParamDef = ParamDef{
+	Type:        ParamTypeString,
+	Description: "next_cursor from an earlier call with the same arguments, to fetch the next page of results (optional)",
+	Required:    false,
+}
+
+// exhaustiveResultsLine closes the text output of a complete answer.
+const exhaustiveResultsLine = "The graph has been fully indexed — these results are exhaustive.\n"
+
+// PageInfo describes the page of results a paged tool returned.
+type PageInfo struct {
+	// TotalAvailable is the number of results across all pages. For
+	// tools matching several symbols it sums their results.
+	TotalAvailable int `json:"total_available"`
+
+	// NextCursor fetches the next page when passed as cursor. Empty on
+	// the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// parseCursorParam returns the cursor argument, or "".
+func parseCursorParam(params map[string]any) string {
+	if raw, ok := params["cursor"]; ok {
+		if cursor, ok := parseStringParam(raw); ok {
+			return strings.TrimSpace(cursor)
+		}
+	}
+	return ""
+}
+
+// cursorGeneration returns the generation cursors over g record.
+func cursorGeneration(g *graph.Graph) string {
+	if g == nil {
+		return ""
+	}
+	return strconv.FormatUint(g.Generation(), 10)
+}
+
+// decodeToolCursor returns the offset of a cursor issued by a paged tool
+// for the same query over g. Errors wrap graph.ErrInvalidCursor or
+// graph.ErrStaleCursor.
+func decodeToolCursor(g *graph.Graph, cursor string, query ...string) (int, error) {
+	return graph.DecodeCursor(cursor, cursorGeneration(g), query...)
+}
+
+// nextToolCursor returns the cursor of the page after offset+limit, or ""
+// when there are no more results.
+func nextToolCursor(g *graph.Graph, more bool, offset, limit int, query ...string) string {
+	if !more {
+		return ""
+	}
+	return graph.EncodeCursor(cursorGeneration(g), offset+limit, query...)
+}
+
+// withPageNote prefixes text output with the position of the page and,
+// when more results exist, how to fetch them. The note goes first so it
+// survives truncation of long tool results. The exhaustive-results footer
+// is reworded on pages that are not the last.
+//
+// Inputs:
+//
+//	text - The tool's text output.
+//	tool - The tool name, for the follow-up instruction.
+//	offset - The offset of the page.
+//	shown - The number of results in the page.
+//	info - The page's PageInfo.
+//
+// Outputs:
+//
+//	string - text with the note; text unchanged for a single page.
+func withPageNote(text, tool string, offset, shown int, info PageInfo) string {
+	if info.NextCursor == "" && offset == 0 {
+		return text
+	}
+	var sb strings.Builder
+	if shown > 0 {
+		fmt.Fprintf(&sb, "Page: results %d-%d of %d.", offset+1, offset+shown, info.TotalAvailable)
+	} else {
+		fmt.Fprintf(&sb, "Page: no results past offset %d of %d.", offset, info.TotalAvailable)
+	}
+	if info.NextCursor != "" {
+		fmt.Fprintf(&sb, " More results exist: call %s again with the same arguments and cursor=%q.\n\n", tool, info.NextCursor)
+		text = strings.Replace(text, exhaustiveResultsLine,
+			"The graph has been fully indexed — this page is partial; fetch the next page for the rest.\n", 1)
+	} else {
+		sb.WriteString(" This is the last page.\n\n")
+	}
+	return sb.String() + text
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestFindCallersTool_Paging(t *testing.T) {
+	ctx := context.Background()
+	g, idx := createTestGraphWithCallers(t)
+	tool := NewFindCallersTool(g, idx)
+
+	first, err := tool.Execute(ctx, MapParams{Params: map[string]any{
+		"function_name": "parseConfig",
+		"limit":         2,
+	}})
+	if err != nil || !first.Success {
+		t.Fatalf("first page: %v, %s", err, first.Error)
+	}
+	output := first.Output.(FindCallersOutput)
+	if output.TotalCallers != 2 || output.TotalAvailable != 3 || output.NextCursor == "" {
+		t.Errorf("first page: %d of %d callers, cursor %q", output.TotalCallers, output.TotalAvailable, output.NextCursor)
+	}
+	if first.NextCursor != output.NextCursor {
+		t.Errorf("Result.NextCursor = %q, want %q", first.NextCursor, output.NextCursor)
+	}
+	if !strings.HasPrefix(first.OutputText, "Page: results 1-2 of 3.") || !strings.Contains(first.OutputText, "cursor=") {
+		t.Errorf("first page text lacks the page note:\n%s", first.OutputText)
+	}
+
+	second, err := tool.Execute(ctx, MapParams{Params: map[string]any{
+		"function_name": "parseConfig",
+		"limit":         2,
+		"cursor":        first.NextCursor,
+	}})
+	if err != nil || !second.Success {
+		t.Fatalf("second page: %v, %s", err, second.Error)
+	}
+	output = second.Output.(FindCallersOutput)
+	if output.TotalCallers != 1 || output.NextCursor != "" {
+		t.Errorf("second page: %d callers, cursor %q; want 1 and none", output.TotalCallers, output.NextCursor)
+	}
+	if !strings.Contains(second.OutputText, "This is the last page.") {
+		t.Errorf("second page text:\n%s", second.OutputText)
+	}
+
+	t.Run("cursor of another graph generation", func(t *testing.T) {
+		rebuilt, rebuiltIdx := createTestGraphWithCallers(t)
+		result, err := NewFindCallersTool(rebuilt, rebuiltIdx).Execute(ctx, MapParams{Params: map[string]any{
+			"function_name": "parseConfig",
+			"limit":         2,
+			"cursor":        first.NextCursor,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Success || !strings.Contains(result.Error, "older graph generation") {
+			t.Errorf("stale cursor: success=%v error=%q", result.Success, result.Error)
+		}
+	})
+
+	t.Run("cursor of another query", func(t *testing.T) {
+		result, err := tool.Execute(ctx, MapParams{Params: map[string]any{
+			"function_name": "main",
+			"cursor":        first.NextCursor,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Success {
+			t.Error("cursor of another query was accepted")
+		}
+	})
+}
@@ -0,0 +1,232 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Limit kinds recorded when a tool execution hits a sandbox limit.
+const (
+	// LimitTimeout indicates the tool exceeded its execution timeout.
+	LimitTimeout = "timeout"
+
+	// LimitOutputSize indicates the tool output exceeded MaxResultBytes.
+	LimitOutputSize = "output_size"
+
+	// LimitConcurrency indicates the tool waited for a concurrency slot.
+	LimitConcurrency = "concurrency"
+)
+
+// Metadata keys set on Result.Metadata when a limit is hit.
+const (
+	// MetaLimitHit names the limit that was hit (LimitTimeout, LimitOutputSize).
+	MetaLimitHit = "limit_hit"
+
+	// MetaOriginalBytes is the output size before truncation.
+	MetaOriginalBytes = "original_bytes"
+
+	// MetaTruncated mirrors Result.Truncated so the flag survives JSON
+	// conversion into the LLM-visible tool message.
+	MetaTruncated = "truncated"
+)
+
+// elisionMarkerFormat is inserted between head and tail of truncated output.
+const elisionMarkerFormat = "\n\n... [truncated=true: %d bytes elided of %d total] ...\n\n"
+
+// ToolExecutionPolicy bounds the resources a single tool call may consume.
+//
+// Description:
+//
+//	A policy is attached to the Registry (project-wide defaults) and may be
+//	overridden per run through WithExecutionPolicy on the Executor. It caps
+//	execution time per tool, the size of the result payload handed back to
+//	the LLM, and how many tool executions may be in flight at once.
+//
+// Thread Safety: ToolExecutionPolicy is a value type; copies are safe to share.
+type ToolExecutionPolicy struct {
+	// DefaultTimeout applies to tools without a per-tool override.
+	// Zero falls back to ToolDefinition.Timeout / ExecutorOptions.DefaultTimeout.
+	DefaultTimeout time.Duration `json:"default_timeout"`
+
+	// ToolTimeouts overrides the timeout for specific tools by name.
+	ToolTimeouts map[string]time.Duration `json:"tool_timeouts,omitempty"`
+
+	// MaxResultBytes caps Result.OutputText. Larger outputs keep a head and
+	// tail with an elision marker between them. Zero disables the cap.
+	MaxResultBytes int `json:"max_result_bytes"`
+
+	// MaxConcurrent caps in-flight tool executions per Executor (per run).
+	// Zero disables the cap.
+	MaxConcurrent int `json:"max_concurrent"`
+}
+
+// DefaultToolExecutionPolicy returns the policy used by NewRegistry.
+//
+// Outputs:
+//
+//	ToolExecutionPolicy - 30s timeout, 64KiB result cap, 4 concurrent executions.
+func DefaultToolExecutionPolicy() ToolExecutionPolicy {
+	return ToolExecutionPolicy{
+		DefaultTimeout: 30 * time.Second,
+		ToolTimeouts:   make(map[string]time.Duration),
+		MaxResultBytes: 64 * 1024,
+		MaxConcurrent:  4,
+	}
+}
+
+// TimeoutFor resolves the effective timeout for a tool.
+//
+// Description:
+//
+//	Precedence: ToolTimeouts[name] > ToolDefinition.Timeout > DefaultTimeout
+//	> fallback. The fallback is used when nothing else is configured.
+//
+// Inputs:
+//
+//	def - The tool definition.
+//	fallback - Timeout to use when the policy and definition are silent.
+//
+// Outputs:
+//
+//	time.Duration - The effective timeout.
+func (p ToolExecutionPolicy) TimeoutFor(def ToolDefinition, fallback time.Duration) time.Duration {
+	if t, ok := p.ToolTimeouts[def.Name]; ok && t > 0 {
+		return t
+	}
+	if def.Timeout > 0 {
+		return def.Timeout
+	}
+	if p.DefaultTimeout > 0 {
+		return p.DefaultTimeout
+	}
+	return fallback
+}
+
+// Merge returns a copy of p with non-zero fields from overrides applied.
+//
+// Description:
+//
+//	Used to layer a per-run policy on top of the registry policy. Tool
+//	timeouts are merged key by key; overrides win on conflict.
+//
+// Inputs:
+//
+//	overrides - Partial policy. Zero fields are ignored.
+//
+// Outputs:
+//
+//	ToolExecutionPolicy - The merged policy. The receiver is not modified.
+func (p ToolExecutionPolicy) Merge(overrides ToolExecutionPolicy) ToolExecutionPolicy {
+	merged := p
+	merged.ToolTimeouts = make(map[string]time.Duration, len(p.ToolTimeouts)+len(overrides.ToolTimeouts))
+	for name, t := range p.ToolTimeouts {
+		merged.ToolTimeouts[name] = t
+	}
+	for name, t := range overrides.ToolTimeouts {
+		if t > 0 {
+			merged.ToolTimeouts[name] = t
+		}
+	}
+	if overrides.DefaultTimeout > 0 {
+		merged.DefaultTimeout = overrides.DefaultTimeout
+	}
+	if overrides.MaxResultBytes > 0 {
+		merged.MaxResultBytes = overrides.MaxResultBytes
+	}
+	if overrides.MaxConcurrent > 0 {
+		merged.MaxConcurrent = overrides.MaxConcurrent
+	}
+	return merged
+}
+
+// TruncateHeadTail truncates text to at most maxBytes, keeping head and tail.
+//
+// Description:
+//
+//	Deterministically keeps the first and last portions of text (roughly
+//	two thirds head, one third tail) separated by an elision marker that
+//	states truncated=true and how many bytes were dropped. Cuts are moved
+//	back to UTF-8 rune boundaries so the output is always valid UTF-8.
+//
+// Inputs:
+//
+//	text - The text to truncate.
+//	maxBytes - Maximum output size in bytes. <= 0 disables truncation.
+//
+// Outputs:
+//
+//	string - The (possibly) truncated text.
+//	bool - True if truncation occurred.
+func TruncateHeadTail(text string, maxBytes int) (string, bool) {
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text, false
+	}
+
+	marker := fmt.Sprintf(elisionMarkerFormat, len(text)-maxBytes, len(text))
+	budget := maxBytes - len(marker)
+	if budget <= 0 {
+		return marker, true
+	}
+
+	headLen := budget * 2 / 3
+	tailLen := budget - headLen
+
+	for headLen > 0 && !utf8.RuneStart(text[headLen]) {
+		headLen--
+	}
+	tailStart := len(text) - tailLen
+	for tailStart < len(text) && !utf8.RuneStart(text[tailStart]) {
+		tailStart++
+	}
+
+	return text[:headLen] + marker + text[tailStart:], true
+}
+
+// Sandbox limit metrics.
+var (
+	policyMeter = otel.Meter("aleutian.tools.policy")
+
+	toolLimitHits metric.Int64Counter
+
+	policyMetricsOnce sync.Once
+	policyMetricsErr  error
+)
+
+// initPolicyMetrics initializes limit metrics.
+func initPolicyMetrics() error {
+	policyMetricsOnce.Do(func() {
+		toolLimitHits, policyMetricsErr = policyMeter.Int64Counter(
+			"trace_tool_limit_hits_total",
+			metric.WithDescription("Total tool executions that hit a sandbox limit"),
+		)
+	})
+	return policyMetricsErr
+}
+
+// recordLimitHit records that a tool hit the given limit kind.
+func recordLimitHit(ctx context.Context, toolName, limit string) {
+	if err := initPolicyMetrics(); err != nil {
+		return
+	}
+	toolLimitHits.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("tool", toolName),
+		attribute.String("limit", limit),
+	))
+}
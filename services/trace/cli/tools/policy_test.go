@@ -0,0 +1,326 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// funcTool is a tool whose Execute behaviour is supplied by the test.
+type funcTool struct {
+	name    string
+	timeout time.Duration
+	fn      func(ctx context.Context) (*Result, error)
+}
+
+func (t *funcTool) Name() string           { return t.name }
+func (t *funcTool) Category() ToolCategory { return CategoryExploration }
+func (t *funcTool) Definition() ToolDefinition {
+	return ToolDefinition{Name: t.name, Timeout: t.timeout}
+}
+func (t *funcTool) Schema() ToolSchema { return NewToolSchema(t.Definition(), nil) }
+func (t *funcTool) Execute(ctx context.Context, _ TypedParams) (*Result, error) {
+	return t.fn(ctx)
+}
+
+func TestTruncateHeadTail(t *testing.T) {
+	t.Run("under cap is unchanged", func(t *testing.T) {
+		out, truncated := TruncateHeadTail("short", 100)
+		if truncated || out != "short" {
+			t.Errorf("expected unchanged, got %q truncated=%v", out, truncated)
+		}
+	})
+
+	t.Run("zero cap disables", func(t *testing.T) {
+		text := strings.Repeat("x", 1000)
+		out, truncated := TruncateHeadTail(text, 0)
+		if truncated || out != text {
+			t.Error("expected zero cap to disable truncation")
+		}
+	})
+
+	t.Run("keeps head and tail with marker", func(t *testing.T) {
+		text := "HEAD" + strings.Repeat("m", 5000) + "TAIL"
+		out, truncated := TruncateHeadTail(text, 500)
+		if !truncated {
+			t.Fatal("expected truncation")
+		}
+		if len(out) > 500 {
+			t.Errorf("output %d bytes exceeds cap 500", len(out))
+		}
+		if !strings.HasPrefix(out, "HEAD") || !strings.HasSuffix(out, "TAIL") {
+			t.Errorf("expected head and tail preserved, got %q...%q", out[:10], out[len(out)-10:])
+		}
+		if !strings.Contains(out, "truncated=true") {
+			t.Error("expected elision marker with truncated=true")
+		}
+	})
+
+	t.Run("deterministic", func(t *testing.T) {
+		text := strings.Repeat("abcdef", 2000)
+		a, _ := TruncateHeadTail(text, 1024)
+		b, _ := TruncateHeadTail(text, 1024)
+		if a != b {
+			t.Error("expected identical output for identical input")
+		}
+	})
+
+	t.Run("respects rune boundaries", func(t *testing.T) {
+		text := strings.Repeat("日本語", 1000)
+		out, truncated := TruncateHeadTail(text, 301)
+		if !truncated {
+			t.Fatal("expected truncation")
+		}
+		if !utf8.ValidString(out) {
+			t.Error("expected valid UTF-8 output")
+		}
+	})
+}
+
+func TestToolExecutionPolicy_TimeoutFor(t *testing.T) {
+	policy := ToolExecutionPolicy{
+		DefaultTimeout: 10 * time.Second,
+		ToolTimeouts:   map[string]time.Duration{"data_flow": 2 * time.Minute},
+	}
+
+	tests := []struct {
+		name string
+		def  ToolDefinition
+		want time.Duration
+	}{
+		{"per-tool override wins", ToolDefinition{Name: "data_flow", Timeout: time.Second}, 2 * time.Minute},
+		{"definition timeout", ToolDefinition{Name: "find_callers", Timeout: 5 * time.Second}, 5 * time.Second},
+		{"policy default", ToolDefinition{Name: "find_callers"}, 10 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.TimeoutFor(tt.def, time.Minute); got != tt.want {
+				t.Errorf("TimeoutFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := (ToolExecutionPolicy{}).TimeoutFor(ToolDefinition{Name: "x"}, time.Minute); got != time.Minute {
+		t.Errorf("expected fallback, got %v", got)
+	}
+}
+
+func TestToolExecutionPolicy_Merge(t *testing.T) {
+	base := DefaultToolExecutionPolicy()
+	base.ToolTimeouts["a"] = time.Second
+
+	merged := base.Merge(ToolExecutionPolicy{
+		ToolTimeouts:  map[string]time.Duration{"b": 2 * time.Second},
+		MaxConcurrent: 1,
+	})
+
+	if merged.ToolTimeouts["a"] != time.Second || merged.ToolTimeouts["b"] != 2*time.Second {
+		t.Errorf("expected merged tool timeouts, got %v", merged.ToolTimeouts)
+	}
+	if merged.MaxConcurrent != 1 {
+		t.Errorf("expected MaxConcurrent override, got %d", merged.MaxConcurrent)
+	}
+	if merged.MaxResultBytes != base.MaxResultBytes {
+		t.Errorf("expected MaxResultBytes kept, got %d", merged.MaxResultBytes)
+	}
+	if _, ok := base.ToolTimeouts["b"]; ok {
+		t.Error("Merge must not mutate the receiver")
+	}
+}
+
+func TestExecutor_Execute_TimeoutReturnsLimitError(t *testing.T) {
+	registry := NewRegistry()
+	release := make(chan struct{})
+	defer close(release)
+
+	// Ignores ctx entirely, simulating a pathological traversal.
+	registry.Register(&funcTool{
+		name:    "hang",
+		timeout: 20 * time.Millisecond,
+		fn: func(ctx context.Context) (*Result, error) {
+			<-release
+			return &Result{Success: true}, nil
+		},
+	})
+	executor := NewExecutor(registry, nil)
+
+	start := time.Now()
+	_, err := executor.Execute(context.Background(), &Invocation{ToolName: "hang"})
+	if time.Since(start) > time.Second {
+		t.Fatal("executor did not return at the deadline")
+	}
+
+	var limitErr *LimitError
+	if !errors.As(err, &limitErr) {
+		t.Fatalf("expected *LimitError, got %v", err)
+	}
+	if limitErr.Limit != LimitTimeout || limitErr.Tool != "hang" {
+		t.Errorf("unexpected limit error: %+v", limitErr)
+	}
+	if !errors.Is(err, ErrTimeout) {
+		t.Error("expected LimitError to unwrap to ErrTimeout")
+	}
+	if limitErr.Hint() == "" {
+		t.Error("expected a recovery hint")
+	}
+}
+
+func TestExecutor_Execute_TimedOutToolKeepsSlot(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetPolicy(ToolExecutionPolicy{MaxConcurrent: 1})
+	release := make(chan struct{})
+	returned := make(chan struct{})
+
+	// Ignores ctx, so it keeps running past its deadline.
+	registry.Register(&funcTool{
+		name:    "hang",
+		timeout: 20 * time.Millisecond,
+		fn: func(ctx context.Context) (*Result, error) {
+			defer close(returned)
+			<-release
+			return &Result{Success: true}, nil
+		},
+	})
+	registry.Register(&funcTool{
+		name: "quick",
+		fn: func(ctx context.Context) (*Result, error) {
+			return &Result{Success: true}, nil
+		},
+	})
+	executor := NewExecutor(registry, nil)
+
+	if _, err := executor.Execute(context.Background(), &Invocation{ToolName: "hang"}); !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+
+	// The timed-out call is still running and must still hold the only slot.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := executor.Execute(ctx, &Invocation{ToolName: "quick"}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected to wait for the slot until ctx expired, got %v", err)
+	}
+
+	close(release)
+	<-returned
+	result, err := executor.Execute(context.Background(), &Invocation{ToolName: "quick"})
+	if err != nil {
+		t.Fatalf("expected the slot to be free once the call returned, got %v", err)
+	}
+	if !result.Success {
+		t.Error("expected success")
+	}
+}
+
+func TestExecutor_Execute_PerRunTimeoutOverride(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(&funcTool{
+		name:    "slow",
+		timeout: 10 * time.Millisecond,
+		fn: func(ctx context.Context) (*Result, error) {
+			select {
+			case <-time.After(50 * time.Millisecond):
+				return &Result{Success: true, OutputText: "done"}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	})
+
+	executor := NewExecutorWithOptions(registry, nil, WithExecutionPolicy(ToolExecutionPolicy{
+		ToolTimeouts: map[string]time.Duration{"slow": time.Second},
+	}))
+
+	result, err := executor.Execute(context.Background(), &Invocation{ToolName: "slow"})
+	if err != nil {
+		t.Fatalf("expected override to allow completion, got %v", err)
+	}
+	if !result.Success {
+		t.Error("expected success")
+	}
+}
+
+func TestExecutor_Execute_CapsResultBytes(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetPolicy(ToolExecutionPolicy{MaxResultBytes: 256})
+	registry.Register(&funcTool{
+		name: "big",
+		fn: func(ctx context.Context) (*Result, error) {
+			return &Result{Success: true, OutputText: strings.Repeat("z", 4096), TokensUsed: 10}, nil
+		},
+	})
+	executor := NewExecutor(registry, nil)
+
+	result, err := executor.Execute(context.Background(), &Invocation{ToolName: "big"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Truncated {
+		t.Error("expected Truncated=true")
+	}
+	if len(result.OutputText) > 256 {
+		t.Errorf("output %d bytes exceeds cap", len(result.OutputText))
+	}
+	if result.Metadata[MetaLimitHit] != LimitOutputSize {
+		t.Errorf("expected limit_hit metadata, got %v", result.Metadata[MetaLimitHit])
+	}
+	if result.Metadata[MetaOriginalBytes] != 4096 {
+		t.Errorf("expected original_bytes=4096, got %v", result.Metadata[MetaOriginalBytes])
+	}
+}
+
+func TestExecutor_Execute_MaxConcurrent(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetPolicy(ToolExecutionPolicy{MaxConcurrent: 2})
+
+	var inFlight, peak int32
+	registry.Register(&funcTool{
+		name: "busy",
+		fn: func(ctx context.Context) (*Result, error) {
+			n := atomic.AddInt32(&inFlight, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			return &Result{Success: true}, nil
+		},
+	})
+	executor := NewExecutorWithOptions(registry, &ExecutorOptions{
+		DefaultTimeout:  time.Second,
+		MaxOutputTokens: 4000,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := executor.Execute(context.Background(), &Invocation{ToolName: "busy"}); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("peak concurrency %d exceeds MaxConcurrent=2", peak)
+	}
+}
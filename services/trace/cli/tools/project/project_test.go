@@ -0,0 +1,252 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package project
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+// writeFiles creates files under a temporary root.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range files {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// parseOne parses a tools file holding one tool.
+func parseOne(t *testing.T, doc string) Spec {
+	t.Helper()
+	specs, err := Parse([]byte(doc), nil)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(specs) != 1 {
+		t.Fatalf("got %d specs, want 1", len(specs))
+	}
+	return specs[0]
+}
+
+// run executes a spec against root.
+func run(t *testing.T, spec Spec, root string, params map[string]any) *tools.Result {
+	t.Helper()
+	result, err := NewTool(spec, root).Execute(context.Background(), tools.MapParams{Tool: spec.Name, Params: params})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	return result
+}
+
+func TestParse_Invalid(t *testing.T) {
+	cases := map[string]string{
+		"unknown key":        "tools:\n  - name: a\n    description: d\n    kind: glob\n    paths: ['*']\n    colour: red\n",
+		"bad name":           "tools:\n  - name: Find-It\n    description: d\n    kind: glob\n    paths: ['*']\n",
+		"no description":     "tools:\n  - name: a\n    kind: glob\n    paths: ['*']\n",
+		"unknown kind":       "tools:\n  - name: a\n    description: d\n    kind: sql\n",
+		"glob without paths": "tools:\n  - name: a\n    description: d\n    kind: glob\n",
+		"bad pattern":        "tools:\n  - name: a\n    description: d\n    kind: regex\n    paths: ['*']\n    pattern: '('\n",
+		"unknown field":      "tools:\n  - name: a\n    description: d\n    kind: regex\n    paths: ['*']\n    pattern: '(?P<x>a)'\n    fields: [y]\n",
+		"escaping path":      "tools:\n  - name: a\n    description: d\n    kind: glob\n    paths: ['../*']\n",
+		"absolute file":      "tools:\n  - name: a\n    description: d\n    kind: extract\n    file: /etc/config.json\n",
+		"undeclared param":   "tools:\n  - name: a\n    description: d\n    kind: extract\n    file: '{{env}}.json'\n",
+		"limit too large":    "tools:\n  - name: a\n    description: d\n    kind: glob\n    paths: ['*']\n    limit: 10000\n",
+		"duplicate":          "tools:\n  - {name: a, description: d, kind: glob, paths: ['*']}\n  - {name: a, description: d, kind: glob, paths: ['*']}\n",
+	}
+	for name, doc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(doc), nil); !errors.Is(err, ErrInvalidTool) {
+				t.Errorf("err = %v, want ErrInvalidTool", err)
+			}
+		})
+	}
+}
+
+func TestParse_RejectsBuiltinName(t *testing.T) {
+	doc := "tools:\n  - name: find_callers\n    description: d\n    kind: glob\n    paths: ['*']\n"
+	_, err := Parse([]byte(doc), []string{"Glob", "find_callers"})
+	if !errors.Is(err, ErrInvalidTool) || !strings.Contains(err.Error(), "built-in") {
+		t.Fatalf("err = %v, want a built-in collision", err)
+	}
+	doc = "tools:\n  - name: glob\n    description: d\n    kind: glob\n    paths: ['*']\n"
+	if _, err := Parse([]byte(doc), []string{"Glob"}); !errors.Is(err, ErrInvalidTool) {
+		t.Fatalf("err = %v, want a case-insensitive collision", err)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	specs, err := Load(t.TempDir(), nil)
+	if err != nil || specs != nil {
+		t.Fatalf("Load = %v, %v; want nil, nil", specs, err)
+	}
+}
+
+func TestDefinition(t *testing.T) {
+	spec := parseOne(t, `
+tools:
+  - name: list_handlers
+    description: Lists HTTP handlers.
+    kind: glob
+    keywords: [handler, endpoint]
+    paths: ["**/*_handler.go"]
+    parameters:
+      scope:
+        description: Subdirectory.
+        enum: [api, admin]
+`)
+	def := NewTool(spec, t.TempDir()).Definition()
+	if def.Category != tools.CategoryProject || def.WhenToUse.UseWhen != "Lists HTTP handlers." {
+		t.Errorf("definition = %+v", def)
+	}
+	if len(def.WhenToUse.Keywords) != 2 || def.Parameters["scope"].Type != tools.ParamTypeString || len(def.Parameters["scope"].Enum) != 2 {
+		t.Errorf("definition = %+v", def)
+	}
+}
+
+func TestGlobTool(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"migrations/001_init.sql":      "",
+		"migrations/002_users.sql":     "",
+		"migrations/old/000_seed.sql":  "",
+		"node_modules/x/migration.sql": "",
+		"README.md":                    "",
+	})
+	spec := parseOne(t, `
+tools:
+  - name: list_migrations
+    description: Lists SQL migrations.
+    kind: glob
+    paths: ["{{dir}}/**/*.sql"]
+    exclude_paths: ["**/old/**"]
+    parameters:
+      dir: {description: Directory, default: migrations}
+`)
+	result := run(t, spec, root, nil)
+	if !result.Success {
+		t.Fatalf("Execute failed: %s", result.Error)
+	}
+	got := result.Output.(*GlobResult)
+	if strings.Join(got.Files, ",") != "migrations/001_init.sql,migrations/002_users.sql" {
+		t.Errorf("files = %v", got.Files)
+	}
+
+	spec.Limit = 1
+	if got := run(t, spec, root, nil).Output.(*GlobResult); got.Count != 1 || !got.Truncated {
+		t.Errorf("limited result = %+v", got)
+	}
+
+	if result := run(t, spec, root, map[string]any{"dir": "../etc"}); result.Success {
+		t.Error("a parameter leaving the root was accepted")
+	}
+}
+
+func TestRegexTool(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"config/flags.go": "package config\n\nfunc init() {\n\tRegister(\"checkout.v2\", true)\n\tRegister(\"search.beta\", false)\n}\n",
+		"config/other.go": "package config\n\n// Register(\"commented\", true)\n",
+	})
+	spec := parseOne(t, `
+tools:
+  - name: list_feature_flags
+    description: Lists feature flags.
+    kind: regex
+    paths: ["config/*.go"]
+    pattern: '^\s*Register\("(?P<flag>{{prefix}}[a-z0-9_.]*)",\s*(?P<default>true|false)'
+    parameters:
+      prefix: {description: Flag prefix}
+`)
+	result := run(t, spec, root, nil)
+	if !result.Success {
+		t.Fatalf("Execute failed: %s", result.Error)
+	}
+	got := result.Output.(*RegexResult)
+	if got.Count != 2 || got.FilesSearched != 2 {
+		t.Fatalf("result = %+v", got)
+	}
+	m := got.Matches[0]
+	if m.File != "config/flags.go" || m.Line != 4 || m.Captures["flag"] != "checkout.v2" || m.Captures["default"] != "true" {
+		t.Errorf("first match = %+v", m)
+	}
+
+	// Parameter values are matched literally.
+	got = run(t, spec, root, map[string]any{"prefix": "search."}).Output.(*RegexResult)
+	if got.Count != 1 || got.Matches[0].Captures["flag"] != "search.beta" {
+		t.Errorf("prefixed result = %+v", got)
+	}
+	if got := run(t, spec, root, map[string]any{"prefix": ".*"}).Output.(*RegexResult); got.Count != 0 {
+		t.Errorf("a parameter was interpreted as a pattern: %+v", got)
+	}
+}
+
+func TestRegexTool_SkipsSymlinkOutsideRoot(t *testing.T) {
+	outside := writeFiles(t, map[string]string{"secret.txt": "token=abc\n"})
+	root := writeFiles(t, map[string]string{"notes.txt": "token=def\n"})
+	if err := os.Symlink(filepath.Join(outside, "secret.txt"), filepath.Join(root, "link.txt")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	spec := parseOne(t, "tools:\n  - name: tokens\n    description: d\n    kind: regex\n    paths: ['*.txt']\n    pattern: 'token=(\\w+)'\n")
+	got := run(t, spec, root, nil).Output.(*RegexResult)
+	if got.Count != 1 || got.Matches[0].Captures["1"] != "def" {
+		t.Errorf("result = %+v", got)
+	}
+}
+
+func TestExtractTool(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"deploy/prod.yaml": "services:\n  - name: api\n    replicas: 3\n  - name: worker\n    replicas: 1\n",
+		"package.json":     `{"scripts": {"test": "jest"}}`,
+	})
+	spec := parseOne(t, `
+tools:
+  - name: deploy_services
+    description: Lists deployed services.
+    kind: extract
+    file: "deploy/{{env}}.yaml"
+    query: ".services[*]"
+    parameters:
+      env: {description: Environment, required: true, enum: [prod, staging]}
+`)
+	result := run(t, spec, root, map[string]any{"env": "prod"})
+	if !result.Success {
+		t.Fatalf("Execute failed: %s", result.Error)
+	}
+	got := result.Output.(*ExtractResult)
+	services, ok := got.Value.([]any)
+	if got.File != "deploy/prod.yaml" || !ok || len(services) != 2 {
+		t.Errorf("result = %+v", got)
+	}
+
+	if result := run(t, spec, root, map[string]any{"env": "dev"}); result.Success {
+		t.Error("a value outside the enum was accepted")
+	}
+	if result := run(t, spec, root, map[string]any{"env": "staging"}); result.Success {
+		t.Error("a missing file produced a successful result")
+	}
+
+	spec = parseOne(t, "tools:\n  - name: test_script\n    description: d\n    kind: extract\n    file: package.json\n    query: .scripts.test\n")
+	if result := run(t, spec, root, nil); !result.Success || result.OutputText != "jest" {
+		t.Errorf("result = %+v", result)
+	}
+}
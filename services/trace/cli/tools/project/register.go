@@ -0,0 +1,35 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package project
+
+import (
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
+// RegisterProjectTools registers the tools of a project.
+//
+// Description:
+//
+//	Specs must come from Parse or Load with the registry's built-in names
+//	reserved, so registration never replaces a built-in tool.
+//
+// Inputs:
+//
+//	registry - The registry to add to.
+//	specs - The project's tools.
+//	projectRoot - The root the tools read from.
+//
+// Thread Safety: Safe if the registry is.
+func RegisterProjectTools(registry *tools.Registry, specs []Spec, projectRoot string) {
+	for _, spec := range specs {
+		registry.Register(NewTool(spec, projectRoot))
+	}
+}
@@ -0,0 +1,401 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package project provides declarative tools defined by the analyzed
+// project in .trace/tools.yaml.
+//
+// Each tool has a kind, and a generic executor per kind runs it:
+//
+//	glob     list the files matching path globs
+//	regex    search the files matching path globs with a regular
+//	         expression and return its capture groups
+//	extract  read one JSON or YAML file and return the value at a
+//	         jq-style path
+//
+// Tool parameters are substituted into the globs, pattern, file and query
+// through {{name}} placeholders. Files are read only from inside the
+// project root, with the same limits as the file tools.
+package project
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools/file"
+)
+
+// DefaultFile is the tools file, relative to the project root.
+const DefaultFile = ".trace/tools.yaml"
+
+// MaxTools bounds the number of tools in one file.
+const MaxTools = 32
+
+// MaxLimit is the largest result limit a tool may declare.
+const MaxLimit = file.MaxGrepLimit
+
+// DefaultLimit is the result limit of glob and regex tools that do not
+// declare one.
+const DefaultLimit = file.DefaultGrepLimit
+
+// ErrInvalidTool is returned for a tools file that cannot be used.
+var ErrInvalidTool = errors.New("invalid project tool")
+
+// Kind selects the executor of a tool.
+type Kind string
+
+// Tool kinds.
+const (
+	// KindGlob lists the files matching Paths.
+	KindGlob Kind = "glob"
+
+	// KindRegex searches the files matching Paths with Pattern.
+	KindRegex Kind = "regex"
+
+	// KindExtract returns the value at Query in File.
+	KindExtract Kind = "extract"
+)
+
+// Param declares one tool parameter.
+type Param struct {
+	// Type is "string", "integer" or "boolean". Empty is "string".
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+
+	// Description tells the model what to pass.
+	Description string `yaml:"description" json:"description"`
+
+	// Required parameters must be passed.
+	Required bool `yaml:"required,omitempty" json:"required,omitempty"`
+
+	// Default is used when the parameter is not passed.
+	Default any `yaml:"default,omitempty" json:"default,omitempty"`
+
+	// Enum restricts string values.
+	Enum []string `yaml:"enum,omitempty" json:"enum,omitempty"`
+}
+
+// Spec is one tool in the tools file.
+type Spec struct {
+	// Name is the tool name: lower case letters, digits and underscores.
+	Name string `yaml:"name" json:"name"`
+
+	// Description is shown to the tool router and the model.
+	Description string `yaml:"description" json:"description"`
+
+	// Kind selects the executor.
+	Kind Kind `yaml:"kind" json:"kind"`
+
+	// Keywords are query terms that suggest the tool to the router.
+	Keywords []string `yaml:"keywords,omitempty" json:"keywords,omitempty"`
+
+	// Parameters are the tool's arguments, by name.
+	Parameters map[string]Param `yaml:"parameters,omitempty" json:"parameters,omitempty"`
+
+	// Paths are globs of project-relative file paths, with ** across
+	// directories. Required for glob and regex tools.
+	Paths []string `yaml:"paths,omitempty" json:"paths,omitempty"`
+
+	// ExcludePaths are globs of paths to skip.
+	ExcludePaths []string `yaml:"exclude_paths,omitempty" json:"exclude_paths,omitempty"`
+
+	// Pattern is the regular expression of a regex tool, applied to each
+	// line.
+	Pattern string `yaml:"pattern,omitempty" json:"pattern,omitempty"`
+
+	// Fields are the capture groups a regex tool returns, by name or
+	// number. Empty returns every named group, or every group when none
+	// is named.
+	Fields []string `yaml:"fields,omitempty" json:"fields,omitempty"`
+
+	// File is the project-relative JSON or YAML file of an extract tool.
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+
+	// Query is the jq-style path of an extract tool: .field, .list[0],
+	// .list[*]. Empty returns the whole document.
+	Query string `yaml:"query,omitempty" json:"query,omitempty"`
+
+	// Returns describes the result for tool listings.
+	Returns string `yaml:"returns,omitempty" json:"returns,omitempty"`
+
+	// Limit caps the files or matches returned (DefaultLimit, at most
+	// MaxLimit).
+	Limit int `yaml:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// fileSpec is the document layout of the tools file.
+type fileSpec struct {
+	Tools []Spec `yaml:"tools"`
+}
+
+var (
+	namePattern        = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+	placeholderPattern = regexp.MustCompile(`\{\{\s*([a-z][a-z0-9_]*)\s*\}\}`)
+)
+
+// Parse reads and validates a tools file.
+//
+// Description:
+//
+//	The document has a single key, tools, listing Specs:
+//
+//	  tools:
+//	    - name: list_feature_flags
+//	      description: Lists the feature flags registered in config/flags.go.
+//	      kind: regex
+//	      paths: ["config/flags.go"]
+//	      pattern: 'Register\("(?P<flag>[a-z_.]+)",\s*(?P<default>true|false)'
+//	      keywords: [feature flag, flags]
+//
+//	Unknown keys are rejected, as are names matching reserved
+//	case-insensitively.
+//
+// Inputs:
+//
+//	data - The YAML document.
+//	reserved - Names of built-in tools.
+//
+// Outputs:
+//
+//	[]Spec - The tools, in file order.
+//	error - ErrInvalidTool (wrapped) naming the first bad tool.
+func Parse(data []byte, reserved []string) ([]Spec, error) {
+	var f fileSpec
+	dec := yaml.NewDecoder(strings.NewReader(string(data)))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTool, err)
+	}
+	if len(f.Tools) > MaxTools {
+		return nil, fmt.Errorf("%w: %d tools, at most %d", ErrInvalidTool, len(f.Tools), MaxTools)
+	}
+	taken := make(map[string]bool, len(reserved)+len(f.Tools))
+	for _, name := range reserved {
+		taken[strings.ToLower(name)] = true
+	}
+	seen := make(map[string]bool, len(f.Tools))
+	for i := range f.Tools {
+		s := &f.Tools[i]
+		label := s.Name
+		if label == "" {
+			label = fmt.Sprintf("#%d", i+1)
+		}
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("%w: tool %s: %v", ErrInvalidTool, label, err)
+		}
+		if taken[s.Name] {
+			return nil, fmt.Errorf("%w: tool %s: name collides with a built-in tool", ErrInvalidTool, label)
+		}
+		if seen[s.Name] {
+			return nil, fmt.Errorf("%w: tool %s: duplicate name", ErrInvalidTool, label)
+		}
+		seen[s.Name] = true
+	}
+	return f.Tools, nil
+}
+
+// Load reads the tools file of a project. See Parse.
+//
+// Outputs:
+//
+//	[]Spec - The tools. Nil, with a nil error, when the project has no
+//	  tools file.
+//	error - ErrInvalidTool (wrapped), or a read error.
+func Load(projectRoot string, reserved []string) ([]Spec, error) {
+	data, err := os.ReadFile(filepath.Join(projectRoot, filepath.FromSlash(DefaultFile)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", DefaultFile, err)
+	}
+	specs, err := Parse(data, reserved)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", DefaultFile, err)
+	}
+	return specs, nil
+}
+
+// validate checks one spec.
+func (s *Spec) validate() error {
+	if !namePattern.MatchString(s.Name) {
+		return fmt.Errorf("name must be lower case letters, digits and underscores, starting with a letter")
+	}
+	if strings.TrimSpace(s.Description) == "" {
+		return fmt.Errorf("description is required")
+	}
+	for name, p := range s.Parameters {
+		if !namePattern.MatchString(name) {
+			return fmt.Errorf("parameter %q: name must be lower case letters, digits and underscores", name)
+		}
+		switch p.Type {
+		case "", "string", "integer", "boolean":
+		default:
+			return fmt.Errorf("parameter %s: unknown type %q", name, p.Type)
+		}
+		if len(p.Enum) > 0 && p.Type != "" && p.Type != "string" {
+			return fmt.Errorf("parameter %s: enum needs type string", name)
+		}
+	}
+	if s.Limit < 0 || s.Limit > MaxLimit {
+		return fmt.Errorf("limit must be between 0 and %d", MaxLimit)
+	}
+
+	var templates []string
+	switch s.Kind {
+	case KindGlob, KindRegex:
+		if len(s.Paths) == 0 {
+			return fmt.Errorf("%s tools need paths", s.Kind)
+		}
+		if s.File != "" || s.Query != "" {
+			return fmt.Errorf("%s tools do not use file or query", s.Kind)
+		}
+		for _, p := range append(append([]string{}, s.Paths...), s.ExcludePaths...) {
+			if err := checkRelative(placeholderPattern.ReplaceAllString(p, "x")); err != nil {
+				return fmt.Errorf("path %q: %v", p, err)
+			}
+			if _, err := path.Match(placeholderPattern.ReplaceAllString(p, "x"), ""); err != nil {
+				return fmt.Errorf("path %q: %v", p, err)
+			}
+		}
+		templates = append(templates, s.Paths...)
+		templates = append(templates, s.ExcludePaths...)
+		if s.Kind == KindGlob {
+			if s.Pattern != "" || len(s.Fields) > 0 {
+				return fmt.Errorf("glob tools do not use pattern or fields")
+			}
+			break
+		}
+		if s.Pattern == "" {
+			return fmt.Errorf("regex tools need a pattern")
+		}
+		re, err := regexp.Compile(placeholderPattern.ReplaceAllString(s.Pattern, "x"))
+		if err != nil {
+			return fmt.Errorf("pattern: %v", err)
+		}
+		for _, field := range s.Fields {
+			if !hasGroup(re, field) {
+				return fmt.Errorf("field %q is not a capture group of the pattern", field)
+			}
+		}
+		templates = append(templates, s.Pattern)
+	case KindExtract:
+		if s.File == "" {
+			return fmt.Errorf("extract tools need a file")
+		}
+		if len(s.Paths) > 0 || len(s.ExcludePaths) > 0 || s.Pattern != "" || len(s.Fields) > 0 || s.Limit != 0 {
+			return fmt.Errorf("extract tools do not use paths, exclude_paths, pattern, fields or limit")
+		}
+		if err := checkRelative(placeholderPattern.ReplaceAllString(s.File, "x")); err != nil {
+			return fmt.Errorf("file %q: %v", s.File, err)
+		}
+		templates = append(templates, s.File, s.Query)
+	case "":
+		return fmt.Errorf("kind is required")
+	default:
+		return fmt.Errorf("unknown kind %q: want glob, regex or extract", s.Kind)
+	}
+
+	for _, t := range templates {
+		for _, m := range placeholderPattern.FindAllStringSubmatch(t, -1) {
+			if _, ok := s.Parameters[m[1]]; !ok {
+				return fmt.Errorf("placeholder {{%s}} has no parameter", m[1])
+			}
+		}
+	}
+	return nil
+}
+
+// checkRelative rejects absolute paths and paths that leave the project
+// root.
+func checkRelative(p string) error {
+	if p == "" {
+		return fmt.Errorf("empty path")
+	}
+	if path.IsAbs(p) || filepath.IsAbs(p) {
+		return fmt.Errorf("must be relative to the project root")
+	}
+	for _, part := range strings.Split(filepath.ToSlash(p), "/") {
+		if part == ".." {
+			return fmt.Errorf("must not contain ..")
+		}
+	}
+	return nil
+}
+
+// hasGroup reports whether field names a capture group of re, by name or
+// by number.
+func hasGroup(re *regexp.Regexp, field string) bool {
+	if re.SubexpIndex(field) >= 0 {
+		return true
+	}
+	var n int
+	if _, err := fmt.Sscanf(field, "%d", &n); err == nil && fmt.Sprint(n) == field {
+		return n >= 1 && n <= re.NumSubexp()
+	}
+	return false
+}
+
+// Definition returns the tool definition of s, as used by the tool
+// registry and the router.
+func (s Spec) Definition() tools.ToolDefinition {
+	params := make(map[string]tools.ParamDef, len(s.Parameters))
+	for name, p := range s.Parameters {
+		pd := tools.ParamDef{
+			Type:        paramType(p.Type),
+			Description: p.Description,
+			Required:    p.Required,
+			Default:     p.Default,
+		}
+		for _, e := range p.Enum {
+			pd.Enum = append(pd.Enum, e)
+		}
+		params[name] = pd
+	}
+	return tools.ToolDefinition{
+		Name:        s.Name,
+		Description: s.Description,
+		Parameters:  params,
+		Category:    tools.CategoryProject,
+		Priority:    70,
+		WhenToUse: tools.WhenToUse{
+			Keywords: s.Keywords,
+			UseWhen:  s.Description,
+		},
+	}
+}
+
+// ParamNames returns the parameter names of s, sorted.
+func (s Spec) ParamNames() []string {
+	names := make([]string, 0, len(s.Parameters))
+	for name := range s.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// paramType maps a declared parameter type to a tools.ParamType.
+func paramType(t string) tools.ParamType {
+	switch t {
+	case "integer":
+		return tools.ParamTypeInt
+	case "boolean":
+		return tools.ParamTypeBool
+	}
+	return tools.ParamTypeString
+}
@@ -0,0 +1,564 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools/file"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gopkg.in/yaml.v3"
+)
+
+var tracer = otel.Tracer("tools.project")
+
+// DefaultTimeout bounds one execution of a project tool.
+const DefaultTimeout = 30 * time.Second
+
+// GlobResult is the output of a glob tool.
+type GlobResult struct {
+	// Files are project-relative paths with forward slashes, sorted.
+	Files []string `json:"files"`
+
+	// Count is the number of files returned.
+	Count int `json:"count"`
+
+	// Truncated is true when more files matched than the limit.
+	Truncated bool `json:"truncated"`
+}
+
+// Match is one matching line of a regex tool.
+type Match struct {
+	// File is the project-relative path with forward slashes.
+	File string `json:"file"`
+
+	// Line is the 1-based line number.
+	Line int `json:"line"`
+
+	// Text is the line, cut to file.MaxLineLengthChars.
+	Text string `json:"text"`
+
+	// Captures maps the returned capture groups to their values.
+	Captures map[string]string `json:"captures,omitempty"`
+}
+
+// RegexResult is the output of a regex tool.
+type RegexResult struct {
+	// Matches are in file and line order.
+	Matches []Match `json:"matches"`
+
+	// Count is the number of matches returned.
+	Count int `json:"count"`
+
+	// FilesSearched is the number of files read.
+	FilesSearched int `json:"files_searched"`
+
+	// Truncated is true when the search stopped at the limit.
+	Truncated bool `json:"truncated"`
+}
+
+// ExtractResult is the output of an extract tool.
+type ExtractResult struct {
+	// File is the project-relative path with forward slashes.
+	File string `json:"file"`
+
+	// Query is the query after parameter substitution.
+	Query string `json:"query,omitempty"`
+
+	// Value is the value found.
+	Value any `json:"value"`
+}
+
+// Tool runs one Spec against a project root.
+//
+// Thread Safety: Tool is safe for concurrent use.
+type Tool struct {
+	spec   Spec
+	config *file.Config
+}
+
+// NewTool creates the tool of a validated spec.
+//
+// Inputs:
+//
+//	spec - A spec returned by Parse or Load.
+//	projectRoot - The root files are read from. Paths that resolve outside
+//	  it, including through symlinks, are skipped or rejected.
+//
+// Outputs:
+//
+//	*Tool - The tool.
+func NewTool(spec Spec, projectRoot string) *Tool {
+	return &Tool{spec: spec, config: file.NewConfig(projectRoot)}
+}
+
+// Name returns the tool name.
+func (t *Tool) Name() string {
+	return t.spec.Name
+}
+
+// Category returns tools.CategoryProject.
+func (t *Tool) Category() tools.ToolCategory {
+	return tools.CategoryProject
+}
+
+// Definition returns the tool definition.
+func (t *Tool) Definition() tools.ToolDefinition {
+	def := t.spec.Definition()
+	def.Timeout = DefaultTimeout
+	return def
+}
+
+// Schema returns the input and output schema.
+func (t *Tool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), t.output())
+}
+
+// Spec returns the spec of the tool.
+func (t *Tool) Spec() Spec {
+	return t.spec
+}
+
+// output returns the zero result of the tool's kind.
+func (t *Tool) output() any {
+	switch t.spec.Kind {
+	case KindGlob:
+		return GlobResult{}
+	case KindRegex:
+		return RegexResult{}
+	}
+	return ExtractResult{}
+}
+
+// Execute runs the tool.
+//
+// Description:
+//
+//	Substitutes the parameters into the spec, then lists, searches or
+//	extracts. Bad parameters and unreadable files produce an unsuccessful
+//	result rather than an error, as with the file tools.
+//
+// Inputs:
+//
+//	ctx - Cancellation stops the file walk.
+//	params - The tool parameters.
+//
+// Outputs:
+//
+//	*tools.Result - The result; Output is a GlobResult, RegexResult or
+//	  ExtractResult.
+//	error - Always nil.
+func (t *Tool) Execute(ctx context.Context, params tools.TypedParams) (*tools.Result, error) {
+	start := time.Now()
+	ctx, span := tracer.Start(ctx, "project.Tool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool.name", t.spec.Name),
+			attribute.String("tool.kind", string(t.spec.Kind)),
+		),
+	)
+	defer span.End()
+
+	fail := func(err error) (*tools.Result, error) {
+		telemetry.SetErrorStatus(span, err)
+		return &tools.Result{
+			Success:  false,
+			Error:    err.Error(),
+			Duration: time.Since(start),
+		}, nil
+	}
+
+	values, err := t.resolveParams(params.ToMap())
+	if err != nil {
+		return fail(err)
+	}
+
+	var (
+		output any
+		text   string
+	)
+	switch t.spec.Kind {
+	case KindGlob:
+		var r *GlobResult
+		r, err = t.runGlob(ctx, values)
+		if r != nil {
+			output, text = r, strings.Join(r.Files, "\n")
+			span.SetAttributes(attribute.Int("result.count", r.Count))
+		}
+	case KindRegex:
+		var r *RegexResult
+		r, err = t.runRegex(ctx, values)
+		if r != nil {
+			output, text = r, formatMatches(r.Matches)
+			span.SetAttributes(attribute.Int("result.count", r.Count))
+		}
+	case KindExtract:
+		var r *ExtractResult
+		r, err = t.runExtract(values)
+		if r != nil {
+			output, text = r, formatValue(r.Value)
+			span.SetAttributes(telemetry.SensitiveString("tool.file", r.File))
+		}
+	default:
+		err = fmt.Errorf("unknown kind %q", t.spec.Kind)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	jsonOutput, _ := json.Marshal(output)
+	return &tools.Result{
+		Success:    true,
+		Output:     output,
+		OutputText: text,
+		Duration:   time.Since(start),
+		TokensUsed: len(jsonOutput) / 4,
+		Truncated:  truncated(output),
+	}, nil
+}
+
+// resolveParams returns the string value of every declared parameter,
+// applying defaults and checking required parameters and enums.
+func (t *Tool) resolveParams(m map[string]any) (map[string]string, error) {
+	values := make(map[string]string, len(t.spec.Parameters))
+	for _, name := range t.spec.ParamNames() {
+		p := t.spec.Parameters[name]
+		v, ok := m[name]
+		if !ok || v == nil {
+			if p.Required {
+				return nil, fmt.Errorf("parameter %s is required", name)
+			}
+			v = p.Default
+		}
+		s := ""
+		if v != nil {
+			s = fmt.Sprint(v)
+		}
+		switch p.Type {
+		case "integer":
+			if s != "" {
+				if _, err := strconv.Atoi(s); err != nil {
+					return nil, fmt.Errorf("parameter %s must be an integer", name)
+				}
+			}
+		case "boolean":
+			if s != "" {
+				if _, err := strconv.ParseBool(s); err != nil {
+					return nil, fmt.Errorf("parameter %s must be a boolean", name)
+				}
+			}
+		}
+		if len(p.Enum) > 0 && s != "" && !slices.Contains(p.Enum, s) {
+			return nil, fmt.Errorf("parameter %s must be one of %s", name, strings.Join(p.Enum, ", "))
+		}
+		values[name] = s
+	}
+	return values, nil
+}
+
+// expand substitutes parameter values into a template; quote, when set,
+// is applied to each value.
+func expand(template string, values map[string]string, quote func(string) string) string {
+	return placeholderPattern.ReplaceAllStringFunc(template, func(m string) string {
+		v := values[placeholderPattern.FindStringSubmatch(m)[1]]
+		if quote != nil {
+			v = quote(v)
+		}
+		return v
+	})
+}
+
+// expandPaths substitutes parameter values into path templates and
+// rejects results that leave the project root.
+func expandPaths(templates []string, values map[string]string) ([]string, error) {
+	out := make([]string, 0, len(templates))
+	for _, tpl := range templates {
+		p := expand(tpl, values, nil)
+		if err := checkRelative(p); err != nil {
+			return nil, fmt.Errorf("path %q: %v", p, err)
+		}
+		out = append(out, filepath.ToSlash(p))
+	}
+	return out, nil
+}
+
+// limit returns the effective result limit.
+func (t *Tool) limit() int {
+	if t.spec.Limit > 0 {
+		return t.spec.Limit
+	}
+	return DefaultLimit
+}
+
+// walk calls visit with the project-relative slash path of every regular
+// file matching the spec's paths, in lexical order. It skips the file
+// tools' default exclusions and files that resolve outside the root.
+// visit returns false to stop.
+func (t *Tool) walk(ctx context.Context, values map[string]string, visit func(abs, rel string) bool) error {
+	includes, err := expandPaths(t.spec.Paths, values)
+	if err != nil {
+		return err
+	}
+	excludes, err := expandPaths(t.spec.ExcludePaths, values)
+	if err != nil {
+		return err
+	}
+	matcher := manifest.NewGlobMatcher(includes, excludes)
+	root := t.config.WorkingDir
+
+	stop := fmt.Errorf("stop")
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root {
+				return err
+			}
+			return nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if d.IsDir() {
+			if p != root && slices.Contains(file.DefaultExclusions, d.Name()) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, relErr := filepath.Rel(root, p)
+		if relErr != nil || !matcher.Match(filepath.ToSlash(rel)) {
+			return nil
+		}
+		if !d.Type().IsRegular() && d.Type()&fs.ModeSymlink == 0 {
+			return nil
+		}
+		if !t.config.IsPathAllowed(p) {
+			return nil
+		}
+		if !visit(p, filepath.ToSlash(rel)) {
+			return stop
+		}
+		return nil
+	})
+	if err == stop {
+		return nil
+	}
+	return err
+}
+
+// runGlob lists the matching files.
+func (t *Tool) runGlob(ctx context.Context, values map[string]string) (*GlobResult, error) {
+	limit := t.limit()
+	r := &GlobResult{Files: []string{}}
+	err := t.walk(ctx, values, func(_, rel string) bool {
+		if len(r.Files) == limit {
+			r.Truncated = true
+			return false
+		}
+		r.Files = append(r.Files, rel)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Count = len(r.Files)
+	return r, nil
+}
+
+// runRegex searches the matching files line by line.
+func (t *Tool) runRegex(ctx context.Context, values map[string]string) (*RegexResult, error) {
+	re, err := regexp.Compile(expand(t.spec.Pattern, values, regexp.QuoteMeta))
+	if err != nil {
+		return nil, fmt.Errorf("pattern: %v", err)
+	}
+	fields := t.captureFields(re)
+	limit := t.limit()
+
+	r := &RegexResult{Matches: []Match{}}
+	err = t.walk(ctx, values, func(abs, rel string) bool {
+		info, statErr := os.Stat(abs)
+		if statErr != nil || !info.Mode().IsRegular() || info.Size() > file.MaxFileSizeBytes {
+			return true
+		}
+		content, readErr := os.ReadFile(abs)
+		if readErr != nil || isBinary(content) {
+			return true
+		}
+		r.FilesSearched++
+
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(make([]byte, 64*1024), file.MaxFileSizeBytes)
+		line := 0
+		for scanner.Scan() {
+			line++
+			text := scanner.Text()
+			sub := re.FindStringSubmatch(text)
+			if sub == nil {
+				continue
+			}
+			if len(r.Matches) == limit {
+				r.Truncated = true
+				return false
+			}
+			m := Match{File: rel, Line: line, Text: text}
+			if len(m.Text) > file.MaxLineLengthChars {
+				m.Text = m.Text[:file.MaxLineLengthChars]
+			}
+			if len(fields) > 0 {
+				m.Captures = make(map[string]string, len(fields))
+				for _, f := range fields {
+					m.Captures[f.name] = sub[f.index]
+				}
+			}
+			r.Matches = append(r.Matches, m)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	r.Count = len(r.Matches)
+	return r, nil
+}
+
+// captureField is a returned capture group.
+type captureField struct {
+	name  string
+	index int
+}
+
+// captureFields returns the groups a regex tool returns: the spec's
+// fields, else every named group, else every group by number.
+func (t *Tool) captureFields(re *regexp.Regexp) []captureField {
+	var fields []captureField
+	if len(t.spec.Fields) > 0 {
+		for _, f := range t.spec.Fields {
+			if i := re.SubexpIndex(f); i >= 0 {
+				fields = append(fields, captureField{f, i})
+			} else if n, err := strconv.Atoi(f); err == nil && n >= 1 && n <= re.NumSubexp() {
+				fields = append(fields, captureField{f, n})
+			}
+		}
+		return fields
+	}
+	for i, name := range re.SubexpNames() {
+		if i > 0 && name != "" {
+			fields = append(fields, captureField{name, i})
+		}
+	}
+	if len(fields) > 0 {
+		return fields
+	}
+	for i := 1; i <= re.NumSubexp(); i++ {
+		fields = append(fields, captureField{strconv.Itoa(i), i})
+	}
+	return fields
+}
+
+// runExtract reads the spec's file and applies its query.
+func (t *Tool) runExtract(values map[string]string) (*ExtractResult, error) {
+	rels, err := expandPaths([]string{t.spec.File}, values)
+	if err != nil {
+		return nil, err
+	}
+	rel := rels[0]
+	abs := filepath.Join(t.config.WorkingDir, filepath.FromSlash(rel))
+	if !t.config.IsPathAllowed(abs) {
+		return nil, fmt.Errorf("path is outside allowed directories")
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", rel, err)
+	}
+	if info.Size() > file.MaxFileSizeBytes {
+		return nil, fmt.Errorf("file too large (%d bytes, max %d)", info.Size(), file.MaxFileSizeBytes)
+	}
+	content, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", rel, err)
+	}
+
+	var data any
+	switch strings.ToLower(filepath.Ext(rel)) {
+	case ".json":
+		err = json.Unmarshal(content, &data)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, &data)
+	default:
+		return nil, fmt.Errorf("%s: extract tools read .json, .yaml or .yml files", rel)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", rel, err)
+	}
+
+	query := expand(t.spec.Query, values, nil)
+	value, err := file.QueryJSON(data, query)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %v", err)
+	}
+	return &ExtractResult{File: rel, Query: query, Value: value}, nil
+}
+
+// isBinary reports whether content looks binary: a NUL byte in the first
+// 8000 bytes, as git decides.
+func isBinary(content []byte) bool {
+	if len(content) > 8000 {
+		content = content[:8000]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// formatMatches renders matches as file:line: text lines.
+func formatMatches(matches []Match) string {
+	var b strings.Builder
+	for _, m := range matches {
+		fmt.Fprintf(&b, "%s:%d: %s\n", m.File, m.Line, m.Text)
+	}
+	return b.String()
+}
+
+// formatValue renders an extracted value: strings as is, anything else as
+// indented JSON.
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return "null"
+	}
+	out, _ := json.MarshalIndent(v, "", "  ")
+	return string(out)
+}
+
+// truncated reports whether a result was cut at its limit.
+func truncated(output any) bool {
+	switch r := output.(type) {
+	case *GlobResult:
+		return r.Truncated
+	case *RegexResult:
+		return r.Truncated
+	}
+	return false
+}
@@ -31,6 +31,12 @@ type Registry struct {
 
 	// byCategory maps categories to lists of tools.
 	byCategory map[ToolCategory][]Tool
+
+	// schemas caches each tool's Schema(), computed once at registration.
+	schemas map[string]ToolSchema
+
+	// policy bounds tool execution (timeouts, output caps, concurrency).
+	policy ToolExecutionPolicy
 }
 
 // NewRegistry creates a new empty tool registry.
@@ -42,9 +48,37 @@ func NewRegistry() *Registry {
 	return &Registry{
 		byName:     make(map[string]Tool),
 		byCategory: make(map[ToolCategory][]Tool),
+		schemas:    make(map[string]ToolSchema),
+		policy:     DefaultToolExecutionPolicy(),
 	}
 }
 
+// SetPolicy replaces the registry's tool execution policy.
+//
+// Inputs:
+//
+//	policy - The policy applied by executors built on this registry.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (r *Registry) SetPolicy(policy ToolExecutionPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.policy = policy.Merge(ToolExecutionPolicy{})
+}
+
+// Policy returns a copy of the registry's tool execution policy.
+//
+// Outputs:
+//
+//	ToolExecutionPolicy - The current policy.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (r *Registry) Policy() ToolExecutionPolicy {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.policy.Merge(ToolExecutionPolicy{})
+}
+
 // Register adds a tool to the registry.
 //
 // Description:
@@ -68,11 +102,14 @@ func (r *Registry) Register(tool Tool) {
 		return
 	}
 
+	schema := tool.Schema()
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	name := tool.Name()
 	category := tool.Category()
+	r.schemas[name] = schema
 
 	// Check if we're replacing an existing tool
 	if existing, ok := r.byName[name]; ok {
@@ -385,10 +422,48 @@ func (r *Registry) Unregister(name string) bool {
 	}
 
 	delete(r.byName, name)
+	delete(r.schemas, name)
 	r.removeFromCategory(tool.Category(), name)
 	return true
 }
 
+// Schema returns the cached schema of a registered tool.
+//
+// Inputs:
+//
+//	name - The tool name
+//
+// Outputs:
+//
+//	ToolSchema - The tool's argument and output schemas
+//	bool - True if the tool was found
+//
+// Thread Safety: This method is safe for concurrent use.
+func (r *Registry) Schema(name string) (ToolSchema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schema, ok := r.schemas[name]
+	return schema, ok
+}
+
+// Schemas returns the schemas of all registered tools, sorted by name.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (r *Registry) Schemas() []ToolSchema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schemas := make([]ToolSchema, 0, len(r.schemas))
+	for _, schema := range r.schemas {
+		schemas = append(schemas, schema)
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Name < schemas[j].Name
+	})
+	return schemas
+}
+
 // GetDefinitions returns definitions for all registered tools.
 //
 // Outputs:
@@ -0,0 +1,516 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+// =============================================================================
+// ResultCache — Tool Results Keyed by Graph Generation
+// =============================================================================
+//
+// Deterministic graph tools are often called repeatedly with identical
+// arguments, within one agent run and across runs against the same graph.
+// ResultCache is shared by all executors of a service so those calls are
+// answered without re-running the tool.
+//
+// Design choices:
+//
+//	1. Generation in the key: every entry is keyed by the graph generation
+//	   (the graph content hash) it was computed from. A rebuilt graph has a
+//	   new generation, so stale results can never be returned; Invalidate
+//	   drops the old generation's entries wholesale to free the space.
+//
+//	2. Serialized values: results are stored as JSON so a hit can never
+//	   alias a result another run is mutating, and the same bytes can be
+//	   spilled to the store. A decoded Output is the JSON form of the
+//	   tool's output (maps and slices), not the tool's Go type.
+//
+//	3. Opt-in per tool: only tools whose ToolDefinition sets Cacheable
+//	   participate. Tools that read files, call external services, or have
+//	   side effects never do.
+//
+// Storage layout (ResultCacheStore):
+//
+//	tools/result/v1/{generation}/{digest}  →  JSON-encoded Result
+//
+// where digest is SHA-256 of the tool name and canonical JSON arguments.
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	dgbadger "github.com/dgraph-io/badger/v4"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// DefaultResultCacheCapacity is the default number of results held in memory.
+const DefaultResultCacheCapacity = 2048
+
+// resultCacheKeyPrefix versions the ResultCacheStore key layout.
+const resultCacheKeyPrefix = "tools/result/v1/"
+
+// resultCacheDefaultTTL bounds how long spilled results survive in the store.
+const resultCacheDefaultTTL = 7 * 24 * time.Hour
+
+// MetaCacheGeneration is set on Result.Metadata of results served from the
+// ResultCache. It names the graph generation the result was computed from.
+const MetaCacheGeneration = "cache_generation"
+
+// Cache tiers reported in metrics.
+const (
+	cacheTierMemory = "memory"
+	cacheTierStore  = "store"
+)
+
+// ResultCacheStore persists serialized tool results beyond the in-memory LRU.
+//
+// Thread Safety: Implementations must be safe for concurrent use.
+type ResultCacheStore interface {
+	// Load returns the value stored under key. Returns (nil, nil) on a miss.
+	Load(ctx context.Context, key string) ([]byte, error)
+
+	// Save stores value under key.
+	Save(ctx context.Context, key string, value []byte) error
+
+	// DeletePrefix removes every key starting with prefix and returns the
+	// number removed.
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+}
+
+// ResultCache caches serialized results of Cacheable tools per graph generation.
+//
+// Description:
+//
+//	An LRU of JSON-encoded results, optionally written through to a
+//	ResultCacheStore. Memory misses fall back to the store and promote
+//	the entry. Store failures are logged and treated as misses; the cache
+//	never fails a tool execution.
+//
+// Thread Safety: Safe for concurrent use.
+type ResultCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+
+	store  ResultCacheStore
+	logger *slog.Logger
+}
+
+// resultCacheItem is one LRU entry.
+type resultCacheItem struct {
+	key        string
+	generation string
+	value      []byte
+}
+
+// NewResultCache creates a result cache.
+//
+// Inputs:
+//
+//	capacity - Maximum results held in memory. <= 0 uses DefaultResultCacheCapacity.
+//	store - Optional spill store. May be nil for memory-only caching.
+//
+// Outputs:
+//
+//	*ResultCache - The cache. Never nil.
+func NewResultCache(capacity int, store ResultCacheStore) *ResultCache {
+	if capacity <= 0 {
+		capacity = DefaultResultCacheCapacity
+	}
+	return &ResultCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		store:    store,
+		logger:   slog.Default().With(slog.String("component", "tool_result_cache")),
+	}
+}
+
+// SetStore sets the spill store. Nil disables spilling.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *ResultCache) SetStore(store ResultCacheStore) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.store = store
+}
+
+// Len returns the number of results held in memory.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *ResultCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// Get returns the cached result of toolName with params for generation.
+//
+// Description:
+//
+//	Looks up memory first, then the store. Records a hit or miss metric.
+//	The returned result is freshly decoded and owned by the caller.
+//
+// Inputs:
+//
+//	ctx - Context for store access.
+//	generation - Graph generation. Empty never hits.
+//	toolName - The tool name.
+//	params - The coerced invocation parameters.
+//
+// Outputs:
+//
+//	*Result - The cached result, or nil on a miss.
+//	bool - True on a hit.
+func (c *ResultCache) Get(ctx context.Context, generation, toolName string, params map[string]any) (*Result, bool) {
+	if generation == "" {
+		return nil, false
+	}
+	key, err := resultCacheKey(generation, toolName, params)
+	if err != nil {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	var value []byte
+	if elem, ok := c.entries[key]; ok {
+		c.order.MoveToFront(elem)
+		value = elem.Value.(*resultCacheItem).value
+	}
+	store := c.store
+	c.mu.Unlock()
+
+	tier := cacheTierMemory
+	if value == nil && store != nil {
+		tier = cacheTierStore
+		value, err = store.Load(ctx, key)
+		if err != nil {
+			c.logger.Warn("tool result cache load failed",
+				slog.String("tool", toolName),
+				telemetry.SensitiveLogError("error", err))
+			value = nil
+		}
+		if value != nil {
+			c.insert(key, generation, value)
+		}
+	}
+
+	if value == nil {
+		recordCacheLookup(ctx, toolName, false, "")
+		return nil, false
+	}
+
+	var result Result
+	if err := json.Unmarshal(value, &result); err != nil {
+		c.logger.Warn("tool result cache entry undecodable",
+			slog.String("tool", toolName),
+			telemetry.SensitiveLogError("error", err))
+		recordCacheLookup(ctx, toolName, false, "")
+		return nil, false
+	}
+	recordCacheLookup(ctx, toolName, true, tier)
+	return &result, true
+}
+
+// Put caches result for toolName with params under generation.
+//
+// Description:
+//
+//	Serializes result immediately, so the caller may modify it afterwards.
+//	Unsuccessful results and an empty generation are not cached.
+//
+// Inputs:
+//
+//	ctx - Context for store access.
+//	generation - Graph generation the result was computed from.
+//	toolName - The tool name.
+//	params - The coerced invocation parameters.
+//	result - The tool result.
+func (c *ResultCache) Put(ctx context.Context, generation, toolName string, params map[string]any, result *Result) {
+	if generation == "" || result == nil || !result.Success {
+		return
+	}
+	key, err := resultCacheKey(generation, toolName, params)
+	if err != nil {
+		return
+	}
+	value, err := json.Marshal(result)
+	if err != nil {
+		c.logger.Debug("tool result not serializable, skipping cache",
+			slog.String("tool", toolName),
+			telemetry.SensitiveLogError("error", err))
+		return
+	}
+
+	store := c.insert(key, generation, value)
+	if store != nil {
+		if err := store.Save(ctx, key, value); err != nil {
+			c.logger.Warn("tool result cache save failed",
+				slog.String("tool", toolName),
+				telemetry.SensitiveLogError("error", err))
+		}
+	}
+}
+
+// insert adds or refreshes an entry and evicts beyond capacity. Returns
+// the current store so callers can write through without holding mu.
+func (c *ResultCache) insert(key, generation string, value []byte) ResultCacheStore {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*resultCacheItem).value = value
+		c.order.MoveToFront(elem)
+		return c.store
+	}
+	c.entries[key] = c.order.PushFront(&resultCacheItem{key: key, generation: generation, value: value})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resultCacheItem).key)
+	}
+	return c.store
+}
+
+// Invalidate drops every result cached for generation, in memory and in
+// the store.
+//
+// Description:
+//
+//	Called when the graph of that generation is replaced by a rebuild.
+//	Results of other generations (other projects) are kept.
+//
+// Inputs:
+//
+//	ctx - Context for store access.
+//	generation - The generation to drop. Empty is a no-op.
+//
+// Outputs:
+//
+//	int - Number of in-memory entries removed.
+//
+// Thread Safety: Safe for concurrent use.
+func (c *ResultCache) Invalidate(ctx context.Context, generation string) int {
+	if generation == "" {
+		return 0
+	}
+	c.mu.Lock()
+	removed := 0
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		item := elem.Value.(*resultCacheItem)
+		if item.generation == generation {
+			c.order.Remove(elem)
+			delete(c.entries, item.key)
+			removed++
+		}
+		elem = next
+	}
+	store := c.store
+	c.mu.Unlock()
+
+	if store != nil {
+		if _, err := store.DeletePrefix(ctx, generation+"/"); err != nil {
+			c.logger.Warn("tool result cache invalidation failed",
+				slog.String("generation", generation),
+				telemetry.SensitiveLogError("error", err))
+		}
+	}
+	if removed > 0 {
+		c.logger.Debug("tool result cache invalidated",
+			slog.String("generation", generation),
+			slog.Int("removed", removed))
+	}
+	return removed
+}
+
+// resultCacheKey returns "{generation}/{digest}" where digest is SHA-256 of
+// the tool name and the canonical JSON of params.
+//
+// Description:
+//
+//	encoding/json writes map keys in sorted order at every level, so
+//	equal parameter maps always produce the same digest regardless of
+//	map iteration order.
+func resultCacheKey(generation, toolName string, params map[string]any) (string, error) {
+	canonical, err := json.Marshal(params)
+	if err != nil {
+		return "", fmt.Errorf("canonicalize params: %w", err)
+	}
+	h := sha256.New()
+	h.Write([]byte(toolName))
+	h.Write([]byte{0})
+	h.Write(canonical)
+	return generation + "/" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// =============================================================================
+// BadgerResultCacheStore
+// =============================================================================
+
+// BadgerResultCacheStore implements ResultCacheStore backed by BadgerDB.
+//
+// Description:
+//
+//	Entries expire through BadgerDB's native TTL. The DB is owned by the
+//	caller and must stay open while the store is in use.
+//
+// Thread Safety: Safe for concurrent use.
+type BadgerResultCacheStore struct {
+	db  *badgerstore.DB
+	ttl time.Duration
+}
+
+// NewBadgerResultCacheStore creates a store backed by db.
+//
+// Inputs:
+//
+//	db - Opened BadgerDB wrapper. Must not be nil.
+//	ttl - Lifetime of each entry. <= 0 uses the default (7 days).
+//
+// Outputs:
+//
+//	*BadgerResultCacheStore - The store. Never nil.
+func NewBadgerResultCacheStore(db *badgerstore.DB, ttl time.Duration) *BadgerResultCacheStore {
+	if db == nil {
+		panic("NewBadgerResultCacheStore: db must not be nil")
+	}
+	if ttl <= 0 {
+		ttl = resultCacheDefaultTTL
+	}
+	return &BadgerResultCacheStore{db: db, ttl: ttl}
+}
+
+// Load implements ResultCacheStore.
+func (s *BadgerResultCacheStore) Load(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		item, err := txn.Get([]byte(resultCacheKeyPrefix + key))
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	if errors.Is(err, dgbadger.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("tool result cache load: %w", err)
+	}
+	return value, nil
+}
+
+// Save implements ResultCacheStore.
+func (s *BadgerResultCacheStore) Save(ctx context.Context, key string, value []byte) error {
+	err := s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.SetEntry(dgbadger.NewEntry([]byte(resultCacheKeyPrefix+key), value).WithTTL(s.ttl))
+	})
+	if err != nil {
+		return fmt.Errorf("tool result cache save: %w", err)
+	}
+	return nil
+}
+
+// DeletePrefix implements ResultCacheStore.
+func (s *BadgerResultCacheStore) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	full := []byte(resultCacheKeyPrefix + prefix)
+	var keys [][]byte
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		opts := dgbadger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		opts.Prefix = full
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.ValidForPrefix(full); it.Next() {
+			keys = append(keys, it.Item().KeyCopy(nil))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("tool result cache scan: %w", err)
+	}
+
+	const batch = 1000
+	for start := 0; start < len(keys); start += batch {
+		end := min(start+batch, len(keys))
+		err := s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+			for _, k := range keys[start:end] {
+				if err := txn.Delete(k); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return start, fmt.Errorf("tool result cache delete: %w", err)
+		}
+	}
+	return len(keys), nil
+}
+
+// =============================================================================
+// Metrics
+// =============================================================================
+
+var (
+	resultCacheMeter = otel.Meter("aleutian.tools.result_cache")
+
+	resultCacheHits   metric.Int64Counter
+	resultCacheMisses metric.Int64Counter
+
+	resultCacheMetricsOnce sync.Once
+	resultCacheMetricsErr  error
+)
+
+// initResultCacheMetrics initializes hit/miss counters.
+func initResultCacheMetrics() error {
+	resultCacheMetricsOnce.Do(func() {
+		resultCacheHits, resultCacheMetricsErr = resultCacheMeter.Int64Counter(
+			"trace_tool_cache_hits_total",
+			metric.WithDescription("Total tool executions answered from the result cache"),
+		)
+		if resultCacheMetricsErr != nil {
+			return
+		}
+		resultCacheMisses, resultCacheMetricsErr = resultCacheMeter.Int64Counter(
+			"trace_tool_cache_misses_total",
+			metric.WithDescription("Total cacheable tool executions not found in the result cache"),
+		)
+	})
+	return resultCacheMetricsErr
+}
+
+// recordCacheLookup records a hit (with the tier that answered) or a miss.
+func recordCacheLookup(ctx context.Context, toolName string, hit bool, tier string) {
+	if err := initResultCacheMetrics(); err != nil {
+		return
+	}
+	if hit {
+		resultCacheHits.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("tool", toolName),
+			attribute.String("tier", tier),
+		))
+		return
+	}
+	resultCacheMisses.Add(ctx, 1, metric.WithAttributes(attribute.String("tool", toolName)))
+}
@@ -0,0 +1,285 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+)
+
+// countingTool counts executions and echoes the "symbol" parameter.
+type countingTool struct {
+	def   ToolDefinition
+	calls atomic.Int32
+}
+
+func (t *countingTool) Name() string               { return t.def.Name }
+func (t *countingTool) Category() ToolCategory     { return CategoryExploration }
+func (t *countingTool) Definition() ToolDefinition { return t.def }
+func (t *countingTool) Schema() ToolSchema         { return NewToolSchema(t.def, nil) }
+func (t *countingTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
+	t.calls.Add(1)
+	symbol, _ := params.(MapParams).Params["symbol"].(string)
+	return &Result{
+		Success:    true,
+		Output:     map[string]any{"symbol": symbol},
+		OutputText: "callers of " + symbol,
+		TokensUsed: 3,
+	}, nil
+}
+
+func newCountingTool(cacheable bool) *countingTool {
+	return &countingTool{def: ToolDefinition{
+		Name:      "find_callers",
+		Cacheable: cacheable,
+		Parameters: map[string]ParamDef{
+			"symbol": {Type: ParamTypeString},
+			"limit":  {Type: ParamTypeInt},
+		},
+	}}
+}
+
+func newCacheTestExecutor(tool Tool, cache *ResultCache, generation string, opts ...ExecutorOption) *Executor {
+	registry := NewRegistry()
+	registry.Register(tool)
+	execOpts := DefaultExecutorOptions()
+	execOpts.EnableCaching = false
+	opts = append([]ExecutorOption{WithResultCache(cache, generation)}, opts...)
+	return NewExecutorWithOptions(registry, &execOpts, opts...)
+}
+
+func TestResultCacheKey_Canonical(t *testing.T) {
+	a, err := resultCacheKey("gen", "find_callers", map[string]any{"symbol": "Foo", "limit": 10, "opts": map[string]any{"x": 1, "y": 2}})
+	if err != nil {
+		t.Fatalf("key: %v", err)
+	}
+	b, _ := resultCacheKey("gen", "find_callers", map[string]any{"opts": map[string]any{"y": 2, "x": 1}, "limit": 10, "symbol": "Foo"})
+	if a != b {
+		t.Errorf("keys differ for equal params: %s vs %s", a, b)
+	}
+	c, _ := resultCacheKey("gen2", "find_callers", map[string]any{"symbol": "Foo", "limit": 10, "opts": map[string]any{"x": 1, "y": 2}})
+	if a == c {
+		t.Error("keys equal across generations")
+	}
+	d, _ := resultCacheKey("gen", "find_callees", map[string]any{"symbol": "Foo", "limit": 10, "opts": map[string]any{"x": 1, "y": 2}})
+	if a == d {
+		t.Error("keys equal across tools")
+	}
+}
+
+func TestResultCache_LRUEviction(t *testing.T) {
+	ctx := context.Background()
+	cache := NewResultCache(2, nil)
+	for _, sym := range []string{"A", "B"} {
+		cache.Put(ctx, "gen", "t", map[string]any{"s": sym}, &Result{Success: true, OutputText: sym})
+	}
+	// Touch A so B is least recently used.
+	if _, ok := cache.Get(ctx, "gen", "t", map[string]any{"s": "A"}); !ok {
+		t.Fatal("expected hit for A")
+	}
+	cache.Put(ctx, "gen", "t", map[string]any{"s": "C"}, &Result{Success: true, OutputText: "C"})
+
+	if cache.Len() != 2 {
+		t.Errorf("Len = %d, want 2", cache.Len())
+	}
+	if _, ok := cache.Get(ctx, "gen", "t", map[string]any{"s": "B"}); ok {
+		t.Error("B should have been evicted")
+	}
+	if r, ok := cache.Get(ctx, "gen", "t", map[string]any{"s": "A"}); !ok || r.OutputText != "A" {
+		t.Errorf("A: got %v, %v", r, ok)
+	}
+}
+
+func TestResultCache_SkipsFailuresAndEmptyGeneration(t *testing.T) {
+	ctx := context.Background()
+	cache := NewResultCache(0, nil)
+	cache.Put(ctx, "gen", "t", nil, &Result{Success: false, Error: "boom"})
+	cache.Put(ctx, "", "t", nil, &Result{Success: true})
+	if cache.Len() != 0 {
+		t.Errorf("Len = %d, want 0", cache.Len())
+	}
+}
+
+func TestResultCache_InvalidateGeneration(t *testing.T) {
+	ctx := context.Background()
+	cache := NewResultCache(0, nil)
+	cache.Put(ctx, "old", "t", nil, &Result{Success: true, OutputText: "old"})
+	cache.Put(ctx, "other", "t", nil, &Result{Success: true, OutputText: "other"})
+
+	if n := cache.Invalidate(ctx, "old"); n != 1 {
+		t.Errorf("Invalidate removed %d, want 1", n)
+	}
+	if _, ok := cache.Get(ctx, "old", "t", nil); ok {
+		t.Error("old generation still cached")
+	}
+	if _, ok := cache.Get(ctx, "other", "t", nil); !ok {
+		t.Error("other generation was dropped")
+	}
+}
+
+func TestResultCache_BadgerSpill(t *testing.T) {
+	db, err := badgerstore.OpenDB(badgerstore.InMemoryConfig())
+	if err != nil {
+		t.Fatalf("open badger: %v", err)
+	}
+	defer db.Close()
+	store := NewBadgerResultCacheStore(db, 0)
+	ctx := context.Background()
+
+	first := NewResultCache(0, store)
+	first.Put(ctx, "gen", "t", map[string]any{"s": "A"}, &Result{Success: true, OutputText: "A"})
+
+	// A fresh cache (e.g. after a restart) is answered from the store.
+	second := NewResultCache(0, store)
+	r, ok := second.Get(ctx, "gen", "t", map[string]any{"s": "A"})
+	if !ok || r.OutputText != "A" {
+		t.Fatalf("spill lookup: got %v, %v", r, ok)
+	}
+	if second.Len() != 1 {
+		t.Errorf("store hit not promoted to memory, Len = %d", second.Len())
+	}
+
+	second.Invalidate(ctx, "gen")
+	if _, ok := NewResultCache(0, store).Get(ctx, "gen", "t", map[string]any{"s": "A"}); ok {
+		t.Error("store entry survived invalidation")
+	}
+}
+
+func TestExecutor_SharedCacheHit(t *testing.T) {
+	ctx := context.Background()
+	cache := NewResultCache(0, nil)
+	tool := newCountingTool(true)
+
+	// Two executors (two runs) against the same graph generation.
+	for run := 0; run < 2; run++ {
+		executor := newCacheTestExecutor(tool, cache, "gen1")
+		for i := 0; i < 2; i++ {
+			result, err := executor.Execute(ctx, &Invocation{
+				ToolName:   "find_callers",
+				Parameters: map[string]any{"symbol": "Foo", "limit": "5"},
+			})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			wantCached := run > 0 || i > 0
+			if result.Cached != wantCached {
+				t.Errorf("run %d call %d: Cached = %v, want %v", run, i, result.Cached, wantCached)
+			}
+			if wantCached && result.Metadata[MetaCacheGeneration] != "gen1" {
+				t.Errorf("cache generation = %v, want gen1", result.Metadata[MetaCacheGeneration])
+			}
+			if result.OutputText != "callers of Foo" {
+				t.Errorf("OutputText = %q", result.OutputText)
+			}
+		}
+	}
+	if got := tool.calls.Load(); got != 1 {
+		t.Errorf("tool ran %d times, want 1", got)
+	}
+
+	// A rebuilt graph has a new generation and misses.
+	executor := newCacheTestExecutor(tool, cache, "gen2")
+	result, err := executor.Execute(ctx, &Invocation{
+		ToolName:   "find_callers",
+		Parameters: map[string]any{"symbol": "Foo", "limit": 5},
+	})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Cached || tool.calls.Load() != 2 {
+		t.Errorf("new generation: Cached = %v, calls = %d", result.Cached, tool.calls.Load())
+	}
+}
+
+func TestExecutor_SharedCacheStableIDs(t *testing.T) {
+	cache := NewResultCache(0, nil)
+	tool := newCountingTool(true)
+	resolve := func(id string) (string, bool) {
+		switch id {
+		case "svc.go:10:Foo", "svc.go::Foo@1234abcd":
+			return "svc.go::Foo@1234abcd", true
+		}
+		return "", false
+	}
+	executor := newCacheTestExecutor(tool, cache, "gen1", WithStableIDResolver(resolve))
+
+	for i, symbol := range []string{"svc.go:10:Foo", "svc.go::Foo@1234abcd"} {
+		result, err := executor.Execute(context.Background(), &Invocation{
+			ToolName:   "find_callers",
+			Parameters: map[string]any{"symbol": symbol},
+		})
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if result.Cached != (i > 0) {
+			t.Errorf("call %d: Cached = %v", i, result.Cached)
+		}
+		// The tool ran with the ID it was called with, not the key form.
+		if result.OutputText != "callers of svc.go:10:Foo" {
+			t.Errorf("OutputText = %q", result.OutputText)
+		}
+	}
+	if got := tool.calls.Load(); got != 1 {
+		t.Errorf("tool ran %d times, want 1 for both ID forms", got)
+	}
+}
+
+func TestExecutor_SharedCacheSkipsNonCacheable(t *testing.T) {
+	cache := NewResultCache(0, nil)
+	tool := newCountingTool(false)
+	executor := newCacheTestExecutor(tool, cache, "gen1")
+
+	for i := 0; i < 2; i++ {
+		if _, err := executor.Execute(context.Background(), &Invocation{
+			ToolName:   "find_callers",
+			Parameters: map[string]any{"symbol": "Foo"},
+		}); err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	}
+	if got := tool.calls.Load(); got != 2 {
+		t.Errorf("tool ran %d times, want 2", got)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("non-cacheable result was cached")
+	}
+}
+
+func TestExecutor_CacheBypass(t *testing.T) {
+	cache := NewResultCache(0, nil)
+	tool := newCountingTool(true)
+	registry := NewRegistry()
+	registry.Register(tool)
+	// The executor's own cache is enabled too; bypass must skip both.
+	executor := NewExecutorWithOptions(registry, nil, WithResultCache(cache, "gen1"), WithCacheBypass(true))
+
+	for i := 0; i < 2; i++ {
+		result, err := executor.Execute(context.Background(), &Invocation{
+			ToolName:   "find_callers",
+			Parameters: map[string]any{"symbol": "Foo"},
+		})
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+		if result.Cached {
+			t.Errorf("call %d served from cache despite bypass", i)
+		}
+	}
+	if got := tool.calls.Load(); got != 2 {
+		t.Errorf("tool ran %d times, want 2", got)
+	}
+	if cache.Len() != 0 {
+		t.Errorf("bypassed executor wrote to the shared cache")
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// rootParamDef is the shared definition of the optional "root" parameter.
+//
+// Description:
+//
+//	In a multi-root workspace graph every file path starts with the name of
+//	its root (e.g. "shared-lib/pkg/util.go"). The root parameter restricts
+//	results to one of those roots. On a single-root graph it acts as a
+//	top-level directory filter.
+var rootParamDef = ParamDef{
+	Type:        ParamTypeString,
+	Description: "Restrict results to one root of a multi-root workspace, by root name (optional)",
+	Required:    false,
+}
+
+// filterSymbolsByRoot keeps the symbols whose file lies in root.
+//
+// Inputs:
+//   - symbols: Symbols to filter. Nil entries are dropped.
+//   - root: Workspace root name. Empty keeps every non-nil symbol.
+//
+// Outputs:
+//   - []*ast.Symbol: The symbols in root, in input order.
+//
+// Thread Safety: This function is safe for concurrent use (stateless).
+func filterSymbolsByRoot(symbols []*ast.Symbol, root string) []*ast.Symbol {
+	filtered := make([]*ast.Symbol, 0, len(symbols))
+	for _, sym := range symbols {
+		if sym != nil && graph.FilePathInRoot(sym.FilePath, root) {
+			filtered = append(filtered, sym)
+		}
+	}
+	return filtered
+}
+
+// filterQueryResultsByRoot drops result symbols outside root, in place.
+//
+// Inputs:
+//   - results: Query results keyed by queried symbol ID. Nil entries are skipped.
+//   - root: Workspace root name. Empty is a no-op.
+//
+// Thread Safety: Not safe for concurrent use on the same results.
+func filterQueryResultsByRoot(results map[string]*graph.QueryResult, root string) {
+	if root == "" {
+		return
+	}
+	for _, qr := range results {
+		if qr != nil {
+			qr.Symbols = filterSymbolsByRoot(qr.Symbols, root)
+		}
+	}
+}
+
+// filterInheritanceResultsByRoot drops direct and inherited callers outside
+// root, in place.
+//
+// Inputs:
+//   - results: Inheritance-aware results keyed by queried symbol ID.
+//   - root: Workspace root name. Empty is a no-op.
+//
+// Thread Safety: Not safe for concurrent use on the same results.
+func filterInheritanceResultsByRoot(results map[string]*graph.InheritanceQueryResult, root string) {
+	if root == "" {
+		return
+	}
+	for _, iqr := range results {
+		if iqr == nil {
+			continue
+		}
+		if iqr.DirectCallers != nil {
+			iqr.DirectCallers.Symbols = filterSymbolsByRoot(iqr.DirectCallers.Symbols, root)
+		}
+		filterQueryResultsByRoot(iqr.InheritedCallers, root)
+	}
+}
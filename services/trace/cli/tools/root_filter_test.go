@@ -0,0 +1,90 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+func TestFilterSymbolsByRoot(t *testing.T) {
+	symbols := []*ast.Symbol{
+		{ID: "a", FilePath: "service-a/main.go"},
+		nil,
+		{ID: "b", FilePath: "shared-lib/util.go"},
+		{ID: "c", FilePath: "service-ab/main.go"},
+	}
+
+	got := filterSymbolsByRoot(symbols, "service-a")
+	if len(got) != 1 || got[0].ID != "a" {
+		t.Errorf("filterSymbolsByRoot(service-a) = %v, want only a", got)
+	}
+
+	if got := filterSymbolsByRoot(symbols, ""); len(got) != 3 {
+		t.Errorf("filterSymbolsByRoot(\"\") kept %d symbols, want 3", len(got))
+	}
+}
+
+func TestFilterInheritanceResultsByRoot(t *testing.T) {
+	results := map[string]*graph.InheritanceQueryResult{
+		"target": {
+			DirectCallers: &graph.QueryResult{Symbols: []*ast.Symbol{
+				{ID: "a", FilePath: "service-a/main.go"},
+				{ID: "b", FilePath: "service-b/main.go"},
+			}},
+			InheritedCallers: map[string]*graph.QueryResult{
+				"parent": {Symbols: []*ast.Symbol{{ID: "c", FilePath: "service-b/other.go"}}},
+			},
+		},
+		"missing": nil,
+	}
+
+	filterInheritanceResultsByRoot(results, "service-a")
+
+	if got := results["target"].DirectCallers.Symbols; len(got) != 1 || got[0].ID != "a" {
+		t.Errorf("direct callers = %v, want only a", got)
+	}
+	if got := results["target"].InheritedCallers["parent"].Symbols; len(got) != 0 {
+		t.Errorf("inherited callers = %v, want none", got)
+	}
+}
+
+func TestFindSymbolTool_RootFilter(t *testing.T) {
+	ctx := context.Background()
+	g := graph.NewGraph("/ws")
+	idx := index.NewSymbolIndex()
+
+	for _, sym := range []*ast.Symbol{
+		{ID: "service-a/config.go:3:Load", Name: "Load", Kind: ast.SymbolKindFunction, FilePath: "service-a/config.go", StartLine: 3, EndLine: 9, Package: "config", Language: "go"},
+		{ID: "shared-lib/config.go:3:Load", Name: "Load", Kind: ast.SymbolKindFunction, FilePath: "shared-lib/config.go", StartLine: 3, EndLine: 9, Package: "config", Language: "go"},
+	} {
+		g.AddNode(sym)
+		_ = idx.Add(sym)
+	}
+	g.Freeze()
+
+	tool := NewFindSymbolTool(g, idx)
+	result, err := tool.Execute(ctx, FindSymbolParams{Name: "Load", Kind: "all", Root: "shared-lib"})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Execute() failed: %s", result.Error)
+	}
+	output := result.Output.(FindSymbolOutput)
+	if output.MatchCount != 1 || output.Symbols[0].File != "shared-lib/config.go" {
+		t.Errorf("root=shared-lib: got %+v, want only shared-lib/config.go", output.Symbols)
+	}
+}
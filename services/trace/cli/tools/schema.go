@@ -0,0 +1,499 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JSONSchema is the subset of JSON Schema used to describe tool arguments
+// and results.
+//
+// Description:
+//
+//	Serializes to a plain JSON Schema object that OpenAI "parameters",
+//	Anthropic "input_schema" and external orchestrators accept as is.
+//	An empty JSONSchema ({}) accepts any value.
+//
+// NOTE: Enum and Default use `any` because their values take the type of
+// the schema they belong to, as in ParamDef.
+//
+// Thread Safety: Immutable after construction; safe for concurrent reads.
+type JSONSchema struct {
+	// Type is the JSON type: object, array, string, integer, number, boolean.
+	Type string `json:"type,omitempty"`
+
+	// Description explains the value.
+	Description string `json:"description,omitempty"`
+
+	// Format refines a string type (e.g., "date-time").
+	Format string `json:"format,omitempty"`
+
+	// Properties describes the fields of an object.
+	Properties map[string]*JSONSchema `json:"properties,omitempty"`
+
+	// Required lists the object fields that must be present, sorted.
+	Required []string `json:"required,omitempty"`
+
+	// AdditionalProperties describes the values of a map-like object.
+	AdditionalProperties *JSONSchema `json:"additionalProperties,omitempty"`
+
+	// Items describes the elements of an array.
+	Items *JSONSchema `json:"items,omitempty"`
+
+	// Enum restricts the value to a set of options.
+	Enum []any `json:"enum,omitempty"`
+
+	// Default is the value used when the argument is omitted.
+	Default any `json:"default,omitempty"`
+
+	// MinLength and MaxLength bound string length.
+	MinLength *int `json:"minLength,omitempty"`
+	MaxLength *int `json:"maxLength,omitempty"`
+
+	// Minimum and Maximum bound numeric values.
+	Minimum *float64 `json:"minimum,omitempty"`
+	Maximum *float64 `json:"maximum,omitempty"`
+
+	// AnyOf lists alternative schemas, for outputs with more than one shape.
+	AnyOf []*JSONSchema `json:"anyOf,omitempty"`
+}
+
+// MetaInvalidParameter is the Result.Metadata key naming the argument that
+// failed schema validation.
+const MetaInvalidParameter = "invalid_parameter"
+
+// ToolSchema holds the JSON Schemas of a tool's arguments and result.
+type ToolSchema struct {
+	// Name is the tool name.
+	Name string `json:"name"`
+
+	// Input describes the arguments object passed to Execute.
+	Input *JSONSchema `json:"input"`
+
+	// Output describes Result.Output on success.
+	Output *JSONSchema `json:"output"`
+}
+
+// NewToolSchema builds a tool's schema from its definition and output type.
+//
+// Description:
+//
+//	Input is derived from the definition's parameters. Output is derived
+//	by reflection from output, a zero value of the type the tool puts in
+//	Result.Output, following its json tags: fields tagged omitempty are
+//	optional, the rest required. A nil output yields a schema that
+//	accepts any value, for tools whose output is free-form.
+//
+// Inputs:
+//
+//	def - The tool definition.
+//	output - A zero value of the output type, or nil.
+//
+// Outputs:
+//
+//	ToolSchema - The schema.
+//
+// Thread Safety: This function is safe for concurrent use.
+func NewToolSchema(def ToolDefinition, output any) ToolSchema {
+	schema := ToolSchema{Name: def.Name, Input: def.InputSchema()}
+	if output == nil {
+		schema.Output = &JSONSchema{Description: "Free-form result; see output_text."}
+	} else {
+		schema.Output = SchemaOf(output)
+	}
+	return schema
+}
+
+// SchemaOf derives the JSON Schema of value's type as encoding/json would
+// serialize it: json tag names, omitempty fields optional, untagged
+// embedded structs flattened, time.Time as a date-time string.
+//
+// Thread Safety: This function is safe for concurrent use.
+func SchemaOf(value any) *JSONSchema {
+	if value == nil {
+		return &JSONSchema{}
+	}
+	return schemaForType(reflect.TypeOf(value), make(map[reflect.Type]bool))
+}
+
+// InputSchema returns the JSON Schema of the tool's arguments object.
+func (d *ToolDefinition) InputSchema() *JSONSchema {
+	return objectSchema("", d.Parameters)
+}
+
+// Schema returns the JSON Schema of a single parameter.
+func (p ParamDef) Schema() *JSONSchema {
+	s := &JSONSchema{
+		Type:        string(p.Type),
+		Description: p.Description,
+		Enum:        p.Enum,
+		Default:     p.Default,
+		Minimum:     p.Minimum,
+		Maximum:     p.Maximum,
+	}
+	if p.MinLength > 0 {
+		s.MinLength = &p.MinLength
+	}
+	if p.MaxLength > 0 {
+		s.MaxLength = &p.MaxLength
+	}
+	if p.Items != nil {
+		s.Items = p.Items.Schema()
+	}
+	if p.Type == ParamTypeObject && len(p.Properties) > 0 {
+		nested := objectSchema(p.Description, p.Properties)
+		s.Properties, s.Required = nested.Properties, nested.Required
+	}
+	return s
+}
+
+// objectSchema builds an object schema from named parameters.
+func objectSchema(description string, params map[string]ParamDef) *JSONSchema {
+	s := &JSONSchema{
+		Type:        string(ParamTypeObject),
+		Description: description,
+		Properties:  make(map[string]*JSONSchema, len(params)),
+	}
+	for name, param := range params {
+		s.Properties[name] = param.Schema()
+		if param.Required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// ValidateArgs checks a tool's arguments against its input schema.
+//
+// Description:
+//
+//	Checks that required arguments are present, then validates each
+//	provided argument, descending into array items and object properties.
+//	Arguments the schema does not declare are ignored. Integers may arrive
+//	as int, int64 or float64 (the JSON decoding of every number). A
+//	non-array value for an array argument is left to the tool, which may
+//	accept a comma-separated string.
+//
+// Inputs:
+//
+//	args - The arguments map.
+//
+// Outputs:
+//
+//	error - A *ValidationError naming the offending argument (e.g.
+//	  "files[2]" or "options.depth"), or nil.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *JSONSchema) ValidateArgs(args map[string]any) error {
+	if s == nil {
+		return nil
+	}
+	if err := s.validateObject("", args); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateObject validates the fields of an object value.
+func (s *JSONSchema) validateObject(path string, obj map[string]any) *ValidationError {
+	for _, name := range s.Required {
+		if _, ok := obj[name]; !ok {
+			return &ValidationError{Parameter: joinPath(path, name), Message: "required parameter missing"}
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop, ok := s.Properties[name]
+		if !ok {
+			continue
+		}
+		required := false
+		for _, r := range s.Required {
+			if r == name {
+				required = true
+				break
+			}
+		}
+		if err := prop.validate(joinPath(path, name), obj[name], required); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate validates a single value against s.
+func (s *JSONSchema) validate(path string, value any, required bool) *ValidationError {
+	if value == nil {
+		if required {
+			return &ValidationError{Parameter: path, Message: "required parameter is nil"}
+		}
+		return nil
+	}
+
+	switch ParamType(s.Type) {
+	case ParamTypeString:
+		str, ok := value.(string)
+		if !ok {
+			return &ValidationError{Parameter: path, Message: "expected string", Actual: fmt.Sprintf("%T", value)}
+		}
+		if s.MinLength != nil && len(str) < *s.MinLength {
+			return &ValidationError{Parameter: path, Message: fmt.Sprintf("string length must be at least %d", *s.MinLength)}
+		}
+		if s.MaxLength != nil && len(str) > *s.MaxLength {
+			return &ValidationError{Parameter: path, Message: fmt.Sprintf("string length must be at most %d", *s.MaxLength)}
+		}
+
+	case ParamTypeInt:
+		var num float64
+		switch v := value.(type) {
+		case int:
+			num = float64(v)
+		case int64:
+			num = float64(v)
+		case float64:
+			num = v
+		default:
+			return &ValidationError{Parameter: path, Message: "expected integer", Actual: fmt.Sprintf("%T", value)}
+		}
+		if err := s.checkRange(path, num); err != nil {
+			return err
+		}
+
+	case ParamTypeFloat:
+		num, ok := value.(float64)
+		if !ok {
+			return &ValidationError{Parameter: path, Message: "expected number", Actual: fmt.Sprintf("%T", value)}
+		}
+		if err := s.checkRange(path, num); err != nil {
+			return err
+		}
+
+	case ParamTypeBool:
+		if _, ok := value.(bool); !ok {
+			return &ValidationError{Parameter: path, Message: "expected boolean", Actual: fmt.Sprintf("%T", value)}
+		}
+
+	case ParamTypeArray:
+		items, ok := value.([]any)
+		if !ok {
+			return nil
+		}
+		if s.Items != nil {
+			for i, item := range items {
+				if err := s.Items.validate(path+"["+strconv.Itoa(i)+"]", item, true); err != nil {
+					return err
+				}
+			}
+		}
+
+	case ParamTypeObject:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return &ValidationError{Parameter: path, Message: "expected object", Actual: fmt.Sprintf("%T", value)}
+		}
+		if err := s.validateObject(path, obj); err != nil {
+			return err
+		}
+	}
+
+	if len(s.Enum) > 0 && reflect.TypeOf(value).Comparable() {
+		for _, allowed := range s.Enum {
+			if value == allowed {
+				return nil
+			}
+		}
+		return &ValidationError{
+			Parameter: path,
+			Message:   "value not in allowed enum",
+			Expected:  fmt.Sprintf("%v", s.Enum),
+			Actual:    fmt.Sprintf("%v", value),
+		}
+	}
+	return nil
+}
+
+// checkRange enforces Minimum and Maximum.
+func (s *JSONSchema) checkRange(path string, num float64) *ValidationError {
+	if s.Minimum != nil && num < *s.Minimum {
+		return &ValidationError{Parameter: path, Message: fmt.Sprintf("value must be at least %v", *s.Minimum)}
+	}
+	if s.Maximum != nil && num > *s.Maximum {
+		return &ValidationError{Parameter: path, Message: fmt.Sprintf("value must be at most %v", *s.Maximum)}
+	}
+	return nil
+}
+
+// joinPath appends a field name to a dotted argument path.
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	durationType      = reflect.TypeOf(time.Duration(0))
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// schemaForType derives a schema from a Go type the way encoding/json
+// would serialize it. seen guards against recursive types, which are
+// described as a plain object at the point of recursion.
+func schemaForType(t reflect.Type, seen map[reflect.Type]bool) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &JSONSchema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return &JSONSchema{Type: "integer", Description: "Duration in nanoseconds."}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return &JSONSchema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return &JSONSchema{Type: "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string", Format: "byte"}
+		}
+		return &JSONSchema{Type: "array", Items: schemaForType(t.Elem(), seen)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: schemaForType(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return &JSONSchema{Type: "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		s := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+		addStructFields(s, t, seen)
+		sort.Strings(s.Required)
+		return s
+	default:
+		// Interfaces, funcs and channels: any value.
+		return &JSONSchema{}
+	}
+}
+
+// addStructFields adds the json-visible fields of t to s, flattening
+// untagged embedded structs as encoding/json does.
+func addStructFields(s *JSONSchema, t reflect.Type, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addStructFields(s, ft, seen)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		s.Properties[name] = schemaForType(field.Type, seen)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// OpenAITool is a tool in the OpenAI chat completions "tools" format.
+type OpenAITool struct {
+	Type     string         `json:"type"`
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFunction is the function part of an OpenAITool.
+type OpenAIFunction struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Parameters  *JSONSchema `json:"parameters"`
+}
+
+// AnthropicTool is a tool in the Anthropic messages "tools" format.
+type AnthropicTool struct {
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	InputSchema *JSONSchema `json:"input_schema"`
+}
+
+// ToOpenAITools converts tool definitions to the OpenAI tools format.
+//
+// Thread Safety: This function is safe for concurrent use.
+func ToOpenAITools(defs []ToolDefinition) []OpenAITool {
+	out := make([]OpenAITool, 0, len(defs))
+	for i := range defs {
+		out = append(out, OpenAITool{
+			Type: "function",
+			Function: OpenAIFunction{
+				Name:        defs[i].Name,
+				Description: defs[i].Description,
+				Parameters:  defs[i].InputSchema(),
+			},
+		})
+	}
+	return out
+}
+
+// ToAnthropicTools converts tool definitions to the Anthropic tools format.
+//
+// Thread Safety: This function is safe for concurrent use.
+func ToAnthropicTools(defs []ToolDefinition) []AnthropicTool {
+	out := make([]AnthropicTool, 0, len(defs))
+	for i := range defs {
+		out = append(out, AnthropicTool{
+			Name:        defs[i].Name,
+			Description: defs[i].Description,
+			InputSchema: defs[i].InputSchema(),
+		})
+	}
+	return out
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+func schemaTestDefinition() ToolDefinition {
+	minDepth, maxDepth := 1.0, 5.0
+	return ToolDefinition{
+		Name:        "schema_tool",
+		Description: "Exercises the schema",
+		Parameters: map[string]ParamDef{
+			"name":   {Type: ParamTypeString, Description: "Symbol name", Required: true, MinLength: 1},
+			"format": {Type: ParamTypeString, Enum: []any{"text", "json"}, Default: "text"},
+			"files":  {Type: ParamTypeArray, Items: &ParamDef{Type: ParamTypeString}},
+			"options": {
+				Type: ParamTypeObject,
+				Properties: map[string]ParamDef{
+					"depth":   {Type: ParamTypeInt, Required: true, Minimum: &minDepth, Maximum: &maxDepth},
+					"verbose": {Type: ParamTypeBool},
+				},
+			},
+		},
+	}
+}
+
+func TestToolDefinition_InputSchema(t *testing.T) {
+	def := schemaTestDefinition()
+	s := def.InputSchema()
+
+	if s.Type != "object" || len(s.Properties) != 4 {
+		t.Fatalf("schema = %+v", s)
+	}
+	if len(s.Required) != 1 || s.Required[0] != "name" {
+		t.Errorf("Required = %v", s.Required)
+	}
+	if got := s.Properties["name"].MinLength; got == nil || *got != 1 {
+		t.Errorf("name.minLength = %v", got)
+	}
+	if s.Properties["files"].Items.Type != "string" {
+		t.Errorf("files.items = %+v", s.Properties["files"].Items)
+	}
+	options := s.Properties["options"]
+	if len(options.Required) != 1 || options.Properties["depth"].Maximum == nil {
+		t.Errorf("options = %+v", options)
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"enum":["text","json"]`, `"default":"text"`, `"minimum":1`, `"required":["name"]`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("JSON %s lacks %s", data, want)
+		}
+	}
+}
+
+func TestJSONSchema_ValidateArgs(t *testing.T) {
+	def := schemaTestDefinition()
+	s := def.InputSchema()
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		wantParam string
+	}{
+		{"valid", map[string]any{"name": "Save", "files": []any{"a.go"}, "options": map[string]any{"depth": 2.0}}, ""},
+		{"unknown arguments ignored", map[string]any{"name": "Save", "extra": 1}, ""},
+		{"comma-separated array left to the tool", map[string]any{"name": "Save", "files": "a.go,b.go"}, ""},
+		{"missing required", map[string]any{"format": "text"}, "name"},
+		{"wrong type", map[string]any{"name": 3.0}, "name"},
+		{"too short", map[string]any{"name": ""}, "name"},
+		{"not in enum", map[string]any{"name": "Save", "format": "xml"}, "format"},
+		{"bad array item", map[string]any{"name": "Save", "files": []any{"a.go", 7.0}}, "files[1]"},
+		{"nested required", map[string]any{"name": "Save", "options": map[string]any{}}, "options.depth"},
+		{"nested range", map[string]any{"name": "Save", "options": map[string]any{"depth": 9.0}}, "options.depth"},
+		{"nested type", map[string]any{"name": "Save", "options": map[string]any{"depth": 2.0, "verbose": "yes"}}, "options.verbose"},
+		{"object expected", map[string]any{"name": "Save", "options": "deep"}, "options"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.ValidateArgs(tt.args)
+			if tt.wantParam == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			var ve *ValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("err = %v, want *ValidationError", err)
+			}
+			if ve.Parameter != tt.wantParam {
+				t.Errorf("Parameter = %q, want %q (%v)", ve.Parameter, tt.wantParam, err)
+			}
+		})
+	}
+}
+
+type schemaNode struct {
+	Name     string        `json:"name"`
+	Children []*schemaNode `json:"children,omitempty"`
+}
+
+type schemaBase struct {
+	ID string `json:"id"`
+}
+
+type schemaOutput struct {
+	schemaBase
+	Count    int               `json:"count"`
+	Score    float64           `json:"score,omitempty"`
+	When     time.Time         `json:"when"`
+	Tags     map[string]string `json:"tags,omitempty"`
+	Root     *schemaNode       `json:"root,omitempty"`
+	Any      any               `json:"any,omitempty"`
+	Internal string            `json:"-"`
+	hidden   string
+}
+
+func TestSchemaOf(t *testing.T) {
+	s := SchemaOf(schemaOutput{hidden: "x"})
+
+	if s.Type != "object" {
+		t.Fatalf("Type = %q", s.Type)
+	}
+	for _, name := range []string{"id", "count", "score", "when", "tags", "root", "any"} {
+		if _, ok := s.Properties[name]; !ok {
+			t.Errorf("missing property %q", name)
+		}
+	}
+	for _, name := range []string{"Internal", "-", "hidden", "schemaBase"} {
+		if _, ok := s.Properties[name]; ok {
+			t.Errorf("unexpected property %q", name)
+		}
+	}
+	if strings.Join(s.Required, ",") != "count,id,when" {
+		t.Errorf("Required = %v, want omitempty fields optional", s.Required)
+	}
+	if s.Properties["when"].Format != "date-time" {
+		t.Errorf("when = %+v", s.Properties["when"])
+	}
+	if s.Properties["tags"].AdditionalProperties.Type != "string" {
+		t.Errorf("tags = %+v", s.Properties["tags"])
+	}
+	// The recursive field stops at the second visit instead of looping.
+	children := s.Properties["root"].Properties["children"]
+	if children.Type != "array" || children.Items.Type != "object" || children.Items.Properties != nil {
+		t.Errorf("children = %+v", children)
+	}
+}
+
+func TestExecutor_ValidatesAgainstSchema(t *testing.T) {
+	registry := NewRegistry()
+	tool := &mockTool{name: "schema_tool", definition: schemaTestDefinition()}
+	registry.Register(tool)
+	executor := NewExecutor(registry, nil)
+
+	if schema, ok := registry.Schema("schema_tool"); !ok || schema.Input.Properties["options"] == nil {
+		t.Fatalf("registry schema = %+v, %v", schema, ok)
+	}
+
+	_, err := executor.Execute(context.Background(), &Invocation{
+		ToolName:   "schema_tool",
+		Parameters: map[string]any{"name": "Save", "files": []any{"a.go", 3.0}},
+	})
+	if !errors.Is(err, ErrValidationFailed) {
+		t.Fatalf("err = %v, want ErrValidationFailed", err)
+	}
+	var ve *ValidationError
+	if !errors.As(err, &ve) || ve.Parameter != "files[1]" {
+		t.Errorf("err = %v, want a ValidationError naming files[1]", err)
+	}
+
+	if registry.Unregister("schema_tool"); len(registry.Schemas()) != 0 {
+		t.Error("Unregister should drop the cached schema")
+	}
+}
+
+func TestProviderToolFormats(t *testing.T) {
+	defs := []ToolDefinition{schemaTestDefinition()}
+
+	openai, err := json.Marshal(ToOpenAITools(defs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(openai), `"type":"function","function":{"name":"schema_tool"`) ||
+		!strings.Contains(string(openai), `"parameters":{"type":"object"`) {
+		t.Errorf("openai = %s", openai)
+	}
+
+	anthropic, err := json.Marshal(ToAnthropicTools(defs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(anthropic), `"name":"schema_tool"`) ||
+		!strings.Contains(string(anthropic), `"input_schema":{"type":"object"`) {
+		t.Errorf("anthropic = %s", anthropic)
+	}
+}
+
+func TestRegisteredTools_Schemas(t *testing.T) {
+	g := graph.NewGraph("/project")
+	g.Freeze()
+	registry := NewRegistry()
+	RegisterExploreTools(registry, g, index.NewSymbolIndex())
+
+	schemas := registry.Schemas()
+	if len(schemas) != registry.Count() {
+		t.Fatalf("%d schemas for %d tools", len(schemas), registry.Count())
+	}
+	for _, schema := range schemas {
+		tool, _ := registry.Get(schema.Name)
+		def := tool.Definition()
+		if schema.Input == nil || schema.Input.Type != "object" || len(schema.Input.Properties) != len(def.Parameters) {
+			t.Errorf("%s: input schema does not match definition", schema.Name)
+		}
+		if schema.Output == nil {
+			t.Errorf("%s: no output schema", schema.Name)
+		}
+		if _, err := json.Marshal(schema); err != nil {
+			t.Errorf("%s: %v", schema.Name, err)
+		}
+	}
+	if s, _ := registry.Schema("find_callers"); s.Output.Type != "object" || len(s.Output.Properties) == 0 {
+		t.Errorf("find_callers output = %+v, want its typed output", s.Output)
+	}
+}
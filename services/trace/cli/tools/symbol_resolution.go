@@ -19,6 +19,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // KindFilter controls which symbol kinds are accepted during resolution.
@@ -224,8 +225,8 @@ func ResolveFunctionWithFuzzy(
 	if strings.Contains(name, ":") {
 		if sym, ok := index.GetByID(name); ok {
 			logger.Info("Symbol resolution: full-ID bypass",
-				slog.String("query", name),
-				slog.String("resolved", sym.Name),
+				telemetry.SensitiveLog("query", name),
+				telemetry.SensitiveLog("resolved", sym.Name),
 				slog.String("kind", sym.Kind.String()),
 			)
 			return sym, false, nil
@@ -244,10 +245,10 @@ func ResolveFunctionWithFuzzy(
 			// Request object in lib/request.js.
 			best := pickMostSignificantSymbol(exactMatches)
 			logger.Info("Symbol resolution: exact match",
-				slog.String("query", name),
-				slog.String("resolved", best.Name),
+				telemetry.SensitiveLog("query", name),
+				telemetry.SensitiveLog("resolved", best.Name),
 				slog.String("kind", best.Kind.String()),
-				slog.String("file", best.FilePath),
+				telemetry.SensitiveLog("file", best.FilePath),
 				slog.Int("line", best.StartLine),
 				slog.Int("candidates", len(exactMatches)),
 			)
@@ -257,10 +258,10 @@ func ResolveFunctionWithFuzzy(
 		for _, sym := range exactMatches {
 			if matchesKindFilter(sym, cfg.kindFilter) {
 				logger.Info("Symbol resolution: exact match",
-					slog.String("query", name),
-					slog.String("resolved", sym.Name),
+					telemetry.SensitiveLog("query", name),
+					telemetry.SensitiveLog("resolved", sym.Name),
 					slog.String("kind", sym.Kind.String()),
-					slog.String("file", sym.FilePath),
+					telemetry.SensitiveLog("file", sym.FilePath),
 					slog.Int("line", sym.StartLine),
 				)
 				return sym, false, nil
@@ -268,7 +269,7 @@ func ResolveFunctionWithFuzzy(
 		}
 		// Exact matches exist but none pass the kind filter — fall through
 		logger.Debug("Symbol resolution: exact matches found but none match kind filter",
-			slog.String("query", name),
+			telemetry.SensitiveLog("query", name),
 			slog.Int("total_matches", len(exactMatches)),
 			slog.String("kind_filter", cfg.kindFilter.String()),
 		)
@@ -283,16 +284,16 @@ func ResolveFunctionWithFuzzy(
 			// Function/Method/Property; if caller wants types only, this should fall through)
 			if matchesKindFilter(sym, cfg.kindFilter) {
 				logger.Info("Symbol resolution: dot notation match",
-					slog.String("query", name),
+					telemetry.SensitiveLog("query", name),
 					slog.String("type", typeName),
-					slog.String("method", methodName),
-					slog.String("resolved", sym.Name),
-					slog.String("file", sym.FilePath),
+					telemetry.SensitiveLog("method", methodName),
+					telemetry.SensitiveLog("resolved", sym.Name),
+					telemetry.SensitiveLog("file", sym.FilePath),
 				)
 				return sym, false, nil
 			}
 			logger.Debug("Symbol resolution: dot notation match rejected by kind filter",
-				slog.String("query", name),
+				telemetry.SensitiveLog("query", name),
 				slog.String("resolved_kind", sym.Kind.String()),
 				slog.String("kind_filter", cfg.kindFilter.String()),
 			)
@@ -313,22 +314,22 @@ func ResolveFunctionWithFuzzy(
 			}
 			if len(filtered) == 1 {
 				logger.Info("Symbol resolution: bare method fallback",
-					slog.String("query", name),
-					slog.String("bare_name", methodName),
-					slog.String("resolved", filtered[0].Name),
+					telemetry.SensitiveLog("query", name),
+					telemetry.SensitiveLog("bare_name", methodName),
+					telemetry.SensitiveLog("resolved", filtered[0].Name),
 					slog.String("kind", filtered[0].Kind.String()),
-					slog.String("file", filtered[0].FilePath),
+					telemetry.SensitiveLog("file", filtered[0].FilePath),
 				)
 				return filtered[0], false, nil
 			}
 			if len(filtered) > 1 {
 				best := pickBestBareCandidate(filtered, typeName)
 				logger.Info("Symbol resolution: bare method fallback (disambiguated)",
-					slog.String("query", name),
-					slog.String("bare_name", methodName),
+					telemetry.SensitiveLog("query", name),
+					telemetry.SensitiveLog("bare_name", methodName),
 					slog.String("type_prefix", typeName),
-					slog.String("resolved", best.Name),
-					slog.String("file", best.FilePath),
+					telemetry.SensitiveLog("resolved", best.Name),
+					telemetry.SensitiveLog("file", best.FilePath),
 					slog.Int("candidates", len(filtered)),
 				)
 				return best, false, nil
@@ -339,7 +340,7 @@ func ResolveFunctionWithFuzzy(
 
 	// Step 4: Fuzzy search (slower, requires context timeout)
 	logger.Info("Symbol resolution: exact match failed, trying fuzzy search",
-		slog.String("query", name),
+		telemetry.SensitiveLog("query", name),
 	)
 
 	searchCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
@@ -348,15 +349,15 @@ func ResolveFunctionWithFuzzy(
 	fuzzyMatches, err := index.Search(searchCtx, name, 20) // Get top 20 to filter by kind
 	if err != nil {
 		logger.Debug("Symbol resolution: fuzzy search failed",
-			slog.String("query", name),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("query", name),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, false, fmt.Errorf("no match found for '%s': %w", name, err)
 	}
 
 	if len(fuzzyMatches) == 0 {
 		logger.Debug("Symbol resolution: no fuzzy matches found",
-			slog.String("query", name),
+			telemetry.SensitiveLog("query", name),
 		)
 		return nil, false, fmt.Errorf("no match found for '%s'", name)
 	}
@@ -371,7 +372,7 @@ func ResolveFunctionWithFuzzy(
 
 	// Log all matches (showing which were filtered)
 	logger.Info("Symbol resolution: fuzzy search results",
-		slog.String("query", name),
+		telemetry.SensitiveLog("query", name),
 		slog.Int("total_matches", len(fuzzyMatches)),
 		slog.Int("kind_filtered", len(kindFiltered)),
 	)
@@ -380,11 +381,11 @@ func ResolveFunctionWithFuzzy(
 		if i < 8 { // Log top 8 matches in detail
 			logger.Info("Symbol resolution: fuzzy match candidate",
 				slog.Int("rank", i+1),
-				slog.String("query", name),
-				slog.String("matched_name", match.Name),
+				telemetry.SensitiveLog("query", name),
+				telemetry.SensitiveLog("matched_name", match.Name),
 				slog.String("kind", match.Kind.String()),
 				slog.Bool("matches_filter", matchesKindFilter(match, cfg.kindFilter)),
-				slog.String("file", match.FilePath),
+				telemetry.SensitiveLog("file", match.FilePath),
 				slog.Int("line", match.StartLine),
 			)
 		}
@@ -393,7 +394,7 @@ func ResolveFunctionWithFuzzy(
 	// Check if we have any matches after kind filtering
 	if len(kindFiltered) == 0 {
 		logger.Debug("Symbol resolution: fuzzy search found matches but none pass kind filter",
-			slog.String("query", name),
+			telemetry.SensitiveLog("query", name),
 			slog.Int("total_matches", len(fuzzyMatches)),
 			slog.String("kind_filter", cfg.kindFilter.String()),
 		)
@@ -412,10 +413,10 @@ func ResolveFunctionWithFuzzy(
 		selectedMatch = kindFiltered[0]
 	}
 	logger.Info("Symbol resolution: selected fuzzy match",
-		slog.String("query", name),
-		slog.String("matched", selectedMatch.Name),
+		telemetry.SensitiveLog("query", name),
+		telemetry.SensitiveLog("matched", selectedMatch.Name),
 		slog.String("kind", selectedMatch.Kind.String()),
-		slog.String("file", selectedMatch.FilePath),
+		telemetry.SensitiveLog("file", selectedMatch.FilePath),
 		slog.Int("line", selectedMatch.StartLine),
 		slog.Int("candidates", len(kindFiltered)),
 	)
@@ -595,7 +596,7 @@ func ResolveFunctionCandidates(
 		pascalName := snakeToPascal(name)
 		if pascalName != name {
 			logger.Info("ResolveFunctionCandidates: trying snake_case → PascalCase fallback",
-				slog.String("original", name),
+				telemetry.SensitiveLog("original", name),
 				slog.String("pascal", pascalName),
 			)
 			pascalMatches := idx.GetByName(pascalName)
@@ -645,17 +646,17 @@ func ResolveFunctionCandidates(
 	}
 
 	logger.Info("ResolveFunctionCandidates: ranked candidates",
-		slog.String("query", name),
+		telemetry.SensitiveLog("query", name),
 		slog.Int("candidates", len(allCandidates)),
 		slog.Int("max", maxCandidates),
 	)
 	for i, c := range allCandidates {
 		logger.Debug("ResolveFunctionCandidates: candidate",
 			slog.Int("rank", i),
-			slog.String("name", c.Name),
+			telemetry.SensitiveLog("name", c.Name),
 			slog.String("kind", c.Kind.String()),
-			slog.String("file", c.FilePath),
-			slog.String("id", c.ID),
+			telemetry.SensitiveLog("file", c.FilePath),
+			telemetry.SensitiveLog("id", c.ID),
 		)
 	}
 
@@ -753,8 +754,8 @@ func ResolveMultipleFunctionsWithFuzzy(
 		if err != nil {
 			logger.Debug("Failed to resolve symbol in batch",
 				slog.Int("index", i),
-				slog.String("name", name),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("name", name),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return nil, nil, fmt.Errorf("failed to resolve '%s': %w", name, err)
 		}
@@ -843,8 +844,8 @@ func resolveTypeDotMethod(
 		best := pickBestCandidate(candidates)
 		logger.Debug("resolveTypeDotMethod: matched via receiver/ID",
 			slog.String("type", typeName),
-			slog.String("method", methodName),
-			slog.String("resolved_id", best.ID),
+			telemetry.SensitiveLog("method", methodName),
+			telemetry.SensitiveLog("resolved_id", best.ID),
 			slog.Int("candidates", len(candidates)),
 		)
 		return best, nil
@@ -882,8 +883,8 @@ func resolveTypeDotMethod(
 		if nonOverloadMatch != nil {
 			logger.Debug("resolveTypeDotMethod: matched via parent class children (non-overload)",
 				slog.String("type", typeName),
-				slog.String("method", methodName),
-				slog.String("resolved_id", nonOverloadMatch.ID),
+				telemetry.SensitiveLog("method", methodName),
+				telemetry.SensitiveLog("resolved_id", nonOverloadMatch.ID),
 				slog.String("parent_id", typeSym.ID),
 			)
 			return nonOverloadMatch, nil
@@ -891,8 +892,8 @@ func resolveTypeDotMethod(
 		if overloadFallback != nil {
 			logger.Debug("resolveTypeDotMethod: matched via parent class children (overload fallback)",
 				slog.String("type", typeName),
-				slog.String("method", methodName),
-				slog.String("resolved_id", overloadFallback.ID),
+				telemetry.SensitiveLog("method", methodName),
+				telemetry.SensitiveLog("resolved_id", overloadFallback.ID),
 				slog.String("parent_id", typeSym.ID),
 			)
 			return overloadFallback, nil
@@ -911,7 +912,7 @@ func resolveTypeDotMethod(
 		if typeSym.Metadata == nil || typeSym.Metadata.Extends == "" {
 			logger.Debug("resolveTypeDotMethod: Strategy 4 skipped (no Extends metadata)",
 				slog.String("type", typeName),
-				slog.String("symbol_id", typeSym.ID),
+				telemetry.SensitiveLog("symbol_id", typeSym.ID),
 			)
 			continue
 		}
@@ -935,8 +936,8 @@ func resolveTypeDotMethod(
 			if override := findChildOverride(idx, typeName, methodName, parentSym); override != nil {
 				logger.Debug("resolveTypeDotMethod: found child override of parent method",
 					slog.String("type", typeName),
-					slog.String("method", methodName),
-					slog.String("parent_method", parentSym.ID),
+					telemetry.SensitiveLog("method", methodName),
+					telemetry.SensitiveLog("parent_method", parentSym.ID),
 					slog.String("override", override.ID),
 				)
 				return override, nil
@@ -23,6 +23,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -172,6 +173,7 @@ func (t *checkReducibilityTool) Definition() ToolDefinition {
 		Priority:    79,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     30 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -187,6 +189,10 @@ func (t *checkReducibilityTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *checkReducibilityTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), CheckReducibilityOutput{})
+}
+
 // Execute runs the check_reducibility tool.
 func (t *checkReducibilityTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	// Parse and validate parameters
@@ -250,26 +256,26 @@ func (t *checkReducibilityTool) executeOnce(ctx context.Context, p CheckReducibi
 
 	// Check context cancellation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
 	// Detect entry point for dominator tree
 	entry, err := DetectEntryPoint(ctx, t.index, t.analytics)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to detect entry point: %v", err),
 		}, nil
 	}
 
-	span.SetAttributes(attribute.String("entry", entry))
+	span.SetAttributes(telemetry.SensitiveString("entry", entry))
 
 	// Compute dominator tree
 	domTree, err := t.analytics.Dominators(ctx, entry)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to compute dominators: %v", err),
@@ -278,7 +284,7 @@ func (t *checkReducibilityTool) executeOnce(ctx context.Context, p CheckReducibi
 
 	// Check context again
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
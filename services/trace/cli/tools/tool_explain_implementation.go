@@ -0,0 +1,297 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// =============================================================================
+// explain_implementation Tool
+// =============================================================================
+
+var explainImplementationTracer = otel.Tracer("tools.explain_implementation")
+
+// ExplainImplementationParams contains the validated input parameters.
+type ExplainImplementationParams struct {
+	// Type is the struct, class or type, by symbol ID or unique name.
+	Type string
+
+	// Interface is the interface, by symbol ID or unique name.
+	Interface string
+}
+
+// ToolName returns the tool name for TypedParams interface.
+func (p ExplainImplementationParams) ToolName() string { return "explain_implementation" }
+
+// ToMap converts typed parameters to the map consumed by Tool.Execute().
+func (p ExplainImplementationParams) ToMap() map[string]any {
+	return map[string]any{
+		"type":      p.Type,
+		"interface": p.Interface,
+	}
+}
+
+// explainImplementationTool explains why a type does or does not implement
+// an interface.
+//
+// Description:
+//
+//	Re-runs the graph builder's method-set comparison for one type and one
+//	interface and reports each interface method as matched or missing,
+//	with the candidate methods and their receivers and whether an embedded
+//	type's promoted method or cross-file method association supplied it.
+//	Answers "why doesn't X implement Y?" when find_implementations leaves
+//	X out.
+//
+// Thread Safety: Safe for concurrent use. All operations are read-only.
+type explainImplementationTool struct {
+	graph  *graph.Graph
+	logger *slog.Logger
+}
+
+// NewExplainImplementationTool creates the explain_implementation tool.
+//
+// Inputs:
+//
+//   - g: The code graph. Must not be nil.
+//
+// Outputs:
+//
+//   - Tool: The explain_implementation tool implementation.
+//
+// Limitations:
+//
+//   - Matching is by method name only, as in the graph builder.
+func NewExplainImplementationTool(g *graph.Graph) Tool {
+	return &explainImplementationTool{
+		graph:  g,
+		logger: slog.Default(),
+	}
+}
+
+func (t *explainImplementationTool) Name() string {
+	return "explain_implementation"
+}
+
+func (t *explainImplementationTool) Category() ToolCategory {
+	return CategoryExploration
+}
+
+func (t *explainImplementationTool) Definition() ToolDefinition {
+	return explainImplementationDefinition()
+}
+
+func (t *explainImplementationTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), graph.ImplementsExplanation{})
+}
+
+// explainImplementationDefinition is shared by the tool and
+// StaticToolDefinitions.
+func explainImplementationDefinition() ToolDefinition {
+	return ToolDefinition{
+		Name: "explain_implementation",
+		Description: "Explain why a type does or does not implement an interface. " +
+			"Lists each interface method as matched or missing, the candidate methods with their receivers, " +
+			"and whether promoted methods from embedded types or methods declared in other files were used. " +
+			"Use for 'why doesn't X implement Y?' or 'does X satisfy Y?'.",
+		Parameters: map[string]ParamDef{
+			"type": {
+				Type:        ParamTypeString,
+				Description: "The struct, class or type, by name or symbol ID (e.g., 'MemStore').",
+				Required:    true,
+			},
+			"interface": {
+				Type:        ParamTypeString,
+				Description: "The interface or protocol, by name or symbol ID (e.g., 'Store').",
+				Required:    true,
+			},
+		},
+		Category:    CategoryExploration,
+		Priority:    80,
+		Requires:    []string{"graph_initialized"},
+		SideEffects: false,
+		Timeout:     5 * time.Second,
+		WhenToUse: WhenToUse{
+			Keywords: []string{
+				"why doesn't", "does not implement", "doesn't implement", "satisfy",
+				"satisfies", "conform", "missing method", "implement interface",
+			},
+			UseWhen:   "User asks whether or why a specific type implements, satisfies or conforms to a specific interface.",
+			AvoidWhen: "User wants every implementation of an interface — use find_implementations.",
+		},
+	}
+}
+
+// Execute runs the explain_implementation tool.
+func (t *explainImplementationTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
+	start := time.Now()
+
+	p, err := t.parseParams(params.ToMap())
+	if err != nil {
+		errStep := crs.NewTraceStepBuilder().
+			WithAction("tool_explain_implementation").
+			WithTool("explain_implementation").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return &Result{
+			Success:   false,
+			Error:     err.Error(),
+			TraceStep: &errStep,
+			Duration:  time.Since(start),
+		}, nil
+	}
+
+	_, span := explainImplementationTracer.Start(ctx, "explainImplementationTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "explain_implementation"),
+			attribute.String("type", p.Type),
+			telemetry.SensitiveString("interface", p.Interface),
+		),
+	)
+	defer span.End()
+
+	target := p.Type + " -> " + p.Interface
+	exp, err := t.explain(p)
+	if err != nil {
+		notFoundStep := crs.NewTraceStepBuilder().
+			WithAction("tool_explain_implementation").
+			WithTarget(target).
+			WithTool("explain_implementation").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return &Result{
+			Success:   false,
+			Error:     err.Error(),
+			TraceStep: &notFoundStep,
+			Duration:  time.Since(start),
+		}, nil
+	}
+	span.SetAttributes(
+		attribute.Bool("implements", exp.Implements),
+		attribute.Int("missing", len(exp.Missing)),
+	)
+
+	text := formatImplementsExplanation(exp)
+	duration := time.Since(start)
+	toolStep := crs.NewTraceStepBuilder().
+		WithAction("tool_explain_implementation").
+		WithTarget(exp.TypeID+" -> "+exp.InterfaceID).
+		WithTool("explain_implementation").
+		WithDuration(duration).
+		WithMetadata("implements", fmt.Sprintf("%t", exp.Implements)).
+		WithMetadata("missing_count", fmt.Sprintf("%d", len(exp.Missing))).
+		Build()
+
+	return &Result{
+		Success:     true,
+		Output:      exp,
+		OutputText:  text,
+		TokensUsed:  estimateTokens(text),
+		TraceStep:   &toolStep,
+		Duration:    duration,
+		ResultCount: len(exp.Methods),
+	}, nil
+}
+
+// explain resolves both symbols and runs the graph's explanation.
+func (t *explainImplementationTool) explain(p ExplainImplementationParams) (*graph.ImplementsExplanation, error) {
+	typeID, err := t.graph.ResolveImplementsSymbol(p.Type, false)
+	if err != nil {
+		return nil, fmt.Errorf("type: %w", err)
+	}
+	ifaceID, err := t.graph.ResolveImplementsSymbol(p.Interface, true)
+	if err != nil {
+		return nil, fmt.Errorf("interface: %w", err)
+	}
+	return t.graph.ExplainImplements(typeID, ifaceID)
+}
+
+// formatImplementsExplanation renders the explanation for the LLM.
+func formatImplementsExplanation(exp *graph.ImplementsExplanation) string {
+	var sb strings.Builder
+	if exp.Implements {
+		sb.WriteString(fmt.Sprintf("## GRAPH RESULT: %s implements %s\n\n", exp.TypeID, exp.InterfaceID))
+	} else {
+		sb.WriteString(fmt.Sprintf("## GRAPH RESULT: %s does NOT implement %s\n\n", exp.TypeID, exp.InterfaceID))
+	}
+	for _, reason := range exp.Reasons {
+		sb.WriteString(fmt.Sprintf("- %s\n", reason))
+	}
+	if len(exp.Missing) > 0 {
+		sb.WriteString(fmt.Sprintf("Missing methods: %s\n", strings.Join(exp.Missing, ", ")))
+	}
+	if exp.EdgePresent != exp.Implements {
+		sb.WriteString(fmt.Sprintf("Note: the graph's IMPLEMENTS edge is %s; it may come from an explicit declaration.\n",
+			map[bool]string{true: "present", false: "absent"}[exp.EdgePresent]))
+	}
+
+	sb.WriteString("\n### Interface methods\n\n")
+	for _, m := range exp.Methods {
+		if !m.Matched {
+			sb.WriteString(fmt.Sprintf("- %s: MISSING", m.Name))
+		} else {
+			sb.WriteString(fmt.Sprintf("- %s: matched (%s", m.Name, m.Via))
+			if m.PromotedFrom != "" {
+				sb.WriteString(" from " + m.PromotedFrom)
+			}
+			sb.WriteString(")")
+		}
+		if m.DeclaredIn != exp.InterfaceID {
+			sb.WriteString(fmt.Sprintf(", required by embedded %s", m.DeclaredIn))
+		}
+		sb.WriteString("\n")
+		for _, c := range m.Candidates {
+			marker := "candidate"
+			if c.Selected {
+				marker = "selected"
+			}
+			sb.WriteString(fmt.Sprintf("  - %s: %s.%s at %s:%d\n", marker, c.Receiver, m.Name, c.FilePath, c.Line))
+		}
+	}
+	return sb.String()
+}
+
+// parseParams validates and extracts typed parameters.
+func (t *explainImplementationTool) parseParams(params map[string]any) (ExplainImplementationParams, error) {
+	var p ExplainImplementationParams
+	if raw, ok := params["type"]; ok {
+		if s, ok := parseStringParam(raw); ok {
+			p.Type = strings.TrimSpace(s)
+		}
+	}
+	if raw, ok := params["interface"]; ok {
+		if s, ok := parseStringParam(raw); ok {
+			p.Interface = strings.TrimSpace(s)
+		}
+	}
+	if p.Type == "" {
+		return p, fmt.Errorf("'type' parameter is required")
+	}
+	if p.Interface == "" {
+		return p, fmt.Errorf("'interface' parameter is required")
+	}
+	return p, nil
+}
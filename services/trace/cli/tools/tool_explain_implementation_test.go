@@ -0,0 +1,117 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// createExplainTestGraph builds a graph where Engine gets Close from an
+// embedded Base and declares Serve, and Client only declares Serve.
+func createExplainTestGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	sym := func(id, name string, kind ast.SymbolKind, file, sig string, meta *ast.SymbolMetadata) *ast.Symbol {
+		return &ast.Symbol{
+			ID: id, Name: name, Kind: kind, FilePath: file, StartLine: 1, EndLine: 5,
+			Language: "go", Signature: sig, Metadata: meta,
+		}
+	}
+	parse := func(file string, symbols ...*ast.Symbol) *ast.ParseResult {
+		return &ast.ParseResult{FilePath: file, Language: "go", Package: "app", Symbols: symbols}
+	}
+
+	results := []*ast.ParseResult{
+		parse("server.go", sym("server.go:1:Server", "Server", ast.SymbolKindInterface, "server.go", "", &ast.SymbolMetadata{
+			Methods: []ast.MethodSignature{{Name: "Serve"}, {Name: "Close"}},
+		})),
+		parse("base.go",
+			sym("base.go:1:Base", "Base", ast.SymbolKindStruct, "base.go", "", &ast.SymbolMetadata{
+				Methods: []ast.MethodSignature{{Name: "Close", ReceiverType: "*Base"}},
+			}),
+			sym("base.go:3:Close", "Close", ast.SymbolKindMethod, "base.go", "func (b *Base) Close() error", nil)),
+		parse("engine.go",
+			sym("engine.go:1:Engine", "Engine", ast.SymbolKindStruct, "engine.go", "", &ast.SymbolMetadata{
+				Extends: "Base",
+				Methods: []ast.MethodSignature{{Name: "Serve", ReceiverType: "*Engine"}},
+			}),
+			sym("engine.go:3:Serve", "Serve", ast.SymbolKindMethod, "engine.go", "func (e *Engine) Serve() error", nil)),
+		parse("client.go",
+			sym("client.go:1:Client", "Client", ast.SymbolKindStruct, "client.go", "", &ast.SymbolMetadata{
+				Methods: []ast.MethodSignature{{Name: "Serve", ReceiverType: "*Client"}},
+			}),
+			sym("client.go:3:Serve", "Serve", ast.SymbolKindMethod, "client.go", "func (c *Client) Serve() error", nil)),
+	}
+	result, err := graph.NewBuilder(graph.WithProjectRoot("/test")).Build(context.Background(), results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return result.Graph
+}
+
+func TestExplainImplementationTool_Execute(t *testing.T) {
+	ctx := context.Background()
+	tool := NewExplainImplementationTool(createExplainTestGraph(t))
+
+	t.Run("explains a promoted match", func(t *testing.T) {
+		result, err := tool.Execute(ctx, ExplainImplementationParams{Type: "Engine", Interface: "Server"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Execute() failed: %s", result.Error)
+		}
+		exp := result.Output.(*graph.ImplementsExplanation)
+		if !exp.Implements || !exp.UsedPromoted {
+			t.Errorf("Implements=%v UsedPromoted=%v, want both true", exp.Implements, exp.UsedPromoted)
+		}
+		if !strings.Contains(result.OutputText, "Engine implements") ||
+			!strings.Contains(result.OutputText, "promoted from base.go:1:Base") {
+			t.Errorf("OutputText = %q", result.OutputText)
+		}
+	})
+
+	t.Run("names the missing methods", func(t *testing.T) {
+		result, err := tool.Execute(ctx, ExplainImplementationParams{Type: "Client", Interface: "Server"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		exp := result.Output.(*graph.ImplementsExplanation)
+		if exp.Implements || len(exp.Missing) != 1 || exp.Missing[0] != "Close" {
+			t.Errorf("Implements=%v Missing=%v, want false and [Close]", exp.Implements, exp.Missing)
+		}
+		if !strings.Contains(result.OutputText, "does NOT implement") || !strings.Contains(result.OutputText, "Close: MISSING") {
+			t.Errorf("OutputText = %q", result.OutputText)
+		}
+	})
+
+	t.Run("rejects missing parameters and unknown symbols", func(t *testing.T) {
+		result, err := tool.Execute(ctx, ExplainImplementationParams{Type: "Engine"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Success || !strings.Contains(result.Error, "'interface'") {
+			t.Errorf("missing interface: Success=%v Error=%q", result.Success, result.Error)
+		}
+
+		result, err = tool.Execute(ctx, ExplainImplementationParams{Type: "Nope", Interface: "Server"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Success || !strings.Contains(result.Error, "symbol not found") {
+			t.Errorf("unknown type: Success=%v Error=%q", result.Success, result.Error)
+		}
+	})
+}
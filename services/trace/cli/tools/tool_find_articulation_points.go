@@ -23,6 +23,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -199,6 +200,7 @@ func (t *findArticulationPointsTool) Definition() ToolDefinition {
 		Priority:    84,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     30 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -214,6 +216,10 @@ func (t *findArticulationPointsTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findArticulationPointsTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindArticulationPointsOutput{})
+}
+
 // Execute runs the find_articulation_points tool.
 func (t *findArticulationPointsTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -247,7 +253,7 @@ func (t *findArticulationPointsTool) Execute(ctx context.Context, params TypedPa
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -290,7 +296,7 @@ func (t *findArticulationPointsTool) Execute(ctx context.Context, params TypedPa
 		}
 
 		t.logger.Info("IT-07: find_articulation_points scope filter",
-			slog.String("package", p.Package),
+			telemetry.SensitiveLog("package", p.Package),
 			slog.Int("before", scopePreCount),
 			slog.Int("after", len(filteredPoints)),
 		)
@@ -47,6 +47,20 @@ type FindCalleesParams struct {
 	// IT-06c Bug C: Used to disambiguate when multiple symbols share the same name.
 	// For example, "Build" in Hugo matches 11 symbols; "hugolib" narrows to the right one.
 	PackageHint string
+
+	// Root restricts results to one root of a multi-root workspace (optional).
+	Root string
+
+	// FollowDependencies continues into the callees of dependency code
+	// (symbols with DependencyDepth > 0), up to maxDependencyHops calls
+	// deep. Only useful on graphs built with resolve_dependencies.
+	FollowDependencies bool
+
+	// Cursor is the next_cursor of the previous page (optional).
+	Cursor string
+
+	// offset is the position Cursor resumes at, per matched symbol.
+	offset int
 }
 
 // ToolName returns the tool name for TypedParams interface.
@@ -61,15 +75,34 @@ func (p FindCalleesParams) ToMap() map[string]any {
 	if p.PackageHint != "" {
 		m["package_hint"] = p.PackageHint
 	}
+	if p.Root != "" {
+		m["root"] = p.Root
+	}
+	if p.FollowDependencies {
+		m["follow_dependencies"] = true
+	}
+	if p.Cursor != "" {
+		m["cursor"] = p.Cursor
+	}
 	return m
 }
 
+// cursorQuery identifies the query in cursors.
+func (p FindCalleesParams) cursorQuery() []string {
+	return []string{"find_callees", p.FunctionName, p.PackageHint, p.Root, fmt.Sprint(p.FollowDependencies)}
+}
+
+// maxDependencyHops bounds how many calls deep follow_dependencies walks
+// into dependency code.
+const maxDependencyHops = 3
+
 // FindCalleesOutput contains the structured result.
 type FindCalleesOutput struct {
 	// FunctionName is the function that was searched for.
 	FunctionName string `json:"function_name"`
 
-	// ResolvedCount is the number of in-codebase callees.
+	// ResolvedCount is the number of in-codebase callees, including
+	// callees in dependency source.
 	ResolvedCount int `json:"resolved_count"`
 
 	// ExternalCount is the number of external/stdlib callees.
@@ -84,10 +117,70 @@ type FindCalleesOutput struct {
 	// ExternalCallees are external/stdlib callees (names only).
 	ExternalCallees []string `json:"external_callees"`
 
+	// ExternalSymbols describe the external callees, in ExternalCallees
+	// order, with what the graph knows about them.
+	ExternalSymbols []ExternalSymbolInfo `json:"external_symbols,omitempty"`
+
 	// ResolvedKind is the ast.SymbolKind of the resolved symbol (e.g., "type", "function").
 	// IT-06b Issue 3: Used by formatText to provide kind-specific messages
 	// (e.g., "HandlerFunc is a type alias, not a function with a body").
 	ResolvedKind string `json:"resolved_kind,omitempty"`
+
+	PageInfo
+}
+
+// ExternalSymbolInfo describes a symbol outside the project, such as a
+// standard library function. External is always true, so the symbol is
+// not mistaken for project code.
+type ExternalSymbolInfo struct {
+	// Name is the symbol name, or the package for a package placeholder.
+	Name string `json:"name"`
+
+	// Package is the import path, if known.
+	Package string `json:"package,omitempty"`
+
+	// External marks the symbol as outside the project.
+	External bool `json:"external"`
+
+	// Signature and Doc are set for Go standard library symbols, from
+	// the embedded stdlib index.
+	Signature string `json:"signature,omitempty"`
+	Doc       string `json:"doc,omitempty"`
+
+	// Members are the names used from the package when the graph
+	// collapses placeholders per package.
+	Members []string `json:"members,omitempty"`
+}
+
+// newExternalSymbolInfo describes an external placeholder symbol.
+func newExternalSymbolInfo(sym *ast.Symbol) ExternalSymbolInfo {
+	info := ExternalSymbolInfo{
+		Name:      sym.Name,
+		Package:   sym.Package,
+		External:  true,
+		Signature: sym.Signature,
+		Doc:       sym.DocComment,
+	}
+	if sym.Metadata != nil {
+		info.Members = sym.Metadata.ExternalMembers
+	}
+	return info
+}
+
+// externalDetail formats what is known about an external symbol for text
+// output: " — <signature>: <doc>" and the members used, or "".
+func externalDetail(signature, doc string, members []string) string {
+	var sb strings.Builder
+	if signature != "" {
+		sb.WriteString(" — " + signature)
+		if doc != "" {
+			sb.WriteString(": " + doc)
+		}
+	}
+	if len(members) > 0 {
+		sb.WriteString(" [uses " + strings.Join(members, ", ") + "]")
+	}
+	return sb.String()
 }
 
 // CalleeInfo holds information about an in-codebase callee.
@@ -109,6 +202,19 @@ type CalleeInfo struct {
 
 	// SourceID is the ID of the caller symbol.
 	SourceID string `json:"source_id"`
+
+	// DependencyDepth is the callee's ast.Symbol.DependencyDepth: 0 for
+	// project code, the import depth for dependency source.
+	DependencyDepth int `json:"dependency_depth,omitempty"`
+
+	// Via is the dependency function that calls this callee, set for
+	// callees reached by follow_dependencies.
+	Via string `json:"via,omitempty"`
+
+	// AmbiguousImport is true when the call edge was resolved through an
+	// import whose module path matched several same-named modules, so the
+	// callee may be one of the others.
+	AmbiguousImport bool `json:"ambiguous_import,omitempty"`
 }
 
 // findCalleesTool wraps graph.FindCalleesByName.
@@ -191,15 +297,26 @@ func (t *findCalleesTool) Definition() ToolDefinition {
 			},
 			"limit": {
 				Type:        ParamTypeInt,
-				Description: "Maximum number of callees to return",
+				Description: "Maximum number of callees to return per page",
 				Required:    false,
 				Default:     50,
 			},
+			"root":   rootParamDef,
+			"cursor": cursorParamDef,
+			"follow_dependencies": {
+				Type: ParamTypeBool,
+				Description: "Also list what dependency functions called by this function call in turn, " +
+					"up to 3 calls deep. Use for 'what does this library call under the hood?'. " +
+					"Requires a graph initialized with resolve_dependencies.",
+				Required: false,
+				Default:  false,
+			},
 		},
 		Category:    CategoryExploration,
 		Priority:    94,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     5 * time.Second,
 		// IT-02 H-3: Added specific callee keywords and grammar hint
 		WhenToUse: WhenToUse{
@@ -221,6 +338,10 @@ func (t *findCalleesTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findCalleesTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindCalleesOutput{})
+}
+
 // Execute runs the find_callees tool.
 func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -246,7 +367,7 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 	ctx, span := findCalleesTracer.Start(ctx, "findCalleesTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_callees"),
-			attribute.String("function_name", p.FunctionName),
+			telemetry.SensitiveString("function_name", p.FunctionName),
 			attribute.Int("limit", p.Limit),
 			attribute.Bool("index_available", t.index != nil),
 		),
@@ -273,8 +394,8 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 				strategy = "dot_notation"
 				logger.Info("dot-notation resolved",
 					slog.String("tool", "find_callees"),
-					slog.String("query", p.FunctionName),
-					slog.String("matched", symbol.Name),
+					telemetry.SensitiveLog("query", p.FunctionName),
+					telemetry.SensitiveLog("matched", symbol.Name),
 				)
 				symbols = []*ast.Symbol{symbol}
 			} else {
@@ -296,8 +417,8 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 					symbols = bareSymbols
 					logger.Info("IT-02 C-1: dot-notation fallback to bare name",
 						slog.String("tool", "find_callees"),
-						slog.String("query", p.FunctionName),
-						slog.String("bare_name", bareName),
+						telemetry.SensitiveLog("query", p.FunctionName),
+						telemetry.SensitiveLog("bare_name", bareName),
 						slog.Int("matches", len(bareSymbols)),
 					)
 					span.SetAttributes(attribute.Bool("dot_notation_fallback", true))
@@ -308,9 +429,9 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 						symbols = []*ast.Symbol{bareSym}
 						logger.Info("IT-02 C-1: dot-notation fallback to bare fuzzy",
 							slog.String("tool", "find_callees"),
-							slog.String("query", p.FunctionName),
-							slog.String("bare_name", bareName),
-							slog.String("matched", bareSym.Name),
+							telemetry.SensitiveLog("query", p.FunctionName),
+							telemetry.SensitiveLog("bare_name", bareName),
+							telemetry.SensitiveLog("matched", bareSym.Name),
 							slog.Bool("fuzzy", fuzzy),
 						)
 						span.SetAttributes(attribute.Bool("dot_notation_bare_fuzzy", true))
@@ -338,8 +459,8 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 					strategy = "fuzzy"
 					logger.Info("P1: Using fuzzy match for function",
 						slog.String("tool", "find_callees"),
-						slog.String("query", p.FunctionName),
-						slog.String("matched", symbol.Name),
+						telemetry.SensitiveLog("query", p.FunctionName),
+						telemetry.SensitiveLog("matched", symbol.Name),
 					)
 					symbols = []*ast.Symbol{symbol}
 				}
@@ -367,6 +488,19 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 			}
 		}
 
+		// Prefer project symbols over same-named ones in dependency source.
+		if len(symbols) > 1 {
+			var projectSymbols []*ast.Symbol
+			for _, sym := range symbols {
+				if sym != nil && sym.DependencyDepth == 0 {
+					projectSymbols = append(projectSymbols, sym)
+				}
+			}
+			if len(projectSymbols) > 0 {
+				symbols = projectSymbols
+			}
+		}
+
 		if len(symbols) > 0 {
 			// IT-06b Issue 3: Capture the resolved symbol's kind for formatText.
 			resolvedKind = symbols[0].Kind
@@ -377,17 +511,17 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 					continue
 				}
 				if err := ctx.Err(); err != nil {
-					span.RecordError(err)
+					telemetry.RecordException(span, err)
 					return nil, err
 				}
-				result, qErr := t.graph.FindCalleesByID(ctx, sym.ID, graph.WithLimit(p.Limit))
+				result, qErr := t.graph.FindCalleesByID(ctx, sym.ID, graph.WithLimit(p.Limit), graph.WithOffset(p.offset))
 				if qErr != nil {
 					queryErrors++
 					logger.Warn("graph query failed",
 						slog.String("tool", "find_callees"),
 						slog.String("operation", "FindCalleesByID"),
-						slog.String("symbol_id", sym.ID),
-						slog.String("error", qErr.Error()),
+						telemetry.SensitiveLog("symbol_id", sym.ID),
+						telemetry.SensitiveLogError("error", qErr),
 					)
 					continue
 				}
@@ -404,13 +538,13 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 		logger.Warn("graph query fallback",
 			slog.String("tool", "find_callees"),
 			slog.String("reason", "index_unavailable"),
-			slog.String("function_name", p.FunctionName),
+			telemetry.SensitiveLog("function_name", p.FunctionName),
 		)
 		span.SetAttributes(attribute.Bool("index_used", false))
 		var gErr error
-		results, gErr = t.graph.FindCalleesByName(ctx, p.FunctionName, graph.WithLimit(p.Limit))
+		results, gErr = t.graph.FindCalleesByName(ctx, p.FunctionName, graph.WithLimit(p.Limit), graph.WithOffset(p.offset))
 		if gErr != nil {
-			span.RecordError(gErr)
+			telemetry.RecordException(span, gErr)
 			errStep := crs.NewTraceStepBuilder().
 				WithAction("tool_find_callees").
 				WithTarget(p.FunctionName).
@@ -427,8 +561,16 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 		}
 	}
 
+	filterQueryResultsByRoot(results, p.Root)
+
 	// Build typed output (single classification pass)
 	output := t.buildOutput(p.FunctionName, results)
+	if p.FollowDependencies {
+		if err := t.followDependencies(ctx, &output, results, p.Limit); err != nil {
+			telemetry.RecordException(span, err)
+			return nil, err
+		}
+	}
 	// IT-06b Issue 3: Thread resolved symbol kind into output for formatText.
 	// Only set when a symbol was actually resolved — otherwise leave empty
 	// so omitempty suppresses it in JSON (avoids misleading "unknown").
@@ -450,11 +592,13 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 	// IT_CRS_01: Compute truncated flag from query results
 	truncated := false
 	for _, qr := range results {
-		if qr != nil && qr.Truncated {
-			truncated = true
-			break
+		if qr != nil {
+			truncated = truncated || qr.Truncated
+			output.TotalAvailable += qr.Total
 		}
 	}
+	output.NextCursor = nextToolCursor(t.graph, truncated, p.offset, p.Limit, p.cursorQuery()...)
+	outputText = withPageNote(outputText, "find_callees", p.offset, output.TotalCount, output.PageInfo)
 
 	// Build CRS TraceStep for reasoning trace continuity
 	toolStep := crs.NewTraceStepBuilder().
@@ -467,6 +611,7 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 		WithMetadata("total_count", fmt.Sprintf("%d", output.TotalCount)).
 		WithMetadata("resolution_strategy", strategy).
 		WithMetadata("truncated", fmt.Sprintf("%t", truncated)).
+		WithMetadata("total_available", fmt.Sprintf("%d", output.TotalAvailable)).
 		Build()
 
 	return &Result{
@@ -477,6 +622,7 @@ func (t *findCalleesTool) Execute(ctx context.Context, params TypedParams) (*Res
 		TraceStep:   &toolStep,
 		Duration:    duration,
 		ResultCount: output.TotalCount,
+		NextCursor:  output.NextCursor,
 	}, nil
 }
 
@@ -519,6 +665,27 @@ func (t *findCalleesTool) parseParams(params map[string]any) (FindCalleesParams,
 		}
 	}
 
+	// Extract root (optional)
+	if rootRaw, ok := params["root"]; ok {
+		if root, ok := parseStringParam(rootRaw); ok {
+			p.Root = root
+		}
+	}
+
+	// Extract follow_dependencies (optional)
+	if followRaw, ok := params["follow_dependencies"]; ok {
+		if follow, ok := parseBoolParam(followRaw); ok {
+			p.FollowDependencies = follow
+		}
+	}
+
+	// Extract cursor (optional)
+	p.Cursor = parseCursorParam(params)
+	var err error
+	if p.offset, err = decodeToolCursor(t.graph, p.Cursor, p.cursorQuery()...); err != nil {
+		return p, err
+	}
+
 	return p, nil
 }
 
@@ -533,7 +700,7 @@ func (t *findCalleesTool) parseParams(params map[string]any) (FindCalleesParams,
 // multiple source symbols resolve to the same callee.
 func (t *findCalleesTool) buildOutput(functionName string, results map[string]*graph.QueryResult) FindCalleesOutput {
 	var resolvedCallees []CalleeInfo
-	var externalCallees []string
+	var externalCallees []*ast.Symbol
 	seenResolved := make(map[string]bool)
 
 	for symbolID, result := range results {
@@ -547,7 +714,7 @@ func (t *findCalleesTool) buildOutput(functionName string, results map[string]*g
 			}
 			// External/placeholder symbols have empty FilePath or Kind=External
 			if sym.FilePath == "" || sym.Kind == ast.SymbolKindExternal {
-				externalCallees = append(externalCallees, sym.Name)
+				externalCallees = append(externalCallees, sym)
 			} else {
 				// L-1: Deduplicate resolved callees by symbol ID
 				if seenResolved[sym.ID] {
@@ -561,7 +728,9 @@ func (t *findCalleesTool) buildOutput(functionName string, results map[string]*g
 					Package:   sym.Package,
 					Signature: sym.Signature,
 					// L-2: CallerID is the symbol whose callees we queried (not the callee's own ID)
-					SourceID: symbolID,
+					SourceID:        symbolID,
+					DependencyDepth: sym.DependencyDepth,
+					AmbiguousImport: ambiguousImportEdge(t.graph, symbolID, sym.ID),
 				})
 			}
 		}
@@ -581,10 +750,12 @@ func (t *findCalleesTool) buildOutput(functionName string, results map[string]*g
 	// Deduplicate external callees
 	seen := make(map[string]bool)
 	var uniqueExternal []string
-	for _, name := range externalCallees {
-		if !seen[name] {
-			seen[name] = true
-			uniqueExternal = append(uniqueExternal, name)
+	var externalSymbols []ExternalSymbolInfo
+	for _, sym := range externalCallees {
+		if !seen[sym.Name] {
+			seen[sym.Name] = true
+			uniqueExternal = append(uniqueExternal, sym.Name)
+			externalSymbols = append(externalSymbols, newExternalSymbolInfo(sym))
 		}
 	}
 
@@ -595,7 +766,103 @@ func (t *findCalleesTool) buildOutput(functionName string, results map[string]*g
 		TotalCount:      len(resolvedCallees) + len(uniqueExternal),
 		ResolvedCallees: resolvedCallees,
 		ExternalCallees: uniqueExternal,
+		ExternalSymbols: externalSymbols,
+	}
+}
+
+// followDependencies adds the callees of dependency code to output.
+//
+// Description:
+//
+//	Walks breadth-first from the callees in results that live in
+//	dependency source (DependencyDepth > 0), up to maxDependencyHops
+//	calls deep, adding each newly reached callee with Via set to the
+//	dependency function that calls it. Stops once output holds limit
+//	resolved callees.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	output - The output built from results. Modified in place.
+//	results - The direct callee query results.
+//	limit - The maximum number of resolved callees.
+//
+// Outputs:
+//
+//	error - Non-nil if ctx is done.
+func (t *findCalleesTool) followDependencies(ctx context.Context, output *FindCalleesOutput, results map[string]*graph.QueryResult, limit int) error {
+	seen := make(map[string]bool)
+	var frontier []*ast.Symbol
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		for _, sym := range result.Symbols {
+			if sym == nil || seen[sym.ID] {
+				continue
+			}
+			seen[sym.ID] = true
+			if sym.DependencyDepth > 0 && sym.FilePath != "" && sym.Kind != ast.SymbolKindExternal {
+				frontier = append(frontier, sym)
+			}
+		}
+	}
+	external := make(map[string]bool, len(output.ExternalCallees))
+	for _, name := range output.ExternalCallees {
+		external[name] = true
+	}
+
+	for hop := 0; hop < maxDependencyHops && len(frontier) > 0; hop++ {
+		var next []*ast.Symbol
+		for _, caller := range frontier {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if len(output.ResolvedCallees) >= limit {
+				break
+			}
+			result, err := t.graph.FindCalleesByID(ctx, caller.ID, graph.WithLimit(limit))
+			if err != nil || result == nil {
+				continue
+			}
+			for _, sym := range result.Symbols {
+				if sym == nil {
+					continue
+				}
+				if sym.FilePath == "" || sym.Kind == ast.SymbolKindExternal {
+					if !external[sym.Name] {
+						external[sym.Name] = true
+						output.ExternalCallees = append(output.ExternalCallees, sym.Name)
+						output.ExternalSymbols = append(output.ExternalSymbols, newExternalSymbolInfo(sym))
+					}
+					continue
+				}
+				if seen[sym.ID] || len(output.ResolvedCallees) >= limit {
+					continue
+				}
+				seen[sym.ID] = true
+				output.ResolvedCallees = append(output.ResolvedCallees, CalleeInfo{
+					Name:            sym.Name,
+					File:            sym.FilePath,
+					Line:            sym.StartLine,
+					Package:         sym.Package,
+					Signature:       sym.Signature,
+					SourceID:        caller.ID,
+					DependencyDepth: sym.DependencyDepth,
+					Via:             caller.Name,
+				})
+				if sym.DependencyDepth > 0 {
+					next = append(next, sym)
+				}
+			}
+		}
+		frontier = next
 	}
+
+	output.ResolvedCount = len(output.ResolvedCallees)
+	output.ExternalCount = len(output.ExternalCallees)
+	output.TotalCount = output.ResolvedCount + output.ExternalCount
+	return nil
 }
 
 // formatText creates a human-readable text summary from the typed output.
@@ -632,11 +899,31 @@ func (t *findCalleesTool) formatText(functionName string, output FindCalleesOutp
 	}
 	sb.WriteString(":\n\n")
 
-	// Show resolved (in-codebase) callees first
-	if output.ResolvedCount > 0 {
+	// Show resolved (in-codebase) callees first, dependency source after
+	var projectCallees, dependencyCallees []CalleeInfo
+	for _, callee := range output.ResolvedCallees {
+		if callee.DependencyDepth > 0 {
+			dependencyCallees = append(dependencyCallees, callee)
+		} else {
+			projectCallees = append(projectCallees, callee)
+		}
+	}
+	if len(projectCallees) > 0 {
 		sb.WriteString("## In-Codebase Callees (navigable)\n")
-		for _, callee := range output.ResolvedCallees {
-			sb.WriteString(fmt.Sprintf("  → %s() in %s:%d\n", callee.Name, callee.File, callee.Line))
+		for _, callee := range projectCallees {
+			sb.WriteString(fmt.Sprintf("  → %s() in %s:%d%s\n", callee.Name, callee.File, callee.Line, ambiguousImportSuffix(callee.AmbiguousImport)))
+		}
+		sb.WriteString("\n")
+	}
+	if len(dependencyCallees) > 0 {
+		sb.WriteString("## Dependency Callees (library source)\n")
+		for _, callee := range dependencyCallees {
+			sb.WriteString(fmt.Sprintf("  → %s() in %s:%d", callee.Name, callee.File, callee.Line))
+			sb.WriteString(ambiguousImportSuffix(callee.AmbiguousImport))
+			if callee.Via != "" {
+				sb.WriteString(fmt.Sprintf(" (via %s)", callee.Via))
+			}
+			sb.WriteString("\n")
 		}
 		sb.WriteString("\n")
 	}
@@ -644,21 +931,23 @@ func (t *findCalleesTool) formatText(functionName string, output FindCalleesOutp
 	// Summarize external callees (already deduplicated by buildOutput)
 	if output.ExternalCount > 0 {
 		sb.WriteString("## External/Stdlib Callees (not in codebase)\n")
-		if len(output.ExternalCallees) <= 10 {
-			for _, name := range output.ExternalCallees {
-				sb.WriteString(fmt.Sprintf("  → %s() (external)\n", name))
+		for i, name := range output.ExternalCallees {
+			if i == 10 {
+				sb.WriteString(fmt.Sprintf("  ... and %d more external calls\n", len(output.ExternalCallees)-10))
+				break
 			}
-		} else {
-			for _, name := range output.ExternalCallees[:10] {
-				sb.WriteString(fmt.Sprintf("  → %s() (external)\n", name))
+			detail := ""
+			if i < len(output.ExternalSymbols) {
+				ext := output.ExternalSymbols[i]
+				detail = externalDetail(ext.Signature, ext.Doc, ext.Members)
 			}
-			sb.WriteString(fmt.Sprintf("  ... and %d more external calls\n", len(output.ExternalCallees)-10))
+			sb.WriteString(fmt.Sprintf("  → %s() (external)%s\n", name, detail))
 		}
 	}
 
 	// GR-59 Group A: Signal definitiveness on success path.
 	sb.WriteString("\n---\n")
-	sb.WriteString("The graph has been fully indexed — these results are exhaustive.\n")
+	sb.WriteString(exhaustiveResultsLine)
 	sb.WriteString("**Do NOT use Grep or Read to verify** — the graph already analyzed all source files.\n")
 
 	return sb.String()
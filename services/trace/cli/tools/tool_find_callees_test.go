@@ -252,6 +252,68 @@ func TestFindCalleesTool_ExternalCallees(t *testing.T) {
 	}
 }
 
+func TestFindCalleesTool_ExternalSymbols(t *testing.T) {
+	g := graph.NewGraph("/test")
+	idx := index.NewSymbolIndex()
+	serve := &ast.Symbol{
+		ID: "main.go:5:serve", Name: "serve", Kind: ast.SymbolKindFunction,
+		FilePath: "main.go", StartLine: 5, EndLine: 12, Package: "main", Language: "go",
+	}
+	listen := &ast.Symbol{
+		ID: "external:net/http:ListenAndServe", Name: "ListenAndServe", Kind: ast.SymbolKindExternal,
+		Package: "net/http", Language: "external",
+		Signature:  "func ListenAndServe(addr string, handler Handler) error",
+		DocComment: "ListenAndServe listens on the TCP network address addr.",
+	}
+	fmtPkg := &ast.Symbol{
+		ID: "external:fmt:fmt", Name: "fmt", Kind: ast.SymbolKindExternal, Package: "fmt", Language: "external",
+		Metadata: &ast.SymbolMetadata{ExternalMembers: []string{"Println", "Sprintf"}},
+	}
+	if err := idx.Add(serve); err != nil {
+		t.Fatal(err)
+	}
+	for _, sym := range []*ast.Symbol{serve, listen, fmtPkg} {
+		g.AddNode(sym)
+	}
+	g.AddEdge(serve.ID, listen.ID, graph.EdgeTypeCalls, ast.Location{FilePath: "main.go", StartLine: 6})
+	g.AddEdge(serve.ID, fmtPkg.ID, graph.EdgeTypeCalls, ast.Location{FilePath: "main.go", StartLine: 7})
+	g.Freeze()
+
+	result, err := NewFindCalleesTool(g, idx, nil).Execute(context.Background(), MapParams{Params: map[string]any{
+		"function_name": "serve",
+	}})
+	if err != nil || !result.Success {
+		t.Fatalf("Execute() = %v, %v", result, err)
+	}
+	output := result.Output.(FindCalleesOutput)
+	if len(output.ExternalSymbols) != 2 {
+		t.Fatalf("external symbols = %+v", output.ExternalSymbols)
+	}
+	for _, ext := range output.ExternalSymbols {
+		if !ext.External {
+			t.Errorf("%s not marked external", ext.Name)
+		}
+		switch ext.Name {
+		case "ListenAndServe":
+			if ext.Package != "net/http" || ext.Signature != listen.Signature || ext.Doc != listen.DocComment {
+				t.Errorf("ListenAndServe = %+v", ext)
+			}
+		case "fmt":
+			if strings.Join(ext.Members, ",") != "Println,Sprintf" {
+				t.Errorf("fmt members = %v", ext.Members)
+			}
+		}
+	}
+	for _, want := range []string{
+		"ListenAndServe() (external) — func ListenAndServe(addr string, handler Handler) error: ListenAndServe listens",
+		"fmt() (external) [uses Println, Sprintf]",
+	} {
+		if !strings.Contains(result.OutputText, want) {
+			t.Errorf("text lacks %q:\n%s", want, result.OutputText)
+		}
+	}
+}
+
 // TestFindCalleesTool_EmptyCallees tests the empty-result message formatting.
 func TestFindCalleesTool_EmptyCallees(t *testing.T) {
 	g, idx := createTestGraphForDotNotation(t)
@@ -921,3 +983,87 @@ func TestFindCalleesTool_SelfNameFilter(t *testing.T) {
 		t.Error("Expected _read to appear as a callee of read_csv")
 	}
 }
+
+// TestFindCalleesTool_FollowDependencies verifies that follow_dependencies
+// continues into dependency source and records the calling function.
+func TestFindCalleesTool_FollowDependencies(t *testing.T) {
+	ctx := context.Background()
+
+	g := graph.NewGraph("/test")
+	idx := index.NewSymbolIndex()
+
+	newSym := func(id, name, file string, depth int) *ast.Symbol {
+		return &ast.Symbol{
+			ID:              id,
+			Name:            name,
+			Kind:            ast.SymbolKindFunction,
+			FilePath:        file,
+			StartLine:       10,
+			EndLine:         20,
+			Package:         "pkg",
+			Language:        "go",
+			DependencyDepth: depth,
+		}
+	}
+	handler := newSym("main.go:10:Handle", "Handle", "main.go", 0)
+	open := newSym("_deps/example.com/db/db.go:10:Open", "Open", "_deps/example.com/db/db.go", 1)
+	dial := newSym("_deps/example.com/db/conn.go:10:dial", "dial", "_deps/example.com/db/conn.go", 1)
+	depHandle := newSym("_deps/example.com/other/o.go:10:Handle", "Handle", "_deps/example.com/other/o.go", 1)
+
+	for _, sym := range []*ast.Symbol{handler, open, dial, depHandle} {
+		g.AddNode(sym)
+		if err := idx.Add(sym); err != nil {
+			t.Fatalf("Failed to add %s: %v", sym.Name, err)
+		}
+	}
+	g.AddEdge(handler.ID, open.ID, graph.EdgeTypeCalls, ast.Location{FilePath: handler.FilePath, StartLine: 12})
+	g.AddEdge(open.ID, dial.ID, graph.EdgeTypeCalls, ast.Location{FilePath: open.FilePath, StartLine: 12})
+	g.AddEdge(depHandle.ID, dial.ID, graph.EdgeTypeCalls, ast.Location{FilePath: depHandle.FilePath, StartLine: 12})
+	g.Freeze()
+
+	tool := NewFindCalleesTool(g, idx, nil)
+
+	t.Run("direct_callees_only_by_default", func(t *testing.T) {
+		result, err := tool.Execute(ctx, MapParams{Params: map[string]any{
+			"function_name": "Handle",
+		}})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		output := result.Output.(FindCalleesOutput)
+
+		// The project Handle is preferred over the dependency one.
+		if output.ResolvedCount != 1 || output.ResolvedCallees[0].Name != "Open" {
+			t.Fatalf("got callees %+v, want only Open", output.ResolvedCallees)
+		}
+		if output.ResolvedCallees[0].DependencyDepth != 1 {
+			t.Errorf("DependencyDepth = %d, want 1", output.ResolvedCallees[0].DependencyDepth)
+		}
+		if !strings.Contains(result.OutputText, "## Dependency Callees") {
+			t.Errorf("OutputText missing dependency section:\n%s", result.OutputText)
+		}
+	})
+
+	t.Run("follows_into_dependency_code", func(t *testing.T) {
+		result, err := tool.Execute(ctx, FindCalleesParams{
+			FunctionName:       "Handle",
+			Limit:              50,
+			FollowDependencies: true,
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		output := result.Output.(FindCalleesOutput)
+
+		if output.ResolvedCount != 2 {
+			t.Fatalf("got %d resolved callees, want 2: %+v", output.ResolvedCount, output.ResolvedCallees)
+		}
+		got := output.ResolvedCallees[1]
+		if got.Name != "dial" || got.Via != "Open" || got.SourceID != open.ID {
+			t.Errorf("got %+v, want dial via Open", got)
+		}
+		if !strings.Contains(result.OutputText, "dial() in _deps/example.com/db/conn.go:10 (via Open)") {
+			t.Errorf("OutputText missing followed callee:\n%s", result.OutputText)
+		}
+	})
+}
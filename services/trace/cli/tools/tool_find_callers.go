@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -45,6 +46,21 @@ type FindCallersParams struct {
 	// PackageHint is an optional package/module context extracted from the query.
 	// IT-06c Bug C: Used to disambiguate when multiple symbols share the same name.
 	PackageHint string
+
+	// Root restricts results to one root of a multi-root workspace (optional).
+	Root string
+
+	// Annotation restricts the called functions to those with an
+	// annotation, as "key" or "key=value" (optional). With an empty
+	// FunctionName, callers of every function with the annotation are
+	// returned.
+	Annotation string
+
+	// Cursor is the next_cursor of the previous page (optional).
+	Cursor string
+
+	// offset is the position Cursor resumes at, per matched symbol.
+	offset int
 }
 
 // ToolName returns the tool name for TypedParams interface.
@@ -59,9 +75,23 @@ func (p FindCallersParams) ToMap() map[string]any {
 	if p.PackageHint != "" {
 		m["package_hint"] = p.PackageHint
 	}
+	if p.Root != "" {
+		m["root"] = p.Root
+	}
+	if p.Annotation != "" {
+		m["annotation"] = p.Annotation
+	}
+	if p.Cursor != "" {
+		m["cursor"] = p.Cursor
+	}
 	return m
 }
 
+// cursorQuery identifies the query in cursors.
+func (p FindCallersParams) cursorQuery() []string {
+	return []string{"find_callers", p.FunctionName, p.PackageHint, p.Root, p.Annotation}
+}
+
 // FindCallersOutput contains the structured result.
 type FindCallersOutput struct {
 	// FunctionName is the function that was searched for.
@@ -75,6 +105,8 @@ type FindCallersOutput struct {
 
 	// Results contains the callers grouped by target symbol ID.
 	Results []CallerResult `json:"results"`
+
+	PageInfo
 }
 
 // CallerResult represents callers for a specific target symbol.
@@ -85,6 +117,9 @@ type CallerResult struct {
 	// CallerCount is the number of callers for this target.
 	CallerCount int `json:"caller_count"`
 
+	// TargetAnnotations are the target's annotations, key → value.
+	TargetAnnotations map[string]string `json:"target_annotations,omitempty"`
+
 	// Callers is the list of caller symbols.
 	Callers []CallerInfo `json:"callers"`
 }
@@ -105,6 +140,32 @@ type CallerInfo struct {
 
 	// Signature is the function signature.
 	Signature string `json:"signature,omitempty"`
+
+	// AmbiguousImport is true when the call edge was resolved through an
+	// import whose module path matched several same-named modules, so the
+	// caller may actually call one of the others.
+	AmbiguousImport bool `json:"ambiguous_import,omitempty"`
+
+	// Annotations are the caller's annotations, key → value.
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ambiguousImportNote marks text output lines whose edge came from an
+// ambiguous import resolution.
+const ambiguousImportNote = " [ambiguous import: target picked among same-named modules]"
+
+// ambiguousImportEdge reports whether the edge fromID → toID in g was
+// resolved through an ambiguous import. False when g is nil.
+func ambiguousImportEdge(g *graph.Graph, fromID, toID string) bool {
+	return g != nil && g.HasAmbiguousImportEdge(fromID, toID)
+}
+
+// ambiguousImportSuffix returns ambiguousImportNote when ambiguous, else "".
+func ambiguousImportSuffix(ambiguous bool) string {
+	if ambiguous {
+		return ambiguousImportNote
+	}
+	return ""
 }
 
 // findCallersTool wraps graph.FindCallersByName.
@@ -182,20 +243,24 @@ func (t *findCallersTool) Definition() ToolDefinition {
 		Parameters: map[string]ParamDef{
 			"function_name": {
 				Type:        ParamTypeString,
-				Description: "Name of the function to find callers for. Use Type.Method format for methods (e.g., 'Context.JSON', 'DB.Open', 'Txn.Get'). For standalone functions, just the name (e.g., 'parseConfig', 'Publish').",
+				Description: "Name of the function to find callers for. Use Type.Method format for methods (e.g., 'Context.JSON', 'DB.Open', 'Txn.Get'). For standalone functions, just the name (e.g., 'parseConfig', 'Publish'). May be empty when annotation is given, to find callers of every function with that annotation (e.g., callers of deprecated functions).",
 				Required:    true,
 			},
 			"limit": {
 				Type:        ParamTypeInt,
-				Description: "Maximum number of callers to return",
+				Description: "Maximum number of callers to return per page",
 				Required:    false,
 				Default:     50,
 			},
+			"root":       rootParamDef,
+			"annotation": annotationParamDef,
+			"cursor":     cursorParamDef,
 		},
 		Category:    CategoryExploration,
 		Priority:    95, // High priority - direct answer to common questions
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     5 * time.Second,
 		// IT-02 H-3: Removed ambiguous "what calls" keyword, added grammar hint
 		WhenToUse: WhenToUse{
@@ -217,6 +282,10 @@ func (t *findCallersTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findCallersTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindCallersOutput{})
+}
+
 // Execute runs the find_callers tool.
 func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -242,9 +311,10 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 	ctx, span := findCallersTracer.Start(ctx, "findCallersTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_callers"),
-			attribute.String("function_name", p.FunctionName),
+			telemetry.SensitiveString("function_name", p.FunctionName),
 			attribute.Int("limit", p.Limit),
 			attribute.Bool("index_available", t.index != nil),
+			attribute.String("annotation", p.Annotation),
 		),
 	)
 	defer span.End()
@@ -255,20 +325,25 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 	var queryErrors int
 	usedInheritancePath := false
 	strategy := "fallback" // IT_CRS_01: Track resolution strategy for CRS metadata
+	annotationFilter := annotationFilterOf(p.Annotation)
 
-	if t.index != nil {
+	if t.index != nil || p.FunctionName == "" {
 		var symbols []*ast.Symbol
 
-		// IT-01 SI-2: If name contains ".", skip exact match (dot-notation names like
-		// "Txn.Get" are never stored as-is in the index) and go straight to fuzzy resolution.
-		if strings.Contains(p.FunctionName, ".") {
+		if p.FunctionName == "" {
+			// Annotation-only lookup: callers of every annotated symbol.
+			symbols = annotatedSymbols(t.graph, annotationFilter)
+			strategy = "annotation"
+		} else if strings.Contains(p.FunctionName, ".") {
+			// IT-01 SI-2: If name contains ".", skip exact match (dot-notation names like
+			// "Txn.Get" are never stored as-is in the index) and go straight to fuzzy resolution.
 			symbol, _, err := ResolveFunctionWithFuzzy(ctx, t.index, p.FunctionName, t.logger)
 			if err == nil {
 				strategy = "dot_notation"
 				t.logger.Info("dot-notation resolved",
 					slog.String("tool", "find_callers"),
-					slog.String("query", p.FunctionName),
-					slog.String("matched", symbol.Name),
+					telemetry.SensitiveLog("query", p.FunctionName),
+					telemetry.SensitiveLog("matched", symbol.Name),
 				)
 				symbols = []*ast.Symbol{symbol}
 			}
@@ -292,14 +367,18 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 					strategy = "fuzzy"
 					t.logger.Info("P1: Using fuzzy match for function",
 						slog.String("tool", "find_callers"),
-						slog.String("query", p.FunctionName),
-						slog.String("matched", symbol.Name),
+						telemetry.SensitiveLog("query", p.FunctionName),
+						telemetry.SensitiveLog("matched", symbol.Name),
 					)
 					symbols = []*ast.Symbol{symbol}
 				}
 			}
 		}
 
+		if p.FunctionName != "" && !annotationFilter.IsZero() {
+			symbols = filterSymbolsByAnnotation(t.graph, symbols, annotationFilter)
+		}
+
 		span.SetAttributes(
 			attribute.Bool("index_used", true),
 			attribute.Int("index_matches", len(symbols)),
@@ -313,7 +392,7 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 					continue
 				}
 				if err := ctx.Err(); err != nil {
-					span.RecordError(err)
+					telemetry.RecordException(span, err)
 					return nil, err
 				}
 
@@ -323,19 +402,19 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 				if len(parentMethodIDs) > 0 {
 					t.logger.Info("inheritance-aware caller search",
 						slog.String("tool", "find_callers"),
-						slog.String("symbol", sym.Name),
+						telemetry.SensitiveLog("symbol", sym.Name),
 						slog.Int("parent_methods", len(parentMethodIDs)),
 					)
 				}
 
-				result, qErr := t.graph.FindCallersWithInheritance(ctx, sym.ID, parentMethodIDs, graph.WithLimit(p.Limit))
+				result, qErr := t.graph.FindCallersWithInheritance(ctx, sym.ID, parentMethodIDs, graph.WithLimit(p.Limit), graph.WithOffset(p.offset))
 				if qErr != nil {
 					queryErrors++
 					t.logger.Warn("graph query failed",
 						slog.String("tool", "find_callers"),
 						slog.String("operation", "FindCallersWithInheritance"),
-						slog.String("symbol_id", sym.ID),
-						slog.String("error", qErr.Error()),
+						telemetry.SensitiveLog("symbol_id", sym.ID),
+						telemetry.SensitiveLogError("error", qErr),
 					)
 					continue
 				}
@@ -354,13 +433,13 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 		t.logger.Warn("graph query fallback",
 			slog.String("tool", "find_callers"),
 			slog.String("reason", "index_unavailable"),
-			slog.String("function_name", p.FunctionName),
+			telemetry.SensitiveLog("function_name", p.FunctionName),
 		)
 		span.SetAttributes(attribute.Bool("index_used", false))
 		var gErr error
-		legacyResults, gErr = t.graph.FindCallersByName(ctx, p.FunctionName, graph.WithLimit(p.Limit))
+		legacyResults, gErr = t.graph.FindCallersByName(ctx, p.FunctionName, graph.WithLimit(p.Limit), graph.WithOffset(p.offset))
 		if gErr != nil {
-			span.RecordError(gErr)
+			telemetry.RecordException(span, gErr)
 			errStep := crs.NewTraceStepBuilder().
 				WithAction("tool_find_callers").
 				WithTarget(p.FunctionName).
@@ -377,13 +456,20 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 		}
 	}
 
+	filterInheritanceResultsByRoot(inheritanceResults, p.Root)
+	filterQueryResultsByRoot(legacyResults, p.Root)
+
 	// Build typed output and format text
 	var output FindCallersOutput
 	var outputText string
 
 	if usedInheritancePath {
 		output = t.buildOutputFromInheritance(p.FunctionName, inheritanceResults)
-		outputText = t.formatTextWithInheritance(p.FunctionName, inheritanceResults)
+		if p.FunctionName == "" && len(inheritanceResults) == 0 {
+			outputText = fmt.Sprintf("No symbols have annotation '%s'.\n", p.Annotation)
+		} else {
+			outputText = t.formatTextWithInheritance(p.targetLabel(), inheritanceResults)
+		}
 	} else {
 		output = t.buildOutput(p.FunctionName, legacyResults)
 		outputText = t.formatText(p.FunctionName, legacyResults)
@@ -400,19 +486,21 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 	truncated := false
 	if usedInheritancePath {
 		for _, iqr := range inheritanceResults {
-			if iqr != nil && iqr.Truncated {
-				truncated = true
-				break
+			if iqr != nil {
+				truncated = truncated || iqr.Truncated
+				output.TotalAvailable += iqr.Total
 			}
 		}
 	} else {
 		for _, qr := range legacyResults {
-			if qr != nil && qr.Truncated {
-				truncated = true
-				break
+			if qr != nil {
+				truncated = truncated || qr.Truncated
+				output.TotalAvailable += qr.Total
 			}
 		}
 	}
+	output.NextCursor = nextToolCursor(t.graph, truncated, p.offset, p.Limit, p.cursorQuery()...)
+	outputText = withPageNote(outputText, "find_callers", p.offset, output.TotalCallers, output.PageInfo)
 
 	// IT_CRS_03 AC-5: Compute inheritance metadata for CRS diagnostics.
 	inheritanceDepth := 0
@@ -439,6 +527,7 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 		WithMetadata("used_inheritance_path", fmt.Sprintf("%t", usedInheritancePath)).
 		WithMetadata("resolution_strategy", strategy).
 		WithMetadata("truncated", fmt.Sprintf("%t", truncated)).
+		WithMetadata("total_available", fmt.Sprintf("%d", output.TotalAvailable)).
 		WithMetadata("inheritance_depth", fmt.Sprintf("%d", inheritanceDepth)).
 		WithMetadata("inherited_caller_count", fmt.Sprintf("%d", inheritedCallerCount)).
 		Build()
@@ -451,6 +540,7 @@ func (t *findCallersTool) Execute(ctx context.Context, params TypedParams) (*Res
 		TraceStep:   &toolStep,
 		Duration:    duration,
 		ResultCount: output.MatchCount,
+		NextCursor:  output.NextCursor,
 	}, nil
 }
 
@@ -460,14 +550,23 @@ func (t *findCallersTool) parseParams(params map[string]any) (FindCallersParams,
 		Limit: 50,
 	}
 
-	// Extract function_name (required)
+	// Extract annotation (optional)
+	annotation, err := parseAnnotationParam(params)
+	if err != nil {
+		return p, err
+	}
+	p.Annotation = annotation
+
+	// Extract function_name (required unless annotation is given)
 	if nameRaw, ok := params["function_name"]; ok {
 		if name, ok := parseStringParam(nameRaw); ok && name != "" {
 			p.FunctionName = name
 		}
 	}
-	if err := ValidateSymbolName(p.FunctionName, "function_name", "'handleRequest', 'Serve', 'Parse'"); err != nil {
-		return p, err
+	if p.FunctionName != "" || p.Annotation == "" {
+		if err := ValidateSymbolName(p.FunctionName, "function_name", "'handleRequest', 'Serve', 'Parse'"); err != nil {
+			return p, err
+		}
 	}
 
 	// Extract limit (optional)
@@ -493,9 +592,34 @@ func (t *findCallersTool) parseParams(params map[string]any) (FindCallersParams,
 		}
 	}
 
+	// Extract root (optional)
+	if rootRaw, ok := params["root"]; ok {
+		if root, ok := parseStringParam(rootRaw); ok {
+			p.Root = root
+		}
+	}
+
+	// Extract cursor (optional)
+	p.Cursor = parseCursorParam(params)
+	if p.offset, err = decodeToolCursor(t.graph, p.Cursor, p.cursorQuery()...); err != nil {
+		return p, err
+	}
+
 	return p, nil
 }
 
+// targetLabel names the queried functions in text output.
+func (p FindCallersParams) targetLabel() string {
+	switch {
+	case p.Annotation == "":
+		return p.FunctionName
+	case p.FunctionName == "":
+		return "annotation " + p.Annotation
+	default:
+		return p.FunctionName + " with annotation " + p.Annotation
+	}
+}
+
 // buildOutput creates the typed output struct.
 func (t *findCallersTool) buildOutput(functionName string, results map[string]*graph.QueryResult) FindCallersOutput {
 	output := FindCallersOutput{
@@ -510,9 +634,10 @@ func (t *findCallersTool) buildOutput(functionName string, results map[string]*g
 		}
 
 		cr := CallerResult{
-			TargetID:    symbolID,
-			CallerCount: len(result.Symbols),
-			Callers:     make([]CallerInfo, 0, len(result.Symbols)),
+			TargetID:          symbolID,
+			CallerCount:       len(result.Symbols),
+			TargetAnnotations: annotationsOfID(t.graph, symbolID),
+			Callers:           make([]CallerInfo, 0, len(result.Symbols)),
 		}
 
 		for _, sym := range result.Symbols {
@@ -520,11 +645,13 @@ func (t *findCallersTool) buildOutput(functionName string, results map[string]*g
 				continue
 			}
 			cr.Callers = append(cr.Callers, CallerInfo{
-				Name:      sym.Name,
-				File:      sym.FilePath,
-				Line:      sym.StartLine,
-				Package:   sym.Package,
-				Signature: sym.Signature,
+				Name:            sym.Name,
+				File:            sym.FilePath,
+				Line:            sym.StartLine,
+				Package:         sym.Package,
+				Signature:       sym.Signature,
+				AmbiguousImport: ambiguousImportEdge(t.graph, sym.ID, symbolID),
+				Annotations:     t.graph.AnnotationsOf(sym),
 			})
 			output.TotalCallers++
 		}
@@ -685,12 +812,13 @@ func (t *findCallersTool) formatText(functionName string, results map[string]*gr
 			continue
 		}
 
-		sb.WriteString(fmt.Sprintf("Target: %s\n", symbolID))
+		sb.WriteString(fmt.Sprintf("Target: %s%s\n", symbolID, targetAnnotationsSuffix(t.graph, symbolID)))
 		for _, sym := range result.Symbols {
 			if sym == nil {
 				continue
 			}
-			sb.WriteString(fmt.Sprintf("  • %s() in %s:%d\n", sym.Name, sym.FilePath, sym.StartLine))
+			sb.WriteString(fmt.Sprintf("  • %s() in %s:%d%s\n", sym.Name, sym.FilePath, sym.StartLine,
+				ambiguousImportSuffix(ambiguousImportEdge(t.graph, sym.ID, symbolID))))
 			if sym.Package != "" {
 				sb.WriteString(fmt.Sprintf("    Package: %s\n", sym.Package))
 			}
@@ -700,7 +828,7 @@ func (t *findCallersTool) formatText(functionName string, results map[string]*gr
 
 	// GR-59 Group A: Signal definitiveness on success path.
 	sb.WriteString("---\n")
-	sb.WriteString("The graph has been fully indexed — these results are exhaustive.\n")
+	sb.WriteString(exhaustiveResultsLine)
 	sb.WriteString("**Do NOT use Grep or Read to verify** — the graph already analyzed all source files.\n")
 
 	return sb.String()
@@ -733,9 +861,10 @@ func (t *findCallersTool) buildOutputFromInheritance(functionName string, result
 
 		allCallers := inhResult.AllCallers()
 		cr := CallerResult{
-			TargetID:    symbolID,
-			CallerCount: len(allCallers.Symbols),
-			Callers:     make([]CallerInfo, 0, len(allCallers.Symbols)),
+			TargetID:          symbolID,
+			CallerCount:       len(allCallers.Symbols),
+			TargetAnnotations: annotationsOfID(t.graph, symbolID),
+			Callers:           make([]CallerInfo, 0, len(allCallers.Symbols)),
 		}
 
 		for _, sym := range allCallers.Symbols {
@@ -743,11 +872,13 @@ func (t *findCallersTool) buildOutputFromInheritance(functionName string, result
 				continue
 			}
 			cr.Callers = append(cr.Callers, CallerInfo{
-				Name:      sym.Name,
-				File:      sym.FilePath,
-				Line:      sym.StartLine,
-				Package:   sym.Package,
-				Signature: sym.Signature,
+				Name:            sym.Name,
+				File:            sym.FilePath,
+				Line:            sym.StartLine,
+				Package:         sym.Package,
+				Signature:       sym.Signature,
+				AmbiguousImport: ambiguousImportEdge(t.graph, sym.ID, symbolID),
+				Annotations:     t.graph.AnnotationsOf(sym),
 			})
 			output.TotalCallers++
 		}
@@ -803,12 +934,13 @@ func (t *findCallersTool) formatTextWithInheritance(functionName string, results
 
 		// Direct callers
 		if inhResult.DirectCallers != nil && len(inhResult.DirectCallers.Symbols) > 0 {
-			sb.WriteString(fmt.Sprintf("Target: %s\n", symbolID))
+			sb.WriteString(fmt.Sprintf("Target: %s%s\n", symbolID, targetAnnotationsSuffix(t.graph, symbolID)))
 			for _, sym := range inhResult.DirectCallers.Symbols {
 				if sym == nil {
 					continue
 				}
-				sb.WriteString(fmt.Sprintf("  • %s() in %s:%d\n", sym.Name, sym.FilePath, sym.StartLine))
+				sb.WriteString(fmt.Sprintf("  • %s() in %s:%d%s\n", sym.Name, sym.FilePath, sym.StartLine,
+					ambiguousImportSuffix(ambiguousImportEdge(t.graph, sym.ID, symbolID))))
 				if sym.Package != "" {
 					sb.WriteString(fmt.Sprintf("    Package: %s\n", sym.Package))
 				}
@@ -831,7 +963,8 @@ func (t *findCallersTool) formatTextWithInheritance(functionName string, results
 					if sym == nil {
 						continue
 					}
-					sb.WriteString(fmt.Sprintf("  • %s() in %s:%d\n", sym.Name, sym.FilePath, sym.StartLine))
+					sb.WriteString(fmt.Sprintf("  • %s() in %s:%d%s\n", sym.Name, sym.FilePath, sym.StartLine,
+						ambiguousImportSuffix(ambiguousImportEdge(t.graph, sym.ID, parentMethodID))))
 					if sym.Package != "" {
 						sb.WriteString(fmt.Sprintf("    Package: %s\n", sym.Package))
 					}
@@ -843,7 +976,7 @@ func (t *findCallersTool) formatTextWithInheritance(functionName string, results
 
 	// GR-59 Group A: Signal definitiveness on success path.
 	sb.WriteString("---\n")
-	sb.WriteString("The graph has been fully indexed — these results are exhaustive.\n")
+	sb.WriteString(exhaustiveResultsLine)
 	sb.WriteString("**Do NOT use Grep or Read to verify** — the graph already analyzed all source files.\n")
 
 	return sb.String()
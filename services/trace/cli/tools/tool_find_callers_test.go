@@ -14,6 +14,10 @@ import (
 	"context"
 	"strings"
 	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 )
 
 func TestFindCallersTool_Execute(t *testing.T) {
@@ -463,3 +467,66 @@ func TestFindCallers_InheritanceMetadata(t *testing.T) {
 		}
 	}
 }
+
+// TestFindCallTools_AmbiguousImport tests that find_callers and find_callees
+// flag call edges resolved through an ambiguous Python import.
+func TestFindCallTools_AmbiguousImport(t *testing.T) {
+	ctx := context.Background()
+	g := graph.NewGraph("/test")
+	idx := index.NewSymbolIndex()
+	sym := func(file string, line int, name string) *ast.Symbol {
+		return &ast.Symbol{
+			ID: ast.GenerateID(file, line, name), Name: name, Kind: ast.SymbolKindFunction,
+			FilePath: file, StartLine: line, EndLine: line + 2, Language: "python",
+		}
+	}
+	checkout := sym("app/views.py", 10, "checkout")
+	refund := sym("app/refunds.py", 4, "refund")
+	charge := sym("libs/core/src/acme/billing.py", 3, "charge")
+	for _, s := range []*ast.Symbol{checkout, refund, charge} {
+		if _, err := g.AddNode(s); err != nil {
+			t.Fatal(err)
+		}
+		if err := idx.Add(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.AddEdgeWithProvenance(checkout.ID, charge.ID, graph.EdgeTypeCalls, ast.Location{FilePath: checkout.FilePath, StartLine: 11},
+		graph.EdgeProvenance{Pass: graph.PassImportAwareResolution, Confidence: graph.ConfidenceLow, Detail: graph.DetailAmbiguousImport}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdgeWithProvenance(refund.ID, charge.ID, graph.EdgeTypeCalls, ast.Location{FilePath: refund.FilePath, StartLine: 5},
+		graph.EdgeProvenance{Pass: graph.PassImportAwareResolution, Confidence: graph.ConfidenceMedium}); err != nil {
+		t.Fatal(err)
+	}
+	g.Freeze()
+
+	result, err := NewFindCallersTool(g, idx).Execute(ctx, MapParams{Params: map[string]any{"function_name": "charge"}})
+	if err != nil || !result.Success {
+		t.Fatalf("find_callers: %v %s", err, result.Error)
+	}
+	output := result.Output.(FindCallersOutput)
+	if len(output.Results) != 1 || len(output.Results[0].Callers) != 2 {
+		t.Fatalf("results = %+v", output.Results)
+	}
+	for _, c := range output.Results[0].Callers {
+		if c.AmbiguousImport != (c.Name == "checkout") {
+			t.Errorf("caller %s AmbiguousImport = %v", c.Name, c.AmbiguousImport)
+		}
+	}
+	if strings.Count(result.OutputText, ambiguousImportNote) != 1 || !strings.Contains(result.OutputText, "checkout() in app/views.py:10"+ambiguousImportNote) {
+		t.Errorf("find_callers text:\n%s", result.OutputText)
+	}
+
+	result, err = NewFindCalleesTool(g, idx, nil).Execute(ctx, MapParams{Params: map[string]any{"function_name": "checkout"}})
+	if err != nil || !result.Success {
+		t.Fatalf("find_callees: %v %s", err, result.Error)
+	}
+	callees := result.Output.(FindCalleesOutput).ResolvedCallees
+	if len(callees) != 1 || !callees[0].AmbiguousImport {
+		t.Errorf("callees = %+v", callees)
+	}
+	if !strings.Contains(result.OutputText, "charge() in libs/core/src/acme/billing.py:3"+ambiguousImportNote) {
+		t.Errorf("find_callees text:\n%s", result.OutputText)
+	}
+}
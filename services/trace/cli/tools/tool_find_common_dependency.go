@@ -165,6 +165,7 @@ func (t *findCommonDependencyTool) Definition() ToolDefinition {
 		Priority:    81,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     30 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -180,6 +181,11 @@ func (t *findCommonDependencyTool) Definition() ToolDefinition {
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *findCommonDependencyTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindCommonDependencyOutput{})
+}
+
 // Execute runs the find_common_dependency tool.
 func (t *findCommonDependencyTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -192,10 +198,10 @@ func (t *findCommonDependencyTool) Execute(ctx context.Context, params TypedPara
 	// Parse and validate parameters
 	p, err := t.parseParams(params.ToMap())
 	if err != nil {
-		span.AddEvent("invalid_params", trace.WithAttributes(
-			attribute.String("error", err.Error()),
-		))
-		logger.Debug("find_common_dependency: invalid parameters", slog.String("error", err.Error()))
+		telemetry.AddSpanEvent(span, "invalid_params",
+			telemetry.SensitiveString("error", err.Error()),
+		)
+		logger.Debug("find_common_dependency: invalid parameters", telemetry.SensitiveLogError("error", err))
 		return &Result{
 			Success: false,
 			Error:   err.Error(),
@@ -217,13 +223,13 @@ func (t *findCommonDependencyTool) Execute(ctx context.Context, params TypedPara
 
 	// Phase 11A: Treat "not found" as successful informational result (Feb 14, 2026)
 	if len(unresolvedTargets) > 0 {
-		span.AddEvent("unresolved_targets", trace.WithAttributes(
-			attribute.StringSlice("unresolved", unresolvedTargets),
-			attribute.StringSlice("resolved", getResolvedNames(resolvedTargets)),
-		))
+		telemetry.AddSpanEvent(span, "unresolved_targets",
+			telemetry.SensitiveStrings("unresolved", unresolvedTargets),
+			telemetry.SensitiveStrings("resolved", getResolvedNames(resolvedTargets)),
+		)
 		logger.Info("find_common_dependency: some targets not found - returning informational result",
-			slog.Any("unresolved", unresolvedTargets),
-			slog.Any("resolved", getResolvedNames(resolvedTargets)),
+			telemetry.SensitiveLogStrings("unresolved", unresolvedTargets),
+			telemetry.SensitiveLogStrings("resolved", getResolvedNames(resolvedTargets)),
 		)
 
 		// Build informational output text
@@ -265,11 +271,11 @@ func (t *findCommonDependencyTool) Execute(ctx context.Context, params TypedPara
 	// Auto-detect entry point if not specified
 	entryNodeID, err := t.resolveEntryPoint(ctx, p.Entry)
 	if err != nil {
-		span.AddEvent("entry_resolution_error", trace.WithAttributes(
-			attribute.String("error", err.Error()),
-		))
+		telemetry.AddSpanEvent(span, "entry_resolution_error",
+			telemetry.SensitiveString("error", err.Error()),
+		)
 		logger.Debug("find_common_dependency: entry resolution failed",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return &Result{
 			Success: false,
@@ -277,14 +283,14 @@ func (t *findCommonDependencyTool) Execute(ctx context.Context, params TypedPara
 		}, nil
 	}
 
-	span.AddEvent("analysis_params", trace.WithAttributes(
+	telemetry.AddSpanEvent(span, "analysis_params",
 		attribute.Int("target_count", len(resolvedTargets)),
-		attribute.String("entry", entryNodeID),
-	))
+		telemetry.SensitiveString("entry", entryNodeID),
+	)
 
 	logger.Debug("find_common_dependency: starting analysis",
 		slog.Int("target_count", len(resolvedTargets)),
-		slog.String("entry", entryNodeID),
+		telemetry.SensitiveLog("entry", entryNodeID),
 	)
 
 	// Compute dominator tree
@@ -384,8 +390,8 @@ func (t *findCommonDependencyTool) parseParams(params map[string]any) (FindCommo
 		if len(legacyTargets) >= 2 {
 			targets = legacyTargets
 			t.logger.Debug("find_common_dependency: using legacy parameter format",
-				slog.String("function_a", legacyTargets[0]),
-				slog.String("function_b", legacyTargets[1]))
+				telemetry.SensitiveLog("function_a", legacyTargets[0]),
+				telemetry.SensitiveLog("function_b", legacyTargets[1]))
 		}
 	}
 
@@ -422,8 +428,8 @@ func (t *findCommonDependencyTool) resolveTargets(
 				if fuzzy {
 					t.logger.Info("P1: Using fuzzy match for target",
 						slog.String("tool", "find_common_dependency"),
-						slog.String("query", target),
-						slog.String("matched", symbol.Name),
+						telemetry.SensitiveLog("query", target),
+						telemetry.SensitiveLog("matched", symbol.Name),
 					)
 				}
 				resolved = append(resolved, ResolvedTarget{
@@ -24,6 +24,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -247,10 +248,15 @@ func (t *findCommunitiesTool) Definition() ToolDefinition {
 		Priority:    82,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     60 * time.Second,
 	}
 }
 
+func (t *findCommunitiesTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindCommunitiesOutput{})
+}
+
 // Execute runs the find_communities tool.
 func (t *findCommunitiesTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -280,14 +286,14 @@ func (t *findCommunitiesTool) Execute(ctx context.Context, params TypedParams) (
 			attribute.Float64("resolution", p.Resolution),
 			attribute.Int("top", p.Top),
 			attribute.Bool("show_cross_edges", p.ShowCrossEdges),
-			attribute.String("package_filter", p.PackageFilter),
+			telemetry.SensitiveString("package_filter", p.PackageFilter),
 		),
 	)
 	defer span.End()
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -172,6 +173,7 @@ func (t *findControlDependenciesTool) Definition() ToolDefinition {
 				Type:        ParamTypeInt,
 				Description: "Maximum dependency chain depth (default: 5, max: 10)",
 				Required:    false,
+				Minimum:     Bound(1),
 				Default:     5,
 			},
 		},
@@ -179,6 +181,7 @@ func (t *findControlDependenciesTool) Definition() ToolDefinition {
 		Priority:    83,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     45 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -194,6 +197,10 @@ func (t *findControlDependenciesTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findControlDependenciesTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindControlDependenciesOutput{})
+}
+
 // Execute runs the find_control_dependencies tool.
 func (t *findControlDependenciesTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	// Parse and validate parameters
@@ -250,7 +257,7 @@ func (t *findControlDependenciesTool) executeOnce(ctx context.Context, p FindCon
 	ctx, span := findControlDependenciesTracer.Start(ctx, "findControlDependenciesTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_control_dependencies"),
-			attribute.String("target", p.Target),
+			telemetry.SensitiveString("target", p.Target),
 			attribute.Int("depth", p.Depth),
 		),
 	)
@@ -258,26 +265,26 @@ func (t *findControlDependenciesTool) executeOnce(ctx context.Context, p FindCon
 
 	// Check context cancellation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
 	// Resolve target symbol
 	targetID, err := t.resolveTarget(ctx, p.Target)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to resolve target: %v", err),
 		}, nil
 	}
 
-	span.SetAttributes(attribute.String("target_id", targetID))
+	span.SetAttributes(telemetry.SensitiveString("target_id", targetID))
 
 	// Detect entry and exit points for dominator trees
 	entry, err := DetectEntryPoint(ctx, t.index, t.analytics)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to detect entry point: %v", err),
@@ -287,10 +294,10 @@ func (t *findControlDependenciesTool) executeOnce(ctx context.Context, p FindCon
 	// Compute post-dominator tree for control dependence
 	postDomTree, err := t.analytics.PostDominators(ctx, "")
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		t.logger.Debug("failed to compute post-dominators, using dominator-based approximation",
 			slog.String("tool", "find_control_dependencies"),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		// Fall back to using dominator tree as approximation
 		postDomTree = nil
@@ -306,7 +313,7 @@ func (t *findControlDependenciesTool) executeOnce(ctx context.Context, p FindCon
 		// Use dominator tree as fallback
 		domTree, domErr := t.analytics.Dominators(ctx, entry)
 		if domErr != nil {
-			span.RecordError(domErr)
+			telemetry.RecordException(span, domErr)
 			return &Result{
 				Success: false,
 				Error:   fmt.Sprintf("failed to compute dominators: %v", domErr),
@@ -364,7 +371,7 @@ func (t *findControlDependenciesTool) executeOnce(ctx context.Context, p FindCon
 
 	t.logger.Debug("find_control_dependencies completed",
 		slog.String("tool", "find_control_dependencies"),
-		slog.String("target", p.Target),
+		telemetry.SensitiveLog("target", p.Target),
 		slog.Int("dependencies", len(filteredDeps)),
 	)
 
@@ -435,7 +442,7 @@ func (t *findControlDependenciesTool) resolveTarget(ctx context.Context, name st
 		// Fall back to using name as ID when index is unavailable
 		t.logger.Debug("symbol index unavailable, using name as ID fallback",
 			slog.String("tool", "find_control_dependencies"),
-			slog.String("name", name),
+			telemetry.SensitiveLog("name", name),
 		)
 		return name, nil
 	}
@@ -455,8 +462,8 @@ func (t *findControlDependenciesTool) resolveTarget(ctx context.Context, name st
 	if fuzzy {
 		t.logger.Info("P1: Using fuzzy match for target",
 			slog.String("tool", "find_control_dependencies"),
-			slog.String("query", name),
-			slog.String("matched", symbol.Name),
+			telemetry.SensitiveLog("query", name),
+			telemetry.SensitiveLog("matched", symbol.Name),
 		)
 	}
 
@@ -19,11 +19,11 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -161,6 +161,7 @@ func (t *findCriticalPathTool) Definition() ToolDefinition {
 		Priority:    86,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     30 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -176,6 +177,10 @@ func (t *findCriticalPathTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findCriticalPathTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindCriticalPathOutput{})
+}
+
 // Execute runs the find_critical_path tool.
 func (t *findCriticalPathTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	// Parse and validate parameters
@@ -232,15 +237,15 @@ func (t *findCriticalPathTool) executeOnce(ctx context.Context, p FindCriticalPa
 	ctx, span := findCriticalPathTracer.Start(ctx, "findCriticalPathTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_critical_path"),
-			attribute.String("target", p.Target),
-			attribute.String("entry", p.Entry),
+			telemetry.SensitiveString("target", p.Target),
+			telemetry.SensitiveString("entry", p.Entry),
 		),
 	)
 	defer span.End()
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -249,7 +254,7 @@ func (t *findCriticalPathTool) executeOnce(ctx context.Context, p FindCriticalPa
 	if entry == "" {
 		detected, err := DetectEntryPoint(ctx, t.index, t.analytics)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			return &Result{
 				Success: false,
 				Error:   fmt.Sprintf("failed to detect entry point: %v", err),
@@ -260,15 +265,15 @@ func (t *findCriticalPathTool) executeOnce(ctx context.Context, p FindCriticalPa
 		// valid entry points. We trust its output without re-validating.
 		t.logger.Debug("auto-detected entry point",
 			slog.String("tool", "find_critical_path"),
-			slog.String("entry", entry),
+			telemetry.SensitiveLog("entry", entry),
 		)
 	} else {
 		// Validate manually provided entry point exists
 		if t.index != nil {
 			if _, exists := t.index.GetByID(entry); !exists {
 				err := fmt.Errorf("entry point %q not found in graph", entry)
-				span.RecordError(err)
-				span.SetStatus(codes.Error, err.Error())
+				telemetry.RecordException(span, err)
+				telemetry.SetErrorStatus(span, err)
 				return &Result{
 					Success: false,
 					Error:   err.Error(),
@@ -277,15 +282,15 @@ func (t *findCriticalPathTool) executeOnce(ctx context.Context, p FindCriticalPa
 		}
 	}
 
-	span.SetAttributes(attribute.String("entry_resolved", entry))
+	span.SetAttributes(telemetry.SensitiveString("entry_resolved", entry))
 
 	// Resolve target to full ID (supports both "D" and "pkg/d.go:10:D")
 	targetID := p.Target
 	if t.index != nil {
 		resolvedTarget, err := t.resolveSymbol(p.Target)
 		if err != nil {
-			span.RecordError(err)
-			span.SetStatus(codes.Error, err.Error())
+			telemetry.RecordException(span, err)
+			telemetry.SetErrorStatus(span, err)
 			return &Result{
 				Success: false,
 				Error:   err.Error(),
@@ -293,11 +298,11 @@ func (t *findCriticalPathTool) executeOnce(ctx context.Context, p FindCriticalPa
 		}
 		targetID = resolvedTarget
 	}
-	span.SetAttributes(attribute.String("target_resolved", targetID))
+	span.SetAttributes(telemetry.SensitiveString("target_resolved", targetID))
 
 	// Check context again before dominator computation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -305,8 +310,8 @@ func (t *findCriticalPathTool) executeOnce(ctx context.Context, p FindCriticalPa
 	domTree, traceStep := t.analytics.DominatorsWithCRS(ctx, entry)
 	if domTree == nil {
 		err := fmt.Errorf("failed to compute dominator tree from entry %q", entry)
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return &Result{
 			Success: false,
 			Error:   err.Error(),
@@ -316,8 +321,8 @@ func (t *findCriticalPathTool) executeOnce(ctx context.Context, p FindCriticalPa
 	// Extract critical path (dominator chain)
 	criticalPath, err := t.extractCriticalPath(entry, targetID, domTree)
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return &Result{
 			Success: false,
 			Error:   err.Error(),
@@ -353,8 +358,8 @@ func (t *findCriticalPathTool) executeOnce(ctx context.Context, p FindCriticalPa
 
 		t.logger.Info("target unreachable",
 			slog.String("tool", "find_critical_path"),
-			slog.String("target", p.Target),
-			slog.String("entry", entry),
+			telemetry.SensitiveLog("target", p.Target),
+			telemetry.SensitiveLog("entry", entry),
 		)
 
 		return &Result{
@@ -392,8 +397,8 @@ func (t *findCriticalPathTool) executeOnce(ctx context.Context, p FindCriticalPa
 	// Log completion for production debugging
 	t.logger.Debug("find_critical_path completed",
 		slog.String("tool", "find_critical_path"),
-		slog.String("target", targetID),
-		slog.String("entry", entry),
+		telemetry.SensitiveLog("target", targetID),
+		telemetry.SensitiveLog("entry", entry),
 		slog.Int("path_length", len(criticalPath)),
 	)
 
@@ -556,7 +561,7 @@ func (t *findCriticalPathTool) resolveSymbol(nameOrID string) (string, error) {
 	if len(symbols) > 1 {
 		// Multiple matches - use first one (could enhance this later)
 		t.logger.Debug("multiple symbols found for name, using first",
-			slog.String("name", nameOrID),
+			telemetry.SensitiveLog("name", nameOrID),
 			slog.Int("count", len(symbols)),
 			slog.String("selected", symbols[0].ID),
 		)
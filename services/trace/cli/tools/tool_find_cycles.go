@@ -24,6 +24,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -194,10 +195,15 @@ func (t *findCyclesTool) Definition() ToolDefinition {
 		Priority:    82,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     15 * time.Second,
 	}
 }
 
+func (t *findCyclesTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindCyclesOutput{})
+}
+
 // Execute runs the find_cycles tool.
 func (t *findCyclesTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -225,7 +231,7 @@ func (t *findCyclesTool) Execute(ctx context.Context, params TypedParams) (*Resu
 			attribute.String("tool", "find_cycles"),
 			attribute.Int("min_size", p.MinSize),
 			attribute.Int("limit", p.Limit),
-			attribute.String("package_filter", p.PackageFilter),
+			telemetry.SensitiveString("package_filter", p.PackageFilter),
 			attribute.String("sort_by", p.SortBy),
 		),
 	)
@@ -233,7 +239,7 @@ func (t *findCyclesTool) Execute(ctx context.Context, params TypedParams) (*Resu
 
 	// Check context cancellation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
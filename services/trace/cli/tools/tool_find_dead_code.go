@@ -24,6 +24,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -182,6 +183,7 @@ func (t *findDeadCodeTool) Definition() ToolDefinition {
 		Priority:    84,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     10 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -202,6 +204,10 @@ func (t *findDeadCodeTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findDeadCodeTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindDeadCodeOutput{})
+}
+
 // Execute runs the find_dead_code tool.
 func (t *findDeadCodeTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -228,7 +234,7 @@ func (t *findDeadCodeTool) Execute(ctx context.Context, params TypedParams) (*Re
 		trace.WithAttributes(
 			attribute.String("tool", "find_dead_code"),
 			attribute.Bool("include_exported", p.IncludeExported),
-			attribute.String("package_filter", p.Package),
+			telemetry.SensitiveString("package_filter", p.Package),
 			attribute.Int("limit", p.Limit),
 			attribute.Bool("exclude_tests", p.ExcludeTests),
 		),
@@ -237,7 +243,7 @@ func (t *findDeadCodeTool) Execute(ctx context.Context, params TypedParams) (*Re
 
 	// Check context cancellation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -278,7 +284,7 @@ func (t *findDeadCodeTool) Execute(ctx context.Context, params TypedParams) (*Re
 		if len(pkgScoped) == 0 {
 			t.logger.Info("CR-11: package filter returned 0 results, returning empty (no fallback)",
 				slog.String("tool", "find_dead_code"),
-				slog.String("package_filter", p.Package),
+				telemetry.SensitiveLog("package_filter", p.Package),
 				slog.Int("raw_count", len(deadCode)),
 			)
 		}
@@ -301,7 +307,7 @@ func (t *findDeadCodeTool) Execute(ctx context.Context, params TypedParams) (*Re
 	if p.Package != "" && len(filtered) == 0 && len(pkgScoped) > 0 && !p.IncludeExported {
 		t.logger.Debug("package scope fallback: including exported symbols in scoped results",
 			slog.String("tool", "find_dead_code"),
-			slog.String("package_filter", p.Package),
+			telemetry.SensitiveLog("package_filter", p.Package),
 			slog.Int("scoped_count", len(pkgScoped)),
 		)
 		filtered = pkgScoped
@@ -345,7 +351,7 @@ func (t *findDeadCodeTool) Execute(ctx context.Context, params TypedParams) (*Re
 			slog.String("tool", "find_dead_code"),
 			slog.Int("raw_count", len(deadCode)),
 			slog.Bool("include_exported", p.IncludeExported),
-			slog.String("package_filter", p.Package),
+			telemetry.SensitiveLog("package_filter", p.Package),
 			slog.Bool("exclude_tests", p.ExcludeTests),
 		)
 	} else if len(filtered) >= p.Limit {
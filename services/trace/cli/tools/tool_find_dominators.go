@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -188,6 +189,7 @@ func (t *findDominatorsTool) Definition() ToolDefinition {
 		Priority:    85,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     30 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -203,6 +205,10 @@ func (t *findDominatorsTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findDominatorsTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindDominatorsOutput{})
+}
+
 // Execute runs the find_dominators tool.
 func (t *findDominatorsTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	// Parse and validate parameters
@@ -259,8 +265,8 @@ func (t *findDominatorsTool) executeOnce(ctx context.Context, p *FindDominatorsP
 	ctx, span := findDominatorsTracer.Start(ctx, "findDominatorsTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_dominators"),
-			attribute.String("target", p.Target),
-			attribute.String("entry", p.Entry),
+			telemetry.SensitiveString("target", p.Target),
+			telemetry.SensitiveString("entry", p.Entry),
 			attribute.Bool("show_tree", p.ShowTree),
 		),
 	)
@@ -268,7 +274,7 @@ func (t *findDominatorsTool) executeOnce(ctx context.Context, p *FindDominatorsP
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -279,7 +285,7 @@ func (t *findDominatorsTool) executeOnce(ctx context.Context, p *FindDominatorsP
 		var detectErr error
 		entry, detectErr = DetectEntryPoint(ctx, t.index, t.analytics)
 		if detectErr != nil {
-			span.RecordError(detectErr)
+			telemetry.RecordException(span, detectErr)
 			return &Result{
 				Success: false,
 				Error:   fmt.Sprintf("failed to detect entry point: %v", detectErr),
@@ -288,20 +294,20 @@ func (t *findDominatorsTool) executeOnce(ctx context.Context, p *FindDominatorsP
 		entryAutoDetected = true
 	}
 
-	span.SetAttributes(attribute.String("resolved_entry", entry))
+	span.SetAttributes(telemetry.SensitiveString("resolved_entry", entry))
 	span.SetAttributes(attribute.Bool("entry_auto_detected", entryAutoDetected))
 
 	// Resolve target
 	targetID, err := t.resolveTarget(ctx, p.Target)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("target resolution failed: %v", err),
 		}, nil
 	}
 
-	span.SetAttributes(attribute.String("resolved_target", targetID))
+	span.SetAttributes(telemetry.SensitiveString("resolved_target", targetID))
 
 	// Compute dominator tree
 	domTree, traceStep := t.analytics.DominatorsWithCRS(ctx, entry)
@@ -391,7 +397,7 @@ func (t *findDominatorsTool) executeOnce(ctx context.Context, p *FindDominatorsP
 	span.SetAttributes(
 		attribute.Int("dominators_count", len(dominators)),
 		attribute.Int("depth", domTree.Depth[targetID]),
-		attribute.String("immediate_dominator", immediateDom),
+		telemetry.SensitiveString("immediate_dominator", immediateDom),
 	)
 
 	// Create tool-level trace step
@@ -409,7 +415,7 @@ func (t *findDominatorsTool) executeOnce(ctx context.Context, p *FindDominatorsP
 
 	t.logger.Debug("find_dominators completed",
 		slog.String("tool", "find_dominators"),
-		slog.String("target", targetID),
+		telemetry.SensitiveLog("target", targetID),
 		slog.Int("dominators_count", len(dominators)),
 		slog.Int("depth", domTree.Depth[targetID]),
 	)
@@ -464,7 +470,7 @@ func (t *findDominatorsTool) resolveTarget(ctx context.Context, name string) (st
 		// Fall back to using name as ID when index is unavailable
 		t.logger.Debug("symbol index unavailable, using name as ID fallback",
 			slog.String("tool", "find_dominators"),
-			slog.String("name", name),
+			telemetry.SensitiveLog("name", name),
 		)
 		return name, nil
 	}
@@ -488,8 +494,8 @@ func (t *findDominatorsTool) resolveTarget(ctx context.Context, name string) (st
 	if fuzzy {
 		t.logger.Info("P1: Using fuzzy match for target",
 			slog.String("tool", "find_dominators"),
-			slog.String("query", name),
-			slog.String("matched", symbol.Name),
+			telemetry.SensitiveLog("query", name),
+			telemetry.SensitiveLog("matched", symbol.Name),
 		)
 	}
 
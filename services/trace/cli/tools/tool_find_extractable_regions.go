@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -199,6 +200,7 @@ func (t *findExtractableRegionsTool) Definition() ToolDefinition {
 		Priority:    80,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     60 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -214,6 +216,10 @@ func (t *findExtractableRegionsTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findExtractableRegionsTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindExtractableRegionsOutput{})
+}
+
 // Execute runs the find_extractable_regions tool.
 func (t *findExtractableRegionsTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	// Parse and validate parameters
@@ -279,26 +285,26 @@ func (t *findExtractableRegionsTool) executeOnce(ctx context.Context, p FindExtr
 
 	// Check context cancellation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
 	// Detect entry point for dominator tree
 	entry, err := DetectEntryPoint(ctx, t.index, t.analytics)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to detect entry point: %v", err),
 		}, nil
 	}
 
-	span.SetAttributes(attribute.String("entry", entry))
+	span.SetAttributes(telemetry.SensitiveString("entry", entry))
 
 	// Compute dominator tree
 	domTree, err := t.analytics.Dominators(ctx, entry)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to compute dominators: %v", err),
@@ -308,10 +314,10 @@ func (t *findExtractableRegionsTool) executeOnce(ctx context.Context, p FindExtr
 	// Compute post-dominator tree
 	postDomTree, err := t.analytics.PostDominators(ctx, "")
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		t.logger.Debug("failed to compute post-dominators",
 			slog.String("tool", "find_extractable_regions"),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		// Continue with empty post-dominator tree
 		postDomTree = nil
@@ -319,7 +325,7 @@ func (t *findExtractableRegionsTool) executeOnce(ctx context.Context, p FindExtr
 
 	// Check context again
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -26,6 +26,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -222,6 +223,7 @@ func (t *findHotspotsTool) Definition() ToolDefinition {
 		Priority:    86,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     5 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -238,6 +240,10 @@ func (t *findHotspotsTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findHotspotsTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindHotspotsOutput{})
+}
+
 // Execute runs the find_hotspots tool.
 func (t *findHotspotsTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -265,7 +271,7 @@ func (t *findHotspotsTool) Execute(ctx context.Context, params TypedParams) (*Re
 			attribute.String("tool", "find_hotspots"),
 			attribute.Int("top", p.Top),
 			attribute.String("kind", p.Kind),
-			attribute.String("package_filter", p.Package),
+			telemetry.SensitiveString("package_filter", p.Package),
 			attribute.Bool("exclude_tests", p.ExcludeTests),
 			attribute.String("sort_by", p.SortBy),
 		),
@@ -274,7 +280,7 @@ func (t *findHotspotsTool) Execute(ctx context.Context, params TypedParams) (*Re
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -288,7 +294,7 @@ func (t *findHotspotsTool) Execute(ctx context.Context, params TypedParams) (*Re
 		t.logger.Debug("hotspot request adjusted for filtering",
 			slog.String("tool", "find_hotspots"),
 			slog.String("kind_filter", p.Kind),
-			slog.String("package_filter", p.Package),
+			telemetry.SensitiveLog("package_filter", p.Package),
 			slog.Bool("exclude_tests", p.ExcludeTests),
 			slog.Int("top_requested", p.Top),
 			slog.Int("request_count", requestCount),
@@ -370,7 +376,7 @@ func (t *findHotspotsTool) Execute(ctx context.Context, params TypedParams) (*Re
 
 		preScopeCount := len(filtered)
 		t.logger.Info("IT-07: find_hotspots package filter applied",
-			slog.String("package", p.Package),
+			telemetry.SensitiveLog("package", p.Package),
 			slog.Int("before", preScopeCount),
 			slog.Int("after", len(pkgFiltered)),
 		)
@@ -442,7 +448,7 @@ func (t *findHotspotsTool) Execute(ctx context.Context, params TypedParams) (*Re
 			slog.String("tool", "find_hotspots"),
 			slog.Int("raw_count", len(hotspots)),
 			slog.String("kind_filter", p.Kind),
-			slog.String("package_filter", p.Package),
+			telemetry.SensitiveLog("package_filter", p.Package),
 		)
 	} else if len(filtered) < p.Top && (p.Kind != "all" || p.Package != "") {
 		t.logger.Debug("fewer hotspots than requested after filtering",
@@ -450,7 +456,7 @@ func (t *findHotspotsTool) Execute(ctx context.Context, params TypedParams) (*Re
 			slog.Int("requested", p.Top),
 			slog.Int("returned", len(filtered)),
 			slog.String("kind_filter", p.Kind),
-			slog.String("package_filter", p.Package),
+			telemetry.SensitiveLog("package_filter", p.Package),
 		)
 	}
 
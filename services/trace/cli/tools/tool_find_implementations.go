@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -45,6 +46,12 @@ type FindImplementationsParams struct {
 	// PackageHint is an optional package/module context extracted from the query.
 	// IT-06c: Used to disambiguate when multiple types share the same name.
 	PackageHint string
+
+	// Cursor is the next_cursor of the previous page (optional).
+	Cursor string
+
+	// offset is the position Cursor resumes at, per matched interface.
+	offset int
 }
 
 // ToolName returns the tool name for TypedParams interface.
@@ -59,9 +66,17 @@ func (p FindImplementationsParams) ToMap() map[string]any {
 	if p.PackageHint != "" {
 		m["package_hint"] = p.PackageHint
 	}
+	if p.Cursor != "" {
+		m["cursor"] = p.Cursor
+	}
 	return m
 }
 
+// cursorQuery identifies the query in cursors.
+func (p FindImplementationsParams) cursorQuery() []string {
+	return []string{"find_implementations", p.InterfaceName, p.PackageHint}
+}
+
 // FindImplementationsOutput contains the structured result.
 type FindImplementationsOutput struct {
 	// InterfaceName is the interface that was searched for.
@@ -75,6 +90,8 @@ type FindImplementationsOutput struct {
 
 	// Results contains the implementations grouped by interface ID.
 	Results []ImplementationResult `json:"results"`
+
+	PageInfo
 }
 
 // ImplementationResult represents implementations for a specific interface.
@@ -176,7 +193,7 @@ func (t *findImplementationsTool) Definition() ToolDefinition {
 		Name: "find_implementations",
 		Description: "Find all types that implement a given interface or extend a given class. " +
 			"Works across languages: Go interfaces (structural typing), Python class inheritance and ABCs, " +
-			"JS/TS class extends and implements. " +
+			"JS/TS class extends and implements, and protobuf services and rpcs (the Go types and methods serving them). " +
 			"Use for 'what implements X?', 'what extends X?', 'what subclasses X?', 'what types derive from X?'.",
 		Parameters: map[string]ParamDef{
 			"interface_name": {
@@ -186,19 +203,25 @@ func (t *findImplementationsTool) Definition() ToolDefinition {
 			},
 			"limit": {
 				Type:        ParamTypeInt,
-				Description: "Maximum number of implementations to return",
+				Description: "Maximum number of implementations to return per page",
 				Required:    false,
 				Default:     50,
 			},
+			"cursor": cursorParamDef,
 		},
 		Category:    CategoryExploration,
 		Priority:    93,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     5 * time.Second,
 	}
 }
 
+func (t *findImplementationsTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindImplementationsOutput{})
+}
+
 // Execute runs the find_implementations tool.
 func (t *findImplementationsTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -224,7 +247,7 @@ func (t *findImplementationsTool) Execute(ctx context.Context, params TypedParam
 	ctx, span := findImplementationsTracer.Start(ctx, "findImplementationsTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_implementations"),
-			attribute.String("interface_name", p.InterfaceName),
+			telemetry.SensitiveString("interface_name", p.InterfaceName),
 			attribute.Int("limit", p.Limit),
 			attribute.Bool("index_available", t.index != nil),
 		),
@@ -255,16 +278,16 @@ func (t *findImplementationsTool) Execute(ctx context.Context, params TypedParam
 		// IT-03 C-3a: Accept interfaces, classes, and structs as valid targets.
 		// Go uses interfaces (SymbolKindInterface), Python/JS/TS use classes
 		// (SymbolKindClass), and Go structs (SymbolKindStruct) can be embedding targets.
+		// Proto rpcs are targets too: Go methods implement them.
 		var interfaces []*ast.Symbol
 		var filtered int
 		for _, sym := range symbols {
 			if sym == nil {
 				continue
 			}
-			switch sym.Kind {
-			case ast.SymbolKindInterface, ast.SymbolKindClass, ast.SymbolKindStruct:
+			if graph.IsImplementationTarget(sym) {
 				interfaces = append(interfaces, sym)
-			default:
+			} else {
 				filtered++
 			}
 		}
@@ -272,7 +295,7 @@ func (t *findImplementationsTool) Execute(ctx context.Context, params TypedParam
 		if filtered > 0 {
 			t.logger.Debug("filtered non-type symbols",
 				slog.String("tool", "find_implementations"),
-				slog.String("interface_name", p.InterfaceName),
+				telemetry.SensitiveLog("interface_name", p.InterfaceName),
 				slog.Int("filtered_count", filtered),
 				slog.Int("types_found", len(interfaces)),
 			)
@@ -289,17 +312,17 @@ func (t *findImplementationsTool) Execute(ctx context.Context, params TypedParam
 			results = make(map[string]*graph.QueryResult, len(interfaces))
 			for _, sym := range interfaces {
 				if err := ctx.Err(); err != nil {
-					span.RecordError(err)
+					telemetry.RecordException(span, err)
 					return nil, err
 				}
-				result, qErr := t.graph.FindImplementationsByID(ctx, sym.ID, graph.WithLimit(p.Limit))
+				result, qErr := t.graph.FindImplementationsByID(ctx, sym.ID, graph.WithLimit(p.Limit), graph.WithOffset(p.offset))
 				if qErr != nil {
 					queryErrors++
 					t.logger.Warn("graph query failed",
 						slog.String("tool", "find_implementations"),
 						slog.String("operation", "FindImplementationsByID"),
-						slog.String("symbol_id", sym.ID),
-						slog.String("error", qErr.Error()),
+						telemetry.SensitiveLog("symbol_id", sym.ID),
+						telemetry.SensitiveLogError("error", qErr),
 					)
 					continue
 				}
@@ -316,13 +339,13 @@ func (t *findImplementationsTool) Execute(ctx context.Context, params TypedParam
 		t.logger.Warn("graph query fallback",
 			slog.String("tool", "find_implementations"),
 			slog.String("reason", "index_unavailable"),
-			slog.String("interface_name", p.InterfaceName),
+			telemetry.SensitiveLog("interface_name", p.InterfaceName),
 		)
 		span.SetAttributes(attribute.Bool("index_used", false))
 		var gErr error
-		results, gErr = t.graph.FindImplementationsByName(ctx, p.InterfaceName, graph.WithLimit(p.Limit))
+		results, gErr = t.graph.FindImplementationsByName(ctx, p.InterfaceName, graph.WithLimit(p.Limit), graph.WithOffset(p.offset))
 		if gErr != nil {
-			span.RecordError(gErr)
+			telemetry.RecordException(span, gErr)
 			errStep := crs.NewTraceStepBuilder().
 				WithAction("tool_find_implementations").
 				WithTarget(p.InterfaceName).
@@ -346,6 +369,16 @@ func (t *findImplementationsTool) Execute(ctx context.Context, params TypedParam
 	// CR-20-7: Pass pre-fetched symbols to avoid redundant index lookup on "not found" path.
 	outputText := t.formatText(p.InterfaceName, results, allIndexSymbols)
 
+	truncated := false
+	for _, qr := range results {
+		if qr != nil {
+			truncated = truncated || qr.Truncated
+			output.TotalAvailable += qr.Total
+		}
+	}
+	output.NextCursor = nextToolCursor(t.graph, truncated, p.offset, p.Limit, p.cursorQuery()...)
+	outputText = withPageNote(outputText, "find_implementations", p.offset, output.TotalImplementations, output.PageInfo)
+
 	span.SetAttributes(
 		attribute.Int("interface_count", len(results)),
 		attribute.Int("total_implementations", output.TotalImplementations),
@@ -363,6 +396,7 @@ func (t *findImplementationsTool) Execute(ctx context.Context, params TypedParam
 		WithMetadata("total_implementations", fmt.Sprintf("%d", output.TotalImplementations)).
 		WithMetadata("index_used", fmt.Sprintf("%v", t.index != nil)).
 		WithMetadata("resolution_strategy", strategy).
+		WithMetadata("total_available", fmt.Sprintf("%d", output.TotalAvailable)).
 		Build()
 
 	return &Result{
@@ -373,6 +407,7 @@ func (t *findImplementationsTool) Execute(ctx context.Context, params TypedParam
 		TraceStep:   &toolStep,
 		Duration:    duration,
 		ResultCount: output.MatchCount,
+		NextCursor:  output.NextCursor,
 	}, nil
 }
 
@@ -415,6 +450,13 @@ func (t *findImplementationsTool) parseParams(params map[string]any) (FindImplem
 		}
 	}
 
+	// Extract cursor (optional)
+	p.Cursor = parseCursorParam(params)
+	var err error
+	if p.offset, err = decodeToolCursor(t.graph, p.Cursor, p.cursorQuery()...); err != nil {
+		return p, err
+	}
+
 	return p, nil
 }
 
@@ -536,7 +578,7 @@ func (t *findImplementationsTool) formatText(interfaceName string, results map[s
 	// The graph already analyzed every source file — these results are exhaustive.
 	// Without this footer, the LLM tries to verify via Grep/Read, triggering CB fires.
 	sb.WriteString("---\n")
-	sb.WriteString("The graph has been fully indexed — these results are exhaustive.\n")
+	sb.WriteString(exhaustiveResultsLine)
 	sb.WriteString("**Do NOT use Grep or Read to verify** — the graph already analyzed all source files.\n")
 
 	return sb.String()
@@ -253,6 +253,76 @@ func TestFindImplementationsTool_ClassInheritance(t *testing.T) {
 	}
 }
 
+// TestFindImplementationsTool_ProtoRPC tests that a protobuf rpc is a valid
+// target and returns the Go method implementing it, while a Go method of
+// the same name is still filtered.
+func TestFindImplementationsTool_ProtoRPC(t *testing.T) {
+	ctx := context.Background()
+	g := graph.NewGraph("/test")
+	idx := index.NewSymbolIndex()
+
+	rpc := &ast.Symbol{
+		ID:        "api/users.proto:9:UserService.UpdateUser",
+		Name:      "UpdateUser",
+		Kind:      ast.SymbolKindMethod,
+		FilePath:  "api/users.proto",
+		StartLine: 9,
+		EndLine:   9,
+		Package:   "acme.users.v1",
+		Language:  "proto",
+		Receiver:  "UserService",
+	}
+
+	goMethod := &ast.Symbol{
+		ID:        "server/server.go:12:UpdateUser",
+		Name:      "UpdateUser",
+		Kind:      ast.SymbolKindMethod,
+		FilePath:  "server/server.go",
+		StartLine: 12,
+		EndLine:   20,
+		Package:   "server",
+		Language:  "go",
+		Receiver:  "Server",
+	}
+
+	g.AddNode(rpc)
+	g.AddNode(goMethod)
+	_ = idx.Add(rpc)
+	_ = idx.Add(goMethod)
+	g.AddEdge(goMethod.ID, rpc.ID, graph.EdgeTypeImplements, ast.Location{
+		FilePath: goMethod.FilePath, StartLine: goMethod.StartLine,
+	})
+	g.Freeze()
+
+	tool := NewFindImplementationsTool(g, idx)
+
+	result, err := tool.Execute(ctx, MapParams{Params: map[string]any{
+		"interface_name": "UpdateUser",
+	}})
+
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("Execute() failed: %s", result.Error)
+	}
+
+	output, ok := result.Output.(FindImplementationsOutput)
+	if !ok {
+		t.Fatalf("Output is not FindImplementationsOutput, got %T", result.Output)
+	}
+
+	if output.MatchCount != 1 {
+		t.Errorf("Expected 1 match (the rpc), got %d", output.MatchCount)
+	}
+	if output.TotalImplementations != 1 {
+		t.Fatalf("Expected 1 implementation, got %d", output.TotalImplementations)
+	}
+	if impl := output.Results[0].Implementations[0]; impl.Name != "UpdateUser" || impl.File != "server/server.go" {
+		t.Errorf("Expected server/server.go UpdateUser, got %+v", impl)
+	}
+}
+
 // TestFindImplementationsTool_MixedEdgeTypes tests that both EdgeTypeImplements
 // and EdgeTypeEmbeds edges are returned without duplicates (IT-03 C-3c).
 func TestFindImplementationsTool_MixedEdgeTypes(t *testing.T) {
@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -208,10 +209,15 @@ func (t *findImportantTool) Definition() ToolDefinition {
 		Priority:    89,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     30 * time.Second,
 	}
 }
 
+func (t *findImportantTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindImportantOutput{})
+}
+
 // Execute runs the find_important tool.
 func (t *findImportantTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -239,7 +245,7 @@ func (t *findImportantTool) Execute(ctx context.Context, params TypedParams) (*R
 			attribute.String("tool", "find_important"),
 			attribute.Int("top", p.Top),
 			attribute.String("kind", p.Kind),
-			attribute.String("package", p.Package),
+			telemetry.SensitiveString("package", p.Package),
 			attribute.Bool("exclude_tests", p.ExcludeTests),
 			attribute.Bool("reverse", p.Reverse),
 		),
@@ -248,7 +254,7 @@ func (t *findImportantTool) Execute(ctx context.Context, params TypedParams) (*R
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -301,8 +307,8 @@ func (t *findImportantTool) Execute(ctx context.Context, params TypedParams) (*R
 				// GR-60c: Debug log for classification decisions on top results
 				if filteredCount <= 10 {
 					t.logger.Info("GR-60c: filtered non-production file from find_important",
-						slog.String("file", filePath),
-						slog.String("symbol", prn.Node.Symbol.Name),
+						telemetry.SensitiveLog("file", filePath),
+						telemetry.SensitiveLog("symbol", prn.Node.Symbol.Name),
 						slog.Float64("pagerank", prn.Score),
 					)
 				}
@@ -359,7 +365,7 @@ func (t *findImportantTool) Execute(ctx context.Context, params TypedParams) (*R
 			}
 		}
 		t.logger.Info("IT-07: find_important package filter applied",
-			slog.String("package", p.Package),
+			telemetry.SensitiveLog("package", p.Package),
 			slog.Int("before", preScopeCount),
 			slog.Int("after", len(packageFiltered)),
 		)
@@ -0,0 +1,405 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// =============================================================================
+// find_literal_usages Tool
+// =============================================================================
+
+var findLiteralUsagesTracer = otel.Tracer("tools.find_literal_usages")
+
+const (
+	// defaultLiteralUsagesLimit is the number of usages returned when no
+	// limit is given.
+	defaultLiteralUsagesLimit = 50
+
+	// maxLiteralUsagesLimit caps the limit parameter.
+	maxLiteralUsagesLimit = 200
+)
+
+// FindLiteralUsagesParams contains the validated input parameters.
+type FindLiteralUsagesParams struct {
+	// Value is the literal to search for. Required.
+	Value string
+
+	// Substring matches string literals containing Value.
+	Substring bool
+
+	// Kind restricts matches to "string", "number" or "bool". Optional.
+	Kind string
+
+	// Limit is the maximum number of usages returned.
+	// Default: 50, Max: 200
+	Limit int
+}
+
+// ToolName returns the tool name for TypedParams interface.
+func (p FindLiteralUsagesParams) ToolName() string { return "find_literal_usages" }
+
+// ToMap converts typed parameters to the map consumed by Tool.Execute().
+func (p FindLiteralUsagesParams) ToMap() map[string]any {
+	m := map[string]any{"value": p.Value}
+	if p.Substring {
+		m["substring"] = true
+	}
+	if p.Kind != "" {
+		m["kind"] = p.Kind
+	}
+	if p.Limit > 0 {
+		m["limit"] = p.Limit
+	}
+	return m
+}
+
+// FindLiteralUsagesOutput contains the structured result.
+type FindLiteralUsagesOutput struct {
+	// Value is the literal searched for.
+	Value string `json:"value"`
+
+	// Substring is true if string literals were matched by containment.
+	Substring bool `json:"substring,omitempty"`
+
+	// Usages are the matching call sites in file order.
+	Usages []LiteralUsageInfo `json:"usages"`
+
+	// Truncated is true if more usages matched than Limit.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// IndexedLiterals is the number of literals the graph indexed. Zero
+	// means literal capture was not enabled when the graph was built.
+	IndexedLiterals int `json:"indexed_literals"`
+
+	// DroppedLiterals is the number of literals left out of the index by
+	// its cap; usages among them cannot be found.
+	DroppedLiterals int `json:"dropped_literals,omitempty"`
+}
+
+// LiteralUsageInfo is one call site passing the literal.
+type LiteralUsageInfo struct {
+	// Value is the literal as captured.
+	Value string `json:"value"`
+
+	// Kind is "string", "number" or "bool".
+	Kind string `json:"kind"`
+
+	// ArgIndex is the position of the argument holding the literal.
+	ArgIndex int `json:"arg_index"`
+
+	// ArgName is the keyword or field name the literal is passed under.
+	ArgName string `json:"arg_name,omitempty"`
+
+	// Call is the called function, with its receiver for method calls.
+	Call string `json:"call"`
+
+	// FilePath and Line locate the literal.
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+
+	// SymbolID, SymbolName and SymbolKind identify the enclosing symbol.
+	SymbolID   string `json:"symbol_id"`
+	SymbolName string `json:"symbol_name,omitempty"`
+	SymbolKind string `json:"symbol_kind,omitempty"`
+}
+
+// findLiteralUsagesTool finds the call sites passing a literal value.
+//
+// Description:
+//
+//	Searches the call-site literal arguments indexed by the graph (see
+//	graph.Graph.FindLiteralUsages) and reports each call with its enclosing
+//	symbol. Answers "where do we set the timeout to 30?" or "which calls
+//	pass 'production' as the environment?".
+//
+// Thread Safety: Safe for concurrent use. All operations are read-only.
+type findLiteralUsagesTool struct {
+	graph  *graph.Graph
+	logger *slog.Logger
+}
+
+// NewFindLiteralUsagesTool creates the find_literal_usages tool.
+//
+// Inputs:
+//
+//   - g: The frozen code graph. Must not be nil.
+//
+// Outputs:
+//
+//   - Tool: The find_literal_usages tool implementation.
+//
+// Limitations:
+//
+//   - Literals are only indexed for languages parsed with the
+//     capture_literal_args parser option; otherwise only string arguments
+//     of calls that also pass a function are indexed.
+//   - Redacted literals are not indexed.
+func NewFindLiteralUsagesTool(g *graph.Graph) Tool {
+	return &findLiteralUsagesTool{
+		graph:  g,
+		logger: slog.Default(),
+	}
+}
+
+func (t *findLiteralUsagesTool) Name() string {
+	return "find_literal_usages"
+}
+
+func (t *findLiteralUsagesTool) Category() ToolCategory {
+	return CategoryExploration
+}
+
+func (t *findLiteralUsagesTool) Definition() ToolDefinition {
+	return findLiteralUsagesDefinition()
+}
+
+func (t *findLiteralUsagesTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindLiteralUsagesOutput{})
+}
+
+// findLiteralUsagesDefinition is shared by the tool and StaticToolDefinitions.
+func findLiteralUsagesDefinition() ToolDefinition {
+	return ToolDefinition{
+		Name: "find_literal_usages",
+		Description: "Find the call sites that pass a literal value (string, number or bool) as an argument, " +
+			"with the function or method containing each call. " +
+			"Use for 'where do we set the timeout to 30?', 'which calls pass \"production\"?'.",
+		Parameters: map[string]ParamDef{
+			"value": {
+				Type:        ParamTypeString,
+				Description: "Literal to search for, without quotes (e.g., '30', 'production', 'true').",
+				Required:    true,
+			},
+			"substring": {
+				Type:        ParamTypeBool,
+				Description: "Match string literals containing the value, ignoring case (default: false).",
+				Required:    false,
+				Default:     false,
+			},
+			"kind": {
+				Type:        ParamTypeString,
+				Description: "Only match literals of this kind.",
+				Required:    false,
+				Enum:        []any{"string", "number", "bool"},
+			},
+			"limit": {
+				Type:        ParamTypeInt,
+				Description: "Maximum number of usages to return (default: 50, max: 200)",
+				Required:    false,
+				Default:     defaultLiteralUsagesLimit,
+			},
+		},
+		Category:    CategoryExploration,
+		Priority:    80,
+		Requires:    []string{"graph_initialized"},
+		SideEffects: false,
+		Timeout:     5 * time.Second,
+		WhenToUse: WhenToUse{
+			Keywords: []string{
+				"set to", "passed as", "pass the value", "literal", "hardcoded",
+				"configured to", "which call sites pass", "where do we set",
+			},
+			UseWhen: "User asks where a specific value (a number, a quoted string, true/false) is " +
+				"passed to a call, e.g. 'where do we set the timeout to 30 seconds?'.",
+			AvoidWhen: "User wants the callers of a function — use find_callers. " +
+				"User wants a text search over all files — use Grep.",
+		},
+	}
+}
+
+// Execute runs the find_literal_usages tool.
+func (t *findLiteralUsagesTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
+	start := time.Now()
+
+	p, err := t.parseParams(params.ToMap())
+	if err != nil {
+		errStep := crs.NewTraceStepBuilder().
+			WithAction("tool_find_literal_usages").
+			WithTool("find_literal_usages").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return &Result{
+			Success:   false,
+			Error:     err.Error(),
+			TraceStep: &errStep,
+			Duration:  time.Since(start),
+		}, nil
+	}
+
+	_, span := findLiteralUsagesTracer.Start(ctx, "findLiteralUsagesTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "find_literal_usages"),
+			telemetry.SensitiveString("value", p.Value),
+			attribute.Bool("substring", p.Substring),
+			attribute.String("kind", p.Kind),
+			attribute.Int("limit", p.Limit),
+		),
+	)
+	defer span.End()
+
+	// Ask for one more than the limit to tell whether results were cut.
+	usages := t.graph.FindLiteralUsages(graph.LiteralQuery{
+		Value:     p.Value,
+		Substring: p.Substring,
+		Kind:      ast.LiteralKind(p.Kind),
+		Limit:     p.Limit + 1,
+	})
+	output := FindLiteralUsagesOutput{
+		Value:     p.Value,
+		Substring: p.Substring,
+		Usages:    make([]LiteralUsageInfo, 0, len(usages)),
+	}
+	output.IndexedLiterals, output.DroppedLiterals = t.graph.LiteralUsageCounts()
+	if len(usages) > p.Limit {
+		usages = usages[:p.Limit]
+		output.Truncated = true
+	}
+	for _, u := range usages {
+		info := LiteralUsageInfo{
+			Value:    u.Arg.Value,
+			Kind:     string(u.Arg.Kind),
+			ArgIndex: u.Arg.Index,
+			ArgName:  u.Arg.Name,
+			Call:     u.Target,
+			FilePath: u.Arg.Location.FilePath,
+			Line:     u.Arg.Location.StartLine,
+			SymbolID: u.SymbolID,
+		}
+		if u.Receiver != "" && !strings.HasPrefix(u.Target, u.Receiver+".") {
+			info.Call = u.Receiver + "." + u.Target
+		}
+		if node, ok := t.graph.GetNode(u.SymbolID); ok && node.Symbol != nil {
+			info.SymbolName = node.Symbol.Name
+			info.SymbolKind = node.Symbol.Kind.String()
+		}
+		output.Usages = append(output.Usages, info)
+	}
+	span.SetAttributes(attribute.Int("usages", len(output.Usages)))
+
+	outputText := formatLiteralUsagesText(output)
+
+	duration := time.Since(start)
+	toolStep := crs.NewTraceStepBuilder().
+		WithAction("tool_find_literal_usages").
+		WithTarget(p.Value).
+		WithTool("find_literal_usages").
+		WithDuration(duration).
+		WithMetadata("usage_count", fmt.Sprintf("%d", len(output.Usages))).
+		Build()
+
+	return &Result{
+		Success:     true,
+		Output:      output,
+		OutputText:  outputText,
+		TokensUsed:  estimateTokens(outputText),
+		TraceStep:   &toolStep,
+		Duration:    duration,
+		ResultCount: len(output.Usages),
+		Truncated:   output.Truncated,
+	}, nil
+}
+
+// formatLiteralUsagesText renders the output for the agent.
+func formatLiteralUsagesText(output FindLiteralUsagesOutput) string {
+	var sb strings.Builder
+	match := "exact"
+	if output.Substring {
+		match = "substring"
+	}
+	switch {
+	case output.IndexedLiterals == 0:
+		sb.WriteString("## GRAPH RESULT: No literals indexed\n\n")
+		sb.WriteString("The graph has no call-site literal arguments. Literal capture is enabled per " +
+			"language with the capture_literal_args parser option at init; use Grep to search the source instead.\n")
+	case len(output.Usages) == 0:
+		sb.WriteString(fmt.Sprintf("## GRAPH RESULT: No call passes %q (%s match)\n\n", output.Value, match))
+		sb.WriteString(fmt.Sprintf("None of the %d indexed literal arguments match. This is the definitive answer "+
+			"for call arguments; the value may still appear in assignments or declarations.\n", output.IndexedLiterals))
+	default:
+		sb.WriteString(fmt.Sprintf("## Calls passing %q (%d usages, %s match)\n\n", output.Value, len(output.Usages), match))
+		for _, u := range output.Usages {
+			arg := fmt.Sprintf("arg %d", u.ArgIndex)
+			if u.ArgName != "" {
+				arg = fmt.Sprintf("%s=%s", arg, u.ArgName)
+			}
+			enclosing := u.SymbolID
+			if u.SymbolName != "" {
+				enclosing = fmt.Sprintf("%s %s", u.SymbolKind, u.SymbolName)
+			}
+			sb.WriteString(fmt.Sprintf("- %s:%d `%s(%s: %q)` in %s\n", u.FilePath, u.Line, u.Call, arg, u.Value, enclosing))
+		}
+		if output.Truncated {
+			sb.WriteString("\nMore usages match; raise limit to see them.\n")
+		}
+	}
+	if output.DroppedLiterals > 0 {
+		sb.WriteString(fmt.Sprintf("\nNote: %d literals were left out of the index by its size cap.\n", output.DroppedLiterals))
+	}
+	return sb.String()
+}
+
+// parseParams validates and extracts typed parameters.
+func (t *findLiteralUsagesTool) parseParams(params map[string]any) (FindLiteralUsagesParams, error) {
+	p := FindLiteralUsagesParams{Limit: defaultLiteralUsagesLimit}
+
+	if raw, ok := params["value"]; ok {
+		switch v := raw.(type) {
+		case bool, int, int64, float64:
+			// Models sometimes send numbers and bools unquoted.
+			p.Value = fmt.Sprint(v)
+		default:
+			if s, ok := parseStringParam(raw); ok {
+				p.Value = s
+			}
+		}
+	}
+	if strings.TrimSpace(p.Value) == "" {
+		return p, fmt.Errorf("'value' parameter is required")
+	}
+	if raw, ok := params["substring"]; ok {
+		if b, ok := parseBoolParam(raw); ok {
+			p.Substring = b
+		}
+	}
+	if raw, ok := params["kind"]; ok {
+		if s, ok := parseStringParam(raw); ok {
+			switch kind := ast.LiteralKind(strings.ToLower(strings.TrimSpace(s))); kind {
+			case "":
+			case ast.LiteralKindString, ast.LiteralKindNumber, ast.LiteralKindBool:
+				p.Kind = string(kind)
+			default:
+				return p, fmt.Errorf("'kind' must be one of string, number, bool; got %q", s)
+			}
+		}
+	}
+	if raw, ok := params["limit"]; ok {
+		if limit, ok := parseIntParam(raw); ok && limit > 0 {
+			p.Limit = min(limit, maxLiteralUsagesLimit)
+		}
+	}
+	return p, nil
+}
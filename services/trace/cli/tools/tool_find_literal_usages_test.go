@@ -0,0 +1,127 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// newLiteralUsagesGraph returns a frozen graph whose functions pass
+// literals to their calls.
+func newLiteralUsagesGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	g := graph.NewGraph("/project")
+	at := func(line int) ast.Location { return ast.Location{FilePath: "server.go", StartLine: line} }
+	symbols := []*ast.Symbol{
+		{
+			ID: "server.go:10:NewServer", Name: "NewServer", Kind: ast.SymbolKindFunction,
+			FilePath: "server.go", StartLine: 10, EndLine: 20, Language: "go",
+			Calls: []ast.CallSite{{
+				Target: "WithTimeout", Location: at(12),
+				LiteralArgs: []ast.LiteralArg{{Value: "30", Kind: ast.LiteralKindNumber, Location: at(12)}},
+			}},
+		},
+		{
+			ID: "server.go:30:Start", Name: "Start", Kind: ast.SymbolKindMethod,
+			FilePath: "server.go", StartLine: 30, EndLine: 40, Language: "go",
+			Calls: []ast.CallSite{{
+				Target: "Dial", Receiver: "client", IsMethod: true, Location: at(33),
+				LiteralArgs: []ast.LiteralArg{
+					{Value: "production", Kind: ast.LiteralKindString, Location: at(33)},
+					{Value: "30", Kind: ast.LiteralKindNumber, Index: 1, Name: "Timeout", Location: at(33)},
+				},
+			}},
+		},
+	}
+	for _, sym := range symbols {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+func TestFindLiteralUsagesTool_Execute(t *testing.T) {
+	ctx := context.Background()
+	tool := NewFindLiteralUsagesTool(newLiteralUsagesGraph(t))
+
+	t.Run("returns call sites with their enclosing symbols", func(t *testing.T) {
+		result, err := tool.Execute(ctx, FindLiteralUsagesParams{Value: "30"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Execute() failed: %s", result.Error)
+		}
+		output := result.Output.(FindLiteralUsagesOutput)
+		if len(output.Usages) != 2 || output.IndexedLiterals != 3 {
+			t.Fatalf("output = %+v, want 2 usages of 3 indexed literals", output)
+		}
+		first, second := output.Usages[0], output.Usages[1]
+		if first.SymbolName != "NewServer" || first.Call != "WithTimeout" || first.Line != 12 {
+			t.Errorf("first usage = %+v", first)
+		}
+		if second.SymbolName != "Start" || second.Call != "client.Dial" || second.ArgName != "Timeout" {
+			t.Errorf("second usage = %+v", second)
+		}
+		if !strings.Contains(result.OutputText, "server.go:33") || !strings.Contains(result.OutputText, "method Start") {
+			t.Errorf("OutputText = %q", result.OutputText)
+		}
+	})
+
+	t.Run("matches substrings and reports truncation", func(t *testing.T) {
+		result, err := tool.Execute(ctx, FindLiteralUsagesParams{Value: "PROD", Substring: true})
+		if err != nil || !result.Success {
+			t.Fatalf("Execute() = %v, %v", result, err)
+		}
+		if output := result.Output.(FindLiteralUsagesOutput); len(output.Usages) != 1 || output.Usages[0].Value != "production" {
+			t.Errorf("usages = %+v, want production", output.Usages)
+		}
+
+		result, err = tool.Execute(ctx, FindLiteralUsagesParams{Value: "30", Limit: 1})
+		if err != nil || !result.Success {
+			t.Fatalf("Execute() = %v, %v", result, err)
+		}
+		if !result.Truncated || len(result.Output.(FindLiteralUsagesOutput).Usages) != 1 {
+			t.Errorf("limit 1: truncated=%v output=%+v", result.Truncated, result.Output)
+		}
+	})
+
+	t.Run("rejects a missing value or unknown kind", func(t *testing.T) {
+		for _, params := range []FindLiteralUsagesParams{{}, {Value: "30", Kind: "float"}} {
+			result, err := tool.Execute(ctx, params)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Success {
+				t.Errorf("Execute(%+v) succeeded, want a validation error", params)
+			}
+		}
+	})
+
+	t.Run("explains an empty index", func(t *testing.T) {
+		g := graph.NewGraph("/project")
+		g.Freeze()
+		result, err := NewFindLiteralUsagesTool(g).Execute(ctx, FindLiteralUsagesParams{Value: "30"})
+		if err != nil || !result.Success {
+			t.Fatalf("Execute() = %v, %v", result, err)
+		}
+		if !strings.Contains(result.OutputText, "capture_literal_args") {
+			t.Errorf("OutputText = %q", result.OutputText)
+		}
+	})
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -203,6 +204,7 @@ func (t *findLoopsTool) Definition() ToolDefinition {
 		Priority:    84,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     30 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -218,6 +220,10 @@ func (t *findLoopsTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findLoopsTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindLoopsOutput{})
+}
+
 // Execute runs the find_loops tool.
 func (t *findLoopsTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	// Parse and validate parameters
@@ -283,26 +289,26 @@ func (t *findLoopsTool) executeOnce(ctx context.Context, p FindLoopsParams) (*Re
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
 	// Detect entry point for dominator analysis
 	entry, err := DetectEntryPoint(ctx, t.index, t.analytics)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to detect entry point: %v", err),
 		}, nil
 	}
 
-	span.SetAttributes(attribute.String("entry", entry))
+	span.SetAttributes(telemetry.SensitiveString("entry", entry))
 
 	// Compute dominator tree
 	domTree, err := t.analytics.Dominators(ctx, entry)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to compute dominators: %v", err),
@@ -311,7 +317,7 @@ func (t *findLoopsTool) executeOnce(ctx context.Context, p FindLoopsParams) (*Re
 
 	// Check context again before loop detection
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -24,6 +24,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -171,6 +172,7 @@ func (t *findMergePointsTool) Definition() ToolDefinition {
 		Priority:    82,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     30 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -186,6 +188,10 @@ func (t *findMergePointsTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findMergePointsTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindMergePointsOutput{})
+}
+
 // Execute runs the find_merge_points tool.
 func (t *findMergePointsTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	// Parse and validate parameters
@@ -253,26 +259,26 @@ func (t *findMergePointsTool) executeOnce(ctx context.Context, p FindMergePoints
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
 	// Detect entry point for dominator analysis
 	entry, err := DetectEntryPoint(ctx, t.index, t.analytics)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to detect entry point: %v", err),
 		}, nil
 	}
 
-	span.SetAttributes(attribute.String("entry", entry))
+	span.SetAttributes(telemetry.SensitiveString("entry", entry))
 
 	// Compute dominator tree
 	domTree, err := t.analytics.Dominators(ctx, entry)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to compute dominators: %v", err),
@@ -281,7 +287,7 @@ func (t *findMergePointsTool) executeOnce(ctx context.Context, p FindMergePoints
 
 	// Check context again before frontier computation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -302,7 +308,7 @@ func (t *findMergePointsTool) executeOnce(ctx context.Context, p FindMergePoints
 
 	// Check if frontier computation returned error via TraceStep
 	if traceStep.Error != "" {
-		span.RecordError(fmt.Errorf("frontier computation failed: %s", traceStep.Error))
+		telemetry.RecordException(span, fmt.Errorf("frontier computation failed: %s", traceStep.Error))
 		return &Result{
 			Success:   false,
 			Error:     fmt.Sprintf("failed to compute dominance frontier: %s", traceStep.Error),
@@ -22,12 +22,12 @@ import (
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -307,6 +307,7 @@ func (t *findModuleAPITool) Definition() ToolDefinition {
 		Priority:    81,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     60 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -322,6 +323,10 @@ func (t *findModuleAPITool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findModuleAPITool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindModuleAPIOutput{})
+}
+
 // Execute runs the find_module_api tool.
 func (t *findModuleAPITool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -356,19 +361,19 @@ func (t *findModuleAPITool) Execute(ctx context.Context, params TypedParams) (*R
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
 	// Step 1: Detect communities
 	communities, commTrace, err := t.detectCommunities(ctx)
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		// E4: Add span event for failure observability
-		span.AddEvent("community_detection_failed", trace.WithAttributes(
-			attribute.String("error", err.Error()),
-		))
+		telemetry.AddSpanEvent(span, "community_detection_failed",
+			telemetry.SensitiveString("error", err.Error()),
+		)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to detect communities for module API analysis: %v", err),
@@ -465,7 +470,7 @@ func (t *findModuleAPITool) Execute(ctx context.Context, params TypedParams) (*R
 	for _, comm := range communitiesToAnalyze {
 		// Check context cancellation
 		if ctx.Err() != nil {
-			span.RecordError(ctx.Err())
+			telemetry.RecordException(span, ctx.Err())
 			t.logger.Info("context cancelled, returning partial results",
 				slog.Int("modules_analyzed", len(modules)),
 			)
@@ -482,7 +487,7 @@ func (t *findModuleAPITool) Execute(ctx context.Context, params TypedParams) (*R
 			// Log warning but continue with other communities (graceful degradation)
 			t.logger.Warn("failed to analyze community, skipping",
 				slog.Int("community_id", comm.ID),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			continue
 		}
@@ -716,10 +721,10 @@ func (t *findModuleAPITool) analyzeCommunity(ctx context.Context, comm graph.Com
 	externalCallers, err := t.findExternalCallers(comm)
 	if err != nil {
 		// E4: Add span event for failure observability
-		span.RecordError(err)
-		span.AddEvent("find_external_callers_failed", trace.WithAttributes(
-			attribute.String("error", err.Error()),
-		))
+		telemetry.RecordException(span, err)
+		telemetry.AddSpanEvent(span, "find_external_callers_failed",
+			telemetry.SensitiveString("error", err.Error()),
+		)
 		return ModuleAPI{}, fmt.Errorf("find external callers: %w", err)
 	}
 
@@ -739,10 +744,10 @@ func (t *findModuleAPITool) analyzeCommunity(ctx context.Context, comm graph.Com
 	apiSurface, err := t.computeAPISurface(ctx, comm, externalCallers)
 	if err != nil {
 		// E4: Add span event for failure observability
-		span.RecordError(err)
-		span.AddEvent("compute_api_surface_failed", trace.WithAttributes(
-			attribute.String("error", err.Error()),
-		))
+		telemetry.RecordException(span, err)
+		telemetry.AddSpanEvent(span, "compute_api_surface_failed",
+			telemetry.SensitiveString("error", err.Error()),
+		)
 		return ModuleAPI{}, fmt.Errorf("compute API surface: %w", err)
 	}
 
@@ -807,15 +812,15 @@ func (t *findModuleAPITool) computeAPISurface(
 		if err != nil {
 			// Log warning but continue with zero coverage (graceful degradation)
 			t.logger.Warn("failed to compute coverage for API function",
-				slog.String("node", internalNode),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("node", internalNode),
+				telemetry.SensitiveLogError("error", err),
 			)
 			// E4: Add span event for partial failure observability
 			span := trace.SpanFromContext(ctx)
-			span.AddEvent("coverage_computation_failed", trace.WithAttributes(
-				attribute.String("node", internalNode),
-				attribute.String("error", err.Error()),
-			))
+			telemetry.AddSpanEvent(span, "coverage_computation_failed",
+				telemetry.SensitiveString("node", internalNode),
+				telemetry.SensitiveString("error", err.Error()),
+			)
 			coverage = 0.0
 			dominated = 0
 		}
@@ -862,7 +867,7 @@ func (t *findModuleAPITool) computeCoverage(ctx context.Context, comm graph.Comm
 		// Fallback: use full graph if subgraph extraction fails
 		t.logger.Warn("failed to extract subgraph, using full graph",
 			slog.Int("community_id", comm.ID),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return t.computeCoverageFullGraph(ctx, comm, entryNode)
 	}
@@ -874,7 +879,7 @@ func (t *findModuleAPITool) computeCoverage(ctx context.Context, comm graph.Comm
 		// Entry node might not be reachable in subgraph (disconnected component)
 		// Return zero coverage for unreachable entry points
 		t.logger.Debug("entry node not reachable in subgraph",
-			slog.String("entry", entryNode),
+			telemetry.SensitiveLog("entry", entryNode),
 			slog.Int("community_id", comm.ID),
 		)
 		return 0.0, 0, nil
@@ -970,8 +975,9 @@ func (t *findModuleAPITool) extractSubgraph(comm graph.Community) (*graph.Hierar
 		memberSet[m] = true
 	}
 
-	// Create new graph for subgraph
-	subgraph := graph.NewGraph(fmt.Sprintf("community_%d_subgraph", comm.ID))
+	// Create new graph for subgraph. Symbols are shared with the live graph,
+	// so interning (which may rewrite symbol string fields) stays off.
+	subgraph := graph.NewGraph(fmt.Sprintf("community_%d_subgraph", comm.ID), graph.WithStringInterning(false))
 
 	// Add all community nodes to subgraph
 	for _, nodeID := range comm.Nodes {
@@ -1003,7 +1009,7 @@ func (t *findModuleAPITool) extractSubgraph(comm graph.Community) (*graph.Hierar
 			toID := edge.ToID
 			if memberSet[toID] {
 				// Both endpoints in community - add to subgraph
-				subgraph.AddEdge(nodeID, toID, edge.Type, edge.Location)
+				subgraph.AddEdgeWithProvenance(nodeID, toID, edge.Type, edge.Location, edge.Provenance)
 			}
 		}
 	}
@@ -0,0 +1,313 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// =============================================================================
+// find_owners Tool
+// =============================================================================
+
+var findOwnersTracer = otel.Tracer("tools.find_owners")
+
+// FindOwnersParams contains the validated input parameters.
+type FindOwnersParams struct {
+	// Symbol is a symbol name or ID. Optional if Files is set.
+	Symbol string
+
+	// Files are changed file paths relative to the project root. Optional
+	// if Symbol is set.
+	Files []string
+}
+
+// ToolName returns the tool name for TypedParams interface.
+func (p FindOwnersParams) ToolName() string { return "find_owners" }
+
+// ToMap converts typed parameters to the map consumed by Tool.Execute().
+func (p FindOwnersParams) ToMap() map[string]any {
+	m := map[string]any{}
+	if p.Symbol != "" {
+		m["symbol"] = p.Symbol
+	}
+	if len(p.Files) > 0 {
+		m["files"] = p.Files
+	}
+	return m
+}
+
+// FindOwnersOutput contains the structured result.
+type FindOwnersOutput struct {
+	// SymbolID is the resolved symbol, if a symbol was given.
+	SymbolID string `json:"symbol_id,omitempty"`
+
+	// Files are the files whose owners were resolved.
+	Files []string `json:"files"`
+
+	// Owners groups the files and symbol by owner, most files first.
+	Owners []graph.OwnerGroup `json:"owners"`
+
+	// UnownedFiles are files no CODEOWNERS rule assigns.
+	UnownedFiles []string `json:"unowned_files"`
+
+	// HasCodeowners is false when the project has no CODEOWNERS file.
+	HasCodeowners bool `json:"has_codeowners"`
+}
+
+// findOwnersTool aggregates the CODEOWNERS owners of a symbol or a set of
+// changed files.
+//
+// Description:
+//
+//	Resolves the symbol to its file, or takes the files as given, and
+//	groups them by owner using the ownership recorded on the graph at init.
+//	Answers "who should review this change?".
+//
+// Thread Safety: Safe for concurrent use. All operations are read-only.
+type findOwnersTool struct {
+	graph  *graph.Graph
+	index  *index.SymbolIndex
+	logger *slog.Logger
+}
+
+// NewFindOwnersTool creates the find_owners tool.
+//
+// Inputs:
+//
+//   - g: The code graph, with ownership recorded. Must not be nil.
+//   - idx: The symbol index for symbol resolution. Must not be nil.
+//
+// Outputs:
+//
+//   - Tool: The find_owners tool implementation.
+//
+// Limitations:
+//
+//   - Ownership is read from CODEOWNERS only; a project without one has no
+//     owners, which is reported rather than treated as an error.
+func NewFindOwnersTool(g *graph.Graph, idx *index.SymbolIndex) Tool {
+	return &findOwnersTool{
+		graph:  g,
+		index:  idx,
+		logger: slog.Default(),
+	}
+}
+
+func (t *findOwnersTool) Name() string {
+	return "find_owners"
+}
+
+func (t *findOwnersTool) Category() ToolCategory {
+	return CategoryExploration
+}
+
+func (t *findOwnersTool) Definition() ToolDefinition {
+	return findOwnersDefinition()
+}
+
+func (t *findOwnersTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindOwnersOutput{})
+}
+
+// findOwnersDefinition is shared by the tool and StaticToolDefinitions.
+func findOwnersDefinition() ToolDefinition {
+	return ToolDefinition{
+		Name: "find_owners",
+		Description: "Find the CODEOWNERS owners (teams or people) of a symbol or a set of changed files. " +
+			"Groups the files by owning team so you can say who should review a change. " +
+			"Use for 'who owns X?', 'who should review this?', 'which teams are affected?'.",
+		Parameters: map[string]ParamDef{
+			"symbol": {
+				Type:        ParamTypeString,
+				Description: "Symbol name or ID (e.g., 'HandleLogin', 'AuthService.Validate'). Optional if files is set.",
+				Required:    false,
+			},
+			"files": {
+				Type:        ParamTypeArray,
+				Description: "Changed file paths relative to project root (e.g., ['src/auth/token.go']). Optional if symbol is set.",
+				Required:    false,
+			},
+		},
+		Category:    CategoryExploration,
+		Priority:    80,
+		Requires:    []string{"graph_initialized"},
+		SideEffects: false,
+		// Ownership can change without a source change; never serve it
+		// from the result cache.
+		Cacheable: false,
+		Timeout:   5 * time.Second,
+		WhenToUse: WhenToUse{
+			Keywords: []string{
+				"owner", "owners", "who owns", "codeowners",
+				"who should review", "reviewer", "which team", "owning team",
+			},
+			UseWhen: "User asks who owns a symbol or file, who should review a change, " +
+				"or which teams a set of changed files belongs to.",
+			AvoidWhen: "User wants the callers or dependents of a change — use find_callers or find_references.",
+		},
+	}
+}
+
+// Execute runs the find_owners tool.
+func (t *findOwnersTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
+	start := time.Now()
+
+	p, err := t.parseParams(params.ToMap())
+	if err != nil {
+		errStep := crs.NewTraceStepBuilder().
+			WithAction("tool_find_owners").
+			WithTool("find_owners").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return &Result{
+			Success:   false,
+			Error:     err.Error(),
+			TraceStep: &errStep,
+			Duration:  time.Since(start),
+		}, nil
+	}
+
+	_, span := findOwnersTracer.Start(ctx, "findOwnersTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "find_owners"),
+			telemetry.SensitiveString("symbol", p.Symbol),
+			attribute.Int("files", len(p.Files)),
+		),
+	)
+	defer span.End()
+
+	output := FindOwnersOutput{
+		Files:         append([]string(nil), p.Files...),
+		HasCodeowners: t.graph.HasOwners(),
+	}
+	var symbolFiles map[string]string
+	target := strings.Join(p.Files, ", ")
+
+	if p.Symbol != "" {
+		sym, err := t.resolveSymbol(ctx, p.Symbol)
+		if err != nil {
+			notFoundStep := crs.NewTraceStepBuilder().
+				WithAction("tool_find_owners").
+				WithTarget(p.Symbol).
+				WithTool("find_owners").
+				WithDuration(time.Since(start)).
+				WithError(err.Error()).
+				Build()
+			return &Result{
+				Success:   false,
+				Error:     fmt.Sprintf("symbol '%s' not found", p.Symbol),
+				TraceStep: &notFoundStep,
+				Duration:  time.Since(start),
+			}, nil
+		}
+		output.SymbolID = sym.ID
+		output.Files = append(output.Files, sym.FilePath)
+		symbolFiles = map[string]string{sym.ID: sym.FilePath}
+		target = sym.ID
+	}
+
+	output.Owners, output.UnownedFiles = t.graph.GroupByOwner(output.Files, symbolFiles)
+	span.SetAttributes(attribute.Int("owners", len(output.Owners)))
+
+	var sb strings.Builder
+	switch {
+	case !output.HasCodeowners:
+		sb.WriteString("## GRAPH RESULT: No CODEOWNERS file\n\n")
+		sb.WriteString("The project has no CODEOWNERS file (checked .github/, the root and docs/), " +
+			"so no owners are recorded. This is the definitive answer.\n")
+	case len(output.Owners) == 0:
+		sb.WriteString(fmt.Sprintf("## GRAPH RESULT: No owners for %s\n\n", target))
+		sb.WriteString("No CODEOWNERS rule assigns an owner to these files.\n")
+	default:
+		sb.WriteString(fmt.Sprintf("## Owners of %s (%d owners)\n\n", target, len(output.Owners)))
+		for _, group := range output.Owners {
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", group.Owner, strings.Join(group.Files, ", ")))
+		}
+		if len(output.UnownedFiles) > 0 {
+			sb.WriteString(fmt.Sprintf("\nUnowned: %s\n", strings.Join(output.UnownedFiles, ", ")))
+		}
+	}
+
+	duration := time.Since(start)
+	toolStep := crs.NewTraceStepBuilder().
+		WithAction("tool_find_owners").
+		WithTarget(target).
+		WithTool("find_owners").
+		WithDuration(duration).
+		WithMetadata("owner_count", fmt.Sprintf("%d", len(output.Owners))).
+		Build()
+
+	return &Result{
+		Success:     true,
+		Output:      output,
+		OutputText:  sb.String(),
+		TokensUsed:  estimateTokens(sb.String()),
+		TraceStep:   &toolStep,
+		Duration:    duration,
+		ResultCount: len(output.Owners),
+	}, nil
+}
+
+// resolveSymbol resolves a symbol ID or name of any kind.
+func (t *findOwnersTool) resolveSymbol(ctx context.Context, name string) (*ast.Symbol, error) {
+	if sym, ok := t.index.GetByID(name); ok {
+		return sym, nil
+	}
+	sym, _, err := ResolveFunctionWithFuzzy(ctx, t.index, name, t.logger, WithKindFilter(KindFilterAny))
+	return sym, err
+}
+
+// parseParams validates and extracts typed parameters.
+func (t *findOwnersTool) parseParams(params map[string]any) (FindOwnersParams, error) {
+	var p FindOwnersParams
+
+	if raw, ok := params["symbol"]; ok {
+		if s, ok := parseStringParam(raw); ok {
+			p.Symbol = strings.TrimSpace(s)
+		}
+	}
+	if raw, ok := params["files"]; ok {
+		files, ok := parseStringArray(raw)
+		if !ok {
+			return p, fmt.Errorf("'files' must be an array of file paths")
+		}
+		for _, f := range files {
+			f = strings.TrimPrefix(strings.TrimSpace(f), "./")
+			if f == "" {
+				continue
+			}
+			if strings.Contains(f, "..") {
+				return p, fmt.Errorf("file path must not contain '..': %s", f)
+			}
+			p.Files = append(p.Files, f)
+		}
+	}
+	if p.Symbol == "" && len(p.Files) == 0 {
+		return p, fmt.Errorf("'symbol' or 'files' parameter is required")
+	}
+	return p, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/owners"
+)
+
+func TestFindOwnersTool_Execute(t *testing.T) {
+	ctx := context.Background()
+	g, idx, _ := createTestGraphWithSourceFiles(t)
+	tool := NewFindOwnersTool(g, idx)
+
+	t.Run("reports a project without CODEOWNERS", func(t *testing.T) {
+		result, err := tool.Execute(ctx, FindOwnersParams{Symbol: "ParseConfig"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Execute() failed: %s", result.Error)
+		}
+		output := result.Output.(FindOwnersOutput)
+		if output.HasCodeowners || len(output.Owners) != 0 {
+			t.Errorf("output = %+v, want no owners", output)
+		}
+		if !strings.Contains(result.OutputText, "No CODEOWNERS file") {
+			t.Errorf("OutputText = %q", result.OutputText)
+		}
+	})
+
+	co, err := owners.Parse(strings.NewReader("* @org/everyone\n/pkg/config/ @org/config @alice\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	graph.RecordFileOwners(g, co.Owners)
+
+	t.Run("resolves a symbol's owners through its file", func(t *testing.T) {
+		result, err := tool.Execute(ctx, FindOwnersParams{Symbol: "ParseConfig"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Execute() failed: %s", result.Error)
+		}
+		output := result.Output.(FindOwnersOutput)
+		if output.SymbolID != "pkg/config/config.go:7:ParseConfig" {
+			t.Errorf("SymbolID = %q", output.SymbolID)
+		}
+		var got []string
+		for _, group := range output.Owners {
+			got = append(got, group.Owner)
+		}
+		if !reflect.DeepEqual(got, []string{"@alice", "@org/config"}) {
+			t.Errorf("owners = %v, want [@alice @org/config]", got)
+		}
+		if !strings.Contains(result.OutputText, "@org/config") {
+			t.Errorf("OutputText = %q", result.OutputText)
+		}
+	})
+
+	t.Run("groups changed files by owner", func(t *testing.T) {
+		result, err := tool.Execute(ctx, FindOwnersParams{
+			Files: []string{"pkg/config/config.go", "cmd/main.go", "./README.md"},
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		output := result.Output.(FindOwnersOutput)
+		if len(output.Owners) != 3 {
+			t.Fatalf("got %d owners, want 3: %+v", len(output.Owners), output.Owners)
+		}
+		// @org/everyone owns two files and sorts first.
+		if output.Owners[0].Owner != "@org/everyone" || len(output.Owners[0].Files) != 2 {
+			t.Errorf("first group = %+v", output.Owners[0])
+		}
+	})
+
+	t.Run("rejects missing parameters and path traversal", func(t *testing.T) {
+		for _, params := range []map[string]any{
+			{},
+			{"files": []any{"../etc/passwd"}},
+		} {
+			result, err := tool.Execute(ctx, MapParams{Params: params})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Success {
+				t.Errorf("Execute(%v) succeeded, want failure", params)
+			}
+		}
+	})
+
+	t.Run("fails for an unknown symbol", func(t *testing.T) {
+		result, err := tool.Execute(ctx, FindOwnersParams{Symbol: "zzzNoSuchSymbolzzz"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Success {
+			t.Error("Execute() succeeded, want failure")
+		}
+	})
+}
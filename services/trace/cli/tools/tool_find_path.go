@@ -26,6 +26,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -167,10 +168,15 @@ func (t *findPathTool) Definition() ToolDefinition {
 		Priority:    83,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     10 * time.Second,
 	}
 }
 
+func (t *findPathTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindPathOutput{})
+}
+
 // Execute runs the find_path tool.
 func (t *findPathTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -196,8 +202,8 @@ func (t *findPathTool) Execute(ctx context.Context, params TypedParams) (*Result
 	ctx, span := findPathTracer.Start(ctx, "findPathTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_path"),
-			attribute.String("from", p.From),
-			attribute.String("to", p.To),
+			telemetry.SensitiveString("from", p.From),
+			telemetry.SensitiveString("to", p.To),
 		),
 	)
 	defer span.End()
@@ -228,8 +234,8 @@ func (t *findPathTool) Execute(ctx context.Context, params TypedParams) (*Result
 	// When both endpoints resolve to the same symbol, try alternate candidates.
 	if fromID != "" && toID != "" && fromID == toID {
 		t.logger.Info("find_path: from==to after primary resolution, trying alternates",
-			slog.String("from_name", p.From),
-			slog.String("to_name", p.To),
+			telemetry.SensitiveLog("from_name", p.From),
+			telemetry.SensitiveLog("to_name", p.To),
 			slog.String("same_id", fromID),
 		)
 		// Try next to-candidate first (from is usually more specific)
@@ -310,15 +316,15 @@ func (t *findPathTool) Execute(ctx context.Context, params TypedParams) (*Result
 	}
 
 	span.SetAttributes(
-		attribute.String("from_id", fromID),
-		attribute.String("from_package", fromPackage),
-		attribute.String("to_id", toID),
-		attribute.String("to_package", toPackage),
+		telemetry.SensitiveString("from_id", fromID),
+		telemetry.SensitiveString("from_package", fromPackage),
+		telemetry.SensitiveString("to_id", toID),
+		telemetry.SensitiveString("to_package", toPackage),
 	)
 
 	// Check context cancellation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
@@ -337,7 +343,7 @@ func (t *findPathTool) Execute(ctx context.Context, params TypedParams) (*Result
 			WithDuration(pathDuration).
 			WithError(err.Error()).
 			Build()
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetAttributes(attribute.String("trace_action", traceStep.Action))
 		return &Result{
 			Success:   false,
@@ -355,10 +361,10 @@ func (t *findPathTool) Execute(ctx context.Context, params TypedParams) (*Result
 	// Structured logging for edge cases
 	if pathResult.Length < 0 {
 		t.logger.Info("find_path: no path found — running chain diagnostic",
-			slog.String("from", p.From),
-			slog.String("to", p.To),
-			slog.String("from_id", fromID),
-			slog.String("to_id", toID),
+			telemetry.SensitiveLog("from", p.From),
+			telemetry.SensitiveLog("to", p.To),
+			telemetry.SensitiveLog("from_id", fromID),
+			telemetry.SensitiveLog("to_id", toID),
 		)
 		// IT-12 Rev 5e diagnostic: trace outgoing edges from the "from" node
 		// link-by-link to find where the expected call chain breaks.
@@ -375,8 +381,8 @@ func (t *findPathTool) Execute(ctx context.Context, params TypedParams) (*Result
 	if pathResult.Length < 0 && (len(fromCandidates) > 1 || len(toCandidates) > 1) {
 		alternativeTried = true
 		t.logger.Info("find_path: no path with primary candidates, trying alternatives",
-			slog.String("from", p.From),
-			slog.String("to", p.To),
+			telemetry.SensitiveLog("from", p.From),
+			telemetry.SensitiveLog("to", p.To),
 			slog.Int("from_candidates", len(fromCandidates)),
 			slog.Int("to_candidates", len(toCandidates)),
 		)
@@ -399,8 +405,8 @@ func (t *findPathTool) Execute(ctx context.Context, params TypedParams) (*Result
 				pathDuration += time.Since(altStart)
 				if altErr == nil && altResult.Length >= 0 {
 					t.logger.Info("find_path: alternative candidates found path",
-						slog.String("from_id", fc.ID),
-						slog.String("to_id", tc.ID),
+						telemetry.SensitiveLog("from_id", fc.ID),
+						telemetry.SensitiveLog("to_id", tc.ID),
 						slog.Int("length", altResult.Length),
 					)
 					pathResult = altResult
@@ -508,8 +514,8 @@ func (t *findPathTool) resolveSymbolCandidates(ctx context.Context, name string,
 		t.logger, max, WithKindFilter(KindFilterAny), WithBareMethodFallback())
 	if err != nil {
 		t.logger.Debug("find_path: candidate resolution failed",
-			slog.String("name", name),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("name", name),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil
 	}
@@ -548,8 +554,8 @@ func (t *findPathTool) logChainDiagnostic(_ context.Context, fromID, toID string
 	// Check "to" node reachability
 	if toNode, ok := t.graph.GetNode(toID); ok {
 		t.logger.Info("find_path diagnostic: TO node edges",
-			slog.String("to_id", toID),
-			slog.String("to_name", toNode.Symbol.Name),
+			telemetry.SensitiveLog("to_id", toID),
+			telemetry.SensitiveLog("to_name", toNode.Symbol.Name),
 			slog.Int("incoming_edges", len(toNode.Incoming)),
 			slog.Int("outgoing_edges", len(toNode.Outgoing)),
 		)
@@ -567,8 +573,8 @@ func (t *findPathTool) logChainDiagnostic(_ context.Context, fromID, toID string
 				}
 			}
 			t.logger.Info("find_path diagnostic: TO node callers",
-				slog.String("to_name", toNode.Symbol.Name),
-				slog.Any("callers", callers),
+				telemetry.SensitiveLog("to_name", toNode.Symbol.Name),
+				telemetry.SensitiveLogStrings("callers", callers),
 			)
 		}
 	}
@@ -576,8 +582,8 @@ func (t *findPathTool) logChainDiagnostic(_ context.Context, fromID, toID string
 	// BFS from "from" node — log first 3 hops of outgoing edges
 	if fromNode, ok := t.graph.GetNode(fromID); ok {
 		t.logger.Info("find_path diagnostic: FROM node edges",
-			slog.String("from_id", fromID),
-			slog.String("from_name", fromNode.Symbol.Name),
+			telemetry.SensitiveLog("from_id", fromID),
+			telemetry.SensitiveLog("from_name", fromNode.Symbol.Name),
 			slog.Int("outgoing_edges", len(fromNode.Outgoing)),
 		)
 
@@ -595,8 +601,8 @@ func (t *findPathTool) logChainDiagnostic(_ context.Context, fromID, toID string
 			}
 		}
 		t.logger.Info("find_path diagnostic: hop 1 (direct callees)",
-			slog.String("from", fromNode.Symbol.Name),
-			slog.Any("callees", hop1Names),
+			telemetry.SensitiveLog("from", fromNode.Symbol.Name),
+			telemetry.SensitiveLogStrings("callees", hop1Names),
 		)
 
 		// Hop 2: callees of callees (only for first 5 hop-1 nodes to limit output)
@@ -623,7 +629,7 @@ func (t *findPathTool) logChainDiagnostic(_ context.Context, fromID, toID string
 			t.logger.Info("find_path diagnostic: hop 2",
 				slog.String("via", hop1Node.Symbol.Name),
 				slog.Int("outgoing", len(hop1Node.Outgoing)),
-				slog.Any("callees", hop2Names),
+				telemetry.SensitiveLogStrings("callees", hop2Names),
 			)
 		}
 	}
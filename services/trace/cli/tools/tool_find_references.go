@@ -48,6 +48,12 @@ type FindReferencesParams struct {
 	// IT-06c: Used to disambiguate when multiple symbols share the same name
 	// during ResolveFunctionWithFuzzy exact-match phase.
 	PackageHint string
+
+	// Cursor is the next_cursor of the previous page (optional).
+	Cursor string
+
+	// offset is the position Cursor resumes at.
+	offset int
 }
 
 // ToolName returns the tool name for TypedParams interface.
@@ -62,9 +68,17 @@ func (p FindReferencesParams) ToMap() map[string]any {
 	if p.PackageHint != "" {
 		m["package_hint"] = p.PackageHint
 	}
+	if p.Cursor != "" {
+		m["cursor"] = p.Cursor
+	}
 	return m
 }
 
+// cursorQuery identifies the query in cursors.
+func (p FindReferencesParams) cursorQuery() []string {
+	return []string{"find_references", p.SymbolName, p.PackageHint}
+}
+
 // FindReferencesOutput contains the structured result.
 type FindReferencesOutput struct {
 	// SymbolName is the symbol that was searched for.
@@ -85,6 +99,8 @@ type FindReferencesOutput struct {
 
 	// References is the list of reference locations.
 	References []ReferenceInfo `json:"references"`
+
+	PageInfo
 }
 
 // ReferenceInfo holds information about a reference location.
@@ -177,19 +193,25 @@ func (t *findReferencesTool) Definition() ToolDefinition {
 			},
 			"limit": {
 				Type:        ParamTypeInt,
-				Description: "Maximum number of references to return",
+				Description: "Maximum number of references to return per page",
 				Required:    false,
 				Default:     100,
 			},
+			"cursor": cursorParamDef,
 		},
 		Category:    CategoryExploration,
 		Priority:    87,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     5 * time.Second,
 	}
 }
 
+func (t *findReferencesTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindReferencesOutput{})
+}
+
 // Execute runs the find_references tool.
 //
 // Description:
@@ -237,7 +259,7 @@ func (t *findReferencesTool) Execute(ctx context.Context, params TypedParams) (*
 	ctx, span := findReferencesTracer.Start(ctx, "findReferencesTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_references"),
-			attribute.String("symbol_name", p.SymbolName),
+			telemetry.SensitiveString("symbol_name", p.SymbolName),
 			attribute.Int("limit", p.Limit),
 		),
 	)
@@ -266,10 +288,10 @@ func (t *findReferencesTool) Execute(ctx context.Context, params TypedParams) (*
 				sym = pickMostSignificantSymbol(filtered)
 				strategy = "package_hint"
 				logger.Info("IT-06c: find_references resolved via package hint",
-					slog.String("symbol", p.SymbolName),
+					telemetry.SensitiveLog("symbol", p.SymbolName),
 					slog.String("hint", p.PackageHint),
-					slog.String("resolved", sym.Name),
-					slog.String("file", sym.FilePath),
+					telemetry.SensitiveLog("resolved", sym.Name),
+					telemetry.SensitiveLog("file", sym.FilePath),
 					slog.Int("before", len(exactMatches)),
 					slog.Int("after", len(filtered)),
 				)
@@ -327,28 +349,24 @@ func (t *findReferencesTool) Execute(ctx context.Context, params TypedParams) (*
 
 	if fuzzy {
 		logger.Info("find_references: fuzzy match",
-			slog.String("query", p.SymbolName),
-			slog.String("matched", sym.Name),
+			telemetry.SensitiveLog("query", p.SymbolName),
+			telemetry.SensitiveLog("matched", sym.Name),
 		)
 	}
 
 	span.SetAttributes(
 		attribute.Bool("symbol_resolved", true),
-		attribute.String("resolved_name", sym.Name),
-		attribute.String("resolved_id", sym.ID),
+		telemetry.SensitiveString("resolved_name", sym.Name),
+		telemetry.SensitiveString("resolved_id", sym.ID),
 		attribute.String("symbol_kind", sym.Kind.String()),
 		attribute.Bool("fuzzy_match", fuzzy),
 	)
 
 	// Find all references to the resolved symbol.
-	// IT-06 Bug 6: Fetch extra results to allow sorting by relevance before truncating.
+	// IT-06 Bug 6: Fetch every result to allow sorting by relevance before paging.
 	// Without this, graph insertion order determines results, causing test/benchmark files
 	// (e.g., asv_bench/) to dominate when they're indexed before core modules.
-	fetchLimit := p.Limit * 3
-	if fetchLimit < 100 {
-		fetchLimit = 100
-	}
-	locations, gErr := t.graph.FindReferencesByID(ctx, sym.ID, graph.WithLimit(fetchLimit))
+	locations, gErr := t.graph.FindReferencesByID(ctx, sym.ID, graph.WithLimit(graph.MaxQueryLimit))
 	if gErr != nil {
 		return nil, fmt.Errorf("find references for '%s': %w", sym.Name, gErr)
 	}
@@ -387,10 +405,12 @@ func (t *findReferencesTool) Execute(ctx context.Context, params TypedParams) (*
 		return referenceFilePriority(allReferences[i].File) < referenceFilePriority(allReferences[j].File)
 	})
 
-	// Truncate to requested limit after sorting
-	if len(allReferences) > p.Limit {
-		allReferences = allReferences[:p.Limit]
-	}
+	// Page after sorting; the sort is stable, so pages line up across calls.
+	pageInfo := PageInfo{TotalAvailable: len(allReferences)}
+	pageStart := min(p.offset, len(allReferences))
+	pageEnd := min(pageStart+p.Limit, len(allReferences))
+	pageInfo.NextCursor = nextToolCursor(t.graph, pageEnd < len(allReferences), p.offset, p.Limit, p.cursorQuery()...)
+	allReferences = allReferences[pageStart:pageEnd]
 
 	// IT-06 Change 3: Build output with definition location
 	definedAt := fmt.Sprintf("%s:%d", sym.FilePath, sym.StartLine)
@@ -401,10 +421,12 @@ func (t *findReferencesTool) Execute(ctx context.Context, params TypedParams) (*
 		SymbolKind:     sym.Kind.String(),
 		ReferenceCount: len(allReferences),
 		References:     allReferences,
+		PageInfo:       pageInfo,
 	}
 
 	// Format text output — Path B (0 refs) or Path C (N refs)
 	outputText := t.formatText(p.SymbolName, sym, allReferences)
+	outputText = withPageNote(outputText, "find_references", p.offset, len(allReferences), pageInfo)
 
 	span.SetAttributes(attribute.Int("reference_count", len(allReferences)))
 
@@ -422,6 +444,7 @@ func (t *findReferencesTool) Execute(ctx context.Context, params TypedParams) (*
 		WithMetadata("symbol_kind", sym.Kind.String()).
 		WithMetadata("fuzzy_match", fmt.Sprintf("%t", fuzzy)).
 		WithMetadata("resolution_strategy", strategy).
+		WithMetadata("total_available", fmt.Sprintf("%d", pageInfo.TotalAvailable)).
 		Build()
 
 	return &Result{
@@ -432,6 +455,7 @@ func (t *findReferencesTool) Execute(ctx context.Context, params TypedParams) (*
 		TraceStep:   &toolStep,
 		Duration:    duration,
 		ResultCount: output.ReferenceCount,
+		NextCursor:  pageInfo.NextCursor,
 	}, nil
 }
 
@@ -465,6 +489,13 @@ func (t *findReferencesTool) parseParams(params map[string]any) (FindReferencesP
 		}
 	}
 
+	// Extract cursor (optional)
+	p.Cursor = parseCursorParam(params)
+	var err error
+	if p.offset, err = decodeToolCursor(t.graph, p.Cursor, p.cursorQuery()...); err != nil {
+		return p, err
+	}
+
 	return p, nil
 }
 
@@ -548,7 +579,7 @@ func (t *findReferencesTool) formatText(symbolName string, sym *ast.Symbol, refs
 
 	// GR-59 Group A: Signal definitiveness on success path.
 	sb.WriteString("\n---\n")
-	sb.WriteString("The graph has been fully indexed — these results are exhaustive.\n")
+	sb.WriteString(exhaustiveResultsLine)
 	sb.WriteString("**Do NOT use Grep or Read to verify** — the graph already analyzed all source files.\n")
 
 	return sb.String()
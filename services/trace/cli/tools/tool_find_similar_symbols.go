@@ -29,6 +29,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/rag"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -164,6 +165,10 @@ func (t *findSimilarSymbolsTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findSimilarSymbolsTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindSimilarSymbolsOutput{})
+}
+
 // Execute runs the find_similar_symbols tool.
 //
 // Description:
@@ -222,7 +227,7 @@ func (t *findSimilarSymbolsTool) Execute(ctx context.Context, params TypedParams
 	ctx, span := findSimilarSymbolsTracer.Start(ctx, "tools.FindSimilarSymbolsTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_similar_symbols"),
-			attribute.String("symbol_name", p.SymbolName),
+			telemetry.SensitiveString("symbol_name", p.SymbolName),
 			attribute.Int("limit", p.Limit),
 		),
 	)
@@ -258,7 +263,7 @@ func (t *findSimilarSymbolsTool) Execute(ctx context.Context, params TypedParams
 	// Embed the search text
 	queryVec, err := t.embedClient.EmbedQuery(ctx, searchText)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		errStep := crs.NewTraceStepBuilder().
 			WithAction("tool_find_similar_symbols").
 			WithTool("find_similar_symbols").
@@ -312,7 +317,7 @@ func (t *findSimilarSymbolsTool) Execute(ctx context.Context, params TypedParams
 		WithLimit(p.Limit).
 		Do(ctx)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		errStep := crs.NewTraceStepBuilder().
 			WithAction("tool_find_similar_symbols").
 			WithTool("find_similar_symbols").
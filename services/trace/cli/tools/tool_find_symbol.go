@@ -11,9 +11,11 @@
 package tools
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -25,6 +27,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -43,6 +46,23 @@ type FindSymbolParams struct {
 
 	// Package filters by package path (optional).
 	Package string
+
+	// Root restricts matches to one root of a multi-root workspace (optional).
+	Root string
+
+	// Annotation restricts matches to symbols with an annotation, as "key"
+	// or "key=value" (optional). With an empty Name, every symbol with
+	// the annotation matches.
+	Annotation string
+
+	// Limit is the page size. Default: 50, Max: 1000
+	Limit int
+
+	// Cursor is the next_cursor of the previous page (optional).
+	Cursor string
+
+	// offset is the position Cursor resumes at.
+	offset int
 }
 
 // ToolName returns the tool name for TypedParams interface.
@@ -57,9 +77,26 @@ func (p FindSymbolParams) ToMap() map[string]any {
 	if p.Package != "" {
 		m["package"] = p.Package
 	}
+	if p.Root != "" {
+		m["root"] = p.Root
+	}
+	if p.Annotation != "" {
+		m["annotation"] = p.Annotation
+	}
+	if p.Limit > 0 {
+		m["limit"] = p.Limit
+	}
+	if p.Cursor != "" {
+		m["cursor"] = p.Cursor
+	}
 	return m
 }
 
+// cursorQuery identifies the query in cursors.
+func (p FindSymbolParams) cursorQuery() []string {
+	return []string{"find_symbol", p.Name, p.Kind, p.Package, p.Root, p.Annotation}
+}
+
 // FindSymbolOutput contains the structured result.
 type FindSymbolOutput struct {
 	// SearchName is the name that was searched for.
@@ -70,6 +107,8 @@ type FindSymbolOutput struct {
 
 	// Symbols is the list of matching symbols.
 	Symbols []SymbolInfo `json:"symbols"`
+
+	PageInfo
 }
 
 // SymbolInfo holds information about a symbol.
@@ -95,6 +134,13 @@ type SymbolInfo struct {
 	// Signature is the function/method signature.
 	Signature string `json:"signature,omitempty"`
 
+	// SyntheticDoc is a model-generated summary of an undocumented
+	// symbol. It is not from the source.
+	SyntheticDoc string `json:"synthetic_doc,omitempty"`
+
+	// Annotations are the symbol's organizational annotations, key → value.
+	Annotations map[string]string `json:"annotations,omitempty"`
+
 	// Exported indicates if the symbol is exported.
 	Exported bool `json:"exported"`
 }
@@ -163,7 +209,7 @@ func (t *findSymbolTool) Definition() ToolDefinition {
 		Parameters: map[string]ParamDef{
 			"name": {
 				Type:        ParamTypeString,
-				Description: "Name of the symbol to find",
+				Description: "Name of the symbol to find. May be empty when annotation is given, to list every symbol with that annotation",
 				Required:    true,
 			},
 			"kind": {
@@ -178,15 +224,29 @@ func (t *findSymbolTool) Definition() ToolDefinition {
 				Description: "Filter by package path (optional)",
 				Required:    false,
 			},
+			"root":       rootParamDef,
+			"annotation": annotationParamDef,
+			"limit": {
+				Type:        ParamTypeInt,
+				Description: "Maximum number of symbols to return per page",
+				Required:    false,
+				Default:     50,
+			},
+			"cursor": cursorParamDef,
 		},
 		Category:    CategoryExploration,
 		Priority:    92,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     5 * time.Second,
 	}
 }
 
+func (t *findSymbolTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), FindSymbolOutput{})
+}
+
 // Execute runs the find_symbol tool.
 func (t *findSymbolTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -212,9 +272,10 @@ func (t *findSymbolTool) Execute(ctx context.Context, params TypedParams) (*Resu
 	ctx, span := findSymbolTracer.Start(ctx, "findSymbolTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_symbol"),
-			attribute.String("name", p.Name),
+			telemetry.SensitiveString("name", p.Name),
 			attribute.String("kind", p.Kind),
-			attribute.String("package", p.Package),
+			telemetry.SensitiveString("package", p.Package),
+			attribute.String("annotation", p.Annotation),
 		),
 	)
 	defer span.End()
@@ -223,8 +284,13 @@ func (t *findSymbolTool) Execute(ctx context.Context, params TypedParams) (*Resu
 	var matches []*ast.Symbol
 	var usedFuzzy bool
 	strategy := "fallback" // IT_CRS_01: Track resolution strategy for CRS metadata
+	annotationFilter := annotationFilterOf(p.Annotation)
 
-	if t.index != nil {
+	if p.Name == "" {
+		// Annotation-only lookup: every symbol carrying the annotation.
+		matches = annotatedSymbols(t.graph, annotationFilter)
+		strategy = "annotation"
+	} else if t.index != nil {
 		// Try exact match first (fast path)
 		matches = t.index.GetByName(p.Name)
 		if len(matches) > 0 {
@@ -243,7 +309,7 @@ func (t *findSymbolTool) Execute(ctx context.Context, params TypedParams) (*Resu
 				strategy = "fuzzy"
 
 				t.logger.Info("find_symbol: exact match failed, using fuzzy search",
-					slog.String("query", p.Name),
+					telemetry.SensitiveLog("query", p.Name),
 					slog.Int("fuzzy_matches", len(fuzzyMatches)))
 			}
 		}
@@ -281,17 +347,38 @@ func (t *findSymbolTool) Execute(ctx context.Context, params TypedParams) (*Resu
 		}
 		filtered = append(filtered, sym)
 	}
+	if p.Root != "" {
+		filtered = filterSymbolsByRoot(filtered, p.Root)
+	}
+	if !annotationFilter.IsZero() {
+		filtered = filterSymbolsByAnnotation(t.graph, filtered, annotationFilter)
+	}
+
+	// Page the matches. Index order is not stable across calls, so a list
+	// spanning several pages is put in location order first.
+	pageInfo := PageInfo{TotalAvailable: len(filtered)}
+	if p.offset > 0 || len(filtered) > p.Limit {
+		slices.SortFunc(filtered, func(a, b *ast.Symbol) int {
+			return cmp.Or(cmp.Compare(a.FilePath, b.FilePath), cmp.Compare(a.StartLine, b.StartLine), cmp.Compare(a.ID, b.ID))
+		})
+		pageStart := min(p.offset, len(filtered))
+		pageEnd := min(pageStart+p.Limit, len(filtered))
+		pageInfo.NextCursor = nextToolCursor(t.graph, pageEnd < len(filtered), p.offset, p.Limit, p.cursorQuery()...)
+		filtered = filtered[pageStart:pageEnd]
+	}
 
 	// Build typed output
 	output := t.buildOutput(p.Name, filtered)
+	output.PageInfo = pageInfo
 
 	// Format text output
-	outputText := t.formatText(p.Name, filtered)
+	outputText := t.formatText(p.searchLabel(), filtered)
 
 	// Add fuzzy match indicator if applicable
 	if usedFuzzy && len(filtered) > 0 {
 		outputText = "⚠️ No exact match found. Showing partial matches:\n\n" + outputText
 	}
+	outputText = withPageNote(outputText, "find_symbol", p.offset, len(filtered), pageInfo)
 
 	span.SetAttributes(
 		attribute.Int("match_count", len(filtered)),
@@ -327,6 +414,7 @@ func (t *findSymbolTool) Execute(ctx context.Context, params TypedParams) (*Resu
 		TraceStep:     &toolStep,
 		Duration:      duration,
 		ResultCount:   output.MatchCount,
+		NextCursor:    pageInfo.NextCursor,
 		ProofDelta:    proofDelta,
 		ScopeApplied:  scopeApplied,
 		PreScopeCount: scopePreCount,
@@ -336,17 +424,27 @@ func (t *findSymbolTool) Execute(ctx context.Context, params TypedParams) (*Resu
 // parseParams validates and extracts typed parameters from the raw map.
 func (t *findSymbolTool) parseParams(params map[string]any) (FindSymbolParams, error) {
 	p := FindSymbolParams{
-		Kind: "all",
+		Kind:  "all",
+		Limit: 50,
+	}
+
+	// Extract annotation (optional)
+	annotation, err := parseAnnotationParam(params)
+	if err != nil {
+		return p, err
 	}
+	p.Annotation = annotation
 
-	// Extract name (required)
+	// Extract name (required unless annotation is given)
 	if nameRaw, ok := params["name"]; ok {
 		if name, ok := parseStringParam(nameRaw); ok && name != "" {
 			p.Name = name
 		}
 	}
-	if err := ValidateSymbolName(p.Name, "name", "'Router', 'handleRequest', 'Config'"); err != nil {
-		return p, err
+	if p.Name != "" || p.Annotation == "" {
+		if err := ValidateSymbolName(p.Name, "name", "'Router', 'handleRequest', 'Config'"); err != nil {
+			return p, err
+		}
 	}
 
 	// Extract kind (optional)
@@ -363,9 +461,42 @@ func (t *findSymbolTool) parseParams(params map[string]any) (FindSymbolParams, e
 		}
 	}
 
+	// Extract root (optional)
+	if rootRaw, ok := params["root"]; ok {
+		if root, ok := parseStringParam(rootRaw); ok {
+			p.Root = root
+		}
+	}
+
+	// Extract limit (optional)
+	if limitRaw, ok := params["limit"]; ok {
+		if limit, ok := parseIntParam(limitRaw); ok {
+			p.Limit = max(1, min(limit, 1000))
+		}
+	}
+
+	// Extract cursor (optional)
+	p.Cursor = parseCursorParam(params)
+	if p.offset, err = decodeToolCursor(t.graph, p.Cursor, p.cursorQuery()...); err != nil {
+		return p, err
+	}
+
 	return p, nil
 }
 
+// searchLabel describes the search in text output, e.g. "matching 'Open'"
+// or "with annotation 'deprecated'".
+func (p FindSymbolParams) searchLabel() string {
+	switch {
+	case p.Annotation == "":
+		return fmt.Sprintf("matching '%s'", p.Name)
+	case p.Name == "":
+		return fmt.Sprintf("with annotation '%s'", p.Annotation)
+	default:
+		return fmt.Sprintf("matching '%s' with annotation '%s'", p.Name, p.Annotation)
+	}
+}
+
 // buildOutput creates the typed output struct.
 func (t *findSymbolTool) buildOutput(searchName string, symbols []*ast.Symbol) FindSymbolOutput {
 	output := FindSymbolOutput{
@@ -379,14 +510,16 @@ func (t *findSymbolTool) buildOutput(searchName string, symbols []*ast.Symbol) F
 			continue
 		}
 		output.Symbols = append(output.Symbols, SymbolInfo{
-			ID:        sym.ID,
-			Name:      sym.Name,
-			Kind:      sym.Kind.String(),
-			File:      sym.FilePath,
-			Line:      sym.StartLine,
-			Package:   sym.Package,
-			Signature: sym.Signature,
-			Exported:  sym.Exported,
+			ID:           sym.ID,
+			Name:         sym.Name,
+			Kind:         sym.Kind.String(),
+			File:         sym.FilePath,
+			Line:         sym.StartLine,
+			Package:      sym.Package,
+			Signature:    sym.Signature,
+			SyntheticDoc: syntheticDoc(t.graph, sym),
+			Annotations:  t.graph.AnnotationsOf(sym),
+			Exported:     sym.Exported,
 		})
 	}
 
@@ -394,15 +527,15 @@ func (t *findSymbolTool) buildOutput(searchName string, symbols []*ast.Symbol) F
 }
 
 // formatText creates a human-readable text summary.
-func (t *findSymbolTool) formatText(searchName string, symbols []*ast.Symbol) string {
+func (t *findSymbolTool) formatText(searchLabel string, symbols []*ast.Symbol) string {
 	var sb strings.Builder
 
 	if len(symbols) == 0 {
-		sb.WriteString(fmt.Sprintf("No symbols found matching '%s'.\n", searchName))
+		sb.WriteString(fmt.Sprintf("No symbols found %s.\n", searchLabel))
 		return sb.String()
 	}
 
-	sb.WriteString(fmt.Sprintf("Found %d symbols matching '%s':\n\n", len(symbols), searchName))
+	sb.WriteString(fmt.Sprintf("Found %d symbols %s:\n\n", len(symbols), searchLabel))
 
 	for _, sym := range symbols {
 		if sym == nil {
@@ -414,6 +547,12 @@ func (t *findSymbolTool) formatText(searchName string, symbols []*ast.Symbol) st
 		if sym.Signature != "" {
 			sb.WriteString(fmt.Sprintf("  Signature: %s\n", sym.Signature))
 		}
+		if doc := syntheticDoc(t.graph, sym); doc != "" {
+			sb.WriteString(fmt.Sprintf("  %s: %s\n", syntheticDocLabel, doc))
+		}
+		if annotations := annotationsText(t.graph, sym); annotations != "" {
+			sb.WriteString(fmt.Sprintf("  Annotations: %s\n", annotations))
+		}
 		if sym.Exported {
 			sb.WriteString("  Exported: yes\n")
 		}
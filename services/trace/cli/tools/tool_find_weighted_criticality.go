@@ -28,6 +28,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -186,6 +187,7 @@ func (t *findWeightedCriticalityTool) Definition() ToolDefinition {
 		Priority:    88,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     45 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -204,6 +206,10 @@ func (t *findWeightedCriticalityTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *findWeightedCriticalityTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), WeightedCriticalityOutput{})
+}
+
 // Execute runs the find_weighted_criticality tool.
 func (t *findWeightedCriticalityTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -229,7 +235,7 @@ func (t *findWeightedCriticalityTool) Execute(ctx context.Context, params TypedP
 	ctx, span := findWeightedCriticalityTracer.Start(ctx, "findWeightedCriticalityTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "find_weighted_criticality"),
-			attribute.String("entry", p.Entry),
+			telemetry.SensitiveString("entry", p.Entry),
 			attribute.Int("top", p.Top),
 			attribute.Bool("show_quadrant", p.ShowQuadrant),
 		),
@@ -238,29 +244,29 @@ func (t *findWeightedCriticalityTool) Execute(ctx context.Context, params TypedP
 
 	// Check context cancellation before expensive operation
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return nil, err
 	}
 
 	// Auto-detect entry if not provided
 	if p.Entry == "" {
 		p.Entry = t.detectEntry()
-		span.SetAttributes(attribute.String("entry_detected", p.Entry))
+		span.SetAttributes(telemetry.SensitiveString("entry_detected", p.Entry))
 	}
 
 	// Step 1: Compute dominator tree
 	t.logger.Debug("computing dominator tree",
 		slog.String("tool", "find_weighted_criticality"),
-		slog.String("entry", p.Entry),
+		telemetry.SensitiveLog("entry", p.Entry),
 	)
 
 	domTree, err := t.analytics.Dominators(ctx, p.Entry)
 	if err != nil || domTree == nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "dominator computation failed")
-		span.AddEvent("dominator_computation_failed", trace.WithAttributes(
-			attribute.String("error", fmt.Sprintf("%v", err)),
-		))
+		telemetry.AddSpanEvent(span, "dominator_computation_failed",
+			telemetry.SensitiveString("error", err.Error()),
+		)
 		return &Result{
 			Success: false,
 			Error:   fmt.Sprintf("failed to compute dominator tree: %v", err),
@@ -280,7 +286,7 @@ func (t *findWeightedCriticalityTool) Execute(ctx context.Context, params TypedP
 	prResult := t.analytics.PageRank(ctx, prOpts)
 	if prResult == nil {
 		err := fmt.Errorf("PageRank returned nil result")
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "PageRank computation failed")
 		span.AddEvent("pagerank_computation_failed", trace.WithAttributes(
 			attribute.String("error", "nil result"),
@@ -402,7 +408,7 @@ func (t *findWeightedCriticalityTool) Execute(ctx context.Context, params TypedP
 		}
 
 		t.logger.Info("IT-07: find_weighted_criticality scope filter",
-			slog.String("package", p.Package),
+			telemetry.SensitiveLog("package", p.Package),
 			slog.Int("before", scopePreCount),
 			slog.Int("after", len(scopeFiltered)),
 		)
@@ -591,10 +597,10 @@ func (t *findWeightedCriticalityTool) detectEntry() string {
 			}
 		}
 		t.logger.Info("detectEntry: selected entry from multiple candidates",
-			slog.String("name", name),
+			telemetry.SensitiveLog("name", name),
 			slog.Int("candidates", len(symbols)),
 			slog.String("selected", best.ID),
-			slog.String("file", best.FilePath),
+			telemetry.SensitiveLog("file", best.FilePath),
 			slog.Int("call_edges", bestCallCount),
 		)
 		return best.ID
@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -143,6 +144,15 @@ type CallChainNode struct {
 	// ExternalPkg is the inferred external package/module name.
 	// Only set when IsExternal is true.
 	ExternalPkg string `json:"external_package,omitempty"`
+
+	// Signature and Doc describe an external Go standard library symbol,
+	// from the embedded stdlib index. Only set when IsExternal is true.
+	Signature string `json:"signature,omitempty"`
+	Doc       string `json:"doc,omitempty"`
+
+	// ExternalMembers are the names used from an external package whose
+	// placeholders the graph collapsed. Only set when IsExternal is true.
+	ExternalMembers []string `json:"external_members,omitempty"`
 }
 
 // getCallChainTool wraps graph.GetCallGraph and GetReverseCallGraph.
@@ -225,6 +235,7 @@ func (t *getCallChainTool) Definition() ToolDefinition {
 				Type:        ParamTypeInt,
 				Description: "Maximum depth to traverse (1-10)",
 				Required:    false,
+				Minimum:     Bound(1),
 				Default:     5,
 			},
 		},
@@ -232,10 +243,15 @@ func (t *getCallChainTool) Definition() ToolDefinition {
 		Priority:    88,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     10 * time.Second,
 	}
 }
 
+func (t *getCallChainTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), GetCallChainOutput{})
+}
+
 // Execute runs the get_call_chain tool.
 func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -261,7 +277,7 @@ func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Re
 	ctx, span := getCallChainTracer.Start(ctx, "getCallChainTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "get_call_chain"),
-			attribute.String("function_name", p.FunctionName),
+			telemetry.SensitiveString("function_name", p.FunctionName),
 			attribute.String("direction", p.Direction),
 			attribute.Int("max_depth", p.MaxDepth),
 		),
@@ -282,8 +298,8 @@ func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Re
 			t.logger, 3, WithKindFilter(KindFilterAny), WithBareMethodFallback())
 		if resolveErr != nil {
 			t.logger.Debug("get_call_chain: symbol resolution failed",
-				slog.String("function_name", p.FunctionName),
-				slog.String("error", resolveErr.Error()),
+				telemetry.SensitiveLog("function_name", p.FunctionName),
+				telemetry.SensitiveLogError("error", resolveErr),
 			)
 		} else if len(candidates) > 0 {
 			symbolID = candidates[0].ID
@@ -298,8 +314,8 @@ func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Re
 		if len(nodes) == 1 {
 			symbolID = nodes[0].Symbol.ID
 			t.logger.Debug("get_call_chain: resolved via graph fallback",
-				slog.String("function_name", p.FunctionName),
-				slog.String("symbol_id", symbolID),
+				telemetry.SensitiveLog("function_name", p.FunctionName),
+				telemetry.SensitiveLog("symbol_id", symbolID),
 			)
 		} else if len(nodes) > 1 {
 			syms := make([]*ast.Symbol, len(nodes))
@@ -313,9 +329,9 @@ func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Re
 				if len(filtered) == 1 {
 					symbolID = filtered[0].ID
 					t.logger.Debug("get_call_chain: resolved via package hint",
-						slog.String("function_name", p.FunctionName),
-						slog.String("package_hint", p.PackageHint),
-						slog.String("symbol_id", symbolID),
+						telemetry.SensitiveLog("function_name", p.FunctionName),
+						telemetry.SensitiveLog("package_hint", p.PackageHint),
+						telemetry.SensitiveLog("symbol_id", symbolID),
 						slog.Int("candidates", len(nodes)),
 					)
 				} else {
@@ -331,8 +347,8 @@ func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Re
 				if best != nil {
 					symbolID = best.ID
 					t.logger.Debug("get_call_chain: resolved via graph fallback with disambiguation",
-						slog.String("function_name", p.FunctionName),
-						slog.String("symbol_id", symbolID),
+						telemetry.SensitiveLog("function_name", p.FunctionName),
+						telemetry.SensitiveLog("symbol_id", symbolID),
 						slog.Int("candidates", len(nodes)),
 					)
 				}
@@ -377,7 +393,7 @@ func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Re
 		parentMethodIDs := t.findParentMethodIDs(symbolID)
 		if len(parentMethodIDs) > 0 {
 			t.logger.Debug("get_call_chain: inheritance-aware upstream",
-				slog.String("symbol_id", symbolID),
+				telemetry.SensitiveLog("symbol_id", symbolID),
 				slog.Int("parent_methods", len(parentMethodIDs)),
 			)
 		}
@@ -398,7 +414,7 @@ func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Re
 	}
 
 	if gErr != nil {
-		span.RecordError(gErr)
+		telemetry.RecordException(span, gErr)
 		errStep := crs.NewTraceStepBuilder().
 			WithAction("tool_get_call_chain").
 			WithTarget(p.FunctionName).
@@ -429,7 +445,7 @@ func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Re
 	// retry cost is negligible.
 	if (traversal.Depth <= 1 || len(traversal.Edges) <= 1) && len(candidates) > 1 {
 		t.logger.Info("get_call_chain: shallow result, trying alternative candidates",
-			slog.String("function_name", p.FunctionName),
+			telemetry.SensitiveLog("function_name", p.FunctionName),
 			slog.String("first_candidate", symbolID),
 			slog.Int("alternatives", len(candidates)-1),
 		)
@@ -443,7 +459,7 @@ func (t *getCallChainTool) Execute(ctx context.Context, params TypedParams) (*Re
 			}
 			if altErr == nil && len(altTraversal.Edges) > 0 {
 				t.logger.Info("get_call_chain: alternative candidate produced results",
-					slog.String("function_name", p.FunctionName),
+					telemetry.SensitiveLog("function_name", p.FunctionName),
 					slog.String("alt_candidate", altCandidate.ID),
 					slog.String("alt_kind", altCandidate.Kind.String()),
 					slog.Int("edges", len(altTraversal.Edges)),
@@ -739,6 +755,9 @@ func (t *getCallChainTool) buildOutputWithDepths(functionName, direction string,
 			node.IsExternal = true
 			node.ExternalPkg = ext.Package
 			node.Name = ext.Name
+			node.Signature = ext.Signature
+			node.Doc = ext.Doc
+			node.ExternalMembers = ext.Members
 		}
 
 		if !node.IsExternal && t.index != nil {
@@ -756,10 +775,7 @@ func (t *getCallChainTool) buildOutputWithDepths(functionName, direction string,
 	if len(externals) > 0 {
 		seen := make(map[string]bool, len(externals))
 		for _, ext := range externals {
-			label := ext.Name
-			if ext.Package != "" {
-				label = ext.Package + "." + ext.Name
-			}
+			label := ext.Label()
 			if !seen[label] {
 				output.ExternalDependencies = append(output.ExternalDependencies, label)
 				seen[label] = true
@@ -834,12 +850,9 @@ func (t *getCallChainTool) formatTextWithDepths(functionName, direction string,
 		sb.WriteString("\n--- External Dependencies ---\n")
 		seen := make(map[string]bool, len(externals))
 		for _, ext := range externals {
-			label := ext.Name
-			if ext.Package != "" {
-				label = ext.Package + "." + ext.Name
-			}
+			label := ext.Label()
 			if !seen[label] {
-				sb.WriteString(fmt.Sprintf("  • %s (depth %d)\n", label, ext.Depth))
+				sb.WriteString(fmt.Sprintf("  • %s (depth %d)%s\n", label, ext.Depth, externalDetail(ext.Signature, ext.Doc, ext.Members)))
 				seen[label] = true
 			}
 		}
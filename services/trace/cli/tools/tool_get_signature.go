@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -81,6 +82,10 @@ type SignatureMatch struct {
 	// DocComment is the extracted documentation comment.
 	DocComment string `json:"doc_comment"`
 
+	// SyntheticDoc is a model-generated one-sentence summary, set only
+	// when the symbol has no DocComment. It is not from the source.
+	SyntheticDoc string `json:"synthetic_doc,omitempty"`
+
 	// Exported indicates if the symbol is publicly visible.
 	Exported bool `json:"exported"`
 
@@ -91,6 +96,22 @@ type SignatureMatch struct {
 	Receiver string `json:"receiver,omitempty"`
 }
 
+// syntheticDocLabel introduces a synthetic doc in tool text output, so it
+// is never mistaken for a comment written by the code's authors.
+const syntheticDocLabel = "Summary (generated, not from source)"
+
+// syntheticDoc returns the synthetic doc of sym's node in g, or "" if sym
+// has a real doc comment or g is nil.
+func syntheticDoc(g *graph.Graph, sym *ast.Symbol) string {
+	if g == nil || sym.DocComment != "" {
+		return ""
+	}
+	if node, ok := g.GetNode(sym.ID); ok {
+		return node.SyntheticDoc()
+	}
+	return ""
+}
+
 // getSignatureTool returns the signature and doc comment of a named symbol.
 //
 // Description:
@@ -163,6 +184,7 @@ func (t *getSignatureTool) Definition() ToolDefinition {
 		Priority:    91,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     5 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -179,6 +201,10 @@ func (t *getSignatureTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *getSignatureTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), GetSignatureOutput{})
+}
+
 // Execute runs the get_signature tool.
 func (t *getSignatureTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -204,7 +230,7 @@ func (t *getSignatureTool) Execute(ctx context.Context, params TypedParams) (*Re
 	_, span := getSignatureTracer.Start(ctx, "getSignatureTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "get_signature"),
-			attribute.String("name", p.Name),
+			telemetry.SensitiveString("name", p.Name),
 		),
 	)
 	defer span.End()
@@ -272,15 +298,16 @@ func (t *getSignatureTool) Execute(ctx context.Context, params TypedParams) (*Re
 		}
 
 		match := SignatureMatch{
-			Name:       sym.Name,
-			Kind:       sym.Kind.String(),
-			FilePath:   sym.FilePath,
-			StartLine:  sym.StartLine,
-			Signature:  sym.Signature,
-			DocComment: sym.DocComment,
-			Exported:   sym.Exported,
-			Package:    sym.Package,
-			Receiver:   sym.Receiver,
+			Name:         sym.Name,
+			Kind:         sym.Kind.String(),
+			FilePath:     sym.FilePath,
+			StartLine:    sym.StartLine,
+			Signature:    sym.Signature,
+			DocComment:   sym.DocComment,
+			SyntheticDoc: syntheticDoc(t.graph, sym),
+			Exported:     sym.Exported,
+			Package:      sym.Package,
+			Receiver:     sym.Receiver,
 		}
 		output.Matches = append(output.Matches, match)
 
@@ -300,6 +327,8 @@ func (t *getSignatureTool) Execute(ctx context.Context, params TypedParams) (*Re
 		}
 		if sym.DocComment != "" {
 			sb.WriteString(fmt.Sprintf("\nDoc: %s\n", sym.DocComment))
+		} else if match.SyntheticDoc != "" {
+			sb.WriteString(fmt.Sprintf("\n%s: %s\n", syntheticDocLabel, match.SyntheticDoc))
 		}
 		sb.WriteString("\n")
 	}
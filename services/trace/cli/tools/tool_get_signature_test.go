@@ -141,6 +141,34 @@ func TestGetSignatureTool_Execute(t *testing.T) {
 	})
 }
 
+func TestGetSignatureTool_SyntheticDoc(t *testing.T) {
+	ctx := context.Background()
+	g, idx, _ := createTestGraphWithSourceFiles(t)
+	for _, id := range []string{"pkg/config/config.go:15:Config", "pkg/config/config.go:7:ParseConfig"} {
+		node, ok := g.GetNode(id)
+		if !ok {
+			t.Fatalf("no node %s", id)
+		}
+		node.SetSyntheticDoc("Holds the settings.")
+	}
+	tool := NewGetSignatureTool(g, idx)
+
+	for name, want := range map[string]string{"Config": "Holds the settings.", "ParseConfig": ""} {
+		result, err := tool.Execute(ctx, MapParams{Params: map[string]any{"name": name}})
+		if err != nil || !result.Success {
+			t.Fatalf("Execute(%s) = %v, %v", name, result, err)
+		}
+		match := result.Output.(GetSignatureOutput).Matches[0]
+		if match.SyntheticDoc != want {
+			t.Errorf("%s: SyntheticDoc = %q, want %q", name, match.SyntheticDoc, want)
+		}
+		labeled := strings.Contains(result.OutputText, syntheticDocLabel+": Holds the settings.")
+		if labeled != (want != "") {
+			t.Errorf("%s: labeled synthetic doc in text = %t:\n%s", name, labeled, result.OutputText)
+		}
+	}
+}
+
 func TestGetSignatureTool_StaticDefinitions(t *testing.T) {
 	defs := StaticToolDefinitions()
 	found := false
@@ -0,0 +1,301 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// =============================================================================
+// graph_query Tool
+// =============================================================================
+
+var graphQueryTracer = otel.Tracer("tools.graph_query")
+
+// GraphQueryParams contains the validated input parameters.
+type GraphQueryParams struct {
+	// Query is the structured query.
+	Query graph.StructuredQuery
+
+	// Explain returns the estimated traversal size instead of rows.
+	Explain bool
+}
+
+// ToolName returns the tool name for TypedParams interface.
+func (p GraphQueryParams) ToolName() string { return "graph_query" }
+
+// ToMap converts typed parameters to the map consumed by Tool.Execute().
+func (p GraphQueryParams) ToMap() map[string]any {
+	m := map[string]any{"query": p.Query}
+	if p.Explain {
+		m["explain"] = true
+	}
+	return m
+}
+
+// graphQueryTool runs structured queries over the graph.
+//
+// Description:
+//
+//	An advanced tool for questions no dedicated tool answers, such as
+//	"exported functions in package X that call anything in package Y".
+//	Wraps graph.RunStructuredQuery, so the same limits as the HTTP
+//	endpoint apply and a query that reaches one returns partial rows.
+//
+// Thread Safety: Safe for concurrent use. All operations are read-only.
+type graphQueryTool struct {
+	graph *graph.Graph
+}
+
+// NewGraphQueryTool creates the graph_query tool.
+//
+// Inputs:
+//
+//   - g: The frozen code graph. Must not be nil.
+//
+// Outputs:
+//
+//   - Tool: The graph_query tool implementation.
+func NewGraphQueryTool(g *graph.Graph) Tool {
+	return &graphQueryTool{graph: g}
+}
+
+func (t *graphQueryTool) Name() string {
+	return "graph_query"
+}
+
+func (t *graphQueryTool) Category() ToolCategory {
+	return CategoryExploration
+}
+
+func (t *graphQueryTool) Definition() ToolDefinition {
+	return graphQueryDefinition()
+}
+
+func (t *graphQueryTool) Schema() ToolSchema {
+	schema := NewToolSchema(t.Definition(), graph.StructuredQueryResult{})
+	schema.Output = &JSONSchema{
+		Description: "Query rows, or the query plan when explain is set.",
+		AnyOf:       []*JSONSchema{schema.Output, NewToolSchema(t.Definition(), graph.QueryPlan{}).Output},
+	}
+	return schema
+}
+
+// graphQueryDefinition is shared by the tool and StaticToolDefinitions.
+func graphQueryDefinition() ToolDefinition {
+	return ToolDefinition{
+		Name: "graph_query",
+		Description: "Advanced: run a structured query over the code graph. " +
+			"'match' selects start symbols by kinds, name_regex, file_glob, languages, package and exported. " +
+			"'traverse' is a list of steps, each following edge_types (calls, imports, defines, implements, embeds, " +
+			"references, returns, receives, parameters) in direction out, in or both for min_depth..max_depth " +
+			"and keeping nodes that pass 'where'. 'select' is 'end' (nodes reached) or 'start' (matched symbols " +
+			"that reach any). Example: {\"match\": {\"kinds\": [\"function\"], \"package\": \"auth\", \"exported\": true}, " +
+			"\"traverse\": [{\"edge_types\": [\"calls\"], \"where\": {\"package\": \"db\"}}], \"select\": \"start\"}.",
+		Parameters: map[string]ParamDef{
+			"query": {
+				Type:        ParamTypeObject,
+				Description: "The structured query: match, traverse, select, fields and limits.",
+				Required:    true,
+			},
+			"explain": {
+				Type:        ParamTypeBool,
+				Description: "Estimate the traversal size instead of running the query.",
+				Required:    false,
+				Default:     false,
+			},
+		},
+		Category: CategoryExploration,
+		// Below the dedicated graph tools: prefer them when they fit.
+		Priority:    40,
+		Requires:    []string{"graph_initialized"},
+		SideEffects: false,
+		// A query cut short by its timeout isn't reproducible; never
+		// serve it from the result cache.
+		Cacheable: false,
+		Timeout:   graph.MaxQueryTimeout + time.Second,
+		WhenToUse: WhenToUse{
+			Keywords: []string{
+				"graph query", "that call anything in", "all exported", "in package",
+				"which functions", "reachable from", "structured query",
+			},
+			UseWhen: "User asks a question combining filters and relationships that no single " +
+				"tool answers, e.g. 'exported functions in package X that call into package Y'.",
+			AvoidWhen: "A dedicated tool fits — use find_callers, find_callees, find_references, " +
+				"find_implementations or find_symbol instead.",
+		},
+	}
+}
+
+// Execute runs the graph_query tool.
+func (t *graphQueryTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
+	start := time.Now()
+
+	p, err := t.parseParams(params.ToMap())
+	if err != nil {
+		return graphQueryError(err, start), nil
+	}
+
+	ctx, span := graphQueryTracer.Start(ctx, "graphQueryTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "graph_query"),
+			attribute.Int("steps", len(p.Query.Traverse)),
+			attribute.Bool("explain", p.Explain),
+		),
+	)
+	defer span.End()
+
+	var (
+		output      any
+		sb          strings.Builder
+		resultCount int
+	)
+	if p.Explain {
+		plan, err := t.graph.ExplainStructuredQuery(&p.Query)
+		if err != nil {
+			return graphQueryError(err, start), nil
+		}
+		output = plan
+		sb.WriteString(fmt.Sprintf("## Query plan: %d start nodes, ~%d nodes visited (limit %d)\n\n",
+			plan.StartNodes, plan.EstimatedVisited, plan.Limits.MaxVisited))
+		for i, step := range plan.Steps {
+			sb.WriteString(fmt.Sprintf("- step %d: %s %s up to depth %d, fan-out %.2f, ~%d nodes\n",
+				i+1, strings.Join(step.EdgeTypes, ","), step.Direction, step.MaxDepth, step.AvgFanout, step.EstimatedNodes))
+		}
+		if !plan.WithinLimits {
+			sb.WriteString("\nThe estimate exceeds the visit limit; narrow 'match' or reduce max_depth.\n")
+		}
+	} else {
+		result, err := t.graph.RunStructuredQuery(ctx, &p.Query)
+		if err != nil {
+			return graphQueryError(err, start), nil
+		}
+		output = result
+		resultCount = len(result.Rows)
+		span.SetAttributes(attribute.Int("rows", resultCount), attribute.Int("visited", result.Visited))
+
+		if resultCount == 0 && !result.Truncated {
+			sb.WriteString("## GRAPH RESULT: No symbols match the query\n\n")
+			sb.WriteString("The graph was searched and nothing matched. This is the definitive answer.\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("## Query results (%d rows, %d nodes visited)\n\n", resultCount, result.Visited))
+			for _, row := range result.Rows {
+				sb.WriteString("- " + formatQueryRow(row) + "\n")
+			}
+			if result.Truncated {
+				sb.WriteString(fmt.Sprintf("\nTruncated: the %s limit was reached; results are partial.\n", result.LimitReached))
+			}
+		}
+	}
+
+	duration := time.Since(start)
+	toolStep := crs.NewTraceStepBuilder().
+		WithAction("tool_graph_query").
+		WithTool("graph_query").
+		WithDuration(duration).
+		WithMetadata("result_count", fmt.Sprintf("%d", resultCount)).
+		Build()
+
+	return &Result{
+		Success:     true,
+		Output:      output,
+		OutputText:  sb.String(),
+		TokensUsed:  estimateTokens(sb.String()),
+		TraceStep:   &toolStep,
+		Duration:    duration,
+		ResultCount: resultCount,
+	}, nil
+}
+
+// parseParams validates and extracts typed parameters.
+//
+// The query may be an object or a JSON string. Unknown fields are
+// rejected so a misspelled filter isn't silently ignored.
+func (t *graphQueryTool) parseParams(params map[string]any) (GraphQueryParams, error) {
+	var p GraphQueryParams
+
+	raw, ok := params["query"]
+	if !ok || raw == nil {
+		return p, fmt.Errorf("'query' parameter is required")
+	}
+	var data []byte
+	if s, ok := raw.(string); ok {
+		data = []byte(s)
+	} else {
+		var err error
+		if data, err = json.Marshal(raw); err != nil {
+			return p, fmt.Errorf("'query' must be a JSON object: %w", err)
+		}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p.Query); err != nil {
+		return p, fmt.Errorf("'query' must be a structured query object: %w", err)
+	}
+
+	if raw, ok := params["explain"]; ok {
+		if b, ok := parseBoolParam(raw); ok {
+			p.Explain = b
+		}
+	}
+	return p, nil
+}
+
+// graphQueryError builds the failed result for an invalid query.
+func graphQueryError(err error, start time.Time) *Result {
+	errStep := crs.NewTraceStepBuilder().
+		WithAction("tool_graph_query").
+		WithTool("graph_query").
+		WithDuration(time.Since(start)).
+		WithError(err.Error()).
+		Build()
+	return &Result{
+		Success:   false,
+		Error:     err.Error(),
+		TraceStep: &errStep,
+		Duration:  time.Since(start),
+	}
+}
+
+// formatQueryRow renders one row, preferring name, kind and location.
+func formatQueryRow(row map[string]any) string {
+	name, hasName := row["name"]
+	if !hasName {
+		name = row["id"]
+	}
+	if name == nil {
+		data, _ := json.Marshal(row)
+		return string(data)
+	}
+	s := fmt.Sprint(name)
+	if kind, ok := row["kind"]; ok {
+		s += fmt.Sprintf(" (%v)", kind)
+	}
+	if file, ok := row["file_path"]; ok {
+		s += fmt.Sprintf(" %v", file)
+		if line, ok := row["start_line"]; ok {
+			s += fmt.Sprintf(":%v", line)
+		}
+	}
+	return s
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// buildGraphQueryTestGraph builds auth.Login -> db.Query and auth.logout.
+func buildGraphQueryTestGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	g := graph.NewGraph("/test/project")
+	for _, sym := range []*ast.Symbol{
+		{ID: "auth/login.go:1:Login", Name: "Login", Kind: ast.SymbolKindFunction, Package: "auth", FilePath: "auth/login.go", StartLine: 1, EndLine: 5, Language: "go", Exported: true},
+		{ID: "auth/login.go:9:logout", Name: "logout", Kind: ast.SymbolKindFunction, Package: "auth", FilePath: "auth/login.go", StartLine: 9, EndLine: 12, Language: "go"},
+		{ID: "db/query.go:1:Query", Name: "Query", Kind: ast.SymbolKindFunction, Package: "db", FilePath: "db/query.go", StartLine: 1, EndLine: 8, Language: "go", Exported: true},
+	} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode() error = %v", err)
+		}
+	}
+	if err := g.AddEdge("auth/login.go:1:Login", "db/query.go:1:Query", graph.EdgeTypeCalls, ast.Location{FilePath: "auth/login.go", StartLine: 3}); err != nil {
+		t.Fatalf("AddEdge() error = %v", err)
+	}
+	g.Freeze()
+	return g
+}
+
+func TestGraphQueryTool_Execute(t *testing.T) {
+	ctx := context.Background()
+	tool := NewGraphQueryTool(buildGraphQueryTestGraph(t))
+
+	t.Run("accepts the query as an object", func(t *testing.T) {
+		query := map[string]any{
+			"match":    map[string]any{"package": "auth"},
+			"traverse": []any{map[string]any{"edge_types": []any{"calls"}, "where": map[string]any{"package": "db"}}},
+			"select":   "start",
+		}
+		result, err := tool.Execute(ctx, MapParams{Tool: "graph_query", Params: map[string]any{"query": query}})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success || result.ResultCount != 1 {
+			t.Fatalf("Execute() = %+v, want one row", result)
+		}
+		if !strings.Contains(result.OutputText, "Login (function) auth/login.go:1") {
+			t.Errorf("OutputText = %q", result.OutputText)
+		}
+	})
+
+	t.Run("accepts the query as a JSON string and explains it", func(t *testing.T) {
+		params := map[string]any{
+			"query":   `{"match": {"kinds": ["function"]}, "traverse": [{"edge_types": ["calls"]}]}`,
+			"explain": true,
+		}
+		result, err := tool.Execute(ctx, MapParams{Tool: "graph_query", Params: params})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		plan, ok := result.Output.(*graph.QueryPlan)
+		if !result.Success || !ok || plan.StartNodes != 3 {
+			t.Fatalf("Execute() = %+v, want a plan with 3 start nodes", result)
+		}
+	})
+
+	t.Run("reports partial results", func(t *testing.T) {
+		result, err := tool.Execute(ctx, GraphQueryParams{Query: graph.StructuredQuery{Limits: graph.QueryLimits{MaxResults: 1}}})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success || result.ResultCount != 1 || !strings.Contains(result.OutputText, "max_results limit was reached") {
+			t.Errorf("Execute() = %+v", result)
+		}
+	})
+
+	t.Run("rejects unknown and invalid fields", func(t *testing.T) {
+		for _, query := range []any{
+			map[string]any{"match": map[string]any{"pkg": "auth"}},
+			map[string]any{"traverse": []any{map[string]any{"edge_types": []any{"likes"}}}},
+		} {
+			result, err := tool.Execute(ctx, MapParams{Tool: "graph_query", Params: map[string]any{"query": query}})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Success {
+				t.Errorf("Execute(%v) succeeded, want an error", query)
+			}
+		}
+	})
+}
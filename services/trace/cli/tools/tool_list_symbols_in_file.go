@@ -25,6 +25,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -150,12 +151,14 @@ func (t *listSymbolsInFileTool) Definition() ToolDefinition {
 				Type:        ParamTypeString,
 				Description: "File path relative to project root (e.g., 'src/main.go', 'pandas/core/frame.py')",
 				Required:    true,
+				Format:      ParamFormatProjectPath,
 			},
 		},
 		Category:    CategoryExploration,
 		Priority:    90,
 		Requires:    []string{"graph_initialized"},
 		SideEffects: false,
+		Cacheable:   true,
 		Timeout:     5 * time.Second,
 		WhenToUse: WhenToUse{
 			Keywords: []string{
@@ -172,6 +175,10 @@ func (t *listSymbolsInFileTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *listSymbolsInFileTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), ListSymbolsInFileOutput{})
+}
+
 // Execute runs the list_symbols_in_file tool.
 func (t *listSymbolsInFileTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -197,7 +204,7 @@ func (t *listSymbolsInFileTool) Execute(ctx context.Context, params TypedParams)
 	_, span := listSymbolsInFileTracer.Start(ctx, "listSymbolsInFileTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "list_symbols_in_file"),
-			attribute.String("path", p.Path),
+			telemetry.SensitiveString("path", p.Path),
 		),
 	)
 	defer span.End()
@@ -13,6 +13,7 @@ package tools
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -26,6 +27,8 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
 )
 
 // =============================================================================
@@ -154,6 +157,7 @@ func (t *readFileTool) Definition() ToolDefinition {
 				Type:        ParamTypeString,
 				Description: "File path relative to project root (e.g., 'src/main.go', 'pandas/io/parsers/readers.py')",
 				Required:    true,
+				Format:      ParamFormatProjectPath,
 			},
 			"start_line": {
 				Type:        ParamTypeInt,
@@ -188,6 +192,10 @@ func (t *readFileTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *readFileTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), ReadFileOutput{})
+}
+
 // Execute runs the read_file tool.
 func (t *readFileTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -213,18 +221,18 @@ func (t *readFileTool) Execute(ctx context.Context, params TypedParams) (*Result
 	ctx, span := readFileTracer.Start(ctx, "readFileTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "read_file"),
-			attribute.String("path", p.Path),
+			telemetry.SensitiveString("path", p.Path),
 			attribute.Int("start_line", p.StartLine),
 			attribute.Int("end_line", p.EndLine),
 		),
 	)
 	defer span.End()
 
-	// Security: resolve and validate path
-	absPath := filepath.Join(t.graph.ProjectRoot, p.Path)
-	resolved, err := filepath.Abs(absPath)
-	if err != nil {
-		span.RecordError(err)
+	// Security: resolve and validate path. Symlinks are resolved so a link
+	// inside the project that points outside it is rejected too.
+	resolved, err := validation.ResolveWithin(t.graph.ProjectRoot, p.Path)
+	if err != nil && !errors.Is(err, validation.ErrPathTraversal) {
+		telemetry.RecordException(span, err)
 		errStep := crs.NewTraceStepBuilder().
 			WithAction("tool_read_file").
 			WithTarget(p.Path).
@@ -240,13 +248,7 @@ func (t *readFileTool) Execute(ctx context.Context, params TypedParams) (*Result
 		}, nil
 	}
 
-	projectRoot, err := filepath.Abs(t.graph.ProjectRoot)
 	if err != nil {
-		span.RecordError(err)
-		return nil, fmt.Errorf("resolving project root: %w", err)
-	}
-
-	if !strings.HasPrefix(resolved, projectRoot+string(filepath.Separator)) && resolved != projectRoot {
 		errMsg := fmt.Sprintf("path traversal rejected: '%s' resolves outside project root", p.Path)
 		span.SetAttributes(attribute.Bool("path_traversal_blocked", true))
 		errStep := crs.NewTraceStepBuilder().
@@ -267,7 +269,7 @@ func (t *readFileTool) Execute(ctx context.Context, params TypedParams) (*Result
 	// Read the file
 	f, err := os.Open(resolved)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		errStep := crs.NewTraceStepBuilder().
 			WithAction("tool_read_file").
 			WithTarget(p.Path).
@@ -295,7 +297,7 @@ func (t *readFileTool) Execute(ctx context.Context, params TypedParams) (*Result
 		}
 	}
 	if scanErr := scanner.Err(); scanErr != nil {
-		span.RecordError(scanErr)
+		telemetry.RecordException(span, scanErr)
 		return nil, fmt.Errorf("reading %s: %w", p.Path, scanErr)
 	}
 
@@ -13,10 +13,10 @@ package tools
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
@@ -28,6 +28,8 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
 )
 
 // =============================================================================
@@ -213,6 +215,10 @@ func (t *readSymbolTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *readSymbolTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), ReadSymbolOutput{})
+}
+
 // Execute runs the read_symbol tool.
 func (t *readSymbolTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
 	start := time.Now()
@@ -238,9 +244,9 @@ func (t *readSymbolTool) Execute(ctx context.Context, params TypedParams) (*Resu
 	ctx, span := readSymbolTracer.Start(ctx, "readSymbolTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "read_symbol"),
-			attribute.String("name", p.Name),
+			telemetry.SensitiveString("name", p.Name),
 			attribute.String("kind", p.Kind),
-			attribute.String("package_hint", p.PackageHint),
+			telemetry.SensitiveString("package_hint", p.PackageHint),
 		),
 	)
 	defer span.End()
@@ -335,8 +341,8 @@ func (t *readSymbolTool) Execute(ctx context.Context, params TypedParams) (*Resu
 		if readErr != nil {
 			t.logger.Warn("failed to read source file",
 				slog.String("tool", "read_symbol"),
-				slog.String("file", sym.FilePath),
-				slog.String("error", readErr.Error()),
+				telemetry.SensitiveLog("file", sym.FilePath),
+				telemetry.SensitiveLogError("error", readErr),
 			)
 			match.Source = fmt.Sprintf("(error reading source: %s)", readErr.Error())
 		} else {
@@ -425,19 +431,13 @@ func (t *readSymbolTool) parseParams(params map[string]any) (ReadSymbolParams, e
 //   - string: The source code lines joined with newlines.
 //   - error: Non-nil if file cannot be read or path is invalid.
 func (t *readSymbolTool) readSourceLines(filePath string, startLine, endLine int) (string, error) {
-	absPath := filepath.Join(t.graph.ProjectRoot, filePath)
-
-	// Security: validate path is under project root
-	resolved, err := filepath.Abs(absPath)
-	if err != nil {
-		return "", fmt.Errorf("resolving path %s: %w", filePath, err)
+	// Security: validate path is under project root, symlinks resolved
+	resolved, err := validation.ResolveWithin(t.graph.ProjectRoot, filePath)
+	if errors.Is(err, validation.ErrPathTraversal) {
+		return "", fmt.Errorf("path traversal rejected: %s resolves outside project root", filePath)
 	}
-	projectRoot, err := filepath.Abs(t.graph.ProjectRoot)
 	if err != nil {
-		return "", fmt.Errorf("resolving project root: %w", err)
-	}
-	if !strings.HasPrefix(resolved, projectRoot+string(filepath.Separator)) && resolved != projectRoot {
-		return "", fmt.Errorf("path traversal rejected: %s resolves outside project root", filePath)
+		return "", fmt.Errorf("resolving path %s: %w", filePath, err)
 	}
 
 	f, err := os.Open(resolved)
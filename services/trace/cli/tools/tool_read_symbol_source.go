@@ -0,0 +1,255 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// =============================================================================
+// read_symbol_source Tool
+// =============================================================================
+
+var readSymbolSourceTracer = otel.Tracer("tools.read_symbol_source")
+
+// defaultSymbolSourceContextLines is the context used when none is given.
+const defaultSymbolSourceContextLines = 5
+
+// ReadSymbolSourceParams contains the validated input parameters.
+type ReadSymbolSourceParams struct {
+	// SymbolID is the exact ID of the symbol to read.
+	SymbolID string
+
+	// ContextLines is the number of lines before and after the symbol.
+	ContextLines int
+}
+
+// ToolName returns the tool name for TypedParams interface.
+func (p ReadSymbolSourceParams) ToolName() string { return "read_symbol_source" }
+
+// ToMap converts typed parameters to the map consumed by Tool.Execute().
+func (p ReadSymbolSourceParams) ToMap() map[string]any {
+	return map[string]any{
+		"symbol_id":     p.SymbolID,
+		"context_lines": p.ContextLines,
+	}
+}
+
+// readSymbolSourceTool reads the source of a symbol by its exact ID.
+//
+// Description:
+//
+//	Complements read_symbol (name lookup) when the caller already has a
+//	symbol ID from another tool. Uses graph.Graph.SymbolSource, so path
+//	validation, byte limits and the file hash match the HTTP endpoint.
+//
+// Thread Safety: Safe for concurrent use. All operations are read-only.
+type readSymbolSourceTool struct {
+	graph  *graph.Graph
+	index  *index.SymbolIndex
+	logger *slog.Logger
+}
+
+// NewReadSymbolSourceTool creates the read_symbol_source tool.
+//
+// Inputs:
+//
+//   - g: The code graph. Must not be nil.
+//   - idx: The symbol index. Must not be nil.
+//
+// Outputs:
+//
+//   - Tool: The read_symbol_source tool implementation.
+//
+// Assumptions:
+//
+//   - Graph is frozen before tool creation
+//   - Project files are accessible at graph.ProjectRoot
+func NewReadSymbolSourceTool(g *graph.Graph, idx *index.SymbolIndex) Tool {
+	return &readSymbolSourceTool{
+		graph:  g,
+		index:  idx,
+		logger: slog.Default(),
+	}
+}
+
+func (t *readSymbolSourceTool) Name() string {
+	return "read_symbol_source"
+}
+
+func (t *readSymbolSourceTool) Category() ToolCategory {
+	return CategoryExploration
+}
+
+func (t *readSymbolSourceTool) Definition() ToolDefinition {
+	return readSymbolSourceDefinition()
+}
+
+func (t *readSymbolSourceTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), graph.SymbolSource{})
+}
+
+// readSymbolSourceDefinition is shared by the tool and StaticToolDefinitions.
+func readSymbolSourceDefinition() ToolDefinition {
+	return ToolDefinition{
+		Name: "read_symbol_source",
+		Description: "Read the source code of a symbol by its exact symbol ID, with optional surrounding context lines. " +
+			"Returns the file path, line range, source text and a file hash for staleness checks. " +
+			"Very large symbols are truncated with a marker.",
+		Parameters: map[string]ParamDef{
+			"symbol_id": {
+				Type:        ParamTypeString,
+				Description: "Exact symbol ID as returned by other tools (e.g., 'pkg/config/config.go:7:ParseConfig')",
+				Required:    true,
+				Format:      ParamFormatSymbolID,
+			},
+			"context_lines": {
+				Type:        ParamTypeInt,
+				Description: "Lines of context before and after the symbol (default 5, max 100)",
+				Required:    false,
+				Default:     defaultSymbolSourceContextLines,
+				Minimum:     Bound(0),
+				Maximum:     Bound(graph.MaxSymbolSourceContextLines),
+			},
+		},
+		Category:    CategoryExploration,
+		Priority:    90,
+		Requires:    []string{"graph_initialized"},
+		SideEffects: false,
+		Timeout:     5 * time.Second,
+		WhenToUse: WhenToUse{
+			Keywords: []string{
+				"symbol id", "source of symbol", "surrounding code", "context around",
+			},
+			UseWhen: "A previous tool returned a symbol ID and the source of that exact symbol " +
+				"(optionally with surrounding lines) is needed.",
+			AvoidWhen: "Only a symbol name is known — use read_symbol. " +
+				"Reading an arbitrary file range — use read_file.",
+		},
+	}
+}
+
+// Execute runs the read_symbol_source tool.
+func (t *readSymbolSourceTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
+	start := time.Now()
+
+	p, err := t.parseParams(params.ToMap())
+	if err != nil {
+		errStep := crs.NewTraceStepBuilder().
+			WithAction("tool_read_symbol_source").
+			WithTool("read_symbol_source").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return &Result{
+			Success:   false,
+			Error:     err.Error(),
+			TraceStep: &errStep,
+			Duration:  time.Since(start),
+		}, nil
+	}
+
+	_, span := readSymbolSourceTracer.Start(ctx, "readSymbolSourceTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "read_symbol_source"),
+			telemetry.SensitiveString("symbol_id", p.SymbolID),
+			attribute.Int("context_lines", p.ContextLines),
+		),
+	)
+	defer span.End()
+
+	src, err := t.graph.SymbolSource(p.SymbolID, p.ContextLines, graph.DefaultSymbolSourceMaxBytes)
+	if err != nil {
+		t.logger.Warn("failed to read symbol source",
+			slog.String("tool", "read_symbol_source"),
+			telemetry.SensitiveLog("symbol_id", p.SymbolID),
+			telemetry.SensitiveLogError("error", err),
+		)
+		errStep := crs.NewTraceStepBuilder().
+			WithAction("tool_read_symbol_source").
+			WithTarget(p.SymbolID).
+			WithTool("read_symbol_source").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return &Result{
+			Success:   false,
+			Error:     err.Error(),
+			TraceStep: &errStep,
+			Duration:  time.Since(start),
+		}, nil
+	}
+
+	span.SetAttributes(
+		attribute.Int("source_bytes", src.SourceBytes),
+		attribute.Bool("truncated", src.Truncated),
+	)
+
+	outputText := fmt.Sprintf("### `%s` in `%s:%d-%d` (showing %d-%d)\n```%s\n%s\n```\n",
+		src.SymbolID, src.FilePath, src.StartLine, src.EndLine,
+		src.FromLine, src.ToLine, src.Language, src.Source)
+
+	duration := time.Since(start)
+	toolStep := crs.NewTraceStepBuilder().
+		WithAction("tool_read_symbol_source").
+		WithTarget(p.SymbolID).
+		WithTool("read_symbol_source").
+		WithDuration(duration).
+		WithMetadata("truncated", fmt.Sprintf("%t", src.Truncated)).
+		Build()
+
+	return &Result{
+		Success:     true,
+		Output:      *src,
+		OutputText:  outputText,
+		TokensUsed:  estimateTokens(outputText),
+		TraceStep:   &toolStep,
+		Duration:    duration,
+		ResultCount: 1,
+	}, nil
+}
+
+// parseParams validates and extracts typed parameters.
+func (t *readSymbolSourceTool) parseParams(params map[string]any) (ReadSymbolSourceParams, error) {
+	p := ReadSymbolSourceParams{ContextLines: defaultSymbolSourceContextLines}
+
+	if raw, ok := params["symbol_id"]; ok {
+		if id, ok := parseStringParam(raw); ok {
+			p.SymbolID = id
+		}
+	}
+	if p.SymbolID == "" {
+		return p, fmt.Errorf("symbol_id is required")
+	}
+
+	if raw, ok := params["context_lines"]; ok {
+		if n, ok := parseIntParam(raw); ok {
+			if n < 0 || n > graph.MaxSymbolSourceContextLines {
+				return p, fmt.Errorf("context_lines must be between 0 and %d", graph.MaxSymbolSourceContextLines)
+			}
+			p.ContextLines = n
+		}
+	}
+
+	return p, nil
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+func TestReadSymbolSourceTool_Execute(t *testing.T) {
+	ctx := context.Background()
+	g, idx, _ := createTestGraphWithSourceFiles(t)
+	tool := NewReadSymbolSourceTool(g, idx)
+
+	t.Run("reads symbol with context", func(t *testing.T) {
+		result, err := tool.Execute(ctx, ReadSymbolSourceParams{
+			SymbolID:     "pkg/config/config.go:7:ParseConfig",
+			ContextLines: 2,
+		})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("Execute() failed: %s", result.Error)
+		}
+		src, ok := result.Output.(graph.SymbolSource)
+		if !ok {
+			t.Fatalf("Output is not graph.SymbolSource, got %T", result.Output)
+		}
+		if src.FromLine != 5 || src.ToLine != 15 {
+			t.Errorf("range = %d-%d, want 5-15", src.FromLine, src.ToLine)
+		}
+		if !strings.Contains(src.Source, "// ParseConfig reads") {
+			t.Error("expected leading context lines in source")
+		}
+		if src.FileHash == "" {
+			t.Error("expected file hash")
+		}
+	})
+
+	t.Run("unknown symbol fails", func(t *testing.T) {
+		result, err := tool.Execute(ctx, ReadSymbolSourceParams{SymbolID: "nope"})
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for unknown symbol")
+		}
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		for _, params := range []map[string]any{
+			{},
+			{"symbol_id": "x", "context_lines": -1},
+		} {
+			result, err := tool.Execute(ctx, MapParams{Params: params})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Success {
+				t.Errorf("expected validation failure for %v", params)
+			}
+		}
+	})
+}
+
+func TestReadSymbolSourceTool_StaticDefinitions(t *testing.T) {
+	for _, def := range StaticToolDefinitions() {
+		if def.Name == "read_symbol_source" {
+			if _, ok := def.Parameters["symbol_id"]; !ok {
+				t.Error("missing 'symbol_id' parameter")
+			}
+			return
+		}
+	}
+	t.Error("read_symbol_source not found in StaticToolDefinitions")
+}
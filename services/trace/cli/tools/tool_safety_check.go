@@ -0,0 +1,171 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+)
+
+// =============================================================================
+// safety_check Tool
+// =============================================================================
+
+// safetyCheckTool explains how the safety policy would treat a tool call.
+//
+// Description:
+//
+//	Evaluates a proposed call against the safety gate's policy and reports
+//	the decision and the rule that decided it. The proposed call is never
+//	executed.
+//
+// Thread Safety: Safe for concurrent use if the evaluator is.
+type safetyCheckTool struct {
+	evaluator safety.Evaluator
+	mode      string
+}
+
+// NewSafetyCheckTool creates the safety_check tool.
+//
+// Inputs:
+//
+//   - evaluator: The safety gate to consult. Must not be nil.
+//   - mode: The run mode of the session; empty uses the policy default.
+//
+// Outputs:
+//
+//   - Tool: The safety_check tool implementation.
+func NewSafetyCheckTool(evaluator safety.Evaluator, mode string) Tool {
+	return &safetyCheckTool{evaluator: evaluator, mode: mode}
+}
+
+func (t *safetyCheckTool) Name() string {
+	return "safety_check"
+}
+
+func (t *safetyCheckTool) Category() ToolCategory {
+	return CategorySafety
+}
+
+func (t *safetyCheckTool) Definition() ToolDefinition {
+	return ToolDefinition{
+		Name: "safety_check",
+		Description: "Dry-run a tool call against the safety policy without executing it. " +
+			"Returns allow or deny and the ID of the rule that decided. " +
+			"Call before writing, deleting, running commands or planning large multi-file changes.",
+		Parameters: map[string]ParamDef{
+			"tool": {
+				Type:        ParamTypeString,
+				Description: "Name of the tool you intend to call (e.g., 'write_file', 'run_command')",
+				Required:    true,
+			},
+			"params": {
+				Type:        ParamTypeObject,
+				Description: "Parameters you intend to pass to the tool (e.g., {\"path\": \"cmd/main.go\"})",
+			},
+			"files_touched": {
+				Type:        ParamTypeInt,
+				Description: "For a change plan: number of files it changes",
+			},
+			"symbols_modified": {
+				Type:        ParamTypeInt,
+				Description: "For a change plan: number of symbols it changes",
+			},
+		},
+		Category:    CategorySafety,
+		Priority:    60,
+		SideEffects: false,
+		Timeout:     2 * time.Second,
+		WhenToUse: WhenToUse{
+			Keywords: []string{"safety", "allowed", "permitted", "policy", "dry run", "blocked"},
+			UseWhen:  "Before a risky operation, to check whether the safety policy permits it.",
+			AvoidWhen: "For read-only graph queries, which the policy rarely restricts — " +
+				"call the query tool directly.",
+		},
+	}
+}
+
+func (t *safetyCheckTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), safety.Decision{})
+}
+
+// Execute runs the safety_check tool.
+func (t *safetyCheckTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
+	start := time.Now()
+	call, err := t.parseParams(params.ToMap())
+	if err != nil {
+		return &Result{Success: false, Error: err.Error(), Duration: time.Since(start)}, nil
+	}
+
+	decision, err := t.evaluator.Evaluate(ctx, call)
+	if err != nil {
+		return &Result{Success: false, Error: err.Error(), Duration: time.Since(start)}, nil
+	}
+
+	var sb strings.Builder
+	verdict := "ALLOWED"
+	if !decision.Allowed {
+		verdict = "DENIED"
+	}
+	sb.WriteString(fmt.Sprintf("## Safety check: %s is %s (%s mode)\n\n", decision.Tool, verdict, decision.Mode))
+	sb.WriteString(fmt.Sprintf("Rule: %s\nReason: %s\n", decision.RuleID, decision.Reason))
+	if len(decision.Matches) > 1 {
+		sb.WriteString("\nMatched rules:\n")
+		for _, m := range decision.Matches {
+			sb.WriteString(fmt.Sprintf("- %s (%s): %s\n", m.RuleID, m.Decision, m.Reason))
+		}
+	}
+	sb.WriteString("\nThe call was NOT executed.\n")
+
+	return &Result{
+		Success:    true,
+		Output:     decision,
+		OutputText: sb.String(),
+		TokensUsed: estimateTokens(sb.String()),
+		Duration:   time.Since(start),
+	}, nil
+}
+
+// parseParams builds the call to evaluate.
+func (t *safetyCheckTool) parseParams(params map[string]any) (safety.ToolCall, error) {
+	call := safety.ToolCall{Mode: t.mode}
+	call.Tool, _ = params["tool"].(string)
+	if call.Tool == "" {
+		return call, fmt.Errorf("tool is required")
+	}
+
+	call.Params, _ = params["params"].(map[string]any)
+
+	files, hasFiles := params["files_touched"]
+	symbols, hasSymbols := params["symbols_modified"]
+	if hasFiles || hasSymbols {
+		call.Plan = &safety.PlanImpact{FilesTouched: toInt(files), SymbolsModified: toInt(symbols)}
+	}
+	return call, nil
+}
+
+// toInt converts a numeric parameter that may arrive as int or float64.
+func toInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	default:
+		return 0
+	}
+}
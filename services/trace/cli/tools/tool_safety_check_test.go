@@ -0,0 +1,76 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+)
+
+func newSafetyCheckTestTool(t *testing.T, mode string) Tool {
+	t.Helper()
+	policy, err := safety.ParsePolicy([]byte("tools:\n  readonly:\n    deny: [write_file]\nblast_radius:\n  max_files: 3\n"))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	return NewSafetyCheckTool(safety.NewPolicyGate(policy), mode)
+}
+
+func TestSafetyCheckTool_Execute(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		params   map[string]any
+		allowed  bool
+		wantRule string
+	}{
+		{"denied in session mode", "readonly", map[string]any{"tool": "write_file", "params": map[string]any{"path": "a.go"}}, false, "tools.readonly.deny[0]"},
+		{"allowed in default mode", "", map[string]any{"tool": "write_file", "params": map[string]any{"path": "a.go"}}, true, safety.RuleDefaultAllow},
+		{"plan too large", "", map[string]any{"tool": safety.PlanToolName, "files_touched": 4}, false, safety.RuleBlastRadiusFiles},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := newSafetyCheckTestTool(t, tt.mode)
+			result, err := tool.Execute(context.Background(), MapParams{Tool: "safety_check", Params: tt.params})
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("Execute failed: %s", result.Error)
+			}
+			d, ok := result.Output.(*safety.Decision)
+			if !ok {
+				t.Fatalf("Output type %T, want *safety.Decision", result.Output)
+			}
+			if d.Allowed != tt.allowed || d.RuleID != tt.wantRule {
+				t.Errorf("decision = %+v, want allowed=%v rule=%s", d, tt.allowed, tt.wantRule)
+			}
+			if !strings.Contains(result.OutputText, tt.wantRule) {
+				t.Errorf("OutputText missing rule ID: %s", result.OutputText)
+			}
+		})
+	}
+}
+
+func TestSafetyCheckTool_MissingTool(t *testing.T) {
+	tool := newSafetyCheckTestTool(t, "")
+	result, err := tool.Execute(context.Background(), MapParams{Tool: "safety_check", Params: map[string]any{}})
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if result.Success {
+		t.Error("expected failure without tool parameter")
+	}
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/rag"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -75,6 +76,11 @@ type SemanticSearchResult struct {
 	// FilePath is the source file path.
 	FilePath string `json:"file_path"`
 
+	// SymbolID and Line locate the symbol in the graph. Set by the
+	// local semantic index only.
+	SymbolID string `json:"symbol_id,omitempty"`
+	Line     int    `json:"line,omitempty"`
+
 	// Score is the similarity score (0.0-1.0, higher is more similar).
 	Score float64 `json:"score"`
 }
@@ -89,6 +95,13 @@ type SemanticSearchOutput struct {
 
 	// Results contains the ranked search results.
 	Results []SemanticSearchResult `json:"results"`
+
+	// Mode is "semantic", or "lexical" when the local semantic index fell
+	// back to keyword matching. Empty for Weaviate searches.
+	Mode string `json:"mode,omitempty"`
+
+	// FallbackReason says why the search ran in lexical mode.
+	FallbackReason string `json:"fallback_reason,omitempty"`
 }
 
 // semanticSearchTool performs vector similarity search over code symbols.
@@ -188,6 +201,10 @@ func (t *semanticSearchTool) Definition() ToolDefinition {
 	}
 }
 
+func (t *semanticSearchTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), SemanticSearchOutput{})
+}
+
 // Execute runs the semantic_search tool.
 //
 // Description:
@@ -246,7 +263,7 @@ func (t *semanticSearchTool) Execute(ctx context.Context, params TypedParams) (*
 	ctx, span := semanticSearchTracer.Start(ctx, "tools.SemanticSearchTool.Execute",
 		trace.WithAttributes(
 			attribute.String("tool", "semantic_search"),
-			attribute.String("query", p.Query),
+			telemetry.SensitiveString("query", p.Query),
 			attribute.Int("limit", p.Limit),
 			attribute.Float64("min_score", p.MinScore),
 		),
@@ -256,7 +273,7 @@ func (t *semanticSearchTool) Execute(ctx context.Context, params TypedParams) (*
 	// Embed query
 	queryVec, err := t.embedClient.EmbedQuery(ctx, p.Query)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		errStep := crs.NewTraceStepBuilder().
 			WithAction("tool_semantic_search").
 			WithTool("semantic_search").
@@ -304,7 +321,7 @@ func (t *semanticSearchTool) Execute(ctx context.Context, params TypedParams) (*
 		WithLimit(p.Limit).
 		Do(ctx)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		errStep := crs.NewTraceStepBuilder().
 			WithAction("tool_semantic_search").
 			WithTool("semantic_search").
@@ -476,7 +493,10 @@ func (t *semanticSearchTool) formatText(output SemanticSearchOutput) string {
 		if r.PackagePath != "" {
 			sb.WriteString(fmt.Sprintf("   Package: %s\n", r.PackagePath))
 		}
-		if r.FilePath != "" {
+		switch {
+		case r.FilePath != "" && r.Line > 0:
+			sb.WriteString(fmt.Sprintf("   File: %s:%d\n", r.FilePath, r.Line))
+		case r.FilePath != "":
 			sb.WriteString(fmt.Sprintf("   File: %s\n", r.FilePath))
 		}
 	}
@@ -0,0 +1,187 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package tools
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/semindex"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// =============================================================================
+// semantic_search Tool - local semantic index
+// =============================================================================
+
+// localSemanticSearchTool is semantic_search over the service's local
+// semantic index instead of Weaviate.
+//
+// Description:
+//
+//	Ranks the graph's functions and methods by cosine similarity of their
+//	locally computed embeddings to the query's. While the graph is not
+//	indexed, or when the embedding model is unavailable, the index falls
+//	back to keyword matching; the output then has mode "lexical" and the
+//	text says so. Parameters, definition and text format are those of
+//	the Weaviate-backed tool.
+//
+// Thread Safety: Safe for concurrent use. All operations are read-only.
+type localSemanticSearchTool struct {
+	semanticSearchTool
+	index   *semindex.Index
+	graphID string
+	graph   *graph.Graph
+}
+
+// NewLocalSemanticSearchTool creates the semantic_search tool over a
+// local semantic index.
+//
+// Inputs:
+//
+//   - ix: The semantic index. May be nil; searches are then lexical.
+//   - graphID: The graph whose vectors to search.
+//   - g: The graph, or a scoped view of it, to return symbols from.
+//
+// Outputs:
+//
+//   - Tool: The semantic_search tool implementation.
+//
+// Thread Safety: Safe for concurrent use after construction.
+func NewLocalSemanticSearchTool(ix *semindex.Index, graphID string, g *graph.Graph) Tool {
+	return &localSemanticSearchTool{index: ix, graphID: graphID, graph: g}
+}
+
+func (t *localSemanticSearchTool) Schema() ToolSchema {
+	return NewToolSchema(t.Definition(), SemanticSearchOutput{})
+}
+
+// Execute runs the semantic_search tool.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and tracing.
+//	params - Must contain "query" (string). Optional: "limit" (int), "min_score" (float64).
+//
+// Outputs:
+//
+//	*Result - Ranked symbols with scores and the search mode.
+//	error - Non-nil only for context cancellation.
+//
+// Thread Safety: Safe for concurrent use.
+func (t *localSemanticSearchTool) Execute(ctx context.Context, params TypedParams) (*Result, error) {
+	start := time.Now()
+
+	p, err := t.parseParams(params.ToMap())
+	if err != nil {
+		errStep := crs.NewTraceStepBuilder().
+			WithAction("tool_semantic_search").
+			WithTool("semantic_search").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return &Result{
+			Success:   false,
+			Error:     err.Error(),
+			TraceStep: &errStep,
+			Duration:  time.Since(start),
+		}, nil
+	}
+
+	ctx, span := semanticSearchTracer.Start(ctx, "tools.LocalSemanticSearchTool.Execute",
+		trace.WithAttributes(
+			attribute.String("tool", "semantic_search"),
+			telemetry.SensitiveString("query", p.Query),
+			attribute.Int("limit", p.Limit),
+			attribute.Float64("min_score", p.MinScore),
+		),
+	)
+	defer span.End()
+
+	res, err := t.index.Search(ctx, t.graphID, t.graph, p.Query, p.Limit, p.MinScore)
+	if err != nil {
+		telemetry.RecordException(span, err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		errStep := crs.NewTraceStepBuilder().
+			WithAction("tool_semantic_search").
+			WithTool("semantic_search").
+			WithDuration(time.Since(start)).
+			WithError(err.Error()).
+			Build()
+		return &Result{
+			Success:   false,
+			Error:     fmt.Sprintf("Semantic search failed: %v", err),
+			TraceStep: &errStep,
+			Duration:  time.Since(start),
+		}, nil
+	}
+
+	output := SemanticSearchOutput{
+		Query:          p.Query,
+		Results:        make([]SemanticSearchResult, 0, len(res.Hits)),
+		Mode:           res.Mode,
+		FallbackReason: res.FallbackReason,
+	}
+	for _, hit := range res.Hits {
+		output.Results = append(output.Results, SemanticSearchResult{
+			Name:        hit.Symbol.Name,
+			Kind:        hit.Symbol.Kind.String(),
+			PackagePath: hit.Symbol.Package,
+			FilePath:    hit.Symbol.FilePath,
+			SymbolID:    hit.Symbol.ID,
+			Line:        hit.Symbol.StartLine,
+			Score:       hit.Score,
+		})
+	}
+	output.ResultCount = len(output.Results)
+
+	span.SetAttributes(
+		attribute.Int("result_count", output.ResultCount),
+		attribute.String("mode", res.Mode),
+	)
+
+	outputText := t.formatText(output)
+	if res.Mode == semindex.ModeLexical {
+		outputText = fmt.Sprintf("Note: semantic index unavailable (%s); these are keyword matches on names and docs, not similarity results.\n\n",
+			res.FallbackReason) + outputText
+	}
+
+	duration := time.Since(start)
+	toolStep := crs.NewTraceStepBuilder().
+		WithAction("tool_semantic_search").
+		WithTarget(p.Query).
+		WithTool("semantic_search").
+		WithDuration(duration).
+		WithMetadata("result_count", fmt.Sprintf("%d", output.ResultCount)).
+		WithMetadata("limit", fmt.Sprintf("%d", p.Limit)).
+		WithMetadata("min_score", fmt.Sprintf("%.2f", p.MinScore)).
+		WithMetadata("mode", res.Mode).
+		WithMetadata("scanned", fmt.Sprintf("%d", res.Scanned)).
+		Build()
+
+	return &Result{
+		Success:     true,
+		Output:      output,
+		OutputText:  outputText,
+		TokensUsed:  estimateTokens(outputText),
+		TraceStep:   &toolStep,
+		Duration:    duration,
+		ResultCount: output.ResultCount,
+	}, nil
+}
@@ -44,6 +44,10 @@ const (
 
 	// CategorySemantic includes tools for vector/semantic search.
 	CategorySemantic ToolCategory = "semantic"
+
+	// CategoryProject includes declarative tools defined by the analyzed
+	// project (.trace/tools.yaml).
+	CategoryProject ToolCategory = "project"
 )
 
 // String returns the string representation of the category.
@@ -74,6 +78,19 @@ const (
 	ParamTypeObject ParamType = "object"
 )
 
+// ParamFormat refines what a string parameter holds, beyond its type.
+// The executor's ArgumentGuard checks and repairs arguments by format.
+type ParamFormat string
+
+const (
+	// ParamFormatSymbolID is the ID of a node in the code graph.
+	ParamFormatSymbolID ParamFormat = "symbol-id"
+
+	// ParamFormatProjectPath is a file or directory path inside the
+	// project, relative to its root or absolute.
+	ParamFormatProjectPath ParamFormat = "project-path"
+)
+
 // ParamDef defines a single parameter for a tool.
 //
 // NOTE: This struct uses `any` types for Default and Enum to support JSON schema
@@ -100,6 +117,11 @@ type ParamDef struct {
 	// Uses `[]any` for JSON schema flexibility - enum values match the param type.
 	Enum []any `json:"enum,omitempty"`
 
+	// Format refines what a string holds (for string type). It is checked
+	// by the executor and not sent to LLM providers, which reject unknown
+	// JSON Schema formats.
+	Format ParamFormat `json:"format,omitempty"`
+
 	// MinLength is the minimum string length (for string type).
 	MinLength int `json:"minLength,omitempty"`
 
@@ -119,6 +141,11 @@ type ParamDef struct {
 	Properties map[string]ParamDef `json:"properties,omitempty"`
 }
 
+// Bound returns a pointer to v, for ParamDef.Minimum and Maximum.
+func Bound(v float64) *float64 {
+	return &v
+}
+
 // ToolDefinition describes a tool's interface for the LLM.
 //
 // This structure is designed to be serializable to JSON Schema format
@@ -145,6 +172,10 @@ type ToolDefinition struct {
 	// SideEffects indicates if the tool modifies state.
 	SideEffects bool `json:"side_effects"`
 
+	// Cacheable marks the tool's result as a pure function of the graph and
+	// its arguments, so it may be served from the shared ResultCache.
+	Cacheable bool `json:"cacheable,omitempty"`
+
 	// Timeout is the default execution timeout.
 	Timeout time.Duration `json:"timeout,omitempty"`
 
@@ -387,6 +418,11 @@ type Tool interface {
 	// Definition returns the tool's parameter schema.
 	Definition() ToolDefinition
 
+	// Schema returns the JSON Schemas of the tool's arguments and of
+	// Result.Output, usually NewToolSchema(Definition(), OutputType{}).
+	// Executors validate arguments against Schema().Input before Execute.
+	Schema() ToolSchema
+
 	// Execute runs the tool with the given parameters.
 	//
 	// Inputs:
@@ -425,6 +461,10 @@ type Result struct {
 	// Truncated indicates if output was truncated.
 	Truncated bool `json:"truncated"`
 
+	// NextCursor is set by paged tools when more results exist. Passing it
+	// as the cursor argument of the same call fetches the next page.
+	NextCursor string `json:"next_cursor,omitempty"`
+
 	// ModifiedFiles lists files written or modified by this tool.
 	// Used by DirtyTracker to trigger incremental graph refresh.
 	// Tools that write to the file system should populate this.
@@ -510,6 +550,51 @@ func DefaultExecutorOptions() ExecutorOptions {
 	}
 }
 
+// LimitError reports that a tool execution hit a sandbox limit.
+//
+// Description:
+//
+//	Returned by Executor.Execute instead of a raw context error so callers
+//	can turn the failure into a tool result the agent can reason about
+//	("the tool timed out, try a narrower scope") rather than failing the run.
+//	Unwraps to ErrTimeout for timeouts.
+type LimitError struct {
+	// Tool is the name of the tool that hit the limit.
+	Tool string `json:"tool"`
+
+	// Limit is the kind of limit hit (LimitTimeout, ...).
+	Limit string `json:"limit"`
+
+	// Timeout is the timeout that was exceeded (for LimitTimeout).
+	Timeout time.Duration `json:"timeout,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *LimitError) Error() string {
+	if e.Limit == LimitTimeout {
+		return ErrTimeout.Error() + ": " + e.Tool + " after " + e.Timeout.String()
+	}
+	return "tool " + e.Tool + " hit " + e.Limit + " limit"
+}
+
+// Unwrap returns the sentinel error for the limit kind.
+func (e *LimitError) Unwrap() error {
+	if e.Limit == LimitTimeout {
+		return ErrTimeout
+	}
+	return nil
+}
+
+// Hint returns guidance for the agent on how to recover from the limit.
+func (e *LimitError) Hint() string {
+	switch e.Limit {
+	case LimitTimeout:
+		return "The tool timed out. Try a narrower scope: target a specific package, file, or symbol, or reduce depth/limit parameters."
+	default:
+		return "The tool hit a resource limit. Try a narrower query."
+	}
+}
+
 // ValidationError represents a parameter validation error.
 type ValidationError struct {
 	// Parameter is the parameter name that failed validation.
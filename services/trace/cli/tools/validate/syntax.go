@@ -146,6 +146,11 @@ For Go files, also validates with 'go build -n' for type checking.`,
 	}
 }
 
+// Schema returns the JSON Schemas of the tool's arguments and output.
+func (t *SyntaxTool) Schema() tools.ToolSchema {
+	return tools.NewToolSchema(t.Definition(), SyntaxOutput{})
+}
+
 // Execute validates syntax for the given file or content.
 //
 // Description:
@@ -17,6 +17,8 @@ import (
 	"sync"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -99,7 +101,7 @@ func MustLoadConceptSynonyms() ConceptSynonyms {
 	synonyms, err := LoadConceptSynonyms()
 	if err != nil {
 		slog.Warn("IT-12: concept synonyms loading failed, continuing without expansion",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return make(ConceptSynonyms)
 	}
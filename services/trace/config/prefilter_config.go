@@ -13,12 +13,19 @@ package config
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"sync"
 
 	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/yaml.v3"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -67,6 +74,18 @@ type PreFilterConfig struct {
 	// ScoreFloor is the minimum absolute score for inclusion. Default: 0.30.
 	ScoreFloor float64 `yaml:"score_floor"`
 
+	// FeedbackWeight is the weight of the routing-feedback k-NN vote added to
+	// Phase 3 scores. Default: 0.2. Negative disables feedback re-ranking.
+	FeedbackWeight float64 `yaml:"feedback_weight"`
+
+	// FeedbackK is the number of nearest past queries that vote. Default: 10.
+	FeedbackK int `yaml:"feedback_k"`
+
+	// HashEmbeddingFallback scores with locally computed hash embeddings when
+	// the Ollama embedding endpoint is unreachable at warm-up, instead of
+	// passing all tools through. Default: false.
+	HashEmbeddingFallback bool `yaml:"hash_embedding_fallback"`
+
 	// AlwaysInclude lists tool names that must always be in the narrowed set.
 	AlwaysInclude []string `yaml:"always_include"`
 
@@ -232,6 +251,12 @@ const (
 	// DefaultScoreFloor is the minimum absolute score for inclusion in the
 	// candidate set. Tools scoring below this are excluded.
 	DefaultScoreFloor = 0.30
+
+	// DefaultFeedbackWeight is the weight of the routing-feedback vote.
+	DefaultFeedbackWeight = 0.2
+
+	// DefaultFeedbackK is the number of neighbors in the routing-feedback vote.
+	DefaultFeedbackK = 10
 )
 
 // =============================================================================
@@ -250,7 +275,9 @@ var (
 // Description:
 //
 //	Loads the pre-filter rules on first call and caches for subsequent calls.
-//	Uses sync.Once for thread-safe initialization.
+//	Uses sync.Once for thread-safe initialization. Rules are read from the
+//	external file (see getExternalPreFilterPath) when one is configured and
+//	readable, otherwise from the embedded default.
 //
 // Inputs:
 //
@@ -283,12 +310,72 @@ func GetPreFilterConfig(ctx context.Context) (*PreFilterConfig, error) {
 	}
 
 	prefilterConfigOnce.Do(func() {
-		cachedPreFilterConfig, prefilterConfigLoadErr = LoadPreFilterConfig(ctx, defaultPreFilterRulesYAML)
+		cachedPreFilterConfig, prefilterConfigLoadErr = loadPreFilterRules(ctx)
 	})
 
 	return cachedPreFilterConfig, prefilterConfigLoadErr
 }
 
+// loadPreFilterRules loads the pre-filter rules from the external file if
+// present, falling back to the embedded default when it cannot be read.
+// An external file that reads but fails validation is an error, as for the
+// tool registry.
+func loadPreFilterRules(ctx context.Context) (*PreFilterConfig, error) {
+	data, source, err := readPreFilterRules(ctx)
+	if err != nil {
+		slog.Warn("External pre-filter rules not available, using embedded default",
+			slog.String("path", source),
+			telemetry.SensitiveLogError("error", err))
+		data, source = defaultPreFilterRulesYAML, SourceEmbedded
+	}
+	cfg, err := LoadPreFilterConfig(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return cfg, nil
+}
+
+// readPreFilterRules reads the external pre-filter rules.
+//
+// Outputs:
+//
+//	[]byte - The YAML. The embedded default if no external file is configured.
+//	string - The file path, or SourceEmbedded.
+//	error - Non-nil if an external file is configured but cannot be read.
+func readPreFilterRules(ctx context.Context) ([]byte, string, error) {
+	path := getExternalPreFilterPath()
+	if path == "" {
+		return defaultPreFilterRulesYAML, SourceEmbedded, nil
+	}
+	data, err := loadExternalYAML(ctx, path)
+	if err != nil {
+		return nil, path, err
+	}
+	return data, path, nil
+}
+
+// getExternalPreFilterPath returns the path to the external pre-filter
+// rules file, or "" if none is configured. PREFILTER_RULES_PATH takes
+// precedence over the default locations.
+func getExternalPreFilterPath() string {
+	if path := os.Getenv("PREFILTER_RULES_PATH"); path != "" {
+		return path
+	}
+
+	locations := []string{
+		"./config/prefilter_rules.yaml",
+		"./prefilter_rules.yaml",
+	}
+	for _, loc := range locations {
+		if _, err := os.Stat(loc); err == nil {
+			absPath, _ := filepath.Abs(loc)
+			return absPath
+		}
+	}
+
+	return ""
+}
+
 // ResetPreFilterConfig resets the cached config for testing.
 //
 // Description:
@@ -357,6 +444,12 @@ func LoadPreFilterConfig(ctx context.Context, data []byte) (*PreFilterConfig, er
 	if cfg.ScoreFloor == 0 {
 		cfg.ScoreFloor = DefaultScoreFloor
 	}
+	if cfg.FeedbackWeight == 0 {
+		cfg.FeedbackWeight = DefaultFeedbackWeight
+	}
+	if cfg.FeedbackK <= 0 {
+		cfg.FeedbackK = DefaultFeedbackK
+	}
 
 	// Ensure min <= max
 	if cfg.MinCandidates > cfg.MaxCandidates {
@@ -386,6 +479,8 @@ func LoadPreFilterConfig(ctx context.Context, data []byte) (*PreFilterConfig, er
 		attribute.String("scoring_mode", cfg.ScoringMode),
 		attribute.Float64("score_gap_threshold", cfg.ScoreGapThreshold),
 		attribute.Float64("score_floor", cfg.ScoreFloor),
+		attribute.Float64("feedback_weight", cfg.FeedbackWeight),
+		attribute.Bool("hash_embedding_fallback", cfg.HashEmbeddingFallback),
 	)
 
 	slog.Info("pre-filter config loaded",
@@ -401,80 +496,119 @@ func LoadPreFilterConfig(ctx context.Context, data []byte) (*PreFilterConfig, er
 }
 
 // validatePreFilterConfig checks all rules for consistency.
+//
+// Every problem is reported, not just the first, so a rejected reload
+// lists everything that needs fixing. Patterns containing ".*" must
+// compile as regular expressions.
 func validatePreFilterConfig(cfg *PreFilterConfig) error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
 	// Validate scoring mode
 	switch cfg.ScoringMode {
 	case "hybrid", "embedding_primary":
 		// valid
 	default:
-		return fmt.Errorf("scoring_mode must be 'hybrid' or 'embedding_primary', got %q", cfg.ScoringMode)
+		fail("scoring_mode must be 'hybrid' or 'embedding_primary', got %q", cfg.ScoringMode)
 	}
 
 	// Validate forced mappings
 	for i, fm := range cfg.ForcedMappings {
 		if fm.Tool == "" {
-			return fmt.Errorf("forced_mapping[%d]: tool must not be empty", i)
+			fail("forced_mapping[%d]: tool must not be empty", i)
 		}
 		if len(fm.Patterns) == 0 {
-			return fmt.Errorf("forced_mapping[%d] (%s): patterns must not be empty", i, fm.Tool)
+			fail("forced_mapping[%d] (%s): patterns must not be empty", i, fm.Tool)
+		}
+		for j, p := range fm.Patterns {
+			if err := validatePattern(p); err != nil {
+				fail("forced_mapping[%d] (%s): patterns[%d]: %w", i, fm.Tool, j, err)
+			}
 		}
 	}
 
 	// Validate negation rules
 	for i, nr := range cfg.NegationRules {
 		if nr.CorrectTool == "" {
-			return fmt.Errorf("negation_rule[%d]: correct_tool must not be empty", i)
+			fail("negation_rule[%d]: correct_tool must not be empty", i)
 		}
 		if nr.WrongTool == "" {
-			return fmt.Errorf("negation_rule[%d]: wrong_tool must not be empty", i)
+			fail("negation_rule[%d]: wrong_tool must not be empty", i)
 		}
 		if len(nr.NegationWords) == 0 {
-			return fmt.Errorf("negation_rule[%d] (%s): negation_words must not be empty", i, nr.CorrectTool)
+			fail("negation_rule[%d] (%s): negation_words must not be empty", i, nr.CorrectTool)
 		}
 		if len(nr.TriggerKeywords) == 0 {
-			return fmt.Errorf("negation_rule[%d] (%s): trigger_keywords must not be empty", i, nr.CorrectTool)
+			fail("negation_rule[%d] (%s): trigger_keywords must not be empty", i, nr.CorrectTool)
 		}
 		if nr.Action != "force" {
-			return fmt.Errorf("negation_rule[%d] (%s): action must be 'force', got %q", i, nr.CorrectTool, nr.Action)
+			fail("negation_rule[%d] (%s): action must be 'force', got %q", i, nr.CorrectTool, nr.Action)
 		}
 	}
 
 	// Validate confusion pairs
 	for i, cp := range cfg.ConfusionPairs {
 		if cp.ToolA == "" {
-			return fmt.Errorf("confusion_pair[%d]: tool_a must not be empty", i)
+			fail("confusion_pair[%d]: tool_a must not be empty", i)
 		}
 		if cp.ToolB == "" {
-			return fmt.Errorf("confusion_pair[%d]: tool_b must not be empty", i)
+			fail("confusion_pair[%d]: tool_b must not be empty", i)
+		}
+		if cp.ToolA != "" && cp.ToolA == cp.ToolB {
+			fail("confusion_pair[%d]: tool_a and tool_b must be different (%s)", i, cp.ToolA)
+		}
+		for j, p := range cp.ToolAPatterns {
+			if err := validatePattern(p); err != nil {
+				fail("confusion_pair[%d]: tool_a_patterns[%d]: %w", i, j, err)
+			}
 		}
-		if cp.ToolA == cp.ToolB {
-			return fmt.Errorf("confusion_pair[%d]: tool_a and tool_b must be different (%s)", i, cp.ToolA)
+		for j, p := range cp.ToolBPatterns {
+			if err := validatePattern(p); err != nil {
+				fail("confusion_pair[%d]: tool_b_patterns[%d]: %w", i, j, err)
+			}
 		}
 	}
 
 	// Validate routing encyclopedia entries (CB-62 Rev 2)
 	for i, entry := range cfg.RoutingEncyclopedia {
 		if entry.Tool == "" {
-			return fmt.Errorf("routing_encyclopedia[%d]: tool must not be empty", i)
+			fail("routing_encyclopedia[%d]: tool must not be empty", i)
 		}
 		switch entry.Tier {
 		case "force", "boost", "hint":
 			// valid
 		default:
-			return fmt.Errorf("routing_encyclopedia[%d] (%s): tier must be 'force', 'boost', or 'hint', got %q", i, entry.Tool, entry.Tier)
+			fail("routing_encyclopedia[%d] (%s): tier must be 'force', 'boost', or 'hint', got %q", i, entry.Tool, entry.Tier)
 		}
 		if entry.Tier != "boost" && entry.BoostAmount > 0 {
-			return fmt.Errorf("routing_encyclopedia[%d] (%s): boost_amount is only valid for tier=boost, got tier=%q with boost_amount=%f", i, entry.Tool, entry.Tier, entry.BoostAmount)
+			fail("routing_encyclopedia[%d] (%s): boost_amount is only valid for tier=boost, got tier=%q with boost_amount=%f", i, entry.Tool, entry.Tier, entry.BoostAmount)
 		}
 		if len(entry.Intents) == 0 {
-			return fmt.Errorf("routing_encyclopedia[%d] (%s): intents must not be empty", i, entry.Tool)
+			fail("routing_encyclopedia[%d] (%s): intents must not be empty", i, entry.Tool)
 		}
 		for j, intent := range entry.Intents {
 			if intent.Pattern == "" {
-				return fmt.Errorf("routing_encyclopedia[%d] (%s): intents[%d].pattern must not be empty", i, entry.Tool, j)
+				fail("routing_encyclopedia[%d] (%s): intents[%d].pattern must not be empty", i, entry.Tool, j)
+			} else if err := validatePattern(intent.Pattern); err != nil {
+				fail("routing_encyclopedia[%d] (%s): intents[%d]: %w", i, entry.Tool, j, err)
 			}
 		}
 	}
 
+	return errors.Join(errs...)
+}
+
+// validatePattern checks that a pattern the pre-filter treats as a regex
+// (one containing ".*") compiles the way the pre-filter compiles it.
+func validatePattern(pattern string) error {
+	lower := strings.ToLower(pattern)
+	if !strings.Contains(lower, ".*") {
+		return nil
+	}
+	if _, err := regexp.Compile("(?i)" + lower); err != nil {
+		return fmt.Errorf("invalid regex %q: %w", pattern, err)
+	}
 	return nil
 }
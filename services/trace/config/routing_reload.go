@@ -0,0 +1,406 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SourceEmbedded is the source of configuration read from the binary.
+const SourceEmbedded = "embedded"
+
+var (
+	routingConfigGeneration atomic.Uint64
+
+	routingConfigGenerationGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "trace_routing_config_generation",
+		Help: "Generation of the pre-filter rules and tool routing registry; incremented on each reload",
+	})
+
+	routingConfigReloads = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "trace_routing_config_reloads_total",
+		Help: "Routing config reload attempts by result (applied, rejected)",
+	}, []string{"result"})
+
+	// routingConfigSwapMu makes SetRoutingConfig atomic with respect to
+	// CurrentRoutingConfig. The per-singleton locks are still taken so
+	// the existing getters stay consistent.
+	routingConfigSwapMu sync.RWMutex
+)
+
+// RoutingConfig is a pre-filter config and tool routing registry loaded
+// together, so a reload swaps both or neither.
+//
+// Thread Safety: Immutable after loading; safe for concurrent use.
+type RoutingConfig struct {
+	// PreFilter is the validated pre-filter configuration.
+	PreFilter *PreFilterConfig
+
+	// Registry is the parsed tool routing registry.
+	Registry *ToolRoutingRegistry
+
+	// PreFilterSource is the rules file path, or SourceEmbedded.
+	PreFilterSource string
+
+	// RegistrySource is the registry file path, or SourceEmbedded.
+	RegistrySource string
+}
+
+// LoadRoutingConfig reads and validates the pre-filter rules and the tool
+// routing registry for a reload.
+//
+// Description:
+//
+//	Reads the same files as GetPreFilterConfig and GetToolRoutingRegistry.
+//	Unlike the startup path, a configured file that cannot be read is an
+//	error rather than a silent fall back to the embedded default, so a
+//	broken file never replaces a working config with the defaults. Both
+//	files are checked and every problem found is returned.
+//
+// Inputs:
+//
+//	ctx - Context for tracing. Must not be nil.
+//
+// Outputs:
+//
+//	*RoutingConfig - The new config. Nil on error.
+//	error - Joined errors of both files, each prefixed with its source.
+//
+// Thread Safety: Safe for concurrent use.
+func LoadRoutingConfig(ctx context.Context) (*RoutingConfig, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("LoadRoutingConfig: ctx must not be nil")
+	}
+
+	rc := &RoutingConfig{}
+	var errs []error
+
+	pfData, pfSource, err := readPreFilterRules(ctx)
+	rc.PreFilterSource = pfSource
+	if err == nil {
+		rc.PreFilter, err = LoadPreFilterConfig(ctx, pfData)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("pre-filter rules (%s): %w", pfSource, err))
+	}
+
+	regData, regSource, err := readToolRegistry(ctx)
+	rc.RegistrySource = regSource
+	if err == nil {
+		rc.Registry, err = parseToolRegistryYAML(ctx, regData)
+	}
+	if err != nil {
+		errs = append(errs, fmt.Errorf("tool registry (%s): %w", regSource, err))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		routingConfigReloads.WithLabelValues("rejected").Inc()
+		return nil, err
+	}
+	return rc, nil
+}
+
+// readToolRegistry reads the external tool registry, or returns the
+// embedded default if no external file is configured.
+func readToolRegistry(ctx context.Context) ([]byte, string, error) {
+	path := getExternalRegistryPath()
+	if path == "" {
+		return defaultToolRegistryYAML, SourceEmbedded, nil
+	}
+	data, err := loadExternalYAML(ctx, path)
+	if err != nil {
+		return nil, path, err
+	}
+	return data, path, nil
+}
+
+// RoutingConfigFiles returns the external files the routing config is
+// read from: the pre-filter rules and the tool registry, each only if
+// configured. Used to watch them for changes.
+func RoutingConfigFiles() []string {
+	var files []string
+	for _, p := range []string{getExternalPreFilterPath(), getExternalRegistryPath()} {
+		if p != "" {
+			files = append(files, p)
+		}
+	}
+	return files
+}
+
+// CurrentRoutingConfig returns the config served by GetPreFilterConfig and
+// GetToolRoutingRegistry, loading it if needed.
+//
+// Outputs:
+//
+//	*RoutingConfig - The current config. Either part is nil if it failed
+//	  to load at startup. Sources are not recorded for the startup config.
+//
+// Thread Safety: Safe for concurrent use.
+func CurrentRoutingConfig(ctx context.Context) *RoutingConfig {
+	routingConfigSwapMu.RLock()
+	defer routingConfigSwapMu.RUnlock()
+
+	pf, _ := GetPreFilterConfig(ctx)
+	reg, _ := GetToolRoutingRegistry(ctx)
+	return &RoutingConfig{PreFilter: pf, Registry: reg}
+}
+
+// SetRoutingConfig makes rc the config served by GetPreFilterConfig and
+// GetToolRoutingRegistry and increments the config generation.
+//
+// Description:
+//
+//	Both singletons are replaced together. Callers that already hold the
+//	previous config keep using it; nothing is mutated in place. Components
+//	that copied the config at construction, such as the routing PreFilter,
+//	must be updated separately.
+//
+// Inputs:
+//
+//	rc - The config from LoadRoutingConfig. Must not be nil and must have
+//	  both parts.
+//
+// Outputs:
+//
+//	uint64 - The new generation.
+//	error - Non-nil if rc is incomplete; nothing is changed.
+//
+// Thread Safety: Safe for concurrent use.
+func SetRoutingConfig(rc *RoutingConfig) (uint64, error) {
+	if rc == nil || rc.PreFilter == nil || rc.Registry == nil {
+		return 0, fmt.Errorf("SetRoutingConfig: pre-filter config and registry are required")
+	}
+
+	routingConfigSwapMu.Lock()
+	defer routingConfigSwapMu.Unlock()
+
+	prefilterConfigMu.Lock()
+	cachedPreFilterConfig = rc.PreFilter
+	prefilterConfigLoadErr = nil
+	prefilterConfigOnce.Do(func() {})
+	prefilterConfigMu.Unlock()
+
+	registryMu.Lock()
+	cachedRegistry = rc.Registry
+	registryLoadErr = nil
+	registryOnce.Do(func() {})
+	registryMu.Unlock()
+
+	gen := routingConfigGeneration.Add(1)
+	routingConfigGenerationGauge.Set(float64(gen))
+	routingConfigReloads.WithLabelValues("applied").Inc()
+	return gen, nil
+}
+
+// RoutingConfigGeneration returns the routing config generation: 0 for the
+// config loaded at startup, incremented by each SetRoutingConfig.
+//
+// Thread Safety: Safe for concurrent use.
+func RoutingConfigGeneration() uint64 {
+	return routingConfigGeneration.Load()
+}
+
+// =============================================================================
+// Diff
+// =============================================================================
+
+// RoutingConfigDiff summarizes what a reload changes.
+//
+// Rules are compared by content, so an edited rule counts as one removed
+// and one added.
+type RoutingConfigDiff struct {
+	ForcedMappingsAdded   int `json:"forced_mappings_added"`
+	ForcedMappingsRemoved int `json:"forced_mappings_removed"`
+	NegationRulesAdded    int `json:"negation_rules_added"`
+	NegationRulesRemoved  int `json:"negation_rules_removed"`
+	ConfusionPairsAdded   int `json:"confusion_pairs_added"`
+	ConfusionPairsRemoved int `json:"confusion_pairs_removed"`
+	EncyclopediaAdded     int `json:"encyclopedia_added"`
+	EncyclopediaRemoved   int `json:"encyclopedia_removed"`
+
+	// ToolsAdded, ToolsRemoved and ToolsChanged name registry entries,
+	// sorted.
+	ToolsAdded   []string `json:"tools_added,omitempty"`
+	ToolsRemoved []string `json:"tools_removed,omitempty"`
+	ToolsChanged []string `json:"tools_changed,omitempty"`
+
+	// SettingsChanged names the changed top-level pre-filter settings by
+	// their YAML key, e.g. "max_candidates".
+	SettingsChanged []string `json:"settings_changed,omitempty"`
+}
+
+// restartOnlySettings are read once when the PreFilter is constructed, so
+// changing them only takes effect after a restart.
+var restartOnlySettings = map[string]bool{
+	"feedback_weight":         true,
+	"feedback_k":              true,
+	"hash_embedding_fallback": true,
+}
+
+// RestartRequired returns the changed settings that only take effect
+// after a restart.
+func (d RoutingConfigDiff) RestartRequired() []string {
+	var out []string
+	for _, s := range d.SettingsChanged {
+		if restartOnlySettings[s] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Empty reports whether the reload changes nothing.
+func (d RoutingConfigDiff) Empty() bool {
+	return d.ForcedMappingsAdded+d.ForcedMappingsRemoved+
+		d.NegationRulesAdded+d.NegationRulesRemoved+
+		d.ConfusionPairsAdded+d.ConfusionPairsRemoved+
+		d.EncyclopediaAdded+d.EncyclopediaRemoved == 0 &&
+		len(d.ToolsAdded)+len(d.ToolsRemoved)+len(d.ToolsChanged)+len(d.SettingsChanged) == 0
+}
+
+// Summary returns a one-line description for logs, e.g.
+// "forced mappings +2/-1, tools +1/-0/~3, settings: max_candidates".
+// Unchanged categories are omitted.
+func (d RoutingConfigDiff) Summary() string {
+	if d.Empty() {
+		return "no changes"
+	}
+	var parts []string
+	pair := func(name string, added, removed int) {
+		if added+removed > 0 {
+			parts = append(parts, fmt.Sprintf("%s +%d/-%d", name, added, removed))
+		}
+	}
+	pair("forced mappings", d.ForcedMappingsAdded, d.ForcedMappingsRemoved)
+	pair("negation rules", d.NegationRulesAdded, d.NegationRulesRemoved)
+	pair("confusion pairs", d.ConfusionPairsAdded, d.ConfusionPairsRemoved)
+	pair("encyclopedia entries", d.EncyclopediaAdded, d.EncyclopediaRemoved)
+	if n := len(d.ToolsAdded) + len(d.ToolsRemoved) + len(d.ToolsChanged); n > 0 {
+		parts = append(parts, fmt.Sprintf("tools +%d/-%d/~%d", len(d.ToolsAdded), len(d.ToolsRemoved), len(d.ToolsChanged)))
+	}
+	if len(d.SettingsChanged) > 0 {
+		parts = append(parts, "settings: "+strings.Join(d.SettingsChanged, ","))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// DiffRoutingConfig compares two routing configs.
+//
+// Inputs:
+//
+//	old - The current config. Nil, or nil parts, count as empty.
+//	updated - The new config. Nil, or nil parts, count as empty.
+//
+// Outputs:
+//
+//	RoutingConfigDiff - What changes from old to updated.
+func DiffRoutingConfig(old, updated *RoutingConfig) RoutingConfigDiff {
+	var oldPF, newPF *PreFilterConfig
+	var oldReg, newReg *ToolRoutingRegistry
+	if old != nil {
+		oldPF, oldReg = old.PreFilter, old.Registry
+	}
+	if updated != nil {
+		newPF, newReg = updated.PreFilter, updated.Registry
+	}
+	if oldPF == nil {
+		oldPF = &PreFilterConfig{}
+	}
+	if newPF == nil {
+		newPF = &PreFilterConfig{}
+	}
+
+	var d RoutingConfigDiff
+	d.ForcedMappingsAdded, d.ForcedMappingsRemoved = diffRules(oldPF.ForcedMappings, newPF.ForcedMappings)
+	d.NegationRulesAdded, d.NegationRulesRemoved = diffRules(oldPF.NegationRules, newPF.NegationRules)
+	d.ConfusionPairsAdded, d.ConfusionPairsRemoved = diffRules(oldPF.ConfusionPairs, newPF.ConfusionPairs)
+	d.EncyclopediaAdded, d.EncyclopediaRemoved = diffRules(oldPF.RoutingEncyclopedia, newPF.RoutingEncyclopedia)
+	d.SettingsChanged = diffSettings(oldPF, newPF)
+
+	oldEntries, newEntries := registryEntries(oldReg), registryEntries(newReg)
+	for name, entry := range newEntries {
+		prev, ok := oldEntries[name]
+		switch {
+		case !ok:
+			d.ToolsAdded = append(d.ToolsAdded, name)
+		case !reflect.DeepEqual(prev, entry):
+			d.ToolsChanged = append(d.ToolsChanged, name)
+		}
+	}
+	for name := range oldEntries {
+		if _, ok := newEntries[name]; !ok {
+			d.ToolsRemoved = append(d.ToolsRemoved, name)
+		}
+	}
+	sort.Strings(d.ToolsAdded)
+	sort.Strings(d.ToolsRemoved)
+	sort.Strings(d.ToolsChanged)
+	return d
+}
+
+// diffRules counts the rules added and removed, comparing them as
+// multisets by content.
+func diffRules[T any](old, updated []T) (added, removed int) {
+	counts := make(map[string]int, len(old))
+	for _, r := range old {
+		counts[fmt.Sprintf("%#v", r)]++
+	}
+	for _, r := range updated {
+		k := fmt.Sprintf("%#v", r)
+		if counts[k] > 0 {
+			counts[k]--
+			continue
+		}
+		added++
+	}
+	for _, n := range counts {
+		removed += n
+	}
+	return added, removed
+}
+
+// diffSettings returns the YAML keys of the changed top-level scalar and
+// list settings, in declaration order. Rule lists are diffed separately.
+func diffSettings(old, updated *PreFilterConfig) []string {
+	var changed []string
+	ov, nv := reflect.ValueOf(*old), reflect.ValueOf(*updated)
+	t := ov.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		switch name {
+		case "", "forced_mappings", "negation_rules", "confusion_pairs", "routing_encyclopedia":
+			continue
+		}
+		if !reflect.DeepEqual(ov.Field(i).Interface(), nv.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+	return changed
+}
+
+// registryEntries returns the entries of a registry, or nil.
+func registryEntries(r *ToolRoutingRegistry) map[string]*ToolRoutingEntry {
+	if r == nil {
+		return nil
+	}
+	return r.entries
+}
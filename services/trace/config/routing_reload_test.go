@@ -0,0 +1,235 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testRulesYAML = `
+enabled: true
+max_candidates: 10
+forced_mappings:
+  - patterns: ["call chain"]
+    tool: get_call_chain
+    reason: test
+`
+
+const testRegistryYAML = `
+tools:
+  - name: find_callers
+    keywords: [callers]
+    use_when: Finding callers
+  - name: get_call_chain
+    keywords: [chain]
+    use_when: Tracing call chains
+`
+
+// writeRoutingFiles writes the rules and registry to temp files and points
+// the loaders at them.
+func writeRoutingFiles(t *testing.T, rules, registry string) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	rulesPath := filepath.Join(dir, "prefilter_rules.yaml")
+	regPath := filepath.Join(dir, "tool_registry.yaml")
+	if err := os.WriteFile(rulesPath, []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(regPath, []byte(registry), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PREFILTER_RULES_PATH", rulesPath)
+	t.Setenv("TOOL_REGISTRY_PATH", regPath)
+	t.Cleanup(func() {
+		ResetPreFilterConfig()
+		ResetToolRoutingRegistry()
+	})
+	return rulesPath, regPath
+}
+
+func TestLoadRoutingConfig_External(t *testing.T) {
+	rulesPath, regPath := writeRoutingFiles(t, testRulesYAML, testRegistryYAML)
+
+	rc, err := LoadRoutingConfig(context.Background())
+	if err != nil {
+		t.Fatalf("LoadRoutingConfig: %v", err)
+	}
+	if rc.PreFilterSource != rulesPath || rc.RegistrySource != regPath {
+		t.Errorf("sources = %q, %q", rc.PreFilterSource, rc.RegistrySource)
+	}
+	if rc.PreFilter.MaxCandidates != 10 || len(rc.PreFilter.ForcedMappings) != 1 {
+		t.Errorf("unexpected pre-filter config: %+v", rc.PreFilter)
+	}
+	if rc.Registry.ToolCount() != 2 {
+		t.Errorf("ToolCount = %d, want 2", rc.Registry.ToolCount())
+	}
+}
+
+func TestLoadRoutingConfig_ReportsAllErrors(t *testing.T) {
+	badRules := `
+enabled: true
+scoring_mode: fastest
+forced_mappings:
+  - patterns: ["find (.*"]
+    tool: find_callers
+  - patterns: []
+    tool: ""
+`
+	writeRoutingFiles(t, badRules, "tools:\n  - keywords: [x]\n")
+
+	_, err := LoadRoutingConfig(context.Background())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	msg := err.Error()
+	for _, want := range []string{
+		"scoring_mode",
+		"invalid regex",
+		"forced_mapping[1]: tool must not be empty",
+		"tool registry",
+		"empty name",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("error %q does not mention %q", msg, want)
+		}
+	}
+}
+
+func TestLoadRoutingConfig_MissingFileIsError(t *testing.T) {
+	writeRoutingFiles(t, testRulesYAML, testRegistryYAML)
+	t.Setenv("PREFILTER_RULES_PATH", filepath.Join(t.TempDir(), "missing.yaml"))
+
+	if _, err := LoadRoutingConfig(context.Background()); err == nil {
+		t.Fatal("expected an error for a missing rules file, not the embedded default")
+	}
+}
+
+func TestGetPreFilterConfig_ExternalFile(t *testing.T) {
+	writeRoutingFiles(t, testRulesYAML, testRegistryYAML)
+	ResetPreFilterConfig()
+
+	cfg, err := GetPreFilterConfig(context.Background())
+	if err != nil {
+		t.Fatalf("GetPreFilterConfig: %v", err)
+	}
+	if cfg.MaxCandidates != 10 {
+		t.Errorf("MaxCandidates = %d, want 10 from the external file", cfg.MaxCandidates)
+	}
+}
+
+func TestSetRoutingConfig_SwapsAndBumpsGeneration(t *testing.T) {
+	writeRoutingFiles(t, testRulesYAML, testRegistryYAML)
+	ctx := context.Background()
+
+	rc, err := LoadRoutingConfig(ctx)
+	if err != nil {
+		t.Fatalf("LoadRoutingConfig: %v", err)
+	}
+	before := RoutingConfigGeneration()
+	gen, err := SetRoutingConfig(rc)
+	if err != nil {
+		t.Fatalf("SetRoutingConfig: %v", err)
+	}
+	if gen != before+1 || RoutingConfigGeneration() != gen {
+		t.Errorf("generation = %d, want %d", gen, before+1)
+	}
+
+	cfg, _ := GetPreFilterConfig(ctx)
+	reg, _ := GetToolRoutingRegistry(ctx)
+	if cfg != rc.PreFilter || reg != rc.Registry {
+		t.Error("getters do not return the swapped config")
+	}
+
+	if _, err := SetRoutingConfig(&RoutingConfig{PreFilter: rc.PreFilter}); err == nil {
+		t.Error("expected an error for a config without a registry")
+	}
+	if RoutingConfigGeneration() != gen {
+		t.Error("a rejected swap must not change the generation")
+	}
+}
+
+func TestDiffRoutingConfig(t *testing.T) {
+	ctx := context.Background()
+	oldPF, err := LoadPreFilterConfig(ctx, []byte(testRulesYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newPF, err := LoadPreFilterConfig(ctx, []byte(`
+enabled: true
+max_candidates: 12
+feedback_k: 5
+forced_mappings:
+  - patterns: ["call chain"]
+    tool: get_call_chain
+    reason: test
+  - patterns: ["who calls"]
+    tool: find_callers
+negation_rules:
+  - negation_words: ["no"]
+    trigger_keywords: ["callers"]
+    wrong_tool: find_callers
+    correct_tool: find_dead_code
+    action: force
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldReg, err := parseToolRegistryYAML(ctx, []byte(testRegistryYAML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	newReg, err := parseToolRegistryYAML(ctx, []byte(`
+tools:
+  - name: find_callers
+    keywords: [callers, "who calls"]
+    use_when: Finding callers
+  - name: find_dead_code
+    keywords: [unused]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := DiffRoutingConfig(
+		&RoutingConfig{PreFilter: oldPF, Registry: oldReg},
+		&RoutingConfig{PreFilter: newPF, Registry: newReg},
+	)
+	if d.ForcedMappingsAdded != 1 || d.ForcedMappingsRemoved != 0 {
+		t.Errorf("forced mappings +%d/-%d, want +1/-0", d.ForcedMappingsAdded, d.ForcedMappingsRemoved)
+	}
+	if d.NegationRulesAdded != 1 {
+		t.Errorf("negation rules added = %d, want 1", d.NegationRulesAdded)
+	}
+	if strings.Join(d.ToolsAdded, ",") != "find_dead_code" ||
+		strings.Join(d.ToolsRemoved, ",") != "get_call_chain" ||
+		strings.Join(d.ToolsChanged, ",") != "find_callers" {
+		t.Errorf("tools = +%v -%v ~%v", d.ToolsAdded, d.ToolsRemoved, d.ToolsChanged)
+	}
+	if strings.Join(d.SettingsChanged, ",") != "max_candidates,feedback_k" {
+		t.Errorf("SettingsChanged = %v", d.SettingsChanged)
+	}
+	if strings.Join(d.RestartRequired(), ",") != "feedback_k" {
+		t.Errorf("RestartRequired = %v", d.RestartRequired())
+	}
+	want := "forced mappings +1/-0, negation rules +1/-0, tools +1/-1/~1, settings: max_candidates,feedback_k"
+	if got := d.Summary(); got != want {
+		t.Errorf("Summary = %q, want %q", got, want)
+	}
+
+	same := DiffRoutingConfig(&RoutingConfig{PreFilter: oldPF, Registry: oldReg}, &RoutingConfig{PreFilter: oldPF, Registry: oldReg})
+	if !same.Empty() || same.Summary() != "no changes" {
+		t.Errorf("identical configs: %+v", same)
+	}
+}
@@ -39,6 +39,7 @@ import (
 	"gopkg.in/yaml.v3"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -301,7 +302,7 @@ func loadToolRoutingRegistry(ctx context.Context) (*ToolRoutingRegistry, error)
 			// R2: Log warning but continue with embedded default
 			slog.Warn("CB-31e: External tool registry not available, using embedded default",
 				slog.String("path", externalPath),
-				slog.String("error", err.Error()))
+				telemetry.SensitiveLogError("error", err))
 		}
 	}
 
@@ -369,7 +370,7 @@ func getExternalRegistryPath() string {
 // SEC2: File size limits
 func loadExternalYAML(ctx context.Context, path string) ([]byte, error) {
 	ctx, span := toolRegistryTracer.Start(ctx, "toolregistry.LoadExternal",
-		trace.WithAttributes(attribute.String("path", path)),
+		trace.WithAttributes(telemetry.SensitiveString("path", path)),
 	)
 	defer span.End()
 
@@ -15,8 +15,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
-	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -24,6 +22,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
 )
 
 // Assembler combines graph traversal, symbol index, and library docs
@@ -135,16 +134,90 @@ func (a *Assembler) WithLibraryDocProvider(p LibraryDocProvider) *Assembler {
 //	}
 //	fmt.Println(result.Context)
 func (a *Assembler) Assemble(ctx context.Context, query string, budget int) (*ContextResult, error) {
+	return a.AssembleWithBudget(ctx, query, ContextBudget{
+		MaxTotalTokens: budget,
+		Ranking:        RankingRelevance,
+	})
+}
+
+// AssembleWithBudget creates context for a query under a full ContextBudget.
+//
+// Description:
+//
+//	Like Assemble, but also enforces per-symbol and per-file limits and
+//	orders candidates with the budget's ranking strategy. The result
+//	records every included symbol with its token cost and every ranked
+//	symbol that was dropped, with the reason, so callers can explain why
+//	something was not shown to the model.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	query - The user's query or task description
+//	budget - Limits and ranking strategy. MaxTotalTokens must be positive.
+//
+// Outputs:
+//
+//	*ContextResult - Assembled context with budget metadata
+//	error - Non-nil if validation fails or fatal error occurs
+//
+// Errors:
+//
+//	ErrGraphNotInitialized, ErrEmptyQuery, ErrQueryTooLong, ErrInvalidBudget
+//	as for Assemble; ErrUnknownRankingStrategy for an unknown Ranking.
+//
+// Example:
+//
+//	result, err := assembler.AssembleWithBudget(ctx, "How does HandleAgent work?",
+//	    ContextBudget{MaxTotalTokens: 8000, MaxSymbolTokens: 1500, Ranking: RankingGraphProximity})
+func (a *Assembler) AssembleWithBudget(ctx context.Context, query string, budget ContextBudget) (*ContextResult, error) {
+	return a.AssembleWithSeeds(ctx, query, budget, nil)
+}
+
+// AssembleWithSeeds creates context for a query that must lead with seeds.
+//
+// Description:
+//
+//	Like AssembleWithBudget, but the seeds (e.g. from DiffSeeds) are
+//	candidates whatever the query matches: changed symbols at depth 0 are
+//	entry points of the graph walk, callers and callees sit at depth 1.
+//	After ranking, changed symbols are packed first, then callers and
+//	callees, then the rest, so budget cuts fall on query matches before
+//	the seeds.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and timeout
+//	query - The user's query or task description
+//	budget - Limits and ranking strategy. MaxTotalTokens must be positive.
+//	seeds - Symbols to lead with. Nil behaves as AssembleWithBudget.
+//
+// Outputs:
+//
+//	*ContextResult - Assembled context with budget metadata
+//	error - Non-nil if validation fails or fatal error occurs
+//
+// Errors:
+//
+//	As for AssembleWithBudget.
+func (a *Assembler) AssembleWithSeeds(ctx context.Context, query string, budget ContextBudget, seeds []Seed) (*ContextResult, error) {
 	// Start tracing span
-	ctx, span := startAssembleSpan(ctx, len(query), budget)
+	ctx, span := startAssembleSpan(ctx, len(query), budget.MaxTotalTokens)
 	defer span.End()
 	start := time.Now()
 
 	// Validate inputs
-	if err := a.validateInputs(query, budget); err != nil {
+	if err := a.validateInputs(query, budget.MaxTotalTokens); err != nil {
 		recordAssembleMetrics(ctx, time.Since(start), 0, 0, false)
 		return nil, err
 	}
+	if err := budget.Validate(); err != nil {
+		recordAssembleMetrics(ctx, time.Since(start), 0, 0, false)
+		return nil, err
+	}
+	if budget.Ranking == "" {
+		budget.Ranking = RankingRelevance
+	}
 
 	// Apply timeout from options
 	if a.options.Timeout > 0 {
@@ -157,6 +230,9 @@ func (a *Assembler) Assemble(ctx context.Context, query string, budget int) (*Co
 		SymbolsIncluded:     make([]string, 0),
 		LibraryDocsIncluded: make([]string, 0),
 		Suggestions:         make([]string, 0),
+		Budget:              budget,
+		Included:            make([]BudgetEntry, 0),
+		Dropped:             make([]BudgetEntry, 0),
 	}
 
 	// Step 1: Find entry points from query
@@ -165,9 +241,14 @@ func (a *Assembler) Assemble(ctx context.Context, query string, budget int) (*Co
 		recordAssembleMetrics(ctx, time.Since(start), 0, 0, false)
 		return nil, err
 	}
+	for _, seed := range seeds {
+		if seed.Relation == SeedChanged {
+			entryPoints = append(entryPoints, seed.Symbol)
+		}
+	}
 	recordEntryPointsMetrics(ctx, len(entryPoints))
 
-	if len(entryPoints) == 0 {
+	if len(entryPoints) == 0 && len(seeds) == 0 {
 		// No matches - return helpful suggestions
 		result.Suggestions = append(result.Suggestions,
 			"No symbols found matching the query. Try using more specific terms.",
@@ -183,16 +264,16 @@ func (a *Assembler) Assemble(ctx context.Context, query string, budget int) (*Co
 		recordAssembleMetrics(ctx, time.Since(start), 0, 0, false)
 		return nil, err
 	}
+	placeSeeds(relatedSymbols, seeds)
 	recordGraphWalkMetrics(ctx, len(relatedSymbols))
 
 	// Step 3: Score and rank symbols
 	scoredSymbols := a.scoreSymbols(query, relatedSymbols)
-	sort.Slice(scoredSymbols, func(i, j int) bool {
-		return scoredSymbols[i].Score > scoredSymbols[j].Score
-	})
+	rankSymbols(budget.Ranking, scoredSymbols, a.projectRoot)
+	leadWithSeeds(scoredSymbols, seeds)
 
 	// Step 4: Calculate budget allocation
-	effectiveBudget := int(float64(budget) * (1.0 - TokenSafetyBuffer))
+	effectiveBudget := int(float64(budget.MaxTotalTokens) * (1.0 - TokenSafetyBuffer))
 	codeBudget := effectiveBudget * a.options.BudgetAllocation.CodePercent / 100
 	typesBudget := effectiveBudget * a.options.BudgetAllocation.TypesPercent / 100
 	libDocsBudget := effectiveBudget * a.options.BudgetAllocation.LibDocsPercent / 100
@@ -201,11 +282,15 @@ func (a *Assembler) Assemble(ctx context.Context, query string, budget int) (*Co
 	var contextBuilder strings.Builder
 
 	// Pack primary code
-	codeSection, codeTokens, includedSymbols := a.packCodeSection(ctx, scoredSymbols, codeBudget)
+	codeSection, codeTokens, included, dropped := a.packCodeSection(ctx, scoredSymbols, codeBudget, budget)
+	result.Dropped = dropped
 	if codeSection != "" {
 		contextBuilder.WriteString("## Relevant Code\n\n")
 		contextBuilder.WriteString(codeSection)
-		result.SymbolsIncluded = includedSymbols
+		result.Included = included
+		for _, entry := range included {
+			result.SymbolsIncluded = append(result.SymbolsIncluded, entry.SymbolID)
+		}
 		result.TokensUsed += codeTokens
 	}
 
@@ -233,13 +318,9 @@ func (a *Assembler) Assemble(ctx context.Context, query string, budget int) (*Co
 	result.AssemblyDurationMs = time.Since(start).Milliseconds()
 
 	// Add suggestions for symbols that didn't fit
-	if result.Truncated && len(scoredSymbols) > len(result.SymbolsIncluded) {
-		for i := len(result.SymbolsIncluded); i < len(scoredSymbols) && i < len(result.SymbolsIncluded)+3; i++ {
-			if scoredSymbols[i].Symbol != nil {
-				result.Suggestions = append(result.Suggestions,
-					fmt.Sprintf("Consider also: %s", scoredSymbols[i].Symbol.FilePath))
-			}
-		}
+	for i := 0; i < len(result.Dropped) && i < 3; i++ {
+		result.Suggestions = append(result.Suggestions,
+			fmt.Sprintf("Consider also: %s", result.Dropped[i].FilePath))
 	}
 
 	// Record successful assembly metrics
@@ -500,9 +581,25 @@ func estimateTokens(text string) int {
 }
 
 // packCodeSection formats code symbols into markdown within budget.
-func (a *Assembler) packCodeSection(ctx context.Context, symbols []*ScoredSymbol, budget int) (string, int, []string) {
+//
+// Description:
+//
+//	Walks symbols in ranked order. Symbols larger than
+//	limits.MaxSymbolTokens are truncated; symbols from a new file beyond
+//	limits.MaxFiles, or that no longer fit in budget, are recorded as
+//	dropped and packing continues with the next candidate.
+//
+// Outputs:
+//
+//	string - The formatted code section.
+//	int - Tokens used.
+//	[]BudgetEntry - Included symbols, in order.
+//	[]BudgetEntry - Dropped symbols, in rank order.
+func (a *Assembler) packCodeSection(ctx context.Context, symbols []*ScoredSymbol, budget int, limits ContextBudget) (string, int, []BudgetEntry, []BudgetEntry) {
 	var builder strings.Builder
-	var included []string
+	included := make([]BudgetEntry, 0)
+	dropped := make([]BudgetEntry, 0)
+	files := make(map[string]bool)
 	tokensUsed := 0
 
 	for _, scored := range symbols {
@@ -520,21 +617,40 @@ func (a *Assembler) packCodeSection(ctx context.Context, symbols []*ScoredSymbol
 			continue
 		}
 
+		entry := BudgetEntry{
+			SymbolID: scored.Symbol.ID,
+			Name:     scored.Symbol.Name,
+			FilePath: scored.Symbol.FilePath,
+			Score:    scored.Score,
+			Depth:    scored.Depth,
+		}
+
+		if limits.MaxFiles > 0 && !files[entry.FilePath] && len(files) >= limits.MaxFiles {
+			entry.Reason = DropReasonMaxFiles
+			dropped = append(dropped, entry)
+			continue
+		}
+
 		// Format symbol as markdown code block with full source
-		section := a.formatCodeSymbol(scored.Symbol)
+		section, truncated := a.formatCodeSymbolLimited(scored.Symbol, limits.MaxSymbolTokens)
 		sectionTokens := estimateTokens(section)
+		entry.Tokens = sectionTokens
+		entry.Truncated = truncated
 
 		if tokensUsed+sectionTokens > budget {
-			break
+			entry.Reason = DropReasonTotalBudget
+			dropped = append(dropped, entry)
+			continue
 		}
 
 		builder.WriteString(section)
 		builder.WriteString("\n")
 		tokensUsed += sectionTokens
-		included = append(included, scored.Symbol.ID)
+		files[entry.FilePath] = true
+		included = append(included, entry)
 	}
 
-	return builder.String(), tokensUsed, included
+	return builder.String(), tokensUsed, included, dropped
 }
 
 // formatCodeSymbol formats a symbol as a markdown code block with full source.
@@ -547,46 +663,110 @@ func (a *Assembler) packCodeSection(ctx context.Context, symbols []*ScoredSymbol
 //
 // Thread Safety: This method is safe for concurrent use.
 func (a *Assembler) formatCodeSymbol(sym *ast.Symbol) string {
-	var builder strings.Builder
-
-	// Header with file path and lines
-	builder.WriteString(fmt.Sprintf("### %s (lines %d-%d)\n",
-		sym.FilePath, sym.StartLine, sym.EndLine))
+	section, _ := a.formatCodeSymbolLimited(sym, 0)
+	return section
+}
 
-	// Code block with language
-	lang := sym.Language
-	if lang == "" {
-		lang = "go" // Default to Go for this codebase
-	}
-	builder.WriteString(fmt.Sprintf("```%s\n", lang))
+// formatCodeSymbolLimited formats a symbol like formatCodeSymbol, cutting the
+// source at a line boundary so the section fits in maxTokens.
+//
+// Description:
+//
+//	A maxTokens of zero or less means no limit. When the source is cut, a
+//	marker line reporting the omitted line count is added inside the code
+//	block so the model knows it is seeing a partial symbol.
+//
+// Outputs:
+//
+//	string - The formatted section.
+//	bool - True if the source was truncated.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (a *Assembler) formatCodeSymbolLimited(sym *ast.Symbol, maxTokens int) (string, bool) {
+	var body strings.Builder
 
 	// Try to read the actual source code
 	sourceCode, err := a.readSourceLines(sym.FilePath, sym.StartLine, sym.EndLine)
 	if err == nil && sourceCode != "" {
 		// Include doc comment if it's not already part of the source
 		if sym.DocComment != "" && !strings.Contains(sourceCode, sym.DocComment) {
-			builder.WriteString(sym.DocComment)
-			builder.WriteString("\n")
+			body.WriteString(sym.DocComment)
+			body.WriteString("\n")
 		}
-		builder.WriteString(sourceCode)
+		body.WriteString(sourceCode)
 	} else {
 		// Fallback to signature-only if file read fails
 		if sym.DocComment != "" {
-			builder.WriteString(sym.DocComment)
-			builder.WriteString("\n")
+			body.WriteString(sym.DocComment)
+			body.WriteString("\n")
 		}
 		if sym.Signature != "" {
-			builder.WriteString(sym.Signature)
+			body.WriteString(sym.Signature)
 		} else {
-			builder.WriteString(fmt.Sprintf("%s %s", sym.Kind.String(), sym.Name))
+			body.WriteString(fmt.Sprintf("%s %s", sym.Kind.String(), sym.Name))
 		}
 	}
 
-	builder.WriteString("\n```\n")
+	// Header with file path and lines, code block with language
+	lang := sym.Language
+	if lang == "" {
+		lang = "go" // Default to Go for this codebase
+	}
+	header := fmt.Sprintf("### %s (lines %d-%d)\n%s```%s\n", sym.FilePath, sym.StartLine, sym.EndLine, a.AnnotationNote(sym), lang)
+	const footer = "\n```\n"
+
+	code := body.String()
+	truncated := false
+	if maxTokens > 0 && estimateTokens(header+code+footer) > maxTokens {
+		code = truncateLines(code, int(float64(maxTokens)*CharsPerToken)-len(header)-len(footer))
+		truncated = true
+	}
+
+	return header + code + footer, truncated
+}
 
+// truncateLines cuts text at a line boundary so the result, including a
+// trailing "... (N more lines truncated)" marker, fits in maxChars.
+// At least one line is always kept.
+func truncateLines(text string, maxChars int) string {
+	lines := strings.Split(text, "\n")
+	var builder strings.Builder
+	kept := 0
+	for i, line := range lines {
+		marker := fmt.Sprintf("\n... (%d more lines truncated)", len(lines)-i-1)
+		if kept > 0 && builder.Len()+1+len(line)+len(marker) > maxChars {
+			break
+		}
+		if kept > 0 {
+			builder.WriteString("\n")
+		}
+		builder.WriteString(line)
+		kept++
+	}
+	if kept < len(lines) {
+		builder.WriteString(fmt.Sprintf("\n... (%d more lines truncated)", len(lines)-kept))
+	}
 	return builder.String()
 }
 
+// AnnotationNote returns a one-line note listing a symbol's annotations,
+// such as "Annotations: deprecated: true; replacement: NewTotal", so the
+// model sees organizational metadata next to the code.
+//
+// Outputs:
+//
+//	string - The note ending in a newline, or "" if the symbol has no
+//	  annotations.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (a *Assembler) AnnotationNote(sym *ast.Symbol) string {
+	annotations := graph.FormatAnnotations(a.graph.AnnotationsOf(sym))
+	if annotations == "" {
+		return ""
+	}
+	return "Annotations: " + annotations + "\n"
+}
+
 // GetSymbolSourceCode returns the source code for a symbol.
 //
 // Description:
@@ -679,7 +859,10 @@ func (a *Assembler) getFileLines(filePath string) ([]string, error) {
 	a.fileCacheMu.RUnlock()
 
 	// Read file from disk
-	fullPath := filepath.Join(a.projectRoot, filePath)
+	fullPath, err := validation.ResolveWithin(a.projectRoot, filePath)
+	if err != nil {
+		return nil, fmt.Errorf("open file %s: %w", filePath, err)
+	}
 	file, err := os.Open(fullPath)
 	if err != nil {
 		return nil, fmt.Errorf("open file %s: %w", filePath, err)
@@ -321,6 +321,69 @@ func TestAssembler_Assemble_BudgetConstraint(t *testing.T) {
 	}
 }
 
+func TestAssembler_AssembleWithBudget(t *testing.T) {
+	g, idx := createTestGraph(t)
+	ctx := context.Background()
+	a := NewAssembler(g, idx)
+
+	t.Run("reports included symbols and budget", func(t *testing.T) {
+		result, err := a.AssembleWithBudget(ctx, "HandleUser", ContextBudget{MaxTotalTokens: 8000})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Budget.Ranking != RankingRelevance {
+			t.Errorf("expected default ranking %q, got %q", RankingRelevance, result.Budget.Ranking)
+		}
+		if len(result.Included) != len(result.SymbolsIncluded) {
+			t.Errorf("Included has %d entries, SymbolsIncluded has %d", len(result.Included), len(result.SymbolsIncluded))
+		}
+		for _, e := range result.Included {
+			if e.Tokens <= 0 {
+				t.Errorf("included %s has no token count", e.SymbolID)
+			}
+		}
+	})
+
+	t.Run("drops symbols beyond MaxFiles", func(t *testing.T) {
+		result, err := a.AssembleWithBudget(ctx, "HandleUser", ContextBudget{MaxTotalTokens: 8000, MaxFiles: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		files := make(map[string]bool)
+		for _, e := range result.Included {
+			files[e.FilePath] = true
+		}
+		if len(files) != 1 {
+			t.Errorf("expected code from 1 file, got %d", len(files))
+		}
+		if len(result.Dropped) == 0 {
+			t.Fatal("expected dropped symbols")
+		}
+		for _, e := range result.Dropped {
+			if e.Reason != DropReasonMaxFiles {
+				t.Errorf("dropped %s: reason %q, want %q", e.SymbolID, e.Reason, DropReasonMaxFiles)
+			}
+		}
+	})
+
+	t.Run("graph-proximity puts the entry point first", func(t *testing.T) {
+		result, err := a.AssembleWithBudget(ctx, "HandleUser", ContextBudget{MaxTotalTokens: 8000, Ranking: RankingGraphProximity})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Included) == 0 || result.Included[0].Depth != 0 {
+			t.Errorf("expected depth-0 symbol first, got %+v", result.Included)
+		}
+	})
+
+	t.Run("rejects unknown ranking", func(t *testing.T) {
+		_, err := a.AssembleWithBudget(ctx, "HandleUser", ContextBudget{MaxTotalTokens: 8000, Ranking: "newest"})
+		if !errors.Is(err, ErrUnknownRankingStrategy) {
+			t.Errorf("expected ErrUnknownRankingStrategy, got %v", err)
+		}
+	})
+}
+
 func TestAssembler_Assemble_NoMatches(t *testing.T) {
 	g, idx := createTestGraph(t)
 	ctx := context.Background()
@@ -0,0 +1,170 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package context
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment variables read by ContextBudgetFromEnv.
+const (
+	// EnvContextMaxTokens sets ContextBudget.MaxTotalTokens.
+	EnvContextMaxTokens = "TRACE_CONTEXT_MAX_TOKENS"
+
+	// EnvContextMaxSymbolTokens sets ContextBudget.MaxSymbolTokens.
+	EnvContextMaxSymbolTokens = "TRACE_CONTEXT_MAX_SYMBOL_TOKENS"
+
+	// EnvContextMaxFiles sets ContextBudget.MaxFiles.
+	EnvContextMaxFiles = "TRACE_CONTEXT_MAX_FILES"
+
+	// EnvContextRanking sets ContextBudget.Ranking.
+	EnvContextRanking = "TRACE_CONTEXT_RANKING"
+)
+
+// Drop reasons reported in ContextResult.Dropped.
+const (
+	// DropReasonTotalBudget means the symbol did not fit in the remaining code budget.
+	DropReasonTotalBudget = "total_budget"
+
+	// DropReasonMaxFiles means the symbol's file would exceed ContextBudget.MaxFiles.
+	DropReasonMaxFiles = "max_files"
+)
+
+// ContextBudget limits what the assembler may put into a prompt.
+//
+// Description:
+//
+//	Zero values mean "no limit" (or "default" for MaxTotalTokens and
+//	Ranking), so a partial budget can be layered over another with Merge.
+//
+// Thread Safety:
+//
+//	ContextBudget is a value type and safe to copy.
+type ContextBudget struct {
+	// MaxTotalTokens is the total token budget for the assembled context.
+	// Zero uses DefaultTokenBudget.
+	MaxTotalTokens int `json:"max_total_tokens,omitempty"`
+
+	// MaxSymbolTokens caps the tokens spent on any single symbol. Larger
+	// symbols are truncated rather than dropped. Zero means no cap.
+	MaxSymbolTokens int `json:"max_symbol_tokens,omitempty"`
+
+	// MaxFiles caps the number of distinct files code is drawn from.
+	// Zero means no cap.
+	MaxFiles int `json:"max_files,omitempty"`
+
+	// Ranking names the ranking strategy (see RankingStrategyNames).
+	// Empty uses RankingRelevance.
+	Ranking string `json:"ranking,omitempty"`
+}
+
+// DefaultContextBudget returns the budget used when nothing is configured.
+func DefaultContextBudget() ContextBudget {
+	return ContextBudget{
+		MaxTotalTokens: DefaultTokenBudget,
+		Ranking:        RankingRelevance,
+	}
+}
+
+// ContextBudgetFromEnv reads a ContextBudget from environment variables.
+//
+// Description:
+//
+//	Starts from DefaultContextBudget and applies TRACE_CONTEXT_MAX_TOKENS,
+//	TRACE_CONTEXT_MAX_SYMBOL_TOKENS, TRACE_CONTEXT_MAX_FILES and
+//	TRACE_CONTEXT_RANKING. Unparseable or negative numbers and unknown
+//	strategy names are ignored so a typo cannot disable context assembly.
+//
+// Outputs:
+//
+//	ContextBudget - The configured budget.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use (reads only environment).
+func ContextBudgetFromEnv() ContextBudget {
+	b := DefaultContextBudget()
+
+	if v := os.Getenv(EnvContextMaxTokens); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			b.MaxTotalTokens = n
+		}
+	}
+	if v := os.Getenv(EnvContextMaxSymbolTokens); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			b.MaxSymbolTokens = n
+		}
+	}
+	if v := os.Getenv(EnvContextMaxFiles); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+			b.MaxFiles = n
+		}
+	}
+	if v := os.Getenv(EnvContextRanking); v != "" && IsValidRankingStrategy(v) {
+		b.Ranking = v
+	}
+
+	return b
+}
+
+// Merge returns b with the non-zero fields of overrides applied.
+func (b ContextBudget) Merge(overrides ContextBudget) ContextBudget {
+	if overrides.MaxTotalTokens > 0 {
+		b.MaxTotalTokens = overrides.MaxTotalTokens
+	}
+	if overrides.MaxSymbolTokens > 0 {
+		b.MaxSymbolTokens = overrides.MaxSymbolTokens
+	}
+	if overrides.MaxFiles > 0 {
+		b.MaxFiles = overrides.MaxFiles
+	}
+	if overrides.Ranking != "" {
+		b.Ranking = overrides.Ranking
+	}
+	return b
+}
+
+// Validate checks that the budget is usable.
+//
+// Outputs:
+//
+//	error - ErrInvalidBudget for negative limits, ErrUnknownRankingStrategy
+//	  for an unknown strategy name.
+func (b ContextBudget) Validate() error {
+	if b.MaxTotalTokens < 0 || b.MaxSymbolTokens < 0 || b.MaxFiles < 0 {
+		return ErrInvalidBudget
+	}
+	if b.Ranking != "" && !IsValidRankingStrategy(b.Ranking) {
+		return fmt.Errorf("%w: %q (valid: %v)", ErrUnknownRankingStrategy, b.Ranking, RankingStrategyNames())
+	}
+	return nil
+}
+
+// TruncateToTokens cuts text at a line boundary so it fits in maxTokens.
+//
+// Description:
+//
+//	Uses the same estimate and truncation marker as the assembler's
+//	per-symbol cap, for callers that hold symbol source outside the
+//	formatted context. A maxTokens of zero or less means no limit.
+//
+// Outputs:
+//
+//	string - The possibly truncated text.
+//	bool - True if text was truncated.
+func TruncateToTokens(text string, maxTokens int) (string, bool) {
+	if maxTokens <= 0 || estimateTokens(text) <= maxTokens {
+		return text, false
+	}
+	return truncateLines(text, int(float64(maxTokens)*CharsPerToken)), true
+}
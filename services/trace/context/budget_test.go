@@ -0,0 +1,96 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package context
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestContextBudgetFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		b := ContextBudgetFromEnv()
+		if b != DefaultContextBudget() {
+			t.Errorf("got %+v, want defaults %+v", b, DefaultContextBudget())
+		}
+	})
+
+	t.Run("all set", func(t *testing.T) {
+		t.Setenv(EnvContextMaxTokens, "12000")
+		t.Setenv(EnvContextMaxSymbolTokens, "1500")
+		t.Setenv(EnvContextMaxFiles, "6")
+		t.Setenv(EnvContextRanking, RankingGraphProximity)
+
+		want := ContextBudget{MaxTotalTokens: 12000, MaxSymbolTokens: 1500, MaxFiles: 6, Ranking: RankingGraphProximity}
+		if b := ContextBudgetFromEnv(); b != want {
+			t.Errorf("got %+v, want %+v", b, want)
+		}
+	})
+
+	t.Run("invalid values ignored", func(t *testing.T) {
+		t.Setenv(EnvContextMaxTokens, "-5")
+		t.Setenv(EnvContextMaxSymbolTokens, "lots")
+		t.Setenv(EnvContextRanking, "newest")
+
+		if b := ContextBudgetFromEnv(); b != DefaultContextBudget() {
+			t.Errorf("got %+v, want defaults", b)
+		}
+	})
+}
+
+func TestContextBudget_Merge(t *testing.T) {
+	base := ContextBudget{MaxTotalTokens: 8000, MaxSymbolTokens: 1000, MaxFiles: 5, Ranking: RankingRelevance}
+	got := base.Merge(ContextBudget{MaxFiles: 2, Ranking: RankingRecency})
+
+	want := ContextBudget{MaxTotalTokens: 8000, MaxSymbolTokens: 1000, MaxFiles: 2, Ranking: RankingRecency}
+	if got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestContextBudget_Validate(t *testing.T) {
+	if err := DefaultContextBudget().Validate(); err != nil {
+		t.Errorf("default budget invalid: %v", err)
+	}
+	if err := (ContextBudget{MaxFiles: -1}).Validate(); !errors.Is(err, ErrInvalidBudget) {
+		t.Errorf("negative MaxFiles: got %v, want ErrInvalidBudget", err)
+	}
+	if err := (ContextBudget{Ranking: "newest"}).Validate(); !errors.Is(err, ErrUnknownRankingStrategy) {
+		t.Errorf("unknown ranking: got %v, want ErrUnknownRankingStrategy", err)
+	}
+}
+
+func TestTruncateToTokens(t *testing.T) {
+	var lines []string
+	for i := 0; i < 200; i++ {
+		lines = append(lines, "x := computeSomethingLong(alpha, beta, gamma)")
+	}
+	text := strings.Join(lines, "\n")
+
+	out, truncated := TruncateToTokens(text, 100)
+	if !truncated {
+		t.Fatal("expected truncation")
+	}
+	if estimateTokens(out) > 100 {
+		t.Errorf("truncated text uses %d tokens, want <= 100", estimateTokens(out))
+	}
+	if !strings.Contains(out, "more lines truncated)") {
+		t.Errorf("missing truncation marker: %q", out[len(out)-60:])
+	}
+
+	if same, truncated := TruncateToTokens("short", 100); truncated || same != "short" {
+		t.Error("short text must be returned unchanged")
+	}
+	if same, truncated := TruncateToTokens(text, 0); truncated || same != text {
+		t.Error("zero limit must not truncate")
+	}
+}
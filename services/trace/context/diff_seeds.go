@@ -0,0 +1,266 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package context
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
+)
+
+// SeedRelation says why a seed symbol is in the context.
+type SeedRelation string
+
+const (
+	// SeedChanged marks a symbol whose lines a diff changes.
+	SeedChanged SeedRelation = "changed"
+
+	// SeedCaller marks a direct caller of a changed symbol.
+	SeedCaller SeedRelation = "caller"
+
+	// SeedCallee marks a symbol a changed symbol calls directly.
+	SeedCallee SeedRelation = "callee"
+)
+
+// MaxSeedNeighbors bounds the callers, and separately the callees, added
+// for each changed symbol, so a change to a hub does not flood the seeds.
+const MaxSeedNeighbors = 10
+
+// Seed is a symbol the context must lead with, such as a symbol a diff
+// changes.
+type Seed struct {
+	// Symbol is the seeded symbol.
+	Symbol *ast.Symbol
+
+	// Relation says why the symbol is seeded.
+	Relation SeedRelation
+
+	// Temporary marks a symbol parsed from a file the graph does not
+	// contain, e.g. one the diff creates. It is not in the graph or index.
+	Temporary bool
+}
+
+// DiffSeeds maps the lines a diff changes onto symbols.
+//
+// Description:
+//
+//	For files in the graph, every changed line is resolved to its
+//	innermost symbol with the per-file interval index; all symbols of a
+//	deleted file count as changed. The direct callers and callees of each
+//	changed symbol follow, up to MaxSeedNeighbors of each.
+//
+//	Files without symbols in the graph are parsed on the fly with parsers:
+//	the file on disk when it exists, otherwise the new file's content from
+//	the diff, which is then served to the assembler in place of the file.
+//	Their top-level symbols touching a changed line become temporary
+//	seeds. Files no parser handles are skipped.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	files - The diff's changed lines (see diff.ChangedLines).
+//	parsers - Parsers for files outside the graph. Nil skips those files.
+//
+// Outputs:
+//
+//	[]Seed - Changed symbols in diff order, then callers and callees.
+//	  Each symbol appears once, with its closest relation.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (a *Assembler) DiffSeeds(ctx context.Context, files []diff.FileLines, parsers *ast.ParserRegistry) []Seed {
+	if a.graph == nil {
+		return nil
+	}
+
+	var changed []Seed
+	seen := make(map[string]bool)
+	add := func(seed Seed) {
+		if seed.Symbol == nil || seen[seed.Symbol.ID] || !seedKind(seed.Symbol.Kind) {
+			return
+		}
+		seen[seed.Symbol.ID] = true
+		changed = append(changed, seed)
+	}
+
+	for _, file := range files {
+		if ctx.Err() != nil {
+			break
+		}
+		inGraph := a.graph.SymbolsInFile(file.Path)
+		switch {
+		case file.IsDelete:
+			for _, node := range inGraph {
+				add(Seed{Symbol: node.Symbol, Relation: SeedChanged})
+			}
+		case len(inGraph) > 0:
+			for _, r := range file.Ranges {
+				for line := r.Start; line <= r.End; line++ {
+					if node := a.graph.SymbolAt(file.Path, line, -1); node != nil {
+						add(Seed{Symbol: node.Symbol, Relation: SeedChanged})
+					}
+				}
+			}
+		default:
+			for _, sym := range a.parseDiffFile(ctx, file, parsers) {
+				add(Seed{Symbol: sym, Relation: SeedChanged, Temporary: true})
+			}
+		}
+	}
+
+	seeds := changed
+	for _, seed := range changed {
+		if seed.Temporary {
+			continue
+		}
+		node, ok := a.graph.GetNode(seed.Symbol.ID)
+		if !ok {
+			continue
+		}
+		var callers, callees []Seed
+		for _, edge := range node.Incoming {
+			if edge.Type == graph.EdgeTypeCalls && len(callers) < MaxSeedNeighbors {
+				callers = a.appendNeighbor(callers, seen, edge.FromID, SeedCaller)
+			}
+		}
+		for _, edge := range node.Outgoing {
+			if edge.Type == graph.EdgeTypeCalls && len(callees) < MaxSeedNeighbors {
+				callees = a.appendNeighbor(callees, seen, edge.ToID, SeedCallee)
+			}
+		}
+		seeds = append(append(seeds, callers...), callees...)
+	}
+	return seeds
+}
+
+// appendNeighbor appends the node id as a seed with relation unless it
+// is already seeded or has no symbol.
+func (a *Assembler) appendNeighbor(seeds []Seed, seen map[string]bool, id string, relation SeedRelation) []Seed {
+	if seen[id] {
+		return seeds
+	}
+	node, ok := a.graph.GetNode(id)
+	if !ok || node.Symbol == nil || !seedKind(node.Symbol.Kind) {
+		return seeds
+	}
+	seen[id] = true
+	return append(seeds, Seed{Symbol: node.Symbol, Relation: relation})
+}
+
+// parseDiffFile parses a changed file the graph does not contain and
+// returns its top-level symbols touching the changed lines.
+func (a *Assembler) parseDiffFile(ctx context.Context, file diff.FileLines, parsers *ast.ParserRegistry) []*ast.Symbol {
+	if parsers == nil {
+		return nil
+	}
+	parser, ok := parsers.GetByExtension(filepath.Ext(file.Path))
+	if !ok {
+		return nil
+	}
+
+	content, fromDisk := a.readProjectFile(file.Path)
+	if !fromDisk {
+		if !file.IsNew {
+			// The hunks of an existing file are fragments; parsing them
+			// would give wrong symbols and lines.
+			return nil
+		}
+		content = []byte(file.NewContent)
+	}
+	result, err := parser.Parse(ctx, content, file.Path)
+	if err != nil || result == nil {
+		return nil
+	}
+	if !fromDisk {
+		a.fileCacheMu.Lock()
+		a.fileCache[file.Path] = strings.Split(strings.TrimSuffix(file.NewContent, "\n"), "\n")
+		a.fileCacheMu.Unlock()
+	}
+
+	var symbols []*ast.Symbol
+	for _, sym := range result.Symbols {
+		if sym == nil {
+			continue
+		}
+		for _, r := range file.Ranges {
+			if r.Overlaps(sym.StartLine, max(sym.EndLine, sym.StartLine)) {
+				symbols = append(symbols, sym)
+				break
+			}
+		}
+	}
+	return symbols
+}
+
+// readProjectFile reads a file under the project root. The bool is false
+// if it cannot be read.
+func (a *Assembler) readProjectFile(relPath string) ([]byte, bool) {
+	if a.projectRoot == "" {
+		return nil, false
+	}
+	fullPath, err := validation.ResolveWithin(a.projectRoot, relPath)
+	if err != nil {
+		return nil, false
+	}
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, false
+	}
+	return content, true
+}
+
+// seedKind reports whether symbols of a kind are worth seeding; imports
+// and package clauses carry no code to review.
+func seedKind(kind ast.SymbolKind) bool {
+	return kind != ast.SymbolKindImport && kind != ast.SymbolKindPackage
+}
+
+// placeSeeds adds the seeds to the assembler's candidates: changed
+// symbols at depth 0, callers and callees at depth 1.
+func placeSeeds(symbols map[string]*ScoredSymbol, seeds []Seed) {
+	for _, seed := range seeds {
+		depth := 1
+		if seed.Relation == SeedChanged {
+			depth = 0
+		}
+		symbols[seed.Symbol.ID] = &ScoredSymbol{Symbol: seed.Symbol, Depth: depth}
+	}
+}
+
+// leadWithSeeds stably reorders ranked symbols so changed symbols come
+// first, then callers and callees, each group keeping its ranked order.
+func leadWithSeeds(ranked []*ScoredSymbol, seeds []Seed) {
+	if len(seeds) == 0 {
+		return
+	}
+	group := make(map[string]int, len(seeds))
+	for _, seed := range seeds {
+		group[seed.Symbol.ID] = 1
+		if seed.Relation == SeedChanged {
+			group[seed.Symbol.ID] = 0
+		}
+	}
+	rank := func(s *ScoredSymbol) int {
+		if g, ok := group[s.Symbol.ID]; ok {
+			return g
+		}
+		return 2
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return rank(ranked[i]) < rank(ranked[j])
+	})
+}
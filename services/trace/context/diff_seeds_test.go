@@ -0,0 +1,88 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package context
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/diff"
+)
+
+// reviewDiff changes HandleAuth and adds a file the test graph lacks.
+const reviewDiff = `--- a/handlers/user.go
++++ b/handlers/user.go
+@@ -39,3 +39,3 @@ func HandleAuth(c *gin.Context) {
+ 	token := c.GetHeader("Authorization")
+-	if token == "" {
++	if strings.TrimSpace(token) == "" {
+ 		c.AbortWithStatus(401)
+--- /dev/null
++++ b/handlers/audit.go
+@@ -0,0 +1,5 @@
++package handlers
++
++func RecordAudit(event string) {
++	println(event)
++}
+`
+
+func TestAssembler_DiffSeeds(t *testing.T) {
+	g, idx := createTestGraph(t)
+	a := NewAssembler(g, idx)
+	files, err := diff.ChangedLines(reviewDiff)
+	if err != nil {
+		t.Fatalf("ChangedLines: %v", err)
+	}
+	parsers := ast.NewParserRegistry()
+	parsers.Register(ast.NewGoParser())
+
+	seeds := a.DiffSeeds(context.Background(), files, parsers)
+	got := make([]string, 0, len(seeds))
+	for _, seed := range seeds {
+		got = append(got, string(seed.Relation)+":"+seed.Symbol.Name)
+	}
+	want := "changed:HandleAuth changed:RecordAudit caller:HandleUser"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("seeds = %v, want %s", got, want)
+	}
+	if seeds[0].Temporary || !seeds[1].Temporary {
+		t.Errorf("only the symbol from the new file is temporary: %+v", seeds)
+	}
+
+	t.Run("without parsers new files are skipped", func(t *testing.T) {
+		seeds := a.DiffSeeds(context.Background(), files, nil)
+		if len(seeds) != 2 || seeds[0].Symbol.Name != "HandleAuth" {
+			t.Errorf("seeds = %+v", seeds)
+		}
+	})
+
+	t.Run("assembly leads with the seeds", func(t *testing.T) {
+		result, err := a.AssembleWithSeeds(context.Background(), "review this change please",
+			ContextBudget{MaxTotalTokens: 8000}, seeds)
+		if err != nil {
+			t.Fatalf("AssembleWithSeeds: %v", err)
+		}
+		var names []string
+		for _, e := range result.Included {
+			names = append(names, e.Name)
+		}
+		if len(names) < 3 || names[2] != "HandleUser" ||
+			!(names[0] == "HandleAuth" && names[1] == "RecordAudit" || names[0] == "RecordAudit" && names[1] == "HandleAuth") {
+			t.Fatalf("included = %v, want the changed symbols then the caller", names)
+		}
+		if !strings.Contains(result.Context, "func RecordAudit(event string) {") {
+			t.Errorf("context lacks the new file's source:\n%s", result.Context)
+		}
+	})
+}
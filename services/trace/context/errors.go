@@ -28,6 +28,9 @@ var (
 
 	// ErrAssemblyTimeout indicates the assembly operation timed out.
 	ErrAssemblyTimeout = errors.New("context assembly timed out")
+
+	// ErrUnknownRankingStrategy indicates a ranking strategy name that is not registered.
+	ErrUnknownRankingStrategy = errors.New("unknown ranking strategy")
 )
 
 // LLM-related errors for summary generation.
@@ -0,0 +1,154 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Ranking strategy names accepted by ContextBudget.Ranking.
+const (
+	// RankingRelevance orders by the combined query-similarity, graph-distance
+	// and symbol-importance score. This is the default.
+	RankingRelevance = "relevance"
+
+	// RankingGraphProximity orders by shortest edge distance from the symbols
+	// resolved from the query, breaking ties by relevance.
+	RankingGraphProximity = "graph-proximity"
+
+	// RankingRecency orders by the modification time of the symbol's file,
+	// most recent first, breaking ties by relevance.
+	RankingRecency = "recency"
+)
+
+// rankingStrategies maps strategy names to their implementations.
+var rankingStrategies = map[string]rankingStrategy{
+	RankingRelevance:      rankByRelevance,
+	RankingGraphProximity: rankByGraphProximity,
+	RankingRecency:        rankByRecency,
+}
+
+// rankingStrategy orders scored symbols in place, best first.
+//
+// projectRoot is used to resolve relative file paths for strategies that
+// need file system metadata. Score and Depth are already populated.
+type rankingStrategy func(symbols []*ScoredSymbol, projectRoot string)
+
+// RankingStrategyNames returns the registered strategy names, sorted.
+func RankingStrategyNames() []string {
+	names := make([]string, 0, len(rankingStrategies))
+	for name := range rankingStrategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsValidRankingStrategy reports whether name is a registered strategy.
+func IsValidRankingStrategy(name string) bool {
+	_, ok := rankingStrategies[name]
+	return ok
+}
+
+// rankSymbols orders symbols with the named strategy.
+//
+// Empty or unknown names fall back to RankingRelevance; callers validate
+// names up front via ContextBudget.Validate.
+func rankSymbols(name string, symbols []*ScoredSymbol, projectRoot string) {
+	strategy, ok := rankingStrategies[name]
+	if !ok {
+		strategy = rankByRelevance
+	}
+	strategy(symbols, projectRoot)
+}
+
+// rankByRelevance orders by Score descending.
+func rankByRelevance(symbols []*ScoredSymbol, _ string) {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		return lessByScore(symbols[i], symbols[j])
+	})
+}
+
+// rankByGraphProximity orders by Depth ascending. Depth is the BFS distance
+// from the entry points, so it is already the shortest edge distance.
+func rankByGraphProximity(symbols []*ScoredSymbol, _ string) {
+	sort.SliceStable(symbols, func(i, j int) bool {
+		if symbols[i].Depth != symbols[j].Depth {
+			return symbols[i].Depth < symbols[j].Depth
+		}
+		return lessByScore(symbols[i], symbols[j])
+	})
+}
+
+// rankByRecency orders by file modification time, newest first. Symbols whose
+// file cannot be stat'ed sort after all others.
+func rankByRecency(symbols []*ScoredSymbol, projectRoot string) {
+	mtimes := make(map[string]time.Time)
+	for _, s := range symbols {
+		if s.Symbol == nil {
+			continue
+		}
+		path := s.Symbol.FilePath
+		if _, seen := mtimes[path]; seen {
+			continue
+		}
+		mtimes[path] = fileModTime(projectRoot, path)
+	}
+
+	sort.SliceStable(symbols, func(i, j int) bool {
+		ti, tj := symbolModTime(symbols[i], mtimes), symbolModTime(symbols[j], mtimes)
+		if !ti.Equal(tj) {
+			return ti.After(tj)
+		}
+		return lessByScore(symbols[i], symbols[j])
+	})
+}
+
+// lessByScore orders by Score descending, then ID for determinism.
+func lessByScore(a, b *ScoredSymbol) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	return scoredSymbolID(a) < scoredSymbolID(b)
+}
+
+func scoredSymbolID(s *ScoredSymbol) string {
+	if s.Symbol == nil {
+		return ""
+	}
+	return s.Symbol.ID
+}
+
+func symbolModTime(s *ScoredSymbol, mtimes map[string]time.Time) time.Time {
+	if s.Symbol == nil {
+		return time.Time{}
+	}
+	return mtimes[s.Symbol.FilePath]
+}
+
+// fileModTime returns the modification time of path, or the zero time if it
+// cannot be determined.
+func fileModTime(projectRoot, path string) time.Time {
+	if path == "" {
+		return time.Time{}
+	}
+	if !filepath.IsAbs(path) && projectRoot != "" {
+		path = filepath.Join(projectRoot, path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
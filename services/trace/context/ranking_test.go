@@ -0,0 +1,123 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package context
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+func scored(id, file string, score float64, depth int) *ScoredSymbol {
+	return &ScoredSymbol{
+		Symbol: &ast.Symbol{ID: id, Name: id, FilePath: file, Kind: ast.SymbolKindFunction},
+		Score:  score,
+		Depth:  depth,
+	}
+}
+
+func rankedIDs(symbols []*ScoredSymbol) []string {
+	ids := make([]string, len(symbols))
+	for i, s := range symbols {
+		ids[i] = s.Symbol.ID
+	}
+	return ids
+}
+
+func assertOrder(t *testing.T, got []*ScoredSymbol, want ...string) {
+	t.Helper()
+	ids := rankedIDs(got)
+	for i := range want {
+		if i >= len(ids) || ids[i] != want[i] {
+			t.Fatalf("order = %v, want %v", ids, want)
+		}
+	}
+}
+
+func TestRankSymbols_Relevance(t *testing.T) {
+	symbols := []*ScoredSymbol{
+		scored("low", "a.go", 0.2, 0),
+		scored("high", "a.go", 0.9, 2),
+		scored("mid", "a.go", 0.5, 1),
+	}
+	rankSymbols(RankingRelevance, symbols, "")
+	assertOrder(t, symbols, "high", "mid", "low")
+}
+
+func TestRankSymbols_GraphProximity(t *testing.T) {
+	symbols := []*ScoredSymbol{
+		scored("far", "a.go", 0.9, 2),
+		scored("near-low", "a.go", 0.3, 1),
+		scored("entry", "a.go", 0.1, 0),
+		scored("near-high", "a.go", 0.8, 1),
+	}
+	rankSymbols(RankingGraphProximity, symbols, "")
+	assertOrder(t, symbols, "entry", "near-high", "near-low", "far")
+}
+
+func TestRankSymbols_Recency(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	for file, age := range map[string]time.Duration{
+		"old.go": 48 * time.Hour,
+		"new.go": time.Minute,
+		"mid.go": time.Hour,
+	} {
+		path := filepath.Join(root, file)
+		if err := os.WriteFile(path, []byte("package x\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := now.Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	symbols := []*ScoredSymbol{
+		scored("old", "old.go", 0.9, 0),
+		scored("missing", "gone.go", 1.0, 0),
+		scored("mid", "mid.go", 0.5, 0),
+		scored("new", "new.go", 0.1, 0),
+	}
+	rankSymbols(RankingRecency, symbols, root)
+	assertOrder(t, symbols, "new", "mid", "old", "missing")
+}
+
+func TestRankSymbols_UnknownFallsBackToRelevance(t *testing.T) {
+	symbols := []*ScoredSymbol{
+		scored("low", "a.go", 0.2, 0),
+		scored("high", "a.go", 0.9, 2),
+	}
+	rankSymbols("bogus", symbols, "")
+	assertOrder(t, symbols, "high", "low")
+}
+
+func TestRankingStrategyNames(t *testing.T) {
+	names := RankingStrategyNames()
+	want := []string{RankingGraphProximity, RankingRecency, RankingRelevance}
+	if len(names) != len(want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("names = %v, want %v", names, want)
+		}
+		if !IsValidRankingStrategy(names[i]) {
+			t.Errorf("%q should be valid", names[i])
+		}
+	}
+	if IsValidRankingStrategy("newest") {
+		t.Error("unknown name must be invalid")
+	}
+}
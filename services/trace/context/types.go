@@ -195,6 +195,42 @@ type ContextResult struct {
 
 	// Truncated indicates if results were limited by budget or timeout.
 	Truncated bool `json:"truncated"`
+
+	// Budget is the effective budget the context was assembled under.
+	Budget ContextBudget `json:"budget"`
+
+	// Included describes each code symbol packed into Context, in order.
+	Included []BudgetEntry `json:"included"`
+
+	// Dropped describes ranked code symbols left out, with the reason.
+	Dropped []BudgetEntry `json:"dropped"`
+}
+
+// BudgetEntry records how a candidate symbol fared against the budget.
+type BudgetEntry struct {
+	// SymbolID is the symbol's ID.
+	SymbolID string `json:"symbol_id"`
+
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// FilePath is the file the symbol is defined in.
+	FilePath string `json:"file_path"`
+
+	// Tokens is the estimated token cost (after truncation, if any).
+	Tokens int `json:"tokens"`
+
+	// Score is the relevance score.
+	Score float64 `json:"score"`
+
+	// Depth is the graph distance from the query's entry points.
+	Depth int `json:"depth"`
+
+	// Truncated is true if the symbol was cut to MaxSymbolTokens.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Reason explains why a symbol was dropped (DropReason* constants).
+	Reason string `json:"reason,omitempty"`
 }
 
 // LibraryDoc represents documentation for an external library symbol.
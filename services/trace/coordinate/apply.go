@@ -0,0 +1,584 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package coordinate
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
+)
+
+// ApplyOptions configures ApplyPlan.
+type ApplyOptions struct {
+	// DryRun computes and reports every edit without writing any file.
+	DryRun bool
+}
+
+// ConflictReason explains why a plan change cannot be applied.
+type ConflictReason string
+
+const (
+	// ConflictFileMissing means the change's file no longer exists.
+	ConflictFileMissing ConflictReason = "file_missing"
+
+	// ConflictOutOfRange means the file has fewer lines than the change's
+	// region.
+	ConflictOutOfRange ConflictReason = "region_out_of_range"
+
+	// ConflictNoBaseline means no content hash was captured at plan time,
+	// so staleness cannot be ruled out.
+	ConflictNoBaseline ConflictReason = "no_content_hash"
+
+	// ConflictContentChanged means the region no longer matches the hash
+	// captured at plan time.
+	ConflictContentChanged ConflictReason = "content_changed"
+
+	// ConflictTextNotFound means the change's current code or old token
+	// does not occur in its region.
+	ConflictTextNotFound ConflictReason = "current_code_not_found"
+
+	// ConflictTextAmbiguous means the change's current code occurs more
+	// than once in its region.
+	ConflictTextAmbiguous ConflictReason = "current_code_ambiguous"
+
+	// ConflictOverlap means the change overlaps another change of the plan.
+	ConflictOverlap ConflictReason = "overlapping_edit"
+)
+
+// StaleHunk is one plan change that cannot be applied.
+type StaleHunk struct {
+	// FilePath, StartLine, EndLine and SymbolID identify the change.
+	FilePath  string `json:"file_path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	SymbolID  string `json:"symbol_id,omitempty"`
+
+	// Reason is why the change cannot be applied.
+	Reason ConflictReason `json:"reason"`
+
+	// ExpectedHash is the region hash captured at plan time.
+	ExpectedHash string `json:"expected_hash,omitempty"`
+
+	// ActualHash is the region hash now, set for content_changed.
+	ActualHash string `json:"actual_hash,omitempty"`
+}
+
+// ConflictError lists the plan changes that cannot be applied. It wraps
+// ErrPlanConflict; nothing was written when it is returned.
+type ConflictError struct {
+	Hunks []StaleHunk
+}
+
+// Error implements error.
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%v: %d stale hunk(s)", ErrPlanConflict, len(e.Hunks))
+}
+
+// Unwrap returns ErrPlanConflict.
+func (e *ConflictError) Unwrap() error {
+	return ErrPlanConflict
+}
+
+// AppliedFile is the outcome of applying a plan to one file.
+type AppliedFile struct {
+	// FilePath is the plan's path of the file.
+	FilePath string `json:"file_path"`
+
+	// Edits is the number of plan changes applied to the file.
+	Edits int `json:"edits"`
+
+	// Hunks are the whole lines each group of adjacent edits replaces,
+	// exactly as written.
+	Hunks []Hunk `json:"hunks"`
+
+	// LinesRemoved and LinesAdded count the lines of Hunks.
+	LinesRemoved int `json:"lines_removed"`
+	LinesAdded   int `json:"lines_added"`
+
+	// BeforeHash and AfterHash are hex SHA-256 digests of the whole file
+	// before and after the edits.
+	BeforeHash string `json:"before_hash"`
+	AfterHash  string `json:"after_hash"`
+}
+
+// ApplyResult is the outcome of ApplyPlan.
+type ApplyResult struct {
+	// PlanID is the applied plan.
+	PlanID string `json:"plan_id"`
+
+	// DryRun is true when nothing was written.
+	DryRun bool `json:"dry_run"`
+
+	// Files lists the changed files in plan order.
+	Files []AppliedFile `json:"files"`
+
+	// TotalEdits is the number of plan changes applied.
+	TotalEdits int `json:"total_edits"`
+}
+
+// RegionHash returns the hex SHA-256 of lines start..end (1-indexed,
+// inclusive). A trailing "\r" on each line is ignored, so the hash does
+// not depend on line endings. The range must lie within lines.
+//
+// Thread Safety: Safe for concurrent use.
+func RegionHash(lines []string, start, end int) string {
+	h := sha256.New()
+	for i := start; i <= end; i++ {
+		h.Write([]byte(strings.TrimSuffix(lines[i-1], "\r")))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// validRegion reports whether start..end is a non-empty line range of lines.
+func validRegion(lines []string, start, end int) bool {
+	return start >= 1 && end >= start && end <= len(lines)
+}
+
+// readProjectFile reads a project file, refusing paths that resolve
+// outside the project root (including through symlinks).
+func readProjectFile(root, filePath string) ([]byte, error) {
+	abs, err := validation.ResolveWithin(root, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(abs)
+}
+
+// hashRegions sets ContentHash on every change whose file is readable
+// below the project root and whose line range exists.
+func (c *MultiFileChangeCoordinator) hashRegions(plan *ChangePlan) {
+	files := make(map[string][]string)
+	for i := range plan.FileChanges {
+		fc := &plan.FileChanges[i]
+		lines, ok := files[fc.FilePath]
+		if !ok {
+			if content, err := readProjectFile(c.graph.ProjectRoot, fc.FilePath); err == nil {
+				lines = strings.Split(string(content), "\n")
+			}
+			files[fc.FilePath] = lines
+		}
+		if validRegion(lines, fc.StartLine, fc.EndLine) {
+			fc.ContentHash = RegionHash(lines, fc.StartLine, fc.EndLine)
+		}
+	}
+}
+
+// ApplyPlan writes a plan's changes to the files below root.
+//
+// # Description
+//
+// Every change is checked before anything is written: its region must
+// still hash to the ContentHash captured at plan time, and its current
+// code (or, for single-token edits, OldText at Column) must occur exactly
+// once in the region. Changes that fail are returned together in a
+// *ConflictError. Changes are otherwise replaced in place, so the rest of
+// each file is untouched.
+//
+// Each file is replaced atomically through a temporary file and rename.
+// The original contents are kept in memory; if a write fails, or a file
+// changes between the check and its write, every file already written is
+// restored and the error is returned.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - root: The project root. Plan paths are resolved against it.
+//   - plan: The plan to apply.
+//   - opts: DryRun reports the edits without writing.
+//
+// # Outputs
+//
+//   - *ApplyResult: The applied (or, for a dry run, planned) edits.
+//   - error: ErrInvalidInput for a nil plan, ErrOutsideRoot if a plan path
+//     escapes root, a *ConflictError for stale changes, or the I/O error.
+//
+// # Thread Safety
+//
+// Safe for concurrent use, but concurrent applies to the same files race;
+// callers serialize them.
+func ApplyPlan(ctx context.Context, root string, plan *ChangePlan, opts ApplyOptions) (*ApplyResult, error) {
+	if ctx == nil || plan == nil {
+		return nil, ErrInvalidInput
+	}
+
+	byFile := make(map[string][]FileChange)
+	for _, fc := range plan.FileChanges {
+		byFile[fc.FilePath] = append(byFile[fc.FilePath], fc)
+	}
+	order := make([]string, 0, len(byFile))
+	seen := make(map[string]bool, len(byFile))
+	for _, path := range plan.Order {
+		if _, ok := byFile[path]; ok && !seen[path] {
+			order = append(order, path)
+			seen[path] = true
+		}
+	}
+	var rest []string
+	for path := range byFile {
+		if !seen[path] {
+			rest = append(rest, path)
+		}
+	}
+	sort.Strings(rest)
+	order = append(order, rest...)
+
+	var stale []StaleHunk
+	pending := make([]*fileEdit, 0, len(order))
+	for _, path := range order {
+		if err := ctx.Err(); err != nil {
+			return nil, ErrContextCanceled
+		}
+		abs, err := validation.ResolveWithin(root, path)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrOutsideRoot, path)
+		}
+		fe, hunks, err := prepareFile(abs, path, byFile[path])
+		if err != nil {
+			return nil, err
+		}
+		stale = append(stale, hunks...)
+		if fe != nil {
+			pending = append(pending, fe)
+		}
+	}
+	if len(stale) > 0 {
+		return nil, &ConflictError{Hunks: stale}
+	}
+
+	result := &ApplyResult{PlanID: plan.ID, DryRun: opts.DryRun, Files: make([]AppliedFile, 0, len(pending))}
+	for _, fe := range pending {
+		result.Files = append(result.Files, fe.applied)
+		result.TotalEdits += fe.applied.Edits
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+	if err := commitFiles(pending); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// fileEdit is the checked edit of one file.
+type fileEdit struct {
+	abs     string
+	mode    fs.FileMode
+	changes []FileChange
+	before  []byte
+	after   []byte
+	applied AppliedFile
+}
+
+// textEdit replaces content[start:end] with text.
+type textEdit struct {
+	start, end int
+	text       string
+	change     int
+}
+
+// sourceText is a file split into lines, with the byte offset of each.
+type sourceText struct {
+	content string
+	lines   []string
+	offsets []int
+}
+
+func newSourceText(content []byte) *sourceText {
+	s := &sourceText{content: string(content)}
+	s.lines = strings.Split(s.content, "\n")
+	s.offsets = make([]int, len(s.lines))
+	off := 0
+	for i, line := range s.lines {
+		s.offsets[i] = off
+		off += len(line) + 1
+	}
+	return s
+}
+
+// lineEnd returns the offset just past the text of 0-indexed line i,
+// before its newline.
+func (s *sourceText) lineEnd(i int) int {
+	return s.offsets[i] + len(s.lines[i])
+}
+
+// lineOf returns the 0-indexed line containing offset.
+func (s *sourceText) lineOf(offset int) int {
+	return sort.Search(len(s.offsets), func(i int) bool { return s.offsets[i] > offset }) - 1
+}
+
+// prepareFile checks a file's changes and computes its new content.
+// Returns the stale changes instead when any change cannot be applied.
+func prepareFile(abs, path string, changes []FileChange) (*fileEdit, []StaleHunk, error) {
+	staleAll := func(reason ConflictReason) []StaleHunk {
+		hunks := make([]StaleHunk, len(changes))
+		for i, fc := range changes {
+			hunks[i] = staleHunk(fc, reason)
+		}
+		return hunks
+	}
+
+	info, err := os.Stat(abs)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, staleAll(ConflictFileMissing), nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("stat %s: %w", path, err)
+	}
+	content, err := os.ReadFile(abs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read %s: %w", path, err)
+	}
+
+	src := newSourceText(content)
+	var stale []StaleHunk
+	edits := make([]textEdit, 0, len(changes))
+	for i, fc := range changes {
+		switch {
+		case !validRegion(src.lines, fc.StartLine, fc.EndLine):
+			stale = append(stale, staleHunk(fc, ConflictOutOfRange))
+			continue
+		case fc.ContentHash == "":
+			stale = append(stale, staleHunk(fc, ConflictNoBaseline))
+			continue
+		}
+		if actual := RegionHash(src.lines, fc.StartLine, fc.EndLine); actual != fc.ContentHash {
+			hunk := staleHunk(fc, ConflictContentChanged)
+			hunk.ActualHash = actual
+			stale = append(stale, hunk)
+			continue
+		}
+		edit, reason := src.locate(fc)
+		if reason != "" {
+			stale = append(stale, staleHunk(fc, reason))
+			continue
+		}
+		edit.change = i
+		edits = append(edits, edit)
+	}
+	if len(stale) > 0 {
+		return nil, stale, nil
+	}
+
+	sort.SliceStable(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start < edits[j].start
+		}
+		return edits[i].end < edits[j].end
+	})
+	kept := edits[:0]
+	for _, e := range edits {
+		if n := len(kept); n > 0 {
+			prev := kept[n-1]
+			if e.start == prev.start && e.end == prev.end && e.text == prev.text {
+				continue
+			}
+			if e.start < prev.end {
+				stale = append(stale, staleHunk(changes[e.change], ConflictOverlap))
+				continue
+			}
+		}
+		kept = append(kept, e)
+	}
+	if len(stale) > 0 {
+		return nil, stale, nil
+	}
+
+	after := src.apply(kept, 0, len(src.content))
+	fe := &fileEdit{
+		abs:     abs,
+		mode:    info.Mode().Perm(),
+		changes: changes,
+		before:  content,
+		after:   []byte(after),
+		applied: AppliedFile{
+			FilePath:   path,
+			Edits:      len(changes),
+			Hunks:      src.hunks(kept),
+			BeforeHash: hashBytes(content),
+			AfterHash:  hashBytes([]byte(after)),
+		},
+	}
+	for _, h := range fe.applied.Hunks {
+		fe.applied.LinesRemoved += len(h.OldLines)
+		fe.applied.LinesAdded += len(h.NewLines)
+	}
+	return fe, nil, nil
+}
+
+// locate finds the bytes a change replaces. The change's region must
+// already be valid. Returns a reason when the change cannot be placed.
+func (s *sourceText) locate(fc FileChange) (textEdit, ConflictReason) {
+	if fc.Column > 0 && fc.OldText != "" {
+		line := s.lines[fc.StartLine-1]
+		at := fc.Column - 1
+		if at+len(fc.OldText) > len(line) || line[at:at+len(fc.OldText)] != fc.OldText {
+			return textEdit{}, ConflictTextNotFound
+		}
+		off := s.offsets[fc.StartLine-1] + at
+		return textEdit{start: off, end: off + len(fc.OldText), text: fc.NewText}, ""
+	}
+
+	start := s.offsets[fc.StartLine-1]
+	region := strings.TrimSuffix(s.content[start:s.lineEnd(fc.EndLine-1)], "\r")
+	if fc.CurrentCode == "" {
+		return textEdit{}, ConflictTextNotFound
+	}
+	switch strings.Count(region, fc.CurrentCode) {
+	case 0:
+		return textEdit{}, ConflictTextNotFound
+	case 1:
+	default:
+		return textEdit{}, ConflictTextAmbiguous
+	}
+	off := start + strings.Index(region, fc.CurrentCode)
+	return textEdit{start: off, end: off + len(fc.CurrentCode), text: fc.ProposedCode}, ""
+}
+
+// apply returns content[from:to] with the sorted, non-overlapping edits
+// that fall inside it applied.
+func (s *sourceText) apply(edits []textEdit, from, to int) string {
+	var sb strings.Builder
+	pos := from
+	for _, e := range edits {
+		if e.start < from || e.end > to {
+			continue
+		}
+		sb.WriteString(s.content[pos:e.start])
+		sb.WriteString(e.text)
+		pos = e.end
+	}
+	sb.WriteString(s.content[pos:to])
+	return sb.String()
+}
+
+// hunks groups edits that share lines and returns, per group, the whole
+// lines before and after.
+func (s *sourceText) hunks(edits []textEdit) []Hunk {
+	hunks := make([]Hunk, 0, len(edits))
+	for i := 0; i < len(edits); {
+		first := s.lineOf(edits[i].start)
+		last := s.lineOf(edits[i].end)
+		j := i + 1
+		for ; j < len(edits) && s.lineOf(edits[j].start) <= last; j++ {
+			if l := s.lineOf(edits[j].end); l > last {
+				last = l
+			}
+		}
+		from, to := s.offsets[first], s.lineEnd(last)
+		hunks = append(hunks, Hunk{
+			StartLine: first + 1,
+			OldLines:  strings.Split(s.content[from:to], "\n"),
+			NewLines:  strings.Split(s.apply(edits[i:j], from, to), "\n"),
+		})
+		i = j
+	}
+	return hunks
+}
+
+// commitFiles writes each file atomically, in order. If a file changed
+// since it was checked, or a write fails, the files already written are
+// restored from their original contents.
+func commitFiles(pending []*fileEdit) error {
+	written := make([]*fileEdit, 0, len(pending))
+	fail := func(err error) error {
+		if rbErr := rollbackFiles(written); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	for _, fe := range pending {
+		current, err := os.ReadFile(fe.abs)
+		if err != nil {
+			return fail(fmt.Errorf("read %s: %w", fe.applied.FilePath, err))
+		}
+		if !bytes.Equal(current, fe.before) {
+			hunks := make([]StaleHunk, len(fe.changes))
+			for i, fc := range fe.changes {
+				hunks[i] = staleHunk(fc, ConflictContentChanged)
+			}
+			return fail(&ConflictError{Hunks: hunks})
+		}
+		if err := writeFileAtomic(fe.abs, fe.after, fe.mode); err != nil {
+			return fail(fmt.Errorf("write %s: %w", fe.applied.FilePath, err))
+		}
+		written = append(written, fe)
+	}
+	return nil
+}
+
+// rollbackFiles restores the original contents of written files, newest
+// first. Returns the first error; it keeps restoring the rest.
+func rollbackFiles(written []*fileEdit) error {
+	var first error
+	for i := len(written) - 1; i >= 0; i-- {
+		fe := written[i]
+		if err := writeFileAtomic(fe.abs, fe.before, fe.mode); err != nil && first == nil {
+			first = fmt.Errorf("restore %s: %w", fe.applied.FilePath, err)
+		}
+	}
+	return first
+}
+
+// writeFileAtomic replaces path with data through a temporary file in the
+// same directory, so readers see either the old or the new content.
+func writeFileAtomic(path string, data []byte, mode fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".apply-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, mode); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
+
+// staleHunk describes fc as a stale hunk.
+func staleHunk(fc FileChange, reason ConflictReason) StaleHunk {
+	return StaleHunk{
+		FilePath:     fc.FilePath,
+		StartLine:    fc.StartLine,
+		EndLine:      fc.EndLine,
+		SymbolID:     fc.SymbolID,
+		Reason:       reason,
+		ExpectedHash: fc.ContentHash,
+	}
+}
+
+// hashBytes returns the hex SHA-256 of data.
+func hashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
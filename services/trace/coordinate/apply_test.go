@@ -0,0 +1,183 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package coordinate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readFile(t *testing.T, root, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestApplyPlan_Rename(t *testing.T) {
+	c := createRenameProject(t)
+	root := c.graph.ProjectRoot
+	ctx := context.Background()
+
+	plan, err := c.PreviewRename(ctx, "pkg/store.go:4:Save", "Write", nil)
+	if err != nil {
+		t.Fatalf("PreviewRename: %v", err)
+	}
+	for _, fc := range plan.FileChanges {
+		if fc.ContentHash == "" {
+			t.Fatalf("change %s:%d has no content hash", fc.FilePath, fc.StartLine)
+		}
+	}
+	before := readFile(t, root, "pkg/use.go")
+
+	dry, err := ApplyPlan(ctx, root, plan, ApplyOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	if !dry.DryRun || dry.TotalEdits != len(plan.FileChanges) || len(dry.Files) != plan.TotalFiles {
+		t.Errorf("dry run result = %+v", dry)
+	}
+	if dry.Files[0].FilePath != "pkg/store.go" {
+		t.Errorf("first file = %s, want plan order", dry.Files[0].FilePath)
+	}
+	if got := readFile(t, root, "pkg/use.go"); got != before {
+		t.Error("dry run wrote a file")
+	}
+	var useHunks []Hunk
+	for _, f := range dry.Files {
+		if f.FilePath == "pkg/use.go" {
+			useHunks = f.Hunks
+		}
+	}
+	if len(useHunks) != 2 || useHunks[0].StartLine != 4 || useHunks[0].NewLines[0] != "\ts.Write(nil)" {
+		t.Errorf("use.go hunks = %+v", useHunks)
+	}
+
+	applied, err := ApplyPlan(ctx, root, plan, ApplyOptions{})
+	if err != nil {
+		t.Fatalf("apply: %v", err)
+	}
+	if applied.TotalEdits != dry.TotalEdits {
+		t.Errorf("applied %d edits, dry run reported %d", applied.TotalEdits, dry.TotalEdits)
+	}
+	if got := readFile(t, root, "pkg/store.go"); got != "package pkg\n\ntype Store interface {\n\tWrite(data []byte) error\n}\n" {
+		t.Errorf("store.go = %q", got)
+	}
+	if got := readFile(t, root, "pkg/use.go"); !strings.Contains(got, "\tf.Write(nil)\n") || !strings.Contains(got, `call("Save")`) {
+		t.Errorf("use.go = %q", got)
+	}
+
+	// The plan's regions changed, so a second apply is a conflict.
+	_, err = ApplyPlan(ctx, root, plan, ApplyOptions{})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrPlanConflict) {
+		t.Fatalf("reapply err = %v, want ConflictError", err)
+	}
+	if len(conflict.Hunks) != len(plan.FileChanges) || conflict.Hunks[0].Reason != ConflictContentChanged || conflict.Hunks[0].ActualHash == "" {
+		t.Errorf("conflict hunks = %+v", conflict.Hunks)
+	}
+}
+
+func TestApplyPlan_StaleHunksWriteNothing(t *testing.T) {
+	c := createRenameProject(t)
+	root := c.graph.ProjectRoot
+	ctx := context.Background()
+
+	plan, err := c.PreviewRename(ctx, "pkg/store.go:4:Save", "Write", nil)
+	if err != nil {
+		t.Fatalf("PreviewRename: %v", err)
+	}
+	edited := "package pkg\n\nfunc Use(s Store, f *FileStore, d any) {\n\ts.Save(ctx)"
+	if err := os.WriteFile(filepath.Join(root, "pkg/use.go"), []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(root, "py/store.py")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ApplyPlan(ctx, root, plan, ApplyOptions{})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("err = %v, want ConflictError", err)
+	}
+	reasons := make(map[string]ConflictReason)
+	for _, h := range conflict.Hunks {
+		reasons[fmt.Sprintf("%s:%d", h.FilePath, h.StartLine)] = h.Reason
+	}
+	want := map[string]ConflictReason{
+		"pkg/use.go:4":  ConflictContentChanged,
+		"pkg/use.go:5":  ConflictOutOfRange,
+		"py/store.py:2": ConflictFileMissing,
+	}
+	if len(reasons) != len(want) {
+		t.Errorf("stale hunks = %+v", conflict.Hunks)
+	}
+	for k, r := range want {
+		if reasons[k] != r {
+			t.Errorf("%s reason = %q, want %q", k, reasons[k], r)
+		}
+	}
+	if got := readFile(t, root, "pkg/store.go"); strings.Contains(got, "Write") {
+		t.Error("a conflicting plan changed an unaffected file")
+	}
+}
+
+func TestApplyPlan_Refusals(t *testing.T) {
+	root := t.TempDir()
+	ctx := context.Background()
+	if err := os.WriteFile(filepath.Join(root, "a.go"), []byte("package a\n\nfunc A() {}\nfunc A() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(readFile(t, root, "a.go"), "\n")
+
+	outside := &ChangePlan{ID: "p", FileChanges: []FileChange{{FilePath: "../a.go", StartLine: 1, EndLine: 1, CurrentCode: "package", ProposedCode: "x"}}}
+	if _, err := ApplyPlan(ctx, root, outside, ApplyOptions{}); !errors.Is(err, ErrOutsideRoot) {
+		t.Errorf("outside root err = %v, want ErrOutsideRoot", err)
+	}
+
+	tests := []struct {
+		name   string
+		change FileChange
+		want   ConflictReason
+	}{
+		{"no hash", FileChange{StartLine: 1, EndLine: 1, CurrentCode: "package a"}, ConflictNoBaseline},
+		{"placeholder code", FileChange{StartLine: 3, EndLine: 3, CurrentCode: "/* call to A */", ContentHash: RegionHash(lines, 3, 3)}, ConflictTextNotFound},
+		{"ambiguous code", FileChange{StartLine: 3, EndLine: 4, CurrentCode: "func A()", ContentHash: RegionHash(lines, 3, 4)}, ConflictTextAmbiguous},
+		{"wrong token", FileChange{StartLine: 3, EndLine: 3, Column: 6, OldText: "B", ContentHash: RegionHash(lines, 3, 3)}, ConflictTextNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change.FilePath = "a.go"
+			_, err := ApplyPlan(ctx, root, &ChangePlan{ID: "p", FileChanges: []FileChange{tt.change}}, ApplyOptions{})
+			var conflict *ConflictError
+			if !errors.As(err, &conflict) || len(conflict.Hunks) != 1 || conflict.Hunks[0].Reason != tt.want {
+				t.Errorf("err = %v, want one %s hunk", err, tt.want)
+			}
+		})
+	}
+
+	overlap := &ChangePlan{ID: "p", FileChanges: []FileChange{
+		{FilePath: "a.go", StartLine: 1, EndLine: 1, CurrentCode: "package a", ProposedCode: "package b", ContentHash: RegionHash(lines, 1, 1)},
+		{FilePath: "a.go", StartLine: 1, EndLine: 1, Column: 9, OldText: "a", NewText: "c", ContentHash: RegionHash(lines, 1, 1)},
+	}}
+	_, err := ApplyPlan(ctx, root, overlap, ApplyOptions{})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Hunks) != 1 || conflict.Hunks[0].Reason != ConflictOverlap {
+		t.Errorf("overlap err = %v", err)
+	}
+}
@@ -31,8 +31,8 @@ import (
 //
 // MultiFileChangeCoordinator handles multi-file change coordination for the agent.
 // It analyzes a proposed change to one symbol and generates a complete plan for
-// all files that need to be updated together. This is READ-ONLY analysis - actual
-// file editing is done by the agent using Edit tools after user approval.
+// all files that need to be updated together. Planning is read-only; files are
+// only written by ApplyPlan once a plan has been approved.
 //
 // # Thread Safety
 //
@@ -227,6 +227,7 @@ func (c *MultiFileChangeCoordinator) PlanChanges(
 	// Set totals
 	plan.TotalFiles = countUniqueFiles(plan.FileChanges)
 	plan.TotalChanges = len(plan.FileChanges)
+	c.hashRegions(plan)
 
 	// Store plan for later retrieval
 	c.mu.Lock()
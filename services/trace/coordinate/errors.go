@@ -34,4 +34,12 @@ var (
 
 	// ErrValidationFailed indicates the change plan failed validation.
 	ErrValidationFailed = errors.New("validation failed")
+
+	// ErrPlanConflict indicates plan changes that no longer match the
+	// source. Returned wrapped in a *ConflictError.
+	ErrPlanConflict = errors.New("plan conflicts with current source")
+
+	// ErrOutsideRoot indicates a plan file that resolves outside the
+	// project root.
+	ErrOutsideRoot = errors.New("path outside project root")
 )
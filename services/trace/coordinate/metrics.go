@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -72,7 +73,7 @@ func startPlanSpan(ctx context.Context, targetID string) (context.Context, trace
 	return tracer.Start(ctx, "coordinate.MultiFileChangeCoordinator.PlanChanges",
 		trace.WithAttributes(
 			attribute.String("coordinate.operation", "plan_changes"),
-			attribute.String("coordinate.target_id", targetID),
+			telemetry.SensitiveString("coordinate.target_id", targetID),
 		),
 	)
 }
@@ -139,3 +140,42 @@ func recordValidatePlanMetrics(ctx context.Context, duration time.Duration, err
 	coordinateLatency.Record(ctx, duration.Seconds(), attrs)
 	coordinateTotal.Add(ctx, 1, attrs)
 }
+
+// ============================================================================
+// PreviewRename OTel
+// ============================================================================
+
+// startPreviewRenameSpan creates a span for rename previews.
+func startPreviewRenameSpan(ctx context.Context, targetID string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "coordinate.MultiFileChangeCoordinator.PreviewRename",
+		trace.WithAttributes(
+			attribute.String("coordinate.operation", "preview_rename"),
+			telemetry.SensitiveString("coordinate.target_id", targetID),
+		),
+	)
+}
+
+// setPreviewRenameSpanResult sets result attributes on a rename preview span.
+func setPreviewRenameSpanResult(span trace.Span, editCount, riskCount int, err error) {
+	span.SetAttributes(
+		attribute.Int("coordinate.edit_count", editCount),
+		attribute.Int("coordinate.risk_count", riskCount),
+		attribute.Bool("coordinate.success", err == nil),
+	)
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+// recordPreviewRenameMetrics records metrics for rename previews.
+func recordPreviewRenameMetrics(ctx context.Context, duration time.Duration, err error) {
+	if initErr := initMetrics(); initErr != nil {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("operation", "preview_rename"),
+		attribute.Bool("success", err == nil),
+	)
+	coordinateLatency.Record(ctx, duration.Seconds(), attrs)
+	coordinateTotal.Add(ctx, 1, attrs)
+}
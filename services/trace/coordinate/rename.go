@@ -0,0 +1,664 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package coordinate
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// identifierPattern matches names accepted as rename targets in every
+// supported language.
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// PreviewRename plans a graph-aware rename without applying it.
+//
+// # Description
+//
+// Finds every place the target's name must change: the declaration,
+// inbound CALLS, REFERENCES and IMPLEMENTS edges, import statements that
+// name the symbol and, for interface methods, the same-named methods of
+// implementing types together with their callers. Each occurrence becomes
+// one FileChange with its line, column, old text and new text. The source
+// line before and after the edit is kept in CurrentCode and ProposedCode
+// so the plan flows through ValidatePlan and PreviewChanges unchanged.
+//
+// Hazards are reported in ChangePlan.Risks: declarations of the new name
+// in the target scope, implementers in another language than the
+// interface, and dynamic or string references that the graph cannot tie
+// to the target. Risks raise the plan's RiskLevel and lower Confidence.
+//
+// Source lines are read from the graph's project root. Occurrences whose
+// file cannot be read are listed in Limitations.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - targetID: Symbol ID to rename.
+//   - newName: The new identifier.
+//   - opts: Optional configuration. IncludeTests and MaxCallers apply.
+//
+// # Outputs
+//
+//   - *ChangePlan: The stored rename plan.
+//   - error: ErrInvalidInput for an empty target or an invalid or unchanged
+//     name, ErrSymbolNotFound if the target is unknown.
+//
+// # Example
+//
+//	plan, err := coordinator.PreviewRename(ctx, "pkg/service.go:10:ProcessOrder", "HandleOrder", nil)
+//	for _, fc := range plan.FileChanges {
+//	    fmt.Printf("%s:%d:%d %s -> %s\n", fc.FilePath, fc.StartLine, fc.Column, fc.OldText, fc.NewText)
+//	}
+func (c *MultiFileChangeCoordinator) PreviewRename(
+	ctx context.Context,
+	targetID string,
+	newName string,
+	opts *PlanOptions,
+) (*ChangePlan, error) {
+	if ctx == nil {
+		return nil, ErrInvalidInput
+	}
+
+	start := time.Now()
+	ctx, span := startPreviewRenameSpan(ctx, targetID)
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrContextCanceled
+	}
+	if targetID == "" {
+		return nil, fmt.Errorf("%w: target ID is empty", ErrInvalidInput)
+	}
+	if !identifierPattern.MatchString(newName) {
+		return nil, fmt.Errorf("%w: %q is not a valid identifier", ErrInvalidInput, newName)
+	}
+
+	options := DefaultPlanOptions()
+	if opts != nil {
+		options = *opts
+	}
+
+	symbol, found := c.index.GetByID(targetID)
+	if !found {
+		return nil, ErrSymbolNotFound
+	}
+	if symbol.Name == newName {
+		return nil, fmt.Errorf("%w: new name equals the current name", ErrInvalidInput)
+	}
+
+	plan := &ChangePlan{
+		ID:          fmt.Sprintf("plan_%d", time.Now().UnixNano()),
+		Description: fmt.Sprintf("Rename %s to %s", symbol.Name, newName),
+		PrimaryChange: ChangeRequest{
+			TargetID:   targetID,
+			ChangeType: ChangeRenameSymbol,
+			NewName:    newName,
+		},
+		FileChanges: make([]FileChange, 0),
+		Order:       make([]string, 0),
+		Warnings:    make([]string, 0),
+		Limitations: make([]string, 0),
+		CreatedAt:   time.Now().UnixMilli(),
+	}
+
+	r := &renamePlanner{
+		c:       c,
+		plan:    plan,
+		options: options,
+		oldName: symbol.Name,
+		newName: newName,
+		files:   make(map[string][]string),
+		seen:    make(map[string]bool),
+		renamed: make(map[string]bool),
+	}
+	if err := r.run(ctx, symbol); err != nil {
+		setPreviewRenameSpanResult(span, 0, 0, err)
+		recordPreviewRenameMetrics(ctx, time.Since(start), err)
+		return nil, err
+	}
+
+	sortRenameChanges(plan.FileChanges)
+	sort.SliceStable(plan.Risks, func(i, j int) bool {
+		a, b := plan.Risks[i], plan.Risks[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		return a.Line < b.Line
+	})
+	plan.Order = c.buildChangeOrder(plan.FileChanges)
+	plan.TotalFiles = countUniqueFiles(plan.FileChanges)
+	plan.TotalChanges = len(plan.FileChanges)
+	plan.RiskLevel = escalateRenameRisk(c.calculateRiskLevel(plan, nil), plan.Risks)
+	plan.Confidence = c.calculateConfidence(plan, nil) - float64(len(plan.Risks))*0.05
+	if plan.Confidence < 0.3 {
+		plan.Confidence = 0.3
+	}
+	c.hashRegions(plan)
+
+	c.mu.Lock()
+	c.plans[plan.ID] = plan
+	c.mu.Unlock()
+
+	dur := time.Since(start)
+	setPreviewRenameSpanResult(span, plan.TotalChanges, len(plan.Risks), nil)
+	recordPreviewRenameMetrics(ctx, dur, nil)
+	c.crs.RecordToolStep(ctx, "preview_rename", plan.TotalChanges, dur, nil)
+
+	return plan, nil
+}
+
+// renamePlanner holds the state of one PreviewRename call.
+//
+// Thread Safety: Not safe for concurrent use; owned by one call.
+type renamePlanner struct {
+	c       *MultiFileChangeCoordinator
+	plan    *ChangePlan
+	options PlanOptions
+	oldName string
+	newName string
+
+	// files caches source lines by file path; nil marks unreadable files.
+	files map[string][]string
+
+	// seen dedupes edits by file:line:column.
+	seen map[string]bool
+
+	// renamed holds the IDs of every symbol whose name changes.
+	renamed map[string]bool
+
+	callerEdits int
+	truncated   bool
+}
+
+// run collects edits and risks for the target and, for interface methods,
+// its implementations.
+func (r *renamePlanner) run(ctx context.Context, target *ast.Symbol) error {
+	r.renamed[target.ID] = true
+	r.addDeclaration(target, FileChangePrimary, "Rename declaration")
+	r.checkCollision(target)
+	if err := r.addInbound(ctx, target); err != nil {
+		return err
+	}
+
+	iface := r.owningInterface(target)
+	if iface != nil {
+		for _, impl := range r.implementations(iface) {
+			if err := ctx.Err(); err != nil {
+				return ErrContextCanceled
+			}
+			r.renamed[impl.ID] = true
+			r.addDeclaration(impl, FileChangeImplementerUpdate,
+				fmt.Sprintf("Implements %s.%s", iface.Name, r.oldName))
+			r.checkCollision(impl)
+			if impl.Language != target.Language {
+				r.addRisk(PlanRisk{
+					Kind:     PlanRiskCrossLanguageImplementer,
+					FilePath: impl.FilePath,
+					Line:     impl.StartLine,
+					SymbolID: impl.ID,
+					Message: fmt.Sprintf("%s implementation of %s.%s must be renamed by hand",
+						impl.Language, iface.Name, r.oldName),
+				})
+			}
+			if err := r.addInbound(ctx, impl); err != nil {
+				return err
+			}
+		}
+	}
+
+	r.addImports(target)
+	if err := r.findUnverifiedCalls(ctx); err != nil {
+		return err
+	}
+	r.findStringReferences()
+	return nil
+}
+
+// addDeclaration adds the edit renaming sym at its declaration line.
+func (r *renamePlanner) addDeclaration(sym *ast.Symbol, changeType FileChangeType, reason string) {
+	if !r.includeFile(sym.FilePath) {
+		return
+	}
+	r.addEdit(sym.FilePath, sym.StartLine, 0, sym.ID, changeType, reason)
+}
+
+// addInbound adds an edit for each inbound CALLS, REFERENCES and
+// IMPLEMENTS edge of sym.
+func (r *renamePlanner) addInbound(ctx context.Context, sym *ast.Symbol) error {
+	node, ok := r.c.graph.GetNode(sym.ID)
+	if !ok {
+		r.plan.Limitations = append(r.plan.Limitations,
+			fmt.Sprintf("%s is not in the graph; only its declaration was renamed", sym.ID))
+		return nil
+	}
+
+	for _, edge := range node.Incoming {
+		if err := ctx.Err(); err != nil {
+			return ErrContextCanceled
+		}
+
+		var changeType FileChangeType
+		var reason string
+		switch edge.Type {
+		case graph.EdgeTypeCalls:
+			changeType, reason = FileChangeCallerUpdate, "Call to "+r.oldName
+		case graph.EdgeTypeReferences:
+			changeType, reason = FileChangeReferenceUpdate, "Reference to "+r.oldName
+		case graph.EdgeTypeImplements:
+			changeType, reason = FileChangeImplementerUpdate, "Implements "+r.oldName
+		default:
+			continue
+		}
+
+		filePath := edge.Location.FilePath
+		if filePath == "" {
+			if from, ok := r.c.graph.GetNode(edge.FromID); ok && from.Symbol != nil {
+				filePath = from.Symbol.FilePath
+			}
+		}
+		if filePath == "" || !r.includeFile(filePath) {
+			continue
+		}
+
+		if changeType == FileChangeCallerUpdate {
+			if r.options.MaxCallers > 0 && r.callerEdits >= r.options.MaxCallers {
+				if !r.truncated {
+					r.truncated = true
+					r.plan.Warnings = append(r.plan.Warnings,
+						fmt.Sprintf("Caller edits truncated at %d; rerun with a higher limit", r.options.MaxCallers))
+				}
+				continue
+			}
+			r.callerEdits++
+		}
+
+		lines := r.lines(filePath)
+		if changeType == FileChangeReferenceUpdate && isImportLine(lineAt(lines, edge.Location.StartLine)) {
+			changeType, reason = FileChangeImportUpdate, "Import of "+r.oldName
+		}
+		if !r.addEdit(filePath, edge.Location.StartLine, edge.Location.StartCol, edge.FromID, changeType, reason) {
+			// Implicit relationships (Go interface satisfaction) have no
+			// occurrence of the name to edit.
+			if edge.Type != graph.EdgeTypeImplements && lines != nil {
+				r.addRisk(PlanRisk{
+					Kind:     PlanRiskUnverifiedReference,
+					FilePath: filePath,
+					Line:     edge.Location.StartLine,
+					SymbolID: edge.FromID,
+					Message:  fmt.Sprintf("%s edge recorded but %q not found on the line", edge.Type, r.oldName),
+				})
+			}
+		}
+	}
+	return nil
+}
+
+// addImports adds edits for import statements naming the target, e.g.
+// "from orders.service import ProcessOrder" or
+// "import { ProcessOrder } from './service'".
+func (r *renamePlanner) addImports(target *ast.Symbol) {
+	stem := strings.TrimSuffix(filepath.Base(target.FilePath), filepath.Ext(target.FilePath))
+	dir := filepath.Base(filepath.Dir(target.FilePath))
+
+	for _, imp := range r.c.index.GetByKind(ast.SymbolKindImport) {
+		if imp.FilePath == target.FilePath || !r.includeFile(imp.FilePath) {
+			continue
+		}
+		line := lineAt(r.lines(imp.FilePath), imp.StartLine)
+		if findIdentifier(line, r.oldName, 0) < 0 {
+			continue
+		}
+		module := path.Base(strings.NewReplacer(".", "/", "\\", "/").Replace(strings.Trim(imp.Name, `"'`)))
+		if module != stem && module != dir {
+			continue
+		}
+		r.addEdit(imp.FilePath, imp.StartLine, 0, imp.ID, FileChangeImportUpdate, "Import of "+r.oldName)
+	}
+}
+
+// findUnverifiedCalls reports call sites that name the old name but did
+// not resolve to any symbol, such as calls through reflection or
+// duck-typed receivers.
+func (r *renamePlanner) findUnverifiedCalls(ctx context.Context) error {
+	for id, node := range r.c.graph.Nodes() {
+		if err := ctx.Err(); err != nil {
+			return ErrContextCanceled
+		}
+		sym := node.Symbol
+		if sym == nil || len(sym.Calls) == 0 || !r.includeFile(sym.FilePath) {
+			continue
+		}
+		for _, call := range sym.Calls {
+			if call.Target != r.oldName && !strings.HasSuffix(call.Target, "."+r.oldName) {
+				continue
+			}
+			if r.resolvesCall(node, call) {
+				continue
+			}
+			filePath := call.Location.FilePath
+			if filePath == "" {
+				filePath = sym.FilePath
+			}
+			r.addRisk(PlanRisk{
+				Kind:     PlanRiskUnverifiedReference,
+				FilePath: filePath,
+				Line:     call.Location.StartLine,
+				SymbolID: id,
+				Message:  fmt.Sprintf("call to %s did not resolve to a symbol; it may reach the renamed one", call.Target),
+			})
+		}
+	}
+	return nil
+}
+
+// resolvesCall reports whether call has a CALLS edge from node, at its
+// line, to a symbol named oldName. Edges to renamed symbols were edited
+// by addInbound; edges to other symbols of that name are unrelated.
+func (r *renamePlanner) resolvesCall(node *graph.Node, call ast.CallSite) bool {
+	for _, edge := range node.Outgoing {
+		if edge.Type != graph.EdgeTypeCalls || edge.Location.StartLine != call.Location.StartLine {
+			continue
+		}
+		if r.renamed[edge.ToID] {
+			return true
+		}
+		if to, ok := r.c.graph.GetNode(edge.ToID); ok && to.Symbol != nil && to.Symbol.Name == r.oldName {
+			return true
+		}
+	}
+	return false
+}
+
+// findStringReferences reports quoted occurrences of the old name in the
+// files the plan edits, e.g. getattr(obj, "ProcessOrder").
+func (r *renamePlanner) findStringReferences() {
+	quoted := []string{`"` + r.oldName + `"`, `'` + r.oldName + `'`, "`" + r.oldName + "`"}
+	files := make([]string, 0)
+	for _, fc := range r.plan.FileChanges {
+		files = append(files, fc.FilePath)
+	}
+	sort.Strings(files)
+
+	var last string
+	for _, filePath := range files {
+		if filePath == last {
+			continue
+		}
+		last = filePath
+		for i, line := range r.lines(filePath) {
+			for _, q := range quoted {
+				if strings.Contains(line, q) {
+					r.addRisk(PlanRisk{
+						Kind:     PlanRiskUnverifiedReference,
+						FilePath: filePath,
+						Line:     i + 1,
+						Message:  fmt.Sprintf("string literal %s may refer to the renamed symbol", q),
+					})
+					break
+				}
+			}
+		}
+	}
+}
+
+// checkCollision reports symbols already named newName in sym's scope:
+// the same directory, receiver and language.
+func (r *renamePlanner) checkCollision(sym *ast.Symbol) {
+	dir := filepath.Dir(sym.FilePath)
+	for _, other := range r.c.index.GetByName(r.newName) {
+		if other.Kind == ast.SymbolKindImport || other.Language != sym.Language ||
+			other.Receiver != sym.Receiver || filepath.Dir(other.FilePath) != dir {
+			continue
+		}
+		if ast.IsBuildVariant(sym, other) {
+			continue
+		}
+		r.addRisk(PlanRisk{
+			Kind:     PlanRiskNameCollision,
+			FilePath: other.FilePath,
+			Line:     other.StartLine,
+			SymbolID: other.ID,
+			Message:  fmt.Sprintf("%s %s is already declared in %s", other.Kind, r.newName, dir),
+		})
+	}
+}
+
+// owningInterface returns the interface declaring sym when sym is an
+// interface method, or nil.
+func (r *renamePlanner) owningInterface(sym *ast.Symbol) *ast.Symbol {
+	if sym.Kind != ast.SymbolKindMethod {
+		return nil
+	}
+	for _, candidate := range r.c.index.GetByFile(sym.FilePath) {
+		if candidate.Kind != ast.SymbolKindInterface {
+			continue
+		}
+		for _, child := range candidate.Children {
+			if child != nil && child.ID == sym.ID {
+				return candidate
+			}
+		}
+		if sym.Receiver == "" && candidate.StartLine < sym.StartLine && sym.EndLine <= candidate.EndLine {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// implementations returns the methods named oldName on types with an
+// IMPLEMENTS edge to iface, in ID order.
+func (r *renamePlanner) implementations(iface *ast.Symbol) []*ast.Symbol {
+	node, ok := r.c.graph.GetNode(iface.ID)
+	if !ok {
+		return nil
+	}
+	impls := make([]*ast.Symbol, 0)
+	for _, edge := range node.Incoming {
+		if edge.Type != graph.EdgeTypeImplements {
+			continue
+		}
+		typeNode, ok := r.c.graph.GetNode(edge.FromID)
+		if !ok || typeNode.Symbol == nil {
+			continue
+		}
+		typeSym := typeNode.Symbol
+		for _, method := range r.c.index.GetByName(r.oldName) {
+			if method.Kind != ast.SymbolKindMethod || r.renamed[method.ID] {
+				continue
+			}
+			if method.Receiver != typeSym.Name && !isChildOf(typeSym, method.ID) {
+				continue
+			}
+			if method.Receiver == typeSym.Name && filepath.Dir(method.FilePath) != filepath.Dir(typeSym.FilePath) {
+				continue
+			}
+			impls = append(impls, method)
+		}
+	}
+	sort.Slice(impls, func(i, j int) bool { return impls[i].ID < impls[j].ID })
+	return impls
+}
+
+// addEdit records one occurrence of oldName on the given line, searching
+// from col (0-indexed). Returns false when the line has no occurrence or
+// the file cannot be read.
+func (r *renamePlanner) addEdit(filePath string, line, col int, symbolID string, changeType FileChangeType, reason string) bool {
+	lines := r.lines(filePath)
+	if lines == nil {
+		return false
+	}
+	text := lineAt(lines, line)
+	at := findIdentifier(text, r.oldName, col)
+	if at < 0 {
+		at = findIdentifier(text, r.oldName, 0)
+	}
+	if at < 0 {
+		return false
+	}
+
+	key := fmt.Sprintf("%s:%d:%d", filePath, line, at)
+	if r.seen[key] {
+		return true
+	}
+	r.seen[key] = true
+
+	r.plan.FileChanges = append(r.plan.FileChanges, FileChange{
+		FilePath:     filePath,
+		SymbolID:     symbolID,
+		ChangeType:   changeType,
+		CurrentCode:  text,
+		ProposedCode: text[:at] + r.newName + text[at+len(r.oldName):],
+		StartLine:    line,
+		EndLine:      line,
+		Column:       at + 1,
+		OldText:      r.oldName,
+		NewText:      r.newName,
+		Reason:       reason,
+	})
+	return true
+}
+
+// addRisk appends a risk, dropping exact duplicates.
+func (r *renamePlanner) addRisk(risk PlanRisk) {
+	for _, existing := range r.plan.Risks {
+		if existing == risk {
+			return
+		}
+	}
+	r.plan.Risks = append(r.plan.Risks, risk)
+}
+
+// includeFile applies the IncludeTests option.
+func (r *renamePlanner) includeFile(filePath string) bool {
+	return r.options.IncludeTests || !isTestFile(filePath)
+}
+
+// lines returns the source lines of filePath, reading it once. Returns nil
+// and records a limitation when the file cannot be read.
+func (r *renamePlanner) lines(filePath string) []string {
+	if lines, ok := r.files[filePath]; ok {
+		return lines
+	}
+	content, err := r.readFile(filePath)
+	if err != nil {
+		r.files[filePath] = nil
+		r.plan.Limitations = append(r.plan.Limitations,
+			fmt.Sprintf("Could not read %s: occurrences in it are not listed", filePath))
+		return nil
+	}
+	lines := strings.Split(string(content), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimSuffix(l, "\r")
+	}
+	r.files[filePath] = lines
+	return lines
+}
+
+// readFile reads a project file, refusing paths that resolve outside the
+// project root (including through symlinks).
+func (r *renamePlanner) readFile(filePath string) ([]byte, error) {
+	return readProjectFile(r.c.graph.ProjectRoot, filePath)
+}
+
+// lineAt returns the 1-indexed line, or "" when out of range.
+func lineAt(lines []string, line int) string {
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return lines[line-1]
+}
+
+// findIdentifier returns the byte offset of the first whole-identifier
+// occurrence of name in text at or after from, or -1.
+func findIdentifier(text, name string, from int) int {
+	if from < 0 || from > len(text) {
+		from = 0
+	}
+	for i := from; i <= len(text)-len(name); {
+		j := strings.Index(text[i:], name)
+		if j < 0 {
+			return -1
+		}
+		at := i + j
+		end := at + len(name)
+		if (at == 0 || !isIdentByte(text[at-1])) && (end == len(text) || !isIdentByte(text[end])) {
+			return at
+		}
+		i = at + 1
+	}
+	return -1
+}
+
+func isIdentByte(b byte) bool {
+	return b == '_' || b == '$' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// isImportLine reports whether a source line is an import statement.
+func isImportLine(line string) bool {
+	t := strings.TrimSpace(line)
+	return strings.HasPrefix(t, "import ") || strings.HasPrefix(t, "from ") ||
+		strings.HasPrefix(t, "use ") || strings.Contains(t, "require(")
+}
+
+// isChildOf reports whether id is a direct child of sym.
+func isChildOf(sym *ast.Symbol, id string) bool {
+	for _, child := range sym.Children {
+		if child != nil && child.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// sortRenameChanges orders edits by file, line and column, keeping the
+// primary declaration first.
+func sortRenameChanges(changes []FileChange) {
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i], changes[j]
+		if (a.ChangeType == FileChangePrimary) != (b.ChangeType == FileChangePrimary) {
+			return a.ChangeType == FileChangePrimary
+		}
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		return a.Column < b.Column
+	})
+}
+
+// escalateRenameRisk raises level for the risks found: collisions and
+// cross-language implementers make a rename at least HIGH, unverified
+// references at least MEDIUM.
+func escalateRenameRisk(level RiskLevel, risks []PlanRisk) RiskLevel {
+	rank := map[RiskLevel]int{RiskLow: 0, RiskMedium: 1, RiskHigh: 2, RiskCritical: 3}
+	for _, risk := range risks {
+		floor := RiskMedium
+		if risk.Kind == PlanRiskNameCollision || risk.Kind == PlanRiskCrossLanguageImplementer {
+			floor = RiskHigh
+		}
+		if rank[floor] > rank[level] {
+			level = floor
+		}
+	}
+	return level
+}
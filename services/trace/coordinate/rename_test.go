@@ -0,0 +1,225 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package coordinate
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/reason"
+)
+
+// createRenameProject writes a small Go/Python project to a temp dir and
+// builds its graph by hand: a Store interface whose Save method is
+// implemented by a Go type and a Python class, and called from Use.
+func createRenameProject(t *testing.T) *MultiFileChangeCoordinator {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"pkg/store.go": "package pkg\n\ntype Store interface {\n\tSave(data []byte) error\n}\n",
+		"pkg/file_store.go": "package pkg\n\ntype FileStore struct{}\n\n" +
+			"func (f *FileStore) Save(data []byte) error { return nil }\n" +
+			"func (f *FileStore) Persist(data []byte) error { return nil }\n",
+		"pkg/use.go": "package pkg\n\nfunc Use(s Store, f *FileStore, d any) {\n" +
+			"\ts.Save(nil)\n\tf.Save(nil)\n\td.(interface{ Save([]byte) error }).Save(nil)\n" +
+			"\tcall(\"Save\")\n}\n",
+		"pkg/use_test.go": "package pkg\n\nfunc TestUse(t *testing.T) {\n\tnew(FileStore).Save(nil)\n}\n",
+		"py/store.py":     "class PyStore:\n    def Save(self, data):\n        return None\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	symbols := []*ast.Symbol{
+		{ID: "pkg/store.go:3:Store", Name: "Store", Kind: ast.SymbolKindInterface, FilePath: "pkg/store.go", StartLine: 3, EndLine: 5, Language: "go"},
+		{ID: "pkg/store.go:4:Save", Name: "Save", Kind: ast.SymbolKindMethod, FilePath: "pkg/store.go", StartLine: 4, EndLine: 4, Language: "go"},
+		{ID: "pkg/file_store.go:3:FileStore", Name: "FileStore", Kind: ast.SymbolKindStruct, FilePath: "pkg/file_store.go", StartLine: 3, EndLine: 3, Language: "go"},
+		{ID: "pkg/file_store.go:5:Save", Name: "Save", Kind: ast.SymbolKindMethod, Receiver: "FileStore", FilePath: "pkg/file_store.go", StartLine: 5, EndLine: 5, Language: "go"},
+		{ID: "pkg/file_store.go:6:Persist", Name: "Persist", Kind: ast.SymbolKindMethod, Receiver: "FileStore", FilePath: "pkg/file_store.go", StartLine: 6, EndLine: 6, Language: "go"},
+		{
+			ID: "pkg/use.go:3:Use", Name: "Use", Kind: ast.SymbolKindFunction, FilePath: "pkg/use.go", StartLine: 3, EndLine: 8, Language: "go",
+			Calls: []ast.CallSite{
+				{Target: "Save", IsMethod: true, Receiver: "s", Location: ast.Location{FilePath: "pkg/use.go", StartLine: 4, StartCol: 1}},
+				{Target: "Save", IsMethod: true, Receiver: "f", Location: ast.Location{FilePath: "pkg/use.go", StartLine: 5, StartCol: 1}},
+				{Target: "Save", IsMethod: true, Receiver: "d", Location: ast.Location{FilePath: "pkg/use.go", StartLine: 6, StartCol: 1}},
+				{Target: "call", Location: ast.Location{FilePath: "pkg/use.go", StartLine: 7, StartCol: 1}},
+			},
+		},
+		{ID: "pkg/use_test.go:3:TestUse", Name: "TestUse", Kind: ast.SymbolKindFunction, FilePath: "pkg/use_test.go", StartLine: 3, EndLine: 5, Language: "go"},
+		{ID: "py/store.py:1:PyStore", Name: "PyStore", Kind: ast.SymbolKindClass, FilePath: "py/store.py", StartLine: 1, EndLine: 3, Language: "python"},
+		{ID: "py/store.py:2:Save", Name: "Save", Kind: ast.SymbolKindMethod, Receiver: "PyStore", FilePath: "py/store.py", StartLine: 2, EndLine: 3, Language: "python"},
+	}
+
+	g := graph.NewGraph(root)
+	idx := index.NewSymbolIndex()
+	for _, sym := range symbols {
+		if err := idx.Add(sym); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	edges := []struct {
+		from, to string
+		typ      graph.EdgeType
+		loc      ast.Location
+	}{
+		{"pkg/use.go:3:Use", "pkg/store.go:4:Save", graph.EdgeTypeCalls, ast.Location{FilePath: "pkg/use.go", StartLine: 4, StartCol: 1}},
+		{"pkg/use.go:3:Use", "pkg/file_store.go:5:Save", graph.EdgeTypeCalls, ast.Location{FilePath: "pkg/use.go", StartLine: 5, StartCol: 1}},
+		{"pkg/use_test.go:3:TestUse", "pkg/file_store.go:5:Save", graph.EdgeTypeCalls, ast.Location{FilePath: "pkg/use_test.go", StartLine: 4, StartCol: 1}},
+		{"pkg/file_store.go:3:FileStore", "pkg/store.go:3:Store", graph.EdgeTypeImplements, ast.Location{FilePath: "pkg/file_store.go", StartLine: 3}},
+		{"py/store.py:1:PyStore", "pkg/store.go:3:Store", graph.EdgeTypeImplements, ast.Location{FilePath: "py/store.py", StartLine: 1}},
+	}
+	for _, e := range edges {
+		if err := g.AddEdge(e.from, e.to, e.typ, e.loc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.Freeze()
+
+	return NewMultiFileChangeCoordinator(g, idx,
+		reason.NewBreakingChangeAnalyzer(g, idx),
+		analysis.NewBlastRadiusAnalyzer(g, idx, nil),
+		reason.NewChangeValidator(idx))
+}
+
+func TestPreviewRename_InterfaceMethod(t *testing.T) {
+	c := createRenameProject(t)
+
+	plan, err := c.PreviewRename(context.Background(), "pkg/store.go:4:Save", "Persist", nil)
+	if err != nil {
+		t.Fatalf("PreviewRename: %v", err)
+	}
+
+	type edit struct {
+		file   string
+		line   int
+		column int
+		kind   FileChangeType
+	}
+	want := []edit{
+		{"pkg/store.go", 4, 2, FileChangePrimary},
+		{"pkg/file_store.go", 5, 21, FileChangeImplementerUpdate},
+		{"pkg/use.go", 4, 4, FileChangeCallerUpdate},
+		{"pkg/use.go", 5, 4, FileChangeCallerUpdate},
+		{"pkg/use_test.go", 4, 17, FileChangeCallerUpdate},
+		{"py/store.py", 2, 9, FileChangeImplementerUpdate},
+	}
+	if len(plan.FileChanges) != len(want) {
+		t.Fatalf("got %d edits, want %d: %+v", len(plan.FileChanges), len(want), plan.FileChanges)
+	}
+	for i, w := range want {
+		fc := plan.FileChanges[i]
+		got := edit{fc.FilePath, fc.StartLine, fc.Column, fc.ChangeType}
+		if got != w {
+			t.Errorf("edit[%d] = %+v, want %+v", i, got, w)
+		}
+		if fc.OldText != "Save" || fc.NewText != "Persist" {
+			t.Errorf("edit[%d] text %q -> %q", i, fc.OldText, fc.NewText)
+		}
+	}
+	if got := plan.FileChanges[0].ProposedCode; got != "\tPersist(data []byte) error" {
+		t.Errorf("primary ProposedCode = %q", got)
+	}
+	if plan.PrimaryChange.ChangeType != ChangeRenameSymbol || plan.PrimaryChange.NewName != "Persist" {
+		t.Errorf("PrimaryChange = %+v", plan.PrimaryChange)
+	}
+	if plan.Order[0] != "pkg/store.go" {
+		t.Errorf("Order = %v, want pkg/store.go first", plan.Order)
+	}
+
+	kinds := make(map[PlanRiskKind][]int)
+	for _, risk := range plan.Risks {
+		kinds[risk.Kind] = append(kinds[risk.Kind], risk.Line)
+	}
+	if lines := kinds[PlanRiskNameCollision]; len(lines) != 1 || lines[0] != 6 {
+		t.Errorf("name collisions at %v, want line 6 of file_store.go", lines)
+	}
+	if len(kinds[PlanRiskCrossLanguageImplementer]) != 1 {
+		t.Errorf("cross-language risks = %v, want 1", kinds[PlanRiskCrossLanguageImplementer])
+	}
+	unverified := kinds[PlanRiskUnverifiedReference]
+	if len(unverified) != 2 || unverified[0] != 6 || unverified[1] != 7 {
+		t.Errorf("unverified references at %v, want lines 6 and 7", unverified)
+	}
+	if plan.RiskLevel != RiskHigh && plan.RiskLevel != RiskCritical {
+		t.Errorf("RiskLevel = %s, want at least HIGH", plan.RiskLevel)
+	}
+
+	if stored, ok := c.GetPlan(plan.ID); !ok || stored != plan {
+		t.Error("plan should be stored for validate_plan and preview_changes")
+	}
+	diffs, err := c.PreviewChanges(context.Background(), plan)
+	if err != nil || len(diffs) != len(want) {
+		t.Errorf("PreviewChanges = %d diffs, %v", len(diffs), err)
+	}
+}
+
+func TestPreviewRename_ExcludeTests(t *testing.T) {
+	c := createRenameProject(t)
+	opts := DefaultPlanOptions()
+	opts.IncludeTests = false
+
+	plan, err := c.PreviewRename(context.Background(), "pkg/file_store.go:5:Save", "Store2", &opts)
+	if err != nil {
+		t.Fatalf("PreviewRename: %v", err)
+	}
+	for _, fc := range plan.FileChanges {
+		if isTestFile(fc.FilePath) {
+			t.Errorf("test file edit %s:%d with IncludeTests=false", fc.FilePath, fc.StartLine)
+		}
+		if fc.FilePath == "pkg/store.go" || fc.FilePath == "py/store.py" {
+			t.Errorf("renaming an implementation should not touch %s", fc.FilePath)
+		}
+	}
+	if len(plan.FileChanges) != 2 {
+		t.Errorf("got %d edits, want declaration and one caller: %+v", len(plan.FileChanges), plan.FileChanges)
+	}
+}
+
+func TestPreviewRename_InvalidInput(t *testing.T) {
+	c := createRenameProject(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		targetID string
+		newName  string
+		wantErr  error
+	}{
+		{"empty target", "", "Persist", ErrInvalidInput},
+		{"invalid identifier", "pkg/store.go:4:Save", "not-valid", ErrInvalidInput},
+		{"unchanged name", "pkg/store.go:4:Save", "Save", ErrInvalidInput},
+		{"unknown symbol", "pkg/none.go:1:None", "Other", ErrSymbolNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := c.PreviewRename(ctx, tt.targetID, tt.newName, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -13,8 +13,8 @@
 // # Description
 //
 // This package enables planning, validating, and previewing coordinated changes
-// across multiple files. It is READ-ONLY - it analyzes and generates plans but
-// does not modify files. The agent uses Edit tools after approval.
+// across multiple files. Planning is read-only; ApplyPlan writes an approved
+// plan after checking each change against the source it was planned from.
 //
 // # Thread Safety
 //
@@ -133,6 +133,21 @@ type FileChange struct {
 	// EndLine is the 1-indexed ending line of the change.
 	EndLine int `json:"end_line"`
 
+	// Column is the 1-indexed byte column of a single-token edit on
+	// StartLine. Zero for whole-region changes.
+	Column int `json:"column,omitempty"`
+
+	// OldText is the token replaced at Column (rename previews only).
+	OldText string `json:"old_text,omitempty"`
+
+	// NewText is the token written at Column (rename previews only).
+	NewText string `json:"new_text,omitempty"`
+
+	// ContentHash is RegionHash of lines StartLine..EndLine when the plan
+	// was made. ApplyPlan refuses the change if the region no longer
+	// matches. Empty when the file could not be read at plan time.
+	ContentHash string `json:"content_hash,omitempty"`
+
 	// Reason explains why this change is needed.
 	Reason string `json:"reason"`
 }
@@ -176,10 +191,50 @@ type ChangePlan struct {
 	// Limitations lists what we couldn't analyze.
 	Limitations []string `json:"limitations,omitempty"`
 
+	// Risks lists specific hazards found while planning, e.g. name
+	// collisions or references that could not be verified.
+	Risks []PlanRisk `json:"risks,omitempty"`
+
 	// CreatedAt is when the plan was created (Unix milliseconds UTC).
 	CreatedAt int64 `json:"created_at"`
 }
 
+// PlanRiskKind categorizes a plan risk.
+type PlanRiskKind string
+
+const (
+	// PlanRiskNameCollision means the new name is already declared in the
+	// target scope.
+	PlanRiskNameCollision PlanRiskKind = "name_collision"
+
+	// PlanRiskCrossLanguageImplementer means an implementer of a renamed
+	// interface method is written in another language and may not be
+	// updated by the language's own tooling.
+	PlanRiskCrossLanguageImplementer PlanRiskKind = "cross_language_implementer"
+
+	// PlanRiskUnverifiedReference means a dynamic or string reference to
+	// the old name was found that the graph cannot tie to the target.
+	PlanRiskUnverifiedReference PlanRiskKind = "unverified_reference"
+)
+
+// PlanRisk is one hazard attached to a change plan.
+type PlanRisk struct {
+	// Kind categorizes the risk.
+	Kind PlanRiskKind `json:"kind"`
+
+	// FilePath is where the risk was found, if it has a location.
+	FilePath string `json:"file_path,omitempty"`
+
+	// Line is the 1-indexed line of the risk, if it has a location.
+	Line int `json:"line,omitempty"`
+
+	// SymbolID is the symbol involved, if any.
+	SymbolID string `json:"symbol_id,omitempty"`
+
+	// Message explains the risk.
+	Message string `json:"message"`
+}
+
 // GetID returns the plan's unique identifier.
 //
 // Description:
@@ -24,6 +24,8 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/google/uuid"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 var (
@@ -232,7 +234,7 @@ func (e *Executor) Run(ctx context.Context, input any) (*Result, error) {
 		e.logger.Error("pipeline failed",
 			slog.String("session_id", sessionID),
 			slog.String("failed_node", result.FailedNode),
-			slog.String("error", result.Error),
+			telemetry.SensitiveLog("error", result.Error),
 		)
 	}
 
@@ -412,7 +414,7 @@ func (e *Executor) executeNode(ctx context.Context, node Node, state *State) err
 		e.logger.Error("node failed",
 			slog.String("node", node.Name()),
 			slog.Duration("duration", duration),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 
 		return NewNodeError(node.Name(), err)
@@ -641,7 +643,7 @@ func (e *Executor) rehydrateNodes(ctx context.Context, state *State) error {
 		if err := rehydratable.OnResume(ctx, output); err != nil {
 			e.logger.Warn("node rehydration failed, will re-execute",
 				slog.String("node", name),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 
 			// Mark node as not completed so it will be re-executed
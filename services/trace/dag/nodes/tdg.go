@@ -17,6 +17,7 @@ import (
 	"time"
 
 	mainDag "github.com/AleutianAI/AleutianFOSS/services/trace/dag"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // TDGConfig contains configuration for Test-Driven Generation.
@@ -184,8 +185,8 @@ func (n *TDGNode) Execute(ctx context.Context, inputs map[string]any) (any, erro
 	start := time.Now()
 
 	n.logger.Info("starting TDG",
-		slog.String("query", truncate(req.Query, 100)),
-		slog.String("target_file", req.TargetFile),
+		telemetry.SensitiveLog("query", truncate(req.Query, 100)),
+		telemetry.SensitiveLog("target_file", req.TargetFile),
 		slog.String("language", req.Language),
 	)
 
@@ -0,0 +1,343 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package dependencies reads a project's third-party dependencies from its
+// manifests and lockfiles and matches them against OSV advisories.
+//
+// Supported files, read from the project root:
+//
+//   - Go: go.mod, with go.sum for modules go.mod does not list
+//   - npm: package.json, package-lock.json (v1-v3) and yarn.lock
+//   - PyPI: requirements.txt and poetry.lock
+//
+// Lockfile versions take precedence over manifest constraints. Nothing is
+// fetched over the network.
+package dependencies
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Ecosystems, named as in OSV advisories.
+const (
+	EcosystemGo   = "Go"
+	EcosystemNPM  = "npm"
+	EcosystemPyPI = "PyPI"
+)
+
+// maxFileSize bounds the size of a manifest or lockfile that is read.
+const maxFileSize = 32 << 20
+
+// Dependency is one third-party package the project depends on.
+type Dependency struct {
+	// Ecosystem is EcosystemGo, EcosystemNPM or EcosystemPyPI.
+	Ecosystem string `json:"ecosystem"`
+
+	// Name is the module path, npm package name or PyPI distribution name.
+	Name string `json:"name"`
+
+	// Version is the resolved version, or "" if only a constraint is known.
+	Version string `json:"version,omitempty"`
+
+	// Constraint is the version range declared in the manifest, if any.
+	Constraint string `json:"constraint,omitempty"`
+
+	// Direct is true if the project's manifest declares the dependency.
+	Direct bool `json:"direct"`
+
+	// Source is the file the version came from, relative to the root.
+	Source string `json:"source"`
+}
+
+// Set is the dependencies of one project.
+//
+// Thread Safety: Safe for concurrent use after Load returns.
+type Set struct {
+	// Files are the manifests and lockfiles read, relative to the root.
+	Files []string
+
+	byKey map[string]*Dependency
+}
+
+// Load reads the dependency files in root.
+//
+// Description:
+//
+//	Missing files are skipped. A file that exists but cannot be read or
+//	parsed is an error; the dependencies read from the other files are
+//	still returned.
+//
+// Inputs:
+//
+//	root - The project root.
+//
+// Outputs:
+//
+//	*Set - The dependencies. Never nil; empty if no file was found.
+//	error - Joined errors of the files that failed to parse.
+func Load(root string) (*Set, error) {
+	s := &Set{byKey: make(map[string]*Dependency)}
+	var errs []error
+
+	for _, p := range parsers {
+		data, err := readFile(root, p.file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err == nil {
+			err = p.parse(s, data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", p.file, err))
+			continue
+		}
+		s.Files = append(s.Files, p.file)
+	}
+	return s, errors.Join(errs...)
+}
+
+// parsers are applied in order. Manifests come before lockfiles so that
+// lockfile versions override manifest constraints.
+var parsers = []struct {
+	file  string
+	parse func(*Set, []byte) error
+}{
+	{"go.mod", parseGoMod},
+	{"go.sum", parseGoSum},
+	{"package.json", parsePackageJSON},
+	{"package-lock.json", parsePackageLock},
+	{"yarn.lock", parseYarnLock},
+	{"requirements.txt", parseRequirements},
+	{"poetry.lock", parsePoetryLock},
+}
+
+// readFile reads a dependency file, refusing oversized files.
+func readFile(root, name string) ([]byte, error) {
+	path := filepath.Join(root, name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxFileSize {
+		return nil, fmt.Errorf("file larger than %d bytes", maxFileSize)
+	}
+	return os.ReadFile(path)
+}
+
+// All returns the dependencies sorted by ecosystem and name.
+func (s *Set) All() []Dependency {
+	out := make([]Dependency, 0, len(s.byKey))
+	for _, d := range s.byKey {
+		out = append(out, *d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Ecosystem != out[j].Ecosystem {
+			return out[i].Ecosystem < out[j].Ecosystem
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// Len returns the number of dependencies.
+func (s *Set) Len() int {
+	return len(s.byKey)
+}
+
+// Get returns a dependency by ecosystem and name.
+func (s *Set) Get(ecosystem, name string) (Dependency, bool) {
+	d, ok := s.byKey[key(ecosystem, name)]
+	if !ok {
+		return Dependency{}, false
+	}
+	return *d, true
+}
+
+// Resolve returns the dependency that provides an import path.
+//
+// Description:
+//
+//	Go import paths resolve to the longest module path they fall under.
+//	npm specifiers resolve to their package name ("lodash/fp" to
+//	"lodash", "@scope/pkg/x" to "@scope/pkg"). Python imports resolve by
+//	top-level module, normalized as PyPI does, with a few well-known
+//	module-to-distribution renames (yaml to PyYAML).
+//
+// Inputs:
+//
+//	importPath - The import path or module as written in source.
+//	language - The importing file's language ("go", "javascript",
+//	  "typescript", "python"), or "" to try every ecosystem.
+//
+// Outputs:
+//
+//	Dependency - The providing dependency.
+//	bool - False if no dependency provides the import, e.g. the standard
+//	  library or a project-internal package.
+func (s *Set) Resolve(importPath, language string) (Dependency, bool) {
+	if s == nil || importPath == "" {
+		return Dependency{}, false
+	}
+	for _, eco := range ecosystemsFor(language) {
+		var d *Dependency
+		switch eco {
+		case EcosystemGo:
+			d = s.resolveGo(importPath)
+		case EcosystemNPM:
+			d = s.byKey[key(EcosystemNPM, npmPackageName(importPath))]
+		case EcosystemPyPI:
+			d = s.resolvePython(importPath)
+		}
+		if d != nil {
+			return *d, true
+		}
+	}
+	return Dependency{}, false
+}
+
+// ecosystemsFor returns the ecosystems to try for a language.
+func ecosystemsFor(language string) []string {
+	switch strings.ToLower(language) {
+	case "go":
+		return []string{EcosystemGo}
+	case "javascript", "typescript", "jsx", "tsx":
+		return []string{EcosystemNPM}
+	case "python":
+		return []string{EcosystemPyPI}
+	default:
+		return []string{EcosystemGo, EcosystemNPM, EcosystemPyPI}
+	}
+}
+
+// resolveGo returns the module with the longest path covering importPath.
+func (s *Set) resolveGo(importPath string) *Dependency {
+	for path := importPath; path != "." && path != "/" && path != ""; path = parentPath(path) {
+		if d, ok := s.byKey[key(EcosystemGo, path)]; ok {
+			return d
+		}
+	}
+	return nil
+}
+
+// parentPath returns path without its last slash-separated element.
+func parentPath(path string) string {
+	i := strings.LastIndexByte(path, '/')
+	if i < 0 {
+		return ""
+	}
+	return path[:i]
+}
+
+// npmPackageName returns the package part of an npm import specifier.
+func npmPackageName(spec string) string {
+	spec = strings.TrimPrefix(spec, "npm:")
+	parts := strings.Split(spec, "/")
+	if strings.HasPrefix(spec, "@") && len(parts) >= 2 {
+		return parts[0] + "/" + parts[1]
+	}
+	return parts[0]
+}
+
+// pythonModuleRenames maps top-level modules to the distributions that
+// provide them, where the names differ beyond normalization.
+var pythonModuleRenames = map[string]string{
+	"yaml":     "pyyaml",
+	"sklearn":  "scikit-learn",
+	"skimage":  "scikit-image",
+	"pil":      "pillow",
+	"bs4":      "beautifulsoup4",
+	"cv2":      "opencv-python",
+	"dateutil": "python-dateutil",
+	"jwt":      "pyjwt",
+	"dotenv":   "python-dotenv",
+	"attr":     "attrs",
+}
+
+// resolvePython returns the distribution providing a module.
+func (s *Set) resolvePython(module string) *Dependency {
+	top := strings.ToLower(strings.SplitN(module, ".", 2)[0])
+	if d, ok := s.byKey[key(EcosystemPyPI, top)]; ok {
+		return d
+	}
+	if dist, ok := pythonModuleRenames[top]; ok {
+		return s.byKey[key(EcosystemPyPI, dist)]
+	}
+	return nil
+}
+
+// normalizePyPI normalizes a distribution name as PEP 503 does.
+func normalizePyPI(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '.' {
+			return '-'
+		}
+		return r
+	}, name)
+}
+
+// key returns the map key of a dependency.
+func key(ecosystem, name string) string {
+	if ecosystem == EcosystemPyPI {
+		name = normalizePyPI(name)
+	}
+	return ecosystem + "\x00" + name
+}
+
+// add records a dependency. A later resolved version replaces an earlier
+// one; Direct and Constraint are kept from the manifest.
+func (s *Set) add(d Dependency) {
+	k := key(d.Ecosystem, d.Name)
+	existing, ok := s.byKey[k]
+	if !ok {
+		s.byKey[k] = &d
+		return
+	}
+	if d.Version != "" {
+		existing.Version = d.Version
+		existing.Source = d.Source
+	}
+	if d.Constraint != "" && existing.Constraint == "" {
+		existing.Constraint = d.Constraint
+	}
+	existing.Direct = existing.Direct || d.Direct
+}
+
+// Merge adds the dependencies of other that s does not have. Used to
+// combine the roots of a workspace; the first root to declare a
+// dependency wins.
+func (s *Set) Merge(other *Set) {
+	if other == nil {
+		return
+	}
+	for k, d := range other.byKey {
+		if _, ok := s.byKey[k]; !ok {
+			dep := *d
+			s.byKey[k] = &dep
+		}
+	}
+	s.Files = append(s.Files, other.Files...)
+}
+
+// NewSet returns a set of the given dependencies, for callers that build
+// one without reading files.
+func NewSet(deps ...Dependency) *Set {
+	s := &Set{byKey: make(map[string]*Dependency)}
+	for _, d := range deps {
+		s.add(d)
+	}
+	return s
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestLoad_Go(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	github.com/gin-gonic/gin v1.9.1
+	golang.org/x/text v0.3.7 // indirect
+	example.com/forked v1.0.0
+	example.com/local v0.1.0
+)
+
+replace example.com/forked => example.com/fork v1.0.5
+
+replace example.com/local => ../local
+`,
+		"go.sum": `github.com/gin-gonic/gin v1.9.1 h1:abc=
+github.com/pkg/errors v0.8.0/go.mod h1:def=
+github.com/pkg/errors v0.9.1 h1:ghi=
+github.com/pkg/errors v0.9.1/go.mod h1:jkl=
+`,
+	})
+
+	s, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		version string
+		direct  bool
+		source  string
+	}{
+		{"github.com/gin-gonic/gin", "v1.9.1", true, "go.mod"},
+		{"golang.org/x/text", "v0.3.7", false, "go.mod"},
+		{"example.com/forked", "v1.0.5", true, "go.mod"},
+		{"example.com/local", "", true, "go.mod"},
+		{"github.com/pkg/errors", "v0.9.1", false, "go.sum"},
+	}
+	for _, tt := range tests {
+		d, ok := s.Get(EcosystemGo, tt.name)
+		if !ok {
+			t.Errorf("%s: not found", tt.name)
+			continue
+		}
+		if d.Version != tt.version || d.Direct != tt.direct || d.Source != tt.source {
+			t.Errorf("%s: got %+v", tt.name, d)
+		}
+	}
+
+	d, ok := s.Resolve("github.com/gin-gonic/gin/binding", "go")
+	if !ok || d.Name != "github.com/gin-gonic/gin" {
+		t.Errorf("Resolve subpackage: got %+v, %v", d, ok)
+	}
+	if _, ok := s.Resolve("fmt", "go"); ok {
+		t.Error("standard library resolved to a dependency")
+	}
+}
+
+func TestLoad_NPM(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"package.json": `{
+  "dependencies": {"lodash": "^4.17.0", "@scope/pkg": "1.2.3"},
+  "devDependencies": {"jest": "~29.0.0"}
+}`,
+		"package-lock.json": `{
+  "lockfileVersion": 3,
+  "packages": {
+    "": {"name": "app"},
+    "node_modules/lodash": {"version": "4.17.21"},
+    "node_modules/jest/node_modules/lodash": {"version": "3.10.1"},
+    "node_modules/jest": {"version": "29.0.3"},
+    "node_modules/@scope/pkg": {"version": "1.2.3"}
+  }
+}`,
+	})
+
+	s, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	d, _ := s.Get(EcosystemNPM, "lodash")
+	if d.Version != "4.17.21" || d.Constraint != "^4.17.0" || !d.Direct || d.Source != "package-lock.json" {
+		t.Errorf("lodash: got %+v", d)
+	}
+	if d, ok := s.Resolve("lodash/fp", "javascript"); !ok || d.Name != "lodash" {
+		t.Errorf("Resolve lodash/fp: got %+v, %v", d, ok)
+	}
+	if d, ok := s.Resolve("@scope/pkg/sub", "typescript"); !ok || d.Version != "1.2.3" {
+		t.Errorf("Resolve scoped: got %+v, %v", d, ok)
+	}
+}
+
+func TestParseYarnLock(t *testing.T) {
+	classic := `# yarn lockfile v1
+
+"@babel/core@^7.0.0", "@babel/core@^7.1.0":
+  version "7.12.3"
+  resolved "https://registry.yarnpkg.com/@babel/core/-/core-7.12.3.tgz"
+
+lodash@^3.0.0:
+  version "3.10.1"
+
+lodash@^4.17.0:
+  version "4.17.21"
+`
+	berry := `__metadata:
+  version: 6
+
+"left-pad@npm:^1.3.0":
+  version: 1.3.0
+`
+	s := NewSet()
+	if err := parseYarnLock(s, []byte(classic)); err != nil {
+		t.Fatal(err)
+	}
+	if err := parseYarnLock(s, []byte(berry)); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"@babel/core": "7.12.3", "lodash": "3.10.1", "left-pad": "1.3.0"} {
+		if d, _ := s.Get(EcosystemNPM, name); d.Version != want {
+			t.Errorf("%s: got %q, want %q", name, d.Version, want)
+		}
+	}
+	if _, ok := s.Get(EcosystemNPM, "__metadata"); ok {
+		t.Error("metadata block recorded as a package")
+	}
+}
+
+func TestLoad_Python(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"requirements.txt": `# comment
+requests[security]==2.25.0 ; python_version >= "3.6"
+Flask>=2.0
+PyYAML==6.0  # pinned
+-r other.txt
+-e git+https://github.com/x/y.git#egg=y
+https://example.com/pkg.tar.gz
+`,
+		"poetry.lock": `[[package]]
+name = "flask"
+version = "2.3.2"
+description = "A simple framework"
+
+[package.dependencies]
+click = ">=8.1.3"
+
+[[package]]
+name = "click"
+version = "8.1.7"
+
+[metadata]
+lock-version = "2.0"
+`,
+	})
+
+	s, err := Load(root)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if s.Len() != 4 {
+		t.Errorf("Len = %d, want 4: %+v", s.Len(), s.All())
+	}
+	if d, _ := s.Get(EcosystemPyPI, "requests"); d.Version != "2.25.0" || !d.Direct {
+		t.Errorf("requests: got %+v", d)
+	}
+	if d, _ := s.Get(EcosystemPyPI, "Flask"); d.Version != "2.3.2" || d.Constraint != ">=2.0" {
+		t.Errorf("flask: got %+v", d)
+	}
+	if d, ok := s.Resolve("yaml.loader", "python"); !ok || d.Version != "6.0" {
+		t.Errorf("Resolve yaml: got %+v, %v", d, ok)
+	}
+	if d, ok := s.Resolve("flask", "python"); !ok || d.Name != "Flask" {
+		t.Errorf("Resolve flask: got %+v, %v", d, ok)
+	}
+}
+
+func TestLoad_ParseErrorKeepsOtherFiles(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"package.json":     `{not json`,
+		"requirements.txt": "requests==2.31.0\n",
+	})
+	s, err := Load(root)
+	if err == nil {
+		t.Fatal("expected error for invalid package.json")
+	}
+	if _, ok := s.Get(EcosystemPyPI, "requests"); !ok {
+		t.Error("requirements.txt was not read")
+	}
+}
+
+func TestLoad_NoFiles(t *testing.T) {
+	s, err := Load(t.TempDir())
+	if err != nil || s == nil || s.Len() != 0 {
+		t.Errorf("Load(empty) = %v, %v", s, err)
+	}
+}
+
+func TestMerge_FirstWins(t *testing.T) {
+	a := NewSet(Dependency{Ecosystem: EcosystemNPM, Name: "lodash", Version: "4.17.21"})
+	b := NewSet(
+		Dependency{Ecosystem: EcosystemNPM, Name: "lodash", Version: "3.10.1"},
+		Dependency{Ecosystem: EcosystemNPM, Name: "react", Version: "18.2.0"},
+	)
+	a.Merge(b)
+	if d, _ := a.Get(EcosystemNPM, "lodash"); d.Version != "4.17.21" {
+		t.Errorf("lodash: got %q", d.Version)
+	}
+	if a.Len() != 2 {
+		t.Errorf("Len = %d, want 2", a.Len())
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package dependencies
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// GoPackageSource is where the source of an imported Go package lives.
+type GoPackageSource struct {
+	// ImportPath is the package's import path.
+	ImportPath string
+
+	// Dir is the absolute directory holding the package's files.
+	Dir string
+
+	// Vendored is true if Dir is under the project's vendor directory.
+	Vendored bool
+
+	// Module and Version identify the providing module. Version is empty
+	// for vendored packages and local replacements.
+	Module  string
+	Version string
+}
+
+// GoSourceLocator finds the source of the Go packages a module imports.
+//
+// Description:
+//
+//	Looks in the project's vendor directory first, then in the module
+//	cache at the version go.mod requires (after replace directives). A
+//	replacement by a local directory is read from that directory. The
+//	module cache is only read, never written: a module that was not
+//	downloaded is not found.
+//
+// Thread Safety: Safe for concurrent use after construction.
+type GoSourceLocator struct {
+	root     string
+	module   string
+	modCache string
+	requires map[string]goRequirement
+}
+
+// goRequirement is the resolved location of a required module.
+type goRequirement struct {
+	path     string
+	version  string
+	localDir string
+}
+
+// NewGoSourceLocator reads the go.mod at root.
+//
+// Inputs:
+//
+//	root - The project root holding go.mod.
+//	modCache - The module cache directory. "" uses GOMODCACHE, then
+//	  $GOPATH/pkg/mod, then ~/go/pkg/mod.
+//
+// Outputs:
+//
+//	*GoSourceLocator - The locator.
+//	error - Non-nil if go.mod is missing or invalid.
+func NewGoSourceLocator(root, modCache string) (*GoSourceLocator, error) {
+	data, err := readFile(root, "go.mod")
+	if err != nil {
+		return nil, err
+	}
+	f, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("go.mod: %w", err)
+	}
+	if f.Module == nil {
+		return nil, fmt.Errorf("go.mod: no module directive")
+	}
+	if modCache == "" {
+		modCache = defaultGoModCache()
+	}
+
+	l := &GoSourceLocator{
+		root:     root,
+		module:   f.Module.Mod.Path,
+		modCache: modCache,
+		requires: make(map[string]goRequirement, len(f.Require)),
+	}
+	for _, r := range f.Require {
+		l.requires[r.Mod.Path] = goRequirement{path: r.Mod.Path, version: r.Mod.Version}
+	}
+	for _, r := range f.Replace {
+		req, ok := l.requires[r.Old.Path]
+		if !ok || (r.Old.Version != "" && r.Old.Version != req.version) {
+			continue
+		}
+		if r.New.Version == "" {
+			dir := r.New.Path
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(root, dir)
+			}
+			req.localDir = dir
+		} else {
+			req.path, req.version = r.New.Path, r.New.Version
+		}
+		l.requires[r.Old.Path] = req
+	}
+	return l, nil
+}
+
+// ModulePath returns the project's module path.
+func (l *GoSourceLocator) ModulePath() string {
+	return l.module
+}
+
+// IsExternal reports whether importPath names a package outside the
+// standard library and the project's own module.
+func (l *GoSourceLocator) IsExternal(importPath string) bool {
+	if importPath == "" || importPath == "C" || IsGoStdlib(importPath) {
+		return false
+	}
+	return importPath != l.module && !strings.HasPrefix(importPath, l.module+"/")
+}
+
+// Locate finds the source directory of an imported package.
+//
+// Inputs:
+//
+//	importPath - The package's import path.
+//
+// Outputs:
+//
+//	GoPackageSource - Where the package's source is.
+//	bool - False if the package is not external (see IsExternal), no
+//	  required module provides it, or its directory does not exist.
+func (l *GoSourceLocator) Locate(importPath string) (GoPackageSource, bool) {
+	if !l.IsExternal(importPath) {
+		return GoPackageSource{}, false
+	}
+
+	vendorDir := filepath.Join(l.root, "vendor", filepath.FromSlash(importPath))
+	if isDir(vendorDir) {
+		return GoPackageSource{ImportPath: importPath, Dir: vendorDir, Vendored: true}, true
+	}
+
+	for path := importPath; path != ""; path = parentPath(path) {
+		req, ok := l.requires[path]
+		if !ok {
+			continue
+		}
+		rest := filepath.FromSlash(strings.TrimPrefix(strings.TrimPrefix(importPath, path), "/"))
+		src := GoPackageSource{ImportPath: importPath, Module: path}
+		if req.localDir != "" {
+			src.Dir = filepath.Join(req.localDir, rest)
+		} else {
+			escPath, err := module.EscapePath(req.path)
+			if err != nil {
+				return GoPackageSource{}, false
+			}
+			escVersion, err := module.EscapeVersion(req.version)
+			if err != nil {
+				return GoPackageSource{}, false
+			}
+			src.Dir = filepath.Join(l.modCache, escPath+"@"+escVersion, rest)
+			src.Version = req.version
+		}
+		if !isDir(src.Dir) {
+			return GoPackageSource{}, false
+		}
+		return src, true
+	}
+	return GoPackageSource{}, false
+}
+
+// IsGoStdlib reports whether importPath is a standard library package:
+// its first path element has no dot.
+func IsGoStdlib(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+// defaultGoModCache returns the module cache directory the go command
+// would use, without running it.
+func defaultGoModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	if gopath := os.Getenv("GOPATH"); gopath != "" {
+		return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, "go", "pkg", "mod")
+}
+
+// isDir reports whether path is an existing directory.
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
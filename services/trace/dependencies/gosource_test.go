@@ -0,0 +1,93 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package dependencies
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGoSourceLocator_Locate(t *testing.T) {
+	root := writeFiles(t, map[string]string{
+		"go.mod": `module example.com/app
+
+go 1.22
+
+require (
+	github.com/Masterminds/semver v1.5.0
+	example.com/vendored v1.0.0
+	example.com/forked v1.0.0
+	example.com/local v0.1.0
+	example.com/missing v1.0.0
+)
+
+replace example.com/forked => example.com/fork v1.0.5
+
+replace example.com/local => ./third_party/local
+`,
+	})
+	modCache := t.TempDir()
+	for _, dir := range []string{
+		filepath.Join(root, "vendor", "example.com", "vendored", "sub"),
+		filepath.Join(root, "third_party", "local", "pkg"),
+		filepath.Join(modCache, "github.com", "!masterminds", "semver@v1.5.0"),
+		filepath.Join(modCache, "example.com", "fork@v1.0.5", "util"),
+	} {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	l, err := NewGoSourceLocator(root, modCache)
+	if err != nil {
+		t.Fatalf("NewGoSourceLocator: %v", err)
+	}
+	if l.ModulePath() != "example.com/app" {
+		t.Errorf("ModulePath() = %q", l.ModulePath())
+	}
+
+	tests := []struct {
+		importPath string
+		found      bool
+		dir        string
+		vendored   bool
+		version    string
+	}{
+		{"github.com/Masterminds/semver", true, filepath.Join(modCache, "github.com", "!masterminds", "semver@v1.5.0"), false, "v1.5.0"},
+		{"example.com/vendored/sub", true, filepath.Join(root, "vendor", "example.com", "vendored", "sub"), true, ""},
+		{"example.com/forked/util", true, filepath.Join(modCache, "example.com", "fork@v1.0.5", "util"), false, "v1.0.5"},
+		{"example.com/local/pkg", true, filepath.Join(root, "third_party", "local", "pkg"), false, ""},
+		{"example.com/missing", false, "", false, ""},
+		{"example.com/unrequired", false, "", false, ""},
+		{"example.com/app/internal", false, "", false, ""},
+		{"net/http", false, "", false, ""},
+	}
+	for _, tt := range tests {
+		src, ok := l.Locate(tt.importPath)
+		if ok != tt.found {
+			t.Errorf("Locate(%q) found = %v, want %v", tt.importPath, ok, tt.found)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if src.Dir != tt.dir || src.Vendored != tt.vendored || src.Version != tt.version {
+			t.Errorf("Locate(%q) = %+v, want dir %s vendored %v version %q", tt.importPath, src, tt.dir, tt.vendored, tt.version)
+		}
+	}
+}
+
+func TestNewGoSourceLocator_NoGoMod(t *testing.T) {
+	if _, err := NewGoSourceLocator(t.TempDir(), ""); err == nil {
+		t.Error("expected an error without go.mod")
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package dependencies
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Advisory is the subset of an OSV advisory used for matching.
+//
+// See https://ossf.github.io/osv-schema/ for the full schema.
+type Advisory struct {
+	ID       string     `json:"id"`
+	Summary  string     `json:"summary,omitempty"`
+	Aliases  []string   `json:"aliases,omitempty"`
+	Affected []Affected `json:"affected"`
+}
+
+// Affected is one package an advisory affects.
+type Affected struct {
+	Package  AffectedPackage `json:"package"`
+	Ranges   []AffectedRange `json:"ranges,omitempty"`
+	Versions []string        `json:"versions,omitempty"`
+}
+
+// AffectedPackage identifies a package by ecosystem and name.
+type AffectedPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+}
+
+// AffectedRange is a SEMVER, ECOSYSTEM or GIT range of affected versions.
+// GIT ranges are ignored.
+type AffectedRange struct {
+	Type   string       `json:"type"`
+	Events []RangeEvent `json:"events"`
+}
+
+// RangeEvent is one event of an affected range.
+type RangeEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// ParseAdvisories decodes OSV JSON: a single advisory, an array of them,
+// or an object with a "vulns" array as returned by the OSV query API.
+//
+// Outputs:
+//
+//	[]Advisory - The advisories.
+//	error - Non-nil if the input is not one of the accepted shapes.
+func ParseAdvisories(data []byte) ([]Advisory, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var list []Advisory
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, err
+		}
+		return list, nil
+	}
+
+	var wrapped struct {
+		Vulns []Advisory `json:"vulns"`
+		Advisory
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, err
+	}
+	if wrapped.Vulns != nil {
+		return wrapped.Vulns, nil
+	}
+	if wrapped.ID == "" {
+		return nil, fmt.Errorf("not an OSV advisory: missing id")
+	}
+	return []Advisory{wrapped.Advisory}, nil
+}
+
+// Affects reports whether the advisory affects a package version.
+//
+// Description:
+//
+//	Matches when the version is listed explicitly or falls in a SEMVER or
+//	ECOSYSTEM range. Versions are compared by numeric segments, which is
+//	exact for semver and a close approximation for PEP 440 and most npm
+//	versions.
+//
+// Inputs:
+//
+//	ecosystem - The dependency's ecosystem.
+//	name - The dependency's name.
+//	version - The resolved version. An empty version never matches.
+//
+// Outputs:
+//
+//	bool - True if the version is affected.
+//	[]string - The fixed versions of the matching ranges, if any.
+func (a *Advisory) Affects(ecosystem, name, version string) (bool, []string) {
+	if version == "" {
+		return false, nil
+	}
+	for _, aff := range a.Affected {
+		if aff.Package.Ecosystem != ecosystem || key(ecosystem, aff.Package.Name) != key(ecosystem, name) {
+			continue
+		}
+		for _, v := range aff.Versions {
+			if compareVersions(v, version) == 0 {
+				return true, fixedVersions(aff.Ranges)
+			}
+		}
+		for _, r := range aff.Ranges {
+			if r.Type == "GIT" {
+				continue
+			}
+			if inRange(r.Events, version) {
+				return true, fixedVersions(aff.Ranges)
+			}
+		}
+	}
+	return false, nil
+}
+
+// inRange evaluates OSV range events against a version.
+//
+// Events are applied in version order: the version is affected after an
+// "introduced" at or below it until a "fixed" at or below it, or a
+// "last_affected" below it.
+func inRange(events []RangeEvent, version string) bool {
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if e.Introduced == "0" || compareVersions(e.Introduced, version) <= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if compareVersions(e.Fixed, version) <= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if compareVersions(e.LastAffected, version) < 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// fixedVersions lists the fixed events of ranges.
+func fixedVersions(ranges []AffectedRange) []string {
+	var out []string
+	for _, r := range ranges {
+		for _, e := range r.Events {
+			if e.Fixed != "" {
+				out = append(out, e.Fixed)
+			}
+		}
+	}
+	return out
+}
+
+// compareVersions compares two versions by their dot-separated numeric
+// segments, ignoring a leading "v" and build metadata. A prerelease sorts
+// before its release.
+func compareVersions(a, b string) int {
+	aRel, aPre := splitVersion(a)
+	bRel, bPre := splitVersion(b)
+
+	aParts, bParts := strings.Split(aRel, "."), strings.Split(bRel, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var x, y string
+		if i < len(aParts) {
+			x = aParts[i]
+		}
+		if i < len(bParts) {
+			y = bParts[i]
+		}
+		if c := compareSegment(x, y); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	case aPre < bPre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// splitVersion separates the release from the prerelease of a version.
+func splitVersion(v string) (release, prerelease string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		return v[:i], v[i+1:]
+	}
+	return v, ""
+}
+
+// compareSegment compares two release segments numerically when both are
+// numbers. A missing segment counts as zero.
+func compareSegment(x, y string) int {
+	if x == "" {
+		x = "0"
+	}
+	if y == "" {
+		y = "0"
+	}
+	xn, xErr := strconv.ParseUint(x, 10, 64)
+	yn, yErr := strconv.ParseUint(y, 10, 64)
+	if xErr == nil && yErr == nil {
+		switch {
+		case xn < yn:
+			return -1
+		case xn > yn:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(x, y)
+}
+
+// Finding is a dependency affected by an advisory.
+type Finding struct {
+	Advisory   Advisory   `json:"advisory"`
+	Dependency Dependency `json:"dependency"`
+	Fixed      []string   `json:"fixed,omitempty"`
+}
+
+// Match returns the dependencies of s affected by the advisories.
+//
+// Outputs:
+//
+//	[]Finding - One finding per affected (advisory, dependency) pair, in
+//	  advisory order. Dependencies without a resolved version are not
+//	  matched.
+func (s *Set) Match(advisories []Advisory) []Finding {
+	var out []Finding
+	for _, adv := range advisories {
+		seen := make(map[string]bool)
+		for _, aff := range adv.Affected {
+			d, ok := s.Get(aff.Package.Ecosystem, aff.Package.Name)
+			k := key(d.Ecosystem, d.Name)
+			if !ok || seen[k] {
+				continue
+			}
+			if hit, fixed := adv.Affects(d.Ecosystem, d.Name, d.Version); hit {
+				seen[k] = true
+				out = append(out, Finding{Advisory: adv, Dependency: d, Fixed: fixed})
+			}
+		}
+	}
+	return out
+}
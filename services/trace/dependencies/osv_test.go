@@ -0,0 +1,114 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package dependencies
+
+import "testing"
+
+const lodashAdvisory = `{
+  "id": "GHSA-35jh-r3h4-6jhm",
+  "summary": "Command injection in lodash",
+  "aliases": ["CVE-2021-23337"],
+  "affected": [{
+    "package": {"ecosystem": "npm", "name": "lodash"},
+    "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+  }]
+}`
+
+func TestParseAdvisories_Shapes(t *testing.T) {
+	for name, input := range map[string]string{
+		"single": lodashAdvisory,
+		"array":  "[" + lodashAdvisory + "]",
+		"vulns":  `{"vulns": [` + lodashAdvisory + `]}`,
+	} {
+		advs, err := ParseAdvisories([]byte(input))
+		if err != nil || len(advs) != 1 || advs[0].ID != "GHSA-35jh-r3h4-6jhm" {
+			t.Errorf("%s: got %+v, %v", name, advs, err)
+		}
+	}
+	if _, err := ParseAdvisories([]byte(`{"summary": "no id"}`)); err == nil {
+		t.Error("expected error for advisory without id")
+	}
+}
+
+func TestAdvisory_Affects(t *testing.T) {
+	advs, err := ParseAdvisories([]byte(lodashAdvisory))
+	if err != nil {
+		t.Fatal(err)
+	}
+	adv := advs[0]
+
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"4.17.20", true},
+		{"3.10.1", true},
+		{"4.17.21", false},
+		{"4.18.0", false},
+		{"4.17.21-beta.1", true},
+		{"", false},
+	}
+	for _, tt := range tests {
+		got, fixed := adv.Affects(EcosystemNPM, "lodash", tt.version)
+		if got != tt.want {
+			t.Errorf("Affects(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+		if got && (len(fixed) != 1 || fixed[0] != "4.17.21") {
+			t.Errorf("Affects(%q) fixed = %v", tt.version, fixed)
+		}
+	}
+	if got, _ := adv.Affects(EcosystemPyPI, "lodash", "1.0.0"); got {
+		t.Error("matched across ecosystems")
+	}
+}
+
+func TestAdvisory_LastAffectedAndVersions(t *testing.T) {
+	adv := Advisory{ID: "X", Affected: []Affected{{
+		Package:  AffectedPackage{Ecosystem: EcosystemPyPI, Name: "PyYAML"},
+		Ranges:   []AffectedRange{{Type: "ECOSYSTEM", Events: []RangeEvent{{Introduced: "5.1"}, {LastAffected: "5.3.1"}}}},
+		Versions: []string{"4.2b1"},
+	}}}
+	for version, want := range map[string]bool{"5.0": false, "5.1": true, "5.3.1": true, "5.4": false, "4.2b1": true} {
+		if got, _ := adv.Affects(EcosystemPyPI, "pyyaml", version); got != want {
+			t.Errorf("Affects(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.2.3", "1.2.3", 0},
+		{"1.10.0", "1.9.0", 1},
+		{"1.2", "1.2.0", 0},
+		{"1.0.0-rc.1", "1.0.0", -1},
+		{"1.0.0+build", "1.0.0", 0},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSet_Match(t *testing.T) {
+	s := NewSet(
+		Dependency{Ecosystem: EcosystemNPM, Name: "lodash", Version: "4.17.15"},
+		Dependency{Ecosystem: EcosystemNPM, Name: "react", Version: "18.2.0"},
+	)
+	advs, _ := ParseAdvisories([]byte(lodashAdvisory))
+	findings := s.Match(advs)
+	if len(findings) != 1 || findings[0].Dependency.Name != "lodash" {
+		t.Fatalf("Match = %+v", findings)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package dependencies
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// exactVersion matches a pinned version such as "1.2.3" or "v1.2.3-rc.1".
+var exactVersion = regexp.MustCompile(`^v?\d+(\.\d+)*([-+][0-9A-Za-z.-]+)?$`)
+
+// parseGoMod reads require and replace directives from go.mod.
+//
+// A replacement by another module version changes the resolved version; a
+// replacement by a local directory leaves the version unknown.
+func parseGoMod(s *Set, data []byte) error {
+	f, err := modfile.Parse("go.mod", data, nil)
+	if err != nil {
+		return err
+	}
+	replaced := make(map[string]*modfile.Replace, len(f.Replace))
+	for _, r := range f.Replace {
+		replaced[r.Old.Path] = r
+	}
+	for _, r := range f.Require {
+		d := Dependency{
+			Ecosystem: EcosystemGo,
+			Name:      r.Mod.Path,
+			Version:   r.Mod.Version,
+			Direct:    !r.Indirect,
+			Source:    "go.mod",
+		}
+		if rep, ok := replaced[r.Mod.Path]; ok && (rep.Old.Version == "" || rep.Old.Version == r.Mod.Version) {
+			d.Version = rep.New.Version
+			if rep.New.Version == "" {
+				d.Constraint = "replaced by " + rep.New.Path
+			}
+		}
+		s.add(d)
+	}
+	return nil
+}
+
+// parseGoSum adds modules that go.mod does not list, at the highest
+// version go.sum records for them.
+func parseGoSum(s *Set, data []byte) error {
+	highest := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		path, version := fields[0], strings.TrimSuffix(fields[1], "/go.mod")
+		if _, listed := s.byKey[key(EcosystemGo, path)]; listed {
+			continue
+		}
+		if cur, ok := highest[path]; !ok || semver.Compare(version, cur) > 0 {
+			highest[path] = version
+		}
+	}
+	for path, version := range highest {
+		s.add(Dependency{Ecosystem: EcosystemGo, Name: path, Version: version, Source: "go.sum"})
+	}
+	return scanner.Err()
+}
+
+// parsePackageJSON reads the declared npm dependencies and their ranges.
+func parsePackageJSON(s *Set, data []byte) error {
+	var pkg struct {
+		Dependencies         map[string]string `json:"dependencies"`
+		DevDependencies      map[string]string `json:"devDependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+		PeerDependencies     map[string]string `json:"peerDependencies"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return err
+	}
+	for _, group := range []map[string]string{pkg.Dependencies, pkg.DevDependencies, pkg.OptionalDependencies, pkg.PeerDependencies} {
+		for name, constraint := range group {
+			d := Dependency{Ecosystem: EcosystemNPM, Name: name, Constraint: constraint, Direct: true, Source: "package.json"}
+			if exactVersion.MatchString(constraint) {
+				d.Version = strings.TrimPrefix(constraint, "v")
+			}
+			s.add(d)
+		}
+	}
+	return nil
+}
+
+// parsePackageLock reads resolved versions from package-lock.json.
+//
+// Lockfile v2 and v3 list packages by install path; a package installed
+// at several paths takes the version at the top-level node_modules. v1
+// lists top-level dependencies by name.
+func parsePackageLock(s *Set, data []byte) error {
+	var lock struct {
+		Packages map[string]struct {
+			Version string `json:"version"`
+		} `json:"packages"`
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return err
+	}
+
+	if len(lock.Packages) > 0 {
+		depth := make(map[string]int)
+		for path, p := range lock.Packages {
+			i := strings.LastIndex(path, "node_modules/")
+			if i < 0 || p.Version == "" {
+				continue
+			}
+			name := path[i+len("node_modules/"):]
+			nesting := strings.Count(path, "node_modules/")
+			if d, ok := depth[name]; ok && d <= nesting {
+				continue
+			}
+			depth[name] = nesting
+			s.add(Dependency{Ecosystem: EcosystemNPM, Name: name, Version: p.Version, Source: "package-lock.json"})
+		}
+		return nil
+	}
+	for name, p := range lock.Dependencies {
+		if p.Version != "" {
+			s.add(Dependency{Ecosystem: EcosystemNPM, Name: name, Version: p.Version, Source: "package-lock.json"})
+		}
+	}
+	return nil
+}
+
+// parseYarnLock reads resolved versions from yarn.lock, classic or berry.
+// A package locked at several versions takes the first one listed.
+func parseYarnLock(s *Set, data []byte) error {
+	seen := make(map[string]bool)
+	var current string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if !strings.HasPrefix(line, " ") {
+			current = yarnEntryName(line)
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if current == "" || seen[current] || !strings.HasPrefix(trimmed, "version") {
+			continue
+		}
+		version := strings.TrimSpace(strings.TrimPrefix(strings.TrimPrefix(trimmed, "version"), ":"))
+		version = strings.Trim(version, `"`)
+		if version == "" {
+			continue
+		}
+		seen[current] = true
+		s.add(Dependency{Ecosystem: EcosystemNPM, Name: current, Version: version, Source: "yarn.lock"})
+	}
+	return scanner.Err()
+}
+
+// yarnEntryName returns the package name of a yarn.lock entry header such
+// as `"@scope/pkg@^1.0.0", "@scope/pkg@^1.2.0":` or `lodash@npm:^4.17.21:`.
+func yarnEntryName(header string) string {
+	header = strings.TrimSuffix(strings.TrimSpace(header), ":")
+	spec := strings.Trim(strings.TrimSpace(strings.SplitN(header, ",", 2)[0]), `"`)
+	at := strings.LastIndexByte(spec, '@')
+	if at <= 0 {
+		return ""
+	}
+	return spec[:at]
+}
+
+// requirementLine splits a requirement into name, extras and constraint.
+var requirementLine = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*(.*)$`)
+
+// parseRequirements reads requirements.txt. Options, includes, editable
+// installs and URL or path requirements are skipped.
+func parseRequirements(s *Set, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		if i := strings.IndexByte(line, ';'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+		m := requirementLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		d := Dependency{Ecosystem: EcosystemPyPI, Name: m[1], Constraint: strings.TrimSpace(m[3]), Direct: true, Source: "requirements.txt"}
+		if v, ok := strings.CutPrefix(d.Constraint, "=="); ok && exactVersion.MatchString(strings.TrimSpace(v)) {
+			d.Version = strings.TrimSpace(v)
+		}
+		s.add(d)
+	}
+	return scanner.Err()
+}
+
+// parsePoetryLock reads the name and version of each [[package]] table in
+// poetry.lock.
+func parsePoetryLock(s *Set, data []byte) error {
+	var name, version string
+	inPackage := false
+	flush := func() {
+		if inPackage && name != "" && version != "" {
+			s.add(Dependency{Ecosystem: EcosystemPyPI, Name: name, Version: version, Source: "poetry.lock"})
+		}
+		name, version = "", ""
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			flush()
+			inPackage = line == "[[package]]"
+			continue
+		}
+		if !inPackage {
+			continue
+		}
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		v = strings.Trim(strings.TrimSpace(v), `"'`)
+		switch strings.TrimSpace(k) {
+		case "name":
+			name = v
+		case "version":
+			version = v
+		}
+	}
+	flush()
+	return scanner.Err()
+}
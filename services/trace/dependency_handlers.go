@@ -0,0 +1,153 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/dependencies"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/reason"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleAnalyzeDependencySurface handles POST /v1/trace/reason/dependency_surface.
+//
+// Description:
+//
+//	Lists the internal symbols that import or call into a third-party
+//	package, grouped by package and depth, with the package's resolved
+//	version when a manifest or lockfile declares it.
+//
+// Response:
+//
+//	200 OK: AgenticResponse with a reason.DependencySurfaceResult
+//	400 Bad Request: Missing package or unknown graph
+func (h *Handlers) HandleAnalyzeDependencySurface(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleAnalyzeDependencySurface")
+
+	var req AnalyzeDependencySurfaceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	cached, err := h.svc.GetGraph(req.GraphID)
+	if err != nil {
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
+		return
+	}
+
+	analyzer := reason.NewDependencySurfaceAnalyzer(cached.Graph, cached.Index)
+	result, err := analyzer.AnalyzeDependencySurface(c.Request.Context(), req.Package, reason.DependencySurfaceOptions{
+		MaxDepth: req.MaxDepth,
+	})
+	if errors.Is(err, reason.ErrInvalidInput) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "package must not be empty")
+		return
+	}
+	if err != nil {
+		logger.Error("Failed to analyze dependency surface", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to analyze dependency surface")
+		return
+	}
+
+	logger.Info("Analyzed dependency surface",
+		"package", req.Package,
+		"direct", result.DirectCount,
+		"transitive", result.TransitiveCount)
+	suppressed := suppressGenerated(cached, req.IncludeGenerated, result)
+	c.JSON(http.StatusOK, AgenticResponse{
+		Result:              result,
+		LatencyMs:           time.Since(start).Milliseconds(),
+		GeneratedSuppressed: suppressed,
+		Limitations:         result.Limitations,
+	})
+}
+
+// HandleDependencyAdvisories handles POST /v1/trace/deps/advisories.
+//
+// Description:
+//
+//	Matches OSV advisories supplied by the client against the resolved
+//	dependency versions of a graph and lists the code touching each
+//	affected dependency. Advisories are not fetched; the client passes
+//	them, e.g. from an offline OSV export.
+//
+// Response:
+//
+//	200 OK: AgenticResponse with a DependencyAdvisoriesResult
+//	400 Bad Request: Advisories are not OSV JSON, or unknown graph
+func (h *Handlers) HandleDependencyAdvisories(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDependencyAdvisories")
+
+	var req DependencyAdvisoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	advisories, err := dependencies.ParseAdvisories(req.Advisories)
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "advisories must be OSV JSON: "+err.Error())
+		return
+	}
+
+	cached, err := h.svc.GetGraph(req.GraphID)
+	if err != nil {
+		logger.Warn("Graph not found", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.New(http.StatusBadRequest, apierror.CodeGraphNotFound, err.Error()).
+			WithDetails("Ensure /init was called first").Write(c)
+		return
+	}
+	deps := cached.Dependencies
+	if deps == nil {
+		deps = dependencies.NewSet()
+	}
+
+	analyzer := reason.NewDependencySurfaceAnalyzer(cached.Graph, cached.Index)
+	exposures, err := analyzer.AnalyzeAdvisoryExposure(c.Request.Context(), deps.Match(advisories))
+	if err != nil {
+		logger.Error("Failed to analyze advisory exposure", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to analyze advisory exposure")
+		return
+	}
+
+	result := DependencyAdvisoriesResult{
+		Dependencies: deps.Len(),
+		Advisories:   len(advisories),
+		Exposures:    exposures,
+	}
+	var limitations []string
+	if deps.Len() == 0 {
+		limitations = append(limitations, "No manifests or lockfiles were found - nothing to match")
+	}
+
+	logger.Info("Matched advisories",
+		"advisories", len(advisories),
+		"exposures", len(exposures))
+	c.JSON(http.StatusOK, AgenticResponse{
+		Result:      result,
+		LatencyMs:   time.Since(start).Milliseconds(),
+		Limitations: limitations,
+	})
+}
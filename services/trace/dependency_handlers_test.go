@@ -0,0 +1,161 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// setupDependencyTestService caches a graph whose load() calls
+// yaml.safe_load, for a project pinning PyYAML 5.3 in requirements.txt.
+func setupDependencyTestService(t *testing.T) (*Service, string) {
+	t.Helper()
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "requirements.txt"), []byte("PyYAML==5.3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	g := graph.NewGraph(root)
+	load := &ast.Symbol{
+		ID: "app/config.py:3:load", Name: "load", Kind: ast.SymbolKindFunction,
+		FilePath: "app/config.py", StartLine: 3, EndLine: 8, Language: "python",
+	}
+	ext := &ast.Symbol{
+		ID: "external:yaml:yaml.safe_load", Name: "yaml.safe_load", Kind: ast.SymbolKindExternal,
+		Package: "yaml", Language: "external",
+	}
+	g.AddNode(load)
+	g.AddNode(ext)
+	g.AddEdge(load.ID, ext.ID, graph.EdgeTypeCalls, ast.Location{FilePath: "app/config.py", StartLine: 5})
+	g.Freeze()
+
+	svc := NewService(DefaultServiceConfig())
+	graphID := svc.generateGraphID(root)
+	svc.graphs[graphID] = &CachedGraph{
+		Graph:        g,
+		Index:        index.NewSymbolIndex(),
+		BuiltAtMilli: g.BuiltAtMilli,
+		ProjectRoot:  root,
+		Dependencies: recordDependencies(g, root, nil),
+	}
+	return svc, graphID
+}
+
+func TestHandlers_HandleAnalyzeDependencySurface(t *testing.T) {
+	svc, graphID := setupDependencyTestService(t)
+	router := setupTestRouter(svc)
+
+	w := postJSON(router, "/v1/trace/reason/dependency_surface",
+		`{"graph_id": "`+graphID+`", "package": "PyYAML"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Result struct {
+			Resolved []struct {
+				Version string `json:"version"`
+			} `json:"resolved"`
+			DirectCount int `json:"direct_count"`
+			Groups      []struct {
+				Package string `json:"package"`
+				Symbols []struct {
+					Name      string `json:"name"`
+					CallSites []struct {
+						Line int `json:"line"`
+					} `json:"call_sites"`
+				} `json:"symbols"`
+			} `json:"groups"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	r := resp.Result
+	if len(r.Resolved) != 1 || r.Resolved[0].Version != "5.3" {
+		t.Errorf("resolved = %+v", r.Resolved)
+	}
+	if r.DirectCount != 1 || r.Groups[0].Package != "app" || r.Groups[0].Symbols[0].CallSites[0].Line != 5 {
+		t.Errorf("result = %+v", r)
+	}
+}
+
+func TestHandlers_HandleAnalyzeDependencySurface_BadRequest(t *testing.T) {
+	svc, graphID := setupDependencyTestService(t)
+	router := setupTestRouter(svc)
+
+	if w := postJSON(router, "/v1/trace/reason/dependency_surface", `{"graph_id": "`+graphID+`"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("missing package: status = %d", w.Code)
+	}
+	if w := postJSON(router, "/v1/trace/reason/dependency_surface", `{"graph_id": "nope", "package": "x"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown graph: status = %d", w.Code)
+	}
+}
+
+func TestHandlers_HandleDependencyAdvisories(t *testing.T) {
+	svc, graphID := setupDependencyTestService(t)
+	router := setupTestRouter(svc)
+
+	advisories := `{"vulns": [
+		{"id": "GHSA-8q59-q68h-6hv4", "aliases": ["CVE-2020-14343"],
+		 "affected": [{"package": {"ecosystem": "PyPI", "name": "pyyaml"},
+		   "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "5.4"}]}]}]},
+		{"id": "GHSA-unrelated",
+		 "affected": [{"package": {"ecosystem": "npm", "name": "lodash"},
+		   "ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]}]}
+	]}`
+	w := postJSON(router, "/v1/trace/deps/advisories",
+		`{"graph_id": "`+graphID+`", "advisories": `+advisories+`}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Result DependencyAdvisoriesResult `json:"result"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	r := resp.Result
+	if r.Dependencies != 1 || r.Advisories != 2 || len(r.Exposures) != 1 {
+		t.Fatalf("result = %+v", r)
+	}
+	e := r.Exposures[0]
+	if e.AdvisoryID != "GHSA-8q59-q68h-6hv4" || e.Dependency.Version != "5.3" || e.Surface.DirectCount != 1 {
+		t.Errorf("exposure = %+v", e)
+	}
+}
+
+func TestHandlers_HandleDependencyAdvisories_InvalidOSV(t *testing.T) {
+	svc, graphID := setupDependencyTestService(t)
+	router := setupTestRouter(svc)
+
+	w := postJSON(router, "/v1/trace/deps/advisories", `{"graph_id": "`+graphID+`", "advisories": {"summary": "no id"}}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d", w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Code != "INVALID_PARAMETER" {
+		t.Errorf("code = %s", resp.Code)
+	}
+}
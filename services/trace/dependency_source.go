@@ -0,0 +1,244 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/dependencies"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// MaxDependencyDepth is the deepest import level resolve_dependencies
+// follows.
+const MaxDependencyDepth = 3
+
+// dependencyPathPrefix prefixes the file paths of dependency source read
+// from outside the project (module cache, local replacements), giving
+// "_deps/<import path>/<file>". Vendored files keep their real
+// "vendor/..." path. Either way the directory ends in the import path,
+// which Go call resolution matches against.
+const dependencyPathPrefix = "_deps/"
+
+// maxDependencyFileBytes skips dependency files larger than this, which
+// are almost always generated tables.
+const maxDependencyFileBytes = 1024 * 1024
+
+// dependencyStats summarizes the dependency source merged into a build.
+type dependencyStats struct {
+	// Packages is the number of dependency packages parsed.
+	Packages int
+
+	// Symbols is the number of symbols merged, children included.
+	Symbols int
+
+	// Truncated is true if MaxDependencySymbols stopped the walk.
+	Truncated bool
+
+	// Errors are non-fatal errors reading or parsing dependency files.
+	Errors []string
+}
+
+// resolveGoDependencies parses the source of the Go packages the project
+// imports.
+//
+// Description:
+//
+//	Starts from the external imports of the project's Go files (not the
+//	standard library, not the project's own module) and walks imports
+//	breadth-first up to discovery.DependencyDepth levels. Each package is
+//	located with dependencies.GoSourceLocator: vendor/ first, then the
+//	module cache, which is only read. Test files and files outside the
+//	build profile are skipped. Results are marked with their import depth
+//	(ast.ParseResult.MarkDependency); beyond depth 1 only exported
+//	symbols are kept. Once MaxDependencySymbols is reached, the remaining
+//	packages are left out and Truncated is set.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	projectRoot - The project root holding go.mod.
+//	results - The project's parse results.
+//	discovery - Supplies the depth and the build profile.
+//
+// Outputs:
+//
+//	[]*ast.ParseResult - Parse results of the dependency files.
+//	dependencyStats - What was merged. A missing or invalid go.mod is
+//	  reported in Errors, not as an error.
+//	error - Non-nil only if ctx is done.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) resolveGoDependencies(ctx context.Context, projectRoot string, results []*ast.ParseResult, discovery DiscoveryOptions) ([]*ast.ParseResult, dependencyStats, error) {
+	var stats dependencyStats
+
+	locator, err := dependencies.NewGoSourceLocator(projectRoot, "")
+	if err != nil {
+		stats.Errors = append(stats.Errors, fmt.Sprintf("resolve_dependencies: %v", err))
+		return nil, stats, nil
+	}
+
+	depth := discovery.DependencyDepth
+	if depth <= 0 {
+		depth = 1
+	}
+	if depth > MaxDependencyDepth {
+		depth = MaxDependencyDepth
+	}
+	limit := s.config.MaxDependencySymbols
+
+	seen := make(map[string]bool)
+	frontier := externalGoImports(locator, results, seen)
+
+	var out []*ast.ParseResult
+	for level := 1; level <= depth && len(frontier) > 0 && !stats.Truncated; level++ {
+		var next []*ast.ParseResult
+		for _, importPath := range frontier {
+			if err := ctx.Err(); err != nil {
+				return nil, stats, err
+			}
+			src, ok := locator.Locate(importPath)
+			if !ok {
+				continue
+			}
+			pkgResults := s.parseDependencyPackage(ctx, src, discovery, &stats)
+			if len(pkgResults) == 0 {
+				continue
+			}
+
+			symbols := 0
+			for _, pr := range pkgResults {
+				if level > 1 {
+					pr.Symbols = exportedSymbols(pr.Symbols)
+				}
+				pr.MarkDependency(level)
+				symbols += countSymbols(pr.Symbols)
+			}
+			if limit > 0 && stats.Symbols+symbols > limit {
+				stats.Truncated = true
+				break
+			}
+			stats.Packages++
+			stats.Symbols += symbols
+			out = append(out, pkgResults...)
+			next = append(next, pkgResults...)
+		}
+		frontier = externalGoImports(locator, next, seen)
+	}
+
+	slog.Info("Resolved dependency source",
+		telemetry.SensitiveLog("project_root", projectRoot),
+		slog.Int("depth", depth),
+		slog.Int("packages", stats.Packages),
+		slog.Int("symbols", stats.Symbols),
+		slog.Bool("truncated", stats.Truncated))
+	return out, stats, nil
+}
+
+// externalGoImports returns the external import paths of results not
+// already in seen, sorted, and adds them to seen.
+func externalGoImports(locator *dependencies.GoSourceLocator, results []*ast.ParseResult, seen map[string]bool) []string {
+	var paths []string
+	for _, pr := range results {
+		if pr == nil || pr.Language != "go" {
+			continue
+		}
+		for _, imp := range pr.Imports {
+			if seen[imp.Path] || !locator.IsExternal(imp.Path) {
+				continue
+			}
+			seen[imp.Path] = true
+			paths = append(paths, imp.Path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// parseDependencyPackage parses the non-test Go files of one dependency
+// package that match the build profile. Read and parse errors are added
+// to stats.Errors.
+func (s *Service) parseDependencyPackage(ctx context.Context, src dependencies.GoPackageSource, discovery DiscoveryOptions, stats *dependencyStats) []*ast.ParseResult {
+	entries, err := os.ReadDir(src.Dir)
+	if err != nil {
+		stats.Errors = append(stats.Errors, fmt.Sprintf("dependency %s: %v", src.ImportPath, err))
+		return nil
+	}
+
+	prefix := dependencyPathPrefix + src.ImportPath
+	if src.Vendored {
+		prefix = path.Join("vendor", src.ImportPath)
+	}
+
+	var results []*ast.ParseResult
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.Type().IsRegular() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		if info, err := entry.Info(); err != nil || info.Size() > maxDependencyFileBytes {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(src.Dir, name))
+		if err != nil {
+			stats.Errors = append(stats.Errors, fmt.Sprintf("dependency %s: %v", src.ImportPath, err))
+			continue
+		}
+		pr, err := s.parseContentToResult(ctx, content, path.Join(prefix, name))
+		if err != nil || pr == nil {
+			if err != nil {
+				stats.Errors = append(stats.Errors, fmt.Sprintf("dependency %s/%s: %v", src.ImportPath, name, err))
+			}
+			continue
+		}
+		results = append(results, pr)
+	}
+	return discovery.filterBuildProfile(results)
+}
+
+// exportedSymbols returns the exported symbols of syms, dropping
+// unexported children of the ones kept.
+func exportedSymbols(syms []*ast.Symbol) []*ast.Symbol {
+	kept := syms[:0]
+	for _, sym := range syms {
+		if sym == nil || !sym.Exported {
+			continue
+		}
+		if len(sym.Children) > 0 {
+			sym.Children = exportedSymbols(sym.Children)
+		}
+		kept = append(kept, sym)
+	}
+	return kept
+}
+
+// countSymbols counts syms and their children.
+func countSymbols(syms []*ast.Symbol) int {
+	n := 0
+	for _, sym := range syms {
+		if sym == nil {
+			continue
+		}
+		n++
+		if len(sym.Children) > 0 {
+			n += countSymbols(sym.Children)
+		}
+	}
+	return n
+}
@@ -0,0 +1,192 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// writeDependencyProject writes a module importing one vendored and one
+// module-cache package, the latter importing a second cached module, and
+// points GOMODCACHE at the cache.
+func writeDependencyProject(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	root := filepath.Join(dir, "app")
+	modCache := filepath.Join(dir, "mod")
+	t.Setenv("GOMODCACHE", modCache)
+
+	files := map[string]string{
+		filepath.Join(root, "go.mod"): `module example.com/app
+
+go 1.22
+
+require (
+	example.com/deep v1.0.0
+	example.com/lib v1.0.0
+	example.com/vend v0.1.0
+)
+`,
+		filepath.Join(root, "main.go"): `package main
+
+import (
+	"example.com/lib"
+	"example.com/vend"
+)
+
+func main() {
+	lib.Open()
+	vend.Run()
+}
+`,
+		filepath.Join(root, "vendor", "example.com", "vend", "vend.go"): `package vend
+
+func Run() {}
+`,
+		filepath.Join(modCache, "example.com", "lib@v1.0.0", "lib.go"): `package lib
+
+import "example.com/deep"
+
+func Open() {
+	deep.Dial()
+}
+`,
+		filepath.Join(modCache, "example.com", "lib@v1.0.0", "lib_test.go"): `package lib
+
+func TestOpen() {}
+`,
+		filepath.Join(modCache, "example.com", "deep@v1.0.0", "deep.go"): `package deep
+
+func Dial() {
+	dial()
+}
+
+func dial() {}
+`,
+	}
+	for path, content := range files {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// definedNodes returns the nodes named name that come from source,
+// skipping placeholders for unresolved calls.
+func definedNodes(cached *CachedGraph, name string) []*graph.Node {
+	var nodes []*graph.Node
+	for _, n := range cached.Graph.GetNodesByName(name) {
+		if n.Symbol != nil && n.Symbol.FilePath != "" && n.Symbol.Kind != ast.SymbolKindExternal {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
+func TestInit_ResolveDependencies(t *testing.T) {
+	ctx := context.Background()
+	root := writeDependencyProject(t)
+	svc := NewService(DefaultServiceConfig())
+
+	resp, err := svc.InitWithDiscovery(ctx, root, []string{"go"}, nil, DiscoveryOptions{
+		ResolveDependencies: true,
+		DependencyDepth:     2,
+	}, true)
+	if err != nil {
+		t.Fatalf("InitWithDiscovery failed: %v", err)
+	}
+	if resp.DependencyPackages != 3 {
+		t.Errorf("DependencyPackages = %d, want 3", resp.DependencyPackages)
+	}
+	if resp.DependenciesTruncated {
+		t.Error("DependenciesTruncated = true, want false")
+	}
+
+	callees, err := svc.FindCallees(ctx, resp.GraphID, "main", 10)
+	if err != nil {
+		t.Fatalf("FindCallees failed: %v", err)
+	}
+	files := make(map[string]int)
+	for _, c := range callees {
+		files[c.FilePath] = c.DependencyDepth
+	}
+	if depth, ok := files["_deps/example.com/lib/lib.go"]; !ok || depth != 1 {
+		t.Errorf("lib.Open: depth %d, found %v; callees %v", depth, ok, files)
+	}
+	if depth, ok := files["vendor/example.com/vend/vend.go"]; !ok || depth != 1 {
+		t.Errorf("vend.Run: depth %d, found %v; callees %v", depth, ok, files)
+	}
+
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph failed: %v", err)
+	}
+	if nodes := definedNodes(cached, "Dial"); len(nodes) != 1 || nodes[0].Symbol.DependencyDepth != 2 {
+		t.Errorf("expected Dial at depth 2, got %d nodes", len(nodes))
+	}
+	if nodes := definedNodes(cached, "dial"); len(nodes) != 0 {
+		t.Error("unexported dial should be dropped at depth 2")
+	}
+	if nodes := definedNodes(cached, "TestOpen"); len(nodes) != 0 {
+		t.Error("dependency test files should not be parsed")
+	}
+}
+
+func TestInit_ResolveDependencies_OffByDefault(t *testing.T) {
+	root := writeDependencyProject(t)
+	svc := NewService(DefaultServiceConfig())
+
+	resp, err := svc.InitWithDiscovery(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{}, true)
+	if err != nil {
+		t.Fatalf("InitWithDiscovery failed: %v", err)
+	}
+	if resp.DependencyPackages != 0 {
+		t.Errorf("DependencyPackages = %d, want 0", resp.DependencyPackages)
+	}
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph failed: %v", err)
+	}
+	if nodes := definedNodes(cached, "Run"); len(nodes) != 0 {
+		t.Error("dependency symbols merged without resolve_dependencies")
+	}
+}
+
+func TestInit_ResolveDependencies_SymbolCap(t *testing.T) {
+	root := writeDependencyProject(t)
+	cfg := DefaultServiceConfig()
+	cfg.MaxDependencySymbols = 1
+	svc := NewService(cfg)
+
+	resp, err := svc.InitWithDiscovery(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{
+		ResolveDependencies: true,
+		DependencyDepth:     MaxDependencyDepth,
+	}, true)
+	if err != nil {
+		t.Fatalf("InitWithDiscovery failed: %v", err)
+	}
+	if !resp.DependenciesTruncated {
+		t.Error("DependenciesTruncated = false, want true")
+	}
+	if resp.DependencySymbols > 1 {
+		t.Errorf("DependencySymbols = %d, want <= 1", resp.DependencySymbols)
+	}
+}
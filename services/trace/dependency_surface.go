@@ -0,0 +1,71 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/dependencies"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// recordDependencies loads the manifests and lockfiles of the project, or
+// of each workspace root, and records the resolved package of each
+// external node on g.
+//
+// Description:
+//
+//	Workspace roots are merged; when two roots declare the same package
+//	the first root's version is used. A file that fails to parse is
+//	logged and skipped; the other files still apply.
+//
+// Outputs:
+//
+//	*dependencies.Set - The declared dependencies. Never nil.
+func recordDependencies(g *graph.Graph, projectRoot string, ws *Workspace) *dependencies.Set {
+	load := func(root string) *dependencies.Set {
+		set, err := dependencies.Load(root)
+		if err != nil {
+			slog.Warn("Failed to parse some dependency files",
+				telemetry.SensitiveLog("root", root),
+				telemetry.SensitiveLogError("error", err),
+			)
+		}
+		return set
+	}
+
+	var set *dependencies.Set
+	if ws == nil {
+		set = load(projectRoot)
+	} else {
+		set = dependencies.NewSet()
+		for _, root := range ws.Roots {
+			set.Merge(load(root.Path))
+		}
+	}
+
+	graph.RecordExternalPackages(g, func(importPath, language string) (graph.ExternalPackage, bool) {
+		d, ok := set.Resolve(importPath, language)
+		if !ok {
+			return graph.ExternalPackage{}, false
+		}
+		return graph.ExternalPackage{Ecosystem: d.Ecosystem, Name: d.Name, Version: d.Version, Direct: d.Direct}, true
+	})
+	if set.Len() > 0 {
+		slog.Info("Loaded dependencies",
+			telemetry.SensitiveLogStrings("files", set.Files),
+			slog.Int("dependencies", set.Len()),
+			slog.Int("resolved_external_nodes", len(g.ExternalPackages)),
+		)
+	}
+	return set
+}
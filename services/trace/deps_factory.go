@@ -33,10 +33,14 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools/file"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools/project"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/projectsummary"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/rag"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/nats-io/nats.go"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
+	oteltrace "go.opentelemetry.io/otel/trace"
 )
 
 // coordinatorRegistry tracks coordinators by session ID for cleanup.
@@ -55,10 +59,12 @@ var persistenceRegistry = struct {
 	managers    map[string]*crs.PersistenceManager
 	journals    map[string]crs.Journal
 	projectKeys map[string]string // CRS-17: maps session ID -> checkpoint key (path hash)
+	runSpans    map[string]oteltrace.SpanContext
 }{
 	managers:    make(map[string]*crs.PersistenceManager),
 	journals:    make(map[string]crs.Journal),
 	projectKeys: make(map[string]string),
+	runSpans:    make(map[string]oteltrace.SpanContext),
 }
 
 // journalsByProject tracks journals by project key (checkpoint key).
@@ -132,9 +138,14 @@ func registerCoordinator(sessionID string, coord *integration.Coordinator) {
 // GR-36: Called when session restore infrastructure is created.
 // CRS-17: projectKey is the checkpoint key (path hash) used by SaveBackup for directory
 // addressing and metadata identity. Must not be empty when pm is non-nil.
-func registerPersistence(sessionID string, pm *crs.PersistenceManager, journal crs.Journal, projectKey string) {
+// runSpan is the session's run span; the checkpoint saved at cleanup records
+// it so a later restore can link back to this run.
+func registerPersistence(sessionID string, pm *crs.PersistenceManager, journal crs.Journal, projectKey string, runSpan oteltrace.SpanContext) {
 	persistenceRegistry.mu.Lock()
 	defer persistenceRegistry.mu.Unlock()
+	if runSpan.IsValid() {
+		persistenceRegistry.runSpans[sessionID] = runSpan
+	}
 	if pm != nil {
 		persistenceRegistry.managers[sessionID] = pm
 	}
@@ -162,7 +173,7 @@ func closeExistingJournalForProject(projectKey string) bool {
 		if err := existingJournal.Close(); err != nil {
 			slog.Warn("GR-36: Failed to close existing journal for project",
 				slog.String("project_key", projectKey),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 		}
 		delete(journalsByProject.journals, projectKey)
@@ -227,11 +238,14 @@ func cleanupPersistence(sessionID string) {
 		if pm != nil && journal != nil && projectKey != "" {
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
+			if runSpan, ok := persistenceRegistry.runSpans[sessionID]; ok {
+				ctx = oteltrace.ContextWithSpanContext(ctx, runSpan)
+			}
 			if _, err := pm.SaveBackup(ctx, projectKey, journal, nil); err != nil {
 				slog.Warn("CRS-PERSIST-01: Failed to save session checkpoint",
 					slog.String("session_id", sessionID),
 					slog.String("project_key", projectKey),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 			} else {
 				slog.Info("CRS-PERSIST-01: Session checkpoint saved",
@@ -246,7 +260,7 @@ func cleanupPersistence(sessionID string) {
 			if err := journal.Close(); err != nil {
 				slog.Warn("GR-36: Failed to close journal",
 					slog.String("session_id", sessionID),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 			} else {
 				slog.Debug("GR-36: Journal closed",
@@ -261,7 +275,7 @@ func cleanupPersistence(sessionID string) {
 			if err := pm.Close(); err != nil {
 				slog.Warn("GR-36: Failed to close persistence manager",
 					slog.String("session_id", sessionID),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 			} else {
 				slog.Debug("GR-36: Persistence manager closed",
@@ -271,6 +285,7 @@ func cleanupPersistence(sessionID string) {
 			delete(persistenceRegistry.managers, sessionID)
 		}
 		delete(persistenceRegistry.projectKeys, sessionID)
+		delete(persistenceRegistry.runSpans, sessionID)
 	}()
 
 	// Phase 2: Under journalsByProject lock — remove stale project entry.
@@ -360,6 +375,14 @@ type DefaultDependenciesFactory struct {
 	// instead of embedded BadgerDB. Falls back to in-memory BadgerJournal when nil.
 	natsJS     nats.JetStreamContext
 	natsStream string // JetStream stream name (default: "CRS_DELTAS")
+
+	// toolInterceptor wraps every per-session tool execution, for
+	// recording runs to fixtures and replaying them. Nil runs tools directly.
+	toolInterceptor tools.ExecutionInterceptor
+
+	// maxKnownFacts caps the project facts given to one run. Zero uses
+	// DefaultMaxInjectedFacts; negative gives none.
+	maxKnownFacts int
 }
 
 // DependenciesFactoryOption configures a DefaultDependenciesFactory.
@@ -598,6 +621,23 @@ func WithNATSJetStream(js nats.JetStreamContext, streamName string) Dependencies
 	}
 }
 
+// WithToolInterceptor routes every per-session tool execution through
+// interceptor (see replay.ToolInterceptor).
+func WithToolInterceptor(interceptor tools.ExecutionInterceptor) DependenciesFactoryOption {
+	return func(f *DefaultDependenciesFactory) {
+		f.toolInterceptor = interceptor
+	}
+}
+
+// WithMaxKnownFacts caps the project facts users confirmed in earlier
+// sessions that are given to one run. Zero uses DefaultMaxInjectedFacts;
+// negative gives none, but facts are still recorded.
+func WithMaxKnownFacts(n int) DependenciesFactoryOption {
+	return func(f *DefaultDependenciesFactory) {
+		f.maxKnownFacts = n
+	}
+}
+
 // Create implements agent.DependenciesFactory.
 //
 // Description:
@@ -644,6 +684,20 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 		graphID := session.GetGraphID()
 		if graphID != "" {
 			cached, err := f.service.GetGraph(graphID)
+			// Semantic indexing always covers the whole graph; the tools
+			// below see only the session's scope.
+			full := cached
+			if scope := session.GetScope(); err == nil && scope != "" {
+				cached, err = f.service.ScopedGraph(scope, graphID, cached)
+				if err != nil {
+					return nil, fmt.Errorf("restricting session to view %q: %w", scope, err)
+				}
+				slog.Info("Session restricted to graph view",
+					slog.String("session_id", session.ID),
+					slog.String("scope", scope),
+					slog.Int("nodes", cached.Graph.NodeCount()),
+				)
+			}
 			if err == nil && cached != nil {
 				slog.Info("Creating dependencies with graph",
 					slog.String("session_id", session.ID),
@@ -657,10 +711,11 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 					mgr, err := agentcontext.NewManager(cached.Graph, cached.Index, nil)
 					if err != nil {
 						slog.Warn("Failed to create ContextManager",
-							slog.String("error", err.Error()),
+							telemetry.SensitiveLogError("error", err),
 						)
 					} else {
-						deps.ContextManager = mgr
+						// Reviewed diffs may add files the graph lacks.
+						deps.ContextManager = mgr.WithParsers(f.service.registry)
 						slog.Info("ContextManager created",
 							slog.String("session_id", session.ID),
 						)
@@ -673,7 +728,7 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 					hg, err := graph.WrapGraph(cached.Graph)
 					if err != nil {
 						slog.Warn("CB-31d: Failed to wrap graph for analytics",
-							slog.String("error", err.Error()),
+							telemetry.SensitiveLogError("error", err),
 						)
 					} else {
 						// Create GraphAnalytics for symbol resolution
@@ -690,7 +745,7 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 							semantic, sErr := rag.NewSemanticResolver(f.weaviateClient, f.weaviateDataSpace, f.embedClient)
 							if sErr != nil {
 								slog.Warn("CRS-26j: Semantic resolver init failed, using structural-only",
-									slog.String("error", sErr.Error()),
+									telemetry.SensitiveLogError("error", sErr),
 								)
 								deps.RAGResolver = structural
 							} else {
@@ -702,13 +757,13 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 
 								// CRS-26l: Delegate symbol indexing to coordinator if available.
 								if f.indexingCoord != nil {
-									f.indexingCoord.TriggerIndexing(graphID, cached)
+									f.indexingCoord.TriggerIndexing(graphID, full)
 									if store := f.indexingCoord.GetSymbolStore(); store != nil {
 										deps.SymbolStore = store
 									}
 								} else {
 									// CRS-25: Legacy fallback — inline goroutine for indexing.
-									preHash := computeGraphContentHash(graphID, cached)
+									preHash := computeGraphContentHash(graphID, full)
 									go func() {
 										defer func() {
 											if r := recover(); r != nil {
@@ -734,13 +789,13 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 										defer cancel()
 										store, storeErr := rag.NewSymbolStore(f.weaviateClient, f.weaviateDataSpace, f.embedClient)
 										if storeErr != nil {
-											slog.Warn("CRS-25: Failed to create symbol store", slog.String("error", storeErr.Error()))
+											slog.Warn("CRS-25: Failed to create symbol store", telemetry.SensitiveLogError("error", storeErr))
 											return
 										}
 										deps.SymbolStore = store
 										hasHash, hashErr := store.HasGraphHash(indexCtx, preHash)
 										if hashErr != nil {
-											slog.Warn("CRS-25: Failed to check graph hash", slog.String("error", hashErr.Error()))
+											slog.Warn("CRS-25: Failed to check graph hash", telemetry.SensitiveLogError("error", hashErr))
 											return
 										}
 										if hasHash {
@@ -748,11 +803,11 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 											return
 										}
 										if delErr := store.DeleteAll(indexCtx); delErr != nil {
-											slog.Warn("CRS-25: Failed to delete stale symbols", slog.String("error", delErr.Error()))
+											slog.Warn("CRS-25: Failed to delete stale symbols", telemetry.SensitiveLogError("error", delErr))
 										}
-										count, idxErr := store.IndexSymbols(indexCtx, cached.Index, preHash, nil)
+										count, idxErr := store.IndexSymbols(indexCtx, full.Index, preHash, nil)
 										if idxErr != nil {
-											slog.Warn("CRS-25: Symbol indexing failed", slog.String("error", idxErr.Error()))
+											slog.Warn("CRS-25: Symbol indexing failed", telemetry.SensitiveLogError("error", idxErr))
 										} else {
 											slog.Info("CRS-25: Symbols indexed into Weaviate",
 												slog.Int("count", count),
@@ -784,6 +839,19 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 					}
 				}
 
+				if cached.Graph != nil {
+					deps.GraphQuality = cached.Graph.QualityReport
+				}
+				if summary, _, err := f.service.ProjectSummary(graphID, true); err == nil {
+					deps.ProjectSummary = summary.Render(projectsummary.DefaultMaxChars)
+				}
+				deps.Facts = serviceFactRecorder{svc: f.service, graphID: graphID}
+				maxFacts := f.maxKnownFacts
+				if maxFacts == 0 {
+					maxFacts = DefaultMaxInjectedFacts
+				}
+				deps.KnownFacts = f.service.KnownFacts(context.Background(), graphID, query, maxFacts)
+
 				// Create ToolRegistry if enabled
 				if f.enableTools && cached.Graph != nil && cached.Index != nil {
 					registry := tools.NewRegistry()
@@ -800,6 +868,17 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 						)
 					}
 
+					// The local semantic index, when configured, answers
+					// semantic_search in place of Weaviate
+					if f.service != nil {
+						if ix := f.service.SemanticIndex(); ix != nil {
+							registry.Register(tools.NewLocalSemanticSearchTool(ix, graphID, cached.Graph))
+							slog.Info("Local semantic search registered",
+								slog.String("session_id", session.ID),
+							)
+						}
+					}
+
 					// Register CB-30 file operation tools (Read, Write, Edit, Glob, Grep, Diff, Tree, JSON)
 					projectRoot := session.GetProjectRoot()
 					if projectRoot != "" {
@@ -807,12 +886,51 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 						file.RegisterFileTools(registry, fileConfig)
 						slog.Info("File tools registered",
 							slog.String("session_id", session.ID),
-							slog.String("project_root", projectRoot),
+							telemetry.SensitiveLog("project_root", projectRoot),
+						)
+					}
+
+					// Register the project's declarative tools (.trace/tools.yaml);
+					// names were checked against the built-ins at init
+					if len(cached.ProjectTools) > 0 {
+						project.RegisterProjectTools(registry, cached.ProjectTools, cached.ProjectRoot)
+						slog.Info("Project tools registered",
+							slog.String("session_id", session.ID),
+							slog.Int("tools", len(cached.ProjectTools)),
 						)
 					}
 
+					// Let the agent dry-run risky calls against the safety policy
+					if evaluator, ok := f.safetyGate.(safety.Evaluator); ok {
+						runMode := ""
+						if session.Config != nil {
+							runMode = session.Config.RunMode
+						}
+						registry.Register(tools.NewSafetyCheckTool(evaluator, runMode))
+					}
+
 					deps.ToolRegistry = registry
-					deps.ToolExecutor = tools.NewExecutor(registry, nil)
+					// Results depend on annotations as well as code, so share
+					// them under the same generation the service invalidates.
+					cacheGeneration := ""
+					if cached.ContentHash != "" {
+						cacheGeneration = graphGeneration(cached)
+					}
+					execOpts := []tools.ExecutorOption{
+						tools.WithSessionID(session.ID),
+						tools.WithExecutionPolicy(toolPolicyFromSessionConfig(session.Config)),
+						tools.WithResultCache(f.service.ToolResultCache(), cacheGeneration),
+						tools.WithStableIDResolver(cached.Graph.StableIDFor),
+						tools.WithWarningBanner(func() string { return graphWarningBanner(cached.Graph) }),
+						tools.WithArgumentGuard(tools.NewArgumentGuard(cached.Graph, cached.Index, projectRoot)),
+					}
+					if session.Config != nil && session.Config.BypassToolCache {
+						execOpts = append(execOpts, tools.WithCacheBypass(true))
+					}
+					if f.toolInterceptor != nil {
+						execOpts = append(execOpts, tools.WithExecutionInterceptor(f.toolInterceptor))
+					}
+					deps.ToolExecutor = tools.NewExecutorWithOptions(registry, nil, execOpts...)
 
 					// Mark graph_initialized requirement as satisfied since we have a valid graph
 					deps.ToolExecutor.SatisfyRequirement("graph_initialized")
@@ -840,7 +958,10 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 		if f.enableSessionRestore {
 			projectRoot := session.GetProjectRoot()
 			if projectRoot != "" {
-				restoreResult = f.trySessionRestore(session.ID, projectRoot, sessionCRS, deps)
+				restoreResult = f.trySessionRestore(session.ID, session.RunSpanContext(), projectRoot, sessionCRS, deps, f.sessionNodeIDMapper(session))
+			}
+			if restoreResult != nil && restoreResult.Restored {
+				session.AddRestoreOrigin(restoreResult.Origin)
 			}
 		}
 
@@ -867,13 +988,13 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 			if memErr == nil {
 				deps.Journal = memJournal
 				bridgeOpts = append(bridgeOpts, integration.WithJournal(memJournal))
-				registerPersistence(session.ID, nil, memJournal, "")
+				registerPersistence(session.ID, nil, memJournal, "", oteltrace.SpanContext{})
 				slog.Info("CRS-WIRE-01: In-memory journal wired to Bridge",
 					slog.String("session_id", session.ID),
 				)
 			} else {
 				slog.Debug("CRS-WIRE-01: In-memory journal creation failed, bridge runs without journal",
-					slog.String("error", memErr.Error()),
+					telemetry.SensitiveLogError("error", memErr),
 				)
 			}
 		}
@@ -922,16 +1043,35 @@ func (f *DefaultDependenciesFactory) Create(session *agent.Session, query string
 	return deps, nil
 }
 
+// sessionNodeIDMapper returns a mapper from node IDs recorded in an earlier
+// session to node IDs of the session's current graph, or nil if the
+// session has no graph. IDs that don't resolve are kept unchanged.
+func (f *DefaultDependenciesFactory) sessionNodeIDMapper(session *agent.Session) func(string) string {
+	if f.service == nil || session.GetGraphID() == "" {
+		return nil
+	}
+	cached, err := f.service.GetGraph(session.GetGraphID())
+	if err != nil || cached.Graph == nil {
+		return nil
+	}
+	return cached.ResolveSymbolID
+}
+
 // trySessionRestore attempts to restore CRS state from a previous session.
 //
 // GR-36: Integrates session restore with dependencies factory.
+// nodeIDMapper, if non-nil, moves restored state onto the current graph's
+// node IDs so it survives symbols moving between lines. runSpan, if valid,
+// parents the restore spans under the session's run.
 func (f *DefaultDependenciesFactory) trySessionRestore(
 	sessionID string,
+	runSpan oteltrace.SpanContext,
 	projectRoot string,
 	sessionCRS crs.CRS,
 	deps *phases.Dependencies,
+	nodeIDMapper func(string) string,
 ) *crs.RestoreResult {
-	ctx := context.Background()
+	ctx := oteltrace.ContextWithSpanContext(context.Background(), runSpan)
 
 	// Determine persistence base directory
 	baseDir := f.persistenceBaseDir
@@ -939,7 +1079,7 @@ func (f *DefaultDependenciesFactory) trySessionRestore(
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
 			slog.Warn("GR-36: Failed to get home directory for persistence",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return nil
 		}
@@ -958,7 +1098,7 @@ func (f *DefaultDependenciesFactory) trySessionRestore(
 	pm, err := crs.NewPersistenceManager(pmConfig)
 	if err != nil {
 		slog.Warn("GR-36: Failed to create persistence manager",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil
 	}
@@ -968,7 +1108,7 @@ func (f *DefaultDependenciesFactory) trySessionRestore(
 	sessionIdentifier, err := crs.NewSessionIdentifier(ctx, projectRoot)
 	if err != nil {
 		slog.Warn("GR-36: Failed to create session identifier",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil
 	}
@@ -994,7 +1134,7 @@ func (f *DefaultDependenciesFactory) trySessionRestore(
 		})
 		if natsErr != nil {
 			slog.Warn("CRS-27: Failed to create NATSJournal, falling back to BadgerDB",
-				slog.String("error", natsErr.Error()),
+				telemetry.SensitiveLogError("error", natsErr),
 			)
 		} else {
 			journal = natsJournal
@@ -1017,7 +1157,7 @@ func (f *DefaultDependenciesFactory) trySessionRestore(
 		badgerJournal, badgerErr := crs.NewBadgerJournal(journalConfig)
 		if badgerErr != nil {
 			slog.Warn("GR-36: Failed to create BadgerJournal",
-				slog.String("error", badgerErr.Error()),
+				telemetry.SensitiveLogError("error", badgerErr),
 			)
 			return nil
 		}
@@ -1029,20 +1169,22 @@ func (f *DefaultDependenciesFactory) trySessionRestore(
 	if natsJournal, ok := journal.(*crs.NATSJournal); ok {
 		if migErr := migrateFromBadgerIfNeeded(ctx, baseDir, projectKey, sessionID, natsJournal, pm); migErr != nil {
 			slog.Warn("CRS-27: Migration from BadgerDB failed (non-fatal)",
-				slog.String("error", migErr.Error()),
+				telemetry.SensitiveLogError("error", migErr),
 			)
 		}
 	}
 
 	// Register for cleanup when session ends (both session-based and project-based)
-	registerPersistence(sessionID, pm, journal, projectKey)
+	registerPersistence(sessionID, pm, journal, projectKey, runSpan)
 	registerJournalForProject(projectKey, sessionID, journal)
 
 	// Create restorer and attempt restore
-	restorer, err := crs.NewSessionRestorer(pm, nil)
+	restorerConfig := crs.DefaultSessionRestorerConfig()
+	restorerConfig.NodeIDMapper = nodeIDMapper
+	restorer, err := crs.NewSessionRestorer(pm, &restorerConfig)
 	if err != nil {
 		slog.Warn("GR-36: Failed to create session restorer",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil
 	}
@@ -1050,7 +1192,7 @@ func (f *DefaultDependenciesFactory) trySessionRestore(
 	result, err := restorer.TryRestore(ctx, sessionCRS, journal, sessionIdentifier)
 	if err != nil {
 		slog.Warn("GR-36: Session restore failed",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil
 	}
@@ -1058,7 +1200,7 @@ func (f *DefaultDependenciesFactory) trySessionRestore(
 	if result.Restored {
 		slog.Info("GR-36: Session restored from checkpoint",
 			slog.String("session_id", sessionID),
-			slog.String("project_root", projectRoot),
+			telemetry.SensitiveLog("project_root", projectRoot),
 			slog.Int64("generation", result.Generation),
 			slog.Duration("checkpoint_age", result.CheckpointAge),
 			slog.Int64("duration_ms", result.DurationMs),
@@ -1119,7 +1261,7 @@ func migrateFromBadgerIfNeeded(
 	latestBackup := filepath.Join(backupDir, "latest.backup.gz")
 	if _, err := os.Stat(latestBackup); os.IsNotExist(err) {
 		slog.Debug("CRS-27: No BadgerDB backup found, nothing to migrate",
-			slog.String("path", latestBackup),
+			telemetry.SensitiveLog("path", latestBackup),
 		)
 		return nil
 	}
@@ -1163,7 +1305,7 @@ func migrateFromBadgerIfNeeded(
 	migratedPath := latestBackup + ".migrated"
 	if renameErr := os.Rename(latestBackup, migratedPath); renameErr != nil {
 		slog.Warn("CRS-27: Failed to rename backup after migration (may re-migrate next time)",
-			slog.String("error", renameErr.Error()),
+			telemetry.SensitiveLogError("error", renameErr),
 		)
 	}
 
@@ -1177,3 +1319,36 @@ func migrateFromBadgerIfNeeded(
 
 // Ensure DefaultDependenciesFactory implements agent.DependenciesFactory.
 var _ agent.DependenciesFactory = (*DefaultDependenciesFactory)(nil)
+
+// toolPolicyFromSessionConfig builds the per-run tool execution policy override.
+//
+// Description:
+//
+//	Maps the session's ToolTimeoutsMs, MaxToolResultBytes and MaxConcurrentTools
+//	onto a ToolExecutionPolicy, converting the millisecond timeouts to
+//	durations. Zero fields leave the registry policy in effect.
+//
+// Inputs:
+//
+//	config - The session configuration. Nil yields an empty override.
+//
+// Outputs:
+//
+//	tools.ToolExecutionPolicy - The per-run override.
+func toolPolicyFromSessionConfig(config *agent.SessionConfig) tools.ToolExecutionPolicy {
+	if config == nil {
+		return tools.ToolExecutionPolicy{}
+	}
+	var timeouts map[string]time.Duration
+	if len(config.ToolTimeoutsMs) > 0 {
+		timeouts = make(map[string]time.Duration, len(config.ToolTimeoutsMs))
+		for name, ms := range config.ToolTimeoutsMs {
+			timeouts[name] = time.Duration(ms) * time.Millisecond
+		}
+	}
+	return tools.ToolExecutionPolicy{
+		ToolTimeouts:   timeouts,
+		MaxResultBytes: config.MaxToolResultBytes,
+		MaxConcurrent:  config.MaxConcurrentTools,
+	}
+}
@@ -11,7 +11,9 @@
 package trace
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/phases"
@@ -170,3 +172,18 @@ func TestCoordinatorRegistry_ConcurrentAccess(t *testing.T) {
 		<-done
 	}
 }
+
+func TestToolPolicyFromSessionConfig_TimeoutsInMilliseconds(t *testing.T) {
+	var config agent.SessionConfig
+	if err := json.Unmarshal([]byte(`{"tool_timeouts_ms": {"find_callers": 30000}}`), &config); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	policy := toolPolicyFromSessionConfig(&config)
+	if got := policy.ToolTimeouts["find_callers"]; got != 30*time.Second {
+		t.Errorf("find_callers timeout = %v, want 30s", got)
+	}
+	if policy := toolPolicyFromSessionConfig(&agent.SessionConfig{}); policy.ToolTimeouts != nil {
+		t.Errorf("empty config timeouts = %v, want nil", policy.ToolTimeouts)
+	}
+}
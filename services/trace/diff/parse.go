@@ -634,6 +634,8 @@ var languageMap = map[string]string{
 	".kt":       "kotlin",
 	".kts":      "kotlin",
 	".scala":    "scala",
+	".ex":       "elixir",
+	".exs":      "elixir",
 	".sh":       "bash",
 	".bash":     "bash",
 	".yaml":     "yaml",
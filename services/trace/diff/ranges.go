@@ -0,0 +1,152 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package diff
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	godiff "github.com/sourcegraph/go-diff/diff"
+)
+
+// =============================================================================
+// Changed Line Ranges
+// =============================================================================
+
+// LineRange is an inclusive range of 1-indexed line numbers.
+type LineRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Overlaps reports whether the range shares a line with [start, end].
+func (r LineRange) Overlaps(start, end int) bool {
+	return r.Start <= end && start <= r.End
+}
+
+// FileLines lists the lines a unified diff changes in one file.
+//
+// # Description
+//
+// Ranges are positions in the new version of the file, which is what a
+// graph built from the working tree indexes. A removal marks the lines on
+// either side of where the removed lines were, so the symbol they were
+// cut from is still found.
+type FileLines struct {
+	// Path is the file's path with any a/ or b/ prefix removed: the new
+	// path, or the old one for a deleted file.
+	Path string
+
+	// IsNew and IsDelete mark files created or deleted by the diff.
+	IsNew    bool
+	IsDelete bool
+
+	// Ranges are the changed new-side lines, sorted and merged. Empty for
+	// deleted files.
+	Ranges []LineRange
+
+	// NewContent is the new-side text the hunks carry: added and context
+	// lines, in order. For a new file it is the whole file.
+	NewContent string
+}
+
+// ChangedLines parses a multi-file unified diff into changed line ranges.
+//
+// # Description
+//
+// Accepts git diff output as well as plain "---"/"+++" diffs. Files whose
+// hunks carry no changes (mode or rename only) are omitted.
+//
+// # Inputs
+//
+//   - diffText: The unified diff text.
+//
+// # Outputs
+//
+//   - []FileLines: One entry per changed file, in diff order.
+//   - error: Non-nil if the diff is malformed.
+func ChangedLines(diffText string) ([]FileLines, error) {
+	fileDiffs, err := godiff.ParseMultiFileDiff([]byte(diffText))
+	if err != nil {
+		return nil, fmt.Errorf("parsing multi-file diff: %w", err)
+	}
+
+	var files []FileLines
+	for _, fd := range fileDiffs {
+		file := FileLines{
+			Path:     cleanDiffPath(fd.NewName),
+			IsNew:    fd.OrigName == "/dev/null",
+			IsDelete: fd.NewName == "/dev/null",
+		}
+		if file.IsDelete {
+			file.Path = cleanDiffPath(fd.OrigName)
+		}
+		if file.Path == "" || len(fd.Hunks) == 0 {
+			continue
+		}
+
+		var lines []int
+		var content strings.Builder
+		for _, h := range fd.Hunks {
+			// A run of removed lines not replaced by added ones marks the
+			// lines around the gap it leaves.
+			next, prev, removal := int(h.NewStartLine), LineContext, false
+			for _, line := range parseHunkBody(string(h.Body), int(h.OrigStartLine), int(h.NewStartLine)) {
+				switch line.Type {
+				case LineAdded:
+					lines = append(lines, line.NewNum)
+					next, removal = line.NewNum+1, false
+				case LineRemoved:
+					removal = removal || prev != LineAdded
+				default:
+					if removal {
+						lines = append(lines, next-1, next)
+					}
+					next, removal = line.NewNum+1, false
+				}
+				if line.Type != LineRemoved {
+					content.WriteString(line.Content)
+					content.WriteString("\n")
+				}
+				prev = line.Type
+			}
+			if removal {
+				lines = append(lines, next-1, next)
+			}
+		}
+		if !file.IsDelete {
+			file.Ranges = mergeLines(lines)
+		}
+		file.NewContent = content.String()
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+// mergeLines sorts line numbers and merges them into ranges of
+// consecutive lines, dropping numbers below 1.
+func mergeLines(lines []int) []LineRange {
+	sort.Ints(lines)
+	var ranges []LineRange
+	for _, n := range lines {
+		if n < 1 {
+			continue
+		}
+		if last := len(ranges) - 1; last >= 0 && n <= ranges[last].End+1 {
+			ranges[last].End = max(ranges[last].End, n)
+			continue
+		}
+		ranges = append(ranges, LineRange{Start: n, End: n})
+	}
+	return ranges
+}
@@ -0,0 +1,115 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangedLines(t *testing.T) {
+	diffText := `diff --git a/handler.go b/handler.go
+index 1111111..2222222 100644
+--- a/handler.go
++++ b/handler.go
+@@ -10,6 +10,7 @@ func Handle() {
+ 	a := 1
+-	b := 2
++	b := 3
++	c := 4
+ 	return
+ }
+
+@@ -40,4 +41,3 @@ func Other() {
+ 	x()
+-	y()
+ 	z()
+ }
+diff --git a/util.go b/util.go
+new file mode 100644
+--- /dev/null
++++ b/util.go
+@@ -0,0 +1,3 @@
++package main
++
++func helper() {}
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package main
+-func gone() {}
+`
+
+	files, err := ChangedLines(diffText)
+	if err != nil {
+		t.Fatalf("ChangedLines() error = %v", err)
+	}
+	if len(files) != 3 {
+		t.Fatalf("got %d files, want 3: %+v", len(files), files)
+	}
+
+	handler := files[0]
+	if handler.Path != "handler.go" || handler.IsNew || handler.IsDelete {
+		t.Errorf("handler.go = %+v", handler)
+	}
+	// Lines 11-12 were added; removing y() marks lines 41 and 42 around it.
+	wantRanges := []LineRange{{Start: 11, End: 12}, {Start: 41, End: 42}}
+	if !reflect.DeepEqual(handler.Ranges, wantRanges) {
+		t.Errorf("handler.go ranges = %v, want %v", handler.Ranges, wantRanges)
+	}
+
+	util := files[1]
+	if util.Path != "util.go" || !util.IsNew {
+		t.Errorf("util.go = %+v", util)
+	}
+	if !reflect.DeepEqual(util.Ranges, []LineRange{{Start: 1, End: 3}}) {
+		t.Errorf("util.go ranges = %v", util.Ranges)
+	}
+	if util.NewContent != "package main\n\nfunc helper() {}\n" {
+		t.Errorf("util.go content = %q", util.NewContent)
+	}
+
+	old := files[2]
+	if old.Path != "old.go" || !old.IsDelete || len(old.Ranges) != 0 {
+		t.Errorf("old.go = %+v", old)
+	}
+}
+
+func TestChangedLines_NotADiff(t *testing.T) {
+	files, err := ChangedLines("please review my change")
+	if err != nil {
+		t.Fatalf("ChangedLines() error = %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("got %d files from plain text, want 0", len(files))
+	}
+}
+
+func TestLineRange_Overlaps(t *testing.T) {
+	r := LineRange{Start: 10, End: 12}
+	tests := []struct {
+		start, end int
+		want       bool
+	}{
+		{1, 9, false},
+		{1, 10, true},
+		{11, 11, true},
+		{12, 20, true},
+		{13, 20, false},
+	}
+	for _, tt := range tests {
+		if got := r.Overlaps(tt.start, tt.end); got != tt.want {
+			t.Errorf("Overlaps(%d, %d) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,530 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package docenrich generates one-sentence summaries for undocumented
+// exported symbols with a cheap chat model and attaches them to graph
+// nodes as synthetic docs.
+package docenrich
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/egress"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// Defaults for zero Config fields.
+const (
+	DefaultRatePerMinute  = 30
+	DefaultMaxSymbols     = 500
+	DefaultMaxSourceBytes = 4000
+	DefaultCallTimeout    = time.Minute
+)
+
+// maxSummaryLen caps a summary in bytes; longer replies are cut at a word.
+const maxSummaryLen = 240
+
+// Run states reported in GraphStatus.State.
+const (
+	StateRunning   = "running"
+	StateComplete  = "complete"
+	StateCancelled = "cancelled"
+)
+
+var (
+	// ErrProviderNotAllowed indicates a cloud provider the egress config
+	// does not explicitly allow.
+	ErrProviderNotAllowed = errors.New("provider not allowed for doc enrichment")
+
+	// ErrEmptySummary indicates the model returned no usable sentence.
+	ErrEmptySummary = errors.New("empty summary")
+)
+
+// summaryPrompt is the system prompt of every summary request.
+const summaryPrompt = "You write API documentation. Given the source of one code symbol, " +
+	"reply with a single sentence of at most 25 words saying what it does or represents. " +
+	"Do not repeat the signature, do not use markdown, and do not add anything else."
+
+// Config configures a Pipeline.
+type Config struct {
+	// Provider is the provider behind the chat client (providers.ProviderOllama, ...).
+	Provider string
+
+	// Model is sent as ChatOptions.Model.
+	Model string
+
+	// RatePerMinute caps summary requests per minute across all graphs.
+	// <= 0 uses DefaultRatePerMinute.
+	RatePerMinute int
+
+	// MaxSymbols caps the symbols enqueued per graph. <= 0 uses
+	// DefaultMaxSymbols.
+	MaxSymbols int
+
+	// MaxSourceBytes caps the source sent per symbol. <= 0 uses
+	// DefaultMaxSourceBytes.
+	MaxSourceBytes int
+
+	// CallTimeout bounds each summary request. <= 0 uses DefaultCallTimeout.
+	CallTimeout time.Duration
+
+	// Egress decides whether a cloud Provider may be used. Nil allows
+	// local providers only.
+	Egress *egress.EgressConfig
+}
+
+// ProviderAllowed reports whether summaries may be generated with
+// provider. Ollama and replay never leave the host. A cloud provider is
+// allowed only when egress is enabled, not local-only, and the provider is
+// explicitly on the allowlist, off the denylist and consented; an empty
+// allowlist does not count.
+//
+// Thread Safety: Safe for concurrent use.
+func ProviderAllowed(provider string, cfg *egress.EgressConfig) bool {
+	switch provider {
+	case providers.ProviderOllama, providers.ProviderReplay:
+		return true
+	}
+	if cfg == nil || !cfg.Enabled || cfg.LocalOnly {
+		return false
+	}
+	return cfg.Allowlist[provider] && !cfg.Denylist[provider] && cfg.ProviderConsent[provider]
+}
+
+// GraphStatus is the progress of one graph's enrichment run.
+type GraphStatus struct {
+	// GraphID is the graph the run enriches.
+	GraphID string `json:"graph_id"`
+
+	// State is StateRunning, StateComplete or StateCancelled.
+	State string `json:"state"`
+
+	// Queued is the number of symbols enqueued.
+	Queued int `json:"queued"`
+
+	// QueueDepth is the number of symbols not yet processed.
+	QueueDepth int `json:"queue_depth"`
+
+	// Completed is the number of symbols that received a summary.
+	Completed int `json:"completed"`
+
+	// Generated is the part of Completed produced by the model.
+	Generated int `json:"generated"`
+
+	// FromStore is the part of Completed loaded from the Store.
+	FromStore int `json:"from_store"`
+
+	// Failed is the number of symbols whose source could not be read or
+	// whose summary request failed.
+	Failed int `json:"failed"`
+
+	// LastError is the most recent failure, if any.
+	LastError string `json:"last_error,omitempty"`
+
+	// StartedAt and FinishedAt are Unix milliseconds. FinishedAt is 0
+	// while running.
+	StartedAt  int64 `json:"started_at"`
+	FinishedAt int64 `json:"finished_at,omitempty"`
+}
+
+// Status is the state of a Pipeline, for GET /v1/trace/enrichment/status.
+type Status struct {
+	// Enabled is false when no pipeline is configured.
+	Enabled bool `json:"enabled"`
+
+	// Provider and Model generate the summaries.
+	Provider string `json:"provider,omitempty"`
+	Model    string `json:"model,omitempty"`
+
+	// RatePerMinute is the request cap.
+	RatePerMinute int `json:"rate_per_minute,omitempty"`
+
+	// QueueDepth sums QueueDepth over running graphs.
+	QueueDepth int `json:"queue_depth"`
+
+	// Queued, Completed and Failed sum the graphs' counters.
+	Queued    int `json:"queued"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+
+	// Graphs holds the latest run per graph, in GraphID order.
+	Graphs []GraphStatus `json:"graphs,omitempty"`
+}
+
+// run is one graph's enrichment run.
+type run struct {
+	cancel context.CancelFunc
+	status GraphStatus // guarded by Pipeline.mu
+}
+
+// Pipeline generates synthetic docs for graphs in the background.
+//
+// Description:
+//
+//	Trigger enqueues the undocumented exported symbols of a graph, most
+//	referenced first, and summarizes them one at a time under a shared
+//	rate limit. Summaries are looked up in and saved to the Store by
+//	ContentHash, so a rebuilt graph only pays for symbols whose code
+//	changed. Results are attached with graph.Node.SetSyntheticDoc.
+//
+// Thread Safety: Safe for concurrent use.
+type Pipeline struct {
+	client  providers.ChatClient
+	store   Store
+	cfg     Config
+	limiter *rate.Limiter
+	logger  *slog.Logger
+
+	mu         sync.Mutex
+	runs       map[string]*run
+	closed     bool
+	onComplete func(GraphStatus)
+}
+
+// New creates a pipeline.
+//
+// Inputs:
+//
+//	client - Chat client of the cheap (router or param-extractor) model. Must not be nil.
+//	store - Summary store. Nil uses a MemoryStore.
+//	cfg - Configuration. cfg.Provider must pass ProviderAllowed.
+//
+// Outputs:
+//
+//	*Pipeline - The pipeline, idle until Trigger.
+//	error - ErrProviderNotAllowed for a cloud provider the egress config does not allow.
+func New(client providers.ChatClient, store Store, cfg Config) (*Pipeline, error) {
+	if client == nil {
+		return nil, errors.New("docenrich: client must not be nil")
+	}
+	if !ProviderAllowed(cfg.Provider, cfg.Egress) {
+		return nil, fmt.Errorf("%w: %q is not explicitly allowed by the egress config", ErrProviderNotAllowed, cfg.Provider)
+	}
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	if cfg.RatePerMinute <= 0 {
+		cfg.RatePerMinute = DefaultRatePerMinute
+	}
+	if cfg.MaxSymbols <= 0 {
+		cfg.MaxSymbols = DefaultMaxSymbols
+	}
+	if cfg.MaxSourceBytes <= 0 {
+		cfg.MaxSourceBytes = DefaultMaxSourceBytes
+	}
+	if cfg.CallTimeout <= 0 {
+		cfg.CallTimeout = DefaultCallTimeout
+	}
+	return &Pipeline{
+		client:  client,
+		store:   store,
+		cfg:     cfg,
+		limiter: rate.NewLimiter(rate.Every(time.Minute/time.Duration(cfg.RatePerMinute)), 1),
+		logger:  slog.Default(),
+		runs:    make(map[string]*run),
+	}, nil
+}
+
+// Trigger starts enriching g in the background, cancelling any run still
+// in progress for graphID. g must be frozen.
+//
+// Thread Safety: Safe for concurrent use.
+func (p *Pipeline) Trigger(graphID string, g *graph.Graph) {
+	if g == nil {
+		return
+	}
+	queue := Candidates(g, p.cfg.MaxSymbols)
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return
+	}
+	if prev := p.runs[graphID]; prev != nil {
+		prev.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &run{
+		cancel: cancel,
+		status: GraphStatus{
+			GraphID:    graphID,
+			State:      StateRunning,
+			Queued:     len(queue),
+			QueueDepth: len(queue),
+			StartedAt:  time.Now().UnixMilli(),
+		},
+	}
+	p.runs[graphID] = r
+	p.mu.Unlock()
+
+	go p.process(ctx, r, g.ProjectRoot, queue)
+}
+
+// SetOnComplete sets a function called with the final status of every
+// run that completes without being cancelled. Nil clears it.
+//
+// Thread Safety: Safe for concurrent use.
+func (p *Pipeline) SetOnComplete(fn func(GraphStatus)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onComplete = fn
+}
+
+// Cancel stops the run in progress for graphID, if any.
+//
+// Thread Safety: Safe for concurrent use.
+func (p *Pipeline) Cancel(graphID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if r := p.runs[graphID]; r != nil {
+		r.cancel()
+	}
+}
+
+// Close cancels every run. Later Trigger calls do nothing.
+//
+// Thread Safety: Safe for concurrent use.
+func (p *Pipeline) Close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	for _, r := range p.runs {
+		r.cancel()
+	}
+}
+
+// Status returns a snapshot of the pipeline's progress.
+//
+// Thread Safety: Safe for concurrent use.
+func (p *Pipeline) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	st := Status{
+		Enabled:       true,
+		Provider:      p.cfg.Provider,
+		Model:         p.cfg.Model,
+		RatePerMinute: p.cfg.RatePerMinute,
+		Graphs:        make([]GraphStatus, 0, len(p.runs)),
+	}
+	for _, r := range p.runs {
+		gs := r.status
+		if gs.State == StateRunning {
+			st.QueueDepth += gs.QueueDepth
+		}
+		st.Queued += gs.Queued
+		st.Completed += gs.Completed
+		st.Failed += gs.Failed
+		st.Graphs = append(st.Graphs, gs)
+	}
+	slices.SortFunc(st.Graphs, func(a, b GraphStatus) int { return cmp.Compare(a.GraphID, b.GraphID) })
+	return st
+}
+
+// Candidates returns the nodes of g to summarize, at most limit of them:
+// exported, non-generated functions, methods and types without a doc
+// comment or synthetic doc, by descending in-degree and then ID.
+//
+// Thread Safety: Safe for concurrent use on a frozen graph.
+func Candidates(g *graph.Graph, limit int) []*graph.Node {
+	var nodes []*graph.Node
+	for _, n := range g.Nodes() {
+		sym := n.Symbol
+		if sym == nil || !sym.Exported || sym.Generated || !summarizable(sym.Kind) {
+			continue
+		}
+		if strings.TrimSpace(sym.DocComment) != "" || n.SyntheticDoc() != "" {
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	slices.SortFunc(nodes, func(a, b *graph.Node) int {
+		if c := cmp.Compare(len(b.Incoming), len(a.Incoming)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	if limit > 0 && len(nodes) > limit {
+		nodes = nodes[:limit]
+	}
+	return nodes
+}
+
+// summarizable reports whether symbols of kind get a summary.
+func summarizable(kind ast.SymbolKind) bool {
+	switch kind {
+	case ast.SymbolKindFunction, ast.SymbolKindMethod, ast.SymbolKindInterface,
+		ast.SymbolKindStruct, ast.SymbolKindType, ast.SymbolKindClass,
+		ast.SymbolKindEnum, ast.SymbolKindComponent:
+		return true
+	}
+	return false
+}
+
+// process summarizes queue in order until done or ctx is cancelled.
+func (p *Pipeline) process(ctx context.Context, r *run, projectRoot string, queue []*graph.Node) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			p.logger.Error("Panic in doc enrichment goroutine", slog.Any("panic", rec))
+			p.finish(r, StateCancelled)
+		}
+	}()
+	defer r.cancel()
+
+	for i, n := range queue {
+		if ctx.Err() != nil {
+			p.finish(r, StateCancelled)
+			return
+		}
+		fromStore, err := p.summarize(ctx, projectRoot, n)
+		if err != nil && ctx.Err() != nil {
+			p.finish(r, StateCancelled)
+			return
+		}
+
+		p.mu.Lock()
+		r.status.QueueDepth = len(queue) - i - 1
+		switch {
+		case err != nil:
+			r.status.Failed++
+			r.status.LastError = err.Error()
+		case fromStore:
+			r.status.Completed++
+			r.status.FromStore++
+		default:
+			r.status.Completed++
+			r.status.Generated++
+		}
+		p.mu.Unlock()
+
+		if err != nil {
+			p.logger.Debug("Doc summary failed",
+				telemetry.SensitiveLog("symbol", n.ID), telemetry.SensitiveLogError("error", err))
+		}
+	}
+	p.finish(r, StateComplete)
+
+	p.mu.Lock()
+	gs, onComplete := r.status, p.onComplete
+	p.mu.Unlock()
+	p.logger.Info("Doc enrichment complete",
+		slog.String("graph_id", gs.GraphID),
+		slog.Int("generated", gs.Generated),
+		slog.Int("from_store", gs.FromStore),
+		slog.Int("failed", gs.Failed))
+	if onComplete != nil {
+		onComplete(gs)
+	}
+}
+
+// finish records the end of r.
+func (p *Pipeline) finish(r *run, state string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r.status.State = state
+	r.status.FinishedAt = time.Now().UnixMilli()
+}
+
+// summarize attaches a summary to n, from the store if one is saved for
+// the symbol's current content and from the model otherwise.
+//
+// Outputs:
+//
+//	bool - True if the summary came from the store.
+//	error - Non-nil if the source could not be read or no summary was produced.
+func (p *Pipeline) summarize(ctx context.Context, projectRoot string, n *graph.Node) (bool, error) {
+	src, err := graph.ReadSymbolSource(projectRoot, n.Symbol, 0, p.cfg.MaxSourceBytes)
+	if err != nil {
+		return false, fmt.Errorf("reading %s: %w", n.ID, err)
+	}
+	hash := ContentHash(n.Symbol, src.Source)
+
+	summary, ok, err := p.store.Load(ctx, hash)
+	if err != nil {
+		p.logger.Warn("Doc summary store load failed", telemetry.SensitiveLogError("error", err))
+	}
+	if ok {
+		n.SetSyntheticDoc(summary)
+		return true, nil
+	}
+
+	summary, err = p.generate(ctx, n.Symbol, src.Source)
+	if err != nil {
+		return false, err
+	}
+	if err := p.store.Save(ctx, hash, summary); err != nil {
+		p.logger.Warn("Doc summary store save failed", telemetry.SensitiveLogError("error", err))
+	}
+	n.SetSyntheticDoc(summary)
+	return false, nil
+}
+
+// generate asks the model for a one-sentence summary of sym, waiting for
+// the rate limiter first.
+func (p *Pipeline) generate(ctx context.Context, sym *ast.Symbol, source string) (string, error) {
+	if err := p.limiter.Wait(ctx); err != nil {
+		return "", err
+	}
+	callCtx, cancel := context.WithTimeout(ctx, p.cfg.CallTimeout)
+	defer cancel()
+
+	prompt := fmt.Sprintf("Summarize the %s %s %q in one sentence.\n\n```\n%s\n```",
+		sym.Language, sym.Kind, sym.Name, source)
+	reply, err := p.client.Chat(callCtx, []providers.Message{
+		{Role: "system", Content: summaryPrompt},
+		{Role: "user", Content: prompt},
+	}, providers.ChatOptions{Temperature: 0, MaxTokens: 96, Model: p.cfg.Model})
+	if err != nil {
+		return "", fmt.Errorf("summarizing %s: %w", sym.ID, err)
+	}
+	summary := firstSentence(reply)
+	if summary == "" {
+		return "", fmt.Errorf("%w for %s", ErrEmptySummary, sym.ID)
+	}
+	return summary, nil
+}
+
+// firstSentence reduces a model reply to its first sentence: the first
+// non-empty line without markdown quoting, cut after the first ". " and
+// at maxSummaryLen bytes.
+func firstSentence(reply string) string {
+	var line string
+	for _, l := range strings.Split(reply, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			line = l
+			break
+		}
+	}
+	line = strings.Trim(line, "\"'`*> ")
+	line = strings.Join(strings.Fields(line), " ")
+	if i := strings.Index(line, ". "); i >= 0 {
+		line = line[:i+1]
+	}
+	if len(line) > maxSummaryLen {
+		cut := strings.LastIndex(line[:maxSummaryLen], " ")
+		if cut <= 0 {
+			cut = maxSummaryLen
+		}
+		line = strings.TrimRight(line[:cut], ",;:") + "…"
+	}
+	return line
+}
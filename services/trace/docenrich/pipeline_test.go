@@ -0,0 +1,231 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package docenrich
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/egress"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// fakeChat answers every request with reply and records the symbols asked about.
+type fakeChat struct {
+	mu    sync.Mutex
+	reply string
+	block chan struct{}
+	asked []string
+}
+
+func (f *fakeChat) Chat(ctx context.Context, messages []providers.Message, _ providers.ChatOptions) (string, error) {
+	if f.block != nil {
+		select {
+		case <-f.block:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.asked = append(f.asked, messages[len(messages)-1].Content)
+	return f.reply, nil
+}
+
+func (f *fakeChat) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.asked)
+}
+
+const testSource = `package svc
+
+func Helper() {}
+
+// Documented does something.
+func Documented() {}
+
+func Popular() {}
+
+func private() {}
+`
+
+// testGraph builds a frozen graph over testSource in which Popular has
+// two callers and Helper one.
+func testGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "svc.go"), []byte(testSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := graph.NewGraph(root)
+	for _, sym := range []*ast.Symbol{
+		{ID: "svc.go:3:Helper", Name: "Helper", Kind: ast.SymbolKindFunction, FilePath: "svc.go", StartLine: 3, EndLine: 3, Language: "go", Exported: true},
+		{ID: "svc.go:6:Documented", Name: "Documented", Kind: ast.SymbolKindFunction, FilePath: "svc.go", StartLine: 6, EndLine: 6, Language: "go", Exported: true, DocComment: "Documented does something."},
+		{ID: "svc.go:8:Popular", Name: "Popular", Kind: ast.SymbolKindFunction, FilePath: "svc.go", StartLine: 8, EndLine: 8, Language: "go", Exported: true},
+		{ID: "svc.go:10:private", Name: "private", Kind: ast.SymbolKindFunction, FilePath: "svc.go", StartLine: 10, EndLine: 10, Language: "go"},
+	} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range [][2]string{
+		{"svc.go:10:private", "svc.go:8:Popular"},
+		{"svc.go:6:Documented", "svc.go:8:Popular"},
+		{"svc.go:10:private", "svc.go:3:Helper"},
+	} {
+		if err := g.AddEdge(e[0], e[1], graph.EdgeTypeCalls, ast.Location{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+func waitState(t *testing.T, p *Pipeline, graphID, state string) GraphStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, gs := range p.Status().Graphs {
+			if gs.GraphID == graphID && gs.State == state {
+				return gs
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("graph %s never reached state %s: %+v", graphID, state, p.Status())
+	return GraphStatus{}
+}
+
+func syntheticDoc(t *testing.T, g *graph.Graph, id string) string {
+	t.Helper()
+	n, ok := g.GetNode(id)
+	if !ok {
+		t.Fatalf("no node %s", id)
+	}
+	return n.SyntheticDoc()
+}
+
+func TestPipeline_SummarizesUndocumentedExportedSymbols(t *testing.T) {
+	chat := &fakeChat{reply: "\n\"Runs the popular path. It also logs.\"\n"}
+	store := NewMemoryStore()
+	p, err := New(chat, store, Config{Provider: providers.ProviderOllama, RatePerMinute: 60_000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	g := testGraph(t)
+	if got := Candidates(g, 0); len(got) != 2 || got[0].Symbol.Name != "Popular" || got[1].Symbol.Name != "Helper" {
+		t.Fatalf("candidates not ordered by in-degree: %v", got)
+	}
+
+	p.Trigger("g1", g)
+	gs := waitState(t, p, "g1", StateComplete)
+	if gs.Queued != 2 || gs.Completed != 2 || gs.Generated != 2 || gs.QueueDepth != 0 || gs.Failed != 0 {
+		t.Errorf("status = %+v", gs)
+	}
+	if doc := syntheticDoc(t, g, "svc.go:8:Popular"); doc != "Runs the popular path." {
+		t.Errorf("Popular synthetic doc = %q", doc)
+	}
+	if doc := syntheticDoc(t, g, "svc.go:6:Documented"); doc != "" {
+		t.Errorf("documented symbol got synthetic doc %q", doc)
+	}
+	if doc := syntheticDoc(t, g, "svc.go:10:private"); doc != "" {
+		t.Errorf("unexported symbol got synthetic doc %q", doc)
+	}
+	if !strings.Contains(chat.asked[0], "Popular") {
+		t.Errorf("first request was not for the most referenced symbol: %q", chat.asked[0])
+	}
+
+	// A rebuilt graph of the same code is served from the store.
+	rebuilt := testGraph(t)
+	p.Trigger("g1", rebuilt)
+	gs = waitState(t, p, "g1", StateComplete)
+	if gs.FromStore != 2 || gs.Generated != 0 || chat.calls() != 2 {
+		t.Errorf("rebuild: status = %+v, model calls = %d", gs, chat.calls())
+	}
+	if doc := syntheticDoc(t, rebuilt, "svc.go:3:Helper"); doc == "" {
+		t.Error("rebuilt Helper has no synthetic doc")
+	}
+}
+
+func TestPipeline_Cancel(t *testing.T) {
+	chat := &fakeChat{reply: "Does things.", block: make(chan struct{})}
+	p, err := New(chat, nil, Config{Provider: providers.ProviderOllama, RatePerMinute: 60_000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	g := testGraph(t)
+	p.Trigger("g1", g)
+	if st := p.Status(); st.QueueDepth != 2 {
+		t.Errorf("queue depth = %d, want 2", st.QueueDepth)
+	}
+	p.Cancel("g1")
+	gs := waitState(t, p, "g1", StateCancelled)
+	if gs.Completed != 0 || gs.Failed != 0 {
+		t.Errorf("cancelled run counted work: %+v", gs)
+	}
+	if st := p.Status(); st.QueueDepth != 0 {
+		t.Errorf("cancelled run still queued: %d", st.QueueDepth)
+	}
+}
+
+func TestProviderAllowed(t *testing.T) {
+	allowed := &egress.EgressConfig{
+		Enabled:         true,
+		Allowlist:       map[string]bool{providers.ProviderAnthropic: true},
+		ProviderConsent: map[string]bool{providers.ProviderAnthropic: true},
+	}
+	if !ProviderAllowed(providers.ProviderOllama, nil) {
+		t.Error("ollama rejected")
+	}
+	if !ProviderAllowed(providers.ProviderAnthropic, allowed) {
+		t.Error("allowlisted, consented provider rejected")
+	}
+	for name, cfg := range map[string]*egress.EgressConfig{
+		"nil config":      nil,
+		"empty allowlist": {Enabled: true, ProviderConsent: allowed.ProviderConsent},
+		"no consent":      {Enabled: true, Allowlist: allowed.Allowlist},
+		"local only":      {Enabled: true, LocalOnly: true, Allowlist: allowed.Allowlist, ProviderConsent: allowed.ProviderConsent},
+		"denylisted":      {Enabled: true, Allowlist: allowed.Allowlist, Denylist: allowed.Allowlist, ProviderConsent: allowed.ProviderConsent},
+	} {
+		if ProviderAllowed(providers.ProviderAnthropic, cfg) {
+			t.Errorf("%s: cloud provider allowed", name)
+		}
+	}
+	if _, err := New(&fakeChat{}, nil, Config{Provider: providers.ProviderOpenAI}); !errors.Is(err, ErrProviderNotAllowed) {
+		t.Errorf("New with cloud provider: err = %v, want ErrProviderNotAllowed", err)
+	}
+}
+
+func TestFirstSentence(t *testing.T) {
+	for in, want := range map[string]string{
+		"Parses the config. Returns errors.": "Parses the config.",
+		"  \n> `Loads   users`\n":            "Loads users",
+		"":                                   "",
+		strings.Repeat("word ", 100):         strings.TrimSpace(strings.Repeat("word ", 48)) + "…",
+	} {
+		if got := firstSentence(in); got != want {
+			t.Errorf("firstSentence(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package docenrich
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	dgbadger "github.com/dgraph-io/badger/v4"
+)
+
+const (
+	// summaryKeyPrefix namespaces summary keys in a shared BadgerDB.
+	summaryKeyPrefix = "docsum:"
+
+	// summaryDefaultTTL is how long a persisted summary is kept.
+	summaryDefaultTTL = 90 * 24 * time.Hour
+)
+
+// Store persists generated summaries keyed by symbol content hash
+// (ContentHash), so unchanged symbols keep their summary across rebuilds.
+type Store interface {
+	// Load returns the summary stored for hash. ok is false if there is none.
+	Load(ctx context.Context, hash string) (summary string, ok bool, err error)
+
+	// Save stores summary for hash, replacing any previous one.
+	Save(ctx context.Context, hash, summary string) error
+}
+
+// ContentHash returns the key a symbol's summary is stored under: a hex
+// SHA-256 of the symbol's language, kind, package, receiver, name,
+// signature and source text. Moving a symbol to another file or line keeps
+// the hash; editing it changes the hash.
+//
+// Thread Safety: Safe for concurrent use.
+func ContentHash(sym *ast.Symbol, source string) string {
+	h := sha256.New()
+	for _, part := range []string{
+		sym.Language, sym.Kind.String(), sym.Package, sym.Receiver, sym.Name, sym.Signature, source,
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryStore is a Store that keeps summaries in memory only.
+//
+// Thread Safety: Safe for concurrent use.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	summaries map[string]string
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{summaries: make(map[string]string)}
+}
+
+// Load implements Store.
+func (s *MemoryStore) Load(_ context.Context, hash string) (string, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	summary, ok := s.summaries[hash]
+	return summary, ok, nil
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, hash, summary string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.summaries[hash] = summary
+	return nil
+}
+
+// BadgerStore is a Store backed by BadgerDB.
+//
+// Description:
+//
+//	Summaries expire through BadgerDB's native TTL so entries for code
+//	that no longer exists are eventually dropped. The DB is owned by the
+//	caller and must stay open while the store is in use.
+//
+// Thread Safety: Safe for concurrent use.
+type BadgerStore struct {
+	db  *badgerstore.DB
+	ttl time.Duration
+}
+
+// NewBadgerStore creates a store backed by db.
+//
+// Inputs:
+//
+//	db - Opened BadgerDB wrapper. Must not be nil.
+//	ttl - Lifetime of each summary. <= 0 uses the default (90 days).
+//
+// Outputs:
+//
+//	*BadgerStore - The store. Never nil.
+func NewBadgerStore(db *badgerstore.DB, ttl time.Duration) *BadgerStore {
+	if db == nil {
+		panic("NewBadgerStore: db must not be nil")
+	}
+	if ttl <= 0 {
+		ttl = summaryDefaultTTL
+	}
+	return &BadgerStore{db: db, ttl: ttl}
+}
+
+// Load implements Store.
+func (s *BadgerStore) Load(ctx context.Context, hash string) (string, bool, error) {
+	var value []byte
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		item, err := txn.Get([]byte(summaryKeyPrefix + hash))
+		if err != nil {
+			return err
+		}
+		value, err = item.ValueCopy(nil)
+		return err
+	})
+	if errors.Is(err, dgbadger.ErrKeyNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("doc summary load: %w", err)
+	}
+	return string(value), true, nil
+}
+
+// Save implements Store.
+func (s *BadgerStore) Save(ctx context.Context, hash, summary string) error {
+	err := s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.SetEntry(dgbadger.NewEntry([]byte(summaryKeyPrefix+hash), []byte(summary)).WithTTL(s.ttl))
+	})
+	if err != nil {
+		return fmt.Errorf("doc summary save: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"fmt"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// maxSymbolDebugEdges caps the edges listed for GET /symbol/:id?debug=true.
+const maxSymbolDebugEdges = 200
+
+// edgeProvenance returns the edge's provenance for an API response, or nil
+// if none was recorded.
+func edgeProvenance(edge *graph.Edge) *graph.EdgeProvenance {
+	if edge.Provenance.IsZero() {
+		return nil
+	}
+	prov := edge.Provenance
+	return &prov
+}
+
+// edgeDebugInfo converts an edge for a debug response.
+func edgeDebugInfo(edge *graph.Edge) EdgeDebugInfo {
+	return EdgeDebugInfo{
+		FromID:     edge.FromID,
+		ToID:       edge.ToID,
+		Type:       edge.Type.String(),
+		Location:   edge.Location,
+		Provenance: edge.Provenance,
+	}
+}
+
+// AnnotateCallEdges attaches the CALLS edges behind callers or callees
+// results.
+//
+// Description:
+//
+//	For each symbol named functionName, finds the CALLS edges between it
+//	and the result symbols and appends them, with their provenance, to
+//	the results' Edges. With callers set the results are callers (edges
+//	point at the queried function); otherwise they are callees.
+//
+// Inputs:
+//
+//	graphID - ID of the graph that produced the results.
+//	functionName - The queried function name.
+//	results - The callers or callees to annotate. Modified in place.
+//	callers - True for callers results, false for callees.
+//
+// Outputs:
+//
+//	error - Non-nil if the graph is not found.
+//
+// Thread Safety: Safe for concurrent use. Read-only access to the graph.
+func (s *Service) AnnotateCallEdges(graphID, functionName string, results []*SymbolInfo, callers bool) error {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]*SymbolInfo, len(results))
+	for _, r := range results {
+		byID[r.ID] = r
+	}
+	for _, node := range cached.Graph.GetNodesByName(functionName) {
+		edges := node.Outgoing
+		if callers {
+			edges = node.Incoming
+		}
+		for _, edge := range edges {
+			if edge.Type != graph.EdgeTypeCalls {
+				continue
+			}
+			peer := edge.ToID
+			if callers {
+				peer = edge.FromID
+			}
+			if r, ok := byID[peer]; ok {
+				r.Edges = append(r.Edges, edgeDebugInfo(edge))
+			}
+		}
+	}
+	return nil
+}
+
+// AnnotateReferenceEdges attaches the provenance of the edge behind each
+// reference.
+//
+// Description:
+//
+//	References are edge locations, so each reference is matched to the
+//	incoming edge of a symbol named symbolName at the same file, line and
+//	column. When several edges share a location the first one wins.
+//
+// Inputs:
+//
+//	graphID - ID of the graph that produced the references.
+//	symbolName - The queried symbol name.
+//	refs - The references to annotate. Modified in place.
+//
+// Outputs:
+//
+//	error - Non-nil if the graph is not found.
+//
+// Thread Safety: Safe for concurrent use. Read-only access to the graph.
+func (s *Service) AnnotateReferenceEdges(graphID, symbolName string, refs []ReferenceInfo) error {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return err
+	}
+
+	byLocation := make(map[string]*graph.Edge)
+	for _, node := range cached.Graph.GetNodesByName(symbolName) {
+		for _, edge := range node.Incoming {
+			key := fmt.Sprintf("%s:%d:%d", edge.Location.FilePath, edge.Location.StartLine, edge.Location.StartCol)
+			if _, ok := byLocation[key]; !ok {
+				byLocation[key] = edge
+			}
+		}
+	}
+	for i := range refs {
+		key := fmt.Sprintf("%s:%d:%d", refs[i].FilePath, refs[i].Line, refs[i].Column)
+		if edge, ok := byLocation[key]; ok {
+			refs[i].Provenance = edgeProvenance(edge)
+		}
+	}
+	return nil
+}
+
+// AnnotateSymbolEdges attaches a symbol's outgoing and incoming edges,
+// with their provenance, up to maxSymbolDebugEdges.
+//
+// Inputs:
+//
+//	graphID - ID of the graph that produced the symbol.
+//	sym - The symbol to annotate. Modified in place.
+//
+// Outputs:
+//
+//	error - Non-nil if the graph is not found.
+//
+// Thread Safety: Safe for concurrent use. Read-only access to the graph.
+func (s *Service) AnnotateSymbolEdges(graphID string, sym *SymbolInfo) error {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return err
+	}
+	node, ok := cached.Graph.GetNode(sym.ID)
+	if !ok {
+		return nil
+	}
+	for _, edges := range [][]*graph.Edge{node.Outgoing, node.Incoming} {
+		for _, edge := range edges {
+			if len(sym.Edges) >= maxSymbolDebugEdges {
+				return nil
+			}
+			sym.Edges = append(sym.Edges, edgeDebugInfo(edge))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// cacheProvenanceGraph caches a graph where main calls helper through an
+// edge with provenance.
+func cacheProvenanceGraph(t *testing.T, svc *Service) string {
+	t.Helper()
+	root := t.TempDir()
+	g := graph.NewGraph(root)
+	idx := index.NewSymbolIndex()
+	for _, sym := range []*ast.Symbol{
+		{ID: "main.go:3:main", Name: "main", Kind: ast.SymbolKindFunction, Package: "main", FilePath: "main.go", StartLine: 3, EndLine: 8, Language: "go"},
+		{ID: "util.go:1:helper", Name: "helper", Kind: ast.SymbolKindFunction, Package: "main", FilePath: "util.go", StartLine: 1, EndLine: 3, Language: "go"},
+	} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+		if err := idx.Add(sym); err != nil {
+			t.Fatalf("index Add: %v", err)
+		}
+	}
+	prov := graph.EdgeProvenance{Pass: graph.PassCallName, Confidence: graph.ConfidenceHigh}
+	if err := g.AddEdgeWithProvenance("main.go:3:main", "util.go:1:helper", graph.EdgeTypeCalls, ast.Location{FilePath: "main.go", StartLine: 4, StartCol: 2}, prov); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+	g.Freeze()
+
+	graphID := svc.generateGraphID(root)
+	svc.graphs[graphID] = &CachedGraph{Graph: g, Index: idx, BuiltAtMilli: g.BuiltAtMilli, ProjectRoot: root}
+	return graphID
+}
+
+func TestHandlers_EdgeProvenanceDebug(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	graphID := cacheProvenanceGraph(t, svc)
+
+	get := func(url string, out any) {
+		t.Helper()
+		req, _ := http.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d: %s", url, w.Code, w.Body.String())
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+	}
+
+	var callers CallersResponse
+	get("/v1/trace/callers?graph_id="+graphID+"&function=helper", &callers)
+	if len(callers.Callers) != 1 || len(callers.Callers[0].Edges) != 0 {
+		t.Fatalf("callers without debug = %+v", callers.Callers)
+	}
+	get("/v1/trace/callers?graph_id="+graphID+"&function=helper&debug=true", &callers)
+	if len(callers.Callers) != 1 || len(callers.Callers[0].Edges) != 1 {
+		t.Fatalf("callers with debug = %+v", callers.Callers)
+	}
+	if p := callers.Callers[0].Edges[0].Provenance; p.Pass != graph.PassCallName || p.Confidence != graph.ConfidenceHigh {
+		t.Errorf("caller edge provenance = %v", p)
+	}
+
+	var callees CalleesResponse
+	get("/v1/trace/callees?graph_id="+graphID+"&function=main&debug=true", &callees)
+	if len(callees.Callees) != 1 || len(callees.Callees[0].Edges) != 1 || callees.Callees[0].Edges[0].ToID != "util.go:1:helper" {
+		t.Errorf("callees with debug = %+v", callees.Callees)
+	}
+
+	var refs ReferencesResponse
+	get("/v1/trace/references?graph_id="+graphID+"&symbol=helper&debug=true", &refs)
+	if len(refs.References) != 1 || refs.References[0].Provenance == nil || refs.References[0].Provenance.Pass != graph.PassCallName {
+		t.Errorf("references with debug = %+v", refs.References)
+	}
+
+	var sym SymbolResponse
+	get("/v1/trace/symbol/util.go:1:helper?graph_id="+graphID+"&debug=true", &sym)
+	if sym.Symbol == nil || len(sym.Symbol.Edges) != 1 || sym.Symbol.Edges[0].FromID != "main.go:3:main" {
+		t.Errorf("symbol with debug = %+v", sym.Symbol)
+	}
+}
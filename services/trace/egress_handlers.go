@@ -0,0 +1,86 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/egress"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleEgressAudit returns persisted egress audit records.
+//
+// Description:
+//
+//	GET /v1/trace/egress/audit?since=&until=&session_id=&limit=. since and
+//	until are RFC 3339 timestamps; limit defaults to 100 and is capped at
+//	1000. Records are returned newest first and never contain message
+//	content, only classifications, byte counts, and redaction placeholders.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleEgressAudit(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleEgressAudit")
+
+	if h.egressAudit == nil {
+		apierror.New(http.StatusServiceUnavailable, apierror.CodeEgressAuditNotAvailable, "Egress audit store is not configured").
+			WithDetails("Set TRACE_EGRESS_AUDIT_DIR to persist egress audit records.").
+			Write(c)
+		return
+	}
+
+	query := egress.AuditQuery{SessionID: c.Query("session_id")}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"since", &query.Since}, {"until", &query.Until}} {
+		if v := c.Query(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, p.name+" must be an RFC 3339 timestamp")
+				return
+			}
+			*p.dst = t
+		}
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && query.Until.Before(query.Since) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "until must not be before since")
+		return
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "limit must be a positive integer")
+			return
+		}
+		query.Limit = parsed
+	}
+
+	records, err := h.egressAudit.Query(c.Request.Context(), query)
+	if err != nil {
+		logger.Error("Failed to query egress audit", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to query egress audit")
+		return
+	}
+
+	logger.Info("Queried egress audit", "session_id", query.SessionID, "count", len(records))
+	c.JSON(http.StatusOK, EgressAuditResponse{
+		Records:   records,
+		Count:     len(records),
+		LatencyMs: time.Since(start).Milliseconds(),
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/egress"
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandlers_HandleEgressAudit(t *testing.T) {
+	db, err := badgerstore.OpenDB(badgerstore.InMemoryConfig())
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer db.Close()
+	store := egress.NewBadgerAuditStore(db, time.Hour)
+
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i, sess := range []string{"s1", "s2", "s1"} {
+		if err := store.Append(context.Background(), &egress.AuditRecord{
+			RequestID: "r" + string(rune('0'+i)),
+			SessionID: sess,
+			Timestamp: base.Add(time.Duration(i) * time.Hour).UnixMilli(),
+			Decision:  egress.AuditDecisionRedacted,
+		}); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	router := gin.New()
+	RegisterRoutes(router.Group("/v1"), NewHandlers(NewService(DefaultServiceConfig())).WithEgressAudit(store))
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		want   []string
+	}{
+		{"session filter", "?session_id=s1", http.StatusOK, []string{"r2", "r0"}},
+		{"time range", "?since=2026-03-01T09:30:00Z&until=2026-03-01T10:30:00Z", http.StatusOK, []string{"r1"}},
+		{"limit", "?limit=1", http.StatusOK, []string{"r2"}},
+		{"bad since", "?since=yesterday", http.StatusBadRequest, nil},
+		{"inverted range", "?since=2026-03-02T00:00:00Z&until=2026-03-01T00:00:00Z", http.StatusBadRequest, nil},
+		{"bad limit", "?limit=0", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/trace/egress/audit"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var resp EgressAuditResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Count != len(tt.want) {
+				t.Fatalf("count = %d, want %v", resp.Count, tt.want)
+			}
+			for i, id := range tt.want {
+				if resp.Records[i].RequestID != id {
+					t.Errorf("record %d = %s, want %s", i, resp.Records[i].RequestID, id)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlers_HandleEgressAudit_NotConfigured(t *testing.T) {
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+
+	req, _ := http.NewRequest("GET", "/v1/trace/egress/audit", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
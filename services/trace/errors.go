@@ -10,7 +10,11 @@
 
 package trace
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
+)
 
 // Sentinel errors for the Trace service.
 var (
@@ -26,6 +30,10 @@ var (
 	// ErrPathTraversal indicates path contains .. traversal sequences.
 	ErrPathTraversal = errors.New("path contains traversal sequences")
 
+	// ErrRootNotAllowed indicates a path is outside the allowed roots. It
+	// is validation.ErrRootNotAllowed so errors from either package match.
+	ErrRootNotAllowed = validation.ErrRootNotAllowed
+
 	// ErrProjectTooLarge indicates the project exceeds size limits.
 	ErrProjectTooLarge = errors.New("project exceeds size limits")
 
@@ -34,4 +42,89 @@ var (
 
 	// ErrInitTimeout indicates the init operation timed out.
 	ErrInitTimeout = errors.New("initialization timed out")
+
+	// ErrInitCancelled indicates the build was cancelled via CancelBuild.
+	ErrInitCancelled = errors.New("initialization cancelled")
+
+	// ErrBuildNotFound indicates the build ID is unknown or was pruned.
+	ErrBuildNotFound = errors.New("build not found")
+
+	// ErrInvalidWorkspace indicates the workspace roots or manifest are unusable.
+	ErrInvalidWorkspace = errors.New("invalid workspace")
+
+	// ErrInvalidGitRef indicates a git_ref that does not resolve to a
+	// commit, or a project root that is not in a git repository.
+	ErrInvalidGitRef = errors.New("invalid git ref")
+
+	// ErrInvalidParseResult indicates an uploaded parse result that is
+	// malformed, names an unsafe file path, or uses an unsupported language.
+	ErrInvalidParseResult = errors.New("invalid parse result")
+
+	// ErrPayloadTooLarge indicates an uploaded body or one of its records
+	// exceeds the configured size limits.
+	ErrPayloadTooLarge = errors.New("payload too large")
+
+	// ErrUnknownRoot indicates a root filter names no root of the graph.
+	ErrUnknownRoot = errors.New("unknown workspace root")
+
+	// ErrCompactionInProgress indicates the graph is already being compacted.
+	ErrCompactionInProgress = errors.New("graph compaction in progress")
+
+	// ErrPlanNotFound indicates the change plan is unknown or was evicted.
+	ErrPlanNotFound = errors.New("plan not found")
+
+	// ErrPlanNotPending indicates an approval decision on a plan that is
+	// not awaiting one.
+	ErrPlanNotPending = errors.New("plan is not pending approval")
+
+	// ErrPlanNotApproved indicates a plan was applied before it was
+	// approved, or after it was rejected, expired or already applied.
+	ErrPlanNotApproved = errors.New("plan is not approved")
+
+	// ErrViewNotFound indicates no scoped view has the given name.
+	ErrViewNotFound = errors.New("view not found")
+
+	// ErrInvalidView indicates a scoped view with a bad name, patterns or
+	// boundary mode, or used with a graph it was not created for.
+	ErrInvalidView = errors.New("invalid view")
+
+	// ErrInvalidChunkRequest indicates chunking options, file patterns or a
+	// cursor that are out of range or malformed.
+	ErrInvalidChunkRequest = errors.New("invalid chunk request")
+
+	// ErrInvalidAssertions indicates an assertions check given both inline
+	// rules and a rules file, or a rules file that cannot be read.
+	ErrInvalidAssertions = errors.New("invalid assertion rules")
+
+	// ErrAnnotationSymbolNotFound indicates an annotation selector that
+	// matches no symbol of the graph.
+	ErrAnnotationSymbolNotFound = errors.New("annotated symbol not found")
+
+	// ErrAnnotationNotFound indicates no annotation matches the symbol and
+	// key.
+	ErrAnnotationNotFound = errors.New("annotation not found")
+
+	// ErrAnnotationReadOnly indicates an annotation defined in the
+	// project's annotations file, which the API cannot change.
+	ErrAnnotationReadOnly = errors.New("annotation is defined in the annotations file")
+
+	// ErrAnnotationsUnavailable indicates a graph installed without an
+	// annotation set.
+	ErrAnnotationsUnavailable = errors.New("annotations not available for graph")
+
+	// ErrFactNotFound indicates no fact of the project has the ID.
+	ErrFactNotFound = errors.New("fact not found")
+
+	// ErrFactUnanchored indicates a candidate fact that names no symbol
+	// of the graph. Facts are only kept while their symbols exist.
+	ErrFactUnanchored = errors.New("fact names no symbol of the graph")
+
+	// ErrFactSymbolNotFound indicates a fact anchor that matches no symbol
+	// of the graph.
+	ErrFactSymbolNotFound = errors.New("fact symbol not found")
+
+	// ErrCaseCollision indicates two file paths that differ only in case.
+	// Only one of them is parsed, since a case-insensitive file system
+	// cannot hold both.
+	ErrCaseCollision = errors.New("paths differ only in case")
 )
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -805,7 +806,7 @@ func (r *Runner) RunAll(ctx context.Context, opts ...RunOption) ([]*Result, erro
 		if err != nil {
 			r.logger.Warn("benchmark failed",
 				slog.String("component", name),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			continue
 		}
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -367,7 +368,7 @@ func (i *Injector) injectFault(ctx context.Context, fault Fault) {
 	if err != nil {
 		i.logger.Warn("failed to inject fault",
 			slog.String("fault", fault.Name()),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return
 	}
@@ -397,7 +398,7 @@ func (i *Injector) revertFault(ctx context.Context, fault Fault, target eval.Eva
 	if err != nil {
 		i.logger.Warn("failed to revert fault",
 			slog.String("fault", fault.Name()),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 	} else {
 		i.logger.Info("fault reverted",
@@ -417,7 +418,7 @@ func (i *Injector) revertFault(ctx context.Context, fault Fault, target eval.Eva
 			result.Error = recoveryErr
 			i.logger.Warn("recovery verification failed",
 				slog.String("fault", fault.Name()),
-				slog.String("error", recoveryErr.Error()),
+				telemetry.SensitiveLogError("error", recoveryErr),
 			)
 		}
 	}
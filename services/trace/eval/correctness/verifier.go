@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -356,7 +357,7 @@ func (v *Verifier) VerifyAll(ctx context.Context, opts ...VerifyOption) ([]*eval
 			// Log but continue with other components
 			logger.Warn("verification failed",
 				slog.String("component", name),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			continue
 		}
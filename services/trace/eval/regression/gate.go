@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/eval"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -274,7 +275,7 @@ func (g *Gate) Check(ctx context.Context, component string, current *CurrentMetr
 				if setErr := g.baseline.Set(ctx, component, newBaseline); setErr != nil {
 					g.logger.Warn("failed to create initial baseline",
 						slog.String("component", component),
-						slog.String("error", setErr.Error()),
+						telemetry.SensitiveLogError("error", setErr),
 					)
 				} else {
 					decision.BaselineUpdated = true
@@ -310,7 +311,7 @@ func (g *Gate) Check(ctx context.Context, component string, current *CurrentMetr
 			if setErr := g.baseline.Set(ctx, component, newBaseline); setErr != nil {
 				g.logger.Warn("failed to update baseline",
 					slog.String("component", component),
-					slog.String("error", setErr.Error()),
+					telemetry.SensitiveLogError("error", setErr),
 				)
 			} else {
 				decision.BaselineUpdated = true
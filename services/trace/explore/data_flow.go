@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 )
@@ -101,6 +102,10 @@ func NewDataFlowTracerWithRegistries(
 //	to identify where data flows. Classifies each visited symbol as a
 //	source, transform, or sink based on pattern matching.
 //
+//	If the symbol is a Go struct field, traces the functions that write
+//	and read it instead, one call hop beyond them, and the points where
+//	it is serialized (Precision "field"; see traceFieldFlow).
+//
 // Inputs:
 //
 //	ctx - Context for cancellation.
@@ -165,6 +170,19 @@ func (t *DataFlowTracer) TraceDataFlow(ctx context.Context, symbolID string, opt
 		return nil, ErrSymbolNotFound
 	}
 
+	// A struct field traces its writers, readers and serialization points.
+	if startNode.Symbol != nil && startNode.Symbol.Kind == ast.SymbolKindField {
+		flow, visited, err := t.traceFieldFlow(ctx, startNode, options.MaxNodes)
+		if err != nil {
+			setTraceSpanResult(span, visited, 0, false)
+			recordTraceMetrics(ctx, "trace_data_flow", time.Since(start), visited, 0, false)
+			return nil, err
+		}
+		setTraceSpanResult(span, visited, len(flow.Sinks), true)
+		recordTraceMetrics(ctx, "trace_data_flow", time.Since(start), visited, len(flow.Sinks), true)
+		return flow, nil
+	}
+
 	flow := &DataFlow{
 		Sources:    make([]DataPoint, 0),
 		Transforms: make([]DataPoint, 0),
@@ -0,0 +1,705 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package explore
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+//go:embed entry_heuristics.yaml
+var defaultEntryHeuristicsYAML []byte
+
+// EntryHeuristicsEnv names the environment variable holding the path of a
+// YAML file with additional entry point heuristics.
+const EntryHeuristicsEnv = "TRACE_ENTRY_HEURISTICS"
+
+// HeuristicMatch selects which recorded construct a heuristic inspects.
+type HeuristicMatch string
+
+const (
+	// MatchCall inspects call sites, e.g. r.GET("/x", handler).
+	MatchCall HeuristicMatch = "call"
+
+	// MatchStructLiteral inspects Go composite literals, e.g. cobra.Command{Run: fn}.
+	MatchStructLiteral HeuristicMatch = "struct_literal"
+
+	// MatchDecorator inspects function decorators, e.g. @app.route("/x").
+	MatchDecorator HeuristicMatch = "decorator"
+)
+
+// httpVerbs are the HTTP methods recognized in method names and arguments.
+var httpVerbs = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true,
+	"HEAD": true, "OPTIONS": true, "CONNECT": true, "TRACE": true,
+}
+
+// FrameworkHeuristic detects framework registrations of entry points.
+//
+// Description:
+//
+//	Where PatternMatcher looks at a symbol's own name and signature, a
+//	heuristic looks at how a handler is wired up: the route registration
+//	call that passes it, the command struct that names it, or the route
+//	decorator on it. Matches carry an EntryPointTrigger describing the
+//	route, command or schedule. See entry_heuristics.yaml for the format.
+//
+// Thread Safety: Immutable after loading; safe for concurrent use.
+type FrameworkHeuristic struct {
+	// Name identifies the heuristic in results and errors.
+	Name string `yaml:"name"`
+
+	// Language is the symbol language this heuristic applies to.
+	Language string `yaml:"language"`
+
+	// Framework is reported on matching entry points.
+	Framework string `yaml:"framework"`
+
+	// Match selects the construct to inspect.
+	Match HeuristicMatch `yaml:"match"`
+
+	// Type is the entry point type reported for matches.
+	Type EntryPointType `yaml:"type"`
+
+	// Trigger is the trigger kind reported for matches.
+	Trigger TriggerKind `yaml:"trigger"`
+
+	// Imports restricts the heuristic to files importing one of these paths.
+	Imports []string `yaml:"imports,omitempty"`
+
+	// Methods are call target names for MatchCall (e.g. "GET", "HandleFunc").
+	Methods []string `yaml:"methods,omitempty"`
+
+	// StructType is the literal type for MatchStructLiteral (e.g. "cobra.Command").
+	StructType string `yaml:"struct_type,omitempty"`
+
+	// HandlerFields are the fields naming the handler, in preference order.
+	HandlerFields []string `yaml:"handler_fields,omitempty"`
+
+	// NameField is the field holding the command name.
+	NameField string `yaml:"name_field,omitempty"`
+
+	// Decorators are decorator name globs for MatchDecorator (e.g. "*.route").
+	Decorators []string `yaml:"decorators,omitempty"`
+
+	// DefaultMethod is the HTTP method used when none can be derived.
+	DefaultMethod string `yaml:"default_method,omitempty"`
+}
+
+// EntryHeuristicsFile is the YAML layout of a heuristics file.
+type EntryHeuristicsFile struct {
+	// ReplaceDefaults drops the built-in heuristics instead of appending them.
+	ReplaceDefaults bool `yaml:"replace_defaults"`
+
+	// Heuristics are tried in order; the first match wins.
+	Heuristics []FrameworkHeuristic `yaml:"heuristics"`
+}
+
+// ParseEntryHeuristics parses and validates a heuristics file.
+//
+// Description:
+//
+//	Unknown keys are rejected so typos do not silently disable a
+//	heuristic. Languages are lower-cased.
+//
+// Inputs:
+//
+//	data - YAML bytes.
+//
+// Outputs:
+//
+//	*EntryHeuristicsFile - The parsed file.
+//	error - Non-nil if the YAML is malformed or a heuristic is invalid.
+func ParseEntryHeuristics(data []byte) (*EntryHeuristicsFile, error) {
+	var file EntryHeuristicsFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse entry heuristics: %w", err)
+	}
+	for i := range file.Heuristics {
+		h := &file.Heuristics[i]
+		h.Language = strings.ToLower(h.Language)
+		if err := h.validate(); err != nil {
+			return nil, fmt.Errorf("entry heuristic %d (%s): %w", i, h.Name, err)
+		}
+	}
+	return &file, nil
+}
+
+// validate checks that the heuristic has the fields its match kind needs.
+func (h *FrameworkHeuristic) validate() error {
+	if h.Name == "" {
+		return errors.New("name is required")
+	}
+	if h.Language == "" {
+		return errors.New("language is required")
+	}
+	switch h.Type {
+	case "", EntryPointAll, EntryPointTest:
+		return fmt.Errorf("type %q is not a reportable entry point type", h.Type)
+	}
+	switch h.Trigger {
+	case TriggerHTTP, TriggerCommand, TriggerSchedule:
+	default:
+		return fmt.Errorf("trigger must be http, command or schedule, got %q", h.Trigger)
+	}
+	switch h.Match {
+	case MatchCall:
+		if len(h.Methods) == 0 {
+			return errors.New("call heuristics need methods")
+		}
+	case MatchStructLiteral:
+		if h.StructType == "" || len(h.HandlerFields) == 0 {
+			return errors.New("struct_literal heuristics need struct_type and handler_fields")
+		}
+	case MatchDecorator:
+		if len(h.Decorators) == 0 {
+			return errors.New("decorator heuristics need decorators")
+		}
+		for _, pattern := range h.Decorators {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("decorator pattern %q: %w", pattern, err)
+			}
+		}
+	default:
+		return fmt.Errorf("match must be call, struct_literal or decorator, got %q", h.Match)
+	}
+	return nil
+}
+
+// DefaultEntryHeuristics returns the built-in heuristics.
+//
+// Thread Safety: Safe for concurrent use. Returns a fresh slice.
+func DefaultEntryHeuristics() []FrameworkHeuristic {
+	file, err := ParseEntryHeuristics(defaultEntryHeuristicsYAML)
+	if err != nil {
+		// The embedded file is covered by tests; fail soft rather than panic.
+		slog.Error("Embedded entry heuristics are invalid", telemetry.SensitiveLogError("error", err))
+		return nil
+	}
+	return file.Heuristics
+}
+
+// LoadEntryHeuristics reads a heuristics file and merges it with the defaults.
+//
+// Description:
+//
+//	The file's heuristics come first so in-house frameworks win over the
+//	built-in ones, followed by the defaults unless the file sets
+//	replace_defaults.
+//
+// Inputs:
+//
+//	filePath - Path to the YAML file.
+//
+// Outputs:
+//
+//	[]FrameworkHeuristic - The merged heuristics.
+//	error - Non-nil if the file cannot be read or is invalid.
+func LoadEntryHeuristics(filePath string) ([]FrameworkHeuristic, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("read entry heuristics: %w", err)
+	}
+	file, err := ParseEntryHeuristics(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+	if file.ReplaceDefaults {
+		return file.Heuristics, nil
+	}
+	return append(file.Heuristics, DefaultEntryHeuristics()...), nil
+}
+
+var (
+	configuredHeuristicsMu sync.Mutex
+	configuredHeuristics   []FrameworkHeuristic
+	configuredHeuristicsOK bool
+)
+
+// ConfiguredEntryHeuristics returns the heuristics used by new registries.
+//
+// Description:
+//
+//	Loads the file named by TRACE_ENTRY_HEURISTICS on first use, falling
+//	back to the defaults with a warning if it cannot be loaded. The result
+//	is cached for the life of the process.
+//
+// Thread Safety: Safe for concurrent use.
+func ConfiguredEntryHeuristics() []FrameworkHeuristic {
+	configuredHeuristicsMu.Lock()
+	defer configuredHeuristicsMu.Unlock()
+
+	if !configuredHeuristicsOK {
+		configuredHeuristics = DefaultEntryHeuristics()
+		if filePath := os.Getenv(EntryHeuristicsEnv); filePath != "" {
+			if loaded, err := LoadEntryHeuristics(filePath); err != nil {
+				slog.Warn("Entry heuristics file not loaded, using built-in heuristics",
+					telemetry.SensitiveLog("path", filePath),
+					telemetry.SensitiveLogError("error", err))
+			} else {
+				configuredHeuristics = loaded
+			}
+		}
+		configuredHeuristicsOK = true
+	}
+	return configuredHeuristics
+}
+
+// ResetEntryHeuristics clears the cached configured heuristics for testing.
+//
+// Thread Safety: Safe for concurrent use.
+func ResetEntryHeuristics() {
+	configuredHeuristicsMu.Lock()
+	defer configuredHeuristicsMu.Unlock()
+	configuredHeuristics = nil
+	configuredHeuristicsOK = false
+}
+
+// findHeuristicEntryPoints applies the registry's heuristics to every
+// function, method and variable in the index.
+//
+// Outputs:
+//
+//	[]EntryPoint - Matches passing the type, package and language filters.
+//	bool - True if the context was canceled and the list is partial.
+func (f *EntryPointFinder) findHeuristicEntryPoints(ctx context.Context, opts EntryPointOptions) ([]EntryPoint, bool) {
+	heuristics := f.registry.Heuristics()
+	if len(heuristics) == 0 || f.index == nil {
+		return nil, false
+	}
+
+	m := &heuristicMatcher{finder: f, heuristics: heuristics, seen: make(map[string]bool)}
+	for _, h := range heuristics {
+		if len(h.Imports) > 0 {
+			m.imports = f.fileImports()
+			break
+		}
+	}
+
+	checkCounter := 0
+	for _, kind := range []ast.SymbolKind{ast.SymbolKindFunction, ast.SymbolKindMethod, ast.SymbolKindVariable} {
+		for _, sym := range f.index.GetByKind(kind) {
+			checkCounter++
+			if checkCounter%contextCheckIntervalEntryPoints == 0 && ctx.Err() != nil {
+				return m.filter(opts), true
+			}
+			if opts.Language != "" && sym.Language != opts.Language {
+				continue
+			}
+			m.matchSymbol(sym)
+		}
+	}
+
+	return m.filter(opts), false
+}
+
+// heuristicMatcher holds the state of one heuristic scan.
+type heuristicMatcher struct {
+	finder     *EntryPointFinder
+	heuristics []FrameworkHeuristic
+	imports    map[string][]string
+	seen       map[string]bool
+	found      []EntryPoint
+}
+
+// matchSymbol records every registration inside sym. For each construct
+// the first matching heuristic wins.
+func (m *heuristicMatcher) matchSymbol(sym *ast.Symbol) {
+	for _, call := range sym.Calls {
+		if len(call.FunctionArgs) == 0 {
+			continue
+		}
+		for i := range m.heuristics {
+			h := &m.heuristics[i]
+			if h.Match == MatchCall && m.applies(h, sym) && containsString(h.Methods, call.Target) {
+				if m.matchCall(h, sym, call) {
+					break
+				}
+			}
+		}
+	}
+
+	for _, lit := range sym.StructLiterals {
+		for i := range m.heuristics {
+			h := &m.heuristics[i]
+			if h.Match == MatchStructLiteral && h.StructType == lit.Type && m.applies(h, sym) {
+				if m.matchStructLiteral(h, sym, lit) {
+					break
+				}
+			}
+		}
+	}
+
+	if sym.Metadata != nil {
+		for _, dec := range sym.Metadata.Decorators {
+			for i := range m.heuristics {
+				h := &m.heuristics[i]
+				if h.Match == MatchDecorator && matchesAnyGlob(h.Decorators, dec) && m.applies(h, sym) {
+					if m.matchDecorator(h, sym, dec) {
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+// applies reports whether h may fire for sym's language and file imports.
+func (m *heuristicMatcher) applies(h *FrameworkHeuristic, sym *ast.Symbol) bool {
+	if h.Language != sym.Language {
+		return false
+	}
+	if len(h.Imports) == 0 || m.imports == nil {
+		return true
+	}
+	for _, imp := range m.imports[sym.FilePath] {
+		for _, want := range h.Imports {
+			if imp == want || strings.HasPrefix(imp, want+"/") || strings.HasPrefix(imp, want+".") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchCall handles r.GET("/x", h), c.AddFunc("@daily", job) and the like.
+func (m *heuristicMatcher) matchCall(h *FrameworkHeuristic, sym *ast.Symbol, call ast.CallSite) bool {
+	trigger := EntryPointTrigger{Kind: h.Trigger}
+	literals := call.StringLiteralArgs()
+	switch h.Trigger {
+	case TriggerHTTP:
+		method, route, ok := httpRoute(call.Target, literals, h.DefaultMethod)
+		if !ok {
+			return false
+		}
+		trigger.Method, trigger.Path = method, route
+	case TriggerSchedule:
+		if len(literals) == 0 {
+			return false
+		}
+		trigger.Schedule = literals[0]
+	case TriggerCommand:
+		if len(literals) == 0 {
+			return false
+		}
+		trigger.Command = firstWord(literals[0])
+	}
+
+	handler := call.FunctionArgs[len(call.FunctionArgs)-1]
+	m.add(h, sym, trigger, call.Location, handler, false)
+	return true
+}
+
+// matchStructLiteral handles &cobra.Command{Use: "serve", RunE: runServe}.
+func (m *heuristicMatcher) matchStructLiteral(h *FrameworkHeuristic, sym *ast.Symbol, lit ast.StructLiteral) bool {
+	var handler string
+	for _, field := range h.HandlerFields {
+		if v := lit.Fields[field]; v != "" {
+			handler = v
+			break
+		}
+	}
+	if handler == "" {
+		// A parent command with only subcommands runs nothing itself.
+		return false
+	}
+
+	trigger := EntryPointTrigger{Kind: h.Trigger}
+	name := firstWord(lit.Fields[h.NameField])
+	switch h.Trigger {
+	case TriggerCommand:
+		trigger.Command = name
+	case TriggerSchedule:
+		trigger.Schedule = lit.Fields[h.NameField]
+	case TriggerHTTP:
+		trigger.Method, trigger.Path = orDefault(h.DefaultMethod, "ANY"), lit.Fields[h.NameField]
+	}
+
+	inline := handler == ast.FuncLiteralValue
+	m.add(h, sym, trigger, lit.Location, handler, inline)
+	return true
+}
+
+// matchDecorator handles @app.route("/x", methods=["POST"]) and @router.get("/x").
+func (m *heuristicMatcher) matchDecorator(h *FrameworkHeuristic, sym *ast.Symbol, dec string) bool {
+	literals := sym.Metadata.DecoratorLiterals[dec]
+	loc := ast.Location{FilePath: sym.FilePath, StartLine: sym.StartLine}
+
+	if h.Trigger != TriggerHTTP {
+		trigger := EntryPointTrigger{Kind: h.Trigger}
+		if len(literals) > 0 {
+			if h.Trigger == TriggerCommand {
+				trigger.Command = firstWord(literals[0])
+			} else {
+				trigger.Schedule = literals[0]
+			}
+		}
+		m.add(h, sym, trigger, loc, "", false)
+		return true
+	}
+
+	var route string
+	var methods []string
+	for _, lit := range literals {
+		if key, value, ok := strings.Cut(lit, "="); ok && key == "methods" {
+			for _, method := range strings.Split(value, ",") {
+				methods = append(methods, strings.ToUpper(strings.TrimSpace(method)))
+			}
+		} else if route == "" && strings.HasPrefix(lit, "/") {
+			route = lit
+		}
+	}
+	if route == "" {
+		return false
+	}
+	if len(methods) == 0 {
+		method, _, _ := httpRoute(dec[strings.LastIndex(dec, ".")+1:], []string{route}, h.DefaultMethod)
+		methods = []string{method}
+	}
+	for _, method := range methods {
+		m.add(h, sym, EntryPointTrigger{Kind: TriggerHTTP, Method: method, Path: route}, loc, "", false)
+	}
+	return true
+}
+
+// add resolves the handler and records the entry point.
+//
+// Inputs:
+//
+//	handler - The handler expression, or "" when sym itself is the handler.
+//	inline - True if the handler is an anonymous function inside sym.
+func (m *heuristicMatcher) add(h *FrameworkHeuristic, sym *ast.Symbol, trigger EntryPointTrigger, loc ast.Location, handler string, inline bool) {
+	if loc.FilePath == "" {
+		loc.FilePath = sym.FilePath
+	}
+	trigger.Heuristic = h.Name
+	trigger.RegisteredIn = sym.ID
+	trigger.RegisteredAt = fmt.Sprintf("%s:%d", loc.FilePath, loc.StartLine)
+	trigger.Inline = inline
+
+	target := sym
+	if handler != "" && !inline {
+		target = m.finder.resolveHandler(handler, sym)
+	}
+
+	ep := EntryPoint{
+		Type:      h.Type,
+		Framework: h.Framework,
+		Trigger:   &trigger,
+	}
+	if target != nil {
+		ep.ID = target.ID
+		ep.Name = target.Name
+		ep.FilePath = target.FilePath
+		ep.Line = target.StartLine
+		ep.Signature = target.Signature
+		ep.DocComment = target.DocComment
+		ep.Package = target.Package
+	} else {
+		// Handlers from other modules (http.NotFound) still define an endpoint.
+		ep.Name = handler
+		ep.FilePath = loc.FilePath
+		ep.Line = loc.StartLine
+		ep.Package = sym.Package
+	}
+
+	key := strings.Join([]string{ep.ID, ep.Name, string(trigger.Kind), trigger.Method, trigger.Path, trigger.Command, trigger.Schedule, trigger.RegisteredAt}, "\x00")
+	if m.seen[key] {
+		return
+	}
+	m.seen[key] = true
+	m.found = append(m.found, ep)
+}
+
+// filter applies the type and package options.
+func (m *heuristicMatcher) filter(opts EntryPointOptions) []EntryPoint {
+	kept := m.found[:0]
+	for _, ep := range m.found {
+		if opts.Type != EntryPointAll && opts.Type != "" && ep.Type != opts.Type {
+			continue
+		}
+		if opts.Package != "" && ep.Package != opts.Package {
+			continue
+		}
+		kept = append(kept, ep)
+	}
+	return kept
+}
+
+// resolveHandler finds the function or method a handler expression names.
+//
+// Description:
+//
+//	Looks up the last segment of the expression (HandleInit for
+//	h.HandleInit) and prefers candidates in the registering file, then in
+//	its package, then in a package named by the qualifier. Ties break on
+//	ID so results are deterministic.
+//
+// Outputs:
+//
+//	*ast.Symbol - The handler, or nil if no function or method matches.
+func (f *EntryPointFinder) resolveHandler(expr string, from *ast.Symbol) *ast.Symbol {
+	leaf, qualifier := expr, ""
+	if i := strings.LastIndex(expr, "."); i >= 0 {
+		leaf, qualifier = expr[i+1:], expr[:i]
+	}
+
+	var best *ast.Symbol
+	bestScore := -1
+	for _, cand := range f.index.GetByName(leaf) {
+		if cand.Kind != ast.SymbolKindFunction && cand.Kind != ast.SymbolKindMethod {
+			continue
+		}
+		score := 0
+		switch {
+		case cand.FilePath == from.FilePath:
+			score = 4
+		case cand.Package == from.Package && path.Dir(cand.FilePath) == path.Dir(from.FilePath):
+			score = 2
+		}
+		if qualifier != "" && (cand.Package == qualifier || strings.HasSuffix(cand.Package, "/"+qualifier)) {
+			score++
+		}
+		if score > bestScore || (score == bestScore && cand.ID < best.ID) {
+			best, bestScore = cand, score
+		}
+	}
+	return best
+}
+
+// fileImports maps file paths to their import paths using the graph's
+// import edges. Returns nil if the graph has no import data, in which case
+// import restrictions are not applied.
+func (f *EntryPointFinder) fileImports() map[string][]string {
+	if f.graph == nil {
+		return nil
+	}
+	var imports map[string][]string
+	for _, edge := range f.graph.Edges() {
+		if edge.Type != graph.EdgeTypeImports {
+			continue
+		}
+		importPath := edge.ToID
+		if node, ok := f.graph.GetNode(edge.ToID); ok && node.Symbol != nil && node.Symbol.Name != "" {
+			importPath = node.Symbol.Name
+		}
+		if imports == nil {
+			imports = make(map[string][]string)
+		}
+		imports[edge.Location.FilePath] = append(imports[edge.Location.FilePath], importPath)
+	}
+	return imports
+}
+
+// httpRoute derives the HTTP method and path of a route registration.
+//
+// Description:
+//
+//	The path is the first literal starting with "/", or the path half of a
+//	"GET /x" pattern. The method comes from the pattern, a leading verb
+//	literal (chi's Method("GET", "/x", h)), the registration name when it
+//	is a verb, "ANY" for Any/All, or defaultMethod.
+//
+// Outputs:
+//
+//	string - Upper-case method.
+//	string - Route path.
+//	bool - False if no path was found.
+func httpRoute(name string, literals []string, defaultMethod string) (string, string, bool) {
+	method := ""
+	if upper := strings.ToUpper(name); httpVerbs[upper] {
+		method = upper
+	} else if upper == "ANY" || upper == "ALL" {
+		method = "ANY"
+	}
+
+	if len(literals) > 1 && httpVerbs[strings.ToUpper(literals[0])] {
+		method = strings.ToUpper(literals[0])
+		literals = literals[1:]
+	}
+
+	for _, lit := range literals {
+		if strings.HasPrefix(lit, "/") {
+			return orDefault(method, orDefault(strings.ToUpper(defaultMethod), "ANY")), lit, true
+		}
+		if verb, rest, ok := strings.Cut(lit, " "); ok && httpVerbs[strings.ToUpper(verb)] {
+			if rest = strings.TrimSpace(rest); strings.HasPrefix(rest, "/") {
+				return strings.ToUpper(verb), rest, true
+			}
+		}
+	}
+	return "", "", false
+}
+
+// sortEntryPoints orders entry points by file, line, then trigger.
+func sortEntryPoints(eps []EntryPoint) {
+	sort.SliceStable(eps, func(i, j int) bool {
+		a, b := eps[i], eps[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		if a.Trigger == nil || b.Trigger == nil {
+			return a.Trigger == nil && b.Trigger != nil
+		}
+		if a.Trigger.Path != b.Trigger.Path {
+			return a.Trigger.Path < b.Trigger.Path
+		}
+		return a.Trigger.Method < b.Trigger.Method
+	})
+}
+
+// matchesAnyGlob reports whether name matches one of the path.Match patterns.
+func matchesAnyGlob(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// firstWord returns the first whitespace-separated word of s ("serve" for
+// cobra's Use: "serve [flags]").
+func firstWord(s string) string {
+	if fields := strings.Fields(s); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+// orDefault returns s, or def if s is empty.
+func orDefault(s, def string) string {
+	if s == "" {
+		return def
+	}
+	return s
+}
@@ -0,0 +1,501 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package explore
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+const heuristicsGinSource = `package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handlers struct{}
+
+func (h *Handlers) GetUser(c *gin.Context) {}
+
+func createUser(c *gin.Context) {}
+
+func Register(r *gin.Engine, h *Handlers, auth gin.HandlerFunc) {
+	r.GET("/users/:id", auth, h.GetUser)
+	r.POST("/users", createUser)
+	r.Any("/legacy", gin.WrapF(http.NotFound))
+	r.GET("/health", func(c *gin.Context) {})
+	r.Handle("/static", auth, h.GetUser)
+}
+`
+
+const heuristicsMuxSource = `package web
+
+import "net/http"
+
+func listOrders(w http.ResponseWriter, r *http.Request) {}
+
+func Routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /orders/{id}", listOrders)
+	mux.HandleFunc("/orders", listOrders)
+	mux.Handle("/missing", external.Handler)
+}
+`
+
+const heuristicsCobraSource = `package cmd
+
+import (
+	"github.com/robfig/cron/v3"
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{Use: "app"}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve [flags]",
+	Short: "Run the server",
+	RunE:  runServe,
+}
+
+func runServe(cmd *cobra.Command, args []string) error { return nil }
+
+func newMigrateCmd() *cobra.Command {
+	return &cobra.Command{Use: "migrate", Run: func(cmd *cobra.Command, args []string) {}}
+}
+
+func cleanup() {}
+
+func Schedule(c *cron.Cron) {
+	c.AddFunc("@every 1h", cleanup)
+}
+`
+
+const heuristicsFlaskSource = `from flask import Flask
+
+app = Flask(__name__)
+
+@app.route("/items", methods=["GET", "POST"])
+def items():
+    pass
+
+@app.get("/items/<id>")
+def item(id):
+    pass
+
+@app.route("/health")
+def health():
+    pass
+`
+
+const heuristicsFastAPISource = `from fastapi import APIRouter
+
+router = APIRouter()
+
+@router.post("/orders")
+async def create_order():
+    pass
+
+@router.get(f"/dynamic/{x}")
+def dynamic():
+    pass
+`
+
+const heuristicsExpressSource = `function listUsers(req, res) {}
+
+function requireAuth(req, res, next) {}
+
+function register(app) {
+    app.get('/users', requireAuth, listUsers);
+    app.post("/users", (req, res) => {});
+}
+
+module.exports = { register };
+`
+
+// buildHeuristicsGraph parses the files, builds a frozen graph and indexes
+// every symbol, as /init does.
+func buildHeuristicsGraph(t *testing.T, files map[string]string) (*graph.Graph, *index.SymbolIndex) {
+	t.Helper()
+	ctx := context.Background()
+
+	var results []*ast.ParseResult
+	for path, src := range files {
+		var parser ast.Parser
+		switch filepath.Ext(path) {
+		case ".go":
+			parser = ast.NewGoParser()
+		case ".py":
+			parser = ast.NewPythonParser()
+		case ".js":
+			parser = ast.NewJavaScriptParser()
+		}
+		result, err := parser.Parse(ctx, []byte(src), path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		results = append(results, result)
+	}
+
+	built, err := graph.NewBuilder().Build(ctx, results)
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+
+	idx := index.NewSymbolIndex()
+	var add func(syms []*ast.Symbol)
+	add = func(syms []*ast.Symbol) {
+		for _, sym := range syms {
+			if err := idx.Add(sym); err != nil {
+				t.Fatalf("index %s: %v", sym.ID, err)
+			}
+			add(sym.Children)
+		}
+	}
+	for _, r := range results {
+		add(r.Symbols)
+	}
+	return built.Graph, idx
+}
+
+// heuristicFinder returns a finder using only the built-in heuristics,
+// independent of TRACE_ENTRY_HEURISTICS.
+func heuristicFinder(g *graph.Graph, idx *index.SymbolIndex) *EntryPointFinder {
+	registry := NewEntryPointRegistry()
+	registry.SetHeuristics(DefaultEntryHeuristics())
+	return NewEntryPointFinder(g, idx).WithRegistry(registry)
+}
+
+// triggered returns the entry points with a trigger, keyed by
+// "METHOD path", "command name" or "schedule spec".
+func triggered(t *testing.T, eps []EntryPoint) map[string]EntryPoint {
+	t.Helper()
+	out := make(map[string]EntryPoint)
+	for _, ep := range eps {
+		if ep.Trigger == nil {
+			continue
+		}
+		var key string
+		switch ep.Trigger.Kind {
+		case TriggerHTTP:
+			key = ep.Trigger.Method + " " + ep.Trigger.Path
+		case TriggerCommand:
+			key = "command " + ep.Trigger.Command
+		case TriggerSchedule:
+			key = "schedule " + ep.Trigger.Schedule
+		}
+		if _, dup := out[key]; dup {
+			t.Errorf("duplicate entry point for %s", key)
+		}
+		out[key] = ep
+	}
+	return out
+}
+
+func TestEntryHeuristics_Go(t *testing.T) {
+	g, idx := buildHeuristicsGraph(t, map[string]string{
+		"api/routes.go": heuristicsGinSource,
+		"web/mux.go":    heuristicsMuxSource,
+		"cmd/root.go":   heuristicsCobraSource,
+	})
+
+	opts := DefaultEntryPointOptions()
+	opts.Limit = 0
+	result, err := heuristicFinder(g, idx).FindEntryPoints(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := triggered(t, result.EntryPoints)
+
+	tests := []struct {
+		key       string
+		name      string
+		framework string
+		epType    EntryPointType
+	}{
+		{"GET /users/:id", "GetUser", "gin", EntryPointHandler},
+		{"POST /users", "createUser", "gin", EntryPointHandler},
+		{"ANY /static", "GetUser", "gin", EntryPointHandler},
+		{"GET /orders/{id}", "listOrders", "net/http", EntryPointHandler},
+		{"ANY /orders", "listOrders", "net/http", EntryPointHandler},
+		{"ANY /missing", "external.Handler", "net/http", EntryPointHandler},
+		{"command serve", "runServe", "cobra", EntryPointCommand},
+		{"command migrate", "newMigrateCmd", "cobra", EntryPointCommand},
+		{"schedule @every 1h", "cleanup", "robfig/cron", EntryPointScheduled},
+	}
+	for _, tt := range tests {
+		ep, ok := got[tt.key]
+		if !ok {
+			t.Errorf("missing entry point %q (have %v)", tt.key, keysOf(got))
+			continue
+		}
+		if ep.Name != tt.name || ep.Framework != tt.framework || ep.Type != tt.epType {
+			t.Errorf("%s = %s/%s/%s, want %s/%s/%s", tt.key, ep.Name, ep.Framework, ep.Type, tt.name, tt.framework, tt.epType)
+		}
+	}
+
+	if ep := got["GET /users/:id"]; ep.FilePath != "api/routes.go" || ep.Trigger.RegisteredAt != "api/routes.go:16" || ep.ID == "" {
+		t.Errorf("GetUser = %+v, trigger %+v", ep, ep.Trigger)
+	}
+	if ep := got["ANY /missing"]; ep.ID != "" || ep.FilePath != "web/mux.go" {
+		t.Errorf("unresolved handler = %+v, want no ID and the registration site", ep)
+	}
+	if ep := got["command migrate"]; !ep.Trigger.Inline {
+		t.Error("inline cobra Run should be marked inline")
+	}
+	if _, ok := got["command app"]; ok {
+		t.Error("a cobra command without Run is not an entry point")
+	}
+	// A gin route passing a non-identifier (gin.WrapF(...)) or an inline
+	// function has no handler to report.
+	for _, key := range []string{"ANY /legacy", "GET /health"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("unexpected entry point %s", key)
+		}
+	}
+
+	// createUser also matches the gin signature pattern; it is reported
+	// once, with its trigger.
+	count := 0
+	for _, ep := range result.EntryPoints {
+		if ep.Name == "createUser" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("createUser reported %d times, want 1", count)
+	}
+}
+
+func TestEntryHeuristics_ImportsDisambiguate(t *testing.T) {
+	echoSource := strings.ReplaceAll(heuristicsGinSource, "github.com/gin-gonic/gin", "github.com/labstack/echo/v4")
+	g, idx := buildHeuristicsGraph(t, map[string]string{"api/routes.go": echoSource})
+
+	handlers, err := heuristicFinder(g, idx).FindByFramework(context.Background(), "echo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := triggered(t, handlers)
+	if _, ok := got["GET /users/:id"]; !ok {
+		t.Errorf("echo routes = %v, want GET /users/:id", keysOf(got))
+	}
+	if gin, _ := heuristicFinder(g, idx).FindByFramework(context.Background(), "gin"); len(triggered(t, gin)) != 0 {
+		t.Errorf("file without a gin import produced gin routes: %v", keysOf(triggered(t, gin)))
+	}
+}
+
+func TestEntryHeuristics_PythonAndExpress(t *testing.T) {
+	g, idx := buildHeuristicsGraph(t, map[string]string{
+		"app/views.py":     heuristicsFlaskSource,
+		"app/orders.py":    heuristicsFastAPISource,
+		"server/routes.js": heuristicsExpressSource,
+	})
+
+	handlers, err := heuristicFinder(g, idx).FindHandlers(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := triggered(t, handlers)
+
+	want := map[string]string{
+		"GET /items":      "items",
+		"POST /items":     "items",
+		"GET /items/<id>": "item",
+		"GET /health":     "health",
+		"POST /orders":    "create_order",
+		"GET /users":      "listUsers",
+	}
+	for key, name := range want {
+		if ep, ok := got[key]; !ok || ep.Name != name {
+			t.Errorf("%s = %+v, want handler %s (have %v)", key, ep, name, keysOf(got))
+		}
+	}
+	if got["POST /orders"].Framework != "fastapi" || got["GET /items/<id>"].Framework != "flask" {
+		t.Error("framework should follow the file's imports")
+	}
+	if _, ok := got["GET /dynamic/{x}"]; ok {
+		t.Error("f-string routes cannot be resolved and should be skipped")
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %v, want exactly %d routes", keysOf(got), len(want))
+	}
+}
+
+func TestEntryHeuristics_Filters(t *testing.T) {
+	g, idx := buildHeuristicsGraph(t, map[string]string{
+		"api/routes.go": heuristicsGinSource,
+		"cmd/root.go":   heuristicsCobraSource,
+	})
+	finder := heuristicFinder(g, idx)
+
+	opts := DefaultEntryPointOptions()
+	opts.Type = EntryPointScheduled
+	result, err := finder.FindEntryPoints(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.EntryPoints) != 1 || result.EntryPoints[0].Name != "cleanup" {
+		t.Errorf("scheduled = %+v, want only cleanup", result.EntryPoints)
+	}
+
+	opts = DefaultEntryPointOptions()
+	opts.Package = "cmd"
+	opts.Limit = 1
+	result, err = finder.FindEntryPoints(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.EntryPoints) != 1 || !result.Truncated || result.EntryPoints[0].Package != "cmd" {
+		t.Errorf("limited = %+v (truncated %v)", result.EntryPoints, result.Truncated)
+	}
+
+	registry := NewEntryPointRegistry()
+	registry.SetHeuristics(nil)
+	result, err = NewEntryPointFinder(g, idx).WithRegistry(registry).FindEntryPoints(context.Background(), DefaultEntryPointOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := triggered(t, result.EntryPoints); len(got) != 0 {
+		t.Errorf("heuristics disabled but got %v", keysOf(got))
+	}
+}
+
+func TestHTTPRoute(t *testing.T) {
+	tests := []struct {
+		name       string
+		literals   []string
+		def        string
+		wantMethod string
+		wantPath   string
+		wantOK     bool
+	}{
+		{"GET", []string{"/x"}, "", "GET", "/x", true},
+		{"get", []string{"/x"}, "", "GET", "/x", true},
+		{"Any", []string{"/x"}, "", "ANY", "/x", true},
+		{"all", []string{"/x"}, "", "ANY", "/x", true},
+		{"HandleFunc", []string{"/x"}, "", "ANY", "/x", true},
+		{"route", []string{"/x"}, "get", "GET", "/x", true},
+		{"HandleFunc", []string{"DELETE /x/{id}"}, "", "DELETE", "/x/{id}", true},
+		{"Method", []string{"patch", "/x"}, "", "PATCH", "/x", true},
+		{"Get", []string{"cache-key"}, "", "", "", false},
+		{"GET", nil, "", "", "", false},
+	}
+	for _, tt := range tests {
+		method, path, ok := httpRoute(tt.name, tt.literals, tt.def)
+		if method != tt.wantMethod || path != tt.wantPath || ok != tt.wantOK {
+			t.Errorf("httpRoute(%s, %v) = %s %s %v, want %s %s %v", tt.name, tt.literals, method, path, ok, tt.wantMethod, tt.wantPath, tt.wantOK)
+		}
+	}
+}
+
+func TestParseEntryHeuristics(t *testing.T) {
+	if len(DefaultEntryHeuristics()) == 0 {
+		t.Fatal("embedded heuristics failed to load")
+	}
+
+	bad := map[string]string{
+		"unknown key":     "heuristics:\n  - name: x\n    language: go\n    match: call\n    type: handler\n    trigger: http\n    methods: [GET]\n    method: [GET]\n",
+		"missing methods": "heuristics:\n  - name: x\n    language: go\n    match: call\n    type: handler\n    trigger: http\n",
+		"bad trigger":     "heuristics:\n  - name: x\n    language: go\n    match: call\n    type: handler\n    trigger: queue\n    methods: [GET]\n",
+		"bad glob":        "heuristics:\n  - name: x\n    language: python\n    match: decorator\n    type: handler\n    trigger: http\n    decorators: [\"[\"]\n",
+		"test type":       "heuristics:\n  - name: x\n    language: go\n    match: call\n    type: test\n    trigger: http\n    methods: [GET]\n",
+		"no struct type":  "heuristics:\n  - name: x\n    language: go\n    match: struct_literal\n    type: command\n    trigger: command\n",
+	}
+	for name, data := range bad {
+		if _, err := ParseEntryHeuristics([]byte(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	file, err := ParseEntryHeuristics([]byte("heuristics:\n  - name: x\n    language: Go\n    match: call\n    type: handler\n    trigger: http\n    methods: [Route]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Heuristics[0].Language != "go" {
+		t.Errorf("language = %q, want lower-cased", file.Heuristics[0].Language)
+	}
+}
+
+func TestConfiguredEntryHeuristics_InHouseFramework(t *testing.T) {
+	dir := t.TempDir()
+	custom := `heuristics:
+  - name: acme-rpc
+    language: go
+    framework: acme
+    match: call
+    type: handler
+    trigger: http
+    methods: [Expose]
+    default_method: POST
+`
+	path := filepath.Join(dir, "heuristics.yaml")
+	if err := os.WriteFile(path, []byte(custom), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	merged, err := LoadEntryHeuristics(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if merged[0].Name != "acme-rpc" || len(merged) != len(DefaultEntryHeuristics())+1 {
+		t.Errorf("merged = %d heuristics starting with %s", len(merged), merged[0].Name)
+	}
+
+	t.Setenv(EntryHeuristicsEnv, path)
+	ResetEntryHeuristics()
+	t.Cleanup(ResetEntryHeuristics)
+
+	g, idx := buildHeuristicsGraph(t, map[string]string{"rpc/svc.go": `package rpc
+
+func ping() {}
+
+func Init(s *acme.Server) {
+	s.Expose("/ping", ping)
+}
+`})
+	handlers, err := NewEntryPointFinder(g, idx).FindByFramework(context.Background(), "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := triggered(t, handlers)
+	if ep, ok := got["POST /ping"]; !ok || ep.Name != "ping" || ep.Trigger.Heuristic != "acme-rpc" {
+		t.Errorf("acme routes = %v", keysOf(got))
+	}
+
+	if err := os.WriteFile(path, []byte("replace_defaults: true\n"+custom), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	replaced, err := LoadEntryHeuristics(path)
+	if err != nil || len(replaced) != 1 {
+		t.Errorf("replace_defaults = %d heuristics, %v; want 1", len(replaced), err)
+	}
+
+	t.Setenv(EntryHeuristicsEnv, filepath.Join(dir, "missing.yaml"))
+	ResetEntryHeuristics()
+	if got := ConfiguredEntryHeuristics(); len(got) != len(DefaultEntryHeuristics()) {
+		t.Errorf("missing file should fall back to defaults, got %d heuristics", len(got))
+	}
+}
+
+func keysOf(m map[string]EntryPoint) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -79,15 +79,19 @@ type EntryPointPatterns struct {
 	GRPC []PatternMatcher
 }
 
-// EntryPointRegistry holds patterns for all supported languages.
+// EntryPointRegistry holds patterns for all supported languages and the
+// framework heuristics applied across them.
 type EntryPointRegistry struct {
-	patterns map[string]*EntryPointPatterns
+	patterns   map[string]*EntryPointPatterns
+	heuristics []FrameworkHeuristic
 }
 
-// NewEntryPointRegistry creates a new registry with default patterns.
+// NewEntryPointRegistry creates a new registry with default patterns and
+// the configured framework heuristics (see ConfiguredEntryHeuristics).
 func NewEntryPointRegistry() *EntryPointRegistry {
 	r := &EntryPointRegistry{
-		patterns: make(map[string]*EntryPointPatterns),
+		patterns:   make(map[string]*EntryPointPatterns),
+		heuristics: ConfiguredEntryHeuristics(),
 	}
 
 	// Register default patterns
@@ -110,6 +114,16 @@ func (r *EntryPointRegistry) RegisterPatterns(language string, patterns *EntryPo
 	r.patterns[strings.ToLower(language)] = patterns
 }
 
+// Heuristics returns the framework heuristics, in the order they are tried.
+func (r *EntryPointRegistry) Heuristics() []FrameworkHeuristic {
+	return r.heuristics
+}
+
+// SetHeuristics replaces the framework heuristics. Pass nil to disable them.
+func (r *EntryPointRegistry) SetHeuristics(heuristics []FrameworkHeuristic) {
+	r.heuristics = heuristics
+}
+
 // Languages returns all registered languages.
 func (r *EntryPointRegistry) Languages() []string {
 	langs := make([]string, 0, len(r.patterns))
@@ -12,7 +12,6 @@ package explore
 
 import (
 	"context"
-	"sort"
 	"strings"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
@@ -154,6 +153,27 @@ func (f *EntryPointFinder) FindEntryPoints(ctx context.Context, opts EntryPointO
 		EntryPoints: make([]EntryPoint, 0),
 	}
 
+	// Framework registrations first: they carry trigger metadata, so a
+	// handler they find is not reported again by the signature patterns.
+	heuristic, canceled := f.findHeuristicEntryPoints(ctx, opts)
+	covered := make(map[string]bool, len(heuristic))
+	for _, ep := range heuristic {
+		if ep.ID != "" {
+			covered[ep.ID] = true
+		}
+	}
+	if opts.Limit > 0 && len(heuristic) > opts.Limit {
+		heuristic = heuristic[:opts.Limit]
+		result.Truncated = true
+	}
+	result.EntryPoints = append(result.EntryPoints, heuristic...)
+	result.TotalFound = len(heuristic)
+	if canceled {
+		result.Truncated = true
+		sortEntryPoints(result.EntryPoints)
+		return result, nil
+	}
+
 	// Process each symbol kind that could be an entry point
 	symbolKinds := []ast.SymbolKind{
 		ast.SymbolKindFunction,
@@ -164,6 +184,10 @@ func (f *EntryPointFinder) FindEntryPoints(ctx context.Context, opts EntryPointO
 
 	checkCounter := 0
 	for _, kind := range symbolKinds {
+		if result.Truncated || (opts.Limit > 0 && len(result.EntryPoints) >= opts.Limit) {
+			result.Truncated = result.Truncated || len(result.EntryPoints) > 0
+			break
+		}
 		symbols := f.index.GetByKind(kind)
 		for _, sym := range symbols {
 			checkCounter++
@@ -185,6 +209,10 @@ func (f *EntryPointFinder) FindEntryPoints(ctx context.Context, opts EntryPointO
 				continue
 			}
 
+			if covered[sym.ID] {
+				continue
+			}
+
 			// Check if symbol matches any entry point pattern
 			entryPoint, matched := f.matchEntryPoint(sym, opts.Type)
 			if !matched {
@@ -212,12 +240,7 @@ func (f *EntryPointFinder) FindEntryPoints(ctx context.Context, opts EntryPointO
 	}
 
 	// Sort by file path, then line number
-	sort.Slice(result.EntryPoints, func(i, j int) bool {
-		if result.EntryPoints[i].FilePath != result.EntryPoints[j].FilePath {
-			return result.EntryPoints[i].FilePath < result.EntryPoints[j].FilePath
-		}
-		return result.EntryPoints[i].Line < result.EntryPoints[j].Line
-	})
+	sortEntryPoints(result.EntryPoints)
 
 	// Update total count from stats if we didn't hit any matches
 	if result.TotalFound == 0 {
@@ -0,0 +1,255 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package explore
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// serializationTagKeys are the struct tag keys of serialization formats.
+var serializationTagKeys = []string{"json", "xml", "yaml", "toml", "bson", "msgpack", "protobuf"}
+
+// serializerPackages maps the packages whose Marshal functions serialize
+// their argument to the format they produce.
+var serializerPackages = map[string]string{
+	"json":      "json",
+	"xml":       "xml",
+	"yaml":      "yaml",
+	"toml":      "toml",
+	"bson":      "bson",
+	"msgpack":   "msgpack",
+	"proto":     "protobuf",
+	"protojson": "json",
+	"gob":       "gob",
+}
+
+// serializerMethods maps response writer methods that serialize their
+// argument (gin, echo, fiber: c.JSON(200, v)) to their format.
+var serializerMethods = map[string]string{
+	"JSON":         "json",
+	"IndentedJSON": "json",
+	"XML":          "xml",
+	"YAML":         "yaml",
+}
+
+// fieldFlowLimitations document the precision of field-level tracing.
+var fieldFlowLimitations = []string{
+	"Field accesses are resolved by field name and declared variable types, without type checking",
+	"Flow beyond the functions that access the field is followed one call hop, at function precision",
+	"Structs with more fields than the builder's field cap are not tracked",
+	"Go struct fields only",
+}
+
+// traceFieldFlow traces the data flow of a Go struct field.
+//
+// Description:
+//
+//	Lists the functions that write and read the field (from field access
+//	REFERENCES edges), then follows one call hop: callers of writers,
+//	which supply the written value as a parameter, and callers and
+//	callees of readers, which receive it as a return value or parameter.
+//	Accessors and hop targets are classified as sources, transforms and
+//	sinks as in TraceDataFlow. Serialization points are the field's
+//	serialization struct tags and serializer calls (json.Marshal,
+//	c.JSON) in the traced functions.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	field - The field node.
+//	maxNodes - Maximum functions to visit.
+//
+// Outputs:
+//
+//	*DataFlow - The flow with Precision "field".
+//	int - The number of functions visited.
+//	error - ErrContextCanceled if ctx was canceled.
+func (t *DataFlowTracer) traceFieldFlow(ctx context.Context, field *graph.Node, maxNodes int) (*DataFlow, int, error) {
+	flow := &DataFlow{
+		Sources:             make([]DataPoint, 0),
+		Transforms:          make([]DataPoint, 0),
+		Sinks:               make([]DataPoint, 0),
+		Path:                []string{field.ID},
+		Precision:           "field",
+		Writers:             make([]DataPoint, 0),
+		Readers:             make([]DataPoint, 0),
+		SerializationPoints: fieldTagSerializationPoints(field.Symbol),
+		Limitations:         append([]string(nil), fieldFlowLimitations...),
+	}
+
+	visited := map[string]bool{field.ID: true}
+	var traced []*graph.Node
+	visit := func(id string) bool {
+		if visited[id] {
+			return false
+		}
+		if len(traced) >= maxNodes {
+			return false
+		}
+		node, ok := t.graph.GetNode(id)
+		if !ok || node.Symbol == nil {
+			return false
+		}
+		visited[id] = true
+		traced = append(traced, node)
+		t.classifyNode(node, flow)
+		flow.Path = append(flow.Path, id)
+		return true
+	}
+
+	var writers, readers []*graph.Node
+	for _, edge := range field.Incoming {
+		if edge.Type != graph.EdgeTypeReferences || edge.Provenance.Pass != graph.PassFieldAccess {
+			continue
+		}
+		from, ok := t.graph.GetNode(edge.FromID)
+		if !ok || from.Symbol == nil {
+			continue
+		}
+		point := DataPoint{
+			ID:         from.ID,
+			Name:       from.Symbol.Name,
+			Location:   fmt.Sprintf("%s:%d", edge.Location.FilePath, edge.Location.StartLine),
+			Category:   edge.Provenance.Detail,
+			Confidence: fieldAccessConfidence(edge.Provenance.Confidence),
+		}
+		if edge.Provenance.Detail != graph.FieldAccessRead {
+			point.Type = "writer"
+			flow.Writers = append(flow.Writers, point)
+			writers = append(writers, from)
+		}
+		if edge.Provenance.Detail != graph.FieldAccessWrite {
+			point.Type = "reader"
+			flow.Readers = append(flow.Readers, point)
+			readers = append(readers, from)
+		}
+		visit(from.ID)
+	}
+	if len(writers) == 0 && len(readers) == 0 {
+		flow.Limitations = append(flow.Limitations,
+			"No resolved accesses: the field may be accessed only through embedding, reflection or untyped expressions")
+	}
+
+	// One hop: where written values come from, where read values go.
+	hop := func(nodes []*graph.Node, callers, callees bool) {
+		for _, node := range nodes {
+			if callers {
+				for _, edge := range node.Incoming {
+					if edge.Type == graph.EdgeTypeCalls {
+						visit(edge.FromID)
+					}
+				}
+			}
+			if callees {
+				for _, edge := range node.Outgoing {
+					if edge.Type == graph.EdgeTypeCalls {
+						visit(edge.ToID)
+					}
+				}
+			}
+		}
+	}
+	hop(writers, true, false)
+	hop(readers, true, true)
+	if err := ctx.Err(); err != nil {
+		return nil, len(traced), ErrContextCanceled
+	}
+	if len(traced) >= maxNodes {
+		flow.Limitations = append(flow.Limitations,
+			fmt.Sprintf("Traversal truncated at %d nodes", maxNodes))
+	}
+
+	for _, node := range traced {
+		flow.SerializationPoints = append(flow.SerializationPoints, serializerCallPoints(node.Symbol)...)
+	}
+	return flow, len(traced), nil
+}
+
+// fieldAccessConfidence maps a field access edge's confidence level to a
+// DataPoint confidence.
+func fieldAccessConfidence(c graph.EdgeConfidence) float64 {
+	switch c {
+	case graph.ConfidenceHigh:
+		return 0.9
+	case graph.ConfidenceMedium:
+		return 0.6
+	}
+	return 0.3
+}
+
+// fieldTagSerializationPoints returns a serialization point per
+// serialization key in the field's struct tag. A "-" name means the
+// format skips the field and is reported with Type "excluded".
+func fieldTagSerializationPoints(field *ast.Symbol) []DataPoint {
+	points := make([]DataPoint, 0)
+	if field == nil || field.Metadata == nil || field.Metadata.StructTag == "" {
+		return points
+	}
+	tag := reflect.StructTag(field.Metadata.StructTag)
+	for _, key := range serializationTagKeys {
+		value, ok := tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		point := DataPoint{
+			ID:         field.ID,
+			Type:       "struct_tag",
+			Name:       fmt.Sprintf("%s:%q", key, value),
+			Location:   fmt.Sprintf("%s:%d", field.FilePath, field.StartLine),
+			Category:   key,
+			Confidence: 1.0,
+		}
+		if name, _, _ := strings.Cut(value, ","); name == "-" {
+			point.Type = "excluded"
+		}
+		points = append(points, point)
+	}
+	return points
+}
+
+// serializerCallPoints returns the serializer calls made by fn.
+func serializerCallPoints(fn *ast.Symbol) []DataPoint {
+	var points []DataPoint
+	for _, call := range fn.Calls {
+		format := ""
+		switch {
+		case call.Target == "Marshal" || call.Target == "MarshalIndent":
+			format = serializerPackages[call.Receiver]
+		case call.Target == "Encode" && strings.Contains(call.Receiver, "Encoder"):
+			format, _, _ = strings.Cut(call.Receiver, ".")
+			format = serializerPackages[format]
+		case call.IsMethod:
+			format = serializerMethods[call.Target]
+		}
+		if format == "" {
+			continue
+		}
+		name := call.Target
+		if call.Receiver != "" {
+			name = call.Receiver + "." + call.Target
+		}
+		points = append(points, DataPoint{
+			ID:         fn.ID,
+			Type:       "serializer_call",
+			Name:       name,
+			Location:   fmt.Sprintf("%s:%d", call.Location.FilePath, call.Location.StartLine),
+			Category:   format,
+			Confidence: 0.6,
+		})
+	}
+	return points
+}
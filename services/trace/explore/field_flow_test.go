@@ -0,0 +1,117 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package explore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+const fieldFlowTestGo = `package users
+
+import "encoding/json"
+
+type User struct {
+	Email    string ` + "`json:\"email\"`" + `
+	Password string ` + "`json:\"-\"`" + `
+}
+
+func NewUser(email string) *User {
+	return &User{Email: email}
+}
+
+func Signup(form map[string]string) *User {
+	return NewUser(form["email"])
+}
+
+func Render(u *User) ([]byte, error) {
+	return json.Marshal(u.Email)
+}
+
+func Handle() {
+	Render(Signup(nil))
+}
+`
+
+func TestTraceDataFlow_Field(t *testing.T) {
+	ctx := context.Background()
+	parsed, err := ast.NewGoParser().Parse(ctx, []byte(fieldFlowTestGo), "users/user.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := graph.NewBuilder().Build(ctx, []*ast.ParseResult{parsed})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var emailID, passwordID string
+	for _, sym := range parsed.Symbols {
+		for _, child := range sym.Children {
+			switch child.Name {
+			case "Email":
+				emailID = child.ID
+			case "Password":
+				passwordID = child.ID
+			}
+		}
+	}
+
+	tracer := NewDataFlowTracer(result.Graph, index.NewSymbolIndex())
+	flow, err := tracer.TraceDataFlow(ctx, emailID)
+	if err != nil {
+		t.Fatalf("TraceDataFlow: %v", err)
+	}
+	if flow.Precision != "field" {
+		t.Errorf("Precision = %q, want field", flow.Precision)
+	}
+	if len(flow.Writers) != 1 || flow.Writers[0].Name != "NewUser" {
+		t.Errorf("Writers = %+v, want NewUser", flow.Writers)
+	}
+	if len(flow.Readers) != 1 || flow.Readers[0].Name != "Render" {
+		t.Errorf("Readers = %+v, want Render", flow.Readers)
+	}
+
+	// One hop: Signup calls the writer, Handle calls the reader.
+	onPath := make(map[string]bool)
+	for _, id := range flow.Path {
+		if node, ok := result.Graph.GetNode(id); ok {
+			onPath[node.Symbol.Name] = true
+		}
+	}
+	for _, name := range []string{"Email", "NewUser", "Render", "Signup", "Handle"} {
+		if !onPath[name] {
+			t.Errorf("%s missing from path %v", name, flow.Path)
+		}
+	}
+
+	kinds := make(map[string]string)
+	for _, p := range flow.SerializationPoints {
+		kinds[p.Type] = p.Name
+	}
+	if kinds["struct_tag"] != `json:"email"` || kinds["serializer_call"] != "json.Marshal" {
+		t.Errorf("SerializationPoints = %+v", flow.SerializationPoints)
+	}
+
+	password, err := tracer.TraceDataFlow(ctx, passwordID)
+	if err != nil {
+		t.Fatalf("TraceDataFlow(Password): %v", err)
+	}
+	if len(password.SerializationPoints) != 1 || password.SerializationPoints[0].Type != "excluded" {
+		t.Errorf("Password serialization = %+v, want excluded json tag", password.SerializationPoints)
+	}
+	if len(password.Writers)+len(password.Readers) != 0 {
+		t.Errorf("Password has no accesses, got %+v %+v", password.Writers, password.Readers)
+	}
+}
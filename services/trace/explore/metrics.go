@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -88,7 +89,7 @@ func startTraceSpan(ctx context.Context, operation, symbolID string) (context.Co
 	return tracer.Start(ctx, "DataFlowTracer."+operation,
 		trace.WithAttributes(
 			attribute.String("explore.operation", operation),
-			attribute.String("explore.symbol_id", symbolID),
+			telemetry.SensitiveString("explore.symbol_id", symbolID),
 		),
 	)
 }
@@ -486,6 +486,7 @@ func (s *PackageAPISummarizer) FindPackageAPI(ctx context.Context, packagePath s
 				Name:      sym.Name,
 				Signature: sym.Signature,
 				DocString: sym.DocComment,
+				FilePath:  sym.FilePath,
 			})
 		}
 	}
@@ -42,6 +42,9 @@ const (
 	// EntryPointGRPC represents gRPC service implementations.
 	EntryPointGRPC EntryPointType = "grpc"
 
+	// EntryPointScheduled represents jobs registered with a scheduler (cron).
+	EntryPointScheduled EntryPointType = "scheduled"
+
 	// EntryPointAll is a special value for finding all entry point types.
 	EntryPointAll EntryPointType = "all"
 )
@@ -74,6 +77,56 @@ type EntryPoint struct {
 
 	// Package is the package/module containing this entry point.
 	Package string `json:"package,omitempty"`
+
+	// Trigger describes how the entry point is invoked (route, command name,
+	// schedule). Only set for entry points found by a framework heuristic.
+	Trigger *EntryPointTrigger `json:"trigger,omitempty"`
+}
+
+// TriggerKind categorizes what invokes an entry point.
+type TriggerKind string
+
+const (
+	// TriggerHTTP is an HTTP route.
+	TriggerHTTP TriggerKind = "http"
+
+	// TriggerCommand is a CLI command or subcommand.
+	TriggerCommand TriggerKind = "command"
+
+	// TriggerSchedule is a scheduled job.
+	TriggerSchedule TriggerKind = "schedule"
+)
+
+// EntryPointTrigger describes how a heuristically detected entry point is invoked.
+type EntryPointTrigger struct {
+	// Kind is the trigger category.
+	Kind TriggerKind `json:"kind"`
+
+	// Method is the HTTP method (GET, POST, ...), or "ANY". HTTP only.
+	Method string `json:"method,omitempty"`
+
+	// Path is the route path as registered, without any group prefix. HTTP only.
+	Path string `json:"path,omitempty"`
+
+	// Command is the command name. Command only.
+	Command string `json:"command,omitempty"`
+
+	// Schedule is the schedule spec, e.g. "@every 1h" or "0 * * * *". Schedule only.
+	Schedule string `json:"schedule,omitempty"`
+
+	// Heuristic is the name of the heuristic that matched.
+	Heuristic string `json:"heuristic"`
+
+	// RegisteredIn is the ID of the symbol containing the registration.
+	// Equal to the entry point ID for decorators.
+	RegisteredIn string `json:"registered_in"`
+
+	// RegisteredAt is the "file:line" of the registration.
+	RegisteredAt string `json:"registered_at"`
+
+	// Inline is true when the handler is an anonymous function; the entry
+	// point then names the symbol that contains it.
+	Inline bool `json:"inline,omitempty"`
 }
 
 // EntryPointResult contains the result of an entry point search.
@@ -178,9 +231,23 @@ type DataFlow struct {
 	// Path contains the ordered function calls in the flow.
 	Path []string `json:"path"`
 
-	// Precision indicates analysis precision ("function" or "variable").
+	// Precision indicates analysis precision ("function", "variable" or
+	// "field").
 	Precision string `json:"precision"`
 
+	// Writers contains the functions that write the traced struct field.
+	// Only set for field-level traces.
+	Writers []DataPoint `json:"writers,omitempty"`
+
+	// Readers contains the functions that read the traced struct field.
+	// Only set for field-level traces.
+	Readers []DataPoint `json:"readers,omitempty"`
+
+	// SerializationPoints contains the field's serialization struct tags
+	// and the serializer calls (json.Marshal, c.JSON) in the traced
+	// functions. Only set for field-level traces.
+	SerializationPoints []DataPoint `json:"serialization_points,omitempty"`
+
 	// Limitations documents what we couldn't track.
 	Limitations []string `json:"limitations,omitempty"`
 }
@@ -273,6 +340,9 @@ type APISymbol struct {
 
 	// DocString is the documentation comment.
 	DocString string `json:"doc_string,omitempty"`
+
+	// FilePath is the file declaring the symbol.
+	FilePath string `json:"file_path,omitempty"`
 }
 
 // PackageAPI represents the public API of a package.
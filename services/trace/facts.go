@@ -0,0 +1,508 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// DefaultMaxInjectedFacts caps the facts given to one agent run.
+const DefaultMaxInjectedFacts = 8
+
+// factEventsTotal counts facts proposed by agent sessions, given to runs
+// and invalidated by rebuilds.
+var factEventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "trace_facts_total",
+	Help: "Project facts by event (stored, unanchored, applied, invalidated)",
+}, []string{"event"})
+
+// serviceFactRecorder is the phases.FactRecorder of a session's graph.
+type serviceFactRecorder struct {
+	svc     *Service
+	graphID string
+}
+
+// ProposeFact implements phases.FactRecorder.
+func (r serviceFactRecorder) ProposeFact(ctx context.Context, c facts.Candidate) (*facts.Fact, error) {
+	return r.svc.ProposeFact(ctx, r.graphID, c)
+}
+
+// FactsApplied implements phases.FactRecorder.
+func (r serviceFactRecorder) FactsApplied(ctx context.Context, sessionID string, ids []string) {
+	r.svc.RecordFactsApplied(ctx, r.graphID, sessionID, ids)
+}
+
+// SetFactStore sets the store project facts are persisted in.
+//
+// Inputs:
+//
+//	store - The store. Nil keeps facts in memory only.
+func (s *Service) SetFactStore(store facts.Store) {
+	if store == nil {
+		store = facts.NewMemoryStore()
+	}
+	s.factStore = store
+}
+
+// ProposeFact stores a fact a user stated in an agent session.
+//
+// Description:
+//
+//	The candidate's mentions are resolved against the graph: a stable or
+//	node ID, a file, a directory (anchored to its package), a qualified
+//	name such as "Server.Handle", or a name only one symbol has. A
+//	candidate that resolves to no symbol is not stored. Stating a fact
+//	again updates it and makes it active again.
+//
+// Inputs:
+//
+//	graphID - The session's graph.
+//	c - The candidate.
+//
+// Outputs:
+//
+//	*facts.Fact - The stored fact.
+//	error - ErrFactUnanchored, a GetGraph error, or a store error.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ProposeFact(ctx context.Context, graphID string, c facts.Candidate) (*facts.Fact, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	statement, err := facts.NormalizeStatement(c.Statement)
+	if err != nil {
+		return nil, err
+	}
+	anchors := resolveFactMentions(cached, c.Mentions)
+	if len(anchors) == 0 {
+		factEventsTotal.WithLabelValues("unanchored").Inc()
+		return nil, fmt.Errorf("%w: %s", ErrFactUnanchored, strings.Join(c.Mentions, ", "))
+	}
+
+	s.factsMu.Lock()
+	defer s.factsMu.Unlock()
+	now := time.Now().UnixMilli()
+	f := facts.Fact{ID: facts.IDFor(statement), CreatedAtMilli: now}
+	if existing, ok, err := s.findFact(ctx, cached.ProjectRoot, f.ID); err != nil {
+		return nil, err
+	} else if ok {
+		f = existing
+	}
+	f.Statement = statement
+	f.Kind = c.Kind
+	f.Anchors = anchors
+	f.SessionID = c.SessionID
+	f.ConfirmedBy = c.ConfirmedBy
+	f.UpdatedAtMilli = now
+	f.State = facts.StateActive
+	f.InvalidatedReason, f.InvalidatedAtMilli = "", 0
+	if err := s.factStore.Put(ctx, cached.ProjectRoot, f); err != nil {
+		return nil, err
+	}
+	factEventsTotal.WithLabelValues("stored").Inc()
+	return &f, nil
+}
+
+// ListFacts returns the facts of a graph's project.
+//
+// Inputs:
+//
+//	graphID - The graph.
+//	includeInvalidated - Whether to list invalidated facts too.
+//
+// Outputs:
+//
+//	[]facts.Fact - Most recently updated first.
+//	string - The project root.
+//	error - A GetGraph error or a store error.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ListFacts(ctx context.Context, graphID string, includeInvalidated bool) ([]facts.Fact, string, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, "", err
+	}
+	list, err := s.factStore.List(ctx, cached.ProjectRoot)
+	if err != nil {
+		return nil, "", err
+	}
+	out := make([]facts.Fact, 0, len(list))
+	for _, f := range list {
+		if includeInvalidated || f.Active() {
+			out = append(out, f)
+		}
+	}
+	return out, cached.ProjectRoot, nil
+}
+
+// UpdateFact edits a fact of a graph's project.
+//
+// Description:
+//
+//	A new statement replaces the old one; new symbol IDs, in any form the
+//	graph resolves, replace the anchors. Either makes the fact active
+//	again, after checking its anchors against the graph, and marks it as
+//	confirmed through the API.
+//
+// Inputs:
+//
+//	graphID - The graph used to resolve symbol IDs.
+//	id - The fact.
+//	statement - The new statement, or nil to keep it.
+//	symbolIDs - The new anchors, or empty to keep them.
+//
+// Outputs:
+//
+//	facts.Fact - The edited fact.
+//	error - ErrFactNotFound, ErrFactSymbolNotFound, facts.ErrInvalidFact,
+//	  a GetGraph error, or a store error.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) UpdateFact(ctx context.Context, graphID, id string, statement *string, symbolIDs []string) (facts.Fact, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return facts.Fact{}, err
+	}
+	var normalized string
+	if statement != nil {
+		if normalized, err = facts.NormalizeStatement(*statement); err != nil {
+			return facts.Fact{}, err
+		}
+	}
+	var anchors []facts.Anchor
+	for _, symbolID := range symbolIDs {
+		stableID, ok := cached.Graph.StableIDFor(symbolID)
+		if !ok {
+			return facts.Fact{}, fmt.Errorf("%w: %s", ErrFactSymbolNotFound, symbolID)
+		}
+		node, _ := cached.Graph.GetNodeByStableID(stableID)
+		anchors = append(anchors, factAnchor(node.Symbol))
+	}
+
+	s.factsMu.Lock()
+	defer s.factsMu.Unlock()
+	f, ok, err := s.findFact(ctx, cached.ProjectRoot, id)
+	if err != nil {
+		return facts.Fact{}, err
+	}
+	if !ok {
+		return facts.Fact{}, fmt.Errorf("%w: %s", ErrFactNotFound, id)
+	}
+	if statement != nil {
+		f.Statement = normalized
+	}
+	if len(anchors) > 0 {
+		f.Anchors = anchors
+	}
+	f.ConfirmedBy = facts.ConfirmedByAPI
+	f.UpdatedAtMilli = time.Now().UnixMilli()
+	f.State = facts.StateActive
+	f.InvalidatedReason, f.InvalidatedAtMilli = "", 0
+	if reason := factInvalidation(cached.Graph, f); reason != "" {
+		f.State, f.InvalidatedReason, f.InvalidatedAtMilli = facts.StateInvalidated, reason, f.UpdatedAtMilli
+	}
+	if err := s.factStore.Put(ctx, cached.ProjectRoot, f); err != nil {
+		return facts.Fact{}, err
+	}
+	return f, nil
+}
+
+// DeleteFact deletes a fact of a graph's project.
+//
+// Outputs:
+//
+//	error - ErrFactNotFound, a GetGraph error, or a store error.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) DeleteFact(ctx context.Context, graphID, id string) error {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return err
+	}
+	s.factsMu.Lock()
+	defer s.factsMu.Unlock()
+	if _, ok, err := s.findFact(ctx, cached.ProjectRoot, id); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("%w: %s", ErrFactNotFound, id)
+	}
+	return s.factStore.Delete(ctx, cached.ProjectRoot, id)
+}
+
+// KnownFacts returns the active facts of a graph's project most worth
+// giving to a query, ranked by facts.Rank.
+//
+// Inputs:
+//
+//	graphID - The session's graph.
+//	query - The session's query.
+//	limit - The most facts to return.
+//
+// Outputs:
+//
+//	[]facts.Fact - The facts. Nil if the graph or store is unavailable.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) KnownFacts(ctx context.Context, graphID, query string, limit int) []facts.Fact {
+	list, _, err := s.ListFacts(ctx, graphID, false)
+	if err != nil {
+		slog.Warn("Project facts unavailable", slog.String("graph_id", graphID), telemetry.SensitiveLogError("error", err))
+		return nil
+	}
+	return facts.Rank(list, query, time.Now(), limit)
+}
+
+// RecordFactsApplied records that facts were given to an agent run, for
+// ranking and audit.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) RecordFactsApplied(ctx context.Context, graphID, sessionID string, ids []string) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil || len(ids) == 0 {
+		return
+	}
+	s.factsMu.Lock()
+	defer s.factsMu.Unlock()
+	list, err := s.factStore.List(ctx, cached.ProjectRoot)
+	if err != nil {
+		slog.Warn("Project facts unavailable", slog.String("graph_id", graphID), telemetry.SensitiveLogError("error", err))
+		return
+	}
+	applied := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		applied[id] = true
+	}
+	now := time.Now().UnixMilli()
+	for _, f := range list {
+		if !applied[f.ID] {
+			continue
+		}
+		f.UseCount++
+		f.LastUsedAtMilli = now
+		f.LastUsedSession = sessionID
+		if err := s.factStore.Put(ctx, cached.ProjectRoot, f); err != nil {
+			slog.Warn("Recording fact use failed", slog.String("fact_id", f.ID), telemetry.SensitiveLogError("error", err))
+			return
+		}
+		factEventsTotal.WithLabelValues("applied").Inc()
+	}
+}
+
+// invalidateFacts marks the facts of a newly installed working-tree graph's
+// project whose anchored symbols were removed or changed signature.
+//
+// Description:
+//
+//	Graphs built from a git ref describe an older tree and do not
+//	invalidate facts. Invalidated facts stay stored, with the reason,
+//	until they are edited or deleted.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) invalidateFacts(ctx context.Context, cached *CachedGraph) {
+	if cached.Graph == nil || cached.GitRef != "" || cached.ProjectRoot == "" {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	s.factsMu.Lock()
+	defer s.factsMu.Unlock()
+	list, err := s.factStore.List(ctx, cached.ProjectRoot)
+	if err != nil {
+		slog.Warn("Project facts unavailable, not checked against the graph",
+			telemetry.SensitiveLog("root", cached.ProjectRoot),
+			telemetry.SensitiveLogError("error", err))
+		return
+	}
+	now := time.Now().UnixMilli()
+	for _, f := range list {
+		if !f.Active() {
+			continue
+		}
+		reason := factInvalidation(cached.Graph, f)
+		if reason == "" {
+			continue
+		}
+		f.State, f.InvalidatedReason, f.InvalidatedAtMilli = facts.StateInvalidated, reason, now
+		if err := s.factStore.Put(ctx, cached.ProjectRoot, f); err != nil {
+			slog.Warn("Invalidating fact failed", slog.String("fact_id", f.ID), telemetry.SensitiveLogError("error", err))
+			return
+		}
+		factEventsTotal.WithLabelValues("invalidated").Inc()
+		slog.Info("Project fact invalidated", slog.String("fact_id", f.ID), slog.String("reason", reason))
+	}
+}
+
+// findFact returns the fact with the ID. Caller must hold factsMu.
+func (s *Service) findFact(ctx context.Context, projectRoot, id string) (facts.Fact, bool, error) {
+	list, err := s.factStore.List(ctx, projectRoot)
+	if err != nil {
+		return facts.Fact{}, false, err
+	}
+	for _, f := range list {
+		if f.ID == id {
+			return f, true, nil
+		}
+	}
+	return facts.Fact{}, false, nil
+}
+
+// factInvalidation returns why a fact no longer holds in g, or "" if all
+// its anchors are still there. An anchor whose stable ID is gone but whose
+// file still has a symbol of the same qualified name changed signature.
+func factInvalidation(g *graph.Graph, f facts.Fact) string {
+	for _, a := range f.Anchors {
+		if _, ok := g.GetNodeByStableID(a.StableID); ok {
+			continue
+		}
+		qualified := qualifiedSymbolName(a.StableID)
+		for _, node := range g.SymbolsInFile(a.FilePath) {
+			if node.Symbol != nil && node.Symbol.StableID != "" && qualifiedSymbolName(node.Symbol.StableID) == qualified {
+				return fmt.Sprintf("%s in %s changed signature", a.Name, a.FilePath)
+			}
+		}
+		return fmt.Sprintf("%s in %s was removed", a.Name, a.FilePath)
+	}
+	return ""
+}
+
+// resolveFactMentions anchors the mentions of a candidate fact to symbols
+// of the graph. Mentions that resolve to nothing, or to several symbols,
+// are skipped.
+func resolveFactMentions(cached *CachedGraph, mentions []string) []facts.Anchor {
+	var anchors []facts.Anchor
+	seen := make(map[string]bool)
+	for _, m := range mentions {
+		sym := resolveFactMention(cached, m)
+		if sym == nil || seen[sym.StableID] {
+			continue
+		}
+		seen[sym.StableID] = true
+		anchors = append(anchors, factAnchor(sym))
+	}
+	return anchors
+}
+
+// resolveFactMention returns the symbol one mention names, or nil.
+func resolveFactMention(cached *CachedGraph, mention string) *ast.Symbol {
+	g := cached.Graph
+	if stableID, ok := g.StableIDFor(mention); ok {
+		node, _ := g.GetNodeByStableID(stableID)
+		return node.Symbol
+	}
+
+	if strings.Contains(mention, "/") || path.Ext(mention) != "" {
+		clean := path.Clean(strings.TrimPrefix(mention, "./"))
+		if files := matchFactFiles(cached, clean, func(f string) string { return f }); len(files) == 1 {
+			return fileAnchorSymbol(g, files[0])
+		}
+		if dirs := matchFactFiles(cached, clean, path.Dir); len(dirs) == 1 {
+			return dirAnchorSymbol(cached, dirs[0])
+		}
+	}
+
+	name := mention
+	if i := strings.LastIndexByte(mention, '.'); i >= 0 {
+		name = mention[i+1:]
+	}
+	if cached.Index == nil || name == "" {
+		return nil
+	}
+	var match *ast.Symbol
+	for _, sym := range cached.Index.GetByName(name) {
+		if sym.StableID == "" || (name != mention && qualifiedSymbolName(sym.StableID) != mention) {
+			continue
+		}
+		if match != nil && match.StableID != sym.StableID {
+			return nil
+		}
+		match = sym
+	}
+	return match
+}
+
+// matchFactFiles returns the distinct keys of the graph's files equal to
+// mention or ending in "/"+mention, sorted.
+func matchFactFiles(cached *CachedGraph, mention string, key func(string) string) []string {
+	if cached.Index == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for _, f := range cached.Index.GetUniqueFilePaths() {
+		k := key(f)
+		if (k == mention || strings.HasSuffix(k, "/"+mention)) && !seen[k] {
+			seen[k] = true
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// fileAnchorSymbol returns the symbol a fact about a file is anchored to:
+// its package declaration, else its first symbol.
+func fileAnchorSymbol(g *graph.Graph, filePath string) *ast.Symbol {
+	var first *ast.Symbol
+	for _, node := range g.SymbolsInFile(filePath) {
+		sym := node.Symbol
+		if sym == nil || sym.StableID == "" {
+			continue
+		}
+		if sym.Kind == ast.SymbolKindPackage {
+			return sym
+		}
+		if first == nil || sym.StartLine < first.StartLine {
+			first = sym
+		}
+	}
+	return first
+}
+
+// dirAnchorSymbol returns the symbol a fact about a directory is anchored
+// to: that of its first file in path order.
+func dirAnchorSymbol(cached *CachedGraph, dir string) *ast.Symbol {
+	var files []string
+	for _, f := range cached.Index.GetUniqueFilePaths() {
+		if path.Dir(f) == dir {
+			files = append(files, f)
+		}
+	}
+	sort.Strings(files)
+	for _, f := range files {
+		if sym := fileAnchorSymbol(cached.Graph, f); sym != nil {
+			return sym
+		}
+	}
+	return nil
+}
+
+// factAnchor returns the anchor of a symbol.
+func factAnchor(sym *ast.Symbol) facts.Anchor {
+	name := qualifiedSymbolName(sym.StableID)
+	if name == "" {
+		name = sym.Name
+	}
+	return facts.Anchor{StableID: sym.StableID, Name: name, FilePath: sym.FilePath}
+}
@@ -0,0 +1,474 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package facts keeps the facts a user has confirmed or corrected about a
+// project, so that later agent sessions start from them.
+//
+// A fact is a short statement, such as "the retry logic lives in
+// pkg/transport", anchored to the stable IDs of the symbols it is about.
+// Facts are stored per project root and invalidated when an anchored
+// symbol disappears or changes signature.
+package facts
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxStatementLength caps Fact.Statement in bytes.
+const MaxStatementLength = 300
+
+// MaxMentions caps the symbol and path mentions taken from one statement.
+const MaxMentions = 8
+
+// RecencyHalfLife is the age at which a fact's recency score halves.
+const RecencyHalfLife = 30 * 24 * time.Hour
+
+// minStatementWords is the fewest words a detected statement may have;
+// shorter replies ("yes, exactly") confirm nothing that can be stored.
+const minStatementWords = 3
+
+// Kinds of fact.
+const (
+	// KindCorrection is a fact the user stated to correct the agent.
+	KindCorrection = "correction"
+
+	// KindConfirmation is a fact the user confirmed.
+	KindConfirmation = "confirmation"
+)
+
+// States of a fact.
+const (
+	// StateActive facts are given to agent sessions.
+	StateActive = "active"
+
+	// StateInvalidated facts have an anchor that no longer exists or whose
+	// signature changed. They are kept for review until edited or deleted.
+	StateInvalidated = "invalidated"
+)
+
+// How a fact was confirmed (Fact.ConfirmedBy).
+const (
+	// ConfirmedByClarification is a fact from the answer to a
+	// clarification question.
+	ConfirmedByClarification = "clarification"
+
+	// ConfirmedByFollowUp is a fact from a follow-up message on an
+	// answered query.
+	ConfirmedByFollowUp = "follow_up"
+
+	// ConfirmedByAPI is a fact last edited through the facts endpoints.
+	ConfirmedByAPI = "api"
+)
+
+// ErrInvalidFact indicates an empty or oversized statement.
+var ErrInvalidFact = errors.New("invalid fact")
+
+// Anchor is a symbol a fact is about.
+type Anchor struct {
+	// StableID is the symbol's stable ID when the fact was anchored.
+	StableID string `json:"stable_id"`
+
+	// Name is the symbol's scope-qualified name, e.g. "Server.Handle".
+	Name string `json:"name"`
+
+	// FilePath is the symbol's file, relative to the project root.
+	FilePath string `json:"file_path"`
+}
+
+// Fact is a statement about a project confirmed by a user.
+type Fact struct {
+	// ID identifies the fact within its project. It is derived from the
+	// statement when the fact is first stored and kept across edits.
+	ID string `json:"id"`
+
+	// Statement is the fact, one or two sentences.
+	Statement string `json:"statement"`
+
+	// Kind is KindCorrection or KindConfirmation.
+	Kind string `json:"kind"`
+
+	// Anchors are the symbols the fact is about. Never empty.
+	Anchors []Anchor `json:"anchors"`
+
+	// SessionID is the agent session the fact was stated in. Empty for
+	// facts created through the API.
+	SessionID string `json:"session_id,omitempty"`
+
+	// ConfirmedBy is how the user stated the fact: ConfirmedByClarification,
+	// ConfirmedByFollowUp or ConfirmedByAPI.
+	ConfirmedBy string `json:"confirmed_by"`
+
+	// CreatedAtMilli is when the fact was first stored (Unix milliseconds UTC).
+	CreatedAtMilli int64 `json:"created_at"`
+
+	// UpdatedAtMilli is when the fact was last stated or edited.
+	UpdatedAtMilli int64 `json:"updated_at"`
+
+	// State is StateActive or StateInvalidated.
+	State string `json:"state"`
+
+	// InvalidatedReason says which anchor invalidated the fact and how.
+	InvalidatedReason string `json:"invalidated_reason,omitempty"`
+
+	// InvalidatedAtMilli is when the fact was invalidated.
+	InvalidatedAtMilli int64 `json:"invalidated_at,omitempty"`
+
+	// UseCount is the number of agent runs the fact was given to.
+	UseCount int `json:"use_count"`
+
+	// LastUsedAtMilli is when the fact was last given to an agent run.
+	LastUsedAtMilli int64 `json:"last_used_at,omitempty"`
+
+	// LastUsedSession is the session the fact was last given to.
+	LastUsedSession string `json:"last_used_session,omitempty"`
+}
+
+// Active reports whether the fact is given to agent sessions.
+func (f Fact) Active() bool {
+	return f.State == StateActive
+}
+
+// Candidate is a fact detected in a user message, not yet anchored.
+type Candidate struct {
+	// Statement is the fact.
+	Statement string
+
+	// Kind is KindCorrection or KindConfirmation.
+	Kind string
+
+	// Mentions are the identifiers and paths the statement names, for
+	// anchoring: "pkg/transport", "Server.Handle", "parseConfig".
+	Mentions []string
+
+	// SessionID is the session the message was sent in.
+	SessionID string
+
+	// ConfirmedBy is ConfirmedByClarification or ConfirmedByFollowUp.
+	ConfirmedBy string
+}
+
+// correctionMarker matches the opening of a message correcting the agent.
+var correctionMarker = regexp.MustCompile(`(?i)^(?:no|nope|wrong|incorrect|not quite|actually|that'?s (?:not right|not correct|wrong|incorrect)|that is (?:not right|not correct|wrong|incorrect))\b[\s,.:;!-]*`)
+
+// confirmationMarker matches the opening of a message confirming what the
+// agent said.
+var confirmationMarker = regexp.MustCompile(`(?i)^(?:yes|yep|yeah|correct|right|exactly|confirmed|that'?s (?:right|correct)|that is (?:right|correct))\b[\s,.:;!-]*`)
+
+// Detect returns the fact a user message states, if it explicitly confirms
+// or corrects something.
+//
+// Description:
+//
+//	Only messages that open with a correction ("no,", "actually",
+//	"that's wrong") or a confirmation ("yes,", "correct", "exactly") are
+//	considered, and only if the rest says something of at least three
+//	words that names an identifier or path. The statement is the rest
+//	of the message, on one line, cut at MaxStatementLength.
+//
+// Inputs:
+//
+//	text - The user's message.
+//
+// Outputs:
+//
+//	Candidate - The fact, with Statement, Kind and Mentions set.
+//	bool - False if the message states no fact.
+func Detect(text string) (Candidate, bool) {
+	text = strings.TrimSpace(text)
+	kind := KindCorrection
+	loc := correctionMarker.FindStringIndex(text)
+	if loc == nil {
+		kind = KindConfirmation
+		loc = confirmationMarker.FindStringIndex(text)
+	}
+	if loc == nil {
+		return Candidate{}, false
+	}
+	rest := strings.Join(strings.Fields(text[loc[1]:]), " ")
+	if len(strings.Fields(rest)) < minStatementWords {
+		return Candidate{}, false
+	}
+	mentions := Mentions(rest)
+	if len(mentions) == 0 {
+		return Candidate{}, false
+	}
+	return Candidate{Statement: clip(capitalize(rest)), Kind: kind, Mentions: mentions}, true
+}
+
+// Mentions returns the identifiers and paths a text names: backquoted
+// spans, paths, dotted names, and CamelCase or snake_case identifiers,
+// in order of appearance and at most MaxMentions.
+func Mentions(text string) []string {
+	var mentions []string
+	seen := make(map[string]bool)
+	add := func(m string) {
+		m = strings.TrimSuffix(strings.Trim(m, ".,;:!?()[]{}\"'"), "()")
+		if m == "" || seen[m] || len(mentions) >= MaxMentions {
+			return
+		}
+		seen[m] = true
+		mentions = append(mentions, m)
+	}
+
+	for {
+		start := strings.IndexByte(text, '`')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(text[start+1:], '`')
+		if end < 0 {
+			break
+		}
+		add(text[start+1 : start+1+end])
+		text = text[:start] + " " + text[start+2+end:]
+	}
+	for _, word := range strings.Fields(text) {
+		word = strings.Trim(word, ".,;:!?()[]{}\"'")
+		if looksLikeSymbol(word) {
+			add(word)
+		}
+	}
+	return mentions
+}
+
+// looksLikeSymbol reports whether a word is a path, a dotted name or an
+// identifier no English word is spelled like.
+func looksLikeSymbol(word string) bool {
+	if word == "" || strings.Contains(word, "://") {
+		return false
+	}
+	if strings.ContainsAny(word, "/_") {
+		return true
+	}
+	if i := strings.IndexByte(word, '.'); i > 0 && i < len(word)-1 {
+		return isIdentifier(strings.ReplaceAll(word, ".", ""))
+	}
+	// parseConfig, ParseConfig, HTTPServer; not "The" or "API".
+	for i, r := range word {
+		if i > 0 && unicode.IsUpper(r) && strings.IndexFunc(word, unicode.IsLower) >= 0 {
+			return isIdentifier(word)
+		}
+	}
+	return false
+}
+
+// isIdentifier reports whether s has only letters, digits and underscores.
+func isIdentifier(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}) < 0
+}
+
+// NormalizeStatement returns a statement trimmed to one line.
+//
+// Outputs:
+//
+//	string - The statement.
+//	error - ErrInvalidFact if it is empty or longer than MaxStatementLength.
+func NormalizeStatement(statement string) (string, error) {
+	statement = strings.Join(strings.Fields(statement), " ")
+	switch {
+	case statement == "":
+		return "", fmt.Errorf("%w: statement is empty", ErrInvalidFact)
+	case len(statement) > MaxStatementLength:
+		return "", fmt.Errorf("%w: statement is %d bytes, max %d", ErrInvalidFact, len(statement), MaxStatementLength)
+	}
+	return statement, nil
+}
+
+// IDFor returns the ID a new fact with the statement gets. Statements
+// differing only in case, spacing or final punctuation share an ID, so
+// stating a fact again updates it.
+func IDFor(statement string) string {
+	key := strings.ToLower(strings.Join(strings.Fields(statement), " "))
+	key = strings.TrimRight(key, ".!")
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Rank returns the active facts most worth giving to a query.
+//
+// Description:
+//
+//	Each fact scores twice the fraction of the query's terms found in its
+//	statement or anchors, plus a recency score that halves every
+//	RecencyHalfLife since it was last stated or used. Ties keep the most
+//	recently updated first.
+//
+// Inputs:
+//
+//	list - The project's facts.
+//	query - The query the facts are for.
+//	now - The current time.
+//	limit - The most facts to return. Zero or less returns none.
+//
+// Outputs:
+//
+//	[]Fact - At most limit active facts, best first.
+func Rank(list []Fact, query string, now time.Time, limit int) []Fact {
+	if limit <= 0 {
+		return nil
+	}
+	queryTerms := terms(query)
+	type scored struct {
+		fact  Fact
+		score float64
+	}
+	var ranked []scored
+	for _, f := range list {
+		if !f.Active() {
+			continue
+		}
+		ranked = append(ranked, scored{f, 2*relevance(f, queryTerms) + recency(f, now)})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].score != ranked[j].score {
+			return ranked[i].score > ranked[j].score
+		}
+		if ranked[i].fact.UpdatedAtMilli != ranked[j].fact.UpdatedAtMilli {
+			return ranked[i].fact.UpdatedAtMilli > ranked[j].fact.UpdatedAtMilli
+		}
+		return ranked[i].fact.ID < ranked[j].fact.ID
+	})
+	if len(ranked) > limit {
+		ranked = ranked[:limit]
+	}
+	out := make([]Fact, len(ranked))
+	for i, r := range ranked {
+		out[i] = r.fact
+	}
+	return out
+}
+
+// relevance is the fraction of query terms a fact mentions.
+func relevance(f Fact, queryTerms map[string]bool) float64 {
+	if len(queryTerms) == 0 {
+		return 0
+	}
+	text := f.Statement
+	for _, a := range f.Anchors {
+		text += " " + a.Name + " " + a.FilePath
+	}
+	factTerms := terms(text)
+	matched := 0
+	for t := range queryTerms {
+		if factTerms[t] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(queryTerms))
+}
+
+// recency is 1 for a fact stated or used now, halving every
+// RecencyHalfLife.
+func recency(f Fact, now time.Time) float64 {
+	last := max(f.UpdatedAtMilli, f.LastUsedAtMilli)
+	age := now.Sub(time.UnixMilli(last))
+	if age < 0 {
+		age = 0
+	}
+	return math.Pow(0.5, float64(age)/float64(RecencyHalfLife))
+}
+
+// stopTerms are words too common to relate a query to a fact.
+var stopTerms = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "was": true, "not": true,
+	"but": true, "does": true, "what": true, "where": true, "which": true, "how": true,
+	"who": true, "why": true, "this": true, "that": true, "with": true, "from": true,
+	"into": true, "its": true, "lives": true, "is": true, "in": true, "of": true,
+}
+
+// terms returns the lowercase words of three or more letters in text,
+// splitting identifiers and paths into their parts.
+func terms(text string) map[string]bool {
+	out := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		for _, part := range splitCamel(word) {
+			part = strings.ToLower(part)
+			if len(part) >= 3 && !stopTerms[part] {
+				out[part] = true
+			}
+		}
+		if lower := strings.ToLower(word); len(lower) >= 3 && !stopTerms[lower] {
+			out[lower] = true
+		}
+	}
+	return out
+}
+
+// splitCamel splits "parseHTTPConfig" into "parse", "HTTP", "Config".
+func splitCamel(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+		acronymEnd := i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
+
+// Render formats facts as the bullet list given to the model, one fact
+// per line with its anchors.
+func Render(list []Fact) string {
+	var b strings.Builder
+	for _, f := range list {
+		b.WriteString("- ")
+		b.WriteString(f.Statement)
+		names := make([]string, 0, len(f.Anchors))
+		for _, a := range f.Anchors {
+			names = append(names, a.Name+" in "+a.FilePath)
+		}
+		if len(names) > 0 {
+			b.WriteString(" (about ")
+			b.WriteString(strings.Join(names, "; "))
+			b.WriteString(")")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// capitalize upper-cases the first letter of s.
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+// clip cuts s at a word to at most MaxStatementLength bytes.
+func clip(s string) string {
+	if len(s) <= MaxStatementLength {
+		return s
+	}
+	cut := strings.LastIndex(s[:MaxStatementLength-len("…")], " ")
+	if cut <= 0 {
+		cut = MaxStatementLength - len("…")
+	}
+	return strings.TrimRight(s[:cut], ",;:") + "…"
+}
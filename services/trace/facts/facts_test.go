@@ -0,0 +1,170 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package facts
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+)
+
+func TestDetect(t *testing.T) {
+	c, ok := Detect("No, the retry logic lives in pkg/transport not pkg/client.")
+	if !ok {
+		t.Fatal("Detect(correction) = false")
+	}
+	if c.Kind != KindCorrection || c.Statement != "The retry logic lives in pkg/transport not pkg/client." {
+		t.Errorf("Detect(correction) = %+v", c)
+	}
+	if want := []string{"pkg/transport", "pkg/client"}; !reflect.DeepEqual(c.Mentions, want) {
+		t.Errorf("Mentions = %v, want %v", c.Mentions, want)
+	}
+
+	c, ok = Detect("yes, `Server.Handle` is the only entry point for uploads")
+	if !ok || c.Kind != KindConfirmation || !reflect.DeepEqual(c.Mentions, []string{"Server.Handle"}) {
+		t.Errorf("Detect(confirmation) = %+v, %v", c, ok)
+	}
+
+	for _, text := range []string{
+		"yes, exactly",
+		"what calls parseConfig?",
+		"no, that is not what I asked about",
+		"Actually nevermind",
+		"nobody calls parseConfig from main",
+	} {
+		if c, ok := Detect(text); ok {
+			t.Errorf("Detect(%q) = %+v, want no fact", text, c)
+		}
+	}
+
+	c, ok = Detect("actually parseConfig is called " + strings.Repeat("from somewhere ", 40))
+	if !ok || len(c.Statement) > MaxStatementLength || !strings.HasSuffix(c.Statement, "…") {
+		t.Errorf("Detect(long) statement = %q, want it cut at %d bytes", c.Statement, MaxStatementLength)
+	}
+}
+
+func TestMentions(t *testing.T) {
+	got := Mentions("Use parseConfig() or HTTPServer, see config.go and retry_policy; not The API at https://x.io/a")
+	want := []string{"parseConfig", "HTTPServer", "config.go", "retry_policy"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Mentions = %v, want %v", got, want)
+	}
+}
+
+func TestIDFor(t *testing.T) {
+	if IDFor("Retry lives in pkg/transport.") != IDFor("retry  lives in pkg/transport") {
+		t.Error("IDFor differs for statements differing in case, spacing and final period")
+	}
+	if IDFor("a b c") == IDFor("a b d") {
+		t.Error("IDFor is the same for different statements")
+	}
+}
+
+func TestNormalizeStatement(t *testing.T) {
+	if s, err := NormalizeStatement("  a\n b "); err != nil || s != "a b" {
+		t.Errorf("NormalizeStatement = %q, %v", s, err)
+	}
+	for _, bad := range []string{" ", strings.Repeat("x", MaxStatementLength+1)} {
+		if _, err := NormalizeStatement(bad); !errors.Is(err, ErrInvalidFact) {
+			t.Errorf("NormalizeStatement(%d bytes) err = %v, want ErrInvalidFact", len(bad), err)
+		}
+	}
+}
+
+func TestRank(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day := int64(24 * time.Hour / time.Millisecond)
+	list := []Fact{
+		{ID: "old-relevant", Statement: "Retry logic lives in pkg/transport", State: StateActive, UpdatedAtMilli: now.UnixMilli() - 90*day},
+		{ID: "new-unrelated", Statement: "Config is loaded once by LoadConfig", State: StateActive, UpdatedAtMilli: now.UnixMilli()},
+		{ID: "invalidated", Statement: "Retry logic lives in pkg/client", State: StateInvalidated, UpdatedAtMilli: now.UnixMilli()},
+		{ID: "older-unrelated", Statement: "Handlers are registered in routes.go", State: StateActive, UpdatedAtMilli: now.UnixMilli() - 10*day},
+	}
+
+	got := Rank(list, "where is the retry logic?", now, 2)
+	if len(got) != 2 || got[0].ID != "old-relevant" || got[1].ID != "new-unrelated" {
+		t.Errorf("Rank = %v, want the relevant fact, then the most recent", ids(got))
+	}
+
+	// A recent use counts as recency.
+	list[3].LastUsedAtMilli = now.UnixMilli()
+	list[3].UpdatedAtMilli = now.UnixMilli() - day
+	if got := Rank(list, "", now, 1); got[0].ID != "new-unrelated" {
+		t.Errorf("Rank(no query) = %v, want the most recently updated", ids(got))
+	}
+	if got := Rank(list, "", now, 0); got != nil {
+		t.Errorf("Rank(limit 0) = %v, want none", ids(got))
+	}
+}
+
+func TestRender(t *testing.T) {
+	got := Render([]Fact{{Statement: "Retry lives in pkg/transport", Anchors: []Anchor{{Name: "transport", FilePath: "pkg/transport/retry.go"}}}})
+	if got != "- Retry lives in pkg/transport (about transport in pkg/transport/retry.go)\n" {
+		t.Errorf("Render = %q", got)
+	}
+}
+
+func TestBadgerStore(t *testing.T) {
+	db, err := badgerstore.OpenDB(badgerstore.InMemoryConfig())
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	for name, store := range map[string]Store{"badger": NewBadgerStore(db), "memory": NewMemoryStore()} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			for _, f := range []Fact{
+				{ID: "a", Statement: "first", UpdatedAtMilli: 1, Anchors: []Anchor{{StableID: "a.go::F@1"}}},
+				{ID: "b", Statement: "second", UpdatedAtMilli: 2},
+				{ID: "a", Statement: "first, edited", UpdatedAtMilli: 3, Anchors: []Anchor{{StableID: "a.go::F@2"}}},
+			} {
+				if err := store.Put(ctx, "/repo", f); err != nil {
+					t.Fatalf("Put: %v", err)
+				}
+			}
+			if err := store.Put(ctx, "/repo-other", Fact{ID: "x"}); err != nil {
+				t.Fatalf("Put: %v", err)
+			}
+
+			list, err := store.List(ctx, "/repo")
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if len(list) != 2 || list[0].Statement != "first, edited" || list[0].Anchors[0].StableID != "a.go::F@2" || list[1].ID != "b" {
+				t.Errorf("List = %+v, want the edited fact first", list)
+			}
+
+			if err := store.Delete(ctx, "/repo", "a"); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+			if err := store.Delete(ctx, "/repo", "a"); err != nil {
+				t.Errorf("Delete of a missing fact: %v", err)
+			}
+			if list, _ := store.List(ctx, "/repo"); len(list) != 1 {
+				t.Errorf("List after Delete = %+v, want one fact", list)
+			}
+		})
+	}
+}
+
+func ids(list []Fact) []string {
+	out := make([]string, len(list))
+	for i, f := range list {
+		out[i] = f.ID
+	}
+	return out
+}
@@ -0,0 +1,193 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package facts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	dgbadger "github.com/dgraph-io/badger/v4"
+)
+
+// factKeyPrefix namespaces fact keys in a shared BadgerDB.
+const factKeyPrefix = "fact:"
+
+// Store persists facts per project.
+//
+// The project is identified by its root path.
+type Store interface {
+	// List returns the facts of a project, most recently updated first.
+	List(ctx context.Context, project string) ([]Fact, error)
+
+	// Put stores a fact, replacing one with the same ID.
+	Put(ctx context.Context, project string, f Fact) error
+
+	// Delete removes a fact. Deleting a missing one is not an error.
+	Delete(ctx context.Context, project, id string) error
+}
+
+// MemoryStore is a Store that keeps facts in memory only.
+//
+// Thread Safety: Safe for concurrent use.
+type MemoryStore struct {
+	mu       sync.RWMutex
+	projects map[string]map[string]Fact
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{projects: make(map[string]map[string]Fact)}
+}
+
+// List implements Store.
+func (s *MemoryStore) List(_ context.Context, project string) ([]Fact, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]Fact, 0, len(s.projects[project]))
+	for _, f := range s.projects[project] {
+		list = append(list, cloneFact(f))
+	}
+	sortFacts(list)
+	return list, nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(_ context.Context, project string, f Fact) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	byID, ok := s.projects[project]
+	if !ok {
+		byID = make(map[string]Fact)
+		s.projects[project] = byID
+	}
+	byID[f.ID] = cloneFact(f)
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, project, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.projects[project], id)
+	return nil
+}
+
+// BadgerStore is a Store backed by BadgerDB.
+//
+// Description:
+//
+//	Each fact is one key, fact:{project}\x00{ID}, holding the JSON Fact.
+//	Facts do not expire. The DB is owned by the caller and must stay open
+//	while the store is in use.
+//
+// Thread Safety: Safe for concurrent use.
+type BadgerStore struct {
+	db *badgerstore.DB
+}
+
+// NewBadgerStore creates a store backed by db.
+//
+// Inputs:
+//
+//	db - Opened BadgerDB wrapper. Must not be nil.
+//
+// Outputs:
+//
+//	*BadgerStore - The store. Never nil.
+func NewBadgerStore(db *badgerstore.DB) *BadgerStore {
+	if db == nil {
+		panic("NewBadgerStore: db must not be nil")
+	}
+	return &BadgerStore{db: db}
+}
+
+// List implements Store.
+func (s *BadgerStore) List(ctx context.Context, project string) ([]Fact, error) {
+	var list []Fact
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		opts := dgbadger.DefaultIteratorOptions
+		opts.Prefix = projectPrefix(project)
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			var f Fact
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &f)
+			}); err != nil {
+				return err
+			}
+			list = append(list, f)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fact list: %w", err)
+	}
+	sortFacts(list)
+	return list, nil
+}
+
+// Put implements Store.
+func (s *BadgerStore) Put(ctx context.Context, project string, f Fact) error {
+	value, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("fact encode: %w", err)
+	}
+	err = s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.Set(factKey(project, f.ID), value)
+	})
+	if err != nil {
+		return fmt.Errorf("fact put: %w", err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *BadgerStore) Delete(ctx context.Context, project, id string) error {
+	err := s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.Delete(factKey(project, id))
+	})
+	if err != nil && !errors.Is(err, dgbadger.ErrKeyNotFound) {
+		return fmt.Errorf("fact delete: %w", err)
+	}
+	return nil
+}
+
+// sortFacts orders facts most recently updated first, then by ID.
+func sortFacts(list []Fact) {
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].UpdatedAtMilli != list[j].UpdatedAtMilli {
+			return list[i].UpdatedAtMilli > list[j].UpdatedAtMilli
+		}
+		return list[i].ID < list[j].ID
+	})
+}
+
+// cloneFact copies a fact so that its anchors are not shared.
+func cloneFact(f Fact) Fact {
+	f.Anchors = append([]Anchor(nil), f.Anchors...)
+	return f
+}
+
+// projectPrefix is the key prefix of a project's facts.
+func projectPrefix(project string) []byte {
+	return []byte(factKeyPrefix + project + "\x00")
+}
+
+// factKey is the key of one fact.
+func factKey(project, id string) []byte {
+	return []byte(factKeyPrefix + project + "\x00" + id)
+}
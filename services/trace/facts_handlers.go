@@ -0,0 +1,155 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleListFacts lists the facts users confirmed about a graph's project.
+//
+// Description:
+//
+//	GET /v1/trace/facts. Facts are stated in agent sessions, when a user
+//	confirms or corrects an answer, and given to later sessions of the
+//	project. Invalidated facts, whose symbols were removed or changed
+//	signature, are listed only on request.
+//
+// Query Parameters:
+//
+//	graph_id: ID of the graph (optional, uses first cached if not specified)
+//	project_root: Project root to look up graph (alternative to graph_id)
+//	git_ref: With project_root, the git_ref the graph was built from
+//	include_invalidated: "true" to list invalidated facts too
+//
+// Response:
+//
+//	200 OK: FactsResponse
+//	404 Not Found: No graphs cached or graph not found
+//	500 Internal Server Error: The fact store failed
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleListFacts(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleListFacts")
+
+	graphID, _, ok := h.statusGraph(c, logger)
+	if !ok {
+		return
+	}
+	list, projectRoot, err := h.svc.ListFacts(c.Request.Context(), graphID, c.Query("include_invalidated") == "true")
+	if err != nil {
+		writeFactError(c, logger, err)
+		return
+	}
+	c.JSON(http.StatusOK, FactsResponse{GraphID: graphID, ProjectRoot: projectRoot, Facts: list, Count: len(list)})
+}
+
+// HandleUpdateFact edits a fact.
+//
+// Description:
+//
+//	PATCH /v1/trace/facts/:id with an UpdateFactRequest. A new statement
+//	or new anchors make an invalidated fact active again if its anchors
+//	are in the graph.
+//
+// Response:
+//
+//	200 OK: FactResponse
+//	400 Bad Request: Invalid body, nothing to change, or INVALID_FACT
+//	404 Not Found: FACT_NOT_FOUND, SYMBOL_NOT_FOUND, or graph not found
+//	500 Internal Server Error: The fact store failed
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleUpdateFact(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleUpdateFact")
+
+	var req UpdateFactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body: graph_id is required")
+		return
+	}
+	if req.Statement == nil && len(req.SymbolIDs) == 0 {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "statement or symbol_ids is required")
+		return
+	}
+
+	f, err := h.svc.UpdateFact(c.Request.Context(), req.GraphID, c.Param("id"), req.Statement, req.SymbolIDs)
+	if err != nil {
+		writeFactError(c, logger, err)
+		return
+	}
+
+	logger.Info("Fact updated", "graph_id", req.GraphID, "fact_id", f.ID, "state", f.State)
+	c.JSON(http.StatusOK, FactResponse{Fact: f})
+}
+
+// HandleDeleteFact deletes a fact.
+//
+// Description:
+//
+//	DELETE /v1/trace/facts/:id?graph_id=... (or project_root=...). The
+//	graph only identifies the project; unlike the list, it is required.
+//
+// Response:
+//
+//	204 No Content: Deleted
+//	400 Bad Request: graph_id and project_root both missing
+//	404 Not Found: FACT_NOT_FOUND, or graph not found
+//	500 Internal Server Error: The fact store failed
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleDeleteFact(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDeleteFact")
+
+	if c.Query("graph_id") == "" && c.Query("project_root") == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "graph_id or project_root is required")
+		return
+	}
+	graphID, _, ok := h.statusGraph(c, logger)
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteFact(c.Request.Context(), graphID, c.Param("id")); err != nil {
+		writeFactError(c, logger, err)
+		return
+	}
+
+	logger.Info("Fact deleted", "graph_id", graphID, "fact_id", c.Param("id"))
+	c.Status(http.StatusNoContent)
+}
+
+// writeFactError writes the problem response for a facts service error.
+func writeFactError(c *gin.Context, logger *slog.Logger, err error) {
+	switch {
+	case isGraphStateError(err):
+		apierror.Write(c, http.StatusNotFound, apierror.CodeGraphNotFound, err.Error())
+	case errors.Is(err, facts.ErrInvalidFact):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidFact, err.Error())
+	case errors.Is(err, ErrFactNotFound):
+		apierror.Write(c, http.StatusNotFound, apierror.CodeFactNotFound, err.Error())
+	case errors.Is(err, ErrFactSymbolNotFound):
+		apierror.Write(c, http.StatusNotFound, apierror.CodeSymbolNotFound, err.Error())
+	default:
+		logger.Error("Fact operation failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "fact store: "+err.Error())
+	}
+}
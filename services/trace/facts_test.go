@@ -0,0 +1,176 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
+)
+
+// proposeDetected detects the fact a message states and proposes it.
+func proposeDetected(t *testing.T, svc *Service, graphID, message string) (*facts.Fact, error) {
+	t.Helper()
+	c, ok := facts.Detect(message)
+	if !ok {
+		t.Fatalf("Detect(%q) found no fact", message)
+	}
+	c.SessionID, c.ConfirmedBy = "sess-1", facts.ConfirmedByFollowUp
+	return svc.ProposeFact(context.Background(), graphID, c)
+}
+
+func TestFacts_ProposeRankAndInvalidate(t *testing.T) {
+	root := writeMonorepo(t)
+	svc := NewService(DefaultServiceConfig())
+	ctx := context.Background()
+	resp, err := svc.Init(ctx, root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	charge, err := proposeDetected(t, svc, resp.GraphID, "No, `Charge` validates the amount, not PlaceOrder.")
+	if err != nil {
+		t.Fatalf("ProposeFact(Charge): %v", err)
+	}
+	if charge.Kind != facts.KindCorrection || len(charge.Anchors) != 2 || charge.Anchors[0].Name != "Charge" ||
+		charge.Anchors[0].FilePath != "services/payment/charge.go" || charge.SessionID != "sess-1" {
+		t.Errorf("Charge fact = %+v, want a correction anchored to Charge and PlaceOrder", charge)
+	}
+	pkg, err := proposeDetected(t, svc, resp.GraphID, "yes, the store interface lives in store/ and nowhere else")
+	if err != nil {
+		t.Fatalf("ProposeFact(store/): %v", err)
+	}
+	if len(pkg.Anchors) != 1 || pkg.Anchors[0].FilePath != "store/store.go" {
+		t.Errorf("directory fact anchors = %+v, want the store package", pkg.Anchors)
+	}
+	if _, err := proposeDetected(t, svc, resp.GraphID, "actually retries live in pkg/transport"); !errors.Is(err, ErrFactUnanchored) {
+		t.Errorf("ProposeFact(unknown path) err = %v, want ErrFactUnanchored", err)
+	}
+
+	// Stating a fact again updates it instead of adding one.
+	if again, err := proposeDetected(t, svc, resp.GraphID, "no, `Charge` validates the amount, not PlaceOrder"); err != nil || again.ID != charge.ID {
+		t.Errorf("restated fact = %+v, %v; want ID %s", again, err, charge.ID)
+	}
+
+	known := svc.KnownFacts(ctx, resp.GraphID, "how does Charge check the amount?", 1)
+	if len(known) != 1 || known[0].ID != charge.ID {
+		t.Fatalf("KnownFacts = %+v, want the Charge fact", known)
+	}
+	svc.RecordFactsApplied(ctx, resp.GraphID, "sess-2", []string{charge.ID})
+
+	// Changing Charge's signature invalidates the fact anchored to it.
+	chargeFile := filepath.Join(root, "services/payment/charge.go")
+	source, err := os.ReadFile(chargeFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := strings.Replace(string(source), "func Charge(amount int) error", "func Charge(amount int, currency string) error", 1)
+	if err := os.WriteFile(chargeFile, []byte(changed), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Init(ctx, root, nil, nil, true); err != nil {
+		t.Fatalf("re-Init: %v", err)
+	}
+
+	list, _, err := svc.ListFacts(ctx, resp.GraphID, true)
+	if err != nil {
+		t.Fatalf("ListFacts: %v", err)
+	}
+	byID := make(map[string]facts.Fact)
+	for _, f := range list {
+		byID[f.ID] = f
+	}
+	if f := byID[charge.ID]; f.State != facts.StateInvalidated ||
+		f.InvalidatedReason != "Charge in services/payment/charge.go changed signature" ||
+		f.UseCount != 1 || f.LastUsedSession != "sess-2" {
+		t.Errorf("Charge fact after rebuild = %+v, want invalidated for the signature change", f)
+	}
+	if f := byID[pkg.ID]; !f.Active() {
+		t.Errorf("store fact after rebuild = %+v, want active", f)
+	}
+	if active, _, _ := svc.ListFacts(ctx, resp.GraphID, false); len(active) != 1 {
+		t.Errorf("active facts = %+v, want only the store fact", active)
+	}
+	if known := svc.KnownFacts(ctx, resp.GraphID, "Charge", 5); len(known) != 1 || known[0].ID != pkg.ID {
+		t.Errorf("KnownFacts after rebuild = %+v, want only the active fact", known)
+	}
+}
+
+func TestFactsHandlers(t *testing.T) {
+	root := writeMonorepo(t)
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	f, err := proposeDetected(t, svc, resp.GraphID, "yes, PlaceOrder is the only caller of `Charge`")
+	if err != nil {
+		t.Fatalf("ProposeFact: %v", err)
+	}
+	router := setupTestRouter(svc)
+	do := func(method, target, body string, out any) *httptest.ResponseRecorder {
+		t.Helper()
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if out != nil && w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+				t.Fatalf("decode %s: %v", w.Body.String(), err)
+			}
+		}
+		return w
+	}
+
+	var listed FactsResponse
+	if w := do(http.MethodGet, "/v1/trace/facts?project_root="+root, "", &listed); w.Code != http.StatusOK {
+		t.Fatalf("GET: status %d: %s", w.Code, w.Body.String())
+	}
+	if listed.Count != 1 || listed.Facts[0].ID != f.ID || listed.GraphID != resp.GraphID {
+		t.Errorf("GET = %+v, want the proposed fact", listed)
+	}
+
+	// Editing re-anchors the fact and marks it as confirmed through the API.
+	var edited FactResponse
+	w := do(http.MethodPatch, "/v1/trace/facts/"+f.ID,
+		`{"graph_id": "`+resp.GraphID+`", "statement": "PlaceOrder is the only caller of payment.Charge", "symbol_ids": ["`+f.Anchors[1].StableID+`"]}`, &edited)
+	if w.Code != http.StatusOK {
+		t.Fatalf("PATCH: status %d: %s", w.Code, w.Body.String())
+	}
+	if edited.Fact.ID != f.ID || edited.Fact.Statement != "PlaceOrder is the only caller of payment.Charge" ||
+		len(edited.Fact.Anchors) != 1 || edited.Fact.ConfirmedBy != facts.ConfirmedByAPI || !edited.Fact.Active() {
+		t.Errorf("PATCH = %+v", edited.Fact)
+	}
+
+	graphBody := `{"graph_id": "` + resp.GraphID + `"`
+	assertProblem(t, do(http.MethodPatch, "/v1/trace/facts/"+f.ID, graphBody+`}`, nil), http.StatusBadRequest, apierror.CodeInvalidRequest)
+	assertProblem(t, do(http.MethodPatch, "/v1/trace/facts/"+f.ID, graphBody+`, "statement": " "}`, nil), http.StatusBadRequest, apierror.CodeInvalidFact)
+	assertProblem(t, do(http.MethodPatch, "/v1/trace/facts/"+f.ID, graphBody+`, "symbol_ids": ["nope.go::X@1"]}`, nil), http.StatusNotFound, apierror.CodeSymbolNotFound)
+	assertProblem(t, do(http.MethodPatch, "/v1/trace/facts/missing", graphBody+`, "statement": "x y z"}`, nil), http.StatusNotFound, apierror.CodeFactNotFound)
+
+	assertProblem(t, do(http.MethodDelete, "/v1/trace/facts/"+f.ID, "", nil), http.StatusBadRequest, apierror.CodeMissingParameter)
+	if w := do(http.MethodDelete, "/v1/trace/facts/"+f.ID+"?graph_id="+resp.GraphID, "", nil); w.Code != http.StatusNoContent {
+		t.Fatalf("DELETE: status %d: %s", w.Code, w.Body.String())
+	}
+	assertProblem(t, do(http.MethodDelete, "/v1/trace/facts/"+f.ID+"?graph_id="+resp.GraphID, "", nil), http.StatusNotFound, apierror.CodeFactNotFound)
+	if w := do(http.MethodGet, "/v1/trace/facts?graph_id="+resp.GraphID, "", &listed); w.Code != http.StatusOK || listed.Count != 0 {
+		t.Errorf("GET after DELETE = %d %+v, want no facts", w.Code, listed)
+	}
+}
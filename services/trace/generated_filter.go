@@ -0,0 +1,235 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// generatedFilterMaxDepth bounds how deep filterGenerated descends into a
+// result, guarding against cyclic pointer structures.
+const generatedFilterMaxDepth = 10
+
+// generatedFileFields name string fields holding a file path, or a
+// "file:line" location, that identify where a result item lives.
+var generatedFileFields = []string{"FilePath", "File", "Location"}
+
+// generatedIDFields name string fields holding the symbol ID a result
+// item describes.
+var generatedIDFields = []string{"ID", "SymbolID", "CallerID", "TestID"}
+
+// generatedFilter removes generated-code hits from tool results.
+//
+// Thread Safety: Not safe for concurrent use; create one per request.
+type generatedFilter struct {
+	idx *index.SymbolIndex
+
+	// files caches whether a file contains generated symbols.
+	files map[string]bool
+
+	suppressed int
+}
+
+// filterGenerated removes items that point into generated code from result.
+//
+// Description:
+//
+//	Walks result and drops slice elements that are generated symbols,
+//	that name a generated symbol by ID, or whose file path or location
+//	is in a file with generated symbols. Only slices reachable through
+//	pointers and exported struct fields are filtered; maps are left as is.
+//
+// Inputs:
+//
+//	idx - The graph's symbol index. Nil disables filtering.
+//	result - A pointer to the tool result.
+//
+// Outputs:
+//
+//	int - The number of items removed.
+//
+// Thread Safety: Safe for concurrent use; result must not be shared.
+func filterGenerated(idx *index.SymbolIndex, result any) int {
+	if idx == nil || result == nil {
+		return 0
+	}
+	f := &generatedFilter{idx: idx, files: make(map[string]bool)}
+	f.walk(reflect.ValueOf(result), 0)
+	return f.suppressed
+}
+
+// walk filters the slices reachable from v.
+func (f *generatedFilter) walk(v reflect.Value, depth int) {
+	if depth > generatedFilterMaxDepth {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() && !isSharedGraphData(v) {
+			f.walk(v.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				f.walk(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Slice:
+		if v.CanSet() {
+			f.filterSlice(v)
+		}
+		for i := 0; i < v.Len(); i++ {
+			f.walk(v.Index(i), depth+1)
+		}
+	}
+}
+
+// filterSlice replaces v with a copy without its generated elements. The
+// copy keeps backing arrays shared with the graph intact.
+func (f *generatedFilter) filterSlice(v reflect.Value) {
+	var kept reflect.Value
+	for i := 0; i < v.Len(); i++ {
+		if !f.isGenerated(v.Index(i)) {
+			if kept.IsValid() {
+				kept = reflect.Append(kept, v.Index(i))
+			}
+			continue
+		}
+		f.suppressed++
+		if !kept.IsValid() {
+			kept = reflect.MakeSlice(v.Type(), 0, v.Len()-1)
+			kept = reflect.AppendSlice(kept, v.Slice(0, i))
+		}
+	}
+	if kept.IsValid() {
+		v.Set(kept)
+	}
+}
+
+// isGenerated reports whether a single result item points into generated code.
+func (f *generatedFilter) isGenerated(v reflect.Value) bool {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		if sym, ok := v.Interface().(*ast.Symbol); ok {
+			return sym.Generated
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return f.isGeneratedID(v.String())
+	case reflect.Struct:
+	default:
+		return false
+	}
+	for _, name := range generatedFileFields {
+		if field := v.FieldByName(name); field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+			return f.isGeneratedFile(locationFile(field.String()))
+		}
+	}
+	for _, name := range generatedIDFields {
+		if field := v.FieldByName(name); field.IsValid() && field.Kind() == reflect.String && field.String() != "" {
+			return f.isGeneratedID(field.String())
+		}
+	}
+	return false
+}
+
+// isSharedGraphData reports whether v points at symbols or graph nodes
+// owned by the cached graph, which must never be modified.
+func isSharedGraphData(v reflect.Value) bool {
+	if v.Kind() != reflect.Pointer {
+		return false
+	}
+	switch v.Type().Elem().PkgPath() {
+	case astPkgPath, graphPkgPath:
+		return true
+	}
+	return false
+}
+
+var (
+	astPkgPath   = reflect.TypeOf(ast.Symbol{}).PkgPath()
+	graphPkgPath = reflect.TypeOf(graph.Node{}).PkgPath()
+)
+
+// isGeneratedID reports whether id names a generated symbol.
+func (f *generatedFilter) isGeneratedID(id string) bool {
+	sym, ok := f.idx.GetByID(id)
+	return ok && sym.Generated
+}
+
+// isGeneratedFile reports whether path contains generated symbols.
+func (f *generatedFilter) isGeneratedFile(path string) bool {
+	if generated, ok := f.files[path]; ok {
+		return generated
+	}
+	generated := false
+	for _, sym := range f.idx.GetByFile(path) {
+		if sym.Generated {
+			generated = true
+			break
+		}
+	}
+	f.files[path] = generated
+	return generated
+}
+
+// locationFile strips a ":line" or ":line:col" suffix from a location.
+func locationFile(location string) string {
+	for {
+		i := strings.LastIndexByte(location, ':')
+		if i < 0 || !isDigits(location[i+1:]) {
+			return location
+		}
+		location = location[:i]
+	}
+}
+
+// isDigits reports whether s is a non-empty run of ASCII digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// suppressGenerated applies filterGenerated to a tool result unless the
+// request opted into generated code.
+//
+// Inputs:
+//
+//	cached - The graph the result was computed from.
+//	includeGenerated - The request's include_generated flag.
+//	result - A pointer to the tool result.
+//
+// Outputs:
+//
+//	int - The number of items removed, for AgenticResponse.GeneratedSuppressed.
+func suppressGenerated(cached *CachedGraph, includeGenerated bool, result any) int {
+	if includeGenerated || cached == nil {
+		return 0
+	}
+	return filterGenerated(cached.Index, result)
+}
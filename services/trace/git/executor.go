@@ -17,6 +17,8 @@ import (
 	"os/exec"
 	"path/filepath"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // GitAwareExecutor executes git commands with automatic cache invalidation.
@@ -140,7 +142,7 @@ func (e *GitAwareExecutor) Execute(ctx context.Context, args ...string) (*Execut
 		if err := e.performInvalidation(ctx, invType, args); err != nil {
 			slog.Warn("Cache invalidation failed",
 				"args", args,
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 			// Continue - command succeeded, just log the invalidation failure
 		}
 	}
@@ -160,7 +162,7 @@ func (e *GitAwareExecutor) performInvalidation(ctx context.Context, invType Inva
 	// Wait for any in-flight rebuilds
 	if err := e.config.Cache.WaitForRebuilds(); err != nil {
 		slog.Warn("Failed to wait for cache rebuilds",
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 		// Continue anyway
 	}
 
@@ -174,7 +176,7 @@ func (e *GitAwareExecutor) performInvalidation(ctx context.Context, invType Inva
 		files, err := extractTargetedFiles(timeoutCtx, args, e.config.WorkDir)
 		if err != nil {
 			slog.Warn("Failed to extract targeted files, falling back to full invalidation",
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 			return e.config.Cache.InvalidateAll()
 		}
 		if len(files) == 0 {
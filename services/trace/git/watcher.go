@@ -17,6 +17,8 @@ import (
 	"path/filepath"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // HeadWatcher watches for changes to .git/HEAD (branch switches).
@@ -88,7 +90,7 @@ func (w *HeadWatcher) Start(ctx context.Context) {
 	if err := w.watcher.Add(headPath); err != nil {
 		slog.Warn("Failed to watch .git/HEAD",
 			"path", headPath,
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 	}
 
 	// Also watch .git/refs/heads for branch updates
@@ -97,7 +99,7 @@ func (w *HeadWatcher) Start(ctx context.Context) {
 		if err := w.watcher.Add(refsPath); err != nil {
 			slog.Debug("Failed to watch refs/heads",
 				"path", refsPath,
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 		}
 	}
 
@@ -107,7 +109,7 @@ func (w *HeadWatcher) Start(ctx context.Context) {
 		if err := w.watcher.Add(packedRefs); err != nil {
 			slog.Debug("Failed to watch packed-refs",
 				"path", packedRefs,
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 		}
 	}
 
@@ -127,7 +129,7 @@ func (w *HeadWatcher) Start(ctx context.Context) {
 				return
 			}
 			slog.Warn("Git HEAD watcher error",
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 
 		case <-ctx.Done():
 			slog.Debug("Git HEAD watcher stopping")
@@ -150,7 +152,7 @@ func (w *HeadWatcher) handleEvent(event fsnotify.Event) {
 	if w.cache != nil {
 		if err := w.cache.InvalidateAll(); err != nil {
 			slog.Warn("Failed to invalidate cache after HEAD change",
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 		}
 	}
 
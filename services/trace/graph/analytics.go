@@ -25,6 +25,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/dgraph-io/badger/v4"
 )
 
@@ -1637,13 +1638,13 @@ func (ga *GraphAnalytics) LCAWithCRS(ctx context.Context, u, v string) (string,
 	// Validate and record
 	if err := step.Validate(); err != nil {
 		ga.logger.Error("invalid step record",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.String("tool", "LCA"),
 		)
 	} else {
 		if err := ga.crs.RecordStep(ctx, step); err != nil {
 			ga.logger.Warn("failed to record step",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "LCA"),
 			)
 		}
@@ -1730,13 +1731,13 @@ func (ga *GraphAnalytics) DistanceWithCRS(ctx context.Context, u, v string) (int
 	// Validate and record
 	if err := step.Validate(); err != nil {
 		ga.logger.Error("invalid step record",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.String("tool", "Distance"),
 		)
 	} else {
 		if err := ga.crs.RecordStep(ctx, step); err != nil {
 			ga.logger.Warn("failed to record step",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "Distance"),
 			)
 		}
@@ -1829,13 +1830,13 @@ func (ga *GraphAnalytics) DecomposePathWithCRS(ctx context.Context, u, v string)
 	// Validate and record
 	if err := step.Validate(); err != nil {
 		ga.logger.Error("invalid step record",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.String("tool", "DecomposePath"),
 		)
 	} else {
 		if err := ga.crs.RecordStep(ctx, step); err != nil {
 			ga.logger.Warn("failed to record step",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "DecomposePath"),
 			)
 		}
@@ -1937,13 +1938,13 @@ func (ga *GraphAnalytics) BatchLCAWithCRS(ctx context.Context, pairs [][2]string
 	// Validate and record
 	if err := step.Validate(); err != nil {
 		ga.logger.Error("invalid step record",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.String("tool", "BatchLCA"),
 		)
 	} else {
 		if err := ga.crs.RecordStep(ctx, step); err != nil {
 			ga.logger.Warn("failed to record step",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "BatchLCA"),
 			)
 		}
@@ -2101,7 +2102,7 @@ func (ga *GraphAnalytics) getCachedPathQueryResult(ctx context.Context, u, v str
 			ga.badgerCacheErrors.Add(1)
 			ga.logger.Warn("badger cache read error",
 				slog.String("key", key),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 		}
 		ga.badgerCacheMisses.Add(1)
@@ -2133,7 +2134,7 @@ func (ga *GraphAnalytics) setCachedPathQueryResult(ctx context.Context, u, v str
 		ga.badgerCacheErrors.Add(1)
 		ga.logger.Warn("badger cache write error",
 			slog.String("key", key),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 	}
 }
@@ -2196,13 +2197,13 @@ func (ga *GraphAnalytics) PathSumWithCRS(ctx context.Context, u, v string, value
 
 			if err := step.Validate(); err != nil {
 				ga.logger.Error("invalid step record",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 					slog.String("tool", "PathSum"),
 				)
 			} else {
 				if err := ga.crs.RecordStep(ctx, step); err != nil {
 					ga.logger.Warn("failed to record step",
-						slog.String("error", err.Error()),
+						telemetry.SensitiveLogError("error", err),
 						slog.String("tool", "PathSum"),
 					)
 				}
@@ -2267,13 +2268,13 @@ func (ga *GraphAnalytics) PathSumWithCRS(ctx context.Context, u, v string, value
 
 		if err := step.Validate(); err != nil {
 			ga.logger.Error("invalid step record",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "PathSum"),
 			)
 		} else {
 			if err := ga.crs.RecordStep(ctx, step); err != nil {
 				ga.logger.Warn("failed to record step",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 					slog.String("tool", "PathSum"),
 				)
 			}
@@ -2331,13 +2332,13 @@ func (ga *GraphAnalytics) PathMinWithCRS(ctx context.Context, u, v string, value
 
 			if err := step.Validate(); err != nil {
 				ga.logger.Error("invalid step record",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 					slog.String("tool", "PathMin"),
 				)
 			} else {
 				if err := ga.crs.RecordStep(ctx, step); err != nil {
 					ga.logger.Warn("failed to record step",
-						slog.String("error", err.Error()),
+						telemetry.SensitiveLogError("error", err),
 						slog.String("tool", "PathMin"),
 					)
 				}
@@ -2399,13 +2400,13 @@ func (ga *GraphAnalytics) PathMinWithCRS(ctx context.Context, u, v string, value
 
 		if err := step.Validate(); err != nil {
 			ga.logger.Error("invalid step record",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "PathMin"),
 			)
 		} else {
 			if err := ga.crs.RecordStep(ctx, step); err != nil {
 				ga.logger.Warn("failed to record step",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 					slog.String("tool", "PathMin"),
 				)
 			}
@@ -2461,13 +2462,13 @@ func (ga *GraphAnalytics) PathMaxWithCRS(ctx context.Context, u, v string, value
 
 			if err := step.Validate(); err != nil {
 				ga.logger.Error("invalid step record",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 					slog.String("tool", "PathMax"),
 				)
 			} else {
 				if err := ga.crs.RecordStep(ctx, step); err != nil {
 					ga.logger.Warn("failed to record step",
-						slog.String("error", err.Error()),
+						telemetry.SensitiveLogError("error", err),
 						slog.String("tool", "PathMax"),
 					)
 				}
@@ -2529,13 +2530,13 @@ func (ga *GraphAnalytics) PathMaxWithCRS(ctx context.Context, u, v string, value
 
 		if err := step.Validate(); err != nil {
 			ga.logger.Error("invalid step record",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "PathMax"),
 			)
 		} else {
 			if err := ga.crs.RecordStep(ctx, step); err != nil {
 				ga.logger.Warn("failed to record step",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 					slog.String("tool", "PathMax"),
 				)
 			}
@@ -2591,13 +2592,13 @@ func (ga *GraphAnalytics) PathGCDWithCRS(ctx context.Context, u, v string, value
 
 			if err := step.Validate(); err != nil {
 				ga.logger.Error("invalid step record",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 					slog.String("tool", "PathGCD"),
 				)
 			} else {
 				if err := ga.crs.RecordStep(ctx, step); err != nil {
 					ga.logger.Warn("failed to record step",
-						slog.String("error", err.Error()),
+						telemetry.SensitiveLogError("error", err),
 						slog.String("tool", "PathGCD"),
 					)
 				}
@@ -2659,13 +2660,13 @@ func (ga *GraphAnalytics) PathGCDWithCRS(ctx context.Context, u, v string, value
 
 		if err := step.Validate(); err != nil {
 			ga.logger.Error("invalid step record",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "PathGCD"),
 			)
 		} else {
 			if err := ga.crs.RecordStep(ctx, step); err != nil {
 				ga.logger.Warn("failed to record step",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 					slog.String("tool", "PathGCD"),
 				)
 			}
@@ -0,0 +1,167 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// AnnotationSource provides the annotations of symbols by stable ID.
+//
+// Implementations must be safe for concurrent use; annotations may change
+// while the graph is read (see annotations.Set).
+type AnnotationSource interface {
+	// Of returns the annotations of a symbol as key → value, or nil.
+	Of(stableID string) map[string]string
+
+	// SymbolIDs returns the stable IDs of all annotated symbols.
+	SymbolIDs() []string
+}
+
+// RecordAnnotations sets where a graph looks up symbol annotations.
+//
+// Description:
+//
+//	Annotations are not copied onto nodes: each lookup asks src by the
+//	symbol's StableID, so annotations changed after the graph was built
+//	are seen immediately and re-attach to the same symbols in the next
+//	build. A nil src clears annotations.
+//
+// Thread Safety: Must be called before the graph is shared for reads.
+func RecordAnnotations(g *Graph, src AnnotationSource) {
+	if g == nil {
+		return
+	}
+	g.annotations = src
+}
+
+// HasAnnotations reports whether an annotation source was recorded for
+// the graph.
+func (g *Graph) HasAnnotations() bool {
+	return g != nil && g.annotations != nil
+}
+
+// AnnotationsOf returns the annotations of a symbol.
+//
+// Outputs:
+//
+//	map[string]string - key → value, or nil if the symbol has none or the
+//	  graph has no annotation source. Callers may modify the map.
+//
+// Thread Safety: Safe for concurrent use.
+func (g *Graph) AnnotationsOf(sym *ast.Symbol) map[string]string {
+	if g == nil || g.annotations == nil || sym == nil || sym.StableID == "" {
+		return nil
+	}
+	return g.annotations.Of(sym.StableID)
+}
+
+// AnnotatedNodes returns the nodes whose annotations match filter, sorted
+// by node ID. Annotated stable IDs without a node in the graph are
+// skipped.
+//
+// Thread Safety: Safe for concurrent use on frozen graphs.
+func (g *Graph) AnnotatedNodes(filter AnnotationFilter) []*Node {
+	if g == nil || g.annotations == nil {
+		return nil
+	}
+	var nodes []*Node
+	for _, id := range g.annotations.SymbolIDs() {
+		node, ok := g.nodesByStableID[id]
+		if !ok || !filter.Matches(g.annotations.Of(id)) {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// AnnotationFilter matches symbols by annotation: "key" matches any
+// symbol with the key, "key=value" only those with that value.
+type AnnotationFilter struct {
+	// Key is the annotation key. Empty matches every symbol.
+	Key string
+
+	// Value is compared when HasValue is set.
+	Value string
+
+	// HasValue is true for the "key=value" form.
+	HasValue bool
+}
+
+// ParseAnnotationFilter parses "key" or "key=value". Surrounding spaces
+// are ignored; an empty string is the zero filter.
+//
+// Outputs:
+//
+//	AnnotationFilter - The filter.
+//	error - Non-nil for "=value" without a key.
+func ParseAnnotationFilter(s string) (AnnotationFilter, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return AnnotationFilter{}, nil
+	}
+	key, value, hasValue := strings.Cut(s, "=")
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return AnnotationFilter{}, fmt.Errorf("annotation filter %q has no key", s)
+	}
+	return AnnotationFilter{Key: key, Value: strings.TrimSpace(value), HasValue: hasValue}, nil
+}
+
+// IsZero reports whether the filter matches everything.
+func (f AnnotationFilter) IsZero() bool {
+	return f.Key == ""
+}
+
+// Matches reports whether annotations satisfy the filter.
+func (f AnnotationFilter) Matches(annotations map[string]string) bool {
+	if f.Key == "" {
+		return true
+	}
+	value, ok := annotations[f.Key]
+	if !ok {
+		return false
+	}
+	return !f.HasValue || value == f.Value
+}
+
+// String returns the filter in the form ParseAnnotationFilter accepts.
+func (f AnnotationFilter) String() string {
+	if f.HasValue {
+		return f.Key + "=" + f.Value
+	}
+	return f.Key
+}
+
+// FormatAnnotations renders annotations as "key: value; key2: value2",
+// sorted by key. A key with an empty value is rendered alone.
+func FormatAnnotations(annotations map[string]string) string {
+	keys := make([]string, 0, len(annotations))
+	for key := range annotations {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if value := annotations[key]; value != "" {
+			parts = append(parts, key+": "+value)
+		} else {
+			parts = append(parts, key)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"slices"
+	"testing"
+)
+
+// mapAnnotationSource is an AnnotationSource over a fixed map.
+type mapAnnotationSource map[string]map[string]string
+
+func (m mapAnnotationSource) Of(stableID string) map[string]string { return m[stableID] }
+
+func (m mapAnnotationSource) SymbolIDs() []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func TestGraph_AnnotatedNodes(t *testing.T) {
+	g := buildStableIDGraph(t, stableIDSource)
+	load, _ := g.StableIDFor(nodeIDByName(t, g, "Load"))
+	save, _ := g.StableIDFor(nodeIDByName(t, g, "Save"))
+	RecordAnnotations(g, mapAnnotationSource{
+		load:             {"deprecated": "use Fetch", "tier": "low"},
+		save:             {"tier": "critical"},
+		"gone.go::X@123": {"tier": "critical"},
+	})
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"tier", []string{"Load", "Save"}},
+		{"tier=critical", []string{"Save"}},
+		{" deprecated ", []string{"Load"}},
+		{"owner", nil},
+	}
+	for _, tt := range tests {
+		filter, err := ParseAnnotationFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseAnnotationFilter(%q): %v", tt.filter, err)
+		}
+		var got []string
+		for _, node := range g.AnnotatedNodes(filter) {
+			got = append(got, node.Symbol.Name)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("AnnotatedNodes(%q) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+
+	node, _ := g.GetNode(nodeIDByName(t, g, "Load"))
+	if got := FormatAnnotations(g.AnnotationsOf(node.Symbol)); got != "deprecated: use Fetch; tier: low" {
+		t.Errorf("FormatAnnotations = %q", got)
+	}
+	if _, err := ParseAnnotationFilter("=x"); err == nil {
+		t.Error("ParseAnnotationFilter(=x) succeeded, want an error")
+	}
+	if filter, _ := ParseAnnotationFilter(""); !filter.IsZero() || !filter.Matches(nil) {
+		t.Error("empty filter should match everything")
+	}
+}
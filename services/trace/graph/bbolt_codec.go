@@ -33,10 +33,11 @@ type nodePayload struct {
 
 // edgePayload is the gob-serializable representation of an Edge.
 type edgePayload struct {
-	FromID   string
-	ToID     string
-	Type     EdgeType
-	Location ast.Location
+	FromID     string
+	ToID       string
+	Type       EdgeType
+	Location   ast.Location
+	Provenance EdgeProvenance
 }
 
 func init() {
@@ -150,10 +151,11 @@ func encodeEdges(edges []*Edge) ([]byte, error) {
 			continue
 		}
 		payloads[i] = edgePayload{
-			FromID:   e.FromID,
-			ToID:     e.ToID,
-			Type:     e.Type,
-			Location: e.Location,
+			FromID:     e.FromID,
+			ToID:       e.ToID,
+			Type:       e.Type,
+			Location:   e.Location,
+			Provenance: e.Provenance,
 		}
 	}
 
@@ -200,10 +202,11 @@ func decodeEdges(data []byte) ([]*Edge, error) {
 	edges := make([]*Edge, len(payloads))
 	for i, p := range payloads {
 		edges[i] = &Edge{
-			FromID:   p.FromID,
-			ToID:     p.ToID,
-			Type:     p.Type,
-			Location: p.Location,
+			FromID:     p.FromID,
+			ToID:       p.ToID,
+			Type:       p.Type,
+			Location:   p.Location,
+			Provenance: p.Provenance,
 		}
 	}
 
@@ -307,6 +310,20 @@ func encodeMetaFileMtimes(value map[string]int64) ([]byte, error) {
 	return gobSnappyEncode(value)
 }
 
+// encodeMetaFileHashes encodes a file content hash map to snappy-compressed gob bytes.
+//
+// Thread Safety: Safe for concurrent use (no shared state).
+func encodeMetaFileHashes(value map[string]string) ([]byte, error) {
+	return gobSnappyEncode(value)
+}
+
+// encodeMetaQualityReport encodes a quality report to snappy-compressed gob bytes.
+//
+// Thread Safety: Safe for concurrent use (no shared state).
+func encodeMetaQualityReport(value *QualityReport) ([]byte, error) {
+	return gobSnappyEncode(value)
+}
+
 // decodeMetaString decodes a string metadata value from snappy-compressed gob bytes.
 //
 // Description:
@@ -442,3 +459,62 @@ func decodeMetaFileMtimes(data []byte) (map[string]int64, error) {
 
 	return mtimes, nil
 }
+
+// decodeMetaFileHashes decodes a file content hash map from snappy-compressed gob bytes.
+//
+// Inputs:
+//
+//	data - The compressed encoded bytes. Must not be empty.
+//
+// Outputs:
+//
+//	map[string]string - The decoded file hashes map.
+//	error - Non-nil if decompression or decoding fails.
+//
+// Thread Safety: Safe for concurrent use (no shared state).
+func decodeMetaFileHashes(data []byte) (map[string]string, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty data for file hashes decode")
+	}
+
+	decoded, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, fmt.Errorf("snappy decoding file hashes: %w", err)
+	}
+
+	var hashes map[string]string
+	if err := gob.NewDecoder(bytes.NewReader(decoded)).Decode(&hashes); err != nil {
+		return nil, fmt.Errorf("gob decoding file hashes: %w", err)
+	}
+
+	return hashes, nil
+}
+
+// decodeMetaQualityReport decodes a quality report from snappy-compressed gob bytes.
+//
+// Inputs:
+//
+//	data - The compressed encoded bytes. Must not be empty.
+//
+// Outputs:
+//
+//	*QualityReport - The decoded report.
+//	error - Non-nil if decompression or decoding fails.
+//
+// Thread Safety: Safe for concurrent use (no shared state).
+func decodeMetaQualityReport(data []byte) (*QualityReport, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty data for quality report decode")
+	}
+
+	decoded, err := snappy.Decode(nil, data)
+	if err != nil {
+		return nil, fmt.Errorf("snappy decoding quality report: %w", err)
+	}
+
+	var report QualityReport
+	if err := gob.NewDecoder(bytes.NewReader(decoded)).Decode(&report); err != nil {
+		return nil, fmt.Errorf("gob decoding quality report: %w", err)
+	}
+	return &report, nil
+}
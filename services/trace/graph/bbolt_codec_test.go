@@ -307,6 +307,7 @@ func TestBboltCodec_EncodeDecodeEdges(t *testing.T) {
 			EdgeTypeUnknown, EdgeTypeCalls, EdgeTypeImports, EdgeTypeDefines,
 			EdgeTypeImplements, EdgeTypeEmbeds, EdgeTypeReferences,
 			EdgeTypeReturns, EdgeTypeReceives, EdgeTypeParameters,
+			EdgeTypeVariantOf,
 		}
 
 		for _, et := range edgeTypes {
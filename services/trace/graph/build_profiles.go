@@ -0,0 +1,148 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// applyBuildProfile drops the Go files that are not part of the build
+// profile.
+//
+// Description:
+//
+//	With AllBuildProfiles every file is kept. Otherwise a Go parse result
+//	whose BuildConstraint does not match BuildProfile is removed. Non-Go
+//	results and nil entries are always kept; collectPhase reports the
+//	latter.
+//
+// Inputs:
+//
+//	results - Parse results in FilePath order. Not modified.
+//
+// Outputs:
+//
+//	[]*ast.ParseResult - The results to build, in the same order.
+//	[]string - File paths of the excluded results, in FilePath order.
+func (b *Builder) applyBuildProfile(results []*ast.ParseResult) ([]*ast.ParseResult, []string) {
+	if b.options.AllBuildProfiles {
+		return results, nil
+	}
+	var excluded []string
+	kept := results[:0:0]
+	for _, r := range results {
+		if r != nil && r.Language == "go" && !b.options.BuildProfile.Matches(r.BuildConstraint) {
+			excluded = append(excluded, r.FilePath)
+			continue
+		}
+		kept = append(kept, r)
+	}
+	if len(excluded) == 0 {
+		return results, nil
+	}
+	slog.Debug("build profile excluded files",
+		slog.String("profile", b.options.BuildProfile.String()),
+		slog.Int("excluded", len(excluded)),
+	)
+	return kept, excluded
+}
+
+// variantKinds are the declaration kinds linked as platform variants.
+var variantKinds = map[ast.SymbolKind]bool{
+	ast.SymbolKindFunction:  true,
+	ast.SymbolKindMethod:    true,
+	ast.SymbolKindInterface: true,
+	ast.SymbolKindStruct:    true,
+	ast.SymbolKindType:      true,
+	ast.SymbolKindVariable:  true,
+	ast.SymbolKindConstant:  true,
+}
+
+// linkBuildVariants links platform variants of the same declaration.
+//
+// Description:
+//
+//	Groups Go declarations by package directory, kind, receiver and name,
+//	and adds EdgeTypeVariantOf edges in both directions between every two
+//	members of a group that ast.IsBuildVariant accepts, e.g. the Open
+//	function of file_unix.go and file_windows.go. Only runs when the
+//	builder keeps all build profiles; with a single profile at most one
+//	variant survives.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and tracing.
+//	state - Build state with the graph and symbol indexes.
+//
+// Thread Safety: Runs on the build goroutine after edge extraction.
+func (b *Builder) linkBuildVariants(ctx context.Context, state *buildState) {
+	_, span := tracer.Start(ctx, "GraphBuilder.linkBuildVariants")
+	defer span.End()
+
+	groups := make(map[string][]*ast.Symbol)
+	var keys []string
+	for _, sym := range state.symbolsInIDOrder() {
+		if sym.Language != "go" || !variantKinds[sym.Kind] {
+			continue
+		}
+		key := strings.Join([]string{filepath.Dir(sym.FilePath), sym.Kind.String(), sym.Receiver, sym.Name}, "\x00")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], sym)
+	}
+
+	linked := 0
+	addEdge := func(from, to *ast.Symbol) {
+		if err := stateAddEdge(state, from.ID, to.ID, EdgeTypeVariantOf, from.Location(), EdgeProvenance{Pass: PassBuildVariant, Confidence: ConfidenceHigh}); err != nil {
+			if strings.Contains(err.Error(), "already exists") {
+				return
+			}
+			stateAddEdgeError(state, EdgeError{
+				FromID:   from.ID,
+				ToID:     to.ID,
+				EdgeType: EdgeTypeVariantOf,
+				Err:      fmt.Errorf("build variant edge: %w", err),
+			})
+			return
+		}
+		stateStats(state).EdgesCreated++
+		stateStats(state).VariantEdges++
+		linked++
+	}
+
+	for _, key := range keys {
+		if ctx.Err() != nil {
+			slog.Debug("context cancelled during build variant linking")
+			break
+		}
+		group := groups[key]
+		for i := 0; i < len(group); i++ {
+			for j := i + 1; j < len(group); j++ {
+				if !ast.IsBuildVariant(group[i], group[j]) {
+					continue
+				}
+				addEdge(group[i], group[j])
+				addEdge(group[j], group[i])
+			}
+		}
+	}
+
+	span.SetAttributes(attribute.Int("variant_edges", linked))
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// buildProfileTestFiles declares Open once per platform, plus a shared file.
+var buildProfileTestFiles = map[string]string{
+	"fsys/open_linux.go":   "package fsys\n\nfunc Open(name string) error { return nil }\n",
+	"fsys/open_windows.go": "package fsys\n\nfunc Open(name string) error { return nil }\n",
+	"fsys/open_other.go":   "//go:build !linux && !windows\n\npackage fsys\n\nfunc Open(name string) error { return nil }\n",
+	"fsys/file.go":         "package fsys\n\nfunc Read(name string) error { return Open(name) }\n",
+}
+
+// buildProfileTestGraph parses buildProfileTestFiles and builds them with opts.
+func buildProfileTestGraph(t *testing.T, opts ...BuilderOption) *BuildResult {
+	t.Helper()
+	ctx := context.Background()
+
+	var results []*ast.ParseResult
+	for path, content := range buildProfileTestFiles {
+		r, err := ast.NewGoParser().Parse(ctx, []byte(content), path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		results = append(results, r)
+	}
+	result, err := NewBuilder(opts...).Build(ctx, results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return result
+}
+
+// openFiles returns the files declaring Open in g.
+func openFiles(g *Graph) []string {
+	var files []string
+	for _, n := range g.GetNodesByName("Open") {
+		files = append(files, n.Symbol.FilePath)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestBuilder_BuildProfile(t *testing.T) {
+	t.Run("single profile keeps matching files", func(t *testing.T) {
+		result := buildProfileTestGraph(t, WithBuildProfile(ast.GoBuildProfile{GOOS: "windows", GOARCH: "amd64"}))
+
+		if got := openFiles(result.Graph); len(got) != 1 || got[0] != "fsys/open_windows.go" {
+			t.Errorf("Open declared in %v, want only fsys/open_windows.go", got)
+		}
+		want := []string{"fsys/open_linux.go", "fsys/open_other.go"}
+		if len(result.ProfileExcludedFiles) != 2 ||
+			result.ProfileExcludedFiles[0] != want[0] || result.ProfileExcludedFiles[1] != want[1] {
+			t.Errorf("ProfileExcludedFiles = %v, want %v", result.ProfileExcludedFiles, want)
+		}
+		if result.Stats.FilesExcludedByProfile != 2 {
+			t.Errorf("FilesExcludedByProfile = %d, want 2", result.Stats.FilesExcludedByProfile)
+		}
+		if len(result.Graph.GetEdgesByType(EdgeTypeVariantOf)) != 0 {
+			t.Error("single profile build should have no variant edges")
+		}
+	})
+
+	t.Run("constraint expressions", func(t *testing.T) {
+		result := buildProfileTestGraph(t, WithBuildProfile(ast.GoBuildProfile{GOOS: "darwin", GOARCH: "arm64"}))
+		if got := openFiles(result.Graph); len(got) != 1 || got[0] != "fsys/open_other.go" {
+			t.Errorf("Open declared in %v, want only fsys/open_other.go", got)
+		}
+	})
+
+	t.Run("all profiles links variants", func(t *testing.T) {
+		result := buildProfileTestGraph(t, WithAllBuildProfiles())
+
+		if got := openFiles(result.Graph); len(got) != 3 {
+			t.Fatalf("Open declared in %v, want all three variants", got)
+		}
+		if len(result.ProfileExcludedFiles) != 0 {
+			t.Errorf("ProfileExcludedFiles = %v, want none", result.ProfileExcludedFiles)
+		}
+
+		// Three variants, linked pairwise in both directions.
+		edges := result.Graph.GetEdgesByType(EdgeTypeVariantOf)
+		if len(edges) != 6 || result.Stats.VariantEdges != 6 {
+			t.Fatalf("variant edges = %d (stats %d), want 6", len(edges), result.Stats.VariantEdges)
+		}
+		for _, e := range edges {
+			from, _ := result.Graph.GetNode(e.FromID)
+			to, _ := result.Graph.GetNode(e.ToID)
+			if from.Symbol.Name != "Open" || to.Symbol.Name != "Open" || from.Symbol.FilePath == to.Symbol.FilePath {
+				t.Errorf("unexpected variant edge %s -> %s", e.FromID, e.ToID)
+			}
+		}
+	})
+}
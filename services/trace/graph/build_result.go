@@ -56,6 +56,16 @@ func (e EdgeError) Unwrap() error {
 	return e.Err
 }
 
+// LanguageStats counts the files and symbols of one language in a build.
+type LanguageStats struct {
+	// Files is the number of files processed.
+	Files int
+
+	// Symbols is the number of nodes added for the files' symbols,
+	// children included.
+	Symbols int
+}
+
 // BuildStats contains statistics about a build operation.
 type BuildStats struct {
 	// FilesProcessed is the number of files successfully processed.
@@ -70,10 +80,30 @@ type BuildStats struct {
 	// EdgesCreated is the number of edges added to the graph.
 	EdgesCreated int
 
+	// ByLanguage breaks FilesProcessed and NodesCreated down by the
+	// language of the parse results (ast.ParseResult.Language).
+	ByLanguage map[string]LanguageStats
+
 	// PlaceholderNodes is the number of placeholder nodes created for
 	// external/unresolved symbols.
 	PlaceholderNodes int
 
+	// CollapsedPlaceholderMembers is the number of member names recorded
+	// on package placeholders under PlaceholderCollapsed.
+	CollapsedPlaceholderMembers int
+
+	// PlaceholderEdgesOmitted is the number of edges to external symbols
+	// dropped under PlaceholderOmit.
+	PlaceholderEdgesOmitted int
+
+	// CrossModuleEdges is the number of import and call edges between
+	// files of different Go modules of the project (BuilderOptions.GoModules).
+	CrossModuleEdges int
+
+	// StdlibPlaceholdersEnriched is the number of Go standard library
+	// placeholders given a signature and doc from the embedded index.
+	StdlibPlaceholdersEnriched int
+
 	// AmbiguousResolves is the number of call resolutions that matched
 	// multiple symbols (over-approximated by creating edges to all).
 	AmbiguousResolves int
@@ -93,12 +123,43 @@ type BuildStats struct {
 	// See GR-41 for details.
 	CallEdgesUnresolved int
 
+	// CallEdgesByInferredType is the number of resolved EdgeTypeCalls
+	// edges whose Go method target was picked by the receiver variable's
+	// declared or inferred type (PassCallInferredType).
+	CallEdgesByInferredType int
+
+	// CallEdgesByHeuristic is the number of resolved EdgeTypeCalls edges
+	// whose method target was guessed: by receiver name matching or by
+	// the method and variable fallbacks.
+	CallEdgesByHeuristic int
+
+	// ChainHopsResolved is the number of chained method calls (hops after
+	// the first of a.B().C()) resolved by walking the chain's return
+	// types (PassCallChain).
+	ChainHopsResolved int
+
+	// ChainHopsAbandoned is the number of chained method calls whose walk
+	// stopped at a hop with an unknown type or method. They are left to
+	// the other strategies.
+	ChainHopsAbandoned int
+
 	// NamedImportEdgesResolved is the number of EdgeTypeReferences edges
 	// created by the named import resolution pass (GR-62). Each represents
 	// a "from X import Y" statement where Y was resolved to an in-project
 	// symbol node.
 	NamedImportEdgesResolved int
 
+	// AmbiguousImports is the number of Python named imports resolved
+	// among several equally matching files. BuildResult.AmbiguousImports
+	// holds the first maxErrorSliceLen of them.
+	AmbiguousImports int
+
+	// ReExportsResolved is the number of Python imports and calls resolved
+	// through a package __init__.py re-export ("from .impl import Thing") to
+	// the defining module. Counted in both the named import pass and call
+	// resolution, so one re-exported name may be counted several times.
+	ReExportsResolved int
+
 	// CommonJSImportEdgesResolved is the number of EdgeTypeReferences edges
 	// created by the CommonJS import alias resolution pass (IT-06d Bug D).
 	// Each represents a "var X = require('./module')" statement where the
@@ -117,6 +178,64 @@ type BuildStats struct {
 	// @NgModule({imports: [X]}) decorator array that resolved to an in-project symbol.
 	DecoratorArgEdgesResolved int
 
+	// SQLReferenceEdgesResolved is the number of EdgeTypeReferences edges
+	// created by the SQL reference pass: functions to the tables their query
+	// strings name, db-tagged struct fields to SQL columns, and their
+	// structs to the matching tables.
+	SQLReferenceEdgesResolved int
+
+	// FieldAccessEdgesResolved is the number of EdgeTypeReferences edges
+	// from Go functions to the struct fields they read or write.
+	FieldAccessEdgesResolved int
+
+	// FieldAccessesUnresolved is the number of Go field accesses that
+	// matched no tracked field or more than one.
+	FieldAccessesUnresolved int
+
+	// StructsOverFieldCap is the number of Go structs left out of field
+	// access resolution for having more than
+	// BuilderOptions.MaxTrackedStructFields fields.
+	StructsOverFieldCap int
+
+	// LiteralUsagesIndexed is the number of call-site literal arguments
+	// indexed for Graph.FindLiteralUsages.
+	LiteralUsagesIndexed int
+
+	// LiteralUsagesDropped is the number of call-site literal arguments
+	// left out of the index by BuilderOptions.MaxLiteralUsages.
+	LiteralUsagesDropped int
+
+	// ProtoServiceEdgesResolved is the number of edges created by the
+	// protobuf service pass: EdgeTypeImplements from Go server types to
+	// proto services and from Go methods to rpcs, and EdgeTypeReferences
+	// from rpcs to their request and response messages.
+	ProtoServiceEdgesResolved int
+
+	// TemplateRenderEdgesResolved is the number of EdgeTypeReferences edges
+	// from Go functions to the template files they parse or execute.
+	TemplateRenderEdgesResolved int
+
+	// TemplateIncludeEdgesResolved is the number of EdgeTypeReferences edges
+	// from Go template files to the template files they include.
+	TemplateIncludeEdgesResolved int
+
+	// CSSSelectorEdgesResolved is the number of EdgeTypeReferences edges
+	// from HTML elements and files to the CSS selectors targeting them.
+	CSSSelectorEdgesResolved int
+
+	// DOMSelectorEdgesResolved is the number of EdgeTypeReferences edges
+	// from JavaScript and TypeScript functions to the HTML elements their
+	// DOM lookups select.
+	DOMSelectorEdgesResolved int
+
+	// FilesExcludedByProfile is the number of Go files left out because
+	// their build constraint does not match BuilderOptions.BuildProfile.
+	FilesExcludedByProfile int
+
+	// VariantEdges is the number of EdgeTypeVariantOf edges linking
+	// platform variants. Only non-zero with BuilderOptions.AllBuildProfiles.
+	VariantEdges int
+
 	// DurationMilli is the total build time in milliseconds.
 	// NOTE: For fast builds (< 1ms), this rounds to 0. Use DurationMicro for precision.
 	DurationMilli int64
@@ -142,9 +261,22 @@ type BuildStats struct {
 	// GR-71: Tracked for observability — helps diagnose false-positive edge creation.
 	ValidationRejected int
 
+	// EdgesDisabled is the number of edges dropped because their pass was
+	// disabled with WithDisabledPasses.
+	EdgesDisabled int
+
 	// LSPEnrichment contains statistics from the LSP enrichment phase (GR-74).
 	// Zero-valued if LSP enrichment was not configured or not run.
 	LSPEnrichment EnrichmentStats
+
+	// ContentHash is Graph.ContentHash() of the finished graph. Identical
+	// source yields the same value regardless of WorkerCount or input order.
+	// Empty if the build was interrupted before finalization.
+	ContentHash string
+
+	// CommitSHA is the commit the files were read at when the build used
+	// BuilderOptions.SourceRevision. Empty for builds from the filesystem.
+	CommitSHA string
 }
 
 // EnrichmentStats contains statistics about the LSP enrichment phase.
@@ -209,6 +341,30 @@ func (e *EnrichmentStats) Merge(delta EnrichmentStats) {
 	e.FilesQueried = delta.FilesQueried
 }
 
+// AmbiguousImport is a Python import that could name a definition in
+// more than one file, typically the same module path under two namespace
+// package roots.
+type AmbiguousImport struct {
+	// FilePath is the importing file.
+	FilePath string `json:"file_path"`
+
+	// Line is the line of the import statement.
+	Line int `json:"line"`
+
+	// ModulePath is the imported module path as written.
+	ModulePath string `json:"module_path"`
+
+	// Name is the imported name.
+	Name string `json:"name"`
+
+	// Candidates are the files defining Name that match ModulePath equally
+	// well, sorted.
+	Candidates []string `json:"candidates"`
+
+	// Chosen is the candidate the import's edges point at.
+	Chosen string `json:"chosen"`
+}
+
 // BuildResult contains the result of a graph build operation.
 //
 // Build operations are designed to be resilient: individual file failures
@@ -227,6 +383,15 @@ type BuildResult struct {
 	// The graph may still contain valid edges despite these errors.
 	EdgeErrors []EdgeError
 
+	// AmbiguousImports lists the Python imports whose module path matched
+	// the imported name in more than one file, ordered by FilePath and
+	// Line. Capped at maxErrorSliceLen.
+	AmbiguousImports []AmbiguousImport
+
+	// ProfileExcludedFiles lists the Go files left out because their build
+	// constraint does not match the build profile, in FilePath order.
+	ProfileExcludedFiles []string
+
 	// Stats contains build statistics.
 	Stats BuildStats
 
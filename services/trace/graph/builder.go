@@ -21,7 +21,6 @@ import (
 	"sort"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 	"unicode"
 
@@ -29,6 +28,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Default builder configuration values.
@@ -43,6 +43,12 @@ const (
 	// Set to 0 to use runtime.NumCPU().
 	DefaultWorkerCount = 0
 
+	// DefaultMaxTrackedStructFields is the default per-struct field limit
+	// of the field access pass. Structs with more fields (generated API
+	// types, wide config structs) are skipped: their fields are resolved
+	// by name only and would contribute mostly noise.
+	DefaultMaxTrackedStructFields = 256
+
 	// maxEmbedResolutionDepth is the maximum recursion depth for resolvePromotedMethods
 	// and resolveInterfaceEmbedsRecursive. Prevents stack overflow on pathological
 	// interface/struct embedding chains. 20 is generous for any realistic codebase
@@ -131,9 +137,9 @@ type BuilderOptions struct {
 	WorkerCount int
 
 	// ProgressCallback is called periodically with build progress.
-	// May be nil. GR-73: Must be goroutine-safe — with WorkerCount > 1,
-	// this callback may be invoked from multiple goroutines concurrently.
-	// Progress reports may arrive out-of-order.
+	// May be nil. GR-73: With WorkerCount > 1 the callback may be invoked
+	// from different worker goroutines, but calls are serialized and
+	// FilesProcessed is monotonically non-decreasing within a phase.
 	ProgressCallback ProgressFunc
 
 	// MaxNodes is the maximum number of nodes (passed to Graph).
@@ -142,10 +148,70 @@ type BuilderOptions struct {
 	// MaxEdges is the maximum number of edges (passed to Graph).
 	MaxEdges int
 
+	// DisableStringInterning turns off string interning in the built
+	// graph (passed to Graph).
+	// Default: false (interning enabled)
+	DisableStringInterning bool
+
 	// LSPEnrichment configures optional LSP-based placeholder resolution.
 	// GR-74: When non-nil with a valid Querier, an enrichment phase runs
 	// between edge extraction and finalization.
 	LSPEnrichment *LSPEnrichmentConfig
+
+	// BuildProfile is the GOOS/GOARCH/tags the graph is built for. Go
+	// files whose build constraint does not match it are left out.
+	// Ignored when AllBuildProfiles is set.
+	// Default: ast.DefaultGoBuildProfile()
+	BuildProfile ast.GoBuildProfile
+
+	// AllBuildProfiles keeps Go files of every platform and links
+	// same-named declarations from differently constrained files with
+	// EdgeTypeVariantOf edges.
+	AllBuildProfiles bool
+
+	// SourceRevision is the commit SHA the parse results were read at
+	// when they come from git objects instead of a checkout. File paths
+	// then name blobs in that commit's tree, not files under ProjectRoot,
+	// so the builder does not stat them or hand them to LSP enrichment.
+	// Recorded in BuildStats.CommitSHA.
+	// Default: "" (files are read from ProjectRoot)
+	SourceRevision string
+
+	// DisabledPasses holds edge passes whose edges are not added, for
+	// bisecting which pass produced a bad edge. Dropped edges are counted
+	// in BuildStats.EdgesDisabled.
+	// Default: nil (every pass runs)
+	DisabledPasses map[EdgePass]bool
+
+	// MaxTrackedStructFields is the number of fields above which a Go
+	// struct is left out of field access resolution. Zero or negative
+	// disables field access edges entirely.
+	// Default: DefaultMaxTrackedStructFields
+	MaxTrackedStructFields int
+
+	// MaxLiteralUsages is the maximum number of call-site literal
+	// arguments indexed for FindLiteralUsages (passed to Graph). Literals
+	// over the cap are counted in BuildStats.LiteralUsagesDropped.
+	// Default: DefaultMaxLiteralUsages
+	MaxLiteralUsages int
+
+	// PythonSourceRoots are the package directories declared by the
+	// project's pyproject.toml and setup.cfg files. Python imports are
+	// matched against module paths under these roots before falling back
+	// to path suffix matching.
+	// Default: nil (module paths are taken from the project layout)
+	PythonSourceRoots []PythonSourceRoot
+
+	// GoModules are the Go modules whose source is in the project, as
+	// declared by its go.mod and go.work files. Go imports under a module
+	// path resolve to the package directory in the project.
+	// Default: nil (Go imports are matched by path suffix)
+	GoModules []GoModule
+
+	// PlaceholderPolicy selects how symbols outside the project are
+	// represented (see PlaceholderPolicy). Empty means PlaceholderFull.
+	// Default: PlaceholderFull
+	PlaceholderPolicy PlaceholderPolicy
 }
 
 // DefaultBuilderOptions returns sensible defaults.
@@ -155,6 +221,11 @@ func DefaultBuilderOptions() BuilderOptions {
 		WorkerCount: runtime.NumCPU(),
 		MaxNodes:    DefaultMaxNodes,
 		MaxEdges:    DefaultMaxEdges,
+
+		BuildProfile:           ast.DefaultGoBuildProfile(),
+		MaxTrackedStructFields: DefaultMaxTrackedStructFields,
+		MaxLiteralUsages:       DefaultMaxLiteralUsages,
+		PlaceholderPolicy:      PlaceholderFull,
 	}
 }
 
@@ -203,6 +274,14 @@ func WithBuilderMaxEdges(n int) BuilderOption {
 	}
 }
 
+// WithBuilderStringInterning enables or disables string interning in the
+// built graph.
+func WithBuilderStringInterning(enabled bool) BuilderOption {
+	return func(o *BuilderOptions) {
+		o.DisableStringInterning = !enabled
+	}
+}
+
 // WithLSPEnrichment configures LSP-based placeholder resolution.
 //
 // Description:
@@ -220,6 +299,129 @@ func WithLSPEnrichment(config *LSPEnrichmentConfig) BuilderOption {
 	}
 }
 
+// WithBuildProfile sets the GOOS/GOARCH/tags the graph is built for.
+//
+// Description:
+//
+//	Go files whose build constraint (ast.ParseResult.BuildConstraint)
+//	does not match the profile are excluded from the graph and listed in
+//	BuildResult.ProfileExcludedFiles.
+func WithBuildProfile(profile ast.GoBuildProfile) BuilderOption {
+	return func(o *BuilderOptions) {
+		o.BuildProfile = profile
+	}
+}
+
+// WithAllBuildProfiles keeps Go files for every platform in the graph.
+//
+// Description:
+//
+//	Instead of picking one profile, every file is kept and same-named
+//	declarations from files with different build constraints are linked
+//	as variants with EdgeTypeVariantOf edges in both directions.
+func WithAllBuildProfiles() BuilderOption {
+	return func(o *BuilderOptions) {
+		o.AllBuildProfiles = true
+	}
+}
+
+// WithSourceRevision marks the parse results as read from a git commit.
+//
+// Description:
+//
+//	Set when the files were enumerated from a commit's tree rather than
+//	the working tree (see BuilderOptions.SourceRevision). File mtimes are
+//	not recorded and LSP enrichment is skipped, since neither the files
+//	nor a language server's view of them exist on disk at that revision.
+//
+// Inputs:
+//
+//	sha - The resolved commit SHA.
+func WithSourceRevision(sha string) BuilderOption {
+	return func(o *BuilderOptions) {
+		o.SourceRevision = sha
+	}
+}
+
+// WithDisabledPasses drops the edges of the given passes.
+//
+// Description:
+//
+//	Edges whose EdgeProvenance.Pass is one of passes are not added to the
+//	graph. Used to bisect a wrong edge to the pass that created it: build
+//	with the suspect pass disabled and compare. PassUnknown is ignored.
+//
+// Inputs:
+//
+//	passes - The passes to disable. Use ParseEdgePasses for names.
+func WithDisabledPasses(passes ...EdgePass) BuilderOption {
+	return func(o *BuilderOptions) {
+		for _, p := range passes {
+			if p == PassUnknown {
+				continue
+			}
+			if o.DisabledPasses == nil {
+				o.DisabledPasses = make(map[EdgePass]bool)
+			}
+			o.DisabledPasses[p] = true
+		}
+	}
+}
+
+// WithPythonSourceRoots sets the Python source roots used to resolve
+// imports.
+//
+// Description:
+//
+//	Each root makes the Python files under its directory importable under
+//	its package prefix, so "from acme.models import Order" resolves to
+//	libs/core/src/acme/models.py when libs/core/src is a root. Use
+//	ParsePythonPackageConfig to read the roots of a packaging config.
+//
+// Inputs:
+//
+//	roots - The roots. Appended to any set earlier.
+func WithPythonSourceRoots(roots ...PythonSourceRoot) BuilderOption {
+	return func(o *BuilderOptions) {
+		o.PythonSourceRoots = append(o.PythonSourceRoots, roots...)
+	}
+}
+
+// WithGoModules sets the Go modules used to resolve imports.
+//
+// Description:
+//
+//	Each module maps its path to its project directory, so in a
+//	multi-module repository "example.com/repo/billing/invoice", imported
+//	from another module, resolves to billing/invoice instead of a
+//	placeholder. Use ParseGoModuleConfig to read the modules of a go.mod
+//	or go.work.
+//
+// Inputs:
+//
+//	modules - The modules. Appended to any set earlier.
+func WithGoModules(modules ...GoModule) BuilderOption {
+	return func(o *BuilderOptions) {
+		o.GoModules = append(o.GoModules, modules...)
+	}
+}
+
+// WithMaxTrackedStructFields sets the per-struct field limit of the field
+// access pass. Zero or negative disables field access edges.
+func WithMaxTrackedStructFields(n int) BuilderOption {
+	return func(o *BuilderOptions) {
+		o.MaxTrackedStructFields = n
+	}
+}
+
+// WithBuilderMaxLiteralUsages sets the maximum number of call-site literal
+// arguments the built graph indexes.
+func WithBuilderMaxLiteralUsages(n int) BuilderOption {
+	return func(o *BuilderOptions) {
+		o.MaxLiteralUsages = n
+	}
+}
+
 // Builder constructs code graphs from parsed AST results.
 //
 // The builder is stateless and can be reused across multiple builds.
@@ -292,27 +494,147 @@ type buildState struct {
 	// "merge" from "pandas.core.reshape.merge", we can resolve to the right symbol.
 	importNameMap map[string]map[string]importEntry
 
+	// pythonReExports maps a package __init__.py path → exported name → importEntry.
+	// Built by buildImportNameMap from the __init__.py's own named imports, with
+	// relative module paths ("from .impl import Thing") made absolute against the
+	// package directory. Lets resolution follow one level of re-export from the
+	// package to the defining module.
+	pythonReExports map[string]map[string]importEntry
+
+	// pythonModuleAliases maps filePath → local name → module path for bare
+	// Python imports ("import pandas as pd" → pd: "pandas"; "import os.path" →
+	// os: "os"). Used to resolve attribute-chain calls like pd.core.frame.f().
+	pythonModuleAliases map[string]map[string]string
+
 	// symbolsByLocation maps "filePath:startLine" to symbol IDs at that location.
 	// GR-74: Built during collectPhase. Used by LSP enrichment to match definition
 	// locations returned by LSP servers to existing graph nodes.
 	symbolsByLocation map[string][]string
 
+	// disabledPasses holds the passes whose edges are dropped, from
+	// BuilderOptions.DisabledPasses. Nil when every pass runs.
+	disabledPasses map[EdgePass]bool
+
 	// GR-73: When non-nil, per-file edge extraction writes to this collector
 	// instead of directly to the graph. Used during parallel edge extraction.
 	collector *edgeCollector
 }
 
+// symbolsInIDOrder returns the collected symbols sorted by ID, for phases
+// whose output would otherwise depend on map iteration order.
+func (s *buildState) symbolsInIDOrder() []*ast.Symbol {
+	symbols := make([]*ast.Symbol, 0, len(s.symbolsByID))
+	for _, sym := range s.symbolsByID {
+		symbols = append(symbols, sym)
+	}
+	sort.Slice(symbols, func(i, j int) bool {
+		return symbols[i].ID < symbols[j].ID
+	})
+	return symbols
+}
+
+// lookupSymbol returns the collected symbol with the given ID, or nil.
+func (s *buildState) lookupSymbol(id string) *ast.Symbol {
+	return s.symbolsByID[id]
+}
+
+// sortParseResults returns a copy of results ordered by FilePath, with nil
+// entries last. The copy keeps the caller's slice untouched.
+func sortParseResults(results []*ast.ParseResult) []*ast.ParseResult {
+	sorted := make([]*ast.ParseResult, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a == nil || b == nil {
+			return a != nil && b == nil
+		}
+		return a.FilePath < b.FilePath
+	})
+	return sorted
+}
+
+// sortBuildErrors orders FileErrors by path, EdgeErrors by
+// (FromID, ToID, EdgeType, message) and AmbiguousImports by
+// (FilePath, Line, Name) so their order does not depend on worker
+// scheduling or map iteration.
+func sortBuildErrors(result *BuildResult) {
+	sort.SliceStable(result.FileErrors, func(i, j int) bool {
+		return result.FileErrors[i].FilePath < result.FileErrors[j].FilePath
+	})
+	sort.SliceStable(result.EdgeErrors, func(i, j int) bool {
+		a, b := result.EdgeErrors[i], result.EdgeErrors[j]
+		if a.FromID != b.FromID {
+			return a.FromID < b.FromID
+		}
+		if a.ToID != b.ToID {
+			return a.ToID < b.ToID
+		}
+		if a.EdgeType != b.EdgeType {
+			return a.EdgeType < b.EdgeType
+		}
+		return errString(a.Err) < errString(b.Err)
+	})
+	sort.SliceStable(result.AmbiguousImports, func(i, j int) bool {
+		a, b := result.AmbiguousImports[i], result.AmbiguousImports[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Name < b.Name
+	})
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// orderPlaceholderIndexes moves placeholder nodes behind real nodes in the
+// name and kind indexes and sorts them by ID.
+//
+// Real nodes are indexed in collect order, which is deterministic once parse
+// results are sorted. Placeholders are created on first use in sequential
+// builds but in ID order during the parallel merge, so without this the
+// order of GetNodesByName results would depend on WorkerCount.
+func orderPlaceholderIndexes(g *Graph) {
+	less := func(nodes []*Node) func(i, j int) bool {
+		return func(i, j int) bool {
+			pi, pj := isPlaceholderNode(nodes[i]), isPlaceholderNode(nodes[j])
+			if pi != pj {
+				return pj
+			}
+			return pi && nodes[i].ID < nodes[j].ID
+		}
+	}
+	for _, nodes := range g.nodesByName {
+		sort.SliceStable(nodes, less(nodes))
+	}
+	for _, nodes := range g.nodesByKind {
+		sort.SliceStable(nodes, less(nodes))
+	}
+}
+
+func isPlaceholderNode(n *Node) bool {
+	return n != nil && n.Symbol != nil && n.Symbol.Kind == ast.SymbolKindExternal
+}
+
 // pendingEdge represents an edge to be inserted into the graph during the merge phase.
 // GR-73: Workers produce pendingEdges instead of calling AddEdge directly.
 type pendingEdge struct {
-	FromID   string
-	ToID     string
-	Type     EdgeType
-	Location ast.Location
+	FromID     string
+	ToID       string
+	Type       EdgeType
+	Location   ast.Location
+	Provenance EdgeProvenance
 }
 
 // pendingPlaceholder represents a placeholder node to be created during the merge phase.
 // GR-73: Workers compute deterministic IDs and record placeholder requests.
+// Under PlaceholderCollapsed several requests share an ID, one per member.
 type pendingPlaceholder struct {
 	ID   string
 	Pkg  string
@@ -333,7 +655,7 @@ type workerResult struct {
 // gets its own collector.
 type edgeCollector struct {
 	edges        []pendingEdge
-	placeholders map[string]pendingPlaceholder // keyed by deterministic ID for dedup
+	placeholders map[string]pendingPlaceholder // keyed by deterministic ID and name for dedup
 	edgeErrors   []EdgeError
 	stats        BuildStats
 }
@@ -356,7 +678,10 @@ func (c *edgeCollector) toWorkerResult() workerResult {
 	}
 	// Sort placeholders by ID for deterministic creation order during merge.
 	sort.Slice(placeholders, func(i, j int) bool {
-		return placeholders[i].ID < placeholders[j].ID
+		if placeholders[i].ID != placeholders[j].ID {
+			return placeholders[i].ID < placeholders[j].ID
+		}
+		return placeholders[i].Name < placeholders[j].Name
 	})
 	return workerResult{
 		Edges:        c.edges,
@@ -375,36 +700,52 @@ func stateStats(state *buildState) *BuildStats {
 	return &state.result.Stats
 }
 
+// errPassDisabled is returned by stateAddEdge for an edge whose pass was
+// disabled with WithDisabledPasses. stateAddEdgeError ignores it.
+var errPassDisabled = errors.New("edge pass disabled")
+
 // stateAddEdge adds an edge to the graph (sequential) or buffers it (parallel).
 // In parallel mode, errors are not possible since edges are just buffered.
 // In sequential mode, returns the error from Graph.AddEdge.
-func stateAddEdge(state *buildState, fromID, toID string, edgeType EdgeType, loc ast.Location) error {
+// Edges of a disabled pass are dropped and counted with errPassDisabled.
+// Edges to an omitted placeholder (empty toID) are dropped and counted
+// with errPlaceholderOmitted.
+func stateAddEdge(state *buildState, fromID, toID string, edgeType EdgeType, loc ast.Location, prov EdgeProvenance) error {
+	if state.disabledPasses[prov.Pass] {
+		stateStats(state).EdgesDisabled++
+		return errPassDisabled
+	}
+	if toID == "" {
+		stateStats(state).PlaceholderEdgesOmitted++
+		return errPlaceholderOmitted
+	}
 	if state.collector != nil {
 		state.collector.edges = append(state.collector.edges, pendingEdge{
-			FromID:   fromID,
-			ToID:     toID,
-			Type:     edgeType,
-			Location: loc,
+			FromID:     fromID,
+			ToID:       toID,
+			Type:       edgeType,
+			Location:   loc,
+			Provenance: prov,
 		})
 		return nil
 	}
-	return state.graph.AddEdge(fromID, toID, edgeType, loc)
+	return state.graph.AddEdgeWithProvenance(fromID, toID, edgeType, loc, prov)
 }
 
 // stateGetOrCreatePlaceholder returns a placeholder ID. In parallel mode,
 // records the placeholder request without creating the node. In sequential mode,
-// delegates to the builder's getOrCreatePlaceholder.
+// delegates to the builder's getOrCreatePlaceholder. Returns "" under
+// PlaceholderOmit; stateAddEdge drops edges to "".
 func stateGetOrCreatePlaceholder(b *Builder, state *buildState, pkg, name string) string {
 	if state.collector != nil {
 		// Compute deterministic ID without graph access
-		var id string
-		if pkg != "" {
-			id = fmt.Sprintf("external:%s:%s", pkg, name)
-		} else {
-			id = fmt.Sprintf("external::%s", name)
+		id, _ := placeholderID(b.options.PlaceholderPolicy, pkg, name)
+		if id == "" {
+			return ""
 		}
-		if _, exists := state.collector.placeholders[id]; !exists {
-			state.collector.placeholders[id] = pendingPlaceholder{
+		key := id + "\x00" + name
+		if _, exists := state.collector.placeholders[key]; !exists {
+			state.collector.placeholders[key] = pendingPlaceholder{
 				ID:   id,
 				Pkg:  pkg,
 				Name: name,
@@ -418,6 +759,9 @@ func stateGetOrCreatePlaceholder(b *Builder, state *buildState, pkg, name string
 
 // stateAddEdgeError adds an edge error to the collector (parallel) or result (sequential).
 func stateAddEdgeError(state *buildState, ee EdgeError) {
+	if errors.Is(ee.Err, errPassDisabled) || errors.Is(ee.Err, errPlaceholderOmitted) {
+		return
+	}
 	if state.collector != nil {
 		state.collector.edgeErrors = append(state.collector.edgeErrors, ee)
 		return
@@ -507,32 +851,35 @@ type importEntry struct {
 //  2. EXTRACT EDGES: Create edges for imports, calls, implements, etc.
 //  3. LSP ENRICHMENT (optional): Resolve placeholder targets via LSP definition lookup (GR-74)
 //  4. FINALIZE: Freeze graph and compute statistics
+//
+// Determinism:
+//
+//	Parse results are processed in FilePath order and every output that
+//	could depend on scheduling (placeholder index order, error order) is
+//	canonicalized, so the same input yields the same graph, Stats and
+//	Stats.ContentHash for any WorkerCount and any input order.
 func (b *Builder) Build(ctx context.Context, results []*ast.ParseResult) (*BuildResult, error) {
 	// Start tracing span
 	ctx, span := startBuildSpan(ctx, len(results))
 	defer span.End()
 
-	state := &buildState{
-		graph: NewGraph(b.options.ProjectRoot,
-			WithMaxNodes(b.options.MaxNodes),
-			WithMaxEdges(b.options.MaxEdges),
-		),
-		result: &BuildResult{
-			FileErrors: make([]FileError, 0),
-			EdgeErrors: make([]EdgeError, 0),
-		},
-		symbolsByID:            make(map[string]*ast.Symbol),
-		symbolsByName:          make(map[string][]*ast.Symbol),
-		fileImports:            make(map[string][]ast.Import),
-		placeholders:           make(map[string]*Node),
-		symbolParent:           make(map[string]string),
-		classExtends:           make(map[string]string),
-		classAdditionalParents: make(map[string][]string),
-		importNameMap:          make(map[string]map[string]importEntry),
-		symbolsByLocation:      make(map[string][]string),
-		startTime:              time.Now(),
-	}
-	state.result.Graph = state.graph
+	results = sortParseResults(results)
+	parsed := results
+	results, profileExcluded := b.applyBuildProfile(results)
+
+	state := b.newBuildState(NewGraph(b.options.ProjectRoot,
+		WithMaxNodes(b.options.MaxNodes),
+		WithMaxEdges(b.options.MaxEdges),
+		WithMaxLiteralUsages(b.options.MaxLiteralUsages),
+		WithStringInterning(!b.options.DisableStringInterning),
+	))
+	state.result.ProfileExcludedFiles = profileExcluded
+	state.graph.PythonSourceRoots = b.options.PythonSourceRoots
+	state.graph.GoModules = b.options.GoModules
+	state.graph.PlaceholderPolicy = b.options.PlaceholderPolicy
+	stateStats(state).CommitSHA = b.options.SourceRevision
+	stateStats(state).FilesExcludedByProfile = len(profileExcluded)
+	defer sortBuildErrors(state.result)
 
 	// Phase 1: Collect symbols as nodes
 	if err := b.collectPhase(ctx, state, results); err != nil {
@@ -562,14 +909,19 @@ func (b *Builder) Build(ctx context.Context, results []*ast.ParseResult) (*Build
 		return state.result, fmt.Errorf("graph build interrupted: %w", err)
 	}
 
+	// Platform variants are only present when every profile is kept.
+	if b.options.AllBuildProfiles {
+		b.linkBuildVariants(ctx, state)
+	}
+
 	// Phase 2.5: LSP Enrichment (optional)
 	// GR-74: Query LSP servers to resolve placeholder edge targets.
-	if b.options.LSPEnrichment != nil && b.options.LSPEnrichment.Querier != nil {
+	if b.options.LSPEnrichment != nil && b.options.LSPEnrichment.Querier != nil && b.options.SourceRevision == "" {
 		enrichStats, enrichErr := b.lspEnrichmentPhase(ctx, state)
 		state.result.Stats.LSPEnrichment = enrichStats
 		if enrichErr != nil {
 			slog.Warn("GR-74: LSP enrichment failed",
-				slog.String("error", enrichErr.Error()),
+				telemetry.SensitiveLogError("error", enrichErr),
 				slog.Int("resolved", enrichStats.PlaceholdersResolved),
 				slog.Int("failed", enrichStats.PlaceholdersFailed),
 			)
@@ -577,10 +929,21 @@ func (b *Builder) Build(ctx context.Context, results []*ast.ParseResult) (*Build
 	}
 
 	// Phase 3: Finalize
+	stateStats(state).StdlibPlaceholdersEnriched = enrichStdlibPlaceholders(state, nil)
+	orderPlaceholderIndexes(state.graph)
 	state.graph.Freeze()
+	stateStats(state).ContentHash = state.graph.ContentHash()
+	stateStats(state).LiteralUsagesIndexed, stateStats(state).LiteralUsagesDropped = state.graph.LiteralUsageCounts()
+	state.graph.QualityReport = NewQualityReport(state.graph, buildParseErrorFiles(state.result, results))
 
 	// CRS-19: Record file modification times for staleness detection across sessions.
-	RecordFileMtimes(state.graph, b.options.ProjectRoot)
+	// Files read from a revision have no mtime; the commit SHA identifies them.
+	if b.options.SourceRevision == "" {
+		RecordFileMtimes(state.graph, b.options.ProjectRoot)
+	}
+	// Files the build profile left out were still parsed; recording them
+	// keeps clients that compare hashes from seeing them as new files.
+	RecordFileHashes(state.graph, parsed)
 
 	duration := time.Since(state.startTime)
 	stateStats(state).DurationMilli = duration.Milliseconds()
@@ -595,6 +958,32 @@ func (b *Builder) Build(ctx context.Context, results []*ast.ParseResult) (*Build
 	return state.result, nil
 }
 
+// newBuildState returns an empty build state that adds to g.
+func (b *Builder) newBuildState(g *Graph) *buildState {
+	state := &buildState{
+		graph: g,
+		result: &BuildResult{
+			FileErrors: make([]FileError, 0),
+			EdgeErrors: make([]EdgeError, 0),
+		},
+		symbolsByID:            make(map[string]*ast.Symbol),
+		symbolsByName:          make(map[string][]*ast.Symbol),
+		fileImports:            make(map[string][]ast.Import),
+		placeholders:           make(map[string]*Node),
+		symbolParent:           make(map[string]string),
+		classExtends:           make(map[string]string),
+		classAdditionalParents: make(map[string][]string),
+		importNameMap:          make(map[string]map[string]importEntry),
+		pythonReExports:        make(map[string]map[string]importEntry),
+		pythonModuleAliases:    make(map[string]map[string]string),
+		symbolsByLocation:      make(map[string][]string),
+		disabledPasses:         b.options.DisabledPasses,
+		startTime:              time.Now(),
+	}
+	state.result.Graph = g
+	return state
+}
+
 // collectPhase validates parse results and adds symbols as nodes.
 func (b *Builder) collectPhase(ctx context.Context, state *buildState, results []*ast.ParseResult) error {
 	for i, r := range results {
@@ -622,8 +1011,12 @@ func (b *Builder) collectPhase(ctx context.Context, state *buildState, results [
 		// Store imports for edge extraction
 		state.fileImports[r.FilePath] = r.Imports
 
+		// Line-independent IDs must be set before the symbols are shared.
+		ast.AssignStableIDs(r.Symbols)
+
 		// Add symbols as nodes
 		maxNodesHit := false
+		nodesBefore := stateStats(state).NodesCreated
 		for _, sym := range r.Symbols {
 			if sym == nil {
 				continue
@@ -668,11 +1061,19 @@ func (b *Builder) collectPhase(ctx context.Context, state *buildState, results [
 				state.classAdditionalParents[sym.Name] = sym.Metadata.Implements
 			}
 
+			// Mixins (PHP traits, Ruby modules) contribute methods reachable
+			// through $this and self.
+			if sym.Metadata != nil && len(sym.Metadata.Mixins) > 0 {
+				parents := append([]string(nil), state.classAdditionalParents[sym.Name]...)
+				state.classAdditionalParents[sym.Name] = append(parents, sym.Metadata.Mixins...)
+			}
+
 			// Recursively add children with parent tracking
 			b.addChildSymbols(state, sym.Children, sym.ID)
 		}
 
 		stateStats(state).FilesProcessed++
+		recordLanguageStats(stateStats(state), r.Language, stateStats(state).NodesCreated-nodesBefore)
 		b.reportProgress(state, ProgressPhaseCollecting, len(results), i+1)
 
 		// GR-70: Break file loop when node limit reached.
@@ -696,6 +1097,18 @@ func (b *Builder) collectPhase(ctx context.Context, state *buildState, results [
 	return nil
 }
 
+// recordLanguageStats counts one processed file of language with symbols
+// nodes in stats.ByLanguage.
+func recordLanguageStats(stats *BuildStats, language string, symbols int) {
+	if stats.ByLanguage == nil {
+		stats.ByLanguage = make(map[string]LanguageStats)
+	}
+	ls := stats.ByLanguage[language]
+	ls.Files++
+	ls.Symbols += symbols
+	stats.ByLanguage[language] = ls
+}
+
 // addChildSymbols recursively adds child symbols to the graph.
 // parentID tracks the owning symbol for reverse parent lookup.
 func (b *Builder) addChildSymbols(state *buildState, children []*ast.Symbol, parentID string) {
@@ -741,6 +1154,13 @@ func (b *Builder) addChildSymbols(state *buildState, children []*ast.Symbol, par
 			state.classAdditionalParents[child.Name] = child.Metadata.Implements
 		}
 
+		// Mixins (PHP traits, Ruby modules) contribute methods reachable
+		// through $this and self.
+		if child.Metadata != nil && len(child.Metadata.Mixins) > 0 {
+			parents := append([]string(nil), state.classAdditionalParents[child.Name]...)
+			state.classAdditionalParents[child.Name] = append(parents, child.Metadata.Mixins...)
+		}
+
 		// Recurse
 		b.addChildSymbols(state, child.Children, child.ID)
 	}
@@ -764,7 +1184,9 @@ func (b *Builder) extractEdgesPhase(ctx context.Context, state *buildState, resu
 
 	// GR-73: Use sequential path for single worker or small workloads.
 	if workerCount <= 1 || len(validResults) <= 1 {
-		b.extractEdgesSequential(ctx, state, validResults)
+		if err := b.extractEdgesSequential(ctx, state, validResults); err != nil {
+			return err
+		}
 	} else {
 		if err := b.extractEdgesParallel(ctx, state, validResults, workerCount); err != nil {
 			return err
@@ -818,6 +1240,25 @@ func (b *Builder) extractEdgesPhase(ctx context.Context, state *buildState, resu
 	// UserService, making AppModule appear in find_references for UserService.
 	b.resolveDecoratorArgEdges(ctx, state, results)
 
+	// Link code to SQL schema symbols: query strings to tables, db-tagged
+	// struct fields to columns. Makes "which functions touch the users
+	// table" answerable with find_references on the table.
+	b.resolveSQLReferenceEdges(ctx, state, results)
+
+	// Link Go functions to the struct fields they read and write, for
+	// field-level trace_data_flow.
+	b.resolveFieldAccessEdges(ctx, state, results)
+
+	// Link protobuf services to their Go implementations and rpcs to their
+	// messages. Runs after interface detection so the generated
+	// <Service>Server interfaces already have their IMPLEMENTS edges.
+	b.resolveProtoServiceEdges(ctx, state, results)
+
+	// Link Go template calls to template files, templates to the templates
+	// they include, and HTML elements to the CSS selectors and DOM lookups
+	// that target them.
+	b.resolveWebLinkEdges(ctx, state, results)
+
 	// GR-41: Record call edge metrics after all edges extracted
 	recordCallEdgeMetrics(ctx,
 		stateStats(state).CallEdgesResolved,
@@ -830,11 +1271,13 @@ func (b *Builder) extractEdgesPhase(ctx context.Context, state *buildState, resu
 
 // extractEdgesSequential processes files sequentially (WorkerCount=1 path).
 // This is the original extraction loop, preserved for correctness baseline.
-func (b *Builder) extractEdgesSequential(ctx context.Context, state *buildState, results []*ast.ParseResult) {
+// Returns the context error on cancellation; edges from files processed
+// before cancellation remain in the graph.
+func (b *Builder) extractEdgesSequential(ctx context.Context, state *buildState, results []*ast.ParseResult) error {
 	for i, r := range results {
 		if err := ctx.Err(); err != nil {
 			state.result.Incomplete = true
-			return
+			return err
 		}
 
 		b.extractFileEdges(ctx, state, r)
@@ -854,20 +1297,32 @@ func (b *Builder) extractEdgesSequential(ctx context.Context, state *buildState,
 		if b.options.MaxMemoryMB > 0 && (i+1)%memoryCheckInterval == 0 {
 			if err := b.checkMemoryLimit(); err != nil {
 				state.result.Incomplete = true
-				return
+				return nil
 			}
 		}
 	}
+	return nil
 }
 
 // extractEdgesParallel processes files in parallel using worker goroutines.
 // GR-73: Each worker gets an edgeCollector; results are merged single-threaded.
 //
+// Description:
+//
+//	The lookup maps on buildState (symbolsByID, symbolsByName, symbolParent,
+//	classExtends, importNameMap, ...) are fully populated by collectPhase
+//	and buildImportNameMap before this runs, so workers only read them.
+//	On context cancellation, whatever each worker collected is still merged
+//	so the caller gets a partial graph with the edges of every file that
+//	was processed, marked Incomplete.
+//
 // Thread Safety:
 //
 //	Workers read shared state (symbolsByID, symbolsByName, etc.) concurrently.
 //	Workers write only to their local edgeCollector — no shared mutation.
-//	The merge phase runs single-threaded after all workers complete.
+//	Progress reports are serialized through progressMu so FilesProcessed
+//	never goes backwards. The merge phase runs single-threaded after all
+//	workers complete.
 func (b *Builder) extractEdgesParallel(ctx context.Context, state *buildState, results []*ast.ParseResult, workerCount int) error {
 	if workerCount > len(results) {
 		workerCount = len(results)
@@ -882,7 +1337,8 @@ func (b *Builder) extractEdgesParallel(ctx context.Context, state *buildState, r
 	// Launch workers
 	workerResults := make([]workerResult, workerCount)
 	var wg sync.WaitGroup
-	var filesProcessed atomic.Int64
+	var progressMu sync.Mutex
+	filesProcessed := 0
 	cancelCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
@@ -892,6 +1348,11 @@ func (b *Builder) extractEdgesParallel(ctx context.Context, state *buildState, r
 			defer wg.Done()
 
 			collector := newEdgeCollector()
+			// Hand back whatever was collected, even on early return, so a
+			// cancelled build still merges the edges of processed files.
+			defer func() {
+				workerResults[workerIdx] = collector.toWorkerResult()
+			}()
 
 			// Create a worker-local buildState that shares read-only maps
 			// but writes to the local collector.
@@ -909,7 +1370,10 @@ func (b *Builder) extractEdgesParallel(ctx context.Context, state *buildState, r
 				classExtends:           state.classExtends,
 				classAdditionalParents: state.classAdditionalParents,
 				importNameMap:          state.importNameMap,
+				pythonReExports:        state.pythonReExports,
+				pythonModuleAliases:    state.pythonModuleAliases,
 				symbolsByLocation:      state.symbolsByLocation,
+				disabledPasses:         state.disabledPasses,
 				startTime:              state.startTime,
 				collector:              collector,
 			}
@@ -921,8 +1385,10 @@ func (b *Builder) extractEdgesParallel(ctx context.Context, state *buildState, r
 
 				b.extractFileEdges(cancelCtx, workerState, r)
 
-				processed := filesProcessed.Add(1)
-				b.reportProgress(state, ProgressPhaseExtractingEdges, len(results), int(processed))
+				progressMu.Lock()
+				filesProcessed++
+				b.reportProgress(state, ProgressPhaseExtractingEdges, len(results), filesProcessed)
+				progressMu.Unlock()
 
 				// GR-73/GR-70: Check memory limit periodically (checkMemoryLimit is safe
 				// to call concurrently — runtime.ReadMemStats uses STW).
@@ -933,8 +1399,6 @@ func (b *Builder) extractEdgesParallel(ctx context.Context, state *buildState, r
 					}
 				}
 			}
-
-			workerResults[workerIdx] = collector.toWorkerResult()
 		}(w, partitions[w])
 	}
 
@@ -945,10 +1409,13 @@ func (b *Builder) extractEdgesParallel(ctx context.Context, state *buildState, r
 	// calls cancel() on cancelCtx, which does not propagate to the parent ctx.
 	if cancelCtx.Err() != nil {
 		state.result.Incomplete = true
-		// If the parent context is also cancelled, return that error.
+		// If the parent context is also cancelled, keep the partial edges
+		// and return that error.
 		if ctx.Err() != nil {
+			b.mergeWorkerResults(state, workerResults)
 			return ctx.Err()
 		}
+		// Memory limit: merging would only grow the heap further.
 		return ErrMemoryLimitExceeded
 	}
 
@@ -963,17 +1430,19 @@ func (b *Builder) extractEdgesParallel(ctx context.Context, state *buildState, r
 //
 // Thread Safety: Must be called single-threaded after all workers complete.
 func (b *Builder) mergeWorkerResults(state *buildState, results []workerResult) {
-	// Phase 1: Create all placeholder nodes (deduped across workers)
+	// Phase 1: Create all placeholder nodes (deduped across workers).
+	// Collapsed placeholders are requested once per member.
 	seenPlaceholders := make(map[string]bool)
 	for _, wr := range results {
 		for _, p := range wr.Placeholders {
-			if seenPlaceholders[p.ID] {
+			key := p.ID + "\x00" + p.Name
+			if seenPlaceholders[key] {
 				continue
 			}
-			seenPlaceholders[p.ID] = true
+			seenPlaceholders[key] = true
 
 			// Only create if not already in graph (may exist from collectPhase)
-			if _, exists := state.graph.GetNode(p.ID); !exists {
+			if node, exists := state.graph.GetNode(p.ID); !exists || isPlaceholderNode(node) {
 				b.getOrCreatePlaceholder(state, p.Pkg, p.Name)
 			}
 		}
@@ -982,7 +1451,7 @@ func (b *Builder) mergeWorkerResults(state *buildState, results []workerResult)
 	// Phase 2: Insert all edges
 	for _, wr := range results {
 		for _, pe := range wr.Edges {
-			err := state.graph.AddEdge(pe.FromID, pe.ToID, pe.Type, pe.Location)
+			err := state.graph.AddEdgeWithProvenance(pe.FromID, pe.ToID, pe.Type, pe.Location, pe.Provenance)
 			if err != nil && !strings.Contains(err.Error(), "already exists") {
 				appendEdgeError(state, EdgeError{
 					FromID:   pe.FromID,
@@ -1009,8 +1478,16 @@ func (b *Builder) mergeWorkerResults(state *buildState, results []workerResult)
 		state.result.Stats.AmbiguousResolves += wr.Stats.AmbiguousResolves
 		state.result.Stats.CallEdgesResolved += wr.Stats.CallEdgesResolved
 		state.result.Stats.CallEdgesUnresolved += wr.Stats.CallEdgesUnresolved
+		state.result.Stats.CallEdgesByInferredType += wr.Stats.CallEdgesByInferredType
+		state.result.Stats.CallEdgesByHeuristic += wr.Stats.CallEdgesByHeuristic
+		state.result.Stats.ChainHopsResolved += wr.Stats.ChainHopsResolved
+		state.result.Stats.ChainHopsAbandoned += wr.Stats.ChainHopsAbandoned
+		state.result.Stats.ReExportsResolved += wr.Stats.ReExportsResolved
 		state.result.Stats.ValidationBypassed += wr.Stats.ValidationBypassed
 		state.result.Stats.ValidationRejected += wr.Stats.ValidationRejected
+		state.result.Stats.EdgesDisabled += wr.Stats.EdgesDisabled
+		state.result.Stats.PlaceholderEdgesOmitted += wr.Stats.PlaceholderEdgesOmitted
+		state.result.Stats.CrossModuleEdges += wr.Stats.CrossModuleEdges
 	}
 
 	// GR-70: Check if max edges was exceeded during merge
@@ -1078,7 +1555,7 @@ func (b *Builder) extractImportEdges(ctx context.Context, state *buildState, r *
 	// GR-41c: OTel tracing for observability
 	_, span := tracer.Start(ctx, "GraphBuilder.extractImportEdges",
 		trace.WithAttributes(
-			attribute.String("file", r.FilePath),
+			telemetry.SensitiveString("file", r.FilePath),
 			attribute.Int("import_count", len(r.Imports)),
 		),
 	)
@@ -1088,7 +1565,7 @@ func (b *Builder) extractImportEdges(ctx context.Context, state *buildState, r *
 	sourceID := findPackageSymbolID(r)
 	if sourceID == "" {
 		slog.Debug("GR-41c: No package symbol found for import edges (barrel file)",
-			slog.String("file", r.FilePath),
+			telemetry.SensitiveLog("file", r.FilePath),
 			slog.Int("import_count", len(r.Imports)),
 		)
 		span.SetAttributes(attribute.Bool("no_source_symbol", true))
@@ -1098,8 +1575,8 @@ func (b *Builder) extractImportEdges(ctx context.Context, state *buildState, r *
 	// GR-41c: Verify sourceID exists in graph before using (I-1: use GetNode method)
 	if _, exists := state.graph.GetNode(sourceID); !exists {
 		slog.Debug("GR-41c: Package symbol not in graph",
-			slog.String("file", r.FilePath),
-			slog.String("source_id", sourceID),
+			telemetry.SensitiveLog("file", r.FilePath),
+			telemetry.SensitiveLog("source_id", sourceID),
 		)
 		span.SetAttributes(attribute.Bool("source_not_in_graph", true))
 		return
@@ -1113,7 +1590,7 @@ func (b *Builder) extractImportEdges(ctx context.Context, state *buildState, r *
 		if i > 0 && i%10 == 0 {
 			if ctx.Err() != nil {
 				slog.Debug("GR-41c: Context cancelled during import edge extraction",
-					slog.String("file", r.FilePath),
+					telemetry.SensitiveLog("file", r.FilePath),
 					slog.Int("processed", i),
 					slog.Int("total", len(r.Imports)),
 				)
@@ -1125,11 +1602,22 @@ func (b *Builder) extractImportEdges(ctx context.Context, state *buildState, r *
 				return
 			}
 		}
-		// Create placeholder for imported package
-		pkgID := stateGetOrCreatePlaceholder(b, state, imp.Path, imp.Path)
+		// A Go import under a project module targets the package in the
+		// project; others a placeholder for the imported package.
+		prov := EdgeProvenance{Pass: PassImport, Confidence: ConfidenceHigh}
+		pkgID, inProject := "", false
+		if r.Language == "go" {
+			pkgID = b.goModulePackage(state, imp.Path)
+			inProject = pkgID != ""
+		}
+		if inProject {
+			prov.Detail = "go-module"
+		} else {
+			pkgID = stateGetOrCreatePlaceholder(b, state, imp.Path, imp.Path)
+		}
 
 		// Create edge from package symbol to imported package
-		err := stateAddEdge(state, sourceID, pkgID, EdgeTypeImports, imp.Location)
+		err := stateAddEdge(state, sourceID, pkgID, EdgeTypeImports, imp.Location, prov)
 		if err != nil {
 			// Check if it's a duplicate edge error (not fatal)
 			if !strings.Contains(err.Error(), "already exists") {
@@ -1141,10 +1629,10 @@ func (b *Builder) extractImportEdges(ctx context.Context, state *buildState, r *
 				})
 				edgesFailed++
 				slog.Debug("GR-41c: Failed to create import edge",
-					slog.String("file", r.FilePath),
-					slog.String("from", sourceID),
-					slog.String("to", pkgID),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLog("file", r.FilePath),
+					telemetry.SensitiveLog("from", sourceID),
+					telemetry.SensitiveLog("to", pkgID),
+					telemetry.SensitiveLogError("error", err),
 				)
 			}
 			continue
@@ -1152,11 +1640,14 @@ func (b *Builder) extractImportEdges(ctx context.Context, state *buildState, r *
 
 		stateStats(state).EdgesCreated++
 		edgesCreated++
+		if inProject && b.crossesGoModules(r.FilePath, state.symbolsByID[pkgID].FilePath) {
+			stateStats(state).CrossModuleEdges++
+		}
 
 		slog.Debug("GR-41c: Created import edge",
-			slog.String("file", r.FilePath),
-			slog.String("from", sourceID),
-			slog.String("import", imp.Path),
+			telemetry.SensitiveLog("file", r.FilePath),
+			telemetry.SensitiveLog("from", sourceID),
+			telemetry.SensitiveLog("import", imp.Path),
 		)
 	}
 
@@ -1231,6 +1722,7 @@ func (b *Builder) extractSymbolEdges(ctx context.Context, state *buildState, sym
 		b.extractImplementsEdges(state, sym)
 		// Extract embeds edges from fields
 		b.extractEmbedsEdges(state, sym)
+		b.extractMixinEdges(state, sym)
 
 	case ast.SymbolKindInterface:
 		// Phase 18: Interfaces can embed other interfaces — create EMBEDS edges
@@ -1264,7 +1756,7 @@ func (b *Builder) extractReceiverEdge(state *buildState, sym *ast.Symbol) {
 	}
 
 	for _, targetID := range targets {
-		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReceives, sym.Location())
+		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReceives, sym.Location(), nameMatchProvenance(PassReceiver, targets))
 		if err != nil {
 			stateAddEdgeError(state, EdgeError{
 				FromID:   sym.ID,
@@ -1302,7 +1794,7 @@ func (b *Builder) extractReturnTypeEdges(state *buildState, sym *ast.Symbol) {
 	}
 
 	for _, targetID := range targets {
-		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReturns, sym.Location())
+		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReturns, sym.Location(), nameMatchProvenance(PassReturnType, targets))
 		if err != nil {
 			stateAddEdgeError(state, EdgeError{
 				FromID:   sym.ID,
@@ -1350,14 +1842,14 @@ func (b *Builder) extractImplementsEdges(state *buildState, sym *ast.Symbol) {
 			if !b.validateEdgeType(state, sym.ID, targetID, EdgeTypeImplements) {
 				stateStats(state).ValidationRejected++
 				slog.Debug("GR-71: edge rejected by validateEdgeType",
-					slog.String("from", sym.ID),
-					slog.String("to", targetID),
+					telemetry.SensitiveLog("from", sym.ID),
+					telemetry.SensitiveLog("to", targetID),
 					slog.String("edge_type", EdgeTypeImplements.String()),
 				)
 				continue
 			}
 
-			err := stateAddEdge(state, sym.ID, targetID, EdgeTypeImplements, sym.Location())
+			err := stateAddEdge(state, sym.ID, targetID, EdgeTypeImplements, sym.Location(), nameMatchProvenance(PassDeclaredImplements, targets))
 			if err != nil {
 				stateAddEdgeError(state, EdgeError{
 					FromID:   sym.ID,
@@ -1391,7 +1883,7 @@ func (b *Builder) extractEmbedsEdges(state *buildState, sym *ast.Symbol) {
 	}
 
 	for _, targetID := range targets {
-		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeEmbeds, sym.Location())
+		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeEmbeds, sym.Location(), nameMatchProvenance(PassEmbeds, targets))
 		if err != nil {
 			stateAddEdgeError(state, EdgeError{
 				FromID:   sym.ID,
@@ -1423,7 +1915,7 @@ func (b *Builder) extractEmbedsEdges(state *buildState, sym *ast.Symbol) {
 				addlTargets = []string{targetID}
 			}
 			for _, targetID := range addlTargets {
-				err := stateAddEdge(state, sym.ID, targetID, EdgeTypeEmbeds, sym.Location())
+				err := stateAddEdge(state, sym.ID, targetID, EdgeTypeEmbeds, sym.Location(), nameMatchProvenance(PassEmbeds, addlTargets))
 				if err != nil {
 					stateAddEdgeError(state, EdgeError{
 						FromID:   sym.ID,
@@ -1439,6 +1931,48 @@ func (b *Builder) extractEmbedsEdges(state *buildState, sym *ast.Symbol) {
 	}
 }
 
+// extractMixinEdges creates EMBEDS edges from a class to the types listed
+// in Metadata.Mixins (PHP traits, Ruby modules added with include,
+// prepend or extend).
+//
+// Description:
+//
+//	A trait's or module's methods are copied into every class that uses
+//	it, which is what an embedded field does for a Go struct. Modelling trait use as
+//	EMBEDS lets mergeEmbeddedMethods promote the trait's methods, so a
+//	class whose interface methods come from a trait is still found to
+//	implement the interface.
+//
+// Thread Safety: Not safe for concurrent use; called during the build.
+func (b *Builder) extractMixinEdges(state *buildState, sym *ast.Symbol) {
+	if sym.Metadata == nil {
+		return
+	}
+	for _, mixin := range sym.Metadata.Mixins {
+		targets := b.resolveSymbolByName(state, mixin, sym.FilePath)
+		if len(targets) == 0 {
+			targetID := stateGetOrCreatePlaceholder(b, state, placeholderPkg(sym), mixin)
+			targets = []string{targetID}
+		}
+		for _, targetID := range targets {
+			err := stateAddEdge(state, sym.ID, targetID, EdgeTypeEmbeds, sym.Location(), nameMatchProvenance(PassMixin, targets))
+			if err != nil {
+				stateAddEdgeError(state, EdgeError{
+					FromID:   sym.ID,
+					ToID:     targetID,
+					EdgeType: EdgeTypeEmbeds,
+					Err:      err,
+				})
+				continue
+			}
+			stateStats(state).EdgesCreated++
+		}
+		if len(targets) > 1 {
+			stateStats(state).AmbiguousResolves++
+		}
+	}
+}
+
 // extractInterfaceEmbedsEdges creates EMBEDS edges from an interface to its embedded interfaces.
 //
 // Description:
@@ -1475,7 +2009,7 @@ func (b *Builder) extractInterfaceEmbedsEdges(state *buildState, sym *ast.Symbol
 	}
 
 	for _, targetID := range targets {
-		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeEmbeds, sym.Location())
+		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeEmbeds, sym.Location(), nameMatchProvenance(PassInterfaceEmbeds, targets))
 		if err != nil {
 			stateAddEdgeError(state, EdgeError{
 				FromID:   sym.ID,
@@ -1497,7 +2031,7 @@ func (b *Builder) extractInterfaceEmbedsEdges(state *buildState, sym *ast.Symbol
 				addlTargets = []string{targetID}
 			}
 			for _, targetID := range addlTargets {
-				err := stateAddEdge(state, sym.ID, targetID, EdgeTypeEmbeds, sym.Location())
+				err := stateAddEdge(state, sym.ID, targetID, EdgeTypeEmbeds, sym.Location(), nameMatchProvenance(PassInterfaceEmbeds, addlTargets))
 				if err != nil {
 					stateAddEdgeError(state, EdgeError{
 						FromID:   sym.ID,
@@ -1542,7 +2076,7 @@ func (b *Builder) extractDecoratorArgEdges(state *buildState, sym *ast.Symbol) {
 			}
 
 			for _, targetID := range targets {
-				err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, sym.Location())
+				err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, sym.Location(), nameMatchProvenance(PassDecoratorArg, targets))
 				if err != nil {
 					stateAddEdgeError(state, EdgeError{
 						FromID:   sym.ID,
@@ -1581,7 +2115,7 @@ func (b *Builder) extractTypeArgEdges(state *buildState, sym *ast.Symbol) {
 			continue // Don't create placeholders for type args
 		}
 		for _, targetID := range targets {
-			err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, sym.Location())
+			err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, sym.Location(), nameMatchProvenance(PassTypeArg, targets))
 			if err != nil && !strings.Contains(err.Error(), "already exists") {
 				stateAddEdgeError(state, EdgeError{
 					FromID:   sym.ID,
@@ -1610,7 +2144,7 @@ func (b *Builder) extractTypeNarrowingEdges(state *buildState, sym *ast.Symbol)
 			continue
 		}
 		for _, targetID := range targets {
-			err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, sym.Location())
+			err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, sym.Location(), nameMatchProvenance(PassTypeNarrowing, targets))
 			if err != nil && !strings.Contains(err.Error(), "already exists") {
 				stateAddEdgeError(state, EdgeError{
 					FromID:   sym.ID,
@@ -1668,7 +2202,7 @@ func (b *Builder) extractTypeRefEdges(state *buildState, sym *ast.Symbol) {
 		// containing `-> Series` or `: Series`, not the function's `def` line.
 		edgeLoc := typeRef.Location
 		for _, targetID := range targets {
-			err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, edgeLoc)
+			err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, edgeLoc, nameMatchProvenance(PassTypeRef, targets))
 			if err != nil && !strings.Contains(err.Error(), "already exists") {
 				stateAddEdgeError(state, EdgeError{
 					FromID:   sym.ID,
@@ -1713,8 +2247,8 @@ func (b *Builder) extractCallEdges(ctx context.Context, state *buildState, sym *
 	// GR-41: OTel tracing for observability
 	_, span := tracer.Start(ctx, "GraphBuilder.extractCallEdges",
 		trace.WithAttributes(
-			attribute.String("symbol.id", sym.ID),
-			attribute.String("symbol.name", sym.Name),
+			telemetry.SensitiveString("symbol.id", sym.ID),
+			telemetry.SensitiveString("symbol.name", sym.Name),
 			attribute.Int("call_sites.count", len(sym.Calls)),
 		),
 	)
@@ -1730,23 +2264,40 @@ func (b *Builder) extractCallEdges(ctx context.Context, state *buildState, sym *
 		}
 
 		// Try to resolve the target to a symbol ID
-		targetID := b.resolveCallTarget(state, call, sym)
+		targetID, prov := b.resolveCallTargetWithProvenance(state, call, sym)
 		if targetID == "" {
 			// IT-05a: Infer package from the calling file's imports before
 			// creating the placeholder. This gives external nodes accurate
 			// package information (e.g., "pd.read_csv" → package "pandas").
 			pkg := inferPackageFromCall(call, state.fileImports[sym.FilePath])
 			targetID = stateGetOrCreatePlaceholder(b, state, pkg, call.Target)
+			prov = EdgeProvenance{Pass: PassCallPlaceholder, Confidence: ConfidenceUnresolved}
 			callsUnresolved++
 		} else {
 			callsResolved++
+			switch prov.Pass {
+			case PassCallInferredType:
+				stateStats(state).CallEdgesByInferredType++
+			case PassReceiverMatch, PassCallMethodFallback, PassCallVariableFallback:
+				stateStats(state).CallEdgesByHeuristic++
+			}
+			if target := state.symbolsByID[targetID]; target != nil && b.crossesGoModules(sym.FilePath, target.FilePath) {
+				stateStats(state).CrossModuleEdges++
+			}
+		}
+		if len(call.Chain) > 0 {
+			if prov.Pass == PassCallChain {
+				stateStats(state).ChainHopsResolved++
+			} else {
+				stateStats(state).ChainHopsAbandoned++
+			}
 		}
 
 		// Skip self-referential calls (recursive calls are valid but don't need edges)
 		if targetID == sym.ID {
 			slog.Debug("GR-41: Skipping self-referential call",
-				slog.String("symbol", sym.Name),
-				slog.String("target", call.Target),
+				telemetry.SensitiveLog("symbol", sym.Name),
+				telemetry.SensitiveLog("target", call.Target),
 			)
 			continue
 		}
@@ -1755,15 +2306,15 @@ func (b *Builder) extractCallEdges(ctx context.Context, state *buildState, sym *
 		if !b.validateEdgeType(state, sym.ID, targetID, EdgeTypeCalls) {
 			stateStats(state).ValidationRejected++
 			slog.Debug("GR-71: edge rejected by validateEdgeType",
-				slog.String("from", sym.ID),
-				slog.String("to", targetID),
+				telemetry.SensitiveLog("from", sym.ID),
+				telemetry.SensitiveLog("to", targetID),
 				slog.String("edge_type", EdgeTypeCalls.String()),
 			)
 			continue
 		}
 
 		// Create the edge
-		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeCalls, call.Location)
+		err := stateAddEdge(state, sym.ID, targetID, EdgeTypeCalls, call.Location, prov)
 		if err != nil {
 			// Check if it's a duplicate edge error (not fatal)
 			if !strings.Contains(err.Error(), "already exists") {
@@ -1783,6 +2334,11 @@ func (b *Builder) extractCallEdges(ctx context.Context, state *buildState, sym *
 	for _, call := range sym.Calls {
 		for _, argName := range call.FunctionArgs {
 			targets := b.resolveSymbolByName(state, argName, sym.FilePath)
+			if sym.Language == "go" {
+				// Go records every identifier argument (route registration
+				// needs them), so only function values count as callbacks.
+				targets = b.filterFunctionSymbols(state, targets)
+			}
 			if len(targets) == 0 {
 				continue // Don't create placeholders for callback args
 			}
@@ -1790,7 +2346,7 @@ func (b *Builder) extractCallEdges(ctx context.Context, state *buildState, sym *
 				if targetID == sym.ID {
 					continue
 				}
-				err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, call.Location)
+				err := stateAddEdge(state, sym.ID, targetID, EdgeTypeReferences, call.Location, nameMatchProvenance(PassCallbackRef, targets))
 				if err != nil && !strings.Contains(err.Error(), "already exists") {
 					stateAddEdgeError(state, EdgeError{
 						FromID:   sym.ID,
@@ -1835,8 +2391,44 @@ func (b *Builder) extractCallEdges(ctx context.Context, state *buildState, sym *
 //
 // Thread Safety: This function is safe for concurrent use.
 func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller *ast.Symbol) string {
+	targetID, _ := b.resolveCallTargetWithProvenance(state, call, caller)
+	return targetID
+}
+
+// resolveCallTargetWithProvenance is resolveCallTarget that also reports
+// which strategy resolved the call.
+//
+// Description:
+//
+//	Each strategy maps to an EdgePass. Confidence is high when the
+//	strategy's candidate was unique or named by the source, medium when a
+//	heuristic (imports, receiver name, inheritance) picked it among
+//	candidates, and low when the first of several was taken.
+//
+// Outputs:
+//   - string: The resolved symbol ID, or empty string if unresolved.
+//   - EdgeProvenance: The resolving strategy. Zero when unresolved.
+//
+// Thread Safety: This function is safe for concurrent use.
+func (b *Builder) resolveCallTargetWithProvenance(state *buildState, call ast.CallSite, caller *ast.Symbol) (string, EdgeProvenance) {
 	target := call.Target
 
+	// Strategy 0: Go method call on a variable whose type the parser
+	// declared or inferred (s := NewServer(); s.Start() → Server.Start).
+	if call.IsMethod && call.Receiver != "" && caller.Language == "go" {
+		if resolved, prov := b.resolveViaInferredType(state, call, caller); resolved != "" {
+			return resolved, prov
+		}
+	}
+
+	// Strategy 0b: Hop of a method call chain (client.Users().Get()),
+	// resolved through the return type of the hop before it.
+	if len(call.Chain) > 0 {
+		if resolved, prov := b.resolveViaCallChain(state, call, caller); resolved != "" {
+			return resolved, prov
+		}
+	}
+
 	// Strategy 1: Direct name match in same package
 	// For simple calls like "DoWork()"
 	if !strings.Contains(target, ".") && !call.IsMethod {
@@ -1865,25 +2457,32 @@ func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller
 			// R3-P2b-ImportMap: Try import-aware resolution first to disambiguate
 			// among cross-file candidates.
 			if len(candidates) > 0 {
-				if resolved := b.resolveViaImportMap(state, target, caller.FilePath, candidates); resolved != "" {
-					return resolved
+				if resolved, ambiguous := b.resolveViaImportMap(state, target, caller.FilePath, candidates); resolved != "" {
+					if ambiguous {
+						return resolved, ambiguousImportProvenance(PassImportAwareResolution)
+					}
+					return resolved, EdgeProvenance{Pass: PassImportAwareResolution, Confidence: ConfidenceMedium}
 				}
+				prov := nameMatchProvenance(PassCallName, candidates)
 				// Prefer functions/methods, not types
 				for _, id := range candidates {
 					if sym, ok := state.symbolsByID[id]; ok {
 						if sym.Kind == ast.SymbolKindFunction || sym.Kind == ast.SymbolKindMethod {
-							return id
+							return id, prov
 						}
 					}
 				}
 				// Fall back to first match
-				return candidates[0]
+				return candidates[0], prov
 			}
 
 			// R3-P2b-ImportMap: Even with no candidates, try import map
 			// (for aliased imports where the local name doesn't match any symbol name).
-			if resolved := b.resolveViaImportMap(state, target, caller.FilePath, nil); resolved != "" {
-				return resolved
+			if resolved, ambiguous := b.resolveViaImportMap(state, target, caller.FilePath, nil); resolved != "" {
+				if ambiguous {
+					return resolved, ambiguousImportProvenance(PassImportAwareResolution)
+				}
+				return resolved, EdgeProvenance{Pass: PassImportAwareResolution, Confidence: ConfidenceMedium, Detail: "alias"}
 			}
 		}
 	}
@@ -1896,7 +2495,7 @@ func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller
 			funcName := parts[1]
 			candidates := b.resolveSymbolByName(state, funcName, caller.FilePath)
 			if len(candidates) > 0 {
-				return candidates[0]
+				return candidates[0], nameMatchProvenance(PassCallQualified, candidates)
 			}
 		}
 	}
@@ -1913,14 +2512,22 @@ func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller
 		if call.Receiver == "super" {
 			allCandidates := b.resolveAllSymbolsByName(state, target)
 			if resolved := b.resolveSuperCall(state, allCandidates, caller); resolved != "" {
-				return resolved
+				return resolved, EdgeProvenance{Pass: PassCallSuper, Confidence: ConfidenceMedium}
 			}
 		}
 
 		// Sub-strategy 3a: this/self receiver → resolve to caller's owning class
 		if call.Receiver == "this" || call.Receiver == "self" {
 			if resolved := b.resolveThisSelfCall(state, candidates, caller); resolved != "" {
-				return resolved
+				return resolved, EdgeProvenance{Pass: PassCallThisSelf, Confidence: ConfidenceHigh}
+			}
+		}
+
+		// Sub-strategy 3a1: Python attribute chain through an imported module
+		// (import pandas as pd; pd.merge(), pandas.core.frame.concat()).
+		if caller.Language == "python" && call.Receiver != "self" && call.Receiver != "super" {
+			if resolved := b.resolveViaModuleAlias(state, call, caller); resolved != "" {
+				return resolved, EdgeProvenance{Pass: PassCallModuleAlias, Confidence: ConfidenceMedium}
 			}
 		}
 
@@ -1932,7 +2539,10 @@ func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller
 		// variable name, not a package alias).
 		if caller.Language == "go" && call.Receiver != "this" && call.Receiver != "self" && call.Receiver != "super" {
 			if targetID, matched := b.resolveViaPackageImport(state, call, caller); matched {
-				return targetID
+				if targetID == "" {
+					return "", EdgeProvenance{}
+				}
+				return targetID, EdgeProvenance{Pass: PassCallPackageImport, Confidence: ConfidenceHigh}
 			}
 		}
 
@@ -1940,7 +2550,7 @@ func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller
 		// Handles: txn.Get() → Txn.Get, ctx.Done() → Context.Done
 		if call.Receiver != "this" && call.Receiver != "self" && call.Receiver != "super" {
 			if resolved := b.resolveReceiverCaseInsensitive(state, candidates, call.Receiver); resolved != "" {
-				return resolved
+				return resolved, EdgeProvenance{Pass: PassReceiverMatch, Confidence: ConfidenceMedium, Detail: "3b"}
 			}
 
 			// Sub-strategy 3b2: Receiver matching failed on same-file candidates.
@@ -1951,7 +2561,7 @@ func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller
 			allCandidates := b.resolveAllSymbolsByName(state, target)
 			if len(allCandidates) > len(candidates) {
 				if resolved := b.resolveReceiverCaseInsensitive(state, allCandidates, call.Receiver); resolved != "" {
-					return resolved
+					return resolved, EdgeProvenance{Pass: PassReceiverMatch, Confidence: ConfidenceMedium, Detail: "3b2"}
 				}
 			}
 		}
@@ -1995,13 +2605,13 @@ func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller
 					}
 				}
 				if len(methodCandidates) == 1 {
-					return methodCandidates[0]
+					return methodCandidates[0], EdgeProvenance{Pass: PassCallMethodFallback, Confidence: ConfidenceMedium}
 				}
 			} else {
 				for _, id := range candidates {
 					if sym, ok := state.symbolsByID[id]; ok {
 						if sym.Kind == ast.SymbolKindMethod || sym.Kind == ast.SymbolKindProperty {
-							return id
+							return id, EdgeProvenance{Pass: PassCallMethodFallback, Confidence: ConfidenceLow}
 						}
 					}
 				}
@@ -2024,13 +2634,13 @@ func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller
 					}
 				}
 				if len(varCandidates) == 1 {
-					return varCandidates[0]
+					return varCandidates[0], EdgeProvenance{Pass: PassCallVariableFallback, Confidence: ConfidenceMedium}
 				}
 			} else {
 				for _, id := range candidates {
 					if sym, ok := state.symbolsByID[id]; ok {
 						if sym.Kind == ast.SymbolKindVariable {
-							return id
+							return id, EdgeProvenance{Pass: PassCallVariableFallback, Confidence: ConfidenceLow}
 						}
 					}
 				}
@@ -2039,37 +2649,98 @@ func (b *Builder) resolveCallTarget(state *buildState, call ast.CallSite, caller
 	}
 
 	// Unresolved - caller will create placeholder
-	return ""
+	return "", EdgeProvenance{}
 }
 
-// resolveThisSelfCall resolves method calls on this/self to the caller's owning class.
+// resolveViaInferredType resolves a Go method call through the type of its
+// receiver variable.
 //
 // Description:
 //
-//	When a method calls this.foo() or self.foo(), the target should resolve to
-//	a method on the same class (or a parent class via inheritance). This function
-//	finds the caller's owning class and matches the target method.
+//	Looks the call's receiver up in the caller's LocalTypes and matches
+//	methods named call.Target whose receiver is that type. A qualified
+//	type (http.Request) only matches methods in that package; an
+//	unqualified one prefers methods in the caller's directory. Types
+//	without a matching method in the project (interfaces, external
+//	types) leave the call to the other strategies.
 //
 // Inputs:
 //
-//	state - Build state with symbol indexes and parent maps.
-//	candidates - Symbol IDs that match the target method name.
-//	caller - The calling function/method symbol.
+//	state - Build state with symbol indexes.
+//	call - The method call site.
+//	caller - The calling Go function or method.
 //
 // Outputs:
 //
-//	string - Resolved symbol ID, or empty if no match found.
-func (b *Builder) resolveThisSelfCall(state *buildState, candidates []string, caller *ast.Symbol) string {
-	// Find the caller's owning class
-	ownerClassName := b.findOwnerClassName(state, caller)
-	if ownerClassName == "" {
-		return ""
+//	string - Resolved symbol ID, or empty if the type is unknown or has
+//	  no such method.
+//	EdgeProvenance - PassCallInferredType, high confidence when one method
+//	  matched and low when the first of several was taken.
+func (b *Builder) resolveViaInferredType(state *buildState, call ast.CallSite, caller *ast.Symbol) (string, EdgeProvenance) {
+	if caller.Metadata == nil || len(caller.Metadata.LocalTypes) == 0 {
+		return "", EdgeProvenance{}
+	}
+	typeName, ok := caller.Metadata.LocalTypes[call.Receiver]
+	if !ok || typeName == "" {
+		return "", EdgeProvenance{}
+	}
+	pkg := ""
+	if dot := strings.LastIndex(typeName, "."); dot >= 0 {
+		pkg, typeName = typeName[:dot], typeName[dot+1:]
 	}
 
-	// Try to find a candidate method that belongs to this class or a parent class
-	classChain := b.buildInheritanceChain(state, ownerClassName)
-
-	for _, className := range classChain {
+	callerDir := filepath.Dir(caller.FilePath)
+	var matches, local []string
+	for _, id := range b.resolveAllSymbolsByName(state, call.Target) {
+		sym, ok := state.symbolsByID[id]
+		if !ok || sym.Kind != ast.SymbolKindMethod || sym.Receiver != typeName || sym.Language != "go" {
+			continue
+		}
+		if pkg != "" && sym.Package != pkg {
+			continue
+		}
+		matches = append(matches, id)
+		if filepath.Dir(sym.FilePath) == callerDir {
+			local = append(local, id)
+		}
+	}
+	if pkg == "" && len(local) > 0 {
+		matches = local
+	}
+	if len(matches) == 0 {
+		return "", EdgeProvenance{}
+	}
+	return matches[0], nameMatchProvenance(PassCallInferredType, matches)
+}
+
+// resolveThisSelfCall resolves method calls on this/self to the caller's owning class.
+//
+// Description:
+//
+//	When a method calls this.foo() or self.foo(), the target should resolve to
+//	a method on the same class (or a parent class via inheritance). This function
+//	finds the caller's owning class and matches the target method.
+//
+// Inputs:
+//
+//	state - Build state with symbol indexes and parent maps.
+//	candidates - Symbol IDs that match the target method name.
+//	caller - The calling function/method symbol.
+//
+// Outputs:
+//
+//	string - Resolved symbol ID, or empty if no match found.
+func (b *Builder) resolveThisSelfCall(state *buildState, candidates []string, caller *ast.Symbol) string {
+	// Find the caller's owning class
+	ownerClassName := b.findOwnerClassName(state, caller)
+	if ownerClassName == "" {
+		return ""
+	}
+
+	// Try to find a candidate method that belongs to this class or a parent class
+	classChain := b.buildInheritanceChain(state, ownerClassName)
+
+	for _, className := range classChain {
 		for _, id := range candidates {
 			sym, ok := state.symbolsByID[id]
 			if !ok {
@@ -2321,6 +2992,18 @@ func (b *Builder) resolveSymbolByName(state *buildState, name string, currentFil
 	return other
 }
 
+// filterFunctionSymbols keeps the IDs of function and method symbols.
+func (b *Builder) filterFunctionSymbols(state *buildState, ids []string) []string {
+	kept := ids[:0:0]
+	for _, id := range ids {
+		if sym := state.symbolsByID[id]; sym != nil &&
+			(sym.Kind == ast.SymbolKindFunction || sym.Kind == ast.SymbolKindMethod) {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
 // resolveAllSymbolsByName returns ALL symbol IDs matching a name across all files.
 //
 // Description:
@@ -2456,7 +3139,7 @@ func (b *Builder) resolveNamedImportEdges(ctx context.Context, state *buildState
 		if sourceID == "" {
 			skippedNoSource += len(fileMap)
 			slog.Debug("GR-62: no package symbol for file, skipping named import resolution",
-				slog.String("file", r.FilePath),
+				telemetry.SensitiveLog("file", r.FilePath),
 			)
 			continue
 		}
@@ -2473,21 +3156,23 @@ func (b *Builder) resolveNamedImportEdges(ctx context.Context, state *buildState
 				continue
 			}
 
-			// Filter to symbols whose file path matches the import module path.
-			// matchesImportPath converts "flask.globals" → "flask/globals" and
-			// handles __init__.py packages, so relative and absolute imports both work.
-			matched := false
-			for _, sym := range candidates {
-				if !matchesImportPath(sym.FilePath, entry.ModulePath) {
-					continue
-				}
-
-				// A matching symbol was found in the index. Mark matched=true now
-				// so that skippedNoTarget reflects "no symbol in index" not "AddEdge failed".
-				// Edge failures are separately recorded in EdgeErrors.
-				matched = true
+			// Keep the symbols of the file the import names: an exact module
+			// path match under the project or a declared source root, else a
+			// path suffix match (matchesImportPath converts "flask.globals" →
+			// "flask/globals" and handles __init__.py packages).
+			targets, tied := b.pythonImportTargets(r.FilePath, entry.ModulePath, candidates)
+			prov := EdgeProvenance{Pass: PassNamedImport, Confidence: ConfidenceHigh}
+			if len(tied) > 0 {
+				prov = ambiguousImportProvenance(PassNamedImport)
+				recordAmbiguousImport(state, r.FilePath, entry, tied, targets[0].FilePath)
+			}
 
-				err := stateAddEdge(state, sourceID, sym.ID, EdgeTypeReferences, entry.Location)
+			// A matching symbol was found in the index. Set matched now so
+			// that skippedNoTarget reflects "no symbol in index" not "AddEdge
+			// failed". Edge failures are separately recorded in EdgeErrors.
+			matched := len(targets) > 0
+			for _, sym := range targets {
+				err := stateAddEdge(state, sourceID, sym.ID, EdgeTypeReferences, entry.Location, prov)
 				if err != nil {
 					if strings.Contains(err.Error(), "already exists") {
 						// Duplicate edges are benign — same symbol imported via
@@ -2509,13 +3194,37 @@ func (b *Builder) resolveNamedImportEdges(ctx context.Context, state *buildState
 				resolved++
 
 				slog.Debug("GR-62: named import edge created",
-					slog.String("from", sourceID),
-					slog.String("to", sym.ID),
-					slog.String("module", entry.ModulePath),
-					slog.String("name", entry.OriginalName),
+					telemetry.SensitiveLog("from", sourceID),
+					telemetry.SensitiveLog("to", sym.ID),
+					telemetry.SensitiveLog("module", entry.ModulePath),
+					telemetry.SensitiveLog("name", entry.OriginalName),
 				)
 			}
 
+			// Not defined in the module itself: follow a package re-export
+			// (from pandas import DataFrame → pandas/core/frame.py).
+			if !matched {
+				for _, targetID := range b.resolveViaReExport(state, entry.ModulePath, entry.OriginalName) {
+					matched = true
+					err := stateAddEdge(state, sourceID, targetID, EdgeTypeReferences, entry.Location, EdgeProvenance{Pass: PassNamedImport, Confidence: ConfidenceHigh, Detail: "re-export"})
+					if err != nil {
+						if !strings.Contains(err.Error(), "already exists") {
+							stateAddEdgeError(state, EdgeError{
+								FromID:   sourceID,
+								ToID:     targetID,
+								EdgeType: EdgeTypeReferences,
+								Err:      fmt.Errorf("GR-62 re-exported import edge: %w", err),
+							})
+						}
+						continue
+					}
+					stateStats(state).EdgesCreated++
+					stateStats(state).NamedImportEdgesResolved++
+					stateStats(state).ReExportsResolved++
+					resolved++
+				}
+			}
+
 			if !matched {
 				skippedNoTarget++
 			}
@@ -2698,7 +3407,7 @@ func (b *Builder) resolveCommonJSAliasImportEdges(ctx context.Context, state *bu
 					continue
 				}
 
-				err := stateAddEdge(state, sourceID, sym.ID, EdgeTypeReferences, imp.Location)
+				err := stateAddEdge(state, sourceID, sym.ID, EdgeTypeReferences, imp.Location, EdgeProvenance{Pass: PassCommonJSAlias, Confidence: ConfidenceHigh})
 				if err != nil {
 					if strings.Contains(err.Error(), "already exists") {
 						found = true
@@ -2719,11 +3428,11 @@ func (b *Builder) resolveCommonJSAliasImportEdges(ctx context.Context, state *bu
 				found = true
 
 				slog.Debug("IT-06d: CommonJS alias import edge created",
-					slog.String("from", sourceID),
-					slog.String("to", sym.ID),
-					slog.String("alias", imp.Alias),
-					slog.String("module", imp.Path),
-					slog.String("semantic_name", semanticName),
+					telemetry.SensitiveLog("from", sourceID),
+					telemetry.SensitiveLog("to", sym.ID),
+					telemetry.SensitiveLog("alias", imp.Alias),
+					telemetry.SensitiveLog("module", imp.Path),
+					telemetry.SensitiveLog("semantic_name", semanticName),
 				)
 			}
 			if !found {
@@ -2828,7 +3537,7 @@ func (b *Builder) resolveDynamicImportEdges(ctx context.Context, state *buildSta
 					continue
 				}
 
-				err := stateAddEdge(state, sourceID, sym.ID, EdgeTypeReferences, imp.Location)
+				err := stateAddEdge(state, sourceID, sym.ID, EdgeTypeReferences, imp.Location, EdgeProvenance{Pass: PassDynamicImport, Confidence: ConfidenceHigh})
 				if err != nil {
 					if strings.Contains(err.Error(), "already exists") {
 						found = true
@@ -2849,10 +3558,10 @@ func (b *Builder) resolveDynamicImportEdges(ctx context.Context, state *buildSta
 				found = true
 
 				slog.Debug("IT-06e Bug 4: dynamic import edge created",
-					slog.String("from", sourceID),
-					slog.String("to", sym.ID),
-					slog.String("module", imp.Path),
-					slog.String("semantic_name", semanticName),
+					telemetry.SensitiveLog("from", sourceID),
+					telemetry.SensitiveLog("to", sym.ID),
+					telemetry.SensitiveLog("module", imp.Path),
+					telemetry.SensitiveLog("semantic_name", semanticName),
 				)
 			}
 			if !found {
@@ -2952,7 +3661,11 @@ func (b *Builder) resolveDecoratorArgEdges(ctx context.Context, state *buildStat
 					// Try to resolve via the file's import name map first.
 					// This handles: import { UserService } from './user.service'
 					// then @Module({ providers: [UserService] })
-					resolvedID := b.resolveViaImportMap(state, argName, r.FilePath, nil)
+					resolvedID, ambiguous := b.resolveViaImportMap(state, argName, r.FilePath, nil)
+					prov := EdgeProvenance{Pass: PassDecoratorArg, Confidence: ConfidenceMedium, Detail: "import-map"}
+					if ambiguous {
+						prov = ambiguousImportProvenance(PassDecoratorArg)
+					}
 
 					// Fall back to a name-only lookup if the import map has no entry.
 					// This handles same-file references and re-exported symbols.
@@ -2964,6 +3677,10 @@ func (b *Builder) resolveDecoratorArgEdges(ctx context.Context, state *buildStat
 								break
 							}
 						}
+						prov = EdgeProvenance{Pass: PassDecoratorArg, Confidence: ConfidenceLow, Detail: "name"}
+						if len(candidates) == 1 {
+							prov.Confidence = ConfidenceHigh
+						}
 					}
 
 					if resolvedID == "" {
@@ -2976,7 +3693,7 @@ func (b *Builder) resolveDecoratorArgEdges(ctx context.Context, state *buildStat
 						StartLine: sym.StartLine,
 						EndLine:   sym.EndLine,
 					}
-					err := stateAddEdge(state, sourceID, resolvedID, EdgeTypeReferences, loc)
+					err := stateAddEdge(state, sourceID, resolvedID, EdgeTypeReferences, loc, prov)
 					if err != nil {
 						if strings.Contains(err.Error(), "already exists") {
 							// Edge already exists (created by another pass). Count it in stats.
@@ -2998,9 +3715,9 @@ func (b *Builder) resolveDecoratorArgEdges(ctx context.Context, state *buildStat
 					resolved++
 
 					slog.Debug("IT-06e Bug 5: decorator arg edge created",
-						slog.String("from", sourceID),
-						slog.String("to", resolvedID),
-						slog.String("arg", argName),
+						telemetry.SensitiveLog("from", sourceID),
+						telemetry.SensitiveLog("to", resolvedID),
+						telemetry.SensitiveLog("arg", argName),
 					)
 				}
 			}
@@ -3042,6 +3759,9 @@ func (b *Builder) buildImportNameMap(state *buildState) {
 				// IT-06d Bug E/D: CommonJS whole-module imports: var X = require('./module')
 				// These have Alias set but Names empty. Include them so resolveViaImportMap
 				// can redirect bare calls (new Route()) to the actual cross-file class.
+				if !imp.IsWildcard && strings.HasSuffix(filePath, ".py") {
+					recordPythonModuleAlias(state, filePath, imp)
+				}
 				if imp.IsCommonJS && imp.Alias != "" {
 					if state.importNameMap[filePath] == nil {
 						state.importNameMap[filePath] = make(map[string]importEntry)
@@ -3063,6 +3783,7 @@ func (b *Builder) buildImportNameMap(state *buildState) {
 				state.importNameMap[filePath] = make(map[string]importEntry)
 			}
 
+			isPackageInit := strings.HasSuffix(filePath, "/__init__.py") || filePath == "__init__.py"
 			for _, name := range imp.Names {
 				localName, originalName := parseAliasedName(name)
 				state.importNameMap[filePath][localName] = importEntry{
@@ -3071,6 +3792,10 @@ func (b *Builder) buildImportNameMap(state *buildState) {
 					Location:     imp.Location,
 				}
 				entries++
+
+				if isPackageInit {
+					recordPythonReExport(state, filePath, localName, originalName, imp)
+				}
 			}
 		}
 	}
@@ -3101,36 +3826,81 @@ func (b *Builder) buildImportNameMap(state *buildState) {
 // Outputs:
 //
 //	string - Resolved symbol ID, or empty string if no import match found.
+//	bool - True if the Python module path matched several files equally
+//	  well and the ID is one of them (see pythonImportTargets).
 //
 // Thread Safety: This function is safe for concurrent use.
-func (b *Builder) resolveViaImportMap(state *buildState, target string, callerFile string, candidates []string) string {
+func (b *Builder) resolveViaImportMap(state *buildState, target string, callerFile string, candidates []string) (string, bool) {
 	fileMap := state.importNameMap[callerFile]
 	if fileMap == nil {
-		return ""
+		return "", false
 	}
 
 	entry, ok := fileMap[target]
 	if !ok {
-		return ""
+		return "", false
 	}
 
-	// Look through ALL symbols named originalName (not just candidates,
-	// which may be filtered to same-file).
-	allCandidates := b.resolveAllSymbolsByName(state, entry.OriginalName)
-	for _, id := range allCandidates {
-		sym := state.symbolsByID[id]
-		if sym != nil && matchesImportPath(sym.FilePath, entry.ModulePath) {
+	// Python: pick the file the module path names, by exact dotted path
+	// under the project or a declared source root before suffix matching.
+	if strings.HasSuffix(callerFile, ".py") {
+		targets, tied := b.pythonImportTargets(callerFile, entry.ModulePath, state.symbolsByName[entry.OriginalName])
+		if len(targets) > 0 {
 			slog.Debug("R3-P2b: import-aware resolution succeeded",
-				slog.String("target", target),
-				slog.String("import_path", entry.ModulePath),
-				slog.String("original_name", entry.OriginalName),
-				slog.String("resolved_id", id),
+				telemetry.SensitiveLog("target", target),
+				telemetry.SensitiveLog("import_path", entry.ModulePath),
+				telemetry.SensitiveLog("original_name", entry.OriginalName),
+				telemetry.SensitiveLog("resolved_id", targets[0].ID),
+				slog.Int("tied_files", len(tied)),
 			)
-			return id
+			return targets[0].ID, len(tied) > 0
+		}
+	} else {
+		// Look through ALL symbols named originalName (not just candidates,
+		// which may be filtered to same-file).
+		allCandidates := b.resolveAllSymbolsByName(state, entry.OriginalName)
+		for _, id := range allCandidates {
+			sym := state.symbolsByID[id]
+			if sym != nil && matchesImportPath(sym.FilePath, entry.ModulePath) {
+				slog.Debug("R3-P2b: import-aware resolution succeeded",
+					telemetry.SensitiveLog("target", target),
+					telemetry.SensitiveLog("import_path", entry.ModulePath),
+					telemetry.SensitiveLog("original_name", entry.OriginalName),
+					telemetry.SensitiveLog("resolved_id", id),
+				)
+				return id, false
+			}
 		}
 	}
 
-	return ""
+	// No direct definition: the module may be a package whose __init__.py
+	// re-exports the name from a submodule.
+	if ids := b.resolveViaReExport(state, entry.ModulePath, entry.OriginalName); len(ids) > 0 {
+		stateStats(state).ReExportsResolved++
+		return ids[0], false
+	}
+
+	return "", false
+}
+
+// recordAmbiguousImport adds an AmbiguousImport to the result, enforcing
+// maxErrorSliceLen. Every ambiguous import is counted in
+// BuildStats.AmbiguousImports.
+//
+// Thread Safety: Must be called single-threaded (named import pass).
+func recordAmbiguousImport(state *buildState, filePath string, entry importEntry, candidates []string, chosen string) {
+	stateStats(state).AmbiguousImports++
+	if len(state.result.AmbiguousImports) >= maxErrorSliceLen {
+		return
+	}
+	state.result.AmbiguousImports = append(state.result.AmbiguousImports, AmbiguousImport{
+		FilePath:   filePath,
+		Line:       entry.Location.StartLine,
+		ModulePath: entry.ModulePath,
+		Name:       entry.OriginalName,
+		Candidates: candidates,
+		Chosen:     chosen,
+	})
 }
 
 // matchesImportPath checks if a symbol's file path corresponds to an import module path.
@@ -3181,6 +3951,227 @@ func matchesImportPath(filePath string, importPath string) bool {
 	return strings.HasSuffix(normalized, "/"+pathFragment)
 }
 
+// recordPythonModuleAlias records the local name bound by a bare Python import.
+//
+// Description:
+//
+//	"import pandas as pd" binds pd → "pandas". "import pandas.core.frame"
+//	binds only the top-level package, pandas → "pandas"; the rest of the
+//	dotted path is spelled out at each use site (pandas.core.frame.f()).
+//
+// Thread Safety: Must be called from buildImportNameMap (single-threaded phase).
+func recordPythonModuleAlias(state *buildState, filePath string, imp ast.Import) {
+	if imp.Path == "" || strings.HasPrefix(imp.Path, ".") {
+		return
+	}
+
+	localName, modulePath := imp.Alias, imp.Path
+	if localName == "" {
+		localName, _, _ = strings.Cut(imp.Path, ".")
+		modulePath = localName
+	}
+
+	if state.pythonModuleAliases == nil {
+		state.pythonModuleAliases = make(map[string]map[string]string)
+	}
+	if state.pythonModuleAliases[filePath] == nil {
+		state.pythonModuleAliases[filePath] = make(map[string]string)
+	}
+	state.pythonModuleAliases[filePath][localName] = modulePath
+}
+
+// recordPythonReExport records a name that a package __init__.py imports and
+// therefore exposes as a package attribute.
+//
+// Thread Safety: Must be called from buildImportNameMap (single-threaded phase).
+func recordPythonReExport(state *buildState, initFile, localName, originalName string, imp ast.Import) {
+	modulePath := absolutePythonModulePath(initFile, imp.Path)
+	if modulePath == "" {
+		return
+	}
+
+	if state.pythonReExports == nil {
+		state.pythonReExports = make(map[string]map[string]importEntry)
+	}
+	if state.pythonReExports[initFile] == nil {
+		state.pythonReExports[initFile] = make(map[string]importEntry)
+	}
+	state.pythonReExports[initFile][localName] = importEntry{
+		ModulePath:   modulePath,
+		OriginalName: originalName,
+		Location:     imp.Location,
+	}
+}
+
+// absolutePythonModulePath resolves a (possibly relative) Python module path
+// against the package that contains fromFile.
+//
+// Description:
+//
+//	Relative paths are anchored at the importing file's directory: one leading
+//	dot is the current package, each further dot goes up one level. The result
+//	is a dotted path rooted at the project root, which matchesImportPath can
+//	compare exactly against symbol file paths. Absolute paths are returned as is.
+//
+//	  ("pandas/__init__.py", ".core.frame") → "pandas.core.frame"
+//	  ("pandas/io/__init__.py", "..util")   → "pandas.util"
+//	  ("pandas/__init__.py", "numpy")       → "numpy"
+//
+// Outputs:
+//
+//	string - Absolute dotted module path, or "" if the path climbs above the
+//	project root or names no module ("from . import x").
+//
+// Thread Safety: Safe for concurrent use (pure function).
+func absolutePythonModulePath(fromFile, modulePath string) string {
+	if !strings.HasPrefix(modulePath, ".") {
+		return modulePath
+	}
+
+	rest := strings.TrimLeft(modulePath, ".")
+	if rest == "" {
+		return ""
+	}
+	levels := len(modulePath) - len(rest)
+
+	dir := posixpath.Dir(fromFile)
+	for i := 1; i < levels; i++ {
+		if dir == "." || dir == "/" {
+			return ""
+		}
+		dir = posixpath.Dir(dir)
+	}
+
+	if dir == "." || dir == "/" {
+		return rest
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(dir, "/"), "/", ".") + "." + rest
+}
+
+// resolveViaReExport follows one level of Python package re-export.
+//
+// Description:
+//
+//	When modulePath names a package whose __init__.py does
+//	"from .x import Name", returns the IDs of Name in the defining module
+//	(x). Only one level is followed: a re-export of a re-export is not chased.
+//
+// Inputs:
+//
+//	state - Build state with pythonReExports and the full symbol index.
+//	modulePath - The imported module path (e.g., "pandas" or ".").
+//	name - The name looked up in that module.
+//
+// Outputs:
+//
+//	[]string - Matching symbol IDs, sorted for deterministic resolution.
+//	  Nil if the module is not a re-exporting package.
+//
+// Thread Safety: Safe for concurrent use (read-only access to state maps).
+func (b *Builder) resolveViaReExport(state *buildState, modulePath, name string) []string {
+	if len(state.pythonReExports) == 0 || name == "" {
+		return nil
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for initFile, exports := range state.pythonReExports {
+		entry, ok := exports[name]
+		if !ok || !matchesImportPath(initFile, modulePath) {
+			continue
+		}
+		for _, id := range b.resolveAllSymbolsByName(state, entry.OriginalName) {
+			sym := state.symbolsByID[id]
+			if sym == nil || seen[id] || !matchesImportPath(sym.FilePath, entry.ModulePath) {
+				continue
+			}
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Strings(ids)
+	return ids
+}
+
+// resolveViaModuleAlias resolves a Python attribute-chain call through an
+// imported module.
+//
+// Description:
+//
+//	For "import pandas as pd" followed by pd.core.frame.concat(), the parser
+//	reports Receiver "pd.core.frame" and Target "concat". The first receiver
+//	segment is looked up in the file's module aliases and the remainder is
+//	appended, giving module "pandas.core.frame". The target is then matched
+//	against module-level symbols defined there, falling back to one level of
+//	__init__.py re-export (pd.merge() → pandas/core/reshape/merge.py).
+//
+// Inputs:
+//
+//	state - Build state with pythonModuleAliases and the full symbol index.
+//	call - The call site. Receiver must be a plain dotted name.
+//	caller - The calling symbol (provides the file for alias lookup).
+//
+// Outputs:
+//
+//	string - Resolved symbol ID, or "" if the receiver is not an imported module.
+//
+// Thread Safety: Safe for concurrent use (read-only access to state maps).
+func (b *Builder) resolveViaModuleAlias(state *buildState, call ast.CallSite, caller *ast.Symbol) string {
+	aliases := state.pythonModuleAliases[caller.FilePath]
+	if len(aliases) == 0 || !isDottedIdentifier(call.Receiver) {
+		return ""
+	}
+
+	head, rest, _ := strings.Cut(call.Receiver, ".")
+	modulePath, ok := aliases[head]
+	if !ok {
+		return ""
+	}
+	if rest != "" {
+		modulePath += "." + rest
+	}
+
+	for _, id := range b.resolveAllSymbolsByName(state, call.Target) {
+		sym := state.symbolsByID[id]
+		if sym == nil || !matchesImportPath(sym.FilePath, modulePath) {
+			continue
+		}
+		// Module attributes are top-level definitions, not class members.
+		if _, isMember := state.symbolParent[id]; isMember {
+			continue
+		}
+		return id
+	}
+
+	if ids := b.resolveViaReExport(state, modulePath, call.Target); len(ids) > 0 {
+		stateStats(state).ReExportsResolved++
+		return ids[0]
+	}
+
+	return ""
+}
+
+// isDottedIdentifier reports whether s is a plain dotted name like "pd.core.frame"
+// (no calls, subscripts or whitespace).
+func isDottedIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "" {
+			return false
+		}
+		for i, r := range part {
+			if r == '_' || unicode.IsLetter(r) || (i > 0 && unicode.IsDigit(r)) {
+				continue
+			}
+			return false
+		}
+	}
+	return true
+}
+
 // matchesGoImportPath checks if a symbol's file directory matches a Go import
 // path by progressive suffix matching on path segments.
 //
@@ -3293,6 +4284,7 @@ func (b *Builder) resolveViaPackageImport(
 			}
 		}
 		allCandidates := b.resolveAllSymbolsByName(state, call.Target)
+		dir, inProject := goImportDir(b.options.GoModules, imp.Path)
 
 		for _, id := range allCandidates {
 			sym := state.symbolsByID[id]
@@ -3305,7 +4297,15 @@ func (b *Builder) resolveViaPackageImport(
 				continue
 			}
 
-			// Disambiguate: verify directory suffix matches import path
+			// Disambiguate: a project module gives the package's
+			// directory; otherwise verify a directory suffix matches the
+			// import path.
+			if inProject {
+				if goFileDir(sym.FilePath) == dir {
+					return id, true
+				}
+				continue
+			}
 			if matchesGoImportPath(sym.FilePath, imp.Path) {
 				return id, true
 			}
@@ -3378,16 +4378,35 @@ var builtinsByLanguage = map[string]map[string]bool{
 		"type": true, "complex": true, "any": true,
 		"Optional": true, "Union": true, "Callable": true, "Iterator": true,
 		"Generator": true, "Sequence": true, "Mapping": true, "FrozenSet": true,
+		"Self": true,
 	},
 	"typescript": {
 		"number": true, "string": true, "boolean": true, "undefined": true, "null": true,
 		"void": true, "never": true, "unknown": true, "any": true, "symbol": true,
-		"bigint": true, "object": true,
+		"bigint": true, "object": true, "this": true,
 	},
 	"javascript": {
 		"number": true, "string": true, "boolean": true, "undefined": true, "null": true,
 		"void": true, "never": true, "unknown": true, "any": true, "symbol": true,
-		"bigint": true, "object": true,
+		"bigint": true, "object": true, "this": true,
+	},
+	"kotlin": {
+		"String": true, "Int": true, "Long": true, "Short": true, "Byte": true,
+		"Double": true, "Float": true, "Boolean": true, "Char": true, "Unit": true,
+		"Any": true, "Nothing": true, "Array": true, "List": true, "MutableList": true,
+		"Map": true, "MutableMap": true, "Set": true, "MutableSet": true,
+	},
+	"swift": {
+		"String": true, "Int": true, "Int8": true, "Int16": true, "Int32": true, "Int64": true,
+		"UInt": true, "UInt8": true, "UInt16": true, "UInt32": true, "UInt64": true,
+		"Double": true, "Float": true, "Bool": true, "Character": true, "Void": true,
+		"Any": true, "AnyObject": true, "Never": true, "Self": true,
+		"Array": true, "Dictionary": true, "Set": true, "Optional": true,
+	},
+	"php": {
+		"int": true, "float": true, "string": true, "bool": true, "array": true,
+		"void": true, "mixed": true, "null": true, "callable": true, "iterable": true,
+		"object": true, "self": true, "static": true, "never": true, "false": true, "true": true,
 	},
 }
 
@@ -3544,44 +4563,54 @@ func inferPackageFromCall(call ast.CallSite, fileImports []ast.Import) string {
 	return ""
 }
 
-// getOrCreatePlaceholder returns an existing placeholder or creates a new one.
+// getOrCreatePlaceholder returns an existing placeholder or creates a new one,
+// following BuilderOptions.PlaceholderPolicy. Returns "" under PlaceholderOmit.
 func (b *Builder) getOrCreatePlaceholder(state *buildState, pkg, name string) string {
-	var id string
-	if pkg != "" {
-		id = fmt.Sprintf("external:%s:%s", pkg, name)
-	} else {
-		id = fmt.Sprintf("external::%s", name)
+	id, member := placeholderID(b.options.PlaceholderPolicy, pkg, name)
+	if id == "" {
+		return ""
 	}
 
 	state.mu.Lock()
 	defer state.mu.Unlock()
 
-	if node, exists := state.placeholders[id]; exists {
-		return node.ID
-	}
+	node, exists := state.placeholders[id]
+	if !exists {
+		// Create placeholder symbol. A collapsed placeholder is named
+		// after its package.
+		placeholder := &ast.Symbol{
+			ID:       id,
+			Name:     name,
+			Kind:     ast.SymbolKindExternal,
+			Package:  pkg,
+			Language: "external",
+		}
+		if b.options.PlaceholderPolicy == PlaceholderCollapsed && pkg != "" {
+			placeholder.Name = pkg
+		}
 
-	// Create placeholder symbol
-	placeholder := &ast.Symbol{
-		ID:       id,
-		Name:     name,
-		Kind:     ast.SymbolKindExternal,
-		Package:  pkg,
-		Language: "external",
+		var err error
+		node, err = state.graph.AddNode(placeholder)
+		if err != nil {
+			// Node might already exist (race condition) - just return the ID
+			return id
+		}
+		state.placeholders[id] = node
+		stateStats(state).PlaceholderNodes++
 	}
 
-	node, err := state.graph.AddNode(placeholder)
-	if err != nil {
-		// Node might already exist (race condition) - just return the ID
-		return id
+	if member != "" && addExternalMember(node.Symbol, member) {
+		stateStats(state).CollapsedPlaceholderMembers++
 	}
-
-	state.placeholders[id] = node
-	stateStats(state).PlaceholderNodes++
 	return id
 }
 
 // validateEdgeType checks if an edge type is valid for the given nodes.
 func (b *Builder) validateEdgeType(state *buildState, fromID, toID string, edgeType EdgeType) bool {
+	if toID == "" {
+		// Omitted placeholder: stateAddEdge drops and counts the edge.
+		return true
+	}
 	fromSym := state.symbolsByID[fromID]
 	toSym := state.symbolsByID[toID]
 
@@ -3590,8 +4619,8 @@ func (b *Builder) validateEdgeType(state *buildState, fromID, toID string, edgeT
 	if fromSym == nil || toSym == nil {
 		stateStats(state).ValidationBypassed++
 		slog.Debug("GR-71: edge validation bypassed — symbol not in symbolsByID",
-			slog.String("from_id", fromID),
-			slog.String("to_id", toID),
+			telemetry.SensitiveLog("from_id", fromID),
+			telemetry.SensitiveLog("to_id", toID),
 			slog.String("edge_type", edgeType.String()),
 			slog.Bool("from_nil", fromSym == nil),
 			slog.Bool("to_nil", toSym == nil),
@@ -3605,7 +4634,9 @@ func (b *Builder) validateEdgeType(state *buildState, fromID, toID string, edgeT
 		// (constructor calls like DataFrameFormatter(), new Router()).
 		return isCallable(fromSym.Kind) && isCallTarget(toSym.Kind)
 	case EdgeTypeImplements:
-		return toSym.Kind == ast.SymbolKindInterface
+		// Proto rpcs are implemented by Go methods (resolveProtoServiceEdges).
+		return toSym.Kind == ast.SymbolKindInterface ||
+			(toSym.Kind == ast.SymbolKindMethod && toSym.Language == "proto")
 	case EdgeTypeEmbeds:
 		return fromSym.Kind == ast.SymbolKindStruct || fromSym.Kind == ast.SymbolKindClass || fromSym.Kind == ast.SymbolKindInterface
 	default:
@@ -3642,13 +4673,9 @@ func (b *Builder) validateParseResult(r *ast.ParseResult) error {
 		return fmt.Errorf("nil ParseResult")
 	}
 
-	if r.FilePath == "" {
-		return fmt.Errorf("empty FilePath")
-	}
-
-	// Check for path traversal
-	if strings.Contains(r.FilePath, "..") {
-		return fmt.Errorf("FilePath contains path traversal")
+	// Empty paths and path traversal (shared with ReadSymbolSource)
+	if err := ValidateFilePath(r.FilePath); err != nil {
+		return err
 	}
 
 	// Validate non-nil symbols only
@@ -3717,6 +4744,14 @@ func (b *Builder) checkMemoryLimit() error {
 //	The parser's associateMethodsWithTypes() only works within a single file.
 //	This function operates on the complete symbol set to handle cross-file cases.
 //
+//	Swift extensions are handled the same way: the parser emits extension
+//	methods as top-level symbols with Receiver = the extended type, and they
+//	count toward that type's protocol conformance wherever the type is
+//	declared. Ruby classes reopened in several files are handled the same
+//	way: each declaration of the class gets the instance methods of all of
+//	them. Methods are keyed by language so a Go type never picks up a
+//	Swift method with the same receiver name.
+//
 // Inputs:
 //
 //	ctx - Context for tracing
@@ -3724,7 +4759,8 @@ func (b *Builder) checkMemoryLimit() error {
 //
 // Side Effects:
 //
-//	Modifies Symbol.Metadata.Methods for types (structs and type aliases)
+//	Modifies Symbol.Metadata.Methods for types (structs and type aliases; Swift
+//	structs, classes and enums; Ruby classes and modules)
 //
 // Thread Safety:
 //
@@ -3738,13 +4774,16 @@ func (b *Builder) associateMethodsWithTypesCrossFile(ctx context.Context, state
 		return
 	}
 
-	// Collect all Go methods by receiver type name
-	// methodsByReceiverType[receiverTypeName] = []MethodSignature
-	methodsByReceiverType := make(map[string][]ast.MethodSignature)
+	// Collect all Go, Swift and Ruby methods by receiver type name
+	// methodsByReceiverType[language][receiverTypeName] = []MethodSignature
+	methodsByReceiverType := make(map[string]map[string][]ast.MethodSignature)
+	receiverTypeCount := 0
 	methodCount := 0
 	skippedNoReceiver := 0
 
-	for _, sym := range state.symbolsByID {
+	// Iterate in ID order: the collected slices become Metadata.Methods.
+	orderedSymbols := state.symbolsInIDOrder()
+	for _, sym := range orderedSymbols {
 		// L-6: Check context periodically for large codebases
 		if methodCount%1000 == 0 {
 			if err := ctx.Err(); err != nil {
@@ -3755,13 +4794,14 @@ func (b *Builder) associateMethodsWithTypesCrossFile(ctx context.Context, state
 			}
 		}
 
-		if sym.Kind != ast.SymbolKindMethod || sym.Language != "go" {
+		if sym.Kind != ast.SymbolKindMethod {
 			continue
 		}
 
-		// Extract receiver type name from signature
-		// Signature format: "func (r *Type) Name(params) returns" or "func (r Type) Name(params) returns"
-		receiverType := extractReceiverTypeFromSignature(sym.Signature)
+		receiverType, returns, ok := crossFileReceiver(sym)
+		if !ok {
+			continue
+		}
 		if receiverType == "" {
 			skippedNoReceiver++
 			continue
@@ -3771,19 +4811,27 @@ func (b *Builder) associateMethodsWithTypesCrossFile(ctx context.Context, state
 		sig := ast.MethodSignature{
 			Name:         sym.Name,
 			Params:       extractParamsFromSignature(sym.Signature),
-			Returns:      extractReturnsFromSignature(sym.Signature),
+			Returns:      returns,
 			ReceiverType: receiverType,
 		}
 		sig.ParamCount = countParamString(sig.Params)
 		sig.ReturnCount = countReturnString(sig.Returns)
 
-		methodsByReceiverType[receiverType] = append(methodsByReceiverType[receiverType], sig)
+		byType := methodsByReceiverType[sym.Language]
+		if byType == nil {
+			byType = make(map[string][]ast.MethodSignature)
+			methodsByReceiverType[sym.Language] = byType
+		}
+		if _, seen := byType[receiverType]; !seen {
+			receiverTypeCount++
+		}
+		byType[receiverType] = append(byType[receiverType], sig)
 		methodCount++
 	}
 
 	span.SetAttributes(
 		attribute.Int("methods_collected", methodCount),
-		attribute.Int("receiver_types", len(methodsByReceiverType)),
+		attribute.Int("receiver_types", receiverTypeCount),
 		attribute.Int("skipped_no_receiver", skippedNoReceiver),
 	)
 
@@ -3802,15 +4850,25 @@ func (b *Builder) associateMethodsWithTypesCrossFile(ctx context.Context, state
 
 	// Associate methods with their types (cross-file!)
 	typesUpdated := 0
-	for _, sym := range state.symbolsByID {
-		if sym.Language != "go" {
-			continue
-		}
-		if sym.Kind != ast.SymbolKindStruct && sym.Kind != ast.SymbolKindType {
+	for _, sym := range orderedSymbols {
+		switch sym.Language {
+		case "go":
+			if sym.Kind != ast.SymbolKindStruct && sym.Kind != ast.SymbolKindType {
+				continue
+			}
+		case "swift":
+			if sym.Kind != ast.SymbolKindStruct && sym.Kind != ast.SymbolKindClass && sym.Kind != ast.SymbolKindEnum {
+				continue
+			}
+		case "ruby":
+			if sym.Kind != ast.SymbolKindClass {
+				continue
+			}
+		default:
 			continue
 		}
 
-		methods, ok := methodsByReceiverType[sym.Name]
+		methods, ok := methodsByReceiverType[sym.Language][sym.Name]
 		if !ok || len(methods) == 0 {
 			continue
 		}
@@ -3838,11 +4896,49 @@ func (b *Builder) associateMethodsWithTypesCrossFile(ctx context.Context, state
 
 	slog.Debug("cross-file method association complete",
 		slog.Int("methods_collected", methodCount),
-		slog.Int("receiver_types", len(methodsByReceiverType)),
+		slog.Int("receiver_types", receiverTypeCount),
 		slog.Int("types_updated", typesUpdated),
 	)
 }
 
+// crossFileReceiver returns the receiver type name and return types of a
+// method that associateMethodsWithTypesCrossFile attaches to its type.
+//
+// Description:
+//
+//	Only Go, Swift and Ruby methods are associated across files. ok is
+//	false for methods of other languages and for Swift and Ruby static
+//	methods and initializers, which are not part of the instance method
+//	set. A true ok with an empty receiverType means the receiver could not
+//	be parsed.
+//
+// Thread Safety: This function is safe for concurrent use.
+func crossFileReceiver(sym *ast.Symbol) (receiverType, returns string, ok bool) {
+	switch sym.Language {
+	case "go":
+		// Extract receiver type name from signature
+		// Signature format: "func (r *Type) Name(params) returns" or "func (r Type) Name(params) returns"
+		return extractReceiverTypeFromSignature(sym.Signature), extractReturnsFromSignature(sym.Signature), true
+	case "swift":
+		// Signature format: "func name(params) -> Return"
+		if sym.Metadata != nil && (sym.Metadata.IsStatic || sym.Metadata.IsConstructor) {
+			return "", "", false
+		}
+		if sym.Metadata != nil {
+			returns = sym.Metadata.ReturnType
+		}
+		return sym.Receiver, returns, true
+	case "ruby":
+		// Signature format: "def name(params)"; Ruby declares no return types.
+		if sym.Metadata != nil && (sym.Metadata.IsStatic || sym.Metadata.IsConstructor) {
+			return "", "", false
+		}
+		return sym.Receiver, "", true
+	default:
+		return "", "", false
+	}
+}
+
 // extractReceiverTypeFromSignature extracts the receiver type name from a Go method signature.
 // Example: "func (h *Handler) Handle()" returns "Handler"
 // Example: "func (s Server) Start()" returns "Server"
@@ -4070,6 +5166,73 @@ func countReturnString(returns string) int {
 
 // === GR-40/GR-40a: Implicit Interface Implementation Detection ===
 
+// implicitInterfaceLanguages are the languages whose interfaces are matched
+// against type method sets by computeInterfaceImplementations.
+// GR-40: Go, GR-40a: Python, IT-03a A-1: TypeScript.
+var implicitInterfaceLanguages = map[string]bool{
+	"go":         true,
+	"python":     true,
+	"typescript": true,
+	"kotlin":     true,
+	"swift":      true,
+	"php":        true,
+}
+
+// interfaceCandidateReason reports why sym is not matched as an interface by
+// computeInterfaceImplementations, or "" if it is.
+//
+// Description:
+//
+//	Shared by the builder and ExplainImplements so both apply the same
+//	eligibility rules. An interface with no direct methods is still a
+//	candidate: it may gain methods through embedded or extended interfaces
+//	and is only pruned once those are resolved.
+//
+// Thread Safety: This function is safe for concurrent use.
+func interfaceCandidateReason(sym *ast.Symbol) string {
+	if sym.Kind != ast.SymbolKindInterface {
+		return fmt.Sprintf("%s is a %s, not an interface", sym.Name, sym.Kind)
+	}
+	if !implicitInterfaceLanguages[sym.Language] {
+		return fmt.Sprintf("implicit implementations are not detected for %s", sym.Language)
+	}
+	// Phase 18: Don't skip interfaces with no direct methods yet — they may gain
+	// methods via embedded/extended interfaces (Go: ReadWriter embeds Reader + Writer,
+	// TS: interface ReadWriter extends Reader, Writer {}, Python: class Combined(ReadProto, WriteProto, Protocol)).
+	// Only an interface without metadata at all is truly empty.
+	if sym.Metadata == nil {
+		return fmt.Sprintf("%s has no method metadata", sym.Name)
+	}
+	return ""
+}
+
+// typeCandidateReason reports why sym is not matched as an implementing
+// type by computeInterfaceImplementations, or "" if it is.
+//
+// Thread Safety: This function is safe for concurrent use.
+func typeCandidateReason(sym *ast.Symbol) string {
+	if sym.Kind != ast.SymbolKindStruct && sym.Kind != ast.SymbolKindType && sym.Kind != ast.SymbolKindClass &&
+		(sym.Kind != ast.SymbolKindEnum || sym.Language != "swift") {
+		return fmt.Sprintf("%s is a %s, not a struct, class or type", sym.Name, sym.Kind)
+	}
+	if !implicitInterfaceLanguages[sym.Language] {
+		return fmt.Sprintf("implicit implementations are not detected for %s", sym.Language)
+	}
+	// IT-03 H-3: Include types that have embeds even if they have no direct methods,
+	// because promoted methods from embedded types may satisfy interfaces.
+	if sym.Metadata == nil {
+		return fmt.Sprintf("%s has no methods or embedded types", sym.Name)
+	}
+	// CR-20-4: Check Implements slice too — Phase 16 G-1 stores additional Go struct
+	// embeds in Implements. A type with only Implements entries (no Extends, no Methods)
+	// would be incorrectly skipped without this check.
+	if len(sym.Metadata.Methods) == 0 && sym.Metadata.Extends == "" && len(sym.Metadata.Implements) == 0 &&
+		len(sym.Metadata.Mixins) == 0 {
+		return fmt.Sprintf("%s has no methods or embedded types", sym.Name)
+	}
+	return ""
+}
+
 // computeInterfaceImplementations detects implicit interface implementations via method-set matching.
 //
 // Description:
@@ -4083,6 +5246,10 @@ func countReturnString(returns string) int {
 //	Supported languages:
 //	  - Go: All interfaces (GR-40)
 //	  - Python: typing.Protocol classes (GR-40a)
+//	  - TypeScript: Interfaces (IT-03a A-1)
+//	  - Kotlin: Interfaces
+//	  - Swift: Protocols, matched against structs, classes and enums
+//	    including methods added by extensions in other files
 //
 //	This is called after all symbols are collected and their Metadata.Methods populated.
 //
@@ -4123,22 +5290,9 @@ func (b *Builder) computeInterfaceImplementations(ctx context.Context, state *bu
 	interfacesByLang := make(map[string]map[string]map[string]bool)
 
 	for _, sym := range state.symbolsByID {
-		if sym.Kind != ast.SymbolKindInterface {
+		if interfaceCandidateReason(sym) != "" {
 			continue
 		}
-		// GR-40: Go, GR-40a: Python, IT-03a A-1: TypeScript
-		if sym.Language != "go" && sym.Language != "python" && sym.Language != "typescript" {
-			continue
-		}
-		// Phase 18: Don't skip interfaces with no direct methods yet — they may gain
-		// methods via embedded/extended interfaces (Go: ReadWriter embeds Reader + Writer,
-		// TS: interface ReadWriter extends Reader, Writer {}, Python: class Combined(ReadProto, WriteProto, Protocol)).
-		// We resolve embedded methods below via EMBEDS edges, then prune still-empty ones.
-		if sym.Metadata == nil {
-			// No metadata at all — truly empty, safe to skip
-			continue
-		}
-		// Collect even if Methods is empty — will resolve via EMBEDS edges
 
 		if interfacesByLang[sym.Language] == nil {
 			interfacesByLang[sym.Language] = make(map[string]map[string]bool)
@@ -4195,24 +5349,9 @@ func (b *Builder) computeInterfaceImplementations(ctx context.Context, state *bu
 	typesByLang := make(map[string]map[string]map[string]bool)
 
 	for _, sym := range state.symbolsByID {
-		if sym.Kind != ast.SymbolKindStruct && sym.Kind != ast.SymbolKindType && sym.Kind != ast.SymbolKindClass {
-			continue
-		}
-		// GR-40: Go, GR-40a: Python, IT-03a A-1: TypeScript
-		if sym.Language != "go" && sym.Language != "python" && sym.Language != "typescript" {
+		if typeCandidateReason(sym) != "" {
 			continue
 		}
-		// IT-03 H-3: Include types that have embeds even if they have no direct methods,
-		// because promoted methods from embedded types may satisfy interfaces.
-		if sym.Metadata == nil {
-			continue
-		}
-		// CR-20-4: Check Implements slice too — Phase 16 G-1 stores additional Go struct
-		// embeds in Implements. A type with only Implements entries (no Extends, no Methods)
-		// would be incorrectly skipped without this check.
-		if len(sym.Metadata.Methods) == 0 && sym.Metadata.Extends == "" && len(sym.Metadata.Implements) == 0 {
-			continue // No methods and no embeds
-		}
 
 		if typesByLang[sym.Language] == nil {
 			typesByLang[sym.Language] = make(map[string]map[string]bool)
@@ -4229,10 +5368,14 @@ func (b *Builder) computeInterfaceImplementations(ctx context.Context, state *bu
 	// In Go, when struct A embeds struct B, A inherits B's methods (promoted methods).
 	// Walk EMBEDS edges recursively and merge embedded type methods into each type's method set.
 	promotedCount := 0
+	promotedTypes := make(map[string]bool)
 	for _, types := range typesByLang {
 		for typeID, methodSet := range types {
 			added := b.resolvePromotedMethods(state, typeID, methodSet, make(map[string]bool))
 			promotedCount += added
+			if added > 0 {
+				promotedTypes[typeID] = true
+			}
 		}
 	}
 
@@ -4297,8 +5440,14 @@ func (b *Builder) computeInterfaceImplementations(ctx context.Context, state *bu
 
 				// Check if type's method set is a superset of interface's method set
 				if isMethodSuperset(typeMethods, ifaceMethods) {
-					// Create EdgeTypeImplements from type to interface
-					err := stateAddEdge(state, typeID, ifaceID, EdgeTypeImplements, typeSym.Location())
+					// Create EdgeTypeImplements from type to interface. The
+					// match needed promoted methods if the type's own methods
+					// fall short.
+					prov := EdgeProvenance{Pass: PassMethodSet, Confidence: ConfidenceMedium}
+					if promotedTypes[typeID] && !isMethodSuperset(ownMethodSet(typeSym), ifaceMethods) {
+						prov.Pass = PassPromotedMethods
+					}
+					err := stateAddEdge(state, typeID, ifaceID, EdgeTypeImplements, typeSym.Location(), prov)
 					if err != nil {
 						stateAddEdgeError(state, EdgeError{
 							FromID:   typeID,
@@ -4373,6 +5522,19 @@ func isMethodSuperset(superset, subset map[string]bool) bool {
 	return true
 }
 
+// ownMethodSet returns the names of the methods sym declares itself,
+// without methods promoted from embedded types.
+func ownMethodSet(sym *ast.Symbol) map[string]bool {
+	methods := make(map[string]bool)
+	if sym.Metadata == nil {
+		return methods
+	}
+	for _, m := range sym.Metadata.Methods {
+		methods[m.Name] = true
+	}
+	return methods
+}
+
 // resolvePromotedMethods follows outgoing EMBEDS edges from a type and merges
 // the embedded type's methods into the given method set.
 //
@@ -4407,18 +5569,48 @@ func (b *Builder) resolvePromotedMethods(state *buildState, typeID string, metho
 // CR-20-3: Added depth parameter to prevent stack overflow on pathological embedding chains.
 // Stops at maxEmbedResolutionDepth (20). Cycle detection via visited set is retained.
 func (b *Builder) resolvePromotedMethodsWithDepth(state *buildState, typeID string, methodSet map[string]bool, visited map[string]bool, depth int) int {
+	return mergeEmbeddedMethods(state.graph, state.lookupSymbol, typeID, methodSet, nil, visited, depth)
+}
+
+// mergeEmbeddedMethods follows outgoing EMBEDS edges from id and merges the
+// embedded symbols' method names into methodSet.
+//
+// Description:
+//
+//	The shared walk behind promoted-method resolution for types and
+//	embedded-method resolution for interfaces: a struct embedding another
+//	struct gains its methods, and an interface embedding another interface
+//	requires its methods. Embeds are followed transitively.
+//
+// Inputs:
+//   - g: The graph whose EMBEDS edges are followed.
+//   - lookup: Returns the symbol for an ID, or nil.
+//   - id: The type or interface to resolve.
+//   - methodSet: The method set to merge into. Modified in place.
+//   - origin: Optional. Records, for each method added, the ID of the
+//     embedded symbol that declares it.
+//   - visited: IDs already walked, to prevent infinite cycles.
+//   - depth: The current recursion depth.
+//
+// Outputs:
+//   - int: Number of methods added to methodSet.
+//
+// Thread Safety: Not safe for concurrent use on the same methodSet.
+//
+// CR-20-3: Depth-limited to maxEmbedResolutionDepth (20) to prevent stack overflow.
+func mergeEmbeddedMethods(g *Graph, lookup func(id string) *ast.Symbol, id string, methodSet map[string]bool, origin map[string]string, visited map[string]bool, depth int) int {
 	if depth > maxEmbedResolutionDepth {
 		return 0
 	}
-	if visited[typeID] {
+	if visited[id] {
 		return 0
 	}
-	visited[typeID] = true
+	visited[id] = true
 
 	added := 0
 
 	// Look up the node in the graph to find outgoing EMBEDS edges
-	node, exists := state.graph.GetNode(typeID)
+	node, exists := g.GetNode(id)
 	if !exists || node == nil {
 		return 0
 	}
@@ -4428,22 +5620,25 @@ func (b *Builder) resolvePromotedMethodsWithDepth(state *buildState, typeID stri
 			continue
 		}
 
-		// Get the embedded type's symbol to access its methods
-		embeddedSym := state.symbolsByID[edge.ToID]
+		// Get the embedded symbol to access its methods
+		embeddedSym := lookup(edge.ToID)
 		if embeddedSym == nil || embeddedSym.Metadata == nil {
 			continue
 		}
 
-		// Merge the embedded type's methods into our method set
+		// Merge the embedded symbol's methods into our method set
 		for _, m := range embeddedSym.Metadata.Methods {
 			if !methodSet[m.Name] {
 				methodSet[m.Name] = true
+				if origin != nil {
+					origin[m.Name] = edge.ToID
+				}
 				added++
 			}
 		}
 
-		// Recurse: the embedded type may itself embed other types
-		added += b.resolvePromotedMethodsWithDepth(state, edge.ToID, methodSet, visited, depth+1)
+		// Recurse: the embedded symbol may itself embed others
+		added += mergeEmbeddedMethods(g, lookup, edge.ToID, methodSet, origin, visited, depth+1)
 	}
 
 	return added
@@ -4517,43 +5712,5 @@ func (b *Builder) resolveInterfaceEmbedsRecursive(state *buildState, ifaceID str
 // embedding chains. Stops at maxEmbedResolutionDepth (20). Cycle detection via visited
 // set is retained as the primary guard; depth limit is defense-in-depth.
 func (b *Builder) resolveInterfaceEmbedsRecursiveWithDepth(state *buildState, ifaceID string, methodSet map[string]bool, visited map[string]bool, depth int) int {
-	if depth > maxEmbedResolutionDepth {
-		return 0
-	}
-	if visited[ifaceID] {
-		return 0
-	}
-	visited[ifaceID] = true
-
-	added := 0
-
-	node, exists := state.graph.GetNode(ifaceID)
-	if !exists || node == nil {
-		return 0
-	}
-
-	for _, edge := range node.Outgoing {
-		if edge.Type != EdgeTypeEmbeds {
-			continue
-		}
-
-		// Get the embedded interface's symbol to access its methods
-		embeddedSym := state.symbolsByID[edge.ToID]
-		if embeddedSym == nil || embeddedSym.Metadata == nil {
-			continue
-		}
-
-		// Merge the embedded interface's methods into our method set
-		for _, m := range embeddedSym.Metadata.Methods {
-			if !methodSet[m.Name] {
-				methodSet[m.Name] = true
-				added++
-			}
-		}
-
-		// Recurse: the embedded interface may itself embed other interfaces
-		added += b.resolveInterfaceEmbedsRecursiveWithDepth(state, edge.ToID, methodSet, visited, depth+1)
-	}
-
-	return added
+	return mergeEmbeddedMethods(state.graph, state.lookupSymbol, ifaceID, methodSet, nil, visited, depth)
 }
@@ -14,6 +14,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -1304,6 +1308,172 @@ func TestBuilder_GoInterfaceImplementation(t *testing.T) {
 	})
 }
 
+// TestBuilder_GoGenericInterfaceImplementation verifies that generic Go types
+// satisfy generic interfaces regardless of type parameters, and that type
+// arguments of instantiated generics produce REFERENCES edges.
+func TestBuilder_GoGenericInterfaceImplementation(t *testing.T) {
+	builder := NewBuilder(WithProjectRoot("/test"))
+	ctx := context.Background()
+
+	t.Run("generic struct implements generic interface", func(t *testing.T) {
+		// type Repository[T any] interface { Get(id string) (T, error); Save(item T) error }
+		repoInterface := &ast.Symbol{
+			ID:        "repo.go:10:Repository",
+			Name:      "Repository",
+			Kind:      ast.SymbolKindInterface,
+			FilePath:  "repo.go",
+			StartLine: 10,
+			EndLine:   15,
+			StartCol:  0,
+			EndCol:    50,
+			Language:  "go",
+			Metadata: &ast.SymbolMetadata{
+				TypeParameters: []string{"T"},
+				Constraints:    []string{"any"},
+				Methods: []ast.MethodSignature{
+					{Name: "Get", ParamCount: 1, ReturnCount: 2},
+					{Name: "Save", ParamCount: 1, ReturnCount: 1},
+				},
+			},
+		}
+
+		// type MemoryRepo[T any] struct { ... } — methods declared in another file
+		memoryRepo := &ast.Symbol{
+			ID:        "memory.go:5:MemoryRepo",
+			Name:      "MemoryRepo",
+			Kind:      ast.SymbolKindStruct,
+			FilePath:  "memory.go",
+			StartLine: 5,
+			EndLine:   10,
+			StartCol:  0,
+			EndCol:    50,
+			Language:  "go",
+			Metadata: &ast.SymbolMetadata{
+				TypeParameters: []string{"T"},
+				Constraints:    []string{"any"},
+			},
+		}
+
+		getMethod := &ast.Symbol{
+			ID:        "memory_methods.go:10:MemoryRepo.Get",
+			Name:      "Get",
+			Kind:      ast.SymbolKindMethod,
+			FilePath:  "memory_methods.go",
+			StartLine: 10,
+			EndLine:   15,
+			StartCol:  0,
+			EndCol:    50,
+			Language:  "go",
+			Receiver:  "MemoryRepo",
+			Signature: "func (r *MemoryRepo[T]) Get(id string) (T, error)",
+			Metadata:  &ast.SymbolMetadata{TypeParameters: []string{"T"}},
+		}
+		saveMethod := &ast.Symbol{
+			ID:        "memory_methods.go:20:MemoryRepo.Save",
+			Name:      "Save",
+			Kind:      ast.SymbolKindMethod,
+			FilePath:  "memory_methods.go",
+			StartLine: 20,
+			EndLine:   25,
+			StartCol:  0,
+			EndCol:    50,
+			Language:  "go",
+			Receiver:  "MemoryRepo",
+			Signature: "func (r *MemoryRepo[T]) Save(item T) error",
+			Metadata:  &ast.SymbolMetadata{TypeParameters: []string{"T"}},
+		}
+
+		result, err := builder.Build(ctx, []*ast.ParseResult{
+			testParseResult("repo.go", []*ast.Symbol{repoInterface}, nil),
+			testParseResult("memory.go", []*ast.Symbol{memoryRepo}, nil),
+			testParseResult("memory_methods.go", []*ast.Symbol{getMethod, saveMethod}, nil),
+		})
+		if err != nil {
+			t.Fatalf("build failed: %v", err)
+		}
+
+		repoNode, ok := result.Graph.GetNode(memoryRepo.ID)
+		if !ok {
+			t.Fatal("MemoryRepo node not found")
+		}
+		if repoNode.Symbol.Metadata == nil || len(repoNode.Symbol.Metadata.Methods) != 2 {
+			t.Fatalf("expected 2 methods associated with MemoryRepo, got %+v", repoNode.Symbol.Metadata)
+		}
+
+		foundImplements := false
+		for _, edge := range repoNode.Outgoing {
+			if edge.Type == EdgeTypeImplements && edge.ToID == repoInterface.ID {
+				foundImplements = true
+				break
+			}
+		}
+		if !foundImplements {
+			t.Error("expected EdgeTypeImplements from MemoryRepo to Repository")
+		}
+
+		// The generic receiver must resolve to the declared type, not a placeholder.
+		for id := range result.Graph.Nodes() {
+			if strings.Contains(id, "MemoryRepo[") {
+				t.Errorf("unexpected placeholder node for instantiated receiver: %s", id)
+			}
+		}
+	})
+
+	t.Run("type arguments create references edges", func(t *testing.T) {
+		user := &ast.Symbol{
+			ID:        "user.go:5:User",
+			Name:      "User",
+			Kind:      ast.SymbolKindStruct,
+			FilePath:  "user.go",
+			StartLine: 5,
+			EndLine:   10,
+			StartCol:  0,
+			EndCol:    50,
+			Language:  "go",
+		}
+
+		// func LoadUsers() { repo := NewMemoryRepo[User](); ... }
+		loadUsers := &ast.Symbol{
+			ID:        "service.go:10:LoadUsers",
+			Name:      "LoadUsers",
+			Kind:      ast.SymbolKindFunction,
+			FilePath:  "service.go",
+			StartLine: 10,
+			EndLine:   20,
+			StartCol:  0,
+			EndCol:    50,
+			Language:  "go",
+			Signature: "func LoadUsers()",
+			Metadata: &ast.SymbolMetadata{
+				TypeArguments: []string{"User"},
+			},
+		}
+
+		result, err := builder.Build(ctx, []*ast.ParseResult{
+			testParseResult("user.go", []*ast.Symbol{user}, nil),
+			testParseResult("service.go", []*ast.Symbol{loadUsers}, nil),
+		})
+		if err != nil {
+			t.Fatalf("build failed: %v", err)
+		}
+
+		loadNode, ok := result.Graph.GetNode(loadUsers.ID)
+		if !ok {
+			t.Fatal("LoadUsers node not found")
+		}
+		foundRef := false
+		for _, edge := range loadNode.Outgoing {
+			if edge.Type == EdgeTypeReferences && edge.ToID == user.ID {
+				foundRef = true
+				break
+			}
+		}
+		if !foundRef {
+			t.Error("expected EdgeTypeReferences from LoadUsers to User via type argument")
+		}
+	})
+}
+
 // TestBuilder_PromotedMethodResolution verifies that Go structs with embedded types
 // inherit promoted methods for interface implementation detection.
 // IT-03 H-3: Engine embeds RouterGroup → Engine gets RouterGroup's methods → Engine satisfies IRouter.
@@ -7955,3 +8125,810 @@ func BenchmarkBuilder_Build_Workers4(b *testing.B) {
 		_, _ = builder.Build(ctx, results)
 	}
 }
+
+// makeEdgeExtractionCorpus builds a synthetic project of fileCount files with
+// symbolsPerFile functions each. Every function calls a function in the next
+// file (cross-file resolution) and an unresolved external helper.
+func makeEdgeExtractionCorpus(fileCount, symbolsPerFile int) []*ast.ParseResult {
+	results := make([]*ast.ParseResult, fileCount)
+	for f := 0; f < fileCount; f++ {
+		filePath := fmt.Sprintf("pkg%d/file_%d.go", f%50, f)
+		symbols := make([]*ast.Symbol, symbolsPerFile)
+		for s := 0; s < symbolsPerFile; s++ {
+			line := s*20 + 1
+			target := fmt.Sprintf("Func_%d_%d", (f+1)%fileCount, s)
+			symbols[s] = testSymbolWithCalls(fmt.Sprintf("Func_%d_%d", f, s), ast.SymbolKindFunction, filePath, line, []ast.CallSite{
+				{Target: target, Location: ast.Location{FilePath: filePath, StartLine: line + 2}},
+				{Target: "ExternalHelper", Location: ast.Location{FilePath: filePath, StartLine: line + 3}},
+			})
+		}
+		results[f] = testParseResult(filePath, symbols, []ast.Import{
+			{Path: "fmt", Alias: "fmt", Location: ast.Location{FilePath: filePath, StartLine: 1}},
+		})
+	}
+	return results
+}
+
+// TestBuilder_Build_Parallel_MatchesSequentialOnCorpus verifies that parallel
+// edge extraction over a cross-file corpus produces the same graph as the
+// sequential path.
+func TestBuilder_Build_Parallel_MatchesSequentialOnCorpus(t *testing.T) {
+	results := makeEdgeExtractionCorpus(200, 5)
+	ctx := context.Background()
+
+	seq, err := NewBuilder(WithProjectRoot("/test"), WithWorkerCount(1)).Build(ctx, results)
+	if err != nil {
+		t.Fatalf("sequential build error: %v", err)
+	}
+	par, err := NewBuilder(WithProjectRoot("/test"), WithWorkerCount(8)).Build(ctx, results)
+	if err != nil {
+		t.Fatalf("parallel build error: %v", err)
+	}
+
+	if seq.Graph.EdgeCount() != par.Graph.EdgeCount() {
+		t.Errorf("edge count mismatch: sequential=%d parallel=%d", seq.Graph.EdgeCount(), par.Graph.EdgeCount())
+	}
+	if seq.Graph.Hash() != par.Graph.Hash() {
+		t.Error("graph hash differs between sequential and parallel builds")
+	}
+	if seq.Stats.CallEdgesResolved != par.Stats.CallEdgesResolved {
+		t.Errorf("resolved calls mismatch: sequential=%d parallel=%d", seq.Stats.CallEdgesResolved, par.Stats.CallEdgesResolved)
+	}
+}
+
+// TestBuilder_Build_Parallel_ProgressMonotonic verifies that edge extraction
+// progress never goes backwards when reported from multiple workers.
+func TestBuilder_Build_Parallel_ProgressMonotonic(t *testing.T) {
+	results := makeEdgeExtractionCorpus(400, 2)
+
+	last := 0
+	calls := 0
+	builder := NewBuilder(
+		WithProjectRoot("/test"),
+		WithWorkerCount(8),
+		WithProgressCallback(func(p BuildProgress) {
+			if p.Phase != ProgressPhaseExtractingEdges {
+				return
+			}
+			calls++
+			if p.FilesProcessed < last {
+				t.Errorf("progress went backwards: %d after %d", p.FilesProcessed, last)
+			}
+			last = p.FilesProcessed
+		}),
+	)
+
+	if _, err := builder.Build(context.Background(), results); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if calls != len(results) || last != len(results) {
+		t.Errorf("expected %d extraction reports ending at %d, got %d ending at %d", len(results), len(results), calls, last)
+	}
+}
+
+// TestBuilder_Build_CancelDuringEdgeExtraction verifies that cancelling
+// mid-extraction returns the context error and a partial graph that keeps
+// the edges of the files processed so far.
+func TestBuilder_Build_CancelDuringEdgeExtraction(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			results := makeEdgeExtractionCorpus(200, 3)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			builder := NewBuilder(
+				WithProjectRoot("/test"),
+				WithWorkerCount(workers),
+				WithProgressCallback(func(p BuildProgress) {
+					if p.Phase == ProgressPhaseExtractingEdges && p.FilesProcessed == 50 {
+						cancel()
+					}
+				}),
+			)
+
+			result, err := builder.Build(ctx, results)
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+			if !result.Incomplete {
+				t.Error("expected Incomplete=true")
+			}
+			if result.Graph == nil {
+				t.Fatal("expected partial graph")
+			}
+			if result.Graph.NodeCount() < 600 {
+				t.Errorf("expected all collected nodes, got %d", result.Graph.NodeCount())
+			}
+			if result.Graph.EdgeCount() == 0 {
+				t.Error("expected edges from files processed before cancellation")
+			}
+			if result.Stats.CallEdgesResolved == 0 {
+				t.Error("expected call edge stats from processed files")
+			}
+		})
+	}
+}
+
+// BenchmarkBuilder_EdgeExtraction_10kSymbols compares sequential and parallel
+// edge extraction on a synthetic 10k-symbol corpus (1000 files x 10 symbols).
+func BenchmarkBuilder_EdgeExtraction_10kSymbols(b *testing.B) {
+	results := makeEdgeExtractionCorpus(1000, 10)
+	ctx := context.Background()
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				builder := NewBuilder(WithProjectRoot("/test"), WithWorkerCount(workers))
+				if _, err := builder.Build(ctx, results); err != nil {
+					b.Fatalf("build error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// makeDeterminismCorpus returns a fresh cross-file corpus with call edges,
+// placeholders and Go methods declared away from their receiver type. Each
+// call allocates new symbols because builds write type metadata in place.
+func makeDeterminismCorpus() []*ast.ParseResult {
+	results := makeEdgeExtractionCorpus(120, 4)
+	server := testSymbol("Server", ast.SymbolKindStruct, "srv/server.go", 3)
+	results = append(results, testParseResult("srv/server.go", []*ast.Symbol{server}, nil))
+	for i, name := range []string{"Start", "Stop", "Reload", "Close"} {
+		filePath := fmt.Sprintf("srv/method_%d.go", i)
+		m := testSymbol(name, ast.SymbolKindMethod, filePath, 5)
+		m.Receiver = "Server"
+		m.Signature = fmt.Sprintf("func (s *Server) %s() error", name)
+		results = append(results, testParseResult(filePath, []*ast.Symbol{m}, nil))
+	}
+	return results
+}
+
+// TestBuilder_Build_Deterministic verifies that the same input produces the
+// same content hash and stats for any worker count and input order.
+func TestBuilder_Build_Deterministic(t *testing.T) {
+	ctx := context.Background()
+
+	build := func(t *testing.T, workers int, shuffle bool) *BuildResult {
+		t.Helper()
+		results := makeDeterminismCorpus()
+		if shuffle {
+			rnd := rand.New(rand.NewSource(int64(workers)))
+			rnd.Shuffle(len(results), func(i, j int) { results[i], results[j] = results[j], results[i] })
+		}
+		result, err := NewBuilder(WithProjectRoot("/test"), WithWorkerCount(workers)).Build(ctx, results)
+		if err != nil {
+			t.Fatalf("build with %d workers: %v", workers, err)
+		}
+		if result.Stats.ContentHash == "" {
+			t.Fatalf("build with %d workers: empty ContentHash", workers)
+		}
+		return result
+	}
+
+	methodNames := func(r *BuildResult) []string {
+		nodes := r.Graph.GetNodesByName("Server")
+		if len(nodes) != 1 || nodes[0].Symbol.Metadata == nil {
+			t.Fatalf("expected one Server node with metadata, got %d", len(nodes))
+		}
+		var names []string
+		for _, m := range nodes[0].Symbol.Metadata.Methods {
+			names = append(names, m.Name)
+		}
+		return names
+	}
+	placeholderIDs := func(r *BuildResult) []string {
+		var ids []string
+		for _, n := range r.Graph.GetNodesByKind(ast.SymbolKindExternal) {
+			ids = append(ids, n.ID)
+		}
+		return ids
+	}
+
+	base := build(t, 1, false)
+	if got := base.Stats.ContentHash; got != base.Graph.ContentHash() {
+		t.Errorf("Stats.ContentHash %q does not match Graph.ContentHash %q", got, base.Graph.ContentHash())
+	}
+
+	for _, tc := range []struct {
+		workers int
+		shuffle bool
+	}{{1, true}, {2, false}, {4, false}, {8, false}, {8, true}} {
+		t.Run(fmt.Sprintf("workers=%d/shuffle=%v", tc.workers, tc.shuffle), func(t *testing.T) {
+			got := build(t, tc.workers, tc.shuffle)
+
+			if got.Stats.ContentHash != base.Stats.ContentHash {
+				t.Errorf("ContentHash = %s, want %s", got.Stats.ContentHash, base.Stats.ContentHash)
+			}
+			if got.Graph.Hash() != base.Graph.Hash() {
+				t.Error("Graph.Hash differs")
+			}
+
+			a, b := base.Stats, got.Stats
+			a.DurationMilli, a.DurationMicro = 0, 0
+			b.DurationMilli, b.DurationMicro = 0, 0
+			if !reflect.DeepEqual(a, b) {
+				t.Errorf("Stats differ:\n base=%+v\n  got=%+v", a, b)
+			}
+			if !reflect.DeepEqual(methodNames(base), methodNames(got)) {
+				t.Errorf("Server methods = %v, want %v", methodNames(got), methodNames(base))
+			}
+			if !reflect.DeepEqual(placeholderIDs(base), placeholderIDs(got)) {
+				t.Errorf("placeholder order = %v, want %v", placeholderIDs(got), placeholderIDs(base))
+			}
+		})
+	}
+}
+
+// buildMultiLanguageTestGraph parses the given files in path order with
+// the real parser for each file's extension and builds a graph from them.
+// Files with an extension that has no parser fail the test.
+func buildMultiLanguageTestGraph(t *testing.T, files map[string]string) *BuildResult {
+	t.Helper()
+	ctx := context.Background()
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var results []*ast.ParseResult
+	for _, path := range paths {
+		var parser ast.Parser
+		switch filepath.Ext(path) {
+		case ".go":
+			parser = ast.NewGoParser()
+		case ".swift":
+			parser = ast.NewSwiftParser()
+		case ".kt":
+			parser = ast.NewKotlinParser()
+		case ".php":
+			parser = ast.NewPHPParser()
+		case ".rb":
+			parser = ast.NewRubyParser()
+		case ".ex":
+			parser = ast.NewElixirParser()
+		default:
+			t.Fatalf("no parser for %s", path)
+		}
+		r, err := parser.Parse(ctx, []byte(files[path]), path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		results = append(results, r)
+	}
+
+	result, err := NewBuilder(WithProjectRoot("/test")).Build(ctx, results)
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	return result
+}
+
+// hasOutgoingEdge reports whether the node named from in file has an edge
+// of edgeType to a node named to.
+func hasOutgoingEdge(t *testing.T, g *Graph, file, from string, edgeType EdgeType, to string) bool {
+	t.Helper()
+	for _, node := range g.Nodes() {
+		if node.Symbol == nil || node.Symbol.Name != from || node.Symbol.FilePath != file {
+			continue
+		}
+		for _, edge := range node.Outgoing {
+			if edge.Type != edgeType {
+				continue
+			}
+			if target, ok := g.GetNode(edge.ToID); ok && target.Symbol != nil && target.Symbol.Name == to {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestBuilder_SwiftProtocolConformance(t *testing.T) {
+	t.Run("extension in another file completes the method set", func(t *testing.T) {
+		result := buildMultiLanguageTestGraph(t, map[string]string{
+			"a.swift": `protocol Store {
+    func load(key: String) -> Data
+    func save(key: String, data: Data)
+}
+`,
+			"b.swift": `struct DiskStore {
+    func load(key: String) -> Data { read(key) }
+}
+`,
+			"c.swift": `extension DiskStore {
+    func save(key: String, data: Data) { write(key, data) }
+}
+`,
+		})
+
+		if !hasOutgoingEdge(t, result.Graph, "b.swift", "DiskStore", EdgeTypeImplements, "Store") {
+			t.Error("expected IMPLEMENTS edge from DiskStore to Store via the cross-file extension")
+		}
+		if !hasOutgoingEdge(t, result.Graph, "c.swift", "save", EdgeTypeReceives, "DiskStore") {
+			t.Error("expected RECEIVES edge from the extension method save to DiskStore")
+		}
+	})
+
+	t.Run("without the extension the struct does not conform", func(t *testing.T) {
+		result := buildMultiLanguageTestGraph(t, map[string]string{
+			"a.swift": `protocol Store {
+    func load(key: String) -> Data
+    func save(key: String, data: Data)
+}
+`,
+			"b.swift": `struct DiskStore {
+    func load(key: String) -> Data { read(key) }
+}
+`,
+		})
+
+		if hasOutgoingEdge(t, result.Graph, "b.swift", "DiskStore", EdgeTypeImplements, "Store") {
+			t.Error("DiskStore lacks save and should not implement Store")
+		}
+	})
+
+	t.Run("declared conformance and calls", func(t *testing.T) {
+		result := buildMultiLanguageTestGraph(t, map[string]string{
+			"a.swift": `protocol Greeter {
+    func greet() -> String
+}
+`,
+			"b.swift": `class Base {}
+
+class Service: Base, Greeter {
+    func greet() -> String { return self.name() }
+    func name() -> String { "svc" }
+}
+`,
+		})
+
+		if !hasOutgoingEdge(t, result.Graph, "b.swift", "Service", EdgeTypeImplements, "Greeter") {
+			t.Error("expected IMPLEMENTS edge from Service to Greeter")
+		}
+		if !hasOutgoingEdge(t, result.Graph, "b.swift", "greet", EdgeTypeCalls, "name") {
+			t.Error("expected CALLS edge from greet to name via self")
+		}
+	})
+
+	t.Run("Swift methods are not associated with Go types", func(t *testing.T) {
+		result := buildMultiLanguageTestGraph(t, map[string]string{
+			"a.swift": `extension Store {
+    func flush() {}
+}
+`,
+			"main.go": `package main
+
+type Flusher interface {
+	flush()
+}
+
+type Store struct{}
+`,
+		})
+
+		if hasOutgoingEdge(t, result.Graph, "main.go", "Store", EdgeTypeImplements, "Flusher") {
+			t.Error("a Swift extension method must not make a Go type implement a Go interface")
+		}
+	})
+}
+
+func TestBuilder_KotlinInterfaceImplementation(t *testing.T) {
+	result := buildMultiLanguageTestGraph(t, map[string]string{
+		"a.kt": `package app
+
+interface Repository {
+    fun load(id: String): User?
+    suspend fun save(user: User)
+}
+`,
+		"b.kt": `package app
+
+open class Base
+
+class UserRepository : Base(), Repository {
+    override fun load(id: String): User? = null
+    override suspend fun save(user: User) { log() }
+    private fun log() {}
+}
+
+class CachedRepository {
+    fun load(id: String): User? = null
+    suspend fun save(user: User) {}
+}
+`,
+	})
+	g := result.Graph
+
+	if !hasOutgoingEdge(t, g, "b.kt", "UserRepository", EdgeTypeImplements, "Repository") {
+		t.Error("expected IMPLEMENTS edge from UserRepository to Repository")
+	}
+	if !hasOutgoingEdge(t, g, "b.kt", "CachedRepository", EdgeTypeImplements, "Repository") {
+		t.Error("expected implicit IMPLEMENTS edge from CachedRepository, whose methods cover Repository")
+	}
+	if !hasOutgoingEdge(t, g, "b.kt", "save", EdgeTypeCalls, "log") {
+		t.Error("expected CALLS edge from save to log")
+	}
+}
+
+func TestBuilder_PHPTraitsAndInheritance(t *testing.T) {
+	result := buildMultiLanguageTestGraph(t, map[string]string{
+		"a.php": `<?php
+namespace App;
+
+interface Auditable
+{
+    public function audit(): array;
+}
+
+interface Named
+{
+    public function name(): string;
+}
+
+trait Audits
+{
+    public function audit(): array { return $this->entries(); }
+    protected function entries(): array { return []; }
+}
+`,
+		"b.php": `<?php
+namespace App;
+
+abstract class Model
+{
+    public function save(): void {}
+}
+
+class User extends Model implements Named
+{
+    use Audits;
+
+    public function name(): string { return Str::upper('user'); }
+
+    public function save(): void
+    {
+        parent::save();
+        $this->audit();
+        self::boot();
+    }
+
+    public static function boot(): void {}
+}
+
+class Str
+{
+    public static function upper(string $s): string { return $s; }
+}
+`,
+	})
+	g := result.Graph
+
+	if !hasOutgoingEdge(t, g, "b.php", "User", EdgeTypeImplements, "Named") {
+		t.Error("expected IMPLEMENTS edge from User to Named")
+	}
+	if !hasOutgoingEdge(t, g, "b.php", "User", EdgeTypeEmbeds, "Model") {
+		t.Error("expected EMBEDS edge from User to its parent class Model")
+	}
+	if !hasOutgoingEdge(t, g, "b.php", "User", EdgeTypeEmbeds, "Audits") {
+		t.Error("expected EMBEDS edge from User to the trait Audits")
+	}
+	if !hasOutgoingEdge(t, g, "b.php", "User", EdgeTypeImplements, "Auditable") {
+		t.Error("expected implicit IMPLEMENTS edge from User to Auditable via the promoted trait method")
+	}
+
+	for _, want := range []struct{ from, to string }{
+		{"save", "audit"}, {"save", "boot"}, {"name", "upper"}, {"audit", "entries"},
+	} {
+		file := "b.php"
+		if want.from == "audit" {
+			file = "a.php"
+		}
+		if !hasOutgoingEdge(t, g, file, want.from, EdgeTypeCalls, want.to) {
+			t.Errorf("expected CALLS edge from %s to %s", want.from, want.to)
+		}
+	}
+
+	// parent::save() resolves to Model.save, not to User.save itself.
+	var userSave *Node
+	for _, node := range g.Nodes() {
+		if node.Symbol != nil && node.Symbol.Name == "save" && node.Symbol.Receiver == "User" {
+			userSave = node
+		}
+	}
+	if userSave == nil {
+		t.Fatal("User.save not found")
+	}
+	foundParent := false
+	for _, edge := range userSave.Outgoing {
+		if target, ok := g.GetNode(edge.ToID); ok && edge.Type == EdgeTypeCalls && target.Symbol != nil &&
+			target.Symbol.Name == "save" && target.Symbol.Receiver == "Model" {
+			foundParent = true
+		}
+	}
+	if !foundParent {
+		t.Error("expected CALLS edge from User.save to Model.save via parent::")
+	}
+}
+
+func TestBuilder_RubyMixinsAndReopenedClasses(t *testing.T) {
+	result := buildMultiLanguageTestGraph(t, map[string]string{
+		"a.rb": `module Auditing
+  def audit
+    self.entries
+  end
+
+  def entries
+    []
+  end
+end
+
+class Model
+  def save
+  end
+end
+
+class Invoice < Model
+  include Auditing
+
+  def save
+    super
+    self.audit
+    self.finalize
+  end
+end
+`,
+		"b.rb": `class Invoice
+  def finalize
+    self.audit
+  end
+
+  def self.lookup(id)
+  end
+end
+`,
+	})
+	g := result.Graph
+
+	if !hasOutgoingEdge(t, g, "a.rb", "Invoice", EdgeTypeEmbeds, "Model") {
+		t.Error("expected EMBEDS edge from Invoice to its superclass Model")
+	}
+	if !hasOutgoingEdge(t, g, "a.rb", "Invoice", EdgeTypeEmbeds, "Auditing") {
+		t.Error("expected EMBEDS edge from Invoice to the included module Auditing")
+	}
+
+	// self.audit resolves through the mixin, from both declarations of Invoice.
+	for _, want := range []struct{ file, from, to string }{
+		{"a.rb", "save", "audit"}, {"a.rb", "save", "finalize"}, {"b.rb", "finalize", "audit"}, {"a.rb", "audit", "entries"},
+	} {
+		if !hasOutgoingEdge(t, g, want.file, want.from, EdgeTypeCalls, want.to) {
+			t.Errorf("expected CALLS edge from %s to %s in %s", want.from, want.to, want.file)
+		}
+	}
+
+	// super resolves to Model#save, not to Invoice#save itself.
+	foundSuper := false
+	for _, node := range g.Nodes() {
+		if node.Symbol == nil || node.Symbol.Name != "save" || node.Symbol.Receiver != "Invoice" {
+			continue
+		}
+		for _, edge := range node.Outgoing {
+			if target, ok := g.GetNode(edge.ToID); ok && edge.Type == EdgeTypeCalls && target.Symbol != nil &&
+				target.Symbol.Name == "save" && target.Symbol.Receiver == "Model" {
+				foundSuper = true
+			}
+		}
+	}
+	if !foundSuper {
+		t.Error("expected CALLS edge from Invoice#save to Model#save via super")
+	}
+
+	// Each declaration of the reopened class carries the instance methods
+	// of both files; class methods are left out.
+	for _, file := range []string{"a.rb", "b.rb"} {
+		var invoice *ast.Symbol
+		for _, node := range g.Nodes() {
+			if node.Symbol != nil && node.Symbol.Name == "Invoice" && node.Symbol.FilePath == file {
+				invoice = node.Symbol
+			}
+		}
+		if invoice == nil || invoice.Metadata == nil {
+			t.Fatalf("Invoice in %s not found", file)
+		}
+		methods := make(map[string]bool)
+		for _, m := range invoice.Metadata.Methods {
+			methods[m.Name] = true
+		}
+		if !methods["save"] || !methods["finalize"] || methods["lookup"] {
+			t.Errorf("Invoice in %s methods = %+v, want save and finalize", file, invoice.Metadata.Methods)
+		}
+	}
+}
+
+// =============================================================================
+// Method call resolution through inferred local variable types
+// =============================================================================
+
+func TestBuilder_ResolveCallTarget_InferredLocalType(t *testing.T) {
+	files := map[string]string{
+		"app/app.go": `package app
+
+import "example.com/proj/b"
+
+type Server struct{}
+
+func (s *Server) Start() {}
+
+type Worker struct{}
+
+func (w *Worker) Start() {}
+
+func NewWorker() *Worker { return &Worker{} }
+
+func Run() {
+	w := NewWorker()
+	w.Start()
+}
+
+func Restart(srv *Worker) {
+	srv.Start()
+}
+
+func Remote() {
+	c, err := b.NewClient()
+	_ = err
+	c.Do()
+}
+
+func Guess(x thing) {
+	x.Start()
+}
+`,
+		"a/a.go": `package a
+
+type Client struct{}
+
+func (c *Client) Do() {}
+`,
+		"b/b.go": `package b
+
+type Client struct{}
+
+func (c *Client) Do() {}
+
+func NewClient() (*Client, error) { return &Client{}, nil }
+`,
+	}
+	ctx := context.Background()
+	var results []*ast.ParseResult
+	ids := make(map[string]string)
+	for _, path := range []string{"a/a.go", "app/app.go", "b/b.go"} {
+		parsed, err := ast.NewGoParser().Parse(ctx, []byte(files[path]), path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		results = append(results, parsed)
+		for _, sym := range parsed.Symbols {
+			ids[sym.Package+"."+sym.Receiver+"."+sym.Name] = sym.ID
+		}
+	}
+	result, err := NewBuilder().Build(ctx, results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	// callee returns the target of caller's call of a method (Start or Do).
+	callee := func(caller string) (string, EdgeProvenance) {
+		t.Helper()
+		node, ok := result.Graph.GetNode(ids[caller])
+		if !ok {
+			t.Fatalf("caller %s not found", caller)
+		}
+		for _, edge := range node.Outgoing {
+			if edge.Type != EdgeTypeCalls {
+				continue
+			}
+			if target, ok := result.Graph.GetNode(edge.ToID); ok && target.Symbol != nil &&
+				(target.Symbol.Name == "Start" || target.Symbol.Name == "Do") {
+				return edge.ToID, edge.Provenance
+			}
+		}
+		t.Fatalf("%s has no method call edge", caller)
+		return "", EdgeProvenance{}
+	}
+
+	for caller, want := range map[string]string{
+		"app..Run":     "app.Worker.Start",
+		"app..Restart": "app.Worker.Start",
+		"app..Remote":  "b.Client.Do",
+	} {
+		got, prov := callee(caller)
+		if got != ids[want] {
+			t.Errorf("%s calls %s, want %s", caller, got, ids[want])
+		}
+		if prov.Pass != PassCallInferredType || prov.Confidence != ConfidenceHigh {
+			t.Errorf("%s provenance = %+v, want call-inferred-type/high", caller, prov)
+		}
+	}
+	if _, prov := callee("app..Guess"); prov.Pass == PassCallInferredType {
+		t.Errorf("call on a variable of unknown type resolved by inferred type")
+	}
+
+	if result.Stats.CallEdgesByInferredType != 3 {
+		t.Errorf("CallEdgesByInferredType = %d, want 3", result.Stats.CallEdgesByInferredType)
+	}
+	if result.Stats.CallEdgesByHeuristic == 0 {
+		t.Error("CallEdgesByHeuristic = 0, want the Guess call counted")
+	}
+}
+
+func TestBuilder_ElixirProtocolImplementation(t *testing.T) {
+	result := buildMultiLanguageTestGraph(t, map[string]string{
+		"a.ex": `defprotocol Notifier do
+  def notify(target, msg)
+end
+
+defmodule Chat.Message do
+  def new(body), do: %{body: body}
+end
+
+defmodule Chat.Mailer do
+  def notify(target, msg), do: {target, msg}
+end
+`,
+		"b.ex": `defimpl Notifier, for: Chat.Message do
+  def notify(target, msg), do: Chat.Log.write(msg)
+end
+
+defmodule Chat.Log do
+  def write(msg), do: format(msg)
+  defp format(msg), do: msg
+end
+
+defmodule Chat.Room do
+  alias Chat.Message
+
+  def post(body) do
+    msg = Message.new(body)
+    Chat.Log.write(msg)
+    Enum.each([msg], &Chat.Log.write/1)
+  end
+end
+`,
+	})
+	g := result.Graph
+
+	if !hasOutgoingEdge(t, g, "b.ex", "Message", EdgeTypeImplements, "Notifier") {
+		t.Error("expected IMPLEMENTS edge from the defimpl for Chat.Message to Notifier")
+	}
+	// Protocols are explicit: defining notify/2 does not implement Notifier.
+	for _, from := range []string{"Message", "Mailer"} {
+		if hasOutgoingEdge(t, g, "a.ex", from, EdgeTypeImplements, "Notifier") {
+			t.Errorf("unexpected IMPLEMENTS edge from %s in a.ex", from)
+		}
+	}
+
+	// Remote calls resolve through the module name, after alias expansion.
+	for _, want := range []struct{ from, to, toFile string }{
+		{"post", "new", "a.ex"}, {"post", "write", "b.ex"}, {"notify", "write", "b.ex"}, {"write", "format", "b.ex"},
+	} {
+		found := false
+		for _, node := range g.Nodes() {
+			if node.Symbol == nil || node.Symbol.Name != want.from || node.Symbol.FilePath != "b.ex" {
+				continue
+			}
+			for _, edge := range node.Outgoing {
+				if target, ok := g.GetNode(edge.ToID); ok && edge.Type == EdgeTypeCalls && target.Symbol != nil &&
+					target.Symbol.Name == want.to && target.Symbol.FilePath == want.toFile {
+					found = true
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected CALLS edge from %s to %s in %s", want.from, want.to, want.toFile)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// resolveViaCallChain resolves a hop of a method call chain through the
+// return types of the hops before it.
+//
+// Description:
+//
+//	call.Chain is the root receiver, the methods called on it in order,
+//	and call.Target last (see ast.CallSite.Chain). The walk starts from
+//	the type of the root: the caller's class for this or self, the
+//	variable's declared or inferred type for Go (LocalTypes), or the
+//	return type of the function for a root call like NewClient(). Any
+//	other root resolves the first hop with the other strategies and
+//	continues from its return type. Each following hop is the method of
+//	that name on the current type or one of its parents, and its return
+//	type is the next type; a return type of this or Self keeps the
+//	current type, as fluent builders do. The walk gives up at the first
+//	hop whose type or method is unknown.
+//
+//	Every hop of a chain is its own call site with the chain up to it, so
+//	each resolvable hop gets its own CALLS edge.
+//
+// Inputs:
+//
+//	state - Build state with symbol indexes.
+//	call - The chained call site. call.Chain has at least three entries.
+//	caller - The calling function or method.
+//
+// Outputs:
+//
+//	string - The resolved symbol ID of call.Target, or empty if the walk
+//	  stopped.
+//	EdgeProvenance - PassCallChain, with the lowest confidence of the
+//	  hops walked.
+func (b *Builder) resolveViaCallChain(state *buildState, call ast.CallSite, caller *ast.Symbol) (string, EdgeProvenance) {
+	chain := call.Chain
+	if len(chain) < 3 {
+		return "", EdgeProvenance{}
+	}
+
+	confidence := ConfidenceHigh
+	typeName, next := b.chainRootType(state, chain[0], caller)
+	if typeName == "" {
+		// Resolve the first hop like a plain method call on the root.
+		first := ast.CallSite{Target: chain[1], Receiver: chain[0], IsMethod: true, Location: call.Location}
+		id, prov := b.resolveCallTargetWithProvenance(state, first, caller)
+		sym, ok := state.symbolsByID[id]
+		if !ok {
+			return "", EdgeProvenance{}
+		}
+		confidence = min(confidence, prov.Confidence)
+		typeName = chainReturnType(sym, b.findOwnerClassName(state, sym))
+		next = 2
+	}
+
+	for i := next; i < len(chain); i++ {
+		if typeName == "" {
+			return "", EdgeProvenance{}
+		}
+		matches := b.methodsOfType(state, typeName, chain[i], caller)
+		if len(matches) == 0 {
+			return "", EdgeProvenance{}
+		}
+		if len(matches) > 1 {
+			confidence = ConfidenceLow
+		}
+		if i == len(chain)-1 {
+			return matches[0], EdgeProvenance{Pass: PassCallChain, Confidence: confidence}
+		}
+		typeName = chainReturnType(state.symbolsByID[matches[0]], typeName)
+	}
+	return "", EdgeProvenance{}
+}
+
+// chainRootType returns the type of a chain's root receiver when it is
+// known without resolving a call on it, and the index of the first hop
+// to resolve on that type.
+//
+// Outputs:
+//
+//	string - The root's type, or empty if unknown.
+//	int - 1, the first hop.
+func (b *Builder) chainRootType(state *buildState, root string, caller *ast.Symbol) (string, int) {
+	switch {
+	case root == "this" || root == "self":
+		return b.findOwnerClassName(state, caller), 1
+	case strings.HasSuffix(root, "()"):
+		name := strings.TrimSuffix(root, "()")
+		for _, id := range b.resolveSymbolByName(state, name, caller.FilePath) {
+			sym, ok := state.symbolsByID[id]
+			if ok && sym.Kind == ast.SymbolKindFunction && sym.Language == caller.Language {
+				return chainReturnType(sym, ""), 1
+			}
+		}
+	case caller.Language == "go" && caller.Metadata != nil:
+		return caller.Metadata.LocalTypes[root], 1
+	}
+	return "", 1
+}
+
+// methodsOfType returns the methods named method of typeName or the
+// nearest of its parents that has one.
+//
+// Description:
+//
+//	A method belongs to a type when its receiver is the type (Go, and
+//	JavaScript class methods) or its parent symbol is the type (Python
+//	and TypeScript classes). A package-qualified Go type (store.User)
+//	only matches methods in that package; when several match an
+//	unqualified one, methods in the caller's directory are preferred.
+//
+// Outputs:
+//
+//	[]string - Matching symbol IDs, empty if the type has no such method
+//	  in the project.
+func (b *Builder) methodsOfType(state *buildState, typeName, method string, caller *ast.Symbol) []string {
+	pkg := ""
+	if caller.Language == "go" {
+		if dot := strings.LastIndex(typeName, "."); dot >= 0 {
+			pkg, typeName = typeName[:dot], typeName[dot+1:]
+		}
+	}
+	candidates := b.resolveAllSymbolsByName(state, method)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	callerDir := filepath.Dir(caller.FilePath)
+	for _, className := range b.buildInheritanceChain(state, typeName) {
+		var matches, local []string
+		for _, id := range candidates {
+			sym, ok := state.symbolsByID[id]
+			if !ok || sym.Language != caller.Language {
+				continue
+			}
+			if sym.Kind != ast.SymbolKindMethod && sym.Kind != ast.SymbolKindFunction && sym.Kind != ast.SymbolKindProperty {
+				continue
+			}
+			if pkg != "" && sym.Package != pkg {
+				continue
+			}
+			owner := sym.Receiver
+			if owner == "" {
+				if parentID, ok := state.symbolParent[id]; ok {
+					if parent, ok := state.symbolsByID[parentID]; ok {
+						owner = parent.Name
+					}
+				}
+			}
+			if owner != className {
+				continue
+			}
+			matches = append(matches, id)
+			if filepath.Dir(sym.FilePath) == callerDir {
+				local = append(local, id)
+			}
+		}
+		if pkg == "" && len(local) > 0 && len(local) < len(matches) {
+			return local
+		}
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+	return nil
+}
+
+// chainReturnType returns the type a call to sym evaluates to, for the
+// next hop of a chain.
+//
+// Description:
+//
+//	Uses Metadata.ReturnType, or for Go the first result in the
+//	signature. this, self and Self stand for receiverType, the type the
+//	method was called on. Pointers, slices and generic arguments are
+//	stripped; built-in types yield "".
+//
+// Inputs:
+//
+//	sym - The resolved hop. May be nil.
+//	receiverType - The type the hop was called on. Empty for functions.
+//
+// Outputs:
+//
+//	string - The type name, possibly package-qualified for Go, or ""
+//	  when unknown.
+func chainReturnType(sym *ast.Symbol, receiverType string) string {
+	if sym == nil {
+		return ""
+	}
+	returns := ""
+	if sym.Metadata != nil {
+		returns = sym.Metadata.ReturnType
+	}
+	if returns == "" && sym.Language == "go" {
+		returns = firstGoResult(extractReturnsFromSignature(sym.Signature))
+	}
+	returns = strings.Trim(strings.TrimSpace(returns), `"'`)
+	switch returns {
+	case "":
+		return ""
+	case "this", "self", "Self":
+		return receiverType
+	}
+	return extractTypeName(returns, sym.Language)
+}
+
+// firstGoResult returns the type of the first result of a Go result list
+// such as "*User, error" or "u *User, err error".
+func firstGoResult(results string) string {
+	end, depth := len(results), 0
+	for i, r := range results {
+		switch r {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 && end == len(results) {
+				end = i
+			}
+		}
+	}
+	first := strings.TrimSpace(results[:end])
+	// Named result: "u *User" → "*User".
+	if name, typ, ok := strings.Cut(first, " "); ok && !strings.ContainsAny(name, "*[]().") && name != "chan" && name != "func" {
+		return strings.TrimSpace(typ)
+	}
+	return first
+}
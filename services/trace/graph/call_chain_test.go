@@ -0,0 +1,194 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// buildChainTestGraph parses one file with parser and builds its graph.
+func buildChainTestGraph(t *testing.T, parser ast.Parser, path, src string) (*BuildResult, map[string]string) {
+	t.Helper()
+	ctx := context.Background()
+	parsed, err := parser.Parse(ctx, []byte(src), path)
+	if err != nil {
+		t.Fatalf("parse %s: %v", path, err)
+	}
+	ids := make(map[string]string)
+	var collect func(syms []*ast.Symbol, owner string)
+	collect = func(syms []*ast.Symbol, owner string) {
+		for _, sym := range syms {
+			recv := sym.Receiver
+			if recv == "" {
+				recv = owner
+			}
+			ids[recv+"."+sym.Name] = sym.ID
+			collect(sym.Children, sym.Name)
+		}
+	}
+	collect(parsed.Symbols, "")
+	result, err := NewBuilder().Build(ctx, []*ast.ParseResult{parsed})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return result, ids
+}
+
+// callEdge returns the provenance of the CALLS edge from caller to
+// callee, failing the test if there is none.
+func callEdge(t *testing.T, result *BuildResult, callerID, calleeID string) EdgeProvenance {
+	t.Helper()
+	node, ok := result.Graph.GetNode(callerID)
+	if !ok {
+		t.Fatalf("caller %s not found", callerID)
+	}
+	for _, edge := range node.Outgoing {
+		if edge.Type == EdgeTypeCalls && edge.ToID == calleeID {
+			return edge.Provenance
+		}
+	}
+	var got []string
+	for _, edge := range node.Outgoing {
+		if edge.Type == EdgeTypeCalls {
+			got = append(got, edge.ToID)
+		}
+	}
+	t.Fatalf("%s does not call %s; calls %v", callerID, calleeID, got)
+	return EdgeProvenance{}
+}
+
+func TestBuilder_GoFluentCallChain(t *testing.T) {
+	result, ids := buildChainTestGraph(t, ast.NewGoParser(), "api/api.go", `package api
+
+import "example.com/external"
+
+type Client struct{}
+type Users struct{}
+type User struct{}
+type Profile struct{}
+
+func NewClient() *Client { return &Client{} }
+
+func (c *Client) Users() *Users { return &Users{} }
+
+func (u *Users) Get(id string) *User { return &User{} }
+
+func (u *User) Profile() *Profile { return &Profile{} }
+
+type Orders struct{}
+type Order struct{}
+
+func (o *Orders) Get(id string) *Order { return &Order{} }
+
+func (o *Order) Profile() *Profile { return &Profile{} }
+
+func Show(c *Client) {
+	c.Users().Get("1").Profile()
+}
+
+func ShowNew() {
+	NewClient().Users().Get("2")
+}
+
+func Unknown(x external.Thing) {
+	x.Users().Get("3").Profile()
+}
+`)
+
+	callEdge(t, result, ids[".Show"], ids["Client.Users"])
+	for _, hop := range []string{"Users.Get", "User.Profile"} {
+		prov := callEdge(t, result, ids[".Show"], ids[hop])
+		if prov.Pass != PassCallChain || prov.Confidence != ConfidenceHigh {
+			t.Errorf("Show → %s provenance = %+v, want call-chain/high", hop, prov)
+		}
+	}
+	if prov := callEdge(t, result, ids[".ShowNew"], ids["Users.Get"]); prov.Pass != PassCallChain {
+		t.Errorf("ShowNew → Users.Get provenance = %+v, want call-chain", prov)
+	}
+
+	// x's type is outside the project: the walk stops at the first hop
+	// and the hops are left to the other strategies.
+	node, _ := result.Graph.GetNode(ids[".Unknown"])
+	for _, edge := range node.Outgoing {
+		if edge.Provenance.Pass == PassCallChain {
+			t.Errorf("Unknown has call-chain edge to %s", edge.ToID)
+		}
+	}
+
+	if result.Stats.ChainHopsResolved != 3 {
+		t.Errorf("ChainHopsResolved = %d, want 3", result.Stats.ChainHopsResolved)
+	}
+	if result.Stats.ChainHopsAbandoned != 2 {
+		t.Errorf("ChainHopsAbandoned = %d, want 2", result.Stats.ChainHopsAbandoned)
+	}
+}
+
+func TestBuilder_ExpressStyleCallChain(t *testing.T) {
+	result, ids := buildChainTestGraph(t, ast.NewJavaScriptParser(), "app.js", `
+class Route {
+  constructor(path) { this.path = path; this.stack = []; }
+  get(handler) { this.stack.push(handler); return this; }
+  post(handler) { this.stack.push(handler); return this; }
+}
+
+class Application {
+  route(path) { return new Route(path); }
+}
+
+class Cache {
+  get(key) { return null; }
+  post(key) { return null; }
+}
+
+function handler(req, res) {}
+
+function setup(app) {
+  app.route('/x').get(handler).post(handler);
+}
+`)
+
+	callEdge(t, result, ids[".setup"], ids["Application.route"])
+	for _, hop := range []string{"Route.get", "Route.post"} {
+		if prov := callEdge(t, result, ids[".setup"], ids[hop]); prov.Pass != PassCallChain {
+			t.Errorf("setup → %s provenance = %+v, want call-chain", hop, prov)
+		}
+	}
+	if result.Stats.ChainHopsResolved != 2 || result.Stats.ChainHopsAbandoned != 0 {
+		t.Errorf("chain hops resolved/abandoned = %d/%d, want 2/0",
+			result.Stats.ChainHopsResolved, result.Stats.ChainHopsAbandoned)
+	}
+}
+
+func TestChainReturnType(t *testing.T) {
+	tests := []struct {
+		name     string
+		sym      *ast.Symbol
+		receiver string
+		want     string
+	}{
+		{"go pointer", &ast.Symbol{Language: "go", Signature: "func (c *Client) Users() *Users"}, "Client", "Users"},
+		{"go first of several", &ast.Symbol{Language: "go", Signature: "func Open(path string) (*store.DB, error)"}, "", "store.DB"},
+		{"go named", &ast.Symbol{Language: "go", Signature: "func Open() (db *DB, err error)"}, "", "DB"},
+		{"go builtin", &ast.Symbol{Language: "go", Signature: "func (c *Client) Name() string"}, "Client", ""},
+		{"ts this", &ast.Symbol{Language: "typescript", Metadata: &ast.SymbolMetadata{ReturnType: "this"}}, "QueryBuilder", "QueryBuilder"},
+		{"python forward ref", &ast.Symbol{Language: "python", Metadata: &ast.SymbolMetadata{ReturnType: `"Query"`}}, "Session", "Query"},
+		{"python Self", &ast.Symbol{Language: "python", Metadata: &ast.SymbolMetadata{ReturnType: "Self"}}, "Query", "Query"},
+		{"unknown", &ast.Symbol{Language: "javascript"}, "Route", ""},
+	}
+	for _, tt := range tests {
+		if got := chainReturnType(tt.sym, tt.receiver); got != tt.want {
+			t.Errorf("%s: chainReturnType = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
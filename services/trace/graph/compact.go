@@ -0,0 +1,326 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// CompactResult contains the outcome of a graph compaction.
+//
+// Thread Safety: Immutable after construction.
+type CompactResult struct {
+	// Graph is the compacted graph (frozen, read-only).
+	Graph *Graph
+
+	// PlaceholdersResolved is the number of placeholder nodes matched to a
+	// real symbol. Their edges were rewritten and the placeholders removed.
+	PlaceholdersResolved int
+
+	// EdgesRewritten is the number of edges re-pointed from a placeholder
+	// to the real symbol it resolved to.
+	EdgesRewritten int
+
+	// DuplicateEdgesRemoved is the number of edges removed because an
+	// identical edge (same source, target, type and location) remained.
+	DuplicateEdgesRemoved int
+
+	// PlaceholdersRemaining is the number of placeholder nodes left.
+	PlaceholdersRemaining int
+
+	// DurationMilli is the total time for the compaction.
+	DurationMilli int64
+}
+
+// Changed reports whether compaction modified the graph.
+func (r *CompactResult) Changed() bool {
+	return r.PlaceholdersResolved > 0 || r.DuplicateEdgesRemoved > 0
+}
+
+// Compact resolves placeholder nodes and removes duplicate edges.
+//
+// Description:
+//
+//	Incremental refreshes resolve edges only for changed files, so
+//	placeholders created for symbols that did not exist yet (a package
+//	that was later vendored, a type added in another file) stay in the
+//	graph, and re-resolution can add an edge that already exists.
+//	Compact works on a clone of the base graph:
+//	  1. Each placeholder is matched by name against the real symbols.
+//	     A placeholder with a package only matches symbols in that
+//	     package; one without only matches a name that is unique in the
+//	     graph. Ambiguous or kind-incompatible matches are left alone.
+//	  2. Edges into a resolved placeholder are re-pointed to the real
+//	     symbol and the placeholder is removed.
+//	  3. Identical edges are collapsed to one.
+//	  4. The clone is frozen.
+//	The base graph is not modified, so callers can install the result
+//	atomically in place of it.
+//
+// Inputs:
+//   - ctx: Context for cancellation and tracing. Must not be nil.
+//   - baseGraph: The graph to compact (frozen). Must not be nil.
+//
+// Outputs:
+//   - *CompactResult: The compacted graph and what changed.
+//   - error: Non-nil if the context is cancelled or an edge cannot be
+//     rewritten.
+//
+// Thread Safety: Safe for concurrent use (creates a new graph via Clone).
+func Compact(ctx context.Context, baseGraph *Graph) (*CompactResult, error) {
+	if ctx == nil {
+		return nil, fmt.Errorf("ctx must not be nil")
+	}
+	if baseGraph == nil {
+		return nil, fmt.Errorf("base graph must not be nil")
+	}
+
+	ctx, span := incrementalTracer.Start(ctx, "graph.Compact",
+		trace.WithAttributes(
+			attribute.Int("base_nodes", baseGraph.NodeCount()),
+			attribute.Int("base_edges", baseGraph.EdgeCount()),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	working := baseGraph.Clone()
+	result := &CompactResult{}
+
+	// Phase 1: Resolve placeholders against the current symbols, in ID
+	// order so that the outcome does not depend on map iteration.
+	var placeholders []*Node
+	for _, node := range working.nodes {
+		if isPlaceholderNode(node) {
+			placeholders = append(placeholders, node)
+		}
+	}
+	sort.Slice(placeholders, func(i, j int) bool {
+		return placeholders[i].ID < placeholders[j].ID
+	})
+
+	touched := make(map[string]struct{})
+	for _, placeholder := range placeholders {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("compaction cancelled: %w", err)
+		}
+		target := resolvePlaceholder(working, placeholder)
+		if target == nil {
+			result.PlaceholdersRemaining++
+			continue
+		}
+		incoming := append([]*Edge(nil), placeholder.Incoming...)
+		for _, edge := range incoming {
+			if err := working.ReplaceEdgeTarget(edge, target.ID); err != nil {
+				return nil, fmt.Errorf("rewriting edge %s -> %s: %w", edge.FromID, edge.ToID, err)
+			}
+			edge.Provenance = retargetProvenance(PassCompaction, ConfidenceMedium, edge.Provenance)
+			touched[edge.FromID] = struct{}{}
+			result.EdgesRewritten++
+		}
+		touched[target.ID] = struct{}{}
+		for _, edge := range placeholder.Outgoing {
+			touched[edge.ToID] = struct{}{}
+		}
+		if err := working.RemoveNode(placeholder.ID); err != nil {
+			return nil, fmt.Errorf("removing placeholder %s: %w", placeholder.ID, err)
+		}
+		result.PlaceholdersResolved++
+	}
+
+	// Phase 2: Collapse identical edges, including those produced by
+	// the rewrites above.
+	result.DuplicateEdgesRemoved = working.removeDuplicateEdges(touched)
+
+	// Metadata recorded after the base graph was frozen is not part of
+	// Clone; carry it over, dropping resolved placeholders' packages.
+	working.FileMtimes = baseGraph.FileMtimes
+	working.FileHashes = baseGraph.FileHashes
+	working.FileOwners = baseGraph.FileOwners
+	working.ownersOf = baseGraph.ownersOf
+	working.annotations = baseGraph.annotations
+	if baseGraph.ExternalPackages != nil {
+		working.ExternalPackages = make(map[string]ExternalPackage, len(baseGraph.ExternalPackages))
+		for id, pkg := range baseGraph.ExternalPackages {
+			if _, ok := working.nodes[id]; ok {
+				working.ExternalPackages[id] = pkg
+			}
+		}
+	}
+
+	working.Freeze()
+	if baseGraph.QualityReport != nil {
+		working.QualityReport = NewQualityReport(working, baseGraph.QualityReport.ParseErrors.Files)
+	}
+	touchedIDs := make([]string, 0, len(touched))
+	for id := range touched {
+		touchedIDs = append(touchedIDs, id)
+	}
+	report, inRegion := working.validateTouched(nil, touchedIDs)
+	working.SetValidation(report.carry(baseGraph.Validation(), inRegion))
+
+	result.Graph = working
+	result.DurationMilli = time.Since(start).Milliseconds()
+
+	span.SetAttributes(
+		attribute.Int("placeholders_resolved", result.PlaceholdersResolved),
+		attribute.Int("edges_rewritten", result.EdgesRewritten),
+		attribute.Int("duplicate_edges_removed", result.DuplicateEdgesRemoved),
+		attribute.Int64("duration_ms", result.DurationMilli),
+	)
+
+	slog.Info("Graph compaction complete",
+		slog.Int("placeholders_resolved", result.PlaceholdersResolved),
+		slog.Int("placeholders_remaining", result.PlaceholdersRemaining),
+		slog.Int("edges_rewritten", result.EdgesRewritten),
+		slog.Int("duplicate_edges_removed", result.DuplicateEdgesRemoved),
+		slog.Int64("duration_ms", result.DurationMilli),
+	)
+
+	return result, nil
+}
+
+// resolvePlaceholder finds the single real symbol a placeholder stands for.
+//
+// Description:
+//
+//	Dotted call targets ("pkg.Func", "obj.method") are matched on their
+//	last segment. Placeholders for imported packages (name equal to the
+//	package path) and placeholders with outgoing edges are never resolved.
+//	Every incoming call edge must be able to target the candidate's kind.
+//
+// Outputs:
+//
+//	*Node - The real node, or nil if there is no unambiguous match.
+func resolvePlaceholder(g *Graph, placeholder *Node) *Node {
+	sym := placeholder.Symbol
+	if sym.Name == "" || sym.Name == sym.Package || len(placeholder.Outgoing) > 0 {
+		return nil
+	}
+	name := sym.Name
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	var match *Node
+	for _, candidate := range g.nodesByName[name] {
+		if isPlaceholderNode(candidate) || candidate.Symbol == nil {
+			continue
+		}
+		if sym.Package != "" && !symbolInPackage(candidate.Symbol, sym.Package) {
+			continue
+		}
+		if match != nil {
+			return nil
+		}
+		match = candidate
+	}
+	if match == nil {
+		return nil
+	}
+	for _, edge := range placeholder.Incoming {
+		if edge.Type == EdgeTypeCalls && !isCallTarget(match.Symbol.Kind) {
+			return nil
+		}
+	}
+	return match
+}
+
+// symbolInPackage reports whether sym belongs to pkg: its declared
+// package, or the directory or module file an import path ("github.com/x/auth",
+// possibly vendored) or a Python dotted path ("app.models") names.
+func symbolInPackage(sym *ast.Symbol, pkg string) bool {
+	if sym.Package == pkg {
+		return true
+	}
+	if sym.FilePath == "" {
+		return false
+	}
+	dir := path.Dir(sym.FilePath)
+	module := strings.TrimSuffix(sym.FilePath, path.Ext(sym.FilePath))
+	for _, p := range []string{pkg, strings.ReplaceAll(pkg, ".", "/")} {
+		if hasPathSuffix(dir, p) || hasPathSuffix(module, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPathSuffix reports whether s is suffix or ends with "/"+suffix.
+func hasPathSuffix(s, suffix string) bool {
+	return s == suffix || strings.HasSuffix(s, "/"+suffix)
+}
+
+// edgeKey identifies an edge by everything but its identity.
+type edgeKey struct {
+	from, to string
+	typ      EdgeType
+	loc      ast.Location
+}
+
+// removeDuplicateEdges removes edges identical to an earlier edge and
+// returns how many were removed. The endpoints of removed edges are added
+// to touched.
+//
+// Thread Safety: NOT safe for concurrent use. Must be called during build
+// phase only.
+func (g *Graph) removeDuplicateEdges(touched map[string]struct{}) int {
+	seen := make(map[edgeKey]struct{}, len(g.edges))
+	duplicates := make(map[*Edge]struct{})
+	kept := g.edges[:0]
+	for _, e := range g.edges {
+		key := edgeKey{from: e.FromID, to: e.ToID, typ: e.Type, loc: e.Location}
+		if _, ok := seen[key]; ok {
+			duplicates[e] = struct{}{}
+			touched[e.FromID] = struct{}{}
+			touched[e.ToID] = struct{}{}
+			continue
+		}
+		seen[key] = struct{}{}
+		kept = append(kept, e)
+	}
+	if len(duplicates) == 0 {
+		return 0
+	}
+	g.edges = kept
+
+	without := func(edges []*Edge) []*Edge {
+		out := edges[:0]
+		for _, e := range edges {
+			if _, dup := duplicates[e]; !dup {
+				out = append(out, e)
+			}
+		}
+		return out
+	}
+	for _, node := range g.nodes {
+		node.Outgoing = without(node.Outgoing)
+		node.Incoming = without(node.Incoming)
+	}
+	for i := range g.edgesByType {
+		g.edgesByType[i] = without(g.edgesByType[i])
+	}
+	for file, edges := range g.edgesByFile {
+		g.edgesByFile[file] = without(edges)
+	}
+	return len(duplicates)
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+func TestCompact(t *testing.T) {
+	g := NewGraph("/proj")
+	add := func(sym *ast.Symbol) {
+		t.Helper()
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode(%s): %v", sym.ID, err)
+		}
+	}
+	edge := func(from, to string, line int) {
+		t.Helper()
+		loc := ast.Location{FilePath: "app/main.go", StartLine: line}
+		if err := g.AddEdge(from, to, EdgeTypeCalls, loc); err != nil {
+			t.Fatalf("AddEdge(%s, %s): %v", from, to, err)
+		}
+	}
+
+	add(&ast.Symbol{ID: "app/main.go:3:run", Name: "run", Kind: ast.SymbolKindFunction, Package: "main", FilePath: "app/main.go"})
+	// A package that was external when run's edges were extracted and
+	// has since been vendored.
+	add(&ast.Symbol{ID: "vendor/github.com/acme/log/log.go:5:Info", Name: "Info", Kind: ast.SymbolKindFunction, Package: "log", FilePath: "vendor/github.com/acme/log/log.go"})
+	add(&ast.Symbol{ID: "external:github.com/acme/log:log.Info", Name: "log.Info", Kind: ast.SymbolKindExternal, Package: "github.com/acme/log"})
+	// An unqualified name matching two symbols stays a placeholder.
+	add(&ast.Symbol{ID: "a/h.go:1:helper", Name: "helper", Kind: ast.SymbolKindFunction, Package: "a", FilePath: "a/h.go"})
+	add(&ast.Symbol{ID: "b/h.go:1:helper", Name: "helper", Kind: ast.SymbolKindFunction, Package: "b", FilePath: "b/h.go"})
+	add(&ast.Symbol{ID: "external::helper", Name: "helper", Kind: ast.SymbolKindExternal})
+
+	edge("app/main.go:3:run", "external:github.com/acme/log:log.Info", 4)
+	edge("app/main.go:3:run", "external:github.com/acme/log:log.Info", 6)
+	edge("app/main.go:3:run", "external::helper", 7)
+	// Already resolved by an earlier refresh; duplicates the rewrite of line 4.
+	edge("app/main.go:3:run", "vendor/github.com/acme/log/log.go:5:Info", 4)
+	// Plain duplicate.
+	edge("app/main.go:3:run", "a/h.go:1:helper", 8)
+	edge("app/main.go:3:run", "a/h.go:1:helper", 8)
+	g.Freeze()
+	baseEdges, baseNodes := g.EdgeCount(), g.NodeCount()
+
+	result, err := Compact(context.Background(), g)
+	if err != nil {
+		t.Fatalf("Compact: %v", err)
+	}
+
+	if result.PlaceholdersResolved != 1 || result.PlaceholdersRemaining != 1 {
+		t.Errorf("placeholders resolved %d remaining %d, want 1 and 1", result.PlaceholdersResolved, result.PlaceholdersRemaining)
+	}
+	if result.EdgesRewritten != 2 {
+		t.Errorf("EdgesRewritten = %d, want 2", result.EdgesRewritten)
+	}
+	if result.DuplicateEdgesRemoved != 2 {
+		t.Errorf("DuplicateEdgesRemoved = %d, want 2", result.DuplicateEdgesRemoved)
+	}
+
+	compacted := result.Graph
+	if !compacted.IsFrozen() {
+		t.Error("compacted graph should be frozen")
+	}
+	if _, ok := compacted.GetNode("external:github.com/acme/log:log.Info"); ok {
+		t.Error("resolved placeholder should be removed")
+	}
+	if _, ok := compacted.GetNode("external::helper"); !ok {
+		t.Error("ambiguous placeholder should remain")
+	}
+	info, _ := compacted.GetNode("vendor/github.com/acme/log/log.go:5:Info")
+	if len(info.Incoming) != 2 {
+		t.Errorf("Info has %d incoming edges, want 2", len(info.Incoming))
+	}
+	if compacted.EdgeCount() != baseEdges-2 || len(compacted.GetEdgesByType(EdgeTypeCalls)) != baseEdges-2 {
+		t.Errorf("EdgeCount = %d, want %d", compacted.EdgeCount(), baseEdges-2)
+	}
+	if n := len(compacted.GetEdgesByFile("app/main.go")); n != baseEdges-2 {
+		t.Errorf("edgesByFile has %d edges, want %d", n, baseEdges-2)
+	}
+
+	// The base graph is untouched.
+	if g.EdgeCount() != baseEdges || g.NodeCount() != baseNodes {
+		t.Errorf("base graph modified: %d nodes, %d edges", g.NodeCount(), g.EdgeCount())
+	}
+
+	again, err := Compact(context.Background(), compacted)
+	if err != nil {
+		t.Fatalf("second Compact: %v", err)
+	}
+	if again.Changed() {
+		t.Errorf("second compaction changed the graph: %+v", again)
+	}
+}
+
+func TestCompact_NilInputs(t *testing.T) {
+	if _, err := Compact(context.Background(), nil); err == nil {
+		t.Error("expected error for nil base graph")
+	}
+}
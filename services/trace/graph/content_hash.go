@@ -0,0 +1,165 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// ContentHash returns a SHA-256 hash of the graph's canonical content.
+//
+// Description:
+//
+//	Unlike Hash, which covers only node IDs and (from, to, type) tuples,
+//	ContentHash also covers each node's symbol (name, kind, file, position,
+//	signature, receiver, package, language, exported, doc comment) and each
+//	edge's location. Nodes are hashed in ID order and edges in a total
+//	order over all hashed fields, so the result does not depend on the
+//	order in which nodes and edges were added.
+//
+//	Excluded: BuiltAtMilli, FileMtimes, FileHashes, ProjectRoot, Symbol.ParsedAtMilli
+//	and symbol metadata. Identical source therefore yields the same hash
+//	regardless of when or where it was built, or with how many workers.
+//
+// Outputs:
+//
+//	string - Hex-encoded SHA-256 (64 characters). Empty string if g is nil.
+//
+// Complexity:
+//
+//	O(V log V + E log E).
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) ContentHash() string {
+	if g == nil {
+		return ""
+	}
+
+	h := sha256.New()
+	w := contentHashWriter{h: h}
+
+	nodeIDs := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		nodeIDs = append(nodeIDs, id)
+	}
+	sort.Strings(nodeIDs)
+
+	w.int(int64(len(nodeIDs)))
+	for _, id := range nodeIDs {
+		w.str(id)
+		w.symbol(g.nodes[id].Symbol)
+	}
+
+	edges := make([]*Edge, len(g.edges))
+	copy(edges, g.edges)
+	sort.Slice(edges, func(i, j int) bool {
+		return edgeContentLess(edges[i], edges[j])
+	})
+
+	w.int(int64(len(edges)))
+	for _, e := range edges {
+		w.str(e.FromID)
+		w.str(e.ToID)
+		w.int(int64(e.Type))
+		w.location(e.Location)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// edgeContentLess orders edges by every field ContentHash covers.
+func edgeContentLess(a, b *Edge) bool {
+	if a.FromID != b.FromID {
+		return a.FromID < b.FromID
+	}
+	if a.ToID != b.ToID {
+		return a.ToID < b.ToID
+	}
+	if a.Type != b.Type {
+		return a.Type < b.Type
+	}
+	la, lb := a.Location, b.Location
+	if la.FilePath != lb.FilePath {
+		return la.FilePath < lb.FilePath
+	}
+	if la.StartLine != lb.StartLine {
+		return la.StartLine < lb.StartLine
+	}
+	if la.StartCol != lb.StartCol {
+		return la.StartCol < lb.StartCol
+	}
+	if la.EndLine != lb.EndLine {
+		return la.EndLine < lb.EndLine
+	}
+	return la.EndCol < lb.EndCol
+}
+
+// contentHashWriter writes length-prefixed fields so that adjacent values
+// cannot run together ("ab"+"c" vs "a"+"bc").
+type contentHashWriter struct {
+	h   hash.Hash
+	buf [binary.MaxVarintLen64]byte
+}
+
+func (w *contentHashWriter) int(v int64) {
+	n := binary.PutVarint(w.buf[:], v)
+	w.h.Write(w.buf[:n])
+}
+
+func (w *contentHashWriter) str(s string) {
+	w.int(int64(len(s)))
+	w.h.Write([]byte(s))
+}
+
+func (w *contentHashWriter) bool(b bool) {
+	if b {
+		w.int(1)
+		return
+	}
+	w.int(0)
+}
+
+func (w *contentHashWriter) location(loc ast.Location) {
+	w.str(loc.FilePath)
+	w.int(int64(loc.StartLine))
+	w.int(int64(loc.EndLine))
+	w.int(int64(loc.StartCol))
+	w.int(int64(loc.EndCol))
+}
+
+func (w *contentHashWriter) symbol(sym *ast.Symbol) {
+	if sym == nil {
+		w.bool(false)
+		return
+	}
+	w.bool(true)
+	w.str(sym.Name)
+	w.int(int64(sym.Kind))
+	w.str(sym.FilePath)
+	w.int(int64(sym.StartLine))
+	w.int(int64(sym.EndLine))
+	w.int(int64(sym.StartCol))
+	w.int(int64(sym.EndCol))
+	w.str(sym.Signature)
+	w.str(sym.DocComment)
+	w.str(sym.Receiver)
+	w.str(sym.Package)
+	w.str(sym.Language)
+	w.bool(sym.Exported)
+}
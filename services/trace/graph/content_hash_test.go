@@ -0,0 +1,125 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+func contentHashFixture(reverse bool, parsedAt int64) *Graph {
+	syms := []*ast.Symbol{
+		testSymbol("A", ast.SymbolKindFunction, "a.go", 1),
+		testSymbol("B", ast.SymbolKindFunction, "b.go", 1),
+		testSymbol("C", ast.SymbolKindFunction, "c.go", 1),
+	}
+	type edge struct{ from, to int }
+	edges := []edge{{0, 1}, {1, 2}, {0, 2}}
+
+	g := NewGraph("/test")
+	for i := range syms {
+		if reverse {
+			i = len(syms) - 1 - i
+		}
+		syms[i].ParsedAtMilli = parsedAt
+		g.AddNode(syms[i])
+	}
+	for i := range edges {
+		if reverse {
+			i = len(edges) - 1 - i
+		}
+		e := edges[i]
+		loc := ast.Location{FilePath: syms[e.from].FilePath, StartLine: 2 + i}
+		if err := g.AddEdge(syms[e.from].ID, syms[e.to].ID, EdgeTypeCalls, loc); err != nil {
+			panic(err)
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+func TestGraph_ContentHash(t *testing.T) {
+	base := contentHashFixture(false, 1000)
+	hash := base.ContentHash()
+	if len(hash) != 64 {
+		t.Fatalf("expected 64-char hex hash, got %q", hash)
+	}
+
+	t.Run("stable across calls", func(t *testing.T) {
+		if got := base.ContentHash(); got != hash {
+			t.Errorf("ContentHash changed between calls: %s vs %s", got, hash)
+		}
+	})
+
+	t.Run("independent of insertion order", func(t *testing.T) {
+		if got := contentHashFixture(true, 1000).ContentHash(); got != hash {
+			t.Errorf("reversed insertion changed hash: %s vs %s", got, hash)
+		}
+	})
+
+	t.Run("excludes timestamps", func(t *testing.T) {
+		g := contentHashFixture(false, 999999)
+		g.BuiltAtMilli = base.BuiltAtMilli + 5000
+		if got := g.ContentHash(); got != hash {
+			t.Errorf("timestamps changed hash: %s vs %s", got, hash)
+		}
+	})
+
+	t.Run("covers symbol content", func(t *testing.T) {
+		g := NewGraph("/test")
+		for _, sym := range []*ast.Symbol{
+			testSymbol("A", ast.SymbolKindFunction, "a.go", 1),
+			testSymbol("B", ast.SymbolKindFunction, "b.go", 1),
+			testSymbol("C", ast.SymbolKindFunction, "c.go", 1),
+		} {
+			sym.Signature = "func()"
+			g.AddNode(sym)
+		}
+		g.Freeze()
+		if g.ContentHash() == contentHashFixture(false, 1000).ContentHash() {
+			t.Error("signature change did not change hash")
+		}
+	})
+
+	t.Run("covers edge locations", func(t *testing.T) {
+		a := contentHashFixture(false, 1000)
+		b := contentHashFixture(false, 1000)
+		if a.Hash() != b.Hash() || a.ContentHash() != b.ContentHash() {
+			t.Fatal("identical fixtures must hash equally")
+		}
+		moved := NewGraph("/test")
+		for _, n := range a.Nodes() {
+			moved.AddNode(n.Symbol)
+		}
+		for _, e := range a.Edges() {
+			loc := e.Location
+			loc.StartLine += 100
+			if err := moved.AddEdge(e.FromID, e.ToID, e.Type, loc); err != nil {
+				t.Fatal(err)
+			}
+		}
+		moved.Freeze()
+		if moved.Hash() != a.Hash() {
+			t.Fatal("structural hash should ignore locations")
+		}
+		if moved.ContentHash() == a.ContentHash() {
+			t.Error("edge location change did not change ContentHash")
+		}
+	})
+
+	t.Run("nil graph", func(t *testing.T) {
+		var g *Graph
+		if got := g.ContentHash(); got != "" {
+			t.Errorf("expected empty hash for nil graph, got %q", got)
+		}
+	})
+}
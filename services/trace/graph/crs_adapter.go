@@ -26,6 +26,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -225,19 +226,19 @@ func (a *CRSGraphAdapter) InvalidateCache() {
 func (a *CRSGraphAdapter) FindSymbolByID(ctx context.Context, id string) (*ast.Symbol, bool, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.FindSymbolByID",
 		trace.WithAttributes(
-			attribute.String("symbol_id", id),
+			telemetry.SensitiveString("symbol_id", id),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, false, err
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, false, err
 	}
@@ -272,19 +273,19 @@ func (a *CRSGraphAdapter) FindSymbolByID(ctx context.Context, id string) (*ast.S
 func (a *CRSGraphAdapter) FindSymbolsByName(ctx context.Context, name string) ([]*ast.Symbol, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.FindSymbolsByName",
 		trace.WithAttributes(
-			attribute.String("name", name),
+			telemetry.SensitiveString("name", name),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, err
 	}
@@ -323,13 +324,13 @@ func (a *CRSGraphAdapter) FindSymbolsByKind(ctx context.Context, kind ast.Symbol
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, err
 	}
@@ -360,19 +361,19 @@ func (a *CRSGraphAdapter) FindSymbolsByKind(ctx context.Context, kind ast.Symbol
 func (a *CRSGraphAdapter) FindSymbolsInFile(ctx context.Context, filePath string) ([]*ast.Symbol, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.FindSymbolsInFile",
 		trace.WithAttributes(
-			attribute.String("file_path", filePath),
+			telemetry.SensitiveString("file_path", filePath),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, err
 	}
@@ -419,19 +420,19 @@ func (a *CRSGraphAdapter) FindSymbolsInFile(ctx context.Context, filePath string
 func (a *CRSGraphAdapter) FindCallers(ctx context.Context, symbolID string) ([]*ast.Symbol, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.FindCallers",
 		trace.WithAttributes(
-			attribute.String("symbol_id", symbolID),
+			telemetry.SensitiveString("symbol_id", symbolID),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
@@ -483,8 +484,8 @@ func (a *CRSGraphAdapter) FindCallers(ctx context.Context, symbolID string) ([]*
 	})
 
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("finding callers for %s: %w", symbolID, err)
 	}
 
@@ -492,8 +493,8 @@ func (a *CRSGraphAdapter) FindCallers(ctx context.Context, symbolID string) ([]*
 	result, ok := resultI.(*QueryResult)
 	if !ok {
 		err := fmt.Errorf("unexpected type from singleflight group 'callersGroup': got %T", resultI)
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 	span.SetAttributes(
@@ -531,19 +532,19 @@ func (a *CRSGraphAdapter) FindCallers(ctx context.Context, symbolID string) ([]*
 func (a *CRSGraphAdapter) FindCallees(ctx context.Context, symbolID string) ([]*ast.Symbol, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.FindCallees",
 		trace.WithAttributes(
-			attribute.String("symbol_id", symbolID),
+			telemetry.SensitiveString("symbol_id", symbolID),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
@@ -595,8 +596,8 @@ func (a *CRSGraphAdapter) FindCallees(ctx context.Context, symbolID string) ([]*
 	})
 
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("finding callees for %s: %w", symbolID, err)
 	}
 
@@ -604,8 +605,8 @@ func (a *CRSGraphAdapter) FindCallees(ctx context.Context, symbolID string) ([]*
 	result, ok := resultI.(*QueryResult)
 	if !ok {
 		err := fmt.Errorf("unexpected type from singleflight group 'calleesGroup': got %T", resultI)
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 	span.SetAttributes(
@@ -638,27 +639,27 @@ func (a *CRSGraphAdapter) FindCallees(ctx context.Context, symbolID string) ([]*
 func (a *CRSGraphAdapter) FindImplementations(ctx context.Context, interfaceName string) ([]*ast.Symbol, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.FindImplementations",
 		trace.WithAttributes(
-			attribute.String("interface_name", interfaceName),
+			telemetry.SensitiveString("interface_name", interfaceName),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
 
 	results, err := a.graph.Graph.FindImplementationsByName(ctx, interfaceName)
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("finding implementations of %s: %w", interfaceName, err)
 	}
 
@@ -686,19 +687,19 @@ func (a *CRSGraphAdapter) FindImplementations(ctx context.Context, interfaceName
 func (a *CRSGraphAdapter) FindReferences(ctx context.Context, symbolID string) ([]*ast.Symbol, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.FindReferences",
 		trace.WithAttributes(
-			attribute.String("symbol_id", symbolID),
+			telemetry.SensitiveString("symbol_id", symbolID),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, err
 	}
@@ -743,19 +744,19 @@ func (a *CRSGraphAdapter) FindReferences(ctx context.Context, symbolID string) (
 func (a *CRSGraphAdapter) GetEdgesByFile(ctx context.Context, filePath string) ([]*Edge, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.GetEdgesByFile",
 		trace.WithAttributes(
-			attribute.String("file_path", filePath),
+			telemetry.SensitiveString("file_path", filePath),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, err
 	}
@@ -787,19 +788,19 @@ func (a *CRSGraphAdapter) GetEdgesByFile(ctx context.Context, filePath string) (
 func (a *CRSGraphAdapter) GetEdgeCountByFile(ctx context.Context, filePath string) (int, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.GetEdgeCountByFile",
 		trace.WithAttributes(
-			attribute.String("file_path", filePath),
+			telemetry.SensitiveString("file_path", filePath),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return 0, err
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return 0, err
 	}
@@ -849,19 +850,19 @@ func (a *CRSGraphAdapter) GetEdgeCountByFile(ctx context.Context, filePath strin
 func (a *CRSGraphAdapter) HasCycleFrom(ctx context.Context, symbolID string) (bool, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.HasCycleFrom",
 		trace.WithAttributes(
-			attribute.String("symbol_id", symbolID),
+			telemetry.SensitiveString("symbol_id", symbolID),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return false, err
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return false, err
 	}
@@ -873,7 +874,7 @@ func (a *CRSGraphAdapter) HasCycleFrom(ctx context.Context, symbolID string) (bo
 
 	hasCycle, err := a.hasCycleUtil(ctx, symbolID, visited, recStack, 0)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		return false, err
 	}
 
@@ -896,7 +897,7 @@ func (a *CRSGraphAdapter) hasCycleUtil(ctx context.Context, nodeID string, visit
 		// Log warning when depth limit is reached - cycle detection is incomplete
 		_, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.hasCycleUtil.DepthLimitReached",
 			trace.WithAttributes(
-				attribute.String("node_id", nodeID),
+				telemetry.SensitiveString("node_id", nodeID),
 				attribute.Int("depth", depth),
 				attribute.Int("max_depth", maxCycleDetectionDepth),
 			),
@@ -953,13 +954,13 @@ func (a *CRSGraphAdapter) CallEdgeCount(ctx context.Context) (int, error) {
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return 0, err
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return 0, err
 	}
@@ -1038,21 +1039,21 @@ const maxCycleDetectionDepth = 1000
 func (a *CRSGraphAdapter) GetCallChain(ctx context.Context, fromID, toID string, maxDepth int) ([]string, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.GetCallChain",
 		trace.WithAttributes(
-			attribute.String("from_id", fromID),
-			attribute.String("to_id", toID),
+			telemetry.SensitiveString("from_id", fromID),
+			telemetry.SensitiveString("to_id", toID),
 			attribute.Int("max_depth", maxDepth),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
@@ -1068,8 +1069,8 @@ func (a *CRSGraphAdapter) GetCallChain(ctx context.Context, fromID, toID string,
 	// Directly use BFS to find path (avoids redundant GetCallGraph traversal)
 	path, err := a.reconstructPath(ctx, fromID, toID, maxDepth)
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("reconstructing path from %s to %s: %w", fromID, toID, err)
 	}
 
@@ -1179,20 +1180,20 @@ func (a *CRSGraphAdapter) reconstructPath(ctx context.Context, fromID, toID stri
 func (a *CRSGraphAdapter) ShortestPath(ctx context.Context, fromID, toID string) ([]string, error) {
 	ctx, span := crsAdapterTracer.Start(ctx, "graph.CRSGraphAdapter.ShortestPath",
 		trace.WithAttributes(
-			attribute.String("from_id", fromID),
-			attribute.String("to_id", toID),
+			telemetry.SensitiveString("from_id", fromID),
+			telemetry.SensitiveString("to_id", toID),
 		),
 	)
 	defer span.End()
 
 	if err := a.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
@@ -1241,8 +1242,8 @@ func (a *CRSGraphAdapter) ShortestPath(ctx context.Context, fromID, toID string)
 	})
 
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("finding shortest path from %s to %s: %w", fromID, toID, err)
 	}
 
@@ -1250,8 +1251,8 @@ func (a *CRSGraphAdapter) ShortestPath(ctx context.Context, fromID, toID string)
 	result, ok := resultI.(*PathResult)
 	if !ok {
 		err := fmt.Errorf("unexpected type from singleflight group 'pathsGroup': got %T", resultI)
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 	span.SetAttributes(
@@ -1317,13 +1318,13 @@ func (ga *crsAnalyticsAdapter) HotSpots(ctx context.Context, k int) ([]crs.Graph
 	defer span.End()
 
 	if err := ga.adapter.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
@@ -1378,13 +1379,13 @@ func (ga *crsAnalyticsAdapter) DeadCode(ctx context.Context) ([]string, error) {
 	defer span.End()
 
 	if err := ga.adapter.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
@@ -1421,13 +1422,13 @@ func (ga *crsAnalyticsAdapter) CyclicDependencies(ctx context.Context) ([][]stri
 	defer span.End()
 
 	if err := ga.adapter.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
@@ -1478,13 +1479,13 @@ func (ga *crsAnalyticsAdapter) PageRank(ctx context.Context) (map[string]float64
 	defer span.End()
 
 	if err := ga.adapter.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
@@ -1541,8 +1542,8 @@ func (ga *crsAnalyticsAdapter) PageRank(ctx context.Context) (map[string]float64
 	})
 
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 
@@ -1668,13 +1669,13 @@ func (ga *crsAnalyticsAdapter) Communities(ctx context.Context) ([]crs.GraphComm
 	defer span.End()
 
 	if err := ga.adapter.checkClosed(); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, fmt.Errorf("adapter closed: %w", err)
 	}
 
 	if err := ctx.Err(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context cancelled")
 		return nil, fmt.Errorf("context cancelled: %w", err)
 	}
@@ -1748,8 +1749,8 @@ func (ga *crsAnalyticsAdapter) Communities(ctx context.Context) ([]crs.GraphComm
 	})
 
 	if err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 
@@ -0,0 +1,104 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// Query cursor errors.
+var (
+	// ErrInvalidCursor indicates a cursor that is malformed or was issued
+	// for another query.
+	ErrInvalidCursor = errors.New("invalid cursor")
+
+	// ErrStaleCursor indicates a cursor issued against another generation
+	// of the graph. The query must be restarted without a cursor.
+	ErrStaleCursor = errors.New("cursor is from an older graph generation; repeat the query without a cursor")
+)
+
+// EncodeCursor returns an opaque cursor that resumes a paged query at
+// offset.
+//
+// Description:
+//
+//	The cursor records the offset, a fingerprint of the query and the
+//	generation of the graph the page was read from. Queries over a frozen
+//	graph return results in edge order, which is fixed per generation, so
+//	an offset identifies the same position on every call.
+//
+// Inputs:
+//
+//	generation - Identifies the graph, e.g. Graph.Generation() or a
+//	  content hash.
+//	offset - The number of results already returned.
+//	query - The query's identifying parts, e.g. tool name and symbol.
+//
+// Outputs:
+//
+//	string - The URL-safe cursor.
+func EncodeCursor(generation string, offset int, query ...string) string {
+	raw := strconv.Itoa(offset) + ":" + cursorFingerprint(query) + ":" + generation
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor returns the offset recorded by EncodeCursor.
+//
+// Inputs:
+//
+//	cursor - The cursor. Empty starts at offset 0.
+//	generation - The current generation of the graph.
+//	query - The query's identifying parts, as given to EncodeCursor.
+//
+// Outputs:
+//
+//	int - The offset to resume at.
+//	error - Wraps ErrInvalidCursor if the cursor is malformed or belongs
+//	  to another query, or ErrStaleCursor if the generation changed.
+func DecodeCursor(cursor, generation string, query ...string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("%w: not a cursor", ErrInvalidCursor)
+	}
+	parts := strings.SplitN(string(raw), ":", 3)
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("%w: not a cursor", ErrInvalidCursor)
+	}
+	offset, err := strconv.Atoi(parts[0])
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("%w: bad offset", ErrInvalidCursor)
+	}
+	if parts[1] != cursorFingerprint(query) {
+		return 0, fmt.Errorf("%w: cursor belongs to another query", ErrInvalidCursor)
+	}
+	if parts[2] != generation {
+		return 0, ErrStaleCursor
+	}
+	return offset, nil
+}
+
+// cursorFingerprint hashes the query parts of a cursor.
+func cursorFingerprint(query []string) string {
+	h := fnv.New64a()
+	for _, part := range query {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return strconv.FormatUint(h.Sum64(), 36)
+}
@@ -0,0 +1,139 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// hubGraph returns a frozen graph where n functions call hub and hub
+// calls each of them back.
+func hubGraph(t *testing.T, n int) *Graph {
+	t.Helper()
+	g := NewGraph("/test/project")
+	hub := &ast.Symbol{ID: "log.go:1:Log", Name: "Log", Kind: ast.SymbolKindFunction, FilePath: "log.go", StartLine: 1, Language: "go"}
+	if _, err := g.AddNode(hub); err != nil {
+		t.Fatal(err)
+	}
+	for i := range n {
+		sym := &ast.Symbol{ID: fmt.Sprintf("a.go:%d:f%02d", i+10, i), Name: fmt.Sprintf("f%02d", i),
+			Kind: ast.SymbolKindFunction, FilePath: "a.go", StartLine: i + 10, Language: "go"}
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+		loc := ast.Location{FilePath: "a.go", StartLine: i + 11}
+		if err := g.AddEdge(sym.ID, hub.ID, EdgeTypeCalls, loc); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.AddEdge(hub.ID, sym.ID, EdgeTypeCalls, ast.Location{FilePath: "log.go", StartLine: 2}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+func TestGraph_QueryOffset(t *testing.T) {
+	ctx := context.Background()
+	g := hubGraph(t, 25)
+
+	names := func(syms []*ast.Symbol) []string {
+		out := make([]string, len(syms))
+		for i, s := range syms {
+			out[i] = s.Name
+		}
+		return out
+	}
+	queries := map[string]func(opts ...QueryOption) (*QueryResult, error){
+		"callers": func(opts ...QueryOption) (*QueryResult, error) {
+			return g.FindCallersByID(ctx, "log.go:1:Log", opts...)
+		},
+		"callees": func(opts ...QueryOption) (*QueryResult, error) {
+			return g.FindCalleesByID(ctx, "log.go:1:Log", opts...)
+		},
+		"inherited": func(opts ...QueryOption) (*QueryResult, error) {
+			r, err := g.FindCallersWithInheritance(ctx, "log.go:1:Log", nil, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return &QueryResult{Symbols: r.DirectCallers.Symbols, Truncated: r.Truncated, Total: r.Total}, nil
+		},
+	}
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			var pages []string
+			for offset := 0; ; offset += 10 {
+				r, err := query(WithLimit(10), WithOffset(offset))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if r.Total != 25 {
+					t.Errorf("offset %d: Total = %d, want 25", offset, r.Total)
+				}
+				pages = append(pages, names(r.Symbols)...)
+				if !r.Truncated {
+					break
+				}
+			}
+			all, _ := query(WithLimit(100))
+			if fmt.Sprint(pages) != fmt.Sprint(names(all.Symbols)) {
+				t.Errorf("pages = %v, want %v", pages, names(all.Symbols))
+			}
+		})
+	}
+
+	t.Run("references", func(t *testing.T) {
+		if got := g.ReferenceCount("log.go:1:Log"); got != 25 {
+			t.Errorf("ReferenceCount = %d, want 25", got)
+		}
+		first, _ := g.FindReferencesByID(ctx, "log.go:1:Log", WithLimit(20))
+		rest, _ := g.FindReferencesByID(ctx, "log.go:1:Log", WithLimit(20), WithOffset(20))
+		if len(first) != 20 || len(rest) != 5 || rest[0] == first[19] {
+			t.Errorf("pages of %d and %d", len(first), len(rest))
+		}
+	})
+}
+
+func TestCursor(t *testing.T) {
+	cursor := EncodeCursor("gen-1", 50, "find_callers", "Log")
+
+	offset, err := DecodeCursor(cursor, "gen-1", "find_callers", "Log")
+	if err != nil || offset != 50 {
+		t.Errorf("DecodeCursor = %d, %v; want 50", offset, err)
+	}
+	if offset, err := DecodeCursor("", "gen-1", "find_callers", "Log"); err != nil || offset != 0 {
+		t.Errorf("empty cursor = %d, %v", offset, err)
+	}
+	if _, err := DecodeCursor(cursor, "gen-2", "find_callers", "Log"); !errors.Is(err, ErrStaleCursor) {
+		t.Errorf("other generation: %v, want ErrStaleCursor", err)
+	}
+	if _, err := DecodeCursor(cursor, "gen-1", "find_callers", "Open"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("other query: %v, want ErrInvalidCursor", err)
+	}
+	if _, err := DecodeCursor("not*base64", "gen-1"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("garbage: %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestGraph_Generation(t *testing.T) {
+	a, b := hubGraph(t, 1), hubGraph(t, 1)
+	if a.Generation() == 0 || a.Generation() == b.Generation() {
+		t.Errorf("generations %d and %d, want distinct and non-zero", a.Generation(), b.Generation())
+	}
+	if NewGraph("/x").Generation() != 0 {
+		t.Error("unfrozen graph has a generation")
+	}
+}
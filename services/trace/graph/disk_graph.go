@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	bolt "go.etcd.io/bbolt"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -41,6 +42,8 @@ type DiskGraph struct {
 	graphHash    string
 	builtAtMilli int64
 	fileMtimes   map[string]int64
+	fileHashes   map[string]string
+	quality      *QualityReport
 }
 
 // OpenDiskGraph opens a bbolt graph file and validates its schema version.
@@ -156,6 +159,24 @@ func (dg *DiskGraph) readMeta() error {
 			dg.fileMtimes = fm
 		}
 
+		// Read file hashes, absent in files written before they existed.
+		if data := metaBkt.Get(metaKeyFileHashes); data != nil {
+			fh, fhErr := decodeMetaFileHashes(data)
+			if fhErr != nil {
+				return fmt.Errorf("decoding file_hashes: %w", fhErr)
+			}
+			dg.fileHashes = fh
+		}
+
+		// Read the quality report, absent in files written before it existed.
+		if data := metaBkt.Get(metaKeyQuality); data != nil {
+			qr, qrErr := decodeMetaQualityReport(data)
+			if qrErr != nil {
+				return fmt.Errorf("decoding quality_report: %w", qrErr)
+			}
+			dg.quality = qr
+		}
+
 		return nil
 	})
 }
@@ -285,6 +306,32 @@ func (dg *DiskGraph) FileMtimes() map[string]int64 {
 	return result
 }
 
+// FileHashes returns the file content hashes recorded at build time.
+//
+// Description:
+//
+//	Returns a copy of the file hashes map to prevent mutation. Nil for
+//	files written before hashes were recorded.
+//
+// Thread Safety: Safe for concurrent use.
+func (dg *DiskGraph) FileHashes() map[string]string {
+	if dg.fileHashes == nil {
+		return nil
+	}
+	result := make(map[string]string, len(dg.fileHashes))
+	for k, v := range dg.fileHashes {
+		result[k] = v
+	}
+	return result
+}
+
+// QualityReport returns the quality report recorded at build time, or nil.
+//
+// Thread Safety: Safe for concurrent use. The report must not be modified.
+func (dg *DiskGraph) QualityReport() *QualityReport {
+	return dg.quality
+}
+
 // BuiltAtMilli returns the build timestamp recorded in metadata.
 //
 // Thread Safety: Safe for concurrent use.
@@ -332,7 +379,7 @@ func (dg *DiskGraph) LoadAsGraph(ctx context.Context) (*Graph, error) {
 	span.SetAttributes(
 		attribute.Int("node_count", dg.nodeCount),
 		attribute.Int("edge_count", dg.edgeCount),
-		attribute.String("project_root", dg.projectRoot),
+		telemetry.SensitiveString("project_root", dg.projectRoot),
 	)
 
 	// Headroom of +100 allows incremental refresh to add nodes/edges
@@ -379,7 +426,7 @@ func (dg *DiskGraph) LoadAsGraph(ctx context.Context) (*Graph, error) {
 				return fmt.Errorf("decoding outgoing edges for %s: %w", string(k), err)
 			}
 			for _, edge := range edges {
-				if err := g.AddEdge(edge.FromID, edge.ToID, edge.Type, edge.Location); err != nil {
+				if err := g.AddEdgeWithProvenance(edge.FromID, edge.ToID, edge.Type, edge.Location, edge.Provenance); err != nil {
 					return fmt.Errorf("adding edge %s -> %s: %w", edge.FromID, edge.ToID, err)
 				}
 			}
@@ -393,6 +440,8 @@ func (dg *DiskGraph) LoadAsGraph(ctx context.Context) (*Graph, error) {
 	g.Freeze()
 	g.BuiltAtMilli = dg.builtAtMilli
 	g.FileMtimes = dg.FileMtimes()
+	g.FileHashes = dg.FileHashes()
+	g.QualityReport = dg.QualityReport()
 
 	return g, nil
 }
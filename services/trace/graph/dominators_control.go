@@ -313,7 +313,7 @@ func (a *GraphAnalytics) ComputeControlDependence(
 	// Start OTel span
 	ctx, span := controlDependenceTracer.Start(ctx, "GraphAnalytics.ComputeControlDependence",
 		trace.WithAttributes(
-			attribute.String("exit", postDomTree.Entry),
+			telemetry.SensitiveString("exit", postDomTree.Entry),
 			attribute.Int("node_count", len(postDomTree.ImmediateDom)),
 		),
 	)
@@ -421,7 +421,7 @@ func (a *GraphAnalytics) ComputeControlDependence(
 
 			if chainLength >= maxChainLength {
 				telemetry.LoggerWithTrace(ctx, slog.Default()).Warn("control_dependence: ipostdom chain exceeded max length",
-					slog.String("node", nodeID),
+					telemetry.SensitiveLog("node", nodeID),
 					slog.String("succ", succID),
 					slog.Int("chain_length", chainLength),
 				)
@@ -457,7 +457,7 @@ func (a *GraphAnalytics) ComputeControlDependence(
 			if len(deps) > result.MaxDependencies {
 				result.MaxDependencies = len(deps)
 				telemetry.LoggerWithTrace(ctx, slog.Default()).Debug("control_dependence: node with many dependencies",
-					slog.String("node", nodeID),
+					telemetry.SensitiveLog("node", nodeID),
 					slog.Int("count", len(deps)),
 				)
 			}
@@ -264,7 +264,7 @@ func (a *GraphAnalytics) ComputeDominanceFrontier(
 	if len(domTree.ImmediateDom) == 0 {
 		telemetry.LoggerWithTrace(ctx, slog.Default()).Warn(
 			"dominance_frontier: empty dominator tree - graph may not be ready",
-			slog.String("entry", domTree.Entry),
+			telemetry.SensitiveLog("entry", domTree.Entry),
 		)
 		return result, &DominanceFrontierError{
 			Message: "dominator tree has no nodes - graph may not be indexed yet or entry point is isolated",
@@ -277,7 +277,7 @@ func (a *GraphAnalytics) ComputeDominanceFrontier(
 	// Start OTel span
 	ctx, span := dominanceFrontierTracer.Start(ctx, "GraphAnalytics.ComputeDominanceFrontier",
 		trace.WithAttributes(
-			attribute.String("entry", domTree.Entry),
+			telemetry.SensitiveString("entry", domTree.Entry),
 			attribute.Int("node_count", len(domTree.ImmediateDom)),
 		),
 	)
@@ -384,7 +384,7 @@ func (a *GraphAnalytics) ComputeDominanceFrontier(
 
 			if chainLength >= maxChainLength {
 				telemetry.LoggerWithTrace(ctx, slog.Default()).Warn("dominance_frontier: idom chain exceeded max length",
-					slog.String("node", nodeID),
+					telemetry.SensitiveLog("node", nodeID),
 					slog.String("pred", predID),
 					slog.Int("chain_length", chainLength),
 				)
@@ -309,7 +309,7 @@ func (a *GraphAnalytics) DetectLoops(
 	// Start OTel span
 	ctx, span := loopDetectionTracer.Start(ctx, "GraphAnalytics.DetectLoops",
 		trace.WithAttributes(
-			attribute.String("entry", domTree.Entry),
+			telemetry.SensitiveString("entry", domTree.Entry),
 			attribute.Int("node_count", len(domTree.ImmediateDom)),
 		),
 	)
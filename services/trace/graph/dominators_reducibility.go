@@ -196,7 +196,7 @@ func (a *GraphAnalytics) CheckReducibility(ctx context.Context, domTree *Dominat
 	// Start OTel span
 	ctx, span := reducibilityTracer.Start(ctx, "CheckReducibility",
 		trace.WithAttributes(
-			attribute.String("entry", domTree.Entry),
+			telemetry.SensitiveString("entry", domTree.Entry),
 			attribute.Int("node_count", nodeCount),
 			attribute.Int("edge_count", edgeCount),
 		),
@@ -209,7 +209,7 @@ func (a *GraphAnalytics) CheckReducibility(ctx context.Context, domTree *Dominat
 	logger.Debug("starting reducibility check",
 		slog.Int("node_count", nodeCount),
 		slog.Int("edge_count", edgeCount),
-		slog.String("entry", domTree.Entry),
+		telemetry.SensitiveLog("entry", domTree.Entry),
 	)
 
 	// Handle empty graph
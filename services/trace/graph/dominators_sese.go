@@ -224,8 +224,8 @@ func (a *GraphAnalytics) DetectSESERegions(
 
 	ctx, span := seseTracer.Start(ctx, "GraphAnalytics.DetectSESERegions",
 		trace.WithAttributes(
-			attribute.String("dom_entry", domTree.Entry),
-			attribute.String("postdom_entry", postDomTree.Entry),
+			telemetry.SensitiveString("dom_entry", domTree.Entry),
+			telemetry.SensitiveString("postdom_entry", postDomTree.Entry),
 			attribute.Int("node_count", nodeCount),
 			attribute.Int("edge_count", edgeCount),
 		),
@@ -182,7 +182,7 @@ func (a *GraphAnalytics) Dominators(ctx context.Context, entry string) (*Dominat
 
 	ctx, span := dominatorTracer.Start(ctx, "GraphAnalytics.Dominators",
 		trace.WithAttributes(
-			attribute.String("entry", entry),
+			telemetry.SensitiveString("entry", entry),
 			attribute.Int("node_count", nodeCount),
 			attribute.Int("edge_count", edgeCount),
 		),
@@ -208,7 +208,7 @@ func (a *GraphAnalytics) Dominators(ctx context.Context, entry string) (*Dominat
 	// Verify entry node exists
 	if _, ok := a.graph.GetNode(entry); !ok {
 		span.AddEvent("entry_not_found")
-		telemetry.LoggerWithTrace(ctx, slog.Default()).Debug("dominators: entry node not found", slog.String("entry", entry))
+		telemetry.LoggerWithTrace(ctx, slog.Default()).Debug("dominators: entry node not found", telemetry.SensitiveLog("entry", entry))
 		return result, &DominatorError{Message: "entry node not found: " + entry}
 	}
 
@@ -343,7 +343,7 @@ func (a *GraphAnalytics) Dominators(ctx context.Context, entry string) (*Dominat
 	))
 
 	telemetry.LoggerWithTrace(ctx, slog.Default()).Debug("dominators: analysis complete",
-		slog.String("entry", entry),
+		telemetry.SensitiveLog("entry", entry),
 		slog.Int("iterations", iterations),
 		slog.Bool("converged", result.Converged),
 		slog.Int("reachable_nodes", len(result.ImmediateDom)),
@@ -1101,7 +1101,7 @@ func (a *GraphAnalytics) LCDWithCRS(
 	// Start OTel span
 	ctx, span := lcdTracer.Start(ctx, "GraphAnalytics.LCDWithCRS",
 		trace.WithAttributes(
-			attribute.String("entry", domTree.Entry),
+			telemetry.SensitiveString("entry", domTree.Entry),
 			attribute.Int("nodes_queried", len(nodes)),
 		),
 	)
@@ -1382,7 +1382,7 @@ func (a *GraphAnalytics) PostDominators(ctx context.Context, exit string) (*Domi
 	// Start OTel span
 	ctx, span := postDominatorTracer.Start(ctx, "GraphAnalytics.PostDominators",
 		trace.WithAttributes(
-			attribute.String("exit", exit),
+			telemetry.SensitiveString("exit", exit),
 			attribute.Int("node_count", nodeCount),
 			attribute.Int("edge_count", edgeCount),
 		),
@@ -1438,7 +1438,7 @@ func (a *GraphAnalytics) PostDominators(ctx context.Context, exit string) (*Domi
 		// Verify specified exit exists
 		if _, ok := a.graph.GetNode(exit); !ok {
 			span.AddEvent("exit_not_found")
-			telemetry.LoggerWithTrace(ctx, slog.Default()).Debug("post_dominators: exit node not found", slog.String("exit", exit))
+			telemetry.LoggerWithTrace(ctx, slog.Default()).Debug("post_dominators: exit node not found", telemetry.SensitiveLog("exit", exit))
 			return result, &DominatorError{Message: "exit node not found: " + exit}
 		}
 		exits = []string{exit}
@@ -1586,7 +1586,7 @@ func (a *GraphAnalytics) PostDominators(ctx context.Context, exit string) (*Domi
 	))
 
 	telemetry.LoggerWithTrace(ctx, slog.Default()).Debug("post_dominators: analysis complete",
-		slog.String("exit", exit),
+		telemetry.SensitiveLog("exit", exit),
 		slog.Int("iterations", iterations),
 		slog.Bool("converged", result.Converged),
 		slog.Int("reachable_nodes", len(result.ImmediateDom)),
@@ -1643,7 +1643,7 @@ func (a *GraphAnalytics) DetectEntryNodes(ctx context.Context) []string {
 			mainFunctionFound = true
 			mainFunctionIncoming = len(node.Incoming)
 			slog.Debug("GR-17: Found main function",
-				slog.String("id", node.ID),
+				telemetry.SensitiveLog("id", node.ID),
 				slog.Int("incoming_edges", len(node.Incoming)),
 				slog.Int("outgoing_edges", len(node.Outgoing)),
 			)
@@ -1659,10 +1659,10 @@ func (a *GraphAnalytics) DetectEntryNodes(ctx context.Context) []string {
 					}
 					slog.Debug("GR-17: Main incoming edge",
 						slog.Int("edge_num", i),
-						slog.String("from_id", edge.FromID),
-						slog.String("from_name", fromName),
+						telemetry.SensitiveLog("from_id", edge.FromID),
+						telemetry.SensitiveLog("from_name", fromName),
 						slog.String("edge_type", edge.Type.String()),
-						slog.String("location", edge.Location.String()),
+						telemetry.SensitiveLog("location", edge.Location.String()),
 					)
 				}
 			}
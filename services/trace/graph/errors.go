@@ -85,4 +85,25 @@ var (
 	// has not been frozen yet. The graph must be frozen (read-only) before
 	// wrapping with HierarchicalGraph.
 	ErrGraphNotFrozen = errors.New("graph must be frozen before wrapping")
+
+	// ErrInvalidQuery is returned when a StructuredQuery fails validation:
+	// an unknown kind, edge type, field or direction, a bad regex or glob,
+	// or depth bounds out of range.
+	ErrInvalidQuery = errors.New("invalid structured query")
+
+	// ErrAmbiguousSymbol is returned when a symbol name matches more than
+	// one symbol and an ID is needed to choose.
+	ErrAmbiguousSymbol = errors.New("ambiguous symbol name")
+
+	// ErrUnknownEdgePass is returned when a pass name given to disable a
+	// builder pass is not a known EdgePass.
+	ErrUnknownEdgePass = errors.New("unknown edge pass")
+
+	// ErrInvalidScope is returned when a scoped view has no patterns, too
+	// many, a malformed glob, or an unknown boundary mode.
+	ErrInvalidScope = errors.New("invalid scope")
+
+	// ErrInvalidCorpusConfig is returned when a SyntheticCorpusConfig has
+	// a non-positive size, a ratio outside [0, 1], or an unknown language.
+	ErrInvalidCorpusConfig = errors.New("invalid synthetic corpus config")
 )
@@ -0,0 +1,369 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// MatchVia says how a type's method set came to contain an interface method.
+type MatchVia string
+
+const (
+	// MatchViaDirect means the type declares the method itself, in the
+	// type's own file.
+	MatchViaDirect MatchVia = "direct"
+
+	// MatchViaCrossFile means the method is declared in a different file
+	// from the type and was attached to it by cross-file method
+	// association (Go methods, Swift extensions).
+	MatchViaCrossFile MatchVia = "cross_file"
+
+	// MatchViaPromoted means the method is promoted from an embedded type.
+	MatchViaPromoted MatchVia = "promoted"
+)
+
+// maxExplainCandidates caps the candidate methods listed per interface
+// method, so common names like String don't flood the report.
+const maxExplainCandidates = 10
+
+// ImplementsExplanation reports why a type does or does not implement an
+// interface under implicit (method-set) matching.
+type ImplementsExplanation struct {
+	// TypeID is the type that was checked.
+	TypeID string `json:"type_id"`
+
+	// InterfaceID is the interface that was checked.
+	InterfaceID string `json:"interface_id"`
+
+	// Language is the type's language.
+	Language string `json:"language"`
+
+	// Implements is the verdict of the method-set comparison.
+	Implements bool `json:"implements"`
+
+	// EdgePresent is true if the graph has an IMPLEMENTS edge from the
+	// type to the interface. Explicit declarations (Java, C#) produce
+	// edges without method-set matching.
+	EdgePresent bool `json:"edge_present"`
+
+	// Reasons explain a negative verdict that is not down to missing
+	// methods: an ineligible kind or language, or an empty interface.
+	Reasons []string `json:"reasons,omitempty"`
+
+	// Methods has one entry per interface method, sorted by name.
+	Methods []InterfaceMethodMatch `json:"methods"`
+
+	// Missing lists the interface methods the type lacks.
+	Missing []string `json:"missing,omitempty"`
+
+	// UsedPromoted is true if any match came from an embedded type.
+	UsedPromoted bool `json:"used_promoted"`
+
+	// UsedCrossFile is true if any match came from cross-file association.
+	UsedCrossFile bool `json:"used_cross_file"`
+}
+
+// InterfaceMethodMatch is the outcome for one interface method.
+type InterfaceMethodMatch struct {
+	// Name is the method name. Matching compares names only.
+	Name string `json:"name"`
+
+	// DeclaredIn is the interface declaring the method: the checked
+	// interface, or one it embeds or extends.
+	DeclaredIn string `json:"declared_in"`
+
+	// Matched is true if the type's method set contains the method.
+	Matched bool `json:"matched"`
+
+	// Via says how the method entered the type's method set. Empty when
+	// unmatched.
+	Via MatchVia `json:"via,omitempty"`
+
+	// PromotedFrom is the embedded type declaring a promoted method.
+	PromotedFrom string `json:"promoted_from,omitempty"`
+
+	// Candidates are method symbols with this name in the type's language,
+	// the selected one first.
+	Candidates []MethodCandidate `json:"candidates,omitempty"`
+}
+
+// MethodCandidate is a method symbol considered for an interface method.
+type MethodCandidate struct {
+	// SymbolID is the method's symbol ID.
+	SymbolID string `json:"symbol_id"`
+
+	// Receiver is the receiver type name, without pointer or type
+	// parameters.
+	Receiver string `json:"receiver"`
+
+	// FilePath and Line locate the declaration.
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+
+	// Signature is the method's declared signature.
+	Signature string `json:"signature,omitempty"`
+
+	// Selected is true for the method that satisfies the interface method.
+	Selected bool `json:"selected"`
+}
+
+// ExplainImplements explains the implicit-implementation verdict for a
+// type and an interface.
+//
+// Description:
+//
+//	Re-runs the comparison computeInterfaceImplementations makes for the
+//	pair, with the same eligibility rules, embed resolution and
+//	isMethodSuperset check, and reports per interface method whether the
+//	type has it and how: declared directly, attached across files, or
+//	promoted from an embedded type. The type's method set is read from the
+//	built graph, so cross-file association has already run; a method
+//	declared outside the type's file is reported as a cross-file match.
+//
+// Inputs:
+//   - typeID: The struct, class or type symbol ID.
+//   - ifaceID: The interface symbol ID.
+//
+// Outputs:
+//   - *ImplementsExplanation: The report. Never nil on success.
+//   - error: Wraps ErrNodeNotFound if either ID is not in the graph.
+//
+// Limitations:
+//   - Like the builder, matching is by method name only; parameter and
+//     return types are listed in candidates but not compared.
+//
+// Thread Safety: Safe for concurrent use on a frozen graph.
+func (g *Graph) ExplainImplements(typeID, ifaceID string) (*ImplementsExplanation, error) {
+	typeNode, ok := g.GetNode(typeID)
+	if !ok || typeNode.Symbol == nil {
+		return nil, fmt.Errorf("type %s: %w", typeID, ErrNodeNotFound)
+	}
+	ifaceNode, ok := g.GetNode(ifaceID)
+	if !ok || ifaceNode.Symbol == nil {
+		return nil, fmt.Errorf("interface %s: %w", ifaceID, ErrNodeNotFound)
+	}
+	typeSym, ifaceSym := typeNode.Symbol, ifaceNode.Symbol
+
+	exp := &ImplementsExplanation{
+		TypeID:      typeID,
+		InterfaceID: ifaceID,
+		Language:    typeSym.Language,
+		Methods:     []InterfaceMethodMatch{},
+	}
+	for _, edge := range typeNode.Outgoing {
+		if edge.Type == EdgeTypeImplements && edge.ToID == ifaceID {
+			exp.EdgePresent = true
+			break
+		}
+	}
+
+	lookup := func(id string) *ast.Symbol {
+		if node, ok := g.GetNode(id); ok {
+			return node.Symbol
+		}
+		return nil
+	}
+
+	eligible := true
+	if reason := interfaceCandidateReason(ifaceSym); reason != "" {
+		exp.Reasons = append(exp.Reasons, reason)
+		eligible = false
+	}
+	if reason := typeCandidateReason(typeSym); reason != "" {
+		exp.Reasons = append(exp.Reasons, reason)
+		eligible = false
+	}
+	if typeSym.Language != ifaceSym.Language {
+		exp.Reasons = append(exp.Reasons, fmt.Sprintf(
+			"types are only matched against interfaces of the same language (%s vs %s)",
+			typeSym.Language, ifaceSym.Language))
+		eligible = false
+	}
+
+	// The interface's method set, with the interface declaring each method.
+	ifaceMethods := make(map[string]bool)
+	declaredIn := make(map[string]string)
+	if ifaceSym.Metadata != nil {
+		for _, m := range ifaceSym.Metadata.Methods {
+			ifaceMethods[m.Name] = true
+		}
+	}
+	mergeEmbeddedMethods(g, lookup, ifaceID, ifaceMethods, declaredIn, make(map[string]bool), 0)
+	if len(ifaceMethods) == 0 {
+		exp.Reasons = append(exp.Reasons, fmt.Sprintf(
+			"%s has no methods after resolving embedded interfaces; empty interfaces are never matched", ifaceSym.Name))
+		eligible = false
+	}
+
+	// The type's method set, with the embedded type providing each
+	// promoted method.
+	typeMethods := make(map[string]bool)
+	if typeSym.Metadata != nil {
+		for _, m := range typeSym.Metadata.Methods {
+			typeMethods[m.Name] = true
+		}
+	}
+	promotedFrom := make(map[string]string)
+	mergeEmbeddedMethods(g, lookup, typeID, typeMethods, promotedFrom, make(map[string]bool), 0)
+
+	names := make([]string, 0, len(ifaceMethods))
+	for name := range ifaceMethods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		match := InterfaceMethodMatch{
+			Name:       name,
+			DeclaredIn: ifaceID,
+			Matched:    typeMethods[name],
+		}
+		if from, ok := declaredIn[name]; ok {
+			match.DeclaredIn = from
+		}
+
+		receiver := typeSym.Name
+		if match.Matched {
+			match.Via = MatchViaDirect
+			if from, ok := promotedFrom[name]; ok {
+				match.Via = MatchViaPromoted
+				match.PromotedFrom = from
+				if embedded := lookup(from); embedded != nil {
+					receiver = embedded.Name
+				}
+				exp.UsedPromoted = true
+			}
+		} else {
+			exp.Missing = append(exp.Missing, name)
+		}
+
+		match.Candidates = g.methodCandidates(name, typeSym.Language, receiver, match.Matched)
+		if match.Via == MatchViaDirect && len(match.Candidates) > 0 && match.Candidates[0].Selected {
+			if _, _, ok := crossFileReceiver(lookup(match.Candidates[0].SymbolID)); ok &&
+				match.Candidates[0].FilePath != typeSym.FilePath {
+				match.Via = MatchViaCrossFile
+				exp.UsedCrossFile = true
+			}
+		}
+		exp.Methods = append(exp.Methods, match)
+	}
+
+	exp.Implements = eligible && isMethodSuperset(typeMethods, ifaceMethods)
+	return exp, nil
+}
+
+// ResolveImplementsSymbol resolves a type or interface given by ID or name.
+//
+// Description:
+//
+//	A ref that is a node ID is returned as is. Otherwise ref is matched by
+//	name against interfaces (wantInterface) or against structs, classes,
+//	types and enums.
+//
+// Inputs:
+//   - ref: A symbol ID or name. Must not be empty.
+//   - wantInterface: True to resolve an interface, false for a type.
+//
+// Outputs:
+//   - string: The symbol ID.
+//   - error: Wraps ErrSymbolNotFound when nothing matches, or
+//     ErrAmbiguousSymbol (listing the IDs) when several names match.
+//
+// Thread Safety: Safe for concurrent use on a frozen graph.
+func (g *Graph) ResolveImplementsSymbol(ref string, wantInterface bool) (string, error) {
+	if node, ok := g.GetNode(ref); ok && node.Symbol != nil {
+		return ref, nil
+	}
+	var ids []string
+	for _, node := range g.GetNodesByName(ref) {
+		if node.Symbol == nil {
+			continue
+		}
+		switch node.Symbol.Kind {
+		case ast.SymbolKindInterface:
+			if !wantInterface {
+				continue
+			}
+		case ast.SymbolKindStruct, ast.SymbolKindType, ast.SymbolKindClass, ast.SymbolKindEnum:
+			if wantInterface {
+				continue
+			}
+		default:
+			continue
+		}
+		ids = append(ids, node.ID)
+	}
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("%w: %s", ErrSymbolNotFound, ref)
+	case 1:
+		return ids[0], nil
+	default:
+		sort.Strings(ids)
+		return "", fmt.Errorf("%w: %s matches %s", ErrAmbiguousSymbol, ref, strings.Join(ids, ", "))
+	}
+}
+
+// methodCandidates returns the method symbols named name in language,
+// with the one whose receiver is receiver first and marked selected when
+// selected is true.
+func (g *Graph) methodCandidates(name, language, receiver string, selected bool) []MethodCandidate {
+	var candidates []MethodCandidate
+	for _, node := range g.GetNodesByName(name) {
+		sym := node.Symbol
+		if sym == nil || sym.Kind != ast.SymbolKindMethod || sym.Language != language {
+			continue
+		}
+		c := MethodCandidate{
+			SymbolID:  sym.ID,
+			Receiver:  methodReceiverName(sym),
+			FilePath:  sym.FilePath,
+			Line:      sym.StartLine,
+			Signature: sym.Signature,
+		}
+		c.Selected = selected && c.Receiver == receiver
+		candidates = append(candidates, c)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Selected != candidates[j].Selected {
+			return candidates[i].Selected
+		}
+		return candidates[i].SymbolID < candidates[j].SymbolID
+	})
+	// Only one method satisfies the interface method; a second method on
+	// the same receiver (e.g. a redeclaration) is not selected.
+	for i := 1; i < len(candidates); i++ {
+		candidates[i].Selected = false
+	}
+	if len(candidates) > maxExplainCandidates {
+		candidates = candidates[:maxExplainCandidates]
+	}
+	return candidates
+}
+
+// methodReceiverName returns a method's receiver type name, parsing it
+// from the signature the way cross-file association does where the parser
+// leaves Receiver empty.
+func methodReceiverName(sym *ast.Symbol) string {
+	if receiver, _, ok := crossFileReceiver(sym); ok && receiver != "" {
+		return receiver
+	}
+	receiver := strings.TrimPrefix(sym.Receiver, "*")
+	if i := strings.Index(receiver, "["); i > 0 {
+		receiver = receiver[:i]
+	}
+	return receiver
+}
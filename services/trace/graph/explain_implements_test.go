@@ -0,0 +1,161 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// buildExplainGraph builds a Go graph where Server implements Service
+// through a cross-file method (Serve) and a promoted one (Close, from the
+// embedded Base), and Client implements neither.
+func buildExplainGraph(t *testing.T) *Graph {
+	t.Helper()
+	goSym := func(id, name string, kind ast.SymbolKind, file string, line int, sig string, meta *ast.SymbolMetadata) *ast.Symbol {
+		return &ast.Symbol{
+			ID: id, Name: name, Kind: kind, FilePath: file,
+			StartLine: line, EndLine: line + 3, EndCol: 50,
+			Language: "go", Signature: sig, Metadata: meta,
+		}
+	}
+
+	closer := goSym("iface.go:1:Closer", "Closer", ast.SymbolKindInterface, "iface.go", 1, "", &ast.SymbolMetadata{
+		Methods: []ast.MethodSignature{{Name: "Close", ReturnCount: 1}},
+	})
+	service := goSym("iface.go:5:Service", "Service", ast.SymbolKindInterface, "iface.go", 5, "", &ast.SymbolMetadata{
+		Extends: "Closer",
+		Methods: []ast.MethodSignature{{Name: "Serve", ReturnCount: 1}},
+	})
+	empty := goSym("iface.go:10:Marker", "Marker", ast.SymbolKindInterface, "iface.go", 10, "", &ast.SymbolMetadata{})
+
+	base := goSym("base.go:1:Base", "Base", ast.SymbolKindStruct, "base.go", 1, "", &ast.SymbolMetadata{
+		Methods: []ast.MethodSignature{{Name: "Close", ReturnCount: 1, ReceiverType: "*Base"}},
+	})
+	baseClose := goSym("base.go:5:Close", "Close", ast.SymbolKindMethod, "base.go", 5, "func (b *Base) Close() error", nil)
+
+	server := goSym("server.go:1:Server", "Server", ast.SymbolKindStruct, "server.go", 1, "", &ast.SymbolMetadata{
+		Extends: "Base",
+	})
+	serverServe := goSym("server_http.go:1:Serve", "Serve", ast.SymbolKindMethod, "server_http.go", 1, "func (s *Server) Serve() error", nil)
+
+	client := goSym("client.go:1:Client", "Client", ast.SymbolKindStruct, "client.go", 1, "", &ast.SymbolMetadata{
+		Methods: []ast.MethodSignature{{Name: "Serve", ReturnCount: 1, ReceiverType: "*Client"}},
+	})
+	clientServe := goSym("client.go:5:Serve", "Serve", ast.SymbolKindMethod, "client.go", 5, "func (c *Client) Serve() error", nil)
+
+	builder := NewBuilder(WithProjectRoot("/test"))
+	result, err := builder.Build(context.Background(), []*ast.ParseResult{
+		testParseResult("iface.go", []*ast.Symbol{closer, service, empty}, nil),
+		testParseResult("base.go", []*ast.Symbol{base, baseClose}, nil),
+		testParseResult("server.go", []*ast.Symbol{server}, nil),
+		testParseResult("server_http.go", []*ast.Symbol{serverServe}, nil),
+		testParseResult("client.go", []*ast.Symbol{client, clientServe}, nil),
+	})
+	if err != nil {
+		t.Fatalf("build failed: %v", err)
+	}
+	return result.Graph
+}
+
+func TestGraph_ExplainImplements(t *testing.T) {
+	g := buildExplainGraph(t)
+
+	t.Run("cross-file and promoted matches", func(t *testing.T) {
+		exp, err := g.ExplainImplements("server.go:1:Server", "iface.go:5:Service")
+		if err != nil {
+			t.Fatalf("ExplainImplements failed: %v", err)
+		}
+		if !exp.Implements || !exp.EdgePresent {
+			t.Fatalf("Implements=%v EdgePresent=%v, want both true; reasons %v", exp.Implements, exp.EdgePresent, exp.Reasons)
+		}
+		if !exp.UsedPromoted || !exp.UsedCrossFile {
+			t.Errorf("UsedPromoted=%v UsedCrossFile=%v, want both true", exp.UsedPromoted, exp.UsedCrossFile)
+		}
+		if len(exp.Methods) != 2 {
+			t.Fatalf("Methods = %+v, want Close and Serve", exp.Methods)
+		}
+
+		closeMatch := exp.Methods[0]
+		if closeMatch.Name != "Close" || closeMatch.Via != MatchViaPromoted || closeMatch.PromotedFrom != "base.go:1:Base" {
+			t.Errorf("Close match = %+v, want promoted from Base", closeMatch)
+		}
+		if closeMatch.DeclaredIn != "iface.go:1:Closer" {
+			t.Errorf("Close DeclaredIn = %s, want the embedded Closer", closeMatch.DeclaredIn)
+		}
+		if len(closeMatch.Candidates) != 1 || !closeMatch.Candidates[0].Selected || closeMatch.Candidates[0].Receiver != "Base" {
+			t.Errorf("Close candidates = %+v, want Base.Close selected", closeMatch.Candidates)
+		}
+
+		serveMatch := exp.Methods[1]
+		if serveMatch.Name != "Serve" || serveMatch.Via != MatchViaCrossFile {
+			t.Errorf("Serve match = %+v, want a cross-file match", serveMatch)
+		}
+		if len(serveMatch.Candidates) != 2 {
+			t.Fatalf("Serve candidates = %+v, want Server.Serve and Client.Serve", serveMatch.Candidates)
+		}
+		if first := serveMatch.Candidates[0]; first.SymbolID != "server_http.go:1:Serve" || !first.Selected {
+			t.Errorf("first Serve candidate = %+v, want Server.Serve selected", first)
+		}
+		if serveMatch.Candidates[1].Selected {
+			t.Error("Client.Serve is selected for Server")
+		}
+	})
+
+	t.Run("missing methods", func(t *testing.T) {
+		exp, err := g.ExplainImplements("client.go:1:Client", "iface.go:5:Service")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp.Implements || exp.EdgePresent {
+			t.Errorf("Implements=%v EdgePresent=%v, want both false", exp.Implements, exp.EdgePresent)
+		}
+		if len(exp.Missing) != 1 || exp.Missing[0] != "Close" {
+			t.Errorf("Missing = %v, want [Close]", exp.Missing)
+		}
+		if len(exp.Reasons) != 0 {
+			t.Errorf("Reasons = %v, want none for a plain mismatch", exp.Reasons)
+		}
+		if serve := exp.Methods[1]; serve.Via != MatchViaDirect {
+			t.Errorf("Client.Serve via = %s, want direct (same file)", serve.Via)
+		}
+	})
+
+	t.Run("empty interface and wrong kind are explained", func(t *testing.T) {
+		exp, err := g.ExplainImplements("server.go:1:Server", "iface.go:10:Marker")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp.Implements || len(exp.Reasons) == 0 {
+			t.Errorf("empty interface: Implements=%v Reasons=%v, want false with a reason", exp.Implements, exp.Reasons)
+		}
+
+		exp, err = g.ExplainImplements("iface.go:1:Closer", "iface.go:5:Service")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp.Implements || len(exp.Reasons) == 0 {
+			t.Errorf("interface as type: Implements=%v Reasons=%v, want false with a reason", exp.Implements, exp.Reasons)
+		}
+	})
+
+	t.Run("unknown IDs", func(t *testing.T) {
+		if _, err := g.ExplainImplements("nope", "iface.go:5:Service"); !errors.Is(err, ErrNodeNotFound) {
+			t.Errorf("unknown type error = %v, want ErrNodeNotFound", err)
+		}
+		if _, err := g.ExplainImplements("server.go:1:Server", "nope"); !errors.Is(err, ErrNodeNotFound) {
+			t.Errorf("unknown interface error = %v, want ErrNodeNotFound", err)
+		}
+	})
+}
@@ -44,6 +44,25 @@ type ExternalDependency struct {
 
 	// Depth is the traversal depth at which this boundary was encountered.
 	Depth int
+
+	// Signature and Doc are the declaration and doc one-liner of Go
+	// standard library symbols, from the embedded stdlib index. Empty for
+	// other external symbols.
+	Signature string
+	Doc       string
+
+	// Members are the names used from the package when the graph
+	// collapses placeholders per package (PlaceholderCollapsed).
+	Members []string
+}
+
+// Label returns the dependency's qualified name, e.g. "os.MkdirAll", or
+// just the package for a package placeholder.
+func (d ExternalDependency) Label() string {
+	if d.Package == "" || d.Package == d.Name {
+		return d.Name
+	}
+	return d.Package + "." + d.Name
 }
 
 // ClassifyExternalNodes identifies external dependency boundaries in a traversal result.
@@ -100,6 +119,11 @@ func ClassifyExternalNodes(g *Graph, result *TraversalResult) []ExternalDependen
 			Package:    node.Symbol.Package,
 			CalledFrom: callerMap[nodeID],
 			Depth:      depthMap[nodeID],
+			Signature:  node.Symbol.Signature,
+			Doc:        node.Symbol.DocComment,
+		}
+		if node.Symbol.Metadata != nil {
+			dep.Members = node.Symbol.Metadata.ExternalMembers
 		}
 
 		// If Package is empty, try to infer from the Name's dot-prefix.
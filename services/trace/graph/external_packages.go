@@ -0,0 +1,101 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// ExternalPackage is the third-party package an external node belongs to,
+// as declared in the project's manifests and lockfiles.
+type ExternalPackage struct {
+	// Ecosystem is "Go", "npm" or "PyPI".
+	Ecosystem string `json:"ecosystem"`
+
+	// Name is the module path, npm package or PyPI distribution.
+	Name string `json:"name"`
+
+	// Version is the resolved version, or "" if only a range is declared.
+	Version string `json:"version,omitempty"`
+
+	// Direct is true if the project declares the package itself.
+	Direct bool `json:"direct"`
+}
+
+// RecordExternalPackages resolves external nodes to the packages that
+// provide them.
+//
+// Description:
+//
+//	Resolves the package of every external placeholder node. The import
+//	path is the node's Package; the language is that of the first symbol
+//	with an edge to the node, so "yaml" imported from Python resolves to
+//	PyYAML rather than an npm package. Nodes that resolve to nothing, such
+//	as standard library imports, are absent. A nil resolve clears the
+//	mapping.
+//
+// Inputs:
+//   - g: The graph to populate. Must not be nil.
+//   - resolve: Returns the package providing an import path.
+//
+// Thread Safety: Must be called before the graph is shared for reads.
+func RecordExternalPackages(g *Graph, resolve func(importPath, language string) (ExternalPackage, bool)) {
+	if g == nil {
+		return
+	}
+	g.ExternalPackages = nil
+	if resolve == nil {
+		return
+	}
+
+	resolved := make(map[string]ExternalPackage)
+	for _, node := range g.nodesByKind[ast.SymbolKindExternal] {
+		if node.Symbol == nil || node.Symbol.Package == "" {
+			continue
+		}
+		language := ""
+		for _, e := range node.Incoming {
+			if from, ok := g.nodes[e.FromID]; ok && from.Symbol != nil {
+				language = from.Symbol.Language
+				break
+			}
+		}
+		if pkg, ok := resolve(node.Symbol.Package, language); ok {
+			resolved[node.ID] = pkg
+		}
+	}
+	g.ExternalPackages = resolved
+
+	slog.Debug("Recorded external packages",
+		slog.Int("resolved_nodes", len(resolved)),
+		telemetry.SensitiveLog("project_root", g.ProjectRoot),
+	)
+}
+
+// ExternalPackageOf returns the package an external node belongs to.
+//
+// Outputs:
+//
+//	ExternalPackage - The package.
+//	bool - False if the node is not external or its package is not
+//	  declared by the project.
+//
+// Thread Safety: Safe for concurrent use.
+func (g *Graph) ExternalPackageOf(nodeID string) (ExternalPackage, bool) {
+	if g == nil {
+		return ExternalPackage{}, false
+	}
+	pkg, ok := g.ExternalPackages[nodeID]
+	return pkg, ok
+}
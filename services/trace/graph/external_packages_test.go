@@ -0,0 +1,57 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+func TestRecordExternalPackages(t *testing.T) {
+	g := NewGraph("/proj")
+	pyFn := &ast.Symbol{ID: "a.py:1:f", Name: "f", Kind: ast.SymbolKindFunction, FilePath: "a.py", StartLine: 1, EndLine: 2, Language: "python"}
+	yamlExt := &ast.Symbol{ID: "external:yaml:yaml.load", Name: "yaml.load", Kind: ast.SymbolKindExternal, Package: "yaml", Language: "external"}
+	osExt := &ast.Symbol{ID: "external:os:os.getcwd", Name: "os.getcwd", Kind: ast.SymbolKindExternal, Package: "os", Language: "external"}
+	for _, sym := range []*ast.Symbol{pyFn, yamlExt, osExt} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.AddEdge(pyFn.ID, yamlExt.ID, EdgeTypeCalls, ast.Location{FilePath: "a.py", StartLine: 1}); err != nil {
+		t.Fatal(err)
+	}
+	g.Freeze()
+
+	var gotLanguage string
+	RecordExternalPackages(g, func(importPath, language string) (ExternalPackage, bool) {
+		if importPath != "yaml" {
+			return ExternalPackage{}, false
+		}
+		gotLanguage = language
+		return ExternalPackage{Ecosystem: "PyPI", Name: "PyYAML", Version: "6.0"}, true
+	})
+
+	if gotLanguage != "python" {
+		t.Errorf("language = %q, want python", gotLanguage)
+	}
+	if pkg, ok := g.ExternalPackageOf(yamlExt.ID); !ok || pkg.Version != "6.0" {
+		t.Errorf("ExternalPackageOf(yaml) = %+v, %v", pkg, ok)
+	}
+	if _, ok := g.ExternalPackageOf(osExt.ID); ok {
+		t.Error("standard library module resolved")
+	}
+
+	RecordExternalPackages(g, nil)
+	if _, ok := g.ExternalPackageOf(yamlExt.ID); ok {
+		t.Error("nil resolver did not clear packages")
+	}
+}
@@ -687,3 +687,19 @@ func TestClassifyExternalNodes(t *testing.T) {
 		}
 	})
 }
+
+func TestExternalDependency_Label(t *testing.T) {
+	tests := []struct {
+		dep  ExternalDependency
+		want string
+	}{
+		{ExternalDependency{Name: "MkdirAll", Package: "os"}, "os.MkdirAll"},
+		{ExternalDependency{Name: "net/http", Package: "net/http"}, "net/http"},
+		{ExternalDependency{Name: "Connect"}, "Connect"},
+	}
+	for _, tt := range tests {
+		if got := tt.dep.Label(); got != tt.want {
+			t.Errorf("Label(%+v) = %q, want %q", tt.dep, got, tt.want)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"strings"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -189,8 +190,8 @@ func ClassifyFiles(hg *HierarchicalGraph, opts FileClassificationOptions) (*File
 	config, configErr := loadTraceConfig(opts.ProjectRoot)
 	if configErr != nil {
 		slog.Warn("GR-60: trace.config.yaml error, using defaults",
-			slog.String("error", configErr.Error()),
-			slog.String("project_root", opts.ProjectRoot),
+			telemetry.SensitiveLogError("error", configErr),
+			telemetry.SensitiveLog("project_root", opts.ProjectRoot),
 		)
 	}
 
@@ -360,7 +361,7 @@ func ClassifyFiles(hg *HierarchicalGraph, opts FileClassificationOptions) (*File
 				reclassified++
 				if callerPurityFailed && ratio >= 0.10 {
 					slog.Debug("GR-60c: Phase 6 caller purity reclassified file",
-						slog.String("file", filePath),
+						telemetry.SensitiveLog("file", filePath),
 						slog.Int("prod_in", prodIn),
 						slog.Int("orig_in", origIn),
 						slog.Float64("caller_purity", float64(prodIn)/float64(origIn)),
@@ -370,7 +371,7 @@ func ClassifyFiles(hg *HierarchicalGraph, opts FileClassificationOptions) (*File
 			} else if pass == 0 && strings.Contains(strings.ToLower(filePath), "test") {
 				// GR-60c diagnostic: log test-path files that survive Phase 6
 				slog.Debug("GR-60c: Phase 6 kept test-path file as production",
-					slog.String("file", filePath),
+					telemetry.SensitiveLog("file", filePath),
 					slog.Int("prod_in", prodIn),
 					slog.Int("prod_out", prodOut),
 					slog.Float64("prod_ratio", ratio),
@@ -0,0 +1,197 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"sort"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// buildFileIntervals builds the per-file interval index used by
+// SymbolAt and SymbolsInFile.
+//
+// Description:
+//
+//	Groups nodes by Symbol.FilePath and sorts each group by start
+//	position ascending, then end position descending, so an enclosing
+//	symbol always precedes the symbols nested inside it. Ties are
+//	broken by ID for deterministic ordering.
+//
+// Thread Safety:
+//
+//	NOT safe for concurrent use. Called by Freeze() only.
+func (g *Graph) buildFileIntervals() {
+	intervals := make(map[string][]*Node)
+	for _, node := range g.nodes {
+		if node.Symbol == nil || node.Symbol.FilePath == "" {
+			continue
+		}
+		intervals[node.Symbol.FilePath] = append(intervals[node.Symbol.FilePath], node)
+	}
+
+	for _, nodes := range intervals {
+		sort.Slice(nodes, func(i, j int) bool {
+			a, b := nodes[i].Symbol, nodes[j].Symbol
+			if a.StartLine != b.StartLine {
+				return a.StartLine < b.StartLine
+			}
+			if a.StartCol != b.StartCol {
+				return a.StartCol < b.StartCol
+			}
+			aEnd, bEnd := symbolEndLine(a), symbolEndLine(b)
+			if aEnd != bEnd {
+				return aEnd > bEnd
+			}
+			if a.EndCol != b.EndCol {
+				return a.EndCol > b.EndCol
+			}
+			return a.ID < b.ID
+		})
+	}
+	g.fileIntervals = intervals
+}
+
+// SymbolsInFile returns the nodes defined in a file in source order.
+//
+// Description:
+//
+//	Uses the per-file interval index built by Freeze(). Nodes are ordered
+//	by start position; a symbol precedes the symbols nested inside it.
+//	Returns a defensive copy.
+//
+// Inputs:
+//
+//	filePath - The relative file path, as stored in Symbol.FilePath.
+//
+// Outputs:
+//
+//	[]*Node - Nodes from that file. Empty slice if none found or the
+//	graph is not frozen.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) SymbolsInFile(filePath string) []*Node {
+	nodes := g.fileIntervals[filePath]
+	result := make([]*Node, len(nodes))
+	copy(result, nodes)
+	return result
+}
+
+// SymbolAt returns the innermost node whose source range contains a
+// position.
+//
+// Description:
+//
+//	Uses the per-file interval index built by Freeze(). Candidates are
+//	the nodes starting at or before line; the last candidate (in source
+//	order) whose range contains the position is the innermost one, e.g.
+//	a method rather than the class around it.
+//
+//	Columns are only compared when they carry information: a symbol
+//	whose end column is unknown (zero) is treated as extending to the
+//	end of its last line. Pass col < 0 to ignore columns entirely.
+//
+// Inputs:
+//
+//	filePath - The relative file path, as stored in Symbol.FilePath.
+//	line - 1-indexed line number.
+//	col - 0-indexed column, or negative to match whole lines.
+//
+// Outputs:
+//
+//	*Node - The innermost enclosing node, or nil if none.
+//
+// Complexity:
+//
+//	O(log n + k) where n is the number of nodes in the file and k the
+//	number of nodes starting at or before line.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) SymbolAt(filePath string, line, col int) *Node {
+	nodes := g.fileIntervals[filePath]
+	end := sort.Search(len(nodes), func(i int) bool {
+		return nodes[i].Symbol.StartLine > line
+	})
+	for i := end - 1; i >= 0; i-- {
+		if symbolContains(nodes[i].Symbol, line, col) {
+			return nodes[i]
+		}
+	}
+	return nil
+}
+
+// SymbolsAt returns every node whose source range contains a position,
+// innermost first.
+//
+// Description:
+//
+//	Like SymbolAt, but also returns the enclosing symbols: for a position
+//	inside a method, the method followed by its class. Positions are
+//	compared as described on SymbolAt.
+//
+// Inputs:
+//
+//	filePath - The relative file path, as stored in Symbol.FilePath.
+//	line - 1-indexed line number.
+//	col - 0-indexed column, or negative to match whole lines.
+//
+// Outputs:
+//
+//	[]*Node - Enclosing nodes, innermost first. Empty if none.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) SymbolsAt(filePath string, line, col int) []*Node {
+	nodes := g.fileIntervals[filePath]
+	end := sort.Search(len(nodes), func(i int) bool {
+		return nodes[i].Symbol.StartLine > line
+	})
+	result := make([]*Node, 0)
+	for i := end - 1; i >= 0; i-- {
+		if symbolContains(nodes[i].Symbol, line, col) {
+			result = append(result, nodes[i])
+		}
+	}
+	return result
+}
+
+// symbolContains reports whether sym's source range contains the
+// position (line, col). See SymbolAt for how columns are compared.
+func symbolContains(sym *ast.Symbol, line, col int) bool {
+	endLine := symbolEndLine(sym)
+	if line < sym.StartLine || line > endLine {
+		return false
+	}
+	if col < 0 {
+		return true
+	}
+	if line == sym.StartLine && col < sym.StartCol {
+		return false
+	}
+	if line == endLine && sym.EndCol > 0 && col > sym.EndCol {
+		return false
+	}
+	return true
+}
+
+// symbolEndLine returns sym.EndLine, or StartLine for symbols that do not
+// record an end.
+func symbolEndLine(sym *ast.Symbol) int {
+	if sym.EndLine < sym.StartLine {
+		return sym.StartLine
+	}
+	return sym.EndLine
+}
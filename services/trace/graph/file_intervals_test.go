@@ -0,0 +1,129 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// makeRangedSymbol creates a test symbol spanning the given range.
+func makeRangedSymbol(id, filePath string, startLine, startCol, endLine, endCol int) *ast.Symbol {
+	sym := makeSymbol(id, id, ast.SymbolKindFunction, filePath)
+	sym.StartLine, sym.StartCol = startLine, startCol
+	sym.EndLine, sym.EndCol = endLine, endCol
+	return sym
+}
+
+func newIntervalTestGraph(t *testing.T) *Graph {
+	t.Helper()
+	g := NewGraph("/project")
+	for _, sym := range []*ast.Symbol{
+		makeRangedSymbol("Server", "server.go", 3, 0, 20, 1),
+		makeRangedSymbol("Start", "server.go", 5, 1, 9, 2),
+		makeRangedSymbol("Stop", "server.go", 11, 1, 14, 2),
+		makeRangedSymbol("port", "server.go", 16, 1, 16, 10),
+		makeRangedSymbol("main", "main.go", 1, 0, 4, 1),
+		makeRangedSymbol("version", "server.go", 1, 0, 0, 0), // no end recorded
+	} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode(%s): %v", sym.ID, err)
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+func TestGraph_SymbolAt(t *testing.T) {
+	g := newIntervalTestGraph(t)
+
+	tests := []struct {
+		name string
+		file string
+		line int
+		col  int
+		want string
+	}{
+		{"nested method", "server.go", 7, 4, "Start"},
+		{"between methods", "server.go", 10, 0, "Server"},
+		{"second method", "server.go", 12, 3, "Stop"},
+		{"single-line symbol", "server.go", 16, 5, "port"},
+		{"past end column", "server.go", 16, 12, "Server"},
+		{"before start column", "server.go", 5, 0, "Server"},
+		{"ignore columns", "server.go", 5, -1, "Start"},
+		{"symbol without end", "server.go", 1, 8, "version"},
+		{"outside all symbols", "server.go", 25, 0, ""},
+		{"other file", "main.go", 2, 0, "main"},
+		{"unknown file", "missing.go", 2, 0, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			node := g.SymbolAt(tc.file, tc.line, tc.col)
+			got := ""
+			if node != nil {
+				got = node.ID
+			}
+			if got != tc.want {
+				t.Errorf("SymbolAt(%s, %d, %d) = %q, want %q", tc.file, tc.line, tc.col, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGraph_SymbolsAt(t *testing.T) {
+	g := newIntervalTestGraph(t)
+
+	var got []string
+	for _, node := range g.SymbolsAt("server.go", 7, 4) {
+		got = append(got, node.ID)
+	}
+	if len(got) != 2 || got[0] != "Start" || got[1] != "Server" {
+		t.Errorf("SymbolsAt(server.go, 7, 4) = %v, want [Start Server]", got)
+	}
+	if nodes := g.SymbolsAt("server.go", 25, 0); len(nodes) != 0 {
+		t.Errorf("SymbolsAt outside all symbols = %d nodes, want 0", len(nodes))
+	}
+}
+
+func TestGraph_SymbolsInFile(t *testing.T) {
+	g := newIntervalTestGraph(t)
+
+	var got []string
+	for _, node := range g.SymbolsInFile("server.go") {
+		got = append(got, node.ID)
+	}
+	want := []string{"version", "Server", "Start", "Stop", "port"}
+	if len(got) != len(want) {
+		t.Fatalf("SymbolsInFile = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("SymbolsInFile = %v, want %v", got, want)
+		}
+	}
+
+	if nodes := g.SymbolsInFile("missing.go"); len(nodes) != 0 {
+		t.Errorf("SymbolsInFile(missing.go) = %d nodes, want 0", len(nodes))
+	}
+
+	// Unfrozen graphs have no interval index.
+	building := NewGraph("/project")
+	if _, err := building.AddNode(makeRangedSymbol("x", "x.go", 1, 0, 2, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if node := building.SymbolAt("x.go", 1, 0); node != nil {
+		t.Errorf("SymbolAt on unfrozen graph = %s, want nil", node.ID)
+	}
+	if nodes := building.GetNodesByFile("x.go"); len(nodes) != 1 {
+		t.Errorf("GetNodesByFile on unfrozen graph = %d nodes, want 1", len(nodes))
+	}
+}
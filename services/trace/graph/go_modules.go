@@ -0,0 +1,225 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"golang.org/x/mod/modfile"
+)
+
+// GoModule is a Go module whose source is in the project.
+//
+// Description:
+//
+//	Imports under Path are packages in the directories under Dir: with
+//	Path "example.com/billing" and Dir "services/billing", the import
+//	"example.com/billing/invoice" is the package in
+//	services/billing/invoice. Each go.mod declares its own module; a
+//	replace directive pointing at a directory in the project declares the
+//	replaced module path there too.
+type GoModule struct {
+	// Path is the module path.
+	Path string `json:"path"`
+
+	// Dir is the project-relative module directory, "" for the project
+	// root.
+	Dir string `json:"dir"`
+}
+
+// GoModuleConfigFiles are the file names ParseGoModuleConfig reads.
+var GoModuleConfigFiles = []string{"go.mod", "go.work"}
+
+// ParseGoModuleConfig returns the project modules a go.mod or go.work
+// declares.
+//
+// Description:
+//
+//	A go.mod declares its module directive, in the go.mod's directory,
+//	and its replace directives whose target is a local directory. A
+//	go.work declares its replace directives; the modules it uses declare
+//	themselves in their own go.mod files. Directories are resolved
+//	against the config file's directory; replacements outside the project
+//	are left out.
+//
+// Inputs:
+//
+//	configPath - Project-relative path of the config file; its base name
+//	  selects the format.
+//	data - The file content.
+//
+// Outputs:
+//
+//	[]GoModule - The declared modules, sorted by Path. Nil for other file
+//	  names.
+//	error - Non-nil if the file does not parse.
+//
+// Thread Safety: Safe for concurrent use (pure function).
+func ParseGoModuleConfig(configPath string, data []byte) ([]GoModule, error) {
+	baseDir := path.Dir(configPath)
+	var raw []GoModule
+	var replaces []*modfile.Replace
+	switch path.Base(configPath) {
+	case "go.mod":
+		f, err := modfile.Parse(configPath, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		if f.Module != nil && f.Module.Mod.Path != "" {
+			raw = append(raw, GoModule{Path: f.Module.Mod.Path, Dir: "."})
+		}
+		replaces = f.Replace
+	case "go.work":
+		f, err := modfile.ParseWork(configPath, data, nil)
+		if err != nil {
+			return nil, err
+		}
+		replaces = f.Replace
+	default:
+		return nil, nil
+	}
+	for _, r := range replaces {
+		if r.New.Version != "" || !modfile.IsDirectoryPath(r.New.Path) || path.IsAbs(r.New.Path) {
+			continue
+		}
+		raw = append(raw, GoModule{Path: r.Old.Path, Dir: r.New.Path})
+	}
+
+	seen := make(map[GoModule]bool)
+	var modules []GoModule
+	for _, m := range raw {
+		dir := path.Clean(path.Join(baseDir, m.Dir))
+		if dir == "." {
+			dir = ""
+		}
+		if dir == ".." || strings.HasPrefix(dir, "../") || strings.HasPrefix(dir, "/") {
+			continue
+		}
+		module := GoModule{Path: m.Path, Dir: dir}
+		if seen[module] {
+			continue
+		}
+		seen[module] = true
+		modules = append(modules, module)
+	}
+	sortGoModules(modules)
+	return modules, nil
+}
+
+// sortGoModules orders modules by Path, then Dir.
+func sortGoModules(modules []GoModule) {
+	sort.Slice(modules, func(i, j int) bool {
+		if modules[i].Path != modules[j].Path {
+			return modules[i].Path < modules[j].Path
+		}
+		return modules[i].Dir < modules[j].Dir
+	})
+}
+
+// goImportDir returns the project directory of the package importPath
+// names, under the module with the longest matching path.
+func goImportDir(modules []GoModule, importPath string) (string, bool) {
+	best := -1
+	for i, m := range modules {
+		if importPath != m.Path && !strings.HasPrefix(importPath, m.Path+"/") {
+			continue
+		}
+		if best < 0 || len(m.Path) > len(modules[best].Path) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	rest := strings.TrimPrefix(importPath[len(modules[best].Path):], "/")
+	if modules[best].Dir == "" {
+		return rest, true
+	}
+	return path.Join(modules[best].Dir, rest), true
+}
+
+// goModuleDirOf returns the Dir of the module holding the file, the one
+// with the longest Dir containing it.
+func goModuleDirOf(modules []GoModule, filePath string) (string, bool) {
+	dir := goFileDir(filePath)
+	best, found := "", false
+	for _, m := range modules {
+		if m.Dir != "" && dir != m.Dir && !strings.HasPrefix(dir, m.Dir+"/") {
+			continue
+		}
+		if !found || len(m.Dir) > len(best) {
+			best, found = m.Dir, true
+		}
+	}
+	return best, found
+}
+
+// crossesGoModules reports whether two files are in different project
+// modules.
+func (b *Builder) crossesGoModules(fromFile, toFile string) bool {
+	if len(b.options.GoModules) < 2 {
+		return false
+	}
+	from, ok := goModuleDirOf(b.options.GoModules, fromFile)
+	if !ok {
+		return false
+	}
+	to, ok := goModuleDirOf(b.options.GoModules, toFile)
+	return ok && from != to
+}
+
+// goModulePackage returns the package symbol of a Go import path under a
+// project module, or "" if no module holds the path or no file of the
+// directory was parsed.
+//
+// Description:
+//
+//	The package symbol is found by the last element of the import path,
+//	the package name of all but unusual layouts. Of a package's files,
+//	the symbol with the lowest ID is taken, so the choice is stable.
+func (b *Builder) goModulePackage(state *buildState, importPath string) string {
+	dir, ok := goImportDir(b.options.GoModules, importPath)
+	if !ok {
+		return ""
+	}
+	id := ""
+	for _, sym := range state.symbolsByName[path.Base(importPath)] {
+		if sym == nil || sym.Kind != ast.SymbolKindPackage || sym.Language != "go" || goFileDir(sym.FilePath) != dir {
+			continue
+		}
+		if id == "" || sym.ID < id {
+			id = sym.ID
+		}
+	}
+	return id
+}
+
+// goFileDir returns the directory of a project file, "" for the project
+// root.
+func goFileDir(filePath string) string {
+	if dir := path.Dir(filePath); dir != "." {
+		return dir
+	}
+	return ""
+}
+
+// isGoModulePackage reports whether sym is the package symbol of a
+// project package with the given import path.
+func (g *Graph) isGoModulePackage(sym *ast.Symbol, importPath string) bool {
+	if sym.Kind != ast.SymbolKindPackage || len(g.GoModules) == 0 {
+		return false
+	}
+	dir, ok := goImportDir(g.GoModules, importPath)
+	return ok && goFileDir(sym.FilePath) == dir
+}
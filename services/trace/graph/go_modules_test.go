@@ -0,0 +1,99 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseGoModuleConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		data string
+		want []GoModule
+	}{
+		{
+			name: "module and local replace",
+			path: "services/api/go.mod",
+			data: "module example.com/acme/api\n\ngo 1.22\n\nreplace (\n\texample.com/acme/billing => ../billing\n\texample.com/ledger v1.2.0 => ../../third_party/ledger\n)\n",
+			want: []GoModule{
+				{Path: "example.com/acme/api", Dir: "services/api"},
+				{Path: "example.com/acme/billing", Dir: "services/billing"},
+				{Path: "example.com/ledger", Dir: "third_party/ledger"},
+			},
+		},
+		{
+			name: "root module",
+			path: "go.mod",
+			data: "module example.com/acme\n",
+			want: []GoModule{{Path: "example.com/acme", Dir: ""}},
+		},
+		{
+			name: "versioned, absolute and outside replacements ignored",
+			path: "go.mod",
+			data: "module example.com/acme\n\nreplace example.com/a => example.com/b v1.0.0\nreplace example.com/c => /opt/c\nreplace example.com/d => ../d\n",
+			want: []GoModule{{Path: "example.com/acme", Dir: ""}},
+		},
+		{
+			name: "go.work replace",
+			path: "go.work",
+			data: "go 1.22\n\nuse (\n\t./api\n\t./billing\n)\n\nreplace example.com/ledger => ./third_party/ledger\n",
+			want: []GoModule{{Path: "example.com/ledger", Dir: "third_party/ledger"}},
+		},
+		{
+			name: "other file",
+			path: "go.sum",
+			data: "example.com/a v1.0.0 h1:abc=\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseGoModuleConfig(tt.path, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseGoModuleConfig: %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("modules = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseGoModuleConfig("go.mod", []byte("module\n")); err == nil {
+		t.Error("expected an error for a malformed go.mod")
+	}
+}
+
+func TestGoImportDir(t *testing.T) {
+	modules := []GoModule{
+		{Path: "example.com/acme", Dir: ""},
+		{Path: "example.com/acme/billing", Dir: "services/billing"},
+	}
+	tests := []struct {
+		importPath string
+		want       string
+		wantOK     bool
+	}{
+		{"example.com/acme/billing/invoice", "services/billing/invoice", true},
+		{"example.com/acme/billing", "services/billing", true},
+		{"example.com/acme/internal/db", "internal/db", true},
+		{"example.com/acme", "", true},
+		{"example.com/acmewidgets", "", false},
+		{"fmt", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := goImportDir(modules, tt.importPath)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("goImportDir(%q) = %q, %v; want %q, %v", tt.importPath, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
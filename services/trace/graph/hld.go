@@ -23,6 +23,8 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Sentinel errors for HLD operations.
@@ -149,7 +151,7 @@ func BuildHLD(ctx context.Context, g *Graph, root string) (*HLDecomposition, err
 
 	ctx, span := otel.Tracer("trace").Start(ctx, "graph.BuildHLD",
 		trace.WithAttributes(
-			attribute.String("root", root),
+			telemetry.SensitiveString("root", root),
 		),
 	)
 	defer span.End()
@@ -161,7 +163,7 @@ func BuildHLD(ctx context.Context, g *Graph, root string) (*HLDecomposition, err
 	// Phase 2: Tree Structure Validation
 	span.AddEvent("validating_tree")
 	if err := g.IsTree(ctx, root); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "invalid tree")
 		return nil, fmt.Errorf("%w: %v", ErrInvalidTree, err)
 	}
@@ -170,7 +172,7 @@ func BuildHLD(ctx context.Context, g *Graph, root string) (*HLDecomposition, err
 	span.AddEvent("initializing_hld")
 	hld, err := initializeHLD(g, root)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "initialization failed")
 		return nil, fmt.Errorf("initialize HLD: %w", err)
 	}
@@ -185,7 +187,7 @@ func BuildHLD(ctx context.Context, g *Graph, root string) (*HLDecomposition, err
 	span.AddEvent("computing_subtree_sizes")
 	subtreeStart := time.Now()
 	if err := hld.dfsSubtreeSize(ctx, hld.root, -1, 0, adj); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "subtree size computation failed")
 		return nil, fmt.Errorf("compute subtree sizes: %w", err)
 	}
@@ -204,7 +206,7 @@ func BuildHLD(ctx context.Context, g *Graph, root string) (*HLDecomposition, err
 	span.AddEvent("decomposing_paths")
 	pos := 0
 	if err := hld.dfsDecompose(ctx, hld.root, hld.root, &pos, adj); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "path decomposition failed")
 		return nil, fmt.Errorf("decompose paths: %w", err)
 	}
@@ -212,13 +214,13 @@ func BuildHLD(ctx context.Context, g *Graph, root string) (*HLDecomposition, err
 	// Phase 8: Validation
 	span.AddEvent("validating_hld")
 	if err := hld.Validate(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "validation failed")
 		recordHLDValidationError(ctx)
 		slog.Error("HLD validation failed",
-			slog.String("root", root),
+			telemetry.SensitiveLog("root", root),
 			slog.Int("node_count", hld.nodeCount),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
 	}
@@ -242,7 +244,7 @@ func BuildHLD(ctx context.Context, g *Graph, root string) (*HLDecomposition, err
 	hld.graphHash = g.Hash()
 
 	slog.Info("HLD construction complete",
-		slog.String("root", root),
+		telemetry.SensitiveLog("root", root),
 		slog.Int("node_count", hld.nodeCount),
 		slog.Int("heavy_paths", stats.HeavyPathCount),
 		slog.Int("light_edges", stats.LightEdgeCount),
@@ -22,6 +22,8 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // dfsFrame represents a stack frame for iterative DFS traversal.
@@ -231,7 +233,7 @@ func buildHLDInternal(ctx context.Context, g *Graph, root string, skipTreeValida
 
 	ctx, span := otel.Tracer("trace").Start(ctx, "graph.BuildHLD",
 		trace.WithAttributes(
-			attribute.String("root", root),
+			telemetry.SensitiveString("root", root),
 			attribute.Bool("skip_tree_validation", skipTreeValidation),
 		),
 	)
@@ -243,7 +245,7 @@ func buildHLDInternal(ctx context.Context, g *Graph, root string, skipTreeValida
 	if !skipTreeValidation {
 		span.AddEvent("validating_tree")
 		if err := g.IsTree(ctx, root); err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "invalid tree")
 			return nil, fmt.Errorf("%w: %v", ErrInvalidTree, err)
 		}
@@ -260,7 +262,7 @@ func buildHLDInternal(ctx context.Context, g *Graph, root string, skipTreeValida
 		visited := make(map[string]bool)
 		component := []string{}
 		if err := collectComponent(ctx, g, root, visited, &component); err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "component collection failed")
 			return nil, fmt.Errorf("collect component: %w", err)
 		}
@@ -268,7 +270,7 @@ func buildHLDInternal(ctx context.Context, g *Graph, root string, skipTreeValida
 
 		hld, err = initializeHLDForComponent(component, root)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "initialization failed")
 			return nil, fmt.Errorf("initialize HLD for component: %w", err)
 		}
@@ -276,7 +278,7 @@ func buildHLDInternal(ctx context.Context, g *Graph, root string, skipTreeValida
 		// Single tree mode: use all graph nodes
 		hld, err = initializeHLD(g, root)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "initialization failed")
 			return nil, fmt.Errorf("initialize HLD: %w", err)
 		}
@@ -291,7 +293,7 @@ func buildHLDInternal(ctx context.Context, g *Graph, root string, skipTreeValida
 	// Phase 5: DFS - compute subtree sizes
 	span.AddEvent("computing_subtree_sizes")
 	if err := hld.dfsSubtreeSize(ctx, hld.root, -1, 0, adj); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "subtree size computation failed")
 		return nil, fmt.Errorf("compute subtree sizes: %w", err)
 	}
@@ -304,7 +306,7 @@ func buildHLDInternal(ctx context.Context, g *Graph, root string, skipTreeValida
 	span.AddEvent("decomposing_paths")
 	pos := 0
 	if err := hld.dfsDecompose(ctx, hld.root, hld.root, &pos, adj); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "path decomposition failed")
 		return nil, fmt.Errorf("decompose paths: %w", err)
 	}
@@ -312,13 +314,13 @@ func buildHLDInternal(ctx context.Context, g *Graph, root string, skipTreeValida
 	// Phase 8: Validation
 	span.AddEvent("validating_hld")
 	if err := hld.Validate(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "validation failed")
 		recordHLDValidationError(ctx)
 		slog.Error("HLD validation failed",
-			slog.String("root", root),
+			telemetry.SensitiveLog("root", root),
 			slog.Int("node_count", hld.nodeCount),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
 	}
@@ -339,7 +341,7 @@ func buildHLDInternal(ctx context.Context, g *Graph, root string, skipTreeValida
 
 	span.SetStatus(codes.Ok, "HLD constructed successfully")
 	slog.Info("HLD construction complete",
-		slog.String("root", root),
+		telemetry.SensitiveLog("root", root),
 		slog.Int("node_count", hld.nodeCount),
 		slog.Int("heavy_paths", stats.HeavyPathCount),
 		slog.Int("light_edges", stats.LightEdgeCount),
@@ -359,7 +361,7 @@ func buildHLDIterativeInternal(ctx context.Context, g *Graph, root string, skipT
 
 	ctx, span := otel.Tracer("trace").Start(ctx, "graph.BuildHLDIterative",
 		trace.WithAttributes(
-			attribute.String("root", root),
+			telemetry.SensitiveString("root", root),
 			attribute.Bool("iterative", true),
 			attribute.Bool("skip_tree_validation", skipTreeValidation),
 		),
@@ -372,7 +374,7 @@ func buildHLDIterativeInternal(ctx context.Context, g *Graph, root string, skipT
 	if !skipTreeValidation {
 		span.AddEvent("validating_tree")
 		if err := g.IsTree(ctx, root); err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "invalid tree")
 			return nil, fmt.Errorf("%w: %v", ErrInvalidTree, err)
 		}
@@ -389,7 +391,7 @@ func buildHLDIterativeInternal(ctx context.Context, g *Graph, root string, skipT
 		visited := make(map[string]bool)
 		component := []string{}
 		if err := collectComponent(ctx, g, root, visited, &component); err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "component collection failed")
 			return nil, fmt.Errorf("collect component: %w", err)
 		}
@@ -397,7 +399,7 @@ func buildHLDIterativeInternal(ctx context.Context, g *Graph, root string, skipT
 
 		hld, err = initializeHLDForComponent(component, root)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "initialization failed")
 			return nil, fmt.Errorf("initialize HLD for component: %w", err)
 		}
@@ -405,7 +407,7 @@ func buildHLDIterativeInternal(ctx context.Context, g *Graph, root string, skipT
 		// Single tree mode: use all graph nodes
 		hld, err = initializeHLD(g, root)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "initialization failed")
 			return nil, fmt.Errorf("initialize HLD: %w", err)
 		}
@@ -420,7 +422,7 @@ func buildHLDIterativeInternal(ctx context.Context, g *Graph, root string, skipT
 	// Phase 5: Iterative DFS - compute subtree sizes
 	span.AddEvent("computing_subtree_sizes_iterative")
 	if err := hld.dfsSubtreeSizeIterative(ctx, hld.root, adj); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "subtree size computation failed")
 		return nil, fmt.Errorf("compute subtree sizes: %w", err)
 	}
@@ -433,7 +435,7 @@ func buildHLDIterativeInternal(ctx context.Context, g *Graph, root string, skipT
 	span.AddEvent("decomposing_paths_iterative")
 	pos := 0
 	if err := hld.dfsDecomposeIterative(ctx, hld.root, hld.root, &pos, adj); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "path decomposition failed")
 		return nil, fmt.Errorf("decompose paths: %w", err)
 	}
@@ -441,13 +443,13 @@ func buildHLDIterativeInternal(ctx context.Context, g *Graph, root string, skipT
 	// Phase 8: Validation
 	span.AddEvent("validating_hld")
 	if err := hld.Validate(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "validation failed")
 		recordHLDValidationError(ctx)
 		slog.Error("HLD validation failed",
-			slog.String("root", root),
+			telemetry.SensitiveLog("root", root),
 			slog.Int("node_count", hld.nodeCount),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
 	}
@@ -471,7 +473,7 @@ func buildHLDIterativeInternal(ctx context.Context, g *Graph, root string, skipT
 
 	span.SetStatus(codes.Ok, "HLD constructed successfully (iterative)")
 	slog.Info("HLD construction complete (iterative)",
-		slog.String("root", root),
+		telemetry.SensitiveLog("root", root),
 		slog.Int("node_count", hld.nodeCount),
 		slog.Int("heavy_paths", stats.HeavyPathCount),
 		slog.Int("light_edges", stats.LightEdgeCount),
@@ -524,7 +526,7 @@ func BuildHLDIterative(ctx context.Context, g *Graph, root string) (*HLDecomposi
 
 	ctx, span := otel.Tracer("trace").Start(ctx, "graph.BuildHLDIterative",
 		trace.WithAttributes(
-			attribute.String("root", root),
+			telemetry.SensitiveString("root", root),
 			attribute.Bool("iterative", true),
 		),
 	)
@@ -537,7 +539,7 @@ func BuildHLDIterative(ctx context.Context, g *Graph, root string) (*HLDecomposi
 	// Phase 2: Tree Structure Validation
 	span.AddEvent("validating_tree")
 	if err := g.IsTree(ctx, root); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "invalid tree")
 		return nil, fmt.Errorf("%w: %v", ErrInvalidTree, err)
 	}
@@ -546,7 +548,7 @@ func BuildHLDIterative(ctx context.Context, g *Graph, root string) (*HLDecomposi
 	span.AddEvent("initializing_hld")
 	hld, err := initializeHLD(g, root)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "initialization failed")
 		return nil, fmt.Errorf("initialize HLD: %w", err)
 	}
@@ -560,7 +562,7 @@ func BuildHLDIterative(ctx context.Context, g *Graph, root string) (*HLDecomposi
 	// Phase 5: Iterative DFS - compute subtree sizes
 	span.AddEvent("computing_subtree_sizes_iterative")
 	if err := hld.dfsSubtreeSizeIterative(ctx, hld.root, adj); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "subtree size computation failed")
 		return nil, fmt.Errorf("compute subtree sizes: %w", err)
 	}
@@ -573,7 +575,7 @@ func BuildHLDIterative(ctx context.Context, g *Graph, root string) (*HLDecomposi
 	span.AddEvent("decomposing_paths_iterative")
 	pos := 0
 	if err := hld.dfsDecomposeIterative(ctx, hld.root, hld.root, &pos, adj); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "path decomposition failed")
 		return nil, fmt.Errorf("decompose paths: %w", err)
 	}
@@ -581,13 +583,13 @@ func BuildHLDIterative(ctx context.Context, g *Graph, root string) (*HLDecomposi
 	// Phase 8: Validation
 	span.AddEvent("validating_hld")
 	if err := hld.Validate(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "validation failed")
 		recordHLDValidationError(ctx)
 		slog.Error("HLD validation failed",
-			slog.String("root", root),
+			telemetry.SensitiveLog("root", root),
 			slog.Int("node_count", hld.nodeCount),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, fmt.Errorf("%w: %v", ErrValidationFailed, err)
 	}
@@ -611,7 +613,7 @@ func BuildHLDIterative(ctx context.Context, g *Graph, root string) (*HLDecomposi
 
 	span.SetStatus(codes.Ok, "HLD constructed successfully (iterative)")
 	slog.Info("HLD construction complete (iterative)",
-		slog.String("root", root),
+		telemetry.SensitiveLog("root", root),
 		slog.Int("node_count", hld.nodeCount),
 		slog.Int("heavy_paths", stats.HeavyPathCount),
 		slog.Int("light_edges", stats.LightEdgeCount),
@@ -689,7 +691,7 @@ func BuildHLDForest(ctx context.Context, g *Graph, useIterative bool) (*HLDFores
 	span.AddEvent("finding_connected_components")
 	roots, err := findForestRoots(ctx, g)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "failed to find roots")
 		return nil, fmt.Errorf("find forest roots: %w", err)
 	}
@@ -708,10 +710,8 @@ func BuildHLDForest(ctx context.Context, g *Graph, useIterative bool) (*HLDFores
 	}
 
 	for i, root := range roots {
-		span.AddEvent(fmt.Sprintf("building_tree_%d", i),
-			trace.WithAttributes(
-				attribute.String("root", root),
-			),
+		telemetry.AddSpanEvent(span, fmt.Sprintf("building_tree_%d", i),
+			telemetry.SensitiveString("root", root),
 		)
 
 		var hld *HLDecomposition
@@ -724,7 +724,7 @@ func BuildHLDForest(ctx context.Context, g *Graph, useIterative bool) (*HLDFores
 		}
 
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, fmt.Sprintf("failed to build tree %d", i))
 			return nil, fmt.Errorf("build HLD for tree %d (root=%s): %w", i, root, err)
 		}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.opentelemetry.io/otel"
@@ -543,8 +544,8 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 	// Create OpenTelemetry span
 	ctx, span := otel.Tracer("trace").Start(ctx, "graph.PathQueryEngine.PathQuery",
 		trace.WithAttributes(
-			attribute.String("node_u", u),
-			attribute.String("node_v", v),
+			telemetry.SensitiveString("node_u", u),
+			telemetry.SensitiveString("node_v", v),
 			attribute.String("agg_func", pqe.aggFunc.String()),
 		),
 	)
@@ -568,7 +569,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 			treeHLD := pqe.forest.GetHLD(u)
 			if treeHLD == nil {
 				err := fmt.Errorf("HLD not found for node %s", u)
-				span.RecordError(err)
+				telemetry.RecordException(span, err)
 				queryErr = err
 				return 0, err
 			}
@@ -577,7 +578,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 
 		if !ok {
 			err := fmt.Errorf("node %s not found", u)
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			queryErr = err
 			return 0, err
 		}
@@ -593,7 +594,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 			treeOffset, offsetErr := pqe.forest.GetTreeOffset(u)
 			if offsetErr != nil {
 				err := fmt.Errorf("getting tree offset for node %s: %w", u, offsetErr)
-				span.RecordError(err)
+				telemetry.RecordException(span, err)
 				queryErr = err
 				return 0, err
 			}
@@ -603,7 +604,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 
 		result, err := pqe.segTree.Query(ctx, pos, pos)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			queryErr = fmt.Errorf("querying node %s: %w", u, err)
 			return 0, queryErr
 		}
@@ -641,7 +642,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 	// C3: Forest mode - validate nodes in same tree
 	if pqe.forest != nil {
 		if err := pqe.checkTreeComponent(u, v); err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "cross-tree query")
 			queryErr = err
 			return 0, queryErr
@@ -678,7 +679,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 	span.AddEvent("computing_lca")
 	lca, err := pqe.computeLCAWithCache(ctx, u, v)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "LCA failed")
 		queryErr = fmt.Errorf("computing LCA for path %s->%s: %w", u, v, err)
 		return 0, queryErr
@@ -696,7 +697,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 		treeHLD := pqe.forest.GetHLD(u)
 		if treeHLD == nil {
 			err := fmt.Errorf("HLD not found for node %s", u)
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "forest HLD lookup failed")
 			queryErr = err
 			return 0, queryErr
@@ -725,7 +726,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 	}
 
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "decomposition failed")
 		queryErr = fmt.Errorf("decomposing path %s->%s: %w", u, v, err)
 		return 0, queryErr
@@ -734,7 +735,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 	// L3: Check depth limit
 	if len(segments) > pqe.opts.MaxTreeDepth {
 		err := fmt.Errorf("path depth %d exceeds maximum %d", len(segments), pqe.opts.MaxTreeDepth)
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "depth limit exceeded")
 		queryErr = err
 		return 0, queryErr
@@ -751,7 +752,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 		lcaIdx, ok := pqe.hld.NodeToIdx(lca)
 		if !ok {
 			err := fmt.Errorf("LCA node %s not found in HLD", lca)
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			queryErr = err
 			return 0, queryErr
 		}
@@ -761,14 +762,14 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 		treeHLD := pqe.forest.GetHLD(lca)
 		if treeHLD == nil {
 			err := fmt.Errorf("HLD not found for LCA node %s", lca)
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			queryErr = err
 			return 0, queryErr
 		}
 		lcaIdx, ok := treeHLD.NodeToIdx(lca)
 		if !ok {
 			err := fmt.Errorf("LCA node %s not found in tree HLD", lca)
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			queryErr = err
 			return 0, queryErr
 		}
@@ -776,7 +777,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 		// Get tree offset for global segment tree position
 		treeOffset, err := pqe.forest.GetTreeOffset(lca)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			queryErr = fmt.Errorf("getting tree offset for LCA %s: %w", lca, err)
 			return 0, queryErr
 		}
@@ -787,7 +788,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 
 	result, err := pqe.querySegments(ctx, segments, lcaPos, logger, span)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "segment query failed")
 		queryErr = fmt.Errorf("querying segments for path %s->%s: %w", u, v, err)
 		return 0, queryErr
@@ -814,7 +815,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 	}
 
 	span.SetAttributes(
-		attribute.String("lca", lca),
+		telemetry.SensitiveString("lca", lca),
 		attribute.Int("segment_count", len(segments)),
 		attribute.Int64("result", result),
 		attribute.Int64("duration_us", duration.Microseconds()),
@@ -840,7 +841,7 @@ func (pqe *PathQueryEngine) PathQuery(ctx context.Context, u, v string, logger *
 			slog.Duration("duration", duration),
 			slog.Duration("threshold", pqe.opts.SlowQueryThreshold),
 			slog.Int("segments", len(segments)),
-			slog.String("lca", lca),
+			telemetry.SensitiveLog("lca", lca),
 		)
 	}
 
@@ -1060,7 +1061,7 @@ func (pqe *PathQueryEngine) querySegments(ctx context.Context, segments []PathSe
 				slog.Int("segment_index", i),
 				slog.Int("start", start),
 				slog.Int("end", end),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return result, fmt.Errorf("segment %d query [%d,%d] failed: %w", i, start, end, err)
 		}
@@ -1454,7 +1455,7 @@ func (pqe *PathQueryEngine) Validate() error {
 	_, span := otel.Tracer("trace").Start(context.Background(), "graph.PathQueryEngine.Validate")
 	defer func() {
 		if r := recover(); r != nil {
-			span.RecordError(fmt.Errorf("validation panic: %v", r))
+			telemetry.RecordException(span, fmt.Errorf("validation panic: %v", r))
 			span.SetStatus(codes.Error, "panic during validation")
 			span.End()
 			panic(r)
@@ -1465,7 +1466,7 @@ func (pqe *PathQueryEngine) Validate() error {
 	// Validate HLD
 	if pqe.hld != nil {
 		if err := pqe.hld.Validate(); err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "HLD validation failed")
 			span.SetAttributes(attribute.String("validation_error", "hld_invalid"))
 			return fmt.Errorf("HLD invalid: %w", err)
@@ -1475,7 +1476,7 @@ func (pqe *PathQueryEngine) Validate() error {
 		if pqe.hld.NodeCount() != pqe.segTree.size {
 			err := fmt.Errorf("size mismatch: HLD %d != SegTree %d",
 				pqe.hld.NodeCount(), pqe.segTree.size)
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "size mismatch")
 			span.SetAttributes(attribute.String("validation_error", "hld_size_mismatch"))
 			return err
@@ -1485,7 +1486,7 @@ func (pqe *PathQueryEngine) Validate() error {
 	// Validate Forest
 	if pqe.forest != nil {
 		if err := pqe.forest.Validate(); err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "forest validation failed")
 			span.SetAttributes(attribute.String("validation_error", "forest_invalid"))
 			return fmt.Errorf("forest invalid: %w", err)
@@ -1495,7 +1496,7 @@ func (pqe *PathQueryEngine) Validate() error {
 		if pqe.forest.TotalNodes() != pqe.segTree.size {
 			err := fmt.Errorf("size mismatch: Forest %d != SegTree %d",
 				pqe.forest.TotalNodes(), pqe.segTree.size)
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "size mismatch")
 			span.SetAttributes(attribute.String("validation_error", "forest_size_mismatch"))
 			return err
@@ -1504,7 +1505,7 @@ func (pqe *PathQueryEngine) Validate() error {
 
 	// Validate segment tree
 	if err := pqe.segTree.Validate(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "segment tree validation failed")
 		span.SetAttributes(attribute.String("validation_error", "segtree_invalid"))
 		return fmt.Errorf("segment tree invalid: %w", err)
@@ -1514,7 +1515,7 @@ func (pqe *PathQueryEngine) Validate() error {
 	if pqe.aggFunc != pqe.segTree.aggFunc {
 		err := fmt.Errorf("aggregation mismatch: engine %s != segment tree %s",
 			pqe.aggFunc, pqe.segTree.aggFunc)
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "aggregation mismatch")
 		span.SetAttributes(attribute.String("validation_error", "agg_func_mismatch"))
 		return err
@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.opentelemetry.io/otel"
@@ -272,8 +273,8 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 	// Create OpenTelemetry span (H-OBS-1)
 	ctx, span := otel.Tracer("trace").Start(ctx, "graph.PathUpdateEngine.PathUpdate",
 		trace.WithAttributes(
-			attribute.String("node_u", u),
-			attribute.String("node_v", v),
+			telemetry.SensitiveString("node_u", u),
+			telemetry.SensitiveString("node_v", v),
 			attribute.Int64("delta", delta),
 		),
 	)
@@ -315,7 +316,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 			treeHLD := pue.PathQueryEngine.forest.GetHLD(u)
 			if treeHLD == nil {
 				err := fmt.Errorf("HLD not found for node %s", u)
-				span.RecordError(err)
+				telemetry.RecordException(span, err)
 				span.SetStatus(codes.Error, "node lookup failed")
 				updateErr = err
 				return updateErr
@@ -325,7 +326,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 
 		if !ok {
 			err := fmt.Errorf("node %s not found", u)
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "node not found")
 			updateErr = err
 			return updateErr
@@ -342,7 +343,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 			treeOffset, offsetErr := pue.PathQueryEngine.forest.GetTreeOffset(u)
 			if offsetErr != nil {
 				err := fmt.Errorf("getting tree offset for node %s: %w", u, offsetErr)
-				span.RecordError(err)
+				telemetry.RecordException(span, err)
 				span.SetStatus(codes.Error, "tree offset failed")
 				updateErr = err
 				return updateErr
@@ -354,7 +355,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 		// Update single position
 		err := pue.segTree.RangeUpdate(ctx, pos, pos, delta)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "range update failed")
 			updateErr = fmt.Errorf("updating node %s at position %d: %w", u, pos, err)
 			return updateErr
@@ -389,7 +390,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 	// H-PRE-4: Forest mode validation
 	if pue.PathQueryEngine.forest != nil {
 		if err := pue.PathQueryEngine.checkTreeComponent(u, v); err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "cross-tree update")
 			updateErr = err
 			return updateErr
@@ -400,7 +401,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 	span.AddEvent("computing_lca")
 	lca, err := pue.computeLCAWithCache(ctx, u, v)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "LCA failed")
 		updateErr = fmt.Errorf("computing LCA for path %s->%s: %w", u, v, err)
 
@@ -409,7 +410,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 			slog.String("u", u),
 			slog.String("v", v),
 			slog.Int64("delta", delta),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.String("error_type", string(classifyUpdateError(err))),
 		)
 
@@ -429,13 +430,13 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 		treeHLD := pue.PathQueryEngine.forest.GetHLD(u)
 		if treeHLD == nil {
 			err := fmt.Errorf("HLD not found for node %s", u)
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "forest HLD lookup failed")
 			updateErr = err
 
 			logger.Error("path_update_hld_lookup_failed",
 				slog.String("u", u),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 
 			return updateErr
@@ -458,7 +459,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 	}
 
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "decomposition failed")
 		updateErr = fmt.Errorf("decomposing path %s->%s: %w", u, v, err)
 
@@ -466,7 +467,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 			slog.String("u", u),
 			slog.String("v", v),
 			slog.Int64("delta", delta),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.String("error_type", string(classifyUpdateError(err))),
 		)
 
@@ -481,7 +482,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 	// C-PRE-2: Add ctx parameter to updateSegments
 	totalPositions, err := pue.updateSegments(ctx, segments, delta, u, v, logger)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "segment update failed")
 		updateErr = fmt.Errorf("updating segments for path %s->%s: %w", u, v, err)
 
@@ -490,7 +491,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 			slog.String("v", v),
 			slog.Int64("delta", delta),
 			slog.Int("segments", len(segments)),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 			slog.String("error_type", string(classifyUpdateError(err))),
 		)
 
@@ -518,7 +519,7 @@ func (pue *PathUpdateEngine) PathUpdate(ctx context.Context, u, v string, delta
 	}
 
 	span.SetAttributes(
-		attribute.String("lca", lca),
+		telemetry.SensitiveString("lca", lca),
 		attribute.Int("segment_count", len(segments)),
 		attribute.Int("total_positions", totalPositions),
 		attribute.Int64("duration_us", duration.Microseconds()),
@@ -796,7 +797,7 @@ func (pue *PathUpdateEngine) updateSegments(ctx context.Context, segments []Path
 				slog.Int("start", start),
 				slog.Int("end", end),
 				slog.Int("tree_size", pue.segTree.size),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return totalPositions, err
 		}
@@ -819,7 +820,7 @@ func (pue *PathUpdateEngine) updateSegments(ctx context.Context, segments []Path
 				slog.Int("start", start),
 				slog.Int("end", end),
 				slog.Int64("delta", delta),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return totalPositions, fmt.Errorf("updating path %s->%s: segment %d update failed: %w", u, v, i, err)
 		}
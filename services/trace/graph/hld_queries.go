@@ -27,6 +27,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // ==============================================================================
@@ -374,8 +376,8 @@ func (hld *HLDecomposition) LCA(ctx context.Context, u, v string) (lca string, e
 	// Phase 2: Create OTel span (O-H1: set known attributes early)
 	ctx, span := getTracer().Start(ctx, "graph.HLDecomposition.LCA",
 		trace.WithAttributes(
-			attribute.String("node_u", u),
-			attribute.String("node_v", v),
+			telemetry.SensitiveString("node_u", u),
+			telemetry.SensitiveString("node_v", v),
 			attribute.Int("node_count", hld.nodeCount),
 		),
 	)
@@ -385,7 +387,7 @@ func (hld *HLDecomposition) LCA(ctx context.Context, u, v string) (lca string, e
 	uIdx, ok := hld.nodeToIdx[u]
 	if !ok {
 		err = fmt.Errorf("LCA: node %q does not exist: %w", u, ErrHLDNotInitialized)
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "node not found")
 		return "", err
 	}
@@ -393,7 +395,7 @@ func (hld *HLDecomposition) LCA(ctx context.Context, u, v string) (lca string, e
 	vIdx, ok := hld.nodeToIdx[v]
 	if !ok {
 		err = fmt.Errorf("LCA: node %q does not exist: %w", v, ErrHLDNotInitialized)
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "node not found")
 		return "", err
 	}
@@ -402,7 +404,7 @@ func (hld *HLDecomposition) LCA(ctx context.Context, u, v string) (lca string, e
 	select {
 	case <-ctx.Done():
 		err = fmt.Errorf("LCA: %w", ctx.Err())
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "context canceled")
 		return "", err
 	default:
@@ -420,7 +422,7 @@ func (hld *HLDecomposition) LCA(ctx context.Context, u, v string) (lca string, e
 	iterations = iters
 
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "LCA computation failed")
 		return "", fmt.Errorf("LCA: %w", err)
 	}
@@ -432,7 +434,7 @@ func (hld *HLDecomposition) LCA(ctx context.Context, u, v string) (lca string, e
 
 	// Phase 7: Set span attributes (O-H1: set result attributes)
 	span.SetAttributes(
-		attribute.String("lca", lca),
+		telemetry.SensitiveString("lca", lca),
 		attribute.Int("iterations", iterations),
 		attribute.Int("distance", distance),
 		attribute.Int("depth_u", hld.depth[uIdx]),
@@ -451,7 +453,7 @@ func (hld *HLDecomposition) LCA(ctx context.Context, u, v string) (lca string, e
 		slog.DebugContext(ctx, "LCA computed",
 			slog.String("u", u),
 			slog.String("v", v),
-			slog.String("lca", lca),
+			telemetry.SensitiveLog("lca", lca),
 			slog.Int("iterations", iterations),
 			slog.Int("distance", distance),
 		)
@@ -576,11 +578,11 @@ func (hld *HLDecomposition) lcaIndex(ctx context.Context, uIdx, vIdx int) (int,
 		// P-M2: Only check invariants in debug mode for performance
 		if debugEnabled {
 			if err := hld.validateHeadIsAncestor(uIdx); err != nil {
-				slog.Error("HLD invariant violated", slog.String("error", err.Error()))
+				slog.Error("HLD invariant violated", telemetry.SensitiveLogError("error", err))
 				return 0, iterations, fmt.Errorf("HLD structure corrupted: %w", err)
 			}
 			if err := hld.validateHeadIsAncestor(vIdx); err != nil {
-				slog.Error("HLD invariant violated", slog.String("error", err.Error()))
+				slog.Error("HLD invariant violated", telemetry.SensitiveLogError("error", err))
 				return 0, iterations, fmt.Errorf("HLD structure corrupted: %w", err)
 			}
 		}
@@ -724,8 +726,8 @@ func (hld *HLDecomposition) Distance(ctx context.Context, u, v string) (int, err
 	// Phase 2: Create span (O-H1: set attributes early)
 	ctx, span := getTracer().Start(ctx, "graph.HLDecomposition.Distance",
 		trace.WithAttributes(
-			attribute.String("node_u", u),
-			attribute.String("node_v", v),
+			telemetry.SensitiveString("node_u", u),
+			telemetry.SensitiveString("node_v", v),
 		),
 	)
 	defer span.End()
@@ -734,7 +736,7 @@ func (hld *HLDecomposition) Distance(ctx context.Context, u, v string) (int, err
 	uIdx, ok := hld.nodeToIdx[u]
 	if !ok {
 		err := fmt.Errorf("Distance: node %q does not exist: %w", u, ErrHLDNotInitialized)
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "node not found")
 		return 0, err
 	}
@@ -743,7 +745,7 @@ func (hld *HLDecomposition) Distance(ctx context.Context, u, v string) (int, err
 	if !ok {
 		// R-H1: Fix error message - use 'v' not 'u'
 		err := fmt.Errorf("Distance: node %q does not exist: %w", v, ErrHLDNotInitialized)
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "node not found")
 		return 0, err
 	}
@@ -751,7 +753,7 @@ func (hld *HLDecomposition) Distance(ctx context.Context, u, v string) (int, err
 	// Phase 4: Compute LCA
 	lcaIdx, _, err := hld.lcaIndex(ctx, uIdx, vIdx)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "LCA failed")
 		return 0, fmt.Errorf("Distance: LCA failed: %w", err)
 	}
@@ -763,7 +765,7 @@ func (hld *HLDecomposition) Distance(ctx context.Context, u, v string) (int, err
 	lcaDepth := hld.depth[lcaIdx]
 	if hld.depth[uIdx] < lcaDepth || hld.depth[vIdx] < lcaDepth {
 		err := errors.New("depth array corrupted: LCA deeper than nodes")
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "depth corrupted")
 		slog.ErrorContext(ctx, "depth corruption detected",
 			slog.String("u", u),
@@ -778,7 +780,7 @@ func (hld *HLDecomposition) Distance(ctx context.Context, u, v string) (int, err
 	// Phase 7: Distance Sign Validation
 	if distance < 0 {
 		err := fmt.Errorf("negative distance computed: %d", distance)
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "negative distance")
 		slog.ErrorContext(ctx, "negative distance",
 			slog.String("u", u),
@@ -790,7 +792,7 @@ func (hld *HLDecomposition) Distance(ctx context.Context, u, v string) (int, err
 
 	// Phase 8: Set span attributes
 	span.SetAttributes(
-		attribute.String("lca", hld.idxToNode[lcaIdx]),
+		telemetry.SensitiveString("lca", hld.idxToNode[lcaIdx]),
 		attribute.Int("distance", distance),
 	)
 	span.SetStatus(codes.Ok, "distance computed")
@@ -801,7 +803,7 @@ func (hld *HLDecomposition) Distance(ctx context.Context, u, v string) (int, err
 			slog.String("u", u),
 			slog.String("v", v),
 			slog.Int("distance", distance),
-			slog.String("lca", hld.idxToNode[lcaIdx]),
+			telemetry.SensitiveLog("lca", hld.idxToNode[lcaIdx]),
 		)
 	}
 
@@ -911,8 +913,8 @@ func (hld *HLDecomposition) DecomposePath(ctx context.Context, u, v string) (seg
 	// Phase 2: Create span (O-H1: set attributes early)
 	ctx, span := getTracer().Start(ctx, "graph.HLDecomposition.DecomposePath",
 		trace.WithAttributes(
-			attribute.String("node_u", u),
-			attribute.String("node_v", v),
+			telemetry.SensitiveString("node_u", u),
+			telemetry.SensitiveString("node_v", v),
 		),
 	)
 	defer span.End()
@@ -921,7 +923,7 @@ func (hld *HLDecomposition) DecomposePath(ctx context.Context, u, v string) (seg
 	uIdx, ok := hld.nodeToIdx[u]
 	if !ok {
 		err = fmt.Errorf("DecomposePath: node %q does not exist: %w", u, ErrHLDNotInitialized)
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "node not found")
 		return nil, err
 	}
@@ -929,7 +931,7 @@ func (hld *HLDecomposition) DecomposePath(ctx context.Context, u, v string) (seg
 	vIdx, ok := hld.nodeToIdx[v]
 	if !ok {
 		err = fmt.Errorf("DecomposePath: node %q does not exist: %w", v, ErrHLDNotInitialized)
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "node not found")
 		return nil, err
 	}
@@ -938,7 +940,7 @@ func (hld *HLDecomposition) DecomposePath(ctx context.Context, u, v string) (seg
 	span.AddEvent("computing_lca")
 	lcaIdx, _, err := hld.lcaIndex(ctx, uIdx, vIdx)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "LCA failed")
 		return nil, fmt.Errorf("DecomposePath: LCA failed: %w", err)
 	}
@@ -957,7 +959,7 @@ func (hld *HLDecomposition) DecomposePath(ctx context.Context, u, v string) (seg
 
 	// Decompose u → lca
 	if err = hld.decomposeUpward(ctx, uIdx, lcaIdx, &segments); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "decomposition failed")
 		return nil, fmt.Errorf("DecomposePath: decompose u→lca failed: %w", err)
 	}
@@ -965,7 +967,7 @@ func (hld *HLDecomposition) DecomposePath(ctx context.Context, u, v string) (seg
 	// Decompose v → lca (excluding LCA to avoid double-counting)
 	if vIdx != lcaIdx {
 		if err = hld.decomposeUpward(ctx, vIdx, lcaIdx, &segments); err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "decomposition failed")
 			return nil, fmt.Errorf("DecomposePath: decompose v→lca failed: %w", err)
 		}
@@ -984,7 +986,7 @@ func (hld *HLDecomposition) DecomposePath(ctx context.Context, u, v string) (seg
 
 	// Phase 8: Set span attributes
 	span.SetAttributes(
-		attribute.String("lca", hld.idxToNode[lcaIdx]),
+		telemetry.SensitiveString("lca", hld.idxToNode[lcaIdx]),
 		attribute.Int("segment_count", len(segments)),
 		attribute.Int("total_positions", totalPositions),
 		attribute.Int64("duration_us", time.Since(start).Microseconds()),
@@ -996,7 +998,7 @@ func (hld *HLDecomposition) DecomposePath(ctx context.Context, u, v string) (seg
 		slog.DebugContext(ctx, "Path decomposed",
 			slog.String("u", u),
 			slog.String("v", v),
-			slog.String("lca", hld.idxToNode[lcaIdx]),
+			telemetry.SensitiveLog("lca", hld.idxToNode[lcaIdx]),
 			slog.Int("segment_count", len(segments)),
 			slog.Int("total_positions", totalPositions),
 		)
@@ -1071,7 +1073,7 @@ func (hld *HLDecomposition) decomposeUpward(ctx context.Context, uIdx, ancestorI
 		if slog.Default().Enabled(ctx, slog.LevelDebug) {
 			if err := hld.validateHeadIsAncestor(currentIdx); err != nil {
 				slog.Error("HLD invariant violated in decomposeUpward",
-					slog.String("error", err.Error()))
+					telemetry.SensitiveLogError("error", err))
 				return fmt.Errorf("HLD structure corrupted: %w", err)
 			}
 		}
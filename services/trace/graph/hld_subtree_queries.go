@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/mcts/crs"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
@@ -281,7 +282,7 @@ func (sqe *SubtreeQueryEngine) SubtreeQuery(ctx context.Context, v string) (int6
 	// Start OTel span (L-POST-1: add mode attribute)
 	ctx, span := otel.Tracer("trace").Start(ctx, "graph.SubtreeQueryEngine.SubtreeQuery",
 		trace.WithAttributes(
-			attribute.String("node", v),
+			telemetry.SensitiveString("node", v),
 			attribute.String("agg_func", sqe.aggFunc.String()),
 			attribute.String("mode", sqe.mode()),
 		),
@@ -293,7 +294,7 @@ func (sqe *SubtreeQueryEngine) SubtreeQuery(ctx context.Context, v string) (int6
 	// Get subtree range (with caching - H-PRE-4)
 	rangeStart, rangeEnd, err := sqe.getSubtreeRange(ctx, v, span)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "failed to get subtree range")
 		return 0, fmt.Errorf("subtree query for node %q: %w", v, err)
 	}
@@ -306,7 +307,7 @@ func (sqe *SubtreeQueryEngine) SubtreeQuery(ctx context.Context, v string) (int6
 		span.AddEvent("fast_path_single_node")
 		result, err = sqe.segTree.GetValue(ctx, rangeStart)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "get value failed")
 			return 0, fmt.Errorf("subtree query for node %q: get value: %w", v, err)
 		}
@@ -322,7 +323,7 @@ func (sqe *SubtreeQueryEngine) SubtreeQuery(ctx context.Context, v string) (int6
 
 		result, err = sqe.segTree.Query(ctx, rangeStart, rangeEnd)
 		if err != nil {
-			span.RecordError(err)
+			telemetry.RecordException(span, err)
 			span.SetStatus(codes.Error, "segment tree query failed")
 			return 0, fmt.Errorf("subtree query for node %q: segment tree query [%d,%d]: %w",
 				v, rangeStart, rangeEnd, err)
@@ -345,7 +346,7 @@ func (sqe *SubtreeQueryEngine) SubtreeQuery(ctx context.Context, v string) (int6
 	// Log for large subtrees (M-PRE-5)
 	if subtreeSize > 10000 {
 		sqe.logger.Warn("large subtree query",
-			slog.String("node", v),
+			telemetry.SensitiveLog("node", v),
 			slog.Int("size", subtreeSize),
 			slog.Duration("duration", duration),
 		)
@@ -370,7 +371,7 @@ func (sqe *SubtreeQueryEngine) SubtreeQuery(ctx context.Context, v string) (int6
 
 		if err := sqe.crs.RecordStep(ctx, step); err != nil {
 			sqe.logger.Warn("failed to record CRS step",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "SubtreeQuery"),
 			)
 		}
@@ -396,7 +397,7 @@ func (sqe *SubtreeQueryEngine) getSubtreeRange(ctx context.Context, v string, sp
 		r := cached.([2]int)
 		span.AddEvent("range_cache_hit")
 		sqe.logger.Debug("subtree range cache hit",
-			slog.String("node", v),
+			telemetry.SensitiveLog("node", v),
 			slog.Int("start", r[0]),
 			slog.Int("end", r[1]),
 		)
@@ -449,7 +450,7 @@ func (sqe *SubtreeQueryEngine) getSubtreeRange(ctx context.Context, v string, sp
 	// Validate bounds (C-PRE-2)
 	if start < 0 || end >= sqe.segTree.size {
 		sqe.logger.Error("subtree range out of bounds",
-			slog.String("node", v),
+			telemetry.SensitiveLog("node", v),
 			slog.Int("start", start),
 			slog.Int("end", end),
 			slog.Int("tree_size", sqe.segTree.size),
@@ -471,7 +472,7 @@ func (sqe *SubtreeQueryEngine) getSubtreeRange(ctx context.Context, v string, sp
 
 	// Debug logging (M-POST-1)
 	sqe.logger.Debug("computed subtree range",
-		slog.String("node", v),
+		telemetry.SensitiveLog("node", v),
 		slog.Int("start", start),
 		slog.Int("end", end),
 		slog.Int("size", end-start+1),
@@ -1002,7 +1003,7 @@ func (sue *SubtreeUpdateEngine) SubtreeUpdate(ctx context.Context, v string, del
 	// Start OTel span
 	ctx, span := otel.Tracer("trace").Start(ctx, "graph.SubtreeUpdateEngine.SubtreeUpdate",
 		trace.WithAttributes(
-			attribute.String("node", v),
+			telemetry.SensitiveString("node", v),
 			attribute.Int64("delta", delta),
 		),
 	)
@@ -1013,7 +1014,7 @@ func (sue *SubtreeUpdateEngine) SubtreeUpdate(ctx context.Context, v string, del
 	// Get subtree range
 	rangeStart, rangeEnd, err := sue.getSubtreeRange(ctx, v, span)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "failed to get subtree range")
 		return fmt.Errorf("subtree update for node %q: %w", v, err)
 	}
@@ -1032,7 +1033,7 @@ func (sue *SubtreeUpdateEngine) SubtreeUpdate(ctx context.Context, v string, del
 	// Perform range update
 	err = sue.segTree.RangeUpdate(ctx, rangeStart, rangeEnd, delta)
 	if err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "range update failed")
 		return fmt.Errorf("subtree update for node %q: range update [%d,%d]: %w",
 			v, rangeStart, rangeEnd, err)
@@ -1069,7 +1070,7 @@ func (sue *SubtreeUpdateEngine) SubtreeUpdate(ctx context.Context, v string, del
 
 		if err := sue.crs.RecordStep(ctx, step); err != nil {
 			sue.logger.Warn("failed to record CRS step",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "SubtreeUpdate"),
 			)
 		}
@@ -1078,7 +1079,7 @@ func (sue *SubtreeUpdateEngine) SubtreeUpdate(ctx context.Context, v string, del
 	// Large update warning (M-POST-3)
 	if subtreeSize > 10000 {
 		sue.logger.Warn("large subtree update",
-			slog.String("node", v),
+			telemetry.SensitiveLog("node", v),
 			slog.Int("size", subtreeSize),
 			slog.Int64("delta", delta),
 		)
@@ -1181,7 +1182,7 @@ func (sue *SubtreeUpdateEngine) SubtreeSet(ctx context.Context, v string, value
 
 		if err := sue.crs.RecordStep(ctx, step); err != nil {
 			sue.logger.Warn("failed to record CRS step",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.String("tool", "SubtreeSet"),
 			)
 		}
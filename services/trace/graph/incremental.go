@@ -22,6 +22,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // incrementalTracer is the OTel tracer for incremental graph operations.
@@ -167,6 +168,9 @@ func IncrementalRefresh(
 		ChangedFiles: changedFiles,
 	}
 
+	// The nodes linked to the changed files are validated with them.
+	neighbours := linkedNodeIDs(baseGraph, changedFiles)
+
 	// Phase 1: Remove old data for changed files.
 	// RemoveFile atomically removes all nodes and edges for a file,
 	// rebuilding all secondary indexes (nodesByName, nodesByKind,
@@ -187,7 +191,8 @@ func IncrementalRefresh(
 	// Phase 2: Reconstruct build state from existing nodes.
 	// Edge extraction resolves calls by name, so we need the complete
 	// symbolsByName map (unchanged + newly added symbols).
-	builder := NewBuilder(WithProjectRoot(baseGraph.ProjectRoot))
+	builder := NewBuilder(WithProjectRoot(baseGraph.ProjectRoot), WithPythonSourceRoots(baseGraph.PythonSourceRoots...),
+		WithGoModules(baseGraph.GoModules...), WithPlaceholderPolicy(baseGraph.PlaceholderPolicy))
 	state := &buildState{
 		graph: working,
 		result: &BuildResult{
@@ -202,6 +207,8 @@ func IncrementalRefresh(
 		classExtends:           make(map[string]string),
 		classAdditionalParents: make(map[string][]string),
 		importNameMap:          make(map[string]map[string]importEntry),
+		pythonReExports:        make(map[string]map[string]importEntry),
+		pythonModuleAliases:    make(map[string]map[string]string),
 		symbolsByLocation:      make(map[string][]string),
 		startTime:              time.Now(),
 	}
@@ -210,6 +217,12 @@ func IncrementalRefresh(
 	// Populate symbolsByID, symbolsByName, symbolsByLocation, and placeholders
 	// from remaining (unchanged) nodes.
 	for _, node := range working.Nodes() {
+		// The clone shares symbols with the base graph. Placeholder symbols
+		// gain collapsed members and stdlib docs during the refresh, so
+		// each gets its own copy.
+		if isPlaceholderNode(node) {
+			node.Symbol = clonePlaceholderSymbol(node.Symbol)
+		}
 		if node.Symbol != nil {
 			state.symbolsByID[node.Symbol.ID] = node.Symbol
 			state.symbolsByName[node.Symbol.Name] = append(
@@ -255,6 +268,9 @@ func IncrementalRefresh(
 		}
 		builder.extractFileEdges(ctx, state, r)
 	}
+	// Field accesses of the changed functions resolve against the fields
+	// of every struct in the graph.
+	builder.resolveFieldAccessEdges(ctx, state, changedResults)
 	result.EdgesCreated = state.result.Stats.EdgesCreated
 
 	// Phase 6: LSP re-enrichment for changed files only (GR-76).
@@ -278,14 +294,20 @@ func IncrementalRefresh(
 		result.EnrichmentStats = enrichStats
 		if enrichErr != nil {
 			slog.Warn("GR-76: LSP enrichment during incremental refresh failed",
-				slog.String("error", enrichErr.Error()),
+				telemetry.SensitiveLogError("error", enrichErr),
 			)
 			// Non-fatal: proceed with heuristic-only edges
 		}
 	}
 
+	enrichStdlibPlaceholders(state, nil)
+
 	// Freeze the updated graph.
 	working.Freeze()
+	working.FileHashes = refreshedFileHashes(baseGraph.FileHashes, changedFiles, changedResults)
+	working.QualityReport = NewQualityReport(working, refreshedParseErrorFiles(baseGraph, changedFiles, state.result, changedResults))
+	report, inRegion := working.validateTouched(changedFiles, neighbours)
+	working.SetValidation(report.carry(baseGraph.Validation(), inRegion))
 
 	result.Graph = working
 	result.DurationMilli = time.Since(start).Milliseconds()
@@ -309,3 +331,23 @@ func IncrementalRefresh(
 
 	return result, nil
 }
+
+// refreshedParseErrorFiles returns the files with parse errors after an
+// incremental refresh: those of the base graph that did not change, plus
+// the changed files that failed to parse again.
+func refreshedParseErrorFiles(baseGraph *Graph, changedFiles []string, result *BuildResult, changedResults []*ast.ParseResult) []string {
+	files := buildParseErrorFiles(result, changedResults)
+	if baseGraph.QualityReport == nil {
+		return files
+	}
+	changed := make(map[string]struct{}, len(changedFiles))
+	for _, f := range changedFiles {
+		changed[f] = struct{}{}
+	}
+	for _, f := range baseGraph.QualityReport.ParseErrors.Files {
+		if _, ok := changed[f]; !ok {
+			files = append(files, f)
+		}
+	}
+	return files
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"unsafe"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// stringTable deduplicates strings while a graph is being built.
+//
+// Description:
+//
+//	Each distinct string is stored once, on first sight; later intern
+//	calls with equal content return the stored copy, so every field that
+//	holds that value shares one backing array. The public Node, Edge and
+//	ast.Symbol fields stay plain strings — the table only decides which
+//	allocation they point at.
+//
+//	Parsers allocate a fresh string for every call target, receiver and
+//	referenced type name they read, and graphs loaded from snapshots, bbolt
+//	or JSON also repeat FilePath, Language, Package and both edge endpoint
+//	IDs per record; those are the copies the table folds together.
+//
+// Thread Safety:
+//
+//	NOT safe for concurrent use. Owned by a Graph in the building state
+//	and released by Freeze, so frozen graphs never touch it.
+type stringTable struct {
+	strs map[string]string
+}
+
+// newStringTable creates an empty string table.
+func newStringTable() *stringTable {
+	return &stringTable{strs: make(map[string]string)}
+}
+
+// intern returns the canonical copy of s, adding s to the table if needed.
+func (t *stringTable) intern(s string) string {
+	if s == "" {
+		return s
+	}
+	if canonical, ok := t.strs[s]; ok {
+		return canonical
+	}
+	t.strs[s] = s
+	return s
+}
+
+// len returns the number of distinct strings in the table.
+func (t *stringTable) len() int {
+	return len(t.strs)
+}
+
+// estimatedBytes approximates the table's own overhead (map entries),
+// excluding the string data that graph fields also reference.
+func (t *stringTable) estimatedBytes() int64 {
+	const entry = 2*int64(unsafe.Sizeof("")) + mapEntryOverhead
+	return int64(len(t.strs)) * entry
+}
+
+// intern returns the graph's canonical copy of s, or s unchanged when
+// interning is disabled.
+func (g *Graph) intern(s string) string {
+	if g.options.DisableStringInterning || s == "" {
+		return s
+	}
+	if g.strings == nil {
+		g.strings = newStringTable()
+	}
+	return g.strings.intern(s)
+}
+
+// internField replaces *field with its canonical copy. The field is only
+// written when the canonical copy is a different allocation, so symbols
+// that are already interned (e.g. shared with another graph) are left
+// untouched.
+func (g *Graph) internField(field *string) {
+	canonical := g.intern(*field)
+	if unsafe.StringData(canonical) != unsafe.StringData(*field) {
+		*field = canonical
+	}
+}
+
+// internSymbol canonicalizes the repetitive string fields of sym: name,
+// file paths, language, package, receivers, call targets and chains,
+// referenced type names and the common metadata type strings. IDs,
+// signatures and doc comments are left alone; they are mostly unique and
+// interning them would only grow the table.
+func (g *Graph) internSymbol(sym *ast.Symbol) {
+	if g.options.DisableStringInterning {
+		return
+	}
+	g.internField(&sym.Name)
+	g.internField(&sym.FilePath)
+	g.internField(&sym.Language)
+	g.internField(&sym.Package)
+	g.internField(&sym.Receiver)
+	for i := range sym.Calls {
+		g.internField(&sym.Calls[i].Target)
+		g.internField(&sym.Calls[i].Receiver)
+		g.internField(&sym.Calls[i].Location.FilePath)
+		for j := range sym.Calls[i].Chain {
+			g.internField(&sym.Calls[i].Chain[j])
+		}
+	}
+	for i := range sym.TypeReferences {
+		g.internField(&sym.TypeReferences[i].Name)
+		g.internField(&sym.TypeReferences[i].Location.FilePath)
+	}
+	if md := sym.Metadata; md != nil {
+		g.internField(&md.ReturnType)
+		g.internField(&md.AccessModifier)
+		g.internField(&md.Extends)
+		g.internField(&md.ParentName)
+	}
+}
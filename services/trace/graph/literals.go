@@ -0,0 +1,181 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// DefaultMaxLiteralUsages is the default number of call-site literal
+// arguments a graph indexes.
+const DefaultMaxLiteralUsages = 200_000
+
+// LiteralUsage is a literal argument passed at a call site.
+//
+// Thread Safety: LiteralUsage is immutable after creation and safe for concurrent read.
+type LiteralUsage struct {
+	// SymbolID is the ID of the symbol making the call.
+	SymbolID string
+
+	// Target is the called function or method (see ast.CallSite.Target).
+	Target string
+
+	// Receiver is the receiver expression of a method call.
+	Receiver string
+
+	// CallLocation is where the call expression occurs.
+	CallLocation ast.Location
+
+	// Arg is the literal, with its own location.
+	Arg ast.LiteralArg
+}
+
+// LiteralQuery selects literal usages. See Graph.FindLiteralUsages.
+type LiteralQuery struct {
+	// Value is the literal to look for, as written for numbers and bools
+	// and unquoted for strings. Required.
+	Value string
+
+	// Substring matches string literals containing Value, case-insensitively.
+	// Number and bool literals always match exactly.
+	Substring bool
+
+	// Kind restricts matches to one literal kind. Empty matches all kinds.
+	Kind ast.LiteralKind
+
+	// Limit is the maximum number of usages returned. Zero or negative
+	// returns all.
+	Limit int
+}
+
+// buildLiteralIndex collects the literal arguments of every node's call
+// sites in (file, line, column, argument) order, keeping at most
+// GraphOptions.MaxLiteralUsages. Redacted literals are skipped: their
+// value is a placeholder. Called by Freeze().
+//
+// Thread Safety:
+//
+//	NOT safe for concurrent use. Called by Freeze() only.
+func (g *Graph) buildLiteralIndex() {
+	var usages []LiteralUsage
+	for _, node := range g.nodes {
+		if node.Symbol == nil {
+			continue
+		}
+		for _, call := range node.Symbol.Calls {
+			for _, arg := range call.LiteralArgs {
+				if arg.Redacted {
+					continue
+				}
+				usages = append(usages, LiteralUsage{
+					SymbolID:     node.ID,
+					Target:       call.Target,
+					Receiver:     call.Receiver,
+					CallLocation: call.Location,
+					Arg:          arg,
+				})
+			}
+		}
+	}
+
+	sort.Slice(usages, func(i, j int) bool {
+		a, b := usages[i].Arg.Location, usages[j].Arg.Location
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		if a.StartCol != b.StartCol {
+			return a.StartCol < b.StartCol
+		}
+		return usages[i].SymbolID < usages[j].SymbolID
+	})
+
+	// A call inside a nested symbol can be recorded by both the nested
+	// symbol and its parent; keep the first.
+	kept := usages[:0]
+	for i, u := range usages {
+		if i > 0 && u.Arg.Location == usages[i-1].Arg.Location && u.Arg.Value == usages[i-1].Arg.Value {
+			continue
+		}
+		kept = append(kept, u)
+	}
+	usages = kept
+
+	g.literalUsagesDropped = 0
+	if limit := g.options.MaxLiteralUsages; len(usages) > limit {
+		if limit < 0 {
+			limit = 0
+		}
+		g.literalUsagesDropped = len(usages) - limit
+		usages = usages[:limit]
+	}
+	g.literalUsages = usages
+}
+
+// LiteralUsageCounts returns how many literal arguments the graph indexed
+// and how many were left out by GraphOptions.MaxLiteralUsages. Both are
+// zero before Freeze().
+//
+// Thread Safety: Safe for concurrent use on frozen graphs.
+func (g *Graph) LiteralUsageCounts() (indexed, dropped int) {
+	return len(g.literalUsages), g.literalUsagesDropped
+}
+
+// FindLiteralUsages returns the call sites passing a literal.
+//
+// Description:
+//
+//	Matches the literal arguments captured by the parsers (see
+//	ast.ParseOptions.CaptureLiteralArgs) against q.Value: exactly, or for
+//	strings with q.Substring, by case-insensitive containment. Results are
+//	in file, line and column order.
+//
+// Inputs:
+//
+//	q - The query. An empty Value matches nothing.
+//
+// Outputs:
+//
+//	[]LiteralUsage - Matching usages, at most q.Limit if positive. Nil
+//	before Freeze().
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) FindLiteralUsages(q LiteralQuery) []LiteralUsage {
+	if q.Value == "" {
+		return nil
+	}
+	needle := strings.ToLower(q.Value)
+	var result []LiteralUsage
+	for _, u := range g.literalUsages {
+		if q.Kind != "" && u.Arg.Kind != q.Kind {
+			continue
+		}
+		match := u.Arg.Value == q.Value
+		if !match && q.Substring && u.Arg.Kind == ast.LiteralKindString {
+			match = strings.Contains(strings.ToLower(u.Arg.Value), needle)
+		}
+		if !match {
+			continue
+		}
+		result = append(result, u)
+		if q.Limit > 0 && len(result) >= q.Limit {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// literalArg returns a literal at line of file.
+func literalArg(file string, line int, value string, kind ast.LiteralKind) ast.LiteralArg {
+	return ast.LiteralArg{
+		Value:    value,
+		Kind:     kind,
+		Location: ast.Location{FilePath: file, StartLine: line, EndLine: line},
+	}
+}
+
+// literalGraphSymbols returns two functions whose calls pass literals.
+func literalGraphSymbols() []*ast.Symbol {
+	return []*ast.Symbol{
+		{
+			ID: "b.go:1:Setup", Name: "Setup", Kind: ast.SymbolKindFunction,
+			FilePath: "b.go", StartLine: 1, EndLine: 9, Language: "go",
+			Calls: []ast.CallSite{{
+				Target:   "WithTimeout",
+				Location: ast.Location{FilePath: "b.go", StartLine: 3},
+				LiteralArgs: []ast.LiteralArg{
+					literalArg("b.go", 3, "30", ast.LiteralKindNumber),
+					literalArg("b.go", 3, "production", ast.LiteralKindString),
+					{Value: ast.RedactedLiteral, Kind: ast.LiteralKindString, Redacted: true,
+						Location: ast.Location{FilePath: "b.go", StartLine: 3, StartCol: 40}},
+				},
+			}},
+		},
+		{
+			ID: "a.go:1:Main", Name: "Main", Kind: ast.SymbolKindFunction,
+			FilePath: "a.go", StartLine: 1, EndLine: 9, Language: "go",
+			Calls: []ast.CallSite{{
+				Target:   "Connect",
+				Receiver: "db",
+				IsMethod: true,
+				Location: ast.Location{FilePath: "a.go", StartLine: 5},
+				LiteralArgs: []ast.LiteralArg{
+					literalArg("a.go", 5, "Production-EU", ast.LiteralKindString),
+					literalArg("a.go", 5, "30", ast.LiteralKindNumber),
+				},
+			}},
+		},
+	}
+}
+
+func TestGraph_FindLiteralUsages(t *testing.T) {
+	g := NewGraph("/project")
+	for _, sym := range literalGraphSymbols() {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	if got := g.FindLiteralUsages(LiteralQuery{Value: "30"}); got != nil {
+		t.Errorf("before Freeze: %v, want nil", got)
+	}
+	g.Freeze()
+
+	if indexed, dropped := g.LiteralUsageCounts(); indexed != 4 || dropped != 0 {
+		t.Errorf("counts = %d, %d, want 4 indexed (redacted skipped), 0 dropped", indexed, dropped)
+	}
+
+	got := g.FindLiteralUsages(LiteralQuery{Value: "30"})
+	if len(got) != 2 || got[0].SymbolID != "a.go:1:Main" || got[1].SymbolID != "b.go:1:Setup" {
+		t.Fatalf("exact 30 = %+v, want Main then Setup", got)
+	}
+	if got[0].Target != "Connect" || got[0].Receiver != "db" {
+		t.Errorf("usage = %+v, want db.Connect", got[0])
+	}
+
+	if got := g.FindLiteralUsages(LiteralQuery{Value: "production"}); len(got) != 1 {
+		t.Errorf("exact production = %d usages, want 1", len(got))
+	}
+	if got := g.FindLiteralUsages(LiteralQuery{Value: "production", Substring: true}); len(got) != 2 {
+		t.Errorf("substring production = %d usages, want 2", len(got))
+	}
+	if got := g.FindLiteralUsages(LiteralQuery{Value: "3", Substring: true}); len(got) != 0 {
+		t.Errorf("substring on numbers = %d usages, want 0", len(got))
+	}
+	if got := g.FindLiteralUsages(LiteralQuery{Value: "30", Kind: ast.LiteralKindString}); len(got) != 0 {
+		t.Errorf("kind string = %d usages, want 0", len(got))
+	}
+	if got := g.FindLiteralUsages(LiteralQuery{Value: "30", Limit: 1}); len(got) != 1 {
+		t.Errorf("limit 1 = %d usages, want 1", len(got))
+	}
+	if got := g.FindLiteralUsages(LiteralQuery{Value: ast.RedactedLiteral}); len(got) != 0 {
+		t.Errorf("redacted placeholder matched %d usages, want 0", len(got))
+	}
+}
+
+func TestBuilder_LiteralUsageCap(t *testing.T) {
+	result := &ast.ParseResult{FilePath: "a.go", Language: "go", Symbols: literalGraphSymbols()[1:]}
+	result.Symbols[0].Calls[0].LiteralArgs = append(result.Symbols[0].Calls[0].LiteralArgs,
+		literalArg("a.go", 6, "true", ast.LiteralKindBool))
+
+	built, err := NewBuilder(WithBuilderMaxLiteralUsages(2)).Build(context.Background(), []*ast.ParseResult{result})
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if built.Stats.LiteralUsagesIndexed != 2 || built.Stats.LiteralUsagesDropped != 1 {
+		t.Errorf("stats = %d indexed, %d dropped, want 2 and 1",
+			built.Stats.LiteralUsagesIndexed, built.Stats.LiteralUsagesDropped)
+	}
+	// The cap keeps the literals first in file order.
+	if got := built.Graph.FindLiteralUsages(LiteralQuery{Value: "true"}); len(got) != 0 {
+		t.Errorf("dropped literal found: %+v", got)
+	}
+	if stats := built.Graph.Stats(); stats.LiteralUsages != 2 || stats.LiteralUsagesDropped != 1 {
+		t.Errorf("graph stats = %d, %d, want 2 and 1", stats.LiteralUsages, stats.LiteralUsagesDropped)
+	}
+}
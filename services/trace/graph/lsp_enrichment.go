@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/lsp"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -325,11 +326,12 @@ func (b *Builder) lspEnrichmentPhaseInternal(
 				slog.String("edge_from", r.query.edge.FromID),
 				slog.String("old_to", r.query.placeholder.ID),
 				slog.String("new_to", r.resolvedNodeID),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			stats.PlaceholdersFailed++
 			continue
 		}
+		r.query.edge.Provenance = retargetProvenance(PassLSP, ConfidenceHigh, r.query.edge.Provenance)
 		stats.PlaceholdersResolved++
 	}
 
@@ -452,7 +454,7 @@ func processFileEnrichment(
 ) []enrichmentResult {
 	_, span := tracer.Start(ctx, "graph.Builder.lspEnrichFile",
 		trace.WithAttributes(
-			attribute.String("file", filePath),
+			telemetry.SensitiveString("file", filePath),
 			attribute.Int("queries", len(queries)),
 		),
 	)
@@ -615,8 +617,8 @@ func removeOrphanedPlaceholders(state *buildState) int {
 		if len(placeholder.Incoming) == 0 && len(placeholder.Outgoing) == 0 {
 			if err := state.graph.RemoveNode(id); err != nil {
 				slog.Debug("GR-74: failed to remove orphaned placeholder",
-					slog.String("id", id),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLog("id", id),
+					telemetry.SensitiveLogError("error", err),
 				)
 				continue
 			}
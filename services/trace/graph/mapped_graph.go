@@ -0,0 +1,894 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+	"unsafe"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Mapped graph file layout (version 1). All integers are little-endian.
+//
+//	header   mappedHeaderSize bytes, see the mappedHdr* offsets
+//	nodes    nodeCount records of mappedNodeSize bytes, sorted by ID
+//	edges    edgeCount records of mappedEdgeSize bytes, grouped by source
+//	         node in each node's Outgoing order
+//	in       edgeCount uint32 edge indexes, grouped by target node in each
+//	         node's Incoming order
+//	hash     hashSlots slots of mappedSlotSize bytes: node index + 1 (0
+//	         when empty) and the high 32 bits of mappedHash of the ID,
+//	         open addressing on the low bits
+//	strings  the deduplicated string bytes
+//
+// Strings are referenced as (offset, length) uint32 pairs relative to the
+// string table.
+const (
+	// MappedGraphVersion is the format version written by WriteMapped.
+	MappedGraphVersion = 1
+
+	mappedHeaderSize = 96
+	mappedNodeSize   = 104
+	mappedEdgeSize   = 36
+	mappedSlotSize   = 8
+
+	mappedHdrVersion     = 8
+	mappedHdrNodeCount   = 12
+	mappedHdrEdgeCount   = 16
+	mappedHdrHashSlots   = 20
+	mappedHdrBuiltAt     = 24
+	mappedHdrProjectRoot = 32
+	mappedHdrGraphHash   = 40
+	mappedHdrNodesOff    = 48
+	mappedHdrEdgesOff    = 56
+	mappedHdrInOff       = 64
+	mappedHdrHashOff     = 72
+	mappedHdrStringsOff  = 80
+	mappedHdrStringsLen  = 88
+)
+
+// Field offsets within a node record. The eight strings come first.
+const (
+	mappedNodeID        = 0
+	mappedNodeName      = 8
+	mappedNodeFile      = 16
+	mappedNodeSignature = 24
+	mappedNodeReceiver  = 32
+	mappedNodePackage   = 40
+	mappedNodeLanguage  = 48
+	mappedNodeDoc       = 56
+	mappedNodeKind      = 64
+	mappedNodeStartLine = 68
+	mappedNodeEndLine   = 72
+	mappedNodeStartCol  = 76
+	mappedNodeEndCol    = 80
+	mappedNodeFlags     = 84
+	mappedNodeOutStart  = 88
+	mappedNodeOutCount  = 92
+	mappedNodeInStart   = 96
+	mappedNodeInCount   = 100
+)
+
+// Bits of a node record's flags word. Files written before the generated
+// bit existed store 0 or 1 here and decode unchanged.
+const (
+	mappedFlagExported  = 1 << 0
+	mappedFlagGenerated = 1 << 1
+)
+
+// Field offsets within an edge record.
+const (
+	mappedEdgeFrom      = 0
+	mappedEdgeTo        = 4
+	mappedEdgeType      = 8
+	mappedEdgeFile      = 12
+	mappedEdgeStartLine = 20
+	mappedEdgeEndLine   = 24
+	mappedEdgeStartCol  = 28
+	mappedEdgeEndCol    = 32
+)
+
+// Layout of an edge record's type word: the edge type in the low byte,
+// then the provenance pass and confidence. Files written before provenance
+// existed have zero high bytes and decode with no provenance. The
+// provenance detail is not stored.
+const (
+	mappedEdgeTypeMask        = 0xff
+	mappedEdgePassShift       = 8
+	mappedEdgeConfidenceShift = 16
+)
+
+// mappedMagic identifies a mapped graph file.
+var mappedMagic = [8]byte{'T', 'R', 'C', 'G', 'M', 'A', 'P', 0}
+
+// ErrInvalidMappedGraph is returned when a file is not a valid mapped
+// graph: wrong magic or version, truncated, or with out-of-range offsets.
+var ErrInvalidMappedGraph = errors.New("invalid mapped graph file")
+
+// GraphReader is the read API shared by the heap Graph and MappedGraph.
+//
+// Description:
+//
+//	Code that only reads a frozen graph can accept a GraphReader and be
+//	handed either an in-process Graph or a MappedGraph opened from a file
+//	another process wrote.
+type GraphReader interface {
+	// GetNode returns the node with the given ID.
+	GetNode(id string) (*Node, bool)
+
+	// FindCallersByID returns the direct callers of a symbol.
+	FindCallersByID(ctx context.Context, symbolID string, opts ...QueryOption) (*QueryResult, error)
+
+	// Nodes iterates over all nodes in unspecified order.
+	Nodes() func(yield func(string, *Node) bool)
+
+	// AllEdges iterates over all edges in unspecified order.
+	AllEdges() func(yield func(*Edge) bool)
+
+	// NodeCount returns the number of nodes.
+	NodeCount() int
+
+	// EdgeCount returns the number of edges.
+	EdgeCount() int
+}
+
+var (
+	_ GraphReader = (*Graph)(nil)
+	_ GraphReader = (*MappedGraph)(nil)
+)
+
+// AllEdges returns an iterator over all edges in the graph.
+//
+// Description:
+//
+//	The iterator form of Edges, so callers can range over the edges of a
+//	Graph and a MappedGraph alike.
+//
+// Thread Safety: Safe for concurrent use on frozen graphs.
+func (g *Graph) AllEdges() func(yield func(*Edge) bool) {
+	return func(yield func(*Edge) bool) {
+		for _, edge := range g.edges {
+			if !yield(edge) {
+				return
+			}
+		}
+	}
+}
+
+// WriteMapped writes a frozen graph in the mapped graph format.
+//
+// Description:
+//
+//	Produces a compact, flat file that OpenMappedGraph maps read-only, so
+//	several processes can share one copy of the graph through the page
+//	cache instead of each building its own. The file is written to a temp
+//	file and renamed into place.
+//
+//	Symbols keep their ID, name, kind, location, signature, receiver,
+//	package, language, export flag and doc comment. Metadata, children and
+//	call sites are not written.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and tracing. Must not be nil.
+//	path - Path of the file to create. Parent dir must exist.
+//
+// Outputs:
+//
+//	error - Non-nil if the graph is not frozen, is too large for 32-bit
+//	  offsets, or I/O fails.
+//
+// Thread Safety: Safe for concurrent use on frozen graphs.
+func (g *Graph) WriteMapped(ctx context.Context, path string) error {
+	ctx, span := otel.Tracer("graph").Start(ctx, "graph.WriteMapped")
+	defer span.End()
+
+	if g == nil {
+		return fmt.Errorf("graph must not be nil")
+	}
+	if !g.IsFrozen() {
+		return fmt.Errorf("graph must be frozen before writing a mapped graph")
+	}
+	span.SetAttributes(
+		attribute.Int("node_count", g.NodeCount()),
+		attribute.Int("edge_count", g.EdgeCount()),
+	)
+
+	data, err := g.encodeMapped(ctx)
+	if err != nil {
+		return err
+	}
+
+	tmpPath := path + ".tmp"
+	renamed := false
+	defer func() {
+		if !renamed {
+			os.Remove(tmpPath)
+		}
+	}()
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", tmpPath, err)
+	}
+	w := bufio.NewWriterSize(f, 1<<20)
+	if _, err := w.Write(data); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", tmpPath, err)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", tmpPath, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("renaming %s -> %s: %w", tmpPath, path, err)
+	}
+	renamed = true
+
+	span.SetAttributes(attribute.Int("bytes", len(data)))
+	return nil
+}
+
+// mappedStrings builds the deduplicated string table.
+type mappedStrings struct {
+	buf     []byte
+	offsets map[string]uint32
+}
+
+// ref appends s to the table if new and returns its encoded reference.
+func (t *mappedStrings) ref(s string) (uint32, uint32, error) {
+	if s == "" {
+		return 0, 0, nil
+	}
+	if off, ok := t.offsets[s]; ok {
+		return off, uint32(len(s)), nil
+	}
+	if uint64(len(t.buf))+uint64(len(s)) > 1<<32-1 {
+		return 0, 0, fmt.Errorf("string table exceeds 4 GiB")
+	}
+	off := uint32(len(t.buf))
+	t.buf = append(t.buf, s...)
+	t.offsets[s] = off
+	return off, uint32(len(s)), nil
+}
+
+// encodeMapped serializes the graph into the mapped graph format.
+func (g *Graph) encodeMapped(ctx context.Context) ([]byte, error) {
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	index := make(map[string]uint32, len(ids))
+	for i, id := range ids {
+		index[id] = uint32(i)
+	}
+	if len(ids) > 1<<31 || len(g.edges) > 1<<31 {
+		return nil, fmt.Errorf("graph too large for the mapped format")
+	}
+
+	nodeCount, edgeCount := len(ids), 0
+	for _, id := range ids {
+		edgeCount += len(g.nodes[id].Outgoing)
+	}
+	hashSlots := 1
+	for hashSlots < 2*nodeCount {
+		hashSlots <<= 1
+	}
+
+	nodesOff := uint64(mappedHeaderSize)
+	edgesOff := nodesOff + uint64(nodeCount)*mappedNodeSize
+	inOff := edgesOff + uint64(edgeCount)*mappedEdgeSize
+	hashOff := inOff + uint64(edgeCount)*4
+	stringsOff := hashOff + uint64(hashSlots)*mappedSlotSize
+
+	fixed := make([]byte, stringsOff)
+	le := binary.LittleEndian
+	strs := &mappedStrings{offsets: make(map[string]uint32)}
+	putRef := func(buf []byte, s string) error {
+		off, n, err := strs.ref(s)
+		if err != nil {
+			return err
+		}
+		le.PutUint32(buf, off)
+		le.PutUint32(buf[4:], n)
+		return nil
+	}
+
+	// Edges, grouped by source node, and the edge index of each heap edge
+	// for the incoming index.
+	edgeIndex := make(map[*Edge]uint32, edgeCount)
+	next := uint32(0)
+	for i, id := range ids {
+		if i%4096 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		for _, edge := range g.nodes[id].Outgoing {
+			to, ok := index[edge.ToID]
+			if !ok {
+				return nil, fmt.Errorf("edge %s -> %s: %w", edge.FromID, edge.ToID, ErrNodeNotFound)
+			}
+			rec := fixed[edgesOff+uint64(next)*mappedEdgeSize:]
+			le.PutUint32(rec[mappedEdgeFrom:], uint32(i))
+			le.PutUint32(rec[mappedEdgeTo:], to)
+			le.PutUint32(rec[mappedEdgeType:], uint32(edge.Type)&mappedEdgeTypeMask|
+				uint32(edge.Provenance.Pass)<<mappedEdgePassShift|
+				uint32(edge.Provenance.Confidence)<<mappedEdgeConfidenceShift)
+			if err := putRef(rec[mappedEdgeFile:], edge.Location.FilePath); err != nil {
+				return nil, err
+			}
+			le.PutUint32(rec[mappedEdgeStartLine:], uint32(edge.Location.StartLine))
+			le.PutUint32(rec[mappedEdgeEndLine:], uint32(edge.Location.EndLine))
+			le.PutUint32(rec[mappedEdgeStartCol:], uint32(edge.Location.StartCol))
+			le.PutUint32(rec[mappedEdgeEndCol:], uint32(edge.Location.EndCol))
+			edgeIndex[edge] = next
+			next++
+		}
+	}
+
+	// Nodes, the incoming index and the ID hash table.
+	outStart, inStart := uint32(0), uint32(0)
+	for i, id := range ids {
+		node := g.nodes[id]
+		rec := fixed[nodesOff+uint64(i)*mappedNodeSize:]
+		sym := node.Symbol
+		if sym == nil {
+			sym = &ast.Symbol{ID: id}
+		}
+		for _, f := range []struct {
+			at int
+			s  string
+		}{
+			{mappedNodeID, id},
+			{mappedNodeName, sym.Name},
+			{mappedNodeFile, sym.FilePath},
+			{mappedNodeSignature, sym.Signature},
+			{mappedNodeReceiver, sym.Receiver},
+			{mappedNodePackage, sym.Package},
+			{mappedNodeLanguage, sym.Language},
+			{mappedNodeDoc, sym.DocComment},
+		} {
+			if err := putRef(rec[f.at:], f.s); err != nil {
+				return nil, err
+			}
+		}
+		le.PutUint32(rec[mappedNodeKind:], uint32(sym.Kind))
+		le.PutUint32(rec[mappedNodeStartLine:], uint32(sym.StartLine))
+		le.PutUint32(rec[mappedNodeEndLine:], uint32(sym.EndLine))
+		le.PutUint32(rec[mappedNodeStartCol:], uint32(sym.StartCol))
+		le.PutUint32(rec[mappedNodeEndCol:], uint32(sym.EndCol))
+		var flags uint32
+		if sym.Exported {
+			flags |= mappedFlagExported
+		}
+		if sym.Generated {
+			flags |= mappedFlagGenerated
+		}
+		le.PutUint32(rec[mappedNodeFlags:], flags)
+		le.PutUint32(rec[mappedNodeOutStart:], outStart)
+		le.PutUint32(rec[mappedNodeOutCount:], uint32(len(node.Outgoing)))
+		outStart += uint32(len(node.Outgoing))
+
+		incoming := 0
+		for _, edge := range node.Incoming {
+			idx, ok := edgeIndex[edge]
+			if !ok {
+				continue
+			}
+			le.PutUint32(fixed[inOff+uint64(inStart)*4+uint64(incoming)*4:], idx)
+			incoming++
+		}
+		le.PutUint32(rec[mappedNodeInStart:], inStart)
+		le.PutUint32(rec[mappedNodeInCount:], uint32(incoming))
+		inStart += uint32(incoming)
+
+		mask := uint64(hashSlots - 1)
+		h := mappedHash(id)
+		for slot := h & mask; ; slot = (slot + 1) & mask {
+			at := hashOff + slot*mappedSlotSize
+			if le.Uint32(fixed[at:]) == 0 {
+				le.PutUint32(fixed[at:], uint32(i)+1)
+				le.PutUint32(fixed[at+4:], uint32(h>>32))
+				break
+			}
+		}
+	}
+
+	copy(fixed, mappedMagic[:])
+	le.PutUint32(fixed[mappedHdrVersion:], MappedGraphVersion)
+	le.PutUint32(fixed[mappedHdrNodeCount:], uint32(nodeCount))
+	le.PutUint32(fixed[mappedHdrEdgeCount:], uint32(edgeCount))
+	le.PutUint32(fixed[mappedHdrHashSlots:], uint32(hashSlots))
+	le.PutUint64(fixed[mappedHdrBuiltAt:], uint64(g.BuiltAtMilli))
+	if err := putRef(fixed[mappedHdrProjectRoot:], g.ProjectRoot); err != nil {
+		return nil, err
+	}
+	if err := putRef(fixed[mappedHdrGraphHash:], g.Hash()); err != nil {
+		return nil, err
+	}
+	le.PutUint64(fixed[mappedHdrNodesOff:], nodesOff)
+	le.PutUint64(fixed[mappedHdrEdgesOff:], edgesOff)
+	le.PutUint64(fixed[mappedHdrInOff:], inOff)
+	le.PutUint64(fixed[mappedHdrHashOff:], hashOff)
+	le.PutUint64(fixed[mappedHdrStringsOff:], stringsOff)
+	le.PutUint64(fixed[mappedHdrStringsLen:], uint64(len(strs.buf)))
+
+	return append(fixed, strs.buf...), nil
+}
+
+// mappedHash is the hash used by the ID table. It mixes eight bytes at a
+// time with the FNV-1a constants and finishes with a xor-shift so that the
+// low bits, which pick the slot, depend on every byte. It must never
+// change within a format version.
+func mappedHash(id string) uint64 {
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64)
+	for len(id) >= 8 {
+		h = (h ^ binary.LittleEndian.Uint64(unsafe.Slice(unsafe.StringData(id), 8))) * prime64
+		h ^= h >> 29
+		id = id[8:]
+	}
+	for i := 0; i < len(id); i++ {
+		h = (h ^ uint64(id[i])) * prime64
+	}
+	h ^= h >> 32
+	h *= 0x9e3779b97f4a7c15
+	return h ^ h>>29
+}
+
+// MappedGraph is a read-only graph backed by a memory-mapped file written
+// by WriteMapped.
+//
+// Description:
+//
+//	Nodes and edges are decoded from the mapping on demand, so opening is
+//	cheap and the file's pages are shared through the page cache by every
+//	process that maps it. Strings returned in nodes, symbols and edges
+//	point into the mapping rather than being copied.
+//
+//	A node is decoded once, on first access, and then reused, so the heap
+//	cost grows with the nodes a process touches rather than with the
+//	graph.
+//
+// Limitations:
+//
+//	Strictly read-only; there is no way to add or remove nodes or edges.
+//	Symbol.Metadata, Children and Calls are not stored and are always nil.
+//	Nodes, symbols and edges obtained from a MappedGraph, and their
+//	strings, must not be used after Close.
+//
+// Thread Safety:
+//
+//	Safe for concurrent reads. Close must not be called concurrently with
+//	reads.
+type MappedGraph struct {
+	data    []byte
+	unmap   func() error
+	decoded []atomic.Pointer[Node]
+
+	nodeCount int
+	edgeCount int
+	hashSlots uint64
+	nodesOff  uint64
+	edgesOff  uint64
+	inOff     uint64
+	hashOff   uint64
+	strings   []byte
+
+	projectRoot  string
+	graphHash    string
+	builtAtMilli int64
+}
+
+// OpenMappedGraph maps a mapped graph file read-only.
+//
+// Description:
+//
+//	Maps the file (on Windows it is read into memory instead) and
+//	validates the header and every offset in it, so later reads need no
+//	bounds checks beyond Go's own. The caller must call Close.
+//
+// Inputs:
+//
+//	path - Path to a file written by WriteMapped.
+//
+// Outputs:
+//
+//	*MappedGraph - The graph. Caller must Close.
+//	error - Wraps ErrInvalidMappedGraph if the file is not a valid mapped
+//	  graph, or is an I/O error.
+//
+// Thread Safety: Safe for concurrent use after construction.
+func OpenMappedGraph(path string) (*MappedGraph, error) {
+	data, unmap, err := mapFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("mapping %s: %w", path, err)
+	}
+	m, err := newMappedGraph(data)
+	if err != nil {
+		unmap()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	m.unmap = unmap
+	return m, nil
+}
+
+// newMappedGraph validates data and wraps it.
+func newMappedGraph(data []byte) (*MappedGraph, error) {
+	le := binary.LittleEndian
+	if len(data) < mappedHeaderSize || [8]byte(data[:8]) != mappedMagic {
+		return nil, fmt.Errorf("%w: bad magic", ErrInvalidMappedGraph)
+	}
+	if v := le.Uint32(data[mappedHdrVersion:]); v != MappedGraphVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d (expected %d)", ErrInvalidMappedGraph, v, MappedGraphVersion)
+	}
+
+	m := &MappedGraph{
+		data:         data,
+		nodeCount:    int(le.Uint32(data[mappedHdrNodeCount:])),
+		edgeCount:    int(le.Uint32(data[mappedHdrEdgeCount:])),
+		hashSlots:    uint64(le.Uint32(data[mappedHdrHashSlots:])),
+		builtAtMilli: int64(le.Uint64(data[mappedHdrBuiltAt:])),
+		nodesOff:     le.Uint64(data[mappedHdrNodesOff:]),
+		edgesOff:     le.Uint64(data[mappedHdrEdgesOff:]),
+		inOff:        le.Uint64(data[mappedHdrInOff:]),
+		hashOff:      le.Uint64(data[mappedHdrHashOff:]),
+	}
+	stringsOff := le.Uint64(data[mappedHdrStringsOff:])
+	stringsLen := le.Uint64(data[mappedHdrStringsLen:])
+
+	size := uint64(len(data))
+	nodes, edges := uint64(m.nodeCount), uint64(m.edgeCount)
+	if m.hashSlots == 0 || m.hashSlots&(m.hashSlots-1) != 0 || m.hashSlots < nodes ||
+		m.nodesOff != mappedHeaderSize ||
+		m.edgesOff != m.nodesOff+nodes*mappedNodeSize ||
+		m.inOff != m.edgesOff+edges*mappedEdgeSize ||
+		m.hashOff != m.inOff+edges*4 ||
+		stringsOff != m.hashOff+m.hashSlots*mappedSlotSize ||
+		stringsOff+stringsLen != size {
+		return nil, fmt.Errorf("%w: section offsets do not match the file size", ErrInvalidMappedGraph)
+	}
+	m.strings = data[stringsOff:size]
+
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	m.projectRoot = m.str(data[mappedHdrProjectRoot:])
+	m.graphHash = m.str(data[mappedHdrGraphHash:])
+	m.decoded = make([]atomic.Pointer[Node], m.nodeCount)
+	return m, nil
+}
+
+// validate checks every string reference, node index and edge range.
+func (m *MappedGraph) validate() error {
+	le := binary.LittleEndian
+	strLen := uint64(len(m.strings))
+	checkRef := func(b []byte) bool {
+		return uint64(le.Uint32(b))+uint64(le.Uint32(b[4:])) <= strLen
+	}
+	if !checkRef(m.data[mappedHdrProjectRoot:]) || !checkRef(m.data[mappedHdrGraphHash:]) {
+		return fmt.Errorf("%w: header string out of range", ErrInvalidMappedGraph)
+	}
+
+	edges := uint64(m.edgeCount)
+	for i := 0; i < m.nodeCount; i++ {
+		rec := m.node(uint32(i))
+		for at := 0; at < mappedNodeKind; at += 8 {
+			if !checkRef(rec[at:]) {
+				return fmt.Errorf("%w: node %d string out of range", ErrInvalidMappedGraph, i)
+			}
+		}
+		out := uint64(le.Uint32(rec[mappedNodeOutStart:])) + uint64(le.Uint32(rec[mappedNodeOutCount:]))
+		in := uint64(le.Uint32(rec[mappedNodeInStart:])) + uint64(le.Uint32(rec[mappedNodeInCount:]))
+		if out > edges || in > edges {
+			return fmt.Errorf("%w: node %d edge range out of range", ErrInvalidMappedGraph, i)
+		}
+	}
+	for i := 0; i < m.edgeCount; i++ {
+		rec := m.edge(uint32(i))
+		if int(le.Uint32(rec[mappedEdgeFrom:])) >= m.nodeCount || int(le.Uint32(rec[mappedEdgeTo:])) >= m.nodeCount ||
+			!checkRef(rec[mappedEdgeFile:]) {
+			return fmt.Errorf("%w: edge %d out of range", ErrInvalidMappedGraph, i)
+		}
+		if idx := le.Uint32(m.data[m.inOff+uint64(i)*4:]); int(idx) >= m.edgeCount {
+			return fmt.Errorf("%w: incoming index %d out of range", ErrInvalidMappedGraph, i)
+		}
+	}
+	for slot := uint64(0); slot < m.hashSlots; slot++ {
+		if v := le.Uint32(m.data[m.hashOff+slot*mappedSlotSize:]); int(v) > m.nodeCount {
+			return fmt.Errorf("%w: hash slot %d out of range", ErrInvalidMappedGraph, slot)
+		}
+	}
+	return nil
+}
+
+// Close unmaps the file. Nodes, symbols, edges and strings obtained from
+// the graph must not be used afterwards.
+func (m *MappedGraph) Close() error {
+	if m.unmap == nil {
+		return nil
+	}
+	unmap := m.unmap
+	m.unmap = nil
+	m.data, m.strings, m.decoded = nil, nil, nil
+	return unmap()
+}
+
+// node returns the record of node i.
+func (m *MappedGraph) node(i uint32) []byte {
+	at := m.nodesOff + uint64(i)*mappedNodeSize
+	return m.data[at : at+mappedNodeSize]
+}
+
+// edge returns the record of edge i.
+func (m *MappedGraph) edge(i uint32) []byte {
+	at := m.edgesOff + uint64(i)*mappedEdgeSize
+	return m.data[at : at+mappedEdgeSize]
+}
+
+// str returns the string referenced at b, pointing into the mapping.
+func (m *MappedGraph) str(b []byte) string {
+	off := binary.LittleEndian.Uint32(b)
+	n := binary.LittleEndian.Uint32(b[4:])
+	if n == 0 {
+		return ""
+	}
+	return unsafe.String(&m.strings[off], int(n))
+}
+
+// lookup returns the index of the node with the given ID.
+func (m *MappedGraph) lookup(id string) (uint32, bool) {
+	if m.nodeCount == 0 {
+		return 0, false
+	}
+	le := binary.LittleEndian
+	mask := m.hashSlots - 1
+	h := mappedHash(id)
+	tag := uint32(h >> 32)
+	for slot, probes := h&mask, uint64(0); probes < m.hashSlots; slot, probes = (slot+1)&mask, probes+1 {
+		at := m.hashOff + slot*mappedSlotSize
+		v := le.Uint32(m.data[at:])
+		if v == 0 {
+			return 0, false
+		}
+		// Compare the stored hash bits first so a collision costs no
+		// access to the node record.
+		if le.Uint32(m.data[at+4:]) != tag {
+			continue
+		}
+		rec := m.node(v - 1)
+		off := le.Uint32(rec[mappedNodeID:])
+		n := le.Uint32(rec[mappedNodeID+4:])
+		if int(n) == len(id) && string(m.strings[off:off+n]) == id {
+			return v - 1, true
+		}
+	}
+	return 0, false
+}
+
+// symbol decodes the symbol of node i.
+func (m *MappedGraph) symbol(i uint32) *ast.Symbol {
+	le := binary.LittleEndian
+	rec := m.node(i)
+	flags := le.Uint32(rec[mappedNodeFlags:])
+	return &ast.Symbol{
+		ID:         m.str(rec[mappedNodeID:]),
+		Name:       m.str(rec[mappedNodeName:]),
+		Kind:       ast.SymbolKind(le.Uint32(rec[mappedNodeKind:])),
+		FilePath:   m.str(rec[mappedNodeFile:]),
+		StartLine:  int(le.Uint32(rec[mappedNodeStartLine:])),
+		EndLine:    int(le.Uint32(rec[mappedNodeEndLine:])),
+		StartCol:   int(le.Uint32(rec[mappedNodeStartCol:])),
+		EndCol:     int(le.Uint32(rec[mappedNodeEndCol:])),
+		Signature:  m.str(rec[mappedNodeSignature:]),
+		DocComment: m.str(rec[mappedNodeDoc:]),
+		Receiver:   m.str(rec[mappedNodeReceiver:]),
+		Package:    m.str(rec[mappedNodePackage:]),
+		Exported:   flags&mappedFlagExported != 0,
+		Generated:  flags&mappedFlagGenerated != 0,
+		Language:   m.str(rec[mappedNodeLanguage:]),
+	}
+}
+
+// decodeEdge decodes edge i.
+func (m *MappedGraph) decodeEdge(i uint32) *Edge {
+	le := binary.LittleEndian
+	rec := m.edge(i)
+	typeWord := le.Uint32(rec[mappedEdgeType:])
+	return &Edge{
+		FromID: m.str(m.node(le.Uint32(rec[mappedEdgeFrom:]))[mappedNodeID:]),
+		ToID:   m.str(m.node(le.Uint32(rec[mappedEdgeTo:]))[mappedNodeID:]),
+		Type:   EdgeType(typeWord & mappedEdgeTypeMask),
+		Provenance: EdgeProvenance{
+			Pass:       EdgePass(typeWord >> mappedEdgePassShift),
+			Confidence: EdgeConfidence(typeWord >> mappedEdgeConfidenceShift),
+		},
+		Location: ast.Location{
+			FilePath:  m.str(rec[mappedEdgeFile:]),
+			StartLine: int(le.Uint32(rec[mappedEdgeStartLine:])),
+			EndLine:   int(le.Uint32(rec[mappedEdgeEndLine:])),
+			StartCol:  int(le.Uint32(rec[mappedEdgeStartCol:])),
+			EndCol:    int(le.Uint32(rec[mappedEdgeEndCol:])),
+		},
+	}
+}
+
+// nodeAt returns node i, decoding and caching it on first access.
+func (m *MappedGraph) nodeAt(i uint32) *Node {
+	if node := m.decoded[i].Load(); node != nil {
+		return node
+	}
+	le := binary.LittleEndian
+	rec := m.node(i)
+	node := &Node{Symbol: m.symbol(i)}
+	node.ID = node.Symbol.ID
+
+	outStart, outCount := le.Uint32(rec[mappedNodeOutStart:]), le.Uint32(rec[mappedNodeOutCount:])
+	if outCount > 0 {
+		node.Outgoing = make([]*Edge, outCount)
+		for j := uint32(0); j < outCount; j++ {
+			node.Outgoing[j] = m.decodeEdge(outStart + j)
+		}
+	}
+	inStart, inCount := le.Uint32(rec[mappedNodeInStart:]), le.Uint32(rec[mappedNodeInCount:])
+	if inCount > 0 {
+		node.Incoming = make([]*Edge, inCount)
+		for j := uint32(0); j < inCount; j++ {
+			node.Incoming[j] = m.decodeEdge(le.Uint32(m.data[m.inOff+uint64(inStart+j)*4:]))
+		}
+	}
+
+	// Concurrent decodes of the same node produce equal values; keep the
+	// first so callers see one pointer per node.
+	if !m.decoded[i].CompareAndSwap(nil, node) {
+		return m.decoded[i].Load()
+	}
+	return node
+}
+
+// GetNode returns the node with the given ID.
+//
+// Description:
+//
+//	Looks the ID up in the file's hash table and decodes the node with its
+//	outgoing and incoming edges on first access.
+//
+// Thread Safety: Safe for concurrent use.
+func (m *MappedGraph) GetNode(id string) (*Node, bool) {
+	i, ok := m.lookup(id)
+	if !ok {
+		return nil, false
+	}
+	return m.nodeAt(i), true
+}
+
+// FindCallersByID returns all symbols that call the given function/method.
+//
+// Description:
+//
+//	Same semantics as Graph.FindCallersByID: a missing node gives an empty
+//	result, and a cancelled context a truncated one. Reads the incoming
+//	index directly, decoding only the callers.
+//
+// Thread Safety: Safe for concurrent use.
+func (m *MappedGraph) FindCallersByID(ctx context.Context, symbolID string, opts ...QueryOption) (*QueryResult, error) {
+	start := time.Now()
+	options := applyOptions(opts)
+	result := &QueryResult{Symbols: make([]*ast.Symbol, 0)}
+
+	i, ok := m.lookup(symbolID)
+	if !ok {
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
+	le := binary.LittleEndian
+	rec := m.node(i)
+	inStart, inCount := le.Uint32(rec[mappedNodeInStart:]), le.Uint32(rec[mappedNodeInCount:])
+	for j := uint32(0); j < inCount; j++ {
+		if err := ctx.Err(); err != nil {
+			result.Truncated = true
+			result.Duration = time.Since(start)
+			return result, nil
+		}
+		edge := m.edge(le.Uint32(m.data[m.inOff+uint64(inStart+j)*4:]))
+		if EdgeType(le.Uint32(edge[mappedEdgeType:])&mappedEdgeTypeMask) != EdgeTypeCalls {
+			continue
+		}
+		if len(result.Symbols) >= options.Limit {
+			result.Truncated = true
+			break
+		}
+		result.Symbols = append(result.Symbols, m.nodeAt(le.Uint32(edge[mappedEdgeFrom:])).Symbol)
+	}
+
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// Nodes returns an iterator over all nodes, in ID order.
+//
+// Thread Safety: Safe for concurrent use.
+func (m *MappedGraph) Nodes() func(yield func(string, *Node) bool) {
+	return func(yield func(string, *Node) bool) {
+		for i := 0; i < m.nodeCount; i++ {
+			node := m.nodeAt(uint32(i))
+			if !yield(node.ID, node) {
+				return
+			}
+		}
+	}
+}
+
+// AllEdges returns an iterator over all edges, grouped by source node.
+//
+// Description:
+//
+//	Edges of nodes that have already been decoded are yielded from the
+//	node cache; the rest are decoded as they are yielded and not cached,
+//	so iterating does not grow the heap with the graph.
+//
+// Thread Safety: Safe for concurrent use.
+func (m *MappedGraph) AllEdges() func(yield func(*Edge) bool) {
+	return func(yield func(*Edge) bool) {
+		le := binary.LittleEndian
+		for i := 0; i < m.nodeCount; i++ {
+			if node := m.decoded[i].Load(); node != nil {
+				for _, e := range node.Outgoing {
+					if !yield(e) {
+						return
+					}
+				}
+				continue
+			}
+			rec := m.node(uint32(i))
+			start, count := le.Uint32(rec[mappedNodeOutStart:]), le.Uint32(rec[mappedNodeOutCount:])
+			for j := start; j < start+count; j++ {
+				if !yield(m.decodeEdge(j)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// NodeCount returns the number of nodes.
+func (m *MappedGraph) NodeCount() int { return m.nodeCount }
+
+// EdgeCount returns the number of edges.
+func (m *MappedGraph) EdgeCount() int { return m.edgeCount }
+
+// ProjectRoot returns the project root the graph was built from.
+func (m *MappedGraph) ProjectRoot() string { return m.projectRoot }
+
+// GraphHash returns the graph's content hash (Graph.Hash at write time).
+func (m *MappedGraph) GraphHash() string { return m.graphHash }
+
+// BuiltAtMilli returns when the source graph was frozen.
+func (m *MappedGraph) BuiltAtMilli() int64 { return m.builtAtMilli }
@@ -0,0 +1,285 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// mappedFixture builds a frozen graph of n functions in files of ten, where
+// function i calls i+1 and i+7 and references i+3.
+func mappedFixture(tb testing.TB, n int) *Graph {
+	tb.Helper()
+	g := NewGraph("/project", WithMaxNodes(n+1), WithMaxEdges(3*n+1))
+	id := func(i int) string { return fmt.Sprintf("pkg/f%d.go:%d:Func%d", i/10, i%10*10+1, i) }
+	for i := 0; i < n; i++ {
+		file := fmt.Sprintf("pkg/f%d.go", i/10)
+		if _, err := g.AddNode(&ast.Symbol{
+			ID: id(i), Name: fmt.Sprintf("Func%d", i), Kind: ast.SymbolKindFunction,
+			FilePath: file, StartLine: i%10*10 + 1, EndLine: i%10*10 + 8, EndCol: 1,
+			Signature: fmt.Sprintf("func Func%d(ctx context.Context) error", i),
+			Package:   "pkg", Language: "go", Exported: i%2 == 0, Generated: i%3 == 0,
+			DocComment: "// Func does things.",
+		}); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	for i := 0; i < n; i++ {
+		loc := ast.Location{FilePath: fmt.Sprintf("pkg/f%d.go", i/10), StartLine: i%10*10 + 2, EndLine: i%10*10 + 2, StartCol: 4, EndCol: 12}
+		for _, e := range []struct {
+			to int
+			t  EdgeType
+		}{{i + 1, EdgeTypeCalls}, {i + 7, EdgeTypeCalls}, {i + 3, EdgeTypeReferences}} {
+			if e.to >= n {
+				continue
+			}
+			if err := g.AddEdge(id(i), id(e.to), e.t, loc); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+// openMappedFixture writes g and maps it, closing it when the test ends.
+func openMappedFixture(tb testing.TB, g *Graph) *MappedGraph {
+	tb.Helper()
+	path := filepath.Join(tb.TempDir(), "graph.tgm")
+	if err := g.WriteMapped(context.Background(), path); err != nil {
+		tb.Fatalf("WriteMapped failed: %v", err)
+	}
+	m, err := OpenMappedGraph(path)
+	if err != nil {
+		tb.Fatalf("OpenMappedGraph failed: %v", err)
+	}
+	tb.Cleanup(func() { m.Close() })
+	return m
+}
+
+func edgeKeys(edges []*Edge) []string {
+	keys := make([]string, len(edges))
+	for i, e := range edges {
+		keys[i] = fmt.Sprintf("%s>%s:%d@%s", e.FromID, e.ToID, e.Type, e.Location)
+	}
+	return keys
+}
+
+func TestMappedGraph_MatchesHeapGraph(t *testing.T) {
+	g := mappedFixture(t, 200)
+	m := openMappedFixture(t, g)
+	ctx := context.Background()
+
+	if m.NodeCount() != g.NodeCount() || m.EdgeCount() != g.EdgeCount() {
+		t.Fatalf("counts = %d/%d, want %d/%d", m.NodeCount(), m.EdgeCount(), g.NodeCount(), g.EdgeCount())
+	}
+	if m.ProjectRoot() != "/project" || m.GraphHash() != g.Hash() || m.BuiltAtMilli() != g.BuiltAtMilli {
+		t.Errorf("metadata = %q %q %d", m.ProjectRoot(), m.GraphHash(), m.BuiltAtMilli())
+	}
+
+	// Iterate before and after the nodes are decoded and cached.
+	coldEdges := allEdgeKeys(m)
+
+	for id, want := range g.Nodes() {
+		got, ok := m.GetNode(id)
+		if !ok {
+			t.Fatalf("GetNode(%s) not found", id)
+		}
+		if !reflect.DeepEqual(got.Symbol, want.Symbol) {
+			t.Fatalf("symbol %s = %+v, want %+v", id, got.Symbol, want.Symbol)
+		}
+		if !reflect.DeepEqual(edgeKeys(got.Outgoing), edgeKeys(want.Outgoing)) ||
+			!reflect.DeepEqual(edgeKeys(got.Incoming), edgeKeys(want.Incoming)) {
+			t.Fatalf("edges of %s differ", id)
+		}
+		again, _ := m.GetNode(id)
+		if again != got {
+			t.Fatalf("GetNode(%s) decoded the node twice", id)
+		}
+
+		wantCallers, _ := g.FindCallersByID(ctx, id)
+		gotCallers, _ := m.FindCallersByID(ctx, id)
+		if !reflect.DeepEqual(gotCallers.Symbols, wantCallers.Symbols) {
+			t.Fatalf("FindCallersByID(%s) = %d callers, want %d", id, len(gotCallers.Symbols), len(wantCallers.Symbols))
+		}
+	}
+
+	if _, ok := m.GetNode("missing"); ok {
+		t.Error("GetNode(missing) found a node")
+	}
+	if res, err := m.FindCallersByID(ctx, "missing"); err != nil || len(res.Symbols) != 0 {
+		t.Errorf("FindCallersByID(missing) = %v, %v", res, err)
+	}
+	if res, _ := m.FindCallersByID(ctx, "pkg/f1.go:11:Func11", WithLimit(1)); len(res.Symbols) != 1 || !res.Truncated {
+		t.Errorf("limited FindCallersByID = %d symbols, truncated %v", len(res.Symbols), res.Truncated)
+	}
+
+	if !reflect.DeepEqual(allEdgeKeys(m), coldEdges) || !reflect.DeepEqual(coldEdges, allEdgeKeys(g)) {
+		t.Error("AllEdges differs from the heap graph")
+	}
+}
+
+// allEdgeKeys returns the sorted keys of every edge in r.
+func allEdgeKeys(r GraphReader) []string {
+	var edges []*Edge
+	for e := range r.AllEdges() {
+		edges = append(edges, e)
+	}
+	keys := edgeKeys(edges)
+	sort.Strings(keys)
+	return keys
+}
+
+func TestMappedGraph_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("unfrozen graph", func(t *testing.T) {
+		g := NewGraph("/project")
+		if err := g.WriteMapped(context.Background(), filepath.Join(dir, "x")); err == nil {
+			t.Error("WriteMapped on an unfrozen graph succeeded")
+		}
+	})
+
+	t.Run("empty graph round-trips", func(t *testing.T) {
+		g := NewGraph("/project")
+		g.Freeze()
+		m := openMappedFixture(t, g)
+		if m.NodeCount() != 0 {
+			t.Errorf("NodeCount = %d", m.NodeCount())
+		}
+		if _, ok := m.GetNode("x"); ok {
+			t.Error("GetNode found a node in an empty graph")
+		}
+	})
+
+	t.Run("corrupt files are rejected", func(t *testing.T) {
+		path := filepath.Join(dir, "good.tgm")
+		if err := mappedFixture(t, 20).WriteMapped(context.Background(), path); err != nil {
+			t.Fatal(err)
+		}
+		good, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cases := map[string][]byte{
+			"not a graph": []byte("hello, world"),
+			"truncated":   good[:len(good)-1],
+			"bad version": append(append([]byte{}, good[:mappedHdrVersion]...), append([]byte{9, 0, 0, 0}, good[mappedHdrVersion+4:]...)...),
+		}
+		// An edge pointing past the last node.
+		badEdge := append([]byte{}, good...)
+		edgesOff := mappedHeaderSize + 20*mappedNodeSize
+		badEdge[edgesOff+mappedEdgeTo] = 0xff
+		badEdge[edgesOff+mappedEdgeTo+1] = 0xff
+		cases["edge out of range"] = badEdge
+
+		for name, data := range cases {
+			p := filepath.Join(dir, "bad.tgm")
+			if err := os.WriteFile(p, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := OpenMappedGraph(p); !errors.Is(err, ErrInvalidMappedGraph) {
+				t.Errorf("%s: error = %v, want ErrInvalidMappedGraph", name, err)
+			}
+		}
+	})
+}
+
+// The mapped graph should answer within 2x of the heap graph. Run with
+//
+//	go test ./services/trace/graph -run '^$' -bench 'Graph_(GetNode|FindCallers|Edges)'
+func benchmarkReaders(b *testing.B) (*Graph, *MappedGraph, []string) {
+	g := mappedFixture(b, 50000)
+	m := openMappedFixture(b, g)
+	ids := make([]string, 0, g.NodeCount())
+	for id := range g.Nodes() {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	// Decode every node once so both graphs are measured warm.
+	for _, id := range ids {
+		m.GetNode(id)
+	}
+	return g, m, ids
+}
+
+func BenchmarkGraph_GetNode(b *testing.B) {
+	g, m, ids := benchmarkReaders(b)
+	for name, r := range map[string]GraphReader{"heap": g, "mapped": m} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, ok := r.GetNode(ids[i%len(ids)]); !ok {
+					b.Fatal("node not found")
+				}
+			}
+		})
+	}
+	b.Run("mapped_cold", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if i%len(ids) == 0 {
+				b.StopTimer()
+				m.decoded = make([]atomic.Pointer[Node], m.nodeCount)
+				b.StartTimer()
+			}
+			if _, ok := m.GetNode(ids[i%len(ids)]); !ok {
+				b.Fatal("node not found")
+			}
+		}
+	})
+}
+
+func BenchmarkGraph_FindCallersByID(b *testing.B) {
+	g, m, ids := benchmarkReaders(b)
+	ctx := context.Background()
+	for name, r := range map[string]GraphReader{"heap": g, "mapped": m} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := r.FindCallersByID(ctx, ids[i%len(ids)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkGraph_Edges(b *testing.B) {
+	g, m, _ := benchmarkReaders(b)
+	for name, r := range map[string]GraphReader{"heap": g, "mapped": m} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				calls := 0
+				for e := range r.AllEdges() {
+					if e.Type == EdgeTypeCalls {
+						calls++
+					}
+				}
+				if calls == 0 {
+					b.Fatal("no calls")
+				}
+			}
+		})
+	}
+}
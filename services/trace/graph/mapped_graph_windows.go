@@ -0,0 +1,31 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+//go:build windows
+
+package graph
+
+import (
+	"fmt"
+	"os"
+)
+
+// mapFile reads the file at path into memory. Windows processes do not
+// share the pages; the format and read path are otherwise the same.
+func mapFile(path string) ([]byte, func() error, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil, fmt.Errorf("%w: empty file", ErrInvalidMappedGraph)
+	}
+	return data, func() error { return nil }, nil
+}
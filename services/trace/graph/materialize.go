@@ -20,6 +20,8 @@ import (
 	bolt "go.etcd.io/bbolt"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // bbolt bucket names for graph persistence.
@@ -42,6 +44,8 @@ var (
 	metaKeyEdgeCount     = []byte("edge_count")
 	metaKeyGraphHash     = []byte("graph_hash")
 	metaKeyFileMtimes    = []byte("file_mtimes")
+	metaKeyFileHashes    = []byte("file_hashes")
+	metaKeyQuality       = []byte("quality_report")
 )
 
 // MaterializeToDisk persists a frozen graph to a bbolt file.
@@ -84,7 +88,7 @@ func (g *Graph) MaterializeToDisk(ctx context.Context, path string) error {
 	span.SetAttributes(
 		attribute.Int("node_count", g.NodeCount()),
 		attribute.Int("edge_count", g.EdgeCount()),
-		attribute.String("project_root", g.ProjectRoot),
+		telemetry.SensitiveString("project_root", g.ProjectRoot),
 	)
 
 	// Write to a temp file then rename for atomicity.
@@ -295,6 +299,16 @@ func (g *Graph) writeToBbolt(db *bolt.DB) error {
 			if err := add(metaKeyFileMtimes, func() ([]byte, error) { return encodeMetaFileMtimes(g.FileMtimes) }); err != nil {
 				return nil, err
 			}
+			if g.FileHashes != nil {
+				if err := add(metaKeyFileHashes, func() ([]byte, error) { return encodeMetaFileHashes(g.FileHashes) }); err != nil {
+					return nil, err
+				}
+			}
+			if g.QualityReport != nil {
+				if err := add(metaKeyQuality, func() ([]byte, error) { return encodeMetaQualityReport(g.QualityReport) }); err != nil {
+					return nil, err
+				}
+			}
 			return entries, nil
 		}
 
@@ -0,0 +1,312 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"strings"
+	"unsafe"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// mapEntryOverhead approximates the per-entry cost of a Go map beyond the
+// key and value themselves (tophash, bucket slack, overflow pointers).
+const mapEntryOverhead = 16
+
+// pointerSize is the size of one pointer slot in a slice.
+const pointerSize = int64(unsafe.Sizeof(uintptr(0)))
+
+// MemoryStats is an estimate of the heap held by a graph, by category.
+//
+// Estimates are derived from struct sizes, slice capacities and string
+// lengths; allocator rounding and map internals are approximated. They are
+// meant for comparing graphs and build options, not for exact accounting.
+type MemoryStats struct {
+	// NodeCount and EdgeCount are the graph sizes the estimate covers.
+	NodeCount int `json:"node_count"`
+	EdgeCount int `json:"edge_count"`
+
+	// NodeBytes covers Node structs and the symbols they point to,
+	// including call site and type reference arrays (excluding strings).
+	NodeBytes int64 `json:"node_bytes"`
+
+	// EdgeBytes covers Edge structs (excluding strings).
+	EdgeBytes int64 `json:"edge_bytes"`
+
+	// StringBytes is the string data referenced by nodes and edges, with
+	// each distinct allocation counted once.
+	StringBytes int64 `json:"string_bytes"`
+
+	// DuplicateStringBytes is the part of StringBytes held in allocations
+	// whose content is also stored elsewhere — what interning could still
+	// save.
+	DuplicateStringBytes int64 `json:"duplicate_string_bytes"`
+
+	// IndexBytes covers the node map, adjacency lists and secondary indexes.
+	IndexBytes int64 `json:"index_bytes"`
+
+	// InternTableBytes is the overhead of the build-time string table.
+	// Zero once the graph is frozen.
+	InternTableBytes int64 `json:"intern_table_bytes"`
+
+	// InternedStrings is the number of distinct strings in the table.
+	// Zero once the graph is frozen.
+	InternedStrings int `json:"interned_strings"`
+
+	// TotalBytes is the sum of all byte categories.
+	TotalBytes int64 `json:"total_bytes"`
+}
+
+// MemoryStats estimates the memory held by the graph, by category.
+//
+// Description:
+//
+//	Walks every node, symbol and edge once. String data is attributed by
+//	allocation, so strings shared through interning are counted once and
+//	copies with identical content show up in DuplicateStringBytes.
+//
+// Outputs:
+//
+//	MemoryStats - The estimate.
+//
+// Complexity:
+//
+//	O(V + E) time, plus temporary maps sized by the number of distinct
+//	strings.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs. Not safe during building.
+func (g *Graph) MemoryStats() MemoryStats {
+	stats := MemoryStats{
+		NodeCount: len(g.nodes),
+		EdgeCount: len(g.edges),
+	}
+
+	sc := newStringCounter()
+
+	for id, node := range g.nodes {
+		stats.NodeBytes += int64(unsafe.Sizeof(*node))
+		stats.IndexBytes += int64(unsafe.Sizeof(id)) + pointerSize + mapEntryOverhead
+		stats.IndexBytes += int64(cap(node.Outgoing)+cap(node.Incoming)) * pointerSize
+		sc.add(id)
+		sc.add(node.ID)
+		if node.Symbol != nil {
+			stats.NodeBytes += symbolBytes(node.Symbol)
+			sc.addSymbol(node.Symbol)
+		}
+	}
+
+	for _, edge := range g.edges {
+		stats.EdgeBytes += int64(unsafe.Sizeof(*edge))
+		sc.add(edge.FromID)
+		sc.add(edge.ToID)
+		sc.add(edge.Location.FilePath)
+	}
+
+	stats.IndexBytes += int64(cap(g.edges)) * pointerSize
+	for i := range g.edgesByType {
+		stats.IndexBytes += int64(cap(g.edgesByType[i])) * pointerSize
+	}
+	for file, edges := range g.edgesByFile {
+		sc.add(file)
+		stats.IndexBytes += int64(unsafe.Sizeof(file)+unsafe.Sizeof(edges)) + mapEntryOverhead
+		stats.IndexBytes += int64(cap(edges)) * pointerSize
+	}
+	for name, nodes := range g.nodesByName {
+		sc.add(name)
+		stats.IndexBytes += int64(unsafe.Sizeof(name)+unsafe.Sizeof(nodes)) + mapEntryOverhead
+		stats.IndexBytes += int64(cap(nodes)) * pointerSize
+	}
+	for _, nodes := range g.nodesByKind {
+		stats.IndexBytes += int64(cap(nodes)) * pointerSize
+	}
+
+	if g.strings != nil {
+		stats.InternTableBytes = g.strings.estimatedBytes()
+		stats.InternedStrings = g.strings.len()
+	}
+
+	stats.StringBytes = sc.bytes
+	stats.DuplicateStringBytes = sc.duplicateBytes
+	stats.TotalBytes = stats.NodeBytes + stats.EdgeBytes + stats.StringBytes +
+		stats.IndexBytes + stats.InternTableBytes
+	return stats
+}
+
+// FilePathInRoot reports whether filePath belongs to the workspace root
+// namespace root (e.g. "shared-lib"). An empty root matches every path.
+//
+// Thread Safety: Safe for concurrent use (pure function).
+func FilePathInRoot(filePath, root string) bool {
+	root = strings.Trim(root, "/")
+	if root == "" {
+		return true
+	}
+	return filePath == root || strings.HasPrefix(filePath, root+"/")
+}
+
+// MemoryStatsByRoot estimates the memory held by each workspace root.
+//
+// Description:
+//
+//	A node belongs to the root containing its symbol's file; an edge to the
+//	root containing its location. Nodes and edges outside every root, such
+//	as placeholders for external packages, are not attributed. Each root
+//	counts its node map entries and adjacency lists as IndexBytes; the
+//	graph-wide secondary indexes and the intern table are not split.
+//
+// Inputs:
+//
+//	roots - Workspace root namespaces. Must not overlap.
+//
+// Outputs:
+//
+//	map[string]MemoryStats - Estimate per root. Every root has an entry.
+//
+// Complexity:
+//
+//	O((V + E) * len(roots)) time.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs. Not safe during building.
+func (g *Graph) MemoryStatsByRoot(roots []string) map[string]MemoryStats {
+	stats := make(map[string]MemoryStats, len(roots))
+	counters := make(map[string]*stringCounter, len(roots))
+	for _, root := range roots {
+		stats[root] = MemoryStats{}
+		counters[root] = newStringCounter()
+	}
+	rootOf := func(filePath string) (string, bool) {
+		for _, root := range roots {
+			if FilePathInRoot(filePath, root) {
+				return root, true
+			}
+		}
+		return "", false
+	}
+
+	for id, node := range g.nodes {
+		if node.Symbol == nil {
+			continue
+		}
+		root, ok := rootOf(node.Symbol.FilePath)
+		if !ok {
+			continue
+		}
+		s := stats[root]
+		s.NodeCount++
+		s.NodeBytes += int64(unsafe.Sizeof(*node)) + symbolBytes(node.Symbol)
+		s.IndexBytes += int64(unsafe.Sizeof(id)) + pointerSize + mapEntryOverhead
+		s.IndexBytes += int64(cap(node.Outgoing)+cap(node.Incoming)) * pointerSize
+		stats[root] = s
+		sc := counters[root]
+		sc.add(id)
+		sc.add(node.ID)
+		sc.addSymbol(node.Symbol)
+	}
+
+	for _, edge := range g.edges {
+		root, ok := rootOf(edge.Location.FilePath)
+		if !ok {
+			continue
+		}
+		s := stats[root]
+		s.EdgeCount++
+		s.EdgeBytes += int64(unsafe.Sizeof(*edge))
+		stats[root] = s
+		sc := counters[root]
+		sc.add(edge.FromID)
+		sc.add(edge.ToID)
+		sc.add(edge.Location.FilePath)
+	}
+
+	for root, s := range stats {
+		s.StringBytes = counters[root].bytes
+		s.DuplicateStringBytes = counters[root].duplicateBytes
+		s.TotalBytes = s.NodeBytes + s.EdgeBytes + s.StringBytes + s.IndexBytes
+		stats[root] = s
+	}
+	return stats
+}
+
+// symbolBytes returns the non-string size of a symbol and its inline arrays.
+func symbolBytes(sym *ast.Symbol) int64 {
+	n := int64(unsafe.Sizeof(*sym))
+	n += int64(cap(sym.Calls)) * int64(unsafe.Sizeof(ast.CallSite{}))
+	n += int64(cap(sym.TypeReferences)) * int64(unsafe.Sizeof(ast.TypeReference{}))
+	n += int64(cap(sym.Children)) * pointerSize
+	if sym.Metadata != nil {
+		n += int64(unsafe.Sizeof(*sym.Metadata))
+	}
+	return n
+}
+
+// stringCounter attributes string bytes to distinct allocations.
+type stringCounter struct {
+	byData         map[*byte]struct{}
+	byContent      map[string]struct{}
+	bytes          int64
+	duplicateBytes int64
+}
+
+func newStringCounter() *stringCounter {
+	return &stringCounter{
+		byData:    make(map[*byte]struct{}),
+		byContent: make(map[string]struct{}),
+	}
+}
+
+// add counts s unless its backing array was already seen.
+func (c *stringCounter) add(s string) {
+	if s == "" {
+		return
+	}
+	data := unsafe.StringData(s)
+	if _, seen := c.byData[data]; seen {
+		return
+	}
+	c.byData[data] = struct{}{}
+	c.bytes += int64(len(s))
+	if _, seen := c.byContent[s]; seen {
+		c.duplicateBytes += int64(len(s))
+		return
+	}
+	c.byContent[s] = struct{}{}
+}
+
+// addSymbol counts the string fields of sym.
+func (c *stringCounter) addSymbol(sym *ast.Symbol) {
+	c.add(sym.ID)
+	c.add(sym.Name)
+	c.add(sym.FilePath)
+	c.add(sym.Signature)
+	c.add(sym.DocComment)
+	c.add(sym.Receiver)
+	c.add(sym.Package)
+	c.add(sym.Language)
+	for i := range sym.Calls {
+		c.add(sym.Calls[i].Target)
+		c.add(sym.Calls[i].Receiver)
+		c.add(sym.Calls[i].Location.FilePath)
+	}
+	for i := range sym.TypeReferences {
+		c.add(sym.TypeReferences[i].Name)
+		c.add(sym.TypeReferences[i].Location.FilePath)
+	}
+	if md := sym.Metadata; md != nil {
+		c.add(md.ReturnType)
+		c.add(md.AccessModifier)
+		c.add(md.Extends)
+		c.add(md.ParentName)
+	}
+}
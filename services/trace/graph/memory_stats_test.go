@@ -0,0 +1,351 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// decodedEdge is an edge as it comes out of a decoder: endpoint IDs are
+// fresh copies, not the node IDs.
+type decodedEdge struct {
+	from, to string
+	loc      ast.Location
+}
+
+// makeDecodedCorpus builds symbolCount Go methods spread over files of 20
+// symbols each. Each symbol makes callsPerSymbol calls (one call edge per
+// call site) and references context.Context and error. Every string is a
+// separate allocation, as it would be after decoding a snapshot or bbolt
+// graph.
+func makeDecodedCorpus(symbolCount, callsPerSymbol int) ([]*ast.Symbol, []decodedEdge) {
+	const perFile = 20
+	symbols := make([]*ast.Symbol, symbolCount)
+	for i := range symbols {
+		file := fmt.Sprintf("services/module_%d/internal/handlers/file_%d.go", i/perFile/10, i/perFile)
+		name := fmt.Sprintf("Handle%d", i)
+		line := i%perFile*10 + 1
+		symbols[i] = &ast.Symbol{
+			ID:        ast.GenerateID(file, line, name),
+			Name:      name,
+			Kind:      ast.SymbolKindMethod,
+			FilePath:  strings.Clone(file),
+			StartLine: line,
+			EndLine:   line + 9,
+			Signature: fmt.Sprintf("func (s *Service%d) %s(ctx context.Context) error", i/perFile, name),
+			Receiver:  fmt.Sprintf("Service%d", i/perFile),
+			Package:   fmt.Sprintf("module_%d", i/perFile/10),
+			Language:  strings.Clone("go"),
+			Exported:  true,
+			Metadata:  &ast.SymbolMetadata{ReturnType: strings.Clone("error")},
+			TypeReferences: []ast.TypeReference{
+				{Name: strings.Clone("context.Context"), Location: ast.Location{FilePath: strings.Clone(file), StartLine: line}},
+				{Name: strings.Clone("error"), Location: ast.Location{FilePath: strings.Clone(file), StartLine: line}},
+			},
+		}
+	}
+
+	edges := make([]decodedEdge, 0, symbolCount*callsPerSymbol)
+	for i, sym := range symbols {
+		sym.Calls = make([]ast.CallSite, 0, callsPerSymbol)
+		for k := 1; k <= callsPerSymbol; k++ {
+			target := symbols[(i+k*7)%len(symbols)]
+			loc := ast.Location{FilePath: strings.Clone(sym.FilePath), StartLine: sym.StartLine + k}
+			sym.Calls = append(sym.Calls, ast.CallSite{
+				Target:   strings.Clone(target.Name),
+				Location: loc,
+				IsMethod: true,
+				Receiver: strings.Clone("s"),
+			})
+			edges = append(edges, decodedEdge{
+				from: strings.Clone(sym.ID),
+				to:   strings.Clone(target.ID),
+				loc:  ast.Location{FilePath: strings.Clone(sym.FilePath), StartLine: sym.StartLine + k},
+			})
+		}
+	}
+	return symbols, edges
+}
+
+func buildDecodedGraph(tb testing.TB, symbols []*ast.Symbol, edges []decodedEdge, opts ...GraphOption) *Graph {
+	tb.Helper()
+	g := NewGraph("/test", opts...)
+	for _, sym := range symbols {
+		if _, err := g.AddNode(sym); err != nil {
+			tb.Fatalf("AddNode: %v", err)
+		}
+	}
+	for _, e := range edges {
+		if err := g.AddEdge(e.from, e.to, EdgeTypeCalls, e.loc); err != nil {
+			tb.Fatalf("AddEdge: %v", err)
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+func TestGraph_StringInterning(t *testing.T) {
+	symbols, edges := makeDecodedCorpus(100, 2)
+	g := buildDecodedGraph(t, symbols, edges)
+
+	t.Run("edges share node IDs", func(t *testing.T) {
+		for _, e := range g.edges {
+			from, _ := g.GetNode(e.FromID)
+			to, _ := g.GetNode(e.ToID)
+			if unsafe.StringData(e.FromID) != unsafe.StringData(from.ID) {
+				t.Fatalf("edge FromID %s not shared with node ID", e.FromID)
+			}
+			if unsafe.StringData(e.ToID) != unsafe.StringData(to.ID) {
+				t.Fatalf("edge ToID %s not shared with node ID", e.ToID)
+			}
+		}
+	})
+
+	t.Run("symbol fields share storage per value", func(t *testing.T) {
+		a, b := symbols[0], symbols[1]
+		if a.FilePath != b.FilePath {
+			t.Fatal("test corpus should put the first two symbols in one file")
+		}
+		if unsafe.StringData(a.FilePath) != unsafe.StringData(b.FilePath) {
+			t.Error("FilePath not interned")
+		}
+		if unsafe.StringData(a.Language) != unsafe.StringData(symbols[99].Language) {
+			t.Error("Language not interned")
+		}
+	})
+
+	t.Run("public API returns equal strings", func(t *testing.T) {
+		node, ok := g.GetNode(symbols[5].ID)
+		if !ok {
+			t.Fatal("node not found")
+		}
+		if node.Symbol.FilePath != "services/module_0/internal/handlers/file_0.go" || node.Symbol.Language != "go" {
+			t.Errorf("unexpected symbol fields: %q %q", node.Symbol.FilePath, node.Symbol.Language)
+		}
+	})
+
+	t.Run("freeze releases table", func(t *testing.T) {
+		if g.strings != nil {
+			t.Error("expected string table to be released on Freeze")
+		}
+		if stats := g.MemoryStats(); stats.InternTableBytes != 0 || stats.InternedStrings != 0 {
+			t.Errorf("expected no intern table after Freeze, got %+v", stats)
+		}
+	})
+}
+
+func TestGraph_StringInterning_Disabled(t *testing.T) {
+	symbols, edges := makeDecodedCorpus(40, 1)
+	g := buildDecodedGraph(t, symbols, edges, WithStringInterning(false))
+
+	if unsafe.StringData(symbols[0].FilePath) == unsafe.StringData(symbols[1].FilePath) {
+		t.Error("FilePath interned with interning disabled")
+	}
+	if g.MemoryStats().DuplicateStringBytes == 0 {
+		t.Error("expected duplicate string bytes without interning")
+	}
+}
+
+func TestGraph_MemoryStats(t *testing.T) {
+	t.Run("empty graph", func(t *testing.T) {
+		g := NewGraph("/test")
+		g.Freeze()
+		stats := g.MemoryStats()
+		if stats.NodeCount != 0 || stats.EdgeCount != 0 || stats.StringBytes != 0 {
+			t.Errorf("expected empty stats, got %+v", stats)
+		}
+	})
+
+	t.Run("categories sum to total", func(t *testing.T) {
+		symbols, edges := makeDecodedCorpus(200, 3)
+		stats := buildDecodedGraph(t, symbols, edges).MemoryStats()
+
+		if stats.NodeCount != 200 || stats.EdgeCount != 600 {
+			t.Errorf("counts = %d/%d, want 200/600", stats.NodeCount, stats.EdgeCount)
+		}
+		sum := stats.NodeBytes + stats.EdgeBytes + stats.StringBytes + stats.IndexBytes + stats.InternTableBytes
+		if sum != stats.TotalBytes {
+			t.Errorf("TotalBytes = %d, categories sum to %d", stats.TotalBytes, sum)
+		}
+		if stats.NodeBytes == 0 || stats.EdgeBytes == 0 || stats.IndexBytes == 0 {
+			t.Errorf("expected all categories populated, got %+v", stats)
+		}
+	})
+
+	t.Run("intern table reported while building", func(t *testing.T) {
+		g := NewGraph("/test")
+		if _, err := g.AddNode(testSymbol("A", ast.SymbolKindFunction, "a.go", 1)); err != nil {
+			t.Fatal(err)
+		}
+		if stats := g.MemoryStats(); stats.InternedStrings == 0 || stats.InternTableBytes == 0 {
+			t.Errorf("expected intern table stats while building, got %+v", stats)
+		}
+	})
+
+	t.Run("interning removes duplicated strings", func(t *testing.T) {
+		symbols, edges := makeDecodedCorpus(2000, 4)
+		plain := buildDecodedGraph(t, symbols, edges, WithStringInterning(false)).MemoryStats()
+
+		symbols, edges = makeDecodedCorpus(2000, 4)
+		interned := buildDecodedGraph(t, symbols, edges).MemoryStats()
+
+		stringReduction := 1 - float64(interned.StringBytes)/float64(plain.StringBytes)
+		totalReduction := 1 - float64(interned.TotalBytes)/float64(plain.TotalBytes)
+		t.Logf("strings: %d -> %d (-%.1f%%), total: %d -> %d (-%.1f%%)",
+			plain.StringBytes, interned.StringBytes, stringReduction*100,
+			plain.TotalBytes, interned.TotalBytes, totalReduction*100)
+
+		if stringReduction < 0.30 {
+			t.Errorf("expected >= 30%% less string data, got %.1f%%", stringReduction*100)
+		}
+		if interned.DuplicateStringBytes*10 > plain.DuplicateStringBytes {
+			t.Errorf("expected interning to remove >= 90%% of duplicate bytes: plain=%d interned=%d",
+				plain.DuplicateStringBytes, interned.DuplicateStringBytes)
+		}
+		if interned.TotalBytes >= plain.TotalBytes {
+			t.Errorf("expected a smaller total estimate: plain=%d interned=%d", plain.TotalBytes, interned.TotalBytes)
+		}
+	})
+}
+
+// buildRetainedGraph builds a synthetic corpus of symbolCount symbols with
+// Builder.Build and returns the live heap the graph retains
+// (runtime.MemStats HeapAlloc after GC, before the corpus is generated vs
+// after the parse results are dropped) along with its MemoryStats.
+func buildRetainedGraph(tb testing.TB, symbolCount int, interning bool) (int64, MemoryStats) {
+	tb.Helper()
+	cfg := DefaultSyntheticCorpusConfig()
+	cfg.Files = symbolCount / cfg.SymbolsPerFile
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	corpus, err := GenerateSyntheticCorpus(cfg)
+	if err != nil {
+		tb.Fatalf("GenerateSyntheticCorpus: %v", err)
+	}
+	result, err := NewBuilder(WithProjectRoot("/synthetic"), WithBuilderStringInterning(interning)).
+		Build(context.Background(), corpus.Results)
+	if err != nil {
+		tb.Fatalf("Build: %v", err)
+	}
+	g := result.Graph
+	corpus, result = nil, nil
+
+	runtime.GC()
+	var after runtime.MemStats
+	runtime.ReadMemStats(&after)
+	stats := g.MemoryStats()
+	runtime.KeepAlive(g)
+	return int64(after.HeapAlloc) - int64(before.HeapAlloc), stats
+}
+
+func TestGraph_StringInterning_SyntheticBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds two 100k-symbol graphs")
+	}
+	plainHeap, plain := buildRetainedGraph(t, 100_000, false)
+	internedHeap, interned := buildRetainedGraph(t, 100_000, true)
+
+	stringReduction := 1 - float64(interned.StringBytes)/float64(plain.StringBytes)
+	t.Logf("strings: %.1f MB -> %.1f MB (-%.1f%%), retained heap: %.1f MB -> %.1f MB (-%.1f%%)",
+		float64(plain.StringBytes)/(1<<20), float64(interned.StringBytes)/(1<<20), stringReduction*100,
+		float64(plainHeap)/(1<<20), float64(internedHeap)/(1<<20), (1-float64(internedHeap)/float64(plainHeap))*100)
+
+	if stringReduction < 0.30 {
+		t.Errorf("expected interning to hold >= 30%% less string data, got %.1f%%", stringReduction*100)
+	}
+	if interned.DuplicateStringBytes != 0 {
+		t.Errorf("expected no duplicate string bytes after interning, got %d", interned.DuplicateStringBytes)
+	}
+	if internedHeap >= plainHeap {
+		t.Errorf("expected interning to retain less heap: plain=%d interned=%d", plainHeap, internedHeap)
+	}
+}
+
+// BenchmarkGraph_Build_100kSymbols_Interning builds the synthetic 100k
+// symbol corpus with Builder.Build, with and without interning, and
+// reports the live heap each graph retains and its string data.
+func BenchmarkGraph_Build_100kSymbols_Interning(b *testing.B) {
+	silenceBenchLogs(b)
+	for _, interning := range []bool{false, true} {
+		b.Run(fmt.Sprintf("interning=%v", interning), func(b *testing.B) {
+			var retained int64
+			var stats MemoryStats
+			for i := 0; i < b.N; i++ {
+				retained, stats = buildRetainedGraph(b, 100_000, interning)
+			}
+			b.ReportMetric(float64(retained)/(1<<20), "heap-MB")
+			b.ReportMetric(float64(stats.StringBytes)/(1<<20), "string-MB")
+			b.ReportMetric(float64(stats.TotalBytes)/(1<<20), "est-MB")
+		})
+	}
+}
+
+func TestFilePathInRoot(t *testing.T) {
+	tests := []struct {
+		path, root string
+		want       bool
+	}{
+		{"shared-lib/pkg/util.go", "shared-lib", true},
+		{"shared-lib/pkg/util.go", "shared-lib/", true},
+		{"shared-lib", "shared-lib", true},
+		{"shared-lib-v2/util.go", "shared-lib", false},
+		{"service-a/main.go", "shared-lib", false},
+		{"service-a/main.go", "", true},
+	}
+	for _, tt := range tests {
+		if got := FilePathInRoot(tt.path, tt.root); got != tt.want {
+			t.Errorf("FilePathInRoot(%q, %q) = %v, want %v", tt.path, tt.root, got, tt.want)
+		}
+	}
+}
+
+func TestGraph_MemoryStatsByRoot(t *testing.T) {
+	g := NewGraph("/ws")
+	libFn := testSymbol("Helper", ast.SymbolKindFunction, "shared-lib/util/util.go", 1)
+	svcFn := testSymbol("Main", ast.SymbolKindFunction, "service-a/main.go", 1)
+	svcFn2 := testSymbol("Run", ast.SymbolKindFunction, "service-a/run.go", 1)
+	for _, sym := range []*ast.Symbol{libFn, svcFn, svcFn2} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.AddEdge(svcFn.ID, libFn.ID, EdgeTypeCalls, ast.Location{FilePath: "service-a/main.go", StartLine: 2}); err != nil {
+		t.Fatal(err)
+	}
+	g.Freeze()
+
+	stats := g.MemoryStatsByRoot([]string{"shared-lib", "service-a", "service-b"})
+	if len(stats) != 3 {
+		t.Fatalf("expected an entry per root, got %d", len(stats))
+	}
+	if s := stats["service-a"]; s.NodeCount != 2 || s.EdgeCount != 1 || s.TotalBytes == 0 {
+		t.Errorf("service-a stats = %+v", s)
+	}
+	if s := stats["shared-lib"]; s.NodeCount != 1 || s.EdgeCount != 0 || s.TotalBytes == 0 {
+		t.Errorf("shared-lib stats = %+v", s)
+	}
+	if s := stats["service-b"]; s.TotalBytes != 0 {
+		t.Errorf("service-b should be empty, got %+v", s)
+	}
+	if total := g.MemoryStats().TotalBytes; stats["service-a"].TotalBytes+stats["shared-lib"].TotalBytes > total {
+		t.Errorf("per-root totals exceed graph total %d", total)
+	}
+}
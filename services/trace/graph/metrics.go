@@ -19,6 +19,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Package-level tracer and meter for graph operations.
@@ -218,7 +220,7 @@ func startQuerySpan(ctx context.Context, queryType, symbolID string) (context.Co
 	return tracer.Start(ctx, "Graph."+queryType,
 		trace.WithAttributes(
 			attribute.String("graph.query_type", queryType),
-			attribute.String("graph.symbol_id", symbolID),
+			telemetry.SensitiveString("graph.symbol_id", symbolID),
 		),
 	)
 }
@@ -0,0 +1,180 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"log/slog"
+	"sort"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// RecordFileOwners populates a graph's FileOwners field from a CODEOWNERS
+// resolver.
+//
+// Description:
+//
+//	Resolves the owners of every file that has a node in the graph. Symbols
+//	are owned by the owners of their file; see OwnersOf. ownersOf is kept
+//	to resolve paths that are not in the graph. A nil ownersOf clears
+//	ownership.
+//
+// Inputs:
+//   - g: The graph to populate. Must not be nil.
+//   - ownersOf: Returns the owners of a relative file path, or nil.
+//
+// Thread Safety: Must be called before the graph is shared for reads.
+func RecordFileOwners(g *Graph, ownersOf func(filePath string) []string) {
+	if g == nil {
+		return
+	}
+	g.FileOwners = nil
+	g.ownersOf = ownersOf
+	if ownersOf == nil {
+		return
+	}
+
+	owned := make(map[string][]string)
+	for _, node := range g.nodes {
+		if node.Symbol == nil || node.Symbol.FilePath == "" {
+			continue
+		}
+		path := node.Symbol.FilePath
+		if _, done := owned[path]; done {
+			continue
+		}
+		owned[path] = ownersOf(path)
+	}
+	for path, owners := range owned {
+		if len(owners) == 0 {
+			delete(owned, path)
+		}
+	}
+	g.FileOwners = owned
+
+	slog.Debug("Recorded file owners",
+		slog.Int("owned_files", len(owned)),
+		telemetry.SensitiveLog("project_root", g.ProjectRoot),
+	)
+}
+
+// HasOwners reports whether ownership was recorded for the graph.
+func (g *Graph) HasOwners() bool {
+	return g != nil && g.ownersOf != nil
+}
+
+// OwnersOf returns the CODEOWNERS owners of a file path relative to the
+// project root. A symbol's owners are OwnersOf(symbol.FilePath).
+//
+// Outputs:
+//
+//	[]string - The owners, or nil if the file has none or the project has
+//	  no CODEOWNERS. The slice is shared; callers must not modify it.
+//
+// Thread Safety: Safe for concurrent use.
+func (g *Graph) OwnersOf(filePath string) []string {
+	if g == nil || g.ownersOf == nil {
+		return nil
+	}
+	if owners, ok := g.FileOwners[filePath]; ok {
+		return owners
+	}
+	return g.ownersOf(filePath)
+}
+
+// OwnerGroup is the set of files and symbols one owner owns.
+type OwnerGroup struct {
+	// Owner is the @user, @org/team or email owner.
+	Owner string `json:"owner"`
+
+	// Files are the owned files, sorted.
+	Files []string `json:"files"`
+
+	// SymbolIDs are the owned symbols, sorted. Empty when grouping files.
+	SymbolIDs []string `json:"symbol_ids,omitempty"`
+}
+
+// GroupByOwner groups files and symbols by their owners.
+//
+// Description:
+//
+//	Each file and symbol is listed under every one of its owners. Files
+//	and symbols without owners are returned in unowned. Groups are sorted
+//	by number of symbols, then files, descending, then by owner.
+//
+// Inputs:
+//   - files: File paths relative to the project root.
+//   - symbolFiles: Map of symbol ID to the symbol's file path. May be nil.
+//
+// Outputs:
+//   - []OwnerGroup: One group per owner. Empty, never nil.
+//   - []string: Unowned files, sorted, including files of unowned symbols.
+//
+// Thread Safety: Safe for concurrent use.
+func (g *Graph) GroupByOwner(files []string, symbolFiles map[string]string) ([]OwnerGroup, []string) {
+	byOwner := make(map[string]*OwnerGroup)
+	seenFile := make(map[string]map[string]bool)
+	unowned := make(map[string]bool)
+
+	addFile := func(path string) []string {
+		owners := g.OwnersOf(path)
+		if len(owners) == 0 {
+			unowned[path] = true
+			return nil
+		}
+		for _, owner := range owners {
+			group, ok := byOwner[owner]
+			if !ok {
+				group = &OwnerGroup{Owner: owner, Files: []string{}}
+				byOwner[owner] = group
+				seenFile[owner] = make(map[string]bool)
+			}
+			if !seenFile[owner][path] {
+				seenFile[owner][path] = true
+				group.Files = append(group.Files, path)
+			}
+		}
+		return owners
+	}
+
+	for _, path := range files {
+		addFile(path)
+	}
+	for id, path := range symbolFiles {
+		for _, owner := range addFile(path) {
+			byOwner[owner].SymbolIDs = append(byOwner[owner].SymbolIDs, id)
+		}
+	}
+
+	groups := make([]OwnerGroup, 0, len(byOwner))
+	for _, group := range byOwner {
+		sort.Strings(group.Files)
+		sort.Strings(group.SymbolIDs)
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if len(a.SymbolIDs) != len(b.SymbolIDs) {
+			return len(a.SymbolIDs) > len(b.SymbolIDs)
+		}
+		if len(a.Files) != len(b.Files) {
+			return len(a.Files) > len(b.Files)
+		}
+		return a.Owner < b.Owner
+	})
+
+	unownedFiles := make([]string, 0, len(unowned))
+	for path := range unowned {
+		unownedFiles = append(unownedFiles, path)
+	}
+	sort.Strings(unownedFiles)
+	return groups, unownedFiles
+}
@@ -21,6 +21,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Parallel BFS configuration constants.
@@ -71,7 +73,7 @@ var parallelTracer = otel.Tracer("graph.parallel")
 func (g *Graph) GetCallGraphParallel(ctx context.Context, symbolID string, opts ...QueryOption) (*TraversalResult, error) {
 	ctx, span := parallelTracer.Start(ctx, "graph.GetCallGraphParallel",
 		trace.WithAttributes(
-			attribute.String("symbol_id", symbolID),
+			telemetry.SensitiveString("symbol_id", symbolID),
 		),
 	)
 	defer span.End()
@@ -85,8 +87,8 @@ func (g *Graph) GetCallGraphParallel(ctx context.Context, symbolID string, opts
 	root, ok := g.nodes[symbolID]
 	if !ok {
 		err := fmt.Errorf("root node not found: %s", symbolID)
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 
@@ -157,7 +159,7 @@ func (g *Graph) GetCallGraphParallel(ctx context.Context, symbolID string, opts
 	span.SetStatus(codes.Ok, "")
 
 	slog.Debug("parallel BFS completed",
-		slog.String("symbol_id", symbolID),
+		telemetry.SensitiveLog("symbol_id", symbolID),
 		slog.Int("total_nodes", len(result.VisitedNodes)),
 		slog.Int("parallel_levels", parallelLevels),
 		slog.Int("sequential_levels", sequentialLevels),
@@ -191,7 +193,7 @@ func (g *Graph) GetCallGraphParallel(ctx context.Context, symbolID string, opts
 func (g *Graph) GetReverseCallGraphParallel(ctx context.Context, symbolID string, opts ...QueryOption) (*TraversalResult, error) {
 	ctx, span := parallelTracer.Start(ctx, "graph.GetReverseCallGraphParallel",
 		trace.WithAttributes(
-			attribute.String("symbol_id", symbolID),
+			telemetry.SensitiveString("symbol_id", symbolID),
 		),
 	)
 	defer span.End()
@@ -205,8 +207,8 @@ func (g *Graph) GetReverseCallGraphParallel(ctx context.Context, symbolID string
 	root, ok := g.nodes[symbolID]
 	if !ok {
 		err := fmt.Errorf("root node not found: %s", symbolID)
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return nil, err
 	}
 
@@ -277,7 +279,7 @@ func (g *Graph) GetReverseCallGraphParallel(ctx context.Context, symbolID string
 	span.SetStatus(codes.Ok, "")
 
 	slog.Debug("parallel reverse BFS completed",
-		slog.String("symbol_id", symbolID),
+		telemetry.SensitiveLog("symbol_id", symbolID),
 		slog.Int("total_nodes", len(result.VisitedNodes)),
 		slog.Int("parallel_levels", parallelLevels),
 		slog.Int("sequential_levels", sequentialLevels),
@@ -0,0 +1,178 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/stdlib"
+)
+
+// PlaceholderPolicy selects how the builder represents symbols outside
+// the project, such as imported packages and unresolved call targets.
+type PlaceholderPolicy string
+
+const (
+	// PlaceholderFull creates one placeholder node per external symbol,
+	// e.g. external:fmt:Println. The default.
+	PlaceholderFull PlaceholderPolicy = "full"
+
+	// PlaceholderCollapsed creates one placeholder node per external
+	// package, external:<pkg>:<pkg>, shared with the package's import
+	// placeholder. The member names used are accumulated in its
+	// Metadata.ExternalMembers. Symbols of an unknown package keep their
+	// own placeholder.
+	PlaceholderCollapsed PlaceholderPolicy = "collapsed"
+
+	// PlaceholderOmit creates no placeholder nodes. Edges to external
+	// symbols are dropped and counted in BuildStats.PlaceholderEdgesOmitted.
+	PlaceholderOmit PlaceholderPolicy = "omit"
+)
+
+// ErrUnknownPlaceholderPolicy indicates a policy name that is not one of
+// the PlaceholderPolicy constants.
+var ErrUnknownPlaceholderPolicy = errors.New("unknown placeholder policy")
+
+// ParsePlaceholderPolicy returns the policy with the given name. The
+// empty name is PlaceholderFull.
+func ParsePlaceholderPolicy(name string) (PlaceholderPolicy, error) {
+	switch p := PlaceholderPolicy(name); p {
+	case "":
+		return PlaceholderFull, nil
+	case PlaceholderFull, PlaceholderCollapsed, PlaceholderOmit:
+		return p, nil
+	}
+	return "", fmt.Errorf("%w: %q (valid: %s, %s, %s)", ErrUnknownPlaceholderPolicy,
+		name, PlaceholderFull, PlaceholderCollapsed, PlaceholderOmit)
+}
+
+// WithPlaceholderPolicy sets how external symbols are represented.
+// Default: PlaceholderFull.
+func WithPlaceholderPolicy(policy PlaceholderPolicy) BuilderOption {
+	return func(o *BuilderOptions) {
+		o.PlaceholderPolicy = policy
+	}
+}
+
+// errPlaceholderOmitted is returned by stateAddEdge for an edge to an
+// external symbol under PlaceholderOmit. stateAddEdgeError ignores it.
+var errPlaceholderOmitted = errors.New("placeholder omitted")
+
+// placeholderID returns the ID of the placeholder for name in pkg under
+// the policy, and the member name a collapsed placeholder records ("" if
+// none). Under PlaceholderOmit the ID is "".
+func placeholderID(policy PlaceholderPolicy, pkg, name string) (id, member string) {
+	switch {
+	case policy == PlaceholderOmit:
+		return "", ""
+	case pkg == "":
+		return "external::" + name, ""
+	case policy == PlaceholderCollapsed:
+		if name != pkg {
+			member = name
+		}
+		return "external:" + pkg + ":" + pkg, member
+	}
+	return "external:" + pkg + ":" + name, ""
+}
+
+// addExternalMember records member on a collapsed placeholder, keeping
+// Metadata.ExternalMembers sorted and unique. Reports whether it was new.
+func addExternalMember(sym *ast.Symbol, member string) bool {
+	if sym.Metadata == nil {
+		sym.Metadata = &ast.SymbolMetadata{}
+	}
+	i, found := slices.BinarySearch(sym.Metadata.ExternalMembers, member)
+	if found {
+		return false
+	}
+	sym.Metadata.ExternalMembers = slices.Insert(sym.Metadata.ExternalMembers, i, member)
+	return true
+}
+
+// clonePlaceholderSymbol returns a copy of a placeholder symbol that can
+// be modified without affecting sym.
+func clonePlaceholderSymbol(sym *ast.Symbol) *ast.Symbol {
+	clone := *sym
+	if sym.Metadata != nil {
+		meta := *sym.Metadata
+		meta.ExternalMembers = slices.Clone(meta.ExternalMembers)
+		clone.Metadata = &meta
+	}
+	return &clone
+}
+
+// enrichStdlibPlaceholders fills in the signature and doc one-liner of
+// placeholders for Go standard library symbols from the embedded index.
+//
+// Description:
+//
+//	A placeholder qualifies when its package is in the index and a Go
+//	symbol refers to it, so a Python "import os" is not mistaken for
+//	Go's os. Package placeholders (import placeholders and collapsed
+//	ones) get the package synopsis. Placeholders that already carry a
+//	signature are left alone.
+//
+// Inputs:
+//
+//	state - The build state. Its placeholders are updated in place.
+//	idx - The index. Nil uses stdlib.Default().
+//
+// Outputs:
+//
+//	int - The number of placeholders enriched.
+//
+// Thread Safety: Must be called single-threaded before the graph is frozen.
+func enrichStdlibPlaceholders(state *buildState, idx *stdlib.Index) int {
+	if len(state.placeholders) == 0 {
+		return 0
+	}
+	if idx == nil {
+		idx = stdlib.Default()
+	}
+	enriched := 0
+	for _, node := range state.placeholders {
+		sym := node.Symbol
+		if sym == nil || sym.Signature != "" || !referencedFromGo(state.graph, node) {
+			continue
+		}
+		pkg, ok := idx.Package(sym.Package)
+		if !ok {
+			continue
+		}
+		if sym.Name == sym.Package {
+			sym.Signature = "package " + sym.Package
+			sym.DocComment = pkg.Doc
+			enriched++
+			continue
+		}
+		if decl, ok := pkg.Symbols[sym.Name]; ok {
+			sym.Signature = decl.Signature
+			sym.DocComment = decl.Doc
+			sym.Exported = true
+			enriched++
+		}
+	}
+	return enriched
+}
+
+// referencedFromGo reports whether a Go symbol has an edge to node.
+func referencedFromGo(g *Graph, node *Node) bool {
+	for _, edge := range node.Incoming {
+		if from, ok := g.GetNode(edge.FromID); ok && from.Symbol != nil && from.Symbol.Language == "go" {
+			return true
+		}
+	}
+	return false
+}
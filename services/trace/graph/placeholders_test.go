@@ -0,0 +1,165 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// placeholderTestResults returns a Go file calling fmt and net/http, and
+// a Python file importing os.
+func placeholderTestResults() []*ast.ParseResult {
+	call := func(receiver, target string, line int) ast.CallSite {
+		return ast.CallSite{Target: target, Receiver: receiver, IsMethod: true,
+			Location: ast.Location{FilePath: "main.go", StartLine: line}}
+	}
+	mainFunc := testSymbolWithCalls("main", ast.SymbolKindFunction, "main.go", 5, []ast.CallSite{
+		call("fmt", "Println", 6),
+		call("fmt", "Sprintf", 7),
+		call("fmt", "Println", 8),
+		call("http", "ListenAndServe", 9),
+		{Target: "mystery", Location: ast.Location{FilePath: "main.go", StartLine: 10}},
+	})
+	goFile := testParseResult("main.go", []*ast.Symbol{mainFunc}, []ast.Import{{Path: "fmt"}, {Path: "net/http"}})
+
+	pyFunc := testSymbol("run", ast.SymbolKindFunction, "tool.py", 3)
+	pyFunc.Language = "python"
+	pyFile := testParseResult("tool.py", []*ast.Symbol{pyFunc}, []ast.Import{{Path: "os"}})
+	pyFile.Language = "python"
+	return []*ast.ParseResult{goFile, pyFile}
+}
+
+// placeholderIDs returns the sorted IDs of the graph's placeholder nodes.
+func placeholderIDs(g *Graph) []string {
+	var ids []string
+	for _, node := range g.Nodes() {
+		if isPlaceholderNode(node) {
+			ids = append(ids, node.ID)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestBuilder_PlaceholderPolicy(t *testing.T) {
+	build := func(t *testing.T, workers int, policy PlaceholderPolicy) *BuildResult {
+		t.Helper()
+		result, err := NewBuilder(WithWorkerCount(workers), WithPlaceholderPolicy(policy)).
+			Build(context.Background(), placeholderTestResults())
+		if err != nil {
+			t.Fatalf("Build: %v", err)
+		}
+		return result
+	}
+
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("full/workers=%d", workers), func(t *testing.T) {
+			result := build(t, workers, PlaceholderFull)
+			want := []string{"external::mystery", "external:fmt:Println", "external:fmt:Sprintf", "external:fmt:fmt",
+				"external:net/http:ListenAndServe", "external:net/http:net/http", "external:os:os"}
+			if got := placeholderIDs(result.Graph); !reflect.DeepEqual(got, want) {
+				t.Errorf("placeholders = %v, want %v", got, want)
+			}
+			if result.Stats.PlaceholderNodes != len(want) || result.Stats.PlaceholderEdgesOmitted != 0 {
+				t.Errorf("stats = %+v", result.Stats)
+			}
+			if result.Graph.PlaceholderPolicy != PlaceholderFull {
+				t.Errorf("graph policy = %q", result.Graph.PlaceholderPolicy)
+			}
+		})
+
+		t.Run(fmt.Sprintf("collapsed/workers=%d", workers), func(t *testing.T) {
+			result := build(t, workers, PlaceholderCollapsed)
+			want := []string{"external::mystery", "external:fmt:fmt", "external:net/http:net/http", "external:os:os"}
+			if got := placeholderIDs(result.Graph); !reflect.DeepEqual(got, want) {
+				t.Errorf("placeholders = %v, want %v", got, want)
+			}
+			fmtNode, _ := result.Graph.GetNode("external:fmt:fmt")
+			if fmtNode.Symbol.Name != "fmt" || fmtNode.Symbol.Metadata == nil ||
+				!reflect.DeepEqual(fmtNode.Symbol.Metadata.ExternalMembers, []string{"Println", "Sprintf"}) {
+				t.Errorf("fmt placeholder = %+v", fmtNode.Symbol)
+			}
+			if result.Stats.PlaceholderNodes != 4 || result.Stats.CollapsedPlaceholderMembers != 3 {
+				t.Errorf("stats = %+v", result.Stats)
+			}
+		})
+
+		t.Run(fmt.Sprintf("omit/workers=%d", workers), func(t *testing.T) {
+			result := build(t, workers, PlaceholderOmit)
+			if got := placeholderIDs(result.Graph); len(got) != 0 {
+				t.Errorf("placeholders = %v, want none", got)
+			}
+			// Three imports and five unresolved calls.
+			if result.Stats.PlaceholderEdgesOmitted != 8 || result.Stats.CallEdgesUnresolved != 5 {
+				t.Errorf("stats = %+v", result.Stats)
+			}
+			if len(result.EdgeErrors) != 0 {
+				t.Errorf("omitted edges reported as errors: %v", result.EdgeErrors)
+			}
+		})
+	}
+}
+
+func TestBuilder_StdlibPlaceholderEnrichment(t *testing.T) {
+	result, err := NewBuilder().Build(context.Background(), placeholderTestResults())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	g := result.Graph
+
+	tests := []struct {
+		id, signature, doc string
+	}{
+		{"external:fmt:Println", "func Println(a ...any) (n int, err error)", "Println formats using the default formats"},
+		{"external:net/http:ListenAndServe", "func ListenAndServe(addr string, handler Handler) error", "ListenAndServe listens on the TCP network address"},
+		{"external:net/http:net/http", "package net/http", "Package http provides HTTP client and server implementations."},
+	}
+	for _, tt := range tests {
+		node, ok := g.GetNode(tt.id)
+		if !ok {
+			t.Fatalf("missing %s", tt.id)
+		}
+		if node.Symbol.Signature != tt.signature || len(node.Symbol.DocComment) < len(tt.doc) ||
+			node.Symbol.DocComment[:len(tt.doc)] != tt.doc {
+			t.Errorf("%s = %q / %q", tt.id, node.Symbol.Signature, node.Symbol.DocComment)
+		}
+		if node.Symbol.Kind != ast.SymbolKindExternal {
+			t.Errorf("%s kind = %v, want external", tt.id, node.Symbol.Kind)
+		}
+	}
+
+	// Python's os is not Go's os.
+	if node, _ := g.GetNode("external:os:os"); node.Symbol.Signature != "" || node.Symbol.DocComment != "" {
+		t.Errorf("python import enriched: %+v", node.Symbol)
+	}
+	if result.Stats.StdlibPlaceholdersEnriched != 5 {
+		t.Errorf("enriched = %d, want 5", result.Stats.StdlibPlaceholdersEnriched)
+	}
+}
+
+func TestParsePlaceholderPolicy(t *testing.T) {
+	for name, want := range map[string]PlaceholderPolicy{"": PlaceholderFull, "full": PlaceholderFull,
+		"collapsed": PlaceholderCollapsed, "omit": PlaceholderOmit} {
+		if got, err := ParsePlaceholderPolicy(name); err != nil || got != want {
+			t.Errorf("ParsePlaceholderPolicy(%q) = %q, %v", name, got, err)
+		}
+	}
+	if _, err := ParsePlaceholderPolicy("none"); !errors.Is(err, ErrUnknownPlaceholderPolicy) {
+		t.Errorf("unknown policy error = %v", err)
+	}
+}
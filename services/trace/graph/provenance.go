@@ -0,0 +1,458 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EdgePass identifies the builder pass that created an edge.
+//
+// Passes have stable kebab-case names (see String) used by the API, the
+// export formats and WithDisabledPasses. Call resolution has one pass per
+// strategy of resolveCallTarget, so a bad CALLS edge can be traced to the
+// strategy that produced it.
+type EdgePass uint8
+
+const (
+	// PassUnknown marks edges whose pass was not recorded: edges added
+	// with AddEdge outside the builder, or loaded from a graph saved before
+	// provenance existed.
+	PassUnknown EdgePass = iota
+
+	// PassImport is the IMPORTS edge from a file's package symbol to an
+	// imported package.
+	PassImport
+
+	// PassReceiver is the RECEIVES edge from a method to its receiver type.
+	PassReceiver
+
+	// PassReturnType is the RETURNS edge from a function to its return type.
+	PassReturnType
+
+	// PassDeclaredImplements is an IMPLEMENTS edge the source declares
+	// (implements clause, base class).
+	PassDeclaredImplements
+
+	// PassEmbeds is an EMBEDS edge from a struct or class to an embedded or
+	// extended type.
+	PassEmbeds
+
+	// PassMixin is an EMBEDS edge from a class to a PHP trait or Ruby module.
+	PassMixin
+
+	// PassInterfaceEmbeds is an EMBEDS edge between interfaces.
+	PassInterfaceEmbeds
+
+	// PassDecoratorArg is a REFERENCES edge from a decorated symbol to a
+	// decorator argument.
+	PassDecoratorArg
+
+	// PassTypeArg is a REFERENCES edge to a generic type argument.
+	PassTypeArg
+
+	// PassTypeNarrowing is a REFERENCES edge to a type in an instanceof check.
+	PassTypeNarrowing
+
+	// PassTypeRef is a REFERENCES edge to a type in a parameter or return
+	// annotation.
+	PassTypeRef
+
+	// PassCallName is a CALLS edge resolved by bare name (strategy 1).
+	PassCallName
+
+	// PassImportAwareResolution is a CALLS edge resolved through the
+	// calling file's imports (strategy 1, import map).
+	PassImportAwareResolution
+
+	// PassCallQualified is a CALLS edge resolved from a pkg.Func target
+	// (strategy 2).
+	PassCallQualified
+
+	// PassCallSuper is a CALLS edge from super().m() to a parent class
+	// method (strategy 3-super).
+	PassCallSuper
+
+	// PassCallThisSelf is a CALLS edge from this.m() or self.m() to the
+	// caller's own class (strategy 3a).
+	PassCallThisSelf
+
+	// PassCallModuleAlias is a CALLS edge through a Python module alias
+	// (strategy 3a1).
+	PassCallModuleAlias
+
+	// PassCallPackageImport is a CALLS edge through an imported Go package
+	// name (strategy 3a2).
+	PassCallPackageImport
+
+	// PassReceiverMatch is a CALLS edge matched on the receiver variable's
+	// name (strategies 3b and 3b2).
+	PassReceiverMatch
+
+	// PassCallMethodFallback is a CALLS edge to a method or property picked
+	// by name alone (strategy 3c).
+	PassCallMethodFallback
+
+	// PassCallVariableFallback is a CALLS edge to a variable picked by name
+	// alone (strategy 3d).
+	PassCallVariableFallback
+
+	// PassCallPlaceholder is a CALLS edge to a placeholder for an
+	// unresolved target.
+	PassCallPlaceholder
+
+	// PassCallbackRef is a REFERENCES edge to a function passed as a call
+	// argument.
+	PassCallbackRef
+
+	// PassMethodSet is an IMPLEMENTS edge found by method-set matching.
+	PassMethodSet
+
+	// PassPromotedMethods is an IMPLEMENTS edge found by method-set
+	// matching that needed methods promoted from embedded types.
+	PassPromotedMethods
+
+	// PassNamedImport is a REFERENCES edge from a Python "from X import Y".
+	PassNamedImport
+
+	// PassCommonJSAlias is a REFERENCES edge from a require() alias.
+	PassCommonJSAlias
+
+	// PassDynamicImport is a REFERENCES edge from a dynamic import().
+	PassDynamicImport
+
+	// PassSQLRef is a REFERENCES edge to a SQL table or column.
+	PassSQLRef
+
+	// PassProtoService links protobuf services, rpcs and messages to their
+	// Go implementations.
+	PassProtoService
+
+	// PassBuildVariant is a VARIANT_OF edge between build variants.
+	PassBuildVariant
+
+	// PassLSP marks an edge whose placeholder target was replaced by an LSP
+	// definition lookup. Detail names the pass that created the edge.
+	PassLSP
+
+	// PassCompaction marks an edge whose placeholder target was replaced by
+	// graph compaction. Detail names the pass that created the edge.
+	PassCompaction
+
+	// PassFieldAccess is a REFERENCES edge from a Go function to a struct
+	// field it reads or writes. Detail is "read", "write" or "read-write".
+	PassFieldAccess
+
+	// PassCallInferredType is a CALLS edge from a Go method call whose
+	// receiver variable's type was declared or inferred in the caller
+	// (ast.SymbolMetadata.LocalTypes).
+	PassCallInferredType
+
+	// PassTemplateRender is a REFERENCES edge from a Go function to the
+	// template files it parses or executes by literal name.
+	PassTemplateRender
+
+	// PassTemplateInclude is a REFERENCES edge from a Go template file to
+	// the template file defining a {{template "name"}} it includes.
+	PassTemplateInclude
+
+	// PassCSSSelector is a REFERENCES edge from an HTML element, or an HTML
+	// file for elements without an id, to the CSS class and id selectors
+	// that target it by exact name.
+	PassCSSSelector
+
+	// PassDOMSelector is a REFERENCES edge from a JavaScript or TypeScript
+	// function to the HTML elements a literal querySelector,
+	// getElementById or getElementsByClassName selector matches.
+	PassDOMSelector
+
+	// PassCallChain is a CALLS edge to a hop of a method call chain
+	// (a.B().C()), resolved through the return type of the hop before it.
+	PassCallChain
+
+	// numEdgePasses is the number of defined passes.
+	numEdgePasses
+)
+
+// edgePassNames are the stable names of the passes, indexed by EdgePass.
+var edgePassNames = [numEdgePasses]string{
+	PassUnknown:               "unknown",
+	PassImport:                "import",
+	PassReceiver:              "receiver",
+	PassReturnType:            "return-type",
+	PassDeclaredImplements:    "declared-implements",
+	PassEmbeds:                "embeds",
+	PassMixin:                 "mixin",
+	PassInterfaceEmbeds:       "interface-embeds",
+	PassDecoratorArg:          "decorator-arg",
+	PassTypeArg:               "type-arg",
+	PassTypeNarrowing:         "type-narrowing",
+	PassTypeRef:               "type-ref",
+	PassCallName:              "call-name",
+	PassImportAwareResolution: "import-aware-resolution",
+	PassCallQualified:         "call-qualified",
+	PassCallSuper:             "call-super",
+	PassCallThisSelf:          "call-this-self",
+	PassCallModuleAlias:       "call-module-alias",
+	PassCallPackageImport:     "call-package-import",
+	PassReceiverMatch:         "receiver-match",
+	PassCallMethodFallback:    "call-method-fallback",
+	PassCallVariableFallback:  "call-variable-fallback",
+	PassCallPlaceholder:       "call-placeholder",
+	PassCallbackRef:           "callback-ref",
+	PassMethodSet:             "method-set",
+	PassPromotedMethods:       "promoted-methods",
+	PassNamedImport:           "named-import",
+	PassCommonJSAlias:         "commonjs-alias",
+	PassDynamicImport:         "dynamic-import",
+	PassSQLRef:                "sql-ref",
+	PassProtoService:          "proto-service",
+	PassBuildVariant:          "build-variant",
+	PassLSP:                   "lsp",
+	PassCompaction:            "compaction",
+	PassFieldAccess:           "field-access",
+	PassCallInferredType:      "call-inferred-type",
+	PassTemplateRender:        "template-render",
+	PassTemplateInclude:       "template-include",
+	PassCSSSelector:           "css-selector",
+	PassDOMSelector:           "dom-selector",
+	PassCallChain:             "call-chain",
+}
+
+// String returns the pass's stable name.
+func (p EdgePass) String() string {
+	if p < numEdgePasses {
+		return edgePassNames[p]
+	}
+	return fmt.Sprintf("pass(%d)", uint8(p))
+}
+
+// MarshalText encodes the pass as its name.
+func (p EdgePass) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a pass name. Unknown names decode to PassUnknown
+// so graphs written by newer versions still load.
+func (p *EdgePass) UnmarshalText(text []byte) error {
+	parsed, ok := ParseEdgePass(string(text))
+	if !ok {
+		parsed = PassUnknown
+	}
+	*p = parsed
+	return nil
+}
+
+// ParseEdgePass returns the pass with the given name.
+//
+// Outputs:
+//
+//	EdgePass - The pass.
+//	bool - False if no pass has that name.
+func ParseEdgePass(name string) (EdgePass, bool) {
+	name = strings.TrimSpace(strings.ToLower(name))
+	for i, n := range edgePassNames {
+		if n == name {
+			return EdgePass(i), true
+		}
+	}
+	return PassUnknown, false
+}
+
+// ParseEdgePasses parses pass names, as accepted by WithDisabledPasses.
+//
+// Outputs:
+//
+//	[]EdgePass - The passes, in input order.
+//	error - Non-nil listing every unknown name and the valid ones.
+func ParseEdgePasses(names []string) ([]EdgePass, error) {
+	passes := make([]EdgePass, 0, len(names))
+	var unknown []string
+	for _, name := range names {
+		p, ok := ParseEdgePass(name)
+		if !ok || p == PassUnknown {
+			unknown = append(unknown, name)
+			continue
+		}
+		passes = append(passes, p)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("%w: %s (valid: %s)", ErrUnknownEdgePass,
+			strings.Join(unknown, ", "), strings.Join(EdgePassNames(), ", "))
+	}
+	return passes, nil
+}
+
+// EdgePassNames returns the names of all passes except "unknown", sorted.
+func EdgePassNames() []string {
+	names := make([]string, 0, numEdgePasses-1)
+	for i := PassUnknown + 1; i < numEdgePasses; i++ {
+		names = append(names, edgePassNames[i])
+	}
+	sort.Strings(names)
+	return names
+}
+
+// EdgeConfidence is how sure the resolver was of an edge's target.
+// Higher values are more certain.
+type EdgeConfidence uint8
+
+const (
+	// ConfidenceUnknown is the confidence of edges without provenance.
+	ConfidenceUnknown EdgeConfidence = iota
+
+	// ConfidenceUnresolved means the target is a placeholder standing in
+	// for a symbol that was not found.
+	ConfidenceUnresolved
+
+	// ConfidenceLow means the target was picked by name alone among
+	// several candidates, or is one of several candidates all linked.
+	ConfidenceLow
+
+	// ConfidenceMedium means the target was picked among candidates with
+	// supporting evidence: an import, the receiver's name, a matching
+	// method set.
+	ConfidenceMedium
+
+	// ConfidenceHigh means the target was the only candidate, or the
+	// source names it directly.
+	ConfidenceHigh
+)
+
+// edgeConfidenceNames are the names of the levels, indexed by
+// EdgeConfidence.
+var edgeConfidenceNames = [...]string{
+	ConfidenceUnknown:    "unknown",
+	ConfidenceUnresolved: "unresolved",
+	ConfidenceLow:        "low",
+	ConfidenceMedium:     "medium",
+	ConfidenceHigh:       "high",
+}
+
+// String returns the level's name.
+func (c EdgeConfidence) String() string {
+	if int(c) < len(edgeConfidenceNames) {
+		return edgeConfidenceNames[c]
+	}
+	return fmt.Sprintf("confidence(%d)", uint8(c))
+}
+
+// MarshalText encodes the level as its name.
+func (c EdgeConfidence) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a level name. Unknown names decode to
+// ConfidenceUnknown.
+func (c *EdgeConfidence) UnmarshalText(text []byte) error {
+	*c = ConfidenceUnknown
+	for i, n := range edgeConfidenceNames {
+		if n == string(text) {
+			*c = EdgeConfidence(i)
+			break
+		}
+	}
+	return nil
+}
+
+// EdgeProvenance records how an edge was created.
+//
+// Description:
+//
+//	Two bytes plus an optional short detail, which is interned by the
+//	graph: the call strategy ("3b2"), the resolution route ("re-export"),
+//	or for PassLSP and PassCompaction the pass that created the edge.
+//
+// Thread Safety: Value type; immutable once the edge is added.
+type EdgeProvenance struct {
+	// Pass is the builder pass that created the edge.
+	Pass EdgePass `json:"pass"`
+
+	// Confidence is how sure the resolver was of the target.
+	Confidence EdgeConfidence `json:"confidence"`
+
+	// Detail is an optional short qualifier of Pass.
+	Detail string `json:"detail,omitempty"`
+}
+
+// DetailAmbiguousImport is the Detail of edges whose target was resolved
+// through an import whose module path matched several files. The edge
+// points at one of them; BuildResult.AmbiguousImports lists the others.
+const DetailAmbiguousImport = "ambiguous_import"
+
+// IsAmbiguousImport reports whether the edge came from an ambiguous import
+// resolution.
+func (p EdgeProvenance) IsAmbiguousImport() bool {
+	return p.Detail == DetailAmbiguousImport
+}
+
+// IsZero reports whether no provenance was recorded.
+func (p EdgeProvenance) IsZero() bool {
+	return p.Pass == PassUnknown && p.Confidence == ConfidenceUnknown && p.Detail == ""
+}
+
+// String formats the provenance as "pass/confidence[/detail]".
+func (p EdgeProvenance) String() string {
+	if p.Detail == "" {
+		return p.Pass.String() + "/" + p.Confidence.String()
+	}
+	return p.Pass.String() + "/" + p.Confidence.String() + "/" + p.Detail
+}
+
+// nameMatchProvenance returns the provenance of an edge created by pass
+// to each of targets, the symbols its name resolved to. A lone placeholder
+// target means the name did not resolve.
+func nameMatchProvenance(pass EdgePass, targets []string) EdgeProvenance {
+	switch {
+	case len(targets) == 1 && strings.HasPrefix(targets[0], "external:"):
+		return EdgeProvenance{Pass: pass, Confidence: ConfidenceUnresolved}
+	case len(targets) == 1:
+		return EdgeProvenance{Pass: pass, Confidence: ConfidenceHigh}
+	default:
+		return EdgeProvenance{Pass: pass, Confidence: ConfidenceLow}
+	}
+}
+
+// retargetProvenance returns the provenance of an edge whose placeholder
+// target pass replaced with a real symbol, with the given confidence. The
+// pass that created the edge is kept as the detail.
+func retargetProvenance(pass EdgePass, confidence EdgeConfidence, old EdgeProvenance) EdgeProvenance {
+	detail := ""
+	if old.Pass != PassUnknown {
+		detail = old.Pass.String()
+	}
+	return EdgeProvenance{Pass: pass, Confidence: confidence, Detail: detail}
+}
+
+// ambiguousImportProvenance returns the provenance of an edge created by
+// pass to a target picked among several files matching an import.
+func ambiguousImportProvenance(pass EdgePass) EdgeProvenance {
+	return EdgeProvenance{Pass: pass, Confidence: ConfidenceLow, Detail: DetailAmbiguousImport}
+}
+
+// HasAmbiguousImportEdge reports whether an edge from fromID to toID was
+// resolved through an ambiguous import.
+//
+// Thread Safety: Safe for concurrent use on a frozen graph.
+func (g *Graph) HasAmbiguousImportEdge(fromID, toID string) bool {
+	node, ok := g.GetNode(fromID)
+	if !ok {
+		return false
+	}
+	for _, edge := range node.Outgoing {
+		if edge.ToID == toID && edge.Provenance.IsAmbiguousImport() {
+			return true
+		}
+	}
+	return false
+}
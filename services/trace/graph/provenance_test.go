@@ -0,0 +1,221 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+func TestEdgePass_Names(t *testing.T) {
+	for p := PassUnknown + 1; p < numEdgePasses; p++ {
+		got, ok := ParseEdgePass(p.String())
+		if !ok || got != p {
+			t.Errorf("ParseEdgePass(%q) = %v, %v", p.String(), got, ok)
+		}
+	}
+
+	var p EdgePass
+	if err := p.UnmarshalText([]byte("from-the-future")); err != nil || p != PassUnknown {
+		t.Errorf("unknown name decoded to %v, %v", p, err)
+	}
+
+	if _, err := ParseEdgePasses([]string{"call-name", "bogus"}); !errors.Is(err, ErrUnknownEdgePass) {
+		t.Errorf("ParseEdgePasses error = %v, want ErrUnknownEdgePass", err)
+	}
+	if _, err := ParseEdgePasses([]string{"unknown"}); !errors.Is(err, ErrUnknownEdgePass) {
+		t.Errorf("\"unknown\" accepted as a pass to disable")
+	}
+}
+
+func TestEdgeProvenance_JSON(t *testing.T) {
+	data, err := json.Marshal(EdgeProvenance{Pass: PassReceiverMatch, Confidence: ConfidenceMedium, Detail: "3b2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"pass":"receiver-match","confidence":"medium","detail":"3b2"}` {
+		t.Errorf("json = %s", data)
+	}
+	var back EdgeProvenance
+	if err := json.Unmarshal(data, &back); err != nil || back.Pass != PassReceiverMatch || back.Confidence != ConfidenceMedium {
+		t.Errorf("round trip = %+v, %v", back, err)
+	}
+}
+
+// provenanceFixture has Caller call Callee (unique) and ExternalFunc
+// (unresolved), and Engine implement IRouter only through the methods
+// it gets by embedding RouterGroup.
+func provenanceFixture() []*ast.ParseResult {
+	caller := testSymbolWithCalls("Caller", ast.SymbolKindFunction, "main.go", 5, []ast.CallSite{
+		{Target: "Callee", Location: ast.Location{FilePath: "main.go", StartLine: 6}},
+		{Target: "ExternalFunc", Location: ast.Location{FilePath: "main.go", StartLine: 7}},
+	})
+	callee := testSymbol("Callee", ast.SymbolKindFunction, "main.go", 20)
+
+	iface := testSymbol("IRouter", ast.SymbolKindInterface, "router.go", 1)
+	iface.Metadata = &ast.SymbolMetadata{Methods: []ast.MethodSignature{{Name: "GET"}, {Name: "Group"}}}
+	group := testSymbol("RouterGroup", ast.SymbolKindStruct, "router.go", 20)
+	group.Metadata = &ast.SymbolMetadata{Methods: []ast.MethodSignature{{Name: "GET"}, {Name: "Group"}}}
+	engine := testSymbol("Engine", ast.SymbolKindStruct, "engine.go", 1)
+	engine.Metadata = &ast.SymbolMetadata{Extends: "RouterGroup", Methods: []ast.MethodSignature{{Name: "Run"}}}
+
+	return []*ast.ParseResult{
+		testParseResult("main.go", []*ast.Symbol{caller, callee}, nil),
+		testParseResult("router.go", []*ast.Symbol{iface, group}, nil),
+		testParseResult("engine.go", []*ast.Symbol{engine}, nil),
+	}
+}
+
+// outgoingProvenance returns the provenance of from's edges of type et,
+// keyed by target ID.
+func outgoingProvenance(t *testing.T, g *Graph, from string, et EdgeType) map[string]EdgeProvenance {
+	t.Helper()
+	node, ok := g.GetNode(from)
+	if !ok {
+		t.Fatalf("node %s not found", from)
+	}
+	provs := make(map[string]EdgeProvenance)
+	for _, e := range node.Outgoing {
+		if e.Type == et {
+			provs[e.ToID] = e.Provenance
+		}
+	}
+	return provs
+}
+
+func TestBuilder_EdgeProvenance(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			result, err := NewBuilder(WithWorkerCount(workers)).Build(context.Background(), provenanceFixture())
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			g := result.Graph
+
+			calls := outgoingProvenance(t, g, ast.GenerateID("main.go", 5, "Caller"), EdgeTypeCalls)
+			want := EdgeProvenance{Pass: PassCallName, Confidence: ConfidenceHigh}
+			if got := calls[ast.GenerateID("main.go", 20, "Callee")]; got != want {
+				t.Errorf("Callee edge provenance = %v, want %v", got, want)
+			}
+			want = EdgeProvenance{Pass: PassCallPlaceholder, Confidence: ConfidenceUnresolved}
+			if got := calls["external::ExternalFunc"]; got != want {
+				t.Errorf("placeholder edge provenance = %v, want %v (edges %v)", got, want, calls)
+			}
+
+			ifaceID := ast.GenerateID("router.go", 1, "IRouter")
+			impl := outgoingProvenance(t, g, ast.GenerateID("router.go", 20, "RouterGroup"), EdgeTypeImplements)
+			if got := impl[ifaceID].Pass; got != PassMethodSet {
+				t.Errorf("RouterGroup implements pass = %v, want method-set", got)
+			}
+			impl = outgoingProvenance(t, g, ast.GenerateID("engine.go", 1, "Engine"), EdgeTypeImplements)
+			if got := impl[ifaceID].Pass; got != PassPromotedMethods {
+				t.Errorf("Engine implements pass = %v, want promoted-methods", got)
+			}
+		})
+	}
+}
+
+func TestBuilder_WithDisabledPasses(t *testing.T) {
+	for _, workers := range []int{1, 4} {
+		t.Run(fmt.Sprintf("workers=%d", workers), func(t *testing.T) {
+			builder := NewBuilder(WithWorkerCount(workers), WithDisabledPasses(PassCallName, PassPromotedMethods))
+			result, err := builder.Build(context.Background(), provenanceFixture())
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			g := result.Graph
+
+			calls := outgoingProvenance(t, g, ast.GenerateID("main.go", 5, "Caller"), EdgeTypeCalls)
+			if _, ok := calls[ast.GenerateID("main.go", 20, "Callee")]; ok {
+				t.Error("edge of disabled pass call-name was added")
+			}
+			if _, ok := calls["external::ExternalFunc"]; !ok {
+				t.Error("edge of an enabled pass is missing")
+			}
+			if impl := outgoingProvenance(t, g, ast.GenerateID("engine.go", 1, "Engine"), EdgeTypeImplements); len(impl) != 0 {
+				t.Errorf("promoted-methods edges added: %v", impl)
+			}
+			if result.Stats.EdgesDisabled != 2 {
+				t.Errorf("EdgesDisabled = %d, want 2", result.Stats.EdgesDisabled)
+			}
+			if len(result.EdgeErrors) != 0 {
+				t.Errorf("disabled edges reported as errors: %v", result.EdgeErrors)
+			}
+		})
+	}
+}
+
+func TestEdgeProvenance_Persistence(t *testing.T) {
+	result, err := NewBuilder().Build(context.Background(), provenanceFixture())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	g := result.Graph
+	callerID := ast.GenerateID("main.go", 5, "Caller")
+	calleeID := ast.GenerateID("main.go", 20, "Callee")
+	want := EdgeProvenance{Pass: PassCallName, Confidence: ConfidenceHigh}
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(g.ToSerializable())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sg SerializableGraph
+		if err := json.Unmarshal(data, &sg); err != nil {
+			t.Fatal(err)
+		}
+		back, err := FromSerializable(&sg)
+		if err != nil {
+			t.Fatalf("FromSerializable: %v", err)
+		}
+		if got := outgoingProvenance(t, back, callerID, EdgeTypeCalls)[calleeID]; got != want {
+			t.Errorf("provenance = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("bbolt codec", func(t *testing.T) {
+		node, _ := g.GetNode(callerID)
+		data, err := encodeEdges(node.Outgoing)
+		if err != nil {
+			t.Fatal(err)
+		}
+		edges, err := decodeEdges(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, e := range edges {
+			if e.Provenance != node.Outgoing[i].Provenance {
+				t.Errorf("edge %d provenance = %v, want %v", i, e.Provenance, node.Outgoing[i].Provenance)
+			}
+		}
+	})
+
+	t.Run("mapped", func(t *testing.T) {
+		m := openMappedFixture(t, g)
+		found := false
+		for e := range m.AllEdges() {
+			if e.FromID == callerID && e.ToID == calleeID {
+				found = true
+				if e.Type != EdgeTypeCalls || e.Provenance != want {
+					t.Errorf("mapped edge = %v %v, want calls %v", e.Type, e.Provenance, want)
+				}
+			}
+		}
+		if !found {
+			t.Error("mapped edge not found")
+		}
+	})
+}
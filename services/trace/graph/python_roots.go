@@ -0,0 +1,424 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// PythonSourceRoot is a directory whose Python files are importable under
+// a package prefix, as declared by a pyproject.toml or setup.cfg.
+//
+// Description:
+//
+//	With Dir "libs/core/src" and Package "", the file
+//	libs/core/src/acme/models.py is the module acme.models. With Dir
+//	"billing" and Package "acme.billing" (setuptools package_dir
+//	{"acme.billing": "billing"}), billing/models.py is
+//	acme.billing.models.
+type PythonSourceRoot struct {
+	// Dir is the project-relative directory, "" for the project root.
+	Dir string `json:"dir"`
+
+	// Package is the dotted package of the files directly in Dir, "" for
+	// top-level modules.
+	Package string `json:"package,omitempty"`
+}
+
+// PythonPackageConfigFiles are the file names ParsePythonPackageConfig
+// reads.
+var PythonPackageConfigFiles = []string{"pyproject.toml", "setup.cfg"}
+
+var (
+	// tomlStringRe matches a basic or literal TOML string.
+	tomlStringRe = regexp.MustCompile(`"([^"]*)"|'([^']*)'`)
+
+	// tomlPairRe matches `key = "value"` inside an inline table, with a
+	// bare or quoted key.
+	tomlPairRe = regexp.MustCompile(`(?:"([^"]*)"|'([^']*)'|([A-Za-z0-9_.\-]+))\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+	// tomlInlineTableRe matches one inline table without nested tables.
+	tomlInlineTableRe = regexp.MustCompile(`\{[^{}]*\}`)
+)
+
+// ParsePythonPackageConfig returns the source roots a packaging config
+// declares.
+//
+// Description:
+//
+//	Reads the settings that move packages away from the project layout:
+//
+//	  pyproject.toml  [tool.setuptools] package-dir, [tool.setuptools.package-dir],
+//	                  [tool.setuptools.packages.find] where,
+//	                  [tool.poetry] packages (from),
+//	                  [tool.hatch.build.targets.wheel] packages
+//	  setup.cfg       [options] package_dir, [options.packages.find] where
+//
+//	Directories are resolved against the config file's directory. The
+//	parser is line based and does not handle every TOML construct; keys
+//	it does not understand are ignored.
+//
+// Inputs:
+//
+//	configPath - Project-relative path of the config file; its base name
+//	  selects the format.
+//	data - The file content.
+//
+// Outputs:
+//
+//	[]PythonSourceRoot - The declared roots, sorted by Dir. Nil for other
+//	  file names or when nothing is declared.
+//
+// Thread Safety: Safe for concurrent use (pure function).
+func ParsePythonPackageConfig(configPath string, data []byte) []PythonSourceRoot {
+	baseDir := path.Dir(configPath)
+	if baseDir == "." || baseDir == "/" {
+		baseDir = ""
+	}
+	var raw []PythonSourceRoot
+	switch path.Base(configPath) {
+	case "pyproject.toml":
+		raw = parsePyprojectRoots(string(data))
+	case "setup.cfg":
+		raw = parseSetupCfgRoots(string(data))
+	default:
+		return nil
+	}
+
+	seen := make(map[PythonSourceRoot]bool)
+	var roots []PythonSourceRoot
+	for _, r := range raw {
+		dir := path.Clean(path.Join(baseDir, r.Dir))
+		if dir == "." {
+			dir = ""
+		}
+		if strings.HasPrefix(dir, "..") || strings.HasPrefix(dir, "/") {
+			continue
+		}
+		root := PythonSourceRoot{Dir: dir, Package: strings.Trim(r.Package, ".")}
+		if root == (PythonSourceRoot{}) || seen[root] {
+			continue
+		}
+		seen[root] = true
+		roots = append(roots, root)
+	}
+	sortPythonSourceRoots(roots)
+	return roots
+}
+
+// sortPythonSourceRoots orders roots by Dir, then Package.
+func sortPythonSourceRoots(roots []PythonSourceRoot) {
+	sort.Slice(roots, func(i, j int) bool {
+		if roots[i].Dir != roots[j].Dir {
+			return roots[i].Dir < roots[j].Dir
+		}
+		return roots[i].Package < roots[j].Package
+	})
+}
+
+// parsePyprojectRoots returns the roots of a pyproject.toml, with Dir
+// relative to the file.
+func parsePyprojectRoots(content string) []PythonSourceRoot {
+	var roots []PythonSourceRoot
+	lines := strings.Split(content, "\n")
+	section := ""
+	for i := 0; i < len(lines); i++ {
+		line := stripTOMLComment(strings.TrimSpace(lines[i]))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			section = strings.TrimSpace(strings.Trim(line, "[]"))
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = unquoteTOMLKey(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		// Arrays and inline tables may span several lines.
+		for tomlOpenBrackets(value) > 0 && i+1 < len(lines) {
+			i++
+			value += " " + stripTOMLComment(strings.TrimSpace(lines[i]))
+		}
+
+		switch {
+		case section == "tool.setuptools.package-dir":
+			if dirs := tomlStrings(value); len(dirs) == 1 {
+				roots = append(roots, PythonSourceRoot{Dir: dirs[0], Package: key})
+			}
+		case section == "tool.setuptools" && key == "package-dir":
+			for _, pair := range tomlPairRe.FindAllStringSubmatch(value, -1) {
+				roots = append(roots, PythonSourceRoot{Dir: pair[4] + pair[5], Package: pair[1] + pair[2] + pair[3]})
+			}
+		case section == "tool.setuptools.packages.find" && key == "where":
+			for _, dir := range tomlStrings(value) {
+				roots = append(roots, PythonSourceRoot{Dir: dir})
+			}
+		case section == "tool.poetry" && key == "packages":
+			for _, table := range tomlInlineTableRe.FindAllString(value, -1) {
+				for _, pair := range tomlPairRe.FindAllStringSubmatch(table, -1) {
+					if pair[1]+pair[2]+pair[3] == "from" {
+						roots = append(roots, PythonSourceRoot{Dir: pair[4] + pair[5]})
+					}
+				}
+			}
+		case section == "tool.hatch.build.targets.wheel" && key == "packages":
+			// Each entry is a package directory; its parent is the root.
+			for _, pkg := range tomlStrings(value) {
+				roots = append(roots, PythonSourceRoot{Dir: path.Dir(path.Clean(pkg))})
+			}
+		}
+	}
+	return roots
+}
+
+// parseSetupCfgRoots returns the roots of a setup.cfg, with Dir relative
+// to the file.
+func parseSetupCfgRoots(content string) []PythonSourceRoot {
+	var roots []PythonSourceRoot
+	lines := strings.Split(content, "\n")
+	section := ""
+	for i := 0; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";") {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "[") {
+			section = strings.TrimSpace(strings.Trim(trimmed, "[]"))
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		// Indented lines continue the value, one entry per line.
+		entries := []string{strings.TrimSpace(value)}
+		for i+1 < len(lines) && lines[i+1] != strings.TrimLeft(lines[i+1], " \t") {
+			i++
+			entries = append(entries, strings.TrimSpace(lines[i]))
+		}
+
+		switch {
+		case section == "options" && key == "package_dir":
+			for _, entry := range entries {
+				pkg, dir, ok := strings.Cut(entry, "=")
+				if !ok {
+					continue
+				}
+				roots = append(roots, PythonSourceRoot{Dir: strings.TrimSpace(dir), Package: strings.TrimSpace(pkg)})
+			}
+		case section == "options.packages.find" && key == "where":
+			for _, entry := range entries {
+				if entry != "" {
+					roots = append(roots, PythonSourceRoot{Dir: entry})
+				}
+			}
+		}
+	}
+	return roots
+}
+
+// stripTOMLComment removes a trailing # comment outside strings.
+func stripTOMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return strings.TrimSpace(line[:i])
+		}
+	}
+	return line
+}
+
+// tomlOpenBrackets returns the number of [ and { in value outside strings
+// that are not yet closed.
+func tomlOpenBrackets(value string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// tomlStrings returns the string literals in value, in order.
+func tomlStrings(value string) []string {
+	var out []string
+	for _, m := range tomlStringRe.FindAllStringSubmatch(value, -1) {
+		out = append(out, m[1]+m[2])
+	}
+	return out
+}
+
+// unquoteTOMLKey strips the quotes of a quoted key.
+func unquoteTOMLKey(key string) string {
+	if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+		return key[1 : len(key)-1]
+	}
+	return key
+}
+
+// pythonModuleNames returns the dotted module names of a Python file: its
+// path from the project root, plus one name for each source root that
+// contains it.
+//
+// Description:
+//
+//	("libs/core/src/acme/models.py", [{libs/core/src, ""}])
+//	  → ["libs.core.src.acme.models", "acme.models"]
+//	("billing/__init__.py", [{billing, "acme.billing"}])
+//	  → ["billing", "acme.billing"]
+//
+// Thread Safety: Safe for concurrent use (pure function).
+func pythonModuleNames(filePath string, roots []PythonSourceRoot) []string {
+	module := strings.TrimSuffix(filePath, ".py")
+	module = strings.TrimSuffix(module, "/__init__")
+	if module == "__init__" {
+		return nil
+	}
+	names := []string{strings.ReplaceAll(module, "/", ".")}
+	for _, root := range roots {
+		var rel string
+		switch {
+		case root.Dir == "":
+			rel = module
+		case module == root.Dir:
+			rel = ""
+		case strings.HasPrefix(module, root.Dir+"/"):
+			rel = module[len(root.Dir)+1:]
+		default:
+			continue
+		}
+		name := strings.ReplaceAll(rel, "/", ".")
+		switch {
+		case root.Package == "":
+		case name == "":
+			name = root.Package
+		default:
+			name = root.Package + "." + name
+		}
+		if name != "" && !containsString(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// pythonSourceRootOf returns the Dir of the innermost root containing
+// filePath, or "" when no root does.
+func pythonSourceRootOf(filePath string, roots []PythonSourceRoot) string {
+	best := ""
+	for _, root := range roots {
+		if root.Dir != "" && strings.HasPrefix(filePath, root.Dir+"/") && len(root.Dir) > len(best) {
+			best = root.Dir
+		}
+	}
+	return best
+}
+
+// pythonImportTargets picks, among the symbols named like an imported
+// name, the ones defined by the imported module.
+//
+// Description:
+//
+//	A candidate file matches exactly when one of its module names (see
+//	pythonModuleNames) equals the import's absolute module path, and by
+//	suffix when matchesImportPath accepts it. Exact matches win; suffix
+//	matches are used only when nothing matches exactly. When several
+//	files match at the same level the import is ambiguous: the file under
+//	the importer's own source root is chosen, otherwise the first in path
+//	order, and every matching file is returned as a candidate.
+//
+// Inputs:
+//
+//	importerFile - The importing file.
+//	modulePath - The imported module path, possibly relative.
+//	symbols - The symbols named like the imported name.
+//
+// Outputs:
+//
+//	[]*ast.Symbol - The symbols of the chosen file. Nil if no file matches.
+//	[]string - The matching files, sorted, when more than one matches;
+//	  nil otherwise.
+//
+// Thread Safety: Safe for concurrent use (read-only access to b.options).
+func (b *Builder) pythonImportTargets(importerFile, modulePath string, symbols []*ast.Symbol) ([]*ast.Symbol, []string) {
+	absolute := absolutePythonModulePath(importerFile, modulePath)
+	roots := b.options.PythonSourceRoots
+
+	byFile := make(map[string][]*ast.Symbol)
+	var exact, suffix []string
+	for _, sym := range symbols {
+		if sym == nil || !strings.HasSuffix(sym.FilePath, ".py") {
+			continue
+		}
+		_, known := byFile[sym.FilePath]
+		byFile[sym.FilePath] = append(byFile[sym.FilePath], sym)
+		if known {
+			continue
+		}
+		switch {
+		case absolute != "" && containsString(pythonModuleNames(sym.FilePath, roots), absolute):
+			exact = append(exact, sym.FilePath)
+		case matchesImportPath(sym.FilePath, modulePath):
+			suffix = append(suffix, sym.FilePath)
+		}
+	}
+
+	files := exact
+	if len(files) == 0 {
+		files = suffix
+	}
+	switch len(files) {
+	case 0:
+		return nil, nil
+	case 1:
+		return byFile[files[0]], nil
+	}
+
+	sort.Strings(files)
+	chosen := files[0]
+	importerRoot := pythonSourceRootOf(importerFile, roots)
+	if importerRoot != "" {
+		for _, f := range files {
+			if pythonSourceRootOf(f, roots) == importerRoot {
+				chosen = f
+				break
+			}
+		}
+	}
+	return byFile[chosen], files
+}
@@ -0,0 +1,238 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+func TestParsePythonPackageConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+		data string
+		want []PythonSourceRoot
+	}{
+		{
+			name: "setuptools package-dir table",
+			path: "libs/core/pyproject.toml",
+			data: `[project]
+name = "acme-core"
+
+[tool.setuptools.package-dir]
+"" = "src"
+"acme.billing" = "billing"  # namespace portion
+`,
+			want: []PythonSourceRoot{{Dir: "libs/core/billing", Package: "acme.billing"}, {Dir: "libs/core/src"}},
+		},
+		{
+			name: "setuptools inline package-dir and find",
+			path: "pyproject.toml",
+			data: `[tool.setuptools]
+package-dir = {"" = "lib"}
+
+[tool.setuptools.packages.find]
+where = [
+    "lib",
+    "plugins",
+]
+`,
+			want: []PythonSourceRoot{{Dir: "lib"}, {Dir: "plugins"}},
+		},
+		{
+			name: "poetry and hatch",
+			path: "svc/pyproject.toml",
+			data: `[tool.poetry]
+packages = [
+  { include = "acme", from = "src" },
+  { include = "tools" },
+]
+
+[tool.hatch.build.targets.wheel]
+packages = ["pkgs/acme_extra"]
+`,
+			want: []PythonSourceRoot{{Dir: "svc/pkgs"}, {Dir: "svc/src"}},
+		},
+		{
+			name: "setup.cfg",
+			path: "setup.cfg",
+			data: `[metadata]
+name = acme
+
+[options]
+package_dir =
+    =src
+    acme.legacy = old/legacy
+packages = find_namespace:
+
+[options.packages.find]
+where = src
+`,
+			want: []PythonSourceRoot{{Dir: "old/legacy", Package: "acme.legacy"}, {Dir: "src"}},
+		},
+		{
+			name: "outside project and root only",
+			path: "pyproject.toml",
+			data: `[tool.setuptools.package-dir]
+"" = "."
+other = "../other"
+`,
+		},
+		{name: "other file", path: "setup.py", data: `package_dir={"": "src"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParsePythonPackageConfig(tt.path, []byte(tt.data))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("roots = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPythonModuleNames(t *testing.T) {
+	roots := []PythonSourceRoot{{Dir: "libs/core/src"}, {Dir: "billing", Package: "acme.billing"}}
+	tests := []struct {
+		file string
+		want []string
+	}{
+		{"libs/core/src/acme/models.py", []string{"libs.core.src.acme.models", "acme.models"}},
+		{"billing/__init__.py", []string{"billing", "acme.billing"}},
+		{"billing/invoices.py", []string{"billing.invoices", "acme.billing.invoices"}},
+		{"app/views.py", []string{"app.views"}},
+		{"__init__.py", nil},
+	}
+	for _, tt := range tests {
+		if got := pythonModuleNames(tt.file, roots); !slices.Equal(got, tt.want) {
+			t.Errorf("pythonModuleNames(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+// namespaceFixture returns a project with two modules named acme/models.py,
+// each defining Order, under libs/core/src and second, plus a view in
+// importer that does "from acme.models import Order" and calls Order().
+func namespaceFixture(second, importer string) ([]*ast.ParseResult, *ast.Symbol, *ast.Symbol, *ast.Symbol, *ast.Symbol) {
+	coreFile := "libs/core/src/acme/models.py"
+	otherFile := second + "/acme/models.py"
+	coreOrder := makePythonVariable(coreFile, 3, "Order")
+	otherOrder := makePythonVariable(otherFile, 5, "Order")
+	coreOrder.Kind, otherOrder.Kind = ast.SymbolKindClass, ast.SymbolKindClass
+
+	viewPkg := makePythonPackageSymbol(importer, "views")
+	checkout := &ast.Symbol{
+		ID: ast.GenerateID(importer, 10, "checkout"), Name: "checkout", Kind: ast.SymbolKindFunction,
+		FilePath: importer, Language: "python", StartLine: 10, EndLine: 12,
+		Calls: []ast.CallSite{{Target: "Order", Location: ast.Location{FilePath: importer, StartLine: 11}}},
+	}
+	imp := makeImport("acme.models", []string{"Order"}, false, false)
+	imp.Location = ast.Location{FilePath: importer, StartLine: 2}
+
+	results := []*ast.ParseResult{
+		{FilePath: coreFile, Language: "python", Symbols: []*ast.Symbol{makePythonPackageSymbol(coreFile, "models"), coreOrder}},
+		{FilePath: otherFile, Language: "python", Symbols: []*ast.Symbol{makePythonPackageSymbol(otherFile, "models"), otherOrder}},
+		{FilePath: importer, Language: "python", Symbols: []*ast.Symbol{viewPkg, checkout}, Imports: []ast.Import{imp}},
+	}
+	return results, coreOrder, otherOrder, viewPkg, checkout
+}
+
+// edgeProvenance returns the provenance of the first edge of edgeType from
+// fromID to toID.
+func edgeProvenance(t *testing.T, g *Graph, fromID, toID string, edgeType EdgeType) EdgeProvenance {
+	t.Helper()
+	node, _ := g.GetNode(fromID)
+	if node != nil {
+		for _, e := range node.Outgoing {
+			if e.ToID == toID && e.Type == edgeType {
+				return e.Provenance
+			}
+		}
+	}
+	t.Fatalf("no %s edge %s → %s", edgeType, fromID, toID)
+	return EdgeProvenance{}
+}
+
+func TestBuilder_PythonNamespaceRoots_ExactPathWins(t *testing.T) {
+	results, coreOrder, vendoredOrder, viewPkg, checkout := namespaceFixture("vendor/legacy", "app/views.py")
+
+	// Without roots both files match "acme.models" by suffix.
+	result, err := NewBuilder().Build(context.Background(), results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(result.AmbiguousImports) != 1 || result.Stats.AmbiguousImports != 1 {
+		t.Fatalf("AmbiguousImports = %+v, want one", result.AmbiguousImports)
+	}
+	amb := result.AmbiguousImports[0]
+	wantCandidates := []string{coreOrder.FilePath, vendoredOrder.FilePath}
+	if amb.FilePath != "app/views.py" || amb.Line != 2 || amb.Name != "Order" || !slices.Equal(amb.Candidates, wantCandidates) || amb.Chosen != coreOrder.FilePath {
+		t.Errorf("AmbiguousImport = %+v", amb)
+	}
+	if prov := edgeProvenance(t, result.Graph, viewPkg.ID, coreOrder.ID, EdgeTypeReferences); !prov.IsAmbiguousImport() || prov.Confidence != ConfidenceLow {
+		t.Errorf("reference provenance = %s, want ambiguous", prov)
+	}
+	if hasEdge(result.Graph, viewPkg.ID, vendoredOrder.ID, EdgeTypeReferences) {
+		t.Error("ambiguous import linked to every candidate")
+	}
+	if !result.Graph.HasAmbiguousImportEdge(checkout.ID, coreOrder.ID) {
+		t.Error("call edge not marked ambiguous")
+	}
+
+	// With libs/core/src declared as a root, acme.models names exactly one file.
+	result, err = NewBuilder(WithPythonSourceRoots(PythonSourceRoot{Dir: "libs/core/src"})).Build(context.Background(), results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(result.AmbiguousImports) != 0 {
+		t.Errorf("AmbiguousImports = %+v, want none", result.AmbiguousImports)
+	}
+	if prov := edgeProvenance(t, result.Graph, viewPkg.ID, coreOrder.ID, EdgeTypeReferences); prov.Confidence != ConfidenceHigh || prov.IsAmbiguousImport() {
+		t.Errorf("reference provenance = %s, want high", prov)
+	}
+	if prov := edgeProvenance(t, result.Graph, checkout.ID, coreOrder.ID, EdgeTypeCalls); prov.Pass != PassImportAwareResolution || prov.IsAmbiguousImport() {
+		t.Errorf("call provenance = %s", prov)
+	}
+	if hasEdge(result.Graph, viewPkg.ID, vendoredOrder.ID, EdgeTypeReferences) {
+		t.Error("reference to the vendored module")
+	}
+}
+
+func TestBuilder_PythonNamespaceRoots_TieRecorded(t *testing.T) {
+	// Both files are acme.models under a declared root; the importer lives
+	// under the second root, whose module is chosen.
+	results, coreOrder, extraOrder, viewPkg, checkout := namespaceFixture("libs/extra/src", "libs/extra/src/acme/views.py")
+	roots := []PythonSourceRoot{{Dir: "libs/core/src"}, {Dir: "libs/extra/src"}}
+
+	result, err := NewBuilder(WithPythonSourceRoots(roots...)).Build(context.Background(), results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if len(result.AmbiguousImports) != 1 {
+		t.Fatalf("AmbiguousImports = %+v, want one", result.AmbiguousImports)
+	}
+	amb := result.AmbiguousImports[0]
+	if !slices.Equal(amb.Candidates, []string{coreOrder.FilePath, extraOrder.FilePath}) || amb.Chosen != extraOrder.FilePath {
+		t.Errorf("AmbiguousImport = %+v", amb)
+	}
+	if prov := edgeProvenance(t, result.Graph, viewPkg.ID, extraOrder.ID, EdgeTypeReferences); !prov.IsAmbiguousImport() {
+		t.Errorf("reference provenance = %s, want ambiguous", prov)
+	}
+	if prov := edgeProvenance(t, result.Graph, checkout.ID, extraOrder.ID, EdgeTypeCalls); !prov.IsAmbiguousImport() {
+		t.Errorf("call provenance = %s, want ambiguous", prov)
+	}
+	if !slices.Equal(result.Graph.PythonSourceRoots, roots) {
+		t.Errorf("graph roots = %+v", result.Graph.PythonSourceRoots)
+	}
+}
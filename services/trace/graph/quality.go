@@ -0,0 +1,567 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// MaxUnresolvedCallTargets is the number of unresolved call targets a
+// QualityReport lists.
+const MaxUnresolvedCallTargets = 20
+
+// QualityLevel buckets a graph's confidence score.
+type QualityLevel string
+
+const (
+	// QualityHigh means answers can rely on the graph.
+	QualityHigh QualityLevel = "high"
+
+	// QualityMedium means some relationships are likely missing.
+	QualityMedium QualityLevel = "medium"
+
+	// QualityLow means many relationships are missing; answers based on
+	// the graph should be verified against the source.
+	QualityLow QualityLevel = "low"
+)
+
+// Confidence thresholds for QualityHigh and QualityMedium.
+const (
+	qualityHighThreshold   = 0.85
+	qualityMediumThreshold = 0.65
+)
+
+// QualityReport describes how completely a graph captures its project.
+//
+// Description:
+//
+//	Computed by NewQualityReport at the end of a build and persisted with
+//	the graph. Calls resolved to a project symbol count as resolved; calls
+//	to a placeholder with a known package (e.g. "fmt.Println") count as
+//	placeholder, as do calls to Go and Python builtins; other calls to a
+//	placeholder with no package count as unresolved. Placeholder calls are
+//	external code, not gaps, so they do not lower the resolution rate.
+type QualityReport struct {
+	// GeneratedAtMilli is when the report was computed (Unix milliseconds UTC).
+	GeneratedAtMilli int64 `json:"generated_at_milli"`
+
+	// Languages breaks the graph's symbols and calls down by language.
+	Languages map[string]*LanguageQuality `json:"languages"`
+
+	// Calls counts EdgeTypeCalls edges by how their target was resolved.
+	Calls CallResolution `json:"calls"`
+
+	// Imports counts EdgeTypeImports edges by whether the imported path is
+	// part of the project.
+	Imports ImportResolution `json:"imports"`
+
+	// Interfaces counts interfaces with and without an implementation.
+	Interfaces InterfaceSatisfaction `json:"interfaces"`
+
+	// ParseErrors lists the files that failed to parse or parsed with errors.
+	ParseErrors ParseErrorSummary `json:"parse_errors"`
+
+	// TopUnresolvedCalls lists the most frequent unresolved call targets,
+	// at most MaxUnresolvedCallTargets, most frequent first.
+	TopUnresolvedCalls []UnresolvedTarget `json:"top_unresolved_calls"`
+
+	// Confidence is a composite score in [0, 1]: 50% call resolution, 20%
+	// import resolution, 20% parsed files and 10% implemented interfaces.
+	Confidence float64 `json:"confidence"`
+
+	// Level buckets Confidence.
+	Level QualityLevel `json:"level"`
+}
+
+// LanguageQuality is the part of a QualityReport for one language.
+type LanguageQuality struct {
+	// Files is the number of files with at least one symbol.
+	Files int `json:"files"`
+
+	// Symbols is the number of symbols, children included.
+	Symbols int `json:"symbols"`
+
+	// SymbolsByKind counts Symbols by kind name ("function", "struct", ...).
+	SymbolsByKind map[string]int `json:"symbols_by_kind"`
+
+	// Calls counts the calls made from this language's symbols.
+	Calls CallResolution `json:"calls"`
+}
+
+// CallResolution counts call edges by how their target was resolved.
+type CallResolution struct {
+	// Total is the number of call edges.
+	Total int `json:"total"`
+
+	// Resolved is the number of calls to a project symbol.
+	Resolved int `json:"resolved"`
+
+	// Placeholder is the number of calls to an external symbol whose
+	// package is known, or to a language builtin.
+	Placeholder int `json:"placeholder"`
+
+	// Unresolved is the number of calls whose target could not be found.
+	Unresolved int `json:"unresolved"`
+
+	// ResolutionRate is Resolved / (Resolved + Unresolved), or 1 when both
+	// are zero.
+	ResolutionRate float64 `json:"resolution_rate"`
+}
+
+// ImportResolution counts import edges by what the imported path names.
+type ImportResolution struct {
+	// Total is the number of import edges.
+	Total int `json:"total"`
+
+	// Resolved is the number of imports of a project package or module.
+	Resolved int `json:"resolved"`
+
+	// External is the number of imports of third-party or standard
+	// library code.
+	External int `json:"external"`
+
+	// Unresolved is the number of imports that look like project imports
+	// (relative paths, or Go paths under a module the project imports
+	// from) but match no project file.
+	Unresolved int `json:"unresolved"`
+
+	// ResolutionRate is Resolved / (Resolved + Unresolved), or 1 when both
+	// are zero.
+	ResolutionRate float64 `json:"resolution_rate"`
+}
+
+// InterfaceSatisfaction counts interfaces by whether anything implements them.
+type InterfaceSatisfaction struct {
+	// Total is the number of interfaces.
+	Total int `json:"total"`
+
+	// Implemented is the number of interfaces with an EdgeTypeImplements
+	// edge pointing at them.
+	Implemented int `json:"implemented"`
+
+	// Unimplemented is Total - Implemented.
+	Unimplemented int `json:"unimplemented"`
+}
+
+// ParseErrorSummary lists the files with parse errors.
+type ParseErrorSummary struct {
+	// Count is len(Files).
+	Count int `json:"count"`
+
+	// Files are the project-relative paths, sorted.
+	Files []string `json:"files"`
+
+	// TotalFiles is the number of files in the graph plus those that
+	// failed to parse.
+	TotalFiles int `json:"total_files"`
+}
+
+// UnresolvedTarget is a call target that could not be resolved.
+type UnresolvedTarget struct {
+	// Target is the called name, as written at the call site.
+	Target string `json:"target"`
+
+	// Count is the number of call edges to Target.
+	Count int `json:"count"`
+
+	// Languages are the languages of the calling symbols, sorted.
+	Languages []string `json:"languages"`
+
+	// Example is one call site, as "file:line".
+	Example string `json:"example"`
+}
+
+// NewQualityReport computes the quality report of a graph.
+//
+// Description:
+//
+//	Walks the graph's nodes and its calls, imports and implements edges.
+//	Files that failed to parse have no nodes, so the builder passes them
+//	in with the files that parsed with errors.
+//
+// Inputs:
+//
+//	g - The graph. Should be frozen. Must not be nil.
+//	parseErrorFiles - Project-relative paths of files with parse errors.
+//	  Duplicates are ignored.
+//
+// Outputs:
+//
+//	*QualityReport - The report. Never nil.
+//
+// Thread Safety: Safe for concurrent use on frozen graphs.
+func NewQualityReport(g *Graph, parseErrorFiles []string) *QualityReport {
+	report := &QualityReport{
+		GeneratedAtMilli:   time.Now().UnixMilli(),
+		Languages:          make(map[string]*LanguageQuality),
+		TopUnresolvedCalls: []UnresolvedTarget{},
+	}
+
+	files := make(map[string]struct{})
+	filesByLanguage := make(map[string]map[string]struct{})
+	for _, node := range g.Nodes() {
+		if node.Symbol == nil || isPlaceholderNode(node) {
+			continue
+		}
+		sym := node.Symbol
+		lq := report.language(sym.Language)
+		lq.Symbols++
+		lq.SymbolsByKind[sym.Kind.String()]++
+		if sym.FilePath == "" {
+			continue
+		}
+		files[sym.FilePath] = struct{}{}
+		if filesByLanguage[sym.Language] == nil {
+			filesByLanguage[sym.Language] = make(map[string]struct{})
+		}
+		filesByLanguage[sym.Language][sym.FilePath] = struct{}{}
+	}
+	for lang, set := range filesByLanguage {
+		report.Languages[lang].Files = len(set)
+	}
+
+	report.countCalls(g)
+	report.countImports(g, files)
+	report.countInterfaces(g)
+	report.countParseErrors(files, parseErrorFiles)
+	report.score()
+	return report
+}
+
+// language returns the entry for lang, creating it.
+func (r *QualityReport) language(lang string) *LanguageQuality {
+	lq, ok := r.Languages[lang]
+	if !ok {
+		lq = &LanguageQuality{SymbolsByKind: make(map[string]int)}
+		r.Languages[lang] = lq
+	}
+	return lq
+}
+
+// countCalls fills Calls, the per-language calls and TopUnresolvedCalls.
+func (r *QualityReport) countCalls(g *Graph) {
+	type unresolved struct {
+		count     int
+		languages map[string]struct{}
+		example   string
+	}
+	targets := make(map[string]*unresolved)
+
+	for _, edge := range g.GetEdgesByType(EdgeTypeCalls) {
+		lang := ""
+		if from, ok := g.GetNode(edge.FromID); ok && from.Symbol != nil {
+			lang = from.Symbol.Language
+		}
+		lq := r.language(lang)
+		r.Calls.Total++
+		lq.Calls.Total++
+
+		to, ok := g.GetNode(edge.ToID)
+		switch {
+		case ok && !isPlaceholderNode(to):
+			r.Calls.Resolved++
+			lq.Calls.Resolved++
+		case ok && (to.Symbol.Package != "" || isBuiltinCall(lang, to.Symbol.Name)):
+			r.Calls.Placeholder++
+			lq.Calls.Placeholder++
+		default:
+			r.Calls.Unresolved++
+			lq.Calls.Unresolved++
+			name := strings.TrimPrefix(edge.ToID, "external::")
+			if ok {
+				name = to.Symbol.Name
+			}
+			u := targets[name]
+			if u == nil {
+				u = &unresolved{languages: make(map[string]struct{})}
+				targets[name] = u
+			}
+			u.count++
+			u.languages[lang] = struct{}{}
+			site := fmt.Sprintf("%s:%d", edge.Location.FilePath, edge.Location.StartLine)
+			if u.example == "" || site < u.example {
+				u.example = site
+			}
+		}
+	}
+
+	r.Calls.ResolutionRate = resolutionRate(r.Calls.Resolved, r.Calls.Unresolved)
+	for _, lq := range r.Languages {
+		lq.Calls.ResolutionRate = resolutionRate(lq.Calls.Resolved, lq.Calls.Unresolved)
+	}
+
+	for name, u := range targets {
+		langs := make([]string, 0, len(u.languages))
+		for lang := range u.languages {
+			langs = append(langs, lang)
+		}
+		sort.Strings(langs)
+		r.TopUnresolvedCalls = append(r.TopUnresolvedCalls, UnresolvedTarget{
+			Target:    name,
+			Count:     u.count,
+			Languages: langs,
+			Example:   u.example,
+		})
+	}
+	sort.Slice(r.TopUnresolvedCalls, func(i, j int) bool {
+		a, b := r.TopUnresolvedCalls[i], r.TopUnresolvedCalls[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Target < b.Target
+	})
+	if len(r.TopUnresolvedCalls) > MaxUnresolvedCallTargets {
+		r.TopUnresolvedCalls = r.TopUnresolvedCalls[:MaxUnresolvedCallTargets]
+	}
+}
+
+// builtinCalls are the builtin functions and conversions of each language
+// that calls resolve to without a definition in the project.
+var builtinCalls = map[string]map[string]struct{}{
+	"go": setOf("append", "cap", "clear", "close", "complex", "copy", "delete", "imag", "len", "make",
+		"max", "min", "new", "panic", "print", "println", "real", "recover",
+		"bool", "byte", "complex64", "complex128", "error", "float32", "float64", "int", "int8",
+		"int16", "int32", "int64", "rune", "string", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "any"),
+	"python": setOf("abs", "all", "any", "bool", "bytes", "callable", "dict", "dir", "enumerate",
+		"filter", "float", "format", "frozenset", "getattr", "hasattr", "hash", "id", "int",
+		"isinstance", "issubclass", "iter", "len", "list", "map", "max", "min", "next", "object",
+		"open", "print", "range", "repr", "reversed", "round", "set", "setattr", "sorted", "str",
+		"sum", "super", "tuple", "type", "vars", "zip"),
+}
+
+// setOf returns a set of the given names.
+func setOf(names ...string) map[string]struct{} {
+	set := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		set[name] = struct{}{}
+	}
+	return set
+}
+
+// isBuiltinCall reports whether name is a builtin of lang.
+func isBuiltinCall(lang, name string) bool {
+	_, ok := builtinCalls[lang][name]
+	return ok
+}
+
+// countImports fills Imports.
+//
+// Description:
+//
+//	A Go import is resolved when it targets a project package (see
+//	WithGoModules), or one of its "/"-suffixes is a project directory,
+//	or two or more trailing elements of one. Other imports are resolved when, with leading dots and
+//	"./" removed and "." or "::" read as "/", they are a suffix of a
+//	project file path without its extension, or of a project directory.
+//	Go imports are unresolved when they share a module prefix
+//	("host/owner/repo") with a resolved import; others when they are
+//	relative.
+func (r *QualityReport) countImports(g *Graph, files map[string]struct{}) {
+	dirs := make(map[string]struct{})
+	modules := make(map[string]struct{})
+	for file := range files {
+		dir := path.Dir(file)
+		for d := dir; d != "." && d != "/"; d = path.Dir(d) {
+			dirs[d] = struct{}{}
+			// Suffixes of two or more elements cover roots whose module
+			// path does not end with the directory's name.
+			for p := d; strings.Count(p, "/") >= 1; p = p[strings.IndexByte(p, '/')+1:] {
+				dirs[p] = struct{}{}
+			}
+		}
+		module := strings.TrimSuffix(file, path.Ext(file))
+		addPathSuffixes(modules, module)
+		for _, index := range []string{"/__init__", "/index"} {
+			if trimmed, ok := strings.CutSuffix(module, index); ok {
+				addPathSuffixes(modules, trimmed)
+			}
+		}
+		if dir != "." {
+			addPathSuffixes(modules, dir)
+		}
+	}
+
+	var unmatchedGo []string
+	resolvedGoModules := make(map[string]struct{})
+
+	for _, edge := range g.GetEdgesByType(EdgeTypeImports) {
+		to, ok := g.GetNode(edge.ToID)
+		if !ok || to.Symbol == nil {
+			continue
+		}
+		importPath := to.Symbol.Package
+		lang := ""
+		if from, ok := g.GetNode(edge.FromID); ok && from.Symbol != nil {
+			lang = from.Symbol.Language
+		}
+		r.Imports.Total++
+
+		if lang == "go" {
+			if to.Symbol.Kind == ast.SymbolKindPackage || goImportInProject(importPath, dirs) {
+				r.Imports.Resolved++
+				if module := goModulePrefix(importPath); module != "" {
+					resolvedGoModules[module] = struct{}{}
+				}
+			} else {
+				unmatchedGo = append(unmatchedGo, importPath)
+			}
+			continue
+		}
+
+		relative := strings.HasPrefix(importPath, ".")
+		fragment := strings.TrimLeft(importPath, "./")
+		if lang != "javascript" && lang != "typescript" {
+			fragment = strings.ReplaceAll(fragment, "::", "/")
+			fragment = strings.ReplaceAll(fragment, ".", "/")
+		} else {
+			fragment = strings.TrimSuffix(fragment, path.Ext(fragment))
+		}
+		_, found := modules[fragment]
+		switch {
+		case found || (relative && fragment == ""):
+			r.Imports.Resolved++
+		case relative:
+			r.Imports.Unresolved++
+		default:
+			r.Imports.External++
+		}
+	}
+
+	for _, importPath := range unmatchedGo {
+		module := goModulePrefix(importPath)
+		if _, ok := resolvedGoModules[module]; ok && module != "" {
+			r.Imports.Unresolved++
+		} else {
+			r.Imports.External++
+		}
+	}
+	r.Imports.ResolutionRate = resolutionRate(r.Imports.Resolved, r.Imports.Unresolved)
+}
+
+// addPathSuffixes adds p and each of its "/"-suffixes to set.
+func addPathSuffixes(set map[string]struct{}, p string) {
+	for {
+		set[p] = struct{}{}
+		i := strings.IndexByte(p, '/')
+		if i < 0 {
+			return
+		}
+		p = p[i+1:]
+	}
+}
+
+// goImportInProject reports whether one of importPath's "/"-suffixes is in
+// dirs.
+func goImportInProject(importPath string, dirs map[string]struct{}) bool {
+	for p := importPath; ; {
+		if _, ok := dirs[p]; ok {
+			return true
+		}
+		i := strings.IndexByte(p, '/')
+		if i < 0 {
+			return false
+		}
+		p = p[i+1:]
+	}
+}
+
+// goModulePrefix returns the "host/owner/repo" prefix of a Go import path
+// whose first element is a domain, or "" (e.g. for the standard library).
+func goModulePrefix(importPath string) string {
+	parts := strings.SplitN(importPath, "/", 4)
+	if len(parts) < 3 || !strings.Contains(parts[0], ".") {
+		return ""
+	}
+	return strings.Join(parts[:3], "/")
+}
+
+// countInterfaces fills Interfaces.
+func (r *QualityReport) countInterfaces(g *Graph) {
+	for _, node := range g.GetNodesByKind(ast.SymbolKindInterface) {
+		r.Interfaces.Total++
+		if slices.ContainsFunc(node.Incoming, func(e *Edge) bool { return e.Type == EdgeTypeImplements }) {
+			r.Interfaces.Implemented++
+		}
+	}
+	r.Interfaces.Unimplemented = r.Interfaces.Total - r.Interfaces.Implemented
+}
+
+// countParseErrors fills ParseErrors.
+func (r *QualityReport) countParseErrors(files map[string]struct{}, parseErrorFiles []string) {
+	failed := make(map[string]struct{}, len(parseErrorFiles))
+	for _, f := range parseErrorFiles {
+		failed[f] = struct{}{}
+	}
+	r.ParseErrors.Files = make([]string, 0, len(failed))
+	total := len(files)
+	for f := range failed {
+		r.ParseErrors.Files = append(r.ParseErrors.Files, f)
+		if _, ok := files[f]; !ok {
+			total++
+		}
+	}
+	sort.Strings(r.ParseErrors.Files)
+	r.ParseErrors.Count = len(r.ParseErrors.Files)
+	r.ParseErrors.TotalFiles = total
+}
+
+// score sets Confidence and Level.
+func (r *QualityReport) score() {
+	parsed := 1.0
+	if r.ParseErrors.TotalFiles > 0 {
+		parsed = 1 - float64(r.ParseErrors.Count)/float64(r.ParseErrors.TotalFiles)
+	}
+	implemented := 1.0
+	if r.Interfaces.Total > 0 {
+		implemented = float64(r.Interfaces.Implemented) / float64(r.Interfaces.Total)
+	}
+	r.Confidence = 0.5*r.Calls.ResolutionRate + 0.2*r.Imports.ResolutionRate + 0.2*parsed + 0.1*implemented
+
+	switch {
+	case r.Confidence >= qualityHighThreshold:
+		r.Level = QualityHigh
+	case r.Confidence >= qualityMediumThreshold:
+		r.Level = QualityMedium
+	default:
+		r.Level = QualityLow
+	}
+}
+
+// resolutionRate returns resolved / (resolved + unresolved), or 1 when
+// both are zero.
+func resolutionRate(resolved, unresolved int) float64 {
+	if resolved+unresolved == 0 {
+		return 1
+	}
+	return float64(resolved) / float64(resolved+unresolved)
+}
+
+// buildParseErrorFiles returns the files of results that failed to parse or
+// parsed with errors.
+func buildParseErrorFiles(result *BuildResult, results []*ast.ParseResult) []string {
+	var files []string
+	for _, fe := range result.FileErrors {
+		files = append(files, fe.FilePath)
+	}
+	for _, r := range results {
+		if r != nil && r.HasErrors() {
+			files = append(files, r.FilePath)
+		}
+	}
+	return files
+}
@@ -0,0 +1,204 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// qualityTestResults returns a Go project where main.go imports a project
+// package, a missing package of the same module and the standard library,
+// and calls one project function, fmt.Println, len and two unknown
+// functions.
+func qualityTestResults() []*ast.ParseResult {
+	call := func(target string, line int) ast.CallSite {
+		return ast.CallSite{Target: target, Location: ast.Location{FilePath: "cmd/app/main.go", StartLine: line}}
+	}
+	run := testSymbolWithCalls("run", ast.SymbolKindFunction, "cmd/app/main.go", 10, []ast.CallSite{
+		call("store.Save", 11),
+		call("fmt.Println", 12),
+		call("helper", 14),
+		call("helper", 13),
+		call("cleanup", 15),
+		call("len", 16),
+	})
+	main := &ast.ParseResult{
+		FilePath: "cmd/app/main.go",
+		Language: "go",
+		Symbols:  []*ast.Symbol{testSymbol("main", ast.SymbolKindPackage, "cmd/app/main.go", 1), run},
+		Imports: []ast.Import{
+			{Path: "fmt", Location: ast.Location{FilePath: "cmd/app/main.go", StartLine: 3}},
+			{Path: "example.com/app/internal/store", Location: ast.Location{FilePath: "cmd/app/main.go", StartLine: 4}},
+			{Path: "example.com/app/internal/cache", Location: ast.Location{FilePath: "cmd/app/main.go", StartLine: 5}},
+		},
+	}
+	store := testParseResult("internal/store/store.go", []*ast.Symbol{
+		testSymbol("store", ast.SymbolKindPackage, "internal/store/store.go", 1),
+		testSymbol("Save", ast.SymbolKindFunction, "internal/store/store.go", 5),
+		testSymbol("Saver", ast.SymbolKindInterface, "internal/store/store.go", 20),
+	}, nil)
+	broken := testParseResult("internal/store/broken.go", []*ast.Symbol{
+		testSymbol("store", ast.SymbolKindPackage, "internal/store/broken.go", 1),
+	}, nil)
+	broken.Errors = []string{"expected '}', found 'EOF'"}
+	return []*ast.ParseResult{main, store, broken}
+}
+
+func TestBuilder_Build_QualityReport(t *testing.T) {
+	result, err := NewBuilder().Build(context.Background(), qualityTestResults())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	report := result.Graph.QualityReport
+	if report == nil {
+		t.Fatal("QualityReport not set")
+	}
+
+	goLang := report.Languages["go"]
+	if goLang == nil || goLang.Files != 3 || goLang.SymbolsByKind["function"] != 2 || goLang.SymbolsByKind["interface"] != 1 {
+		t.Errorf("go = %+v", goLang)
+	}
+	if _, ok := report.Languages["external"]; ok {
+		t.Error("placeholders counted as a language")
+	}
+
+	wantCalls := CallResolution{Total: 6, Resolved: 1, Placeholder: 2, Unresolved: 3, ResolutionRate: 0.25}
+	if report.Calls != wantCalls {
+		t.Errorf("Calls = %+v, want %+v", report.Calls, wantCalls)
+	}
+	if goLang != nil && goLang.Calls != wantCalls {
+		t.Errorf("go calls = %+v, want %+v", goLang.Calls, wantCalls)
+	}
+	if len(report.TopUnresolvedCalls) != 2 {
+		t.Fatalf("TopUnresolvedCalls = %+v", report.TopUnresolvedCalls)
+	}
+	top := report.TopUnresolvedCalls[0]
+	if top.Target != "helper" || top.Count != 2 || top.Example != "cmd/app/main.go:13" || !slices.Equal(top.Languages, []string{"go"}) {
+		t.Errorf("top unresolved = %+v", top)
+	}
+
+	wantImports := ImportResolution{Total: 3, Resolved: 1, External: 1, Unresolved: 1, ResolutionRate: 0.5}
+	if report.Imports != wantImports {
+		t.Errorf("Imports = %+v, want %+v", report.Imports, wantImports)
+	}
+	if report.Interfaces != (InterfaceSatisfaction{Total: 1, Unimplemented: 1}) {
+		t.Errorf("Interfaces = %+v", report.Interfaces)
+	}
+	if report.ParseErrors.Count != 1 || report.ParseErrors.TotalFiles != 3 || report.ParseErrors.Files[0] != "internal/store/broken.go" {
+		t.Errorf("ParseErrors = %+v", report.ParseErrors)
+	}
+
+	// 0.5*0.25 + 0.2*0.5 + 0.2*(2/3) + 0.1*0
+	if want := 0.125 + 0.1 + 0.4/3; math.Abs(report.Confidence-want) > 1e-9 || report.Level != QualityLow {
+		t.Errorf("Confidence = %v %s, want %v low", report.Confidence, report.Level, want)
+	}
+}
+
+func TestNewQualityReport_ImportsAndInterfaces(t *testing.T) {
+	g := NewGraph("/proj")
+	symbols := []*ast.Symbol{
+		{ID: "app/views.py:1:views", Name: "views", Kind: ast.SymbolKindPackage, FilePath: "app/views.py", Language: "python"},
+		{ID: "app/models/__init__.py:1:models", Name: "models", Kind: ast.SymbolKindPackage, FilePath: "app/models/__init__.py", Language: "python"},
+		{ID: "web/src/index.ts:1:index", Name: "index", Kind: ast.SymbolKindPackage, FilePath: "web/src/index.ts", Language: "typescript"},
+		{ID: "web/src/api/index.ts:3:Client", Name: "Client", Kind: ast.SymbolKindInterface, FilePath: "web/src/api/index.ts", Language: "typescript"},
+		{ID: "web/src/api/http.ts:3:HTTPClient", Name: "HTTPClient", Kind: ast.SymbolKindClass, FilePath: "web/src/api/http.ts", Language: "typescript"},
+		{ID: "web/src/api/index.ts:9:Logger", Name: "Logger", Kind: ast.SymbolKindInterface, FilePath: "web/src/api/index.ts", Language: "typescript"},
+	}
+	for _, sym := range symbols {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	imports := []struct {
+		from, path string
+	}{
+		{"app/views.py:1:views", "app.models"},         // resolved: package __init__
+		{"app/views.py:1:views", ".models"},            // resolved: relative
+		{"app/views.py:1:views", ".forms"},             // unresolved: relative, missing
+		{"app/views.py:1:views", "django.http"},        // external
+		{"web/src/index.ts:1:index", "./api"},          // resolved: index file
+		{"web/src/index.ts:1:index", "./api/http.js"},  // resolved: extension
+		{"web/src/index.ts:1:index", "../lib/missing"}, // unresolved
+		{"web/src/index.ts:1:index", "react"},          // external
+	}
+	for i, imp := range imports {
+		id := "external:" + imp.path + ":" + imp.path
+		if _, err := g.AddNode(&ast.Symbol{ID: id, Name: imp.path, Kind: ast.SymbolKindExternal, Package: imp.path, Language: "external"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.AddEdge(imp.from, id, EdgeTypeImports, ast.Location{FilePath: fmt.Sprint(i), StartLine: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := g.AddEdge("web/src/api/http.ts:3:HTTPClient", "web/src/api/index.ts:3:Client", EdgeTypeImplements, ast.Location{}); err != nil {
+		t.Fatal(err)
+	}
+	g.Freeze()
+
+	report := NewQualityReport(g, []string{"app/broken.py", "app/broken.py"})
+
+	wantImports := ImportResolution{Total: 8, Resolved: 4, External: 2, Unresolved: 2, ResolutionRate: 4.0 / 6}
+	if report.Imports != wantImports {
+		t.Errorf("Imports = %+v, want %+v", report.Imports, wantImports)
+	}
+	if report.Interfaces != (InterfaceSatisfaction{Total: 2, Implemented: 1, Unimplemented: 1}) {
+		t.Errorf("Interfaces = %+v", report.Interfaces)
+	}
+	if report.ParseErrors.Count != 1 || report.ParseErrors.TotalFiles != 6 {
+		t.Errorf("ParseErrors = %+v", report.ParseErrors)
+	}
+	if report.Languages["typescript"].Files != 3 || report.Languages["python"].Symbols != 2 {
+		t.Errorf("Languages = %+v %+v", report.Languages["typescript"], report.Languages["python"])
+	}
+	if report.Calls.ResolutionRate != 1 {
+		t.Errorf("call resolution rate without calls = %v, want 1", report.Calls.ResolutionRate)
+	}
+}
+
+func TestQualityReport_Persisted(t *testing.T) {
+	result, err := NewBuilder().Build(context.Background(), qualityTestResults())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	want := result.Graph.QualityReport
+
+	restored, err := FromSerializable(result.Graph.ToSerializable())
+	if err != nil {
+		t.Fatalf("FromSerializable: %v", err)
+	}
+	if got := restored.QualityReport; got == nil || got.Confidence != want.Confidence || got.Calls != want.Calls {
+		t.Errorf("serialized report = %+v, want %+v", got, want)
+	}
+
+	path := t.TempDir() + "/graph.db"
+	if err := result.Graph.MaterializeToDisk(context.Background(), path); err != nil {
+		t.Fatalf("MaterializeToDisk: %v", err)
+	}
+	dg, err := OpenDiskGraph(path)
+	if err != nil {
+		t.Fatalf("OpenDiskGraph: %v", err)
+	}
+	defer dg.Close()
+	loaded, err := dg.LoadAsGraph(context.Background())
+	if err != nil {
+		t.Fatalf("LoadAsGraph: %v", err)
+	}
+	got := loaded.QualityReport
+	if got == nil || got.Confidence != want.Confidence || got.Imports != want.Imports || len(got.TopUnresolvedCalls) != 2 {
+		t.Errorf("disk report = %+v, want %+v", got, want)
+	}
+}
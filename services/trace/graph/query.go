@@ -15,9 +15,16 @@ import (
 	"fmt"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
+var queryTracer = otel.Tracer("graph.query")
+
 // Query configuration limits.
 const (
 	// DefaultQueryLimit is the default maximum number of results.
@@ -44,6 +51,10 @@ type QueryResult struct {
 	// Truncated is true if limit was reached or context was cancelled.
 	Truncated bool
 
+	// Total is the number of matches, including those skipped by Offset
+	// and those past Limit. A lower bound when the context was cancelled.
+	Total int
+
 	// Duration is the query execution time.
 	Duration time.Duration
 }
@@ -72,6 +83,10 @@ type InheritanceQueryResult struct {
 	// Truncated is true if limit was reached or context was cancelled.
 	Truncated bool
 
+	// Total is the number of distinct callers across all levels,
+	// including those skipped by Offset and those past Limit.
+	Total int
+
 	// Duration is the total query execution time.
 	Duration time.Duration
 }
@@ -126,6 +141,10 @@ type QueryOptions struct {
 	// Limit is the maximum number of results (default: 1000, max: 10000).
 	Limit int
 
+	// Offset is the number of leading results to skip, for paging
+	// (default: 0). Results are in the frozen graph's edge order.
+	Offset int
+
 	// MaxDepth is the maximum traversal depth (default: 10, max: 100).
 	MaxDepth int
 
@@ -160,6 +179,18 @@ func WithLimit(n int) QueryOption {
 	}
 }
 
+// WithOffset skips the first n results, for paging. Only the direct
+// queries (FindCallersByID, FindCallersWithInheritance, FindCalleesByID,
+// FindImplementationsByID, FindReferencesByID and their ByName variants)
+// honor it.
+//
+// If n < 0, uses 0.
+func WithOffset(n int) QueryOption {
+	return func(o *QueryOptions) {
+		o.Offset = max(n, 0)
+	}
+}
+
 // WithMaxDepth sets the maximum traversal depth.
 //
 // If d < 0, uses default (10).
@@ -192,35 +223,6 @@ func applyOptions(opts []QueryOption) QueryOptions {
 	return options
 }
 
-// Validate checks that the graph is in a consistent state for querying.
-//
-// Description:
-//
-//	Verifies all edges reference existing nodes. Should be called once
-//	after build, before queries. Queries will return error if validation
-//	fails.
-//
-// Outputs:
-//
-//	error - Non-nil if graph is corrupt (dangling edges)
-//
-// Example:
-//
-//	if err := graph.Validate(); err != nil {
-//	    return fmt.Errorf("graph corrupt: %w", err)
-//	}
-func (g *Graph) Validate() error {
-	for i, edge := range g.edges {
-		if _, ok := g.nodes[edge.FromID]; !ok {
-			return fmt.Errorf("edge[%d]: source node %q not found", i, edge.FromID)
-		}
-		if _, ok := g.nodes[edge.ToID]; !ok {
-			return fmt.Errorf("edge[%d]: target node %q not found", i, edge.ToID)
-		}
-	}
-	return nil
-}
-
 // FindCallersByID returns all symbols that call the given function/method.
 //
 // Description:
@@ -232,7 +234,7 @@ func (g *Graph) Validate() error {
 //
 //	ctx - Context for cancellation
 //	symbolID - ID of the function/method to find callers for
-//	opts - Query options (Limit, Timeout)
+//	opts - Query options (Limit, Offset, Timeout)
 //
 // Outputs:
 //
@@ -269,13 +271,16 @@ func (g *Graph) FindCallersByID(ctx context.Context, symbolID string, opts ...Qu
 			continue
 		}
 
-		if len(result.Symbols) >= options.Limit {
-			result.Truncated = true
-			break
+		callerNode, ok := g.edgeTarget(edge, edge.FromID)
+		if !ok || callerNode.Symbol == nil {
+			continue
 		}
-
-		callerNode, exists := g.nodes[edge.FromID]
-		if exists {
+		result.Total++
+		switch {
+		case result.Total <= options.Offset:
+		case len(result.Symbols) >= options.Limit:
+			result.Truncated = true
+		default:
 			result.Symbols = append(result.Symbols, callerNode.Symbol)
 		}
 	}
@@ -300,7 +305,7 @@ func (g *Graph) FindCallersByID(ctx context.Context, symbolID string, opts ...Qu
 //	ctx - Context for cancellation.
 //	symbolID - ID of the target method (e.g., Plot.renderImmediately).
 //	parentMethodIDs - IDs of the same-named method on parent classes.
-//	opts - Query options (Limit, Timeout).
+//	opts - Query options (Limit, Offset, Timeout).
 //
 // Outputs:
 //
@@ -318,6 +323,46 @@ func (g *Graph) FindCallersWithInheritance(ctx context.Context, symbolID string,
 	seen := make(map[string]bool)
 	totalCount := 0
 
+	// keep counts a caller and reports whether it belongs in the page.
+	keep := func() bool {
+		result.Total++
+		switch {
+		case result.Total <= options.Offset:
+			return false
+		case totalCount >= options.Limit:
+			result.Truncated = true
+			return false
+		}
+		totalCount++
+		return true
+	}
+
+	ctx, span := queryTracer.Start(ctx, "graph.Graph.FindCallersWithInheritance",
+		trace.WithAttributes(
+			telemetry.SensitiveString("symbol_id", symbolID),
+			attribute.Int("parent_method_count", len(parentMethodIDs)),
+		),
+	)
+	defer func() {
+		// Nodes visited: the queried methods found in the graph plus
+		// every distinct caller examined.
+		visited := len(seen)
+		if _, ok := g.nodes[symbolID]; ok {
+			visited++
+		}
+		for _, parentID := range parentMethodIDs {
+			if _, ok := g.nodes[parentID]; ok {
+				visited++
+			}
+		}
+		span.SetAttributes(
+			attribute.Int("nodes_visited", visited),
+			attribute.Int("result_count", totalCount),
+			attribute.Bool("truncated", result.Truncated),
+		)
+		span.End()
+	}()
+
 	// Phase 1: Direct callers of the primary method
 	if node, ok := g.nodes[symbolID]; ok {
 		for _, edge := range node.Incoming {
@@ -333,13 +378,8 @@ func (g *Graph) FindCallersWithInheritance(ctx context.Context, symbolID string,
 				continue
 			}
 			seen[edge.FromID] = true
-			if totalCount >= options.Limit {
-				result.Truncated = true
-				break
-			}
-			if callerNode, exists := g.nodes[edge.FromID]; exists {
+			if callerNode, ok := g.edgeTarget(edge, edge.FromID); ok && callerNode.Symbol != nil && keep() {
 				result.DirectCallers.Symbols = append(result.DirectCallers.Symbols, callerNode.Symbol)
-				totalCount++
 			}
 		}
 	}
@@ -372,13 +412,8 @@ func (g *Graph) FindCallersWithInheritance(ctx context.Context, symbolID string,
 				continue
 			}
 			seen[edge.FromID] = true
-			if totalCount >= options.Limit {
-				result.Truncated = true
-				break
-			}
-			if callerNode, exists := g.nodes[edge.FromID]; exists {
+			if callerNode, ok := g.edgeTarget(edge, edge.FromID); ok && callerNode.Symbol != nil && keep() {
 				parentResult.Symbols = append(parentResult.Symbols, callerNode.Symbol)
-				totalCount++
 			}
 		}
 
@@ -402,7 +437,7 @@ func (g *Graph) FindCallersWithInheritance(ctx context.Context, symbolID string,
 //
 //	ctx - Context for cancellation
 //	symbolID - ID of the function/method to find callees for
-//	opts - Query options (Limit, Timeout)
+//	opts - Query options (Limit, Offset, Timeout)
 //
 // Outputs:
 //
@@ -434,14 +469,17 @@ func (g *Graph) FindCalleesByID(ctx context.Context, symbolID string, opts ...Qu
 			continue
 		}
 
-		if len(result.Symbols) >= options.Limit {
-			result.Truncated = true
-			break
+		calleeNode, ok := g.edgeTarget(edge, edge.ToID)
+		if !ok || calleeNode.Symbol == nil || seen[calleeNode.Symbol.ID] {
+			continue
 		}
-
-		calleeNode, exists := g.nodes[edge.ToID]
-		if exists && !seen[calleeNode.Symbol.ID] {
-			seen[calleeNode.Symbol.ID] = true
+		seen[calleeNode.Symbol.ID] = true
+		result.Total++
+		switch {
+		case result.Total <= options.Offset:
+		case len(result.Symbols) >= options.Limit:
+			result.Truncated = true
+		default:
 			result.Symbols = append(result.Symbols, calleeNode.Symbol)
 		}
 	}
@@ -464,7 +502,7 @@ func (g *Graph) FindCalleesByID(ctx context.Context, symbolID string, opts ...Qu
 //
 //	ctx - Context for cancellation
 //	interfaceID - ID of the interface, class, or struct to find implementers for
-//	opts - Query options (Limit, Timeout)
+//	opts - Query options (Limit, Offset, Timeout)
 //
 // Outputs:
 //
@@ -500,14 +538,17 @@ func (g *Graph) FindImplementationsByID(ctx context.Context, interfaceID string,
 			continue
 		}
 
-		if len(result.Symbols) >= options.Limit {
-			result.Truncated = true
-			break
+		implNode, ok := g.edgeTarget(edge, edge.FromID)
+		if !ok || implNode.Symbol == nil || seen[implNode.ID] {
+			continue
 		}
-
-		implNode, exists := g.nodes[edge.FromID]
-		if exists && implNode.Symbol != nil && !seen[implNode.ID] {
-			seen[implNode.ID] = true
+		seen[implNode.ID] = true
+		result.Total++
+		switch {
+		case result.Total <= options.Offset:
+		case len(result.Symbols) >= options.Limit:
+			result.Truncated = true
+		default:
 			result.Symbols = append(result.Symbols, implNode.Symbol)
 		}
 	}
@@ -527,7 +568,7 @@ func (g *Graph) FindImplementationsByID(ctx context.Context, interfaceID string,
 //
 //	ctx - Context for cancellation
 //	symbolID - ID of the symbol to find references for
-//	opts - Query options (Limit, Timeout)
+//	opts - Query options (Limit, Offset, Timeout)
 //
 // Outputs:
 //
@@ -542,6 +583,7 @@ func (g *Graph) FindReferencesByID(ctx context.Context, symbolID string, opts ..
 	}
 
 	locations := make([]ast.Location, 0)
+	skip := options.Offset
 
 	// IT-06d Bug 10: Two-pass edge collection ensures EdgeTypeReferences and
 	// EdgeTypeImplements edges appear before EdgeTypeCalls edges, regardless of
@@ -559,6 +601,10 @@ func (g *Graph) FindReferencesByID(ctx context.Context, symbolID string, opts ..
 			return locations, nil
 		}
 		if edge.Type == EdgeTypeReferences || edge.Type == EdgeTypeImplements {
+			if skip > 0 {
+				skip--
+				continue
+			}
 			locations = append(locations, edge.Location)
 		}
 	}
@@ -572,6 +618,10 @@ func (g *Graph) FindReferencesByID(ctx context.Context, symbolID string, opts ..
 			return locations, nil
 		}
 		if edge.Type != EdgeTypeReferences && edge.Type != EdgeTypeImplements {
+			if skip > 0 {
+				skip--
+				continue
+			}
 			locations = append(locations, edge.Location)
 		}
 	}
@@ -579,6 +629,16 @@ func (g *Graph) FindReferencesByID(ctx context.Context, symbolID string, opts ..
 	return locations, nil
 }
 
+// ReferenceCount returns the number of locations FindReferencesByID
+// finds for symbolID without a limit: one per incoming edge.
+func (g *Graph) ReferenceCount(symbolID string) int {
+	node, ok := g.nodes[symbolID]
+	if !ok {
+		return 0
+	}
+	return len(node.Incoming)
+}
+
 // findSymbolsByName returns all symbols matching the given name.
 //
 // GR-06: Uses nodesByName secondary index for O(1) lookup instead of O(V) scan.
@@ -659,13 +719,44 @@ func (g *Graph) FindCalleesByName(ctx context.Context, name string, opts ...Quer
 	return results, nil
 }
 
+// IsImplementationTarget reports whether sym can have implementations.
+//
+// Description:
+//
+//	IT-03 C-3b: Interfaces have EdgeTypeImplements edges from concrete
+//	types; classes and structs have EdgeTypeEmbeds edges from subclasses
+//	and embedding types. Protobuf rpcs have EdgeTypeImplements edges from
+//	the Go methods serving them.
+//
+// Inputs:
+//
+//	sym - The candidate symbol. May be nil.
+//
+// Outputs:
+//
+//	bool - True for interfaces, classes, structs and proto rpcs.
+func IsImplementationTarget(sym *ast.Symbol) bool {
+	if sym == nil {
+		return false
+	}
+	switch sym.Kind {
+	case ast.SymbolKindInterface, ast.SymbolKindClass, ast.SymbolKindStruct:
+		return true
+	case ast.SymbolKindMethod:
+		return sym.Language == "proto"
+	default:
+		return false
+	}
+}
+
 // FindImplementationsByName returns implementations for all types matching the given name.
 //
 // Description:
 //
 //	When multiple types (interfaces, classes, structs) share the same name,
 //	this returns implementers/subclasses for each, keyed by type ID.
-//	Non-type symbols (functions, variables, etc.) are filtered out.
+//	Protobuf rpcs are also accepted and return the Go methods implementing
+//	them. Other non-type symbols (functions, variables, etc.) are filtered out.
 //
 // Inputs:
 //
@@ -686,18 +777,12 @@ func (g *Graph) FindImplementationsByName(ctx context.Context, name string, opts
 			return results, nil
 		}
 
-		// IT-03 C-3b: Accept interfaces, classes, and structs as valid targets.
-		// Interfaces have EdgeTypeImplements edges from concrete types.
-		// Classes/structs have EdgeTypeEmbeds edges from subclasses/embedding types.
-		if node.Symbol != nil {
-			switch node.Symbol.Kind {
-			case ast.SymbolKindInterface, ast.SymbolKindClass, ast.SymbolKindStruct:
-				result, err := g.FindImplementationsByID(ctx, node.ID, opts...)
-				if err != nil {
-					return results, err
-				}
-				results[node.ID] = result
+		if IsImplementationTarget(node.Symbol) {
+			result, err := g.FindImplementationsByID(ctx, node.ID, opts...)
+			if err != nil {
+				return results, err
 			}
+			results[node.ID] = result
 		}
 	}
 
@@ -709,6 +794,8 @@ func (g *Graph) FindImplementationsByName(ctx context.Context, name string, opts
 // Description:
 //
 //	Finds all files that have an IMPORTS edge to nodes in the given package.
+//	Go imports resolved to a project package (see WithGoModules) match
+//	by the package's import path.
 //
 // Inputs:
 //
@@ -740,7 +827,7 @@ func (g *Graph) FindImporters(ctx context.Context, packagePath string, opts ...Q
 			continue
 		}
 
-		if targetNode.Symbol != nil && targetNode.Symbol.Package == packagePath {
+		if targetNode.Symbol != nil && (targetNode.Symbol.Package == packagePath || g.isGoModulePackage(targetNode.Symbol, packagePath)) {
 			// Get the file path from the source
 			sourceNode, ok := g.nodes[edge.FromID]
 			if ok && sourceNode.Symbol != nil {
@@ -835,6 +922,9 @@ func (g *Graph) GetCallGraph(ctx context.Context, symbolID string, opts ...Query
 			if visited[edge.ToID] {
 				continue // Cycle detection
 			}
+			if _, ok := g.edgeTarget(edge, edge.ToID); !ok {
+				continue
+			}
 			visited[edge.ToID] = true
 			result.Edges = append(result.Edges, edge)
 			queue = append(queue, queueItem{edge.ToID, item.depth + 1})
@@ -918,6 +1008,9 @@ func (g *Graph) GetReverseCallGraph(ctx context.Context, symbolID string, opts .
 			if visited[edge.FromID] {
 				continue // Cycle detection
 			}
+			if _, ok := g.edgeTarget(edge, edge.FromID); !ok {
+				continue
+			}
 			visited[edge.FromID] = true
 			result.Edges = append(result.Edges, edge)
 			queue = append(queue, queueItem{edge.FromID, item.depth + 1})
@@ -1014,6 +1107,9 @@ func (g *Graph) GetDependencyTree(ctx context.Context, filePath string, opts ...
 			if visited[edge.ToID] {
 				continue
 			}
+			if _, ok := g.edgeTarget(edge, edge.ToID); !ok {
+				continue
+			}
 			visited[edge.ToID] = true
 			result.Edges = append(result.Edges, edge)
 			queue = append(queue, queueItem{edge.ToID, item.depth + 1})
@@ -1099,6 +1195,9 @@ func (g *Graph) GetTypeHierarchy(ctx context.Context, typeID string, opts ...Que
 			if visited[edge.ToID] {
 				continue
 			}
+			if _, ok := g.edgeTarget(edge, edge.ToID); !ok {
+				continue
+			}
 			visited[edge.ToID] = true
 			result.Edges = append(result.Edges, edge)
 			queue = append(queue, queueItem{edge.ToID, item.depth + 1})
@@ -1112,6 +1211,9 @@ func (g *Graph) GetTypeHierarchy(ctx context.Context, typeID string, opts ...Que
 			if visited[edge.FromID] {
 				continue
 			}
+			if _, ok := g.edgeTarget(edge, edge.FromID); !ok {
+				continue
+			}
 			visited[edge.FromID] = true
 			result.Edges = append(result.Edges, edge)
 			queue = append(queue, queueItem{edge.FromID, item.depth + 1})
@@ -1146,6 +1248,23 @@ func (g *Graph) ShortestPath(ctx context.Context, fromID, toID string) (*PathRes
 		Length: -1,
 	}
 
+	// BFS with parent tracking
+	visited := make(map[string]bool)
+
+	ctx, span := queryTracer.Start(ctx, "graph.Graph.ShortestPath",
+		trace.WithAttributes(
+			telemetry.SensitiveString("from_id", fromID),
+			telemetry.SensitiveString("to_id", toID),
+		),
+	)
+	defer func() {
+		span.SetAttributes(
+			attribute.Int("nodes_visited", len(visited)),
+			attribute.Int("path_length", result.Length),
+		)
+		span.End()
+	}()
+
 	if _, ok := g.nodes[fromID]; !ok {
 		return nil, fmt.Errorf("source node not found: %s", fromID)
 	}
@@ -1160,8 +1279,6 @@ func (g *Graph) ShortestPath(ctx context.Context, fromID, toID string) (*PathRes
 		return result, nil
 	}
 
-	// BFS with parent tracking
-	visited := make(map[string]bool)
 	parent := make(map[string]string)
 	queue := []string{fromID}
 	visited[fromID] = true
@@ -1179,6 +1296,9 @@ func (g *Graph) ShortestPath(ctx context.Context, fromID, toID string) (*PathRes
 			if visited[edge.ToID] {
 				continue
 			}
+			if _, ok := g.edgeTarget(edge, edge.ToID); !ok {
+				continue
+			}
 			visited[edge.ToID] = true
 			parent[edge.ToID] = current
 
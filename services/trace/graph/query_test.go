@@ -16,6 +16,10 @@ import (
 	"testing"
 	"time"
 
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 )
 
@@ -155,7 +159,7 @@ func createTestGraph(t *testing.T) *Graph {
 func TestGraph_Validate(t *testing.T) {
 	t.Run("valid graph returns nil", func(t *testing.T) {
 		g := createTestGraph(t)
-		if err := g.Validate(); err != nil {
+		if err := g.Validate().Err(); err != nil {
 			t.Errorf("Validate() = %v, want nil", err)
 		}
 	})
@@ -172,7 +176,7 @@ func TestGraph_Validate(t *testing.T) {
 			Type:   EdgeTypeCalls,
 		})
 
-		err := g.Validate()
+		err := g.Validate().Err()
 		if err == nil {
 			t.Error("Validate() = nil, want error for dangling edge")
 		}
@@ -858,3 +862,51 @@ func TestQueryResult_Duration(t *testing.T) {
 		t.Errorf("Duration = %v, want >= 0", result.Duration)
 	}
 }
+
+func TestGraph_QuerySpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(tp)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prev)
+		_ = tp.Shutdown(context.Background())
+	})
+
+	ctx := context.Background()
+	g := createTestGraph(t)
+
+	if _, err := g.ShortestPath(ctx, "main.go:10:main", "handlers.go:30:handleAgent"); err != nil {
+		t.Fatalf("ShortestPath() error = %v", err)
+	}
+	// handleAgent is called by setup; helper (standing in for a parent
+	// method) is called twice by run.
+	if _, err := g.FindCallersWithInheritance(ctx, "handlers.go:30:handleAgent", []string{"utils.go:50:helper"}); err != nil {
+		t.Fatalf("FindCallersWithInheritance() error = %v", err)
+	}
+
+	spans := make(map[string]map[string]int64)
+	for _, s := range exporter.GetSpans() {
+		attrs := make(map[string]int64)
+		for _, a := range s.Attributes {
+			attrs[string(a.Key)] = a.Value.AsInt64()
+		}
+		spans[s.Name] = attrs
+	}
+
+	path, ok := spans["graph.Graph.ShortestPath"]
+	if !ok {
+		t.Fatal("ShortestPath span not recorded")
+	}
+	if path["path_length"] != 2 || path["nodes_visited"] < 3 {
+		t.Errorf("ShortestPath attributes = %v", path)
+	}
+
+	callers, ok := spans["graph.Graph.FindCallersWithInheritance"]
+	if !ok {
+		t.Fatal("FindCallersWithInheritance span not recorded")
+	}
+	if callers["result_count"] != 2 || callers["nodes_visited"] != 4 {
+		t.Errorf("FindCallersWithInheritance attributes = %v, want result_count 2, nodes_visited 4", callers)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // RefreshConfig configures refresh behavior.
@@ -236,8 +237,8 @@ func (r *Refresher) RefreshFiles(ctx context.Context, paths []string) (*RefreshR
 		removed, err := clonedGraph.RemoveFile(path)
 		if err != nil {
 			r.logger.Warn("failed to remove file from graph",
-				slog.String("path", path),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("path", path),
+				telemetry.SensitiveLogError("error", err),
 			)
 			continue
 		}
@@ -253,8 +254,8 @@ func (r *Refresher) RefreshFiles(ctx context.Context, paths []string) (*RefreshR
 		added, err := clonedGraph.MergeParseResult(pr)
 		if err != nil {
 			r.logger.Warn("failed to merge parse result",
-				slog.String("path", pr.FilePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("path", pr.FilePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 			continue
 		}
@@ -0,0 +1,285 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// Field access directions, recorded in EdgeProvenance.Detail of
+// PassFieldAccess edges.
+const (
+	FieldAccessRead      = "read"
+	FieldAccessWrite     = "write"
+	FieldAccessReadWrite = "read-write"
+)
+
+// trackedField is a Go struct field that field accesses resolve to.
+type trackedField struct {
+	field *ast.Symbol
+	owner *ast.Symbol
+}
+
+// goFieldIndex maps Go struct field names to the fields of that name.
+type goFieldIndex struct {
+	byName map[string][]trackedField
+
+	// overCap is the number of structs skipped for having too many fields.
+	overCap int
+}
+
+// newGoFieldIndex indexes the fields of the Go structs in the build.
+//
+// Description:
+//
+//	Uses every struct in state.symbolsByID, so incremental refreshes
+//	resolve against unchanged files too. Structs with more than maxFields
+//	fields are skipped. Candidates are sorted by field ID so resolution
+//	does not depend on map order.
+func newGoFieldIndex(state *buildState, maxFields int) *goFieldIndex {
+	idx := &goFieldIndex{byName: make(map[string][]trackedField)}
+	for _, sym := range state.symbolsByID {
+		if sym == nil || sym.Kind != ast.SymbolKindStruct || sym.Language != "go" {
+			continue
+		}
+		fields := 0
+		for _, child := range sym.Children {
+			if child != nil && child.Kind == ast.SymbolKindField {
+				fields++
+			}
+		}
+		if fields > maxFields {
+			idx.overCap++
+			continue
+		}
+		for _, child := range sym.Children {
+			if child == nil || child.Kind != ast.SymbolKindField {
+				continue
+			}
+			if _, exists := state.graph.GetNode(child.ID); !exists {
+				continue
+			}
+			idx.byName[child.Name] = append(idx.byName[child.Name], trackedField{field: child, owner: sym})
+		}
+	}
+	for _, fields := range idx.byName {
+		sort.Slice(fields, func(i, j int) bool { return fields[i].field.ID < fields[j].field.ID })
+	}
+	return idx
+}
+
+// resolve finds the field an access of fn refers to.
+//
+// Description:
+//
+//	With a receiver type, the candidates are narrowed to structs of that
+//	name, preferring the package the type is qualified with or, for an
+//	unqualified type, fn's package. Without one, the access resolves only
+//	if a single tracked field has the name.
+//
+// Outputs:
+//
+//	*ast.Symbol - The field, or nil if none or ambiguous.
+//	bool - True if the receiver type was used.
+func (idx *goFieldIndex) resolve(fn *ast.Symbol, access ast.FieldAccess) (*ast.Symbol, bool) {
+	candidates := idx.byName[access.Field]
+	if len(candidates) == 0 {
+		return nil, false
+	}
+	if access.ReceiverType == "" {
+		if len(candidates) == 1 {
+			return candidates[0].field, false
+		}
+		return nil, false
+	}
+
+	pkg, typeName := fn.Package, access.ReceiverType
+	if dot := strings.LastIndex(typeName, "."); dot >= 0 {
+		pkg, typeName = typeName[:dot], typeName[dot+1:]
+	}
+	var named, samePkg []*ast.Symbol
+	for _, c := range candidates {
+		if c.owner.Name != typeName {
+			continue
+		}
+		named = append(named, c.field)
+		if c.owner.Package == pkg {
+			samePkg = append(samePkg, c.field)
+		}
+	}
+	switch {
+	case len(samePkg) == 1:
+		return samePkg[0], true
+	case len(named) == 1:
+		return named[0], true
+	}
+	return nil, false
+}
+
+// resolveFieldAccessEdges links Go functions to the struct fields they
+// read and write.
+//
+// Description:
+//
+//	Creates one EdgeTypeReferences edge per (function, field) from the
+//	ast.FieldAccess records of Go functions and methods. The edge's
+//	provenance Detail is FieldAccessRead, FieldAccessWrite or
+//	FieldAccessReadWrite, and its location is the first access.
+//	Accesses whose receiver type is known resolve with ConfidenceHigh;
+//	accesses resolved by a unique field name alone with ConfidenceMedium.
+//	Ambiguous accesses are counted and skipped.
+//
+//	Does nothing when BuilderOptions.MaxTrackedStructFields is not
+//	positive.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and tracing.
+//	state - Build state with the graph and symbol indexes.
+//	results - The parse results whose functions are resolved.
+//
+// Limitations:
+//   - No type checking: field names and declared variable types only.
+//     Fields of embedded structs accessed through the outer struct
+//     resolve only by unique name.
+//   - Structs with more than MaxTrackedStructFields fields are skipped.
+//
+// Thread Safety: Runs on the build goroutine after edge extraction.
+func (b *Builder) resolveFieldAccessEdges(ctx context.Context, state *buildState, results []*ast.ParseResult) {
+	if b.options.MaxTrackedStructFields <= 0 {
+		return
+	}
+	_, span := tracer.Start(ctx, "GraphBuilder.resolveFieldAccessEdges")
+	defer span.End()
+
+	hasAccesses := false
+	for _, r := range results {
+		if r != nil && r.Language == "go" {
+			hasAccesses = true
+			break
+		}
+	}
+	if !hasAccesses {
+		return
+	}
+
+	idx := newGoFieldIndex(state, b.options.MaxTrackedStructFields)
+	stateStats(state).StructsOverFieldCap += idx.overCap
+	if len(idx.byName) == 0 {
+		return
+	}
+
+	type fieldUse struct {
+		field       *ast.Symbol
+		read, write bool
+		typed       bool
+		loc         ast.Location
+	}
+
+	resolved, unresolved := 0, 0
+	var visit func(sym *ast.Symbol)
+	visit = func(sym *ast.Symbol) {
+		if sym == nil {
+			return
+		}
+		for _, child := range sym.Children {
+			visit(child)
+		}
+		if len(sym.FieldAccesses) == 0 {
+			return
+		}
+		if _, exists := state.graph.GetNode(sym.ID); !exists {
+			return
+		}
+
+		var uses []*fieldUse
+		byField := make(map[string]*fieldUse)
+		for _, access := range sym.FieldAccesses {
+			field, typed := idx.resolve(sym, access)
+			if field == nil {
+				unresolved++
+				continue
+			}
+			use := byField[field.ID]
+			if use == nil {
+				use = &fieldUse{field: field, loc: access.Location}
+				byField[field.ID] = use
+				uses = append(uses, use)
+			}
+			use.read = use.read || !access.Write
+			use.write = use.write || access.Write
+			use.typed = use.typed || typed
+		}
+
+		for _, use := range uses {
+			prov := EdgeProvenance{Pass: PassFieldAccess, Confidence: ConfidenceMedium, Detail: FieldAccessRead}
+			if use.typed {
+				prov.Confidence = ConfidenceHigh
+			}
+			switch {
+			case use.read && use.write:
+				prov.Detail = FieldAccessReadWrite
+			case use.write:
+				prov.Detail = FieldAccessWrite
+			}
+			if err := stateAddEdge(state, sym.ID, use.field.ID, EdgeTypeReferences, use.loc, prov); err != nil {
+				if strings.Contains(err.Error(), "already exists") {
+					continue
+				}
+				stateAddEdgeError(state, EdgeError{
+					FromID:   sym.ID,
+					ToID:     use.field.ID,
+					EdgeType: EdgeTypeReferences,
+					Err:      fmt.Errorf("field access edge: %w", err),
+				})
+				continue
+			}
+			stateStats(state).EdgesCreated++
+			stateStats(state).FieldAccessEdgesResolved++
+			resolved++
+		}
+	}
+
+	for _, r := range results {
+		if ctx.Err() != nil {
+			slog.Debug("context cancelled during field access resolution")
+			break
+		}
+		if r == nil || r.Language != "go" {
+			continue
+		}
+		for _, sym := range r.Symbols {
+			visit(sym)
+		}
+	}
+	stateStats(state).FieldAccessesUnresolved += unresolved
+
+	span.SetAttributes(
+		attribute.Int("resolved", resolved),
+		attribute.Int("unresolved", unresolved),
+		attribute.Int("structs_over_cap", idx.overCap),
+	)
+	if resolved > 0 {
+		slog.Debug("field access resolution complete",
+			slog.Int("edges_created", resolved),
+			slog.Int("unresolved", unresolved),
+			slog.Int("structs_over_cap", idx.overCap),
+		)
+	}
+}
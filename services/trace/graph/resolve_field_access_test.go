@@ -0,0 +1,152 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+const fieldAccessTestGo = `package orders
+
+type Order struct {
+	Status string
+	Total  int
+}
+
+type Invoice struct {
+	Status string
+}
+
+func (o *Order) Cancel() {
+	o.Status = "cancelled"
+}
+
+func Describe(o Order) string {
+	return o.Status
+}
+
+func (o *Order) Double() {
+	o.Total = o.Total * 2
+}
+
+func Untyped() int {
+	return lookup().Total
+}
+
+func Ambiguous() string {
+	return lookup().Status
+}
+`
+
+// buildFieldAccessTestGraph parses the test file and builds a graph with opts.
+func buildFieldAccessTestGraph(t *testing.T, opts ...BuilderOption) (*BuildResult, []*ast.ParseResult) {
+	t.Helper()
+	ctx := context.Background()
+	parsed, err := ast.NewGoParser().Parse(ctx, []byte(fieldAccessTestGo), "orders/order.go")
+	if err != nil {
+		t.Fatalf("Go parse: %v", err)
+	}
+	results := []*ast.ParseResult{parsed}
+	result, err := NewBuilder(opts...).Build(ctx, results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return result, results
+}
+
+// fieldAccessEdges returns the field access edges into id, keyed by the
+// name of the accessing function.
+func fieldAccessEdges(t *testing.T, g *Graph, id string) map[string]*Edge {
+	t.Helper()
+	node, ok := g.GetNode(id)
+	if !ok {
+		t.Fatalf("node %s not in graph", id)
+	}
+	edges := make(map[string]*Edge)
+	for _, edge := range node.Incoming {
+		if edge.Type != EdgeTypeReferences || edge.Provenance.Pass != PassFieldAccess {
+			continue
+		}
+		if from, ok := g.GetNode(edge.FromID); ok {
+			edges[from.Symbol.Name] = edge
+		}
+	}
+	return edges
+}
+
+func TestResolveFieldAccessEdges(t *testing.T) {
+	result, results := buildFieldAccessTestGraph(t)
+	g := result.Graph
+
+	var orderStatus, orderTotal, invoiceStatus string
+	for _, sym := range results[0].Symbols {
+		for _, child := range sym.Children {
+			switch {
+			case sym.Name == "Order" && child.Name == "Status":
+				orderStatus = child.ID
+			case sym.Name == "Order" && child.Name == "Total":
+				orderTotal = child.ID
+			case sym.Name == "Invoice" && child.Name == "Status":
+				invoiceStatus = child.ID
+			}
+		}
+	}
+	if orderStatus == "" || orderTotal == "" || invoiceStatus == "" {
+		t.Fatalf("fields not found: %q %q %q", orderStatus, orderTotal, invoiceStatus)
+	}
+
+	status := fieldAccessEdges(t, g, orderStatus)
+	if e := status["Cancel"]; e == nil || e.Provenance.Detail != FieldAccessWrite || e.Provenance.Confidence != ConfidenceHigh {
+		t.Errorf("Cancel -> Order.Status = %+v, want high-confidence write", e)
+	}
+	if e := status["Describe"]; e == nil || e.Provenance.Detail != FieldAccessRead {
+		t.Errorf("Describe -> Order.Status = %+v, want read", e)
+	}
+	if _, ok := status["Ambiguous"]; ok {
+		t.Error("untyped Status access is ambiguous between Order and Invoice")
+	}
+	if len(fieldAccessEdges(t, g, invoiceStatus)) != 0 {
+		t.Error("no function accesses Invoice.Status")
+	}
+
+	total := fieldAccessEdges(t, g, orderTotal)
+	if e := total["Double"]; e == nil || e.Provenance.Detail != FieldAccessReadWrite {
+		t.Errorf("Double -> Order.Total = %+v, want read-write", e)
+	}
+	if e := total["Untyped"]; e == nil || e.Provenance.Confidence != ConfidenceMedium {
+		t.Errorf("Untyped -> Order.Total = %+v, want medium confidence by unique name", e)
+	}
+
+	if result.Stats.FieldAccessEdgesResolved != 4 || result.Stats.FieldAccessesUnresolved != 1 {
+		t.Errorf("stats resolved=%d unresolved=%d, want 4 and 1",
+			result.Stats.FieldAccessEdgesResolved, result.Stats.FieldAccessesUnresolved)
+	}
+}
+
+func TestResolveFieldAccessEdges_FieldCap(t *testing.T) {
+	result, _ := buildFieldAccessTestGraph(t, WithMaxTrackedStructFields(1))
+	if result.Stats.StructsOverFieldCap != 1 {
+		t.Errorf("StructsOverFieldCap = %d, want 1 (Order)", result.Stats.StructsOverFieldCap)
+	}
+	// Only Invoice.Status is tracked; the untyped Status access now
+	// resolves to it by unique name.
+	if result.Stats.FieldAccessEdgesResolved != 1 {
+		t.Errorf("FieldAccessEdgesResolved = %d, want 1", result.Stats.FieldAccessEdgesResolved)
+	}
+
+	disabled, _ := buildFieldAccessTestGraph(t, WithMaxTrackedStructFields(0))
+	if disabled.Stats.FieldAccessEdgesResolved != 0 {
+		t.Errorf("FieldAccessEdgesResolved = %d with the pass off", disabled.Stats.FieldAccessEdgesResolved)
+	}
+}
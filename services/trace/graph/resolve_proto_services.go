@@ -0,0 +1,315 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// protoService is a proto service symbol with its rpcs.
+type protoService struct {
+	sym  *ast.Symbol
+	rpcs []*ast.Symbol
+}
+
+// resolveProtoServiceEdges links protobuf definitions to Go code.
+//
+// Description:
+//
+//	For every proto service S (ast.ProtoParser maps services to
+//	SymbolKindInterface and rpcs to SymbolKindMethod):
+//
+//	  1. Each rpc gets EdgeTypeReferences edges to its request and
+//	     response message symbols, resolved by fully-qualified name
+//	     ("acme.v1.GetUserRequest") or relative to the service's package.
+//	  2. Go types implementing S are found, in order of preference:
+//	       - types with an IMPLEMENTS edge to a Go interface named
+//	         "<S>Server" (the protoc-gen-go-grpc server interface), from
+//	         computeInterfaceImplementations;
+//	       - types embedding "Unimplemented<S>Server", for when the
+//	         generated code is not part of the build;
+//	       - types whose method set covers every rpc of S.
+//	     The generated Unimplemented<S>Server and Unsafe<S>Server types
+//	     are never treated as implementations.
+//	  3. Each implementing type gets an EdgeTypeImplements edge to S, and
+//	     each of its methods named after an rpc gets an EdgeTypeImplements
+//	     edge to that rpc.
+//
+//	With these edges find_implementations answers "who implements the
+//	UpdateUser rpc" and "who implements UserService" without special
+//	handling in callers.
+//
+//	Only runs when the build contains proto services.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and tracing.
+//	state - Build state with the graph and symbol indexes.
+//	results - All parse results of the build.
+//
+// Thread Safety: Runs on the build goroutine after interface detection.
+func (b *Builder) resolveProtoServiceEdges(ctx context.Context, state *buildState, results []*ast.ParseResult) {
+	_, span := tracer.Start(ctx, "GraphBuilder.resolveProtoServiceEdges")
+	defer span.End()
+
+	services, messages := collectProtoDefinitions(results)
+	if len(services) == 0 {
+		return
+	}
+
+	resolved, skipped := 0, 0
+	addEdge := func(from *ast.Symbol, toID string, edgeType EdgeType) {
+		if err := stateAddEdge(state, from.ID, toID, edgeType, from.Location(), EdgeProvenance{Pass: PassProtoService, Confidence: ConfidenceMedium}); err != nil {
+			if strings.Contains(err.Error(), "already exists") {
+				return
+			}
+			stateAddEdgeError(state, EdgeError{
+				FromID:   from.ID,
+				ToID:     toID,
+				EdgeType: edgeType,
+				Err:      fmt.Errorf("proto service edge: %w", err),
+			})
+			return
+		}
+		stateStats(state).EdgesCreated++
+		stateStats(state).ProtoServiceEdgesResolved++
+		resolved++
+	}
+
+	// Go methods by package directory, receiver type and name.
+	goMethods := make(map[string]*ast.Symbol)
+	for _, sym := range state.symbolsInIDOrder() {
+		if sym.Kind == ast.SymbolKindMethod && sym.Language == "go" && sym.Receiver != "" {
+			goMethods[goMethodKey(filepath.Dir(sym.FilePath), sym.Receiver, sym.Name)] = sym
+		}
+	}
+
+	for _, svc := range services {
+		if ctx.Err() != nil {
+			slog.Debug("context cancelled during proto service resolution")
+			break
+		}
+
+		for _, rpc := range svc.rpcs {
+			for _, typeName := range []string{rpc.Metadata.ProtoRequestType, rpc.Metadata.ProtoResponseType} {
+				msg := resolveProtoMessage(messages, svc.sym.Package, typeName)
+				if msg == nil {
+					skipped++
+					continue
+				}
+				addEdge(rpc, msg.ID, EdgeTypeReferences)
+			}
+		}
+
+		for _, impl := range b.findProtoServiceImplementations(state, svc) {
+			addEdge(impl, svc.sym.ID, EdgeTypeImplements)
+			dir := filepath.Dir(impl.FilePath)
+			for _, rpc := range svc.rpcs {
+				method := goMethods[goMethodKey(dir, impl.Name, rpc.Name)]
+				if method == nil {
+					skipped++
+					continue
+				}
+				addEdge(method, rpc.ID, EdgeTypeImplements)
+			}
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("services", len(services)),
+		attribute.Int("resolved", resolved),
+		attribute.Int("skipped", skipped),
+	)
+	if resolved > 0 {
+		slog.Debug("proto service resolution complete",
+			slog.Int("services", len(services)),
+			slog.Int("edges_created", resolved),
+			slog.Int("skipped", skipped),
+		)
+	}
+}
+
+// findProtoServiceImplementations returns the Go types implementing svc,
+// using the first strategy described on resolveProtoServiceEdges that
+// finds any.
+func (b *Builder) findProtoServiceImplementations(state *buildState, svc protoService) []*ast.Symbol {
+	name := svc.sym.Name
+	serverIface := name + "Server"
+	generated := map[string]bool{
+		"Unimplemented" + serverIface: true,
+		"Unsafe" + serverIface:        true,
+	}
+
+	var impls []*ast.Symbol
+	seen := make(map[string]bool)
+	add := func(sym *ast.Symbol) {
+		if sym == nil || seen[sym.ID] || generated[sym.Name] || sym.Language != "go" {
+			return
+		}
+		seen[sym.ID] = true
+		impls = append(impls, sym)
+	}
+
+	// 1. Implementers of the generated <S>Server interface.
+	for _, iface := range state.symbolsByName[serverIface] {
+		if iface.Kind != ast.SymbolKindInterface || iface.Language != "go" {
+			continue
+		}
+		node, ok := state.graph.GetNode(iface.ID)
+		if !ok {
+			continue
+		}
+		for _, edge := range node.Incoming {
+			if edge.Type == EdgeTypeImplements {
+				add(state.symbolsByID[edge.FromID])
+			}
+		}
+	}
+	if len(impls) > 0 {
+		return impls
+	}
+
+	// 2. Types embedding Unimplemented<S>Server.
+	goTypes := goTypesWithMetadata(state)
+	for _, sym := range goTypes {
+		embeds := append([]string{sym.Metadata.Extends}, sym.Metadata.Implements...)
+		for _, embedded := range embeds {
+			if goBaseTypeName(embedded) == "Unimplemented"+serverIface {
+				add(sym)
+			}
+		}
+	}
+	if len(impls) > 0 || len(svc.rpcs) == 0 {
+		return impls
+	}
+
+	// 3. Types whose own methods cover every rpc.
+	rpcNames := make(map[string]bool, len(svc.rpcs))
+	for _, rpc := range svc.rpcs {
+		rpcNames[rpc.Name] = true
+	}
+	for _, sym := range goTypes {
+		methods := make(map[string]bool, len(sym.Metadata.Methods))
+		for _, m := range sym.Metadata.Methods {
+			methods[m.Name] = true
+		}
+		if isMethodSuperset(methods, rpcNames) {
+			add(sym)
+		}
+	}
+	return impls
+}
+
+// goTypesWithMetadata returns Go struct and named types that have
+// metadata, in ID order.
+func goTypesWithMetadata(state *buildState) []*ast.Symbol {
+	var types []*ast.Symbol
+	for _, sym := range state.symbolsInIDOrder() {
+		if sym.Language != "go" || sym.Metadata == nil {
+			continue
+		}
+		if sym.Kind == ast.SymbolKindStruct || sym.Kind == ast.SymbolKindType {
+			types = append(types, sym)
+		}
+	}
+	return types
+}
+
+// collectProtoDefinitions returns the proto services of results, in
+// result order, and their messages and enums keyed by fully-qualified
+// name ("acme.v1.User.Address", or "User.Address" without a package).
+func collectProtoDefinitions(results []*ast.ParseResult) ([]protoService, map[string]*ast.Symbol) {
+	var services []protoService
+	messages := make(map[string]*ast.Symbol)
+
+	var collectMessages func(syms []*ast.Symbol, pkg, parent string)
+	collectMessages = func(syms []*ast.Symbol, pkg, parent string) {
+		for _, sym := range syms {
+			if sym == nil || (sym.Kind != ast.SymbolKindStruct && sym.Kind != ast.SymbolKindEnum) {
+				continue
+			}
+			qualified := sym.Name
+			if parent != "" {
+				qualified = parent + "." + sym.Name
+			}
+			key := qualified
+			if pkg != "" {
+				key = pkg + "." + qualified
+			}
+			if _, exists := messages[key]; !exists {
+				messages[key] = sym
+			}
+			collectMessages(sym.Children, pkg, qualified)
+		}
+	}
+
+	for _, r := range results {
+		if r == nil || r.Language != "proto" {
+			continue
+		}
+		collectMessages(r.Symbols, r.Package, "")
+		for _, sym := range r.Symbols {
+			if sym == nil || sym.Kind != ast.SymbolKindInterface {
+				continue
+			}
+			svc := protoService{sym: sym}
+			for _, child := range sym.Children {
+				if child.Kind == ast.SymbolKindMethod && child.Metadata != nil {
+					svc.rpcs = append(svc.rpcs, child)
+				}
+			}
+			services = append(services, svc)
+		}
+	}
+	return services, messages
+}
+
+// resolveProtoMessage resolves a message type name as written in an rpc
+// of package pkg: fully-qualified first (a leading dot forces this), then
+// relative to pkg.
+func resolveProtoMessage(messages map[string]*ast.Symbol, pkg, typeName string) *ast.Symbol {
+	if typeName == "" {
+		return nil
+	}
+	if strings.HasPrefix(typeName, ".") {
+		return messages[typeName[1:]]
+	}
+	if msg, ok := messages[typeName]; ok {
+		return msg
+	}
+	if pkg != "" {
+		return messages[pkg+"."+typeName]
+	}
+	return nil
+}
+
+// goMethodKey keys a Go method by package directory, receiver type and name.
+func goMethodKey(dir, receiver, name string) string {
+	return dir + "\x00" + receiver + "\x00" + name
+}
+
+// goBaseTypeName strips pointer and package qualifiers from a Go type:
+// "*pb.UnimplementedUserServiceServer" -> "UnimplementedUserServiceServer".
+func goBaseTypeName(typeName string) string {
+	typeName = strings.TrimLeft(strings.TrimSpace(typeName), "*")
+	if i := strings.LastIndex(typeName, "."); i >= 0 {
+		typeName = typeName[i+1:]
+	}
+	return typeName
+}
@@ -0,0 +1,240 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+const protoServicesTestProto = `syntax = "proto3";
+package acme.users.v1;
+
+message User { string id = 1; }
+message GetUserRequest { string id = 1; }
+message UpdateUserRequest { User user = 1; }
+
+service UserService {
+  rpc GetUser(GetUserRequest) returns (User);
+  rpc UpdateUser(.acme.users.v1.UpdateUserRequest) returns (User);
+}
+`
+
+// protoServicesTestGenerated mirrors the server half of protoc-gen-go-grpc
+// output.
+const protoServicesTestGenerated = `package usersv1
+
+type UserServiceServer interface {
+	GetUser(ctx context.Context, req *GetUserRequest) (*User, error)
+	UpdateUser(ctx context.Context, req *UpdateUserRequest) (*User, error)
+	mustEmbedUnimplementedUserServiceServer()
+}
+
+type UnimplementedUserServiceServer struct{}
+
+func (UnimplementedUserServiceServer) GetUser(ctx context.Context, req *GetUserRequest) (*User, error) {
+	return nil, nil
+}
+
+func (UnimplementedUserServiceServer) UpdateUser(ctx context.Context, req *UpdateUserRequest) (*User, error) {
+	return nil, nil
+}
+
+func (UnimplementedUserServiceServer) mustEmbedUnimplementedUserServiceServer() {}
+`
+
+const protoServicesTestServer = `package usersv1
+
+type Server struct {
+	UnimplementedUserServiceServer
+}
+
+func (s *Server) UpdateUser(ctx context.Context, req *UpdateUserRequest) (*User, error) {
+	return req.User, nil
+}
+`
+
+// buildProtoServicesTestGraph parses the given files with the real parsers
+// and builds a graph from them.
+func buildProtoServicesTestGraph(t *testing.T, files map[string]string) *BuildResult {
+	t.Helper()
+	ctx := context.Background()
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var results []*ast.ParseResult
+	for _, path := range paths {
+		var parser ast.Parser = ast.NewGoParser()
+		if strings.HasSuffix(path, ".proto") {
+			parser = ast.NewProtoParser()
+		}
+		r, err := parser.Parse(ctx, []byte(files[path]), path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		results = append(results, r)
+	}
+
+	result, err := NewBuilder().Build(ctx, results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return result
+}
+
+// protoImplementers returns "kind:name@file" for each implementation of
+// the symbols named name.
+func protoImplementers(t *testing.T, g *Graph, name string) []string {
+	t.Helper()
+	results, err := g.FindImplementationsByName(context.Background(), name)
+	if err != nil {
+		t.Fatalf("FindImplementationsByName(%s): %v", name, err)
+	}
+	var impls []string
+	for _, r := range results {
+		for _, sym := range r.Symbols {
+			impls = append(impls, sym.Kind.String()+":"+sym.Name+"@"+sym.FilePath)
+		}
+	}
+	sort.Strings(impls)
+	return impls
+}
+
+func TestResolveProtoServiceEdges_GeneratedServerInterface(t *testing.T) {
+	result := buildProtoServicesTestGraph(t, map[string]string{
+		"api/users.proto":              protoServicesTestProto,
+		"gen/usersv1/users_grpc.pb.go": protoServicesTestGenerated,
+		"gen/usersv1/server.go":        protoServicesTestServer,
+	})
+	g := result.Graph
+
+	// The proto service is implemented by Server, not the generated stub.
+	services := protoImplementers(t, g, "UserService")
+	if len(services) != 1 || services[0] != "struct:Server@gen/usersv1/server.go" {
+		t.Errorf("UserService implementers = %v, want [struct:Server@gen/usersv1/server.go]", services)
+	}
+
+	// Only the rpc Server overrides maps to a Server method.
+	if got := protoImplementers(t, g, "UpdateUser"); len(got) != 1 || got[0] != "method:UpdateUser@gen/usersv1/server.go" {
+		t.Errorf("UpdateUser implementers = %v, want the Server method", got)
+	}
+	if got := protoImplementers(t, g, "GetUser"); len(got) != 0 {
+		t.Errorf("GetUser implementers = %v, want none (served by the embedded stub)", got)
+	}
+
+	if result.Stats.ProtoServiceEdgesResolved == 0 {
+		t.Error("expected ProtoServiceEdgesResolved > 0")
+	}
+}
+
+func TestResolveProtoServiceEdges_RPCMessageReferences(t *testing.T) {
+	result := buildProtoServicesTestGraph(t, map[string]string{
+		"api/users.proto": protoServicesTestProto,
+	})
+	g := result.Graph
+
+	referenced := make(map[string][]string)
+	for _, node := range g.GetNodesByName("UpdateUser") {
+		for _, edge := range node.Outgoing {
+			if edge.Type != EdgeTypeReferences {
+				continue
+			}
+			if to, ok := g.GetNode(edge.ToID); ok {
+				referenced[node.Symbol.Name] = append(referenced[node.Symbol.Name], to.Symbol.Name)
+			}
+		}
+	}
+	got := referenced["UpdateUser"]
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != "UpdateUserRequest" || got[1] != "User" {
+		t.Errorf("UpdateUser references = %v, want [UpdateUserRequest User]", got)
+	}
+}
+
+func TestResolveProtoServiceEdges_WithoutGeneratedCode(t *testing.T) {
+	// Generated code excluded from the build: Server embeds the stub by
+	// name and Full implements every rpc itself.
+	result := buildProtoServicesTestGraph(t, map[string]string{
+		"api/users.proto": protoServicesTestProto,
+		"server/embed.go": `package server
+
+type Server struct {
+	usersv1.UnimplementedUserServiceServer
+}
+
+func (s *Server) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.User, error) {
+	return nil, nil
+}
+`,
+		"other/full.go": `package other
+
+type Full struct{}
+
+func (f *Full) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.User, error) {
+	return nil, nil
+}
+
+func (f *Full) UpdateUser(ctx context.Context, req *usersv1.UpdateUserRequest) (*usersv1.User, error) {
+	return nil, nil
+}
+`,
+	})
+	g := result.Graph
+
+	// Embedding wins over method-set matching, so Full is only a fallback.
+	services := protoImplementers(t, g, "UserService")
+	if len(services) != 1 || services[0] != "struct:Server@server/embed.go" {
+		t.Errorf("UserService implementers = %v, want [struct:Server@server/embed.go]", services)
+	}
+	if got := protoImplementers(t, g, "GetUser"); len(got) != 1 || got[0] != "method:GetUser@server/embed.go" {
+		t.Errorf("GetUser implementers = %v", got)
+	}
+
+	result = buildProtoServicesTestGraph(t, map[string]string{
+		"api/users.proto": protoServicesTestProto,
+		"other/full.go": `package other
+
+type Full struct{}
+
+func (f *Full) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.User, error) {
+	return nil, nil
+}
+
+func (f *Full) UpdateUser(ctx context.Context, req *usersv1.UpdateUserRequest) (*usersv1.User, error) {
+	return nil, nil
+}
+`,
+	})
+	if got := protoImplementers(t, result.Graph, "UserService"); len(got) != 1 || got[0] != "struct:Full@other/full.go" {
+		t.Errorf("UserService implementers = %v, want [struct:Full@other/full.go]", got)
+	}
+}
+
+func TestGoBaseTypeName(t *testing.T) {
+	tests := map[string]string{
+		"*pb.UnimplementedUserServiceServer": "UnimplementedUserServiceServer",
+		"UnimplementedUserServiceServer":     "UnimplementedUserServiceServer",
+		" *Server":                           "Server",
+	}
+	for in, want := range tests {
+		if got := goBaseTypeName(in); got != want {
+			t.Errorf("goBaseTypeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
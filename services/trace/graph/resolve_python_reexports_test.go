@@ -0,0 +1,254 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+// =============================================================================
+// Python __init__.py Re-export and Module Alias Resolution Tests
+// =============================================================================
+//
+// These tests use a pandas-like layout where the public API is re-exported
+// through the package __init__.py:
+//
+//	pandas/__init__.py               from .core.frame import DataFrame
+//	                                 from .core.reshape.merge import merge
+//	pandas/core/frame.py             class DataFrame (with a DataFrame.merge method)
+//	pandas/core/reshape/merge.py     def merge, def merge_asof
+//
+// Test scenarios:
+//   - absolutePythonModulePath resolves relative paths against the package
+//   - "from pandas import DataFrame" references the defining module's class
+//   - "from pandas import merge; merge()" calls the module function, not DataFrame.merge
+//   - "import pandas as pd; pd.merge()" resolves through the re-export
+//   - "import pandas; pandas.core.reshape.merge.merge_asof()" resolves the attribute chain
+//   - Unknown receivers are left to the existing strategies
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// =============================================================================
+// Helpers
+// =============================================================================
+
+// makePythonFunction creates a module-level function symbol with optional call sites.
+func makePythonFunction(filePath string, line int, name string, calls ...ast.CallSite) *ast.Symbol {
+	return &ast.Symbol{
+		ID:        ast.GenerateID(filePath, line, name),
+		Name:      name,
+		Kind:      ast.SymbolKindFunction,
+		FilePath:  filePath,
+		Language:  "python",
+		Exported:  true,
+		StartLine: line,
+		EndLine:   line + 5,
+		Calls:     calls,
+	}
+}
+
+// pandasLikeResults builds the re-exporting package layout shared by these tests.
+// Returns the parse results plus the symbols tests assert against.
+func pandasLikeResults() (results []*ast.ParseResult, dataFrame, dfMerge, merge, mergeAsof *ast.Symbol) {
+	dfMerge = &ast.Symbol{
+		ID:        ast.GenerateID("pandas/core/frame.py", 40, "merge"),
+		Name:      "merge",
+		Kind:      ast.SymbolKindMethod,
+		FilePath:  "pandas/core/frame.py",
+		Language:  "python",
+		Exported:  true,
+		StartLine: 40,
+		EndLine:   45,
+	}
+	dataFrame = &ast.Symbol{
+		ID:        ast.GenerateID("pandas/core/frame.py", 10, "DataFrame"),
+		Name:      "DataFrame",
+		Kind:      ast.SymbolKindClass,
+		FilePath:  "pandas/core/frame.py",
+		Language:  "python",
+		Exported:  true,
+		StartLine: 10,
+		EndLine:   50,
+		Children:  []*ast.Symbol{dfMerge},
+	}
+	merge = makePythonFunction("pandas/core/reshape/merge.py", 20, "merge")
+	mergeAsof = makePythonFunction("pandas/core/reshape/merge.py", 60, "merge_asof")
+
+	results = []*ast.ParseResult{
+		{
+			FilePath: "pandas/__init__.py",
+			Language: "python",
+			Symbols:  []*ast.Symbol{makePythonPackageSymbol("pandas/__init__.py", "pandas")},
+			Imports: []ast.Import{
+				makeImport(".core.frame", []string{"DataFrame"}, true, false),
+				makeImport(".core.reshape.merge", []string{"merge"}, true, false),
+			},
+		},
+		{
+			FilePath: "pandas/core/frame.py",
+			Language: "python",
+			Symbols:  []*ast.Symbol{makePythonPackageSymbol("pandas/core/frame.py", "pandas.core.frame"), dataFrame},
+		},
+		{
+			FilePath: "pandas/core/reshape/merge.py",
+			Language: "python",
+			Symbols: []*ast.Symbol{
+				makePythonPackageSymbol("pandas/core/reshape/merge.py", "pandas.core.reshape.merge"),
+				merge, mergeAsof,
+			},
+		},
+	}
+	return results, dataFrame, dfMerge, merge, mergeAsof
+}
+
+// =============================================================================
+// Tests
+// =============================================================================
+
+func TestAbsolutePythonModulePath(t *testing.T) {
+	cases := []struct {
+		fromFile, modulePath, want string
+	}{
+		{"pandas/__init__.py", ".core.frame", "pandas.core.frame"},
+		{"pandas/io/__init__.py", "..util", "pandas.util"},
+		{"pandas/__init__.py", "numpy", "numpy"},
+		{"__init__.py", ".impl", "impl"},
+		{"pandas/__init__.py", ".", ""},
+		{"pandas/__init__.py", "...toofar", ""},
+	}
+	for _, tc := range cases {
+		if got := absolutePythonModulePath(tc.fromFile, tc.modulePath); got != tc.want {
+			t.Errorf("absolutePythonModulePath(%q, %q) = %q, want %q", tc.fromFile, tc.modulePath, got, tc.want)
+		}
+	}
+}
+
+// TestResolveNamedImportEdges_ReExport verifies that "from pandas import DataFrame"
+// references the class defined in pandas/core/frame.py.
+func TestResolveNamedImportEdges_ReExport(t *testing.T) {
+	results, dataFrame, _, _, _ := pandasLikeResults()
+	userPkg := makePythonPackageSymbol("analysis/report.py", "analysis.report")
+	results = append(results, &ast.ParseResult{
+		FilePath: "analysis/report.py",
+		Language: "python",
+		Symbols:  []*ast.Symbol{userPkg},
+		Imports:  []ast.Import{makeImport("pandas", []string{"DataFrame"}, false, false)},
+	})
+
+	result, err := NewBuilder().Build(context.Background(), results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if !hasEdge(result.Graph, userPkg.ID, dataFrame.ID, EdgeTypeReferences) {
+		t.Error("expected REFERENCES edge from analysis/report.py to pandas.core.frame.DataFrame")
+	}
+	if result.Stats.ReExportsResolved == 0 {
+		t.Error("expected ReExportsResolved > 0")
+	}
+}
+
+// TestResolveCallTarget_ReExportedNamedImport verifies that a bare call to a
+// re-exported name resolves to the defining module's function rather than a
+// same-named method elsewhere.
+func TestResolveCallTarget_ReExportedNamedImport(t *testing.T) {
+	results, _, dfMerge, merge, _ := pandasLikeResults()
+	run := makePythonFunction("analysis/report.py", 10, "run", ast.CallSite{
+		Target:   "merge",
+		Location: ast.Location{FilePath: "analysis/report.py", StartLine: 12, EndLine: 12},
+	})
+	results = append(results, &ast.ParseResult{
+		FilePath: "analysis/report.py",
+		Language: "python",
+		Symbols:  []*ast.Symbol{makePythonPackageSymbol("analysis/report.py", "analysis.report"), run},
+		Imports:  []ast.Import{makeImport("pandas", []string{"merge"}, false, false)},
+	})
+
+	result, err := NewBuilder().Build(context.Background(), results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if !hasEdge(result.Graph, run.ID, merge.ID, EdgeTypeCalls) {
+		t.Error("expected CALLS edge from run to pandas.core.reshape.merge.merge")
+	}
+	if hasEdge(result.Graph, run.ID, dfMerge.ID, EdgeTypeCalls) {
+		t.Error("run must not call DataFrame.merge")
+	}
+}
+
+// TestResolveCallTarget_ModuleAlias verifies attribute-chain calls through
+// "import pandas as pd" and "import pandas".
+func TestResolveCallTarget_ModuleAlias(t *testing.T) {
+	results, _, dfMerge, merge, mergeAsof := pandasLikeResults()
+	run := makePythonFunction("analysis/report.py", 10, "run",
+		ast.CallSite{
+			Target:   "merge",
+			Receiver: "pd",
+			IsMethod: true,
+			Location: ast.Location{FilePath: "analysis/report.py", StartLine: 12, EndLine: 12},
+		},
+		ast.CallSite{
+			Target:   "merge_asof",
+			Receiver: "pandas.core.reshape.merge",
+			IsMethod: true,
+			Location: ast.Location{FilePath: "analysis/report.py", StartLine: 13, EndLine: 13},
+		},
+	)
+	results = append(results, &ast.ParseResult{
+		FilePath: "analysis/report.py",
+		Language: "python",
+		Symbols:  []*ast.Symbol{makePythonPackageSymbol("analysis/report.py", "analysis.report"), run},
+		Imports: []ast.Import{
+			{Path: "pandas", Alias: "pd"},
+			{Path: "pandas.core.reshape.merge"},
+		},
+	})
+
+	result, err := NewBuilder().Build(context.Background(), results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if !hasEdge(result.Graph, run.ID, merge.ID, EdgeTypeCalls) {
+		t.Error("expected pd.merge() to resolve to pandas.core.reshape.merge.merge via re-export")
+	}
+	if hasEdge(result.Graph, run.ID, dfMerge.ID, EdgeTypeCalls) {
+		t.Error("pd.merge() must not resolve to DataFrame.merge")
+	}
+	if !hasEdge(result.Graph, run.ID, mergeAsof.ID, EdgeTypeCalls) {
+		t.Error("expected pandas.core.reshape.merge.merge_asof() to resolve via the attribute chain")
+	}
+	if result.Stats.ReExportsResolved == 0 {
+		t.Error("expected ReExportsResolved > 0")
+	}
+}
+
+func TestIsDottedIdentifier(t *testing.T) {
+	cases := map[string]bool{
+		"pd":              true,
+		"pd.core.frame":   true,
+		"_private.mod2":   true,
+		"":                false,
+		"pd.":             false,
+		"df[0]":           false,
+		"get_frame()":     false,
+		"pd .core":        false,
+		"2fast.furious":   false,
+		"self.client.api": true,
+	}
+	for in, want := range cases {
+		if got := isDottedIdentifier(in); got != want {
+			t.Errorf("isDottedIdentifier(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
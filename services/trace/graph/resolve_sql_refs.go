@@ -0,0 +1,262 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// sqlSchemaIndex holds the SQL table, view and column symbols of a build,
+// keyed by lower-cased name (unquoted SQL identifiers are case-insensitive).
+type sqlSchemaIndex struct {
+	tables        map[string][]*ast.Symbol
+	columns       map[string][]*ast.Symbol // column name -> columns of any table
+	tableOfColumn map[string]string        // column symbol ID -> lower-cased table name
+}
+
+// newSQLSchemaIndex indexes the SQL symbols in results.
+func newSQLSchemaIndex(results []*ast.ParseResult) *sqlSchemaIndex {
+	idx := &sqlSchemaIndex{
+		tables:        make(map[string][]*ast.Symbol),
+		columns:       make(map[string][]*ast.Symbol),
+		tableOfColumn: make(map[string]string),
+	}
+	for _, r := range results {
+		if r == nil || r.Language != "sql" {
+			continue
+		}
+		for _, sym := range r.Symbols {
+			if sym == nil {
+				continue
+			}
+			switch sym.Kind {
+			case ast.SymbolKindTable, ast.SymbolKindView:
+				key := strings.ToLower(sym.Name)
+				idx.tables[key] = append(idx.tables[key], sym)
+			case ast.SymbolKindColumn:
+				if sym.Metadata == nil || sym.Metadata.ParentName == "" {
+					continue
+				}
+				key := strings.ToLower(sym.Name)
+				idx.columns[key] = append(idx.columns[key], sym)
+				idx.tableOfColumn[sym.ID] = strings.ToLower(sym.Metadata.ParentName)
+			}
+		}
+	}
+	return idx
+}
+
+// resolveSQLReferenceEdges links code symbols to SQL schema symbols.
+//
+// Description:
+//
+//	Creates EdgeTypeReferences edges from:
+//	  - functions and methods to the tables and views named in their SQL
+//	    query string literals (ast.SymbolMetadata.SQLTableRefs);
+//	  - struct fields tagged db:"col" or gorm:"column:col"
+//	    (ast.SymbolMetadata.DBColumn) to the matching SQL column;
+//	  - structs to the table their tagged fields were matched against.
+//
+//	A tagged field resolves to a column of the table named after its
+//	struct (User -> user, users; OrderItem -> order_item, order_items). If
+//	no such table has the column, the field resolves only when exactly one
+//	table in the project has it; ambiguous columns such as "id" are
+//	skipped rather than linked to every table.
+//
+//	Only runs when the build contains SQL symbols.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and tracing.
+//	state - Build state with the graph and symbol indexes.
+//	results - All parse results of the build.
+//
+// Thread Safety: Runs on the build goroutine after edge extraction.
+func (b *Builder) resolveSQLReferenceEdges(ctx context.Context, state *buildState, results []*ast.ParseResult) {
+	_, span := tracer.Start(ctx, "GraphBuilder.resolveSQLReferenceEdges")
+	defer span.End()
+
+	schema := newSQLSchemaIndex(results)
+	if len(schema.tables) == 0 {
+		return
+	}
+
+	resolved, skipped := 0, 0
+	addEdge := func(from *ast.Symbol, toID string) {
+		if _, exists := state.graph.GetNode(from.ID); !exists {
+			skipped++
+			return
+		}
+		loc := ast.Location{
+			FilePath:  from.FilePath,
+			StartLine: from.StartLine,
+			EndLine:   from.EndLine,
+		}
+		if err := stateAddEdge(state, from.ID, toID, EdgeTypeReferences, loc, EdgeProvenance{Pass: PassSQLRef, Confidence: ConfidenceMedium}); err != nil {
+			if strings.Contains(err.Error(), "already exists") {
+				return
+			}
+			stateAddEdgeError(state, EdgeError{
+				FromID:   from.ID,
+				ToID:     toID,
+				EdgeType: EdgeTypeReferences,
+				Err:      fmt.Errorf("sql reference edge: %w", err),
+			})
+			return
+		}
+		stateStats(state).EdgesCreated++
+		stateStats(state).SQLReferenceEdgesResolved++
+		resolved++
+	}
+
+	var visit func(sym *ast.Symbol)
+	visit = func(sym *ast.Symbol) {
+		if sym == nil {
+			return
+		}
+		if sym.Metadata != nil {
+			for _, ref := range sym.Metadata.SQLTableRefs {
+				tables := schema.tables[strings.ToLower(ref)]
+				if len(tables) == 0 {
+					skipped++
+				}
+				for _, table := range tables {
+					addEdge(sym, table.ID)
+				}
+			}
+			if sym.Kind == ast.SymbolKindField && sym.Metadata.DBColumn != "" {
+				parent := state.symbolsByID[state.symbolParent[sym.ID]]
+				column, table := schema.resolveColumn(parent, sym.Metadata.DBColumn)
+				if column == nil {
+					skipped++
+				} else {
+					addEdge(sym, column.ID)
+					if parent != nil && table != nil {
+						addEdge(parent, table.ID)
+					}
+				}
+			}
+		}
+		for _, child := range sym.Children {
+			visit(child)
+		}
+	}
+
+	for _, r := range results {
+		if ctx.Err() != nil {
+			slog.Debug("context cancelled during SQL reference resolution")
+			break
+		}
+		if r == nil || r.Language == "sql" {
+			continue
+		}
+		for _, sym := range r.Symbols {
+			visit(sym)
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("resolved", resolved),
+		attribute.Int("skipped", skipped),
+	)
+	if resolved > 0 {
+		slog.Debug("SQL reference resolution complete",
+			slog.Int("edges_created", resolved),
+			slog.Int("skipped", skipped),
+		)
+	}
+}
+
+// resolveColumn finds the SQL column a db-tagged field of owner maps to.
+//
+// Outputs:
+//
+//	*ast.Symbol - The column, or nil if none or ambiguous.
+//	*ast.Symbol - The table matched by owner's name, or nil if the column
+//	  was resolved by uniqueness alone.
+func (idx *sqlSchemaIndex) resolveColumn(owner *ast.Symbol, column string) (*ast.Symbol, *ast.Symbol) {
+	candidates := idx.columns[strings.ToLower(column)]
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	if owner != nil {
+		for _, tableName := range sqlTableNamesFor(owner.Name) {
+			for _, col := range candidates {
+				if idx.tableOfColumn[col.ID] != tableName {
+					continue
+				}
+				var table *ast.Symbol
+				if tables := idx.tables[tableName]; len(tables) > 0 {
+					table = tables[0]
+				}
+				return col, table
+			}
+		}
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], nil
+	}
+	return nil, nil
+}
+
+// sqlTableNamesFor returns the table names conventionally used for a Go
+// type, most specific first: snake_case singular and plural, then the
+// lower-cased name singular and plural.
+//
+// Example: "OrderItem" -> order_item, order_items, orderitem, orderitems.
+func sqlTableNamesFor(typeName string) []string {
+	var snake strings.Builder
+	runes := []rune(typeName)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && !unicode.IsUpper(runes[i-1])
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				snake.WriteByte('_')
+			}
+		}
+		snake.WriteRune(unicode.ToLower(r))
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, base := range []string{snake.String(), strings.ToLower(typeName)} {
+		for _, name := range []string{base, sqlPlural(base)} {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// sqlPlural returns a simple English plural of a lower-case table name.
+func sqlPlural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "y") && len(name) > 1 && !strings.ContainsRune("aeiou", rune(name[len(name)-2])):
+		return name[:len(name)-1] + "ies"
+	case strings.HasSuffix(name, "s"), strings.HasSuffix(name, "x"), strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"):
+		return name + "es"
+	default:
+		return name + "s"
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+const sqlRefsTestMigration = `-- +goose Up
+CREATE TABLE users (
+    id BIGINT PRIMARY KEY,
+    email TEXT NOT NULL
+);
+
+CREATE TABLE orders (
+    id BIGINT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    total_cents BIGINT NOT NULL
+);
+`
+
+const sqlRefsTestGo = "package store\n" + `
+type User struct {
+	ID    int64  ` + "`db:\"id\"`" + `
+	Email string ` + "`db:\"email\"`" + `
+}
+
+type Receipt struct {
+	ID    int64 ` + "`db:\"id\"`" + `
+	Total int64 ` + "`gorm:\"column:total_cents\"`" + `
+}
+
+func FindUser(db DB, id int64) (*User, error) {
+	row := db.QueryRow("SELECT id, email FROM users WHERE id = $1", id)
+	return scanUser(row)
+}
+
+func CountOrders(db DB) int {
+	return db.Count(` + "`SELECT count(*) FROM orders o JOIN users u ON u.id = o.user_id`" + `)
+}
+
+func Unrelated() string {
+	return "SELECT 1 FROM sessions"
+}
+`
+
+// buildSQLRefsTestGraph parses the test migration and Go file with the real
+// parsers and builds a graph from them.
+func buildSQLRefsTestGraph(t *testing.T) (*BuildResult, []*ast.ParseResult) {
+	t.Helper()
+	ctx := context.Background()
+
+	sqlResult, err := ast.NewSQLParser().Parse(ctx, []byte(sqlRefsTestMigration), "migrations/00001_init.sql")
+	if err != nil {
+		t.Fatalf("SQL parse: %v", err)
+	}
+	goResult, err := ast.NewGoParser().Parse(ctx, []byte(sqlRefsTestGo), "store/store.go")
+	if err != nil {
+		t.Fatalf("Go parse: %v", err)
+	}
+
+	results := []*ast.ParseResult{goResult, sqlResult}
+	result, err := NewBuilder().Build(ctx, results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return result, results
+}
+
+// sqlRefsSymbolID returns the ID of the symbol with the given name and kind.
+func sqlRefsSymbolID(t *testing.T, results []*ast.ParseResult, name string, kind ast.SymbolKind) string {
+	t.Helper()
+	var found string
+	var visit func(syms []*ast.Symbol)
+	visit = func(syms []*ast.Symbol) {
+		for _, sym := range syms {
+			if sym.Name == name && sym.Kind == kind {
+				found = sym.ID
+			}
+			visit(sym.Children)
+		}
+	}
+	for _, r := range results {
+		visit(r.Symbols)
+	}
+	if found == "" {
+		t.Fatalf("symbol %s (%s) not found", name, kind)
+	}
+	return found
+}
+
+// sqlRefsIncoming returns the names of the symbols with a REFERENCES edge
+// into id.
+func sqlRefsIncoming(t *testing.T, g *Graph, id string) []string {
+	t.Helper()
+	node, ok := g.GetNode(id)
+	if !ok {
+		t.Fatalf("node %s not in graph", id)
+	}
+	var names []string
+	for _, edge := range node.Incoming {
+		if edge.Type != EdgeTypeReferences {
+			continue
+		}
+		if from, ok := g.GetNode(edge.FromID); ok {
+			names = append(names, from.Symbol.Name)
+		}
+	}
+	return names
+}
+
+func TestResolveSQLReferenceEdges_FunctionsToTables(t *testing.T) {
+	result, results := buildSQLRefsTestGraph(t)
+	g := result.Graph
+
+	usersID := sqlRefsSymbolID(t, results, "users", ast.SymbolKindTable)
+	ordersID := sqlRefsSymbolID(t, results, "orders", ast.SymbolKindTable)
+
+	users := sqlRefsIncoming(t, g, usersID)
+	for _, want := range []string{"FindUser", "CountOrders", "User"} {
+		if !containsString(users, want) {
+			t.Errorf("users table referenced by %v, want %s among them", users, want)
+		}
+	}
+	if containsString(users, "Unrelated") {
+		t.Error("Unrelated queries sessions and must not reference users")
+	}
+
+	orders := sqlRefsIncoming(t, g, ordersID)
+	if !containsString(orders, "CountOrders") {
+		t.Errorf("orders table referenced by %v, want CountOrders", orders)
+	}
+
+	refs, err := g.FindReferencesByID(context.Background(), usersID)
+	if err != nil {
+		t.Fatalf("FindReferencesByID: %v", err)
+	}
+	if len(refs) == 0 {
+		t.Error("find_references on the users table returned nothing")
+	}
+
+	if result.Stats.SQLReferenceEdgesResolved == 0 {
+		t.Error("expected SQLReferenceEdgesResolved > 0")
+	}
+}
+
+func TestResolveSQLReferenceEdges_TaggedFieldsToColumns(t *testing.T) {
+	result, results := buildSQLRefsTestGraph(t)
+	g := result.Graph
+
+	// users.email is unique and matches the User struct's table.
+	var emailID string
+	for _, sym := range results[1].Symbols {
+		if sym.Kind == ast.SymbolKindColumn && sym.Name == "email" {
+			emailID = sym.ID
+		}
+	}
+	if emailID == "" {
+		t.Fatal("email column not found")
+	}
+	if got := sqlRefsIncoming(t, g, emailID); !reflect.DeepEqual(got, []string{"Email"}) {
+		t.Errorf("email column referenced by %v, want [Email]", got)
+	}
+
+	// id exists on both tables: User.ID resolves to users.id by struct
+	// name, Receipt.ID has no receipts table and is ambiguous.
+	for _, sym := range results[1].Symbols {
+		if sym.Kind != ast.SymbolKindColumn || sym.Name != "id" {
+			continue
+		}
+		got := sqlRefsIncoming(t, g, sym.ID)
+		switch sym.Metadata.ParentName {
+		case "users":
+			if len(got) != 1 {
+				t.Errorf("users.id referenced by %v, want exactly User.ID", got)
+			}
+		case "orders":
+			if len(got) != 0 {
+				t.Errorf("orders.id referenced by %v, want none (ambiguous id)", got)
+			}
+		}
+	}
+
+	// Receipt.Total resolves by uniqueness to orders.total_cents.
+	var totalID string
+	for _, sym := range results[1].Symbols {
+		if sym.Kind == ast.SymbolKindColumn && sym.Name == "total_cents" {
+			totalID = sym.ID
+		}
+	}
+	if got := sqlRefsIncoming(t, g, totalID); !reflect.DeepEqual(got, []string{"Total"}) {
+		t.Errorf("total_cents referenced by %v, want [Total]", got)
+	}
+}
+
+func TestSQLTableNamesFor(t *testing.T) {
+	tests := []struct {
+		typeName string
+		want     []string
+	}{
+		{"User", []string{"user", "users"}},
+		{"OrderItem", []string{"order_item", "order_items", "orderitem", "orderitems"}},
+		{"Category", []string{"category", "categories"}},
+		{"HTTPLog", []string{"http_log", "http_logs", "httplog", "httplogs"}},
+		{"Address", []string{"address", "addresses"}},
+	}
+	for _, tt := range tests {
+		if got := sqlTableNamesFor(tt.typeName); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sqlTableNamesFor(%q) = %v, want %v", tt.typeName, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,517 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// webIndex holds the template, HTML and CSS symbols of a build.
+type webIndex struct {
+	// templates are the HTML file symbols (ast.SymbolKindFile), keyed by
+	// base name and by the templates they define.
+	templates []*ast.Symbol
+	byBase    map[string][]*ast.Symbol
+	byDefine  map[string][]*ast.Symbol
+
+	// elements are the HTML elements with an id, also keyed by id; byClass
+	// are the HTML elements and files carrying each class
+	// (ast.SymbolMetadata.HTMLClasses), and classed lists them in build order.
+	elements     []*ast.Symbol
+	elementsByID map[string][]*ast.Symbol
+	byClass      map[string][]*ast.Symbol
+	classed      []*ast.Symbol
+
+	// cssClasses and cssIDs are the CSS selectors by name, without the
+	// leading "." or "#".
+	cssClasses map[string][]*ast.Symbol
+	cssIDs     map[string][]*ast.Symbol
+}
+
+// newWebIndex indexes the HTML and CSS symbols in results, including
+// those of inline <style> blocks.
+func newWebIndex(results []*ast.ParseResult) *webIndex {
+	idx := &webIndex{
+		byBase:       make(map[string][]*ast.Symbol),
+		byDefine:     make(map[string][]*ast.Symbol),
+		elementsByID: make(map[string][]*ast.Symbol),
+		byClass:      make(map[string][]*ast.Symbol),
+		cssClasses:   make(map[string][]*ast.Symbol),
+		cssIDs:       make(map[string][]*ast.Symbol),
+	}
+	for _, r := range results {
+		if r == nil || (r.Language != "html" && r.Language != "css") {
+			continue
+		}
+		for _, sym := range r.Symbols {
+			if sym == nil {
+				continue
+			}
+			switch sym.Kind {
+			case ast.SymbolKindCSSClass:
+				idx.cssClasses[sym.Name] = append(idx.cssClasses[sym.Name], sym)
+				continue
+			case ast.SymbolKindCSSID:
+				idx.cssIDs[sym.Name] = append(idx.cssIDs[sym.Name], sym)
+				continue
+			case ast.SymbolKindFile:
+				if sym.Language != "html" {
+					continue
+				}
+				idx.templates = append(idx.templates, sym)
+				idx.byBase[sym.Name] = append(idx.byBase[sym.Name], sym)
+				if sym.Metadata != nil {
+					for _, name := range sym.Metadata.TemplateDefines {
+						idx.byDefine[name] = append(idx.byDefine[name], sym)
+					}
+				}
+			case ast.SymbolKindElement:
+				idx.elements = append(idx.elements, sym)
+				idx.elementsByID[sym.Name] = append(idx.elementsByID[sym.Name], sym)
+			default:
+				continue
+			}
+			if sym.Metadata != nil && len(sym.Metadata.HTMLClasses) > 0 {
+				idx.classed = append(idx.classed, sym)
+				for _, class := range sym.Metadata.HTMLClasses {
+					idx.byClass[class] = append(idx.byClass[class], sym)
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// webEdgeAdder adds the REFERENCES edges of one web linkage pass.
+type webEdgeAdder struct {
+	state *buildState
+	pass  EdgePass
+
+	// counter is the pass's BuildStats field.
+	counter *int
+
+	// linked holds the from/to pairs already linked, so a template parsed
+	// and then executed by the same function gets one edge.
+	linked map[[2]string]bool
+
+	resolved, unresolved int
+}
+
+// add links from to to, once per pair. Edges from symbols without a node
+// are skipped.
+func (a *webEdgeAdder) add(from, to *ast.Symbol, loc ast.Location, confidence EdgeConfidence) {
+	if _, exists := a.state.graph.GetNode(from.ID); !exists {
+		a.unresolved++
+		return
+	}
+	pair := [2]string{from.ID, to.ID}
+	if a.linked[pair] {
+		return
+	}
+	if a.linked == nil {
+		a.linked = make(map[[2]string]bool)
+	}
+	a.linked[pair] = true
+	if loc.FilePath == "" {
+		loc = ast.Location{FilePath: from.FilePath, StartLine: from.StartLine, EndLine: from.EndLine}
+	}
+	if err := stateAddEdge(a.state, from.ID, to.ID, EdgeTypeReferences, loc, EdgeProvenance{Pass: a.pass, Confidence: confidence}); err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return
+		}
+		stateAddEdgeError(a.state, EdgeError{
+			FromID:   from.ID,
+			ToID:     to.ID,
+			EdgeType: EdgeTypeReferences,
+			Err:      fmt.Errorf("%s edge: %w", a.pass, err),
+		})
+		return
+	}
+	stateStats(a.state).EdgesCreated++
+	*a.counter++
+	a.resolved++
+}
+
+// resolveWebLinkEdges links Go templates, HTML, CSS and DOM lookups.
+//
+// Description:
+//
+//	Runs four passes, each of which can be turned off with
+//	WithDisabledPasses:
+//	  - template-render: Go functions to the template files named by the
+//	    literal arguments of their ParseFiles, ParseGlob, ParseFS and
+//	    ExecuteTemplate calls;
+//	  - template-include: template files to the files defining the
+//	    templates they include with {{template "name"}};
+//	  - css-selector: HTML elements (by id and class) and HTML files (by
+//	    the classes of their elements without an id) to the CSS selectors
+//	    with exactly that name;
+//	  - dom-selector: JavaScript and TypeScript functions to the HTML
+//	    elements matched by their literal getElementById,
+//	    getElementsByClassName and querySelector(All) selectors.
+//
+//	Only simple selectors ("#id", ".a.b", "div.card", comma lists of them)
+//	are resolved; selectors with combinators, attributes or pseudo-classes
+//	are skipped. Only runs when the build contains HTML or CSS symbols.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation and tracing.
+//	state - Build state with the graph and symbol indexes.
+//	results - All parse results of the build.
+//
+// Thread Safety: Runs on the build goroutine after edge extraction.
+func (b *Builder) resolveWebLinkEdges(ctx context.Context, state *buildState, results []*ast.ParseResult) {
+	_, span := tracer.Start(ctx, "GraphBuilder.resolveWebLinkEdges")
+	defer span.End()
+
+	idx := newWebIndex(results)
+	if len(idx.templates) == 0 && len(idx.elements) == 0 && len(idx.classed) == 0 {
+		return
+	}
+
+	stats := stateStats(state)
+	render := &webEdgeAdder{state: state, pass: PassTemplateRender, counter: &stats.TemplateRenderEdgesResolved}
+	include := &webEdgeAdder{state: state, pass: PassTemplateInclude, counter: &stats.TemplateIncludeEdgesResolved}
+	css := &webEdgeAdder{state: state, pass: PassCSSSelector, counter: &stats.CSSSelectorEdgesResolved}
+	dom := &webEdgeAdder{state: state, pass: PassDOMSelector, counter: &stats.DOMSelectorEdgesResolved}
+
+	for _, tmpl := range idx.templates {
+		idx.linkTemplateIncludes(include, tmpl)
+	}
+	idx.linkCSSSelectors(css)
+
+	var visit func(sym *ast.Symbol)
+	visit = func(sym *ast.Symbol) {
+		if sym == nil {
+			return
+		}
+		if len(sym.Calls) > 0 {
+			switch sym.Language {
+			case "go":
+				if len(idx.templates) > 0 {
+					idx.linkTemplateCalls(render, sym)
+				}
+			case "javascript", "typescript":
+				idx.linkDOMLookups(dom, sym)
+			}
+		}
+		for _, child := range sym.Children {
+			visit(child)
+		}
+	}
+	for _, r := range results {
+		if ctx.Err() != nil {
+			slog.Debug("context cancelled during web link resolution")
+			break
+		}
+		if r == nil {
+			continue
+		}
+		for _, sym := range r.Symbols {
+			visit(sym)
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("template_render", render.resolved),
+		attribute.Int("template_include", include.resolved),
+		attribute.Int("css_selector", css.resolved),
+		attribute.Int("dom_selector", dom.resolved),
+		attribute.Int("unresolved", render.unresolved+include.unresolved+css.unresolved+dom.unresolved),
+	)
+	if render.resolved+include.resolved+css.resolved+dom.resolved > 0 {
+		slog.Debug("Web link resolution complete",
+			slog.Int("template_render", render.resolved),
+			slog.Int("template_include", include.resolved),
+			slog.Int("css_selector", css.resolved),
+			slog.Int("dom_selector", dom.resolved),
+		)
+	}
+}
+
+// linkTemplateCalls links a Go function to the template files its
+// template calls name.
+//
+// Description:
+//
+//	ParseFiles arguments match files whose path ends with them; ParseGlob
+//	and ParseFS patterns match with path.Match against as many trailing
+//	path segments as the pattern has. A ParseFiles path matching several
+//	files is ambiguous and skipped. ExecuteTemplate names resolve to the
+//	file defining the template, else the file with that base name; when
+//	several match, only those the function itself parsed are kept.
+func (idx *webIndex) linkTemplateCalls(a *webEdgeAdder, fn *ast.Symbol) {
+	var parsed []*ast.Symbol
+	for _, call := range fn.Calls {
+		switch call.Target {
+		case "ParseFiles", "ParseGlob", "ParseFS":
+		default:
+			continue
+		}
+		for _, arg := range call.LiteralArgs {
+			if arg.Kind != ast.LiteralKindString || arg.Redacted {
+				continue
+			}
+			matches := idx.templatesMatching(arg.Value)
+			if call.Target == "ParseFiles" && len(matches) > 1 {
+				matches = nil
+			}
+			if len(matches) == 0 {
+				a.unresolved++
+			}
+			for _, tmpl := range matches {
+				a.add(fn, tmpl, call.Location, ConfidenceHigh)
+				parsed = append(parsed, tmpl)
+			}
+		}
+	}
+
+	for _, call := range fn.Calls {
+		if call.Target != "ExecuteTemplate" {
+			continue
+		}
+		name := firstStringLiteral(call)
+		if name == "" {
+			continue
+		}
+		candidates := idx.byDefine[name]
+		if len(candidates) == 0 {
+			candidates = idx.byBase[name]
+		}
+		if len(candidates) > 1 {
+			var own []*ast.Symbol
+			for _, c := range candidates {
+				if slices.Contains(parsed, c) {
+					own = append(own, c)
+				}
+			}
+			candidates = own
+		}
+		if len(candidates) != 1 {
+			a.unresolved++
+			continue
+		}
+		a.add(fn, candidates[0], call.Location, ConfidenceMedium)
+	}
+}
+
+// templatesMatching returns the template files matching a ParseFiles path
+// or a ParseGlob/ParseFS pattern.
+func (idx *webIndex) templatesMatching(pattern string) []*ast.Symbol {
+	pattern = strings.TrimPrefix(path.Clean(strings.ReplaceAll(pattern, "\\", "/")), "./")
+	if pattern == "." || strings.HasPrefix(pattern, "../") {
+		return nil
+	}
+	want := strings.Count(pattern, "/") + 1
+	var matches []*ast.Symbol
+	for _, tmpl := range idx.templates {
+		segments := strings.Split(tmpl.FilePath, "/")
+		if len(segments) < want {
+			continue
+		}
+		tail := strings.Join(segments[len(segments)-want:], "/")
+		if ok, err := path.Match(pattern, tail); err == nil && ok {
+			matches = append(matches, tmpl)
+		}
+	}
+	return matches
+}
+
+// linkTemplateIncludes links a template file to the files defining the
+// templates it includes. Templates the file defines itself are skipped; of
+// several defining files, one in the same directory wins.
+func (idx *webIndex) linkTemplateIncludes(a *webEdgeAdder, tmpl *ast.Symbol) {
+	if tmpl.Metadata == nil {
+		return
+	}
+	for _, name := range tmpl.Metadata.TemplateRefs {
+		if slices.Contains(tmpl.Metadata.TemplateDefines, name) {
+			continue
+		}
+		candidates := withoutSymbol(idx.byDefine[name], tmpl)
+		if len(candidates) == 0 {
+			candidates = withoutSymbol(idx.byBase[name], tmpl)
+		}
+		if len(candidates) > 1 {
+			var sameDir []*ast.Symbol
+			for _, c := range candidates {
+				if path.Dir(c.FilePath) == path.Dir(tmpl.FilePath) {
+					sameDir = append(sameDir, c)
+				}
+			}
+			candidates = sameDir
+		}
+		if len(candidates) != 1 {
+			a.unresolved++
+			continue
+		}
+		a.add(tmpl, candidates[0], ast.Location{}, ConfidenceMedium)
+	}
+}
+
+// linkCSSSelectors links HTML elements and files to the CSS selectors
+// naming their id or classes.
+func (idx *webIndex) linkCSSSelectors(a *webEdgeAdder) {
+	if len(idx.cssClasses) == 0 && len(idx.cssIDs) == 0 {
+		return
+	}
+	for _, el := range idx.elements {
+		for _, sel := range idx.cssIDs[el.Name] {
+			a.add(el, sel, ast.Location{}, ConfidenceMedium)
+		}
+	}
+	for _, sym := range idx.classed {
+		for _, class := range sym.Metadata.HTMLClasses {
+			for _, sel := range idx.cssClasses[class] {
+				a.add(sym, sel, ast.Location{}, ConfidenceMedium)
+			}
+		}
+	}
+}
+
+// linkDOMLookups links a JavaScript or TypeScript function to the HTML
+// elements its literal DOM lookups select.
+func (idx *webIndex) linkDOMLookups(a *webEdgeAdder, fn *ast.Symbol) {
+	for _, call := range fn.Calls {
+		selector := ""
+		switch call.Target {
+		case "getElementById":
+			selector = "#" + firstStringLiteral(call)
+		case "getElementsByClassName":
+			selector = "." + strings.Join(strings.Fields(firstStringLiteral(call)), ".")
+		case "querySelector", "querySelectorAll":
+			selector = firstStringLiteral(call)
+		default:
+			continue
+		}
+		for _, part := range strings.Split(selector, ",") {
+			id, classes, ok := parseSimpleSelector(part)
+			if !ok {
+				continue
+			}
+			matches := idx.elementsMatching(id, classes)
+			if len(matches) == 0 {
+				a.unresolved++
+			}
+			for _, el := range matches {
+				a.add(fn, el, call.Location, ConfidenceMedium)
+			}
+		}
+	}
+}
+
+// elementsMatching returns the HTML symbols with the given id (if any) that
+// carry every one of classes.
+func (idx *webIndex) elementsMatching(id string, classes []string) []*ast.Symbol {
+	candidates := idx.elementsByID[id]
+	if id == "" {
+		candidates = idx.byClass[classes[0]]
+	}
+	var matches []*ast.Symbol
+	for _, sym := range candidates {
+		ok := true
+		for _, class := range classes {
+			if sym.Metadata == nil || !slices.Contains(sym.Metadata.HTMLClasses, class) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			matches = append(matches, sym)
+		}
+	}
+	return matches
+}
+
+// parseSimpleSelector parses a compound CSS selector made of an optional
+// tag name followed by "#id" and ".class" parts, such as "div.card.active"
+// or "#main".
+//
+// Outputs:
+//
+//	string - The id, or "" if none.
+//	[]string - The classes.
+//	bool - False for anything else: combinators, attribute and pseudo
+//	  selectors, several ids, or a bare tag name.
+func parseSimpleSelector(sel string) (string, []string, bool) {
+	sel = strings.TrimSpace(sel)
+	i := 0
+	for i < len(sel) && isSelectorNameByte(sel[i]) {
+		i++
+	}
+	id := ""
+	var classes []string
+	for i < len(sel) {
+		marker := sel[i]
+		if marker != '#' && marker != '.' {
+			return "", nil, false
+		}
+		j := i + 1
+		for j < len(sel) && isSelectorNameByte(sel[j]) {
+			j++
+		}
+		if j == i+1 {
+			return "", nil, false
+		}
+		name := sel[i+1 : j]
+		if marker == '#' {
+			if id != "" {
+				return "", nil, false
+			}
+			id = name
+		} else {
+			classes = append(classes, name)
+		}
+		i = j
+	}
+	if id == "" && len(classes) == 0 {
+		return "", nil, false
+	}
+	return id, classes, true
+}
+
+// isSelectorNameByte reports whether c can appear in a CSS identifier.
+func isSelectorNameByte(c byte) bool {
+	return c == '-' || c == '_' || c >= 0x80 ||
+		('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
+
+// firstStringLiteral returns the first string literal argument of call
+// that was not redacted, or "".
+func firstStringLiteral(call ast.CallSite) string {
+	for _, arg := range call.LiteralArgs {
+		if arg.Kind == ast.LiteralKindString && !arg.Redacted {
+			return arg.Value
+		}
+	}
+	return ""
+}
+
+// withoutSymbol returns symbols without sym.
+func withoutSymbol(symbols []*ast.Symbol, sym *ast.Symbol) []*ast.Symbol {
+	var out []*ast.Symbol
+	for _, s := range symbols {
+		if s != sym {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,209 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+const webLinksTestGo = `package web
+
+var views = template.Must(template.ParseGlob("templates/*.html"))
+
+func Index(w http.ResponseWriter, r *http.Request) {
+	t := template.Must(template.ParseFiles("templates/layout.html", "templates/index.html"))
+	t.ExecuteTemplate(w, "layout", nil)
+}
+
+func Partials() {
+	template.ParseGlob("templates/partials/*.html")
+}
+
+func Missing(w http.ResponseWriter) {
+	views.ExecuteTemplate(w, "nowhere", nil)
+}
+`
+
+const webLinksTestJS = `function init() {
+  document.getElementById('main');
+  document.querySelectorAll('p.lead, .missing');
+  document.querySelector('#main > .lead');
+}
+`
+
+// webLinksTestFiles are the HTML and CSS files of the test project.
+var webLinksTestFiles = []struct{ path, content string }{
+	{"web/templates/layout.html", `{{define "layout"}}<html><body>
+<div id="main" class="container">{{template "content" .}}</div>
+{{template "header.html" .}}
+</body></html>{{end}}`},
+	{"web/templates/index.html", `{{define "content"}}<p class="lead">Hi</p>{{end}}`},
+	{"web/templates/partials/header.html", `<header class="site-header">{{.Title}}</header>`},
+	{"web/static/site.css", ".container { margin: 0; }\n#main { padding: 0; }\n.lead { color: red; }\n.unused { color: blue; }\n"},
+}
+
+// buildWebLinksTestGraph parses the test project with the real parsers and
+// builds a graph from it.
+func buildWebLinksTestGraph(t *testing.T, opts ...BuilderOption) *BuildResult {
+	t.Helper()
+	ctx := context.Background()
+
+	goResult, err := ast.NewGoParser().Parse(ctx, []byte(webLinksTestGo), "web/handlers.go")
+	if err != nil {
+		t.Fatalf("Go parse: %v", err)
+	}
+	jsResult, err := ast.NewJavaScriptParser().Parse(ctx, []byte(webLinksTestJS), "web/static/app.js")
+	if err != nil {
+		t.Fatalf("JS parse: %v", err)
+	}
+	results := []*ast.ParseResult{goResult, jsResult}
+	for _, f := range webLinksTestFiles {
+		var parser ast.Parser = ast.NewHTMLParser()
+		if f.path == "web/static/site.css" {
+			parser = ast.NewCSSParser()
+		}
+		r, err := parser.Parse(ctx, []byte(f.content), f.path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", f.path, err)
+		}
+		results = append(results, r)
+	}
+
+	result, err := NewBuilder(opts...).Build(ctx, results)
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	return result
+}
+
+// webLinks returns the sorted "file:name" targets of the REFERENCES edges
+// created by pass from the symbols of filePath named from.
+func webLinks(g *Graph, filePath, from string, pass EdgePass) []string {
+	var targets []string
+	for _, node := range g.Nodes() {
+		if node.Symbol.FilePath != filePath || node.Symbol.Name != from {
+			continue
+		}
+		for _, edge := range node.Outgoing {
+			if edge.Type != EdgeTypeReferences || edge.Provenance.Pass != pass {
+				continue
+			}
+			if to, ok := g.GetNode(edge.ToID); ok {
+				targets = append(targets, to.Symbol.FilePath+":"+to.Symbol.Name)
+			}
+		}
+	}
+	sort.Strings(targets)
+	return targets
+}
+
+func TestResolveWebLinkEdges(t *testing.T) {
+	result := buildWebLinksTestGraph(t)
+	g := result.Graph
+
+	tests := []struct {
+		name     string
+		filePath string
+		from     string
+		pass     EdgePass
+		want     []string
+	}{
+		{
+			name: "ParseFiles and ExecuteTemplate", filePath: "web/handlers.go", from: "Index", pass: PassTemplateRender,
+			want: []string{"web/templates/index.html:index.html", "web/templates/layout.html:layout.html"},
+		},
+		{
+			name: "ParseGlob matches trailing segments", filePath: "web/handlers.go", from: "Partials", pass: PassTemplateRender,
+			want: []string{"web/templates/partials/header.html:header.html"},
+		},
+		{
+			name: "unknown template name", filePath: "web/handlers.go", from: "Missing", pass: PassTemplateRender,
+		},
+		{
+			name: "template includes by define and base name", filePath: "web/templates/layout.html", from: "layout.html", pass: PassTemplateInclude,
+			want: []string{"web/templates/index.html:index.html", "web/templates/partials/header.html:header.html"},
+		},
+		{
+			name: "element id and classes", filePath: "web/templates/layout.html", from: "main", pass: PassCSSSelector,
+			want: []string{"web/static/site.css:container", "web/static/site.css:main"},
+		},
+		{
+			name: "classes of elements without an id", filePath: "web/templates/index.html", from: "index.html", pass: PassCSSSelector,
+			want: []string{"web/static/site.css:lead"},
+		},
+		{
+			name: "no selector for the class", filePath: "web/templates/partials/header.html", from: "header.html", pass: PassCSSSelector,
+		},
+		{
+			name: "DOM lookups", filePath: "web/static/app.js", from: "init", pass: PassDOMSelector,
+			want: []string{"web/templates/index.html:index.html", "web/templates/layout.html:main"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := webLinks(g, tt.filePath, tt.from, tt.pass); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("%s %s edges = %v, want %v", tt.from, tt.pass, got, tt.want)
+			}
+		})
+	}
+
+	stats := result.Stats
+	if stats.TemplateRenderEdgesResolved != 3 || stats.TemplateIncludeEdgesResolved != 2 ||
+		stats.CSSSelectorEdgesResolved != 3 || stats.DOMSelectorEdgesResolved != 2 {
+		t.Errorf("stats render=%d include=%d css=%d dom=%d, want 3, 2, 3, 2",
+			stats.TemplateRenderEdgesResolved, stats.TemplateIncludeEdgesResolved,
+			stats.CSSSelectorEdgesResolved, stats.DOMSelectorEdgesResolved)
+	}
+}
+
+func TestResolveWebLinkEdges_DisabledPass(t *testing.T) {
+	result := buildWebLinksTestGraph(t, WithDisabledPasses(PassCSSSelector))
+	if result.Stats.CSSSelectorEdgesResolved != 0 {
+		t.Errorf("CSSSelectorEdgesResolved = %d with the pass disabled, want 0", result.Stats.CSSSelectorEdgesResolved)
+	}
+	if got := webLinks(result.Graph, "web/templates/layout.html", "main", PassCSSSelector); len(got) != 0 {
+		t.Errorf("css-selector edges = %v with the pass disabled", got)
+	}
+	if result.Stats.TemplateRenderEdgesResolved == 0 || result.Stats.DOMSelectorEdgesResolved == 0 {
+		t.Error("other web linkage passes must still run")
+	}
+}
+
+func TestParseSimpleSelector(t *testing.T) {
+	tests := []struct {
+		sel     string
+		id      string
+		classes []string
+		ok      bool
+	}{
+		{"#main", "main", nil, true},
+		{" .card.active ", "", []string{"card", "active"}, true},
+		{"div.card", "", []string{"card"}, true},
+		{"form#login.wide", "login", []string{"wide"}, true},
+		{"div", "", nil, false},
+		{"#main .card", "", nil, false},
+		{"a[href]", "", nil, false},
+		{"li:first-child", "", nil, false},
+		{"#a#b", "", nil, false},
+		{".", "", nil, false},
+	}
+	for _, tt := range tests {
+		id, classes, ok := parseSimpleSelector(tt.sel)
+		if id != tt.id || !reflect.DeepEqual(classes, tt.classes) || ok != tt.ok {
+			t.Errorf("parseSimpleSelector(%q) = %q, %v, %v; want %q, %v, %v", tt.sel, id, classes, ok, tt.id, tt.classes, tt.ok)
+		}
+	}
+}
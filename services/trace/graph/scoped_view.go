@@ -0,0 +1,469 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+)
+
+// maxScopePatterns bounds the include plus exclude globs of a view.
+const maxScopePatterns = 64
+
+// BoundaryMode controls how a ScopedView shows edges that cross its scope.
+type BoundaryMode string
+
+const (
+	// BoundaryPlaceholder keeps crossing edges; the node outside the scope
+	// is shown as a boundary node with only its crossing edges.
+	BoundaryPlaceholder BoundaryMode = "placeholder"
+
+	// BoundaryHide drops crossing edges, except that a type inside the
+	// scope keeps its implements/embeds edges to interfaces and base
+	// types outside it.
+	BoundaryHide BoundaryMode = "hide"
+)
+
+// ParseBoundaryMode parses a boundary mode. Empty means BoundaryPlaceholder.
+//
+// Outputs:
+//
+//	BoundaryMode - The mode.
+//	error - ErrInvalidScope if s is not a known mode.
+func ParseBoundaryMode(s string) (BoundaryMode, error) {
+	switch BoundaryMode(s) {
+	case "", BoundaryPlaceholder:
+		return BoundaryPlaceholder, nil
+	case BoundaryHide:
+		return BoundaryHide, nil
+	default:
+		return "", fmt.Errorf("%w: unknown boundary mode %q (want %q or %q)", ErrInvalidScope, s, BoundaryPlaceholder, BoundaryHide)
+	}
+}
+
+// ScopedView is a read-only view of a graph restricted to a sub-tree of
+// the project.
+//
+// Description:
+//
+//	The scope is the set of nodes whose file matches the include globs
+//	(every file if there are none) and no exclude glob; ** crosses
+//	directories. External placeholders are never in scope. An edge
+//	between two nodes in scope is visible, one between two nodes outside
+//	is not, and a crossing edge depends on the BoundaryMode. The node
+//	outside the scope at the end of a visible crossing edge is a boundary
+//	node: GetNode returns it with only its visible edges.
+//
+//	Whatever the mode, a type in scope keeps its implements and embeds
+//	edges to interfaces and base types outside the scope, so those stay
+//	visible as boundary nodes and find_implementations on them still
+//	finds the implementations in scope.
+//
+//	Creating a view is one pass over the nodes and edges that records
+//	the IDs in scope and on the boundary; nothing is copied. Materialize
+//	copies the visible part into a standalone graph for code that needs a
+//	*Graph.
+//
+// Thread Safety: Safe for concurrent use; the underlying graph must be
+// frozen and is never modified.
+type ScopedView struct {
+	g        *Graph
+	include  []string
+	exclude  []string
+	mode     BoundaryMode
+	matcher  *manifest.GlobMatcher
+	inScope  map[string]struct{}
+	boundary map[string]struct{}
+
+	edgeCount int
+}
+
+var _ GraphReader = (*ScopedView)(nil)
+
+// NewScopedView creates a view of g restricted by include/exclude globs.
+//
+// Inputs:
+//
+//	g - The graph. Must be frozen.
+//	include - File globs of the scope, relative to the project root.
+//	  Empty includes every file not excluded.
+//	exclude - File globs removed from the scope.
+//	mode - How crossing edges are shown.
+//
+// Outputs:
+//
+//	*ScopedView - The view.
+//	error - ErrNilGraph, ErrGraphNotFrozen, or ErrInvalidScope if there
+//	  are no patterns, too many, or a malformed one, or mode is unknown.
+func NewScopedView(g *Graph, include, exclude []string, mode BoundaryMode) (*ScopedView, error) {
+	if g == nil {
+		return nil, ErrNilGraph
+	}
+	if !g.IsFrozen() {
+		return nil, ErrGraphNotFrozen
+	}
+	if _, err := ParseBoundaryMode(string(mode)); err != nil {
+		return nil, err
+	}
+	if mode == "" {
+		mode = BoundaryPlaceholder
+	}
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil, fmt.Errorf("%w: at least one include or exclude pattern is required", ErrInvalidScope)
+	}
+	if len(include)+len(exclude) > maxScopePatterns {
+		return nil, fmt.Errorf("%w: more than %d patterns", ErrInvalidScope, maxScopePatterns)
+	}
+	for _, pattern := range append(append([]string(nil), include...), exclude...) {
+		if err := validateScopePattern(pattern); err != nil {
+			return nil, err
+		}
+	}
+
+	v := &ScopedView{
+		g:        g,
+		include:  append([]string(nil), include...),
+		exclude:  append([]string(nil), exclude...),
+		mode:     mode,
+		inScope:  make(map[string]struct{}),
+		boundary: make(map[string]struct{}),
+	}
+	v.matcher = manifest.NewGlobMatcher(v.include, v.exclude)
+
+	for id, node := range g.nodes {
+		if node.Symbol != nil && !isPlaceholderNode(node) && v.matcher.Match(node.Symbol.FilePath) {
+			v.inScope[id] = struct{}{}
+		}
+	}
+	for _, edge := range g.edges {
+		if !v.EdgeVisible(edge) {
+			continue
+		}
+		v.edgeCount++
+		if !v.Contains(edge.FromID) {
+			v.boundary[edge.FromID] = struct{}{}
+		} else if !v.Contains(edge.ToID) {
+			v.boundary[edge.ToID] = struct{}{}
+		}
+	}
+	return v, nil
+}
+
+// validateScopePattern checks one include or exclude glob.
+func validateScopePattern(pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("%w: empty pattern", ErrInvalidScope)
+	}
+	if len(pattern) > maxQueryPatternLen {
+		return fmt.Errorf("%w: pattern longer than %d", ErrInvalidScope, maxQueryPatternLen)
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("%w: pattern %q: %v", ErrInvalidScope, pattern, err)
+	}
+	return nil
+}
+
+// Graph returns the underlying graph.
+func (v *ScopedView) Graph() *Graph {
+	return v.g
+}
+
+// Include returns the include globs. Callers must not modify the slice.
+func (v *ScopedView) Include() []string {
+	return v.include
+}
+
+// Exclude returns the exclude globs. Callers must not modify the slice.
+func (v *ScopedView) Exclude() []string {
+	return v.exclude
+}
+
+// Mode returns the boundary mode.
+func (v *ScopedView) Mode() BoundaryMode {
+	return v.mode
+}
+
+// Contains reports whether the node is in scope.
+func (v *ScopedView) Contains(id string) bool {
+	_, ok := v.inScope[id]
+	return ok
+}
+
+// IsBoundary reports whether the node is outside the scope but shown
+// because a visible edge reaches it.
+func (v *ScopedView) IsBoundary(id string) bool {
+	_, ok := v.boundary[id]
+	return ok
+}
+
+// HasNode reports whether the node is visible: in scope or a boundary node.
+func (v *ScopedView) HasNode(id string) bool {
+	return v.Contains(id) || v.IsBoundary(id)
+}
+
+// ContainsFile reports whether a project-relative file is in scope.
+func (v *ScopedView) ContainsFile(filePath string) bool {
+	return v.matcher.Match(filePath)
+}
+
+// ScopeSize returns the number of nodes in scope and on the boundary.
+func (v *ScopedView) ScopeSize() (inScope, boundary int) {
+	return len(v.inScope), len(v.boundary)
+}
+
+// EdgeVisible reports whether the view shows an edge of the graph.
+func (v *ScopedView) EdgeVisible(edge *Edge) bool {
+	fromIn, toIn := v.Contains(edge.FromID), v.Contains(edge.ToID)
+	switch {
+	case fromIn && toIn:
+		return true
+	case !fromIn && !toIn:
+		return false
+	case v.mode == BoundaryPlaceholder:
+		return true
+	default:
+		// A type in scope keeps the interfaces and bases it implements.
+		return fromIn && (edge.Type == EdgeTypeImplements || edge.Type == EdgeTypeEmbeds)
+	}
+}
+
+// GetNode returns a visible node with only its visible edges.
+//
+// Outputs:
+//
+//	*Node - The node; a copy if some of its edges are hidden. Must not be
+//	  modified.
+//	bool - False if the node is not in the graph or not visible.
+func (v *ScopedView) GetNode(id string) (*Node, bool) {
+	if !v.HasNode(id) {
+		return nil, false
+	}
+	node, ok := v.g.nodes[id]
+	if !ok {
+		return nil, false
+	}
+	return v.filterNode(node), true
+}
+
+// filterNode returns node with its hidden edges removed.
+func (v *ScopedView) filterNode(node *Node) *Node {
+	outgoing := v.visibleEdges(node.Outgoing)
+	incoming := v.visibleEdges(node.Incoming)
+	if len(outgoing) == len(node.Outgoing) && len(incoming) == len(node.Incoming) {
+		return node
+	}
+	return &Node{ID: node.ID, Symbol: node.Symbol, Outgoing: outgoing, Incoming: incoming}
+}
+
+// visibleEdges returns the visible edges, or edges itself if all are.
+func (v *ScopedView) visibleEdges(edges []*Edge) []*Edge {
+	for i, edge := range edges {
+		if v.EdgeVisible(edge) {
+			continue
+		}
+		out := append(make([]*Edge, 0, len(edges)-1), edges[:i]...)
+		for _, rest := range edges[i+1:] {
+			if v.EdgeVisible(rest) {
+				out = append(out, rest)
+			}
+		}
+		return out
+	}
+	return edges
+}
+
+// FindCallersByID returns the visible direct callers of a symbol.
+//
+// Description:
+//
+//	As Graph.FindCallersByID, over visible call edges only. A symbol that
+//	is not visible has no callers.
+func (v *ScopedView) FindCallersByID(ctx context.Context, symbolID string, opts ...QueryOption) (*QueryResult, error) {
+	return v.findIncoming(ctx, symbolID, opts, func(t EdgeType) bool { return t == EdgeTypeCalls })
+}
+
+// FindImplementationsByID returns the visible types implementing or
+// extending a symbol. See Graph.FindImplementationsByID.
+func (v *ScopedView) FindImplementationsByID(ctx context.Context, interfaceID string, opts ...QueryOption) (*QueryResult, error) {
+	return v.findIncoming(ctx, interfaceID, opts, func(t EdgeType) bool {
+		return t == EdgeTypeImplements || t == EdgeTypeEmbeds
+	})
+}
+
+// findIncoming returns the distinct sources of visible incoming edges of
+// the matching types.
+func (v *ScopedView) findIncoming(ctx context.Context, symbolID string, opts []QueryOption, match func(EdgeType) bool) (*QueryResult, error) {
+	start := time.Now()
+	options := applyOptions(opts)
+	result := &QueryResult{Symbols: make([]*ast.Symbol, 0)}
+
+	node, ok := v.g.nodes[symbolID]
+	if !ok || !v.HasNode(symbolID) {
+		result.Duration = time.Since(start)
+		return result, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, edge := range node.Incoming {
+		if ctx.Err() != nil {
+			result.Truncated = true
+			break
+		}
+		if !match(edge.Type) || !v.EdgeVisible(edge) || seen[edge.FromID] {
+			continue
+		}
+		if len(result.Symbols) >= options.Limit {
+			result.Truncated = true
+			break
+		}
+		if from, exists := v.g.nodes[edge.FromID]; exists && from.Symbol != nil {
+			seen[edge.FromID] = true
+			result.Symbols = append(result.Symbols, from.Symbol)
+		}
+	}
+
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// Nodes iterates over the visible nodes in unspecified order.
+func (v *ScopedView) Nodes() func(yield func(string, *Node) bool) {
+	return func(yield func(string, *Node) bool) {
+		for _, ids := range []map[string]struct{}{v.inScope, v.boundary} {
+			for id := range ids {
+				node, ok := v.g.nodes[id]
+				if !ok {
+					continue
+				}
+				if !yield(id, v.filterNode(node)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// AllEdges iterates over the visible edges in unspecified order.
+func (v *ScopedView) AllEdges() func(yield func(*Edge) bool) {
+	return func(yield func(*Edge) bool) {
+		for _, edge := range v.g.edges {
+			if v.EdgeVisible(edge) && !yield(edge) {
+				return
+			}
+		}
+	}
+}
+
+// NodeCount returns the number of visible nodes.
+func (v *ScopedView) NodeCount() int {
+	return len(v.inScope) + len(v.boundary)
+}
+
+// EdgeCount returns the number of visible edges.
+func (v *ScopedView) EdgeCount() int {
+	return v.edgeCount
+}
+
+// Materialize copies the visible nodes and edges into a frozen graph.
+//
+// Description:
+//
+//	For code that takes a *Graph, such as the agent tools. Symbols are
+//	shared with the underlying graph, not copied. File mtimes are kept
+//	for files in scope; owners and external packages are shared.
+//
+// Outputs:
+//
+//	*Graph - The scoped graph.
+//	error - Non-nil if the view exceeds the graph's node or edge limits.
+func (v *ScopedView) Materialize() (*Graph, error) {
+	sub := NewGraph(v.g.ProjectRoot,
+		WithMaxNodes(v.g.options.MaxNodes),
+		WithMaxEdges(v.g.options.MaxEdges),
+		// The symbols are shared with a graph that is already being read.
+		WithStringInterning(false),
+	)
+	for _, ids := range []map[string]struct{}{v.inScope, v.boundary} {
+		for id := range ids {
+			if _, err := sub.AddNode(v.g.nodes[id].Symbol); err != nil {
+				return nil, fmt.Errorf("materializing scoped view: %w", err)
+			}
+		}
+	}
+	for edge := range v.AllEdges() {
+		if err := sub.AddEdgeWithProvenance(edge.FromID, edge.ToID, edge.Type, edge.Location, edge.Provenance); err != nil {
+			return nil, fmt.Errorf("materializing scoped view: %w", err)
+		}
+	}
+
+	if v.g.FileMtimes != nil {
+		sub.FileMtimes = make(map[string]int64)
+		for file, mtime := range v.g.FileMtimes {
+			if v.ContainsFile(file) {
+				sub.FileMtimes[file] = mtime
+			}
+		}
+	}
+	if v.g.FileHashes != nil {
+		sub.FileHashes = make(map[string]string)
+		for file, hash := range v.g.FileHashes {
+			if v.ContainsFile(file) {
+				sub.FileHashes[file] = hash
+			}
+		}
+	}
+	sub.FileOwners = v.g.FileOwners
+	sub.ownersOf = v.g.ownersOf
+	sub.annotations = v.g.annotations
+	sub.ExternalPackages = v.g.ExternalPackages
+	sub.Freeze()
+	return sub, nil
+}
+
+// FilterIndex returns a symbol index holding the symbols of idx in scope
+// and the symbols of the view's boundary nodes.
+//
+// Inputs:
+//
+//	idx - The index of the underlying graph. Must not be nil.
+//
+// Outputs:
+//
+//	*index.SymbolIndex - A new index sharing the symbols of idx.
+//	error - Non-nil if a symbol is rejected by the new index.
+func (v *ScopedView) FilterIndex(idx *index.SymbolIndex) (*index.SymbolIndex, error) {
+	filtered := index.NewSymbolIndex()
+	for _, file := range idx.GetUniqueFilePaths() {
+		if !v.ContainsFile(file) {
+			continue
+		}
+		if err := filtered.AddBatch(idx.GetByFile(file)); err != nil {
+			return nil, fmt.Errorf("filtering index for scoped view: %w", err)
+		}
+	}
+	for id := range v.boundary {
+		sym, ok := idx.GetByID(id)
+		if !ok {
+			continue
+		}
+		if err := filtered.Add(sym); err != nil && !errors.Is(err, index.ErrDuplicateSymbol) {
+			return nil, fmt.Errorf("filtering index for scoped view: %w", err)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,250 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// Symbol IDs of the scoped view test graph. The payment service is the
+// scope; orders and pkg/store are outside it.
+const (
+	svCharge       = "services/payment/charge.go:10:Charge"
+	svRefund       = "services/payment/charge.go:30:Refund"
+	svPaymentStore = "services/payment/store.go:5:PaymentStore"
+	svGenerated    = "services/payment/gen/mock.go:1:MockCharge"
+	svPlaceOrder   = "services/orders/order.go:8:PlaceOrder"
+	svAuditLog     = "services/orders/audit.go:3:AuditLog"
+	svOrderStore   = "services/orders/store.go:5:OrderStore"
+	svStore        = "pkg/store/store.go:3:Store"
+	svPrintln      = "external:fmt:Println"
+)
+
+// buildScopedViewGraph builds a small monorepo graph with edges inside,
+// across and outside the payment service.
+func buildScopedViewGraph(t *testing.T) *Graph {
+	t.Helper()
+	g := NewGraph("/repo")
+	symbols := []*ast.Symbol{
+		{ID: svCharge, Name: "Charge", Kind: ast.SymbolKindFunction, FilePath: "services/payment/charge.go", StartLine: 10, EndLine: 20, Language: "go"},
+		{ID: svRefund, Name: "Refund", Kind: ast.SymbolKindFunction, FilePath: "services/payment/charge.go", StartLine: 30, EndLine: 40, Language: "go"},
+		{ID: svPaymentStore, Name: "PaymentStore", Kind: ast.SymbolKindStruct, FilePath: "services/payment/store.go", StartLine: 5, EndLine: 9, Language: "go"},
+		{ID: svGenerated, Name: "MockCharge", Kind: ast.SymbolKindFunction, FilePath: "services/payment/gen/mock.go", StartLine: 1, EndLine: 5, Language: "go"},
+		{ID: svPlaceOrder, Name: "PlaceOrder", Kind: ast.SymbolKindFunction, FilePath: "services/orders/order.go", StartLine: 8, EndLine: 18, Language: "go"},
+		{ID: svAuditLog, Name: "AuditLog", Kind: ast.SymbolKindFunction, FilePath: "services/orders/audit.go", StartLine: 3, EndLine: 6, Language: "go"},
+		{ID: svOrderStore, Name: "OrderStore", Kind: ast.SymbolKindStruct, FilePath: "services/orders/store.go", StartLine: 5, EndLine: 9, Language: "go"},
+		{ID: svStore, Name: "Store", Kind: ast.SymbolKindInterface, FilePath: "pkg/store/store.go", StartLine: 3, EndLine: 7, Language: "go"},
+		{ID: svPrintln, Name: "Println", Kind: ast.SymbolKindExternal, Language: "external"},
+	}
+	for _, sym := range symbols {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode(%s): %v", sym.ID, err)
+		}
+	}
+	edges := []struct {
+		from, to string
+		typ      EdgeType
+	}{
+		{svRefund, svCharge, EdgeTypeCalls},
+		{svGenerated, svCharge, EdgeTypeCalls},
+		{svPlaceOrder, svCharge, EdgeTypeCalls},
+		{svCharge, svAuditLog, EdgeTypeCalls},
+		{svCharge, svPrintln, EdgeTypeCalls},
+		{svPlaceOrder, svAuditLog, EdgeTypeCalls},
+		{svPaymentStore, svStore, EdgeTypeImplements},
+		{svOrderStore, svStore, EdgeTypeImplements},
+	}
+	for _, e := range edges {
+		if err := g.AddEdge(e.from, e.to, e.typ, ast.Location{FilePath: "x.go", StartLine: 1}); err != nil {
+			t.Fatalf("AddEdge(%s, %s): %v", e.from, e.to, err)
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+// newPaymentView returns the payment service view of the test graph.
+func newPaymentView(t *testing.T, mode BoundaryMode) *ScopedView {
+	t.Helper()
+	view, err := NewScopedView(buildScopedViewGraph(t), []string{"services/payment/**"}, []string{"**/gen/**"}, mode)
+	if err != nil {
+		t.Fatalf("NewScopedView: %v", err)
+	}
+	return view
+}
+
+// symbolIDs returns the sorted IDs of symbols.
+func symbolIDs(symbols []*ast.Symbol) []string {
+	ids := make([]string, 0, len(symbols))
+	for _, sym := range symbols {
+		ids = append(ids, sym.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func equalIDs(got, want []string) bool {
+	sort.Strings(want)
+	if len(got) != len(want) {
+		return false
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestScopedView_Placeholder(t *testing.T) {
+	view := newPaymentView(t, BoundaryPlaceholder)
+
+	for _, id := range []string{svCharge, svRefund, svPaymentStore} {
+		if !view.Contains(id) {
+			t.Errorf("%s not in scope", id)
+		}
+	}
+	// Excluded files are outside the scope like any other.
+	for _, id := range []string{svPlaceOrder, svAuditLog, svStore, svPrintln, svGenerated} {
+		if view.Contains(id) || !view.IsBoundary(id) {
+			t.Errorf("%s: want a boundary node", id)
+		}
+	}
+	if _, ok := view.GetNode(svOrderStore); ok {
+		t.Error("OrderStore visible, want hidden")
+	}
+	if view.NodeCount() != 8 {
+		t.Errorf("NodeCount = %d, want 8", view.NodeCount())
+	}
+	// Hidden: PlaceOrder->AuditLog, OrderStore->Store.
+	if view.EdgeCount() != 6 {
+		t.Errorf("EdgeCount = %d, want 6", view.EdgeCount())
+	}
+
+	callers, err := view.FindCallersByID(context.Background(), svCharge)
+	if err != nil {
+		t.Fatalf("FindCallersByID: %v", err)
+	}
+	if got := symbolIDs(callers.Symbols); !equalIDs(got, []string{svRefund, svPlaceOrder, svGenerated}) {
+		t.Errorf("callers of Charge = %v", got)
+	}
+
+	// A boundary node shows only its edges into the scope.
+	placeOrder, ok := view.GetNode(svPlaceOrder)
+	if !ok || len(placeOrder.Outgoing) != 1 || placeOrder.Outgoing[0].ToID != svCharge {
+		t.Errorf("PlaceOrder boundary node = %+v", placeOrder)
+	}
+
+	impls, _ := view.FindImplementationsByID(context.Background(), svStore)
+	if got := symbolIDs(impls.Symbols); !equalIDs(got, []string{svPaymentStore}) {
+		t.Errorf("implementations of Store = %v", got)
+	}
+}
+
+func TestScopedView_Hide(t *testing.T) {
+	view := newPaymentView(t, BoundaryHide)
+
+	callers, _ := view.FindCallersByID(context.Background(), svCharge)
+	if got := symbolIDs(callers.Symbols); !equalIDs(got, []string{svRefund}) {
+		t.Errorf("callers of Charge = %v", got)
+	}
+	for _, id := range []string{svPlaceOrder, svAuditLog, svPrintln, svGenerated} {
+		if view.HasNode(id) {
+			t.Errorf("%s visible in hide mode", id)
+		}
+	}
+
+	// The interface implemented in scope stays visible as a boundary node.
+	if !view.IsBoundary(svStore) {
+		t.Fatal("Store not a boundary node in hide mode")
+	}
+	impls, _ := view.FindImplementationsByID(context.Background(), svStore)
+	if got := symbolIDs(impls.Symbols); !equalIDs(got, []string{svPaymentStore}) {
+		t.Errorf("implementations of Store = %v", got)
+	}
+
+	charge, _ := view.GetNode(svCharge)
+	if len(charge.Outgoing) != 0 || len(charge.Incoming) != 1 {
+		t.Errorf("Charge edges = %d out, %d in; want 0, 1", len(charge.Outgoing), len(charge.Incoming))
+	}
+}
+
+func TestScopedView_Materialize(t *testing.T) {
+	view := newPaymentView(t, BoundaryHide)
+
+	sub, err := view.Materialize()
+	if err != nil {
+		t.Fatalf("Materialize: %v", err)
+	}
+	if !sub.IsFrozen() || sub.NodeCount() != view.NodeCount() || sub.EdgeCount() != view.EdgeCount() {
+		t.Errorf("materialized %d nodes, %d edges; view has %d, %d",
+			sub.NodeCount(), sub.EdgeCount(), view.NodeCount(), view.EdgeCount())
+	}
+	callers, _ := sub.FindCallersByID(context.Background(), svCharge)
+	if got := symbolIDs(callers.Symbols); !equalIDs(got, []string{svRefund}) {
+		t.Errorf("materialized callers of Charge = %v", got)
+	}
+
+	idx := index.NewSymbolIndex()
+	for _, node := range view.Graph().nodes {
+		if node.Symbol.FilePath != "" {
+			if err := idx.Add(node.Symbol); err != nil {
+				t.Fatalf("index Add: %v", err)
+			}
+		}
+	}
+	filtered, err := view.FilterIndex(idx)
+	if err != nil {
+		t.Fatalf("FilterIndex: %v", err)
+	}
+	for _, id := range []string{svCharge, svPaymentStore, svStore} {
+		if _, ok := filtered.GetByID(id); !ok {
+			t.Errorf("%s missing from filtered index", id)
+		}
+	}
+	for _, id := range []string{svGenerated, svOrderStore, svPlaceOrder} {
+		if _, ok := filtered.GetByID(id); ok {
+			t.Errorf("%s in filtered index", id)
+		}
+	}
+}
+
+func TestNewScopedView_Invalid(t *testing.T) {
+	g := buildScopedViewGraph(t)
+
+	tests := []struct {
+		name             string
+		include, exclude []string
+		mode             BoundaryMode
+	}{
+		{"no patterns", nil, nil, BoundaryPlaceholder},
+		{"malformed glob", []string{"services/[payment"}, nil, BoundaryPlaceholder},
+		{"empty pattern", []string{""}, nil, BoundaryPlaceholder},
+		{"unknown mode", []string{"services/**"}, nil, BoundaryMode("fold")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewScopedView(g, tt.include, tt.exclude, tt.mode); !errors.Is(err, ErrInvalidScope) {
+				t.Errorf("error = %v, want ErrInvalidScope", err)
+			}
+		})
+	}
+
+	if _, err := NewScopedView(NewGraph("/repo"), []string{"**"}, nil, BoundaryHide); !errors.Is(err, ErrGraphNotFrozen) {
+		t.Errorf("unfrozen graph error = %v, want ErrGraphNotFrozen", err)
+	}
+}
@@ -27,6 +27,8 @@ import (
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 	"log/slog"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Sentinel errors for segment tree operations.
@@ -268,7 +270,7 @@ func NewSegmentTree(ctx context.Context, arr []int64, aggFunc AggregateFunc) (*S
 	// Phase 4: Build tree bottom-up
 	span.AddEvent("building_tree")
 	if err := st.build(ctx, arr); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "build failed")
 		return nil, fmt.Errorf("build segment tree: %w", err)
 	}
@@ -285,13 +287,13 @@ func NewSegmentTree(ctx context.Context, arr []int64, aggFunc AggregateFunc) (*S
 	// Phase 5: Validation
 	span.AddEvent("validating_tree")
 	if err := st.Validate(); err != nil {
-		span.RecordError(err)
+		telemetry.RecordException(span, err)
 		span.SetStatus(codes.Error, "validation failed")
 		recordSegTreeValidationError(ctx)
 		slog.Error("segment tree validation failed",
 			slog.Int("size", st.size),
 			slog.String("agg_func", st.aggFunc.String()),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, fmt.Errorf("segment tree validation: %w", err)
 	}
@@ -425,8 +427,8 @@ func (st *SegmentTree) Query(ctx context.Context, left, right int) (int64, error
 
 	// Validate range
 	if err := st.validateRange(left, right); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return 0, err
 	}
 
@@ -522,8 +524,8 @@ func (st *SegmentTree) Update(ctx context.Context, index int, value int64) error
 
 	if index < 0 || index >= st.size {
 		err := fmt.Errorf("%w: index %d out of bounds [0,%d)", ErrInvalidRange, index, st.size)
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return err
 	}
 
@@ -608,15 +610,15 @@ func (st *SegmentTree) RangeUpdate(ctx context.Context, left, right int, delta i
 	// CRITICAL FIX: Range update only works for SUM
 	if st.aggFunc != AggregateSUM {
 		err := fmt.Errorf("%w: current function is %s", ErrRangeUpdateNotSUM, st.aggFunc.String())
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return err
 	}
 
 	// Validate range
 	if err := st.validateRange(left, right); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, err.Error())
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
 		return err
 	}
 
@@ -52,6 +52,21 @@ type SerializableGraph struct {
 	// FileMtimes records file modification times (Unix seconds) at build time.
 	// CRS-19: Used for staleness detection across sessions.
 	FileMtimes map[string]int64 `json:"file_mtimes,omitempty"`
+
+	// FileHashes records the content hash of each file at parse time.
+	FileHashes map[string]string `json:"file_hashes,omitempty"`
+
+	// QualityReport is the report computed when the graph was built.
+	QualityReport *QualityReport `json:"quality_report,omitempty"`
+
+	// PythonSourceRoots are the roots Python imports were resolved against.
+	PythonSourceRoots []PythonSourceRoot `json:"python_source_roots,omitempty"`
+
+	// GoModules are the Go modules Go imports were resolved against.
+	GoModules []GoModule `json:"go_modules,omitempty"`
+
+	// PlaceholderPolicy is the policy external symbols were built with.
+	PlaceholderPolicy PlaceholderPolicy `json:"placeholder_policy,omitempty"`
 }
 
 // SerializableNode is the JSON-serializable representation of a Node.
@@ -79,6 +94,10 @@ type SerializableEdge struct {
 
 	// Location is where the relationship is expressed in code.
 	Location ast.Location `json:"location"`
+
+	// Provenance is the pass that created the edge and its confidence.
+	// Omitted for edges without recorded provenance.
+	Provenance *EdgeProvenance `json:"provenance,omitempty"`
 }
 
 // ToSerializable converts a Graph to its JSON-serializable representation.
@@ -131,11 +150,12 @@ func (g *Graph) ToSerializable() *SerializableGraph {
 	edges := make([]SerializableEdge, 0, len(g.edges))
 	for _, edge := range g.edges {
 		edges = append(edges, SerializableEdge{
-			FromID:   edge.FromID,
-			ToID:     edge.ToID,
-			Type:     edge.Type.String(),
-			TypeCode: edge.Type,
-			Location: edge.Location,
+			FromID:     edge.FromID,
+			ToID:       edge.ToID,
+			Type:       edge.Type.String(),
+			TypeCode:   edge.Type,
+			Location:   edge.Location,
+			Provenance: serializableProvenance(edge.Provenance),
 		})
 	}
 
@@ -158,6 +178,12 @@ func (g *Graph) ToSerializable() *SerializableGraph {
 		Nodes:         nodes,
 		Edges:         edges,
 		FileMtimes:    g.FileMtimes,
+		FileHashes:    g.FileHashes,
+		QualityReport: g.QualityReport,
+
+		PythonSourceRoots: g.PythonSourceRoots,
+		GoModules:         g.GoModules,
+		PlaceholderPolicy: g.PlaceholderPolicy,
 	}
 }
 
@@ -202,11 +228,21 @@ func FromSerializable(sg *SerializableGraph, opts ...GraphOption) (*Graph, error
 
 	g := NewGraph(sg.ProjectRoot, opts...)
 
-	// Add all nodes
+	symbols := make([]*ast.Symbol, 0, len(sg.Nodes))
 	for i, sn := range sg.Nodes {
 		if sn.Symbol == nil {
 			return nil, fmt.Errorf("node at index %d has nil symbol (id=%s)", i, sn.ID)
 		}
+		symbols = append(symbols, sn.Symbol)
+	}
+
+	// Snapshots written before stable IDs existed lack them. Scope is
+	// derived from source ranges, so the flat node list yields the same
+	// IDs a fresh build would.
+	ast.AssignStableIDs(symbols)
+
+	// Add all nodes
+	for _, sn := range sg.Nodes {
 		if _, err := g.AddNode(sn.Symbol); err != nil {
 			return nil, fmt.Errorf("adding node %s: %w", sn.ID, err)
 		}
@@ -214,7 +250,11 @@ func FromSerializable(sg *SerializableGraph, opts ...GraphOption) (*Graph, error
 
 	// Add all edges using TypeCode for exact reconstruction
 	for i, se := range sg.Edges {
-		if err := g.AddEdge(se.FromID, se.ToID, se.TypeCode, se.Location); err != nil {
+		var prov EdgeProvenance
+		if se.Provenance != nil {
+			prov = *se.Provenance
+		}
+		if err := g.AddEdgeWithProvenance(se.FromID, se.ToID, se.TypeCode, se.Location, prov); err != nil {
 			return nil, fmt.Errorf("adding edge %d (%s -> %s): %w", i, se.FromID, se.ToID, err)
 		}
 	}
@@ -225,6 +265,20 @@ func FromSerializable(sg *SerializableGraph, opts ...GraphOption) (*Graph, error
 
 	// CRS-19: Restore file mtimes for staleness detection.
 	g.FileMtimes = sg.FileMtimes
+	g.FileHashes = sg.FileHashes
+	g.QualityReport = sg.QualityReport
+	g.PythonSourceRoots = sg.PythonSourceRoots
+	g.GoModules = sg.GoModules
+	g.PlaceholderPolicy = sg.PlaceholderPolicy
 
 	return g, nil
 }
+
+// serializableProvenance returns prov for a SerializableEdge, or nil when
+// no provenance was recorded so the field is omitted.
+func serializableProvenance(prov EdgeProvenance) *EdgeProvenance {
+	if prov.IsZero() {
+		return nil
+	}
+	return &prov
+}
@@ -23,6 +23,8 @@ import (
 	"time"
 
 	"github.com/dgraph-io/badger/v4"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // BadgerDB key prefixes for graph snapshots.
@@ -221,7 +223,7 @@ func (m *SnapshotManager) Save(ctx context.Context, g *Graph, label string) (*Sn
 
 	m.logger.Info("snapshot saved",
 		slog.String("snapshot_id", snapshotID),
-		slog.String("project_root", g.ProjectRoot),
+		telemetry.SensitiveLog("project_root", g.ProjectRoot),
 		slog.Int("node_count", meta.NodeCount),
 		slog.Int("edge_count", meta.EdgeCount),
 		slog.Int64("compressed_size", meta.CompressedSize),
@@ -363,7 +365,7 @@ func (m *SnapshotManager) List(ctx context.Context, projectHash string, limit in
 				return json.Unmarshal(val, &meta)
 			})
 			if err != nil {
-				m.logger.Warn("skipping corrupt metadata", slog.String("key", key), slog.Any("error", err))
+				m.logger.Warn("skipping corrupt metadata", slog.String("key", key), telemetry.SensitiveLogError("error", err))
 				continue
 			}
 
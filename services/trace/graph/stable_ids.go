@@ -0,0 +1,152 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"strconv"
+	"strings"
+)
+
+// buildStableIndex maps Symbol.StableID to nodes. Called by Freeze().
+// Symbols without a StableID are skipped; if two nodes share one (which
+// AssignStableIDs prevents within a build), the first by ID wins.
+//
+// Thread Safety:
+//
+//	NOT safe for concurrent use. Called by Freeze() only.
+func (g *Graph) buildStableIndex() {
+	index := make(map[string]*Node, len(g.nodes))
+	for _, node := range g.nodes {
+		if node.Symbol == nil || node.Symbol.StableID == "" {
+			continue
+		}
+		if existing, ok := index[node.Symbol.StableID]; ok && existing.ID < node.ID {
+			continue
+		}
+		index[node.Symbol.StableID] = node
+	}
+	g.nodesByStableID = index
+}
+
+// GetNodeByStableID retrieves a node by its symbol's StableID.
+//
+// Inputs:
+//
+//	stableID - The line-independent ID (see ast.GenerateStableID).
+//
+// Outputs:
+//
+//	*Node - The node if found, nil otherwise.
+//	bool - True if found. Always false before Freeze().
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) GetNodeByStableID(stableID string) (*Node, bool) {
+	node, ok := g.nodesByStableID[stableID]
+	return node, ok
+}
+
+// ResolveNodeID maps any accepted form of a symbol ID to the node ID.
+//
+// Description:
+//
+//	Accepts, in order:
+//	  1. A node ID ("file_path:start_line:name").
+//	  2. A stable ID ("file_path::Scope.name@sighash").
+//	  3. A line-based ID from an earlier build of the same source whose
+//	     line no longer matches. It resolves to the only node with that
+//	     name in that file; if the name is ambiguous it does not resolve.
+//
+//	Form 3 is the migration path for IDs stored before stable IDs
+//	existed (session state, bookmarks, cached tool results).
+//
+// Inputs:
+//
+//	id - A node ID, stable ID or line-based ID.
+//
+// Outputs:
+//
+//	string - The node ID, or "" if not found.
+//	bool - True if resolved.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) ResolveNodeID(id string) (string, bool) {
+	if _, ok := g.nodes[id]; ok {
+		return id, true
+	}
+	if node, ok := g.nodesByStableID[id]; ok {
+		return node.ID, true
+	}
+	filePath, name, ok := parseLineID(id)
+	if !ok {
+		return "", false
+	}
+	var match *Node
+	for _, node := range g.fileIntervals[filePath] {
+		if node.Symbol.Name != name {
+			continue
+		}
+		if match != nil && match.Symbol.StableID != node.Symbol.StableID {
+			return "", false
+		}
+		match = node
+	}
+	if match == nil {
+		return "", false
+	}
+	return match.ID, true
+}
+
+// StableIDFor returns the stable ID of the symbol identified by id, which
+// may be in any form accepted by ResolveNodeID.
+//
+// Outputs:
+//
+//	string - The stable ID, or "" if id does not resolve.
+//	bool - True if found.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) StableIDFor(id string) (string, bool) {
+	nodeID, ok := g.ResolveNodeID(id)
+	if !ok {
+		return "", false
+	}
+	node := g.nodes[nodeID]
+	if node.Symbol == nil || node.Symbol.StableID == "" {
+		return "", false
+	}
+	return node.Symbol.StableID, true
+}
+
+// parseLineID splits a "file_path:start_line:name" ID. The file path may
+// itself contain colons.
+func parseLineID(id string) (filePath, name string, ok bool) {
+	if strings.Contains(id, "::") {
+		return "", "", false
+	}
+	last := strings.LastIndexByte(id, ':')
+	if last <= 0 {
+		return "", "", false
+	}
+	prev := strings.LastIndexByte(id[:last], ':')
+	if prev <= 0 {
+		return "", "", false
+	}
+	if _, err := strconv.Atoi(id[prev+1 : last]); err != nil {
+		return "", "", false
+	}
+	return id[:prev], id[last+1:], id[last+1:] != ""
+}
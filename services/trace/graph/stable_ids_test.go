@@ -0,0 +1,100 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+const stableIDSource = `package svc
+
+func Load() error { return nil }
+
+func Save() error { return Load() }
+`
+
+// buildStableIDGraph parses content as svc/svc.go and builds a graph.
+func buildStableIDGraph(t *testing.T, content string) *Graph {
+	t.Helper()
+	result, err := ast.NewGoParser().Parse(context.Background(), []byte(content), "svc/svc.go")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	built, err := NewBuilder(WithProjectRoot("/project")).Build(context.Background(), []*ast.ParseResult{result})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	return built.Graph
+}
+
+// nodeIDByName returns the node ID of the symbol called name.
+func nodeIDByName(t *testing.T, g *Graph, name string) string {
+	t.Helper()
+	nodes := g.GetNodesByName(name)
+	if len(nodes) != 1 {
+		t.Fatalf("got %d nodes named %s, want 1", len(nodes), name)
+	}
+	return nodes[0].ID
+}
+
+func TestGraph_ResolveNodeID(t *testing.T) {
+	before := buildStableIDGraph(t, stableIDSource)
+	oldID := nodeIDByName(t, before, "Save")
+	stableID, ok := before.StableIDFor(oldID)
+	if !ok || stableID == "" {
+		t.Fatalf("StableIDFor(%q) = %q, %v", oldID, stableID, ok)
+	}
+
+	// Rebuild with the functions moved down two lines.
+	after := buildStableIDGraph(t, "// Package svc.\n//\n"+stableIDSource)
+	newID := nodeIDByName(t, after, "Save")
+	if newID == oldID {
+		t.Fatal("test source did not move the symbol")
+	}
+
+	for _, id := range []string{newID, stableID, oldID} {
+		got, ok := after.ResolveNodeID(id)
+		if !ok || got != newID {
+			t.Errorf("ResolveNodeID(%q) = %q, %v; want %q", id, got, ok, newID)
+		}
+	}
+	if node, ok := after.GetNodeByStableID(stableID); !ok || node.ID != newID {
+		t.Errorf("GetNodeByStableID(%q) = %v, %v", stableID, node, ok)
+	}
+	if _, ok := after.ResolveNodeID("svc/svc.go:3:Missing"); ok {
+		t.Error("unknown name resolved")
+	}
+	if _, ok := after.ResolveNodeID("not-an-id"); ok {
+		t.Error("malformed ID resolved")
+	}
+}
+
+func TestFromSerializable_BackfillsStableIDs(t *testing.T) {
+	g := buildStableIDGraph(t, stableIDSource)
+	want, _ := g.StableIDFor(nodeIDByName(t, g, "Load"))
+
+	sg := g.ToSerializable()
+	for i := range sg.Nodes {
+		sym := *sg.Nodes[i].Symbol
+		sym.StableID = ""
+		sg.Nodes[i].Symbol = &sym
+	}
+	loaded, err := FromSerializable(sg)
+	if err != nil {
+		t.Fatalf("FromSerializable: %v", err)
+	}
+	if got, ok := loaded.StableIDFor(nodeIDByName(t, loaded, "Load")); !ok || got != want {
+		t.Errorf("backfilled stable ID = %q, want %q", got, want)
+	}
+}
@@ -17,6 +17,9 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // StalenessCheckSampleSize is the number of files to spot-check per staleness check.
@@ -266,6 +269,55 @@ func RecordFileMtimes(g *Graph, projectRoot string) {
 
 	slog.Debug("CRS-19: Recorded file mtimes for staleness detection",
 		slog.Int("files", len(mtimes)),
-		slog.String("project_root", projectRoot),
+		telemetry.SensitiveLog("project_root", projectRoot),
 	)
 }
+
+// RecordFileHashes populates a graph's FileHashes field from the content
+// hashes of the parse results it was built from.
+//
+// Description:
+//
+//	Records ast.ParseResult.Hash for every result with a file path and a
+//	hash. Clients compare these against the files on disk to measure drift
+//	without re-parsing. Should be called after Freeze().
+//
+// Inputs:
+//   - g: The graph to populate. Nil is a no-op.
+//   - results: The parse results the graph was built from.
+//
+// Thread Safety: Must be called during build (before concurrent reads).
+func RecordFileHashes(g *Graph, results []*ast.ParseResult) {
+	if g == nil {
+		return
+	}
+	hashes := make(map[string]string, len(results))
+	for _, r := range results {
+		if r != nil && r.FilePath != "" && r.Hash != "" {
+			hashes[r.FilePath] = r.Hash
+		}
+	}
+	g.FileHashes = hashes
+}
+
+// refreshedFileHashes returns the file hashes after an incremental refresh:
+// those of the base graph for unchanged files, plus the hashes of the
+// re-parsed files. Changed files without a result were deleted.
+func refreshedFileHashes(base map[string]string, changedFiles []string, changedResults []*ast.ParseResult) map[string]string {
+	if base == nil {
+		return nil
+	}
+	hashes := make(map[string]string, len(base)+len(changedResults))
+	for file, hash := range base {
+		hashes[file] = hash
+	}
+	for _, f := range changedFiles {
+		delete(hashes, f)
+	}
+	for _, r := range changedResults {
+		if r != nil && r.FilePath != "" && r.Hash != "" {
+			hashes[r.FilePath] = r.Hash
+		}
+	}
+	return hashes
+}
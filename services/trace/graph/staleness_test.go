@@ -11,8 +11,10 @@
 package graph
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"reflect"
 	"testing"
 	"time"
 
@@ -283,3 +285,53 @@ func TestRecordFileMtimes_NilGraph(t *testing.T) {
 	// Should not panic
 	RecordFileMtimes(nil, "/tmp")
 }
+
+func TestRecordFileHashes_BuildAndIncrementalRefresh(t *testing.T) {
+	ctx := context.Background()
+	results := []*ast.ParseResult{
+		{FilePath: "a.go", Language: "go", Hash: "hash-a", Symbols: []*ast.Symbol{
+			{ID: "a.go:1:A", Name: "A", Kind: ast.SymbolKindFunction, FilePath: "a.go", StartLine: 1, EndLine: 2, Language: "go"},
+		}},
+		{FilePath: "b.go", Language: "go", Hash: "hash-b"},
+		{FilePath: "c.go", Language: "go", Hash: "hash-c"},
+		{FilePath: "nohash.go", Language: "go"},
+	}
+	built, err := NewBuilder().Build(ctx, results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	want := map[string]string{"a.go": "hash-a", "b.go": "hash-b", "c.go": "hash-c"}
+	if !reflect.DeepEqual(built.Graph.FileHashes, want) {
+		t.Fatalf("FileHashes = %v, want %v", built.Graph.FileHashes, want)
+	}
+
+	restored, err := FromSerializable(built.Graph.ToSerializable())
+	if err != nil {
+		t.Fatalf("FromSerializable: %v", err)
+	}
+	if !reflect.DeepEqual(restored.FileHashes, want) {
+		t.Errorf("FileHashes after round trip = %v, want %v", restored.FileHashes, want)
+	}
+
+	// b.go changed, c.go was deleted and d.go was added.
+	changed := []*ast.ParseResult{
+		{FilePath: "b.go", Language: "go", Hash: "hash-b2"},
+		{FilePath: "d.go", Language: "go", Hash: "hash-d"},
+	}
+	refreshed, err := IncrementalRefresh(ctx, built.Graph, []string{"b.go", "c.go", "d.go"}, changed, nil)
+	if err != nil {
+		t.Fatalf("IncrementalRefresh: %v", err)
+	}
+	want = map[string]string{"a.go": "hash-a", "b.go": "hash-b2", "d.go": "hash-d"}
+	if !reflect.DeepEqual(refreshed.Graph.FileHashes, want) {
+		t.Errorf("FileHashes after refresh = %v, want %v", refreshed.Graph.FileHashes, want)
+	}
+	if built.Graph.FileHashes["b.go"] != "hash-b" {
+		t.Error("refresh modified the base graph's hashes")
+	}
+}
+
+func TestRecordFileHashes_NilGraph(t *testing.T) {
+	// Should not panic
+	RecordFileHashes(nil, nil)
+}
@@ -0,0 +1,775 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/manifest"
+)
+
+// Structured query limits. Requested limits are clamped to the Max values,
+// so no query can walk more of the graph or run longer than these allow.
+const (
+	// DefaultQueryMaxVisited is the default cap on nodes visited.
+	DefaultQueryMaxVisited = 10000
+
+	// MaxQueryMaxVisited is the hard cap on nodes visited.
+	MaxQueryMaxVisited = 100000
+
+	// DefaultQueryMaxResults is the default cap on returned rows.
+	DefaultQueryMaxResults = 100
+
+	// MaxQueryMaxResults is the hard cap on returned rows.
+	MaxQueryMaxResults = 1000
+
+	// DefaultQueryTimeout is the default time budget for one query.
+	DefaultQueryTimeout = 2 * time.Second
+
+	// MaxQueryTimeout is the hard cap on the time budget.
+	MaxQueryTimeout = 10 * time.Second
+
+	// MaxQuerySteps is the maximum number of traversal steps.
+	MaxQuerySteps = 4
+
+	// MaxQueryStepDepth is the maximum depth of one traversal step.
+	MaxQueryStepDepth = 6
+
+	// maxQueryPatternLen bounds name_regex and file_glob.
+	maxQueryPatternLen = 256
+
+	// queryCtxCheckInterval is how often (in visits) the deadline is checked.
+	queryCtxCheckInterval = 256
+)
+
+// Limit names reported in StructuredQueryResult.LimitReached.
+const (
+	QueryLimitVisited = "max_visited"
+	QueryLimitResults = "max_results"
+	QueryLimitTimeout = "timeout"
+)
+
+// Traversal directions for TraverseStep.Direction.
+const (
+	QueryDirectionOut  = "out"
+	QueryDirectionIn   = "in"
+	QueryDirectionBoth = "both"
+)
+
+// Values for StructuredQuery.Select.
+const (
+	// QuerySelectEnd returns the nodes reached by the last step.
+	QuerySelectEnd = "end"
+
+	// QuerySelectStart returns the matched nodes from which the traversal
+	// reaches at least one node.
+	QuerySelectStart = "start"
+)
+
+// defaultQueryFields are returned when StructuredQuery.Fields is empty.
+var defaultQueryFields = []string{"id", "name", "kind", "file_path", "start_line"}
+
+// queryFieldGetters project one symbol field into a result row.
+var queryFieldGetters = map[string]func(*ast.Symbol) any{
+	"id":         func(s *ast.Symbol) any { return s.ID },
+	"stable_id":  func(s *ast.Symbol) any { return s.StableID },
+	"name":       func(s *ast.Symbol) any { return s.Name },
+	"kind":       func(s *ast.Symbol) any { return s.Kind.String() },
+	"file_path":  func(s *ast.Symbol) any { return s.FilePath },
+	"start_line": func(s *ast.Symbol) any { return s.StartLine },
+	"end_line":   func(s *ast.Symbol) any { return s.EndLine },
+	"package":    func(s *ast.Symbol) any { return s.Package },
+	"language":   func(s *ast.Symbol) any { return s.Language },
+	"exported":   func(s *ast.Symbol) any { return s.Exported },
+	"signature":  func(s *ast.Symbol) any { return s.Signature },
+	"receiver":   func(s *ast.Symbol) any { return s.Receiver },
+}
+
+// StructuredQuery is a JSON query over a frozen graph.
+//
+// Description:
+//
+//	Match selects the start nodes. Each Traverse step then follows the
+//	given edge types from the current node set, keeping the nodes found
+//	between MinDepth and MaxDepth that pass the step's Where filter; those
+//	become the input of the next step. Select chooses whether the rows
+//	are the nodes reached at the end or the start nodes that reach any.
+//
+//	For example, "exported functions in package X that call anything in
+//	package Y":
+//
+//	{
+//	  "match": {"kinds": ["function"], "package": "X", "exported": true},
+//	  "traverse": [{"edge_types": ["calls"], "where": {"package": "Y"}}],
+//	  "select": "start"
+//	}
+type StructuredQuery struct {
+	// Match selects the start nodes. An empty filter matches every node.
+	Match NodeFilter `json:"match"`
+
+	// Traverse steps, applied in order. At most MaxQuerySteps.
+	Traverse []TraverseStep `json:"traverse,omitempty"`
+
+	// Select is QuerySelectEnd (default) or QuerySelectStart.
+	Select string `json:"select,omitempty"`
+
+	// Fields are the symbol fields in each row. Default: id, name, kind,
+	// file_path, start_line.
+	Fields []string `json:"fields,omitempty"`
+
+	// Limits bound the work done. Zero values use the defaults.
+	Limits QueryLimits `json:"limits,omitempty"`
+}
+
+// NodeFilter matches nodes by symbol attributes. All set fields must match.
+type NodeFilter struct {
+	// Kinds are symbol kinds (e.g., "function", "method"). Any may match.
+	Kinds []string `json:"kinds,omitempty"`
+
+	// NameRegex is an RE2 expression matched against the symbol name.
+	NameRegex string `json:"name_regex,omitempty"`
+
+	// FileGlob matches the file path; ** crosses directories.
+	FileGlob string `json:"file_glob,omitempty"`
+
+	// Languages are language names (e.g., "go", "python"). Any may match.
+	Languages []string `json:"languages,omitempty"`
+
+	// Package is the exact package name.
+	Package string `json:"package,omitempty"`
+
+	// Exported, when set, requires the symbol's exported flag to equal it.
+	Exported *bool `json:"exported,omitempty"`
+}
+
+// TraverseStep follows edges from the current node set.
+type TraverseStep struct {
+	// EdgeTypes are the edge types to follow (e.g., "calls"). Required.
+	EdgeTypes []string `json:"edge_types"`
+
+	// Direction is "out" (default), "in" or "both".
+	Direction string `json:"direction,omitempty"`
+
+	// MinDepth is the smallest depth kept. Default 1; 0 also keeps the
+	// input nodes.
+	MinDepth *int `json:"min_depth,omitempty"`
+
+	// MaxDepth is the largest depth walked. Default 1, max MaxQueryStepDepth.
+	MaxDepth int `json:"max_depth,omitempty"`
+
+	// Where filters the nodes kept by this step. Nodes that fail it are
+	// still walked through.
+	Where *NodeFilter `json:"where,omitempty"`
+}
+
+// QueryLimits bound the work done by a structured query.
+type QueryLimits struct {
+	// MaxVisited caps the nodes visited, start nodes included.
+	MaxVisited int `json:"max_visited,omitempty"`
+
+	// MaxResults caps the rows returned.
+	MaxResults int `json:"max_results,omitempty"`
+
+	// TimeoutMs caps the wall time in milliseconds.
+	TimeoutMs int `json:"timeout_ms,omitempty"`
+}
+
+// StructuredQueryResult is the result of RunStructuredQuery.
+type StructuredQueryResult struct {
+	// Rows hold the selected fields of each result node, ordered by ID.
+	Rows []map[string]any `json:"rows"`
+
+	// Visited is the number of nodes visited.
+	Visited int `json:"visited"`
+
+	// Truncated is true if a limit stopped the query early.
+	Truncated bool `json:"truncated"`
+
+	// LimitReached names the limit that stopped the query, if any.
+	LimitReached string `json:"limit_reached,omitempty"`
+
+	// Limits are the effective limits after defaults and clamping.
+	Limits QueryLimits `json:"limits"`
+}
+
+// QueryPlan is the dry-run estimate returned by ExplainStructuredQuery.
+type QueryPlan struct {
+	// StartNodes is the exact number of nodes Match selects.
+	StartNodes int `json:"start_nodes"`
+
+	// Steps estimates each traversal step.
+	Steps []QueryStepEstimate `json:"steps"`
+
+	// EstimatedVisited estimates the nodes the query would visit.
+	EstimatedVisited int `json:"estimated_visited"`
+
+	// WithinLimits is false if the estimate exceeds Limits.MaxVisited, in
+	// which case the query would likely be truncated.
+	WithinLimits bool `json:"within_limits"`
+
+	// Limits are the effective limits after defaults and clamping.
+	Limits QueryLimits `json:"limits"`
+}
+
+// QueryStepEstimate estimates one traversal step.
+type QueryStepEstimate struct {
+	// EdgeTypes and Direction echo the step.
+	EdgeTypes []string `json:"edge_types"`
+	Direction string   `json:"direction"`
+
+	// MaxDepth is the effective maximum depth.
+	MaxDepth int `json:"max_depth"`
+
+	// AvgFanout is the mean number of matching edges per node.
+	AvgFanout float64 `json:"avg_fanout"`
+
+	// EstimatedNodes estimates the nodes this step visits, capped at the
+	// graph size per traversal.
+	EstimatedNodes int `json:"estimated_nodes"`
+}
+
+// compiledFilter is a validated NodeFilter.
+type compiledFilter struct {
+	kinds     []ast.SymbolKind
+	name      *regexp.Regexp
+	glob      *manifest.GlobMatcher
+	languages []string
+	pkg       string
+	exported  *bool
+}
+
+// compiledStep is a validated TraverseStep.
+type compiledStep struct {
+	edgeTypes [NumEdgeTypes]bool
+	out, in   bool
+	minDepth  int
+	maxDepth  int
+	where     *compiledFilter
+}
+
+// compiledQuery is a validated StructuredQuery with effective limits.
+type compiledQuery struct {
+	match       *compiledFilter
+	steps       []compiledStep
+	selectStart bool
+	fields      []string
+	limits      QueryLimits
+}
+
+// RunStructuredQuery evaluates a structured query against the graph.
+//
+// Description:
+//
+//	Validates the query, selects the start nodes and runs each traversal
+//	step breadth-first. Work is bounded by the effective limits: when
+//	MaxVisited, MaxResults or the timeout is reached the query stops and
+//	returns the rows found so far with Truncated set and LimitReached
+//	naming the limit. Rows are ordered by node ID.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. The query timeout is applied on top.
+//	q - The query. Must not be nil.
+//
+// Outputs:
+//
+//	*StructuredQueryResult - The rows and the work done.
+//	error - Wraps ErrInvalidQuery if the query fails validation.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) RunStructuredQuery(ctx context.Context, q *StructuredQuery) (*StructuredQueryResult, error) {
+	cq, err := compileStructuredQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(cq.limits.TimeoutMs)*time.Millisecond)
+	defer cancel()
+
+	run := &queryRun{ctx: ctx, g: g, limits: cq.limits}
+	result := &StructuredQueryResult{Rows: []map[string]any{}, Limits: cq.limits}
+
+	start := run.matchStart(cq.match)
+	var selected []*Node
+	if cq.selectStart {
+		// Each start node is walked alone so that it can be kept as soon as
+		// the last step reaches anything.
+		for _, node := range start {
+			if run.stopped() {
+				break
+			}
+			if len(run.traverse([]*Node{node}, cq.steps, true)) > 0 {
+				selected = append(selected, node)
+			}
+			// Start nodes are in ID order, so one row past the limit
+			// is enough to know the rows are truncated.
+			if len(selected) > cq.limits.MaxResults {
+				break
+			}
+		}
+	} else {
+		selected = run.traverse(start, cq.steps, false)
+	}
+
+	sort.Slice(selected, func(i, j int) bool { return selected[i].ID < selected[j].ID })
+	if len(selected) > cq.limits.MaxResults {
+		selected = selected[:cq.limits.MaxResults]
+		run.stop(QueryLimitResults)
+	}
+	for _, node := range selected {
+		result.Rows = append(result.Rows, projectRow(node.Symbol, cq.fields))
+	}
+
+	result.Visited = run.visited
+	result.LimitReached = run.limitReached
+	result.Truncated = run.limitReached != ""
+	return result, nil
+}
+
+// ExplainStructuredQuery estimates the work a structured query would do
+// without traversing the graph.
+//
+// Description:
+//
+//	Counts the start nodes exactly, then estimates each step from the mean
+//	fan-out of its edge types (edges of those types per node), summed over
+//	its depths and capped at the graph size. Select "start" walks each
+//	start node alone, so its estimate is per start node.
+//
+// Inputs:
+//
+//	q - The query. Must not be nil.
+//
+// Outputs:
+//
+//	*QueryPlan - The estimate.
+//	error - Wraps ErrInvalidQuery if the query fails validation.
+//
+// Thread Safety:
+//
+//	Safe for concurrent use on frozen graphs.
+func (g *Graph) ExplainStructuredQuery(q *StructuredQuery) (*QueryPlan, error) {
+	cq, err := compileStructuredQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	for _, node := range g.candidateNodes(cq.match) {
+		if cq.match.matches(node.Symbol) {
+			start++
+		}
+	}
+
+	plan := &QueryPlan{StartNodes: start, Steps: make([]QueryStepEstimate, 0, len(cq.steps)), Limits: cq.limits}
+	// Select "start" walks each start node alone, so one walk starts from
+	// a single node and the walk is repeated per start node.
+	total := float64(g.NodeCount())
+	frontier, walks := float64(start), 1.0
+	if cq.selectStart {
+		frontier, walks = math.Min(1, frontier), float64(start)
+	}
+	walked := 0.0
+	for i, step := range cq.steps {
+		fanout := 0.0
+		for t := EdgeType(0); t < NumEdgeTypes; t++ {
+			if step.edgeTypes[t] && total > 0 {
+				fanout += float64(g.GetEdgeCountByType(t)) / total
+			}
+		}
+		if step.in && step.out {
+			fanout *= 2
+		}
+		stepNodes, level := 0.0, frontier
+		for d := 1; d <= step.maxDepth; d++ {
+			level *= fanout
+			stepNodes += level
+		}
+		stepNodes = math.Min(stepNodes, total)
+		walked += stepNodes
+		frontier = stepNodes
+
+		plan.Steps = append(plan.Steps, QueryStepEstimate{
+			EdgeTypes:      q.Traverse[i].EdgeTypes,
+			Direction:      stepDirection(step),
+			MaxDepth:       step.maxDepth,
+			AvgFanout:      math.Round(fanout*100) / 100,
+			EstimatedNodes: int(math.Ceil(stepNodes)),
+		})
+	}
+	visited := float64(start) + walks*walked
+
+	plan.EstimatedVisited = int(math.Min(math.Ceil(visited), math.MaxInt32))
+	plan.WithinLimits = plan.EstimatedVisited <= cq.limits.MaxVisited
+	return plan, nil
+}
+
+// queryRun tracks the shared budget of one query evaluation.
+type queryRun struct {
+	ctx          context.Context
+	g            *Graph
+	limits       QueryLimits
+	visited      int
+	limitReached string
+}
+
+// visit counts one node visit. It returns false once a limit is reached.
+func (r *queryRun) visit() bool {
+	if r.limitReached != "" {
+		return false
+	}
+	if r.visited >= r.limits.MaxVisited {
+		r.stop(QueryLimitVisited)
+		return false
+	}
+	r.visited++
+	if r.visited%queryCtxCheckInterval == 0 && r.ctx.Err() != nil {
+		r.stop(QueryLimitTimeout)
+		return false
+	}
+	return true
+}
+
+// stop records the first limit reached.
+func (r *queryRun) stop(limit string) {
+	if r.limitReached == "" {
+		r.limitReached = limit
+	}
+}
+
+// stopped reports whether a limit has been reached, checking the deadline.
+func (r *queryRun) stopped() bool {
+	if r.limitReached == "" && r.ctx.Err() != nil {
+		r.stop(QueryLimitTimeout)
+	}
+	return r.limitReached != ""
+}
+
+// matchStart returns the nodes the match filter selects, ordered by ID.
+func (r *queryRun) matchStart(f *compiledFilter) []*Node {
+	candidates := r.g.candidateNodes(f)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+
+	var start []*Node
+	for i, node := range candidates {
+		if i%queryCtxCheckInterval == 0 && r.stopped() {
+			break
+		}
+		if f.matches(node.Symbol) {
+			if !r.visit() {
+				break
+			}
+			start = append(start, node)
+		}
+	}
+	return start
+}
+
+// traverse applies the steps in order and returns the final node set.
+// With firstOnly, the last step returns as soon as it keeps one node.
+func (r *queryRun) traverse(nodes []*Node, steps []compiledStep, firstOnly bool) []*Node {
+	for i, step := range steps {
+		if len(nodes) == 0 || r.stopped() {
+			return nil
+		}
+		nodes = r.expand(nodes, step, firstOnly && i == len(steps)-1)
+	}
+	return nodes
+}
+
+// expand runs one step breadth-first from nodes.
+func (r *queryRun) expand(nodes []*Node, step compiledStep, firstOnly bool) []*Node {
+	seen := make(map[string]bool, len(nodes))
+	var kept []*Node
+	keep := func(n *Node, depth int) bool {
+		if depth >= step.minDepth && (step.where == nil || step.where.matches(n.Symbol)) {
+			kept = append(kept, n)
+			return firstOnly
+		}
+		return false
+	}
+
+	frontier := make([]*Node, 0, len(nodes))
+	for _, n := range nodes {
+		if !seen[n.ID] {
+			seen[n.ID] = true
+			frontier = append(frontier, n)
+			if keep(n, 0) {
+				return kept
+			}
+		}
+	}
+
+	for depth := 1; depth <= step.maxDepth && len(frontier) > 0; depth++ {
+		var next []*Node
+		for _, n := range frontier {
+			for _, id := range step.neighbors(n) {
+				if seen[id] {
+					continue
+				}
+				seen[id] = true
+				neighbor, ok := r.g.GetNode(id)
+				if !ok || neighbor.Symbol == nil {
+					continue
+				}
+				if !r.visit() {
+					return kept
+				}
+				next = append(next, neighbor)
+				if keep(neighbor, depth) {
+					return kept
+				}
+			}
+		}
+		frontier = next
+	}
+	return kept
+}
+
+// neighbors returns the IDs one step away along the step's edges.
+func (s *compiledStep) neighbors(n *Node) []string {
+	var ids []string
+	if s.out {
+		for _, e := range n.Outgoing {
+			if s.edgeTypes[e.Type] {
+				ids = append(ids, e.ToID)
+			}
+		}
+	}
+	if s.in {
+		for _, e := range n.Incoming {
+			if s.edgeTypes[e.Type] {
+				ids = append(ids, e.FromID)
+			}
+		}
+	}
+	return ids
+}
+
+// candidateNodes returns the nodes that may match f, using the kind index
+// when the filter names kinds.
+func (g *Graph) candidateNodes(f *compiledFilter) []*Node {
+	if len(f.kinds) > 0 {
+		var nodes []*Node
+		for _, kind := range f.kinds {
+			nodes = append(nodes, g.nodesByKind[kind]...)
+		}
+		return nodes
+	}
+	nodes := make([]*Node, 0, len(g.nodes))
+	for _, node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// matches reports whether sym passes every set field of the filter.
+func (f *compiledFilter) matches(sym *ast.Symbol) bool {
+	if sym == nil {
+		return false
+	}
+	if len(f.kinds) > 0 && !slices.Contains(f.kinds, sym.Kind) {
+		return false
+	}
+	if len(f.languages) > 0 && !slices.Contains(f.languages, sym.Language) {
+		return false
+	}
+	if f.pkg != "" && sym.Package != f.pkg {
+		return false
+	}
+	if f.exported != nil && sym.Exported != *f.exported {
+		return false
+	}
+	if f.name != nil && !f.name.MatchString(sym.Name) {
+		return false
+	}
+	if f.glob != nil && !f.glob.Match(sym.FilePath) {
+		return false
+	}
+	return true
+}
+
+// projectRow builds one result row with the given fields.
+func projectRow(sym *ast.Symbol, fields []string) map[string]any {
+	row := make(map[string]any, len(fields))
+	for _, field := range fields {
+		row[field] = queryFieldGetters[field](sym)
+	}
+	return row
+}
+
+// stepDirection returns the direction name of a compiled step.
+func stepDirection(s compiledStep) string {
+	switch {
+	case s.in && s.out:
+		return QueryDirectionBoth
+	case s.in:
+		return QueryDirectionIn
+	default:
+		return QueryDirectionOut
+	}
+}
+
+// compileStructuredQuery validates q and resolves its effective limits.
+func compileStructuredQuery(q *StructuredQuery) (*compiledQuery, error) {
+	if q == nil {
+		return nil, fmt.Errorf("%w: query is required", ErrInvalidQuery)
+	}
+
+	match, err := compileFilter(&q.Match, "match")
+	if err != nil {
+		return nil, err
+	}
+	cq := &compiledQuery{match: match}
+
+	if len(q.Traverse) > MaxQuerySteps {
+		return nil, fmt.Errorf("%w: at most %d traverse steps", ErrInvalidQuery, MaxQuerySteps)
+	}
+	for i := range q.Traverse {
+		step, err := compileStep(&q.Traverse[i], i)
+		if err != nil {
+			return nil, err
+		}
+		cq.steps = append(cq.steps, step)
+	}
+
+	switch q.Select {
+	case "", QuerySelectEnd:
+	case QuerySelectStart:
+		cq.selectStart = true
+	default:
+		return nil, fmt.Errorf("%w: select must be %q or %q", ErrInvalidQuery, QuerySelectEnd, QuerySelectStart)
+	}
+
+	cq.fields = defaultQueryFields
+	if len(q.Fields) > 0 {
+		for _, field := range q.Fields {
+			if _, ok := queryFieldGetters[field]; !ok {
+				return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidQuery, field)
+			}
+		}
+		cq.fields = q.Fields
+	}
+
+	cq.limits, err = effectiveQueryLimits(q.Limits)
+	if err != nil {
+		return nil, err
+	}
+	return cq, nil
+}
+
+// compileFilter validates a NodeFilter. where names it in errors.
+func compileFilter(f *NodeFilter, where string) (*compiledFilter, error) {
+	cf := &compiledFilter{languages: f.Languages, pkg: f.Package, exported: f.Exported}
+	for _, name := range f.Kinds {
+		kind := ast.ParseSymbolKind(name)
+		if kind == ast.SymbolKindUnknown {
+			return nil, fmt.Errorf("%w: %s: unknown kind %q", ErrInvalidQuery, where, name)
+		}
+		cf.kinds = append(cf.kinds, kind)
+	}
+	if f.NameRegex != "" {
+		if len(f.NameRegex) > maxQueryPatternLen {
+			return nil, fmt.Errorf("%w: %s: name_regex longer than %d", ErrInvalidQuery, where, maxQueryPatternLen)
+		}
+		re, err := regexp.Compile(f.NameRegex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: name_regex: %v", ErrInvalidQuery, where, err)
+		}
+		cf.name = re
+	}
+	if f.FileGlob != "" {
+		if len(f.FileGlob) > maxQueryPatternLen {
+			return nil, fmt.Errorf("%w: %s: file_glob longer than %d", ErrInvalidQuery, where, maxQueryPatternLen)
+		}
+		if _, err := filepath.Match(f.FileGlob, ""); err != nil {
+			return nil, fmt.Errorf("%w: %s: file_glob: %v", ErrInvalidQuery, where, err)
+		}
+		cf.glob = manifest.NewGlobMatcher([]string{f.FileGlob}, nil)
+	}
+	return cf, nil
+}
+
+// compileStep validates a TraverseStep at index i.
+func compileStep(s *TraverseStep, i int) (compiledStep, error) {
+	var cs compiledStep
+	where := fmt.Sprintf("traverse[%d]", i)
+
+	if len(s.EdgeTypes) == 0 {
+		return cs, fmt.Errorf("%w: %s: edge_types is required", ErrInvalidQuery, where)
+	}
+	for _, name := range s.EdgeTypes {
+		t, ok := ParseEdgeType(name)
+		if !ok {
+			return cs, fmt.Errorf("%w: %s: unknown edge type %q", ErrInvalidQuery, where, name)
+		}
+		cs.edgeTypes[t] = true
+	}
+
+	switch s.Direction {
+	case "", QueryDirectionOut:
+		cs.out = true
+	case QueryDirectionIn:
+		cs.in = true
+	case QueryDirectionBoth:
+		cs.out, cs.in = true, true
+	default:
+		return cs, fmt.Errorf("%w: %s: direction must be out, in or both", ErrInvalidQuery, where)
+	}
+
+	cs.minDepth, cs.maxDepth = 1, s.MaxDepth
+	if s.MinDepth != nil {
+		cs.minDepth = *s.MinDepth
+	}
+	if cs.maxDepth == 0 {
+		cs.maxDepth = max(1, cs.minDepth)
+	}
+	if cs.minDepth < 0 || cs.maxDepth > MaxQueryStepDepth || cs.minDepth > cs.maxDepth {
+		return cs, fmt.Errorf("%w: %s: need 0 <= min_depth <= max_depth <= %d", ErrInvalidQuery, where, MaxQueryStepDepth)
+	}
+
+	if s.Where != nil {
+		f, err := compileFilter(s.Where, where+".where")
+		if err != nil {
+			return cs, err
+		}
+		cs.where = f
+	}
+	return cs, nil
+}
+
+// effectiveQueryLimits applies defaults and clamps to the hard caps.
+func effectiveQueryLimits(l QueryLimits) (QueryLimits, error) {
+	if l.MaxVisited < 0 || l.MaxResults < 0 || l.TimeoutMs < 0 {
+		return l, fmt.Errorf("%w: limits must not be negative", ErrInvalidQuery)
+	}
+	clamp := func(v, def, hi int) int {
+		if v == 0 {
+			return def
+		}
+		return min(v, hi)
+	}
+	return QueryLimits{
+		MaxVisited: clamp(l.MaxVisited, DefaultQueryMaxVisited, MaxQueryMaxVisited),
+		MaxResults: clamp(l.MaxResults, DefaultQueryMaxResults, MaxQueryMaxResults),
+		TimeoutMs:  clamp(l.TimeoutMs, int(DefaultQueryTimeout/time.Millisecond), int(MaxQueryTimeout/time.Millisecond)),
+	}, nil
+}
@@ -0,0 +1,356 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// buildQueryTestGraph builds two packages:
+//
+//	x.Public -> y.Helper -> y.deep
+//	x.private -> y.Helper
+//	x.Lonely (calls nothing)
+func buildQueryTestGraph(t *testing.T) *Graph {
+	t.Helper()
+	g := NewGraph("/test/project")
+	add := func(id, name, pkg, file string, exported bool) {
+		sym := makeSymbol(id, name, ast.SymbolKindFunction, file)
+		sym.Package = pkg
+		sym.Exported = exported
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", id, err)
+		}
+	}
+	add("x/a.go:1:Public", "Public", "x", "x/a.go", true)
+	add("x/a.go:5:private", "private", "x", "x/a.go", false)
+	add("x/b.go:1:Lonely", "Lonely", "x", "x/b.go", true)
+	add("y/h.go:1:Helper", "Helper", "y", "y/h.go", true)
+	add("y/internal/d.go:1:deep", "deep", "y", "y/internal/d.go", false)
+
+	for _, e := range [][2]string{
+		{"x/a.go:1:Public", "y/h.go:1:Helper"},
+		{"x/a.go:5:private", "y/h.go:1:Helper"},
+		{"y/h.go:1:Helper", "y/internal/d.go:1:deep"},
+	} {
+		if err := g.AddEdge(e[0], e[1], EdgeTypeCalls, makeLocation("x/a.go", 2)); err != nil {
+			t.Fatalf("AddEdge error = %v", err)
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+// buildChainGraph builds f0 -> f1 -> ... -> f(n-1).
+func buildChainGraph(t *testing.T, n int) *Graph {
+	t.Helper()
+	g := NewGraph("/test/project")
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("chain.go:f%03d", i)
+		if _, err := g.AddNode(makeSymbol(id, fmt.Sprintf("f%03d", i), ast.SymbolKindFunction, "chain.go")); err != nil {
+			t.Fatalf("AddNode error = %v", err)
+		}
+		if i > 0 {
+			prev := fmt.Sprintf("chain.go:f%03d", i-1)
+			if err := g.AddEdge(prev, id, EdgeTypeCalls, makeLocation("chain.go", i)); err != nil {
+				t.Fatalf("AddEdge error = %v", err)
+			}
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+func intPtr(v int) *int { return &v }
+
+func rowIDs(rows []map[string]any) []string {
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i], _ = row["id"].(string)
+	}
+	return ids
+}
+
+func TestRunStructuredQuery_SelectStart(t *testing.T) {
+	g := buildQueryTestGraph(t)
+	exported := true
+	q := &StructuredQuery{
+		Match:    NodeFilter{Kinds: []string{"function"}, Package: "x", Exported: &exported},
+		Traverse: []TraverseStep{{EdgeTypes: []string{"calls"}, Where: &NodeFilter{Package: "y"}}},
+		Select:   QuerySelectStart,
+	}
+
+	result, err := g.RunStructuredQuery(context.Background(), q)
+	if err != nil {
+		t.Fatalf("RunStructuredQuery() error = %v", err)
+	}
+	ids := rowIDs(result.Rows)
+	if len(ids) != 1 || ids[0] != "x/a.go:1:Public" {
+		t.Errorf("rows = %v, want only x.Public", ids)
+	}
+	if result.Truncated {
+		t.Errorf("result truncated by %s", result.LimitReached)
+	}
+}
+
+func TestRunStructuredQuery_SelectEndWithDepth(t *testing.T) {
+	g := buildQueryTestGraph(t)
+
+	tests := []struct {
+		name string
+		step TraverseStep
+		want []string
+	}{
+		{"depth 1", TraverseStep{EdgeTypes: []string{"calls"}}, []string{"y/h.go:1:Helper"}},
+		{"depth 1-2", TraverseStep{EdgeTypes: []string{"calls"}, MaxDepth: 2}, []string{"y/h.go:1:Helper", "y/internal/d.go:1:deep"}},
+		{"depth 2 only", TraverseStep{EdgeTypes: []string{"calls"}, MinDepth: intPtr(2), MaxDepth: 2}, []string{"y/internal/d.go:1:deep"}},
+		{"min depth 0 keeps the start", TraverseStep{EdgeTypes: []string{"calls"}, MinDepth: intPtr(0), MaxDepth: 1, Where: &NodeFilter{NameRegex: "^P"}}, []string{"x/a.go:1:Public"}},
+		{"walks through filtered nodes", TraverseStep{EdgeTypes: []string{"calls"}, MaxDepth: 2, Where: &NodeFilter{FileGlob: "y/internal/**"}}, []string{"y/internal/d.go:1:deep"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &StructuredQuery{Match: NodeFilter{NameRegex: "^Public$"}, Traverse: []TraverseStep{tt.step}}
+			result, err := g.RunStructuredQuery(context.Background(), q)
+			if err != nil {
+				t.Fatalf("RunStructuredQuery() error = %v", err)
+			}
+			if got := rowIDs(result.Rows); fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("rows = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunStructuredQuery_IncomingAndFields(t *testing.T) {
+	g := buildQueryTestGraph(t)
+	q := &StructuredQuery{
+		Match:    NodeFilter{NameRegex: "^Helper$", Languages: []string{"go"}},
+		Traverse: []TraverseStep{{EdgeTypes: []string{"calls"}, Direction: QueryDirectionIn}},
+		Fields:   []string{"name", "exported"},
+	}
+
+	result, err := g.RunStructuredQuery(context.Background(), q)
+	if err != nil {
+		t.Fatalf("RunStructuredQuery() error = %v", err)
+	}
+	if len(result.Rows) != 2 {
+		t.Fatalf("got %d rows, want the 2 callers", len(result.Rows))
+	}
+	// Rows are ordered by ID: x.Public, then x.private.
+	if result.Rows[0]["name"] != "Public" || result.Rows[0]["exported"] != true || result.Rows[1]["name"] != "private" {
+		t.Errorf("rows = %v", result.Rows)
+	}
+	if _, ok := result.Rows[0]["id"]; ok {
+		t.Error("rows should hold only the requested fields")
+	}
+}
+
+func TestRunStructuredQuery_MaxVisited(t *testing.T) {
+	g := buildChainGraph(t, 50)
+	q := &StructuredQuery{
+		Match:    NodeFilter{NameRegex: "^f000$"},
+		Traverse: []TraverseStep{{EdgeTypes: []string{"calls"}, MaxDepth: MaxQueryStepDepth}},
+		Limits:   QueryLimits{MaxVisited: 4},
+	}
+
+	result, err := g.RunStructuredQuery(context.Background(), q)
+	if err != nil {
+		t.Fatalf("RunStructuredQuery() error = %v", err)
+	}
+	if !result.Truncated || result.LimitReached != QueryLimitVisited {
+		t.Errorf("Truncated = %v, LimitReached = %q, want max_visited", result.Truncated, result.LimitReached)
+	}
+	if result.Visited != 4 {
+		t.Errorf("Visited = %d, want exactly the limit of 4", result.Visited)
+	}
+	if len(result.Rows) != 3 {
+		t.Errorf("got %d rows, want the 3 nodes reached before the limit", len(result.Rows))
+	}
+}
+
+func TestRunStructuredQuery_MaxVisitedCountsStartNodes(t *testing.T) {
+	g := buildChainGraph(t, 50)
+	q := &StructuredQuery{Match: NodeFilter{Kinds: []string{"function"}}, Limits: QueryLimits{MaxVisited: 10}}
+
+	result, err := g.RunStructuredQuery(context.Background(), q)
+	if err != nil {
+		t.Fatalf("RunStructuredQuery() error = %v", err)
+	}
+	if result.LimitReached != QueryLimitVisited || result.Visited != 10 || len(result.Rows) != 10 {
+		t.Errorf("LimitReached = %q, Visited = %d, rows = %d, want max_visited, 10, 10",
+			result.LimitReached, result.Visited, len(result.Rows))
+	}
+}
+
+func TestRunStructuredQuery_MaxResults(t *testing.T) {
+	g := buildChainGraph(t, 50)
+
+	t.Run("select end", func(t *testing.T) {
+		q := &StructuredQuery{Match: NodeFilter{Kinds: []string{"function"}}, Limits: QueryLimits{MaxResults: 5}}
+		result, err := g.RunStructuredQuery(context.Background(), q)
+		if err != nil {
+			t.Fatalf("RunStructuredQuery() error = %v", err)
+		}
+		if len(result.Rows) != 5 || !result.Truncated || result.LimitReached != QueryLimitResults {
+			t.Errorf("rows = %d, LimitReached = %q, want 5 rows truncated by max_results", len(result.Rows), result.LimitReached)
+		}
+		if result.Rows[0]["id"] != "chain.go:f000" {
+			t.Errorf("first row = %v, want the lowest ID", result.Rows[0]["id"])
+		}
+	})
+
+	t.Run("select start stops walking", func(t *testing.T) {
+		q := &StructuredQuery{
+			Match:    NodeFilter{Kinds: []string{"function"}},
+			Traverse: []TraverseStep{{EdgeTypes: []string{"calls"}}},
+			Select:   QuerySelectStart,
+			Limits:   QueryLimits{MaxResults: 3},
+		}
+		result, err := g.RunStructuredQuery(context.Background(), q)
+		if err != nil {
+			t.Fatalf("RunStructuredQuery() error = %v", err)
+		}
+		if len(result.Rows) != 3 || result.LimitReached != QueryLimitResults {
+			t.Errorf("rows = %d, LimitReached = %q, want 3 rows truncated by max_results", len(result.Rows), result.LimitReached)
+		}
+		// 50 start nodes plus one neighbor for each of the 4 walks.
+		if result.Visited != 54 {
+			t.Errorf("Visited = %d, want 54: walking should stop past the limit", result.Visited)
+		}
+	})
+}
+
+func TestRunStructuredQuery_Timeout(t *testing.T) {
+	g := buildChainGraph(t, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	q := &StructuredQuery{Traverse: []TraverseStep{{EdgeTypes: []string{"calls"}}}}
+	result, err := g.RunStructuredQuery(ctx, q)
+	if err != nil {
+		t.Fatalf("RunStructuredQuery() error = %v", err)
+	}
+	if !result.Truncated || result.LimitReached != QueryLimitTimeout {
+		t.Errorf("Truncated = %v, LimitReached = %q, want timeout", result.Truncated, result.LimitReached)
+	}
+	if len(result.Rows) != 0 {
+		t.Errorf("got %d rows after the deadline, want 0", len(result.Rows))
+	}
+}
+
+func TestRunStructuredQuery_LimitsClamped(t *testing.T) {
+	g := buildChainGraph(t, 3)
+
+	result, err := g.RunStructuredQuery(context.Background(), &StructuredQuery{
+		Limits: QueryLimits{MaxVisited: 1 << 30, MaxResults: 1 << 30, TimeoutMs: 1 << 30},
+	})
+	if err != nil {
+		t.Fatalf("RunStructuredQuery() error = %v", err)
+	}
+	want := QueryLimits{MaxVisited: MaxQueryMaxVisited, MaxResults: MaxQueryMaxResults, TimeoutMs: int(MaxQueryTimeout.Milliseconds())}
+	if result.Limits != want {
+		t.Errorf("Limits = %+v, want the hard caps %+v", result.Limits, want)
+	}
+
+	result, err = g.RunStructuredQuery(context.Background(), &StructuredQuery{})
+	if err != nil {
+		t.Fatalf("RunStructuredQuery() error = %v", err)
+	}
+	want = QueryLimits{MaxVisited: DefaultQueryMaxVisited, MaxResults: DefaultQueryMaxResults, TimeoutMs: int(DefaultQueryTimeout.Milliseconds())}
+	if result.Limits != want {
+		t.Errorf("Limits = %+v, want the defaults %+v", result.Limits, want)
+	}
+}
+
+func TestRunStructuredQuery_Invalid(t *testing.T) {
+	g := buildChainGraph(t, 3)
+	calls := []string{"calls"}
+
+	tests := []struct {
+		name string
+		q    *StructuredQuery
+	}{
+		{"nil query", nil},
+		{"unknown kind", &StructuredQuery{Match: NodeFilter{Kinds: []string{"gadget"}}}},
+		{"bad regex", &StructuredQuery{Match: NodeFilter{NameRegex: "("}}},
+		{"long regex", &StructuredQuery{Match: NodeFilter{NameRegex: string(make([]byte, maxQueryPatternLen+1))}}},
+		{"bad glob", &StructuredQuery{Match: NodeFilter{FileGlob: "["}}},
+		{"no edge types", &StructuredQuery{Traverse: []TraverseStep{{}}}},
+		{"unknown edge type", &StructuredQuery{Traverse: []TraverseStep{{EdgeTypes: []string{"likes"}}}}},
+		{"unknown direction", &StructuredQuery{Traverse: []TraverseStep{{EdgeTypes: calls, Direction: "up"}}}},
+		{"depth too deep", &StructuredQuery{Traverse: []TraverseStep{{EdgeTypes: calls, MaxDepth: MaxQueryStepDepth + 1}}}},
+		{"min above max", &StructuredQuery{Traverse: []TraverseStep{{EdgeTypes: calls, MinDepth: intPtr(3), MaxDepth: 2}}}},
+		{"negative depth", &StructuredQuery{Traverse: []TraverseStep{{EdgeTypes: calls, MinDepth: intPtr(-1)}}}},
+		{"bad where", &StructuredQuery{Traverse: []TraverseStep{{EdgeTypes: calls, Where: &NodeFilter{Kinds: []string{"gadget"}}}}}},
+		{"too many steps", &StructuredQuery{Traverse: make([]TraverseStep, MaxQuerySteps+1)}},
+		{"unknown field", &StructuredQuery{Fields: []string{"body"}}},
+		{"unknown select", &StructuredQuery{Select: "middle"}},
+		{"negative limit", &StructuredQuery{Limits: QueryLimits{MaxVisited: -1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := g.RunStructuredQuery(context.Background(), tt.q); !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("RunStructuredQuery() error = %v, want ErrInvalidQuery", err)
+			}
+			if _, err := g.ExplainStructuredQuery(tt.q); !errors.Is(err, ErrInvalidQuery) {
+				t.Errorf("ExplainStructuredQuery() error = %v, want ErrInvalidQuery", err)
+			}
+		})
+	}
+}
+
+func TestExplainStructuredQuery(t *testing.T) {
+	g := buildChainGraph(t, 50)
+	q := &StructuredQuery{
+		Match:    NodeFilter{Kinds: []string{"function"}},
+		Traverse: []TraverseStep{{EdgeTypes: []string{"calls"}, MaxDepth: 2}},
+	}
+
+	plan, err := g.ExplainStructuredQuery(q)
+	if err != nil {
+		t.Fatalf("ExplainStructuredQuery() error = %v", err)
+	}
+	if plan.StartNodes != 50 || len(plan.Steps) != 1 {
+		t.Fatalf("plan = %+v, want 50 start nodes and 1 step", plan)
+	}
+	// 49 call edges over 50 nodes.
+	if plan.Steps[0].AvgFanout != 0.98 {
+		t.Errorf("AvgFanout = %v, want 0.98", plan.Steps[0].AvgFanout)
+	}
+	if plan.EstimatedVisited < 50 || plan.EstimatedVisited > 150 || !plan.WithinLimits {
+		t.Errorf("EstimatedVisited = %d, WithinLimits = %v", plan.EstimatedVisited, plan.WithinLimits)
+	}
+
+	q.Limits.MaxVisited = 10
+	plan, err = g.ExplainStructuredQuery(q)
+	if err != nil {
+		t.Fatalf("ExplainStructuredQuery() error = %v", err)
+	}
+	if plan.WithinLimits {
+		t.Error("WithinLimits = true, want false when the estimate exceeds max_visited")
+	}
+}
+
+func TestParseEdgeType(t *testing.T) {
+	if got, ok := ParseEdgeType("implements"); !ok || got != EdgeTypeImplements {
+		t.Errorf("ParseEdgeType(implements) = %v, %v", got, ok)
+	}
+	for _, name := range []string{"unknown", "", "CALLS"} {
+		if _, ok := ParseEdgeType(name); ok {
+			t.Errorf("ParseEdgeType(%q) ok = true, want false", name)
+		}
+	}
+}
@@ -0,0 +1,235 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
+)
+
+// Symbol source retrieval limits.
+const (
+	// DefaultSymbolSourceMaxBytes caps the returned source text.
+	DefaultSymbolSourceMaxBytes = 64 * 1024
+
+	// MaxSymbolSourceContextLines caps the context lines on each side.
+	MaxSymbolSourceContextLines = 100
+)
+
+var (
+	// ErrSymbolNotFound is returned when a symbol ID is not in the graph.
+	ErrSymbolNotFound = errors.New("symbol not found")
+
+	// ErrEmptyFilePath is returned by ValidateFilePath for an empty path.
+	ErrEmptyFilePath = errors.New("empty FilePath")
+
+	// ErrPathTraversal is returned when a FilePath could escape the project root.
+	ErrPathTraversal = errors.New("FilePath contains path traversal")
+)
+
+// SymbolSource is the source text of a symbol plus surrounding context.
+type SymbolSource struct {
+	// SymbolID is the symbol's unique ID.
+	SymbolID string `json:"symbol_id"`
+
+	// FilePath is the file path relative to the project root.
+	FilePath string `json:"file_path"`
+
+	// Language is the source language.
+	Language string `json:"language"`
+
+	// StartLine and EndLine are the symbol's own range (1-indexed, inclusive).
+	StartLine int `json:"start_line"`
+	EndLine   int `json:"end_line"`
+
+	// FromLine and ToLine are the returned range including context lines.
+	FromLine int `json:"from_line"`
+	ToLine   int `json:"to_line"`
+
+	// Source is the text of lines FromLine..ToLine.
+	Source string `json:"source"`
+
+	// Truncated is true if Source was cut to the byte limit.
+	Truncated bool `json:"truncated"`
+
+	// SourceBytes is the size of the untruncated slice in bytes.
+	SourceBytes int `json:"source_bytes"`
+
+	// FileHash is the SHA256 of the whole file, comparable to
+	// ast.ParseResult.Hash. A mismatch means the graph is stale for this file.
+	FileHash string `json:"file_hash"`
+}
+
+// ValidateFilePath checks a project-relative FilePath for building or reading.
+//
+// Description:
+//
+//	This is the check the Builder applies to ParseResult.FilePath. Readers
+//	that resolve symbol file paths against the project root use it too, so
+//	build-time and read-time validation cannot drift apart.
+//
+// Inputs:
+//
+//	filePath - The path relative to the project root.
+//
+// Outputs:
+//
+//	error - ErrEmptyFilePath or ErrPathTraversal, nil if the path is acceptable.
+func ValidateFilePath(filePath string) error {
+	if filePath == "" {
+		return ErrEmptyFilePath
+	}
+	if strings.Contains(filePath, "..") {
+		return ErrPathTraversal
+	}
+	return nil
+}
+
+// SymbolSource returns the source of a symbol in this graph.
+//
+// Description:
+//
+//	Looks up the symbol by ID and reads its lines from ProjectRoot.
+//	See ReadSymbolSource for the slicing and truncation rules.
+//
+// Inputs:
+//
+//	symbolID - The symbol ID.
+//	contextLines - Lines of context before and after the symbol.
+//	maxBytes - Byte cap for Source. <= 0 uses DefaultSymbolSourceMaxBytes.
+//
+// Outputs:
+//
+//	*SymbolSource - The source slice.
+//	error - ErrSymbolNotFound, a path validation error, or a read error.
+//
+// Thread Safety: Safe for concurrent use on a frozen graph.
+func (g *Graph) SymbolSource(symbolID string, contextLines, maxBytes int) (*SymbolSource, error) {
+	node, ok := g.GetNode(symbolID)
+	if !ok || node.Symbol == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSymbolNotFound, symbolID)
+	}
+	return ReadSymbolSource(g.ProjectRoot, node.Symbol, contextLines, maxBytes)
+}
+
+// ReadSymbolSource reads a symbol's lines plus context from disk.
+//
+// Description:
+//
+//	Validates sym.FilePath with ValidateFilePath and confirms it resolves
+//	under projectRoot. Reads the file, hashes it, and slices
+//	StartLine-contextLines through EndLine+contextLines (clamped to the
+//	file). Output larger than maxBytes is cut at a line boundary and a
+//	truncation marker is appended.
+//
+// Inputs:
+//
+//	projectRoot - Absolute project root.
+//	sym - The symbol. Must not be nil.
+//	contextLines - Context lines on each side, clamped to [0, MaxSymbolSourceContextLines].
+//	maxBytes - Byte cap for Source. <= 0 uses DefaultSymbolSourceMaxBytes.
+//
+// Outputs:
+//
+//	*SymbolSource - The source slice.
+//	error - ErrEmptyFilePath/ErrPathTraversal for invalid paths, or a read error.
+//
+// Thread Safety: Safe for concurrent use.
+func ReadSymbolSource(projectRoot string, sym *ast.Symbol, contextLines, maxBytes int) (*SymbolSource, error) {
+	if sym == nil {
+		return nil, fmt.Errorf("symbol must not be nil")
+	}
+	if err := ValidateFilePath(sym.FilePath); err != nil {
+		return nil, err
+	}
+	if contextLines < 0 {
+		contextLines = 0
+	}
+	if contextLines > MaxSymbolSourceContextLines {
+		contextLines = MaxSymbolSourceContextLines
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultSymbolSourceMaxBytes
+	}
+
+	// Symlinks are resolved, so a link inside the project pointing
+	// outside it is refused.
+	absPath, err := validation.ResolveWithin(projectRoot, sym.FilePath)
+	if errors.Is(err, validation.ErrPathTraversal) {
+		return nil, fmt.Errorf("%w: %s resolves outside project root", ErrPathTraversal, sym.FilePath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", sym.FilePath, err)
+	}
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", sym.FilePath, err)
+	}
+	hash := sha256.Sum256(content)
+
+	lines := bytes.Split(content, []byte("\n"))
+	if len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+
+	from := sym.StartLine - contextLines
+	if from < 1 {
+		from = 1
+	}
+	to := sym.EndLine + contextLines
+	if to < sym.StartLine {
+		to = sym.StartLine + contextLines
+	}
+	if to > len(lines) {
+		to = len(lines)
+	}
+
+	out := &SymbolSource{
+		SymbolID:  sym.ID,
+		FilePath:  sym.FilePath,
+		Language:  sym.Language,
+		StartLine: sym.StartLine,
+		EndLine:   sym.EndLine,
+		FromLine:  from,
+		ToLine:    to,
+		FileHash:  hex.EncodeToString(hash[:]),
+	}
+	if from > to {
+		return out, nil
+	}
+
+	source := string(bytes.Join(lines[from-1:to], []byte("\n")))
+	out.SourceBytes = len(source)
+	if len(source) > maxBytes {
+		cut := strings.LastIndexByte(source[:maxBytes], '\n')
+		if cut <= 0 {
+			cut = maxBytes
+			for cut > 0 && !utf8.RuneStart(source[cut]) {
+				cut--
+			}
+		}
+		out.Source = source[:cut] + fmt.Sprintf("\n... [truncated: %d of %d bytes shown] ...", cut, len(source))
+		out.Truncated = true
+	} else {
+		out.Source = source
+	}
+	return out, nil
+}
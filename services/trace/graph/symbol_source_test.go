@@ -0,0 +1,109 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+func writeSymbolSourceFixture(t *testing.T) (string, []byte) {
+	t.Helper()
+	root := t.TempDir()
+	content := []byte("package a\n\n// Add adds.\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Sub() {}\n")
+	if err := os.MkdirAll(filepath.Join(root, "a"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a", "a.go"), content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return root, content
+}
+
+func TestReadSymbolSource_Context(t *testing.T) {
+	root, content := writeSymbolSourceFixture(t)
+	sym := &ast.Symbol{ID: "a/a.go:4:Add", Name: "Add", FilePath: "a/a.go", StartLine: 4, EndLine: 6, Language: "go"}
+
+	src, err := ReadSymbolSource(root, sym, 0, 0)
+	if err != nil {
+		t.Fatalf("ReadSymbolSource: %v", err)
+	}
+	if want := "func Add(a, b int) int {\n\treturn a + b\n}"; src.Source != want {
+		t.Errorf("Source = %q, want %q", src.Source, want)
+	}
+	sum := sha256.Sum256(content)
+	if src.FileHash != hex.EncodeToString(sum[:]) {
+		t.Errorf("FileHash = %s, want sha256 of file", src.FileHash)
+	}
+
+	src, err = ReadSymbolSource(root, sym, 5, 0)
+	if err != nil {
+		t.Fatalf("ReadSymbolSource: %v", err)
+	}
+	if src.FromLine != 1 || src.ToLine != 8 {
+		t.Errorf("range = %d-%d, want clamped 1-8", src.FromLine, src.ToLine)
+	}
+	if !strings.HasPrefix(src.Source, "package a") || !strings.HasSuffix(src.Source, "func Sub() {}") {
+		t.Errorf("unexpected context source: %q", src.Source)
+	}
+}
+
+func TestReadSymbolSource_Truncation(t *testing.T) {
+	root, _ := writeSymbolSourceFixture(t)
+	sym := &ast.Symbol{ID: "a/a.go:1:a", FilePath: "a/a.go", StartLine: 1, EndLine: 8}
+
+	src, err := ReadSymbolSource(root, sym, 0, 20)
+	if err != nil {
+		t.Fatalf("ReadSymbolSource: %v", err)
+	}
+	if !src.Truncated {
+		t.Fatal("expected Truncated")
+	}
+	if !strings.Contains(src.Source, "[truncated:") {
+		t.Errorf("missing truncation marker: %q", src.Source)
+	}
+	if src.SourceBytes <= 20 {
+		t.Errorf("SourceBytes = %d, want full size", src.SourceBytes)
+	}
+}
+
+func TestReadSymbolSource_PathValidation(t *testing.T) {
+	root, _ := writeSymbolSourceFixture(t)
+
+	for _, tc := range []struct {
+		path string
+		want error
+	}{
+		{"", ErrEmptyFilePath},
+		{"../etc/passwd", ErrPathTraversal},
+		{"a/../../x.go", ErrPathTraversal},
+	} {
+		_, err := ReadSymbolSource(root, &ast.Symbol{FilePath: tc.path, StartLine: 1, EndLine: 1}, 0, 0)
+		if !errors.Is(err, tc.want) {
+			t.Errorf("path %q: err = %v, want %v", tc.path, err, tc.want)
+		}
+	}
+}
+
+func TestGraph_SymbolSource_NotFound(t *testing.T) {
+	g := NewGraph(t.TempDir())
+	g.Freeze()
+	if _, err := g.SymbolSource("missing", 0, 0); !errors.Is(err, ErrSymbolNotFound) {
+		t.Errorf("err = %v, want ErrSymbolNotFound", err)
+	}
+}
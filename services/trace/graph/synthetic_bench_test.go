@@ -0,0 +1,170 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+// The BenchmarkSynthetic_ benchmarks are the builder regression suite run
+// by cmd/trace-bench. Their names and corpus sizes are part of the stored
+// baseline: rename or resize them only together with a new baseline.
+
+// syntheticBenchSizes are the corpus sizes of the regression suite.
+var syntheticBenchSizes = []struct {
+	name           string
+	files, symbols int
+}{
+	{"files=200", 200, 10},
+	{"files=2000", 2000, 10},
+}
+
+// syntheticBenchConfig returns the corpus config of a suite size.
+func syntheticBenchConfig(files, symbols int) SyntheticCorpusConfig {
+	cfg := DefaultSyntheticCorpusConfig()
+	cfg.Files = files
+	cfg.SymbolsPerFile = symbols
+	return cfg
+}
+
+// mustSyntheticCorpus generates a fresh corpus. Builds write type metadata
+// into symbols, so each build gets its own corpus.
+func mustSyntheticCorpus(b *testing.B, cfg SyntheticCorpusConfig) *SyntheticCorpus {
+	b.Helper()
+	corpus, err := GenerateSyntheticCorpus(cfg)
+	if err != nil {
+		b.Fatalf("GenerateSyntheticCorpus: %v", err)
+	}
+	return corpus
+}
+
+// silenceBenchLogs discards the builder's logs for the benchmark so they do
+// not interleave with the result lines cmd/trace-bench parses.
+func silenceBenchLogs(b *testing.B) {
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	b.Cleanup(func() { slog.SetDefault(prev) })
+}
+
+// BenchmarkSynthetic_Build benchmarks a full build of the synthetic corpus.
+func BenchmarkSynthetic_Build(b *testing.B) {
+	silenceBenchLogs(b)
+	ctx := context.Background()
+	for _, size := range syntheticBenchSizes {
+		b.Run(size.name, func(b *testing.B) {
+			cfg := syntheticBenchConfig(size.files, size.symbols)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				results := mustSyntheticCorpus(b, cfg).Results
+				b.StartTimer()
+				if _, err := NewBuilder(WithProjectRoot("/synthetic")).Build(ctx, results); err != nil {
+					b.Fatalf("Build: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSynthetic_EdgeExtraction benchmarks the edge extraction phase
+// alone, after symbols are collected.
+func BenchmarkSynthetic_EdgeExtraction(b *testing.B) {
+	silenceBenchLogs(b)
+	ctx := context.Background()
+	for _, size := range syntheticBenchSizes {
+		b.Run(size.name, func(b *testing.B) {
+			cfg := syntheticBenchConfig(size.files, size.symbols)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				results := sortParseResults(mustSyntheticCorpus(b, cfg).Results)
+				builder := NewBuilder(WithProjectRoot("/synthetic"))
+				state := builder.newBuildState(NewGraph("/synthetic"))
+				if err := builder.collectPhase(ctx, state, results); err != nil {
+					b.Fatalf("collectPhase: %v", err)
+				}
+				builder.buildImportNameMap(state)
+				b.StartTimer()
+				if err := builder.extractEdgesPhase(ctx, state, results); err != nil {
+					b.Fatalf("extractEdgesPhase: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSynthetic_FindCallersWithInheritance benchmarks the inherited
+// callers query on the hub methods, the most-called nodes of the corpus.
+func BenchmarkSynthetic_FindCallersWithInheritance(b *testing.B) {
+	silenceBenchLogs(b)
+	ctx := context.Background()
+	for _, size := range syntheticBenchSizes {
+		b.Run(size.name, func(b *testing.B) {
+			corpus := mustSyntheticCorpus(b, syntheticBenchConfig(size.files, size.symbols))
+			result, err := NewBuilder(WithProjectRoot("/synthetic")).Build(ctx, corpus.Results)
+			if err != nil {
+				b.Fatalf("Build: %v", err)
+			}
+			g := result.Graph
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				hub := corpus.Hubs[i%len(corpus.Hubs)]
+				if _, err := g.FindCallersWithInheritance(ctx, hub.MethodID, hub.ParentMethodIDs); err != nil {
+					b.Fatalf("FindCallersWithInheritance: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSynthetic_IncrementalRefresh benchmarks an incremental rebuild
+// after 1% of the files changed.
+func BenchmarkSynthetic_IncrementalRefresh(b *testing.B) {
+	silenceBenchLogs(b)
+	ctx := context.Background()
+	for _, size := range syntheticBenchSizes {
+		b.Run(size.name, func(b *testing.B) {
+			cfg := syntheticBenchConfig(size.files, size.symbols)
+			corpus := mustSyntheticCorpus(b, cfg)
+			base, err := NewBuilder(WithProjectRoot("/synthetic")).Build(ctx, corpus.Results)
+			if err != nil {
+				b.Fatalf("Build: %v", err)
+			}
+			changed := corpus.SampleFiles(0.01)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				changedResults := mustSyntheticCorpus(b, cfg).ResultsFor(changed)
+				b.StartTimer()
+				if _, err := IncrementalRefresh(ctx, base.Graph, changed, changedResults, nil); err != nil {
+					b.Fatalf("IncrementalRefresh: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkSynthetic_Generate benchmarks the corpus generator itself, so a
+// slower generator is not mistaken for a slower builder.
+func BenchmarkSynthetic_Generate(b *testing.B) {
+	cfg := syntheticBenchConfig(2000, 10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := GenerateSyntheticCorpus(cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
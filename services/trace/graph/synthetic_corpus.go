@@ -0,0 +1,512 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// Limits of a SyntheticCorpusConfig.
+const (
+	maxSyntheticFiles          = 1_000_000
+	maxSyntheticSymbolsPerFile = 1000
+	maxSyntheticCallsPerSymbol = 100
+)
+
+// syntheticExtensions maps the languages a synthetic corpus can contain to
+// their file extensions.
+var syntheticExtensions = map[string]string{
+	"go":         ".go",
+	"python":     ".py",
+	"typescript": ".ts",
+	"java":       ".java",
+}
+
+// SyntheticCorpusConfig describes the size and shape of a synthetic corpus.
+//
+// The same config, including Seed, always generates the same corpus, so
+// benchmark results over it are comparable across machines and runs.
+type SyntheticCorpusConfig struct {
+	// Seed seeds the generator.
+	Seed int64
+
+	// Files is the number of source files, not counting hub files.
+	Files int
+
+	// SymbolsPerFile is the number of functions or methods per file.
+	SymbolsPerFile int
+
+	// CallsPerSymbol is the call density: call sites per function.
+	CallsPerSymbol int
+
+	// CrossFileRatio is the fraction of calls, other than hub calls, that
+	// target a function in another file. The calling file imports it.
+	CrossFileRatio float64
+
+	// HubCount is the number of hub methods. Each hub is a base class
+	// method overridden in a derived class (a plain method for Go).
+	HubCount int
+
+	// HubCallRatio is the fraction of calls that target a hub.
+	HubCallRatio float64
+
+	// FilesPerPackage is the number of files per package directory.
+	FilesPerPackage int
+
+	// LanguageMix weights the languages of the files: "go", "python",
+	// "typescript" and "java". Files are assigned languages at random in
+	// proportion to their weights.
+	LanguageMix map[string]int
+}
+
+// DefaultSyntheticCorpusConfig returns a mid-sized mixed-language corpus
+// config: 1000 files of 10 functions with 3 calls each.
+//
+// Outputs:
+//
+//	SyntheticCorpusConfig - The config, with seed 1.
+func DefaultSyntheticCorpusConfig() SyntheticCorpusConfig {
+	return SyntheticCorpusConfig{
+		Seed:            1,
+		Files:           1000,
+		SymbolsPerFile:  10,
+		CallsPerSymbol:  3,
+		CrossFileRatio:  0.3,
+		HubCount:        8,
+		HubCallRatio:    0.05,
+		FilesPerPackage: 20,
+		LanguageMix:     map[string]int{"go": 4, "python": 3, "typescript": 2, "java": 1},
+	}
+}
+
+// Validate checks the config.
+//
+// Outputs:
+//
+//	error - ErrInvalidCorpusConfig describing the first invalid field.
+func (c SyntheticCorpusConfig) Validate() error {
+	switch {
+	case c.Files <= 0 || c.Files > maxSyntheticFiles:
+		return fmt.Errorf("%w: files must be 1-%d, got %d", ErrInvalidCorpusConfig, maxSyntheticFiles, c.Files)
+	case c.SymbolsPerFile <= 0 || c.SymbolsPerFile > maxSyntheticSymbolsPerFile:
+		return fmt.Errorf("%w: symbols per file must be 1-%d, got %d", ErrInvalidCorpusConfig, maxSyntheticSymbolsPerFile, c.SymbolsPerFile)
+	case c.CallsPerSymbol < 0 || c.CallsPerSymbol > maxSyntheticCallsPerSymbol:
+		return fmt.Errorf("%w: calls per symbol must be 0-%d, got %d", ErrInvalidCorpusConfig, maxSyntheticCallsPerSymbol, c.CallsPerSymbol)
+	case c.CrossFileRatio < 0 || c.CrossFileRatio > 1:
+		return fmt.Errorf("%w: cross-file ratio must be in [0, 1], got %v", ErrInvalidCorpusConfig, c.CrossFileRatio)
+	case c.HubCallRatio < 0 || c.HubCallRatio > 1:
+		return fmt.Errorf("%w: hub call ratio must be in [0, 1], got %v", ErrInvalidCorpusConfig, c.HubCallRatio)
+	case c.HubCount < 0:
+		return fmt.Errorf("%w: hub count must not be negative, got %d", ErrInvalidCorpusConfig, c.HubCount)
+	case c.FilesPerPackage <= 0:
+		return fmt.Errorf("%w: files per package must be positive, got %d", ErrInvalidCorpusConfig, c.FilesPerPackage)
+	case len(c.LanguageMix) == 0:
+		return fmt.Errorf("%w: language mix is empty", ErrInvalidCorpusConfig)
+	}
+	for lang, weight := range c.LanguageMix {
+		if _, ok := syntheticExtensions[lang]; !ok {
+			return fmt.Errorf("%w: unsupported language %q", ErrInvalidCorpusConfig, lang)
+		}
+		if weight <= 0 {
+			return fmt.Errorf("%w: weight of %q must be positive, got %d", ErrInvalidCorpusConfig, lang, weight)
+		}
+	}
+	return nil
+}
+
+// SyntheticHub is a hub method of a synthetic corpus, the target of many
+// calls from across the corpus.
+type SyntheticHub struct {
+	// MethodID is the overriding method in the derived class.
+	MethodID string
+
+	// ParentMethodIDs are the base class methods it overrides, for
+	// FindCallersWithInheritance. Empty for Go hubs.
+	ParentMethodIDs []string
+}
+
+// SyntheticCorpus is a generated set of parse results.
+type SyntheticCorpus struct {
+	// Config is the config the corpus was generated from.
+	Config SyntheticCorpusConfig
+
+	// Results holds one parse result per file, hub files first.
+	Results []*ast.ParseResult
+
+	// Hubs are the hub methods, in hub order.
+	Hubs []SyntheticHub
+}
+
+// syntheticFile is the plan of one generated file, fixed before any
+// symbol is generated so calls can target any file.
+type syntheticFile struct {
+	path     string
+	lang     string
+	pkg      string
+	module   string // import path of the file
+	typeName string // Java class holding the functions
+}
+
+// GenerateSyntheticCorpus generates a deterministic synthetic corpus.
+//
+// Description:
+//
+//	Generates parse results shaped like the parsers' output, without
+//	parsing anything. Each language is shaped so the builder resolves
+//	calls the way it does for real code:
+//	  - Go: package functions; cross-package calls are qualified with the
+//	    package name and import it.
+//	  - Python and TypeScript: module functions; cross-file calls import
+//	    the function by name.
+//	  - Java: static methods of one class per file; cross-file calls are
+//	    qualified with the class and import it.
+//	Hub files hold a base class method, overridden in a derived class,
+//	that a share of all calls target as a method call.
+//
+// Inputs:
+//
+//	cfg - The size and shape of the corpus.
+//
+// Outputs:
+//
+//	*SyntheticCorpus - The corpus.
+//	error - ErrInvalidCorpusConfig if cfg is invalid.
+//
+// Thread Safety: Safe for concurrent use; each call has its own generator.
+func GenerateSyntheticCorpus(cfg SyntheticCorpusConfig) (*SyntheticCorpus, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(cfg.Seed))
+
+	langs := make([]string, 0, len(cfg.LanguageMix))
+	totalWeight := 0
+	for lang, weight := range cfg.LanguageMix {
+		langs = append(langs, lang)
+		totalWeight += weight
+	}
+	sort.Strings(langs)
+
+	files := make([]syntheticFile, cfg.Files)
+	filesByLang := make(map[string][]int, len(langs))
+	for i := range files {
+		pick := rng.Intn(totalWeight)
+		lang := langs[len(langs)-1]
+		for _, l := range langs {
+			if pick < cfg.LanguageMix[l] {
+				lang = l
+				break
+			}
+			pick -= cfg.LanguageMix[l]
+		}
+		files[i] = planSyntheticFile(i, lang, cfg.FilesPerPackage)
+		filesByLang[lang] = append(filesByLang[lang], i)
+	}
+
+	corpus := &SyntheticCorpus{
+		Config:  cfg,
+		Results: make([]*ast.ParseResult, 0, cfg.Files+2*cfg.HubCount),
+		Hubs:    make([]SyntheticHub, 0, cfg.HubCount),
+	}
+	hubNames := make([]string, cfg.HubCount)
+	for k := 0; k < cfg.HubCount; k++ {
+		results, hub, name := generateSyntheticHub(k, langs[k%len(langs)])
+		corpus.Results = append(corpus.Results, results...)
+		corpus.Hubs = append(corpus.Hubs, hub)
+		hubNames[k] = name
+	}
+
+	for i := range files {
+		corpus.Results = append(corpus.Results, generateSyntheticFile(rng, cfg, files, filesByLang, hubNames, i))
+	}
+	return corpus, nil
+}
+
+// planSyntheticFile returns the path, package and import path of file i.
+func planSyntheticFile(i int, lang string, filesPerPackage int) syntheticFile {
+	pkg := fmt.Sprintf("pkg%d", i/filesPerPackage)
+	f := syntheticFile{lang: lang, pkg: pkg}
+	switch lang {
+	case "go":
+		f.path = fmt.Sprintf("%s/file%d.go", pkg, i)
+		f.module = "example.com/synthetic/" + pkg
+	case "python":
+		f.path = fmt.Sprintf("%s/file%d.py", pkg, i)
+		f.module = fmt.Sprintf("%s.file%d", pkg, i)
+	case "typescript":
+		f.path = fmt.Sprintf("%s/file%d.ts", pkg, i)
+		f.module = fmt.Sprintf("../%s/file%d", pkg, i)
+	case "java":
+		f.typeName = fmt.Sprintf("File%d", i)
+		f.path = fmt.Sprintf("%s/%s.java", pkg, f.typeName)
+		f.module = fmt.Sprintf("com.synthetic.%s.%s", pkg, f.typeName)
+	}
+	return f
+}
+
+// syntheticFuncName is the name of function s of file i, unique in the
+// corpus so calls resolve without ambiguity.
+func syntheticFuncName(lang string, i, s int) string {
+	if lang == "python" {
+		return fmt.Sprintf("func_%d_%d", i, s)
+	}
+	if lang == "go" {
+		return fmt.Sprintf("Func%d_%d", i, s)
+	}
+	return fmt.Sprintf("func%d_%d", i, s)
+}
+
+// syntheticFuncLine is the start line of function s.
+func syntheticFuncLine(s int) int {
+	return 10 + s*20
+}
+
+// generateSyntheticHub generates the base and derived class files of hub k.
+//
+// Outputs:
+//
+//	[]*ast.ParseResult - The hub files.
+//	SyntheticHub - The hub.
+//	string - The hub method name.
+func generateSyntheticHub(k int, lang string) ([]*ast.ParseResult, SyntheticHub, string) {
+	ext := syntheticExtensions[lang]
+	if lang == "go" {
+		name := fmt.Sprintf("Handle%d", k)
+		path := fmt.Sprintf("hubs/hub%d.go", k)
+		typ := syntheticSymbol(fmt.Sprintf("Hub%d", k), ast.SymbolKindStruct, path, lang, 3, 5)
+		method := syntheticSymbol(name, ast.SymbolKindMethod, path, lang, 7, 12)
+		method.Receiver = typ.Name
+		method.Signature = fmt.Sprintf("func (h *%s) %s() error", typ.Name, name)
+		result := syntheticParseResult(path, lang, "hubs", []*ast.Symbol{typ, method}, nil)
+		return []*ast.ParseResult{result}, SyntheticHub{MethodID: method.ID}, name
+	}
+
+	name := fmt.Sprintf("handle%d", k)
+	baseName := fmt.Sprintf("BaseHandler%d", k)
+	basePath := fmt.Sprintf("hubs/base%d%s", k, ext)
+	baseMethod := syntheticSymbol(name, ast.SymbolKindMethod, basePath, lang, 5, 10)
+	base := syntheticSymbol(baseName, ast.SymbolKindClass, basePath, lang, 3, 12)
+	base.Children = []*ast.Symbol{baseMethod}
+
+	derivedPath := fmt.Sprintf("hubs/derived%d%s", k, ext)
+	derivedMethod := syntheticSymbol(name, ast.SymbolKindMethod, derivedPath, lang, 7, 12)
+	derived := syntheticSymbol(fmt.Sprintf("Handler%d", k), ast.SymbolKindClass, derivedPath, lang, 5, 14)
+	derived.Children = []*ast.Symbol{derivedMethod}
+	derived.Metadata = &ast.SymbolMetadata{Extends: baseName}
+
+	baseModule := fmt.Sprintf("hubs/base%d", k)
+	switch lang {
+	case "python":
+		baseModule = fmt.Sprintf("hubs.base%d", k)
+	case "typescript":
+		baseModule = fmt.Sprintf("./base%d", k)
+	case "java":
+		baseModule = fmt.Sprintf("com.synthetic.hubs.%s", baseName)
+	}
+	imports := []ast.Import{{
+		Path:     baseModule,
+		Names:    []string{baseName},
+		Location: ast.Location{FilePath: derivedPath, StartLine: 1},
+	}}
+
+	results := []*ast.ParseResult{
+		syntheticParseResult(basePath, lang, "hubs", []*ast.Symbol{base}, nil),
+		syntheticParseResult(derivedPath, lang, "hubs", []*ast.Symbol{derived}, imports),
+	}
+	return results, SyntheticHub{MethodID: derivedMethod.ID, ParentMethodIDs: []string{baseMethod.ID}}, name
+}
+
+// generateSyntheticFile generates the parse result of file i.
+func generateSyntheticFile(
+	rng *rand.Rand,
+	cfg SyntheticCorpusConfig,
+	files []syntheticFile,
+	filesByLang map[string][]int,
+	hubNames []string,
+	i int,
+) *ast.ParseResult {
+	file := files[i]
+	sameLang := filesByLang[file.lang]
+	imported := make(map[string]int) // module -> index in imports
+	var imports []ast.Import
+
+	addImport := func(target syntheticFile, name string) {
+		if at, ok := imported[target.module]; ok {
+			if len(imports[at].Names) > 0 && !containsString(imports[at].Names, name) {
+				imports[at].Names = append(imports[at].Names, name)
+			}
+			return
+		}
+		imp := ast.Import{Path: target.module, Location: ast.Location{FilePath: file.path, StartLine: len(imports) + 1}}
+		switch file.lang {
+		case "go":
+			imp.Alias = target.pkg
+		case "python", "typescript":
+			imp.Names = []string{name}
+		}
+		imported[target.module] = len(imports)
+		imports = append(imports, imp)
+	}
+
+	funcs := make([]*ast.Symbol, cfg.SymbolsPerFile)
+	for s := range funcs {
+		line := syntheticFuncLine(s)
+		kind := ast.SymbolKindFunction
+		if file.lang == "java" {
+			kind = ast.SymbolKindMethod
+		}
+		fn := syntheticSymbol(syntheticFuncName(file.lang, i, s), kind, file.path, file.lang, line, line+15)
+		fn.Calls = make([]ast.CallSite, 0, cfg.CallsPerSymbol)
+
+		for c := 0; c < cfg.CallsPerSymbol; c++ {
+			loc := ast.Location{FilePath: file.path, StartLine: line + 1 + c%14}
+			if len(hubNames) > 0 && rng.Float64() < cfg.HubCallRatio {
+				fn.Calls = append(fn.Calls, ast.CallSite{
+					Target:   hubNames[rng.Intn(len(hubNames))],
+					IsMethod: true,
+					Receiver: "handler",
+					Location: loc,
+				})
+				continue
+			}
+
+			j, t := i, rng.Intn(cfg.SymbolsPerFile)
+			if len(sameLang) > 1 && rng.Float64() < cfg.CrossFileRatio {
+				for j == i {
+					j = sameLang[rng.Intn(len(sameLang))]
+				}
+			} else if cfg.SymbolsPerFile > 1 && t == s {
+				t = (t + 1) % cfg.SymbolsPerFile
+			}
+			target := files[j]
+			name := syntheticFuncName(target.lang, j, t)
+
+			call := ast.CallSite{Target: name, Location: loc}
+			if j != i {
+				switch {
+				case file.lang == "go" && target.pkg != file.pkg:
+					call.Target = target.pkg + "." + name
+					addImport(target, name)
+				case file.lang == "java":
+					call.Target = target.typeName + "." + name
+					if target.pkg != file.pkg {
+						addImport(target, name)
+					}
+				case file.lang == "python" || file.lang == "typescript":
+					addImport(target, name)
+				}
+			}
+			fn.Calls = append(fn.Calls, call)
+		}
+		funcs[s] = fn
+	}
+
+	symbols := funcs
+	if file.lang == "java" {
+		class := syntheticSymbol(file.typeName, ast.SymbolKindClass, file.path, file.lang, 3, syntheticFuncLine(cfg.SymbolsPerFile))
+		class.Children = funcs
+		symbols = []*ast.Symbol{class}
+	}
+	return syntheticParseResult(file.path, file.lang, file.pkg, symbols, imports)
+}
+
+// syntheticSymbol returns a symbol with its ID and location set.
+func syntheticSymbol(name string, kind ast.SymbolKind, path, lang string, start, end int) *ast.Symbol {
+	exported := lang != "go" || strings.ToUpper(name[:1]) == name[:1]
+	return &ast.Symbol{
+		ID:        ast.GenerateID(path, start, name),
+		Name:      name,
+		Kind:      kind,
+		FilePath:  path,
+		StartLine: start,
+		EndLine:   end,
+		EndCol:    1,
+		Language:  lang,
+		Exported:  exported,
+	}
+}
+
+// syntheticParseResult returns the parse result of a generated file.
+func syntheticParseResult(path, lang, pkg string, symbols []*ast.Symbol, imports []ast.Import) *ast.ParseResult {
+	return &ast.ParseResult{
+		FilePath: path,
+		Language: lang,
+		Package:  pkg,
+		Symbols:  symbols,
+		Imports:  imports,
+	}
+}
+
+// SymbolCount returns the number of symbols in the corpus, including
+// class and method children.
+func (c *SyntheticCorpus) SymbolCount() int {
+	count := 0
+	var walk func([]*ast.Symbol)
+	walk = func(symbols []*ast.Symbol) {
+		for _, sym := range symbols {
+			count++
+			walk(sym.Children)
+		}
+	}
+	for _, r := range c.Results {
+		walk(r.Symbols)
+	}
+	return count
+}
+
+// SampleFiles returns a deterministic sample of fraction of the non-hub
+// files, evenly spread, for incremental rebuild benchmarks.
+//
+// Inputs:
+//
+//	fraction - The share of files to return, in (0, 1]. At least one
+//	  file is returned.
+//
+// Outputs:
+//
+//	[]string - The file paths, in corpus order.
+func (c *SyntheticCorpus) SampleFiles(fraction float64) []string {
+	files := c.Results[len(c.Results)-c.Config.Files:]
+	n := int(float64(len(files)) * fraction)
+	if n < 1 {
+		n = 1
+	}
+	if n > len(files) {
+		n = len(files)
+	}
+	step := len(files) / n
+	paths := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		paths = append(paths, files[i*step].FilePath)
+	}
+	return paths
+}
+
+// ResultsFor returns the parse results of the given files, in the given
+// order. Unknown paths are skipped.
+func (c *SyntheticCorpus) ResultsFor(paths []string) []*ast.ParseResult {
+	byPath := make(map[string]*ast.ParseResult, len(c.Results))
+	for _, r := range c.Results {
+		byPath[r.FilePath] = r
+	}
+	results := make([]*ast.ParseResult, 0, len(paths))
+	for _, p := range paths {
+		if r, ok := byPath[p]; ok {
+			results = append(results, r)
+		}
+	}
+	return results
+}
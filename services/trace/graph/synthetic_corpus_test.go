@@ -0,0 +1,120 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestGenerateSyntheticCorpus_Deterministic(t *testing.T) {
+	cfg := DefaultSyntheticCorpusConfig()
+	cfg.Files = 150
+
+	encode := func(cfg SyntheticCorpusConfig) string {
+		t.Helper()
+		corpus, err := GenerateSyntheticCorpus(cfg)
+		if err != nil {
+			t.Fatalf("GenerateSyntheticCorpus: %v", err)
+		}
+		data, err := json.Marshal(corpus)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	first := encode(cfg)
+	if encode(cfg) != first {
+		t.Error("same config generated different corpora")
+	}
+	cfg.Seed++
+	if encode(cfg) == first {
+		t.Error("different seeds generated the same corpus")
+	}
+}
+
+func TestGenerateSyntheticCorpus_Shape(t *testing.T) {
+	cfg := DefaultSyntheticCorpusConfig()
+	cfg.Files = 300
+	corpus, err := GenerateSyntheticCorpus(cfg)
+	if err != nil {
+		t.Fatalf("GenerateSyntheticCorpus: %v", err)
+	}
+
+	// Go hubs have one file, the others a base and a derived class file.
+	hubFiles := len(corpus.Results) - cfg.Files
+	if hubFiles < cfg.HubCount || hubFiles > 2*cfg.HubCount {
+		t.Errorf("%d hub files for %d hubs", hubFiles, cfg.HubCount)
+	}
+	languages := make(map[string]int)
+	for _, r := range corpus.Results[hubFiles:] {
+		languages[r.Language]++
+	}
+	for lang := range cfg.LanguageMix {
+		if languages[lang] == 0 {
+			t.Errorf("no %s files in the mix", lang)
+		}
+	}
+	if languages["go"] <= languages["java"] {
+		t.Errorf("language counts %v do not follow the weights", languages)
+	}
+
+	sample := corpus.SampleFiles(0.01)
+	if len(sample) != 3 || len(corpus.ResultsFor(sample)) != 3 {
+		t.Errorf("1%% sample of %d files = %v", cfg.Files, sample)
+	}
+
+	result, err := NewBuilder(WithProjectRoot("/synthetic")).Build(context.Background(), corpus.Results)
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if result.Stats.CallEdgesResolved == 0 {
+		t.Fatal("no calls resolved")
+	}
+	for _, hub := range corpus.Hubs {
+		callers, err := result.Graph.FindCallersWithInheritance(context.Background(), hub.MethodID, hub.ParentMethodIDs)
+		if err != nil {
+			t.Fatalf("FindCallersWithInheritance(%s): %v", hub.MethodID, err)
+		}
+		if len(callers.AllCallers().Symbols) == 0 {
+			t.Errorf("hub %s has no callers", hub.MethodID)
+		}
+	}
+}
+
+func TestSyntheticCorpusConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*SyntheticCorpusConfig)
+	}{
+		{"no files", func(c *SyntheticCorpusConfig) { c.Files = 0 }},
+		{"no symbols", func(c *SyntheticCorpusConfig) { c.SymbolsPerFile = 0 }},
+		{"negative calls", func(c *SyntheticCorpusConfig) { c.CallsPerSymbol = -1 }},
+		{"cross-file ratio above 1", func(c *SyntheticCorpusConfig) { c.CrossFileRatio = 1.5 }},
+		{"negative hub ratio", func(c *SyntheticCorpusConfig) { c.HubCallRatio = -0.1 }},
+		{"no packages", func(c *SyntheticCorpusConfig) { c.FilesPerPackage = 0 }},
+		{"empty mix", func(c *SyntheticCorpusConfig) { c.LanguageMix = nil }},
+		{"unknown language", func(c *SyntheticCorpusConfig) { c.LanguageMix = map[string]int{"cobol": 1} }},
+		{"zero weight", func(c *SyntheticCorpusConfig) { c.LanguageMix = map[string]int{"go": 0} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultSyntheticCorpusConfig()
+			tt.mutate(&cfg)
+			if _, err := GenerateSyntheticCorpus(cfg); !errors.Is(err, ErrInvalidCorpusConfig) {
+				t.Errorf("error = %v, want ErrInvalidCorpusConfig", err)
+			}
+		})
+	}
+}
@@ -14,6 +14,8 @@ import (
 	"fmt"
 	"hash/fnv"
 	"sort"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
@@ -85,6 +87,12 @@ const (
 	// EdgeTypeParameters indicates a function takes a type as parameter.
 	EdgeTypeParameters
 
+	// EdgeTypeVariantOf links platform variants of one declaration: Go
+	// symbols with the same name in the same package, declared in files
+	// with different build constraints (foo_linux.go, foo_windows.go).
+	// Only created when the builder includes all build profiles.
+	EdgeTypeVariantOf
+
 	// NumEdgeTypes is the total number of edge types (for array sizing).
 	// GR-08: Used for edgesByType index.
 	NumEdgeTypes
@@ -102,6 +110,7 @@ var edgeTypeNames = map[EdgeType]string{
 	EdgeTypeReturns:    "returns",
 	EdgeTypeReceives:   "receives",
 	EdgeTypeParameters: "parameters",
+	EdgeTypeVariantOf:  "variant_of",
 }
 
 // String returns the string representation of the EdgeType.
@@ -112,6 +121,21 @@ func (t EdgeType) String() string {
 	return "unknown"
 }
 
+// ParseEdgeType converts a string (e.g., "calls") to an EdgeType.
+//
+// Outputs:
+//
+//	EdgeType - The matching type, or EdgeTypeUnknown.
+//	bool - False if the name is not a known edge type.
+func ParseEdgeType(s string) (EdgeType, bool) {
+	for t, name := range edgeTypeNames {
+		if name == s && t != EdgeTypeUnknown {
+			return t, true
+		}
+	}
+	return EdgeTypeUnknown, false
+}
+
 // Edge represents a directed relationship between two symbols.
 //
 // Multiple edges of the same type between the same nodes are allowed,
@@ -130,6 +154,10 @@ type Edge struct {
 
 	// Location is where the relationship is expressed in code.
 	Location ast.Location
+
+	// Provenance records the builder pass that created the edge and how
+	// sure it was of the target. Zero for edges added with AddEdge.
+	Provenance EdgeProvenance
 }
 
 // Node represents a symbol in the code graph with its relationships.
@@ -153,6 +181,38 @@ type Node struct {
 	// For example, if this node is a function, Incoming contains
 	// all the functions that call it.
 	Incoming []*Edge
+
+	// syntheticDoc is the generated summary set by SetSyntheticDoc. It is
+	// held outside Symbol so it can be set on a frozen graph.
+	syntheticDoc atomic.Pointer[string]
+}
+
+// SyntheticDoc returns the generated one-sentence summary of an
+// undocumented symbol, or "" if none has been set.
+//
+// Thread Safety: Safe for concurrent use.
+func (n *Node) SyntheticDoc() string {
+	if doc := n.syntheticDoc.Load(); doc != nil {
+		return *doc
+	}
+	return ""
+}
+
+// SetSyntheticDoc sets the generated summary of the node's symbol. A real
+// doc comment is never shadowed: nothing is set for a symbol with a
+// DocComment, or for a node without a symbol.
+//
+// Outputs:
+//
+//	bool - True if doc was set.
+//
+// Thread Safety: Safe for concurrent use, including on a frozen graph.
+func (n *Node) SetSyntheticDoc(doc string) bool {
+	if n.Symbol == nil || strings.TrimSpace(n.Symbol.DocComment) != "" {
+		return false
+	}
+	n.syntheticDoc.Store(&doc)
+	return true
 }
 
 // GraphOptions configures Graph behavior and limits.
@@ -164,13 +224,25 @@ type GraphOptions struct {
 	// MaxEdges is the maximum number of edges the graph can hold.
 	// Default: 10,000,000
 	MaxEdges int
+
+	// DisableStringInterning turns off deduplication of file paths,
+	// languages, packages and edge endpoint IDs during building.
+	// Default: false (interning enabled)
+	DisableStringInterning bool
+
+	// MaxLiteralUsages is the maximum number of call-site literal
+	// arguments indexed by Freeze() for FindLiteralUsages. Literals past
+	// the cap, in file order, are counted but not searchable.
+	// Default: DefaultMaxLiteralUsages
+	MaxLiteralUsages int
 }
 
 // DefaultGraphOptions returns sensible defaults for graph configuration.
 func DefaultGraphOptions() GraphOptions {
 	return GraphOptions{
-		MaxNodes: DefaultMaxNodes,
-		MaxEdges: DefaultMaxEdges,
+		MaxNodes:         DefaultMaxNodes,
+		MaxEdges:         DefaultMaxEdges,
+		MaxLiteralUsages: DefaultMaxLiteralUsages,
 	}
 }
 
@@ -191,6 +263,24 @@ func WithMaxEdges(n int) GraphOption {
 	}
 }
 
+// WithMaxLiteralUsages sets the maximum number of call-site literal
+// arguments the graph indexes.
+func WithMaxLiteralUsages(n int) GraphOption {
+	return func(o *GraphOptions) {
+		o.MaxLiteralUsages = n
+	}
+}
+
+// WithStringInterning enables or disables string interning while building.
+//
+// Disable it when adding symbols that are shared with another live graph,
+// since interning may rewrite string fields of the added symbols.
+func WithStringInterning(enabled bool) GraphOption {
+	return func(o *GraphOptions) {
+		o.DisableStringInterning = !enabled
+	}
+}
+
 // Graph represents the code relationship graph for a project.
 //
 // Thread Safety:
@@ -240,6 +330,21 @@ type Graph struct {
 	// Thread safety: Writes during build only, reads after Freeze().
 	edgesByFile map[string][]*Edge
 
+	// fileIntervals maps file path to that file's nodes ordered by source
+	// range (see buildFileIntervals). Built by Freeze() for position
+	// lookups; nil while building.
+	fileIntervals map[string][]*Node
+
+	// nodesByStableID maps Symbol.StableID to nodes. Built by Freeze()
+	// (see buildStableIndex); nil while building.
+	nodesByStableID map[string]*Node
+
+	// literalUsages holds the indexed call-site literal arguments and
+	// literalUsagesDropped the number over the cap. Built by Freeze()
+	// (see buildLiteralIndex); nil while building.
+	literalUsages        []LiteralUsage
+	literalUsagesDropped int
+
 	// state is the current lifecycle state.
 	state GraphState
 
@@ -250,10 +355,68 @@ type Graph struct {
 	// Zero if the graph has not been frozen.
 	BuiltAtMilli int64
 
+	// generation identifies this frozen graph within the process. Set by
+	// Freeze(); see Generation.
+	generation uint64
+
 	// FileMtimes records file modification times (Unix seconds) at build time.
 	// CRS-19: Used for staleness detection across sessions. Populated by
 	// RecordFileMtimes() after Freeze(). Key is relative file path.
 	FileMtimes map[string]int64
+
+	// FileHashes maps each relative file path to the SHA256 hash of its
+	// content at parse time (ast.ParseResult.Hash), for every file given to
+	// the builder, including those the build profile excluded. Populated by
+	// RecordFileHashes() after Freeze(); nil for graphs built without hashes.
+	FileHashes map[string]string
+
+	// FileOwners maps each relative file path to its CODEOWNERS owners.
+	// Populated by RecordFileOwners() after Freeze(); files without owners
+	// are absent. Nil when the project has no CODEOWNERS file.
+	FileOwners map[string][]string
+
+	// ownersOf resolves owners of paths not in FileOwners, e.g. new files.
+	// Set by RecordFileOwners.
+	ownersOf func(filePath string) []string
+
+	// annotations looks up symbol annotations by stable ID. Set by
+	// RecordAnnotations; nil when annotations are not recorded.
+	annotations AnnotationSource
+
+	// ExternalPackages maps external node IDs to the third-party package
+	// providing them. Populated by RecordExternalPackages() after Freeze();
+	// nodes whose package the project does not declare are absent.
+	ExternalPackages map[string]ExternalPackage
+
+	// QualityReport describes how completely the graph captures the
+	// project. Set by the builder after Freeze(); nil for graphs that were
+	// not built by Builder.Build.
+	QualityReport *QualityReport
+
+	// PythonSourceRoots are the roots the builder resolved Python imports
+	// against (BuilderOptions.PythonSourceRoots), kept so an incremental
+	// refresh resolves the same way. Kept by ToSerializable snapshots,
+	// not by MaterializeToDisk.
+	PythonSourceRoots []PythonSourceRoot
+
+	// GoModules are the Go modules the builder resolved Go imports
+	// against (BuilderOptions.GoModules), kept like PythonSourceRoots.
+	GoModules []GoModule
+
+	// PlaceholderPolicy is the policy the builder represented external
+	// symbols with (BuilderOptions.PlaceholderPolicy), kept so an
+	// incremental refresh follows it. Kept by ToSerializable snapshots,
+	// not by MaterializeToDisk.
+	PlaceholderPolicy PlaceholderPolicy
+
+	// validation is the last recorded validation report. Held outside the
+	// frozen data so it can be set on a frozen graph; see Validation.
+	validation atomic.Pointer[ValidationReport]
+
+	// strings deduplicates repeated strings while building. Created lazily
+	// by intern and dropped by Freeze; the interned strings stay referenced
+	// by the nodes and edges that use them.
+	strings *stringTable
 }
 
 // NewGraph creates a new empty graph for the given project root.
@@ -348,8 +511,34 @@ func (g *Graph) Freeze() {
 	// GR-06/07/08: Validate secondary indexes before freezing
 	g.validateIndexes()
 
+	// The string table is only needed to deduplicate future additions.
+	g.strings = nil
+
+	g.buildFileIntervals()
+	g.buildStableIndex()
+	g.buildLiteralIndex()
+
 	g.state = GraphStateReadOnly
 	g.BuiltAtMilli = time.Now().UnixMilli()
+	g.generation = freezeGenerations.Add(1)
+}
+
+// freezeGenerations numbers the graphs frozen by this process.
+var freezeGenerations atomic.Uint64
+
+// Generation returns the number identifying this frozen graph among the
+// graphs frozen by the process, or 0 if the graph is not frozen.
+//
+// Description:
+//
+//	Every Freeze() takes a new number, so an incremental refresh or a
+//	reload from a snapshot yields a new generation even when the content
+//	is unchanged. Query cursors (see EncodeCursor) record it to detect
+//	that the graph they were issued against is gone.
+//
+// Thread Safety: Safe for concurrent use after Freeze().
+func (g *Graph) Generation() uint64 {
+	return g.generation
 }
 
 // sortEdges sorts a slice of edges by (FromID, ToID, Type, FilePath, StartLine)
@@ -482,7 +671,10 @@ func (g *Graph) EdgeCount() int {
 // Ownership:
 //
 //	The graph stores a pointer to the symbol but does NOT own it.
-//	The symbol MUST NOT be mutated after this call.
+//	The symbol MUST NOT be mutated after this call. Unless interning is
+//	disabled, AddNode itself replaces the symbol's FilePath, Language,
+//	Package and Receiver (and call-site/type-reference paths) with equal
+//	strings from the graph's string table before storing it.
 func (g *Graph) AddNode(symbol *ast.Symbol) (*Node, error) {
 	if g.state == GraphStateReadOnly {
 		return nil, ErrGraphFrozen
@@ -500,6 +692,8 @@ func (g *Graph) AddNode(symbol *ast.Symbol) (*Node, error) {
 		return nil, fmt.Errorf("%w: %s", ErrDuplicateNode, symbol.ID)
 	}
 
+	g.internSymbol(symbol)
+
 	node := &Node{
 		ID:       symbol.ID,
 		Symbol:   symbol,
@@ -565,6 +759,26 @@ func (g *Graph) GetNode(id string) (*Node, bool) {
 //	ErrNodeNotFound - Source or target node doesn't exist
 //	ErrMaxEdgesExceeded - Graph is at edge capacity
 func (g *Graph) AddEdge(fromID, toID string, edgeType EdgeType, loc ast.Location) error {
+	return g.AddEdgeWithProvenance(fromID, toID, edgeType, loc, EdgeProvenance{})
+}
+
+// AddEdgeWithProvenance creates a directed edge that records how it was
+// created.
+//
+// Description:
+//
+//	Same as AddEdge, with the edge's Provenance set to prov. The
+//	provenance detail is interned with the file paths.
+//
+// Inputs:
+//
+//	fromID, toID, edgeType, loc - As for AddEdge.
+//	prov - The pass and confidence that produced the edge.
+//
+// Outputs:
+//
+//	error - As for AddEdge.
+func (g *Graph) AddEdgeWithProvenance(fromID, toID string, edgeType EdgeType, loc ast.Location, prov EdgeProvenance) error {
 	if g.state == GraphStateReadOnly {
 		return ErrGraphFrozen
 	}
@@ -583,11 +797,21 @@ func (g *Graph) AddEdge(fromID, toID string, edgeType EdgeType, loc ast.Location
 		return fmt.Errorf("%w: target %s", ErrNodeNotFound, toID)
 	}
 
+	// Share the node IDs rather than keeping the caller's copies; decoded
+	// and placeholder IDs are separate allocations per edge.
+	if !g.options.DisableStringInterning {
+		fromID, toID = fromNode.ID, toNode.ID
+		loc.FilePath = g.intern(loc.FilePath)
+		if prov.Detail != "" {
+			prov.Detail = g.intern(prov.Detail)
+		}
+	}
 	edge := &Edge{
-		FromID:   fromID,
-		ToID:     toID,
-		Type:     edgeType,
-		Location: loc,
+		FromID:     fromID,
+		ToID:       toID,
+		Type:       edgeType,
+		Location:   loc,
+		Provenance: prov,
 	}
 
 	g.edges = append(g.edges, edge)
@@ -660,6 +884,9 @@ func (g *Graph) ReplaceEdgeTarget(edge *Edge, newToID string) error {
 
 	// Update edge target
 	edge.ToID = newToID
+	if !g.options.DisableStringInterning {
+		edge.ToID = newTarget.ID
+	}
 
 	// Add to new target's Incoming slice
 	newTarget.Incoming = append(newTarget.Incoming, edge)
@@ -891,6 +1118,11 @@ type GraphStats struct {
 
 	// BuiltAtMilli is when Freeze() was called (0 if not frozen).
 	BuiltAtMilli int64
+
+	// LiteralUsages is the number of indexed call-site literal arguments
+	// and LiteralUsagesDropped the number over MaxLiteralUsages.
+	LiteralUsages        int
+	LiteralUsagesDropped int
 }
 
 // Stats returns statistics about the graph.
@@ -939,6 +1171,9 @@ func (g *Graph) Stats() GraphStats {
 		MaxEdges:     g.options.MaxEdges,
 		State:        g.state,
 		BuiltAtMilli: g.BuiltAtMilli,
+
+		LiteralUsages:        len(g.literalUsages),
+		LiteralUsagesDropped: g.literalUsagesDropped,
 	}
 }
 
@@ -976,6 +1211,10 @@ func (g *Graph) Clone() *Graph {
 		state:        GraphStateBuilding, // Allow modifications on clone
 		options:      g.options,
 		BuiltAtMilli: g.BuiltAtMilli,
+
+		PythonSourceRoots: g.PythonSourceRoots,
+		GoModules:         g.GoModules,
+		PlaceholderPolicy: g.PlaceholderPolicy,
 	}
 
 	// First pass: clone all nodes and update node indexes
@@ -1008,10 +1247,11 @@ func (g *Graph) Clone() *Graph {
 	// Second pass: clone edges, update node references, and update edge index
 	for _, edge := range g.edges {
 		clonedEdge := &Edge{
-			FromID:   edge.FromID,
-			ToID:     edge.ToID,
-			Type:     edge.Type,
-			Location: edge.Location,
+			FromID:     edge.FromID,
+			ToID:       edge.ToID,
+			Type:       edge.Type,
+			Location:   edge.Location,
+			Provenance: edge.Provenance,
 		}
 		clone.edges = append(clone.edges, clonedEdge)
 
@@ -1258,6 +1498,8 @@ func (g *Graph) MergeParseResult(result *ast.ParseResult) (int, error) {
 			return added, ErrMaxNodesExceeded
 		}
 
+		g.internSymbol(sym)
+
 		node := &Node{
 			ID:       sym.ID,
 			Symbol:   sym,
@@ -1293,8 +1535,12 @@ func (g *Graph) MergeParseResult(result *ast.ParseResult) (int, error) {
 //
 // Outputs:
 //
-//	[]*Node - Nodes from that file. Empty slice if none found.
+//	[]*Node - Nodes from that file. Empty slice if none found. On a
+//	frozen graph the nodes are in source order (see SymbolsInFile).
 func (g *Graph) GetNodesByFile(filePath string) []*Node {
+	if g.fileIntervals != nil {
+		return g.SymbolsInFile(filePath)
+	}
 	result := make([]*Node, 0)
 	for _, node := range g.nodes {
 		if node.Symbol != nil && node.Symbol.FilePath == filePath {
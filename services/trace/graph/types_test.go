@@ -78,6 +78,7 @@ func TestEdgeType_String(t *testing.T) {
 		{EdgeTypeReturns, "returns"},
 		{EdgeTypeReceives, "receives"},
 		{EdgeTypeParameters, "parameters"},
+		{EdgeTypeVariantOf, "variant_of"},
 		{EdgeType(99), "unknown"},
 	}
 
@@ -0,0 +1,729 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// Validation modes.
+const (
+	// ValidationModeFull checks every node, edge and index.
+	ValidationModeFull = "full"
+
+	// ValidationModeTouched checks the nodes and files an incremental
+	// refresh or compaction changed, and their neighbours.
+	ValidationModeTouched = "touched"
+)
+
+// Violation kinds.
+const (
+	// ViolationDanglingEdge is an edge whose FromID or ToID is not a node.
+	ViolationDanglingEdge = "dangling_edge"
+
+	// ViolationAdjacency is an edge missing from, or misplaced in, the
+	// Outgoing or Incoming list of one of its endpoints.
+	ViolationAdjacency = "adjacency_mismatch"
+
+	// ViolationNilSymbol is a node of a frozen graph without a symbol.
+	ViolationNilSymbol = "nil_symbol"
+
+	// ViolationNodeIndex is a node map, name, kind or stable ID index
+	// entry that disagrees with the nodes.
+	ViolationNodeIndex = "node_index"
+
+	// ViolationEdgeIndex is an edge list, type or file index entry that
+	// disagrees with the edges.
+	ViolationEdgeIndex = "edge_index"
+
+	// ViolationFileIndex is a file interval index entry that disagrees
+	// with the nodes.
+	ViolationFileIndex = "file_index"
+)
+
+// MaxReportedViolations bounds ValidationReport.Violations. Further
+// violations are counted but not listed.
+const MaxReportedViolations = 100
+
+// Violation is one broken graph invariant.
+type Violation struct {
+	// Kind is one of the Violation* constants.
+	Kind string `json:"kind"`
+
+	// NodeID is the node concerned, if any.
+	NodeID string `json:"node_id,omitempty"`
+
+	// FromID, ToID and EdgeType identify the edge concerned, if any.
+	FromID   string `json:"from_id,omitempty"`
+	ToID     string `json:"to_id,omitempty"`
+	EdgeType string `json:"edge_type,omitempty"`
+
+	// FilePath is the file of the node or edge, if known.
+	FilePath string `json:"file_path,omitempty"`
+
+	// Detail describes what is wrong.
+	Detail string `json:"detail"`
+
+	// Carried is true for a violation found by an earlier check, outside
+	// the region this check covered, and not verified since.
+	Carried bool `json:"carried,omitempty"`
+}
+
+// ValidationReport is the outcome of a graph validation.
+//
+// Thread Safety: Immutable after construction.
+type ValidationReport struct {
+	// Mode is ValidationModeFull or ValidationModeTouched.
+	Mode string `json:"mode"`
+
+	// CheckedAtMilli is when the check ran (Unix milliseconds UTC).
+	CheckedAtMilli int64 `json:"checked_at_milli"`
+
+	// DurationMilli is how long the check took.
+	DurationMilli int64 `json:"duration_ms"`
+
+	// NodesChecked and EdgesChecked count what the check visited.
+	NodesChecked int `json:"nodes_checked"`
+	EdgesChecked int `json:"edges_checked"`
+
+	// ViolationCount is the number of violations the check found,
+	// including those beyond MaxReportedViolations.
+	ViolationCount int `json:"violation_count"`
+
+	// ViolationsByKind counts the violations found by kind.
+	ViolationsByKind map[string]int `json:"violations_by_kind,omitempty"`
+
+	// CarriedViolations counts violations of the previous report that lie
+	// outside the region a touched check covered. They stay until a full
+	// check or a rebuild.
+	CarriedViolations int `json:"carried_violations,omitempty"`
+
+	// Violations lists the first violations found, then carried ones, at
+	// most MaxReportedViolations.
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Degraded reports whether the graph broke any invariant, now or in a
+// check not yet superseded.
+func (r *ValidationReport) Degraded() bool {
+	return r != nil && (r.ViolationCount > 0 || r.CarriedViolations > 0)
+}
+
+// Err returns an error describing the violations, or nil if there are
+// none.
+func (r *ValidationReport) Err() error {
+	if !r.Degraded() {
+		return nil
+	}
+	total := r.ViolationCount + r.CarriedViolations
+	if len(r.Violations) == 0 {
+		return fmt.Errorf("graph validation: %d violations", total)
+	}
+	first := r.Violations[0]
+	return fmt.Errorf("graph validation: %d violations, first: %s: %s", total, first.Kind, first.Detail)
+}
+
+// Validate checks the graph's invariants.
+//
+// Description:
+//
+//	Verifies that every edge's FromID and ToID resolve to nodes, that
+//	each edge is in its source's Outgoing and its target's Incoming list
+//	and nowhere else, that a frozen graph has no node without a symbol,
+//	and that the secondary indexes (names, kinds, stable IDs, edge types
+//	and files, file intervals) list exactly the live nodes and edges
+//	under the right keys. Violations are reported, never panicked on.
+//	The report is not recorded on the graph; see SetValidation.
+//
+// Outputs:
+//
+//	*ValidationReport - The violations found. Never nil.
+//
+// Limitations:
+//
+//	O(V + E) time and memory. Incremental refreshes and compactions run
+//	a cheaper check of the region they changed instead.
+//
+// Thread Safety: Safe for concurrent use on frozen graphs.
+func (g *Graph) Validate() *ValidationReport {
+	v := newValidator(g, ValidationModeFull)
+
+	edges := make(map[*Edge]struct{}, len(g.edges))
+	for i, e := range g.edges {
+		if e == nil {
+			v.add(Violation{Kind: ViolationEdgeIndex, Detail: fmt.Sprintf("edge list entry %d is nil", i)})
+			continue
+		}
+		edges[e] = struct{}{}
+	}
+	v.report.EdgesChecked = len(edges)
+
+	ids := make([]string, 0, len(g.nodes))
+	for id := range g.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	outgoing := make(map[*Edge]struct{}, len(edges))
+	incoming := make(map[*Edge]struct{}, len(edges))
+	for _, id := range ids {
+		node := g.nodes[id]
+		if !v.checkNode(id, node) {
+			continue
+		}
+		for _, e := range node.Outgoing {
+			if e == nil {
+				continue
+			}
+			outgoing[e] = struct{}{}
+			if _, ok := edges[e]; !ok {
+				v.add(edgeViolation(ViolationAdjacency, e, "in the Outgoing list of "+id+" but not in the graph's edge list"))
+			}
+		}
+		for _, e := range node.Incoming {
+			if e == nil {
+				continue
+			}
+			incoming[e] = struct{}{}
+			if _, ok := edges[e]; !ok {
+				v.add(edgeViolation(ViolationAdjacency, e, "in the Incoming list of "+id+" but not in the graph's edge list"))
+			}
+		}
+	}
+
+	for _, e := range g.edges {
+		if e == nil {
+			continue
+		}
+		_, fromOK := g.nodes[e.FromID]
+		_, toOK := g.nodes[e.ToID]
+		if !fromOK || !toOK {
+			v.add(danglingViolation(e, fromOK))
+		}
+		if _, ok := outgoing[e]; fromOK && !ok {
+			v.add(edgeViolation(ViolationAdjacency, e, "missing from the Outgoing list of its source"))
+		}
+		if _, ok := incoming[e]; toOK && !ok {
+			v.add(edgeViolation(ViolationAdjacency, e, "missing from the Incoming list of its target"))
+		}
+	}
+
+	kinds := make(map[string][]*Node, len(g.nodesByKind))
+	for kind, nodes := range g.nodesByKind {
+		kinds[kind.String()] = nodes
+	}
+	v.checkNodeIndex(ViolationNodeIndex, "name", g.nodesByName, func(n *Node) string {
+		return n.Symbol.Name
+	})
+	v.checkNodeIndex(ViolationNodeIndex, "kind", kinds, func(n *Node) string {
+		return n.Symbol.Kind.String()
+	})
+
+	types := make(map[string][]*Edge, NumEdgeTypes)
+	for t, list := range g.edgesByType {
+		if len(list) > 0 {
+			types[EdgeType(t).String()] = list
+		}
+	}
+	v.checkEdgeIndex("type", types, edges, func(e *Edge) string {
+		if e.Type < 0 || e.Type >= NumEdgeTypes {
+			return ""
+		}
+		return e.Type.String()
+	})
+	v.checkEdgeIndex("file", g.edgesByFile, edges, func(e *Edge) string {
+		return e.Location.FilePath
+	})
+
+	if g.IsFrozen() {
+		v.checkNodeIndex(ViolationFileIndex, "file interval", g.fileIntervals, func(n *Node) string {
+			return n.Symbol.FilePath
+		})
+		v.checkStableIndex()
+	}
+	return v.finish()
+}
+
+// validateTouched checks the region an incremental refresh or compaction
+// changed.
+//
+// Description:
+//
+//	Checks the nodes of files and the nodes nodeIDs as Validate checks
+//	every node, except that an edge's membership in its counterpart's
+//	adjacency list is found by scanning that list, and each node's index
+//	entries are checked rather than whole indexes. The edge and interval
+//	index entries of files are checked too. nodeIDs that are not nodes
+//	(e.g. removed placeholders) are ignored.
+//
+// Inputs:
+//
+//	files - The changed files.
+//	nodeIDs - Further changed nodes, typically the neighbours of changed
+//	          or removed nodes in the previous graph.
+//
+// Outputs:
+//
+//	*ValidationReport - The violations found. Never nil.
+//	func(Violation) bool - Reports whether a violation lies in the region.
+//
+// Thread Safety: Safe for concurrent use on frozen graphs.
+func (g *Graph) validateTouched(files, nodeIDs []string) (*ValidationReport, func(Violation) bool) {
+	v := newValidator(g, ValidationModeTouched)
+
+	region := make(map[string]struct{}, len(nodeIDs))
+	for _, id := range nodeIDs {
+		region[id] = struct{}{}
+	}
+	fileSet := make(map[string]struct{}, len(files))
+	for _, f := range files {
+		fileSet[f] = struct{}{}
+		for _, n := range g.GetNodesByFile(f) {
+			region[n.ID] = struct{}{}
+		}
+	}
+	ids := make([]string, 0, len(region))
+	for id := range region {
+		if _, ok := g.nodes[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		node := g.nodes[id]
+		if !v.checkNode(id, node) {
+			continue
+		}
+		for _, e := range node.Outgoing {
+			if e == nil {
+				continue
+			}
+			v.report.EdgesChecked++
+			if to, ok := g.nodes[e.ToID]; !ok {
+				v.add(danglingViolation(e, true))
+			} else if !containsEdge(to.Incoming, e) {
+				v.add(edgeViolation(ViolationAdjacency, e, "missing from the Incoming list of its target"))
+			}
+		}
+		for _, e := range node.Incoming {
+			if e == nil {
+				continue
+			}
+			v.report.EdgesChecked++
+			if from, ok := g.nodes[e.FromID]; !ok {
+				v.add(danglingViolation(e, false))
+			} else if !containsEdge(from.Outgoing, e) {
+				v.add(edgeViolation(ViolationAdjacency, e, "missing from the Outgoing list of its source"))
+			}
+		}
+		v.checkNodeEntries(node)
+	}
+
+	sortedFiles := append([]string(nil), files...)
+	sort.Strings(sortedFiles)
+	for _, f := range sortedFiles {
+		for _, e := range g.edgesByFile[f] {
+			switch {
+			case e == nil:
+				v.add(Violation{Kind: ViolationEdgeIndex, FilePath: f, Detail: "file index holds a nil edge"})
+			case e.Location.FilePath != f:
+				v.add(edgeViolation(ViolationEdgeIndex, e, "file index lists the edge under "+f))
+			default:
+				_, fromOK := g.nodes[e.FromID]
+				_, toOK := g.nodes[e.ToID]
+				if !fromOK || !toOK {
+					v.add(danglingViolation(e, fromOK))
+				}
+			}
+		}
+		if !g.IsFrozen() {
+			continue
+		}
+		for _, n := range g.fileIntervals[f] {
+			if n == nil || g.nodes[n.ID] != n {
+				v.add(Violation{Kind: ViolationFileIndex, NodeID: idOf(n), FilePath: f, Detail: "file interval index holds a node that is not in the graph"})
+			}
+		}
+	}
+
+	inRegion := func(vi Violation) bool {
+		for _, id := range []string{vi.NodeID, vi.FromID, vi.ToID} {
+			if _, ok := region[id]; ok && id != "" {
+				return true
+			}
+		}
+		_, ok := fileSet[vi.FilePath]
+		return ok && vi.FilePath != ""
+	}
+	return v.finish(), inRegion
+}
+
+// linkedNodeIDs returns the IDs of the nodes linked by an edge to a node
+// of files, so a refresh of files can validate the nodes whose adjacency
+// lists it changed.
+func linkedNodeIDs(g *Graph, files []string) []string {
+	var ids []string
+	for _, f := range files {
+		for _, n := range g.GetNodesByFile(f) {
+			for _, e := range n.Outgoing {
+				ids = append(ids, e.ToID)
+			}
+			for _, e := range n.Incoming {
+				ids = append(ids, e.FromID)
+			}
+		}
+	}
+	return ids
+}
+
+// carry adds the violations of prev, a report of the graph r's region
+// was changed in, that lie outside the region. A prev that listed only
+// some of its violations carries the unlisted ones as a count.
+func (r *ValidationReport) carry(prev *ValidationReport, inRegion func(Violation) bool) *ValidationReport {
+	if !prev.Degraded() {
+		return r
+	}
+	for _, vi := range prev.Violations {
+		if inRegion(vi) {
+			continue
+		}
+		r.CarriedViolations++
+		if len(r.Violations) < MaxReportedViolations {
+			vi.Carried = true
+			r.Violations = append(r.Violations, vi)
+		}
+	}
+	r.CarriedViolations += prev.ViolationCount + prev.CarriedViolations - len(prev.Violations)
+	return r
+}
+
+// Validation returns the last validation report recorded for the graph,
+// or nil if it was never validated.
+//
+// Description:
+//
+//	IncrementalRefresh and Compact record a touched-region report on the
+//	graph they produce; a full Validate is recorded by its caller.
+//
+// Thread Safety: Safe for concurrent use.
+func (g *Graph) Validation() *ValidationReport {
+	return g.validation.Load()
+}
+
+// SetValidation records report as the graph's validation report.
+//
+// Thread Safety: Safe for concurrent use, also on frozen graphs.
+func (g *Graph) SetValidation(report *ValidationReport) {
+	g.validation.Store(report)
+}
+
+// edgeTarget returns the node at endpoint id of edge.
+//
+// Description:
+//
+//	Queries and traversals follow edges through edgeTarget so that a
+//	dangling edge, whose endpoint is not a node, is skipped with a
+//	warning instead of dereferencing a nil node. Validate reports such
+//	edges.
+//
+// Outputs:
+//
+//	*Node - The node, nil if missing.
+//	bool - False if the edge is dangling.
+func (g *Graph) edgeTarget(edge *Edge, id string) (*Node, bool) {
+	node, ok := g.nodes[id]
+	if !ok || node == nil {
+		slog.Warn("Skipping dangling graph edge",
+			telemetry.SensitiveLog("from_id", edge.FromID),
+			telemetry.SensitiveLog("to_id", edge.ToID),
+			slog.String("edge_type", edge.Type.String()),
+		)
+		return nil, false
+	}
+	return node, true
+}
+
+// validator accumulates a ValidationReport.
+type validator struct {
+	g      *Graph
+	start  time.Time
+	report *ValidationReport
+}
+
+func newValidator(g *Graph, mode string) *validator {
+	return &validator{
+		g:      g,
+		start:  time.Now(),
+		report: &ValidationReport{Mode: mode},
+	}
+}
+
+// add records a violation.
+func (v *validator) add(vi Violation) {
+	r := v.report
+	r.ViolationCount++
+	if r.ViolationsByKind == nil {
+		r.ViolationsByKind = make(map[string]int)
+	}
+	r.ViolationsByKind[vi.Kind]++
+	if len(r.Violations) < MaxReportedViolations {
+		r.Violations = append(r.Violations, vi)
+	}
+}
+
+// finish stamps and returns the report.
+func (v *validator) finish() *ValidationReport {
+	v.report.CheckedAtMilli = time.Now().UnixMilli()
+	v.report.DurationMilli = time.Since(v.start).Milliseconds()
+	return v.report
+}
+
+// checkNode checks the node stored under id and the edges of its
+// adjacency lists that concern only the node itself. Returns false if
+// there is no node to check further.
+func (v *validator) checkNode(id string, node *Node) bool {
+	v.report.NodesChecked++
+	if node == nil {
+		v.add(Violation{Kind: ViolationNodeIndex, NodeID: id, Detail: "node map holds nil"})
+		return false
+	}
+	if node.ID != id {
+		v.add(Violation{Kind: ViolationNodeIndex, NodeID: id, Detail: "node map holds node " + node.ID})
+	}
+	if node.Symbol == nil && v.g.IsFrozen() {
+		v.add(Violation{Kind: ViolationNilSymbol, NodeID: id, Detail: "frozen graph node has no symbol"})
+	}
+	for _, e := range node.Outgoing {
+		switch {
+		case e == nil:
+			v.add(Violation{Kind: ViolationAdjacency, NodeID: id, Detail: "Outgoing list holds a nil edge"})
+		case e.FromID != node.ID:
+			v.add(edgeViolation(ViolationAdjacency, e, "in the Outgoing list of "+node.ID))
+		}
+	}
+	for _, e := range node.Incoming {
+		switch {
+		case e == nil:
+			v.add(Violation{Kind: ViolationAdjacency, NodeID: id, Detail: "Incoming list holds a nil edge"})
+		case e.ToID != node.ID:
+			v.add(edgeViolation(ViolationAdjacency, e, "in the Incoming list of "+node.ID))
+		}
+	}
+	return true
+}
+
+// checkNodeEntries checks that node is listed in the indexes that should
+// list it.
+func (v *validator) checkNodeEntries(node *Node) {
+	g, sym := v.g, node.Symbol
+	if sym == nil {
+		return
+	}
+	missing := func(kind, index string) {
+		v.add(Violation{Kind: kind, NodeID: node.ID, FilePath: sym.FilePath, Detail: "missing from the " + index + " index"})
+	}
+	if sym.Name != "" && !containsNode(g.nodesByName[sym.Name], node) {
+		missing(ViolationNodeIndex, "name")
+	}
+	if !containsNode(g.nodesByKind[sym.Kind], node) {
+		missing(ViolationNodeIndex, "kind")
+	}
+	if !g.IsFrozen() {
+		return
+	}
+	if sym.FilePath != "" && !containsNode(g.fileIntervals[sym.FilePath], node) {
+		missing(ViolationFileIndex, "file interval")
+	}
+	if sym.StableID != "" {
+		if n, ok := g.nodesByStableID[sym.StableID]; !ok || g.nodes[n.ID] != n {
+			missing(ViolationNodeIndex, "stable ID")
+		}
+	}
+}
+
+// checkNodeIndex checks that index lists each live node with a non-empty
+// key once, under that key, and nothing else. keyOf is called on nodes
+// with a symbol only.
+func (v *validator) checkNodeIndex(kind, name string, index map[string][]*Node, keyOf func(*Node) string) {
+	g := v.g
+	listed := make(map[*Node]struct{}, len(g.nodes))
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, n := range index[key] {
+			switch {
+			case n == nil:
+				v.add(Violation{Kind: kind, Detail: fmt.Sprintf("%s index holds nil under %q", name, key)})
+			case g.nodes[n.ID] != n:
+				v.add(Violation{Kind: kind, NodeID: n.ID, Detail: fmt.Sprintf("%s index holds a node that is not in the graph", name)})
+			case n.Symbol == nil || keyOf(n) != key:
+				v.add(Violation{Kind: kind, NodeID: n.ID, Detail: fmt.Sprintf("%s index lists the node under %q", name, key)})
+			default:
+				if _, dup := listed[n]; dup {
+					v.add(Violation{Kind: kind, NodeID: n.ID, Detail: fmt.Sprintf("%s index lists the node twice", name)})
+				}
+				listed[n] = struct{}{}
+			}
+		}
+	}
+
+	indexed := 0
+	for _, n := range g.nodes {
+		if n != nil && n.Symbol != nil && keyOf(n) != "" {
+			indexed++
+		}
+	}
+	if len(listed) == indexed {
+		return
+	}
+	var unlisted []string
+	for id, n := range g.nodes {
+		if n == nil || n.Symbol == nil || keyOf(n) == "" {
+			continue
+		}
+		if _, ok := listed[n]; !ok {
+			unlisted = append(unlisted, id)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, id := range unlisted {
+		v.add(Violation{Kind: kind, NodeID: id, FilePath: g.nodes[id].Symbol.FilePath, Detail: "missing from the " + name + " index"})
+	}
+}
+
+// checkStableIndex checks the stable ID index: every entry is a live node
+// with that stable ID, and every stable ID has an entry.
+func (v *validator) checkStableIndex() {
+	g, index := v.g, v.g.nodesByStableID
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		n := index[key]
+		switch {
+		case n == nil || g.nodes[n.ID] != n:
+			v.add(Violation{Kind: ViolationNodeIndex, NodeID: idOf(n), Detail: "stable ID index holds a node that is not in the graph"})
+		case n.Symbol == nil || n.Symbol.StableID != key:
+			v.add(Violation{Kind: ViolationNodeIndex, NodeID: n.ID, Detail: fmt.Sprintf("stable ID index lists the node under %q", key)})
+		}
+	}
+	var unlisted []string
+	for id, n := range g.nodes {
+		if n == nil || n.Symbol == nil || n.Symbol.StableID == "" {
+			continue
+		}
+		if _, ok := index[n.Symbol.StableID]; !ok {
+			unlisted = append(unlisted, id)
+		}
+	}
+	sort.Strings(unlisted)
+	for _, id := range unlisted {
+		v.add(Violation{Kind: ViolationNodeIndex, NodeID: id, Detail: "missing from the stable ID index"})
+	}
+}
+
+// checkEdgeIndex checks that index lists each edge of the edge list with
+// a non-empty key once, under that key, and nothing else.
+func (v *validator) checkEdgeIndex(name string, index map[string][]*Edge, edges map[*Edge]struct{}, keyOf func(*Edge) string) {
+	listed := make(map[*Edge]struct{}, len(edges))
+	keys := make([]string, 0, len(index))
+	for key := range index {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		for _, e := range index[key] {
+			if e == nil {
+				v.add(Violation{Kind: ViolationEdgeIndex, Detail: fmt.Sprintf("%s index holds nil under %q", name, key)})
+				continue
+			}
+			if _, ok := edges[e]; !ok {
+				v.add(edgeViolation(ViolationEdgeIndex, e, name+" index holds an edge that is not in the edge list"))
+				continue
+			}
+			if keyOf(e) != key {
+				v.add(edgeViolation(ViolationEdgeIndex, e, fmt.Sprintf("%s index lists the edge under %q", name, key)))
+				continue
+			}
+			if _, dup := listed[e]; dup {
+				v.add(edgeViolation(ViolationEdgeIndex, e, name+" index lists the edge twice"))
+			}
+			listed[e] = struct{}{}
+		}
+	}
+	for _, e := range v.g.edges {
+		if e == nil || keyOf(e) == "" {
+			continue
+		}
+		if _, ok := listed[e]; !ok {
+			v.add(edgeViolation(ViolationEdgeIndex, e, "missing from the "+name+" index"))
+		}
+	}
+}
+
+// edgeViolation describes a violation concerning e.
+func edgeViolation(kind string, e *Edge, detail string) Violation {
+	return Violation{
+		Kind:     kind,
+		FromID:   e.FromID,
+		ToID:     e.ToID,
+		EdgeType: e.Type.String(),
+		FilePath: e.Location.FilePath,
+		Detail:   detail,
+	}
+}
+
+// danglingViolation describes a dangling edge; fromOK tells which end
+// is missing.
+func danglingViolation(e *Edge, fromOK bool) Violation {
+	if fromOK {
+		return edgeViolation(ViolationDanglingEdge, e, "target "+e.ToID+" is not a node")
+	}
+	return edgeViolation(ViolationDanglingEdge, e, "source "+e.FromID+" is not a node")
+}
+
+// idOf returns n's ID, or "" for nil.
+func idOf(n *Node) string {
+	if n == nil {
+		return ""
+	}
+	return n.ID
+}
+
+// containsNode reports whether nodes holds n.
+func containsNode(nodes []*Node, n *Node) bool {
+	for _, m := range nodes {
+		if m == n {
+			return true
+		}
+	}
+	return false
+}
+
+// containsEdge reports whether edges holds e.
+func containsEdge(edges []*Edge, e *Edge) bool {
+	for _, f := range edges {
+		if f == e {
+			return true
+		}
+	}
+	return false
+}
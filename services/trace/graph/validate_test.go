@@ -0,0 +1,158 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package graph
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+func TestGraph_ValidateReport(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(g *Graph)
+		want    map[string]int
+	}{
+		{"valid", func(g *Graph) {}, nil},
+		{"dangling edge", func(g *Graph) {
+			addDanglingEdge(g, "main.go:10:main", "gone.go:1:gone")
+		}, map[string]int{ViolationDanglingEdge: 1}},
+		{"edge missing from target's incoming list", func(g *Graph) {
+			g.nodes["main.go:20:setup"].Incoming = nil
+		}, map[string]int{ViolationAdjacency: 1}},
+		{"nil symbol", func(g *Graph) {
+			g.nodes["orphan"] = &Node{ID: "orphan"}
+		}, map[string]int{ViolationNilSymbol: 1}},
+		{"name index", func(g *Graph) {
+			delete(g.nodesByName, "helper")
+		}, map[string]int{ViolationNodeIndex: 1}},
+		{"file interval index", func(g *Graph) {
+			g.fileIntervals["main.go"] = g.fileIntervals["main.go"][:1]
+		}, map[string]int{ViolationFileIndex: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := createTestGraph(t)
+			tt.corrupt(g)
+			report := g.Validate()
+			if !reflect.DeepEqual(report.ViolationsByKind, tt.want) {
+				t.Errorf("violations = %v (%+v), want %v", report.ViolationsByKind, report.Violations, tt.want)
+			}
+			if report.Mode != ValidationModeFull || report.NodesChecked != g.NodeCount() {
+				t.Errorf("report = %+v", report)
+			}
+			if report.Degraded() != (tt.want != nil) || (report.Err() == nil) == report.Degraded() {
+				t.Errorf("Degraded() = %v, Err() = %v", report.Degraded(), report.Err())
+			}
+		})
+	}
+}
+
+func TestGraph_DanglingEdgeIsSkipped(t *testing.T) {
+	ctx := context.Background()
+	g := createTestGraph(t)
+	addDanglingEdge(g, "main.go:10:main", "gone.go:1:gone")
+	addDanglingEdge(g, "gone.go:1:gone", "main.go:20:setup")
+
+	callGraph, err := g.GetCallGraph(ctx, "main.go:10:main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range callGraph.VisitedNodes {
+		if id == "gone.go:1:gone" {
+			t.Error("call graph visited the missing node")
+		}
+	}
+	if _, err := g.GetReverseCallGraph(ctx, "main.go:20:setup"); err != nil {
+		t.Fatal(err)
+	}
+	callees, _ := g.FindCalleesByID(ctx, "main.go:10:main")
+	callers, _ := g.FindCallersByID(ctx, "main.go:20:setup")
+	if callees.Total != 2 || callers.Total != 1 {
+		t.Errorf("callees = %d, callers = %d; want the dangling edges skipped", callees.Total, callers.Total)
+	}
+}
+
+func TestGraph_ValidationAfterRefreshAndCompaction(t *testing.T) {
+	ctx := context.Background()
+	builder := NewBuilder(WithProjectRoot("/test"))
+	built, err := builder.Build(ctx, []*ast.ParseResult{
+		testParseResult("file_a.go", []*ast.Symbol{testSymbol("FuncA", ast.SymbolKindFunction, "file_a.go", 1)}, nil),
+		testParseResult("file_b.go", []*ast.Symbol{testSymbol("FuncB", ast.SymbolKindFunction, "file_b.go", 1)}, nil),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	built.Graph.SetValidation(&ValidationReport{
+		Mode:           ValidationModeFull,
+		ViolationCount: 2,
+		Violations: []Violation{
+			{Kind: ViolationDanglingEdge, FromID: "x", ToID: "y", FilePath: "file_b.go"},
+			{Kind: ViolationNodeIndex, NodeID: "elsewhere", FilePath: "file_c.go"},
+		},
+	})
+
+	refreshed, err := IncrementalRefresh(ctx, built.Graph, []string{"file_b.go"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := refreshed.Graph.Validation()
+	if report == nil || report.Mode != ValidationModeTouched || report.ViolationCount != 0 {
+		t.Fatalf("refresh report = %+v, want a clean touched check", report)
+	}
+	if report.CarriedViolations != 1 || report.Violations[0].NodeID != "elsewhere" || !report.Violations[0].Carried {
+		t.Errorf("carried = %d, %+v; want only the violation outside file_b.go", report.CarriedViolations, report.Violations)
+	}
+
+	compacted, err := Compact(ctx, refreshed.Graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := compacted.Graph.Validation(); r == nil || r.CarriedViolations != 1 || r.ViolationCount != 0 {
+		t.Errorf("compaction report = %+v, want the violation still carried", r)
+	}
+
+	compacted.Graph.SetValidation(compacted.Graph.Validate())
+	if compacted.Graph.Validation().Degraded() {
+		t.Errorf("full check = %+v, want clean", compacted.Graph.Validation())
+	}
+}
+
+func TestGraph_ValidateTouched(t *testing.T) {
+	g := createTestGraph(t)
+	addDanglingEdge(g, "main.go:10:main", "gone.go:1:gone")
+	addDanglingEdge(g, "types.go:70:FileReader", "gone.go:2:gone")
+
+	report, inRegion := g.validateTouched([]string{"main.go"}, nil)
+	if report.ViolationCount != 1 || report.Violations[0].FromID != "main.go:10:main" {
+		t.Errorf("violations = %+v, want only the edge from main.go", report.Violations)
+	}
+	if !inRegion(Violation{FilePath: "main.go"}) || inRegion(Violation{NodeID: "types.go:70:FileReader"}) {
+		t.Error("region does not match main.go")
+	}
+}
+
+// addDanglingEdge adds an edge to g whose endpoint is not a node, as a
+// broken refresh would leave it.
+func addDanglingEdge(g *Graph, fromID, toID string) {
+	e := &Edge{FromID: fromID, ToID: toID, Type: EdgeTypeCalls}
+	g.edges = append(g.edges, e)
+	g.edgesByType[EdgeTypeCalls] = append(g.edgesByType[EdgeTypeCalls], e)
+	if from, ok := g.nodes[fromID]; ok {
+		from.Outgoing = append(from.Outgoing, e)
+	}
+	if to, ok := g.nodes[toID]; ok {
+		to.Incoming = append(to.Incoming, e)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// CompactGraph resolves placeholders and removes duplicate edges in a
+// cached graph.
+//
+// Description:
+//
+//	Runs graph.Compact on a copy of the cached graph and installs the
+//	result the way a rebuild does: in one step, so readers see either
+//	the old or the compacted graph. If the graph is replaced while it is
+//	being compacted (a rebuild or refresh finished first), the compacted
+//	copy is discarded and Installed is false. The symbol index is shared
+//	with the previous graph, since compaction only removes placeholder
+//	nodes. The IncrementalUpdates counter is reset.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	graphID - The graph to compact.
+//
+// Outputs:
+//
+//	*GraphCompactResponse - What changed.
+//	error - ErrGraphNotInitialized or ErrGraphExpired for an unknown graph,
+//	        ErrCompactionInProgress if the graph is already being compacted.
+//
+// Thread Safety: Safe for concurrent use. At most one compaction runs per graph.
+func (s *Service) CompactGraph(ctx context.Context, graphID string) (*GraphCompactResponse, error) {
+	if !s.startCompaction(graphID) {
+		return nil, ErrCompactionInProgress
+	}
+	defer s.finishCompaction(graphID)
+
+	prev, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := graph.Compact(ctx, prev.Graph)
+	if err != nil {
+		return nil, fmt.Errorf("compacting graph: %w", err)
+	}
+
+	next := *prev
+	next.IncrementalUpdates = 0
+	if result.Changed() {
+		g := result.Graph
+		next.Graph = g
+		next.ContentHash = g.ContentHash()
+		next.BuiltAtMilli = time.Now().UnixMilli()
+		next.Assembler = cbcontext.NewAssembler(g, prev.Index)
+		if s.libDocProvider != nil {
+			next.Assembler = next.Assembler.WithLibraryDocProvider(s.libDocProvider)
+		}
+		next.Adapter = nil
+		if hg, err := graph.WrapGraph(g); err == nil {
+			next.Adapter, _ = graph.NewCRSGraphAdapter(hg, prev.Index, 1, next.BuiltAtMilli, nil)
+		}
+	}
+
+	installed, err := s.installGraph(ctx, graphID, prev, &next)
+	if err != nil {
+		return nil, err
+	}
+	if !installed {
+		slog.Info("Graph replaced during compaction, discarding result",
+			slog.String("graph_id", graphID),
+		)
+	}
+
+	return &GraphCompactResponse{
+		GraphID:               graphID,
+		Installed:             installed,
+		PlaceholdersResolved:  result.PlaceholdersResolved,
+		PlaceholdersRemaining: result.PlaceholdersRemaining,
+		EdgesRewritten:        result.EdgesRewritten,
+		DuplicateEdgesRemoved: result.DuplicateEdgesRemoved,
+		NodeCount:             next.Graph.NodeCount(),
+		EdgeCount:             next.Graph.EdgeCount(),
+		ContentHash:           next.ContentHash,
+		DurationMs:            time.Since(start).Milliseconds(),
+	}, nil
+}
+
+// maybeCompactGraph starts a background compaction of graphID once cached
+// has reached ServiceConfig.CompactAfterUpdates incremental refreshes.
+// No-op if automatic compaction is disabled or one is already running.
+func (s *Service) maybeCompactGraph(graphID string, cached *CachedGraph) {
+	limit := s.config.CompactAfterUpdates
+	if limit <= 0 || cached.IncrementalUpdates < limit {
+		return
+	}
+	go func() {
+		ctx := context.Background()
+		if s.config.MaxInitDuration > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, s.config.MaxInitDuration)
+			defer cancel()
+		}
+		resp, err := s.CompactGraph(ctx, graphID)
+		if err != nil {
+			slog.Warn("Background graph compaction failed",
+				slog.String("graph_id", graphID),
+				telemetry.SensitiveLogError("error", err),
+			)
+			return
+		}
+		slog.Info("Background graph compaction finished",
+			slog.String("graph_id", graphID),
+			slog.Int("incremental_updates", cached.IncrementalUpdates),
+			slog.Bool("installed", resp.Installed),
+			slog.Int("placeholders_resolved", resp.PlaceholdersResolved),
+			slog.Int("duplicate_edges_removed", resp.DuplicateEdgesRemoved),
+		)
+	}()
+}
+
+// startCompaction claims graphID for compaction. Returns false if it is
+// already being compacted.
+func (s *Service) startCompaction(graphID string) bool {
+	s.compactingMu.Lock()
+	defer s.compactingMu.Unlock()
+	if _, busy := s.compacting[graphID]; busy {
+		return false
+	}
+	s.compacting[graphID] = struct{}{}
+	return true
+}
+
+// finishCompaction releases graphID after startCompaction.
+func (s *Service) finishCompaction(graphID string) {
+	s.compactingMu.Lock()
+	defer s.compactingMu.Unlock()
+	delete(s.compacting, graphID)
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// cacheGraphWithPlaceholder caches a graph whose call to helper still
+// points at a placeholder although helper is now in the graph.
+func cacheGraphWithPlaceholder(t *testing.T, svc *Service, updates int) (string, *CachedGraph) {
+	t.Helper()
+	root := t.TempDir()
+	g := graph.NewGraph(root)
+	for _, sym := range []*ast.Symbol{
+		{ID: "main.go:3:main", Name: "main", Kind: ast.SymbolKindFunction, Package: "main", FilePath: "main.go", StartLine: 3},
+		{ID: "util.go:1:helper", Name: "helper", Kind: ast.SymbolKindFunction, Package: "main", FilePath: "util.go", StartLine: 1},
+		{ID: "external:main:helper", Name: "helper", Kind: ast.SymbolKindExternal, Package: "main"},
+	} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	if err := g.AddEdge("main.go:3:main", "external:main:helper", graph.EdgeTypeCalls, ast.Location{FilePath: "main.go", StartLine: 4}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+	g.Freeze()
+
+	graphID := svc.generateGraphID(root)
+	cached := &CachedGraph{
+		Graph:              g,
+		BuiltAtMilli:       g.BuiltAtMilli,
+		ProjectRoot:        root,
+		ContentHash:        g.ContentHash(),
+		IncrementalUpdates: updates,
+	}
+	svc.graphs[graphID] = cached
+	return graphID, cached
+}
+
+func TestHandlers_HandleCompactGraph(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	graphID, prev := cacheGraphWithPlaceholder(t, svc, 3)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/v1/trace/graph/compact", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := post(`{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("missing graph_id: expected %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := post(`{"graph_id":"nonexistent"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown graph: expected %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w := post(`{"graph_id":"` + graphID + `"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp GraphCompactResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !resp.Installed || resp.PlaceholdersResolved != 1 || resp.EdgesRewritten != 1 || resp.NodeCount != 2 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	current, err := svc.GetGraph(graphID)
+	if err != nil {
+		t.Fatalf("GetGraph: %v", err)
+	}
+	if current == prev || current.IncrementalUpdates != 0 || current.ContentHash != resp.ContentHash {
+		t.Errorf("compacted graph not installed: %+v", current)
+	}
+	if _, ok := current.Graph.GetNode("external:main:helper"); ok {
+		t.Error("installed graph still has the placeholder")
+	}
+	if _, ok := prev.Graph.GetNode("external:main:helper"); !ok {
+		t.Error("previous graph was modified")
+	}
+
+	if !svc.startCompaction(graphID) {
+		t.Fatal("startCompaction should succeed when idle")
+	}
+	if w := post(`{"graph_id":"` + graphID + `"}`); w.Code != http.StatusConflict {
+		t.Errorf("concurrent compaction: expected %d, got %d", http.StatusConflict, w.Code)
+	}
+	svc.finishCompaction(graphID)
+}
+
+func TestService_InstallGraph_Conditional(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	graphID, prev := cacheGraphWithPlaceholder(t, svc, 0)
+
+	rebuilt := *prev
+	if err := svc.swapGraph(context.Background(), graphID, &rebuilt); err != nil {
+		t.Fatalf("swapGraph: %v", err)
+	}
+	compacted := *prev
+	installed, err := svc.installGraph(context.Background(), graphID, prev, &compacted)
+	if err != nil || installed {
+		t.Fatalf("installGraph over a replaced graph = %v, %v; want false, nil", installed, err)
+	}
+	if current, _ := svc.GetGraph(graphID); current != &rebuilt {
+		t.Error("newer graph was overwritten")
+	}
+}
+
+func TestService_MaybeCompactGraph(t *testing.T) {
+	cfg := DefaultServiceConfig()
+	cfg.CompactAfterUpdates = 2
+	svc := NewService(cfg)
+
+	graphID, below := cacheGraphWithPlaceholder(t, svc, 1)
+	svc.maybeCompactGraph(graphID, below)
+	if !svc.startCompaction(graphID) {
+		t.Fatal("compaction started below the threshold")
+	}
+	svc.finishCompaction(graphID)
+
+	below.IncrementalUpdates = 2
+	svc.maybeCompactGraph(graphID, below)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		current, err := svc.GetGraph(graphID)
+		if err != nil {
+			t.Fatalf("GetGraph: %v", err)
+		}
+		if current != below {
+			if _, ok := current.Graph.GetNode("external:main:helper"); ok {
+				t.Error("background compaction did not resolve the placeholder")
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background compaction did not install a graph")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if _, err := svc.CompactGraph(context.Background(), "nonexistent"); !errors.Is(err, ErrGraphNotInitialized) {
+		t.Errorf("CompactGraph(unknown) error = %v, want ErrGraphNotInitialized", err)
+	}
+}
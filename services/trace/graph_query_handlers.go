@@ -12,12 +12,13 @@ package trace
 
 import (
 	"errors"
-	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/gin-gonic/gin"
 )
 
@@ -48,57 +49,75 @@ func isSymbolNotFoundError(err error) bool {
 //
 //	graph_id: ID of the graph to query (required)
 //	function: Name of the function to find callees for (required)
-//	limit: Maximum number of results (optional, default 50)
+//	limit: Page size (optional, default 50, max 1000)
+//	cursor: next_cursor of the previous page (optional)
+//	root: Workspace root to restrict results to (optional)
+//	scope: Named view to restrict results to (optional)
+//	debug: Add each callee's CALLS edges with their provenance (optional)
 //
 // Response:
 //
 //	200 OK: CalleesResponse (may be empty array)
-//	400 Bad Request: Missing parameters or graph not initialized
+//	400 Bad Request: Missing parameters, unknown root or scope, graph not initialized, or malformed cursor
+//	409 Conflict: Cursor from a graph generation that was replaced
 func (h *Handlers) HandleFindCallees(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindCallees")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindCallees")
 
 	var req CalleesRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		logger.Warn("Invalid query parameters", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid query parameters: graph_id and function are required",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid query parameters", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid query parameters: graph_id and function are required")
 		return
 	}
 
-	if req.Limit <= 0 {
-		req.Limit = 50
+	if req.Root != "" {
+		if err := h.svc.CheckRootFilter(req.GraphID, req.Root); err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+			return
+		}
+	}
+	view, ok := h.scopeFilter(c, req.GraphID, req.Scope)
+	if !ok {
+		return
+	}
+	page, ok := h.pageQuery(c, req.GraphID, req.Limit, req.Cursor, "callees", req.Function, req.Root, req.Scope)
+	if !ok {
+		return
 	}
 
-	logger.Info("Finding callees", "graph_id", req.GraphID, "function", req.Function)
+	logger.Info("Finding callees", "graph_id", req.GraphID, telemetry.SensitiveLog("function", req.Function))
 
-	callees, err := h.svc.FindCallees(c.Request.Context(), req.GraphID, req.Function, req.Limit)
+	callees, err := h.svc.FindCallees(c.Request.Context(), req.GraphID, req.Function, queryPageScanLimit)
 	if err != nil {
 		if isGraphStateError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   err.Error(),
-				Code:    "GRAPH_NOT_INITIALIZED",
-				Details: "Ensure /init was called first",
-			})
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
 			return
 		}
 
-		logger.Error("Find callees failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "QUERY_FAILED",
-		})
+		logger.Error("Find callees failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
-	logger.Info("Found callees", "count", len(callees))
+	scanFull := len(callees) >= queryPageScanLimit
+	callees, pageInfo := pageOf(filterSymbolResults(callees, view, req.Root, len(callees)), page, scanFull)
+	if req.Debug {
+		if err := h.svc.AnnotateCallEdges(req.GraphID, req.Function, callees, false); err != nil {
+			logger.Warn("Annotating callee edges failed", telemetry.SensitiveLogError("error", err))
+		}
+	}
+
+	logger.Info("Found callees", "count", len(callees), "total", pageInfo.Total)
 
-	c.JSON(http.StatusOK, CalleesResponse{
+	resp := CalleesResponse{
 		Function: req.Function,
 		Callees:  callees,
-	})
+		PageInfo: pageInfo,
+	}
+	h.linkLocations(c, req.GraphID, &resp)
+	c.JSON(http.StatusOK, resp)
 }
 
 // HandleGetCallChain handles GET /v1/trace/call-chain.
@@ -119,55 +138,45 @@ func (h *Handlers) HandleFindCallees(c *gin.Context) {
 //	400 Bad Request: Missing parameters, graph not initialized, or function not found
 func (h *Handlers) HandleGetCallChain(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleGetCallChain")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGetCallChain")
 
 	var req CallChainRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		logger.Warn("Invalid query parameters", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid query parameters: graph_id, from, and to are required",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid query parameters", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid query parameters: graph_id, from, and to are required")
 		return
 	}
 
-	logger.Info("Getting call chain", "graph_id", req.GraphID, "from", req.From, "to", req.To)
+	logger.Info("Getting call chain", "graph_id", req.GraphID, telemetry.SensitiveLog("from", req.From), telemetry.SensitiveLog("to", req.To))
 
 	path, length, err := h.svc.GetCallChain(c.Request.Context(), req.GraphID, req.From, req.To)
 	if err != nil {
 		if isGraphStateError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   err.Error(),
-				Code:    "GRAPH_NOT_INITIALIZED",
-				Details: "Ensure /init was called first",
-			})
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
 			return
 		}
 
 		if isSymbolNotFoundError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error: err.Error(),
-				Code:  "SYMBOL_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeSymbolNotFound, err.Error())
 			return
 		}
 
-		logger.Error("Get call chain failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "QUERY_FAILED",
-		})
+		logger.Error("Get call chain failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
 	logger.Info("Found call chain", "length", length)
 
-	c.JSON(http.StatusOK, CallChainResponse{
+	resp := CallChainResponse{
 		From:   req.From,
 		To:     req.To,
 		Path:   path,
 		Length: length,
-	})
+	}
+	h.linkLocations(c, req.GraphID, &resp)
+	c.JSON(http.StatusOK, resp)
 }
 
 // HandleFindReferences handles GET /v1/trace/references.
@@ -180,57 +189,75 @@ func (h *Handlers) HandleGetCallChain(c *gin.Context) {
 //
 //	graph_id: ID of the graph to query (required)
 //	symbol: Name of the symbol to find references for (required)
-//	limit: Maximum number of results (optional, default 50)
+//	limit: Page size (optional, default 50, max 1000)
+//	cursor: next_cursor of the previous page (optional)
+//	root: Workspace root to restrict results to (optional)
+//	scope: Named view to restrict results to (optional)
+//	debug: Add the provenance of the edge behind each reference (optional)
 //
 // Response:
 //
 //	200 OK: ReferencesResponse (may be empty array)
-//	400 Bad Request: Missing parameters or graph not initialized
+//	400 Bad Request: Missing parameters, unknown root or scope, graph not initialized, or malformed cursor
+//	409 Conflict: Cursor from a graph generation that was replaced
 func (h *Handlers) HandleFindReferences(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindReferences")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindReferences")
 
 	var req ReferencesRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		logger.Warn("Invalid query parameters", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid query parameters: graph_id and symbol are required",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid query parameters", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid query parameters: graph_id and symbol are required")
 		return
 	}
 
-	if req.Limit <= 0 {
-		req.Limit = 50
+	if req.Root != "" {
+		if err := h.svc.CheckRootFilter(req.GraphID, req.Root); err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+			return
+		}
+	}
+	view, ok := h.scopeFilter(c, req.GraphID, req.Scope)
+	if !ok {
+		return
+	}
+	page, ok := h.pageQuery(c, req.GraphID, req.Limit, req.Cursor, "references", req.Symbol, req.Root, req.Scope)
+	if !ok {
+		return
 	}
 
-	logger.Info("Finding references", "graph_id", req.GraphID, "symbol", req.Symbol)
+	logger.Info("Finding references", "graph_id", req.GraphID, telemetry.SensitiveLog("symbol", req.Symbol))
 
-	refs, err := h.svc.FindReferences(c.Request.Context(), req.GraphID, req.Symbol, req.Limit)
+	refs, err := h.svc.FindReferences(c.Request.Context(), req.GraphID, req.Symbol, queryPageScanLimit)
 	if err != nil {
 		if isGraphStateError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   err.Error(),
-				Code:    "GRAPH_NOT_INITIALIZED",
-				Details: "Ensure /init was called first",
-			})
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
 			return
 		}
 
-		logger.Error("Find references failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "QUERY_FAILED",
-		})
+		logger.Error("Find references failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
-	logger.Info("Found references", "count", len(refs))
+	scanFull := len(refs) >= queryPageScanLimit
+	refs, pageInfo := pageOf(filterReferenceResults(refs, view, req.Root, len(refs)), page, scanFull)
+	if req.Debug {
+		if err := h.svc.AnnotateReferenceEdges(req.GraphID, req.Symbol, refs); err != nil {
+			logger.Warn("Annotating reference edges failed", telemetry.SensitiveLogError("error", err))
+		}
+	}
+
+	logger.Info("Found references", "count", len(refs), "total", pageInfo.Total)
 
-	c.JSON(http.StatusOK, ReferencesResponse{
+	resp := ReferencesResponse{
 		Symbol:     req.Symbol,
 		References: refs,
-	})
+		PageInfo:   pageInfo,
+	}
+	h.linkLocations(c, req.GraphID, &resp)
+	c.JSON(http.StatusOK, resp)
 }
 
 // HandleFindHotspots handles POST /v1/trace/analytics/hotspots.
@@ -251,15 +278,12 @@ func (h *Handlers) HandleFindReferences(c *gin.Context) {
 func (h *Handlers) HandleFindHotspots(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindHotspots")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindHotspots")
 
 	var req FindHotspotsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
@@ -268,19 +292,13 @@ func (h *Handlers) HandleFindHotspots(c *gin.Context) {
 	result, err := h.svc.FindHotspots(c.Request.Context(), req.GraphID, req.Limit)
 	if err != nil {
 		if isGraphStateError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   err.Error(),
-				Code:    "GRAPH_NOT_INITIALIZED",
-				Details: "Ensure /init was called first",
-			})
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
 			return
 		}
 
-		logger.Error("Find hotspots failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Find hotspots failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -307,15 +325,12 @@ func (h *Handlers) HandleFindHotspots(c *gin.Context) {
 func (h *Handlers) HandleFindCycles(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindCycles")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindCycles")
 
 	var req FindCyclesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
@@ -324,19 +339,13 @@ func (h *Handlers) HandleFindCycles(c *gin.Context) {
 	result, err := h.svc.FindCycles(c.Request.Context(), req.GraphID)
 	if err != nil {
 		if isGraphStateError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   err.Error(),
-				Code:    "GRAPH_NOT_INITIALIZED",
-				Details: "Ensure /init was called first",
-			})
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
 			return
 		}
 
-		logger.Error("Find cycles failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Find cycles failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -364,15 +373,12 @@ func (h *Handlers) HandleFindCycles(c *gin.Context) {
 func (h *Handlers) HandleFindImportant(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindImportant")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindImportant")
 
 	var req FindImportantRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
@@ -381,19 +387,13 @@ func (h *Handlers) HandleFindImportant(c *gin.Context) {
 	result, err := h.svc.FindImportant(c.Request.Context(), req.GraphID, req.Limit)
 	if err != nil {
 		if isGraphStateError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   err.Error(),
-				Code:    "GRAPH_NOT_INITIALIZED",
-				Details: "Ensure /init was called first",
-			})
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
 			return
 		}
 
-		logger.Error("Find important failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Find important failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -420,15 +420,12 @@ func (h *Handlers) HandleFindImportant(c *gin.Context) {
 func (h *Handlers) HandleFindCommunities(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindCommunities")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindCommunities")
 
 	var req FindCommunitiesRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
@@ -437,19 +434,13 @@ func (h *Handlers) HandleFindCommunities(c *gin.Context) {
 	result, err := h.svc.FindCommunities(c.Request.Context(), req.GraphID)
 	if err != nil {
 		if isGraphStateError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   err.Error(),
-				Code:    "GRAPH_NOT_INITIALIZED",
-				Details: "Ensure /init was called first",
-			})
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
 			return
 		}
 
-		logger.Error("Find communities failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Find communities failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -478,44 +469,32 @@ func (h *Handlers) HandleFindCommunities(c *gin.Context) {
 func (h *Handlers) HandleFindPath(c *gin.Context) {
 	start := time.Now()
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleFindPath")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleFindPath")
 
 	var req FindPathRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
-	logger.Info("Finding path", "graph_id", req.GraphID, "from", req.From, "to", req.To)
+	logger.Info("Finding path", "graph_id", req.GraphID, telemetry.SensitiveLog("from", req.From), telemetry.SensitiveLog("to", req.To))
 
 	result, err := h.svc.FindPath(c.Request.Context(), req.GraphID, req.From, req.To)
 	if err != nil {
 		if isGraphStateError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error:   err.Error(),
-				Code:    "GRAPH_NOT_INITIALIZED",
-				Details: "Ensure /init was called first",
-			})
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
 			return
 		}
 
 		if isSymbolNotFoundError(err) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error: err.Error(),
-				Code:  "SYMBOL_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeSymbolNotFound, err.Error())
 			return
 		}
 
-		logger.Error("Find path failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "INTERNAL_ERROR",
-		})
+		logger.Error("Find path failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -524,3 +503,161 @@ func (h *Handlers) HandleFindPath(c *gin.Context) {
 		LatencyMs: time.Since(start).Milliseconds(),
 	})
 }
+
+// HandleGraphQuery handles POST /v1/trace/graph/query.
+//
+// Description:
+//
+//	Runs a structured query (match, traverse, select, fields) over the
+//	frozen graph. Work is bounded by the query limits, clamped to hard
+//	caps; a query that reaches one returns partial rows with truncated
+//	set. With explain, returns an estimate of the traversal size instead.
+//
+// Request Body:
+//
+//	graph_id: ID of the graph to query (required)
+//	query: graph.StructuredQuery (required)
+//	explain: Return a graph.QueryPlan without traversing (optional)
+//
+// Response:
+//
+//	200 OK: AgenticResponse wrapping rows or the query plan
+//	400 Bad Request: Invalid body or query, or graph not initialized
+func (h *Handlers) HandleGraphQuery(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGraphQuery")
+
+	var req GraphQueryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	logger.Info("Running graph query", "graph_id", req.GraphID, "steps", len(req.Query.Traverse), "explain", req.Explain)
+
+	result, err := h.svc.QueryGraph(c.Request.Context(), req.GraphID, &req.Query, req.Explain)
+	if err != nil {
+		if isGraphStateError(err) {
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
+			return
+		}
+
+		if errors.Is(err, graph.ErrInvalidQuery) {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+			return
+		}
+
+		logger.Error("Graph query failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, AgenticResponse{
+		Result:    result,
+		LatencyMs: time.Since(start).Milliseconds(),
+	})
+}
+
+// HandleCompactGraph handles POST /v1/trace/graph/compact.
+//
+// Description:
+//
+//	Resolves placeholder nodes that now match real symbols, rewrites
+//	their edges and removes duplicate edges, then atomically installs the
+//	compacted graph. See Service.CompactGraph.
+//
+// Request Body:
+//
+//	GraphCompactRequest
+//
+// Response:
+//
+//	200 OK: GraphCompactResponse
+//	400 Bad Request: Invalid request or graph not initialized
+//	409 Conflict: The graph is already being compacted
+func (h *Handlers) HandleCompactGraph(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleCompactGraph")
+
+	var req GraphCompactRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	resp, err := h.svc.CompactGraph(c.Request.Context(), req.GraphID)
+	if err != nil {
+		if isGraphStateError(err) {
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
+			return
+		}
+		if errors.Is(err, ErrCompactionInProgress) {
+			apierror.Write(c, http.StatusConflict, apierror.CodeCompactionInProgress, err.Error())
+			return
+		}
+		logger.Error("Graph compaction failed", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		return
+	}
+
+	logger.Info("Graph compacted",
+		"graph_id", req.GraphID,
+		"installed", resp.Installed,
+		"placeholders_resolved", resp.PlaceholdersResolved,
+		"edges_rewritten", resp.EdgesRewritten,
+		"duplicate_edges_removed", resp.DuplicateEdgesRemoved)
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleValidateGraph handles POST /v1/trace/graph/validate.
+//
+// Description:
+//
+//	Runs a full invariant check of a cached graph: dangling edges,
+//	adjacency lists, nil symbols and index consistency. The report is
+//	recorded on the graph, so a clean check clears its degraded flag.
+//	See Service.ValidateGraph.
+//
+// Request Body:
+//
+//	GraphValidateRequest
+//
+// Response:
+//
+//	200 OK: GraphValidateResponse (also when violations are found)
+//	400 Bad Request: Invalid request or graph not initialized
+func (h *Handlers) HandleValidateGraph(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleValidateGraph")
+
+	var req GraphValidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	resp, err := h.svc.ValidateGraph(c.Request.Context(), req.GraphID)
+	if err != nil {
+		if isGraphStateError(err) {
+			apierror.New(http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error()).
+				WithDetails("Ensure /init was called first").Write(c)
+			return
+		}
+		logger.Error("Graph validation failed", "graph_id", req.GraphID, telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		return
+	}
+
+	logger.Info("Graph validated",
+		"graph_id", req.GraphID,
+		"degraded", resp.Degraded,
+		"violations", resp.Report.ViolationCount,
+		"duration_ms", resp.Report.DurationMilli)
+	c.JSON(http.StatusOK, resp)
+}
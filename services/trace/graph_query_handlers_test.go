@@ -753,3 +753,124 @@ func TestHandlers_HandleFindPath_SymbolNotFound(t *testing.T) {
 		t.Errorf("expected code 'SYMBOL_NOT_FOUND', got %q", errResp.Code)
 	}
 }
+
+// =============================================================================
+// HandleGraphQuery Tests
+// =============================================================================
+
+// postGraphQuery sends a GraphQueryRequest and decodes the result into out.
+func postGraphQuery(t *testing.T, svc *Service, body GraphQueryRequest, out any) *httptest.ResponseRecorder {
+	t.Helper()
+	router := setupTestRouter(svc)
+
+	data, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", "/v1/trace/graph/query", bytes.NewBuffer(data))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if out != nil && w.Code == http.StatusOK {
+		resp := AgenticResponse{Result: out}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+	}
+	return w
+}
+
+func TestHandlers_HandleGraphQuery_Success(t *testing.T) {
+	svc, graphID := setupGraphQueryTestService(t)
+
+	var result graph.StructuredQueryResult
+	w := postGraphQuery(t, svc, GraphQueryRequest{
+		GraphID: graphID,
+		Query: graph.StructuredQuery{
+			Match:    graph.NodeFilter{NameRegex: "^funcA$"},
+			Traverse: []graph.TraverseStep{{EdgeTypes: []string{"calls"}, MaxDepth: 2}},
+			Fields:   []string{"name"},
+		},
+	}, &result)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if len(result.Rows) != 2 || result.Rows[0]["name"] != "funcB" || result.Rows[1]["name"] != "funcC" {
+		t.Errorf("rows = %v, want funcB and funcC", result.Rows)
+	}
+	if result.Truncated {
+		t.Errorf("unexpected truncation by %s", result.LimitReached)
+	}
+}
+
+func TestHandlers_HandleGraphQuery_LimitReached(t *testing.T) {
+	svc, graphID := setupGraphQueryTestService(t)
+
+	var result graph.StructuredQueryResult
+	w := postGraphQuery(t, svc, GraphQueryRequest{
+		GraphID: graphID,
+		Query: graph.StructuredQuery{
+			Match:    graph.NodeFilter{NameRegex: "^funcA$"},
+			Traverse: []graph.TraverseStep{{EdgeTypes: []string{"calls"}, MaxDepth: 2}},
+			Limits:   graph.QueryLimits{MaxVisited: 2},
+		},
+	}, &result)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if !result.Truncated || result.LimitReached != graph.QueryLimitVisited || len(result.Rows) != 1 {
+		t.Errorf("result = %+v, want one row truncated by max_visited", result)
+	}
+}
+
+func TestHandlers_HandleGraphQuery_Explain(t *testing.T) {
+	svc, graphID := setupGraphQueryTestService(t)
+
+	var plan graph.QueryPlan
+	w := postGraphQuery(t, svc, GraphQueryRequest{
+		GraphID: graphID,
+		Query: graph.StructuredQuery{
+			Match:    graph.NodeFilter{Kinds: []string{"function"}},
+			Traverse: []graph.TraverseStep{{EdgeTypes: []string{"calls"}}},
+		},
+		Explain: true,
+	}, &plan)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if plan.StartNodes != 3 || len(plan.Steps) != 1 || !plan.WithinLimits {
+		t.Errorf("plan = %+v, want 3 start nodes, 1 step, within limits", plan)
+	}
+}
+
+func TestHandlers_HandleGraphQuery_Errors(t *testing.T) {
+	svc, graphID := setupGraphQueryTestService(t)
+
+	tests := []struct {
+		name string
+		body GraphQueryRequest
+		code string
+	}{
+		{"missing graph", GraphQueryRequest{}, "INVALID_REQUEST"},
+		{"graph not initialized", GraphQueryRequest{GraphID: "nonexistent"}, "GRAPH_NOT_INITIALIZED"},
+		{"invalid query", GraphQueryRequest{GraphID: graphID, Query: graph.StructuredQuery{
+			Traverse: []graph.TraverseStep{{EdgeTypes: []string{"likes"}}},
+		}}, "INVALID_PARAMETER"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postGraphQuery(t, svc, tt.body, nil)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+			}
+			var errResp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if errResp.Code != tt.code {
+				t.Errorf("expected code %q, got %q", tt.code, errResp.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// graphValidationsTotal counts graph validations by mode (full or
+	// touched) and result (ok or degraded).
+	graphValidationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "trace_graph_validations_total",
+		Help: "Graph invariant checks by mode and result",
+	}, []string{"mode", "result"})
+
+	// graphViolationsTotal counts the violations graph validations found.
+	graphViolationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "trace_graph_validation_violations_total",
+		Help: "Graph invariant violations found by kind",
+	}, []string{"kind"})
+
+	// graphsDegraded is the number of cached graphs whose last validation
+	// found violations.
+	graphsDegraded = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "trace_graphs_degraded",
+		Help: "Cached graphs whose last validation found violations",
+	})
+)
+
+// ValidateGraph runs a full invariant check of a cached graph.
+//
+// Description:
+//
+//	Runs graph.Graph.Validate and records the report on the graph,
+//	replacing the touched-region reports of refreshes and compactions:
+//	a clean full check clears the degraded flag, violations set it.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	graphID - The graph to validate.
+//
+// Outputs:
+//
+//	*GraphValidateResponse - The report.
+//	error - ErrGraphNotInitialized or ErrGraphExpired for an unknown graph.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ValidateGraph(ctx context.Context, graphID string) (*GraphValidateResponse, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	report := cached.Graph.Validate()
+	cached.Graph.SetValidation(report)
+	s.recordValidation(graphID, report)
+	s.countDegradedGraphs()
+
+	return &GraphValidateResponse{
+		GraphID:  graphID,
+		Degraded: report.Degraded(),
+		Report:   report,
+	}, nil
+}
+
+// recordValidation logs a validation report and counts it in metrics.
+func (s *Service) recordValidation(graphID string, report *graph.ValidationReport) {
+	result := "ok"
+	if report.Degraded() {
+		result = "degraded"
+	}
+	graphValidationsTotal.WithLabelValues(report.Mode, result).Inc()
+	for kind, n := range report.ViolationsByKind {
+		graphViolationsTotal.WithLabelValues(kind).Add(float64(n))
+	}
+
+	if !report.Degraded() {
+		slog.Debug("Graph validation passed",
+			slog.String("graph_id", graphID),
+			slog.String("mode", report.Mode),
+			slog.Int("nodes_checked", report.NodesChecked),
+			slog.Int64("duration_ms", report.DurationMilli),
+		)
+		return
+	}
+	slog.Warn("Graph validation found violations, graph is degraded",
+		slog.String("graph_id", graphID),
+		slog.String("mode", report.Mode),
+		slog.Int("violations", report.ViolationCount),
+		slog.Int("carried_violations", report.CarriedViolations),
+		slog.Any("by_kind", report.ViolationsByKind),
+		slog.String("first", report.Err().Error()),
+	)
+}
+
+// countDegradedGraphs updates the degraded graphs gauge.
+func (s *Service) countDegradedGraphs() {
+	s.mu.RLock()
+	degraded := 0
+	for _, cached := range s.graphs {
+		if cached.Graph != nil && cached.Graph.Validation().Degraded() {
+			degraded++
+		}
+	}
+	s.mu.RUnlock()
+	graphsDegraded.Set(float64(degraded))
+}
+
+// graphWarningBanner returns the warning put before the results of tools
+// run against g while it is degraded, or "" if its last validation passed.
+func graphWarningBanner(g *graph.Graph) string {
+	if g == nil {
+		return ""
+	}
+	report := g.Validation()
+	if !report.Degraded() {
+		return ""
+	}
+	kinds := make([]string, 0, len(report.ViolationsByKind)+1)
+	for kind, n := range report.ViolationsByKind {
+		kinds = append(kinds, fmt.Sprintf("%d %s", n, kind))
+	}
+	sort.Strings(kinds)
+	if report.CarriedViolations > 0 {
+		kinds = append(kinds, fmt.Sprintf("%d from an earlier check", report.CarriedViolations))
+	}
+	return fmt.Sprintf("WARNING: the code graph is degraded (%s). Results may be incomplete or wrong until the graph is rebuilt.",
+		strings.Join(kinds, ", "))
+}
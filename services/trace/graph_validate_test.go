@@ -0,0 +1,84 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+func TestHandlers_HandleValidateGraph(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	graphID, cached := cacheGraphWithPlaceholder(t, svc, 0)
+	cached.Graph.SetValidation(&graph.ValidationReport{
+		Mode:             graph.ValidationModeTouched,
+		ViolationCount:   1,
+		ViolationsByKind: map[string]int{graph.ViolationDanglingEdge: 1},
+		Violations:       []graph.Violation{{Kind: graph.ViolationDanglingEdge, FromID: "main.go:3:main", ToID: "gone"}},
+	})
+
+	status := func() GraphStatusResponse {
+		req, _ := http.NewRequest("GET", "/v1/trace/graph/status?graph_id="+graphID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp GraphStatusResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal status: %v", err)
+		}
+		return resp
+	}
+	post := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/v1/trace/graph/validate", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if s := status(); !s.Degraded || s.Validation == nil || s.Validation.ViolationCount != 1 {
+		t.Errorf("status = %+v, want degraded", s)
+	}
+	if banner := graphWarningBanner(cached.Graph); !strings.Contains(banner, "1 dangling_edge") {
+		t.Errorf("banner = %q", banner)
+	}
+
+	if w := post(`{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("missing graph_id: expected %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	if w := post(`{"graph_id":"nonexistent"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown graph: expected %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	w := post(`{"graph_id":"` + graphID + `"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp GraphValidateResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.Degraded || resp.Report == nil || resp.Report.Mode != graph.ValidationModeFull || resp.Report.NodesChecked != 3 {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	if s := status(); s.Degraded || s.Validation == nil || s.Validation.Mode != graph.ValidationModeFull {
+		t.Errorf("status after a clean full check = %+v, want not degraded", s)
+	}
+	if banner := graphWarningBanner(cached.Graph); banner != "" {
+		t.Errorf("banner after a clean full check = %q", banner)
+	}
+}
@@ -11,13 +11,31 @@
 package trace
 
 import (
+	"compress/gzip"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
-
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/egress"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/usage"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
 	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/memory"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/seeder"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/semindex"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
@@ -42,6 +60,21 @@ type Handlers struct {
 	// natsClient is the NATS JetStream client for CRS delta streaming.
 	// CRS-27: Optional. If nil, NATS-related features are disabled.
 	natsClient NATSHealthChecker
+	// safetyGate evaluates tool calls and change plans against the safety
+	// policy. Nil uses a gate with the default policy.
+	safetyGate safety.Evaluator
+
+	// egressAudit holds persisted egress audit records. Nil disables the
+	// audit query endpoint.
+	egressAudit egress.AuditStore
+
+	// usageStore holds persisted LLM usage records. Nil disables the usage
+	// query endpoint.
+	usageStore usage.Store
+
+	// routingReloader reloads the pre-filter rules and tool registry. Nil
+	// disables the reload endpoint.
+	routingReloader *RoutingReloader
 }
 
 // NATSHealthChecker is the interface for NATS health checking.
@@ -63,13 +96,34 @@ func (h *Handlers) WithNATS(client NATSHealthChecker) *Handlers {
 	return h
 }
 
+// WithSafetyGate sets the gate used by the safety evaluation endpoint and
+// the blast radius check on change plans. Nil restores the default policy.
+func (h *Handlers) WithSafetyGate(gate safety.Evaluator) *Handlers {
+	h.safetyGate = gate
+	return h
+}
+
+// WithEgressAudit sets the store queried by the egress audit endpoint.
+// Nil disables the endpoint.
+func (h *Handlers) WithEgressAudit(store egress.AuditStore) *Handlers {
+	h.egressAudit = store
+	return h
+}
+
+// WithUsageStore sets the store queried by the usage endpoint. Nil
+// disables the endpoint.
+func (h *Handlers) WithUsageStore(store usage.Store) *Handlers {
+	h.usageStore = store
+	return h
+}
+
 // WithWeaviate sets the Weaviate client for library seeding and memory.
 func (h *Handlers) WithWeaviate(client *weaviate.Client) *Handlers {
 	h.weaviate = client
 	if client != nil {
 		s, err := seeder.NewSeeder(client, seeder.DefaultSeederConfig())
 		if err != nil {
-			slog.Error("Failed to create seeder", "error", err)
+			slog.Error("Failed to create seeder", telemetry.SensitiveLogError("error", err))
 		} else {
 			h.seeder = s
 		}
@@ -96,21 +150,21 @@ func (h *Handlers) WithMemory(dataSpace string) *Handlers {
 	if h.weaviate != nil {
 		store, err := memory.NewMemoryStore(h.weaviate, dataSpace)
 		if err != nil {
-			slog.Error("Failed to create memory store", "error", err)
+			slog.Error("Failed to create memory store", telemetry.SensitiveLogError("error", err))
 			return h
 		}
 		h.memoryStore = store
 
 		retriever, err := memory.NewMemoryRetriever(h.weaviate, h.memoryStore, dataSpace)
 		if err != nil {
-			slog.Error("Failed to create memory retriever", "error", err)
+			slog.Error("Failed to create memory retriever", telemetry.SensitiveLogError("error", err))
 			return h
 		}
 		h.memoryRetriever = retriever
 
 		lifecycle, err := memory.NewLifecycleManager(h.weaviate, h.memoryStore, dataSpace)
 		if err != nil {
-			slog.Error("Failed to create lifecycle manager", "error", err)
+			slog.Error("Failed to create lifecycle manager", telemetry.SensitiveLogError("error", err))
 			return h
 		}
 		h.lifecycleManager = lifecycle
@@ -144,6 +198,25 @@ func (h *Handlers) WithIndexingCoordinator(coord *SymbolIndexingCoordinator) *Ha
 //	Initializes a code graph for a project. Parses the project files,
 //	builds the code graph and symbol index, and caches the result.
 //
+//	Only one build runs per project. If one is already running, the
+//	request returns 202 with status "already_building" and the build's
+//	progress, unless ?wait=true is set, in which case it blocks until the
+//	running build finishes and returns its result with joined=true.
+//	Queries keep using the previous graph until the new one is ready.
+//
+//	With "async": true in the body, the build runs in the background and
+//	the request returns 202 with a BuildStatusResponse at once; poll
+//	GET /v1/trace/init/:build_id and cancel with DELETE.
+//
+//	Instead of project_root, "roots" or "workspace_file" builds one graph
+//	from several roots (see Workspace). Exactly one of the three is
+//	required.
+//
+// Query Parameters:
+//
+//	wait - If true, block on an in-progress build instead of returning 202.
+//	  Ignored when async is set.
+//
 // Request Body:
 //
 //	InitRequest
@@ -151,52 +224,173 @@ func (h *Handlers) WithIndexingCoordinator(coord *SymbolIndexingCoordinator) *Ha
 // Response:
 //
 //	200 OK: InitResponse
+//	202 Accepted: InitResponse with status "already_building", or
+//	  BuildStatusResponse when async
 //	400 Bad Request: Validation error
+//	409 Conflict: The build was cancelled
 //	500 Internal Server Error: Processing error
 func (h *Handlers) HandleInit(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleInit")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleInit")
 
 	var req InitRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	targets := 0
+	for _, set := range []bool{req.ProjectRoot != "", len(req.Roots) > 0, req.WorkspaceFile != ""} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "exactly one of project_root, roots, or workspace_file is required")
+		return
+	}
+	if req.GitRef != "" && req.ProjectRoot == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "git_ref requires project_root")
+		return
+	}
+	if req.ResolveDependencies && (req.ProjectRoot == "" || req.GitRef != "") {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "resolve_dependencies requires project_root and no git_ref")
+		return
+	}
+	if req.DependencyDepth < 0 || req.DependencyDepth > MaxDependencyDepth {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter,
+			fmt.Sprintf("dependency_depth must be between 1 and %d", MaxDependencyDepth))
+		return
+	}
+	roots := req.Roots
+	if req.WorkspaceFile != "" {
+		var err error
+		if filepath.IsAbs(req.WorkspaceFile) {
+			_, err = h.svc.CheckRoot(req.WorkspaceFile)
+		}
+		if err == nil {
+			roots, err = LoadWorkspaceManifest(req.WorkspaceFile)
+		}
+		if err != nil {
+			statusCode, errCode := initErrorStatus(err)
+			logger.Warn("Invalid workspace manifest", "workspace_file", req.WorkspaceFile, telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, statusCode, errCode, err.Error())
+			return
+		}
+	}
+
+	disabledPasses, passErr := graph.ParseEdgePasses(req.DisabledPasses)
+	if passErr != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, passErr.Error())
+		return
+	}
+
+	placeholderPolicy, policyErr := graph.ParsePlaceholderPolicy(req.PlaceholderPolicy)
+	if policyErr != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, policyErr.Error())
+		return
+	}
+
+	parserSettings, parserErr := h.svc.initParserSettings(req.Languages, req.ParserOptions)
+	if parserErr != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, parserErr.Error())
+		return
+	}
+
+	if !SymlinkPolicy(req.FollowSymlinks).Valid() {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter,
+			fmt.Sprintf("follow_symlinks must be one of %s, %s or %s", SymlinksInsideRoot, SymlinksAlways, SymlinksNever))
+		return
+	}
+
+	if req.GOOS != "" && !ast.KnownGOOS(req.GOOS) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "unknown goos: "+req.GOOS)
+		return
+	}
+	if req.GOARCH != "" && !ast.KnownGOARCH(req.GOARCH) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "unknown goarch: "+req.GOARCH)
 		return
 	}
 
-	logger.Info("Initializing graph", "project_root", req.ProjectRoot)
+	wait := false
+	if v := c.Query("wait"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "wait must be a boolean")
+			return
+		}
+		wait = parsed
+	}
+
+	logger.Info("Initializing graph", telemetry.SensitiveLog("project_root", req.ProjectRoot), telemetry.SensitiveLogStrings("roots", roots), "git_ref", req.GitRef, "wait", wait, "async", req.Async)
 
 	// GR-70a: HandleInit is an explicit user request — always rebuild.
-	resp, err := h.svc.Init(c.Request.Context(), req.ProjectRoot, req.Languages, req.ExcludePatterns, true)
-	if err != nil {
-		statusCode := http.StatusInternalServerError
-		errCode := "INIT_FAILED"
+	discovery := DiscoveryOptions{
+		IgnoreGlobs:         req.IgnoreGlobs,
+		DisableGitignore:    req.DisableGitignore,
+		SkipGenerated:       req.SkipGenerated,
+		GOOS:                req.GOOS,
+		GOARCH:              req.GOARCH,
+		BuildTags:           req.BuildTags,
+		AllBuildProfiles:    req.AllBuildProfiles,
+		GitRef:              req.GitRef,
+		ResolveDependencies: req.ResolveDependencies,
+		DependencyDepth:     req.DependencyDepth,
+		DisabledPasses:      disabledPasses,
+		PlaceholderPolicy:   placeholderPolicy,
+		ParserSettings:      parserSettings,
+		FollowSymlinks:      SymlinkPolicy(req.FollowSymlinks),
+	}
 
-		if errors.Is(err, ErrRelativePath) {
-			statusCode = http.StatusBadRequest
-			errCode = "INVALID_PATH"
-		} else if errors.Is(err, ErrPathTraversal) {
-			statusCode = http.StatusBadRequest
-			errCode = "PATH_TRAVERSAL"
-		} else if errors.Is(err, ErrProjectTooLarge) {
-			statusCode = http.StatusBadRequest
-			errCode = "PROJECT_TOO_LARGE"
-		} else if errors.Is(err, ErrInitInProgress) {
-			statusCode = http.StatusConflict
-			errCode = "INIT_IN_PROGRESS"
-		} else if errors.Is(err, ErrInitTimeout) {
-			statusCode = http.StatusGatewayTimeout
-			errCode = "INIT_TIMEOUT"
+	if req.Async {
+		var (
+			status *BuildStatusResponse
+			err    error
+		)
+		if len(roots) > 0 {
+			status, err = h.svc.StartInitWorkspaceAsync(c.Request.Context(), roots, req.Languages, req.ExcludePatterns, discovery)
+		} else {
+			status, err = h.svc.StartInitAsync(c.Request.Context(), req.ProjectRoot, req.Languages, req.ExcludePatterns, discovery)
+		}
+		if err != nil {
+			statusCode, errCode := initErrorStatus(err)
+			logger.Error("Async init failed", telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, statusCode, errCode, err.Error())
+			return
 		}
+		logger.Info("Async init started", "build_id", status.BuildID, "graph_id", status.GraphID)
+		c.JSON(http.StatusAccepted, status)
+		return
+	}
 
-		logger.Error("Init failed", "error", err)
-		c.JSON(statusCode, ErrorResponse{
-			Error: err.Error(),
-			Code:  errCode,
-		})
+	var (
+		resp *InitResponse
+		err  error
+	)
+	switch {
+	case len(roots) > 0 && wait:
+		resp, err = h.svc.InitWorkspace(c.Request.Context(), roots, req.Languages, req.ExcludePatterns, discovery, true)
+	case len(roots) > 0:
+		resp, err = h.svc.StartInitWorkspace(c.Request.Context(), roots, req.Languages, req.ExcludePatterns, discovery, true)
+	case wait:
+		resp, err = h.svc.InitWithDiscovery(c.Request.Context(), req.ProjectRoot, req.Languages, req.ExcludePatterns, discovery, true)
+	default:
+		resp, err = h.svc.StartInitWithDiscovery(c.Request.Context(), req.ProjectRoot, req.Languages, req.ExcludePatterns, discovery, true)
+	}
+	if err != nil {
+		statusCode, errCode := initErrorStatus(err)
+		logger.Error("Init failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, statusCode, errCode, err.Error())
+		return
+	}
+
+	if resp.Status == InitStatusAlreadyBuilding {
+		logger.Info("Init already in progress",
+			"graph_id", resp.GraphID,
+			"phase", resp.Progress.Phase,
+			"elapsed_ms", resp.Progress.ElapsedMs)
+		c.JSON(http.StatusAccepted, resp)
 		return
 	}
 
@@ -204,11 +398,189 @@ func (h *Handlers) HandleInit(c *gin.Context) {
 		"graph_id", resp.GraphID,
 		"files_parsed", resp.FilesParsed,
 		"symbols_extracted", resp.SymbolsExtracted,
-		"parse_time_ms", resp.ParseTimeMs)
+		"parse_time_ms", resp.ParseTimeMs,
+		"joined", resp.Joined)
 
 	c.JSON(http.StatusOK, resp)
 }
 
+// HandleInitFromParseResults handles POST /v1/trace/init/from-parse-results.
+//
+// Description:
+//
+//	Builds a graph from parse results computed elsewhere, skipping file
+//	discovery and parsing (see Service.InitFromParseResults). The body is
+//	either newline-delimited JSON (application/x-ndjson) with one
+//	ast.ParseResult per line or a JSON array of them, optionally gzipped
+//	(Content-Encoding: gzip or Content-Type: application/gzip).
+//
+// Query Parameters:
+//
+//	project_root - Absolute project root the results' paths are relative
+//	  to (required). It need not exist on this machine.
+//
+// Request Body:
+//
+//	NDJSON or a JSON array of ast.ParseResult, at most
+//	MaxParseResultsBytes decompressed and MaxParseResultBytes per result.
+//
+// Response:
+//
+//	200 OK: ParseResultsInitResponse
+//	400 Bad Request: Invalid project root or parse result
+//	403 Forbidden: project_root outside the allowed roots
+//	413 Request Entity Too Large: Body or a result exceeds the size limits
+//	500 Internal Server Error: Build failed
+func (h *Handlers) HandleInitFromParseResults(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleInitFromParseResults")
+
+	projectRoot := c.Query("project_root")
+	if projectRoot == "" {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "project_root is required")
+		return
+	}
+
+	limit := h.svc.config.MaxParseResultsBytes
+	var body io.Reader = c.Request.Body
+	if limit > 0 {
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+	}
+	contentType := strings.ToLower(c.ContentType())
+	if strings.EqualFold(c.GetHeader("Content-Encoding"), "gzip") ||
+		contentType == "application/gzip" || contentType == "application/x-gzip" {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "invalid gzip body: "+err.Error())
+			return
+		}
+		defer gz.Close()
+		body = gz
+		if limit > 0 {
+			// Bound the decompressed size too.
+			body = http.MaxBytesReader(nil, gz, limit)
+		}
+	}
+
+	results, err := DecodeParseResults(body, h.svc.config.MaxParseResultBytes, h.svc.config.MaxProjectFiles)
+	if err == nil {
+		var resp *ParseResultsInitResponse
+		resp, err = h.svc.InitFromParseResults(c.Request.Context(), projectRoot, results)
+		if err == nil {
+			logger.Info("Graph initialized from parse results",
+				"graph_id", resp.GraphID,
+				"project_root", projectRoot,
+				"results", len(results),
+				"nodes", resp.Build.NodesCreated,
+				"edges", resp.Build.EdgesCreated)
+			c.JSON(http.StatusOK, resp)
+			return
+		}
+	}
+	statusCode, errCode := initErrorStatus(err)
+	logger.Warn("Init from parse results failed", "project_root", projectRoot, telemetry.SensitiveLogError("error", err))
+	apierror.Write(c, statusCode, errCode, err.Error())
+}
+
+// initErrorStatus maps an Init error to an HTTP status and error code.
+func initErrorStatus(err error) (int, apierror.Code) {
+	switch {
+	case errors.Is(err, ErrRelativePath):
+		return http.StatusBadRequest, apierror.CodeInvalidPath
+	case errors.Is(err, ErrPathTraversal):
+		return http.StatusBadRequest, apierror.CodePathTraversal
+	case errors.Is(err, ErrRootNotAllowed):
+		return http.StatusForbidden, apierror.CodeRootNotAllowed
+	case errors.Is(err, ErrInvalidWorkspace):
+		return http.StatusBadRequest, apierror.CodeInvalidWorkspace
+	case errors.Is(err, ErrInvalidGitRef):
+		return http.StatusBadRequest, apierror.CodeInvalidGitRef
+	case errors.Is(err, ErrProjectTooLarge):
+		return http.StatusBadRequest, apierror.CodeProjectTooLarge
+	case errors.Is(err, ErrInvalidParseResult):
+		return http.StatusBadRequest, apierror.CodeInvalidParseResult
+	case errors.Is(err, ErrPayloadTooLarge):
+		return http.StatusRequestEntityTooLarge, apierror.CodePayloadTooLarge
+	case errors.Is(err, ErrInitInProgress):
+		return http.StatusConflict, apierror.CodeInitInProgress
+	case errors.Is(err, ErrInitCancelled):
+		return http.StatusConflict, apierror.CodeInitCancelled
+	case errors.Is(err, ErrInitTimeout):
+		return http.StatusGatewayTimeout, apierror.CodeInitTimeout
+	case errors.Is(err, ast.ErrParseFailed):
+		return http.StatusUnprocessableEntity, apierror.CodeParseFailed
+	default:
+		return http.StatusInternalServerError, apierror.CodeInternal
+	}
+}
+
+// HandleInitStatus handles GET /v1/trace/init/:build_id.
+//
+// Description:
+//
+//	Returns a build's progress (phase, files processed/total, nodes and
+//	edges created, parse errors so far) and, once it has completed, the
+//	final InitResponse. Finished builds stay queryable until pruned.
+//
+// Path Parameters:
+//
+//	build_id - Build ID from POST /v1/trace/init.
+//
+// Response:
+//
+//	200 OK: BuildStatusResponse
+//	404 Not Found: Unknown or pruned build ID
+func (h *Handlers) HandleInitStatus(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleInitStatus")
+
+	buildID := c.Param("build_id")
+	status, err := h.svc.BuildStatus(buildID)
+	if err != nil {
+		logger.Debug("Build not found", "build_id", buildID)
+		apierror.Write(c, http.StatusNotFound, apierror.CodeBuildNotFound, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// HandleCancelInit handles DELETE /v1/trace/init/:build_id.
+//
+// Description:
+//
+//	Cancels a running build. The cancelled build never installs its
+//	graph, so the project keeps its previous graph or stays
+//	uninitialized. The returned state is usually still "running"; poll
+//	GET /v1/trace/init/:build_id until it becomes "cancelled".
+//
+// Path Parameters:
+//
+//	build_id - Build ID from POST /v1/trace/init.
+//
+// Response:
+//
+//	202 Accepted: BuildStatusResponse, cancellation requested
+//	200 OK: BuildStatusResponse, the build had already finished
+//	404 Not Found: Unknown or pruned build ID
+func (h *Handlers) HandleCancelInit(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleCancelInit")
+
+	buildID := c.Param("build_id")
+	status, cancelled, err := h.svc.CancelBuild(buildID)
+	if err != nil {
+		logger.Debug("Build not found", "build_id", buildID)
+		apierror.Write(c, http.StatusNotFound, apierror.CodeBuildNotFound, err.Error())
+		return
+	}
+	if !cancelled {
+		c.JSON(http.StatusOK, status)
+		return
+	}
+	logger.Info("Build cancellation requested", "build_id", buildID)
+	c.JSON(http.StatusAccepted, status)
+}
+
 // HandleContext handles POST /v1/trace/context.
 //
 // Description:
@@ -226,15 +598,12 @@ func (h *Handlers) HandleInit(c *gin.Context) {
 //	500 Internal Server Error: Processing error
 func (h *Handlers) HandleContext(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleContext")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleContext")
 
 	var req ContextRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
@@ -252,27 +621,27 @@ func (h *Handlers) HandleContext(c *gin.Context) {
 	resp, err := h.svc.GetContext(c.Request.Context(), req.GraphID, req.Query, budget)
 	if err != nil {
 		statusCode := http.StatusInternalServerError
-		errCode := "CONTEXT_FAILED"
+		errCode := apierror.CodeInternal
 
 		if errors.Is(err, ErrGraphNotInitialized) {
 			statusCode = http.StatusBadRequest
-			errCode = "GRAPH_NOT_INITIALIZED"
+			errCode = apierror.CodeGraphNotInitialized
 		} else if errors.Is(err, ErrGraphExpired) {
 			statusCode = http.StatusBadRequest
-			errCode = "GRAPH_EXPIRED"
+			errCode = apierror.CodeGraphExpired
 		} else if errors.Is(err, cbcontext.ErrEmptyQuery) {
 			statusCode = http.StatusBadRequest
-			errCode = "EMPTY_QUERY"
+			errCode = apierror.CodeEmptyQuery
 		} else if errors.Is(err, cbcontext.ErrQueryTooLong) {
 			statusCode = http.StatusBadRequest
-			errCode = "QUERY_TOO_LONG"
+			errCode = apierror.CodeQueryTooLong
+		} else if errors.Is(err, cbcontext.ErrLLMUnavailable) || errors.Is(err, cbcontext.ErrCircuitOpen) {
+			statusCode = http.StatusServiceUnavailable
+			errCode = apierror.CodeProviderUnavailable
 		}
 
-		logger.Error("Context assembly failed", "error", err)
-		c.JSON(statusCode, ErrorResponse{
-			Error: err.Error(),
-			Code:  errCode,
-		})
+		logger.Error("Context assembly failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, statusCode, errCode, err.Error())
 		return
 	}
 
@@ -287,11 +656,13 @@ func (h *Handlers) HandleContext(c *gin.Context) {
 //
 // Description:
 //
-//	Retrieves detailed information about a symbol by its ID.
+//	Retrieves detailed information about a symbol by its ID, including
+//	its annotations.
 //
 // Query Parameters:
 //
 //	graph_id: ID of the graph to query (required)
+//	debug: Add the symbol's edges with their provenance (optional)
 //
 // Path Parameters:
 //
@@ -304,49 +675,127 @@ func (h *Handlers) HandleContext(c *gin.Context) {
 //	404 Not Found: Symbol not found
 func (h *Handlers) HandleSymbol(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleSymbol")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSymbol")
 
 	graphID := c.Query("graph_id")
 	if graphID == "" {
 		logger.Warn("Missing graph_id parameter")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "graph_id parameter is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "graph_id parameter is required")
 		return
 	}
 
 	symbolID := c.Param("id")
 	if symbolID == "" {
 		logger.Warn("Missing symbol id")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "symbol id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "symbol id is required")
 		return
 	}
 
-	logger.Info("Getting symbol", "graph_id", graphID, "symbol_id", symbolID)
+	logger.Info("Getting symbol", "graph_id", graphID, telemetry.SensitiveLog("symbol_id", symbolID))
 
 	sym, err := h.svc.GetSymbol(c.Request.Context(), graphID, symbolID)
 	if err != nil {
 		if errors.Is(err, ErrGraphNotInitialized) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error: err.Error(),
-				Code:  "GRAPH_NOT_INITIALIZED",
-			})
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
 			return
 		}
 
-		logger.Warn("Symbol not found", "error", err)
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error: err.Error(),
-			Code:  "SYMBOL_NOT_FOUND",
-		})
+		logger.Warn("Symbol not found", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusNotFound, apierror.CodeSymbolNotFound, err.Error())
 		return
 	}
 
-	c.JSON(http.StatusOK, SymbolResponse{Symbol: sym})
+	if c.Query("debug") == "true" {
+		if err := h.svc.AnnotateSymbolEdges(graphID, sym); err != nil {
+			logger.Warn("Annotating symbol edges failed", telemetry.SensitiveLogError("error", err))
+		}
+	}
+
+	owners, _ := h.svc.OwnersOf(graphID, sym.FilePath)
+	annotations, _ := h.svc.AnnotationsOf(graphID, sym.ID)
+	resp := SymbolResponse{
+		Symbol:      sym,
+		Owners:      ownersOrEmpty(owners),
+		Annotations: annotationsOrEmpty(annotations),
+	}
+	h.linkLocations(c, graphID, &resp)
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleSymbolSource handles GET /v1/trace/symbol/:id/source.
+//
+// Description:
+//
+//	Returns the source text of a symbol plus surrounding context lines,
+//	with a hash of the whole file for staleness detection.
+//
+// Query Parameters:
+//
+//	graph_id: ID of the graph to query (required)
+//	context: Lines of context before and after the symbol (default 5, max 100)
+//
+// Path Parameters:
+//
+//	id: Symbol ID (required)
+//
+// Response:
+//
+//	200 OK: graph.SymbolSource
+//	400 Bad Request: Missing/invalid parameter, graph not initialized, or invalid file path
+//	404 Not Found: Symbol not found
+//	500 Internal Server Error: Source file could not be read
+func (h *Handlers) HandleSymbolSource(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSymbolSource")
+
+	graphID := c.Query("graph_id")
+	if graphID == "" {
+		logger.Warn("Missing graph_id parameter")
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "graph_id parameter is required")
+		return
+	}
+
+	symbolID := c.Param("id")
+	if symbolID == "" {
+		logger.Warn("Missing symbol id")
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "symbol id is required")
+		return
+	}
+
+	contextLines := 5
+	if raw := c.Query("context"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 || n > graph.MaxSymbolSourceContextLines {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, fmt.Sprintf("context must be an integer between 0 and %d", graph.MaxSymbolSourceContextLines))
+			return
+		}
+		contextLines = n
+	}
+
+	logger.Info("Getting symbol source", "graph_id", graphID, telemetry.SensitiveLog("symbol_id", symbolID), "context", contextLines)
+
+	src, err := h.svc.GetSymbolSource(c.Request.Context(), graphID, symbolID, contextLines)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrGraphNotInitialized):
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
+		case errors.Is(err, graph.ErrSymbolNotFound):
+			logger.Warn("Symbol not found", telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusNotFound, apierror.CodeSymbolNotFound, err.Error())
+		case errors.Is(err, os.ErrNotExist):
+			logger.Warn("Symbol source file missing", telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusNotFound, apierror.CodeFileNotFound, err.Error())
+		case errors.Is(err, graph.ErrEmptyFilePath), errors.Is(err, graph.ErrPathTraversal):
+			logger.Warn("Rejected symbol file path", telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidFilePath, err.Error())
+		default:
+			logger.Error("Failed to read symbol source", telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, src)
 }
 
 // HandleCallers handles GET /v1/trace/callers.
@@ -359,56 +808,74 @@ func (h *Handlers) HandleSymbol(c *gin.Context) {
 //
 //	graph_id: ID of the graph to query (required)
 //	function: Name of the function to find callers for (required)
-//	limit: Maximum number of results (optional, default 50)
+//	limit: Page size (optional, default 50, max 1000)
+//	cursor: next_cursor of the previous page (optional)
+//	root: Workspace root to restrict results to (optional)
+//	scope: Named view to restrict results to (optional)
+//	debug: Add each caller's CALLS edges with their provenance (optional)
 //
 // Response:
 //
 //	200 OK: CallersResponse (may be empty array)
-//	400 Bad Request: Missing parameters or graph not initialized
+//	400 Bad Request: Missing parameters, unknown root or scope, graph not initialized, or malformed cursor
+//	409 Conflict: Cursor from a graph generation that was replaced
 func (h *Handlers) HandleCallers(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleCallers")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleCallers")
 
 	var req CallersRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		logger.Warn("Invalid query parameters", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid query parameters: graph_id and function are required",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid query parameters", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid query parameters: graph_id and function are required")
 		return
 	}
 
-	if req.Limit <= 0 {
-		req.Limit = 50
+	if req.Root != "" {
+		if err := h.svc.CheckRootFilter(req.GraphID, req.Root); err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+			return
+		}
+	}
+	view, ok := h.scopeFilter(c, req.GraphID, req.Scope)
+	if !ok {
+		return
+	}
+	page, ok := h.pageQuery(c, req.GraphID, req.Limit, req.Cursor, "callers", req.Function, req.Root, req.Scope)
+	if !ok {
+		return
 	}
 
-	logger.Info("Finding callers", "graph_id", req.GraphID, "function", req.Function)
+	logger.Info("Finding callers", "graph_id", req.GraphID, telemetry.SensitiveLog("function", req.Function))
 
-	callers, err := h.svc.FindCallers(c.Request.Context(), req.GraphID, req.Function, req.Limit)
+	callers, err := h.svc.FindCallers(c.Request.Context(), req.GraphID, req.Function, queryPageScanLimit)
 	if err != nil {
 		if errors.Is(err, ErrGraphNotInitialized) || errors.Is(err, ErrGraphExpired) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error: err.Error(),
-				Code:  "GRAPH_NOT_INITIALIZED",
-			})
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
 			return
 		}
 
-		logger.Error("Find callers failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "QUERY_FAILED",
-		})
+		logger.Error("Find callers failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
-	logger.Info("Found callers", "count", len(callers))
+	scanFull := len(callers) >= queryPageScanLimit
+	callers, pageInfo := pageOf(filterSymbolResults(callers, view, req.Root, len(callers)), page, scanFull)
+	if req.Debug {
+		if err := h.svc.AnnotateCallEdges(req.GraphID, req.Function, callers, true); err != nil {
+			logger.Warn("Annotating caller edges failed", telemetry.SensitiveLogError("error", err))
+		}
+	}
 
-	c.JSON(http.StatusOK, CallersResponse{
+	logger.Info("Found callers", "count", len(callers), "total", pageInfo.Total)
+
+	resp := CallersResponse{
 		Function: req.Function,
 		Callers:  callers,
-	})
+		PageInfo: pageInfo,
+	}
+	h.linkLocations(c, req.GraphID, &resp)
+	c.JSON(http.StatusOK, resp)
 }
 
 // HandleImplementations handles GET /v1/trace/implementations.
@@ -421,56 +888,68 @@ func (h *Handlers) HandleCallers(c *gin.Context) {
 //
 //	graph_id: ID of the graph to query (required)
 //	interface: Name of the interface to find implementations for (required)
-//	limit: Maximum number of results (optional, default 50)
+//	limit: Page size (optional, default 50, max 1000)
+//	cursor: next_cursor of the previous page (optional)
+//	root: Workspace root to restrict results to (optional)
+//	scope: Named view to restrict results to (optional)
 //
 // Response:
 //
 //	200 OK: ImplementationsResponse (may be empty array)
-//	400 Bad Request: Missing parameters or graph not initialized
+//	400 Bad Request: Missing parameters, unknown root or scope, graph not initialized, or malformed cursor
+//	409 Conflict: Cursor from a graph generation that was replaced
 func (h *Handlers) HandleImplementations(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleImplementations")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleImplementations")
 
 	var req ImplementationsRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		logger.Warn("Invalid query parameters", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid query parameters: graph_id and interface are required",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid query parameters", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid query parameters: graph_id and interface are required")
 		return
 	}
 
-	if req.Limit <= 0 {
-		req.Limit = 50
+	if req.Root != "" {
+		if err := h.svc.CheckRootFilter(req.GraphID, req.Root); err != nil {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+			return
+		}
+	}
+	view, ok := h.scopeFilter(c, req.GraphID, req.Scope)
+	if !ok {
+		return
+	}
+	page, ok := h.pageQuery(c, req.GraphID, req.Limit, req.Cursor, "implementations", req.Interface, req.Root, req.Scope)
+	if !ok {
+		return
 	}
 
 	logger.Info("Finding implementations", "graph_id", req.GraphID, "interface", req.Interface)
 
-	implementations, err := h.svc.FindImplementations(c.Request.Context(), req.GraphID, req.Interface, req.Limit)
+	implementations, err := h.svc.FindImplementations(c.Request.Context(), req.GraphID, req.Interface, queryPageScanLimit)
 	if err != nil {
 		if errors.Is(err, ErrGraphNotInitialized) || errors.Is(err, ErrGraphExpired) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error: err.Error(),
-				Code:  "GRAPH_NOT_INITIALIZED",
-			})
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
 			return
 		}
 
-		logger.Error("Find implementations failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "QUERY_FAILED",
-		})
+		logger.Error("Find implementations failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
-	logger.Info("Found implementations", "count", len(implementations))
+	scanFull := len(implementations) >= queryPageScanLimit
+	implementations, pageInfo := pageOf(filterSymbolResults(implementations, view, req.Root, len(implementations)), page, scanFull)
 
-	c.JSON(http.StatusOK, ImplementationsResponse{
+	logger.Info("Found implementations", "count", len(implementations), "total", pageInfo.Total)
+
+	resp := ImplementationsResponse{
 		Interface:       req.Interface,
 		Implementations: implementations,
-	})
+		PageInfo:        pageInfo,
+	}
+	h.linkLocations(c, req.GraphID, &resp)
+	c.JSON(http.StatusOK, resp)
 }
 
 // HandleIndexingStatus handles GET /v1/trace/indexing/status.
@@ -493,58 +972,189 @@ func (h *Handlers) HandleIndexingStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, h.indexingCoord.GetProgress())
 }
 
-// HandleHealth handles GET /v1/trace/health.
+// HandleEnrichmentStatus handles GET /v1/trace/enrichment/status.
 //
 // Description:
 //
-//	Returns the health status of the service. Always returns 200 if running.
+//	Returns the progress of synthetic doc generation for undocumented
+//	symbols: queue depth, symbols completed and failed, per graph.
+//	Reports enabled=false when doc enrichment is not configured.
 //
 // Response:
 //
-//	200 OK: HealthResponse
-func (h *Handlers) HandleHealth(c *gin.Context) {
-	resp := HealthResponse{
-		Status:  "healthy",
-		Version: ServiceVersion,
+//	200 OK: docenrich.Status
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleEnrichmentStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.svc.DocEnrichmentStatus())
+}
+
+// HandleSemanticSearch handles GET /v1/trace/search/semantic.
+//
+// Description:
+//
+//	Ranks the functions and methods of a graph by similarity to a natural
+//	language query, e.g. "validate email addresses". With the semantic
+//	index enabled, the query is embedded by the local model and compared
+//	to the symbols' vectors; while the graph is not indexed, or when the
+//	model is unavailable, the search falls back to keyword matching and
+//	reports mode "lexical" with the reason.
+//
+// Query Parameters:
+//
+//	graph_id: ID of the graph to search (required)
+//	query: Natural language query (required)
+//	limit: Maximum results (optional, default 10, max 100)
+//	min_score: Minimum cosine similarity of a semantic result (optional, default 0)
+//
+// Response:
+//
+//	200 OK: SemanticSearchResponse (may be empty array)
+//	400 Bad Request: Missing parameters or graph not initialized
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleSemanticSearch(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSemanticSearch")
+
+	var req SemanticSearchRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid query parameters: graph_id and query are required")
+		return
+	}
+	switch {
+	case req.Limit <= 0:
+		req.Limit = 10
+	case req.Limit > 100:
+		req.Limit = 100
 	}
 
-	// GR-75: Include LSP status when enrichment is enabled.
-	enabled, languages := h.svc.LSPEnrichmentStatus()
-	if enabled {
-		resp.LSP = &LSPHealthStatus{
-			Enabled:    true,
-			Python:     LSPLanguageStatus{Available: languages["python"]},
-			TypeScript: LSPLanguageStatus{Available: languages["typescript"]},
-			JavaScript: LSPLanguageStatus{Available: languages["javascript"]},
+	res, err := h.svc.SemanticSearch(c.Request.Context(), req.GraphID, req.Query, req.Limit, req.MinScore)
+	if err != nil {
+		if errors.Is(err, ErrGraphNotInitialized) || errors.Is(err, ErrGraphExpired) {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
+			return
+		}
+		if errors.Is(err, semindex.ErrEmptyQuery) {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+			return
 		}
+		logger.Error("Semantic search failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		return
 	}
 
+	results := make([]SemanticSearchResult, 0, len(res.Hits))
+	for _, hit := range res.Hits {
+		results = append(results, SemanticSearchResult{Symbol: SymbolInfoFromAST(hit.Symbol), Score: hit.Score})
+	}
+	logger.Info("Semantic search", "mode", res.Mode, "count", len(results), "scanned", res.Scanned)
+
+	resp := SemanticSearchResponse{
+		Query:          req.Query,
+		Mode:           res.Mode,
+		FallbackReason: res.FallbackReason,
+		Results:        results,
+		Scanned:        res.Scanned,
+		Capped:         res.Capped,
+		Coverage:       h.svc.SemanticIndexCoverage(req.GraphID),
+	}
+	h.linkLocations(c, req.GraphID, &resp)
 	c.JSON(http.StatusOK, resp)
 }
 
-// HandleReady handles GET /v1/trace/ready.
+// HandleSemanticIndexStatus handles GET /v1/trace/search/semantic/status.
 //
 // Description:
 //
-//	Returns the readiness status of the service including dependency checks.
-//	Returns 503 Service Unavailable if model warmup has not completed.
+//	Returns the progress and coverage of semantic indexing per graph.
+//	Reports enabled=false when the semantic index is not configured.
 //
 // Response:
 //
-//	200 OK: ReadyResponse (Ready=true) - Service is fully ready
-//	503 Service Unavailable: ReadyResponse (Ready=false) - Warmup in progress
+//	200 OK: semindex.Status
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleSemanticIndexStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.svc.SemanticIndexStatus())
+}
+
+// HandleLive handles GET /v1/trace/health/live.
+//
+// Description:
+//
+//	Liveness probe. Returns 200 whenever the process is up and serving
+//	HTTP; it checks no dependencies, so a down provider never gets the
+//	pod restarted.
+//
+// Response:
+//
+//	200 OK: HealthResponse
+func (h *Handlers) HandleLive(c *gin.Context) {
+	c.JSON(http.StatusOK, HealthResponse{
+		Status:  "healthy",
+		Version: ServiceVersion,
+		LSP:     h.lspHealth(),
+	})
+}
+
+// HandleReady handles GET /v1/trace/health/ready, and its aliases
+// GET /v1/trace/ready and GET /v1/trace/health.
+//
+// Description:
+//
+//	Readiness probe. Ready requires every model role registered for
+//	warmup to have finished, and every required subsystem (the configured
+//	main and router providers) to pass its connectivity check. Check
+//	results are cached for DefaultHealthCheckTTL. The body reports each
+//	warmup role and subsystem with its status, last error and last check
+//	time.
+//
+// Response:
+//
+//	200 OK: ReadyResponse (Ready=true) - Ready; status "degraded" if an
+//	        optional subsystem is failing
+//	503 Service Unavailable: ReadyResponse (Ready=false) - Warmup in
+//	        progress or a required subsystem is failing
+//
+// Thread Safety: This method is safe for concurrent use.
 func (h *Handlers) HandleReady(c *gin.Context) {
-	// Check warmup status - return 503 if still warming up
 	warmupComplete := IsWarmupComplete()
+	subsystems := append(defaultHealthRegistry.Check(c.Request.Context()), h.graphStoreHealth())
+
+	ready := warmupComplete
+	degraded := false
+	for _, s := range subsystems {
+		if s.Status != SubsystemFailing {
+			continue
+		}
+		if s.Required {
+			ready = false
+		} else {
+			degraded = true
+		}
+	}
 
 	resp := ReadyResponse{
-		Ready:      warmupComplete,
-		GraphCount: h.svc.GraphCount(),
-		WeaviateOK: false,
-		NATSOK:     false,
+		Ready:                   ready,
+		Status:                  "healthy",
+		Version:                 ServiceVersion,
+		Warmup:                  WarmupStatuses(),
+		Subsystems:              subsystems,
+		LSP:                     h.lspHealth(),
+		LLMCircuits:             circuitStates(),
+		RoutingConfigGeneration: config.RoutingConfigGeneration(),
+		GraphCount:              h.svc.GraphCount(),
+		WeaviateOK:              false,
+		NATSOK:                  h.natsClient != nil && h.natsClient.IsConnected(),
+	}
+	if degraded {
+		resp.Status = "degraded"
 	}
 
-	if !warmupComplete {
+	if !ready {
+		resp.Status = "unhealthy"
 		c.Header("Retry-After", "30")
 		c.JSON(http.StatusServiceUnavailable, resp)
 		return
@@ -553,6 +1163,67 @@ func (h *Handlers) HandleReady(c *gin.Context) {
 	c.JSON(http.StatusOK, resp)
 }
 
+// lspHealth returns the LSP status, or nil when enrichment is disabled.
+// GR-75: Reports per-language availability for container diagnostics.
+func (h *Handlers) lspHealth() *LSPHealthStatus {
+	enabled, languages := h.svc.LSPEnrichmentStatus()
+	if !enabled {
+		return nil
+	}
+	return &LSPHealthStatus{
+		Enabled:    true,
+		Python:     LSPLanguageStatus{Available: languages["python"]},
+		TypeScript: LSPLanguageStatus{Available: languages["typescript"]},
+		JavaScript: LSPLanguageStatus{Available: languages["javascript"]},
+	}
+}
+
+// graphStoreHealth reports the graph store. Graphs are held in memory and,
+// when BboltDir is set, persisted there; an unusable directory only means
+// graphs are rebuilt on restart, so the subsystem is optional. No graph
+// being loaded is normal for a fresh instance and is not a failure.
+func (h *Handlers) graphStoreHealth() SubsystemHealth {
+	now := time.Now()
+	health := SubsystemHealth{
+		Name:          SubsystemGraphStore,
+		Status:        SubsystemOK,
+		Detail:        fmt.Sprintf("%d graphs loaded, in memory", h.svc.GraphCount()),
+		LastCheckedAt: &now,
+	}
+	dir := h.svc.config.BboltDir
+	if dir == "" {
+		return health
+	}
+	health.Detail = fmt.Sprintf("%d graphs loaded, persisted to %s", h.svc.GraphCount(), dir)
+	if info, err := os.Stat(dir); err != nil {
+		health.Status = SubsystemFailing
+		health.LastError = err.Error()
+		health.LastErrorAt = &now
+	} else if !info.IsDir() {
+		health.Status = SubsystemFailing
+		health.LastError = dir + " is not a directory"
+		health.LastErrorAt = &now
+	}
+	return health
+}
+
+// HandleListErrors handles GET /v1/trace/errors.
+//
+// Description:
+//
+//	Returns the catalog of error codes with their default HTTP status,
+//	title and meaning, so client generators can build enums. Problem
+//	responses link here through their "type" member.
+//
+// Response:
+//
+//	200 OK: ErrorCatalogResponse
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *Handlers) HandleListErrors(c *gin.Context) {
+	c.JSON(http.StatusOK, ErrorCatalogResponse{Errors: apierror.Catalog()})
+}
+
 // HandleGetGraphStats handles GET /v1/trace/debug/graph/stats.
 //
 // Description:
@@ -574,7 +1245,7 @@ func (h *Handlers) HandleReady(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use. Read-only access to graph.
 func (h *Handlers) HandleGetGraphStats(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleGetGraphStats")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGetGraphStats")
 
 	graphID := c.Query("graph_id")
 
@@ -594,11 +1265,8 @@ func (h *Handlers) HandleGetGraphStats(c *gin.Context) {
 		// Try to get specific graph
 		cached, err = h.svc.GetGraph(graphID)
 		if err != nil {
-			logger.Warn("Graph not found", "graph_id", graphID, "error", err)
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "graph not found",
-				Code:  "GRAPH_NOT_FOUND",
-			})
+			logger.Warn("Graph not found", "graph_id", graphID, telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusNotFound, apierror.CodeGraphNotFound, "graph not found")
 			return
 		}
 	} else {
@@ -606,13 +1274,10 @@ func (h *Handlers) HandleGetGraphStats(c *gin.Context) {
 		cached = h.svc.getFirstGraph()
 		if cached == nil {
 			logger.Info("No graphs cached")
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "no graphs cached",
-				Code:  "NO_GRAPHS",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeNoGraphs, "no graphs cached")
 			return
 		}
-		graphID = h.svc.generateGraphID(cached.ProjectRoot)
+		graphID = h.svc.cachedGraphID(cached)
 	}
 
 	// Get stats from the graph
@@ -647,9 +1312,217 @@ func (h *Handlers) HandleGetGraphStats(c *gin.Context) {
 		BuiltAtMilli: stats.BuiltAtMilli,
 		EdgesByType:  edgesByType,
 		NodesByKind:  nodesByKind,
+
+		LiteralUsages:        stats.LiteralUsages,
+		LiteralUsagesDropped: stats.LiteralUsagesDropped,
 	})
 }
 
+// HandleGraphStatus handles GET /v1/trace/graph/status.
+//
+// Description:
+//
+//	Returns a summary of a cached graph, including its content hash.
+//	Two builds of the same source report the same content_hash, so
+//	clients can detect whether a rebuild changed anything. degraded and
+//	validation report the graph's last invariant check.
+//
+// Query Parameters:
+//
+//	graph_id: ID of the graph to query (optional, uses first cached if not specified)
+//	project_root: Project root to look up graph (alternative to graph_id)
+//	git_ref: With project_root, the git_ref the graph was built from
+//
+// Response:
+//
+//	200 OK: GraphStatusResponse
+//	404 Not Found: No graphs cached or graph not found
+//
+// Thread Safety: This method is safe for concurrent use. Read-only access to graph.
+func (h *Handlers) HandleGraphStatus(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGraphStatus")
+
+	graphID, cached, ok := h.statusGraph(c, logger)
+	if !ok {
+		return
+	}
+
+	contentHash := cached.ContentHash
+	if contentHash == "" {
+		// Graphs cached without a recorded hash (e.g. loaded from a snapshot).
+		contentHash = cached.Graph.ContentHash()
+	}
+	validation := cached.Graph.Validation()
+
+	c.JSON(http.StatusOK, GraphStatusResponse{
+		GraphID:          graphID,
+		ProjectRoot:      cached.ProjectRoot,
+		State:            cached.Graph.State().String(),
+		NodeCount:        cached.Graph.NodeCount(),
+		EdgeCount:        cached.Graph.EdgeCount(),
+		BuiltAtMilli:     cached.BuiltAtMilli,
+		ExpiresAtMilli:   cached.ExpiresAtMilli,
+		ContentHash:      contentHash,
+		Roots:            cached.RootStats,
+		GitRef:           cached.GitRef,
+		CommitSHA:        cached.CommitSHA,
+		FromParseResults: cached.FromParseResults,
+		Degraded:         validation.Degraded(),
+		Validation:       validation,
+	})
+}
+
+// HandleGraphReport handles GET /v1/trace/graph/report.
+//
+// Description:
+//
+//	Returns the quality report of a cached graph: symbols per language
+//	and kind, call and import resolution, interface satisfaction, files
+//	with parse errors, the most frequent unresolved call targets and the
+//	composite confidence score. The report is computed when the graph is
+//	built and persisted with it; graphs without one get it computed now.
+//	When the semantic index is enabled, semantic_index reports how many
+//	of the graph's functions and methods have a vector.
+//
+// Query Parameters:
+//
+//	graph_id: ID of the graph to query (optional, uses first cached if not specified)
+//	project_root: Project root to look up graph (alternative to graph_id)
+//	git_ref: With project_root, the git_ref the graph was built from
+//
+// Response:
+//
+//	200 OK: GraphReportResponse
+//	404 Not Found: No graphs cached or graph not found
+//
+// Thread Safety: This method is safe for concurrent use. Read-only access to graph.
+func (h *Handlers) HandleGraphReport(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGraphReport")
+
+	graphID, cached, ok := h.statusGraph(c, logger)
+	if !ok {
+		return
+	}
+
+	report := cached.Graph.QualityReport
+	if report == nil {
+		report = graph.NewQualityReport(cached.Graph, nil)
+	}
+	c.JSON(http.StatusOK, GraphReportResponse{
+		GraphID:       graphID,
+		ProjectRoot:   cached.ProjectRoot,
+		QualityReport: report,
+		SemanticIndex: h.svc.SemanticIndexCoverage(graphID),
+	})
+}
+
+// HandleGraphManifest handles GET /v1/trace/graph/manifest.
+//
+// Description:
+//
+//	Returns the content hash (SHA-256 hex, as in ast.ParseResult.Hash) of
+//	every file the graph was built from. Clients hash the files on disk the
+//	same way to find changed, added and removed files without re-parsing.
+//
+// Query Parameters:
+//
+//	graph_id: ID of the graph to query (optional, uses first cached if not specified)
+//	project_root: Project root to look up graph (alternative to graph_id)
+//	git_ref: With project_root, the git_ref the graph was built from
+//
+// Response:
+//
+//	200 OK: GraphManifestResponse
+//	404 Not Found: No graphs cached or graph not found
+//	409 Conflict: The graph has no recorded file hashes
+//
+// Thread Safety: This method is safe for concurrent use. Read-only access to graph.
+func (h *Handlers) HandleGraphManifest(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGraphManifest")
+
+	graphID, cached, ok := h.statusGraph(c, logger)
+	if !ok {
+		return
+	}
+
+	files := cached.Graph.FileHashes
+	if files == nil {
+		logger.Info("Graph has no file hashes", "graph_id", graphID)
+		apierror.Write(c, http.StatusConflict, apierror.CodeManifestUnavailable, "graph has no recorded file hashes")
+		return
+	}
+
+	contentHash := cached.ContentHash
+	if contentHash == "" {
+		contentHash = cached.Graph.ContentHash()
+	}
+
+	c.JSON(http.StatusOK, GraphManifestResponse{
+		GraphID:      graphID,
+		ProjectRoot:  cached.ProjectRoot,
+		ContentHash:  contentHash,
+		BuiltAtMilli: cached.BuiltAtMilli,
+		CommitSHA:    cached.CommitSHA,
+		Languages:    graphLanguages(cached.Graph, files),
+		FileCount:    len(files),
+		Files:        files,
+	})
+}
+
+// graphLanguages returns the distinct languages of the symbols declared
+// in files, sorted. Placeholder and dependency symbols are not counted.
+func graphLanguages(g *graph.Graph, files map[string]string) []string {
+	seen := make(map[string]struct{})
+	languages := make([]string, 0)
+	for _, node := range g.Nodes() {
+		if node.Symbol == nil || node.Symbol.Language == "" {
+			continue
+		}
+		if _, inFiles := files[node.Symbol.FilePath]; !inFiles {
+			continue
+		}
+		if _, ok := seen[node.Symbol.Language]; !ok {
+			seen[node.Symbol.Language] = struct{}{}
+			languages = append(languages, node.Symbol.Language)
+		}
+	}
+	slices.Sort(languages)
+	return languages
+}
+
+// statusGraph resolves the graph named by the graph_id, or project_root
+// and git_ref, query parameters, defaulting to the first cached graph.
+// On failure it writes a 404 and returns false.
+func (h *Handlers) statusGraph(c *gin.Context, logger *slog.Logger) (string, *CachedGraph, bool) {
+	graphID := c.Query("graph_id")
+	if graphID == "" {
+		if projectRoot := c.Query("project_root"); projectRoot != "" {
+			graphID = h.svc.projectGraphID(projectRoot, c.Query("git_ref"))
+		}
+	}
+
+	if graphID != "" {
+		cached, err := h.svc.GetGraph(graphID)
+		if err != nil {
+			logger.Warn("Graph not found", "graph_id", graphID, telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusNotFound, apierror.CodeGraphNotFound, "graph not found")
+			return "", nil, false
+		}
+		return graphID, cached, true
+	}
+
+	cached := h.svc.getFirstGraph()
+	if cached == nil {
+		logger.Info("No graphs cached")
+		apierror.Write(c, http.StatusNotFound, apierror.CodeNoGraphs, "no graphs cached")
+		return "", nil, false
+	}
+	return h.svc.cachedGraphID(cached), cached, true
+}
+
 // HandleGetCacheStats handles GET /v1/trace/debug/cache.
 //
 // Description:
@@ -670,7 +1543,7 @@ func (h *Handlers) HandleGetGraphStats(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use. Read-only access.
 func (h *Handlers) HandleGetCacheStats(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleGetCacheStats")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGetCacheStats")
 
 	graphID := c.Query("graph_id")
 
@@ -680,31 +1553,22 @@ func (h *Handlers) HandleGetCacheStats(c *gin.Context) {
 	if graphID != "" {
 		cached, err = h.svc.GetGraph(graphID)
 		if err != nil {
-			logger.Warn("Graph not found", "graph_id", graphID, "error", err)
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "graph not found",
-				Code:  "GRAPH_NOT_FOUND",
-			})
+			logger.Warn("Graph not found", "graph_id", graphID, telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusNotFound, apierror.CodeGraphNotFound, "graph not found")
 			return
 		}
 	} else {
 		cached = h.svc.getFirstGraph()
 		if cached == nil {
 			logger.Info("No graphs cached")
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "no graphs cached",
-				Code:  "NO_GRAPHS",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeNoGraphs, "no graphs cached")
 			return
 		}
 	}
 
 	if cached.Adapter == nil {
 		logger.Warn("Graph has no adapter for cache stats")
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "cache not available for this graph",
-			Code:  "CACHE_NOT_AVAILABLE",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeCacheNotAvailable, "cache not available for this graph")
 		return
 	}
 
@@ -729,6 +1593,12 @@ func getOrCreateRequestID(c *gin.Context) string {
 	return requestID
 }
 
+// requestLogger returns the default logger bound to the request context,
+// so every record it emits carries the request's trace_id.
+func requestLogger(c *gin.Context) *slog.Logger {
+	return telemetry.LoggerFromContext(c.Request.Context())
+}
+
 // HandleListMemories handles GET /v1/trace/memories.
 //
 // Description:
@@ -749,24 +1619,18 @@ func getOrCreateRequestID(c *gin.Context) string {
 //	503 Service Unavailable: Memory system not configured
 func (h *Handlers) HandleListMemories(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleListMemories")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleListMemories")
 
 	if h.memoryStore == nil {
 		logger.Warn("Memory list requested but memory system not configured")
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "Memory system requires Weaviate and data space configuration",
-			Code:  "MEMORY_NOT_CONFIGURED",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeMemoryNotConfigured, "Memory system requires Weaviate and data space configuration")
 		return
 	}
 
 	var req memory.ListRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		logger.Warn("Invalid query parameters", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid query parameters",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid query parameters", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid query parameters")
 		return
 	}
 
@@ -779,11 +1643,8 @@ func (h *Handlers) HandleListMemories(c *gin.Context) {
 		req.MinConfidence,
 	)
 	if err != nil {
-		logger.Error("List memories failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "LIST_FAILED",
-		})
+		logger.Error("List memories failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -812,24 +1673,18 @@ func (h *Handlers) HandleListMemories(c *gin.Context) {
 //	503 Service Unavailable: Memory system not configured
 func (h *Handlers) HandleStoreMemory(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleStoreMemory")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleStoreMemory")
 
 	if h.memoryStore == nil {
 		logger.Warn("Memory store requested but memory system not configured")
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "Memory system requires Weaviate and data space configuration",
-			Code:  "MEMORY_NOT_CONFIGURED",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeMemoryNotConfigured, "Memory system requires Weaviate and data space configuration")
 		return
 	}
 
 	var req memory.StoreRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
@@ -860,18 +1715,12 @@ func (h *Handlers) HandleStoreMemory(c *gin.Context) {
 			errors.Is(err, memory.ErrInvalidMemoryType) ||
 			errors.Is(err, memory.ErrInvalidMemorySource) ||
 			errors.Is(err, memory.ErrInvalidConfidence) {
-			c.JSON(http.StatusBadRequest, ErrorResponse{
-				Error: err.Error(),
-				Code:  "VALIDATION_FAILED",
-			})
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
 			return
 		}
 
-		logger.Error("Store memory failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "STORE_FAILED",
-		})
+		logger.Error("Store memory failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -902,24 +1751,18 @@ func (h *Handlers) HandleStoreMemory(c *gin.Context) {
 //	503 Service Unavailable: Memory system not configured
 func (h *Handlers) HandleRetrieveMemories(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleRetrieveMemories")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleRetrieveMemories")
 
 	if h.memoryRetriever == nil {
 		logger.Warn("Memory retrieve requested but memory system not configured")
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "Memory system requires Weaviate and data space configuration",
-			Code:  "MEMORY_NOT_CONFIGURED",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeMemoryNotConfigured, "Memory system requires Weaviate and data space configuration")
 		return
 	}
 
 	var req memory.RetrieveRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logger.Warn("Invalid request body", "error", err)
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "Invalid request body",
-			Code:  "INVALID_REQUEST",
-		})
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
 		return
 	}
 
@@ -933,11 +1776,8 @@ func (h *Handlers) HandleRetrieveMemories(c *gin.Context) {
 
 	results, err := h.memoryRetriever.Retrieve(c.Request.Context(), opts)
 	if err != nil {
-		logger.Error("Retrieve memories failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "RETRIEVE_FAILED",
-		})
+		logger.Error("Retrieve memories failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -967,42 +1807,30 @@ func (h *Handlers) HandleRetrieveMemories(c *gin.Context) {
 //	503 Service Unavailable: Memory system not configured
 func (h *Handlers) HandleDeleteMemory(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleDeleteMemory")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDeleteMemory")
 
 	if h.memoryStore == nil {
 		logger.Warn("Memory delete requested but memory system not configured")
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "Memory system requires Weaviate and data space configuration",
-			Code:  "MEMORY_NOT_CONFIGURED",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeMemoryNotConfigured, "Memory system requires Weaviate and data space configuration")
 		return
 	}
 
 	memoryID := c.Param("id")
 	if memoryID == "" {
 		logger.Warn("Missing memory id")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "memory id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "memory id is required")
 		return
 	}
 
 	err := h.memoryStore.Delete(c.Request.Context(), memoryID)
 	if err != nil {
 		if errors.Is(err, memory.ErrMemoryNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: err.Error(),
-				Code:  "MEMORY_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeMemoryNotFound, err.Error())
 			return
 		}
 
-		logger.Error("Delete memory failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "DELETE_FAILED",
-		})
+		logger.Error("Delete memory failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -1028,53 +1856,38 @@ func (h *Handlers) HandleDeleteMemory(c *gin.Context) {
 //	503 Service Unavailable: Memory system not configured
 func (h *Handlers) HandleValidateMemory(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleValidateMemory")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleValidateMemory")
 
 	if h.lifecycleManager == nil {
 		logger.Warn("Memory validate requested but memory system not configured")
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "Memory system requires Weaviate and data space configuration",
-			Code:  "MEMORY_NOT_CONFIGURED",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeMemoryNotConfigured, "Memory system requires Weaviate and data space configuration")
 		return
 	}
 
 	memoryID := c.Param("id")
 	if memoryID == "" {
 		logger.Warn("Missing memory id")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "memory id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "memory id is required")
 		return
 	}
 
 	err := h.lifecycleManager.ValidateMemory(c.Request.Context(), memoryID)
 	if err != nil {
 		if errors.Is(err, memory.ErrMemoryNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: err.Error(),
-				Code:  "MEMORY_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeMemoryNotFound, err.Error())
 			return
 		}
 
-		logger.Error("Validate memory failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "VALIDATE_FAILED",
-		})
+		logger.Error("Validate memory failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
 	// Fetch updated memory
 	mem, err := h.memoryStore.Get(c.Request.Context(), memoryID)
 	if err != nil {
-		logger.Error("Get memory after validate failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "GET_FAILED",
-		})
+		logger.Error("Get memory after validate failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
@@ -1106,24 +1919,18 @@ func (h *Handlers) HandleValidateMemory(c *gin.Context) {
 //	503 Service Unavailable: Memory system not configured
 func (h *Handlers) HandleContradictMemory(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleContradictMemory")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleContradictMemory")
 
 	if h.lifecycleManager == nil {
 		logger.Warn("Memory contradict requested but memory system not configured")
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "Memory system requires Weaviate and data space configuration",
-			Code:  "MEMORY_NOT_CONFIGURED",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeMemoryNotConfigured, "Memory system requires Weaviate and data space configuration")
 		return
 	}
 
 	memoryID := c.Param("id")
 	if memoryID == "" {
 		logger.Warn("Missing memory id")
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "memory id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "memory id is required")
 		return
 	}
 
@@ -1138,18 +1945,12 @@ func (h *Handlers) HandleContradictMemory(c *gin.Context) {
 	err := h.lifecycleManager.ContradictMemory(c.Request.Context(), memoryID, req.Reason)
 	if err != nil {
 		if errors.Is(err, memory.ErrMemoryNotFound) {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: err.Error(),
-				Code:  "MEMORY_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeMemoryNotFound, err.Error())
 			return
 		}
 
-		logger.Error("Contradict memory failed", "error", err)
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: err.Error(),
-			Code:  "CONTRADICT_FAILED",
-		})
+		logger.Error("Contradict memory failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
 		return
 	}
 
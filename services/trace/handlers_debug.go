@@ -16,7 +16,9 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/gin-gonic/gin"
 )
 
@@ -44,14 +46,11 @@ import (
 // Thread Safety: This method is safe for concurrent use. Read-only access to graph.
 func (h *Handlers) HandleInspectNode(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleInspectNode")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleInspectNode")
 
 	name := c.Query("name")
 	if name == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "name parameter is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "name parameter is required")
 		return
 	}
 
@@ -108,11 +107,12 @@ func (h *Handlers) HandleInspectNode(c *gin.Context) {
 				continue
 			}
 			match.Outgoing = append(match.Outgoing, InspectEdge{
-				PeerID:   edge.ToID,
-				PeerName: peerNode.Symbol.Name,
-				PeerKind: peerNode.Symbol.Kind.String(),
-				EdgeType: edge.Type.String(),
-				Location: &edge.Location,
+				PeerID:     edge.ToID,
+				PeerName:   peerNode.Symbol.Name,
+				PeerKind:   peerNode.Symbol.Kind.String(),
+				EdgeType:   edge.Type.String(),
+				Location:   &edge.Location,
+				Provenance: edgeProvenance(edge),
 			})
 		}
 
@@ -127,11 +127,12 @@ func (h *Handlers) HandleInspectNode(c *gin.Context) {
 				continue
 			}
 			match.Incoming = append(match.Incoming, InspectEdge{
-				PeerID:   edge.FromID,
-				PeerName: peerNode.Symbol.Name,
-				PeerKind: peerNode.Symbol.Kind.String(),
-				EdgeType: edge.Type.String(),
-				Location: &edge.Location,
+				PeerID:     edge.FromID,
+				PeerName:   peerNode.Symbol.Name,
+				PeerKind:   peerNode.Symbol.Kind.String(),
+				EdgeType:   edge.Type.String(),
+				Location:   &edge.Location,
+				Provenance: edgeProvenance(edge),
 			})
 		}
 
@@ -140,7 +141,7 @@ func (h *Handlers) HandleInspectNode(c *gin.Context) {
 
 	logger.Info("inspect node",
 		slog.String("graph_id", graphID),
-		slog.String("name", name),
+		telemetry.SensitiveLog("name", name),
 		slog.Int("matches", len(response.Matches)),
 	)
 
@@ -167,7 +168,7 @@ func (h *Handlers) HandleInspectNode(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use. Read-only access to graph.
 func (h *Handlers) HandleExportGraph(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleExportGraph")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleExportGraph")
 
 	cached, graphID, err := h.resolveGraph(c)
 	if err != nil {
@@ -189,7 +190,7 @@ func (h *Handlers) HandleExportGraph(c *gin.Context) {
 	encoder := json.NewEncoder(c.Writer)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(sg); err != nil {
-		logger.Error("failed to encode graph", slog.Any("error", err))
+		logger.Error("failed to encode graph", telemetry.SensitiveLogError("error", err))
 		// Can't write error response since we already started writing the body
 	}
 }
@@ -214,13 +215,10 @@ func (h *Handlers) HandleExportGraph(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use.
 func (h *Handlers) HandleSaveSnapshot(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleSaveSnapshot")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSaveSnapshot")
 
 	if h.svc.snapshotMgr == nil {
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "snapshot persistence not configured",
-			Code:  "SNAPSHOTS_NOT_AVAILABLE",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeSnapshotsNotAvailable, "snapshot persistence not configured")
 		return
 	}
 
@@ -237,30 +235,21 @@ func (h *Handlers) HandleSaveSnapshot(c *gin.Context) {
 	if req.GraphID != "" {
 		cached, resolveErr = h.svc.GetGraph(req.GraphID)
 		if resolveErr != nil {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "graph not found",
-				Code:  "GRAPH_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeGraphNotFound, "graph not found")
 			return
 		}
 	} else {
 		cached = h.svc.getFirstGraph()
 		if cached == nil {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "no graphs cached",
-				Code:  "NO_GRAPHS",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeNoGraphs, "no graphs cached")
 			return
 		}
 	}
 
 	meta, err := h.svc.snapshotMgr.Save(c.Request.Context(), cached.Graph, req.Label)
 	if err != nil {
-		logger.Error("snapshot save failed", slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to save snapshot: " + err.Error(),
-			Code:  "SNAPSHOT_SAVE_FAILED",
-		})
+		logger.Error("snapshot save failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "failed to save snapshot: "+err.Error())
 		return
 	}
 
@@ -297,13 +286,10 @@ func (h *Handlers) HandleSaveSnapshot(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use.
 func (h *Handlers) HandleListSnapshots(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleListSnapshots")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleListSnapshots")
 
 	if h.svc.snapshotMgr == nil {
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "snapshot persistence not configured",
-			Code:  "SNAPSHOTS_NOT_AVAILABLE",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeSnapshotsNotAvailable, "snapshot persistence not configured")
 		return
 	}
 
@@ -323,11 +309,8 @@ func (h *Handlers) HandleListSnapshots(c *gin.Context) {
 
 	snapshots, err := h.svc.snapshotMgr.List(c.Request.Context(), projectHash, limit)
 	if err != nil {
-		logger.Error("failed to list snapshots", slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "failed to list snapshots: " + err.Error(),
-			Code:  "SNAPSHOT_LIST_FAILED",
-		})
+		logger.Error("failed to list snapshots", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "failed to list snapshots: "+err.Error())
 		return
 	}
 
@@ -359,32 +342,23 @@ func (h *Handlers) HandleListSnapshots(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use.
 func (h *Handlers) HandleLoadSnapshot(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleLoadSnapshot")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleLoadSnapshot")
 
 	if h.svc.snapshotMgr == nil {
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "snapshot persistence not configured",
-			Code:  "SNAPSHOTS_NOT_AVAILABLE",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeSnapshotsNotAvailable, "snapshot persistence not configured")
 		return
 	}
 
 	snapshotID := c.Param("id")
 	if snapshotID == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "snapshot id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "snapshot id is required")
 		return
 	}
 
 	g, meta, err := h.svc.snapshotMgr.Load(c.Request.Context(), snapshotID)
 	if err != nil {
-		logger.Warn("snapshot not found", slog.String("snapshot_id", snapshotID), slog.Any("error", err))
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error: "snapshot not found: " + err.Error(),
-			Code:  "SNAPSHOT_NOT_FOUND",
-		})
+		logger.Warn("snapshot not found", slog.String("snapshot_id", snapshotID), telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusNotFound, apierror.CodeSnapshotNotFound, "snapshot not found: "+err.Error())
 		return
 	}
 
@@ -420,31 +394,22 @@ func (h *Handlers) HandleLoadSnapshot(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use.
 func (h *Handlers) HandleDeleteSnapshot(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleDeleteSnapshot")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDeleteSnapshot")
 
 	if h.svc.snapshotMgr == nil {
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "snapshot persistence not configured",
-			Code:  "SNAPSHOTS_NOT_AVAILABLE",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeSnapshotsNotAvailable, "snapshot persistence not configured")
 		return
 	}
 
 	snapshotID := c.Param("id")
 	if snapshotID == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "snapshot id is required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "snapshot id is required")
 		return
 	}
 
 	if err := h.svc.snapshotMgr.Delete(c.Request.Context(), snapshotID); err != nil {
-		logger.Warn("snapshot delete failed", slog.String("snapshot_id", snapshotID), slog.Any("error", err))
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error: "snapshot not found or delete failed: " + err.Error(),
-			Code:  "SNAPSHOT_NOT_FOUND",
-		})
+		logger.Warn("snapshot delete failed", slog.String("snapshot_id", snapshotID), telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusNotFound, apierror.CodeSnapshotNotFound, "snapshot not found or delete failed: "+err.Error())
 		return
 	}
 
@@ -474,13 +439,10 @@ func (h *Handlers) HandleDeleteSnapshot(c *gin.Context) {
 // Thread Safety: This method is safe for concurrent use.
 func (h *Handlers) HandleDiffSnapshots(c *gin.Context) {
 	requestID := getOrCreateRequestID(c)
-	logger := slog.With("request_id", requestID, "handler", "HandleDiffSnapshots")
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleDiffSnapshots")
 
 	if h.svc.snapshotMgr == nil {
-		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
-			Error: "snapshot persistence not configured",
-			Code:  "SNAPSHOTS_NOT_AVAILABLE",
-		})
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeSnapshotsNotAvailable, "snapshot persistence not configured")
 		return
 	}
 
@@ -488,45 +450,33 @@ func (h *Handlers) HandleDiffSnapshots(c *gin.Context) {
 	targetID := c.Query("target")
 
 	if baseID == "" || targetID == "" {
-		c.JSON(http.StatusBadRequest, ErrorResponse{
-			Error: "both 'base' and 'target' parameters are required",
-			Code:  "MISSING_PARAMETER",
-		})
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeMissingParameter, "both 'base' and 'target' parameters are required")
 		return
 	}
 
 	// Load both snapshots
 	baseGraph, _, err := h.svc.snapshotMgr.Load(c.Request.Context(), baseID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error: "base snapshot not found: " + err.Error(),
-			Code:  "SNAPSHOT_NOT_FOUND",
-		})
+		apierror.Write(c, http.StatusNotFound, apierror.CodeSnapshotNotFound, "base snapshot not found: "+err.Error())
 		return
 	}
 
 	targetGraph, _, err := h.svc.snapshotMgr.Load(c.Request.Context(), targetID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error: "target snapshot not found: " + err.Error(),
-			Code:  "SNAPSHOT_NOT_FOUND",
-		})
+		apierror.Write(c, http.StatusNotFound, apierror.CodeSnapshotNotFound, "target snapshot not found: "+err.Error())
 		return
 	}
 
 	diff, err := graph.DiffSnapshots(baseGraph, targetGraph, baseID, targetID)
 	if err != nil {
-		logger.Error("diff failed", slog.Any("error", err))
-		c.JSON(http.StatusInternalServerError, ErrorResponse{
-			Error: "diff computation failed: " + err.Error(),
-			Code:  "DIFF_FAILED",
-		})
+		logger.Error("diff failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "diff computation failed: "+err.Error())
 		return
 	}
 
 	logger.Info("snapshot diff computed",
 		slog.String("base", baseID),
-		slog.String("target", targetID),
+		telemetry.SensitiveLog("target", targetID),
 		slog.Int("total_changes", diff.Summary.TotalChanges),
 	)
 
@@ -551,10 +501,7 @@ func (h *Handlers) resolveGraph(c *gin.Context) (*CachedGraph, string, error) {
 	if graphID != "" {
 		cached, err := h.svc.GetGraph(graphID)
 		if err != nil {
-			c.JSON(http.StatusNotFound, ErrorResponse{
-				Error: "graph not found",
-				Code:  "GRAPH_NOT_FOUND",
-			})
+			apierror.Write(c, http.StatusNotFound, apierror.CodeGraphNotFound, "graph not found")
 			return nil, "", err
 		}
 		return cached, graphID, nil
@@ -562,13 +509,10 @@ func (h *Handlers) resolveGraph(c *gin.Context) (*CachedGraph, string, error) {
 
 	cached := h.svc.getFirstGraph()
 	if cached == nil {
-		c.JSON(http.StatusNotFound, ErrorResponse{
-			Error: "no graphs cached",
-			Code:  "NO_GRAPHS",
-		})
+		apierror.Write(c, http.StatusNotFound, apierror.CodeNoGraphs, "no graphs cached")
 		return nil, "", ErrGraphNotInitialized
 	}
 
-	graphID = h.svc.generateGraphID(cached.ProjectRoot)
+	graphID = h.svc.cachedGraphID(cached)
 	return cached, graphID, nil
 }
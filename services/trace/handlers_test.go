@@ -12,11 +12,27 @@ package trace
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
-
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/docenrich"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/semindex"
 	"github.com/gin-gonic/gin"
 )
 
@@ -36,10 +52,20 @@ func setupTestRouter(svc *Service) *gin.Engine {
 func TestHandlers_HandleHealth(t *testing.T) {
 	svc := NewService(DefaultServiceConfig())
 	router := setupTestRouter(svc)
+	defer ResetWarmupStatus()
 
+	// /health is an alias of /health/ready: unavailable until warmup.
 	req, _ := http.NewRequest("GET", "/v1/trace/health", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("before warmup: expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+
+	MarkWarmupComplete()
+	req, _ = http.NewRequest("GET", "/v1/trace/health", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
 
 	if w.Code != http.StatusOK {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
@@ -117,6 +143,90 @@ func TestHandlers_HandleInit_InvalidRequest(t *testing.T) {
 			wantStatus: http.StatusBadRequest,
 			wantCode:   "PATH_TRAVERSAL",
 		},
+		{
+			name:       "project root and roots",
+			body:       `{"project_root": "/a", "roots": ["/b", "/c"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_REQUEST",
+		},
+		{
+			name:       "relative workspace file",
+			body:       `{"workspace_file": "workspace.yaml"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PATH",
+		},
+		{
+			name:       "roots without common parent",
+			body:       `{"roots": ["/"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_WORKSPACE",
+		},
+		{
+			name:       "unknown goos",
+			body:       `{"project_root": "/a", "goos": "beos"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "unknown goarch",
+			body:       `{"project_root": "/a", "goarch": "z80"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "git ref with roots",
+			body:       `{"roots": ["/a/b", "/a/c"], "git_ref": "main"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "resolve dependencies with roots",
+			body:       `{"roots": ["/a/b", "/a/c"], "resolve_dependencies": true}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "dependency depth too deep",
+			body:       `{"project_root": "/a", "resolve_dependencies": true, "dependency_depth": 9}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "unknown disabled pass",
+			body:       `{"project_root": "/a", "disabled_passes": ["call-name", "guesswork"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "unknown placeholder policy",
+			body:       `{"project_root": "/a", "placeholder_policy": "none"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "unknown language",
+			body:       `{"project_root": "/a", "languages": ["go", "cobol"]}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "misspelled parser option",
+			body:       `{"project_root": "/a", "parser_options": {"go": {"extract_body": true}}}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "parser options for an unselected language",
+			body:       `{"project_root": "/a", "languages": ["go"], "parser_options": {"typescript": {"max_file_size": 1024}}}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
+		{
+			name:       "parser option of the wrong type",
+			body:       `{"project_root": "/a", "parser_options": {"go": {"max_file_size": "big"}}}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   "INVALID_PARAMETER",
+		},
 	}
 
 	for _, tt := range tests {
@@ -143,6 +253,107 @@ func TestHandlers_HandleInit_InvalidRequest(t *testing.T) {
 	}
 }
 
+func TestHandlers_HandleInit_ParserOptions(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"main.go": "package main\n\nfunc Exported() {}\n\nfunc private() {}\n",
+		"web.ts":  "export function render(): void {}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+
+	init := func(body string) InitResponse {
+		t.Helper()
+		w := postJSON(router, "/v1/trace/init", body)
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+		}
+		var resp InitResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := init(`{"project_root": "` + root + `", "languages": ["go", "typescript"]}`)
+	if resp.ByLanguage["go"].Files != 1 || resp.ByLanguage["typescript"].Files != 1 {
+		t.Errorf("by_language = %+v, want one go and one typescript file", resp.ByLanguage)
+	}
+	defaultGoSymbols := resp.ByLanguage["go"].Symbols
+
+	// TypeScript left out; Go parsed without private symbols.
+	resp = init(`{"project_root": "` + root + `", "languages": ["go"], "parser_options": {"go": {"include_private": false}}}`)
+	if _, ok := resp.ByLanguage["typescript"]; ok || resp.ByLanguage["go"].Files != 1 {
+		t.Errorf("by_language = %+v, want go only", resp.ByLanguage)
+	}
+	if got := resp.ByLanguage["go"].Symbols; got != defaultGoSymbols-1 {
+		t.Errorf("go symbols with include_private=false = %d, want %d", got, defaultGoSymbols-1)
+	}
+
+	w := postJSON(router, "/v1/trace/init", `{"project_root": "`+root+`", "parser_options": {"go": {"extract_body": true}}}`)
+	if !strings.Contains(w.Body.String(), `did you mean \"extract_bodies\"`) {
+		t.Errorf("typo error does not suggest extract_bodies: %s", w.Body.String())
+	}
+}
+
+func TestHandlers_HandleInit_AllowedRoots(t *testing.T) {
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowed := filepath.Join(base, "allowed")
+	outside := filepath.Join(base, "outside")
+	writeTestGoFiles(t, filepath.Join(allowed, "proj"))
+	writeTestGoFiles(t, outside)
+	if err := os.Symlink(outside, filepath.Join(allowed, "escape")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	config := DefaultServiceConfig()
+	config.AllowedRoots = []string{allowed}
+	router := setupTestRouter(NewService(config))
+
+	tests := []struct {
+		name       string
+		root       string
+		wantStatus int
+		wantCode   string
+	}{
+		{"outside", outside, http.StatusForbidden, "ROOT_NOT_ALLOWED"},
+		{"sibling sharing a prefix", allowed + "-other", http.StatusForbidden, "ROOT_NOT_ALLOWED"},
+		{"symlink escape", filepath.Join(allowed, "escape"), http.StatusForbidden, "ROOT_NOT_ALLOWED"},
+		{"dot-dot traversal", allowed + "/../outside", http.StatusBadRequest, "PATH_TRAVERSAL"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(map[string]string{"project_root": tt.root})
+			w := postJSON(router, "/v1/trace/init", string(body))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			var errResp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+				t.Fatal(err)
+			}
+			if errResp.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", errResp.Code, tt.wantCode)
+			}
+		})
+	}
+
+	body, _ := json.Marshal(map[string]string{"project_root": filepath.Join(allowed, "proj")})
+	if w := postJSON(router, "/v1/trace/init", string(body)); w.Code != http.StatusOK {
+		t.Errorf("allowed root: status = %d: %s", w.Code, w.Body.String())
+	}
+}
+
 func TestHandlers_HandleContext_GraphNotInitialized(t *testing.T) {
 	svc := NewService(DefaultServiceConfig())
 	router := setupTestRouter(svc)
@@ -505,3 +716,476 @@ func TestHandlers_HandleIndexingStatus_WithCoordinator(t *testing.T) {
 		}
 	})
 }
+
+// summaryChat is a providers.ChatClient that returns the same summary for
+// every request.
+type summaryChat struct{}
+
+func (summaryChat) Chat(context.Context, []providers.Message, providers.ChatOptions) (string, error) {
+	return "Starts the server.", nil
+}
+
+func TestHandlers_HandleEnrichmentStatus(t *testing.T) {
+	getStatus := func(t *testing.T, router *gin.Engine) docenrich.Status {
+		t.Helper()
+		req, _ := http.NewRequest("GET", "/v1/trace/enrichment/status", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		var st docenrich.Status
+		if err := json.Unmarshal(w.Body.Bytes(), &st); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return st
+	}
+
+	t.Run("disabled without a pipeline", func(t *testing.T) {
+		if st := getStatus(t, setupTestRouter(NewService(DefaultServiceConfig()))); st.Enabled {
+			t.Errorf("expected enabled=false, got %+v", st)
+		}
+	})
+
+	t.Run("reports runs started by init", func(t *testing.T) {
+		root := t.TempDir()
+		src := "package app\n\nfunc Serve() {}\n\n// Stop stops.\nfunc Stop() {}\n"
+		if err := os.WriteFile(filepath.Join(root, "app.go"), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		pipeline, err := docenrich.New(summaryChat{}, nil, docenrich.Config{Provider: providers.ProviderOllama, RatePerMinute: 60_000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc := NewService(DefaultServiceConfig())
+		svc.SetDocEnricher(pipeline)
+		defer svc.Close(context.Background())
+		router := setupTestRouter(svc)
+
+		resp, err := svc.Init(context.Background(), root, []string{"go"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var st docenrich.Status
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if st = getStatus(t, router); len(st.Graphs) == 1 && st.Graphs[0].State == docenrich.StateComplete {
+				break
+			}
+		}
+		if !st.Enabled || len(st.Graphs) != 1 || st.Graphs[0].GraphID != resp.GraphID ||
+			st.Graphs[0].State != docenrich.StateComplete || st.Completed != 1 || st.QueueDepth != 0 {
+			t.Fatalf("unexpected status %+v", st)
+		}
+
+		cached, err := svc.GetGraph(resp.GraphID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		serve, stop := cached.Graph.GetNodesByName("Serve"), cached.Graph.GetNodesByName("Stop")
+		if len(serve) != 1 || len(stop) != 1 {
+			t.Fatalf("expected one Serve and one Stop node, got %d and %d", len(serve), len(stop))
+		}
+		if doc := serve[0].SyntheticDoc(); doc != "Starts the server." {
+			t.Errorf("Serve synthetic doc = %q", doc)
+		}
+		if doc := stop[0].SyntheticDoc(); doc != "" {
+			t.Errorf("documented Stop got synthetic doc %q", doc)
+		}
+	})
+}
+
+// keywordEmbedder is a semindex.Embedder that embeds a text as the
+// counts of "serve" and "stop" in it.
+type keywordEmbedder struct{}
+
+func (keywordEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		lower := strings.ToLower(text)
+		out[i] = []float32{float32(strings.Count(lower, "serve")), float32(strings.Count(lower, "stop")), 0.1}
+	}
+	return out, nil
+}
+
+func TestHandlers_HandleSemanticSearch(t *testing.T) {
+	root := t.TempDir()
+	src := "package app\n\n// Serve starts the server.\nfunc Serve() {}\n\n// Stop stops the server.\nfunc Stop() {}\n"
+	if err := os.WriteFile(filepath.Join(root, "app.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	search := func(t *testing.T, router *gin.Engine, query string) (int, SemanticSearchResponse) {
+		t.Helper()
+		req, _ := http.NewRequest("GET", "/v1/trace/search/semantic?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp SemanticSearchResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+		}
+		return w.Code, resp
+	}
+
+	t.Run("lexical without an index", func(t *testing.T) {
+		svc := NewService(DefaultServiceConfig())
+		router := setupTestRouter(svc)
+		initResp, err := svc.Init(context.Background(), root, []string{"go"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		code, resp := search(t, router, "graph_id="+initResp.GraphID+"&query=stop+the+server")
+		if code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, code)
+		}
+		if resp.Mode != semindex.ModeLexical || resp.FallbackReason == "" || resp.Coverage != nil {
+			t.Errorf("unexpected response %+v", resp)
+		}
+		if len(resp.Results) == 0 || resp.Results[0].Symbol.Name != "Stop" {
+			t.Errorf("expected Stop first, got %+v", resp.Results)
+		}
+
+		if code, _ := search(t, router, "graph_id="+initResp.GraphID); code != http.StatusBadRequest {
+			t.Errorf("missing query: expected status %d, got %d", http.StatusBadRequest, code)
+		}
+		if code, _ := search(t, router, "graph_id=nope&query=stop"); code != http.StatusBadRequest {
+			t.Errorf("unknown graph: expected status %d, got %d", http.StatusBadRequest, code)
+		}
+	})
+
+	t.Run("semantic once indexed", func(t *testing.T) {
+		ix, err := semindex.New(keywordEmbedder{}, nil, semindex.Config{Model: "test-embed", RatePerMinute: 60_000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc := NewService(DefaultServiceConfig())
+		svc.SetSemanticIndex(ix)
+		defer svc.Close(context.Background())
+		router := setupTestRouter(svc)
+		initResp, err := svc.Init(context.Background(), root, []string{"go"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+			if gs := svc.SemanticIndexCoverage(initResp.GraphID); gs != nil && gs.State == semindex.StateComplete {
+				break
+			}
+		}
+
+		code, resp := search(t, router, "graph_id="+initResp.GraphID+"&query=serve&limit=1")
+		if code != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, code)
+		}
+		if resp.Mode != semindex.ModeSemantic || resp.Coverage == nil || resp.Coverage.Indexed != 2 {
+			t.Fatalf("unexpected response %+v", resp)
+		}
+		if len(resp.Results) != 1 || resp.Results[0].Symbol.Name != "Serve" {
+			t.Errorf("expected Serve only, got %+v", resp.Results)
+		}
+
+		req, _ := http.NewRequest("GET", "/v1/trace/search/semantic/status", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var st semindex.Status
+		if err := json.Unmarshal(w.Body.Bytes(), &st); err != nil {
+			t.Fatalf("failed to unmarshal status: %v", err)
+		}
+		if !st.Enabled || st.Model != "test-embed" || len(st.Graphs) != 1 {
+			t.Errorf("unexpected status %+v", st)
+		}
+	})
+}
+
+func TestHandlers_HandleSymbolSource(t *testing.T) {
+	root := t.TempDir()
+	content := "package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	sym := &ast.Symbol{ID: "main.go:3:main", Name: "main", Kind: ast.SymbolKindFunction,
+		FilePath: "main.go", StartLine: 3, EndLine: 5, Language: "go"}
+	gone := &ast.Symbol{ID: "gone.go:1:gone", Name: "gone", Kind: ast.SymbolKindFunction,
+		FilePath: "gone.go", StartLine: 1, EndLine: 1, Language: "go"}
+	g := graph.NewGraph(root)
+	g.AddNode(sym)
+	g.AddNode(gone)
+	g.Freeze()
+	idx := index.NewSymbolIndex()
+	_ = idx.Add(sym)
+	_ = idx.Add(gone)
+	graphID := svc.generateGraphID(root)
+	svc.graphs[graphID] = &CachedGraph{Graph: g, Index: idx, BuiltAtMilli: g.BuiltAtMilli, ProjectRoot: root}
+	router := setupTestRouter(svc)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/v1/trace/symbol/" + url.PathEscape(sym.ID) + "/source?graph_id=" + graphID + "&context=1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var src graph.SymbolSource
+	if err := json.Unmarshal(w.Body.Bytes(), &src); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if src.FromLine != 2 || src.ToLine != 5 || !strings.Contains(src.Source, "println") {
+		t.Errorf("unexpected source %d-%d: %q", src.FromLine, src.ToLine, src.Source)
+	}
+	if src.FileHash == "" {
+		t.Error("expected file hash")
+	}
+
+	cases := []struct {
+		path string
+		code int
+	}{
+		{"/v1/trace/symbol/missing/source?graph_id=" + graphID, http.StatusNotFound},
+		{"/v1/trace/symbol/" + url.PathEscape(gone.ID) + "/source?graph_id=" + graphID, http.StatusNotFound},
+		{"/v1/trace/symbol/missing/source?graph_id=" + graphID + "&context=-1", http.StatusBadRequest},
+		{"/v1/trace/symbol/missing/source", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		if w := get(tc.path); w.Code != tc.code {
+			t.Errorf("%s: expected status %d, got %d", tc.path, tc.code, w.Code)
+		}
+	}
+}
+
+func TestHandlers_HandleGraphStatus(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/v1/trace/graph/status"); w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d with no graphs, got %d", http.StatusNotFound, w.Code)
+	}
+	if w := get("/v1/trace/graph/status?graph_id=nonexistent"); w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for unknown graph, got %d", http.StatusNotFound, w.Code)
+	}
+
+	root := t.TempDir()
+	g := graph.NewGraph(root)
+	g.AddNode(&ast.Symbol{ID: "main.go:3:main", Name: "main", Kind: ast.SymbolKindFunction,
+		FilePath: "main.go", StartLine: 3, EndLine: 5, Language: "go"})
+	g.Freeze()
+	graphID := svc.generateGraphID(root)
+	svc.graphs[graphID] = &CachedGraph{Graph: g, BuiltAtMilli: g.BuiltAtMilli, ProjectRoot: root}
+
+	w := get("/v1/trace/graph/status?project_root=" + url.QueryEscape(root))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp GraphStatusResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if resp.GraphID != graphID || resp.NodeCount != 1 || resp.State != "readonly" {
+		t.Errorf("unexpected status: %+v", resp)
+	}
+	if resp.ContentHash != g.ContentHash() {
+		t.Errorf("content_hash = %q, want %q", resp.ContentHash, g.ContentHash())
+	}
+}
+
+func TestHandlers_HandleGraphManifest(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/v1/trace/graph/manifest?graph_id=nonexistent"); w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for unknown graph, got %d", http.StatusNotFound, w.Code)
+	}
+
+	// A graph built without hashes has no manifest.
+	bare := t.TempDir()
+	g := graph.NewGraph(bare)
+	g.Freeze()
+	svc.graphs[svc.generateGraphID(bare)] = &CachedGraph{Graph: g, BuiltAtMilli: g.BuiltAtMilli, ProjectRoot: bare}
+	if w := get("/v1/trace/graph/manifest?project_root=" + url.QueryEscape(bare)); w.Code != http.StatusConflict {
+		t.Errorf("expected status %d without file hashes, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+
+	root := writeMonorepo(t)
+	resp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	w := get("/v1/trace/graph/manifest?graph_id=" + resp.GraphID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var manifest GraphManifestResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &manifest); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if manifest.GraphID != resp.GraphID || manifest.ContentHash == "" || manifest.FileCount != len(manifest.Files) || manifest.FileCount == 0 {
+		t.Fatalf("unexpected manifest: %s", w.Body.String())
+	}
+	if !slices.Equal(manifest.Languages, []string{"go"}) {
+		t.Errorf("languages = %v, want [go]", manifest.Languages)
+	}
+	for rel, hash := range manifest.Files {
+		content, err := os.ReadFile(filepath.Join(root, rel))
+		if err != nil {
+			t.Fatalf("manifest lists %s: %v", rel, err)
+		}
+		if sum := sha256.Sum256(content); hex.EncodeToString(sum[:]) != hash {
+			t.Errorf("hash of %s = %s, want the SHA-256 of its content", rel, hash)
+		}
+	}
+}
+
+func TestHandlers_HandleGraphReport(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	get := func(path string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/v1/trace/graph/report?graph_id=nonexistent"); w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for unknown graph, got %d", http.StatusNotFound, w.Code)
+	}
+
+	resp, err := svc.Init(context.Background(), writeMonorepo(t), nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	w := get("/v1/trace/graph/report?graph_id=" + resp.GraphID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var report GraphReportResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if report.GraphID != resp.GraphID || report.QualityReport == nil || report.Level == "" {
+		t.Fatalf("unexpected report: %s", w.Body.String())
+	}
+	if goLang := report.Languages["go"]; goLang == nil || goLang.Files == 0 || goLang.SymbolsByKind["function"] == 0 {
+		t.Errorf("go stats = %+v", goLang)
+	}
+	if report.Calls.Total == 0 || report.Confidence <= 0 || report.Confidence > 1 {
+		t.Errorf("calls = %+v, confidence = %v", report.Calls, report.Confidence)
+	}
+
+	// Graphs without a report get one computed on request.
+	cached, _ := svc.GetGraph(resp.GraphID)
+	cached.Graph.QualityReport = nil
+	if w := get("/v1/trace/graph/report?graph_id=" + resp.GraphID); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"confidence"`) {
+		t.Errorf("report without a stored one: %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandlers_HandleListErrors(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	req, _ := http.NewRequest("GET", apierror.CatalogPath, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp ErrorCatalogResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(resp.Errors) != len(apierror.Catalog()) {
+		t.Errorf("expected %d codes, got %d", len(apierror.Catalog()), len(resp.Errors))
+	}
+	found := false
+	for _, info := range resp.Errors {
+		if info.Code == apierror.CodeGraphNotInitialized {
+			found = info.Status == http.StatusBadRequest && info.Title != ""
+		}
+	}
+	if !found {
+		t.Error("expected GRAPH_NOT_INITIALIZED with status and title in catalog")
+	}
+}
+
+// TestHandlers_ProblemResponse verifies that handler errors are RFC 7807
+// problems that still carry the legacy "error" member.
+func TestHandlers_ProblemResponse(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	req, _ := http.NewRequest("GET", "/v1/trace/debug/graph/stats?graph_id=nonexistent", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != apierror.ContentType {
+		t.Errorf("expected Content-Type %q, got %q", apierror.ContentType, ct)
+	}
+	var p apierror.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &p); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if p.Status != w.Code || p.Code != apierror.CodeGraphNotFound {
+		t.Errorf("unexpected problem status/code: %d %s", p.Status, p.Code)
+	}
+	if p.Type != apierror.CatalogPath+"#GRAPH_NOT_FOUND" || p.Title != "Graph not found" {
+		t.Errorf("unexpected type/title: %q %q", p.Type, p.Title)
+	}
+	if p.Instance != "/v1/trace/debug/graph/stats" {
+		t.Errorf("unexpected instance %q", p.Instance)
+	}
+	if p.Error == "" || p.Error != p.Detail {
+		t.Errorf("expected legacy error to mirror detail, got error=%q detail=%q", p.Error, p.Detail)
+	}
+}
+
+func TestHandlers_HandleSymbol_StableID(t *testing.T) {
+	_, svc, router, graphID := setupHTTPCacheTest(t, DefaultServiceConfig())
+	nodeID := symbolIDByName(t, svc, graphID, "helper")
+	cached, err := svc.GetGraph(graphID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stableID, ok := cached.Graph.StableIDFor(nodeID)
+	if !ok {
+		t.Fatalf("no stable ID for %s", nodeID)
+	}
+
+	// Node ID, stable ID, and a line-based ID from before the symbol moved.
+	movedID := strings.Replace(nodeID, ":3:", ":1:", 1)
+	for _, id := range []string{nodeID, stableID, movedID} {
+		req, _ := http.NewRequest("GET", "/v1/trace/symbol/"+url.PathEscape(id)+"?graph_id="+graphID, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d, got %d: %s", id, http.StatusOK, w.Code, w.Body.String())
+		}
+		var resp SymbolResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if resp.Symbol.ID != nodeID || resp.Symbol.StableID != stableID {
+			t.Errorf("%s: got id %q stable_id %q", id, resp.Symbol.ID, resp.Symbol.StableID)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Subsystem health statuses reported by the readiness endpoint.
+const (
+	// SubsystemOK means the last check passed.
+	SubsystemOK = "ok"
+
+	// SubsystemFailing means the last check failed.
+	SubsystemFailing = "failing"
+
+	// SubsystemDisabled means the subsystem is not configured.
+	SubsystemDisabled = "disabled"
+)
+
+// Subsystem names reported by the readiness endpoint.
+const (
+	SubsystemMainProvider   = "main_provider"
+	SubsystemRouterProvider = "router_provider"
+	SubsystemRoutingCache   = "routing_cache"
+	SubsystemPolicyEngine   = "policy_engine"
+	SubsystemGraphStore     = "graph_store"
+	SubsystemSessionStore   = "session_store"
+)
+
+// DefaultHealthCheckTTL is how long a subsystem check result is reused.
+// Probes run every few seconds; provider checks go over the network.
+const DefaultHealthCheckTTL = 30 * time.Second
+
+// healthCheckTimeout bounds a single subsystem check.
+const healthCheckTimeout = 5 * time.Second
+
+// HealthCheckFunc checks one subsystem. A nil error means healthy.
+type HealthCheckFunc func(ctx context.Context) error
+
+// HealthCheck describes a subsystem for the readiness endpoint.
+type HealthCheck struct {
+	// Name identifies the subsystem, e.g. SubsystemMainProvider.
+	Name string
+
+	// Required subsystems must pass for the service to be ready. Failing
+	// optional subsystems only make it degraded.
+	Required bool
+
+	// Detail is shown as-is, e.g. "ollama/glm-4.7-flash".
+	Detail string
+
+	// Check runs the check. Nil reports the subsystem as disabled.
+	Check HealthCheckFunc
+}
+
+// SubsystemHealth is the last known state of a subsystem.
+type SubsystemHealth struct {
+	// Name identifies the subsystem.
+	Name string `json:"name"`
+
+	// Status is ok, failing or disabled.
+	Status string `json:"status"`
+
+	// Required is true if the subsystem gates readiness.
+	Required bool `json:"required"`
+
+	// Detail describes the subsystem's configuration.
+	Detail string `json:"detail,omitempty"`
+
+	// LastError is the most recent check error. Kept after the subsystem
+	// recovers, together with LastErrorAt, to show flapping.
+	LastError string `json:"last_error,omitempty"`
+
+	// LastErrorAt is when LastError occurred.
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+
+	// LastCheckedAt is when the subsystem was last checked.
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+}
+
+// registeredCheck is a HealthCheck with its cached result.
+type registeredCheck struct {
+	HealthCheck
+	mu        sync.Mutex
+	result    SubsystemHealth
+	checkedAt time.Time
+}
+
+// HealthRegistry runs subsystem checks for the readiness endpoint and
+// caches their results.
+//
+// Description:
+//
+//	Each check's result is reused for the registry's TTL, so frequent
+//	probes don't hit providers on every call. Checks that are due run
+//	concurrently, each with a timeout; concurrent callers wait for a
+//	running check instead of starting another.
+//
+// Thread Safety: Safe for concurrent use.
+type HealthRegistry struct {
+	mu     sync.RWMutex
+	checks []*registeredCheck
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewHealthRegistry creates an empty registry.
+//
+// Inputs:
+//
+//	ttl - How long results are reused. Zero uses DefaultHealthCheckTTL.
+//
+// Outputs:
+//
+//	*HealthRegistry - The registry.
+func NewHealthRegistry(ttl time.Duration) *HealthRegistry {
+	if ttl <= 0 {
+		ttl = DefaultHealthCheckTTL
+	}
+	return &HealthRegistry{ttl: ttl, now: time.Now}
+}
+
+// Register adds a subsystem check, replacing any with the same name.
+//
+// Thread Safety: Safe for concurrent use.
+func (r *HealthRegistry) Register(check HealthCheck) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry := &registeredCheck{HealthCheck: check}
+	for i, existing := range r.checks {
+		if existing.Name == check.Name {
+			r.checks[i] = entry
+			return
+		}
+	}
+	r.checks = append(r.checks, entry)
+}
+
+// Check returns the state of every subsystem in registration order,
+// running the checks whose cached result has expired.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//
+// Outputs:
+//
+//	[]SubsystemHealth - One entry per registered check.
+//
+// Thread Safety: Safe for concurrent use.
+func (r *HealthRegistry) Check(ctx context.Context) []SubsystemHealth {
+	r.mu.RLock()
+	checks := make([]*registeredCheck, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	results := make([]SubsystemHealth, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check *registeredCheck) {
+			defer wg.Done()
+			results[i] = r.run(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+	return results
+}
+
+// run returns check's cached result, refreshing it if expired.
+func (r *HealthRegistry) run(ctx context.Context, check *registeredCheck) SubsystemHealth {
+	check.mu.Lock()
+	defer check.mu.Unlock()
+
+	if check.Check == nil {
+		return SubsystemHealth{
+			Name:     check.Name,
+			Status:   SubsystemDisabled,
+			Required: check.Required,
+			Detail:   check.Detail,
+		}
+	}
+	if !check.checkedAt.IsZero() && r.now().Sub(check.checkedAt) < r.ttl {
+		return check.result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	err := runHealthCheck(checkCtx, check.Check)
+	cancel()
+
+	now := r.now()
+	result := check.result
+	result.Name = check.Name
+	result.Required = check.Required
+	result.Detail = check.Detail
+	result.LastCheckedAt = &now
+	result.Status = SubsystemOK
+	if err != nil {
+		result.Status = SubsystemFailing
+		result.LastError = err.Error()
+		result.LastErrorAt = &now
+	}
+	check.result = result
+	check.checkedAt = now
+	return result
+}
+
+// runHealthCheck calls fn, turning a panic into an error.
+func runHealthCheck(ctx context.Context, fn HealthCheckFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("health check panicked: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// defaultHealthRegistry is the registry used by the readiness endpoint.
+// Like the warmup state, it is populated from cmd/trace/main.go.
+var defaultHealthRegistry = NewHealthRegistry(DefaultHealthCheckTTL)
+
+// RegisterHealthCheck adds a subsystem check to the readiness endpoint.
+//
+// Description:
+//
+//	Called from cmd/trace/main.go as subsystems are set up. Registering a
+//	name again replaces the earlier check.
+//
+// Thread Safety: This function is safe for concurrent use.
+func RegisterHealthCheck(check HealthCheck) {
+	defaultHealthRegistry.Register(check)
+}
+
+// StaticHealthCheck returns a check that always reports err. Used for
+// subsystems whose state is fixed at startup, such as a store that
+// failed to open.
+func StaticHealthCheck(err error) HealthCheckFunc {
+	return func(context.Context) error { return err }
+}
+
+// circuitStatesFunc reports LLM circuit breaker states for the readiness
+// endpoint. Like the health checks, it is set from cmd/trace/main.go.
+var circuitStatesFunc atomic.Pointer[func() map[string]string]
+
+// RegisterCircuitStates sets the source of the per-model LLM circuit
+// breaker states shown by the readiness endpoint.
+//
+// Inputs:
+//
+//	fn - Returns breaker state ("closed", "open", "half-open") by model.
+//
+// Thread Safety: This function is safe for concurrent use.
+func RegisterCircuitStates(fn func() map[string]string) {
+	circuitStatesFunc.Store(&fn)
+}
+
+// circuitStates returns the registered breaker states, or nil.
+func circuitStates() map[string]string {
+	fn := circuitStatesFunc.Load()
+	if fn == nil {
+		return nil
+	}
+	return (*fn)()
+}
+
+// ResetHealthChecks removes every registered check.
+//
+// Description:
+//
+//	Used for testing to reset the readiness state between tests. Also
+//	clears the circuit breaker state source.
+//
+// Thread Safety: This function is safe for concurrent use.
+func ResetHealthChecks() {
+	defaultHealthRegistry.mu.Lock()
+	defaultHealthRegistry.checks = nil
+	defaultHealthRegistry.mu.Unlock()
+	circuitStatesFunc.Store(nil)
+}
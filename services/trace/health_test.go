@@ -0,0 +1,164 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHealthRegistry_CachesResults(t *testing.T) {
+	registry := NewHealthRegistry(30 * time.Second)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	registry.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	var fail atomic.Bool
+	registry.Register(HealthCheck{
+		Name:     SubsystemMainProvider,
+		Required: true,
+		Detail:   "ollama/test",
+		Check: func(context.Context) error {
+			calls.Add(1)
+			if fail.Load() {
+				return errors.New("connection refused")
+			}
+			return nil
+		},
+	})
+	registry.Register(HealthCheck{Name: SubsystemRoutingCache, Detail: "not configured"})
+
+	results := registry.Check(context.Background())
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if results[0].Status != SubsystemOK || results[0].LastCheckedAt == nil {
+		t.Errorf("main provider = %+v, want ok with check time", results[0])
+	}
+	if results[1].Status != SubsystemDisabled {
+		t.Errorf("routing cache status = %q, want disabled", results[1].Status)
+	}
+
+	// Within the TTL the cached result is reused, even if the check would fail.
+	fail.Store(true)
+	now = now.Add(10 * time.Second)
+	results = registry.Check(context.Background())
+	if calls.Load() != 1 || results[0].Status != SubsystemOK {
+		t.Errorf("calls = %d, status = %q; want cached ok", calls.Load(), results[0].Status)
+	}
+
+	now = now.Add(30 * time.Second)
+	results = registry.Check(context.Background())
+	if results[0].Status != SubsystemFailing || results[0].LastError != "connection refused" {
+		t.Errorf("after TTL = %+v, want failing with error", results[0])
+	}
+
+	// The last error is kept after recovery.
+	fail.Store(false)
+	now = now.Add(31 * time.Second)
+	results = registry.Check(context.Background())
+	if results[0].Status != SubsystemOK || results[0].LastError == "" || results[0].LastErrorAt == nil {
+		t.Errorf("after recovery = %+v, want ok with last error retained", results[0])
+	}
+}
+
+func TestHealthRegistry_RecoversPanics(t *testing.T) {
+	registry := NewHealthRegistry(0)
+	registry.Register(HealthCheck{
+		Name:  SubsystemPolicyEngine,
+		Check: func(context.Context) error { panic("boom") },
+	})
+	results := registry.Check(context.Background())
+	if results[0].Status != SubsystemFailing {
+		t.Errorf("status = %q, want failing", results[0].Status)
+	}
+}
+
+// getReady requests path and decodes the readiness response.
+func getReady(t *testing.T, path string) (int, ReadyResponse) {
+	t.Helper()
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+	req, _ := http.NewRequest("GET", path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var resp ReadyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	return w.Code, resp
+}
+
+func TestHandlers_HandleReady_Subsystems(t *testing.T) {
+	t.Cleanup(ResetHealthChecks)
+	t.Cleanup(ResetWarmupStatus)
+	MarkWarmupComplete()
+
+	RegisterHealthCheck(HealthCheck{Name: SubsystemMainProvider, Required: true, Check: StaticHealthCheck(nil)})
+	RegisterHealthCheck(HealthCheck{Name: SubsystemPolicyEngine, Check: StaticHealthCheck(errors.New("no patterns"))})
+
+	code, resp := getReady(t, "/v1/trace/health/ready")
+	if code != http.StatusOK || !resp.Ready || resp.Status != "degraded" {
+		t.Errorf("optional failure: code = %d, ready = %v, status = %q; want 200 degraded", code, resp.Ready, resp.Status)
+	}
+	var sawGraphStore bool
+	for _, s := range resp.Subsystems {
+		if s.Name == SubsystemGraphStore {
+			sawGraphStore = true
+		}
+	}
+	if !sawGraphStore {
+		t.Errorf("subsystems = %+v, want graph_store entry", resp.Subsystems)
+	}
+
+	RegisterHealthCheck(HealthCheck{Name: SubsystemMainProvider, Required: true, Check: StaticHealthCheck(errors.New("ollama unreachable"))})
+	code, resp = getReady(t, "/v1/trace/health/ready")
+	if code != http.StatusServiceUnavailable || resp.Ready || resp.Status != "unhealthy" {
+		t.Errorf("required failure: code = %d, ready = %v, status = %q; want 503 unhealthy", code, resp.Ready, resp.Status)
+	}
+
+	// Liveness ignores subsystems.
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+	req, _ := http.NewRequest("GET", "/v1/trace/health/live", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("live: expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestHandlers_HandleReady_PerRoleWarmup(t *testing.T) {
+	t.Cleanup(ResetWarmupStatus)
+	ExpectWarmup(WarmupRoleRouter)
+	MarkWarmupComplete()
+
+	code, resp := getReady(t, "/v1/trace/health/ready")
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("router pending: expected status %d, got %d", http.StatusServiceUnavailable, code)
+	}
+	if len(resp.Warmup) != 2 || resp.Warmup[0].Role != WarmupRoleMain || resp.Warmup[1].Complete {
+		t.Errorf("warmup = %+v, want main complete and router pending", resp.Warmup)
+	}
+
+	MarkRoleWarmupComplete(WarmupRoleRouter, errors.New("model not found"))
+	code, resp = getReady(t, "/v1/trace/health/ready")
+	if code != http.StatusOK {
+		t.Errorf("router warmed: expected status %d, got %d", http.StatusOK, code)
+	}
+	if resp.Warmup[1].Error != "model not found" {
+		t.Errorf("router warmup error = %q", resp.Warmup[1].Error)
+	}
+}
@@ -0,0 +1,366 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+// =============================================================================
+// HTTP Caching for Read-Only Graph Endpoints
+// =============================================================================
+//
+// Graphs are frozen once built, so every read-only query against a graph
+// returns the same answer until the graph is rebuilt. IDE plugins poll these
+// endpoints heavily; this file lets them revalidate cheaply.
+//
+// Design choices:
+//
+//	1. One ETag per graph generation: the ETag is the graph content hash,
+//	   shared by every read endpoint of that graph. A rebuild with changed
+//	   source yields a new hash and so a new ETag. Weak ETags are used since
+//	   the JSON encoding is not guaranteed byte-stable across versions.
+//
+//	2. Only successful responses are cacheable: ETag and Cache-Control are
+//	   dropped when the handler answers with anything other than 200.
+//
+//	3. Memoization of expensive endpoints: callers and implementations
+//	   responses are kept in a small LRU keyed by (generation, path, query).
+//	   Entries of a generation are dropped when its graph is replaced or
+//	   evicted, alongside the tool result cache.
+
+import (
+	"bytes"
+	"container/list"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultResponseMemoEntries is the default number of memoized responses.
+const DefaultResponseMemoEntries = 256
+
+// headerTraceCache reports whether a response was served from the memo.
+const headerTraceCache = "X-Trace-Cache"
+
+// HTTPCacheConfig configures HTTP caching of read-only graph endpoints.
+type HTTPCacheConfig struct {
+	// SymbolMaxAge is the Cache-Control max-age for /symbol/:id and
+	// /symbol/:id/source. Zero sends "no-cache", so clients always
+	// revalidate with If-None-Match.
+	// Default: 60s
+	SymbolMaxAge time.Duration
+
+	// QueryMaxAge is the Cache-Control max-age for /callers,
+	// /implementations, /callees, /call-chain and /references.
+	// Default: 0 (always revalidate)
+	QueryMaxAge time.Duration
+
+	// MemoEntries caps the number of memoized responses of expensive
+	// endpoints. Zero or negative disables memoization.
+	// Default: 256
+	MemoEntries int
+}
+
+// DefaultHTTPCacheConfig returns the default HTTP cache configuration.
+func DefaultHTTPCacheConfig() HTTPCacheConfig {
+	return HTTPCacheConfig{
+		SymbolMaxAge: 60 * time.Second,
+		QueryMaxAge:  0,
+		MemoEntries:  DefaultResponseMemoEntries,
+	}
+}
+
+// endpointCacheGroup selects the Cache-Control max-age for a route.
+type endpointCacheGroup int
+
+const (
+	// cacheGroupSymbol covers symbol lookups by ID.
+	cacheGroupSymbol endpointCacheGroup = iota
+
+	// cacheGroupQuery covers graph queries by name.
+	cacheGroupQuery
+)
+
+// graphGeneration returns the generation identifying a graph's content.
+//
+// Description:
+//
+//	The content hash recorded at build time, suffixed with the CODEOWNERS
+//	hash when ownership is recorded and the annotation hash when the
+//	project has annotations. The annotation hash changes whenever an
+//	annotation does, without a rebuild. Graphs installed without a content
+//	hash fall back to the build time, which still changes on every rebuild.
+//	Hashing the graph here would cost O(V log V) per request.
+func graphGeneration(cached *CachedGraph) string {
+	generation := fmt.Sprintf("built-%d", cached.BuiltAtMilli)
+	if cached.ContentHash != "" {
+		generation = cached.ContentHash
+		if cached.OwnersHash != "" {
+			generation += "-" + cached.OwnersHash[:16]
+		}
+	}
+	if hash := cached.Annotations.Hash(); hash != "" {
+		generation += "-a" + hash[:16]
+	}
+	return generation
+}
+
+// graphETag returns the weak ETag for a graph generation.
+func graphETag(generation string) string {
+	return `W/"` + generation + `"`
+}
+
+// etagMatches reports whether an If-None-Match header matches etag.
+// Uses the weak comparison of RFC 9110 section 13.1.2.
+func etagMatches(ifNoneMatch, etag string) bool {
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControlValue formats a Cache-Control header for maxAge.
+func cacheControlValue(maxAge time.Duration) string {
+	if maxAge <= 0 {
+		return "private, no-cache"
+	}
+	return "private, max-age=" + strconv.Itoa(int(maxAge/time.Second))
+}
+
+// httpCache returns middleware adding ETag support to a read-only graph route.
+//
+// Description:
+//
+//	Resolves the graph named by the graph_id query parameter and:
+//	  - answers 304 Not Modified when If-None-Match matches its ETag,
+//	  - serves a memoized response when memoize is set and one exists,
+//	  - otherwise runs the handler, adding ETag and Cache-Control to a 200
+//	    response and memoizing it when memoize is set.
+//	Requests whose graph cannot be resolved pass through untouched so the
+//	handler reports the error, as do requests with a scope or
+//	link_template parameter.
+//
+// Inputs:
+//
+//	group - Selects the Cache-Control max-age.
+//	memoize - Whether to memoize responses of this route.
+//
+// Outputs:
+//
+//	gin.HandlerFunc - The middleware.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) httpCache(group endpointCacheGroup, memoize bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		graphID := c.Query("graph_id")
+		// Scoped results also depend on the view, and linked ones on the
+		// link template, which the graph ETag does not cover.
+		if graphID == "" || c.Query("scope") != "" || c.Query("link_template") != "" {
+			c.Next()
+			return
+		}
+		cached, err := h.svc.GetGraph(graphID)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		cfg := h.svc.config.HTTPCache
+		maxAge := cfg.QueryMaxAge
+		if group == cacheGroupSymbol {
+			maxAge = cfg.SymbolMaxAge
+		}
+		generation := graphGeneration(cached)
+		etag := graphETag(generation)
+		cacheControl := cacheControlValue(maxAge)
+
+		if inm := c.GetHeader("If-None-Match"); inm != "" && etagMatches(inm, etag) {
+			c.Header("ETag", etag)
+			c.Header("Cache-Control", cacheControl)
+			c.AbortWithStatus(http.StatusNotModified)
+			return
+		}
+
+		memo := h.svc.responseMemo
+		var key string
+		if memoize && memo != nil {
+			key = c.Request.URL.Path + "?" + c.Request.URL.Query().Encode()
+			if entry, ok := memo.get(generation, key); ok {
+				c.Header("ETag", etag)
+				c.Header("Cache-Control", cacheControl)
+				c.Header(headerTraceCache, "hit")
+				c.Data(http.StatusOK, entry.contentType, entry.body)
+				c.Abort()
+				return
+			}
+		}
+
+		c.Header("ETag", etag)
+		c.Header("Cache-Control", cacheControl)
+		writer := &cachingResponseWriter{ResponseWriter: c.Writer, capture: key != ""}
+		c.Writer = writer
+		c.Next()
+
+		if key != "" && writer.Status() == http.StatusOK {
+			memo.put(generation, key, memoEntry{
+				contentType: writer.Header().Get("Content-Type"),
+				body:        writer.body.Bytes(),
+			})
+		}
+	}
+}
+
+// cachingResponseWriter drops cache headers from non-200 responses and
+// optionally captures the response body for memoization.
+type cachingResponseWriter struct {
+	gin.ResponseWriter
+	capture bool
+	body    bytes.Buffer
+
+	// dropped is set once cache headers were removed from the response.
+	dropped bool
+}
+
+// beforeWrite removes cache headers before they are sent with a non-200
+// status. Headers are flushed on the first body write.
+func (w *cachingResponseWriter) beforeWrite() {
+	if w.dropped || w.Written() {
+		return
+	}
+	if w.Status() != http.StatusOK {
+		w.Header().Del("ETag")
+		w.Header().Del("Cache-Control")
+		w.dropped = true
+	}
+}
+
+// WriteHeaderNow implements gin.ResponseWriter.
+func (w *cachingResponseWriter) WriteHeaderNow() {
+	w.beforeWrite()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Write implements io.Writer.
+func (w *cachingResponseWriter) Write(data []byte) (int, error) {
+	w.beforeWrite()
+	if w.capture {
+		w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString implements io.StringWriter.
+func (w *cachingResponseWriter) WriteString(s string) (int, error) {
+	w.beforeWrite()
+	if w.capture {
+		w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// memoEntry is one memoized response.
+type memoEntry struct {
+	contentType string
+	body        []byte
+}
+
+// responseMemo is an LRU of successful responses keyed by graph generation
+// and request.
+//
+// Thread Safety: Safe for concurrent use.
+type responseMemo struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// responseMemoItem is one LRU element.
+type responseMemoItem struct {
+	key        string
+	generation string
+	entry      memoEntry
+}
+
+// newResponseMemo creates a memo holding up to capacity responses.
+// Returns nil when capacity <= 0, which disables memoization.
+func newResponseMemo(capacity int) *responseMemo {
+	if capacity <= 0 {
+		return nil
+	}
+	return &responseMemo{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// memoKey joins a generation and request key.
+func memoKey(generation, key string) string {
+	return generation + "\x00" + key
+}
+
+// get returns the memoized response for key in generation.
+func (m *responseMemo) get(generation, key string) (memoEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	elem, ok := m.entries[memoKey(generation, key)]
+	if !ok {
+		return memoEntry{}, false
+	}
+	m.order.MoveToFront(elem)
+	return elem.Value.(*responseMemoItem).entry, true
+}
+
+// put memoizes a response, evicting the least recently used one if full.
+func (m *responseMemo) put(generation, key string, entry memoEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	full := memoKey(generation, key)
+	if elem, ok := m.entries[full]; ok {
+		elem.Value.(*responseMemoItem).entry = entry
+		m.order.MoveToFront(elem)
+		return
+	}
+	m.entries[full] = m.order.PushFront(&responseMemoItem{key: full, generation: generation, entry: entry})
+	for m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*responseMemoItem).key)
+	}
+}
+
+// invalidate drops every memoized response of generation.
+func (m *responseMemo) invalidate(generation string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for elem := m.order.Front(); elem != nil; {
+		next := elem.Next()
+		if item := elem.Value.(*responseMemoItem); item.generation == generation {
+			m.order.Remove(elem)
+			delete(m.entries, item.key)
+		}
+		elem = next
+	}
+}
+
+// len returns the number of memoized responses.
+func (m *responseMemo) len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
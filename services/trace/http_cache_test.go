@@ -0,0 +1,274 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// setupHTTPCacheTest builds a graph for a temp project and returns the
+// project dir, service, router and graph ID.
+func setupHTTPCacheTest(t *testing.T, cfg ServiceConfig) (string, *Service, *gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	writeTestGoFiles(t, dir)
+
+	svc := NewService(cfg)
+	resp, err := svc.Init(context.Background(), dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return dir, svc, setupTestRouter(svc), resp.GraphID
+}
+
+// cacheGet issues a GET with an optional If-None-Match header.
+func cacheGet(router *gin.Engine, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestHTTPCache_IfNoneMatchReturns304(t *testing.T) {
+	_, _, router, graphID := setupHTTPCacheTest(t, DefaultServiceConfig())
+	path := "/v1/trace/callers?graph_id=" + graphID + "&function=helper"
+
+	first := cacheGet(router, path, "")
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", first.Code, first.Body.String())
+	}
+	etag := first.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected ETag on 200 response")
+	}
+	if got := first.Header().Get("Cache-Control"); got != "private, no-cache" {
+		t.Errorf("Cache-Control = %q, want %q", got, "private, no-cache")
+	}
+
+	second := cacheGet(router, path, etag)
+	if second.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want 304", second.Code)
+	}
+	if second.Body.Len() != 0 {
+		t.Errorf("304 response has body %q", second.Body.String())
+	}
+	if got := second.Header().Get("ETag"); got != etag {
+		t.Errorf("304 ETag = %q, want %q", got, etag)
+	}
+
+	// The ETag is per graph, so it validates other read endpoints too.
+	other := cacheGet(router, "/v1/trace/callees?graph_id="+graphID+"&function=main", etag)
+	if other.Code != http.StatusNotModified {
+		t.Errorf("callees status = %d, want 304", other.Code)
+	}
+
+	mismatch := cacheGet(router, path, `W/"other"`)
+	if mismatch.Code != http.StatusOK {
+		t.Errorf("mismatched If-None-Match status = %d, want 200", mismatch.Code)
+	}
+}
+
+func TestHTTPCache_SymbolMaxAge(t *testing.T) {
+	cfg := DefaultServiceConfig()
+	cfg.HTTPCache.SymbolMaxAge = 90 * time.Second
+	_, svc, router, graphID := setupHTTPCacheTest(t, cfg)
+
+	cached, err := svc.GetGraph(graphID)
+	if err != nil {
+		t.Fatalf("GetGraph: %v", err)
+	}
+	var symbolID string
+	for _, node := range cached.Graph.Nodes() {
+		if node.Symbol != nil && node.Symbol.Name == "helper" {
+			symbolID = node.Symbol.ID
+		}
+	}
+	if symbolID == "" {
+		t.Fatal("helper symbol not found")
+	}
+
+	w := cacheGet(router, "/v1/trace/symbol/"+symbolID+"?graph_id="+graphID, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Cache-Control"); got != "private, max-age=90" {
+		t.Errorf("Cache-Control = %q, want %q", got, "private, max-age=90")
+	}
+}
+
+func TestHTTPCache_ErrorResponsesHaveNoETag(t *testing.T) {
+	_, _, router, graphID := setupHTTPCacheTest(t, DefaultServiceConfig())
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{"missing_function", "/v1/trace/callers?graph_id=" + graphID},
+		{"unknown_graph", "/v1/trace/callers?graph_id=nope&function=helper"},
+		{"unknown_symbol", "/v1/trace/symbol/missing?graph_id=" + graphID},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := cacheGet(router, tt.path, "")
+			if w.Code == http.StatusOK {
+				t.Fatalf("status = 200, want an error")
+			}
+			if got := w.Header().Get("ETag"); got != "" {
+				t.Errorf("error response has ETag %q", got)
+			}
+			if got := w.Header().Get("Cache-Control"); got != "" {
+				t.Errorf("error response has Cache-Control %q", got)
+			}
+		})
+	}
+}
+
+func TestHTTPCache_RebuildChangesETag(t *testing.T) {
+	dir, svc, router, graphID := setupHTTPCacheTest(t, DefaultServiceConfig())
+	path := "/v1/trace/callers?graph_id=" + graphID + "&function=helper"
+
+	before := cacheGet(router, path, "")
+	oldETag := before.Header().Get("ETag")
+	if oldETag == "" {
+		t.Fatal("expected ETag before rebuild")
+	}
+	if svc.responseMemo.len() != 1 {
+		t.Fatalf("memo len = %d, want 1", svc.responseMemo.len())
+	}
+
+	extra := "package main\n\nfunc other() { helper() }\n"
+	if err := os.WriteFile(filepath.Join(dir, "other.go"), []byte(extra), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Init(context.Background(), dir, []string{"go"}, nil, true); err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+
+	if svc.responseMemo.len() != 0 {
+		t.Errorf("memo len after rebuild = %d, want 0", svc.responseMemo.len())
+	}
+
+	after := cacheGet(router, path, oldETag)
+	if after.Code != http.StatusOK {
+		t.Fatalf("status after rebuild = %d, want 200", after.Code)
+	}
+	newETag := after.Header().Get("ETag")
+	if newETag == "" || newETag == oldETag {
+		t.Errorf("ETag after rebuild = %q, want a new value (old %q)", newETag, oldETag)
+	}
+	if after.Header().Get(headerTraceCache) != "" {
+		t.Error("response after rebuild served from memo")
+	}
+}
+
+func TestHTTPCache_MemoizesExpensiveEndpoints(t *testing.T) {
+	_, svc, router, graphID := setupHTTPCacheTest(t, DefaultServiceConfig())
+	path := "/v1/trace/callers?graph_id=" + graphID + "&function=helper"
+
+	first := cacheGet(router, path, "")
+	if first.Header().Get(headerTraceCache) != "" {
+		t.Error("first response served from memo")
+	}
+	second := cacheGet(router, path, "")
+	if second.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", second.Code)
+	}
+	if second.Header().Get(headerTraceCache) != "hit" {
+		t.Error("second response not served from memo")
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("memoized body = %s, want %s", second.Body.String(), first.Body.String())
+	}
+	if got := second.Header().Get("Content-Type"); got != first.Header().Get("Content-Type") {
+		t.Errorf("memoized Content-Type = %q, want %q", got, first.Header().Get("Content-Type"))
+	}
+
+	// Callees are cheap and not memoized; errors are never memoized.
+	cacheGet(router, "/v1/trace/callees?graph_id="+graphID+"&function=main", "")
+	cacheGet(router, "/v1/trace/implementations?graph_id="+graphID, "")
+	if svc.responseMemo.len() != 1 {
+		t.Errorf("memo len = %d, want 1", svc.responseMemo.len())
+	}
+}
+
+func TestHTTPCache_MemoDisabled(t *testing.T) {
+	cfg := DefaultServiceConfig()
+	cfg.HTTPCache.MemoEntries = 0
+	_, svc, router, graphID := setupHTTPCacheTest(t, cfg)
+	if svc.responseMemo != nil {
+		t.Fatal("expected nil memo when MemoEntries is 0")
+	}
+
+	path := "/v1/trace/callers?graph_id=" + graphID + "&function=helper"
+	cacheGet(router, path, "")
+	w := cacheGet(router, path, "")
+	if w.Code != http.StatusOK || w.Header().Get(headerTraceCache) != "" {
+		t.Errorf("status = %d, cache = %q; want 200 without memo", w.Code, w.Header().Get(headerTraceCache))
+	}
+}
+
+func TestResponseMemo_LRUAndInvalidate(t *testing.T) {
+	memo := newResponseMemo(2)
+	memo.put("g1", "a", memoEntry{body: []byte("a")})
+	memo.put("g1", "b", memoEntry{body: []byte("b")})
+	if _, ok := memo.get("g1", "a"); !ok {
+		t.Fatal("expected hit for a")
+	}
+	memo.put("g2", "c", memoEntry{body: []byte("c")})
+
+	if _, ok := memo.get("g1", "b"); ok {
+		t.Error("least recently used entry b not evicted")
+	}
+	if _, ok := memo.get("g2", "a"); ok {
+		t.Error("entry of g1 returned for g2")
+	}
+
+	memo.invalidate("g1")
+	if _, ok := memo.get("g1", "a"); ok {
+		t.Error("g1 entry survived invalidate")
+	}
+	if entry, ok := memo.get("g2", "c"); !ok || string(entry.body) != "c" {
+		t.Error("g2 entry lost on g1 invalidate")
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	etag := `W/"abc"`
+	tests := []struct {
+		header string
+		want   bool
+	}{
+		{`W/"abc"`, true},
+		{`"abc"`, true},
+		{`"x", W/"abc"`, true},
+		{`*`, true},
+		{`W/"abd"`, false},
+		{`abc`, false},
+	}
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, etag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ignore
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// GeneratedRule is the skip-breakdown label for generated-file detection.
+const GeneratedRule = "generated"
+
+// generatedHeaderBytes bounds how much of a file is scanned for a marker.
+const generatedHeaderBytes = 4096
+
+// DefaultGeneratedGlobs are file-name patterns treated as generated code
+// even without a header marker: protobuf/gRPC stubs, mocks and codegen
+// output that commonly omit "DO NOT EDIT".
+var DefaultGeneratedGlobs = []string{
+	"*.pb.go",
+	"*.pb.gw.go",
+	"*_mock.go",
+	"mock_*.go",
+	"*_gen.go",
+	"*_gen.ts",
+	"*.generated.ts",
+	"*_pb2.py",
+	"*_pb2_grpc.py",
+}
+
+// goGeneratedRe is the standard Go marker (https://go.dev/s/generatedcode).
+var goGeneratedRe = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// IsGenerated reports whether the file at path carries a generated-code header.
+//
+// Description:
+//
+//	Go files must contain the canonical "// Code generated ... DO NOT EDIT."
+//	line before the package clause. Other languages match a header comment
+//	containing "@generated" or both "generated" and "DO NOT EDIT". Only the
+//	first 4KiB are read.
+//
+// Inputs:
+//
+//	path - Absolute file path.
+//
+// Outputs:
+//
+//	bool - True if the file looks generated.
+//	error - Non-nil if the file cannot be read.
+func IsGenerated(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	return IsGeneratedReader(f, path)
+}
+
+// IsGeneratedReader is IsGenerated for content read from r.
+//
+// Inputs:
+//
+//	r - The file content. At most 4KiB are read.
+//	name - The file's name or path, used to detect Go files.
+//
+// Outputs:
+//
+//	bool - True if the content looks generated.
+//	error - Non-nil if r cannot be read.
+func IsGeneratedReader(r io.Reader, name string) (bool, error) {
+	return isGeneratedReader(io.LimitReader(r, generatedHeaderBytes), strings.HasSuffix(name, ".go"))
+}
+
+// isGeneratedReader scans a file header for a generated-code marker.
+func isGeneratedReader(r io.Reader, isGo bool) (bool, error) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if isGo {
+			if strings.HasPrefix(line, "package ") {
+				return false, nil
+			}
+			if goGeneratedRe.MatchString(line) {
+				return true, nil
+			}
+			continue
+		}
+		if strings.Contains(line, "@generated") {
+			return true, nil
+		}
+		lower := strings.ToLower(line)
+		if strings.Contains(lower, "generated") && strings.Contains(line, "DO NOT EDIT") {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// GeneratedDetector classifies parsed files as generated code.
+//
+// Description:
+//
+//	A file is generated if its path matches one of the configured globs
+//	(gitignore syntax, so "*_mock.go" matches in any directory) or its
+//	header carries a generated-code marker as recognized by IsGenerated.
+//
+// Thread Safety: Safe for concurrent use.
+type GeneratedDetector struct {
+	globs *Matcher
+}
+
+// NewGeneratedDetector compiles globs into a detector.
+//
+// Inputs:
+//
+//	globs - Gitignore-syntax file patterns. May be empty, in which case only
+//	        header markers are detected.
+//
+// Outputs:
+//
+//	*GeneratedDetector - The detector.
+//	error - Non-nil if a glob is invalid.
+func NewGeneratedDetector(globs []string) (*GeneratedDetector, error) {
+	m, err := New("", Options{Globs: globs})
+	if err != nil {
+		return nil, err
+	}
+	return &GeneratedDetector{globs: m}, nil
+}
+
+// IsGenerated reports whether the file at relPath with the given content is
+// generated code.
+//
+// Inputs:
+//
+//	relPath - Slash-separated path relative to the project root.
+//	content - The file content. Only the first 4KiB are scanned.
+//
+// Outputs:
+//
+//	bool - True if the path matches a glob or the header has a marker.
+func (d *GeneratedDetector) IsGenerated(relPath string, content []byte) bool {
+	if d == nil {
+		return false
+	}
+	if ignored, _ := d.globs.Match(relPath, false); ignored {
+		return true
+	}
+	generated, _ := IsGeneratedReader(bytes.NewReader(content), relPath)
+	return generated
+}
@@ -0,0 +1,423 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package ignore implements gitignore-style path filtering for file discovery.
+//
+// Rules come from .gitignore files (root and nested), a root .traceignore
+// file, and globs supplied by the caller. All sources use gitignore syntax.
+// Rules are evaluated in order and the last matching rule wins, so negations
+// ("!pattern") in later rules re-include paths excluded earlier.
+package ignore
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Rule sources, used as prefixes in Match results and skip breakdowns.
+const (
+	// SourceGitignore marks rules loaded from .gitignore files.
+	SourceGitignore = ".gitignore"
+
+	// SourceTraceignore marks rules loaded from the root .traceignore file.
+	SourceTraceignore = ".traceignore"
+
+	// SourceGlobs marks rules supplied by the caller (e.g. ignore_globs).
+	SourceGlobs = "ignore_globs"
+
+	// TraceignoreFile is the name of the trace-specific ignore file.
+	TraceignoreFile = ".traceignore"
+)
+
+// Options configures which rule sources a Matcher loads.
+type Options struct {
+	// UseGitignore loads .gitignore files from the project root and, as
+	// directories are visited via LoadDir, from nested directories.
+	UseGitignore bool
+
+	// UseTraceignore loads <root>/.traceignore.
+	UseTraceignore bool
+
+	// Globs are additional gitignore-syntax patterns, applied last.
+	Globs []string
+
+	// Open, when set, reads ignore files instead of the filesystem. It
+	// receives slash-separated paths relative to the project root and
+	// reports a missing file with an error wrapping fs.ErrNotExist. Used
+	// when files come from a git revision rather than a checkout.
+	Open func(relPath string) (io.ReadCloser, error)
+}
+
+// DefaultOptions honors .gitignore and .traceignore with no extra globs.
+func DefaultOptions() Options {
+	return Options{UseGitignore: true, UseTraceignore: true}
+}
+
+// rule is a single compiled ignore pattern.
+type rule struct {
+	// label identifies the rule for stats, e.g. ".gitignore:node_modules/".
+	label string
+
+	// re matches slash-separated paths relative to the project root.
+	re *regexp.Regexp
+
+	negate  bool
+	dirOnly bool
+}
+
+// Matcher decides whether project-relative paths are ignored.
+//
+// Description:
+//
+//	Built once per discovery walk by New. Nested .gitignore files are added
+//	lazily via LoadDir as the walk descends so only visited directories are
+//	read.
+//
+// Thread Safety: Safe for concurrent use.
+type Matcher struct {
+	root string
+	opts Options
+
+	mu sync.RWMutex
+
+	// gitRules are loaded from .gitignore files, parents before children.
+	gitRules []rule
+
+	// extraRules come from .traceignore and Options.Globs. They are
+	// evaluated after gitRules so they win regardless of load order.
+	extraRules []rule
+
+	loaded map[string]bool
+}
+
+// New creates a Matcher for projectRoot.
+//
+// Inputs:
+//
+//	projectRoot - Absolute path to the project root.
+//	opts - Which rule sources to load.
+//
+// Outputs:
+//
+//	*Matcher - The matcher.
+//	error - Non-nil if a glob is invalid or an ignore file cannot be read.
+//	        Missing ignore files are not an error.
+func New(projectRoot string, opts Options) (*Matcher, error) {
+	m := &Matcher{
+		root:   projectRoot,
+		opts:   opts,
+		loaded: make(map[string]bool),
+	}
+
+	if opts.UseGitignore {
+		if err := m.LoadDir(""); err != nil {
+			return nil, err
+		}
+	}
+	if opts.UseTraceignore {
+		if err := m.loadFile(TraceignoreFile, "", SourceTraceignore); err != nil {
+			return nil, err
+		}
+	}
+	for _, glob := range opts.Globs {
+		r, ok, err := compileRule(glob, "", SourceGlobs)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			m.extraRules = append(m.extraRules, r)
+		}
+	}
+	return m, nil
+}
+
+// LoadDir loads the .gitignore in relDir (relative to the project root).
+//
+// Description:
+//
+//	No-op when gitignore support is disabled or the directory was already
+//	loaded. Rules from nested .gitignore files are anchored to their
+//	directory, matching git's behavior.
+//
+// Inputs:
+//
+//	relDir - Directory relative to the root ("" or "." for the root).
+//
+// Outputs:
+//
+//	error - Non-nil if the file exists but cannot be read or parsed.
+func (m *Matcher) LoadDir(relDir string) error {
+	if m == nil || !m.opts.UseGitignore {
+		return nil
+	}
+	relDir = normalize(relDir)
+
+	m.mu.Lock()
+	if m.loaded[relDir] {
+		m.mu.Unlock()
+		return nil
+	}
+	m.loaded[relDir] = true
+	m.mu.Unlock()
+
+	return m.loadFile(path.Join(relDir, ".gitignore"), relDir, SourceGitignore)
+}
+
+// LoadParents loads .gitignore files for every ancestor directory of relPath.
+//
+// Description:
+//
+//	Used when paths are checked outside a directory walk (e.g. a list of
+//	changed files from git), so nested rules are still applied.
+//
+// Inputs:
+//
+//	relPath - File path relative to the project root.
+//
+// Outputs:
+//
+//	error - Non-nil if an ignore file cannot be read.
+func (m *Matcher) LoadParents(relPath string) error {
+	if m == nil || !m.opts.UseGitignore {
+		return nil
+	}
+	dir := path.Dir(normalize(relPath))
+	var dirs []string
+	for dir != "." && dir != "/" && dir != "" {
+		dirs = append(dirs, dir)
+		dir = path.Dir(dir)
+	}
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := m.LoadDir(dirs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Match reports whether relPath is ignored.
+//
+// Description:
+//
+//	A path is ignored if any ancestor directory is ignored, or if the last
+//	rule matching the path itself is a non-negated rule. Dir-only rules
+//	("build/") apply only when isDir is true or to ancestors.
+//
+// Inputs:
+//
+//	relPath - Path relative to the project root (either separator).
+//	isDir - Whether relPath is a directory.
+//
+// Outputs:
+//
+//	bool - True if ignored.
+//	string - Label of the deciding rule (e.g. ".traceignore:*.pb.go").
+func (m *Matcher) Match(relPath string, isDir bool) (bool, string) {
+	if m == nil {
+		return false, ""
+	}
+	relPath = normalize(relPath)
+	if relPath == "" {
+		return false, ""
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	parts := strings.Split(relPath, "/")
+	for i := 1; i < len(parts); i++ {
+		if ignored, label := m.matchOne(strings.Join(parts[:i], "/"), true); ignored {
+			return true, label
+		}
+	}
+	return m.matchOne(relPath, isDir)
+}
+
+// matchOne evaluates rules against a single path. Caller holds m.mu.
+func (m *Matcher) matchOne(p string, isDir bool) (bool, string) {
+	ignored, label := false, ""
+	for _, rules := range [][]rule{m.gitRules, m.extraRules} {
+		for _, r := range rules {
+			if r.dirOnly && !isDir {
+				continue
+			}
+			if r.re.MatchString(p) {
+				ignored = !r.negate
+				label = r.label
+			}
+		}
+	}
+	if !ignored {
+		return false, ""
+	}
+	return true, label
+}
+
+// loadFile parses an ignore file whose rules are anchored at baseDir.
+// relFile is the file's slash-separated path relative to the root.
+func (m *Matcher) loadFile(relFile, baseDir, source string) error {
+	f, filePath, err := m.open(relFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("opening %s: %w", filePath, err)
+	}
+	defer f.Close()
+
+	var rules []rule
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		r, ok, err := compileRule(scanner.Text(), baseDir, source)
+		if err != nil {
+			return fmt.Errorf("%s: %w", filePath, err)
+		}
+		if ok {
+			rules = append(rules, r)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", filePath, err)
+	}
+
+	m.mu.Lock()
+	if source == SourceGitignore {
+		m.gitRules = append(m.gitRules, rules...)
+	} else {
+		m.extraRules = append(m.extraRules, rules...)
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+// open opens an ignore file with Options.Open, or from the filesystem
+// under the root. It also returns the path to name in errors.
+func (m *Matcher) open(relFile string) (io.ReadCloser, string, error) {
+	if m.opts.Open != nil {
+		f, err := m.opts.Open(relFile)
+		return f, relFile, err
+	}
+	filePath := filepath.Join(m.root, filepath.FromSlash(relFile))
+	f, err := os.Open(filePath)
+	return f, filePath, err
+}
+
+// compileRule converts one gitignore line into a rule.
+//
+// Outputs:
+//
+//	rule - The compiled rule.
+//	bool - False for blank lines and comments.
+//	error - Non-nil if the pattern cannot be compiled.
+func compileRule(line, baseDir, source string) (rule, bool, error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false, nil
+	}
+
+	label := source + ":" + line
+	if baseDir != "" {
+		label = source + "(" + baseDir + "):" + line
+	}
+
+	r := rule{label: label}
+	pattern := line
+	if strings.HasPrefix(pattern, "!") {
+		r.negate = true
+		pattern = pattern[1:]
+	} else if strings.HasPrefix(pattern, `\`) {
+		pattern = pattern[1:]
+	}
+	if strings.HasSuffix(pattern, "/") {
+		r.dirOnly = true
+		pattern = strings.TrimRight(pattern, "/")
+	}
+	if pattern == "" {
+		return rule{}, false, nil
+	}
+
+	// A slash anywhere but the end anchors the pattern to baseDir.
+	anchored := strings.Contains(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if baseDir != "" {
+		sb.WriteString(regexp.QuoteMeta(baseDir) + "/")
+	}
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+	sb.WriteString(globToRegexp(pattern))
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return rule{}, false, fmt.Errorf("invalid ignore pattern %q: %w", line, err)
+	}
+	r.re = re
+	return r, true, nil
+}
+
+// globToRegexp translates gitignore glob syntax to a regexp fragment.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(glob[i:], "**") && i+2 == len(glob):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + class + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(string(glob[i])))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// normalize converts a relative path to clean slash form ("" for the root).
+func normalize(p string) string {
+	p = path.Clean(filepath.ToSlash(p))
+	if p == "." || p == "/" {
+		return ""
+	}
+	return strings.TrimPrefix(p, "./")
+}
@@ -0,0 +1,213 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package ignore
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMatcher_Patterns(t *testing.T) {
+	m, err := New(t.TempDir(), Options{Globs: []string{
+		"node_modules/",
+		"*.pb.go",
+		"/gen",
+		"docs/**/*.md",
+		"**/testdata",
+		"!keep.pb.go",
+		"build/**",
+	}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"node_modules", true, true},
+		{"web/node_modules", true, true},
+		{"node_modules", false, false}, // dir-only rule
+		{"web/node_modules/react/index.js", false, true},
+		{"api/v1/service.pb.go", false, true},
+		{"api/v1/keep.pb.go", false, false},
+		{"gen", true, true},
+		{"pkg/gen", true, false}, // anchored to root
+		{"docs/a/b/c.md", false, true},
+		{"docs/c.md", false, true},
+		{"README.md", false, false},
+		{"pkg/x/testdata", true, true},
+		{"build/out/main.go", false, true},
+		{"main.go", false, false},
+	}
+	for _, tt := range tests {
+		got, label := m.Match(tt.path, tt.isDir)
+		if got != tt.want {
+			t.Errorf("Match(%q, dir=%v) = %v (%s), want %v", tt.path, tt.isDir, got, label, tt.want)
+		}
+		if got && !strings.HasPrefix(label, SourceGlobs+":") {
+			t.Errorf("Match(%q) label = %q, want ignore_globs prefix", tt.path, label)
+		}
+	}
+}
+
+func TestMatcher_Files(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, ".gitignore"), "# deps\nvendor/\n*.log\n")
+	writeFile(t, filepath.Join(root, TraceignoreFile), "*_mock.go\n")
+	writeFile(t, filepath.Join(root, "svc", ".gitignore"), "local.go\n")
+
+	m, err := New(root, DefaultOptions())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := m.LoadDir("svc"); err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+
+	if ok, label := m.Match("vendor", true); !ok || label != ".gitignore:vendor/" {
+		t.Errorf("vendor: got %v %q", ok, label)
+	}
+	if ok, label := m.Match("store/db_mock.go", false); !ok || label != ".traceignore:*_mock.go" {
+		t.Errorf("mock: got %v %q", ok, label)
+	}
+	if ok, _ := m.Match("svc/local.go", false); !ok {
+		t.Error("expected nested .gitignore to apply in svc/")
+	}
+	if ok, _ := m.Match("other/local.go", false); ok {
+		t.Error("nested .gitignore must not apply outside its directory")
+	}
+
+	disabled, err := New(root, Options{UseTraceignore: true})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if ok, _ := disabled.Match("vendor", true); ok {
+		t.Error("expected .gitignore rules to be disabled")
+	}
+}
+
+func TestMatcher_Open(t *testing.T) {
+	files := map[string]string{
+		".gitignore":     "vendor/\n",
+		TraceignoreFile:  "*_mock.go\n",
+		"svc/.gitignore": "local.go\n",
+	}
+	var opened []string
+	open := func(relPath string) (io.ReadCloser, error) {
+		opened = append(opened, relPath)
+		content, ok := files[relPath]
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", relPath, fs.ErrNotExist)
+		}
+		return io.NopCloser(strings.NewReader(content)), nil
+	}
+
+	// The root does not exist: every ignore file comes from Open.
+	m, err := New(filepath.Join(t.TempDir(), "missing"), Options{UseGitignore: true, UseTraceignore: true, Open: open})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := m.LoadDir("svc"); err != nil {
+		t.Fatalf("LoadDir: %v", err)
+	}
+	if err := m.LoadDir("pkg"); err != nil {
+		t.Fatalf("LoadDir of a directory without .gitignore: %v", err)
+	}
+
+	if ok, _ := m.Match("vendor", true); !ok {
+		t.Error("expected root .gitignore from Open to apply")
+	}
+	if ok, _ := m.Match("store/db_mock.go", false); !ok {
+		t.Error("expected .traceignore from Open to apply")
+	}
+	if ok, _ := m.Match("svc/local.go", false); !ok {
+		t.Error("expected nested .gitignore from Open to apply")
+	}
+	want := []string{".gitignore", TraceignoreFile, "svc/.gitignore", "pkg/.gitignore"}
+	if strings.Join(opened, ",") != strings.Join(want, ",") {
+		t.Errorf("opened %v, want %v", opened, want)
+	}
+}
+
+func TestIsGenerated(t *testing.T) {
+	dir := t.TempDir()
+	cases := map[string]struct {
+		content string
+		want    bool
+	}{
+		"gen.go":    {"// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage api\n", true},
+		"late.go":   {"package api\n\n// Code generated by x. DO NOT EDIT.\n", false},
+		"plain.go":  {"// Package api does things.\npackage api\n", false},
+		"schema.ts": {"/* eslint-disable */\n// @generated by graphql-codegen\nexport type X = {}\n", true},
+		"model.py":  {"# Generated by the protocol buffer compiler.  DO NOT EDIT!\n", true},
+		"app.py":    {"def main():\n    pass\n", false},
+	}
+	for name, tc := range cases {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, tc.content)
+		got, err := IsGenerated(path)
+		if err != nil {
+			t.Fatalf("IsGenerated(%s): %v", name, err)
+		}
+		if got != tc.want {
+			t.Errorf("IsGenerated(%s) = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestGeneratedDetector(t *testing.T) {
+	d, err := NewGeneratedDetector(DefaultGeneratedGlobs)
+	if err != nil {
+		t.Fatalf("NewGeneratedDetector: %v", err)
+	}
+	cases := []struct {
+		path    string
+		content string
+		want    bool
+	}{
+		{"api/v1/user.pb.go", "package v1\n", true},
+		{"internal/store/store_mock.go", "package store\n", true},
+		{"web/src/schema_gen.ts", "export type X = {}\n", true},
+		{"pkg/zz_deepcopy.go", "// Code generated by controller-gen. DO NOT EDIT.\n\npackage pkg\n", true},
+		{"pkg/handler.go", "package pkg\n", false},
+		{"pkg/mockery.go", "package pkg\n", false},
+	}
+	for _, tc := range cases {
+		if got := d.IsGenerated(tc.path, []byte(tc.content)); got != tc.want {
+			t.Errorf("IsGenerated(%s) = %v, want %v", tc.path, got, tc.want)
+		}
+	}
+
+	var nilDetector *GeneratedDetector
+	if nilDetector.IsGenerated("a.pb.go", nil) {
+		t.Error("nil detector should report nothing as generated")
+	}
+	if _, err := NewGeneratedDetector([]string{"[z-a].go"}); err == nil {
+		t.Error("expected error for invalid glob")
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -87,7 +88,7 @@ func initMetrics() error {
 func startAnalysisSpan(ctx context.Context, targetID string) (context.Context, trace.Span) {
 	return tracer.Start(ctx, "ChangeImpactAnalyzer.AnalyzeImpact",
 		trace.WithAttributes(
-			attribute.String("impact.target_id", targetID),
+			telemetry.SensitiveString("impact.target_id", targetID),
 		),
 	)
 }
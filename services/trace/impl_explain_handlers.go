@@ -0,0 +1,80 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleImplExplain explains why a type does or does not implement an
+// interface.
+//
+// Description:
+//
+//	GET /v1/trace/impl/explain?graph_id=&type=&iface=. Re-runs the
+//	builder's method-set comparison for the pair and reports, per
+//	interface method, whether the type has it, the candidate methods with
+//	their receivers, and whether a promoted method from an embedded type
+//	or cross-file method association supplied it. type and iface are
+//	symbol IDs or unique names.
+//
+// Response:
+//
+//	200 OK: ImplExplainResponse
+//	400 Bad Request: Missing parameters, ambiguous name, or graph not initialized
+//	404 Not Found: Type or interface not in the graph
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleImplExplain(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleImplExplain")
+	start := time.Now()
+
+	var req ImplExplainRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		logger.Warn("Invalid query parameters", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid query parameters: graph_id, type and iface are required")
+		return
+	}
+
+	logger.Info("Explaining implementation", "graph_id", req.GraphID, "type", req.Type, "iface", req.Iface)
+
+	exp, err := h.svc.ExplainImplements(c.Request.Context(), req.GraphID, req.Type, req.Iface)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrGraphNotInitialized) || errors.Is(err, ErrGraphExpired):
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
+		case errors.Is(err, graph.ErrSymbolNotFound) || errors.Is(err, graph.ErrNodeNotFound):
+			apierror.Write(c, http.StatusNotFound, apierror.CodeSymbolNotFound, err.Error())
+		case errors.Is(err, graph.ErrAmbiguousSymbol):
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error()+"; pass a symbol ID")
+		default:
+			logger.Error("Explain implementation failed", telemetry.SensitiveLogError("error", err))
+			apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, err.Error())
+		}
+		return
+	}
+
+	logger.Info("Explained implementation", "type_id", exp.TypeID, "interface_id", exp.InterfaceID,
+		"implements", exp.Implements, "missing", len(exp.Missing))
+
+	c.JSON(http.StatusOK, ImplExplainResponse{
+		ImplementsExplanation: exp,
+		LatencyMs:             time.Since(start).Milliseconds(),
+	})
+}
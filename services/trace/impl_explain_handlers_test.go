@@ -0,0 +1,95 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+func TestHandlers_HandleImplExplain(t *testing.T) {
+	dir := t.TempDir()
+	writeWorkspaceFiles(t, dir, map[string]string{
+		"go.mod":     "module example.com/store\n\ngo 1.22\n",
+		"store.go":   "package store\n\ntype Store interface {\n\tGet() string\n\tClose() error\n}\n",
+		"base.go":    "package store\n\ntype Base struct{}\n\nfunc (b *Base) Close() error { return nil }\n",
+		"mem.go":     "package store\n\ntype Mem struct {\n\tBase\n}\n",
+		"mem_get.go": "package store\n\nfunc (m *Mem) Get() string { return \"\" }\n",
+		"other.go":   "package store\n\ntype Other struct{}\n\nfunc (o Other) Get() string { return \"\" }\n",
+	})
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	router := setupTestRouter(svc)
+
+	explain := func(typ, iface string) (*httptest.ResponseRecorder, ImplExplainResponse) {
+		q := url.Values{"graph_id": {resp.GraphID}, "type": {typ}, "iface": {iface}}
+		req, _ := http.NewRequest("GET", "/v1/trace/impl/explain?"+q.Encode(), nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var body ImplExplainResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding response: %v", err)
+			}
+		}
+		return w, body
+	}
+
+	t.Run("promoted and cross-file methods", func(t *testing.T) {
+		w, body := explain("Mem", "Store")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+		}
+		if body.ImplementsExplanation == nil || !body.Implements || !body.EdgePresent {
+			t.Fatalf("response = %s, want Mem to implement Store", w.Body.String())
+		}
+		if !body.UsedPromoted || !body.UsedCrossFile {
+			t.Errorf("used_promoted=%v used_cross_file=%v, want both", body.UsedPromoted, body.UsedCrossFile)
+		}
+		for _, m := range body.Methods {
+			want := map[string]graph.MatchVia{"Close": graph.MatchViaPromoted, "Get": graph.MatchViaCrossFile}[m.Name]
+			if m.Via != want {
+				t.Errorf("%s via %q, want %q", m.Name, m.Via, want)
+			}
+		}
+	})
+
+	t.Run("missing method", func(t *testing.T) {
+		w, body := explain("Other", "Store")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+		}
+		if body.Implements || len(body.Missing) != 1 || body.Missing[0] != "Close" {
+			t.Errorf("implements=%v missing=%v, want false and [Close]", body.Implements, body.Missing)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		if w, _ := explain("Nope", "Store"); w.Code != http.StatusNotFound {
+			t.Errorf("unknown type status = %d, want 404", w.Code)
+		}
+		req, _ := http.NewRequest("GET", "/v1/trace/impl/explain?graph_id="+resp.GraphID+"&type=Mem", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("missing iface status = %d, want 400", w.Code)
+		}
+	})
+}
@@ -74,6 +74,7 @@ type IndexStats struct {
 //
 // The index maintains multiple maps for efficient access patterns:
 //   - byID: Primary index for unique symbol lookup
+//   - byStableID: Alternate unique lookup by line-independent Symbol.StableID
 //   - byName: Secondary index for name-based queries (multiple symbols can share a name)
 //   - byFile: Secondary index for file-based queries
 //   - byKind: Secondary index for kind-based queries
@@ -93,6 +94,9 @@ type SymbolIndex struct {
 	// Primary index: ID → Symbol
 	byID map[string]*ast.Symbol
 
+	// Alternate unique index: StableID → Symbol
+	byStableID map[string]*ast.Symbol
+
 	// Secondary indexes: key → []*Symbol
 	byName map[string][]*ast.Symbol
 	byFile map[string][]*ast.Symbol
@@ -128,6 +132,7 @@ func NewSymbolIndex(opts ...SymbolIndexOption) *SymbolIndex {
 
 	return &SymbolIndex{
 		byID:       make(map[string]*ast.Symbol),
+		byStableID: make(map[string]*ast.Symbol),
 		byName:     make(map[string][]*ast.Symbol),
 		byFile:     make(map[string][]*ast.Symbol),
 		byKind:     make(map[ast.SymbolKind][]*ast.Symbol),
@@ -276,6 +281,9 @@ func (idx *SymbolIndex) AddBatch(symbols []*ast.Symbol) error {
 // addSymbolLocked adds a symbol to all indexes. Caller must hold idx.mu.Lock().
 func (idx *SymbolIndex) addSymbolLocked(symbol *ast.Symbol) {
 	idx.byID[symbol.ID] = symbol
+	if symbol.StableID != "" {
+		idx.byStableID[symbol.StableID] = symbol
+	}
 	idx.byName[symbol.Name] = append(idx.byName[symbol.Name], symbol)
 	idx.byFile[symbol.FilePath] = append(idx.byFile[symbol.FilePath], symbol)
 	idx.byKind[symbol.Kind] = append(idx.byKind[symbol.Kind], symbol)
@@ -288,11 +296,13 @@ func (idx *SymbolIndex) addSymbolLocked(symbol *ast.Symbol) {
 //
 // Description:
 //
-//	Performs O(1) lookup in the primary index.
+//	Performs O(1) lookup in the primary index, then in the stable ID
+//	index, so either ID form is accepted.
 //
 // Inputs:
 //
-//	id - The symbol ID (format: "file_path:line:name")
+//	id - The symbol ID ("file_path:line:name") or stable ID
+//	  ("file_path::Scope.name@sighash")
 //
 // Outputs:
 //
@@ -306,7 +316,10 @@ func (idx *SymbolIndex) GetByID(id string) (*ast.Symbol, bool) {
 	idx.mu.RLock()
 	defer idx.mu.RUnlock()
 
-	sym, exists := idx.byID[id]
+	if sym, exists := idx.byID[id]; exists {
+		return sym, true
+	}
+	sym, exists := idx.byStableID[id]
 	return sym, exists
 }
 
@@ -880,6 +893,9 @@ func (idx *SymbolIndex) RemoveByFile(filePath string) int {
 	for _, sym := range symbols {
 		// Remove from byID
 		delete(idx.byID, sym.ID)
+		if idx.byStableID[sym.StableID] == sym {
+			delete(idx.byStableID, sym.StableID)
+		}
 
 		// Remove from byName
 		idx.byName[sym.Name] = removeFromSlice(idx.byName[sym.Name], sym)
@@ -934,6 +950,7 @@ func (idx *SymbolIndex) Clear() {
 	defer idx.mu.Unlock()
 
 	idx.byID = make(map[string]*ast.Symbol)
+	idx.byStableID = make(map[string]*ast.Symbol)
 	idx.byName = make(map[string][]*ast.Symbol)
 	idx.byFile = make(map[string][]*ast.Symbol)
 	idx.byKind = make(map[ast.SymbolKind][]*ast.Symbol)
@@ -1028,6 +1045,7 @@ func (idx *SymbolIndex) Clone() *SymbolIndex {
 
 	clone := &SymbolIndex{
 		byID:       make(map[string]*ast.Symbol, len(idx.byID)),
+		byStableID: make(map[string]*ast.Symbol, len(idx.byStableID)),
 		byName:     make(map[string][]*ast.Symbol, len(idx.byName)),
 		byFile:     make(map[string][]*ast.Symbol, len(idx.byFile)),
 		byKind:     make(map[ast.SymbolKind][]*ast.Symbol, len(idx.byKind)),
@@ -1040,6 +1058,9 @@ func (idx *SymbolIndex) Clone() *SymbolIndex {
 	for id, sym := range idx.byID {
 		clone.byID[id] = sym
 	}
+	for id, sym := range idx.byStableID {
+		clone.byStableID[id] = sym
+	}
 
 	// Copy byName (secondary index)
 	for name, symbols := range idx.byName {
@@ -520,6 +520,31 @@ func TestSymbolIndex_Search(t *testing.T) {
 	})
 }
 
+func TestSymbolIndex_GetByStableID(t *testing.T) {
+	idx := NewSymbolIndex()
+	sym := makeSymbol("handler.go:10:HandleAgent", "HandleAgent", ast.SymbolKindFunction, "handler.go")
+	sym.StableID = "handler.go::HandleAgent@1234abcd"
+	if err := idx.Add(sym); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	for _, id := range []string{sym.ID, sym.StableID} {
+		if got, ok := idx.GetByID(id); !ok || got != sym {
+			t.Errorf("GetByID(%q) = %v, %v", id, got, ok)
+		}
+	}
+
+	clone := idx.Clone()
+	if _, ok := clone.GetByID(sym.StableID); !ok {
+		t.Error("clone lost the stable ID index")
+	}
+
+	idx.RemoveByFile("handler.go")
+	if _, ok := idx.GetByID(sym.StableID); ok {
+		t.Error("stable ID still resolves after RemoveByFile")
+	}
+}
+
 func TestSymbolIndex_RemoveByFile(t *testing.T) {
 	t.Run("remove existing file", func(t *testing.T) {
 		idx := NewSymbolIndex()
@@ -0,0 +1,593 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var initTracer = otel.Tracer("aleutian.trace.init")
+
+const (
+	// initPhaseParsing is the InitProgress.Phase reported before the graph
+	// builder starts. Later phases use graph.ProgressPhase names.
+	initPhaseParsing = "parsing"
+
+	// maxRetainedBuilds is how many finished builds stay queryable by ID.
+	maxRetainedBuilds = 32
+
+	// progressEventBuckets is how many "build.progress" span events a phase
+	// emits at most, one per 1/progressEventBuckets of the files.
+	progressEventBuckets = 10
+)
+
+// initFlight is one in-progress Init for a graph ID.
+//
+// Description:
+//
+//	The first Init for a project becomes the leader and runs the build;
+//	concurrent Inits for the same project and build options either join
+//	it (blocking until the leader finishes and sharing its result) or
+//	return its progress immediately. The cached graph is only replaced
+//	when the leader finishes, so reads keep using the previous graph
+//	throughout.
+//
+//	The build runs detached from the leader's cancellation. It is
+//	abandoned (cancelled) only once the leader's context has ended and no
+//	joined caller is still waiting. An async Init that joins the flight
+//	polls it instead of waiting, so once one has joined the build is
+//	never abandoned and only CancelBuild stops it.
+//
+//	Each flight has a build ID and stays queryable through
+//	Service.BuildStatus after it ends, until pruned.
+//
+// Thread Safety:
+//
+//	resp and err are written once by finish before done is closed and are
+//	read only after done is closed. All other mutable fields are guarded
+//	by mu.
+type initFlight struct {
+	id          string
+	graphID     string
+	projectRoot string
+	options     string
+	startedAt   time.Time
+	done        chan struct{}
+
+	// workspace is set for multi-root builds. projectRoot is then the
+	// workspace base.
+	workspace *Workspace
+
+	resp *InitResponse
+	err  error
+
+	mu              sync.Mutex
+	parsing         bool
+	progress        graph.BuildProgress
+	parseErrors     []string
+	waiters         int
+	polled          bool
+	leaderGone      bool
+	finishedAt      time.Time
+	cancel          context.CancelFunc
+	cancelRequested bool
+	span            oteltrace.Span
+	eventPhase      graph.ProgressPhase
+	eventBucket     int
+}
+
+// newInitFlight creates a flight in the parsing phase. options is the
+// flight's initOptionsKey.
+func newInitFlight(graphID, projectRoot, options string) *initFlight {
+	return &initFlight{
+		id:          uuid.NewString(),
+		graphID:     graphID,
+		projectRoot: projectRoot,
+		options:     options,
+		startedAt:   time.Now(),
+		done:        make(chan struct{}),
+		parsing:     true,
+		eventBucket: -1,
+	}
+}
+
+// begin attaches the leader's cancel func and span. If Cancel was called
+// before the leader started, the build is cancelled immediately.
+func (f *initFlight) begin(cancel context.CancelFunc, span oteltrace.Span) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cancel = cancel
+	f.span = span
+	if f.cancelRequested {
+		cancel()
+	}
+}
+
+// requestCancel cancels the leader's context.
+//
+// Outputs:
+//
+//	bool - False if the flight had already finished.
+func (f *initFlight) requestCancel() bool {
+	select {
+	case <-f.done:
+		return false
+	default:
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cancelRequested = true
+	if f.cancel != nil {
+		f.cancel()
+	}
+	return true
+}
+
+// leaderLeft records that the leader's context ended. The build is
+// abandoned if no joined caller is waiting on it.
+func (f *initFlight) leaderLeft() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.leaderGone = true
+	f.abandonIfUnwatchedLocked()
+}
+
+// addPoller records that an async Init joined the flight. The build is
+// then no longer abandoned when its waiters leave.
+func (f *initFlight) addPoller() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.polled = true
+}
+
+// abandonIfUnwatchedLocked cancels the build once neither the leader nor
+// any joined caller is waiting on it, unless an async Init is polling it.
+// Caller holds mu.
+func (f *initFlight) abandonIfUnwatchedLocked() {
+	if !f.leaderGone || f.waiters > 0 || f.polled || f.cancelRequested {
+		return
+	}
+	f.cancelRequested = true
+	if f.cancel != nil {
+		f.cancel()
+	}
+}
+
+// wasCancelled reports whether requestCancel was called or the build was
+// abandoned.
+func (f *initFlight) wasCancelled() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.cancelRequested
+}
+
+// startBuild records that parsing finished and the builder is starting.
+func (f *initFlight) startBuild(filesTotal int, parseErrors []string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.parsing = false
+	f.progress = graph.BuildProgress{FilesTotal: filesTotal}
+	f.parseErrors = append([]string(nil), parseErrors...)
+	f.addProgressEventLocked()
+}
+
+// reportBuild is the builder's progress callback.
+func (f *initFlight) reportBuild(p graph.BuildProgress) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.parsing = false
+	f.progress = p
+	f.addProgressEventLocked()
+}
+
+// addProgressEventLocked emits a "build.progress" span event when the phase
+// changes or the files processed cross into a new bucket. Caller holds mu.
+func (f *initFlight) addProgressEventLocked() {
+	if f.span == nil {
+		return
+	}
+	bucket := 0
+	if f.progress.FilesTotal > 0 {
+		bucket = f.progress.FilesProcessed * progressEventBuckets / f.progress.FilesTotal
+	}
+	if f.progress.Phase == f.eventPhase && bucket == f.eventBucket {
+		return
+	}
+	f.eventPhase = f.progress.Phase
+	f.eventBucket = bucket
+	f.span.AddEvent("build.progress", oteltrace.WithAttributes(
+		attribute.String("phase", f.progress.Phase.String()),
+		attribute.Int("files_total", f.progress.FilesTotal),
+		attribute.Int("files_processed", f.progress.FilesProcessed),
+		attribute.Int("nodes_created", f.progress.NodesCreated),
+		attribute.Int("edges_created", f.progress.EdgesCreated),
+	))
+}
+
+// snapshot returns the flight's current progress.
+func (f *initFlight) snapshot() *InitProgress {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	phase := initPhaseParsing
+	if !f.parsing {
+		phase = f.progress.Phase.String()
+	}
+	end := f.finishedAt
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return &InitProgress{
+		Phase:          phase,
+		FilesTotal:     f.progress.FilesTotal,
+		FilesProcessed: f.progress.FilesProcessed,
+		NodesCreated:   f.progress.NodesCreated,
+		EdgesCreated:   f.progress.EdgesCreated,
+		StartedAtMilli: f.startedAt.UnixMilli(),
+		ElapsedMs:      end.Sub(f.startedAt).Milliseconds(),
+		Waiters:        f.waiters,
+		Errors:         append([]string(nil), f.parseErrors...),
+	}
+}
+
+// status returns the flight as a BuildStatusResponse.
+func (f *initFlight) status() *BuildStatusResponse {
+	st := &BuildStatusResponse{
+		BuildID:     f.id,
+		GraphID:     f.graphID,
+		ProjectRoot: f.projectRoot,
+		State:       BuildStateRunning,
+		Progress:    f.snapshot(),
+	}
+	select {
+	case <-f.done:
+	default:
+		return st
+	}
+
+	f.mu.Lock()
+	st.FinishedAtMilli = f.finishedAt.UnixMilli()
+	f.mu.Unlock()
+	switch {
+	case f.err == nil:
+		st.State = BuildStateCompleted
+		st.Result = f.resp
+	case errors.Is(f.err, ErrInitCancelled):
+		st.State = BuildStateCancelled
+		st.Error = f.err.Error()
+	default:
+		st.State = BuildStateFailed
+		st.Error = f.err.Error()
+	}
+	return st
+}
+
+// wait blocks until the leader finishes or ctx is done.
+//
+// Outputs:
+//
+//	*InitResponse - A copy of the leader's response with Joined set.
+//	error - The leader's error, or ctx.Err() if the caller gave up first.
+func (f *initFlight) wait(ctx context.Context) (*InitResponse, error) {
+	f.mu.Lock()
+	f.waiters++
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.waiters--
+		f.abandonIfUnwatchedLocked()
+		f.mu.Unlock()
+	}()
+
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := *f.resp
+	resp.Joined = true
+	return &resp, nil
+}
+
+// finish publishes the leader's result and releases waiters.
+func (f *initFlight) finish(resp *InitResponse, err error) {
+	f.mu.Lock()
+	f.finishedAt = time.Now()
+	f.mu.Unlock()
+	f.resp = resp
+	f.err = err
+	close(f.done)
+}
+
+// joinOrLeadInit returns the in-progress flight for graphID, or registers a
+// new one with the caller as leader.
+//
+// Outputs:
+//
+//	*initFlight - The flight.
+//	bool - True if the caller is the leader and must call leadInit.
+//	error - ErrInitInProgress if the running flight was started with
+//	  different build options (see initOptionsKey).
+func (s *Service) joinOrLeadInit(graphID, projectRoot string, ws *Workspace, options string) (*initFlight, bool, error) {
+	s.flightsMu.Lock()
+	defer s.flightsMu.Unlock()
+	if f, ok := s.flights[graphID]; ok {
+		if f.options != options {
+			return nil, false, fmt.Errorf("%w: build %s uses different languages, excludes or discovery options", ErrInitInProgress, f.id)
+		}
+		return f, false, nil
+	}
+	f := newInitFlight(graphID, projectRoot, options)
+	f.workspace = ws
+	s.flights[graphID] = f
+	s.builds[f.id] = f
+	return f, true, nil
+}
+
+// initOptionsKey identifies the options a build runs with, so that only
+// Inits that would build the same graph share a flight. Order does not
+// matter within the language and glob lists.
+func initOptionsKey(languages, excludes []string, discovery DiscoveryOptions) string {
+	sorted := func(ss []string) []string {
+		out := append([]string(nil), ss...)
+		sort.Strings(out)
+		return out
+	}
+	return fmt.Sprintf("languages=%q excludes=%q ignore=%q gitignore=%t generated=%t goos=%q goarch=%q tags=%q all_profiles=%t",
+		sorted(languages), sorted(excludes), sorted(discovery.IgnoreGlobs),
+		!discovery.DisableGitignore, discovery.SkipGenerated,
+		discovery.GOOS, discovery.GOARCH, sorted(discovery.BuildTags), discovery.AllBuildProfiles)
+}
+
+// leadInit runs the flight's build and publishes the result.
+//
+// Description:
+//
+//	Builds under a context detached from ctx's cancellation (its values
+//	and span are kept), so that callers who joined the flight are not
+//	failed when the leader's client goes away; when ctx ends, the build
+//	is abandoned only if no joined caller is still waiting. The leader
+//	still blocks until the build ends. CancelBuild stops the build
+//	through the same context. The span started here carries the
+//	"build.progress" events. If the build is cancelled or abandoned,
+//	nothing is installed and the error wraps ErrInitCancelled.
+func (s *Service) leadInit(ctx context.Context, f *initFlight, languages, excludes []string, discovery DiscoveryOptions) (resp *InitResponse, err error) {
+	stop := context.AfterFunc(ctx, f.leaderLeft)
+	defer stop()
+	ctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	ctx, span := initTracer.Start(ctx, "trace.Service.Init",
+		oteltrace.WithAttributes(
+			attribute.String("build_id", f.id),
+			attribute.String("graph_id", f.graphID),
+			telemetry.SensitiveString("project_root", f.projectRoot),
+		),
+	)
+	defer span.End()
+	f.begin(cancel, span)
+
+	finished := false
+	defer func() {
+		if !finished {
+			// The build panicked; release waiters before the panic unwinds.
+			s.endInit(f, nil, fmt.Errorf("init for %s aborted", f.projectRoot))
+		}
+	}()
+
+	resp, err = s.buildProject(ctx, f, f.projectRoot, f.graphID, languages, excludes, discovery)
+	if err != nil && f.wasCancelled() && errors.Is(err, context.Canceled) {
+		err = fmt.Errorf("%w: %w", ErrInitCancelled, err)
+	}
+	if err != nil {
+		telemetry.RecordException(span, err)
+		telemetry.SetErrorStatus(span, err)
+	} else {
+		if resp.Status == "" {
+			resp.Status = InitStatusBuilt
+		}
+		resp.BuildID = f.id
+		span.SetAttributes(
+			attribute.Int("files_parsed", resp.FilesParsed),
+			attribute.Int("symbols_extracted", resp.SymbolsExtracted),
+			attribute.Int("edges_built", resp.EdgesBuilt),
+		)
+	}
+	finished = true
+	s.endInit(f, resp, err)
+	return resp, err
+}
+
+// endInit publishes the leader's result and unregisters the flight, so the
+// next Init starts a fresh build. The flight stays in s.builds for status
+// queries until pruned.
+func (s *Service) endInit(f *initFlight, resp *InitResponse, err error) {
+	f.finish(resp, err)
+
+	s.flightsMu.Lock()
+	defer s.flightsMu.Unlock()
+	if s.flights[f.graphID] == f {
+		delete(s.flights, f.graphID)
+	}
+	s.pruneBuildsLocked()
+}
+
+// pruneBuildsLocked drops the oldest finished builds beyond
+// maxRetainedBuilds. Caller holds flightsMu.
+func (s *Service) pruneBuildsLocked() {
+	var ended []*initFlight
+	for _, f := range s.builds {
+		select {
+		case <-f.done:
+			ended = append(ended, f)
+		default:
+		}
+	}
+	if len(ended) <= maxRetainedBuilds {
+		return
+	}
+	sort.Slice(ended, func(i, j int) bool {
+		return ended[i].startedAt.Before(ended[j].startedAt)
+	})
+	for _, f := range ended[:len(ended)-maxRetainedBuilds] {
+		delete(s.builds, f.id)
+	}
+}
+
+// StartInitAsync starts a graph build in the background.
+//
+// Description:
+//
+//	Validates the request, then starts a rebuild detached from ctx's
+//	cancellation (ctx's values and span are kept) and returns at once.
+//	If a build is already running for the project, its status is returned
+//	instead of starting another. Poll BuildStatus with the returned build
+//	ID; stop the build with CancelBuild.
+//
+// Inputs:
+//
+//	ctx - Context for values and tracing only.
+//	projectRoot - Absolute path to the project root.
+//	languages - Languages to parse. Default: ["go"].
+//	excludes - Glob patterns to exclude.
+//	discovery - File discovery options.
+//
+// Outputs:
+//
+//	*BuildStatusResponse - The running build's status.
+//	error - ErrRelativePath or ErrPathTraversal if the root is invalid,
+//	  ErrInitInProgress if the running build has different options.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Service) StartInitAsync(ctx context.Context, projectRoot string, languages, excludes []string, discovery DiscoveryOptions) (*BuildStatusResponse, error) {
+	graphID, languages, excludes, err := s.prepareInit(projectRoot, languages, excludes, discovery)
+	if err != nil {
+		return nil, err
+	}
+	return s.startAsync(ctx, graphID, projectRoot, nil, languages, excludes, discovery)
+}
+
+// startAsync starts a detached build for graphID, or returns the status of
+// the one already running.
+func (s *Service) startAsync(ctx context.Context, graphID, projectRoot string, ws *Workspace, languages, excludes []string, discovery DiscoveryOptions) (*BuildStatusResponse, error) {
+	f, leader, err := s.joinOrLeadInit(graphID, projectRoot, ws, initOptionsKey(languages, excludes, discovery))
+	if err != nil {
+		return nil, err
+	}
+	if !leader {
+		// A joined synchronous leader's disconnect must not abandon a
+		// build this caller is polling.
+		f.addPoller()
+		return f.status(), nil
+	}
+	go func() {
+		if _, err := s.leadInit(context.WithoutCancel(ctx), f, languages, excludes, discovery); err != nil {
+			slog.Warn("Async init failed",
+				slog.String("build_id", f.id),
+				telemetry.SensitiveLog("project_root", projectRoot),
+				telemetry.SensitiveLogError("error", err),
+			)
+		}
+	}()
+	return f.status(), nil
+}
+
+// BuildStatus returns the status of a build started by any Init variant.
+//
+// Outputs:
+//
+//	*BuildStatusResponse - Current progress, plus the result once finished.
+//	error - ErrBuildNotFound if the ID is unknown or was pruned.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Service) BuildStatus(buildID string) (*BuildStatusResponse, error) {
+	s.flightsMu.Lock()
+	f, ok := s.builds[buildID]
+	s.flightsMu.Unlock()
+	if !ok {
+		return nil, ErrBuildNotFound
+	}
+	return f.status(), nil
+}
+
+// CancelBuild cancels a running build.
+//
+// Description:
+//
+//	Cancels the build's context, which stops parsing and the graph
+//	builder. A cancelled build never installs its graph, so the project
+//	keeps its previous graph (or stays uninitialized). Callers waiting on
+//	the build receive an error wrapping ErrInitCancelled. Cancelling a
+//	finished build is a no-op.
+//
+// Outputs:
+//
+//	*BuildStatusResponse - The build's status; State stays
+//	  BuildStateRunning until the builder observes the cancellation.
+//	bool - False if the build had already finished.
+//	error - ErrBuildNotFound if the ID is unknown or was pruned.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Service) CancelBuild(buildID string) (*BuildStatusResponse, bool, error) {
+	s.flightsMu.Lock()
+	f, ok := s.builds[buildID]
+	s.flightsMu.Unlock()
+	if !ok {
+		return nil, false, ErrBuildNotFound
+	}
+	cancelled := f.requestCancel()
+	if cancelled {
+		slog.Info("Init cancellation requested",
+			slog.String("build_id", buildID),
+			telemetry.SensitiveLog("project_root", f.projectRoot),
+		)
+	}
+	return f.status(), cancelled, nil
+}
+
+// InitProgress returns the progress of the in-progress Init for a project.
+//
+// Inputs:
+//
+//	projectRoot - The project root passed to Init.
+//	gitRef - The git_ref the build was started with, or "" for the
+//	  working tree. Builds of different refs are separate flights.
+//
+// Outputs:
+//
+//	*InitProgress - Current progress.
+//	bool - False if no Init is running for the project and ref.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Service) InitProgress(projectRoot, gitRef string) (*InitProgress, bool) {
+	s.flightsMu.Lock()
+	f, ok := s.flights[s.projectGraphID(projectRoot, gitRef)]
+	s.flightsMu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return f.snapshot(), true
+}
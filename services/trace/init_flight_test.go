@@ -0,0 +1,720 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	testSpansOnce sync.Once
+	testSpans     *tracetest.InMemoryExporter
+)
+
+// recordSpans returns an in-memory exporter, emptied, that receives the
+// spans of every tracer in the process. The global provider is installed
+// once: package-level tracers bind to the first provider set, so a later
+// otel.SetTracerProvider would not reach them.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	testSpansOnce.Do(func() {
+		testSpans = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(testSpans)))
+	})
+	testSpans.Reset()
+	return testSpans
+}
+
+// gateBuilds makes every graph build on svc block until the returned
+// release func is called. entered is closed when the first build reaches
+// the gate.
+func gateBuilds(svc *Service) (entered <-chan struct{}, release func()) {
+	in := make(chan struct{})
+	gate := make(chan struct{})
+	var once, releaseOnce sync.Once
+	svc.beforeBuild = func() {
+		once.Do(func() { close(in) })
+		<-gate
+	}
+	return in, func() { releaseOnce.Do(func() { close(gate) }) }
+}
+
+// waitForWaiters polls until the in-progress init for root has n waiters.
+func waitForWaiters(t *testing.T, svc *Service, root string, n int) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		if p, ok := svc.InitProgress(root, ""); ok && p.Waiters == n {
+			return
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d init waiters", n)
+}
+
+// waitForBuildState polls until the build reaches a state other than running.
+func waitForBuildState(t *testing.T, svc *Service, buildID string) *BuildStatusResponse {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		st, err := svc.BuildStatus(buildID)
+		if err != nil {
+			t.Fatalf("BuildStatus failed: %v", err)
+		}
+		if st.State != BuildStateRunning {
+			return st
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for build %s to finish", buildID)
+	return nil
+}
+
+func TestInit_ConcurrentSameProject_SingleBuild(t *testing.T) {
+	root := t.TempDir()
+	writeTestGoFiles(t, root)
+
+	svc := NewService(DefaultServiceConfig())
+	entered, release := gateBuilds(svc)
+	defer release()
+
+	const callers = 10
+	var wg sync.WaitGroup
+	resps := make([]*InitResponse, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resps[i], errs[i] = svc.InitWithDiscovery(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{}, true)
+		}(i)
+	}
+
+	<-entered
+	waitForWaiters(t, svc, root, callers-1)
+	release()
+	wg.Wait()
+
+	if got := svc.graphBuilds.Load(); got != 1 {
+		t.Errorf("expected exactly 1 build, got %d", got)
+	}
+
+	joined := 0
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("caller %d: Init failed: %v", i, errs[i])
+		}
+		if resps[i].GraphID != resps[0].GraphID {
+			t.Errorf("caller %d: graph ID %s, want %s", i, resps[i].GraphID, resps[0].GraphID)
+		}
+		if resps[i].Status != InitStatusBuilt {
+			t.Errorf("caller %d: status %q, want %q", i, resps[i].Status, InitStatusBuilt)
+		}
+		if resps[i].SymbolsExtracted == 0 {
+			t.Errorf("caller %d: expected symbols", i)
+		}
+		if resps[i].Joined {
+			joined++
+		}
+	}
+	if joined != callers-1 {
+		t.Errorf("expected %d joined callers, got %d", callers-1, joined)
+	}
+	if _, ok := svc.InitProgress(root, ""); ok {
+		t.Error("expected no init in progress after build finished")
+	}
+}
+
+func TestInit_LeaderDisconnect(t *testing.T) {
+	t.Run("joined caller keeps the build", func(t *testing.T) {
+		root := t.TempDir()
+		writeTestGoFiles(t, root)
+		svc := NewService(DefaultServiceConfig())
+		entered, release := gateBuilds(svc)
+		defer release()
+
+		leaderCtx, disconnect := context.WithCancel(context.Background())
+		leader := make(chan error, 1)
+		go func() {
+			_, err := svc.Init(leaderCtx, root, []string{"go"}, nil, true)
+			leader <- err
+		}()
+		<-entered
+
+		type result struct {
+			resp *InitResponse
+			err  error
+		}
+		joiner := make(chan result, 1)
+		go func() {
+			resp, err := svc.Init(context.Background(), root, []string{"go"}, nil, true)
+			joiner <- result{resp, err}
+		}()
+		waitForWaiters(t, svc, root, 1)
+
+		disconnect()
+		release()
+
+		got := <-joiner
+		if got.err != nil {
+			t.Fatalf("joined caller: Init failed: %v", got.err)
+		}
+		if !got.resp.Joined || got.resp.Status != InitStatusBuilt {
+			t.Errorf("expected joined built response, got status=%q joined=%v", got.resp.Status, got.resp.Joined)
+		}
+		if err := <-leader; err != nil {
+			t.Errorf("leader: expected the shared build to complete, got %v", err)
+		}
+		if _, err := svc.GetGraph(got.resp.GraphID); err != nil {
+			t.Errorf("expected the graph to be installed: %v", err)
+		}
+	})
+
+	t.Run("build abandoned once every caller left", func(t *testing.T) {
+		root := t.TempDir()
+		writeTestGoFiles(t, root)
+		svc := NewService(DefaultServiceConfig())
+		entered, release := gateBuilds(svc)
+		defer release()
+
+		leaderCtx, disconnectLeader := context.WithCancel(context.Background())
+		leader := make(chan error, 1)
+		go func() {
+			_, err := svc.Init(leaderCtx, root, []string{"go"}, nil, true)
+			leader <- err
+		}()
+		<-entered
+
+		joinerCtx, disconnectJoiner := context.WithCancel(context.Background())
+		joiner := make(chan error, 1)
+		go func() {
+			_, err := svc.Init(joinerCtx, root, []string{"go"}, nil, true)
+			joiner <- err
+		}()
+		waitForWaiters(t, svc, root, 1)
+
+		disconnectLeader()
+		disconnectJoiner()
+		if err := <-joiner; !errors.Is(err, context.Canceled) {
+			t.Errorf("joiner: expected context.Canceled, got %v", err)
+		}
+		// The leader's departure is observed asynchronously.
+		svc.flightsMu.Lock()
+		flight := svc.flights[svc.generateGraphID(root)]
+		svc.flightsMu.Unlock()
+		deadline := time.Now().Add(10 * time.Second)
+		for !flight.wasCancelled() && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		release()
+
+		if err := <-leader; !errors.Is(err, ErrInitCancelled) {
+			t.Errorf("leader: expected ErrInitCancelled, got %v", err)
+		}
+		if _, err := svc.GetGraph(svc.generateGraphID(root)); err == nil {
+			t.Error("expected no graph after an abandoned build")
+		}
+	})
+
+	t.Run("async caller keeps the build", func(t *testing.T) {
+		root := t.TempDir()
+		writeTestGoFiles(t, root)
+		svc := NewService(DefaultServiceConfig())
+		entered, release := gateBuilds(svc)
+		defer release()
+
+		leaderCtx, disconnect := context.WithCancel(context.Background())
+		leader := make(chan error, 1)
+		go func() {
+			_, err := svc.Init(leaderCtx, root, []string{"go"}, nil, true)
+			leader <- err
+		}()
+		<-entered
+
+		st, err := svc.StartInitAsync(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{})
+		if err != nil {
+			t.Fatalf("StartInitAsync: %v", err)
+		}
+		svc.flightsMu.Lock()
+		flight := svc.flights[svc.generateGraphID(root)]
+		svc.flightsMu.Unlock()
+		if st.BuildID != flight.id {
+			t.Fatalf("async Init started build %s, want to join %s", st.BuildID, flight.id)
+		}
+
+		disconnect()
+		// The leader's departure is observed asynchronously.
+		deadline := time.Now().Add(10 * time.Second)
+		for time.Now().Before(deadline) {
+			flight.mu.Lock()
+			gone := flight.leaderGone
+			flight.mu.Unlock()
+			if gone {
+				break
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		if flight.wasCancelled() {
+			t.Fatal("build abandoned while an async caller was polling it")
+		}
+		release()
+
+		if final := waitForBuildState(t, svc, st.BuildID); final.State != BuildStateCompleted {
+			t.Fatalf("async caller: build state = %s (%s), want completed", final.State, final.Error)
+		}
+		if err := <-leader; err != nil {
+			t.Errorf("leader: expected the shared build to complete, got %v", err)
+		}
+		if _, err := svc.GetGraph(st.GraphID); err != nil {
+			t.Errorf("expected the graph to be installed: %v", err)
+		}
+	})
+}
+
+func TestInit_MismatchedOptionsNotShared(t *testing.T) {
+	root := t.TempDir()
+	writeTestGoFiles(t, root)
+	svc := NewService(DefaultServiceConfig())
+	entered, release := gateBuilds(svc)
+	defer release()
+
+	leader := make(chan error, 1)
+	go func() {
+		_, err := svc.Init(context.Background(), root, []string{"go"}, nil, true)
+		leader <- err
+	}()
+	<-entered
+
+	if _, err := svc.Init(context.Background(), root, []string{"go", "python"}, nil, true); !errors.Is(err, ErrInitInProgress) {
+		t.Errorf("different languages: expected ErrInitInProgress, got %v", err)
+	}
+	if _, err := svc.StartInitWithDiscovery(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{SkipGenerated: true}, true); !errors.Is(err, ErrInitInProgress) {
+		t.Errorf("different discovery options: expected ErrInitInProgress, got %v", err)
+	}
+	if _, err := svc.StartInitAsync(context.Background(), root, []string{"go"}, []string{"gen/*"}, DiscoveryOptions{}); !errors.Is(err, ErrInitInProgress) {
+		t.Errorf("different excludes: expected ErrInitInProgress, got %v", err)
+	}
+
+	release()
+	if err := <-leader; err != nil {
+		t.Fatalf("leader: Init failed: %v", err)
+	}
+	if got := svc.graphBuilds.Load(); got != 1 {
+		t.Errorf("expected exactly 1 build, got %d", got)
+	}
+}
+
+func TestInit_RebuildKeepsServingPreviousGraph(t *testing.T) {
+	root := t.TempDir()
+	writeTestGoFiles(t, root)
+
+	svc := NewService(DefaultServiceConfig())
+	ctx := context.Background()
+
+	first, err := svc.Init(ctx, root, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("first Init failed: %v", err)
+	}
+	previous, err := svc.GetGraph(first.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph failed: %v", err)
+	}
+
+	entered, release := gateBuilds(svc)
+	defer release()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := svc.Init(ctx, root, []string{"go"}, nil, true)
+		done <- err
+	}()
+	<-entered
+
+	t.Run("reads_use_previous_graph", func(t *testing.T) {
+		cached, err := svc.GetGraph(first.GraphID)
+		if err != nil {
+			t.Fatalf("GetGraph during rebuild failed: %v", err)
+		}
+		if cached != previous {
+			t.Error("expected previous graph while rebuilding")
+		}
+	})
+
+	t.Run("non_rebuild_init_returns_cached", func(t *testing.T) {
+		resp, err := svc.Init(ctx, root, []string{"go"}, nil)
+		if err != nil {
+			t.Fatalf("Init during rebuild failed: %v", err)
+		}
+		if resp.Status != InitStatusCached {
+			t.Errorf("expected status %q, got %q", InitStatusCached, resp.Status)
+		}
+	})
+
+	release()
+	if err := <-done; err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+
+	t.Run("swapped_after_rebuild", func(t *testing.T) {
+		cached, err := svc.GetGraph(first.GraphID)
+		if err != nil {
+			t.Fatalf("GetGraph after rebuild failed: %v", err)
+		}
+		if cached == previous {
+			t.Error("expected new graph after rebuild")
+		}
+	})
+}
+
+func TestStartInit_AlreadyBuilding(t *testing.T) {
+	root := t.TempDir()
+	writeTestGoFiles(t, root)
+
+	svc := NewService(DefaultServiceConfig())
+	entered, release := gateBuilds(svc)
+	defer release()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := svc.StartInitWithDiscovery(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{}, true)
+		done <- err
+	}()
+	<-entered
+
+	resp, err := svc.StartInitWithDiscovery(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{}, true)
+	if err != nil {
+		t.Fatalf("StartInitWithDiscovery failed: %v", err)
+	}
+	if resp.Status != InitStatusAlreadyBuilding {
+		t.Fatalf("expected status %q, got %q", InitStatusAlreadyBuilding, resp.Status)
+	}
+	if resp.Progress == nil {
+		t.Fatal("expected progress")
+	}
+	if resp.Progress.Phase == initPhaseParsing {
+		t.Errorf("expected a build phase once the builder started, got %q", resp.Progress.Phase)
+	}
+	if resp.Progress.FilesTotal == 0 {
+		t.Error("expected files_total to be reported")
+	}
+
+	t.Run("waiting_caller_gives_up_with_context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := svc.InitWithDiscovery(ctx, root, []string{"go"}, nil, DiscoveryOptions{}, true); err != context.DeadlineExceeded {
+			t.Errorf("expected context.DeadlineExceeded, got %v", err)
+		}
+	})
+
+	release()
+	if err := <-done; err != nil {
+		t.Fatalf("leading init failed: %v", err)
+	}
+	if got := svc.graphBuilds.Load(); got != 1 {
+		t.Errorf("expected exactly 1 build, got %d", got)
+	}
+}
+
+func TestHandlers_HandleInit_AlreadyBuilding(t *testing.T) {
+	root := t.TempDir()
+	writeTestGoFiles(t, root)
+
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	entered, release := gateBuilds(svc)
+	defer release()
+
+	post := func(path string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(InitRequest{ProjectRoot: root, Languages: []string{"go"}})
+		req, _ := http.NewRequest("POST", path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	leader := make(chan *httptest.ResponseRecorder, 1)
+	go func() { leader <- post("/v1/trace/init") }()
+	<-entered
+
+	t.Run("returns_202_with_progress", func(t *testing.T) {
+		w := post("/v1/trace/init")
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+		}
+		var resp InitResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		if resp.Status != InitStatusAlreadyBuilding || resp.Progress == nil {
+			t.Errorf("expected already_building with progress, got %+v", resp)
+		}
+	})
+
+	t.Run("invalid_wait", func(t *testing.T) {
+		if w := post("/v1/trace/init?wait=maybe"); w.Code != http.StatusBadRequest {
+			t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+		}
+	})
+
+	waiter := make(chan *httptest.ResponseRecorder, 1)
+	go func() { waiter <- post("/v1/trace/init?wait=true") }()
+	waitForWaiters(t, svc, root, 1)
+	release()
+
+	if w := <-leader; w.Code != http.StatusOK {
+		t.Fatalf("leader: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w := <-waiter
+	if w.Code != http.StatusOK {
+		t.Fatalf("waiter: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp InitResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if !resp.Joined || resp.Status != InitStatusBuilt {
+		t.Errorf("expected joined built response, got status=%q joined=%v", resp.Status, resp.Joined)
+	}
+	if got := svc.graphBuilds.Load(); got != 1 {
+		t.Errorf("expected exactly 1 build, got %d", got)
+	}
+}
+
+func TestStartInitAsync_PollUntilComplete(t *testing.T) {
+	exporter := recordSpans(t)
+
+	root := t.TempDir()
+	writeTestGoFiles(t, root)
+
+	svc := NewService(DefaultServiceConfig())
+	entered, release := gateBuilds(svc)
+	defer release()
+
+	started, err := svc.StartInitAsync(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{})
+	if err != nil {
+		t.Fatalf("StartInitAsync failed: %v", err)
+	}
+	if started.BuildID == "" || started.State != BuildStateRunning {
+		t.Fatalf("expected running build with ID, got %+v", started)
+	}
+	<-entered
+
+	t.Run("second_start_reports_same_build", func(t *testing.T) {
+		again, err := svc.StartInitAsync(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{})
+		if err != nil {
+			t.Fatalf("StartInitAsync failed: %v", err)
+		}
+		if again.BuildID != started.BuildID {
+			t.Errorf("expected build %s, got %s", started.BuildID, again.BuildID)
+		}
+	})
+
+	t.Run("running_status_has_progress", func(t *testing.T) {
+		st, err := svc.BuildStatus(started.BuildID)
+		if err != nil {
+			t.Fatalf("BuildStatus failed: %v", err)
+		}
+		if st.State != BuildStateRunning || st.Progress == nil || st.Progress.FilesTotal == 0 {
+			t.Errorf("expected running status with files_total, got %+v", st)
+		}
+		if st.Result != nil {
+			t.Error("expected no result while running")
+		}
+	})
+
+	release()
+	st := waitForBuildState(t, svc, started.BuildID)
+	if st.State != BuildStateCompleted {
+		t.Fatalf("expected completed, got %s (%s)", st.State, st.Error)
+	}
+	if st.Result == nil || st.Result.BuildID != started.BuildID || st.Result.SymbolsExtracted == 0 {
+		t.Errorf("unexpected result %+v", st.Result)
+	}
+	if st.Progress.FilesProcessed != st.Progress.FilesTotal {
+		t.Errorf("expected all files processed, got %d/%d", st.Progress.FilesProcessed, st.Progress.FilesTotal)
+	}
+	if _, err := svc.GetGraph(started.GraphID); err != nil {
+		t.Errorf("expected graph installed: %v", err)
+	}
+
+	t.Run("progress_span_events", func(t *testing.T) {
+		phases := map[string]bool{}
+		for _, span := range exporter.GetSpans() {
+			if span.Name != "trace.Service.Init" {
+				continue
+			}
+			for _, ev := range span.Events {
+				if ev.Name != "build.progress" {
+					continue
+				}
+				for _, kv := range ev.Attributes {
+					if kv.Key == "phase" {
+						phases[kv.Value.AsString()] = true
+					}
+				}
+			}
+		}
+		for _, want := range []string{"collecting", "extracting_edges", "finalizing"} {
+			if !phases[want] {
+				t.Errorf("missing build.progress event for phase %q (got %v)", want, phases)
+			}
+		}
+	})
+}
+
+func TestCancelBuild_LeavesPreInitState(t *testing.T) {
+	tests := []struct {
+		name          string
+		existingGraph bool
+	}{
+		{"uninitialized", false},
+		{"rebuild", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeTestGoFiles(t, root)
+
+			svc := NewService(DefaultServiceConfig())
+			var previous *CachedGraph
+			if tt.existingGraph {
+				resp, err := svc.Init(context.Background(), root, []string{"go"}, nil)
+				if err != nil {
+					t.Fatalf("Init failed: %v", err)
+				}
+				previous, _ = svc.GetGraph(resp.GraphID)
+			}
+			entered, release := gateBuilds(svc)
+			defer release()
+
+			started, err := svc.StartInitAsync(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{})
+			if err != nil {
+				t.Fatalf("StartInitAsync failed: %v", err)
+			}
+			<-entered
+
+			waiter := make(chan error, 1)
+			go func() {
+				_, err := svc.Init(context.Background(), root, []string{"go"}, nil, true)
+				waiter <- err
+			}()
+			waitForWaiters(t, svc, root, 1)
+
+			if _, cancelled, err := svc.CancelBuild(started.BuildID); err != nil || !cancelled {
+				t.Fatalf("CancelBuild = %v, %v; want true, nil", cancelled, err)
+			}
+			release()
+
+			st := waitForBuildState(t, svc, started.BuildID)
+			if st.State != BuildStateCancelled {
+				t.Fatalf("expected cancelled, got %s (%s)", st.State, st.Error)
+			}
+			if err := <-waiter; !errors.Is(err, ErrInitCancelled) {
+				t.Errorf("expected waiter to get ErrInitCancelled, got %v", err)
+			}
+
+			cached, err := svc.GetGraph(started.GraphID)
+			if tt.existingGraph {
+				if err != nil || cached != previous {
+					t.Errorf("expected previous graph to remain, got %v, %v", cached, err)
+				}
+			} else if err == nil {
+				t.Error("expected no graph after cancelled first build")
+			}
+
+			if _, cancelled, err := svc.CancelBuild(started.BuildID); err != nil || cancelled {
+				t.Errorf("cancelling a finished build = %v, %v; want false, nil", cancelled, err)
+			}
+		})
+	}
+}
+
+func TestHandlers_AsyncInit(t *testing.T) {
+	root := t.TempDir()
+	writeTestGoFiles(t, root)
+
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	entered, release := gateBuilds(svc)
+	defer release()
+
+	do := func(method, path string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			_ = json.NewEncoder(&buf).Encode(body)
+		}
+		req, _ := http.NewRequest(method, path, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) BuildStatusResponse {
+		t.Helper()
+		var st BuildStatusResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &st); err != nil {
+			t.Fatalf("failed to unmarshal response: %v", err)
+		}
+		return st
+	}
+
+	w := do("POST", "/v1/trace/init", InitRequest{ProjectRoot: root, Languages: []string{"go"}, Async: true})
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	started := decode(w)
+	if started.BuildID == "" {
+		t.Fatal("expected build_id")
+	}
+	<-entered
+
+	w = do("GET", "/v1/trace/init/"+started.BuildID, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if st := decode(w); st.State != BuildStateRunning {
+		t.Errorf("expected running, got %s", st.State)
+	}
+
+	w = do("DELETE", "/v1/trace/init/"+started.BuildID, nil)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+	release()
+	if st := waitForBuildState(t, svc, started.BuildID); st.State != BuildStateCancelled {
+		t.Errorf("expected cancelled, got %s", st.State)
+	}
+
+	if w := do("DELETE", "/v1/trace/init/"+started.BuildID, nil); w.Code != http.StatusOK {
+		t.Errorf("expected status %d for finished build, got %d", http.StatusOK, w.Code)
+	}
+	for _, method := range []string{"GET", "DELETE"} {
+		if w := do(method, "/v1/trace/init/unknown", nil); w.Code != http.StatusNotFound {
+			t.Errorf("%s unknown build: expected status %d, got %d", method, http.StatusNotFound, w.Code)
+		}
+	}
+	if w := do("POST", "/v1/trace/init", InitRequest{ProjectRoot: "relative", Async: true}); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for relative root, got %d", http.StatusBadRequest, w.Code)
+	}
+}
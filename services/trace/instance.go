@@ -0,0 +1,89 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers for running several instances behind a load balancer.
+const (
+	// HeaderInstance names the instance that served a response.
+	HeaderInstance = "X-Trace-Instance"
+
+	// HeaderStickyKey is the header clients echo an InstanceHint's
+	// StickyKey in, for load balancers that hash on it.
+	HeaderStickyKey = "X-Trace-Sticky-Key"
+)
+
+// InstanceConfig describes this instance to the agent handlers when
+// several instances serve the same clients.
+type InstanceConfig struct {
+	// ID identifies the instance in responses and shared session records.
+	ID string
+
+	// StickyHints adds an InstanceHint to agent run responses.
+	StickyHints bool
+
+	// SharedSessions is true when sessions are restored from a store shared
+	// with other instances. Continuing a session whose graph is not loaded
+	// here then returns 409 GRAPH_NOT_INITIALIZED_HERE.
+	SharedSessions bool
+}
+
+// InstanceHint tells clients and load balancers how to keep a project's
+// requests on one instance.
+type InstanceHint struct {
+	// Instance is the instance that served the request.
+	Instance string `json:"instance"`
+
+	// StickyKey is the project's graph ID. Requests that send it in the
+	// X-Trace-Sticky-Key header hash to the same instance under a
+	// consistent-hash load balancer, which keeps the project's graph and
+	// sessions together. It equals the graph_id returned by /init, so
+	// clients can send it from their first request.
+	StickyKey string `json:"sticky_key"`
+
+	// Header is the request header to send StickyKey in.
+	Header string `json:"header"`
+}
+
+// ResolveInstanceID returns the instance ID: TRACE_INSTANCE_ID if set,
+// else the hostname, else "trace".
+func ResolveInstanceID() string {
+	if id := os.Getenv("TRACE_INSTANCE_ID"); id != "" {
+		return id
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return host
+	}
+	return "trace"
+}
+
+// InstanceMiddleware sets the X-Trace-Instance header on every response.
+//
+// Inputs:
+//
+//	instanceID - The ID to report. Usually ResolveInstanceID().
+//
+// Outputs:
+//
+//	gin.HandlerFunc - The middleware.
+//
+// Thread Safety: Safe for concurrent use.
+func InstanceMiddleware(instanceID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(HeaderInstance, instanceID)
+		c.Next()
+	}
+}
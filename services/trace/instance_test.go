@@ -0,0 +1,119 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/gin-gonic/gin"
+)
+
+func TestResolveInstanceID(t *testing.T) {
+	t.Setenv("TRACE_INSTANCE_ID", "replica-7")
+	if got := ResolveInstanceID(); got != "replica-7" {
+		t.Errorf("ResolveInstanceID() = %q, want replica-7", got)
+	}
+	t.Setenv("TRACE_INSTANCE_ID", "")
+	if got := ResolveInstanceID(); got == "" {
+		t.Error("ResolveInstanceID() without TRACE_INSTANCE_ID is empty")
+	}
+}
+
+func TestInstanceMiddleware(t *testing.T) {
+	root := t.TempDir()
+	svc := NewService(DefaultServiceConfig())
+	handlers := NewAgentHandlers(&MockAgentLoop{}, svc,
+		WithInstance(InstanceConfig{ID: "replica-a", StickyHints: true}))
+	r := gin.New()
+	r.Use(InstanceMiddleware("replica-a"))
+	RegisterAgentRoutes(r.Group("/v1"), handlers)
+
+	body, _ := json.Marshal(AgentRunRequest{ProjectRoot: root, Query: "what does main do?"})
+	req := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Status = %d, body %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get(HeaderInstance); got != "replica-a" {
+		t.Errorf("%s = %q, want replica-a", HeaderInstance, got)
+	}
+	var resp AgentRunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.InstanceHint == nil {
+		t.Fatal("instance_hint missing with sticky hints enabled")
+	}
+	want := svc.projectGraphID(root, "")
+	if resp.InstanceHint.Instance != "replica-a" || resp.InstanceHint.StickyKey != want || resp.InstanceHint.Header != HeaderStickyKey {
+		t.Errorf("instance_hint = %+v, want sticky_key %s", resp.InstanceHint, want)
+	}
+}
+
+func TestAgentHandlers_HandleAgentContinue_GraphNotInitializedHere(t *testing.T) {
+	session, _ := agent.NewSession("/test/project", nil)
+	session.SetGraphID("abc123")
+	session.Config.ToolRouterEnabled = false
+	mockLoop := &MockAgentLoop{
+		getSessionFunc: func(string) (*agent.Session, error) { return session, nil },
+	}
+	svc := NewService(DefaultServiceConfig())
+	continueReq := func(cfg InstanceConfig) *httptest.ResponseRecorder {
+		r := setupAgentTestRouter(NewAgentHandlers(mockLoop, svc, WithInstance(cfg)))
+		body, _ := json.Marshal(AgentContinueRequest{SessionID: session.ID, Clarification: "the auth one"})
+		req := httptest.NewRequest("POST", "/v1/trace/agent/continue", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Single instance: behavior unchanged.
+	if w := continueReq(InstanceConfig{}); w.Code != http.StatusOK {
+		t.Errorf("without shared sessions: status = %d, want 200", w.Code)
+	}
+
+	w := continueReq(InstanceConfig{ID: "replica-b", SharedSessions: true})
+	if w.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409; body %s", w.Code, w.Body.String())
+	}
+	var problem struct {
+		Code    string `json:"code"`
+		Detail  string `json:"detail"`
+		Details string `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if problem.Code != string(apierror.CodeGraphNotInitializedHere) {
+		t.Errorf("code = %q, want %s", problem.Code, apierror.CodeGraphNotInitializedHere)
+	}
+	if !strings.Contains(problem.Detail, "replica-b") || !strings.Contains(problem.Details, "/test/project") {
+		t.Errorf("problem does not name the instance and project root: %+v", problem)
+	}
+
+	// Once the graph is loaded here the session continues.
+	svc.graphs["abc123"] = &CachedGraph{Graph: graph.NewGraph("/test/project"), ProjectRoot: "/test/project"}
+	if w := continueReq(InstanceConfig{ID: "replica-b", SharedSessions: true}); w.Code != http.StatusOK {
+		t.Errorf("with graph loaded: status = %d, want 200; body %s", w.Code, w.Body.String())
+	}
+}
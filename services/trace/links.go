@@ -0,0 +1,546 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	posixpath "path"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/gin-gonic/gin"
+	"github.com/go-git/go-git/v5"
+)
+
+// ErrInvalidLinkTemplate indicates a link template that cannot be parsed.
+var ErrInvalidLinkTemplate = errors.New("invalid link template")
+
+// maxLinkTemplateLen bounds the length of a link template.
+const maxLinkTemplateLen = 2048
+
+// maxResponseLocations caps the locations collected from one tool result.
+const maxResponseLocations = 500
+
+// linkTemplateKey is the gin context key of a request's link template
+// override.
+const linkTemplateKey = "trace.link_template"
+
+// linkPlaceholders are the placeholders a link template may use.
+var linkPlaceholders = map[string]bool{
+	"path": true, "repo_path": true, "abs": true,
+	"line": true, "end_line": true, "sha": true,
+}
+
+// LinkTemplate renders code locations as URIs, for editor deep links or
+// links to a code host.
+//
+// Description:
+//
+//	A template is text with placeholders in braces:
+//
+//	  {path}      the file path relative to the project root
+//	  {repo_path} the file path relative to the git repository root
+//	  {abs}       the absolute file path, without its leading slash
+//	  {line}      the first line (1 when unknown)
+//	  {end_line}  the last line ({line} when unknown)
+//	  {sha}       the commit the graph was built from
+//
+//	Paths are slash-separated and each segment is percent-escaped, so
+//	"vscode://file/{abs}:{line}" and
+//	"https://github.com/org/repo/blob/{sha}/{repo_path}#L{line}-L{end_line}"
+//	both produce valid URIs. A template using {sha} renders nothing for a
+//	graph that is not in a git repository.
+//
+// Thread Safety: Immutable; safe for concurrent use.
+type LinkTemplate struct {
+	parts   []linkPart
+	usesSHA bool
+}
+
+// linkPart is a literal or a placeholder of a link template.
+type linkPart struct {
+	text        string
+	placeholder bool
+}
+
+// ParseLinkTemplate parses a link template.
+//
+// Inputs:
+//
+//	raw - The template, e.g. "vscode://file/{abs}:{line}".
+//
+// Outputs:
+//
+//	LinkTemplate - The parsed template. The zero value for an empty raw.
+//	error - Wraps ErrInvalidLinkTemplate for unbalanced braces, unknown
+//	  placeholders, a template without a path placeholder, or one longer
+//	  than 2048 bytes.
+func ParseLinkTemplate(raw string) (LinkTemplate, error) {
+	if raw == "" {
+		return LinkTemplate{}, nil
+	}
+	if len(raw) > maxLinkTemplateLen {
+		return LinkTemplate{}, fmt.Errorf("%w: longer than %d bytes", ErrInvalidLinkTemplate, maxLinkTemplateLen)
+	}
+	var t LinkTemplate
+	hasPath := false
+	rest := raw
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if closing := strings.IndexByte(rest, '}'); closing >= 0 && (open < 0 || closing < open) {
+			return LinkTemplate{}, fmt.Errorf("%w: unmatched }", ErrInvalidLinkTemplate)
+		}
+		if open < 0 {
+			t.parts = append(t.parts, linkPart{text: rest})
+			break
+		}
+		if open > 0 {
+			t.parts = append(t.parts, linkPart{text: rest[:open]})
+		}
+		end := strings.IndexByte(rest[open:], '}')
+		if end < 0 {
+			return LinkTemplate{}, fmt.Errorf("%w: unmatched {", ErrInvalidLinkTemplate)
+		}
+		name := rest[open+1 : open+end]
+		if !linkPlaceholders[name] {
+			return LinkTemplate{}, fmt.Errorf("%w: unknown placeholder {%s}", ErrInvalidLinkTemplate, name)
+		}
+		switch name {
+		case "path", "repo_path", "abs":
+			hasPath = true
+		case "sha":
+			t.usesSHA = true
+		}
+		t.parts = append(t.parts, linkPart{text: name, placeholder: true})
+		rest = rest[open+end+1:]
+	}
+	if !hasPath {
+		return LinkTemplate{}, fmt.Errorf("%w: needs {path}, {repo_path} or {abs}", ErrInvalidLinkTemplate)
+	}
+	return t, nil
+}
+
+// IsZero reports whether t is the empty template, which renders nothing.
+func (t LinkTemplate) IsZero() bool {
+	return len(t.parts) == 0
+}
+
+// linkTarget is what a template needs to know about a graph.
+type linkTarget struct {
+	// root is the absolute project root.
+	root string
+
+	// repoSubdir is the project root's path within its repository.
+	repoSubdir string
+
+	// sha is the commit the graph was built from, or "".
+	sha string
+}
+
+// render returns the URI of loc, or "" when t is empty or uses {sha} and
+// target has none.
+func (t LinkTemplate) render(loc CodeLocation, target linkTarget) string {
+	if t.IsZero() || loc.Path == "" || (t.usesSHA && target.sha == "") {
+		return ""
+	}
+	line := max(loc.StartLine, 1)
+	endLine := max(loc.EndLine, line)
+	var b strings.Builder
+	for _, part := range t.parts {
+		if !part.placeholder {
+			b.WriteString(part.text)
+			continue
+		}
+		switch part.text {
+		case "path":
+			b.WriteString(escapeLinkPath(loc.Path))
+		case "repo_path":
+			b.WriteString(escapeLinkPath(posixpath.Join(target.repoSubdir, filepath.ToSlash(loc.Path))))
+		case "abs":
+			abs := filepath.Join(target.root, filepath.FromSlash(loc.Path))
+			b.WriteString(escapeLinkPath(strings.TrimPrefix(filepath.ToSlash(abs), "/")))
+		case "line":
+			b.WriteString(strconv.Itoa(line))
+		case "end_line":
+			b.WriteString(strconv.Itoa(endLine))
+		case "sha":
+			b.WriteString(url.PathEscape(target.sha))
+		}
+	}
+	return b.String()
+}
+
+// escapeLinkPath percent-escapes each segment of a file path and joins
+// them with slashes.
+func escapeLinkPath(path string) string {
+	segments := strings.Split(filepath.ToSlash(path), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// detectGitHead returns the commit checked out at projectRoot and the
+// root's path within its repository, or empty strings when projectRoot
+// is not in a git working tree.
+func detectGitHead(projectRoot string) (sha, subdir string) {
+	repo, err := git.PlainOpenWithOptions(projectRoot, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return "", ""
+	}
+	head, err := repo.Head()
+	if err != nil {
+		// An unborn branch has no commit to link to.
+		return "", repoSubdir(repo, projectRoot)
+	}
+	return head.Hash().String(), repoSubdir(repo, projectRoot)
+}
+
+// linkTemplateOverride is middleware that parses the link_template query
+// parameter, which overrides the server's link template for the request.
+// An invalid template is answered with 400 INVALID_PARAMETER.
+func linkTemplateOverride(c *gin.Context) {
+	raw := c.Query("link_template")
+	if raw == "" {
+		c.Next()
+		return
+	}
+	tmpl, err := ParseLinkTemplate(raw)
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+		c.Abort()
+		return
+	}
+	c.Set(linkTemplateKey, tmpl)
+	c.Next()
+}
+
+// requestLinkTemplate returns the request's link template override, or
+// the server's template.
+func requestLinkTemplate(c *gin.Context, svc *Service) LinkTemplate {
+	if v, ok := c.Get(linkTemplateKey); ok {
+		if tmpl, ok := v.(LinkTemplate); ok {
+			return tmpl
+		}
+	}
+	if svc == nil {
+		return LinkTemplate{}
+	}
+	return svc.linkTemplate
+}
+
+// codeLinker builds the code locations of one graph for one request.
+//
+// Thread Safety: Safe for concurrent use.
+type codeLinker struct {
+	tmpl   LinkTemplate
+	target linkTarget
+}
+
+// newCodeLinker returns a linker rendering tmpl for cached's files.
+func newCodeLinker(cached *CachedGraph, tmpl LinkTemplate) *codeLinker {
+	sha := cached.CommitSHA
+	if sha == "" {
+		sha = cached.HeadSHA
+	}
+	return &codeLinker{
+		tmpl: tmpl,
+		target: linkTarget{
+			root:       cached.ProjectRoot,
+			repoSubdir: cached.RepoSubdir,
+			sha:        sha,
+		},
+	}
+}
+
+// location returns the location of lines start to end of path.
+func (l *codeLinker) location(path string, start, end int, symbolID string) CodeLocation {
+	loc := CodeLocation{Path: path, StartLine: start, EndLine: end, SymbolID: symbolID}
+	loc.URI = l.tmpl.render(loc, l.target)
+	return loc
+}
+
+// symbolLocation returns the location of sym.
+func (l *codeLinker) symbolLocation(sym *ast.Symbol) CodeLocation {
+	return l.location(sym.FilePath, sym.StartLine, sym.EndLine, sym.ID)
+}
+
+// locatable is a response item that carries its own CodeLocation.
+type locatable interface {
+	codeRange() (path string, start, end int, symbolID string)
+	setLocation(loc *CodeLocation)
+}
+
+func (s *SymbolInfo) codeRange() (string, int, int, string) {
+	return s.FilePath, s.StartLine, s.EndLine, s.ID
+}
+
+func (s *SymbolInfo) setLocation(loc *CodeLocation) { s.Location = loc }
+
+func (r *ReferenceInfo) codeRange() (string, int, int, string) {
+	return r.FilePath, r.Line, r.Line, ""
+}
+
+func (r *ReferenceInfo) setLocation(loc *CodeLocation) { r.Location = loc }
+
+// linkLocations sets the Location of every SymbolInfo and ReferenceInfo
+// reachable from resp.
+//
+// Description:
+//
+//	Used by the core endpoints, whose responses are built from these
+//	types. The link template is the request's override or the server's.
+//	Does nothing when the graph is gone.
+//
+// Inputs:
+//
+//	c - The request.
+//	graphID - The graph the response was built from.
+//	resp - A pointer to the response.
+func (h *Handlers) linkLocations(c *gin.Context, graphID string, resp any) {
+	cached, err := h.svc.GetGraph(graphID)
+	if err != nil {
+		return
+	}
+	linker := newCodeLinker(cached, requestLinkTemplate(c, h.svc))
+	linker.attach(reflect.ValueOf(resp), 0)
+}
+
+// attach sets the location of the locatable items reachable from v.
+func (l *codeLinker) attach(v reflect.Value, depth int) {
+	if depth > generatedFilterMaxDepth {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() && !isSharedGraphData(v) {
+			l.attach(v.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		if v.CanAddr() {
+			if item, ok := v.Addr().Interface().(locatable); ok {
+				path, start, end, id := item.codeRange()
+				if path != "" {
+					loc := l.location(path, start, end, id)
+					item.setLocation(&loc)
+				}
+			}
+		}
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				l.attach(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			l.attach(v.Index(i), depth+1)
+		}
+	}
+}
+
+// writeAgentic writes an explore, reason or patterns tool response with
+// the code locations its result refers to.
+func (h *Handlers) writeAgentic(c *gin.Context, cached *CachedGraph, resp AgenticResponse) {
+	linker := newCodeLinker(cached, requestLinkTemplate(c, h.svc))
+	resp.Locations = collectLocations(linker, cached.Index, resp.Result)
+	if banner := graphWarningBanner(cached.Graph); banner != "" {
+		resp.Warnings = append([]string{banner}, resp.Warnings...)
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// locationCollector gathers the code locations of a tool result.
+type locationCollector struct {
+	linker *codeLinker
+	idx    *index.SymbolIndex
+	seen   map[string]bool
+	out    []CodeLocation
+}
+
+// collectLocations returns the code locations result refers to.
+//
+// Description:
+//
+//	Tool results are package-specific types, so their items are found
+//	the way filterGenerated finds them: a struct naming a symbol by ID
+//	(ID, SymbolID, CallerID, TestID) yields the symbol's location; one
+//	with a file (FilePath or File) and a line (StartLine or Line, and
+//	EndLine) or a "file:line" Location yields that line. *ast.Symbol
+//	values yield their own location. Each location is reported once, in
+//	order of discovery, up to 500.
+//
+// Inputs:
+//
+//	linker - Renders the locations' URIs.
+//	idx - The graph's symbol index. May be nil (IDs are not resolved).
+//	result - The tool result.
+//
+// Outputs:
+//
+//	[]CodeLocation - The locations, nil if none.
+//
+// Thread Safety: Safe for concurrent use; result is only read.
+func collectLocations(linker *codeLinker, idx *index.SymbolIndex, result any) []CodeLocation {
+	if result == nil {
+		return nil
+	}
+	c := &locationCollector{linker: linker, idx: idx, seen: make(map[string]bool)}
+	c.walk(reflect.ValueOf(result), 0)
+	return c.out
+}
+
+// walk collects the locations reachable from v.
+func (c *locationCollector) walk(v reflect.Value, depth int) {
+	if depth > generatedFilterMaxDepth || len(c.out) >= maxResponseLocations {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		if v.CanInterface() {
+			if sym, ok := v.Interface().(*ast.Symbol); ok {
+				c.add(c.linker.symbolLocation(sym))
+				return
+			}
+		}
+		if !isSharedGraphData(v) {
+			c.walk(v.Elem(), depth+1)
+		}
+	case reflect.Struct:
+		c.addItem(v)
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				c.walk(v.Field(i), depth+1)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			c.walk(v.Index(i), depth+1)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			c.walk(iter.Value(), depth+1)
+		}
+	}
+}
+
+// addItem collects the location of a result item, if it has one.
+func (c *locationCollector) addItem(v reflect.Value) {
+	if c.idx != nil {
+		for _, name := range generatedIDFields {
+			if id := stringField(v, name); id != "" {
+				if sym, ok := c.idx.GetByID(id); ok {
+					c.add(c.linker.symbolLocation(sym))
+					return
+				}
+			}
+		}
+	}
+	path := stringField(v, "FilePath")
+	if path == "" {
+		path = stringField(v, "File")
+	}
+	start := intField(v, "StartLine")
+	if start == 0 {
+		start = intField(v, "Line")
+	}
+	if path == "" {
+		location := stringField(v, "Location")
+		path = locationFile(location)
+		if path == location {
+			return
+		}
+		start, _ = strconv.Atoi(strings.SplitN(location[len(path)+1:], ":", 2)[0])
+	}
+	if path != "" {
+		c.add(c.linker.location(path, start, intField(v, "EndLine"), ""))
+	}
+}
+
+// add appends loc unless an equal location was collected.
+func (c *locationCollector) add(loc CodeLocation) {
+	if loc.Path == "" || len(c.out) >= maxResponseLocations {
+		return
+	}
+	key := loc.Path + ":" + strconv.Itoa(loc.StartLine) + "-" + strconv.Itoa(loc.EndLine)
+	if c.seen[key] {
+		return
+	}
+	c.seen[key] = true
+	c.out = append(c.out, loc)
+}
+
+// stringField returns v's string field name, or "".
+func stringField(v reflect.Value, name string) string {
+	if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+// intField returns v's integer field name, or 0.
+func intField(v reflect.Value, name string) int {
+	if f := v.FieldByName(name); f.IsValid() && f.CanInt() {
+		return int(f.Int())
+	}
+	return 0
+}
+
+// linkCitations adds links to the verified citations of a run's answer.
+//
+// Description:
+//
+//	Sets the URI of each verified citation from the link template (the
+//	request's override or the server's) and rewrites its markers in the
+//	answer as markdown links (see agent.LinkCitations). Does nothing
+//	without a template, citations, or the session's graph.
+//
+// Inputs:
+//
+//	c - The request.
+//	session - The agent session.
+//	result - The run result, with citations validated by the loop.
+func (h *AgentHandlers) linkCitations(c *gin.Context, session *agent.Session, result *agent.RunResult) {
+	if result == nil || len(result.Citations) == 0 || h.svc == nil {
+		return
+	}
+	tmpl := requestLinkTemplate(c, h.svc)
+	if tmpl.IsZero() {
+		return
+	}
+	cached, err := h.svc.GetGraph(session.GetGraphID())
+	if err != nil {
+		return
+	}
+	linker := newCodeLinker(cached, tmpl)
+	for i := range result.Citations {
+		citation := &result.Citations[i]
+		citation.URI = linker.location(citation.File, citation.Line, 0, citation.SymbolID).URI
+	}
+	result.Response = agent.LinkCitations(result.Response, result.Citations)
+}
@@ -0,0 +1,225 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/gin-gonic/gin"
+	"github.com/go-git/go-git/v5"
+)
+
+func TestLinkTemplate_Render(t *testing.T) {
+	target := linkTarget{root: "/home/me/my proj", repoSubdir: "svc/api", sha: "3f9c0d1"}
+	loc := CodeLocation{Path: "pkg/a b/c#1.go", StartLine: 12, EndLine: 40}
+	tests := []struct {
+		name     string
+		template string
+		loc      CodeLocation
+		target   linkTarget
+		want     string
+	}{
+		{
+			name:     "editor scheme",
+			template: "vscode://file/{abs}:{line}",
+			loc:      loc, target: target,
+			want: "vscode://file/home/me/my%20proj/pkg/a%20b/c%231.go:12",
+		},
+		{
+			name:     "web url with sha",
+			template: "https://github.com/org/repo/blob/{sha}/{repo_path}#L{line}-L{end_line}",
+			loc:      loc, target: target,
+			want: "https://github.com/org/repo/blob/3f9c0d1/svc/api/pkg/a%20b/c%231.go#L12-L40",
+		},
+		{
+			name:     "project path and unknown lines",
+			template: "idea://open?file={path}&line={line}&end={end_line}",
+			loc:      CodeLocation{Path: "docs/ü.md"}, target: target,
+			want: "idea://open?file=docs/%C3%BC.md&line=1&end=1",
+		},
+		{
+			name:     "sha outside a repository",
+			template: "https://example.com/{sha}/{path}",
+			loc:      loc, target: linkTarget{root: "/src"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseLinkTemplate(tt.template)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := tmpl.render(tt.loc, tt.target); got != tt.want {
+				t.Errorf("render = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLinkTemplate_Invalid(t *testing.T) {
+	for _, raw := range []string{
+		"vscode://file/{abs:{line}",
+		"vscode://file/abs}:{line}",
+		"https://x/{commit}/{path}",
+		"https://x/#L{line}",
+		"https://x/" + strings.Repeat("a", maxLinkTemplateLen) + "{path}",
+	} {
+		if _, err := ParseLinkTemplate(raw); !errors.Is(err, ErrInvalidLinkTemplate) {
+			t.Errorf("ParseLinkTemplate(%q) error = %v, want ErrInvalidLinkTemplate", raw, err)
+		}
+	}
+	if tmpl, err := ParseLinkTemplate(""); err != nil || !tmpl.IsZero() {
+		t.Errorf("empty template = %+v, %v; want zero template", tmpl, err)
+	}
+}
+
+func TestCollectLocations(t *testing.T) {
+	sym := &ast.Symbol{
+		ID: "store/db.go:41:Save", Name: "Save", Kind: ast.SymbolKindFunction,
+		FilePath: "store/db.go", StartLine: 41, EndLine: 60, Language: "go",
+	}
+	idx := index.NewSymbolIndex()
+	if err := idx.Add(sym); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := ParseLinkTemplate("editor://{path}:{line}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	linker := &codeLinker{tmpl: tmpl, target: linkTarget{root: "/p"}}
+
+	type hit struct {
+		SymbolID string
+		FilePath string
+		Line     int
+		Location string
+	}
+	result := &struct {
+		Hits    []hit
+		ByName  map[string]*hit
+		Symbols []*ast.Symbol
+	}{
+		Hits: []hit{
+			{SymbolID: sym.ID},
+			{FilePath: "main.go", Line: 7},
+			{Location: "cmd/run.go:12:3"},
+			{SymbolID: "unknown", FilePath: "main.go", Line: 7},
+		},
+		ByName:  map[string]*hit{"x": {FilePath: "x.go", Line: 2}},
+		Symbols: []*ast.Symbol{sym},
+	}
+
+	got := collectLocations(linker, idx, result)
+	want := []CodeLocation{
+		{Path: "store/db.go", StartLine: 41, EndLine: 60, URI: "editor://store/db.go:41", SymbolID: sym.ID},
+		{Path: "main.go", StartLine: 7, URI: "editor://main.go:7"},
+		{Path: "cmd/run.go", StartLine: 12, URI: "editor://cmd/run.go:12"},
+		{Path: "x.go", StartLine: 2, URI: "editor://x.go:2"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("locations = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("location %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLinks_Endpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha := commitFiles(t, repo, dir, map[string]string{
+		"my pkg/main.go": "package main\n\nfunc helper() {}\n\nfunc main() {\n\thelper()\n}\n",
+	})
+
+	cfg := DefaultServiceConfig()
+	cfg.LinkTemplate = "vscode://file/{abs}:{line}"
+	svc := NewService(cfg)
+	initResp, err := svc.Init(context.Background(), dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	router := setupTestRouter(svc)
+	callers := "/v1/trace/callers?graph_id=" + initResp.GraphID + "&function=helper"
+
+	t.Run("server template", func(t *testing.T) {
+		w := cacheGet(router, callers, "")
+		var resp CallersResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Callers) != 1 {
+			t.Fatalf("status %d: %s", w.Code, w.Body.String())
+		}
+		loc := resp.Callers[0].Location
+		abs := strings.TrimPrefix(filepath.ToSlash(filepath.Join(dir, "my pkg", "main.go")), "/")
+		if loc == nil || loc.Path != "my pkg/main.go" || loc.StartLine != 5 || loc.URI != "vscode://file/"+escapeLinkPath(abs)+":5" {
+			t.Errorf("location = %+v", loc)
+		}
+	})
+
+	t.Run("request override", func(t *testing.T) {
+		tmpl := "https://github.com/org/repo/blob/{sha}/{repo_path}#L{line}"
+		w := cacheGet(router, "/v1/trace/references?graph_id="+initResp.GraphID+"&symbol=helper&link_template="+url.QueryEscape(tmpl), "")
+		var resp ReferencesResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.References) == 0 {
+			t.Fatalf("status %d: %s", w.Code, w.Body.String())
+		}
+		want := "https://github.com/org/repo/blob/" + sha + "/my%20pkg/main.go#L6"
+		if loc := resp.References[0].Location; loc == nil || loc.URI != want {
+			t.Errorf("location = %+v, want uri %s", loc, want)
+		}
+		if w.Header().Get("ETag") != "" {
+			t.Error("response with a link_template override has an ETag")
+		}
+	})
+
+	t.Run("explore locations", func(t *testing.T) {
+		body := strings.NewReader(`{"graph_id": "` + initResp.GraphID + `"}`)
+		req := httptest.NewRequest(http.MethodPost, "/v1/trace/explore/entry_points?link_template="+url.QueryEscape("{path}#{line}"), body)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp AgenticResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || w.Code != http.StatusOK {
+			t.Fatalf("status %d: %s", w.Code, w.Body.String())
+		}
+		found := false
+		for _, loc := range resp.Locations {
+			if loc.URI == "my%20pkg/main.go#5" && loc.SymbolID != "" {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("locations = %+v, want main's", resp.Locations)
+		}
+	})
+
+	t.Run("invalid override", func(t *testing.T) {
+		w := cacheGet(router, callers+"&link_template="+url.QueryEscape("x://{nope}"), "")
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID_PARAMETER") {
+			t.Errorf("status %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
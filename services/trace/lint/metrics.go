@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -98,7 +99,7 @@ func startLintSpan(ctx context.Context, language, filePath string) (context.Cont
 	return tracer.Start(ctx, "LintRunner.Lint",
 		trace.WithAttributes(
 			attribute.String("lint.language", language),
-			attribute.String("lint.file_path", filePath),
+			telemetry.SensitiveString("lint.file_path", filePath),
 		),
 	)
 }
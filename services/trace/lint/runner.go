@@ -21,6 +21,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -304,7 +306,7 @@ func (r *LintRunner) LintWithLanguage(ctx context.Context, filePath, language st
 	recordLintMetrics(ctx, language, time.Since(start), len(errors), len(warnings), true)
 
 	slog.Debug("Lint completed",
-		slog.String("file", filePath),
+		telemetry.SensitiveLog("file", filePath),
 		slog.String("linter", config.Command),
 		slog.Duration("duration", result.Duration),
 		slog.Int("errors", len(errors)),
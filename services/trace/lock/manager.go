@@ -23,6 +23,8 @@ import (
 	"time"
 
 	"github.com/fsnotify/fsnotify"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // FileLockManager manages file locks for safe concurrent file operations.
@@ -109,7 +111,7 @@ func NewFileLockManager(config ManagerConfig) (*FileLockManager, error) {
 		cleaned, err := m.CleanupStaleLocks()
 		if err != nil {
 			slog.Warn("Failed to cleanup stale locks on init",
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 		} else if cleaned > 0 {
 			slog.Info("Cleaned up stale locks on init",
 				"count", cleaned)
@@ -282,7 +284,7 @@ func (m *FileLockManager) releaseLockEntry(absPath string, entry *lockEntry) err
 		if err := m.locker.Unlock(f); err != nil {
 			slog.Warn("Failed to unlock file",
 				"path", absPath,
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 		}
 		f.Close()
 	}
@@ -291,7 +293,7 @@ func (m *FileLockManager) releaseLockEntry(absPath string, entry *lockEntry) err
 	if err := os.Remove(entry.lockPath); err != nil && !os.IsNotExist(err) {
 		slog.Warn("Failed to remove lock file",
 			"path", entry.lockPath,
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 	}
 
 	// Remove from our map
@@ -409,7 +411,7 @@ func (m *FileLockManager) CleanupStaleLocks() (int, error) {
 		if err != nil {
 			slog.Warn("Failed to read lock info",
 				"path", lockPath,
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 			continue
 		}
 
@@ -426,7 +428,7 @@ func (m *FileLockManager) CleanupStaleLocks() (int, error) {
 			if err := os.Remove(lockPath); err != nil {
 				slog.Warn("Failed to remove stale lock",
 					"path", lockPath,
-					"error", err)
+					telemetry.SensitiveLogError("error", err))
 			} else {
 				cleaned++
 			}
@@ -470,7 +472,7 @@ func (m *FileLockManager) Close() error {
 	// Release all locks
 	if err := m.ReleaseAll(); err != nil {
 		slog.Warn("Error releasing locks during close",
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 	}
 
 	// Close watcher
@@ -529,7 +531,7 @@ func (m *FileLockManager) addWatch(path string) {
 	if err := m.watcher.Add(path); err != nil {
 		slog.Warn("Failed to watch file",
 			"path", path,
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 	}
 }
 
@@ -565,7 +567,7 @@ func (m *FileLockManager) watchLoop() {
 				return
 			}
 			slog.Warn("File watcher error",
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 		}
 	}
 }
@@ -0,0 +1,211 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// ContextKeyAPIKeyID is the gin context key an authentication middleware
+// sets to the ID of the API key that authenticated the request. The access
+// log reports it as api_key_id.
+const ContextKeyAPIKeyID = "api_key_id"
+
+// headerTraceID carries the request's trace ID back to the client.
+const headerTraceID = "X-Trace-ID"
+
+// probePaths are logged at DEBUG when they succeed.
+var probePaths = map[string]bool{
+	"/v1/trace/health":       true,
+	"/v1/trace/health/live":  true,
+	"/v1/trace/health/ready": true,
+	"/v1/trace/ready":        true,
+	"/v1/metrics":            true,
+}
+
+// AccessLogMiddleware logs one record per request and makes the request's
+// trace ID available to every log line emitted while handling it.
+//
+// Description:
+//
+//	Must run after the OTel middleware. The trace ID is the active span's;
+//	when there is none (no trace headers and OTel export disabled) a random
+//	one is generated so requests can still be correlated. It is attached
+//	to the request context with telemetry.ContextWithLogAttrs and returned
+//	in the X-Trace-ID header.
+//
+//	The record has method, path, status, latency_ms, bytes, api_key_id and
+//	trace_id. api_key_id is taken from ContextKeyAPIKeyID if set, else it
+//	is a short hash of the X-API-Key header or bearer token; the key itself
+//	is never logged. Server errors log at ERROR, client errors at WARN,
+//	successful health probes and metrics scrapes at DEBUG, and the rest at
+//	INFO.
+//
+//	Request paths can carry symbol IDs. Unless the telemetry privacy mode
+//	is full, path is logged through telemetry.SensitiveLog with the route
+//	template beside it, and the OTel server span's url.path is replaced
+//	by the route template.
+//
+// Outputs:
+//
+//	gin.HandlerFunc - The middleware.
+//
+// Thread Safety: Safe for concurrent use.
+func AccessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		ctx := c.Request.Context()
+
+		traceID := telemetry.TraceID(ctx)
+		if traceID == "" {
+			traceID = newTraceID()
+			ctx = telemetry.ContextWithLogAttrs(ctx, slog.String("trace_id", traceID))
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Header(headerTraceID, traceID)
+		private := telemetry.CurrentPrivacyMode() != telemetry.PrivacyFull
+		if private {
+			oteltrace.SpanFromContext(ctx).SetAttributes(attribute.String("url.path", c.FullPath()))
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case probePaths[c.Request.URL.Path]:
+			// Health probes and scrapes would drown out real traffic.
+			level = slog.LevelDebug
+		}
+
+		path := c.Request.URL.Path
+		if raw := c.Request.URL.RawQuery; raw != "" {
+			path += "?" + raw
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			telemetry.SensitiveLog("path", path),
+			slog.Int("status", status),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("api_key_id", apiKeyID(c)),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if private {
+			attrs = append(attrs, slog.String("route", c.FullPath()))
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, telemetry.SensitiveLog("error", c.Errors.String()))
+		}
+		slog.LogAttrs(ctx, level, "HTTP request", attrs...)
+	}
+}
+
+// apiKeyID identifies the API key of a request without revealing it.
+func apiKeyID(c *gin.Context) string {
+	if id := c.GetString(ContextKeyAPIKeyID); id != "" {
+		return id
+	}
+	key := c.GetHeader("X-API-Key")
+	if key == "" {
+		if auth := c.GetHeader("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			key = strings.TrimPrefix(auth, "Bearer ")
+		}
+	}
+	if key == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "key_" + hex.EncodeToString(sum[:6])
+}
+
+// newTraceID returns a random W3C-format trace ID.
+func newTraceID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// HandleGetLogLevel handles GET /v1/trace/admin/loglevel.
+//
+// Description:
+//
+//	Returns the current process-wide log level.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleGetLogLevel(c *gin.Context) {
+	c.JSON(http.StatusOK, LogLevelResponse{
+		Level: strings.ToLower(telemetry.LogLevel().String()),
+	})
+}
+
+// HandleSetLogLevel handles POST /v1/trace/admin/loglevel.
+//
+// Description:
+//
+//	Changes the process-wide log level without a restart. Takes effect
+//	immediately for every logger, including running agent sessions. The
+//	change is not persisted; a restart uses -log-level again.
+//
+// Request Body:
+//
+//	LogLevelRequest - {"level": "debug" | "info" | "warn" | "error"}
+//
+// Response:
+//
+//	200 OK: LogLevelResponse with the new and previous level
+//	400 Bad Request: Missing or unknown level
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleSetLogLevel(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSetLogLevel")
+
+	var req LogLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body: level is required")
+		return
+	}
+	level, err := telemetry.ParseLogLevel(req.Level)
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	previous := telemetry.SetLogLevel(level)
+	// Logged at WARN so the change is recorded whatever the new level.
+	logger.Warn("Log level changed",
+		"level", level.String(),
+		"previous", previous.String(),
+	)
+	c.JSON(http.StatusOK, LogLevelResponse{
+		Level:    strings.ToLower(level.String()),
+		Previous: strings.ToLower(previous.String()),
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// captureLogs routes the default logger to a JSON buffer for the test.
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(telemetry.NewContextHandler(
+		slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}),
+	)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// logRecords returns the JSON records in buf with the given message.
+func logRecords(t *testing.T, buf *bytes.Buffer, msg string) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", line, err)
+		}
+		if rec["msg"] == msg {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+func setupAccessLogRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AccessLogMiddleware())
+	RegisterRoutes(router.Group("/v1"), NewHandlers(NewService(DefaultServiceConfig())))
+	return router
+}
+
+func TestAccessLogMiddleware(t *testing.T) {
+	buf := captureLogs(t)
+	router := setupAccessLogRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/trace/admin/loglevel?x=1", nil)
+	req.Header.Set("X-API-Key", "secret-key-value")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d", w.Code)
+	}
+
+	traceID := w.Header().Get("X-Trace-ID")
+	if len(traceID) != 32 {
+		t.Fatalf("X-Trace-ID = %q, want 32 hex chars", traceID)
+	}
+	records := logRecords(t, buf, "HTTP request")
+	if len(records) != 1 {
+		t.Fatalf("got %d access log records, want 1", len(records))
+	}
+	rec := records[0]
+	if rec["method"] != "GET" || rec["path"] != "/v1/trace/admin/loglevel?x=1" || rec["status"] != float64(200) {
+		t.Errorf("record = %v", rec)
+	}
+	if rec["bytes"] != float64(w.Body.Len()) {
+		t.Errorf("bytes = %v, want %d", rec["bytes"], w.Body.Len())
+	}
+	if rec["trace_id"] != traceID {
+		t.Errorf("trace_id = %v, want %s", rec["trace_id"], traceID)
+	}
+	if _, ok := rec["latency_ms"]; !ok {
+		t.Error("missing latency_ms")
+	}
+	keyID, _ := rec["api_key_id"].(string)
+	if !strings.HasPrefix(keyID, "key_") || strings.Contains(buf.String(), "secret-key-value") {
+		t.Errorf("api_key_id = %q; the raw key must not be logged", keyID)
+	}
+}
+
+func TestAccessLogMiddleware_HandlerLogsShareTraceID(t *testing.T) {
+	buf := captureLogs(t)
+	router := setupAccessLogRouter()
+	t.Cleanup(func() { telemetry.SetLogLevel(slog.LevelInfo) })
+
+	w := postJSON(router, "/v1/trace/admin/loglevel", `{"level":"debug"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	traceID := w.Header().Get("X-Trace-ID")
+	handlerRecords := logRecords(t, buf, "Log level changed")
+	if len(handlerRecords) != 1 {
+		t.Fatalf("got %d handler records, want 1", len(handlerRecords))
+	}
+	if handlerRecords[0]["trace_id"] != traceID {
+		t.Errorf("handler trace_id = %v, want %s", handlerRecords[0]["trace_id"], traceID)
+	}
+}
+
+func TestHandleSetLogLevel(t *testing.T) {
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+	t.Cleanup(func() { telemetry.SetLogLevel(slog.LevelInfo) })
+	telemetry.SetLogLevel(slog.LevelInfo)
+
+	for _, body := range []string{`{}`, `{"level":"verbose"}`} {
+		if w := postJSON(router, "/v1/trace/admin/loglevel", body); w.Code != http.StatusBadRequest {
+			t.Errorf("POST %s status = %d, want 400", body, w.Code)
+		}
+	}
+
+	w := postJSON(router, "/v1/trace/admin/loglevel", `{"level":"WARN"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp LogLevelResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Level != "warn" || resp.Previous != "info" {
+		t.Errorf("response = %+v, want level warn, previous info", resp)
+	}
+	if telemetry.LogLevel() != slog.LevelWarn {
+		t.Errorf("LogLevel = %v, want WARN", telemetry.LogLevel())
+	}
+
+	get := cacheGet(router, "/v1/trace/admin/loglevel", "")
+	if !strings.Contains(get.Body.String(), `"level":"warn"`) {
+		t.Errorf("GET body = %s", get.Body.String())
+	}
+}
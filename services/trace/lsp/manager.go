@@ -17,6 +17,8 @@ import (
 	"os/exec"
 	"sync"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -450,8 +452,8 @@ func (m *Manager) ReleaseFile(ctx context.Context, filePath string) error {
 		if err := srv.Notify("textDocument/didClose", params); err != nil {
 			slog.Warn("Failed to send didClose to LSP server",
 				slog.String("language", srv.Language()),
-				slog.String("file", filePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("file", filePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 			lastErr = err
 		}
@@ -520,8 +522,8 @@ func (m *Manager) ReopenFile(ctx context.Context, filePath string, content strin
 		if err := srv.Notify("textDocument/didOpen", params); err != nil {
 			slog.Warn("Failed to send didOpen to LSP server",
 				slog.String("language", srv.Language()),
-				slog.String("file", filePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("file", filePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 			lastErr = err
 		}
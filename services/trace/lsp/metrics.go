@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -89,7 +90,7 @@ func startOperationSpan(ctx context.Context, operation, language, filePath strin
 		trace.WithAttributes(
 			attribute.String("lsp.operation", operation),
 			attribute.String("lsp.language", language),
-			attribute.String("lsp.file_path", filePath),
+			telemetry.SensitiveString("lsp.file_path", filePath),
 		),
 	)
 }
@@ -20,6 +20,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -204,7 +206,7 @@ func (o *Operations) requestWithRetry(
 				slog.Debug("Retrying LSP request after server error",
 					slog.String("language", language),
 					slog.Int("attempt", attempt+1),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				time.Sleep(retryDelay)
 				continue
@@ -219,7 +221,7 @@ func (o *Operations) requestWithRetry(
 				slog.Debug("Retrying LSP request after transient error",
 					slog.String("language", language),
 					slog.Int("attempt", attempt+1),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				time.Sleep(retryDelay)
 				continue
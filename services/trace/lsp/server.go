@@ -20,6 +20,8 @@ import (
 	"os/exec"
 	"sync"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -169,7 +171,7 @@ func (s *Server) Start(ctx context.Context) error {
 	slog.Info("Starting LSP server",
 		slog.String("language", s.config.Language),
 		slog.String("command", path),
-		slog.String("root_path", s.rootPath),
+		telemetry.SensitiveLog("root_path", s.rootPath),
 	)
 
 	// Create server context (independent of caller's context)
@@ -157,6 +157,21 @@ type DidChangeTextDocumentParams struct {
 	ContentChanges []TextDocumentContentChangeEvent `json:"contentChanges"`
 }
 
+// DidSaveTextDocumentParams contains params for textDocument/didSave.
+type DidSaveTextDocumentParams struct {
+	// TextDocument is the document that was saved.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+
+	// Text is the content when saved, if the client includes it.
+	Text *string `json:"text,omitempty"`
+}
+
+// DocumentSymbolParams contains params for textDocument/documentSymbol.
+type DocumentSymbolParams struct {
+	// TextDocument is the document to list symbols for.
+	TextDocument TextDocumentIdentifier `json:"textDocument"`
+}
+
 // TextDocumentContentChangeEvent describes a content change event.
 type TextDocumentContentChangeEvent struct {
 	// Range is the range that got replaced. Omit for full document sync.
@@ -236,6 +251,28 @@ type SymbolInformation struct {
 	ContainerName string `json:"containerName,omitempty"`
 }
 
+// DocumentSymbol represents a symbol in a document, with its children.
+type DocumentSymbol struct {
+	// Name is the symbol's name.
+	Name string `json:"name"`
+
+	// Detail is additional information, such as a signature.
+	Detail string `json:"detail,omitempty"`
+
+	// Kind is the symbol kind (function, class, etc.).
+	Kind SymbolKind `json:"kind"`
+
+	// Range encloses the whole symbol, including its body.
+	Range Range `json:"range"`
+
+	// SelectionRange is the range to reveal, typically the name.
+	// Must be contained in Range.
+	SelectionRange Range `json:"selectionRange"`
+
+	// Children are the symbols nested inside this one.
+	Children []DocumentSymbol `json:"children,omitempty"`
+}
+
 // SymbolKind represents the kind of a symbol.
 type SymbolKind int
 
@@ -459,6 +496,9 @@ type ServerCapabilities struct {
 
 	// WorkspaceSymbolProvider indicates workspace/symbol is supported.
 	WorkspaceSymbolProvider interface{} `json:"workspaceSymbolProvider,omitempty"`
+
+	// DocumentSymbolProvider indicates textDocument/documentSymbol is supported.
+	DocumentSymbolProvider interface{} `json:"documentSymbolProvider,omitempty"`
 }
 
 // HasDefinitionProvider returns true if definition is supported.
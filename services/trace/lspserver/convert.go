@@ -0,0 +1,326 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lspserver
+
+import (
+	"math"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lsp"
+)
+
+// Positions
+//
+// Graph symbols use 1-indexed lines and 0-indexed byte columns; LSP uses
+// 0-indexed lines and UTF-16 columns. Lines are converted exactly;
+// columns are passed through, which is exact for ASCII source.
+
+// fileCache converts graph locations to LSP locations, reading each file
+// at most once per request to find symbol names.
+type fileCache struct {
+	sess  *session
+	root  string
+	lines map[string][]string
+}
+
+// newFileCache creates a fileCache for graph paths relative to root.
+func newFileCache(sess *session, root string) *fileCache {
+	return &fileCache{sess: sess, root: root, lines: make(map[string][]string)}
+}
+
+// linesOf returns the lines of the graph file relPath, or nil if the file
+// cannot be read.
+func (f *fileCache) linesOf(relPath string) []string {
+	if lines, ok := f.lines[relPath]; ok {
+		return lines
+	}
+	var lines []string
+	if text, ok := f.sess.documentText(filepath.Join(f.root, relPath)); ok {
+		lines = strings.Split(text, "\n")
+	}
+	f.lines[relPath] = lines
+	return lines
+}
+
+// uri returns the file URI of the graph file relPath.
+func (f *fileCache) uri(relPath string) string {
+	return pathToURI(filepath.Join(f.root, relPath))
+}
+
+// symbolLocation returns the location of sym's name.
+func (f *fileCache) symbolLocation(sym *ast.Symbol) lsp.Location {
+	return lsp.Location{URI: f.uri(sym.FilePath), Range: f.selectionRange(sym)}
+}
+
+// selectionRange returns the range of sym's name: its first occurrence on
+// the start line at or after the start column. Falls back to the start of
+// the symbol when the name is not found within the symbol's range.
+func (f *fileCache) selectionRange(sym *ast.Symbol) lsp.Range {
+	full := symbolRange(sym)
+	fallback := lsp.Range{Start: full.Start, End: full.Start}
+
+	lines := f.linesOf(sym.FilePath)
+	if sym.Name == "" || sym.StartLine < 1 || sym.StartLine > len(lines) {
+		return fallback
+	}
+	line := strings.TrimSuffix(lines[sym.StartLine-1], "\r")
+	if sym.StartCol > len(line) {
+		return fallback
+	}
+	idx := strings.Index(line[sym.StartCol:], sym.Name)
+	if idx < 0 {
+		return fallback
+	}
+	start := lsp.Position{Line: sym.StartLine - 1, Character: sym.StartCol + idx}
+	sel := lsp.Range{Start: start, End: lsp.Position{Line: start.Line, Character: start.Character + len(sym.Name)}}
+	if !rangeContains(full, sel) {
+		return fallback
+	}
+	return sel
+}
+
+// edgeLocation returns where an edge is expressed, or the location of the
+// source symbol when the edge has no location.
+func (f *fileCache) edgeLocation(edge *graph.Edge, from *ast.Symbol) lsp.Location {
+	loc := edge.Location
+	if loc.FilePath == "" && from != nil {
+		return f.symbolLocation(from)
+	}
+	start := lsp.Position{Line: max(loc.StartLine-1, 0), Character: loc.StartCol}
+	end := lsp.Position{Line: max(loc.EndLine-1, 0), Character: loc.EndCol}
+	if positionBefore(end, start) {
+		end = start
+	}
+	return lsp.Location{URI: f.uri(loc.FilePath), Range: lsp.Range{Start: start, End: end}}
+}
+
+// symbolRange returns the full source range of sym.
+func symbolRange(sym *ast.Symbol) lsp.Range {
+	start := lsp.Position{Line: max(sym.StartLine-1, 0), Character: sym.StartCol}
+	end := lsp.Position{Line: max(sym.EndLine-1, 0), Character: sym.EndCol}
+	if positionBefore(end, start) {
+		end = start
+	}
+	return lsp.Range{Start: start, End: end}
+}
+
+// rangeContains reports whether inner lies within outer.
+func rangeContains(outer, inner lsp.Range) bool {
+	return !positionBefore(inner.Start, outer.Start) && !positionBefore(outer.End, inner.End)
+}
+
+// positionBefore reports whether a is strictly before b.
+func positionBefore(a, b lsp.Position) bool {
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Character < b.Character
+}
+
+// outlineEntry is a symbol with the symbols nested inside it.
+type outlineEntry struct {
+	sym      *ast.Symbol
+	children []*outlineEntry
+}
+
+// buildOutline nests the symbols of one file by source range.
+//
+// Description:
+//
+//	nodes must be in the order of graph.Graph.SymbolsInFile, where a
+//	symbol precedes the symbols nested inside it. Each symbol becomes a
+//	child of the nearest preceding symbol whose range encloses it.
+//	Symbols excluded by includeInOutline are skipped.
+func buildOutline(nodes []*graph.Node, files *fileCache) []lsp.DocumentSymbol {
+	var roots []*outlineEntry
+	var stack []*outlineEntry
+	for _, node := range nodes {
+		if node.Symbol == nil || !includeInOutline(node.Symbol) {
+			continue
+		}
+		entry := &outlineEntry{sym: node.Symbol}
+		for len(stack) > 0 && !encloses(stack[len(stack)-1].sym, entry.sym) {
+			stack = stack[:len(stack)-1]
+		}
+		if len(stack) == 0 {
+			roots = append(roots, entry)
+		} else {
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, entry)
+		}
+		stack = append(stack, entry)
+	}
+	return toDocumentSymbols(roots, files)
+}
+
+// toDocumentSymbols converts outline entries to LSP document symbols.
+func toDocumentSymbols(entries []*outlineEntry, files *fileCache) []lsp.DocumentSymbol {
+	result := make([]lsp.DocumentSymbol, 0, len(entries))
+	for _, entry := range entries {
+		detail, _, _ := strings.Cut(entry.sym.Signature, "\n")
+		ds := lsp.DocumentSymbol{
+			Name:           entry.sym.Name,
+			Detail:         strings.TrimSpace(detail),
+			Kind:           symbolKind(entry.sym.Kind),
+			Range:          symbolRange(entry.sym),
+			SelectionRange: files.selectionRange(entry.sym),
+		}
+		if len(entry.children) > 0 {
+			ds.Children = toDocumentSymbols(entry.children, files)
+		}
+		result = append(result, ds)
+	}
+	return result
+}
+
+// encloses reports whether outer's source range contains inner's. An end
+// column of zero is treated as the end of the line.
+func encloses(outer, inner *ast.Symbol) bool {
+	endOf := func(sym *ast.Symbol) (int, int) {
+		line, col := sym.EndLine, sym.EndCol
+		if line < sym.StartLine {
+			line = sym.StartLine
+		}
+		if col == 0 {
+			col = math.MaxInt
+		}
+		return line, col
+	}
+	if inner.StartLine < outer.StartLine ||
+		(inner.StartLine == outer.StartLine && inner.StartCol < outer.StartCol) {
+		return false
+	}
+	outerLine, outerCol := endOf(outer)
+	innerLine, innerCol := endOf(inner)
+	return innerLine < outerLine || (innerLine == outerLine && innerCol <= outerCol)
+}
+
+// includeInOutline reports whether sym is listed by documentSymbol and
+// workspace/symbol. Imports and parameters are noise in an outline.
+func includeInOutline(sym *ast.Symbol) bool {
+	if sym.Name == "" {
+		return false
+	}
+	switch sym.Kind {
+	case ast.SymbolKindImport, ast.SymbolKindParameter, ast.SymbolKindUnknown:
+		return false
+	}
+	return true
+}
+
+// symbolKinds maps graph symbol kinds to LSP symbol kinds. Kinds not
+// listed map to SymbolKindVariable.
+var symbolKinds = map[ast.SymbolKind]lsp.SymbolKind{
+	ast.SymbolKindPackage:    lsp.SymbolKindPackage,
+	ast.SymbolKindFile:       lsp.SymbolKindFile,
+	ast.SymbolKindFunction:   lsp.SymbolKindFunction,
+	ast.SymbolKindMethod:     lsp.SymbolKindMethod,
+	ast.SymbolKindInterface:  lsp.SymbolKindInterface,
+	ast.SymbolKindStruct:     lsp.SymbolKindStruct,
+	ast.SymbolKindType:       lsp.SymbolKindClass,
+	ast.SymbolKindVariable:   lsp.SymbolKindVariable,
+	ast.SymbolKindConstant:   lsp.SymbolKindConstant,
+	ast.SymbolKindField:      lsp.SymbolKindField,
+	ast.SymbolKindClass:      lsp.SymbolKindClass,
+	ast.SymbolKindDecorator:  lsp.SymbolKindFunction,
+	ast.SymbolKindEnum:       lsp.SymbolKindEnum,
+	ast.SymbolKindEnumMember: lsp.SymbolKindEnumMember,
+	ast.SymbolKindProperty:   lsp.SymbolKindProperty,
+	ast.SymbolKindComponent:  lsp.SymbolKindClass,
+	ast.SymbolKindTable:      lsp.SymbolKindStruct,
+	ast.SymbolKindColumn:     lsp.SymbolKindField,
+	ast.SymbolKindView:       lsp.SymbolKindStruct,
+	ast.SymbolKindIndex:      lsp.SymbolKindKey,
+	ast.SymbolKindTrigger:    lsp.SymbolKindEvent,
+	ast.SymbolKindProcedure:  lsp.SymbolKindFunction,
+	ast.SymbolKindSchema:     lsp.SymbolKindNamespace,
+}
+
+// symbolKind returns the LSP kind for a graph symbol kind.
+func symbolKind(kind ast.SymbolKind) lsp.SymbolKind {
+	if k, ok := symbolKinds[kind]; ok {
+		return k
+	}
+	return lsp.SymbolKindVariable
+}
+
+// containerName returns the name shown next to a workspace symbol: the
+// receiver of a method, the parent of a nested symbol, or the package.
+func containerName(sym *ast.Symbol) string {
+	if sym.Receiver != "" {
+		return sym.Receiver
+	}
+	if sym.Metadata != nil && sym.Metadata.ParentName != "" {
+		return sym.Metadata.ParentName
+	}
+	return sym.Package
+}
+
+// pathToURI converts an absolute path to a file URI.
+func pathToURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// lineAt returns the 0-indexed line n of text, or "" if out of range.
+func lineAt(text string, n int) string {
+	for i := 0; i < n; i++ {
+		idx := strings.IndexByte(text, '\n')
+		if idx < 0 {
+			return ""
+		}
+		text = text[idx+1:]
+	}
+	if idx := strings.IndexByte(text, '\n'); idx >= 0 {
+		text = text[:idx]
+	}
+	return strings.TrimSuffix(text, "\r")
+}
+
+// identifierAt returns the identifier touching column col of line, or ""
+// if there is none. A cursor just after an identifier selects it, as
+// editors place the cursor there after typing a name.
+func identifierAt(line string, col int) string {
+	if col > len(line) {
+		col = len(line)
+	}
+	if col < 0 {
+		return ""
+	}
+	start := col
+	for start > 0 {
+		r, size := utf8.DecodeLastRuneInString(line[:start])
+		if !isIdentRune(r) {
+			break
+		}
+		start -= size
+	}
+	end := col
+	for end < len(line) {
+		r, size := utf8.DecodeRuneInString(line[end:])
+		if !isIdentRune(r) {
+			break
+		}
+		end += size
+	}
+	return line[start:end]
+}
+
+// isIdentRune reports whether r can be part of an identifier in the
+// languages the graph parses.
+func isIdentRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package lspserver exposes the Trace code graph as a Language Server.
+//
+// Where package lsp is a client that asks external language servers
+// (gopls, pyright, ...) for answers, this package is the other direction:
+// editors connect to Trace over the Language Server Protocol and query the
+// same in-memory graphs the HTTP API serves.
+//
+// # Supported Methods
+//
+//   - initialize: builds (or reuses) the graph for the workspace root
+//   - textDocument/definition: symbol lookup by file and position
+//   - textDocument/references: callers (FindCallersByID) and REFERENCES edges
+//   - textDocument/documentSymbol: the symbols of a file, nested
+//   - workspace/symbol: the fuzzy symbol index
+//   - textDocument/didSave: refreshes the graph (incremental when a
+//     snapshot is available)
+//
+// Positions are mapped to symbols with the per-file interval index built
+// when the graph is frozen (graph.Graph.SymbolAt).
+//
+// # Thread Safety
+//
+// A Server may serve several connections concurrently. Each connection
+// handles its messages sequentially.
+//
+// # Example
+//
+//	srv := lspserver.NewServer(svc)
+//	err := srv.Serve(ctx, os.Stdin, os.Stdout)
+package lspserver
@@ -0,0 +1,474 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lspserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lsp"
+)
+
+// Result limits.
+const (
+	// maxDefinitionCandidates caps definitions resolved by name alone.
+	maxDefinitionCandidates = 20
+
+	// maxReferences caps locations returned by textDocument/references.
+	maxReferences = 500
+
+	// maxWorkspaceSymbols caps workspace/symbol results.
+	maxWorkspaceSymbols = 100
+)
+
+// serverName is reported in the initialize result.
+const serverName = "aleutian-trace"
+
+// initializationOptions are the Trace-specific options a client may pass
+// in initialize.initializationOptions.
+type initializationOptions struct {
+	// Languages restricts parsing, as in POST /v1/trace/init.
+	Languages []string `json:"languages"`
+
+	// Excludes are glob patterns to skip, as in POST /v1/trace/init.
+	Excludes []string `json:"excludes"`
+}
+
+// textDocumentSyncOptions advertises full-document sync with save
+// notifications.
+type textDocumentSyncOptions struct {
+	OpenClose bool        `json:"openClose"`
+	Change    int         `json:"change"`
+	Save      saveOptions `json:"save"`
+}
+
+// saveOptions configures didSave notifications.
+type saveOptions struct {
+	IncludeText bool `json:"includeText"`
+}
+
+// textDocumentSyncFull is TextDocumentSyncKind.Full.
+const textDocumentSyncFull = 1
+
+// initialize builds or reuses the graph for the workspace root.
+func (sess *session) initialize(params json.RawMessage) (interface{}, *lsp.ResponseError) {
+	var p lsp.InitializeParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+
+	var opts initializationOptions
+	if p.InitializationOptions != nil {
+		raw, err := json.Marshal(p.InitializationOptions)
+		if err == nil {
+			err = json.Unmarshal(raw, &opts)
+		}
+		if err != nil {
+			return nil, invalidParams(fmt.Errorf("initializationOptions: %w", err))
+		}
+	}
+
+	root := ""
+	switch {
+	case p.RootURI != "":
+		root = lsp.URIToPath(p.RootURI)
+	case p.RootPath != "":
+		root = p.RootPath
+	case len(p.WorkspaceFolders) > 0:
+		root = lsp.URIToPath(p.WorkspaceFolders[0].URI)
+	}
+	if root == "" {
+		return nil, &lsp.ResponseError{Code: codeInvalidParams, Message: "a workspace root (rootUri) is required"}
+	}
+
+	resp, err := sess.server.svc.Init(sess.ctx, root, opts.Languages, opts.Excludes)
+	if err != nil {
+		return nil, requestFailed(fmt.Errorf("build graph for %s: %w", root, err))
+	}
+
+	sess.mu.Lock()
+	sess.root = root
+	sess.graphID = resp.GraphID
+	sess.languages = opts.Languages
+	sess.excludes = opts.Excludes
+	sess.mu.Unlock()
+
+	return lsp.InitializeResult{
+		Capabilities: lsp.ServerCapabilities{
+			TextDocumentSync: textDocumentSyncOptions{
+				OpenClose: true,
+				Change:    textDocumentSyncFull,
+				Save:      saveOptions{IncludeText: false},
+			},
+			DefinitionProvider:      true,
+			ReferencesProvider:      true,
+			DocumentSymbolProvider:  true,
+			WorkspaceSymbolProvider: true,
+		},
+		ServerInfo: &lsp.ServerInfo{Name: serverName},
+	}, nil
+}
+
+// didOpen records the text of an opened document.
+func (sess *session) didOpen(params json.RawMessage) (interface{}, *lsp.ResponseError) {
+	var p lsp.DidOpenTextDocumentParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+	sess.mu.Lock()
+	sess.docs[lsp.URIToPath(p.TextDocument.URI)] = p.TextDocument.Text
+	sess.mu.Unlock()
+	return nil, nil
+}
+
+// didChange records the new text of a document. Only full-document sync
+// is advertised, so the last change holds the whole text.
+func (sess *session) didChange(params json.RawMessage) (interface{}, *lsp.ResponseError) {
+	var p lsp.DidChangeTextDocumentParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+	if len(p.ContentChanges) == 0 {
+		return nil, nil
+	}
+	change := p.ContentChanges[len(p.ContentChanges)-1]
+	if change.Range != nil {
+		return nil, invalidParams(fmt.Errorf("incremental changes are not supported"))
+	}
+	sess.mu.Lock()
+	sess.docs[lsp.URIToPath(p.TextDocument.URI)] = change.Text
+	sess.mu.Unlock()
+	return nil, nil
+}
+
+// didClose forgets the text of a closed document.
+func (sess *session) didClose(params json.RawMessage) (interface{}, *lsp.ResponseError) {
+	var p lsp.DidCloseTextDocumentParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+	sess.mu.Lock()
+	delete(sess.docs, lsp.URIToPath(p.TextDocument.URI))
+	sess.mu.Unlock()
+	return nil, nil
+}
+
+// didSave refreshes the graph in the background.
+func (sess *session) didSave(params json.RawMessage) (interface{}, *lsp.ResponseError) {
+	var p lsp.DidSaveTextDocumentParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+	if p.Text != nil {
+		sess.mu.Lock()
+		sess.docs[lsp.URIToPath(p.TextDocument.URI)] = *p.Text
+		sess.mu.Unlock()
+	}
+	sess.scheduleRefresh()
+	return nil, nil
+}
+
+// definition returns the definitions of the identifier at a position.
+func (sess *session) definition(params json.RawMessage) (interface{}, *lsp.ResponseError) {
+	var p lsp.TextDocumentPositionParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+	cached, rpcErr := sess.graph()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	targets := sess.resolveTargets(cached, p.TextDocument.URI, p.Position)
+	files := newFileCache(sess, cached.ProjectRoot)
+	locations := make([]lsp.Location, 0, len(targets))
+	for _, target := range targets {
+		locations = append(locations, files.symbolLocation(target.Symbol))
+	}
+	return locations, nil
+}
+
+// references returns the call sites and references of the symbol at a
+// position.
+//
+// Description:
+//
+//	The symbol is resolved as for definition. Its references are the
+//	call sites of its callers (FindCallersByID) followed by the
+//	locations of its incoming EdgeTypeReferences edges, deduplicated.
+func (sess *session) references(params json.RawMessage) (interface{}, *lsp.ResponseError) {
+	var p lsp.ReferenceParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+	cached, rpcErr := sess.graph()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	g := cached.Graph
+
+	files := newFileCache(sess, cached.ProjectRoot)
+	locations := make([]lsp.Location, 0)
+	seen := make(map[string]bool)
+	add := func(loc lsp.Location) {
+		key := fmt.Sprintf("%s:%d:%d", loc.URI, loc.Range.Start.Line, loc.Range.Start.Character)
+		if seen[key] || len(locations) >= maxReferences {
+			return
+		}
+		seen[key] = true
+		locations = append(locations, loc)
+	}
+
+	for _, target := range sess.resolveTargets(cached, p.TextDocument.URI, p.Position) {
+		if p.Context.IncludeDeclaration {
+			add(files.symbolLocation(target.Symbol))
+		}
+
+		callers, err := g.FindCallersByID(sess.ctx, target.ID, graph.WithLimit(maxReferences))
+		if err != nil {
+			return nil, requestFailed(err)
+		}
+		for _, caller := range callers.Symbols {
+			callerNode, ok := g.GetNode(caller.ID)
+			if !ok {
+				continue
+			}
+			for _, edge := range callerNode.Outgoing {
+				if edge.Type == graph.EdgeTypeCalls && edge.ToID == target.ID {
+					add(files.edgeLocation(edge, caller))
+				}
+			}
+		}
+
+		for _, edge := range target.Incoming {
+			if edge.Type != graph.EdgeTypeReferences {
+				continue
+			}
+			from, _ := g.GetNode(edge.FromID)
+			var fromSym *ast.Symbol
+			if from != nil {
+				fromSym = from.Symbol
+			}
+			add(files.edgeLocation(edge, fromSym))
+		}
+	}
+	return locations, nil
+}
+
+// documentSymbol returns the symbols of a file, nested by source range.
+func (sess *session) documentSymbol(params json.RawMessage) (interface{}, *lsp.ResponseError) {
+	var p lsp.DocumentSymbolParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+	cached, rpcErr := sess.graph()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	relPath, ok := relativePath(cached.ProjectRoot, sess.rootPath(), lsp.URIToPath(p.TextDocument.URI))
+	if !ok {
+		return []lsp.DocumentSymbol{}, nil
+	}
+	files := newFileCache(sess, cached.ProjectRoot)
+	return buildOutline(cached.Graph.SymbolsInFile(relPath), files), nil
+}
+
+// workspaceSymbol searches the fuzzy symbol index.
+func (sess *session) workspaceSymbol(params json.RawMessage) (interface{}, *lsp.ResponseError) {
+	var p lsp.WorkspaceSymbolParams
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, invalidParams(err)
+	}
+	cached, rpcErr := sess.graph()
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	symbols := make([]lsp.SymbolInformation, 0)
+	if cached.Index == nil || strings.TrimSpace(p.Query) == "" {
+		return symbols, nil
+	}
+	matches, err := cached.Index.Search(sess.ctx, p.Query, maxWorkspaceSymbols)
+	if err != nil {
+		return nil, requestFailed(err)
+	}
+
+	files := newFileCache(sess, cached.ProjectRoot)
+	for _, sym := range matches {
+		if !includeInOutline(sym) {
+			continue
+		}
+		symbols = append(symbols, lsp.SymbolInformation{
+			Name:          sym.Name,
+			Kind:          symbolKind(sym.Kind),
+			Location:      files.symbolLocation(sym),
+			ContainerName: containerName(sym),
+		})
+	}
+	return symbols, nil
+}
+
+// graph returns the session's current graph. The graph is looked up on
+// every request, so refreshes (from didSave or the HTTP API) are seen
+// immediately.
+func (sess *session) graph() (*trace.CachedGraph, *lsp.ResponseError) {
+	sess.mu.Lock()
+	graphID := sess.graphID
+	sess.mu.Unlock()
+
+	cached, err := sess.server.svc.GetGraph(graphID)
+	if err != nil {
+		return nil, requestFailed(err)
+	}
+	return cached, nil
+}
+
+// rootPath returns the workspace root given in initialize.
+func (sess *session) rootPath() string {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.root
+}
+
+// documentText returns the text of the document at absPath: the open
+// editor buffer if there is one, otherwise the file on disk.
+func (sess *session) documentText(absPath string) (string, bool) {
+	sess.mu.Lock()
+	text, ok := sess.docs[absPath]
+	sess.mu.Unlock()
+	if ok {
+		return text, true
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// resolveTargets returns the graph nodes the identifier at pos refers to.
+//
+// Description:
+//
+//	Tries, in order, and returns the first non-empty result:
+//
+//	  1. A symbol declared on this line under the cursor's name (the
+//	     cursor is on a declaration).
+//	  2. Targets of edges expressed on this line whose target has the
+//	     cursor's name (a call or reference at the cursor).
+//	  3. Targets of outgoing edges of the enclosing symbols with that
+//	     name.
+//	  4. Symbols of that name in the same file.
+//	  5. Symbols of that name anywhere, up to maxDefinitionCandidates.
+func (sess *session) resolveTargets(cached *trace.CachedGraph, uri string, pos lsp.Position) []*graph.Node {
+	g := cached.Graph
+	absPath := lsp.URIToPath(uri)
+	relPath, ok := relativePath(cached.ProjectRoot, sess.rootPath(), absPath)
+	if !ok {
+		return nil
+	}
+	text, ok := sess.documentText(absPath)
+	if !ok {
+		return nil
+	}
+	word := identifierAt(lineAt(text, pos.Line), pos.Character)
+	if word == "" {
+		return nil
+	}
+	line := pos.Line + 1
+
+	for _, node := range g.SymbolsAt(relPath, line, -1) {
+		if node.Symbol.Name == word && node.Symbol.StartLine == line {
+			return []*graph.Node{node}
+		}
+	}
+
+	var targets []*graph.Node
+	seen := make(map[string]bool)
+	addTarget := func(id string) {
+		node, ok := g.GetNode(id)
+		if !ok || seen[id] || node.Symbol == nil || node.Symbol.Name != word {
+			return
+		}
+		seen[id] = true
+		targets = append(targets, node)
+	}
+
+	for _, edge := range g.GetEdgesByFile(relPath) {
+		end := edge.Location.EndLine
+		if end < edge.Location.StartLine {
+			end = edge.Location.StartLine
+		}
+		if line >= edge.Location.StartLine && line <= end {
+			addTarget(edge.ToID)
+		}
+	}
+	if len(targets) > 0 {
+		return targets
+	}
+
+	for _, node := range g.SymbolsAt(relPath, line, pos.Character) {
+		for _, edge := range node.Outgoing {
+			addTarget(edge.ToID)
+		}
+	}
+	if len(targets) > 0 {
+		return targets
+	}
+
+	for _, node := range g.SymbolsInFile(relPath) {
+		addTarget(node.ID)
+	}
+	if len(targets) > 0 {
+		return targets
+	}
+
+	for _, node := range g.GetNodesByName(word) {
+		if len(targets) >= maxDefinitionCandidates {
+			break
+		}
+		addTarget(node.ID)
+	}
+	return targets
+}
+
+// invalidParams wraps a params decoding error.
+func invalidParams(err error) *lsp.ResponseError {
+	return &lsp.ResponseError{Code: codeInvalidParams, Message: err.Error()}
+}
+
+// requestFailed wraps an error from the graph service.
+func requestFailed(err error) *lsp.ResponseError {
+	return &lsp.ResponseError{Code: codeRequestFailed, Message: err.Error()}
+}
+
+// relativePath converts an absolute document path to a graph file path.
+// Paths are tried against the graph's project root and then the root the
+// client sent, which differ when the root contains symlinks.
+func relativePath(projectRoot, clientRoot, absPath string) (string, bool) {
+	for _, root := range []string{projectRoot, clientRoot} {
+		if root == "" {
+			continue
+		}
+		rel, err := filepath.Rel(root, absPath)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return rel, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,164 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lspserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lsp"
+)
+
+// JSON-RPC error codes used by the server.
+const (
+	codeParseError           = -32700
+	codeMethodNotFound       = -32601
+	codeInvalidParams        = -32602
+	codeInternalError        = -32603
+	codeServerNotInitialized = -32002
+	codeRequestFailed        = -32803
+)
+
+// maxMessageSize bounds the Content-Length accepted from clients.
+const maxMessageSize = 64 << 20
+
+// incomingMessage is a request or notification received from the client.
+//
+// ID is kept raw because clients may use numbers or strings; it is echoed
+// back unchanged. Notifications have no ID.
+type incomingMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// isNotification reports whether the message expects no response.
+func (m *incomingMessage) isNotification() bool {
+	return len(m.ID) == 0
+}
+
+// outgoingResponse is a response sent to the client.
+//
+// Result is always present on success (as JSON null when there is no
+// result), as the protocol requires.
+type outgoingResponse struct {
+	JSONRPC string             `json:"jsonrpc"`
+	ID      json.RawMessage    `json:"id"`
+	Result  json.RawMessage    `json:"result,omitempty"`
+	Error   *lsp.ResponseError `json:"error,omitempty"`
+}
+
+// conn reads and writes Content-Length framed JSON-RPC messages.
+//
+// Thread Safety: reads must come from a single goroutine; writes are
+// serialized by writeMu.
+type conn struct {
+	reader  *bufio.Reader
+	writer  io.Writer
+	writeMu sync.Mutex
+}
+
+// newConn creates a conn over r and w.
+func newConn(r io.Reader, w io.Writer) *conn {
+	return &conn{reader: bufio.NewReader(r), writer: w}
+}
+
+// read reads the next message body.
+//
+// Outputs:
+//
+//	[]byte - The message body.
+//	error - io.EOF when the client closed the stream, otherwise a framing error.
+func (c *conn) read() ([]byte, error) {
+	contentLength := -1
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && contentLength < 0 {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("read header: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if contentLength < 0 {
+				// Tolerate blank lines between messages.
+				continue
+			}
+			break
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok || !strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid Content-Length %q", strings.TrimSpace(value))
+		}
+		if n > maxMessageSize {
+			return nil, fmt.Errorf("content length %d exceeds limit %d", n, maxMessageSize)
+		}
+		contentLength = n
+	}
+
+	body := make([]byte, contentLength)
+	if _, err := io.ReadFull(c.reader, body); err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return body, nil
+}
+
+// write marshals v and writes it with a Content-Length header.
+func (c *conn) write(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if _, err := fmt.Fprintf(c.writer, "Content-Length: %d\r\n\r\n", len(data)); err != nil {
+		return fmt.Errorf("write header: %w", err)
+	}
+	if _, err := c.writer.Write(data); err != nil {
+		return fmt.Errorf("write body: %w", err)
+	}
+	return nil
+}
+
+// reply writes a success response for id.
+func (c *conn) reply(id json.RawMessage, result interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return c.replyError(id, codeInternalError, fmt.Sprintf("marshal result: %v", err))
+	}
+	return c.write(outgoingResponse{JSONRPC: lsp.JSONRPCVersion, ID: id, Result: data})
+}
+
+// replyError writes an error response for id. A missing id (for messages
+// that could not be parsed) is sent as null.
+func (c *conn) replyError(id json.RawMessage, code int, message string) error {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	return c.write(outgoingResponse{
+		JSONRPC: lsp.JSONRPCVersion,
+		ID:      id,
+		Error:   &lsp.ResponseError{Code: code, Message: message},
+	})
+}
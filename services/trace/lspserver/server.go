@@ -0,0 +1,356 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lspserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lsp"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// ErrExitWithoutShutdown is returned by Serve when the client sends exit
+// without a preceding shutdown request. Callers should exit with status 1.
+var ErrExitWithoutShutdown = errors.New("exit received before shutdown")
+
+// GraphService is the part of trace.Service the LSP server uses.
+//
+// *trace.Service implements it; passing the same Service to the HTTP
+// handlers and the LSP server makes both share one set of graphs.
+type GraphService interface {
+	// Init builds or returns the cached graph for projectRoot.
+	Init(ctx context.Context, projectRoot string, languages, excludes []string, forceRebuild ...bool) (*trace.InitResponse, error)
+
+	// GetGraph returns the current graph for graphID.
+	GetGraph(graphID string) (*trace.CachedGraph, error)
+}
+
+// Server answers LSP requests from the Trace code graph.
+//
+// Thread Safety: Safe for concurrent use. Each call to Serve handles one
+// client connection.
+type Server struct {
+	svc    GraphService
+	logger *slog.Logger
+}
+
+// NewServer creates an LSP server backed by svc.
+//
+// Inputs:
+//
+//	svc - The graph service. Must not be nil.
+//
+// Outputs:
+//
+//	*Server - The server.
+func NewServer(svc GraphService) *Server {
+	return &Server{
+		svc:    svc,
+		logger: slog.Default().With(slog.String("component", "lspserver")),
+	}
+}
+
+// Serve handles one client connection until it exits or the stream ends.
+//
+// Description:
+//
+//	Reads Content-Length framed JSON-RPC messages from r and writes
+//	responses to w. Messages are handled in order. Background graph
+//	refreshes started by didSave are cancelled and awaited before Serve
+//	returns.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. Cancelling it stops the connection
+//	      after the message currently being read.
+//	r - Stream of client messages (e.g. os.Stdin).
+//	w - Stream for server messages (e.g. os.Stdout).
+//
+// Outputs:
+//
+//	error - nil on a clean shutdown/exit or when the client closes the
+//	        stream, ErrExitWithoutShutdown for exit without shutdown,
+//	        otherwise the read error.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	sess := &session{
+		server: s,
+		conn:   newConn(r, w),
+		ctx:    ctx,
+		docs:   make(map[string]string),
+	}
+	defer func() {
+		cancel()
+		sess.refreshWG.Wait()
+	}()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		body, err := sess.conn.read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+
+		var msg incomingMessage
+		if err := json.Unmarshal(body, &msg); err != nil {
+			_ = sess.conn.replyError(nil, codeParseError, fmt.Sprintf("parse message: %v", err))
+			continue
+		}
+		if msg.Method == "" {
+			// Responses to server-initiated requests; none are sent.
+			continue
+		}
+		if msg.Method == "exit" {
+			if sess.isShutdown() {
+				return nil
+			}
+			return ErrExitWithoutShutdown
+		}
+
+		sess.handle(&msg)
+	}
+}
+
+// ListenAndServe accepts LSP clients over TCP until ctx is cancelled.
+//
+// Description:
+//
+//	Each accepted connection is served by Serve in its own goroutine.
+//	Connections share the server's GraphService, so clients opening the
+//	same workspace share one graph.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. Cancelling closes the listener and
+//	      all open connections.
+//	addr - TCP address to listen on, e.g. "127.0.0.1:12218".
+//
+// Outputs:
+//
+//	error - Non-nil if listening fails; nil after ctx is cancelled.
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", addr, err)
+	}
+	s.logger.Info("LSP server listening", slog.String("address", listener.Addr().String()))
+	return s.serveListener(ctx, listener)
+}
+
+// serveListener accepts connections from listener until ctx is cancelled.
+func (s *Server) serveListener(ctx context.Context, listener net.Listener) error {
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		c, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("accept: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			connCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go func() {
+				<-connCtx.Done()
+				c.Close()
+			}()
+
+			remote := c.RemoteAddr().String()
+			s.logger.Info("LSP client connected", slog.String("remote", remote))
+			if err := s.Serve(connCtx, c, c); err != nil && connCtx.Err() == nil {
+				s.logger.Warn("LSP client connection ended with error",
+					slog.String("remote", remote),
+					telemetry.SensitiveLogError("error", err))
+				return
+			}
+			s.logger.Info("LSP client disconnected", slog.String("remote", remote))
+		}()
+	}
+}
+
+// session is the state of one client connection.
+type session struct {
+	server *Server
+	conn   *conn
+	ctx    context.Context
+
+	mu        sync.Mutex
+	root      string
+	graphID   string
+	languages []string
+	excludes  []string
+	shutdown  bool
+
+	// docs holds the text of open documents by absolute path, so
+	// positions resolve against what the editor shows.
+	docs map[string]string
+
+	// refreshing is true while a didSave refresh runs; refreshPending
+	// records saves that arrived during it.
+	refreshing     bool
+	refreshPending bool
+	refreshWG      sync.WaitGroup
+}
+
+// handlerFunc handles a request or notification and returns the result.
+type handlerFunc func(sess *session, params json.RawMessage) (interface{}, *lsp.ResponseError)
+
+// handlers maps LSP methods to their handlers.
+var handlers = map[string]handlerFunc{
+	"initialize":                  (*session).initialize,
+	"initialized":                 (*session).ignore,
+	"shutdown":                    (*session).shutdownRequest,
+	"textDocument/didOpen":        (*session).didOpen,
+	"textDocument/didChange":      (*session).didChange,
+	"textDocument/didClose":       (*session).didClose,
+	"textDocument/didSave":        (*session).didSave,
+	"textDocument/definition":     (*session).definition,
+	"textDocument/references":     (*session).references,
+	"textDocument/documentSymbol": (*session).documentSymbol,
+	"workspace/symbol":            (*session).workspaceSymbol,
+}
+
+// handle dispatches msg and writes the response for requests.
+func (sess *session) handle(msg *incomingMessage) {
+	handler, ok := handlers[msg.Method]
+	if !ok {
+		if !msg.isNotification() {
+			_ = sess.conn.replyError(msg.ID, codeMethodNotFound, "method not supported: "+msg.Method)
+		}
+		return
+	}
+
+	if msg.Method != "initialize" && !sess.isInitialized() {
+		if !msg.isNotification() {
+			_ = sess.conn.replyError(msg.ID, codeServerNotInitialized, "initialize has not been called")
+		}
+		return
+	}
+
+	result, rpcErr := handler(sess, msg.Params)
+	if msg.isNotification() {
+		if rpcErr != nil {
+			sess.server.logger.Debug("LSP notification failed",
+				slog.String("method", msg.Method),
+				telemetry.SensitiveLog("error", rpcErr.Message))
+		}
+		return
+	}
+
+	var err error
+	if rpcErr != nil {
+		err = sess.conn.replyError(msg.ID, rpcErr.Code, rpcErr.Message)
+	} else {
+		err = sess.conn.reply(msg.ID, result)
+	}
+	if err != nil {
+		sess.server.logger.Warn("LSP write failed",
+			slog.String("method", msg.Method),
+			telemetry.SensitiveLogError("error", err))
+	}
+}
+
+// isInitialized reports whether initialize has succeeded.
+func (sess *session) isInitialized() bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.graphID != ""
+}
+
+// isShutdown reports whether shutdown was requested.
+func (sess *session) isShutdown() bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.shutdown
+}
+
+// ignore accepts a notification that needs no action.
+func (sess *session) ignore(json.RawMessage) (interface{}, *lsp.ResponseError) {
+	return nil, nil
+}
+
+// shutdownRequest handles shutdown. The connection stays open until exit.
+func (sess *session) shutdownRequest(json.RawMessage) (interface{}, *lsp.ResponseError) {
+	sess.mu.Lock()
+	sess.shutdown = true
+	sess.mu.Unlock()
+	return nil, nil
+}
+
+// scheduleRefresh rebuilds the session's graph in the background.
+//
+// Description:
+//
+//	Calls Init with forceRebuild, which applies an incremental refresh
+//	when a prior snapshot is available and falls back to a full build.
+//	Saves arriving while a refresh runs are coalesced into one more
+//	refresh after it, so the final graph reflects the last save.
+func (sess *session) scheduleRefresh() {
+	sess.mu.Lock()
+	if sess.refreshing {
+		sess.refreshPending = true
+		sess.mu.Unlock()
+		return
+	}
+	sess.refreshing = true
+	root, languages, excludes := sess.root, sess.languages, sess.excludes
+	sess.mu.Unlock()
+
+	sess.refreshWG.Add(1)
+	go func() {
+		defer sess.refreshWG.Done()
+		for {
+			resp, err := sess.server.svc.Init(sess.ctx, root, languages, excludes, true)
+			if err != nil {
+				sess.server.logger.Warn("LSP graph refresh failed",
+					telemetry.SensitiveLog("root", root),
+					telemetry.SensitiveLogError("error", err))
+			} else {
+				sess.server.logger.Debug("LSP graph refreshed",
+					slog.String("graph_id", resp.GraphID),
+					slog.String("status", string(resp.Status)))
+			}
+
+			sess.mu.Lock()
+			if !sess.refreshPending || sess.ctx.Err() != nil {
+				sess.refreshing = false
+				sess.refreshPending = false
+				sess.mu.Unlock()
+				return
+			}
+			sess.refreshPending = false
+			sess.mu.Unlock()
+		}
+	}()
+}
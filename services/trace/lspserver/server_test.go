@@ -0,0 +1,502 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package lspserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/lsp"
+)
+
+const usersGo = `package store
+
+// User is a stored user.
+type User struct {
+	ID   int
+	Name string
+}
+
+// Store holds users.
+type Store struct {
+	users map[int]*User
+}
+
+// Get returns a user.
+func (s *Store) Get(id int) *User {
+	return s.users[id]
+}
+
+// Lookup finds a user by ID.
+func Lookup(s *Store, id int) *User {
+	return s.Get(id)
+}
+`
+
+const handlerGo = `package store
+
+func Handle(s *Store) string {
+	u := Lookup(s, 1)
+	return u.Name
+}
+`
+
+// writeProject writes files under a new temp directory and returns it.
+func writeProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range files {
+		abs := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(abs), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(abs, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+// testClient drives a Server over in-memory pipes.
+type testClient struct {
+	t      *testing.T
+	conn   *conn
+	toSrv  io.WriteCloser
+	nextID int
+	done   chan error
+}
+
+func newTestClient(t *testing.T, svc GraphService) *testClient {
+	t.Helper()
+	clientToServer, serverIn := io.Pipe()
+	serverToClient, serverOut := io.Pipe()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServer(svc).Serve(context.Background(), clientToServer, serverOut)
+		serverOut.Close()
+	}()
+
+	c := &testClient{t: t, conn: newConn(serverToClient, serverIn), toSrv: serverIn, done: done}
+	t.Cleanup(func() { serverIn.Close() })
+	return c
+}
+
+// call sends a request and decodes its result into result.
+func (c *testClient) call(method string, params, result interface{}) *lsp.ResponseError {
+	c.t.Helper()
+	c.nextID++
+	id := strconv.Itoa(c.nextID)
+	err := c.conn.write(map[string]interface{}{
+		"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params,
+	})
+	if err != nil {
+		c.t.Fatalf("write %s: %v", method, err)
+	}
+
+	for {
+		body, err := c.conn.read()
+		if err != nil {
+			c.t.Fatalf("read response to %s: %v", method, err)
+		}
+		var resp struct {
+			ID     json.RawMessage    `json:"id"`
+			Result json.RawMessage    `json:"result"`
+			Error  *lsp.ResponseError `json:"error"`
+		}
+		if err := json.Unmarshal(body, &resp); err != nil {
+			c.t.Fatalf("decode response to %s: %v", method, err)
+		}
+		if string(resp.ID) != id {
+			continue
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				c.t.Fatalf("decode result of %s: %v (%s)", method, err, resp.Result)
+			}
+		}
+		return nil
+	}
+}
+
+// notify sends a notification.
+func (c *testClient) notify(method string, params interface{}) {
+	c.t.Helper()
+	if err := c.conn.write(lsp.Notification{JSONRPC: "2.0", Method: method, Params: params}); err != nil {
+		c.t.Fatalf("write %s: %v", method, err)
+	}
+}
+
+// initialize initializes the session for root.
+func (c *testClient) initialize(root string) lsp.InitializeResult {
+	c.t.Helper()
+	var result lsp.InitializeResult
+	if rpcErr := c.call("initialize", map[string]interface{}{
+		"processId": 1,
+		"rootUri":   pathToURI(root),
+	}, &result); rpcErr != nil {
+		c.t.Fatalf("initialize: %s", rpcErr.Message)
+	}
+	c.notify("initialized", map[string]interface{}{})
+	return result
+}
+
+func positionParams(root, rel string, line, char int) map[string]interface{} {
+	return map[string]interface{}{
+		"textDocument": map[string]string{"uri": pathToURI(filepath.Join(root, rel))},
+		"position":     map[string]int{"line": line, "character": char},
+	}
+}
+
+func TestServer_Lifecycle(t *testing.T) {
+	root := writeProject(t, map[string]string{"store/users.go": usersGo})
+	c := newTestClient(t, trace.NewService(trace.DefaultServiceConfig()))
+
+	// Requests before initialize are rejected.
+	rpcErr := c.call("workspace/symbol", map[string]string{"query": "User"}, nil)
+	if rpcErr == nil || rpcErr.Code != codeServerNotInitialized {
+		t.Fatalf("request before initialize: got %+v, want code %d", rpcErr, codeServerNotInitialized)
+	}
+
+	result := c.initialize(root)
+	caps := result.Capabilities
+	if !caps.HasDefinitionProvider() || !caps.HasReferencesProvider() ||
+		caps.DocumentSymbolProvider != true || caps.WorkspaceSymbolProvider != true {
+		t.Errorf("capabilities = %+v, want definition, references, documentSymbol and workspaceSymbol", caps)
+	}
+	if result.ServerInfo == nil || result.ServerInfo.Name != serverName {
+		t.Errorf("serverInfo = %+v, want %s", result.ServerInfo, serverName)
+	}
+
+	rpcErr = c.call("textDocument/hover", positionParams(root, "store/users.go", 0, 0), nil)
+	if rpcErr == nil || rpcErr.Code != codeMethodNotFound {
+		t.Errorf("unsupported method: got %+v, want code %d", rpcErr, codeMethodNotFound)
+	}
+
+	if rpcErr := c.call("shutdown", nil, nil); rpcErr != nil {
+		t.Fatalf("shutdown: %s", rpcErr.Message)
+	}
+	c.notify("exit", nil)
+	select {
+	case err := <-c.done:
+		if err != nil {
+			t.Errorf("Serve after shutdown+exit = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after exit")
+	}
+}
+
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	c := newTestClient(t, trace.NewService(trace.DefaultServiceConfig()))
+	c.notify("exit", nil)
+	select {
+	case err := <-c.done:
+		if err != ErrExitWithoutShutdown {
+			t.Errorf("Serve = %v, want ErrExitWithoutShutdown", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after exit")
+	}
+}
+
+func TestServer_DefinitionAndReferences(t *testing.T) {
+	root := writeProject(t, map[string]string{
+		"store/users.go":   usersGo,
+		"store/handler.go": handlerGo,
+	})
+	c := newTestClient(t, trace.NewService(trace.DefaultServiceConfig()))
+	c.initialize(root)
+
+	usersURI := pathToURI(filepath.Join(root, "store", "users.go"))
+	handlerURI := pathToURI(filepath.Join(root, "store", "handler.go"))
+
+	// "u := Lookup(s, 1)" -> func Lookup on line 19 (0-indexed).
+	var defs []lsp.Location
+	if rpcErr := c.call("textDocument/definition", positionParams(root, "store/handler.go", 3, 8), &defs); rpcErr != nil {
+		t.Fatalf("definition: %s", rpcErr.Message)
+	}
+	if len(defs) != 1 || defs[0].URI != usersURI || defs[0].Range.Start.Line != 19 {
+		t.Fatalf("definition = %+v, want %s line 19", defs, usersURI)
+	}
+	if got := defs[0].Range.Start.Character; got != len("func ") {
+		t.Errorf("definition character = %d, want %d (the name)", got, len("func "))
+	}
+
+	// On the declaration itself, definition returns the declaration.
+	if rpcErr := c.call("textDocument/definition", positionParams(root, "store/users.go", 19, 6), &defs); rpcErr != nil {
+		t.Fatalf("definition on declaration: %s", rpcErr.Message)
+	}
+	if len(defs) != 1 || defs[0].Range.Start.Line != 19 {
+		t.Errorf("definition on declaration = %+v, want line 19", defs)
+	}
+
+	// Whitespace resolves to nothing.
+	if rpcErr := c.call("textDocument/definition", positionParams(root, "store/handler.go", 1, 0), &defs); rpcErr != nil {
+		t.Fatalf("definition on blank line: %s", rpcErr.Message)
+	}
+	if len(defs) != 0 {
+		t.Errorf("definition on blank line = %+v, want none", defs)
+	}
+
+	// References of Lookup: the call in Handle, plus the declaration.
+	params := positionParams(root, "store/users.go", 19, 6)
+	params["context"] = map[string]bool{"includeDeclaration": true}
+	var refs []lsp.Location
+	if rpcErr := c.call("textDocument/references", params, &refs); rpcErr != nil {
+		t.Fatalf("references: %s", rpcErr.Message)
+	}
+	var sawCall, sawDecl bool
+	for _, ref := range refs {
+		if ref.URI == handlerURI && ref.Range.Start.Line == 3 {
+			sawCall = true
+		}
+		if ref.URI == usersURI && ref.Range.Start.Line == 19 {
+			sawDecl = true
+		}
+	}
+	if !sawCall || !sawDecl {
+		t.Errorf("references = %+v, want the call in handler.go:4 and the declaration", refs)
+	}
+
+	// References of the User type include its uses in signatures.
+	params = positionParams(root, "store/users.go", 3, 6)
+	params["context"] = map[string]bool{"includeDeclaration": false}
+	if rpcErr := c.call("textDocument/references", params, &refs); rpcErr != nil {
+		t.Fatalf("references of User: %s", rpcErr.Message)
+	}
+	for _, ref := range refs {
+		if ref.URI == usersURI && ref.Range.Start.Line == 3 {
+			t.Errorf("references without includeDeclaration returned the declaration: %+v", ref)
+		}
+	}
+}
+
+func TestServer_DocumentSymbolAndWorkspaceSymbol(t *testing.T) {
+	root := writeProject(t, map[string]string{"store/users.go": usersGo})
+	c := newTestClient(t, trace.NewService(trace.DefaultServiceConfig()))
+	c.initialize(root)
+
+	var outline []lsp.DocumentSymbol
+	if rpcErr := c.call("textDocument/documentSymbol", map[string]interface{}{
+		"textDocument": map[string]string{"uri": pathToURI(filepath.Join(root, "store", "users.go"))},
+	}, &outline); rpcErr != nil {
+		t.Fatalf("documentSymbol: %s", rpcErr.Message)
+	}
+	byName := make(map[string]lsp.DocumentSymbol)
+	for _, sym := range outline {
+		byName[sym.Name] = sym
+		if !rangeContains(sym.Range, sym.SelectionRange) {
+			t.Errorf("%s: selectionRange %+v not inside range %+v", sym.Name, sym.SelectionRange, sym.Range)
+		}
+	}
+	for _, name := range []string{"User", "Store", "Get", "Lookup"} {
+		if _, ok := byName[name]; !ok {
+			t.Errorf("documentSymbol missing %s; got %+v", name, outline)
+		}
+	}
+	if got := byName["Lookup"].Kind; got != lsp.SymbolKindFunction {
+		t.Errorf("Lookup kind = %d, want %d", got, lsp.SymbolKindFunction)
+	}
+	if got := byName["Get"].Kind; got != lsp.SymbolKindMethod {
+		t.Errorf("Get kind = %d, want %d", got, lsp.SymbolKindMethod)
+	}
+	var fields []string
+	for _, child := range byName["User"].Children {
+		fields = append(fields, child.Name)
+	}
+	if strings.Join(fields, ",") != "ID,Name" {
+		t.Errorf("User children = %v, want [ID Name]", fields)
+	}
+
+	var symbols []lsp.SymbolInformation
+	if rpcErr := c.call("workspace/symbol", map[string]string{"query": "Looku"}, &symbols); rpcErr != nil {
+		t.Fatalf("workspace/symbol: %s", rpcErr.Message)
+	}
+	if len(symbols) == 0 || symbols[0].Name != "Lookup" {
+		t.Fatalf("workspace/symbol(Looku) = %+v, want Lookup first", symbols)
+	}
+	if symbols[0].Location.Range.Start.Line != 19 {
+		t.Errorf("Lookup location = %+v, want line 19", symbols[0].Location)
+	}
+}
+
+func TestServer_DidSaveRefreshesGraph(t *testing.T) {
+	root := writeProject(t, map[string]string{"store/users.go": usersGo})
+	c := newTestClient(t, trace.NewService(trace.DefaultServiceConfig()))
+	c.initialize(root)
+
+	path := filepath.Join(root, "store", "users.go")
+	updated := usersGo + "\nfunc Delete(s *Store, id int) {\n\tdelete(s.users, id)\n}\n"
+	if err := os.WriteFile(path, []byte(updated), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	c.notify("textDocument/didSave", map[string]interface{}{
+		"textDocument": map[string]string{"uri": pathToURI(path)},
+	})
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var symbols []lsp.SymbolInformation
+		if rpcErr := c.call("workspace/symbol", map[string]string{"query": "Delete"}, &symbols); rpcErr != nil {
+			t.Fatalf("workspace/symbol: %s", rpcErr.Message)
+		}
+		if len(symbols) > 0 && symbols[0].Name == "Delete" {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("graph was not refreshed after didSave")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestServer_OpenDocumentTextIsUsed(t *testing.T) {
+	root := writeProject(t, map[string]string{
+		"store/users.go":   usersGo,
+		"store/handler.go": handlerGo,
+	})
+	c := newTestClient(t, trace.NewService(trace.DefaultServiceConfig()))
+	c.initialize(root)
+
+	// The editor buffer has an extra leading line; without the buffer the
+	// position would land on the blank line.
+	uri := pathToURI(filepath.Join(root, "store", "handler.go"))
+	c.notify("textDocument/didOpen", map[string]interface{}{
+		"textDocument": map[string]interface{}{
+			"uri": uri, "languageId": "go", "version": 1,
+			"text": strings.Replace(handlerGo, "\n\n", "\n", 1),
+		},
+	})
+
+	var defs []lsp.Location
+	if rpcErr := c.call("textDocument/definition", positionParams(root, "store/handler.go", 1, 6), &defs); rpcErr != nil {
+		t.Fatalf("definition: %s", rpcErr.Message)
+	}
+	found := false
+	for _, def := range defs {
+		if strings.HasSuffix(def.URI, "handler.go") && def.Range.Start.Line == 2 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("definition with open buffer = %+v, want Handle in handler.go", defs)
+	}
+}
+
+func TestServer_ListenAndServe(t *testing.T) {
+	root := writeProject(t, map[string]string{"store/users.go": usersGo})
+	srv := NewServer(trace.NewService(trace.DefaultServiceConfig()))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.serveListener(ctx, listener) }()
+
+	netConn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer netConn.Close()
+	c := &testClient{t: t, conn: newConn(netConn, netConn)}
+	c.initialize(root)
+
+	var symbols []lsp.SymbolInformation
+	if rpcErr := c.call("workspace/symbol", map[string]string{"query": "Store"}, &symbols); rpcErr != nil {
+		t.Fatalf("workspace/symbol: %s", rpcErr.Message)
+	}
+	if len(symbols) == 0 {
+		t.Error("workspace/symbol over TCP returned nothing")
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("serveListener = %v, want nil after cancel", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("serveListener did not return after cancel")
+	}
+}
+
+func TestConn_Read(t *testing.T) {
+	input := "Content-Type: application/vscode-jsonrpc; charset=utf-8\r\n" +
+		"content-length: 2\r\n\r\n{}" +
+		"\r\nContent-Length: 4\r\n\r\nnull"
+	c := newConn(strings.NewReader(input), io.Discard)
+
+	for _, want := range []string{"{}", "null"} {
+		body, err := c.read()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if string(body) != want {
+			t.Errorf("body = %q, want %q", body, want)
+		}
+	}
+	if _, err := c.read(); err != io.EOF {
+		t.Errorf("read at end = %v, want io.EOF", err)
+	}
+
+	c = newConn(strings.NewReader("Content-Length: abc\r\n\r\n"), io.Discard)
+	if _, err := c.read(); err == nil {
+		t.Error("read with invalid Content-Length succeeded")
+	}
+}
+
+func TestConn_ReplyNullResult(t *testing.T) {
+	var buf bytes.Buffer
+	c := newConn(strings.NewReader(""), &buf)
+	if err := c.reply(json.RawMessage("7"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(buf.String(), `{"jsonrpc":"2.0","id":7,"result":null}`) {
+		t.Errorf("reply = %q, want a null result", buf.String())
+	}
+}
+
+func TestIdentifierAt(t *testing.T) {
+	tests := []struct {
+		line string
+		col  int
+		want string
+	}{
+		{"\tu := Lookup(s, 1)", 8, "Lookup"},
+		{"\tu := Lookup(s, 1)", 6, "Lookup"},
+		{"\tu := Lookup(s, 1)", 12, "Lookup"},
+		{"\tu := Lookup(s, 1)", 5, ""},
+		{"self.$store_name", 10, "$store_name"},
+		{"", 0, ""},
+		{"abc", 99, "abc"},
+	}
+	for _, tc := range tests {
+		if got := identifierAt(tc.line, tc.col); got != tc.want {
+			t.Errorf("identifierAt(%q, %d) = %q, want %q", tc.line, tc.col, got, tc.want)
+		}
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/filters"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/graphql"
 	"github.com/weaviate/weaviate/entities/models"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // RetrieverConfig configures the memory retriever.
@@ -225,7 +227,7 @@ func (r *MemoryRetriever) Retrieve(ctx context.Context, opts RetrieveOptions) ([
 			if err := r.store.MarkUsed(ctx, res.Memory.MemoryID); err != nil {
 				slog.Warn("Failed to mark memory as used",
 					"memory_id", res.Memory.MemoryID,
-					"error", err)
+					telemetry.SensitiveLogError("error", err))
 			}
 		}
 	}
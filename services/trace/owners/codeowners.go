@@ -0,0 +1,227 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package owners parses GitHub CODEOWNERS files and resolves the owners of
+// repository paths.
+package owners
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Locations are the paths searched for a CODEOWNERS file, relative to the
+// repository root, in the order GitHub uses. The first one found is used.
+var Locations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// Rule is one CODEOWNERS line.
+type Rule struct {
+	// Pattern is the path pattern as written.
+	Pattern string
+
+	// Owners are the @user, @org/team or email owners. Empty when the line
+	// has no owners, which removes ownership set by earlier rules.
+	Owners []string
+
+	// Line is the 1-indexed line number in the file.
+	Line int
+
+	re *regexp.Regexp
+}
+
+// Matches reports whether the rule's pattern matches a file path.
+//
+// Description:
+//
+//	path is slash-separated and relative to the repository root. Matching
+//	follows the gitignore rules GitHub applies to CODEOWNERS:
+//
+//	  - A pattern starting with "/" or containing a "/" before its last
+//	    character is anchored at the root: "/docs/" and "docs/*" only match
+//	    under the top-level docs directory.
+//	  - Other patterns match at any depth: "*.go" matches every Go file and
+//	    "apps/" every apps directory.
+//	  - A trailing "/" matches directories only, so the pattern owns every
+//	    file below the directory.
+//	  - "*" and "?" never match "/"; "**" matches any number of directories.
+//	  - A pattern ending in "/*" matches the directory's direct children only:
+//	    "docs/*" matches docs/a.md but not docs/guide/a.md.
+func (r Rule) Matches(path string) bool {
+	return r.re.MatchString(strings.TrimPrefix(path, "/"))
+}
+
+// File is a parsed CODEOWNERS file.
+//
+// Thread Safety: Safe for concurrent use; immutable after Parse or Load.
+type File struct {
+	// Path is where the file was loaded from, relative to the repository
+	// root. Empty for files from Parse.
+	Path string
+
+	// Hash is the hex SHA-256 of the file content.
+	Hash string
+
+	// Rules are the rules in file order.
+	Rules []Rule
+}
+
+// Parse parses CODEOWNERS content.
+//
+// Description:
+//
+//	Blank lines and lines starting with "#" are skipped, as is anything
+//	after a "#" that starts a field. Lines are "pattern owner...".
+//	Negation ("!") and character ranges ("[...]") are not supported by
+//	GitHub and are matched literally.
+//
+// Outputs:
+//
+//	*File - The parsed rules. Never nil on success.
+//	error - Non-nil if r fails.
+func Parse(r io.Reader) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	f := &File{Hash: hex.EncodeToString(sum[:])}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		for i, field := range fields {
+			if strings.HasPrefix(field, "#") {
+				fields = fields[:i]
+				break
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		f.Rules = append(f.Rules, Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			Line:    line,
+			re:      compilePattern(fields[0]),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Load parses the CODEOWNERS file of the repository at root.
+//
+// Outputs:
+//
+//	*File - The parsed file, or nil if root has no CODEOWNERS file.
+//	error - Non-nil if a CODEOWNERS file exists but cannot be read.
+func Load(root string) (*File, error) {
+	for _, loc := range Locations {
+		fh, err := os.Open(filepath.Join(root, filepath.FromSlash(loc)))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", loc, err)
+		}
+		f, err := Parse(fh)
+		fh.Close()
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", loc, err)
+		}
+		f.Path = loc
+		return f, nil
+	}
+	return nil, nil
+}
+
+// Owners returns the owners of a file path.
+//
+// Description:
+//
+//	The last matching rule wins, as on GitHub. Returns nil if no rule
+//	matches, if the matching rule has no owners, or if f is nil. The
+//	returned slice is shared; callers must not modify it.
+func (f *File) Owners(path string) []string {
+	if f == nil {
+		return nil
+	}
+	for i := len(f.Rules) - 1; i >= 0; i-- {
+		if f.Rules[i].Matches(path) {
+			if len(f.Rules[i].Owners) == 0 {
+				return nil
+			}
+			return f.Rules[i].Owners
+		}
+	}
+	return nil
+}
+
+// compilePattern translates a CODEOWNERS pattern to an anchored regexp
+// over slash-separated file paths. See Rule.Matches for the semantics.
+func compilePattern(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.TrimSuffix(pattern, "/")
+	anchored := strings.HasPrefix(p, "/") || strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	childrenOnly := strings.HasSuffix(p, "/*")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch c := p[i]; {
+		case c == '*' && strings.HasPrefix(p[i:], "**"):
+			rest := p[i+2:]
+			switch {
+			case strings.HasPrefix(rest, "/"):
+				// "**/" matches zero or more leading directories.
+				sb.WriteString("(?:.*/)?")
+				i += 2
+			default:
+				sb.WriteString(".*")
+				i++
+			}
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	switch {
+	case dirOnly:
+		// A directory pattern owns everything below it.
+		sb.WriteString("/.*")
+	case !childrenOnly:
+		// A pattern naming a directory also owns its contents.
+		sb.WriteString("(?:/.*)?")
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
@@ -0,0 +1,149 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package owners
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRule_Matches(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		// Unanchored patterns match at any depth.
+		{"*.go", "main.go", true},
+		{"*.go", "pkg/a/b.go", true},
+		{"*.go", "main.py", false},
+		{"*", "any/file.txt", true},
+		{"apps/", "apps/a.go", true},
+		{"apps/", "svc/apps/x/a.go", true},
+		{"apps/", "apps", false},
+		{"Makefile", "build/Makefile", true},
+
+		// A leading slash anchors at the root.
+		{"/docs/", "docs/a.md", true},
+		{"/docs/", "docs/guide/a.md", true},
+		{"/docs/", "src/docs/a.md", false},
+		{"/docs", "docs/guide/a.md", true},
+		{"/build/logs/", "build/logs/x.log", true},
+		{"/build/logs/", "x/build/logs/x.log", false},
+
+		// A slash in the middle anchors too; "/*" matches direct children only.
+		{"docs/*", "docs/a.md", true},
+		{"docs/*", "docs/guide/a.md", false},
+		{"docs/*", "src/docs/a.md", false},
+		{"src/auth", "src/auth/token.go", true},
+		{"src/auth", "lib/src/auth/token.go", false},
+
+		// Double star.
+		{"**/logs", "logs/a.log", true},
+		{"**/logs", "deploy/logs/a.log", true},
+		{"docs/**/*.md", "docs/a.md", true},
+		{"docs/**/*.md", "docs/x/y/a.md", true},
+		{"docs/**/*.md", "docs/x/a.txt", false},
+		{"src/**", "src/a/b.go", true},
+
+		// Single-character wildcard and literal metacharacters.
+		{"file?.go", "file1.go", true},
+		{"file?.go", "file12.go", false},
+		{"a+b.go", "a+b.go", true},
+	}
+	for _, tt := range tests {
+		f, err := Parse(strings.NewReader(tt.pattern + " @o"))
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", tt.pattern, err)
+		}
+		if got := f.Rules[0].Matches(tt.path); got != tt.want {
+			t.Errorf("%q matches %q = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestFile_Owners_LastMatchWins(t *testing.T) {
+	content := `# Default owners
+*       @org/everyone
+
+*.go    @org/go-team   # inline comment
+/docs/  @org/docs dev@example.com
+docs/internal/
+/services/auth/ @org/security
+`
+	f, err := Parse(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(f.Rules) != 5 {
+		t.Fatalf("rules = %d, want 5", len(f.Rules))
+	}
+	if f.Rules[1].Line != 4 || !reflect.DeepEqual(f.Rules[1].Owners, []string{"@org/go-team"}) {
+		t.Errorf("rule 1 = %+v", f.Rules[1])
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@org/everyone"}},
+		{"cmd/main.go", []string{"@org/go-team"}},
+		{"docs/guide.md", []string{"@org/docs", "dev@example.com"}},
+		// docs/internal/ has no owners and is anchored (slash in the middle).
+		{"docs/internal/notes.md", nil},
+		// The later /services/auth/ rule overrides *.go.
+		{"services/auth/token.go", []string{"@org/security"}},
+	}
+	for _, tt := range tests {
+		if got := f.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	f, err := Load(dir)
+	if err != nil || f != nil {
+		t.Fatalf("Load without CODEOWNERS = %v, %v; want nil, nil", f, err)
+	}
+	if got := f.Owners("a.go"); got != nil {
+		t.Errorf("nil File Owners = %v, want nil", got)
+	}
+
+	// .github/CODEOWNERS takes precedence over the root file.
+	if err := os.WriteFile(filepath.Join(dir, "CODEOWNERS"), []byte("* @root\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte("* @github\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err = Load(dir)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if f.Path != ".github/CODEOWNERS" {
+		t.Errorf("Path = %q, want .github/CODEOWNERS", f.Path)
+	}
+	if got := f.Owners("a.go"); !reflect.DeepEqual(got, []string{"@github"}) {
+		t.Errorf("Owners = %v, want [@github]", got)
+	}
+	if len(f.Hash) != 64 {
+		t.Errorf("Hash = %q, want 64 hex chars", f.Hash)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/owners"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// recordOwners loads the CODEOWNERS file of the project, or of each
+// workspace root, and records file owners on g.
+//
+// Description:
+//
+//	Workspace file paths are prefixed with the root name, so each root's
+//	CODEOWNERS matches paths relative to that root. A missing CODEOWNERS
+//	leaves files unowned; an unreadable one is logged and treated as
+//	missing.
+//
+// Outputs:
+//
+//	string - A hash of the CODEOWNERS content used, or "" if none was
+//	  found. Folded into the graph generation so that ownership changes
+//	  invalidate cached responses even when the source did not change.
+func recordOwners(g *graph.Graph, projectRoot string, ws *Workspace) string {
+	load := func(root string) *owners.File {
+		f, err := owners.Load(root)
+		if err != nil {
+			slog.Warn("Failed to load CODEOWNERS, files are unowned",
+				telemetry.SensitiveLog("root", root),
+				telemetry.SensitiveLogError("error", err),
+			)
+			return nil
+		}
+		return f
+	}
+
+	if ws == nil {
+		f := load(projectRoot)
+		if f == nil {
+			graph.RecordFileOwners(g, nil)
+			return ""
+		}
+		graph.RecordFileOwners(g, f.Owners)
+		slog.Info("Loaded CODEOWNERS",
+			telemetry.SensitiveLog("path", f.Path),
+			slog.Int("rules", len(f.Rules)),
+			slog.Int("owned_files", len(g.FileOwners)),
+		)
+		return f.Hash
+	}
+
+	byRoot := make(map[string]*owners.File)
+	h := sha256.New()
+	for _, root := range ws.Roots {
+		if f := load(root.Path); f != nil {
+			byRoot[root.Name] = f
+			h.Write([]byte(root.Name + "\x00" + f.Hash + "\x00"))
+		}
+	}
+	if len(byRoot) == 0 {
+		graph.RecordFileOwners(g, nil)
+		return ""
+	}
+	graph.RecordFileOwners(g, func(filePath string) []string {
+		root, ok := ws.rootOf(filePath)
+		if !ok {
+			return nil
+		}
+		return byRoot[root.Name].Owners(strings.TrimPrefix(filePath, root.Name+"/"))
+	})
+	slog.Info("Loaded workspace CODEOWNERS",
+		slog.Int("roots_with_codeowners", len(byRoot)),
+		slog.Int("owned_files", len(g.FileOwners)),
+	)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ownersOrEmpty returns owners, or an empty slice so that JSON encodes
+// "owners": [] rather than null.
+func ownersOrEmpty(owners []string) []string {
+	if owners == nil {
+		return []string{}
+	}
+	return owners
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// writeCodeowners writes .github/CODEOWNERS under dir.
+func writeCodeowners(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(dir, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".github", "CODEOWNERS"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// symbolIDByName returns the ID of the first non-package symbol named name.
+func symbolIDByName(t *testing.T, svc *Service, graphID, name string) string {
+	t.Helper()
+	cached, err := svc.GetGraph(graphID)
+	if err != nil {
+		t.Fatalf("GetGraph: %v", err)
+	}
+	for _, node := range cached.Graph.Nodes() {
+		if node.Symbol != nil && node.Symbol.Name == name && node.Symbol.Kind != ast.SymbolKindPackage {
+			return node.Symbol.ID
+		}
+	}
+	t.Fatalf("symbol %q not found", name)
+	return ""
+}
+
+func TestHandleSymbol_Owners(t *testing.T) {
+	t.Run("owners from CODEOWNERS", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTestGoFiles(t, dir)
+		writeCodeowners(t, dir, "* @org/core\n/util.go @org/util @bob\n")
+
+		svc := NewService(DefaultServiceConfig())
+		resp, err := svc.Init(context.Background(), dir, []string{"go"}, nil)
+		if err != nil {
+			t.Fatalf("Init failed: %v", err)
+		}
+		router := setupTestRouter(svc)
+
+		tests := []struct {
+			symbol string
+			want   []string
+		}{
+			{"main", []string{"@org/core"}},
+			{"helper", []string{"@org/util", "@bob"}},
+		}
+		for _, tt := range tests {
+			id := symbolIDByName(t, svc, resp.GraphID, tt.symbol)
+			w := cacheGet(router, "/v1/trace/symbol/"+id+"?graph_id="+resp.GraphID, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+			}
+			var body SymbolResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(body.Owners, tt.want) {
+				t.Errorf("%s owners = %v, want %v", tt.symbol, body.Owners, tt.want)
+			}
+		}
+	})
+
+	t.Run("empty owners without CODEOWNERS", func(t *testing.T) {
+		_, svc, router, graphID := setupHTTPCacheTest(t, DefaultServiceConfig())
+		id := symbolIDByName(t, svc, graphID, "helper")
+
+		w := cacheGet(router, "/v1/trace/symbol/"+id+"?graph_id="+graphID, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+		}
+		var raw map[string]json.RawMessage
+		if err := json.Unmarshal(w.Body.Bytes(), &raw); err != nil {
+			t.Fatal(err)
+		}
+		if got := string(raw["owners"]); got != "[]" {
+			t.Errorf("owners = %s, want []", got)
+		}
+	})
+}
+
+func TestAnalyzeImpact_ByOwner(t *testing.T) {
+	dir := t.TempDir()
+	writeTestGoFiles(t, dir)
+	writeCodeowners(t, dir, "*.go @org/core\n/sub/ @org/handlers\n")
+
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	router := setupTestRouter(svc)
+
+	body := `{"graph_id":"` + resp.GraphID + `","files":["util.go","sub/handler.go"]}`
+	w := postJSON(router, "/v1/trace/reason/impact", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var out struct {
+		Result struct {
+			ByOwner []struct {
+				Owner string   `json:"owner"`
+				Files []string `json:"files"`
+			} `json:"by_owner"`
+			UnownedFiles []string `json:"unowned_files"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	owners := make(map[string][]string)
+	for _, group := range out.Result.ByOwner {
+		owners[group.Owner] = group.Files
+	}
+	if files := owners["@org/handlers"]; !reflect.DeepEqual(files, []string{"sub/handler.go"}) {
+		t.Errorf("@org/handlers files = %v, want [sub/handler.go]", files)
+	}
+	if _, ok := owners["@org/core"]; !ok {
+		t.Errorf("by_owner = %+v, want an @org/core group", out.Result.ByOwner)
+	}
+	if len(out.Result.UnownedFiles) != 0 {
+		t.Errorf("unowned_files = %v, want none", out.Result.UnownedFiles)
+	}
+}
+
+func TestHTTPCache_CodeownersChangeChangesETag(t *testing.T) {
+	dir, svc, router, graphID := setupHTTPCacheTest(t, DefaultServiceConfig())
+	path := "/v1/trace/callers?graph_id=" + graphID + "&function=helper"
+
+	oldETag := cacheGet(router, path, "").Header().Get("ETag")
+	if oldETag == "" {
+		t.Fatal("expected ETag")
+	}
+
+	// Only CODEOWNERS changes; the source content hash stays the same.
+	writeCodeowners(t, dir, "* @org/core\n")
+	if _, err := svc.Init(context.Background(), dir, []string{"go"}, nil, true); err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+
+	after := cacheGet(router, path, oldETag)
+	if after.Code != http.StatusOK {
+		t.Fatalf("status after CODEOWNERS change = %d, want 200", after.Code)
+	}
+	if newETag := after.Header().Get("ETag"); newETag == "" || newETag == oldETag {
+		t.Errorf("ETag = %q, want a new value (old %q)", newETag, oldETag)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/gin-gonic/gin"
+)
+
+// Paging of /callers, /callees, /implementations and /references.
+const (
+	// defaultQueryPageSize is the page size when limit is not given.
+	defaultQueryPageSize = 50
+
+	// maxQueryPageSize caps limit.
+	maxQueryPageSize = 1000
+
+	// queryPageScanLimit is the number of results a paged query collects
+	// before the root and scope filters and paging run. Past it, totals
+	// are lower bounds and later results cannot be reached.
+	queryPageScanLimit = graph.MaxQueryLimit
+)
+
+// PageInfo describes a page of a paged query response.
+type PageInfo struct {
+	// Total is the number of results across all pages.
+	Total int `json:"total"`
+
+	// TotalIsLowerBound is true when the query stopped collecting results
+	// at the scan limit, so Total only counts those.
+	TotalIsLowerBound bool `json:"total_is_lower_bound,omitempty"`
+
+	// NextCursor fetches the next page when passed as cursor. Empty on
+	// the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// queryPage is the position of a paged query request.
+type queryPage struct {
+	offset     int
+	limit      int
+	generation string
+	query      []string
+}
+
+// pageQuery resolves the limit and cursor parameters of a paged query.
+//
+// Description:
+//
+//	A limit of zero or less uses defaultQueryPageSize; larger limits are
+//	clamped to maxQueryPageSize. The cursor must come from the same query
+//	against the same graph generation (see graphGeneration), so a rebuild
+//	that changes the graph invalidates it.
+//
+// Inputs:
+//
+//	c - The request. Receives the error response on failure.
+//	graphID - The queried graph.
+//	limit - The limit parameter.
+//	cursor - The cursor parameter.
+//	query - The parameters selecting the results, e.g. endpoint and name.
+//
+// Outputs:
+//
+//	queryPage - The page to return.
+//	bool - False if an error response was written: 400 for a missing
+//	  graph or malformed cursor, 409 CURSOR_EXPIRED for a stale cursor.
+func (h *Handlers) pageQuery(c *gin.Context, graphID string, limit int, cursor string, query ...string) (queryPage, bool) {
+	page := queryPage{limit: limit, query: query}
+	switch {
+	case page.limit <= 0:
+		page.limit = defaultQueryPageSize
+	case page.limit > maxQueryPageSize:
+		page.limit = maxQueryPageSize
+	}
+
+	cached, err := h.svc.GetGraph(graphID)
+	if err != nil {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
+		return page, false
+	}
+	page.generation = graphGeneration(cached)
+
+	page.offset, err = graph.DecodeCursor(cursor, page.generation, query...)
+	switch {
+	case errors.Is(err, graph.ErrStaleCursor):
+		apierror.New(http.StatusConflict, apierror.CodeCursorExpired, err.Error()).
+			WithDetails("The graph was rebuilt since the first page; repeat the query without a cursor").Write(c)
+		return page, false
+	case err != nil:
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+		return page, false
+	}
+	return page, true
+}
+
+// pageOf returns the page of items, the filtered results of a query, and
+// its PageInfo.
+//
+// Inputs:
+//
+//	items - All results, in query order.
+//	page - The requested page.
+//	scanFull - True if the query stopped at queryPageScanLimit.
+//
+// Outputs:
+//
+//	[]T - The page's items; empty past the end.
+//	PageInfo - The total and the next page's cursor.
+func pageOf[T any](items []T, page queryPage, scanFull bool) ([]T, PageInfo) {
+	info := PageInfo{Total: len(items), TotalIsLowerBound: scanFull}
+	start := min(page.offset, len(items))
+	end := min(start+page.limit, len(items))
+	if end < len(items) {
+		info.NextCursor = graph.EncodeCursor(page.generation, end, page.query...)
+	}
+	return items[start:end], info
+}
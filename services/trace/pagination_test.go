@@ -0,0 +1,133 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/gin-gonic/gin"
+)
+
+// setupPagingTest builds a project where seven functions call logf and
+// returns the project dir, service, router and graph ID.
+func setupPagingTest(t *testing.T) (string, *Service, *gin.Engine, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	dir := t.TempDir()
+	var src strings.Builder
+	src.WriteString("package main\n\nfunc logf(s string) {}\n")
+	for i := range 7 {
+		fmt.Fprintf(&src, "\nfunc f%d() { logf(\"x\") }\n", i)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(src.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	return dir, svc, setupTestRouter(svc), resp.GraphID
+}
+
+func TestPaging_Callers(t *testing.T) {
+	_, _, router, graphID := setupPagingTest(t)
+
+	var names []string
+	cursor := ""
+	for pages := 1; ; pages++ {
+		path := "/v1/trace/callers?graph_id=" + graphID + "&function=logf&limit=3&cursor=" + url.QueryEscape(cursor)
+		w := cacheGet(router, path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("page %d: status %d: %s", pages, w.Code, w.Body.String())
+		}
+		var resp CallersResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Total != 7 || resp.TotalIsLowerBound {
+			t.Errorf("page %d: total = %d (lower bound %v), want 7", pages, resp.Total, resp.TotalIsLowerBound)
+		}
+		for _, caller := range resp.Callers {
+			names = append(names, caller.Name)
+		}
+		if resp.NextCursor == "" {
+			if pages != 3 {
+				t.Errorf("%d pages, want 3", pages)
+			}
+			break
+		}
+		if len(resp.Callers) != 3 {
+			t.Errorf("page %d has %d callers, want 3", pages, len(resp.Callers))
+		}
+		cursor = resp.NextCursor
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range names {
+		seen[name] = true
+	}
+	if len(names) != 7 || len(seen) != 7 {
+		t.Errorf("callers across pages = %v, want f0..f6 once each", names)
+	}
+}
+
+func TestPaging_CursorErrors(t *testing.T) {
+	dir, svc, router, graphID := setupPagingTest(t)
+
+	first := cacheGet(router, "/v1/trace/references?graph_id="+graphID+"&symbol=logf&limit=2", "")
+	var resp ReferencesResponse
+	if err := json.Unmarshal(first.Body.Bytes(), &resp); err != nil || resp.NextCursor == "" {
+		t.Fatalf("first page: %v, %s", err, first.Body.String())
+	}
+	if len(resp.References) != 2 || resp.Total != 7 {
+		t.Errorf("first page has %d of %d references, want 2 of 7", len(resp.References), resp.Total)
+	}
+
+	t.Run("cursor of another query", func(t *testing.T) {
+		w := cacheGet(router, "/v1/trace/callers?graph_id="+graphID+"&function=logf&cursor="+resp.NextCursor, "")
+		if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), string(apierror.CodeInvalidParameter)) {
+			t.Errorf("status %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("malformed cursor", func(t *testing.T) {
+		w := cacheGet(router, "/v1/trace/callees?graph_id="+graphID+"&function=f1&cursor=%21%21", "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("status %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("graph rebuilt", func(t *testing.T) {
+		extra := "package main\n\nfunc g() { logf(\"y\") }\n"
+		if err := os.WriteFile(filepath.Join(dir, "g.go"), []byte(extra), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.Init(context.Background(), dir, []string{"go"}, nil, true); err != nil {
+			t.Fatalf("rebuild failed: %v", err)
+		}
+		w := cacheGet(router, "/v1/trace/references?graph_id="+graphID+"&symbol=logf&limit=2&cursor="+resp.NextCursor, "")
+		if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), string(apierror.CodeCursorExpired)) {
+			t.Errorf("status %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
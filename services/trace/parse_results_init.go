@@ -0,0 +1,363 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// ParseResultsInitResponse is the response for
+// POST /v1/trace/init/from-parse-results.
+type ParseResultsInitResponse struct {
+	InitResponse
+
+	// Build holds the builder's statistics.
+	Build InitBuildStats `json:"build"`
+}
+
+// InitBuildStats reports the statistics of a graph build
+// (see graph.BuildStats).
+type InitBuildStats struct {
+	FilesProcessed      int   `json:"files_processed"`
+	FilesFailed         int   `json:"files_failed"`
+	NodesCreated        int   `json:"nodes_created"`
+	EdgesCreated        int   `json:"edges_created"`
+	PlaceholderNodes    int   `json:"placeholder_nodes"`
+	PlaceholdersOmitted int   `json:"placeholder_edges_omitted"`
+	StdlibEnriched      int   `json:"stdlib_placeholders_enriched"`
+	AmbiguousResolves   int   `json:"ambiguous_resolves"`
+	CallEdgesResolved   int   `json:"call_edges_resolved"`
+	CallEdgesUnresolved int   `json:"call_edges_unresolved"`
+	InterfaceEdges      int   `json:"interface_edges"`
+	DurationMicro       int64 `json:"duration_us"`
+
+	// Incomplete is true if the build stopped early at a limit.
+	Incomplete bool `json:"incomplete"`
+}
+
+// DecodeParseResults reads parse results from r.
+//
+// Description:
+//
+//	Accepts either a JSON array of ast.ParseResult or newline-delimited
+//	JSON with one result per line; the first non-space byte decides.
+//	Unknown fields are rejected so a client built against a different
+//	ParseResult schema fails loudly instead of losing data.
+//
+// Inputs:
+//
+//	r - The (decompressed) body. The caller bounds its total size.
+//	maxRecordBytes - Maximum encoded size of one result. 0 means no limit.
+//	maxRecords - Maximum number of results. 0 means no limit.
+//
+// Outputs:
+//
+//	[]*ast.ParseResult - The decoded results.
+//	error - ErrInvalidParseResult for malformed input, ErrPayloadTooLarge
+//	  if a record exceeds maxRecordBytes, ErrProjectTooLarge if there are
+//	  more than maxRecords, or the reader's error.
+func DecodeParseResults(r io.Reader, maxRecordBytes int64, maxRecords int) ([]*ast.ParseResult, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(br)
+	isArray := first == '['
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParseResult, err)
+		}
+	}
+
+	var results []*ast.ParseResult
+	for {
+		if isArray && !dec.More() {
+			break
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if !isArray && err == io.EOF {
+				break
+			}
+			return nil, decodeReadError(err, len(results))
+		}
+		if maxRecordBytes > 0 && int64(len(raw)) > maxRecordBytes {
+			return nil, fmt.Errorf("%w: record %d is %d bytes, limit %d", ErrPayloadTooLarge, len(results), len(raw), maxRecordBytes)
+		}
+		if maxRecords > 0 && len(results) >= maxRecords {
+			return nil, fmt.Errorf("%w: more than %d parse results", ErrProjectTooLarge, maxRecords)
+		}
+
+		var pr ast.ParseResult
+		rd := json.NewDecoder(bytes.NewReader(raw))
+		rd.DisallowUnknownFields()
+		if err := rd.Decode(&pr); err != nil {
+			return nil, fmt.Errorf("%w: record %d: %v", ErrInvalidParseResult, len(results), err)
+		}
+		results = append(results, &pr)
+	}
+	if isArray {
+		if _, err := dec.Token(); err != nil {
+			return nil, decodeReadError(err, len(results))
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			return nil, fmt.Errorf("%w: data after the closing bracket", ErrInvalidParseResult)
+		}
+	}
+	return results, nil
+}
+
+// peekNonSpace returns the first non-whitespace byte of br without
+// consuming it.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, br.UnreadByte()
+		}
+	}
+}
+
+// decodeReadError classifies an error from reading record n: the body
+// limit and transport errors pass through, anything else is malformed
+// JSON.
+func decodeReadError(err error, n int) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: body exceeds %d bytes", ErrPayloadTooLarge, maxBytesErr.Limit)
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: record %d: %v", ErrInvalidParseResult, n, err)
+	}
+	return err
+}
+
+// InitFromParseResults builds a graph from parse results computed elsewhere.
+//
+// Description:
+//
+//	Runs the graph builder on results produced by another tool (or a
+//	test), skipping file discovery and parsing, so nothing under
+//	projectRoot has to exist on this machine. The graph is cached under
+//	its own ID, distinct from the working-tree graph of the same root,
+//	and is neither LSP-enriched nor snapshotted.
+//
+//	Every result must pass ast.ParseResult.Validate, have a clean
+//	relative slash-separated FilePath that no other result uses and that
+//	all its symbols share, and name a language this service has a parser
+//	for.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation. MaxInitDuration applies.
+//	projectRoot - Absolute project root the paths are relative to. Must
+//	  be within the allowed roots.
+//	results - The parse results.
+//
+// Outputs:
+//
+//	*ParseResultsInitResponse - Graph ID and build statistics.
+//	error - ErrRelativePath, ErrPathTraversal, ErrRootNotAllowed,
+//	  ErrInvalidParseResult, ErrProjectTooLarge, or a build error.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) InitFromParseResults(ctx context.Context, projectRoot string, results []*ast.ParseResult) (*ParseResultsInitResponse, error) {
+	if !filepath.IsAbs(projectRoot) {
+		return nil, ErrRelativePath
+	}
+	if strings.Contains(projectRoot, "..") {
+		return nil, ErrPathTraversal
+	}
+	if _, err := s.CheckRoot(projectRoot); err != nil {
+		return nil, err
+	}
+	projectRoot = filepath.Clean(projectRoot)
+
+	if s.config.MaxProjectFiles > 0 && len(results) > s.config.MaxProjectFiles {
+		return nil, fmt.Errorf("%w: %d parse results, limit %d", ErrProjectTooLarge, len(results), s.config.MaxProjectFiles)
+	}
+	if err := s.validateParseResults(results); err != nil {
+		return nil, err
+	}
+	for _, pr := range results {
+		// Content is not available here, so only the globs and a
+		// client-supplied generated flag apply.
+		if pr.Generated || s.generated.IsGenerated(pr.FilePath, nil) {
+			pr.MarkGenerated()
+		}
+	}
+
+	if s.config.MaxInitDuration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.config.MaxInitDuration)
+		defer cancel()
+	}
+	start := time.Now()
+
+	graphID := s.parseResultsGraphID(projectRoot)
+	s.mu.RLock()
+	_, isRefresh := s.graphs[graphID]
+	s.mu.RUnlock()
+
+	s.graphBuilds.Add(1)
+	buildResult, err := graph.NewBuilder(graph.WithProjectRoot(projectRoot)).Build(ctx, results)
+	if err != nil {
+		slog.Warn("graph build from parse results failed",
+			telemetry.SensitiveLog("project_root", projectRoot),
+			telemetry.SensitiveLogError("error", err),
+		)
+		return nil, fmt.Errorf("building graph: %w", err)
+	}
+
+	var buildErrors []string
+	for _, fe := range buildResult.FileErrors {
+		buildErrors = append(buildErrors, fe.Error())
+	}
+	for _, ee := range buildResult.EdgeErrors {
+		buildErrors = append(buildErrors, ee.Error())
+	}
+
+	cached := s.newCachedGraph(projectRoot, results, buildResult)
+	cached.FromParseResults = true
+	set, annotationsErr := s.recordAnnotations(ctx, cached.Graph, projectRoot, false)
+	if annotationsErr != nil {
+		buildErrors = append(buildErrors, annotationsErr.Error())
+	}
+	cached.Annotations = set
+	if s.config.GraphTTL > 0 {
+		cached.ExpiresAtMilli = time.Now().Add(s.config.GraphTTL).UnixMilli()
+	}
+	if err := s.swapGraph(ctx, graphID, cached); err != nil {
+		return nil, err
+	}
+
+	stats := buildResult.Stats
+	resp := &ParseResultsInitResponse{
+		InitResponse: InitResponse{
+			GraphID:          graphID,
+			IsRefresh:        isRefresh,
+			FilesParsed:      stats.FilesProcessed,
+			SymbolsExtracted: cached.Index.Stats().TotalSymbols,
+			EdgesBuilt:       cached.Graph.EdgeCount(),
+			ParseTimeMs:      time.Since(start).Milliseconds(),
+			Errors:           buildErrors,
+			Status:           InitStatusBuilt,
+		},
+		Build: InitBuildStats{
+			FilesProcessed:      stats.FilesProcessed,
+			FilesFailed:         stats.FilesFailed,
+			NodesCreated:        stats.NodesCreated,
+			EdgesCreated:        stats.EdgesCreated,
+			PlaceholderNodes:    stats.PlaceholderNodes,
+			PlaceholdersOmitted: stats.PlaceholderEdgesOmitted,
+			StdlibEnriched:      stats.StdlibPlaceholdersEnriched,
+			AmbiguousResolves:   stats.AmbiguousResolves,
+			CallEdgesResolved:   stats.CallEdgesResolved,
+			CallEdgesUnresolved: stats.CallEdgesUnresolved,
+			InterfaceEdges:      stats.GoInterfaceEdges,
+			DurationMicro:       stats.DurationMicro,
+			Incomplete:          buildResult.Incomplete,
+		},
+	}
+	if isRefresh {
+		resp.PreviousID = graphID
+	}
+	return resp, nil
+}
+
+// validateParseResults applies the checks InitFromParseResults documents.
+func (s *Service) validateParseResults(results []*ast.ParseResult) error {
+	seen := make(map[string]bool, len(results))
+	for i, pr := range results {
+		if pr == nil {
+			return fmt.Errorf("%w: result %d is null", ErrInvalidParseResult, i)
+		}
+		if err := validateParseResultPath(pr.FilePath); err != nil {
+			return fmt.Errorf("%w: result %d: %v", ErrInvalidParseResult, i, err)
+		}
+		if seen[pr.FilePath] {
+			return fmt.Errorf("%w: result %d: duplicate file_path %q", ErrInvalidParseResult, i, pr.FilePath)
+		}
+		seen[pr.FilePath] = true
+		if _, ok := s.registry.GetByLanguage(pr.Language); !ok {
+			return fmt.Errorf("%w: result %d (%s): unsupported language %q", ErrInvalidParseResult, i, pr.FilePath, pr.Language)
+		}
+		if err := checkSymbolPaths(pr.Symbols, pr.FilePath); err != nil {
+			return fmt.Errorf("%w: result %d (%s): %v", ErrInvalidParseResult, i, pr.FilePath, err)
+		}
+		if err := pr.Validate(); err != nil {
+			return fmt.Errorf("%w: result %d (%s): %v", ErrInvalidParseResult, i, pr.FilePath, err)
+		}
+	}
+	return nil
+}
+
+// validateParseResultPath checks that p is a clean, relative,
+// slash-separated path inside the project, as file discovery produces.
+func validateParseResultPath(p string) error {
+	switch {
+	case p == "":
+		return fmt.Errorf("file_path must not be empty")
+	case strings.ContainsRune(p, 0):
+		return fmt.Errorf("file_path %q contains a NUL byte", p)
+	case strings.Contains(p, `\`):
+		return fmt.Errorf("file_path %q must use forward slashes", p)
+	case path.IsAbs(p) || filepath.IsAbs(p) || filepath.VolumeName(p) != "":
+		return fmt.Errorf("file_path %q must be relative to the project root", p)
+	case strings.Contains(p, ".."):
+		return fmt.Errorf("file_path %q must not contain path traversal (..)", p)
+	case path.Clean(p) != p || p == ".":
+		return fmt.Errorf("file_path %q is not a clean path", p)
+	}
+	return nil
+}
+
+// checkSymbolPaths checks that every symbol in the tree is non-nil and
+// belongs to filePath.
+func checkSymbolPaths(symbols []*ast.Symbol, filePath string) error {
+	for _, sym := range symbols {
+		if sym == nil {
+			return fmt.Errorf("null symbol")
+		}
+		if sym.FilePath != filePath {
+			return fmt.Errorf("symbol %q has file_path %q, want %q", sym.Name, sym.FilePath, filePath)
+		}
+		if err := checkSymbolPaths(sym.Children, filePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// parseGoFiles parses files with the Go parser, as a client building parse
+// results offline would.
+func parseGoFiles(t *testing.T, files map[string]string) []*ast.ParseResult {
+	t.Helper()
+	parser := ast.NewGoParser()
+	var results []*ast.ParseResult
+	for path, content := range files {
+		pr, err := parser.Parse(context.Background(), []byte(content), path)
+		if err != nil {
+			t.Fatalf("Parse(%s) failed: %v", path, err)
+		}
+		results = append(results, pr)
+	}
+	return results
+}
+
+func postParseResults(router http.Handler, root string, body []byte, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/v1/trace/init/from-parse-results?project_root="+url.QueryEscape(root), bytes.NewReader(body))
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func encodeNDJSON(t *testing.T, results []*ast.ParseResult) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, pr := range results {
+		if err := enc.Encode(pr); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+var parseResultsFixture = map[string]string{
+	"app/main.go": `package main
+
+import "example.com/demo/lib"
+
+func main() { lib.Helper() }
+`,
+	"lib/lib.go": `package lib
+
+func Helper() { inner() }
+
+func inner() {}
+`,
+}
+
+func TestHandleInitFromParseResults(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	root := t.TempDir()
+	results := parseGoFiles(t, parseResultsFixture)
+
+	t.Run("ndjson", func(t *testing.T) {
+		w := postParseResults(router, root, encodeNDJSON(t, results), map[string]string{"Content-Type": "application/x-ndjson"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body %s", w.Code, w.Body)
+		}
+		var resp ParseResultsInitResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Build.FilesProcessed != 2 || resp.Build.NodesCreated == 0 || resp.EdgesBuilt == 0 {
+			t.Errorf("build stats = %+v, edges %d", resp.Build, resp.EdgesBuilt)
+		}
+		if resp.GraphID == svc.projectGraphID(root, "") {
+			t.Error("graph shares the working tree's graph ID")
+		}
+
+		cached, err := svc.GetGraph(resp.GraphID)
+		if err != nil {
+			t.Fatalf("GetGraph failed: %v", err)
+		}
+		if !cached.FromParseResults || svc.cachedGraphID(cached) != resp.GraphID {
+			t.Errorf("cached graph = from_parse_results %v, id %s", cached.FromParseResults, svc.cachedGraphID(cached))
+		}
+		callers, err := cached.Graph.FindCallersByName(context.Background(), "inner")
+		found := 0
+		for _, res := range callers {
+			found += len(res.Symbols)
+		}
+		if err != nil || found != 1 {
+			t.Errorf("FindCallersByName(inner) = %d callers, %v", found, err)
+		}
+	})
+
+	t.Run("gzip array", func(t *testing.T) {
+		data, err := json.Marshal(results)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(data)
+		gz.Close()
+
+		w := postParseResults(router, root, buf.Bytes(), map[string]string{"Content-Type": "application/json", "Content-Encoding": "gzip"})
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, body %s", w.Code, w.Body)
+		}
+		var resp ParseResultsInitResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if !resp.IsRefresh || resp.FilesParsed != 2 {
+			t.Errorf("is_refresh = %v, files_parsed = %d", resp.IsRefresh, resp.FilesParsed)
+		}
+	})
+}
+
+func TestHandleInitFromParseResults_Rejects(t *testing.T) {
+	root := t.TempDir()
+	valid := encodeNDJSON(t, parseGoFiles(t, parseResultsFixture))
+
+	withResult := func(mutate func(pr *ast.ParseResult)) []byte {
+		results := parseGoFiles(t, map[string]string{"a.go": "package a\n\nfunc A() {}\n"})
+		mutate(results[0])
+		return encodeNDJSON(t, results)
+	}
+
+	tests := []struct {
+		name   string
+		root   string
+		body   []byte
+		status int
+		code   apierror.Code
+	}{
+		{"relative root", "project", valid, http.StatusBadRequest, apierror.CodeInvalidPath},
+		{"malformed json", root, []byte(`{"file_path":`), http.StatusBadRequest, apierror.CodeInvalidParseResult},
+		{"unknown field", root, []byte(`{"file_path":"a.go","language":"go","bogus":1}`), http.StatusBadRequest, apierror.CodeInvalidParseResult},
+		{"traversal", root, withResult(func(pr *ast.ParseResult) { pr.FilePath = "../a.go" }), http.StatusBadRequest, apierror.CodeInvalidParseResult},
+		{"absolute path", root, withResult(func(pr *ast.ParseResult) { pr.FilePath = "/etc/a.go" }), http.StatusBadRequest, apierror.CodeInvalidParseResult},
+		{"unclean path", root, withResult(func(pr *ast.ParseResult) { pr.FilePath = "./a.go" }), http.StatusBadRequest, apierror.CodeInvalidParseResult},
+		{"symbol in another file", root, withResult(func(pr *ast.ParseResult) { pr.Symbols[0].FilePath = "b.go" }), http.StatusBadRequest, apierror.CodeInvalidParseResult},
+		{"unsupported language", root, withResult(func(pr *ast.ParseResult) { pr.Language = "cobol" }), http.StatusBadRequest, apierror.CodeInvalidParseResult},
+		{"oversized record", root, withResult(func(pr *ast.ParseResult) { pr.Errors = []string{strings.Repeat("x", 4096)} }), http.StatusRequestEntityTooLarge, apierror.CodePayloadTooLarge},
+		{"oversized body", root, bytes.Repeat(valid, 8), http.StatusRequestEntityTooLarge, apierror.CodePayloadTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultServiceConfig()
+			cfg.MaxParseResultBytes = 2048
+			cfg.MaxParseResultsBytes = int64(len(valid)) * 4
+			svc := NewService(cfg)
+			w := postParseResults(setupTestRouter(svc), tt.root, tt.body, nil)
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), string(tt.code)) {
+				t.Errorf("got %d %s, want %d %s", w.Code, w.Body, tt.status, tt.code)
+			}
+			if len(svc.graphs) != 0 {
+				t.Error("a rejected upload cached a graph")
+			}
+		})
+	}
+}
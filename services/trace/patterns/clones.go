@@ -0,0 +1,366 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package patterns
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// CloneOptions configures clone clustering.
+type CloneOptions struct {
+	// MinSimilarity is the minimum similarity of two bodies to link them
+	// (default: 0.8).
+	MinSimilarity float64
+
+	// MinBodyTokens is the minimum normalized body length; shorter bodies
+	// (accessors, one-line wrappers) are ignored (default: 20).
+	MinBodyTokens int
+
+	// IncludeTests includes test files in analysis.
+	IncludeTests bool
+
+	// IncludeGenerated also fingerprints files flagged as generated code.
+	IncludeGenerated bool
+
+	// CrossLanguageOnly keeps only clusters spanning more than one language.
+	CrossLanguageOnly bool
+
+	// MaxClusters limits the number of clusters (0 = unlimited).
+	MaxClusters int
+}
+
+// DefaultCloneOptions returns sensible defaults.
+func DefaultCloneOptions() CloneOptions {
+	return CloneOptions{
+		MinSimilarity: 0.8,
+		MinBodyTokens: 20,
+	}
+}
+
+// newCloneParserRegistry returns parsers that attach normalized body tokens.
+func newCloneParserRegistry() *ast.ParserRegistry {
+	opts := ast.DefaultParseOptions()
+	opts.ExtractBodyTokens = true
+
+	registry := ast.NewParserRegistry()
+	registry.Register(ast.NewGoParser(ast.WithParseOptions(opts)))
+	registry.Register(ast.NewPythonParser(ast.WithPythonParseOptions(opts)))
+	registry.Register(ast.NewTypeScriptParser(ast.WithTypeScriptParseOptions(opts)))
+	registry.Register(ast.NewJavaScriptParser(ast.WithJSExtractBodyTokens(true)))
+	return registry
+}
+
+// FindClones groups functions with near-identical bodies into clusters.
+//
+// # Description
+//
+// Unlike FindDuplication, which compares raw source, this re-parses the
+// files of the index's functions and methods with ExtractBodyTokens, so
+// identifiers, literals and language syntax are normalized away and the
+// same logic in Go, TypeScript, JavaScript and Python matches. Each body
+// is fingerprinted with winnowed k-gram hashes and a MinHash signature
+// (FingerprintTokens); only fingerprints are kept, never body text.
+// Candidates come from an LSH index and are linked when the Jaccard
+// similarity of their winnowed hashes is at least MinSimilarity.
+// Clusters are the connected components of those links.
+//
+// # Inputs
+//
+//   - ctx: Context for cancellation.
+//   - scope: File path prefix; a cluster is kept if any member is in
+//     scope (empty = all).
+//   - opts: Clustering options. Nil uses DefaultCloneOptions.
+//
+// # Outputs
+//
+//   - []DuplicationCluster: Clusters, largest first, then by similarity.
+//   - error: Non-nil on invalid input or cancellation.
+//
+// # Thread Safety
+//
+// Safe for concurrent use; uses no state shared with FindDuplication.
+func (d *DuplicationFinder) FindClones(
+	ctx context.Context,
+	scope string,
+	opts *CloneOptions,
+) ([]DuplicationCluster, error) {
+	if ctx == nil {
+		return nil, ErrInvalidInput
+	}
+	if opts == nil {
+		defaults := DefaultCloneOptions()
+		opts = &defaults
+	}
+	if opts.MinSimilarity <= 0 || opts.MinSimilarity > 1 {
+		return nil, fmt.Errorf("%w: min similarity %v not in (0, 1]", ErrInvalidInput, opts.MinSimilarity)
+	}
+
+	start := time.Now()
+	ctx, span := startCloneClusterSpan(ctx, scope, opts.MinSimilarity)
+	defer span.End()
+
+	fingerprints, err := d.fingerprintBodies(ctx, opts)
+	if err != nil {
+		setDuplicationSpanResult(span, 0, err)
+		return nil, err
+	}
+
+	// 25 bands x 4 rows: candidates down to ~45% similarity, below any
+	// useful threshold; exact Jaccard on the winnowed hashes decides.
+	lsh := NewLSHIndex(25, 4)
+	for _, fp := range fingerprints {
+		lsh.Add(fp)
+	}
+
+	uf := newUnionFind(len(fingerprints))
+	position := make(map[string]int, len(fingerprints))
+	for i, fp := range fingerprints {
+		position[fp.SymbolID] = i
+	}
+	linkSimilarity := make(map[int]float64)
+	checked := make(map[string]bool)
+	for i, fp := range fingerprints {
+		if ctx.Err() != nil {
+			setDuplicationSpanResult(span, 0, ctx.Err())
+			return nil, ctx.Err()
+		}
+		for _, id := range lsh.Query(fp) {
+			key := canonicalPairKey(fp.SymbolID, id)
+			if checked[key] {
+				continue
+			}
+			checked[key] = true
+			j, ok := position[id]
+			if !ok {
+				continue
+			}
+			// Platform variants are meant to match; don't cluster them.
+			if fp.IsBuildVariantOf(fingerprints[j]) {
+				continue
+			}
+			similarity := fp.JaccardSimilarity(fingerprints[j])
+			if similarity < opts.MinSimilarity {
+				continue
+			}
+			uf.union(i, j)
+			root := uf.find(i)
+			if current, ok := linkSimilarity[root]; !ok || similarity < current {
+				linkSimilarity[root] = similarity
+			}
+		}
+	}
+
+	// Link similarities were recorded against the root at the time; fold
+	// them onto the final roots.
+	clusterSimilarity := make(map[int]float64)
+	for root, similarity := range linkSimilarity {
+		final := uf.find(root)
+		if current, ok := clusterSimilarity[final]; !ok || similarity < current {
+			clusterSimilarity[final] = similarity
+		}
+	}
+
+	members := make(map[int][]*CodeFingerprint)
+	for i, fp := range fingerprints {
+		root := uf.find(i)
+		if _, linked := clusterSimilarity[root]; linked {
+			members[root] = append(members[root], fp)
+		}
+	}
+
+	var clusters []DuplicationCluster
+	for root, fps := range members {
+		cluster := buildCluster(fps, clusterSimilarity[root])
+		if opts.CrossLanguageOnly && !cluster.CrossLanguage {
+			continue
+		}
+		if scope != "" && !clusterInScope(cluster, scope) {
+			continue
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	sort.Slice(clusters, func(i, j int) bool {
+		if len(clusters[i].Members) != len(clusters[j].Members) {
+			return len(clusters[i].Members) > len(clusters[j].Members)
+		}
+		if clusters[i].Similarity != clusters[j].Similarity {
+			return clusters[i].Similarity > clusters[j].Similarity
+		}
+		return clusters[i].Members[0].SymbolID < clusters[j].Members[0].SymbolID
+	})
+	if opts.MaxClusters > 0 && len(clusters) > opts.MaxClusters {
+		clusters = clusters[:opts.MaxClusters]
+	}
+
+	dur := time.Since(start)
+	setDuplicationSpanResult(span, len(clusters), nil)
+	recordDuplicationMetrics(ctx, dur, len(clusters), nil)
+	d.crs.RecordToolStep(ctx, "find_duplication", len(clusters), dur, nil)
+
+	return clusters, nil
+}
+
+// fingerprintBodies re-parses the files holding indexed functions and
+// fingerprints every body of at least opts.MinBodyTokens tokens.
+func (d *DuplicationFinder) fingerprintBodies(ctx context.Context, opts *CloneOptions) ([]*CodeFingerprint, error) {
+	files := make(map[string]bool)
+	for _, kind := range []ast.SymbolKind{ast.SymbolKindFunction, ast.SymbolKindMethod} {
+		for _, sym := range d.idx.GetByKind(kind) {
+			if !opts.IncludeTests && isTestFile(sym.FilePath) {
+				continue
+			}
+			if !opts.IncludeGenerated && sym.Generated {
+				continue
+			}
+			files[sym.FilePath] = true
+		}
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	registry := newCloneParserRegistry()
+	var fingerprints []*CodeFingerprint
+	for _, path := range paths {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		parser, ok := registry.GetByExtension(filepath.Ext(path))
+		if !ok {
+			continue
+		}
+		content, err := d.fileReader.ReadFile(path)
+		if err != nil {
+			continue // Skip files we can't read
+		}
+		result, err := parser.Parse(ctx, content, path)
+		if err != nil {
+			continue // Skip files we can't parse
+		}
+		fingerprints = d.appendBodyFingerprints(fingerprints, result.Symbols, opts.MinBodyTokens)
+	}
+	return fingerprints, nil
+}
+
+// appendBodyFingerprints fingerprints functions and methods in symbols and
+// their children. The symbols, and with them the token streams, are
+// dropped by the caller after this returns.
+func (d *DuplicationFinder) appendBodyFingerprints(out []*CodeFingerprint, symbols []*ast.Symbol, minTokens int) []*CodeFingerprint {
+	for _, sym := range symbols {
+		if sym == nil {
+			continue
+		}
+		if (sym.Kind == ast.SymbolKindFunction || sym.Kind == ast.SymbolKindMethod) &&
+			len(sym.BodyTokens) >= minTokens {
+			if fp := d.fingerprinter.FingerprintTokens(sym); fp != nil {
+				out = append(out, fp)
+			}
+		}
+		out = d.appendBodyFingerprints(out, sym.Children, minTokens)
+	}
+	return out
+}
+
+// buildCluster converts linked fingerprints into a cluster.
+func buildCluster(fps []*CodeFingerprint, similarity float64) DuplicationCluster {
+	sort.Slice(fps, func(i, j int) bool {
+		if fps[i].FilePath != fps[j].FilePath {
+			return fps[i].FilePath < fps[j].FilePath
+		}
+		return fps[i].LineStart < fps[j].LineStart
+	})
+
+	languageSet := make(map[string]bool)
+	cluster := DuplicationCluster{Similarity: similarity}
+	for _, fp := range fps {
+		cluster.Members = append(cluster.Members, DupLocation{
+			FilePath:  fp.FilePath,
+			LineStart: fp.LineStart,
+			LineEnd:   fp.LineEnd,
+			SymbolID:  fp.SymbolID,
+			Language:  fp.Language,
+		})
+		if !languageSet[fp.Language] {
+			languageSet[fp.Language] = true
+			cluster.Languages = append(cluster.Languages, fp.Language)
+		}
+	}
+	sort.Strings(cluster.Languages)
+	cluster.CrossLanguage = len(cluster.Languages) > 1
+
+	switch {
+	case cluster.CrossLanguage:
+		cluster.Suggestion = fmt.Sprintf("Same logic implemented in %s. Consider generating it from one definition or moving it behind a single service",
+			strings.Join(cluster.Languages, ", "))
+	case len(cluster.Members) > 2:
+		cluster.Suggestion = fmt.Sprintf("%d near-identical functions. Extract the shared logic into one helper", len(cluster.Members))
+	default:
+		cluster.Suggestion = "Similar code patterns detected. Consider parameterizing differences"
+	}
+	return cluster
+}
+
+// clusterInScope reports whether any member's file is under scope.
+func clusterInScope(cluster DuplicationCluster, scope string) bool {
+	for _, m := range cluster.Members {
+		if strings.HasPrefix(m.FilePath, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTestFile reports whether a path looks like a test file in any of the
+// languages clone clustering parses.
+func isTestFile(filePath string) bool {
+	base := filepath.Base(filePath)
+	return strings.HasSuffix(base, "_test.go") ||
+		strings.HasPrefix(base, "test_") || strings.HasSuffix(base, "_test.py") ||
+		strings.Contains(base, ".test.") || strings.Contains(base, ".spec.") ||
+		strings.Contains(filePath, "__tests__/")
+}
+
+// unionFind is a disjoint-set forest over indices.
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &unionFind{parent: parent}
+}
+
+func (u *unionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+func (u *unionFind) union(i, j int) {
+	if ri, rj := u.find(i), u.find(j); ri != rj {
+		u.parent[ri] = rj
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package patterns
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+var cloneTestFiles = map[string]string{
+	"server/validate.go": `package server
+
+import (
+	"errors"
+	"strings"
+)
+
+func ValidateEmail(email string) error {
+	if len(email) == 0 {
+		return errors.New("email is required")
+	}
+	if len(email) > 254 {
+		return errors.New("email is too long")
+	}
+	if !strings.Contains(email, "@") {
+		return errors.New("email must contain @")
+	}
+	return nil
+}
+
+func Total(prices []float64) float64 {
+	sum := 0.0
+	for _, p := range prices {
+		if p > 0 {
+			sum += p
+		}
+	}
+	return sum * 1.2
+}
+`,
+	"web/src/validate.ts": `export function checkAddress(value: string): Error | null {
+  if (value.length === 0) {
+    return new Error("address is required");
+  }
+  if (value.length > 254) {
+    return new Error("address is too long");
+  }
+  if (!value.includes("@")) {
+    return new Error("address must contain @");
+  }
+  return null;
+}
+`,
+	"web/src/validate.test.ts": `export function checkAddressCopy(value: string): Error | null {
+  if (value.length === 0) {
+    return new Error("address is required");
+  }
+  if (value.length > 254) {
+    return new Error("address is too long");
+  }
+  if (!value.includes("@")) {
+    return new Error("address must contain @");
+  }
+  return null;
+}
+`,
+}
+
+// setupCloneProject writes cloneTestFiles and indexes their symbols.
+func setupCloneProject(t *testing.T) (*index.SymbolIndex, string) {
+	t.Helper()
+	return setupCloneProjectFiles(t, cloneTestFiles)
+}
+
+// setupCloneProjectFiles writes files and indexes their functions.
+func setupCloneProjectFiles(t *testing.T, files map[string]string) (*index.SymbolIndex, string) {
+	t.Helper()
+	root := t.TempDir()
+	idx := index.NewSymbolIndex()
+	parsers := map[string]ast.Parser{
+		".go": ast.NewGoParser(),
+		".ts": ast.NewTypeScriptParser(),
+	}
+	for path, content := range files {
+		full := filepath.Join(root, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		result, err := parsers[filepath.Ext(path)].Parse(context.Background(), []byte(content), path)
+		if err != nil {
+			t.Fatalf("parse %s: %v", path, err)
+		}
+		for _, sym := range result.Symbols {
+			if sym.Kind == ast.SymbolKindFunction {
+				if err := idx.Add(sym); err != nil {
+					t.Fatalf("index %s: %v", sym.ID, err)
+				}
+			}
+		}
+	}
+	return idx, root
+}
+
+func TestFindClones_CrossLanguage(t *testing.T) {
+	idx, root := setupCloneProject(t)
+	finder := NewDuplicationFinder(idx, root)
+
+	clusters, err := finder.FindClones(context.Background(), "", nil)
+	if err != nil {
+		t.Fatalf("FindClones: %v", err)
+	}
+	if len(clusters) != 1 {
+		t.Fatalf("got %d clusters, want 1: %+v", len(clusters), clusters)
+	}
+	cluster := clusters[0]
+	if !cluster.CrossLanguage || len(cluster.Languages) != 2 {
+		t.Errorf("languages = %v, cross_language = %v", cluster.Languages, cluster.CrossLanguage)
+	}
+	if cluster.Similarity < 0.99 {
+		t.Errorf("similarity = %v, want 1.0 for identical normalized bodies", cluster.Similarity)
+	}
+	if len(cluster.Members) != 2 {
+		t.Fatalf("members = %+v, want the Go and TypeScript validators", cluster.Members)
+	}
+	goMember, tsMember := cluster.Members[0], cluster.Members[1]
+	if goMember.FilePath != "server/validate.go" || goMember.LineStart != 8 || goMember.LineEnd != 19 || goMember.Language != "go" {
+		t.Errorf("go member = %+v", goMember)
+	}
+	if tsMember.FilePath != "web/src/validate.ts" || tsMember.LineStart != 1 || tsMember.Language != "typescript" {
+		t.Errorf("ts member = %+v", tsMember)
+	}
+}
+
+func TestFindClones_Options(t *testing.T) {
+	idx, root := setupCloneProject(t)
+	finder := NewDuplicationFinder(idx, root)
+	ctx := context.Background()
+
+	t.Run("include tests adds the test copy", func(t *testing.T) {
+		opts := DefaultCloneOptions()
+		opts.IncludeTests = true
+		clusters, err := finder.FindClones(ctx, "", &opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) != 1 || len(clusters[0].Members) != 3 {
+			t.Errorf("clusters = %+v, want one cluster of 3", clusters)
+		}
+	})
+
+	t.Run("min body tokens suppresses small bodies", func(t *testing.T) {
+		opts := DefaultCloneOptions()
+		opts.MinBodyTokens = 100
+		clusters, err := finder.FindClones(ctx, "", &opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) != 0 {
+			t.Errorf("clusters = %+v, want none", clusters)
+		}
+	})
+
+	t.Run("scope keeps clusters with a member in scope", func(t *testing.T) {
+		clusters, err := finder.FindClones(ctx, "web/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) != 1 {
+			t.Errorf("got %d clusters, want 1", len(clusters))
+		}
+		clusters, err = finder.FindClones(ctx, "cmd/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(clusters) != 0 {
+			t.Errorf("got %d clusters out of scope, want 0", len(clusters))
+		}
+	})
+
+	t.Run("invalid similarity", func(t *testing.T) {
+		opts := DefaultCloneOptions()
+		opts.MinSimilarity = 1.5
+		if _, err := finder.FindClones(ctx, "", &opts); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func TestFindClones_SkipsBuildVariants(t *testing.T) {
+	body := `(name string) (*File, error) {
+	if len(name) == 0 {
+		return nil, errors.New("name is required")
+	}
+	if len(name) > 255 {
+		return nil, errors.New("name is too long")
+	}
+	if strings.Contains(name, "\\x00") {
+		return nil, errors.New("name contains NUL")
+	}
+	return &File{name: name}, nil
+}
+`
+	header := "package fsys\n\nimport (\n\t\"errors\"\n\t\"strings\"\n)\n\n"
+	variants := map[string]string{
+		"fsys/open_unix.go":    "//go:build unix\n\n" + header + "func Open" + body,
+		"fsys/open_windows.go": header + "func Open" + body,
+	}
+	ctx := context.Background()
+	opts := DefaultDuplicationOptions()
+	opts.MinLines = 3
+
+	t.Run("variants are not clones", func(t *testing.T) {
+		idx, root := setupCloneProjectFiles(t, variants)
+		finder := NewDuplicationFinder(idx, root)
+		clusters, err := finder.FindClones(ctx, "", nil)
+		if err != nil {
+			t.Fatalf("FindClones: %v", err)
+		}
+		if len(clusters) != 0 {
+			t.Errorf("clusters = %+v, want none", clusters)
+		}
+		dups, err := finder.FindDuplication(ctx, "", &opts)
+		if err != nil {
+			t.Fatalf("FindDuplication: %v", err)
+		}
+		if len(dups) != 0 {
+			t.Errorf("duplications = %+v, want none", dups)
+		}
+	})
+
+	t.Run("a copy under another name still is", func(t *testing.T) {
+		files := map[string]string{"fsys/copy.go": header + "func OpenCopy" + body}
+		for path, content := range variants {
+			files[path] = content
+		}
+		idx, root := setupCloneProjectFiles(t, files)
+		finder := NewDuplicationFinder(idx, root)
+		clusters, err := finder.FindClones(ctx, "", nil)
+		if err != nil {
+			t.Fatalf("FindClones: %v", err)
+		}
+		if len(clusters) != 1 || len(clusters[0].Members) != 3 {
+			t.Errorf("clusters = %+v, want one cluster of 3", clusters)
+		}
+		dups, err := finder.FindDuplication(ctx, "", &opts)
+		if err != nil {
+			t.Fatalf("FindDuplication: %v", err)
+		}
+		if len(dups) != 2 {
+			t.Fatalf("got %d duplications, want OpenCopy with each variant: %+v", len(dups), dups)
+		}
+		for _, dup := range dups {
+			a, b := dup.Locations[0].FilePath, dup.Locations[1].FilePath
+			if a != "fsys/copy.go" && b != "fsys/copy.go" {
+				t.Errorf("variants reported as duplicates: %s, %s", a, b)
+			}
+		}
+	})
+}
+
+func TestWinnow(t *testing.T) {
+	hashes := []uint64{77, 74, 42, 17, 98, 50, 17, 98, 8, 88, 67, 39, 77, 74, 42, 17, 98}
+	got := winnow(hashes, 4)
+	want := []uint64{17, 17, 8, 39, 17}
+	if len(got) != len(want) {
+		t.Fatalf("winnow = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("winnow = %v, want %v", got, want)
+		}
+	}
+}
@@ -14,7 +14,6 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"sort"
 	"strings"
@@ -24,6 +23,7 @@ import (
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
 )
 
 // DeadCodeExclusions configures what to exclude from dead code detection.
@@ -124,6 +124,10 @@ type DeadCodeOptions struct {
 	// IncludeTests includes test files in analysis.
 	IncludeTests bool
 
+	// IncludeGenerated reports unreferenced generated code too, such as
+	// unused mock methods or protobuf getters.
+	IncludeGenerated bool
+
 	// MaxResults limits the number of results (0 = unlimited).
 	MaxResults int
 }
@@ -246,6 +250,11 @@ func (d *DeadCodeFinder) FindDeadCode(
 			continue
 		}
 
+		// Generated code is unreferenced by design (mocks, stubs)
+		if !opts.IncludeGenerated && sym.Generated {
+			continue
+		}
+
 		// Check if referenced
 		if referenced[sym.ID] {
 			continue
@@ -464,7 +473,10 @@ func (d *DeadCodeFinder) implementsInterface(sym *ast.Symbol) bool {
 // hasReflectionPattern checks if a symbol's file uses reflection.
 func (d *DeadCodeFinder) hasReflectionPattern(sym *ast.Symbol, patterns []string) bool {
 	// Read file content
-	filePath := filepath.Join(d.projectRoot, sym.FilePath)
+	filePath, err := validation.ResolveWithin(d.projectRoot, sym.FilePath)
+	if err != nil {
+		return false
+	}
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return false
@@ -483,7 +495,10 @@ func (d *DeadCodeFinder) hasReflectionPattern(sym *ast.Symbol, patterns []string
 
 // hasBuildTag checks if a file has build tags.
 func (d *DeadCodeFinder) hasBuildTag(filePath string) bool {
-	fullPath := filepath.Join(d.projectRoot, filePath)
+	fullPath, err := validation.ResolveWithin(d.projectRoot, filePath)
+	if err != nil {
+		return false
+	}
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
 		return false
@@ -535,7 +550,10 @@ func (d *DeadCodeFinder) hasAnnotation(sym *ast.Symbol, patterns []string) bool
 
 // readSymbolContext reads the source code around a symbol.
 func (d *DeadCodeFinder) readSymbolContext(sym *ast.Symbol) (string, error) {
-	filePath := filepath.Join(d.projectRoot, sym.FilePath)
+	filePath, err := validation.ResolveWithin(d.projectRoot, sym.FilePath)
+	if err != nil {
+		return "", err
+	}
 	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return "", err
@@ -598,7 +616,10 @@ func (d *DeadCodeFinder) calculateConfidence(sym *ast.Symbol, opts *DeadCodeOpti
 
 // hasStructTags checks if a file contains struct tags (suggesting reflection use).
 func (d *DeadCodeFinder) hasStructTags(filePath string) bool {
-	fullPath := filepath.Join(d.projectRoot, filePath)
+	fullPath, err := validation.ResolveWithin(d.projectRoot, filePath)
+	if err != nil {
+		return false
+	}
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
 		return false
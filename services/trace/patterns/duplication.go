@@ -52,6 +52,10 @@ type DuplicationOptions struct {
 	// IncludeTests includes test files in analysis.
 	IncludeTests bool
 
+	// IncludeGenerated includes generated code (ast.Symbol.Generated) in
+	// analysis.
+	IncludeGenerated bool
+
 	// MaxResults limits the number of results (0 = unlimited).
 	MaxResults int
 }
@@ -197,6 +201,11 @@ func (d *DuplicationFinder) BuildIndex(ctx context.Context, opts *DuplicationOpt
 			continue
 		}
 
+		// Skip generated code if not requested
+		if !opts.IncludeGenerated && sym.Generated {
+			continue
+		}
+
 		// Skip symbols below minimum lines
 		lineCount := sym.EndLine - sym.StartLine + 1
 		if lineCount < opts.MinLines {
@@ -302,6 +311,11 @@ func (d *DuplicationFinder) FindDuplication(
 			}
 		}
 
+		// Platform variants (foo_linux.go vs foo_windows.go) are meant to match
+		if fp1.IsBuildVariantOf(fp2) {
+			continue
+		}
+
 		// Determine duplication type
 		dupType := d.classifyDuplication(fp1, fp2, pair.Similarity)
 
@@ -430,7 +444,7 @@ func (d *DuplicationFinder) FindDuplicatesOf(
 		}
 
 		matchFP, ok := d.lshIndex.GetFingerprint(match.SymbolID)
-		if !ok {
+		if !ok || queryFP.IsBuildVariantOf(matchFP) {
 			continue
 		}
 
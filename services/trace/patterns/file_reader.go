@@ -13,11 +13,11 @@ package patterns
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"sync"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
 )
 
 // FileReader provides cached file reading for pattern analysis tools.
@@ -72,7 +72,10 @@ func (r *FileReader) ReadLines(filePath string) ([]string, error) {
 	}
 	r.mu.RUnlock()
 
-	fullPath := filepath.Join(r.projectRoot, filePath)
+	fullPath, err := validation.ResolveWithin(r.projectRoot, filePath)
+	if err != nil {
+		return nil, err
+	}
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
 		return nil, fmt.Errorf("reading file %s: %w", fullPath, err)
@@ -121,6 +124,33 @@ func (r *FileReader) ReadSymbolCode(sym *ast.Symbol) (string, error) {
 	return strings.Join(symbolLines, "\n"), nil
 }
 
+// ReadFile reads a whole file without caching it.
+//
+// # Description
+//
+// For one-pass consumers that parse each file once, where caching every
+// file's lines would only grow memory.
+//
+// # Inputs
+//
+//   - filePath: Relative file path (joined with projectRoot).
+//
+// # Outputs
+//
+//   - []byte: File contents.
+//   - error: Non-nil on I/O failure.
+func (r *FileReader) ReadFile(filePath string) ([]byte, error) {
+	fullPath, err := validation.ResolveWithin(r.projectRoot, filePath)
+	if err != nil {
+		return nil, err
+	}
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading file %s: %w", fullPath, err)
+	}
+	return content, nil
+}
+
 // CacheSize returns the number of cached files.
 func (r *FileReader) CacheSize() int {
 	r.mu.RLock()
@@ -28,6 +28,11 @@ type FingerprintConfig struct {
 
 	// NormalizeIdentifiers removes identifier differences.
 	NormalizeIdentifiers bool
+
+	// WinnowWindow is the winnowing window for token-stream fingerprints:
+	// the smallest k-gram hash of every WinnowWindow consecutive hashes is
+	// kept. Used by FingerprintTokens only.
+	WinnowWindow int
 }
 
 // DefaultFingerprintConfig returns sensible defaults.
@@ -36,6 +41,7 @@ func DefaultFingerprintConfig() FingerprintConfig {
 		KGramSize:            5,
 		NumHashFuncs:         100,
 		NormalizeIdentifiers: true,
+		WinnowWindow:         4,
 	}
 }
 
@@ -57,6 +63,9 @@ type CodeFingerprint struct {
 	// FilePath is the source file path.
 	FilePath string
 
+	// Language is the source language of the symbol.
+	Language string
+
 	// LineStart is the starting line of the code.
 	LineStart int
 
@@ -80,6 +89,48 @@ type CodeFingerprint struct {
 
 	// Complexity is a cyclomatic complexity estimate.
 	Complexity int
+
+	// Name, Kind and Receiver identify the source declaration.
+	Name     string
+	Kind     ast.SymbolKind
+	Receiver string
+
+	// BuildConstraint is the Go build constraint of the source file.
+	BuildConstraint string
+}
+
+// IsBuildVariantOf reports whether fp and other fingerprint platform
+// variants of one declaration (see ast.IsBuildVariant), e.g. the same
+// function in file_unix.go and file_windows.go. Their similarity is
+// expected and not reported as duplication.
+func (fp *CodeFingerprint) IsBuildVariantOf(other *CodeFingerprint) bool {
+	if fp == nil || other == nil {
+		return false
+	}
+	return ast.IsBuildVariant(fp.declaration(), other.declaration())
+}
+
+// declaration rebuilds the identifying fields of the source symbol.
+func (fp *CodeFingerprint) declaration() *ast.Symbol {
+	sym := &ast.Symbol{
+		Name:     fp.Name,
+		Kind:     fp.Kind,
+		Receiver: fp.Receiver,
+		FilePath: fp.FilePath,
+		Language: fp.Language,
+	}
+	if fp.BuildConstraint != "" {
+		sym.Metadata = &ast.SymbolMetadata{BuildConstraint: fp.BuildConstraint}
+	}
+	return sym
+}
+
+// symbolBuildConstraint returns the build constraint recorded on sym.
+func symbolBuildConstraint(sym *ast.Symbol) string {
+	if sym.Metadata == nil {
+		return ""
+	}
+	return sym.Metadata.BuildConstraint
 }
 
 // Fingerprinter creates code fingerprints from symbols.
@@ -195,6 +246,7 @@ func (f *Fingerprinter) Fingerprint(symbol *ast.Symbol, code string) *CodeFinger
 	return &CodeFingerprint{
 		SymbolID:     symbol.ID,
 		FilePath:     symbol.FilePath,
+		Language:     symbol.Language,
 		LineStart:    symbol.StartLine,
 		LineEnd:      symbol.EndLine,
 		TokenHashes:  tokenHashes,
@@ -203,7 +255,101 @@ func (f *Fingerprinter) Fingerprint(symbol *ast.Symbol, code string) *CodeFinger
 		TokenCount:   len(tokens),
 		LineCount:    symbol.EndLine - symbol.StartLine + 1,
 		Complexity:   complexity,
+
+		Name:            symbol.Name,
+		Kind:            symbol.Kind,
+		Receiver:        symbol.Receiver,
+		BuildConstraint: symbolBuildConstraint(symbol),
+	}
+}
+
+// FingerprintTokens creates a fingerprint from a symbol's normalized body.
+//
+// # Description
+//
+// Uses the parser-normalized ast.BodyTokens instead of raw source, so the
+// same logic in different languages gets the same fingerprint. K-grams of
+// tokens are hashed and winnowed (the minimum hash of each window of
+// WinnowWindow k-grams is kept), and the MinHash signature is computed
+// from the winnowed set. TokenHashes holds the winnowed hashes.
+//
+// # Inputs
+//
+//   - symbol: The symbol to fingerprint. Must have BodyTokens.
+//
+// # Outputs
+//
+//   - *CodeFingerprint: The computed fingerprint, or nil if the symbol
+//     has no body tokens.
+func (f *Fingerprinter) FingerprintTokens(symbol *ast.Symbol) *CodeFingerprint {
+	if symbol == nil || len(symbol.BodyTokens) == 0 {
+		return nil
+	}
+	tokens := symbol.BodyTokens
+
+	k := min(f.config.KGramSize, len(tokens))
+	hashes := make([]uint64, 0, len(tokens)-k+1)
+	buf := make([]byte, k)
+	for i := 0; i+k <= len(tokens); i++ {
+		for j := 0; j < k; j++ {
+			buf[j] = byte(tokens[i+j])
+		}
+		h := fnv.New64a()
+		h.Write(buf)
+		hashes = append(hashes, h.Sum64())
+	}
+
+	winnowed := winnow(hashes, f.config.WinnowWindow)
+	complexity := 1
+	for _, tok := range tokens {
+		switch tok {
+		case ast.BodyTokenIf, ast.BodyTokenElse, ast.BodyTokenLoop, ast.BodyTokenCase,
+			ast.BodyTokenCatch, ast.BodyTokenAnd, ast.BodyTokenOr:
+			complexity++
+		}
+	}
+
+	return &CodeFingerprint{
+		SymbolID:    symbol.ID,
+		FilePath:    symbol.FilePath,
+		Language:    symbol.Language,
+		LineStart:   symbol.StartLine,
+		LineEnd:     symbol.EndLine,
+		TokenHashes: winnowed,
+		MinHashSig:  f.computeMinHash(winnowed),
+		TokenCount:  len(tokens),
+		LineCount:   symbol.EndLine - symbol.StartLine + 1,
+		Complexity:  complexity,
+
+		Name:            symbol.Name,
+		Kind:            symbol.Kind,
+		Receiver:        symbol.Receiver,
+		BuildConstraint: symbolBuildConstraint(symbol),
+	}
+}
+
+// winnow keeps the minimum hash of each window of w consecutive hashes,
+// without repeats. Fewer than w hashes are all kept.
+func winnow(hashes []uint64, w int) []uint64 {
+	if w <= 1 || len(hashes) <= w {
+		return hashes
+	}
+	selected := make([]uint64, 0, 2*len(hashes)/w+1)
+	lastPos := -1
+	for start := 0; start+w <= len(hashes); start++ {
+		minPos := start
+		for i := start + 1; i < start+w; i++ {
+			// Rightmost minimum, as in the winnowing paper.
+			if hashes[i] <= hashes[minPos] {
+				minPos = i
+			}
+		}
+		if minPos != lastPos {
+			selected = append(selected, hashes[minPos])
+			lastPos = minPos
+		}
 	}
+	return selected
 }
 
 // tokenize splits code into tokens.
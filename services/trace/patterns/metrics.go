@@ -176,6 +176,16 @@ func startDuplicationSpan(ctx context.Context, scope string) (context.Context, t
 	)
 }
 
+// startCloneClusterSpan creates a span for clone clustering.
+func startCloneClusterSpan(ctx context.Context, scope string, minSimilarity float64) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "patterns.DuplicationFinder.FindClones",
+		trace.WithAttributes(
+			attribute.String("duplication.scope", scope),
+			attribute.Float64("duplication.min_similarity", minSimilarity),
+		),
+	)
+}
+
 // setDuplicationSpanResult sets result attributes on a duplication detection span.
 func setDuplicationSpanResult(span trace.Span, count int, err error) {
 	span.SetAttributes(
@@ -125,6 +125,10 @@ func BuildPackageGraph(g *graph.Graph, projectModule string) *PackageGraph {
 
 		fromPkg := packageFromFile(fromNode.Symbol.FilePath, projectModule)
 		toPkg := toNode.Symbol.Package
+		if toNode.Symbol.Kind == ast.SymbolKindPackage {
+			// A Go import resolved to a project package.
+			toPkg = packageFromFile(toNode.Symbol.FilePath, projectModule)
+		}
 
 		if fromPkg == "" || toPkg == "" {
 			continue
@@ -375,6 +375,48 @@ func TestDeadCodeFinder_WithReferencedSymbols(t *testing.T) {
 	}
 }
 
+func TestDeadCodeFinder_SkipsGeneratedCode(t *testing.T) {
+	g := graph.NewGraph("/test")
+	idx := index.NewSymbolIndex()
+
+	handwritten := &ast.Symbol{
+		ID: "svc.go:1:unused", Name: "unused", Kind: ast.SymbolKindFunction,
+		FilePath: "svc.go", StartLine: 1, EndLine: 5, Language: "go",
+	}
+	mock := &ast.Symbol{
+		ID: "svc_mock.go:1:mockUnused", Name: "mockUnused", Kind: ast.SymbolKindFunction,
+		FilePath: "svc_mock.go", StartLine: 1, EndLine: 5, Language: "go", Generated: true,
+	}
+	for _, sym := range []*ast.Symbol{handwritten, mock} {
+		idx.Add(sym)
+		_, _ = g.AddNode(sym)
+	}
+	g.Freeze()
+
+	finder := NewDeadCodeFinder(g, idx, "/test")
+	opts := DefaultDeadCodeOptions()
+
+	names := func() map[string]bool {
+		dead, err := finder.FindDeadCode(context.Background(), "", &opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		found := make(map[string]bool)
+		for _, d := range dead {
+			found[d.Name] = true
+		}
+		return found
+	}
+
+	if found := names(); !found["unused"] || found["mockUnused"] {
+		t.Errorf("default dead code = %v, want unused without mockUnused", found)
+	}
+	opts.IncludeGenerated = true
+	if found := names(); !found["mockUnused"] {
+		t.Errorf("dead code with IncludeGenerated = %v, want mockUnused", found)
+	}
+}
+
 // ============================================================================
 // Convention Extractor Tests
 // ============================================================================
@@ -323,15 +323,25 @@ func (s *SmellFinder) detectGodObjects(ctx context.Context, scope string, opts *
 		methodCounts[t.Name] = 0
 	}
 
-	// Count methods
+	// Count methods. Platform variants of a method (foo_linux.go,
+	// foo_windows.go) are one method of the type, so count names once.
 	methods := s.idx.GetByKind(ast.SymbolKindMethod)
+	counted := make(map[string]bool, len(methods))
 	for _, m := range methods {
 		if m.Receiver != "" {
 			// Extract type name from receiver (e.g., "*MyType" -> "MyType")
 			typeName := strings.TrimPrefix(m.Receiver, "*")
-			if _, exists := methodCounts[typeName]; exists {
-				methodCounts[typeName]++
+			if _, exists := methodCounts[typeName]; !exists {
+				continue
+			}
+			key := typeName + "." + m.Name
+			if m.Metadata != nil && m.Metadata.BuildConstraint != "" {
+				if counted[key] {
+					continue
+				}
+				counted[key] = true
 			}
+			methodCounts[typeName]++
 		}
 	}
 
@@ -204,6 +204,28 @@ type DupLocation struct {
 
 	// SymbolID is the containing symbol (if applicable).
 	SymbolID string `json:"symbol_id,omitempty"`
+
+	// Language is the source language. Set by clone clustering.
+	Language string `json:"language,omitempty"`
+}
+
+// DuplicationCluster is a group of functions with near-identical bodies.
+type DuplicationCluster struct {
+	// Similarity is the lowest similarity among the matches that formed
+	// the cluster (0.0 - 1.0).
+	Similarity float64 `json:"similarity"`
+
+	// CrossLanguage is true when members are in more than one language.
+	CrossLanguage bool `json:"cross_language"`
+
+	// Languages are the distinct member languages, sorted.
+	Languages []string `json:"languages"`
+
+	// Members are the cluster's functions, ordered by file and line.
+	Members []DupLocation `json:"members"`
+
+	// Suggestion recommends how to consolidate the members.
+	Suggestion string `json:"suggestion"`
 }
 
 // Convention represents an observed coding convention.
@@ -87,3 +87,29 @@ func (a *PhaseAdapter) Name() string {
 
 // Ensure PhaseAdapter implements agent.PhaseExecutor.
 var _ agent.PhaseExecutor = (*PhaseAdapter)(nil)
+
+// NamedPhaseExecutors returns the executors a pipeline config can name.
+//
+// Description:
+//
+//	Wraps the given built-in phases and every phase registered with
+//	phases.RegisterPhase in PhaseAdapters, keyed by phase name, for
+//	agent.NewPipeline.
+//
+// Inputs:
+//
+//	builtins - The configured built-in phases.
+//
+// Outputs:
+//
+//	map[string]agent.PhaseExecutor - Executors by phase name.
+func NamedPhaseExecutors(builtins ...phases.Phase) map[string]agent.PhaseExecutor {
+	named := make(map[string]agent.PhaseExecutor)
+	for _, phase := range phases.RegisteredPhases() {
+		named[phase.Name()] = NewPhaseAdapter(phase)
+	}
+	for _, phase := range builtins {
+		named[phase.Name()] = NewPhaseAdapter(phase)
+	}
+	return named
+}
@@ -0,0 +1,244 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
+)
+
+const (
+	// planApplyFilesArg is the PlanApplyCommand argument replaced by the
+	// changed files.
+	planApplyFilesArg = "{files}"
+
+	// maxPostApplyOutput caps the post-apply command output kept.
+	maxPostApplyOutput = 64 * 1024
+)
+
+// ApplyPlan writes a stored change plan to the project files.
+//
+// Description:
+//
+//	The plan must be approved; a dry run also accepts a pending plan so
+//	reviewers can see the exact edits. coordinate.ApplyPlan checks every
+//	change against the region hash captured at plan time and writes each
+//	file atomically, restoring all of them if one write fails. After a
+//	real apply the plan becomes applied, PlanApplyCommand runs in the
+//	project root, and the plan's graph is refreshed incrementally from
+//	the touched files. Applies are serialized.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation; also bounds the post-apply command.
+//	planID - The plan to apply.
+//	dryRun - Report the edits without writing anything.
+//
+// Outputs:
+//
+//	*ApplyPlanResponse - The edits, the plan's new state, and the
+//	  post-apply command and refresh outcomes. LatencyMs is not set.
+//	error - ErrPlanNotFound, ErrPlanNotApproved, ErrGraphNotInitialized
+//	  or ErrGraphExpired for the plan's graph, coordinate.ErrOutsideRoot,
+//	  a *coordinate.ConflictError for stale changes, or an I/O error.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ApplyPlan(ctx context.Context, planID string, dryRun bool) (*ApplyPlanResponse, error) {
+	s.applyMu.Lock()
+	defer s.applyMu.Unlock()
+
+	summary, planData, err := s.PlanRecord(planID)
+	if err != nil {
+		return nil, err
+	}
+	if summary.Status != PlanStatusApproved && !(dryRun && summary.Status == PlanStatusPending) {
+		return nil, fmt.Errorf("%w: plan %s is %s", ErrPlanNotApproved, planID, summary.Status)
+	}
+	plan, ok := planData.(*coordinate.ChangePlan)
+	if !ok {
+		return nil, fmt.Errorf("plan %s has no change plan", planID)
+	}
+	cached, err := s.GetGraphForPlan(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := coordinate.ApplyPlan(ctx, cached.ProjectRoot, plan, coordinate.ApplyOptions{DryRun: dryRun})
+	if err != nil {
+		return nil, err
+	}
+	resp := &ApplyPlanResponse{ApplyResult: result, Summary: summary}
+	if dryRun {
+		return resp, nil
+	}
+
+	if applied, ok := s.markPlanApplied(planID); ok {
+		resp.Summary = applied
+	}
+	files := make([]string, len(result.Files))
+	for i, f := range result.Files {
+		files[i] = f.FilePath
+	}
+	resp.PostApply = s.runPostApply(ctx, cached.ProjectRoot, files)
+
+	graphID := s.cachedGraphID(cached)
+	if graphID == "" {
+		graphID = plan.GraphID
+	}
+	resp.GraphRefresh = &PlanGraphRefresh{GraphID: graphID}
+	refreshed, err := s.refreshPlanFiles(ctx, graphID, files)
+	if err != nil {
+		slog.Warn("Graph refresh after plan apply failed",
+			slog.String("plan_id", planID),
+			slog.String("graph_id", graphID),
+			telemetry.SensitiveLogError("error", err),
+		)
+		resp.GraphRefresh.Error = err.Error()
+	} else {
+		resp.GraphRefresh.Refreshed = true
+		resp.GraphRefresh.FilesParsed = refreshed.FilesParsed
+	}
+	return resp, nil
+}
+
+// markPlanApplied moves an approved plan to applied and emits the
+// transition. Returns false if the plan is gone or no longer approved.
+func (s *Service) markPlanApplied(planID string) (PlanSummary, bool) {
+	s.plansMu.Lock()
+	cached, ok := s.plans[planID]
+	if !ok || cached.Status != PlanStatusApproved {
+		s.plansMu.Unlock()
+		return PlanSummary{}, false
+	}
+	cached.Status = PlanStatusApplied
+	cached.UpdatedAt = time.Now()
+	summary := summarizePlan(planID, cached)
+	s.plansMu.Unlock()
+
+	s.emitPlanStatus(planStatusChange{from: PlanStatusApproved, summary: summary, reason: "plan applied"})
+	return summary, true
+}
+
+// runPostApply runs PlanApplyCommand in root. Returns nil when no command
+// is configured.
+func (s *Service) runPostApply(ctx context.Context, root string, files []string) *PostApplyResult {
+	if len(s.config.PlanApplyCommand) == 0 {
+		return nil
+	}
+	var args []string
+	for _, arg := range s.config.PlanApplyCommand {
+		if arg == planApplyFilesArg {
+			args = append(args, files...)
+			continue
+		}
+		args = append(args, arg)
+	}
+	result := &PostApplyResult{Command: args, ExitCode: -1}
+
+	timeout := s.config.PlanApplyTimeout
+	if timeout <= 0 {
+		timeout = 2 * time.Minute
+	}
+	cmdCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var out bytes.Buffer
+	cmd := exec.CommandContext(cmdCtx, args[0], args[1:]...)
+	cmd.Dir = root
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	result.DurationMs = time.Since(start).Milliseconds()
+
+	output := out.Bytes()
+	if len(output) > maxPostApplyOutput {
+		output = output[:maxPostApplyOutput]
+		result.Truncated = true
+	}
+	result.Output = string(output)
+
+	var exitErr *exec.ExitError
+	switch {
+	case cmdCtx.Err() == context.DeadlineExceeded:
+		result.Error = fmt.Sprintf("timed out after %s", timeout)
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.Error = err.Error()
+	default:
+		result.ExitCode = 0
+	}
+	return result
+}
+
+// refreshPlanFiles re-parses files of a graph after they were edited and
+// installs the incrementally refreshed graph. Files are parsed with the
+// default parser settings.
+func (s *Service) refreshPlanFiles(ctx context.Context, graphID string, files []string) (*InitResponse, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	ws := cached.Workspace
+
+	var results []*ast.ParseResult
+	var errs []string
+	var rootFilesParsed map[string]int
+	if ws != nil {
+		rootFilesParsed = make(map[string]int, len(ws.Roots))
+	}
+	for _, relPath := range files {
+		if _, ok := s.registry.GetByExtension(filepath.Ext(relPath)); !ok {
+			continue
+		}
+		absPath, err := validation.ResolveWithin(cached.ProjectRoot, relPath)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", relPath, err))
+			continue
+		}
+		pr, err := s.parseFileToResult(ctx, absPath, relPath)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", relPath, err))
+			continue
+		}
+		results = append(results, pr)
+		if ws != nil {
+			if root, ok := ws.rootOf(relPath); ok {
+				rootFilesParsed[root.Name]++
+			}
+		}
+	}
+	if ws != nil {
+		ws.rewriteImports(results)
+	}
+
+	incr, err := graph.IncrementalRefresh(ctx, cached.Graph, files, results, s.buildLSPEnrichmentConfig(graphID))
+	if err != nil {
+		return nil, fmt.Errorf("incremental refresh: %w", err)
+	}
+	stats := cached.EnrichmentStats
+	stats.Merge(incr.EnrichmentStats)
+	return s.cacheAndReturn(ctx, incr.Graph, cached.ProjectRoot, graphID, start, len(results), errs, &stats, ws, rootFilesParsed)
+}
@@ -0,0 +1,401 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+// =============================================================================
+// Change Plan Approval
+// =============================================================================
+//
+// Coordinate-phase change plans are a human-in-the-loop gate: when the
+// safety policy's approval rules select a plan, it is stored pending and
+// nothing may act on it until a reviewer approves it.
+//
+// Lifecycle:
+//
+//	pending ──approve──► approved ──TTL──► expired
+//	   │                    │                 ▲
+//	   │                    └─apply─► applied │
+//	   ├──reject───► rejected                 │
+//	   └──────────────────TTL─────────────────┘
+//
+// Plans that need no approval start approved. Every transition emits a
+// TypePlanStatus event, streamed by GET /v1/trace/plans/events.
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/analysis"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/reason"
+)
+
+// PlanStatus is the approval status of a change plan.
+type PlanStatus string
+
+const (
+	// PlanStatusPending means the plan awaits a reviewer's decision.
+	PlanStatusPending PlanStatus = "pending"
+
+	// PlanStatusApproved means the plan was approved, or needed no approval.
+	PlanStatusApproved PlanStatus = "approved"
+
+	// PlanStatusRejected means a reviewer rejected the plan.
+	PlanStatusRejected PlanStatus = "rejected"
+
+	// PlanStatusExpired means the plan outlived ServiceConfig.PlanTTL.
+	PlanStatusExpired PlanStatus = "expired"
+
+	// PlanStatusApplied means the plan was written to the project files.
+	PlanStatusApplied PlanStatus = "applied"
+)
+
+// IsFinal returns true if the status can no longer change.
+func (s PlanStatus) IsFinal() bool {
+	return s == PlanStatusRejected || s == PlanStatusExpired || s == PlanStatusApplied
+}
+
+// validPlanStatus reports whether s names a plan status.
+func validPlanStatus(s PlanStatus) bool {
+	switch s {
+	case PlanStatusPending, PlanStatusApproved, PlanStatusRejected, PlanStatusExpired, PlanStatusApplied:
+		return true
+	}
+	return false
+}
+
+const (
+	// maxStoredPlans caps the number of plans kept, oldest removed first.
+	maxStoredPlans = 100
+
+	// planRetention is how long rejected and expired plans stay listed.
+	planRetention = 24 * time.Hour
+)
+
+// PlanApproval records whether and why the safety policy requires
+// approval for a plan.
+type PlanApproval struct {
+	// Required is true when the plan must be approved before use.
+	Required bool `json:"required"`
+
+	// RuleID is the approval rule that matched.
+	RuleID string `json:"rule_id,omitempty"`
+
+	// Reason explains the match.
+	Reason string `json:"reason,omitempty"`
+}
+
+// PlanSummary describes a stored change plan and its approval state.
+type PlanSummary struct {
+	// PlanID identifies the plan.
+	PlanID string `json:"plan_id"`
+
+	// GraphID is the graph the plan was created for.
+	GraphID string `json:"graph_id,omitempty"`
+
+	// SessionID is the agent session the plan belongs to, if any.
+	SessionID string `json:"session_id,omitempty"`
+
+	// Status is the approval status.
+	Status PlanStatus `json:"status"`
+
+	// Approval is why the plan needs approval.
+	Approval PlanApproval `json:"approval"`
+
+	// Comment is the reviewer's comment on approve or reject.
+	Comment string `json:"comment,omitempty"`
+
+	// Description, TotalFiles, TotalChanges and RiskLevel are copied from
+	// the plan.
+	Description  string `json:"description,omitempty"`
+	TotalFiles   int    `json:"total_files"`
+	TotalChanges int    `json:"total_changes"`
+	RiskLevel    string `json:"risk_level,omitempty"`
+
+	// CreatedAt, UpdatedAt and ExpiresAt are Unix milliseconds UTC.
+	CreatedAt int64 `json:"created_at"`
+	UpdatedAt int64 `json:"updated_at"`
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+// PlanFilter selects plans in ListPlans. Zero fields match every plan.
+type PlanFilter struct {
+	Status    PlanStatus
+	SessionID string
+}
+
+// planStatusChange is one status transition to emit as an event.
+type planStatusChange struct {
+	from    PlanStatus
+	summary PlanSummary
+	reason  string
+}
+
+// summarizePlan builds the summary of a cached plan. Caller must hold plansMu.
+func summarizePlan(planID string, cached *CachedPlan) PlanSummary {
+	summary := PlanSummary{
+		PlanID:    planID,
+		GraphID:   cached.GraphID,
+		SessionID: cached.SessionID,
+		Status:    cached.Status,
+		Approval:  cached.Approval,
+		Comment:   cached.Comment,
+		CreatedAt: cached.CreatedAt.UnixMilli(),
+		UpdatedAt: cached.UpdatedAt.UnixMilli(),
+		ExpiresAt: cached.ExpiresAt.UnixMilli(),
+	}
+	if plan, ok := cached.Plan.(*coordinate.ChangePlan); ok {
+		summary.Description = plan.Description
+		summary.TotalFiles = plan.TotalFiles
+		summary.TotalChanges = plan.TotalChanges
+		summary.RiskLevel = string(plan.RiskLevel)
+	}
+	return summary
+}
+
+// SetEventEmitter sets the emitter that receives plan status events.
+//
+// Inputs:
+//
+//	emitter - The emitter. Can be nil to disable plan events.
+func (s *Service) SetEventEmitter(emitter *events.Emitter) {
+	s.plansMu.Lock()
+	defer s.plansMu.Unlock()
+	s.eventEmitter = emitter
+}
+
+// EventEmitter returns the emitter set by SetEventEmitter, or nil.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) EventEmitter() *events.Emitter {
+	s.plansMu.RLock()
+	defer s.plansMu.RUnlock()
+	return s.eventEmitter
+}
+
+// planTTL returns the configured plan lifetime.
+func (s *Service) planTTL() time.Duration {
+	if s.config.PlanTTL > 0 {
+		return s.config.PlanTTL
+	}
+	return time.Hour
+}
+
+// PlanRecord returns a stored plan and its summary.
+//
+// Description:
+//
+//	A pending or approved plan past its expiry is marked expired first.
+//	Expired plans are still returned until they are evicted.
+//
+// Outputs:
+//
+//	PlanSummary - The plan's summary.
+//	interface{} - The plan (caller casts to *coordinate.ChangePlan).
+//	error - ErrPlanNotFound if no such plan is stored.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) PlanRecord(planID string) (PlanSummary, interface{}, error) {
+	s.plansMu.Lock()
+	cached, ok := s.plans[planID]
+	if !ok {
+		s.plansMu.Unlock()
+		return PlanSummary{}, nil, fmt.Errorf("%w: %s", ErrPlanNotFound, planID)
+	}
+	changes := s.expirePlansLocked(time.Now())
+	summary := summarizePlan(planID, cached)
+	s.plansMu.Unlock()
+
+	s.emitPlanStatus(changes...)
+	return summary, cached.Plan, nil
+}
+
+// ListPlans returns the summaries of the stored plans matching filter,
+// newest first.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ListPlans(filter PlanFilter) []PlanSummary {
+	s.plansMu.Lock()
+	changes := s.expirePlansLocked(time.Now())
+	summaries := make([]PlanSummary, 0, len(s.plans))
+	for id, cached := range s.plans {
+		if filter.Status != "" && cached.Status != filter.Status {
+			continue
+		}
+		if filter.SessionID != "" && cached.SessionID != filter.SessionID {
+			continue
+		}
+		summaries = append(summaries, summarizePlan(id, cached))
+	}
+	s.plansMu.Unlock()
+
+	s.emitPlanStatus(changes...)
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].CreatedAt != summaries[j].CreatedAt {
+			return summaries[i].CreatedAt > summaries[j].CreatedAt
+		}
+		return summaries[i].PlanID < summaries[j].PlanID
+	})
+	return summaries
+}
+
+// PendingPlanIDs returns the IDs of the session's plans awaiting approval,
+// sorted.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) PendingPlanIDs(sessionID string) []string {
+	if sessionID == "" {
+		return nil
+	}
+	pending := s.ListPlans(PlanFilter{Status: PlanStatusPending, SessionID: sessionID})
+	ids := make([]string, len(pending))
+	for i, p := range pending {
+		ids[i] = p.PlanID
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ApprovePlan approves a pending plan.
+//
+// Inputs:
+//
+//	planID - The plan to approve.
+//	comment - Optional reviewer comment.
+//
+// Outputs:
+//
+//	PlanSummary - The plan after the change.
+//	error - ErrPlanNotFound, or ErrPlanNotPending if the plan is not pending.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ApprovePlan(planID, comment string) (PlanSummary, error) {
+	return s.decidePlan(planID, PlanStatusApproved, comment)
+}
+
+// RejectPlan rejects a pending plan. See ApprovePlan.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) RejectPlan(planID, comment string) (PlanSummary, error) {
+	return s.decidePlan(planID, PlanStatusRejected, comment)
+}
+
+// decidePlan moves a pending plan to status. An approved plan's expiry
+// restarts so the agent has a full TTL to act on it.
+func (s *Service) decidePlan(planID string, status PlanStatus, comment string) (PlanSummary, error) {
+	now := time.Now()
+	s.plansMu.Lock()
+	cached, ok := s.plans[planID]
+	if !ok {
+		s.plansMu.Unlock()
+		return PlanSummary{}, fmt.Errorf("%w: %s", ErrPlanNotFound, planID)
+	}
+	changes := s.expirePlansLocked(now)
+	if cached.Status != PlanStatusPending {
+		summary := summarizePlan(planID, cached)
+		s.plansMu.Unlock()
+		s.emitPlanStatus(changes...)
+		return summary, fmt.Errorf("%w: plan %s is %s", ErrPlanNotPending, planID, summary.Status)
+	}
+	cached.Status = status
+	cached.Comment = comment
+	cached.UpdatedAt = now
+	if status == PlanStatusApproved {
+		cached.ExpiresAt = now.Add(s.planTTL())
+	}
+	summary := summarizePlan(planID, cached)
+	s.plansMu.Unlock()
+
+	s.emitPlanStatus(changes...)
+	s.emitPlanStatus(planStatusChange{from: PlanStatusPending, summary: summary})
+	return summary, nil
+}
+
+// ExpirePlans marks every pending or approved plan past its expiry as
+// expired and emits the transitions. Returns the number expired.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ExpirePlans() int {
+	s.plansMu.Lock()
+	changes := s.expirePlansLocked(time.Now())
+	s.plansMu.Unlock()
+
+	s.emitPlanStatus(changes...)
+	return len(changes)
+}
+
+// expirePlansLocked expires plans past their expiry and returns the
+// transitions. Caller must hold plansMu for writing.
+func (s *Service) expirePlansLocked(now time.Time) []planStatusChange {
+	var changes []planStatusChange
+	for id, cached := range s.plans {
+		if cached.Status.IsFinal() || cached.ExpiresAt.IsZero() || now.Before(cached.ExpiresAt) {
+			continue
+		}
+		from := cached.Status
+		cached.Status = PlanStatusExpired
+		cached.UpdatedAt = now
+		changes = append(changes, planStatusChange{
+			from:    from,
+			summary: summarizePlan(id, cached),
+			reason:  "plan outlived its TTL",
+		})
+	}
+	return changes
+}
+
+// emitPlanStatus emits one TypePlanStatus event per change. Must not be
+// called with plansMu held: subscribers run synchronously.
+func (s *Service) emitPlanStatus(changes ...planStatusChange) {
+	if len(changes) == 0 {
+		return
+	}
+	emitter := s.EventEmitter()
+	if emitter == nil {
+		return
+	}
+	for _, change := range changes {
+		emitter.Emit(events.TypePlanStatus, &events.PlanStatusData{
+			PlanID:     change.summary.PlanID,
+			SessionID:  change.summary.SessionID,
+			FromStatus: string(change.from),
+			ToStatus:   string(change.summary.Status),
+			Comment:    change.summary.Comment,
+			Reason:     change.reason,
+		})
+	}
+}
+
+// PreviewPlan generates the unified diffs of a plan against its graph.
+//
+// Outputs:
+//
+//	[]coordinate.FileDiff - One diff per changed file.
+//	error - Non-nil if the plan's graph is gone or the preview fails.
+func (s *Service) PreviewPlan(ctx context.Context, plan *coordinate.ChangePlan) ([]coordinate.FileDiff, error) {
+	cached, err := s.GetGraphForPlan(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	breakingAnalyzer := reason.NewBreakingChangeAnalyzer(cached.Graph, cached.Index)
+	blastAnalyzer := analysis.NewBlastRadiusAnalyzer(cached.Graph, cached.Index, nil)
+	validator := reason.NewChangeValidator(cached.Index)
+
+	coordinator := coordinate.NewMultiFileChangeCoordinator(
+		cached.Graph, cached.Index,
+		breakingAnalyzer, blastAnalyzer, validator,
+	)
+	return coordinator.PreviewChanges(ctx, plan)
+}
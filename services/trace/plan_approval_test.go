@@ -0,0 +1,538 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
+	"github.com/gin-gonic/gin"
+)
+
+// planStatusEvents returns the plan status events emitted so far.
+func planStatusEvents(emitter *events.Emitter) []*events.PlanStatusData {
+	var out []*events.PlanStatusData
+	for _, e := range emitter.GetBufferByType(events.TypePlanStatus) {
+		if data, ok := e.Data.(*events.PlanStatusData); ok {
+			out = append(out, data)
+		}
+	}
+	return out
+}
+
+// postJSON issues a POST with a JSON body.
+func postJSON(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestService_StorePlan_ApprovalStatus(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	emitter := events.NewEmitter()
+	svc.SetEventEmitter(emitter)
+
+	free := svc.StorePlan(&coordinate.ChangePlan{ID: "free"}, "s1", PlanApproval{})
+	if free.Status != PlanStatusApproved {
+		t.Errorf("status without approval = %s, want approved", free.Status)
+	}
+	gated := svc.StorePlan(&coordinate.ChangePlan{ID: "gated"}, "s1",
+		PlanApproval{Required: true, RuleID: safety.RuleApprovalRequired, Reason: "all plans need approval"})
+	if gated.Status != PlanStatusPending {
+		t.Errorf("status with approval = %s, want pending", gated.Status)
+	}
+	if gated.ExpiresAt <= gated.CreatedAt {
+		t.Errorf("expires_at %d not after created_at %d", gated.ExpiresAt, gated.CreatedAt)
+	}
+
+	if got := svc.PendingPlanIDs("s1"); len(got) != 1 || got[0] != "gated" {
+		t.Errorf("PendingPlanIDs = %v, want [gated]", got)
+	}
+	if got := svc.PendingPlanIDs("other"); len(got) != 0 {
+		t.Errorf("PendingPlanIDs(other) = %v, want none", got)
+	}
+
+	evs := planStatusEvents(emitter)
+	if len(evs) != 2 {
+		t.Fatalf("events = %d, want 2", len(evs))
+	}
+	if evs[1].PlanID != "gated" || evs[1].ToStatus != string(PlanStatusPending) || evs[1].Reason != "all plans need approval" {
+		t.Errorf("creation event = %+v", evs[1])
+	}
+}
+
+func TestService_DecidePlan(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	emitter := events.NewEmitter()
+	svc.SetEventEmitter(emitter)
+	svc.StorePlan(&coordinate.ChangePlan{ID: "a"}, "s1", PlanApproval{Required: true})
+	svc.StorePlan(&coordinate.ChangePlan{ID: "b"}, "s1", PlanApproval{Required: true})
+
+	approved, err := svc.ApprovePlan("a", "looks good")
+	if err != nil {
+		t.Fatalf("ApprovePlan: %v", err)
+	}
+	if approved.Status != PlanStatusApproved || approved.Comment != "looks good" {
+		t.Errorf("approved = %+v", approved)
+	}
+	if _, err := svc.RejectPlan("a", ""); !errors.Is(err, ErrPlanNotPending) {
+		t.Errorf("RejectPlan on approved plan error = %v, want ErrPlanNotPending", err)
+	}
+
+	rejected, err := svc.RejectPlan("b", "too risky")
+	if err != nil {
+		t.Fatalf("RejectPlan: %v", err)
+	}
+	if rejected.Status != PlanStatusRejected {
+		t.Errorf("rejected status = %s", rejected.Status)
+	}
+	if _, err := svc.ApprovePlan("missing", ""); !errors.Is(err, ErrPlanNotFound) {
+		t.Errorf("ApprovePlan on missing plan error = %v, want ErrPlanNotFound", err)
+	}
+
+	if got := svc.ListPlans(PlanFilter{Status: PlanStatusRejected}); len(got) != 1 || got[0].PlanID != "b" {
+		t.Errorf("ListPlans(rejected) = %+v", got)
+	}
+
+	evs := planStatusEvents(emitter)
+	last := evs[len(evs)-1]
+	if last.PlanID != "b" || last.FromStatus != string(PlanStatusPending) || last.ToStatus != string(PlanStatusRejected) || last.Comment != "too risky" {
+		t.Errorf("reject event = %+v", last)
+	}
+}
+
+func TestService_ExpirePlans(t *testing.T) {
+	cfg := DefaultServiceConfig()
+	cfg.PlanTTL = time.Millisecond
+	svc := NewService(cfg)
+	emitter := events.NewEmitter()
+	svc.SetEventEmitter(emitter)
+	svc.StorePlan(&coordinate.ChangePlan{ID: "p"}, "s1", PlanApproval{Required: true})
+
+	time.Sleep(5 * time.Millisecond)
+	if n := svc.ExpirePlans(); n != 1 {
+		t.Fatalf("ExpirePlans = %d, want 1", n)
+	}
+	summary, _, err := svc.PlanRecord("p")
+	if err != nil {
+		t.Fatalf("PlanRecord: %v", err)
+	}
+	if summary.Status != PlanStatusExpired {
+		t.Errorf("status = %s, want expired", summary.Status)
+	}
+	if _, err := svc.GetPlan("p"); err == nil {
+		t.Error("GetPlan returned an expired plan")
+	}
+	if _, err := svc.ApprovePlan("p", ""); !errors.Is(err, ErrPlanNotPending) {
+		t.Errorf("ApprovePlan on expired plan error = %v, want ErrPlanNotPending", err)
+	}
+	if got := svc.PendingPlanIDs("s1"); len(got) != 0 {
+		t.Errorf("PendingPlanIDs = %v, want none", got)
+	}
+
+	evs := planStatusEvents(emitter)
+	last := evs[len(evs)-1]
+	if last.ToStatus != string(PlanStatusExpired) || last.FromStatus != string(PlanStatusPending) {
+		t.Errorf("expiry event = %+v", last)
+	}
+}
+
+func TestPlanHandlers_ListGetDecide(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	svc.StorePlan(&coordinate.ChangePlan{ID: "p1", Description: "rename"}, "s1", PlanApproval{Required: true})
+	svc.StorePlan(&coordinate.ChangePlan{ID: "p2"}, "s2", PlanApproval{Required: true})
+
+	w := cacheGet(router, "/v1/trace/plans?status=pending&session_id=s1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("list status = %d: %s", w.Code, w.Body.String())
+	}
+	var list ListPlansResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("unmarshal list: %v", err)
+	}
+	if list.Count != 1 || list.Plans[0].PlanID != "p1" {
+		t.Errorf("list = %+v, want only p1", list)
+	}
+
+	if w := cacheGet(router, "/v1/trace/plans?status=bogus", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid status code = %d, want 400", w.Code)
+	}
+
+	w = cacheGet(router, "/v1/trace/plans/p1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("get status = %d: %s", w.Code, w.Body.String())
+	}
+	var detail PlanDetailResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &detail); err != nil {
+		t.Fatalf("unmarshal detail: %v", err)
+	}
+	if detail.Summary.Status != PlanStatusPending || detail.Plan == nil || detail.Plan.Description != "rename" {
+		t.Errorf("detail = %+v", detail)
+	}
+	// The plan has no graph, so the preview cannot be built.
+	if detail.PreviewError == "" {
+		t.Error("expected preview_error for a plan without a graph")
+	}
+
+	if w := cacheGet(router, "/v1/trace/plans/missing", ""); w.Code != http.StatusNotFound {
+		t.Errorf("get missing code = %d, want 404", w.Code)
+	}
+
+	w = postJSON(router, "/v1/trace/plans/p1/approve", `{"comment": "ship it"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("approve status = %d: %s", w.Code, w.Body.String())
+	}
+	var summary PlanSummary
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("unmarshal approve: %v", err)
+	}
+	if summary.Status != PlanStatusApproved || summary.Comment != "ship it" {
+		t.Errorf("approve summary = %+v", summary)
+	}
+
+	if w := postJSON(router, "/v1/trace/plans/p1/reject", ""); w.Code != http.StatusConflict {
+		t.Errorf("reject approved plan code = %d, want 409", w.Code)
+	}
+	if w := postJSON(router, "/v1/trace/plans/p2/reject", ""); w.Code != http.StatusOK {
+		t.Errorf("reject without body code = %d, want 200: %s", w.Code, w.Body.String())
+	}
+	if w := postJSON(router, "/v1/trace/plans/missing/approve", ""); w.Code != http.StatusNotFound {
+		t.Errorf("approve missing code = %d, want 404", w.Code)
+	}
+}
+
+func TestPlanHandlers_EventsNotAvailable(t *testing.T) {
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+	w := cacheGet(router, "/v1/trace/plans/events", "")
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("code = %d, want 503", w.Code)
+	}
+}
+
+func TestHandlers_HandlePlanMultiFileChange_ApprovalPolicy(t *testing.T) {
+	router, handlers := setupSafetyTestRouter(t, "approval:\n  required: true\n")
+	dir := t.TempDir()
+	writeTestGoFiles(t, dir)
+	resp, err := handlers.svc.Init(context.Background(), dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	cached, err := handlers.svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph: %v", err)
+	}
+	var targetID string
+	for _, node := range cached.Graph.Nodes() {
+		if node.Symbol != nil && node.Symbol.Name == "helper" {
+			targetID = node.Symbol.ID
+		}
+	}
+
+	body, _ := json.Marshal(PlanMultiFileChangeRequest{
+		GraphID:      resp.GraphID,
+		TargetID:     targetID,
+		ChangeType:   "add_parameter",
+		NewSignature: "func helper(x int) string",
+		SessionID:    "s1",
+	})
+	w := postJSON(router, "/v1/trace/coordinate/plan_changes", string(body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var out AgenticResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if out.PlanStatus == nil || out.PlanStatus.Status != PlanStatusPending {
+		t.Fatalf("plan_status = %+v, want pending", out.PlanStatus)
+	}
+	if out.PlanStatus.Approval.RuleID != safety.RuleApprovalRequired {
+		t.Errorf("approval rule = %q, want %q", out.PlanStatus.Approval.RuleID, safety.RuleApprovalRequired)
+	}
+	if got := handlers.svc.PendingPlanIDs("s1"); len(got) != 1 || got[0] != out.PlanStatus.PlanID {
+		t.Errorf("PendingPlanIDs = %v, want [%s]", got, out.PlanStatus.PlanID)
+	}
+}
+
+func TestAgentHandlers_HandleAgentContinue_PlanApproval(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	var gotMessage string
+	mockLoop := &MockAgentLoop{
+		continueFunc: func(ctx context.Context, sessionID, clarification string) (*agent.RunResult, error) {
+			gotMessage = clarification
+			return &agent.RunResult{State: agent.StateComplete, StepsTaken: 2}, nil
+		},
+	}
+	router := setupAgentTestRouter(NewAgentHandlers(mockLoop, svc))
+
+	svc.StorePlan(&coordinate.ChangePlan{ID: "p1", Description: "add ctx param"}, "s1", PlanApproval{Required: true})
+	svc.StorePlan(&coordinate.ChangePlan{ID: "other"}, "s2", PlanApproval{Required: true})
+
+	w := postJSON(router, "/v1/trace/agent/continue", `{"session_id": "s1", "clarification": "go on"}`)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "PLAN_AWAITING_APPROVAL") {
+		t.Fatalf("continue with pending plan = %d %s, want 409 PLAN_AWAITING_APPROVAL", w.Code, w.Body.String())
+	}
+
+	w = postJSON(router, "/v1/trace/agent/continue", `{"session_id": "s1", "plan_id": "p1"}`)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), "PLAN_NOT_APPROVED") {
+		t.Fatalf("continue with pending plan_id = %d %s, want 409 PLAN_NOT_APPROVED", w.Code, w.Body.String())
+	}
+
+	if w := postJSON(router, "/v1/trace/agent/continue", `{"session_id": "s1", "plan_id": "other"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("continue with another session's plan = %d, want 400", w.Code)
+	}
+	if w := postJSON(router, "/v1/trace/agent/continue", `{"session_id": "s1"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("continue without clarification or plan_id = %d, want 400", w.Code)
+	}
+
+	if _, err := svc.ApprovePlan("p1", "use context.Context"); err != nil {
+		t.Fatalf("ApprovePlan: %v", err)
+	}
+	w = postJSON(router, "/v1/trace/agent/continue", `{"session_id": "s1", "plan_id": "p1"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("continue with approved plan = %d: %s", w.Code, w.Body.String())
+	}
+	for _, want := range []string{"p1", "approved", "add ctx param", "use context.Context"} {
+		if !strings.Contains(gotMessage, want) {
+			t.Errorf("continuation message missing %q:\n%s", want, gotMessage)
+		}
+	}
+	var resp AgentRunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.Status != "" || len(resp.PendingPlans) != 0 {
+		t.Errorf("status = %q pending = %v, want none", resp.Status, resp.PendingPlans)
+	}
+}
+
+func TestAgentHandlers_HandleAgentRun_AwaitingApproval(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	mockLoop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			// Simulate the agent creating a plan for its session mid-run.
+			svc.StorePlan(&coordinate.ChangePlan{ID: "p1"}, session.ID, PlanApproval{Required: true})
+			return &agent.RunResult{State: agent.StateComplete, StepsTaken: 1}, nil
+		},
+	}
+	router := setupAgentTestRouter(NewAgentHandlers(mockLoop, svc))
+
+	body, _ := json.Marshal(AgentRunRequest{ProjectRoot: t.TempDir(), Query: "rename helper"})
+	w := postJSON(router, "/v1/trace/agent/run", string(body))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp AgentRunResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if resp.Status != AgentStatusAwaitingApproval || len(resp.PendingPlans) != 1 || resp.PendingPlans[0] != "p1" {
+		t.Errorf("status = %q pending = %v, want awaiting_approval [p1]", resp.Status, resp.PendingPlans)
+	}
+}
+
+// initApplyProject initializes a one-file Go project and returns its root
+// and a rename of Hello to Greet planned against it.
+func initApplyProject(t *testing.T, svc *Service) (string, *coordinate.ChangePlan) {
+	t.Helper()
+	root := t.TempDir()
+	src := "package main\n\nfunc Hello() string {\n\treturn \"hi\"\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := svc.Init(context.Background(), root, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	lines := strings.Split(src, "\n")
+	plan := &coordinate.ChangePlan{
+		ID:      "p1",
+		GraphID: resp.GraphID,
+		FileChanges: []coordinate.FileChange{{
+			FilePath: "main.go", StartLine: 3, EndLine: 3, Column: 6,
+			OldText: "Hello", NewText: "Greet", ContentHash: coordinate.RegionHash(lines, 3, 3),
+		}},
+		Order:      []string{"main.go"},
+		TotalFiles: 1,
+	}
+	return root, plan
+}
+
+func TestPlanHandlers_ApplyPlan(t *testing.T) {
+	cfg := DefaultServiceConfig()
+	cfg.PlanApplyCommand = []string{"echo", "changed", "{files}"}
+	svc := NewService(cfg)
+	emitter := events.NewEmitter()
+	svc.SetEventEmitter(emitter)
+	router := setupTestRouter(svc)
+	root, plan := initApplyProject(t, svc)
+	svc.StorePlan(plan, "s1", PlanApproval{Required: true})
+
+	w := postJSON(router, "/v1/trace/plans/p1/apply", "")
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), string(apierror.CodePlanNotApproved)) {
+		t.Fatalf("apply pending = %d: %s", w.Code, w.Body.String())
+	}
+
+	w = postJSON(router, "/v1/trace/plans/p1/apply", `{"dry_run": true}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("dry run = %d: %s", w.Code, w.Body.String())
+	}
+	var dry ApplyPlanResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &dry); err != nil {
+		t.Fatalf("unmarshal dry run: %v", err)
+	}
+	if !dry.DryRun || dry.TotalEdits != 1 || dry.Summary.Status != PlanStatusPending || dry.PostApply != nil {
+		t.Errorf("dry run = %+v", dry)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "main.go")); strings.Contains(string(data), "Greet") {
+		t.Error("dry run wrote the file")
+	}
+
+	if _, err := svc.ApprovePlan("p1", ""); err != nil {
+		t.Fatalf("ApprovePlan: %v", err)
+	}
+	w = postJSON(router, "/v1/trace/plans/p1/apply", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("apply = %d: %s", w.Code, w.Body.String())
+	}
+	var applied ApplyPlanResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &applied); err != nil {
+		t.Fatalf("unmarshal apply: %v", err)
+	}
+	if applied.DryRun || applied.Summary.Status != PlanStatusApplied {
+		t.Errorf("apply = %+v", applied)
+	}
+	if applied.PostApply == nil || applied.PostApply.ExitCode != 0 || applied.PostApply.Output != "changed main.go\n" {
+		t.Errorf("post_apply = %+v", applied.PostApply)
+	}
+	if applied.GraphRefresh == nil || !applied.GraphRefresh.Refreshed || applied.GraphRefresh.FilesParsed != 1 {
+		t.Errorf("graph_refresh = %+v", applied.GraphRefresh)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "main.go")); !strings.Contains(string(data), "func Greet() string") {
+		t.Errorf("main.go = %q", data)
+	}
+	cached, err := svc.GetGraph(plan.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph: %v", err)
+	}
+	var names []string
+	for _, node := range cached.Graph.Nodes() {
+		if node.Symbol != nil && node.Symbol.FilePath == "main.go" {
+			names = append(names, node.Symbol.Name)
+		}
+	}
+	if !slices.Contains(names, "Greet") || slices.Contains(names, "Hello") {
+		t.Errorf("graph symbols after apply = %v, want Greet", names)
+	}
+	evs := planStatusEvents(emitter)
+	if last := evs[len(evs)-1]; last.ToStatus != string(PlanStatusApplied) || last.FromStatus != string(PlanStatusApproved) {
+		t.Errorf("last event = %+v, want approved -> applied", last)
+	}
+
+	// An applied plan cannot be applied again.
+	if w := postJSON(router, "/v1/trace/plans/p1/apply", ""); w.Code != http.StatusConflict {
+		t.Errorf("reapply = %d, want 409", w.Code)
+	}
+	if w := postJSON(router, "/v1/trace/plans/missing/apply", ""); w.Code != http.StatusNotFound {
+		t.Errorf("apply missing = %d, want 404", w.Code)
+	}
+}
+
+func TestPlanHandlers_ApplyPlan_Conflict(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	root, plan := initApplyProject(t, svc)
+	svc.StorePlan(plan, "s1", PlanApproval{})
+
+	edited := "package main\n\n// Hello greets.\nfunc Hello() string {\n\treturn \"hi\"\n}\n"
+	if err := os.WriteFile(filepath.Join(root, "main.go"), []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	w := postJSON(router, "/v1/trace/plans/p1/apply", "")
+	if w.Code != http.StatusConflict {
+		t.Fatalf("apply stale = %d: %s", w.Code, w.Body.String())
+	}
+	var problem struct {
+		Code       string                 `json:"code"`
+		StaleHunks []coordinate.StaleHunk `json:"stale_hunks"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("unmarshal problem: %v", err)
+	}
+	if problem.Code != string(apierror.CodePlanConflict) || len(problem.StaleHunks) != 1 ||
+		problem.StaleHunks[0].Reason != coordinate.ConflictContentChanged {
+		t.Errorf("problem = %+v", problem)
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "main.go")); string(data) != edited {
+		t.Errorf("a conflicting apply changed main.go: %q", data)
+	}
+	if summary, _, _ := svc.PlanRecord("p1"); summary.Status != PlanStatusApproved {
+		t.Errorf("status after conflict = %s, want approved", summary.Status)
+	}
+}
+
+func TestPlanHandlers_ApplyPlan_SafetyDenied(t *testing.T) {
+	policy, err := safety.ParsePolicy([]byte("paths:\n  deny: [\"main.go\"]\n"))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	svc := NewService(DefaultServiceConfig())
+	router := gin.New()
+	RegisterRoutes(router.Group("/v1"), NewHandlers(svc).WithSafetyGate(safety.NewPolicyGate(policy)))
+	root, plan := initApplyProject(t, svc)
+	svc.StorePlan(plan, "s1", PlanApproval{})
+
+	w := postJSON(router, "/v1/trace/plans/p1/apply", "")
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), string(apierror.CodeSafetyDenied)) {
+		t.Fatalf("apply denied = %d: %s", w.Code, w.Body.String())
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "main.go")); strings.Contains(string(data), "Greet") {
+		t.Error("a denied apply wrote the file")
+	}
+}
+
+func TestPlanHandlers_ApplyPlan_ModeNotFromRequest(t *testing.T) {
+	policy, err := safety.ParsePolicy([]byte("tools:\n  default:\n    deny: [apply_plan]\n  edit:\n    allow: [\"*\"]\n"))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	svc := NewService(DefaultServiceConfig())
+	router := gin.New()
+	RegisterRoutes(router.Group("/v1"), NewHandlers(svc).WithSafetyGate(safety.NewPolicyGate(policy)))
+	root, plan := initApplyProject(t, svc)
+	svc.StorePlan(plan, "s1", PlanApproval{})
+
+	w := postJSON(router, "/v1/trace/plans/p1/apply", `{"mode": "edit"}`)
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "tools.default.deny") {
+		t.Fatalf("apply with a permissive mode = %d: %s", w.Code, w.Body.String())
+	}
+	if data, _ := os.ReadFile(filepath.Join(root, "main.go")); strings.Contains(string(data), "Greet") {
+		t.Error("an apply in a caller-chosen mode wrote the file")
+	}
+}
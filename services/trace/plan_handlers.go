@@ -0,0 +1,314 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// planEventsHeartbeat is how often the plan event stream sends a heartbeat
+// and sweeps expired plans.
+var planEventsHeartbeat = 15 * time.Second
+
+// HandleListPlans lists stored change plans.
+//
+// Description:
+//
+//	GET /v1/trace/plans?status=&session_id=. status is one of pending,
+//	approved, rejected, expired or applied. Plans are returned newest first.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleListPlans(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleListPlans")
+
+	filter := PlanFilter{
+		Status:    PlanStatus(c.Query("status")),
+		SessionID: c.Query("session_id"),
+	}
+	if filter.Status != "" && !validPlanStatus(filter.Status) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "status must be one of pending, approved, rejected, expired, applied")
+		return
+	}
+
+	plans := h.svc.ListPlans(filter)
+	logger.Info("Listed plans", "status", filter.Status, "session_id", filter.SessionID, "count", len(plans))
+	c.JSON(http.StatusOK, ListPlansResponse{Plans: plans, Count: len(plans)})
+}
+
+// HandleGetPlan returns a change plan with its approval state and a full
+// diff preview.
+//
+// Description:
+//
+//	GET /v1/trace/plans/:id. Expired and rejected plans are still
+//	returned until evicted. When the preview cannot be built, e.g. the
+//	plan's graph was evicted, the plan is returned with preview_error set.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleGetPlan(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleGetPlan")
+
+	planID := c.Param("id")
+	summary, planData, err := h.svc.PlanRecord(planID)
+	if err != nil {
+		apierror.Write(c, http.StatusNotFound, apierror.CodePlanNotFound, "Plan not found")
+		return
+	}
+	plan, ok := planData.(*coordinate.ChangePlan)
+	if !ok {
+		logger.Error("Invalid plan type", "plan_id", planID)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid plan data")
+		return
+	}
+
+	resp := PlanDetailResponse{Summary: summary, Plan: plan, Preview: []coordinate.FileDiff{}}
+	diffs, err := h.svc.PreviewPlan(c.Request.Context(), plan)
+	if err != nil {
+		logger.Warn("Plan preview failed", "plan_id", planID, telemetry.SensitiveLogError("error", err))
+		resp.PreviewError = err.Error()
+	} else if diffs != nil {
+		resp.Preview = diffs
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleApprovePlan approves a pending change plan.
+//
+// Description:
+//
+//	POST /v1/trace/plans/:id/approve with an optional {"comment": "..."}.
+//	Returns 409 PLAN_NOT_PENDING if the plan was already decided or expired.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleApprovePlan(c *gin.Context) {
+	h.handlePlanDecision(c, "HandleApprovePlan", h.svc.ApprovePlan)
+}
+
+// HandleRejectPlan rejects a pending change plan. See HandleApprovePlan.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleRejectPlan(c *gin.Context) {
+	h.handlePlanDecision(c, "HandleRejectPlan", h.svc.RejectPlan)
+}
+
+// handlePlanDecision applies an approve or reject decision.
+func (h *Handlers) handlePlanDecision(c *gin.Context, handler string, decide func(planID, comment string) (PlanSummary, error)) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", handler)
+
+	// The body is optional; an empty body means no comment.
+	var req PlanDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	planID := c.Param("id")
+	summary, err := decide(planID, req.Comment)
+	switch {
+	case errors.Is(err, ErrPlanNotFound):
+		apierror.Write(c, http.StatusNotFound, apierror.CodePlanNotFound, "Plan not found")
+		return
+	case errors.Is(err, ErrPlanNotPending):
+		apierror.Write(c, http.StatusConflict, apierror.CodePlanNotPending, err.Error())
+		return
+	case err != nil:
+		logger.Error("Plan decision failed", "plan_id", planID, telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to update plan")
+		return
+	}
+
+	logger.Info("Plan decided", "plan_id", planID, "status", summary.Status, "session_id", summary.SessionID)
+	c.JSON(http.StatusOK, summary)
+}
+
+// PlanConflictProblem is the 409 response when a plan no longer matches
+// the source.
+type PlanConflictProblem struct {
+	*apierror.Problem
+
+	// StaleHunks lists the plan changes that no longer apply.
+	StaleHunks []coordinate.StaleHunk `json:"stale_hunks"`
+}
+
+// HandleApplyPlan writes an approved change plan to the project files.
+//
+// Description:
+//
+//	POST /v1/trace/plans/:id/apply with an optional {"dry_run": true}.
+//	A dry run reports the exact edits without writing and also accepts a
+//	pending plan. The apply is checked against the safety policy as the
+//	"apply_plan" tool with the plan's files, under the default run mode's
+//	tool rules: the caller cannot pick a more permissive mode. Returns 409 PLAN_NOT_APPROVED for a plan that is not approved,
+//	and 409 PLAN_CONFLICT with stale_hunks if the source changed since
+//	the plan was made, in which case nothing is written.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleApplyPlan(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleApplyPlan")
+	start := time.Now()
+
+	// The body is optional; an empty body means a real apply.
+	var req ApplyPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	planID := c.Param("id")
+	_, planData, err := h.svc.PlanRecord(planID)
+	if err != nil {
+		apierror.Write(c, http.StatusNotFound, apierror.CodePlanNotFound, "Plan not found")
+		return
+	}
+	plan, ok := planData.(*coordinate.ChangePlan)
+	if !ok {
+		logger.Error("Invalid plan type", "plan_id", planID)
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Invalid plan data")
+		return
+	}
+
+	decision, err := h.checkPlanApply(c.Request.Context(), plan)
+	if err != nil {
+		logger.Error("Failed to evaluate safety policy", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to evaluate safety policy")
+		return
+	}
+	if !decision.Allowed {
+		logger.Warn("Safety policy denied plan apply",
+			"plan_id", planID,
+			"rule_id", decision.RuleID,
+			"reason", decision.Reason,
+		)
+		apierror.New(http.StatusForbidden, apierror.CodeSafetyDenied, decision.Reason).
+			WithDetails("Denied by safety rule " + decision.RuleID + "; adjust the rule in TRACE_SAFETY_POLICY to apply this plan").Write(c)
+		return
+	}
+
+	resp, err := h.svc.ApplyPlan(c.Request.Context(), planID, req.DryRun)
+	var conflict *coordinate.ConflictError
+	switch {
+	case errors.Is(err, ErrPlanNotFound):
+		apierror.Write(c, http.StatusNotFound, apierror.CodePlanNotFound, "Plan not found")
+		return
+	case errors.Is(err, ErrPlanNotApproved):
+		apierror.Write(c, http.StatusConflict, apierror.CodePlanNotApproved, err.Error())
+		return
+	case errors.As(err, &conflict):
+		logger.Warn("Plan conflicts with source", "plan_id", planID, "stale_hunks", len(conflict.Hunks))
+		writePlanConflict(c, conflict)
+		return
+	case errors.Is(err, coordinate.ErrOutsideRoot):
+		apierror.Write(c, http.StatusBadRequest, apierror.CodePathTraversal, err.Error())
+		return
+	case errors.Is(err, ErrGraphNotInitialized), errors.Is(err, ErrGraphExpired):
+		apierror.Write(c, http.StatusNotFound, apierror.CodeGraphNotFound, "The plan's graph is no longer cached")
+		return
+	case err != nil:
+		logger.Error("Plan apply failed", "plan_id", planID, telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to apply plan")
+		return
+	}
+
+	resp.LatencyMs = time.Since(start).Milliseconds()
+	logger.Info("Plan applied",
+		"plan_id", planID,
+		"dry_run", req.DryRun,
+		"files", len(resp.Files),
+		"edits", resp.TotalEdits,
+		"status", resp.Summary.Status,
+	)
+	c.JSON(http.StatusOK, resp)
+}
+
+// writePlanConflict writes the 409 response for a stale plan with its
+// stale hunks.
+func writePlanConflict(c *gin.Context, conflict *coordinate.ConflictError) {
+	problem := &PlanConflictProblem{
+		Problem:    apierror.New(http.StatusConflict, apierror.CodePlanConflict, conflict.Error()),
+		StaleHunks: conflict.Hunks,
+	}
+	problem.Instance = c.Request.URL.Path
+	problem.TraceID = apierror.TraceID(c.Request.Context())
+	body, err := json.Marshal(problem)
+	if err != nil {
+		apierror.Write(c, http.StatusConflict, apierror.CodePlanConflict, conflict.Error())
+		return
+	}
+	c.Data(http.StatusConflict, apierror.ContentType, body)
+}
+
+// HandlePlanEvents streams plan status events as server-sent events.
+//
+// Description:
+//
+//	GET /v1/trace/plans/events?session_id=. Sends one "plan_status" event
+//	per plan creation, approval, rejection and expiry, and a "heartbeat"
+//	event every 15 seconds. Each heartbeat also expires overdue plans, so
+//	expiry shows up on the stream without other traffic.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandlePlanEvents(c *gin.Context) {
+	emitter := h.svc.EventEmitter()
+	if emitter == nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodePlanEventsNotAvailable, "Plan events are not available")
+		return
+	}
+
+	sessionID := c.Query("session_id")
+	ch := make(chan events.Event, 64)
+	subID := emitter.SubscribeWithFilter(events.ChannelHandler(ch, true), func(e *events.Event) bool {
+		if sessionID == "" {
+			return true
+		}
+		data, ok := e.Data.(*events.PlanStatusData)
+		return ok && data.SessionID == sessionID
+	}, events.TypePlanStatus)
+	defer emitter.Unsubscribe(subID)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	ctx := c.Request.Context()
+	heartbeat := time.NewTicker(planEventsHeartbeat)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-heartbeat.C:
+			h.svc.ExpirePlans()
+			c.SSEvent("heartbeat", gin.H{"ts": time.Now().UnixMilli()})
+			return true
+		case event := <-ch:
+			c.SSEvent(string(events.TypePlanStatus), event)
+			return true
+		}
+	})
+}
@@ -0,0 +1,224 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/projectsummary"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// summaryKey returns the key of a graph's project summary: its content
+// hash, or its build time for graphs without one.
+func summaryKey(cached *CachedGraph) string {
+	if cached.ContentHash != "" {
+		return cached.ContentHash
+	}
+	return fmt.Sprintf("built-%d", cached.BuiltAtMilli)
+}
+
+// SetSummaryPolisher sets the polisher that writes the prose of project
+// summaries. Prose is written lazily, the first time a summary is asked
+// for with prose, and kept until the graph's content changes.
+//
+// Inputs:
+//
+//	p - The polisher. Can be nil to disable prose.
+func (s *Service) SetSummaryPolisher(p *projectsummary.Polisher) {
+	s.summariesMu.Lock()
+	defer s.summariesMu.Unlock()
+	s.summaryPolisher = p
+}
+
+// refreshProjectSummaries generates the project summary of a newly
+// installed graph unless one exists for its content, and drops the
+// summaries of graphs no longer installed.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) refreshProjectSummaries(ctx context.Context, cached *CachedGraph) {
+	if cached.Graph == nil {
+		return
+	}
+	key := summaryKey(cached)
+	s.summariesMu.Lock()
+	_, ok := s.summaries[key]
+	s.summariesMu.Unlock()
+
+	var summary *projectsummary.Summary
+	if !ok {
+		summary = projectsummary.Generate(context.WithoutCancel(ctx), cached.Graph, cached.Index, cached.Dependencies)
+	}
+
+	s.mu.RLock()
+	live := make(map[string]bool, len(s.graphs))
+	for _, g := range s.graphs {
+		live[summaryKey(g)] = true
+	}
+	s.mu.RUnlock()
+
+	s.summariesMu.Lock()
+	defer s.summariesMu.Unlock()
+	if summary != nil && s.summaries[key] == nil {
+		s.summaries[key] = summary
+	}
+	for k := range s.summaries {
+		if !live[k] {
+			delete(s.summaries, k)
+		}
+	}
+}
+
+// ProjectSummary returns the project summary of a cached graph.
+//
+// Description:
+//
+//	Summaries are generated when a graph is installed; one missing for
+//	any reason is generated now. With prose, a summary without Prose
+//	starts the polisher in the background, and a later call returns the
+//	polished summary. Without a polisher, prose is ignored.
+//
+// Inputs:
+//
+//	graphID - The graph.
+//	prose - Whether to start writing Prose if it is missing.
+//
+// Outputs:
+//
+//	*projectsummary.Summary - The summary. Must not be modified.
+//	bool - True while Prose is being written.
+//	error - ErrGraphNotInitialized or ErrGraphExpired.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ProjectSummary(graphID string, prose bool) (*projectsummary.Summary, bool, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, false, err
+	}
+	key := summaryKey(cached)
+	s.summariesMu.Lock()
+	summary := s.summaries[key]
+	s.summariesMu.Unlock()
+	if summary == nil {
+		summary = projectsummary.Generate(context.Background(), cached.Graph, cached.Index, cached.Dependencies)
+		s.summariesMu.Lock()
+		if existing := s.summaries[key]; existing != nil {
+			summary = existing
+		} else {
+			s.summaries[key] = summary
+		}
+		s.summariesMu.Unlock()
+	}
+
+	s.summariesMu.Lock()
+	defer s.summariesMu.Unlock()
+	polisher := s.summaryPolisher
+	if !prose || polisher == nil || summary.Prose != "" {
+		return summary, s.polishing[key], nil
+	}
+	if !s.polishing[key] {
+		s.polishing[key] = true
+		go s.polishSummary(polisher, key, summary)
+	}
+	return summary, true, nil
+}
+
+// polishSummary writes the prose of the summary stored under key.
+func (s *Service) polishSummary(p *projectsummary.Polisher, key string, summary *projectsummary.Summary) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			slog.Error("Panic in project summary goroutine", slog.Any("panic", rec))
+		}
+		s.summariesMu.Lock()
+		delete(s.polishing, key)
+		s.summariesMu.Unlock()
+	}()
+
+	polished, err := p.Polish(context.Background(), summary)
+	if err != nil {
+		slog.Warn("Project summary prose failed",
+			slog.String("content_hash", summary.ContentHash),
+			telemetry.SensitiveLogError("error", err))
+		return
+	}
+	s.summariesMu.Lock()
+	defer s.summariesMu.Unlock()
+	// The graph may have been rebuilt or evicted meanwhile.
+	if s.summaries[key] == summary {
+		s.summaries[key] = polished
+	}
+}
+
+// HandleProjectSummary handles GET /v1/trace/summary.
+//
+// Description:
+//
+//	Returns the project summary of a cached graph: languages, directory
+//	tree, the largest and most depended-on packages, frameworks and entry
+//	points, as structured fields and as the bounded text the agent's
+//	Plan phase receives. The summary is regenerated whenever the graph's
+//	content changes. prose=true asks the configured cheap model for a
+//	short description; the first request returns prose_pending and a
+//	later one the description.
+//
+// Query Parameters:
+//
+//	graph_id: ID of the graph to query (optional, uses first cached if not specified)
+//	project_root: Project root to look up graph (alternative to graph_id)
+//	git_ref: With project_root, the git_ref the graph was built from
+//	prose: "true" to write the prose description if missing
+//	max_chars: Size budget of text (default projectsummary.DefaultMaxChars)
+//
+// Response:
+//
+//	200 OK: ProjectSummaryResponse
+//	400 Bad Request: Invalid max_chars
+//	404 Not Found: No graphs cached or graph not found
+//
+// Thread Safety: This method is safe for concurrent use.
+func (h *Handlers) HandleProjectSummary(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleProjectSummary")
+
+	maxChars := projectsummary.DefaultMaxChars
+	if raw := c.Query("max_chars"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, "max_chars must be a positive integer")
+			return
+		}
+		maxChars = n
+	}
+
+	graphID, _, ok := h.statusGraph(c, logger)
+	if !ok {
+		return
+	}
+	summary, pending, err := h.svc.ProjectSummary(graphID, c.Query("prose") == "true")
+	if err != nil {
+		logger.Warn("Graph not found", "graph_id", graphID, telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusNotFound, apierror.CodeGraphNotFound, "graph not found")
+		return
+	}
+	c.JSON(http.StatusOK, ProjectSummaryResponse{
+		GraphID:      graphID,
+		Summary:      summary,
+		Text:         summary.Render(maxChars),
+		ProsePending: pending,
+	})
+}
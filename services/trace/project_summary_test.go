@@ -0,0 +1,110 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/projectsummary"
+)
+
+// proseChat answers every request with a fixed description.
+type proseChat struct{}
+
+func (proseChat) Chat(context.Context, []providers.Message, providers.ChatOptions) (string, error) {
+	return "A shop with payment and order services.", nil
+}
+
+func TestHandlers_HandleProjectSummary(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+	get := func(path string) (*httptest.ResponseRecorder, ProjectSummaryResponse) {
+		t.Helper()
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp ProjectSummaryResponse
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+		}
+		return w, resp
+	}
+
+	if w, _ := get("/v1/trace/summary?graph_id=nonexistent"); w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for unknown graph, got %d", http.StatusNotFound, w.Code)
+	}
+
+	root := writeMonorepo(t)
+	init, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if w, _ := get("/v1/trace/summary?max_chars=0&graph_id=" + init.GraphID); w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d for max_chars=0, got %d", http.StatusBadRequest, w.Code)
+	}
+	w, first := get("/v1/trace/summary?graph_id=" + init.GraphID)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if first.Summary == nil || first.Files != 3 || first.CentralPackages[0].Path != "store" {
+		t.Fatalf("unexpected summary: %s", w.Body.String())
+	}
+	if !strings.HasPrefix(first.Text, "3 files,") || first.Prose != "" || first.ProsePending {
+		t.Errorf("text = %q, prose = %q, pending = %v", first.Text, first.Prose, first.ProsePending)
+	}
+
+	// prose=true writes the description in the background.
+	svc.SetSummaryPolisher(projectsummary.NewPolisher(proseChat{}, "small"))
+	if _, resp := get("/v1/trace/summary?prose=true&graph_id=" + init.GraphID); !resp.ProsePending && resp.Prose == "" {
+		t.Errorf("prose neither pending nor written: %+v", resp)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	var polished ProjectSummaryResponse
+	for time.Now().Before(deadline) {
+		if _, polished = get("/v1/trace/summary?graph_id=" + init.GraphID); polished.Prose != "" {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if polished.ProseModel != "small" || !strings.HasPrefix(polished.Text, "A shop with payment") {
+		t.Fatalf("polished summary = %+v", polished)
+	}
+
+	// A rebuild of changed source regenerates the summary.
+	if err := os.WriteFile(filepath.Join(root, "store", "memory.go"), []byte("package store\n\nfunc NewMemory() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rebuilt, err := svc.Init(context.Background(), root, nil, nil, true)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	_, after := get("/v1/trace/summary?graph_id=" + rebuilt.GraphID)
+	if after.Files != 4 || after.ContentHash == first.ContentHash || after.Prose != "" {
+		t.Errorf("summary after rebuild = %+v, want 4 files, a new hash and no prose", after)
+	}
+	svc.summariesMu.Lock()
+	_, stale := svc.summaries[first.ContentHash]
+	svc.summariesMu.Unlock()
+	if stale {
+		t.Error("the summary of the replaced graph was kept")
+	}
+}
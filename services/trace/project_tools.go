@@ -0,0 +1,119 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools/file"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools/project"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// builtinToolNames returns the names a project tool may not take: every
+// agent tool and every endpoint tool listed by GET /v1/trace/tools.
+var builtinToolNames = sync.OnceValue(func() []string {
+	g := graph.NewGraph("/")
+	g.Freeze()
+	registry := tools.NewRegistry()
+	tools.RegisterExploreTools(registry, g, index.NewSymbolIndex())
+	tools.RegisterSemanticTools(registry, nil, "", nil, nil)
+	file.RegisterFileTools(registry, file.NewConfig("/"))
+	registry.Register(tools.NewSafetyCheckTool(nil, ""))
+
+	names := registry.Names()
+	for _, def := range allToolDefinitions() {
+		names = append(names, def.Name)
+	}
+	return names
+})
+
+// recordProjectTools loads the declarative tools of the project's
+// .trace/tools.yaml.
+//
+// Description:
+//
+//	A tools file that fails to parse, or that names a built-in tool, is
+//	logged and reported; the graph is still built, without project
+//	tools.
+//
+// Outputs:
+//
+//	[]project.Spec - The tools. Nil when there are none or the file is
+//	  invalid.
+//	error - Why the tools file was ignored, for InitResponse.Errors.
+func recordProjectTools(projectRoot string) ([]project.Spec, error) {
+	specs, err := project.Load(projectRoot, builtinToolNames())
+	if err != nil {
+		slog.Warn("Ignoring project tools",
+			telemetry.SensitiveLog("root", projectRoot),
+			telemetry.SensitiveLogError("error", err),
+		)
+		return nil, err
+	}
+	if len(specs) > 0 {
+		slog.Info("Loaded project tools", slog.Int("tools", len(specs)))
+	}
+	return specs, nil
+}
+
+// projectToolRegistry returns a registry holding only the graph's project
+// tools.
+func projectToolRegistry(cached *CachedGraph) *tools.Registry {
+	registry := tools.NewRegistry()
+	project.RegisterProjectTools(registry, cached.ProjectTools, cached.ProjectRoot)
+	return registry
+}
+
+// projectToolDefinitions describes the graph's project tools in the
+// GET /v1/trace/tools format. Their input schema is the tool's parameters
+// and their output schema the tool's result; both are passed through
+// POST /v1/trace/tools/:name/execute.
+func projectToolDefinitions(cached *CachedGraph) []ToolDefinition {
+	defs := make([]ToolDefinition, 0, len(cached.ProjectTools))
+	for _, spec := range cached.ProjectTools {
+		tool := project.NewTool(spec, cached.ProjectRoot)
+		schema := tool.Schema()
+		def := ToolDefinition{
+			Name:         spec.Name,
+			Description:  spec.Description,
+			Category:     string(tools.CategoryProject),
+			Parameters:   []ToolParam{},
+			Returns:      spec.Returns,
+			InputSchema:  schema.Input,
+			OutputSchema: schema.Output,
+		}
+		if def.Returns == "" {
+			def.Returns = fmt.Sprintf("Result of the project's %s tool", spec.Kind)
+		}
+		for _, name := range spec.ParamNames() {
+			p := spec.Parameters[name]
+			param := ToolParam{
+				Name:        name,
+				Type:        string(tool.Definition().Parameters[name].Type),
+				Description: p.Description,
+				Required:    p.Required,
+				Enum:        p.Enum,
+			}
+			if p.Default != nil {
+				param.Default = fmt.Sprint(p.Default)
+			}
+			def.Parameters = append(def.Parameters, param)
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools/project"
+)
+
+// writeToolsFile writes .trace/tools.yaml under root.
+func writeToolsFile(t *testing.T, root, content string) {
+	t.Helper()
+	dir := filepath.Join(root, ".trace")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "tools.yaml"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const storeMethodsTool = `tools:
+  - name: list_store_methods
+    description: Lists the Save methods of the project's store types.
+    kind: regex
+    keywords: [store, save]
+    paths: ["services/**/*.go"]
+    pattern: '^func \((\w+) \*(?P<type>{{prefix}}\w*Store)\) (?P<method>Save)\('
+    returns: Store types and their Save methods
+    parameters:
+      prefix:
+        description: Only store types starting with this prefix
+`
+
+func TestProjectTools_RegexToolEndToEnd(t *testing.T) {
+	root := writeMonorepo(t)
+	writeToolsFile(t, root, storeMethodsTool)
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	router := setupTestRouter(svc)
+
+	// Listed with the graph's project tools.
+	req := httptest.NewRequest(http.MethodGet, "/v1/trace/tools?graph_id="+resp.GraphID, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /tools: status %d: %s", w.Code, w.Body.String())
+	}
+	var listing ToolsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &listing); err != nil {
+		t.Fatal(err)
+	}
+	var def *ToolDefinition
+	for i := range listing.Tools {
+		if listing.Tools[i].Name == "list_store_methods" {
+			def = &listing.Tools[i]
+		}
+	}
+	if def == nil {
+		t.Fatalf("project tool not listed among %d tools", len(listing.Tools))
+	}
+	if def.Category != "project" || len(def.Parameters) != 1 || def.Parameters[0].Name != "prefix" {
+		t.Errorf("definition = %+v", def)
+	}
+	if def.InputSchema == nil || def.InputSchema.Properties["prefix"] == nil {
+		t.Errorf("input_schema = %+v", def.InputSchema)
+	}
+	if def.OutputSchema == nil || def.OutputSchema.Properties["matches"] == nil {
+		t.Errorf("output_schema = %+v", def.OutputSchema)
+	}
+
+	// Executed directly.
+	w = postJSON(router, "/v1/trace/tools/list_store_methods/execute",
+		`{"graph_id":"`+resp.GraphID+`","params":{"prefix":"Payment"}}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("execute: status %d: %s", w.Code, w.Body.String())
+	}
+	var out struct {
+		Tool   string              `json:"tool"`
+		Result project.RegexResult `json:"result"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Tool != "list_store_methods" || out.Result.Count != 1 {
+		t.Fatalf("response = %s", w.Body.String())
+	}
+	m := out.Result.Matches[0]
+	if m.File != "services/payment/charge.go" || m.Captures["type"] != "PaymentStore" || m.Captures["method"] != "Save" {
+		t.Errorf("match = %+v", m)
+	}
+
+	// Without the filter, both stores.
+	w = postJSON(router, "/v1/trace/tools/list_store_methods/execute", `{"graph_id":"`+resp.GraphID+`"}`)
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || out.Result.Count != 2 {
+		t.Errorf("unfiltered response = %s", w.Body.String())
+	}
+}
+
+func TestProjectTools_ExecuteErrors(t *testing.T) {
+	root := writeMonorepo(t)
+	writeToolsFile(t, root, storeMethodsTool)
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	router := setupTestRouter(svc)
+
+	tests := []struct {
+		name, path, body string
+		status           int
+		code             string
+	}{
+		{"unknown graph", "/v1/trace/tools/list_store_methods/execute", `{"graph_id":"nope"}`, http.StatusBadRequest, "GRAPH_NOT_FOUND"},
+		{"unknown tool", "/v1/trace/tools/list_things/execute", `{"graph_id":"` + resp.GraphID + `"}`, http.StatusNotFound, "TOOL_NOT_FOUND"},
+		{"built-in tool", "/v1/trace/tools/find_callers/execute", `{"graph_id":"` + resp.GraphID + `"}`, http.StatusBadRequest, "INVALID_PARAMETER"},
+		{"bad parameter", "/v1/trace/tools/list_store_methods/execute", `{"graph_id":"` + resp.GraphID + `","params":{"prefix":["a"]}}`, http.StatusBadRequest, "INVALID_PARAMETER"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(router, tt.path, tt.body)
+			if w.Code != tt.status || !strings.Contains(w.Body.String(), tt.code) {
+				t.Errorf("status %d, body %s; want %d %s", w.Code, w.Body.String(), tt.status, tt.code)
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/trace/tools?graph_id=nope", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET /tools with an unknown graph: status %d", w.Code)
+	}
+}
+
+func TestProjectTools_BuiltinCollisionRejected(t *testing.T) {
+	root := writeMonorepo(t)
+	writeToolsFile(t, root, `tools:
+  - name: find_callers
+    description: Shadows the built-in.
+    kind: glob
+    paths: ["**/*.go"]
+`)
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	found := false
+	for _, e := range resp.Errors {
+		if strings.Contains(e, "find_callers") && strings.Contains(e, "built-in") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Init errors = %v, want the collision", resp.Errors)
+	}
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cached.ProjectTools) != 0 {
+		t.Errorf("project tools = %+v, want none", cached.ProjectTools)
+	}
+}
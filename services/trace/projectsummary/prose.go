@@ -0,0 +1,118 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package projectsummary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+)
+
+// DefaultProseTimeout bounds one prose request.
+const DefaultProseTimeout = 2 * time.Minute
+
+// maxProseLen caps Summary.Prose in bytes; longer replies are cut at a word.
+const maxProseLen = 900
+
+// ErrEmptyProse indicates the model returned no usable text.
+var ErrEmptyProse = errors.New("empty project description")
+
+// prosePrompt is the system prompt of every prose request.
+const prosePrompt = "You describe software projects to engineers who are about to work on them. " +
+	"Given facts extracted from a code graph, reply with one paragraph of at most 100 words " +
+	"saying what the project is and how it is organized. Use only the facts given, " +
+	"do not use markdown, and do not add anything else."
+
+// Polisher writes Summary.Prose with a chat model.
+//
+// Thread Safety: Safe for concurrent use.
+type Polisher struct {
+	client  providers.ChatClient
+	model   string
+	timeout time.Duration
+}
+
+// NewPolisher creates a polisher.
+//
+// Inputs:
+//
+//	client - Chat client of the cheap (router or param-extractor) model. Must not be nil.
+//	model - Sent as ChatOptions.Model.
+//
+// Outputs:
+//
+//	*Polisher - The polisher.
+func NewPolisher(client providers.ChatClient, model string) *Polisher {
+	return &Polisher{client: client, model: model, timeout: DefaultProseTimeout}
+}
+
+// Model returns the model the polisher asks.
+func (p *Polisher) Model() string {
+	return p.model
+}
+
+// Polish returns a copy of s whose Prose is written by the model from the
+// rest of the summary.
+//
+// Inputs:
+//
+//	ctx - Cancels the request.
+//	s - The summary. Not modified.
+//
+// Outputs:
+//
+//	*Summary - The copy with Prose and ProseModel set.
+//	error - The chat error, or ErrEmptyProse.
+//
+// Thread Safety: Safe for concurrent use.
+func (p *Polisher) Polish(ctx context.Context, s *Summary) (*Summary, error) {
+	callCtx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	facts := *s
+	facts.Prose = ""
+	reply, err := p.client.Chat(callCtx, []providers.Message{
+		{Role: "system", Content: prosePrompt},
+		{Role: "user", Content: "Describe this project.\n\n" + facts.Render(DefaultMaxChars)},
+	}, providers.ChatOptions{Temperature: 0, MaxTokens: 256, Model: p.model})
+	if err != nil {
+		return nil, fmt.Errorf("describing project: %w", err)
+	}
+	prose := paragraph(reply)
+	if prose == "" {
+		return nil, ErrEmptyProse
+	}
+	facts.Prose = prose
+	facts.ProseModel = p.model
+	return &facts, nil
+}
+
+// paragraph reduces a model reply to its first paragraph on one line,
+// without markdown quoting, cut at maxProseLen bytes.
+func paragraph(reply string) string {
+	reply = strings.TrimSpace(reply)
+	if i := strings.Index(reply, "\n\n"); i >= 0 {
+		reply = reply[:i]
+	}
+	text := strings.Join(strings.Fields(strings.Trim(reply, "\"'`*> ")), " ")
+	if len(text) > maxProseLen {
+		cut := strings.LastIndex(text[:maxProseLen], " ")
+		if cut <= 0 {
+			cut = maxProseLen
+		}
+		text = strings.TrimRight(text[:cut], ",;:") + "…"
+	}
+	return text
+}
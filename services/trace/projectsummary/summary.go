@@ -0,0 +1,529 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package projectsummary derives a compact, deterministic overview of a
+// project from its code graph: languages, directory tree, the largest and
+// most depended-on packages, frameworks and entry points. The overview is
+// injected into agent prompts so the model starts oriented.
+package projectsummary
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/dependencies"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/explore"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// Limits on the lists a Summary keeps.
+const (
+	// DefaultMaxChars is the Render budget used for prompts.
+	DefaultMaxChars = 2400
+
+	// MaxDirectoryDepth is the depth of Summary.Directories.
+	MaxDirectoryDepth = 2
+
+	maxDirectories = 40
+	maxPackages    = 8
+	maxEntryPoints = 15
+)
+
+// Summary is the overview of one graph.
+type Summary struct {
+	// ContentHash is the content hash of the graph the summary describes.
+	ContentHash string `json:"content_hash"`
+
+	// Files and Symbols count the graph's source files and the symbols
+	// counted below.
+	Files   int `json:"files"`
+	Symbols int `json:"symbols"`
+
+	// Languages are the languages present, by descending symbol count.
+	Languages []LanguageStat `json:"languages"`
+
+	// Directories are the directories up to MaxDirectoryDepth, in path
+	// order, with the files and symbols beneath them.
+	Directories []DirectoryStat `json:"directories"`
+
+	// LargestPackages are the packages with the most symbols.
+	LargestPackages []PackageStat `json:"largest_packages"`
+
+	// CentralPackages are the packages used by the most other packages.
+	CentralPackages []PackageStat `json:"central_packages"`
+
+	// Frameworks are the detected frameworks, sorted.
+	Frameworks []string `json:"frameworks,omitempty"`
+
+	// EntryPoints are the main functions and the handlers, commands and
+	// jobs registered with a framework, most fundamental type first.
+	EntryPoints []EntryPointRef `json:"entry_points,omitempty"`
+
+	// Prose is a short description written by a model from the other
+	// fields. Empty until a Polisher has run.
+	Prose string `json:"prose,omitempty"`
+
+	// ProseModel is the model that wrote Prose.
+	ProseModel string `json:"prose_model,omitempty"`
+}
+
+// LanguageStat counts one language's files and symbols.
+type LanguageStat struct {
+	Language string `json:"language"`
+	Files    int    `json:"files"`
+	Symbols  int    `json:"symbols"`
+}
+
+// DirectoryStat counts the files and symbols beneath one directory.
+type DirectoryStat struct {
+	Path    string `json:"path"`
+	Files   int    `json:"files"`
+	Symbols int    `json:"symbols"`
+}
+
+// PackageStat describes one package: the symbols declared directly in a
+// directory and the number of other packages whose code calls, imports,
+// implements, embeds or references them.
+type PackageStat struct {
+	Path    string `json:"path"`
+	Symbols int    `json:"symbols"`
+	FanIn   int    `json:"fan_in"`
+}
+
+// EntryPointRef is an entry point of the project.
+type EntryPointRef struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Type      string `json:"type"`
+	Framework string `json:"framework,omitempty"`
+	FilePath  string `json:"file_path"`
+	Line      int    `json:"line"`
+
+	// Trigger is how a framework invokes the entry point: the route
+	// ("GET /users"), command name or schedule. Empty for main functions.
+	Trigger string `json:"trigger,omitempty"`
+}
+
+// knownFrameworks maps dependency names to the framework they provide.
+var knownFrameworks = map[string]string{
+	"github.com/gin-gonic/gin":            "gin",
+	"github.com/labstack/echo":            "echo",
+	"github.com/labstack/echo/v4":         "echo",
+	"github.com/gofiber/fiber/v2":         "fiber",
+	"github.com/spf13/cobra":              "cobra",
+	"github.com/gorilla/mux":              "gorilla/mux",
+	"github.com/go-chi/chi/v5":            "chi",
+	"google.golang.org/grpc":              "grpc",
+	"express":                             "express",
+	"fastify":                             "fastify",
+	"koa":                                 "koa",
+	"@nestjs/core":                        "nestjs",
+	"react":                               "react",
+	"next":                                "next",
+	"vue":                                 "vue",
+	"@angular/core":                       "angular",
+	"django":                              "django",
+	"flask":                               "flask",
+	"fastapi":                             "fastapi",
+	"celery":                              "celery",
+	"click":                               "click",
+	"github.com/urfave/cli/v2":            "urfave/cli",
+	"go.opentelemetry.io/otel":            "opentelemetry",
+	"github.com/prometheus/client_golang": "prometheus",
+}
+
+// entryPointRank orders entry point types in Summary.EntryPoints.
+var entryPointRank = map[explore.EntryPointType]int{
+	explore.EntryPointMain:      0,
+	explore.EntryPointCommand:   1,
+	explore.EntryPointHandler:   2,
+	explore.EntryPointGRPC:      3,
+	explore.EntryPointLambda:    4,
+	explore.EntryPointScheduled: 5,
+}
+
+// Generate summarizes g.
+//
+// Description:
+//
+//	The result depends only on the graph, the index and deps, so the same
+//	source always yields the same summary. A package is the directory of
+//	a file. Generated symbols and package, file, import and parameter
+//	nodes are not counted. Frameworks come from the framework
+//	registrations explore.EntryPointFinder finds and from the project's
+//	direct dependencies.
+//
+// Inputs:
+//
+//	ctx - Bounds the entry point scan.
+//	g - The graph. Must be frozen.
+//	idx - The graph's symbol index. Nil skips entry points.
+//	deps - The project's dependencies. Can be nil.
+//
+// Outputs:
+//
+//	*Summary - The summary. Never nil.
+//
+// Thread Safety: Safe for concurrent use on a frozen graph.
+func Generate(ctx context.Context, g *graph.Graph, idx *index.SymbolIndex, deps *dependencies.Set) *Summary {
+	s := &Summary{ContentHash: g.ContentHash()}
+
+	languages := make(map[string]*LanguageStat)
+	packages := make(map[string]*PackageStat)
+	files := make(map[string]string) // file path -> language
+	fileSymbols := make(map[string]int)
+	for _, n := range g.Nodes() {
+		sym := n.Symbol
+		if sym == nil || sym.FilePath == "" || sym.Generated || !counted(sym.Kind) {
+			continue
+		}
+		files[sym.FilePath] = sym.Language
+		fileSymbols[sym.FilePath]++
+		s.Symbols++
+		if languages[sym.Language] == nil {
+			languages[sym.Language] = &LanguageStat{Language: sym.Language}
+		}
+		languages[sym.Language].Symbols++
+		dir := packageOf(sym.FilePath)
+		if packages[dir] == nil {
+			packages[dir] = &PackageStat{Path: dir}
+		}
+		packages[dir].Symbols++
+	}
+	s.Files = len(files)
+
+	directories := make(map[string]*DirectoryStat)
+	for file, language := range files {
+		if languages[language] != nil {
+			languages[language].Files++
+		}
+		for _, dir := range ancestors(file) {
+			if directories[dir] == nil {
+				directories[dir] = &DirectoryStat{Path: dir}
+			}
+			directories[dir].Files++
+			directories[dir].Symbols += fileSymbols[file]
+		}
+	}
+
+	for dir, users := range fanIn(g) {
+		if p := packages[dir]; p != nil {
+			p.FanIn = len(users)
+		}
+	}
+
+	for _, l := range languages {
+		s.Languages = append(s.Languages, *l)
+	}
+	slices.SortFunc(s.Languages, func(a, b LanguageStat) int {
+		if c := cmp.Compare(b.Symbols, a.Symbols); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Language, b.Language)
+	})
+
+	s.Directories = topDirectories(directories)
+	s.LargestPackages = topPackages(packages, func(p PackageStat) int { return p.Symbols })
+	s.CentralPackages = topPackages(packages, func(p PackageStat) int { return p.FanIn })
+
+	frameworks := make(map[string]bool)
+	if idx != nil {
+		s.EntryPoints = entryPoints(ctx, g, idx, frameworks)
+	}
+	if deps != nil {
+		for _, d := range deps.All() {
+			if name, ok := knownFrameworks[d.Name]; ok && d.Direct {
+				frameworks[name] = true
+			}
+		}
+	}
+	for name := range frameworks {
+		s.Frameworks = append(s.Frameworks, name)
+	}
+	slices.Sort(s.Frameworks)
+	return s
+}
+
+// counted reports whether symbols of kind are counted in a Summary.
+func counted(kind ast.SymbolKind) bool {
+	switch kind {
+	case ast.SymbolKindPackage, ast.SymbolKindFile, ast.SymbolKindImport, ast.SymbolKindParameter:
+		return false
+	}
+	return true
+}
+
+// packageOf returns the package of a slash-separated file path, "." for
+// files at the project root.
+func packageOf(file string) string {
+	return path.Dir(strings.TrimPrefix(file, "./"))
+}
+
+// ancestors returns the directories of file up to MaxDirectoryDepth,
+// outermost first, with "." for the project root.
+func ancestors(file string) []string {
+	dir := packageOf(file)
+	out := []string{"."}
+	if dir == "." {
+		return out
+	}
+	parts := strings.Split(dir, "/")
+	for i := 1; i <= len(parts) && i <= MaxDirectoryDepth; i++ {
+		out = append(out, strings.Join(parts[:i], "/"))
+	}
+	return out
+}
+
+// fanIn maps each package to the set of other packages with an edge into it.
+func fanIn(g *graph.Graph) map[string]map[string]bool {
+	users := make(map[string]map[string]bool)
+	for _, e := range g.Edges() {
+		switch e.Type {
+		case graph.EdgeTypeCalls, graph.EdgeTypeImports, graph.EdgeTypeImplements,
+			graph.EdgeTypeEmbeds, graph.EdgeTypeReferences:
+		default:
+			continue
+		}
+		from, ok := g.GetNode(e.FromID)
+		if !ok || from.Symbol == nil || from.Symbol.FilePath == "" {
+			continue
+		}
+		to, ok := g.GetNode(e.ToID)
+		if !ok || to.Symbol == nil || to.Symbol.FilePath == "" {
+			continue
+		}
+		fromPkg, toPkg := packageOf(from.Symbol.FilePath), packageOf(to.Symbol.FilePath)
+		if fromPkg == toPkg {
+			continue
+		}
+		if users[toPkg] == nil {
+			users[toPkg] = make(map[string]bool)
+		}
+		users[toPkg][fromPkg] = true
+	}
+	return users
+}
+
+// topDirectories returns at most maxDirectories directories, keeping the
+// ones with the most symbols, in path order.
+func topDirectories(directories map[string]*DirectoryStat) []DirectoryStat {
+	out := make([]DirectoryStat, 0, len(directories))
+	for _, d := range directories {
+		out = append(out, *d)
+	}
+	if len(out) > maxDirectories {
+		slices.SortFunc(out, func(a, b DirectoryStat) int {
+			if c := cmp.Compare(b.Symbols, a.Symbols); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.Path, b.Path)
+		})
+		out = out[:maxDirectories]
+	}
+	slices.SortFunc(out, func(a, b DirectoryStat) int { return cmp.Compare(a.Path, b.Path) })
+	return out
+}
+
+// topPackages returns at most maxPackages packages with a positive key,
+// by descending key and then path.
+func topPackages(packages map[string]*PackageStat, key func(PackageStat) int) []PackageStat {
+	var out []PackageStat
+	for _, p := range packages {
+		if key(*p) > 0 {
+			out = append(out, *p)
+		}
+	}
+	slices.SortFunc(out, func(a, b PackageStat) int {
+		if c := cmp.Compare(key(b), key(a)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Path, b.Path)
+	})
+	if len(out) > maxPackages {
+		out = out[:maxPackages]
+	}
+	return out
+}
+
+// entryPoints returns the main functions of g and the entry points found
+// at a framework registration, most fundamental type first, and adds the
+// frameworks of the latter to frameworks. Entry points matched on name
+// or signature alone are left out: outside main they are too noisy to
+// orient anyone. A failed scan yields none.
+func entryPoints(ctx context.Context, g *graph.Graph, idx *index.SymbolIndex, frameworks map[string]bool) []EntryPointRef {
+	result, err := explore.NewEntryPointFinder(g, idx).FindEntryPoints(ctx, explore.EntryPointOptions{
+		Type: explore.EntryPointAll,
+	})
+	if err != nil {
+		return nil
+	}
+	var out []EntryPointRef
+	for _, ep := range result.EntryPoints {
+		if ep.Type == explore.EntryPointTest || (ep.Type != explore.EntryPointMain && ep.Trigger == nil) {
+			continue
+		}
+		if n, ok := g.GetNode(ep.ID); ok && n.Symbol != nil && n.Symbol.Generated {
+			continue
+		}
+		ref := EntryPointRef{
+			ID:        ep.ID,
+			Name:      ep.Name,
+			Type:      string(ep.Type),
+			Framework: ep.Framework,
+			FilePath:  ep.FilePath,
+			Line:      ep.Line,
+		}
+		if ep.Trigger != nil {
+			ref.Trigger = triggerString(ep.Trigger)
+			if ep.Framework != "" {
+				frameworks[ep.Framework] = true
+			}
+		}
+		out = append(out, ref)
+	}
+	slices.SortStableFunc(out, func(a, b EntryPointRef) int {
+		return cmp.Compare(rank(a.Type), rank(b.Type))
+	})
+	if len(out) > maxEntryPoints {
+		out = out[:maxEntryPoints]
+	}
+	return out
+}
+
+// triggerString formats a trigger as a route, command name or schedule.
+func triggerString(t *explore.EntryPointTrigger) string {
+	switch {
+	case t.Path != "":
+		return strings.TrimSpace(t.Method + " " + t.Path)
+	case t.Command != "":
+		return t.Command
+	default:
+		return t.Schedule
+	}
+}
+
+// rank returns the position of an entry point type in Summary.EntryPoints.
+func rank(t string) int {
+	if r, ok := entryPointRank[explore.EntryPointType(t)]; ok {
+		return r
+	}
+	return len(entryPointRank)
+}
+
+// Render formats the summary as plain text of at most maxChars bytes.
+//
+// Description:
+//
+//	Sections come in order of importance: the overview (Prose when set),
+//	languages, frameworks, entry points, the most depended-on packages,
+//	the largest packages and the directory tree. Lines are added until
+//	the next one would exceed maxChars, so a small budget keeps the first
+//	sections whole and drops the rest.
+//
+// Inputs:
+//
+//	maxChars - The size budget. <= 0 uses DefaultMaxChars.
+//
+// Outputs:
+//
+//	string - The rendered summary.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Summary) Render(maxChars int) string {
+	if maxChars <= 0 {
+		maxChars = DefaultMaxChars
+	}
+	var b strings.Builder
+	for _, line := range s.lines() {
+		if b.Len()+len(line)+1 > maxChars {
+			break
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// lines returns every line of the rendered summary, most important first.
+func (s *Summary) lines() []string {
+	var lines []string
+	if s.Prose != "" {
+		lines = append(lines, s.Prose, "")
+	}
+	lines = append(lines, counts(s.Files, s.Symbols)+".")
+
+	if len(s.Languages) > 0 {
+		parts := make([]string, 0, len(s.Languages))
+		for _, l := range s.Languages {
+			parts = append(parts, fmt.Sprintf("%s (%s)", l.Language, counts(l.Files, l.Symbols)))
+		}
+		lines = append(lines, "Languages: "+strings.Join(parts, ", "))
+	}
+	if len(s.Frameworks) > 0 {
+		lines = append(lines, "Frameworks: "+strings.Join(s.Frameworks, ", "))
+	}
+	if len(s.EntryPoints) > 0 {
+		lines = append(lines, "", "Entry points:")
+		for _, ep := range s.EntryPoints {
+			kind := ep.Type
+			if ep.Framework != "" {
+				kind += ", " + ep.Framework
+			}
+			name := ep.Name
+			if ep.Trigger != "" {
+				name = ep.Trigger + " -> " + name
+			}
+			lines = append(lines, fmt.Sprintf("- %s (%s) %s:%d", name, kind, ep.FilePath, ep.Line))
+		}
+	}
+	if len(s.CentralPackages) > 0 {
+		lines = append(lines, "", "Most depended-on packages:")
+		for _, p := range s.CentralPackages {
+			lines = append(lines, fmt.Sprintf("- %s: used by %s, %s", p.Path, plural(p.FanIn, "package"), plural(p.Symbols, "symbol")))
+		}
+	}
+	if len(s.LargestPackages) > 0 {
+		lines = append(lines, "", "Largest packages:")
+		for _, p := range s.LargestPackages {
+			lines = append(lines, fmt.Sprintf("- %s: %s", p.Path, plural(p.Symbols, "symbol")))
+		}
+	}
+	if len(s.Directories) > 0 {
+		lines = append(lines, "", "Directories:")
+		for _, d := range s.Directories {
+			depth := 0
+			if d.Path != "." {
+				depth = strings.Count(d.Path, "/") + 1
+			}
+			lines = append(lines, fmt.Sprintf("%s%s/ (%s)",
+				strings.Repeat("  ", depth), path.Base(d.Path), counts(d.Files, d.Symbols)))
+		}
+	}
+	return lines
+}
+
+// counts formats a file and symbol count.
+func counts(files, symbols int) string {
+	return plural(files, "file") + ", " + plural(symbols, "symbol")
+}
+
+// plural formats n with word, adding an s unless n is 1.
+func plural(n int, word string) string {
+	if n == 1 {
+		return "1 " + word
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
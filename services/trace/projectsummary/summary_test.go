@@ -0,0 +1,160 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package projectsummary
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/dependencies"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// testProject builds a frozen graph of a small Go service: a main
+// package calling into pkg/store, which pkg/api also uses.
+func testProject(t *testing.T) (*graph.Graph, *index.SymbolIndex) {
+	t.Helper()
+	g := graph.NewGraph("/project")
+	idx := index.NewSymbolIndex()
+	for _, sym := range []*ast.Symbol{
+		{ID: "cmd/server/main.go:5:main", Name: "main", Kind: ast.SymbolKindFunction, FilePath: "cmd/server/main.go", StartLine: 5, EndLine: 5, Package: "main", Language: "go"},
+		{ID: "pkg/store/store.go:3:Store", Name: "Store", Kind: ast.SymbolKindStruct, FilePath: "pkg/store/store.go", StartLine: 3, EndLine: 3, Package: "store", Language: "go", Exported: true},
+		{ID: "pkg/store/store.go:8:Get", Name: "Get", Kind: ast.SymbolKindMethod, FilePath: "pkg/store/store.go", StartLine: 8, EndLine: 8, Package: "store", Language: "go", Exported: true},
+		{ID: "pkg/store/store.go:12:Put", Name: "Put", Kind: ast.SymbolKindMethod, FilePath: "pkg/store/store.go", StartLine: 12, EndLine: 12, Package: "store", Language: "go", Exported: true},
+		{ID: "pkg/api/api.go:4:Serve", Name: "Serve", Kind: ast.SymbolKindFunction, FilePath: "pkg/api/api.go", StartLine: 4, EndLine: 4, Package: "api", Language: "go", Exported: true},
+		{ID: "pkg/store/store.pb.go:1:Msg", Name: "Msg", Kind: ast.SymbolKindStruct, FilePath: "pkg/store/store.pb.go", StartLine: 1, EndLine: 1, Package: "store", Language: "go", Generated: true},
+		{ID: "web/app.ts:1:App", Name: "App", Kind: ast.SymbolKindFunction, FilePath: "web/app.ts", StartLine: 1, EndLine: 1, Language: "typescript", Exported: true},
+	} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+		if err := idx.Add(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range [][2]string{
+		{"cmd/server/main.go:5:main", "pkg/store/store.go:8:Get"},
+		{"cmd/server/main.go:5:main", "pkg/api/api.go:4:Serve"},
+		{"pkg/api/api.go:4:Serve", "pkg/store/store.go:12:Put"},
+		{"pkg/store/store.go:8:Get", "pkg/store/store.go:12:Put"},
+	} {
+		if err := g.AddEdge(e[0], e[1], graph.EdgeTypeCalls, ast.Location{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.Freeze()
+	return g, idx
+}
+
+func TestGenerate(t *testing.T) {
+	g, idx := testProject(t)
+	deps := dependencies.NewSet(
+		dependencies.Dependency{Ecosystem: dependencies.EcosystemGo, Name: "github.com/gin-gonic/gin", Direct: true},
+		dependencies.Dependency{Ecosystem: dependencies.EcosystemGo, Name: "github.com/spf13/cobra"},
+	)
+	s := Generate(context.Background(), g, idx, deps)
+
+	if s.ContentHash != g.ContentHash() || s.Files != 4 || s.Symbols != 6 {
+		t.Errorf("hash, files, symbols = %q, %d, %d; want %q, 4, 6", s.ContentHash, s.Files, s.Symbols, g.ContentHash())
+	}
+	wantLanguages := []LanguageStat{{Language: "go", Files: 3, Symbols: 5}, {Language: "typescript", Files: 1, Symbols: 1}}
+	if !reflect.DeepEqual(s.Languages, wantLanguages) {
+		t.Errorf("languages = %+v, want %+v", s.Languages, wantLanguages)
+	}
+	if len(s.CentralPackages) != 2 || s.CentralPackages[0] != (PackageStat{Path: "pkg/store", Symbols: 3, FanIn: 2}) {
+		t.Errorf("central packages = %+v, want pkg/store used by 2 first", s.CentralPackages)
+	}
+	if len(s.LargestPackages) == 0 || s.LargestPackages[0].Path != "pkg/store" {
+		t.Errorf("largest packages = %+v, want pkg/store first", s.LargestPackages)
+	}
+	var dirs []string
+	for _, d := range s.Directories {
+		dirs = append(dirs, d.Path)
+	}
+	if want := []string{".", "cmd", "cmd/server", "pkg", "pkg/api", "pkg/store", "web"}; !reflect.DeepEqual(dirs, want) {
+		t.Errorf("directories = %v, want %v", dirs, want)
+	}
+	if len(s.EntryPoints) == 0 || s.EntryPoints[0].Name != "main" || s.EntryPoints[0].Type != "main" {
+		t.Errorf("entry points = %+v, want main first", s.EntryPoints)
+	}
+	// cobra is only an indirect dependency.
+	if !reflect.DeepEqual(s.Frameworks, []string{"gin"}) {
+		t.Errorf("frameworks = %v, want [gin]", s.Frameworks)
+	}
+
+	if again := Generate(context.Background(), g, idx, deps); !reflect.DeepEqual(again, s) {
+		t.Error("Generate is not deterministic")
+	}
+}
+
+func TestSummary_Render(t *testing.T) {
+	g, idx := testProject(t)
+	s := Generate(context.Background(), g, idx, nil)
+
+	full := s.Render(0)
+	order := []string{"4 files, 6 symbols.", "Languages: go", "Entry points:", "Most depended-on packages:", "Largest packages:", "Directories:"}
+	last := -1
+	for _, section := range order {
+		i := strings.Index(full, section)
+		if i <= last {
+			t.Fatalf("section %q missing or out of order in:\n%s", section, full)
+		}
+		last = i
+	}
+
+	short := s.Render(120)
+	if len(short) > 120 || !strings.HasPrefix(short, "4 files, 6 symbols.") || strings.Contains(short, "Directories:") {
+		t.Errorf("Render(120) = %q, want the first sections only", short)
+	}
+}
+
+// fakeChat answers every request with reply.
+type fakeChat struct {
+	reply string
+	asked string
+}
+
+func (f *fakeChat) Chat(_ context.Context, messages []providers.Message, _ providers.ChatOptions) (string, error) {
+	f.asked = messages[len(messages)-1].Content
+	return f.reply, nil
+}
+
+func TestPolisher_Polish(t *testing.T) {
+	g, idx := testProject(t)
+	s := Generate(context.Background(), g, idx, nil)
+	chat := &fakeChat{reply: "\n\"A Go service with a store and an HTTP API.\"\n\nIgnored second paragraph."}
+
+	polished, err := NewPolisher(chat, "small").Polish(context.Background(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polished.Prose != "A Go service with a store and an HTTP API." || polished.ProseModel != "small" {
+		t.Errorf("prose, model = %q, %q", polished.Prose, polished.ProseModel)
+	}
+	if s.Prose != "" {
+		t.Error("Polish modified its input")
+	}
+	if !strings.Contains(chat.asked, "Entry points:") {
+		t.Errorf("prompt lacks the summary facts: %q", chat.asked)
+	}
+	if !strings.HasPrefix(polished.Render(0), polished.Prose) {
+		t.Error("Render does not lead with the prose")
+	}
+
+	if _, err := NewPolisher(&fakeChat{reply: "  "}, "small").Polish(context.Background(), s); err != ErrEmptyProse {
+		t.Errorf("empty reply: err = %v, want ErrEmptyProse", err)
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"log/slog"
 
 	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // CombinedResolver chains structural (Layer 1) and semantic (Layer 2) resolution.
@@ -86,7 +88,7 @@ func (c *CombinedResolver) Resolve(ctx context.Context, query string) *Extractio
 			semanticEntities, err := c.semantic.Resolve(ctx, detail.UnresolvedTokens, detail.ResolvedSet)
 			if err != nil {
 				slog.Warn("CRS-25: Semantic resolution failed, using structural-only",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 			} else if len(semanticEntities) > 0 {
 				ec.ResolvedEntities = append(ec.ResolvedEntities, semanticEntities...)
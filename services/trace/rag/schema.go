@@ -17,6 +17,8 @@ import (
 
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
 	"github.com/weaviate/weaviate/entities/models"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // CodeSymbolClassName is the Weaviate class name for code symbols.
@@ -192,7 +194,7 @@ func ResetCodeSymbolSchema(ctx context.Context, client *weaviate.Client) error {
 		// Weaviate returns an error if the class doesn't exist. That's fine — we're
 		// about to recreate it anyway.
 		slog.Debug("CRS-26n: Class delete returned error (may not exist yet)",
-			slog.String("error", err.Error()))
+			telemetry.SensitiveLogError("error", err))
 	}
 
 	// Recreate with correct schema and vectorizer.
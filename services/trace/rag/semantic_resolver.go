@@ -22,6 +22,8 @@ import (
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/graphql"
 	"github.com/weaviate/weaviate/entities/models"
 	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // availabilityTTL is how long a cached IsAvailable result is valid.
@@ -153,7 +155,7 @@ func (r *SemanticResolver) Resolve(ctx context.Context, unresolvedTokens []strin
 		if err != nil {
 			slog.Warn("CRS-26j: Failed to embed query token",
 				slog.String("token", token),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			continue
 		}
@@ -172,7 +174,7 @@ func (r *SemanticResolver) Resolve(ctx context.Context, unresolvedTokens []strin
 		if err != nil {
 			slog.Warn("CRS-26j: Semantic search failed for token",
 				slog.String("token", token),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			continue
 		}
@@ -181,7 +183,7 @@ func (r *SemanticResolver) Resolve(ctx context.Context, unresolvedTokens []strin
 			for _, e := range result.Errors {
 				slog.Warn("CRS-26j: Semantic search GraphQL error",
 					slog.String("token", token),
-					slog.String("error", e.Message),
+					telemetry.SensitiveLog("error", e.Message),
 				)
 			}
 			continue
@@ -19,6 +19,7 @@ import (
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/filters"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate/graphql"
@@ -167,7 +168,7 @@ func (s *SymbolStore) IndexSymbols(ctx context.Context, idx *index.SymbolIndex,
 		vectors, err := s.embedder.EmbedDocuments(ctx, texts)
 		if err != nil {
 			slog.Warn("CRS-26i: Failed to compute vectors, inserting without vectors",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.Int("symbol_count", len(objects)),
 			)
 		} else if len(vectors) != len(objects) {
@@ -213,8 +214,8 @@ func (s *SymbolStore) IndexSymbols(ctx context.Context, idx *index.SymbolIndex,
 				batchErrors++
 				for _, e := range r.Result.Errors.Error {
 					slog.Warn("CRS-26i: Symbol batch insert error",
-						slog.String("symbol", fmt.Sprintf("%v", r.Properties)),
-						slog.String("error", e.Message),
+						telemetry.SensitiveLog("symbol", fmt.Sprintf("%v", r.Properties)),
+						telemetry.SensitiveLog("error", e.Message),
 					)
 				}
 			}
@@ -254,7 +255,7 @@ func (s *SymbolStore) IndexSymbols(ctx context.Context, idx *index.SymbolIndex,
 func (s *SymbolStore) DeleteByFile(ctx context.Context, filePath string) error {
 	ctx, span := tracer.Start(ctx, "rag.SymbolStore.DeleteByFile")
 	defer span.End()
-	span.SetAttributes(attribute.String("rag.file_path", filePath))
+	span.SetAttributes(telemetry.SensitiveString("rag.file_path", filePath))
 
 	where := filters.Where().
 		WithOperator(filters.And).
@@ -440,7 +441,7 @@ func (s *SymbolStore) IndexFileSymbols(ctx context.Context, idx *index.SymbolInd
 		vectors, err := s.embedder.EmbedDocuments(ctx, texts)
 		if err != nil {
 			slog.Warn("CRS-26i: Failed to compute file symbol vectors, inserting without vectors",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 				slog.Int("symbol_count", len(objects)),
 			)
 		} else if len(vectors) != len(objects) {
@@ -478,8 +479,8 @@ func (s *SymbolStore) IndexFileSymbols(ctx context.Context, idx *index.SymbolInd
 				batchErrors++
 				for _, e := range r.Result.Errors.Error {
 					slog.Warn("CRS-26i: File symbol batch insert error",
-						slog.String("symbol", fmt.Sprintf("%v", r.Properties)),
-						slog.String("error", e.Message),
+						telemetry.SensitiveLog("symbol", fmt.Sprintf("%v", r.Properties)),
+						telemetry.SensitiveLog("error", e.Message),
 					)
 				}
 			}
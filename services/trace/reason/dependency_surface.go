@@ -0,0 +1,534 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package reason
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/dependencies"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+const (
+	// DefaultSurfaceDepth is the default number of hops from the package.
+	// Depth 1 is code that touches the package directly; depth 2 adds its
+	// callers.
+	DefaultSurfaceDepth = 2
+
+	// MaxSurfaceDepth caps the configurable traversal depth.
+	MaxSurfaceDepth = 5
+
+	// maxSurfaceSymbols bounds the symbols collected per request.
+	maxSurfaceSymbols = 2000
+
+	// maxSurfaceCallSites bounds the call sites listed per symbol.
+	maxSurfaceCallSites = 20
+)
+
+// DependencySurfaceAnalyzer finds the code exposed to a third-party package.
+//
+// Description:
+//
+//	Matches the external nodes that belong to a package, then walks the
+//	graph backwards: symbols with an edge into the package (imports,
+//	calls, references) are at depth 1, and their transitive callers at
+//	depth 2 and beyond. The result is what a vulnerable or abandoned
+//	dependency would touch.
+//
+// Thread Safety:
+//
+//	DependencySurfaceAnalyzer is safe for concurrent use.
+type DependencySurfaceAnalyzer struct {
+	graph *graph.Graph
+	index *index.SymbolIndex
+	crs   CRSRecorder
+}
+
+// NewDependencySurfaceAnalyzer creates a new DependencySurfaceAnalyzer.
+//
+// Inputs:
+//
+//	g - The code graph. Must be frozen.
+//	idx - The symbol index. May be nil.
+//
+// Outputs:
+//
+//	*DependencySurfaceAnalyzer - The configured analyzer.
+func NewDependencySurfaceAnalyzer(g *graph.Graph, idx *index.SymbolIndex) *DependencySurfaceAnalyzer {
+	return &DependencySurfaceAnalyzer{
+		graph: g,
+		index: idx,
+		crs:   &NopCRSRecorder{},
+	}
+}
+
+// SetCRS configures CRS recording for this analyzer.
+func (a *DependencySurfaceAnalyzer) SetCRS(recorder CRSRecorder) {
+	a.crs = recorder
+}
+
+// DependencySurfaceOptions configures dependency surface analysis.
+type DependencySurfaceOptions struct {
+	// MaxDepth is the number of hops to follow (1 = direct users only).
+	// Zero uses DefaultSurfaceDepth; values above MaxSurfaceDepth are capped.
+	MaxDepth int
+}
+
+// SurfaceCallSite is a place where code touches the package.
+type SurfaceCallSite struct {
+	// FilePath is the file containing the call or import.
+	FilePath string `json:"file_path"`
+
+	// Line is the 1-indexed line.
+	Line int `json:"line"`
+
+	// Target is the external symbol touched, e.g. "yaml.Unmarshal".
+	Target string `json:"target"`
+
+	// Via is the edge type (imports, calls, references, ...).
+	Via string `json:"via"`
+}
+
+// SurfaceSymbol is an internal symbol exposed to the package.
+type SurfaceSymbol struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+
+	// Depth is 1 for symbols touching the package, 2+ for their callers.
+	Depth int `json:"depth"`
+
+	// CallSites are the direct uses of the package. Depth 1 only.
+	CallSites []SurfaceCallSite `json:"call_sites,omitempty"`
+
+	// pkg is the group the symbol is listed under.
+	pkg string
+}
+
+// SurfaceGroup is the exposed symbols of one internal package at one depth.
+type SurfaceGroup struct {
+	// Package is the symbol's package, or its directory if unknown.
+	Package string `json:"package"`
+
+	// Depth is the hop count shared by Symbols.
+	Depth int `json:"depth"`
+
+	// Symbols are sorted by file, then line.
+	Symbols []SurfaceSymbol `json:"symbols"`
+}
+
+// DependencySurfaceResult is the code exposed to a third-party package.
+type DependencySurfaceResult struct {
+	// Package echoes the requested package.
+	Package string `json:"package"`
+
+	// Resolved lists the declared dependencies that matched, with versions.
+	// Empty if the project's manifests don't declare the package.
+	Resolved []graph.ExternalPackage `json:"resolved"`
+
+	// ExternalNodes are the graph's external nodes belonging to the package.
+	ExternalNodes []string `json:"external_nodes"`
+
+	// MaxDepth is the effective traversal depth.
+	MaxDepth int `json:"max_depth"`
+
+	// Groups are sorted by depth, then package.
+	Groups []SurfaceGroup `json:"groups"`
+
+	// DirectCount is the number of symbols at depth 1.
+	DirectCount int `json:"direct_count"`
+
+	// TransitiveCount is the number of symbols at depth 2 and beyond.
+	TransitiveCount int `json:"transitive_count"`
+
+	// Truncated is true when the symbol limit was reached.
+	Truncated bool `json:"truncated"`
+
+	// Confidence is how confident we are in the analysis (0.0-1.0).
+	Confidence float64 `json:"confidence"`
+
+	// Limitations lists what we couldn't analyze.
+	Limitations []string `json:"limitations"`
+}
+
+// AnalyzeDependencySurface lists the internal symbols that use a package.
+//
+// Description:
+//
+//	An external node belongs to pkg if its import path is pkg or below it
+//	("github.com/x/y/sub", "yaml.constructor"), or if it resolved to a
+//	declared dependency named pkg (so "PyYAML" finds imports of "yaml").
+//	Matching is on the graph alone, so it works for packages no manifest
+//	declares; their versions are then unknown.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	pkg - A module path, npm package, PyPI distribution or import path.
+//	opts - Traversal options.
+//
+// Outputs:
+//
+//	*DependencySurfaceResult - The exposed symbols, grouped.
+//	error - ErrInvalidInput for an empty package, ErrGraphNotReady if the
+//	  graph is not frozen, ErrContextCanceled on cancellation.
+//
+// Limitations:
+//
+//   - Code reaching the package through reflection, dynamic imports or
+//     dependency injection is missed.
+//   - Calls the parser could not attribute to an import (package-less
+//     external nodes) are not matched.
+func (a *DependencySurfaceAnalyzer) AnalyzeDependencySurface(
+	ctx context.Context,
+	pkg string,
+	opts DependencySurfaceOptions,
+) (*DependencySurfaceResult, error) {
+	if ctx == nil {
+		return nil, ErrInvalidInput
+	}
+
+	start := time.Now()
+	ctx, span := startDependencySurfaceSpan(ctx, pkg)
+	defer span.End()
+
+	pkg = strings.TrimSpace(pkg)
+	if pkg == "" || a.graph == nil {
+		return nil, ErrInvalidInput
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, ErrContextCanceled
+	}
+	if !a.graph.IsFrozen() {
+		return nil, ErrGraphNotReady
+	}
+
+	maxDepth := opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultSurfaceDepth
+	}
+	if maxDepth > MaxSurfaceDepth {
+		maxDepth = MaxSurfaceDepth
+	}
+
+	result := &DependencySurfaceResult{
+		Package:       pkg,
+		Resolved:      make([]graph.ExternalPackage, 0),
+		ExternalNodes: make([]string, 0),
+		MaxDepth:      maxDepth,
+		Groups:        make([]SurfaceGroup, 0),
+		Limitations:   make([]string, 0),
+	}
+
+	external := a.matchExternalNodes(pkg, result)
+	if len(external) == 0 {
+		result.Limitations = append(result.Limitations,
+			fmt.Sprintf("No imports of or calls into %q were found in the graph", pkg))
+	}
+
+	surface, truncated, err := a.walkSurface(ctx, external, maxDepth)
+	if err != nil {
+		setDependencySurfaceSpanResult(span, 0, err)
+		return nil, err
+	}
+	result.Truncated = truncated
+	if truncated {
+		result.Limitations = append(result.Limitations,
+			fmt.Sprintf("Symbol limit of %d reached - results are partial", maxSurfaceSymbols))
+	}
+	result.Groups = groupSurface(surface)
+	for _, sym := range surface {
+		if sym.Depth == 1 {
+			result.DirectCount++
+		} else {
+			result.TransitiveCount++
+		}
+	}
+	if len(result.Resolved) == 0 && len(external) > 0 {
+		result.Limitations = append(result.Limitations,
+			"Package is not declared in a manifest or lockfile - version unknown")
+	}
+	result.Confidence = a.surfaceConfidence(result)
+
+	dur := time.Since(start)
+	total := result.DirectCount + result.TransitiveCount
+	setDependencySurfaceSpanResult(span, total, nil)
+	recordDependencySurfaceMetrics(ctx, dur, nil)
+	a.crs.RecordToolStep(ctx, "analyze_dependency_surface", total, dur, nil)
+
+	return result, nil
+}
+
+// matchExternalNodes returns the external nodes belonging to pkg and
+// records them, with their resolved packages, on result.
+func (a *DependencySurfaceAnalyzer) matchExternalNodes(pkg string, result *DependencySurfaceResult) []*graph.Node {
+	var matched []*graph.Node
+	resolved := make(map[string]graph.ExternalPackage)
+	for _, node := range a.graph.GetNodesByKind(ast.SymbolKindExternal) {
+		if node.Symbol == nil {
+			continue
+		}
+		ext, hasExt := a.graph.ExternalPackageOf(node.ID)
+		if !belongsToPackage(node.Symbol.Package, ext, hasExt, pkg) {
+			continue
+		}
+		matched = append(matched, node)
+		result.ExternalNodes = append(result.ExternalNodes, node.ID)
+		if hasExt {
+			resolved[ext.Ecosystem+"\x00"+ext.Name] = ext
+		}
+	}
+	sort.Strings(result.ExternalNodes)
+	for _, ext := range resolved {
+		result.Resolved = append(result.Resolved, ext)
+	}
+	sort.Slice(result.Resolved, func(i, j int) bool {
+		if result.Resolved[i].Ecosystem != result.Resolved[j].Ecosystem {
+			return result.Resolved[i].Ecosystem < result.Resolved[j].Ecosystem
+		}
+		return result.Resolved[i].Name < result.Resolved[j].Name
+	})
+	return matched
+}
+
+// belongsToPackage reports whether an external node with the given
+// import path and resolved package belongs to pkg.
+//
+// Subpackages match by "/" ("github.com/x/y/sub", "@scope/pkg/x"). Python
+// submodules match by "." ("yaml.constructor"), unless the node resolved
+// to a non-Python package, so "lodash" doesn't match "lodash.merge".
+func belongsToPackage(importPath string, ext graph.ExternalPackage, hasExt bool, pkg string) bool {
+	if hasExt && strings.EqualFold(ext.Name, pkg) {
+		return true
+	}
+	if importPath == "" {
+		return false
+	}
+	if importPath == pkg || strings.HasPrefix(importPath, pkg+"/") {
+		return true
+	}
+	return strings.HasPrefix(importPath, pkg+".") && (!hasExt || ext.Ecosystem == dependencies.EcosystemPyPI)
+}
+
+// walkSurface collects the internal symbols reaching the external nodes,
+// breadth-first up to maxDepth hops.
+//
+// Returns true if the symbol limit was hit.
+func (a *DependencySurfaceAnalyzer) walkSurface(
+	ctx context.Context,
+	external []*graph.Node,
+	maxDepth int,
+) (map[string]*SurfaceSymbol, bool, error) {
+	surface := make(map[string]*SurfaceSymbol)
+	var frontier []string
+
+	for _, ext := range external {
+		for _, e := range ext.Incoming {
+			from, ok := a.graph.GetNode(e.FromID)
+			if !ok || from.Symbol == nil || from.Symbol.Kind == ast.SymbolKindExternal {
+				continue
+			}
+			sym, seen := surface[from.ID]
+			if !seen {
+				if len(surface) >= maxSurfaceSymbols {
+					return surface, true, nil
+				}
+				sym = toSurfaceSymbol(from.Symbol, 1)
+				surface[from.ID] = sym
+				frontier = append(frontier, from.ID)
+			}
+			if len(sym.CallSites) < maxSurfaceCallSites {
+				site := SurfaceCallSite{
+					FilePath: e.Location.FilePath,
+					Line:     e.Location.StartLine,
+					Target:   ext.Symbol.Name,
+					Via:      e.Type.String(),
+				}
+				if site.FilePath == "" {
+					site.FilePath = from.Symbol.FilePath
+				}
+				sym.CallSites = append(sym.CallSites, site)
+			}
+		}
+	}
+
+	for depth := 2; depth <= maxDepth && len(frontier) > 0; depth++ {
+		if err := ctx.Err(); err != nil {
+			return nil, false, ErrContextCanceled
+		}
+		var next []string
+		for _, id := range frontier {
+			node, ok := a.graph.GetNode(id)
+			if !ok {
+				continue
+			}
+			for _, e := range node.Incoming {
+				if e.Type != graph.EdgeTypeCalls {
+					continue
+				}
+				if _, seen := surface[e.FromID]; seen {
+					continue
+				}
+				from, ok := a.graph.GetNode(e.FromID)
+				if !ok || from.Symbol == nil || from.Symbol.Kind == ast.SymbolKindExternal {
+					continue
+				}
+				if len(surface) >= maxSurfaceSymbols {
+					return surface, true, nil
+				}
+				surface[from.ID] = toSurfaceSymbol(from.Symbol, depth)
+				next = append(next, from.ID)
+			}
+		}
+		frontier = next
+	}
+	return surface, false, nil
+}
+
+// toSurfaceSymbol converts a symbol to a SurfaceSymbol.
+func toSurfaceSymbol(sym *ast.Symbol, depth int) *SurfaceSymbol {
+	pkg := sym.Package
+	if pkg == "" {
+		pkg = filepath.ToSlash(filepath.Dir(sym.FilePath))
+	}
+	return &SurfaceSymbol{
+		ID:       sym.ID,
+		Name:     sym.Name,
+		Kind:     sym.Kind.String(),
+		FilePath: sym.FilePath,
+		Line:     sym.StartLine,
+		Depth:    depth,
+		pkg:      pkg,
+	}
+}
+
+// groupSurface groups surface symbols by depth, then package.
+func groupSurface(surface map[string]*SurfaceSymbol) []SurfaceGroup {
+	type groupKey struct {
+		depth int
+		pkg   string
+	}
+	byKey := make(map[groupKey]*SurfaceGroup)
+	for _, sym := range surface {
+		k := groupKey{sym.Depth, sym.pkg}
+		group, ok := byKey[k]
+		if !ok {
+			group = &SurfaceGroup{Package: sym.pkg, Depth: sym.Depth}
+			byKey[k] = group
+		}
+		group.Symbols = append(group.Symbols, *sym)
+	}
+
+	groups := make([]SurfaceGroup, 0, len(byKey))
+	for _, group := range byKey {
+		sort.Slice(group.Symbols, func(i, j int) bool {
+			a, b := group.Symbols[i], group.Symbols[j]
+			if a.FilePath != b.FilePath {
+				return a.FilePath < b.FilePath
+			}
+			if a.Line != b.Line {
+				return a.Line < b.Line
+			}
+			return a.ID < b.ID
+		})
+		groups = append(groups, *group)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Depth != groups[j].Depth {
+			return groups[i].Depth < groups[j].Depth
+		}
+		return groups[i].Package < groups[j].Package
+	})
+	return groups
+}
+
+// surfaceConfidence rates the analysis: lower when truncated or when no
+// external node matched, since the package may be used under another name.
+func (a *DependencySurfaceAnalyzer) surfaceConfidence(result *DependencySurfaceResult) float64 {
+	confidence := 0.9
+	if len(result.ExternalNodes) == 0 {
+		confidence = 0.5
+	}
+	if result.Truncated {
+		confidence -= 0.2
+	}
+	return confidence
+}
+
+// AdvisoryExposure is a vulnerable dependency and the code that touches it.
+type AdvisoryExposure struct {
+	// AdvisoryID is the OSV ID, e.g. "GHSA-xxxx-xxxx-xxxx".
+	AdvisoryID string `json:"advisory_id"`
+
+	// Aliases are other IDs of the advisory, e.g. CVE numbers.
+	Aliases []string `json:"aliases,omitempty"`
+
+	// Summary is the advisory's one-line summary.
+	Summary string `json:"summary,omitempty"`
+
+	// Dependency is the affected dependency at its resolved version.
+	Dependency dependencies.Dependency `json:"dependency"`
+
+	// Fixed are the versions that fix the advisory, if known.
+	Fixed []string `json:"fixed,omitempty"`
+
+	// Surface is the code touching the dependency directly.
+	Surface *DependencySurfaceResult `json:"surface"`
+}
+
+// AnalyzeAdvisoryExposure flags the code touching vulnerable versions.
+//
+// Description:
+//
+//	For each finding, lists the symbols with a direct edge into the
+//	affected package (depth 1), with their call sites. Findings are the
+//	output of dependencies.Set.Match, so only dependencies with a
+//	resolved version are considered.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	findings - Affected dependencies.
+//
+// Outputs:
+//
+//	[]AdvisoryExposure - One per finding, in input order.
+//	error - As AnalyzeDependencySurface.
+func (a *DependencySurfaceAnalyzer) AnalyzeAdvisoryExposure(
+	ctx context.Context,
+	findings []dependencies.Finding,
+) ([]AdvisoryExposure, error) {
+	out := make([]AdvisoryExposure, 0, len(findings))
+	for _, f := range findings {
+		surface, err := a.AnalyzeDependencySurface(ctx, f.Dependency.Name, DependencySurfaceOptions{MaxDepth: 1})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, AdvisoryExposure{
+			AdvisoryID: f.Advisory.ID,
+			Aliases:    f.Advisory.Aliases,
+			Summary:    f.Advisory.Summary,
+			Dependency: f.Dependency,
+			Fixed:      f.Fixed,
+			Surface:    surface,
+		})
+	}
+	return out, nil
+}
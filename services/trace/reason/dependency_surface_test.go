@@ -0,0 +1,196 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package reason
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/dependencies"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// setupSurfaceTestGraph builds:
+//
+//	config/load.go   package config imports gopkg.in/yaml.v3
+//	config/load.go   Load → yaml.Unmarshal (gopkg.in/yaml.v3)
+//	cmd/main.go      main → Load
+//	tools/conv.py    convert → yaml.safe_load (yaml)
+//	web/app.js       render → lodash.merge (unresolved)
+//
+// gopkg.in/yaml.v3 v3.0.0 and PyYAML 5.3 are declared dependencies.
+func setupSurfaceTestGraph() *graph.Graph {
+	g := graph.NewGraph("/test/project")
+
+	mk := func(id, name string, kind ast.SymbolKind, file string, line int, lang, pkg string) *ast.Symbol {
+		return &ast.Symbol{ID: id, Name: name, Kind: kind, FilePath: file, StartLine: line, EndLine: line + 5, Language: lang, Package: pkg}
+	}
+	ext := func(pkg, name string) *ast.Symbol {
+		return &ast.Symbol{ID: "external:" + pkg + ":" + name, Name: name, Kind: ast.SymbolKindExternal, Package: pkg, Language: "external"}
+	}
+
+	pkgConfig := mk("config/load.go:1:config", "config", ast.SymbolKindPackage, "config/load.go", 1, "go", "config")
+	load := mk("config/load.go:10:Load", "Load", ast.SymbolKindFunction, "config/load.go", 10, "go", "config")
+	mainFn := mk("cmd/main.go:5:main", "main", ast.SymbolKindFunction, "cmd/main.go", 5, "go", "main")
+	convert := mk("tools/conv.py:3:convert", "convert", ast.SymbolKindFunction, "tools/conv.py", 3, "python", "")
+	render := mk("web/app.js:7:render", "render", ast.SymbolKindFunction, "web/app.js", 7, "javascript", "")
+	yamlImport := ext("gopkg.in/yaml.v3", "gopkg.in/yaml.v3")
+	yamlCall := ext("gopkg.in/yaml.v3", "yaml.Unmarshal")
+	pyYAML := ext("yaml", "yaml.safe_load")
+	lodashMerge := ext("lodash.merge", "merge")
+
+	for _, sym := range []*ast.Symbol{pkgConfig, load, mainFn, convert, render, yamlImport, yamlCall, pyYAML, lodashMerge} {
+		g.AddNode(sym)
+	}
+	edge := func(from, to *ast.Symbol, et graph.EdgeType, line int) {
+		g.AddEdge(from.ID, to.ID, et, ast.Location{FilePath: from.FilePath, StartLine: line})
+	}
+	edge(pkgConfig, yamlImport, graph.EdgeTypeImports, 4)
+	edge(load, yamlCall, graph.EdgeTypeCalls, 12)
+	edge(mainFn, load, graph.EdgeTypeCalls, 6)
+	edge(convert, pyYAML, graph.EdgeTypeCalls, 4)
+	edge(render, lodashMerge, graph.EdgeTypeCalls, 8)
+	g.Freeze()
+
+	deps := dependencies.NewSet(
+		dependencies.Dependency{Ecosystem: dependencies.EcosystemGo, Name: "gopkg.in/yaml.v3", Version: "v3.0.0", Direct: true},
+		dependencies.Dependency{Ecosystem: dependencies.EcosystemPyPI, Name: "PyYAML", Version: "5.3", Direct: true},
+	)
+	graph.RecordExternalPackages(g, func(importPath, language string) (graph.ExternalPackage, bool) {
+		d, ok := deps.Resolve(importPath, language)
+		return graph.ExternalPackage{Ecosystem: d.Ecosystem, Name: d.Name, Version: d.Version, Direct: d.Direct}, ok
+	})
+	return g
+}
+
+func TestAnalyzeDependencySurface_GroupsByDepthAndPackage(t *testing.T) {
+	g := setupSurfaceTestGraph()
+	a := NewDependencySurfaceAnalyzer(g, nil)
+
+	result, err := a.AnalyzeDependencySurface(context.Background(), "gopkg.in/yaml.v3", DependencySurfaceOptions{})
+	if err != nil {
+		t.Fatalf("AnalyzeDependencySurface: %v", err)
+	}
+	if result.MaxDepth != DefaultSurfaceDepth {
+		t.Errorf("MaxDepth = %d, want %d", result.MaxDepth, DefaultSurfaceDepth)
+	}
+	if len(result.Resolved) != 1 || result.Resolved[0].Version != "v3.0.0" {
+		t.Errorf("Resolved = %+v", result.Resolved)
+	}
+	if result.DirectCount != 2 || result.TransitiveCount != 1 {
+		t.Fatalf("counts = %d direct, %d transitive; groups %+v", result.DirectCount, result.TransitiveCount, result.Groups)
+	}
+	if len(result.Groups) != 2 {
+		t.Fatalf("groups = %+v", result.Groups)
+	}
+	direct := result.Groups[0]
+	if direct.Depth != 1 || direct.Package != "config" || len(direct.Symbols) != 2 {
+		t.Fatalf("direct group = %+v", direct)
+	}
+	loadSym := direct.Symbols[1]
+	if loadSym.Name != "Load" || len(loadSym.CallSites) != 1 {
+		t.Fatalf("Load = %+v", loadSym)
+	}
+	if site := loadSym.CallSites[0]; site.Line != 12 || site.Target != "yaml.Unmarshal" || site.Via != "calls" {
+		t.Errorf("call site = %+v", site)
+	}
+	if transitive := result.Groups[1]; transitive.Depth != 2 || transitive.Package != "main" || transitive.Symbols[0].Name != "main" {
+		t.Errorf("transitive group = %+v", transitive)
+	}
+}
+
+func TestAnalyzeDependencySurface_DistributionName(t *testing.T) {
+	g := setupSurfaceTestGraph()
+	a := NewDependencySurfaceAnalyzer(g, nil)
+
+	result, err := a.AnalyzeDependencySurface(context.Background(), "PyYAML", DependencySurfaceOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("AnalyzeDependencySurface: %v", err)
+	}
+	if result.DirectCount != 1 || result.Groups[0].Symbols[0].Name != "convert" {
+		t.Errorf("groups = %+v", result.Groups)
+	}
+	if result.Groups[0].Package != "tools" {
+		t.Errorf("package = %q, want directory fallback %q", result.Groups[0].Package, "tools")
+	}
+}
+
+func TestAnalyzeDependencySurface_UnresolvedPackage(t *testing.T) {
+	g := setupSurfaceTestGraph()
+	a := NewDependencySurfaceAnalyzer(g, nil)
+
+	// lodash.merge is unresolved, so the "." rule applies and it matches
+	// lodash; the version is unknown.
+	result, err := a.AnalyzeDependencySurface(context.Background(), "lodash", DependencySurfaceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.DirectCount != 1 || len(result.Resolved) != 0 || len(result.Limitations) == 0 {
+		t.Errorf("result = %+v", result)
+	}
+
+	result, err = a.AnalyzeDependencySurface(context.Background(), "left-pad", DependencySurfaceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Groups) != 0 || result.Confidence >= 0.9 {
+		t.Errorf("unused package: groups %+v confidence %v", result.Groups, result.Confidence)
+	}
+}
+
+func TestAnalyzeDependencySurface_Errors(t *testing.T) {
+	g := setupSurfaceTestGraph()
+	a := NewDependencySurfaceAnalyzer(g, nil)
+
+	if _, err := a.AnalyzeDependencySurface(context.Background(), " ", DependencySurfaceOptions{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("empty package: err = %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := a.AnalyzeDependencySurface(ctx, "yaml", DependencySurfaceOptions{}); !errors.Is(err, ErrContextCanceled) {
+		t.Errorf("canceled: err = %v", err)
+	}
+	unfrozen := NewDependencySurfaceAnalyzer(graph.NewGraph("/x"), nil)
+	if _, err := unfrozen.AnalyzeDependencySurface(context.Background(), "yaml", DependencySurfaceOptions{}); !errors.Is(err, ErrGraphNotReady) {
+		t.Errorf("unfrozen: err = %v", err)
+	}
+}
+
+func TestAnalyzeAdvisoryExposure(t *testing.T) {
+	g := setupSurfaceTestGraph()
+	a := NewDependencySurfaceAnalyzer(g, nil)
+
+	deps := dependencies.NewSet(dependencies.Dependency{Ecosystem: dependencies.EcosystemPyPI, Name: "PyYAML", Version: "5.3"})
+	advs, err := dependencies.ParseAdvisories([]byte(`{
+		"id": "GHSA-8q59-q68h-6hv4", "aliases": ["CVE-2020-14343"],
+		"affected": [{"package": {"ecosystem": "PyPI", "name": "pyyaml"},
+			"ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"fixed": "5.4"}]}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exposures, err := a.AnalyzeAdvisoryExposure(context.Background(), deps.Match(advs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exposures) != 1 {
+		t.Fatalf("exposures = %+v", exposures)
+	}
+	e := exposures[0]
+	if e.AdvisoryID != "GHSA-8q59-q68h-6hv4" || len(e.Fixed) != 1 || e.Fixed[0] != "5.4" {
+		t.Errorf("exposure = %+v", e)
+	}
+	if e.Surface.DirectCount != 1 || e.Surface.Groups[0].Symbols[0].CallSites[0].FilePath != "tools/conv.py" {
+		t.Errorf("surface = %+v", e.Surface)
+	}
+}
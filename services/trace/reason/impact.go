@@ -0,0 +1,670 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package reason
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+const (
+	// DefaultImpactDepth is the default number of dependent hops followed.
+	DefaultImpactDepth = 3
+
+	// MaxImpactDepth caps the configurable traversal depth.
+	MaxImpactDepth = 10
+
+	// maxImpactDependents bounds the total dependents collected per request
+	// so a change to a widely used utility cannot produce an unbounded response.
+	maxImpactDependents = 5000
+)
+
+// Impact relations describe how a dependent was reached.
+const (
+	ImpactViaCalls      = "calls"
+	ImpactViaReferences = "references"
+	ImpactViaImplements = "implements"
+)
+
+// FileImpactAnalyzer computes the symbols affected by a set of changed files.
+//
+// Description:
+//
+//	For each changed file, FileImpactAnalyzer walks the code graph backwards
+//	from the symbols the file defines: callers (CALLS) and referrers
+//	(REFERENCES) are dependents, and interface implementation is treated as
+//	bidirectional (changing an interface impacts its implementers and the
+//	callers of the interface; changing an implementation impacts callers that
+//	reach it through the interface). Test functions and test files among the
+//	dependents are flagged so the result can drive test selection directly.
+//
+// Thread Safety:
+//
+//	FileImpactAnalyzer is safe for concurrent use.
+type FileImpactAnalyzer struct {
+	graph *graph.Graph
+	index *index.SymbolIndex
+	crs   CRSRecorder
+}
+
+// NewFileImpactAnalyzer creates a new FileImpactAnalyzer.
+//
+// Inputs:
+//
+//	g - The code graph. Must be frozen.
+//	idx - The symbol index.
+//
+// Outputs:
+//
+//	*FileImpactAnalyzer - The configured analyzer.
+func NewFileImpactAnalyzer(g *graph.Graph, idx *index.SymbolIndex) *FileImpactAnalyzer {
+	return &FileImpactAnalyzer{
+		graph: g,
+		index: idx,
+		crs:   &NopCRSRecorder{},
+	}
+}
+
+// SetCRS configures CRS recording for this analyzer.
+func (a *FileImpactAnalyzer) SetCRS(recorder CRSRecorder) {
+	a.crs = recorder
+}
+
+// FileImpactOptions configures file impact analysis.
+type FileImpactOptions struct {
+	// MaxDepth is the number of dependent hops to follow (1 = direct only).
+	// Zero uses DefaultImpactDepth; values above MaxImpactDepth are capped.
+	MaxDepth int
+}
+
+// ImpactedSymbol is a symbol defined in, or depending on, a changed file.
+type ImpactedSymbol struct {
+	// ID is the symbol ID.
+	ID string `json:"id"`
+
+	// Name is the symbol name.
+	Name string `json:"name"`
+
+	// Kind is the symbol kind (function, method, struct, ...).
+	Kind string `json:"kind"`
+
+	// FilePath is where the symbol is defined.
+	FilePath string `json:"file_path"`
+
+	// Depth is the number of hops from the changed file (0 for defined symbols).
+	Depth int `json:"depth"`
+
+	// Via is the relation of the hop that reached this symbol
+	// (calls, references, implements). Empty for defined symbols.
+	Via string `json:"via,omitempty"`
+
+	// IsTest is true for test functions and symbols in test files.
+	IsTest bool `json:"is_test"`
+}
+
+// FileImpact is the impact of a single changed file.
+type FileImpact struct {
+	// FilePath is the changed file as given in the request.
+	FilePath string `json:"file_path"`
+
+	// Indexed is false when the file has no symbols in the index.
+	Indexed bool `json:"indexed"`
+
+	// DefinedSymbols are the symbols defined in the file.
+	DefinedSymbols []ImpactedSymbol `json:"defined_symbols"`
+
+	// DirectDependents are symbols one hop away.
+	DirectDependents []ImpactedSymbol `json:"direct_dependents"`
+
+	// TransitiveDependents are symbols two or more hops away, up to MaxDepth.
+	TransitiveDependents []ImpactedSymbol `json:"transitive_dependents"`
+
+	// DirectCount is len(DirectDependents).
+	DirectCount int `json:"direct_count"`
+
+	// TransitiveCount is len(TransitiveDependents).
+	TransitiveCount int `json:"transitive_count"`
+}
+
+// FileImpactResult is the result of analyzing a set of changed files.
+//
+// Dependents are de-duplicated across files: each symbol is listed once,
+// under the file that reaches it in the fewest hops (ties go to the file
+// listed first in the request). Symbols defined in any changed file are
+// never listed as dependents.
+type FileImpactResult struct {
+	// ChangedFiles echoes the analyzed files in request order.
+	ChangedFiles []string `json:"changed_files"`
+
+	// MaxDepth is the effective traversal depth.
+	MaxDepth int `json:"max_depth"`
+
+	// Files holds the per-file impact, in request order.
+	Files []FileImpact `json:"files"`
+
+	// TotalDirectDependents is the number of unique direct dependents.
+	TotalDirectDependents int `json:"total_direct_dependents"`
+
+	// TotalTransitiveDependents is the number of unique transitive dependents.
+	TotalTransitiveDependents int `json:"total_transitive_dependents"`
+
+	// AffectedTests lists every test symbol that is defined in a changed
+	// file or depends on one, sorted by file then name.
+	AffectedTests []ImpactedSymbol `json:"affected_tests"`
+
+	// AffectedTestFiles lists the unique files containing AffectedTests.
+	AffectedTestFiles []string `json:"affected_test_files"`
+
+	// ByOwner groups the changed files and every affected symbol (defined
+	// and dependent) by CODEOWNERS owner. Empty when the project has no
+	// CODEOWNERS file.
+	ByOwner []graph.OwnerGroup `json:"by_owner"`
+
+	// UnownedFiles lists changed files and files of affected symbols that
+	// no CODEOWNERS rule assigns. Empty when the project has no CODEOWNERS
+	// file.
+	UnownedFiles []string `json:"unowned_files"`
+
+	// Truncated is true when the dependent limit was reached.
+	Truncated bool `json:"truncated"`
+
+	// Confidence is how confident we are in the analysis (0.0-1.0).
+	Confidence float64 `json:"confidence"`
+
+	// Limitations lists what we couldn't analyze.
+	Limitations []string `json:"limitations"`
+}
+
+// impactHop is a dependent discovered during traversal.
+type impactHop struct {
+	id  string
+	via string
+}
+
+// impactReach records the best (shallowest) way a changed file reaches a dependent.
+type impactReach struct {
+	fileIdx int
+	depth   int
+	via     string
+}
+
+// AnalyzeFileImpact lists the symbols affected by changes to the given files.
+//
+// Description:
+//
+//	Collects the symbols defined in each file, then follows dependents
+//	breadth-first up to opts.MaxDepth hops. Results are de-duplicated across
+//	files and test symbols are flagged and aggregated.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	filePaths - Changed file paths, relative to the project root. Must be non-empty.
+//	opts - Traversal options.
+//
+// Outputs:
+//
+//	*FileImpactResult - Per-file impact and aggregated tests.
+//	error - ErrInvalidInput for empty input, ErrGraphNotReady if the graph
+//	  is not frozen, ErrContextCanceled on cancellation.
+//
+// Example:
+//
+//	result, err := analyzer.AnalyzeFileImpact(ctx,
+//	    []string{"pkg/auth/token.go"}, FileImpactOptions{MaxDepth: 2})
+//	for _, f := range result.AffectedTestFiles {
+//	    fmt.Println("run:", f)
+//	}
+//
+// Limitations:
+//
+//   - Static analysis only: reflection, dependency injection and dynamic
+//     dispatch outside interface implementation are not followed.
+//   - Tests that exercise code without calling it (e.g., via HTTP) are missed.
+func (a *FileImpactAnalyzer) AnalyzeFileImpact(
+	ctx context.Context,
+	filePaths []string,
+	opts FileImpactOptions,
+) (*FileImpactResult, error) {
+	if ctx == nil {
+		return nil, ErrInvalidInput
+	}
+
+	start := time.Now()
+	ctx, span := startImpactSpan(ctx, len(filePaths))
+	defer span.End()
+
+	if err := ctx.Err(); err != nil {
+		return nil, ErrContextCanceled
+	}
+	if len(filePaths) == 0 || a.index == nil {
+		return nil, ErrInvalidInput
+	}
+	if a.graph != nil && !a.graph.IsFrozen() {
+		return nil, ErrGraphNotReady
+	}
+
+	maxDepth := opts.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultImpactDepth
+	}
+	if maxDepth > MaxImpactDepth {
+		maxDepth = MaxImpactDepth
+	}
+
+	result := &FileImpactResult{
+		ChangedFiles:      make([]string, 0, len(filePaths)),
+		MaxDepth:          maxDepth,
+		Files:             make([]FileImpact, 0, len(filePaths)),
+		AffectedTests:     make([]ImpactedSymbol, 0),
+		AffectedTestFiles: make([]string, 0),
+		ByOwner:           make([]graph.OwnerGroup, 0),
+		UnownedFiles:      make([]string, 0),
+		Limitations:       make([]string, 0),
+	}
+
+	// Collect defined symbols for every file first so dependents can exclude
+	// symbols that are themselves being changed.
+	defined := make(map[string]bool)
+	seeds := make([][]*ast.Symbol, 0, len(filePaths))
+	var unindexed []string
+	for _, path := range filePaths {
+		symbols := a.symbolsInFile(path)
+		seeds = append(seeds, symbols)
+		for _, sym := range symbols {
+			defined[sym.ID] = true
+		}
+
+		fi := FileImpact{
+			FilePath:             path,
+			Indexed:              len(symbols) > 0,
+			DefinedSymbols:       make([]ImpactedSymbol, 0, len(symbols)),
+			DirectDependents:     make([]ImpactedSymbol, 0),
+			TransitiveDependents: make([]ImpactedSymbol, 0),
+		}
+		for _, sym := range symbols {
+			fi.DefinedSymbols = append(fi.DefinedSymbols, toImpactedSymbol(sym, 0, ""))
+		}
+		sortImpactedSymbols(fi.DefinedSymbols)
+		if !fi.Indexed {
+			unindexed = append(unindexed, path)
+		}
+
+		result.ChangedFiles = append(result.ChangedFiles, path)
+		result.Files = append(result.Files, fi)
+	}
+
+	if len(unindexed) > 0 {
+		result.Limitations = append(result.Limitations,
+			fmt.Sprintf("No indexed symbols for: %s", strings.Join(unindexed, ", ")))
+	}
+
+	if a.graph == nil {
+		result.Limitations = append(result.Limitations,
+			"Graph not available - dependents not analyzed")
+	} else {
+		parents := a.buildParentMap()
+		best := make(map[string]impactReach)
+
+		for fileIdx, symbols := range seeds {
+			if err := ctx.Err(); err != nil {
+				return nil, ErrContextCanceled
+			}
+			complete, err := a.collectDependents(ctx, fileIdx, symbols, maxDepth, defined, parents, best)
+			if err != nil {
+				return nil, ErrContextCanceled
+			}
+			if !complete {
+				result.Truncated = true
+			}
+		}
+
+		for id, reach := range best {
+			sym := a.symbolFor(id)
+			if sym == nil {
+				continue
+			}
+			impacted := toImpactedSymbol(sym, reach.depth, reach.via)
+			fi := &result.Files[reach.fileIdx]
+			if reach.depth == 1 {
+				fi.DirectDependents = append(fi.DirectDependents, impacted)
+				result.TotalDirectDependents++
+			} else {
+				fi.TransitiveDependents = append(fi.TransitiveDependents, impacted)
+				result.TotalTransitiveDependents++
+			}
+		}
+
+		if result.Truncated {
+			result.Limitations = append(result.Limitations,
+				fmt.Sprintf("Dependent limit of %d reached - results are partial", maxImpactDependents))
+		}
+	}
+
+	testFiles := make(map[string]bool)
+	for i := range result.Files {
+		fi := &result.Files[i]
+		sortImpactedSymbols(fi.DirectDependents)
+		sortImpactedSymbols(fi.TransitiveDependents)
+		fi.DirectCount = len(fi.DirectDependents)
+		fi.TransitiveCount = len(fi.TransitiveDependents)
+
+		for _, group := range [][]ImpactedSymbol{fi.DefinedSymbols, fi.DirectDependents, fi.TransitiveDependents} {
+			for _, sym := range group {
+				if !sym.IsTest {
+					continue
+				}
+				result.AffectedTests = append(result.AffectedTests, sym)
+				if !testFiles[sym.FilePath] {
+					testFiles[sym.FilePath] = true
+					result.AffectedTestFiles = append(result.AffectedTestFiles, sym.FilePath)
+				}
+			}
+		}
+	}
+	sort.Slice(result.AffectedTests, func(i, j int) bool {
+		if result.AffectedTests[i].FilePath != result.AffectedTests[j].FilePath {
+			return result.AffectedTests[i].FilePath < result.AffectedTests[j].FilePath
+		}
+		return result.AffectedTests[i].Name < result.AffectedTests[j].Name
+	})
+	sort.Strings(result.AffectedTestFiles)
+
+	if a.graph.HasOwners() {
+		symbolFiles := make(map[string]string)
+		for _, fi := range result.Files {
+			for _, group := range [][]ImpactedSymbol{fi.DefinedSymbols, fi.DirectDependents, fi.TransitiveDependents} {
+				for _, sym := range group {
+					symbolFiles[sym.ID] = sym.FilePath
+				}
+			}
+		}
+		result.ByOwner, result.UnownedFiles = a.graph.GroupByOwner(result.ChangedFiles, symbolFiles)
+	}
+
+	result.Confidence = a.calculateImpactConfidence(result)
+
+	dur := time.Since(start)
+	dependents := result.TotalDirectDependents + result.TotalTransitiveDependents
+	setImpactSpanResult(span, dependents, len(result.AffectedTests), nil)
+	recordImpactMetrics(ctx, dur, nil)
+	a.crs.RecordToolStep(ctx, "analyze_impact", dependents, dur, nil)
+
+	return result, nil
+}
+
+// collectDependents runs a breadth-first walk from one file's symbols and
+// merges the reached dependents into best.
+//
+// Returns false if the global dependent limit was hit, and ctx's error if
+// it was cancelled during the walk, in which case best is partial.
+func (a *FileImpactAnalyzer) collectDependents(
+	ctx context.Context,
+	fileIdx int,
+	seeds []*ast.Symbol,
+	maxDepth int,
+	defined map[string]bool,
+	parents map[string]string,
+	best map[string]impactReach,
+) (bool, error) {
+	visited := make(map[string]bool, len(seeds))
+	frontier := make([]string, 0, len(seeds))
+	for _, sym := range seeds {
+		if !visited[sym.ID] {
+			visited[sym.ID] = true
+			frontier = append(frontier, sym.ID)
+		}
+	}
+
+	for depth := 1; depth <= maxDepth && len(frontier) > 0; depth++ {
+		if err := ctx.Err(); err != nil {
+			return true, err
+		}
+
+		var next []string
+		for _, id := range frontier {
+			for _, hop := range a.dependentsOf(id, parents) {
+				if visited[hop.id] {
+					continue
+				}
+				visited[hop.id] = true
+				next = append(next, hop.id)
+
+				if defined[hop.id] {
+					continue
+				}
+				prev, seen := best[hop.id]
+				if !seen {
+					if len(best) >= maxImpactDependents {
+						return false, nil
+					}
+					best[hop.id] = impactReach{fileIdx: fileIdx, depth: depth, via: hop.via}
+				} else if depth < prev.depth {
+					best[hop.id] = impactReach{fileIdx: fileIdx, depth: depth, via: hop.via}
+				}
+			}
+		}
+		frontier = next
+	}
+	if err := ctx.Err(); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// dependentsOf returns the symbols that depend on id.
+//
+// Description:
+//
+//	Callers and referrers come from incoming CALLS/REFERENCES edges.
+//	Implementation is followed in both directions at the type level
+//	(interface ↔ implementer) and at the method level (interface method ↔
+//	same-named method on each implementer), so a change on either side
+//	reaches callers that go through the other.
+func (a *FileImpactAnalyzer) dependentsOf(id string, parents map[string]string) []impactHop {
+	node, ok := a.graph.GetNode(id)
+	if !ok || node == nil {
+		return nil
+	}
+
+	var hops []impactHop
+	for _, edge := range node.Incoming {
+		switch edge.Type {
+		case graph.EdgeTypeCalls:
+			hops = append(hops, impactHop{id: edge.FromID, via: ImpactViaCalls})
+		case graph.EdgeTypeReferences:
+			hops = append(hops, impactHop{id: edge.FromID, via: ImpactViaReferences})
+		case graph.EdgeTypeImplements:
+			// Interface changed → implementers are impacted.
+			hops = append(hops, impactHop{id: edge.FromID, via: ImpactViaImplements})
+		}
+	}
+	for _, edge := range node.Outgoing {
+		if edge.Type == graph.EdgeTypeImplements {
+			// Implementation changed → the interface (and its callers) are impacted.
+			hops = append(hops, impactHop{id: edge.ToID, via: ImpactViaImplements})
+		}
+	}
+
+	if node.Symbol != nil && (node.Symbol.Kind == ast.SymbolKindMethod || node.Symbol.Kind == ast.SymbolKindFunction) {
+		for _, counterpart := range a.implementationCounterparts(node, parents) {
+			hops = append(hops, impactHop{id: counterpart, via: ImpactViaImplements})
+		}
+	}
+
+	return hops
+}
+
+// implementationCounterparts maps a method to its same-named methods across
+// an implementation relationship: interface method → implementer methods,
+// implementer method → interface methods.
+func (a *FileImpactAnalyzer) implementationCounterparts(method *graph.Node, parents map[string]string) []string {
+	name := method.Symbol.Name
+	owners := a.ownersOf(method, parents)
+
+	var counterparts []string
+	for _, ownerID := range owners {
+		owner, ok := a.graph.GetNode(ownerID)
+		if !ok || owner == nil {
+			continue
+		}
+		for _, edge := range owner.Incoming {
+			if edge.Type == graph.EdgeTypeImplements {
+				counterparts = append(counterparts, a.methodsNamed(edge.FromID, name)...)
+			}
+		}
+		for _, edge := range owner.Outgoing {
+			if edge.Type == graph.EdgeTypeImplements {
+				counterparts = append(counterparts, a.methodsNamed(edge.ToID, name)...)
+			}
+		}
+	}
+	return counterparts
+}
+
+// ownersOf returns the type(s) a method belongs to: the receiver type for Go
+// methods (outgoing RECEIVES) or the declaring interface/class.
+func (a *FileImpactAnalyzer) ownersOf(method *graph.Node, parents map[string]string) []string {
+	var owners []string
+	for _, edge := range method.Outgoing {
+		if edge.Type == graph.EdgeTypeReceives {
+			owners = append(owners, edge.ToID)
+		}
+	}
+	if parentID, ok := parents[method.ID]; ok {
+		owners = append(owners, parentID)
+	}
+	return owners
+}
+
+// methodsNamed returns the IDs of methods named name that belong to typeID,
+// either as declared children or via RECEIVES edges.
+func (a *FileImpactAnalyzer) methodsNamed(typeID, name string) []string {
+	typeNode, ok := a.graph.GetNode(typeID)
+	if !ok || typeNode == nil {
+		return nil
+	}
+
+	var ids []string
+	if typeNode.Symbol != nil {
+		for _, child := range typeNode.Symbol.Children {
+			if child != nil && child.Name == name {
+				ids = append(ids, child.ID)
+			}
+		}
+	}
+	for _, edge := range typeNode.Incoming {
+		if edge.Type != graph.EdgeTypeReceives {
+			continue
+		}
+		if from, ok := a.graph.GetNode(edge.FromID); ok && from.Symbol != nil && from.Symbol.Name == name {
+			ids = append(ids, edge.FromID)
+		}
+	}
+	return ids
+}
+
+// buildParentMap maps member symbol IDs to their declaring interface or class.
+func (a *FileImpactAnalyzer) buildParentMap() map[string]string {
+	parents := make(map[string]string)
+	for _, kind := range []ast.SymbolKind{ast.SymbolKindInterface, ast.SymbolKindClass, ast.SymbolKindStruct} {
+		for _, owner := range a.index.GetByKind(kind) {
+			for _, child := range owner.Children {
+				if child != nil {
+					parents[child.ID] = owner.ID
+				}
+			}
+		}
+	}
+	return parents
+}
+
+// symbolsInFile returns the indexed symbols defined in path, tolerating
+// "./" prefixes and unclean paths.
+func (a *FileImpactAnalyzer) symbolsInFile(path string) []*ast.Symbol {
+	if symbols := a.index.GetByFile(path); len(symbols) > 0 {
+		return symbols
+	}
+	return a.index.GetByFile(normalizePath(path))
+}
+
+// symbolFor resolves a symbol ID via the index, falling back to the graph node.
+func (a *FileImpactAnalyzer) symbolFor(id string) *ast.Symbol {
+	if sym, ok := a.index.GetByID(id); ok && sym != nil {
+		return sym
+	}
+	if node, ok := a.graph.GetNode(id); ok && node != nil {
+		return node.Symbol
+	}
+	return nil
+}
+
+// calculateImpactConfidence calculates confidence for the impact analysis.
+func (a *FileImpactAnalyzer) calculateImpactConfidence(result *FileImpactResult) float64 {
+	cal := NewConfidenceCalibration(0.85)
+
+	for range result.Limitations {
+		cal.Apply(ConfidenceAdjustment{
+			Reason:     "impact limitation",
+			Multiplier: 0.85,
+		})
+	}
+
+	cal.Apply(AdjustmentStaticAnalysisOnly)
+
+	return cal.FinalScore
+}
+
+// toImpactedSymbol converts a symbol to its impact representation.
+func toImpactedSymbol(sym *ast.Symbol, depth int, via string) ImpactedSymbol {
+	return ImpactedSymbol{
+		ID:       sym.ID,
+		Name:     sym.Name,
+		Kind:     sym.Kind.String(),
+		FilePath: sym.FilePath,
+		Depth:    depth,
+		Via:      via,
+		IsTest:   isTestFunction(sym) || isTestPath(sym.FilePath),
+	}
+}
+
+// isTestPath extends isTestFile with Python's test_*.py / conftest.py conventions.
+func isTestPath(filePath string) bool {
+	if isTestFile(filePath) {
+		return true
+	}
+	base := filepath.Base(filePath)
+	return strings.HasSuffix(base, ".py") && (strings.HasPrefix(base, "test_") || base == "conftest.py")
+}
+
+// sortImpactedSymbols orders symbols by depth, file, name, then ID.
+func sortImpactedSymbols(symbols []ImpactedSymbol) {
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].Depth != symbols[j].Depth {
+			return symbols[i].Depth < symbols[j].Depth
+		}
+		if symbols[i].FilePath != symbols[j].FilePath {
+			return symbols[i].FilePath < symbols[j].FilePath
+		}
+		if symbols[i].Name != symbols[j].Name {
+			return symbols[i].Name < symbols[j].Name
+		}
+		return symbols[i].ID < symbols[j].ID
+	})
+}
@@ -0,0 +1,283 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package reason
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+)
+
+// setupImpactTestGraph builds:
+//
+//	store/repo.go        interface Repository { Save }
+//	store/mem.go         struct MemRepo, method (MemRepo) Save   — implements Repository
+//	service/user.go      CreateUser → Repository.Save
+//	api/handler.go       HandleCreate → CreateUser
+//	api/handler_test.go  TestHandleCreate → HandleCreate
+//	tests/test_user.py   test_create_user → CreateUser
+func setupImpactTestGraph() (*graph.Graph, *index.SymbolIndex) {
+	g := graph.NewGraph("/test/project")
+	idx := index.NewSymbolIndex()
+
+	mk := func(id, name string, kind ast.SymbolKind, file string, line int, lang string) *ast.Symbol {
+		return &ast.Symbol{
+			ID:        id,
+			Name:      name,
+			Kind:      kind,
+			FilePath:  file,
+			StartLine: line,
+			EndLine:   line + 10,
+			Language:  lang,
+			Exported:  true,
+		}
+	}
+
+	ifaceSave := mk("store/repo.go:5:Save", "Save", ast.SymbolKindMethod, "store/repo.go", 5, "go")
+	repo := mk("store/repo.go:3:Repository", "Repository", ast.SymbolKindInterface, "store/repo.go", 3, "go")
+	repo.Children = []*ast.Symbol{ifaceSave}
+	memRepo := mk("store/mem.go:3:MemRepo", "MemRepo", ast.SymbolKindStruct, "store/mem.go", 3, "go")
+	memSave := mk("store/mem.go:10:MemRepo.Save", "Save", ast.SymbolKindMethod, "store/mem.go", 10, "go")
+	createUser := mk("service/user.go:10:CreateUser", "CreateUser", ast.SymbolKindFunction, "service/user.go", 10, "go")
+	handle := mk("api/handler.go:10:HandleCreate", "HandleCreate", ast.SymbolKindFunction, "api/handler.go", 10, "go")
+	test := mk("api/handler_test.go:10:TestHandleCreate", "TestHandleCreate", ast.SymbolKindFunction, "api/handler_test.go", 10, "go")
+	pyTest := mk("tests/test_user.py:4:test_create_user", "test_create_user", ast.SymbolKindFunction, "tests/test_user.py", 4, "python")
+
+	all := []*ast.Symbol{repo, ifaceSave, memRepo, memSave, createUser, handle, test, pyTest}
+	for _, sym := range all {
+		g.AddNode(sym)
+	}
+
+	edge := func(from, to *ast.Symbol, et graph.EdgeType) {
+		g.AddEdge(from.ID, to.ID, et, ast.Location{FilePath: from.FilePath, StartLine: from.StartLine + 1})
+	}
+	edge(memRepo, repo, graph.EdgeTypeImplements)
+	edge(memSave, memRepo, graph.EdgeTypeReceives)
+	edge(createUser, ifaceSave, graph.EdgeTypeCalls)
+	edge(handle, createUser, graph.EdgeTypeCalls)
+	edge(test, handle, graph.EdgeTypeCalls)
+	edge(pyTest, createUser, graph.EdgeTypeCalls)
+
+	g.Freeze()
+
+	for _, sym := range all {
+		idx.Add(sym)
+	}
+
+	return g, idx
+}
+
+func findImpacted(symbols []ImpactedSymbol, id string) (ImpactedSymbol, bool) {
+	for _, s := range symbols {
+		if s.ID == id {
+			return s, true
+		}
+	}
+	return ImpactedSymbol{}, false
+}
+
+func TestFileImpactAnalyzer_AnalyzeFileImpact(t *testing.T) {
+	g, idx := setupImpactTestGraph()
+	analyzer := NewFileImpactAnalyzer(g, idx)
+	ctx := context.Background()
+
+	t.Run("implementation change reaches callers through the interface", func(t *testing.T) {
+		result, err := analyzer.AnalyzeFileImpact(ctx, []string{"store/mem.go"}, FileImpactOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.MaxDepth != DefaultImpactDepth {
+			t.Errorf("MaxDepth = %d, want %d", result.MaxDepth, DefaultImpactDepth)
+		}
+		if len(result.Files) != 1 {
+			t.Fatalf("expected 1 file, got %d", len(result.Files))
+		}
+		fi := result.Files[0]
+		if !fi.Indexed || len(fi.DefinedSymbols) != 2 {
+			t.Errorf("expected 2 defined symbols, got %d (indexed=%v)", len(fi.DefinedSymbols), fi.Indexed)
+		}
+
+		save, ok := findImpacted(fi.DirectDependents, "store/repo.go:5:Save")
+		if !ok || save.Via != ImpactViaImplements {
+			t.Errorf("expected interface method Save as direct dependent via implements, got %+v", fi.DirectDependents)
+		}
+		if _, ok := findImpacted(fi.DirectDependents, "store/repo.go:3:Repository"); !ok {
+			t.Error("expected Repository interface as direct dependent")
+		}
+
+		create, ok := findImpacted(fi.TransitiveDependents, "service/user.go:10:CreateUser")
+		if !ok || create.Depth != 2 || create.Via != ImpactViaCalls {
+			t.Errorf("expected CreateUser at depth 2 via calls, got %+v", create)
+		}
+		if _, ok := findImpacted(fi.TransitiveDependents, "api/handler.go:10:HandleCreate"); !ok {
+			t.Error("expected HandleCreate at depth 3")
+		}
+		if _, ok := findImpacted(fi.TransitiveDependents, "api/handler_test.go:10:TestHandleCreate"); ok {
+			t.Error("TestHandleCreate is 4 hops away and must not appear at default depth")
+		}
+		if fi.DirectCount != len(fi.DirectDependents) || fi.TransitiveCount != len(fi.TransitiveDependents) {
+			t.Error("counts must match list lengths")
+		}
+
+		// Python test reaches CreateUser at depth 3.
+		if len(result.AffectedTestFiles) != 1 || result.AffectedTestFiles[0] != "tests/test_user.py" {
+			t.Errorf("AffectedTestFiles = %v, want [tests/test_user.py]", result.AffectedTestFiles)
+		}
+		if result.Confidence <= 0 || result.Confidence > 1 {
+			t.Errorf("confidence out of range: %f", result.Confidence)
+		}
+	})
+
+	t.Run("interface change reaches implementers", func(t *testing.T) {
+		result, err := analyzer.AnalyzeFileImpact(ctx, []string{"store/repo.go"}, FileImpactOptions{MaxDepth: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		fi := result.Files[0]
+		for _, id := range []string{"store/mem.go:3:MemRepo", "store/mem.go:10:MemRepo.Save", "service/user.go:10:CreateUser"} {
+			if _, ok := findImpacted(fi.DirectDependents, id); !ok {
+				t.Errorf("expected %s as direct dependent", id)
+			}
+		}
+		if fi.TransitiveCount != 0 {
+			t.Errorf("MaxDepth 1 must not produce transitive dependents, got %d", fi.TransitiveCount)
+		}
+	})
+
+	t.Run("deeper traversal flags go tests", func(t *testing.T) {
+		result, err := analyzer.AnalyzeFileImpact(ctx, []string{"store/mem.go"}, FileImpactOptions{MaxDepth: 4})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		test, ok := findImpacted(result.AffectedTests, "api/handler_test.go:10:TestHandleCreate")
+		if !ok || !test.IsTest || test.Depth != 4 {
+			t.Errorf("expected TestHandleCreate as affected test at depth 4, got %+v", result.AffectedTests)
+		}
+		if len(result.AffectedTestFiles) != 2 {
+			t.Errorf("expected 2 affected test files, got %v", result.AffectedTestFiles)
+		}
+	})
+
+	t.Run("dependents are de-duplicated across files", func(t *testing.T) {
+		result, err := analyzer.AnalyzeFileImpact(ctx,
+			[]string{"store/mem.go", "service/user.go"}, FileImpactOptions{MaxDepth: 3})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		mem, user := result.Files[0], result.Files[1]
+
+		// CreateUser is itself changed, so it is never a dependent.
+		for _, fi := range result.Files {
+			if _, ok := findImpacted(append(fi.DirectDependents, fi.TransitiveDependents...), "service/user.go:10:CreateUser"); ok {
+				t.Errorf("changed symbol CreateUser listed as dependent of %s", fi.FilePath)
+			}
+		}
+		// HandleCreate is 1 hop from service/user.go but 3 from store/mem.go.
+		if _, ok := findImpacted(user.DirectDependents, "api/handler.go:10:HandleCreate"); !ok {
+			t.Error("expected HandleCreate under service/user.go")
+		}
+		if _, ok := findImpacted(mem.TransitiveDependents, "api/handler.go:10:HandleCreate"); ok {
+			t.Error("HandleCreate must be listed only once")
+		}
+		total := 0
+		for _, fi := range result.Files {
+			total += fi.DirectCount + fi.TransitiveCount
+		}
+		if total != result.TotalDirectDependents+result.TotalTransitiveDependents {
+			t.Errorf("per-file counts %d do not sum to totals", total)
+		}
+	})
+
+	t.Run("changed test file is an affected test", func(t *testing.T) {
+		result, err := analyzer.AnalyzeFileImpact(ctx, []string{"./api/handler_test.go"}, FileImpactOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.AffectedTestFiles) != 1 || result.AffectedTestFiles[0] != "api/handler_test.go" {
+			t.Errorf("AffectedTestFiles = %v", result.AffectedTestFiles)
+		}
+	})
+
+	t.Run("unindexed file is reported as a limitation", func(t *testing.T) {
+		result, err := analyzer.AnalyzeFileImpact(ctx, []string{"docs/README.md"}, FileImpactOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Files[0].Indexed {
+			t.Error("expected Indexed=false")
+		}
+		if len(result.Limitations) == 0 {
+			t.Error("expected a limitation for the unindexed file")
+		}
+	})
+}
+
+func TestFileImpactAnalyzer_Errors(t *testing.T) {
+	g, idx := setupImpactTestGraph()
+	analyzer := NewFileImpactAnalyzer(g, idx)
+
+	if _, err := analyzer.AnalyzeFileImpact(context.Background(), nil, FileImpactOptions{}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("empty files: got %v, want ErrInvalidInput", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := analyzer.AnalyzeFileImpact(ctx, []string{"store/mem.go"}, FileImpactOptions{}); !errors.Is(err, ErrContextCanceled) {
+		t.Errorf("canceled: got %v, want ErrContextCanceled", err)
+	}
+
+	unfrozen := graph.NewGraph("/test/project")
+	if _, err := NewFileImpactAnalyzer(unfrozen, idx).AnalyzeFileImpact(context.Background(), []string{"store/mem.go"}, FileImpactOptions{}); !errors.Is(err, ErrGraphNotReady) {
+		t.Errorf("unfrozen: got %v, want ErrGraphNotReady", err)
+	}
+}
+
+func TestFileImpactAnalyzer_CollectDependentsCanceled(t *testing.T) {
+	g, idx := setupImpactTestGraph()
+	analyzer := NewFileImpactAnalyzer(g, idx)
+	seed, ok := g.GetNode("store/repo.go:5:Save")
+	if !ok {
+		t.Fatal("seed node missing")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// maxDepth 0 skips the walk, so only the final check sees the cancellation.
+	for _, maxDepth := range []int{0, 3} {
+		best := make(map[string]impactReach)
+		_, err := analyzer.collectDependents(ctx, 0, []*ast.Symbol{seed.Symbol}, maxDepth,
+			map[string]bool{}, analyzer.buildParentMap(), best)
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("maxDepth %d: got %v, want context.Canceled", maxDepth, err)
+		}
+	}
+}
+
+func TestIsTestPath(t *testing.T) {
+	cases := map[string]bool{
+		"pkg/foo_test.go":        true,
+		"tests/test_user.py":     true,
+		"tests/conftest.py":      true,
+		"web/app.spec.ts":        true,
+		"pkg/foo.go":             false,
+		"pkg/testing_helpers.py": false,
+	}
+	for path, want := range cases {
+		if got := isTestPath(path); got != want {
+			t.Errorf("isTestPath(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
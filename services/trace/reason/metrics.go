@@ -15,6 +15,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -88,7 +89,7 @@ func startAnalysisSpan(ctx context.Context, operation, targetID string) (context
 	return tracer.Start(ctx, "BreakingChangeAnalyzer."+operation,
 		trace.WithAttributes(
 			attribute.String("reason.operation", operation),
-			attribute.String("reason.target_id", targetID),
+			telemetry.SensitiveString("reason.target_id", targetID),
 		),
 	)
 }
@@ -131,7 +132,7 @@ func startRefactorSpan(ctx context.Context, targetID string) (context.Context, t
 	return tracer.Start(ctx, "reason.RefactorSuggester.SuggestRefactor",
 		trace.WithAttributes(
 			attribute.String("reason.operation", "suggest_refactor"),
-			attribute.String("reason.target_id", targetID),
+			telemetry.SensitiveString("reason.target_id", targetID),
 		),
 	)
 }
@@ -169,7 +170,7 @@ func startCoverageSpan(ctx context.Context, targetID string) (context.Context, t
 	return tracer.Start(ctx, "reason.TestCoverageFinder.FindTestCoverage",
 		trace.WithAttributes(
 			attribute.String("reason.operation", "find_test_coverage"),
-			attribute.String("reason.target_id", targetID),
+			telemetry.SensitiveString("reason.target_id", targetID),
 		),
 	)
 }
@@ -207,7 +208,7 @@ func startValidateSpan(ctx context.Context, filePath string) (context.Context, t
 	return tracer.Start(ctx, "reason.ChangeValidator.ValidateChange",
 		trace.WithAttributes(
 			attribute.String("reason.operation", "validate_change"),
-			attribute.String("reason.file_path", filePath),
+			telemetry.SensitiveString("reason.file_path", filePath),
 		),
 	)
 }
@@ -246,7 +247,7 @@ func startSimulateSpan(ctx context.Context, targetID string) (context.Context, t
 	return tracer.Start(ctx, "reason.ChangeSimulator.SimulateChange",
 		trace.WithAttributes(
 			attribute.String("reason.operation", "simulate_change"),
-			attribute.String("reason.target_id", targetID),
+			telemetry.SensitiveString("reason.target_id", targetID),
 		),
 	)
 }
@@ -274,3 +275,82 @@ func recordSimulateMetrics(ctx context.Context, duration time.Duration, err erro
 	analysisLatency.Record(ctx, duration.Seconds(), attrs)
 	analysisTotal.Add(ctx, 1, attrs)
 }
+
+// ============================================================================
+// File Impact Analyzer OTel
+// ============================================================================
+
+// startImpactSpan creates a span for file impact analysis.
+func startImpactSpan(ctx context.Context, fileCount int) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "reason.FileImpactAnalyzer.AnalyzeFileImpact",
+		trace.WithAttributes(
+			attribute.String("reason.operation", "analyze_impact"),
+			attribute.Int("reason.file_count", fileCount),
+		),
+	)
+}
+
+// setImpactSpanResult sets result attributes on an impact analysis span.
+func setImpactSpanResult(span trace.Span, dependents, affectedTests int, err error) {
+	span.SetAttributes(
+		attribute.Int("reason.dependents", dependents),
+		attribute.Int("reason.affected_tests", affectedTests),
+		attribute.Bool("reason.success", err == nil),
+	)
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+// recordImpactMetrics records metrics for file impact analysis.
+func recordImpactMetrics(ctx context.Context, duration time.Duration, err error) {
+	if initErr := initMetrics(); initErr != nil {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("operation", "analyze_impact"),
+		attribute.Bool("success", err == nil),
+	)
+	analysisLatency.Record(ctx, duration.Seconds(), attrs)
+	analysisTotal.Add(ctx, 1, attrs)
+}
+
+// ============================================================================
+// Dependency Surface Analyzer OTel
+// ============================================================================
+
+// startDependencySurfaceSpan creates a span for dependency surface analysis.
+func startDependencySurfaceSpan(ctx context.Context, pkg string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, "reason.DependencySurfaceAnalyzer.AnalyzeDependencySurface",
+		trace.WithAttributes(
+			attribute.String("reason.operation", "analyze_dependency_surface"),
+			attribute.String("reason.package", pkg),
+		),
+	)
+}
+
+// setDependencySurfaceSpanResult sets result attributes on a dependency
+// surface span.
+func setDependencySurfaceSpanResult(span trace.Span, symbols int, err error) {
+	span.SetAttributes(
+		attribute.Int("reason.surface_symbols", symbols),
+		attribute.Bool("reason.success", err == nil),
+	)
+	if err != nil {
+		span.RecordError(err)
+	}
+}
+
+// recordDependencySurfaceMetrics records metrics for dependency surface
+// analysis.
+func recordDependencySurfaceMetrics(ctx context.Context, duration time.Duration, err error) {
+	if initErr := initMetrics(); initErr != nil {
+		return
+	}
+	attrs := metric.WithAttributes(
+		attribute.String("operation", "analyze_dependency_surface"),
+		attribute.Bool("success", err == nil),
+	)
+	analysisLatency.Record(ctx, duration.Seconds(), attrs)
+	analysisTotal.Add(ctx, 1, attrs)
+}
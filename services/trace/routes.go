@@ -32,19 +32,52 @@ import (
 // Core Endpoints:
 //
 //	POST /v1/trace/init - Initialize a code graph
+//	POST /v1/trace/init/from-parse-results - Build a graph from uploaded parse results
+//	GET  /v1/trace/init/:build_id - Get build progress and result
+//	DELETE /v1/trace/init/:build_id - Cancel a running build
 //	POST /v1/trace/context - Assemble context for LLM prompt
 //	GET  /v1/trace/symbol/:id - Get symbol by ID
+//	GET  /v1/trace/symbol/:id/source - Get symbol source with context lines
 //	GET  /v1/trace/callers - Find function callers
 //	GET  /v1/trace/implementations - Find interface implementations
+//	GET  /v1/trace/impl/explain - Explain why a type does or does not implement an interface
 //	GET  /v1/trace/callees - Find function callees
 //	GET  /v1/trace/call-chain - Find shortest call chain between two functions
 //	GET  /v1/trace/references - Find symbol references
+//	GET  /v1/trace/graph/status - Graph summary and content hash
+//	GET  /v1/trace/graph/report - Graph quality report and confidence score
+//	GET  /v1/trace/graph/manifest - Content hash of every file in the graph
+//	GET  /v1/trace/summary - Project summary (languages, packages, frameworks, entry points)
+//	POST /v1/trace/graph/query - Structured graph query (or explain)
+//	POST /v1/trace/graph/compact - Resolve placeholders and deduplicate edges
+//	POST /v1/trace/graph/validate - Full graph invariant check
+//	POST /v1/trace/views - Create a scoped view of a graph
+//	GET  /v1/trace/views - List scoped views
+//	GET  /v1/trace/views/:name - Get a scoped view
+//	DELETE /v1/trace/views/:name - Delete a scoped view
+//	POST /v1/trace/annotations - Add or replace symbol annotations
+//	GET  /v1/trace/annotations - List symbol annotations
+//	DELETE /v1/trace/annotations - Delete API annotations of a symbol
+//	GET  /v1/trace/facts - List facts users confirmed about the project
+//	PATCH /v1/trace/facts/:id - Edit a fact's statement or anchors
+//	DELETE /v1/trace/facts/:id - Delete a fact
+//	POST /v1/trace/chunks - Split project files into symbol-aligned chunks
+//	POST /v1/trace/deps/advisories - Flag code using vulnerable dependency versions
+//	POST /v1/trace/assertions/check - Check architectural assertion rules
+//	GET  /v1/trace/errors - Error code catalog
 //	POST /v1/trace/analytics/hotspots - Find most-connected nodes
 //	POST /v1/trace/analytics/cycles - Find cyclic dependencies
 //	POST /v1/trace/analytics/important - Find most important nodes (PageRank)
 //	POST /v1/trace/analytics/communities - Detect code communities
 //	POST /v1/trace/analytics/path - Find shortest path between functions
 //
+//	The symbol, callers, implementations, impl/explain, callees,
+//	call-chain and references endpoints return a per-graph ETag and answer 304 Not
+//	Modified when If-None-Match matches (see HTTPCacheConfig).
+//
+//	Every endpoint takes a link_template query parameter overriding the
+//	server's template for the uri of code locations (see LinkTemplate).
+//
 // Memory Endpoints:
 //
 //	GET  /v1/trace/memories - List memories
@@ -54,9 +87,10 @@ import (
 //	POST /v1/trace/memories/:id/validate - Validate a memory
 //	POST /v1/trace/memories/:id/contradict - Contradict a memory
 //
-// Agentic Tool Endpoints (24 tools):
+// Agentic Tool Endpoints (26 tools):
 //
-//	GET  /v1/trace/tools - Discover available tools
+//	GET  /v1/trace/tools - Discover available tools (graph_id adds project tools)
+//	POST /v1/trace/tools/:name/execute - Run a project tool
 //
 //	POST /v1/trace/explore/entry_points - Find entry points
 //	POST /v1/trace/explore/data_flow - Trace data flow
@@ -74,10 +108,13 @@ import (
 //	POST /v1/trace/reason/test_coverage - Find test coverage
 //	POST /v1/trace/reason/side_effects - Detect side effects
 //	POST /v1/trace/reason/suggest_refactor - Suggest refactoring
+//	POST /v1/trace/reason/impact - Analyze impact of changed files
+//	POST /v1/trace/reason/dependency_surface - Code using a third-party package
 //
 //	POST /v1/trace/coordinate/plan_changes - Plan multi-file changes
 //	POST /v1/trace/coordinate/validate_plan - Validate a change plan
 //	POST /v1/trace/coordinate/preview_changes - Preview changes as diffs
+//	POST /v1/trace/coordinate/preview_rename - Plan a graph-aware rename
 //
 //	POST /v1/trace/patterns/detect - Detect design patterns
 //	POST /v1/trace/patterns/code_smells - Find code smells
@@ -86,14 +123,44 @@ import (
 //	POST /v1/trace/patterns/conventions - Extract conventions
 //	POST /v1/trace/patterns/dead_code - Find dead code
 //
+// Safety Endpoints:
+//
+//	POST /v1/trace/safety/evaluate - Dry-run a tool call against the safety policy
+//
+// Plan Approval Endpoints:
+//
+//	GET  /v1/trace/plans - List change plans by status or session
+//	GET  /v1/trace/plans/events - Stream plan status events (SSE)
+//	GET  /v1/trace/plans/:id - Get a plan with its diff preview
+//	POST /v1/trace/plans/:id/approve - Approve a pending plan
+//	POST /v1/trace/plans/:id/reject - Reject a pending plan
+//	POST /v1/trace/plans/:id/apply - Apply an approved plan
+//
+// Egress Endpoints:
+//
+//	GET /v1/trace/egress/audit - Query persisted egress audit records
+//
+// Usage Endpoints:
+//
+//	GET /v1/trace/usage - LLM token usage and cost by API key, session or model
+//
+// Admin Endpoints:
+//
+//	GET  /v1/trace/admin/loglevel - Get the current log level
+//	POST /v1/trace/admin/loglevel - Change the log level at runtime
+//	POST /v1/trace/admin/reload-routing - Reload pre-filter rules and tool registry
+//	POST /v1/trace/admin/routing/reembed - Re-embed the routing tool corpus in the background
+//
 // Metrics Endpoints:
 //
 //	GET  /v1/metrics - Prometheus metrics (served via OTel exporter)
 //
 // Health Endpoints:
 //
-//	GET  /v1/trace/health - Health check
-//	GET  /v1/trace/ready - Readiness check
+//	GET  /v1/trace/health/live - Liveness (process is up)
+//	GET  /v1/trace/health/ready - Readiness with per-subsystem detail
+//	GET  /v1/trace/health - Alias for /health/ready
+//	GET  /v1/trace/ready - Alias for /health/ready
 //
 // Example:
 //
@@ -114,22 +181,60 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 	})
 
 	trace := rg.Group("/trace")
+	trace.Use(linkTemplateOverride)
 	{
 		// Graph lifecycle
 		trace.POST("/init", handlers.HandleInit)
+		trace.POST("/init/from-parse-results", handlers.HandleInitFromParseResults)
+		trace.GET("/init/:build_id", handlers.HandleInitStatus)
+		trace.DELETE("/init/:build_id", handlers.HandleCancelInit)
 
 		// Context assembly
 		trace.POST("/context", handlers.HandleContext)
 
 		// Symbol queries
-		trace.GET("/symbol/:id", handlers.HandleSymbol)
-		trace.GET("/callers", handlers.HandleCallers)
-		trace.GET("/implementations", handlers.HandleImplementations)
+		// Read-only graph queries carry the graph ETag and honor
+		// If-None-Match; callers and implementations are memoized.
+		trace.GET("/symbol/:id", handlers.httpCache(cacheGroupSymbol, false), handlers.HandleSymbol)
+		trace.GET("/symbol/:id/source", handlers.httpCache(cacheGroupSymbol, false), handlers.HandleSymbolSource)
+		trace.GET("/callers", handlers.httpCache(cacheGroupQuery, true), handlers.HandleCallers)
+		trace.GET("/implementations", handlers.httpCache(cacheGroupQuery, true), handlers.HandleImplementations)
+		trace.GET("/impl/explain", handlers.httpCache(cacheGroupQuery, false), handlers.HandleImplExplain)
 
 		// Graph query endpoints (CB-00.0)
-		trace.GET("/callees", handlers.HandleFindCallees)
-		trace.GET("/call-chain", handlers.HandleGetCallChain)
-		trace.GET("/references", handlers.HandleFindReferences)
+		trace.GET("/callees", handlers.httpCache(cacheGroupQuery, false), handlers.HandleFindCallees)
+		trace.GET("/call-chain", handlers.httpCache(cacheGroupQuery, false), handlers.HandleGetCallChain)
+		trace.GET("/references", handlers.httpCache(cacheGroupQuery, false), handlers.HandleFindReferences)
+		trace.GET("/graph/status", handlers.HandleGraphStatus)
+		trace.GET("/graph/report", handlers.HandleGraphReport)
+		trace.GET("/graph/manifest", handlers.HandleGraphManifest)
+		trace.GET("/summary", handlers.HandleProjectSummary)
+		trace.POST("/graph/query", handlers.HandleGraphQuery)
+		trace.POST("/graph/compact", handlers.HandleCompactGraph)
+		trace.POST("/graph/validate", handlers.HandleValidateGraph)
+		trace.GET("/errors", handlers.HandleListErrors)
+
+		// Scoped graph views
+		viewsGroup := trace.Group("/views")
+		{
+			viewsGroup.POST("", handlers.HandleCreateView)
+			viewsGroup.GET("", handlers.HandleListViews)
+			viewsGroup.GET("/:name", handlers.HandleGetView)
+			viewsGroup.DELETE("/:name", handlers.HandleDeleteView)
+		}
+
+		// Symbol annotations (deprecated, tier, owner, ...)
+		trace.POST("/annotations", handlers.HandlePutAnnotations)
+		trace.GET("/annotations", handlers.HandleListAnnotations)
+		trace.DELETE("/annotations", handlers.HandleDeleteAnnotations)
+
+		// Facts users confirmed or corrected in agent sessions
+		trace.GET("/facts", handlers.HandleListFacts)
+		trace.PATCH("/facts/:id", handlers.HandleUpdateFact)
+		trace.DELETE("/facts/:id", handlers.HandleDeleteFact)
+
+		// Symbol-aligned chunks for embedding pipelines
+		trace.POST("/chunks", handlers.HandleChunks)
 
 		// Graph analytics endpoints (CB-00.0)
 		analyticsGroup := trace.Group("/analytics")
@@ -152,8 +257,18 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 		// Indexing status (polled by trace-proxy for progress feedback)
 		trace.GET("/indexing/status", handlers.HandleIndexingStatus)
 
+		// Synthetic doc generation progress
+		trace.GET("/enrichment/status", handlers.HandleEnrichmentStatus)
+
+		// Semantic code search over locally embedded symbols
+		trace.GET("/search/semantic", handlers.HandleSemanticSearch)
+		trace.GET("/search/semantic/status", handlers.HandleSemanticIndexStatus)
+
 		// Health checks
-		trace.GET("/health", handlers.HandleHealth)
+		trace.GET("/health/live", handlers.HandleLive)
+		trace.GET("/health/ready", handlers.HandleReady)
+		// Aliases kept for existing probes and clients.
+		trace.GET("/health", handlers.HandleReady)
 		trace.GET("/ready", handlers.HandleReady)
 
 		// =================================================================
@@ -185,6 +300,7 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 
 		// Tool discovery
 		trace.GET("/tools", handlers.HandleGetTools)
+		trace.POST("/tools/:name/execute", handlers.HandleExecuteProjectTool)
 
 		// Exploration tools (9 endpoints)
 		explore := trace.Group("/explore")
@@ -200,7 +316,7 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 			explore.POST("/change_impact", handlers.HandleAnalyzeChangeImpact)
 		}
 
-		// Reasoning tools (6 endpoints)
+		// Reasoning tools (8 endpoints)
 		reason := trace.Group("/reason")
 		{
 			reason.POST("/breaking_changes", handlers.HandleCheckBreakingChanges)
@@ -209,14 +325,23 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 			reason.POST("/test_coverage", handlers.HandleFindTestCoverage)
 			reason.POST("/side_effects", handlers.HandleDetectSideEffects)
 			reason.POST("/suggest_refactor", handlers.HandleSuggestRefactor)
+			reason.POST("/impact", handlers.HandleAnalyzeImpact)
+			reason.POST("/dependency_surface", handlers.HandleAnalyzeDependencySurface)
 		}
 
-		// Coordination tools (3 endpoints)
+		// Dependency advisories (client-supplied OSV JSON, no network)
+		trace.POST("/deps/advisories", handlers.HandleDependencyAdvisories)
+
+		// Architectural assertions (inline or project rules file)
+		trace.POST("/assertions/check", handlers.HandleCheckAssertions)
+
+		// Coordination tools (4 endpoints)
 		coordinate := trace.Group("/coordinate")
 		{
 			coordinate.POST("/plan_changes", handlers.HandlePlanMultiFileChange)
 			coordinate.POST("/validate_plan", handlers.HandleValidatePlan)
 			coordinate.POST("/preview_changes", handlers.HandlePreviewChanges)
+			coordinate.POST("/preview_rename", handlers.HandlePreviewRename)
 		}
 
 		// Pattern tools (6 endpoints)
@@ -229,6 +354,41 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 			patterns.POST("/conventions", handlers.HandleExtractConventions)
 			patterns.POST("/dead_code", handlers.HandleFindDeadCode)
 		}
+
+		// Safety policy dry run
+		safetyGroup := trace.Group("/safety")
+		{
+			safetyGroup.POST("/evaluate", handlers.HandleSafetyEvaluate)
+		}
+
+		// Change plan approval
+		plansGroup := trace.Group("/plans")
+		{
+			plansGroup.GET("", handlers.HandleListPlans)
+			plansGroup.GET("/events", handlers.HandlePlanEvents)
+			plansGroup.GET("/:id", handlers.HandleGetPlan)
+			plansGroup.POST("/:id/approve", handlers.HandleApprovePlan)
+			plansGroup.POST("/:id/reject", handlers.HandleRejectPlan)
+			plansGroup.POST("/:id/apply", handlers.HandleApplyPlan)
+		}
+
+		// Egress audit trail
+		egressGroup := trace.Group("/egress")
+		{
+			egressGroup.GET("/audit", handlers.HandleEgressAudit)
+		}
+
+		// LLM usage accounting
+		trace.GET("/usage", handlers.HandleUsage)
+
+		// Runtime administration
+		adminGroup := trace.Group("/admin")
+		{
+			adminGroup.GET("/loglevel", handlers.HandleGetLogLevel)
+			adminGroup.POST("/loglevel", handlers.HandleSetLogLevel)
+			adminGroup.POST("/reload-routing", handlers.HandleReloadRouting)
+			adminGroup.POST("/routing/reembed", handlers.HandleReembedRouting)
+		}
 	}
 }
 
@@ -250,9 +410,16 @@ func RegisterRoutes(rg *gin.RouterGroup, handlers *Handlers) {
 //	POST /v1/trace/agent/run - Start a new agent session
 //	POST /v1/trace/agent/continue - Continue from CLARIFY state
 //	POST /v1/trace/agent/abort - Abort an active session
+//	GET  /v1/trace/agent/queue - Run queue status
+//	GET  /v1/trace/agent/sessions - List persisted session transcripts
+//	GET  /v1/trace/agent/sessions/:id - Get a persisted session transcript
+//	DELETE /v1/trace/agent/sessions/:id - Delete a persisted session transcript
 //	GET  /v1/trace/agent/:id - Get session state
 //	GET  /v1/trace/agent/:id/reasoning - Get reasoning trace
 //	GET  /v1/trace/agent/:id/crs - Get CRS state export
+//	GET  /v1/trace/agent/debug/routing/feedback - Inspect routing feedback
+//	DELETE /v1/trace/agent/debug/routing/feedback - Reset routing feedback
+//	POST /v1/trace/routing/explain - Explain how a query would be routed
 //
 // Example:
 //
@@ -271,27 +438,35 @@ func RegisterAgentRoutes(rg *gin.RouterGroup, handlers *AgentHandlers) {
 // Description:
 //
 //	Same as RegisterAgentRoutes but allows applying middleware (e.g., warmup guard)
-//	to all agent endpoints. If middleware is nil, no additional middleware is applied.
+//	to the endpoints that call a model: /run, /continue and /routing/explain.
+//	Session, state, CRS and debug endpoints stay available while models warm
+//	up. If middleware is nil, no additional middleware is applied.
 //
 // Inputs:
 //
 //	rg - The router group to register routes under.
 //	handlers - The agent handlers.
-//	middleware - Optional middleware to apply to all agent routes. Can be nil.
+//	middleware - Optional middleware for the model-backed routes. Can be nil.
 //
 // Thread Safety: This function is safe for concurrent use.
 func RegisterAgentRoutesWithMiddleware(rg *gin.RouterGroup, handlers *AgentHandlers, middleware gin.HandlerFunc) {
-	var agent *gin.RouterGroup
+	agent := rg.Group("/trace/agent")
+	agent.Use(linkTemplateOverride)
+	modelBacked := agent.Group("")
 	if middleware != nil {
-		agent = rg.Group("/trace/agent", middleware)
-	} else {
-		agent = rg.Group("/trace/agent")
+		modelBacked.Use(middleware)
 	}
 	{
 		// Session lifecycle
-		agent.POST("/run", handlers.HandleAgentRun)
-		agent.POST("/continue", handlers.HandleAgentContinue)
+		modelBacked.POST("/run", handlers.HandleAgentRun)
+		modelBacked.POST("/continue", handlers.HandleAgentContinue)
 		agent.POST("/abort", handlers.HandleAgentAbort)
+		agent.GET("/queue", handlers.HandleAgentQueue)
+
+		// Persisted session transcripts
+		agent.GET("/sessions", handlers.HandleListSessions)
+		agent.GET("/sessions/:id", handlers.HandleGetSession)
+		agent.DELETE("/sessions/:id", handlers.HandleDeleteSession)
 
 		// Session state
 		agent.GET("/:id", handlers.HandleAgentState)
@@ -308,6 +483,15 @@ func RegisterAgentRoutesWithMiddleware(rg *gin.RouterGroup, handlers *AgentHandl
 		{
 			debug.GET("/crs", handlers.HandleDebugCRS)
 			debug.GET("/history", handlers.HandleDebugHistory)
+			debug.GET("/routing/feedback", handlers.HandleGetRoutingFeedback)
+			debug.DELETE("/routing/feedback", handlers.HandleResetRoutingFeedback)
 		}
 	}
+
+	// Routing explanation runs the router model, so it is model-backed too.
+	routingGroup := rg.Group("/trace/routing")
+	if middleware != nil {
+		routingGroup.Use(middleware)
+	}
+	routingGroup.POST("/explain", handlers.HandleRoutingExplain)
 }
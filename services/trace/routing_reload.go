@@ -0,0 +1,367 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/gin-gonic/gin"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// DefaultRoutingReloadDebounce is how long the watcher waits after the last
+// change to a routing config file before reloading. Editors often write a
+// file in several steps.
+const DefaultRoutingReloadDebounce = 500 * time.Millisecond
+
+// RoutingConfigError is returned by Reload when the new config fails to
+// load or validate. The current config is unchanged.
+type RoutingConfigError struct {
+	// Problems lists every problem found, one per entry.
+	Problems []string
+}
+
+// Error implements error.
+func (e *RoutingConfigError) Error() string {
+	return "invalid routing config: " + strings.Join(e.Problems, "; ")
+}
+
+// RoutingReloader reloads the pre-filter rules and the tool routing
+// registry without a restart.
+//
+// Description:
+//
+//	A reload reads both files, validates them, and only then swaps them
+//	in: first into the pre-filter, then into the config singletons. An
+//	invalid config is rejected with every problem found and the running
+//	config is kept. Reloads are serialized.
+//
+//	Each pre-filter call reads the rules once, so a query in flight
+//	during a reload is routed entirely by the old rules or entirely by
+//	the new ones.
+//
+// Thread Safety: Safe for concurrent use.
+type RoutingReloader struct {
+	mu        sync.Mutex
+	preFilter *routing.PreFilter
+	logger    *slog.Logger
+}
+
+// NewRoutingReloader creates a reloader.
+//
+// Inputs:
+//
+//	preFilter - The pre-filter to update. Nil if the pre-filter was
+//	  disabled at startup; reloads then only update the config singletons
+//	  and enabling the pre-filter takes a restart.
+//	logger - Logger for reload results. Nil uses slog.Default().
+//
+// Outputs:
+//
+//	*RoutingReloader - The reloader.
+func NewRoutingReloader(preFilter *routing.PreFilter, logger *slog.Logger) *RoutingReloader {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &RoutingReloader{preFilter: preFilter, logger: logger}
+}
+
+// Reload loads, validates and applies the routing config.
+//
+// Inputs:
+//
+//	ctx - Context for tracing. Must not be nil.
+//
+// Outputs:
+//
+//	*RoutingReloadResponse - The new generation and what changed.
+//	error - *RoutingConfigError if the config is invalid; the running
+//	  config is unchanged.
+func (r *RoutingReloader) Reload(ctx context.Context) (*RoutingReloadResponse, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	updated, err := config.LoadRoutingConfig(ctx)
+	if err != nil {
+		problems := splitJoinedErrors(err)
+		r.logger.Warn("Routing config reload rejected, keeping current config",
+			slog.Int("problems", len(problems)),
+			telemetry.SensitiveLogError("error", err))
+		return nil, &RoutingConfigError{Problems: problems}
+	}
+
+	diff := config.DiffRoutingConfig(config.CurrentRoutingConfig(ctx), updated)
+
+	if r.preFilter != nil {
+		if err := r.preFilter.SetRules(updated.Registry, updated.PreFilter); err != nil {
+			return nil, fmt.Errorf("applying pre-filter rules: %w", err)
+		}
+	}
+	generation, err := config.SetRoutingConfig(updated)
+	if err != nil {
+		return nil, fmt.Errorf("applying routing config: %w", err)
+	}
+
+	resp := &RoutingReloadResponse{
+		Generation:       generation,
+		PreFilterSource:  updated.PreFilterSource,
+		RegistrySource:   updated.RegistrySource,
+		PreFilterActive:  r.preFilter != nil,
+		Changes:          diff,
+		Summary:          diff.Summary(),
+		RestartRequired:  diff.RestartRequired(),
+		ToolCount:        updated.Registry.ToolCount(),
+		PreFilterEnabled: updated.PreFilter.Enabled,
+	}
+	if r.preFilter == nil && updated.PreFilter.Enabled {
+		resp.RestartRequired = append(resp.RestartRequired, "enabled")
+	}
+
+	r.logger.Info("Routing config reloaded",
+		slog.Uint64("generation", generation),
+		slog.String("changes", resp.Summary),
+		slog.String("prefilter_source", updated.PreFilterSource),
+		slog.String("registry_source", updated.RegistrySource),
+		slog.Any("restart_required", resp.RestartRequired))
+	return resp, nil
+}
+
+// errRoutingReembedUnavailable is returned by Reembed when there is
+// nothing to re-embed.
+var errRoutingReembedUnavailable = errors.New("routing re-embedding is not available")
+
+// Reembed queues a forced background re-embedding of the tool corpus the
+// pre-filter routes with.
+//
+// Description:
+//
+//	Cached tool vectors are ignored and every tool is embedded again, for
+//	example after the model behind the embedding endpoint was replaced
+//	under the same name. Queries keep using the current vectors until the
+//	new ones are ready.
+//
+// Outputs:
+//
+//	*RoutingReembedResponse - The corpus being re-embedded.
+//	error - Wraps errRoutingReembedUnavailable if the pre-filter was
+//	  disabled at startup or has not routed any tools yet.
+func (r *RoutingReloader) Reembed() (*RoutingReembedResponse, error) {
+	if r.preFilter == nil {
+		return nil, fmt.Errorf("%w: the tool pre-filter is disabled", errRoutingReembedUnavailable)
+	}
+	status, ok := r.preFilter.Reembed()
+	if !ok {
+		return nil, fmt.Errorf("%w: no tools have been routed yet", errRoutingReembedUnavailable)
+	}
+	return &RoutingReembedResponse{CorpusHash: status.CorpusHash, ToolCount: status.ToolCount, Status: "queued"}, nil
+}
+
+// splitJoinedErrors returns one message per line of an errors.Join error.
+func splitJoinedErrors(err error) []string {
+	var out []string
+	for _, line := range strings.Split(err.Error(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// Watch reloads the routing config when its files change, until ctx is
+// done.
+//
+// Description:
+//
+//	Watches the directories of the external rules and registry files
+//	(see config.RoutingConfigFiles), so files replaced by rename, as
+//	editors and Kubernetes ConfigMap updates do, are still seen. Changes
+//	are debounced; a failed reload is logged and the current config kept.
+//
+// Inputs:
+//
+//	ctx - Stops the watcher when done.
+//	debounce - Quiet period before reloading. Zero or less uses
+//	  DefaultRoutingReloadDebounce.
+//
+// Outputs:
+//
+//	error - Non-nil if no external file is configured or the watcher
+//	  cannot be created. Reload failures are only logged.
+func (r *RoutingReloader) Watch(ctx context.Context, debounce time.Duration) error {
+	files := config.RoutingConfigFiles()
+	if len(files) == 0 {
+		return errors.New("no external routing config files to watch")
+	}
+	if debounce <= 0 {
+		debounce = DefaultRoutingReloadDebounce
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating file watcher: %w", err)
+	}
+
+	watched := make(map[string]bool, len(files))
+	dirs := make(map[string]bool, len(files))
+	for _, f := range files {
+		watched[filepath.Clean(f)] = true
+		dirs[filepath.Dir(f)] = true
+	}
+	for dir := range dirs {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("watching %s: %w", dir, err)
+		}
+	}
+
+	r.logger.Info("Watching routing config files", slog.Any("files", files))
+	go r.watchLoop(ctx, watcher, watched, debounce)
+	return nil
+}
+
+// watchLoop debounces watcher events into reloads.
+func (r *RoutingReloader) watchLoop(ctx context.Context, watcher *fsnotify.Watcher, watched map[string]bool, debounce time.Duration) {
+	defer watcher.Close()
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if !isRoutingConfigEvent(event, watched) {
+				continue
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Warn("Routing config watcher error", telemetry.SensitiveLogError("error", err))
+		case <-timer.C:
+			if _, err := r.Reload(ctx); err != nil {
+				r.logger.Warn("Routing config file changed but reload failed",
+					telemetry.SensitiveLogError("error", err))
+			}
+		}
+	}
+}
+
+// isRoutingConfigEvent reports whether a watcher event may have changed a
+// watched file. Kubernetes updates ConfigMap volumes by swapping the
+// "..data" symlink, which renames no watched file.
+func isRoutingConfigEvent(event fsnotify.Event, watched map[string]bool) bool {
+	if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+		return false
+	}
+	name := filepath.Clean(event.Name)
+	return watched[name] || filepath.Base(name) == "..data"
+}
+
+// WithRoutingReloader sets the reloader behind the routing reload
+// endpoint. Nil disables the endpoint.
+func (h *Handlers) WithRoutingReloader(r *RoutingReloader) *Handlers {
+	h.routingReloader = r
+	return h
+}
+
+// HandleReloadRouting handles POST /v1/trace/admin/reload-routing.
+//
+// Description:
+//
+//	Reloads the pre-filter rules and the tool routing registry from
+//	their files and swaps them in if they are valid. Queries already
+//	being routed finish with the old rules.
+//
+// Response:
+//
+//	200 OK: RoutingReloadResponse with the new generation and changes
+//	422 Unprocessable Entity: INVALID_ROUTING_CONFIG listing every
+//	        problem; the current config is kept
+//	503 Service Unavailable: No reloader is configured
+//
+// Thread Safety: Safe for concurrent use. Reloads are serialized.
+func (h *Handlers) HandleReloadRouting(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleReloadRouting")
+
+	if h.routingReloader == nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeRoutingReloadNotAvailable, "Routing config reload is not configured")
+		return
+	}
+
+	resp, err := h.routingReloader.Reload(c.Request.Context())
+	if err != nil {
+		var invalid *RoutingConfigError
+		if errors.As(err, &invalid) {
+			apierror.New(http.StatusUnprocessableEntity, apierror.CodeInvalidRoutingConfig,
+				"Routing config rejected, current config kept: "+strings.Join(invalid.Problems, "; ")).
+				WithDetails("Fix the listed problems and reload again").
+				Write(c)
+			return
+		}
+		logger.Error("Routing config reload failed", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "reloading routing config: "+err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// HandleReembedRouting handles POST /v1/trace/admin/routing/reembed.
+//
+// Description:
+//
+//	Queues a forced re-embedding of every routing tool, ignoring cached
+//	vectors. It runs in the background; its completion is logged and
+//	the routing embedding cache entries it replaces are deleted.
+//
+// Response:
+//
+//	202 Accepted: RoutingReembedResponse with the corpus being re-embedded
+//	503 Service Unavailable: ROUTING_REEMBED_NOT_AVAILABLE if the
+//	        pre-filter is disabled or has not routed any tools yet
+//
+// Thread Safety: Safe for concurrent use. Requests arriving while a
+// re-embedding runs queue one more.
+func (h *Handlers) HandleReembedRouting(c *gin.Context) {
+	if h.routingReloader == nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeRoutingReembedNotAvailable, "Routing re-embedding is not configured")
+		return
+	}
+
+	resp, err := h.routingReloader.Reembed()
+	if err != nil {
+		apierror.Write(c, http.StatusServiceUnavailable, apierror.CodeRoutingReembedNotAvailable, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusAccepted, resp)
+}
@@ -0,0 +1,266 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/routing"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
+)
+
+const reloadRulesYAML = `
+enabled: true
+forced_mappings:
+  - patterns: ["call chain from"]
+    tool: get_call_chain
+    reason: test
+`
+
+const reloadRegistryYAML = `
+tools:
+  - name: get_call_chain
+    keywords: [chain]
+  - name: find_path
+    keywords: [path]
+`
+
+// setupRoutingFiles writes routing config files and points the config
+// loaders at them. Returns the rules file path.
+func setupRoutingFiles(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	rulesPath := filepath.Join(dir, "prefilter_rules.yaml")
+	regPath := filepath.Join(dir, "tool_registry.yaml")
+	writeFile(t, rulesPath, reloadRulesYAML)
+	writeFile(t, regPath, reloadRegistryYAML)
+	t.Setenv("PREFILTER_RULES_PATH", rulesPath)
+	t.Setenv("TOOL_REGISTRY_PATH", regPath)
+	config.ResetPreFilterConfig()
+	config.ResetToolRoutingRegistry()
+	t.Cleanup(func() {
+		config.ResetPreFilterConfig()
+		config.ResetToolRoutingRegistry()
+	})
+	return rulesPath
+}
+
+// newReloadRouter returns a router whose handlers use reloader.
+func newReloadRouter(reloader *RoutingReloader) *gin.Engine {
+	router := gin.New()
+	RegisterRoutes(router.Group("/v1"), NewHandlers(NewService(DefaultServiceConfig())).WithRoutingReloader(reloader))
+	return router
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newReloadTestPreFilter builds a pre-filter from the current config files.
+func newReloadTestPreFilter(t *testing.T) *routing.PreFilter {
+	t.Helper()
+	cfg, err := config.GetPreFilterConfig(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return routing.NewPreFilter(nil, cfg, slog.Default(), nil)
+}
+
+func forcedTool(pf *routing.PreFilter, query string) string {
+	specs := []routing.ToolSpec{{Name: "get_call_chain"}, {Name: "find_path"}, {Name: "answer"}}
+	return pf.Filter(context.Background(), query, specs, nil).ForcedTool
+}
+
+func TestHandleReloadRouting_AppliesValidConfig(t *testing.T) {
+	rulesPath := setupRoutingFiles(t)
+	pf := newReloadTestPreFilter(t)
+	router := newReloadRouter(NewRoutingReloader(pf, slog.Default()))
+
+	if got := forcedTool(pf, "show the call chain from main"); got != "get_call_chain" {
+		t.Fatalf("before reload: forced tool = %q", got)
+	}
+
+	writeFile(t, rulesPath, `
+enabled: true
+max_candidates: 8
+forced_mappings:
+  - patterns: ["call chain from"]
+    tool: find_path
+    reason: reloaded
+  - patterns: ["who calls"]
+    tool: get_call_chain
+    reason: reloaded
+`)
+	before := config.RoutingConfigGeneration()
+	w := postJSON(router, "/v1/trace/admin/reload-routing", `{}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp RoutingReloadResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Generation != before+1 || config.RoutingConfigGeneration() != resp.Generation {
+		t.Errorf("generation = %d, want %d", resp.Generation, before+1)
+	}
+	if resp.Changes.ForcedMappingsAdded != 2 || resp.Changes.ForcedMappingsRemoved != 1 {
+		t.Errorf("changes = %+v", resp.Changes)
+	}
+	if !strings.Contains(resp.Summary, "forced mappings +2/-1") || !strings.Contains(resp.Summary, "max_candidates") {
+		t.Errorf("summary = %q", resp.Summary)
+	}
+	if !resp.PreFilterActive || resp.PreFilterSource != rulesPath || resp.ToolCount != 2 {
+		t.Errorf("response = %+v", resp)
+	}
+	if got := forcedTool(pf, "show the call chain from main"); got != "find_path" {
+		t.Errorf("after reload: forced tool = %q, want find_path", got)
+	}
+	if cfg, _ := config.GetPreFilterConfig(context.Background()); cfg.MaxCandidates != 8 {
+		t.Errorf("config singleton not swapped: max_candidates = %d", cfg.MaxCandidates)
+	}
+}
+
+func TestHandleReloadRouting_RejectsInvalidConfig(t *testing.T) {
+	rulesPath := setupRoutingFiles(t)
+	pf := newReloadTestPreFilter(t)
+	router := newReloadRouter(NewRoutingReloader(pf, slog.Default()))
+
+	writeFile(t, rulesPath, `
+enabled: true
+scoring_mode: fastest
+forced_mappings:
+  - patterns: ["call chain (.*"]
+    tool: find_path
+`)
+	before := config.RoutingConfigGeneration()
+	w := postJSON(router, "/v1/trace/admin/reload-routing", `{}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var problem apierror.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatal(err)
+	}
+	if problem.Code != apierror.CodeInvalidRoutingConfig {
+		t.Errorf("code = %s", problem.Code)
+	}
+	if !strings.Contains(problem.Detail, "scoring_mode") || !strings.Contains(problem.Detail, "invalid regex") {
+		t.Errorf("detail should list every problem: %q", problem.Detail)
+	}
+
+	if config.RoutingConfigGeneration() != before {
+		t.Error("a rejected reload must not change the generation")
+	}
+	if got := forcedTool(pf, "show the call chain from main"); got != "get_call_chain" {
+		t.Errorf("old rules should be kept: forced tool = %q", got)
+	}
+}
+
+func TestHandleReloadRouting_NotConfigured(t *testing.T) {
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+
+	w := postJSON(router, "/v1/trace/admin/reload-routing", `{}`)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), string(apierror.CodeRoutingReloadNotAvailable)) {
+		t.Errorf("body = %s", w.Body.String())
+	}
+}
+
+func TestHandleReembedRouting(t *testing.T) {
+	t.Setenv("EMBEDDING_SERVICE_URL", "http://127.0.0.1:1/api/embed")
+	setupRoutingFiles(t)
+
+	w := postJSON(setupTestRouter(NewService(DefaultServiceConfig())), "/v1/trace/admin/routing/reembed", `{}`)
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), string(apierror.CodeRoutingReembedNotAvailable)) {
+		t.Fatalf("not configured: status = %d: %s", w.Code, w.Body.String())
+	}
+
+	pf := newReloadTestPreFilter(t)
+	router := newReloadRouter(NewRoutingReloader(pf, slog.Default()))
+	w = postJSON(router, "/v1/trace/admin/routing/reembed", `{}`)
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "no tools have been routed yet") {
+		t.Fatalf("before routing: status = %d: %s", w.Code, w.Body.String())
+	}
+
+	specs := []routing.ToolSpec{{Name: "get_call_chain"}, {Name: "find_symbol"}, {Name: "answer"}}
+	pf.Filter(context.Background(), "where is the config parsed", specs, nil)
+
+	w = postJSON(router, "/v1/trace/admin/routing/reembed", `{}`)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var resp RoutingReembedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != "queued" || resp.ToolCount != len(specs) || resp.CorpusHash == "" {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestRoutingReloader_WithoutPreFilter(t *testing.T) {
+	setupRoutingFiles(t)
+	reloader := NewRoutingReloader(nil, slog.Default())
+
+	resp, err := reloader.Reload(context.Background())
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if resp.PreFilterActive {
+		t.Error("PreFilterActive should be false without a pre-filter")
+	}
+	if strings.Join(resp.RestartRequired, ",") != "enabled" {
+		t.Errorf("RestartRequired = %v, want [enabled]", resp.RestartRequired)
+	}
+}
+
+func TestRoutingReloader_Watch(t *testing.T) {
+	rulesPath := setupRoutingFiles(t)
+	pf := newReloadTestPreFilter(t)
+	reloader := NewRoutingReloader(pf, slog.Default())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if err := reloader.Watch(ctx, 20*time.Millisecond); err != nil {
+		t.Fatalf("Watch: %v", err)
+	}
+
+	before := config.RoutingConfigGeneration()
+	writeFile(t, rulesPath, strings.Replace(reloadRulesYAML, "tool: get_call_chain", "tool: find_path", 1))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for config.RoutingConfigGeneration() == before {
+		if time.Now().After(deadline) {
+			t.Fatal("config was not reloaded after the file changed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := forcedTool(pf, "show the call chain from main"); got != "find_path" {
+		t.Errorf("after watch reload: forced tool = %q, want find_path", got)
+	}
+}
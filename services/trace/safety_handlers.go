@@ -0,0 +1,143 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	defaultSafetyGateOnce sync.Once
+	defaultSafetyGate     *safety.DefaultGate
+)
+
+// safetyEvaluator returns the configured gate, or a shared gate with the
+// default policy.
+func (h *Handlers) safetyEvaluator() safety.Evaluator {
+	if h.safetyGate != nil {
+		return h.safetyGate
+	}
+	defaultSafetyGateOnce.Do(func() {
+		defaultSafetyGate = safety.NewPolicyGate(nil)
+	})
+	return defaultSafetyGate
+}
+
+// HandleSafetyEvaluate explains how the safety policy treats a tool call.
+//
+// Description:
+//
+//	POST /v1/trace/safety/evaluate. Dry run: evaluates the proposed call
+//	against the policy and returns the decision with the matched rules.
+//	The call is never executed. A denial is still a 200 response; read
+//	decision.allowed.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleSafetyEvaluate(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleSafetyEvaluate")
+
+	var req SafetyEvaluateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body")
+		return
+	}
+
+	decision, err := h.safetyEvaluator().Evaluate(c.Request.Context(), safety.ToolCall{
+		Tool:   req.Tool,
+		Mode:   req.Mode,
+		Params: req.Params,
+		Plan:   req.Plan,
+	})
+	if err != nil {
+		logger.Error("Failed to evaluate safety policy", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to evaluate safety policy")
+		return
+	}
+
+	logger.Info("Evaluated safety policy",
+		"tool", decision.Tool,
+		"mode", decision.Mode,
+		"decision", decision.Decision,
+		"rule_id", decision.RuleID,
+	)
+	c.JSON(http.StatusOK, SafetyEvaluateResponse{
+		Decision:  decision,
+		LatencyMs: time.Since(start).Milliseconds(),
+	})
+}
+
+// checkPlanBlastRadius evaluates a change plan against the policy's blast
+// radius limits.
+//
+// Outputs:
+//
+//	*safety.Decision - The decision; Allowed is false if the plan is too large.
+//	error - Non-nil if evaluation failed.
+func (h *Handlers) checkPlanBlastRadius(ctx context.Context, plan *coordinate.ChangePlan) (*safety.Decision, error) {
+	symbols := make(map[string]struct{})
+	for _, fc := range plan.FileChanges {
+		if fc.SymbolID != "" {
+			symbols[fc.SymbolID] = struct{}{}
+		}
+	}
+	return h.safetyEvaluator().Evaluate(ctx, safety.ToolCall{
+		Tool: safety.PlanToolName,
+		Plan: &safety.PlanImpact{
+			FilesTouched:    plan.TotalFiles,
+			SymbolsModified: len(symbols),
+		},
+	})
+}
+
+// checkPlanApply evaluates writing a change plan to disk against the safety
+// policy. Path rules see the plan's files under the "files" parameter. The
+// tool rules are always the default run mode's: plans are stored without a
+// trusted run mode, so the mode is not the caller's to choose.
+//
+// Outputs:
+//
+//	*safety.Decision - The decision; Allowed is false if a rule denies it.
+//	error - Non-nil if evaluation failed.
+func (h *Handlers) checkPlanApply(ctx context.Context, plan *coordinate.ChangePlan) (*safety.Decision, error) {
+	files := make([]string, 0, len(plan.Order))
+	seen := make(map[string]struct{})
+	symbols := make(map[string]struct{})
+	for _, fc := range plan.FileChanges {
+		if _, ok := seen[fc.FilePath]; !ok {
+			seen[fc.FilePath] = struct{}{}
+			files = append(files, fc.FilePath)
+		}
+		if fc.SymbolID != "" {
+			symbols[fc.SymbolID] = struct{}{}
+		}
+	}
+	return h.safetyEvaluator().Evaluate(ctx, safety.ToolCall{
+		Tool:   safety.ApplyPlanToolName,
+		Mode:   safety.DefaultRunMode,
+		Params: map[string]any{"files": files},
+		Plan: &safety.PlanImpact{
+			FilesTouched:    len(files),
+			SymbolsModified: len(symbols),
+		},
+	})
+}
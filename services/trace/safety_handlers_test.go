@@ -0,0 +1,111 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
+	"github.com/gin-gonic/gin"
+)
+
+func setupSafetyTestRouter(t *testing.T, policyYAML string) (*gin.Engine, *Handlers) {
+	t.Helper()
+	policy, err := safety.ParsePolicy([]byte(policyYAML))
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	handlers := NewHandlers(NewService(DefaultServiceConfig())).WithSafetyGate(safety.NewPolicyGate(policy))
+	router := gin.New()
+	RegisterRoutes(router.Group("/v1"), handlers)
+	return router, handlers
+}
+
+func TestHandlers_HandleSafetyEvaluate(t *testing.T) {
+	router, _ := setupSafetyTestRouter(t, "tools:\n  readonly:\n    deny: [write_file]\n")
+
+	tests := []struct {
+		name     string
+		body     string
+		allowed  bool
+		wantRule string
+	}{
+		{"denied", `{"tool": "write_file", "mode": "readonly", "params": {"path": "a.go"}}`, false, "tools.readonly.deny[0]"},
+		{"allowed", `{"tool": "find_callers", "mode": "readonly"}`, true, safety.RuleDefaultAllow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("POST", "/v1/trace/safety/evaluate", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var resp SafetyEvaluateResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if resp.Decision.Allowed != tt.allowed || resp.Decision.RuleID != tt.wantRule {
+				t.Errorf("decision = %+v, want allowed=%v rule=%s", resp.Decision, tt.allowed, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestHandlers_HandleSafetyEvaluate_InvalidRequest(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	router := setupTestRouter(svc)
+
+	req, _ := http.NewRequest("POST", "/v1/trace/safety/evaluate", bytes.NewBufferString(`{"mode": "edit"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestHandlers_CheckPlanBlastRadius(t *testing.T) {
+	_, handlers := setupSafetyTestRouter(t, "blast_radius:\n  max_files: 2\n  max_symbols: 1\n")
+
+	plan := &coordinate.ChangePlan{
+		TotalFiles: 2,
+		FileChanges: []coordinate.FileChange{
+			{FilePath: "a.go", SymbolID: "a.go:1:Foo"},
+			{FilePath: "b.go", SymbolID: "a.go:1:Foo"},
+		},
+	}
+	d, err := handlers.checkPlanBlastRadius(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("checkPlanBlastRadius: %v", err)
+	}
+	if !d.Allowed {
+		t.Errorf("plan within limits denied: %+v", d)
+	}
+
+	plan.FileChanges = append(plan.FileChanges, coordinate.FileChange{FilePath: "b.go", SymbolID: "b.go:3:Bar"})
+	d, err = handlers.checkPlanBlastRadius(context.Background(), plan)
+	if err != nil {
+		t.Fatalf("checkPlanBlastRadius: %v", err)
+	}
+	if d.Allowed || d.RuleID != safety.RuleBlastRadiusSymbols {
+		t.Errorf("decision = %+v, want deny by %s", d, safety.RuleBlastRadiusSymbols)
+	}
+}
@@ -0,0 +1,372 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// viewNamePattern is the form of a view name, as used in the scope
+// parameter and the /views/:name path.
+var viewNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// GraphView describes a named scoped view of a cached graph.
+type GraphView struct {
+	// Name identifies the view in scope parameters.
+	Name string `json:"name"`
+
+	// GraphID is the graph the view restricts.
+	GraphID string `json:"graph_id"`
+
+	// Include and Exclude are the file globs of the scope.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Boundary is how edges crossing the scope are shown.
+	Boundary graph.BoundaryMode `json:"boundary"`
+
+	// NodeCount is the number of nodes in scope.
+	NodeCount int `json:"node_count"`
+
+	// BoundaryNodeCount is the number of nodes outside the scope shown
+	// because a visible edge reaches them.
+	BoundaryNodeCount int `json:"boundary_node_count"`
+
+	// EdgeCount is the number of visible edges.
+	EdgeCount int `json:"edge_count"`
+
+	// CreatedAtMilli is when the view was created (Unix milliseconds UTC).
+	CreatedAtMilli int64 `json:"created_at"`
+}
+
+// registeredView is a view with the graph it was computed over.
+//
+// The scoped graph and index for agent sessions are materialized on first
+// use, once per registeredView; a refreshed graph gets a new one.
+type registeredView struct {
+	info  GraphView
+	graph *graph.Graph
+	view  *graph.ScopedView
+
+	materializeOnce sync.Once
+	materialized    *CachedGraph
+	materializeErr  error
+}
+
+// newRegisteredView computes the view info over g.
+func newRegisteredView(info GraphView, g *graph.Graph) (*registeredView, error) {
+	view, err := graph.NewScopedView(g, info.Include, info.Exclude, info.Boundary)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidView, err)
+	}
+	info.NodeCount, info.BoundaryNodeCount = view.ScopeSize()
+	info.EdgeCount = view.EdgeCount()
+	return &registeredView{info: info, graph: g, view: view}, nil
+}
+
+// CreateView creates or replaces a named scoped view of a cached graph.
+//
+// Description:
+//
+//	The view restricts the graph to the files matching include and not
+//	exclude (see graph.ScopedView). Nothing is rebuilt or copied: the
+//	view records which node IDs are in scope and on the boundary. When
+//	the graph is refreshed the view is recomputed on next use.
+//
+// Inputs:
+//
+//	name - The view name: letters, digits, '.', '_' and '-', at most 64.
+//	graphID - The cached graph to restrict.
+//	include, exclude - File globs relative to the project root.
+//	boundary - "placeholder" (default) or "hide".
+//
+// Outputs:
+//
+//	GraphView - The view.
+//	error - ErrInvalidView for a bad name, patterns or boundary mode;
+//	  ErrGraphNotInitialized or ErrGraphExpired for the graph.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) CreateView(name, graphID string, include, exclude []string, boundary string) (GraphView, error) {
+	if !viewNamePattern.MatchString(name) {
+		return GraphView{}, fmt.Errorf("%w: name %q must be 1-64 letters, digits, '.', '_' or '-'", ErrInvalidView, name)
+	}
+	mode, err := graph.ParseBoundaryMode(boundary)
+	if err != nil {
+		return GraphView{}, fmt.Errorf("%w: %v", ErrInvalidView, err)
+	}
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return GraphView{}, err
+	}
+
+	rv, err := newRegisteredView(GraphView{
+		Name:           name,
+		GraphID:        graphID,
+		Include:        append([]string(nil), include...),
+		Exclude:        append([]string(nil), exclude...),
+		Boundary:       mode,
+		CreatedAtMilli: time.Now().UnixMilli(),
+	}, cached.Graph)
+	if err != nil {
+		return GraphView{}, err
+	}
+
+	s.viewsMu.Lock()
+	s.views[name] = rv
+	s.viewsMu.Unlock()
+	return rv.info, nil
+}
+
+// GetView returns a view by name.
+//
+// Outputs:
+//
+//	GraphView - The view.
+//	error - ErrViewNotFound if there is no view with that name.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) GetView(name string) (GraphView, error) {
+	s.viewsMu.RLock()
+	defer s.viewsMu.RUnlock()
+	rv, ok := s.views[name]
+	if !ok {
+		return GraphView{}, fmt.Errorf("%w: %q", ErrViewNotFound, name)
+	}
+	return rv.info, nil
+}
+
+// ListViews returns all views, sorted by name.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ListViews() []GraphView {
+	s.viewsMu.RLock()
+	defer s.viewsMu.RUnlock()
+	views := make([]GraphView, 0, len(s.views))
+	for _, rv := range s.views {
+		views = append(views, rv.info)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	return views
+}
+
+// DeleteView removes a view.
+//
+// Outputs:
+//
+//	error - ErrViewNotFound if there is no view with that name.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) DeleteView(name string) error {
+	s.viewsMu.Lock()
+	defer s.viewsMu.Unlock()
+	if _, ok := s.views[name]; !ok {
+		return fmt.Errorf("%w: %q", ErrViewNotFound, name)
+	}
+	delete(s.views, name)
+	return nil
+}
+
+// resolveView returns a view over the current version of its graph.
+//
+// Description:
+//
+//	If the cached graph was replaced since the view was computed (a
+//	refresh or rebuild), the view is recomputed over the new graph.
+//
+// Outputs:
+//
+//	*registeredView - The view.
+//	error - ErrViewNotFound, or the error of GetGraph for the view's
+//	  graph.
+func (s *Service) resolveView(name string) (*registeredView, error) {
+	s.viewsMu.RLock()
+	rv, ok := s.views[name]
+	s.viewsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrViewNotFound, name)
+	}
+
+	cached, err := s.GetGraph(rv.info.GraphID)
+	if err != nil {
+		return nil, err
+	}
+	if cached.Graph == rv.graph {
+		return rv, nil
+	}
+
+	s.viewsMu.Lock()
+	defer s.viewsMu.Unlock()
+	current, ok := s.views[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrViewNotFound, name)
+	}
+	// The view may have been replaced or recomputed meanwhile.
+	if current.graph == cached.Graph {
+		return current, nil
+	}
+	rv = current
+	fresh, err := newRegisteredView(rv.info, cached.Graph)
+	if err != nil {
+		return nil, err
+	}
+	s.views[name] = fresh
+	return fresh, nil
+}
+
+// ScopeFilter returns the scoped view named scope for a query on graphID.
+//
+// Outputs:
+//
+//	*graph.ScopedView - The view; nil if scope is empty.
+//	error - ErrViewNotFound; ErrInvalidView if the view is over another
+//	  graph; or the error of GetGraph.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ScopeFilter(graphID, scope string) (*graph.ScopedView, error) {
+	if scope == "" {
+		return nil, nil
+	}
+	rv, err := s.viewForGraph(graphID, scope)
+	if err != nil {
+		return nil, err
+	}
+	return rv.view, nil
+}
+
+// viewForGraph resolves the view named scope and checks it is over graphID.
+func (s *Service) viewForGraph(graphID, scope string) (*registeredView, error) {
+	rv, err := s.resolveView(scope)
+	if err != nil {
+		return nil, err
+	}
+	if rv.info.GraphID != graphID {
+		return nil, fmt.Errorf("%w: view %q is over graph %s, not %s", ErrInvalidView, scope, rv.info.GraphID, graphID)
+	}
+	return rv, nil
+}
+
+// ScopedGraph returns a copy of cached restricted to the view named scope.
+//
+// Description:
+//
+//	For agent sessions, whose tools take a *graph.Graph and an index. The
+//	scoped graph and index are materialized on first use of the view and
+//	shared by all sessions using it. The content hash is suffixed with the
+//	view so cached tool results of the full graph are not reused.
+//
+// Inputs:
+//
+//	scope - The view name.
+//	graphID - The graph the session uses.
+//	cached - The cached graph with that ID.
+//
+// Outputs:
+//
+//	*CachedGraph - The scoped copy.
+//	error - As ScopeFilter, or non-nil if materializing fails.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ScopedGraph(scope, graphID string, cached *CachedGraph) (*CachedGraph, error) {
+	rv, err := s.viewForGraph(graphID, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	rv.materializeOnce.Do(func() {
+		sub, err := rv.view.Materialize()
+		if err != nil {
+			rv.materializeErr = err
+			return
+		}
+		scoped := *cached
+		scoped.Graph = sub
+		scoped.Assembler = nil
+		scoped.Adapter = nil
+		scoped.ContentHash = cached.ContentHash + "#view=" + rv.info.Name
+		if cached.Index != nil {
+			idx, err := rv.view.FilterIndex(cached.Index)
+			if err != nil {
+				rv.materializeErr = err
+				return
+			}
+			scoped.Index = idx
+		}
+		rv.materialized = &scoped
+	})
+	return rv.materialized, rv.materializeErr
+}
+
+// filterSymbolsByScope keeps symbols visible in view, up to limit.
+func filterSymbolsByScope(symbols []*SymbolInfo, view *graph.ScopedView, limit int) []*SymbolInfo {
+	filtered := make([]*SymbolInfo, 0, len(symbols))
+	for _, sym := range symbols {
+		if sym != nil && view.HasNode(sym.ID) {
+			filtered = append(filtered, sym)
+			if len(filtered) == limit {
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// filterReferencesByScope keeps references located in files in scope, up
+// to limit.
+func filterReferencesByScope(refs []ReferenceInfo, view *graph.ScopedView, limit int) []ReferenceInfo {
+	filtered := make([]ReferenceInfo, 0, len(refs))
+	for _, ref := range refs {
+		if view.ContainsFile(ref.FilePath) {
+			filtered = append(filtered, ref)
+			if len(filtered) == limit {
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// filterSymbolResults applies the scope and root filters of a query, when
+// set, and limits the result.
+func filterSymbolResults(symbols []*SymbolInfo, view *graph.ScopedView, root string, limit int) []*SymbolInfo {
+	if view != nil {
+		scopeLimit := limit
+		if root != "" {
+			scopeLimit = len(symbols)
+		}
+		symbols = filterSymbolsByScope(symbols, view, scopeLimit)
+	}
+	if root != "" {
+		symbols = filterSymbolsByRoot(symbols, root, limit)
+	}
+	return symbols
+}
+
+// filterReferenceResults is filterSymbolResults for references.
+func filterReferenceResults(refs []ReferenceInfo, view *graph.ScopedView, root string, limit int) []ReferenceInfo {
+	if view != nil {
+		scopeLimit := limit
+		if root != "" {
+			scopeLimit = len(refs)
+		}
+		refs = filterReferencesByScope(refs, view, scopeLimit)
+	}
+	if root != "" {
+		refs = filterReferencesByRoot(refs, root, limit)
+	}
+	return refs
+}
@@ -0,0 +1,221 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+)
+
+// writeMonorepo writes a Go module with a payment and an orders service
+// sharing a store interface. orders calls into payment.
+func writeMonorepo(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/shop\n\ngo 1.22\n",
+		"store/store.go": `package store
+
+type Store interface {
+	Save(key string) error
+}
+`,
+		"services/payment/charge.go": `package payment
+
+type PaymentStore struct{}
+
+func (p *PaymentStore) Save(key string) error { return nil }
+
+func Charge(amount int) error {
+	return validate(amount)
+}
+
+func validate(amount int) error { return nil }
+`,
+		"services/orders/order.go": `package orders
+
+import "example.com/shop/services/payment"
+
+type OrderStore struct{}
+
+func (o *OrderStore) Save(key string) error { return nil }
+
+func PlaceOrder() error {
+	return payment.Charge(10)
+}
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestService_Views(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	root := writeMonorepo(t)
+	resp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	view, err := svc.CreateView("payment", resp.GraphID, []string{"services/payment/**"}, nil, "")
+	if err != nil {
+		t.Fatalf("CreateView: %v", err)
+	}
+	if view.Boundary != "placeholder" || view.NodeCount == 0 {
+		t.Errorf("view = %+v", view)
+	}
+
+	if _, err := svc.CreateView("bad name!", resp.GraphID, []string{"**"}, nil, ""); !errors.Is(err, ErrInvalidView) {
+		t.Errorf("bad name: error = %v, want ErrInvalidView", err)
+	}
+	if _, err := svc.CreateView("x", resp.GraphID, []string{"["}, nil, ""); !errors.Is(err, ErrInvalidView) {
+		t.Errorf("bad glob: error = %v, want ErrInvalidView", err)
+	}
+	if _, err := svc.CreateView("x", "missing", []string{"**"}, nil, ""); !errors.Is(err, ErrGraphNotInitialized) {
+		t.Errorf("missing graph: error = %v, want ErrGraphNotInitialized", err)
+	}
+
+	if _, err := svc.ScopeFilter("other-graph", "payment"); !errors.Is(err, ErrInvalidView) {
+		t.Errorf("ScopeFilter on another graph = %v, want ErrInvalidView", err)
+	}
+
+	cached, _ := svc.GetGraph(resp.GraphID)
+	scoped, err := svc.ScopedGraph("payment", resp.GraphID, cached)
+	if err != nil {
+		t.Fatalf("ScopedGraph: %v", err)
+	}
+	if scoped.Graph.NodeCount() >= cached.Graph.NodeCount() || scoped.ContentHash == cached.ContentHash {
+		t.Errorf("scoped graph has %d of %d nodes, hash %q", scoped.Graph.NodeCount(), cached.Graph.NodeCount(), scoped.ContentHash)
+	}
+	if len(scoped.Index.GetByName("Charge")) != 1 || len(scoped.Index.GetByName("OrderStore")) != 0 {
+		t.Error("scoped index should hold Charge and not OrderStore")
+	}
+	if again, _ := svc.ScopedGraph("payment", resp.GraphID, cached); again != scoped {
+		t.Error("ScopedGraph materialized twice")
+	}
+
+	if err := svc.DeleteView("payment"); err != nil {
+		t.Fatalf("DeleteView: %v", err)
+	}
+	if _, err := svc.GetView("payment"); !errors.Is(err, ErrViewNotFound) {
+		t.Errorf("GetView after delete = %v, want ErrViewNotFound", err)
+	}
+}
+
+func TestHandlers_Views(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+	root := writeMonorepo(t)
+	resp, err := svc.Init(context.Background(), root, nil, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	router := setupTestRouter(svc)
+
+	do := func(method, url string, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			_ = json.NewEncoder(&buf).Encode(body)
+		}
+		req, _ := http.NewRequest(method, url, &buf)
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	decodeCode := func(w *httptest.ResponseRecorder) apierror.Code {
+		var problem apierror.Problem
+		_ = json.Unmarshal(w.Body.Bytes(), &problem)
+		return problem.Code
+	}
+
+	for _, boundary := range []string{"placeholder", "hide"} {
+		w := do("POST", "/v1/trace/views", CreateViewRequest{
+			Name: "payment-" + boundary, GraphID: resp.GraphID,
+			Include: []string{"services/payment/**"}, Boundary: boundary,
+		})
+		if w.Code != http.StatusCreated {
+			t.Fatalf("create %s view: status %d: %s", boundary, w.Code, w.Body.String())
+		}
+	}
+
+	w := do("POST", "/v1/trace/views", CreateViewRequest{Name: "bad", GraphID: resp.GraphID, Boundary: "fold"})
+	if w.Code != http.StatusBadRequest || decodeCode(w) != apierror.CodeInvalidParameter {
+		t.Errorf("invalid view: status %d code %s", w.Code, decodeCode(w))
+	}
+
+	w = do("GET", "/v1/trace/views", nil)
+	var list ListViewsResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil || len(list.Views) != 2 || list.Views[0].Name != "payment-hide" {
+		t.Errorf("list views = %s", w.Body.String())
+	}
+
+	callers := func(scope string) []*SymbolInfo {
+		t.Helper()
+		w := do("GET", "/v1/trace/callers?graph_id="+resp.GraphID+"&function=Charge&scope="+scope, nil)
+		if w.Code != http.StatusOK {
+			t.Fatalf("callers scope=%s: status %d: %s", scope, w.Code, w.Body.String())
+		}
+		var out CallersResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		return out.Callers
+	}
+	all := callers("")
+	if len(all) == 0 {
+		t.Skip("builder did not resolve the cross-package call")
+	}
+	if got := callers("payment-placeholder"); len(got) != len(all) {
+		t.Errorf("placeholder scope: %d callers, want %d (boundary caller kept)", len(got), len(all))
+	}
+	if got := callers("payment-hide"); len(got) != 0 {
+		t.Errorf("hide scope: callers = %+v, want none", got)
+	}
+
+	// The store interface lives outside the scope but is implemented in it.
+	w = do("GET", "/v1/trace/implementations?graph_id="+resp.GraphID+"&interface=Store&scope=payment-hide", nil)
+	var impls ImplementationsResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &impls)
+	for _, impl := range impls.Implementations {
+		if impl.Name == "OrderStore" {
+			t.Errorf("hide scope returned OrderStore outside the scope")
+		}
+	}
+
+	w = do("GET", "/v1/trace/callees?graph_id="+resp.GraphID+"&function=Charge&scope=nope", nil)
+	if w.Code != http.StatusBadRequest || decodeCode(w) != apierror.CodeInvalidParameter {
+		t.Errorf("unknown scope: status %d code %s", w.Code, decodeCode(w))
+	}
+
+	if w = do("DELETE", "/v1/trace/views/payment-hide", nil); w.Code != http.StatusNoContent {
+		t.Errorf("delete: status %d", w.Code)
+	}
+	if w = do("GET", "/v1/trace/views/payment-hide", nil); w.Code != http.StatusNotFound || decodeCode(w) != apierror.CodeViewNotFound {
+		t.Errorf("get deleted view: status %d code %s", w.Code, decodeCode(w))
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package semindex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultEndpoint is the local Ollama embedding endpoint.
+const DefaultEndpoint = "http://localhost:11434/api/embed"
+
+// Embedder turns texts into embedding vectors.
+type Embedder interface {
+	// Embed returns one vector per text, in order.
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// EndpointAllowed reports whether embedding text may be sent to endpoint.
+//
+// Description:
+//
+//	A loopback endpoint (localhost, 127.0.0.0/8, ::1) is always allowed.
+//	Any other host is allowed only when allowRemote is set explicitly and
+//	localOnly (the egress config's TRACE_LOCAL_ONLY) is not, since the
+//	embedded text is project source.
+//
+// Outputs:
+//
+//	error - ErrEndpointNotAllowed, or a parse error, when the endpoint
+//	  may not be used.
+//
+// Thread Safety: Safe for concurrent use.
+func EndpointAllowed(endpoint string, allowRemote, localOnly bool) error {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid embedding endpoint %q", endpoint)
+	}
+	if isLoopback(u.Hostname()) {
+		return nil
+	}
+	if !allowRemote {
+		return fmt.Errorf("%w: %s is not on localhost and remote embedding is not enabled", ErrEndpointNotAllowed, u.Host)
+	}
+	if localOnly {
+		return fmt.Errorf("%w: %s is not on localhost and the egress config is local-only", ErrEndpointNotAllowed, u.Host)
+	}
+	return nil
+}
+
+// isLoopback reports whether host names the local machine.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ollamaEmbedReq is the Ollama /api/embed request body.
+type ollamaEmbedReq struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+// ollamaEmbedResp is the Ollama /api/embed response body.
+type ollamaEmbedResp struct {
+	Embeddings [][]float32 `json:"embeddings"`
+}
+
+// OllamaEmbedder is an Embedder calling Ollama's /api/embed endpoint
+// with batched input.
+//
+// Thread Safety: Safe for concurrent use.
+type OllamaEmbedder struct {
+	endpoint string
+	model    string
+	client   *http.Client
+}
+
+// NewOllamaEmbedder creates an embedder for model at endpoint.
+//
+// Inputs:
+//
+//	endpoint - The /api/embed URL. Empty uses DefaultEndpoint.
+//	model - The embedding model name.
+//
+// Outputs:
+//
+//	*OllamaEmbedder - The embedder. Never nil.
+func NewOllamaEmbedder(endpoint, model string) *OllamaEmbedder {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	return &OllamaEmbedder{
+		endpoint: endpoint,
+		model:    model,
+		client:   &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// Endpoint returns the URL texts are sent to.
+func (e *OllamaEmbedder) Endpoint() string {
+	return e.endpoint
+}
+
+// Embed implements Embedder.
+func (e *OllamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	body, err := json.Marshal(ollamaEmbedReq{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("marshal embed request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create embed request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrEmbeddingUnavailable, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read embed response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: embed service returned %d: %s", ErrEmbeddingUnavailable, resp.StatusCode, raw)
+	}
+
+	var out ollamaEmbedResp
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("parse embed response: %w", err)
+	}
+	if len(out.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("embed service returned %d embeddings for %d inputs", len(out.Embeddings), len(texts))
+	}
+	for _, vec := range out.Embeddings {
+		if len(vec) == 0 {
+			return nil, fmt.Errorf("embed service returned empty vector")
+		}
+	}
+	return out.Embeddings, nil
+}
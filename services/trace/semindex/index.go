@@ -0,0 +1,592 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package semindex embeds the functions and methods of a graph with a
+// local embedding model and answers natural language queries by cosine
+// similarity over the vectors, falling back to keyword matching when the
+// model is unavailable.
+package semindex
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// Defaults for zero Config fields.
+const (
+	DefaultRatePerMinute = 120
+	DefaultBatchSize     = 16
+	DefaultMaxSymbols    = 20000
+	DefaultMaxBodyBytes  = 1500
+	DefaultMaxCandidates = 50000
+	DefaultCallTimeout   = time.Minute
+	DefaultQueryTimeout  = 5 * time.Second
+)
+
+// maxConsecutiveFailures is the number of failed embedding requests in a
+// row after which a run stops with StateUnavailable.
+const maxConsecutiveFailures = 3
+
+// Run states reported in GraphStatus.State.
+const (
+	StateRunning     = "running"
+	StateComplete    = "complete"
+	StateCancelled   = "cancelled"
+	StateUnavailable = "unavailable"
+)
+
+var (
+	// ErrEndpointNotAllowed indicates an embedding endpoint off localhost
+	// that the configuration does not explicitly allow.
+	ErrEndpointNotAllowed = errors.New("embedding endpoint not allowed")
+
+	// ErrEmbeddingUnavailable indicates the embedding model could not be
+	// reached or refused the request.
+	ErrEmbeddingUnavailable = errors.New("embedding model unavailable")
+
+	// ErrEmptyQuery indicates a search without query text.
+	ErrEmptyQuery = errors.New("query must not be empty")
+)
+
+// Config configures an Index.
+type Config struct {
+	// Model is the embedding model name. Vectors are stored per model.
+	Model string
+
+	// Endpoint is the URL the Embedder sends text to. Empty means
+	// DefaultEndpoint.
+	Endpoint string
+
+	// AllowRemote permits an Endpoint off localhost. See EndpointAllowed.
+	AllowRemote bool
+
+	// LocalOnly blocks a remote Endpoint even with AllowRemote. Set from
+	// the egress config's LocalOnly.
+	LocalOnly bool
+
+	// RatePerMinute caps embedding requests per minute across all graphs
+	// while indexing. Queries are not limited. <= 0 uses
+	// DefaultRatePerMinute.
+	RatePerMinute int
+
+	// BatchSize is the number of symbols embedded per request. <= 0 uses
+	// DefaultBatchSize.
+	BatchSize int
+
+	// MaxSymbols caps the symbols indexed per graph. <= 0 uses
+	// DefaultMaxSymbols.
+	MaxSymbols int
+
+	// MaxBodyBytes caps the source embedded per symbol. <= 0 uses
+	// DefaultMaxBodyBytes.
+	MaxBodyBytes int
+
+	// MaxCandidates caps the vectors scored per query. <= 0 uses
+	// DefaultMaxCandidates.
+	MaxCandidates int
+
+	// CallTimeout bounds each indexing request. <= 0 uses
+	// DefaultCallTimeout.
+	CallTimeout time.Duration
+
+	// QueryTimeout bounds embedding a query. <= 0 uses
+	// DefaultQueryTimeout.
+	QueryTimeout time.Duration
+}
+
+// GraphStatus is the progress and coverage of one graph's index.
+type GraphStatus struct {
+	// GraphID is the indexed graph.
+	GraphID string `json:"graph_id"`
+
+	// State is StateRunning, StateComplete, StateCancelled or
+	// StateUnavailable.
+	State string `json:"state"`
+
+	// Candidates is the number of symbols to index.
+	Candidates int `json:"candidates"`
+
+	// QueueDepth is the number of candidates not yet processed.
+	QueueDepth int `json:"queue_depth"`
+
+	// Indexed is the number of candidates with a vector.
+	Indexed int `json:"indexed"`
+
+	// Embedded is the part of Indexed embedded by the model in this run.
+	Embedded int `json:"embedded"`
+
+	// FromStore is the part of Indexed loaded from the Store.
+	FromStore int `json:"from_store"`
+
+	// Failed is the number of candidates whose source could not be read
+	// or whose embedding request failed.
+	Failed int `json:"failed"`
+
+	// Coverage is Indexed / Candidates, 0 when there are no candidates.
+	Coverage float64 `json:"coverage"`
+
+	// LastError is the most recent failure, if any.
+	LastError string `json:"last_error,omitempty"`
+
+	// StartedAt and FinishedAt are Unix milliseconds. FinishedAt is 0
+	// while running.
+	StartedAt  int64 `json:"started_at"`
+	FinishedAt int64 `json:"finished_at,omitempty"`
+}
+
+// Status is the state of an Index, for GET /v1/trace/search/semantic/status.
+type Status struct {
+	// Enabled is false when no index is configured.
+	Enabled bool `json:"enabled"`
+
+	// Model embeds the symbols and queries.
+	Model string `json:"model,omitempty"`
+
+	// RatePerMinute is the indexing request cap.
+	RatePerMinute int `json:"rate_per_minute,omitempty"`
+
+	// Graphs holds the latest run per graph, in GraphID order.
+	Graphs []GraphStatus `json:"graphs,omitempty"`
+}
+
+// run is one graph's indexing run and the vectors it produced.
+type run struct {
+	cancel context.CancelFunc
+
+	// Guarded by Index.mu.
+	status  GraphStatus
+	ids     []string             // indexed symbol IDs, in queue order
+	vectors map[string][]float32 // symbol ID → unit vector
+	dim     int                  // dimension of vectors; 0 until the first
+}
+
+// Index embeds the functions and methods of graphs in the background and
+// searches them.
+//
+// Description:
+//
+//	Trigger enqueues a graph's functions and methods, most referenced
+//	first, and embeds them in batches under a shared rate limit. Each
+//	symbol is embedded from its signature, doc comment and truncated
+//	body (EmbeddingText). Vectors are looked up in and saved to the Store
+//	by VectorKey, so a rebuilt graph only pays for symbols whose code
+//	changed. Search ranks a graph's vectors by cosine similarity to the
+//	query and falls back to keyword matching while the graph has no
+//	vectors or the model is unavailable.
+//
+// Thread Safety: Safe for concurrent use.
+type Index struct {
+	embedder Embedder
+	store    Store
+	cfg      Config
+	limiter  *rate.Limiter
+	logger   *slog.Logger
+
+	mu     sync.RWMutex
+	runs   map[string]*run
+	closed bool
+}
+
+// New creates an index.
+//
+// Inputs:
+//
+//	embedder - Embeds symbols and queries. Must not be nil.
+//	store - Vector store. Nil uses a MemoryStore.
+//	cfg - Configuration. cfg.Model must be set and cfg.Endpoint must pass
+//	  EndpointAllowed.
+//
+// Outputs:
+//
+//	*Index - The index, empty until Trigger.
+//	error - ErrEndpointNotAllowed for an endpoint off localhost that the
+//	  configuration does not allow.
+func New(embedder Embedder, store Store, cfg Config) (*Index, error) {
+	if embedder == nil {
+		return nil, errors.New("semindex: embedder must not be nil")
+	}
+	if cfg.Model == "" {
+		return nil, errors.New("semindex: model must not be empty")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultEndpoint
+	}
+	if err := EndpointAllowed(cfg.Endpoint, cfg.AllowRemote, cfg.LocalOnly); err != nil {
+		return nil, err
+	}
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	if cfg.RatePerMinute <= 0 {
+		cfg.RatePerMinute = DefaultRatePerMinute
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.MaxSymbols <= 0 {
+		cfg.MaxSymbols = DefaultMaxSymbols
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	if cfg.MaxCandidates <= 0 {
+		cfg.MaxCandidates = DefaultMaxCandidates
+	}
+	if cfg.CallTimeout <= 0 {
+		cfg.CallTimeout = DefaultCallTimeout
+	}
+	if cfg.QueryTimeout <= 0 {
+		cfg.QueryTimeout = DefaultQueryTimeout
+	}
+	return &Index{
+		embedder: embedder,
+		store:    store,
+		cfg:      cfg,
+		limiter:  rate.NewLimiter(rate.Every(time.Minute/time.Duration(cfg.RatePerMinute)), 1),
+		logger:   slog.Default(),
+		runs:     make(map[string]*run),
+	}, nil
+}
+
+// Trigger starts indexing g in the background, replacing the vectors of
+// graphID and cancelling any run still in progress for it. g must be
+// frozen.
+//
+// Thread Safety: Safe for concurrent use.
+func (ix *Index) Trigger(graphID string, g *graph.Graph) {
+	if g == nil {
+		return
+	}
+	queue := Candidates(g, ix.cfg.MaxSymbols)
+
+	ix.mu.Lock()
+	if ix.closed {
+		ix.mu.Unlock()
+		return
+	}
+	if prev := ix.runs[graphID]; prev != nil {
+		prev.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &run{
+		cancel: cancel,
+		status: GraphStatus{
+			GraphID:    graphID,
+			State:      StateRunning,
+			Candidates: len(queue),
+			QueueDepth: len(queue),
+			StartedAt:  time.Now().UnixMilli(),
+		},
+		vectors: make(map[string][]float32, len(queue)),
+	}
+	ix.runs[graphID] = r
+	ix.mu.Unlock()
+
+	go ix.process(ctx, r, g.ProjectRoot, queue)
+}
+
+// Cancel stops the run in progress for graphID, if any. Vectors indexed
+// so far stay searchable.
+//
+// Thread Safety: Safe for concurrent use.
+func (ix *Index) Cancel(graphID string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if r := ix.runs[graphID]; r != nil {
+		r.cancel()
+	}
+}
+
+// Close cancels every run. Later Trigger calls do nothing.
+//
+// Thread Safety: Safe for concurrent use.
+func (ix *Index) Close() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.closed = true
+	for _, r := range ix.runs {
+		r.cancel()
+	}
+}
+
+// Status returns a snapshot of the index's progress.
+//
+// Thread Safety: Safe for concurrent use.
+func (ix *Index) Status() Status {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	st := Status{
+		Enabled:       true,
+		Model:         ix.cfg.Model,
+		RatePerMinute: ix.cfg.RatePerMinute,
+		Graphs:        make([]GraphStatus, 0, len(ix.runs)),
+	}
+	for _, r := range ix.runs {
+		st.Graphs = append(st.Graphs, r.status)
+	}
+	slices.SortFunc(st.Graphs, func(a, b GraphStatus) int { return cmp.Compare(a.GraphID, b.GraphID) })
+	return st
+}
+
+// Coverage returns the status of graphID's index. ok is false if the
+// graph was never triggered.
+//
+// Thread Safety: Safe for concurrent use.
+func (ix *Index) Coverage(graphID string) (GraphStatus, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	r := ix.runs[graphID]
+	if r == nil {
+		return GraphStatus{}, false
+	}
+	return r.status, true
+}
+
+// Candidates returns the nodes of g to embed, at most limit of them:
+// non-generated functions and methods, by descending in-degree and then
+// ID.
+//
+// Thread Safety: Safe for concurrent use on a frozen graph.
+func Candidates(g *graph.Graph, limit int) []*graph.Node {
+	var nodes []*graph.Node
+	for _, n := range g.Nodes() {
+		sym := n.Symbol
+		if sym == nil || sym.Generated {
+			continue
+		}
+		if sym.Kind != ast.SymbolKindFunction && sym.Kind != ast.SymbolKindMethod {
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	slices.SortFunc(nodes, func(a, b *graph.Node) int {
+		if c := cmp.Compare(len(b.Incoming), len(a.Incoming)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	if limit > 0 && len(nodes) > limit {
+		nodes = nodes[:limit]
+	}
+	return nodes
+}
+
+// EmbeddingText returns the text sym is embedded from: its kind and
+// qualified name, signature, doc comment and source, the source cut to
+// maxBodyBytes.
+//
+// Inputs:
+//
+//	projectRoot - The graph's project root.
+//	sym - The symbol. Must not be nil.
+//	maxBodyBytes - Source cap. <= 0 uses DefaultMaxBodyBytes.
+//
+// Outputs:
+//
+//	string - The text.
+//	error - Non-nil if the source could not be read.
+func EmbeddingText(projectRoot string, sym *ast.Symbol, maxBodyBytes int) (string, error) {
+	if maxBodyBytes <= 0 {
+		maxBodyBytes = DefaultMaxBodyBytes
+	}
+	src, err := graph.ReadSymbolSource(projectRoot, sym, 0, maxBodyBytes)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", sym.ID, err)
+	}
+	name := sym.Name
+	if sym.Receiver != "" {
+		name = sym.Receiver + "." + sym.Name
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s %s %s\n", sym.Language, sym.Kind, name)
+	if sym.Signature != "" {
+		sb.WriteString(sym.Signature)
+		sb.WriteByte('\n')
+	}
+	if doc := strings.TrimSpace(sym.DocComment); doc != "" {
+		sb.WriteString(doc)
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(src.Source)
+	return sb.String(), nil
+}
+
+// pendingSymbol is a candidate waiting for its embedding.
+type pendingSymbol struct {
+	id  string
+	key string
+}
+
+// process embeds queue in batches until done or ctx is cancelled.
+func (ix *Index) process(ctx context.Context, r *run, projectRoot string, queue []*graph.Node) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			ix.logger.Error("Panic in semantic indexing goroutine", slog.Any("panic", rec))
+			ix.finish(r, StateCancelled)
+		}
+	}()
+	defer r.cancel()
+
+	failures := 0
+	for start := 0; start < len(queue); start += ix.cfg.BatchSize {
+		if ctx.Err() != nil {
+			ix.finish(r, StateCancelled)
+			return
+		}
+		batch := queue[start:min(start+ix.cfg.BatchSize, len(queue))]
+		err := ix.indexBatch(ctx, r, projectRoot, batch)
+		if err != nil && ctx.Err() != nil {
+			ix.finish(r, StateCancelled)
+			return
+		}
+
+		ix.mu.Lock()
+		r.status.QueueDepth = len(queue) - start - len(batch)
+		ix.mu.Unlock()
+
+		if err == nil {
+			failures = 0
+			continue
+		}
+		ix.logger.Debug("Embedding batch failed", telemetry.SensitiveLogError("error", err))
+		if failures++; failures >= maxConsecutiveFailures {
+			ix.finish(r, StateUnavailable)
+			ix.logger.Warn("Semantic indexing stopped: embedding model unavailable",
+				slog.String("graph_id", r.status.GraphID),
+				telemetry.SensitiveLogError("error", err))
+			return
+		}
+	}
+	ix.finish(r, StateComplete)
+
+	ix.mu.RLock()
+	gs := r.status
+	ix.mu.RUnlock()
+	ix.logger.Info("Semantic indexing complete",
+		slog.String("graph_id", gs.GraphID),
+		slog.Int("embedded", gs.Embedded),
+		slog.Int("from_store", gs.FromStore),
+		slog.Int("failed", gs.Failed))
+}
+
+// indexBatch gives every node of batch a vector, from the store when one
+// is saved for the symbol's current text and from one embedding request
+// for the rest.
+//
+// Outputs:
+//
+//	error - Non-nil if the embedding request failed.
+func (ix *Index) indexBatch(ctx context.Context, r *run, projectRoot string, batch []*graph.Node) error {
+	var pending []pendingSymbol
+	var texts []string
+	for _, n := range batch {
+		text, err := EmbeddingText(projectRoot, n.Symbol, ix.cfg.MaxBodyBytes)
+		if err != nil {
+			ix.fail(r, 1, err)
+			continue
+		}
+		stableID := cmp.Or(n.Symbol.StableID, n.ID)
+		key := VectorKey(ix.cfg.Model, stableID, ContentHash(text))
+		vec, ok, err := ix.store.Load(ctx, key)
+		if err != nil {
+			ix.logger.Warn("Vector store load failed", telemetry.SensitiveLogError("error", err))
+		}
+		if ok && ix.add(r, n.ID, vec, true) {
+			continue
+		}
+		pending = append(pending, pendingSymbol{id: n.ID, key: key})
+		texts = append(texts, text)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	if err := ix.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	callCtx, cancel := context.WithTimeout(ctx, ix.cfg.CallTimeout)
+	defer cancel()
+	vecs, err := ix.embedder.Embed(callCtx, texts)
+	if err != nil {
+		ix.fail(r, len(pending), err)
+		return err
+	}
+	for i, p := range pending {
+		vec := unitNormalize(vecs[i])
+		if vec == nil {
+			ix.fail(r, 1, fmt.Errorf("zero vector for %s", p.id))
+			continue
+		}
+		if !ix.add(r, p.id, vec, false) {
+			ix.fail(r, 1, fmt.Errorf("vector of %s has dimension %d, index has %d", p.id, len(vec), r.dim))
+			continue
+		}
+		if err := ix.store.Save(ctx, p.key, vec); err != nil {
+			ix.logger.Warn("Vector store save failed", telemetry.SensitiveLogError("error", err))
+		}
+	}
+	return nil
+}
+
+// add records vec for symbol id in r. It returns false, recording
+// nothing, if vec's dimension differs from r's.
+func (ix *Index) add(r *run, id string, vec []float32, fromStore bool) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if r.dim == 0 {
+		r.dim = len(vec)
+	}
+	if len(vec) != r.dim {
+		return false
+	}
+	if _, ok := r.vectors[id]; !ok {
+		r.ids = append(r.ids, id)
+	}
+	r.vectors[id] = vec
+	r.status.Indexed = len(r.vectors)
+	if fromStore {
+		r.status.FromStore++
+	} else {
+		r.status.Embedded++
+	}
+	if r.status.Candidates > 0 {
+		r.status.Coverage = float64(r.status.Indexed) / float64(r.status.Candidates)
+	}
+	return true
+}
+
+// fail records n failed candidates in r.
+func (ix *Index) fail(r *run, n int, err error) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	r.status.Failed += n
+	r.status.LastError = err.Error()
+}
+
+// finish records the end of r.
+func (ix *Index) finish(r *run, state string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	r.status.State = state
+	r.status.FinishedAt = time.Now().UnixMilli()
+}
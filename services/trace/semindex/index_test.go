@@ -0,0 +1,284 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package semindex
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// fakeEmbedder embeds a text as counts of the words in vocab, plus a
+// constant so no vector is zero. It fails every request after down is set.
+type fakeEmbedder struct {
+	mu     sync.Mutex
+	vocab  []string
+	down   bool
+	texts  int
+	blocks chan struct{}
+}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	if f.blocks != nil {
+		select {
+		case <-f.blocks:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.down {
+		return nil, ErrEmbeddingUnavailable
+	}
+	f.texts += len(texts)
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		vec := make([]float32, len(f.vocab)+1)
+		vec[len(f.vocab)] = 0.1
+		lower := strings.ToLower(text)
+		for j, w := range f.vocab {
+			vec[j] = float32(strings.Count(lower, w))
+		}
+		out[i] = vec
+	}
+	return out, nil
+}
+
+func (f *fakeEmbedder) embedded() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.texts
+}
+
+func (f *fakeEmbedder) setDown(down bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.down = down
+}
+
+const testSource = `package svc
+
+func RetryRequest() { backoff() }
+
+// ParseConfig reads the settings file.
+func ParseConfig() {}
+
+func backoff() {}
+`
+
+// testGraph builds a frozen graph over testSource in which backoff has
+// two callers.
+func testGraph(t *testing.T) *graph.Graph {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "svc.go"), []byte(testSource), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := graph.NewGraph(root)
+	for _, sym := range []*ast.Symbol{
+		{ID: "svc.go:3:RetryRequest", Name: "RetryRequest", Kind: ast.SymbolKindFunction, FilePath: "svc.go", StartLine: 3, EndLine: 3, Language: "go", Exported: true},
+		{ID: "svc.go:6:ParseConfig", Name: "ParseConfig", Kind: ast.SymbolKindFunction, FilePath: "svc.go", StartLine: 6, EndLine: 6, Language: "go", Exported: true, DocComment: "ParseConfig reads the settings file."},
+		{ID: "svc.go:8:backoff", Name: "backoff", Kind: ast.SymbolKindFunction, FilePath: "svc.go", StartLine: 8, EndLine: 8, Language: "go"},
+		{ID: "svc.go:1:Settings", Name: "Settings", Kind: ast.SymbolKindStruct, FilePath: "svc.go", StartLine: 1, EndLine: 1, Language: "go", Exported: true},
+	} {
+		if _, err := g.AddNode(sym); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, e := range [][2]string{
+		{"svc.go:3:RetryRequest", "svc.go:8:backoff"},
+		{"svc.go:6:ParseConfig", "svc.go:8:backoff"},
+	} {
+		if err := g.AddEdge(e[0], e[1], graph.EdgeTypeCalls, ast.Location{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g.Freeze()
+	return g
+}
+
+func waitState(t *testing.T, ix *Index, graphID, state string) GraphStatus {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if gs, ok := ix.Coverage(graphID); ok && gs.State == state {
+			return gs
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("graph %s never reached state %s: %+v", graphID, state, ix.Status())
+	return GraphStatus{}
+}
+
+func newTestIndex(t *testing.T, emb Embedder, store Store) *Index {
+	t.Helper()
+	ix, err := New(emb, store, Config{Model: "test-embed", RatePerMinute: 60_000, BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(ix.Close)
+	return ix
+}
+
+func TestIndex_IndexesAndSearches(t *testing.T) {
+	emb := &fakeEmbedder{vocab: []string{"retry", "config"}}
+	store := NewMemoryStore()
+	ix := newTestIndex(t, emb, store)
+
+	g := testGraph(t)
+	if got := Candidates(g, 0); len(got) != 3 || got[0].Symbol.Name != "backoff" {
+		t.Fatalf("candidates = %v, want 3 functions with backoff first", got)
+	}
+
+	ix.Trigger("g1", g)
+	gs := waitState(t, ix, "g1", StateComplete)
+	if gs.Candidates != 3 || gs.Indexed != 3 || gs.Embedded != 3 || gs.Coverage != 1 || gs.QueueDepth != 0 {
+		t.Errorf("status = %+v", gs)
+	}
+
+	res, err := ix.Search(context.Background(), "g1", g, "retry the request", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Mode != ModeSemantic || res.Scanned != 3 {
+		t.Fatalf("results = %+v", res)
+	}
+	if len(res.Hits) != 2 || res.Hits[0].Symbol.Name != "RetryRequest" {
+		t.Errorf("hits = %+v, want RetryRequest first", res.Hits)
+	}
+
+	// A rebuilt graph of the same code is served from the store.
+	before := emb.embedded()
+	ix.Trigger("g1", testGraph(t))
+	gs = waitState(t, ix, "g1", StateComplete)
+	if gs.FromStore != 3 || gs.Embedded != 0 || emb.embedded() != before {
+		t.Errorf("rebuild: status = %+v, embedded %d texts", gs, emb.embedded()-before)
+	}
+}
+
+func TestIndex_SearchFallsBackToLexical(t *testing.T) {
+	g := testGraph(t)
+
+	var nilIndex *Index
+	res, err := nilIndex.Search(context.Background(), "g1", g, "parse settings", 5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Mode != ModeLexical || res.FallbackReason == "" {
+		t.Errorf("nil index: results = %+v", res)
+	}
+	if len(res.Hits) == 0 || res.Hits[0].Symbol.Name != "ParseConfig" {
+		t.Errorf("nil index: hits = %+v, want ParseConfig first", res.Hits)
+	}
+
+	emb := &fakeEmbedder{vocab: []string{"retry"}}
+	ix := newTestIndex(t, emb, nil)
+	res, err = ix.Search(context.Background(), "g1", g, "parse settings", 5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Mode != ModeLexical || !strings.Contains(res.FallbackReason, "not indexed") {
+		t.Errorf("unindexed graph: results = %+v", res)
+	}
+
+	ix.Trigger("g1", g)
+	waitState(t, ix, "g1", StateComplete)
+	emb.setDown(true)
+	res, err = ix.Search(context.Background(), "g1", g, "parse settings", 5, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Mode != ModeLexical || !strings.Contains(res.FallbackReason, ErrEmbeddingUnavailable.Error()) {
+		t.Errorf("model down: results = %+v", res)
+	}
+
+	if _, err := ix.Search(context.Background(), "g1", g, "  ", 5, 0); !errors.Is(err, ErrEmptyQuery) {
+		t.Errorf("empty query error = %v, want ErrEmptyQuery", err)
+	}
+}
+
+func TestIndex_StopsWhenModelUnavailable(t *testing.T) {
+	emb := &fakeEmbedder{vocab: []string{"retry"}, down: true}
+	ix, err := New(emb, nil, Config{Model: "test-embed", RatePerMinute: 60_000, BatchSize: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ix.Close()
+
+	ix.Trigger("g1", testGraph(t))
+	gs := waitState(t, ix, "g1", StateUnavailable)
+	if gs.Indexed != 0 || gs.Failed != maxConsecutiveFailures || gs.LastError == "" {
+		t.Errorf("status = %+v", gs)
+	}
+}
+
+func TestIndex_Cancel(t *testing.T) {
+	emb := &fakeEmbedder{vocab: []string{"retry"}, blocks: make(chan struct{})}
+	ix := newTestIndex(t, emb, nil)
+
+	ix.Trigger("g1", testGraph(t))
+	waitState(t, ix, "g1", StateRunning)
+	ix.Cancel("g1")
+	gs := waitState(t, ix, "g1", StateCancelled)
+	if gs.Indexed != 0 {
+		t.Errorf("status = %+v", gs)
+	}
+}
+
+func TestEndpointAllowed(t *testing.T) {
+	tests := []struct {
+		endpoint    string
+		allowRemote bool
+		localOnly   bool
+		wantErr     bool
+	}{
+		{"http://localhost:11434/api/embed", false, true, false},
+		{"http://127.0.0.1:11434/api/embed", false, false, false},
+		{"http://[::1]:11434/api/embed", false, false, false},
+		{"http://gpu-box:11434/api/embed", false, false, true},
+		{"http://gpu-box:11434/api/embed", true, false, false},
+		{"http://gpu-box:11434/api/embed", true, true, true},
+		{"not a url", true, false, true},
+	}
+	for _, tt := range tests {
+		err := EndpointAllowed(tt.endpoint, tt.allowRemote, tt.localOnly)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("EndpointAllowed(%q, %v, %v) = %v, wantErr %v", tt.endpoint, tt.allowRemote, tt.localOnly, err, tt.wantErr)
+		}
+	}
+
+	if _, err := New(&fakeEmbedder{}, nil, Config{Model: "m", Endpoint: "http://gpu-box/api/embed"}); !errors.Is(err, ErrEndpointNotAllowed) {
+		t.Errorf("New with remote endpoint error = %v, want ErrEndpointNotAllowed", err)
+	}
+}
+
+func TestVectorEncoding(t *testing.T) {
+	vec := []float32{0.5, -1.25, 3}
+	got, err := decodeVector(encodeVector(vec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != len(vec) || got[0] != vec[0] || got[1] != vec[1] || got[2] != vec[2] {
+		t.Errorf("decodeVector(encodeVector(%v)) = %v", vec, got)
+	}
+	if _, err := decodeVector([]byte{1, 2, 3}); err == nil {
+		t.Error("decodeVector of 3 bytes returned no error")
+	}
+}
@@ -0,0 +1,318 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package semindex
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+)
+
+// Search modes reported in Results.Mode.
+const (
+	// ModeSemantic ranks by cosine similarity of embeddings.
+	ModeSemantic = "semantic"
+
+	// ModeLexical ranks by the share of query words found in the
+	// symbol's name, signature and docs.
+	ModeLexical = "lexical"
+)
+
+// Hit is one ranked symbol.
+type Hit struct {
+	// Symbol is the matched symbol.
+	Symbol *ast.Symbol
+
+	// Score is the cosine similarity in ModeSemantic and the matched
+	// share of query words in ModeLexical, both at most 1.
+	Score float64
+}
+
+// Results is the answer to a search.
+type Results struct {
+	// Mode is ModeSemantic or ModeLexical.
+	Mode string
+
+	// FallbackReason says why a search ran in ModeLexical.
+	FallbackReason string
+
+	// Hits are the best matches, highest score first.
+	Hits []Hit
+
+	// Scanned is the number of symbols scored.
+	Scanned int
+
+	// Capped is true when Scanned stopped at the candidate cap.
+	Capped bool
+}
+
+// Search ranks the symbols of g by similarity to query.
+//
+// Description:
+//
+//	Embeds query and scores it against graphID's vectors whose symbol is
+//	in g (so a scoped view of the graph narrows the results), at most
+//	Config.MaxCandidates of them in indexing order. When the graph has no
+//	vectors yet, the vectors have another dimension than the query, or
+//	the model cannot embed the query, the search runs in ModeLexical and
+//	FallbackReason says why. A nil Index always searches lexically.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	graphID - The graph whose vectors to search.
+//	g - The graph (or a scoped view of it) to return symbols from.
+//	query - Natural language query. Must not be empty.
+//	limit - Maximum hits. <= 0 returns none.
+//	minScore - Minimum cosine similarity of a semantic hit. Lexical hits
+//	  only need one matching word.
+//
+// Outputs:
+//
+//	*Results - The hits.
+//	error - ErrEmptyQuery, or ctx's error if it was cancelled.
+//
+// Thread Safety: Safe for concurrent use.
+func (ix *Index) Search(ctx context.Context, graphID string, g *graph.Graph, query string, limit int, minScore float64) (*Results, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, ErrEmptyQuery
+	}
+	if g == nil {
+		return nil, errors.New("graph must not be nil")
+	}
+	if ix == nil {
+		return lexical(g, query, limit, DefaultMaxCandidates, "semantic index is not enabled"), nil
+	}
+
+	ix.mu.RLock()
+	r := ix.runs[graphID]
+	indexed := r != nil && len(r.vectors) > 0
+	ix.mu.RUnlock()
+	if !indexed {
+		return lexical(g, query, limit, ix.cfg.MaxCandidates, "graph is not indexed yet"), nil
+	}
+
+	embedCtx, cancel := context.WithTimeout(ctx, ix.cfg.QueryTimeout)
+	vecs, err := ix.embedder.Embed(embedCtx, []string{query})
+	cancel()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return lexical(g, query, limit, ix.cfg.MaxCandidates, fmt.Sprintf("%s: %v", ErrEmbeddingUnavailable, err)), nil
+	}
+	queryVec := unitNormalize(vecs[0])
+	if queryVec == nil {
+		return lexical(g, query, limit, ix.cfg.MaxCandidates, "query embedding is a zero vector"), nil
+	}
+
+	res := &Results{Mode: ModeSemantic}
+	ix.mu.RLock()
+	if len(queryVec) != r.dim {
+		ix.mu.RUnlock()
+		return lexical(g, query, limit, ix.cfg.MaxCandidates,
+			fmt.Sprintf("query embedding has dimension %d, index has %d; the model changed since indexing", len(queryVec), r.dim)), nil
+	}
+	for _, id := range r.ids {
+		if res.Scanned >= ix.cfg.MaxCandidates {
+			res.Capped = true
+			break
+		}
+		n, ok := g.GetNode(id)
+		if !ok || n.Symbol == nil {
+			continue
+		}
+		res.Scanned++
+		if score := dot(queryVec, r.vectors[id]); score >= minScore {
+			res.Hits = append(res.Hits, Hit{Symbol: n.Symbol, Score: score})
+		}
+	}
+	ix.mu.RUnlock()
+
+	res.Hits = topHits(res.Hits, limit)
+	return res, nil
+}
+
+// Lexical ranks the functions and methods of g by the share of query
+// words found in their name, receiver, signature, doc comment or
+// synthetic doc. A word matches a name word exactly or by a shared
+// prefix of at least four letters ("validates" matches "validate"); a
+// match in the name counts twice.
+//
+// Inputs:
+//
+//	g - The graph.
+//	query - Natural language query.
+//	limit - Maximum hits.
+//
+// Outputs:
+//
+//	*Results - The hits, in ModeLexical.
+//
+// Thread Safety: Safe for concurrent use on a frozen graph.
+func Lexical(g *graph.Graph, query string, limit int) *Results {
+	return lexical(g, query, limit, DefaultMaxCandidates, "")
+}
+
+// lexical is Lexical scanning at most maxCandidates symbols, recording
+// reason as the FallbackReason.
+func lexical(g *graph.Graph, query string, limit, maxCandidates int, reason string) *Results {
+	res := &Results{Mode: ModeLexical, FallbackReason: reason}
+	terms := queryWords(query)
+	if len(terms) == 0 {
+		return res
+	}
+	for _, n := range Candidates(g, 0) {
+		if res.Scanned >= maxCandidates {
+			res.Capped = true
+			break
+		}
+		res.Scanned++
+		sym := n.Symbol
+		nameWords := words(sym.Receiver + " " + sym.Name)
+		otherWords := words(sym.Signature + " " + sym.DocComment + " " + n.SyntheticDoc())
+		matched := 0
+		for _, term := range terms {
+			switch {
+			case matchesAny(term, nameWords):
+				matched += 2
+			case matchesAny(term, otherWords):
+				matched++
+			}
+		}
+		if matched > 0 {
+			res.Hits = append(res.Hits, Hit{Symbol: sym, Score: float64(matched) / float64(2*len(terms))})
+		}
+	}
+	res.Hits = topHits(res.Hits, limit)
+	return res
+}
+
+// topHits sorts hits by descending score, then symbol ID, and keeps the
+// first limit.
+func topHits(hits []Hit, limit int) []Hit {
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Symbol.ID, b.Symbol.ID)
+	})
+	if limit < 0 {
+		limit = 0
+	}
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// stopwords are query words that say nothing about the code sought.
+var stopwords = map[string]bool{
+	"a": true, "an": true, "the": true, "of": true, "for": true, "to": true,
+	"in": true, "on": true, "and": true, "or": true, "is": true, "that": true,
+	"this": true, "with": true, "by": true, "from": true, "code": true,
+	"find": true, "function": true, "functions": true, "method": true,
+	"methods": true, "where": true, "what": true, "which": true, "how": true,
+	"does": true, "do": true, "all": true, "any": true, "me": true, "show": true,
+}
+
+// queryWords returns the distinct words of query without stopwords.
+func queryWords(query string) []string {
+	var out []string
+	for _, w := range words(query) {
+		if !stopwords[w] && !slices.Contains(out, w) {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// words splits s into lowercase words at non-alphanumeric characters and
+// camelCase boundaries, dropping one-letter words. "parseHTTPRequest"
+// gives "parse", "http" and "request".
+func words(s string) []string {
+	var out []string
+	runes := []rune(s)
+	start := -1
+	flush := func(end int) {
+		if start >= 0 && end-start > 1 {
+			out = append(out, strings.ToLower(string(runes[start:end])))
+		}
+		start = -1
+	}
+	for i, c := range runes {
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			flush(i)
+			continue
+		}
+		if start >= 0 && unicode.IsUpper(c) {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				flush(i)
+			}
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	flush(len(runes))
+	return out
+}
+
+// matchesAny reports whether term equals a word of ws or shares a prefix
+// of at least four letters with it, the shorter being a prefix of the
+// longer.
+func matchesAny(term string, ws []string) bool {
+	for _, w := range ws {
+		if w == term {
+			return true
+		}
+		short, long := term, w
+		if len(short) > len(long) {
+			short, long = long, short
+		}
+		if len(short) >= 4 && strings.HasPrefix(long, short) {
+			return true
+		}
+	}
+	return false
+}
+
+// dot returns the dot product of two vectors of equal length.
+func dot(a, b []float32) float64 {
+	var sum float64
+	for i := range a {
+		sum += float64(a[i]) * float64(b[i])
+	}
+	return sum
+}
+
+// unitNormalize returns v scaled to unit length, or nil if v has zero norm.
+func unitNormalize(v []float32) []float32 {
+	norm := math.Sqrt(dot(v, v))
+	if norm == 0 {
+		return nil
+	}
+	out := make([]float32, len(v))
+	for i, x := range v {
+		out[i] = float32(float64(x) / norm)
+	}
+	return out
+}
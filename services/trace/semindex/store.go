@@ -0,0 +1,181 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package semindex
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	dgbadger "github.com/dgraph-io/badger/v4"
+)
+
+const (
+	// vectorKeyPrefix namespaces vector keys in a shared BadgerDB.
+	vectorKeyPrefix = "semvec:"
+
+	// vectorDefaultTTL is how long a persisted vector is kept.
+	vectorDefaultTTL = 90 * 24 * time.Hour
+)
+
+// Store persists symbol embeddings keyed by VectorKey, so unchanged
+// symbols keep their vector across rebuilds and restarts.
+type Store interface {
+	// Load returns the vector stored under key. ok is false if there is none.
+	Load(ctx context.Context, key string) (vec []float32, ok bool, err error)
+
+	// Save stores vec under key, replacing any previous one.
+	Save(ctx context.Context, key string, vec []float32) error
+}
+
+// ContentHash returns a hex SHA-256 of the text a symbol is embedded
+// from. Editing the symbol's signature, doc comment or body changes it.
+//
+// Thread Safety: Safe for concurrent use.
+func ContentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// VectorKey returns the key a symbol's vector is stored under: the
+// embedding model, the symbol's stable ID and the content hash of its
+// embedding text. A vector is reused only for the same model and
+// content.
+//
+// Thread Safety: Safe for concurrent use.
+func VectorKey(model, stableID, contentHash string) string {
+	return model + "/" + stableID + "@" + contentHash
+}
+
+// MemoryStore is a Store that keeps vectors in memory only.
+//
+// Thread Safety: Safe for concurrent use.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	vectors map[string][]float32
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{vectors: make(map[string][]float32)}
+}
+
+// Load implements Store.
+func (s *MemoryStore) Load(_ context.Context, key string) ([]float32, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	vec, ok := s.vectors[key]
+	return vec, ok, nil
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, key string, vec []float32) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vectors[key] = vec
+	return nil
+}
+
+// BadgerStore is a Store backed by BadgerDB.
+//
+// Description:
+//
+//	Vectors are stored as little-endian float32s and expire through
+//	BadgerDB's native TTL, so vectors of code that no longer exists are
+//	eventually dropped. The DB is owned by the caller and must stay open
+//	while the store is in use.
+//
+// Thread Safety: Safe for concurrent use.
+type BadgerStore struct {
+	db  *badgerstore.DB
+	ttl time.Duration
+}
+
+// NewBadgerStore creates a store backed by db.
+//
+// Inputs:
+//
+//	db - Opened BadgerDB wrapper. Must not be nil.
+//	ttl - Lifetime of each vector. <= 0 uses the default (90 days).
+//
+// Outputs:
+//
+//	*BadgerStore - The store. Never nil.
+func NewBadgerStore(db *badgerstore.DB, ttl time.Duration) *BadgerStore {
+	if db == nil {
+		panic("NewBadgerStore: db must not be nil")
+	}
+	if ttl <= 0 {
+		ttl = vectorDefaultTTL
+	}
+	return &BadgerStore{db: db, ttl: ttl}
+}
+
+// Load implements Store.
+func (s *BadgerStore) Load(ctx context.Context, key string) ([]float32, bool, error) {
+	var vec []float32
+	err := s.db.WithReadTxn(ctx, func(txn *dgbadger.Txn) error {
+		item, err := txn.Get([]byte(vectorKeyPrefix + key))
+		if err != nil {
+			return err
+		}
+		return item.Value(func(raw []byte) error {
+			vec, err = decodeVector(raw)
+			return err
+		})
+	})
+	if errors.Is(err, dgbadger.ErrKeyNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("vector load: %w", err)
+	}
+	return vec, true, nil
+}
+
+// Save implements Store.
+func (s *BadgerStore) Save(ctx context.Context, key string, vec []float32) error {
+	err := s.db.WithTxn(ctx, func(txn *dgbadger.Txn) error {
+		return txn.SetEntry(dgbadger.NewEntry([]byte(vectorKeyPrefix+key), encodeVector(vec)).WithTTL(s.ttl))
+	})
+	if err != nil {
+		return fmt.Errorf("vector save: %w", err)
+	}
+	return nil
+}
+
+// encodeVector serializes vec as little-endian float32s.
+func encodeVector(vec []float32) []byte {
+	buf := make([]byte, 4*len(vec))
+	for i, x := range vec {
+		binary.LittleEndian.PutUint32(buf[4*i:], math.Float32bits(x))
+	}
+	return buf
+}
+
+// decodeVector parses the output of encodeVector.
+func decodeVector(raw []byte) ([]float32, error) {
+	if len(raw) == 0 || len(raw)%4 != 0 {
+		return nil, fmt.Errorf("corrupt vector of %d bytes", len(raw))
+	}
+	vec := make([]float32, len(raw)/4)
+	for i := range vec {
+		vec[i] = math.Float32frombits(binary.LittleEndian.Uint32(raw[4*i:]))
+	}
+	return vec, nil
+}
@@ -23,21 +23,36 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"os/exec"
 
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/events"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/annotations"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/chunk"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/docenrich"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ignore"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/lsp"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/projectsummary"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/semindex"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
 )
 
 // ServiceConfig configures the Trace service.
@@ -54,6 +69,16 @@ type ServiceConfig struct {
 	// Default: 100MB
 	MaxProjectSize int64
 
+	// MaxParseResultsBytes caps the decompressed size of a
+	// POST /v1/trace/init/from-parse-results body.
+	// Default: 512MB
+	MaxParseResultsBytes int64
+
+	// MaxParseResultBytes caps the encoded size of one parse result in
+	// such a body.
+	// Default: 16MB
+	MaxParseResultBytes int64
+
 	// MaxCachedGraphs is the maximum number of graphs to cache.
 	// Default: 5
 	MaxCachedGraphs int
@@ -62,8 +87,12 @@ type ServiceConfig struct {
 	// Default: 0 (no expiry)
 	GraphTTL time.Duration
 
-	// AllowedRoots is an optional list of allowed project root prefixes.
-	// If empty, all paths are allowed. Security feature.
+	// AllowedRoots lists the directories /init, /seed and agent runs may
+	// use as project roots. Entries may contain filepath.Match globs and
+	// match the root or any ancestor of it; roots are compared with
+	// symlinks resolved. If empty, all paths are allowed. The server sets
+	// it from TRACE_ALLOWED_ROOTS, defaulting to $HOME and the working
+	// directory. Security feature.
 	AllowedRoots []string
 
 	// LSPIdleTimeout is how long an LSP server can be idle before shutdown.
@@ -83,19 +112,85 @@ type ServiceConfig struct {
 	// If empty, bbolt persistence is disabled (BadgerDB snapshots only).
 	// GR-77a: Phase 1a bbolt persistence.
 	BboltDir string
+
+	// HTTPCache configures ETag, Cache-Control and response memoization
+	// for read-only graph endpoints.
+	HTTPCache HTTPCacheConfig
+
+	// PlanTTL is how long a change plan stays usable. Pending and approved
+	// plans older than this become expired.
+	// Default: 1 hour
+	PlanTTL time.Duration
+
+	// PlanApplyCommand runs in the project root after a plan is applied,
+	// e.g. ["gofmt", "-w", "{files}"] or ["go", "build", "./..."]. An
+	// argument "{files}" expands to the changed files. Its output is
+	// reported; a failure does not undo the apply. The server sets it from
+	// TRACE_PLAN_APPLY_COMMAND. Empty runs nothing.
+	PlanApplyCommand []string
+
+	// PlanApplyTimeout bounds PlanApplyCommand.
+	// Default: 2 minutes
+	PlanApplyTimeout time.Duration
+
+	// GeneratedGlobs are gitignore-syntax patterns for files treated as
+	// generated code in addition to files with a generated-code header.
+	// Symbols from generated files are flagged and hidden from tool
+	// results unless include_generated is set. The server sets it from
+	// TRACE_GENERATED_GLOBS.
+	// Default: ignore.DefaultGeneratedGlobs
+	GeneratedGlobs []string
+
+	// CompactAfterUpdates starts a background compaction of a graph (see
+	// CompactGraph) once this many incremental refreshes were applied
+	// since it was last built or compacted. 0 disables automatic
+	// compaction; POST /v1/trace/graph/compact still works. The server
+	// sets it from TRACE_GRAPH_COMPACT_AFTER.
+	// Default: 20
+	CompactAfterUpdates int
+
+	// MaxDependencySymbols caps the symbols an init with
+	// resolve_dependencies merges from dependency source. Packages past
+	// the cap are left out and the response reports the truncation. The
+	// server sets it from TRACE_MAX_DEPENDENCY_SYMBOLS; 0 means no cap.
+	// Default: 50000
+	MaxDependencySymbols int
+
+	// ChunkTokenEstimator sizes the chunks of POST /v1/trace/chunks. Set
+	// it to match the embedding model's tokenizer. The server sets a
+	// chunk.CharEstimator from TRACE_CHUNK_CHARS_PER_TOKEN.
+	// Default: nil, one token per 4 characters
+	ChunkTokenEstimator chunk.TokenEstimator
+
+	// LinkTemplate renders the uri of the code locations in responses,
+	// e.g. "vscode://file/{abs}:{line}" or
+	// "https://github.com/org/repo/blob/{sha}/{repo_path}#L{line}" (see
+	// ParseLinkTemplate). Requests may override it with the
+	// link_template query parameter. The server sets it from
+	// TRACE_LINK_TEMPLATE.
+	// Default: "" (locations have no uri)
+	LinkTemplate string
 }
 
 // DefaultServiceConfig returns sensible defaults.
 func DefaultServiceConfig() ServiceConfig {
 	return ServiceConfig{
-		MaxInitDuration:   30 * time.Second,
-		MaxProjectFiles:   10000,
-		MaxProjectSize:    100 * 1024 * 1024, // 100MB
-		MaxCachedGraphs:   5,
-		GraphTTL:          0, // No expiry
-		LSPIdleTimeout:    10 * time.Minute,
-		LSPStartupTimeout: 30 * time.Second,
-		LSPRequestTimeout: 10 * time.Second,
+		MaxInitDuration:      30 * time.Second,
+		MaxProjectFiles:      10000,
+		MaxProjectSize:       100 * 1024 * 1024, // 100MB
+		MaxParseResultsBytes: 512 * 1024 * 1024, // 512MB
+		MaxParseResultBytes:  16 * 1024 * 1024,  // 16MB
+		MaxCachedGraphs:      5,
+		GraphTTL:             0, // No expiry
+		LSPIdleTimeout:       10 * time.Minute,
+		LSPStartupTimeout:    30 * time.Second,
+		LSPRequestTimeout:    10 * time.Second,
+		HTTPCache:            DefaultHTTPCacheConfig(),
+		PlanTTL:              time.Hour,
+		PlanApplyTimeout:     2 * time.Minute,
+		GeneratedGlobs:       ignore.DefaultGeneratedGlobs,
+		CompactAfterUpdates:  20,
+		MaxDependencySymbols: 50000,
 	}
 }
 
@@ -106,10 +201,37 @@ func DefaultServiceConfig() ServiceConfig {
 //	Service is safe for concurrent use. Multiple goroutines can call
 //	any combination of methods simultaneously.
 type Service struct {
-	config    ServiceConfig
-	graphs    map[string]*CachedGraph
-	mu        sync.RWMutex
-	initLocks sync.Map // projectRoot -> *sync.Mutex
+	config ServiceConfig
+
+	// roots enforces config.AllowedRoots; nil allows every path.
+	roots *validation.RootAllowlist
+
+	// generated flags parse results from generated files.
+	generated *ignore.GeneratedDetector
+
+	graphs map[string]*CachedGraph
+	mu     sync.RWMutex
+
+	// flights holds the in-progress Init per graph ID (single-flight);
+	// builds holds running and recently finished Inits by build ID.
+	flights   map[string]*initFlight
+	builds    map[string]*initFlight
+	flightsMu sync.Mutex
+
+	// graphBuilds counts full graph builds started, for diagnostics.
+	graphBuilds atomic.Int64
+
+	// compacting holds the IDs of graphs being compacted, so that at most
+	// one compaction runs per graph.
+	compacting   map[string]struct{}
+	compactingMu sync.Mutex
+
+	// beforeBuild, if set, runs after parsing and before the graph builder.
+	// Test seam for holding a build open.
+	beforeBuild func()
+
+	// linkTemplate is the parsed config.LinkTemplate.
+	linkTemplate LinkTemplate
 
 	// registry holds parser instances
 	registry *ast.ParserRegistry
@@ -117,10 +239,21 @@ type Service struct {
 	// libDocProvider is optional library documentation provider
 	libDocProvider cbcontext.LibraryDocProvider
 
-	// plans holds cached change plans for validation and preview
+	// plans holds cached change plans for validation, preview and approval
 	plans   map[string]*CachedPlan
 	plansMu sync.RWMutex
 
+	// applyMu serializes plan applies so two plans never edit files at
+	// the same time.
+	applyMu sync.Mutex
+
+	// views holds named scoped views of cached graphs
+	views   map[string]*registeredView
+	viewsMu sync.RWMutex
+
+	// eventEmitter receives plan status events. Nil disables them.
+	eventEmitter *events.Emitter
+
 	// lspManagers holds LSP managers per graph (graphID -> manager)
 	lspManagers map[string]*lsp.Manager
 	lspMu       sync.RWMutex
@@ -134,9 +267,50 @@ type Service struct {
 
 	// lspLanguages tracks which languages have LSP enrichment available.
 	lspLanguages map[string]bool
+
+	// toolCache holds results of Cacheable agent tools, keyed by graph
+	// content hash and shared by all sessions. Invalidated per graph
+	// generation when a graph is replaced or evicted.
+	toolCache *tools.ResultCache
+
+	// responseMemo holds responses of expensive read endpoints, keyed by
+	// graph generation. Nil when memoization is disabled.
+	responseMemo *responseMemo
+
+	// docEnricher generates synthetic docs for every installed graph.
+	// Nil when doc enrichment is disabled.
+	docEnricher *docenrich.Pipeline
+
+	// semanticIndex embeds the functions and methods of every installed
+	// graph for semantic search. Nil when the semantic index is disabled.
+	semanticIndex *semindex.Index
+
+	// annotationStore persists API annotations. In memory by default.
+	annotationStore annotations.Store
+
+	// annotationSets holds the annotation set of each project root,
+	// shared by all of the project's graphs.
+	annotationSets map[string]*annotations.Set
+	annotationsMu  sync.Mutex
+
+	// factStore persists the facts users confirmed about each project. In
+	// memory by default. factsMu serializes its read-modify-writes.
+	factStore facts.Store
+	factsMu   sync.Mutex
+
+	// summaries holds the project summary of every installed graph, keyed
+	// by summaryKey. Guarded by summariesMu.
+	summaries   map[string]*projectsummary.Summary
+	summariesMu sync.Mutex
+
+	// summaryPolisher writes the prose of project summaries on request.
+	// Nil when prose is disabled. polishing holds the keys being polished.
+	summaryPolisher *projectsummary.Polisher
+	polishing       map[string]bool
 }
 
-// CachedPlan holds a change plan and its associated graph ID.
+// CachedPlan holds a change plan, its associated graph ID and its
+// approval state.
 type CachedPlan struct {
 	// GraphID is the graph this plan was created for.
 	GraphID string
@@ -146,6 +320,24 @@ type CachedPlan struct {
 
 	// CreatedAt is when the plan was created.
 	CreatedAt time.Time
+
+	// SessionID is the agent session the plan was produced for, if any.
+	SessionID string
+
+	// Status is the approval status. Guarded by Service.plansMu.
+	Status PlanStatus
+
+	// Approval is why the plan needs approval; zero if it does not.
+	Approval PlanApproval
+
+	// Comment is the reviewer's comment on approve or reject.
+	Comment string
+
+	// UpdatedAt is when Status last changed.
+	UpdatedAt time.Time
+
+	// ExpiresAt is when a pending or approved plan expires.
+	ExpiresAt time.Time
 }
 
 // NewService creates a new Trace service.
@@ -164,18 +356,58 @@ type CachedPlan struct {
 //	*Service - The configured service
 func NewService(config ServiceConfig) *Service {
 	svc := &Service{
-		config:      config,
-		graphs:      make(map[string]*CachedGraph),
-		registry:    ast.NewParserRegistry(),
-		plans:       make(map[string]*CachedPlan),
-		lspManagers: make(map[string]*lsp.Manager),
+		config:       config,
+		graphs:       make(map[string]*CachedGraph),
+		flights:      make(map[string]*initFlight),
+		builds:       make(map[string]*initFlight),
+		compacting:   make(map[string]struct{}),
+		registry:     ast.NewParserRegistry(),
+		plans:        make(map[string]*CachedPlan),
+		views:        make(map[string]*registeredView),
+		lspManagers:  make(map[string]*lsp.Manager),
+		toolCache:    tools.NewResultCache(tools.DefaultResultCacheCapacity, nil),
+		responseMemo: newResponseMemo(config.HTTPCache.MemoEntries),
+
+		annotationStore: annotations.NewMemoryStore(),
+		annotationSets:  make(map[string]*annotations.Set),
+		factStore:       facts.NewMemoryStore(),
+		summaries:       make(map[string]*projectsummary.Summary),
+		polishing:       make(map[string]bool),
+	}
+
+	if len(config.AllowedRoots) > 0 {
+		roots, err := validation.NewRootAllowlist(config.AllowedRoots)
+		if err != nil {
+			// Fail closed: an allowlist that cannot be parsed allows nothing.
+			slog.Error("invalid allowed roots; all project roots will be refused",
+				slog.Any("roots", config.AllowedRoots), telemetry.SensitiveLogError("error", err))
+			roots, _ = validation.NewRootAllowlist(nil)
+		}
+		svc.roots = roots
+	}
+
+	generated, err := ignore.NewGeneratedDetector(config.GeneratedGlobs)
+	if err != nil {
+		slog.Error("invalid generated globs; only generated-code headers will be detected",
+			slog.Any("globs", config.GeneratedGlobs), telemetry.SensitiveLogError("error", err))
+		generated, _ = ignore.NewGeneratedDetector(nil)
+	}
+	svc.generated = generated
+
+	if config.LinkTemplate != "" {
+		tmpl, err := ParseLinkTemplate(config.LinkTemplate)
+		if err != nil {
+			slog.Error("invalid link template; code locations will have no uri",
+				slog.String("template", config.LinkTemplate), telemetry.SensitiveLogError("error", err))
+		}
+		svc.linkTemplate = tmpl
 	}
 
-	// Register default parsers
-	svc.registry.Register(ast.NewGoParser())
-	svc.registry.Register(ast.NewPythonParser())
-	svc.registry.Register(ast.NewTypeScriptParser())
-	svc.registry.Register(ast.NewJavaScriptParser())
+	// Register default parsers. Their factories construct parsers with
+	// per-build settings (DiscoveryOptions.ParserSettings).
+	for _, factory := range ast.DefaultParserFactories() {
+		svc.registry.RegisterFactory(factory)
+	}
 
 	return svc
 }
@@ -199,6 +431,148 @@ func (s *Service) SetSnapshotManager(mgr *graph.SnapshotManager) {
 	s.snapshotMgr = mgr
 }
 
+// SetToolResultCacheStore sets the persistent store that tool results
+// cached in memory are written through to.
+//
+// Description:
+//
+//	With a store, results of Cacheable tools survive restarts and remain
+//	available for as long as the project's graph content is unchanged.
+//
+// Inputs:
+//
+//	store - The store. Can be nil to cache in memory only.
+func (s *Service) SetToolResultCacheStore(store tools.ResultCacheStore) {
+	s.toolCache.SetStore(store)
+}
+
+// ToolResultCache returns the tool result cache shared by agent sessions.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) ToolResultCache() *tools.ResultCache {
+	return s.toolCache
+}
+
+// SetDocEnricher sets the pipeline that generates synthetic docs for the
+// undocumented exported symbols of every graph the service installs.
+//
+// Description:
+//
+//	Call before the first Init. When a run completes, tool results and
+//	memoized responses cached for the graph are invalidated so they pick
+//	up the new synthetic docs.
+//
+// Inputs:
+//
+//	p - The pipeline. Can be nil to disable doc enrichment.
+func (s *Service) SetDocEnricher(p *docenrich.Pipeline) {
+	s.docEnricher = p
+	if p == nil {
+		return
+	}
+	p.SetOnComplete(func(st docenrich.GraphStatus) {
+		if st.Completed == 0 {
+			return
+		}
+		cached, err := s.GetGraph(st.GraphID)
+		if err != nil {
+			return
+		}
+		generation := graphGeneration(cached)
+		s.toolCache.Invalidate(context.Background(), generation)
+		if s.responseMemo != nil {
+			s.responseMemo.invalidate(generation)
+		}
+	})
+}
+
+// DocEnrichmentStatus returns the progress of synthetic doc generation,
+// with Enabled false when no pipeline is set.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) DocEnrichmentStatus() docenrich.Status {
+	if s.docEnricher == nil {
+		return docenrich.Status{}
+	}
+	return s.docEnricher.Status()
+}
+
+// SetSemanticIndex sets the index that embeds the functions and methods
+// of every graph the service installs. Call before the first Init.
+//
+// Inputs:
+//
+//	ix - The index. Can be nil to disable the semantic index; searches
+//	  then match keywords only.
+func (s *Service) SetSemanticIndex(ix *semindex.Index) {
+	s.semanticIndex = ix
+}
+
+// SemanticIndex returns the semantic index, or nil when it is disabled.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) SemanticIndex() *semindex.Index {
+	return s.semanticIndex
+}
+
+// SemanticIndexStatus returns the progress of semantic indexing, with
+// Enabled false when no index is set.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) SemanticIndexStatus() semindex.Status {
+	if s.semanticIndex == nil {
+		return semindex.Status{}
+	}
+	return s.semanticIndex.Status()
+}
+
+// SemanticIndexCoverage returns the coverage of graphID's semantic
+// index, or nil when the index is disabled or the graph was never
+// indexed.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) SemanticIndexCoverage(graphID string) *semindex.GraphStatus {
+	if s.semanticIndex == nil {
+		return nil
+	}
+	gs, ok := s.semanticIndex.Coverage(graphID)
+	if !ok {
+		return nil
+	}
+	return &gs
+}
+
+// SemanticSearch ranks the functions and methods of a graph by
+// similarity to a natural language query.
+//
+// Description:
+//
+//	Uses the semantic index when it is enabled and has vectors for the
+//	graph; otherwise, or when the embedding model is unavailable, falls
+//	back to keyword matching (see semindex.Index.Search).
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	graphID - ID of the graph to search.
+//	query - Natural language query. Must not be empty.
+//	limit - Maximum results.
+//	minScore - Minimum cosine similarity of a semantic result.
+//
+// Outputs:
+//
+//	*semindex.Results - The ranked symbols and the search mode.
+//	error - Non-nil if the graph is not found or the query is empty.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) SemanticSearch(ctx context.Context, graphID, query string, limit int, minScore float64) (*semindex.Results, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	return s.semanticIndex.Search(ctx, graphID, cached.Graph, query, limit, minScore)
+}
+
 // SetLSPEnabled configures LSP enrichment availability on the service.
 //
 // Description:
@@ -245,7 +619,15 @@ func (s *Service) LSPEnrichmentStatus() (bool, map[string]bool) {
 //
 //	Parses the project, builds the code graph and symbol index, and
 //	caches the result. If a graph already exists for the project, it
-//	is replaced.
+//	is replaced atomically once the new graph is ready; reads keep using
+//	the previous graph until then.
+//
+//	Concurrent Inits for the same project share one build: the first
+//	caller runs it, later callers block until it finishes and receive a
+//	copy of its response with Joined set. A caller whose languages,
+//	excludes or discovery options differ from the running build's gets
+//	ErrInitInProgress instead. Without forceRebuild, an already cached
+//	graph is returned immediately even while a rebuild is running.
 //
 // Inputs:
 //
@@ -264,54 +646,251 @@ func (s *Service) LSPEnrichmentStatus() (bool, map[string]bool) {
 //	ErrRelativePath - Project root is not absolute
 //	ErrPathTraversal - Project root contains .. sequences
 //	ErrProjectTooLarge - Project exceeds configured limits
-//	ErrInitInProgress - Another init is running for this project
 //	ErrInitTimeout - Init took too long
+//	ErrInitInProgress - A build with different options is running
+//	ctx.Err() - The caller's context ended while waiting on another
+//	  caller's build. The build itself is only cancelled once every
+//	  caller sharing it has gone; the callers then receive an error
+//	  wrapping ErrInitCancelled.
 func (s *Service) Init(ctx context.Context, projectRoot string, languages, excludes []string, forceRebuild ...bool) (*InitResponse, error) {
+	return s.InitWithDiscovery(ctx, projectRoot, languages, excludes, DiscoveryOptions{}, forceRebuild...)
+}
+
+// InitWithDiscovery initializes a code graph with explicit discovery options.
+//
+// Description:
+//
+//	Same as Init, but lets the caller add ignore globs, disable .gitignore
+//	handling, or opt in to generated-file skipping. Init uses the zero
+//	DiscoveryOptions, which honors .gitignore and .traceignore.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	projectRoot - Absolute path to the project root
+//	languages - Languages to parse (default: ["go"])
+//	excludes - Glob patterns to exclude (see Init for defaults)
+//	discovery - Ignore rules and generated-file handling
+//
+// Outputs:
+//
+//	*InitResponse - Graph statistics, including SkippedFiles by rule
+//	error - Same errors as Init
+func (s *Service) InitWithDiscovery(ctx context.Context, projectRoot string, languages, excludes []string, discovery DiscoveryOptions, forceRebuild ...bool) (*InitResponse, error) {
 	rebuild := len(forceRebuild) > 0 && forceRebuild[0]
+	return s.initProject(ctx, projectRoot, languages, excludes, discovery, rebuild, true)
+}
+
+// StartInitWithDiscovery is InitWithDiscovery without waiting on other
+// callers.
+//
+// Description:
+//
+//	If no Init is running for the project, the caller runs the build and
+//	blocks until it completes, exactly like InitWithDiscovery. If one is
+//	already running, it returns at once with Status InitStatusAlreadyBuilding
+//	and the running build's Progress instead of joining it.
+//
+// Inputs:
+//
+//	Same as InitWithDiscovery; forceRebuild is not optional.
+//
+// Outputs:
+//
+//	*InitResponse - The build result, or an already_building status.
+//	error - Same errors as Init.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Service) StartInitWithDiscovery(ctx context.Context, projectRoot string, languages, excludes []string, discovery DiscoveryOptions, forceRebuild bool) (*InitResponse, error) {
+	return s.initProject(ctx, projectRoot, languages, excludes, discovery, forceRebuild, false)
+}
+
+// initProject validates the request, then returns the cached graph, joins
+// or reports an in-progress build, or leads a new one.
+func (s *Service) initProject(ctx context.Context, projectRoot string, languages, excludes []string, discovery DiscoveryOptions, rebuild, wait bool) (*InitResponse, error) {
+	graphID, languages, excludes, err := s.prepareInit(projectRoot, languages, excludes, discovery)
+	if err != nil {
+		return nil, err
+	}
+	return s.initGraph(ctx, graphID, projectRoot, nil, languages, excludes, discovery, rebuild, wait)
+}
+
+// initGraph returns the cached graph, joins or reports an in-progress
+// build, or leads a new one. ws is nil for single-root projects.
+func (s *Service) initGraph(ctx context.Context, graphID, projectRoot string, ws *Workspace, languages, excludes []string, discovery DiscoveryOptions, rebuild, wait bool) (*InitResponse, error) {
+	// GR-70a: Return cached graph if it exists and no rebuild was requested.
+	// The agent's InitPhase calls Init to get a graph handle, not to rebuild.
+	// Without this, the second Init OOMs due to GR-70's memory limit enforcement
+	// (first graph's heap allocations are still live).
+	s.mu.RLock()
+	existing := s.graphs[graphID]
+	s.mu.RUnlock()
+	if existing != nil && !rebuild {
+		slog.Info("GR-70a: Returning cached graph (skipping rebuild)",
+			slog.String("graph_id", graphID),
+			slog.Int("nodes", existing.Graph.NodeCount()),
+			slog.Int("edges", existing.Graph.EdgeCount()),
+		)
+		return &InitResponse{
+			GraphID:          graphID,
+			Status:           InitStatusCached,
+			IsRefresh:        false,
+			FilesParsed:      0,
+			SymbolsExtracted: existing.Graph.NodeCount(),
+			EdgesBuilt:       existing.Graph.EdgeCount(),
+			ParseTimeMs:      0,
+			Roots:            existing.RootStats,
+			CommitSHA:        existing.CommitSHA,
+		}, nil
+	}
+
+	// Single-flight: share an in-progress build for this project.
+	flight, leader, err := s.joinOrLeadInit(graphID, projectRoot, ws, initOptionsKey(languages, excludes, discovery))
+	if err != nil {
+		return nil, err
+	}
+	if !leader {
+		if !wait {
+			return &InitResponse{
+				GraphID:  graphID,
+				BuildID:  flight.id,
+				Status:   InitStatusAlreadyBuilding,
+				Progress: flight.snapshot(),
+			}, nil
+		}
+		slog.Info("Joining in-progress init",
+			slog.String("graph_id", graphID),
+			slog.String("build_id", flight.id),
+			telemetry.SensitiveLog("project_root", projectRoot),
+		)
+		return flight.wait(ctx)
+	}
+	return s.leadInit(ctx, flight, languages, excludes, discovery)
+}
+
+// prepareInit validates projectRoot and applies language and exclude
+// defaults.
+//
+// Outputs:
+//
+//	string - The graph ID for projectRoot and discovery.GitRef.
+//	[]string - Languages, defaulted.
+//	[]string - Excludes, defaulted.
+//	error - Non-nil if projectRoot is invalid.
+func (s *Service) prepareInit(projectRoot string, languages, excludes []string, discovery DiscoveryOptions) (string, []string, []string, error) {
 	// Validate project root
 	if err := s.validateProjectRoot(projectRoot); err != nil {
-		return nil, err
+		return "", nil, nil, err
 	}
 
-	// Apply defaults
+	languages, excludes = applyInitDefaults(languages, excludes)
+	return s.projectGraphID(projectRoot, discovery.GitRef), languages, excludes, nil
+}
+
+// applyInitDefaults returns languages and excludes with defaults applied
+// to empty lists.
+func applyInitDefaults(languages, excludes []string) ([]string, []string) {
 	if len(languages) == 0 {
 		languages = []string{"go"}
 	}
 	if len(excludes) == 0 {
-		excludes = []string{
-			// Directory excludes (matched against dir name at walk time)
-			"vendor",
-			"node_modules",
-			"__pycache__",
-			".venv",
-			"venv",
-			"dist",
-			"build",
-			".tox",
-			".mypy_cache",
-			// Go test files
-			"*_test.go",
-			// Python test files
-			"*_test.py",
-			"test_*.py",
-			"conftest.py",
-			// TypeScript / JavaScript test files
-			"*.test.ts",
-			"*.test.js",
-			"*.spec.ts",
-			"*.spec.js",
-			"*.test.tsx",
-			"*.test.jsx",
-		}
-	}
-
-	// Get init lock for this project to prevent concurrent inits
-	lock := s.getInitLock(projectRoot)
-	if !lock.TryLock() {
-		return nil, ErrInitInProgress
-	}
-	defer lock.Unlock()
+		excludes = DefaultExcludePatterns()
+	}
+	return languages, excludes
+}
+
+// defaultExcludePatterns are the exclude patterns of init requests
+// without any.
+var defaultExcludePatterns = []string{
+	// Directory excludes (matched against dir name at walk time)
+	"vendor",
+	"node_modules",
+	"__pycache__",
+	".venv",
+	"venv",
+	"dist",
+	"build",
+	".tox",
+	".mypy_cache",
+	// Go test files
+	"*_test.go",
+	// Python test files
+	"*_test.py",
+	"test_*.py",
+	"conftest.py",
+	// TypeScript / JavaScript test files
+	"*.test.ts",
+	"*.test.js",
+	"*.spec.ts",
+	"*.spec.js",
+	"*.test.tsx",
+	"*.test.jsx",
+}
 
+// DefaultExcludePatterns returns the exclude patterns applied to init
+// requests that specify none.
+//
+// Outputs:
+//
+//	[]string - A fresh copy of the patterns; callers may modify it.
+func DefaultExcludePatterns() []string {
+	return slices.Clone(defaultExcludePatterns)
+}
+
+// initParserSettings validates the languages and parser options of an
+// init request.
+//
+// Description:
+//
+//	Every language must have a registered parser, and every language in
+//	options must be one of languages (["go"] when empty). The options of
+//	each language are checked against its parser factory, which names
+//	the closest accepted option for a misspelled one.
+//
+// Outputs:
+//
+//	map[string]ast.ParserSettings - The settings for
+//	  DiscoveryOptions.ParserSettings. Nil without options.
+//	error - Wraps ast.ErrUnsupportedLanguage, ast.ErrUnknownParserOption
+//	  or ast.ErrInvalidParserOption.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Service) initParserSettings(languages []string, options map[string]map[string]any) (map[string]ast.ParserSettings, error) {
+	known := s.registry.Languages()
+	sort.Strings(known)
+	for _, lang := range languages {
+		if _, ok := s.registry.GetByLanguage(lang); !ok {
+			return nil, unknownLanguageError("languages", lang, known)
+		}
+	}
+	if len(options) == 0 {
+		return nil, nil
+	}
+	languages, _ = applyInitDefaults(languages, []string{""})
+	settings := make(map[string]ast.ParserSettings, len(options))
+	for lang, opts := range options {
+		if !slices.Contains(languages, lang) {
+			if _, ok := s.registry.GetByLanguage(lang); !ok {
+				return nil, unknownLanguageError("parser_options", lang, known)
+			}
+			return nil, fmt.Errorf("%w: parser_options for %q, which is not in languages %v", ast.ErrUnsupportedLanguage, lang, languages)
+		}
+		settings[lang] = ast.ParserSettings(opts)
+	}
+	if _, err := s.registry.Configure(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// unknownLanguageError reports a language without a parser in field.
+func unknownLanguageError(field, lang string, known []string) error {
+	return fmt.Errorf("%w: %s has %q; supported: %s", ast.ErrUnsupportedLanguage, field, lang, strings.Join(known, ", "))
+}
+
+// buildProject runs a full or incremental build for the flight's leader and
+// swaps the result into the cache.
+func (s *Service) buildProject(ctx context.Context, flight *initFlight, projectRoot, graphID string, languages, excludes []string, discovery DiscoveryOptions) (*InitResponse, error) {
 	// Apply timeout
 	if s.config.MaxInitDuration > 0 {
 		var cancel context.CancelFunc
@@ -321,9 +900,6 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 
 	start := time.Now()
 
-	// Generate graph ID
-	graphID := s.generateGraphID(projectRoot)
-
 	// Check if we're replacing an existing graph
 	s.mu.RLock()
 	existing, isRefresh := s.graphs[graphID]
@@ -333,57 +909,96 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 	}
 	s.mu.RUnlock()
 
-	// GR-70a: Return cached graph if it exists and no rebuild was requested.
-	// The agent's InitPhase calls Init to get a graph handle, not to rebuild.
-	// Without this, the second Init OOMs due to GR-70's memory limit enforcement
-	// (first graph's heap allocations are still live).
-	if isRefresh && existing != nil && !rebuild {
-		slog.Info("GR-70a: Returning cached graph (skipping rebuild)",
-			slog.String("graph_id", graphID),
-			slog.Int("nodes", existing.Graph.NodeCount()),
-			slog.Int("edges", existing.Graph.EdgeCount()),
-		)
-		return &InitResponse{
-			GraphID:          graphID,
-			IsRefresh:        false,
-			FilesParsed:      0,
-			SymbolsExtracted: existing.Graph.NodeCount(),
-			EdgesBuilt:       existing.Graph.EdgeCount(),
-			ParseTimeMs:      0,
-		}, nil
+	// CRS-18: Try incremental refresh from prior snapshot. Workspaces
+	// refresh from the cached graph, one root at a time. A git ref build
+	// reads a commit, which snapshots of the working tree do not describe.
+	ws := flight.workspace
+	var source SourceProvider
+	switch {
+	case ws != nil:
+		if incrResp, incrErr := s.tryWorkspaceRefresh(ctx, ws, graphID, languages, excludes, discovery); incrErr == nil && incrResp != nil {
+			return incrResp, nil
+		}
+	case discovery.GitRef != "":
+		gitSource, err := NewGitObjectSource(projectRoot, discovery.GitRef)
+		if err != nil {
+			return nil, err
+		}
+		source = gitSource
+	default:
+		if incrResp, incrErr := s.tryIncrementalRefresh(ctx, projectRoot, graphID, languages, excludes, discovery); incrErr == nil && incrResp != nil {
+			return incrResp, nil
+		}
+		source = NewFileSystemSource(projectRoot, discovery.sourceOptions()...)
 	}
-
-	// CRS-18: Try incremental refresh from prior snapshot.
-	if incrResp, incrErr := s.tryIncrementalRefresh(ctx, projectRoot, graphID, languages, excludes); incrErr == nil && incrResp != nil {
-		return incrResp, nil
+	commitSHA := ""
+	if source != nil {
+		commitSHA = source.Revision()
 	}
 
-	// Create index
-	idx := index.NewSymbolIndex()
-
 	// Parse files into ParseResults
-	parseResults, result, err := s.parseProjectToResults(ctx, projectRoot, languages, excludes)
+	var (
+		parseResults    []*ast.ParseResult
+		result          *parseResult
+		rootFilesParsed map[string]int
+		err             error
+	)
+	if ws != nil {
+		parseResults, result, rootFilesParsed, err = s.parseWorkspaceToResults(ctx, ws, languages, excludes, discovery)
+	} else {
+		parseResults, result, err = s.parseProjectToResults(ctx, source, languages, excludes, discovery)
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	// Dependency source is merged only into working-tree builds of a
+	// single root, the one place a go.mod and module cache describe it.
+	var depStats dependencyStats
+	if discovery.ResolveDependencies && ws == nil && commitSHA == "" {
+		var depResults []*ast.ParseResult
+		depResults, depStats, err = s.resolveGoDependencies(ctx, projectRoot, parseResults, discovery)
+		if err != nil {
+			return nil, err
+		}
+		parseResults = append(parseResults, depResults...)
+		result.Errors = append(result.Errors, depStats.Errors...)
+	}
+
 	// Build graph with edges using the Builder
 	// GR-41c: This ensures edge extraction (imports, calls, etc.) runs properly
 	builderOpts := []graph.BuilderOption{graph.WithProjectRoot(projectRoot)}
+	builderOpts = append(builderOpts, discovery.builderOptions()...)
+	if len(result.PythonSourceRoots) > 0 {
+		builderOpts = append(builderOpts, graph.WithPythonSourceRoots(result.PythonSourceRoots...))
+	}
+	if len(result.GoModules) > 0 {
+		builderOpts = append(builderOpts, graph.WithGoModules(result.GoModules...))
+	}
+	if commitSHA != "" {
+		builderOpts = append(builderOpts, graph.WithSourceRevision(commitSHA))
+	}
 
 	// GR-74/76: Wire LSP enrichment when LSP manager is available.
 	if lspConfig := s.buildLSPEnrichmentConfig(graphID); lspConfig != nil {
 		builderOpts = append(builderOpts, graph.WithLSPEnrichment(lspConfig))
 	}
 
+	builderOpts = append(builderOpts, graph.WithProgressCallback(flight.reportBuild))
+	flight.startBuild(len(parseResults), result.Errors)
+	if s.beforeBuild != nil {
+		s.beforeBuild()
+	}
+	s.graphBuilds.Add(1)
+
 	builder := graph.NewBuilder(builderOpts...)
 	buildResult, err := builder.Build(ctx, parseResults)
 	if err != nil {
 		// GR-70: Build() now returns errors on context cancellation and memory limits.
 		// The partial buildResult is still usable for diagnostics.
 		slog.Warn("graph build failed",
-			slog.String("project_root", projectRoot),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("project_root", projectRoot),
+			telemetry.SensitiveLogError("error", err),
 			slog.Int("nodes_created", buildResult.Stats.NodesCreated),
 			slog.Int("edges_created", buildResult.Stats.EdgesCreated),
 		)
@@ -393,13 +1008,14 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 	// R-1: Handle incomplete builds (limit short-circuit without context error)
 	if buildResult.Incomplete {
 		slog.Warn("graph build incomplete",
-			slog.String("project_root", projectRoot),
+			telemetry.SensitiveLog("project_root", projectRoot),
 			slog.Int("nodes_created", buildResult.Stats.NodesCreated),
 			slog.Int("edges_created", buildResult.Stats.EdgesCreated),
 		)
 	}
 
 	// R-2: Merge builder errors into result.Errors
+	buildResult.FileErrors = append(buildResult.FileErrors, result.FileErrors...)
 	for _, fe := range buildResult.FileErrors {
 		result.Errors = append(result.Errors, fe.Error())
 	}
@@ -407,13 +1023,123 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 		result.Errors = append(result.Errors, ee.Error())
 	}
 
+	cached := s.newCachedGraph(projectRoot, parseResults, buildResult)
+	cached.GitRef = discovery.GitRef
+	cached.HeadSHA, cached.RepoSubdir = detectGitHead(projectRoot)
+	result.SymbolsExtracted = cached.Index.Stats().TotalSymbols
+	g := cached.Graph
+
+	// CODEOWNERS and manifests are read from the working tree, which
+	// does not describe another revision.
+	if commitSHA == "" {
+		cached.OwnersHash = recordOwners(g, projectRoot, ws)
+		cached.Dependencies = recordDependencies(g, projectRoot, ws)
+		specs, toolsErr := recordProjectTools(projectRoot)
+		if toolsErr != nil {
+			result.Errors = append(result.Errors, toolsErr.Error())
+		}
+		cached.ProjectTools = specs
+	}
+	// API annotations apply to every revision; the annotations file is
+	// read only for the working tree.
+	set, annotationsErr := s.recordAnnotations(ctx, g, projectRoot, commitSHA == "")
+	if annotationsErr != nil {
+		result.Errors = append(result.Errors, annotationsErr.Error())
+	}
+	cached.Annotations = set
+	if ws != nil {
+		cached.Workspace = ws
+		cached.RootStats = workspaceRootStats(g, ws, rootFilesParsed)
+	}
+
+	if s.config.GraphTTL > 0 {
+		cached.ExpiresAtMilli = time.Now().Add(s.config.GraphTTL).UnixMilli()
+	}
+
+	if err := s.swapGraph(ctx, graphID, cached); err != nil {
+		return nil, err
+	}
+
+	// Snapshots are keyed by project root; a workspace shares its base
+	// with any single-root project there, and a git ref build shares its
+	// root with the working tree's graph, so neither is persisted. Nor is
+	// a graph with dependency source, which a refresh could not keep, or
+	// one built with passes disabled, parser settings, or a symlink or
+	// placeholder policy of its own.
+	if ws == nil && commitSHA == "" && !discovery.ResolveDependencies && len(discovery.DisabledPasses) == 0 && len(discovery.ParserSettings) == 0 && discovery.defaultSymlinks() && discovery.defaultPlaceholders() {
+		// CRS-18: Save graph snapshot for future incremental refresh.
+		s.saveGraphSnapshot(ctx, g)
+
+		// GR-77a: Materialize to bbolt for fast restart.
+		s.saveBboltSnapshot(ctx, g)
+	}
+
+	return &InitResponse{
+		GraphID:               graphID,
+		IsRefresh:             isRefresh,
+		PreviousID:            previousID,
+		FilesParsed:           result.FilesParsed,
+		SymbolsExtracted:      result.SymbolsExtracted,
+		EdgesBuilt:            g.EdgeCount(),
+		ParseTimeMs:           time.Since(start).Milliseconds(),
+		SkippedFiles:          result.SkippedFiles,
+		SkippedByRule:         result.SkippedByRule,
+		Errors:                result.Errors,
+		Roots:                 cached.RootStats,
+		CommitSHA:             cached.CommitSHA,
+		DependencyPackages:    depStats.Packages,
+		DependencySymbols:     depStats.Symbols,
+		DependenciesTruncated: depStats.Truncated,
+		ByLanguage:            initLanguageStats(buildResult.Stats.ByLanguage),
+		GoModules:             len(result.GoModules),
+		CrossModuleEdges:      buildResult.Stats.CrossModuleEdges,
+	}, nil
+}
+
+// initLanguageStats converts the builder's per-language counts for an
+// InitResponse. Nil for an empty breakdown.
+func initLanguageStats(stats map[string]graph.LanguageStats) map[string]InitLanguageStats {
+	if len(stats) == 0 {
+		return nil
+	}
+	out := make(map[string]InitLanguageStats, len(stats))
+	for lang, ls := range stats {
+		out[lang] = InitLanguageStats{Files: ls.Files, Symbols: ls.Symbols}
+	}
+	return out
+}
+
+// newCachedGraph indexes a finished build and wraps it for the cache.
+//
+// Description:
+//
+//	Adds the symbols of every parse result the build kept to a new symbol
+//	index, logs the build statistics, and creates the context assembler
+//	and CRS adapter. The caller sets the fields that depend on how the
+//	graph was built (GitRef, Workspace, ...) and swaps it in.
+//
+// Inputs:
+//
+//	projectRoot - The graph's project root, for logging.
+//	parseResults - The results the graph was built from.
+//	buildResult - The successful build.
+//
+// Outputs:
+//
+//	*CachedGraph - The graph, its index, assembler and adapter.
+func (s *Service) newCachedGraph(projectRoot string, parseResults []*ast.ParseResult, buildResult *graph.BuildResult) *CachedGraph {
 	g := buildResult.Graph
+	idx := index.NewSymbolIndex()
 
 	// I-1: Add symbols to index recursively (including child symbols)
 	// IT-04: Observable pipeline — log all index add failures for diagnostics.
 	var totalAdded, totalDropped, totalRetried int
+	profileExcluded := make(map[string]bool, len(buildResult.ProfileExcludedFiles))
+	for _, f := range buildResult.ProfileExcludedFiles {
+		profileExcluded[f] = true
+	}
 	for _, pr := range parseResults {
-		if pr == nil {
+		if pr == nil || profileExcluded[pr.FilePath] {
 			continue
 		}
 		added, dropped, retried := addSymbolsToIndexRecursive(idx, pr.Symbols, slog.Default())
@@ -429,16 +1155,18 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 		)
 	}
 
-	result.SymbolsExtracted = idx.Stats().TotalSymbols
-
 	// O-1: Log build statistics for observability
 	logAttrs := []any{
-		slog.String("project_root", projectRoot),
+		telemetry.SensitiveLog("project_root", projectRoot),
 		slog.Int("nodes", buildResult.Stats.NodesCreated),
 		slog.Int("edges", buildResult.Stats.EdgesCreated),
 		slog.Int("placeholders", buildResult.Stats.PlaceholderNodes),
 		slog.Int("call_edges_resolved", buildResult.Stats.CallEdgesResolved),
 		slog.Int("call_edges_unresolved", buildResult.Stats.CallEdgesUnresolved),
+		slog.Int("call_edges_inferred_type", buildResult.Stats.CallEdgesByInferredType),
+		slog.Int("call_edges_heuristic", buildResult.Stats.CallEdgesByHeuristic),
+		slog.Int("chain_hops_resolved", buildResult.Stats.ChainHopsResolved),
+		slog.Int("chain_hops_abandoned", buildResult.Stats.ChainHopsAbandoned),
 		slog.Int("interface_edges", buildResult.Stats.GoInterfaceEdges),
 		slog.Int64("build_duration_ms", buildResult.Stats.DurationMilli),
 	}
@@ -446,6 +1174,30 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 	if buildResult.Stats.DurationMilli == 0 && buildResult.Stats.DurationMicro > 0 {
 		logAttrs = append(logAttrs, slog.Int64("build_duration_us", buildResult.Stats.DurationMicro))
 	}
+	if buildResult.Stats.EdgesDisabled > 0 {
+		logAttrs = append(logAttrs, slog.Int("edges_disabled", buildResult.Stats.EdgesDisabled))
+	}
+	if len(g.GoModules) > 0 {
+		logAttrs = append(logAttrs,
+			slog.Int("go_modules", len(g.GoModules)),
+			slog.Int("cross_module_edges", buildResult.Stats.CrossModuleEdges),
+		)
+	}
+	if st := buildResult.Stats; st.CollapsedPlaceholderMembers+st.PlaceholderEdgesOmitted+st.StdlibPlaceholdersEnriched > 0 {
+		logAttrs = append(logAttrs,
+			slog.Int("placeholder_members_collapsed", st.CollapsedPlaceholderMembers),
+			slog.Int("placeholder_edges_omitted", st.PlaceholderEdgesOmitted),
+			slog.Int("stdlib_placeholders_enriched", st.StdlibPlaceholdersEnriched),
+		)
+	}
+	if st := buildResult.Stats; st.TemplateRenderEdgesResolved+st.TemplateIncludeEdgesResolved+st.CSSSelectorEdgesResolved+st.DOMSelectorEdgesResolved > 0 {
+		logAttrs = append(logAttrs,
+			slog.Int("template_render_edges", st.TemplateRenderEdgesResolved),
+			slog.Int("template_include_edges", st.TemplateIncludeEdgesResolved),
+			slog.Int("css_selector_edges", st.CSSSelectorEdgesResolved),
+			slog.Int("dom_selector_edges", st.DOMSelectorEdgesResolved),
+		)
+	}
 	logAttrs = append(logAttrs, slog.Bool("incomplete", buildResult.Incomplete))
 	slog.Info("GR-41c: Graph built", logAttrs...)
 
@@ -461,26 +1213,26 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 	hg, err := graph.WrapGraph(g)
 	if err != nil {
 		slog.Warn("GR-10: Failed to create hierarchical graph for adapter",
-			slog.String("project_root", projectRoot),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("project_root", projectRoot),
+			telemetry.SensitiveLogError("error", err),
 		)
 	} else {
 		adapter, err = graph.NewCRSGraphAdapter(hg, idx, 1, builtAtMilli, nil)
 		if err != nil {
 			slog.Warn("GR-10: Failed to create CRS graph adapter",
-				slog.String("project_root", projectRoot),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("project_root", projectRoot),
+				telemetry.SensitiveLogError("error", err),
 			)
 		} else {
 			slog.Info("GR-10: CRS graph adapter created successfully",
-				slog.String("project_root", projectRoot),
+				telemetry.SensitiveLog("project_root", projectRoot),
 				slog.Int("nodes", g.NodeCount()),
 				slog.Int("edges", g.EdgeCount()),
 			)
 
 			// P3: Log explicit graph readiness for easier debugging
 			slog.Info("graph ready for queries",
-				slog.String("project_root", projectRoot),
+				telemetry.SensitiveLog("project_root", projectRoot),
 				slog.Int("nodes", g.NodeCount()),
 				slog.Int("edges", g.EdgeCount()),
 				slog.Int64("build_time_us", buildResult.Stats.DurationMicro),
@@ -489,8 +1241,7 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 		}
 	}
 
-	// Cache the graph
-	cached := &CachedGraph{
+	return &CachedGraph{
 		Graph:           g,
 		Index:           idx,
 		Assembler:       assembler,
@@ -498,33 +1249,9 @@ func (s *Service) Init(ctx context.Context, projectRoot string, languages, exclu
 		BuiltAtMilli:    builtAtMilli,
 		ProjectRoot:     projectRoot,
 		EnrichmentStats: buildResult.Stats.LSPEnrichment,
+		ContentHash:     buildResult.Stats.ContentHash,
+		CommitSHA:       buildResult.Stats.CommitSHA,
 	}
-
-	if s.config.GraphTTL > 0 {
-		cached.ExpiresAtMilli = time.Now().Add(s.config.GraphTTL).UnixMilli()
-	}
-
-	s.mu.Lock()
-	s.graphs[graphID] = cached
-	s.evictIfNeeded()
-	s.mu.Unlock()
-
-	// CRS-18: Save graph snapshot for future incremental refresh.
-	s.saveGraphSnapshot(ctx, g)
-
-	// GR-77a: Materialize to bbolt for fast restart.
-	s.saveBboltSnapshot(ctx, g)
-
-	return &InitResponse{
-		GraphID:          graphID,
-		IsRefresh:        isRefresh,
-		PreviousID:       previousID,
-		FilesParsed:      result.FilesParsed,
-		SymbolsExtracted: result.SymbolsExtracted,
-		EdgesBuilt:       g.EdgeCount(),
-		ParseTimeMs:      time.Since(start).Milliseconds(),
-		Errors:           result.Errors,
-	}, nil
 }
 
 // saveGraphSnapshot saves a graph snapshot via the SnapshotManager if configured.
@@ -543,14 +1270,14 @@ func (s *Service) saveGraphSnapshot(ctx context.Context, g *graph.Graph) {
 	meta, err := s.snapshotMgr.Save(saveCtx, g, "")
 	if err != nil {
 		slog.Warn("CRS-18: Failed to save graph snapshot",
-			slog.String("project_root", g.ProjectRoot),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("project_root", g.ProjectRoot),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return
 	}
 	slog.Info("CRS-18: Graph snapshot saved",
 		slog.String("snapshot_id", meta.SnapshotID),
-		slog.String("project_root", g.ProjectRoot),
+		telemetry.SensitiveLog("project_root", g.ProjectRoot),
 		slog.Int("nodes", meta.NodeCount),
 		slog.Int("edges", meta.EdgeCount),
 	)
@@ -576,7 +1303,7 @@ func (s *Service) saveBboltSnapshot(ctx context.Context, g *graph.Graph) {
 	if err := os.MkdirAll(s.config.BboltDir, 0o755); err != nil {
 		slog.Warn("GR-77a: Failed to create bbolt directory",
 			slog.String("path", s.config.BboltDir),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return
 	}
@@ -586,16 +1313,16 @@ func (s *Service) saveBboltSnapshot(ctx context.Context, g *graph.Graph) {
 
 	if err := g.MaterializeToDisk(saveCtx, path); err != nil {
 		slog.Warn("GR-77a: Failed to materialize graph to bbolt",
-			slog.String("project_root", g.ProjectRoot),
-			slog.String("path", path),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("project_root", g.ProjectRoot),
+			telemetry.SensitiveLog("path", path),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return
 	}
 
 	slog.Info("GR-77a: Graph materialized to bbolt",
-		slog.String("project_root", g.ProjectRoot),
-		slog.String("path", path),
+		telemetry.SensitiveLog("project_root", g.ProjectRoot),
+		telemetry.SensitiveLog("path", path),
 		slog.Int("nodes", g.NodeCount()),
 		slog.Int("edges", g.EdgeCount()),
 	)
@@ -639,8 +1366,8 @@ func (s *Service) tryLoadFromBbolt(ctx context.Context, projectRoot string) (*gr
 	dg, err := graph.OpenDiskGraph(path)
 	if err != nil {
 		slog.Warn("GR-77a: Failed to open bbolt file, will be overwritten on next build",
-			slog.String("path", path),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("path", path),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, nil
 	}
@@ -649,14 +1376,14 @@ func (s *Service) tryLoadFromBbolt(ctx context.Context, projectRoot string) (*gr
 	loadedGraph, err := dg.LoadAsGraph(ctx)
 	if err != nil {
 		slog.Warn("GR-77a: Failed to load graph from bbolt, will be overwritten on next build",
-			slog.String("path", path),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("path", path),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, nil
 	}
 
 	slog.Info("GR-77a: Graph loaded from bbolt",
-		slog.String("project_root", projectRoot),
+		telemetry.SensitiveLog("project_root", projectRoot),
 		slog.Int("nodes", loadedGraph.NodeCount()),
 		slog.Int("edges", loadedGraph.EdgeCount()),
 	)
@@ -680,6 +1407,7 @@ func (s *Service) tryLoadFromBbolt(ctx context.Context, projectRoot string) (*gr
 //   - graphID: The graph ID for caching.
 //   - languages: Language filters for parsing.
 //   - excludes: Exclude patterns for parsing.
+//   - discovery: Ignore rules applied to changed files.
 //
 // Outputs:
 //   - *InitResponse: The init response if incremental refresh succeeded.
@@ -690,10 +1418,18 @@ func (s *Service) tryIncrementalRefresh(
 	ctx context.Context,
 	projectRoot, graphID string,
 	languages, excludes []string,
+	discovery DiscoveryOptions,
 ) (*InitResponse, error) {
 	if s.snapshotMgr == nil && s.config.BboltDir == "" {
 		return nil, nil
 	}
+	// Variant edges span files; a full build keeps them complete.
+	// Dependency source is not part of the snapshot, and a refresh would
+	// bring back the edges of disabled passes. Snapshots were parsed with
+	// the default parser settings, symlink and placeholder policies.
+	if discovery.AllBuildProfiles || discovery.ResolveDependencies || len(discovery.DisabledPasses) > 0 || len(discovery.ParserSettings) > 0 || !discovery.defaultSymlinks() || !discovery.defaultPlaceholders() {
+		return nil, nil
+	}
 
 	start := time.Now()
 
@@ -703,7 +1439,7 @@ func (s *Service) tryIncrementalRefresh(
 	if bboltGraph, err := s.tryLoadFromBbolt(ctx, projectRoot); err == nil && bboltGraph != nil {
 		baseGraph = bboltGraph
 		slog.Info("GR-77a: Using bbolt graph for incremental refresh",
-			slog.String("project_root", projectRoot),
+			telemetry.SensitiveLog("project_root", projectRoot),
 			slog.Int("nodes", baseGraph.NodeCount()),
 			slog.Int("edges", baseGraph.EdgeCount()),
 		)
@@ -723,8 +1459,8 @@ func (s *Service) tryIncrementalRefresh(
 		baseGraph, snapMeta, err = s.snapshotMgr.LoadLatest(ctx, projectHash)
 		if err != nil {
 			slog.Debug("CRS-18: No prior snapshot found",
-				slog.String("project_root", projectRoot),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("project_root", projectRoot),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return nil, nil
 		}
@@ -739,10 +1475,10 @@ func (s *Service) tryIncrementalRefresh(
 		snapshotTimeMilli = baseGraph.BuiltAtMilli
 	}
 	snapshotTime := time.UnixMilli(snapshotTimeMilli)
-	changedFiles, err := findChangedSourceFiles(ctx, projectRoot, snapshotTime, languages, excludes)
+	changedFiles, err := findChangedSourceFiles(ctx, projectRoot, snapshotTime, languages, excludes, discovery)
 	if err != nil {
 		slog.Debug("CRS-18: Failed to detect changed files, falling back to full build",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, nil
 	}
@@ -795,7 +1531,7 @@ func (s *Service) tryIncrementalRefresh(
 			slog.Int("nodes", baseGraph.NodeCount()),
 			slog.Int("edges", baseGraph.EdgeCount()),
 		)
-		return s.cacheAndReturn(ctx, baseGraph, projectRoot, graphID, start, 0, nil, nil)
+		return s.cacheAndReturn(ctx, baseGraph, projectRoot, graphID, start, 0, nil, nil, nil, nil)
 	}
 
 	// Parse only the changed files
@@ -814,14 +1550,16 @@ func (s *Service) tryIncrementalRefresh(
 		pr, parseErr := s.parseFileToResult(ctx, absPath, relPath)
 		if parseErr != nil {
 			slog.Debug("CRS-18: Failed to parse changed file, skipping",
-				slog.String("file", relPath),
-				slog.String("error", parseErr.Error()),
+				telemetry.SensitiveLog("file", relPath),
+				telemetry.SensitiveLogError("error", parseErr),
 			)
 			continue
 		}
 		changedResults = append(changedResults, pr)
 	}
 
+	changedResults = discovery.filterBuildProfile(changedResults)
+
 	// GR-76: Wire LSP enrichment config into incremental refresh.
 	lspConfig := s.buildLSPEnrichmentConfig(graphID)
 
@@ -829,7 +1567,7 @@ func (s *Service) tryIncrementalRefresh(
 	incrResult, err := graph.IncrementalRefresh(ctx, baseGraph, changedFiles, changedResults, lspConfig)
 	if err != nil {
 		slog.Warn("CRS-18: Incremental refresh failed, falling back to full build",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, nil
 	}
@@ -853,12 +1591,14 @@ func (s *Service) tryIncrementalRefresh(
 	}
 	s.mu.RUnlock()
 
-	return s.cacheAndReturn(ctx, incrResult.Graph, projectRoot, graphID, start, len(changedFiles), nil, mergedStats)
+	return s.cacheAndReturn(ctx, incrResult.Graph, projectRoot, graphID, start, len(changedFiles), nil, mergedStats, nil, nil)
 }
 
 // cacheAndReturn builds the CachedGraph, caches it, saves a snapshot, and returns InitResponse.
 //
 // GR-76: enrichmentStats is optional — if non-nil, stored on CachedGraph.
+// ws is nil for single-root graphs; workspace graphs record per-root stats
+// from rootFilesParsed and are not snapshotted.
 func (s *Service) cacheAndReturn(
 	ctx context.Context,
 	g *graph.Graph,
@@ -867,6 +1607,8 @@ func (s *Service) cacheAndReturn(
 	filesParsed int,
 	errs []string,
 	enrichmentStats *graph.EnrichmentStats,
+	ws *Workspace,
+	rootFilesParsed map[string]int,
 ) (*InitResponse, error) {
 	idx := index.NewSymbolIndex()
 
@@ -875,8 +1617,8 @@ func (s *Service) cacheAndReturn(
 		if node.Symbol != nil {
 			if addErr := idx.Add(node.Symbol); addErr != nil {
 				slog.Debug("CRS-18: Failed to add symbol to index",
-					slog.String("symbol_id", node.Symbol.ID),
-					slog.String("error", addErr.Error()),
+					telemetry.SensitiveLog("symbol_id", node.Symbol.ID),
+					telemetry.SensitiveLogError("error", addErr),
 				)
 			}
 		}
@@ -894,6 +1636,15 @@ func (s *Service) cacheAndReturn(
 		adapter, _ = graph.NewCRSGraphAdapter(hg, idx, 1, builtAtMilli, nil)
 	}
 
+	projectTools, toolsErr := recordProjectTools(projectRoot)
+	if toolsErr != nil {
+		errs = append(errs, toolsErr.Error())
+	}
+	annotationSet, annotationsErr := s.recordAnnotations(ctx, g, projectRoot, true)
+	if annotationsErr != nil {
+		errs = append(errs, annotationsErr.Error())
+	}
+
 	cached := &CachedGraph{
 		Graph:        g,
 		Index:        idx,
@@ -901,22 +1652,42 @@ func (s *Service) cacheAndReturn(
 		Adapter:      adapter,
 		BuiltAtMilli: builtAtMilli,
 		ProjectRoot:  projectRoot,
+		ContentHash:  g.ContentHash(),
+		OwnersHash:   recordOwners(g, projectRoot, ws),
+		Dependencies: recordDependencies(g, projectRoot, ws),
+		ProjectTools: projectTools,
+		Annotations:  annotationSet,
 	}
+	cached.HeadSHA, cached.RepoSubdir = detectGitHead(projectRoot)
 	// GR-76: Store enrichment stats if available.
 	if enrichmentStats != nil {
 		cached.EnrichmentStats = *enrichmentStats
 	}
+	if ws != nil {
+		cached.Workspace = ws
+		cached.RootStats = workspaceRootStats(g, ws, rootFilesParsed)
+	}
 	if s.config.GraphTTL > 0 {
 		cached.ExpiresAtMilli = time.Now().Add(s.config.GraphTTL).UnixMilli()
 	}
+	if filesParsed > 0 {
+		s.mu.RLock()
+		if prev, ok := s.graphs[graphID]; ok {
+			cached.IncrementalUpdates = prev.IncrementalUpdates
+		}
+		s.mu.RUnlock()
+		cached.IncrementalUpdates++
+	}
 
-	s.mu.Lock()
-	s.graphs[graphID] = cached
-	s.evictIfNeeded()
-	s.mu.Unlock()
+	if err := s.swapGraph(ctx, graphID, cached); err != nil {
+		return nil, err
+	}
+	s.maybeCompactGraph(graphID, cached)
 
 	// Save updated snapshot
-	s.saveGraphSnapshot(ctx, g)
+	if ws == nil {
+		s.saveGraphSnapshot(ctx, g)
+	}
 
 	return &InitResponse{
 		GraphID:          graphID,
@@ -926,16 +1697,18 @@ func (s *Service) cacheAndReturn(
 		EdgesBuilt:       g.EdgeCount(),
 		ParseTimeMs:      time.Since(start).Milliseconds(),
 		Errors:           errs,
+		Roots:            cached.RootStats,
 	}, nil
 }
 
 // findChangedSourceFiles detects files changed since a given time,
-// filtering to only source files matching the language and exclude filters.
+// filtering to only source files matching the language, exclude and ignore filters.
 func findChangedSourceFiles(
 	ctx context.Context,
 	projectRoot string,
 	since time.Time,
 	languages, excludes []string,
+	discovery DiscoveryOptions,
 ) ([]string, error) {
 	// Try git diff first (fast, reliable)
 	files, err := findChangedViaGit(ctx, projectRoot, since)
@@ -947,10 +1720,28 @@ func findChangedSourceFiles(
 		}
 	}
 
+	matcher, err := newIgnoreMatcher(projectRoot, discovery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("loading ignore rules: %w", err)
+	}
+
 	// Filter to source files matching language extensions
 	var filtered []string
 	extMap := buildLanguageExtMap(languages)
 	for _, f := range files {
+		// Skip paths matched by .gitignore/.traceignore/ignore_globs
+		if err := matcher.LoadParents(f); err != nil {
+			return nil, fmt.Errorf("loading ignore rules: %w", err)
+		}
+		if ignored, _ := matcher.Match(f, false); ignored {
+			continue
+		}
+		if discovery.SkipGenerated {
+			if generated, _ := ignore.IsGenerated(filepath.Join(projectRoot, f)); generated {
+				continue
+			}
+		}
+
 		// Skip excluded patterns
 		excluded := false
 		for _, pat := range excludes {
@@ -1056,22 +1847,250 @@ func buildLanguageExtMap(languages []string) map[string]struct{} {
 			extMap[".java"] = struct{}{}
 		case "rust":
 			extMap[".rs"] = struct{}{}
+		case "sql":
+			extMap[".sql"] = struct{}{}
+		case "proto":
+			extMap[".proto"] = struct{}{}
+		case "kotlin":
+			extMap[".kt"] = struct{}{}
+			extMap[".kts"] = struct{}{}
+		case "swift":
+			extMap[".swift"] = struct{}{}
+		case "php":
+			extMap[".php"] = struct{}{}
+		case "ruby":
+			extMap[".rb"] = struct{}{}
+		case "elixir":
+			extMap[".ex"] = struct{}{}
+			extMap[".exs"] = struct{}{}
+		case "html":
+			extMap[".html"] = struct{}{}
+			extMap[".htm"] = struct{}{}
+		case "css":
+			extMap[".css"] = struct{}{}
+		}
+	}
+	return extMap
+}
+
+// LanguageExtensions returns the file extensions discovered for the given
+// languages, sorted. Unknown languages contribute none.
+//
+// Inputs:
+//
+//	languages - Language names as accepted by /v1/trace/init.
+//
+// Outputs:
+//
+//	[]string - Extensions including the leading dot, e.g. ".go".
+func LanguageExtensions(languages []string) []string {
+	extMap := buildLanguageExtMap(languages)
+	exts := make([]string, 0, len(extMap))
+	for ext := range extMap {
+		exts = append(exts, ext)
+	}
+	slices.Sort(exts)
+	return exts
+}
+
+// parseResult holds intermediate parsing results.
+type parseResult struct {
+	FilesParsed      int
+	SymbolsExtracted int
+	Errors           []string
+
+	// SkippedFiles counts paths skipped by ignore rules. A skipped directory
+	// counts once; its contents are not walked.
+	SkippedFiles int
+
+	// SkippedByRule breaks SkippedFiles down by the rule that matched.
+	SkippedByRule map[string]int
+
+	// FileErrors are warnings about discovered files that were left out,
+	// such as a case-only path collision. Merged into the build's
+	// FileErrors.
+	FileErrors []graph.FileError
+
+	// PythonSourceRoots are the package directories declared by the
+	// pyproject.toml and setup.cfg files found during the walk, used to
+	// resolve Python imports.
+	PythonSourceRoots []graph.PythonSourceRoot
+
+	// GoModules are the Go modules declared by the go.mod and go.work
+	// files found during the walk, used to resolve Go imports between
+	// the project's modules.
+	GoModules []graph.GoModule
+}
+
+// recordSkip counts a path skipped by the named rule.
+func (r *parseResult) recordSkip(rule string) {
+	if r.SkippedByRule == nil {
+		r.SkippedByRule = make(map[string]int)
+	}
+	r.SkippedFiles++
+	r.SkippedByRule[rule]++
+}
+
+// DiscoveryOptions controls which files are considered during parsing.
+//
+// Description:
+//
+//	Ignore rules only apply at discovery time. Symbols already present in a
+//	built or snapshotted graph are not removed when rules change.
+type DiscoveryOptions struct {
+	// IgnoreGlobs are extra gitignore-syntax patterns applied after
+	// .gitignore and .traceignore.
+	IgnoreGlobs []string
+
+	// DisableGitignore stops .gitignore files from being honored.
+	// .traceignore is always honored.
+	DisableGitignore bool
+
+	// SkipGenerated skips files with a generated-code header
+	// (e.g. Go's "// Code generated ... DO NOT EDIT.").
+	SkipGenerated bool
+
+	// GOOS, GOARCH and BuildTags select the Go build profile. Go files
+	// whose build constraint does not match it are left out of the graph
+	// and the index. Empty GOOS and GOARCH default to the server's own.
+	GOOS      string
+	GOARCH    string
+	BuildTags []string
+
+	// AllBuildProfiles keeps Go files of every platform and links
+	// same-named declarations of different platforms with variant_of
+	// edges. GOOS, GOARCH and BuildTags are ignored.
+	AllBuildProfiles bool
+
+	// GitRef builds the graph from this commit-ish of the project's git
+	// repository instead of the working tree (see NewGitObjectSource).
+	// The graph gets its own graph ID per ref, is held in memory only and
+	// is always fully rebuilt. Ignored for workspaces.
+	GitRef string
+
+	// ResolveDependencies merges the source of the Go packages the project
+	// imports, from vendor/ or the module cache, into the graph (see
+	// resolveGoDependencies). The graph is held in memory only and is
+	// always fully rebuilt. Ignored for workspaces and GitRef builds.
+	ResolveDependencies bool
+
+	// DependencyDepth is how many import levels ResolveDependencies
+	// follows: 1 parses the packages the project imports, 2 also the
+	// packages those import, and so on. <= 0 means 1; capped at
+	// MaxDependencyDepth.
+	DependencyDepth int
+
+	// DisabledPasses are graph builder passes whose edges are left out
+	// (see graph.WithDisabledPasses). A graph built with any is held in
+	// memory only and is always fully rebuilt.
+	DisabledPasses []graph.EdgePass
+
+	// PlaceholderPolicy selects how the builder represents external
+	// symbols (see graph.WithPlaceholderPolicy). Empty means
+	// graph.PlaceholderFull. A graph built with another policy is held in
+	// memory only and is always fully rebuilt.
+	PlaceholderPolicy graph.PlaceholderPolicy
+
+	// ParserSettings configure the parsers of the languages they name
+	// for this build (see ast.ParserRegistry.Configure); other languages
+	// use the defaults. A graph built with any is held in memory only and
+	// is always fully rebuilt.
+	ParserSettings map[string]ast.ParserSettings
+
+	// FollowSymlinks selects which symlinks working-tree discovery
+	// follows. Empty means SymlinksInsideRoot. A graph built with another
+	// policy is held in memory only and is always fully rebuilt.
+	FollowSymlinks SymlinkPolicy
+}
+
+// sourceOptions returns the file system source options for the walk.
+func (d DiscoveryOptions) sourceOptions() []FileSystemSourceOption {
+	return []FileSystemSourceOption{WithSymlinkPolicy(d.FollowSymlinks)}
+}
+
+// defaultSymlinks reports whether the build follows symlinks the default
+// way, the only way incremental refresh and snapshots know.
+func (d DiscoveryOptions) defaultSymlinks() bool {
+	return d.FollowSymlinks == "" || d.FollowSymlinks == SymlinksInsideRoot
+}
+
+// defaultPlaceholders reports whether the build uses the default
+// placeholder policy, the only one snapshots are built with.
+func (d DiscoveryOptions) defaultPlaceholders() bool {
+	return d.PlaceholderPolicy == "" || d.PlaceholderPolicy == graph.PlaceholderFull
+}
+
+// buildProfile returns the Go build profile selected by the options.
+func (d DiscoveryOptions) buildProfile() ast.GoBuildProfile {
+	profile := ast.DefaultGoBuildProfile()
+	if d.GOOS != "" {
+		profile.GOOS = d.GOOS
+	}
+	if d.GOARCH != "" {
+		profile.GOARCH = d.GOARCH
+	}
+	profile.Tags = d.BuildTags
+	return profile
+}
+
+// builderOptions returns the graph builder options for the build profile.
+func (d DiscoveryOptions) builderOptions() []graph.BuilderOption {
+	opts := []graph.BuilderOption{graph.WithBuildProfile(d.buildProfile())}
+	if d.AllBuildProfiles {
+		opts = []graph.BuilderOption{graph.WithAllBuildProfiles()}
+	}
+	if len(d.DisabledPasses) > 0 {
+		opts = append(opts, graph.WithDisabledPasses(d.DisabledPasses...))
+	}
+	if d.PlaceholderPolicy != "" {
+		opts = append(opts, graph.WithPlaceholderPolicy(d.PlaceholderPolicy))
+	}
+	return opts
+}
+
+// filterBuildProfile drops the Go parse results outside the build profile,
+// as the graph builder does for a full build. Used for the changed files
+// of an incremental refresh.
+func (d DiscoveryOptions) filterBuildProfile(results []*ast.ParseResult) []*ast.ParseResult {
+	if d.AllBuildProfiles {
+		return results
+	}
+	profile := d.buildProfile()
+	kept := results[:0]
+	for _, pr := range results {
+		if pr != nil && pr.Language == "go" && !profile.Matches(pr.BuildConstraint) {
+			continue
 		}
+		kept = append(kept, pr)
 	}
-	return extMap
+	return kept
 }
 
-// parseResult holds intermediate parsing results.
-type parseResult struct {
-	FilesParsed      int
-	SymbolsExtracted int
-	Errors           []string
+// newIgnoreMatcher builds the ignore matcher for a discovery walk.
+func newIgnoreMatcher(projectRoot string, discovery DiscoveryOptions, source SourceProvider) (*ignore.Matcher, error) {
+	opts := ignore.Options{
+		UseGitignore:   !discovery.DisableGitignore,
+		UseTraceignore: true,
+		Globs:          discovery.IgnoreGlobs,
+	}
+	if source != nil {
+		opts.Open = func(relPath string) (io.ReadCloser, error) {
+			return source.Open(filepath.FromSlash(relPath))
+		}
+	}
+	return ignore.New(projectRoot, opts)
 }
 
 // fileEntry holds a file discovered during directory walk, pending parsing.
 type fileEntry struct {
-	absPath string
+	// srcPath is the file's path within its SourceProvider.
+	srcPath string
+
+	// relPath is the file path recorded in the graph.
 	relPath string
+
+	// symlinked is true when srcPath passes through a symlink.
+	symlinked bool
 }
 
 // parseProjectToResults parses project files and returns ParseResults for builder.
@@ -1083,92 +2102,209 @@ type fileEntry struct {
 //	CRS-23: Parallelized file parsing using a bounded goroutine pool.
 //	Phase 1 (sequential): Walk directory to collect file paths and enforce limits.
 //	Phase 2 (parallel): Parse files concurrently using runtime.NumCPU() workers.
+//	Files come from source: the working tree, or the blobs of a git commit.
 //
 // Inputs:
 //   - ctx: Context for cancellation
-//   - projectRoot: Absolute path to project root
+//   - source: The project's files (see NewFileSystemSource, NewGitObjectSource)
 //   - languages: Language filters
 //   - excludes: Exclusion patterns
+//   - discovery: Ignore rules (.gitignore, .traceignore, globs) and generated-file skipping
 //
 // Outputs:
 //   - []*ast.ParseResult: Parse results for all files, in walk order
-//   - *parseResult: Stats (FilesParsed, Errors)
+//   - *parseResult: Stats (FilesParsed, Errors, SkippedFiles)
 //   - error: Non-nil on fatal errors
 //
 // Thread Safety: Safe for concurrent use. Each file is parsed independently.
-func (s *Service) parseProjectToResults(ctx context.Context, projectRoot string, languages, excludes []string) ([]*ast.ParseResult, *parseResult, error) {
+func (s *Service) parseProjectToResults(ctx context.Context, source SourceProvider, languages, excludes []string, discovery DiscoveryOptions) ([]*ast.ParseResult, *parseResult, error) {
+	return s.parseRootToResults(ctx, source, "", languages, excludes, discovery, &walkBudget{})
+}
+
+// walkBudget tracks the files and bytes collected against the project
+// limits. Workspace builds share one budget across all roots.
+type walkBudget struct {
+	files int
+	size  int64
+}
+
+// parseRootToResults is parseProjectToResults for one root of a possibly
+// larger project. Exclude and ignore rules match paths relative to root;
+// file paths and symbol IDs are prefix joined with the path relative to root.
+func (s *Service) parseRootToResults(ctx context.Context, source SourceProvider, prefix string, languages, excludes []string, discovery DiscoveryOptions, budget *walkBudget) ([]*ast.ParseResult, *parseResult, error) {
 	result := &parseResult{
 		Errors: make([]string, 0),
 	}
 
+	parsers, err := s.registry.Configure(discovery.ParserSettings)
+	if err != nil {
+		return nil, result, err
+	}
+
+	matcher, err := newIgnoreMatcher("", discovery, source)
+	if err != nil {
+		return nil, result, fmt.Errorf("loading ignore rules: %w", err)
+	}
+
 	// --- Phase 1: Collect file paths (sequential) ---
 	// Walk the directory tree, enforce size/count limits, collect parseable files.
 	var files []fileEntry
-	var totalSize int64
-
-	err := filepath.WalkDir(projectRoot, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return nil // Skip files we can't access
-		}
+	// A file reachable by several paths (through symlinks or hard links)
+	// is parsed once, and of paths differing only in case only the first
+	// is parsed. Both map to an index in files.
+	byFileID := make(map[string]int)
+	byFoldedPath := make(map[string]int)
 
+	err = source.Walk(func(entry SourceEntry) error {
 		// Check context
 		if ctx.Err() != nil {
 			return ctx.Err()
 		}
 
+		relPath := entry.RelPath
+
 		// Skip directories
-		if d.IsDir() {
+		if entry.IsDir {
+			if entry.OutsideRoot {
+				result.recordSkip("symlink_outside_root")
+				return filepath.SkipDir
+			}
+			if entry.NotFollowed {
+				result.recordSkip("symlink_not_followed")
+				return filepath.SkipDir
+			}
 			// Skip excluded directories by matching both the relative path
 			// and the directory name itself. Bare names like "vendor" match
 			// the directory at any depth; glob patterns like "vendor/*" match
 			// the relPath for single-depth exclusions.
-			relPath, _ := filepath.Rel(projectRoot, path)
-			dirName := d.Name()
 			for _, pattern := range excludes {
 				if matched, _ := filepath.Match(pattern, relPath); matched {
+					result.recordSkip("exclude_patterns:" + pattern)
 					return filepath.SkipDir
 				}
-				if matched, _ := filepath.Match(pattern, dirName); matched {
+				if matched, _ := filepath.Match(pattern, entry.Name); matched {
+					result.recordSkip("exclude_patterns:" + pattern)
 					return filepath.SkipDir
 				}
 			}
+			if ignored, rule := matcher.Match(relPath, true); ignored {
+				result.recordSkip(rule)
+				return filepath.SkipDir
+			}
+			if loadErr := matcher.LoadDir(relPath); loadErr != nil {
+				result.Errors = append(result.Errors, loadErr.Error())
+			}
 			return nil
 		}
 
-		// Get relative path
-		relPath, err := filepath.Rel(projectRoot, path)
-		if err != nil {
+		// Packaging configs declare where Python packages live, which
+		// decides the module path Python imports are resolved against.
+		if slices.Contains(graph.PythonPackageConfigFiles, entry.Name) && !entry.OutsideRoot && !entry.NotFollowed && s.isLanguageFile(".py", languages) {
+			roots, readErr := readPythonSourceRoots(source, relPath, filepath.ToSlash(filepath.Join(prefix, relPath)))
+			if readErr != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", filepath.Join(prefix, relPath), readErr))
+			}
+			result.PythonSourceRoots = append(result.PythonSourceRoots, roots...)
+			return nil
+		}
+
+		// go.mod and go.work map module paths to project directories,
+		// which resolves imports between the modules of a repository.
+		if slices.Contains(graph.GoModuleConfigFiles, entry.Name) && !entry.OutsideRoot && !entry.NotFollowed && s.isLanguageFile(".go", languages) {
+			modules, readErr := readGoModules(source, relPath, filepath.ToSlash(filepath.Join(prefix, relPath)))
+			if readErr != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", filepath.Join(prefix, relPath), readErr))
+			}
+			result.GoModules = append(result.GoModules, modules...)
+			return nil
+		}
+
+		// Check file extension matches languages
+		ext := filepath.Ext(relPath)
+		if !s.isLanguageFile(ext, languages) {
+			return nil
+		}
+
+		// A symlinked file is read through its target; skip it when the
+		// target is outside the project.
+		if entry.OutsideRoot {
+			result.recordSkip("symlink_outside_root")
+			return nil
+		}
+		if entry.NotFollowed {
+			result.recordSkip("symlink_not_followed")
 			return nil
 		}
 
 		// Check exclusions
 		for _, pattern := range excludes {
 			if matched, _ := filepath.Match(pattern, relPath); matched {
+				result.recordSkip("exclude_patterns:" + pattern)
 				return nil
 			}
 		}
 
-		// Check file extension matches languages
-		ext := filepath.Ext(path)
-		if !s.isLanguageFile(ext, languages) {
+		// Check ignore rules (.gitignore, .traceignore, ignore_globs)
+		if ignored, rule := matcher.Match(relPath, false); ignored {
+			result.recordSkip(rule)
+			return nil
+		}
+
+		// Opt-in generated-file detection
+		if discovery.SkipGenerated {
+			if generated, genErr := isGeneratedSource(source, relPath); genErr == nil && generated {
+				result.recordSkip(ignore.GeneratedRule)
+				return nil
+			}
+		}
+
+		// Parse each file once, under the path without symlinks if it has
+		// one, so symbol IDs keep the path users see in the project.
+		if entry.FileID != "" {
+			if i, dup := byFileID[entry.FileID]; dup {
+				result.recordSkip("duplicate_file")
+				if files[i].symlinked && !entry.Symlinked {
+					if _, taken := byFoldedPath[strings.ToLower(relPath)]; !taken {
+						delete(byFoldedPath, strings.ToLower(files[i].srcPath))
+						byFoldedPath[strings.ToLower(relPath)] = i
+						files[i] = fileEntry{srcPath: relPath, relPath: filepath.Join(prefix, relPath)}
+					}
+				}
+				return nil
+			}
+		}
+
+		// Paths that differ only in case cannot coexist on a
+		// case-insensitive file system; keep the first.
+		if i, collides := byFoldedPath[strings.ToLower(relPath)]; collides {
+			result.recordSkip("case_collision")
+			result.FileErrors = append(result.FileErrors, graph.FileError{
+				FilePath: filepath.Join(prefix, relPath),
+				Err:      fmt.Errorf("%w: kept %s", ErrCaseCollision, files[i].relPath),
+			})
 			return nil
 		}
 
 		// Check limits
-		info, err := d.Info()
+		size, err := entry.Size()
 		if err != nil {
 			return nil
 		}
-		totalSize += info.Size()
-		if totalSize > s.config.MaxProjectSize {
+		budget.size += size
+		if budget.size > s.config.MaxProjectSize {
 			return ErrProjectTooLarge
 		}
 
-		if len(files) >= s.config.MaxProjectFiles {
+		if budget.files >= s.config.MaxProjectFiles {
 			return ErrProjectTooLarge
 		}
 
-		files = append(files, fileEntry{absPath: path, relPath: relPath})
+		budget.files++
+		if entry.FileID != "" {
+			byFileID[entry.FileID] = len(files)
+		}
+		byFoldedPath[strings.ToLower(relPath)] = len(files)
+		files = append(files, fileEntry{srcPath: relPath, relPath: filepath.Join(prefix, relPath), symlinked: entry.Symlinked})
 		return nil
 	})
 
@@ -1217,7 +2353,7 @@ func (s *Service) parseProjectToResults(ctx context.Context, projectRoot string,
 					return
 				}
 				f := files[idx]
-				pr, parseErr := s.parseFileToResult(ctx, f.absPath, f.relPath)
+				pr, parseErr := s.parseSourceFile(ctx, parsers, source, f.srcPath, f.relPath)
 				entries[idx] = parseEntry{result: pr, err: parseErr}
 			}
 		}()
@@ -1251,6 +2387,7 @@ func (s *Service) parseProjectToResults(ctx context.Context, projectRoot string,
 		slog.Int("workers", numWorkers),
 		slog.Int("parsed", result.FilesParsed),
 		slog.Int("errors", len(result.Errors)),
+		slog.Int("skipped", result.SkippedFiles),
 	)
 
 	// CR-23-2: Return partial results alongside context error so callers can
@@ -1295,18 +2432,110 @@ func (s *Service) parseFileToResult(ctx context.Context, absPath, relPath string
 	if err != nil {
 		return nil, err
 	}
+	return s.parseContentToResult(ctx, content, relPath)
+}
+
+// parseSourceFile is parseFileToResult for a file read from source.
+//
+// Inputs:
+//   - ctx: Context for cancellation. Passed to parser.Parse().
+//   - parsers: The parsers to use, by file extension.
+//   - source: The source the file belongs to.
+//   - srcPath: The file's path within source.
+//   - relPath: The file path recorded in the graph.
+//
+// Thread Safety: Safe for concurrent use if source is.
+func (s *Service) parseSourceFile(ctx context.Context, parsers *ast.ParserRegistry, source SourceProvider, srcPath, relPath string) (*ast.ParseResult, error) {
+	f, err := source.Open(srcPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+	return s.parseContentWith(ctx, parsers, content, relPath)
+}
+
+// isGeneratedSource reports whether the file at relPath in source carries
+// a generated-code header (see ignore.IsGenerated).
+func isGeneratedSource(source SourceProvider, relPath string) (bool, error) {
+	f, err := source.Open(relPath)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	return ignore.IsGeneratedReader(f, relPath)
+}
+
+// maxPythonPackageConfigSize caps how much of a pyproject.toml or
+// setup.cfg is read.
+const maxPythonPackageConfigSize = 1 << 20
+
+// readPythonSourceRoots reads the Python source roots a pyproject.toml
+// or setup.cfg declares. configPath is the file's path in the graph,
+// which the roots are resolved against.
+func readPythonSourceRoots(source SourceProvider, relPath, configPath string) ([]graph.PythonSourceRoot, error) {
+	f, err := source.Open(relPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxPythonPackageConfigSize))
+	if err != nil {
+		return nil, err
+	}
+	return graph.ParsePythonPackageConfig(configPath, data), nil
+}
+
+// maxGoModuleConfigSize caps how much of a go.mod or go.work is read.
+const maxGoModuleConfigSize = 1 << 20
+
+// readGoModules reads the Go modules a go.mod or go.work declares.
+// configPath is the file's path in the graph, which module directories
+// are resolved against.
+func readGoModules(source SourceProvider, relPath, configPath string) ([]graph.GoModule, error) {
+	f, err := source.Open(relPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxGoModuleConfigSize))
+	if err != nil {
+		return nil, err
+	}
+	return graph.ParseGoModuleConfig(configPath, data)
+}
 
+// parseContentToResult parses content with the parser registered for
+// relPath's extension. Results from generated files are flagged via
+// ast.ParseResult.MarkGenerated.
+func (s *Service) parseContentToResult(ctx context.Context, content []byte, relPath string) (*ast.ParseResult, error) {
+	return s.parseContentWith(ctx, s.registry, content, relPath)
+}
+
+// parseContentWith is parseContentToResult with the parser parsers
+// registers for relPath's extension.
+func (s *Service) parseContentWith(ctx context.Context, parsers *ast.ParserRegistry, content []byte, relPath string) (*ast.ParseResult, error) {
 	// Determine language from extension
 	ext := filepath.Ext(relPath)
 
 	// Get parser for file extension
-	parser, ok := s.registry.GetByExtension(ext)
+	parser, ok := parsers.GetByExtension(ext)
 	if !ok {
 		return nil, fmt.Errorf("no parser for extension: %s", ext)
 	}
 
 	// Parse the file
-	return parser.Parse(ctx, content, relPath)
+	result, err := parser.Parse(ctx, content, relPath)
+	if err != nil || result == nil {
+		return result, err
+	}
+	if s.generated.IsGenerated(relPath, content) {
+		result.MarkGenerated()
+	}
+	return result, nil
 }
 
 // isLanguageFile checks if a file extension matches any of the specified languages.
@@ -1376,10 +2605,10 @@ func addSymbolsToIndexRecursive(idx *index.SymbolIndex, symbols []*ast.Symbol, l
 		case errors.Is(err, index.ErrDuplicateSymbol):
 			// Expected for some patterns (re-exports, aliases). Log at debug level.
 			logger.Debug("index: duplicate symbol skipped",
-				slog.String("name", sym.Name),
+				telemetry.SensitiveLog("name", sym.Name),
 				slog.String("kind", sym.Kind.String()),
-				slog.String("id", sym.ID),
-				slog.String("file", sym.FilePath),
+				telemetry.SensitiveLog("id", sym.ID),
+				telemetry.SensitiveLog("file", sym.FilePath),
 			)
 			dropped++
 			// Still recurse children — they may have unique IDs
@@ -1394,11 +2623,11 @@ func addSymbolsToIndexRecursive(idx *index.SymbolIndex, symbols []*ast.Symbol, l
 			// Children may be poisoning parent validation. Strip children, retry parent,
 			// then add children independently.
 			logger.Warn("index: invalid symbol with children, retrying without children",
-				slog.String("name", sym.Name),
+				telemetry.SensitiveLog("name", sym.Name),
 				slog.String("kind", sym.Kind.String()),
-				slog.String("file", sym.FilePath),
+				telemetry.SensitiveLog("file", sym.FilePath),
 				slog.Int("children", len(sym.Children)),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			retried++
 			// Clone parent before stripping children to avoid mutating the original.
@@ -1411,10 +2640,10 @@ func addSymbolsToIndexRecursive(idx *index.SymbolIndex, symbols []*ast.Symbol, l
 				added++
 			} else {
 				logger.Error("index: symbol still invalid after stripping children",
-					slog.String("name", sym.Name),
+					telemetry.SensitiveLog("name", sym.Name),
 					slog.String("kind", sym.Kind.String()),
-					slog.String("file", sym.FilePath),
-					slog.String("error", retryErr.Error()),
+					telemetry.SensitiveLog("file", sym.FilePath),
+					telemetry.SensitiveLogError("error", retryErr),
 				)
 				dropped++
 			}
@@ -1426,10 +2655,10 @@ func addSymbolsToIndexRecursive(idx *index.SymbolIndex, symbols []*ast.Symbol, l
 
 		case errors.Is(err, index.ErrInvalidSymbol):
 			logger.Error("index: invalid symbol dropped",
-				slog.String("name", sym.Name),
+				telemetry.SensitiveLog("name", sym.Name),
 				slog.String("kind", sym.Kind.String()),
-				slog.String("file", sym.FilePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("file", sym.FilePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 			dropped++
 
@@ -1437,8 +2666,8 @@ func addSymbolsToIndexRecursive(idx *index.SymbolIndex, symbols []*ast.Symbol, l
 			// Count remaining symbols (including current) as dropped.
 			remaining := len(symbols) - i
 			logger.Warn("index: max symbols exceeded, stopping",
-				slog.String("name", sym.Name),
-				slog.String("file", sym.FilePath),
+				telemetry.SensitiveLog("name", sym.Name),
+				telemetry.SensitiveLog("file", sym.FilePath),
 				slog.Int("remaining_unprocessed", remaining),
 			)
 			dropped += remaining
@@ -1446,10 +2675,10 @@ func addSymbolsToIndexRecursive(idx *index.SymbolIndex, symbols []*ast.Symbol, l
 
 		default:
 			logger.Error("index: unexpected error adding symbol",
-				slog.String("name", sym.Name),
+				telemetry.SensitiveLog("name", sym.Name),
 				slog.String("kind", sym.Kind.String()),
-				slog.String("file", sym.FilePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("file", sym.FilePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 			dropped++
 		}
@@ -1526,7 +2755,7 @@ func (s *Service) FindCallers(ctx context.Context, graphID, functionName string,
 		// Resolve function name to symbol IDs first using secondary index
 		matches := cached.Graph.GetNodesByName(functionName)
 		slog.Debug("GR-10: FindCallers using adapter",
-			slog.String("function", functionName),
+			telemetry.SensitiveLog("function", functionName),
 			slog.Int("matches", len(matches)),
 			slog.Bool("adapter_available", true),
 		)
@@ -1539,20 +2768,20 @@ func (s *Service) FindCallers(ctx context.Context, graphID, functionName string,
 				continue
 			}
 			slog.Debug("GR-10: Querying callers for symbol",
-				slog.String("symbol_id", node.ID),
-				slog.String("symbol_name", node.Symbol.Name),
+				telemetry.SensitiveLog("symbol_id", node.ID),
+				telemetry.SensitiveLog("symbol_name", node.Symbol.Name),
 			)
 			// Use cached FindCallers from adapter
 			symbols, err := cached.Adapter.FindCallers(ctx, node.ID)
 			if err != nil {
 				slog.Debug("GR-10: FindCallers error",
-					slog.String("symbol_id", node.ID),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLog("symbol_id", node.ID),
+					telemetry.SensitiveLogError("error", err),
 				)
 				continue // Skip on error, try other matches
 			}
 			slog.Debug("GR-10: FindCallers result",
-				slog.String("symbol_id", node.ID),
+				telemetry.SensitiveLog("symbol_id", node.ID),
 				slog.Int("callers_found", len(symbols)),
 			)
 			for _, sym := range symbols {
@@ -1568,7 +2797,7 @@ func (s *Service) FindCallers(ctx context.Context, graphID, functionName string,
 		return callers, nil
 	} else {
 		slog.Debug("GR-10: FindCallers adapter not available, using direct query",
-			slog.String("function", functionName),
+			telemetry.SensitiveLog("function", functionName),
 		)
 	}
 
@@ -1630,6 +2859,46 @@ func (s *Service) FindImplementations(ctx context.Context, graphID, interfaceNam
 	return implementations, nil
 }
 
+// ExplainImplements explains whether a type implements an interface.
+//
+// Description:
+//
+//	Resolves the type and interface by ID or unique name and re-runs the
+//	builder's method-set comparison for the pair. See
+//	graph.Graph.ExplainImplements.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	graphID - ID of the graph to query
+//	typeRef - Type symbol ID or name
+//	ifaceRef - Interface symbol ID or name
+//
+// Outputs:
+//
+//	*graph.ImplementsExplanation - The per-method report
+//	error - Non-nil if the graph is not found, or wraps
+//	  graph.ErrSymbolNotFound or graph.ErrAmbiguousSymbol
+func (s *Service) ExplainImplements(ctx context.Context, graphID, typeRef, ifaceRef string) (*graph.ImplementsExplanation, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	typeID, err := cached.Graph.ResolveImplementsSymbol(typeRef, false)
+	if err != nil {
+		return nil, err
+	}
+	ifaceID, err := cached.Graph.ResolveImplementsSymbol(ifaceRef, true)
+	if err != nil {
+		return nil, err
+	}
+	return cached.Graph.ExplainImplements(typeID, ifaceID)
+}
+
 // FindCallees returns all functions called by the given function.
 //
 // Description:
@@ -1707,8 +2976,8 @@ func (s *Service) FindReferences(ctx context.Context, graphID, symbolName string
 		locations, err := cached.Graph.FindReferencesByID(ctx, node.ID, graph.WithLimit(limit-len(refs)))
 		if err != nil {
 			slog.Debug("FindReferences: error querying references for node",
-				slog.String("node_id", node.ID),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("node_id", node.ID),
+				telemetry.SensitiveLogError("error", err),
 			)
 			continue
 		}
@@ -1957,11 +3226,41 @@ func (s *Service) FindPath(ctx context.Context, graphID, from, to string) (*Call
 	}, nil
 }
 
+// QueryGraph runs a structured query against a graph.
+//
+// Description:
+//
+//	Evaluates q with graph.RunStructuredQuery. With explain set, nothing
+//	is traversed and a graph.QueryPlan estimating the work is returned
+//	instead.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	graphID - ID of the graph to query
+//	q - The structured query
+//	explain - Return the estimate instead of running the query
+//
+// Outputs:
+//
+//	any - *graph.StructuredQueryResult, or *graph.QueryPlan with explain
+//	error - Non-nil if graph not found; wraps graph.ErrInvalidQuery if q is invalid
+func (s *Service) QueryGraph(ctx context.Context, graphID string, q *graph.StructuredQuery, explain bool) (any, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	if explain {
+		return cached.Graph.ExplainStructuredQuery(q)
+	}
+	return cached.Graph.RunStructuredQuery(ctx, q)
+}
+
 // GetSymbol retrieves a symbol by its ID.
 //
 // Description:
 //
-//	Looks up a symbol in the graph by its unique ID.
+//	Looks up a symbol in the graph by its ID or stable ID.
 //
 // Inputs:
 //
@@ -1979,7 +3278,7 @@ func (s *Service) GetSymbol(ctx context.Context, graphID, symbolID string) (*Sym
 		return nil, err
 	}
 
-	sym, ok := cached.Index.GetByID(symbolID)
+	sym, ok := cached.Index.GetByID(cached.ResolveSymbolID(symbolID))
 	if !ok {
 		return nil, fmt.Errorf("symbol not found: %s", symbolID)
 	}
@@ -1987,6 +3286,56 @@ func (s *Service) GetSymbol(ctx context.Context, graphID, symbolID string) (*Sym
 	return SymbolInfoFromAST(sym), nil
 }
 
+// OwnersOf returns the CODEOWNERS owners of a file in a graph.
+//
+// Outputs:
+//
+//	[]string - The owners, or nil if the file has none or the project has
+//	  no CODEOWNERS file.
+//	error - Non-nil if the graph is not found.
+func (s *Service) OwnersOf(graphID, filePath string) ([]string, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+	return cached.Graph.OwnersOf(filePath), nil
+}
+
+// GetSymbolSource returns the source text of a symbol with context lines.
+//
+// Description:
+//
+//	Slices the symbol's StartLine-EndLine range plus contextLines on each
+//	side from the file on disk. Output is capped at
+//	graph.DefaultSymbolSourceMaxBytes with a truncation marker. The
+//	returned FileHash is comparable to ast.ParseResult.Hash so clients can
+//	detect that the file changed since the graph was built.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	graphID - ID of the graph to query
+//	symbolID - ID of the symbol to read
+//	contextLines - Lines of context before and after the symbol
+//
+// Outputs:
+//
+//	*graph.SymbolSource - The source slice
+//	error - ErrGraphNotInitialized, graph.ErrSymbolNotFound, or a read error
+func (s *Service) GetSymbolSource(ctx context.Context, graphID, symbolID string, contextLines int) (*graph.SymbolSource, error) {
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, ok := cached.Index.GetByID(cached.ResolveSymbolID(symbolID))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", graph.ErrSymbolNotFound, symbolID)
+	}
+
+	return graph.ReadSymbolSource(cached.Graph.ProjectRoot, sym, contextLines, graph.DefaultSymbolSourceMaxBytes)
+}
+
 // GetGraph retrieves a cached graph by ID.
 //
 // Description:
@@ -2025,8 +3374,26 @@ func (s *Service) GraphCount() int {
 	return len(s.graphs)
 }
 
-// validateProjectRoot validates the project root path.
+// validateProjectRoot validates the project root path and checks it
+// against the allowed roots. The allowlist is checked before the path is
+// required to exist, so a refused caller learns nothing about paths
+// outside its roots.
 func (s *Service) validateProjectRoot(projectRoot string) error {
+	if !filepath.IsAbs(projectRoot) {
+		return ErrRelativePath
+	}
+	if strings.Contains(projectRoot, "..") {
+		return ErrPathTraversal
+	}
+	if _, err := s.CheckRoot(projectRoot); err != nil {
+		return err
+	}
+	return validateRootPath(projectRoot)
+}
+
+// validateRootPath checks that projectRoot is an absolute, existing path
+// without traversal sequences.
+func validateRootPath(projectRoot string) error {
 	// Must be absolute
 	if !filepath.IsAbs(projectRoot) {
 		return ErrRelativePath
@@ -2037,27 +3404,40 @@ func (s *Service) validateProjectRoot(projectRoot string) error {
 		return ErrPathTraversal
 	}
 
-	// Resolve symlinks and verify still within allowed roots
-	resolved, err := filepath.EvalSymlinks(projectRoot)
-	if err != nil {
+	// Must resolve
+	if _, err := filepath.EvalSymlinks(projectRoot); err != nil {
 		return fmt.Errorf("resolve path: %w", err)
 	}
+	return nil
+}
 
-	// Check against allowlist if configured
-	if len(s.config.AllowedRoots) > 0 {
-		allowed := false
-		for _, root := range s.config.AllowedRoots {
-			if strings.HasPrefix(resolved, root) {
-				allowed = true
-				break
-			}
-		}
-		if !allowed {
-			return ErrPathTraversal
+// CheckRoot verifies that path is inside the allowed roots.
+//
+// Description:
+//
+//	Resolves symlinks before matching, so a symlink under an allowed
+//	root that points outside it is refused. With no AllowedRoots
+//	configured every path is allowed.
+//
+// Inputs:
+//
+//	path - The project root or file to check.
+//
+// Outputs:
+//
+//	string - The canonical path.
+//	error - ErrRootNotAllowed (wrapped) if no allowed root contains path.
+//
+// Thread Safety: Safe for concurrent use.
+func (s *Service) CheckRoot(path string) (string, error) {
+	canonical, err := s.roots.Check(path)
+	if err != nil {
+		if errors.Is(err, ErrRootNotAllowed) {
+			slog.Warn("project root outside allowed roots", telemetry.SensitiveLog("path", path))
 		}
+		return "", err
 	}
-
-	return nil
+	return canonical, nil
 }
 
 // generateGraphID creates a deterministic ID for a project.
@@ -2066,14 +3446,96 @@ func (s *Service) generateGraphID(projectRoot string) string {
 	return hex.EncodeToString(hash[:])[:16]
 }
 
-// getInitLock returns the init lock for a project.
-func (s *Service) getInitLock(projectRoot string) *sync.Mutex {
-	lock, _ := s.initLocks.LoadOrStore(projectRoot, &sync.Mutex{})
-	return lock.(*sync.Mutex)
+// projectGraphID returns the graph ID of a single-root project built from
+// the working tree, or from gitRef when it is not empty.
+func (s *Service) projectGraphID(projectRoot, gitRef string) string {
+	if gitRef == "" {
+		return s.generateGraphID(projectRoot)
+	}
+	return s.generateGraphID(projectRoot + "@" + gitRef)
+}
+
+// parseResultsGraphID returns the graph ID of a graph built from uploaded
+// parse results, which is distinct from that of the same root's working
+// tree.
+func (s *Service) parseResultsGraphID(projectRoot string) string {
+	return s.generateGraphID(projectRoot + "#parse-results")
+}
+
+// cachedGraphID returns the graph ID under which cached is stored.
+func (s *Service) cachedGraphID(cached *CachedGraph) string {
+	if cached.Workspace != nil {
+		return cached.Workspace.GraphID
+	}
+	if cached.FromParseResults {
+		return s.parseResultsGraphID(cached.ProjectRoot)
+	}
+	return s.projectGraphID(cached.ProjectRoot, cached.GitRef)
+}
+
+// swapGraph replaces the cached graph for graphID in one step. Readers see
+// either the old or the new CachedGraph, never a partially built one.
+// Nothing is installed if ctx is already done, so a cancelled build leaves
+// the previous graph in place.
+//
+// Tool results and memoized responses cached for the replaced or evicted
+// graphs are invalidated unless the new graph has the same content.
+func (s *Service) swapGraph(ctx context.Context, graphID string, cached *CachedGraph) error {
+	_, err := s.installGraph(ctx, graphID, nil, cached)
+	return err
+}
+
+// installGraph is swapGraph that, if expected is non-nil, installs cached
+// only while expected is still the cached graph for graphID. It reports
+// whether cached was installed.
+func (s *Service) installGraph(ctx context.Context, graphID string, expected, cached *CachedGraph) (bool, error) {
+	s.mu.Lock()
+	if err := ctx.Err(); err != nil {
+		s.mu.Unlock()
+		return false, err
+	}
+	prev, ok := s.graphs[graphID]
+	if expected != nil && prev != expected {
+		s.mu.Unlock()
+		return false, nil
+	}
+	var stale []string
+	if ok && graphGeneration(prev) != graphGeneration(cached) {
+		stale = append(stale, graphGeneration(prev))
+	}
+	s.graphs[graphID] = cached
+	stale = append(stale, s.evictIfNeeded()...)
+	s.mu.Unlock()
+
+	// Refreshes and compactions validate the region they changed.
+	if report := cached.Graph.Validation(); report != nil && (!ok || prev.Graph != cached.Graph) {
+		s.recordValidation(graphID, report)
+	}
+	s.countDegradedGraphs()
+	s.refreshProjectSummaries(ctx, cached)
+	if !ok || prev.Graph != cached.Graph {
+		s.invalidateFacts(ctx, cached)
+	}
+
+	for _, generation := range stale {
+		s.toolCache.Invalidate(context.WithoutCancel(ctx), generation)
+		if s.responseMemo != nil {
+			s.responseMemo.invalidate(generation)
+		}
+	}
+	if s.docEnricher != nil && cached.Graph != nil {
+		s.docEnricher.Trigger(graphID, cached.Graph)
+	}
+	if s.semanticIndex != nil && cached.Graph != nil {
+		s.semanticIndex.Trigger(graphID, cached.Graph)
+	}
+	return true, nil
 }
 
-// evictIfNeeded removes graphs if over capacity. Caller must hold write lock.
-func (s *Service) evictIfNeeded() {
+// evictIfNeeded removes graphs if over capacity and returns the
+// generations of the removed graphs. Caller must hold write lock.
+func (s *Service) evictIfNeeded() []string {
+	var evicted []string
 	for len(s.graphs) > s.config.MaxCachedGraphs {
 		// Find oldest graph
 		var oldestID string
@@ -2085,9 +3547,11 @@ func (s *Service) evictIfNeeded() {
 			}
 		}
 		if oldestID != "" {
+			evicted = append(evicted, graphGeneration(s.graphs[oldestID]))
 			delete(s.graphs, oldestID)
 		}
 	}
+	return evicted
 }
 
 // getFirstGraph returns the first cached graph, or nil if none exist.
@@ -2123,30 +3587,29 @@ func (s *Service) getFirstGraph() *CachedGraph {
 // PLAN STORAGE METHODS (CB-22b)
 // =============================================================================
 
-// StorePlan stores a change plan for later validation and preview.
+// StorePlan stores a change plan for later validation, preview and approval.
 //
 // Description:
 //
 //	Stores the plan with its associated graph ID so it can be retrieved
-//	for validation and preview. Plans expire after 1 hour.
+//	for validation and preview. A plan that requires approval starts
+//	pending; any other plan starts approved. Plans expire after
+//	ServiceConfig.PlanTTL. Emits a plan status event for the new plan.
 //
 // Inputs:
 //
 //	plan - The change plan (must have ID field)
+//	sessionID - The agent session the plan belongs to. May be empty.
+//	approval - Whether and why the plan needs approval.
+//
+// Outputs:
+//
+//	PlanSummary - The stored plan's summary.
 //
 // Thread Safety:
 //
 //	Safe for concurrent use.
-func (s *Service) StorePlan(plan interface{}) {
-	s.plansMu.Lock()
-	defer s.plansMu.Unlock()
-
-	// Extract plan ID and graph ID via reflection or type assertion
-	// For now we use a type switch
-	type planWithID interface {
-		GetID() string
-	}
-
+func (s *Service) StorePlan(plan interface{}, sessionID string, approval PlanApproval) PlanSummary {
 	var planID string
 	var graphID string
 
@@ -2160,26 +3623,44 @@ func (s *Service) StorePlan(plan interface{}) {
 		graphID = p.GetGraphID()
 	}
 
-	// Fallback: use the plan pointer address as ID
+	// Fallback: use the current time as ID
 	if planID == "" {
 		planID = fmt.Sprintf("plan_%d", time.Now().UnixNano())
 	}
 
-	s.plans[planID] = &CachedPlan{
+	now := time.Now()
+	cached := &CachedPlan{
 		GraphID:   graphID,
 		Plan:      plan,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+		SessionID: sessionID,
+		Status:    PlanStatusApproved,
+		Approval:  approval,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(s.planTTL()),
+	}
+	if approval.Required {
+		cached.Status = PlanStatusPending
 	}
 
+	s.plansMu.Lock()
+	s.plans[planID] = cached
+	summary := summarizePlan(planID, cached)
 	// Evict old plans (keep last 100)
-	s.evictOldPlans()
+	changes := s.evictOldPlans(now)
+	s.plansMu.Unlock()
+
+	s.emitPlanStatus(planStatusChange{summary: summary, reason: approval.Reason})
+	s.emitPlanStatus(changes...)
+	return summary
 }
 
 // GetPlan retrieves a stored change plan by ID.
 //
 // Description:
 //
-//	Returns the plan if found and not expired.
+//	Returns the plan if found and not expired, whatever its approval
+//	status, so pending plans can be validated and previewed.
 //
 // Inputs:
 //
@@ -2188,22 +3669,16 @@ func (s *Service) StorePlan(plan interface{}) {
 // Outputs:
 //
 //	interface{} - The plan (caller casts to *coordinate.ChangePlan)
-//	error - Non-nil if plan not found
+//	error - Non-nil if plan not found or expired
 func (s *Service) GetPlan(planID string) (interface{}, error) {
-	s.plansMu.RLock()
-	defer s.plansMu.RUnlock()
-
-	cached, ok := s.plans[planID]
-	if !ok {
-		return nil, fmt.Errorf("plan not found: %s", planID)
+	summary, plan, err := s.PlanRecord(planID)
+	if err != nil {
+		return nil, err
 	}
-
-	// Check expiry (1 hour)
-	if time.Since(cached.CreatedAt) > time.Hour {
+	if summary.Status == PlanStatusExpired {
 		return nil, fmt.Errorf("plan expired: %s", planID)
 	}
-
-	return cached.Plan, nil
+	return plan, nil
 }
 
 // GetGraphForPlan returns the graph associated with a plan.
@@ -2246,32 +3721,36 @@ func (s *Service) GetGraphForPlan(plan interface{}) (*CachedGraph, error) {
 	return s.GetGraph(graphID)
 }
 
-// evictOldPlans removes plans older than 1 hour or if over 100 plans.
-func (s *Service) evictOldPlans() {
-	maxPlans := 100
-	maxAge := time.Hour
+// evictOldPlans expires pending and approved plans past their expiry,
+// removes plans that ended more than planRetention ago, and removes the
+// oldest plans while over maxStoredPlans. Returns the expiry transitions
+// for the caller to emit after releasing the lock. Caller must hold
+// plansMu for writing.
+func (s *Service) evictOldPlans(now time.Time) []planStatusChange {
+	changes := s.expirePlansLocked(now)
 
-	// Remove expired plans
 	for id, cached := range s.plans {
-		if time.Since(cached.CreatedAt) > maxAge {
+		if cached.Status.IsFinal() && now.Sub(cached.UpdatedAt) > planRetention {
 			delete(s.plans, id)
 		}
 	}
 
 	// If still over limit, remove oldest
-	for len(s.plans) > maxPlans {
+	for len(s.plans) > maxStoredPlans {
 		var oldestID string
-		var oldestTime time.Time = time.Now()
+		var oldestTime time.Time = now
 		for id, cached := range s.plans {
 			if cached.CreatedAt.Before(oldestTime) {
 				oldestTime = cached.CreatedAt
 				oldestID = id
 			}
 		}
-		if oldestID != "" {
-			delete(s.plans, oldestID)
+		if oldestID == "" {
+			break
 		}
+		delete(s.plans, oldestID)
 	}
+	return changes
 }
 
 // =============================================================================
@@ -2709,8 +4188,8 @@ func (s *Service) LSPStatus(graphID string) (*LSPStatusResponse, error) {
 //
 // Description:
 //
-//	Gracefully shuts down all running LSP servers. Should be called
-//	when the service is being stopped.
+//	Cancels doc enrichment and semantic indexing and gracefully shuts
+//	down all running LSP servers. Should be called when the service is being stopped.
 //
 // Inputs:
 //
@@ -2724,6 +4203,13 @@ func (s *Service) LSPStatus(graphID string) (*LSPStatusResponse, error) {
 //
 //	Safe for concurrent use.
 func (s *Service) Close(ctx context.Context) error {
+	if s.docEnricher != nil {
+		s.docEnricher.Close()
+	}
+	if s.semanticIndex != nil {
+		s.semanticIndex.Close()
+	}
+
 	s.lspMu.Lock()
 	managers := make(map[string]*lsp.Manager)
 	for id, mgr := range s.lspManagers {
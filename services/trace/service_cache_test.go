@@ -16,6 +16,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
 )
 
 // TestGR70a_CacheHit_ReturnsCachedGraph verifies that a second Init call with
@@ -170,6 +172,58 @@ func TestGR70a_AgentInitAfterStartup_NoBuild(t *testing.T) {
 	})
 }
 
+// TestToolResultCache_InvalidatedOnRebuild verifies that tool results cached
+// for a graph are dropped when a rebuild changes its content, and kept when
+// the content is unchanged.
+func TestToolResultCache_InvalidatedOnRebuild(t *testing.T) {
+	tmpDir := t.TempDir()
+	writeTestGoFiles(t, tmpDir)
+
+	svc := NewService(DefaultServiceConfig())
+	ctx := context.Background()
+
+	resp, err := svc.Init(ctx, tmpDir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph failed: %v", err)
+	}
+	gen1 := cached.ContentHash
+	if gen1 == "" {
+		t.Fatal("expected a content hash")
+	}
+
+	cache := svc.ToolResultCache()
+	params := map[string]any{"function_name": "helper"}
+	cache.Put(ctx, gen1, "find_callers", params, &tools.Result{Success: true, OutputText: "main"})
+
+	// Unchanged content: results stay valid.
+	if _, err := svc.Init(ctx, tmpDir, []string{"go"}, nil, true); err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+	if _, ok := cache.Get(ctx, gen1, "find_callers", params); !ok {
+		t.Fatal("result dropped by a rebuild with unchanged content")
+	}
+
+	// Changed content: the old generation is invalidated.
+	extra := filepath.Join(tmpDir, "extra.go")
+	if err := os.WriteFile(extra, []byte("package main\n\nfunc extra() { helper() }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.Init(ctx, tmpDir, []string{"go"}, nil, true); err != nil {
+		t.Fatalf("rebuild failed: %v", err)
+	}
+	rebuilt, _ := svc.GetGraph(resp.GraphID)
+	if rebuilt.ContentHash == gen1 {
+		t.Fatal("expected content hash to change")
+	}
+	if _, ok := cache.Get(ctx, gen1, "find_callers", params); ok {
+		t.Error("stale result survived a rebuild")
+	}
+}
+
 // writeTestGoFiles creates a minimal Go project for testing.
 func writeTestGoFiles(t *testing.T, dir string) {
 	t.Helper()
@@ -15,7 +15,11 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 )
 
 // TestCRS23_ParallelParseProjectToResults verifies that parallel file parsing
@@ -54,7 +58,7 @@ func (h *Handler) Handle() {}
 	svc := NewService(DefaultServiceConfig())
 	ctx := context.Background()
 
-	results, stats, err := svc.parseProjectToResults(ctx, tmpDir, []string{"go"}, nil)
+	results, stats, err := svc.parseProjectToResults(ctx, NewFileSystemSource(tmpDir), []string{"go"}, nil, DiscoveryOptions{})
 	if err != nil {
 		t.Fatalf("parseProjectToResults failed: %v", err)
 	}
@@ -99,7 +103,7 @@ func TestCRS23_ParallelParseContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // Cancel immediately
 
-	results, stats, err := svc.parseProjectToResults(ctx, tmpDir, []string{"go"}, nil)
+	results, stats, err := svc.parseProjectToResults(ctx, NewFileSystemSource(tmpDir), []string{"go"}, nil, DiscoveryOptions{})
 	// Either nil error (walk completed before ctx check) or a wrapped
 	// context.Canceled is acceptable. The key assertion: no panic.
 	if err != nil && !errors.Is(err, context.Canceled) {
@@ -121,7 +125,7 @@ func TestCRS23_ParallelParseEmptyProject(t *testing.T) {
 	svc := NewService(DefaultServiceConfig())
 	ctx := context.Background()
 
-	results, stats, err := svc.parseProjectToResults(ctx, tmpDir, []string{"go"}, nil)
+	results, stats, err := svc.parseProjectToResults(ctx, NewFileSystemSource(tmpDir), []string{"go"}, nil, DiscoveryOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -150,7 +154,7 @@ func TestCRS23_ParallelParseExclusions(t *testing.T) {
 	svc := NewService(DefaultServiceConfig())
 	ctx := context.Background()
 
-	results, stats, err := svc.parseProjectToResults(ctx, tmpDir, []string{"go"}, []string{"*_test.go"})
+	results, stats, err := svc.parseProjectToResults(ctx, NewFileSystemSource(tmpDir), []string{"go"}, []string{"*_test.go"}, DiscoveryOptions{})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -182,7 +186,7 @@ func TestCRS23_ParallelParseDeterministicOrder(t *testing.T) {
 	// Run 5 times and verify order is consistent
 	var firstOrder []string
 	for run := 0; run < 5; run++ {
-		results, _, err := svc.parseProjectToResults(ctx, tmpDir, []string{"go"}, nil)
+		results, _, err := svc.parseProjectToResults(ctx, NewFileSystemSource(tmpDir), []string{"go"}, nil, DiscoveryOptions{})
 		if err != nil {
 			t.Fatalf("run %d: %v", run, err)
 		}
@@ -225,7 +229,7 @@ func TestCRS23_ParallelParseMaxFilesLimit(t *testing.T) {
 	svc := NewService(cfg)
 	ctx := context.Background()
 
-	_, _, err := svc.parseProjectToResults(ctx, tmpDir, []string{"go"}, nil)
+	_, _, err := svc.parseProjectToResults(ctx, NewFileSystemSource(tmpDir), []string{"go"}, nil, DiscoveryOptions{})
 	if err != ErrProjectTooLarge {
 		t.Fatalf("expected ErrProjectTooLarge, got %v", err)
 	}
@@ -251,8 +255,404 @@ func TestCRS23_ParallelParseMaxProjectSizeLimit(t *testing.T) {
 	svc := NewService(cfg)
 	ctx := context.Background()
 
-	_, _, err := svc.parseProjectToResults(ctx, tmpDir, []string{"go"}, nil)
+	_, _, err := svc.parseProjectToResults(ctx, NewFileSystemSource(tmpDir), []string{"go"}, nil, DiscoveryOptions{})
 	if err != ErrProjectTooLarge {
 		t.Fatalf("expected ErrProjectTooLarge, got %v", err)
 	}
 }
+
+// TestParseProjectToResults_IgnoreRules verifies .gitignore, .traceignore,
+// ignore_globs and opt-in generated-file skipping, with per-rule skip stats.
+func TestParseProjectToResults_IgnoreRules(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		".gitignore":               "node_modules/\n",
+		".traceignore":             "*_mock.go\n",
+		"main.go":                  "package main\n\nfunc main() {}\n",
+		"store_mock.go":            "package main\n\nfunc mock() {}\n",
+		"gen/api.go":               "package gen\n\nfunc API() {}\n",
+		"api.pb.go":                "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage main\n\nfunc pb() {}\n",
+		"node_modules/dep/dep.go":  "package dep\n\nfunc Dep() {}\n",
+		"node_modules/dep/dep2.go": "package dep\n\nfunc Dep2() {}\n",
+	}
+	for relPath, content := range files {
+		absPath := filepath.Join(tmpDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(absPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	ctx := context.Background()
+
+	_, stats, err := svc.parseProjectToResults(ctx, NewFileSystemSource(tmpDir), []string{"go"}, nil, DiscoveryOptions{
+		IgnoreGlobs: []string{"gen/"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// main.go + api.pb.go (generated detection is opt-in)
+	if stats.FilesParsed != 2 {
+		t.Errorf("expected 2 files parsed, got %d", stats.FilesParsed)
+	}
+	want := map[string]int{
+		".gitignore:node_modules/": 1,
+		".traceignore:*_mock.go":   1,
+		"ignore_globs:gen/":        1,
+	}
+	for rule, n := range want {
+		if stats.SkippedByRule[rule] != n {
+			t.Errorf("SkippedByRule[%q] = %d, want %d (all: %v)", rule, stats.SkippedByRule[rule], n, stats.SkippedByRule)
+		}
+	}
+	if stats.SkippedFiles != 3 {
+		t.Errorf("SkippedFiles = %d, want 3", stats.SkippedFiles)
+	}
+
+	_, stats, err = svc.parseProjectToResults(ctx, NewFileSystemSource(tmpDir), []string{"go"}, nil, DiscoveryOptions{
+		DisableGitignore: true,
+		SkipGenerated:    true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// main.go + gen/api.go + node_modules/dep/*.go
+	if stats.FilesParsed != 4 {
+		t.Errorf("expected 4 files parsed with gitignore disabled, got %d", stats.FilesParsed)
+	}
+	if stats.SkippedByRule["generated"] != 1 {
+		t.Errorf("expected generated file skipped, got %v", stats.SkippedByRule)
+	}
+}
+
+// TestInit_SQLMigrationsLinkedToGo verifies that a project initialized with
+// the "sql" language extracts tables from its migration directories and that
+// FindReferences on a table returns the Go functions querying it.
+func TestInit_SQLMigrationsLinkedToGo(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"db/migrations/000001_users.up.sql":   "CREATE TABLE users (id BIGINT PRIMARY KEY, email TEXT);\n",
+		"db/migrations/000001_users.down.sql": "DROP TABLE users;\n",
+		"store/users.go": `package store
+
+func FindByEmail(db DB, email string) error {
+	return db.QueryRow("SELECT id FROM users WHERE email = $1", email).Err()
+}
+`,
+	}
+	for relPath, content := range files {
+		absPath := filepath.Join(tmpDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(absPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	ctx := context.Background()
+
+	resp, err := svc.Init(ctx, tmpDir, []string{"go", "sql"}, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+
+	refs, err := svc.FindReferences(ctx, resp.GraphID, "users", 0)
+	if err != nil {
+		t.Fatalf("FindReferences: %v", err)
+	}
+	found := false
+	for _, ref := range refs {
+		if ref.FilePath == filepath.Join("store", "users.go") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a reference to users from store/users.go, got %+v", refs)
+	}
+}
+
+// TestInit_BuildProfile verifies that a Go build profile keeps files of
+// other platforms out of both the graph and the symbol index.
+func TestInit_BuildProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"fsys/open_linux.go":   "package fsys\n\nfunc OpenLinux() {}\n",
+		"fsys/open_windows.go": "package fsys\n\nfunc OpenWindows() {}\n",
+	}
+	for relPath, content := range files {
+		absPath := filepath.Join(tmpDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(absPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	ctx := context.Background()
+
+	resp, err := svc.InitWithDiscovery(ctx, tmpDir, []string{"go"}, nil, DiscoveryOptions{GOOS: "windows", GOARCH: "amd64"}, true)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph: %v", err)
+	}
+	if len(cached.Index.GetByName("OpenLinux")) != 0 || len(cached.Graph.GetNodesByName("OpenLinux")) != 0 {
+		t.Error("linux-only symbol should be excluded for windows")
+	}
+	if len(cached.Index.GetByName("OpenWindows")) != 1 || len(cached.Graph.GetNodesByName("OpenWindows")) != 1 {
+		t.Error("windows symbol should be indexed and in the graph")
+	}
+}
+
+// TestInit_GoModules verifies that imports between the modules of a go.work
+// repository, and of a module replaced by a project directory, resolve to
+// the project's source.
+func TestInit_GoModules(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"go.work":                           "go 1.22\n\nuse (\n\t./api\n\t./billing\n)\n",
+		"api/go.mod":                        "module example.com/acme/api\n\ngo 1.22\n\nrequire example.com/ledger v1.0.0\n\nreplace example.com/ledger => ../third_party/ledger-fork\n",
+		"billing/go.mod":                    "module example.com/acme/billing\n\ngo 1.22\n",
+		"third_party/ledger-fork/go.mod":    "module github.com/someone/ledger-fork\n\ngo 1.22\n",
+		"billing/invoice/invoice.go":        "package invoice\n\nfunc Total() int {\n\treturn 1\n}\n",
+		"third_party/ledger-fork/ledger.go": "package ledger\n\nfunc Post() {}\n",
+		"legacy/invoice/invoice.go":         "package invoice\n\nfunc Total() int {\n\treturn 0\n}\n",
+		"api/handler/handler.go": `package handler
+
+import (
+	"example.com/acme/billing/invoice"
+	"example.com/ledger"
+)
+
+func Handle() int {
+	ledger.Post()
+	return invoice.Total()
+}
+`,
+	}
+	for relPath, content := range files {
+		absPath := filepath.Join(tmpDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(absPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), tmpDir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if resp.GoModules != 4 || resp.CrossModuleEdges != 4 {
+		t.Errorf("go_modules = %d, cross_module_edges = %d; want 4 and 4", resp.GoModules, resp.CrossModuleEdges)
+	}
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph: %v", err)
+	}
+
+	handle := cached.Graph.GetNodesByName("Handle")
+	if len(handle) != 1 {
+		t.Fatalf("got %d Handle nodes, want 1", len(handle))
+	}
+	var callees []string
+	for _, e := range handle[0].Outgoing {
+		if e.Type == graph.EdgeTypeCalls {
+			callees = append(callees, e.ToID)
+		}
+	}
+	sort.Strings(callees)
+	want := []string{"billing/invoice/invoice.go:3:Total", "third_party/ledger-fork/ledger.go:3:Post"}
+	if strings.Join(callees, ",") != strings.Join(want, ",") {
+		t.Errorf("Handle calls %v, want %v", callees, want)
+	}
+
+	var imports []string
+	for _, e := range cached.Graph.GetEdgesByType(graph.EdgeTypeImports) {
+		if strings.HasPrefix(e.FromID, "api/handler/") && e.Provenance.Detail == "go-module" {
+			imports = append(imports, e.ToID)
+		}
+	}
+	sort.Strings(imports)
+	want = []string{"billing/invoice/invoice.go:1:invoice", "third_party/ledger-fork/ledger.go:1:ledger"}
+	if strings.Join(imports, ",") != strings.Join(want, ",") {
+		t.Errorf("handler imports %v, want %v", imports, want)
+	}
+	importers, err := cached.Graph.FindImporters(context.Background(), "example.com/acme/billing/invoice")
+	if err != nil || len(importers) != 1 || importers[0] != "api/handler/handler.go" {
+		t.Errorf("FindImporters = %v, %v", importers, err)
+	}
+}
+
+// TestInit_PythonNamespaceRoots verifies that package directories declared
+// in pyproject.toml decide which same-named module a Python import names.
+func TestInit_PythonNamespaceRoots(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"libs/core/pyproject.toml":     "[tool.setuptools.package-dir]\n\"\" = \"src\"\n",
+		"libs/core/src/acme/models.py": "class Order:\n    pass\n",
+		"legacy/acme/models.py":        "class Order:\n    pass\n",
+		"app/views.py":                 "from acme.models import Order\n\n\ndef checkout():\n    return Order()\n",
+	}
+	for relPath, content := range files {
+		absPath := filepath.Join(tmpDir, relPath)
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(absPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.Init(context.Background(), tmpDir, []string{"python"}, nil)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatalf("GetGraph: %v", err)
+	}
+	if roots := cached.Graph.PythonSourceRoots; len(roots) != 1 || roots[0].Dir != "libs/core/src" {
+		t.Fatalf("PythonSourceRoots = %+v", roots)
+	}
+
+	orders := cached.Graph.GetNodesByName("Order")
+	if len(orders) != 2 {
+		t.Fatalf("got %d Order nodes, want 2", len(orders))
+	}
+	for _, node := range orders {
+		var fromViews []string
+		for _, e := range node.Incoming {
+			if strings.HasPrefix(e.FromID, "app/views.py") {
+				fromViews = append(fromViews, e.Type.String()+" "+e.Provenance.String())
+				if e.Provenance.IsAmbiguousImport() {
+					t.Errorf("edge %s → %s marked ambiguous", e.FromID, e.ToID)
+				}
+			}
+		}
+		switch node.Symbol.FilePath {
+		case "libs/core/src/acme/models.py":
+			if len(fromViews) == 0 {
+				t.Error("no edge from app/views.py to the declared package's Order")
+			}
+		default:
+			if len(fromViews) != 0 {
+				t.Errorf("edges from app/views.py to %s: %v", node.Symbol.FilePath, fromViews)
+			}
+		}
+	}
+}
+
+// TestParseProject_SymlinkedDirectories verifies that each symlink policy
+// follows the directories it should, that a file reached through a link
+// and directly is parsed once under its direct path, and that a link to
+// an ancestor does not loop.
+func TestParseProject_SymlinkedDirectories(t *testing.T) {
+	root, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside := t.TempDir()
+	writeWorkspaceFiles(t, root, map[string]string{
+		"pkg/a.go":  "package pkg\n\nfunc A() {}\n",
+		"real/c.go": "package real\n\nfunc C() {}\n",
+	})
+	writeWorkspaceFiles(t, outside, map[string]string{"ext.go": "package ext\n\nfunc Ext() {}\n"})
+	for link, target := range map[string]string{
+		"alias":  filepath.Join(root, "pkg"),  // visited before pkg
+		"shared": filepath.Join(root, "real"), // real/ itself is ignored
+		"ext":    outside,
+		"loop":   root,
+	} {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	tests := []struct {
+		policy    SymlinkPolicy
+		wantFiles []string
+		wantSkips map[string]int
+	}{
+		{"", []string{"pkg/a.go", "shared/c.go"}, map[string]int{"duplicate_file": 1, "symlink_outside_root": 1}},
+		{SymlinksAlways, []string{"ext/ext.go", "pkg/a.go", "shared/c.go"}, map[string]int{"duplicate_file": 1}},
+		{SymlinksNever, []string{"pkg/a.go"}, map[string]int{"symlink_not_followed": 4}},
+	}
+	for _, tt := range tests {
+		name := string(tt.policy)
+		if name == "" {
+			name = "default"
+		}
+		t.Run(name, func(t *testing.T) {
+			discovery := DiscoveryOptions{IgnoreGlobs: []string{"/real/"}, FollowSymlinks: tt.policy}
+			results, stats, err := svc.parseProjectToResults(context.Background(), NewFileSystemSource(root, discovery.sourceOptions()...), []string{"go"}, nil, discovery)
+			if err != nil {
+				t.Fatalf("parseProjectToResults: %v", err)
+			}
+			var files []string
+			for _, pr := range results {
+				files = append(files, filepath.ToSlash(pr.FilePath))
+			}
+			sort.Strings(files)
+			if strings.Join(files, ",") != strings.Join(tt.wantFiles, ",") {
+				t.Errorf("parsed %v, want %v", files, tt.wantFiles)
+			}
+			for rule, n := range tt.wantSkips {
+				if stats.SkippedByRule[rule] != n {
+					t.Errorf("SkippedByRule[%q] = %d, want %d (all: %v)", rule, stats.SkippedByRule[rule], n, stats.SkippedByRule)
+				}
+			}
+		})
+	}
+}
+
+// TestInit_CaseCollision verifies that of two paths differing only in
+// case, one is parsed and the other is reported as a file error.
+func TestInit_CaseCollision(t *testing.T) {
+	root := t.TempDir()
+	writeWorkspaceFiles(t, root, map[string]string{
+		"Util.go": "package main\n\nfunc Upper() {}\n",
+		"util.go": "package main\n\nfunc Lower() {}\n",
+	})
+	if entries, err := os.ReadDir(root); err != nil || len(entries) != 2 {
+		t.Skip("file system is case-insensitive")
+	}
+
+	svc := NewService(DefaultServiceConfig())
+	_, stats, err := svc.parseProjectToResults(context.Background(), NewFileSystemSource(root), []string{"go"}, nil, DiscoveryOptions{})
+	if err != nil {
+		t.Fatalf("parseProjectToResults: %v", err)
+	}
+	if stats.FilesParsed != 1 || stats.SkippedByRule["case_collision"] != 1 {
+		t.Errorf("FilesParsed = %d, skips = %v", stats.FilesParsed, stats.SkippedByRule)
+	}
+	if len(stats.FileErrors) != 1 || stats.FileErrors[0].FilePath != "util.go" || !errors.Is(stats.FileErrors[0], ErrCaseCollision) {
+		t.Fatalf("FileErrors = %v", stats.FileErrors)
+	}
+
+	resp, err := svc.InitWithDiscovery(context.Background(), root, []string{"go"}, nil, DiscoveryOptions{}, true)
+	if err != nil {
+		t.Fatalf("InitWithDiscovery: %v", err)
+	}
+	if len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0], "kept Util.go") {
+		t.Errorf("Errors = %v", resp.Errors)
+	}
+}
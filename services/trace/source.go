@@ -0,0 +1,461 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	posixpath "path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/validation"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// SourceProvider supplies the files of one project root to a build.
+//
+// Description:
+//
+//	The parse phase walks a SourceProvider instead of the filesystem, so
+//	a graph can be built from the working tree (NewFileSystemSource) or
+//	from the objects of a git commit (NewGitObjectSource) without a
+//	checkout. Ignore files, generated-file detection and parsing all read
+//	through Open, so the same discovery rules apply to both.
+//
+// Thread Safety: Implementations must be safe for concurrent use of Open.
+type SourceProvider interface {
+	// Walk calls fn for the root, then for every directory and file below
+	// it, parents before children. fn returning fs.SkipDir for a
+	// directory skips its contents; any other error stops the walk and is
+	// returned.
+	Walk(fn func(entry SourceEntry) error) error
+
+	// Open opens the file at relPath (as in SourceEntry.RelPath). A
+	// missing file is reported with an error wrapping fs.ErrNotExist.
+	Open(relPath string) (io.ReadCloser, error)
+
+	// Revision returns the commit SHA the files are read at, or "" when
+	// they are read from the working tree.
+	Revision() string
+}
+
+// SourceEntry is a directory or file visited by SourceProvider.Walk.
+type SourceEntry struct {
+	// RelPath is the path relative to the root in filepath form; "." for
+	// the root itself.
+	RelPath string
+
+	// Name is the last element of the path. For the root it is the name
+	// of the project root directory.
+	Name string
+
+	// IsDir is true for directories.
+	IsDir bool
+
+	// OutsideRoot is true for a symlinked file or directory whose target
+	// is outside the root. Such files are not parsed and such directories
+	// are not descended into.
+	OutsideRoot bool
+
+	// NotFollowed is true for a symlink the source's SymlinkPolicy does
+	// not follow. It is treated like OutsideRoot.
+	NotFollowed bool
+
+	// Symlinked is true when the path is a symlink or lies in a
+	// symlinked directory.
+	Symlinked bool
+
+	// FileID identifies the file the path leads to: device and inode
+	// where the platform has them, else the canonical path. Two entries
+	// with the same FileID are the same file reached by different paths.
+	// Empty for directories and for sources without links.
+	FileID string
+
+	size func() (int64, error)
+}
+
+// Size returns the file's size in bytes.
+func (e SourceEntry) Size() (int64, error) {
+	if e.size == nil {
+		return 0, nil
+	}
+	return e.size()
+}
+
+// SymlinkPolicy selects which symbolic links a file system source follows.
+type SymlinkPolicy string
+
+const (
+	// SymlinksInsideRoot follows a symlinked file or directory only when
+	// its target resolves inside the project root. The default.
+	SymlinksInsideRoot SymlinkPolicy = "inside_root"
+
+	// SymlinksAlways follows every symlink, wherever its target is.
+	SymlinksAlways SymlinkPolicy = "always"
+
+	// SymlinksNever follows no symlink. Symlinked files and directories
+	// are skipped.
+	SymlinksNever SymlinkPolicy = "never"
+)
+
+// Valid reports whether p is a known policy. The empty policy is valid
+// and means SymlinksInsideRoot.
+func (p SymlinkPolicy) Valid() bool {
+	switch p {
+	case "", SymlinksInsideRoot, SymlinksAlways, SymlinksNever:
+		return true
+	}
+	return false
+}
+
+// fileSystemSource reads files under a directory on disk.
+type fileSystemSource struct {
+	root     string
+	symlinks SymlinkPolicy
+}
+
+// FileSystemSourceOption configures NewFileSystemSource.
+type FileSystemSourceOption func(*fileSystemSource)
+
+// WithSymlinkPolicy sets which symlinks the source follows. The default
+// is SymlinksInsideRoot.
+func WithSymlinkPolicy(policy SymlinkPolicy) FileSystemSourceOption {
+	return func(s *fileSystemSource) {
+		if policy != "" {
+			s.symlinks = policy
+		}
+	}
+}
+
+// NewFileSystemSource returns a SourceProvider for the files under root.
+//
+// Inputs:
+//
+//	root - Absolute path to the project root.
+//	opts - Optional settings, e.g. WithSymlinkPolicy.
+func NewFileSystemSource(root string, opts ...FileSystemSourceOption) SourceProvider {
+	s := &fileSystemSource{root: root, symlinks: SymlinksInsideRoot}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Walk implements SourceProvider. Entries that cannot be read, including
+// broken symlinks, are skipped.
+//
+// Description:
+//
+//	Symlinked directories the policy follows are walked under the link's
+//	own path, so a file can be visited more than once; SourceEntry.FileID
+//	tells the copies apart. A directory that links back to one of the
+//	directories it is in is not descended into.
+func (s *fileSystemSource) Walk(fn func(entry SourceEntry) error) error {
+	info, err := os.Stat(s.root)
+	if err != nil || !info.IsDir() {
+		return nil
+	}
+	err = fn(SourceEntry{RelPath: ".", Name: filepath.Base(s.root), IsDir: true})
+	if err == fs.SkipDir {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ancestors := map[string]bool{fileIdentity(s.root, info): true}
+	return s.walkDir(s.root, ".", false, ancestors, fn)
+}
+
+// walkDir visits the entries of the directory at absDir, whose path
+// relative to the root is relDir. linked is true when absDir was reached
+// through a symlink; ancestors holds the identities of absDir and the
+// directories above it.
+func (s *fileSystemSource) walkDir(absDir, relDir string, linked bool, ancestors map[string]bool, fn func(entry SourceEntry) error) error {
+	dirEntries, err := os.ReadDir(absDir)
+	if err != nil {
+		return nil // Skip directories we can't read
+	}
+	for _, d := range dirEntries {
+		absPath := filepath.Join(absDir, d.Name())
+		relPath := filepath.Join(relDir, d.Name())
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		entry := SourceEntry{
+			RelPath:   relPath,
+			Name:      d.Name(),
+			IsDir:     d.IsDir(),
+			Symlinked: linked,
+		}
+		if d.Type()&os.ModeSymlink != 0 {
+			// The entry takes the kind and size of its target.
+			if info, err = os.Stat(absPath); err != nil {
+				continue
+			}
+			entry.IsDir = info.IsDir()
+			entry.Symlinked = true
+			switch s.symlinks {
+			case SymlinksNever:
+				entry.NotFollowed = true
+			case SymlinksAlways:
+			default:
+				if _, err := validation.ResolveWithin(s.root, relPath); err != nil {
+					entry.OutsideRoot = true
+				}
+			}
+		}
+		size := info.Size()
+		entry.size = func() (int64, error) { return size, nil }
+		followed := !entry.OutsideRoot && !entry.NotFollowed
+		var id string
+		if followed {
+			id = fileIdentity(absPath, info)
+			if !entry.IsDir {
+				entry.FileID = id
+			}
+		}
+
+		err = fn(entry)
+		if !entry.IsDir {
+			if err == fs.SkipDir {
+				return nil // Skip the rest of this directory
+			}
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if err == fs.SkipDir {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !followed || ancestors[id] {
+			continue
+		}
+		ancestors[id] = true
+		err = s.walkDir(absPath, relPath, entry.Symlinked, ancestors, fn)
+		delete(ancestors, id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// canonicalIdentity identifies a file by its path with symlinks resolved.
+func canonicalIdentity(path string) string {
+	if canonical, err := filepath.EvalSymlinks(path); err == nil {
+		return canonical
+	}
+	return filepath.Clean(path)
+}
+
+// Open implements SourceProvider.
+func (s *fileSystemSource) Open(relPath string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(s.root, relPath))
+}
+
+// Revision implements SourceProvider.
+func (s *fileSystemSource) Revision() string { return "" }
+
+// GitObjectSource reads the files of a git commit from the repository's
+// object database.
+//
+// Description:
+//
+//	Files are the blobs of the commit's tree, under the project root's
+//	path within the repository, so nothing is checked out and the working
+//	tree (if any) is never read. Regular and executable files are
+//	visited; symlinks and submodules are not. Paths are in-repo paths
+//	relative to the project root, as they would be in a checkout.
+//
+// Thread Safety: Safe for concurrent use. Object reads are serialized.
+type GitObjectSource struct {
+	name string
+	sha  string
+
+	mu   sync.Mutex
+	repo *git.Repository
+	tree *object.Tree
+}
+
+// NewGitObjectSource resolves ref in the repository containing
+// projectRoot and returns a source for the commit's files.
+//
+// Description:
+//
+//	ref is any revision git-rev-parse accepts that go-git resolves: a
+//	branch ("main"), a remote-tracking branch ("origin/feature-x"), a tag,
+//	a full or abbreviated SHA, or an expression such as "HEAD~2". The
+//	repository may be bare. When projectRoot is a subdirectory of a
+//	non-bare repository, only that subdirectory's tree is used.
+//
+// Inputs:
+//
+//	projectRoot - Absolute path to the repository or a directory in its
+//	  working tree.
+//	ref - The commit-ish to read. Must not be empty.
+//
+// Outputs:
+//
+//	*GitObjectSource - The source.
+//	error - Wraps ErrInvalidGitRef if the repository cannot be opened or
+//	  ref does not name a commit.
+func NewGitObjectSource(projectRoot, ref string) (*GitObjectSource, error) {
+	if strings.TrimSpace(ref) == "" {
+		return nil, fmt.Errorf("%w: empty ref", ErrInvalidGitRef)
+	}
+	// A bare repository is only found at its own path; a working tree
+	// subdirectory is found by searching upwards for .git.
+	repo, err := git.PlainOpenWithOptions(projectRoot, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		repo, err = git.PlainOpenWithOptions(projectRoot, &git.PlainOpenOptions{
+			DetectDotGit:          true,
+			EnableDotGitCommonDir: true,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: opening repository at %s: %v", ErrInvalidGitRef, projectRoot, err)
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, fmt.Errorf("%w: resolving %q: %v", ErrInvalidGitRef, ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q is not a commit: %v", ErrInvalidGitRef, ref, err)
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("reading tree of %s: %w", hash, err)
+	}
+
+	if prefix := repoSubdir(repo, projectRoot); prefix != "" {
+		tree, err = tree.Tree(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s does not exist at %s", ErrInvalidGitRef, prefix, hash)
+		}
+	}
+
+	return &GitObjectSource{
+		name: filepath.Base(projectRoot),
+		sha:  hash.String(),
+		repo: repo,
+		tree: tree,
+	}, nil
+}
+
+// repoSubdir returns projectRoot's slash-separated path within repo's
+// working tree, or "" for the top level and for bare repositories.
+func repoSubdir(repo *git.Repository, projectRoot string) string {
+	wt, err := repo.Worktree()
+	if err != nil {
+		return ""
+	}
+	rel, err := filepath.Rel(wt.Filesystem.Root(), projectRoot)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Walk implements SourceProvider.
+func (s *GitObjectSource) Walk(fn func(entry SourceEntry) error) error {
+	err := fn(SourceEntry{RelPath: ".", Name: s.name, IsDir: true})
+	if err == fs.SkipDir {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return s.walkTree(s.tree, "", fn)
+}
+
+// walkTree visits the entries of tree, whose slash-separated path
+// relative to the root is dir.
+func (s *GitObjectSource) walkTree(tree *object.Tree, dir string, fn func(entry SourceEntry) error) error {
+	for _, e := range tree.Entries {
+		relPath := posixpath.Join(dir, e.Name)
+		switch e.Mode {
+		case filemode.Dir:
+			err := fn(SourceEntry{RelPath: filepath.FromSlash(relPath), Name: e.Name, IsDir: true})
+			if err == fs.SkipDir {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			s.mu.Lock()
+			sub, err := s.repo.TreeObject(e.Hash)
+			s.mu.Unlock()
+			if err != nil {
+				return fmt.Errorf("reading tree %s: %w", relPath, err)
+			}
+			if err := s.walkTree(sub, relPath, fn); err != nil {
+				return err
+			}
+		case filemode.Regular, filemode.Executable, filemode.Deprecated:
+			hash := e.Hash
+			err := fn(SourceEntry{
+				RelPath: filepath.FromSlash(relPath),
+				Name:    e.Name,
+				size: func() (int64, error) {
+					s.mu.Lock()
+					defer s.mu.Unlock()
+					blob, err := s.repo.BlobObject(hash)
+					if err != nil {
+						return 0, err
+					}
+					return blob.Size, nil
+				},
+			})
+			if err == fs.SkipDir {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Open implements SourceProvider. The blob is read into memory.
+func (s *GitObjectSource) Open(relPath string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.tree.File(filepath.ToSlash(relPath))
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) || errors.Is(err, object.ErrDirectoryNotFound) || errors.Is(err, object.ErrEntryNotFound) {
+			return nil, fmt.Errorf("%s at %s: %w", relPath, s.sha, fs.ErrNotExist)
+		}
+		return nil, err
+	}
+	content, err := f.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("reading %s at %s: %w", relPath, s.sha, err)
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+// Revision implements SourceProvider.
+func (s *GitObjectSource) Revision() string { return s.sha }
@@ -0,0 +1,268 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// commitFiles writes files under the repository's working tree, stages
+// everything and commits it, returning the commit SHA.
+func commitFiles(t *testing.T, repo *git.Repository, dir string, files map[string]string) string {
+	t.Helper()
+	writeWorkspaceFiles(t, dir, files)
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.AddGlob("."); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := wt.Commit("commit", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(1700000000, 0)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash.String()
+}
+
+// initGitProject creates a repository whose "feature" branch has a
+// helper.go and a gitignored file, then rewrites the working tree so it
+// differs from the branch.
+func initGitProject(t *testing.T) (dir, featureSHA string) {
+	t.Helper()
+	dir = t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	commitFiles(t, repo, dir, map[string]string{
+		"main.go": "package main\n\nfunc main() {}\n",
+	})
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	commitFiles(t, repo, dir, map[string]string{
+		".gitignore": "gen/\n",
+		"main.go":    "package main\n\nfunc main() { Helper() }\n",
+		"helper.go":  "package main\n\nfunc Helper() {}\n",
+	})
+	// A file ignored at the revision, force-added so it is in the tree.
+	writeWorkspaceFiles(t, dir, map[string]string{"gen/gen.go": "package gen\n\nfunc Generated() {}\n"})
+	if _, err := wt.Add("gen/gen.go"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wt.Commit("gen", &git.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Unix(1700000100, 0)},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	featureSHA, err = resolveSHA(repo, "feature")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The working tree now has neither helper.go nor the feature's main.go.
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}); err != nil {
+		t.Fatal(err)
+	}
+	writeWorkspaceFiles(t, dir, map[string]string{"wip.go": "package main\n\nfunc WorkInProgress() {}\n"})
+	return dir, featureSHA
+}
+
+func resolveSHA(repo *git.Repository, ref string) (string, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", err
+	}
+	return hash.String(), nil
+}
+
+// walkFiles returns the files a source's Walk visits.
+func walkFiles(t *testing.T, source SourceProvider) []string {
+	t.Helper()
+	var files []string
+	err := source.Walk(func(entry SourceEntry) error {
+		if !entry.IsDir {
+			files = append(files, filepath.ToSlash(entry.RelPath))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+	return files
+}
+
+func TestGitObjectSource(t *testing.T) {
+	dir, featureSHA := initGitProject(t)
+
+	t.Run("reads the commit, not the working tree", func(t *testing.T) {
+		source, err := NewGitObjectSource(dir, "feature")
+		if err != nil {
+			t.Fatalf("NewGitObjectSource failed: %v", err)
+		}
+		if source.Revision() != featureSHA {
+			t.Errorf("Revision() = %s, want %s", source.Revision(), featureSHA)
+		}
+		got := walkFiles(t, source)
+		want := []string{".gitignore", "gen/gen.go", "helper.go", "main.go"}
+		if len(got) != len(want) {
+			t.Fatalf("files = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("files = %v, want %v", got, want)
+			}
+		}
+
+		f, err := source.Open("main.go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(f)
+		f.Close()
+		if string(content) != "package main\n\nfunc main() { Helper() }\n" {
+			t.Errorf("main.go = %q, want the feature branch's content", content)
+		}
+
+		if _, err := source.Open("wip.go"); !errors.Is(err, fs.ErrNotExist) {
+			t.Errorf("Open(wip.go) error = %v, want fs.ErrNotExist", err)
+		}
+	})
+
+	t.Run("reads a bare clone", func(t *testing.T) {
+		bare := filepath.Join(t.TempDir(), "repo.git")
+		if _, err := git.PlainClone(bare, true, &git.CloneOptions{URL: dir}); err != nil {
+			t.Fatal(err)
+		}
+		source, err := NewGitObjectSource(bare, "origin/feature")
+		if err != nil {
+			t.Fatalf("NewGitObjectSource failed: %v", err)
+		}
+		if source.Revision() != featureSHA {
+			t.Errorf("Revision() = %s, want %s", source.Revision(), featureSHA)
+		}
+	})
+
+	t.Run("rejects unknown refs and non-repositories", func(t *testing.T) {
+		if _, err := NewGitObjectSource(dir, "no-such-branch"); !errors.Is(err, ErrInvalidGitRef) {
+			t.Errorf("unknown ref error = %v, want ErrInvalidGitRef", err)
+		}
+		if _, err := NewGitObjectSource(t.TempDir(), "main"); !errors.Is(err, ErrInvalidGitRef) {
+			t.Errorf("non-repository error = %v, want ErrInvalidGitRef", err)
+		}
+	})
+}
+
+func TestInit_GitRef(t *testing.T) {
+	dir, featureSHA := initGitProject(t)
+	svc := NewService(DefaultServiceConfig())
+	ctx := context.Background()
+
+	resp, err := svc.InitWithDiscovery(ctx, dir, []string{"go"}, nil, DiscoveryOptions{GitRef: "feature"})
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if resp.CommitSHA != featureSHA {
+		t.Errorf("CommitSHA = %q, want %q", resp.CommitSHA, featureSHA)
+	}
+	if resp.FilesParsed != 2 {
+		t.Errorf("FilesParsed = %d, want 2 (main.go, helper.go)", resp.FilesParsed)
+	}
+	if resp.SkippedByRule[".gitignore:gen/"] != 1 {
+		t.Errorf("SkippedByRule = %v, want the revision's .gitignore applied", resp.SkippedByRule)
+	}
+
+	cached, err := svc.GetGraph(resp.GraphID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.CommitSHA != featureSHA || cached.GitRef != "feature" {
+		t.Errorf("cached revision = %s@%s, want feature@%s", cached.GitRef, cached.CommitSHA, featureSHA)
+	}
+	if len(cached.Index.GetByName("Helper")) == 0 {
+		t.Error("Helper from the feature branch is not indexed")
+	}
+	if len(cached.Index.GetByName("WorkInProgress")) != 0 {
+		t.Error("working tree file was indexed")
+	}
+	if len(cached.Graph.FileMtimes) != 0 {
+		t.Errorf("FileMtimes = %v, want none for a revision build", cached.Graph.FileMtimes)
+	}
+
+	// The working tree gets its own graph.
+	wtResp, err := svc.Init(ctx, dir, []string{"go"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wtResp.GraphID == resp.GraphID {
+		t.Error("working tree and git ref share a graph ID")
+	}
+	if wtResp.CommitSHA != "" {
+		t.Errorf("working tree CommitSHA = %q, want empty", wtResp.CommitSHA)
+	}
+
+	if _, err := svc.InitWithDiscovery(ctx, dir, []string{"go"}, nil, DiscoveryOptions{GitRef: "nope"}); !errors.Is(err, ErrInvalidGitRef) {
+		t.Errorf("unknown ref error = %v, want ErrInvalidGitRef", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "helper.go")); err == nil {
+		t.Error("the revision build checked out files")
+	}
+}
+
+func TestInitProgress_GitRef(t *testing.T) {
+	dir, _ := initGitProject(t)
+	svc := NewService(DefaultServiceConfig())
+	entered, release := gateBuilds(svc)
+	defer release()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := svc.InitWithDiscovery(context.Background(), dir, []string{"go"}, nil, DiscoveryOptions{GitRef: "feature"})
+		done <- err
+	}()
+	<-entered
+
+	if _, ok := svc.InitProgress(dir, "feature"); !ok {
+		t.Error("expected progress for the running git_ref build")
+	}
+	if _, ok := svc.InitProgress(dir, ""); ok {
+		t.Error("working tree reported the git_ref build's progress")
+	}
+
+	release()
+	if err := <-done; err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if _, ok := svc.InitProgress(dir, "feature"); ok {
+		t.Error("expected no init in progress after build finished")
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package stdlib looks up the signatures and doc one-liners of Go
+// standard library symbols in an index embedded in the binary.
+//
+// The index is generated offline from the go/doc data of the Go
+// distribution by cmd/trace-stdlib-index; regenerate it with go generate
+// after a Go upgrade.
+package stdlib
+
+//go:generate go run ../../../cmd/trace-stdlib-index -out go_stdlib.json.gz
+
+import (
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+//go:embed go_stdlib.json.gz
+var embeddedIndex []byte
+
+// Symbol kinds recorded in the index.
+const (
+	KindFunc   = "func"
+	KindType   = "type"
+	KindMethod = "method"
+	KindVar    = "var"
+	KindConst  = "const"
+)
+
+// Symbol is an exported standard library declaration.
+type Symbol struct {
+	// Kind is one of the Kind constants.
+	Kind string `json:"kind"`
+
+	// Signature is the declaration without body, e.g.
+	// "func Println(a ...any) (n int, err error)". Struct and interface
+	// types are shortened to "type Client struct".
+	Signature string `json:"signature"`
+
+	// Doc is the first sentence of the doc comment.
+	Doc string `json:"doc,omitempty"`
+}
+
+// Package is an indexed standard library package.
+type Package struct {
+	// Doc is the first sentence of the package doc comment.
+	Doc string `json:"doc,omitempty"`
+
+	// Symbols maps an exported name to its declaration. Methods are keyed
+	// "Type.Method".
+	Symbols map[string]Symbol `json:"symbols"`
+}
+
+// Index maps import paths to their packages.
+//
+// Thread Safety: Read-only after loading; safe for concurrent use.
+type Index struct {
+	// GoVersion is the Go release the index was generated from.
+	GoVersion string `json:"go_version"`
+
+	// Packages maps an import path such as "net/http" to its package.
+	Packages map[string]*Package `json:"packages"`
+}
+
+// Load reads a gzip-compressed JSON index.
+//
+// Inputs:
+//   - r: The compressed index.
+//
+// Outputs:
+//   - *Index: The index.
+//   - error: Non-nil if r is not a valid compressed index.
+func Load(r io.Reader) (*Index, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open stdlib index: %w", err)
+	}
+	defer zr.Close()
+	var idx Index
+	if err := json.NewDecoder(zr).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("decode stdlib index: %w", err)
+	}
+	return &idx, nil
+}
+
+// Write writes idx as gzip-compressed JSON in the format Load reads.
+func Write(w io.Writer, idx *Index) error {
+	zw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(zw).Encode(idx); err != nil {
+		return fmt.Errorf("encode stdlib index: %w", err)
+	}
+	return zw.Close()
+}
+
+// Default returns the embedded index. It is decoded on first use; a
+// corrupt embedded index is logged and yields an empty index.
+//
+// Thread Safety: Safe for concurrent use.
+var Default = sync.OnceValue(func() *Index {
+	idx, err := Load(bytes.NewReader(embeddedIndex))
+	if err != nil {
+		slog.Warn("Embedded Go stdlib index unusable", telemetry.SensitiveLogError("error", err))
+		return &Index{}
+	}
+	return idx
+})
+
+// Package returns the indexed package with the import path.
+func (x *Index) Package(importPath string) (*Package, bool) {
+	if x == nil {
+		return nil, false
+	}
+	pkg, ok := x.Packages[importPath]
+	return pkg, ok
+}
+
+// Lookup returns the declaration of name in the package with the import
+// path. name is an exported identifier or "Type.Method".
+func (x *Index) Lookup(importPath, name string) (Symbol, bool) {
+	pkg, ok := x.Package(importPath)
+	if !ok {
+		return Symbol{}, false
+	}
+	sym, ok := pkg.Symbols[name]
+	return sym, ok
+}
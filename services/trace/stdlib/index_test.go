@@ -0,0 +1,71 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package stdlib
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDefault(t *testing.T) {
+	idx := Default()
+	if idx.GoVersion == "" || len(idx.Packages) == 0 {
+		t.Fatalf("embedded index is empty: %q, %d packages", idx.GoVersion, len(idx.Packages))
+	}
+
+	tests := []struct {
+		pkg, name, kind, signature string
+	}{
+		{"fmt", "Println", KindFunc, "func Println(a ...any) (n int, err error)"},
+		{"net/http", "Client", KindType, "type Client struct"},
+		{"net/http", "Client.Do", KindMethod, "func (c *Client) Do(req *Request) (*Response, error)"},
+		{"net/http", "StatusOK", KindConst, "const StatusOK = 200"},
+	}
+	for _, tt := range tests {
+		sym, ok := idx.Lookup(tt.pkg, tt.name)
+		if !ok || sym.Kind != tt.kind || sym.Signature != tt.signature || sym.Doc == "" {
+			t.Errorf("Lookup(%q, %q) = %+v, %v", tt.pkg, tt.name, sym, ok)
+		}
+	}
+	if pkg, ok := idx.Package("strings"); !ok || !strings.HasPrefix(pkg.Doc, "Package strings") {
+		t.Errorf("Package(strings) = %+v, %v", pkg, ok)
+	}
+	if _, ok := idx.Lookup("github.com/gin-gonic/gin", "New"); ok {
+		t.Error("non-stdlib package found")
+	}
+	if _, ok := (*Index)(nil).Lookup("fmt", "Println"); ok {
+		t.Error("nil index found a symbol")
+	}
+}
+
+func TestWriteLoad(t *testing.T) {
+	idx := &Index{GoVersion: "go1.0", Packages: map[string]*Package{
+		"errors": {Doc: "Package errors implements functions to manipulate errors.", Symbols: map[string]Symbol{
+			"New": {Kind: KindFunc, Signature: "func New(text string) error", Doc: "New returns an error."},
+		}},
+	}}
+	var buf bytes.Buffer
+	if err := Write(&buf, idx); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	got, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !reflect.DeepEqual(got, idx) {
+		t.Errorf("round trip = %+v, want %+v", got, idx)
+	}
+	if _, err := Load(strings.NewReader("not gzip")); err == nil {
+		t.Error("Load accepted garbage")
+	}
+}
@@ -34,6 +34,8 @@ import (
 	"time"
 
 	"github.com/dgraph-io/badger/v4"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Config holds configuration for a BadgerDB instance.
@@ -66,6 +68,12 @@ type Config struct {
 	// GCDiscardRatio is the minimum ratio of discardable data before GC.
 	// Default: 0.5 (GC when 50% of value log is garbage).
 	GCDiscardRatio float64
+
+	// ReadOnly opens an existing database without write access. Several
+	// processes may open the same directory read-only, but not while a
+	// process has it open for writing. Writes fail and GC is not run.
+	// Ignored when InMemory is true.
+	ReadOnly bool
 }
 
 // DefaultConfig returns sensible defaults for production use.
@@ -158,11 +166,13 @@ func Open(cfg Config) (*badger.DB, error) {
 	if cfg.InMemory {
 		opts = badger.DefaultOptions("").WithInMemory(true)
 	} else {
-		// Ensure directory exists
-		if err := os.MkdirAll(cfg.Path, 0750); err != nil {
-			return nil, fmt.Errorf("create database directory %s: %w", cfg.Path, err)
+		// Ensure directory exists; a read-only database must already.
+		if !cfg.ReadOnly {
+			if err := os.MkdirAll(cfg.Path, 0750); err != nil {
+				return nil, fmt.Errorf("create database directory %s: %w", cfg.Path, err)
+			}
 		}
-		opts = badger.DefaultOptions(cfg.Path)
+		opts = badger.DefaultOptions(cfg.Path).WithReadOnly(cfg.ReadOnly)
 	}
 
 	// Apply configuration
@@ -324,7 +334,7 @@ func (r *GCRunner) runGC() {
 	} else if !errors.Is(err, badger.ErrNoRewrite) {
 		// ErrNoRewrite means no GC was needed, not an error
 		if r.logger != nil {
-			r.logger.Warn("badger value log GC error", slog.String("error", err.Error()))
+			r.logger.Warn("badger value log GC error", telemetry.SensitiveLogError("error", err))
 		}
 	}
 }
@@ -367,7 +377,7 @@ func OpenDB(cfg Config) (*DB, error) {
 	}
 
 	// Start GC runner if configured
-	if cfg.GCInterval > 0 && !cfg.InMemory {
+	if cfg.GCInterval > 0 && !cfg.InMemory && !cfg.ReadOnly {
 		runner, err := NewGCRunner(db, cfg.GCInterval, cfg.GCDiscardRatio, cfg.Logger)
 		if err != nil {
 			db.Close()
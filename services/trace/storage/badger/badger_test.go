@@ -80,6 +80,43 @@ func TestOpenWithPath(t *testing.T) {
 	require.NoError(t, err)
 }
 
+// TestOpenReadOnly verifies that a closed database can be opened by
+// several read-only handles at once and rejects writes.
+func TestOpenReadOnly(t *testing.T) {
+	dir, err := TempDir("badger-test-")
+	require.NoError(t, err)
+	defer CleanupDir(dir)
+
+	db, err := OpenWithPath(dir)
+	require.NoError(t, err)
+	require.NoError(t, db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte("snapshot-key"), []byte("snapshot-value"))
+	}))
+	require.NoError(t, db.Close())
+
+	cfg := DefaultConfig()
+	cfg.Path = dir
+	cfg.ReadOnly = true
+	first, err := OpenDB(cfg)
+	require.NoError(t, err)
+	defer first.Close()
+	second, err := OpenDB(cfg)
+	require.NoError(t, err)
+	defer second.Close()
+
+	require.NoError(t, second.View(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte("snapshot-key"))
+		return err
+	}))
+	assert.Error(t, first.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte("other"), []byte("value"))
+	}))
+
+	cfg.Path = dir + "-missing"
+	_, err = Open(cfg)
+	assert.Error(t, err, "read-only open must not create the directory")
+}
+
 // TestOpenRequiresPath verifies that persistent mode requires a path.
 func TestOpenRequiresPath(t *testing.T) {
 	cfg := Config{
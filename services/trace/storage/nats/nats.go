@@ -23,6 +23,8 @@ import (
 	"time"
 
 	"github.com/nats-io/nats.go"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Config configures the NATS JetStream client.
@@ -153,7 +155,7 @@ func NewClient(cfg Config) (*Client, error) {
 		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
 			if err != nil {
 				cfg.Logger.Warn("CRS-27: NATS disconnected",
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 			}
 		}),
@@ -265,7 +267,7 @@ func (c *Client) Close() error {
 
 	if err := c.conn.Drain(); err != nil {
 		c.logger.Warn("CRS-27: NATS drain failed, forcing close",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		c.conn.Close()
 		return nil
@@ -0,0 +1,365 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+// Package redis provides a minimal Redis client for small shared records.
+//
+// Only the commands the trace server needs (GET, SET with expiry, DEL,
+// PING) are implemented, over the RESP2 protocol with a small connection
+// pool. It is not a general-purpose client: no pipelining, pub/sub,
+// cluster or TLS.
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNil is returned by Get when the key does not exist.
+var ErrNil = errors.New("redis: nil")
+
+// ErrClosed is returned by operations on a closed client.
+var ErrClosed = errors.New("redis: client closed")
+
+// Config configures the Redis client.
+//
+// Description:
+//
+//	URL has the form redis://[:password@]host:port[/db]. A username in the
+//	URL is sent with AUTH (Redis 6 ACLs).
+type Config struct {
+	// URL is the server URL.
+	URL string
+
+	// DialTimeout bounds connecting and the AUTH/SELECT handshake.
+	// Zero means 5 seconds.
+	DialTimeout time.Duration
+
+	// IOTimeout bounds each command round trip when the context has no
+	// earlier deadline. Zero means 5 seconds.
+	IOTimeout time.Duration
+
+	// PoolSize is the maximum number of idle connections kept.
+	// Zero means 4.
+	PoolSize int
+
+	// Logger receives connection diagnostics. If nil, slog.Default() is used.
+	Logger *slog.Logger
+}
+
+// defaults fills in zero-value fields.
+func (c *Config) defaults() {
+	if c.DialTimeout == 0 {
+		c.DialTimeout = 5 * time.Second
+	}
+	if c.IOTimeout == 0 {
+		c.IOTimeout = 5 * time.Second
+	}
+	if c.PoolSize <= 0 {
+		c.PoolSize = 4
+	}
+	if c.Logger == nil {
+		c.Logger = slog.Default()
+	}
+}
+
+// Client is a pooled Redis client.
+//
+// Thread Safety: Safe for concurrent use.
+type Client struct {
+	config   Config
+	addr     string
+	username string
+	password string
+	db       int
+
+	mu     sync.Mutex
+	idle   []*conn
+	closed bool
+}
+
+// conn is one RESP connection.
+type conn struct {
+	nc net.Conn
+	r  *bufio.Reader
+}
+
+// NewClient parses the URL and checks the server with PING.
+//
+// Inputs:
+//
+//	ctx - Context for the initial connection.
+//	cfg - Client configuration. URL is required.
+//
+// Outputs:
+//
+//	*Client - The client. Close it when done.
+//	error - Non-nil if the URL is invalid or the server is unreachable.
+func NewClient(ctx context.Context, cfg Config) (*Client, error) {
+	cfg.defaults()
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("redis: parsing URL: %w", err)
+	}
+	if u.Scheme != "redis" {
+		return nil, fmt.Errorf("redis: unsupported URL scheme %q (want redis://)", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("redis: URL has no host")
+	}
+	c := &Client{config: cfg, addr: u.Host}
+	if u.Port() == "" {
+		c.addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+	}
+	if db := strings.TrimPrefix(u.Path, "/"); db != "" {
+		if c.db, err = strconv.Atoi(db); err != nil || c.db < 0 {
+			return nil, fmt.Errorf("redis: invalid database %q", db)
+		}
+	}
+	if err := c.Ping(ctx); err != nil {
+		return nil, err
+	}
+	cfg.Logger.Info("redis: connected", slog.String("addr", c.addr), slog.Int("db", c.db))
+	return c, nil
+}
+
+// Get returns the value of key, or ErrNil if it does not exist.
+func (c *Client) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := c.do(ctx, "GET", key)
+	if err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, ErrNil
+	}
+	b, ok := reply.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("redis: unexpected GET reply %T", reply)
+	}
+	return b, nil
+}
+
+// Set stores value under key. A positive ttl sets an expiry, rounded up
+// to whole milliseconds.
+func (c *Client) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []string{"SET", key, string(value)}
+	if ttl > 0 {
+		ms := (ttl + time.Millisecond - 1) / time.Millisecond
+		args = append(args, "PX", strconv.FormatInt(int64(ms), 10))
+	}
+	_, err := c.do(ctx, args...)
+	return err
+}
+
+// Del removes key. Removing a missing key is not an error.
+func (c *Client) Del(ctx context.Context, key string) error {
+	_, err := c.do(ctx, "DEL", key)
+	return err
+}
+
+// Ping checks the server is reachable.
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, "PING")
+	return err
+}
+
+// Close closes the idle connections. In-flight commands finish on their
+// own connections, which are then discarded.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	var firstErr error
+	for _, cn := range c.idle {
+		if err := cn.nc.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	c.idle = nil
+	return firstErr
+}
+
+// do runs one command and returns its reply: nil for a nil bulk string,
+// []byte for bulk strings, string for simple strings, int64 for integers
+// and []any for arrays. Server errors are returned as errors.
+func (c *Client) do(ctx context.Context, args ...string) (any, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(c.config.IOTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	reply, err := cn.roundTrip(deadline, args...)
+	var serverErr serverError
+	if err != nil && !errors.As(err, &serverErr) {
+		// The connection state is unknown after an I/O error.
+		_ = cn.nc.Close()
+		return nil, fmt.Errorf("redis: %s: %w", args[0], err)
+	}
+	c.put(cn)
+	if err != nil {
+		return nil, fmt.Errorf("redis: %s: %w", args[0], err)
+	}
+	return reply, nil
+}
+
+// get takes an idle connection or dials a new one.
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, ErrClosed
+	}
+	if n := len(c.idle); n > 0 {
+		cn := c.idle[n-1]
+		c.idle = c.idle[:n-1]
+		c.mu.Unlock()
+		return cn, nil
+	}
+	c.mu.Unlock()
+	return c.dial(ctx)
+}
+
+// put returns a connection to the pool, or closes it if the pool is full.
+func (c *Client) put(cn *conn) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed || len(c.idle) >= c.config.PoolSize {
+		_ = cn.nc.Close()
+		return
+	}
+	c.idle = append(c.idle, cn)
+}
+
+// dial connects and authenticates a new connection.
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	dialer := net.Dialer{Timeout: c.config.DialTimeout}
+	nc, err := dialer.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return nil, fmt.Errorf("redis: connecting to %s: %w", c.addr, err)
+	}
+	cn := &conn{nc: nc, r: bufio.NewReader(nc)}
+	deadline := time.Now().Add(c.config.DialTimeout)
+	if c.password != "" {
+		args := []string{"AUTH", c.password}
+		if c.username != "" {
+			args = []string{"AUTH", c.username, c.password}
+		}
+		if _, err := cn.roundTrip(deadline, args...); err != nil {
+			_ = nc.Close()
+			return nil, fmt.Errorf("redis: AUTH: %w", err)
+		}
+	}
+	if c.db != 0 {
+		if _, err := cn.roundTrip(deadline, "SELECT", strconv.Itoa(c.db)); err != nil {
+			_ = nc.Close()
+			return nil, fmt.Errorf("redis: SELECT %d: %w", c.db, err)
+		}
+	}
+	return cn, nil
+}
+
+// serverError is an error reply from the server.
+type serverError string
+
+func (e serverError) Error() string { return string(e) }
+
+// roundTrip writes a command as a RESP array of bulk strings and reads
+// its reply.
+func (cn *conn) roundTrip(deadline time.Time, args ...string) (any, error) {
+	if err := cn.nc.SetDeadline(deadline); err != nil {
+		return nil, err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(cn.nc, sb.String()); err != nil {
+		return nil, err
+	}
+	return readReply(cn.r)
+}
+
+// maxBulkLen bounds the bulk strings and arrays accepted from the server.
+const maxBulkLen = 512 << 20
+
+// readReply reads one RESP2 reply.
+func readReply(r *bufio.Reader) (any, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || !strings.HasSuffix(line, "\r\n") {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, body := line[0], line[1:len(line)-2]
+	switch kind {
+	case '+':
+		return body, nil
+	case '-':
+		return nil, serverError(body)
+	case ':':
+		return strconv.ParseInt(body, 10, 64)
+	case '$':
+		n, err := strconv.Atoi(body)
+		if err != nil || n > maxBulkLen {
+			return nil, fmt.Errorf("malformed bulk length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	case '*':
+		n, err := strconv.Atoi(body)
+		if err != nil || n > maxBulkLen {
+			return nil, fmt.Errorf("malformed array length %q", body)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			item, err := readReply(r)
+			var serverErr serverError
+			if errors.As(err, &serverErr) {
+				// Keep reading so the connection stays in sync.
+				item = serverErr
+			} else if err != nil {
+				return nil, err
+			}
+			items[i] = item
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("unknown reply type %q", kind)
+}
@@ -0,0 +1,196 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeServer is an in-process server for the commands the client uses.
+type fakeServer struct {
+	ln       net.Listener
+	password string
+
+	mu       sync.Mutex
+	data     map[string]string
+	commands []string
+}
+
+func startFakeServer(t *testing.T, password string) *fakeServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeServer{ln: ln, password: password, data: make(map[string]string)}
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(nc)
+		}
+	}()
+	return s
+}
+
+func (s *fakeServer) serve(nc net.Conn) {
+	defer nc.Close()
+	r := bufio.NewReader(nc)
+	authed := s.password == ""
+	for {
+		reply, err := readReply(r)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			b, _ := item.([]byte)
+			args[i] = string(b)
+		}
+		if len(args) == 0 {
+			return
+		}
+		s.mu.Lock()
+		s.commands = append(s.commands, strings.Join(args, " "))
+		out := "-ERR unknown command\r\n"
+		switch cmd := strings.ToUpper(args[0]); {
+		case cmd == "AUTH":
+			if args[len(args)-1] == s.password {
+				authed = true
+				out = "+OK\r\n"
+			} else {
+				out = "-WRONGPASS invalid password\r\n"
+			}
+		case !authed:
+			out = "-NOAUTH Authentication required.\r\n"
+		case cmd == "PING":
+			out = "+PONG\r\n"
+		case cmd == "SELECT":
+			out = "+OK\r\n"
+		case cmd == "GET":
+			if v, ok := s.data[args[1]]; ok {
+				out = fmt.Sprintf("$%d\r\n%s\r\n", len(v), v)
+			} else {
+				out = "$-1\r\n"
+			}
+		case cmd == "SET":
+			s.data[args[1]] = args[2]
+			out = "+OK\r\n"
+		case cmd == "DEL":
+			delete(s.data, args[1])
+			out = ":1\r\n"
+		}
+		s.mu.Unlock()
+		if _, err := nc.Write([]byte(out)); err != nil {
+			return
+		}
+	}
+}
+
+func (s *fakeServer) url(userinfo, path string) string {
+	return "redis://" + userinfo + s.ln.Addr().String() + path
+}
+
+func TestClient_GetSetDel(t *testing.T) {
+	srv := startFakeServer(t, "")
+	ctx := context.Background()
+	c, err := NewClient(ctx, Config{URL: srv.url("", "/2")})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	defer c.Close()
+
+	if _, err := c.Get(ctx, "k"); !errors.Is(err, ErrNil) {
+		t.Fatalf("Get on missing key: err = %v, want ErrNil", err)
+	}
+	value := []byte("line1\r\nline2 with $ and *")
+	if err := c.Set(ctx, "k", value, 1500*time.Microsecond); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	got, err := c.Get(ctx, "k")
+	if err != nil || string(got) != string(value) {
+		t.Fatalf("Get = %q, %v; want %q", got, err, value)
+	}
+	if err := c.Del(ctx, "k"); err != nil {
+		t.Fatalf("Del: %v", err)
+	}
+	if _, err := c.Get(ctx, "k"); !errors.Is(err, ErrNil) {
+		t.Errorf("Get after Del: err = %v, want ErrNil", err)
+	}
+
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	if srv.commands[0] != "SELECT 2" {
+		t.Errorf("first command = %q, want SELECT 2", srv.commands[0])
+	}
+	foundExpiry := false
+	for _, cmd := range srv.commands {
+		if strings.HasPrefix(cmd, "SET k ") && strings.HasSuffix(cmd, " PX 2") {
+			foundExpiry = true
+		}
+	}
+	if !foundExpiry {
+		t.Errorf("SET did not carry PX 2 (ttl rounded up): %v", srv.commands)
+	}
+}
+
+func TestClient_Auth(t *testing.T) {
+	srv := startFakeServer(t, "secret")
+	ctx := context.Background()
+
+	if _, err := NewClient(ctx, Config{URL: srv.url("", "")}); err == nil {
+		t.Error("expected NOAUTH error without a password")
+	}
+	if _, err := NewClient(ctx, Config{URL: srv.url(":wrong@", "")}); err == nil {
+		t.Error("expected error with a wrong password")
+	}
+	c, err := NewClient(ctx, Config{URL: srv.url(":secret@", "")})
+	if err != nil {
+		t.Fatalf("NewClient with password: %v", err)
+	}
+	defer c.Close()
+	if err := c.Set(ctx, "k", []byte("v"), 0); err != nil {
+		t.Errorf("Set after AUTH: %v", err)
+	}
+}
+
+func TestClient_InvalidConfig(t *testing.T) {
+	ctx := context.Background()
+	for _, url := range []string{"http://localhost:6379", "redis://", "redis://localhost:6379/x"} {
+		if _, err := NewClient(ctx, Config{URL: url}); err == nil {
+			t.Errorf("NewClient(%q) succeeded, want error", url)
+		}
+	}
+}
+
+func TestClient_Closed(t *testing.T) {
+	srv := startFakeServer(t, "")
+	c, err := NewClient(context.Background(), Config{URL: srv.url("", "")})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	_ = c.Close()
+	if err := c.Ping(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("Ping after Close: err = %v, want ErrClosed", err)
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"time"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/rag"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 	"github.com/weaviate/weaviate-go-client/v5/weaviate"
 )
 
@@ -133,14 +134,14 @@ func (c *SymbolIndexingCoordinator) TriggerIndexing(graphID string, cached *Cach
 		store, storeErr := rag.NewSymbolStore(c.weaviateClient, c.weaviateDataSpace, c.embedClient)
 		if storeErr != nil {
 			slog.Warn("CRS-26l: Failed to create symbol store",
-				slog.String("error", storeErr.Error()))
+				telemetry.SensitiveLogError("error", storeErr))
 			return
 		}
 
 		hasHash, hashErr := store.HasGraphHash(indexCtx, preHash)
 		if hashErr != nil {
 			slog.Warn("CRS-26l: Failed to check graph hash",
-				slog.String("error", hashErr.Error()))
+				telemetry.SensitiveLogError("error", hashErr))
 			return
 		}
 		if hasHash {
@@ -165,7 +166,7 @@ func (c *SymbolIndexingCoordinator) TriggerIndexing(graphID string, cached *Cach
 		// Delete stale symbols and re-index.
 		if delErr := store.DeleteAll(indexCtx); delErr != nil {
 			slog.Warn("CRS-26l: Failed to delete stale symbols",
-				slog.String("error", delErr.Error()))
+				telemetry.SensitiveLogError("error", delErr))
 		}
 
 		onProgress := func(phase string, completed, total, symbolsTotal int) {
@@ -183,7 +184,7 @@ func (c *SymbolIndexingCoordinator) TriggerIndexing(graphID string, cached *Cach
 		count, idxErr := store.IndexSymbols(indexCtx, cached.Index, preHash, onProgress)
 		if idxErr != nil {
 			slog.Warn("CRS-26l: Symbol indexing failed",
-				slog.String("error", idxErr.Error()))
+				telemetry.SensitiveLogError("error", idxErr))
 			c.mu.Lock()
 			c.progress = IndexingStatusResponse{
 				InProgress: false,
@@ -17,6 +17,8 @@ import (
 	"log/slog"
 
 	"github.com/google/uuid"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -139,7 +141,7 @@ func (c *Controller) Run(ctx context.Context, req *Request) (*Result, error) {
 			)
 			// Rollback on timeout
 			if rollbackErr := c.files.Rollback(); rollbackErr != nil {
-				c.logger.Error("Rollback failed", slog.String("error", rollbackErr.Error()))
+				c.logger.Error("Rollback failed", telemetry.SensitiveLogError("error", rollbackErr))
 			}
 			// Record timeout metrics
 			setSessionSpanResult(span, false, string(StateFailed), c.ctx.TestRetries, c.ctx.FixRetries)
@@ -150,7 +152,7 @@ func (c *Controller) Run(ctx context.Context, req *Request) (*Result, error) {
 			if err = c.step(ctx); err != nil {
 				c.logger.Error("Step failed",
 					slog.String("state", string(c.ctx.State)),
-					slog.String("error", err.Error()),
+					telemetry.SensitiveLogError("error", err),
 				)
 				// Don't return immediately, let state machine handle it
 			}
@@ -276,7 +278,7 @@ func (c *Controller) stepWriteTest(ctx context.Context) error {
 
 func (c *Controller) stepVerifyFail(ctx context.Context) error {
 	c.logger.Debug("Verifying test fails",
-		slog.String("test_name", c.ctx.ReproducerTest.Name),
+		telemetry.SensitiveLog("test_name", c.ctx.ReproducerTest.Name),
 	)
 
 	c.ctx.Metrics.TestsRun++
@@ -294,7 +296,7 @@ func (c *Controller) stepVerifyFail(ctx context.Context) error {
 	if result.Passed {
 		// Test passed when it should fail - bad reproducer
 		c.logger.Warn("Test passed unexpectedly",
-			slog.String("test_name", c.ctx.ReproducerTest.Name),
+			telemetry.SensitiveLog("test_name", c.ctx.ReproducerTest.Name),
 			slog.Int("attempt", c.ctx.TestRetries),
 		)
 
@@ -312,7 +314,7 @@ func (c *Controller) stepVerifyFail(ctx context.Context) error {
 
 	// Test failed as expected - good reproducer!
 	c.logger.Info("Test correctly fails (reproduces bug)",
-		slog.String("test_name", c.ctx.ReproducerTest.Name),
+		telemetry.SensitiveLog("test_name", c.ctx.ReproducerTest.Name),
 	)
 
 	c.transition(StateWriteFix)
@@ -368,7 +370,7 @@ func (c *Controller) stepWriteFix(ctx context.Context) error {
 
 func (c *Controller) stepVerifyPass(ctx context.Context) error {
 	c.logger.Debug("Verifying test passes",
-		slog.String("test_name", c.ctx.ReproducerTest.Name),
+		telemetry.SensitiveLog("test_name", c.ctx.ReproducerTest.Name),
 	)
 
 	c.ctx.Metrics.TestsRun++
@@ -386,7 +388,7 @@ func (c *Controller) stepVerifyPass(ctx context.Context) error {
 	if !result.Passed {
 		// Test still fails - fix didn't work
 		c.logger.Warn("Test still fails after fix",
-			slog.String("test_name", c.ctx.ReproducerTest.Name),
+			telemetry.SensitiveLog("test_name", c.ctx.ReproducerTest.Name),
 			slog.Int("attempt", c.ctx.FixRetries),
 		)
 
@@ -399,7 +401,7 @@ func (c *Controller) stepVerifyPass(ctx context.Context) error {
 
 		// Rollback and try again
 		if err := c.files.Rollback(); err != nil {
-			c.logger.Error("Rollback failed", slog.String("error", err.Error()))
+			c.logger.Error("Rollback failed", telemetry.SensitiveLogError("error", err))
 		}
 		c.ctx.AppliedPatches = nil
 
@@ -409,7 +411,7 @@ func (c *Controller) stepVerifyPass(ctx context.Context) error {
 
 	// Test passes - fix works!
 	c.logger.Info("Test passes with fix",
-		slog.String("test_name", c.ctx.ReproducerTest.Name),
+		telemetry.SensitiveLog("test_name", c.ctx.ReproducerTest.Name),
 	)
 
 	c.transition(StateRegression)
@@ -452,7 +454,7 @@ func (c *Controller) stepRegression(ctx context.Context) error {
 
 		// Rollback and try to fix again (with regression info)
 		if err := c.files.Rollback(); err != nil {
-			c.logger.Error("Rollback failed", slog.String("error", err.Error()))
+			c.logger.Error("Rollback failed", telemetry.SensitiveLogError("error", err))
 		}
 		c.ctx.AppliedPatches = nil
 
@@ -16,6 +16,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -89,17 +91,17 @@ func (m *FileManager) WriteTest(tc *TestCase) error {
 	}
 
 	m.logger.Debug("Writing test file",
-		slog.String("path", filePath),
+		telemetry.SensitiveLog("path", filePath),
 		slog.Int("size", len(tc.Content)),
-		slog.String("test_name", tc.Name),
+		telemetry.SensitiveLog("test_name", tc.Name),
 	)
 
 	// Ensure directory exists
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		m.logger.Error("Failed to create directory",
-			slog.String("path", dir),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("path", dir),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return fmt.Errorf("%w: create directory: %v", ErrTestWriteFailed, err)
 	}
@@ -108,7 +110,7 @@ func (m *FileManager) WriteTest(tc *TestCase) error {
 	if existing, err := os.ReadFile(filePath); err == nil {
 		m.backups[filePath] = existing
 		m.logger.Debug("Backed up existing file",
-			slog.String("path", filePath),
+			telemetry.SensitiveLog("path", filePath),
 			slog.Int("size", len(existing)),
 		)
 	}
@@ -117,8 +119,8 @@ func (m *FileManager) WriteTest(tc *TestCase) error {
 	tempPath := filePath + ".tdg.tmp"
 	if err := os.WriteFile(tempPath, []byte(tc.Content), 0644); err != nil {
 		m.logger.Error("Failed to write temp file",
-			slog.String("path", tempPath),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("path", tempPath),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return fmt.Errorf("%w: write temp: %v", ErrTestWriteFailed, err)
 	}
@@ -127,8 +129,8 @@ func (m *FileManager) WriteTest(tc *TestCase) error {
 		_ = os.Remove(tempPath) // Clean up temp file
 		m.logger.Error("Failed to rename temp file",
 			slog.String("temp", tempPath),
-			slog.String("target", filePath),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("target", filePath),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return fmt.Errorf("%w: rename: %v", ErrTestWriteFailed, err)
 	}
@@ -139,7 +141,7 @@ func (m *FileManager) WriteTest(tc *TestCase) error {
 	}
 
 	m.logger.Info("Wrote test file",
-		slog.String("path", filePath),
+		telemetry.SensitiveLog("path", filePath),
 		slog.Int("size", len(tc.Content)),
 	)
 
@@ -178,7 +180,7 @@ func (m *FileManager) ApplyPatch(patch *Patch) error {
 	}
 
 	m.logger.Debug("Applying patch",
-		slog.String("path", filePath),
+		telemetry.SensitiveLog("path", filePath),
 		slog.Int("new_size", len(patch.NewContent)),
 	)
 
@@ -186,8 +188,8 @@ func (m *FileManager) ApplyPatch(patch *Patch) error {
 	existing, err := os.ReadFile(filePath)
 	if err != nil && !os.IsNotExist(err) {
 		m.logger.Error("Failed to read existing file",
-			slog.String("path", filePath),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("path", filePath),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return fmt.Errorf("%w: read existing: %v", ErrPatchApplyFailed, err)
 	}
@@ -207,8 +209,8 @@ func (m *FileManager) ApplyPatch(patch *Patch) error {
 	dir := filepath.Dir(filePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		m.logger.Error("Failed to create directory",
-			slog.String("path", dir),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("path", dir),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return fmt.Errorf("%w: create directory: %v", ErrPatchApplyFailed, err)
 	}
@@ -217,8 +219,8 @@ func (m *FileManager) ApplyPatch(patch *Patch) error {
 	tempPath := filePath + ".tdg.tmp"
 	if err := os.WriteFile(tempPath, []byte(patch.NewContent), 0644); err != nil {
 		m.logger.Error("Failed to write temp file",
-			slog.String("path", tempPath),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("path", tempPath),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return fmt.Errorf("%w: write temp: %v", ErrPatchApplyFailed, err)
 	}
@@ -227,8 +229,8 @@ func (m *FileManager) ApplyPatch(patch *Patch) error {
 		_ = os.Remove(tempPath)
 		m.logger.Error("Failed to rename temp file",
 			slog.String("temp", tempPath),
-			slog.String("target", filePath),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("target", filePath),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return fmt.Errorf("%w: rename: %v", ErrPatchApplyFailed, err)
 	}
@@ -236,7 +238,7 @@ func (m *FileManager) ApplyPatch(patch *Patch) error {
 	patch.Applied = true
 
 	m.logger.Info("Applied patch",
-		slog.String("path", filePath),
+		telemetry.SensitiveLog("path", filePath),
 		slog.Int("old_size", len(existing)),
 		slog.Int("new_size", len(patch.NewContent)),
 	)
@@ -272,13 +274,13 @@ func (m *FileManager) Rollback() error {
 	for filePath, content := range m.backups {
 		if err := os.WriteFile(filePath, content, 0644); err != nil {
 			m.logger.Error("Failed to restore file",
-				slog.String("path", filePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("path", filePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 			lastErr = fmt.Errorf("%w: restore %s: %v", ErrPatchRollbackFailed, filePath, err)
 		} else {
 			m.logger.Debug("Restored file",
-				slog.String("path", filePath),
+				telemetry.SensitiveLog("path", filePath),
 				slog.Int("size", len(content)),
 			)
 		}
@@ -292,13 +294,13 @@ func (m *FileManager) Rollback() error {
 		}
 		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
 			m.logger.Error("Failed to remove created file",
-				slog.String("path", filePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("path", filePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 			lastErr = fmt.Errorf("%w: remove %s: %v", ErrPatchRollbackFailed, filePath, err)
 		} else {
 			m.logger.Debug("Removed created file",
-				slog.String("path", filePath),
+				telemetry.SensitiveLog("path", filePath),
 			)
 		}
 	}
@@ -345,13 +347,13 @@ func (m *FileManager) Cleanup() error {
 		}
 		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
 			m.logger.Error("Failed to remove file",
-				slog.String("path", filePath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("path", filePath),
+				telemetry.SensitiveLogError("error", err),
 			)
 			lastErr = err
 		} else {
 			m.logger.Debug("Removed file",
-				slog.String("path", filePath),
+				telemetry.SensitiveLog("path", filePath),
 			)
 		}
 	}
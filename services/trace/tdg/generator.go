@@ -17,6 +17,8 @@ import (
 	"strings"
 	"sync/atomic"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -127,7 +129,7 @@ func (g *TestGenerator) GenerateReproducerTest(ctx context.Context, req *Request
 		codeContext, err = g.context.AssembleContext(ctx, req.BugDescription, 4000)
 		if err != nil {
 			g.logger.Warn("Failed to assemble context",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 			// Continue without context
 		}
@@ -141,7 +143,7 @@ func (g *TestGenerator) GenerateReproducerTest(ctx context.Context, req *Request
 	response, err := g.llm.GenerateWithSystem(ctx, tdgSystemPrompt, prompt)
 	if err != nil {
 		g.logger.Error("LLM generation failed",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, fmt.Errorf("%w: %v", ErrLLMGenerationFailed, err)
 	}
@@ -150,7 +152,7 @@ func (g *TestGenerator) GenerateReproducerTest(ctx context.Context, req *Request
 	tc, err := g.parseTestFromResponse(response, req.Language)
 	if err != nil {
 		g.logger.Error("Failed to parse test from response",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, err
 	}
@@ -163,8 +165,8 @@ func (g *TestGenerator) GenerateReproducerTest(ctx context.Context, req *Request
 	}
 
 	g.logger.Info("Generated reproducer test",
-		slog.String("name", tc.Name),
-		slog.String("file", tc.FilePath),
+		telemetry.SensitiveLog("name", tc.Name),
+		telemetry.SensitiveLog("file", tc.FilePath),
 		slog.Duration("duration", time.Since(start)),
 	)
 
@@ -196,7 +198,7 @@ func (g *TestGenerator) RefineTest(ctx context.Context, req *Request, previousTe
 
 	start := time.Now()
 	g.logger.Debug("Refining test",
-		slog.String("previous_name", previousTest.Name),
+		telemetry.SensitiveLog("previous_name", previousTest.Name),
 	)
 
 	prompt := g.buildTestRefinementPrompt(req, previousTest, testOutput)
@@ -213,7 +215,7 @@ func (g *TestGenerator) RefineTest(ctx context.Context, req *Request, previousTe
 	}
 
 	g.logger.Info("Refined test",
-		slog.String("name", tc.Name),
+		telemetry.SensitiveLog("name", tc.Name),
 		slog.Duration("duration", time.Since(start)),
 	)
 
@@ -249,7 +251,7 @@ func (g *TestGenerator) GenerateFix(ctx context.Context, req *Request, tc *TestC
 
 	start := time.Now()
 	g.logger.Debug("Generating fix",
-		slog.String("test_name", tc.Name),
+		telemetry.SensitiveLog("test_name", tc.Name),
 		slog.Int("output_length", len(testOutput)),
 	)
 
@@ -260,7 +262,7 @@ func (g *TestGenerator) GenerateFix(ctx context.Context, req *Request, tc *TestC
 		codeContext, err = g.context.AssembleContext(ctx, req.BugDescription, 4000)
 		if err != nil {
 			g.logger.Warn("Failed to assemble context",
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLogError("error", err),
 			)
 		}
 	}
@@ -276,13 +278,13 @@ func (g *TestGenerator) GenerateFix(ctx context.Context, req *Request, tc *TestC
 	patch, err := g.parsePatchFromResponse(response)
 	if err != nil {
 		g.logger.Error("Failed to parse patch from response",
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return nil, err
 	}
 
 	g.logger.Info("Generated fix",
-		slog.String("file", patch.FilePath),
+		telemetry.SensitiveLog("file", patch.FilePath),
 		slog.Int("new_size", len(patch.NewContent)),
 		slog.Duration("duration", time.Since(start)),
 	)
@@ -315,7 +317,7 @@ func (g *TestGenerator) RefineFix(ctx context.Context, req *Request, tc *TestCas
 
 	start := time.Now()
 	g.logger.Debug("Refining fix",
-		slog.String("previous_file", previousPatch.FilePath),
+		telemetry.SensitiveLog("previous_file", previousPatch.FilePath),
 	)
 
 	prompt := g.buildFixRefinementPrompt(req, tc, previousPatch, testOutput)
@@ -332,7 +334,7 @@ func (g *TestGenerator) RefineFix(ctx context.Context, req *Request, tc *TestCas
 	}
 
 	g.logger.Info("Refined fix",
-		slog.String("file", patch.FilePath),
+		telemetry.SensitiveLog("file", patch.FilePath),
 		slog.Duration("duration", time.Since(start)),
 	)
 
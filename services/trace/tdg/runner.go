@@ -19,6 +19,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // =============================================================================
@@ -94,8 +96,8 @@ func (r *TestRunner) RunTest(ctx context.Context, tc *TestCase) (*TestResult, er
 
 	start := time.Now()
 	r.logger.Debug("Running single test",
-		slog.String("test_name", tc.Name),
-		slog.String("file", tc.FilePath),
+		telemetry.SensitiveLog("test_name", tc.Name),
+		telemetry.SensitiveLog("file", tc.FilePath),
 		slog.String("language", tc.Language),
 	)
 
@@ -118,7 +120,7 @@ func (r *TestRunner) RunTest(ctx context.Context, tc *TestCase) (*TestResult, er
 	}
 
 	r.logger.Info("Test completed",
-		slog.String("test_name", tc.Name),
+		telemetry.SensitiveLog("test_name", tc.Name),
 		slog.Bool("passed", result.Passed),
 		slog.Duration("duration", duration),
 		slog.Int("exit_code", result.ExitCode),
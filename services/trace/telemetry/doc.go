@@ -55,6 +55,9 @@
 //   - OTEL_EXPORTER_OTLP_ENDPOINT: OTLP endpoint (default: localhost:4317)
 //   - OTEL_TRACES_EXPORTER: otlp, stdout, or none (default: otlp)
 //   - OTEL_METRICS_EXPORTER: prometheus, otlp, stdout, or none (default: prometheus)
+//   - OTEL_TRACES_SAMPLER: always_on, always_off, traceidratio, or their
+//     parentbased_ forms (default: Config.SampleRate)
+//   - OTEL_TRACES_SAMPLER_ARG: sampling ratio for the ratio samplers
 //   - ALEUTIAN_ENV: environment name (default: development)
 //
 // # Thread Safety
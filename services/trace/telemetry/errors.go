@@ -20,6 +20,10 @@ var (
 	// ErrUnknownExporter is returned when an unknown exporter type is specified.
 	ErrUnknownExporter = errors.New("unknown exporter type")
 
+	// ErrUnknownSampler is returned when OTEL_TRACES_SAMPLER names an
+	// unsupported sampler or its argument is invalid.
+	ErrUnknownSampler = errors.New("unknown or invalid trace sampler")
+
 	// ErrAlreadyInitialized is returned if Init is called more than once.
 	ErrAlreadyInitialized = errors.New("telemetry already initialized")
 )
@@ -0,0 +1,248 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log formats accepted by SetupLogging.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
+// logLevel is the process-wide log level used by SetupLogging handlers.
+// Changing it takes effect immediately for every logger.
+var logLevel slog.LevelVar
+
+// logAttrsKey is the context key for attributes added by ContextWithLogAttrs.
+type logAttrsKey struct{}
+
+// ContextWithLogAttrs returns a context whose log records carry attrs.
+//
+// Description:
+//
+//	Records logged with the returned context (slog.InfoContext,
+//	logger.InfoContext, or a logger from LoggerFromContext) get attrs added
+//	by ContextHandler. Attributes accumulate: calling it again on the
+//	returned context keeps the earlier ones.
+//
+// Inputs:
+//
+//	ctx - Parent context. Must not be nil.
+//	attrs - Attributes to add, e.g. slog.String("session_id", id).
+//
+// Outputs:
+//
+//	context.Context - Context carrying the attributes.
+//
+// Thread Safety: Safe for concurrent use.
+func ContextWithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	existing := LogAttrsFromContext(ctx)
+	merged := make([]slog.Attr, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, logAttrsKey{}, merged)
+}
+
+// LogAttrsFromContext returns the attributes added by ContextWithLogAttrs.
+// The returned slice must not be modified.
+func LogAttrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// ContextHandler is a slog.Handler that adds request correlation fields
+// from the context to every record.
+//
+// Description:
+//
+//	For each record it adds trace_id and span_id from the active span, if
+//	the span context is valid, and the attributes set with
+//	ContextWithLogAttrs (e.g. session_id). Keys already on the record are
+//	not repeated, so call sites that log session_id explicitly do not
+//	produce duplicate fields.
+//
+//	The handler uses the context passed to Handle, so correlation needs the
+//	Context logging variants. A handler from LoggerFromContext is bound to
+//	a context instead and also works with slog.Logger.Info.
+//
+// Thread Safety: Safe for concurrent use if the wrapped handler is.
+type ContextHandler struct {
+	inner slog.Handler
+	bound context.Context
+}
+
+// NewContextHandler wraps inner with context correlation.
+//
+// Inputs:
+//
+//	inner - The handler that formats and writes records. Must not be nil.
+//
+// Outputs:
+//
+//	*ContextHandler - The wrapping handler.
+func NewContextHandler(inner slog.Handler) *ContextHandler {
+	return &ContextHandler{inner: inner}
+}
+
+// Enabled implements slog.Handler.
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	var seen map[string]bool
+	add := func(attr slog.Attr) {
+		if seen == nil {
+			seen = make(map[string]bool, r.NumAttrs()+4)
+			r.Attrs(func(a slog.Attr) bool {
+				seen[a.Key] = true
+				return true
+			})
+		}
+		if seen[attr.Key] {
+			return
+		}
+		seen[attr.Key] = true
+		r.AddAttrs(attr)
+	}
+	addFrom := func(c context.Context) {
+		if c == nil {
+			return
+		}
+		if spanCtx := trace.SpanContextFromContext(c); spanCtx.IsValid() {
+			add(slog.String("trace_id", spanCtx.TraceID().String()))
+			add(slog.String("span_id", spanCtx.SpanID().String()))
+		}
+		for _, attr := range LogAttrsFromContext(c) {
+			add(attr)
+		}
+	}
+
+	addFrom(ctx)
+	if h.bound != nil && h.bound != ctx {
+		addFrom(h.bound)
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{inner: h.inner.WithAttrs(attrs), bound: h.bound}
+}
+
+// WithGroup implements slog.Handler.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	return &ContextHandler{inner: h.inner.WithGroup(name), bound: h.bound}
+}
+
+// LoggerFromContext returns the default logger bound to ctx.
+//
+// Description:
+//
+//	Every record from the returned logger carries the correlation fields
+//	of ctx, even when logged without a context (logger.Info). Use it where
+//	a logger is built once per request, e.g. in HTTP handlers.
+//
+// Inputs:
+//
+//	ctx - The request or session context. May be nil.
+//
+// Outputs:
+//
+//	*slog.Logger - A logger bound to ctx; slog.Default() if ctx is nil.
+//
+// Thread Safety: Safe for concurrent use.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if ctx == nil {
+		return logger
+	}
+	inner := logger.Handler()
+	if ch, ok := inner.(*ContextHandler); ok {
+		inner = ch.inner
+	}
+	return slog.New(&ContextHandler{inner: inner, bound: ctx})
+}
+
+// SetupLogging configures the process-wide default slog logger.
+//
+// Description:
+//
+//	Installs a text or JSON handler writing to w, wrapped in a
+//	ContextHandler, as slog's default. The log package is redirected to it
+//	too. The level can be changed later with SetLogLevel.
+//
+// Inputs:
+//
+//	w - Destination for log output, typically os.Stderr. Must not be nil.
+//	format - LogFormatText or LogFormatJSON.
+//	level - The initial minimum level.
+//
+// Outputs:
+//
+//	error - Non-nil if format is unknown. The default logger is unchanged.
+//
+// Thread Safety: Call once at startup, before serving requests.
+func SetupLogging(w io.Writer, format string, level slog.Level) error {
+	opts := &slog.HandlerOptions{Level: &logLevel}
+	var inner slog.Handler
+	switch strings.ToLower(format) {
+	case LogFormatText, "":
+		inner = slog.NewTextHandler(w, opts)
+	case LogFormatJSON:
+		inner = slog.NewJSONHandler(w, opts)
+	default:
+		return fmt.Errorf("unknown log format %q: want %q or %q", format, LogFormatText, LogFormatJSON)
+	}
+	logLevel.Set(level)
+	slog.SetDefault(slog.New(NewContextHandler(inner)))
+	return nil
+}
+
+// ParseLogLevel parses "debug", "info", "warn" or "error", case-insensitively.
+func ParseLogLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: want debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// LogLevel returns the current process-wide log level.
+func LogLevel() slog.Level {
+	return logLevel.Level()
+}
+
+// SetLogLevel changes the process-wide log level and returns the previous
+// one. It affects handlers installed by SetupLogging.
+//
+// Thread Safety: Safe for concurrent use.
+func SetLogLevel(level slog.Level) slog.Level {
+	previous := logLevel.Level()
+	logLevel.Set(level)
+	return previous
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanContext returns ctx with a valid, fixed span context.
+func spanContext(t *testing.T) (context.Context, trace.SpanContext) {
+	t.Helper()
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID})
+	return trace.ContextWithSpanContext(context.Background(), sc), sc
+}
+
+// decodeLines parses JSON log lines.
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestContextHandler_AddsContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil)))
+
+	ctx, sc := spanContext(t)
+	ctx = ContextWithLogAttrs(ctx, slog.String("session_id", "sess-1"))
+	ctx = ContextWithLogAttrs(ctx, slog.String("tenant", "acme"))
+
+	logger.InfoContext(ctx, "with context")
+	logger.InfoContext(ctx, "explicit", slog.String("session_id", "explicit"))
+	logger.Info("without context")
+
+	records := decodeLines(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	if records[0]["trace_id"] != sc.TraceID().String() || records[0]["span_id"] != sc.SpanID().String() {
+		t.Errorf("record 0 trace fields = %v, %v", records[0]["trace_id"], records[0]["span_id"])
+	}
+	if records[0]["session_id"] != "sess-1" || records[0]["tenant"] != "acme" {
+		t.Errorf("record 0 attrs = %v", records[0])
+	}
+
+	// A key already on the record is not repeated.
+	if got := strings.Count(strings.Split(buf.String(), "\n")[1], `"session_id"`); got != 1 {
+		t.Errorf("session_id appears %d times, want 1", got)
+	}
+	if records[1]["session_id"] != "explicit" {
+		t.Errorf("record 1 session_id = %v, want explicit", records[1]["session_id"])
+	}
+
+	if _, ok := records[2]["trace_id"]; ok {
+		t.Errorf("record without context has trace_id: %v", records[2])
+	}
+}
+
+func TestLoggerFromContext_BindsContext(t *testing.T) {
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(NewContextHandler(slog.NewJSONHandler(&buf, nil))))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	ctx, sc := spanContext(t)
+	ctx = ContextWithLogAttrs(ctx, slog.String("session_id", "sess-2"))
+
+	LoggerFromContext(ctx).With("handler", "test").Info("bound")
+
+	records := decodeLines(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	rec := records[0]
+	if rec["trace_id"] != sc.TraceID().String() || rec["session_id"] != "sess-2" || rec["handler"] != "test" {
+		t.Errorf("record = %v", rec)
+	}
+}
+
+func TestSetupLogging_FormatAndLevel(t *testing.T) {
+	previous := slog.Default()
+	previousLevel := LogLevel()
+	t.Cleanup(func() {
+		slog.SetDefault(previous)
+		SetLogLevel(previousLevel)
+	})
+
+	var buf bytes.Buffer
+	if err := SetupLogging(&buf, "yaml", slog.LevelInfo); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+	if err := SetupLogging(&buf, LogFormatJSON, slog.LevelWarn); err != nil {
+		t.Fatalf("SetupLogging: %v", err)
+	}
+
+	slog.Info("dropped")
+	slog.Warn("kept")
+	if old := SetLogLevel(slog.LevelDebug); old != slog.LevelWarn {
+		t.Errorf("previous level = %v, want WARN", old)
+	}
+	slog.Debug("now kept")
+
+	records := decodeLines(t, &buf)
+	if len(records) != 2 || records[0]["msg"] != "kept" || records[1]["msg"] != "now kept" {
+		t.Errorf("records = %v, want [kept, now kept]", records)
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{" warn ", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLogLevel(tt.in)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParseLogLevel(%q) = %v, %v; want %v, err %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
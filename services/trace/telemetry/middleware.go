@@ -91,14 +91,18 @@ func TracingMiddleware(tracerName string) func(http.Handler) http.Handler {
 			// Extract trace context from incoming headers
 			ctx := otel.GetTextMapPropagator().Extract(r.Context(), &headerCarrier{r.Header})
 
-			// Create span with HTTP semantic attributes
+			// Create span with HTTP semantic attributes. Paths can carry
+			// symbol IDs, so they follow the privacy mode.
 			spanName := r.Method + " " + r.URL.Path
+			if CurrentPrivacyMode() != PrivacyFull {
+				spanName = r.Method
+			}
 			ctx, span := tracer.Start(ctx, spanName,
 				trace.WithSpanKind(trace.SpanKindServer),
 				trace.WithAttributes(
 					attribute.String("http.method", r.Method),
-					attribute.String("http.url", r.URL.String()),
-					attribute.String("http.target", r.URL.Path),
+					SensitiveString("http.url", r.URL.String()),
+					SensitiveString("http.target", r.URL.Path),
 					attribute.String("http.host", r.Host),
 					attribute.String("http.scheme", schemeFromRequest(r)),
 					attribute.String("http.user_agent", r.UserAgent()),
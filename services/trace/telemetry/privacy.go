@@ -0,0 +1,192 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package telemetry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PrivacyMode controls what span attributes and log fields reveal about
+// the code being analyzed: file paths, symbol names and IDs, queries and
+// code snippets.
+type PrivacyMode string
+
+// Privacy modes accepted by ParsePrivacyMode.
+const (
+	// PrivacyFull records sensitive values as they are. The default, for
+	// local use.
+	PrivacyFull PrivacyMode = "full"
+
+	// PrivacyPathsHashed replaces sensitive values with stable hashes
+	// (see HashValue), so spans and logs about the same file or symbol can
+	// still be correlated.
+	PrivacyPathsHashed PrivacyMode = "paths_hashed"
+
+	// PrivacyMinimal drops sensitive values, leaving counts, durations
+	// and enumerations such as tool names and outcomes.
+	PrivacyMinimal PrivacyMode = "minimal"
+)
+
+// EnvPrivacyMode is the environment variable read by DefaultConfig.
+const EnvPrivacyMode = "TRACE_TELEMETRY_PRIVACY"
+
+// privacyMode is the process-wide privacy mode. Nil means PrivacyFull.
+var privacyMode atomic.Pointer[PrivacyMode]
+
+// ParsePrivacyMode parses "full", "paths_hashed" or "minimal",
+// case-insensitively. An empty string is PrivacyFull.
+func ParsePrivacyMode(s string) (PrivacyMode, error) {
+	switch m := PrivacyMode(strings.ToLower(strings.TrimSpace(s))); m {
+	case "":
+		return PrivacyFull, nil
+	case PrivacyFull, PrivacyPathsHashed, PrivacyMinimal:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid telemetry privacy mode %q: want full, paths_hashed or minimal", s)
+}
+
+// CurrentPrivacyMode returns the process-wide privacy mode.
+//
+// Thread Safety: Safe for concurrent use.
+func CurrentPrivacyMode() PrivacyMode {
+	if m := privacyMode.Load(); m != nil {
+		return *m
+	}
+	return PrivacyFull
+}
+
+// SetPrivacyMode changes the process-wide privacy mode and returns the
+// previous one. Init sets it from Config.Privacy; tests may set it
+// directly. Attributes and log fields built before the change keep their
+// values.
+//
+// Thread Safety: Safe for concurrent use.
+func SetPrivacyMode(m PrivacyMode) PrivacyMode {
+	previous := CurrentPrivacyMode()
+	privacyMode.Store(&m)
+	return previous
+}
+
+// HashValue returns a stable, non-reversible token for a sensitive value:
+// "h:" and the first 16 hex digits of its SHA-256. Equal values give equal
+// tokens in every process.
+func HashValue(v string) string {
+	sum := sha256.Sum256([]byte(v))
+	return "h:" + hex.EncodeToString(sum[:8])
+}
+
+// Redact returns v as the current privacy mode allows it to be recorded:
+// v itself, its HashValue, or "" in minimal mode. Use it for values
+// embedded in span names, events or messages; prefer SensitiveString and
+// SensitiveLog for attributes and log fields.
+//
+// Thread Safety: Safe for concurrent use.
+func Redact(v string) string {
+	switch CurrentPrivacyMode() {
+	case PrivacyPathsHashed:
+		return HashValue(v)
+	case PrivacyMinimal:
+		return ""
+	}
+	return v
+}
+
+// SensitiveString returns a span attribute for a file path, symbol name or
+// ID, query or code snippet.
+//
+// Description:
+//
+//	In full mode it is attribute.String(key, v). In paths_hashed mode the
+//	value is HashValue(v). In minimal mode it is the zero KeyValue, which
+//	Span.SetAttributes and trace.WithAttributes discard.
+//
+// Thread Safety: Safe for concurrent use.
+func SensitiveString(key, v string) attribute.KeyValue {
+	switch CurrentPrivacyMode() {
+	case PrivacyPathsHashed:
+		return attribute.String(key, HashValue(v))
+	case PrivacyMinimal:
+		return attribute.KeyValue{}
+	}
+	return attribute.String(key, v)
+}
+
+// SensitiveStrings is SensitiveString for a list of values. In minimal
+// mode it records only their number, as key + "_count".
+//
+// Thread Safety: Safe for concurrent use.
+func SensitiveStrings(key string, vs []string) attribute.KeyValue {
+	switch CurrentPrivacyMode() {
+	case PrivacyPathsHashed:
+		return attribute.StringSlice(key, hashAll(vs))
+	case PrivacyMinimal:
+		return attribute.Int(key+"_count", len(vs))
+	}
+	return attribute.StringSlice(key, vs)
+}
+
+// SensitiveLog returns a log field for a file path, symbol name or ID,
+// query or code snippet: slog.String(key, v) in full mode, the value's
+// HashValue in paths_hashed mode, and the empty Attr, which slog handlers
+// ignore, in minimal mode.
+//
+// Thread Safety: Safe for concurrent use.
+func SensitiveLog(key, v string) slog.Attr {
+	switch CurrentPrivacyMode() {
+	case PrivacyPathsHashed:
+		return slog.String(key, HashValue(v))
+	case PrivacyMinimal:
+		return slog.Attr{}
+	}
+	return slog.String(key, v)
+}
+
+// SensitiveLogError is SensitiveLog for an error, whose message usually
+// names files and symbols. A nil error logs as slog.Any(key, nil) in every
+// mode, as it would unredacted.
+//
+// Thread Safety: Safe for concurrent use.
+func SensitiveLogError(key string, err error) slog.Attr {
+	if err == nil || CurrentPrivacyMode() == PrivacyFull {
+		return slog.Any(key, err)
+	}
+	return SensitiveLog(key, err.Error())
+}
+
+// SensitiveLogStrings is SensitiveLog for a list of values. In minimal
+// mode it logs only their number, as key + "_count".
+//
+// Thread Safety: Safe for concurrent use.
+func SensitiveLogStrings(key string, vs []string) slog.Attr {
+	switch CurrentPrivacyMode() {
+	case PrivacyPathsHashed:
+		return slog.Any(key, hashAll(vs))
+	case PrivacyMinimal:
+		return slog.Int(key+"_count", len(vs))
+	}
+	return slog.Any(key, vs)
+}
+
+// hashAll returns the HashValue of each value.
+func hashAll(vs []string) []string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = HashValue(v)
+	}
+	return out
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// withPrivacyMode sets the privacy mode for the rest of the test.
+func withPrivacyMode(t *testing.T, m PrivacyMode) {
+	t.Helper()
+	previous := SetPrivacyMode(m)
+	t.Cleanup(func() { SetPrivacyMode(previous) })
+}
+
+func TestParsePrivacyMode(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    PrivacyMode
+		wantErr bool
+	}{
+		{"", PrivacyFull, false},
+		{"full", PrivacyFull, false},
+		{" Paths_Hashed ", PrivacyPathsHashed, false},
+		{"minimal", PrivacyMinimal, false},
+		{"none", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParsePrivacyMode(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParsePrivacyMode(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestInit_InvalidPrivacyMode(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.TraceExporter = "none"
+	cfg.MetricExporter = "none"
+	cfg.Privacy = "secret"
+	if _, err := Init(context.Background(), cfg); err == nil {
+		t.Fatal("Init() succeeded with an invalid privacy mode")
+	}
+}
+
+func TestSensitiveHelpers(t *testing.T) {
+	const path = "pkg/payments/stripe.go"
+	files := []string{"a.go", "b.go"}
+	err := &fs.PathError{Op: "open", Path: path, Err: errors.New("denied")}
+
+	t.Run("full", func(t *testing.T) {
+		withPrivacyMode(t, PrivacyFull)
+		if kv := SensitiveString("file", path); kv != attribute.String("file", path) {
+			t.Errorf("SensitiveString = %v", kv)
+		}
+		if kv := SensitiveStrings("files", files); kv.Key != "files" || len(kv.Value.AsStringSlice()) != 2 {
+			t.Errorf("SensitiveStrings = %v", kv)
+		}
+		if a := SensitiveLog("file", path); a.Value.String() != path {
+			t.Errorf("SensitiveLog = %v", a)
+		}
+		if a := SensitiveLogError("error", err); a.Value.Any() != error(err) {
+			t.Errorf("SensitiveLogError = %v, want the error itself", a)
+		}
+		if got := Redact(path); got != path {
+			t.Errorf("Redact = %q", got)
+		}
+	})
+
+	t.Run("paths_hashed", func(t *testing.T) {
+		withPrivacyMode(t, PrivacyPathsHashed)
+		hash := HashValue(path)
+		if !strings.HasPrefix(hash, "h:") || len(hash) != 18 || hash != HashValue(path) || hash == HashValue(files[0]) {
+			t.Errorf("HashValue = %q: want a stable h: token of 16 hex digits", hash)
+		}
+		if kv := SensitiveString("file", path); kv.Value.AsString() != hash {
+			t.Errorf("SensitiveString = %v, want %s", kv, hash)
+		}
+		if kv := SensitiveStrings("files", files); kv.Value.AsStringSlice()[1] != HashValue("b.go") {
+			t.Errorf("SensitiveStrings = %v", kv)
+		}
+		if a := SensitiveLog("file", path); a.Value.String() != hash {
+			t.Errorf("SensitiveLog = %v", a)
+		}
+		if a := SensitiveLogError("error", err); a.Value.String() != HashValue(err.Error()) {
+			t.Errorf("SensitiveLogError = %v", a)
+		}
+	})
+
+	t.Run("minimal", func(t *testing.T) {
+		withPrivacyMode(t, PrivacyMinimal)
+		if kv := SensitiveString("file", path); kv.Valid() {
+			t.Errorf("SensitiveString = %v, want the zero KeyValue", kv)
+		}
+		if kv := SensitiveStrings("files", files); kv != attribute.Int("files_count", 2) {
+			t.Errorf("SensitiveStrings = %v", kv)
+		}
+		if a := SensitiveLog("file", path); !a.Equal(slog.Attr{}) {
+			t.Errorf("SensitiveLog = %v, want the empty Attr", a)
+		}
+		if a := SensitiveLogError("error", err); !a.Equal(slog.Attr{}) {
+			t.Errorf("SensitiveLogError = %v, want the empty Attr", a)
+		}
+		if a := SensitiveLogError("error", nil); a.Key != "error" || a.Value.Any() != nil {
+			t.Errorf("SensitiveLogError(nil) = %v", a)
+		}
+		if a := SensitiveLogStrings("files", files); a.Key != "files_count" || a.Value.Int64() != 2 {
+			t.Errorf("SensitiveLogStrings = %v", a)
+		}
+		if got := Redact(path); got != "" {
+			t.Errorf("Redact = %q", got)
+		}
+	})
+}
+
+func TestPrivacy_SpanEventsAndErrors(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp)).Tracer("test")
+	withPrivacyMode(t, PrivacyMinimal)
+
+	err := &fs.PathError{Op: "open", Path: "/home/dev/shop/pkg/secret.go", Err: errors.New("denied")}
+	_, span := tracer.Start(context.Background(), "op")
+	AddSpanEvent(span, "resolved", SensitiveString("symbol", "PlaceOrder"), attribute.Int("count", 3))
+	RecordError(span, err)
+	span.End()
+
+	spans := exp.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	s := spans[0]
+	if s.Status.Description != "*fs.PathError" {
+		t.Errorf("status description = %q, want the error type", s.Status.Description)
+	}
+	for _, ev := range s.Events {
+		for _, a := range ev.Attributes {
+			if !a.Valid() {
+				t.Errorf("event %s has an invalid attribute", ev.Name)
+			}
+			if v := a.Value.Emit(); strings.Contains(v, "secret.go") || strings.Contains(v, "PlaceOrder") {
+				t.Errorf("event %s attribute %s = %q leaks a sensitive value", ev.Name, a.Key, v)
+			}
+		}
+	}
+	if len(s.Events) != 2 || len(s.Events[0].Attributes) != 1 {
+		t.Errorf("events = %+v, want resolved with count only, then exception", s.Events)
+	}
+}
@@ -16,6 +16,8 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -66,6 +68,20 @@ type Config struct {
 	// Otherwise, TraceIDRatioBased() is used.
 	SampleRate float64 `json:"sample_rate"`
 
+	// Sampler names a standard OTel sampler, as in OTEL_TRACES_SAMPLER:
+	// always_on, always_off, traceidratio, parentbased_always_on,
+	// parentbased_always_off or parentbased_traceidratio. When set it
+	// overrides SampleRate. Empty uses SampleRate.
+	Sampler string `json:"sampler,omitempty"`
+
+	// SamplerArg is the sampler argument, as in OTEL_TRACES_SAMPLER_ARG.
+	// For the ratio samplers it is the ratio (0.0-1.0, default 1.0).
+	SamplerArg string `json:"sampler_arg,omitempty"`
+
+	// Privacy is the telemetry privacy mode: "full", "paths_hashed" or
+	// "minimal" (see PrivacyMode). Empty is "full".
+	Privacy string `json:"privacy,omitempty"`
+
 	// AllowDegraded allows the service to start even if telemetry backends are unavailable.
 	// When true, Init() logs a warning but returns success with noop providers.
 	// Default: true (server starts cleanly without OTel backends).
@@ -79,6 +95,8 @@ type Config struct {
 //   - OTEL_TRACES_EXPORTER: trace exporter type
 //   - OTEL_METRICS_EXPORTER: metric exporter type
 //   - OTEL_EXPORTER_OTLP_ENDPOINT: OTLP endpoint
+//   - OTEL_TRACES_SAMPLER, OTEL_TRACES_SAMPLER_ARG: trace sampler
+//   - TRACE_TELEMETRY_PRIVACY: privacy mode (full, paths_hashed, minimal)
 func DefaultConfig() Config {
 	return Config{
 		ServiceName:    "aleutian",
@@ -90,6 +108,9 @@ func DefaultConfig() Config {
 		OTLPInsecure:   true,
 		PrometheusPort: 9090,
 		SampleRate:     1.0, // 100% sampling for development
+		Sampler:        os.Getenv("OTEL_TRACES_SAMPLER"),
+		SamplerArg:     os.Getenv("OTEL_TRACES_SAMPLER_ARG"),
+		Privacy:        getEnvOr(EnvPrivacyMode, string(PrivacyFull)),
 		AllowDegraded:  true,
 	}
 }
@@ -99,8 +120,9 @@ func DefaultConfig() Config {
 // Description:
 //
 //	Sets up OpenTelemetry TracerProvider and MeterProvider based on the
-//	configuration. After Init returns successfully, otel.Tracer() and
-//	otel.Meter() can be used throughout the application.
+//	configuration and sets the process-wide privacy mode. After Init
+//	returns successfully, otel.Tracer() and otel.Meter() can be used
+//	throughout the application.
 //
 // Inputs:
 //
@@ -110,7 +132,7 @@ func DefaultConfig() Config {
 // Outputs:
 //
 //	shutdown - Function to call on application exit for cleanup. Must be called.
-//	error - Non-nil if initialization fails.
+//	error - Non-nil if the privacy mode is invalid or initialization fails.
 //
 // Example:
 //
@@ -125,6 +147,11 @@ func Init(ctx context.Context, cfg Config) (shutdown func(context.Context) error
 	if ctx == nil {
 		return nil, ErrNilContext
 	}
+	privacy, err := ParsePrivacyMode(cfg.Privacy)
+	if err != nil {
+		return nil, err
+	}
+	SetPrivacyMode(privacy)
 
 	var shutdownFuncs []func(context.Context) error
 
@@ -181,8 +208,14 @@ func Init(ctx context.Context, cfg Config) (shutdown func(context.Context) error
 
 // initTracer creates and returns a configured TracerProvider.
 func initTracer(ctx context.Context, cfg Config, res *resource.Resource) (*trace.TracerProvider, error) {
+	// Select sampler first so a bad sampler config fails before an exporter
+	// is opened. A named sampler (OTEL_TRACES_SAMPLER) wins over SampleRate.
+	sampler, err := newSampler(cfg)
+	if err != nil {
+		return nil, err
+	}
+
 	var exporter trace.SpanExporter
-	var err error
 
 	switch cfg.TraceExporter {
 	case "otlp", "jaeger":
@@ -198,7 +231,7 @@ func initTracer(ctx context.Context, cfg Config, res *resource.Resource) (*trace
 			if cfg.AllowDegraded {
 				slog.Warn("trace exporter unavailable, using noop provider",
 					slog.String("endpoint", cfg.OTLPEndpoint),
-					slog.String("error", err.Error()))
+					SensitiveLogError("error", err))
 				return trace.NewTracerProvider(trace.WithResource(res)), nil
 			}
 			return nil, fmt.Errorf("create OTLP exporter: %w", err)
@@ -214,9 +247,6 @@ func initTracer(ctx context.Context, cfg Config, res *resource.Resource) (*trace
 		return nil, fmt.Errorf("%w: %s", ErrUnknownExporter, cfg.TraceExporter)
 	}
 
-	// Select sampler based on SampleRate configuration
-	sampler := getSampler(cfg.SampleRate)
-
 	// Create TracerProvider with batcher (batches spans before export)
 	tp := trace.NewTracerProvider(
 		trace.WithBatcher(exporter),
@@ -242,6 +272,68 @@ func getSampler(rate float64) trace.Sampler {
 	return trace.TraceIDRatioBased(rate)
 }
 
+// newSampler returns the sampler named by cfg.Sampler, or the SampleRate
+// sampler if none is named.
+//
+// Description:
+//
+//	Accepts the sampler names and arguments of the OTel SDK environment
+//	variable spec. The parent-based samplers follow the sampling decision
+//	of an incoming traceparent, so a sampled caller gets the full agent
+//	trace.
+//
+// Inputs:
+//
+//	cfg - Telemetry configuration.
+//
+// Outputs:
+//
+//	trace.Sampler - The sampler.
+//	error - ErrUnknownSampler for an unsupported name or invalid ratio.
+func newSampler(cfg Config) (trace.Sampler, error) {
+	name := strings.ToLower(strings.TrimSpace(cfg.Sampler))
+	if name == "" {
+		return getSampler(cfg.SampleRate), nil
+	}
+
+	ratio := func() (float64, error) {
+		arg := strings.TrimSpace(cfg.SamplerArg)
+		if arg == "" {
+			return 1.0, nil
+		}
+		r, err := strconv.ParseFloat(arg, 64)
+		if err != nil || r < 0 || r > 1 {
+			return 0, fmt.Errorf("%w: %s ratio %q must be between 0 and 1", ErrUnknownSampler, name, arg)
+		}
+		return r, nil
+	}
+
+	switch name {
+	case "always_on":
+		return trace.AlwaysSample(), nil
+	case "always_off":
+		return trace.NeverSample(), nil
+	case "traceidratio":
+		r, err := ratio()
+		if err != nil {
+			return nil, err
+		}
+		return trace.TraceIDRatioBased(r), nil
+	case "parentbased_always_on":
+		return trace.ParentBased(trace.AlwaysSample()), nil
+	case "parentbased_always_off":
+		return trace.ParentBased(trace.NeverSample()), nil
+	case "parentbased_traceidratio":
+		r, err := ratio()
+		if err != nil {
+			return nil, err
+		}
+		return trace.ParentBased(trace.TraceIDRatioBased(r)), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSampler, cfg.Sampler)
+	}
+}
+
 // getEnvOr returns the environment variable value or the fallback.
 func getEnvOr(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
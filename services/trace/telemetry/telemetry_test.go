@@ -13,6 +13,7 @@ package telemetry
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
@@ -408,6 +409,58 @@ func TestGetSampler(t *testing.T) {
 	}
 }
 
+func TestNewSampler(t *testing.T) {
+	tests := []struct {
+		sampler string
+		arg     string
+		want    string
+		wantErr bool
+	}{
+		{"", "", "AlwaysOnSampler", false},
+		{"always_off", "", "AlwaysOffSampler", false},
+		{"traceidratio", "0.25", "TraceIDRatioBased{0.25}", false},
+		{"traceidratio", "", "AlwaysOnSampler", false},
+		{"parentbased_always_on", "", "ParentBased{root:AlwaysOnSampler", false},
+		{"ParentBased_TraceIDRatio", "0.5", "ParentBased{root:TraceIDRatioBased{0.5}", false},
+		{"traceidratio", "1.5", "", true},
+		{"traceidratio", "half", "", true},
+		{"jaeger_remote", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sampler+"/"+tt.arg, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.SampleRate = 1.0
+			cfg.Sampler = tt.sampler
+			cfg.SamplerArg = tt.arg
+
+			sampler, err := newSampler(cfg)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownSampler) {
+					t.Fatalf("newSampler() error = %v, want ErrUnknownSampler", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newSampler() error = %v", err)
+			}
+			if !strings.HasPrefix(sampler.Description(), tt.want) {
+				t.Errorf("Description() = %q, want prefix %q", sampler.Description(), tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultConfig_SamplerFromEnv(t *testing.T) {
+	t.Setenv("OTEL_TRACES_SAMPLER", "parentbased_traceidratio")
+	t.Setenv("OTEL_TRACES_SAMPLER_ARG", "0.1")
+
+	cfg := DefaultConfig()
+	if cfg.Sampler != "parentbased_traceidratio" || cfg.SamplerArg != "0.1" {
+		t.Errorf("Sampler = %q, SamplerArg = %q", cfg.Sampler, cfg.SamplerArg)
+	}
+}
+
 func TestInit_WithSampleRate(t *testing.T) {
 	t.Run("full sampling (1.0)", func(t *testing.T) {
 		cfg := DefaultConfig()
@@ -86,7 +86,8 @@ func SpanFromContext(ctx context.Context) trace.Span {
 // Description:
 //
 //	Records the error as a span event and sets the span status to Error.
-//	If the span or error is nil, this is a no-op.
+//	Unless the privacy mode is full, the message is left out and only the
+//	error's type is recorded. If the span or error is nil, this is a no-op.
 //
 // Inputs:
 //
@@ -108,15 +109,7 @@ func RecordError(span trace.Span, err error, attrs ...attribute.KeyValue) {
 		return
 	}
 
-	// Record the error as a span event with additional attributes
-	opts := make([]trace.EventOption, 0, 1)
-	if len(attrs) > 0 {
-		opts = append(opts, trace.WithAttributes(attrs...))
-	}
-	span.RecordError(err, opts...)
-
-	// Set span status to error
-	span.SetStatus(codes.Error, err.Error())
+	recordError(span, err, attrs...)
 }
 
 // RecordErrorf records a formatted error message on the current span.
@@ -144,8 +137,50 @@ func RecordErrorf(span trace.Span, format string, args ...interface{}) {
 	if span == nil {
 		return
 	}
-	err := fmt.Errorf(format, args...)
-	span.RecordError(err)
+	recordError(span, fmt.Errorf(format, args...))
+}
+
+// recordError records err as an exception event and sets the span status
+// to error.
+func recordError(span trace.Span, err error, attrs ...attribute.KeyValue) {
+	RecordException(span, err, attrs...)
+	SetErrorStatus(span, err)
+}
+
+// RecordException records err as an exception event on span without
+// changing its status. It is span.RecordError with the privacy mode
+// applied: error messages often quote paths and symbol names, so unless
+// the mode is full only the error's type is recorded.
+//
+// Thread Safety: Safe for concurrent use.
+func RecordException(span trace.Span, err error, attrs ...attribute.KeyValue) {
+	if span == nil || err == nil {
+		return
+	}
+	if CurrentPrivacyMode() != PrivacyFull {
+		span.AddEvent("exception", trace.WithAttributes(
+			append([]attribute.KeyValue{attribute.String("exception.type", fmt.Sprintf("%T", err))}, attrs...)...))
+		return
+	}
+	var opts []trace.EventOption
+	if len(attrs) > 0 {
+		opts = append(opts, trace.WithAttributes(attrs...))
+	}
+	span.RecordError(err, opts...)
+}
+
+// SetErrorStatus sets the span status to error, described by err's
+// message, or by its type unless the privacy mode is full.
+//
+// Thread Safety: Safe for concurrent use.
+func SetErrorStatus(span trace.Span, err error) {
+	if span == nil || err == nil {
+		return
+	}
+	if CurrentPrivacyMode() != PrivacyFull {
+		span.SetStatus(codes.Error, fmt.Sprintf("%T", err))
+		return
+	}
 	span.SetStatus(codes.Error, err.Error())
 }
 
@@ -183,7 +218,9 @@ func SetSpanOK(span trace.Span) {
 // Description:
 //
 //	Records a timestamped event on the span. Events are useful for
-//	marking significant points in a span's lifecycle.
+//	marking significant points in a span's lifecycle. Attributes dropped
+//	by the privacy mode (the zero KeyValue from SensitiveString) are
+//	skipped.
 //
 // Inputs:
 //
@@ -200,7 +237,13 @@ func AddSpanEvent(span trace.Span, name string, attrs ...attribute.KeyValue) {
 	if span == nil {
 		return
 	}
-	span.AddEvent(name, trace.WithAttributes(attrs...))
+	valid := attrs[:0:0]
+	for _, a := range attrs {
+		if a.Valid() {
+			valid = append(valid, a)
+		}
+	}
+	span.AddEvent(name, trace.WithAttributes(valid...))
 }
 
 // SetSpanAttributes sets attributes on the span.
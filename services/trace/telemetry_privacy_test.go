@@ -0,0 +1,153 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	agentllm "github.com/AleutianAI/AleutianFOSS/services/trace/agent/llm"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// TestTelemetryPrivacy_MinimalSpans builds a graph, looks up its symbols
+// and answers a quick agent query in minimal mode, then checks that no
+// exported span or event attribute and no log record names a file or a
+// symbol of the project.
+func TestTelemetryPrivacy_MinimalSpans(t *testing.T) {
+	exporter := recordSpans(t)
+	logs := captureLogs(t)
+	previous := telemetry.SetPrivacyMode(telemetry.PrivacyMinimal)
+	t.Cleanup(func() { telemetry.SetPrivacyMode(previous) })
+
+	root := writeMonorepo(t)
+	svc := NewService(DefaultServiceConfig())
+	graphID := initGraphOverHTTP(t, setupTestRouter(svc), root)
+	for _, path := range []string{
+		"/v1/trace/symbol/charge.go:7:Charge?graph_id=" + graphID,
+		"/v1/trace/symbol/charge.go:7:Charge/source?graph_id=" + graphID,
+		"/v1/trace/callers?graph_id=" + graphID + "&function=Charge",
+		"/v1/trace/references?graph_id=" + graphID + "&symbol=PaymentStore",
+	} {
+		setupTestRouter(svc).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	mockLoop := &MockAgentLoop{
+		runFunc: func(ctx context.Context, session *agent.Session, query string) (*agent.RunResult, error) {
+			return &agent.RunResult{State: agent.StateComplete, Response: "full answer"}, nil
+		},
+	}
+	client := agentllm.NewMockClient().QueueFinalResponse("Charge is called by PlaceOrder.")
+	r := setupAgentTestRouter(NewAgentHandlers(mockLoop, svc,
+		WithQuickAnswer(QuickAnswerConfig{Client: client, Timeout: time.Minute})))
+	body, _ := json.Marshal(AgentRunRequest{Query: "Who calls Charge?", ProjectRoot: root})
+	req := httptest.NewRequest("POST", "/v1/trace/agent/run", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("agent run: status %d: %s", w.Code, w.Body.String())
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) == 0 {
+		t.Fatal("no spans exported")
+	}
+	pathLike := regexp.MustCompile(`[\w.-]+/[\w./-]*\.go\b|\w+\.go\b`)
+	identifier := regexp.MustCompile(`\b(Charge|PlaceOrder|PaymentStore|OrderStore|Store|Save|validate)\b`)
+	check := func(where string, attrs []attribute.KeyValue) {
+		for _, a := range attrs {
+			v := a.Value.Emit()
+			if strings.Contains(v, root) || pathLike.MatchString(v) || identifier.MatchString(v) {
+				t.Errorf("%s: attribute %s = %q reveals the project", where, a.Key, v)
+			}
+		}
+	}
+	for _, s := range spans {
+		check("span "+s.Name, s.Attributes)
+		for _, ev := range s.Events {
+			check("span "+s.Name+" event "+ev.Name, ev.Attributes)
+		}
+		if pathLike.MatchString(s.Status.Description) || identifier.MatchString(s.Status.Description) {
+			t.Errorf("span %s: status %q reveals the project", s.Name, s.Status.Description)
+		}
+	}
+
+	records := 0
+	for _, line := range strings.Split(logs.String(), "\n") {
+		if line == "" {
+			continue
+		}
+		var rec map[string]any
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			t.Fatalf("invalid JSON log line %q: %v", line, err)
+		}
+		records++
+		for key, value := range flattenLogRecord("", rec) {
+			if strings.Contains(value, root) || pathLike.MatchString(value) || identifier.MatchString(value) {
+				t.Errorf("log %q: field %s = %q reveals the project", rec["msg"], key, value)
+			}
+		}
+	}
+	if records == 0 {
+		t.Fatal("no log records captured")
+	}
+}
+
+// initGraphOverHTTP initializes root through POST /v1/trace/init and
+// returns the graph ID.
+func initGraphOverHTTP(t *testing.T, router http.Handler, root string) string {
+	t.Helper()
+	body, _ := json.Marshal(InitRequest{ProjectRoot: root})
+	req := httptest.NewRequest("POST", "/v1/trace/init", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("init: status %d: %s", w.Code, w.Body.String())
+	}
+	var resp InitResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("init response: %v", err)
+	}
+	return resp.GraphID
+}
+
+// flattenLogRecord returns the string form of every value in a decoded
+// JSON log record, keyed by its dotted field path. The time field is
+// skipped.
+func flattenLogRecord(prefix string, rec map[string]any) map[string]string {
+	out := make(map[string]string)
+	for k, v := range rec {
+		key := prefix + k
+		switch v := v.(type) {
+		case map[string]any:
+			for fk, fv := range flattenLogRecord(key+".", v) {
+				out[fk] = fv
+			}
+		default:
+			if key != "time" {
+				out[key] = fmt.Sprint(v)
+			}
+		}
+	}
+	return out
+}
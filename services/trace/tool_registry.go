@@ -10,6 +10,12 @@
 
 package trace
 
+import (
+	"strconv"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+)
+
 // ToolParam represents a parameter in a tool definition.
 type ToolParam struct {
 	Name        string   `json:"name"`
@@ -28,6 +34,14 @@ type ToolDefinition struct {
 	Parameters  []ToolParam `json:"parameters"`
 	Returns     string      `json:"returns"`
 	Performance string      `json:"performance"`
+
+	// InputSchema is the JSON Schema of the request body, derived from
+	// Parameters.
+	InputSchema *tools.JSONSchema `json:"input_schema"`
+
+	// OutputSchema is the JSON Schema of the AgenticResponse envelope;
+	// its result property is described by Returns.
+	OutputSchema *tools.JSONSchema `json:"output_schema"`
 }
 
 // ToolRegistry provides tool definitions for agent discovery.
@@ -41,8 +55,16 @@ type ToolRegistry struct {
 
 // NewToolRegistry creates a registry with all available tools.
 func NewToolRegistry() *ToolRegistry {
+	defs := allToolDefinitions()
+	for i := range defs {
+		if acceptsIncludeGenerated(defs[i]) {
+			defs[i].Parameters = append(defs[i].Parameters, includeGeneratedParam)
+		}
+		defs[i].InputSchema = inputSchema(defs[i].Parameters)
+		defs[i].OutputSchema = outputSchema(defs[i].Returns)
+	}
 	return &ToolRegistry{
-		tools: allToolDefinitions(),
+		tools: defs,
 	}
 }
 
@@ -62,7 +84,118 @@ func (r *ToolRegistry) GetToolsByCategory(category string) []ToolDefinition {
 	return result
 }
 
-// allToolDefinitions returns all 24 tool definitions.
+// OpenAITools returns the registry in the OpenAI function-calling format.
+func (r *ToolRegistry) OpenAITools() []tools.OpenAITool {
+	result := make([]tools.OpenAITool, 0, len(r.tools))
+	for _, t := range r.tools {
+		result = append(result, tools.OpenAITool{
+			Type: "function",
+			Function: tools.OpenAIFunction{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.InputSchema,
+			},
+		})
+	}
+	return result
+}
+
+// AnthropicTools returns the registry in the Anthropic tool-use format.
+func (r *ToolRegistry) AnthropicTools() []tools.AnthropicTool {
+	result := make([]tools.AnthropicTool, 0, len(r.tools))
+	for _, t := range r.tools {
+		result = append(result, tools.AnthropicTool{
+			Name:        t.Name,
+			Description: t.Description,
+			InputSchema: t.InputSchema,
+		})
+	}
+	return result
+}
+
+// includeGeneratedParam is shared by every tool that hides generated code.
+var includeGeneratedParam = ToolParam{
+	Name:        "include_generated",
+	Type:        "boolean",
+	Description: "Include hits in generated code (protobuf stubs, mocks, files marked DO NOT EDIT)",
+	Required:    false,
+	Default:     "false",
+}
+
+// acceptsIncludeGenerated reports whether def hides generated code unless
+// include_generated is set: graph-backed explore and reason tools, plus
+// duplication and dead code detection.
+func acceptsIncludeGenerated(def ToolDefinition) bool {
+	switch def.Name {
+	case "find_duplication", "find_dead_code":
+		return true
+	}
+	if def.Category != "explore" && def.Category != "reason" {
+		return false
+	}
+	for _, p := range def.Parameters {
+		if p.Name == "graph_id" {
+			return true
+		}
+	}
+	return false
+}
+
+// inputSchema converts endpoint parameters to an object schema. String
+// defaults are converted to the parameter's type.
+func inputSchema(params []ToolParam) *tools.JSONSchema {
+	def := tools.ToolDefinition{Parameters: make(map[string]tools.ParamDef, len(params))}
+	for _, p := range params {
+		pd := tools.ParamDef{
+			Type:        tools.ParamType(p.Type),
+			Description: p.Description,
+			Required:    p.Required,
+			Default:     typedDefault(p.Type, p.Default),
+		}
+		for _, e := range p.Enum {
+			pd.Enum = append(pd.Enum, e)
+		}
+		def.Parameters[p.Name] = pd
+	}
+	return def.InputSchema()
+}
+
+// typedDefault parses a string default as the parameter type. Empty or
+// unparsable defaults are omitted.
+func typedDefault(typ, value string) any {
+	if value == "" {
+		return nil
+	}
+	switch tools.ParamType(typ) {
+	case tools.ParamTypeString:
+		return value
+	case tools.ParamTypeInt:
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+	case tools.ParamTypeFloat:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case tools.ParamTypeBool:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return nil
+}
+
+// outputSchema describes the AgenticResponse envelope every endpoint
+// returns, with the endpoint's result described by returns.
+func outputSchema(returns string) *tools.JSONSchema {
+	schema := tools.SchemaOf(AgenticResponse{})
+	if result, ok := schema.Properties["result"]; ok {
+		result.Description = returns
+	}
+	return schema
+}
+
+// allToolDefinitions returns all 27 tool definitions.
 func allToolDefinitions() []ToolDefinition {
 	return []ToolDefinition{
 		// ==================== EXPLORATION TOOLS ====================
@@ -72,7 +205,7 @@ func allToolDefinitions() []ToolDefinition {
 			Category:    "explore",
 			Parameters: []ToolParam{
 				{Name: "graph_id", Type: "string", Description: "The graph ID from /init", Required: true},
-				{Name: "type", Type: "string", Description: "Filter by type: main, handler, command, test, lambda, grpc, all", Required: false, Default: "all", Enum: []string{"main", "handler", "command", "test", "lambda", "grpc", "all"}},
+				{Name: "type", Type: "string", Description: "Filter by type: main, handler, command, test, lambda, grpc, scheduled, all", Required: false, Default: "all", Enum: []string{"main", "handler", "command", "test", "lambda", "grpc", "scheduled", "all"}},
 				{Name: "package", Type: "string", Description: "Filter by package prefix", Required: false},
 				{Name: "limit", Type: "integer", Description: "Maximum results to return", Required: false, Default: "100"},
 				{Name: "include_tests", Type: "boolean", Description: "Include test entry points", Required: false, Default: "false"},
@@ -82,11 +215,11 @@ func allToolDefinitions() []ToolDefinition {
 		},
 		{
 			Name:        "trace_data_flow",
-			Description: "Trace how data flows from sources (HTTP input, env vars, files) to sinks (database, response, logs). Identifies transformations along the path. Critical for understanding dependencies.",
+			Description: "Trace how data flows from sources (HTTP input, env vars, files) to sinks (database, response, logs). Identifies transformations along the path. Critical for understanding dependencies. Given a Go struct field ID, lists the functions that write and read the field, one call hop beyond them, and where it is serialized (json tags, json.Marshal).",
 			Category:    "explore",
 			Parameters: []ToolParam{
 				{Name: "graph_id", Type: "string", Description: "The graph ID from /init", Required: true},
-				{Name: "source_id", Type: "string", Description: "Symbol ID to trace from", Required: true},
+				{Name: "source_id", Type: "string", Description: "Symbol ID to trace from; a Go struct field ID traces that field", Required: true},
 				{Name: "max_hops", Type: "integer", Description: "Maximum call depth to trace", Required: false, Default: "5"},
 				{Name: "include_code", Type: "boolean", Description: "Include code snippets", Required: false, Default: "true"},
 			},
@@ -250,6 +383,30 @@ func allToolDefinitions() []ToolDefinition {
 			Returns:     "Refactoring suggestions with priority and expected improvement",
 			Performance: "<100ms",
 		},
+		{
+			Name:        "analyze_impact",
+			Description: "Given a list of changed files, list the symbols they define plus direct and transitive dependents (callers, referrers, interface implementations). Flags affected tests so you know what to re-run.",
+			Category:    "reason",
+			Parameters: []ToolParam{
+				{Name: "graph_id", Type: "string", Description: "The graph ID from /init", Required: true},
+				{Name: "files", Type: "array", Description: "Changed file paths relative to the project root", Required: true},
+				{Name: "max_depth", Type: "integer", Description: "Dependent hops to follow (max 10)", Required: false, Default: "3"},
+			},
+			Returns:     "Per-file defined symbols, direct and transitive dependents with counts, and affected tests",
+			Performance: "<300ms",
+		},
+		{
+			Name:        "analyze_dependency_surface",
+			Description: "Given a third-party package (Go module, npm package, PyPI distribution or import path), list every internal symbol that imports or calls into it, grouped by package and depth, with call sites and the version resolved from go.mod/go.sum, package.json/lockfiles or requirements.txt/poetry.lock.",
+			Category:    "reason",
+			Parameters: []ToolParam{
+				{Name: "graph_id", Type: "string", Description: "The graph ID from /init", Required: true},
+				{Name: "package", Type: "string", Description: "The package name or import path, e.g. gopkg.in/yaml.v3, lodash or PyYAML", Required: true},
+				{Name: "max_depth", Type: "integer", Description: "Hops from the package: 1 = direct users, 2+ adds their callers (max 5)", Required: false, Default: "2"},
+			},
+			Returns:     "Resolved versions, external nodes matched, and exposed symbols grouped by package and depth",
+			Performance: "<200ms",
+		},
 
 		// ==================== COORDINATION TOOLS ====================
 		{
@@ -288,6 +445,19 @@ func allToolDefinitions() []ToolDefinition {
 			Returns:     "File diffs with hunks showing additions and removals",
 			Performance: "<100ms",
 		},
+		{
+			Name:        "preview_rename",
+			Description: "Preview renaming a symbol without applying it. Lists every edit (file, line, column, old and new text) from call, reference, implements and import sites, and flags name collisions, cross-language implementers and dynamic or string references that cannot be verified.",
+			Category:    "coordinate",
+			Parameters: []ToolParam{
+				{Name: "graph_id", Type: "string", Description: "The graph ID from /init", Required: true},
+				{Name: "target_id", Type: "string", Description: "Symbol to rename", Required: true},
+				{Name: "new_name", Type: "string", Description: "The new identifier", Required: true},
+				{Name: "include_tests", Type: "boolean", Description: "Include test file edits", Required: false, Default: "true"},
+			},
+			Returns:     "Change plan with one edit per occurrence and a risks list; its plan_id works with validate_plan and preview_changes",
+			Performance: "<300ms",
+		},
 
 		// ==================== PATTERN TOOLS ====================
 		{
@@ -319,16 +489,19 @@ func allToolDefinitions() []ToolDefinition {
 		},
 		{
 			Name:        "find_duplication",
-			Description: "Find duplicate or near-duplicate code using LSH-based similarity matching. O(n log n) performance.",
+			Description: "Find duplicate or near-duplicate code using LSH-based similarity matching. O(n log n) performance. Mode clusters compares identifier- and literal-normalized function bodies and also finds the same logic copied across Go, TypeScript, JavaScript and Python.",
 			Category:    "patterns",
 			Parameters: []ToolParam{
 				{Name: "graph_id", Type: "string", Description: "The graph ID from /init", Required: true},
 				{Name: "scope", Type: "string", Description: "Package or file to scan", Required: false, Default: ""},
 				{Name: "min_similarity", Type: "number", Description: "Minimum similarity (0.0-1.0)", Required: false, Default: "0.8"},
-				{Name: "type", Type: "string", Description: "Type: exact, near, structural, all", Required: false, Default: "all", Enum: []string{"exact", "near", "structural", "all"}},
+				{Name: "type", Type: "string", Description: "Type: exact, near, structural, all (pairs mode only)", Required: false, Default: "all", Enum: []string{"exact", "near", "structural", "all"}},
 				{Name: "include_tests", Type: "boolean", Description: "Include test files", Required: false, Default: "false"},
+				{Name: "mode", Type: "string", Description: "pairs: duplicate pairs from raw source; clusters: near-duplicate clusters across languages", Required: false, Default: "pairs", Enum: []string{"pairs", "clusters"}},
+				{Name: "min_body_tokens", Type: "integer", Description: "Minimum normalized body size to consider (clusters mode only)", Required: false, Default: "20"},
+				{Name: "cross_language_only", Type: "boolean", Description: "Only report clusters spanning more than one language (clusters mode only)", Required: false, Default: "false"},
 			},
-			Returns:     "Duplications with locations, similarity, and refactoring suggestions",
+			Returns:     "Duplications with locations, similarity, and refactoring suggestions; in clusters mode, clusters of members with file:line ranges and languages",
 			Performance: "<500ms",
 		},
 		{
@@ -21,6 +21,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // Manager provides atomic file operations with git-based rollback.
@@ -138,7 +140,7 @@ func NewManager(config Config) (*Manager, error) {
 	if config.CleanupOnInit {
 		if err := m.cleanupStale(context.Background()); err != nil {
 			m.logger.Warn("failed to cleanup stale transactions",
-				"error", err)
+				telemetry.SensitiveLogError("error", err))
 		}
 	}
 
@@ -339,7 +341,7 @@ func (m *Manager) Begin(ctx context.Context, sessionID string) (tx *Transaction,
 	if err := m.persistTransaction(tx); err != nil {
 		logger.Warn("failed to persist transaction state",
 			"tx_id", tx.ID,
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 	}
 
 	m.activeTransaction = tx
@@ -461,7 +463,7 @@ func (m *Manager) Commit(ctx context.Context, message string) (result *Result, e
 	if m.preflightStashRef != "" {
 		if cleanupErr := m.preflightGuard.Cleanup(ctx, m.preflightStashRef); cleanupErr != nil {
 			logger.Warn("failed to restore auto-stashed changes (may have conflicts)",
-				"error", cleanupErr)
+				telemetry.SensitiveLogError("error", cleanupErr))
 		}
 		m.preflightStashRef = ""
 	}
@@ -571,7 +573,7 @@ func (m *Manager) rollbackInternal(ctx context.Context, tx *Transaction, reason
 		tx.Error = err.Error()
 		logger.Error("CRITICAL: rollback failed",
 			"tx_id", tx.ID,
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 		return nil, fmt.Errorf("%w: %v", ErrRollbackFailed, err)
 	}
 
@@ -593,7 +595,7 @@ func (m *Manager) rollbackInternal(ctx context.Context, tx *Transaction, reason
 	if m.preflightStashRef != "" {
 		if cleanupErr := m.preflightGuard.Cleanup(ctx, m.preflightStashRef); cleanupErr != nil {
 			logger.Warn("failed to restore auto-stashed changes (may have conflicts)",
-				"error", cleanupErr)
+				telemetry.SensitiveLogError("error", cleanupErr))
 		}
 		m.preflightStashRef = ""
 	}
@@ -733,7 +735,7 @@ func (m *Manager) Close() error {
 	if m.preflightStashRef != "" {
 		if cleanupErr := m.preflightGuard.Cleanup(context.Background(), m.preflightStashRef); cleanupErr != nil {
 			m.logger.Warn("failed to restore auto-stashed changes on close",
-				"error", cleanupErr)
+				telemetry.SensitiveLogError("error", cleanupErr))
 		}
 		m.preflightStashRef = ""
 	}
@@ -805,7 +807,7 @@ func (m *Manager) commitStash(ctx context.Context, tx *Transaction, message stri
 		// Find and drop our stash
 		stashes, err := m.git.StashList(ctx)
 		if err != nil {
-			m.logger.Warn("failed to list stashes", "error", err)
+			m.logger.Warn("failed to list stashes", telemetry.SensitiveLogError("error", err))
 		} else {
 			stashMessage := fmt.Sprintf("agent-checkpoint-%s", tx.ID)
 			for _, stash := range stashes {
@@ -851,7 +853,7 @@ func (m *Manager) rollbackStash(ctx context.Context, tx *Transaction) error {
 
 	// Clean untracked files
 	if err := m.git.CleanUntracked(ctx); err != nil {
-		m.logger.Warn("failed to clean untracked files", "error", err)
+		m.logger.Warn("failed to clean untracked files", telemetry.SensitiveLogError("error", err))
 	}
 
 	// Restore stashed changes if any
@@ -859,14 +861,14 @@ func (m *Manager) rollbackStash(ctx context.Context, tx *Transaction) error {
 		// Find and pop our stash
 		stashes, err := m.git.StashList(ctx)
 		if err != nil {
-			m.logger.Warn("failed to list stashes", "error", err)
+			m.logger.Warn("failed to list stashes", telemetry.SensitiveLogError("error", err))
 		} else {
 			stashMessage := fmt.Sprintf("agent-checkpoint-%s", tx.ID)
 			for _, stash := range stashes {
 				if stash.Message == stashMessage {
 					if err := m.git.StashPop(ctx); err != nil {
 						m.logger.Warn("failed to pop stash (may have conflicts)",
-							"error", err)
+							telemetry.SensitiveLogError("error", err))
 					}
 					break
 				}
@@ -928,7 +930,7 @@ func (m *Manager) commitBranch(ctx context.Context, tx *Transaction, message str
 	// Delete the backup branch (we don't need it anymore)
 	if tx.WorkBranch != "" && m.git.BranchExists(ctx, tx.WorkBranch) {
 		if err := m.git.DeleteBranch(ctx, tx.WorkBranch, true); err != nil {
-			m.logger.Warn("failed to delete work branch", "error", err)
+			m.logger.Warn("failed to delete work branch", telemetry.SensitiveLogError("error", err))
 		}
 	}
 
@@ -974,13 +976,13 @@ func (m *Manager) rollbackBranch(ctx context.Context, tx *Transaction) error {
 
 	// Clean untracked files
 	if err := m.git.CleanUntracked(ctx); err != nil {
-		m.logger.Warn("failed to clean untracked files", "error", err)
+		m.logger.Warn("failed to clean untracked files", telemetry.SensitiveLogError("error", err))
 	}
 
 	// Delete the work branch
 	if tx.WorkBranch != "" && m.git.BranchExists(ctx, tx.WorkBranch) {
 		if err := m.git.DeleteBranch(ctx, tx.WorkBranch, true); err != nil {
-			m.logger.Warn("failed to delete work branch", "error", err)
+			m.logger.Warn("failed to delete work branch", telemetry.SensitiveLogError("error", err))
 		}
 	}
 
@@ -1001,7 +1003,7 @@ func (m *Manager) restoreUserStash(ctx context.Context, tx *Transaction) {
 	stashMessage := fmt.Sprintf("agent-user-wip-%s", tx.ID)
 	stashes, err := m.git.StashList(ctx)
 	if err != nil {
-		m.logger.Warn("failed to list stashes for user WIP restore", "error", err)
+		m.logger.Warn("failed to list stashes for user WIP restore", telemetry.SensitiveLogError("error", err))
 		return
 	}
 
@@ -1009,7 +1011,7 @@ func (m *Manager) restoreUserStash(ctx context.Context, tx *Transaction) {
 		if stash.Message == stashMessage {
 			if err := m.git.StashPop(ctx); err != nil {
 				m.logger.Warn("failed to restore user WIP (may have conflicts)",
-					"error", err)
+					telemetry.SensitiveLogError("error", err))
 			} else {
 				m.logger.Debug("restored user WIP", "tx_id", tx.ID)
 			}
@@ -1113,7 +1115,7 @@ func (m *Manager) commitWorktree(ctx context.Context, tx *Transaction, message s
 		// No changes - clean up worktree and return original checkpoint
 		m.logger.Info("no changes in worktree")
 		if err := m.git.RemoveWorktree(ctx, tx.WorktreePath, false); err != nil {
-			m.logger.Warn("failed to remove worktree", "error", err)
+			m.logger.Warn("failed to remove worktree", telemetry.SensitiveLogError("error", err))
 		}
 		return tx.CheckpointRef, nil
 	}
@@ -1141,7 +1143,7 @@ func (m *Manager) commitWorktree(ctx context.Context, tx *Transaction, message s
 
 	// Remove the worktree
 	if err := m.git.RemoveWorktree(ctx, tx.WorktreePath, false); err != nil {
-		m.logger.Warn("failed to remove worktree after commit", "error", err)
+		m.logger.Warn("failed to remove worktree after commit", telemetry.SensitiveLogError("error", err))
 	}
 
 	return commitSHA, nil
@@ -1172,7 +1174,7 @@ func (m *Manager) rollbackWorktree(ctx context.Context, tx *Transaction) error {
 		// If git removal fails, try direct filesystem removal
 		m.logger.Warn("git worktree remove failed, trying filesystem removal",
 			"path", tx.WorktreePath,
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 		if err := os.RemoveAll(tx.WorktreePath); err != nil {
 			return fmt.Errorf("removing worktree: %w", err)
 		}
@@ -1237,13 +1239,13 @@ func (m *Manager) cleanupStale(ctx context.Context) error {
 		path := filepath.Join(m.config.StateDir, entry.Name())
 		data, err := os.ReadFile(path)
 		if err != nil {
-			m.logger.Warn("failed to read stale transaction", "path", path, "error", err)
+			m.logger.Warn("failed to read stale transaction", "path", path, telemetry.SensitiveLogError("error", err))
 			continue
 		}
 
 		var tx Transaction
 		if err := json.Unmarshal(data, &tx); err != nil {
-			m.logger.Warn("failed to parse stale transaction", "path", path, "error", err)
+			m.logger.Warn("failed to parse stale transaction", "path", path, telemetry.SensitiveLogError("error", err))
 			_ = os.Remove(path)
 			continue
 		}
@@ -1256,7 +1258,7 @@ func (m *Manager) cleanupStale(ctx context.Context) error {
 		// Try to rollback the stale transaction
 		_, err = m.rollbackInternal(ctx, &tx, "stale transaction cleanup")
 		if err != nil {
-			m.logger.Warn("failed to rollback stale transaction", "tx_id", tx.ID, "error", err)
+			m.logger.Warn("failed to rollback stale transaction", "tx_id", tx.ID, telemetry.SensitiveLogError("error", err))
 		}
 
 		// Remove the state file regardless of rollback success
@@ -1369,7 +1371,7 @@ func (m *Manager) commitStashWithTrace(ctx context.Context, tx *Transaction, mes
 		recordGitOp(ctx, "stash_list", time.Since(start), err)
 		m.tracer.EndGitOp(gitSpan, err)
 		if err != nil {
-			m.logger.Warn("failed to list stashes", "error", err)
+			m.logger.Warn("failed to list stashes", telemetry.SensitiveLogError("error", err))
 		} else {
 			stashMessage := fmt.Sprintf("agent-checkpoint-%s", tx.ID)
 			for _, stash := range stashes {
@@ -1434,7 +1436,7 @@ func (m *Manager) commitBranchWithTrace(ctx context.Context, tx *Transaction, me
 		recordGitOp(ctx, "delete_branch", time.Since(start), err)
 		m.tracer.EndGitOp(gitSpan, err)
 		if err != nil {
-			m.logger.Warn("failed to delete work branch", "error", err)
+			m.logger.Warn("failed to delete work branch", telemetry.SensitiveLogError("error", err))
 		}
 	}
 
@@ -1501,7 +1503,7 @@ func (m *Manager) rollbackStashWithTrace(ctx context.Context, tx *Transaction) e
 	recordGitOp(ctx, "clean_untracked", time.Since(start), cleanErr)
 	m.tracer.EndGitOp(gitSpan, cleanErr)
 	if cleanErr != nil {
-		m.logger.Warn("failed to clean untracked files", "error", cleanErr)
+		m.logger.Warn("failed to clean untracked files", telemetry.SensitiveLogError("error", cleanErr))
 	}
 
 	// Restore stashed changes if any
@@ -1512,7 +1514,7 @@ func (m *Manager) rollbackStashWithTrace(ctx context.Context, tx *Transaction) e
 		recordGitOp(ctx, "stash_list", time.Since(start), err)
 		m.tracer.EndGitOp(gitSpan, err)
 		if err != nil {
-			m.logger.Warn("failed to list stashes", "error", err)
+			m.logger.Warn("failed to list stashes", telemetry.SensitiveLogError("error", err))
 		} else {
 			stashMessage := fmt.Sprintf("agent-checkpoint-%s", tx.ID)
 			for _, stash := range stashes {
@@ -1524,7 +1526,7 @@ func (m *Manager) rollbackStashWithTrace(ctx context.Context, tx *Transaction) e
 					m.tracer.EndGitOp(gitSpan, popErr)
 					if popErr != nil {
 						m.logger.Warn("failed to pop stash (may have conflicts)",
-							"error", popErr)
+							telemetry.SensitiveLogError("error", popErr))
 					}
 					break
 				}
@@ -1554,7 +1556,7 @@ func (m *Manager) rollbackBranchWithTrace(ctx context.Context, tx *Transaction)
 	recordGitOp(ctx, "clean_untracked", time.Since(start), cleanErr)
 	m.tracer.EndGitOp(gitSpan, cleanErr)
 	if cleanErr != nil {
-		m.logger.Warn("failed to clean untracked files", "error", cleanErr)
+		m.logger.Warn("failed to clean untracked files", telemetry.SensitiveLogError("error", cleanErr))
 	}
 
 	// Delete the work branch
@@ -1565,7 +1567,7 @@ func (m *Manager) rollbackBranchWithTrace(ctx context.Context, tx *Transaction)
 		recordGitOp(ctx, "delete_branch", time.Since(start), delErr)
 		m.tracer.EndGitOp(gitSpan, delErr)
 		if delErr != nil {
-			m.logger.Warn("failed to delete work branch", "error", delErr)
+			m.logger.Warn("failed to delete work branch", telemetry.SensitiveLogError("error", delErr))
 		}
 	}
 
@@ -1665,7 +1667,7 @@ func (m *Manager) commitWorktreeWithTrace(ctx context.Context, tx *Transaction,
 		recordGitOp(ctx, "worktree_remove", time.Since(start), removeErr)
 		m.tracer.EndGitOp(gitSpan, removeErr)
 		if removeErr != nil {
-			m.logger.Warn("failed to remove worktree", "error", removeErr)
+			m.logger.Warn("failed to remove worktree", telemetry.SensitiveLogError("error", removeErr))
 		}
 		return tx.CheckpointRef, nil
 	}
@@ -1717,7 +1719,7 @@ func (m *Manager) commitWorktreeWithTrace(ctx context.Context, tx *Transaction,
 	recordGitOp(ctx, "worktree_remove", time.Since(start), removeErr)
 	m.tracer.EndGitOp(gitSpan, removeErr)
 	if removeErr != nil {
-		m.logger.Warn("failed to remove worktree after commit", "error", removeErr)
+		m.logger.Warn("failed to remove worktree after commit", telemetry.SensitiveLogError("error", removeErr))
 	}
 
 	return commitSHA, nil
@@ -1739,7 +1741,7 @@ func (m *Manager) rollbackWorktreeWithTrace(ctx context.Context, tx *Transaction
 		// If git removal fails, try direct filesystem removal
 		m.logger.Warn("git worktree remove failed, trying filesystem removal",
 			"path", tx.WorktreePath,
-			"error", err)
+			telemetry.SensitiveLogError("error", err))
 		if err := os.RemoveAll(tx.WorktreePath); err != nil {
 			return fmt.Errorf("removing worktree: %w", err)
 		}
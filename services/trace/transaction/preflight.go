@@ -15,6 +15,8 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // PreFlightConfig configures pre-flight check behavior.
@@ -180,7 +182,7 @@ func NewPreFlightGuard(git GitClient, config PreFlightConfig, logger *slog.Logge
 	// Validate config and log warning if invalid (don't fail - use default behavior)
 	if err := ValidateConfig(config); err != nil {
 		logger.Warn("invalid preflight config, using defaults",
-			"error", err.Error())
+			telemetry.SensitiveLogError("error", err))
 		// If Force and AutoStash are both set, Force takes precedence
 		if config.Force && config.AutoStash {
 			config.AutoStash = false
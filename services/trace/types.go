@@ -11,11 +11,29 @@
 package trace
 
 import (
+	"encoding/json"
+
 	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/egress"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/usage"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/safety"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/annotations"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/assertions"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/chunk"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/cli/tools/project"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/config"
 	cbcontext "github.com/AleutianAI/AleutianFOSS/services/trace/context"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/coordinate"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/dependencies"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/facts"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
 	"github.com/AleutianAI/AleutianFOSS/services/trace/index"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/projectsummary"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/reason"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/semindex"
 )
 
 // IndexingStatusResponse is the response for GET /v1/trace/indexing/status.
@@ -53,14 +71,102 @@ type IndexingStatusResponse struct {
 // InitRequest is the request body for POST /v1/trace/init.
 type InitRequest struct {
 	// ProjectRoot is the absolute path to the project root directory.
-	// Required.
-	ProjectRoot string `json:"project_root" binding:"required"`
+	// Exactly one of ProjectRoot, Roots and WorkspaceFile is required.
+	ProjectRoot string `json:"project_root"`
+
+	// Roots are the absolute paths of a multi-root workspace built into
+	// one graph. File paths and symbol IDs are namespaced by each root's
+	// path relative to the roots' common parent (see Workspace).
+	Roots []string `json:"roots"`
 
-	// Languages is the list of languages to parse. Default: ["go"].
+	// WorkspaceFile is the absolute path to a workspace manifest (YAML or
+	// JSON with a "roots" list) used instead of Roots.
+	WorkspaceFile string `json:"workspace_file"`
+
+	// Languages is the list of languages to parse; files of other
+	// languages are skipped. Unknown names are rejected. Default: ["go"].
 	Languages []string `json:"languages"`
 
+	// ParserOptions sets parser options per language, e.g.
+	// {"go": {"extract_bodies": true, "max_file_size": 2097152}}. Each
+	// language must be in Languages, and each option one its parser
+	// accepts (see ast.DefaultParserFactories). The graph is held in
+	// memory only. Default: none (parser defaults).
+	ParserOptions map[string]map[string]any `json:"parser_options"`
+
 	// ExcludePatterns is a list of glob patterns to exclude. Default: ["vendor/*", "*_test.go"].
 	ExcludePatterns []string `json:"exclude_patterns"`
+
+	// IgnoreGlobs are extra gitignore-syntax patterns applied during file
+	// discovery, after .gitignore and .traceignore.
+	IgnoreGlobs []string `json:"ignore_globs"`
+
+	// DisableGitignore stops .gitignore files from being honored. Default: false.
+	DisableGitignore bool `json:"disable_gitignore"`
+
+	// SkipGenerated skips files with a generated-code header
+	// (e.g. "// Code generated ... DO NOT EDIT."). Default: false.
+	SkipGenerated bool `json:"skip_generated"`
+
+	// GOOS and GOARCH select the platform the Go graph is built for. Go
+	// files whose //go:build line or _GOOS/_GOARCH file suffix does not
+	// match are left out. Default: the server's platform.
+	GOOS   string `json:"goos"`
+	GOARCH string `json:"goarch"`
+
+	// BuildTags are extra Go build tags, as passed to go build -tags.
+	BuildTags []string `json:"build_tags"`
+
+	// AllBuildProfiles keeps Go files of every platform instead of one,
+	// linking same-named declarations of different platforms with
+	// variant_of edges. GOOS, GOARCH and BuildTags are ignored.
+	// Default: false.
+	AllBuildProfiles bool `json:"all_build_profiles"`
+
+	// GitRef builds the graph from a commit-ish of ProjectRoot's git
+	// repository, e.g. "origin/feature-x", reading objects instead of the
+	// working tree. The repository may be bare. Not allowed with Roots or
+	// WorkspaceFile. Default: "" (the working tree).
+	GitRef string `json:"git_ref"`
+
+	// ResolveDependencies parses the source of the Go packages the project
+	// imports, from vendor/ first and then the module cache (read-only),
+	// and merges their symbols into the graph with a dependency_depth.
+	// Calls into them then resolve to real functions instead of external
+	// placeholders. Not allowed with GitRef, Roots or WorkspaceFile.
+	// Default: false.
+	ResolveDependencies bool `json:"resolve_dependencies"`
+
+	// DependencyDepth is how many import levels resolve_dependencies
+	// follows. Beyond depth 1 only exported symbols are kept.
+	// Default: 1, max: 3.
+	DependencyDepth int `json:"dependency_depth"`
+
+	// DisabledPasses names graph builder passes whose edges are left out,
+	// e.g. ["call-method-fallback"], to bisect which pass produced a wrong
+	// edge. Edge provenance (see ?debug=true) names the pass of each edge.
+	// The graph is held in memory only. Default: none.
+	DisabledPasses []string `json:"disabled_passes"`
+
+	// PlaceholderPolicy selects how symbols outside the project are
+	// represented: "full" (a placeholder node per external symbol),
+	// "collapsed" (one per external package, listing the members used)
+	// or "omit" (none; edges to them are dropped). A graph built with a
+	// policy other than "full" is held in memory only. Default: "full".
+	PlaceholderPolicy string `json:"placeholder_policy"`
+
+	// FollowSymlinks selects which symlinked files and directories are
+	// followed: "inside_root" (only those whose target resolves inside
+	// the project root), "always" or "never". A file reached by several
+	// paths is parsed once, under its path without symlinks where there
+	// is one. Other than the default, the graph is held in memory only.
+	// Ignored with GitRef. Default: "inside_root".
+	FollowSymlinks string `json:"follow_symlinks"`
+
+	// Async returns a BuildStatusResponse immediately instead of waiting
+	// for the build. Poll GET /v1/trace/init/:build_id for progress.
+	// Default: false.
+	Async bool `json:"async"`
 }
 
 // InitResponse is the response for POST /v1/trace/init.
@@ -68,6 +174,10 @@ type InitResponse struct {
 	// GraphID is the unique identifier for this graph.
 	GraphID string `json:"graph_id"`
 
+	// BuildID identifies the build that produced (or is producing) the
+	// graph. Empty when Status is InitStatusCached.
+	BuildID string `json:"build_id,omitempty"`
+
 	// IsRefresh indicates if this replaced an existing graph.
 	IsRefresh bool `json:"is_refresh"`
 
@@ -86,10 +196,192 @@ type InitResponse struct {
 	// ParseTimeMs is the total parse time in milliseconds.
 	ParseTimeMs int64 `json:"parse_time_ms"`
 
+	// SkippedFiles is the number of paths skipped by exclude patterns and
+	// ignore rules. A skipped directory counts once.
+	SkippedFiles int `json:"skipped_files"`
+
+	// SkippedByRule breaks SkippedFiles down by matching rule,
+	// e.g. ".gitignore:node_modules/" or "ignore_globs:*.pb.go".
+	SkippedByRule map[string]int `json:"skipped_by_rule,omitempty"`
+
 	// Errors contains non-fatal errors encountered during parsing.
 	Errors []string `json:"errors,omitempty"`
+
+	// Status is InitStatusBuilt, InitStatusCached, or
+	// InitStatusAlreadyBuilding.
+	Status string `json:"status"`
+
+	// Progress describes the in-progress build when Status is
+	// InitStatusAlreadyBuilding. The other counts are zero in that case.
+	Progress *InitProgress `json:"progress,omitempty"`
+
+	// Joined is true if this caller waited on a build started by another
+	// caller and shares its result.
+	Joined bool `json:"joined,omitempty"`
+
+	// Roots summarizes each root of a workspace graph. Empty for
+	// single-root graphs.
+	Roots []WorkspaceRootStats `json:"roots,omitempty"`
+
+	// CommitSHA is the commit the graph was built from when the request
+	// set git_ref.
+	CommitSHA string `json:"commit_sha,omitempty"`
+
+	// DependencyPackages and DependencySymbols count the dependency
+	// packages parsed and the symbols merged from them when the request
+	// set resolve_dependencies.
+	DependencyPackages int `json:"dependency_packages,omitempty"`
+	DependencySymbols  int `json:"dependency_symbols,omitempty"`
+
+	// DependenciesTruncated is true if the dependency symbol cap left
+	// some dependency packages out of the graph.
+	DependenciesTruncated bool `json:"dependencies_truncated,omitempty"`
+
+	// ByLanguage is the number of files and symbols the build took from
+	// each language. Not set for cached graphs or incremental refreshes.
+	ByLanguage map[string]InitLanguageStats `json:"by_language,omitempty"`
+
+	// GoModules is the number of Go modules found in the project's go.mod
+	// and go.work files, replace directives included. CrossModuleEdges
+	// counts the import and call edges resolved between them.
+	GoModules        int `json:"go_modules,omitempty"`
+	CrossModuleEdges int `json:"cross_module_edges,omitempty"`
+}
+
+// InitLanguageStats counts the files and symbols of one language in a
+// graph build.
+type InitLanguageStats struct {
+	// Files is the number of files parsed into the graph.
+	Files int `json:"files"`
+
+	// Symbols is the number of symbols added, nested symbols included.
+	Symbols int `json:"symbols"`
+}
+
+// WorkspaceRootStats summarizes one root of a workspace graph.
+type WorkspaceRootStats struct {
+	// Name is the root's namespace (see WorkspaceRoot.Name).
+	Name string `json:"name"`
+
+	// Path is the absolute path of the root.
+	Path string `json:"path"`
+
+	// GoModule is the module path from the root's go.mod, if any.
+	GoModule string `json:"go_module,omitempty"`
+
+	// PackageName is the name from the root's package.json, if any.
+	PackageName string `json:"package_name,omitempty"`
+
+	// FilesParsed is the number of the root's files parsed by the build
+	// that produced the graph. Zero if an incremental refresh found the
+	// root unchanged.
+	FilesParsed int `json:"files_parsed"`
+
+	// Files is the number of the root's source files in the graph.
+	Files int `json:"files"`
+
+	// Nodes is the number of graph nodes from the root's files.
+	Nodes int `json:"nodes"`
+
+	// Edges is the number of graph edges located in the root's files.
+	Edges int `json:"edges"`
+
+	// MemoryBytes is the estimated heap held by the root's nodes and
+	// edges (see graph.Graph.MemoryStatsByRoot).
+	MemoryBytes int64 `json:"memory_bytes"`
+}
+
+// InitResponse.Status values.
+const (
+	// InitStatusBuilt means this call built (or refreshed) the graph.
+	InitStatusBuilt = "built"
+
+	// InitStatusCached means an existing graph was returned without a build.
+	InitStatusCached = "cached"
+
+	// InitStatusAlreadyBuilding means another caller's build is running;
+	// poll again or retry with wait=true.
+	InitStatusAlreadyBuilding = "already_building"
+)
+
+// InitProgress is the progress of an in-progress graph build.
+type InitProgress struct {
+	// Phase is "parsing" before the graph builder starts, then the
+	// builder's phase (e.g. "collecting", "extracting_edges", "finalizing").
+	Phase string `json:"phase"`
+
+	// FilesTotal is the number of parsed files handed to the builder.
+	FilesTotal int `json:"files_total"`
+
+	// FilesProcessed is the number of files the builder has processed.
+	FilesProcessed int `json:"files_processed"`
+
+	// NodesCreated is the number of graph nodes created so far.
+	NodesCreated int `json:"nodes_created"`
+
+	// EdgesCreated is the number of graph edges created so far.
+	EdgesCreated int `json:"edges_created"`
+
+	// StartedAtMilli is when the build started (Unix milliseconds UTC).
+	StartedAtMilli int64 `json:"started_at_milli"`
+
+	// ElapsedMs is the time since the build started.
+	ElapsedMs int64 `json:"elapsed_ms"`
+
+	// Waiters is the number of callers currently blocked on the build.
+	Waiters int `json:"waiters"`
+
+	// Errors are the non-fatal parse errors collected so far.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// BuildStatusResponse is the response for GET and DELETE
+// /v1/trace/init/:build_id, and for POST /v1/trace/init with async.
+type BuildStatusResponse struct {
+	// BuildID is the build's identifier.
+	BuildID string `json:"build_id"`
+
+	// GraphID is the graph the build will replace.
+	GraphID string `json:"graph_id"`
+
+	// ProjectRoot is the project being built.
+	ProjectRoot string `json:"project_root"`
+
+	// State is BuildStateRunning, BuildStateCompleted, BuildStateFailed,
+	// or BuildStateCancelled.
+	State string `json:"state"`
+
+	// Progress is the latest build progress. Kept after the build ends.
+	Progress *InitProgress `json:"progress"`
+
+	// Result is the final InitResponse when State is BuildStateCompleted.
+	Result *InitResponse `json:"result,omitempty"`
+
+	// Error is the failure reason when State is BuildStateFailed or
+	// BuildStateCancelled.
+	Error string `json:"error,omitempty"`
+
+	// FinishedAtMilli is when the build ended (Unix milliseconds UTC).
+	// Zero while running.
+	FinishedAtMilli int64 `json:"finished_at_milli,omitempty"`
 }
 
+// BuildStatusResponse.State values.
+const (
+	// BuildStateRunning means the build is parsing or building.
+	BuildStateRunning = "running"
+
+	// BuildStateCompleted means the new graph is installed.
+	BuildStateCompleted = "completed"
+
+	// BuildStateFailed means the build ended with an error.
+	BuildStateFailed = "failed"
+
+	// BuildStateCancelled means the build was cancelled; the previous
+	// graph, if any, is unchanged.
+	BuildStateCancelled = "cancelled"
+)
+
 // ContextRequest is the request body for POST /v1/trace/context.
 type ContextRequest struct {
 	// GraphID is the graph to query. Required.
@@ -131,8 +423,23 @@ type CallersRequest struct {
 	// Function is the function name to find callers for. Required.
 	Function string `form:"function" binding:"required"`
 
-	// Limit is the maximum number of results. Default: 50.
+	// Limit is the page size. Default: 50, at most 1000.
 	Limit int `form:"limit"`
+
+	// Cursor fetches the next page; pass the previous next_cursor.
+	// Optional.
+	Cursor string `form:"cursor"`
+
+	// Root restricts results to one root of a workspace graph, by name.
+	// Optional.
+	Root string `form:"root"`
+
+	// Scope restricts results to a named view of the graph (see
+	// POST /v1/trace/views). Optional.
+	Scope string `form:"scope"`
+
+	// Debug adds the provenance of the edges behind each result. Optional.
+	Debug bool `form:"debug"`
 }
 
 // CallersResponse is the response for GET /v1/trace/callers.
@@ -140,8 +447,10 @@ type CallersResponse struct {
 	// Function is the function name that was searched.
 	Function string `json:"function"`
 
-	// Callers is the list of symbols that call the function.
+	// Callers is the page of symbols that call the function.
 	Callers []*SymbolInfo `json:"callers"`
+
+	PageInfo
 }
 
 // ImplementationsRequest is the query params for GET /v1/trace/implementations.
@@ -152,8 +461,20 @@ type ImplementationsRequest struct {
 	// Interface is the interface name to find implementations for. Required.
 	Interface string `form:"interface" binding:"required"`
 
-	// Limit is the maximum number of results. Default: 50.
+	// Limit is the page size. Default: 50, at most 1000.
 	Limit int `form:"limit"`
+
+	// Cursor fetches the next page; pass the previous next_cursor.
+	// Optional.
+	Cursor string `form:"cursor"`
+
+	// Root restricts results to one root of a workspace graph, by name.
+	// Optional.
+	Root string `form:"root"`
+
+	// Scope restricts results to a named view of the graph (see
+	// POST /v1/trace/views). Optional.
+	Scope string `form:"scope"`
 }
 
 // ImplementationsResponse is the response for GET /v1/trace/implementations.
@@ -161,8 +482,31 @@ type ImplementationsResponse struct {
 	// Interface is the interface name that was searched.
 	Interface string `json:"interface"`
 
-	// Implementations is the list of types that implement the interface.
+	// Implementations is the page of types that implement the interface.
 	Implementations []*SymbolInfo `json:"implementations"`
+
+	PageInfo
+}
+
+// ImplExplainRequest is the query params for GET /v1/trace/impl/explain.
+type ImplExplainRequest struct {
+	// GraphID is the graph to query. Required.
+	GraphID string `form:"graph_id" binding:"required"`
+
+	// Type is the struct, class or type, by symbol ID or unique name.
+	// Required.
+	Type string `form:"type" binding:"required"`
+
+	// Iface is the interface, by symbol ID or unique name. Required.
+	Iface string `form:"iface" binding:"required"`
+}
+
+// ImplExplainResponse is the response for GET /v1/trace/impl/explain.
+type ImplExplainResponse struct {
+	*graph.ImplementsExplanation
+
+	// LatencyMs is the time taken to explain, in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
 }
 
 // CalleesRequest is the query params for GET /v1/trace/callees.
@@ -173,8 +517,23 @@ type CalleesRequest struct {
 	// Function is the function name to find callees for. Required.
 	Function string `form:"function" binding:"required"`
 
-	// Limit is the maximum number of results. Default: 50.
+	// Limit is the page size. Default: 50, at most 1000.
 	Limit int `form:"limit"`
+
+	// Cursor fetches the next page; pass the previous next_cursor.
+	// Optional.
+	Cursor string `form:"cursor"`
+
+	// Root restricts results to one root of a workspace graph, by name.
+	// Optional.
+	Root string `form:"root"`
+
+	// Scope restricts results to a named view of the graph (see
+	// POST /v1/trace/views). Optional.
+	Scope string `form:"scope"`
+
+	// Debug adds the provenance of the edges behind each result. Optional.
+	Debug bool `form:"debug"`
 }
 
 // CalleesResponse is the response for GET /v1/trace/callees.
@@ -182,8 +541,10 @@ type CalleesResponse struct {
 	// Function is the function name that was searched.
 	Function string `json:"function"`
 
-	// Callees is the list of symbols that the function calls.
+	// Callees is the page of symbols that the function calls.
 	Callees []*SymbolInfo `json:"callees"`
+
+	PageInfo
 }
 
 // CallChainRequest is the query params for GET /v1/trace/call-chain.
@@ -221,8 +582,23 @@ type ReferencesRequest struct {
 	// Symbol is the symbol name to find references for. Required.
 	Symbol string `form:"symbol" binding:"required"`
 
-	// Limit is the maximum number of results. Default: 50.
+	// Limit is the page size. Default: 50, at most 1000.
 	Limit int `form:"limit"`
+
+	// Cursor fetches the next page; pass the previous next_cursor.
+	// Optional.
+	Cursor string `form:"cursor"`
+
+	// Root restricts results to one root of a workspace graph, by name.
+	// Optional.
+	Root string `form:"root"`
+
+	// Scope restricts results to a named view of the graph (see
+	// POST /v1/trace/views). Optional.
+	Scope string `form:"scope"`
+
+	// Debug adds the provenance of the edges behind each result. Optional.
+	Debug bool `form:"debug"`
 }
 
 // ReferenceInfo represents a single reference location.
@@ -235,6 +611,14 @@ type ReferenceInfo struct {
 
 	// Column is the 0-indexed column number.
 	Column int `json:"column"`
+
+	// Provenance is the builder pass and confidence of the edge behind
+	// this reference. Set only with debug=true.
+	Provenance *graph.EdgeProvenance `json:"provenance,omitempty"`
+
+	// Location is the reference's line, with a link to it when a link
+	// template is configured.
+	Location *CodeLocation `json:"location,omitempty"`
 }
 
 // ReferencesResponse is the response for GET /v1/trace/references.
@@ -242,8 +626,254 @@ type ReferencesResponse struct {
 	// Symbol is the symbol name that was searched.
 	Symbol string `json:"symbol"`
 
-	// References is the list of reference locations.
+	// References is the page of reference locations.
 	References []ReferenceInfo `json:"references"`
+
+	PageInfo
+}
+
+// SemanticSearchRequest is the query params for GET /v1/trace/search/semantic.
+type SemanticSearchRequest struct {
+	// GraphID is the graph to search. Required.
+	GraphID string `form:"graph_id" binding:"required"`
+
+	// Query is the natural language query, e.g. "validate email
+	// addresses". Required.
+	Query string `form:"query" binding:"required"`
+
+	// Limit is the maximum number of results. Default: 10, at most 100.
+	Limit int `form:"limit"`
+
+	// MinScore is the minimum cosine similarity of a semantic result.
+	// Default: 0.
+	MinScore float64 `form:"min_score"`
+}
+
+// SemanticSearchResult is one ranked symbol of a semantic search.
+type SemanticSearchResult struct {
+	// Symbol is the matched function or method.
+	Symbol *SymbolInfo `json:"symbol"`
+
+	// Score is the cosine similarity, or in lexical mode the share of
+	// query words matched. At most 1.
+	Score float64 `json:"score"`
+}
+
+// SemanticSearchResponse is the response for GET /v1/trace/search/semantic.
+type SemanticSearchResponse struct {
+	// Query is the query that was searched.
+	Query string `json:"query"`
+
+	// Mode is "semantic", or "lexical" when the search fell back to
+	// keyword matching.
+	Mode string `json:"mode"`
+
+	// FallbackReason says why the search ran in lexical mode.
+	FallbackReason string `json:"fallback_reason,omitempty"`
+
+	// Results are the best matches, highest score first.
+	Results []SemanticSearchResult `json:"results"`
+
+	// Scanned is the number of symbols scored.
+	Scanned int `json:"scanned"`
+
+	// Capped is true when scoring stopped at the candidate cap.
+	Capped bool `json:"capped,omitempty"`
+
+	// Coverage is the graph's semantic index coverage. Absent when the
+	// index is disabled.
+	Coverage *semindex.GraphStatus `json:"coverage,omitempty"`
+}
+
+// CreateViewRequest is the request body for POST /v1/trace/views.
+type CreateViewRequest struct {
+	// Name identifies the view in scope parameters. Required. Replaces
+	// an existing view with the same name.
+	Name string `json:"name" binding:"required"`
+
+	// GraphID is the graph to restrict. Required.
+	GraphID string `json:"graph_id" binding:"required"`
+
+	// Include are file globs of the scope, relative to the project root;
+	// ** crosses directories. Empty includes every file not excluded.
+	Include []string `json:"include,omitempty"`
+
+	// Exclude are file globs removed from the scope.
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Boundary is how edges crossing the scope are shown: "placeholder"
+	// (default) keeps them with the outside node as a boundary node;
+	// "hide" drops them except implements/embeds edges from the scope.
+	Boundary string `json:"boundary,omitempty"`
+}
+
+// ListViewsResponse is the response for GET /v1/trace/views.
+type ListViewsResponse struct {
+	// Views are the views, sorted by name.
+	Views []GraphView `json:"views"`
+}
+
+// PutAnnotationsRequest is the request body for POST /v1/trace/annotations.
+type PutAnnotationsRequest struct {
+	// GraphID is the graph used to resolve symbol selectors. Required.
+	// The annotations apply to every graph of its project.
+	GraphID string `json:"graph_id" binding:"required"`
+
+	// Annotations are the entries to add or replace. Required, at most
+	// MaxAnnotationsPerRequest.
+	Annotations []annotations.Entry `json:"annotations" binding:"required"`
+}
+
+// MaxAnnotationsPerRequest bounds the entries of one PutAnnotationsRequest.
+const MaxAnnotationsPerRequest = 1000
+
+// SymbolAnnotation is an annotation with the graph symbol it is attached
+// to.
+type SymbolAnnotation struct {
+	annotations.Annotation
+
+	// Attached is true when the graph has a symbol with SymbolID. Stable
+	// IDs of removed or renamed symbols stay annotated but unattached.
+	Attached bool `json:"attached"`
+
+	// NodeID, Name and FilePath describe the attached symbol.
+	NodeID   string `json:"node_id,omitempty"`
+	Name     string `json:"name,omitempty"`
+	FilePath string `json:"file_path,omitempty"`
+}
+
+// AnnotationsResponse is the response for POST and GET
+// /v1/trace/annotations.
+type AnnotationsResponse struct {
+	// GraphID is the graph the annotations were resolved against.
+	GraphID string `json:"graph_id"`
+
+	// Annotations are the annotations written or listed.
+	Annotations []SymbolAnnotation `json:"annotations"`
+
+	// Count is len(Annotations).
+	Count int `json:"count"`
+}
+
+// DeleteAnnotationsResponse is the response for DELETE
+// /v1/trace/annotations.
+type DeleteAnnotationsResponse struct {
+	// Deleted is the number of API annotations deleted.
+	Deleted int `json:"deleted"`
+}
+
+// FactsResponse is the response for GET /v1/trace/facts.
+type FactsResponse struct {
+	// GraphID is the graph whose project was listed.
+	GraphID string `json:"graph_id"`
+
+	// ProjectRoot is the project the facts belong to.
+	ProjectRoot string `json:"project_root"`
+
+	// Facts are most recently updated first.
+	Facts []facts.Fact `json:"facts"`
+
+	// Count is len(Facts).
+	Count int `json:"count"`
+}
+
+// UpdateFactRequest is the request body for PATCH /v1/trace/facts/:id.
+type UpdateFactRequest struct {
+	// GraphID is the graph used to resolve SymbolIDs and check anchors.
+	// Required.
+	GraphID string `json:"graph_id" binding:"required"`
+
+	// Statement replaces the fact's statement. Optional.
+	Statement *string `json:"statement,omitempty"`
+
+	// SymbolIDs replace the fact's anchors, in any ID form the graph
+	// resolves. Optional.
+	SymbolIDs []string `json:"symbol_ids,omitempty"`
+}
+
+// FactResponse is the response for PATCH /v1/trace/facts/:id.
+type FactResponse struct {
+	// Fact is the edited fact.
+	Fact facts.Fact `json:"fact"`
+}
+
+// ChunksRequest is the request body for POST /v1/trace/chunks.
+type ChunksRequest struct {
+	// ProjectRoot is the project whose working-tree graph is chunked. The
+	// graph must be initialized. Required.
+	ProjectRoot string `json:"project_root" binding:"required"`
+
+	// MaxTokens is the estimated token budget of a chunk. Default: 512,
+	// at most 32768.
+	MaxTokens int `json:"max_tokens,omitempty"`
+
+	// OverlapTokens is how much of the end of a split symbol's part is
+	// repeated at the start of the next part, in whole lines. At most
+	// half of MaxTokens. Default: 0.
+	OverlapTokens int `json:"overlap_tokens,omitempty"`
+
+	// Include are file globs to chunk, relative to the project root; **
+	// crosses directories. Empty chunks every file not excluded.
+	Include []string `json:"include,omitempty"`
+
+	// Exclude are file globs not to chunk.
+	Exclude []string `json:"exclude,omitempty"`
+
+	// Limit is the maximum number of chunks returned. Default: 200, at
+	// most 2000.
+	Limit int `json:"limit,omitempty"`
+
+	// Cursor resumes after the previous page; pass its next_cursor.
+	Cursor string `json:"cursor,omitempty"`
+}
+
+// ChunksResponse is the response for POST /v1/trace/chunks.
+type ChunksResponse struct {
+	// GraphID is the graph whose symbols aligned the chunks.
+	GraphID string `json:"graph_id"`
+
+	// Chunks are the page's chunks, by file path and then line.
+	Chunks []chunk.Chunk `json:"chunks"`
+
+	// Count is the number of chunks in the page.
+	Count int `json:"count"`
+
+	// NextCursor fetches the next page. Empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+
+	// Errors lists files that could not be read, as "path: error".
+	Errors []string `json:"errors,omitempty"`
+
+	// LatencyMs is the request duration in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// AssertionsCheckRequest is the request body for POST /v1/trace/assertions/check.
+type AssertionsCheckRequest struct {
+	// GraphID is the graph to check. Required.
+	GraphID string `json:"graph_id" binding:"required"`
+
+	// Rules are inline assertion rules. Mutually exclusive with RulesPath.
+	Rules []assertions.Rule `json:"rules,omitempty"`
+
+	// RulesPath is a YAML rules file relative to the project root.
+	// Default: .trace-assertions.yaml when Rules is empty.
+	RulesPath string `json:"rules_path,omitempty"`
+}
+
+// AssertionsCheckResponse is the response for POST /v1/trace/assertions/check.
+// Failing rules are reported with 200 OK and passed=false.
+type AssertionsCheckResponse struct {
+	// GraphID is the checked graph.
+	GraphID string `json:"graph_id"`
+
+	// RulesPath is the rules file used. Empty for inline rules.
+	RulesPath string `json:"rules_path,omitempty"`
+
+	assertions.Report
+
+	// LatencyMs is the request duration in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
 }
 
 // FindHotspotsRequest is the request body for POST /v1/trace/analytics/hotspots.
@@ -288,6 +918,18 @@ type FindPathRequest struct {
 	To string `json:"to" binding:"required"`
 }
 
+// GraphQueryRequest is the request body for POST /v1/trace/graph/query.
+type GraphQueryRequest struct {
+	// GraphID is the graph to query. Required.
+	GraphID string `json:"graph_id" binding:"required"`
+
+	// Query is the structured query. See graph.StructuredQuery.
+	Query graph.StructuredQuery `json:"query"`
+
+	// Explain returns an estimate of the traversal instead of running it.
+	Explain bool `json:"explain"`
+}
+
 // SymbolRequest is the query params for GET /v1/trace/symbol/:id.
 type SymbolRequest struct {
 	// GraphID is the graph to query. Required.
@@ -298,6 +940,14 @@ type SymbolRequest struct {
 type SymbolResponse struct {
 	// Symbol is the detailed symbol information.
 	Symbol *SymbolInfo `json:"symbol"`
+
+	// Owners are the CODEOWNERS owners of the symbol's file. Empty when the
+	// file has no owners or the project has no CODEOWNERS file.
+	Owners []string `json:"owners"`
+
+	// Annotations are the symbol's annotations, key → value. Empty when
+	// it has none.
+	Annotations map[string]string `json:"annotations"`
 }
 
 // SymbolInfo is a simplified symbol representation for API responses.
@@ -305,6 +955,11 @@ type SymbolInfo struct {
 	// ID is the unique symbol identifier.
 	ID string `json:"id"`
 
+	// StableID is the line-independent identifier. Endpoints taking a
+	// symbol ID accept either form; clients that persist IDs should
+	// store this one.
+	StableID string `json:"stable_id,omitempty"`
+
 	// Name is the symbol name.
 	Name string `json:"name"`
 
@@ -331,6 +986,63 @@ type SymbolInfo struct {
 
 	// Exported indicates if the symbol is publicly visible.
 	Exported bool `json:"exported"`
+
+	// Generated indicates the symbol is in generated code.
+	Generated bool `json:"generated,omitempty"`
+
+	// DependencyDepth is non-zero for a symbol of dependency source merged
+	// by resolve_dependencies: how many imports away from the project its
+	// package is.
+	DependencyDepth int `json:"dependency_depth,omitempty"`
+
+	// Edges are the graph edges linking this symbol to the queried one,
+	// with their provenance. Set only by endpoints called with debug=true.
+	Edges []EdgeDebugInfo `json:"edges,omitempty"`
+
+	// Location is where the symbol is, with a link to it when a link
+	// template is configured.
+	Location *CodeLocation `json:"location,omitempty"`
+}
+
+// CodeLocation is a range of lines in a project file, as reported by
+// every endpoint that points at code.
+type CodeLocation struct {
+	// Path is the file path, relative to the project root.
+	Path string `json:"path"`
+
+	// StartLine is the 1-indexed first line, or 0 if unknown.
+	StartLine int `json:"start_line,omitempty"`
+
+	// EndLine is the 1-indexed last line, or 0 if unknown.
+	EndLine int `json:"end_line,omitempty"`
+
+	// URI links to the location, rendered from the link template (see
+	// LinkTemplate). Empty when no template is configured or the
+	// template needs a commit SHA the graph does not have.
+	URI string `json:"uri,omitempty"`
+
+	// SymbolID is the symbol at the location, when the location was
+	// derived from one.
+	SymbolID string `json:"symbol_id,omitempty"`
+}
+
+// EdgeDebugInfo describes a graph edge and how the builder created it.
+type EdgeDebugInfo struct {
+	// FromID is the ID of the source node.
+	FromID string `json:"from_id"`
+
+	// ToID is the ID of the target node.
+	ToID string `json:"to_id"`
+
+	// Type is the relationship type (e.g., "calls").
+	Type string `json:"type"`
+
+	// Location is where the relationship is expressed in code.
+	Location ast.Location `json:"location"`
+
+	// Provenance is the builder pass that created the edge and its
+	// confidence.
+	Provenance graph.EdgeProvenance `json:"provenance"`
 }
 
 // SeedRequest is the request body for POST /v1/trace/seed.
@@ -354,9 +1066,9 @@ type SeedResponse struct {
 	Errors []string `json:"errors,omitempty"`
 }
 
-// HealthResponse is the response for GET /v1/trace/health.
+// HealthResponse is the response for GET /v1/trace/health/live.
 type HealthResponse struct {
-	// Status is "healthy" or "degraded".
+	// Status is "healthy" from the liveness endpoint.
 	Status string `json:"status"`
 
 	// Version is the service version.
@@ -399,11 +1111,43 @@ type LSPLanguageStatus struct {
 	Available bool `json:"available"`
 }
 
-// ReadyResponse is the response for GET /v1/trace/ready.
+// ReadyResponse is the response for GET /v1/trace/health/ready.
+//
+// Description:
+//
+//	Returned by /v1/trace/health/ready and its aliases /ready and /health.
+//	Ready requires every warmup role to be complete and every required
+//	subsystem to pass its (cached) check. Optional subsystems that fail
+//	leave the service ready but "degraded".
 type ReadyResponse struct {
 	// Ready is true if the service is ready to accept requests.
 	Ready bool `json:"ready"`
 
+	// Status is "healthy", "degraded" (ready, but an optional subsystem
+	// is failing) or "unhealthy" (not ready).
+	Status string `json:"status"`
+
+	// Version is the service version.
+	Version string `json:"version"`
+
+	// Warmup is the warmup state of each model role.
+	Warmup []WarmupRoleStatus `json:"warmup"`
+
+	// Subsystems is the state of each subsystem: main_provider,
+	// router_provider, routing_cache, policy_engine, graph_store.
+	Subsystems []SubsystemHealth `json:"subsystems"`
+
+	// LSP contains LSP enrichment status. Present only when LSP is enabled.
+	LSP *LSPHealthStatus `json:"lsp,omitempty"`
+
+	// LLMCircuits is the circuit breaker state ("closed", "open",
+	// "half-open") of each Ollama model called so far.
+	LLMCircuits map[string]string `json:"llm_circuits,omitempty"`
+
+	// RoutingConfigGeneration is the pre-filter rules and tool registry
+	// generation: 0 for the startup config, incremented by each reload.
+	RoutingConfigGeneration uint64 `json:"routing_config_generation"`
+
 	// GraphCount is the number of cached graphs.
 	GraphCount int `json:"graph_count"`
 
@@ -415,7 +1159,11 @@ type ReadyResponse struct {
 	NATSOK bool `json:"nats_ok"`
 }
 
-// ErrorResponse is the standard error response format.
+// ErrorResponse is the pre-problem+json error format.
+//
+// Deprecated: Handlers write apierror.Problem, which still carries the
+// "error", "code" and "details" members for one release, so this type
+// keeps decoding error responses until then.
 type ErrorResponse struct {
 	// Error is the error message.
 	Error string `json:"error"`
@@ -455,6 +1203,78 @@ type CachedGraph struct {
 	// that produced this graph. Zero-valued if enrichment was not configured.
 	// GR-76: Used by CRS to emit a TraceStep on session start.
 	EnrichmentStats graph.EnrichmentStats
+
+	// ContentHash is graph.Graph.ContentHash() of Graph. Identical source
+	// yields the same hash regardless of build worker count or timing.
+	ContentHash string
+
+	// Workspace is set for multi-root graphs. ProjectRoot is then the
+	// workspace base.
+	Workspace *Workspace
+
+	// RootStats summarizes each workspace root. Nil for single-root graphs.
+	RootStats []WorkspaceRootStats
+
+	// OwnersHash identifies the CODEOWNERS content whose owners are recorded
+	// on Graph. Empty when the project has no CODEOWNERS file.
+	OwnersHash string
+
+	// Dependencies are the third-party packages declared by the project's
+	// manifests and lockfiles. Empty when the project has none.
+	Dependencies *dependencies.Set
+
+	// ProjectTools are the declarative tools of the project's
+	// .trace/tools.yaml. Empty when the project has none.
+	ProjectTools []project.Spec
+
+	// Annotations are the annotations of the project, recorded on Graph.
+	// Shared by every graph of the project and updated in place by the
+	// annotations API.
+	Annotations *annotations.Set
+
+	// GitRef is the ref the graph was built from (DiscoveryOptions.GitRef).
+	// Empty for graphs of the working tree.
+	GitRef string
+
+	// CommitSHA is the commit GitRef resolved to when the graph was built.
+	CommitSHA string
+
+	// HeadSHA is the commit checked out in the project root when a graph
+	// of the working tree was built, if the project is in a git
+	// repository. Links to code use CommitSHA, else HeadSHA.
+	HeadSHA string
+
+	// RepoSubdir is the project root's slash-separated path within its
+	// git repository, or "" at the top level or outside a repository.
+	RepoSubdir string
+
+	// FromParseResults is true for graphs built from parse results
+	// uploaded to POST /v1/trace/init/from-parse-results rather than from
+	// files on disk.
+	FromParseResults bool
+
+	// IncrementalUpdates counts incremental refreshes applied since the
+	// graph was last fully built or compacted. See
+	// ServiceConfig.CompactAfterUpdates.
+	IncrementalUpdates int
+}
+
+// ResolveSymbolID maps a symbol ID in any accepted form (node ID, stable
+// ID, or a line-based ID from an earlier build of the same source) to
+// the node ID used by Graph and Index.
+//
+// Outputs:
+//
+//	string - The node ID, or id unchanged if it does not resolve, so
+//	  callers report "not found" against what the client sent.
+func (c *CachedGraph) ResolveSymbolID(id string) string {
+	if c.Graph == nil {
+		return id
+	}
+	if nodeID, ok := c.Graph.ResolveNodeID(id); ok {
+		return nodeID
+	}
+	return id
 }
 
 // SymbolInfoFromAST converts an ast.Symbol to SymbolInfo.
@@ -463,16 +1283,19 @@ func SymbolInfoFromAST(s *ast.Symbol) *SymbolInfo {
 		return nil
 	}
 	return &SymbolInfo{
-		ID:         s.ID,
-		Name:       s.Name,
-		Kind:       s.Kind.String(),
-		FilePath:   s.FilePath,
-		StartLine:  s.StartLine,
-		EndLine:    s.EndLine,
-		Signature:  s.Signature,
-		DocComment: s.DocComment,
-		Package:    s.Package,
-		Exported:   s.Exported,
+		ID:              s.ID,
+		StableID:        s.StableID,
+		Name:            s.Name,
+		Kind:            s.Kind.String(),
+		FilePath:        s.FilePath,
+		StartLine:       s.StartLine,
+		EndLine:         s.EndLine,
+		Signature:       s.Signature,
+		DocComment:      s.DocComment,
+		Package:         s.Package,
+		Exported:        s.Exported,
+		Generated:       s.Generated,
+		DependencyDepth: s.DependencyDepth,
 	}
 }
 
@@ -491,6 +1314,38 @@ type AgentRunRequest struct {
 
 	// Config is optional session configuration overrides.
 	Config *agent.SessionConfig `json:"config,omitempty"`
+
+	// Record saves the run's LLM calls and tool executions to a replay
+	// fixture. Rejected unless the server allows recording.
+	Record bool `json:"record,omitempty"`
+
+	// Scope restricts the session's graph tools to a named view of the
+	// project's graph (see POST /v1/trace/views). Optional.
+	Scope string `json:"scope,omitempty"`
+
+	// Priority is the run's queue priority: "interactive" or "ci".
+	// Interactive runs start before waiting ci runs. Must be one the
+	// request's API key may use. Default: the key's highest allowed.
+	Priority string `json:"priority,omitempty"`
+
+	// QueueDuringWarmup holds the request until the models it needs finish
+	// warming up, instead of answering 503 WARMING_UP. The wait and the
+	// number of held requests are bounded.
+	QueueDuringWarmup bool `json:"queue_during_warmup,omitempty"`
+
+	// Mode is "quick" to answer a simple lookup with one or two graph
+	// tools and a single LLM call, or "full" for the agent loop. Empty
+	// picks quick for simple lookups ("who calls X", "where is X defined")
+	// when the server enables quick answers. A quick answer that cannot
+	// be given falls back to the full loop.
+	Mode string `json:"mode,omitempty"`
+
+	// Diff is a unified diff (e.g. git diff output) for "review this
+	// change" queries. The symbols whose lines it changes, and their
+	// direct callers and callees, lead the initial context; symbols in
+	// files the graph lacks are parsed from the file or the diff.
+	// Optional; implies the full loop.
+	Diff string `json:"diff,omitempty"`
 }
 
 // AgentRunResponse is the response for POST /v1/trace/agent/run.
@@ -507,26 +1362,100 @@ type AgentRunResponse struct {
 	// TokensUsed is the total tokens consumed.
 	TokensUsed int `json:"tokens_used"`
 
-	// Response is the agent's final response (if complete).
-	Response string `json:"response,omitempty"`
+	// Response is the agent's final response (if complete).
+	Response string `json:"response,omitempty"`
+
+	// Citations are the verified citation markers in Response: the
+	// symbol ID, file:line, and the tool call that returned the symbol.
+	Citations []agent.Citation `json:"citations,omitempty"`
+
+	// UnverifiedCitations are citation markers in Response that match no
+	// symbol observed via tools. Removed from Response when the session
+	// config sets strip_unverified_citations.
+	UnverifiedCitations []agent.UnverifiedCitation `json:"unverified_citations,omitempty"`
+
+	// NeedsClarify contains clarification details if state is CLARIFY.
+	NeedsClarify *agent.ClarifyRequest `json:"needs_clarify,omitempty"`
+
+	// Error is the error message if state is ERROR.
+	Error string `json:"error,omitempty"`
+
+	// Abort describes the abort if state is ABORTED, including whether
+	// /agent/continue can resume the session.
+	Abort *agent.AbortRecord `json:"abort,omitempty"`
+
+	// DegradedMode indicates if the session is running with limited capabilities.
+	DegradedMode bool `json:"degraded_mode"`
+
+	// TranscriptURL links to the persisted session transcript.
+	// Empty when session persistence is not configured.
+	TranscriptURL string `json:"transcript_url,omitempty"`
+
+	// ContextAssembly reports which symbols were placed in the initial
+	// context, their token cost, and which were dropped for budget.
+	// Nil when context assembly is disabled.
+	ContextAssembly *agent.ContextAssembly `json:"context_assembly,omitempty"`
+
+	// Status is "awaiting_approval" when the session has change plans
+	// pending approval. The session cannot continue until each is decided.
+	Status string `json:"status,omitempty"`
+
+	// PendingPlans lists the IDs of the session's plans awaiting approval.
+	PendingPlans []string `json:"pending_plans,omitempty"`
+
+	// FixturePath is where the run's replay fixture was saved. Set only
+	// for recorded runs.
+	FixturePath string `json:"fixture_path,omitempty"`
+
+	// Usage is the token usage and cost of the LLM calls made by this
+	// run (not the whole session). Nil when usage accounting is disabled.
+	Usage *usage.Totals `json:"usage,omitempty"`
+
+	// InstanceHint tells load balancers how to keep the project on this
+	// instance. Nil unless sticky hints are enabled (TRACE_STICKY_HINTS).
+	InstanceHint *InstanceHint `json:"instance_hint,omitempty"`
 
-	// NeedsClarify contains clarification details if state is CLARIFY.
-	NeedsClarify *agent.ClarifyRequest `json:"needs_clarify,omitempty"`
+	// Mode is the mode that served the run: "quick" or "full". Quick
+	// answers are not kept as sessions and cannot be continued.
+	Mode string `json:"mode"`
 
-	// Error is the error message if state is ERROR.
-	Error string `json:"error,omitempty"`
+	// QuickFallback is why a quick answer was not given when one was
+	// attempted, e.g. "ambiguous_symbol" or "timeout". Mode is then "full".
+	QuickFallback string `json:"quick_fallback,omitempty"`
 
-	// DegradedMode indicates if the session is running with limited capabilities.
-	DegradedMode bool `json:"degraded_mode"`
+	// DiffSymbols lists the symbols derived from the request's diff:
+	// changed symbols, then their direct callers and callees, with
+	// whether each fit in the initial context. Empty without a diff.
+	DiffSymbols []agent.DiffSymbol `json:"diff_symbols,omitempty"`
 }
 
+// AgentStatusAwaitingApproval is the AgentRunResponse status of a session
+// that has change plans pending approval.
+const AgentStatusAwaitingApproval = "awaiting_approval"
+
 // AgentContinueRequest is the request body for POST /v1/trace/agent/continue.
 type AgentContinueRequest struct {
 	// SessionID is the session to continue. Required.
 	SessionID string `json:"session_id" binding:"required"`
 
-	// Clarification is the user's response to the clarification request. Required.
-	Clarification string `json:"clarification" binding:"required"`
+	// Clarification is the user's response to the clarification request.
+	// Required unless PlanID or OptionID is set.
+	Clarification string `json:"clarification"`
+
+	// OptionID selects one of the options of the clarification request
+	// (needs_clarify.options) instead of a typed Clarification.
+	OptionID string `json:"option_id,omitempty"`
+
+	// PlanID resumes the session with an approved change plan. The plan
+	// must belong to the session and be approved.
+	PlanID string `json:"plan_id,omitempty"`
+
+	// Priority is the continuation's queue priority, as in
+	// AgentRunRequest.Priority.
+	Priority string `json:"priority,omitempty"`
+
+	// QueueDuringWarmup is as in AgentRunRequest.QueueDuringWarmup.
+	QueueDuringWarmup bool `json:"queue_during_warmup,omitempty"`
 }
 
 // AgentAbortRequest is the request body for POST /v1/trace/agent/abort.
@@ -535,6 +1464,58 @@ type AgentAbortRequest struct {
 	SessionID string `json:"session_id" binding:"required"`
 }
 
+// AgentAbortResponse is the response for POST /v1/trace/agent/abort.
+type AgentAbortResponse struct {
+	// Message describes what was done.
+	Message string `json:"message"`
+
+	// SessionID is the aborted session.
+	SessionID string `json:"session_id"`
+
+	// State is the session's state after the abort. ABORTED, or the
+	// terminal state a finished session was left in. Empty for a queued
+	// run.
+	State string `json:"state,omitempty"`
+
+	// Abort describes the abort. Nil for a queued run and for sessions
+	// that had already finished.
+	Abort *agent.AbortRecord `json:"abort,omitempty"`
+}
+
+// ListSessionsResponse is the response for GET /v1/trace/agent/sessions.
+type ListSessionsResponse struct {
+	// Sessions is the requested page of session summaries, newest first.
+	Sessions []*agent.SessionTranscriptSummary `json:"sessions"`
+
+	// Total is the number of sessions matching the filter.
+	Total int `json:"total"`
+
+	// Limit is the page size applied.
+	Limit int `json:"limit"`
+
+	// Offset is the number of sessions skipped.
+	Offset int `json:"offset"`
+}
+
+// ResetRoutingFeedbackResponse is the response for DELETE /v1/trace/agent/debug/routing/feedback.
+type ResetRoutingFeedbackResponse struct {
+	// Removed is the number of feedback records deleted.
+	Removed int `json:"removed"`
+}
+
+// RoutingExplainRequest is the request for POST /v1/trace/routing/explain.
+type RoutingExplainRequest struct {
+	// Query is the query to route.
+	Query string `json:"query" binding:"required"`
+
+	// SkipRouter explains the pre-filter only, without calling the router
+	// model.
+	SkipRouter bool `json:"skip_router,omitempty"`
+
+	// QueueDuringWarmup is as in AgentRunRequest.QueueDuringWarmup.
+	QueueDuringWarmup bool `json:"queue_during_warmup,omitempty"`
+}
+
 // AgentStateResponse is the response for GET /v1/trace/agent/:id.
 type AgentStateResponse struct {
 	// SessionID is the unique session identifier.
@@ -762,72 +1743,119 @@ type ToolsResponse struct {
 	Tools []ToolDefinition `json:"tools"`
 }
 
+// OpenAIToolsResponse is the response for GET /v1/trace/tools?format=openai.
+type OpenAIToolsResponse struct {
+	Tools []tools.OpenAITool `json:"tools"`
+}
+
+// AnthropicToolsResponse is the response for GET /v1/trace/tools?format=anthropic.
+type AnthropicToolsResponse struct {
+	Tools []tools.AnthropicTool `json:"tools"`
+}
+
+// ToolExecuteRequest is the request for POST /v1/trace/tools/:name/execute.
+type ToolExecuteRequest struct {
+	// GraphID selects the project whose tool runs.
+	GraphID string `json:"graph_id" binding:"required"`
+
+	// Params are the tool's parameters, as in its input_schema.
+	Params map[string]any `json:"params"`
+}
+
+// ToolExecuteResponse is the response for POST /v1/trace/tools/:name/execute.
+type ToolExecuteResponse struct {
+	// Tool is the tool name.
+	Tool string `json:"tool"`
+
+	// Result is the tool output, as in its output_schema.
+	Result any `json:"result"`
+
+	// OutputText is the output as shown to the agent.
+	OutputText string `json:"output_text"`
+
+	// Truncated is true when the result was cut at the tool's limit or
+	// the output size cap.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// LatencyMs is the execution time in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
 // --- Exploration Tool Types ---
 
 // FindEntryPointsRequest is the request for POST /v1/trace/explore/entry_points.
 type FindEntryPointsRequest struct {
-	GraphID      string `json:"graph_id" binding:"required"`
-	Type         string `json:"type"`
-	Package      string `json:"package"`
-	Limit        int    `json:"limit"`
-	IncludeTests bool   `json:"include_tests"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	Type             string `json:"type"`
+	Package          string `json:"package"`
+	Limit            int    `json:"limit"`
+	IncludeTests     bool   `json:"include_tests"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // TraceDataFlowRequest is the request for POST /v1/trace/explore/data_flow.
 type TraceDataFlowRequest struct {
-	GraphID     string `json:"graph_id" binding:"required"`
-	SourceID    string `json:"source_id" binding:"required"`
-	MaxHops     int    `json:"max_hops"`
-	IncludeCode bool   `json:"include_code"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	SourceID         string `json:"source_id" binding:"required"`
+	MaxHops          int    `json:"max_hops"`
+	IncludeCode      bool   `json:"include_code"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // TraceErrorFlowRequest is the request for POST /v1/trace/explore/error_flow.
 type TraceErrorFlowRequest struct {
-	GraphID string `json:"graph_id" binding:"required"`
-	Scope   string `json:"scope" binding:"required"`
-	MaxHops int    `json:"max_hops"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	Scope            string `json:"scope" binding:"required"`
+	MaxHops          int    `json:"max_hops"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // FindConfigUsageRequest is the request for POST /v1/trace/explore/config_usage.
 type FindConfigUsageRequest struct {
-	GraphID         string `json:"graph_id" binding:"required"`
-	ConfigKey       string `json:"config_key" binding:"required"`
-	IncludeDefaults bool   `json:"include_defaults"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	ConfigKey        string `json:"config_key" binding:"required"`
+	IncludeDefaults  bool   `json:"include_defaults"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // FindSimilarCodeRequest is the request for POST /v1/trace/explore/similar_code.
 type FindSimilarCodeRequest struct {
-	GraphID       string  `json:"graph_id" binding:"required"`
-	SymbolID      string  `json:"symbol_id" binding:"required"`
-	MinSimilarity float64 `json:"min_similarity"`
-	Limit         int     `json:"limit"`
+	GraphID          string  `json:"graph_id" binding:"required"`
+	SymbolID         string  `json:"symbol_id" binding:"required"`
+	MinSimilarity    float64 `json:"min_similarity"`
+	Limit            int     `json:"limit"`
+	IncludeGenerated bool    `json:"include_generated"`
 }
 
 // BuildMinimalContextRequest is the request for POST /v1/trace/explore/minimal_context.
 type BuildMinimalContextRequest struct {
-	GraphID        string `json:"graph_id" binding:"required"`
-	SymbolID       string `json:"symbol_id" binding:"required"`
-	TokenBudget    int    `json:"token_budget"`
-	IncludeCallees bool   `json:"include_callees"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	SymbolID         string `json:"symbol_id" binding:"required"`
+	TokenBudget      int    `json:"token_budget"`
+	IncludeCallees   bool   `json:"include_callees"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // SummarizeFileRequest is the request for POST /v1/trace/explore/summarize_file.
 type SummarizeFileRequest struct {
-	GraphID  string `json:"graph_id" binding:"required"`
-	FilePath string `json:"file_path" binding:"required"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	FilePath         string `json:"file_path" binding:"required"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // SummarizePackageRequest is the request for POST /v1/trace/explore/summarize_package.
 type SummarizePackageRequest struct {
-	GraphID string `json:"graph_id" binding:"required"`
-	Package string `json:"package" binding:"required"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	Package          string `json:"package" binding:"required"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // AnalyzeChangeImpactRequest is the request for POST /v1/trace/explore/change_impact.
 type AnalyzeChangeImpactRequest struct {
-	GraphID    string `json:"graph_id" binding:"required"`
-	SymbolID   string `json:"symbol_id" binding:"required"`
-	ChangeType string `json:"change_type"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	SymbolID         string `json:"symbol_id" binding:"required"`
+	ChangeType       string `json:"change_type"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // --- Reasoning Tool Types ---
@@ -837,14 +1865,16 @@ type CheckBreakingChangesRequest struct {
 	GraphID           string `json:"graph_id" binding:"required"`
 	SymbolID          string `json:"symbol_id" binding:"required"`
 	ProposedSignature string `json:"proposed_signature" binding:"required"`
+	IncludeGenerated  bool   `json:"include_generated"`
 }
 
 // SimulateChangeRequest is the request for POST /v1/trace/reason/simulate_change.
 type SimulateChangeRequest struct {
-	GraphID       string                 `json:"graph_id" binding:"required"`
-	SymbolID      string                 `json:"symbol_id" binding:"required"`
-	ChangeType    string                 `json:"change_type" binding:"required"`
-	ChangeDetails map[string]interface{} `json:"change_details" binding:"required"`
+	GraphID          string                 `json:"graph_id" binding:"required"`
+	SymbolID         string                 `json:"symbol_id" binding:"required"`
+	ChangeType       string                 `json:"change_type" binding:"required"`
+	ChangeDetails    map[string]interface{} `json:"change_details" binding:"required"`
+	IncludeGenerated bool                   `json:"include_generated"`
 }
 
 // ValidateChangeRequest is the request for POST /v1/trace/reason/validate_change.
@@ -855,22 +1885,61 @@ type ValidateChangeRequest struct {
 
 // FindTestCoverageRequest is the request for POST /v1/trace/reason/test_coverage.
 type FindTestCoverageRequest struct {
-	GraphID         string `json:"graph_id" binding:"required"`
-	SymbolID        string `json:"symbol_id" binding:"required"`
-	IncludeIndirect bool   `json:"include_indirect"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	SymbolID         string `json:"symbol_id" binding:"required"`
+	IncludeIndirect  bool   `json:"include_indirect"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // DetectSideEffectsRequest is the request for POST /v1/trace/reason/side_effects.
 type DetectSideEffectsRequest struct {
-	GraphID    string `json:"graph_id" binding:"required"`
-	SymbolID   string `json:"symbol_id" binding:"required"`
-	Transitive bool   `json:"transitive"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	SymbolID         string `json:"symbol_id" binding:"required"`
+	Transitive       bool   `json:"transitive"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // SuggestRefactorRequest is the request for POST /v1/trace/reason/suggest_refactor.
 type SuggestRefactorRequest struct {
-	GraphID  string `json:"graph_id" binding:"required"`
-	SymbolID string `json:"symbol_id" binding:"required"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	SymbolID         string `json:"symbol_id" binding:"required"`
+	IncludeGenerated bool   `json:"include_generated"`
+}
+
+// AnalyzeImpactRequest is the request for POST /v1/trace/reason/impact.
+type AnalyzeImpactRequest struct {
+	GraphID          string   `json:"graph_id" binding:"required"`
+	Files            []string `json:"files" binding:"required,min=1"`
+	MaxDepth         int      `json:"max_depth"`
+	IncludeGenerated bool     `json:"include_generated"`
+}
+
+// AnalyzeDependencySurfaceRequest is the request for POST /v1/trace/reason/dependency_surface.
+type AnalyzeDependencySurfaceRequest struct {
+	GraphID          string `json:"graph_id" binding:"required"`
+	Package          string `json:"package" binding:"required"`
+	MaxDepth         int    `json:"max_depth"`
+	IncludeGenerated bool   `json:"include_generated"`
+}
+
+// DependencyAdvisoriesRequest is the request for POST /v1/trace/deps/advisories.
+type DependencyAdvisoriesRequest struct {
+	GraphID string `json:"graph_id" binding:"required"`
+
+	// Advisories is OSV JSON: one advisory, an array, or {"vulns": [...]}.
+	Advisories json.RawMessage `json:"advisories" binding:"required"`
+}
+
+// DependencyAdvisoriesResult is the result of POST /v1/trace/deps/advisories.
+type DependencyAdvisoriesResult struct {
+	// Dependencies is the number of dependencies the graph's project declares.
+	Dependencies int `json:"dependencies"`
+
+	// Advisories is the number of advisories received.
+	Advisories int `json:"advisories"`
+
+	// Exposures lists each affected dependency and the code touching it.
+	Exposures []reason.AdvisoryExposure `json:"exposures"`
 }
 
 // --- Coordination Tool Types ---
@@ -884,6 +1953,22 @@ type PlanMultiFileChangeRequest struct {
 	NewName      string `json:"new_name"`
 	Description  string `json:"description"`
 	IncludeTests bool   `json:"include_tests"`
+
+	// SessionID links the plan to an agent session. While the session has
+	// plans pending approval, /agent/continue is refused. Optional.
+	SessionID string `json:"session_id"`
+}
+
+// PreviewRenameRequest is the request for POST /v1/trace/coordinate/preview_rename.
+type PreviewRenameRequest struct {
+	GraphID      string `json:"graph_id" binding:"required"`
+	TargetID     string `json:"target_id" binding:"required"`
+	NewName      string `json:"new_name" binding:"required"`
+	IncludeTests bool   `json:"include_tests"`
+
+	// SessionID links the plan to an agent session, as for plan_changes.
+	// Optional.
+	SessionID string `json:"session_id"`
 }
 
 // ValidatePlanRequest is the request for POST /v1/trace/coordinate/validate_plan.
@@ -897,6 +1982,226 @@ type PreviewChangesRequest struct {
 	ContextLines int    `json:"context_lines"`
 }
 
+// --- Safety Types ---
+
+// SafetyEvaluateRequest is the request for POST /v1/trace/safety/evaluate.
+type SafetyEvaluateRequest struct {
+	// Tool is the tool name to evaluate.
+	Tool string `json:"tool" binding:"required"`
+
+	// Mode is the run mode; empty uses the policy's default mode.
+	Mode string `json:"mode"`
+
+	// Params are the parameters the tool would be called with.
+	Params map[string]any `json:"params"`
+
+	// Plan is the size of a change plan to check against the blast radius.
+	Plan *safety.PlanImpact `json:"plan"`
+}
+
+// SafetyEvaluateResponse is the response for POST /v1/trace/safety/evaluate.
+type SafetyEvaluateResponse struct {
+	// Decision is the policy decision. The call is never executed.
+	Decision *safety.Decision `json:"decision"`
+
+	// LatencyMs is the evaluation time in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// --- Admin Types ---
+
+// LogLevelRequest is the request for POST /v1/trace/admin/loglevel.
+type LogLevelRequest struct {
+	// Level is "debug", "info", "warn" or "error".
+	Level string `json:"level" binding:"required"`
+}
+
+// LogLevelResponse is the response for GET and POST /v1/trace/admin/loglevel.
+type LogLevelResponse struct {
+	// Level is the current log level.
+	Level string `json:"level"`
+
+	// Previous is the level before the change. Empty for GET.
+	Previous string `json:"previous,omitempty"`
+}
+
+// RoutingReloadResponse is the response for POST /v1/trace/admin/reload-routing.
+type RoutingReloadResponse struct {
+	// Generation is the routing config generation now in use.
+	Generation uint64 `json:"generation"`
+
+	// PreFilterSource is the rules file read, or "embedded".
+	PreFilterSource string `json:"prefilter_source"`
+
+	// RegistrySource is the registry file read, or "embedded".
+	RegistrySource string `json:"registry_source"`
+
+	// PreFilterActive is false if the pre-filter was disabled at startup;
+	// the new rules are then stored but not applied until a restart.
+	PreFilterActive bool `json:"prefilter_active"`
+
+	// PreFilterEnabled is the new config's enabled flag.
+	PreFilterEnabled bool `json:"prefilter_enabled"`
+
+	// ToolCount is the number of tools in the new registry.
+	ToolCount int `json:"tool_count"`
+
+	// Changes counts the rules and tools added, removed and changed.
+	Changes config.RoutingConfigDiff `json:"changes"`
+
+	// Summary is Changes as one line, e.g. "forced mappings +1/-0".
+	Summary string `json:"summary"`
+
+	// RestartRequired lists changed settings that only take effect after
+	// a restart.
+	RestartRequired []string `json:"restart_required,omitempty"`
+}
+
+// RoutingReembedResponse is the response for POST
+// /v1/trace/admin/routing/reembed.
+type RoutingReembedResponse struct {
+	// CorpusHash is the hash of the tool corpus being re-embedded.
+	CorpusHash string `json:"corpus_hash"`
+
+	// ToolCount is the number of tools being re-embedded.
+	ToolCount int `json:"tool_count"`
+
+	// Status is "queued": the re-embedding runs in the background.
+	Status string `json:"status"`
+}
+
+// --- Egress Types ---
+
+// EgressAuditResponse is the response for GET /v1/trace/egress/audit.
+type EgressAuditResponse struct {
+	// Records are the matching audit records, newest first.
+	Records []egress.AuditRecord `json:"records"`
+
+	// Count is the number of records returned.
+	Count int `json:"count"`
+
+	// LatencyMs is the query time in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// UsageResponse is the response for GET /v1/trace/usage.
+type UsageResponse struct {
+	// GroupBy is the grouping: key, session or model.
+	GroupBy string `json:"group_by"`
+
+	// From and To echo the queried range (RFC 3339), if bounded.
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+
+	// Groups are the per-group totals, largest total first.
+	Groups []usage.Group `json:"groups"`
+
+	// Totals sums all groups.
+	Totals usage.Totals `json:"totals"`
+
+	// LatencyMs is the query time in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// --- Plan Approval Types ---
+
+// ListPlansResponse is the response for GET /v1/trace/plans.
+type ListPlansResponse struct {
+	// Plans are the matching plans, newest first.
+	Plans []PlanSummary `json:"plans"`
+
+	// Count is the number of plans returned.
+	Count int `json:"count"`
+}
+
+// PlanDetailResponse is the response for GET /v1/trace/plans/:id.
+type PlanDetailResponse struct {
+	// Summary is the plan's approval state.
+	Summary PlanSummary `json:"summary"`
+
+	// Plan is the full change plan.
+	Plan *coordinate.ChangePlan `json:"plan"`
+
+	// Preview holds the unified diffs of the plan. Empty when the preview
+	// failed; see PreviewError.
+	Preview []coordinate.FileDiff `json:"preview"`
+
+	// PreviewError explains why Preview is empty, e.g. the plan's graph
+	// was evicted.
+	PreviewError string `json:"preview_error,omitempty"`
+}
+
+// PlanDecisionRequest is the request for POST /v1/trace/plans/:id/approve
+// and /reject.
+type PlanDecisionRequest struct {
+	// Comment is an optional note recorded with the decision.
+	Comment string `json:"comment"`
+}
+
+// ApplyPlanRequest is the request for POST /v1/trace/plans/:id/apply.
+type ApplyPlanRequest struct {
+	// DryRun reports the edits without writing, running the post-apply
+	// command or refreshing the graph.
+	DryRun bool `json:"dry_run"`
+}
+
+// ApplyPlanResponse is the response for POST /v1/trace/plans/:id/apply.
+type ApplyPlanResponse struct {
+	*coordinate.ApplyResult
+
+	// Summary is the plan's state after the apply; applied unless this
+	// was a dry run.
+	Summary PlanSummary `json:"summary"`
+
+	// PostApply is the output of the configured post-apply command. Nil
+	// for dry runs or when no command is configured.
+	PostApply *PostApplyResult `json:"post_apply,omitempty"`
+
+	// GraphRefresh reports the incremental refresh of the plan's graph.
+	// Nil for dry runs.
+	GraphRefresh *PlanGraphRefresh `json:"graph_refresh,omitempty"`
+
+	// LatencyMs is the time spent in milliseconds.
+	LatencyMs int64 `json:"latency_ms"`
+}
+
+// PostApplyResult is the outcome of the post-apply command. A failing
+// command does not undo the apply.
+type PostApplyResult struct {
+	// Command is the command run, with {files} expanded.
+	Command []string `json:"command"`
+
+	// ExitCode is the command's exit status; -1 if it did not exit.
+	ExitCode int `json:"exit_code"`
+
+	// Output is the combined stdout and stderr, truncated to 64 KiB.
+	Output string `json:"output"`
+
+	// Truncated is true when Output was cut.
+	Truncated bool `json:"truncated,omitempty"`
+
+	// Error explains a command that could not start or timed out.
+	Error string `json:"error,omitempty"`
+
+	// DurationMs is how long the command ran.
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// PlanGraphRefresh reports the graph refresh after an apply.
+type PlanGraphRefresh struct {
+	// GraphID is the refreshed graph.
+	GraphID string `json:"graph_id"`
+
+	// Refreshed is true when the graph was updated.
+	Refreshed bool `json:"refreshed"`
+
+	// FilesParsed is the number of touched files re-parsed.
+	FilesParsed int `json:"files_parsed"`
+
+	// Error explains why the graph was not refreshed; re-run /init then.
+	Error string `json:"error,omitempty"`
+}
+
 // --- Pattern Tool Types ---
 
 // DetectPatternsRequest is the request for POST /v1/trace/patterns/detect.
@@ -917,12 +2222,21 @@ type FindCodeSmellsRequest struct {
 }
 
 // FindDuplicationRequest is the request for POST /v1/trace/patterns/duplication.
+//
+// Mode "pairs" (the default) compares raw source and returns duplicate
+// pairs. Mode "clusters" compares normalized function bodies, matches
+// across languages, and returns clusters; Type is ignored and
+// MinBodyTokens and CrossLanguageOnly apply.
 type FindDuplicationRequest struct {
-	GraphID       string  `json:"graph_id" binding:"required"`
-	Scope         string  `json:"scope"`
-	MinSimilarity float64 `json:"min_similarity"`
-	Type          string  `json:"type"`
-	IncludeTests  bool    `json:"include_tests"`
+	GraphID           string  `json:"graph_id" binding:"required"`
+	Scope             string  `json:"scope"`
+	MinSimilarity     float64 `json:"min_similarity"`
+	Type              string  `json:"type"`
+	IncludeTests      bool    `json:"include_tests"`
+	Mode              string  `json:"mode"`
+	MinBodyTokens     int     `json:"min_body_tokens"`
+	CrossLanguageOnly bool    `json:"cross_language_only"`
+	IncludeGenerated  bool    `json:"include_generated"`
 }
 
 // FindCircularDepsRequest is the request for POST /v1/trace/patterns/circular_deps.
@@ -942,9 +2256,10 @@ type ExtractConventionsRequest struct {
 
 // FindDeadCodeRequest is the request for POST /v1/trace/patterns/dead_code.
 type FindDeadCodeRequest struct {
-	GraphID         string `json:"graph_id" binding:"required"`
-	Scope           string `json:"scope"`
-	IncludeExported bool   `json:"include_exported"`
+	GraphID          string `json:"graph_id" binding:"required"`
+	Scope            string `json:"scope"`
+	IncludeExported  bool   `json:"include_exported"`
+	IncludeGenerated bool   `json:"include_generated"`
 }
 
 // --- Common Response Wrapper ---
@@ -962,6 +2277,19 @@ type AgenticResponse struct {
 
 	// Limitations documents what couldn't be analyzed.
 	Limitations []string `json:"limitations,omitempty"`
+
+	// PlanStatus is the approval state of a plan created by the request.
+	PlanStatus *PlanSummary `json:"plan_status,omitempty"`
+
+	// GeneratedSuppressed counts result items hidden because they are in
+	// generated code. Set by explore and reason tools unless the request
+	// sets include_generated.
+	GeneratedSuppressed int `json:"generated_suppressed,omitempty"`
+
+	// Locations are the code locations Result refers to, by symbol or by
+	// file and line, each once and with a link when a link template is
+	// configured.
+	Locations []CodeLocation `json:"locations,omitempty"`
 }
 
 // =============================================================================
@@ -1197,6 +2525,203 @@ type GraphStatsResponse struct {
 
 	// NodesByKind maps symbol kind name to count.
 	NodesByKind map[string]int `json:"nodes_by_kind"`
+
+	// LiteralUsages is the number of call-site literal arguments indexed
+	// for find_literal_usages, and LiteralUsagesDropped the number left
+	// out by the index cap.
+	LiteralUsages        int `json:"literal_usages"`
+	LiteralUsagesDropped int `json:"literal_usages_dropped,omitempty"`
+}
+
+// ErrorCatalogResponse is the response for GET /v1/trace/errors.
+type ErrorCatalogResponse struct {
+	// Errors lists every error code the API can return, sorted by code.
+	Errors []apierror.CodeInfo `json:"errors"`
+}
+
+// GraphStatusResponse is the response for GET /v1/trace/graph/status.
+//
+// Description:
+//
+//	Summarizes a cached graph. ContentHash identifies the graph's content
+//	independently of when it was built, so clients can tell whether two
+//	builds of a project produced the same graph.
+type GraphStatusResponse struct {
+	// GraphID is the unique identifier for this graph.
+	GraphID string `json:"graph_id"`
+
+	// ProjectRoot is the absolute path to the project root.
+	ProjectRoot string `json:"project_root"`
+
+	// State is the graph state ("building" or "readonly").
+	State string `json:"state"`
+
+	// NodeCount is the total number of nodes in the graph.
+	NodeCount int `json:"node_count"`
+
+	// EdgeCount is the total number of edges in the graph.
+	EdgeCount int `json:"edge_count"`
+
+	// BuiltAtMilli is the Unix timestamp in milliseconds when graph was built.
+	BuiltAtMilli int64 `json:"built_at_milli"`
+
+	// ExpiresAtMilli is when the graph expires (0 = never).
+	ExpiresAtMilli int64 `json:"expires_at_milli"`
+
+	// ContentHash is the graph's content hash (see graph.Graph.ContentHash).
+	ContentHash string `json:"content_hash"`
+
+	// Roots summarizes each root of a workspace graph. Empty for
+	// single-root graphs.
+	Roots []WorkspaceRootStats `json:"roots,omitempty"`
+
+	// GitRef and CommitSHA identify the revision a graph built with
+	// git_ref was read from. Empty for graphs of the working tree.
+	GitRef    string `json:"git_ref,omitempty"`
+	CommitSHA string `json:"commit_sha,omitempty"`
+
+	// FromParseResults is true if the graph was built from uploaded parse
+	// results rather than files on disk.
+	FromParseResults bool `json:"from_parse_results,omitempty"`
+
+	// Degraded is true if the graph's last validation found violations:
+	// tool results carry a warning until the graph is rebuilt or passes
+	// POST /v1/trace/graph/validate.
+	Degraded bool `json:"degraded"`
+
+	// Validation is the graph's last validation report. Nil if the graph
+	// has not been validated since it was fully built.
+	Validation *graph.ValidationReport `json:"validation,omitempty"`
+}
+
+// GraphManifestResponse is the response for GET /v1/trace/graph/manifest.
+//
+// Description:
+//
+//	Lists the content hash of every file the graph was built from, so
+//	clients can measure how far the files on disk have drifted from it.
+type GraphManifestResponse struct {
+	// GraphID is the unique identifier for this graph.
+	GraphID string `json:"graph_id"`
+
+	// ProjectRoot is the absolute path to the project root.
+	ProjectRoot string `json:"project_root"`
+
+	// ContentHash is the graph's content hash (see graph.Graph.ContentHash).
+	ContentHash string `json:"content_hash"`
+
+	// BuiltAtMilli is the Unix timestamp in milliseconds when graph was built.
+	BuiltAtMilli int64 `json:"built_at_milli"`
+
+	// CommitSHA identifies the revision a graph built with git_ref was
+	// read from. Empty for graphs of the working tree.
+	CommitSHA string `json:"commit_sha,omitempty"`
+
+	// Languages are the languages of the graph's symbols, sorted. Sending
+	// them to /v1/trace/init rebuilds the graph with the same parsers.
+	Languages []string `json:"languages"`
+
+	// FileCount is the number of entries in Files.
+	FileCount int `json:"file_count"`
+
+	// Files maps each file path, relative to the project root, to the
+	// SHA-256 hex hash of its content at parse time.
+	Files map[string]string `json:"files"`
+}
+
+// GraphReportResponse is the response for GET /v1/trace/graph/report.
+type GraphReportResponse struct {
+	// GraphID is the unique identifier for this graph.
+	GraphID string `json:"graph_id"`
+
+	// ProjectRoot is the absolute path to the project root.
+	ProjectRoot string `json:"project_root"`
+
+	// QualityReport is the graph's quality report.
+	*graph.QualityReport
+
+	// SemanticIndex is the coverage of the graph's semantic index.
+	// Absent when the index is disabled or has not seen the graph.
+	SemanticIndex *semindex.GraphStatus `json:"semantic_index,omitempty"`
+}
+
+// ProjectSummaryResponse is the response for GET /v1/trace/summary.
+type ProjectSummaryResponse struct {
+	// GraphID is the unique identifier for this graph.
+	GraphID string `json:"graph_id"`
+
+	// Summary is the project summary.
+	*projectsummary.Summary
+
+	// Text is the summary rendered within max_chars, as injected into
+	// the Plan phase prompt.
+	Text string `json:"text"`
+
+	// ProsePending is true while the prose description is being written.
+	ProsePending bool `json:"prose_pending,omitempty"`
+}
+
+// GraphCompactRequest is the request body for POST /v1/trace/graph/compact.
+type GraphCompactRequest struct {
+	// GraphID is the graph to compact. Required.
+	GraphID string `json:"graph_id" binding:"required"`
+}
+
+// GraphCompactResponse is the response for POST /v1/trace/graph/compact.
+//
+// Description:
+//
+//	Reports what a compaction changed (see Service.CompactGraph). Counts
+//	describe the compacted copy; if Installed is false the graph was
+//	replaced while compacting and the copy was discarded.
+type GraphCompactResponse struct {
+	// GraphID is the compacted graph.
+	GraphID string `json:"graph_id"`
+
+	// Installed is true if the compacted graph replaced the cached one.
+	Installed bool `json:"installed"`
+
+	// PlaceholdersResolved is the number of placeholder nodes resolved to
+	// a real symbol and removed.
+	PlaceholdersResolved int `json:"placeholders_resolved"`
+
+	// PlaceholdersRemaining is the number of placeholder nodes left.
+	PlaceholdersRemaining int `json:"placeholders_remaining"`
+
+	// EdgesRewritten is the number of edges re-pointed from a placeholder
+	// to a real symbol.
+	EdgesRewritten int `json:"edges_rewritten"`
+
+	// DuplicateEdgesRemoved is the number of identical edges removed.
+	DuplicateEdgesRemoved int `json:"duplicate_edges_removed"`
+
+	// NodeCount and EdgeCount are the compacted graph's sizes.
+	NodeCount int `json:"node_count"`
+	EdgeCount int `json:"edge_count"`
+
+	// ContentHash is the compacted graph's content hash.
+	ContentHash string `json:"content_hash"`
+
+	// DurationMs is how long the compaction took.
+	DurationMs int64 `json:"duration_ms"`
+}
+
+// GraphValidateRequest is the request for POST /v1/trace/graph/validate.
+type GraphValidateRequest struct {
+	// GraphID is the graph to validate. Required.
+	GraphID string `json:"graph_id" binding:"required"`
+}
+
+// GraphValidateResponse is the response for POST /v1/trace/graph/validate.
+type GraphValidateResponse struct {
+	// GraphID is the validated graph.
+	GraphID string `json:"graph_id"`
+
+	// Degraded is true if the check found violations.
+	Degraded bool `json:"degraded"`
+
+	// Report lists the violations found.
+	Report *graph.ValidationReport `json:"report"`
 }
 
 // =============================================================================
@@ -1419,6 +2944,10 @@ type InspectEdge struct {
 
 	// Location is where the relationship is expressed in code.
 	Location *ast.Location `json:"location,omitempty"`
+
+	// Provenance is the builder pass that created the edge and its
+	// confidence. Omitted for edges without recorded provenance.
+	Provenance *graph.EdgeProvenance `json:"provenance,omitempty"`
 }
 
 // =============================================================================
@@ -0,0 +1,92 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/usage"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleUsage returns LLM token usage and cost rolled up by API key,
+// session or model.
+//
+// Description:
+//
+//	GET /v1/trace/usage?group_by=key|session|model&from=&to=. group_by
+//	defaults to key. from and to are RFC 3339 timestamps; from is
+//	inclusive and to exclusive, and either may be omitted. Groups are
+//	returned largest total first. API keys appear only as their short
+//	hash IDs, as in the access log.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleUsage(c *gin.Context) {
+	start := time.Now()
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleUsage")
+
+	if h.usageStore == nil {
+		apierror.New(http.StatusServiceUnavailable, apierror.CodeUsageNotAvailable, "Usage store is not configured").
+			WithDetails("Set TRACE_USAGE_DIR to persist LLM usage records.").
+			Write(c)
+		return
+	}
+
+	query := usage.Query{GroupBy: c.DefaultQuery("group_by", usage.GroupByKey)}
+	switch query.GroupBy {
+	case usage.GroupByKey, usage.GroupBySession, usage.GroupByModel:
+	default:
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, usage.ErrInvalidGroupBy.Error())
+		return
+	}
+	for _, p := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &query.From}, {"to", &query.To}} {
+		if v := c.Query(p.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, p.name+" must be an RFC 3339 timestamp")
+				return
+			}
+			*p.dst = t
+		}
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && !query.To.After(query.From) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "to must be after from")
+		return
+	}
+
+	groups, err := h.usageStore.Query(c.Request.Context(), query)
+	if err != nil {
+		logger.Error("Failed to query usage", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusInternalServerError, apierror.CodeInternal, "Failed to query usage")
+		return
+	}
+
+	resp := UsageResponse{
+		GroupBy: query.GroupBy,
+		From:    c.Query("from"),
+		To:      c.Query("to"),
+		Groups:  groups,
+	}
+	for _, g := range groups {
+		resp.Totals.Merge(g.Totals)
+	}
+	resp.LatencyMs = time.Since(start).Milliseconds()
+
+	logger.Info("Queried usage", "group_by", query.GroupBy, "groups", len(groups))
+	c.JSON(http.StatusOK, resp)
+}
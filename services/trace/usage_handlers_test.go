@@ -0,0 +1,106 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers/usage"
+	badgerstore "github.com/AleutianAI/AleutianFOSS/services/trace/storage/badger"
+	"github.com/gin-gonic/gin"
+)
+
+func TestHandlers_HandleUsage(t *testing.T) {
+	db, err := badgerstore.OpenDB(badgerstore.InMemoryConfig())
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer db.Close()
+	store := usage.NewBadgerStore(db, time.Hour)
+
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	for i, r := range []usage.Record{
+		{SessionID: "s1", APIKeyID: "key_a", Provider: "anthropic", Model: "claude-sonnet-4-6", PromptTokens: 100, CompletionTokens: 10},
+		{SessionID: "s2", APIKeyID: "key_b", Provider: "ollama", Model: "glm", PromptTokens: 40, CompletionTokens: 4, Estimated: true},
+		{SessionID: "s1", APIKeyID: "key_a", Provider: "anthropic", Model: "claude-sonnet-4-6", PromptTokens: 10, CompletionTokens: 1},
+	} {
+		r.ID = "r" + string(rune('0'+i))
+		r.Timestamp = base.Add(time.Duration(i) * time.Hour).UnixMilli()
+		if _, err := store.Append(context.Background(), &r); err != nil {
+			t.Fatalf("Append: %v", err)
+		}
+	}
+
+	router := gin.New()
+	RegisterRoutes(router.Group("/v1"), NewHandlers(NewService(DefaultServiceConfig())).WithUsageStore(store))
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		want   map[string]int // group key -> total tokens
+	}{
+		{"default groups by key", "", http.StatusOK, map[string]int{"key_a": 121, "key_b": 44}},
+		{"by session", "?group_by=session", http.StatusOK, map[string]int{"s1": 121, "s2": 44}},
+		{"by model in range", "?group_by=model&from=2026-03-01T09:30:00Z&to=2026-03-01T10:30:00Z", http.StatusOK, map[string]int{"ollama/glm": 44}},
+		{"bad group_by", "?group_by=day", http.StatusBadRequest, nil},
+		{"bad from", "?from=yesterday", http.StatusBadRequest, nil},
+		{"inverted range", "?from=2026-03-02T00:00:00Z&to=2026-03-01T00:00:00Z", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/v1/trace/usage"+tt.query, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var resp UsageResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to unmarshal response: %v", err)
+			}
+			if len(resp.Groups) != len(tt.want) {
+				t.Fatalf("groups = %+v, want %v", resp.Groups, tt.want)
+			}
+			sum := 0
+			for _, g := range resp.Groups {
+				if tt.want[g.Key] != g.TotalTokens {
+					t.Errorf("%s = %d tokens, want %d", g.Key, g.TotalTokens, tt.want[g.Key])
+				}
+				sum += g.TotalTokens
+			}
+			if resp.Totals.TotalTokens != sum {
+				t.Errorf("totals = %d tokens, want %d", resp.Totals.TotalTokens, sum)
+			}
+		})
+	}
+}
+
+func TestHandlers_HandleUsage_NotConfigured(t *testing.T) {
+	router := setupTestRouter(NewService(DefaultServiceConfig()))
+
+	req, _ := http.NewRequest("GET", "/v1/trace/usage", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, w.Code)
+	}
+}
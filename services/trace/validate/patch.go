@@ -27,6 +27,7 @@ import (
 	"github.com/sourcegraph/go-diff/diff"
 
 	"github.com/AleutianAI/AleutianFOSS/services/trace/lint"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // PatchValidator validates patches for safety.
@@ -394,7 +395,7 @@ func (v *PatchValidator) checkLint(ctx context.Context, absPath string, fileDiff
 	if !v.lintRunner.IsAvailable(language) {
 		slog.Debug("Linter not available for language",
 			slog.String("language", language),
-			slog.String("file", relPath),
+			telemetry.SensitiveLog("file", relPath),
 		)
 		return
 	}
@@ -405,8 +406,8 @@ func (v *PatchValidator) checkLint(ctx context.Context, absPath string, fileDiff
 		original, err = os.ReadFile(absPath)
 		if err != nil {
 			slog.Warn("Failed to read file for linting",
-				slog.String("file", relPath),
-				slog.String("error", err.Error()),
+				telemetry.SensitiveLog("file", relPath),
+				telemetry.SensitiveLogError("error", err),
 			)
 			return
 		}
@@ -416,8 +417,8 @@ func (v *PatchValidator) checkLint(ctx context.Context, absPath string, fileDiff
 	newContent, err := v.applyDiff(original, fileDiff)
 	if err != nil {
 		slog.Warn("Failed to apply diff for linting",
-			slog.String("file", relPath),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLog("file", relPath),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return
 	}
@@ -431,9 +432,9 @@ func (v *PatchValidator) checkLint(ctx context.Context, absPath string, fileDiff
 	lintResult, err := v.lintRunner.LintContent(ctx, newContent, language)
 	if err != nil {
 		slog.Warn("Linter execution failed",
-			slog.String("file", relPath),
+			telemetry.SensitiveLog("file", relPath),
 			slog.String("language", language),
-			slog.String("error", err.Error()),
+			telemetry.SensitiveLogError("error", err),
 		)
 		return
 	}
@@ -470,7 +471,7 @@ func (v *PatchValidator) checkLint(ctx context.Context, absPath string, fileDiff
 	}
 
 	slog.Debug("Lint check completed",
-		slog.String("file", relPath),
+		telemetry.SensitiveLog("file", relPath),
 		slog.String("linter", lintResult.Linter),
 		slog.Int("errors", len(lintResult.Errors)),
 		slog.Int("warnings", len(lintResult.Warnings)),
@@ -0,0 +1,260 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrRootNotAllowed indicates a path is outside every allowed root.
+var ErrRootNotAllowed = errors.New("path is outside the allowed roots")
+
+// RootAllowlist restricts the directories a server may read.
+//
+// Description:
+//
+//	Holds a list of root patterns. A path is allowed when, after
+//	canonicalization (absolute, cleaned, symlinks resolved), the path or
+//	one of its ancestors matches a pattern. Patterns are directory paths
+//	that may contain filepath.Match globs, e.g. "/srv/projects/*" allows
+//	every project directory under /srv/projects and everything below
+//	them. Canonicalizing before matching means a symlink inside an
+//	allowed root that points elsewhere is judged by its target.
+//
+//	A nil *RootAllowlist allows every path; Check still canonicalizes.
+//
+// Thread Safety: Immutable after construction; safe for concurrent use.
+type RootAllowlist struct {
+	patterns []string
+}
+
+// NewRootAllowlist creates an allowlist from root patterns.
+//
+// Description:
+//
+//	Empty entries are ignored. The literal (glob-free) leading part of
+//	each pattern is canonicalized when it exists, so "/tmp" on a system
+//	where /tmp is a symlink matches the resolved paths it will be
+//	compared against.
+//
+// Inputs:
+//
+//	patterns - Absolute directory paths, optionally with globs.
+//
+// Outputs:
+//
+//	*RootAllowlist - The allowlist. Never nil; with no usable patterns it
+//	  allows nothing.
+//	error - Non-nil if a pattern is relative or not a valid glob.
+func NewRootAllowlist(patterns []string) (*RootAllowlist, error) {
+	a := &RootAllowlist{}
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !filepath.IsAbs(p) {
+			return nil, fmt.Errorf("allowed root %q must be absolute", p)
+		}
+		if _, err := filepath.Match(p, p); err != nil {
+			return nil, fmt.Errorf("allowed root %q: %w", p, err)
+		}
+		a.patterns = append(a.patterns, canonicalPattern(filepath.Clean(p)))
+	}
+	return a, nil
+}
+
+// ParseRootAllowlist parses a TRACE_ALLOWED_ROOTS value.
+//
+// Inputs:
+//
+//	spec - Root patterns separated by colons. Empty entries are ignored.
+//
+// Outputs:
+//
+//	*RootAllowlist - The allowlist.
+//	error - Non-nil if a pattern is invalid or contains a comma. The
+//	  variable used to be comma-separated, so a comma almost always means
+//	  an old-style list that would otherwise become one root matching
+//	  nothing; a literal comma in a directory name can be written as the
+//	  glob "[,]".
+func ParseRootAllowlist(spec string) (*RootAllowlist, error) {
+	patterns := strings.Split(spec, ":")
+	for _, p := range patterns {
+		if strings.Contains(strings.ReplaceAll(p, "[,]", ""), ",") {
+			return nil, fmt.Errorf("allowed root %q contains a comma: separate roots with ':' (use [,] to match a literal comma)", strings.TrimSpace(p))
+		}
+	}
+	return NewRootAllowlist(patterns)
+}
+
+// DefaultRootAllowlist returns the single-user default: the user's home
+// directory and the current working directory.
+func DefaultRootAllowlist() *RootAllowlist {
+	var roots []string
+	if home, err := os.UserHomeDir(); err == nil {
+		roots = append(roots, home)
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		roots = append(roots, cwd)
+	}
+	a, _ := NewRootAllowlist(roots)
+	return a
+}
+
+// Patterns returns the canonicalized root patterns.
+func (a *RootAllowlist) Patterns() []string {
+	if a == nil {
+		return nil
+	}
+	return append([]string(nil), a.patterns...)
+}
+
+// Check canonicalizes path and verifies it is inside an allowed root.
+//
+// Inputs:
+//
+//	path - The path to check. Relative paths are made absolute against
+//	  the working directory. It need not exist; missing trailing
+//	  components are appended to the resolved existing prefix.
+//
+// Outputs:
+//
+//	string - The canonical path.
+//	error - ErrRootNotAllowed (wrapped) if no root allows the path, or the
+//	  error from resolving it.
+func (a *RootAllowlist) Check(path string) (string, error) {
+	canonical, err := CanonicalPath(path)
+	if err != nil {
+		return "", err
+	}
+	if a == nil {
+		return canonical, nil
+	}
+	for dir := canonical; ; dir = filepath.Dir(dir) {
+		for _, pattern := range a.patterns {
+			if ok, _ := filepath.Match(pattern, dir); ok {
+				return canonical, nil
+			}
+		}
+		if parent := filepath.Dir(dir); parent == dir {
+			break
+		}
+	}
+	return "", fmt.Errorf("%w: %s", ErrRootNotAllowed, path)
+}
+
+// CanonicalPath returns path made absolute and cleaned, with symlinks
+// resolved. When path does not exist, the longest existing prefix is
+// resolved and the remaining components are appended.
+func CanonicalPath(path string) (string, error) {
+	if strings.ContainsRune(path, 0) {
+		return "", ErrNullByte
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", path, err)
+	}
+	var missing []string
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		resolved, err := filepath.EvalSymlinks(dir)
+		if err == nil {
+			for i := len(missing) - 1; i >= 0; i-- {
+				resolved = filepath.Join(resolved, missing[i])
+			}
+			return resolved, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("resolve %s: %w", path, err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return abs, nil
+		}
+		missing = append(missing, filepath.Base(dir))
+	}
+}
+
+// ResolveWithin joins relPath to root and verifies that the result, with
+// symlinks resolved, stays inside root.
+//
+// Description:
+//
+//	Used by readers that open project files by their graph-relative path.
+//	Catches "../" in relPath and symlinks inside the project that point
+//	outside it.
+//
+// Inputs:
+//
+//	root - The project root.
+//	relPath - A path relative to root. Absolute paths are taken as is.
+//
+// Outputs:
+//
+//	string - The canonical path to open.
+//	error - ErrPathTraversal (wrapped) if the path escapes root.
+func ResolveWithin(root, relPath string) (string, error) {
+	canonicalRoot, err := CanonicalPath(root)
+	if err != nil {
+		return "", err
+	}
+	path := relPath
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, relPath)
+	}
+	canonical, err := CanonicalPath(path)
+	if err != nil {
+		return "", err
+	}
+	if !IsWithin(canonicalRoot, canonical) {
+		return "", fmt.Errorf("%w: %s resolves outside %s", ErrPathTraversal, relPath, root)
+	}
+	return canonical, nil
+}
+
+// IsWithin reports whether path equals root or lies below it. Both must
+// be clean absolute paths; unlike a plain prefix test, /home/al does not
+// contain /home/alice.
+func IsWithin(root, path string) bool {
+	if path == root {
+		return true
+	}
+	if !strings.HasSuffix(root, string(filepath.Separator)) {
+		root += string(filepath.Separator)
+	}
+	return strings.HasPrefix(path, root)
+}
+
+// canonicalPattern resolves the glob-free leading directories of pattern
+// through symlinks, keeping the rest of the pattern as written.
+func canonicalPattern(pattern string) string {
+	parts := strings.Split(pattern, string(filepath.Separator))
+	literal := len(parts)
+	for i, part := range parts {
+		if strings.ContainsAny(part, `*?[\`) {
+			literal = i
+			break
+		}
+	}
+	prefix := strings.Join(parts[:literal], string(filepath.Separator))
+	if prefix == "" {
+		return pattern
+	}
+	resolved, err := filepath.EvalSymlinks(prefix)
+	if err != nil {
+		return pattern
+	}
+	return filepath.Join(append([]string{resolved}, parts[literal:]...)...)
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package validation
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// rootTree creates base/{allowed/proj, allowed-other, outside/secret} and a
+// symlink allowed/escape -> outside. Returns the canonical base.
+func rootTree(t *testing.T) string {
+	t.Helper()
+	base, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{"allowed/proj", "allowed-other", "outside"} {
+		if err := os.MkdirAll(filepath.Join(base, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(base, "outside", "secret"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(base, "outside"), filepath.Join(base, "allowed", "escape")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	return base
+}
+
+func TestRootAllowlist_Check(t *testing.T) {
+	base := rootTree(t)
+	allow, err := NewRootAllowlist([]string{filepath.Join(base, "allowed")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		allowed bool
+	}{
+		{"root itself", filepath.Join(base, "allowed"), true},
+		{"below root", filepath.Join(base, "allowed", "proj"), true},
+		{"missing path below root", filepath.Join(base, "allowed", "proj", "new", "file.go"), true},
+		{"outside", filepath.Join(base, "outside"), false},
+		{"sibling sharing a prefix", filepath.Join(base, "allowed-other"), false},
+		{"symlink escape", filepath.Join(base, "allowed", "escape"), false},
+		{"file through symlink escape", filepath.Join(base, "allowed", "escape", "secret"), false},
+		{"dot-dot traversal", filepath.Join(base, "allowed") + "/proj/../../outside", false},
+		{"dot-dot staying inside", filepath.Join(base, "allowed") + "/proj/../proj", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := allow.Check(tt.path)
+			if tt.allowed && err != nil {
+				t.Errorf("Check(%s) = %v, want allowed", tt.path, err)
+			}
+			if !tt.allowed && !errors.Is(err, ErrRootNotAllowed) {
+				t.Errorf("Check(%s) = %v, want ErrRootNotAllowed", tt.path, err)
+			}
+		})
+	}
+}
+
+func TestRootAllowlist_Globs(t *testing.T) {
+	base := rootTree(t)
+	allow, err := ParseRootAllowlist(filepath.Join(base, "allowed*") + ":" + filepath.Join(base, "none", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := allow.Check(filepath.Join(base, "allowed-other")); err != nil {
+		t.Errorf("glob should allow allowed-other: %v", err)
+	}
+	if _, err := allow.Check(filepath.Join(base, "allowed", "proj")); err != nil {
+		t.Errorf("glob should allow below a matching directory: %v", err)
+	}
+	if _, err := allow.Check(filepath.Join(base, "outside")); !errors.Is(err, ErrRootNotAllowed) {
+		t.Errorf("outside = %v, want ErrRootNotAllowed", err)
+	}
+	// A glob cannot be satisfied through a symlink whose target is outside.
+	if _, err := allow.Check(filepath.Join(base, "allowed", "escape")); !errors.Is(err, ErrRootNotAllowed) {
+		t.Errorf("symlink escape = %v, want ErrRootNotAllowed", err)
+	}
+}
+
+func TestParseRootAllowlist(t *testing.T) {
+	allow, err := ParseRootAllowlist("/srv/a:/srv/b: /srv/c::")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := allow.Patterns(); len(got) != 3 {
+		t.Errorf("Patterns = %v, want 3 entries", got)
+	}
+	if _, err := ParseRootAllowlist("/srv/a,/srv/b"); err == nil || !strings.Contains(err.Error(), "separate roots with ':'") {
+		t.Errorf("comma-separated roots: err = %v, want a separator diagnostic", err)
+	}
+	comma, err := ParseRootAllowlist("/srv/a[,]b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := comma.Check("/srv/a,b/project"); err != nil {
+		t.Errorf("[,] should match a literal comma: %v", err)
+	}
+	if _, err := ParseRootAllowlist("relative/dir"); err == nil {
+		t.Error("relative root should be rejected")
+	}
+	if _, err := ParseRootAllowlist("/srv/[a"); err == nil {
+		t.Error("malformed glob should be rejected")
+	}
+
+	empty, err := NewRootAllowlist(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := empty.Check("/"); !errors.Is(err, ErrRootNotAllowed) {
+		t.Errorf("empty allowlist allowed /: %v", err)
+	}
+	var none *RootAllowlist
+	if _, err := none.Check("/"); err != nil {
+		t.Errorf("nil allowlist should allow everything: %v", err)
+	}
+}
+
+func TestDefaultRootAllowlist(t *testing.T) {
+	allow := DefaultRootAllowlist()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := allow.Check(cwd); err != nil {
+		t.Errorf("default allowlist should allow the working directory: %v", err)
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		if _, err := allow.Check(filepath.Join(home, "project")); err != nil {
+			t.Errorf("default allowlist should allow $HOME: %v", err)
+		}
+	}
+}
+
+func TestResolveWithin(t *testing.T) {
+	base := rootTree(t)
+	root := filepath.Join(base, "allowed")
+
+	got, err := ResolveWithin(root, "proj/main.go")
+	if err != nil || got != filepath.Join(root, "proj", "main.go") {
+		t.Errorf("ResolveWithin(proj/main.go) = %q, %v", got, err)
+	}
+	for _, rel := range []string{"../outside/secret", "escape/secret", "proj/../../outside", filepath.Join(base, "outside", "secret")} {
+		if _, err := ResolveWithin(root, rel); !errors.Is(err, ErrPathTraversal) {
+			t.Errorf("ResolveWithin(%s) = %v, want ErrPathTraversal", rel, err)
+		}
+	}
+}
+
+func TestIsWithin(t *testing.T) {
+	if !IsWithin("/home/al", "/home/al/x") || !IsWithin("/home/al", "/home/al") {
+		t.Error("IsWithin should accept the root and its children")
+	}
+	if IsWithin("/home/al", "/home/alice") {
+		t.Error("IsWithin must not treat /home/alice as inside /home/al")
+	}
+	if !IsWithin("/", "/etc") {
+		t.Error("everything is within /")
+	}
+}
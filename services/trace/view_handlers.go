@@ -0,0 +1,129 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleCreateView creates or replaces a named scoped view of a graph.
+//
+// Description:
+//
+//	POST /v1/trace/views with a CreateViewRequest. The view can then be
+//	passed as scope to the query endpoints and to agent runs.
+//
+// Response:
+//
+//	201 Created: GraphView
+//	400 Bad Request: Invalid body, name, patterns or boundary mode, or
+//	  graph not initialized
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleCreateView(c *gin.Context) {
+	requestID := getOrCreateRequestID(c)
+	logger := requestLogger(c).With("request_id", requestID, "handler", "HandleCreateView")
+
+	var req CreateViewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Warn("Invalid request body", telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidRequest, "Invalid request body: name and graph_id are required")
+		return
+	}
+
+	view, err := h.svc.CreateView(req.Name, req.GraphID, req.Include, req.Exclude, req.Boundary)
+	if err != nil {
+		if isGraphStateError(err) {
+			apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
+			return
+		}
+		logger.Warn("Invalid view", "name", req.Name, telemetry.SensitiveLogError("error", err))
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+		return
+	}
+
+	logger.Info("Created view",
+		"name", view.Name,
+		"graph_id", view.GraphID,
+		"nodes", view.NodeCount,
+		"boundary_nodes", view.BoundaryNodeCount)
+	c.JSON(http.StatusCreated, view)
+}
+
+// HandleListViews lists the scoped views, sorted by name.
+//
+// Description:
+//
+//	GET /v1/trace/views.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleListViews(c *gin.Context) {
+	c.JSON(http.StatusOK, ListViewsResponse{Views: h.svc.ListViews()})
+}
+
+// HandleGetView returns a scoped view.
+//
+// Description:
+//
+//	GET /v1/trace/views/:name. Returns 404 VIEW_NOT_FOUND if unknown.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleGetView(c *gin.Context) {
+	view, err := h.svc.GetView(c.Param("name"))
+	if err != nil {
+		apierror.Write(c, http.StatusNotFound, apierror.CodeViewNotFound, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, view)
+}
+
+// HandleDeleteView deletes a scoped view.
+//
+// Description:
+//
+//	DELETE /v1/trace/views/:name. Returns 204, or 404 VIEW_NOT_FOUND if
+//	unknown. Running agent sessions keep the scoped graph they started
+//	with.
+//
+// Thread Safety: Safe for concurrent use.
+func (h *Handlers) HandleDeleteView(c *gin.Context) {
+	if err := h.svc.DeleteView(c.Param("name")); err != nil {
+		apierror.Write(c, http.StatusNotFound, apierror.CodeViewNotFound, err.Error())
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// scopeFilter resolves the scope parameter of a query, writing the error
+// response if it is invalid.
+//
+// Outputs:
+//
+//	*graph.ScopedView - The view; nil if scope is empty.
+//	bool - False if an error response was written.
+func (h *Handlers) scopeFilter(c *gin.Context, graphID, scope string) (*graph.ScopedView, bool) {
+	view, err := h.svc.ScopeFilter(graphID, scope)
+	if err == nil {
+		return view, true
+	}
+	if errors.Is(err, ErrGraphNotInitialized) || errors.Is(err, ErrGraphExpired) {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeGraphNotInitialized, err.Error())
+	} else {
+		apierror.Write(c, http.StatusBadRequest, apierror.CodeInvalidParameter, err.Error())
+	}
+	return nil, false
+}
@@ -11,7 +11,30 @@
 package trace
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Warmup roles. Each model role that is warmed at startup reports its own
+// completion; the service is warm once every expected role has.
+const (
+	// WarmupRoleMain is the main agent model. Always expected.
+	WarmupRoleMain = "main"
+
+	// WarmupRoleRouter is the tool router model.
+	WarmupRoleRouter = "router"
+
+	// WarmupRoleParamExtractor is the parameter extractor model.
+	WarmupRoleParamExtractor = "param_extractor"
 )
 
 // warmupStatus tracks whether the main LLM model has completed warming up.
@@ -20,7 +43,47 @@ import (
 // and checked from handlers.go.
 var warmupStatus atomic.Int32
 
-// IsWarmupComplete returns true if the main model warmup has finished.
+// warmupRoles tracks the roles other than main that must warm up before
+// the service is ready, keyed by role. Main lives in warmupStatus so the
+// hot-path IsWarmupComplete check stays lock-free once everything is warm.
+var warmupRoles = struct {
+	sync.RWMutex
+	roles   map[string]*WarmupRoleStatus
+	pending atomic.Int32
+}{roles: make(map[string]*WarmupRoleStatus)}
+
+// mainWarmup records when and how the main role finished.
+var mainWarmup struct {
+	sync.Mutex
+	completedAt time.Time
+	err         string
+}
+
+// warmupChanged is closed and replaced whenever a role completes, waking
+// requests held by the warmup guard.
+var warmupChanged = struct {
+	sync.Mutex
+	ch chan struct{}
+}{ch: make(chan struct{})}
+
+// WarmupRoleStatus is the warmup state of one model role.
+type WarmupRoleStatus struct {
+	// Role is the model role (main, router, param_extractor).
+	Role string `json:"role"`
+
+	// Complete is true once warmup finished, successfully or not.
+	Complete bool `json:"complete"`
+
+	// Error is the warmup error, if warmup failed. A failed warmup still
+	// completes; the model loads on first use instead.
+	Error string `json:"error,omitempty"`
+
+	// CompletedAt is when warmup finished.
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// IsWarmupComplete returns true if the main model and every expected role
+// have finished warming up.
 //
 // Description:
 //
@@ -29,28 +92,290 @@ var warmupStatus atomic.Int32
 //
 // Thread Safety: This function is safe for concurrent use.
 func IsWarmupComplete() bool {
-	return warmupStatus.Load() == 1
+	return warmupStatus.Load() == 1 && warmupRoles.pending.Load() == 0
 }
 
-// MarkWarmupComplete marks the warmup as complete.
+// MarkWarmupComplete marks the main model warmup as complete.
 //
 // Description:
 //
 //	Called from cmd/trace/main.go after model warmup completes (success or failure).
-//	After this is called, the /ready endpoint will return 200 OK.
+//	After this is called, and once every role registered with ExpectWarmup
+//	is complete, the /ready endpoint will return 200 OK.
 //
 // Thread Safety: This function is safe for concurrent use.
 func MarkWarmupComplete() {
-	warmupStatus.Store(1)
+	MarkRoleWarmupComplete(WarmupRoleMain, nil)
+}
+
+// ExpectWarmup registers a role that must finish warming up before the
+// service is ready.
+//
+// Description:
+//
+//	Call before starting the role's warmup; every call must be followed by
+//	MarkRoleWarmupComplete for the role, including on failure and panic
+//	paths, or the service never becomes ready. Registering a role twice,
+//	or registering WarmupRoleMain, has no effect.
+//
+// Inputs:
+//
+//	role - The model role.
+//
+// Thread Safety: This function is safe for concurrent use.
+func ExpectWarmup(role string) {
+	if role == WarmupRoleMain {
+		return
+	}
+	warmupRoles.Lock()
+	defer warmupRoles.Unlock()
+	if _, ok := warmupRoles.roles[role]; ok {
+		return
+	}
+	warmupRoles.roles[role] = &WarmupRoleStatus{Role: role}
+	warmupRoles.pending.Add(1)
+}
+
+// MarkRoleWarmupComplete marks a role's warmup as complete.
+//
+// Description:
+//
+//	err is recorded for the readiness report but does not keep the role
+//	pending. Marking a role that was not expected registers it as
+//	already complete.
+//
+// Inputs:
+//
+//	role - The model role.
+//	err - The warmup error, or nil on success.
+//
+// Thread Safety: This function is safe for concurrent use.
+func MarkRoleWarmupComplete(role string, err error) {
+	now := time.Now()
+	errMsg := ""
+	if err != nil {
+		errMsg = err.Error()
+	}
+
+	if role == WarmupRoleMain {
+		mainWarmup.Lock()
+		if warmupStatus.Load() == 0 {
+			mainWarmup.completedAt = now
+			mainWarmup.err = errMsg
+		}
+		mainWarmup.Unlock()
+		warmupStatus.Store(1)
+		notifyWarmupChanged()
+		return
+	}
+
+	defer notifyWarmupChanged()
+	warmupRoles.Lock()
+	defer warmupRoles.Unlock()
+	status, ok := warmupRoles.roles[role]
+	if !ok {
+		status = &WarmupRoleStatus{Role: role}
+		warmupRoles.roles[role] = status
+	} else if !status.Complete {
+		warmupRoles.pending.Add(-1)
+	}
+	if status.Complete {
+		return
+	}
+	status.Complete = true
+	status.Error = errMsg
+	status.CompletedAt = &now
+}
+
+// WarmupChanged returns a channel that is closed the next time a role
+// completes warmup.
+//
+// Description:
+//
+//	Take the channel before checking PendingWarmupRoles, then wait on it,
+//	so a completion between the check and the wait is not missed.
+//
+// Thread Safety: This function is safe for concurrent use.
+func WarmupChanged() <-chan struct{} {
+	warmupChanged.Lock()
+	defer warmupChanged.Unlock()
+	return warmupChanged.ch
+}
+
+// notifyWarmupChanged wakes everything waiting on WarmupChanged.
+func notifyWarmupChanged() {
+	warmupChanged.Lock()
+	defer warmupChanged.Unlock()
+	close(warmupChanged.ch)
+	warmupChanged.ch = make(chan struct{})
+}
+
+// PendingWarmupRoles returns which of the given roles are still warming up.
+//
+// Description:
+//
+//	A role is pending when it is main and main has not completed, or when
+//	it was registered with ExpectWarmup and has not completed. Roles that
+//	were never expected (cloud-hosted or not configured) are never pending.
+//	With no roles, every expected role is checked.
+//
+// Inputs:
+//
+//	roles - The roles to check.
+//
+// Outputs:
+//
+//	[]string - The pending roles, in the order given. Nil if none.
+//
+// Thread Safety: This function is safe for concurrent use.
+func PendingWarmupRoles(roles ...string) []string {
+	if IsWarmupComplete() {
+		return nil
+	}
+	if len(roles) == 0 {
+		roles = []string{WarmupRoleMain}
+		warmupRoles.RLock()
+		for role := range warmupRoles.roles {
+			roles = append(roles, role)
+		}
+		warmupRoles.RUnlock()
+		sort.Strings(roles[1:])
+	}
+
+	var pending []string
+	warmupRoles.RLock()
+	defer warmupRoles.RUnlock()
+	for _, role := range roles {
+		if role == WarmupRoleMain {
+			if warmupStatus.Load() == 0 {
+				pending = append(pending, role)
+			}
+			continue
+		}
+		if status, ok := warmupRoles.roles[role]; ok && !status.Complete {
+			pending = append(pending, role)
+		}
+	}
+	return pending
+}
+
+// WarmupStatuses returns the warmup state of every role, main first and
+// the rest sorted by role.
+//
+// Thread Safety: This function is safe for concurrent use.
+func WarmupStatuses() []WarmupRoleStatus {
+	main := WarmupRoleStatus{Role: WarmupRoleMain, Complete: warmupStatus.Load() == 1}
+	if main.Complete {
+		mainWarmup.Lock()
+		if !mainWarmup.completedAt.IsZero() {
+			completedAt := mainWarmup.completedAt
+			main.CompletedAt = &completedAt
+		}
+		main.Error = mainWarmup.err
+		mainWarmup.Unlock()
+	}
+
+	warmupRoles.RLock()
+	others := make([]WarmupRoleStatus, 0, len(warmupRoles.roles))
+	for _, status := range warmupRoles.roles {
+		others = append(others, *status)
+	}
+	warmupRoles.RUnlock()
+	sort.Slice(others, func(i, j int) bool { return others[i].Role < others[j].Role })
+
+	return append([]WarmupRoleStatus{main}, others...)
 }
 
 // ResetWarmupStatus resets the warmup status to incomplete.
 //
 // Description:
 //
-//	Used for testing to reset the warmup state between tests.
+//	Used for testing to reset the warmup state between tests. Also forgets
+//	every role registered with ExpectWarmup.
 //
 // Thread Safety: This function is safe for concurrent use.
 func ResetWarmupStatus() {
 	warmupStatus.Store(0)
+	mainWarmup.Lock()
+	mainWarmup.completedAt = time.Time{}
+	mainWarmup.err = ""
+	mainWarmup.Unlock()
+
+	warmupRoles.Lock()
+	warmupRoles.roles = make(map[string]*WarmupRoleStatus)
+	warmupRoles.pending.Store(0)
+	warmupRoles.Unlock()
+}
+
+// warmupEvictedTotal counts warmed models that were no longer resident
+// when checked after warmup, labelled by role.
+var warmupEvictedTotal = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "trace_warmup_model_evicted_total",
+		Help: "Models that were evicted from memory after startup warmup, by role",
+	},
+	[]string{"role"},
+)
+
+// ResidentModelLister lists the models a local inference server holds in
+// memory. Implemented by *llm.MultiModelManager via Ollama's /api/ps.
+type ResidentModelLister interface {
+	RunningModels(ctx context.Context) ([]string, error)
+}
+
+// VerifyWarmupResidency checks that warmed models are still loaded.
+//
+// Description:
+//
+//	Loading several models can evict ones warmed earlier when memory is
+//	tight, which brings back the cold start warmup was meant to avoid.
+//	For each role whose model is not resident, logs a warning and
+//	increments trace_warmup_model_evicted_total. Model names match with
+//	or without the implicit ":latest" tag.
+//
+// Inputs:
+//
+//	ctx - Context for cancellation.
+//	lister - Lists resident models. Must not be nil.
+//	expected - The model warmed for each role, keyed by role.
+//
+// Outputs:
+//
+//	[]string - The roles whose model was evicted, sorted.
+//	error - Non-nil if the resident models could not be listed.
+//
+// Thread Safety: This function is safe for concurrent use.
+func VerifyWarmupResidency(ctx context.Context, lister ResidentModelLister, expected map[string]string) ([]string, error) {
+	running, err := lister.RunningModels(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing resident models: %w", err)
+	}
+	resident := make(map[string]bool, len(running))
+	for _, model := range running {
+		resident[normalizeModelTag(model)] = true
+	}
+
+	var evicted []string
+	for role, model := range expected {
+		if model == "" || resident[normalizeModelTag(model)] {
+			continue
+		}
+		evicted = append(evicted, role)
+		warmupEvictedTotal.WithLabelValues(role).Inc()
+		slog.Warn("Warmed model is no longer resident; first request will cold-start",
+			slog.String("role", role),
+			slog.String("model", model),
+			slog.Any("resident", running))
+	}
+	sort.Strings(evicted)
+	return evicted, nil
+}
+
+// normalizeModelTag adds Ollama's implicit ":latest" tag to an untagged
+// model name.
+func normalizeModelTag(model string) string {
+	if strings.Contains(model, ":") {
+		return model
+	}
+	return model + ":latest"
 }
@@ -0,0 +1,347 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/apierror"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Warmup guard defaults.
+const (
+	// DefaultWarmupQueueSize is how many requests the warmup guard holds at
+	// once when WarmupGuardConfig.QueueSize is zero.
+	DefaultWarmupQueueSize = 32
+
+	// DefaultWarmupQueueTimeout is how long the warmup guard holds a
+	// request when WarmupGuardConfig.QueueTimeout is zero.
+	DefaultWarmupQueueTimeout = 2 * time.Minute
+
+	// warmupRetryAfter is the Retry-After, in seconds, of a WARMING_UP 503.
+	warmupRetryAfter = 30
+
+	// maxWarmupGuardBody caps how much of a request body the guard reads to
+	// classify it. Larger bodies are treated as needing every role.
+	maxWarmupGuardBody = 1 << 20
+)
+
+// Warmup queue outcomes, the values of the outcome label of
+// trace_warmup_queue_requests_total.
+const (
+	warmupQueueServed   = "served"
+	warmupQueueTimeout  = "timeout"
+	warmupQueueFull     = "full"
+	warmupQueueCanceled = "canceled"
+)
+
+var warmupGuardTracer = otel.Tracer("aleutian.trace")
+
+var (
+	// warmupQueueRequests counts requests held during warmup, by outcome.
+	warmupQueueRequests = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "trace_warmup_queue_requests_total",
+			Help: "Requests that asked to queue during model warmup, by outcome",
+		},
+		[]string{"outcome"},
+	)
+
+	// warmupQueueDepth is the number of requests currently held.
+	warmupQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "trace_warmup_queue_depth",
+		Help: "Requests currently held until model warmup completes",
+	})
+)
+
+// WarmupGuardConfig configures NewWarmupGuard.
+type WarmupGuardConfig struct {
+	// Roles are the warmup roles the guarded endpoints can need. Default:
+	// main, router and param_extractor.
+	Roles []string
+
+	// RoleConfig is the startup provider configuration. A role served by a
+	// provider that needs no local model load (a cloud provider, or replay)
+	// never blocks a request. Nil treats every role as local.
+	RoleConfig *providers.RoleConfig
+
+	// QueueSize caps the requests held at once with queue_during_warmup.
+	// Requests beyond it get the 503. Default: DefaultWarmupQueueSize.
+	QueueSize int
+
+	// QueueTimeout caps how long a request is held. A request still
+	// waiting when it expires gets the 503. Default:
+	// DefaultWarmupQueueTimeout.
+	QueueTimeout time.Duration
+}
+
+// warmupGuardRequest is the part of a guarded request body the warmup
+// guard reads: the per-request model overrides and the queue opt-in. It
+// covers AgentRunRequest, AgentContinueRequest and RoutingExplainRequest.
+type warmupGuardRequest struct {
+	Config            *agent.SessionConfig `json:"config,omitempty"`
+	SkipRouter        bool                 `json:"skip_router,omitempty"`
+	QueueDuringWarmup bool                 `json:"queue_during_warmup,omitempty"`
+}
+
+// NewWarmupGuard returns middleware that holds back requests needing a
+// model that is still warming up.
+//
+// Description:
+//
+//	While every role is warm, requests pass straight through. Otherwise
+//	the guard works out which roles the request actually needs: the
+//	configured roles, minus roles whose provider needs no local model
+//	load, minus roles the request moves to a cloud model with a config
+//	override (main_model, tool_router_model, param_extractor_model naming
+//	a claude-, gpt- or gemini- model), minus the router for a routing
+//	explanation with skip_router. A request needing only warm roles
+//	passes.
+//
+//	A request that needs a role still warming gets a 503 WARMING_UP
+//	problem with Retry-After and the request's trace_id, unless its body
+//	sets "queue_during_warmup": true. Then it is held until its roles are
+//	warm and runs at once, or gets the 503 if the queue is full or the
+//	wait times out. A held request whose client disconnects is dropped.
+//
+// Inputs:
+//
+//	cfg - Roles, provider configuration and queue bounds.
+//
+// Outputs:
+//
+//	gin.HandlerFunc - The middleware.
+//
+// Tracing:
+//
+//	Rejections and held requests get a span inheriting the request's W3C
+//	trace context, so clients can correlate a 503 with their traces.
+//
+// Thread Safety: The middleware is safe for concurrent use.
+func NewWarmupGuard(cfg WarmupGuardConfig) gin.HandlerFunc {
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultWarmupQueueSize
+	}
+	if cfg.QueueTimeout <= 0 {
+		cfg.QueueTimeout = DefaultWarmupQueueTimeout
+	}
+	slots := make(chan struct{}, cfg.QueueSize)
+
+	return func(c *gin.Context) {
+		if IsWarmupComplete() {
+			c.Next()
+			return
+		}
+		req := readWarmupGuardRequest(c)
+		roles := cfg.neededRoles(req)
+		if len(roles) == 0 {
+			c.Next()
+			return
+		}
+		pending := PendingWarmupRoles(roles...)
+		if len(pending) == 0 {
+			c.Next()
+			return
+		}
+		if !req.QueueDuringWarmup {
+			rejectWarmingUp(c, pending, "")
+			return
+		}
+
+		select {
+		case slots <- struct{}{}:
+		default:
+			warmupQueueRequests.WithLabelValues(warmupQueueFull).Inc()
+			rejectWarmingUp(c, pending, "the warmup queue is full")
+			return
+		}
+		warmupQueueDepth.Inc()
+		outcome, pending := waitForWarmup(c, roles, cfg.QueueTimeout)
+		warmupQueueDepth.Dec()
+		<-slots
+		warmupQueueRequests.WithLabelValues(outcome).Inc()
+
+		switch outcome {
+		case warmupQueueServed:
+			c.Next()
+		case warmupQueueTimeout:
+			rejectWarmingUp(c, pending, fmt.Sprintf("still loading after waiting %s", cfg.QueueTimeout))
+		default:
+			c.Abort()
+		}
+	}
+}
+
+// neededRoles returns the configured roles req can need a local model for.
+func (cfg WarmupGuardConfig) neededRoles(req warmupGuardRequest) []string {
+	roles := cfg.Roles
+	if len(roles) == 0 {
+		roles = []string{WarmupRoleMain, WarmupRoleRouter, WarmupRoleParamExtractor}
+	}
+	needed := make([]string, 0, len(roles))
+	for _, role := range roles {
+		if role == WarmupRoleRouter && req.SkipRouter {
+			continue
+		}
+		if !cfg.roleIsLocal(role, req.Config) {
+			continue
+		}
+		needed = append(needed, role)
+	}
+	return needed
+}
+
+// roleIsLocal reports whether role runs on a locally loaded model for a
+// request with the given session overrides.
+func (cfg WarmupGuardConfig) roleIsLocal(role string, overrides *agent.SessionConfig) bool {
+	var provider, override string
+	if cfg.RoleConfig != nil {
+		switch role {
+		case WarmupRoleMain:
+			provider = cfg.RoleConfig.Main.Provider
+		case WarmupRoleRouter:
+			provider = cfg.RoleConfig.Router.Provider
+		case WarmupRoleParamExtractor:
+			provider = cfg.RoleConfig.ParamExtractor.Provider
+		}
+	}
+	if overrides != nil {
+		switch role {
+		case WarmupRoleMain:
+			override = overrides.MainModel
+		case WarmupRoleRouter:
+			override = overrides.ToolRouterModel
+		case WarmupRoleParamExtractor:
+			override = overrides.ParamExtractorModel
+		}
+	}
+	if inferred := providers.InferProvider(override); inferred != "" {
+		provider = inferred
+	}
+	return provider == "" || provider == providers.ProviderOllama
+}
+
+// readWarmupGuardRequest decodes the guard's fields from the request body
+// and restores the body for the handler. A body that is missing, too
+// large or not JSON decodes to the zero value.
+func readWarmupGuardRequest(c *gin.Context) warmupGuardRequest {
+	var req warmupGuardRequest
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return req
+	}
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWarmupGuardBody+1))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+	if err != nil || len(body) > maxWarmupGuardBody {
+		return req
+	}
+	if json.Unmarshal(body, &req) != nil {
+		return warmupGuardRequest{}
+	}
+	return req
+}
+
+// waitForWarmup holds the request until roles are warm, the timeout
+// expires or the client goes away.
+//
+// Outputs:
+//
+//	string - warmupQueueServed, warmupQueueTimeout or warmupQueueCanceled.
+//	[]string - The roles still pending on timeout.
+func waitForWarmup(c *gin.Context, roles []string, timeout time.Duration) (string, []string) {
+	ctx, span := warmupGuardTracer.Start(c.Request.Context(), "warmup_guard.queue",
+		oteltrace.WithAttributes(
+			telemetry.SensitiveString("path", c.Request.URL.Path),
+			attribute.StringSlice("roles", roles),
+		),
+	)
+	defer span.End()
+
+	start := time.Now()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		changed := WarmupChanged()
+		pending := PendingWarmupRoles(roles...)
+		if len(pending) == 0 {
+			slog.InfoContext(ctx, "Queued agent request released after warmup",
+				telemetry.SensitiveLog("path", c.Request.URL.Path),
+				slog.Duration("waited", time.Since(start)))
+			span.SetAttributes(attribute.String("outcome", warmupQueueServed))
+			return warmupQueueServed, nil
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			span.SetAttributes(attribute.String("outcome", warmupQueueTimeout))
+			return warmupQueueTimeout, pending
+		case <-ctx.Done():
+			span.SetAttributes(attribute.String("outcome", warmupQueueCanceled))
+			return warmupQueueCanceled, pending
+		}
+	}
+}
+
+// rejectWarmingUp aborts c with the 503 WARMING_UP problem naming the
+// pending roles. reason, if set, says why a queued request was refused.
+func rejectWarmingUp(c *gin.Context, pending []string, reason string) {
+	_, span := warmupGuardTracer.Start(c.Request.Context(), "warmup_guard.reject",
+		oteltrace.WithAttributes(
+			telemetry.SensitiveString("path", c.Request.URL.Path),
+			attribute.String("method", c.Request.Method),
+			attribute.StringSlice("pending_roles", pending),
+			attribute.Int("http.status_code", http.StatusServiceUnavailable),
+		),
+	)
+	defer span.End()
+
+	traceID := ""
+	if spanCtx := span.SpanContext(); spanCtx.HasTraceID() {
+		traceID = spanCtx.TraceID().String()
+	}
+
+	slog.Warn("Agent request rejected: model warmup in progress",
+		telemetry.SensitiveLog("path", c.Request.URL.Path),
+		slog.String("method", c.Request.Method),
+		slog.Any("pending_roles", pending),
+		slog.String("reason", reason),
+		slog.String("trace_id", traceID))
+
+	span.SetStatus(codes.Error, "service unavailable during warmup")
+
+	detail := fmt.Sprintf("The LLM models are still loading (%s). Please retry in %d seconds.",
+		strings.Join(pending, ", "), warmupRetryAfter)
+	if reason != "" {
+		detail = fmt.Sprintf("The LLM models are still loading (%s): %s. Please retry in %d seconds.",
+			strings.Join(pending, ", "), reason, warmupRetryAfter)
+	}
+	c.Header("Retry-After", strconv.Itoa(warmupRetryAfter))
+	apierror.New(http.StatusServiceUnavailable, apierror.CodeWarmingUp, detail).
+		WithTraceID(traceID).
+		Abort(c)
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/agent/providers"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// newWarmupGuardRouter serves POST /run behind the guard, echoing the
+// request body the handler receives.
+func newWarmupGuardRouter(cfg WarmupGuardConfig) *gin.Engine {
+	r := gin.New()
+	r.POST("/run", NewWarmupGuard(cfg), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return r
+}
+
+// serveWarmupGuard sends body to /run and returns the recorded response.
+func serveWarmupGuard(r *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/run", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// waitForQueueDepth waits until n requests are held by the guard.
+func waitForQueueDepth(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for testutil.ToFloat64(warmupQueueDepth) != float64(n) {
+		if time.Now().After(deadline) {
+			t.Fatalf("queue depth = %v, want %d", testutil.ToFloat64(warmupQueueDepth), n)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWarmupGuard_RequestClassification(t *testing.T) {
+	t.Cleanup(ResetWarmupStatus)
+	ResetWarmupStatus()
+	ExpectWarmup(WarmupRoleRouter)
+
+	local := newWarmupGuardRouter(WarmupGuardConfig{})
+	w := serveWarmupGuard(local, `{"query":"q"}`)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+	if w.Header().Get("Retry-After") != "30" || !strings.Contains(w.Body.String(), "WARMING_UP") {
+		t.Errorf("503 = %v %s, want Retry-After 30 and WARMING_UP", w.Header(), w.Body.String())
+	}
+
+	// A cloud main model and a cloud router need no warmup.
+	cloud := newWarmupGuardRouter(WarmupGuardConfig{RoleConfig: &providers.RoleConfig{
+		Main:   providers.ProviderConfig{Provider: providers.ProviderAnthropic},
+		Router: providers.ProviderConfig{Provider: providers.ProviderGemini},
+	}})
+	if w := serveWarmupGuard(cloud, `{"query":"q"}`); w.Code != http.StatusOK || w.Body.String() != `{"query":"q"}` {
+		t.Errorf("cloud roles: %d %q, want 200 with the body intact", w.Code, w.Body.String())
+	}
+
+	// A request moving the pending roles to cloud models passes; one moving
+	// only main still needs the router.
+	overrides := `{"query":"q","config":{"main_model":"claude-sonnet-4","tool_router_model":"gemini-2.5-flash"}}`
+	if w := serveWarmupGuard(local, overrides); w.Code != http.StatusOK {
+		t.Errorf("cloud overrides: status %d, want 200", w.Code)
+	}
+	if w := serveWarmupGuard(local, `{"query":"q","config":{"main_model":"claude-sonnet-4"}}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("main override only: status %d, want 503", w.Code)
+	}
+
+	MarkWarmupComplete()
+	if w := serveWarmupGuard(local, `{"query":"q","skip_router":true}`); w.Code != http.StatusOK {
+		t.Errorf("skip_router with main warm: status %d, want 200", w.Code)
+	}
+	if w := serveWarmupGuard(local, `{"query":"q"}`); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("router pending: status %d, want 503", w.Code)
+	}
+}
+
+func TestWarmupGuard_QueueReleasedOnWarmup(t *testing.T) {
+	t.Cleanup(ResetWarmupStatus)
+	ResetWarmupStatus()
+	ExpectWarmup(WarmupRoleRouter)
+	r := newWarmupGuardRouter(WarmupGuardConfig{QueueSize: 2, QueueTimeout: 10 * time.Second})
+
+	const body = `{"query":"q","queue_during_warmup":true}`
+	results := make([]*httptest.ResponseRecorder, 2)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = serveWarmupGuard(r, body)
+		}()
+	}
+	waitForQueueDepth(t, 2)
+
+	// The queue is full, so a third request is refused at once.
+	if w := serveWarmupGuard(r, body); w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") == "" {
+		t.Errorf("third request: %d, want 503 with Retry-After", w.Code)
+	}
+
+	// Main finishing is not enough: the router is still pending.
+	MarkWarmupComplete()
+	time.Sleep(20 * time.Millisecond)
+	if testutil.ToFloat64(warmupQueueDepth) != 2 {
+		t.Fatal("requests released before every needed role was warm")
+	}
+
+	MarkRoleWarmupComplete(WarmupRoleRouter, nil)
+	wg.Wait()
+	for i, w := range results {
+		if w.Code != http.StatusOK || w.Body.String() != body {
+			t.Errorf("request %d: %d %q, want 200 with the body intact", i, w.Code, w.Body.String())
+		}
+	}
+	waitForQueueDepth(t, 0)
+}
+
+func TestWarmupGuard_QueueTimeout(t *testing.T) {
+	t.Cleanup(ResetWarmupStatus)
+	ResetWarmupStatus()
+	r := newWarmupGuardRouter(WarmupGuardConfig{QueueTimeout: 30 * time.Millisecond})
+
+	w := serveWarmupGuard(r, `{"query":"q","queue_during_warmup":true}`)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Retry-After") != "30" {
+		t.Fatalf("status = %d, Retry-After = %q, want 503 with Retry-After", w.Code, w.Header().Get("Retry-After"))
+	}
+	if !strings.Contains(w.Body.String(), "after waiting") {
+		t.Errorf("body = %s, want the timeout reason", w.Body.String())
+	}
+}
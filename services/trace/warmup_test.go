@@ -0,0 +1,74 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// fakeModelLister returns a fixed set of resident models.
+type fakeModelLister struct {
+	models []string
+	err    error
+}
+
+func (f fakeModelLister) RunningModels(context.Context) ([]string, error) {
+	return f.models, f.err
+}
+
+func TestPendingWarmupRoles(t *testing.T) {
+	t.Cleanup(ResetWarmupStatus)
+	ResetWarmupStatus()
+	ExpectWarmup(WarmupRoleRouter)
+
+	if got := PendingWarmupRoles(); !reflect.DeepEqual(got, []string{WarmupRoleMain, WarmupRoleRouter}) {
+		t.Errorf("PendingWarmupRoles() = %v, want [main router]", got)
+	}
+	// Never-expected roles (e.g. a cloud-hosted param extractor) are not pending.
+	if got := PendingWarmupRoles(WarmupRoleParamExtractor); got != nil {
+		t.Errorf("PendingWarmupRoles(param_extractor) = %v, want none", got)
+	}
+
+	MarkWarmupComplete()
+	if got := PendingWarmupRoles(WarmupRoleMain); got != nil {
+		t.Errorf("PendingWarmupRoles(main) after main warmed = %v, want none", got)
+	}
+	if got := PendingWarmupRoles(WarmupRoleMain, WarmupRoleRouter); !reflect.DeepEqual(got, []string{WarmupRoleRouter}) {
+		t.Errorf("PendingWarmupRoles(main, router) = %v, want [router]", got)
+	}
+
+	MarkRoleWarmupComplete(WarmupRoleRouter, nil)
+	if got := PendingWarmupRoles(); got != nil {
+		t.Errorf("PendingWarmupRoles() after all warmed = %v, want none", got)
+	}
+}
+
+func TestVerifyWarmupResidency(t *testing.T) {
+	lister := fakeModelLister{models: []string{"gpt-oss:20b", "ministral-3:latest"}}
+	evicted, err := VerifyWarmupResidency(context.Background(), lister, map[string]string{
+		WarmupRoleMain:           "gpt-oss:20b",
+		WarmupRoleRouter:         "granite4:micro-h",
+		WarmupRoleParamExtractor: "ministral-3",
+	})
+	if err != nil {
+		t.Fatalf("VerifyWarmupResidency() error = %v", err)
+	}
+	if !reflect.DeepEqual(evicted, []string{WarmupRoleRouter}) {
+		t.Errorf("evicted = %v, want [router]", evicted)
+	}
+
+	if _, err := VerifyWarmupResidency(context.Background(), fakeModelLister{err: errors.New("refused")}, nil); err == nil {
+		t.Error("expected an error when resident models cannot be listed")
+	}
+}
@@ -35,6 +35,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
 )
 
 // -----------------------------------------------------------------------------
@@ -318,7 +320,7 @@ func NewResilientClient(config ClientConfig) (*ResilientClient, error) {
 		if config.AllowStartDegraded {
 			rc.logger.Warn("Weaviate unavailable at startup, starting in degraded mode",
 				slog.String("url", config.URL),
-				slog.String("error", err.Error()))
+				telemetry.SensitiveLogError("error", err))
 			rc.healthWg.Add(1)
 			go rc.runHealthChecker()
 			return rc, nil
@@ -0,0 +1,560 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	posixpath "path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"gopkg.in/yaml.v3"
+)
+
+// workspaceGoImportPrefix replaces a root's Go module path when imports are
+// rewritten. The Go import matcher skips the first segment of long paths the
+// way it skips a module domain, so the namespaced directory is tried first.
+const workspaceGoImportPrefix = "workspace"
+
+// Workspace is a set of sibling project roots built into one graph.
+//
+// Description:
+//
+//	File paths and symbol IDs in a workspace graph are relative to Base,
+//	the deepest directory containing every root. Each root's files are
+//	therefore namespaced by the root's path relative to Base (its Name),
+//	e.g. "shared-lib/pkg/util/util.go", and tools that read files by
+//	joining ProjectRoot with a file path keep working.
+//
+//	Imports that name another root by Go module path, package.json name
+//	or tsconfig path alias are rewritten to namespaced paths before the
+//	build so the builder resolves them to that root's symbols. Python
+//	imports resolve by package name through the builder's module-path
+//	suffix match and are left unchanged.
+//
+// Thread Safety: Immutable after resolveWorkspace returns.
+type Workspace struct {
+	// GraphID identifies the combined graph. Derived from the sorted roots.
+	GraphID string
+
+	// Base is the deepest directory containing every root.
+	Base string
+
+	// Roots are the workspace roots, sorted by Path.
+	Roots []WorkspaceRoot
+}
+
+// WorkspaceRoot is one root of a Workspace.
+type WorkspaceRoot struct {
+	// Name is the root's path relative to the workspace base, with forward
+	// slashes. It prefixes every file path and symbol ID from the root.
+	Name string `json:"name"`
+
+	// Path is the absolute path of the root.
+	Path string `json:"path"`
+
+	// GoModule is the module path from the root's go.mod, if any.
+	GoModule string `json:"go_module,omitempty"`
+
+	// PackageName is the name from the root's package.json, if any.
+	PackageName string `json:"package_name,omitempty"`
+
+	// packageEntry is the namespaced path, without extension, that a bare
+	// import of PackageName resolves to.
+	packageEntry string
+
+	// tsAliases are the compilerOptions.paths entries of the root's
+	// tsconfig.json, with targets namespaced.
+	tsAliases []tsPathAlias
+}
+
+// tsPathAlias is one tsconfig "paths" entry. Pattern and targets contain at
+// most one "*" wildcard.
+type tsPathAlias struct {
+	pattern string
+	targets []string
+}
+
+// WorkspaceManifest is the file format accepted by InitRequest.WorkspaceFile.
+// YAML or JSON.
+//
+//	roots:
+//	  - ../shared-lib
+//	  - ../service-a
+type WorkspaceManifest struct {
+	// Roots are the workspace roots. Relative paths are resolved against
+	// the manifest's directory.
+	Roots []string `json:"roots" yaml:"roots"`
+}
+
+// LoadWorkspaceManifest reads a workspace manifest and returns its roots as
+// absolute paths.
+//
+// Inputs:
+//
+//	manifestPath - Absolute path to a YAML or JSON manifest.
+//
+// Outputs:
+//
+//	[]string - The roots, absolute and cleaned.
+//	error - ErrRelativePath if manifestPath is relative, ErrInvalidWorkspace
+//	  if the file cannot be read, does not parse, or lists no roots.
+func LoadWorkspaceManifest(manifestPath string) ([]string, error) {
+	if !filepath.IsAbs(manifestPath) {
+		return nil, ErrRelativePath
+	}
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading manifest: %v", ErrInvalidWorkspace, err)
+	}
+	var manifest WorkspaceManifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: parsing manifest: %v", ErrInvalidWorkspace, err)
+	}
+	if len(manifest.Roots) == 0 {
+		return nil, fmt.Errorf("%w: manifest lists no roots", ErrInvalidWorkspace)
+	}
+	dir := filepath.Dir(manifestPath)
+	roots := make([]string, 0, len(manifest.Roots))
+	for _, root := range manifest.Roots {
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(dir, root)
+		}
+		roots = append(roots, filepath.Clean(root))
+	}
+	return roots, nil
+}
+
+// resolveWorkspace validates roots and detects each root's module metadata.
+//
+// Description:
+//
+//	Each root and the workspace base are validated like a project root.
+//	Roots must be distinct, must not contain one another, and must share
+//	a parent directory other than the filesystem root.
+//
+// Outputs:
+//
+//	*Workspace - The workspace. Never nil on success.
+//	error - ErrRelativePath, ErrPathTraversal, or ErrInvalidWorkspace.
+func (s *Service) resolveWorkspace(roots []string) (*Workspace, error) {
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("%w: no roots given", ErrInvalidWorkspace)
+	}
+	for _, root := range roots {
+		if err := s.validateProjectRoot(root); err != nil {
+			return nil, err
+		}
+	}
+
+	paths := make([]string, 0, len(roots))
+	seen := make(map[string]bool, len(roots))
+	for _, root := range roots {
+		root = filepath.Clean(root)
+		if !seen[root] {
+			seen[root] = true
+			paths = append(paths, root)
+		}
+	}
+	sort.Strings(paths)
+	for i, outer := range paths {
+		for _, inner := range paths[i+1:] {
+			if isWithin(inner, outer) {
+				return nil, fmt.Errorf("%w: root %s is inside root %s", ErrInvalidWorkspace, inner, outer)
+			}
+		}
+	}
+
+	base := commonAncestor(paths)
+	if base == filepath.Dir(base) {
+		return nil, fmt.Errorf("%w: roots must share a parent directory other than %s", ErrInvalidWorkspace, base)
+	}
+	// The base only namespaces paths and is never walked, so it needs to be
+	// a valid path but not an allowed root itself.
+	if err := validateRootPath(base); err != nil {
+		return nil, err
+	}
+
+	ws := &Workspace{
+		GraphID: s.generateGraphID("workspace:" + strings.Join(paths, "\n")),
+		Base:    base,
+		Roots:   make([]WorkspaceRoot, 0, len(paths)),
+	}
+	for _, p := range paths {
+		rel, err := filepath.Rel(base, p)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidWorkspace, err)
+		}
+		root := WorkspaceRoot{
+			Name:     filepath.ToSlash(rel),
+			Path:     p,
+			GoModule: readGoModulePath(p),
+		}
+		root.PackageName, root.packageEntry = readPackageJSON(p, root.Name)
+		root.tsAliases = readTSPathAliases(p, base)
+		ws.Roots = append(ws.Roots, root)
+	}
+	return ws, nil
+}
+
+// RootNames returns the namespace of every root, in root order.
+func (ws *Workspace) RootNames() []string {
+	names := make([]string, len(ws.Roots))
+	for i, root := range ws.Roots {
+		names[i] = root.Name
+	}
+	return names
+}
+
+// HasRoot reports whether name is the namespace of one of the roots.
+func (ws *Workspace) HasRoot(name string) bool {
+	name = strings.Trim(name, "/")
+	for _, root := range ws.Roots {
+		if root.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// rootOf returns the root whose namespace contains filePath.
+func (ws *Workspace) rootOf(filePath string) (*WorkspaceRoot, bool) {
+	for i := range ws.Roots {
+		name := ws.Roots[i].Name
+		if strings.HasPrefix(filePath, name+"/") {
+			return &ws.Roots[i], true
+		}
+	}
+	return nil, false
+}
+
+// rewriteImports rewrites imports that name a workspace root to namespaced
+// paths. Results are modified in place.
+//
+// Description:
+//
+//	Go imports under a root's module path become
+//	"workspace/<root>/<subpackage>". TypeScript and JavaScript imports
+//	matching a tsconfig path alias of the importing root, or naming a
+//	root's package.json name, become "<root>/<path>". Relative imports and
+//	imports of anything outside the workspace are unchanged.
+//
+// Outputs:
+//
+//	int - The number of imports rewritten.
+func (ws *Workspace) rewriteImports(results []*ast.ParseResult) int {
+	rewritten := 0
+	for _, pr := range results {
+		if pr == nil {
+			continue
+		}
+		from, _ := ws.rootOf(pr.FilePath)
+		for i := range pr.Imports {
+			imp := &pr.Imports[i]
+			var target string
+			switch pr.Language {
+			case "go":
+				target = ws.goImport(imp.Path)
+			case "typescript", "javascript":
+				if !imp.IsRelative && !strings.HasPrefix(imp.Path, ".") && !strings.HasPrefix(imp.Path, "/") {
+					target = ws.jsImport(from, imp.Path)
+				}
+			}
+			if target != "" && target != imp.Path {
+				imp.Path = target
+				rewritten++
+			}
+		}
+	}
+	return rewritten
+}
+
+// goImport maps a Go import path under a root's module to its namespaced
+// form, or returns "" if no root provides it. The longest module path wins.
+func (ws *Workspace) goImport(importPath string) string {
+	var best *WorkspaceRoot
+	for i := range ws.Roots {
+		mod := ws.Roots[i].GoModule
+		if mod == "" || (importPath != mod && !strings.HasPrefix(importPath, mod+"/")) {
+			continue
+		}
+		if best == nil || len(mod) > len(best.GoModule) {
+			best = &ws.Roots[i]
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	sub := strings.TrimPrefix(importPath, best.GoModule)
+	return workspaceGoImportPrefix + "/" + posixpath.Join(best.Name, sub)
+}
+
+// jsImport maps a bare TypeScript/JavaScript import to a namespaced path,
+// or returns "" if neither a path alias of the importing root nor a root's
+// package name matches.
+func (ws *Workspace) jsImport(from *WorkspaceRoot, importPath string) string {
+	if from != nil {
+		for _, alias := range from.tsAliases {
+			if target, ok := alias.apply(importPath); ok {
+				return target
+			}
+		}
+	}
+	for _, root := range ws.Roots {
+		if root.PackageName == "" {
+			continue
+		}
+		if importPath == root.PackageName {
+			return root.packageEntry
+		}
+		if sub, ok := strings.CutPrefix(importPath, root.PackageName+"/"); ok {
+			return posixpath.Join(root.Name, sub)
+		}
+	}
+	return ""
+}
+
+// apply returns the first target with the wildcard substituted if
+// importPath matches the alias pattern.
+func (a tsPathAlias) apply(importPath string) (string, bool) {
+	if len(a.targets) == 0 {
+		return "", false
+	}
+	prefix, suffix, wildcard := strings.Cut(a.pattern, "*")
+	if !wildcard {
+		if importPath != a.pattern {
+			return "", false
+		}
+		return a.targets[0], true
+	}
+	if len(importPath) < len(prefix)+len(suffix) || !strings.HasPrefix(importPath, prefix) || !strings.HasSuffix(importPath, suffix) {
+		return "", false
+	}
+	match := importPath[len(prefix) : len(importPath)-len(suffix)]
+	return strings.Replace(a.targets[0], "*", match, 1), true
+}
+
+// readGoModulePath returns the module path declared in dir/go.mod, or "".
+func readGoModulePath(dir string) string {
+	f, err := os.Open(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		rest, ok := strings.CutPrefix(line, "module")
+		if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+			continue
+		}
+		if comment := strings.Index(rest, "//"); comment >= 0 {
+			rest = rest[:comment]
+		}
+		return strings.Trim(strings.TrimSpace(rest), `"`)
+	}
+	return ""
+}
+
+// readPackageJSON returns the package name from dir/package.json and the
+// namespaced entry file (without extension) a bare import resolves to.
+// The entry is the first of src/index and index with a TypeScript or
+// JavaScript source file, defaulting to "<name>/index".
+func readPackageJSON(dir, name string) (string, string) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return "", ""
+	}
+	var pkg struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Name == "" {
+		return "", ""
+	}
+	for _, entry := range []string{"src/index", "index"} {
+		for _, ext := range []string{".ts", ".tsx", ".js", ".jsx"} {
+			if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(entry)+ext)); err == nil {
+				return pkg.Name, posixpath.Join(name, entry)
+			}
+		}
+	}
+	return pkg.Name, posixpath.Join(name, "index")
+}
+
+// readTSPathAliases returns the compilerOptions.paths of dir/tsconfig.json
+// with targets resolved against baseUrl and made relative to base. Targets
+// outside base are dropped. "extends" is not followed.
+func readTSPathAliases(dir, base string) []tsPathAlias {
+	data, err := os.ReadFile(filepath.Join(dir, "tsconfig.json"))
+	if err != nil {
+		return nil
+	}
+	var cfg struct {
+		CompilerOptions struct {
+			BaseURL string              `json:"baseUrl"`
+			Paths   map[string][]string `json:"paths"`
+		} `json:"compilerOptions"`
+	}
+	if err := json.Unmarshal(stripJSONC(data), &cfg); err != nil {
+		return nil
+	}
+	baseURL := filepath.Join(dir, filepath.FromSlash(cfg.CompilerOptions.BaseURL))
+
+	patterns := make([]string, 0, len(cfg.CompilerOptions.Paths))
+	for pattern := range cfg.CompilerOptions.Paths {
+		patterns = append(patterns, pattern)
+	}
+	// Longer patterns first so "@app/core/*" wins over "@app/*".
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) > len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	var aliases []tsPathAlias
+	for _, pattern := range patterns {
+		alias := tsPathAlias{pattern: pattern}
+		for _, target := range cfg.CompilerOptions.Paths[pattern] {
+			abs := filepath.Join(baseURL, filepath.FromSlash(target))
+			rel, err := filepath.Rel(base, abs)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			rel = filepath.ToSlash(rel)
+			for _, ext := range []string{".ts", ".tsx", ".js", ".jsx", ".d.ts"} {
+				if strings.HasSuffix(rel, ext) && !strings.HasSuffix(rel, "*"+ext) {
+					rel = strings.TrimSuffix(rel, ext)
+					break
+				}
+			}
+			alias.targets = append(alias.targets, rel)
+		}
+		if len(alias.targets) > 0 {
+			aliases = append(aliases, alias)
+		}
+	}
+	return aliases
+}
+
+// stripJSONC removes comments and trailing commas so tsconfig.json, which
+// allows both, can be decoded with encoding/json.
+func stripJSONC(data []byte) []byte {
+	return stripTrailingCommas(stripJSONComments(data))
+}
+
+// stripJSONComments removes // and /* */ comments outside strings. A line
+// comment keeps its newline.
+func stripJSONComments(data []byte) []byte {
+	var out bytes.Buffer
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out.WriteByte(data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		switch {
+		case c == '"':
+			inString = true
+			out.WriteByte(c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out.WriteByte('\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(data)
+			} else {
+				i += end + 3
+			}
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.Bytes()
+}
+
+// stripTrailingCommas removes commas outside strings that are followed,
+// after whitespace, by a closing brace or bracket.
+func stripTrailingCommas(data []byte) []byte {
+	var out bytes.Buffer
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		if inString {
+			out.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out.WriteByte(data[i])
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+		} else if c == ',' {
+			j := i + 1
+			for j < len(data) && (data[j] == ' ' || data[j] == '\t' || data[j] == '\n' || data[j] == '\r') {
+				j++
+			}
+			if j < len(data) && (data[j] == '}' || data[j] == ']') {
+				continue
+			}
+		}
+		out.WriteByte(c)
+	}
+	return out.Bytes()
+}
+
+// commonAncestor returns the deepest directory strictly containing every
+// path. Paths must be absolute, cleaned, and must not contain one another.
+func commonAncestor(paths []string) string {
+	base := filepath.Dir(paths[0])
+	for _, p := range paths[1:] {
+		for !isWithin(p, base) {
+			parent := filepath.Dir(base)
+			if parent == base {
+				return base
+			}
+			base = parent
+		}
+	}
+	return base
+}
+
+// isWithin reports whether p is dir or below it.
+func isWithin(p, dir string) bool {
+	if p == dir {
+		return true
+	}
+	if !strings.HasSuffix(dir, string(filepath.Separator)) {
+		dir += string(filepath.Separator)
+	}
+	return strings.HasPrefix(p, dir)
+}
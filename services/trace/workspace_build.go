@@ -0,0 +1,406 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	posixpath "path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/graph"
+	"github.com/AleutianAI/AleutianFOSS/services/trace/telemetry"
+)
+
+// InitWorkspace builds one graph from several project roots.
+//
+// Description:
+//
+//	Same as InitWithDiscovery, for a multi-root workspace (see Workspace).
+//	Every root is parsed with the same languages, excludes and discovery
+//	options; ignore files are read per root. The project size limits
+//	apply to the workspace as a whole. The graph ID depends only on the
+//	set of roots, not their order.
+//
+//	A rebuild of a cached workspace graph re-parses only the roots whose
+//	files changed since it was built (see tryWorkspaceRefresh).
+//
+// Inputs:
+//
+//	ctx - Context for cancellation
+//	roots - Absolute paths of the workspace roots
+//	languages - Languages to parse (default: ["go"])
+//	excludes - Glob patterns to exclude (see Init for defaults)
+//	discovery - Ignore rules and generated-file handling
+//
+// Outputs:
+//
+//	*InitResponse - Graph statistics, with Roots populated
+//	error - Same errors as Init, plus ErrInvalidWorkspace
+func (s *Service) InitWorkspace(ctx context.Context, roots, languages, excludes []string, discovery DiscoveryOptions, forceRebuild ...bool) (*InitResponse, error) {
+	rebuild := len(forceRebuild) > 0 && forceRebuild[0]
+	ws, languages, excludes, err := s.prepareWorkspaceInit(roots, languages, excludes)
+	if err != nil {
+		return nil, err
+	}
+	return s.initGraph(ctx, ws.GraphID, ws.Base, ws, languages, excludes, discovery, rebuild, true)
+}
+
+// StartInitWorkspace is InitWorkspace without waiting on other callers.
+// See StartInitWithDiscovery.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Service) StartInitWorkspace(ctx context.Context, roots, languages, excludes []string, discovery DiscoveryOptions, forceRebuild bool) (*InitResponse, error) {
+	ws, languages, excludes, err := s.prepareWorkspaceInit(roots, languages, excludes)
+	if err != nil {
+		return nil, err
+	}
+	return s.initGraph(ctx, ws.GraphID, ws.Base, ws, languages, excludes, discovery, forceRebuild, false)
+}
+
+// StartInitWorkspaceAsync starts a workspace build in the background.
+// See StartInitAsync.
+//
+// Thread Safety: This method is safe for concurrent use.
+func (s *Service) StartInitWorkspaceAsync(ctx context.Context, roots, languages, excludes []string, discovery DiscoveryOptions) (*BuildStatusResponse, error) {
+	ws, languages, excludes, err := s.prepareWorkspaceInit(roots, languages, excludes)
+	if err != nil {
+		return nil, err
+	}
+	return s.startAsync(ctx, ws.GraphID, ws.Base, ws, languages, excludes, discovery)
+}
+
+// prepareWorkspaceInit resolves the workspace and applies language and
+// exclude defaults.
+func (s *Service) prepareWorkspaceInit(roots, languages, excludes []string) (*Workspace, []string, []string, error) {
+	ws, err := s.resolveWorkspace(roots)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	languages, excludes = applyInitDefaults(languages, excludes)
+	return ws, languages, excludes, nil
+}
+
+// parseWorkspaceToResults parses every root of ws and rewrites cross-root
+// imports.
+//
+// Outputs:
+//
+//	[]*ast.ParseResult - Parse results for all roots, in root order
+//	*parseResult - Stats summed over all roots
+//	map[string]int - Files parsed per root name
+//	error - Non-nil on fatal errors, including ErrProjectTooLarge when the
+//	  roots together exceed the limits
+func (s *Service) parseWorkspaceToResults(ctx context.Context, ws *Workspace, languages, excludes []string, discovery DiscoveryOptions) ([]*ast.ParseResult, *parseResult, map[string]int, error) {
+	total := &parseResult{Errors: make([]string, 0)}
+	perRoot := make(map[string]int, len(ws.Roots))
+	budget := &walkBudget{}
+	var all []*ast.ParseResult
+
+	for _, root := range ws.Roots {
+		results, stats, err := s.parseRootToResults(ctx, NewFileSystemSource(root.Path, discovery.sourceOptions()...), root.Name, languages, excludes, discovery, budget)
+		if err != nil {
+			return nil, total, perRoot, fmt.Errorf("root %s: %w", root.Name, err)
+		}
+		all = append(all, results...)
+		perRoot[root.Name] = stats.FilesParsed
+		total.FilesParsed += stats.FilesParsed
+		total.Errors = append(total.Errors, stats.Errors...)
+		total.FileErrors = append(total.FileErrors, stats.FileErrors...)
+		total.PythonSourceRoots = append(total.PythonSourceRoots, stats.PythonSourceRoots...)
+		total.GoModules = append(total.GoModules, stats.GoModules...)
+		for rule, n := range stats.SkippedByRule {
+			total.SkippedFiles += n
+			if total.SkippedByRule == nil {
+				total.SkippedByRule = make(map[string]int)
+			}
+			total.SkippedByRule[rule] += n
+		}
+	}
+
+	rewritten := ws.rewriteImports(all)
+	slog.Info("Workspace parsed",
+		slog.String("base", ws.Base),
+		slog.Int("roots", len(ws.Roots)),
+		slog.Int("files", total.FilesParsed),
+		slog.Int("cross_root_imports", rewritten),
+	)
+	return all, total, perRoot, nil
+}
+
+// tryWorkspaceRefresh refreshes a cached workspace graph, re-parsing only
+// the roots that changed.
+//
+// Description:
+//
+//	Changed and deleted files are detected per root since the cached
+//	graph was built. A root whose change ratio is within the incremental
+//	threshold has only its changed files re-parsed; a root above it is
+//	re-parsed in full. Unchanged roots are not parsed at all. The result
+//	is applied with graph.IncrementalRefresh, so cross-file resolution is
+//	not re-run for unchanged files.
+//
+// Outputs:
+//
+//	*InitResponse - The refreshed graph, or nil if a full build is needed
+//	  (no cached workspace graph, change detection failed, or every root
+//	  changed beyond the threshold).
+//	error - Non-nil only if the refreshed graph could not be cached.
+func (s *Service) tryWorkspaceRefresh(ctx context.Context, ws *Workspace, graphID string, languages, excludes []string, discovery DiscoveryOptions) (*InitResponse, error) {
+	s.mu.RLock()
+	existing := s.graphs[graphID]
+	s.mu.RUnlock()
+	if existing == nil || existing.Workspace == nil || existing.Graph == nil {
+		return nil, nil
+	}
+	// Variant edges span files; a full build keeps them complete. A
+	// refresh would parse changed files with the default parser settings
+	// and find them with the default symlink policy.
+	if discovery.AllBuildProfiles || len(discovery.ParserSettings) > 0 || !discovery.defaultSymlinks() {
+		return nil, nil
+	}
+	// A refresh keeps the placeholder policy the graph was built with.
+	built, _ := graph.ParsePlaceholderPolicy(string(existing.Graph.PlaceholderPolicy))
+	wanted, _ := graph.ParsePlaceholderPolicy(string(discovery.PlaceholderPolicy))
+	if built != wanted {
+		return nil, nil
+	}
+	start := time.Now()
+	base := existing.Graph
+	since := time.UnixMilli(base.BuiltAtMilli)
+
+	graphFiles := make(map[string][]string, len(ws.Roots))
+	seen := make(map[string]bool)
+	for _, node := range base.Nodes() {
+		if node.Symbol == nil || node.Symbol.FilePath == "" || seen[node.Symbol.FilePath] {
+			continue
+		}
+		seen[node.Symbol.FilePath] = true
+		if root, ok := ws.rootOf(node.Symbol.FilePath); ok {
+			graphFiles[root.Name] = append(graphFiles[root.Name], node.Symbol.FilePath)
+		}
+	}
+
+	// Decide per root before parsing anything.
+	changedByRoot := make(map[string][]string, len(ws.Roots))
+	fullRoots := make(map[string]bool)
+	for _, root := range ws.Roots {
+		changed, err := findChangedSourceFiles(ctx, root.Path, since, languages, excludes, discovery)
+		if err != nil {
+			slog.Debug("Workspace refresh: change detection failed, falling back to full build",
+				telemetry.SensitiveLog("root", root.Name),
+				telemetry.SensitiveLogError("error", err),
+			)
+			return nil, nil
+		}
+		changedSet := make(map[string]bool, len(changed))
+		for i, f := range changed {
+			changed[i] = posixpath.Join(root.Name, filepath.ToSlash(f))
+			changedSet[changed[i]] = true
+		}
+		for _, f := range graphFiles[root.Name] {
+			if changedSet[f] {
+				continue
+			}
+			if _, err := os.Stat(filepath.Join(ws.Base, filepath.FromSlash(f))); err != nil {
+				changed = append(changed, f)
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		changedByRoot[root.Name] = changed
+		if !graph.ShouldDoIncrementalUpdate(len(changed), len(graphFiles[root.Name])) {
+			fullRoots[root.Name] = true
+		}
+	}
+	if len(fullRoots) == len(ws.Roots) {
+		slog.Info("Workspace refresh: every root changed beyond threshold, falling back to full build")
+		return nil, nil
+	}
+
+	rootFilesParsed := make(map[string]int, len(ws.Roots))
+	if len(changedByRoot) == 0 {
+		return s.cacheAndReturn(ctx, base, ws.Base, graphID, start, 0, nil, &existing.EnrichmentStats, ws, rootFilesParsed)
+	}
+
+	var changedFiles []string
+	var changedResults []*ast.ParseResult
+	var errs []string
+	budget := &walkBudget{}
+	for _, root := range ws.Roots {
+		changed, ok := changedByRoot[root.Name]
+		if !ok {
+			continue
+		}
+		if fullRoots[root.Name] {
+			results, stats, err := s.parseRootToResults(ctx, NewFileSystemSource(root.Path, discovery.sourceOptions()...), root.Name, languages, excludes, discovery, budget)
+			if err != nil {
+				return nil, nil
+			}
+			// Replace every file the root had or has now.
+			removed := make(map[string]bool, len(graphFiles[root.Name]))
+			for _, f := range graphFiles[root.Name] {
+				removed[f] = true
+				changedFiles = append(changedFiles, f)
+			}
+			for _, pr := range results {
+				if !removed[pr.FilePath] {
+					changedFiles = append(changedFiles, pr.FilePath)
+				}
+			}
+			changedResults = append(changedResults, results...)
+			errs = append(errs, stats.Errors...)
+			for _, fe := range stats.FileErrors {
+				errs = append(errs, fe.Error())
+			}
+			rootFilesParsed[root.Name] = stats.FilesParsed
+			continue
+		}
+		changedFiles = append(changedFiles, changed...)
+		for _, relPath := range changed {
+			absPath := filepath.Join(ws.Base, filepath.FromSlash(relPath))
+			if _, err := os.Stat(absPath); err != nil {
+				continue
+			}
+			if !s.isLanguageFile(filepath.Ext(relPath), languages) {
+				continue
+			}
+			pr, err := s.parseFileToResult(ctx, absPath, relPath)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", relPath, err))
+				continue
+			}
+			changedResults = append(changedResults, pr)
+			rootFilesParsed[root.Name]++
+		}
+	}
+	changedResults = discovery.filterBuildProfile(changedResults)
+	ws.rewriteImports(changedResults)
+
+	incrResult, err := graph.IncrementalRefresh(ctx, base, changedFiles, changedResults, s.buildLSPEnrichmentConfig(graphID))
+	if err != nil {
+		slog.Warn("Workspace refresh failed, falling back to full build",
+			telemetry.SensitiveLogError("error", err),
+		)
+		return nil, nil
+	}
+
+	slog.Info("Workspace refresh succeeded",
+		slog.Int("roots_changed", len(changedByRoot)),
+		slog.Int("roots_reparsed", len(fullRoots)),
+		slog.Int("changed_files", len(changedFiles)),
+		slog.Int64("duration_ms", incrResult.DurationMilli),
+	)
+
+	merged := existing.EnrichmentStats
+	merged.Merge(incrResult.EnrichmentStats)
+	filesParsed := 0
+	for _, n := range rootFilesParsed {
+		filesParsed += n
+	}
+	return s.cacheAndReturn(ctx, incrResult.Graph, ws.Base, graphID, start, filesParsed, errs, &merged, ws, rootFilesParsed)
+}
+
+// workspaceRootStats summarizes each root of a workspace graph.
+func workspaceRootStats(g *graph.Graph, ws *Workspace, filesParsed map[string]int) []WorkspaceRootStats {
+	names := ws.RootNames()
+	memory := g.MemoryStatsByRoot(names)
+
+	files := make(map[string]map[string]struct{}, len(names))
+	for _, node := range g.Nodes() {
+		if node.Symbol == nil || node.Symbol.FilePath == "" {
+			continue
+		}
+		root, ok := ws.rootOf(node.Symbol.FilePath)
+		if !ok {
+			continue
+		}
+		if files[root.Name] == nil {
+			files[root.Name] = make(map[string]struct{})
+		}
+		files[root.Name][node.Symbol.FilePath] = struct{}{}
+	}
+
+	stats := make([]WorkspaceRootStats, len(ws.Roots))
+	for i, root := range ws.Roots {
+		mem := memory[root.Name]
+		stats[i] = WorkspaceRootStats{
+			Name:        root.Name,
+			Path:        root.Path,
+			GoModule:    root.GoModule,
+			PackageName: root.PackageName,
+			FilesParsed: filesParsed[root.Name],
+			Files:       len(files[root.Name]),
+			Nodes:       mem.NodeCount,
+			Edges:       mem.EdgeCount,
+			MemoryBytes: mem.TotalBytes,
+		}
+	}
+	return stats
+}
+
+// CheckRootFilter validates a root filter against a cached graph.
+//
+// Outputs:
+//
+//	error - ErrUnknownRoot if the graph is not a workspace or has no root
+//	  named root. Nil if root is empty or the graph is not cached; the
+//	  query itself reports a missing graph.
+func (s *Service) CheckRootFilter(graphID, root string) error {
+	if root == "" {
+		return nil
+	}
+	cached, err := s.GetGraph(graphID)
+	if err != nil {
+		return nil
+	}
+	if cached.Workspace == nil {
+		return fmt.Errorf("%w: graph %s was not built from roots", ErrUnknownRoot, graphID)
+	}
+	if !cached.Workspace.HasRoot(root) {
+		return fmt.Errorf("%w: %q (roots: %s)", ErrUnknownRoot, root, strings.Join(cached.Workspace.RootNames(), ", "))
+	}
+	return nil
+}
+
+// filterSymbolsByRoot keeps symbols whose file is in root, up to limit.
+func filterSymbolsByRoot(symbols []*SymbolInfo, root string, limit int) []*SymbolInfo {
+	filtered := make([]*SymbolInfo, 0, len(symbols))
+	for _, sym := range symbols {
+		if sym != nil && graph.FilePathInRoot(sym.FilePath, root) {
+			filtered = append(filtered, sym)
+			if len(filtered) == limit {
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// filterReferencesByRoot keeps references located in root, up to limit.
+func filterReferencesByRoot(refs []ReferenceInfo, root string, limit int) []ReferenceInfo {
+	filtered := make([]ReferenceInfo, 0, len(refs))
+	for _, ref := range refs {
+		if graph.FilePathInRoot(ref.FilePath, root) {
+			filtered = append(filtered, ref)
+			if len(filtered) == limit {
+				break
+			}
+		}
+	}
+	return filtered
+}
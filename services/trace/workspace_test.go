@@ -0,0 +1,324 @@
+// Copyright (C) 2025 Aleutian AI (jinterlante@aleutian.ai)
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+// See the LICENSE.txt file for the full license text.
+//
+// NOTE: This work is subject to additional terms under AGPL v3 Section 7.
+// See the NOTICE.txt file for details regarding AI system attribution.
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AleutianAI/AleutianFOSS/services/trace/ast"
+)
+
+// writeWorkspaceFiles writes files (relative path -> content) under dir.
+func writeWorkspaceFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for relPath, content := range files {
+		absPath := filepath.Join(dir, relPath)
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(absPath, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// writeGoWorkspace creates a shared-lib module and a service-a module that
+// imports it, and returns their roots.
+func writeGoWorkspace(t *testing.T) (base, sharedLib, serviceA string) {
+	t.Helper()
+	base = t.TempDir()
+	sharedLib = filepath.Join(base, "shared-lib")
+	serviceA = filepath.Join(base, "service-a")
+	writeWorkspaceFiles(t, sharedLib, map[string]string{
+		"go.mod": "module example.com/shared-lib\n\ngo 1.22\n",
+		"util/util.go": `package util
+
+func Helper() string { return "ok" }
+`,
+	})
+	writeWorkspaceFiles(t, serviceA, map[string]string{
+		"go.mod": "module example.com/service-a\n\ngo 1.22\n",
+		"main.go": `package main
+
+import "example.com/shared-lib/util"
+
+func main() {
+	util.Helper()
+}
+`,
+	})
+	return base, sharedLib, serviceA
+}
+
+func TestResolveWorkspace(t *testing.T) {
+	svc := NewService(DefaultServiceConfig())
+
+	t.Run("names roots relative to the common parent", func(t *testing.T) {
+		base, sharedLib, serviceA := writeGoWorkspace(t)
+
+		ws, err := svc.resolveWorkspace([]string{sharedLib, serviceA, sharedLib})
+		if err != nil {
+			t.Fatalf("resolveWorkspace failed: %v", err)
+		}
+		if ws.Base != base {
+			t.Errorf("Base = %q, want %q", ws.Base, base)
+		}
+		if got := strings.Join(ws.RootNames(), ","); got != "service-a,shared-lib" {
+			t.Errorf("RootNames = %q, want service-a,shared-lib", got)
+		}
+		if ws.Roots[1].GoModule != "example.com/shared-lib" {
+			t.Errorf("GoModule = %q, want example.com/shared-lib", ws.Roots[1].GoModule)
+		}
+
+		again, err := svc.resolveWorkspace([]string{serviceA, sharedLib})
+		if err != nil {
+			t.Fatalf("resolveWorkspace failed: %v", err)
+		}
+		if again.GraphID != ws.GraphID {
+			t.Error("GraphID should not depend on root order")
+		}
+	})
+
+	t.Run("rejects nested roots", func(t *testing.T) {
+		base := t.TempDir()
+		inner := filepath.Join(base, "a", "b")
+		if err := os.MkdirAll(inner, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Join(base, "a-b"), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := svc.resolveWorkspace([]string{filepath.Join(base, "a"), filepath.Join(base, "a-b"), inner})
+		if !errors.Is(err, ErrInvalidWorkspace) {
+			t.Errorf("expected ErrInvalidWorkspace, got %v", err)
+		}
+	})
+
+	t.Run("rejects empty and relative roots", func(t *testing.T) {
+		if _, err := svc.resolveWorkspace(nil); !errors.Is(err, ErrInvalidWorkspace) {
+			t.Errorf("expected ErrInvalidWorkspace, got %v", err)
+		}
+		if _, err := svc.resolveWorkspace([]string{"relative/root"}); !errors.Is(err, ErrRelativePath) {
+			t.Errorf("expected ErrRelativePath, got %v", err)
+		}
+	})
+}
+
+func TestLoadWorkspaceManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifest := filepath.Join(dir, "workspace.yaml")
+	writeWorkspaceFiles(t, dir, map[string]string{
+		"workspace.yaml": "roots:\n  - service-a\n  - /abs/shared-lib\n",
+		"empty.yaml":     "roots: []\n",
+	})
+
+	roots, err := LoadWorkspaceManifest(manifest)
+	if err != nil {
+		t.Fatalf("LoadWorkspaceManifest failed: %v", err)
+	}
+	want := []string{filepath.Join(dir, "service-a"), "/abs/shared-lib"}
+	if len(roots) != 2 || roots[0] != want[0] || roots[1] != want[1] {
+		t.Errorf("roots = %v, want %v", roots, want)
+	}
+
+	if _, err := LoadWorkspaceManifest(filepath.Join(dir, "empty.yaml")); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("empty manifest: expected ErrInvalidWorkspace, got %v", err)
+	}
+	if _, err := LoadWorkspaceManifest(filepath.Join(dir, "missing.yaml")); !errors.Is(err, ErrInvalidWorkspace) {
+		t.Errorf("missing manifest: expected ErrInvalidWorkspace, got %v", err)
+	}
+	if _, err := LoadWorkspaceManifest("workspace.yaml"); !errors.Is(err, ErrRelativePath) {
+		t.Errorf("relative manifest: expected ErrRelativePath, got %v", err)
+	}
+}
+
+func TestWorkspace_RewriteImports(t *testing.T) {
+	base := t.TempDir()
+	writeWorkspaceFiles(t, base, map[string]string{
+		"shared-lib/go.mod":       "module example.com/shared-lib\n",
+		"shared-lib/package.json": `{"name": "@acme/shared"}`,
+		"shared-lib/src/index.ts": "export const x = 1;\n",
+		"web/tsconfig.json": `{
+  // comments and trailing commas are allowed
+  "compilerOptions": {
+    "baseUrl": ".",
+    "paths": {"@lib/*": ["../shared-lib/src/*"],},
+  },
+}`,
+	})
+
+	svc := NewService(DefaultServiceConfig())
+	ws, err := svc.resolveWorkspace([]string{filepath.Join(base, "shared-lib"), filepath.Join(base, "web")})
+	if err != nil {
+		t.Fatalf("resolveWorkspace failed: %v", err)
+	}
+
+	results := []*ast.ParseResult{
+		{FilePath: "web/main.go", Language: "go", Imports: []ast.Import{
+			{Path: "example.com/shared-lib/util"},
+			{Path: "example.com/shared-library"},
+			{Path: "fmt"},
+		}},
+		{FilePath: "web/app.ts", Language: "typescript", Imports: []ast.Import{
+			{Path: "@lib/format"},
+			{Path: "@acme/shared"},
+			{Path: "@acme/shared/src/format"},
+			{Path: "./local", IsRelative: true},
+			{Path: "react"},
+		}},
+	}
+
+	if n := ws.rewriteImports(results); n != 4 {
+		t.Errorf("rewriteImports = %d, want 4", n)
+	}
+	want := [][]string{
+		{"workspace/shared-lib/util", "example.com/shared-library", "fmt"},
+		{"shared-lib/src/format", "shared-lib/src/index", "shared-lib/src/format", "./local", "react"},
+	}
+	for i, pr := range results {
+		for j, imp := range pr.Imports {
+			if imp.Path != want[i][j] {
+				t.Errorf("%s import %d = %q, want %q", pr.FilePath, j, imp.Path, want[i][j])
+			}
+		}
+	}
+}
+
+func TestStripJSONC(t *testing.T) {
+	in := `{"a": "x // not a comment", /* block */ "b": [1, 2,], // line
+}`
+	got := string(stripJSONC([]byte(in)))
+	want := `{"a": "x // not a comment",  "b": [1, 2] 
+}`
+	if got != want {
+		t.Errorf("stripJSONC = %q, want %q", got, want)
+	}
+}
+
+func TestInitWorkspace_CrossRootCalls(t *testing.T) {
+	ctx := context.Background()
+	_, sharedLib, serviceA := writeGoWorkspace(t)
+	roots := []string{serviceA, sharedLib}
+
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.InitWorkspace(ctx, roots, nil, nil, DiscoveryOptions{})
+	if err != nil {
+		t.Fatalf("InitWorkspace failed: %v", err)
+	}
+	if len(resp.Roots) != 2 {
+		t.Fatalf("Roots = %+v, want 2 entries", resp.Roots)
+	}
+	for _, rs := range resp.Roots {
+		if rs.FilesParsed != 1 || rs.Nodes == 0 || rs.MemoryBytes == 0 {
+			t.Errorf("root %s stats = %+v, want 1 file parsed and non-zero nodes/memory", rs.Name, rs)
+		}
+	}
+
+	callers, err := svc.FindCallers(ctx, resp.GraphID, "Helper", 10)
+	if err != nil {
+		t.Fatalf("FindCallers failed: %v", err)
+	}
+	if len(callers) != 1 || callers[0].FilePath != "service-a/main.go" {
+		t.Fatalf("callers of Helper = %+v, want main in service-a/main.go", callers)
+	}
+	if !strings.HasPrefix(callers[0].ID, "service-a/") {
+		t.Errorf("caller ID %q should be namespaced by root", callers[0].ID)
+	}
+
+	if err := svc.CheckRootFilter(resp.GraphID, "shared-lib"); err != nil {
+		t.Errorf("CheckRootFilter(shared-lib) = %v, want nil", err)
+	}
+	if err := svc.CheckRootFilter(resp.GraphID, "service-b"); !errors.Is(err, ErrUnknownRoot) {
+		t.Errorf("CheckRootFilter(service-b) = %v, want ErrUnknownRoot", err)
+	}
+
+	t.Run("rebuild re-parses only the changed root", func(t *testing.T) {
+		mainPath := filepath.Join(serviceA, "main.go")
+		future := time.Now().Add(time.Minute)
+		if err := os.Chtimes(mainPath, future, future); err != nil {
+			t.Fatal(err)
+		}
+
+		resp2, err := svc.InitWorkspace(ctx, roots, nil, nil, DiscoveryOptions{}, true)
+		if err != nil {
+			t.Fatalf("rebuild failed: %v", err)
+		}
+		for _, rs := range resp2.Roots {
+			want := 0
+			if rs.Name == "service-a" {
+				want = 1
+			}
+			if rs.FilesParsed != want {
+				t.Errorf("root %s FilesParsed = %d, want %d", rs.Name, rs.FilesParsed, want)
+			}
+		}
+	})
+}
+
+func TestHandlers_WorkspaceRootFilter(t *testing.T) {
+	_, sharedLib, serviceA := writeGoWorkspace(t)
+	svc := NewService(DefaultServiceConfig())
+	resp, err := svc.InitWorkspace(context.Background(), []string{serviceA, sharedLib}, nil, nil, DiscoveryOptions{})
+	if err != nil {
+		t.Fatalf("InitWorkspace failed: %v", err)
+	}
+	router := setupTestRouter(svc)
+
+	get := func(url string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/v1/trace/callers?graph_id=" + resp.GraphID + "&function=Helper&root=service-a")
+	if w.Code != http.StatusOK {
+		t.Fatalf("root=service-a: expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var callers CallersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &callers); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(callers.Callers) != 1 {
+		t.Errorf("root=service-a: got %d callers, want 1", len(callers.Callers))
+	}
+
+	w = get("/v1/trace/callers?graph_id=" + resp.GraphID + "&function=Helper&root=shared-lib")
+	callers = CallersResponse{}
+	if err := json.Unmarshal(w.Body.Bytes(), &callers); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if len(callers.Callers) != 0 {
+		t.Errorf("root=shared-lib: got %d callers, want 0", len(callers.Callers))
+	}
+
+	w = get("/v1/trace/callees?graph_id=" + resp.GraphID + "&function=main&root=service-b")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("unknown root: expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+	if errResp.Code != "INVALID_PARAMETER" {
+		t.Errorf("unknown root: expected code INVALID_PARAMETER, got %q", errResp.Code)
+	}
+}